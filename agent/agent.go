@@ -29,6 +29,7 @@ import (
 	"go.uber.org/atomic"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"tailscale.com/net/speedtest"
 	"tailscale.com/tailcfg"
@@ -44,6 +45,7 @@ import (
 	"github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/agent/proto/resourcesmonitor"
 	"github.com/coder/coder/v2/agent/reconnectingpty"
+	"github.com/coder/coder/v2/agent/sessionrecording"
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/gitauth"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -92,11 +94,14 @@ type Options struct {
 	Devcontainers                bool
 	DevcontainerAPIOptions       []agentcontainers.Option // Enable Devcontainers for these to be effective.
 	Clock                        quartz.Clock
+	// PrebuildClaimed is set when the agent was reinitialized because its
+	// prebuilt workspace was claimed, so that the claim scripts run.
+	PrebuildClaimed bool
 }
 
 type Client interface {
-	ConnectRPC26(ctx context.Context) (
-		proto.DRPCAgentClient26, tailnetproto.DRPCTailnetClient26, error,
+	ConnectRPC27(ctx context.Context) (
+		proto.DRPCAgentClient27, tailnetproto.DRPCTailnetClient27, error,
 	)
 	RewriteDERPMap(derpMap *tailcfg.DERPMap)
 }
@@ -196,6 +201,8 @@ func New(options Options) Agent {
 
 		devcontainers:       options.Devcontainers,
 		containerAPIOptions: options.DevcontainerAPIOptions,
+
+		prebuildClaimed: options.PrebuildClaimed,
 	}
 	// Initially, we have a closed channel, reflecting the fact that we are not initially connected.
 	// Each time we connect we replace the channel (while holding the closeMutex) with a new one
@@ -269,8 +276,14 @@ type agent struct {
 	reportConnectionsMu     sync.Mutex
 	reportConnections       []*proto.ReportConnectionRequest
 
+	sessionRecorder *sessionrecording.Recorder
+
 	logSender *agentsdk.LogSender
 
+	// connectionQuality tracks the ping results for each peer across stats
+	// reports. It is only accessed by Collect.
+	connectionQuality map[netip.Addr]*peerConnectionQuality
+
 	prometheusRegistry *prometheus.Registry
 	// metrics are prometheus registered metrics that will be collected and
 	// labeled in Coder with the agent + workspace.
@@ -280,6 +293,8 @@ type agent struct {
 	devcontainers       bool
 	containerAPIOptions []agentcontainers.Option
 	containerAPI        *agentcontainers.API
+
+	prebuildClaimed bool
 }
 
 func (a *agent) TailnetConn() *tailnet.Conn {
@@ -289,6 +304,7 @@ func (a *agent) TailnetConn() *tailnet.Conn {
 }
 
 func (a *agent) init() {
+	a.sessionRecorder = sessionrecording.New(a.logger.Named("session-recorder"), a.clock)
 	// pass the "hard" context because we explicitly close the SSH server as part of graceful shutdown.
 	sshSrv, err := agentssh.NewServer(a.hardCtx, a.logger.Named("ssh-server"), a.prometheusRegistry, a.filesystem, a.execer, &agentssh.Config{
 		MaxTimeout:          a.sshMaxTimeout,
@@ -297,7 +313,7 @@ func (a *agent) init() {
 		UpdateEnv:           a.updateCommandEnv,
 		WorkingDirectory:    func() string { return a.manifest.Load().Directory },
 		BlockFileTransfer:   a.blockFileTransfer,
-		ReportConnection: func(id uuid.UUID, magicType agentssh.MagicSessionType, ip string) func(code int, reason string) {
+		ReportConnection: func(id uuid.UUID, magicType agentssh.MagicSessionType, ip, clientVersion string) func(code int, reason string) {
 			var connectionType proto.Connection_Type
 			switch magicType {
 			case agentssh.MagicSessionTypeSSH:
@@ -313,7 +329,11 @@ func (a *agent) init() {
 				connectionType = proto.Connection_TYPE_UNSPECIFIED
 			}
 
-			return a.reportConnection(id, connectionType, ip)
+			return a.reportConnection(id, connectionType, ip, clientVersion)
+		},
+
+		RecordSession: func(width, height int) io.WriteCloser {
+			return a.sessionRecorder.Start(sessionrecording.TypeSSH, width, height)
 		},
 
 		ExperimentalContainers: a.devcontainers,
@@ -353,12 +373,17 @@ func (a *agent) init() {
 		a.logger.Named("reconnecting-pty"),
 		a.sshServer,
 		func(id uuid.UUID, ip string) func(code int, reason string) {
-			return a.reportConnection(id, proto.Connection_RECONNECTING_PTY, ip)
+			// Reconnecting PTY connections are proxied by coderd, which
+			// audits the client that opened the terminal.
+			return a.reportConnection(id, proto.Connection_RECONNECTING_PTY, ip, "")
 		},
 		a.metrics.connectionsTotal, a.metrics.reconnectingPTYErrors,
 		a.reconnectingPTYTimeout,
 		func(s *reconnectingpty.Server) {
 			s.ExperimentalContainers = a.devcontainers
+			s.RecordSession = func(width, height int) io.WriteCloser {
+				return a.sessionRecorder.Start(sessionrecording.TypeReconnectingPTY, width, height)
+			}
 		},
 	)
 	go a.runLoop()
@@ -474,7 +499,7 @@ func (t *trySingleflight) Do(key string, fn func()) {
 	fn()
 }
 
-func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 	tickerDone := make(chan struct{})
 	collectDone := make(chan struct{})
 	ctx, cancel := context.WithCancel(ctx)
@@ -689,7 +714,7 @@ func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient26
 
 // reportLifecycle reports the current lifecycle state once. All state
 // changes are reported in order.
-func (a *agent) reportLifecycle(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) reportLifecycle(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 	for {
 		select {
 		case <-a.lifecycleUpdate:
@@ -769,7 +794,7 @@ func (a *agent) setLifecycle(state codersdk.WorkspaceAgentLifecycle) {
 }
 
 // reportConnectionsLoop reports connections to the agent for auditing.
-func (a *agent) reportConnectionsLoop(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) reportConnectionsLoop(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 	for {
 		select {
 		case <-a.reportConnectionsUpdate:
@@ -818,7 +843,7 @@ const (
 	reportConnectionBufferLimit = 2048
 )
 
-func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_Type, ip string) (disconnected func(code int, reason string)) {
+func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_Type, ip, clientVersion string) (disconnected func(code int, reason string)) {
 	// Remove the port from the IP because ports are not supported in coderd.
 	if host, _, err := net.SplitHostPort(ip); err != nil {
 		a.logger.Error(a.hardCtx, "split host and port for connection report failed", slog.F("ip", ip), slog.Error(err))
@@ -827,6 +852,8 @@ func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_T
 		ip = host
 	}
 
+	connectedAt := time.Now()
+
 	a.reportConnectionsMu.Lock()
 	defer a.reportConnectionsMu.Unlock()
 
@@ -840,13 +867,14 @@ func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_T
 	} else {
 		a.reportConnections = append(a.reportConnections, &proto.ReportConnectionRequest{
 			Connection: &proto.Connection{
-				Id:         id[:],
-				Action:     proto.Connection_CONNECT,
-				Type:       connectionType,
-				Timestamp:  timestamppb.New(time.Now()),
-				Ip:         ip,
-				StatusCode: 0,
-				Reason:     nil,
+				Id:            id[:],
+				Action:        proto.Connection_CONNECT,
+				Type:          connectionType,
+				Timestamp:     timestamppb.New(connectedAt),
+				Ip:            ip,
+				StatusCode:    0,
+				Reason:        nil,
+				ClientVersion: clientVersion,
 			},
 		})
 		select {
@@ -868,15 +896,18 @@ func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_T
 			return
 		}
 
+		now := time.Now()
 		a.reportConnections = append(a.reportConnections, &proto.ReportConnectionRequest{
 			Connection: &proto.Connection{
-				Id:         id[:],
-				Action:     proto.Connection_DISCONNECT,
-				Type:       connectionType,
-				Timestamp:  timestamppb.New(time.Now()),
-				Ip:         ip,
-				StatusCode: int32(code), //nolint:gosec
-				Reason:     &reason,
+				Id:            id[:],
+				Action:        proto.Connection_DISCONNECT,
+				Type:          connectionType,
+				Timestamp:     timestamppb.New(now),
+				Ip:            ip,
+				StatusCode:    int32(code), //nolint:gosec
+				Reason:        &reason,
+				ClientVersion: clientVersion,
+				Duration:      durationpb.New(now.Sub(connectedAt)),
 			},
 		})
 		select {
@@ -889,7 +920,7 @@ func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_T
 // fetchServiceBannerLoop fetches the service banner on an interval.  It will
 // not be fetched immediately; the expectation is that it is primed elsewhere
 // (and must be done before the session actually starts).
-func (a *agent) fetchServiceBannerLoop(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) fetchServiceBannerLoop(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 	ticker := time.NewTicker(a.announcementBannersRefreshInterval)
 	defer ticker.Stop()
 	for {
@@ -925,7 +956,7 @@ func (a *agent) run() (retErr error) {
 	a.sessionToken.Store(&sessionToken)
 
 	// ConnectRPC returns the dRPC connection we use for the Agent and Tailnet v2+ APIs
-	aAPI, tAPI, err := a.client.ConnectRPC26(a.hardCtx)
+	aAPI, tAPI, err := a.client.ConnectRPC27(a.hardCtx)
 	if err != nil {
 		return err
 	}
@@ -942,7 +973,7 @@ func (a *agent) run() (retErr error) {
 	connMan := newAPIConnRoutineManager(a.gracefulCtx, a.hardCtx, a.logger, aAPI, tAPI)
 
 	connMan.startAgentAPI("init notification banners", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 			bannersProto, err := aAPI.GetAnnouncementBanners(ctx, &proto.GetAnnouncementBannersRequest{})
 			if err != nil {
 				return xerrors.Errorf("fetch service banner: %w", err)
@@ -959,7 +990,7 @@ func (a *agent) run() (retErr error) {
 	// sending logs gets gracefulShutdownBehaviorRemain because we want to send logs generated by
 	// shutdown scripts.
 	connMan.startAgentAPI("send logs", gracefulShutdownBehaviorRemain,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 			err := a.logSender.SendLoop(ctx, aAPI)
 			if xerrors.Is(err, agentsdk.ErrLogLimitExceeded) {
 				// we don't want this error to tear down the API connection and propagate to the
@@ -978,7 +1009,7 @@ func (a *agent) run() (retErr error) {
 	connMan.startAgentAPI("report metadata", gracefulShutdownBehaviorStop, a.reportMetadata)
 
 	// resources monitor can cease as soon as we start gracefully shutting down.
-	connMan.startAgentAPI("resources monitor", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+	connMan.startAgentAPI("resources monitor", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 		logger := a.logger.Named("resources_monitor")
 		clk := quartz.NewReal()
 		config, err := aAPI.GetResourcesMonitoringConfiguration(ctx, &proto.GetResourcesMonitoringConfigurationRequest{})
@@ -999,10 +1030,55 @@ func (a *agent) run() (retErr error) {
 		return resourcesmonitor.Start(ctx)
 	})
 
+	// resource usage reporting can cease as soon as we start gracefully shutting down.
+	connMan.startAgentAPI("report resource usage", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
+		statfetcher, err := clistat.New()
+		if err != nil {
+			return xerrors.Errorf("failed to create resources fetcher: %w", err)
+		}
+		resourcesFetcher, err := resourcesmonitor.NewFetcher(statfetcher)
+		if err != nil {
+			return xerrors.Errorf("new resource fetcher: %w", err)
+		}
+
+		reporter := resourcesmonitor.NewUsageReporter(a.logger.Named("resource_usage"), quartz.NewReal(), resourcesFetcher, aAPI)
+		return reporter.Start(ctx)
+	})
+
+	// Like resource usage reporting, health checks can cease as soon as we
+	// start gracefully shutting down.
+	connMan.startAgentAPI("watch host health", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
+		statfetcher, err := clistat.New()
+		if err != nil {
+			return xerrors.Errorf("failed to create resources fetcher: %w", err)
+		}
+		resourcesFetcher, err := resourcesmonitor.NewFetcher(statfetcher)
+		if err != nil {
+			return xerrors.Errorf("new resource fetcher: %w", err)
+		}
+
+		var volumes []string
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			volumes = append(volumes, homeDir)
+		} else {
+			a.logger.Warn(ctx, "failed to get home directory, not watching its disk space", slog.Error(err))
+		}
+
+		watcher := resourcesmonitor.NewHealthWatcher(a.logger.Named("health_watcher"), quartz.NewReal(), resourcesFetcher, volumes, aAPI)
+		return watcher.Start(ctx)
+	})
+
 	// Connection reports are part of auditing, we should keep sending them via
 	// gracefulShutdownBehaviorRemain.
 	connMan.startAgentAPI("report connections", gracefulShutdownBehaviorRemain, a.reportConnectionsLoop)
 
+	// Session recordings are part of auditing too, so keep sending them
+	// while shutting down.
+	connMan.startAgentAPI("report session recordings", gracefulShutdownBehaviorRemain,
+		func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
+			return a.sessionRecorder.Run(ctx, aAPI)
+		})
+
 	// channels to sync goroutines below
 	//  handle manifest
 	//       |
@@ -1025,7 +1101,7 @@ func (a *agent) run() (retErr error) {
 	connMan.startAgentAPI("handle manifest", gracefulShutdownBehaviorStop, a.handleManifest(manifestOK))
 
 	connMan.startAgentAPI("app health reporter", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 			if err := manifestOK.wait(ctx); err != nil {
 				return xerrors.Errorf("no manifest: %w", err)
 			}
@@ -1058,7 +1134,7 @@ func (a *agent) run() (retErr error) {
 
 	connMan.startAgentAPI("fetch service banner loop", gracefulShutdownBehaviorStop, a.fetchServiceBannerLoop)
 
-	connMan.startAgentAPI("stats report loop", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+	connMan.startAgentAPI("stats report loop", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 		if err := networkOK.wait(ctx); err != nil {
 			return xerrors.Errorf("no network: %w", err)
 		}
@@ -1073,8 +1149,8 @@ func (a *agent) run() (retErr error) {
 }
 
 // handleManifest returns a function that fetches and processes the manifest
-func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
-	return func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
+	return func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 		var (
 			sentResult = false
 			err        error
@@ -1136,6 +1212,7 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 			return xerrors.Errorf("update workspace agent startup: %w", err)
 		}
 
+		a.sessionRecorder.SetEnabled(manifest.SessionRecordingEnabled)
 		oldManifest := a.manifest.Swap(&manifest)
 		manifestOK.complete(nil)
 		sentResult = true
@@ -1196,6 +1273,11 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 				// finished (both start and post start). For instance, an
 				// autostarted devcontainer will be included in this time.
 				err := a.scriptRunner.Execute(a.gracefulCtx, agentscripts.ExecuteStartScripts)
+				if a.prebuildClaimed {
+					// The claim scripts personalize the workspace for its
+					// new owner, so they may depend on the start scripts.
+					err = errors.Join(err, a.scriptRunner.Execute(a.gracefulCtx, agentscripts.ExecuteClaimScripts))
+				}
 
 				if a.containerAPI != nil {
 					// Start the container API after the startup scripts have
@@ -1237,7 +1319,7 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 
 func (a *agent) createDevcontainer(
 	ctx context.Context,
-	aAPI proto.DRPCAgentClient26,
+	aAPI proto.DRPCAgentClient27,
 	dc codersdk.WorkspaceAgentDevcontainer,
 	script codersdk.WorkspaceAgentScript,
 ) (err error) {
@@ -1269,8 +1351,8 @@ func (a *agent) createDevcontainer(
 
 // createOrUpdateNetwork waits for the manifest to be set using manifestOK, then creates or updates
 // the tailnet using the information in the manifest
-func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(context.Context, proto.DRPCAgentClient26) error {
-	return func(ctx context.Context, aAPI proto.DRPCAgentClient26) (retErr error) {
+func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(context.Context, proto.DRPCAgentClient27) error {
+	return func(ctx context.Context, aAPI proto.DRPCAgentClient27) (retErr error) {
 		if err := manifestOK.wait(ctx); err != nil {
 			return xerrors.Errorf("no manifest: %w", err)
 		}
@@ -1722,6 +1804,10 @@ func (a *agent) Collect(ctx context.Context, networkStats map[netlogtype.Connect
 	durations := []float64{}
 	p2pConns := 0
 	derpConns := 0
+	if a.connectionQuality == nil {
+		a.connectionQuality = make(map[netip.Addr]*peerConnectionQuality)
+	}
+	activePeers := make(map[netip.Addr]struct{})
 	pingCtx, cancelFunc := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelFunc()
 	for nodeID, peer := range status.Peer {
@@ -1735,24 +1821,48 @@ func (a *agent) Collect(ctx context.Context, networkStats map[netlogtype.Connect
 		if len(addresses) == 0 {
 			continue
 		}
+		addr := addresses[0].Addr()
+		quality, ok := a.connectionQuality[addr]
+		if !ok {
+			quality = &peerConnectionQuality{}
+			a.connectionQuality[addr] = quality
+		}
+		quality.lastHandshake = peer.LastHandshake
+		activePeers[addr] = struct{}{}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			duration, p2p, _, err := a.network.Ping(pingCtx, addresses[0].Addr())
+			duration, p2p, pr, err := a.network.Ping(pingCtx, addr)
+			mu.Lock()
+			defer mu.Unlock()
+			quality.pingsSent++
 			if err != nil {
+				quality.pingsLost++
 				return
 			}
-			mu.Lock()
-			defer mu.Unlock()
 			durations = append(durations, float64(duration.Microseconds()))
+			quality.latency = duration
+			quality.p2p = p2p
+			quality.derpRegionCode = ""
 			if p2p {
 				p2pConns++
 			} else {
 				derpConns++
+				quality.derpRegionCode = pr.DERPRegionCode
 			}
 		}()
 	}
 	wg.Wait()
+	for addr, quality := range a.connectionQuality {
+		if _, ok := activePeers[addr]; !ok {
+			delete(a.connectionQuality, addr)
+			continue
+		}
+		stats.ConnectionQuality = append(stats.ConnectionQuality, quality.proto(addr))
+	}
+	slices.SortFunc(stats.ConnectionQuality, func(a, b *proto.Stats_ConnectionQuality) int {
+		return strings.Compare(a.Ip, b.Ip)
+	})
 	sort.Float64s(durations)
 	durationsLength := len(durations)
 	switch {
@@ -2051,7 +2161,7 @@ const (
 
 type apiConnRoutineManager struct {
 	logger    slog.Logger
-	aAPI      proto.DRPCAgentClient26
+	aAPI      proto.DRPCAgentClient27
 	tAPI      tailnetproto.DRPCTailnetClient24
 	eg        *errgroup.Group
 	stopCtx   context.Context
@@ -2060,7 +2170,7 @@ type apiConnRoutineManager struct {
 
 func newAPIConnRoutineManager(
 	gracefulCtx, hardCtx context.Context, logger slog.Logger,
-	aAPI proto.DRPCAgentClient26, tAPI tailnetproto.DRPCTailnetClient24,
+	aAPI proto.DRPCAgentClient27, tAPI tailnetproto.DRPCTailnetClient24,
 ) *apiConnRoutineManager {
 	// routines that remain in operation during graceful shutdown use the remainCtx.  They'll still
 	// exit if the errgroup hits an error, which usually means a problem with the conn.
@@ -2093,7 +2203,7 @@ func newAPIConnRoutineManager(
 // but for Tailnet.
 func (a *apiConnRoutineManager) startAgentAPI(
 	name string, behavior gracefulShutdownBehavior,
-	f func(context.Context, proto.DRPCAgentClient26) error,
+	f func(context.Context, proto.DRPCAgentClient27) error,
 ) {
 	logger := a.logger.With(slog.F("name", name))
 	var ctx context.Context
@@ -195,6 +195,33 @@ func TestAgent_Stats_ReconnectingPTY(t *testing.T) {
 	)
 }
 
+func TestAgent_Stats_ConnectionQuality(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	//nolint:dogsled
+	conn, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	require.True(t, conn.AwaitReachable(ctx))
+
+	var s *proto.Stats
+	require.Eventuallyf(t, func() bool {
+		var ok bool
+		s, ok = <-stats
+		if !ok || len(s.ConnectionQuality) != 1 {
+			return false
+		}
+		quality := s.ConnectionQuality[0]
+		return quality.Ip != "" &&
+			quality.PingsSent > 0 &&
+			quality.PingsLost < quality.PingsSent &&
+			quality.Latency != nil
+	}, testutil.WaitLong, testutil.IntervalFast,
+		"never saw connection quality: %+v", s,
+	)
+}
+
 func TestAgent_Stats_Magic(t *testing.T) {
 	t.Parallel()
 	t.Run("StripsEnvironmentVariable", func(t *testing.T) {
@@ -514,6 +541,51 @@ func TestAgent_SessionTTYExitCode(t *testing.T) {
 	}
 }
 
+func TestAgent_SessionRecording(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		t.Parallel()
+
+		var agentClient *agenttest.Client
+		session := setupSSHSession(t, agentsdk.Manifest{SessionRecordingEnabled: true}, codersdk.ServiceBannerConfig{}, nil, func(c *agenttest.Client, _ *agent.Options) {
+			agentClient = c
+		})
+		err := session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		output, err := session.Output("echo recorded-output")
+		require.NoError(t, err)
+		require.Contains(t, string(output), "recorded-output")
+
+		var recordings []agenttest.FakeSessionRecording
+		require.Eventually(t, func() bool {
+			recordings = agentClient.GetSessionRecordings()
+			return len(recordings) == 1 && recordings[0].Ended
+		}, testutil.WaitMedium, testutil.IntervalFast)
+		require.Equal(t, proto.CreateSessionRecordingRequest_SSH, recordings[0].Request.GetType())
+		require.EqualValues(t, 80, recordings[0].Request.GetWidth())
+		require.EqualValues(t, 24, recordings[0].Request.GetHeight())
+		require.Contains(t, string(recordings[0].Output()), "recorded-output")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var agentClient *agenttest.Client
+		session := setupSSHSession(t, agentsdk.Manifest{}, codersdk.ServiceBannerConfig{}, nil, func(c *agenttest.Client, _ *agent.Options) {
+			agentClient = c
+		})
+		err := session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		_, err = session.Output("echo not-recorded")
+		require.NoError(t, err)
+		require.Empty(t, agentClient.GetSessionRecordings())
+	})
+}
+
 func TestAgent_Session_TTY_MOTD(t *testing.T) {
 	t.Parallel()
 	if runtime.GOOS == "windows" {
@@ -1516,6 +1588,58 @@ func TestAgent_Lifecycle(t *testing.T) {
 		require.Equal(t, want, got)
 	})
 
+	t.Run("ClaimScriptsRunWhenClaimed", func(t *testing.T) {
+		t.Parallel()
+
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "false",
+				Timeout:    30 * time.Second,
+				RunOnClaim: true,
+			}},
+		}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.PrebuildClaimed = true
+		})
+
+		want := []codersdk.WorkspaceAgentLifecycle{
+			codersdk.WorkspaceAgentLifecycleStarting,
+			codersdk.WorkspaceAgentLifecycleStartError,
+		}
+
+		var got []codersdk.WorkspaceAgentLifecycle
+		assert.Eventually(t, func() bool {
+			got = client.GetLifecycleStates()
+			return slices.Contains(got, want[len(want)-1])
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		require.Equal(t, want, got[:len(want)])
+	})
+
+	t.Run("ClaimScriptsSkippedWhenNotClaimed", func(t *testing.T) {
+		t.Parallel()
+
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "false",
+				Timeout:    30 * time.Second,
+				RunOnClaim: true,
+			}},
+		}, 0)
+
+		want := []codersdk.WorkspaceAgentLifecycle{
+			codersdk.WorkspaceAgentLifecycleStarting,
+			codersdk.WorkspaceAgentLifecycleReady,
+		}
+
+		var got []codersdk.WorkspaceAgentLifecycle
+		assert.Eventually(t, func() bool {
+			got = client.GetLifecycleStates()
+			return len(got) > 0 && got[len(got)-1] == want[len(want)-1]
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		require.Equal(t, want, got)
+	})
+
 	t.Run("ShuttingDown", func(t *testing.T) {
 		t.Parallel()
 
@@ -3426,6 +3550,16 @@ func assertConnectionReport(t testing.TB, agentClient *agenttest.Client, connect
 	assert.Equal(t, 0, int(reports[0].GetConnection().GetStatusCode()), "connect status code should be 0")
 	assert.Equal(t, status, int(reports[1].GetConnection().GetStatusCode()), "disconnect status code should be %d", status)
 	assert.Equal(t, "", reports[0].GetConnection().GetReason(), "connect reason should be empty")
+	assert.Nil(t, reports[0].GetConnection().GetDuration(), "connect duration should be unset")
+	assert.NotNil(t, reports[1].GetConnection().GetDuration(), "disconnect duration should be set")
+	assert.GreaterOrEqual(t, reports[1].GetConnection().GetDuration().AsDuration(), time.Duration(0), "disconnect duration should not be negative")
+	if connectionType == proto.Connection_RECONNECTING_PTY {
+		assert.Empty(t, reports[0].GetConnection().GetClientVersion(), "reconnecting pty connections have no client version")
+	} else {
+		// Tests connect with the SSH client from workspacesdk.
+		assert.Equal(t, workspacesdk.AgentSSHClientVersion, reports[0].GetConnection().GetClientVersion(), "connect client version")
+		assert.Equal(t, workspacesdk.AgentSSHClientVersion, reports[1].GetConnection().GetClientVersion(), "disconnect client version")
+	}
 	if reason != "" {
 		assert.Contains(t, reports[1].GetConnection().GetReason(), reason, "disconnect reason should contain %s", reason)
 	} else {
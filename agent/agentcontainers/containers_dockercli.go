@@ -367,9 +367,15 @@ type dockerInspectMount struct {
 }
 
 type dockerInspectState struct {
-	Running  bool   `json:"Running"`
-	ExitCode int    `json:"ExitCode"`
-	Error    string `json:"Error"`
+	Running  bool                 `json:"Running"`
+	ExitCode int                  `json:"ExitCode"`
+	Error    string               `json:"Error"`
+	Health   *dockerInspectHealth `json:"Health"`
+}
+
+// dockerInspectHealth is only present for containers with a health check.
+type dockerInspectHealth struct {
+	Status string `json:"Status"`
 }
 
 type dockerInspectNetworkSettings struct {
@@ -393,6 +399,22 @@ func (dis dockerInspectState) String() string {
 	return sb.String()
 }
 
+// health returns the result of the health check of the container, or an
+// empty string if the container has no health check.
+func (dis dockerInspectState) health() codersdk.WorkspaceAgentContainerHealth {
+	if dis.Health == nil {
+		return ""
+	}
+	switch health := codersdk.WorkspaceAgentContainerHealth(dis.Health.Status); health {
+	case codersdk.WorkspaceAgentContainerHealthStarting,
+		codersdk.WorkspaceAgentContainerHealthHealthy,
+		codersdk.WorkspaceAgentContainerHealthUnhealthy:
+		return health
+	default:
+		return ""
+	}
+}
+
 func convertDockerInspect(raw []byte) ([]codersdk.WorkspaceAgentContainer, []string, error) {
 	var warns []string
 	var ins []dockerInspect
@@ -425,6 +447,7 @@ func convertDockerInspect(raw []byte) ([]codersdk.WorkspaceAgentContainer, []str
 			Ports:        make([]codersdk.WorkspaceAgentContainerPort, 0),
 			Running:      in.State.Running,
 			Status:       in.State.String(),
+			Health:       in.State.health(),
 			Volumes:      make(map[string]string, len(in.Mounts)),
 		}
 
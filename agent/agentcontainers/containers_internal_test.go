@@ -182,6 +182,23 @@ func TestConvertDockerInspect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "container_health",
+			expect: []codersdk.WorkspaceAgentContainer{
+				{
+					CreatedAt:    time.Date(2025, 3, 11, 17, 55, 58, 91280203, time.UTC),
+					ID:           "3f2c5b4a1e0d9c8b7a6f5e4d3c2b1a09f8e7d6c5b4a3928170f6e5d4c3b2a190",
+					FriendlyName: "quirky_lovelace",
+					Image:        "debian:bookworm",
+					Labels:       map[string]string{},
+					Running:      true,
+					Status:       "running",
+					Health:       codersdk.WorkspaceAgentContainerHealthUnhealthy,
+					Ports:        []codersdk.WorkspaceAgentContainerPort{},
+					Volumes:      map[string]string{},
+				},
+			},
+		},
 		{
 			name: "container_labels",
 			expect: []codersdk.WorkspaceAgentContainer{
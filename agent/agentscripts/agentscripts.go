@@ -196,6 +196,7 @@ const (
 	ExecuteStartScripts
 	ExecuteStopScripts
 	ExecuteCronScripts
+	ExecuteClaimScripts
 )
 
 // Execute runs a set of scripts according to a filter.
@@ -217,6 +218,9 @@ func (r *Runner) Execute(ctx context.Context, option ExecuteOption) error {
 		runScript := (option == ExecuteStartScripts && script.RunOnStart) ||
 			(option == ExecuteStopScripts && script.RunOnStop) ||
 			(option == ExecuteCronScripts && script.Cron != "") ||
+			// Scripts that also run on start have already run as part
+			// of the startup by the time the claim scripts run.
+			(option == ExecuteClaimScripts && script.RunOnClaim && !script.RunOnStart) ||
 			option == ExecuteAllScripts
 
 		if !runScript {
@@ -364,7 +368,9 @@ func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript,
 		err = r.trackCommandGoroutine(func() {
 			var stage proto.Timing_Stage
 			switch option {
-			case ExecuteStartScripts:
+			case ExecuteStartScripts, ExecuteClaimScripts:
+				// Claim scripts run as part of the startup of the
+				// agent, once it has reinitialized for the new owner.
 				stage = proto.Timing_START
 			case ExecuteStopScripts:
 				stage = proto.Timing_STOP
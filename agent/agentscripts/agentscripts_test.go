@@ -176,6 +176,19 @@ func TestExecuteOptions(t *testing.T) {
 		Script:      "echo stop",
 		RunOnStop:   true,
 	}
+	claimScript := codersdk.WorkspaceAgentScript{
+		ID:          uuid.New(),
+		LogSourceID: uuid.New(),
+		Script:      "echo claim",
+		RunOnClaim:  true,
+	}
+	startAndClaimScript := codersdk.WorkspaceAgentScript{
+		ID:          uuid.New(),
+		LogSourceID: uuid.New(),
+		Script:      "echo start and claim",
+		RunOnStart:  true,
+		RunOnClaim:  true,
+	}
 	regularScript := codersdk.WorkspaceAgentScript{
 		ID:          uuid.New(),
 		LogSourceID: uuid.New(),
@@ -185,6 +198,8 @@ func TestExecuteOptions(t *testing.T) {
 	scripts := []codersdk.WorkspaceAgentScript{
 		startScript,
 		stopScript,
+		claimScript,
+		startAndClaimScript,
 		regularScript,
 	}
 
@@ -199,9 +214,11 @@ func TestExecuteOptions(t *testing.T) {
 	}
 
 	wantOutput := map[uuid.UUID]string{
-		startScript.ID:   "start",
-		stopScript.ID:    "stop",
-		regularScript.ID: "regular",
+		startScript.ID:         "start",
+		stopScript.ID:          "stop",
+		claimScript.ID:         "claim",
+		startAndClaimScript.ID: "start and claim",
+		regularScript.ID:       "regular",
 	}
 
 	testCases := []struct {
@@ -212,18 +229,23 @@ func TestExecuteOptions(t *testing.T) {
 		{
 			name:    "ExecuteAllScripts",
 			option:  agentscripts.ExecuteAllScripts,
-			wantRun: []uuid.UUID{startScript.ID, stopScript.ID, regularScript.ID},
+			wantRun: []uuid.UUID{startScript.ID, stopScript.ID, claimScript.ID, startAndClaimScript.ID, regularScript.ID},
 		},
 		{
 			name:    "ExecuteStartScripts",
 			option:  agentscripts.ExecuteStartScripts,
-			wantRun: []uuid.UUID{startScript.ID},
+			wantRun: []uuid.UUID{startScript.ID, startAndClaimScript.ID},
 		},
 		{
 			name:    "ExecuteStopScripts",
 			option:  agentscripts.ExecuteStopScripts,
 			wantRun: []uuid.UUID{stopScript.ID},
 		},
+		{
+			name:    "ExecuteClaimScripts",
+			option:  agentscripts.ExecuteClaimScripts,
+			wantRun: []uuid.UUID{claimScript.ID},
+		},
 	}
 
 	for _, tc := range testCases {
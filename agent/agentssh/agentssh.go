@@ -87,7 +87,9 @@ const (
 // BlockedFileTransferCommands contains a list of restricted file transfer commands.
 var BlockedFileTransferCommands = []string{"nc", "rsync", "scp", "sftp"}
 
-type reportConnectionFunc func(id uuid.UUID, sessionType MagicSessionType, ip string) (disconnected func(code int, reason string))
+// reportConnectionFunc reports a connection to the agent. The client version
+// is the version string sent by the SSH client, e.g. "SSH-2.0-OpenSSH_9.6".
+type reportConnectionFunc func(id uuid.UUID, sessionType MagicSessionType, ip, clientVersion string) (disconnected func(code int, reason string))
 
 // Config sets configuration parameters for the agent SSH server.
 type Config struct {
@@ -121,6 +123,10 @@ type Config struct {
 	// forwarding listeners. When nil, a default implementation backed by the
 	// standard library networking package is used.
 	X11Net X11Network
+	// RecordSession returns a writer that records the output of an
+	// interactive session with a terminal of the given size, or nil if the
+	// session should not be recorded.
+	RecordSession func(width, height int) io.WriteCloser
 }
 
 type Server struct {
@@ -175,7 +181,7 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 		}
 	}
 	if config.ReportConnection == nil {
-		config.ReportConnection = func(uuid.UUID, MagicSessionType, string) func(int, string) { return func(int, string) {} }
+		config.ReportConnection = func(uuid.UUID, MagicSessionType, string, string) func(int, string) { return func(int, string) {} }
 	}
 
 	forwardHandler := &ssh.ForwardedTCPHandler{}
@@ -390,7 +396,7 @@ func (s *Server) sessionHandler(session ssh.Session) {
 	if !s.trackSession(session, true) {
 		reason := "unable to accept new session, server is closing"
 		// Report connection attempt even if we couldn't accept it.
-		disconnected := s.config.ReportConnection(id, magicType, session.RemoteAddr().String())
+		disconnected := s.config.ReportConnection(id, magicType, session.RemoteAddr().String(), ctx.ClientVersion())
 		defer disconnected(1, reason)
 
 		logger.Info(ctx, reason)
@@ -425,7 +431,7 @@ func (s *Server) sessionHandler(session ssh.Session) {
 		scr := &sessionCloseTracker{Session: session}
 		session = scr
 
-		disconnected := s.config.ReportConnection(id, magicType, session.RemoteAddr().String())
+		disconnected := s.config.ReportConnection(id, magicType, session.RemoteAddr().String(), ctx.ClientVersion())
 		defer func() {
 			disconnected(scr.exitCode(), reason)
 		}()
@@ -754,7 +760,14 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 	//    after we've Read() all the buffered data from the PTY.
 	// 2. The client hangs up, which cancels the command's Context, and go will
 	//    kill the command's process.  This then has the same effect as (1).
-	n, err := io.Copy(session, ptty.OutputReader())
+	var output io.Writer = session
+	if s.config.RecordSession != nil {
+		if rec := s.config.RecordSession(sshPty.Window.Width, sshPty.Window.Height); rec != nil {
+			defer rec.Close()
+			output = io.MultiWriter(session, rec)
+		}
+	}
+	n, err := io.Copy(output, ptty.OutputReader())
 	logger.Debug(ctx, "copy output done", slog.F("bytes", n), slog.Error(err))
 	if err != nil {
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "output_io_copy").Add(1)
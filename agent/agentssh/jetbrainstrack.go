@@ -30,6 +30,7 @@ type JetbrainsChannelWatcher struct {
 	jetbrainsCounter *atomic.Int64
 	logger           slog.Logger
 	originAddr       string
+	clientVersion    string
 	reportConnection reportConnectionFunc
 }
 
@@ -65,12 +66,13 @@ func NewJetbrainsChannelWatcher(ctx ssh.Context, logger slog.Logger, reportConne
 		jetbrainsCounter: counter,
 		logger:           logger.With(slog.F("destination_port", d.DestPort)),
 		originAddr:       d.OriginAddr,
+		clientVersion:    ctx.ClientVersion(),
 		reportConnection: reportConnection,
 	}
 }
 
 func (w *JetbrainsChannelWatcher) Accept() (gossh.Channel, <-chan *gossh.Request, error) {
-	disconnected := w.reportConnection(uuid.New(), MagicSessionTypeJetBrains, w.originAddr)
+	disconnected := w.reportConnection(uuid.New(), MagicSessionTypeJetBrains, w.originAddr, w.clientVersion)
 
 	c, r, err := w.NewChannel.Accept()
 	if err != nil {
@@ -98,8 +98,8 @@ func (c *Client) Close() {
 	c.derpMapOnce.Do(func() { close(c.derpMapUpdates) })
 }
 
-func (c *Client) ConnectRPC26(ctx context.Context) (
-	agentproto.DRPCAgentClient26, proto.DRPCTailnetClient26, error,
+func (c *Client) ConnectRPC27(ctx context.Context) (
+	agentproto.DRPCAgentClient27, proto.DRPCTailnetClient27, error,
 ) {
 	conn, lis := drpcsdk.MemTransportPipe()
 	c.LastWorkspaceAgent = func() {
@@ -163,6 +163,10 @@ func (c *Client) GetConnectionReports() []*agentproto.ReportConnectionRequest {
 	return c.fakeAgentAPI.GetConnectionReports()
 }
 
+func (c *Client) GetSessionRecordings() []FakeSessionRecording {
+	return c.fakeAgentAPI.GetSessionRecordings()
+}
+
 func (c *Client) GetSubAgents() []*agentproto.SubAgent {
 	return c.fakeAgentAPI.GetSubAgents()
 }
@@ -193,6 +197,9 @@ type FakeAgentAPI struct {
 	metadata            map[string]agentsdk.Metadata
 	timings             []*agentproto.Timing
 	connectionReports   []*agentproto.ReportConnectionRequest
+	resourceUsage       []*agentproto.PushResourceUsageRequest
+	healthEvents        []*agentproto.HealthEvent
+	sessionRecordings   map[uuid.UUID]*FakeSessionRecording
 	subAgents           map[uuid.UUID]*agentproto.SubAgent
 	subAgentDirs        map[uuid.UUID]string
 	subAgentDisplayApps map[uuid.UUID][]agentproto.CreateSubAgentRequest_DisplayApp
@@ -385,6 +392,102 @@ func (f *FakeAgentAPI) GetConnectionReports() []*agentproto.ReportConnectionRequ
 	return slices.Clone(f.connectionReports)
 }
 
+func (f *FakeAgentAPI) PushResourceUsage(_ context.Context, req *agentproto.PushResourceUsageRequest) (*agentproto.PushResourceUsageResponse, error) {
+	f.Lock()
+	f.resourceUsage = append(f.resourceUsage, req)
+	f.Unlock()
+
+	return &agentproto.PushResourceUsageResponse{}, nil
+}
+
+func (f *FakeAgentAPI) GetResourceUsage() []*agentproto.PushResourceUsageRequest {
+	f.Lock()
+	defer f.Unlock()
+	return slices.Clone(f.resourceUsage)
+}
+
+func (f *FakeAgentAPI) ReportHealthEvents(_ context.Context, req *agentproto.ReportHealthEventsRequest) (*agentproto.ReportHealthEventsResponse, error) {
+	f.Lock()
+	f.healthEvents = append(f.healthEvents, req.GetEvents()...)
+	f.Unlock()
+
+	return &agentproto.ReportHealthEventsResponse{}, nil
+}
+
+func (f *FakeAgentAPI) GetHealthEvents() []*agentproto.HealthEvent {
+	f.Lock()
+	defer f.Unlock()
+	return slices.Clone(f.healthEvents)
+}
+
+// FakeSessionRecording is a session recording received by the FakeAgentAPI.
+type FakeSessionRecording struct {
+	Request *agentproto.CreateSessionRecordingRequest
+	Chunks  []*agentproto.AppendSessionRecordingRequest_Chunk
+	Ended   bool
+}
+
+// Output returns all the output appended to the recording.
+func (r FakeSessionRecording) Output() []byte {
+	var output []byte
+	for _, chunk := range r.Chunks {
+		output = append(output, chunk.GetData()...)
+	}
+	return output
+}
+
+func (f *FakeAgentAPI) CreateSessionRecording(_ context.Context, req *agentproto.CreateSessionRecordingRequest) (*agentproto.CreateSessionRecordingResponse, error) {
+	id, err := uuid.FromBytes(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	if f.sessionRecordings == nil {
+		f.sessionRecordings = make(map[uuid.UUID]*FakeSessionRecording)
+	}
+	if _, ok := f.sessionRecordings[id]; !ok {
+		f.sessionRecordings[id] = &FakeSessionRecording{Request: req}
+	}
+	return &agentproto.CreateSessionRecordingResponse{}, nil
+}
+
+func (f *FakeAgentAPI) AppendSessionRecording(_ context.Context, req *agentproto.AppendSessionRecordingRequest) (*agentproto.AppendSessionRecordingResponse, error) {
+	id, err := uuid.FromBytes(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	recording, ok := f.sessionRecordings[id]
+	if !ok {
+		return nil, xerrors.Errorf("session recording %s not found", id)
+	}
+	if recording.Ended {
+		return nil, xerrors.Errorf("session recording %s has ended", id)
+	}
+	recording.Chunks = append(recording.Chunks, req.GetChunks()...)
+	recording.Ended = req.EndedAt != nil
+	return &agentproto.AppendSessionRecordingResponse{}, nil
+}
+
+// GetSessionRecordings returns copies of the session recordings received.
+func (f *FakeAgentAPI) GetSessionRecordings() []FakeSessionRecording {
+	f.Lock()
+	defer f.Unlock()
+	recordings := make([]FakeSessionRecording, 0, len(f.sessionRecordings))
+	for _, recording := range f.sessionRecordings {
+		recordings = append(recordings, FakeSessionRecording{
+			Request: recording.Request,
+			Chunks:  slices.Clone(recording.Chunks),
+			Ended:   recording.Ended,
+		})
+	}
+	return recordings
+}
+
 func (f *FakeAgentAPI) CreateSubAgent(ctx context.Context, req *agentproto.CreateSubAgentRequest) (*agentproto.CreateSubAgentResponse, error) {
 	f.Lock()
 	defer f.Unlock()
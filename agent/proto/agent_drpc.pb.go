@@ -55,6 +55,10 @@ type DRPCAgentClient interface {
 	CreateSubAgent(ctx context.Context, in *CreateSubAgentRequest) (*CreateSubAgentResponse, error)
 	DeleteSubAgent(ctx context.Context, in *DeleteSubAgentRequest) (*DeleteSubAgentResponse, error)
 	ListSubAgents(ctx context.Context, in *ListSubAgentsRequest) (*ListSubAgentsResponse, error)
+	PushResourceUsage(ctx context.Context, in *PushResourceUsageRequest) (*PushResourceUsageResponse, error)
+	ReportHealthEvents(ctx context.Context, in *ReportHealthEventsRequest) (*ReportHealthEventsResponse, error)
+	CreateSessionRecording(ctx context.Context, in *CreateSessionRecordingRequest) (*CreateSessionRecordingResponse, error)
+	AppendSessionRecording(ctx context.Context, in *AppendSessionRecordingRequest) (*AppendSessionRecordingResponse, error)
 }
 
 type drpcAgentClient struct {
@@ -211,6 +215,42 @@ func (c *drpcAgentClient) ListSubAgents(ctx context.Context, in *ListSubAgentsRe
 	return out, nil
 }
 
+func (c *drpcAgentClient) PushResourceUsage(ctx context.Context, in *PushResourceUsageRequest) (*PushResourceUsageResponse, error) {
+	out := new(PushResourceUsageResponse)
+	err := c.cc.Invoke(ctx, "/coder.agent.v2.Agent/PushResourceUsage", drpcEncoding_File_agent_proto_agent_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcAgentClient) ReportHealthEvents(ctx context.Context, in *ReportHealthEventsRequest) (*ReportHealthEventsResponse, error) {
+	out := new(ReportHealthEventsResponse)
+	err := c.cc.Invoke(ctx, "/coder.agent.v2.Agent/ReportHealthEvents", drpcEncoding_File_agent_proto_agent_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcAgentClient) CreateSessionRecording(ctx context.Context, in *CreateSessionRecordingRequest) (*CreateSessionRecordingResponse, error) {
+	out := new(CreateSessionRecordingResponse)
+	err := c.cc.Invoke(ctx, "/coder.agent.v2.Agent/CreateSessionRecording", drpcEncoding_File_agent_proto_agent_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcAgentClient) AppendSessionRecording(ctx context.Context, in *AppendSessionRecordingRequest) (*AppendSessionRecordingResponse, error) {
+	out := new(AppendSessionRecordingResponse)
+	err := c.cc.Invoke(ctx, "/coder.agent.v2.Agent/AppendSessionRecording", drpcEncoding_File_agent_proto_agent_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCAgentServer interface {
 	GetManifest(context.Context, *GetManifestRequest) (*Manifest, error)
 	GetServiceBanner(context.Context, *GetServiceBannerRequest) (*ServiceBanner, error)
@@ -228,6 +268,10 @@ type DRPCAgentServer interface {
 	CreateSubAgent(context.Context, *CreateSubAgentRequest) (*CreateSubAgentResponse, error)
 	DeleteSubAgent(context.Context, *DeleteSubAgentRequest) (*DeleteSubAgentResponse, error)
 	ListSubAgents(context.Context, *ListSubAgentsRequest) (*ListSubAgentsResponse, error)
+	PushResourceUsage(context.Context, *PushResourceUsageRequest) (*PushResourceUsageResponse, error)
+	ReportHealthEvents(context.Context, *ReportHealthEventsRequest) (*ReportHealthEventsResponse, error)
+	CreateSessionRecording(context.Context, *CreateSessionRecordingRequest) (*CreateSessionRecordingResponse, error)
+	AppendSessionRecording(context.Context, *AppendSessionRecordingRequest) (*AppendSessionRecordingResponse, error)
 }
 
 type DRPCAgentUnimplementedServer struct{}
@@ -296,9 +340,25 @@ func (s *DRPCAgentUnimplementedServer) ListSubAgents(context.Context, *ListSubAg
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCAgentUnimplementedServer) PushResourceUsage(context.Context, *PushResourceUsageRequest) (*PushResourceUsageResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCAgentUnimplementedServer) ReportHealthEvents(context.Context, *ReportHealthEventsRequest) (*ReportHealthEventsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCAgentUnimplementedServer) CreateSessionRecording(context.Context, *CreateSessionRecordingRequest) (*CreateSessionRecordingResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCAgentUnimplementedServer) AppendSessionRecording(context.Context, *AppendSessionRecordingRequest) (*AppendSessionRecordingResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCAgentDescription struct{}
 
-func (DRPCAgentDescription) NumMethods() int { return 16 }
+func (DRPCAgentDescription) NumMethods() int { return 20 }
 
 func (DRPCAgentDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -446,6 +506,42 @@ func (DRPCAgentDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*ListSubAgentsRequest),
 					)
 			}, DRPCAgentServer.ListSubAgents, true
+	case 16:
+		return "/coder.agent.v2.Agent/PushResourceUsage", drpcEncoding_File_agent_proto_agent_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAgentServer).
+					PushResourceUsage(
+						ctx,
+						in1.(*PushResourceUsageRequest),
+					)
+			}, DRPCAgentServer.PushResourceUsage, true
+	case 17:
+		return "/coder.agent.v2.Agent/ReportHealthEvents", drpcEncoding_File_agent_proto_agent_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAgentServer).
+					ReportHealthEvents(
+						ctx,
+						in1.(*ReportHealthEventsRequest),
+					)
+			}, DRPCAgentServer.ReportHealthEvents, true
+	case 18:
+		return "/coder.agent.v2.Agent/CreateSessionRecording", drpcEncoding_File_agent_proto_agent_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAgentServer).
+					CreateSessionRecording(
+						ctx,
+						in1.(*CreateSessionRecordingRequest),
+					)
+			}, DRPCAgentServer.CreateSessionRecording, true
+	case 19:
+		return "/coder.agent.v2.Agent/AppendSessionRecording", drpcEncoding_File_agent_proto_agent_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAgentServer).
+					AppendSessionRecording(
+						ctx,
+						in1.(*AppendSessionRecordingRequest),
+					)
+			}, DRPCAgentServer.AppendSessionRecording, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -710,3 +806,67 @@ func (x *drpcAgent_ListSubAgentsStream) SendAndClose(m *ListSubAgentsResponse) e
 	}
 	return x.CloseSend()
 }
+
+type DRPCAgent_PushResourceUsageStream interface {
+	drpc.Stream
+	SendAndClose(*PushResourceUsageResponse) error
+}
+
+type drpcAgent_PushResourceUsageStream struct {
+	drpc.Stream
+}
+
+func (x *drpcAgent_PushResourceUsageStream) SendAndClose(m *PushResourceUsageResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_agent_proto_agent_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCAgent_ReportHealthEventsStream interface {
+	drpc.Stream
+	SendAndClose(*ReportHealthEventsResponse) error
+}
+
+type drpcAgent_ReportHealthEventsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcAgent_ReportHealthEventsStream) SendAndClose(m *ReportHealthEventsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_agent_proto_agent_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCAgent_CreateSessionRecordingStream interface {
+	drpc.Stream
+	SendAndClose(*CreateSessionRecordingResponse) error
+}
+
+type drpcAgent_CreateSessionRecordingStream struct {
+	drpc.Stream
+}
+
+func (x *drpcAgent_CreateSessionRecordingStream) SendAndClose(m *CreateSessionRecordingResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_agent_proto_agent_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCAgent_AppendSessionRecordingStream interface {
+	drpc.Stream
+	SendAndClose(*AppendSessionRecordingResponse) error
+}
+
+type drpcAgent_AppendSessionRecordingStream struct {
+	drpc.Stream
+}
+
+func (x *drpcAgent_AppendSessionRecordingStream) SendAndClose(m *AppendSessionRecordingResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_agent_proto_agent_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
@@ -65,3 +65,14 @@ type DRPCAgentClient26 interface {
 	DeleteSubAgent(ctx context.Context, in *DeleteSubAgentRequest) (*DeleteSubAgentResponse, error)
 	ListSubAgents(ctx context.Context, in *ListSubAgentsRequest) (*ListSubAgentsResponse, error)
 }
+
+// DRPCAgentClient27 is the Agent API at v2.7. It adds the PushResourceUsage,
+// ReportHealthEvents, CreateSessionRecording and AppendSessionRecording RPCs.
+// Compatible with Coder v2.25+
+type DRPCAgentClient27 interface {
+	DRPCAgentClient26
+	PushResourceUsage(ctx context.Context, in *PushResourceUsageRequest) (*PushResourceUsageResponse, error)
+	ReportHealthEvents(ctx context.Context, in *ReportHealthEventsRequest) (*ReportHealthEventsResponse, error)
+	CreateSessionRecording(ctx context.Context, in *CreateSessionRecordingRequest) (*CreateSessionRecordingResponse, error)
+	AppendSessionRecording(ctx context.Context, in *AppendSessionRecordingRequest) (*AppendSessionRecordingResponse, error)
+}
@@ -8,12 +8,15 @@ import (
 
 type Statter interface {
 	IsContainerized() (bool, error)
+	ContainerCPU() (*clistat.Result, error)
+	HostCPU() (*clistat.Result, error)
 	ContainerMemory(p clistat.Prefix) (*clistat.Result, error)
 	HostMemory(p clistat.Prefix) (*clistat.Result, error)
 	Disk(p clistat.Prefix, path string) (*clistat.Result, error)
 }
 
 type Fetcher interface {
+	FetchCPU() (total float64, used float64, err error)
 	FetchMemory() (total int64, used int64, err error)
 	FetchVolume(volume string) (total int64, used int64, err error)
 }
@@ -33,6 +36,40 @@ func NewFetcher(f Statter) (*fetcher, error) {
 	return &fetcher{f, isContainerized}, nil
 }
 
+func (f *fetcher) FetchCPU() (total float64, used float64, err error) {
+	var cpu *clistat.Result
+
+	if f.isContainerized {
+		cpu, err = f.ContainerCPU()
+		if err != nil {
+			return 0, 0, xerrors.Errorf("fetch container cpu: %w", err)
+		}
+	}
+
+	// The container might not be in a cgroup we can read, or might not have a
+	// CPU limit set. If this happens we want to fallback to the host.
+	if cpu == nil {
+		cpu, err = f.HostCPU()
+		if err != nil {
+			return 0, 0, xerrors.Errorf("fetch host cpu: %w", err)
+		}
+	}
+	if cpu.Total == nil {
+		hostCPU, err := f.HostCPU()
+		if err != nil {
+			return 0, 0, xerrors.Errorf("fetch host cpu: %w", err)
+		}
+
+		cpu.Total = hostCPU.Total
+	}
+
+	if cpu.Total == nil {
+		return 0, 0, xerrors.New("cpu total is nil - can not fetch cpu")
+	}
+
+	return *cpu.Total, cpu.Used, nil
+}
+
 func (f *fetcher) FetchMemory() (total int64, used int64, err error) {
 	var mem *clistat.Result
 
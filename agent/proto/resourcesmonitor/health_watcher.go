@@ -0,0 +1,151 @@
+package resourcesmonitor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/quartz"
+)
+
+// HealthCheckInterval is how often the agent checks its host for conditions
+// that are likely to cause failures, such as running out of memory.
+const HealthCheckInterval = 15 * time.Second
+
+const (
+	// NearOutOfMemoryPercent and LowDiskSpacePercent are the usage, as a
+	// percentage of the total, at which the conditions are detected.
+	NearOutOfMemoryPercent = 95
+	LowDiskSpacePercent    = 95
+	// healthRecoveryMargin is how far below the threshold usage must fall
+	// for a condition to be resolved, so that usage hovering around the
+	// threshold doesn't report the condition over and over.
+	healthRecoveryMargin = 5
+)
+
+type healthCondition struct {
+	kind   proto.HealthEvent_Kind
+	volume string
+}
+
+type healthWatcher struct {
+	logger               slog.Logger
+	clock                quartz.Clock
+	resourcesFetcher     Fetcher
+	volumes              []string
+	healthEventsReporter healthEventsReporter
+
+	// active holds whether each condition was ongoing when it was last
+	// reported. Conditions that are missing have not been reported yet.
+	active map[healthCondition]bool
+}
+
+type healthEventsReporter interface {
+	ReportHealthEvents(ctx context.Context, req *proto.ReportHealthEventsRequest) (*proto.ReportHealthEventsResponse, error)
+}
+
+// NewHealthWatcher returns a watcher which periodically checks whether the
+// agent's host is running out of memory, or the given volumes are running out
+// of space, and reports the conditions to coderd as they start and stop.
+//
+// The state of every condition is reported on the first check, so that
+// conditions which stopped while the agent was disconnected are resolved.
+//
+//nolint:revive
+func NewHealthWatcher(logger slog.Logger, clock quartz.Clock, resourcesFetcher Fetcher, volumes []string, healthEventsReporter healthEventsReporter) *healthWatcher {
+	return &healthWatcher{
+		logger:               logger,
+		clock:                clock,
+		resourcesFetcher:     resourcesFetcher,
+		volumes:              volumes,
+		healthEventsReporter: healthEventsReporter,
+		active:               make(map[healthCondition]bool),
+	}
+}
+
+func (w *healthWatcher) Start(ctx context.Context) error {
+	w.clock.TickerFunc(ctx, HealthCheckInterval, func() error {
+		w.check(ctx)
+		return nil
+	}, "health_watcher")
+
+	return nil
+}
+
+func (w *healthWatcher) check(ctx context.Context) {
+	observedAt := timestamppb.New(w.clock.Now())
+	events := make([]*proto.HealthEvent, 0)
+
+	memTotal, memUsed, err := w.resourcesFetcher.FetchMemory()
+	if err != nil {
+		w.logger.Error(ctx, "failed to fetch memory", slog.Error(err))
+	} else if event := w.evaluate(healthCondition{kind: proto.HealthEvent_NEAR_OUT_OF_MEMORY}, NearOutOfMemoryPercent, memTotal, memUsed); event != nil {
+		events = append(events, event)
+	}
+
+	for _, volume := range w.volumes {
+		volTotal, volUsed, err := w.resourcesFetcher.FetchVolume(volume)
+		if err != nil {
+			w.logger.Error(ctx, "failed to fetch volume", slog.F("volume", volume), slog.Error(err))
+			continue
+		}
+		if event := w.evaluate(healthCondition{kind: proto.HealthEvent_LOW_DISK_SPACE, volume: volume}, LowDiskSpacePercent, volTotal, volUsed); event != nil {
+			events = append(events, event)
+		}
+	}
+
+	if len(events) == 0 {
+		return
+	}
+	for _, event := range events {
+		event.ObservedAt = observedAt
+	}
+
+	_, err = w.healthEventsReporter.ReportHealthEvents(ctx, &proto.ReportHealthEventsRequest{
+		Events: events,
+	})
+	if err != nil {
+		// The events are reported again on the next check.
+		w.logger.Error(ctx, "failed to report health events", slog.Error(err))
+		return
+	}
+	for _, event := range events {
+		w.active[healthCondition{kind: event.Kind, volume: event.Volume}] = !event.Resolved
+	}
+}
+
+// evaluate returns the event to report for the condition, or nil if its state
+// hasn't changed since it was last reported.
+func (w *healthWatcher) evaluate(condition healthCondition, thresholdPercent int32, total, used int64) *proto.HealthEvent {
+	if total <= 0 {
+		return nil
+	}
+
+	percent := float64(used) / float64(total) * 100
+	active, reported := w.active[condition]
+
+	var resolved bool
+	switch {
+	case percent >= float64(thresholdPercent):
+		resolved = false
+	case percent < float64(thresholdPercent-healthRecoveryMargin) || !reported:
+		resolved = true
+	default:
+		return nil
+	}
+	if reported && active != resolved {
+		return nil
+	}
+
+	return &proto.HealthEvent{
+		Kind:             condition.kind,
+		Resolved:         resolved,
+		Volume:           condition.volume,
+		Used:             used,
+		Total:            total,
+		ThresholdPercent: thresholdPercent,
+	}
+}
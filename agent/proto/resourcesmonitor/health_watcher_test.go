@@ -0,0 +1,106 @@
+package resourcesmonitor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/agent/proto/resourcesmonitor"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+type healthEventsReporterMock struct {
+	err      error
+	requests []*proto.ReportHealthEventsRequest
+}
+
+func (h *healthEventsReporterMock) ReportHealthEvents(_ context.Context, req *proto.ReportHealthEventsRequest) (*proto.ReportHealthEventsResponse, error) {
+	h.requests = append(h.requests, req)
+	return &proto.ReportHealthEventsResponse{}, h.err
+}
+
+type healthEvent struct {
+	kind     proto.HealthEvent_Kind
+	volume   string
+	resolved bool
+}
+
+func TestHealthWatcher(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	f := &fetcher{
+		totalMemory: 100,
+		usedMemory:  50,
+		totalVolume: 100,
+		usedVolume:  50,
+	}
+	reporter := &healthEventsReporterMock{}
+
+	watcher := resourcesmonitor.NewHealthWatcher(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), clk, f, []string{"/home/coder"}, reporter)
+	require.NoError(t, watcher.Start(ctx))
+
+	tick := func() []healthEvent {
+		t.Helper()
+		before := len(reporter.requests)
+		_, waiter := clk.AdvanceNext()
+		require.NoError(t, waiter.Wait(ctx))
+
+		events := make([]healthEvent, 0)
+		for _, req := range reporter.requests[before:] {
+			for _, event := range req.GetEvents() {
+				require.WithinDuration(t, clk.Now(), event.GetObservedAt().AsTime(), 0)
+				events = append(events, healthEvent{
+					kind:     event.GetKind(),
+					volume:   event.GetVolume(),
+					resolved: event.GetResolved(),
+				})
+			}
+		}
+		return events
+	}
+
+	// The state of every condition is reported on the first check.
+	require.Equal(t, []healthEvent{
+		{kind: proto.HealthEvent_NEAR_OUT_OF_MEMORY, resolved: true},
+		{kind: proto.HealthEvent_LOW_DISK_SPACE, volume: "/home/coder", resolved: true},
+	}, tick())
+
+	// Nothing changed.
+	require.Empty(t, tick())
+
+	f.usedMemory = 96
+	require.Equal(t, []healthEvent{
+		{kind: proto.HealthEvent_NEAR_OUT_OF_MEMORY},
+	}, tick())
+	require.Empty(t, tick())
+
+	// Usage within the recovery margin doesn't resolve the condition.
+	f.usedMemory = 92
+	require.Empty(t, tick())
+
+	f.usedMemory = 80
+	f.usedVolume = 99
+	require.Equal(t, []healthEvent{
+		{kind: proto.HealthEvent_NEAR_OUT_OF_MEMORY, resolved: true},
+		{kind: proto.HealthEvent_LOW_DISK_SPACE, volume: "/home/coder"},
+	}, tick())
+
+	// Events that failed to be reported are reported again.
+	reporter.err = assert.AnError
+	f.usedVolume = 10
+	require.Equal(t, []healthEvent{
+		{kind: proto.HealthEvent_LOW_DISK_SPACE, volume: "/home/coder", resolved: true},
+	}, tick())
+	reporter.err = nil
+	require.Equal(t, []healthEvent{
+		{kind: proto.HealthEvent_LOW_DISK_SPACE, volume: "/home/coder", resolved: true},
+	}, tick())
+	require.Empty(t, tick())
+}
@@ -24,15 +24,22 @@ func (d *datapointsPusherMock) PushResourcesMonitoringUsage(ctx context.Context,
 }
 
 type fetcher struct {
+	totalCPU    float64
+	usedCPU     float64
 	totalMemory int64
 	usedMemory  int64
 	totalVolume int64
 	usedVolume  int64
 
+	errCPU    error
 	errMemory error
 	errVolume error
 }
 
+func (r *fetcher) FetchCPU() (total float64, used float64, err error) {
+	return r.totalCPU, r.usedCPU, r.errCPU
+}
+
 func (r *fetcher) FetchMemory() (total int64, used int64, err error) {
 	return r.totalMemory, r.usedMemory, r.errMemory
 }
@@ -0,0 +1,77 @@
+package resourcesmonitor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/quartz"
+)
+
+// UsageReportInterval is how often the agent reports the resource usage of
+// its host to coderd.
+const UsageReportInterval = time.Minute
+
+type usageReporter struct {
+	logger           slog.Logger
+	clock            quartz.Clock
+	resourcesFetcher Fetcher
+	usagePusher      usagePusher
+}
+
+type usagePusher interface {
+	PushResourceUsage(ctx context.Context, req *proto.PushResourceUsageRequest) (*proto.PushResourceUsageResponse, error)
+}
+
+// NewUsageReporter returns a reporter which periodically pushes the CPU,
+// memory and home directory disk usage of the agent's host to coderd, where
+// it is stored for right-sizing workspaces.
+//
+//nolint:revive
+func NewUsageReporter(logger slog.Logger, clock quartz.Clock, resourcesFetcher Fetcher, usagePusher usagePusher) *usageReporter {
+	return &usageReporter{
+		logger:           logger,
+		clock:            clock,
+		resourcesFetcher: resourcesFetcher,
+		usagePusher:      usagePusher,
+	}
+}
+
+func (r *usageReporter) Start(ctx context.Context) error {
+	r.clock.TickerFunc(ctx, UsageReportInterval, func() error {
+		req := &proto.PushResourceUsageRequest{
+			CollectedAt: timestamppb.New(r.clock.Now()),
+		}
+
+		var err error
+		req.CpuTotal, req.CpuUsed, err = r.resourcesFetcher.FetchCPU()
+		if err != nil {
+			r.logger.Error(ctx, "failed to fetch cpu", slog.Error(err))
+			return nil
+		}
+		req.MemoryTotal, req.MemoryUsed, err = r.resourcesFetcher.FetchMemory()
+		if err != nil {
+			r.logger.Error(ctx, "failed to fetch memory", slog.Error(err))
+			return nil
+		}
+		// An empty path is the home directory of the agent.
+		req.DiskTotal, req.DiskUsed, err = r.resourcesFetcher.FetchVolume("")
+		if err != nil {
+			r.logger.Error(ctx, "failed to fetch volume", slog.Error(err))
+			return nil
+		}
+
+		_, err = r.usagePusher.PushResourceUsage(ctx, req)
+		if err != nil {
+			// As with the resources monitor, a failed push shouldn't stop
+			// the reporting.
+			r.logger.Error(ctx, "failed to push resource usage", slog.Error(err))
+		}
+		return nil
+	}, "resource_usage_reporter")
+
+	return nil
+}
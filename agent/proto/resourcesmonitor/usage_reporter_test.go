@@ -0,0 +1,97 @@
+package resourcesmonitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/agent/proto/resourcesmonitor"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+type usagePusherMock struct {
+	requests []*proto.PushResourceUsageRequest
+}
+
+func (u *usagePusherMock) PushResourceUsage(_ context.Context, req *proto.PushResourceUsageRequest) (*proto.PushResourceUsageResponse, error) {
+	u.requests = append(u.requests, req)
+	return &proto.PushResourceUsageResponse{}, nil
+}
+
+func TestUsageReporter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		fetcher       *fetcher
+		expectPushes  int
+		expectRequest *proto.PushResourceUsageRequest
+	}{
+		{
+			name: "OK",
+			fetcher: &fetcher{
+				totalCPU:    4,
+				usedCPU:     1.5,
+				totalMemory: 16000,
+				usedMemory:  8000,
+				totalVolume: 100000,
+				usedVolume:  50000,
+			},
+			expectPushes: 3,
+			expectRequest: &proto.PushResourceUsageRequest{
+				CpuTotal:    4,
+				CpuUsed:     1.5,
+				MemoryTotal: 16000,
+				MemoryUsed:  8000,
+				DiskTotal:   100000,
+				DiskUsed:    50000,
+			},
+		},
+		{
+			name: "FetchError",
+			fetcher: &fetcher{
+				totalCPU:  4,
+				usedCPU:   1.5,
+				errMemory: assert.AnError,
+			},
+			expectPushes: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitShort)
+			clk := quartz.NewMock(t)
+			pusher := &usagePusherMock{}
+			start := clk.Now()
+
+			reporter := resourcesmonitor.NewUsageReporter(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), clk, tt.fetcher, pusher)
+			require.NoError(t, reporter.Start(ctx))
+
+			for range 3 {
+				_, waiter := clk.AdvanceNext()
+				require.NoError(t, waiter.Wait(ctx))
+			}
+
+			require.Len(t, pusher.requests, tt.expectPushes)
+			for i, req := range pusher.requests {
+				collectedAt := start.Add(time.Duration(i+1) * resourcesmonitor.UsageReportInterval)
+				require.WithinDuration(t, collectedAt, req.GetCollectedAt().AsTime(), 0)
+				require.Equal(t, tt.expectRequest.CpuTotal, req.CpuTotal)
+				require.Equal(t, tt.expectRequest.CpuUsed, req.CpuUsed)
+				require.Equal(t, tt.expectRequest.MemoryTotal, req.MemoryTotal)
+				require.Equal(t, tt.expectRequest.MemoryUsed, req.MemoryUsed)
+				require.Equal(t, tt.expectRequest.DiskTotal, req.DiskTotal)
+				require.Equal(t, tt.expectRequest.DiskUsed, req.DiskUsed)
+			}
+		})
+	}
+}
@@ -26,6 +26,11 @@ type bufferedReconnectingPTY struct {
 	activeConns    map[string]net.Conn
 	circularBuffer *circbuf.Buffer
 
+	// recordSession and activeRecordings record the output seen by each
+	// active connection.
+	recordSession    func(width, height int) io.WriteCloser
+	activeRecordings map[string]io.WriteCloser
+
 	ptty    pty.PTYCmd
 	process pty.Process
 
@@ -42,11 +47,13 @@ type bufferedReconnectingPTY struct {
 // killed.
 func newBuffered(ctx context.Context, logger slog.Logger, execer agentexec.Execer, cmd *pty.Cmd, options *Options) *bufferedReconnectingPTY {
 	rpty := &bufferedReconnectingPTY{
-		activeConns: map[string]net.Conn{},
-		command:     cmd,
-		metrics:     options.Metrics,
-		state:       newState(),
-		timeout:     options.Timeout,
+		activeConns:      map[string]net.Conn{},
+		recordSession:    options.RecordSession,
+		activeRecordings: map[string]io.WriteCloser{},
+		command:          cmd,
+		metrics:          options.Metrics,
+		state:            newState(),
+		timeout:          options.Timeout,
 	}
 
 	// Default to buffer 64KiB.
@@ -117,6 +124,9 @@ func newBuffered(ctx context.Context, logger slog.Logger, execer agentexec.Exece
 					rpty.metrics.WithLabelValues("write").Add(1)
 				}
 			}
+			for _, rec := range rpty.activeRecordings {
+				_, _ = rec.Write(part)
+			}
 			rpty.state.cond.L.Unlock()
 		}
 	}()
@@ -180,7 +190,7 @@ func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string,
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	err := rpty.doAttach(connID, conn)
+	err := rpty.doAttach(connID, conn, height, width)
 	if err != nil {
 		return err
 	}
@@ -189,6 +199,10 @@ func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string,
 		rpty.state.cond.L.Lock()
 		defer rpty.state.cond.L.Unlock()
 		delete(rpty.activeConns, connID)
+		if rec, ok := rpty.activeRecordings[connID]; ok {
+			_ = rec.Close()
+			delete(rpty.activeRecordings, connID)
+		}
 	}()
 
 	state, err := rpty.state.waitForStateOrContext(ctx, StateReady)
@@ -214,7 +228,7 @@ func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string,
 // doAttach adds the connection to the map and replays the buffer.  It exists
 // separately only for convenience to defer the mutex unlock which is not
 // possible in Attach since it blocks.
-func (rpty *bufferedReconnectingPTY) doAttach(connID string, conn net.Conn) error {
+func (rpty *bufferedReconnectingPTY) doAttach(connID string, conn net.Conn, height, width uint16) error {
 	rpty.state.cond.L.Lock()
 	defer rpty.state.cond.L.Unlock()
 
@@ -229,6 +243,14 @@ func (rpty *bufferedReconnectingPTY) doAttach(connID string, conn net.Conn) erro
 	}
 
 	rpty.activeConns[connID] = conn
+	if rpty.recordSession != nil {
+		if rec := rpty.recordSession(int(width), int(height)); rec != nil {
+			// The replayed buffer is recorded too, since it is what the
+			// connection sees first.
+			_, _ = rec.Write(prevBuf)
+			rpty.activeRecordings[connID] = rec
+		}
+	}
 
 	return nil
 }
@@ -34,6 +34,10 @@ type Options struct {
 	Metrics *prometheus.CounterVec
 	// BackendType specifies the ReconnectingPTY backend to use.
 	BackendType string
+	// RecordSession returns a writer that records the output seen by a
+	// connection attached with a terminal of the given size, or nil if the
+	// connection should not be recorded.
+	RecordSession func(width, height int) io.WriteCloser
 }
 
 // ReconnectingPTY is a pty that can be reconnected within a timeout and to
@@ -45,6 +45,9 @@ type screenReconnectingPTY struct {
 
 	configFile string
 
+	// recordSession records the output seen by each connection.
+	recordSession func(width, height int) io.WriteCloser
+
 	metrics *prometheus.CounterVec
 
 	state *ptyState
@@ -62,11 +65,12 @@ type screenReconnectingPTY struct {
 // own which causes it to spawn with the specified size.
 func newScreen(ctx context.Context, logger slog.Logger, execer agentexec.Execer, cmd *pty.Cmd, options *Options) *screenReconnectingPTY {
 	rpty := &screenReconnectingPTY{
-		execer:  execer,
-		command: cmd,
-		metrics: options.Metrics,
-		state:   newState(),
-		timeout: options.Timeout,
+		execer:        execer,
+		command:       cmd,
+		recordSession: options.RecordSession,
+		metrics:       options.Metrics,
+		state:         newState(),
+		timeout:       options.Timeout,
 	}
 
 	// Socket paths are limited to around 100 characters on Linux and macOS which
@@ -244,6 +248,11 @@ func (rpty *screenReconnectingPTY) doAttach(ctx context.Context, conn net.Conn,
 		return nil, nil, err
 	}
 
+	var rec io.WriteCloser
+	if rpty.recordSession != nil {
+		rec = rpty.recordSession(int(width), int(height))
+	}
+
 	// This context lets us abort the version command if the process dies.
 	versionCtx, versionCancel := context.WithCancel(ctx)
 	defer versionCancel()
@@ -254,6 +263,9 @@ func (rpty *screenReconnectingPTY) doAttach(ctx context.Context, conn net.Conn,
 	// output.
 	go func() {
 		defer versionCancel()
+		if rec != nil {
+			defer rec.Close()
+		}
 		defer func() {
 			err := conn.Close()
 			if err != nil {
@@ -284,6 +296,9 @@ func (rpty *screenReconnectingPTY) doAttach(ctx context.Context, conn net.Conn,
 				break
 			}
 			part := buffer[:read]
+			if rec != nil {
+				_, _ = rec.Write(part)
+			}
 			_, err = conn.Write(part)
 			if err != nil {
 				// Connection might have been closed.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"io"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -35,6 +36,9 @@ type Server struct {
 	// Note that this is different from the devcontainers feature, which uses
 	// subagents.
 	ExperimentalContainers bool
+	// RecordSession returns a writer that records the output seen by a
+	// connection, or nil if it should not be recorded.
+	RecordSession func(width, height int) io.WriteCloser
 }
 
 // NewServer returns a new ReconnectingPTY server
@@ -209,9 +213,10 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 			s.commandCreator.Execer,
 			cmd,
 			&Options{
-				Timeout:     s.timeout,
-				Metrics:     s.errorsTotal,
-				BackendType: msg.BackendType,
+				Timeout:       s.timeout,
+				Metrics:       s.errorsTotal,
+				BackendType:   msg.BackendType,
+				RecordSession: s.RecordSession,
 			},
 		)
 
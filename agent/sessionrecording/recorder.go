@@ -0,0 +1,318 @@
+// Package sessionrecording records the output of terminal sessions on the
+// agent and streams it to coderd, which stores it for playback.
+package sessionrecording
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/quartz"
+)
+
+// Type is the kind of session being recorded.
+type Type = proto.CreateSessionRecordingRequest_Type
+
+const (
+	TypeSSH             = proto.CreateSessionRecordingRequest_SSH
+	TypeReconnectingPTY = proto.CreateSessionRecordingRequest_RECONNECTING_PTY
+)
+
+const (
+	// FlushInterval is how often recorded output is sent to coderd.
+	FlushInterval = time.Second
+	// maxPendingBytes is how much output is buffered for a single recording
+	// while it can't be sent to coderd. Output beyond this is dropped so
+	// that a disconnected agent doesn't run out of memory.
+	maxPendingBytes = 4 << 20
+	// maxBatchBytes is the most output sent for a recording in one request.
+	// It must stay below the limit coderd enforces.
+	maxBatchBytes = 512 << 10
+	// coalesceWindow is the window in which writes are merged into a single
+	// chunk. Shells often write a line in several small writes, and storing
+	// each one separately would bloat the recording.
+	coalesceWindow = 10 * time.Millisecond
+	// maxFailures is how many consecutive times a recording can fail to be
+	// sent before it is abandoned.
+	maxFailures = 10
+)
+
+// Client is the part of the agent API used to store recordings.
+type Client interface {
+	CreateSessionRecording(ctx context.Context, req *proto.CreateSessionRecordingRequest) (*proto.CreateSessionRecordingResponse, error)
+	AppendSessionRecording(ctx context.Context, req *proto.AppendSessionRecordingRequest) (*proto.AppendSessionRecordingResponse, error)
+}
+
+// Recorder buffers the output of recorded sessions until it can be sent to
+// coderd. It outlives connections to coderd, so output recorded while the
+// agent is reconnecting is sent once it is connected again.
+type Recorder struct {
+	logger slog.Logger
+	clock  quartz.Clock
+
+	mu         sync.Mutex
+	enabled    bool
+	recordings []*recording
+}
+
+type recording struct {
+	id        uuid.UUID
+	typ       Type
+	width     int32
+	height    int32
+	startedAt time.Time
+
+	// The following fields are protected by the Recorder's mutex.
+	created  bool
+	chunks   []*proto.AppendSessionRecordingRequest_Chunk
+	pending  int
+	dropped  int
+	endedAt  *time.Time
+	failures int
+}
+
+// New creates a recorder. Sessions are not recorded until the recorder is
+// enabled.
+func New(logger slog.Logger, clock quartz.Clock) *Recorder {
+	return &Recorder{
+		logger: logger,
+		clock:  clock,
+	}
+}
+
+// SetEnabled sets whether new sessions are recorded. Sessions that are
+// already being recorded continue to be recorded.
+func (r *Recorder) SetEnabled(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enabled = enabled
+}
+
+// Start begins recording a session with a terminal of the given size. The
+// returned writer records everything written to it until it is closed. If
+// recording is disabled, Start returns nil.
+func (r *Recorder) Start(typ Type, width, height int) io.WriteCloser {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.enabled {
+		return nil
+	}
+	rec := &recording{
+		id:        uuid.New(),
+		typ:       typ,
+		width:     int32(width),  //nolint:gosec // Terminal sizes are small.
+		height:    int32(height), //nolint:gosec // Terminal sizes are small.
+		startedAt: r.clock.Now(),
+	}
+	r.recordings = append(r.recordings, rec)
+	return &writer{r: r, rec: rec}
+}
+
+type writer struct {
+	r      *Recorder
+	rec    *recording
+	closed bool
+}
+
+// Write never fails, as the writer is usually used with io.MultiWriter and
+// a failure would stop the session's output from reaching the user.
+func (w *writer) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	w.r.mu.Lock()
+	defer w.r.mu.Unlock()
+	if w.closed {
+		return len(p), nil
+	}
+	rec := w.rec
+	if rec.pending+len(p) > maxPendingBytes {
+		if rec.dropped == 0 {
+			w.r.logger.Warn(context.Background(), "session recording buffer is full, dropping output",
+				slog.F("recording_id", rec.id))
+		}
+		rec.dropped += len(p)
+		return len(p), nil
+	}
+	now := w.r.clock.Now()
+	if n := len(rec.chunks); n > 0 {
+		last := rec.chunks[n-1]
+		if now.Sub(last.CreatedAt.AsTime()) < coalesceWindow && len(last.Data)+len(p) <= maxBatchBytes {
+			last.Data = append(last.Data, p...)
+			rec.pending += len(p)
+			return len(p), nil
+		}
+	}
+	rec.chunks = append(rec.chunks, &proto.AppendSessionRecordingRequest_Chunk{
+		CreatedAt: timestamppb.New(now),
+		Data:      append([]byte(nil), p...),
+	})
+	rec.pending += len(p)
+	return len(p), nil
+}
+
+// Close ends the recording. Output that has not been sent yet is still sent.
+func (w *writer) Close() error {
+	w.r.mu.Lock()
+	defer w.r.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	endedAt := w.r.clock.Now()
+	w.rec.endedAt = &endedAt
+	if w.rec.dropped > 0 {
+		w.r.logger.Warn(context.Background(), "session recording is incomplete, output was dropped",
+			slog.F("recording_id", w.rec.id), slog.F("dropped_bytes", w.rec.dropped))
+	}
+	return nil
+}
+
+// Run sends recorded output to coderd until the context is canceled.
+func (r *Recorder) Run(ctx context.Context, client Client) error {
+	tkr := r.clock.TickerFunc(ctx, FlushInterval, func() error {
+		r.Flush(ctx, client)
+		return nil
+	}, "sessionrecording", "flush")
+	err := tkr.Wait()
+	if xerrors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// Flush sends the output recorded so far to coderd. Recordings that fail to
+// be sent are retried on the next flush.
+func (r *Recorder) Flush(ctx context.Context, client Client) {
+	r.mu.Lock()
+	recordings := make([]*recording, len(r.recordings))
+	copy(recordings, r.recordings)
+	r.mu.Unlock()
+
+	for _, rec := range recordings {
+		err := r.flushRecording(ctx, client, rec)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		r.mu.Lock()
+		rec.failures++
+		abandon := rec.failures >= maxFailures
+		if abandon {
+			r.remove(rec)
+		}
+		r.mu.Unlock()
+		if abandon {
+			r.logger.Error(ctx, "abandoning session recording after repeated failures",
+				slog.F("recording_id", rec.id), slog.Error(err))
+		} else {
+			r.logger.Warn(ctx, "failed to send session recording",
+				slog.F("recording_id", rec.id), slog.Error(err))
+		}
+	}
+}
+
+func (r *Recorder) flushRecording(ctx context.Context, client Client, rec *recording) error {
+	r.mu.Lock()
+	created := rec.created
+	r.mu.Unlock()
+	if !created {
+		_, err := client.CreateSessionRecording(ctx, &proto.CreateSessionRecordingRequest{
+			Id:        rec.id[:],
+			Type:      rec.typ,
+			StartedAt: timestamppb.New(rec.startedAt),
+			Width:     rec.width,
+			Height:    rec.height,
+		})
+		if err != nil {
+			return xerrors.Errorf("create session recording: %w", err)
+		}
+		r.mu.Lock()
+		rec.created = true
+		r.mu.Unlock()
+	}
+
+	for {
+		r.mu.Lock()
+		var (
+			batch []*proto.AppendSessionRecordingRequest_Chunk
+			size  int
+		)
+		for _, chunk := range rec.chunks {
+			if len(batch) > 0 && size+len(chunk.Data) > maxBatchBytes {
+				break
+			}
+			batch = append(batch, chunk)
+			size += len(chunk.Data)
+		}
+		// The last chunk may still grow, so it's copied to keep the
+		// request unchanged while it is being sent.
+		if n := len(batch); n > 0 {
+			last := batch[n-1]
+			batch[n-1] = &proto.AppendSessionRecordingRequest_Chunk{
+				CreatedAt: last.CreatedAt,
+				Data:      last.Data[:len(last.Data):len(last.Data)],
+			}
+		}
+		var endedAt *timestamppb.Timestamp
+		if rec.endedAt != nil && len(batch) == len(rec.chunks) {
+			endedAt = timestamppb.New(*rec.endedAt)
+		}
+		r.mu.Unlock()
+
+		if len(batch) == 0 && endedAt == nil {
+			return nil
+		}
+		_, err := client.AppendSessionRecording(ctx, &proto.AppendSessionRecordingRequest{
+			Id:      rec.id[:],
+			Chunks:  batch,
+			EndedAt: endedAt,
+		})
+		if err != nil {
+			return xerrors.Errorf("append to session recording: %w", err)
+		}
+
+		r.mu.Lock()
+		rec.failures = 0
+		// Writes may have been appended to the last chunk we sent, so only
+		// the data that was sent is removed.
+		sent := len(batch)
+		if sent > 0 && len(rec.chunks[sent-1].Data) > len(batch[sent-1].Data) {
+			last := rec.chunks[sent-1]
+			rec.pending -= len(batch[sent-1].Data)
+			last.Data = last.Data[len(batch[sent-1].Data):]
+			sent--
+		}
+		for _, chunk := range rec.chunks[:sent] {
+			rec.pending -= len(chunk.Data)
+		}
+		rec.chunks = rec.chunks[sent:]
+		done := endedAt != nil
+		if done {
+			r.remove(rec)
+		}
+		r.mu.Unlock()
+		if done {
+			return nil
+		}
+	}
+}
+
+// remove stops tracking the recording. The caller must hold the mutex.
+func (r *Recorder) remove(rec *recording) {
+	for i, other := range r.recordings {
+		if other == rec {
+			r.recordings = append(r.recordings[:i], r.recordings[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package sessionrecording_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/agent/sessionrecording"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+type fakeClient struct {
+	err     error
+	creates []*proto.CreateSessionRecordingRequest
+	appends []*proto.AppendSessionRecordingRequest
+}
+
+func (c *fakeClient) CreateSessionRecording(_ context.Context, req *proto.CreateSessionRecordingRequest) (*proto.CreateSessionRecordingResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.creates = append(c.creates, req)
+	return &proto.CreateSessionRecordingResponse{}, nil
+}
+
+func (c *fakeClient) AppendSessionRecording(_ context.Context, req *proto.AppendSessionRecordingRequest) (*proto.AppendSessionRecordingResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.appends = append(c.appends, req)
+	return &proto.AppendSessionRecordingResponse{}, nil
+}
+
+func (c *fakeClient) output() []byte {
+	var out []byte
+	for _, req := range c.appends {
+		for _, chunk := range req.GetChunks() {
+			out = append(out, chunk.GetData()...)
+		}
+	}
+	return out
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		r := sessionrecording.New(slogtest.Make(t, nil), quartz.NewMock(t))
+		require.Nil(t, r.Start(sessionrecording.TypeSSH, 80, 24))
+	})
+
+	t.Run("Record", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		clk := quartz.NewMock(t)
+		r := sessionrecording.New(slogtest.Make(t, nil), clk)
+		r.SetEnabled(true)
+		client := &fakeClient{}
+
+		w := r.Start(sessionrecording.TypeSSH, 80, 24)
+		require.NotNil(t, w)
+		startedAt := clk.Now()
+
+		// Writes close together are stored as a single chunk.
+		_, err := w.Write([]byte("hello "))
+		require.NoError(t, err)
+		_, err = w.Write([]byte("world\n"))
+		require.NoError(t, err)
+		clk.Advance(time.Second)
+		_, err = w.Write([]byte("$ "))
+		require.NoError(t, err)
+
+		r.Flush(ctx, client)
+		require.Len(t, client.creates, 1)
+		create := client.creates[0]
+		require.Equal(t, sessionrecording.TypeSSH, create.GetType())
+		require.EqualValues(t, 80, create.GetWidth())
+		require.EqualValues(t, 24, create.GetHeight())
+		require.Equal(t, startedAt, create.GetStartedAt().AsTime())
+		id, err := uuid.FromBytes(create.GetId())
+		require.NoError(t, err)
+
+		require.Len(t, client.appends, 1)
+		appended := client.appends[0]
+		require.Equal(t, id[:], appended.GetId())
+		require.Nil(t, appended.EndedAt)
+		require.Len(t, appended.GetChunks(), 2)
+		require.Equal(t, "hello world\n", string(appended.GetChunks()[0].GetData()))
+		require.Equal(t, startedAt, appended.GetChunks()[0].GetCreatedAt().AsTime())
+		require.Equal(t, "$ ", string(appended.GetChunks()[1].GetData()))
+		require.Equal(t, startedAt.Add(time.Second), appended.GetChunks()[1].GetCreatedAt().AsTime())
+
+		// Nothing is sent when there's no new output.
+		r.Flush(ctx, client)
+		require.Len(t, client.appends, 1)
+
+		_, err = w.Write([]byte("exit\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		r.Flush(ctx, client)
+		require.Len(t, client.creates, 1)
+		require.Len(t, client.appends, 2)
+		require.Equal(t, "exit\n", string(client.appends[1].GetChunks()[0].GetData()))
+		require.Equal(t, clk.Now(), client.appends[1].GetEndedAt().AsTime())
+
+		// Ended recordings are no longer sent.
+		r.Flush(ctx, client)
+		require.Len(t, client.appends, 2)
+		require.Equal(t, "hello world\n$ exit\n", string(client.output()))
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		clk := quartz.NewMock(t)
+		r := sessionrecording.New(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), clk)
+		r.SetEnabled(true)
+		client := &fakeClient{err: xerrors.New("disconnected")}
+
+		w := r.Start(sessionrecording.TypeReconnectingPTY, 80, 24)
+		_, err := w.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		r.Flush(ctx, client)
+
+		// Output recorded while disconnected is sent once reconnected.
+		client.err = nil
+		r.Flush(ctx, client)
+		require.Len(t, client.creates, 1)
+		require.Equal(t, sessionrecording.TypeReconnectingPTY, client.creates[0].GetType())
+		require.Len(t, client.appends, 1)
+		require.Equal(t, "hello", string(client.output()))
+		require.NotNil(t, client.appends[0].EndedAt)
+	})
+
+	t.Run("Batches", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		clk := quartz.NewMock(t)
+		r := sessionrecording.New(slogtest.Make(t, nil), clk)
+		r.SetEnabled(true)
+		client := &fakeClient{}
+
+		w := r.Start(sessionrecording.TypeSSH, 80, 24)
+		data := bytes.Repeat([]byte("a"), 256<<10)
+		for range 5 {
+			_, err := w.Write(data)
+			require.NoError(t, err)
+			clk.Advance(time.Second)
+		}
+		require.NoError(t, w.Close())
+		r.Flush(ctx, client)
+
+		require.Len(t, client.appends, 3)
+		for _, req := range client.appends {
+			var size int
+			for _, chunk := range req.GetChunks() {
+				size += len(chunk.GetData())
+			}
+			require.LessOrEqual(t, size, 512<<10)
+		}
+		require.Nil(t, client.appends[0].EndedAt)
+		require.NotNil(t, client.appends[2].EndedAt)
+		require.Len(t, client.output(), 5*len(data))
+	})
+
+	t.Run("DropsOutputWhenFull", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		clk := quartz.NewMock(t)
+		r := sessionrecording.New(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), clk)
+		r.SetEnabled(true)
+		client := &fakeClient{}
+
+		w := r.Start(sessionrecording.TypeSSH, 80, 24)
+		data := bytes.Repeat([]byte("a"), 1<<20)
+		for range 5 {
+			n, err := w.Write(data)
+			require.NoError(t, err)
+			require.Equal(t, len(data), n)
+		}
+		require.NoError(t, w.Close())
+		r.Flush(ctx, client)
+		require.Len(t, client.output(), 4<<20)
+	})
+}
@@ -3,10 +3,13 @@ package agent
 import (
 	"context"
 	"maps"
+	"net/netip"
 	"sync"
 	"time"
 
 	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"tailscale.com/types/netlogtype"
 
 	"cdr.dev/slog"
@@ -131,3 +134,31 @@ func (s *statsReporter) reportLocked(
 	}
 	return nil
 }
+
+// peerConnectionQuality is the network quality of a single peer, accumulated
+// across stats reports for as long as the peer stays active.
+type peerConnectionQuality struct {
+	p2p            bool
+	derpRegionCode string
+	latency        time.Duration
+	pingsSent      int64
+	pingsLost      int64
+	lastHandshake  time.Time
+}
+
+func (q *peerConnectionQuality) proto(addr netip.Addr) *proto.Stats_ConnectionQuality {
+	pq := &proto.Stats_ConnectionQuality{
+		Ip:             addr.String(),
+		P2P:            q.p2p,
+		DerpRegionCode: q.derpRegionCode,
+		PingsSent:      q.pingsSent,
+		PingsLost:      q.pingsLost,
+	}
+	if q.latency > 0 {
+		pq.Latency = durationpb.New(q.latency)
+	}
+	if !q.lastHandshake.IsZero() {
+		pq.LastHandshake = timestamppb.New(q.lastHandshake)
+	}
+	return pq
+}
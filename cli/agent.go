@@ -329,8 +329,9 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 			reinitEvents := agentsdk.WaitForReinitLoop(ctx, logger, client)
 
 			var (
-				lastErr  error
-				mustExit bool
+				lastErr         error
+				mustExit        bool
+				prebuildClaimed bool
 			)
 			for {
 				prometheusRegistry := prometheus.NewRegistry()
@@ -365,6 +366,7 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 					DevcontainerAPIOptions: []agentcontainers.Option{
 						agentcontainers.WithSubAgentURL(r.agentURL.String()),
 					},
+					PrebuildClaimed: prebuildClaimed,
 				})
 
 				promHandler := agent.PrometheusMetricsHandler(prometheusRegistry, logger)
@@ -379,6 +381,7 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 				case event := <-reinitEvents:
 					logger.Info(ctx, "agent received instruction to reinitialize",
 						slog.F("workspace_id", event.WorkspaceID), slog.F("reason", event.Reason))
+					prebuildClaimed = event.Reason == agentsdk.ReinitializeReasonPrebuildClaimed
 				}
 
 				lastErr = agnt.Close()
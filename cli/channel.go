@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) channel() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "channel <workspace> <stable|beta|none>",
+		Short:       "Set the release channel a workspace tracks",
+		Long:        "Workspaces that track a release channel update to the version published to it instead of the active version of their template. Use \"none\" to follow the active version again.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			channel := codersdk.ReleaseChannel(strings.ToLower(inv.Args[1]))
+			if channel == "none" {
+				channel = ""
+			}
+			if channel != "" && !channel.Valid() {
+				return xerrors.Errorf("invalid channel %q must be one of %q, %q or %q", inv.Args[1], codersdk.ReleaseChannelStable, codersdk.ReleaseChannelBeta, "none")
+			}
+
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			err = client.UpdateWorkspaceReleaseChannel(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceReleaseChannelRequest{
+				Channel: channel,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace release channel: %w", err)
+			}
+			if channel == "" {
+				_, _ = fmt.Fprintf(inv.Stdout, "Workspace %q follows the active version of its template\n", workspace.Name)
+				return nil
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Workspace %q tracks the %q channel\n", workspace.Name, channel)
+			return nil
+		},
+	}
+	return cmd
+}
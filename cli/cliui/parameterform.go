@@ -0,0 +1,542 @@
+package cliui
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+type ParameterFormOptions struct {
+	Message string
+	// Values are the initial values of the form by parameter name. Parameters
+	// without a value start with their default.
+	Values map[string]string
+	// Evaluate renders the parameters for the given values. It is called again
+	// whenever a value changes, so options and validation that depend on other
+	// parameters stay up to date. The returned parameters are the fields of the
+	// form, in order.
+	Evaluate func(ctx context.Context, values map[string]string) (codersdk.DynamicParametersResponse, error)
+}
+
+// ParameterForm displays all parameters at once and lets the user move between
+// them, revisiting earlier answers. Values are validated as they change, and
+// the form can only be submitted once none of them has errors. It returns the
+// values of the parameters by name.
+func ParameterForm(inv *serpent.Invocation, opts ParameterFormOptions) (map[string]string, error) {
+	values := maps.Clone(opts.Values)
+	if values == nil {
+		values = map[string]string{}
+	}
+	state, err := opts.Evaluate(inv.Context(), values)
+	if err != nil {
+		return nil, xerrors.Errorf("evaluate parameters: %w", err)
+	}
+
+	// There is nothing to ask for, so only errors of the parameters that are
+	// not part of the form are reported. Similar hack is applied to Select().
+	if len(state.Parameters) == 0 || flag.Lookup("test.v") != nil {
+		if err := parameterFormError(state); err != nil {
+			return nil, err
+		}
+		return parameterFormValues(state), nil
+	}
+
+	initialModel := newParameterFormModel(inv.Context(), opts, values, state)
+
+	p := tea.NewProgram(
+		initialModel,
+		tea.WithoutSignalHandler(),
+		tea.WithContext(inv.Context()),
+		tea.WithInput(inv.Stdin),
+		tea.WithOutput(inv.Stdout),
+	)
+
+	closeSignalHandler := installSignalHandler(p)
+	defer closeSignalHandler()
+
+	m, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	model, ok := m.(parameterFormModel)
+	if !ok {
+		return nil, xerrors.New(fmt.Sprintf("unknown model found %T (%+v)", m, m))
+	}
+
+	if model.canceled {
+		return nil, ErrCanceled
+	}
+	if model.err != nil {
+		return nil, xerrors.Errorf("evaluate parameters: %w", model.err)
+	}
+
+	return parameterFormValues(model.state), nil
+}
+
+// parameterFormValues returns the values of the parameters that have one.
+func parameterFormValues(state codersdk.DynamicParametersResponse) map[string]string {
+	values := make(map[string]string, len(state.Parameters))
+	for _, p := range state.Parameters {
+		if p.Value.Valid {
+			values[p.Name] = p.Value.Value
+		}
+	}
+	return values
+}
+
+// parameterFormError returns the first error diagnostic of the form, if any.
+func parameterFormError(state codersdk.DynamicParametersResponse) error {
+	for _, d := range state.Diagnostics {
+		if d.Severity == codersdk.DiagnosticSeverityError {
+			return xerrors.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+	for _, p := range state.Parameters {
+		for _, d := range p.Diagnostics {
+			if d.Severity == codersdk.DiagnosticSeverityError {
+				return xerrors.Errorf("parameter %q: %s", p.Name, d.Summary)
+			}
+		}
+	}
+	return nil
+}
+
+type parameterFormEvaluatedMsg struct {
+	id    int
+	state codersdk.DynamicParametersResponse
+	err   error
+}
+
+type parameterFormModel struct {
+	ctx      context.Context
+	evaluate func(ctx context.Context, values map[string]string) (codersdk.DynamicParametersResponse, error)
+	message  string
+
+	// values are the values entered by the user, which are sent with every
+	// evaluation.
+	values map[string]string
+	state  codersdk.DynamicParametersResponse
+
+	// focus is the name of the focused parameter, or empty if the submit
+	// button is focused.
+	focus string
+	// option is the highlighted option of a focused list parameter.
+	option int
+	input  textinput.Model
+
+	// evaluating is the ID of the evaluation in flight, or zero.
+	evaluating     int
+	lastEvaluation int
+	submitting     bool
+
+	submitted bool
+	canceled  bool
+	err       error
+}
+
+func newParameterFormModel(ctx context.Context, opts ParameterFormOptions, values map[string]string, state codersdk.DynamicParametersResponse) parameterFormModel {
+	m := parameterFormModel{
+		ctx:      ctx,
+		evaluate: opts.Evaluate,
+		message:  opts.Message,
+		values:   values,
+		state:    state,
+		input:    textinput.New(),
+	}
+	m.input.Prompt = ""
+	if len(state.Parameters) > 0 {
+		m.setFocus(state.Parameters[0].Name)
+	}
+	return m
+}
+
+func (parameterFormModel) Init() tea.Cmd {
+	return nil
+}
+
+//nolint:revive // For same reason as previous Update definition
+func (m parameterFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case terminateMsg:
+		m.canceled = true
+		return m, tea.Quit
+
+	case parameterFormEvaluatedMsg:
+		if msg.id != m.evaluating {
+			// A newer evaluation is in flight.
+			return m, nil
+		}
+		m.evaluating = 0
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+		m.state = msg.state
+		if m.focused() == nil {
+			// The focused parameter was removed by the new values.
+			m.setFocus("")
+		}
+		if m.submitting {
+			return m.submit()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		p := m.focused()
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			m.canceled = true
+			return m, tea.Quit
+
+		case tea.KeyUp, tea.KeyShiftTab:
+			cmd := m.commitInput()
+			m.moveFocus(-1)
+			return m, cmd
+
+		case tea.KeyDown, tea.KeyTab:
+			cmd := m.commitInput()
+			m.moveFocus(1)
+			return m, cmd
+
+		case tea.KeyEnter:
+			if p == nil {
+				return m.submit()
+			}
+			cmd := m.commitInput()
+			m.moveFocus(1)
+			return m, cmd
+
+		case tea.KeyLeft, tea.KeyRight:
+			if p == nil || isParameterFormText(*p) {
+				break
+			}
+			delta := 1
+			if msg.Type == tea.KeyLeft {
+				delta = -1
+			}
+			switch {
+			case p.Type == codersdk.OptionTypeListString:
+				m.option = (m.option + delta + len(p.Options)) % len(p.Options)
+				return m, nil
+			case len(p.Options) > 0:
+				return m, m.setValue(p.Name, cycleParameterOption(*p, delta))
+			default:
+				return m, m.setValue(p.Name, toggleParameterBool(*p))
+			}
+
+		case tea.KeySpace:
+			if p == nil || isParameterFormText(*p) {
+				break
+			}
+			switch {
+			case p.Type == codersdk.OptionTypeListString:
+				return m, m.setValue(p.Name, toggleParameterListOption(*p, m.option))
+			case len(p.Options) == 0:
+				return m, m.setValue(p.Name, toggleParameterBool(*p))
+			}
+			return m, nil
+		}
+
+		if p != nil && isParameterFormText(*p) {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// submit quits the form if its values are valid. If an evaluation is in flight,
+// the form is submitted once it completes.
+func (m parameterFormModel) submit() (tea.Model, tea.Cmd) {
+	if m.evaluating != 0 {
+		m.submitting = true
+		return m, nil
+	}
+	m.submitting = false
+	if parameterFormError(m.state) != nil {
+		return m, nil
+	}
+	m.submitted = true
+	return m, tea.Quit
+}
+
+func (m *parameterFormModel) focused() *codersdk.PreviewParameter {
+	if m.focus == "" {
+		return nil
+	}
+	for i, p := range m.state.Parameters {
+		if p.Name == m.focus {
+			return &m.state.Parameters[i]
+		}
+	}
+	return nil
+}
+
+func (m *parameterFormModel) setFocus(name string) {
+	m.focus = name
+	m.option = 0
+	m.input.Blur()
+	p := m.focused()
+	if p == nil || !isParameterFormText(*p) {
+		return
+	}
+	m.input.SetValue(parameterFormTextValue(*p))
+	m.input.CursorEnd()
+	m.input.Focus()
+}
+
+// moveFocus moves the focus by delta fields. The submit button is the last
+// field of the form.
+func (m *parameterFormModel) moveFocus(delta int) {
+	names := make([]string, 0, len(m.state.Parameters)+1)
+	for _, p := range m.state.Parameters {
+		names = append(names, p.Name)
+	}
+	names = append(names, "")
+	i := slices.Index(names, m.focus)
+	m.setFocus(names[max(0, min(len(names)-1, i+delta))])
+}
+
+// commitInput stores the value of the focused text field if it changed.
+func (m *parameterFormModel) commitInput() tea.Cmd {
+	p := m.focused()
+	if p == nil || !isParameterFormText(*p) {
+		return nil
+	}
+	value := m.input.Value()
+	if p.Type == codersdk.OptionTypeListString {
+		value = parameterFormListValue(value)
+	}
+	if p.Value.Valid && p.Value.Value == value {
+		return nil
+	}
+	return m.setValue(p.Name, value)
+}
+
+// setValue stores the value of a parameter and evaluates the form again.
+func (m *parameterFormModel) setValue(name, value string) tea.Cmd {
+	m.values[name] = value
+	// Show the new value until the evaluation completes.
+	for i, p := range m.state.Parameters {
+		if p.Name == name {
+			m.state.Parameters[i].Value = codersdk.NullHCLString{Value: value, Valid: true}
+		}
+	}
+
+	m.lastEvaluation++
+	m.evaluating = m.lastEvaluation
+	id, values, evaluate, ctx := m.evaluating, maps.Clone(m.values), m.evaluate, m.ctx
+	return func() tea.Msg {
+		state, err := evaluate(ctx, values)
+		return parameterFormEvaluatedMsg{id: id, state: state, err: err}
+	}
+}
+
+func (m parameterFormModel) View() string {
+	var s strings.Builder
+
+	msg := pretty.Sprintf(pretty.Bold(), "? %s", m.message)
+
+	if m.submitted {
+		_, _ = s.WriteString(msg + "\n")
+		for _, p := range m.state.Parameters {
+			_, _ = s.WriteString(fmt.Sprintf("  %s: %s\n", parameterFormLabel(p), pretty.Sprint(DefaultStyles.Keyword, parameterFormDisplayValue(p))))
+		}
+		return s.String()
+	}
+
+	_, _ = s.WriteString(fmt.Sprintf("%s [Use arrows to move, <left>/<right> or space to change, enter to continue]\n", msg))
+
+	for _, d := range m.state.Diagnostics {
+		_, _ = s.WriteString(renderParameterFormDiagnostic(d, "  "))
+	}
+
+	for _, p := range m.state.Parameters {
+		focused := p.Name == m.focus
+		cursor := "  "
+		label := parameterFormLabel(p)
+		if focused {
+			cursor = pretty.Sprint(DefaultStyles.Keyword, "> ")
+			label = pretty.Sprint(DefaultStyles.Keyword, label)
+		}
+		if p.Ephemeral {
+			label += pretty.Sprint(DefaultStyles.Warn, " (build option)")
+		}
+		_, _ = s.WriteString(fmt.Sprintf("%s%s\n", cursor, label))
+
+		if focused && p.Description != "" {
+			_, _ = s.WriteString("    " + pretty.Sprint(DefaultStyles.Placeholder, strings.ReplaceAll(strings.TrimSpace(p.Description), "\n", "\n    ")) + "\n")
+		}
+		_, _ = s.WriteString("    " + m.renderValue(p, focused) + "\n")
+		for _, d := range p.Diagnostics {
+			_, _ = s.WriteString(renderParameterFormDiagnostic(d, "    "))
+		}
+	}
+
+	cursor := "  "
+	button := "Submit"
+	if m.focus == "" {
+		cursor = pretty.Sprint(DefaultStyles.Keyword, "> ")
+		button = pretty.Sprint(DefaultStyles.Keyword, button)
+	}
+	_, _ = s.WriteString(fmt.Sprintf("\n%s%s", cursor, button))
+	switch {
+	case m.evaluating != 0:
+		_, _ = s.WriteString(pretty.Sprint(DefaultStyles.Placeholder, " (validating...)"))
+	case parameterFormError(m.state) != nil:
+		_, _ = s.WriteString(pretty.Sprint(DefaultStyles.Error, " (fix the errors above to continue)"))
+	}
+	_, _ = s.WriteString("\n")
+
+	return s.String()
+}
+
+func (m parameterFormModel) renderValue(p codersdk.PreviewParameter, focused bool) string {
+	switch {
+	case isParameterFormText(p):
+		if focused {
+			return "[" + m.input.View() + "]"
+		}
+		return "[" + parameterFormTextValue(p) + "]"
+
+	case p.Type == codersdk.OptionTypeListString:
+		var chosen []string
+		_ = json.Unmarshal([]byte(p.Value.Value), &chosen)
+		options := make([]string, 0, len(p.Options))
+		for i, o := range p.Options {
+			box := "[ ]"
+			if slices.Contains(chosen, o.Value.Value) {
+				box = "[x]"
+			}
+			option := box + " " + o.Name
+			if focused && i == m.option {
+				option = pretty.Sprint(DefaultStyles.Keyword, option)
+			}
+			options = append(options, option)
+		}
+		return strings.Join(options, "  ")
+
+	default:
+		value := parameterFormDisplayValue(p)
+		if focused {
+			return pretty.Sprint(DefaultStyles.Keyword, "< "+value+" >")
+		}
+		return value
+	}
+}
+
+func renderParameterFormDiagnostic(d codersdk.FriendlyDiagnostic, indent string) string {
+	style := DefaultStyles.Warn
+	if d.Severity == codersdk.DiagnosticSeverityError {
+		style = DefaultStyles.Error
+	}
+	text := d.Summary
+	if d.Detail != "" {
+		text += ": " + d.Detail
+	}
+	return indent + pretty.Sprint(style, text) + "\n"
+}
+
+func parameterFormLabel(p codersdk.PreviewParameter) string {
+	if p.DisplayName != "" {
+		return p.DisplayName
+	}
+	return p.Name
+}
+
+// parameterFormDisplayValue returns the value of a parameter as shown to the
+// user, which is the name of the selected option if there is one.
+func parameterFormDisplayValue(p codersdk.PreviewParameter) string {
+	if !p.Value.Valid {
+		return ""
+	}
+	for _, o := range p.Options {
+		if o.Value.Valid && o.Value.Value == p.Value.Value {
+			return o.Name
+		}
+	}
+	return p.Value.Value
+}
+
+// isParameterFormText returns whether the value of a parameter is typed in, as
+// opposed to chosen from options.
+func isParameterFormText(p codersdk.PreviewParameter) bool {
+	return len(p.Options) == 0 && p.Type != codersdk.OptionTypeBoolean
+}
+
+// parameterFormTextValue returns the value of a text field. Lists are entered
+// as comma separated values.
+func parameterFormTextValue(p codersdk.PreviewParameter) string {
+	if p.Type != codersdk.OptionTypeListString {
+		return p.Value.Value
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(p.Value.Value), &list); err != nil {
+		return p.Value.Value
+	}
+	return strings.Join(list, ", ")
+}
+
+// parameterFormListValue converts comma separated values to a list(string)
+// value.
+func parameterFormListValue(text string) string {
+	list := []string{}
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	b, _ := json.Marshal(list)
+	return string(b)
+}
+
+func cycleParameterOption(p codersdk.PreviewParameter, delta int) string {
+	i := slices.IndexFunc(p.Options, func(o codersdk.PreviewParameterOption) bool {
+		return o.Value.Valid && o.Value.Value == p.Value.Value
+	})
+	if i == -1 {
+		// Nothing is selected yet, so start at the first or last option.
+		i = min(0, delta)
+	} else {
+		i += delta
+	}
+	return p.Options[(i+len(p.Options))%len(p.Options)].Value.Value
+}
+
+func toggleParameterBool(p codersdk.PreviewParameter) string {
+	if p.Value.Value == "true" {
+		return "false"
+	}
+	return "true"
+}
+
+func toggleParameterListOption(p codersdk.PreviewParameter, option int) string {
+	var list []string
+	_ = json.Unmarshal([]byte(p.Value.Value), &list)
+	value := p.Options[option].Value.Value
+	if i := slices.Index(list, value); i != -1 {
+		list = slices.Delete(list, i, i+1)
+	} else {
+		list = append(list, value)
+	}
+	if list == nil {
+		list = []string{}
+	}
+	b, _ := json.Marshal(list)
+	return string(b)
+}
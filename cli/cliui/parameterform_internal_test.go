@@ -0,0 +1,184 @@
+package cliui
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestParameterForm(t *testing.T) {
+	t.Parallel()
+
+	// evaluate renders a region with options, a zone that only exists in the
+	// "eu" region, and a number of CPUs that must be at most 4.
+	evaluate := func(_ context.Context, values map[string]string) (codersdk.DynamicParametersResponse, error) {
+		value := func(name, def string) codersdk.NullHCLString {
+			if v, ok := values[name]; ok {
+				return codersdk.NullHCLString{Value: v, Valid: true}
+			}
+			return codersdk.NullHCLString{Value: def, Valid: true}
+		}
+		option := func(v string) codersdk.PreviewParameterOption {
+			return codersdk.PreviewParameterOption{Name: v, Value: codersdk.NullHCLString{Value: v, Valid: true}}
+		}
+
+		region := codersdk.PreviewParameter{
+			PreviewParameterData: codersdk.PreviewParameterData{
+				Name:    "region",
+				Type:    codersdk.OptionTypeString,
+				Options: []codersdk.PreviewParameterOption{option("us"), option("eu")},
+			},
+			Value: value("region", "us"),
+		}
+		cpus := codersdk.PreviewParameter{
+			PreviewParameterData: codersdk.PreviewParameterData{
+				Name: "cpus",
+				Type: codersdk.OptionTypeNumber,
+			},
+			Value: value("cpus", "2"),
+		}
+		if n, _ := strconv.Atoi(cpus.Value.Value); n > 4 {
+			cpus.Diagnostics = []codersdk.FriendlyDiagnostic{{
+				Severity: codersdk.DiagnosticSeverityError,
+				Summary:  "At most 4 CPUs are allowed",
+			}}
+		}
+
+		res := codersdk.DynamicParametersResponse{Parameters: []codersdk.PreviewParameter{region}}
+		if region.Value.Value == "eu" {
+			res.Parameters = append(res.Parameters, codersdk.PreviewParameter{
+				PreviewParameterData: codersdk.PreviewParameterData{
+					Name:    "zone",
+					Type:    codersdk.OptionTypeString,
+					Options: []codersdk.PreviewParameterOption{option("eu-1"), option("eu-2")},
+				},
+				Value: value("zone", "eu-1"),
+			})
+		}
+		res.Parameters = append(res.Parameters, cpus)
+		return res, nil
+	}
+
+	// press sends a key to the form, and completes the evaluation it starts.
+	press := func(t *testing.T, m parameterFormModel, key tea.KeyMsg) parameterFormModel {
+		t.Helper()
+		evaluating := m.evaluating
+		model, cmd := m.Update(key)
+		m = model.(parameterFormModel)
+		if m.evaluating != evaluating && cmd != nil {
+			model, _ = m.Update(cmd())
+			m = model.(parameterFormModel)
+		}
+		return m
+	}
+	typeText := func(t *testing.T, m parameterFormModel, text string) parameterFormModel {
+		t.Helper()
+		for _, r := range text {
+			m = press(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		return m
+	}
+	newModel := func(t *testing.T) parameterFormModel {
+		t.Helper()
+		values := map[string]string{}
+		state, err := evaluate(context.Background(), values)
+		require.NoError(t, err)
+		return newParameterFormModel(context.Background(), ParameterFormOptions{
+			Message:  "Workspace parameters",
+			Evaluate: evaluate,
+		}, values, state)
+	}
+
+	t.Run("DependentOptions", func(t *testing.T) {
+		t.Parallel()
+		m := newModel(t)
+		require.Equal(t, "region", m.focus)
+
+		// Choosing the "eu" region adds a zone to the form.
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyRight})
+		require.Equal(t, map[string]string{"region": "eu", "cpus": "2", "zone": "eu-1"}, parameterFormValues(m.state))
+
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		require.Equal(t, "zone", m.focus)
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyRight})
+		require.Equal(t, "eu-2", parameterFormValues(m.state)["zone"])
+
+		// Revisiting the region removes the zone again.
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyUp})
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyLeft})
+		require.Equal(t, map[string]string{"region": "us", "cpus": "2"}, parameterFormValues(m.state))
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+		m := newModel(t)
+
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		require.Equal(t, "cpus", m.focus)
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+		m = typeText(t, m, "8")
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		require.Empty(t, m.focus, "the submit button should be focused")
+		require.Error(t, parameterFormError(m.state))
+		require.Contains(t, m.View(), "At most 4 CPUs are allowed")
+
+		// The form can't be submitted while a value is invalid.
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		require.False(t, m.submitted)
+
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyUp})
+		require.Equal(t, "cpus", m.focus)
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+		m = typeText(t, m, "4")
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		require.True(t, m.submitted)
+		require.Equal(t, map[string]string{"region": "us", "cpus": "4"}, parameterFormValues(m.state))
+	})
+
+	t.Run("SubmitWhileEvaluating", func(t *testing.T) {
+		t.Parallel()
+		m := newModel(t)
+
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+		m = typeText(t, m, "3")
+		// Leave the evaluation of the new value pending.
+		model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = model.(parameterFormModel)
+		require.NotNil(t, cmd)
+
+		model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = model.(parameterFormModel)
+		require.False(t, m.submitted)
+
+		model, _ = m.Update(cmd())
+		m = model.(parameterFormModel)
+		require.True(t, m.submitted)
+		require.Equal(t, "3", parameterFormValues(m.state)["cpus"])
+	})
+}
+
+func TestParameterFormListValue(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, `["a","b"]`, parameterFormListValue(" a, ,b "))
+	require.Equal(t, `[]`, parameterFormListValue(""))
+
+	p := codersdk.PreviewParameter{
+		PreviewParameterData: codersdk.PreviewParameterData{
+			Type: codersdk.OptionTypeListString,
+			Options: []codersdk.PreviewParameterOption{
+				{Name: "A", Value: codersdk.NullHCLString{Value: "a", Valid: true}},
+				{Name: "B", Value: codersdk.NullHCLString{Value: "b", Valid: true}},
+			},
+		},
+		Value: codersdk.NullHCLString{Value: `["a"]`, Valid: true},
+	}
+	require.Equal(t, `["a","b"]`, toggleParameterListOption(p, 1))
+	require.Equal(t, `[]`, toggleParameterListOption(p, 0))
+}
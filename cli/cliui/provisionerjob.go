@@ -89,6 +89,11 @@ func ProvisionerJob(ctx context.Context, wr io.Writer, opts ProvisionerJobOption
 			} else {
 				queuePos = fmt.Sprintf("position: %d", currentQueuePos)
 			}
+			if job.EstimatedStartAt != nil {
+				if wait := time.Until(*job.EstimatedStartAt); wait > 0 {
+					queuePos += fmt.Sprintf(", estimated start in %s", wait.Round(time.Second))
+				}
+			}
 
 			out = pretty.Sprintf(DefaultStyles.Warn, "%s (%s)", currentStage, queuePos)
 		}
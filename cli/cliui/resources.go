@@ -45,8 +45,30 @@ type WorkspaceResourcesOptions struct {
 // │ └─ postgres (linux, amd64)   ⦾ disconnected [4s]   coder ssh dev.postgres  │
 // └────────────────────────────────────────────────────────────────────────────┘
 func WorkspaceResources(writer io.Writer, resources []codersdk.WorkspaceResource, options WorkspaceResourcesOptions) error {
-	// Sort resources by type for consistent output.
+	// Sort resources by display group and order, falling back to type for
+	// consistent output. Ungrouped resources are displayed first, and groups
+	// are ordered by the lowest display order of their resources.
+	groupOrder := map[string]int32{}
+	for _, resource := range resources {
+		order, ok := groupOrder[resource.DisplayGroup]
+		if !ok || resource.DisplayOrder < order {
+			groupOrder[resource.DisplayGroup] = resource.DisplayOrder
+		}
+	}
 	sort.Slice(resources, func(i, j int) bool {
+		groupI, groupJ := resources[i].DisplayGroup, resources[j].DisplayGroup
+		if groupI != groupJ {
+			if groupI == "" || groupJ == "" {
+				return groupI == ""
+			}
+			if groupOrder[groupI] != groupOrder[groupJ] {
+				return groupOrder[groupI] < groupOrder[groupJ]
+			}
+			return groupI < groupJ
+		}
+		if resources[i].DisplayOrder != resources[j].DisplayOrder {
+			return resources[i].DisplayOrder < resources[j].DisplayOrder
+		}
 		return resources[i].Type < resources[j].Type
 	})
 
@@ -72,6 +94,7 @@ func WorkspaceResources(writer io.Writer, resources []codersdk.WorkspaceResource
 		totalAgents += len(resource.Agents)
 	}
 
+	currentGroup := ""
 	for _, resource := range resources {
 		if resource.Type == "random_string" {
 			// Hide resources that aren't substantial to a user!
@@ -79,10 +102,21 @@ func WorkspaceResources(writer io.Writer, resources []codersdk.WorkspaceResource
 			// callers to hide resources eventually.
 			continue
 		}
+		if resource.DisplayGroup != currentGroup {
+			// Display a header line when entering a new group.
+			currentGroup = resource.DisplayGroup
+			tableWriter.AppendRow(table.Row{
+				pretty.Sprint(DefaultStyles.Keyword, currentGroup),
+			})
+			tableWriter.AppendSeparator()
+		}
 		resourceAddress := resource.Type + "." + resource.Name
 
-		// Sort agents by name for consistent output.
+		// Sort agents by display group and name for consistent output.
 		sort.Slice(resource.Agents, func(i, j int) bool {
+			if resource.Agents[i].DisplayGroup != resource.Agents[j].DisplayGroup {
+				return resource.Agents[i].DisplayGroup < resource.Agents[j].DisplayGroup
+			}
 			return resource.Agents[i].Name < resource.Agents[j].Name
 		})
 
@@ -198,13 +232,26 @@ func renderDevcontainerRow(container codersdk.WorkspaceAgentContainer, index, to
 	}
 	row = append(row, sb.String())
 	sb.Reset()
-	// "health" is not applicable here.
-	row = append(row, sb.String())
+	row = append(row, renderContainerHealth(container))
 	_, _ = sb.WriteString(container.Image)
 	row = append(row, sb.String())
 	return row
 }
 
+func renderContainerHealth(container codersdk.WorkspaceAgentContainer) string {
+	switch container.Health {
+	case codersdk.WorkspaceAgentContainerHealthHealthy:
+		return pretty.Sprint(DefaultStyles.Keyword, "✔ healthy")
+	case codersdk.WorkspaceAgentContainerHealthUnhealthy:
+		return pretty.Sprint(DefaultStyles.Error, "✘ unhealthy")
+	case codersdk.WorkspaceAgentContainerHealthStarting:
+		return pretty.Sprint(DefaultStyles.Warn, "⦾ starting")
+	default:
+		// Containers without a health check have no health to report.
+		return ""
+	}
+}
+
 func renderAgentStatus(agent codersdk.WorkspaceAgent) string {
 	switch agent.Status {
 	case codersdk.WorkspaceAgentConnecting:
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/coder/coder/v2/cli/cliui"
@@ -110,4 +111,97 @@ func TestWorkspaceResources(t *testing.T) {
 		ptty.ExpectMatch("coder ssh dev.postgres")
 		<-done
 	})
+
+	t.Run("DisplayGroups", func(t *testing.T) {
+		t.Parallel()
+		ptty := ptytest.New(t)
+		done := make(chan struct{})
+		go func() {
+			err := cliui.WorkspaceResources(ptty.Output(), []codersdk.WorkspaceResource{{
+				Transition:   codersdk.WorkspaceTransitionStart,
+				Type:         "aws_s3_bucket",
+				Name:         "cache",
+				DisplayGroup: "Storage",
+				DisplayOrder: 2,
+			}, {
+				Transition:   codersdk.WorkspaceTransitionStart,
+				Type:         "aws_ebs_volume",
+				Name:         "home",
+				DisplayGroup: "Storage",
+				DisplayOrder: 1,
+			}, {
+				Transition:   codersdk.WorkspaceTransitionStart,
+				Type:         "aws_instance",
+				Name:         "dev",
+				DisplayGroup: "Compute",
+				Agents: []codersdk.WorkspaceAgent{{
+					Status:          codersdk.WorkspaceAgentConnected,
+					LifecycleState:  codersdk.WorkspaceAgentLifecycleReady,
+					Name:            "dev",
+					Architecture:    "amd64",
+					OperatingSystem: "linux",
+					Health:          codersdk.WorkspaceAgentHealth{Healthy: true},
+				}},
+			}, {
+				Transition: codersdk.WorkspaceTransitionStart,
+				Type:       "null_resource",
+				Name:       "ungrouped",
+			}}, cliui.WorkspaceResourcesOptions{
+				WorkspaceName: "dev",
+			})
+			assert.NoError(t, err)
+			close(done)
+		}()
+		ptty.ExpectMatch("null_resource.ungrouped")
+		ptty.ExpectMatch("Compute")
+		ptty.ExpectMatch("aws_instance.dev")
+		ptty.ExpectMatch("coder ssh dev")
+		ptty.ExpectMatch("Storage")
+		ptty.ExpectMatch("aws_ebs_volume.home")
+		ptty.ExpectMatch("aws_s3_bucket.cache")
+		<-done
+	})
+
+	t.Run("DevcontainerHealth", func(t *testing.T) {
+		t.Parallel()
+		ptty := ptytest.New(t)
+		agentID := uuid.New()
+		done := make(chan struct{})
+		go func() {
+			err := cliui.WorkspaceResources(ptty.Output(), []codersdk.WorkspaceResource{{
+				Transition: codersdk.WorkspaceTransitionStart,
+				Type:       "docker_container",
+				Name:       "dev",
+				Agents: []codersdk.WorkspaceAgent{{
+					ID:              agentID,
+					Status:          codersdk.WorkspaceAgentConnected,
+					LifecycleState:  codersdk.WorkspaceAgentLifecycleReady,
+					Name:            "dev",
+					Architecture:    "amd64",
+					OperatingSystem: "linux",
+					Health:          codersdk.WorkspaceAgentHealth{Healthy: true},
+				}},
+			}}, cliui.WorkspaceResourcesOptions{
+				WorkspaceName: "dev",
+				Devcontainers: map[uuid.UUID]codersdk.WorkspaceAgentListContainersResponse{
+					agentID: {
+						Containers: []codersdk.WorkspaceAgentContainer{{
+							FriendlyName: "api",
+							Image:        "debian:bookworm",
+							Running:      true,
+							Status:       "running",
+							Health:       codersdk.WorkspaceAgentContainerHealthUnhealthy,
+						}},
+					},
+				},
+			})
+			assert.NoError(t, err)
+			close(done)
+		}()
+		ptty.ExpectMatch("Devcontainers")
+		ptty.ExpectMatch("api")
+		ptty.ExpectMatch("unhealthy")
+		ptty.ExpectMatch("debian:bookworm")
+		<-done
+	})
 }
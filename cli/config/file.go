@@ -134,6 +134,14 @@ func read(path string) ([]byte, error) {
 	return io.ReadAll(fi)
 }
 
+// DeviceIDFile stores a unique identifier for the machine the CLI runs on. It
+// is sent with every request so the server can bind session tokens to it. It
+// is kept in the cache directory rather than beside the session token, so
+// copying the configuration directory doesn't carry the device along.
+func DeviceIDFile() File {
+	return File(filepath.Join(configdir.LocalCache("coderv2"), "device_id"))
+}
+
 func DefaultDir() string {
 	configDir := configdir.LocalConfig("coderv2")
 	if dir := os.Getenv("CLIDOCGEN_CONFIG_DIRECTORY"); dir != "" {
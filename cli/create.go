@@ -263,6 +263,17 @@ func (r *RootCmd) create() *serpent.Command {
 				}
 			}
 
+			var ownerID uuid.UUID
+			if parameterFlags.parameterForm {
+				// The parameters of the form are evaluated as the owner of the
+				// new workspace, which may be another user.
+				owner, err := client.User(inv.Context(), workspaceOwner)
+				if err != nil {
+					return xerrors.Errorf("get workspace owner: %w", err)
+				}
+				ownerID = owner.ID
+			}
+
 			richParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
 				Action:            WorkspaceCreate,
 				TemplateVersionID: templateVersionID,
@@ -273,6 +284,9 @@ func (r *RootCmd) create() *serpent.Command {
 				RichParameterDefaults: cliBuildParameterDefaults,
 
 				SourceWorkspaceParameters: sourceWorkspaceParameters,
+
+				ParameterForm: parameterFlags.parameterForm,
+				OwnerID:       ownerID,
 			})
 			if err != nil {
 				return xerrors.Errorf("prepare build: %w", err)
@@ -362,6 +376,7 @@ func (r *RootCmd) create() *serpent.Command {
 	)
 	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
 	cmd.Options = append(cmd.Options, parameterFlags.cliParameterDefaults()...)
+	cmd.Options = append(cmd.Options, parameterFlags.form())
 	orgContext.AttachOptions(cmd)
 	return cmd
 }
@@ -381,6 +396,12 @@ type prepWorkspaceBuildArgs struct {
 	RichParameters        []codersdk.WorkspaceBuildParameter
 	RichParameterFile     string
 	RichParameterDefaults []codersdk.WorkspaceBuildParameter
+	UseParameterDefaults  bool
+
+	// ParameterForm prompts with an interactive form, which evaluates the
+	// parameters as the workspace owned by OwnerID.
+	ParameterForm bool
+	OwnerID       uuid.UUID
 }
 
 // prepWorkspaceBuild will ensure a workspace build will succeed on the latest template version.
@@ -414,7 +435,29 @@ func prepWorkspaceBuild(inv *serpent.Invocation, client *codersdk.Client, args p
 		WithPromptRichParameters(args.PromptRichParameters).
 		WithRichParameters(args.RichParameters).
 		WithRichParametersFile(parameterFile).
-		WithRichParametersDefaults(args.RichParameterDefaults)
+		WithRichParametersDefaults(args.RichParameterDefaults).
+		WithUseParameterDefaults(args.UseParameterDefaults)
+	parameterForm := args.ParameterForm
+	if parameterForm && templateVersion.TemplateID != nil {
+		template, err := client.Template(ctx, *templateVersion.TemplateID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template: %w", err)
+		}
+		// Templates with classic parameters can't be evaluated as the user
+		// changes values.
+		if template.UseClassicParameterFlow {
+			cliui.Warn(inv.Stderr, fmt.Sprintf("Template %q does not use dynamic parameters, prompting for one parameter at a time.", template.Name))
+			parameterForm = false
+		}
+	}
+	if parameterForm {
+		resolver.WithParameterForm(func(ctx context.Context, inputs map[string]string) (codersdk.DynamicParametersResponse, error) {
+			return client.EvaluateTemplateVersionDynamicParameters(ctx, templateVersion.ID, codersdk.DynamicParametersRequest{
+				Inputs:  inputs,
+				OwnerID: args.OwnerID,
+			})
+		})
+	}
 	buildParameters, err := resolver.Resolve(inv, args.Action, templateVersionParameters)
 	if err != nil {
 		return nil, err
@@ -932,6 +932,137 @@ func TestCreateValidateRichParameters(t *testing.T) {
 	})
 }
 
+func TestCreateWithParameterForm(t *testing.T) {
+	t.Parallel()
+
+	const mainTF = `
+terraform {
+  required_providers {
+    coder = {
+      source = "coder/coder"
+    }
+  }
+}
+
+data "coder_workspace_owner" "me" {}
+
+data "coder_parameter" "region" {
+  name    = "region"
+  type    = "string"
+  default = "us"
+  mutable = true
+  option {
+    name  = "US"
+    value = "us"
+  }
+  option {
+    name  = "Europe"
+    value = "eu"
+  }
+}
+
+data "coder_parameter" "cpus" {
+  name    = "cpus"
+  type    = "number"
+  default = 2
+  mutable = true
+  validation {
+    min = 1
+    max = 4
+  }
+}
+`
+	staticParams := []*proto.RichParameter{
+		{
+			Name: "region", Type: "string", Mutable: true, DefaultValue: "us",
+			Options: []*proto.RichParameterOption{
+				{Name: "US", Value: "us"},
+				{Name: "Europe", Value: "eu"},
+			},
+		},
+		{Name: "cpus", Type: "number", Mutable: true, DefaultValue: "2", ValidationMin: ptr.Ref(int32(1)), ValidationMax: ptr.Ref(int32(4))},
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		template, _ := coderdtest.DynamicParameterTemplate(t, client, owner.OrganizationID, coderdtest.DynamicParameterTemplateParams{
+			MainTF:       mainTF,
+			StaticParams: staticParams,
+		})
+
+		inv, root := clitest.New(t, "create", "my-workspace", "--template", template.Name, "--parameter-form", "--parameter", "region=eu", "-y")
+		clitest.SetupConfig(t, member, root)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		workspace, err := member.WorkspaceByOwnerAndName(ctx, codersdk.Me, "my-workspace", codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+		buildParameters, err := member.WorkspaceBuildParameters(ctx, workspace.LatestBuild.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+			{Name: "region", Value: "eu"},
+			{Name: "cpus", Value: "2"},
+		}, buildParameters)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		template, _ := coderdtest.DynamicParameterTemplate(t, client, owner.OrganizationID, coderdtest.DynamicParameterTemplateParams{
+			MainTF:       mainTF,
+			StaticParams: staticParams,
+		})
+
+		// The form validates the values it starts with.
+		inv, root := clitest.New(t, "create", "my-workspace", "--template", template.Name, "--parameter-form", "--parameter-default", "cpus=8", "-y")
+		clitest.SetupConfig(t, member, root)
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.ErrorContains(t, err, `parameter "cpus"`)
+	})
+
+	t.Run("ClassicParameters", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, prepareEchoResponses(staticParams))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		inv, root := clitest.New(t, "create", "my-workspace", "--template", template.Name, "--parameter-form")
+		clitest.SetupConfig(t, member, root)
+		pty := ptytest.New(t).Attach(inv)
+		doneChan := make(chan struct{})
+		go func() {
+			defer close(doneChan)
+			err := inv.Run()
+			assert.NoError(t, err)
+		}()
+
+		matches := []string{
+			"does not use dynamic parameters", "",
+			"cpus", "3",
+			"Confirm create?", "yes",
+		}
+		for i := 0; i < len(matches); i += 2 {
+			pty.ExpectMatch(matches[i])
+			if matches[i+1] != "" {
+				pty.WriteLine(matches[i+1])
+			}
+		}
+		<-doneChan
+	})
+}
+
 func TestCreateWithGitAuth(t *testing.T) {
 	t.Parallel()
 	echoResponses := &echo.Responses{
@@ -0,0 +1,318 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/coderd/healthcheck/derphealth"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+type doctorStatus string
+
+const (
+	doctorStatusOK      doctorStatus = "ok"
+	doctorStatusWarning doctorStatus = "warning"
+	doctorStatusError   doctorStatus = "error"
+	doctorStatusSkipped doctorStatus = "skipped"
+)
+
+type doctorCheck struct {
+	Name     string       `json:"name"`
+	Status   doctorStatus `json:"status"`
+	Message  string       `json:"message"`
+	Details  []string     `json:"details,omitempty"`
+	Duration string       `json:"duration"`
+}
+
+type doctorReport struct {
+	Time          time.Time     `json:"time"`
+	AccessURL     string        `json:"access_url"`
+	ClientVersion string        `json:"client_version"`
+	ClientOS      string        `json:"client_os"`
+	ClientArch    string        `json:"client_arch"`
+	ServerVersion string        `json:"server_version,omitempty"`
+	Workspace     string        `json:"workspace,omitempty"`
+	Checks        []doctorCheck `json:"checks"`
+}
+
+func (r *RootCmd) doctor() *serpent.Command {
+	var output string
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "doctor [workspace]",
+		Short: "Diagnose connectivity to the Coder deployment and a workspace",
+		Long: "Checks API reachability, version skew, websocket upgrades and DERP relays, " +
+			"and, when a workspace is given, connectivity to its agent. " +
+			"Attach the output when asking for support.\n\n" + FormatExamples(
+			Example{
+				Description: "Check connectivity to the deployment",
+				Command:     "coder doctor",
+			},
+			Example{
+				Description: "Also check connectivity to a workspace agent",
+				Command:     "coder doctor my-workspace",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			report := doctorReport{
+				Time:          time.Now().UTC(),
+				AccessURL:     client.URL.String(),
+				ClientVersion: buildinfo.Version(),
+				ClientOS:      runtime.GOOS,
+				ClientArch:    runtime.GOARCH,
+			}
+			if len(inv.Args) > 0 {
+				report.Workspace = inv.Args[0]
+			}
+
+			if output != "json" {
+				_, _ = fmt.Fprint(inv.Stderr, "Running checks. This may take a few seconds...\n\n")
+			}
+
+			var buildInfo codersdk.BuildInfoResponse
+			report.Checks = append(report.Checks, runDoctorCheck("API reachability", func() doctorCheck {
+				var err error
+				start := time.Now()
+				buildInfo, err = client.BuildInfo(ctx)
+				if err != nil {
+					return doctorCheck{Status: doctorStatusError, Message: fmt.Sprintf("Unable to reach %s: %s", client.URL, err)}
+				}
+				if _, err := client.User(ctx, codersdk.Me); err != nil {
+					return doctorCheck{Status: doctorStatusError, Message: fmt.Sprintf("Reached the deployment, but authentication failed: %s", err)}
+				}
+				return doctorCheck{Status: doctorStatusOK, Message: fmt.Sprintf("Reached %s and authenticated in %s", client.URL, time.Since(start).Round(time.Millisecond))}
+			}))
+			report.ServerVersion = buildInfo.Version
+
+			var agent codersdk.WorkspaceAgent
+			var agentErr error
+			if report.Workspace != "" && report.ServerVersion != "" {
+				_, agent, agentErr = getWorkspaceAndAgent(ctx, inv, client, false, report.Workspace)
+			}
+
+			report.Checks = append(report.Checks, runDoctorCheck("Version skew", func() doctorCheck {
+				if report.ServerVersion == "" {
+					return doctorCheck{Status: doctorStatusSkipped, Message: "The server version is unknown."}
+				}
+				check := doctorCheck{Status: doctorStatusOK, Message: fmt.Sprintf("Client and server are both running %s", report.ServerVersion)}
+				if !buildinfo.VersionsMatch(report.ClientVersion, report.ServerVersion) {
+					check.Status = doctorStatusWarning
+					check.Message = fmt.Sprintf("Client %s does not match server %s. Download a matching client from %s",
+						report.ClientVersion, report.ServerVersion, client.URL.JoinPath("bin"))
+				}
+				if agent.ID != uuid.Nil && agent.Version != "" && !buildinfo.VersionsMatch(agent.Version, report.ServerVersion) {
+					check.Status = doctorStatusWarning
+					check.Details = append(check.Details, fmt.Sprintf("Agent %s is running %s. Restart the workspace to update it.", agent.Name, agent.Version))
+				}
+				return check
+			}))
+
+			report.Checks = append(report.Checks, runDoctorCheck("Websocket upgrade", func() doctorCheck {
+				if report.ServerVersion == "" {
+					return doctorCheck{Status: doctorStatusSkipped, Message: "The API is unreachable."}
+				}
+				var wsReport healthcheck.WebsocketReport
+				wsReport.Run(ctx, &healthcheck.WebsocketReportOptions{
+					APIKey:     client.SessionToken(),
+					AccessURL:  client.URL,
+					HTTPClient: client.HTTPClient,
+				})
+				if wsReport.Code == http.StatusForbidden {
+					return doctorCheck{Status: doctorStatusSkipped, Message: "Checking websockets requires permission to read debug info. The workspace check also uses a websocket."}
+				}
+				if wsReport.Error != nil {
+					return doctorCheck{Status: doctorStatusError, Message: *wsReport.Error, Details: doctorWarnings(wsReport.Warnings)}
+				}
+				return doctorCheck{Status: doctorStatusOK, Message: "Upgraded a connection to a websocket and echoed messages", Details: doctorWarnings(wsReport.Warnings)}
+			}))
+
+			report.Checks = append(report.Checks, runDoctorCheck("DERP", func() doctorCheck {
+				if report.ServerVersion == "" {
+					return doctorCheck{Status: doctorStatusSkipped, Message: "The API is unreachable."}
+				}
+				connInfo, err := workspacesdk.New(client).AgentConnectionInfoGeneric(ctx)
+				if err != nil {
+					return doctorCheck{Status: doctorStatusError, Message: fmt.Sprintf("Unable to fetch the DERP map: %s", err)}
+				}
+				derpCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				defer cancel()
+				var derpReport derphealth.Report
+				derpReport.Run(derpCtx, &derphealth.ReportOptions{
+					DERPMap: connInfo.DERPMap,
+				})
+
+				check := doctorCheck{
+					Status:  doctorStatusFromSeverity(derpReport.Severity),
+					Message: fmt.Sprintf("Checked %d DERP region(s)", len(derpReport.Regions)),
+					Details: doctorWarnings(derpReport.Warnings),
+				}
+				if derpReport.Error != nil {
+					check.Details = append(check.Details, *derpReport.Error)
+				}
+				var regionErrors []string
+				for _, region := range derpReport.Regions {
+					if region.Error != nil && region.Region != nil {
+						regionErrors = append(regionErrors, fmt.Sprintf("%s: %s", region.Region.RegionName, *region.Error))
+					}
+				}
+				slices.Sort(regionErrors)
+				check.Details = append(check.Details, regionErrors...)
+				return check
+			}))
+
+			report.Checks = append(report.Checks, runDoctorCheck("Workspace agent", func() doctorCheck {
+				if report.Workspace == "" {
+					return doctorCheck{Status: doctorStatusSkipped, Message: "No workspace was specified."}
+				}
+				if report.ServerVersion == "" {
+					return doctorCheck{Status: doctorStatusSkipped, Message: "The API is unreachable."}
+				}
+				if agentErr != nil {
+					return doctorCheck{Status: doctorStatusError, Message: agentErr.Error()}
+				}
+				if agent.Status != codersdk.WorkspaceAgentConnected {
+					return doctorCheck{Status: doctorStatusError, Message: fmt.Sprintf("Agent %s is %s", agent.Name, agent.Status)}
+				}
+
+				dialCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				defer cancel()
+				conn, err := workspacesdk.New(client).DialAgent(dialCtx, agent.ID, &workspacesdk.DialAgentOptions{})
+				if err != nil {
+					return doctorCheck{Status: doctorStatusError, Message: fmt.Sprintf("Unable to dial agent %s: %s", agent.Name, err)}
+				}
+				defer conn.Close()
+				if !conn.AwaitReachable(dialCtx) {
+					return doctorCheck{Status: doctorStatusError, Message: fmt.Sprintf("Agent %s is unreachable", agent.Name)}
+				}
+				latency, p2p, _, err := conn.Ping(dialCtx)
+				if err != nil {
+					return doctorCheck{Status: doctorStatusError, Message: fmt.Sprintf("Unable to ping agent %s: %s", agent.Name, err)}
+				}
+				via := "a DERP relay"
+				if p2p {
+					via = "a direct connection"
+				}
+				return doctorCheck{Status: doctorStatusOK, Message: fmt.Sprintf("Reached agent %s over %s in %s", agent.Name, via, latency.Round(time.Millisecond))}
+			}))
+
+			if output == "json" {
+				enc := json.NewEncoder(inv.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				writeDoctorReport(inv, report)
+			}
+
+			var failed int
+			for _, check := range report.Checks {
+				if check.Status == doctorStatusError {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return xerrors.Errorf("%d check(s) failed", failed)
+			}
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "Output format.",
+			Default:       "text",
+			Value:         serpent.EnumOf(&output, "text", "json"),
+		},
+	}
+	return cmd
+}
+
+func runDoctorCheck(name string, fn func() doctorCheck) doctorCheck {
+	start := time.Now()
+	check := fn()
+	check.Name = name
+	check.Duration = time.Since(start).Round(time.Millisecond).String()
+	return check
+}
+
+func doctorStatusFromSeverity(severity health.Severity) doctorStatus {
+	switch severity {
+	case health.SeverityError:
+		return doctorStatusError
+	case health.SeverityWarning:
+		return doctorStatusWarning
+	default:
+		return doctorStatusOK
+	}
+}
+
+func doctorWarnings(warnings []health.Message) []string {
+	details := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		details = append(details, warning.String())
+	}
+	return details
+}
+
+func writeDoctorReport(inv *serpent.Invocation, report doctorReport) {
+	_, _ = fmt.Fprintf(inv.Stdout, "Access URL:     %s\n", report.AccessURL)
+	_, _ = fmt.Fprintf(inv.Stdout, "Client version: %s (%s/%s)\n", report.ClientVersion, report.ClientOS, report.ClientArch)
+	if report.ServerVersion != "" {
+		_, _ = fmt.Fprintf(inv.Stdout, "Server version: %s\n", report.ServerVersion)
+	}
+	if report.Workspace != "" {
+		_, _ = fmt.Fprintf(inv.Stdout, "Workspace:      %s\n", report.Workspace)
+	}
+	_, _ = fmt.Fprintf(inv.Stdout, "Time:           %s\n\n", report.Time.Format(time.RFC3339))
+
+	for _, check := range report.Checks {
+		var (
+			mark  string
+			style pretty.Style
+		)
+		switch check.Status {
+		case doctorStatusOK:
+			mark, style = "✔", cliui.DefaultStyles.Keyword
+		case doctorStatusWarning:
+			mark, style = "⚠", cliui.DefaultStyles.Warn
+		case doctorStatusError:
+			mark, style = "✘", cliui.DefaultStyles.Error
+		case doctorStatusSkipped:
+			mark, style = "-", cliui.DefaultStyles.Placeholder
+		}
+		_, _ = fmt.Fprintf(inv.Stdout, "%s %s: %s %s\n",
+			pretty.Sprint(style, mark),
+			pretty.Sprint(cliui.DefaultStyles.Field, check.Name),
+			check.Message,
+			pretty.Sprint(cliui.DefaultStyles.Placeholder, "["+check.Duration+"]"),
+		)
+		for _, detail := range check.Details {
+			_, _ = fmt.Fprintf(inv.Stdout, "    %s\n", strings.TrimSpace(detail))
+		}
+	}
+}
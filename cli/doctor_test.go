@@ -0,0 +1,110 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type doctorReport struct {
+	ServerVersion string `json:"server_version"`
+	Workspace     string `json:"workspace"`
+	Checks        []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	} `json:"checks"`
+}
+
+func (r doctorReport) status(name string) string {
+	for _, check := range r.Checks {
+		if check.Name == name {
+			return check.Status
+		}
+	}
+	return ""
+}
+
+func (r doctorReport) failed() bool {
+	for _, check := range r.Checks {
+		if check.Status == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDoctor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deployment", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "doctor", "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+
+		var report doctorReport
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report), out.String())
+		// DERP connectivity depends on the test environment, so only assert
+		// that the command fails exactly when a check does.
+		require.Equal(t, report.failed(), err != nil)
+		require.NotEmpty(t, report.ServerVersion)
+		require.Len(t, report.Checks, 5)
+		require.Equal(t, "ok", report.status("API reachability"))
+		require.Equal(t, "ok", report.status("Version skew"))
+		require.Equal(t, "ok", report.status("Websocket upgrade"))
+		require.Equal(t, "skipped", report.status("Workspace agent"))
+	})
+
+	t.Run("Workspace", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+		_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		inv, root := clitest.New(t, "doctor", workspace.Name, "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+
+		var report doctorReport
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report), out.String())
+		require.Equal(t, report.failed(), err != nil)
+		require.Equal(t, workspace.Name, report.Workspace)
+		require.Equal(t, "ok", report.status("API reachability"))
+		// Members are not allowed to use the debug websocket endpoint.
+		require.Equal(t, "skipped", report.status("Websocket upgrade"))
+		require.Equal(t, "ok", report.status("Workspace agent"))
+	})
+
+	t.Run("AgentDisconnected", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, _ := setupWorkspaceForAgent(t)
+
+		inv, root := clitest.New(t, "doctor", workspace.Name, "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.ErrorContains(t, err, "check(s) failed")
+
+		var report doctorReport
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report), out.String())
+		require.Equal(t, "error", report.status("Workspace agent"))
+	})
+}
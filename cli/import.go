@@ -0,0 +1,565 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+// importBundle is the file format accepted by "coder import". Users are
+// imported first, then groups, then workspaces, so groups and workspaces may
+// refer to users from the same bundle.
+type importBundle struct {
+	Users      []importUser      `json:"users"`
+	Groups     []importGroup     `json:"groups"`
+	Workspaces []importWorkspace `json:"workspaces"`
+}
+
+type importUser struct {
+	Username  string             `json:"username"`
+	Email     string             `json:"email"`
+	Name      string             `json:"name"`
+	LoginType codersdk.LoginType `json:"login_type"`
+	Password  string             `json:"password"`
+}
+
+type importGroup struct {
+	Name           string   `json:"name"`
+	DisplayName    string   `json:"display_name"`
+	QuotaAllowance int      `json:"quota_allowance"`
+	Members        []string `json:"members"`
+}
+
+type importWorkspace struct {
+	Owner      string            `json:"owner"`
+	Name       string            `json:"name"`
+	Template   string            `json:"template"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+type importStatus string
+
+const (
+	importStatusValid   importStatus = "valid"
+	importStatusCreated importStatus = "created"
+	importStatusUpdated importStatus = "updated"
+	importStatusSkipped importStatus = "skipped"
+	importStatusFailed  importStatus = "failed"
+)
+
+type importResult struct {
+	Kind   string       `json:"kind" table:"kind,nosort"`
+	Name   string       `json:"name" table:"name"`
+	Status importStatus `json:"status" table:"status"`
+	Detail string       `json:"detail" table:"detail"`
+}
+
+func (r *RootCmd) importCmd() *serpent.Command {
+	var (
+		dryRun           bool
+		defaultLoginType string
+		orgContext       = NewOrganizationContext()
+		formatter        = cliui.NewOutputFormatter(
+			cliui.TableFormat([]importResult{}, []string{"kind", "name", "status", "detail"}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "import <file>",
+		Short: "Import users, groups, and workspaces from a bundle.",
+		Long: `Import users, groups, and workspaces into an organization, for example when
+migrating from another development environment platform. Existing entities
+are skipped, so an import can safely be retried after fixing failed items.
+
+The bundle is a JSON file with the following format:
+
+  {
+    "users": [
+      {"username": "alice", "email": "alice@example.com", "name": "Alice", "login_type": "oidc"}
+    ],
+    "groups": [
+      {"name": "developers", "display_name": "Developers", "quota_allowance": 0, "members": ["alice"]}
+    ],
+    "workspaces": [
+      {"owner": "alice", "name": "dev", "template": "docker", "parameters": {"region": "eu"}}
+    ]
+  }
+
+Users with the "password" login type must have a password. Group members and
+workspace owners may be existing users or users from the same bundle. Groups
+require a license with the template RBAC feature. Existing groups get the
+listed members added.
+
+Files with a .csv extension are read as a list of users, with a header row
+naming the columns "username", "email", and optionally "name", "login_type",
+and "password".
+
+` + FormatExamples(
+			Example{
+				Description: "Validate a bundle without creating anything",
+				Command:     "coder import --dry-run bundle.json",
+			},
+			Example{
+				Description: "Import users from a CSV file",
+				Command:     "coder import users.csv",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+
+			bundle, err := readImportBundle(inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			authMethods, err := client.AuthMethods(ctx)
+			if err != nil {
+				return xerrors.Errorf("get auth methods: %w", err)
+			}
+
+			imp := &importer{
+				client:           client,
+				organization:     organization,
+				oidcEnabled:      authMethods.OIDC.Enabled,
+				dryRun:           dryRun,
+				defaultLoginType: codersdk.LoginType(defaultLoginType),
+				users:            make(map[string]uuid.UUID),
+				templates:        make(map[string]importTemplate),
+			}
+			results := imp.importUsers(ctx, bundle.Users)
+			results = append(results, imp.importGroups(ctx, bundle.Groups)...)
+			results = append(results, imp.importWorkspaces(ctx, bundle.Workspaces)...)
+
+			out, err := formatter.Format(ctx, results)
+			if err != nil {
+				return xerrors.Errorf("format results: %w", err)
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+
+			failed := 0
+			for _, result := range results {
+				if result.Status == importStatusFailed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return xerrors.Errorf("%d of %d items failed to import", failed, len(results))
+			}
+			if dryRun {
+				cliui.Info(inv.Stderr, "Dry run complete, no changes were made.")
+			}
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "dry-run",
+			Env:         "CODER_IMPORT_DRY_RUN",
+			Description: "Validate the bundle and report what would be imported without making any changes.",
+			Value:       serpent.BoolOf(&dryRun),
+		},
+		{
+			Flag:        "default-login-type",
+			Env:         "CODER_IMPORT_DEFAULT_LOGIN_TYPE",
+			Description: "The login type of imported users that do not specify one.",
+			Default:     string(codersdk.LoginTypeOIDC),
+			Value: serpent.EnumOf(&defaultLoginType,
+				string(codersdk.LoginTypePassword), string(codersdk.LoginTypeNone), string(codersdk.LoginTypeGithub), string(codersdk.LoginTypeOIDC),
+			),
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// readImportBundle reads a JSON bundle, or a CSV file of users.
+func readImportBundle(path string) (importBundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return importBundle{}, xerrors.Errorf("open bundle: %w", err)
+	}
+	defer f.Close()
+
+	var bundle importBundle
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		bundle.Users, err = readImportUsersCSV(f)
+		if err != nil {
+			return importBundle{}, xerrors.Errorf("read users csv: %w", err)
+		}
+		return bundle, nil
+	}
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&bundle); err != nil {
+		return importBundle{}, xerrors.Errorf("decode bundle: %w", err)
+	}
+	return bundle, nil
+}
+
+func readImportUsersCSV(r io.Reader) ([]importUser, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		column = strings.ToLower(strings.TrimSpace(column))
+		switch column {
+		case "username", "email", "name", "login_type", "password":
+			columns[column] = i
+		default:
+			return nil, xerrors.Errorf("unknown column %q", column)
+		}
+	}
+	for _, required := range []string{"username", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, xerrors.Errorf("missing column %q", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	users := make([]importUser, 0, len(records)-1)
+	for _, record := range records[1:] {
+		users = append(users, importUser{
+			Username:  field(record, "username"),
+			Email:     field(record, "email"),
+			Name:      field(record, "name"),
+			LoginType: codersdk.LoginType(field(record, "login_type")),
+			Password:  field(record, "password"),
+		})
+	}
+	return users, nil
+}
+
+type importTemplate struct {
+	template   codersdk.Template
+	parameters []codersdk.TemplateVersionParameter
+}
+
+type importer struct {
+	client           *codersdk.Client
+	organization     codersdk.Organization
+	dryRun           bool
+	defaultLoginType codersdk.LoginType
+	oidcEnabled      bool
+
+	// users maps usernames to the IDs of existing and imported users. Users
+	// that would be created by a dry run map to uuid.Nil.
+	users     map[string]uuid.UUID
+	templates map[string]importTemplate
+}
+
+func (imp *importer) importUsers(ctx context.Context, users []importUser) []importResult {
+	results := make([]importResult, 0, len(users))
+	for _, user := range users {
+		result := importResult{Kind: "user", Name: user.Username}
+		result.Status, result.Detail = imp.importUser(ctx, user)
+		results = append(results, result)
+	}
+	return results
+}
+
+func (imp *importer) importUser(ctx context.Context, user importUser) (importStatus, string) {
+	if err := codersdk.NameValid(user.Username); err != nil {
+		return importStatusFailed, fmt.Sprintf("Invalid username: %s.", err)
+	}
+	if _, ok := imp.users[user.Username]; ok {
+		return importStatusFailed, "Duplicate username in bundle."
+	}
+	if err := validator.New().Var(user.Email, "required,email"); err != nil {
+		return importStatusFailed, fmt.Sprintf("Invalid email address %q.", user.Email)
+	}
+	loginType := user.LoginType
+	if loginType == "" {
+		loginType = imp.defaultLoginType
+	}
+	switch loginType {
+	case codersdk.LoginTypePassword:
+		if user.Password == "" {
+			return importStatusFailed, "A password is required for the password login type."
+		}
+	case codersdk.LoginTypeNone, codersdk.LoginTypeGithub, codersdk.LoginTypeOIDC:
+		if user.Password != "" {
+			return importStatusFailed, fmt.Sprintf("A password cannot be set for the %s login type.", loginType)
+		}
+		if loginType == codersdk.LoginTypeOIDC && !imp.oidcEnabled {
+			return importStatusFailed, "OIDC must be configured before importing OIDC users."
+		}
+	default:
+		return importStatusFailed, fmt.Sprintf("Invalid login type %q.", loginType)
+	}
+
+	existing, ok, err := imp.lookupUser(ctx, user.Username)
+	if err != nil {
+		return importStatusFailed, importErrorDetail(err)
+	}
+	if ok {
+		imp.users[user.Username] = existing.ID
+		return importStatusSkipped, "User already exists."
+	}
+
+	if imp.dryRun {
+		imp.users[user.Username] = uuid.Nil
+		return importStatusValid, ""
+	}
+	created, err := imp.client.CreateUserWithOrgs(ctx, codersdk.CreateUserRequestWithOrgs{
+		Email:           user.Email,
+		Username:        user.Username,
+		Name:            user.Name,
+		Password:        user.Password,
+		UserLoginType:   loginType,
+		OrganizationIDs: []uuid.UUID{imp.organization.ID},
+	})
+	if err != nil {
+		return importStatusFailed, importErrorDetail(err)
+	}
+	imp.users[user.Username] = created.ID
+	return importStatusCreated, ""
+}
+
+// resolveUser returns the ID of an existing or imported user.
+func (imp *importer) resolveUser(ctx context.Context, username string) (uuid.UUID, error) {
+	if id, ok := imp.users[username]; ok {
+		return id, nil
+	}
+	user, ok, err := imp.lookupUser(ctx, username)
+	if err != nil {
+		return uuid.Nil, xerrors.New(importErrorDetail(err))
+	}
+	if !ok {
+		return uuid.Nil, xerrors.Errorf("User %q does not exist.", username)
+	}
+	imp.users[username] = user.ID
+	return user.ID, nil
+}
+
+// lookupUser finds a user by username. The user endpoint does not distinguish
+// unknown users from other bad requests, so users are searched for instead.
+func (imp *importer) lookupUser(ctx context.Context, username string) (codersdk.User, bool, error) {
+	res, err := imp.client.Users(ctx, codersdk.UsersRequest{Search: username})
+	if err != nil {
+		return codersdk.User{}, false, err
+	}
+	for _, user := range res.Users {
+		if strings.EqualFold(user.Username, username) {
+			return user, true, nil
+		}
+	}
+	return codersdk.User{}, false, nil
+}
+
+func (imp *importer) importGroups(ctx context.Context, groups []importGroup) []importResult {
+	results := make([]importResult, 0, len(groups))
+	for _, group := range groups {
+		result := importResult{Kind: "group", Name: group.Name}
+		result.Status, result.Detail = imp.importGroup(ctx, group)
+		results = append(results, result)
+	}
+	return results
+}
+
+func (imp *importer) importGroup(ctx context.Context, group importGroup) (importStatus, string) {
+	if err := codersdk.GroupNameValid(group.Name); err != nil {
+		return importStatusFailed, fmt.Sprintf("Invalid group name: %s.", err)
+	}
+	if group.DisplayName != "" {
+		if err := codersdk.DisplayNameValid(group.DisplayName); err != nil {
+			return importStatusFailed, fmt.Sprintf("Invalid display name: %s.", err)
+		}
+	}
+	memberIDs := make([]uuid.UUID, 0, len(group.Members))
+	for _, member := range group.Members {
+		id, err := imp.resolveUser(ctx, member)
+		if err != nil {
+			return importStatusFailed, err.Error()
+		}
+		memberIDs = append(memberIDs, id)
+	}
+
+	existing, err := imp.client.GroupByOrgAndName(ctx, imp.organization.ID, group.Name)
+	exists := err == nil
+	if err != nil && !isImportNotFound(err) {
+		return importStatusFailed, importErrorDetail(err)
+	}
+
+	addUsers := make([]string, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		if exists && slices.ContainsFunc(existing.Members, func(m codersdk.ReducedUser) bool {
+			return m.ID == id
+		}) {
+			continue
+		}
+		addUsers = append(addUsers, id.String())
+	}
+	if exists && len(addUsers) == 0 {
+		return importStatusSkipped, "Group already exists with all members."
+	}
+	if imp.dryRun {
+		if exists {
+			return importStatusValid, fmt.Sprintf("Would add %d members to the existing group.", len(addUsers))
+		}
+		return importStatusValid, ""
+	}
+
+	status := importStatusUpdated
+	if !exists {
+		existing, err = imp.client.CreateGroup(ctx, imp.organization.ID, codersdk.CreateGroupRequest{
+			Name:           group.Name,
+			DisplayName:    group.DisplayName,
+			QuotaAllowance: group.QuotaAllowance,
+		})
+		if err != nil {
+			return importStatusFailed, importErrorDetail(err)
+		}
+		status = importStatusCreated
+	}
+	if len(addUsers) > 0 {
+		_, err = imp.client.PatchGroup(ctx, existing.ID, codersdk.PatchGroupRequest{
+			AddUsers: addUsers,
+		})
+		if err != nil {
+			return importStatusFailed, fmt.Sprintf("Add members: %s", importErrorDetail(err))
+		}
+	}
+	return status, ""
+}
+
+func (imp *importer) importWorkspaces(ctx context.Context, workspaces []importWorkspace) []importResult {
+	results := make([]importResult, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		result := importResult{Kind: "workspace", Name: workspace.Owner + "/" + workspace.Name}
+		result.Status, result.Detail = imp.importWorkspace(ctx, workspace)
+		results = append(results, result)
+	}
+	return results
+}
+
+func (imp *importer) importWorkspace(ctx context.Context, workspace importWorkspace) (importStatus, string) {
+	if err := codersdk.NameValid(workspace.Name); err != nil {
+		return importStatusFailed, fmt.Sprintf("Invalid workspace name: %s.", err)
+	}
+	ownerID, err := imp.resolveUser(ctx, workspace.Owner)
+	if err != nil {
+		return importStatusFailed, err.Error()
+	}
+	template, err := imp.template(ctx, workspace.Template)
+	if err != nil {
+		return importStatusFailed, err.Error()
+	}
+
+	parameters := make([]codersdk.WorkspaceBuildParameter, 0, len(workspace.Parameters))
+	for name, value := range workspace.Parameters {
+		if !slices.ContainsFunc(template.parameters, func(p codersdk.TemplateVersionParameter) bool {
+			return p.Name == name
+		}) {
+			return importStatusFailed, fmt.Sprintf("Template %q has no parameter %q.", workspace.Template, name)
+		}
+		parameters = append(parameters, codersdk.WorkspaceBuildParameter{Name: name, Value: value})
+	}
+	for _, p := range template.parameters {
+		if _, ok := workspace.Parameters[p.Name]; !ok && p.Required {
+			return importStatusFailed, fmt.Sprintf("Missing required parameter %q.", p.Name)
+		}
+	}
+	slices.SortFunc(parameters, func(a, b codersdk.WorkspaceBuildParameter) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	// Users created by a dry run have no workspaces yet.
+	if ownerID != uuid.Nil {
+		_, err = imp.client.WorkspaceByOwnerAndName(ctx, ownerID.String(), workspace.Name, codersdk.WorkspaceOptions{})
+		if err == nil {
+			return importStatusSkipped, "Workspace already exists."
+		}
+		if !isImportNotFound(err) {
+			return importStatusFailed, importErrorDetail(err)
+		}
+	}
+	if imp.dryRun {
+		return importStatusValid, ""
+	}
+
+	_, err = imp.client.CreateUserWorkspace(ctx, ownerID.String(), codersdk.CreateWorkspaceRequest{
+		TemplateID:          template.template.ID,
+		Name:                workspace.Name,
+		RichParameterValues: parameters,
+	})
+	if err != nil {
+		return importStatusFailed, importErrorDetail(err)
+	}
+	return importStatusCreated, ""
+}
+
+// template returns a template of the organization and the parameters of its
+// active version.
+func (imp *importer) template(ctx context.Context, name string) (importTemplate, error) {
+	if template, ok := imp.templates[name]; ok {
+		return template, nil
+	}
+	template, err := imp.client.TemplateByName(ctx, imp.organization.ID, name)
+	if err != nil {
+		if isImportNotFound(err) {
+			return importTemplate{}, xerrors.Errorf("Template %q does not exist.", name)
+		}
+		return importTemplate{}, xerrors.New(importErrorDetail(err))
+	}
+	parameters, err := imp.client.TemplateVersionRichParameters(ctx, template.ActiveVersionID)
+	if err != nil {
+		return importTemplate{}, xerrors.Errorf("Get template parameters: %s", importErrorDetail(err))
+	}
+	imp.templates[name] = importTemplate{template: template, parameters: parameters}
+	return imp.templates[name], nil
+}
+
+func isImportNotFound(err error) bool {
+	sdkErr, ok := codersdk.AsError(err)
+	return ok && sdkErr.StatusCode() == http.StatusNotFound
+}
+
+// importErrorDetail returns a single line describing err, suitable for the
+// results table.
+func importErrorDetail(err error) string {
+	if sdkErr, ok := codersdk.AsError(err); ok {
+		if sdkErr.Detail != "" {
+			return sdkErr.Message + " " + sdkErr.Detail
+		}
+		return sdkErr.Message
+	}
+	return err.Error()
+}
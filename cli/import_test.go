@@ -0,0 +1,150 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type importResult struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+func runImport(t *testing.T, client *codersdk.Client, bundle string, ext string, args ...string) ([]importResult, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bundle"+ext)
+	require.NoError(t, os.WriteFile(path, []byte(bundle), 0o600))
+
+	inv, root := clitest.New(t, append([]string{"import", path, "-o", "json"}, args...)...)
+	clitest.SetupConfig(t, client, root)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	runErr := inv.Run()
+
+	var results []importResult
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &results), stdout.String())
+	return results, runErr
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("UsersAndWorkspaces", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, prepareEchoResponses([]*proto.RichParameter{
+			{Name: "region", Type: "string", Required: true},
+		}))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		bundle := `{
+			"users": [
+				{"username": "alice", "email": "alice@coder.com", "name": "Alice", "login_type": "none"},
+				{"username": "bob", "email": "bob@coder.com", "login_type": "password", "password": "SomeSecurePassword!"}
+			],
+			"workspaces": [
+				{"owner": "alice", "name": "dev", "template": "` + template.Name + `", "parameters": {"region": "eu"}}
+			]
+		}`
+
+		// A dry run validates the bundle without creating anything.
+		results, err := runImport(t, client, bundle, ".json", "--dry-run")
+		require.NoError(t, err)
+		require.Equal(t, []importResult{
+			{Kind: "user", Name: "alice", Status: "valid"},
+			{Kind: "user", Name: "bob", Status: "valid"},
+			{Kind: "workspace", Name: "alice/dev", Status: "valid"},
+		}, results)
+		_, err = client.User(ctx, "alice")
+		require.Error(t, err)
+
+		results, err = runImport(t, client, bundle, ".json")
+		require.NoError(t, err)
+		require.Equal(t, []importResult{
+			{Kind: "user", Name: "alice", Status: "created"},
+			{Kind: "user", Name: "bob", Status: "created"},
+			{Kind: "workspace", Name: "alice/dev", Status: "created"},
+		}, results)
+
+		alice, err := client.User(ctx, "alice")
+		require.NoError(t, err)
+		require.Equal(t, codersdk.LoginTypeNone, alice.LoginType)
+		workspace, err := client.WorkspaceByOwnerAndName(ctx, "alice", "dev", codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+		require.Equal(t, template.ID, workspace.TemplateID)
+
+		// Importing again skips existing entities.
+		results, err = runImport(t, client, bundle, ".json")
+		require.NoError(t, err)
+		for _, result := range results {
+			require.Equal(t, "skipped", result.Status, result.Name)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		results, err := runImport(t, client, "username,email,login_type\ncarol,carol@coder.com,none\n", ".csv")
+		require.NoError(t, err)
+		require.Equal(t, []importResult{
+			{Kind: "user", Name: "carol", Status: "created"},
+		}, results)
+
+		carol, err := client.User(ctx, "carol")
+		require.NoError(t, err)
+		require.Equal(t, codersdk.LoginTypeNone, carol.LoginType)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, prepareEchoResponses([]*proto.RichParameter{
+			{Name: "region", Type: "string", Required: true},
+		}))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		results, err := runImport(t, client, `{
+			"users": [
+				{"username": "dave", "email": "not-an-email"},
+				{"username": "erin", "email": "erin@coder.com", "login_type": "password"},
+				{"username": "frank", "email": "frank@coder.com"}
+			],
+			"workspaces": [
+				{"owner": "dave", "name": "dev", "template": "`+template.Name+`"},
+				{"owner": "testuser", "name": "dev", "template": "`+template.Name+`"},
+				{"owner": "testuser", "name": "dev", "template": "missing", "parameters": {"region": "eu"}}
+			]
+		}`, ".json", "--dry-run")
+		require.ErrorContains(t, err, "6 of 6 items failed to import")
+		require.Len(t, results, 6)
+		require.Contains(t, results[0].Detail, "Invalid email address")
+		require.Contains(t, results[1].Detail, "password is required")
+		// Users default to the OIDC login type, which is not configured.
+		require.Contains(t, results[2].Detail, "OIDC must be configured")
+		require.Contains(t, results[3].Detail, `User "dave" does not exist`)
+		require.Contains(t, results[4].Detail, `Missing required parameter "region"`)
+		require.Contains(t, results[5].Detail, `Template "missing" does not exist`)
+	})
+}
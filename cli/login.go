@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/browser"
@@ -19,6 +21,7 @@ import (
 	"github.com/coder/pretty"
 
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/userpassword"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/serpent"
@@ -147,6 +150,7 @@ func (r *RootCmd) login() *serpent.Command {
 		password           string
 		trial              bool
 		useTokenForSession bool
+		device             bool
 	)
 	cmd := &serpent.Command{
 		Use:        "login [<url>]",
@@ -342,7 +346,12 @@ func (r *RootCmd) login() *serpent.Command {
 			}
 
 			sessionToken, _ := inv.ParsedFlags().GetString(varToken)
-			if sessionToken == "" {
+			if sessionToken == "" && device {
+				sessionToken, err = loginWithDevice(inv, client)
+				if err != nil {
+					return err
+				}
+			} else if sessionToken == "" {
 				authURL := *serverURL
 				// Don't use filepath.Join, we don't want to use the os separator
 				// for a url.
@@ -359,6 +368,11 @@ func (r *RootCmd) login() *serpent.Command {
 					Validate: func(token string) error {
 						client.SetSessionToken(token)
 						_, err := client.User(ctx, codersdk.Me)
+						var sdkErr *codersdk.Error
+						if errors.As(err, &sdkErr) && sdkErr.Message == httpmw.BoundAPIKeyMessage {
+							// The token is bound to the browser that created it.
+							return xerrors.New("This deployment binds tokens to the device they are issued to. Run \"coder login --device\" to sign in with a browser instead.")
+						}
 						if err != nil {
 							return xerrors.New("That's not a valid token!")
 						}
@@ -443,10 +457,63 @@ func (r *RootCmd) login() *serpent.Command {
 			Description: "By default, the CLI will generate a new session token when logging in. This flag will instead use the provided token as the session token.",
 			Value:       serpent.BoolOf(&useTokenForSession),
 		},
+		{
+			Flag:        "device",
+			Env:         "CODER_LOGIN_DEVICE",
+			Description: "Authenticate by entering a code in a browser on another device. Useful on machines that can't open a browser.",
+			Value:       serpent.BoolOf(&device),
+		},
 	}
 	return cmd
 }
 
+// loginWithDevice prints a code for the user to approve in a browser on
+// another device, and waits for the approval.
+func loginWithDevice(inv *serpent.Invocation, client *codersdk.Client) (string, error) {
+	ctx := inv.Context()
+	start, err := client.LoginDeviceCode(ctx)
+	if err != nil {
+		return "", xerrors.Errorf("start device login: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(inv.Stdout, "Open the following in a browser on any device:\n\n\t%s\n\n", start.VerificationURI)
+	_, _ = fmt.Fprintf(inv.Stdout, "Then enter the code %s, or open this link to enter it for you:\n\n\t%s\n\n",
+		pretty.Sprint(cliui.DefaultStyles.Code, start.UserCode), start.VerificationURIComplete)
+	_, _ = fmt.Fprintln(inv.Stdout, "Waiting for the login to be approved...")
+
+	errExpired := xerrors.New("the code expired before the login was approved, run the command again to get a new code")
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(start.ExpiresIn)*time.Second)
+	defer cancel()
+	ticker := time.NewTicker(time.Duration(start.Interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if xerrors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return "", errExpired
+			}
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+
+		res, err := client.LoginDeviceToken(ctx, codersdk.LoginDeviceTokenRequest{DeviceCode: start.DeviceCode})
+		var sdkErr *codersdk.Error
+		if errors.As(err, &sdkErr) {
+			switch sdkErr.Detail {
+			case codersdk.LoginDeviceAuthorizationPending:
+				continue
+			case codersdk.LoginDeviceExpiredToken:
+				return "", errExpired
+			}
+		}
+		if err != nil {
+			return "", xerrors.Errorf("exchange device code: %w", err)
+		}
+		return res.SessionToken, nil
+	}
+}
+
 // isWSL determines if coder-cli is running within Windows Subsystem for Linux
 func isWSL() (bool, error) {
 	if runtime.GOOS == goosDarwin || runtime.GOOS == goosWindows {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"runtime"
 	"testing"
 
@@ -503,6 +504,35 @@ func TestLogin(t *testing.T) {
 		<-doneChan
 	})
 
+	t.Run("Device", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		doneChan := make(chan struct{})
+		root, cfg := clitest.New(t, "login", client.URL.String(), "--device")
+		pty := ptytest.New(t).Attach(root)
+		go func() {
+			defer close(doneChan)
+			err := root.Run()
+			assert.NoError(t, err)
+		}()
+
+		pty.ExpectMatch(client.URL.String() + "/cli-auth/device")
+		userCode := regexp.MustCompile(`[A-Z]{4}-[A-Z]{4}`).FindString(pty.ExpectRegexMatch(`[A-Z]{4}-[A-Z]{4}`))
+		pty.ExpectMatch("Waiting for the login to be approved")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.AuthorizeLoginDevice(ctx, codersdk.AuthorizeLoginDeviceRequest{UserCode: userCode})
+		require.NoError(t, err)
+		pty.ExpectMatch("Welcome to Coder")
+		<-doneChan
+
+		sessionFile, err := cfg.Session().Read()
+		require.NoError(t, err)
+		require.NotEqual(t, client.SessionToken(), sessionFile)
+	})
+
 	// TokenFlag should generate a new session token and store it in the session file.
 	t.Run("TokenFlag", func(t *testing.T) {
 		t.Parallel()
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) logs() *serpent.Command {
+	var (
+		buildNumber int64
+		stage       string
+		level       string
+		search      string
+		output      string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "logs <workspace>",
+		Short: "Show the build logs of a workspace",
+		Long: "Logs of a build that is still running are streamed until the build completes.\n\n" + FormatExamples(
+			Example{
+				Description: "Show the logs of the latest build of a workspace",
+				Command:     "coder logs my-workspace",
+			},
+			Example{
+				Description: "Show errors of a single build stage as JSON",
+				Command:     `coder logs my-workspace --stage "Setting up" --level error -o json`,
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			var build codersdk.WorkspaceBuild
+			if buildNumber == 0 {
+				workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+				if err != nil {
+					return err
+				}
+				build = workspace.LatestBuild
+			} else {
+				owner, workspace, err := splitNamedWorkspace(inv.Args[0])
+				if err != nil {
+					return err
+				}
+				build, err = client.WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx, owner, workspace, strconv.FormatInt(buildNumber, 10))
+				if err != nil {
+					return err
+				}
+			}
+
+			filter := codersdk.ProvisionerJobLogsFilter{
+				Level:  codersdk.LogLevel(level),
+				Stage:  stage,
+				Search: search,
+			}
+			w := &buildLogWriter{w: inv.Stdout, json: output == "json"}
+
+			if build.Job.CompletedAt != nil {
+				logs, err := client.WorkspaceBuildLogs(ctx, build.ID, filter)
+				if err != nil {
+					return xerrors.Errorf("get build logs: %w", err)
+				}
+				for _, log := range logs {
+					if err := w.Write(log); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			logs, closer, err := client.WorkspaceBuildLogsAfterFiltered(ctx, build.ID, 0, filter)
+			if err != nil {
+				return xerrors.Errorf("follow build logs: %w", err)
+			}
+			defer closer.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case log, ok := <-logs:
+					if !ok {
+						return nil
+					}
+					if err := w.Write(log); err != nil {
+						return err
+					}
+				}
+			}
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		buildNumberOption(&buildNumber),
+		{
+			Flag:        "stage",
+			Env:         "CODER_LOGS_STAGE",
+			Description: "Only show logs of the given build stage.",
+			Value:       serpent.StringOf(&stage),
+		},
+		{
+			Flag:        "level",
+			Env:         "CODER_LOGS_LEVEL",
+			Description: "Only show logs at or above the given level.",
+			Value: serpent.EnumOf(&level,
+				string(codersdk.LogLevelTrace),
+				string(codersdk.LogLevelDebug),
+				string(codersdk.LogLevelInfo),
+				string(codersdk.LogLevelWarn),
+				string(codersdk.LogLevelError),
+			),
+		},
+		{
+			Flag:        "search",
+			Env:         "CODER_LOGS_SEARCH",
+			Description: "Only show logs whose output contains every word of the search.",
+			Value:       serpent.StringOf(&search),
+		},
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "Output format. JSON prints one log object per line.",
+			Default:       "text",
+			Value:         serpent.EnumOf(&output, "text", "json"),
+		},
+	}
+	return cmd
+}
+
+// buildLogWriter writes provisioner job logs either as text, with a header
+// whenever the stage changes, or as newline-delimited JSON.
+type buildLogWriter struct {
+	w     io.Writer
+	json  bool
+	stage string
+}
+
+func (b *buildLogWriter) Write(log codersdk.ProvisionerJobLog) error {
+	if b.json {
+		return json.NewEncoder(b.w).Encode(log)
+	}
+
+	if log.Stage != b.stage {
+		b.stage = log.Stage
+		_, _ = fmt.Fprintf(b.w, "==> %s\n", pretty.Sprint(cliui.DefaultStyles.Keyword, log.Stage))
+	}
+
+	var style pretty.Style
+	switch log.Level {
+	case codersdk.LogLevelTrace, codersdk.LogLevelDebug:
+		style = cliui.DefaultStyles.Placeholder
+	case codersdk.LogLevelError:
+		style = cliui.DefaultStyles.Error
+	case codersdk.LogLevelWarn:
+		style = cliui.DefaultStyles.Warn
+	}
+	line := strings.Join([]string{log.CreatedAt.Local().Format("2006-01-02 15:04:05.000Z07:00"), log.Output}, " ")
+	pretty.Fprintf(b.w, style, "%s\n", line)
+	return nil
+}
@@ -0,0 +1,92 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestLogs(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, dbfake.WorkspaceResponse) {
+		t.Helper()
+		client, store := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		r := dbfake.WorkspaceBuild(t, store, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        memberUser.ID,
+		}).Do()
+
+		ctx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort))
+		now := time.Now()
+		_, err := store.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+			JobID:     r.Build.JobID,
+			CreatedAt: []time.Time{now, now, now},
+			Source:    []database.LogSource{database.LogSourceProvisioner, database.LogSourceProvisioner, database.LogSourceProvisioner},
+			Level:     []database.LogLevel{database.LogLevelInfo, database.LogLevelInfo, database.LogLevelError},
+			Stage:     []string{"Planning infrastructure", "Starting workspace", "Starting workspace"},
+			Output:    []string{"planning output", "starting output", "starting failure"},
+		})
+		require.NoError(t, err)
+		return member, r
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		t.Parallel()
+		member, r := setup(t)
+
+		inv, root := clitest.New(t, "logs", r.Workspace.Name)
+		clitest.SetupConfig(t, member, root)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		err := inv.WithContext(testutil.Context(t, testutil.WaitMedium)).Run()
+		require.NoError(t, err)
+
+		got := out.String()
+		require.Contains(t, got, "==> Planning infrastructure")
+		require.Contains(t, got, "planning output")
+		require.Contains(t, got, "==> Starting workspace")
+		require.Contains(t, got, "starting failure")
+		require.Equal(t, 1, strings.Count(got, "==> Starting workspace"))
+	})
+
+	t.Run("FilterJSON", func(t *testing.T) {
+		t.Parallel()
+		member, r := setup(t)
+
+		inv, root := clitest.New(t, "logs", r.Workspace.Name,
+			"--build", fmt.Sprintf("%d", r.Build.BuildNumber),
+			"--stage", "Starting workspace",
+			"--level", "error",
+			"--output", "json",
+		)
+		clitest.SetupConfig(t, member, root)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		err := inv.WithContext(testutil.Context(t, testutil.WaitMedium)).Run()
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 1)
+		var log codersdk.ProvisionerJobLog
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &log))
+		require.Equal(t, "Starting workspace", log.Stage)
+		require.Equal(t, codersdk.LogLevelError, log.Level)
+		require.Equal(t, "starting failure", log.Output)
+	})
+}
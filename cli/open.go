@@ -342,6 +342,14 @@ func (r *RootCmd) openApp() *serpent.Command {
 				return xerrors.Errorf("region not found")
 			}
 			region = regions[preferredIdx]
+			if !region.Healthy {
+				failover, err := client.RegionFailover(ctx, region.ID, nil)
+				if err != nil {
+					return xerrors.Errorf("failed to get failover region for unhealthy region %q: %w", region.Name, err)
+				}
+				cliui.Warnf(inv.Stderr, "Region %q is unhealthy, using region %q instead.", region.Name, failover.Region.Name)
+				region = failover.Region
+			}
 
 			baseURL, err := url.Parse(region.PathAppURL)
 			if err != nil {
@@ -432,10 +432,11 @@ func applyOrgResourceActions(role *codersdk.Role, resource string, actions []str
 		role.OrganizationPermissions = make([]codersdk.Permission, 0)
 	}
 
-	// Construct new site perms with only new perms for the resource
+	// Construct new site perms with only new perms for the resource.
+	// Negative permissions are not editable interactively, so keep them.
 	keep := make([]codersdk.Permission, 0)
 	for _, perm := range role.OrganizationPermissions {
-		if string(perm.ResourceType) != resource {
+		if string(perm.ResourceType) != resource || perm.Negate {
 			keep = append(keep, perm)
 		}
 	}
@@ -459,7 +460,7 @@ func defaultActions(role *codersdk.Role, resource string) []string {
 
 	defaults := make([]string, 0)
 	for _, perm := range role.OrganizationPermissions {
-		if string(perm.ResourceType) == resource {
+		if string(perm.ResourceType) == resource && !perm.Negate {
 			defaults = append(defaults, string(perm.Action))
 		}
 	}
@@ -24,12 +24,14 @@ type workspaceParameterFlags struct {
 	richParameterDefaults []string
 
 	promptRichParameters bool
+	useParameterDefaults bool
+	parameterForm        bool
 }
 
 func (wpf *workspaceParameterFlags) allOptions() []serpent.Option {
 	options := append(wpf.cliEphemeralParameters(), wpf.cliParameters()...)
 	options = append(options, wpf.cliParameterDefaults()...)
-	return append(options, wpf.alwaysPrompt())
+	return append(options, wpf.alwaysPrompt(), wpf.useDefaults(), wpf.form())
 }
 
 func (wpf *workspaceParameterFlags) cliEphemeralParameters() []serpent.Option {
@@ -100,6 +102,24 @@ func (wpf *workspaceParameterFlags) alwaysPrompt() serpent.Option {
 	}
 }
 
+func (wpf *workspaceParameterFlags) useDefaults() serpent.Option {
+	return serpent.Option{
+		Flag:        "use-parameter-defaults",
+		Env:         "CODER_USE_PARAMETER_DEFAULTS",
+		Description: "Use the current template defaults for mutable parameters instead of the values from the previous build. Immutable parameters keep their values.",
+		Value:       serpent.BoolOf(&wpf.useParameterDefaults),
+	}
+}
+
+func (wpf *workspaceParameterFlags) form() serpent.Option {
+	return serpent.Option{
+		Flag:        "parameter-form",
+		Env:         "CODER_PARAMETER_FORM",
+		Description: "Prompt for parameters with an interactive form that shows all of them at once and validates values as they change, instead of asking for one parameter at a time.",
+		Value:       serpent.BoolOf(&wpf.parameterForm),
+	}
+}
+
 func asWorkspaceBuildParameters(nameValuePairs []string) ([]codersdk.WorkspaceBuildParameter, error) {
 	var params []codersdk.WorkspaceBuildParameter
 	for _, nameValue := range nameValuePairs {
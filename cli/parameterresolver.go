@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -33,6 +34,9 @@ type ParameterResolver struct {
 
 	promptRichParameters      bool
 	promptEphemeralParameters bool
+	useParameterDefaults      bool
+
+	parameterForm func(ctx context.Context, inputs map[string]string) (codersdk.DynamicParametersResponse, error)
 }
 
 func (pr *ParameterResolver) WithLastBuildParameters(params []codersdk.WorkspaceBuildParameter) *ParameterResolver {
@@ -80,6 +84,21 @@ func (pr *ParameterResolver) WithPromptEphemeralParameters(promptEphemeralParame
 	return pr
 }
 
+// WithUseParameterDefaults skips the values of mutable parameters from the last
+// build, so they are resolved from the defaults of the template version.
+func (pr *ParameterResolver) WithUseParameterDefaults(useParameterDefaults bool) *ParameterResolver {
+	pr.useParameterDefaults = useParameterDefaults
+	return pr
+}
+
+// WithParameterForm prompts for all parameters at once with an interactive form
+// instead of one parameter at a time. The form evaluates the parameters with
+// the given function whenever a value changes.
+func (pr *ParameterResolver) WithParameterForm(evaluate func(ctx context.Context, inputs map[string]string) (codersdk.DynamicParametersResponse, error)) *ParameterResolver {
+	pr.parameterForm = evaluate
+	return pr
+}
+
 func (pr *ParameterResolver) Resolve(inv *serpent.Invocation, action WorkspaceCLIAction, templateVersionParameters []codersdk.TemplateVersionParameter) ([]codersdk.WorkspaceBuildParameter, error) {
 	var staged []codersdk.WorkspaceBuildParameter
 	var err error
@@ -91,7 +110,12 @@ func (pr *ParameterResolver) Resolve(inv *serpent.Invocation, action WorkspaceCL
 	if err = pr.verifyConstraints(staged, action, templateVersionParameters); err != nil {
 		return nil, err
 	}
-	if staged, err = pr.resolveWithInput(staged, inv, action, templateVersionParameters); err != nil {
+	if pr.parameterForm != nil {
+		staged, err = pr.resolveWithForm(staged, inv, action, templateVersionParameters)
+	} else {
+		staged, err = pr.resolveWithInput(staged, inv, action, templateVersionParameters)
+	}
+	if err != nil {
 		return nil, err
 	}
 	return staged, nil
@@ -143,7 +167,7 @@ nextEphemeralParameter:
 }
 
 func (pr *ParameterResolver) resolveWithLastBuildParameters(resolved []codersdk.WorkspaceBuildParameter, templateVersionParameters []codersdk.TemplateVersionParameter) []codersdk.WorkspaceBuildParameter {
-	if pr.promptRichParameters {
+	if pr.promptRichParameters || pr.useParameterDefaults {
 		return resolved // don't pull parameters from last build
 	}
 
@@ -228,16 +252,7 @@ func (pr *ParameterResolver) resolveWithInput(resolved []codersdk.WorkspaceBuild
 		}
 		// PreviewParameter has not been resolved yet, so CLI needs to determine if user should input it.
 
-		firstTimeUse := pr.isFirstTimeUse(tvp.Name)
-		promptParameterOption := pr.isLastBuildParameterInvalidOption(tvp)
-
-		if (tvp.Ephemeral && pr.promptEphemeralParameters) ||
-			(action == WorkspaceCreate && tvp.Required) ||
-			(action == WorkspaceCreate && !tvp.Ephemeral) ||
-			(action == WorkspaceUpdate && promptParameterOption) ||
-			(action == WorkspaceUpdate && tvp.Mutable && tvp.Required) ||
-			(action == WorkspaceUpdate && !tvp.Mutable && firstTimeUse) ||
-			(tvp.Mutable && !tvp.Ephemeral && pr.promptRichParameters) {
+		if pr.shouldPrompt(tvp, action) {
 			parameterValue, err := cliui.RichParameter(inv, tvp, pr.richParametersDefaults)
 			if err != nil {
 				return nil, err
@@ -247,19 +262,109 @@ func (pr *ParameterResolver) resolveWithInput(resolved []codersdk.WorkspaceBuild
 				Name:  tvp.Name,
 				Value: parameterValue,
 			})
-		} else if action == WorkspaceUpdate && !tvp.Mutable && !firstTimeUse {
-			_, _ = fmt.Fprintln(inv.Stdout, pretty.Sprint(cliui.DefaultStyles.Warn, fmt.Sprintf("Parameter %q is not mutable, and cannot be customized after workspace creation.", tvp.Name)))
+		} else if action == WorkspaceUpdate && !tvp.Mutable && !pr.isFirstTimeUse(tvp.Name) {
+			warnImmutableParameter(inv, tvp)
 		}
 	}
 	return resolved, nil
 }
 
+func (pr *ParameterResolver) resolveWithForm(resolved []codersdk.WorkspaceBuildParameter, inv *serpent.Invocation, action WorkspaceCLIAction, templateVersionParameters []codersdk.TemplateVersionParameter) ([]codersdk.WorkspaceBuildParameter, error) {
+	// Parameters that are not part of the form are still inputs of the
+	// evaluation, as the options of the form may depend on them.
+	inputs := map[string]string{}
+	initial := map[string]string{}
+	for _, tvp := range templateVersionParameters {
+		if findWorkspaceBuildParameter(tvp.Name, resolved) != nil {
+			continue
+		}
+		if pr.shouldPrompt(tvp, action) {
+			if value, ok := pr.richParametersDefaults[tvp.Name]; ok {
+				initial[tvp.Name] = value
+			}
+			continue
+		}
+		if action == WorkspaceUpdate && !tvp.Mutable && !pr.isFirstTimeUse(tvp.Name) {
+			warnImmutableParameter(inv, tvp)
+		}
+		lastBuildParameter := findWorkspaceBuildParameter(tvp.Name, pr.lastBuildParameters)
+		if lastBuildParameter != nil && !tvp.Ephemeral && (!tvp.Mutable || !pr.useParameterDefaults) {
+			inputs[tvp.Name] = lastBuildParameter.Value
+		}
+	}
+	for _, r := range resolved {
+		inputs[r.Name] = r.Value
+	}
+
+	values, err := cliui.ParameterForm(inv, cliui.ParameterFormOptions{
+		Message: "Workspace parameters",
+		Values:  initial,
+		Evaluate: func(ctx context.Context, values map[string]string) (codersdk.DynamicParametersResponse, error) {
+			merged := make(map[string]string, len(inputs)+len(values))
+			for name, value := range inputs {
+				merged[name] = value
+			}
+			for name, value := range values {
+				merged[name] = value
+			}
+			res, err := pr.parameterForm(ctx, merged)
+			if err != nil {
+				return codersdk.DynamicParametersResponse{}, err
+			}
+			// Only show the parameters the user would be prompted for. The
+			// set can change with the values, so it is filtered on every
+			// evaluation.
+			parameters := make([]codersdk.PreviewParameter, 0, len(res.Parameters))
+			for _, p := range res.Parameters {
+				if _, ok := inputs[p.Name]; ok {
+					continue
+				}
+				if pr.shouldPrompt(previewTemplateVersionParameter(p), action) {
+					parameters = append(parameters, p)
+				}
+			}
+			res.Parameters = parameters
+			return res, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range values {
+		resolved = append(resolved, codersdk.WorkspaceBuildParameter{
+			Name:  name,
+			Value: value,
+		})
+	}
+	return resolved, nil
+}
+
+// shouldPrompt returns whether the user needs to input a value for a parameter
+// that has not been resolved otherwise.
+func (pr *ParameterResolver) shouldPrompt(tvp codersdk.TemplateVersionParameter, action WorkspaceCLIAction) bool {
+	firstTimeUse := pr.isFirstTimeUse(tvp.Name)
+	promptParameterOption := pr.isLastBuildParameterInvalidOption(tvp)
+
+	return (tvp.Ephemeral && pr.promptEphemeralParameters) ||
+		(action == WorkspaceCreate && tvp.Required) ||
+		(action == WorkspaceCreate && !tvp.Ephemeral) ||
+		(action == WorkspaceUpdate && promptParameterOption) ||
+		(action == WorkspaceUpdate && tvp.Mutable && tvp.Required) ||
+		(action == WorkspaceUpdate && !tvp.Mutable && firstTimeUse) ||
+		(tvp.Mutable && !tvp.Ephemeral && pr.promptRichParameters)
+}
+
+func warnImmutableParameter(inv *serpent.Invocation, tvp codersdk.TemplateVersionParameter) {
+	_, _ = fmt.Fprintln(inv.Stdout, pretty.Sprint(cliui.DefaultStyles.Warn, fmt.Sprintf("Parameter %q is not mutable, and cannot be customized after workspace creation.", tvp.Name)))
+}
+
 func (pr *ParameterResolver) isFirstTimeUse(parameterName string) bool {
 	return findWorkspaceBuildParameter(parameterName, pr.lastBuildParameters) == nil
 }
 
 func (pr *ParameterResolver) isLastBuildParameterInvalidOption(templateVersionParameter codersdk.TemplateVersionParameter) bool {
-	if len(templateVersionParameter.Options) == 0 {
+	if len(templateVersionParameter.Options) == 0 || pr.useParameterDefaults {
 		return false
 	}
 
@@ -280,6 +385,25 @@ func findTemplateVersionParameter(workspaceBuildParameter codersdk.WorkspaceBuil
 	return nil
 }
 
+// previewTemplateVersionParameter converts a dynamic parameter to a template
+// version parameter with the fields that decide whether it is prompted for.
+func previewTemplateVersionParameter(p codersdk.PreviewParameter) codersdk.TemplateVersionParameter {
+	tvp := codersdk.TemplateVersionParameter{
+		Name:      p.Name,
+		Type:      string(p.Type),
+		Mutable:   p.Mutable,
+		Required:  p.Required,
+		Ephemeral: p.Ephemeral,
+	}
+	for _, o := range p.Options {
+		tvp.Options = append(tvp.Options, codersdk.TemplateVersionParameterOption{
+			Name:  o.Name,
+			Value: o.Value.Value,
+		})
+	}
+	return tvp
+}
+
 func findWorkspaceBuildParameter(parameterName string, params []codersdk.WorkspaceBuildParameter) *codersdk.WorkspaceBuildParameter {
 	for _, p := range params {
 		if p.Name == parameterName {
@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -25,18 +27,33 @@ func (r *RootCmd) provisionerJobs() *serpent.Command {
 		Children: []*serpent.Command{
 			r.provisionerJobsCancel(),
 			r.provisionerJobsList(),
+			r.provisionerJobsPause(),
+			r.provisionerJobsReap(),
+			r.provisionerJobsResume(),
+			r.provisionerJobsShow(),
 		},
 	}
 	return cmd
 }
 
-func (r *RootCmd) provisionerJobsList() *serpent.Command {
-	type provisionerJobRow struct {
-		codersdk.ProvisionerJob `table:"provisioner_job,recursive_inline,nosort"`
-		OrganizationName        string `json:"organization_name" table:"organization"`
-		Queue                   string `json:"-" table:"queue"`
+type provisionerJobRow struct {
+	codersdk.ProvisionerJob `table:"provisioner_job,recursive_inline,nosort"`
+	OrganizationName        string `json:"organization_name" table:"organization"`
+	Queue                   string `json:"-" table:"queue"`
+}
+
+func newProvisionerJobRow(job codersdk.ProvisionerJob, org codersdk.Organization) provisionerJobRow {
+	row := provisionerJobRow{
+		ProvisionerJob:   job,
+		OrganizationName: org.HumanName(),
 	}
+	if job.Status == codersdk.ProvisionerJobPending {
+		row.Queue = fmt.Sprintf("%d/%d", job.QueuePosition, job.QueueSize)
+	}
+	return row
+}
 
+func (r *RootCmd) provisionerJobsList() *serpent.Command {
 	var (
 		client     = new(codersdk.Client)
 		orgContext = NewOrganizationContext()
@@ -44,14 +61,27 @@ func (r *RootCmd) provisionerJobsList() *serpent.Command {
 			cliui.TableFormat([]provisionerJobRow{}, []string{"created at", "id", "type", "template display name", "status", "queue", "tags"}),
 			cliui.JSONFormat(),
 		)
-		status []string
-		limit  int64
+		status        []string
+		types         []string
+		limit         int64
+		watch         bool
+		watchInterval time.Duration
 	)
 
 	cmd := &serpent.Command{
 		Use:     "list",
 		Short:   "List provisioner jobs",
 		Aliases: []string{"ls"},
+		Long: FormatExamples(
+			Example{
+				Description: "List pending workspace builds",
+				Command:     "coder provisioner jobs list --status pending --type workspace_build",
+			},
+			Example{
+				Description: "Watch the queue for new and updated jobs",
+				Command:     "coder provisioner jobs list --status pending,running --watch",
+			},
+		),
 		Middleware: serpent.Chain(
 			serpent.RequireNArgs(0),
 			r.InitClient(client),
@@ -63,44 +93,88 @@ func (r *RootCmd) provisionerJobsList() *serpent.Command {
 				return xerrors.Errorf("current organization: %w", err)
 			}
 
-			jobs, err := client.OrganizationProvisionerJobs(ctx, org.ID, &codersdk.OrganizationProvisionerJobsOptions{
-				Status: slice.StringEnums[codersdk.ProvisionerJobStatus](status),
-				Limit:  int(limit),
-			})
+			listRows := func(ctx context.Context) ([]provisionerJobRow, error) {
+				jobs, err := client.OrganizationProvisionerJobs(ctx, org.ID, &codersdk.OrganizationProvisionerJobsOptions{
+					Status: slice.StringEnums[codersdk.ProvisionerJobStatus](status),
+					Types:  slice.StringEnums[codersdk.ProvisionerJobType](types),
+					Limit:  int(limit),
+				})
+				if err != nil {
+					return nil, xerrors.Errorf("list provisioner jobs: %w", err)
+				}
+
+				rows := make([]provisionerJobRow, 0, len(jobs))
+				for _, job := range jobs {
+					rows = append(rows, newProvisionerJobRow(job, org))
+				}
+				// Sort manually because the cliui table truncates timestamps and
+				// produces an unstable sort with timestamps that are all the same.
+				slices.SortStableFunc(rows, func(a provisionerJobRow, b provisionerJobRow) int {
+					return a.CreatedAt.Compare(b.CreatedAt)
+				})
+				return rows, nil
+			}
+
+			rows, err := listRows(ctx)
 			if err != nil {
-				return xerrors.Errorf("list provisioner jobs: %w", err)
+				return err
 			}
 
-			if len(jobs) == 0 {
+			if len(rows) == 0 {
 				_, _ = fmt.Fprintln(inv.Stdout, "No provisioner jobs found")
-				return nil
+			} else {
+				out, err := formatter.Format(ctx, rows)
+				if err != nil {
+					return xerrors.Errorf("display provisioner jobs: %w", err)
+				}
+				_, _ = fmt.Fprintln(inv.Stdout, out)
 			}
 
-			var rows []provisionerJobRow
-			for _, job := range jobs {
-				row := provisionerJobRow{
-					ProvisionerJob:   job,
-					OrganizationName: org.HumanName(),
-				}
-				if job.Status == codersdk.ProvisionerJobPending {
-					row.Queue = fmt.Sprintf("%d/%d", job.QueuePosition, job.QueueSize)
-				}
-				rows = append(rows, row)
+			if !watch {
+				return nil
 			}
-			// Sort manually because the cliui table truncates timestamps and
-			// produces an unstable sort with timestamps that are all the same.
-			slices.SortStableFunc(rows, func(a provisionerJobRow, b provisionerJobRow) int {
-				return a.CreatedAt.Compare(b.CreatedAt)
-			})
 
-			out, err := formatter.Format(ctx, rows)
-			if err != nil {
-				return xerrors.Errorf("display provisioner daemons: %w", err)
+			// Tail the queue by printing jobs that are new or whose status
+			// or queue position changed since the last poll.
+			seen := make(map[uuid.UUID]provisionerJobRow, len(rows))
+			for _, row := range rows {
+				seen[row.ID] = row
 			}
+			ticker := time.NewTicker(watchInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
 
-			_, _ = fmt.Fprintln(inv.Stdout, out)
+				rows, err := listRows(ctx)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return err
+				}
 
-			return nil
+				var changed []provisionerJobRow
+				for _, row := range rows {
+					prev, ok := seen[row.ID]
+					if !ok || prev.Status != row.Status || prev.Queue != row.Queue {
+						changed = append(changed, row)
+					}
+					seen[row.ID] = row
+				}
+				if len(changed) == 0 {
+					continue
+				}
+
+				out, err := formatter.Format(ctx, changed)
+				if err != nil {
+					return xerrors.Errorf("display provisioner jobs: %w", err)
+				}
+				_, _ = fmt.Fprintln(inv.Stdout, out)
+			}
 		},
 	}
 
@@ -112,6 +186,13 @@ func (r *RootCmd) provisionerJobsList() *serpent.Command {
 			Description:   "Filter by job status.",
 			Value:         serpent.EnumArrayOf(&status, slice.ToStrings(codersdk.ProvisionerJobStatusEnums())...),
 		},
+		{
+			Flag:          "type",
+			FlagShorthand: "t",
+			Env:           "CODER_PROVISIONER_JOB_LIST_TYPE",
+			Description:   "Filter by job type.",
+			Value:         serpent.EnumArrayOf(&types, slice.ToStrings(codersdk.ProvisionerJobTypeEnums())...),
+		},
 		{
 			Flag:          "limit",
 			FlagShorthand: "l",
@@ -120,6 +201,18 @@ func (r *RootCmd) provisionerJobsList() *serpent.Command {
 			Default:       "50",
 			Value:         serpent.Int64Of(&limit),
 		},
+		{
+			Flag:          "watch",
+			FlagShorthand: "w",
+			Description:   "Keep running and print jobs as they are created or change status.",
+			Value:         serpent.BoolOf(&watch),
+		},
+		{
+			Flag:        "watch-interval",
+			Description: "How often to poll for changes when watching.",
+			Default:     "5s",
+			Value:       serpent.DurationOf(&watchInterval),
+		},
 	}...)
 
 	orgContext.AttachOptions(cmd)
@@ -128,6 +221,65 @@ func (r *RootCmd) provisionerJobsList() *serpent.Command {
 	return cmd
 }
 
+func (r *RootCmd) provisionerJobsShow() *serpent.Command {
+	var (
+		client     = new(codersdk.Client)
+		orgContext = NewOrganizationContext()
+		formatter  = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(
+				cliui.TableFormat([]provisionerJobRow{}, []string{"id", "type", "status", "created at", "started at", "completed at", "worker name", "queue", "template display name", "workspace name", "error"}),
+				func(data any) (any, error) {
+					row, ok := data.(provisionerJobRow)
+					if !ok {
+						return nil, xerrors.Errorf("expected type %T, got %T", row, data)
+					}
+					return []provisionerJobRow{row}, nil
+				},
+			),
+			cliui.JSONFormat(),
+		)
+	)
+	cmd := &serpent.Command{
+		Use:   "show <job_id>",
+		Short: "Show details of a provisioner job",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			org, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			jobID, err := uuid.Parse(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("invalid job ID: %w", err)
+			}
+
+			job, err := client.OrganizationProvisionerJob(ctx, org.ID, jobID)
+			if err != nil {
+				return xerrors.Errorf("get provisioner job: %w", err)
+			}
+
+			out, err := formatter.Format(ctx, newProvisionerJobRow(job, org))
+			if err != nil {
+				return xerrors.Errorf("display provisioner job: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+
+			return nil
+		},
+	}
+
+	orgContext.AttachOptions(cmd)
+	formatter.AttachOptions(&cmd.Options)
+
+	return cmd
+}
+
 func (r *RootCmd) provisionerJobsCancel() *serpent.Command {
 	var (
 		client     = new(codersdk.Client)
@@ -182,3 +334,164 @@ func (r *RootCmd) provisionerJobsCancel() *serpent.Command {
 
 	return cmd
 }
+
+func (r *RootCmd) provisionerJobsReap() *serpent.Command {
+	var (
+		client     = new(codersdk.Client)
+		orgContext = NewOrganizationContext()
+	)
+	cmd := &serpent.Command{
+		Use:   "reap <job_id>",
+		Short: "Mark a stuck provisioner job as failed",
+		Long: "Forcefully terminates a job that has not completed, as if it had been detected as hung. " +
+			"Use this for jobs that are stuck and can't be canceled. The provisioner running the job, if any, " +
+			"is not stopped by this command.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			org, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			jobID, err := uuid.Parse(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("invalid job ID: %w", err)
+			}
+
+			job, err := client.OrganizationProvisionerJob(ctx, org.ID, jobID)
+			if err != nil {
+				return xerrors.Errorf("get provisioner job: %w", err)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Mark %s job %s (%s) as failed?", job.Type, job.ID, job.Status),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.ReapOrganizationProvisionerJob(ctx, org.ID, job.ID)
+			if err != nil {
+				return xerrors.Errorf("reap provisioner job: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, "Job marked as failed")
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		cliui.SkipPromptOption(),
+	}
+	orgContext.AttachOptions(cmd)
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerJobsPause() *serpent.Command {
+	var (
+		client     = new(codersdk.Client)
+		orgContext = NewOrganizationContext()
+		deployment bool
+		reason     string
+	)
+	cmd := &serpent.Command{
+		Use:   "pause",
+		Short: "Pause builds for maintenance",
+		Long: "New provisioner jobs are accepted but held pending until builds are resumed. " +
+			"Held jobs are not picked up by provisioners and do not time out.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			return updateBuildPause(inv, client, orgContext, deployment, codersdk.UpdateBuildPauseRequest{
+				Paused: true,
+				Reason: reason,
+			})
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "deployment",
+			Description: "Pause builds across all organizations.",
+			Value:       serpent.BoolOf(&deployment),
+		},
+		{
+			Flag:        "reason",
+			Description: "Reason shown to users whose builds are held.",
+			Value:       serpent.StringOf(&reason),
+		},
+	}
+	orgContext.AttachOptions(cmd)
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerJobsResume() *serpent.Command {
+	var (
+		client     = new(codersdk.Client)
+		orgContext = NewOrganizationContext()
+		deployment bool
+	)
+	cmd := &serpent.Command{
+		Use:   "resume",
+		Short: "Resume paused builds",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			return updateBuildPause(inv, client, orgContext, deployment, codersdk.UpdateBuildPauseRequest{
+				Paused: false,
+			})
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "deployment",
+			Description: "Resume builds paused across all organizations.",
+			Value:       serpent.BoolOf(&deployment),
+		},
+	}
+	orgContext.AttachOptions(cmd)
+
+	return cmd
+}
+
+func updateBuildPause(inv *serpent.Invocation, client *codersdk.Client, orgContext *OrganizationContext, deployment bool, req codersdk.UpdateBuildPauseRequest) error {
+	ctx := inv.Context()
+	state := "resumed"
+	if req.Paused {
+		state = "paused"
+	}
+
+	if deployment {
+		err := client.PutDeploymentBuildPause(ctx, req)
+		if err != nil {
+			return xerrors.Errorf("update deployment build pause: %w", err)
+		}
+		_, _ = fmt.Fprintf(inv.Stdout, "Builds %s across the deployment\n", state)
+		return nil
+	}
+
+	org, err := orgContext.Selected(inv, client)
+	if err != nil {
+		return xerrors.Errorf("current organization: %w", err)
+	}
+	err = client.PutOrganizationBuildPause(ctx, org.ID, req)
+	if err != nil {
+		return xerrors.Errorf("update organization build pause: %w", err)
+	}
+	_, _ = fmt.Fprintf(inv.Stdout, "Builds %s in organization %q\n", state, org.HumanName())
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -48,6 +49,108 @@ func TestProvisionerJobs(t *testing.T) {
 	// Stop the provisioner so it doesn't grab any more jobs.
 	firstProvisioner.Close()
 
+	// preparePendingImportJob creates a template version import job that no
+	// provisioner will pick up.
+	preparePendingImportJob := func(t *testing.T) database.ProvisionerJob {
+		t.Helper()
+		tvID := uuid.New()
+		job := dbgen.ProvisionerJob(t, db, coderdAPI.Pubsub, database.ProvisionerJob{
+			InitiatorID: templateAdmin.ID,
+			Input:       json.RawMessage(`{"template_version_id":"` + tvID.String() + `"}`),
+			Type:        database.ProvisionerJobTypeTemplateVersionImport,
+			Tags:        database.StringMap{"owner": "", "scope": "organization", "foo": uuid.New().String()},
+		})
+		_ = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: owner.OrganizationID,
+			CreatedBy:      templateAdmin.ID,
+			ID:             tvID,
+			TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+			JobID:          job.ID,
+		})
+		return job
+	}
+
+	t.Run("ListType", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "provisioner", "jobs", "list", "--type", "template_version_import", "--output", "json")
+		clitest.SetupConfig(t, templateAdminClient, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		require.NoError(t, inv.Run())
+
+		var jobs []codersdk.ProvisionerJob
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &jobs))
+		require.NotEmpty(t, jobs)
+		for _, job := range jobs {
+			assert.Equal(t, codersdk.ProvisionerJobTypeTemplateVersionImport, job.Type)
+		}
+	})
+
+	t.Run("ListWatch", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		inv, root := clitest.New(t, "provisioner", "jobs", "list", "--status", "pending", "--watch", "--watch-interval", "100ms", "--column", "id,status")
+		clitest.SetupConfig(t, templateAdminClient, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv.WithContext(ctx))
+		pty.ExpectRegexMatchContext(ctx, "STATUS|No provisioner jobs found")
+
+		// Jobs created after the command started are printed as they appear.
+		job := preparePendingImportJob(t)
+		pty.ExpectMatchContext(ctx, job.ID.String())
+	})
+
+	t.Run("Show", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "provisioner", "jobs", "show", version.Job.ID.String(), "--output", "json")
+		clitest.SetupConfig(t, templateAdminClient, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		require.NoError(t, inv.Run())
+
+		var job codersdk.ProvisionerJob
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &job))
+		assert.Equal(t, version.Job.ID, job.ID)
+		assert.Equal(t, codersdk.ProvisionerJobSucceeded, job.Status)
+	})
+
+	t.Run("Reap", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("OK", func(t *testing.T) {
+			t.Parallel()
+
+			job := preparePendingImportJob(t)
+			inv, root := clitest.New(t, "provisioner", "jobs", "reap", job.ID.String(), "--yes")
+			clitest.SetupConfig(t, templateAdminClient, root)
+			var buf bytes.Buffer
+			inv.Stdout = &buf
+			require.NoError(t, inv.Run())
+			assert.Contains(t, buf.String(), "Job marked as failed")
+
+			job, err := db.GetProvisionerJobByID(testutil.Context(t, testutil.WaitShort), job.ID)
+			require.NoError(t, err)
+			assert.True(t, job.CompletedAt.Valid, "job.CompletedAt.Valid")
+			assert.Contains(t, job.Error.String, "manually terminated")
+		})
+
+		t.Run("MemberDenied", func(t *testing.T) {
+			t.Parallel()
+
+			job := preparePendingImportJob(t)
+			inv, root := clitest.New(t, "provisioner", "jobs", "reap", job.ID.String(), "--yes")
+			clitest.SetupConfig(t, memberClient, root)
+			require.Error(t, inv.Run())
+
+			job, err := db.GetProvisionerJobByID(testutil.Context(t, testutil.WaitShort), job.ID)
+			require.NoError(t, err)
+			assert.False(t, job.CompletedAt.Valid, "job.CompletedAt.Valid")
+		})
+	})
+
 	t.Run("Cancel", func(t *testing.T) {
 		t.Parallel()
 
@@ -186,3 +289,73 @@ func TestProvisionerJobs(t *testing.T) {
 		}
 	})
 }
+
+func TestProvisionerJobsPause(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		inv, root := clitest.New(t, "provisioner", "jobs", "pause", "--reason", "Database upgrade")
+		clitest.SetupConfig(t, client, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, buf.String(), "Builds paused in organization")
+
+		pause, err := client.OrganizationBuildPause(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.True(t, pause.Paused)
+		require.Equal(t, "Database upgrade", pause.Reason)
+
+		inv, root = clitest.New(t, "provisioner", "jobs", "resume")
+		clitest.SetupConfig(t, client, root)
+		buf.Reset()
+		inv.Stdout = &buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, buf.String(), "Builds resumed in organization")
+
+		pause, err = client.OrganizationBuildPause(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.False(t, pause.Paused)
+	})
+
+	t.Run("Deployment", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		inv, root := clitest.New(t, "provisioner", "jobs", "pause", "--deployment")
+		clitest.SetupConfig(t, client, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, buf.String(), "Builds paused across the deployment")
+
+		pause, err := client.DeploymentBuildPause(ctx)
+		require.NoError(t, err)
+		require.True(t, pause.Paused)
+
+		inv, root = clitest.New(t, "provisioner", "jobs", "resume", "--deployment")
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+
+		pause, err = client.DeploymentBuildPause(ctx)
+		require.NoError(t, err)
+		require.False(t, pause.Paused)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		inv, root := clitest.New(t, "provisioner", "jobs", "pause")
+		clitest.SetupConfig(t, memberClient, root)
+		err := inv.WithContext(ctx).Run()
+		require.Error(t, err)
+	})
+}
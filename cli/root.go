@@ -24,6 +24,7 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/mattn/go-isatty"
 	"github.com/mitchellh/go-wordwrap"
 	"golang.org/x/mod/semver"
@@ -89,8 +90,10 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 	// Please re-sort this list alphabetically if you change it!
 	return []*serpent.Command{
 		r.completion(),
+		r.doctor(),
 		r.dotfiles(),
 		r.externalAuth(),
+		r.importCmd(),
 		r.login(),
 		r.logout(),
 		r.netcheck(),
@@ -107,11 +110,13 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 
 		// Workspace Commands
 		r.autoupdate(),
+		r.channel(),
 		r.configSSH(),
 		r.create(),
 		r.deleteWorkspace(),
 		r.favorite(),
 		r.list(),
+		r.logs(),
 		r.open(),
 		r.ping(),
 		r.rename(),
@@ -652,6 +657,11 @@ func (r *RootCmd) configureClient(ctx context.Context, client *codersdk.Client,
 	if err != nil {
 		return xerrors.Errorf("create header transport: %w", err)
 	}
+	if headerTransport.Header.Get(codersdk.DeviceIDHeader) == "" {
+		if id := deviceID(); id != "" {
+			headerTransport.Header.Set(codersdk.DeviceIDHeader, id)
+		}
+	}
 	// The header transport has to come last.
 	// codersdk checks for the header transport to get headers
 	// to clone on the DERP client.
@@ -663,6 +673,20 @@ func (r *RootCmd) configureClient(ctx context.Context, client *codersdk.Client,
 	return nil
 }
 
+// deviceID returns the identifier of this machine, generating it on first
+// use. An empty string is returned if it can't be persisted.
+func deviceID() string {
+	file := config.DeviceIDFile()
+	if id, err := file.Read(); err == nil && id != "" {
+		return id
+	}
+	id := uuid.NewString()
+	if err := file.Write(id); err != nil {
+		return ""
+	}
+	return id
+}
+
 func (r *RootCmd) createUnauthenticatedClient(ctx context.Context, serverURL *url.URL, inv *serpent.Invocation) (*codersdk.Client, error) {
 	var client codersdk.Client
 	err := r.configureClient(ctx, &client, serverURL, inv)
@@ -74,6 +74,8 @@ import (
 	"github.com/coder/coder/v2/cli/config"
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/autobuild"
+	"github.com/coder/coder/v2/coderd/buildalerts"
+	"github.com/coder/coder/v2/coderd/buildarchive"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/awsiamrds"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -83,9 +85,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database/migrations"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/devtunnel"
+	"github.com/coder/coder/v2/coderd/driftcheck"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/joblogarchive"
 	"github.com/coder/coder/v2/coderd/jobreaper"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/oauthpki"
@@ -242,6 +246,12 @@ func enablePrometheus(
 	}
 	afterCtx(ctx, closeWorkspacesFunc)
 
+	closeWorkspaceBuildOutcomesFunc, err := prometheusmetrics.WorkspaceBuildOutcomes(ctx, options.Logger.Named("workspace_build_outcomes_metrics"), quartz.NewReal(), options.PrometheusRegistry, options.Database, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("register workspace build outcomes prometheus metric: %w", err)
+	}
+	afterCtx(ctx, closeWorkspaceBuildOutcomesFunc)
+
 	insightsMetricsCollector, err := insights.NewMetricsCollector(options.Database, options.Logger, 0, 0)
 	if err != nil {
 		return nil, xerrors.Errorf("unable to initialize insights metrics collector: %w", err)
@@ -283,7 +293,11 @@ func enablePrometheus(
 	//nolint:revive
 	return ServeHandler(
 		ctx, logger, promhttp.InstrumentMetricHandler(
-			options.PrometheusRegistry, promhttp.HandlerFor(options.PrometheusRegistry, promhttp.HandlerOpts{}),
+			options.PrometheusRegistry, promhttp.HandlerFor(options.PrometheusRegistry, promhttp.HandlerOpts{
+				// OpenMetrics is required to expose the trace exemplars of
+				// the provisioner job timings.
+				EnableOpenMetrics: true,
+			}),
 		), vals.Prometheus.Address.String(), "prometheus",
 	), nil
 }
@@ -472,6 +486,15 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				return xerrors.Errorf("configure http client: %w", err)
 			}
 
+			if vals.PathAppsOnly.Value() {
+				if vals.DisablePathApps.Value() {
+					return xerrors.New("--path-apps-only cannot be used with --disable-path-apps")
+				}
+				if vals.WildcardAccessURL.String() != "" {
+					return xerrors.New("--path-apps-only cannot be used with --wildcard-access-url")
+				}
+			}
+
 			// If the access URL is empty, we attempt to run a reverse-proxy
 			// tunnel to make the initial setup really simple.
 			var (
@@ -488,7 +511,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				tunnelDone = tunnel.Wait()
 				vals.AccessURL = serpent.URL(*tunnel.URL)
 
-				if vals.WildcardAccessURL.String() == "" {
+				if vals.WildcardAccessURL.String() == "" && !vals.PathAppsOnly.Value() {
 					// Suffixed wildcard access URL.
 					wu := fmt.Sprintf("*--%s", tunnel.URL.Hostname())
 					err = vals.WildcardAccessURL.Set(wu)
@@ -749,7 +772,15 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}
 
 				options.Database = database.New(sqlDB)
-				ps, err := pubsub.New(ctx, logger.Named("pubsub"), sqlDB, dbURL)
+				var ps interface {
+					pubsub.Pubsub
+					prometheus.Collector
+				}
+				if vals.PubsubRedisURL != "" {
+					ps, err = pubsub.NewRedis(ctx, logger.Named("pubsub"), vals.PubsubRedisURL.String())
+				} else {
+					ps, err = pubsub.New(ctx, logger.Named("pubsub"), sqlDB, dbURL)
+				}
 				if err != nil {
 					return xerrors.Errorf("create pubsub: %w", err)
 				}
@@ -764,7 +795,9 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			}
 
 			if options.DeploymentValues.Prometheus.Enable && options.DeploymentValues.Prometheus.CollectDBMetrics {
-				options.Database = dbmetrics.NewQueryMetrics(options.Database, options.Logger, options.PrometheusRegistry)
+				options.Database = dbmetrics.NewQueryMetrics(options.Database, options.Logger, options.PrometheusRegistry,
+					dbmetrics.WithSlowQueryHook(time.Second, dbmetrics.LogSlowQuery(options.Logger.Named("dbmetrics"))),
+				)
 			} else {
 				options.Database = dbmetrics.NewDBMetrics(options.Database, options.Logger, options.PrometheusRegistry)
 			}
@@ -942,6 +975,16 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			notificationReportGenerator := reports.NewReportGenerator(ctx, logger.Named("notifications.report_generator"), options.Database, options.NotificationsEnqueuer, quartz.NewReal())
 			defer notificationReportGenerator.Close()
 
+			// Evaluate build alert rules and notify on breaches.
+			buildAlerts := buildalerts.New(ctx, logger.Named("buildalerts"), options.Database, options.NotificationsEnqueuer, quartz.NewReal())
+			defer buildAlerts.Close()
+
+			// Periodically check stopped and failed workspaces for drift.
+			if vals.Provisioner.DriftCheckInterval.Value() > 0 {
+				driftCheck := driftcheck.New(ctx, logger.Named("driftcheck"), options.Database, options.Pubsub, vals.Provisioner.DriftCheckInterval.Value(), quartz.NewReal())
+				defer driftCheck.Close()
+			}
+
 			// We use a separate coderAPICloser so the Enterprise API
 			// can have its own close functions. This is cleaner
 			// than abstracting the Coder API itself.
@@ -1035,9 +1078,29 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer shutdownConns()
 
 			// Ensures that old database entries are cleaned up over time!
-			purger := dbpurge.New(ctx, logger.Named("dbpurge"), options.Database, quartz.NewReal())
+			purger := dbpurge.New(ctx, logger.Named("dbpurge"), options.Database, quartz.NewReal(),
+				dbpurge.WithDeletedWorkspaceRetention(vals.DeletedWorkspaceRetention.Value()),
+				dbpurge.WithSessionRecordingRetention(vals.SessionRecording.Retention.Value()),
+				dbpurge.WithAgentStatsRetention(vals.Retention.AgentStats.Value()),
+				dbpurge.WithWorkspaceAgentLogsRetention(vals.Retention.WorkspaceAgentLogs.Value()),
+				dbpurge.WithJobLogsRetention(vals.Retention.JobLogs.Value()),
+				dbpurge.WithAuditLogsRetention(vals.Retention.AuditLogs.Value()),
+			)
 			defer purger.Close()
 
+			// Moves logs of old provisioner jobs out of the database.
+			if retention := vals.Provisioner.JobLogRetention.Value(); retention > 0 {
+				archiver := joblogarchive.New(ctx, logger.Named("joblogarchive"), options.Database, quartz.NewReal(), retention)
+				defer archiver.Close()
+			}
+
+			// Moves provisioner state, resource metadata and logs of old
+			// workspace builds out of the database.
+			if age := vals.Provisioner.BuildArchiveAge.Value(); age > 0 {
+				buildArchiver := buildarchive.New(ctx, logger.Named("buildarchive"), options.Database, quartz.NewReal(), age)
+				defer buildArchiver.Close()
+			}
+
 			// Updates workspace usage
 			tracker := workspacestats.NewTracker(options.Database,
 				workspacestats.TrackerWithLogger(logger.Named("workspace_usage_tracker")),
@@ -1104,15 +1167,14 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				return xerrors.Errorf("notify systemd: %w", err)
 			}
 
-			autobuildTicker := time.NewTicker(vals.AutobuildPollInterval.Value())
-			defer autobuildTicker.Stop()
 			autobuildExecutor := autobuild.NewExecutor(
-				ctx, options.Database, options.Pubsub, coderAPI.FileCache, options.PrometheusRegistry, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, logger, autobuildTicker.C, options.NotificationsEnqueuer, coderAPI.Experiments)
+				ctx, options.Database, options.Pubsub, coderAPI.FileCache, options.PrometheusRegistry, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, logger, vals.AutobuildPollInterval.Value(), options.NotificationsEnqueuer, coderAPI.Experiments).
+				WithReadOnlyMode(coderAPI.ReadOnlyMode)
 			autobuildExecutor.Run()
 
-			jobReaperTicker := time.NewTicker(vals.JobReaperDetectorInterval.Value())
-			defer jobReaperTicker.Stop()
-			jobReaper := jobreaper.New(ctx, options.Database, options.Pubsub, logger, jobReaperTicker.C)
+			jobReaper := jobreaper.New(ctx, options.Database, options.Pubsub, logger, vals.JobReaperDetectorInterval.Value()).
+				WithCancelDeadline(vals.Provisioner.CancelDeadline.Value()).
+				WithReadOnlyMode(coderAPI.ReadOnlyMode)
 			jobReaper.Start()
 			defer jobReaper.Close()
 
@@ -1299,6 +1361,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 	createAdminUserCmd := r.newCreateAdminUserCommand()
 	regenerateVapidKeypairCmd := r.newRegenerateVapidKeypairCommand()
+	dbCheckCmd := r.newDBCheckCommand()
 
 	rawURLOpt := serpent.Option{
 		Flag: "raw-url",
@@ -1312,7 +1375,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 	serverCmd.Children = append(
 		serverCmd.Children,
-		createAdminUserCmd, postgresBuiltinURLCmd, postgresBuiltinServeCmd, regenerateVapidKeypairCmd,
+		createAdminUserCmd, dbCheckCmd, postgresBuiltinURLCmd, postgresBuiltinServeCmd, regenerateVapidKeypairCmd,
 	)
 
 	return serverCmd
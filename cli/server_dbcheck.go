@@ -0,0 +1,240 @@
+//go:build !slim
+
+package cli
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/database/awsiamrds"
+	"github.com/coder/coder/v2/coderd/database/dbcheck"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+type dbCheckRow struct {
+	Check   string `table:"check,nosort"`
+	Status  string `table:"status"`
+	Message string `table:"message"`
+}
+
+type dbCheckTransactionRow struct {
+	PID      int64  `table:"pid,nosort"`
+	Username string `table:"username"`
+	State    string `table:"state"`
+	Duration string `table:"duration"`
+	Query    string `table:"query"`
+}
+
+type dbCheckTableRow struct {
+	Table      string `table:"table,nosort"`
+	Rows       string `table:"rows"`
+	Size       string `table:"size"`
+	Operations int    `table:"operations"`
+	Duration   string `table:"estimated duration"`
+}
+
+func (r *RootCmd) newDBCheckCommand() *serpent.Command {
+	var (
+		postgresURL              string
+		postgresAuth             string
+		longTransactionThreshold time.Duration
+		rowsPerSecond            int64
+		formatter                = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+				report, ok := data.(dbcheck.Report)
+				if !ok {
+					// This should never happen
+					return "", xerrors.Errorf("expected dbcheck.Report, got %T", data)
+				}
+				return formatDBCheckReport(report)
+			}),
+			cliui.JSONFormat(),
+		)
+	)
+	cmd := &serpent.Command{
+		Use:   "dbcheck",
+		Short: "Check that a database is ready to be upgraded, and estimate how long the pending migrations take.",
+		Long: "The database is only read from, so it is safe to run this command against a database that is in use. " +
+			"The command exits with an error if any check fails. Durations are estimates based on table statistics, " +
+			"the actual duration depends on the hardware and load of the database.",
+		Handler: func(inv *serpent.Invocation) error {
+			var (
+				ctx, cancel = inv.SignalNotifyContext(inv.Context(), StopSignals...)
+				cfg         = r.createConfig()
+				logger      = inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr))
+			)
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+
+			defer cancel()
+
+			if postgresURL == "" {
+				cliui.Infof(inv.Stderr, "Using built-in PostgreSQL (%s)", cfg.PostgresPath())
+				url, closePg, err := startBuiltinPostgres(ctx, cfg, logger, "")
+				if err != nil {
+					return err
+				}
+				defer func() {
+					_ = closePg()
+				}()
+				postgresURL = url
+			}
+
+			sqlDriver := "postgres"
+			var err error
+			if codersdk.PostgresAuth(postgresAuth) == codersdk.PostgresAuthAWSIAMRDS {
+				sqlDriver, err = awsiamrds.Register(inv.Context(), sqlDriver)
+				if err != nil {
+					return xerrors.Errorf("register aws rds iam auth: %w", err)
+				}
+			}
+
+			// ConnectToPostgres is not used, since it rejects unsupported
+			// versions and dirty databases, which are reported as checks.
+			sqlDB, err := sql.Open(sqlDriver, postgresURL)
+			if err != nil {
+				return xerrors.Errorf("open postgres: %w", err)
+			}
+			defer func() {
+				_ = sqlDB.Close()
+			}()
+			if err := pingPostgres(ctx, sqlDB); err != nil {
+				return xerrors.Errorf("ping postgres: %w", err)
+			}
+
+			report, err := dbcheck.Run(ctx, sqlDB, dbcheck.Options{
+				LongTransactionThreshold: longTransactionThreshold,
+				RowsPerSecond:            rowsPerSecond,
+			})
+			if err != nil {
+				return xerrors.Errorf("check database: %w", err)
+			}
+
+			out, err := formatter.Format(ctx, report)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+
+			if report.Failed() {
+				return xerrors.New("the database is not ready to be upgraded, see the failed checks above")
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Env:         "CODER_PG_CONNECTION_URL",
+			Flag:        "postgres-url",
+			Description: "URL of a PostgreSQL database. If empty, the built-in PostgreSQL deployment will be used (Coder must not be already running in this case).",
+			Value:       serpent.StringOf(&postgresURL),
+		},
+		{
+			Name:        "Postgres Connection Auth",
+			Description: "Type of auth to use when connecting to postgres.",
+			Flag:        "postgres-connection-auth",
+			Env:         "CODER_PG_CONNECTION_AUTH",
+			Default:     "password",
+			Value:       serpent.EnumOf(&postgresAuth, codersdk.PostgresAuthDrivers...),
+		},
+		{
+			Flag:        "long-transaction-threshold",
+			Description: "Transactions that have been open for longer than this are reported, since migrations wait for the locks they hold.",
+			Default:     "5m",
+			Value:       serpent.DurationOf(&longTransactionThreshold),
+		},
+		{
+			Flag:        "rows-per-second",
+			Description: "The assumed number of rows per second a migration statement processes, used to estimate the migration duration.",
+			Default:     "50000",
+			Value:       serpent.Int64Of(&rowsPerSecond),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+
+	return cmd
+}
+
+func formatDBCheckReport(report dbcheck.Report) (string, error) {
+	var sb strings.Builder
+
+	checks := make([]dbCheckRow, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		status := string(check.Status)
+		switch check.Status {
+		case dbcheck.StatusWarning:
+			status = pretty.Sprint(cliui.DefaultStyles.Warn, status)
+		case dbcheck.StatusFailed:
+			status = pretty.Sprint(cliui.DefaultStyles.Error, status)
+		}
+		checks = append(checks, dbCheckRow{
+			Check:   check.Name,
+			Status:  status,
+			Message: check.Message,
+		})
+	}
+	out, err := cliui.DisplayTable(checks, "", nil)
+	if err != nil {
+		return "", xerrors.Errorf("display checks: %w", err)
+	}
+	_, _ = fmt.Fprintln(&sb, out)
+
+	if len(report.LongTransactions) > 0 {
+		transactions := make([]dbCheckTransactionRow, 0, len(report.LongTransactions))
+		for _, tx := range report.LongTransactions {
+			transactions = append(transactions, dbCheckTransactionRow{
+				PID:      tx.PID,
+				Username: tx.Username,
+				State:    tx.State,
+				Duration: tx.Duration.Round(time.Second).String(),
+				Query:    tx.Query,
+			})
+		}
+		out, err := cliui.DisplayTable(transactions, "", nil)
+		if err != nil {
+			return "", xerrors.Errorf("display transactions: %w", err)
+		}
+		_, _ = fmt.Fprintf(&sb, "\n%s\n", out)
+	}
+
+	_, _ = fmt.Fprintf(&sb, "\nCurrent migration version: %d\n", report.CurrentVersion)
+	if len(report.PendingMigrations) == 0 {
+		_, _ = fmt.Fprint(&sb, "There are no pending migrations.")
+		return sb.String(), nil
+	}
+	_, _ = fmt.Fprintf(&sb, "Pending migrations: %d (up to version %d)\n",
+		len(report.PendingMigrations), report.PendingMigrations[len(report.PendingMigrations)-1])
+
+	if len(report.Tables) > 0 {
+		tables := make([]dbCheckTableRow, 0, len(report.Tables))
+		for _, table := range report.Tables {
+			tables = append(tables, dbCheckTableRow{
+				Table:      table.Table,
+				Rows:       humanize.Comma(table.Rows),
+				Size:       humanize.IBytes(uint64(max(table.Size, 0))), // #nosec G115 - Checked to be non-negative.
+				Operations: table.Operations,
+				Duration:   table.Duration.Round(time.Second).String(),
+			})
+		}
+		out, err := cliui.DisplayTable(tables, "", nil)
+		if err != nil {
+			return "", xerrors.Errorf("display tables: %w", err)
+		}
+		_, _ = fmt.Fprintf(&sb, "\n%s\n\n", out)
+	}
+	_, _ = fmt.Fprintf(&sb, "Estimated migration duration: %s", report.EstimatedDuration.Round(time.Second))
+	return sb.String(), nil
+}
@@ -0,0 +1,57 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/database/dbcheck"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestServerDBCheck(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("this test is only supported on postgres")
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		connectionURL, err := dbtestutil.Open(t)
+		require.NoError(t, err)
+
+		inv, _ := clitest.New(t, "server", "dbcheck", "--postgres-url", connectionURL)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "postgres_version")
+		require.Contains(t, stdout.String(), "There are no pending migrations.")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		connectionURL, err := dbtestutil.Open(t)
+		require.NoError(t, err)
+
+		inv, _ := clitest.New(t, "server", "dbcheck", "--postgres-url", connectionURL, "--output", "json")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var report dbcheck.Report
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+		require.False(t, report.Failed())
+		require.NotEmpty(t, report.Checks)
+		require.Empty(t, report.PendingMigrations)
+	})
+}
@@ -1074,6 +1074,40 @@ func TestServer(t *testing.T) {
 		require.ErrorContains(t, err, "must not be empty")
 	})
 
+	t.Run("PathAppsOnlyWithWildcard", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		inv, _ := clitest.New(t,
+			"server",
+			dbArg(t),
+			"--http-address", ":0",
+			"--access-url", "http://example.com",
+			"--wildcard-access-url", "*.example.com",
+			"--path-apps-only",
+		)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "--path-apps-only cannot be used with --wildcard-access-url")
+	})
+
+	t.Run("PathAppsOnlyWithDisablePathApps", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		inv, _ := clitest.New(t,
+			"server",
+			dbArg(t),
+			"--http-address", ":0",
+			"--access-url", "http://example.com",
+			"--disable-path-apps",
+			"--path-apps-only",
+		)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "--path-apps-only cannot be used with --disable-path-apps")
+	})
+
 	// DeprecatedAddress is a test for the deprecated --address flag. If
 	// specified, --http-address and --tls-address are both ignored, a warning
 	// is printed, and the server will either be HTTP-only or TLS-only depending
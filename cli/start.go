@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/cliui"
@@ -118,7 +120,11 @@ func buildWorkspaceStartRequest(inv *serpent.Invocation, client *codersdk.Client
 	version := workspace.LatestBuild.TemplateVersionID
 
 	if workspace.AutomaticUpdates == codersdk.AutomaticUpdatesAlways || action == WorkspaceUpdate {
-		version = workspace.TemplateActiveVersionID
+		var err error
+		version, err = workspaceUpdateVersion(inv.Context(), client, workspace)
+		if err != nil {
+			return codersdk.CreateWorkspaceBuildRequest{}, err
+		}
 		if version != workspace.LatestBuild.TemplateVersionID {
 			action = WorkspaceUpdate
 		}
@@ -156,15 +162,20 @@ func buildWorkspaceStartRequest(inv *serpent.Invocation, client *codersdk.Client
 		RichParameters:            cliRichParameters,
 		RichParameterFile:         parameterFlags.richParameterFile,
 		RichParameterDefaults:     cliRichParameterDefaults,
+		UseParameterDefaults:      parameterFlags.useParameterDefaults,
+
+		ParameterForm: parameterFlags.parameterForm,
+		OwnerID:       workspace.OwnerID,
 	})
 	if err != nil {
 		return codersdk.CreateWorkspaceBuildRequest{}, err
 	}
 
 	wbr := codersdk.CreateWorkspaceBuildRequest{
-		Transition:          codersdk.WorkspaceTransitionStart,
-		RichParameterValues: buildParameters,
-		TemplateVersionID:   version,
+		Transition:           codersdk.WorkspaceTransitionStart,
+		RichParameterValues:  buildParameters,
+		TemplateVersionID:    version,
+		UseParameterDefaults: parameterFlags.useParameterDefaults,
 	}
 	if buildFlags.provisionerLogDebug {
 		wbr.LogLevel = codersdk.ProvisionerLogLevelDebug
@@ -196,3 +207,22 @@ func startWorkspace(inv *serpent.Invocation, client *codersdk.Client, workspace
 
 	return build, nil
 }
+
+// workspaceUpdateVersion returns the template version a workspace updates to.
+// That is the version published to the release channel the workspace tracks,
+// if any, and the active version of its template otherwise.
+func workspaceUpdateVersion(ctx context.Context, client *codersdk.Client, workspace codersdk.Workspace) (uuid.UUID, error) {
+	channel, err := client.WorkspaceReleaseChannel(ctx, workspace.ID)
+	if err != nil {
+		// Older deployments don't support release channels.
+		var sdkErr *codersdk.Error
+		if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound {
+			return workspace.TemplateActiveVersionID, nil
+		}
+		return uuid.Nil, xerrors.Errorf("get workspace release channel: %w", err)
+	}
+	if channel.TemplateVersionID != nil {
+		return *channel.TemplateVersionID, nil
+	}
+	return workspace.TemplateActiveVersionID, nil
+}
@@ -1,30 +1,76 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"golang.org/x/xerrors"
+
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/cli/cliutil"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
 	"github.com/coder/serpent"
 )
 
 func (r *RootCmd) stop() *serpent.Command {
-	var bflags buildFlags
+	var (
+		bflags buildFlags
+		filter string
+		dryRun bool
+	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
 		Annotations: workspaceCommand,
-		Use:         "stop <workspace>",
+		Use:         "stop [workspace]",
 		Short:       "Stop a workspace",
+		Long: FormatExamples(
+			Example{
+				Description: "Stop a workspace",
+				Command:     "coder stop my-workspace",
+			},
+			Example{
+				Description: "List the workspaces of a template that have not been used for 30 days",
+				Command:     "coder stop --filter 'template:foo last_used_before:30d' --dry-run",
+			},
+			Example{
+				Description: "Stop the same workspaces",
+				Command:     "coder stop --filter 'template:foo last_used_before:30d' --yes",
+			},
+		),
 		Middleware: serpent.Chain(
-			serpent.RequireNArgs(1),
+			serpent.RequireRangeArgs(0, 1),
 			r.InitClient(client),
 		),
 		Options: serpent.OptionSet{
 			cliui.SkipPromptOption(),
+			{
+				Flag:        "filter",
+				Env:         "CODER_STOP_FILTER",
+				Description: "Stop all running workspaces matching the search query instead of a single workspace, e.g. \"template:foo last_used_before:30d\".",
+				Value:       serpent.StringOf(&filter),
+			},
+			{
+				Flag:        "dry-run",
+				Description: "List the workspaces that match --filter without stopping them.",
+				Value:       serpent.BoolOf(&dryRun),
+			},
 		},
 		Handler: func(inv *serpent.Invocation) error {
+			if filter != "" {
+				if len(inv.Args) > 0 {
+					return xerrors.New("a workspace cannot be specified together with --filter")
+				}
+				return stopWorkspaces(inv, client, filter, dryRun, bflags)
+			}
+			if len(inv.Args) == 0 {
+				return xerrors.New("a workspace or --filter must be specified")
+			}
+			if dryRun {
+				return xerrors.New("--dry-run can only be used together with --filter")
+			}
+
 			_, err := cliui.Prompt(inv, cliui.PromptOptions{
 				Text:      "Confirm stop workspace?",
 				IsConfirm: true,
@@ -62,6 +108,86 @@ func (r *RootCmd) stop() *serpent.Command {
 	return cmd
 }
 
+type bulkStopRow struct {
+	Workspace string `table:"workspace,default_sort"`
+	Template  string `table:"template"`
+	Status    string `table:"status"`
+	LastUsed  string `table:"last used"`
+}
+
+// stopWorkspaces stops every running workspace matching the search query.
+// Stop builds are only queued, so a failure of one workspace does not hold up
+// the others.
+func stopWorkspaces(inv *serpent.Invocation, client *codersdk.Client, filter string, dryRun bool, bflags buildFlags) error {
+	ctx := inv.Context()
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: filter})
+	if err != nil {
+		return xerrors.Errorf("query workspaces: %w", err)
+	}
+
+	var (
+		now     = time.Now()
+		targets []codersdk.Workspace
+		rows    []bulkStopRow
+	)
+	for _, workspace := range res.Workspaces {
+		if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStart {
+			continue
+		}
+		targets = append(targets, workspace)
+		rows = append(rows, bulkStopRow{
+			Workspace: workspace.OwnerName + "/" + workspace.Name,
+			Template:  workspace.TemplateName,
+			Status:    codersdk.WorkspaceDisplayStatus(workspace.LatestBuild.Job.Status, workspace.LatestBuild.Transition),
+			LastUsed:  durationDisplay(now.Sub(workspace.LastUsedAt).Truncate(time.Second)) + " ago",
+		})
+	}
+	if len(targets) == 0 {
+		_, _ = fmt.Fprintln(inv.Stderr, "No running workspaces match the filter.")
+		return nil
+	}
+
+	table, err := cliui.DisplayTable(rows, "workspace", nil)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(inv.Stdout, table)
+	if dryRun {
+		_, _ = fmt.Fprintf(inv.Stdout, "\n%d workspace(s) would be stopped.\n", len(targets))
+		return nil
+	}
+
+	_, err = cliui.Prompt(inv, cliui.PromptOptions{
+		Text:      fmt.Sprintf("Stop %d workspace(s)?", len(targets)),
+		IsConfirm: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, workspace := range targets {
+		name := workspace.OwnerName + "/" + workspace.Name
+		wbr := codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		}
+		if bflags.provisionerLogDebug {
+			wbr.LogLevel = codersdk.ProvisionerLogLevelDebug
+		}
+		_, err := client.CreateWorkspaceBuild(ctx, workspace.ID, wbr)
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("stop %s: %w", name, err))
+			_, _ = fmt.Fprintf(inv.Stdout, "[%d/%d] %s: %s\n", i+1, len(targets), cliui.Keyword(name), pretty.Sprint(cliui.DefaultStyles.Error, "failed"))
+			continue
+		}
+		_, _ = fmt.Fprintf(inv.Stdout, "[%d/%d] %s: stop queued\n", i+1, len(targets), cliui.Keyword(name))
+	}
+
+	_, _ = fmt.Fprintf(inv.Stdout, "\nQueued %d of %d stop build(s). Follow a build with %s.\n",
+		len(targets)-len(errs), len(targets), pretty.Sprint(cliui.DefaultStyles.Code, "coder logs <workspace>"))
+	return errors.Join(errs...)
+}
+
 func stopWorkspace(inv *serpent.Invocation, client *codersdk.Client, workspace codersdk.Workspace, bflags buildFlags) (codersdk.WorkspaceBuild, error) {
 	if workspace.LatestBuild.Job.Status == codersdk.ProvisionerJobPending {
 		// cliutil.WarnMatchedProvisioners also checks if the job is pending
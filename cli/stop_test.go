@@ -0,0 +1,100 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestStopFilter(t *testing.T) {
+	t.Parallel()
+
+	// setup creates a stale and a recently used workspace of one template,
+	// and a stale workspace of another template.
+	setup := func(t *testing.T) (member *codersdk.Client, stale, recent, other dbfake.WorkspaceResponse) {
+		t.Helper()
+		client, store := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		stale = dbfake.WorkspaceBuild(t, store, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        memberUser.ID,
+			LastUsedAt:     time.Now().Add(-60 * 24 * time.Hour),
+		}).Do()
+		recent = dbfake.WorkspaceBuild(t, store, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        memberUser.ID,
+			TemplateID:     stale.Template.ID,
+			LastUsedAt:     time.Now(),
+		}).Do()
+		other = dbfake.WorkspaceBuild(t, store, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        memberUser.ID,
+			LastUsedAt:     time.Now().Add(-60 * 24 * time.Hour),
+		}).Do()
+		return member, stale, recent, other
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+		member, stale, recent, other := setup(t)
+
+		inv, root := clitest.New(t, "stop", "--filter", "template:"+stale.Template.Name+" last_used_before:30d", "--dry-run")
+		clitest.SetupConfig(t, member, root)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		err := inv.WithContext(testutil.Context(t, testutil.WaitMedium)).Run()
+		require.NoError(t, err)
+
+		require.Contains(t, out.String(), stale.Workspace.Name)
+		require.NotContains(t, out.String(), recent.Workspace.Name)
+		require.NotContains(t, out.String(), other.Workspace.Name)
+		require.Contains(t, out.String(), "1 workspace(s) would be stopped.")
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		workspace, err := member.Workspace(ctx, stale.Workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		t.Parallel()
+		member, stale, recent, _ := setup(t)
+
+		inv, root := clitest.New(t, "stop", "--filter", "template:"+stale.Template.Name, "--yes")
+		clitest.SetupConfig(t, member, root)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		err := inv.WithContext(testutil.Context(t, testutil.WaitMedium)).Run()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "[2/2]")
+		require.Contains(t, out.String(), "Queued 2 of 2 stop build(s).")
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		for _, id := range []uuid.UUID{stale.Workspace.ID, recent.Workspace.ID} {
+			workspace, err := member.Workspace(ctx, id)
+			require.NoError(t, err)
+			require.Equal(t, codersdk.WorkspaceTransitionStop, workspace.LatestBuild.Transition)
+		}
+	})
+
+	t.Run("WorkspaceAndFilter", func(t *testing.T) {
+		t.Parallel()
+		member, stale, _, _ := setup(t)
+
+		inv, root := clitest.New(t, "stop", stale.Workspace.Name, "--filter", "template:"+stale.Template.Name)
+		clitest.SetupConfig(t, member, root)
+		err := inv.WithContext(testutil.Context(t, testutil.WaitMedium)).Run()
+		require.ErrorContains(t, err, "cannot be specified together with --filter")
+	})
+}
@@ -244,6 +244,14 @@ func summarizeBundle(inv *serpent.Invocation, bun *support.Bundle) {
 		cliui.Warn(inv.Stdout, "Deployment health issues detected:", deployHealthSummary...)
 	}
 
+	if jr := bun.Provisioners.JobReaper; jr != nil && len(jr.HungJobCandidates) > 0 {
+		cliui.Warn(inv.Stdout, fmt.Sprintf("%d provisioner job(s) are hung or pending for too long and will be terminated by the job reaper.", len(jr.HungJobCandidates)))
+	}
+
+	if fb := bun.Workspace.FailedBuild; fb != nil {
+		cliui.Warn(inv.Stdout, fmt.Sprintf("Workspace build #%d failed: %s", fb.BuildNumber, fb.Job.Error), "See workspace/failed_build_logs.txt for the last lines of the build logs.")
+	}
+
 	if bun.Network.Netcheck == nil {
 		cliui.Error(inv.Stdout, "No network troubleshooting information available!")
 		return
@@ -285,6 +293,9 @@ func writeBundle(src *support.Bundle, dest *zip.Writer) error {
 		"network/connection_info.json":    src.Network.ConnectionInfo,
 		"network/netcheck.json":           src.Network.Netcheck,
 		"network/interfaces.json":         src.Network.Interfaces,
+		"provisioners/daemons.json":       src.Provisioners.Daemons,
+		"provisioners/job_reaper.json":    src.Provisioners.JobReaper,
+		"workspace/failed_build.json":     src.Workspace.FailedBuild,
 		"workspace/template.json":         src.Workspace.Template,
 		"workspace/template_version.json": src.Workspace.TemplateVersion,
 		"workspace/parameters.json":       src.Workspace.Parameters,
@@ -313,18 +324,19 @@ func writeBundle(src *support.Bundle, dest *zip.Writer) error {
 
 	// The below we just write as we have them:
 	for k, v := range map[string]string{
-		"agent/logs.txt":                 string(src.Agent.Logs),
-		"agent/agent_magicsock.html":     string(src.Agent.AgentMagicsockHTML),
-		"agent/client_magicsock.html":    string(src.Agent.ClientMagicsockHTML),
-		"agent/startup_logs.txt":         humanizeAgentLogs(src.Agent.StartupLogs),
-		"agent/prometheus.txt":           string(src.Agent.Prometheus),
-		"cli_logs.txt":                   string(src.CLILogs),
-		"logs.txt":                       strings.Join(src.Logs, "\n"),
-		"network/coordinator_debug.html": src.Network.CoordinatorDebug,
-		"network/tailnet_debug.html":     src.Network.TailnetDebug,
-		"workspace/build_logs.txt":       humanizeBuildLogs(src.Workspace.BuildLogs),
-		"workspace/template_file.zip":    string(templateVersionBytes),
-		"license-status.txt":             licenseStatus,
+		"agent/logs.txt":                  string(src.Agent.Logs),
+		"agent/agent_magicsock.html":      string(src.Agent.AgentMagicsockHTML),
+		"agent/client_magicsock.html":     string(src.Agent.ClientMagicsockHTML),
+		"agent/startup_logs.txt":          humanizeAgentLogs(src.Agent.StartupLogs),
+		"agent/prometheus.txt":            string(src.Agent.Prometheus),
+		"cli_logs.txt":                    string(src.CLILogs),
+		"logs.txt":                        strings.Join(src.Logs, "\n"),
+		"network/coordinator_debug.html":  src.Network.CoordinatorDebug,
+		"network/tailnet_debug.html":      src.Network.TailnetDebug,
+		"workspace/build_logs.txt":        humanizeBuildLogs(src.Workspace.BuildLogs),
+		"workspace/failed_build_logs.txt": humanizeBuildLogs(src.Workspace.FailedBuildLogs),
+		"workspace/template_file.zip":     string(templateVersionBytes),
+		"license-status.txt":              licenseStatus,
 	} {
 		f, err := dest.Create(k)
 		if err != nil {
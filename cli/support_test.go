@@ -85,6 +85,7 @@ func TestSupportBundle(t *testing.T) {
 			Level:     []database.LogLevel{database.LogLevelInfo},
 			Stage:     []string{"provision"},
 			Output:    []string{"done"},
+			Fields:    []string{"{}"},
 		})
 		require.NoError(t, err)
 		// Insert an agent log
@@ -274,6 +275,21 @@ func assertBundleContents(t *testing.T, path string, wantWorkspace bool, wantAge
 				continue
 			}
 			require.Contains(t, string(bs), "provision done")
+		case "workspace/failed_build.json":
+			var v *codersdk.WorkspaceBuild
+			decodeJSONFromZip(t, f, &v)
+			require.Nil(t, v, "expected no failed workspace build")
+		case "workspace/failed_build_logs.txt":
+			bs := readBytesFromZip(t, f)
+			require.Empty(t, bs, "expected failed build logs to be empty")
+		case "provisioners/daemons.json":
+			var v []codersdk.ProvisionerDaemon
+			decodeJSONFromZip(t, f, &v)
+			require.NotNil(t, v, "provisioner daemons should not be nil")
+		case "provisioners/job_reaper.json":
+			var v healthsdk.JobReaperReport
+			decodeJSONFromZip(t, f, &v)
+			require.NotNil(t, v.ReapedJobs, "job reaper reaped jobs should not be nil")
 		case "workspace/template.json":
 			var v codersdk.Template
 			decodeJSONFromZip(t, f, &v)
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) templatePromote() *serpent.Command {
+	var (
+		templateName        string
+		templateVersionName string
+		channel             string
+		orgContext          = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "promote --template=<template_name> --template-version=<template_version_name> --channel=<stable|beta>",
+		Short: "Publish a template version to a release channel.",
+		Long: "Workspaces that track a release channel update to the version published to it instead of the active version of the template.\n\n" + FormatExamples(
+			Example{
+				Description: "Publish a version to the beta channel",
+				Command:     "coder templates promote -t docker --template-version v2 --channel beta",
+			},
+			Example{
+				Description: "Make a workspace track the beta channel",
+				Command:     "coder channel my-workspace beta",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+
+			template, err := client.TemplateByName(inv.Context(), organization.ID, templateName)
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+
+			version, err := client.TemplateVersionByName(inv.Context(), template.ID, templateVersionName)
+			if err != nil {
+				return xerrors.Errorf("get template version by name: %w", err)
+			}
+
+			_, err = client.PromoteTemplateReleaseChannel(inv.Context(), template.ID, codersdk.ReleaseChannel(channel), codersdk.PromoteTemplateReleaseChannelRequest{
+				TemplateVersionID: version.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("promote template version: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Published version %s of template %s to the %s channel\n",
+				cliui.Keyword(version.Name), cliui.Keyword(template.Name), cliui.Keyword(channel))
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "template",
+			FlagShorthand: "t",
+			Env:           "CODER_TEMPLATE_NAME",
+			Description:   "Specify the template name.",
+			Required:      true,
+			Value:         serpent.StringOf(&templateName),
+		},
+		{
+			Flag:        "template-version",
+			Description: "Specify the template version name to publish.",
+			Env:         "CODER_TEMPLATE_VERSION_NAME",
+			Required:    true,
+			Value:       serpent.StringOf(&templateVersionName),
+		},
+		{
+			Flag:        "channel",
+			Description: "Specify the release channel to publish the version to.",
+			Env:         "CODER_TEMPLATE_CHANNEL",
+			Required:    true,
+			Value:       serpent.EnumOf(&channel, string(codersdk.ReleaseChannelStable), string(codersdk.ReleaseChannelBeta)),
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
@@ -0,0 +1,76 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplatePromote(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PromoteAndUpdate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		beta := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+			req.Name = "beta"
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, beta.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		inv, root := clitest.New(t, "templates", "promote", "--template", template.Name, "--template-version", beta.Name, "--channel", "beta")
+		//nolint:gocritic // Publishing template versions requires template admin permissions.
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+		pty.ExpectMatch("to the beta channel")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		channels, err := client.TemplateReleaseChannels(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, channels, 1)
+		require.Equal(t, codersdk.ReleaseChannelBeta, channels[0].Channel)
+		require.Equal(t, beta.ID, channels[0].TemplateVersionID)
+
+		inv, root = clitest.New(t, "channel", workspace.Name, "beta")
+		clitest.SetupConfig(t, member, root)
+		pty = ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+		pty.ExpectMatch(`tracks the "beta" channel`)
+
+		// The workspace runs the active version, but tracks a channel that a
+		// newer version is published to.
+		inv, root = clitest.New(t, "update", workspace.Name)
+		clitest.SetupConfig(t, member, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		workspace, err = member.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, beta.ID, workspace.LatestBuild.TemplateVersionID)
+	})
+
+	t.Run("InvalidChannel", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "channel", "my-workspace", "nightly")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(testutil.Context(t, testutil.WaitShort)).Run()
+		require.ErrorContains(t, err, `invalid channel "nightly"`)
+	})
+}
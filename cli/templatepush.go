@@ -35,6 +35,7 @@ func (r *RootCmd) templatePush() *serpent.Command {
 		provisionerTags      []string
 		uploadFlags          templateUploadFlags
 		activate             bool
+		ociReference         string
 		orgContext           = NewOrganizationContext()
 	)
 	client := new(codersdk.Client)
@@ -53,6 +54,9 @@ func (r *RootCmd) templatePush() *serpent.Command {
 				return err
 			}
 
+			if ociReference != "" && len(inv.Args) == 0 {
+				return xerrors.New("a template name must be specified when pushing from an OCI artifact")
+			}
 			name, err := uploadFlags.templateName(inv)
 			if err != nil {
 				return err
@@ -121,15 +125,20 @@ func (r *RootCmd) templatePush() *serpent.Command {
 				cliui.Info(inv.Stderr, "Provisioner tags: "+cliui.Code(tagStr))
 			}
 
-			err = uploadFlags.checkForLockfile(inv)
-			if err != nil {
-				return xerrors.Errorf("check for lockfile: %w", err)
+			if ociReference == "" {
+				err = uploadFlags.checkForLockfile(inv)
+				if err != nil {
+					return xerrors.Errorf("check for lockfile: %w", err)
+				}
 			}
 
 			message := uploadFlags.templateMessage(inv)
+			if ociReference != "" && uploadFlags.message == "" {
+				message = "Pulled from " + ociReference
+			}
 
 			var varsFiles []string
-			if !uploadFlags.stdin(inv) {
+			if ociReference == "" && !uploadFlags.stdin(inv) {
 				varsFiles, err = codersdk.DiscoverVarsFiles(uploadFlags.directory)
 				if err != nil {
 					return err
@@ -140,9 +149,14 @@ func (r *RootCmd) templatePush() *serpent.Command {
 				}
 			}
 
-			resp, err := uploadFlags.upload(inv, client)
-			if err != nil {
-				return err
+			// Artifacts are pulled by coderd, so there is nothing to upload.
+			var fileID uuid.UUID
+			if ociReference == "" {
+				resp, err := uploadFlags.upload(inv, client)
+				if err != nil {
+					return err
+				}
+				fileID = resp.ID
 			}
 
 			userVariableValues, err := codersdk.ParseUserVariableValues(
@@ -158,7 +172,8 @@ func (r *RootCmd) templatePush() *serpent.Command {
 				Client:             client,
 				Organization:       organization,
 				Provisioner:        codersdk.ProvisionerType(provisioner),
-				FileID:             resp.ID,
+				FileID:             fileID,
+				OCIReference:       ociReference,
 				ProvisionerTags:    tags,
 				UserVariableValues: userVariableValues,
 			}
@@ -259,6 +274,11 @@ func (r *RootCmd) templatePush() *serpent.Command {
 			Default:     "true",
 			Value:       serpent.BoolOf(&activate),
 		},
+		{
+			Flag:        "oci-reference",
+			Description: "Create the template version from a template artifact in an OCI registry instead of a directory, e.g. ghcr.io/acme/templates/docker:v1. The artifact is pulled by the Coder server.",
+			Value:       serpent.StringOf(&ociReference),
+		},
 		cliui.SkipPromptOption(),
 	}
 	cmd.Options = append(cmd.Options, uploadFlags.options()...)
@@ -413,6 +433,9 @@ type createValidTemplateVersionArgs struct {
 	Organization codersdk.Organization
 	Provisioner  codersdk.ProvisionerType
 	FileID       uuid.UUID
+	// OCIReference is used instead of FileID to create the version from an
+	// OCI artifact.
+	OCIReference string
 
 	// Template is only required if updating a template's active version.
 	Template *codersdk.Template
@@ -432,6 +455,7 @@ func createValidTemplateVersion(inv *serpent.Invocation, args createValidTemplat
 		Message:            args.Message,
 		StorageMethod:      codersdk.ProvisionerStorageMethodFile,
 		FileID:             args.FileID,
+		OCIReference:       args.OCIReference,
 		Provisioner:        args.Provisioner,
 		ProvisionerTags:    args.ProvisionerTags,
 		UserVariableValues: args.UserVariableValues,
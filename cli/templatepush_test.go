@@ -20,6 +20,8 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/oci"
+	"github.com/coder/coder/v2/coderd/oci/ocitest"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
@@ -409,6 +411,40 @@ func TestTemplatePush(t *testing.T) {
 		assert.NotEqual(t, template.ActiveVersionID, templateVersions[1].ID)
 	})
 
+	t.Run("OCIReference", func(t *testing.T) {
+		t.Parallel()
+		source, err := echo.Tar(&echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+		})
+		require.NoError(t, err)
+		registry := ocitest.New(t, "acme/templates/docker", "v1", oci.MediaTypeTemplateLayer, source)
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			HTTPClient:               registry.Client(),
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+
+		inv, root := clitest.New(
+			t, "templates", "push", "docker",
+			"--oci-reference", registry.Reference,
+			"--test.provisioner", string(database.ProvisionerTypeEcho),
+			"--yes",
+		)
+		clitest.SetupConfig(t, templateAdmin, root)
+		require.NoError(t, inv.Run())
+
+		template, err := client.TemplateByName(context.Background(), owner.OrganizationID, "docker")
+		require.NoError(t, err)
+		version, err := client.TemplateVersion(context.Background(), template.ActiveVersionID)
+		require.NoError(t, err)
+		require.Equal(t, "Pulled from "+registry.Reference, version.Message)
+		fl, _, err := client.Download(context.Background(), version.Job.FileID)
+		require.NoError(t, err)
+		require.Equal(t, source, fl)
+	})
+
 	t.Run("ProvisionerTags", func(t *testing.T) {
 		t.Parallel()
 
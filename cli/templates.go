@@ -35,6 +35,7 @@ func (r *RootCmd) templates() *serpent.Command {
 			r.templateVersions(),
 			r.templateDelete(),
 			r.templatePull(),
+			r.templatePromote(),
 			r.archiveTemplateVersions(),
 		},
 	}
@@ -50,6 +50,7 @@ func (r *RootCmd) createToken() *serpent.Command {
 		tokenLifetime string
 		name          string
 		user          string
+		scope         string
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -88,6 +89,7 @@ func (r *RootCmd) createToken() *serpent.Command {
 
 			res, err := client.CreateToken(inv.Context(), userID, codersdk.CreateTokenRequest{
 				Lifetime:  parsedLifetime,
+				Scope:     codersdk.APIKeyScope(scope),
 				TokenName: name,
 			})
 			if err != nil {
@@ -121,6 +123,18 @@ func (r *RootCmd) createToken() *serpent.Command {
 			Description:   "Specify the user to create the token for (Only works if logged in user is admin).",
 			Value:         serpent.StringOf(&user),
 		},
+		{
+			Flag:        "scope",
+			Env:         "CODER_TOKEN_SCOPE",
+			Description: "Specify the scope of the token, which restricts what it can do.",
+			Default:     string(codersdk.APIKeyScopeAll),
+			Value: serpent.EnumOf(&scope,
+				string(codersdk.APIKeyScopeAll),
+				string(codersdk.APIKeyScopeWorkspaceRead),
+				string(codersdk.APIKeyScopeBuildCreate),
+				string(codersdk.APIKeyScopeTemplateAdmin),
+			),
+		},
 	}
 
 	return cmd
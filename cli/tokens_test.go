@@ -156,4 +156,18 @@ func TestTokens(t *testing.T) {
 	res = buf.String()
 	require.NotEmpty(t, res)
 	require.Contains(t, res, "deleted")
+
+	// Create a scoped token
+	inv, root = clitest.New(t, "tokens", "create", "--name", "token-four", "--scope", "workspace:read")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	scopedTokens, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+	require.NoError(t, err)
+	require.Len(t, scopedTokens, 1)
+	require.Equal(t, "token-four", scopedTokens[0].TokenName)
+	require.Equal(t, codersdk.APIKeyScopeWorkspaceRead, scopedTokens[0].Scope)
 }
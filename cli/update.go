@@ -30,7 +30,17 @@ func (r *RootCmd) update() *serpent.Command {
 			if err != nil {
 				return err
 			}
-			if !workspace.Outdated && !parameterFlags.promptRichParameters && !parameterFlags.promptEphemeralParameters && len(parameterFlags.ephemeralParameters) == 0 {
+			// Workspaces that track a release channel are up to date when they
+			// run the version published to the channel.
+			outdated := workspace.Outdated
+			version, err := workspaceUpdateVersion(inv.Context(), client, workspace)
+			if err != nil {
+				return err
+			}
+			if version != workspace.TemplateActiveVersionID {
+				outdated = version != workspace.LatestBuild.TemplateVersionID
+			}
+			if !outdated && !parameterFlags.promptRichParameters && !parameterFlags.promptEphemeralParameters && len(parameterFlags.ephemeralParameters) == 0 && !parameterFlags.useParameterDefaults {
 				_, _ = fmt.Fprintf(inv.Stdout, "Workspace is up-to-date.\n")
 				return nil
 			}
@@ -0,0 +1,722 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// accessRequestMaxDuration bounds how long access can be requested for.
+	accessRequestMaxDuration = 7 * 24 * time.Hour
+	// accessRequestExpiryInterval is how often expired grants are revoked.
+	// Like workspace migrations, grants are revoked by whichever replica
+	// notices first.
+	accessRequestExpiryInterval = 15 * time.Second
+)
+
+// errAccessRequestReviewed is returned when a request was reviewed
+// concurrently.
+var errAccessRequestReviewed = xerrors.New("access request already reviewed")
+
+// accessRequestTarget is the workspace or template an access request is for.
+type accessRequestTarget struct {
+	organizationID uuid.UUID
+	object         rbac.Object
+	// name is shown to reviewers, and path is the path of the resource in
+	// the dashboard.
+	name string
+	path string
+	// ownerID is the owner of the workspace, and is not set for templates.
+	ownerID uuid.UUID
+}
+
+// @Summary Create access request
+// @Description Requests a role on a workspace or template for a limited time.
+// @Description Reviewers of the resource are notified, and the role is
+// @Description granted once the request is approved.
+// @ID create-access-request
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.CreateAccessRequest true "Create access request request"
+// @Success 201 {object} codersdk.AccessRequest
+// @Router /accessrequests [post]
+func (api *API) postAccessRequest(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		apiKey  = httpmw.APIKey(r)
+		auditor = api.Auditor.Load()
+	)
+
+	aReq, commitAudit := audit.InitRequest[database.AccessRequest](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
+	var req codersdk.CreateAccessRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.DurationMillis <= 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid access request.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "duration_ms",
+				Detail: "Access must be requested for a positive duration.",
+			}},
+		})
+		return
+	}
+	if req.DurationMillis > accessRequestMaxDuration.Milliseconds() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid access request.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "duration_ms",
+				Detail: fmt.Sprintf("Access can be requested for at most %s.", accessRequestMaxDuration),
+			}},
+		})
+		return
+	}
+
+	// Requesters usually can't read the resource they request access to.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	resourceType := database.AccessRequestResourceType(req.ResourceType)
+	if !resourceType.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid access request.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "resource_type",
+				Detail: fmt.Sprintf("Resource type must be %q or %q.", codersdk.AccessRequestResourceTypeWorkspace, codersdk.AccessRequestResourceTypeTemplate),
+			}},
+		})
+		return
+	}
+	target, err := api.accessRequestTarget(sysCtx, resourceType, req.ResourceID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The %s %q does not exist.", req.ResourceType, req.ResourceID),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.UpdateOrganizationID(target.organizationID)
+
+	validations, err := api.validateAccessRequest(sysCtx, resourceType, req.ResourceID, apiKey.UserID, req.Role)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid access request.",
+			Validations: validations,
+		})
+		return
+	}
+
+	accessRequest, err := api.Database.InsertAccessRequest(sysCtx, database.InsertAccessRequestParams{
+		ID:             uuid.New(),
+		OrganizationID: target.organizationID,
+		RequesterID:    apiKey.UserID,
+		ResourceType:   resourceType,
+		ResourceID:     req.ResourceID,
+		Role:           req.Role,
+		Reason:         req.Reason,
+		DurationMs:     req.DurationMillis,
+		CreatedAt:      dbtime.Time(api.Clock.Now()),
+	})
+	if database.IsUniqueViolation(err, database.UniqueAccessRequestsOpenIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("You already have a pending or approved access request for this %s.", req.ResourceType),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating access request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = accessRequest
+
+	api.notifyAccessRequestCreated(ctx, accessRequest, target)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertAccessRequest(accessRequest))
+}
+
+// @Summary Get access requests
+// @Description Returns the access requests made by the user, and those the
+// @Description user can review.
+// @ID get-access-requests
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Authorization
+// @Param status query string false "Filter by status" Enums(pending,approved,denied,expired)
+// @Success 200 {array} codersdk.AccessRequest
+// @Router /accessrequests [get]
+func (api *API) accessRequests(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
+	parser := httpapi.NewQueryParamParser()
+	status := httpapi.ParseCustom(parser, r.URL.Query(), "", "status", httpapi.ParseEnum[database.AccessRequestStatus])
+	parser.ErrorExcessParams(r.URL.Query())
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	// Requests are visible to their requester and to those who can review
+	// them, which is checked against the requested resource below.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	requests, err := api.Database.GetAccessRequests(sysCtx, database.GetAccessRequestsParams{
+		Status: string(status),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching access requests.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	canReview := make(map[uuid.UUID]bool)
+	visible := make([]codersdk.AccessRequest, 0, len(requests))
+	for _, accessRequest := range requests {
+		if accessRequest.RequesterID != apiKey.UserID {
+			allowed, ok := canReview[accessRequest.ResourceID]
+			if !ok {
+				target, err := api.accessRequestTarget(sysCtx, accessRequest.ResourceType, accessRequest.ResourceID)
+				if err != nil && !httpapi.Is404Error(err) {
+					httpapi.InternalServerError(rw, err)
+					return
+				}
+				allowed = err == nil && api.Authorize(r, policy.ActionUpdate, target.object)
+				canReview[accessRequest.ResourceID] = allowed
+			}
+			if !allowed {
+				continue
+			}
+		}
+		visible = append(visible, convertAccessRequest(accessRequest))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, visible)
+}
+
+// @Summary Get access request by ID
+// @ID get-access-request-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Authorization
+// @Param accessrequest path string true "Access request ID" format(uuid)
+// @Success 200 {object} codersdk.AccessRequest
+// @Router /accessrequests/{accessrequest} [get]
+func (api *API) accessRequest(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accessRequest, _, ok := api.accessRequestParam(rw, r)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertAccessRequest(accessRequest))
+}
+
+// @Summary Review access request
+// @Description Approves or denies a pending access request. Approving grants
+// @Description the requested role on the resource until the request expires,
+// @Description after which the role the requester had before is restored.
+// @ID review-access-request
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param accessrequest path string true "Access request ID" format(uuid)
+// @Param request body codersdk.ReviewAccessRequest true "Review access request request"
+// @Success 200 {object} codersdk.AccessRequest
+// @Router /accessrequests/{accessrequest}/review [post]
+func (api *API) postAccessRequestReview(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		apiKey  = httpmw.APIKey(r)
+		auditor = api.Auditor.Load()
+	)
+	accessRequest, target, ok := api.accessRequestParam(rw, r)
+	if !ok {
+		return
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.AccessRequest](rw, &audit.RequestParams{
+		Audit:          *auditor,
+		Log:            api.Logger,
+		Request:        r,
+		Action:         database.AuditActionWrite,
+		OrganizationID: accessRequest.OrganizationID,
+	})
+	defer commitAudit()
+	aReq.Old = accessRequest
+
+	var req codersdk.ReviewAccessRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Status != codersdk.AccessRequestStatusApproved && req.Status != codersdk.AccessRequestStatusDenied {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Status must be %q or %q.", codersdk.AccessRequestStatusApproved, codersdk.AccessRequestStatusDenied),
+		})
+		return
+	}
+	if accessRequest.RequesterID == apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can't review your own access request.",
+		})
+		return
+	}
+	if target == nil || !api.Authorize(r, policy.ActionUpdate, target.object) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if accessRequest.Status != database.AccessRequestStatusPending {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The access request is already %s.", accessRequest.Status),
+		})
+		return
+	}
+
+	now := dbtime.Time(api.Clock.Now())
+	params := database.UpdateAccessRequestReviewByIDParams{
+		ID:         accessRequest.ID,
+		Status:     database.AccessRequestStatus(req.Status),
+		ReviewedBy: uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+		ReviewedAt: sql.NullTime{Time: now, Valid: true},
+	}
+	if req.Status == codersdk.AccessRequestStatusApproved {
+		duration := time.Duration(accessRequest.DurationMs) * time.Millisecond
+		params.ExpiresAt = sql.NullTime{Time: now.Add(duration), Valid: true}
+	}
+
+	// The reviewer was authorized against the resource above, and the role
+	// is granted on their behalf.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	err := api.Database.InTx(func(tx database.Store) error {
+		if req.Status == codersdk.AccessRequestStatusApproved {
+			previousRole, err := setAccessRequestRole(sysCtx, tx, accessRequest, nil, accessRequest.Role)
+			if err != nil {
+				return xerrors.Errorf("grant role: %w", err)
+			}
+			params.PreviousRole = previousRole
+		}
+		var err error
+		accessRequest, err = tx.UpdateAccessRequestReviewByID(sysCtx, params)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errAccessRequestReviewed
+		}
+		if err != nil {
+			return xerrors.Errorf("update access request review: %w", err)
+		}
+		return nil
+	}, nil)
+	if errors.Is(err, errAccessRequestReviewed) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The access request has already been reviewed.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reviewing access request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = accessRequest
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertAccessRequest(accessRequest))
+}
+
+// accessRequestParam fetches the access request from the URL, and returns
+// its target if it still exists. It writes a 404 if the request isn't
+// visible to the user.
+func (api *API) accessRequestParam(rw http.ResponseWriter, r *http.Request) (database.AccessRequest, *accessRequestTarget, bool) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	id, ok := httpmw.ParseUUIDParam(rw, r, "accessrequest")
+	if !ok {
+		return database.AccessRequest{}, nil, false
+	}
+
+	// nolint:gocritic // Visibility is checked against the resource below.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	accessRequest, err := api.Database.GetAccessRequestByID(sysCtx, id)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.AccessRequest{}, nil, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching access request.",
+			Detail:  err.Error(),
+		})
+		return database.AccessRequest{}, nil, false
+	}
+
+	var target *accessRequestTarget
+	t, err := api.accessRequestTarget(sysCtx, accessRequest.ResourceType, accessRequest.ResourceID)
+	if err != nil && !httpapi.Is404Error(err) {
+		httpapi.InternalServerError(rw, err)
+		return database.AccessRequest{}, nil, false
+	}
+	if err == nil {
+		target = &t
+	}
+	if accessRequest.RequesterID != apiKey.UserID &&
+		(target == nil || !api.Authorize(r, policy.ActionUpdate, target.object)) {
+		httpapi.ResourceNotFound(rw)
+		return database.AccessRequest{}, nil, false
+	}
+	return accessRequest, target, true
+}
+
+// accessRequestTarget fetches the resource an access request is for. It
+// returns sql.ErrNoRows if the resource doesn't exist.
+func (api *API) accessRequestTarget(ctx context.Context, resourceType database.AccessRequestResourceType, resourceID uuid.UUID) (accessRequestTarget, error) {
+	switch resourceType {
+	case database.AccessRequestResourceTypeWorkspace:
+		workspace, err := api.Database.GetWorkspaceByID(ctx, resourceID)
+		if err != nil {
+			return accessRequestTarget{}, err
+		}
+		if workspace.Deleted {
+			return accessRequestTarget{}, sql.ErrNoRows
+		}
+		return accessRequestTarget{
+			organizationID: workspace.OrganizationID,
+			object:         workspace.RBACObject(),
+			name:           workspace.OwnerUsername + "/" + workspace.Name,
+			path:           fmt.Sprintf("@%s/%s", workspace.OwnerUsername, workspace.Name),
+			ownerID:        workspace.OwnerID,
+		}, nil
+	case database.AccessRequestResourceTypeTemplate:
+		template, err := api.Database.GetTemplateByID(ctx, resourceID)
+		if err != nil {
+			return accessRequestTarget{}, err
+		}
+		if template.Deleted {
+			return accessRequestTarget{}, sql.ErrNoRows
+		}
+		name := template.DisplayName
+		if name == "" {
+			name = template.Name
+		}
+		return accessRequestTarget{
+			organizationID: template.OrganizationID,
+			object:         template.RBACObject(),
+			name:           name,
+			path:           fmt.Sprintf("templates/%s/%s", template.OrganizationName, template.Name),
+		}, nil
+	}
+	return accessRequestTarget{}, xerrors.Errorf("unknown access request resource type %q", resourceType)
+}
+
+// validateAccessRequest checks that the role is valid for the resource, and
+// that it can be granted to the requester.
+func (api *API) validateAccessRequest(ctx context.Context, resourceType database.AccessRequestResourceType, resourceID, requesterID uuid.UUID, role string) ([]codersdk.ValidationError, error) {
+	switch resourceType {
+	case database.AccessRequestResourceTypeWorkspace:
+		workspace, err := api.Database.GetWorkspaceByID(ctx, resourceID)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace by ID: %w", err)
+		}
+		// The role is granted through the workspace ACL, so the same rules
+		// apply as when sharing the workspace.
+		validations, err := api.validateWorkspaceACL(ctx, workspace, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				requesterID.String(): codersdk.WorkspaceRole(role),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range validations {
+			validations[i].Field = "role"
+		}
+		return validations, nil
+	default:
+		template, err := api.Database.GetTemplateByID(ctx, resourceID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template by ID: %w", err)
+		}
+		if len(db2sdk.TemplateRoleActions(codersdk.TemplateRole(role))) == 0 {
+			return []codersdk.ValidationError{{Field: "role", Detail: fmt.Sprintf("Role %q is not a valid template role.", role)}}, nil
+		}
+		rows, err := api.Database.GetOrganizationIDsByMemberIDs(ctx, []uuid.UUID{requesterID})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get organization IDs by member IDs: %w", err)
+		}
+		if !slices.ContainsFunc(rows, func(row database.GetOrganizationIDsByMemberIDsRow) bool {
+			return slices.Contains(row.OrganizationIDs, template.OrganizationID)
+		}) {
+			return []codersdk.ValidationError{{Field: "resource_id", Detail: "You are not a member of the template's organization."}}, nil
+		}
+		return nil, nil
+	}
+}
+
+// notifyAccessRequestCreated notifies the reviewers of the target that a
+// new access request is pending. Workspace owners review requests for their
+// workspaces, and template admins review requests for templates.
+func (api *API) notifyAccessRequestCreated(ctx context.Context, accessRequest database.AccessRequest, target accessRequestTarget) {
+	// nolint:gocritic // The requester and template admins are looked up as the system.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	requester, err := api.Database.GetUserByID(sysCtx, accessRequest.RequesterID)
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to fetch requester for access request notification", slog.F("access_request_id", accessRequest.ID), slog.Error(err))
+		return
+	}
+	reviewers := []uuid.UUID{target.ownerID}
+	if accessRequest.ResourceType == database.AccessRequestResourceTypeTemplate {
+		admins, err := findTemplateAdmins(sysCtx, api.Database)
+		if err != nil {
+			api.Logger.Warn(ctx, "failed to fetch template admins for access request notification", slog.F("access_request_id", accessRequest.ID), slog.Error(err))
+			return
+		}
+		reviewers = make([]uuid.UUID, 0, len(admins))
+		for _, admin := range admins {
+			reviewers = append(reviewers, admin.ID)
+		}
+	}
+	for _, reviewerID := range reviewers {
+		if reviewerID == accessRequest.RequesterID {
+			continue
+		}
+		// nolint:gocritic // Need notifier actor to enqueue notifications
+		if _, err := api.NotificationsEnqueuer.Enqueue(dbauthz.AsNotifier(ctx), reviewerID, notifications.TemplateAccessRequestCreated,
+			map[string]string{
+				"requester":     requester.Username,
+				"role":          accessRequest.Role,
+				"resource_type": string(accessRequest.ResourceType),
+				"resource":      target.name,
+				"resource_path": target.path,
+				"duration":      (time.Duration(accessRequest.DurationMs) * time.Millisecond).String(),
+				"reason":        accessRequest.Reason,
+			}, "api-access-requests",
+			// Associate this notification with all the related entities.
+			accessRequest.ID, accessRequest.ResourceID, accessRequest.OrganizationID,
+		); err != nil {
+			api.Logger.Warn(ctx, "failed to notify of access request", slog.F("access_request_id", accessRequest.ID), slog.Error(err))
+		}
+	}
+}
+
+// setAccessRequestRole sets the role of the requester in the ACL of the
+// requested resource, and returns the role they had before. An empty role
+// removes the requester from the ACL. If from is not nil, the ACL is left
+// alone unless the requester currently has that role. The resource is locked
+// until the end of the transaction, so db must be a transaction.
+func setAccessRequestRole(ctx context.Context, db database.Store, accessRequest database.AccessRequest, from *string, role string) (string, error) {
+	//nolint:gocritic // Reviewers are authorized when the request is reviewed.
+	ctx = dbauthz.AsAccessRequestGranter(ctx)
+	userID := accessRequest.RequesterID.String()
+	switch accessRequest.ResourceType {
+	case database.AccessRequestResourceTypeWorkspace:
+		workspace, err := db.GetWorkspaceByIDForUpdate(ctx, accessRequest.ResourceID)
+		if err != nil {
+			return "", xerrors.Errorf("get workspace by ID for update: %w", err)
+		}
+		previous := convertToWorkspaceRole(workspace.UserACL[userID])
+		if from != nil && string(previous) != *from {
+			return string(previous), nil
+		}
+		err = db.UpdateWorkspaceACLByID(ctx, database.UpdateWorkspaceACLByIDParams{
+			ID: workspace.ID,
+			UserACL: updateWorkspaceACLRoles(workspace.UserACL, map[string]codersdk.WorkspaceRole{
+				userID: codersdk.WorkspaceRole(role),
+			}),
+			GroupACL: workspace.GroupACL,
+		})
+		if err != nil {
+			return "", xerrors.Errorf("update workspace ACL: %w", err)
+		}
+		return string(previous), nil
+	case database.AccessRequestResourceTypeTemplate:
+		template, err := db.GetTemplateByIDForUpdate(ctx, accessRequest.ResourceID)
+		if err != nil {
+			return "", xerrors.Errorf("get template by ID for update: %w", err)
+		}
+		previous := convertToTemplateRole(template.UserACL[userID])
+		if from != nil && string(previous) != *from {
+			return string(previous), nil
+		}
+		userACL := maps.Clone(template.UserACL)
+		if userACL == nil {
+			userACL = database.TemplateACL{}
+		}
+		if role == "" {
+			delete(userACL, userID)
+		} else {
+			userACL[userID] = db2sdk.TemplateRoleActions(codersdk.TemplateRole(role))
+		}
+		err = db.UpdateTemplateACLByID(ctx, database.UpdateTemplateACLByIDParams{
+			ID:       template.ID,
+			UserACL:  userACL,
+			GroupACL: template.GroupACL,
+		})
+		if err != nil {
+			return "", xerrors.Errorf("update template ACL: %w", err)
+		}
+		return string(previous), nil
+	}
+	return "", xerrors.Errorf("unknown access request resource type %q", accessRequest.ResourceType)
+}
+
+func (api *API) runAccessRequestExpiry(ctx context.Context) {
+	defer close(api.accessRequestExpiryDone)
+	//nolint:gocritic // The system revokes expired grants.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	ticker := api.Clock.NewTicker(accessRequestExpiryInterval, "access_requests")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if api.ReadOnlyMode.Enabled() {
+			continue
+		}
+
+		expired, err := api.Database.GetExpiredAccessRequests(ctx, dbtime.Time(api.Clock.Now()))
+		if err != nil {
+			if ctx.Err() == nil {
+				api.Logger.Error(ctx, "failed to fetch expired access requests", slog.Error(err))
+			}
+			continue
+		}
+		for _, accessRequest := range expired {
+			if err := api.expireAccessRequest(ctx, accessRequest); err != nil && ctx.Err() == nil {
+				api.Logger.Error(ctx, "failed to expire access request",
+					slog.F("access_request_id", accessRequest.ID),
+					slog.Error(err),
+				)
+			}
+		}
+	}
+}
+
+// expireAccessRequest restores the role the requester had before the request
+// was approved, and records the expiry in the audit log.
+func (api *API) expireAccessRequest(ctx context.Context, accessRequest database.AccessRequest) error {
+	var expired database.AccessRequest
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		expired, err = tx.UpdateAccessRequestExpiredByID(ctx, accessRequest.ID)
+		if err != nil {
+			return xerrors.Errorf("update access request expired: %w", err)
+		}
+		// The role is only restored if it wasn't changed since the grant, so
+		// that a later grant isn't revoked. The resource may also have been
+		// deleted since the grant.
+		_, err = setAccessRequestRole(ctx, tx, accessRequest, &accessRequest.Role, accessRequest.PreviousRole)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("revoke role: %w", err)
+		}
+		return nil
+	}, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another replica expired the request first.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.AccessRequest]{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		UserID:           accessRequest.RequesterID,
+		OrganizationID:   accessRequest.OrganizationID,
+		Action:           database.AuditActionWrite,
+		Old:              accessRequest,
+		New:              expired,
+		Status:           http.StatusOK,
+		AdditionalFields: audit.BackgroundTaskFieldsBytes(ctx, api.Logger, audit.BackgroundSubsystemAccessRequests),
+	})
+	return nil
+}
+
+func convertToTemplateRole(actions []policy.Action) codersdk.TemplateRole {
+	switch {
+	case slice.SameElements(actions, db2sdk.TemplateRoleActions(codersdk.TemplateRoleAdmin)):
+		return codersdk.TemplateRoleAdmin
+	case slice.SameElements(actions, db2sdk.TemplateRoleActions(codersdk.TemplateRoleUse)):
+		return codersdk.TemplateRoleUse
+	}
+	return codersdk.TemplateRoleDeleted
+}
+
+func convertAccessRequest(accessRequest database.AccessRequest) codersdk.AccessRequest {
+	converted := codersdk.AccessRequest{
+		ID:             accessRequest.ID,
+		OrganizationID: accessRequest.OrganizationID,
+		RequesterID:    accessRequest.RequesterID,
+		ResourceType:   codersdk.AccessRequestResourceType(accessRequest.ResourceType),
+		ResourceID:     accessRequest.ResourceID,
+		Role:           accessRequest.Role,
+		Reason:         accessRequest.Reason,
+		DurationMillis: accessRequest.DurationMs,
+		Status:         codersdk.AccessRequestStatus(accessRequest.Status),
+		CreatedAt:      accessRequest.CreatedAt,
+	}
+	if accessRequest.ReviewedBy.Valid {
+		converted.ReviewerID = ptr.Ref(accessRequest.ReviewedBy.UUID)
+	}
+	if accessRequest.ReviewedAt.Valid {
+		converted.ReviewedAt = ptr.Ref(accessRequest.ReviewedAt.Time)
+	}
+	if accessRequest.ExpiresAt.Valid {
+		converted.ExpiresAt = ptr.Ref(accessRequest.ExpiresAt.Time)
+	}
+	return converted
+}
@@ -0,0 +1,281 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestAccessRequests(t *testing.T) {
+	t.Parallel()
+
+	// canSSH returns whether the client can SSH into the workspace.
+	canSSH := func(t *testing.T, client *codersdk.Client, workspace codersdk.Workspace) bool {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		res, err := client.AuthCheck(ctx, codersdk.AuthorizationRequest{
+			Checks: map[string]codersdk.AuthorizationCheck{
+				"ssh": {
+					Object: codersdk.AuthorizationObject{
+						ResourceType: codersdk.ResourceWorkspace,
+						ResourceID:   workspace.ID.String(),
+					},
+					Action: codersdk.ActionSSH,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return res["ssh"]
+	}
+
+	t.Run("Workspace", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		notifyEnq := notificationstest.NewFakeEnqueuer()
+		clock := quartz.NewMock(t)
+		clock.Set(time.Now())
+		owner := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Auditor:                  auditor,
+			NotificationsEnqueuer:    notifyEnq,
+			Clock:                    clock,
+		})
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, user := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		requester, requesterUser := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		other, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, owner, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, first.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		accessRequest, err := requester.CreateAccessRequest(ctx, codersdk.CreateAccessRequest{
+			ResourceType:   codersdk.AccessRequestResourceTypeWorkspace,
+			ResourceID:     workspace.ID,
+			Role:           string(codersdk.WorkspaceRoleConnect),
+			Reason:         "Debugging a failing deployment",
+			DurationMillis: time.Minute.Milliseconds(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.AccessRequestStatusPending, accessRequest.Status)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeAccessRequest,
+			ResourceID:   accessRequest.ID,
+		}))
+		require.False(t, canSSH(t, requester, workspace))
+
+		// The workspace owner reviews requests for their workspace.
+		sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateAccessRequestCreated))
+		require.Len(t, sent, 1)
+		require.Equal(t, user.ID, sent[0].UserID)
+		require.Equal(t, string(codersdk.WorkspaceRoleConnect), sent[0].Labels["role"])
+
+		// Only one request can be open per resource.
+		_, err = requester.CreateAccessRequest(ctx, codersdk.CreateAccessRequest{
+			ResourceType:   codersdk.AccessRequestResourceTypeWorkspace,
+			ResourceID:     workspace.ID,
+			Role:           string(codersdk.WorkspaceRoleView),
+			Reason:         "Again",
+			DurationMillis: time.Minute.Milliseconds(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// Requests are visible to the requester and the reviewers only.
+		requests, err := client.AccessRequests(ctx, codersdk.AccessRequestFilter{Status: codersdk.AccessRequestStatusPending})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		requests, err = other.AccessRequests(ctx, codersdk.AccessRequestFilter{})
+		require.NoError(t, err)
+		require.Empty(t, requests)
+		_, err = other.AccessRequest(ctx, accessRequest.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		_, err = requester.ReviewAccessRequest(ctx, accessRequest.ID, codersdk.ReviewAccessRequest{Status: codersdk.AccessRequestStatusApproved})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		accessRequest, err = client.ReviewAccessRequest(ctx, accessRequest.ID, codersdk.ReviewAccessRequest{Status: codersdk.AccessRequestStatusApproved})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.AccessRequestStatusApproved, accessRequest.Status)
+		require.NotNil(t, accessRequest.ExpiresAt)
+		require.Equal(t, user.ID, *accessRequest.ReviewerID)
+		require.True(t, canSSH(t, requester, workspace))
+		acl, err := client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, codersdk.WorkspaceRoleConnect, acl.Users[0].Role)
+
+		// The grant is revoked once the request expires.
+		for clock.Now().Before(accessRequest.ExpiresAt.Add(time.Minute)) {
+			clock.AdvanceNext()
+		}
+		require.Eventually(t, func() bool {
+			accessRequest, err = requester.AccessRequest(ctx, accessRequest.ID)
+			return err == nil && accessRequest.Status == codersdk.AccessRequestStatusExpired
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.False(t, canSSH(t, requester, workspace))
+		acl, err = client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, acl.Users)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionWrite,
+			ResourceType: database.ResourceTypeAccessRequest,
+			ResourceID:   accessRequest.ID,
+			UserID:       requesterUser.ID,
+		}))
+	})
+
+	t.Run("ExpiryKeepsChangedRole", func(t *testing.T) {
+		t.Parallel()
+		clock := quartz.NewMock(t)
+		clock.Set(time.Now())
+		owner := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Clock:                    clock,
+		})
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		requester, requesterUser := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, owner, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, first.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		accessRequest, err := requester.CreateAccessRequest(ctx, codersdk.CreateAccessRequest{
+			ResourceType:   codersdk.AccessRequestResourceTypeWorkspace,
+			ResourceID:     workspace.ID,
+			Role:           string(codersdk.WorkspaceRoleConnect),
+			Reason:         "Debugging a failing deployment",
+			DurationMillis: time.Minute.Milliseconds(),
+		})
+		require.NoError(t, err)
+		accessRequest, err = client.ReviewAccessRequest(ctx, accessRequest.ID, codersdk.ReviewAccessRequest{Status: codersdk.AccessRequestStatusApproved})
+		require.NoError(t, err)
+
+		// The owner shares the workspace with the requester for good while the
+		// grant is active.
+		err = client.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				requesterUser.ID.String(): codersdk.WorkspaceRoleView,
+			},
+		})
+		require.NoError(t, err)
+
+		for clock.Now().Before(accessRequest.ExpiresAt.Add(time.Minute)) {
+			clock.AdvanceNext()
+		}
+		require.Eventually(t, func() bool {
+			accessRequest, err = requester.AccessRequest(ctx, accessRequest.ID)
+			return err == nil && accessRequest.Status == codersdk.AccessRequestStatusExpired
+		}, testutil.WaitLong, testutil.IntervalFast)
+		acl, err := client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, codersdk.WorkspaceRoleView, acl.Users[0].Role)
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		t.Parallel()
+		owner := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		first := coderdtest.CreateFirstUser(t, owner)
+		requester, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, owner, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, first.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		accessRequest, err := requester.CreateAccessRequest(ctx, codersdk.CreateAccessRequest{
+			ResourceType:   codersdk.AccessRequestResourceTypeTemplate,
+			ResourceID:     template.ID,
+			Role:           string(codersdk.TemplateRoleAdmin),
+			Reason:         "Updating the template",
+			DurationMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+
+		accessRequest, err = owner.ReviewAccessRequest(ctx, accessRequest.ID, codersdk.ReviewAccessRequest{Status: codersdk.AccessRequestStatusDenied})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.AccessRequestStatusDenied, accessRequest.Status)
+		require.Nil(t, accessRequest.ExpiresAt)
+
+		// Reviewed requests can't be reviewed again.
+		_, err = owner.ReviewAccessRequest(ctx, accessRequest.ID, codersdk.ReviewAccessRequest{Status: codersdk.AccessRequestStatusApproved})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// A denied request doesn't prevent requesting again.
+		accessRequest, err = requester.CreateAccessRequest(ctx, codersdk.CreateAccessRequest{
+			ResourceType:   codersdk.AccessRequestResourceTypeTemplate,
+			ResourceID:     template.ID,
+			Role:           string(codersdk.TemplateRoleAdmin),
+			Reason:         "Updating the template, please",
+			DurationMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		_, err = owner.ReviewAccessRequest(ctx, accessRequest.ID, codersdk.ReviewAccessRequest{Status: codersdk.AccessRequestStatusApproved})
+		require.NoError(t, err)
+
+		res, err := requester.AuthCheck(ctx, codersdk.AuthorizationRequest{
+			Checks: map[string]codersdk.AuthorizationCheck{
+				"update": {
+					Object: codersdk.AuthorizationObject{
+						ResourceType: codersdk.ResourceTemplate,
+						ResourceID:   template.ID.String(),
+					},
+					Action: codersdk.ActionUpdate,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, res["update"])
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		owner := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, owner, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, first.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+
+		for name, req := range map[string]codersdk.CreateAccessRequest{
+			"ResourceType":     {ResourceType: "organization", ResourceID: first.OrganizationID, Role: "admin", Reason: "x", DurationMillis: 1000},
+			"Resource":         {ResourceType: codersdk.AccessRequestResourceTypeTemplate, ResourceID: workspace.ID, Role: "use", Reason: "x", DurationMillis: 1000},
+			"TemplateRole":     {ResourceType: codersdk.AccessRequestResourceTypeTemplate, ResourceID: template.ID, Role: "connect", Reason: "x", DurationMillis: 1000},
+			"WorkspaceRole":    {ResourceType: codersdk.AccessRequestResourceTypeWorkspace, ResourceID: workspace.ID, Role: "admin", Reason: "x", DurationMillis: 1000},
+			"OwnWorkspace":     {ResourceType: codersdk.AccessRequestResourceTypeWorkspace, ResourceID: workspace.ID, Role: "view", Reason: "x", DurationMillis: 1000},
+			"Duration":         {ResourceType: codersdk.AccessRequestResourceTypeTemplate, ResourceID: template.ID, Role: "use", Reason: "x", DurationMillis: (30 * 24 * time.Hour).Milliseconds()},
+			"ZeroDuration":     {ResourceType: codersdk.AccessRequestResourceTypeTemplate, ResourceID: template.ID, Role: "use", Reason: "x", DurationMillis: 0},
+			"NegativeDuration": {ResourceType: codersdk.AccessRequestResourceTypeTemplate, ResourceID: template.ID, Role: "use", Reason: "x", DurationMillis: -1000},
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				ctx := testutil.Context(t, testutil.WaitMedium)
+				_, err := client.CreateAccessRequest(ctx, req)
+				var apiErr *codersdk.Error
+				require.ErrorAs(t, err, &apiErr)
+				require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+			})
+		}
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
+	"github.com/coder/coder/v2/coderd/readonly"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/coderd/wspubsub"
@@ -48,6 +49,9 @@ type API struct {
 	*AppsAPI
 	*MetadataAPI
 	*ResourcesMonitoringAPI
+	*ResourceUsageAPI
+	*HealthEventsAPI
+	*SessionRecordingAPI
 	*LogsAPI
 	*ScriptsAPI
 	*AuditAPI
@@ -79,12 +83,15 @@ type Options struct {
 	PublishWorkspaceUpdateFn          func(ctx context.Context, userID uuid.UUID, event wspubsub.WorkspaceEvent)
 	PublishWorkspaceAgentLogsUpdateFn func(ctx context.Context, workspaceAgentID uuid.UUID, msg agentsdk.LogsNotifyMessage)
 	NetworkTelemetryHandler           func(batch []*tailnetproto.TelemetryEvent)
+	ReadOnlyMode                      *readonly.Mode
 
 	AccessURL                 *url.URL
 	AppHostname               string
+	PathAppsOnly              bool
 	AgentStatsRefreshInterval time.Duration
 	DisableDirectConnections  bool
 	DerpForceWebSockets       bool
+	SessionRecordingEnabled   bool
 	DerpMapUpdateFrequency    time.Duration
 	ExternalAuthConfigs       []*externalauth.Config
 	Experiments               codersdk.Experiments
@@ -105,9 +112,11 @@ func New(opts Options) *API {
 	api.ManifestAPI = &ManifestAPI{
 		AccessURL:                opts.AccessURL,
 		AppHostname:              opts.AppHostname,
+		PathAppsOnly:             opts.PathAppsOnly,
 		ExternalAuthConfigs:      opts.ExternalAuthConfigs,
 		DisableDirectConnections: opts.DisableDirectConnections,
 		DerpForceWebSockets:      opts.DerpForceWebSockets,
+		SessionRecordingEnabled:  opts.SessionRecordingEnabled,
 		AgentFn:                  api.agent,
 		Database:                 opts.Database,
 		DerpMapFn:                opts.DerpMapFn,
@@ -137,6 +146,31 @@ func New(opts Options) *API {
 		},
 	}
 
+	api.ResourceUsageAPI = &ResourceUsageAPI{
+		AgentID:  opts.AgentID,
+		Database: opts.Database,
+		Clock:    opts.Clock,
+	}
+
+	api.HealthEventsAPI = &HealthEventsAPI{
+		AgentID:                  opts.AgentID,
+		WorkspaceID:              opts.WorkspaceID,
+		AgentFn:                  api.agent,
+		Log:                      opts.Log,
+		Clock:                    opts.Clock,
+		Database:                 opts.Database,
+		NotificationsEnqueuer:    opts.NotificationsEnqueuer,
+		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
+	}
+
+	api.SessionRecordingAPI = &SessionRecordingAPI{
+		AgentID:     opts.AgentID,
+		WorkspaceID: opts.WorkspaceID,
+		Enabled:     opts.SessionRecordingEnabled,
+		Log:         opts.Log,
+		Database:    opts.Database,
+	}
+
 	api.StatsAPI = &StatsAPI{
 		AgentFn:                   api.agent,
 		Database:                  opts.Database,
@@ -208,6 +242,22 @@ func New(opts Options) *API {
 	return api
 }
 
+// readOnlyModeRPCs are served while the deployment is in read-only mode. They
+// either only read or, like the tailnet RPCs, keep connections to the
+// workspace working without changing the state of the deployment.
+var readOnlyModeRPCs = []string{
+	"/coder.agent.v2.Agent/GetManifest",
+	"/coder.agent.v2.Agent/GetServiceBanner",
+	"/coder.agent.v2.Agent/GetAnnouncementBanners",
+	"/coder.agent.v2.Agent/GetResourcesMonitoringConfiguration",
+	"/coder.agent.v2.Agent/ListSubAgents",
+	"/coder.tailnet.v2.Tailnet/PostTelemetry",
+	"/coder.tailnet.v2.Tailnet/StreamDERPMaps",
+	"/coder.tailnet.v2.Tailnet/RefreshResumeToken",
+	"/coder.tailnet.v2.Tailnet/Coordinate",
+	"/coder.tailnet.v2.Tailnet/WorkspaceUpdates",
+}
+
 func (a *API) Server(ctx context.Context) (*drpcserver.Server, error) {
 	mux := drpcmux.New()
 	err := agentproto.DRPCRegisterAgent(mux, a)
@@ -220,7 +270,12 @@ func (a *API) Server(ctx context.Context) (*drpcserver.Server, error) {
 		return nil, xerrors.Errorf("register tailnet API protocol in DRPC mux: %w", err)
 	}
 
-	return drpcserver.NewWithOptions(&tracing.DRPCHandler{Handler: mux},
+	handler := &readonly.DRPCHandler{
+		Handler:  mux,
+		Mode:     a.opts.ReadOnlyMode,
+		ReadRPCs: readOnlyModeRPCs,
+	}
+	return drpcserver.NewWithOptions(&tracing.DRPCHandler{Handler: handler},
 		drpcserver.Options{
 			Manager: drpcsdk.DefaultDRPCOptions(nil),
 			Log: func(err error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"strconv"
+	"strings"
 	"sync/atomic"
 
 	"github.com/google/uuid"
@@ -16,7 +17,9 @@ import (
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 )
 
 type AuditAPI struct {
@@ -62,8 +65,13 @@ func (a *AuditAPI) ReportConnection(ctx context.Context, req *agentproto.ReportC
 	type additionalFields struct {
 		audit.AdditionalFields
 
-		ConnectionType agentsdk.ConnectionType `json:"connection_type"`
-		Reason         string                  `json:"reason,omitempty"`
+		ConnectionType agentsdk.ConnectionType  `json:"connection_type"`
+		ClientType     codersdk.AuditClientType `json:"client_type"`
+		ClientVersion  string                   `json:"client_version,omitempty"`
+		Reason         string                   `json:"reason,omitempty"`
+		// DurationSeconds is how long the connection lasted, only set
+		// when disconnecting.
+		DurationSeconds float64 `json:"duration_seconds,omitempty"`
 	}
 	resourceInfo := additionalFields{
 		AdditionalFields: audit.AdditionalFields{
@@ -73,8 +81,11 @@ func (a *AuditAPI) ReportConnection(ctx context.Context, req *agentproto.ReportC
 			BuildNumber:    strconv.FormatInt(int64(build.BuildNumber), 10),
 			BuildReason:    database.BuildReason(string(build.Reason)),
 		},
-		ConnectionType: connectionType,
-		Reason:         req.GetConnection().GetReason(),
+		ConnectionType:  connectionType,
+		ClientType:      connectionClientType(req.GetConnection()),
+		ClientVersion:   req.GetConnection().GetClientVersion(),
+		Reason:          req.GetConnection().GetReason(),
+		DurationSeconds: req.GetConnection().GetDuration().AsDuration().Seconds(),
 	}
 
 	riBytes, err := json.Marshal(resourceInfo)
@@ -103,3 +114,24 @@ func (a *AuditAPI) ReportConnection(ctx context.Context, req *agentproto.ReportC
 
 	return &emptypb.Empty{}, nil
 }
+
+// connectionClientType determines the kind of client behind a connection
+// reported by an agent.
+func connectionClientType(conn *agentproto.Connection) codersdk.AuditClientType {
+	switch conn.GetType() {
+	case agentproto.Connection_VSCODE:
+		return codersdk.AuditClientTypeVSCode
+	case agentproto.Connection_JETBRAINS:
+		return codersdk.AuditClientTypeJetBrains
+	case agentproto.Connection_SSH, agentproto.Connection_TYPE_UNSPECIFIED:
+		switch {
+		case strings.HasPrefix(conn.GetClientVersion(), workspacesdk.AgentSSHClientVersion):
+			return codersdk.AuditClientTypeCLI
+		case conn.GetClientVersion() != "":
+			return codersdk.AuditClientTypeSSH
+		}
+	}
+	// Reconnecting PTY connections are proxied by coderd, which audits the
+	// client that opened the terminal separately.
+	return codersdk.AuditClientTypeUnknown
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	agentproto "github.com/coder/coder/v2/agent/proto"
@@ -21,7 +22,9 @@ import (
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbmock"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 )
 
 func TestAuditReport(t *testing.T) {
@@ -56,53 +59,78 @@ func TestAuditReport(t *testing.T) {
 		ip     string
 		status int32
 		reason string
+
+		clientVersion string
+		duration      time.Duration
+		clientType    codersdk.AuditClientType
 	}{
 		{
-			name:   "SSH Connect",
-			id:     uuid.New(),
-			action: agentproto.Connection_CONNECT.Enum(),
-			typ:    agentproto.Connection_SSH.Enum(),
-			time:   time.Now(),
-			ip:     "127.0.0.1",
-			status: 200,
+			name:          "SSH Connect",
+			id:            uuid.New(),
+			action:        agentproto.Connection_CONNECT.Enum(),
+			typ:           agentproto.Connection_SSH.Enum(),
+			time:          time.Now(),
+			ip:            "127.0.0.1",
+			status:        200,
+			clientVersion: workspacesdk.AgentSSHClientVersion,
+			clientType:    codersdk.AuditClientTypeCLI,
+		},
+		{
+			name:          "OpenSSH Connect",
+			id:            uuid.New(),
+			action:        agentproto.Connection_CONNECT.Enum(),
+			typ:           agentproto.Connection_SSH.Enum(),
+			time:          time.Now(),
+			ip:            "127.0.0.1",
+			clientVersion: "SSH-2.0-OpenSSH_9.6",
+			clientType:    codersdk.AuditClientTypeSSH,
 		},
 		{
-			name:   "VS Code Connect",
-			id:     uuid.New(),
-			action: agentproto.Connection_CONNECT.Enum(),
-			typ:    agentproto.Connection_VSCODE.Enum(),
-			time:   time.Now(),
-			ip:     "8.8.8.8",
+			name:          "VS Code Connect",
+			id:            uuid.New(),
+			action:        agentproto.Connection_CONNECT.Enum(),
+			typ:           agentproto.Connection_VSCODE.Enum(),
+			time:          time.Now(),
+			ip:            "8.8.8.8",
+			clientVersion: "SSH-2.0-OpenSSH_9.6",
+			clientType:    codersdk.AuditClientTypeVSCode,
 		},
 		{
-			name:   "JetBrains Connect",
-			id:     uuid.New(),
-			action: agentproto.Connection_CONNECT.Enum(),
-			typ:    agentproto.Connection_JETBRAINS.Enum(),
-			time:   time.Now(),
+			name:       "JetBrains Connect",
+			id:         uuid.New(),
+			action:     agentproto.Connection_CONNECT.Enum(),
+			typ:        agentproto.Connection_JETBRAINS.Enum(),
+			time:       time.Now(),
+			clientType: codersdk.AuditClientTypeJetBrains,
 		},
 		{
-			name:   "Reconnecting PTY Connect",
-			id:     uuid.New(),
-			action: agentproto.Connection_CONNECT.Enum(),
-			typ:    agentproto.Connection_RECONNECTING_PTY.Enum(),
-			time:   time.Now(),
+			name:       "Reconnecting PTY Connect",
+			id:         uuid.New(),
+			action:     agentproto.Connection_CONNECT.Enum(),
+			typ:        agentproto.Connection_RECONNECTING_PTY.Enum(),
+			time:       time.Now(),
+			clientType: codersdk.AuditClientTypeUnknown,
 		},
 		{
-			name:   "SSH Disconnect",
-			id:     uuid.New(),
-			action: agentproto.Connection_DISCONNECT.Enum(),
-			typ:    agentproto.Connection_SSH.Enum(),
-			time:   time.Now(),
+			name:          "SSH Disconnect",
+			id:            uuid.New(),
+			action:        agentproto.Connection_DISCONNECT.Enum(),
+			typ:           agentproto.Connection_SSH.Enum(),
+			time:          time.Now(),
+			clientVersion: workspacesdk.AgentSSHClientVersion,
+			duration:      90 * time.Second,
+			clientType:    codersdk.AuditClientTypeCLI,
 		},
 		{
-			name:   "SSH Disconnect",
-			id:     uuid.New(),
-			action: agentproto.Connection_DISCONNECT.Enum(),
-			typ:    agentproto.Connection_SSH.Enum(),
-			time:   time.Now(),
-			status: 500,
-			reason: "because error says so",
+			name:       "SSH Disconnect Error",
+			id:         uuid.New(),
+			action:     agentproto.Connection_DISCONNECT.Enum(),
+			typ:        agentproto.Connection_SSH.Enum(),
+			time:       time.Now(),
+			status:     500,
+			reason:     "because error says so",
+			duration:   time.Second,
+			clientType: codersdk.AuditClientTypeUnknown,
 		},
 	}
 	//nolint:paralleltest // No longer necessary to reinitialise the variable tt.
@@ -125,13 +153,15 @@ func TestAuditReport(t *testing.T) {
 			}
 			api.ReportConnection(context.Background(), &agentproto.ReportConnectionRequest{
 				Connection: &agentproto.Connection{
-					Id:         tt.id[:],
-					Action:     *tt.action,
-					Type:       *tt.typ,
-					Timestamp:  timestamppb.New(tt.time),
-					Ip:         tt.ip,
-					StatusCode: tt.status,
-					Reason:     &tt.reason,
+					Id:            tt.id[:],
+					Action:        *tt.action,
+					Type:          *tt.typ,
+					Timestamp:     timestamppb.New(tt.time),
+					Ip:            tt.ip,
+					StatusCode:    tt.status,
+					Reason:        &tt.reason,
+					ClientVersion: tt.clientVersion,
+					Duration:      durationOrNil(tt.duration),
 				},
 			})
 
@@ -156,6 +186,17 @@ func TestAuditReport(t *testing.T) {
 			if tt.reason != "" {
 				require.Equal(t, tt.reason, m["reason"])
 			}
+			require.Equal(t, string(tt.clientType), m["client_type"])
+			if tt.clientVersion != "" {
+				require.Equal(t, tt.clientVersion, m["client_version"])
+			} else {
+				require.NotContains(t, m, "client_version")
+			}
+			if tt.duration != 0 {
+				require.Equal(t, tt.duration.Seconds(), m["duration_seconds"])
+			} else {
+				require.NotContains(t, m, "duration_seconds")
+			}
 		})
 	}
 }
@@ -172,6 +213,13 @@ func agentProtoConnectionTypeToSDK(t *testing.T, typ agentproto.Connection_Type)
 	return action
 }
 
+func durationOrNil(d time.Duration) *durationpb.Duration {
+	if d == 0 {
+		return nil
+	}
+	return durationpb.New(d)
+}
+
 func asAtomicPointer[T any](v T) *atomic.Pointer[T] {
 	var p atomic.Pointer[T]
 	p.Store(&v)
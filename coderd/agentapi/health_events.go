@@ -0,0 +1,229 @@
+package agentapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/quartz"
+)
+
+// HealthEventsAPI records the conditions agents detect on their host, such
+// as running out of memory, so they can be surfaced as workspace health
+// warnings.
+type HealthEventsAPI struct {
+	AgentID     uuid.UUID
+	WorkspaceID uuid.UUID
+	AgentFn     func(context.Context) (database.WorkspaceAgent, error)
+
+	Log                      slog.Logger
+	Clock                    quartz.Clock
+	Database                 database.Store
+	NotificationsEnqueuer    notifications.Enqueuer
+	PublishWorkspaceUpdateFn func(context.Context, *database.WorkspaceAgent, wspubsub.WorkspaceEventKind) error
+}
+
+func (a *HealthEventsAPI) ReportHealthEvents(ctx context.Context, req *agentproto.ReportHealthEventsRequest) (*agentproto.ReportHealthEventsResponse, error) {
+	var (
+		now         = dbtime.Time(a.Clock.Now())
+		changed     bool
+		outOfMemory *agentproto.HealthEvent
+		outOfDisk   []*agentproto.HealthEvent
+
+		monitorsFetched  bool
+		memoryMonitored  bool
+		volumesMonitored map[string]bool
+	)
+	for _, event := range req.GetEvents() {
+		kind, err := healthEventKindFromProto(event.GetKind())
+		if err != nil {
+			return nil, err
+		}
+		if event.GetUsed() < 0 || event.GetTotal() < 0 {
+			return nil, xerrors.New("health event usage cannot be negative")
+		}
+
+		if event.GetResolved() {
+			// Agents report the state of every condition when they
+			// connect, so most of these don't resolve anything.
+			resolved, err := a.Database.ResolveWorkspaceAgentHealthEvent(ctx, database.ResolveWorkspaceAgentHealthEventParams{
+				AgentID:    a.AgentID,
+				Kind:       kind,
+				Volume:     event.GetVolume(),
+				ResolvedAt: sql.NullTime{Time: now, Valid: true},
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("resolve workspace agent health event: %w", err)
+			}
+			changed = changed || resolved > 0
+			continue
+		}
+
+		_, err = a.Database.InsertWorkspaceAgentHealthEvent(ctx, database.InsertWorkspaceAgentHealthEventParams{
+			ID:        uuid.New(),
+			AgentID:   a.AgentID,
+			Kind:      kind,
+			Volume:    event.GetVolume(),
+			Used:      event.GetUsed(),
+			Total:     event.GetTotal(),
+			CreatedAt: now,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// The condition is already ongoing.
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("insert workspace agent health event: %w", err)
+		}
+		changed = true
+
+		// Agents whose template configures resource monitors are already
+		// notified when the configured thresholds are crossed, so there is
+		// no need to notify them twice.
+		if !monitorsFetched {
+			memoryMonitored, volumesMonitored, err = a.enabledMonitors(ctx)
+			if err != nil {
+				return nil, err
+			}
+			monitorsFetched = true
+		}
+		switch kind {
+		case database.WorkspaceAgentHealthEventKindNearOutOfMemory:
+			if !memoryMonitored {
+				outOfMemory = event
+			}
+		case database.WorkspaceAgentHealthEventKindLowDiskSpace:
+			if !volumesMonitored[event.GetVolume()] {
+				outOfDisk = append(outOfDisk, event)
+			}
+		}
+	}
+
+	if changed && a.PublishWorkspaceUpdateFn != nil {
+		agent, err := a.AgentFn(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("get agent: %w", err)
+		}
+		if err := a.PublishWorkspaceUpdateFn(ctx, &agent, wspubsub.WorkspaceEventKindAgentHealthUpdate); err != nil {
+			return nil, xerrors.Errorf("publish workspace update: %w", err)
+		}
+	}
+
+	if outOfMemory != nil || len(outOfDisk) > 0 {
+		// Failing to notify should not make the agent report the events
+		// again, as they have been recorded already.
+		if err := a.notify(ctx, outOfMemory, outOfDisk); err != nil {
+			a.Log.Error(ctx, "failed to notify about workspace agent health events", slog.Error(err))
+		}
+	}
+
+	return &agentproto.ReportHealthEventsResponse{}, nil
+}
+
+// enabledMonitors returns whether the agent has an enabled memory monitor,
+// and the paths of its enabled volume monitors.
+func (a *HealthEventsAPI) enabledMonitors(ctx context.Context) (bool, map[string]bool, error) {
+	memoryMonitor, err := a.Database.FetchMemoryResourceMonitorsByAgentID(ctx, a.AgentID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, nil, xerrors.Errorf("fetch memory resource monitor: %w", err)
+	}
+	memoryMonitored := err == nil && memoryMonitor.Enabled
+
+	volumeMonitors, err := a.Database.FetchVolumesResourceMonitorsByAgentID(ctx, a.AgentID)
+	if err != nil {
+		return false, nil, xerrors.Errorf("fetch volume resource monitors: %w", err)
+	}
+	volumesMonitored := make(map[string]bool, len(volumeMonitors))
+	for _, monitor := range volumeMonitors {
+		if monitor.Enabled {
+			volumesMonitored[monitor.Path] = true
+		}
+	}
+
+	return memoryMonitored, volumesMonitored, nil
+}
+
+func (a *HealthEventsAPI) notify(ctx context.Context, outOfMemory *agentproto.HealthEvent, outOfDisk []*agentproto.HealthEvent) error {
+	workspace, err := a.Database.GetWorkspaceByID(ctx, a.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+
+	if outOfMemory != nil {
+		if _, err := a.NotificationsEnqueuer.EnqueueWithData(
+			// nolint:gocritic // We need to be able to send the notification.
+			dbauthz.AsNotifier(ctx),
+			workspace.OwnerID,
+			notifications.TemplateWorkspaceOutOfMemory,
+			map[string]string{
+				"workspace": workspace.Name,
+				"threshold": fmt.Sprintf("%d%%", outOfMemory.GetThresholdPercent()),
+			},
+			map[string]any{
+				// The timestamp circumvents the daily deduplication of
+				// notifications, see ResourcesMonitoringAPI.
+				"timestamp": a.Clock.Now(),
+			},
+			"workspace-agent-health",
+			workspace.ID,
+			workspace.OwnerID,
+			workspace.OrganizationID,
+		); err != nil {
+			return xerrors.Errorf("notify workspace OOM: %w", err)
+		}
+	}
+
+	if len(outOfDisk) > 0 {
+		volumes := make([]map[string]any, 0, len(outOfDisk))
+		for _, event := range outOfDisk {
+			volumes = append(volumes, map[string]any{
+				"path":      event.GetVolume(),
+				"threshold": fmt.Sprintf("%d%%", event.GetThresholdPercent()),
+			})
+		}
+		if _, err := a.NotificationsEnqueuer.EnqueueWithData(
+			// nolint:gocritic // We need to be able to send the notification.
+			dbauthz.AsNotifier(ctx),
+			workspace.OwnerID,
+			notifications.TemplateWorkspaceOutOfDisk,
+			map[string]string{
+				"workspace": workspace.Name,
+			},
+			map[string]any{
+				"volumes":   volumes,
+				"timestamp": a.Clock.Now(),
+			},
+			"workspace-agent-health",
+			workspace.ID,
+			workspace.OwnerID,
+			workspace.OrganizationID,
+		); err != nil {
+			return xerrors.Errorf("notify workspace OOD: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func healthEventKindFromProto(kind agentproto.HealthEvent_Kind) (database.WorkspaceAgentHealthEventKind, error) {
+	switch kind {
+	case agentproto.HealthEvent_NEAR_OUT_OF_MEMORY:
+		return database.WorkspaceAgentHealthEventKindNearOutOfMemory, nil
+	case agentproto.HealthEvent_LOW_DISK_SPACE:
+		return database.WorkspaceAgentHealthEventKindLowDiskSpace, nil
+	default:
+		return "", xerrors.Errorf("unknown health event kind %q", kind)
+	}
+}
@@ -0,0 +1,154 @@
+package agentapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestReportHealthEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db, _ := dbtestutil.NewDB(t)
+	user := dbgen.User(t, db, database.User{})
+	org := dbgen.Organization(t, db, database.Organization{})
+	template := dbgen.Template(t, db, database.Template{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	templateVersion := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		TemplateID:     uuid.NullUUID{Valid: true, UUID: template.ID},
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		TemplateID:     template.ID,
+		OwnerID:        user.ID,
+	})
+	job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		Type: database.ProvisionerJobTypeWorkspaceBuild,
+	})
+	build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+		JobID:             job.ID,
+		WorkspaceID:       workspace.ID,
+		TemplateVersionID: templateVersion.ID,
+	})
+	resource := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{
+		JobID: build.JobID,
+	})
+	agent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{
+		ResourceID: resource.ID,
+	})
+	// The template monitors this volume already, so low disk space on it
+	// must not be notified twice.
+	dbgen.WorkspaceAgentVolumeResourceMonitor(t, db, database.WorkspaceAgentVolumeResourceMonitor{
+		AgentID: agent.ID,
+		Path:    "/var/lib/docker",
+	})
+
+	notifyEnq := &notificationstest.FakeEnqueuer{}
+	publishes := 0
+	api := &agentapi.HealthEventsAPI{
+		AgentID:     agent.ID,
+		WorkspaceID: workspace.ID,
+		AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+			return agent, nil
+		},
+		Log:                   slogtest.Make(t, nil),
+		Clock:                 quartz.NewMock(t),
+		Database:              db,
+		NotificationsEnqueuer: notifyEnq,
+		PublishWorkspaceUpdateFn: func(_ context.Context, _ *database.WorkspaceAgent, kind wspubsub.WorkspaceEventKind) error {
+			require.Equal(t, wspubsub.WorkspaceEventKindAgentHealthUpdate, kind)
+			publishes++
+			return nil
+		},
+	}
+
+	report := func(events ...*agentproto.HealthEvent) {
+		t.Helper()
+		_, err := api.ReportHealthEvents(ctx, &agentproto.ReportHealthEventsRequest{Events: events})
+		require.NoError(t, err)
+	}
+	unresolved := func() []database.WorkspaceAgentHealthEvent {
+		t.Helper()
+		events, err := db.GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx, []uuid.UUID{agent.ID})
+		require.NoError(t, err)
+		return events
+	}
+
+	// Agents report their conditions as resolved when they connect.
+	report(
+		&agentproto.HealthEvent{Kind: agentproto.HealthEvent_NEAR_OUT_OF_MEMORY, Resolved: true},
+		&agentproto.HealthEvent{Kind: agentproto.HealthEvent_LOW_DISK_SPACE, Volume: "/home/coder", Resolved: true},
+	)
+	require.Empty(t, unresolved())
+	require.Zero(t, publishes)
+
+	report(&agentproto.HealthEvent{
+		Kind:             agentproto.HealthEvent_NEAR_OUT_OF_MEMORY,
+		Used:             97,
+		Total:            100,
+		ThresholdPercent: 95,
+	})
+	events := unresolved()
+	require.Len(t, events, 1)
+	require.Equal(t, database.WorkspaceAgentHealthEventKindNearOutOfMemory, events[0].Kind)
+	require.EqualValues(t, 97, events[0].Used)
+	require.EqualValues(t, 100, events[0].Total)
+	require.Equal(t, 1, publishes)
+	sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceOutOfMemory))
+	require.Len(t, sent, 1)
+	require.Equal(t, user.ID, sent[0].UserID)
+	require.Equal(t, "95%", sent[0].Labels["threshold"])
+
+	// Reporting an ongoing condition again doesn't notify again.
+	report(&agentproto.HealthEvent{
+		Kind:             agentproto.HealthEvent_NEAR_OUT_OF_MEMORY,
+		Used:             99,
+		Total:            100,
+		ThresholdPercent: 95,
+	})
+	require.Len(t, unresolved(), 1)
+	require.Equal(t, 1, publishes)
+	require.Len(t, notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceOutOfMemory)), 1)
+
+	report(
+		&agentproto.HealthEvent{Kind: agentproto.HealthEvent_LOW_DISK_SPACE, Volume: "/home/coder", Used: 96, Total: 100, ThresholdPercent: 95},
+		&agentproto.HealthEvent{Kind: agentproto.HealthEvent_LOW_DISK_SPACE, Volume: "/var/lib/docker", Used: 98, Total: 100, ThresholdPercent: 95},
+	)
+	require.Len(t, unresolved(), 3)
+	sent = notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceOutOfDisk))
+	require.Len(t, sent, 1)
+	require.Equal(t, []map[string]any{{"path": "/home/coder", "threshold": "95%"}}, sent[0].Data["volumes"])
+
+	report(&agentproto.HealthEvent{Kind: agentproto.HealthEvent_NEAR_OUT_OF_MEMORY, Resolved: true})
+	events = unresolved()
+	require.Len(t, events, 2)
+	for _, event := range events {
+		require.Equal(t, database.WorkspaceAgentHealthEventKindLowDiskSpace, event.Kind)
+	}
+	require.Equal(t, 3, publishes)
+
+	_, err := api.ReportHealthEvents(ctx, &agentproto.ReportHealthEventsRequest{
+		Events: []*agentproto.HealthEvent{{Kind: agentproto.HealthEvent_KIND_UNSPECIFIED}},
+	})
+	require.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/url"
 	"strings"
 	"time"
@@ -27,9 +28,11 @@ import (
 type ManifestAPI struct {
 	AccessURL                *url.URL
 	AppHostname              string
+	PathAppsOnly             bool
 	ExternalAuthConfigs      []*externalauth.Config
 	DisableDirectConnections bool
 	DerpForceWebSockets      bool
+	SessionRecordingEnabled  bool
 	WorkspaceID              uuid.UUID
 
 	AgentFn   func(context.Context) (database.WorkspaceAgent, error)
@@ -96,7 +99,7 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		Username:      workspace.OwnerUsername,
 	}
 
-	vscodeProxyURI := vscodeProxyURI(appSlug, a.AccessURL, a.AppHostname)
+	vscodeProxyURI := vscodeProxyURI(appSlug, a.AccessURL, a.AppHostname, a.PathAppsOnly)
 
 	envs, err := db2sdk.WorkspaceAgentEnvironment(workspaceAgent)
 	if err != nil {
@@ -134,6 +137,7 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		DisableDirectConnections: a.DisableDirectConnections,
 		DerpForceWebsockets:      a.DerpForceWebSockets,
 		ParentId:                 parentID,
+		SessionRecordingEnabled:  a.SessionRecordingEnabled,
 
 		DerpMap:       tailnet.DERPMapToProto(a.DerpMapFn()),
 		Scripts:       dbAgentScriptsToProto(scripts),
@@ -143,11 +147,15 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 	}, nil
 }
 
-func vscodeProxyURI(app appurl.ApplicationURL, accessURL *url.URL, appHost string) string {
-	// Proxying by port only works for subdomains. If subdomain support is not
-	// available, return an empty string.
+func vscodeProxyURI(app appurl.ApplicationURL, accessURL *url.URL, appHost string, pathAppsOnly bool) string {
 	if appHost == "" {
-		return ""
+		// Ports are only proxied on paths if the deployment serves all apps
+		// on paths. Otherwise, proxying by port requires subdomains.
+		if !pathAppsOnly {
+			return ""
+		}
+		return strings.TrimSuffix(accessURL.String(), "/") +
+			fmt.Sprintf("/@%s/%s.%s/apps/%s/", app.Username, app.WorkspaceName, app.AgentName, app.AppSlugOrPort)
 	}
 
 	// This will handle the ports from the accessURL or appHost.
@@ -191,6 +199,7 @@ func dbAgentScriptToProto(script database.WorkspaceAgentScript) *agentproto.Work
 		Cron:             script.Cron,
 		RunOnStart:       script.RunOnStart,
 		RunOnStop:        script.RunOnStop,
+		RunOnClaim:       script.RunOnClaim,
 		StartBlocksLogin: script.StartBlocksLogin,
 		Timeout:          durationpb.New(time.Duration(script.TimeoutSeconds) * time.Second),
 	}
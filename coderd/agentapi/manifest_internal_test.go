@@ -32,6 +32,7 @@ func Test_vscodeProxyURI(t *testing.T) {
 		App         appurl.ApplicationURL
 		AccessURL   *url.URL
 		AppHostname string
+		PathApps    bool
 		Expected    string
 	}{
 		{
@@ -48,6 +49,22 @@ func Test_vscodeProxyURI(t *testing.T) {
 			App:         basicApp,
 			Expected:    "",
 		},
+		{
+			Name:        "PathAppsOnly",
+			AccessURL:   coderAccessURL,
+			AppHostname: "",
+			PathApps:    true,
+			App:         basicApp,
+			Expected:    "https://coder.com/@user/workspace.agent/apps/slug/",
+		},
+		{
+			Name:        "PathAppsOnlyAccessURLPort",
+			AccessURL:   accessURLWithPort,
+			AppHostname: "",
+			PathApps:    true,
+			App:         basicApp,
+			Expected:    "https://coder.com:8080/@user/workspace.agent/apps/slug/",
+		},
 		{
 			Name:        "Hostname",
 			AccessURL:   coderAccessURL,
@@ -85,7 +102,7 @@ func Test_vscodeProxyURI(t *testing.T) {
 
 			require.NotNilf(t, c.AccessURL, "AccessURL is required")
 
-			output := vscodeProxyURI(c.App, c.AccessURL, c.AppHostname)
+			output := vscodeProxyURI(c.App, c.AccessURL, c.AppHostname, c.PathApps)
 			require.Equal(t, c.Expected, output)
 		})
 	}
@@ -137,6 +137,7 @@ func TestGetManifest(t *testing.T) {
 				StartBlocksLogin: false,
 				RunOnStart:       false,
 				RunOnStop:        true,
+				RunOnClaim:       true,
 				TimeoutSeconds:   30,
 			},
 		}
@@ -270,6 +271,7 @@ func TestGetManifest(t *testing.T) {
 				Cron:             scripts[1].Cron,
 				RunOnStart:       scripts[1].RunOnStart,
 				RunOnStop:        scripts[1].RunOnStop,
+				RunOnClaim:       scripts[1].RunOnClaim,
 				StartBlocksLogin: scripts[1].StartBlocksLogin,
 				Timeout:          durationpb.New(time.Duration(scripts[1].TimeoutSeconds) * time.Second),
 			},
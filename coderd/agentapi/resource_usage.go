@@ -0,0 +1,54 @@
+package agentapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/quartz"
+)
+
+// ResourceUsageBucket is the interval that the resource usage reported by
+// agents is downsampled to.
+const ResourceUsageBucket = 15 * time.Minute
+
+type ResourceUsageAPI struct {
+	AgentID  uuid.UUID
+	Database database.Store
+	Clock    quartz.Clock
+}
+
+func (a *ResourceUsageAPI) PushResourceUsage(ctx context.Context, req *agentproto.PushResourceUsageRequest) (*agentproto.PushResourceUsageResponse, error) {
+	if req.GetCpuUsed() < 0 || req.GetMemoryUsed() < 0 || req.GetDiskUsed() < 0 {
+		return nil, xerrors.New("resource usage cannot be negative")
+	}
+
+	// Samples are stored in the bucket they were collected in, unless the
+	// agent's clock is ahead of ours.
+	now := dbtime.Time(a.Clock.Now())
+	collectedAt := now
+	if req.GetCollectedAt().IsValid() && req.GetCollectedAt().AsTime().Before(now) {
+		collectedAt = dbtime.Time(req.GetCollectedAt().AsTime())
+	}
+
+	err := a.Database.UpsertWorkspaceAgentResourceUsage(ctx, database.UpsertWorkspaceAgentResourceUsageParams{
+		AgentID:     a.AgentID,
+		BucketStart: collectedAt.Truncate(ResourceUsageBucket),
+		CPUUsed:     req.GetCpuUsed(),
+		CPUTotal:    req.GetCpuTotal(),
+		MemoryUsed:  req.GetMemoryUsed(),
+		MemoryTotal: req.GetMemoryTotal(),
+		DiskUsed:    req.GetDiskUsed(),
+		DiskTotal:   req.GetDiskTotal(),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("upsert workspace agent resource usage: %w", err)
+	}
+
+	return &agentproto.PushResourceUsageResponse{}, nil
+}
@@ -0,0 +1,98 @@
+package agentapi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/quartz"
+)
+
+func TestPushResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 12, 20, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		collectedAt  *timestamppb.Timestamp
+		expectBucket time.Time
+	}{
+		{
+			name:         "CollectedAt",
+			collectedAt:  timestamppb.New(now.Add(-10 * time.Minute)),
+			expectBucket: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "NoCollectedAt",
+			expectBucket: time.Date(2025, 6, 1, 12, 15, 0, 0, time.UTC),
+		},
+		{
+			name:         "CollectedInFuture",
+			collectedAt:  timestamppb.New(now.Add(time.Hour)),
+			expectBucket: time.Date(2025, 6, 1, 12, 15, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			agentID := uuid.New()
+			clock := quartz.NewMock(t)
+			clock.Set(now)
+			db := dbmock.NewMockStore(gomock.NewController(t))
+			api := &agentapi.ResourceUsageAPI{
+				AgentID:  agentID,
+				Database: db,
+				Clock:    clock,
+			}
+
+			db.EXPECT().UpsertWorkspaceAgentResourceUsage(gomock.Any(), database.UpsertWorkspaceAgentResourceUsageParams{
+				AgentID:     agentID,
+				BucketStart: tt.expectBucket,
+				CPUUsed:     1.5,
+				CPUTotal:    4,
+				MemoryUsed:  1 << 30,
+				MemoryTotal: 8 << 30,
+				DiskUsed:    10 << 30,
+				DiskTotal:   100 << 30,
+			}).Return(nil)
+
+			_, err := api.PushResourceUsage(context.Background(), &agentproto.PushResourceUsageRequest{
+				CollectedAt: tt.collectedAt,
+				CpuUsed:     1.5,
+				CpuTotal:    4,
+				MemoryUsed:  1 << 30,
+				MemoryTotal: 8 << 30,
+				DiskUsed:    10 << 30,
+				DiskTotal:   100 << 30,
+			})
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("Negative", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmock.NewMockStore(gomock.NewController(t))
+		api := &agentapi.ResourceUsageAPI{
+			AgentID:  uuid.New(),
+			Database: db,
+			Clock:    quartz.NewMock(t),
+		}
+
+		_, err := api.PushResourceUsage(context.Background(), &agentproto.PushResourceUsageRequest{
+			CpuUsed: -1,
+		})
+		require.ErrorContains(t, err, "cannot be negative")
+	})
+}
@@ -0,0 +1,155 @@
+package agentapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+// MaxSessionRecordingAppendBytes is the most output an agent can append to a
+// recording in a single request.
+const MaxSessionRecordingAppendBytes = 1 << 20
+
+// SessionRecordingAPI stores the output of the terminal sessions agents
+// record when session recording is enabled for the deployment.
+type SessionRecordingAPI struct {
+	AgentID     uuid.UUID
+	WorkspaceID uuid.UUID
+	Enabled     bool
+
+	Log      slog.Logger
+	Database database.Store
+}
+
+func (a *SessionRecordingAPI) CreateSessionRecording(ctx context.Context, req *agentproto.CreateSessionRecordingRequest) (*agentproto.CreateSessionRecordingResponse, error) {
+	if !a.Enabled {
+		return nil, xerrors.New("session recording is disabled")
+	}
+	id, err := uuid.FromBytes(req.GetId())
+	if err != nil {
+		return nil, xerrors.Errorf("parse session recording id: %w", err)
+	}
+	var recordingType database.WorkspaceSessionRecordingType
+	switch req.GetType() {
+	case agentproto.CreateSessionRecordingRequest_SSH:
+		recordingType = database.WorkspaceSessionRecordingTypeSsh
+	case agentproto.CreateSessionRecordingRequest_RECONNECTING_PTY:
+		recordingType = database.WorkspaceSessionRecordingTypeReconnectingPty
+	default:
+		return nil, xerrors.Errorf("unknown session recording type %q", req.GetType())
+	}
+	if req.GetWidth() < 0 || req.GetHeight() < 0 {
+		return nil, xerrors.New("terminal size cannot be negative")
+	}
+	if !req.GetStartedAt().IsValid() {
+		return nil, xerrors.New("session recording start time is required")
+	}
+
+	_, err = a.Database.InsertWorkspaceSessionRecording(ctx, database.InsertWorkspaceSessionRecordingParams{
+		ID:          id,
+		WorkspaceID: a.WorkspaceID,
+		AgentID:     a.AgentID,
+		Type:        recordingType,
+		Width:       req.GetWidth(),
+		Height:      req.GetHeight(),
+		StartedAt:   dbtime.Time(req.GetStartedAt().AsTime()),
+	})
+	if database.IsUniqueViolation(err, database.UniqueWorkspaceSessionRecordingsPkey) {
+		// The agent retries requests that fail while it reconnects, so the
+		// recording may have been created already.
+		if _, err := a.recording(ctx, id); err != nil {
+			return nil, err
+		}
+		return &agentproto.CreateSessionRecordingResponse{}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("insert workspace session recording: %w", err)
+	}
+	return &agentproto.CreateSessionRecordingResponse{}, nil
+}
+
+func (a *SessionRecordingAPI) AppendSessionRecording(ctx context.Context, req *agentproto.AppendSessionRecordingRequest) (*agentproto.AppendSessionRecordingResponse, error) {
+	if !a.Enabled {
+		return nil, xerrors.New("session recording is disabled")
+	}
+	id, err := uuid.FromBytes(req.GetId())
+	if err != nil {
+		return nil, xerrors.Errorf("parse session recording id: %w", err)
+	}
+	var size int64
+	for _, chunk := range req.GetChunks() {
+		if !chunk.GetCreatedAt().IsValid() {
+			return nil, xerrors.New("session recording chunk time is required")
+		}
+		size += int64(len(chunk.GetData()))
+	}
+	if size > MaxSessionRecordingAppendBytes {
+		return nil, xerrors.Errorf("cannot append more than %d bytes to a session recording at once", MaxSessionRecordingAppendBytes)
+	}
+
+	recording, err := a.recording(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if recording.EndedAt.Valid {
+		return nil, xerrors.Errorf("session recording %s has ended", id)
+	}
+
+	var endedAt sql.NullTime
+	if req.EndedAt != nil {
+		endedAt = sql.NullTime{Time: dbtime.Time(req.GetEndedAt().AsTime()), Valid: true}
+	}
+	err = a.Database.InTx(func(tx database.Store) error {
+		for _, chunk := range req.GetChunks() {
+			if len(chunk.GetData()) == 0 {
+				continue
+			}
+			_, err := tx.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+				RecordingID: id,
+				CreatedAt:   dbtime.Time(chunk.GetCreatedAt().AsTime()),
+				Data:        chunk.GetData(),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert workspace session recording chunk: %w", err)
+			}
+		}
+		_, err := tx.UpdateWorkspaceSessionRecording(ctx, database.UpdateWorkspaceSessionRecordingParams{
+			ID:         id,
+			AddedBytes: size,
+			EndedAt:    endedAt,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace session recording: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &agentproto.AppendSessionRecordingResponse{}, nil
+}
+
+// recording returns the recording with the given ID if it was created by the
+// agent.
+func (a *SessionRecordingAPI) recording(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	// nolint:gocritic // Agents cannot read recordings, as playing them back
+	// requires access to the audit logs.
+	recording, err := a.Database.GetWorkspaceSessionRecordingByID(dbauthz.AsSystemRestricted(ctx), id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && recording.AgentID != a.AgentID) {
+		return database.WorkspaceSessionRecording{}, xerrors.Errorf("session recording %s not found", id)
+	}
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, xerrors.Errorf("get workspace session recording: %w", err)
+	}
+	return recording, nil
+}
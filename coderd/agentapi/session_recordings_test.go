@@ -0,0 +1,208 @@
+package agentapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (database.Store, database.WorkspaceTable, database.WorkspaceAgent) {
+		t.Helper()
+
+		db, _ := dbtestutil.NewDB(t)
+		user := dbgen.User(t, db, database.User{})
+		org := dbgen.Organization(t, db, database.Organization{})
+		template := dbgen.Template(t, db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		templateVersion := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{Valid: true, UUID: template.ID},
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			TemplateID:     template.ID,
+			OwnerID:        user.ID,
+		})
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			JobID:             job.ID,
+			WorkspaceID:       workspace.ID,
+			TemplateVersionID: templateVersion.ID,
+		})
+		resource := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{
+			JobID: build.JobID,
+		})
+		agent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{
+			ResourceID: resource.ID,
+		})
+		return db, workspace, agent
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, workspace, agent := setup(t)
+		api := &agentapi.SessionRecordingAPI{
+			AgentID:     agent.ID,
+			WorkspaceID: workspace.ID,
+			Enabled:     true,
+			Log:         slogtest.Make(t, nil),
+			Database:    db,
+		}
+
+		id := uuid.New()
+		startedAt := dbtime.Now().Add(-time.Minute)
+		create := &agentproto.CreateSessionRecordingRequest{
+			Id:        id[:],
+			Type:      agentproto.CreateSessionRecordingRequest_RECONNECTING_PTY,
+			StartedAt: timestamppb.New(startedAt),
+			Width:     120,
+			Height:    40,
+		}
+		_, err := api.CreateSessionRecording(ctx, create)
+		require.NoError(t, err)
+		// Creating the recording again succeeds, as the agent retries requests
+		// that may have succeeded.
+		_, err = api.CreateSessionRecording(ctx, create)
+		require.NoError(t, err)
+
+		_, err = api.AppendSessionRecording(ctx, &agentproto.AppendSessionRecordingRequest{
+			Id: id[:],
+			Chunks: []*agentproto.AppendSessionRecordingRequest_Chunk{{
+				CreatedAt: timestamppb.New(startedAt.Add(time.Second)),
+				Data:      []byte("hello "),
+			}, {
+				CreatedAt: timestamppb.New(startedAt.Add(2 * time.Second)),
+				Data:      []byte("world"),
+			}},
+			EndedAt: timestamppb.New(startedAt.Add(3 * time.Second)),
+		})
+		require.NoError(t, err)
+
+		recording, err := db.GetWorkspaceSessionRecordingByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, workspace.ID, recording.WorkspaceID)
+		require.Equal(t, agent.ID, recording.AgentID)
+		require.Equal(t, database.WorkspaceSessionRecordingTypeReconnectingPty, recording.Type)
+		require.EqualValues(t, 120, recording.Width)
+		require.EqualValues(t, 40, recording.Height)
+		require.EqualValues(t, len("hello world"), recording.SizeBytes)
+		require.True(t, recording.EndedAt.Valid)
+		require.WithinDuration(t, startedAt.Add(3*time.Second), recording.EndedAt.Time, time.Microsecond)
+
+		chunks, err := db.GetWorkspaceSessionRecordingChunks(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, chunks, 2)
+		require.Equal(t, "hello ", string(chunks[0].Data))
+		require.Equal(t, "world", string(chunks[1].Data))
+
+		// Ended recordings can't be appended to.
+		_, err = api.AppendSessionRecording(ctx, &agentproto.AppendSessionRecordingRequest{
+			Id: id[:],
+			Chunks: []*agentproto.AppendSessionRecordingRequest_Chunk{{
+				CreatedAt: timestamppb.New(startedAt.Add(4 * time.Second)),
+				Data:      []byte("!"),
+			}},
+		})
+		require.ErrorContains(t, err, "has ended")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, workspace, agent := setup(t)
+		api := &agentapi.SessionRecordingAPI{
+			AgentID:     agent.ID,
+			WorkspaceID: workspace.ID,
+			Log:         slogtest.Make(t, nil),
+			Database:    db,
+		}
+
+		id := uuid.New()
+		_, err := api.CreateSessionRecording(ctx, &agentproto.CreateSessionRecordingRequest{
+			Id:        id[:],
+			Type:      agentproto.CreateSessionRecordingRequest_SSH,
+			StartedAt: timestamppb.Now(),
+		})
+		require.ErrorContains(t, err, "disabled")
+		_, err = api.AppendSessionRecording(ctx, &agentproto.AppendSessionRecordingRequest{Id: id[:]})
+		require.ErrorContains(t, err, "disabled")
+	})
+
+	t.Run("OtherAgent", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, workspace, agent := setup(t)
+		recording := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+			WorkspaceID: workspace.ID,
+			AgentID:     agent.ID,
+		})
+		api := &agentapi.SessionRecordingAPI{
+			AgentID:     uuid.New(),
+			WorkspaceID: workspace.ID,
+			Enabled:     true,
+			Log:         slogtest.Make(t, nil),
+			Database:    db,
+		}
+
+		_, err := api.AppendSessionRecording(ctx, &agentproto.AppendSessionRecordingRequest{
+			Id: recording.ID[:],
+			Chunks: []*agentproto.AppendSessionRecordingRequest_Chunk{{
+				CreatedAt: timestamppb.Now(),
+				Data:      []byte("hello"),
+			}},
+		})
+		require.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, workspace, agent := setup(t)
+		recording := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+			WorkspaceID: workspace.ID,
+			AgentID:     agent.ID,
+		})
+		api := &agentapi.SessionRecordingAPI{
+			AgentID:     agent.ID,
+			WorkspaceID: workspace.ID,
+			Enabled:     true,
+			Log:         slogtest.Make(t, nil),
+			Database:    db,
+		}
+
+		_, err := api.AppendSessionRecording(ctx, &agentproto.AppendSessionRecordingRequest{
+			Id: recording.ID[:],
+			Chunks: []*agentproto.AppendSessionRecordingRequest_Chunk{{
+				CreatedAt: timestamppb.Now(),
+				Data:      make([]byte, agentapi.MaxSessionRecordingAppendBytes+1),
+			}},
+		})
+		require.ErrorContains(t, err, "cannot append")
+	})
+}
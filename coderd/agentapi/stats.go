@@ -2,6 +2,7 @@ package agentapi
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -80,5 +81,32 @@ func (a *StatsAPI) UpdateStats(ctx context.Context, req *agentproto.UpdateStatsR
 		return nil, xerrors.Errorf("report agent stats: %w", err)
 	}
 
+	now := a.now()
+	for _, quality := range req.Stats.GetConnectionQuality() {
+		params := database.UpsertWorkspaceAgentConnectionQualityParams{
+			AgentID:        workspaceAgent.ID,
+			Ip:             quality.GetIp(),
+			P2P:            quality.GetP2P(),
+			DerpRegionCode: quality.GetDerpRegionCode(),
+			PingsSent:      quality.GetPingsSent(),
+			PingsLost:      quality.GetPingsLost(),
+			UpdatedAt:      now,
+		}
+		if quality.GetLatency() != nil {
+			params.LatencyMS = sql.NullFloat64{
+				Float64: float64(quality.GetLatency().AsDuration().Microseconds()) / 1000,
+				Valid:   true,
+			}
+		}
+		if quality.GetLastHandshake() != nil {
+			params.LastHandshakeAt = sql.NullTime{Time: quality.GetLastHandshake().AsTime(), Valid: true}
+		}
+		// Connection quality is only used for diagnostics, so failing to
+		// store it doesn't fail the stats report.
+		if err := a.Database.UpsertWorkspaceAgentConnectionQuality(ctx, params); err != nil {
+			a.Log.Warn(ctx, "failed to upsert workspace agent connection quality", slog.F("ip", params.Ip), slog.Error(err))
+		}
+	}
+
 	return res, nil
 }
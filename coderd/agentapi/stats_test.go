@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/agentapi"
@@ -248,6 +249,95 @@ func TestUpdateStates(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("ConnectionQuality", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			now                   = dbtime.Now()
+			handshake             = now.Add(-time.Minute)
+			dbM                   = dbmock.NewMockStore(gomock.NewController(t))
+			ps                    = pubsub.NewInMemory()
+			templateScheduleStore = schedule.MockTemplateScheduleStore{
+				GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					panic("should not be called")
+				},
+				SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
+					panic("not implemented")
+				},
+			}
+			batcher = &workspacestatstest.StatsBatcher{}
+
+			req = &agentproto.UpdateStatsRequest{
+				Stats: &agentproto.Stats{
+					ConnectionsByProto: map[string]int64{},
+					ConnectionQuality: []*agentproto.Stats_ConnectionQuality{
+						{
+							Ip:             "fd7a:115c:a1e0::1",
+							P2P:            false,
+							DerpRegionCode: "coder",
+							Latency:        durationpb.New(42500 * time.Microsecond),
+							PingsSent:      10,
+							PingsLost:      1,
+							LastHandshake:  timestamppb.New(handshake),
+						},
+						{
+							Ip:        "fd7a:115c:a1e0::2",
+							P2P:       true,
+							PingsSent: 1,
+							PingsLost: 1,
+						},
+					},
+				},
+			}
+		)
+		api := agentapi.StatsAPI{
+			AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			Database: dbM,
+			StatsReporter: workspacestats.NewReporter(workspacestats.ReporterOptions{
+				Database:              dbM,
+				Pubsub:                ps,
+				UsageTracker:          workspacestats.NewTracker(dbM),
+				StatsBatcher:          batcher,
+				TemplateScheduleStore: templateScheduleStorePtr(templateScheduleStore),
+				// Ignored when nil.
+				UpdateAgentMetricsFn: nil,
+			}),
+			AgentStatsRefreshInterval: 10 * time.Second,
+			TimeNowFn: func() time.Time {
+				return now
+			},
+		}
+
+		// Workspace gets fetched.
+		dbM.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agent.ID).Return(workspace, nil)
+
+		// Connection quality is stored for each peer.
+		dbM.EXPECT().UpsertWorkspaceAgentConnectionQuality(gomock.Any(), database.UpsertWorkspaceAgentConnectionQualityParams{
+			AgentID:         agent.ID,
+			Ip:              "fd7a:115c:a1e0::1",
+			P2P:             false,
+			DerpRegionCode:  "coder",
+			LatencyMS:       sql.NullFloat64{Float64: 42.5, Valid: true},
+			PingsSent:       10,
+			PingsLost:       1,
+			LastHandshakeAt: sql.NullTime{Time: handshake, Valid: true},
+			UpdatedAt:       now,
+		}).Return(nil)
+		dbM.EXPECT().UpsertWorkspaceAgentConnectionQuality(gomock.Any(), database.UpsertWorkspaceAgentConnectionQualityParams{
+			AgentID:   agent.ID,
+			Ip:        "fd7a:115c:a1e0::2",
+			P2P:       true,
+			PingsSent: 1,
+			PingsLost: 1,
+			UpdatedAt: now,
+		}).Return(nil)
+
+		_, err := api.UpdateStats(context.Background(), req)
+		require.NoError(t, err)
+	})
+
 	t.Run("NoStats", func(t *testing.T) {
 		t.Parallel()
 
@@ -56,58 +56,13 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	scope := database.APIKeyScopeAll
-	if scope != "" {
-		scope = database.APIKeyScope(createToken.Scope)
-	}
-
-	tokenName := namesgenerator.GetRandomName(1)
-
-	if len(createToken.TokenName) != 0 {
-		tokenName = createToken.TokenName
-	}
-
-	params := apikey.CreateParams{
-		UserID:          user.ID,
-		LoginType:       database.LoginTypeToken,
-		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
-		Scope:           scope,
-		TokenName:       tokenName,
-	}
-
-	if createToken.Lifetime != 0 {
-		err := api.validateAPIKeyLifetime(ctx, user.ID, createToken.Lifetime)
-		if err != nil {
-			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-				Message: "Failed to validate create API key request.",
-				Detail:  err.Error(),
-			})
-			return
-		}
-		params.ExpiresAt = dbtime.Now().Add(createToken.Lifetime)
-		params.LifetimeSeconds = int64(createToken.Lifetime.Seconds())
-	}
-
-	cookie, key, err := api.createAPIKey(ctx, params)
-	if err != nil {
-		if database.IsUniqueViolation(err, database.UniqueIndexAPIKeyName) {
-			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
-				Message: fmt.Sprintf("A token with name %q already exists.", tokenName),
-				Validations: []codersdk.ValidationError{{
-					Field:  "name",
-					Detail: "This value is already in use and should be unique.",
-				}},
-			})
-			return
-		}
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to create API key.",
-			Detail:  err.Error(),
-		})
+	boundIdentity := httpmw.APIKeyBoundIdentity(codersdk.SessionTokenBinding(api.DeploymentValues.Sessions.TokenBinding), r)
+	token, key, ok := api.createToken(ctx, rw, user.ID, createToken, boundIdentity)
+	if !ok {
 		return
 	}
-	aReq.New = *key
-	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: cookie.Value})
+	aReq.New = key
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: token})
 }
 
 // Creates a new session key, used for logging in via the CLI.
@@ -129,6 +84,7 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
 		LoginType:       database.LoginTypePassword,
 		RemoteAddr:      r.RemoteAddr,
+		BoundIdentity:   httpmw.APIKeyBoundIdentity(codersdk.SessionTokenBinding(api.DeploymentValues.Sessions.TokenBinding), r),
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -357,6 +313,74 @@ func (api *API) tokenConfig(rw http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// createToken validates a token creation request and creates a token for the
+// given user, bound to boundIdentity if it is set. If ok is false, an error
+// response has already been written.
+func (api *API) createToken(ctx context.Context, rw http.ResponseWriter, userID uuid.UUID, createToken codersdk.CreateTokenRequest, boundIdentity string) (token string, key database.APIKey, ok bool) {
+	scope := database.APIKeyScopeAll
+	if createToken.Scope != "" {
+		scope = database.APIKeyScope(createToken.Scope)
+	}
+	if !scope.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to validate create API key request.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "scope",
+				Detail: fmt.Sprintf("%q is not a valid API key scope.", createToken.Scope),
+			}},
+		})
+		return "", database.APIKey{}, false
+	}
+
+	tokenName := namesgenerator.GetRandomName(1)
+
+	if len(createToken.TokenName) != 0 {
+		tokenName = createToken.TokenName
+	}
+
+	params := apikey.CreateParams{
+		UserID:          userID,
+		LoginType:       database.LoginTypeToken,
+		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
+		Scope:           scope,
+		TokenName:       tokenName,
+		BoundIdentity:   boundIdentity,
+	}
+
+	if createToken.Lifetime != 0 {
+		err := api.validateAPIKeyLifetime(ctx, userID, createToken.Lifetime)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Failed to validate create API key request.",
+				Detail:  err.Error(),
+			})
+			return "", database.APIKey{}, false
+		}
+		params.ExpiresAt = dbtime.Now().Add(createToken.Lifetime)
+		params.LifetimeSeconds = int64(createToken.Lifetime.Seconds())
+	}
+
+	cookie, newKey, err := api.createAPIKey(ctx, params)
+	if err != nil {
+		if database.IsUniqueViolation(err, database.UniqueIndexAPIKeyName) {
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("A token with name %q already exists.", tokenName),
+				Validations: []codersdk.ValidationError{{
+					Field:  "name",
+					Detail: "This value is already in use and should be unique.",
+				}},
+			})
+			return "", database.APIKey{}, false
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create API key.",
+			Detail:  err.Error(),
+		})
+		return "", database.APIKey{}, false
+	}
+	return cookie.Value, *newKey, true
+}
+
 func (api *API) validateAPIKeyLifetime(ctx context.Context, userID uuid.UUID, lifetime time.Duration) error {
 	if lifetime <= 0 {
 		return xerrors.New("lifetime must be positive number greater than 0")
@@ -424,3 +448,28 @@ func (api *API) createAPIKey(ctx context.Context, params apikey.CreateParams) (*
 		HttpOnly: true,
 	}), &newkey, nil
 }
+
+// browserAPIKeyBoundIdentity returns the identity API keys issued to a browser
+// signing in with r are bound to. Browsers can't send a device ID, so in
+// device mode one is generated the first time they sign in. The returned
+// cookie stores it and must be set on the response if it is not nil.
+func (api *API) browserAPIKeyBoundIdentity(r *http.Request) (string, *http.Cookie) {
+	mode := codersdk.SessionTokenBinding(api.DeploymentValues.Sessions.TokenBinding)
+	if identity := httpmw.APIKeyBoundIdentity(mode, r); identity != "" || mode != codersdk.SessionTokenBindingDevice {
+		return identity, nil
+	}
+
+	cookie := api.DeploymentValues.HTTPCookies.Apply(&http.Cookie{
+		Name:     codersdk.DeviceIDCookie,
+		Value:    uuid.NewString(),
+		Path:     "/",
+		MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+		HttpOnly: true,
+	})
+	// Compute the identity the same way the middleware will once the browser
+	// sends the cookie back.
+	withCookie := r.Clone(r.Context())
+	withCookie.Header = r.Header.Clone()
+	withCookie.AddCookie(cookie)
+	return httpmw.APIKeyBoundIdentity(mode, withCookie), cookie
+}
@@ -28,6 +28,9 @@ type CreateParams struct {
 	Scope           database.APIKeyScope
 	TokenName       string
 	RemoteAddr      string
+	// BoundIdentity is the identity of the client the key is bound to. See
+	// httpmw.APIKeyBoundIdentity.
+	BoundIdentity string
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -66,9 +69,7 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 	if params.Scope != "" {
 		scope = params.Scope
 	}
-	switch scope {
-	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
-	default:
+	if !scope.Valid() {
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
 
@@ -87,13 +88,14 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 			Valid: true,
 		},
 		// Make sure in UTC time for common time zone
-		ExpiresAt:    params.ExpiresAt.UTC(),
-		CreatedAt:    dbtime.Now(),
-		UpdatedAt:    dbtime.Now(),
-		HashedSecret: hashed[:],
-		LoginType:    params.LoginType,
-		Scope:        scope,
-		TokenName:    params.TokenName,
+		ExpiresAt:     params.ExpiresAt.UTC(),
+		CreatedAt:     dbtime.Now(),
+		UpdatedAt:     dbtime.Now(),
+		HashedSecret:  hashed[:],
+		LoginType:     params.LoginType,
+		Scope:         scope,
+		TokenName:     params.TokenName,
+		BoundIdentity: params.BoundIdentity,
 	}, token, nil
 }
 
@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 	"github.com/coder/serpent"
@@ -85,6 +86,111 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenFineGrainedScopes(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	scopedClient := func(ctx context.Context, t *testing.T, client *codersdk.Client, scope codersdk.APIKeyScope) *codersdk.Client {
+		t.Helper()
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{Scope: scope})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+		return scoped
+	}
+
+	t.Run("WorkspaceRead", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+		scoped := scopedClient(ctx, t, member, codersdk.APIKeyScopeWorkspaceRead)
+
+		_, err := scoped.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		workspaces, err := scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+
+		_, err = scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// The token can't be used to create a token with more permissions.
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{Scope: codersdk.APIKeyScopeAll})
+		require.Error(t, err)
+	})
+
+	t.Run("BuildCreate", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+		scoped := scopedClient(ctx, t, member, codersdk.APIKeyScopeBuildCreate)
+
+		build, err := scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+
+		_, err = scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = scoped.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       coderdtest.RandomUsername(t),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("TemplateAdmin", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		scoped := scopedClient(ctx, t, templateAdmin, codersdk.APIKeyScopeTemplateAdmin)
+
+		version := coderdtest.CreateTemplateVersion(t, scoped, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, scoped, version.ID)
+		coderdtest.CreateTemplate(t, scoped, owner.OrganizationID, version.ID)
+
+		// Template admins can read all workspaces, but the scope can't.
+		_, err := templateAdmin.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		_, err = scoped.Workspace(ctx, workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope: "workspace:delete",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
@@ -351,3 +457,80 @@ func TestAPIKey_SetDefault(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, dc.Sessions.DefaultTokenDuration.Value().Seconds(), apiKey1.LifetimeSeconds)
 }
+
+func TestTokenDeviceBinding(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	dv := coderdtest.DeploymentValues(t)
+	dv.Sessions.TokenBinding = string(codersdk.SessionTokenBindingDevice)
+	client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+	withDevice := func(client *codersdk.Client, deviceID string) *codersdk.Client {
+		client.HTTPClient = &http.Client{
+			Transport: &codersdk.HeaderTransport{
+				Transport: client.HTTPClient.Transport,
+				Header:    http.Header{codersdk.DeviceIDHeader: []string{deviceID}},
+			},
+		}
+		return client
+	}
+	// Keys are bound to the device that signs in, so the first user's
+	// session only works from the laptop.
+	withDevice(client, "laptop")
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+	require.NoError(t, err)
+
+	laptop := withDevice(codersdk.New(client.URL), "laptop")
+	laptop.SetSessionToken(res.Key)
+	_, err = laptop.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	for _, stolen := range []*codersdk.Client{
+		withDevice(codersdk.New(client.URL), "desktop"),
+		codersdk.New(client.URL),
+	} {
+		stolen.SetSessionToken(res.Key)
+		_, err = stolen.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	}
+
+	// Browsers can't send a device ID, so signing in gives them one in a
+	// cookie that their session is bound to.
+	browser := codersdk.New(client.URL)
+	login, err := browser.Request(ctx, http.MethodPost, "/api/v2/users/login", codersdk.LoginWithPasswordRequest{
+		Email:    coderdtest.FirstUserParams.Email,
+		Password: coderdtest.FirstUserParams.Password,
+	})
+	require.NoError(t, err)
+	defer login.Body.Close()
+	require.Equal(t, http.StatusCreated, login.StatusCode)
+	var sessionCookie, deviceCookie *http.Cookie
+	for _, cookie := range login.Cookies() {
+		switch cookie.Name {
+		case codersdk.SessionTokenCookie:
+			sessionCookie = cookie
+		case codersdk.DeviceIDCookie:
+			deviceCookie = cookie
+		}
+	}
+	require.NotNil(t, sessionCookie)
+	require.NotNil(t, deviceCookie)
+	require.True(t, deviceCookie.HttpOnly)
+
+	me := func(cookies ...*http.Cookie) int {
+		res, err := browser.Request(ctx, http.MethodGet, "/api/v2/users/me", nil, func(r *http.Request) {
+			for _, cookie := range cookies {
+				r.AddCookie(cookie)
+			}
+		})
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		return res.StatusCode
+	}
+	require.Equal(t, http.StatusOK, me(sessionCookie, deviceCookie))
+	require.Equal(t, http.StatusUnauthorized, me(sessionCookie))
+}
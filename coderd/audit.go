@@ -34,6 +34,7 @@ import (
 // @Param q query string false "Search query"
 // @Param limit query int true "Page limit"
 // @Param offset query int false "Page offset"
+// @Param after_id query string false "After ID" format(uuid)
 // @Success 200 {object} codersdk.AuditLogResponse
 // @Router /audit [get]
 func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
@@ -58,6 +59,7 @@ func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
 	filter.OffsetOpt = int32(page.Offset)
 	// #nosec G115 - Safe conversion as pagination limit is expected to be within int32 range
 	filter.LimitOpt = int32(page.Limit)
+	filter.AfterID = page.AfterID
 
 	if filter.Username == "me" {
 		filter.UserID = apiKey.UserID
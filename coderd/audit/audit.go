@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 type Auditor interface {
@@ -22,6 +23,21 @@ type AdditionalFields struct {
 	BuildReason    database.BuildReason `json:"build_reason"`
 	WorkspaceOwner string               `json:"workspace_owner"`
 	WorkspaceID    uuid.UUID            `json:"workspace_id"`
+	// BuildInitiatorContext attributes the build to the API key, automation,
+	// or schedule that initiated it.
+	BuildInitiatorContext *database.BuildInitiatorContext `json:"build_initiator_context,omitempty"`
+}
+
+// TemplateVersionFields are the additional fields of audit logs of template
+// versions that are created for a template or promoted to its active version.
+// They summarize how the files of the version differ from the base version,
+// the active version of the template at the time.
+type TemplateVersionFields struct {
+	TemplateVersionID     uuid.UUID                          `json:"template_version_id"`
+	BaseTemplateVersionID uuid.UUID                          `json:"base_template_version_id"`
+	Files                 []codersdk.TemplateVersionFileDiff `json:"files"`
+	// FilesTruncated is set if more files differ than are listed.
+	FilesTruncated bool `json:"files_truncated,omitempty"`
 }
 
 func NewNop() Auditor {
@@ -22,6 +22,10 @@ type Auditable interface {
 		database.AuditOAuthConvertState |
 		database.HealthSettings |
 		database.NotificationsSettings |
+		database.ReadOnlySettings |
+		database.ProvisionerBuildPause |
+		database.AccessRequest |
+		database.LoginDeviceCode |
 		database.OAuth2ProviderApp |
 		database.OAuth2ProviderAppSecret |
 		database.CustomRole |
@@ -10,7 +10,8 @@ import (
 type BackgroundSubsystem string
 
 const (
-	BackgroundSubsystemDormancy BackgroundSubsystem = "dormancy"
+	BackgroundSubsystemDormancy       BackgroundSubsystem = "dormancy"
+	BackgroundSubsystemAccessRequests BackgroundSubsystem = "access_requests"
 )
 
 func BackgroundTaskFields(subsystem BackgroundSubsystem) map[string]string {
@@ -60,6 +60,12 @@ func (r *Request[T]) UpdateOrganizationID(id uuid.UUID) {
 	r.params.OrganizationID = id
 }
 
+// UpdateAdditionalFields can be used if the additional fields are not known
+// at the initiation of an audit log request.
+func (r *Request[T]) UpdateAdditionalFields(fields interface{}) {
+	r.params.AdditionalFields = fields
+}
+
 type BackgroundAuditParams[T Auditable] struct {
 	Audit Auditor
 	Log   slog.Logger
@@ -113,6 +119,14 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return "" // no target?
 	case database.NotificationsSettings:
 		return "" // no target?
+	case database.ReadOnlySettings:
+		return "" // no target?
+	case database.ProvisionerBuildPause:
+		return "" // no target?
+	case database.AccessRequest:
+		return string(typed.ResourceType) + " " + typed.ResourceID.String()
+	case database.LoginDeviceCode:
+		return typed.UserCode
 	case database.OAuth2ProviderApp:
 		return typed.Name
 	case database.OAuth2ProviderAppSecret:
@@ -176,6 +190,15 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 	case database.NotificationsSettings:
 		// Artificial ID for auditing purposes
 		return typed.ID
+	case database.ReadOnlySettings:
+		// Artificial ID for auditing purposes
+		return typed.ID
+	case database.ProvisionerBuildPause:
+		return typed.ID
+	case database.AccessRequest:
+		return typed.ID
+	case database.LoginDeviceCode:
+		return typed.ID
 	case database.OAuth2ProviderApp:
 		return typed.ID
 	case database.OAuth2ProviderAppSecret:
@@ -231,6 +254,14 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeHealthSettings
 	case database.NotificationsSettings:
 		return database.ResourceTypeNotificationsSettings
+	case database.ReadOnlySettings:
+		return database.ResourceTypeReadOnlySettings
+	case database.ProvisionerBuildPause:
+		return database.ResourceTypeProvisionerBuildPause
+	case database.AccessRequest:
+		return database.ResourceTypeAccessRequest
+	case database.LoginDeviceCode:
+		return database.ResourceTypeLoginDeviceCode
 	case database.OAuth2ProviderApp:
 		return database.ResourceTypeOauth2ProviderApp
 	case database.OAuth2ProviderAppSecret:
@@ -288,6 +319,16 @@ func ResourceRequiresOrgID[T Auditable]() bool {
 	case database.NotificationsSettings:
 		// Artificial ID for auditing purposes
 		return false
+	case database.ReadOnlySettings:
+		// Artificial ID for auditing purposes
+		return false
+	case database.ProvisionerBuildPause:
+		// Builds can be paused across the deployment.
+		return false
+	case database.AccessRequest:
+		return true
+	case database.LoginDeviceCode:
+		return false
 	case database.OAuth2ProviderApp:
 		return false
 	case database.OAuth2ProviderAppSecret:
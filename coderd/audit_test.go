@@ -15,10 +15,12 @@ import (
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestAuditLogs(t *testing.T) {
@@ -48,6 +50,55 @@ func TestAuditLogs(t *testing.T) {
 		require.Len(t, alogs.AuditLogs, 1)
 	})
 
+	t.Run("AfterID", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		// Logs with the same time are ordered by ID.
+		now := dbtime.Now()
+		for _, logTime := range []time.Time{now, now, now.Add(-time.Minute)} {
+			err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+				ResourceID:     user.UserID,
+				OrganizationID: user.OrganizationID,
+				Time:           logTime,
+			})
+			require.NoError(t, err)
+		}
+
+		all, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{})
+		require.NoError(t, err)
+		var want, got []uuid.UUID
+		for _, alog := range all.AuditLogs {
+			want = append(want, alog.ID)
+		}
+
+		var afterID uuid.UUID
+		for range all.AuditLogs {
+			alogs, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+				Pagination: codersdk.Pagination{
+					AfterID: afterID,
+					Limit:   1,
+				},
+			})
+			require.NoError(t, err)
+			require.Len(t, alogs.AuditLogs, 1)
+			got = append(got, alogs.AuditLogs[0].ID)
+			afterID = alogs.AuditLogs[0].ID
+		}
+		require.Equal(t, want, got)
+
+		alogs, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			Pagination: codersdk.Pagination{
+				AfterID: afterID,
+			},
+		})
+		require.NoError(t, err)
+		require.Empty(t, alogs.AuditLogs)
+	})
+
 	t.Run("IncludeUser", func(t *testing.T) {
 		t.Parallel()
 
@@ -294,12 +345,13 @@ func TestAuditLogsFilter(t *testing.T) {
 		// Create one log with "Connect" and "Disconect".
 		connectRequestID := uuid.New()
 		err = client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
-			OrganizationID: user.OrganizationID,
-			Action:         codersdk.AuditActionConnect,
-			RequestID:      connectRequestID,
-			ResourceType:   codersdk.ResourceTypeWorkspaceAgent,
-			ResourceID:     workspace.LatestBuild.Resources[0].Agents[0].ID,
-			Time:           time.Date(2022, 8, 15, 14, 30, 45, 100, time.UTC), // 2022-8-15 14:30:45
+			OrganizationID:   user.OrganizationID,
+			Action:           codersdk.AuditActionConnect,
+			RequestID:        connectRequestID,
+			ResourceType:     codersdk.ResourceTypeWorkspaceAgent,
+			ResourceID:       workspace.LatestBuild.Resources[0].Agents[0].ID,
+			AdditionalFields: json.RawMessage(`{"client_type":"vscode"}`),
+			Time:             time.Date(2022, 8, 15, 14, 30, 45, 100, time.UTC), // 2022-8-15 14:30:45
 		})
 		require.NoError(t, err)
 
@@ -431,6 +483,21 @@ func TestAuditLogsFilter(t *testing.T) {
 				SearchQuery:    "resource_type:workspace_agent action:disconnect",
 				ExpectedResult: 1,
 			},
+			{
+				Name:           "FilterOnWorkspaceAgentConnectClientType",
+				SearchQuery:    "resource_type:workspace_agent client_type:vscode",
+				ExpectedResult: 1,
+			},
+			{
+				Name:           "FilterOnOtherClientType",
+				SearchQuery:    "client_type:browser",
+				ExpectedResult: 0,
+			},
+			{
+				Name:          "FilterWithInvalidClientType",
+				SearchQuery:   "client_type:invalid",
+				ExpectedError: true,
+			},
 			{
 				Name:           "FilterOnWorkspaceAgentConnectionRequestID",
 				SearchQuery:    "resource_type:workspace_agent request_id:" + connectRequestID.String(),
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -28,9 +29,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/readonly"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
 )
 
 // Executor automatically starts or stops workspaces.
@@ -43,8 +46,10 @@ type Executor struct {
 	accessControlStore    *atomic.Pointer[dbauthz.AccessControlStore]
 	auditor               *atomic.Pointer[audit.Auditor]
 	log                   slog.Logger
-	tick                  <-chan time.Time
+	clock                 quartz.Clock
+	interval              time.Duration
 	statsCh               chan<- Stats
+	readOnlyMode          *readonly.Mode
 	// NotificationsEnqueuer handles enqueueing notifications for delivery by SMTP, webhook, etc.
 	notificationsEnqueuer notifications.Enqueuer
 	reg                   prometheus.Registerer
@@ -65,7 +70,7 @@ type Stats struct {
 }
 
 // New returns a new wsactions executor.
-func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *files.Cache, reg prometheus.Registerer, tss *atomic.Pointer[schedule.TemplateScheduleStore], auditor *atomic.Pointer[audit.Auditor], acs *atomic.Pointer[dbauthz.AccessControlStore], log slog.Logger, tick <-chan time.Time, enqueuer notifications.Enqueuer, exp codersdk.Experiments) *Executor {
+func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *files.Cache, reg prometheus.Registerer, tss *atomic.Pointer[schedule.TemplateScheduleStore], auditor *atomic.Pointer[audit.Auditor], acs *atomic.Pointer[dbauthz.AccessControlStore], log slog.Logger, interval time.Duration, enqueuer notifications.Enqueuer, exp codersdk.Experiments) *Executor {
 	factory := promauto.With(reg)
 	le := &Executor{
 		//nolint:gocritic // Autostart has a limited set of permissions.
@@ -74,7 +79,8 @@ func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *f
 		ps:                    ps,
 		fileCache:             fc,
 		templateScheduleStore: tss,
-		tick:                  tick,
+		clock:                 quartz.NewReal(),
+		interval:              interval,
 		log:                   log.Named("autobuild"),
 		auditor:               auditor,
 		accessControlStore:    acs,
@@ -95,25 +101,39 @@ func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *f
 }
 
 // WithStatsChannel will cause Executor to push a RunStats to ch after
-// every tick.
+// every run.
 func (e *Executor) WithStatsChannel(ch chan<- Stats) *Executor {
 	e.statsCh = ch
 	return e
 }
 
-// Run will cause executor to start or stop workspaces on every
-// tick from its channel. It will stop when its context is Done, or when
-// its channel is closed.
+// WithClock sets the clock the Executor runs on and timestamps dormancy with.
+// It must be called before Run.
+func (e *Executor) WithClock(clock quartz.Clock) *Executor {
+	e.clock = clock
+	return e
+}
+
+// WithReadOnlyMode will cause Executor to skip ticks while the deployment is
+// in read-only mode.
+func (e *Executor) WithReadOnlyMode(mode *readonly.Mode) *Executor {
+	e.readOnlyMode = mode
+	return e
+}
+
+// Run will cause executor to start or stop workspaces every interval. It will
+// stop when its context is Done.
 func (e *Executor) Run() {
 	go func() {
 		for {
+			// The timer is created for each run so that tests can trap it and
+			// move a mock clock to the time they want the next run to happen.
+			timer := e.clock.NewTimer(e.interval, "autobuild")
 			select {
 			case <-e.ctx.Done():
+				timer.Stop()
 				return
-			case t, ok := <-e.tick:
-				if !ok {
-					return
-				}
+			case t := <-timer.C:
 				stats := e.runOnce(t)
 				e.metrics.autobuildExecutionDuration.Observe(stats.Elapsed.Seconds())
 				if e.statsCh != nil {
@@ -137,8 +157,12 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	// we build the map of transitions concurrently, so need a mutex to serialize writes to the map
 	statsMu := sync.Mutex{}
 	defer func() {
-		stats.Elapsed = time.Since(t)
+		stats.Elapsed = e.clock.Since(t)
 	}()
+	if e.readOnlyMode.Enabled() {
+		e.log.Debug(e.ctx, "deployment is in read-only mode, skipping autobuild")
+		return stats
+	}
 	currentTick := t.Truncate(time.Minute)
 
 	// TTL is set at the workspace level, and deadline at the workspace build level.
@@ -218,6 +242,17 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						return xerrors.Errorf("get workspace by id: %w", err)
 					}
 
+					// Locked workspaces are neither built nor made dormant
+					// until they are unlocked.
+					_, err = tx.GetActiveWorkspaceLockByWorkspaceID(e.ctx, wsID)
+					if err == nil {
+						log.Debug(e.ctx, "workspace is locked, skipping")
+						return nil
+					}
+					if !xerrors.Is(err, sql.ErrNoRows) {
+						return xerrors.Errorf("get active workspace lock: %w", err)
+					}
+
 					user, err := tx.GetUserByID(e.ctx, ws.OwnerID)
 					if err != nil {
 						return xerrors.Errorf("get user by id: %w", err)
@@ -279,11 +314,18 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					}
 
 					if nextTransition != "" {
+						initiatorContext := database.BuildInitiatorContext{
+							Automation: string(codersdk.BuildAutomationLifecycleExecutor),
+						}
+						if reason == database.BuildReasonAutostart {
+							initiatorContext.Schedule = ws.AutostartSchedule.String
+						}
 						builder := wsbuilder.New(ws, nextTransition).
 							SetLastWorkspaceBuildInTx(&latestBuild).
 							SetLastWorkspaceBuildJobInTx(&latestJob).
 							Experiments(e.experiments).
-							Reason(reason)
+							Reason(reason).
+							InitiatorContext(initiatorContext)
 						log.Debug(e.ctx, "auto building workspace", slog.F("transition", nextTransition))
 						if nextTransition == database.WorkspaceTransitionStart &&
 							useActiveVersion(accessControl, ws) {
@@ -310,7 +352,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						wsNew, err := tx.UpdateWorkspaceDormantDeletingAt(e.ctx, database.UpdateWorkspaceDormantDeletingAtParams{
 							ID: ws.ID,
 							DormantAt: sql.NullTime{
-								Time:  dbtime.Now(),
+								Time:  dbtime.Time(e.clock.Now()),
 								Valid: true,
 							},
 						})
@@ -407,7 +449,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					}
 				}
 				if shouldNotifyDormancy {
-					dormantTime := dbtime.Now().Add(time.Duration(tmpl.TimeTilDormant))
+					dormantTime := dbtime.Time(e.clock.Now()).Add(time.Duration(tmpl.TimeTilDormant))
 					_, err = e.notificationsEnqueuer.Enqueue(
 						e.ctx,
 						ws.OwnerID,
@@ -447,9 +489,84 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		e.log.Error(e.ctx, "workspace scheduling errgroup failed", slog.Error(err))
 	}
 
+	e.notifyLongRunningBuilds(currentTick)
+
 	return stats
 }
 
+// notifyLongRunningBuilds notifies the owners of workspace builds which have
+// been running for longer than their build duration notification threshold.
+// Each build is only notified once.
+func (e *Executor) notifyLongRunningBuilds(currentTick time.Time) {
+	builds, err := e.db.GetRunningWorkspaceBuildsExceedingDurationThreshold(e.ctx, currentTick)
+	if err != nil {
+		e.log.Error(e.ctx, "get workspace builds exceeding their duration threshold", slog.Error(err))
+		return
+	}
+
+	for _, build := range builds {
+		log := e.log.With(
+			slog.F("workspace_id", build.WorkspaceID),
+			slog.F("workspace_build_id", build.WorkspaceBuildID),
+		)
+
+		// Record the notification before sending it, so that other replicas
+		// running the executor don't notify the same build.
+		recorded, err := e.db.InsertWorkspaceBuildDurationNotification(e.ctx, database.InsertWorkspaceBuildDurationNotificationParams{
+			WorkspaceBuildID: build.WorkspaceBuildID,
+			NotifiedAt:       dbtime.Time(e.clock.Now()),
+		})
+		if err != nil {
+			log.Warn(e.ctx, "failed to record build duration notification", slog.Error(err))
+			continue
+		}
+		if recorded == 0 {
+			continue
+		}
+
+		_, err = e.notificationsEnqueuer.Enqueue(
+			e.ctx,
+			build.OwnerID,
+			notifications.TemplateWorkspaceBuildRunningLong,
+			map[string]string{
+				"name":         build.WorkspaceName,
+				"transition":   string(build.Transition),
+				"build_number": strconv.Itoa(int(build.BuildNumber)),
+				"threshold":    formatBuildDurationThreshold(time.Duration(build.ThresholdMs) * time.Millisecond),
+			},
+			"lifecycle_executor",
+			build.WorkspaceID,
+			build.OwnerID,
+			build.TemplateID,
+			build.OrganizationID,
+		)
+		if err != nil {
+			log.Warn(e.ctx, "failed to notify of long running workspace build", slog.Error(err))
+		}
+	}
+}
+
+// formatBuildDurationThreshold formats a threshold as hours and minutes, e.g.
+// "1 hour 30 minutes". Thresholds are at least a minute long.
+func formatBuildDurationThreshold(d time.Duration) string {
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	hours, minutes := int64(d/time.Hour), int64(d%time.Hour/time.Minute)
+	switch {
+	case hours == 0:
+		return plural(minutes, "minute")
+	case minutes == 0:
+		return plural(hours, "hour")
+	default:
+		return plural(hours, "hour") + " " + plural(minutes, "minute")
+	}
+}
+
 // getNextTransition returns the next eligible transition for the workspace
 // as well as the reason for why it is transitioning. It is possible
 // for this function to return a nil error as well as an empty transition.
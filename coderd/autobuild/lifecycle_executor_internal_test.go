@@ -161,3 +161,25 @@ func Test_isEligibleForAutostart(t *testing.T) {
 		})
 	}
 }
+
+func Test_formatBuildDurationThreshold(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Threshold time.Duration
+		Expected  string
+	}{
+		{Threshold: time.Minute, Expected: "1 minute"},
+		{Threshold: 45 * time.Minute, Expected: "45 minutes"},
+		{Threshold: time.Hour, Expected: "1 hour"},
+		{Threshold: 90 * time.Minute, Expected: "1 hour 30 minutes"},
+		{Threshold: 2*time.Hour + time.Minute, Expected: "2 hours 1 minute"},
+		// Seconds are dropped.
+		{Threshold: 10*time.Minute + 30*time.Second, Expected: "10 minutes"},
+	} {
+		t.Run(tc.Expected, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.Expected, formatBuildDurationThreshold(tc.Threshold))
+		})
+	}
+}
@@ -17,7 +17,9 @@ import (
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/schedule"
@@ -34,10 +36,10 @@ func TestExecutorAutostartOK(t *testing.T) {
 
 	var (
 		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -50,10 +52,7 @@ func TestExecutorAutostartOK(t *testing.T) {
 	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 	// When: the autobuild executor ticks after the scheduled time
-	go func() {
-		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
-		close(tickCh)
-	}()
+	clock.Tick(sched.Next(workspace.LatestBuild.CreatedAt))
 
 	// Then: the workspace should eventually be started
 	stats := <-statsCh
@@ -64,6 +63,8 @@ func TestExecutorAutostartOK(t *testing.T) {
 
 	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
 	assert.Equal(t, codersdk.BuildReasonAutostart, workspace.LatestBuild.Reason)
+	assert.Equal(t, codersdk.BuildAutomationLifecycleExecutor, workspace.LatestBuild.InitiatorContext.Automation)
+	assert.Equal(t, sched.String(), workspace.LatestBuild.InitiatorContext.Schedule)
 	// Assert some template props. If this is not set correctly, the test
 	// will fail.
 	ctx := testutil.Context(t, testutil.WaitShort)
@@ -80,20 +81,21 @@ func TestMultipleLifecycleExecutors(t *testing.T) {
 	var (
 		sched = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
 		// Create our first client
-		tickCh   = make(chan time.Time, 2)
+		clockA   = coderdtest.NewAutobuildClock(t)
 		statsChA = make(chan autobuild.Stats)
 		clientA  = coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clockA,
 			AutobuildStats:           statsChA,
 			Database:                 db,
 			Pubsub:                   ps,
 		})
 		// ... And then our second client
+		clockB   = coderdtest.NewAutobuildClock(t)
 		statsChB = make(chan autobuild.Stats)
 		_        = coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clockB,
 			AutobuildStats:           statsChB,
 			Database:                 db,
 			Pubsub:                   ps,
@@ -110,16 +112,8 @@ func TestMultipleLifecycleExecutors(t *testing.T) {
 	// Get both clients to perform a lifecycle execution tick
 	next := sched.Next(workspace.LatestBuild.CreatedAt)
 
-	startCh := make(chan struct{})
-	go func() {
-		<-startCh
-		tickCh <- next
-	}()
-	go func() {
-		<-startCh
-		tickCh <- next
-	}()
-	close(startCh)
+	clockA.Tick(next)
+	clockB.Tick(next)
 
 	// Now we want to check the stats for both clients
 	statsA := <-statsChA
@@ -183,12 +177,12 @@ func TestExecutorAutostartTemplateUpdated(t *testing.T) {
 				sched    = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
 				ctx      = context.Background()
 				err      error
-				tickCh   = make(chan time.Time)
+				clock    = coderdtest.NewAutobuildClock(t)
 				statsCh  = make(chan autobuild.Stats)
 				logger   = slogtest.Make(t, &slogtest.Options{IgnoreErrors: !tc.expectStart}).Leveled(slog.LevelDebug)
 				enqueuer = notificationstest.FakeEnqueuer{}
 				client   = coderdtest.New(t, &coderdtest.Options{
-					AutobuildTicker:          tickCh,
+					AutobuildClock:           clock,
 					IncludeProvisionerDaemon: true,
 					AutobuildStats:           statsCh,
 					Logger:                   &logger,
@@ -242,10 +236,7 @@ func TestExecutorAutostartTemplateUpdated(t *testing.T) {
 
 			t.Log("sending autobuild tick")
 			// When: the autobuild executor ticks after the scheduled time
-			go func() {
-				tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
-				close(tickCh)
-			}()
+			clock.Tick(sched.Next(workspace.LatestBuild.CreatedAt))
 
 			stats := <-statsCh
 			if !tc.expectStart {
@@ -295,10 +286,10 @@ func TestExecutorAutostartAlreadyRunning(t *testing.T) {
 
 	var (
 		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -312,10 +303,7 @@ func TestExecutorAutostartAlreadyRunning(t *testing.T) {
 	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
 
 	// When: the autobuild executor ticks
-	go func() {
-		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
-		close(tickCh)
-	}()
+	clock.Tick(sched.Next(workspace.LatestBuild.CreatedAt))
 
 	// Then: the workspace should not be started.
 	stats := <-statsCh
@@ -327,10 +315,10 @@ func TestExecutorAutostartNotEnabled(t *testing.T) {
 	t.Parallel()
 
 	var (
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -347,10 +335,7 @@ func TestExecutorAutostartNotEnabled(t *testing.T) {
 	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 	// When: the autobuild executor ticks way into the future
-	go func() {
-		tickCh <- workspace.LatestBuild.CreatedAt.Add(24 * time.Hour)
-		close(tickCh)
-	}()
+	clock.Tick(workspace.LatestBuild.CreatedAt.Add(24 * time.Hour))
 
 	// Then: the workspace should not be started.
 	stats := <-statsCh
@@ -363,10 +348,10 @@ func TestExecutorAutostartUserSuspended(t *testing.T) {
 
 	var (
 		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -392,10 +377,7 @@ func TestExecutorAutostartUserSuspended(t *testing.T) {
 	require.NoError(t, err, "update user status")
 
 	// When: the autobuild executor ticks after the scheduled time
-	go func() {
-		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
-		close(tickCh)
-	}()
+	clock.Tick(sched.Next(workspace.LatestBuild.CreatedAt))
 
 	// Then: nothing should happen
 	stats := testutil.TryReceive(ctx, t, statsCh)
@@ -407,10 +389,10 @@ func TestExecutorAutostopOK(t *testing.T) {
 	t.Parallel()
 
 	var (
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -422,10 +404,7 @@ func TestExecutorAutostopOK(t *testing.T) {
 	require.NotZero(t, workspace.LatestBuild.Deadline)
 
 	// When: the autobuild executor ticks *after* the deadline:
-	go func() {
-		tickCh <- workspace.LatestBuild.Deadline.Time.Add(time.Minute)
-		close(tickCh)
-	}()
+	clock.Tick(workspace.LatestBuild.Deadline.Time.Add(time.Minute))
 
 	// Then: the workspace should be stopped
 	stats := <-statsCh
@@ -443,10 +422,10 @@ func TestExecutorAutostopExtend(t *testing.T) {
 
 	var (
 		ctx     = context.Background()
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -466,9 +445,7 @@ func TestExecutorAutostopExtend(t *testing.T) {
 	require.NoError(t, err, "extend workspace deadline")
 
 	// When: the autobuild executor ticks *after* the original deadline:
-	go func() {
-		tickCh <- originalDeadline.Time.Add(time.Minute)
-	}()
+	clock.Tick(originalDeadline.Time.Add(time.Minute))
 
 	// Then: nothing should happen and the workspace should stay running
 	stats := <-statsCh
@@ -476,10 +453,7 @@ func TestExecutorAutostopExtend(t *testing.T) {
 	assert.Len(t, stats.Transitions, 0)
 
 	// When: the autobuild executor ticks after the *new* deadline:
-	go func() {
-		tickCh <- newDeadline.Add(time.Minute)
-		close(tickCh)
-	}()
+	clock.Tick(newDeadline.Add(time.Minute))
 
 	// Then: the workspace should be stopped
 	stats = <-statsCh
@@ -493,10 +467,10 @@ func TestExecutorAutostopAlreadyStopped(t *testing.T) {
 	t.Parallel()
 
 	var (
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -510,10 +484,7 @@ func TestExecutorAutostopAlreadyStopped(t *testing.T) {
 	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 	// When: the autobuild executor ticks past the TTL
-	go func() {
-		tickCh <- workspace.LatestBuild.Deadline.Time.Add(time.Minute)
-		close(tickCh)
-	}()
+	clock.Tick(workspace.LatestBuild.Deadline.Time.Add(time.Minute))
 
 	// Then: the workspace should remain stopped and no build should happen.
 	stats := <-statsCh
@@ -525,10 +496,10 @@ func TestExecutorAutostopNotEnabled(t *testing.T) {
 	t.Parallel()
 
 	var (
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -548,10 +519,7 @@ func TestExecutorAutostopNotEnabled(t *testing.T) {
 	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
 
 	// When: the autobuild executor ticks a year in the future
-	go func() {
-		tickCh <- workspace.LatestBuild.Job.CompletedAt.AddDate(1, 0, 0)
-		close(tickCh)
-	}()
+	clock.Tick(workspace.LatestBuild.Job.CompletedAt.AddDate(1, 0, 0))
 
 	// Then: the workspace should not be stopped.
 	stats := <-statsCh
@@ -559,15 +527,45 @@ func TestExecutorAutostopNotEnabled(t *testing.T) {
 	assert.Len(t, stats.Transitions, 0)
 }
 
+func TestExecutorAutostopLocked(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = testutil.Context(t, testutil.WaitShort)
+		clock   = coderdtest.NewAutobuildClock(t)
+		statsCh = make(chan autobuild.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildClock:           clock,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+		})
+		// Given: we have a user with a workspace
+		workspace = mustProvisionWorkspace(t, client)
+	)
+	// Given: workspace is running and locked
+	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	require.NotZero(t, workspace.LatestBuild.Deadline)
+	_, err := client.LockWorkspace(ctx, workspace.ID, codersdk.LockWorkspaceRequest{Reason: "incident"})
+	require.NoError(t, err)
+
+	// When: the autobuild executor ticks *after* the deadline:
+	clock.Tick(workspace.LatestBuild.Deadline.Time.Add(time.Minute))
+
+	// Then: the workspace should not be stopped
+	stats := <-statsCh
+	assert.Len(t, stats.Errors, 0)
+	assert.Len(t, stats.Transitions, 0)
+}
+
 func TestExecutorWorkspaceDeleted(t *testing.T) {
 	t.Parallel()
 
 	var (
 		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -581,10 +579,7 @@ func TestExecutorWorkspaceDeleted(t *testing.T) {
 	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionDelete)
 
 	// When: the autobuild executor ticks
-	go func() {
-		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
-		close(tickCh)
-	}()
+	clock.Tick(sched.Next(workspace.LatestBuild.CreatedAt))
 
 	// Then: nothing should happen
 	stats := <-statsCh
@@ -597,10 +592,10 @@ func TestExecutorWorkspaceAutostartTooEarly(t *testing.T) {
 
 	var (
 		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -613,10 +608,7 @@ func TestExecutorWorkspaceAutostartTooEarly(t *testing.T) {
 	)
 
 	// When: the autobuild executor ticks before the next scheduled time
-	go func() {
-		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt).Add(-time.Minute)
-		close(tickCh)
-	}()
+	clock.Tick(sched.Next(workspace.LatestBuild.CreatedAt).Add(-time.Minute))
 
 	// Then: nothing should happen
 	stats := <-statsCh
@@ -628,10 +620,10 @@ func TestExecutorWorkspaceAutostopBeforeDeadline(t *testing.T) {
 	t.Parallel()
 
 	var (
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -644,10 +636,7 @@ func TestExecutorWorkspaceAutostopBeforeDeadline(t *testing.T) {
 	require.NotZero(t, workspace.LatestBuild.Deadline)
 
 	// When: the autobuild executor ticks before the TTL
-	go func() {
-		tickCh <- workspace.LatestBuild.Deadline.Time.Add(-1 * time.Minute)
-		close(tickCh)
-	}()
+	clock.Tick(workspace.LatestBuild.Deadline.Time.Add(-1 * time.Minute))
 
 	// Then: nothing should happen
 	stats := <-statsCh
@@ -659,10 +648,10 @@ func TestExecuteAutostopSuspendedUser(t *testing.T) {
 	t.Parallel()
 
 	var (
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -686,10 +675,7 @@ func TestExecuteAutostopSuspendedUser(t *testing.T) {
 	require.NoError(t, err, "update user status")
 
 	// When: the autobuild executor ticks after the scheduled time
-	go func() {
-		tickCh <- time.Unix(0, 0) // the exact time is not important
-		close(tickCh)
-	}()
+	clock.Tick(time.Unix(0, 0)) // the exact time is not important
 
 	// Then: the workspace should be stopped
 	stats := <-statsCh
@@ -708,10 +694,10 @@ func TestExecutorWorkspaceAutostopNoWaitChangedMyMind(t *testing.T) {
 
 	var (
 		ctx     = context.Background()
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -728,9 +714,7 @@ func TestExecutorWorkspaceAutostopNoWaitChangedMyMind(t *testing.T) {
 	assert.True(t, !updated.LatestBuild.Deadline.Valid)
 
 	// When: the autobuild executor ticks after the original deadline
-	go func() {
-		tickCh <- workspace.LatestBuild.Deadline.Time.Add(time.Minute)
-	}()
+	clock.Tick(workspace.LatestBuild.Deadline.Time.Add(time.Minute))
 
 	// Then: the workspace should not stop
 	stats := <-statsCh
@@ -747,17 +731,17 @@ func TestExecutorAutostartMultipleOK(t *testing.T) {
 
 	var (
 		sched    = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		tickCh   = make(chan time.Time)
-		tickCh2  = make(chan time.Time)
+		clock1   = coderdtest.NewAutobuildClock(t)
+		clock2   = coderdtest.NewAutobuildClock(t)
 		statsCh1 = make(chan autobuild.Stats)
 		statsCh2 = make(chan autobuild.Stats)
 		client   = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock1,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh1,
 		})
 		_ = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh2,
+			AutobuildClock:           clock2,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh2,
 		})
@@ -770,12 +754,8 @@ func TestExecutorAutostartMultipleOK(t *testing.T) {
 	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 	// When: the autobuild executor ticks past the scheduled time
-	go func() {
-		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
-		tickCh2 <- sched.Next(workspace.LatestBuild.CreatedAt)
-		close(tickCh)
-		close(tickCh2)
-	}()
+	clock1.Tick(sched.Next(workspace.LatestBuild.CreatedAt))
+	clock2.Tick(sched.Next(workspace.LatestBuild.CreatedAt))
 
 	// Then: the workspace should eventually be started
 	stats1 := <-statsCh1
@@ -803,10 +783,10 @@ func TestExecutorAutostartWithParameters(t *testing.T) {
 
 	var (
 		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 		client  = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 		})
@@ -835,10 +815,7 @@ func TestExecutorAutostartWithParameters(t *testing.T) {
 	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 	// When: the autobuild executor ticks after the scheduled time
-	go func() {
-		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
-		close(tickCh)
-	}()
+	clock.Tick(sched.Next(workspace.LatestBuild.CreatedAt))
 
 	// Then: the workspace with parameters should eventually be started
 	stats := <-statsCh
@@ -856,11 +833,11 @@ func TestExecutorAutostartTemplateDisabled(t *testing.T) {
 
 	var (
 		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 
 		client = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 			TemplateScheduleStore: schedule.MockTemplateScheduleStore{
@@ -885,10 +862,7 @@ func TestExecutorAutostartTemplateDisabled(t *testing.T) {
 	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 	// When: the autobuild executor ticks before the next scheduled time
-	go func() {
-		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt).Add(time.Minute)
-		close(tickCh)
-	}()
+	clock.Tick(sched.Next(workspace.LatestBuild.CreatedAt).Add(time.Minute))
 
 	// Then: nothing should happen
 	stats := <-statsCh
@@ -901,11 +875,11 @@ func TestExecutorAutostopTemplateDisabled(t *testing.T) {
 
 	// Given: we have a workspace built from a template that disallows user autostop
 	var (
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 
 		client = coderdtest.New(t, &coderdtest.Options{
-			AutobuildTicker:          tickCh,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
 			// We are using a mock store here as the AGPL store does not implement this.
@@ -929,9 +903,7 @@ func TestExecutorAutostopTemplateDisabled(t *testing.T) {
 	assert.WithinDuration(t, workspace.LatestBuild.CreatedAt.Add(time.Hour), workspace.LatestBuild.Deadline.Time, time.Minute)
 
 	// When: the autobuild executor ticks after the workspace setting, but before the template setting:
-	go func() {
-		tickCh <- workspace.LatestBuild.Job.CompletedAt.Add(45 * time.Minute)
-	}()
+	clock.Tick(workspace.LatestBuild.Job.CompletedAt.Add(45 * time.Minute))
 
 	// Then: nothing should happen
 	stats := <-statsCh
@@ -939,10 +911,7 @@ func TestExecutorAutostopTemplateDisabled(t *testing.T) {
 	assert.Len(t, stats.Transitions, 0)
 
 	// When: the autobuild executor ticks after the template setting:
-	go func() {
-		tickCh <- workspace.LatestBuild.Job.CompletedAt.Add(61 * time.Minute)
-		close(tickCh)
-	}()
+	clock.Tick(workspace.LatestBuild.Job.CompletedAt.Add(61 * time.Minute))
 
 	// Then: the workspace should be stopped
 	stats = <-statsCh
@@ -959,11 +928,11 @@ func TestExecutorRequireActiveVersion(t *testing.T) {
 
 	var (
 		sched  = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
-		ticker = make(chan time.Time)
+		clock  = coderdtest.NewAutobuildClock(t)
 		statCh = make(chan autobuild.Stats)
 
 		ownerClient, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
-			AutobuildTicker:          ticker,
+			AutobuildClock:           clock,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statCh,
 			TemplateScheduleStore:    schedule.NewAGPLTemplateScheduleStore(),
@@ -1005,7 +974,7 @@ func TestExecutorRequireActiveVersion(t *testing.T) {
 		req.TemplateVersionID = inactiveVersion.ID
 	})
 	require.Equal(t, inactiveVersion.ID, ws.LatestBuild.TemplateVersionID)
-	ticker <- sched.Next(ws.LatestBuild.CreatedAt)
+	clock.Tick(sched.Next(ws.LatestBuild.CreatedAt))
 	stats := <-statCh
 	require.Len(t, stats.Transitions, 1)
 
@@ -1026,7 +995,7 @@ func TestExecutorFailedWorkspace(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker = make(chan time.Time)
+			clock  = coderdtest.NewAutobuildClock(t)
 			statCh = make(chan autobuild.Stats)
 			logger = slogtest.Make(t, &slogtest.Options{
 				// We ignore errors here since we expect to fail
@@ -1037,7 +1006,7 @@ func TestExecutorFailedWorkspace(t *testing.T) {
 
 			client = coderdtest.New(t, &coderdtest.Options{
 				Logger:                   &logger,
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewAGPLTemplateScheduleStore(),
@@ -1056,7 +1025,7 @@ func TestExecutorFailedWorkspace(t *testing.T) {
 		ws := coderdtest.CreateWorkspace(t, client, template.ID)
 		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
 		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
-		ticker <- build.Job.CompletedAt.Add(failureTTL * 2)
+		clock.Tick(build.Job.CompletedAt.Add(failureTTL * 2))
 		stats := <-statCh
 		// Expect no transitions since we're using AGPL.
 		require.Len(t, stats.Transitions, 0)
@@ -1076,7 +1045,7 @@ func TestExecutorInactiveWorkspace(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker = make(chan time.Time)
+			clock  = coderdtest.NewAutobuildClock(t)
 			statCh = make(chan autobuild.Stats)
 			logger = slogtest.Make(t, &slogtest.Options{
 				// We ignore errors here since we expect to fail
@@ -1087,7 +1056,7 @@ func TestExecutorInactiveWorkspace(t *testing.T) {
 
 			client = coderdtest.New(t, &coderdtest.Options{
 				Logger:                   &logger,
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewAGPLTemplateScheduleStore(),
@@ -1106,7 +1075,7 @@ func TestExecutorInactiveWorkspace(t *testing.T) {
 		ws := coderdtest.CreateWorkspace(t, client, template.ID)
 		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
-		ticker <- ws.LastUsedAt.Add(inactiveTTL * 2)
+		clock.Tick(ws.LastUsedAt.Add(inactiveTTL * 2))
 		stats := <-statCh
 		// Expect no transitions since we're using AGPL.
 		require.Len(t, stats.Transitions, 0)
@@ -1121,12 +1090,12 @@ func TestNotifications(t *testing.T) {
 
 		// Setup template with dormancy and create a workspace with it
 		var (
-			ticker         = make(chan time.Time)
+			clock          = coderdtest.NewAutobuildClock(t)
 			statCh         = make(chan autobuild.Stats)
 			notifyEnq      = notificationstest.FakeEnqueuer{}
 			timeTilDormant = time.Minute
 			client         = coderdtest.New(t, &coderdtest.Options{
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				AutobuildStats:           statCh,
 				IncludeProvisionerDaemon: true,
 				NotificationsEnqueuer:    &notifyEnq,
@@ -1164,12 +1133,13 @@ func TestNotifications(t *testing.T) {
 
 		// Wait for workspace to become dormant
 		notifyEnq.Clear()
-		ticker <- workspace.LastUsedAt.Add(timeTilDormant * 3)
+		clock.Tick(workspace.LastUsedAt.Add(timeTilDormant * 3))
 		_ = testutil.TryReceive(testutil.Context(t, testutil.WaitShort), t, statCh)
 
 		// Check that the workspace is dormant
 		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
 		require.NotNil(t, workspace.DormantAt)
+		require.Equal(t, clock.Now().UTC(), workspace.DormantAt.UTC())
 
 		// Check that a notification was enqueued
 		sent := notifyEnq.Sent()
@@ -1181,6 +1151,70 @@ func TestNotifications(t *testing.T) {
 		require.Contains(t, sent[0].Targets, workspace.OrganizationID)
 		require.Contains(t, sent[0].Targets, workspace.OwnerID)
 	})
+
+	t.Run("LongRunningBuild", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			clock      = coderdtest.NewAutobuildClock(t)
+			statCh     = make(chan autobuild.Stats)
+			notifyEnq  = notificationstest.FakeEnqueuer{}
+			client, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+				AutobuildClock:        clock,
+				AutobuildStats:        statCh,
+				NotificationsEnqueuer: &notifyEnq,
+			})
+			admin   = coderdtest.CreateFirstUser(t, client)
+			_, user = coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+			ctx     = testutil.Context(t, testutil.WaitShort)
+		)
+
+		// Given: the user wants to know about builds running for more than 10 minutes
+		_, err := client.UpdateUserNotificationBuildDurationSettings(ctx, user.ID, codersdk.NotificationBuildDurationSettings{
+			ThresholdMillis: (10 * time.Minute).Milliseconds(),
+		})
+		require.NoError(t, err)
+
+		// Given: a build of their workspace is running
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: admin.OrganizationID,
+			OwnerID:        user.ID,
+		}).Starting().Do()
+		startedAt := dbtime.Now()
+
+		// When: the build runs for less than the threshold
+		clock.Tick(startedAt.Add(5 * time.Minute))
+		_ = testutil.TryReceive(ctx, t, statCh)
+
+		// Then: no notification is sent
+		require.Empty(t, notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceBuildRunningLong)))
+
+		// When: the build runs for longer than the threshold
+		clock.Tick(startedAt.Add(11 * time.Minute))
+		_ = testutil.TryReceive(ctx, t, statCh)
+
+		// Then: the owner is notified
+		sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceBuildRunningLong))
+		require.Len(t, sent, 1)
+		require.Equal(t, user.ID, sent[0].UserID)
+		require.Equal(t, map[string]string{
+			"name":         r.Workspace.Name,
+			"transition":   string(r.Build.Transition),
+			"build_number": "1",
+			"threshold":    "10 minutes",
+		}, sent[0].Labels)
+		require.Contains(t, sent[0].Targets, r.Workspace.ID)
+		require.Contains(t, sent[0].Targets, r.Workspace.TemplateID)
+		require.Contains(t, sent[0].Targets, r.Workspace.OrganizationID)
+		require.Contains(t, sent[0].Targets, user.ID)
+
+		// When: the build keeps running
+		clock.Tick(startedAt.Add(20 * time.Minute))
+		_ = testutil.TryReceive(ctx, t, statCh)
+
+		// Then: the build is only notified once
+		require.Len(t, notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceBuildRunningLong)), 1)
+	})
 }
 
 func mustProvisionWorkspace(t *testing.T, client *codersdk.Client, mut ...func(*codersdk.CreateWorkspaceRequest)) codersdk.Workspace {
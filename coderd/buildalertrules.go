@@ -0,0 +1,315 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get build alert rules
+// @ID get-build-alert-rules
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.BuildAlertRule
+// @Router /organizations/{organization}/buildalertrules [get]
+func (api *API) buildAlertRules(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	rules, err := api.Database.GetBuildAlertRulesByOrganizationID(ctx, org.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching build alert rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(rules, convertBuildAlertRule))
+}
+
+// @Summary Create build alert rule
+// @ID create-build-alert-rule
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateBuildAlertRuleRequest true "Create build alert rule request"
+// @Success 201 {object} codersdk.BuildAlertRule
+// @Router /organizations/{organization}/buildalertrules [post]
+func (api *API) postBuildAlertRule(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	apiKey := httpmw.APIKey(r)
+
+	var req codersdk.CreateBuildAlertRuleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	templateID, ok := api.validateBuildAlertRule(ctx, rw, org.ID, req.TemplateID, req.Metric, req.Threshold, req.WindowMillis)
+	if !ok {
+		return
+	}
+
+	now := dbtime.Now()
+	rule, err := api.Database.InsertBuildAlertRule(ctx, database.InsertBuildAlertRuleParams{
+		ID:             uuid.New(),
+		OrganizationID: org.ID,
+		TemplateID:     templateID,
+		Name:           req.Name,
+		Metric:         database.BuildAlertRuleMetric(req.Metric),
+		Threshold:      req.Threshold,
+		WindowMs:       req.WindowMillis,
+		CreatedBy:      uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueBuildAlertRulesOrganizationIDNameIndex) {
+		writeBuildAlertRuleNameConflict(ctx, rw, req.Name)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating build alert rule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertBuildAlertRule(rule))
+}
+
+// @Summary Update build alert rule
+// @ID update-build-alert-rule
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param buildalertrule path string true "Build alert rule ID" format(uuid)
+// @Param request body codersdk.UpdateBuildAlertRuleRequest true "Update build alert rule request"
+// @Success 200 {object} codersdk.BuildAlertRule
+// @Router /organizations/{organization}/buildalertrules/{buildalertrule} [put]
+func (api *API) putBuildAlertRule(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	rule, ok := api.buildAlertRuleParam(rw, r, org.ID)
+	if !ok {
+		return
+	}
+
+	var req codersdk.UpdateBuildAlertRuleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	templateID, ok := api.validateBuildAlertRule(ctx, rw, org.ID, req.TemplateID, req.Metric, req.Threshold, req.WindowMillis)
+	if !ok {
+		return
+	}
+
+	rule, err := api.Database.UpdateBuildAlertRuleByID(ctx, database.UpdateBuildAlertRuleByIDParams{
+		ID:         rule.ID,
+		TemplateID: templateID,
+		Name:       req.Name,
+		Metric:     database.BuildAlertRuleMetric(req.Metric),
+		Threshold:  req.Threshold,
+		WindowMs:   req.WindowMillis,
+		UpdatedAt:  dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueBuildAlertRulesOrganizationIDNameIndex) {
+		writeBuildAlertRuleNameConflict(ctx, rw, req.Name)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating build alert rule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertBuildAlertRule(rule))
+}
+
+// @Summary Delete build alert rule
+// @ID delete-build-alert-rule
+// @Security CoderSessionToken
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param buildalertrule path string true "Build alert rule ID" format(uuid)
+// @Success 204
+// @Router /organizations/{organization}/buildalertrules/{buildalertrule} [delete]
+func (api *API) deleteBuildAlertRule(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	rule, ok := api.buildAlertRuleParam(rw, r, org.ID)
+	if !ok {
+		return
+	}
+
+	err := api.Database.DeleteBuildAlertRuleByID(ctx, rule.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting build alert rule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// buildAlertRuleParam fetches the build alert rule in the URL, writing a
+// response and returning false if it does not exist in the organization.
+func (api *API) buildAlertRuleParam(rw http.ResponseWriter, r *http.Request, organizationID uuid.UUID) (database.BuildAlertRule, bool) {
+	ctx := r.Context()
+
+	ruleID, ok := httpmw.ParseUUIDParam(rw, r, "buildalertrule")
+	if !ok {
+		return database.BuildAlertRule{}, false
+	}
+
+	rule, err := api.Database.GetBuildAlertRuleByID(ctx, ruleID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.BuildAlertRule{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching build alert rule.",
+			Detail:  err.Error(),
+		})
+		return database.BuildAlertRule{}, false
+	}
+	if rule.OrganizationID != organizationID {
+		httpapi.ResourceNotFound(rw)
+		return database.BuildAlertRule{}, false
+	}
+	return rule, true
+}
+
+// validateBuildAlertRule checks a build alert rule definition, writing a
+// response and returning false if it is invalid.
+func (api *API) validateBuildAlertRule(ctx context.Context, rw http.ResponseWriter, organizationID uuid.UUID, templateID *uuid.UUID, metric codersdk.BuildAlertRuleMetric, threshold float64, windowMillis int64) (uuid.NullUUID, bool) {
+	var validations []codersdk.ValidationError
+	switch metric {
+	case codersdk.BuildAlertRuleMetricFailureRate:
+		if threshold < 0 || threshold >= 100 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "threshold",
+				Detail: "Must be a percentage between 0 and 100.",
+			})
+		}
+	case codersdk.BuildAlertRuleMetricQueueWaitP95:
+		if threshold <= 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "threshold",
+				Detail: "Must be a duration in milliseconds greater than 0.",
+			})
+		}
+	default:
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "metric",
+			Detail: fmt.Sprintf("Must be one of %q or %q.", codersdk.BuildAlertRuleMetricFailureRate, codersdk.BuildAlertRuleMetricQueueWaitP95),
+		})
+	}
+	if windowMillis <= 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "window_ms",
+			Detail: "Must be greater than 0.",
+		})
+	}
+
+	var nullTemplateID uuid.NullUUID
+	if templateID != nil {
+		template, err := api.Database.GetTemplateByID(ctx, *templateID)
+		if err != nil && !httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return uuid.NullUUID{}, false
+		}
+		if err != nil || template.OrganizationID != organizationID {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "template_id",
+				Detail: "Template not found in the organization.",
+			})
+		}
+		nullTemplateID = uuid.NullUUID{UUID: *templateID, Valid: true}
+	}
+
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid build alert rule.",
+			Validations: validations,
+		})
+		return uuid.NullUUID{}, false
+	}
+	return nullTemplateID, true
+}
+
+func writeBuildAlertRuleNameConflict(ctx context.Context, rw http.ResponseWriter, name string) {
+	httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+		Message: fmt.Sprintf("Build alert rule with name %q already exists.", name),
+		Validations: []codersdk.ValidationError{{
+			Field:  "name",
+			Detail: "This value is already in use and should be unique.",
+		}},
+	})
+}
+
+func convertBuildAlertRule(rule database.BuildAlertRule) codersdk.BuildAlertRule {
+	sdkRule := codersdk.BuildAlertRule{
+		ID:             rule.ID,
+		OrganizationID: rule.OrganizationID,
+		Name:           rule.Name,
+		Metric:         codersdk.BuildAlertRuleMetric(rule.Metric),
+		Threshold:      rule.Threshold,
+		WindowMillis:   rule.WindowMs,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+	if rule.TemplateID.Valid {
+		sdkRule.TemplateID = &rule.TemplateID.UUID
+	}
+	if rule.LastValue.Valid {
+		sdkRule.LastValue = &rule.LastValue.Float64
+	}
+	if rule.LastEvaluatedAt.Valid {
+		sdkRule.LastEvaluatedAt = &rule.LastEvaluatedAt.Time
+	}
+	if rule.BreachedAt.Valid {
+		sdkRule.BreachedAt = &rule.BreachedAt.Time
+	}
+	return sdkRule
+}
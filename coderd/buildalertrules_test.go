@@ -0,0 +1,115 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestBuildAlertRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateUpdateDelete", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		rule, err := client.CreateBuildAlertRule(ctx, owner.OrganizationID, codersdk.CreateBuildAlertRuleRequest{
+			TemplateID:   &template.ID,
+			Name:         "failures",
+			Metric:       codersdk.BuildAlertRuleMetricFailureRate,
+			Threshold:    10,
+			WindowMillis: (15 * time.Minute).Milliseconds(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "failures", rule.Name)
+		require.NotNil(t, rule.TemplateID)
+		require.Equal(t, template.ID, *rule.TemplateID)
+		require.Nil(t, rule.BreachedAt)
+
+		_, err = client.CreateBuildAlertRule(ctx, owner.OrganizationID, codersdk.CreateBuildAlertRuleRequest{
+			Name:         "Failures",
+			Metric:       codersdk.BuildAlertRuleMetricFailureRate,
+			Threshold:    20,
+			WindowMillis: time.Hour.Milliseconds(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		rule, err = client.UpdateBuildAlertRule(ctx, owner.OrganizationID, rule.ID, codersdk.UpdateBuildAlertRuleRequest{
+			Name:         "slow queue",
+			Metric:       codersdk.BuildAlertRuleMetricQueueWaitP95,
+			Threshold:    float64((5 * time.Minute).Milliseconds()),
+			WindowMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.BuildAlertRuleMetricQueueWaitP95, rule.Metric)
+		require.Nil(t, rule.TemplateID)
+
+		rules, err := client.BuildAlertRules(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, rule.ID, rules[0].ID)
+
+		err = client.DeleteBuildAlertRule(ctx, owner.OrganizationID, rule.ID)
+		require.NoError(t, err)
+
+		rules, err = client.BuildAlertRules(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+
+		err = client.DeleteBuildAlertRule(ctx, owner.OrganizationID, rule.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		templateID := uuid.New()
+		_, err := client.CreateBuildAlertRule(ctx, owner.OrganizationID, codersdk.CreateBuildAlertRuleRequest{
+			TemplateID:   &templateID,
+			Name:         "failures",
+			Metric:       codersdk.BuildAlertRuleMetricFailureRate,
+			Threshold:    150,
+			WindowMillis: time.Hour.Milliseconds(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := member.CreateBuildAlertRule(ctx, owner.OrganizationID, codersdk.CreateBuildAlertRuleRequest{
+			Name:         "failures",
+			Metric:       codersdk.BuildAlertRuleMetricFailureRate,
+			Threshold:    10,
+			WindowMillis: time.Hour.Milliseconds(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,267 @@
+// Package buildalerts periodically evaluates build alert rules against
+// workspace build metrics and notifies template administrators when a rule
+// is breached.
+package buildalerts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	delay = time.Minute
+)
+
+// New creates a new periodically evaluating instance. Every rule is
+// evaluated over the builds in its window, and template administrators of
+// the rule's organization are notified when the rule becomes breached.
+// It is the caller's responsibility to call Close on the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock) io.Closer {
+	closed := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system evaluates build alert rules without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	// Start the ticker with the initial delay.
+	ticker := clk.NewTicker(delay)
+	doTick := func(start time.Time) {
+		defer ticker.Reset(delay)
+		// Start a transaction to grab advisory lock, we don't want to
+		// evaluate rules on multiple replicas at the same time.
+		if err := db.InTx(func(tx database.Store) error {
+			ok, err := tx.TryAcquireLock(ctx, database.LockIDBuildAlerts)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				logger.Debug(ctx, "unable to acquire lock for evaluating build alert rules, skipping")
+				return nil
+			}
+
+			return evaluate(ctx, logger, tx, enqueuer, start)
+		}, database.DefaultTXOptions().WithID("build_alerts")); err != nil {
+			logger.Error(ctx, "failed to evaluate build alert rules", slog.Error(err))
+			return
+		}
+		logger.Debug(ctx, "evaluated build alert rules", slog.F("duration", clk.Since(start)))
+	}
+
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		// Force an initial tick.
+		doTick(dbtime.Time(clk.Now()).UTC())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick := <-ticker.C:
+				ticker.Stop()
+				doTick(dbtime.Time(tick).UTC())
+			}
+		}
+	}()
+	return &instance{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+type instance struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (i *instance) Close() error {
+	i.cancel()
+	<-i.closed
+	return nil
+}
+
+func evaluate(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, now time.Time) error {
+	rules, err := db.GetBuildAlertRules(ctx)
+	if err != nil {
+		return xerrors.Errorf("get build alert rules: %w", err)
+	}
+	for _, rule := range rules {
+		if err := evaluateRule(ctx, logger, db, enqueuer, rule, now); err != nil {
+			// A single rule should not prevent the others from being
+			// evaluated.
+			logger.Error(ctx, "failed to evaluate build alert rule",
+				slog.F("rule_id", rule.ID),
+				slog.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+func evaluateRule(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, rule database.BuildAlertRule, now time.Time) error {
+	window := time.Duration(rule.WindowMs) * time.Millisecond
+	metrics, err := db.GetBuildAlertRuleMetrics(ctx, database.GetBuildAlertRuleMetricsParams{
+		Since:          now.Add(-window),
+		Now:            now,
+		OrganizationID: rule.OrganizationID,
+		TemplateID:     rule.TemplateID,
+	})
+	if err != nil {
+		return xerrors.Errorf("get metrics: %w", err)
+	}
+
+	value := metricValue(rule.Metric, metrics)
+	breachedAt := rule.BreachedAt
+	switch {
+	case !value.Valid || value.Float64 <= rule.Threshold:
+		breachedAt.Valid = false
+	case !breachedAt.Valid:
+		// Only notify on the transition, the rule stays breached until
+		// the metric recovers.
+		breachedAt.Time = now
+		breachedAt.Valid = true
+		if err := notify(ctx, db, enqueuer, rule, value.Float64, window); err != nil {
+			return xerrors.Errorf("notify: %w", err)
+		}
+		logger.Info(ctx, "build alert rule breached",
+			slog.F("rule_id", rule.ID),
+			slog.F("value", value.Float64),
+			slog.F("threshold", rule.Threshold),
+		)
+	}
+
+	err = db.UpdateBuildAlertRuleEvaluation(ctx, database.UpdateBuildAlertRuleEvaluationParams{
+		ID:              rule.ID,
+		LastEvaluatedAt: sql.NullTime{Time: now, Valid: true},
+		LastValue:       value,
+		BreachedAt:      breachedAt,
+	})
+	if err != nil {
+		return xerrors.Errorf("update evaluation: %w", err)
+	}
+	return nil
+}
+
+// metricValue returns the current value of the rule's metric. It is not
+// valid if there were no builds to compute the metric over.
+func metricValue(metric database.BuildAlertRuleMetric, metrics database.GetBuildAlertRuleMetricsRow) sql.NullFloat64 {
+	switch metric {
+	case database.BuildAlertRuleMetricFailureRate:
+		if metrics.CompletedBuilds == 0 {
+			return sql.NullFloat64{}
+		}
+		return sql.NullFloat64{Float64: float64(metrics.FailedBuilds) / float64(metrics.CompletedBuilds) * 100, Valid: true}
+	case database.BuildAlertRuleMetricQueueWaitP95:
+		if metrics.QueuedBuilds == 0 {
+			return sql.NullFloat64{}
+		}
+		return sql.NullFloat64{Float64: metrics.QueueWaitP95Ms, Valid: true}
+	default:
+		return sql.NullFloat64{}
+	}
+}
+
+func notify(ctx context.Context, db database.Store, enqueuer notifications.Enqueuer, rule database.BuildAlertRule, value float64, window time.Duration) error {
+	org, err := db.GetOrganizationByID(ctx, rule.OrganizationID)
+	if err != nil {
+		return xerrors.Errorf("get organization: %w", err)
+	}
+	scope := fmt.Sprintf("all templates in organization %s", org.Name)
+	targets := []uuid.UUID{rule.ID, rule.OrganizationID}
+	if rule.TemplateID.Valid {
+		template, err := db.GetTemplateByID(ctx, rule.TemplateID.UUID)
+		if err != nil {
+			return xerrors.Errorf("get template: %w", err)
+		}
+		scope = fmt.Sprintf("template %s", template.Name)
+		targets = append(targets, template.ID)
+	}
+
+	recipients, err := findRecipients(ctx, db, rule.OrganizationID)
+	if err != nil {
+		return err
+	}
+	labels := map[string]string{
+		"rule":      rule.Name,
+		"metric":    metricLabel(rule.Metric),
+		"scope":     scope,
+		"value":     formatValue(rule.Metric, value),
+		"threshold": formatValue(rule.Metric, rule.Threshold),
+		"window":    window.String(),
+	}
+	for _, userID := range recipients {
+		if _, err := enqueuer.Enqueue(ctx, userID, notifications.TemplateWorkspaceBuildAlertTriggered,
+			labels, "build_alerts", targets...,
+		); err != nil {
+			return xerrors.Errorf("enqueue notification for user %s: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// findRecipients returns the owners and template admins that are members
+// of the organization.
+func findRecipients(ctx context.Context, db database.Store, organizationID uuid.UUID) ([]uuid.UUID, error) {
+	users, err := db.GetUsers(ctx, database.GetUsersParams{
+		RbacRole: []string{codersdk.RoleOwner, codersdk.RoleTemplateAdmin},
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get template admins: %w", err)
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	memberships, err := db.GetOrganizationIDsByMemberIDs(ctx, userIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get organization memberships: %w", err)
+	}
+
+	var recipients []uuid.UUID
+	for _, membership := range memberships {
+		if slices.Contains(membership.OrganizationIDs, organizationID) {
+			recipients = append(recipients, membership.UserID)
+		}
+	}
+	slices.SortFunc(recipients, func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	})
+	return slices.Compact(recipients), nil
+}
+
+func metricLabel(metric database.BuildAlertRuleMetric) string {
+	switch metric {
+	case database.BuildAlertRuleMetricFailureRate:
+		return "failure rate"
+	case database.BuildAlertRuleMetricQueueWaitP95:
+		return "95th percentile queue wait"
+	default:
+		return string(metric)
+	}
+}
+
+func formatValue(metric database.BuildAlertRuleMetric, value float64) string {
+	if metric == database.BuildAlertRuleMetricQueueWaitP95 {
+		return (time.Duration(value) * time.Millisecond).Round(time.Second).String()
+	}
+	return fmt.Sprintf("%.1f%%", value)
+}
@@ -0,0 +1,144 @@
+package buildalerts
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FailureRate", func(t *testing.T) {
+		t.Parallel()
+
+		// nolint:gocritic // evaluate is called by the system.
+		ctx := dbauthz.AsSystemRestricted(context.Background())
+		logger := testutil.Logger(t)
+		db, ps := dbtestutil.NewDB(t)
+		enqueuer := &notificationstest.FakeEnqueuer{}
+		now := dbtime.Now()
+
+		org := dbgen.Organization(t, db, database.Organization{})
+		templateAdmin := dbgen.User(t, db, database.User{RBACRoles: []string{rbac.RoleTemplateAdmin().Name}})
+		dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: templateAdmin.ID, OrganizationID: org.ID})
+		// A template admin of another organization is not notified.
+		dbgen.User(t, db, database.User{RBACRoles: []string{rbac.RoleTemplateAdmin().Name}})
+		member := dbgen.User(t, db, database.User{})
+		dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: member.ID, OrganizationID: org.ID})
+
+		template := dbgen.Template(t, db, database.Template{Name: "docker", CreatedBy: templateAdmin.ID, OrganizationID: org.ID})
+		version := dbgen.TemplateVersion(t, db, database.TemplateVersion{CreatedBy: templateAdmin.ID, OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true}, JobID: uuid.New()})
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{TemplateID: template.ID, OwnerID: member.ID, OrganizationID: org.ID})
+		for i, failed := range []bool{true, false, false, true} {
+			job := database.ProvisionerJob{
+				OrganizationID: org.ID,
+				CreatedAt:      now.Add(-10 * time.Minute),
+				StartedAt:      sql.NullTime{Time: now.Add(-9 * time.Minute), Valid: true},
+				CompletedAt:    sql.NullTime{Time: now.Add(-8 * time.Minute), Valid: true},
+			}
+			if failed {
+				job.Error = sql.NullString{String: "badness", Valid: true}
+			}
+			job = dbgen.ProvisionerJob(t, db, ps, job)
+			dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: workspace.ID, BuildNumber: int32(i + 1), TemplateVersionID: version.ID, JobID: job.ID})
+		}
+
+		rule := dbgen.BuildAlertRule(t, db, database.BuildAlertRule{
+			OrganizationID: org.ID,
+			TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+			Name:           "failures",
+			Metric:         database.BuildAlertRuleMetricFailureRate,
+			Threshold:      10,
+			WindowMs:       (15 * time.Minute).Milliseconds(),
+		})
+
+		// Half of the builds failed, so the rule is breached.
+		require.NoError(t, evaluate(ctx, logger, db, enqueuer, now))
+		sent := enqueuer.Sent()
+		require.Len(t, sent, 1)
+		require.Equal(t, templateAdmin.ID, sent[0].UserID)
+		require.Equal(t, notifications.TemplateWorkspaceBuildAlertTriggered, sent[0].TemplateID)
+		require.Equal(t, map[string]string{
+			"rule":      "failures",
+			"metric":    "failure rate",
+			"scope":     "template docker",
+			"value":     "50.0%",
+			"threshold": "10.0%",
+			"window":    "15m0s",
+		}, sent[0].Labels)
+		rule, err := db.GetBuildAlertRuleByID(ctx, rule.ID)
+		require.NoError(t, err)
+		require.True(t, rule.BreachedAt.Valid)
+		require.Equal(t, sql.NullFloat64{Float64: 50, Valid: true}, rule.LastValue)
+
+		// The rule is still breached, but it was already notified.
+		enqueuer.Clear()
+		require.NoError(t, evaluate(ctx, logger, db, enqueuer, now.Add(time.Minute)))
+		require.Empty(t, enqueuer.Sent())
+
+		// The builds left the window, so the rule recovered.
+		require.NoError(t, evaluate(ctx, logger, db, enqueuer, now.Add(time.Hour)))
+		require.Empty(t, enqueuer.Sent())
+		rule, err = db.GetBuildAlertRuleByID(ctx, rule.ID)
+		require.NoError(t, err)
+		require.False(t, rule.BreachedAt.Valid)
+		require.False(t, rule.LastValue.Valid)
+		require.True(t, rule.LastEvaluatedAt.Valid)
+	})
+
+	t.Run("QueueWait", func(t *testing.T) {
+		t.Parallel()
+
+		// nolint:gocritic // evaluate is called by the system.
+		ctx := dbauthz.AsSystemRestricted(context.Background())
+		logger := testutil.Logger(t)
+		db, ps := dbtestutil.NewDB(t)
+		enqueuer := &notificationstest.FakeEnqueuer{}
+		now := dbtime.Now()
+
+		org := dbgen.Organization(t, db, database.Organization{Name: "acme"})
+		owner := dbgen.User(t, db, database.User{RBACRoles: []string{rbac.RoleOwner().Name}})
+		dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: owner.ID, OrganizationID: org.ID})
+
+		template := dbgen.Template(t, db, database.Template{CreatedBy: owner.ID, OrganizationID: org.ID})
+		version := dbgen.TemplateVersion(t, db, database.TemplateVersion{CreatedBy: owner.ID, OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true}, JobID: uuid.New()})
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{TemplateID: template.ID, OwnerID: owner.ID, OrganizationID: org.ID})
+		// A job that is still pending has been waiting for ten minutes.
+		job := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			CreatedAt:      now.Add(-10 * time.Minute),
+		})
+		dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: workspace.ID, BuildNumber: 1, TemplateVersionID: version.ID, JobID: job.ID})
+
+		dbgen.BuildAlertRule(t, db, database.BuildAlertRule{
+			OrganizationID: org.ID,
+			Name:           "slow queue",
+			Metric:         database.BuildAlertRuleMetricQueueWaitP95,
+			Threshold:      float64((5 * time.Minute).Milliseconds()),
+			WindowMs:       time.Hour.Milliseconds(),
+		})
+
+		require.NoError(t, evaluate(ctx, logger, db, enqueuer, now))
+		sent := enqueuer.Sent()
+		require.Len(t, sent, 1)
+		require.Equal(t, owner.ID, sent[0].UserID)
+		require.Equal(t, "all templates in organization acme", sent[0].Labels["scope"])
+		require.Equal(t, "10m0s", sent[0].Labels["value"])
+		require.Equal(t, "5m0s", sent[0].Labels["threshold"])
+	})
+}
@@ -0,0 +1,295 @@
+// Package buildarchive moves the bulky data of old workspace builds out of
+// the hot tables into compressed files, and restores it on demand.
+//
+// A build is archived once it is no longer the latest build of its
+// workspace and its job completed more than a configured age ago. Its
+// provisioner state and resource metadata are compressed into a file, and
+// its job logs are handed to joblogarchive. The build, job, resource and
+// agent rows are kept, so build history keeps rendering from summaries.
+package buildarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/joblogarchive"
+	"github.com/coder/quartz"
+)
+
+const (
+	delay = 10 * time.Minute
+	// batchSize is the maximum number of builds archived per tick.
+	batchSize = 100
+	// Mimetype is the mimetype of archived build files.
+	Mimetype = "application/gzip"
+)
+
+// ErrNotArchived is returned by Rehydrate for builds without an archive.
+var ErrNotArchived = xerrors.New("workspace build is not archived")
+
+// data is the content of an archive file.
+type data struct {
+	ProvisionerState []byte                                `json:"provisioner_state"`
+	ResourceMetadata []database.WorkspaceResourceMetadatum `json:"resource_metadata"`
+}
+
+// New creates a new periodically archiving instance. Builds that are not
+// the latest build of their workspace and whose job completed more than
+// age ago are archived.
+// It is the caller's responsibility to call Close on the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.Clock, age time.Duration) io.Closer {
+	closed := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system archives old builds without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	// Start the ticker with the initial delay.
+	ticker := clk.NewTicker(delay)
+	doTick := func(start time.Time) {
+		defer ticker.Reset(delay)
+		var archived int
+		// Start a transaction to grab advisory lock, we don't want to run
+		// multiple archivers at the same time (multiple replicas).
+		if err := db.InTx(func(tx database.Store) error {
+			ok, err := tx.TryAcquireLock(ctx, database.LockIDWorkspaceBuildArchive)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				logger.Debug(ctx, "unable to acquire lock for archiving workspace builds, skipping")
+				return nil
+			}
+
+			builds, err := tx.GetWorkspaceBuildsToArchive(ctx, database.GetWorkspaceBuildsToArchiveParams{
+				CompletedBefore: start.Add(-age),
+				LimitCount:      batchSize,
+			})
+			if err != nil {
+				return xerrors.Errorf("get workspace builds to archive: %w", err)
+			}
+			for _, build := range builds {
+				if err := Archive(ctx, tx, build.ID, start); err != nil {
+					return xerrors.Errorf("archive workspace build %s: %w", build.ID, err)
+				}
+			}
+			archived = len(builds)
+
+			return nil
+		}, database.DefaultTXOptions().WithID("workspace_build_archive")); err != nil {
+			logger.Error(ctx, "failed to archive workspace builds", slog.Error(err))
+			return
+		}
+		logger.Debug(ctx, "archived workspace builds",
+			slog.F("builds", archived),
+			slog.F("duration", clk.Since(start)),
+		)
+	}
+
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		// Force an initial tick.
+		doTick(dbtime.Time(clk.Now()).UTC())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick := <-ticker.C:
+				ticker.Stop()
+				doTick(dbtime.Time(tick).UTC())
+			}
+		}
+	}()
+	return &instance{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+type instance struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (i *instance) Close() error {
+	i.cancel()
+	<-i.closed
+	return nil
+}
+
+// Archive compresses the provisioner state and resource metadata of a
+// build into a file owned by the build initiator, removes them from the
+// database and archives the logs of the build job. It should be called in
+// a transaction.
+func Archive(ctx context.Context, db database.Store, buildID uuid.UUID, now time.Time) error {
+	build, err := db.GetWorkspaceBuildByID(ctx, buildID)
+	if err != nil {
+		return xerrors.Errorf("get build: %w", err)
+	}
+	resources, err := db.GetWorkspaceResourcesByJobID(ctx, build.JobID)
+	if err != nil {
+		return xerrors.Errorf("get resources: %w", err)
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	metadata, err := db.GetWorkspaceResourceMetadataByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		return xerrors.Errorf("get resource metadata: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(data{
+		ProvisionerState: build.ProvisionerState,
+		ResourceMetadata: metadata,
+	}); err != nil {
+		return xerrors.Errorf("encode build: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return xerrors.Errorf("compress build: %w", err)
+	}
+	hash := sha256.Sum256(buf.Bytes())
+
+	file, err := db.InsertFile(ctx, database.InsertFileParams{
+		ID:        uuid.New(),
+		Hash:      hex.EncodeToString(hash[:]),
+		CreatedAt: now,
+		CreatedBy: build.InitiatorID,
+		Mimetype:  Mimetype,
+		Data:      buf.Bytes(),
+	})
+	if err != nil {
+		return xerrors.Errorf("insert file: %w", err)
+	}
+	_, err = db.InsertWorkspaceBuildArchive(ctx, database.InsertWorkspaceBuildArchiveParams{
+		WorkspaceBuildID:      build.ID,
+		FileID:                file.ID,
+		StateSize:             int32(len(build.ProvisionerState)), // #nosec G115 - A bytea column never exceeds math.MaxInt32 bytes.
+		ResourceMetadataCount: int32(len(metadata)),               // #nosec G115 - A build never has more than math.MaxInt32 metadata entries.
+		ArchivedAt:            now,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert archive: %w", err)
+	}
+
+	// The updated_at of the build is kept, since it is shown to users.
+	err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
+		ID:               build.ID,
+		ProvisionerState: nil,
+		UpdatedAt:        build.UpdatedAt,
+	})
+	if err != nil {
+		return xerrors.Errorf("clear provisioner state: %w", err)
+	}
+	if len(resourceIDs) > 0 {
+		if err := db.DeleteWorkspaceResourceMetadataByResourceIDs(ctx, resourceIDs); err != nil {
+			return xerrors.Errorf("delete resource metadata: %w", err)
+		}
+	}
+
+	// Logs may have been archived already by joblogarchive.
+	_, err = db.GetProvisionerJobLogArchiveByJobID(ctx, build.JobID)
+	if err == nil {
+		return nil
+	}
+	if !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get log archive: %w", err)
+	}
+	if err := joblogarchive.ArchiveJobLogs(ctx, db, build.JobID, build.InitiatorID, now); err != nil {
+		return xerrors.Errorf("archive logs: %w", err)
+	}
+	return nil
+}
+
+// Rehydrate restores the provisioner state and resource metadata of an
+// archived build and deletes the archive. Archived job logs stay in their
+// archive, since they are read from it transparently. It returns
+// ErrNotArchived if the build has no archive. It should be called in a
+// transaction.
+func Rehydrate(ctx context.Context, db database.Store, buildID uuid.UUID) error {
+	archive, err := db.GetWorkspaceBuildArchiveByBuildID(ctx, buildID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return ErrNotArchived
+	}
+	if err != nil {
+		return xerrors.Errorf("get archive: %w", err)
+	}
+	build, err := db.GetWorkspaceBuildByID(ctx, buildID)
+	if err != nil {
+		return xerrors.Errorf("get build: %w", err)
+	}
+	file, err := db.GetFileByID(ctx, archive.FileID)
+	if err != nil {
+		return xerrors.Errorf("get archive file: %w", err)
+	}
+	archived, err := decode(file.Data)
+	if err != nil {
+		return err
+	}
+
+	err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
+		ID:               build.ID,
+		ProvisionerState: archived.ProvisionerState,
+		UpdatedAt:        build.UpdatedAt,
+	})
+	if err != nil {
+		return xerrors.Errorf("restore provisioner state: %w", err)
+	}
+
+	metadata := make(map[uuid.UUID]*database.InsertWorkspaceResourceMetadataParams)
+	resourceIDs := make([]uuid.UUID, 0)
+	for _, metadatum := range archived.ResourceMetadata {
+		params, ok := metadata[metadatum.WorkspaceResourceID]
+		if !ok {
+			params = &database.InsertWorkspaceResourceMetadataParams{
+				WorkspaceResourceID: metadatum.WorkspaceResourceID,
+			}
+			metadata[metadatum.WorkspaceResourceID] = params
+			resourceIDs = append(resourceIDs, metadatum.WorkspaceResourceID)
+		}
+		params.Key = append(params.Key, metadatum.Key)
+		params.Value = append(params.Value, metadatum.Value.String)
+		params.Sensitive = append(params.Sensitive, metadatum.Sensitive)
+	}
+	for _, resourceID := range resourceIDs {
+		if _, err := db.InsertWorkspaceResourceMetadata(ctx, *metadata[resourceID]); err != nil {
+			return xerrors.Errorf("restore resource metadata: %w", err)
+		}
+	}
+
+	if err := db.DeleteWorkspaceBuildArchiveByBuildID(ctx, buildID); err != nil {
+		return xerrors.Errorf("delete archive: %w", err)
+	}
+	return nil
+}
+
+func decode(b []byte) (data, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return data{}, xerrors.Errorf("decompress build: %w", err)
+	}
+	defer zr.Close()
+	var archived data
+	if err := json.NewDecoder(zr).Decode(&archived); err != nil {
+		return data{}, xerrors.Errorf("decode build: %w", err)
+	}
+	return archived, nil
+}
@@ -0,0 +1,178 @@
+package buildarchive_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/buildarchive"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/joblogarchive"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+//nolint:paralleltest // It uses LockIDWorkspaceBuildArchive.
+func TestArchive(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+	db, _ := dbtestutil.NewDB(t)
+	user := dbgen.User(t, db, database.User{})
+	org := dbgen.Organization(t, db, database.Organization{})
+	tpl := dbgen.Template(t, db, database.Template{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	tv := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	ws := dbgen.Workspace(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+		TemplateID:     tpl.ID,
+	})
+
+	build := func(number int32, completedAt time.Time) (database.WorkspaceBuild, database.WorkspaceResourceMetadatum) {
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+			StartedAt:      sql.NullTime{Time: completedAt.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: completedAt, Valid: true},
+		})
+		b := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       ws.ID,
+			TemplateVersionID: tv.ID,
+			JobID:             job.ID,
+			InitiatorID:       user.ID,
+			BuildNumber:       number,
+			ProvisionerState:  []byte("state"),
+		})
+		resource := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: job.ID})
+		metadata := dbgen.WorkspaceResourceMetadatums(t, db, database.WorkspaceResourceMetadatum{
+			WorkspaceResourceID: resource.ID,
+			Key:                 "key",
+			Value:               sql.NullString{String: "value", Valid: true},
+			Sensitive:           true,
+		})
+		_, err := db.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+			JobID:     job.ID,
+			CreatedAt: []time.Time{completedAt},
+			Source:    []database.LogSource{database.LogSourceProvisioner},
+			Level:     []database.LogLevel{database.LogLevelInfo},
+			Stage:     []string{"Planning"},
+			Output:    []string{"output"},
+			Fields:    []string{"{}"},
+		})
+		require.NoError(t, err)
+		return b, metadata[0]
+	}
+	oldBuild, oldMetadatum := build(1, now.Add(-60*24*time.Hour))
+	// The latest build is never archived, no matter how old it is.
+	latestBuild, _ := build(2, now.Add(-59*24*time.Hour))
+
+	done := awaitDoTick(ctx, t, clk)
+	archiver := buildarchive.New(context.Background(), testutil.Logger(t), db, clk, 30*24*time.Hour)
+	<-done
+	require.NoError(t, archiver.Close())
+
+	// The old build's state, metadata and logs were moved out.
+	archive, err := db.GetWorkspaceBuildArchiveByBuildID(ctx, oldBuild.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, len("state"), archive.StateSize)
+	require.EqualValues(t, 1, archive.ResourceMetadataCount)
+	file, err := db.GetFileByID(ctx, archive.FileID)
+	require.NoError(t, err)
+	require.Equal(t, buildarchive.Mimetype, file.Mimetype)
+	got, err := db.GetWorkspaceBuildByID(ctx, oldBuild.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.ProvisionerState)
+	metadata, err := db.GetWorkspaceResourceMetadataByResourceIDs(ctx, []uuid.UUID{oldMetadatum.WorkspaceResourceID})
+	require.NoError(t, err)
+	require.Empty(t, metadata)
+	resources, err := db.GetWorkspaceResourcesByJobID(ctx, oldBuild.JobID)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{JobID: oldBuild.JobID})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+	logs, err = joblogarchive.SearchLogs(ctx, db, database.SearchProvisionerJobLogsParams{JobID: oldBuild.JobID})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+
+	// The latest build was kept.
+	_, err = db.GetWorkspaceBuildArchiveByBuildID(ctx, latestBuild.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	got, err = db.GetWorkspaceBuildByID(ctx, latestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("state"), got.ProvisionerState)
+
+	// Rehydrating restores the build and removes the archive.
+	err = db.InTx(func(tx database.Store) error {
+		return buildarchive.Rehydrate(ctx, tx, oldBuild.ID)
+	}, nil)
+	require.NoError(t, err)
+	got, err = db.GetWorkspaceBuildByID(ctx, oldBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("state"), got.ProvisionerState)
+	metadata, err = db.GetWorkspaceResourceMetadataByResourceIDs(ctx, []uuid.UUID{oldMetadatum.WorkspaceResourceID})
+	require.NoError(t, err)
+	require.Len(t, metadata, 1)
+	assert.Equal(t, oldMetadatum.Key, metadata[0].Key)
+	assert.Equal(t, oldMetadatum.Value, metadata[0].Value)
+	assert.Equal(t, oldMetadatum.Sensitive, metadata[0].Sensitive)
+	_, err = db.GetWorkspaceBuildArchiveByBuildID(ctx, oldBuild.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = db.GetFileByID(ctx, archive.FileID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = buildarchive.Rehydrate(ctx, db, oldBuild.ID)
+	require.ErrorIs(t, err, buildarchive.ErrNotArchived)
+}
+
+func awaitDoTick(ctx context.Context, t *testing.T, clk *quartz.Mock) chan struct{} {
+	t.Helper()
+	ch := make(chan struct{})
+	trapNow := clk.Trap().Now()
+	trapStop := clk.Trap().TickerStop()
+	trapReset := clk.Trap().TickerReset()
+	go func() {
+		defer close(ch)
+		defer trapReset.Close()
+		defer trapStop.Close()
+		defer trapNow.Close()
+		// Wait for the initial tick signified by a call to Now().
+		trapNow.MustWait(ctx).MustRelease(ctx)
+		// doTick runs here. Wait for the next
+		// ticker reset event that signifies it's completed.
+		trapReset.MustWait(ctx).MustRelease(ctx)
+		// Ensure that the next tick happens in 10 minutes from start.
+		d, w := clk.AdvanceNext()
+		if !assert.Equal(t, 10*time.Minute, d) {
+			return
+		}
+		w.MustWait(ctx)
+		// Wait for the ticker stop event.
+		trapStop.MustWait(ctx).MustRelease(ctx)
+	}()
+
+	return ch
+}
@@ -0,0 +1,221 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get deployment build pause
+// @ID get-deployment-build-pause
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.BuildPause
+// @Router /deployment/build-pause [get]
+func (api *API) deploymentBuildPause(rw http.ResponseWriter, r *http.Request) {
+	api.buildPause(rw, r, uuid.NullUUID{})
+}
+
+// @Summary Update deployment build pause
+// @Description While builds are paused, new provisioner jobs are accepted but
+// @Description held pending. Provisioners do not acquire them and they are not
+// @Description timed out until builds are resumed.
+// @ID update-deployment-build-pause
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags General
+// @Param request body codersdk.UpdateBuildPauseRequest true "Build pause request"
+// @Success 200 {object} codersdk.BuildPause
+// @Success 304
+// @Router /deployment/build-pause [put]
+func (api *API) putDeploymentBuildPause(rw http.ResponseWriter, r *http.Request) {
+	api.putBuildPause(rw, r, uuid.NullUUID{}, rbac.ResourceDeploymentConfig)
+}
+
+// @Summary Get organization build pause
+// @ID get-organization-build-pause
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.BuildPause
+// @Router /organizations/{organization}/build-pause [get]
+func (api *API) organizationBuildPause(rw http.ResponseWriter, r *http.Request) {
+	org := httpmw.OrganizationParam(r)
+	api.buildPause(rw, r, uuid.NullUUID{UUID: org.ID, Valid: true})
+}
+
+// @Summary Update organization build pause
+// @Description While builds are paused, new provisioner jobs are accepted but
+// @Description held pending. Provisioners do not acquire them and they are not
+// @Description timed out until builds are resumed.
+// @ID update-organization-build-pause
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.UpdateBuildPauseRequest true "Build pause request"
+// @Success 200 {object} codersdk.BuildPause
+// @Success 304
+// @Router /organizations/{organization}/build-pause [put]
+func (api *API) putOrganizationBuildPause(rw http.ResponseWriter, r *http.Request) {
+	org := httpmw.OrganizationParam(r)
+	api.putBuildPause(rw, r, uuid.NullUUID{UUID: org.ID, Valid: true}, rbac.ResourceProvisionerDaemon.InOrg(org.ID))
+}
+
+func (api *API) buildPause(rw http.ResponseWriter, r *http.Request, organizationID uuid.NullUUID) {
+	ctx := r.Context()
+
+	pause, err := api.Database.GetProvisionerBuildPause(ctx, organizationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.BuildPause{})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching build pause.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerBuildPause(pause))
+}
+
+// putBuildPause pauses or resumes builds in the scope of organizationID, or
+// across the deployment if it is not set. object is what the caller must be
+// allowed to update.
+func (api *API) putBuildPause(rw http.ResponseWriter, r *http.Request, organizationID uuid.NullUUID, object rbac.Object) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
+	if !api.Authorize(r, policy.ActionUpdate, object) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateBuildPauseRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	current, err := api.Database.GetProvisionerBuildPause(ctx, organizationID)
+	paused := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching build pause.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if paused == req.Paused {
+		// See: https://www.rfc-editor.org/rfc/rfc7232#section-4.1
+		httpapi.Write(ctx, rw, http.StatusNotModified, nil)
+		return
+	}
+
+	action := database.AuditActionCreate
+	if !req.Paused {
+		action = database.AuditActionDelete
+	}
+	auditor := api.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.ProvisionerBuildPause](rw, &audit.RequestParams{
+		Audit:          *auditor,
+		Log:            api.Logger,
+		Request:        r,
+		Action:         action,
+		OrganizationID: organizationID.UUID,
+	})
+	defer commitAudit()
+
+	if req.Paused {
+		pause, err := api.Database.InsertProvisionerBuildPause(ctx, database.InsertProvisionerBuildPauseParams{
+			ID:             uuid.New(),
+			OrganizationID: organizationID,
+			Reason:         req.Reason,
+			CreatedBy:      uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+			CreatedAt:      dbtime.Now(),
+		})
+		if database.IsUniqueViolation(err) {
+			// Builds were paused concurrently.
+			httpapi.Write(ctx, rw, http.StatusNotModified, nil)
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error pausing builds.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		aReq.New = pause
+
+		httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerBuildPause(pause))
+		return
+	}
+
+	aReq.Old = current
+	var held []database.ProvisionerJob
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteProvisionerBuildPause(ctx, organizationID)
+		if err != nil {
+			return err
+		}
+		// The jobs have been pending for as long as builds were paused,
+		// restart their timeout so the reaper gives provisioners a chance
+		// to acquire them.
+		held, err = tx.UpdatePendingProvisionerJobsUpdatedAt(ctx, database.UpdatePendingProvisionerJobsUpdatedAtParams{
+			UpdatedAt:      dbtime.Now(),
+			OrganizationID: organizationID,
+		})
+		return err
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resuming builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Wake up idle provisioners rather than waiting for them to poll.
+	for _, job := range held {
+		if err := provisionerjobs.PostJob(api.Pubsub, job); err != nil {
+			api.Logger.Warn(ctx, "failed to post held provisioner job", slog.F("job_id", job.ID), slog.Error(err))
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.BuildPause{})
+}
+
+func convertProvisionerBuildPause(pause database.ProvisionerBuildPause) codersdk.BuildPause {
+	converted := codersdk.BuildPause{
+		Paused:   true,
+		Reason:   pause.Reason,
+		PausedAt: ptr.Ref(pause.CreatedAt),
+	}
+	if pause.CreatedBy.Valid {
+		converted.PausedBy = ptr.Ref(pause.CreatedBy.UUID)
+	}
+	return converted
+}
@@ -0,0 +1,117 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestBuildPause(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PermissionDenied", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		// Members can see whether builds are paused...
+		pause, err := memberClient.DeploymentBuildPause(ctx)
+		require.NoError(t, err)
+		require.False(t, pause.Paused)
+		pause, err = memberClient.OrganizationBuildPause(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.False(t, pause.Paused)
+
+		// ...but can't pause them.
+		var sdkError *codersdk.Error
+		err = memberClient.PutDeploymentBuildPause(ctx, codersdk.UpdateBuildPauseRequest{Paused: true})
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusForbidden, sdkError.StatusCode())
+		err = memberClient.PutOrganizationBuildPause(ctx, owner.OrganizationID, codersdk.UpdateBuildPauseRequest{Paused: true})
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusForbidden, sdkError.StatusCode())
+	})
+
+	t.Run("Audit", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		err := client.PutDeploymentBuildPause(ctx, codersdk.UpdateBuildPauseRequest{
+			Paused: true,
+			Reason: "Storage migration",
+		})
+		require.NoError(t, err)
+
+		pause, err := client.DeploymentBuildPause(ctx)
+		require.NoError(t, err)
+		require.True(t, pause.Paused)
+		require.Equal(t, "Storage migration", pause.Reason)
+		require.NotNil(t, pause.PausedAt)
+		require.NotNil(t, pause.PausedBy)
+		require.Equal(t, owner.UserID, *pause.PausedBy)
+
+		// Pausing again is a no-op.
+		err = client.PutDeploymentBuildPause(ctx, codersdk.UpdateBuildPauseRequest{
+			Paused: true,
+			Reason: "Something else",
+		})
+		require.NoError(t, err)
+		pause, err = client.DeploymentBuildPause(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "Storage migration", pause.Reason)
+
+		err = client.PutDeploymentBuildPause(ctx, codersdk.UpdateBuildPauseRequest{Paused: false})
+		require.NoError(t, err)
+		pause, err = client.DeploymentBuildPause(ctx)
+		require.NoError(t, err)
+		require.False(t, pause.Paused)
+
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			ResourceType: database.ResourceTypeProvisionerBuildPause,
+			Action:       database.AuditActionCreate,
+		}))
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			ResourceType: database.ResourceTypeProvisionerBuildPause,
+			Action:       database.AuditActionDelete,
+		}))
+	})
+
+	t.Run("HoldsJobs", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		err := client.PutOrganizationBuildPause(ctx, owner.OrganizationID, codersdk.UpdateBuildPauseRequest{Paused: true})
+		require.NoError(t, err)
+
+		// The job is accepted, but not acquired by the provisioner.
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		require.Never(t, func() bool {
+			version, err := client.TemplateVersion(ctx, version.ID)
+			return err != nil || version.Job.Status != codersdk.ProvisionerJobPending
+		}, testutil.IntervalSlow, testutil.IntervalFast)
+
+		err = client.PutOrganizationBuildPause(ctx, owner.OrganizationID, codersdk.UpdateBuildPauseRequest{Paused: false})
+		require.NoError(t, err)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	})
+}
@@ -0,0 +1,42 @@
+package coderd
+
+import (
+	"net/http"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	provisionerproto "github.com/coder/coder/v2/provisionerd/proto"
+	tailnetproto "github.com/coder/coder/v2/tailnet/proto"
+)
+
+// @Summary Get deployment capabilities
+// @ID get-deployment-capabilities
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.Capabilities
+// @Router /capabilities [get]
+func (api *API) capabilities(rw http.ResponseWriter, r *http.Request) {
+	experiments := make(map[codersdk.Experiment]bool, len(codersdk.ExperimentsKnown))
+	for _, ex := range codersdk.ExperimentsKnown {
+		experiments[ex] = api.Experiments.Enabled(ex)
+	}
+	// Entitlements are kept up to date by the enterprise API, and are empty
+	// otherwise.
+	features := make(map[codersdk.FeatureName]bool, len(codersdk.FeatureNames))
+	for _, name := range codersdk.FeatureNames {
+		features[name] = api.Entitlements.Enabled(name)
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.Capabilities{
+		APIVersions: codersdk.APIVersions{
+			REST:        []string{"v2"},
+			Agent:       agentproto.CurrentVersion.String(),
+			Provisioner: provisionerproto.CurrentVersion.String(),
+			Tailnet:     tailnetproto.CurrentVersion.String(),
+		},
+		Experiments: experiments,
+		Features:    features,
+	})
+}
@@ -0,0 +1,54 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionerd/proto"
+	tailnetproto "github.com/coder/coder/v2/tailnet/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	cfg := coderdtest.DeploymentValues(t)
+	cfg.Experiments = []string{string(codersdk.ExperimentWebPush)}
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues: cfg,
+	})
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	capabilities, err := client.Capabilities(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"v2"}, capabilities.APIVersions.REST)
+	require.Equal(t, proto.CurrentVersion.String(), capabilities.APIVersions.Provisioner)
+	require.Equal(t, tailnetproto.CurrentVersion.String(), capabilities.APIVersions.Tailnet)
+	require.NotEmpty(t, capabilities.APIVersions.Agent)
+
+	// Every known experiment is listed, so clients can tell disabled
+	// experiments apart from unsupported ones.
+	require.Len(t, capabilities.Experiments, len(codersdk.ExperimentsKnown))
+	require.True(t, capabilities.ExperimentEnabled(codersdk.ExperimentWebPush))
+	enabled, ok := capabilities.Experiments[codersdk.ExperimentExample]
+	require.True(t, ok)
+	require.False(t, enabled)
+	require.False(t, capabilities.ExperimentEnabled("unknown"))
+
+	// Without a license no features are enabled.
+	require.Len(t, capabilities.Features, len(codersdk.FeatureNames))
+	for name, enabled := range capabilities.Features {
+		require.False(t, enabled, name)
+	}
+
+	// Experiments are also surfaced in the build info.
+	buildInfo, err := client.BuildInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.Experiment{codersdk.ExperimentWebPush}, buildInfo.Experiments)
+}
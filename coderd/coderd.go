@@ -79,6 +79,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/rbac/rolestore"
+	"github.com/coder/coder/v2/coderd/readonly"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
@@ -87,6 +88,7 @@ import (
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 	"github.com/coder/coder/v2/coderd/workspacestats"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/healthsdk"
 	"github.com/coder/coder/v2/provisionerd/proto"
@@ -132,6 +134,17 @@ func init() {
 
 var expDERPOnce = sync.Once{}
 
+// readOnlyExemptPaths are the API routes that mutate state but are still
+// served in read-only mode. The read-only settings must stay writable,
+// otherwise read-only mode could never be turned off, and admins must be able
+// to sign in to do so.
+var readOnlyExemptPaths = []string{
+	"/api/v2/deployment/read-only",
+	"/api/v2/users/login",
+	"/api/v2/users/oidc/callback",
+	"/api/v2/users/oauth2/github/callback",
+}
+
 // Options are requires parameters for Coder to start.
 type Options struct {
 	AccessURL *url.URL
@@ -249,7 +262,8 @@ type Options struct {
 	NewTicker func(duration time.Duration) (tick <-chan time.Time, done func())
 
 	// DatabaseRolluper rolls up template usage stats from raw agent and app
-	// stats. This is used to provide insights in the WebUI.
+	// stats, and build duration stats from workspace builds. This is used to
+	// provide insights in the WebUI.
 	DatabaseRolluper *dbrollup.Rolluper
 	// WorkspaceUsageTracker tracks workspace usage by the CLI.
 	WorkspaceUsageTracker *workspacestats.UsageTracker
@@ -270,6 +284,11 @@ type Options struct {
 
 	// WebPushDispatcher is a way to send notifications over Web Push.
 	WebPushDispatcher webpush.Dispatcher
+
+	// WorkspaceBuildPreflightChecks are run in addition to the built-in
+	// checks before the provisioner job of a user-requested workspace build
+	// is inserted, e.g. to enforce organization policy.
+	WorkspaceBuildPreflightChecks []wsbuilder.PreflightCheck
 }
 
 // @title Coder API
@@ -584,6 +603,21 @@ func New(options *Options) *API {
 		),
 		dbRolluper: options.DatabaseRolluper,
 	}
+	api.ReadOnlyMode = readonly.New(
+		ctx,
+		options.Logger.Named("readonly"),
+		options.Database,
+		options.Pubsub,
+		options.Clock,
+	)
+	api.workspaceBuildQueueDone = make(chan struct{})
+	go api.runWorkspaceBuildQueue(ctx)
+	api.workspaceMigrationsDone = make(chan struct{})
+	go api.runWorkspaceMigrations(ctx)
+	api.accessRequestExpiryDone = make(chan struct{})
+	go api.runAccessRequestExpiry(ctx)
+	api.userActivityExportsDone = make(chan struct{})
+	go api.runUserActivityExports(ctx)
 	api.WorkspaceAppsProvider = workspaceapps.NewDBTokenProvider(
 		options.Logger.Named("workspaceapps"),
 		options.AccessURL,
@@ -613,6 +647,7 @@ func New(options *Options) *API {
 		DeploymentID:          api.DeploymentID,
 		WebPushPublicKey:      api.WebpushDispatcher.PublicKey(),
 		Telemetry:             api.Telemetry.Enabled(),
+		Experiments:           experiments,
 	}
 	api.SiteHandler = site.New(&site.Options{
 		BinFS:             binFS,
@@ -673,6 +708,12 @@ func New(options *Options) *API {
 					StaleInterval:          provisionerdserver.StaleInterval,
 					// TimeNow set to default, see healthcheck/provisioner.go
 				},
+				ProvisionerJobs: healthcheck.ProvisionerJobsReportOptions{
+					Store:                       options.Database,
+					QueueDepthThreshold:         options.DeploymentValues.Healthcheck.ThresholdProvisionerQueueDepth.Value(),
+					PendingJobAgeThreshold:      options.DeploymentValues.Healthcheck.ThresholdProvisionerPendingJobAge.Value(),
+					JobReaperStalenessThreshold: options.DeploymentValues.Healthcheck.ThresholdJobReaperStaleness.Value(),
+				},
 			})
 		}
 	}
@@ -778,6 +819,8 @@ func New(options *Options) *API {
 		OAuth2Configs:                 oauthConfigs,
 		RedirectToLogin:               false,
 		DisableSessionExpiryRefresh:   options.DeploymentValues.Sessions.DisableExpiryRefresh.Value(),
+		SessionTokenBinding:           codersdk.SessionTokenBinding(options.DeploymentValues.Sessions.TokenBinding),
+		ReadOnlyMode:                  api.ReadOnlyMode,
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
@@ -788,6 +831,8 @@ func New(options *Options) *API {
 		OAuth2Configs:                 oauthConfigs,
 		RedirectToLogin:               true,
 		DisableSessionExpiryRefresh:   options.DeploymentValues.Sessions.DisableExpiryRefresh.Value(),
+		SessionTokenBinding:           codersdk.SessionTokenBinding(options.DeploymentValues.Sessions.TokenBinding),
+		ReadOnlyMode:                  api.ReadOnlyMode,
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
@@ -798,6 +843,8 @@ func New(options *Options) *API {
 		OAuth2Configs:                 oauthConfigs,
 		RedirectToLogin:               false,
 		DisableSessionExpiryRefresh:   options.DeploymentValues.Sessions.DisableExpiryRefresh.Value(),
+		SessionTokenBinding:           codersdk.SessionTokenBinding(options.DeploymentValues.Sessions.TokenBinding),
+		ReadOnlyMode:                  api.ReadOnlyMode,
 		Optional:                      true,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
@@ -915,6 +962,7 @@ func New(options *Options) *API {
 	r.Route("/oauth2", func(r chi.Router) {
 		r.Use(
 			api.oAuth2ProviderMiddleware,
+			httpmw.RejectMutationsInReadOnlyMode(api.ReadOnlyMode),
 			// Fetch the app as system because in the /tokens route there will be no
 			// authenticated user.
 			httpmw.AsAuthzSystem(httpmw.ExtractOAuth2ProviderApp(options.Database)),
@@ -954,6 +1002,7 @@ func New(options *Options) *API {
 			// limit must be configurable by the admin.
 			apiRateLimiter,
 			httpmw.ReportCLITelemetry(api.Logger, options.Telemetry),
+			httpmw.RejectMutationsInReadOnlyMode(api.ReadOnlyMode, readOnlyExemptPaths...),
 		)
 		r.Get("/", apiRoot)
 		// All CSP errors will be logged
@@ -973,7 +1022,16 @@ func New(options *Options) *API {
 			r.Use(apiKeyMiddleware)
 			r.Get("/config", api.deploymentValues)
 			r.Get("/stats", api.deploymentStats)
+			r.Get("/purge-stats", api.purgeStats)
 			r.Get("/ssh", api.sshConfig)
+			r.Get("/read-only", api.readOnlySettings)
+			r.Put("/read-only", api.putReadOnlySettings)
+			r.Get("/build-pause", api.deploymentBuildPause)
+			r.Put("/build-pause", api.putDeploymentBuildPause)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/capabilities", api.capabilities)
 		})
 		r.Route("/experiments", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -1081,16 +1139,54 @@ func New(options *Options) *API {
 							r.Delete("/", api.deleteOrganizationMember)
 							r.Put("/roles", api.putMemberRoles)
 							r.Post("/workspaces", api.postWorkspacesByOrganization)
+							r.Post("/workspaces/import", api.postImportWorkspace)
 						})
 					})
 				})
+				r.Route("/service-accounts", func(r chi.Router) {
+					r.Get("/", api.serviceAccounts)
+					r.Post("/", api.postServiceAccount)
+					r.Route("/{user}", func(r chi.Router) {
+						r.Use(
+							httpmw.ExtractServiceAccountParam(options.Database),
+						)
+						r.Delete("/", api.deleteServiceAccount)
+						r.Route("/tokens", func(r chi.Router) {
+							r.Get("/", api.serviceAccountTokens)
+							r.Post("/", api.postServiceAccountToken)
+							r.Delete("/{keyname}", api.deleteServiceAccountToken)
+							r.Post("/{keyname}/rotate", api.rotateServiceAccountToken)
+						})
+					})
+				})
+				r.Route("/buildalertrules", func(r chi.Router) {
+					r.Get("/", api.buildAlertRules)
+					r.Post("/", api.postBuildAlertRule)
+					r.Put("/{buildalertrule}", api.putBuildAlertRule)
+					r.Delete("/{buildalertrule}", api.deleteBuildAlertRule)
+				})
 				r.Route("/provisionerdaemons", func(r chi.Router) {
 					r.Get("/", api.provisionerDaemons)
 				})
 				r.Route("/provisionerjobs", func(r chi.Router) {
+					r.Get("/watch", api.watchProvisionerJobs)
 					r.Get("/{job}", api.provisionerJob)
+					r.Post("/{job}/reap", api.reapProvisionerJob)
 					r.Get("/", api.provisionerJobs)
 				})
+				r.Route("/provisionerreservations", func(r chi.Router) {
+					r.Get("/", api.provisionerReservations)
+					r.Post("/", api.postProvisionerReservation)
+					r.Delete("/{reservation}", api.deleteProvisionerReservation)
+				})
+				r.Route("/build-pause", func(r chi.Router) {
+					r.Get("/", api.organizationBuildPause)
+					r.Put("/", api.putOrganizationBuildPause)
+				})
+				r.Route("/workspace-naming-policy", func(r chi.Router) {
+					r.Get("/", api.organizationWorkspaceNamingPolicy)
+					r.Put("/", api.putOrganizationWorkspaceNamingPolicy)
+				})
 			})
 		})
 		r.Route("/templates", func(r chi.Router) {
@@ -1104,6 +1200,7 @@ func New(options *Options) *API {
 					httpmw.ExtractTemplateParam(options.Database),
 				)
 				r.Get("/daus", api.templateDAUs)
+				r.Get("/build-stats", api.templateBuildStats)
 				r.Get("/", api.template)
 				r.Delete("/", api.deleteTemplate)
 				r.Patch("/", api.patchTemplateMeta)
@@ -1113,6 +1210,27 @@ func New(options *Options) *API {
 					r.Patch("/", api.patchActiveTemplateVersion)
 					r.Get("/{templateversionname}", api.templateVersionByName)
 				})
+				r.Route("/presets", func(r chi.Router) {
+					r.Get("/", api.templatePresets)
+					r.Post("/", api.postTemplatePreset)
+					r.Put("/{preset}", api.putTemplatePreset)
+					r.Delete("/{preset}", api.deleteTemplatePreset)
+				})
+				r.Route("/channels", func(r chi.Router) {
+					r.Get("/", api.templateReleaseChannels)
+					r.Put("/{channel}", api.putTemplateReleaseChannel)
+					r.Delete("/{channel}", api.deleteTemplateReleaseChannel)
+				})
+				r.Route("/rollout", func(r chi.Router) {
+					r.Get("/", api.templateVersionRollout)
+					r.Put("/", api.putTemplateVersionRollout)
+					r.Delete("/", api.deleteTemplateVersionRollout)
+				})
+				r.Route("/workspace-naming-policy", func(r chi.Router) {
+					r.Get("/", api.templateWorkspaceNamingPolicy)
+					r.Put("/", api.putTemplateWorkspaceNamingPolicy)
+				})
+				r.Post("/lifecycle-simulation", api.postTemplateLifecycleSimulation)
 			})
 		})
 
@@ -1135,6 +1253,7 @@ func New(options *Options) *API {
 			r.Get("/variables", api.templateVersionVariables)
 			r.Get("/presets", api.templateVersionPresets)
 			r.Get("/resources", api.templateVersionResources)
+			r.Get("/diff", api.templateVersionDiff)
 			r.Get("/logs", api.templateVersionLogs)
 			r.Route("/dry-run", func(r chi.Router) {
 				r.Post("/", api.postTemplateVersionDryRun)
@@ -1144,6 +1263,7 @@ func New(options *Options) *API {
 				r.Get("/{jobID}/matched-provisioners", api.templateVersionDryRunMatchedProvisioners)
 				r.Patch("/{jobID}/cancel", api.patchTemplateVersionDryRunCancel)
 			})
+			r.Post("/test", api.postTemplateVersionTest)
 
 			r.Group(func(r chi.Router) {
 				r.Route("/dynamic-parameters", func(r chi.Router) {
@@ -1156,6 +1276,9 @@ func New(options *Options) *API {
 			r.Get("/first", api.firstUser)
 			r.Post("/first", api.postFirstUser)
 			r.Get("/authmethods", api.userAuthMethods)
+			// Devices poll for a token while the user approves the login, so
+			// this is outside the tight login rate limit.
+			r.Post("/login/device/token", api.postLoginDeviceToken)
 
 			r.Group(func(r chi.Router) {
 				// We use a tight limit for password login to protect against
@@ -1165,6 +1288,7 @@ func New(options *Options) *API {
 				// This value is intentionally increased during tests.
 				r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
 				r.Post("/login", api.postLogin)
+				r.Post("/login/device", api.postLoginDevice)
 				r.Post("/otp/request", api.postRequestOneTimePasscode)
 				r.Post("/validate-password", api.validateUserPassword)
 				r.Post("/otp/change-password", api.postChangePasswordWithOneTimePasscode)
@@ -1191,6 +1315,7 @@ func New(options *Options) *API {
 				r.Post("/", api.postUser)
 				r.Get("/", api.users)
 				r.Post("/logout", api.postLogout)
+				r.Post("/login/device/authorize", api.postLoginDeviceAuthorize)
 				// These routes query information about site wide roles.
 				r.Route("/roles", func(r chi.Router) {
 					r.Get("/", api.AssignableSiteRoles)
@@ -1258,6 +1383,10 @@ func New(options *Options) *API {
 							r.Route("/preferences", func(r chi.Router) {
 								r.Get("/", api.userNotificationPreferences)
 								r.Put("/", api.putUserNotificationPreferences)
+								r.Get("/digest", api.userNotificationDigestSettings)
+								r.Put("/digest", api.putUserNotificationDigestSettings)
+								r.Get("/build-duration", api.userNotificationBuildDurationSettings)
+								r.Put("/build-duration", api.putUserNotificationBuildDurationSettings)
 							})
 						})
 						r.Route("/webpush", func(r chi.Router) {
@@ -1313,8 +1442,10 @@ func New(options *Options) *API {
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
 				r.Get("/connection", api.workspaceAgentConnection)
+				r.Get("/connection-quality", api.workspaceAgentConnectionQuality)
 				r.Get("/containers", api.workspaceAgentListContainers)
 				r.Post("/containers/devcontainers/{devcontainer}/recreate", api.workspaceAgentRecreateDevcontainer)
+				r.Post("/exec", api.workspaceAgentExec)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
 				// PTY is part of workspaceAppServer.
@@ -1325,6 +1456,7 @@ func New(options *Options) *API {
 				apiKeyMiddleware,
 			)
 			r.Get("/", api.workspaces)
+			r.Get("/watch", api.watchWorkspaces)
 			r.Route("/{workspace}", func(r chi.Router) {
 				r.Use(
 					httpmw.ExtractWorkspaceParam(options.Database),
@@ -1334,6 +1466,11 @@ func New(options *Options) *API {
 				r.Route("/builds", func(r chi.Router) {
 					r.Get("/", api.workspaceBuilds)
 					r.Post("/", api.postWorkspaceBuilds)
+					r.Post("/rollback", api.postWorkspaceBuildRollback)
+					r.Route("/queue", func(r chi.Router) {
+						r.Get("/", api.workspaceBuildQueue)
+						r.Delete("/{queuedbuild}", api.deleteQueuedWorkspaceBuild)
+					})
 				})
 				r.Route("/autostart", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceAutostart)
@@ -1346,8 +1483,31 @@ func New(options *Options) *API {
 				r.Put("/extend", api.putExtendWorkspace)
 				r.Post("/usage", api.postWorkspaceUsage)
 				r.Put("/dormant", api.putWorkspaceDormant)
+				r.Post("/restore", api.postWorkspaceRestore)
+				r.Get("/export", api.workspaceExport)
 				r.Put("/favorite", api.putFavoriteWorkspace)
 				r.Delete("/favorite", api.deleteFavoriteWorkspace)
+				r.Route("/channel", func(r chi.Router) {
+					r.Get("/", api.workspaceReleaseChannel)
+					r.Put("/", api.putWorkspaceReleaseChannel)
+				})
+				r.Route("/acl", func(r chi.Router) {
+					r.Get("/", api.workspaceACL)
+					r.Patch("/", api.patchWorkspaceACL)
+				})
+				r.Route("/labels", func(r chi.Router) {
+					r.Get("/", api.workspaceLabels)
+					r.Put("/", api.putWorkspaceLabels)
+				})
+				r.Route("/locks", func(r chi.Router) {
+					r.Get("/", api.workspaceLocks)
+					r.Post("/", api.postWorkspaceLock)
+					r.Delete("/", api.deleteWorkspaceLock)
+				})
+				r.Route("/migrations", func(r chi.Router) {
+					r.Get("/", api.workspaceMigrations)
+					r.Post("/", api.postWorkspaceMigration)
+				})
 				r.Put("/autoupdates", api.putWorkspaceAutoupdates)
 				r.Get("/resolve-autostart", api.resolveAutostart)
 				r.Route("/port-share", func(r chi.Router) {
@@ -1356,6 +1516,12 @@ func New(options *Options) *API {
 					r.Delete("/", api.deleteWorkspaceAgentPortShare)
 				})
 				r.Get("/timings", api.workspaceTimings)
+				r.Get("/recommendations", api.workspaceRecommendations)
+				r.Get("/resource-usage", api.workspaceResourceUsage)
+				r.Route("/session-recordings", func(r chi.Router) {
+					r.Get("/", api.workspaceSessionRecordings)
+					r.Get("/{recording}/transcript", api.workspaceSessionRecordingTranscript)
+				})
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -1365,9 +1531,11 @@ func New(options *Options) *API {
 				httpmw.ExtractWorkspaceParam(options.Database),
 			)
 			r.Get("/", api.workspaceBuild)
+			r.Get("/archive", api.workspaceBuildArchive)
 			r.Patch("/cancel", api.patchCancelWorkspaceBuild)
 			r.Get("/logs", api.workspaceBuildLogs)
 			r.Get("/parameters", api.workspaceBuildParameters)
+			r.Post("/rehydrate", api.postWorkspaceBuildRehydrate)
 			r.Get("/resources", api.workspaceBuildResourcesDeprecated)
 			r.Get("/state", api.workspaceBuildState)
 			r.Get("/timings", api.workspaceBuildTimings)
@@ -1376,6 +1544,15 @@ func New(options *Options) *API {
 			r.Use(apiKeyMiddleware)
 			r.Post("/", api.checkAuthorization)
 		})
+		r.Route("/accessrequests", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.accessRequests)
+			r.Post("/", api.postAccessRequest)
+			r.Route("/{accessrequest}", func(r chi.Router) {
+				r.Get("/", api.accessRequest)
+				r.Post("/review", api.postAccessRequestReview)
+			})
+		})
 		r.Route("/applications", func(r chi.Router) {
 			r.Route("/host", func(r chi.Router) {
 				// Don't leak the hostname to unauthenticated users.
@@ -1398,6 +1575,12 @@ func New(options *Options) *API {
 			r.Get("/user-status-counts", api.insightsUserStatusCounts)
 			r.Get("/user-latency", api.insightsUserLatency)
 			r.Get("/templates", api.insightsTemplates)
+			r.Get("/costs", api.insightsCosts)
+			r.Get("/provisioners", api.insightsProvisioners)
+			r.Route("/user-activity/exports", func(r chi.Router) {
+				r.Post("/", api.postUserActivityExport)
+				r.Get("/{export}", api.userActivityExport)
+			})
 		})
 		r.Route("/debug", func(r chi.Router) {
 			r.Use(
@@ -1424,6 +1607,7 @@ func New(options *Options) *API {
 					r.Put("/", api.putDeploymentHealthSettings)
 				})
 			})
+			r.Get("/job-reaper", api.debugJobReaper)
 			r.Get("/ws", (&healthcheck.WebsocketEchoServer{}).ServeHTTP)
 			r.Route("/{user}", func(r chi.Router) {
 				r.Use(httpmw.ExtractUserParam(options.Database))
@@ -1628,6 +1812,11 @@ type API struct {
 	// This is used to gate features that are not yet ready for production.
 	Experiments codersdk.Experiments
 
+	// ReadOnlyMode tracks whether the deployment is in read-only mode for
+	// maintenance. Background jobs that mutate state should pause while it
+	// is enabled.
+	ReadOnlyMode *readonly.Mode
+
 	healthCheckGroup *singleflight.Group[string, *healthsdk.HealthcheckReport]
 	healthCheckCache atomic.Pointer[healthsdk.HealthcheckReport]
 
@@ -1637,6 +1826,18 @@ type API struct {
 	// dbRolluper rolls up template usage stats from raw agent and app
 	// stats. This is used to provide insights in the WebUI.
 	dbRolluper *dbrollup.Rolluper
+	// workspaceBuildQueueDone is closed once the workspace build queue stops
+	// processing after the API is closed.
+	workspaceBuildQueueDone chan struct{}
+	// workspaceMigrationsDone is closed once active workspace migrations stop
+	// being advanced after the API is closed.
+	workspaceMigrationsDone chan struct{}
+	// accessRequestExpiryDone is closed once expired access requests stop
+	// being revoked after the API is closed.
+	accessRequestExpiryDone chan struct{}
+	// userActivityExportsDone is closed once pending user activity exports
+	// stop being generated after the API is closed.
+	userActivityExportsDone chan struct{}
 }
 
 // Close waits for all WebSocket connections to drain before returning.
@@ -1667,7 +1868,12 @@ func (api *API) Close() error {
 	}
 
 	api.dbRolluper.Close()
+	<-api.workspaceBuildQueueDone
+	<-api.workspaceMigrationsDone
+	<-api.accessRequestExpiryDone
+	<-api.userActivityExportsDone
 	api.metricsCache.Close()
+	_ = api.ReadOnlyMode.Close()
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
@@ -1832,7 +2038,10 @@ func (api *API) CreateInMemoryTaggedProvisionerDaemon(dialCtx context.Context, n
 	if err != nil {
 		return nil, err
 	}
-	server := drpcserver.NewWithOptions(&tracing.DRPCHandler{Handler: mux},
+	// Every provisioner daemon RPC acquires or updates jobs, so none are
+	// served in read-only mode.
+	handler := &readonly.DRPCHandler{Handler: mux, Mode: api.ReadOnlyMode}
+	server := drpcserver.NewWithOptions(&tracing.DRPCHandler{Handler: handler},
 		drpcserver.Options{
 			Manager: drpcsdk.DefaultDRPCOptions(nil),
 			Log: func(err error) {
@@ -0,0 +1,47 @@
+package coderdtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+// AutobuildClock is a mock clock for the lifecycle executor of a test
+// deployment, passed as Options.AutobuildClock. The executor only runs when
+// the test calls Tick, and never runs if Options.AutobuildClock is not set.
+type AutobuildClock struct {
+	*quartz.Mock
+
+	t    testing.TB
+	trap *quartz.Trap
+}
+
+// NewAutobuildClock returns an AutobuildClock that starts at the current time
+// rounded to the precision of the database.
+func NewAutobuildClock(t testing.TB) *AutobuildClock {
+	t.Helper()
+
+	clock := dbtestutil.NewClock(t)
+	// The executor creates a timer for each run. Holding it back lets Tick
+	// move the clock anywhere, including into the past, before it is armed.
+	trap := clock.Trap().NewTimer("autobuild")
+	t.Cleanup(trap.Close)
+	return &AutobuildClock{Mock: clock, t: t, trap: trap}
+}
+
+// Tick moves the clock to at and has the executor run there. The executor
+// must be done with its previous run, i.e. its stats must have been read.
+func (c *AutobuildClock) Tick(at time.Time) {
+	c.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+	call := c.trap.MustWait(ctx)
+	c.Set(at.Add(-call.Duration)).MustWait(ctx)
+	call.MustRelease(ctx)
+	c.Advance(call.Duration).MustWait(ctx)
+}
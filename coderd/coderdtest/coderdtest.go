@@ -40,6 +40,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 	"google.golang.org/api/idtoken"
 	"google.golang.org/api/option"
@@ -83,6 +84,7 @@ import (
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 	"github.com/coder/coder/v2/coderd/workspacestats"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/codersdk/drpcsdk"
@@ -113,7 +115,7 @@ type Options struct {
 	OIDCConfig                     *coderd.OIDCConfig
 	GoogleTokenValidator           *idtoken.Validator
 	SSHKeygenAlgorithm             gitsshkey.Algorithm
-	AutobuildTicker                <-chan time.Time
+	AutobuildClock                 quartz.Clock
 	AutobuildStats                 chan<- autobuild.Stats
 	Auditor                        audit.Auditor
 	TLSCertificates                []tls.Certificate
@@ -178,6 +180,9 @@ type Options struct {
 	OIDCConvertKeyCache                cryptokeys.SigningKeycache
 	Clock                              quartz.Clock
 	TelemetryReporter                  telemetry.Reporter
+	WorkspaceBuildPreflightChecks      []wsbuilder.PreflightCheck
+	HTTPClient                         *http.Client
+	TracerProvider                     trace.TracerProvider
 }
 
 // New constructs a codersdk client connected to an in-memory API instance.
@@ -235,10 +240,8 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		options.GoogleTokenValidator, err = idtoken.NewValidator(ctx, option.WithoutAuthentication())
 		require.NoError(t, err)
 	}
-	if options.AutobuildTicker == nil {
-		ticker := make(chan time.Time)
-		options.AutobuildTicker = ticker
-		t.Cleanup(func() { close(ticker) })
+	if options.AutobuildClock == nil {
+		options.AutobuildClock = quartz.NewMock(t)
 	}
 	if options.AutobuildStats != nil {
 		t.Cleanup(func() {
@@ -366,15 +369,15 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		&auditor,
 		accessControlStore,
 		*options.Logger,
-		options.AutobuildTicker,
+		options.DeploymentValues.AutobuildPollInterval.Value(),
 		options.NotificationsEnqueuer,
 		experiments,
-	).WithStatsChannel(options.AutobuildStats)
+	).WithStatsChannel(options.AutobuildStats).
+		WithClock(options.AutobuildClock)
 	lifecycleExecutor.Run()
 
-	jobReaperTicker := time.NewTicker(options.DeploymentValues.JobReaperDetectorInterval.Value())
-	defer jobReaperTicker.Stop()
-	jobReaper := jobreaper.New(ctx, options.Database, options.Pubsub, options.Logger.Named("reaper.detector"), jobReaperTicker.C)
+	jobReaper := jobreaper.New(ctx, options.Database, options.Pubsub, options.Logger.Named("reaper.detector"), options.DeploymentValues.JobReaperDetectorInterval.Value()).
+		WithCancelDeadline(options.DeploymentValues.Provisioner.CancelDeadline.Value())
 	jobReaper.Start()
 	t.Cleanup(jobReaper.Close)
 
@@ -561,6 +564,9 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			RefreshEntitlements:                options.RefreshEntitlements,
 			TailnetCoordinator:                 options.Coordinator,
 			WebPushDispatcher:                  options.WebpushDispatcher,
+			WorkspaceBuildPreflightChecks:      options.WorkspaceBuildPreflightChecks,
+			HTTPClient:                         options.HTTPClient,
+			TracerProvider:                     options.TracerProvider,
 			BaseDERPMap:                        derpMap,
 			DERPMapUpdateFrequency:             150 * time.Millisecond,
 			CoordinatorResumeTokenProvider:     options.CoordinatorResumeTokenProvider,
@@ -692,6 +698,7 @@ func NewTaggedProvisionerDaemon(t testing.TB, coderAPI *coderd.API, name string,
 			string(database.ProvisionerTypeEcho): sdkproto.NewDRPCProvisionerClient(echoClient),
 		},
 		InitConnectionCh: connectedCh,
+		TracerProvider:   coderAPI.TracerProvider,
 	})
 	// Wait for the provisioner daemon to connect before continuing.
 	// Users of this function tend to assume that the provisioner is connected
@@ -309,6 +309,8 @@ func assertSecurityDefined(t *testing.T, comment SwaggerComment) {
 		comment.router == "/buildinfo" ||
 		comment.router == "/" ||
 		comment.router == "/users/login" ||
+		comment.router == "/users/login/device" ||
+		comment.router == "/users/login/device/token" ||
 		comment.router == "/users/otp/request" ||
 		comment.router == "/users/otp/change-password" {
 		return // endpoints do not require authorization
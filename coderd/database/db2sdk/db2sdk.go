@@ -258,6 +258,7 @@ func Group(row database.GetGroupsRow, members []database.GroupMember, totalMembe
 		Members:                 ReducedUsersFromGroupMembers(members),
 		TotalMemberCount:        totalMemberCount,
 		QuotaAllowance:          int(row.Group.QuotaAllowance),
+		MonthlyBudget:           int(row.Group.MonthlyBudget),
 		Source:                  codersdk.GroupSource(row.Group.Source),
 		OrganizationName:        row.OrganizationName,
 		OrganizationDisplayName: row.OrganizationDisplayName,
@@ -456,6 +457,8 @@ func WorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator,
 		LifecycleState:           codersdk.WorkspaceAgentLifecycle(dbAgent.LifecycleState),
 		Subsystems:               subsystems,
 		DisplayApps:              convertDisplayApps(dbAgent.DisplayApps),
+		DisplayGroup:             dbAgent.DisplayGroup,
+		Collapsed:                dbAgent.Collapsed,
 	}
 	node := coordinator.Node(dbAgent.ID)
 	if node != nil {
@@ -600,6 +603,74 @@ func WorkspaceAppStatus(status database.WorkspaceAppStatus) codersdk.WorkspaceAp
 	}
 }
 
+func WorkspaceDriftChecks(checks []database.GetWorkspaceDriftChecksByWorkspaceIDsRow) []codersdk.WorkspaceDriftCheck {
+	return List(checks, WorkspaceDriftCheck)
+}
+
+func WorkspaceDriftCheck(check database.GetWorkspaceDriftChecksByWorkspaceIDsRow) codersdk.WorkspaceDriftCheck {
+	result := codersdk.WorkspaceDriftCheck{
+		WorkspaceID:       check.WorkspaceID,
+		WorkspaceBuildID:  check.WorkspaceBuildID,
+		CreatedAt:         check.CreatedAt,
+		DriftedResources:  check.DriftedResources,
+		OrphanedResources: check.OrphanedResources,
+	}
+	switch {
+	case check.CompletedAt.Valid:
+		result.CompletedAt = &check.CompletedAt.Time
+		result.Status = codersdk.WorkspaceDriftCheckStatusClean
+		if len(check.DriftedResources) > 0 || len(check.OrphanedResources) > 0 {
+			result.Status = codersdk.WorkspaceDriftCheckStatusDrifted
+		}
+	case check.JobStatus == database.ProvisionerJobStatusFailed,
+		check.JobStatus == database.ProvisionerJobStatusCanceled:
+		result.Status = codersdk.WorkspaceDriftCheckStatusFailed
+	default:
+		result.Status = codersdk.WorkspaceDriftCheckStatusPending
+	}
+	if result.DriftedResources == nil {
+		result.DriftedResources = []string{}
+	}
+	if result.OrphanedResources == nil {
+		result.OrphanedResources = []string{}
+	}
+	return result
+}
+
+func WorkspaceHealthWarning(event database.WorkspaceAgentHealthEvent, agentName string) codersdk.WorkspaceHealthWarning {
+	return codersdk.WorkspaceHealthWarning{
+		AgentID:   event.AgentID,
+		AgentName: agentName,
+		Kind:      codersdk.WorkspaceHealthWarningKind(event.Kind),
+		Volume:    event.Volume,
+		Used:      event.Used,
+		Total:     event.Total,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+func WorkspaceAgentConnectionQuality(quality database.WorkspaceAgentConnectionQuality) codersdk.WorkspaceAgentConnectionQuality {
+	result := codersdk.WorkspaceAgentConnectionQuality{
+		IP:             quality.Ip,
+		P2P:            quality.P2P,
+		DERPRegionCode: quality.DerpRegionCode,
+		PingsSent:      quality.PingsSent,
+		PingsLost:      quality.PingsLost,
+		CreatedAt:      quality.CreatedAt,
+		UpdatedAt:      quality.UpdatedAt,
+	}
+	if quality.LatencyMS.Valid {
+		result.LatencyMS = &quality.LatencyMS.Float64
+	}
+	if quality.PingsSent > 0 {
+		result.PacketLoss = float64(quality.PingsLost) / float64(quality.PingsSent)
+	}
+	if quality.LastHandshakeAt.Valid {
+		result.LastHandshakeAt = &quality.LastHandshakeAt.Time
+	}
+	return result
+}
+
 func ProvisionerDaemon(dbDaemon database.ProvisionerDaemon) codersdk.ProvisionerDaemon {
 	result := codersdk.ProvisionerDaemon{
 		ID:             dbDaemon.ID,
@@ -731,6 +802,7 @@ func Organization(organization database.Organization) codersdk.Organization {
 			Icon:        organization.Icon,
 		},
 		Description: organization.Description,
+		CostCenter:  organization.CostCenter,
 		CreatedAt:   organization.CreatedAt,
 		UpdatedAt:   organization.UpdatedAt,
 		IsDefault:   organization.IsDefault,
@@ -781,6 +853,16 @@ func TemplateRoleActions(role codersdk.TemplateRole) []policy.Action {
 	return []policy.Action{}
 }
 
+func WorkspaceRoleActions(role codersdk.WorkspaceRole) []policy.Action {
+	switch role {
+	case codersdk.WorkspaceRoleView:
+		return []policy.Action{policy.ActionRead}
+	case codersdk.WorkspaceRoleConnect:
+		return []policy.Action{policy.ActionRead, policy.ActionSSH, policy.ActionApplicationConnect}
+	}
+	return []policy.Action{}
+}
+
 func AuditActionFromAgentProtoConnectionAction(action agentproto.Connection_Action) (database.AuditAction, error) {
 	switch action {
 	case agentproto.Connection_CONNECT:
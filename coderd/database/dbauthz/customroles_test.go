@@ -119,20 +119,6 @@ func TestInsertCustomRoles(t *testing.T) {
 			}),
 			errorContains: "invalid resource",
 		},
-		{
-			// Not allowing these at this time.
-			name:           "negative-permission",
-			organizationID: orgID,
-			subject:        merge(canCreateCustomRole, rbac.RoleOwner()),
-			org: []codersdk.Permission{
-				{
-					Negate:       true,
-					ResourceType: codersdk.ResourceWorkspace,
-					Action:       codersdk.ActionRead,
-				},
-			},
-			errorContains: "no negative permissions",
-		},
 		{
 			name:           "wildcard", // not allowed
 			organizationID: orgID,
@@ -197,6 +183,32 @@ func TestInsertCustomRoles(t *testing.T) {
 				codersdk.ResourceWorkspace: {codersdk.ActionRead},
 			}),
 		},
+		{
+			// Negative permissions only remove access, so the caller does
+			// not need to have the permission being denied.
+			name:           "negative-permission",
+			organizationID: orgID,
+			subject:        merge(canCreateCustomRole),
+			org: []codersdk.Permission{
+				{
+					Negate:       true,
+					ResourceType: codersdk.ResourceTemplate,
+					Action:       codersdk.ActionDelete,
+				},
+			},
+		},
+		{
+			name:           "negative-wildcard",
+			organizationID: orgID,
+			subject:        merge(canCreateCustomRole),
+			org: []codersdk.Permission{
+				{
+					Negate:       true,
+					ResourceType: codersdk.ResourceWorkspace,
+					Action:       "*",
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
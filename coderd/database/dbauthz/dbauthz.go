@@ -246,7 +246,9 @@ var (
 		Scope: rbac.ScopeAll,
 	}.WithCachedASTValue()
 
-	// See reaper package.
+	// See reaper package. The reaper only fails provisioner jobs and restores
+	// the provisioner state of their workspace builds, so it does not get the
+	// broad system permissions.
 	subjectJobReaper = rbac.Subject{
 		Type:         rbac.SubjectTypeJobReaper,
 		FriendlyName: "Job Reaper",
@@ -256,10 +258,10 @@ var (
 				Identifier:  rbac.RoleIdentifier{Name: "jobreaper"},
 				DisplayName: "Job Reaper Daemon",
 				Site: rbac.Permissions(map[string][]policy.Action{
-					rbac.ResourceSystem.Type:          {policy.WildcardSymbol},
-					rbac.ResourceTemplate.Type:        {policy.ActionRead},
-					rbac.ResourceWorkspace.Type:       {policy.ActionRead, policy.ActionUpdate},
-					rbac.ResourceProvisionerJobs.Type: {policy.ActionRead, policy.ActionUpdate},
+					rbac.ResourceTemplate.Type:         {policy.ActionRead},
+					rbac.ResourceWorkspace.Type:        {policy.ActionRead, policy.ActionUpdate},
+					rbac.ResourceWorkspaceDormant.Type: {policy.ActionRead, policy.ActionUpdate},
+					rbac.ResourceProvisionerJobs.Type:  {policy.ActionRead, policy.ActionUpdate},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -327,6 +329,27 @@ var (
 		Scope: rbac.ScopeAll,
 	}.WithCachedASTValue()
 
+	subjectAccessRequestGranter = rbac.Subject{
+		Type:         rbac.SubjectTypeAccessRequestGranter,
+		FriendlyName: "Access Request Granter",
+		ID:           uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Identifier:  rbac.RoleIdentifier{Name: "access-request-granter"},
+				DisplayName: "Access Request Granter",
+				Site: rbac.Permissions(map[string][]policy.Action{
+					// Approved access requests are granted and revoked through
+					// the ACL of the requested workspace or template.
+					rbac.ResourceWorkspace.Type: {policy.ActionRead, policy.ActionUpdate},
+					rbac.ResourceTemplate.Type:  {policy.ActionRead, policy.ActionCreate},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
 	subjectResourceMonitor = rbac.Subject{
 		Type:         rbac.SubjectTypeResourceMonitor,
 		FriendlyName: "Resource Monitor",
@@ -525,6 +548,12 @@ func AsNotifier(ctx context.Context) context.Context {
 	return As(ctx, subjectNotifier)
 }
 
+// AsAccessRequestGranter returns a context with an actor that has permissions
+// required for granting and revoking the roles of access requests.
+func AsAccessRequestGranter(ctx context.Context) context.Context {
+	return As(ctx, subjectAccessRequestGranter)
+}
+
 // AsResourceMonitor returns a context with an actor that has permissions required for
 // updating resource monitors.
 func AsResourceMonitor(ctx context.Context) context.Context {
@@ -867,6 +896,49 @@ func (q *querier) authorizeUpdateFileTemplate(ctx context.Context, file database
 	}
 }
 
+// authorizeWorkspaceTemplateUpdate checks that the actor may update the
+// template of the workspace. Administrative actions on a workspace, such as
+// locking it or reserving it as a prebuild, require update access to the
+// template rather than to the workspace, which would let owners undo them.
+func (q *querier) authorizeWorkspaceTemplateUpdate(ctx context.Context, workspaceID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+	template, err := q.db.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, policy.ActionUpdate, template)
+}
+
+// authorizeSessionRecordingRead checks that the actor may play back the
+// sessions recorded in the workspace. Recordings are kept for compliance, so
+// reading them requires access to the audit logs of the organization of the
+// workspace rather than to the workspace.
+func (q *querier) authorizeSessionRecordingRead(ctx context.Context, workspaceID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+	return q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog.InOrg(workspace.OrganizationID))
+}
+
+// authorizeSessionRecordingUpdate checks that the actor may append to a
+// recording, which requires update access to the workspace it was recorded
+// in, like creating the recording.
+func (q *querier) authorizeSessionRecordingUpdate(ctx context.Context, recordingID uuid.UUID) error {
+	recording, err := q.db.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if err != nil {
+		return xerrors.Errorf("get workspace session recording by id: %w", err)
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, recording.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+	return q.authorizeContext(ctx, policy.ActionUpdate, workspace)
+}
+
 // convertToOrganizationRoles converts a set of scoped role names to their unique
 // scoped names. The database stores roles as an array of strings, and needs to be
 // converted.
@@ -1052,6 +1124,16 @@ func authorizedTemplateVersionFromJob(ctx context.Context, q *querier, job datab
 	}
 }
 
+// provisionerBuildPauseObject returns the object that pausing builds is
+// authorized against. Builds can only be paused across the deployment by
+// those who can update the deployment config.
+func provisionerBuildPauseObject(organizationID uuid.NullUUID) rbac.Object {
+	if !organizationID.Valid {
+		return rbac.ResourceDeploymentConfig
+	}
+	return rbac.ResourceProvisionerDaemon.InOrg(organizationID.UUID)
+}
+
 func (q *querier) authorizeTemplateInsights(ctx context.Context, templateIDs []uuid.UUID) error {
 	// Abort early if can read all template insights, aka admins.
 	// TODO: If we know the org, that would allow org admins to abort early too.
@@ -1079,8 +1161,10 @@ func (q *querier) authorizeTemplateInsights(ctx context.Context, templateIDs []u
 // to a custom role. This prevents permission escalation.
 func (q *querier) customRoleEscalationCheck(ctx context.Context, actor rbac.Subject, perm rbac.Permission, object rbac.Object) error {
 	if perm.Negate {
-		// Users do not need negative permissions. We can include it later if required.
-		return xerrors.Errorf("invalid permission for action=%q type=%q, no negative permissions", perm.Action, perm.ResourceType)
+		// Negative permissions only ever take access away, so they cannot be
+		// used to escalate. Wildcards are allowed for the same reason, denying
+		// a resource type that is added later is the expected behavior.
+		return nil
 	}
 
 	if perm.Action == policy.WildcardSymbol || perm.ResourceType == policy.WildcardSymbol {
@@ -1105,7 +1189,6 @@ func (q *querier) customRoleEscalationCheck(ctx context.Context, actor rbac.Subj
 // - Check custom roles are valid for their resource types + actions
 // - Check the actor can create the custom role
 // - Check the custom role does not grant perms the actor does not have
-// - Prevent negative perms
 // - Prevent roles with site and org permissions.
 func (q *querier) customRoleCheck(ctx context.Context, role database.CustomRole) error {
 	act, ok := ActorFromContext(ctx)
@@ -1214,6 +1297,13 @@ func (q *querier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UU
 	return q.db.AllUserIDs(ctx, includeSystem)
 }
 
+func (q *querier) ApproveLoginDeviceCode(ctx context.Context, arg database.ApproveLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.LoginDeviceCode{}, err
+	}
+	return q.db.ApproveLoginDeviceCode(ctx, arg)
+}
+
 func (q *querier) ArchiveUnusedTemplateVersions(ctx context.Context, arg database.ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
 	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
 	if err != nil {
@@ -1301,6 +1391,20 @@ func (q *querier) CleanTailnetTunnels(ctx context.Context) error {
 	return q.db.CleanTailnetTunnels(ctx)
 }
 
+func (q *querier) CompleteWorkspaceMigration(ctx context.Context, arg database.CompleteWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	return q.db.CompleteWorkspaceMigration(ctx, arg)
+}
+
+func (q *querier) ConsumeApprovedLoginDeviceCode(ctx context.Context, arg database.ConsumeApprovedLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return database.LoginDeviceCode{}, err
+	}
+	return q.db.ConsumeApprovedLoginDeviceCode(ctx, arg)
+}
+
 func (q *querier) CountInProgressPrebuilds(ctx context.Context) ([]database.CountInProgressPrebuildsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWorkspace.All()); err != nil {
 		return nil, err
@@ -1308,6 +1412,13 @@ func (q *querier) CountInProgressPrebuilds(ctx context.Context) ([]database.Coun
 	return q.db.CountInProgressPrebuilds(ctx)
 }
 
+func (q *querier) CountInProgressProvisionerJobsByInitiator(ctx context.Context, arg database.CountInProgressProvisionerJobsByInitiatorParams) (database.CountInProgressProvisionerJobsByInitiatorRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return database.CountInProgressProvisionerJobsByInitiatorRow{}, err
+	}
+	return q.db.CountInProgressProvisionerJobsByInitiator(ctx, arg)
+}
+
 func (q *querier) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceInboxNotification.WithOwner(userID.String())); err != nil {
 		return 0, err
@@ -1315,6 +1426,13 @@ func (q *querier) CountUnreadInboxNotificationsByUserID(ctx context.Context, use
 	return q.db.CountUnreadInboxNotificationsByUserID(ctx, userID)
 }
 
+func (q *querier) CreateTimePartition(ctx context.Context, arg database.CreateTimePartitionParams) (bool, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return false, err
+	}
+	return q.db.CreateTimePartition(ctx, arg)
+}
+
 // TODO: Handle org scoped lookups
 func (q *querier) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	roleObject := rbac.ResourceAssignRole
@@ -1373,6 +1491,10 @@ func (q *querier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, u
 	return q.db.DeleteApplicationConnectAPIKeysByUserID(ctx, userID)
 }
 
+func (q *querier) DeleteBuildAlertRuleByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetBuildAlertRuleByID, q.db.DeleteBuildAlertRuleByID)(ctx, id)
+}
+
 func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -1398,6 +1520,13 @@ func (q *querier) DeleteCustomRole(ctx context.Context, arg database.DeleteCusto
 	return q.db.DeleteCustomRole(ctx, arg)
 }
 
+func (q *querier) DeleteExpiredLoginDeviceCodes(ctx context.Context, before time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteExpiredLoginDeviceCodes(ctx, before)
+}
+
 func (q *querier) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	return fetchAndExec(q.log, q.auth, policy.ActionUpdatePersonal, func(ctx context.Context, arg database.DeleteExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		//nolint:gosimple
@@ -1473,6 +1602,20 @@ func (q *querier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Contex
 	return q.db.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
 }
 
+func (q *querier) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldAuditLogs(ctx, arg)
+}
+
+func (q *querier) DeleteOldDeletedWorkspaces(ctx context.Context, before time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldDeletedWorkspaces(ctx, before)
+}
+
 func (q *querier) DeleteOldNotificationMessages(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceNotificationMessage); err != nil {
 		return err
@@ -1487,18 +1630,60 @@ func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	return q.db.DeleteOldProvisionerDaemons(ctx)
 }
 
-func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) error {
+func (q *querier) DeleteOldProvisionerJobLogs(ctx context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldProvisionerJobLogs(ctx, arg)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentConnectionQuality(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentConnectionQuality(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentHealthEvents(ctx context.Context, beforeTime time.Time) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
 	}
+	return q.db.DeleteOldWorkspaceAgentHealthEvents(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
 	return q.db.DeleteOldWorkspaceAgentLogs(ctx, threshold)
 }
 
-func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+func (q *querier) DeleteOldWorkspaceAgentResourceUsage(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentResourceUsage(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context, before time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldWorkspaceAgentStats(ctx, before)
+}
+
+func (q *querier) DeleteOldWorkspaceBuildIdempotencyKeys(ctx context.Context, before time.Time) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
 	}
-	return q.db.DeleteOldWorkspaceAgentStats(ctx)
+	return q.db.DeleteOldWorkspaceBuildIdempotencyKeys(ctx, before)
+}
+
+func (q *querier) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceSessionRecordings(ctx, beforeTime)
 }
 
 func (q *querier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
@@ -1515,10 +1700,28 @@ func (q *querier) DeleteOrganizationMember(ctx context.Context, arg database.Del
 	}, q.db.DeleteOrganizationMember)(ctx, arg)
 }
 
+func (q *querier) DeleteProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, provisionerBuildPauseObject(organizationID)); err != nil {
+		return err
+	}
+	return q.db.DeleteProvisionerBuildPause(ctx, organizationID)
+}
+
+func (q *querier) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteProvisionerJobLogsByJobID(ctx, jobID)
+}
+
 func (q *querier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
 	return deleteQ(q.log, q.auth, q.db.GetProvisionerKeyByID, q.db.DeleteProvisionerKey)(ctx, id)
 }
 
+func (q *querier) DeleteProvisionerReservation(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetProvisionerReservationByID, q.db.DeleteProvisionerReservation)(ctx, id)
+}
+
 func (q *querier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -1568,6 +1771,69 @@ func (q *querier) DeleteTailnetTunnel(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetTunnel(ctx, arg)
 }
 
+func (q *querier) DeleteTemplatePresetByID(ctx context.Context, id uuid.UUID) error {
+	preset, err := q.db.GetTemplatePresetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	tpl, err := q.db.GetTemplateByID(ctx, preset.TemplateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplatePresetByID(ctx, id)
+}
+
+func (q *querier) DeleteTemplatePresetGroupDefaultsByPresetID(ctx context.Context, templatePresetID uuid.UUID) error {
+	preset, err := q.db.GetTemplatePresetByID(ctx, templatePresetID)
+	if err != nil {
+		return err
+	}
+	tpl, err := q.db.GetTemplateByID(ctx, preset.TemplateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplatePresetGroupDefaultsByPresetID(ctx, templatePresetID)
+}
+
+func (q *querier) DeleteTemplateReleaseChannel(ctx context.Context, arg database.DeleteTemplateReleaseChannelParams) error {
+	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateReleaseChannel(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateShare(ctx context.Context, arg database.DeleteTemplateShareParams) error {
+	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateShare(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) error {
+	tpl, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateVersionRolloutByTemplateID(ctx, templateID)
+}
+
 func (q *querier) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceWebpushSubscription.WithOwner(arg.UserID.String())); err != nil {
 		return err
@@ -1609,6 +1875,63 @@ func (q *querier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context,
 	return q.db.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
 }
 
+func (q *querier) DeleteWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceBuildArchiveByBuildID(ctx, workspaceBuildID)
+}
+
+func (q *querier) DeleteWorkspaceBuildInterimState(ctx context.Context, workspaceBuildID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceBuildInterimState(ctx, workspaceBuildID)
+}
+
+func (q *querier) DeleteWorkspaceBuildQueueEntry(ctx context.Context, id uuid.UUID) error {
+	entry, err := q.db.GetWorkspaceBuildQueueEntryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	w, err := q.db.GetWorkspaceByID(ctx, entry.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceBuildQueueEntry(ctx, id)
+}
+
+func (q *querier) DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	fetch := func(ctx context.Context, workspaceID uuid.UUID) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, workspaceID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteWorkspaceLabelsByWorkspaceID)(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	if err := q.authorizeWorkspaceTemplateUpdate(ctx, workspaceID); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspacePrebuildReservationByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	fetch := func(ctx context.Context, workspaceID uuid.UUID) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, workspaceID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteWorkspaceReleaseChannelByWorkspaceID)(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceResourceMetadataByResourceIDs(ctx, ids)
+}
+
 func (q *querier) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, id)
 	if err != nil {
@@ -1629,6 +1952,20 @@ func (q *querier) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	return q.db.DisableForeignKeysAndTriggers(ctx)
 }
 
+func (q *querier) DropOldAuditLogsPartitions(ctx context.Context, before time.Time) (int32, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DropOldAuditLogsPartitions(ctx, before)
+}
+
+func (q *querier) DropOldWorkspaceAgentStatsPartitions(ctx context.Context, before time.Time) (int32, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DropOldWorkspaceAgentStatsPartitions(ctx, before)
+}
+
 func (q *querier) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceNotificationMessage); err != nil {
 		return err
@@ -1720,6 +2057,22 @@ func (q *querier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Tim
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetAPIKeysLastUsedAfter)(ctx, lastUsed)
 }
 
+func (q *querier) GetAccessRequestByID(ctx context.Context, id uuid.UUID) (database.AccessRequest, error) {
+	// Access requests are read by their requester and by those who can update
+	// the requested resource, which the API checks against the resource.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.AccessRequest{}, err
+	}
+	return q.db.GetAccessRequestByID(ctx, id)
+}
+
+func (q *querier) GetAccessRequests(ctx context.Context, arg database.GetAccessRequestsParams) ([]database.AccessRequest, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetAccessRequests(ctx, arg)
+}
+
 func (q *querier) GetActivePresetPrebuildSchedules(ctx context.Context) ([]database.TemplateVersionPresetPrebuildSchedule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTemplate.All()); err != nil {
 		return nil, err
@@ -1742,6 +2095,21 @@ func (q *querier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, temp
 	return q.db.GetActiveWorkspaceBuildsByTemplateID(ctx, templateID)
 }
 
+func (q *querier) GetActiveWorkspaceLockByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceLock, error) {
+	// If we can read the workspace, we can read its locks.
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return database.WorkspaceLock{}, err
+	}
+	return q.db.GetActiveWorkspaceLockByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetActiveWorkspaceMigrations(ctx context.Context) ([]database.WorkspaceMigration, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetActiveWorkspaceMigrations(ctx)
+}
+
 func (q *querier) GetAllTailnetAgents(ctx context.Context) ([]database.TailnetAgent, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTailnetCoordinator); err != nil {
 		return []database.TailnetAgent{}, err
@@ -1787,6 +2155,15 @@ func (q *querier) GetApplicationName(ctx context.Context) (string, error) {
 	return q.db.GetApplicationName(ctx)
 }
 
+func (q *querier) GetAuditLogsForExport(ctx context.Context, arg database.GetAuditLogsForExportParams) ([]database.GetAuditLogsForExportRow, error) {
+	// Audit logs are exported deployment-wide, so all of them must be
+	// readable.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogsForExport(ctx, arg)
+}
+
 func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	// Shortcut if the user is an owner. The SQL filter is noticeable,
 	// and this is an easy win for owners. Which is the common case.
@@ -1810,6 +2187,35 @@ func (q *querier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUI
 	return q.db.GetAuthorizationUserRoles(ctx, userID)
 }
 
+func (q *querier) GetBatchedNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]database.GetBatchedNotificationMessagesByUserIDRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
+		return nil, err
+	}
+	return q.db.GetBatchedNotificationMessagesByUserID(ctx, userID)
+}
+
+func (q *querier) GetBuildAlertRuleByID(ctx context.Context, id uuid.UUID) (database.BuildAlertRule, error) {
+	return fetch(q.log, q.auth, q.db.GetBuildAlertRuleByID)(ctx, id)
+}
+
+func (q *querier) GetBuildAlertRuleMetrics(ctx context.Context, arg database.GetBuildAlertRuleMetricsParams) (database.GetBuildAlertRuleMetricsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.GetBuildAlertRuleMetricsRow{}, err
+	}
+	return q.db.GetBuildAlertRuleMetrics(ctx, arg)
+}
+
+func (q *querier) GetBuildAlertRules(ctx context.Context) ([]database.BuildAlertRule, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetBuildAlertRules(ctx)
+}
+
+func (q *querier) GetBuildAlertRulesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.BuildAlertRule, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetBuildAlertRulesByOrganizationID)(ctx, organizationID)
+}
+
 func (q *querier) GetCoordinatorResumeTokenSigningKey(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -1845,14 +2251,21 @@ func (q *querier) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, er
 	return q.db.GetDBCryptKeys(ctx)
 }
 
-func (q *querier) GetDERPMeshKey(ctx context.Context) (string, error) {
+func (q *querier) GetDBPurgeStats(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return "", err
 	}
-	return q.db.GetDERPMeshKey(ctx)
+	return q.db.GetDBPurgeStats(ctx)
 }
 
-func (q *querier) GetDefaultOrganization(ctx context.Context) (database.Organization, error) {
+func (q *querier) GetDERPMeshKey(ctx context.Context) (string, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return "", err
+	}
+	return q.db.GetDERPMeshKey(ctx)
+}
+
+func (q *querier) GetDefaultOrganization(ctx context.Context) (database.Organization, error) {
 	return fetch(q.log, q.auth, func(ctx context.Context, _ any) (database.Organization, error) {
 		return q.db.GetDefaultOrganization(ctx)
 	})(ctx, nil)
@@ -1863,6 +2276,15 @@ func (q *querier) GetDefaultProxyConfig(ctx context.Context) (database.GetDefaul
 	return q.db.GetDefaultProxyConfig(ctx)
 }
 
+func (q *querier) GetDefaultTemplatePresetForUser(ctx context.Context, arg database.GetDefaultTemplatePresetForUserParams) (database.TemplatePreset, error) {
+	// Builds of the template resolve its presets, so reading the template is
+	// sufficient.
+	if _, err := q.GetTemplateByID(ctx, arg.TemplateID); err != nil {
+		return database.TemplatePreset{}, err
+	}
+	return q.db.GetDefaultTemplatePresetForUser(ctx, arg)
+}
+
 // Only used by metrics cache.
 func (q *querier) GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]database.GetDeploymentDAUsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
@@ -1892,6 +2314,20 @@ func (q *querier) GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx context.C
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetEligibleProvisionerDaemonsByProvisionerJobIDs)(ctx, provisionerJobIDs)
 }
 
+func (q *querier) GetEncryptedWorkspaceSessionRecordingChunks(ctx context.Context) ([]database.WorkspaceSessionRecordingChunk, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetEncryptedWorkspaceSessionRecordingChunks(ctx)
+}
+
+func (q *querier) GetExpiredAccessRequests(ctx context.Context, now time.Time) ([]database.AccessRequest, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetExpiredAccessRequests(ctx, now)
+}
+
 func (q *querier) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	return fetchWithAction(q.log, q.auth, policy.ActionReadPersonal, q.db.GetExternalAuthLink)(ctx, arg)
 }
@@ -2025,6 +2461,13 @@ func (q *querier) GetInboxNotificationsByUserID(ctx context.Context, userID data
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetInboxNotificationsByUserID)(ctx, userID)
 }
 
+func (q *querier) GetJobReaperLastRunAt(ctx context.Context) (string, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return "", err
+	}
+	return q.db.GetJobReaperLastRunAt(ctx)
+}
+
 func (q *querier) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -2039,6 +2482,13 @@ func (q *querier) GetLatestCryptoKeyByFeature(ctx context.Context, feature datab
 	return q.db.GetLatestCryptoKeyByFeature(ctx, feature)
 }
 
+func (q *querier) GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	return q.db.GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAppStatus, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -2084,6 +2534,20 @@ func (q *querier) GetLicenses(ctx context.Context) ([]database.License, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, fetch)(ctx, nil)
 }
 
+func (q *querier) GetLoginDeviceCodeByDeviceCodeHash(ctx context.Context, deviceCodeHash []byte) (database.LoginDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.LoginDeviceCode{}, err
+	}
+	return q.db.GetLoginDeviceCodeByDeviceCodeHash(ctx, deviceCodeHash)
+}
+
+func (q *querier) GetLoginDeviceCodeByUserCode(ctx context.Context, userCode string) (database.LoginDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.LoginDeviceCode{}, err
+	}
+	return q.db.GetLoginDeviceCodeByUserCode(ctx, userCode)
+}
+
 func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetLogoURL(ctx)
@@ -2204,6 +2668,17 @@ func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	return q.db.GetOAuthSigningKey(ctx)
 }
 
+func (q *querier) GetOIDCUserClaims(ctx context.Context, organizationID uuid.UUID) ([]database.GetOIDCUserClaimsRow, error) {
+	resource := rbac.ResourceIdpsyncSettings
+	if organizationID != uuid.Nil {
+		resource = resource.InOrg(organizationID)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, resource); err != nil {
+		return nil, err
+	}
+	return q.db.GetOIDCUserClaims(ctx, organizationID)
+}
+
 func (q *querier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	return fetch(q.log, q.auth, q.db.GetOrganizationByID)(ctx, id)
 }
@@ -2247,6 +2722,20 @@ func (q *querier) GetOrganizationResourceCountByID(ctx context.Context, organiza
 	return q.db.GetOrganizationResourceCountByID(ctx, organizationID)
 }
 
+func (q *querier) GetOrganizationWorkspaceNamingPolicy(ctx context.Context, organizationID uuid.UUID) (database.WorkspaceNamingPolicy, error) {
+	org, err := q.db.GetOrganizationByID(ctx, organizationID)
+	if err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+
+	// Naming policies are enforced for every member creating a workspace, so
+	// reading one is akin to reading the organization.
+	if err := q.authorizeContext(ctx, policy.ActionRead, org); err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+	return q.db.GetOrganizationWorkspaceNamingPolicy(ctx, organizationID)
+}
+
 func (q *querier) GetOrganizations(ctx context.Context, args database.GetOrganizationsParams) ([]database.Organization, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.Organization, error) {
 		return q.db.GetOrganizations(ctx, args)
@@ -2279,6 +2768,27 @@ func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUI
 	return q.db.GetParameterSchemasByJobID(ctx, jobID)
 }
 
+func (q *querier) GetPendingProvisionerJobsQueueDepthByTags(ctx context.Context) ([]database.GetPendingProvisionerJobsQueueDepthByTagsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return nil, err
+	}
+	return q.db.GetPendingProvisionerJobsQueueDepthByTags(ctx)
+}
+
+func (q *querier) GetPendingUserActivityExports(ctx context.Context) ([]database.UserActivityExport, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetPendingUserActivityExports(ctx)
+}
+
+func (q *querier) GetPrebuildClaimDemand(ctx context.Context, since time.Time) ([]database.GetPrebuildClaimDemandRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWorkspace.All()); err != nil {
+		return nil, err
+	}
+	return q.db.GetPrebuildClaimDemand(ctx, since)
+}
+
 func (q *querier) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	// GetPrebuildMetrics returns metrics related to prebuilt workspaces,
 	// such as the number of created and failed prebuilt workspaces.
@@ -2371,6 +2881,17 @@ func (q *querier) GetPreviousTemplateVersion(ctx context.Context, arg database.G
 	return q.db.GetPreviousTemplateVersion(ctx, arg)
 }
 
+func (q *querier) GetProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) (database.ProvisionerBuildPause, error) {
+	// Whether builds are paused across the deployment is visible to all
+	// users, so they can tell why their builds are pending.
+	if organizationID.Valid {
+		if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganization.WithID(organizationID.UUID).InOrg(organizationID.UUID)); err != nil {
+			return database.ProvisionerBuildPause{}, err
+		}
+	}
+	return q.db.GetProvisionerBuildPause(ctx, organizationID)
+}
+
 func (q *querier) GetProvisionerDaemons(ctx context.Context) ([]database.ProvisionerDaemon, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.ProvisionerDaemon, error) {
 		return q.db.GetProvisionerDaemons(ctx)
@@ -2420,6 +2941,22 @@ func (q *querier) GetProvisionerJobByIDForUpdate(ctx context.Context, id uuid.UU
 	return q.db.GetProvisionerJobByIDForUpdate(ctx, id)
 }
 
+func (q *querier) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	// Authorized read on job lets the actor also read the archived logs.
+	_, err := q.GetProvisionerJobByID(ctx, jobID)
+	if err != nil {
+		return database.ProvisionerJobLogArchive{}, err
+	}
+	return q.db.GetProvisionerJobLogArchiveByJobID(ctx, jobID)
+}
+
+func (q *querier) GetProvisionerJobThroughputByTags(ctx context.Context, arg database.GetProvisionerJobThroughputByTagsParams) ([]database.GetProvisionerJobThroughputByTagsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobThroughputByTags(ctx, arg)
+}
+
 func (q *querier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	_, err := q.GetProvisionerJobByID(ctx, jobID)
 	if err != nil {
@@ -2464,6 +3001,20 @@ func (q *querier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetProvisionerJobsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetProvisionerJobsRunningBetween(ctx context.Context, arg database.GetProvisionerJobsRunningBetweenParams) ([]database.GetProvisionerJobsRunningBetweenRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobsRunningBetween(ctx, arg)
+}
+
+func (q *querier) GetProvisionerJobsToArchiveLogs(ctx context.Context, arg database.GetProvisionerJobsToArchiveLogsParams) ([]database.GetProvisionerJobsToArchiveLogsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobsToArchiveLogs(ctx, arg)
+}
+
 func (q *querier) GetProvisionerJobsToBeReaped(ctx context.Context, arg database.GetProvisionerJobsToBeReapedParams) ([]database.ProvisionerJob, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
 		return nil, err
@@ -2492,6 +3043,14 @@ func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.Ge
 	return q.db.GetProvisionerLogsAfterID(ctx, arg)
 }
 
+func (q *querier) GetProvisionerReservationByID(ctx context.Context, id uuid.UUID) (database.ProvisionerReservation, error) {
+	return fetch(q.log, q.auth, q.db.GetProvisionerReservationByID)(ctx, id)
+}
+
+func (q *querier) GetProvisionerReservationsByOrganization(ctx context.Context, arg database.GetProvisionerReservationsByOrganizationParams) ([]database.ProvisionerReservation, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetProvisionerReservationsByOrganization)(ctx, arg)
+}
+
 func (q *querier) GetQuotaAllowanceForUser(ctx context.Context, params database.GetQuotaAllowanceForUserParams) (int64, error) {
 	err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(params.UserID))
 	if err != nil {
@@ -2508,6 +3067,27 @@ func (q *querier) GetQuotaConsumedForUser(ctx context.Context, params database.G
 	return q.db.GetQuotaConsumedForUser(ctx, params)
 }
 
+func (q *querier) GetQuotaMonthlyBudgetForUser(ctx context.Context, arg database.GetQuotaMonthlyBudgetForUserParams) (int64, error) {
+	err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(arg.UserID))
+	if err != nil {
+		return -1, err
+	}
+	return q.db.GetQuotaMonthlyBudgetForUser(ctx, arg)
+}
+
+func (q *querier) GetQuotaMonthlySpendForUser(ctx context.Context, arg database.GetQuotaMonthlySpendForUserParams) (float64, error) {
+	err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(arg.OwnerID))
+	if err != nil {
+		return -1, err
+	}
+	return q.db.GetQuotaMonthlySpendForUser(ctx, arg)
+}
+
+func (q *querier) GetReadOnlySettings(ctx context.Context) (string, error) {
+	// No authz checks
+	return q.db.GetReadOnlySettings(ctx)
+}
+
 func (q *querier) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -2530,6 +3110,13 @@ func (q *querier) GetRunningPrebuiltWorkspaces(ctx context.Context) ([]database.
 	return q.db.GetRunningPrebuiltWorkspaces(ctx)
 }
 
+func (q *querier) GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx context.Context, now time.Time) ([]database.GetRunningWorkspaceBuildsExceedingDurationThresholdRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx, now)
+}
+
 func (q *querier) GetRuntimeConfig(ctx context.Context, key string) (string, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -2537,6 +3124,28 @@ func (q *querier) GetRuntimeConfig(ctx context.Context, key string) (string, err
 	return q.db.GetRuntimeConfig(ctx, key)
 }
 
+func (q *querier) GetSensitiveTemplateVersionVariables(ctx context.Context) ([]database.TemplateVersionVariable, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetSensitiveTemplateVersionVariables(ctx)
+}
+
+func (q *querier) GetServiceAccountByUserID(ctx context.Context, userID uuid.UUID) (database.ServiceAccount, error) {
+	return fetch(q.log, q.auth, q.db.GetServiceAccountByUserID)(ctx, userID)
+}
+
+func (q *querier) GetServiceAccountsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.ServiceAccount, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetServiceAccountsByOrganizationID)(ctx, organizationID)
+}
+
+func (q *querier) GetStartableWorkspaceBuildQueueEntries(ctx context.Context) ([]database.WorkspaceBuildQueue, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetStartableWorkspaceBuildQueueEntries(ctx)
+}
+
 func (q *querier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTailnetCoordinator); err != nil {
 		return nil, err
@@ -2609,10 +3218,32 @@ func (q *querier) GetTemplateAverageBuildTime(ctx context.Context, arg database.
 	return q.db.GetTemplateAverageBuildTime(ctx, arg)
 }
 
+func (q *querier) GetTemplateBuildDurationStats(ctx context.Context, arg database.GetTemplateBuildDurationStatsParams) ([]database.TemplateBuildDurationStat, error) {
+	// Build durations are shown alongside the template, so reading the
+	// template is sufficient.
+	if _, err := q.GetTemplateByID(ctx, arg.TemplateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateBuildDurationStats(ctx, arg)
+}
+
 func (q *querier) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	return fetch(q.log, q.auth, q.db.GetTemplateByID)(ctx, id)
 }
 
+func (q *querier) GetTemplateByIDForUpdate(ctx context.Context, id uuid.UUID) (database.TemplateTable, error) {
+	template, err := q.db.GetTemplateByID(ctx, id)
+	if err != nil {
+		return database.TemplateTable{}, err
+	}
+	// The template is locked to update its ACL, which uses the ActionCreate
+	// action like UpdateTemplateACLByID.
+	if err := q.authorizeContext(ctx, policy.ActionCreate, template); err != nil {
+		return database.TemplateTable{}, err
+	}
+	return q.db.GetTemplateByIDForUpdate(ctx, id)
+}
+
 func (q *querier) GetTemplateByOrganizationAndName(ctx context.Context, arg database.GetTemplateByOrganizationAndNameParams) (database.Template, error) {
 	return fetch(q.log, q.auth, q.db.GetTemplateByOrganizationAndName)(ctx, arg)
 }
@@ -2654,6 +3285,31 @@ func (q *querier) GetTemplateParameterInsights(ctx context.Context, arg database
 	return q.db.GetTemplateParameterInsights(ctx, arg)
 }
 
+func (q *querier) GetTemplatePresetByID(ctx context.Context, id uuid.UUID) (database.TemplatePreset, error) {
+	preset, err := q.db.GetTemplatePresetByID(ctx, id)
+	if err != nil {
+		return database.TemplatePreset{}, err
+	}
+	if _, err := q.GetTemplateByID(ctx, preset.TemplateID); err != nil {
+		return database.TemplatePreset{}, err
+	}
+	return preset, nil
+}
+
+func (q *querier) GetTemplatePresetGroupDefaultsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePresetGroupDefault, error) {
+	if _, err := q.GetTemplateByID(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplatePresetGroupDefaultsByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetTemplatePresetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePreset, error) {
+	if _, err := q.GetTemplateByID(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplatePresetsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	// GetTemplatePresetsWithPrebuilds retrieves template versions with configured presets and prebuilds.
 	// Presets and prebuilds are part of the template, so if you can access templates - you can access them as well.
@@ -2663,6 +3319,35 @@ func (q *querier) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateI
 	return q.db.GetTemplatePresetsWithPrebuilds(ctx, templateID)
 }
 
+func (q *querier) GetTemplateReleaseChannel(ctx context.Context, arg database.GetTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	// Builds of the template resolve its release channels, so reading the
+	// template is sufficient.
+	if _, err := q.GetTemplateByID(ctx, arg.TemplateID); err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	return q.db.GetTemplateReleaseChannel(ctx, arg)
+}
+
+func (q *querier) GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateReleaseChannel, error) {
+	if _, err := q.GetTemplateByID(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateReleaseChannelsByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetTemplateSharesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateShare, error) {
+	// Only actors that can manage the template can see which organizations it
+	// is shared into.
+	tpl, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateSharesByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -2745,6 +3430,22 @@ func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVers
 	return q.db.GetTemplateVersionParameters(ctx, templateVersionID)
 }
 
+func (q *querier) GetTemplateVersionRolloutBuildCounts(ctx context.Context, arg database.GetTemplateVersionRolloutBuildCountsParams) ([]database.GetTemplateVersionRolloutBuildCountsRow, error) {
+	if _, err := q.GetTemplateByID(ctx, arg.TemplateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionRolloutBuildCounts(ctx, arg)
+}
+
+func (q *querier) GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	// Builds of the template resolve its rollout, so reading the template is
+	// sufficient.
+	if _, err := q.GetTemplateByID(ctx, templateID); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.GetTemplateVersionRolloutByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	// The template_version_terraform_values table should follow the same access
 	// control as the template_version table. Rather than reimplement the checks,
@@ -2834,6 +3535,18 @@ func (q *querier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetTemplateVersionsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetTemplateWorkspaceNamingPolicy(ctx context.Context, templateID uuid.UUID) (database.WorkspaceNamingPolicy, error) {
+	tpl, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionRead, tpl); err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+	return q.db.GetTemplateWorkspaceNamingPolicy(ctx, templateID)
+}
+
 func (q *querier) GetTemplates(ctx context.Context) ([]database.Template, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -2841,6 +3554,10 @@ func (q *querier) GetTemplates(ctx context.Context) ([]database.Template, error)
 	return q.db.GetTemplates(ctx)
 }
 
+func (q *querier) GetTemplatesSharedWithOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.Template, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetTemplatesSharedWithOrganization)(ctx, organizationID)
+}
+
 func (q *querier) GetTemplatesWithFilter(ctx context.Context, arg database.GetTemplatesWithFilterParams) ([]database.Template, error) {
 	prep, err := prepareSQLFilter(ctx, q.auth, policy.ActionRead, rbac.ResourceTemplate.Type)
 	if err != nil {
@@ -2856,6 +3573,20 @@ func (q *querier) GetUnexpiredLicenses(ctx context.Context) ([]database.License,
 	return q.db.GetUnexpiredLicenses(ctx)
 }
 
+func (q *querier) GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentHealthEvent, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx, ids)
+}
+
+func (q *querier) GetUserActivityExportByID(ctx context.Context, id uuid.UUID) (database.UserActivityExport, error) {
+	if err := q.authorizeTemplateInsights(ctx, nil); err != nil {
+		return database.UserActivityExport{}, err
+	}
+	return q.db.GetUserActivityExportByID(ctx, id)
+}
+
 func (q *querier) GetUserActivityInsights(ctx context.Context, arg database.GetUserActivityInsightsParams) ([]database.GetUserActivityInsightsRow, error) {
 	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
 	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
@@ -2893,6 +3624,13 @@ func (q *querier) GetUserCount(ctx context.Context, includeSystem bool) (int64,
 	return q.db.GetUserCount(ctx, includeSystem)
 }
 
+func (q *querier) GetUserDailyActivity(ctx context.Context, arg database.GetUserDailyActivityParams) ([]database.GetUserDailyActivityRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, nil); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserDailyActivity(ctx, arg)
+}
+
 func (q *querier) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
 	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
@@ -2936,6 +3674,20 @@ func (q *querier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([
 	return q.db.GetUserLinksByUserID(ctx, userID)
 }
 
+func (q *querier) GetUserNotificationBuildDurationThreshold(ctx context.Context, userID uuid.UUID) (string, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationPreference.WithOwner(userID.String())); err != nil {
+		return "", err
+	}
+	return q.db.GetUserNotificationBuildDurationThreshold(ctx, userID)
+}
+
+func (q *querier) GetUserNotificationDigestFrequency(ctx context.Context, userID uuid.UUID) (string, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationPreference.WithOwner(userID.String())); err != nil {
+		return "", err
+	}
+	return q.db.GetUserNotificationDigestFrequency(ctx, userID)
+}
+
 func (q *querier) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationPreference.WithOwner(userID.String())); err != nil {
 		return nil, err
@@ -3008,6 +3760,13 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetUsersWithDueNotificationDigests(ctx context.Context, now time.Time) ([]database.GetUsersWithDueNotificationDigestsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
+		return nil, err
+	}
+	return q.db.GetUsersWithDueNotificationDigests(ctx, now)
+}
+
 func (q *querier) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWebpushSubscription.WithOwner(userID.String())); err != nil {
 		return nil, err
@@ -3053,6 +3812,22 @@ func (q *querier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanc
 	return agent, nil
 }
 
+func (q *querier) GetWorkspaceAgentConnectionQualityByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentConnectionQuality, error) {
+	_, err := q.GetWorkspaceAgentByID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentConnectionQualityByAgentID(ctx, agentID)
+}
+
+func (q *querier) GetWorkspaceAgentConnectionTypeCounts(ctx context.Context, updatedAfter time.Time) ([]database.GetWorkspaceAgentConnectionTypeCountsRow, error) {
+	// The counts are aggregated over all agents, for telemetry.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentConnectionTypeCounts(ctx, updatedAfter)
+}
+
 func (q *querier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, workspaceAgentID)
 	if err != nil {
@@ -3112,6 +3887,13 @@ func (q *querier) GetWorkspaceAgentPortShare(ctx context.Context, arg database.G
 	return q.db.GetWorkspaceAgentPortShare(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentResourceUsageByWorkspaceID(ctx context.Context, arg database.GetWorkspaceAgentResourceUsageByWorkspaceIDParams) ([]database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow, error) {
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentResourceUsageByWorkspaceID(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3228,6 +4010,15 @@ func (q *querier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt ti
 	return q.db.GetWorkspaceAppsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildArchive, error) {
+	// Authorized read on build lets the actor also read its archive.
+	_, err := q.GetWorkspaceBuildByID(ctx, workspaceBuildID)
+	if err != nil {
+		return database.WorkspaceBuildArchive{}, err
+	}
+	return q.db.GetWorkspaceBuildArchiveByBuildID(ctx, workspaceBuildID)
+}
+
 func (q *querier) GetWorkspaceBuildByID(ctx context.Context, buildID uuid.UUID) (database.WorkspaceBuild, error) {
 	build, err := q.db.GetWorkspaceBuildByID(ctx, buildID)
 	if err != nil {
@@ -3259,6 +4050,49 @@ func (q *querier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Conte
 	return q.db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceBuildIdempotencyKey(ctx context.Context, arg database.GetWorkspaceBuildIdempotencyKeyParams) (database.WorkspaceBuildIdempotencyKey, error) {
+	key, err := q.db.GetWorkspaceBuildIdempotencyKey(ctx, arg)
+	if err != nil {
+		return database.WorkspaceBuildIdempotencyKey{}, err
+	}
+
+	if !key.WorkspaceBuildID.Valid {
+		// The key is reserved by a request that is in progress.
+		err = q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUser.WithID(key.UserID).WithOwner(key.UserID.String()))
+		if err != nil {
+			return database.WorkspaceBuildIdempotencyKey{}, err
+		}
+		return key, nil
+	}
+
+	// Authorized call to get the workspace build. If we can read the build,
+	// we can read its idempotency key.
+	_, err = q.GetWorkspaceBuildByID(ctx, key.WorkspaceBuildID.UUID)
+	if err != nil {
+		return database.WorkspaceBuildIdempotencyKey{}, err
+	}
+
+	return key, nil
+}
+
+func (q *querier) GetWorkspaceBuildInterimStateByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildInterimState, error) {
+	// Authorized call to get the workspace build. If we can read the build,
+	// we can read its state.
+	_, err := q.GetWorkspaceBuildByID(ctx, workspaceBuildID)
+	if err != nil {
+		return database.WorkspaceBuildInterimState{}, err
+	}
+
+	return q.db.GetWorkspaceBuildInterimStateByBuildID(ctx, workspaceBuildID)
+}
+
+func (q *querier) GetWorkspaceBuildOutcomesCompletedBetween(ctx context.Context, arg database.GetWorkspaceBuildOutcomesCompletedBetweenParams) ([]database.GetWorkspaceBuildOutcomesCompletedBetweenRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildOutcomesCompletedBetween(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	// Authorized call to get the workspace build. If we can read the build,
 	// we can read the params.
@@ -3279,7 +4113,27 @@ func (q *querier) GetWorkspaceBuildParametersByBuildIDs(ctx context.Context, wor
 	return q.db.GetAuthorizedWorkspaceBuildParametersByBuildIDs(ctx, workspaceBuildIDs, prep)
 }
 
-func (q *querier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]database.GetWorkspaceBuildStatsByTemplatesRow, error) {
+func (q *querier) GetWorkspaceBuildQueueByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceBuildQueue, error) {
+	// If we can read the workspace, we can read its queued builds.
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildQueueByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuildQueue, error) {
+	entry, err := q.db.GetWorkspaceBuildQueueEntryByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceBuildQueue{}, err
+	}
+	// If we can read the workspace, we can read its queued builds.
+	if _, err := q.GetWorkspaceByID(ctx, entry.WorkspaceID); err != nil {
+		return database.WorkspaceBuildQueue{}, err
+	}
+	return entry, nil
+}
+
+func (q *querier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]database.GetWorkspaceBuildStatsByTemplatesRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
 	}
@@ -3303,6 +4157,13 @@ func (q *querier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetWorkspaceBuildsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceBuildsToArchive(ctx context.Context, arg database.GetWorkspaceBuildsToArchiveParams) ([]database.GetWorkspaceBuildsToArchiveRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildsToArchive(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.Workspace, error) {
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByAgentID)(ctx, agentID)
 }
@@ -3311,6 +4172,22 @@ func (q *querier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (database.
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByID)(ctx, id)
 }
 
+func (q *querier) GetWorkspaceByIDForUpdate(ctx context.Context, id uuid.UUID) (database.WorkspaceTable, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceTable{}, err
+	}
+	// Only users that can update the workspace may lock it.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceTable{}, err
+	}
+	return q.db.GetWorkspaceByIDForUpdate(ctx, id)
+}
+
+func (q *querier) GetWorkspaceByOrganizationIDAndName(ctx context.Context, arg database.GetWorkspaceByOrganizationIDAndNameParams) (database.Workspace, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceByOrganizationIDAndName)(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg database.GetWorkspaceByOwnerIDAndNameParams) (database.Workspace, error) {
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByOwnerIDAndName)(ctx, arg)
 }
@@ -3323,6 +4200,75 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspaceDailyCosts(ctx context.Context, arg database.GetWorkspaceDailyCostsParams) ([]database.GetWorkspaceDailyCostsRow, error) {
+	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
+		for _, templateID := range arg.TemplateIDs {
+			template, err := q.db.GetTemplateByID(ctx, templateID)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, template); err != nil {
+				return nil, err
+			}
+		}
+		if len(arg.TemplateIDs) == 0 {
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate.All()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return q.db.GetWorkspaceDailyCosts(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceDriftCheckCandidates(ctx context.Context, arg database.GetWorkspaceDriftCheckCandidatesParams) ([]database.GetWorkspaceDriftCheckCandidatesRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceDriftCheckCandidates(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceDriftChecksByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetWorkspaceDriftChecksByWorkspaceIDsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceDriftChecksByWorkspaceIDs(ctx, ids)
+}
+
+func (q *querier) GetWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceLabel, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceLabelsByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceLocksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceLock, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceLocksByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceMigrationByID(ctx context.Context, id uuid.UUID) (database.WorkspaceMigration, error) {
+	migration, err := q.db.GetWorkspaceMigrationByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	// If we can read the workspace, we can read its migrations.
+	if _, err := q.GetWorkspaceByID(ctx, migration.WorkspaceID); err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	return migration, nil
+}
+
+func (q *querier) GetWorkspaceMigrationsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceMigration, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceMigrationsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3337,6 +4283,31 @@ func (q *querier) GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetWorkspaceModulesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspacePrebuildReservation, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return database.WorkspacePrebuildReservation{}, err
+	}
+	return q.db.GetWorkspacePrebuildReservationByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspacePrebuildReservationsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspacePrebuildReservation, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacePrebuildReservationsByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetWorkspaceProvisionerAffinity(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceProvisionerAffinity, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceProvisionerAffinity{}, err
+	}
+	return q.db.GetWorkspaceProvisionerAffinity(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, func(ctx context.Context, _ interface{}) ([]database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxies(ctx)
@@ -3358,6 +4329,13 @@ func (q *querier) GetWorkspaceProxyByName(ctx context.Context, name string) (dat
 	return fetch(q.log, q.auth, q.db.GetWorkspaceProxyByName)(ctx, name)
 }
 
+func (q *querier) GetWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceReleaseChannel, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return database.WorkspaceReleaseChannel{}, err
+	}
+	return q.db.GetWorkspaceReleaseChannelByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	// TODO: Optimize this
 	resource, err := q.db.GetWorkspaceResourceByID(ctx, id)
@@ -3451,6 +4429,35 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	recording, err := q.db.GetWorkspaceSessionRecordingByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	if err := q.authorizeSessionRecordingRead(ctx, recording.WorkspaceID); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	return recording, nil
+}
+
+func (q *querier) GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	recording, err := q.db.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeSessionRecordingRead(ctx, recording.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSessionRecordingChunks(ctx, recordingID)
+}
+
+func (q *querier) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	if err := q.authorizeSessionRecordingRead(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIDs []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3496,6 +4503,13 @@ func (q *querier) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyPar
 		q.db.InsertAPIKey)(ctx, arg)
 }
 
+func (q *querier) InsertAccessRequest(ctx context.Context, arg database.InsertAccessRequestParams) (database.AccessRequest, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.AccessRequest{}, err
+	}
+	return q.db.InsertAccessRequest(ctx, arg)
+}
+
 func (q *querier) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (database.Group, error) {
 	// This method creates a new group.
 	return insert(q.log, q.auth, rbac.ResourceGroup.InOrg(organizationID), q.db.InsertAllUsersGroup)(ctx, organizationID)
@@ -3505,6 +4519,10 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertBuildAlertRule(ctx context.Context, arg database.InsertBuildAlertRuleParams) (database.BuildAlertRule, error) {
+	return insert(q.log, q.auth, rbac.ResourceTemplate.InOrg(arg.OrganizationID), q.db.InsertBuildAlertRule)(ctx, arg)
+}
+
 func (q *querier) InsertCryptoKey(ctx context.Context, arg database.InsertCryptoKeyParams) (database.CryptoKey, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceCryptoKey); err != nil {
 		return database.CryptoKey{}, err
@@ -3592,6 +4610,13 @@ func (q *querier) InsertLicense(ctx context.Context, arg database.InsertLicenseP
 	return q.db.InsertLicense(ctx, arg)
 }
 
+func (q *querier) InsertLoginDeviceCode(ctx context.Context, arg database.InsertLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.LoginDeviceCode{}, err
+	}
+	return q.db.InsertLoginDeviceCode(ctx, arg)
+}
+
 func (q *querier) InsertMemoryResourceMonitor(ctx context.Context, arg database.InsertMemoryResourceMonitorParams) (database.WorkspaceAgentMemoryResourceMonitor, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
 		return database.WorkspaceAgentMemoryResourceMonitor{}, err
@@ -3689,12 +4714,26 @@ func (q *querier) InsertPresetPrebuildSchedule(ctx context.Context, arg database
 	return q.db.InsertPresetPrebuildSchedule(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerBuildPause(ctx context.Context, arg database.InsertProvisionerBuildPauseParams) (database.ProvisionerBuildPause, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, provisionerBuildPauseObject(arg.OrganizationID)); err != nil {
+		return database.ProvisionerBuildPause{}, err
+	}
+	return q.db.InsertProvisionerBuildPause(ctx, arg)
+}
+
 func (q *querier) InsertProvisionerJob(ctx context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	// TODO: Remove this once we have a proper rbac check for provisioner jobs.
 	// Details in https://github.com/coder/coder/issues/16160
 	return q.db.InsertProvisionerJob(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.ProvisionerJobLogArchive{}, err
+	}
+	return q.db.InsertProvisionerJobLogArchive(ctx, arg)
+}
+
 func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	// TODO: Remove this once we have a proper rbac check for provisioner jobs.
 	// Details in https://github.com/coder/coder/issues/16160
@@ -3712,6 +4751,10 @@ func (q *querier) InsertProvisionerKey(ctx context.Context, arg database.InsertP
 	return insert(q.log, q.auth, rbac.ResourceProvisionerDaemon.InOrg(arg.OrganizationID).WithID(arg.ID), q.db.InsertProvisionerKey)(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerReservation(ctx context.Context, arg database.InsertProvisionerReservationParams) (database.ProvisionerReservation, error) {
+	return insert(q.log, q.auth, rbac.ResourceProvisionerDaemon.InOrg(arg.OrganizationID).WithID(arg.ID), q.db.InsertProvisionerReservation)(ctx, arg)
+}
+
 func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -3719,6 +4762,10 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertServiceAccount(ctx context.Context, arg database.InsertServiceAccountParams) (database.ServiceAccount, error) {
+	return insert(q.log, q.auth, rbac.ResourceServiceAccount.WithID(arg.UserID).InOrg(arg.OrganizationID), q.db.InsertServiceAccount)(ctx, arg)
+}
+
 func (q *querier) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3734,6 +4781,17 @@ func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplat
 	return q.db.InsertTemplate(ctx, arg)
 }
 
+func (q *querier) InsertTemplatePreset(ctx context.Context, arg database.InsertTemplatePresetParams) (database.TemplatePreset, error) {
+	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplatePreset{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return database.TemplatePreset{}, err
+	}
+	return q.db.InsertTemplatePreset(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	if !arg.TemplateID.Valid {
 		// Making a new template version is the same permission as creating a new template.
@@ -3796,6 +4854,13 @@ func (q *querier) InsertUser(ctx context.Context, arg database.InsertUserParams)
 	return insert(q.log, q.auth, obj, q.db.InsertUser)(ctx, arg)
 }
 
+func (q *querier) InsertUserActivityExport(ctx context.Context, arg database.InsertUserActivityExportParams) (database.UserActivityExport, error) {
+	if err := q.authorizeTemplateInsights(ctx, nil); err != nil {
+		return database.UserActivityExport{}, err
+	}
+	return q.db.InsertUserActivityExport(ctx, arg)
+}
+
 func (q *querier) InsertUserGroupsByID(ctx context.Context, arg database.InsertUserGroupsByIDParams) ([]uuid.UUID, error) {
 	// This is used by OIDC sync. So only used by a system user.
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -3879,6 +4944,19 @@ func (q *querier) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg dat
 	return q.db.InsertWorkspaceAgentDevcontainers(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentHealthEvent(ctx context.Context, arg database.InsertWorkspaceAgentHealthEventParams) (database.WorkspaceAgentHealthEvent, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return database.WorkspaceAgentHealthEvent{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentHealthEvent{}, err
+	}
+
+	return q.db.InsertWorkspaceAgentHealthEvent(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentLogSources(ctx context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
 	// TODO: This is used by the agent, should we have an rbac check here?
 	return q.db.InsertWorkspaceAgentLogSources(ctx, arg)
@@ -3977,6 +5055,30 @@ func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertW
 	return q.db.InsertWorkspaceBuild(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceBuildArchive(ctx context.Context, arg database.InsertWorkspaceBuildArchiveParams) (database.WorkspaceBuildArchive, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceBuildArchive{}, err
+	}
+	return q.db.InsertWorkspaceBuildArchive(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceBuildDurationNotification(ctx context.Context, arg database.InsertWorkspaceBuildDurationNotificationParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.InsertWorkspaceBuildDurationNotification(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceBuildIdempotencyKey(ctx context.Context, arg database.InsertWorkspaceBuildIdempotencyKeyParams) error {
+	// Keys are reserved before the build exists, they belong to the user that
+	// made the request.
+	err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUser.WithID(arg.UserID).WithOwner(arg.UserID.String()))
+	if err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceBuildIdempotencyKey(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	// TODO: Optimize this. We always have the workspace and build already fetched.
 	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.WorkspaceBuildID)
@@ -3997,6 +5099,67 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceBuildQueueEntry(ctx context.Context, arg database.InsertWorkspaceBuildQueueEntryParams) (database.WorkspaceBuildQueue, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceBuildQueue{}, xerrors.Errorf("get workspace by id: %w", err)
+	}
+
+	// Queued builds are started by the system, so the permissions to build
+	// the workspace are checked when the build is queued.
+	var action policy.Action = policy.ActionWorkspaceStart
+	if arg.Transition == database.WorkspaceTransitionDelete {
+		action = policy.ActionDelete
+	} else if arg.Transition == database.WorkspaceTransitionStop {
+		action = policy.ActionWorkspaceStop
+	}
+	if err := q.authorizePrebuiltWorkspace(ctx, action, w); err != nil {
+		return database.WorkspaceBuildQueue{}, err
+	}
+
+	if arg.Transition == database.WorkspaceTransitionStart && arg.TemplateVersionID.Valid {
+		t, err := q.db.GetTemplateByID(ctx, w.TemplateID)
+		if err != nil {
+			return database.WorkspaceBuildQueue{}, xerrors.Errorf("get template by id: %w", err)
+		}
+		accessControl := (*q.acs.Load()).GetTemplateAccessControl(t)
+		if accessControl.RequireActiveVersion && arg.TemplateVersionID.UUID != t.ActiveVersionID {
+			if err = q.authorizeContext(ctx, policy.ActionUpdate, t); err != nil {
+				return database.WorkspaceBuildQueue{}, xerrors.Errorf("cannot use non-active version: %w", err)
+			}
+		}
+	}
+
+	return q.db.InsertWorkspaceBuildQueueEntry(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceLabels(ctx context.Context, arg database.InsertWorkspaceLabelsParams) error {
+	fetch := func(ctx context.Context, arg database.InsertWorkspaceLabelsParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	}
+	return update(q.log, q.auth, fetch, q.db.InsertWorkspaceLabels)(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceLock(ctx context.Context, arg database.InsertWorkspaceLockParams) (database.WorkspaceLock, error) {
+	if err := q.authorizeWorkspaceTemplateUpdate(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspaceLock{}, err
+	}
+	return q.db.InsertWorkspaceLock(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceMigration(ctx context.Context, arg database.InsertWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceMigration{}, xerrors.Errorf("get workspace by id: %w", err)
+	}
+	// The migration stops and deletes the workspace on behalf of the
+	// initiator.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	return q.db.InsertWorkspaceMigration(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceModule{}, err
@@ -4022,6 +5185,33 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	return q.db.InsertWorkspaceSessionRecording(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (database.WorkspaceSessionRecordingChunk, error) {
+	if err := q.authorizeSessionRecordingUpdate(ctx, arg.RecordingID); err != nil {
+		return database.WorkspaceSessionRecordingChunk{}, err
+	}
+	return q.db.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+}
+
+func (q *querier) IsTemplateVersionRolloutCohortMember(ctx context.Context, arg database.IsTemplateVersionRolloutCohortMemberParams) (bool, error) {
+	if _, err := q.GetTemplateByID(ctx, arg.TemplateID); err != nil {
+		return false, err
+	}
+	return q.db.IsTemplateVersionRolloutCohortMember(ctx, arg)
+}
+
 func (q *querier) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.ListProvisionerKeysByOrganization)(ctx, organizationID)
 }
@@ -4054,6 +5244,13 @@ func (q *querier) MarkAllInboxNotificationsAsRead(ctx context.Context, arg datab
 	return q.db.MarkAllInboxNotificationsAsRead(ctx, arg)
 }
 
+func (q *querier) MarkNotificationMessagesDigested(ctx context.Context, arg database.MarkNotificationMessagesDigestedParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationMessage); err != nil {
+		return 0, err
+	}
+	return q.db.MarkNotificationMessagesDigested(ctx, arg)
+}
+
 func (q *querier) OIDCClaimFieldValues(ctx context.Context, args database.OIDCClaimFieldValuesParams) ([]string, error) {
 	resource := rbac.ResourceIdpsyncSettings
 	if args.OrganizationID != uuid.Nil {
@@ -4125,6 +5322,19 @@ func (q *querier) RemoveUserFromGroups(ctx context.Context, arg database.RemoveU
 	return q.db.RemoveUserFromGroups(ctx, arg)
 }
 
+func (q *querier) ResolveWorkspaceAgentHealthEvent(ctx context.Context, arg database.ResolveWorkspaceAgentHealthEventParams) (int64, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return 0, err
+	}
+
+	return q.db.ResolveWorkspaceAgentHealthEvent(ctx, arg)
+}
+
 func (q *querier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -4132,6 +5342,22 @@ func (q *querier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string)
 	return q.db.RevokeDBCryptKey(ctx, activeKeyDigest)
 }
 
+func (q *querier) RotateProvisionerKey(ctx context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+	fetch := func(ctx context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+		return q.db.GetProvisionerKeyByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.RotateProvisionerKey)(ctx, arg)
+}
+
+func (q *querier) SearchProvisionerJobLogs(ctx context.Context, arg database.SearchProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
+	// Authorized read on job lets the actor also read the logs.
+	_, err := q.GetProvisionerJobByID(ctx, arg.JobID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.SearchProvisionerJobLogs(ctx, arg)
+}
+
 func (q *querier) TryAcquireLock(ctx context.Context, id int64) (bool, error) {
 	return q.db.TryAcquireLock(ctx, id)
 }
@@ -4159,6 +5385,13 @@ func (q *querier) UnfavoriteWorkspace(ctx context.Context, id uuid.UUID) error {
 	return update(q.log, q.auth, fetch, q.db.UnfavoriteWorkspace)(ctx, id)
 }
 
+func (q *querier) UnlockWorkspace(ctx context.Context, arg database.UnlockWorkspaceParams) (database.WorkspaceLock, error) {
+	if err := q.authorizeWorkspaceTemplateUpdate(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspaceLock{}, err
+	}
+	return q.db.UnlockWorkspace(ctx, arg)
+}
+
 func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKeyByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateAPIKeyByIDParams) (database.APIKey, error) {
 		return q.db.GetAPIKeyByID(ctx, arg.ID)
@@ -4166,6 +5399,34 @@ func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKe
 	return update(q.log, q.auth, fetch, q.db.UpdateAPIKeyByID)(ctx, arg)
 }
 
+func (q *querier) UpdateAccessRequestExpiredByID(ctx context.Context, id uuid.UUID) (database.AccessRequest, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.AccessRequest{}, err
+	}
+	return q.db.UpdateAccessRequestExpiredByID(ctx, id)
+}
+
+func (q *querier) UpdateAccessRequestReviewByID(ctx context.Context, arg database.UpdateAccessRequestReviewByIDParams) (database.AccessRequest, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.AccessRequest{}, err
+	}
+	return q.db.UpdateAccessRequestReviewByID(ctx, arg)
+}
+
+func (q *querier) UpdateBuildAlertRuleByID(ctx context.Context, arg database.UpdateBuildAlertRuleByIDParams) (database.BuildAlertRule, error) {
+	fetch := func(ctx context.Context, arg database.UpdateBuildAlertRuleByIDParams) (database.BuildAlertRule, error) {
+		return q.db.GetBuildAlertRuleByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateBuildAlertRuleByID)(ctx, arg)
+}
+
+func (q *querier) UpdateBuildAlertRuleEvaluation(ctx context.Context, arg database.UpdateBuildAlertRuleEvaluationParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateBuildAlertRuleEvaluation(ctx, arg)
+}
+
 func (q *querier) UpdateCryptoKeyDeletesAt(ctx context.Context, arg database.UpdateCryptoKeyDeletesAtParams) (database.CryptoKey, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceCryptoKey); err != nil {
 		return database.CryptoKey{}, err
@@ -4322,6 +5583,15 @@ func (q *querier) UpdateOrganizationDeletedByID(ctx context.Context, arg databas
 	return deleteQ(q.log, q.auth, q.db.GetOrganizationByID, deleteF)(ctx, arg.ID)
 }
 
+func (q *querier) UpdatePendingProvisionerJobsUpdatedAt(ctx context.Context, arg database.UpdatePendingProvisionerJobsUpdatedAtParams) ([]database.ProvisionerJob, error) {
+	// Those who can resume builds can restart the pending timeout of the
+	// jobs that were held.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, provisionerBuildPauseObject(arg.OrganizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.UpdatePendingProvisionerJobsUpdatedAt(ctx, arg)
+}
+
 func (q *querier) UpdatePresetPrebuildStatus(ctx context.Context, arg database.UpdatePresetPrebuildStatusParams) error {
 	preset, err := q.db.GetPresetByID(ctx, arg.PresetID)
 	if err != nil {
@@ -4438,6 +5708,13 @@ func (q *querier) UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx context.
 	return q.db.UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) (database.ProvisionerKey, error) {
+		return q.db.GetProvisionerKeyByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateProvisionerKeyLastUsedAt)(ctx, arg)
+}
+
 func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -4487,6 +5764,21 @@ func (q *querier) UpdateTemplateMetaByID(ctx context.Context, arg database.Updat
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateMetaByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplatePresetByID(ctx context.Context, arg database.UpdateTemplatePresetByIDParams) (database.TemplatePreset, error) {
+	preset, err := q.db.GetTemplatePresetByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplatePreset{}, err
+	}
+	tpl, err := q.db.GetTemplateByID(ctx, preset.TemplateID)
+	if err != nil {
+		return database.TemplatePreset{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return database.TemplatePreset{}, err
+	}
+	return q.db.UpdateTemplatePresetByID(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -4582,6 +5874,13 @@ func (q *querier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.
 	return q.db.UpdateTemplateVersionExternalAuthProvidersByJobID(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateVersionVariableValue(ctx context.Context, arg database.UpdateTemplateVersionVariableValueParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateVersionVariableValue(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.TemplateID)
@@ -4590,6 +5889,13 @@ func (q *querier) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg da
 	return fetchAndExec(q.log, q.auth, policy.ActionUpdate, fetch, q.db.UpdateTemplateWorkspacesLastUsedAt)(ctx, arg)
 }
 
+func (q *querier) UpdateUserActivityExportStatusByID(ctx context.Context, arg database.UpdateUserActivityExportStatusByIDParams) (database.UserActivityExport, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.UserActivityExport{}, err
+	}
+	return q.db.UpdateUserActivityExportStatusByID(ctx, arg)
+}
+
 func (q *querier) UpdateUserDeletedByID(ctx context.Context, id uuid.UUID) error {
 	return deleteQ(q.log, q.auth, q.db.GetUserByID, q.db.UpdateUserDeletedByID)(ctx, id)
 }
@@ -4668,6 +5974,27 @@ func (q *querier) UpdateUserLoginType(ctx context.Context, arg database.UpdateUs
 	return q.db.UpdateUserLoginType(ctx, arg)
 }
 
+func (q *querier) UpdateUserNotificationBuildDurationThreshold(ctx context.Context, arg database.UpdateUserNotificationBuildDurationThresholdParams) (database.UserConfig, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
+		return database.UserConfig{}, err
+	}
+	return q.db.UpdateUserNotificationBuildDurationThreshold(ctx, arg)
+}
+
+func (q *querier) UpdateUserNotificationDigestFrequency(ctx context.Context, arg database.UpdateUserNotificationDigestFrequencyParams) (database.UserConfig, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
+		return database.UserConfig{}, err
+	}
+	return q.db.UpdateUserNotificationDigestFrequency(ctx, arg)
+}
+
+func (q *querier) UpdateUserNotificationDigestPreferences(ctx context.Context, arg database.UpdateUserNotificationDigestPreferencesParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
+		return -1, err
+	}
+	return q.db.UpdateUserNotificationDigestPreferences(ctx, arg)
+}
+
 func (q *querier) UpdateUserNotificationPreferences(ctx context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
 		return -1, err
@@ -4768,6 +6095,14 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	// Only users that can update the workspace may share it.
+	return fetchAndExec(q.log, q.auth, policy.ActionUpdate, fetch, q.db.UpdateWorkspaceACLByID)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -4916,13 +6251,49 @@ func (q *querier) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg data
 	return q.db.UpdateWorkspaceBuildDeadlineByID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceBuildIdempotencyKeyBuildID(ctx context.Context, arg database.UpdateWorkspaceBuildIdempotencyKeyBuildIDParams) error {
+	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.WorkspaceBuildID)
+	if err != nil {
+		return err
+	}
+
+	workspace, err := q.db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizePrebuiltWorkspace(ctx, policy.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceBuildIdempotencyKeyBuildID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg database.UpdateWorkspaceBuildProvisionerStateByIDParams) error {
-	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+
+	workspace, err := q.db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionUpdate, workspace.RBACObject())
+	if err != nil {
 		return err
 	}
 	return q.db.UpdateWorkspaceBuildProvisionerStateByID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceBuildQueueEntryFailed(ctx context.Context, arg database.UpdateWorkspaceBuildQueueEntryFailedParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceBuildQueueEntryFailed(ctx, arg)
+}
+
 // Deprecated: Use SoftDeleteWorkspaceByID
 func (q *querier) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	// TODO deleteQ me, placeholder for database.Store
@@ -4944,6 +6315,13 @@ func (q *querier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg data
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceDormantDeletingAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceDriftCheckByJobID(ctx context.Context, arg database.UpdateWorkspaceDriftCheckByJobIDParams) (database.WorkspaceDriftCheck, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceDriftCheck{}, err
+	}
+	return q.db.UpdateWorkspaceDriftCheckByJobID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceLastUsedAtParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -4951,6 +6329,20 @@ func (q *querier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.Up
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceLastUsedAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceMigrationSessionToken(ctx context.Context, arg database.UpdateWorkspaceMigrationSessionTokenParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceMigrationSessionToken(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceMigrationStatus(ctx context.Context, arg database.UpdateWorkspaceMigrationStatusParams) (database.WorkspaceMigration, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	return q.db.UpdateWorkspaceMigrationStatus(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceNextStartAt(ctx context.Context, arg database.UpdateWorkspaceNextStartAtParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceNextStartAtParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -4972,6 +6364,20 @@ func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyDeleted)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceSessionRecording(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	if err := q.authorizeSessionRecordingUpdate(ctx, arg.ID); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	return q.db.UpdateWorkspaceSessionRecording(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceSessionRecordingChunkData(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingChunkDataParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceSessionRecordingChunkData(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -5029,6 +6435,13 @@ func (q *querier) UpsertCoordinatorResumeTokenSigningKey(ctx context.Context, va
 	return q.db.UpsertCoordinatorResumeTokenSigningKey(ctx, value)
 }
 
+func (q *querier) UpsertDBPurgeStats(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertDBPurgeStats(ctx, value)
+}
+
 func (q *querier) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5043,6 +6456,15 @@ func (q *querier) UpsertHealthSettings(ctx context.Context, value string) error
 	return q.db.UpsertHealthSettings(ctx, value)
 }
 
+// UpsertJobReaperLastRunAt is authorized against provisioner jobs rather than
+// the system, as the job reaper doesn't get the broad system permissions.
+func (q *querier) UpsertJobReaperLastRunAt(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceProvisionerJobs); err != nil {
+		return err
+	}
+	return q.db.UpsertJobReaperLastRunAt(ctx, value)
+}
+
 func (q *querier) UpsertLastUpdateCheck(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5085,6 +6507,18 @@ func (q *querier) UpsertOAuthSigningKey(ctx context.Context, value string) error
 	return q.db.UpsertOAuthSigningKey(ctx, value)
 }
 
+func (q *querier) UpsertOrganizationWorkspaceNamingPolicy(ctx context.Context, arg database.UpsertOrganizationWorkspaceNamingPolicyParams) (database.WorkspaceNamingPolicy, error) {
+	org, err := q.db.GetOrganizationByID(ctx, arg.OrganizationID)
+	if err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, org); err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+	return q.db.UpsertOrganizationWorkspaceNamingPolicy(ctx, arg)
+}
+
 func (q *querier) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	res := rbac.ResourceProvisionerDaemon.InOrg(arg.OrganizationID)
 	if arg.Tags[provisionersdk.TagScope] == provisionersdk.ScopeUser {
@@ -5096,6 +6530,13 @@ func (q *querier) UpsertProvisionerDaemon(ctx context.Context, arg database.Upse
 	return q.db.UpsertProvisionerDaemon(ctx, arg)
 }
 
+func (q *querier) UpsertReadOnlySettings(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return err
+	}
+	return q.db.UpsertReadOnlySettings(ctx, value)
+}
+
 func (q *querier) UpsertRuntimeConfig(ctx context.Context, arg database.UpsertRuntimeConfigParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5152,6 +6593,51 @@ func (q *querier) UpsertTelemetryItem(ctx context.Context, arg database.UpsertTe
 	return q.db.UpsertTelemetryItem(ctx, arg)
 }
 
+func (q *querier) UpsertTemplateBuildDurationStats(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertTemplateBuildDurationStats(ctx)
+}
+
+func (q *querier) UpsertTemplatePresetGroupDefault(ctx context.Context, arg database.UpsertTemplatePresetGroupDefaultParams) error {
+	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return err
+	}
+	return q.db.UpsertTemplatePresetGroupDefault(ctx, arg)
+}
+
+func (q *querier) UpsertTemplateReleaseChannel(ctx context.Context, arg database.UpsertTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	return q.db.UpsertTemplateReleaseChannel(ctx, arg)
+}
+
+func (q *querier) UpsertTemplateShare(ctx context.Context, arg database.UpsertTemplateShareParams) (database.TemplateShare, error) {
+	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateShare{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return database.TemplateShare{}, err
+	}
+	// The actor must also be able to see the organization the template is
+	// shared into.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganization.WithID(arg.OrganizationID).InOrg(arg.OrganizationID)); err != nil {
+		return database.TemplateShare{}, err
+	}
+	return q.db.UpsertTemplateShare(ctx, arg)
+}
+
 func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5159,6 +6645,29 @@ func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	return q.db.UpsertTemplateUsageStats(ctx)
 }
 
+func (q *querier) UpsertTemplateVersionRollout(ctx context.Context, arg database.UpsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.UpsertTemplateVersionRollout(ctx, arg)
+}
+
+func (q *querier) UpsertTemplateWorkspaceNamingPolicy(ctx context.Context, arg database.UpsertTemplateWorkspaceNamingPolicyParams) (database.WorkspaceNamingPolicy, error) {
+	tpl, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, tpl); err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+	return q.db.UpsertTemplateWorkspaceNamingPolicy(ctx, arg)
+}
+
 func (q *querier) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -5166,6 +6675,19 @@ func (q *querier) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.Upser
 	return q.db.UpsertWebpushVAPIDKeys(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceAgentConnectionQuality(ctx context.Context, arg database.UpsertWorkspaceAgentConnectionQualityParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceAgentConnectionQuality(ctx, arg)
+}
+
 func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -5180,6 +6702,19 @@ func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg databas
 	return q.db.UpsertWorkspaceAgentPortShare(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceAgentResourceUsage(ctx context.Context, arg database.UpsertWorkspaceAgentResourceUsageParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceAgentResourceUsage(ctx, arg)
+}
+
 func (q *querier) UpsertWorkspaceApp(ctx context.Context, arg database.UpsertWorkspaceAppParams) (database.WorkspaceApp, error) {
 	// NOTE(DanielleMaywood):
 	// It is possible for there to exist an agent without a workspace.
@@ -5204,6 +6739,48 @@ func (q *querier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg databa
 	return q.db.UpsertWorkspaceAppAuditSession(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceBuildInterimState(ctx context.Context, arg database.UpsertWorkspaceBuildInterimStateParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceBuildInterimState(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceDailyCosts(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceDailyCosts(ctx)
+}
+
+func (q *querier) UpsertWorkspaceDriftCheck(ctx context.Context, arg database.UpsertWorkspaceDriftCheckParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceDriftCheck(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspacePrebuildReservation(ctx context.Context, arg database.UpsertWorkspacePrebuildReservationParams) (database.WorkspacePrebuildReservation, error) {
+	if err := q.authorizeWorkspaceTemplateUpdate(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspacePrebuildReservation{}, err
+	}
+	return q.db.UpsertWorkspacePrebuildReservation(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceProvisionerAffinity(ctx context.Context, arg database.UpsertWorkspaceProvisionerAffinityParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceProvisionerAffinity(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceReleaseChannel(ctx context.Context, arg database.UpsertWorkspaceReleaseChannelParams) error {
+	fetch := func(ctx context.Context, arg database.UpsertWorkspaceReleaseChannelParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpsertWorkspaceReleaseChannel)(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
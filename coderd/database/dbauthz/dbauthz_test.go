@@ -206,6 +206,21 @@ func defaultIPAddress() pqtype.Inet {
 	}
 }
 
+func insertTemplatePreset(t *testing.T, db database.Store, templateID uuid.UUID) database.TemplatePreset {
+	t.Helper()
+	preset, err := db.InsertTemplatePreset(context.Background(), database.InsertTemplatePresetParams{
+		ID:          uuid.New(),
+		TemplateID:  templateID,
+		Name:        "small",
+		Description: "A small workspace.",
+		Parameters:  database.StringMap{"cpu": "2"},
+		CreatedAt:   dbtime.Now(),
+		UpdatedAt:   dbtime.Now(),
+	})
+	require.NoError(t, err)
+	return preset
+}
+
 func (s *MethodTestSuite) TestAPIKey() {
 	s.Run("DeleteAPIKeyByID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
@@ -312,6 +327,13 @@ func (s *MethodTestSuite) TestAuditLogs() {
 			AdditionalFields: json.RawMessage("{}"),
 		}).Asserts(rbac.ResourceAuditLog, policy.ActionCreate)
 	}))
+	s.Run("GetAuditLogsForExport", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(database.GetAuditLogsForExportParams{
+			BeforeTime: dbtime.Now().Add(time.Hour),
+			LimitCount: 10,
+		}).Asserts(rbac.ResourceAuditLog, policy.ActionRead)
+	}))
 	s.Run("GetAuditLogsOffset", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
 		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
@@ -730,6 +752,72 @@ func (s *MethodTestSuite) TestProvisionerJob() {
 			JobID: j.ID,
 		}).Asserts(w, policy.ActionRead).Returns([]database.ProvisionerJobLog{})
 	}))
+	s.Run("SearchProvisionerJobLogs", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+			TemplateID:     tpl.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(database.SearchProvisionerJobLogsParams{
+			JobID:    j.ID,
+			MinLevel: database.NullLogLevel{LogLevel: database.LogLevelWarn, Valid: true},
+			Search:   "error",
+		}).Asserts(w, policy.ActionRead).Returns([]database.ProvisionerJobLog{})
+	}))
+	s.Run("GetProvisionerJobLogArchiveByJobID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+			TemplateID:     tpl.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		f := dbgen.File(s.T(), db, database.File{CreatedBy: u.ID})
+		archive, err := db.InsertProvisionerJobLogArchive(context.Background(), database.InsertProvisionerJobLogArchiveParams{
+			JobID:      j.ID,
+			FileID:     f.ID,
+			LogCount:   1,
+			ArchivedAt: dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(j.ID).Asserts(w, policy.ActionRead).Returns(archive)
+	}))
 }
 
 func (s *MethodTestSuite) TestLicense() {
@@ -808,6 +896,13 @@ func (s *MethodTestSuite) TestOrganization() {
 			OrganizationID: id,
 		}).Asserts(rbac.ResourceIdpsyncSettings.InOrg(id), policy.ActionRead).Returns([]string{})
 	}))
+	s.Run("Deployment/GetOIDCUserClaims", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.Nil).Asserts(rbac.ResourceIdpsyncSettings, policy.ActionRead).Returns([]database.GetOIDCUserClaimsRow{})
+	}))
+	s.Run("Organization/GetOIDCUserClaims", s.Subtest(func(db database.Store, check *expects) {
+		id := uuid.New()
+		check.Args(id).Asserts(rbac.ResourceIdpsyncSettings.InOrg(id), policy.ActionRead).Returns([]database.GetOIDCUserClaimsRow{})
+	}))
 	s.Run("ByOrganization/GetGroups", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		a := dbgen.Group(s.T(), db, database.Group{OrganizationID: o.ID})
@@ -1189,6 +1284,11 @@ func (s *MethodTestSuite) TestTemplate() {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(t1.ID).Asserts(t1, policy.ActionRead).Returns(t1)
 	}))
+	s.Run("GetTemplateByIDForUpdate", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionCreate)
+	}))
 	s.Run("GetTemplateByOrganizationAndName", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		o1 := dbgen.Organization(s.T(), db, database.Organization{})
@@ -1543,484 +1643,1102 @@ func (s *MethodTestSuite) TestTemplate() {
 	s.Run("UpsertTemplateUsageStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
-}
-
-func (s *MethodTestSuite) TestUser() {
-	s.Run("GetAuthorizedUsers", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		dbgen.User(s.T(), db, database.User{})
-		// No asserts because SQLFilter.
-		check.Args(database.GetUsersParams{}, emptyPreparedAuthorized{}).
-			Asserts()
-	}))
-	s.Run("DeleteAPIKeysByUserID", s.Subtest(func(db database.Store, check *expects) {
-		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(u.ID).Asserts(rbac.ResourceApiKey.WithOwner(u.ID.String()), policy.ActionDelete).Returns()
-	}))
-	s.Run("GetQuotaAllowanceForUser", s.Subtest(func(db database.Store, check *expects) {
-		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.GetQuotaAllowanceForUserParams{
-			UserID:         u.ID,
-			OrganizationID: uuid.New(),
-		}).Asserts(u, policy.ActionRead).Returns(int64(0))
-	}))
-	s.Run("GetQuotaConsumedForUser", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetTemplateBuildDurationStats", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.GetQuotaConsumedForUserParams{
-			OwnerID:        u.ID,
-			OrganizationID: uuid.New(),
-		}).Asserts(u, policy.ActionRead).Returns(int64(0))
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.GetTemplateBuildDurationStatsParams{
+			TemplateID: tpl.ID,
+			StartTime:  dbtime.Now().Add(-time.Hour * 24 * 30),
+		}).Asserts(tpl, policy.ActionRead)
 	}))
-	s.Run("GetUserByEmailOrUsername", s.Subtest(func(db database.Store, check *expects) {
-		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.GetUserByEmailOrUsernameParams{
-			Username: u.Username,
-			Email:    u.Email,
-		}).Asserts(u, policy.ActionRead).Returns(u)
+	s.Run("UpsertTemplateBuildDurationStats", s.Subtest(func(db database.Store, check *expects) {
+		check.Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
-	s.Run("GetUserByID", s.Subtest(func(db database.Store, check *expects) {
-		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(u.ID).Asserts(u, policy.ActionRead).Returns(u)
+	s.Run("GetWorkspaceDailyCosts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceDailyCostsParams{}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
 	}))
-	s.Run("GetUsersByIDs", s.Subtest(func(db database.Store, check *expects) {
-		a := dbgen.User(s.T(), db, database.User{CreatedAt: dbtime.Now().Add(-time.Hour)})
-		b := dbgen.User(s.T(), db, database.User{CreatedAt: dbtime.Now()})
-		check.Args([]uuid.UUID{a.ID, b.ID}).
-			Asserts(a, policy.ActionRead, b, policy.ActionRead).
-			Returns(slice.New(a, b))
+	s.Run("UpsertWorkspaceDailyCosts", s.Subtest(func(db database.Store, check *expects) {
+		check.Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
-	s.Run("GetUsers", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		dbgen.User(s.T(), db, database.User{Username: "GetUsers-a-user"})
-		dbgen.User(s.T(), db, database.User{Username: "GetUsers-b-user"})
-		check.Args(database.GetUsersParams{}).
-			// Asserts are done in a SQL filter
-			Asserts()
+	s.Run("GetUserDailyActivity", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetUserDailyActivityParams{}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
 	}))
-	s.Run("InsertUser", s.Subtest(func(db database.Store, check *expects) {
-		check.Args(database.InsertUserParams{
+	s.Run("InsertUserActivityExport", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserActivityExportParams{
 			ID:        uuid.New(),
-			LoginType: database.LoginTypePassword,
-			RBACRoles: []string{},
-		}).Asserts(rbac.ResourceAssignRole, policy.ActionAssign, rbac.ResourceUser, policy.ActionCreate)
+			CreatedBy: u.ID,
+			StartTime: dbtime.Now().Add(-24 * time.Hour),
+			EndTime:   dbtime.Now(),
+			Format:    database.UserActivityExportFormatCsv,
+		}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
 	}))
-	s.Run("InsertUserLink", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetUserActivityExportByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.InsertUserLinkParams{
-			UserID:    u.ID,
-			LoginType: database.LoginTypeOIDC,
-		}).Asserts(u, policy.ActionUpdate)
+		export := dbgen.UserActivityExport(s.T(), db, database.UserActivityExport{CreatedBy: u.ID})
+		check.Args(export.ID).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights).Returns(export)
 	}))
-	s.Run("UpdateUserDeletedByID", s.Subtest(func(db database.Store, check *expects) {
-		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(u.ID).Asserts(u, policy.ActionDelete).Returns()
+	s.Run("GetPendingUserActivityExports", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
-	s.Run("UpdateUserGithubComUserID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpdateUserActivityExportStatusByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.UpdateUserGithubComUserIDParams{
-			ID: u.ID,
-		}).Asserts(u, policy.ActionUpdatePersonal)
+		export := dbgen.UserActivityExport(s.T(), db, database.UserActivityExport{CreatedBy: u.ID})
+		check.Args(database.UpdateUserActivityExportStatusByIDParams{
+			ID:     export.ID,
+			Status: database.UserActivityExportStatusFailed,
+			Error:  "failed",
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
-	s.Run("UpdateUserHashedPassword", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetTemplatePresetsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.UpdateUserHashedPasswordParams{
-			ID: u.ID,
-		}).Asserts(u, policy.ActionUpdatePersonal).Returns()
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		preset := insertTemplatePreset(s.T(), db, tpl.ID)
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead).Returns([]database.TemplatePreset{preset})
 	}))
-	s.Run("UpdateUserHashedOneTimePasscode", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetTemplatePresetByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.UpdateUserHashedOneTimePasscodeParams{
-			ID:                       u.ID,
-			HashedOneTimePasscode:    []byte{},
-			OneTimePasscodeExpiresAt: sql.NullTime{Time: u.CreatedAt, Valid: true},
-		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		preset := insertTemplatePreset(s.T(), db, tpl.ID)
+		check.Args(preset.ID).Asserts(tpl, policy.ActionRead).Returns(preset)
 	}))
-	s.Run("UpdateUserQuietHoursSchedule", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("InsertTemplatePreset", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.UpdateUserQuietHoursScheduleParams{
-			ID: u.ID,
-		}).Asserts(u, policy.ActionUpdatePersonal)
-	}))
-	s.Run("UpdateUserLastSeenAt", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.InsertTemplatePresetParams{
+			ID:         uuid.New(),
+			TemplateID: tpl.ID,
+			Name:       "small",
+			Parameters: database.StringMap{"cpu": "2"},
+			CreatedAt:  dbtime.Now(),
+			UpdatedAt:  dbtime.Now(),
+		}).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("UpdateTemplatePresetByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.UpdateUserLastSeenAtParams{
-			ID:         u.ID,
-			UpdatedAt:  u.UpdatedAt,
-			LastSeenAt: u.LastSeenAt,
-		}).Asserts(u, policy.ActionUpdate).Returns(u)
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		preset := insertTemplatePreset(s.T(), db, tpl.ID)
+		check.Args(database.UpdateTemplatePresetByIDParams{
+			ID:         preset.ID,
+			Name:       "large",
+			Parameters: database.StringMap{"cpu": "8"},
+			UpdatedAt:  dbtime.Now(),
+		}).Asserts(tpl, policy.ActionUpdate)
 	}))
-	s.Run("UpdateUserProfile", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("DeleteTemplatePresetByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.UpdateUserProfileParams{
-			ID:        u.ID,
-			Email:     u.Email,
-			Username:  u.Username,
-			Name:      u.Name,
-			UpdatedAt: u.UpdatedAt,
-		}).Asserts(u, policy.ActionUpdatePersonal).Returns(u)
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		preset := insertTemplatePreset(s.T(), db, tpl.ID)
+		check.Args(preset.ID).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
-	s.Run("GetUserWorkspaceBuildParameters", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetTemplatePresetGroupDefaultsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(
-			database.GetUserWorkspaceBuildParametersParams{
-				OwnerID:    u.ID,
-				TemplateID: uuid.UUID{},
-			},
-		).Asserts(u, policy.ActionReadPersonal).Returns(
-			[]database.GetUserWorkspaceBuildParametersRow{},
-		)
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead)
 	}))
-	s.Run("GetUserThemePreference", s.Subtest(func(db database.Store, check *expects) {
-		ctx := context.Background()
+	s.Run("UpsertTemplatePresetGroupDefault", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		db.UpdateUserThemePreference(ctx, database.UpdateUserThemePreferenceParams{
-			UserID:          u.ID,
-			ThemePreference: "light",
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
 		})
-		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns("light")
+		g := dbgen.Group(s.T(), db, database.Group{OrganizationID: org.ID})
+		preset := insertTemplatePreset(s.T(), db, tpl.ID)
+		check.Args(database.UpsertTemplatePresetGroupDefaultParams{
+			TemplateID:       tpl.ID,
+			GroupID:          g.ID,
+			TemplatePresetID: preset.ID,
+		}).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
-	s.Run("UpdateUserThemePreference", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("DeleteTemplatePresetGroupDefaultsByPresetID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		uc := database.UserConfig{
-			UserID: u.ID,
-			Key:    "theme_preference",
-			Value:  "dark",
-		}
-		check.Args(database.UpdateUserThemePreferenceParams{
-			UserID:          u.ID,
-			ThemePreference: uc.Value,
-		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		preset := insertTemplatePreset(s.T(), db, tpl.ID)
+		check.Args(preset.ID).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
-	s.Run("GetUserTerminalFont", s.Subtest(func(db database.Store, check *expects) {
-		ctx := context.Background()
+	s.Run("GetDefaultTemplatePresetForUser", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		db.UpdateUserTerminalFont(ctx, database.UpdateUserTerminalFontParams{
-			UserID:       u.ID,
-			TerminalFont: "ibm-plex-mono",
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
 		})
-		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns("ibm-plex-mono")
+		g := dbgen.Group(s.T(), db, database.Group{OrganizationID: org.ID})
+		dbgen.GroupMember(s.T(), db, database.GroupMemberTable{UserID: u.ID, GroupID: g.ID})
+		preset := insertTemplatePreset(s.T(), db, tpl.ID)
+		err := db.UpsertTemplatePresetGroupDefault(context.Background(), database.UpsertTemplatePresetGroupDefaultParams{
+			TemplateID:       tpl.ID,
+			GroupID:          g.ID,
+			TemplatePresetID: preset.ID,
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetDefaultTemplatePresetForUserParams{
+			TemplateID: tpl.ID,
+			UserID:     u.ID,
+		}).Asserts(tpl, policy.ActionRead).Returns(preset)
 	}))
-	s.Run("UpdateUserTerminalFont", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetTemplateVersionRolloutByTemplateID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		uc := database.UserConfig{
-			UserID: u.ID,
-			Key:    "terminal_font",
-			Value:  "ibm-plex-mono",
-		}
-		check.Args(database.UpdateUserTerminalFontParams{
-			UserID:       u.ID,
-			TerminalFont: uc.Value,
-		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		rollout, err := db.UpsertTemplateVersionRollout(context.Background(), database.UpsertTemplateVersionRolloutParams{
+			TemplateID:        tpl.ID,
+			TemplateVersionID: tv.ID,
+			Percent:           10,
+			CreatedBy:         u.ID,
+			CreatedAt:         dbtime.Now(),
+			UpdatedAt:         dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead).Returns(rollout)
 	}))
-	s.Run("UpdateUserStatus", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpsertTemplateVersionRollout", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.UpdateUserStatusParams{
-			ID:        u.ID,
-			Status:    u.Status,
-			UpdatedAt: u.UpdatedAt,
-		}).Asserts(u, policy.ActionUpdate).Returns(u)
-	}))
-	s.Run("DeleteGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
-		check.Args(key.UserID).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionUpdatePersonal).Returns()
-	}))
-	s.Run("GetGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
-		check.Args(key.UserID).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionReadPersonal).Returns(key)
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.UpsertTemplateVersionRolloutParams{
+			TemplateID:        tpl.ID,
+			TemplateVersionID: tv.ID,
+			Percent:           10,
+			CreatedBy:         u.ID,
+			CreatedAt:         dbtime.Now(),
+			UpdatedAt:         dbtime.Now(),
+		}).Asserts(tpl, policy.ActionUpdate)
 	}))
-	s.Run("InsertGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("DeleteTemplateVersionRolloutByTemplateID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.InsertGitSSHKeyParams{
-			UserID: u.ID,
-		}).Asserts(u, policy.ActionUpdatePersonal)
-	}))
-	s.Run("UpdateGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
-		check.Args(database.UpdateGitSSHKeyParams{
-			UserID:    key.UserID,
-			UpdatedAt: key.UpdatedAt,
-		}).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionUpdatePersonal).Returns(key)
-	}))
-	s.Run("GetExternalAuthLink", s.Subtest(func(db database.Store, check *expects) {
-		link := dbgen.ExternalAuthLink(s.T(), db, database.ExternalAuthLink{})
-		check.Args(database.GetExternalAuthLinkParams{
-			ProviderID: link.ProviderID,
-			UserID:     link.UserID,
-		}).Asserts(rbac.ResourceUserObject(link.UserID), policy.ActionReadPersonal).Returns(link)
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
-	s.Run("InsertExternalAuthLink", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("IsTemplateVersionRolloutCohortMember", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(database.InsertExternalAuthLinkParams{
-			ProviderID: uuid.NewString(),
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.IsTemplateVersionRolloutCohortMemberParams{
+			TemplateID: tpl.ID,
 			UserID:     u.ID,
-		}).Asserts(u, policy.ActionUpdatePersonal)
+		}).Asserts(tpl, policy.ActionRead).Returns(false)
 	}))
-	s.Run("UpdateExternalAuthLinkRefreshToken", s.Subtest(func(db database.Store, check *expects) {
-		link := dbgen.ExternalAuthLink(s.T(), db, database.ExternalAuthLink{})
-		check.Args(database.UpdateExternalAuthLinkRefreshTokenParams{
-			OAuthRefreshToken:      "",
-			OAuthRefreshTokenKeyID: "",
-			ProviderID:             link.ProviderID,
-			UserID:                 link.UserID,
-			UpdatedAt:              link.UpdatedAt,
-		}).Asserts(rbac.ResourceUserObject(link.UserID), policy.ActionUpdatePersonal)
+	s.Run("GetTemplateVersionRolloutBuildCounts", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.GetTemplateVersionRolloutBuildCountsParams{
+			TemplateID:   tpl.ID,
+			CreatedAfter: dbtime.Now().Add(-time.Hour),
+		}).Asserts(tpl, policy.ActionRead)
 	}))
-	s.Run("UpdateExternalAuthLink", s.Subtest(func(db database.Store, check *expects) {
-		link := dbgen.ExternalAuthLink(s.T(), db, database.ExternalAuthLink{})
-		check.Args(database.UpdateExternalAuthLinkParams{
-			ProviderID:        link.ProviderID,
-			UserID:            link.UserID,
-			OAuthAccessToken:  link.OAuthAccessToken,
-			OAuthRefreshToken: link.OAuthRefreshToken,
-			OAuthExpiry:       link.OAuthExpiry,
-			UpdatedAt:         link.UpdatedAt,
-		}).Asserts(rbac.ResourceUserObject(link.UserID), policy.ActionUpdatePersonal).Returns(link)
+	s.Run("GetTemplateReleaseChannelsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead).Returns([]database.TemplateReleaseChannel{})
 	}))
-	s.Run("UpdateUserLink", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		link := dbgen.UserLink(s.T(), db, database.UserLink{})
-		check.Args(database.UpdateUserLinkParams{
-			OAuthAccessToken:  link.OAuthAccessToken,
-			OAuthRefreshToken: link.OAuthRefreshToken,
-			OAuthExpiry:       link.OAuthExpiry,
-			UserID:            link.UserID,
-			LoginType:         link.LoginType,
-			Claims:            database.UserLinkClaims{},
-		}).Asserts(rbac.ResourceUserObject(link.UserID), policy.ActionUpdatePersonal).Returns(link)
+	s.Run("GetTemplateReleaseChannel", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		channel, err := db.UpsertTemplateReleaseChannel(context.Background(), database.UpsertTemplateReleaseChannelParams{
+			TemplateID:        tpl.ID,
+			Channel:           database.ReleaseChannelBeta,
+			TemplateVersionID: tv.ID,
+			UpdatedBy:         u.ID,
+			UpdatedAt:         dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetTemplateReleaseChannelParams{
+			TemplateID: tpl.ID,
+			Channel:    database.ReleaseChannelBeta,
+		}).Asserts(tpl, policy.ActionRead).Returns(channel)
 	}))
-	s.Run("UpdateUserRoles", s.Subtest(func(db database.Store, check *expects) {
-		u := dbgen.User(s.T(), db, database.User{RBACRoles: []string{codersdk.RoleTemplateAdmin}})
-		o := u
-		o.RBACRoles = []string{codersdk.RoleUserAdmin}
-		check.Args(database.UpdateUserRolesParams{
-			GrantedRoles: []string{codersdk.RoleUserAdmin},
-			ID:           u.ID,
-		}).Asserts(
-			u, policy.ActionRead,
-			rbac.ResourceAssignRole, policy.ActionAssign,
-			rbac.ResourceAssignRole, policy.ActionUnassign,
-		).Returns(o)
+	s.Run("UpsertTemplateReleaseChannel", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.UpsertTemplateReleaseChannelParams{
+			TemplateID:        tpl.ID,
+			Channel:           database.ReleaseChannelStable,
+			TemplateVersionID: tv.ID,
+			UpdatedBy:         u.ID,
+			UpdatedAt:         dbtime.Now(),
+		}).Asserts(tpl, policy.ActionUpdate)
 	}))
-	s.Run("AllUserIDs", s.Subtest(func(db database.Store, check *expects) {
-		a := dbgen.User(s.T(), db, database.User{})
-		b := dbgen.User(s.T(), db, database.User{})
-		check.Args(false).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(slice.New(a.ID, b.ID))
+	s.Run("DeleteTemplateReleaseChannel", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.DeleteTemplateReleaseChannelParams{
+			TemplateID: tpl.ID,
+			Channel:    database.ReleaseChannelStable,
+		}).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
-	s.Run("CustomRoles", s.Subtest(func(db database.Store, check *expects) {
-		check.Args(database.CustomRolesParams{}).Asserts(rbac.ResourceAssignRole, policy.ActionRead).Returns([]database.CustomRole{})
+	s.Run("UpsertTemplateShare", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		other := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.UpsertTemplateShareParams{
+			TemplateID:     tpl.ID,
+			OrganizationID: other.ID,
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(tpl, policy.ActionUpdate, other, policy.ActionRead)
 	}))
-	s.Run("Organization/DeleteCustomRole", s.Subtest(func(db database.Store, check *expects) {
-		customRole := dbgen.CustomRole(s.T(), db, database.CustomRole{
-			OrganizationID: uuid.NullUUID{
-				UUID:  uuid.New(),
-				Valid: true,
-			},
+	s.Run("DeleteTemplateShare", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		other := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
 		})
-		check.Args(database.DeleteCustomRoleParams{
-			Name:           customRole.Name,
-			OrganizationID: customRole.OrganizationID,
-		}).Asserts(
-			rbac.ResourceAssignOrgRole.InOrg(customRole.OrganizationID.UUID), policy.ActionDelete)
+		check.Args(database.DeleteTemplateShareParams{
+			TemplateID:     tpl.ID,
+			OrganizationID: other.ID,
+		}).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
-	s.Run("Site/DeleteCustomRole", s.Subtest(func(db database.Store, check *expects) {
-		customRole := dbgen.CustomRole(s.T(), db, database.CustomRole{
-			OrganizationID: uuid.NullUUID{
-				UUID:  uuid.Nil,
-				Valid: false,
-			},
+	s.Run("GetTemplateSharesByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		other := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
 		})
-		check.Args(database.DeleteCustomRoleParams{
-			Name: customRole.Name,
-		}).Asserts(
-		// fails immediately, missing organization id
-		).Errors(dbauthz.NotAuthorizedError{Err: xerrors.New("custom roles must belong to an organization")})
+		share, err := db.UpsertTemplateShare(context.Background(), database.UpsertTemplateShareParams{
+			TemplateID:     tpl.ID,
+			OrganizationID: other.ID,
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		tpl, err = db.GetTemplateByID(context.Background(), tpl.ID)
+		require.NoError(s.T(), err)
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate).Returns([]database.TemplateShare{share})
 	}))
-	s.Run("Blank/UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		customRole := dbgen.CustomRole(s.T(), db, database.CustomRole{
-			OrganizationID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+	s.Run("GetTemplatesSharedWithOrganization", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		other := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
 		})
-		// Blank is no perms in the role
-		check.Args(database.UpdateCustomRoleParams{
-			Name:            customRole.Name,
-			DisplayName:     "Test Name",
-			OrganizationID:  customRole.OrganizationID,
-			SitePermissions: nil,
-			OrgPermissions:  nil,
-			UserPermissions: nil,
-		}).Asserts(rbac.ResourceAssignOrgRole.InOrg(customRole.OrganizationID.UUID), policy.ActionUpdate)
+		_, err := db.UpsertTemplateShare(context.Background(), database.UpsertTemplateShareParams{
+			TemplateID:     tpl.ID,
+			OrganizationID: other.ID,
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		tpl, err = db.GetTemplateByID(context.Background(), tpl.ID)
+		require.NoError(s.T(), err)
+		check.Args(other.ID).Asserts(tpl, policy.ActionRead).Returns([]database.Template{tpl})
 	}))
-	s.Run("SitePermissions/UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
-		check.Args(database.UpdateCustomRoleParams{
-			Name:           "",
-			OrganizationID: uuid.NullUUID{UUID: uuid.Nil, Valid: false},
-			DisplayName:    "Test Name",
-			SitePermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
-				codersdk.ResourceTemplate: {codersdk.ActionCreate, codersdk.ActionRead, codersdk.ActionUpdate, codersdk.ActionDelete, codersdk.ActionViewInsights},
-			}), convertSDKPerm),
-			OrgPermissions: nil,
-			UserPermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
-				codersdk.ResourceWorkspace: {codersdk.ActionRead},
-			}), convertSDKPerm),
-		}).Asserts(
-		// fails immediately, missing organization id
-		).Errors(dbauthz.NotAuthorizedError{Err: xerrors.New("custom roles must belong to an organization")})
-	}))
-	s.Run("OrgPermissions/UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
-		orgID := uuid.New()
-		customRole := dbgen.CustomRole(s.T(), db, database.CustomRole{
-			OrganizationID: uuid.NullUUID{
-				UUID:  orgID,
-				Valid: true,
-			},
-		})
+}
 
-		check.Args(database.UpdateCustomRoleParams{
-			Name:            customRole.Name,
-			DisplayName:     "Test Name",
-			OrganizationID:  customRole.OrganizationID,
-			SitePermissions: nil,
-			OrgPermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
-				codersdk.ResourceTemplate: {codersdk.ActionCreate, codersdk.ActionRead},
-			}), convertSDKPerm),
-			UserPermissions: nil,
-		}).Asserts(
-			// First check
-			rbac.ResourceAssignOrgRole.InOrg(orgID), policy.ActionUpdate,
-			// Escalation checks
-			rbac.ResourceTemplate.InOrg(orgID), policy.ActionCreate,
-			rbac.ResourceTemplate.InOrg(orgID), policy.ActionRead,
-		)
+func (s *MethodTestSuite) TestUser() {
+	s.Run("GetAuthorizedUsers", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		dbgen.User(s.T(), db, database.User{})
+		// No asserts because SQLFilter.
+		check.Args(database.GetUsersParams{}, emptyPreparedAuthorized{}).
+			Asserts()
 	}))
-	s.Run("Blank/InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
-		// Blank is no perms in the role
-		orgID := uuid.New()
-		check.Args(database.InsertCustomRoleParams{
-			Name:            "test",
-			DisplayName:     "Test Name",
-			OrganizationID:  uuid.NullUUID{UUID: orgID, Valid: true},
-			SitePermissions: nil,
-			OrgPermissions:  nil,
-			UserPermissions: nil,
-		}).Asserts(rbac.ResourceAssignOrgRole.InOrg(orgID), policy.ActionCreate)
+	s.Run("DeleteAPIKeysByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceApiKey.WithOwner(u.ID.String()), policy.ActionDelete).Returns()
 	}))
-	s.Run("SitePermissions/InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
-		check.Args(database.InsertCustomRoleParams{
-			Name:        "test",
-			DisplayName: "Test Name",
-			SitePermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
-				codersdk.ResourceTemplate: {codersdk.ActionCreate, codersdk.ActionRead, codersdk.ActionUpdate, codersdk.ActionDelete, codersdk.ActionViewInsights},
-			}), convertSDKPerm),
-			OrgPermissions: nil,
-			UserPermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
-				codersdk.ResourceWorkspace: {codersdk.ActionRead},
-			}), convertSDKPerm),
-		}).Asserts(
-		// fails immediately, missing organization id
-		).Errors(dbauthz.NotAuthorizedError{Err: xerrors.New("custom roles must belong to an organization")})
+	s.Run("GetQuotaAllowanceForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetQuotaAllowanceForUserParams{
+			UserID:         u.ID,
+			OrganizationID: uuid.New(),
+		}).Asserts(u, policy.ActionRead).Returns(int64(0))
 	}))
-	s.Run("OrgPermissions/InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
-		orgID := uuid.New()
-		check.Args(database.InsertCustomRoleParams{
-			Name:        "test",
-			DisplayName: "Test Name",
-			OrganizationID: uuid.NullUUID{
-				UUID:  orgID,
-				Valid: true,
+	s.Run("GetQuotaConsumedForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetQuotaConsumedForUserParams{
+			OwnerID:        u.ID,
+			OrganizationID: uuid.New(),
+		}).Asserts(u, policy.ActionRead).Returns(int64(0))
+	}))
+	s.Run("GetQuotaMonthlyBudgetForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetQuotaMonthlyBudgetForUserParams{
+			UserID:         u.ID,
+			OrganizationID: uuid.New(),
+		}).Asserts(u, policy.ActionRead).Returns(int64(0))
+	}))
+	s.Run("GetQuotaMonthlySpendForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetQuotaMonthlySpendForUserParams{
+			OwnerID:        u.ID,
+			OrganizationID: uuid.New(),
+			StartTime:      dbtime.Now(),
+		}).Asserts(u, policy.ActionRead).Returns(float64(0))
+	}))
+	s.Run("GetUserByEmailOrUsername", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetUserByEmailOrUsernameParams{
+			Username: u.Username,
+			Email:    u.Email,
+		}).Asserts(u, policy.ActionRead).Returns(u)
+	}))
+	s.Run("GetUserByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u, policy.ActionRead).Returns(u)
+	}))
+	s.Run("GetUsersByIDs", s.Subtest(func(db database.Store, check *expects) {
+		a := dbgen.User(s.T(), db, database.User{CreatedAt: dbtime.Now().Add(-time.Hour)})
+		b := dbgen.User(s.T(), db, database.User{CreatedAt: dbtime.Now()})
+		check.Args([]uuid.UUID{a.ID, b.ID}).
+			Asserts(a, policy.ActionRead, b, policy.ActionRead).
+			Returns(slice.New(a, b))
+	}))
+	s.Run("GetUsers", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		dbgen.User(s.T(), db, database.User{Username: "GetUsers-a-user"})
+		dbgen.User(s.T(), db, database.User{Username: "GetUsers-b-user"})
+		check.Args(database.GetUsersParams{}).
+			// Asserts are done in a SQL filter
+			Asserts()
+	}))
+	s.Run("InsertUser", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertUserParams{
+			ID:        uuid.New(),
+			LoginType: database.LoginTypePassword,
+			RBACRoles: []string{},
+		}).Asserts(rbac.ResourceAssignRole, policy.ActionAssign, rbac.ResourceUser, policy.ActionCreate)
+	}))
+	s.Run("InsertUserLink", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserLinkParams{
+			UserID:    u.ID,
+			LoginType: database.LoginTypeOIDC,
+		}).Asserts(u, policy.ActionUpdate)
+	}))
+	s.Run("UpdateUserDeletedByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u, policy.ActionDelete).Returns()
+	}))
+	s.Run("UpdateUserGithubComUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserGithubComUserIDParams{
+			ID: u.ID,
+		}).Asserts(u, policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateUserHashedPassword", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserHashedPasswordParams{
+			ID: u.ID,
+		}).Asserts(u, policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("UpdateUserHashedOneTimePasscode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserHashedOneTimePasscodeParams{
+			ID:                       u.ID,
+			HashedOneTimePasscode:    []byte{},
+			OneTimePasscodeExpiresAt: sql.NullTime{Time: u.CreatedAt, Valid: true},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateUserQuietHoursSchedule", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserQuietHoursScheduleParams{
+			ID: u.ID,
+		}).Asserts(u, policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateUserLastSeenAt", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserLastSeenAtParams{
+			ID:         u.ID,
+			UpdatedAt:  u.UpdatedAt,
+			LastSeenAt: u.LastSeenAt,
+		}).Asserts(u, policy.ActionUpdate).Returns(u)
+	}))
+	s.Run("UpdateUserProfile", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserProfileParams{
+			ID:        u.ID,
+			Email:     u.Email,
+			Username:  u.Username,
+			Name:      u.Name,
+			UpdatedAt: u.UpdatedAt,
+		}).Asserts(u, policy.ActionUpdatePersonal).Returns(u)
+	}))
+	s.Run("GetUserWorkspaceBuildParameters", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(
+			database.GetUserWorkspaceBuildParametersParams{
+				OwnerID:    u.ID,
+				TemplateID: uuid.UUID{},
 			},
-			SitePermissions: nil,
-			OrgPermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
-				codersdk.ResourceTemplate: {codersdk.ActionCreate, codersdk.ActionRead},
-			}), convertSDKPerm),
-			UserPermissions: nil,
-		}).Asserts(
-			// First check
-			rbac.ResourceAssignOrgRole.InOrg(orgID), policy.ActionCreate,
-			// Escalation checks
-			rbac.ResourceTemplate.InOrg(orgID), policy.ActionCreate,
-			rbac.ResourceTemplate.InOrg(orgID), policy.ActionRead,
+		).Asserts(u, policy.ActionReadPersonal).Returns(
+			[]database.GetUserWorkspaceBuildParametersRow{},
 		)
 	}))
-	s.Run("GetUserStatusCounts", s.Subtest(func(db database.Store, check *expects) {
-		check.Args(database.GetUserStatusCountsParams{
-			StartTime: time.Now().Add(-time.Hour * 24 * 30),
-			EndTime:   time.Now(),
-			Interval:  int32((time.Hour * 24).Seconds()),
-		}).Asserts(rbac.ResourceUser, policy.ActionRead)
+	s.Run("GetUserThemePreference", s.Subtest(func(db database.Store, check *expects) {
+		ctx := context.Background()
+		u := dbgen.User(s.T(), db, database.User{})
+		db.UpdateUserThemePreference(ctx, database.UpdateUserThemePreferenceParams{
+			UserID:          u.ID,
+			ThemePreference: "light",
+		})
+		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns("light")
+	}))
+	s.Run("UpdateUserThemePreference", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		uc := database.UserConfig{
+			UserID: u.ID,
+			Key:    "theme_preference",
+			Value:  "dark",
+		}
+		check.Args(database.UpdateUserThemePreferenceParams{
+			UserID:          u.ID,
+			ThemePreference: uc.Value,
+		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
+	}))
+	s.Run("GetUserTerminalFont", s.Subtest(func(db database.Store, check *expects) {
+		ctx := context.Background()
+		u := dbgen.User(s.T(), db, database.User{})
+		db.UpdateUserTerminalFont(ctx, database.UpdateUserTerminalFontParams{
+			UserID:       u.ID,
+			TerminalFont: "ibm-plex-mono",
+		})
+		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns("ibm-plex-mono")
+	}))
+	s.Run("UpdateUserTerminalFont", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		uc := database.UserConfig{
+			UserID: u.ID,
+			Key:    "terminal_font",
+			Value:  "ibm-plex-mono",
+		}
+		check.Args(database.UpdateUserTerminalFontParams{
+			UserID:       u.ID,
+			TerminalFont: uc.Value,
+		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
+	}))
+	s.Run("UpdateUserStatus", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserStatusParams{
+			ID:        u.ID,
+			Status:    u.Status,
+			UpdatedAt: u.UpdatedAt,
+		}).Asserts(u, policy.ActionUpdate).Returns(u)
+	}))
+	s.Run("DeleteGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
+		check.Args(key.UserID).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("GetGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
+		check.Args(key.UserID).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionReadPersonal).Returns(key)
+	}))
+	s.Run("InsertGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertGitSSHKeyParams{
+			UserID: u.ID,
+		}).Asserts(u, policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
+		check.Args(database.UpdateGitSSHKeyParams{
+			UserID:    key.UserID,
+			UpdatedAt: key.UpdatedAt,
+		}).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionUpdatePersonal).Returns(key)
+	}))
+	s.Run("GetExternalAuthLink", s.Subtest(func(db database.Store, check *expects) {
+		link := dbgen.ExternalAuthLink(s.T(), db, database.ExternalAuthLink{})
+		check.Args(database.GetExternalAuthLinkParams{
+			ProviderID: link.ProviderID,
+			UserID:     link.UserID,
+		}).Asserts(rbac.ResourceUserObject(link.UserID), policy.ActionReadPersonal).Returns(link)
+	}))
+	s.Run("InsertExternalAuthLink", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertExternalAuthLinkParams{
+			ProviderID: uuid.NewString(),
+			UserID:     u.ID,
+		}).Asserts(u, policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateExternalAuthLinkRefreshToken", s.Subtest(func(db database.Store, check *expects) {
+		link := dbgen.ExternalAuthLink(s.T(), db, database.ExternalAuthLink{})
+		check.Args(database.UpdateExternalAuthLinkRefreshTokenParams{
+			OAuthRefreshToken:      "",
+			OAuthRefreshTokenKeyID: "",
+			ProviderID:             link.ProviderID,
+			UserID:                 link.UserID,
+			UpdatedAt:              link.UpdatedAt,
+		}).Asserts(rbac.ResourceUserObject(link.UserID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateExternalAuthLink", s.Subtest(func(db database.Store, check *expects) {
+		link := dbgen.ExternalAuthLink(s.T(), db, database.ExternalAuthLink{})
+		check.Args(database.UpdateExternalAuthLinkParams{
+			ProviderID:        link.ProviderID,
+			UserID:            link.UserID,
+			OAuthAccessToken:  link.OAuthAccessToken,
+			OAuthRefreshToken: link.OAuthRefreshToken,
+			OAuthExpiry:       link.OAuthExpiry,
+			UpdatedAt:         link.UpdatedAt,
+		}).Asserts(rbac.ResourceUserObject(link.UserID), policy.ActionUpdatePersonal).Returns(link)
+	}))
+	s.Run("UpdateUserLink", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		link := dbgen.UserLink(s.T(), db, database.UserLink{})
+		check.Args(database.UpdateUserLinkParams{
+			OAuthAccessToken:  link.OAuthAccessToken,
+			OAuthRefreshToken: link.OAuthRefreshToken,
+			OAuthExpiry:       link.OAuthExpiry,
+			UserID:            link.UserID,
+			LoginType:         link.LoginType,
+			Claims:            database.UserLinkClaims{},
+		}).Asserts(rbac.ResourceUserObject(link.UserID), policy.ActionUpdatePersonal).Returns(link)
+	}))
+	s.Run("UpdateUserRoles", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{RBACRoles: []string{codersdk.RoleTemplateAdmin}})
+		o := u
+		o.RBACRoles = []string{codersdk.RoleUserAdmin}
+		check.Args(database.UpdateUserRolesParams{
+			GrantedRoles: []string{codersdk.RoleUserAdmin},
+			ID:           u.ID,
+		}).Asserts(
+			u, policy.ActionRead,
+			rbac.ResourceAssignRole, policy.ActionAssign,
+			rbac.ResourceAssignRole, policy.ActionUnassign,
+		).Returns(o)
+	}))
+	s.Run("AllUserIDs", s.Subtest(func(db database.Store, check *expects) {
+		a := dbgen.User(s.T(), db, database.User{})
+		b := dbgen.User(s.T(), db, database.User{})
+		check.Args(false).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(slice.New(a.ID, b.ID))
+	}))
+	s.Run("CustomRoles", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.CustomRolesParams{}).Asserts(rbac.ResourceAssignRole, policy.ActionRead).Returns([]database.CustomRole{})
+	}))
+	s.Run("Organization/DeleteCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		customRole := dbgen.CustomRole(s.T(), db, database.CustomRole{
+			OrganizationID: uuid.NullUUID{
+				UUID:  uuid.New(),
+				Valid: true,
+			},
+		})
+		check.Args(database.DeleteCustomRoleParams{
+			Name:           customRole.Name,
+			OrganizationID: customRole.OrganizationID,
+		}).Asserts(
+			rbac.ResourceAssignOrgRole.InOrg(customRole.OrganizationID.UUID), policy.ActionDelete)
+	}))
+	s.Run("Site/DeleteCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		customRole := dbgen.CustomRole(s.T(), db, database.CustomRole{
+			OrganizationID: uuid.NullUUID{
+				UUID:  uuid.Nil,
+				Valid: false,
+			},
+		})
+		check.Args(database.DeleteCustomRoleParams{
+			Name: customRole.Name,
+		}).Asserts(
+		// fails immediately, missing organization id
+		).Errors(dbauthz.NotAuthorizedError{Err: xerrors.New("custom roles must belong to an organization")})
+	}))
+	s.Run("Blank/UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		customRole := dbgen.CustomRole(s.T(), db, database.CustomRole{
+			OrganizationID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		})
+		// Blank is no perms in the role
+		check.Args(database.UpdateCustomRoleParams{
+			Name:            customRole.Name,
+			DisplayName:     "Test Name",
+			OrganizationID:  customRole.OrganizationID,
+			SitePermissions: nil,
+			OrgPermissions:  nil,
+			UserPermissions: nil,
+		}).Asserts(rbac.ResourceAssignOrgRole.InOrg(customRole.OrganizationID.UUID), policy.ActionUpdate)
+	}))
+	s.Run("SitePermissions/UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateCustomRoleParams{
+			Name:           "",
+			OrganizationID: uuid.NullUUID{UUID: uuid.Nil, Valid: false},
+			DisplayName:    "Test Name",
+			SitePermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
+				codersdk.ResourceTemplate: {codersdk.ActionCreate, codersdk.ActionRead, codersdk.ActionUpdate, codersdk.ActionDelete, codersdk.ActionViewInsights},
+			}), convertSDKPerm),
+			OrgPermissions: nil,
+			UserPermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
+				codersdk.ResourceWorkspace: {codersdk.ActionRead},
+			}), convertSDKPerm),
+		}).Asserts(
+		// fails immediately, missing organization id
+		).Errors(dbauthz.NotAuthorizedError{Err: xerrors.New("custom roles must belong to an organization")})
+	}))
+	s.Run("OrgPermissions/UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		orgID := uuid.New()
+		customRole := dbgen.CustomRole(s.T(), db, database.CustomRole{
+			OrganizationID: uuid.NullUUID{
+				UUID:  orgID,
+				Valid: true,
+			},
+		})
+
+		check.Args(database.UpdateCustomRoleParams{
+			Name:            customRole.Name,
+			DisplayName:     "Test Name",
+			OrganizationID:  customRole.OrganizationID,
+			SitePermissions: nil,
+			OrgPermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
+				codersdk.ResourceTemplate: {codersdk.ActionCreate, codersdk.ActionRead},
+			}), convertSDKPerm),
+			UserPermissions: nil,
+		}).Asserts(
+			// First check
+			rbac.ResourceAssignOrgRole.InOrg(orgID), policy.ActionUpdate,
+			// Escalation checks
+			rbac.ResourceTemplate.InOrg(orgID), policy.ActionCreate,
+			rbac.ResourceTemplate.InOrg(orgID), policy.ActionRead,
+		)
+	}))
+	s.Run("Blank/InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		// Blank is no perms in the role
+		orgID := uuid.New()
+		check.Args(database.InsertCustomRoleParams{
+			Name:            "test",
+			DisplayName:     "Test Name",
+			OrganizationID:  uuid.NullUUID{UUID: orgID, Valid: true},
+			SitePermissions: nil,
+			OrgPermissions:  nil,
+			UserPermissions: nil,
+		}).Asserts(rbac.ResourceAssignOrgRole.InOrg(orgID), policy.ActionCreate)
+	}))
+	s.Run("SitePermissions/InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertCustomRoleParams{
+			Name:        "test",
+			DisplayName: "Test Name",
+			SitePermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
+				codersdk.ResourceTemplate: {codersdk.ActionCreate, codersdk.ActionRead, codersdk.ActionUpdate, codersdk.ActionDelete, codersdk.ActionViewInsights},
+			}), convertSDKPerm),
+			OrgPermissions: nil,
+			UserPermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
+				codersdk.ResourceWorkspace: {codersdk.ActionRead},
+			}), convertSDKPerm),
+		}).Asserts(
+		// fails immediately, missing organization id
+		).Errors(dbauthz.NotAuthorizedError{Err: xerrors.New("custom roles must belong to an organization")})
+	}))
+	s.Run("OrgPermissions/InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		orgID := uuid.New()
+		check.Args(database.InsertCustomRoleParams{
+			Name:        "test",
+			DisplayName: "Test Name",
+			OrganizationID: uuid.NullUUID{
+				UUID:  orgID,
+				Valid: true,
+			},
+			SitePermissions: nil,
+			OrgPermissions: db2sdk.List(codersdk.CreatePermissions(map[codersdk.RBACResource][]codersdk.RBACAction{
+				codersdk.ResourceTemplate: {codersdk.ActionCreate, codersdk.ActionRead},
+			}), convertSDKPerm),
+			UserPermissions: nil,
+		}).Asserts(
+			// First check
+			rbac.ResourceAssignOrgRole.InOrg(orgID), policy.ActionCreate,
+			// Escalation checks
+			rbac.ResourceTemplate.InOrg(orgID), policy.ActionCreate,
+			rbac.ResourceTemplate.InOrg(orgID), policy.ActionRead,
+		)
+	}))
+	s.Run("GetUserStatusCounts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetUserStatusCountsParams{
+			StartTime: time.Now().Add(-time.Hour * 24 * 30),
+			EndTime:   time.Now(),
+			Interval:  int32((time.Hour * 24).Seconds()),
+		}).Asserts(rbac.ResourceUser, policy.ActionRead)
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspace() {
+	s.Run("GetWorkspaceByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: o.ID,
+			TemplateID:     tpl.ID,
+		})
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceByIDForUpdate", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: o.ID,
+			TemplateID:     tpl.ID,
+		})
+		check.Args(ws.ID).Asserts(ws, policy.ActionUpdate).Returns(ws)
+	}))
+	s.Run("GetWorkspaceByResourceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{Type: database.ProvisionerJobTypeWorkspaceBuild})
+		tpl := dbgen.Template(s.T(), db, database.Template{CreatedBy: u.ID, OrganizationID: o.ID})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			JobID:          j.ID,
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{OwnerID: u.ID, TemplateID: tpl.ID, OrganizationID: o.ID})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: j.ID, TemplateVersionID: tv.ID})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: j.ID})
+		check.Args(res.ID).Asserts(ws, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaces", s.Subtest(func(_ database.Store, check *expects) {
+		// No asserts here because SQLFilter.
+		check.Args(database.GetWorkspacesParams{}).Asserts()
+	}))
+	s.Run("GetAuthorizedWorkspaces", s.Subtest(func(_ database.Store, check *expects) {
+		// No asserts here because SQLFilter.
+		check.Args(database.GetWorkspacesParams{}, emptyPreparedAuthorized{}).Asserts()
+	}))
+	s.Run("GetWorkspacesAndAgentsByOwnerID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{ID: build.JobID, Type: database.ProvisionerJobTypeWorkspaceBuild})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		_ = dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		// No asserts here because SQLFilter.
+		check.Args(ws.OwnerID).Asserts()
+	}))
+	s.Run("GetAuthorizedWorkspacesAndAgentsByOwnerID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{ID: build.JobID, Type: database.ProvisionerJobTypeWorkspaceBuild})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		_ = dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		// No asserts here because SQLFilter.
+		check.Args(ws.OwnerID, emptyPreparedAuthorized{}).Asserts()
+	}))
+	s.Run("GetWorkspaceBuildParametersByBuildIDs", s.Subtest(func(db database.Store, check *expects) {
+		// no asserts here because SQLFilter
+		check.Args([]uuid.UUID{}).Asserts()
+	}))
+	s.Run("GetAuthorizedWorkspaceBuildParametersByBuildIDs", s.Subtest(func(db database.Store, check *expects) {
+		// no asserts here because SQLFilter
+		check.Args([]uuid.UUID{}, emptyPreparedAuthorized{}).Asserts()
+	}))
+	s.Run("GetLatestWorkspaceBuildByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(b)
+	}))
+	s.Run("GetLatestSuccessfulWorkspaceBuildByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type:        database.ProvisionerJobTypeWorkspaceBuild,
+			StartedAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+			Transition:        database.WorkspaceTransitionStart,
+		})
+		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(b)
+	}))
+	s.Run("GetWorkspaceAgentByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(agt)
+	}))
+	s.Run("GetWorkspaceAgentsByWorkspaceAndBuildNumber", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.GetWorkspaceAgentsByWorkspaceAndBuildNumberParams{
+			WorkspaceID: w.ID,
+			BuildNumber: 1,
+		}).Asserts(w, policy.ActionRead).Returns([]database.WorkspaceAgent{agt})
+	}))
+	s.Run("GetWorkspaceAgentLifecycleStateByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(agt.ID).Asserts(w, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentMetadata", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		_ = db.InsertWorkspaceAgentMetadata(context.Background(), database.InsertWorkspaceAgentMetadataParams{
+			WorkspaceAgentID: agt.ID,
+			DisplayName:      "test",
+			Key:              "test",
+		})
+		check.Args(database.GetWorkspaceAgentMetadataParams{
+			WorkspaceAgentID: agt.ID,
+			Keys:             []string{"test"},
+		}).Asserts(w, policy.ActionRead)
 	}))
-}
-
-func (s *MethodTestSuite) TestWorkspace() {
-	s.Run("GetWorkspaceByID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetWorkspaceAgentByInstanceID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
 			OrganizationID: o.ID,
 			CreatedBy:      u.ID,
 		})
-		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
-			OwnerID:        u.ID,
-			OrganizationID: o.ID,
-			TemplateID:     tpl.ID,
-		})
-		check.Args(ws.ID).Asserts(ws, policy.ActionRead)
-	}))
-	s.Run("GetWorkspaceByResourceID", s.Subtest(func(db database.Store, check *expects) {
-		u := dbgen.User(s.T(), db, database.User{})
-		o := dbgen.Organization(s.T(), db, database.Organization{})
-		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{Type: database.ProvisionerJobTypeWorkspaceBuild})
-		tpl := dbgen.Template(s.T(), db, database.Template{CreatedBy: u.ID, OrganizationID: o.ID})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
 			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
-			JobID:          j.ID,
 			OrganizationID: o.ID,
 			CreatedBy:      u.ID,
 		})
-		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{OwnerID: u.ID, TemplateID: tpl.ID, OrganizationID: o.ID})
-		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: j.ID, TemplateVersionID: tv.ID})
-		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: j.ID})
-		check.Args(res.ID).Asserts(ws, policy.ActionRead)
-	}))
-	s.Run("GetWorkspaces", s.Subtest(func(_ database.Store, check *expects) {
-		// No asserts here because SQLFilter.
-		check.Args(database.GetWorkspacesParams{}).Asserts()
-	}))
-	s.Run("GetAuthorizedWorkspaces", s.Subtest(func(_ database.Store, check *expects) {
-		// No asserts here because SQLFilter.
-		check.Args(database.GetWorkspacesParams{}, emptyPreparedAuthorized{}).Asserts()
-	}))
-	s.Run("GetWorkspacesAndAgentsByOwnerID", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
-		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
-		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{ID: build.JobID, Type: database.ProvisionerJobTypeWorkspaceBuild})
-		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
-		_ = dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		// No asserts here because SQLFilter.
-		check.Args(ws.OwnerID).Asserts()
-	}))
-	s.Run("GetAuthorizedWorkspacesAndAgentsByOwnerID", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
-		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
-		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{ID: build.JobID, Type: database.ProvisionerJobTypeWorkspaceBuild})
-		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
-		_ = dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		// No asserts here because SQLFilter.
-		check.Args(ws.OwnerID, emptyPreparedAuthorized{}).Asserts()
-	}))
-	s.Run("GetWorkspaceBuildParametersByBuildIDs", s.Subtest(func(db database.Store, check *expects) {
-		// no asserts here because SQLFilter
-		check.Args([]uuid.UUID{}).Asserts()
-	}))
-	s.Run("GetAuthorizedWorkspaceBuildParametersByBuildIDs", s.Subtest(func(db database.Store, check *expects) {
-		// no asserts here because SQLFilter
-		check.Args([]uuid.UUID{}, emptyPreparedAuthorized{}).Asserts()
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(agt.AuthInstanceID.String).Asserts(w, policy.ActionRead).Returns(agt)
 	}))
-	s.Run("GetLatestWorkspaceBuildByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpdateWorkspaceAgentLifecycleStateByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -2045,9 +2763,14 @@ func (s *MethodTestSuite) TestWorkspace() {
 			WorkspaceID:       w.ID,
 			TemplateVersionID: tv.ID,
 		})
-		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(b)
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+			ID:             agt.ID,
+			LifecycleState: database.WorkspaceAgentLifecycleStateCreated,
+		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
-	s.Run("GetWorkspaceAgentByID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpdateWorkspaceAgentMetadata", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -2074,9 +2797,11 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(agt)
+		check.Args(database.UpdateWorkspaceAgentMetadataParams{
+			WorkspaceAgentID: agt.ID,
+		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
-	s.Run("GetWorkspaceAgentsByWorkspaceAndBuildNumber", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpsertWorkspaceAgentResourceUsage", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -2103,12 +2828,12 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(database.GetWorkspaceAgentsByWorkspaceAndBuildNumberParams{
-			WorkspaceID: w.ID,
-			BuildNumber: 1,
-		}).Asserts(w, policy.ActionRead).Returns([]database.WorkspaceAgent{agt})
+		check.Args(database.UpsertWorkspaceAgentResourceUsageParams{
+			AgentID:     agt.ID,
+			BucketStart: dbtime.Now(),
+		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
-	s.Run("GetWorkspaceAgentLifecycleStateByID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("InsertWorkspaceAgentHealthEvent", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -2135,9 +2860,14 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(agt.ID).Asserts(w, policy.ActionRead)
+		check.Args(database.InsertWorkspaceAgentHealthEventParams{
+			ID:        uuid.New(),
+			AgentID:   agt.ID,
+			Kind:      database.WorkspaceAgentHealthEventKindNearOutOfMemory,
+			CreatedAt: dbtime.Now(),
+		}).Asserts(w, policy.ActionUpdate)
 	}))
-	s.Run("GetWorkspaceAgentMetadata", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("ResolveWorkspaceAgentHealthEvent", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -2164,17 +2894,14 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		_ = db.InsertWorkspaceAgentMetadata(context.Background(), database.InsertWorkspaceAgentMetadataParams{
-			WorkspaceAgentID: agt.ID,
-			DisplayName:      "test",
-			Key:              "test",
-		})
-		check.Args(database.GetWorkspaceAgentMetadataParams{
-			WorkspaceAgentID: agt.ID,
-			Keys:             []string{"test"},
-		}).Asserts(w, policy.ActionRead)
-	}))
-	s.Run("GetWorkspaceAgentByInstanceID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.ResolveWorkspaceAgentHealthEventParams{
+			AgentID:    agt.ID,
+			Kind:       database.WorkspaceAgentHealthEventKindLowDiskSpace,
+			Volume:     "/home/coder",
+			ResolvedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(w, policy.ActionUpdate).Returns(int64(0))
+	}))
+	s.Run("InsertWorkspaceSessionRecording", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -2201,9 +2928,52 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(agt.AuthInstanceID.String).Asserts(w, policy.ActionRead).Returns(agt)
+		check.Args(database.InsertWorkspaceSessionRecordingParams{
+			ID:          uuid.New(),
+			WorkspaceID: w.ID,
+			AgentID:     agt.ID,
+			Type:        database.WorkspaceSessionRecordingTypeSsh,
+			StartedAt:   dbtime.Now(),
+		}).Asserts(w, policy.ActionUpdate)
 	}))
-	s.Run("UpdateWorkspaceAgentLifecycleStateByID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpdateWorkspaceSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{WorkspaceID: ws.ID})
+		check.Args(database.UpdateWorkspaceSessionRecordingParams{
+			ID:         recording.ID,
+			AddedBytes: 1,
+		}).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceSessionRecordingChunk", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{WorkspaceID: ws.ID})
+		check.Args(database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			CreatedAt:   dbtime.Now(),
+			Data:        []byte("hello"),
+		}).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{WorkspaceID: ws.ID})
+		check.Args(recording.ID).Asserts(rbac.ResourceAuditLog.InOrg(ws.OrganizationID), policy.ActionRead).Returns(recording)
+	}))
+	s.Run("GetWorkspaceSessionRecordingsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(rbac.ResourceAuditLog.InOrg(ws.OrganizationID), policy.ActionRead).Returns([]database.WorkspaceSessionRecording{recording})
+	}))
+	s.Run("GetWorkspaceSessionRecordingChunks", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{WorkspaceID: ws.ID})
+		check.Args(recording.ID).Asserts(rbac.ResourceAuditLog.InOrg(ws.OrganizationID), policy.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceAgentConnectionQuality", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -2230,12 +3000,13 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(database.UpdateWorkspaceAgentLifecycleStateByIDParams{
-			ID:             agt.ID,
-			LifecycleState: database.WorkspaceAgentLifecycleStateCreated,
+		check.Args(database.UpsertWorkspaceAgentConnectionQualityParams{
+			AgentID:   agt.ID,
+			Ip:        "fd7a:115c:a1e0::1",
+			UpdatedAt: dbtime.Now(),
 		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
-	s.Run("UpdateWorkspaceAgentMetadata", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetWorkspaceAgentConnectionQualityByAgentID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -2262,9 +3033,16 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(database.UpdateWorkspaceAgentMetadataParams{
-			WorkspaceAgentID: agt.ID,
-		}).Asserts(w, policy.ActionUpdate).Returns()
+		check.Args(agt.ID).Asserts(w, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentResourceUsageByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(database.GetWorkspaceAgentResourceUsageByWorkspaceIDParams{
+			WorkspaceID: ws.ID,
+			StartTime:   dbtime.Now().Add(-time.Hour),
+			EndTime:     dbtime.Now(),
+		}).Asserts(ws, policy.ActionRead).Returns([]database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow{})
 	}))
 	s.Run("UpdateWorkspaceAgentLogOverflowByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
@@ -2456,6 +3234,40 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		check.Args(build.ID).Asserts(ws, policy.ActionRead).Returns(build)
 	}))
+	s.Run("GetWorkspaceBuildArchiveByBuildID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       ws.ID,
+			TemplateVersionID: tv.ID,
+		})
+		f := dbgen.File(s.T(), db, database.File{CreatedBy: u.ID})
+		archive, err := db.InsertWorkspaceBuildArchive(context.Background(), database.InsertWorkspaceBuildArchiveParams{
+			WorkspaceBuildID: build.ID,
+			FileID:           f.ID,
+			ArchivedAt:       dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(build.ID).Asserts(ws, policy.ActionRead).Returns(archive)
+	}))
 	s.Run("GetWorkspaceBuildByJobID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2509,38 +3321,287 @@ func (s *MethodTestSuite) TestWorkspace() {
 			TemplateVersionID: tv.ID,
 			BuildNumber:       10,
 		})
-		check.Args(database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+		check.Args(database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+			WorkspaceID: ws.ID,
+			BuildNumber: build.BuildNumber,
+		}).Asserts(ws, policy.ActionRead).Returns(build)
+	}))
+	s.Run("GetWorkspaceBuildParameters", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       ws.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(build.ID).Asserts(ws, policy.ActionRead).
+			Returns([]database.WorkspaceBuildParameter{})
+	}))
+	s.Run("GetWorkspaceBuildIdempotencyKey", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		key := database.WorkspaceBuildIdempotencyKey{
+			WorkspaceBuildID: uuid.NullUUID{UUID: build.ID, Valid: true},
+			UserID:           ws.OwnerID,
+			IdempotencyKey:   "key",
+			CreatedAt:        dbtime.Now(),
+			RequestHash:      []byte("hash"),
+		}
+		err := db.InsertWorkspaceBuildIdempotencyKey(context.Background(), database.InsertWorkspaceBuildIdempotencyKeyParams{
+			UserID:         key.UserID,
+			IdempotencyKey: key.IdempotencyKey,
+			RequestHash:    key.RequestHash,
+			CreatedAt:      key.CreatedAt,
+		})
+		require.NoError(s.T(), err)
+		err = db.UpdateWorkspaceBuildIdempotencyKeyBuildID(context.Background(), database.UpdateWorkspaceBuildIdempotencyKeyBuildIDParams{
+			WorkspaceBuildID: build.ID,
+			UserID:           key.UserID,
+			IdempotencyKey:   key.IdempotencyKey,
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetWorkspaceBuildIdempotencyKeyParams{
+			UserID:         key.UserID,
+			IdempotencyKey: key.IdempotencyKey,
+		}).Asserts(ws, policy.ActionRead).Returns(key)
+	}))
+	s.Run("GetWorkspaceBuildInterimStateByBuildID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		err := db.UpsertWorkspaceBuildInterimState(context.Background(), database.UpsertWorkspaceBuildInterimStateParams{
+			WorkspaceBuildID: build.ID,
+			State:            []byte("testing"),
+			UpdatedAt:        dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(build.ID).Asserts(ws, policy.ActionRead)
+	}))
+	s.Run("InsertWorkspaceBuildQueueEntry", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(database.InsertWorkspaceBuildQueueEntryParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			InitiatorID: ws.OwnerID,
+			Transition:  database.WorkspaceTransitionStop,
+			CreatedAt:   dbtime.Now(),
+		}).Asserts(ws, policy.ActionWorkspaceStop)
+	}))
+	s.Run("GetWorkspaceBuildQueueByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceBuildQueueEntryByID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		entry, err := db.InsertWorkspaceBuildQueueEntry(context.Background(), database.InsertWorkspaceBuildQueueEntryParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			InitiatorID: ws.OwnerID,
+			Transition:  database.WorkspaceTransitionStop,
+			CreatedAt:   dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(entry.ID).Asserts(ws, policy.ActionRead).Returns(entry)
+	}))
+	s.Run("DeleteWorkspaceBuildQueueEntry", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		entry, err := db.InsertWorkspaceBuildQueueEntry(context.Background(), database.InsertWorkspaceBuildQueueEntryParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			InitiatorID: ws.OwnerID,
+			Transition:  database.WorkspaceTransitionStop,
+			CreatedAt:   dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(entry.ID).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceMigration", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(database.InsertWorkspaceMigrationParams{
+			ID:                        uuid.New(),
+			WorkspaceID:               ws.ID,
+			InitiatorID:               ws.OwnerID,
+			DestinationURL:            "https://coder.example.com",
+			DestinationOrganizationID: uuid.New(),
+			DestinationOwner:          codersdk.Me,
+			DestinationSessionToken:   "token",
+			CreatedAt:                 dbtime.Now(),
+			UpdatedAt:                 dbtime.Now(),
+		}).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceMigrationsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceMigrationByID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		migration, err := db.InsertWorkspaceMigration(context.Background(), database.InsertWorkspaceMigrationParams{
+			ID:                        uuid.New(),
+			WorkspaceID:               ws.ID,
+			InitiatorID:               ws.OwnerID,
+			DestinationURL:            "https://coder.example.com",
+			DestinationOrganizationID: uuid.New(),
+			DestinationOwner:          codersdk.Me,
+			DestinationSessionToken:   "token",
+			CreatedAt:                 dbtime.Now(),
+			UpdatedAt:                 dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(migration.ID).Asserts(ws, policy.ActionRead).Returns(migration)
+	}))
+	s.Run("GetWorkspaceLabelsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead)
+	}))
+	s.Run("DeleteWorkspaceLabelsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(ws.ID).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceLabels", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(database.InsertWorkspaceLabelsParams{
 			WorkspaceID: ws.ID,
-			BuildNumber: build.BuildNumber,
-		}).Asserts(ws, policy.ActionRead).Returns(build)
+			Keys:        []string{"team"},
+			Values:      []string{"payments"},
+			CreatedAt:   dbtime.Now(),
+		}).Asserts(ws, policy.ActionUpdate)
 	}))
-	s.Run("GetWorkspaceBuildParameters", s.Subtest(func(db database.Store, check *expects) {
-		u := dbgen.User(s.T(), db, database.User{})
-		o := dbgen.Organization(s.T(), db, database.Organization{})
-		tpl := dbgen.Template(s.T(), db, database.Template{
-			OrganizationID: o.ID,
-			CreatedBy:      u.ID,
-		})
-		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
-			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
-			OrganizationID: o.ID,
-			CreatedBy:      u.ID,
+	s.Run("GetWorkspaceReleaseChannelByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		err := db.UpsertWorkspaceReleaseChannel(context.Background(), database.UpsertWorkspaceReleaseChannelParams{
+			WorkspaceID: ws.ID,
+			Channel:     database.ReleaseChannelBeta,
+			UpdatedAt:   dbtime.Now(),
 		})
-		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
-			TemplateID:     tpl.ID,
-			OrganizationID: o.ID,
-			OwnerID:        u.ID,
+		require.NoError(s.T(), err)
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceReleaseChannel", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(database.UpsertWorkspaceReleaseChannelParams{
+			WorkspaceID: ws.ID,
+			Channel:     database.ReleaseChannelStable,
+			UpdatedAt:   dbtime.Now(),
+		}).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspaceReleaseChannelByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(ws.ID).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("GetActiveWorkspaceLockByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		lock, err := db.InsertWorkspaceLock(context.Background(), database.InsertWorkspaceLockParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			Reason:      "incident",
+			LockedBy:    ws.OwnerID,
+			LockedAt:    dbtime.Now(),
 		})
-		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
-			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		require.NoError(s.T(), err)
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead).Returns(lock)
+	}))
+	s.Run("GetWorkspaceLocksByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead)
+	}))
+	s.Run("InsertWorkspaceLock", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{TemplateID: tpl.ID})
+		check.Args(database.InsertWorkspaceLockParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			Reason:      "incident",
+			LockedBy:    ws.OwnerID,
+			LockedAt:    dbtime.Now(),
+		}).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("UnlockWorkspace", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{TemplateID: tpl.ID})
+		_, err := db.InsertWorkspaceLock(context.Background(), database.InsertWorkspaceLockParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			Reason:      "incident",
+			LockedBy:    ws.OwnerID,
+			LockedAt:    dbtime.Now(),
 		})
-		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
-			JobID:             j.ID,
-			WorkspaceID:       ws.ID,
-			TemplateVersionID: tv.ID,
+		require.NoError(s.T(), err)
+		check.Args(database.UnlockWorkspaceParams{
+			UnlockedBy:  uuid.NullUUID{UUID: ws.OwnerID, Valid: true},
+			UnlockedAt:  sql.NullTime{Time: dbtime.Now(), Valid: true},
+			WorkspaceID: ws.ID,
+		}).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspacePrebuildReservationByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{OwnerID: database.PrebuildsSystemUserID})
+		reservation, err := db.UpsertWorkspacePrebuildReservation(context.Background(), database.UpsertWorkspacePrebuildReservationParams{
+			WorkspaceID: ws.ID,
+			UserID:      uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			ReservedBy:  uuid.New(),
+			CreatedAt:   dbtime.Now(),
+			ExpiresAt:   dbtime.Now().Add(time.Hour),
 		})
-		check.Args(build.ID).Asserts(ws, policy.ActionRead).
-			Returns([]database.WorkspaceBuildParameter{})
+		require.NoError(s.T(), err)
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead).Returns(reservation)
+	}))
+	s.Run("GetWorkspacePrebuildReservationsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("UpsertWorkspacePrebuildReservation", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{TemplateID: tpl.ID, OwnerID: database.PrebuildsSystemUserID})
+		check.Args(database.UpsertWorkspacePrebuildReservationParams{
+			WorkspaceID: ws.ID,
+			GroupID:     uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			ReservedBy:  uuid.New(),
+			CreatedAt:   dbtime.Now(),
+			ExpiresAt:   dbtime.Now().Add(time.Hour),
+		}).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspacePrebuildReservationByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{TemplateID: tpl.ID, OwnerID: database.PrebuildsSystemUserID})
+		check.Args(ws.ID).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
 	s.Run("GetWorkspaceBuildsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
@@ -2664,6 +3725,23 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Name:    ws.Name,
 		}).Asserts(ws, policy.ActionRead)
 	}))
+	s.Run("GetWorkspaceByOrganizationIDAndName", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.GetWorkspaceByOrganizationIDAndNameParams{
+			OrganizationID: ws.OrganizationID,
+			Name:           ws.Name,
+		}).Asserts(ws, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceResourceByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2945,6 +4023,25 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Value:            []string{"baz", "qux"},
 		}).Asserts(w, policy.ActionUpdate)
 	}))
+	s.Run("InsertWorkspaceBuildIdempotencyKey", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertWorkspaceBuildIdempotencyKeyParams{
+			UserID:         u.ID,
+			IdempotencyKey: "key",
+			RequestHash:    []byte("hash"),
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(rbac.ResourceUser.WithID(u.ID).WithOwner(u.ID.String()), policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateWorkspaceBuildIdempotencyKeyBuildID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: w.ID, JobID: uuid.New()})
+		check.Args(database.UpdateWorkspaceBuildIdempotencyKeyBuildIDParams{
+			WorkspaceBuildID: b.ID,
+			UserID:           w.OwnerID,
+			IdempotencyKey:   "key",
+		}).Asserts(w, policy.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspace", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2996,6 +4093,24 @@ func (s *MethodTestSuite) TestWorkspace() {
 			AutomaticUpdates: database.AutomaticUpdatesAlways,
 		}).Asserts(w, policy.ActionUpdate)
 	}))
+	s.Run("UpdateWorkspaceACLByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.UpdateWorkspaceACLByIDParams{
+			ID:       w.ID,
+			GroupACL: database.WorkspaceACL{},
+			UserACL:  database.WorkspaceACL{},
+		}).Asserts(w, policy.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspaceAppHealthByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -3447,6 +4562,65 @@ func (s *MethodTestSuite) TestWorkspacePortSharing() {
 	}))
 }
 
+func (s *MethodTestSuite) TestWorkspaceNamingPolicies() {
+	s.Run("GetOrganizationWorkspaceNamingPolicy", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		_, err := db.UpsertOrganizationWorkspaceNamingPolicy(context.Background(), database.UpsertOrganizationWorkspaceNamingPolicyParams{
+			ID:              uuid.New(),
+			OrganizationID:  org.ID,
+			Prefix:          "{{.Username}}-",
+			UniquenessScope: database.WorkspaceNameUniquenessScopeOwner,
+			UpdatedAt:       dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(org.ID).Asserts(org, policy.ActionRead)
+	}))
+	s.Run("GetTemplateWorkspaceNamingPolicy", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		_, err := db.UpsertTemplateWorkspaceNamingPolicy(context.Background(), database.UpsertTemplateWorkspaceNamingPolicyParams{
+			ID:              uuid.New(),
+			OrganizationID:  org.ID,
+			TemplateID:      tpl.ID,
+			Regex:           "[a-z]+",
+			UniquenessScope: database.WorkspaceNameUniquenessScopeOwner,
+			UpdatedAt:       dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead)
+	}))
+	s.Run("UpsertOrganizationWorkspaceNamingPolicy", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpsertOrganizationWorkspaceNamingPolicyParams{
+			ID:              uuid.New(),
+			OrganizationID:  org.ID,
+			Suffix:          "-dev",
+			UniquenessScope: database.WorkspaceNameUniquenessScopeOrganization,
+			UpdatedAt:       dbtime.Now(),
+		}).Asserts(org, policy.ActionUpdate)
+	}))
+	s.Run("UpsertTemplateWorkspaceNamingPolicy", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.UpsertTemplateWorkspaceNamingPolicyParams{
+			ID:              uuid.New(),
+			OrganizationID:  org.ID,
+			TemplateID:      tpl.ID,
+			Prefix:          "{{.TemplateName}}-",
+			UniquenessScope: database.WorkspaceNameUniquenessScopeOwner,
+			UpdatedAt:       dbtime.Now(),
+		}).Asserts(tpl, policy.ActionUpdate)
+	}))
+}
+
 func (s *MethodTestSuite) TestProvisionerKeys() {
 	s.Run("InsertProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
 		org := dbgen.Organization(s.T(), db, database.Organization{})
@@ -3471,51 +4645,258 @@ func (s *MethodTestSuite) TestProvisionerKeys() {
 		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
 		check.Args(pk.ID).Asserts(pk, policy.ActionRead).Returns(pk)
 	}))
-	s.Run("GetProvisionerKeyByHashedSecret", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetProvisionerKeyByHashedSecret", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID, HashedSecret: []byte("foo")})
+		check.Args([]byte("foo")).Asserts(pk, policy.ActionRead).Returns(pk)
+	}))
+	s.Run("GetProvisionerKeyByName", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
+		check.Args(database.GetProvisionerKeyByNameParams{
+			OrganizationID: org.ID,
+			Name:           pk.Name,
+		}).Asserts(pk, policy.ActionRead).Returns(pk)
+	}))
+	s.Run("ListProvisionerKeysByOrganization", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
+		pks := []database.ProvisionerKey{
+			{
+				ID:             pk.ID,
+				CreatedAt:      pk.CreatedAt,
+				OrganizationID: pk.OrganizationID,
+				Name:           pk.Name,
+				HashedSecret:   pk.HashedSecret,
+			},
+		}
+		check.Args(org.ID).Asserts(pk, policy.ActionRead).Returns(pks)
+	}))
+	s.Run("ListProvisionerKeysByOrganizationExcludeReserved", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
+		pks := []database.ProvisionerKey{
+			{
+				ID:             pk.ID,
+				CreatedAt:      pk.CreatedAt,
+				OrganizationID: pk.OrganizationID,
+				Name:           pk.Name,
+				HashedSecret:   pk.HashedSecret,
+			},
+		}
+		check.Args(org.ID).Asserts(pk, policy.ActionRead).Returns(pks)
+	}))
+	s.Run("DeleteProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
+		check.Args(pk.ID).Asserts(pk, policy.ActionDelete).Returns()
+	}))
+	s.Run("RotateProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID, HashedSecret: []byte("foo")})
+		check.Args(database.RotateProvisionerKeyParams{
+			ID:           pk.ID,
+			HashedSecret: []byte("bar"),
+			PreviousSecretExpiresAt: sql.NullTime{
+				Time:  dbtime.Now().Add(time.Hour),
+				Valid: true,
+			},
+		}).Asserts(pk, policy.ActionUpdate)
+	}))
+	s.Run("UpdateProvisionerKeyLastUsedAt", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
+		check.Args(database.UpdateProvisionerKeyLastUsedAtParams{
+			ID:         pk.ID,
+			LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(pk, policy.ActionUpdate).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestServiceAccounts() {
+	s.Run("InsertServiceAccount", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{LoginType: database.LoginTypeNone})
+		arg := database.InsertServiceAccountParams{
+			UserID:         u.ID,
+			OrganizationID: org.ID,
+			Description:    "automation",
+			CreatedAt:      dbtime.Now(),
+		}
+		check.Args(arg).Asserts(rbac.ResourceServiceAccount.WithID(u.ID).InOrg(org.ID), policy.ActionCreate)
+	}))
+	s.Run("GetServiceAccountByUserID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{LoginType: database.LoginTypeNone})
+		sa := dbgen.ServiceAccount(s.T(), db, database.ServiceAccount{UserID: u.ID, OrganizationID: org.ID})
+		check.Args(u.ID).Asserts(sa, policy.ActionRead).Returns(sa)
+	}))
+	s.Run("GetServiceAccountsByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{LoginType: database.LoginTypeNone})
+		sa := dbgen.ServiceAccount(s.T(), db, database.ServiceAccount{UserID: u.ID, OrganizationID: org.ID})
+		check.Args(org.ID).Asserts(sa, policy.ActionRead).Returns([]database.ServiceAccount{sa})
+	}))
+}
+
+func (s *MethodTestSuite) TestProvisionerReservations() {
+	insertReservation := func(t *testing.T, db database.Store, orgID uuid.UUID) database.ProvisionerReservation {
+		now := dbtime.Now()
+		r, err := db.InsertProvisionerReservation(context.Background(), database.InsertProvisionerReservationParams{
+			ID:             uuid.New(),
+			OrganizationID: orgID,
+			Tags:           database.StringMap{"purpose": "training"},
+			Slots:          2,
+			StartsAt:       now,
+			EndsAt:         now.Add(time.Hour),
+			CreatedAt:      now,
+		})
+		require.NoError(t, err)
+		return r
+	}
+	s.Run("InsertProvisionerReservation", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		id := uuid.New()
+		now := dbtime.Now()
+		check.Args(database.InsertProvisionerReservationParams{
+			ID:             id,
+			OrganizationID: org.ID,
+			Tags:           database.StringMap{"purpose": "training"},
+			Slots:          1,
+			StartsAt:       now,
+			EndsAt:         now.Add(time.Hour),
+			CreatedAt:      now,
+		}).Asserts(rbac.ResourceProvisionerDaemon.InOrg(org.ID).WithID(id), policy.ActionCreate)
+	}))
+	s.Run("GetProvisionerReservationByID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		r := insertReservation(s.T(), db, org.ID)
+		check.Args(r.ID).Asserts(r, policy.ActionRead).Returns(r)
+	}))
+	s.Run("GetProvisionerReservationsByOrganization", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		r := insertReservation(s.T(), db, org.ID)
+		check.Args(database.GetProvisionerReservationsByOrganizationParams{
+			OrganizationID: org.ID,
+			EndsAfter:      dbtime.Now(),
+		}).Asserts(r, policy.ActionRead).Returns([]database.ProvisionerReservation{r})
+	}))
+	s.Run("DeleteProvisionerReservation", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		r := insertReservation(s.T(), db, org.ID)
+		check.Args(r.ID).Asserts(r, policy.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestBuildAlertRules() {
+	s.Run("InsertBuildAlertRule", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		now := dbtime.Now()
+		check.Args(database.InsertBuildAlertRuleParams{
+			ID:             uuid.New(),
+			OrganizationID: org.ID,
+			Name:           "failures",
+			Metric:         database.BuildAlertRuleMetricFailureRate,
+			Threshold:      10,
+			WindowMs:       time.Hour.Milliseconds(),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}).Asserts(rbac.ResourceTemplate.InOrg(org.ID), policy.ActionCreate)
+	}))
+	s.Run("GetBuildAlertRuleByID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		r := dbgen.BuildAlertRule(s.T(), db, database.BuildAlertRule{OrganizationID: org.ID})
+		check.Args(r.ID).Asserts(r, policy.ActionRead).Returns(r)
+	}))
+	s.Run("GetBuildAlertRulesByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		r := dbgen.BuildAlertRule(s.T(), db, database.BuildAlertRule{OrganizationID: org.ID})
+		check.Args(org.ID).Asserts(r, policy.ActionRead).Returns([]database.BuildAlertRule{r})
+	}))
+	s.Run("UpdateBuildAlertRuleByID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		r := dbgen.BuildAlertRule(s.T(), db, database.BuildAlertRule{OrganizationID: org.ID})
+		check.Args(database.UpdateBuildAlertRuleByIDParams{
+			ID:        r.ID,
+			Name:      r.Name,
+			Metric:    database.BuildAlertRuleMetricQueueWaitP95,
+			Threshold: float64(time.Minute.Milliseconds()),
+			WindowMs:  r.WindowMs,
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(r, policy.ActionUpdate)
+	}))
+	s.Run("DeleteBuildAlertRuleByID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		r := dbgen.BuildAlertRule(s.T(), db, database.BuildAlertRule{OrganizationID: org.ID})
+		check.Args(r.ID).Asserts(r, policy.ActionDelete).Returns()
+	}))
+	s.Run("GetBuildAlertRules", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetBuildAlertRuleMetrics", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		now := dbtime.Now()
+		check.Args(database.GetBuildAlertRuleMetricsParams{
+			OrganizationID: org.ID,
+			Since:          now.Add(-time.Hour),
+			Now:            now,
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateBuildAlertRuleEvaluation", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		r := dbgen.BuildAlertRule(s.T(), db, database.BuildAlertRule{OrganizationID: org.ID})
+		check.Args(database.UpdateBuildAlertRuleEvaluationParams{
+			ID:              r.ID,
+			LastEvaluatedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestProvisionerBuildPauses() {
+	insertPause := func(t *testing.T, db database.Store, orgID uuid.NullUUID) database.ProvisionerBuildPause {
+		p, err := db.InsertProvisionerBuildPause(context.Background(), database.InsertProvisionerBuildPauseParams{
+			ID:             uuid.New(),
+			OrganizationID: orgID,
+			Reason:         "maintenance",
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(t, err)
+		return p
+	}
+	s.Run("Deployment/InsertProvisionerBuildPause", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertProvisionerBuildPauseParams{
+			ID:        uuid.New(),
+			CreatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
+	}))
+	s.Run("Organization/InsertProvisionerBuildPause", s.Subtest(func(db database.Store, check *expects) {
 		org := dbgen.Organization(s.T(), db, database.Organization{})
-		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID, HashedSecret: []byte("foo")})
-		check.Args([]byte("foo")).Asserts(pk, policy.ActionRead).Returns(pk)
+		check.Args(database.InsertProvisionerBuildPauseParams{
+			ID:             uuid.New(),
+			OrganizationID: uuid.NullUUID{UUID: org.ID, Valid: true},
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(rbac.ResourceProvisionerDaemon.InOrg(org.ID), policy.ActionUpdate)
 	}))
-	s.Run("GetProvisionerKeyByName", s.Subtest(func(db database.Store, check *expects) {
-		org := dbgen.Organization(s.T(), db, database.Organization{})
-		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
-		check.Args(database.GetProvisionerKeyByNameParams{
-			OrganizationID: org.ID,
-			Name:           pk.Name,
-		}).Asserts(pk, policy.ActionRead).Returns(pk)
+	s.Run("Deployment/GetProvisionerBuildPause", s.Subtest(func(db database.Store, check *expects) {
+		p := insertPause(s.T(), db, uuid.NullUUID{})
+		check.Args(uuid.NullUUID{}).Asserts().Returns(p)
 	}))
-	s.Run("ListProvisionerKeysByOrganization", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("Organization/GetProvisionerBuildPause", s.Subtest(func(db database.Store, check *expects) {
 		org := dbgen.Organization(s.T(), db, database.Organization{})
-		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
-		pks := []database.ProvisionerKey{
-			{
-				ID:             pk.ID,
-				CreatedAt:      pk.CreatedAt,
-				OrganizationID: pk.OrganizationID,
-				Name:           pk.Name,
-				HashedSecret:   pk.HashedSecret,
-			},
-		}
-		check.Args(org.ID).Asserts(pk, policy.ActionRead).Returns(pks)
+		orgID := uuid.NullUUID{UUID: org.ID, Valid: true}
+		p := insertPause(s.T(), db, orgID)
+		check.Args(orgID).Asserts(rbac.ResourceOrganization.WithID(org.ID).InOrg(org.ID), policy.ActionRead).Returns(p)
 	}))
-	s.Run("ListProvisionerKeysByOrganizationExcludeReserved", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("DeleteProvisionerBuildPause", s.Subtest(func(db database.Store, check *expects) {
 		org := dbgen.Organization(s.T(), db, database.Organization{})
-		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
-		pks := []database.ProvisionerKey{
-			{
-				ID:             pk.ID,
-				CreatedAt:      pk.CreatedAt,
-				OrganizationID: pk.OrganizationID,
-				Name:           pk.Name,
-				HashedSecret:   pk.HashedSecret,
-			},
-		}
-		check.Args(org.ID).Asserts(pk, policy.ActionRead).Returns(pks)
+		orgID := uuid.NullUUID{UUID: org.ID, Valid: true}
+		_ = insertPause(s.T(), db, orgID)
+		check.Args(orgID).Asserts(rbac.ResourceProvisionerDaemon.InOrg(org.ID), policy.ActionUpdate).Returns()
 	}))
-	s.Run("DeleteProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
-		org := dbgen.Organization(s.T(), db, database.Organization{})
-		pk := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
-		check.Args(pk.ID).Asserts(pk, policy.ActionDelete).Returns()
+	s.Run("UpdatePendingProvisionerJobsUpdatedAt", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdatePendingProvisionerJobsUpdatedAtParams{
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
 	}))
 }
 
@@ -3985,6 +5366,124 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		check.Args(database.UpdateWorkspaceBuildProvisionerStateByIDParams{
 			ID:               build.ID,
 			ProvisionerState: []byte("testing"),
+		}).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("UpsertWorkspaceBuildInterimState", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		check.Args(database.UpsertWorkspaceBuildInterimStateParams{
+			WorkspaceBuildID: build.ID,
+			State:            []byte("testing"),
+			UpdatedAt:        dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspaceBuildInterimState", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.New()).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetWorkspaceDriftCheckCandidates", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceDriftCheckCandidatesParams{
+			CheckedBefore: dbtime.Now(),
+			LimitCount:    10,
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceDriftChecksByWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceDriftCheck", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		check.Args(database.UpsertWorkspaceDriftCheckParams{
+			WorkspaceID:      ws.ID,
+			WorkspaceBuildID: build.ID,
+			JobID:            uuid.New(),
+			CreatedAt:        dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceDriftCheckByJobID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		jobID := uuid.New()
+		err := db.UpsertWorkspaceDriftCheck(context.Background(), database.UpsertWorkspaceDriftCheckParams{
+			WorkspaceID:      ws.ID,
+			WorkspaceBuildID: build.ID,
+			JobID:            jobID,
+			CreatedAt:        dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWorkspaceDriftCheckByJobIDParams{
+			JobID:             jobID,
+			CompletedAt:       sql.NullTime{Time: dbtime.Now(), Valid: true},
+			DriftedResources:  []string{},
+			OrphanedResources: []string{"docker_container.workspace"},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetStartableWorkspaceBuildQueueEntries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceBuildQueueEntryFailed", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateWorkspaceBuildQueueEntryFailedParams{
+			ID:       uuid.New(),
+			FailedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+			Error:    "template version is archived",
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetActiveWorkspaceMigrations", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceMigrationStatus", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		migration, err := db.InsertWorkspaceMigration(context.Background(), database.InsertWorkspaceMigrationParams{
+			ID:                        uuid.New(),
+			WorkspaceID:               ws.ID,
+			InitiatorID:               ws.OwnerID,
+			DestinationURL:            "https://coder.example.com",
+			DestinationOrganizationID: uuid.New(),
+			DestinationOwner:          codersdk.Me,
+			DestinationSessionToken:   "token",
+			CreatedAt:                 dbtime.Now(),
+			UpdatedAt:                 dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWorkspaceMigrationStatusParams{
+			ID:            migration.ID,
+			Status:        database.WorkspaceMigrationStatusStopping,
+			SourceBuildID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			UpdatedAt:     dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("CompleteWorkspaceMigration", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		migration, err := db.InsertWorkspaceMigration(context.Background(), database.InsertWorkspaceMigrationParams{
+			ID:                        uuid.New(),
+			WorkspaceID:               ws.ID,
+			InitiatorID:               ws.OwnerID,
+			DestinationURL:            "https://coder.example.com",
+			DestinationOrganizationID: uuid.New(),
+			DestinationOwner:          codersdk.Me,
+			DestinationSessionToken:   "token",
+			CreatedAt:                 dbtime.Now(),
+			UpdatedAt:                 dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.CompleteWorkspaceMigrationParams{
+			ID:          migration.ID,
+			Status:      database.WorkspaceMigrationStatusFailed,
+			Error:       "destination unreachable",
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceMigrationSessionToken", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateWorkspaceMigrationSessionTokenParams{
+			ID:                      uuid.New(),
+			DestinationSessionToken: "token",
 		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
 	s.Run("UpsertLastUpdateCheck", s.Subtest(func(db database.Store, check *expects) {
@@ -3995,6 +5494,22 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		require.NoError(s.T(), err)
 		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("UpsertJobReaperLastRunAt", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args("value").Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
+	}))
+	s.Run("GetJobReaperLastRunAt", s.Subtest(func(db database.Store, check *expects) {
+		err := db.UpsertJobReaperLastRunAt(context.Background(), "value")
+		require.NoError(s.T(), err)
+		check.Args().Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead).Returns("value")
+	}))
+	s.Run("UpsertDBPurgeStats", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args("value").Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetDBPurgeStats", s.Subtest(func(db database.Store, check *expects) {
+		err := db.UpsertDBPurgeStats(context.Background(), "value")
+		require.NoError(s.T(), err)
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead).Returns("value")
+	}))
 	s.Run("GetWorkspaceBuildsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{CreatedAt: time.Now().Add(-time.Hour)})
@@ -4021,12 +5536,55 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		check.Args(time.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
-		check.Args().Asserts(rbac.ResourceSystem, policy.ActionDelete)
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DropOldWorkspaceAgentStatsPartitions", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldAuditLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldAuditLogsParams{Before: time.Now(), LimitCount: 1}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DropOldAuditLogsPartitions", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldProvisionerJobLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldProvisionerJobLogsParams{CompletedBefore: time.Now(), LimitCount: 1}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("CreateTimePartition", s.Subtest(func(db database.Store, check *expects) {
+		start := dbtime.StartOfDay(dbtime.Now())
+		check.Args(database.CreateTimePartitionParams{
+			ParentTable:    "workspace_agent_stats",
+			PartitionStart: start,
+			PartitionEnd:   start.AddDate(0, 0, 1),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
 		check.Args(time.Now()).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
 	}))
+	s.Run("GetProvisionerJobThroughputByTags", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.GetProvisionerJobThroughputByTagsParams{
+			OrganizationIDs: []uuid.UUID{org.ID},
+			StartedAfter:    time.Now().Add(-time.Hour),
+		}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
+	s.Run("GetProvisionerJobsRunningBetween", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args(database.GetProvisionerJobsRunningBetweenParams{
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now(),
+		}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
+	s.Run("GetPendingProvisionerJobsQueueDepthByTags", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
+	s.Run("CountInProgressProvisionerJobsByInitiator", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.CountInProgressProvisionerJobsByInitiatorParams{
+			InitiatorID: u.ID,
+			TemplateID:  uuid.New(),
+		}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
 	s.Run("GetTemplateVersionsByIDs", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		t1 := dbgen.Template(s.T(), db, database.Template{})
@@ -4266,6 +5824,54 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			JobID: j.ID,
 		}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
 	}))
+	s.Run("InsertProvisionerJobLogArchive", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		f := dbgen.File(s.T(), db, database.File{CreatedBy: u.ID})
+		check.Args(database.InsertProvisionerJobLogArchiveParams{
+			JobID:      j.ID,
+			FileID:     f.ID,
+			ArchivedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("GetProvisionerJobsToArchiveLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetProvisionerJobsToArchiveLogsParams{
+			CompletedBefore: dbtime.Now(),
+			LimitCount:      10,
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("DeleteProvisionerJobLogsByJobID", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(j.ID).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetWorkspaceBuildsToArchive", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceBuildsToArchiveParams{
+			CompletedBefore: dbtime.Now(),
+			LimitCount:      10,
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("InsertWorkspaceBuildArchive", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		f := dbgen.File(s.T(), db, database.File{CreatedBy: u.ID})
+		check.Args(database.InsertWorkspaceBuildArchiveParams{
+			WorkspaceBuildID: build.ID,
+			FileID:           f.ID,
+			ArchivedAt:       dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("DeleteWorkspaceBuildArchiveByBuildID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.New()).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteWorkspaceResourceMetadataByResourceIDs", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		check.Args([]uuid.UUID{res.ID}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("UpsertProvisionerDaemon", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		org := dbgen.Organization(s.T(), db, database.Organization{})
@@ -4311,6 +5917,27 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("DeleteOldWorkspaceAgentLogs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
 	}))
+	s.Run("DeleteOldWorkspaceAgentResourceUsage", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldWorkspaceAgentConnectionQuality", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldWorkspaceAgentHealthEvents", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldWorkspaceSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetEncryptedWorkspaceSessionRecordingChunks", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceSessionRecordingChunkData", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateWorkspaceSessionRecordingChunkDataParams{}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("DeleteOldDeletedWorkspaces", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete).Returns(int64(0))
+	}))
 	s.Run("InsertWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAgentStatsParams{}).Asserts(rbac.ResourceSystem, policy.ActionCreate).Errors(errMatchAny)
 	}))
@@ -4388,6 +6015,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("UpsertNotificationsSettings", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("foo").Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
 	}))
+	s.Run("GetReadOnlySettings", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts()
+	}))
+	s.Run("UpsertReadOnlySettings", s.Subtest(func(db database.Store, check *expects) {
+		check.Args("foo").Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
+	}))
 	s.Run("GetDeploymentWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(time.Time{}).Asserts()
 	}))
@@ -4461,6 +6094,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		check.Args(database.InsertTemplateVersionVariableParams{}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
+	s.Run("GetSensitiveTemplateVersionVariables", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateTemplateVersionVariableValue", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateTemplateVersionVariableValueParams{}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 	s.Run("InsertTemplateVersionWorkspaceTag", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		check.Args(database.InsertTemplateVersionWorkspaceTagParams{}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
@@ -4523,6 +6162,24 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetWorkspaceBuildStatsByTemplates", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("GetWorkspaceBuildOutcomesCompletedBetween", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceBuildOutcomesCompletedBetweenParams{
+			CompletedAfter:  dbtime.Now().Add(-time.Hour),
+			CompletedBefore: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetRunningWorkspaceBuildsExceedingDurationThreshold", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("InsertWorkspaceBuildDurationNotification", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: w.ID, JobID: uuid.New()})
+		check.Args(database.InsertWorkspaceBuildDurationNotificationParams{
+			WorkspaceBuildID: b.ID,
+			NotifiedAt:       dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate).Returns(int64(1))
+	}))
 	s.Run("UpsertNotificationReportGeneratorLog", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.UpsertNotificationReportGeneratorLogParams{
 			NotificationTemplateID: uuid.New(),
@@ -4605,6 +6262,36 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetWorkspaceModulesCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("GetWorkspaceProvisionerAffinity", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.New()).Asserts(rbac.ResourceSystem, policy.ActionRead).Errors(sql.ErrNoRows)
+	}))
+	s.Run("UpsertWorkspaceProvisionerAffinity", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: o.ID,
+			TemplateID:     tpl.ID,
+		})
+		d := dbgen.ProvisionerDaemon(s.T(), db, database.ProvisionerDaemon{
+			OrganizationID: o.ID,
+		})
+		check.Args(database.UpsertWorkspaceProvisionerAffinityParams{
+			WorkspaceID:         w.ID,
+			ProvisionerDaemonID: d.ID,
+			TemplateVersionID:   tv.ID,
+			UpdatedAt:           dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 	s.Run("GetTelemetryItem", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("test").Asserts(rbac.ResourceSystem, policy.ActionRead).Errors(sql.ErrNoRows)
 	}))
@@ -4687,6 +6374,20 @@ func (s *MethodTestSuite) TestNotifications() {
 			Limit:  10,
 		}).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
 	}))
+	s.Run("GetUsersWithDueNotificationDigests", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
+	}))
+	s.Run("GetBatchedNotificationMessagesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(user.ID).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead).
+			ErrorsWithInMemDB(dbmem.ErrUnimplemented)
+	}))
+	s.Run("MarkNotificationMessagesDigested", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args(database.MarkNotificationMessagesDigestedParams{
+			IDs:        []uuid.UUID{uuid.New()},
+			DigestedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceNotificationMessage, policy.ActionUpdate)
+	}))
 
 	// webpush subscriptions
 	s.Run("GetWebpushSubscriptionsByUserID", s.Subtest(func(db database.Store, check *expects) {
@@ -4757,6 +6458,50 @@ func (s *MethodTestSuite) TestNotifications() {
 			Disableds:               []bool{true, false},
 		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
 	}))
+	s.Run("UpdateUserNotificationDigestPreferences", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserNotificationDigestPreferencesParams{
+			UserID:                  user.ID,
+			NotificationTemplateIds: []uuid.UUID{notifications.TemplateWorkspaceDormant},
+			Digests:                 []bool{true},
+		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
+	}))
+	s.Run("GetUserNotificationDigestFrequency", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(user.ID).
+			Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionRead).
+			Errors(sql.ErrNoRows)
+	}))
+	s.Run("GetUserNotificationBuildDurationThreshold", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(user.ID).
+			Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionRead).
+			Errors(sql.ErrNoRows)
+	}))
+	s.Run("UpdateUserNotificationBuildDurationThreshold", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserNotificationBuildDurationThresholdParams{
+			UserID:                               user.ID,
+			NotificationBuildDurationThresholdMs: "600000",
+		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate).
+			Returns(database.UserConfig{
+				UserID: user.ID,
+				Key:    "notification_build_duration_threshold_ms",
+				Value:  "600000",
+			})
+	}))
+	s.Run("UpdateUserNotificationDigestFrequency", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserNotificationDigestFrequencyParams{
+			UserID:                      user.ID,
+			NotificationDigestFrequency: "weekly",
+		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate).
+			Returns(database.UserConfig{
+				UserID: user.ID,
+				Key:    "notification_digest_frequency",
+				Value:  "weekly",
+			})
+	}))
 
 	s.Run("GetInboxNotificationsByUserID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
@@ -5061,6 +6806,11 @@ func (s *MethodTestSuite) TestPrebuilds() {
 		check.Args().
 			Asserts(rbac.ResourceWorkspace.All(), policy.ActionRead)
 	}))
+	s.Run("GetPrebuildClaimDemand", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args(dbtime.Now().Add(-time.Hour)).
+			Asserts(rbac.ResourceWorkspace.All(), policy.ActionRead).
+			ErrorsWithInMemDB(dbmem.ErrUnimplemented)
+	}))
 	s.Run("CountInProgressPrebuilds", s.Subtest(func(_ database.Store, check *expects) {
 		check.Args().
 			Asserts(rbac.ResourceWorkspace.All(), policy.ActionRead).
@@ -5394,6 +7144,48 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 	}))
 }
 
+func (s *MethodTestSuite) TestLoginDeviceCodes() {
+	s.Run("InsertLoginDeviceCode", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertLoginDeviceCodeParams{
+			ID:             uuid.New(),
+			DeviceCodeHash: []byte("hash"),
+			UserCode:       "BCDFGHJK",
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("GetLoginDeviceCodeByDeviceCodeHash", s.Subtest(func(db database.Store, check *expects) {
+		code := dbgen.LoginDeviceCode(s.T(), db, database.LoginDeviceCode{})
+		check.Args(code.DeviceCodeHash).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(code)
+	}))
+	s.Run("GetLoginDeviceCodeByUserCode", s.Subtest(func(db database.Store, check *expects) {
+		code := dbgen.LoginDeviceCode(s.T(), db, database.LoginDeviceCode{})
+		check.Args(code.UserCode).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(code)
+	}))
+	s.Run("ApproveLoginDeviceCode", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		code := dbgen.LoginDeviceCode(s.T(), db, database.LoginDeviceCode{})
+		check.Args(database.ApproveLoginDeviceCodeParams{
+			ID:     code.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("ConsumeApprovedLoginDeviceCode", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		code := dbgen.LoginDeviceCode(s.T(), db, database.LoginDeviceCode{
+			UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+		})
+		check.Args(database.ConsumeApprovedLoginDeviceCodeParams{
+			DeviceCodeHash: code.DeviceCodeHash,
+			Now:            dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionDelete).Returns(code)
+	}))
+	s.Run("DeleteExpiredLoginDeviceCodes", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldWorkspaceBuildIdempotencyKeys", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+}
+
 func (s *MethodTestSuite) TestResourcesMonitor() {
 	createAgent := func(t *testing.T, db database.Store) (database.WorkspaceAgent, database.WorkspaceTable) {
 		t.Helper()
@@ -5472,6 +7264,10 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 		check.Args(dbtime.Now()).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionRead)
 	}))
 
+	s.Run("GetWorkspaceAgentConnectionTypeCounts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+
 	s.Run("FetchMemoryResourceMonitorsByAgentID", s.Subtest(func(db database.Store, check *expects) {
 		agt, w := createAgent(s.T(), db)
 
@@ -5627,3 +7423,58 @@ func (s *MethodTestSuite) TestAuthorizePrebuiltWorkspace() {
 			}).Asserts(w, policy.ActionUpdate, w.AsPrebuild(), policy.ActionUpdate)
 	}))
 }
+
+func (s *MethodTestSuite) TestAccessRequests() {
+	s.Run("InsertAccessRequest", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertAccessRequestParams{
+			ID:             uuid.New(),
+			OrganizationID: org.ID,
+			RequesterID:    u.ID,
+			ResourceType:   database.AccessRequestResourceTypeTemplate,
+			ResourceID:     uuid.New(),
+			Role:           "use",
+			DurationMs:     time.Hour.Milliseconds(),
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("GetAccessRequestByID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		r := dbgen.AccessRequest(s.T(), db, database.AccessRequest{OrganizationID: org.ID, RequesterID: u.ID})
+		check.Args(r.ID).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(r)
+	}))
+	s.Run("GetAccessRequests", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		r := dbgen.AccessRequest(s.T(), db, database.AccessRequest{OrganizationID: org.ID, RequesterID: u.ID})
+		check.Args(database.GetAccessRequestsParams{RequesterID: u.ID}).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.AccessRequest{r})
+	}))
+	s.Run("UpdateAccessRequestReviewByID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		r := dbgen.AccessRequest(s.T(), db, database.AccessRequest{OrganizationID: org.ID, RequesterID: u.ID})
+		check.Args(database.UpdateAccessRequestReviewByIDParams{
+			ID:     r.ID,
+			Status: database.AccessRequestStatusDenied,
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetExpiredAccessRequests", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateAccessRequestExpiredByID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		r := dbgen.AccessRequest(s.T(), db, database.AccessRequest{OrganizationID: org.ID, RequesterID: u.ID})
+		now := dbtime.Now()
+		_, err := db.UpdateAccessRequestReviewByID(context.Background(), database.UpdateAccessRequestReviewByIDParams{
+			ID:         r.ID,
+			Status:     database.AccessRequestStatusApproved,
+			ReviewedAt: sql.NullTime{Time: now, Valid: true},
+			ExpiresAt:  sql.NullTime{Time: now, Valid: true},
+		})
+		require.NoError(s.T(), err)
+		check.Args(r.ID).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+}
@@ -0,0 +1,421 @@
+// Package dbcheck validates a PostgreSQL database before Coder migrates it,
+// and estimates how long the pending migrations take. It only reads from the
+// database, so it is safe to run against a database that is in use.
+package dbcheck
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database/migrations"
+)
+
+// MinVersion is the minimum supported PostgreSQL version in the format of
+// server_version_num.
+const MinVersion = 130000
+
+// RequiredExtensions are the extensions the migrations depend on. plpgsql
+// is used by the trigger functions.
+var RequiredExtensions = []string{"plpgsql"}
+
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusWarning Status = "warning"
+	StatusFailed  Status = "failed"
+)
+
+// Check is the result of a single check.
+type Check struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// Transaction is a transaction that has been open for longer than the
+// configured threshold.
+type Transaction struct {
+	PID      int64         `json:"pid"`
+	Username string        `json:"username"`
+	State    string        `json:"state"`
+	Duration time.Duration `json:"duration"`
+	Query    string        `json:"query"`
+}
+
+// TableEstimate is the estimated time the pending migrations spend on
+// rewriting, scanning or indexing a table.
+type TableEstimate struct {
+	Table string `json:"table"`
+	// Rows is the estimated number of rows, including partitions.
+	Rows int64 `json:"rows"`
+	// Size is the size of the table, its partitions and indexes in bytes.
+	Size int64 `json:"size"`
+	// Operations is the number of statements of pending migrations that
+	// process all rows of the table.
+	Operations int           `json:"operations"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Report is the result of Run.
+type Report struct {
+	Checks            []Check         `json:"checks"`
+	LongTransactions  []Transaction   `json:"long_transactions"`
+	CurrentVersion    uint            `json:"current_version"`
+	PendingMigrations []uint          `json:"pending_migrations"`
+	Tables            []TableEstimate `json:"tables"`
+	EstimatedDuration time.Duration   `json:"estimated_duration"`
+}
+
+// Failed returns whether any check failed.
+func (r Report) Failed() bool {
+	return slices.ContainsFunc(r.Checks, func(c Check) bool {
+		return c.Status == StatusFailed
+	})
+}
+
+type Options struct {
+	// LongTransactionThreshold is the age after which open transactions
+	// are reported, since migrations wait for their locks.
+	LongTransactionThreshold time.Duration
+	// RowsPerSecond is the assumed rate at which a migration statement
+	// processes the rows of a table.
+	RowsPerSecond int64
+}
+
+// Run checks the database. Failing checks are reported in the returned
+// report, an error is only returned if the database cannot be queried.
+func Run(ctx context.Context, db *sql.DB, opts Options) (Report, error) {
+	if opts.RowsPerSecond <= 0 {
+		return Report{}, xerrors.New("rows per second must be positive")
+	}
+	var report Report
+
+	check, err := checkVersion(ctx, db)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Checks = append(report.Checks, check)
+
+	check, err = checkExtensions(ctx, db)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Checks = append(report.Checks, check)
+
+	checks, err := checkTimeouts(ctx, db)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Checks = append(report.Checks, checks...)
+
+	report.LongTransactions, err = longTransactions(ctx, db, opts.LongTransactionThreshold)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Checks = append(report.Checks, checkLongTransactions(report.LongTransactions, opts.LongTransactionThreshold))
+
+	pending, current, dirty, err := migrations.Pending(ctx, db)
+	if err != nil {
+		return Report{}, xerrors.Errorf("get pending migrations: %w", err)
+	}
+	report.CurrentVersion = current
+	for _, migration := range pending {
+		report.PendingMigrations = append(report.PendingMigrations, migration.Version)
+	}
+	report.Checks = append(report.Checks, checkMigrations(pending, current, dirty))
+
+	stats, err := tableStats(ctx, db)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Tables = estimate(pending, stats, opts.RowsPerSecond)
+	for _, table := range report.Tables {
+		report.EstimatedDuration += table.Duration
+	}
+	return report, nil
+}
+
+func checkVersion(ctx context.Context, db *sql.DB) (Check, error) {
+	var (
+		num     int
+		version string
+	)
+	err := db.QueryRowContext(ctx, "SELECT current_setting('server_version_num')::int, current_setting('server_version')").Scan(&num, &version)
+	if err != nil {
+		return Check{}, xerrors.Errorf("get postgres version: %w", err)
+	}
+	check := Check{Name: "postgres_version", Status: StatusOK, Message: "PostgreSQL " + version}
+	if num < MinVersion {
+		check.Status = StatusFailed
+		check.Message = fmt.Sprintf("PostgreSQL %s is not supported, v13.0.0 or higher is required", version)
+	}
+	return check, nil
+}
+
+func checkExtensions(ctx context.Context, db *sql.DB) (Check, error) {
+	rows, err := db.QueryContext(ctx, "SELECT extname FROM pg_extension")
+	if err != nil {
+		return Check{}, xerrors.Errorf("get extensions: %w", err)
+	}
+	defer rows.Close()
+	installed := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return Check{}, xerrors.Errorf("scan extension: %w", err)
+		}
+		installed[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return Check{}, xerrors.Errorf("get extensions: %w", err)
+	}
+
+	var missing []string
+	for _, name := range RequiredExtensions {
+		if !installed[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return Check{
+			Name:    "extensions",
+			Status:  StatusFailed,
+			Message: "missing required extensions: " + strings.Join(missing, ", "),
+		}, nil
+	}
+	return Check{
+		Name:    "extensions",
+		Status:  StatusOK,
+		Message: "installed: " + strings.Join(RequiredExtensions, ", "),
+	}, nil
+}
+
+func checkTimeouts(ctx context.Context, db *sql.DB) ([]Check, error) {
+	var lockTimeout, statementTimeout string
+	err := db.QueryRowContext(ctx, "SELECT current_setting('lock_timeout'), current_setting('statement_timeout')").Scan(&lockTimeout, &statementTimeout)
+	if err != nil {
+		return nil, xerrors.Errorf("get timeouts: %w", err)
+	}
+
+	lock := Check{Name: "lock_timeout", Status: StatusOK, Message: "lock_timeout is " + lockTimeout}
+	if lockTimeout == "0" {
+		lock.Status = StatusWarning
+		lock.Message = "lock_timeout is not set, migrations wait indefinitely for locks held by other sessions and block all queries queued behind them"
+	}
+	statement := Check{Name: "statement_timeout", Status: StatusOK, Message: "statement_timeout is not set"}
+	if statementTimeout != "0" {
+		statement.Status = StatusWarning
+		statement.Message = fmt.Sprintf("statement_timeout is %s, migrations of large tables may be canceled", statementTimeout)
+	}
+	return []Check{lock, statement}, nil
+}
+
+func longTransactions(ctx context.Context, db *sql.DB, threshold time.Duration) ([]Transaction, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT
+			pid,
+			COALESCE(usename, ''),
+			COALESCE(state, ''),
+			EXTRACT(EPOCH FROM NOW() - xact_start)::bigint,
+			LEFT(query, 80)
+		FROM
+			pg_stat_activity
+		WHERE
+			datname = current_database()
+			AND pid <> pg_backend_pid()
+			AND xact_start < NOW() - make_interval(secs => $1)
+		ORDER BY
+			xact_start ASC`, threshold.Seconds())
+	if err != nil {
+		return nil, xerrors.Errorf("get long running transactions: %w", err)
+	}
+	defer rows.Close()
+	var transactions []Transaction
+	for rows.Next() {
+		var (
+			transaction Transaction
+			seconds     int64
+		)
+		if err := rows.Scan(&transaction.PID, &transaction.Username, &transaction.State, &seconds, &transaction.Query); err != nil {
+			return nil, xerrors.Errorf("scan transaction: %w", err)
+		}
+		transaction.Duration = time.Duration(seconds) * time.Second
+		transactions = append(transactions, transaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("get long running transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+func checkLongTransactions(transactions []Transaction, threshold time.Duration) Check {
+	if len(transactions) == 0 {
+		return Check{
+			Name:    "long_running_transactions",
+			Status:  StatusOK,
+			Message: fmt.Sprintf("no transactions open longer than %s", threshold),
+		}
+	}
+	return Check{
+		Name:    "long_running_transactions",
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d transactions open longer than %s, migrations wait for their locks", len(transactions), threshold),
+	}
+}
+
+func checkMigrations(pending []migrations.Migration, current uint, dirty bool) Check {
+	check := Check{Name: "migrations"}
+	switch {
+	case dirty:
+		check.Status = StatusFailed
+		check.Message = fmt.Sprintf("database is dirty at version %d, a previous migration failed", current)
+	case len(pending) == 0:
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("database is up to date at version %d", current)
+	default:
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("%d pending migrations from version %d to %d", len(pending), current, pending[len(pending)-1].Version)
+	}
+	return check
+}
+
+type tableStat struct {
+	rows int64
+	size int64
+}
+
+// tableStats returns the estimated row count and size of all tables in the
+// current schema. Partitions are added to their parent table.
+func tableStats(ctx context.Context, db *sql.DB) (map[string]tableStat, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT
+			c.relname,
+			GREATEST(c.reltuples, 0)::bigint,
+			pg_total_relation_size(c.oid),
+			COALESCE(parent.relname, '')
+		FROM
+			pg_class c
+		JOIN
+			pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN
+			pg_inherits i ON i.inhrelid = c.oid
+		LEFT JOIN
+			pg_class parent ON parent.oid = i.inhparent
+		WHERE
+			n.nspname = current_schema()
+			AND c.relkind IN ('r', 'p')`)
+	if err != nil {
+		return nil, xerrors.Errorf("get table stats: %w", err)
+	}
+	defer rows.Close()
+	stats := make(map[string]tableStat)
+	for rows.Next() {
+		var (
+			name, parent string
+			stat         tableStat
+		)
+		if err := rows.Scan(&name, &stat.rows, &stat.size, &parent); err != nil {
+			return nil, xerrors.Errorf("scan table stats: %w", err)
+		}
+		for _, table := range []string{name, parent} {
+			if table == "" {
+				continue
+			}
+			s := stats[table]
+			s.rows += stat.rows
+			s.size += stat.size
+			stats[table] = s
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("get table stats: %w", err)
+	}
+	return stats, nil
+}
+
+// estimate returns the tables processed by the pending migrations, most
+// expensive first. Tables that don't exist yet are empty.
+func estimate(pending []migrations.Migration, stats map[string]tableStat, rowsPerSecond int64) []TableEstimate {
+	operations := make(map[string]int)
+	for _, migration := range pending {
+		for _, table := range processedTables(migration.SQL) {
+			operations[table]++
+		}
+	}
+
+	estimates := make([]TableEstimate, 0, len(operations))
+	for table, count := range operations {
+		stat := stats[table]
+		estimates = append(estimates, TableEstimate{
+			Table:      table,
+			Rows:       stat.rows,
+			Size:       stat.size,
+			Operations: count,
+			Duration:   time.Duration(float64(int64(count)*stat.rows) / float64(rowsPerSecond) * float64(time.Second)),
+		})
+	}
+	slices.SortFunc(estimates, func(a, b TableEstimate) int {
+		if c := cmp.Compare(b.Duration, a.Duration); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Table, b.Table)
+	})
+	return estimates
+}
+
+var (
+	lineComment  = regexp.MustCompile(`--[^\n]*`)
+	dollarQuoted = regexp.MustCompile(`(?s)\$\$.*?\$\$`)
+	createIndex  = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\b.*?\bON\s+(?:ONLY\s+)?(?:public\.)?(\w+)`)
+	alterTable   = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(?:public\.)?(\w+)\s+(.*)$`)
+	update       = regexp.MustCompile(`(?is)^UPDATE\s+(?:ONLY\s+)?(?:public\.)?(\w+)`)
+	deleteFrom   = regexp.MustCompile(`(?is)^DELETE\s+FROM\s+(?:ONLY\s+)?(?:public\.)?(\w+)`)
+	// Table rewrites and full scans caused by ALTER TABLE. Constraints
+	// added as NOT VALID skip the scan.
+	rewrite       = regexp.MustCompile(`(?is)\bALTER\s+(?:COLUMN\s+)?\w+\s+(?:SET\s+DATA\s+)?TYPE\b|\bSET\s+NOT\s+NULL\b`)
+	addConstraint = regexp.MustCompile(`(?is)\bADD\s+(?:CONSTRAINT\s+\w+\s+)?(?:CHECK|FOREIGN\s+KEY|UNIQUE|PRIMARY\s+KEY)\b`)
+	notValid      = regexp.MustCompile(`(?is)\bNOT\s+VALID\b`)
+)
+
+// processedTables returns the tables whose rows are all processed by a
+// statement of the migration, once per statement. Statements that only
+// change the catalog, like adding a nullable column, are fast regardless of
+// the table size and are ignored.
+func processedTables(migration string) []string {
+	migration = lineComment.ReplaceAllString(migration, "")
+	// Function bodies don't process rows when the migration runs.
+	migration = dollarQuoted.ReplaceAllString(migration, "")
+
+	var tables []string
+	for _, statement := range strings.Split(migration, ";") {
+		statement = strings.TrimSpace(statement)
+		if m := createIndex.FindStringSubmatch(statement); m != nil {
+			tables = append(tables, m[1])
+			continue
+		}
+		if m := update.FindStringSubmatch(statement); m != nil {
+			tables = append(tables, m[1])
+			continue
+		}
+		if m := deleteFrom.FindStringSubmatch(statement); m != nil {
+			tables = append(tables, m[1])
+			continue
+		}
+		if m := alterTable.FindStringSubmatch(statement); m != nil {
+			actions := m[2]
+			if rewrite.MatchString(actions) || (addConstraint.MatchString(actions) && !notValid.MatchString(actions)) {
+				tables = append(tables, m[1])
+			}
+		}
+	}
+	return tables
+}
@@ -0,0 +1,98 @@
+package dbcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/migrations"
+)
+
+func TestProcessedTables(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		migration string
+		expected  []string
+	}{
+		{
+			name:      "AddNullableColumn",
+			migration: "ALTER TABLE workspace_builds ADD COLUMN note text;",
+		},
+		{
+			name:      "CreateTable",
+			migration: "CREATE TABLE foo (id uuid PRIMARY KEY);",
+		},
+		{
+			name:      "CreateIndex",
+			migration: "CREATE INDEX IF NOT EXISTS idx_logs_job ON provisioner_job_logs USING btree (job_id);",
+			expected:  []string{"provisioner_job_logs"},
+		},
+		{
+			name:      "CreateUniqueIndexWithoutName",
+			migration: "CREATE UNIQUE INDEX ON ONLY public.users (email);",
+			expected:  []string{"users"},
+		},
+		{
+			name:      "ChangeColumnType",
+			migration: "ALTER TABLE ONLY workspace_agent_stats ALTER COLUMN connection_count TYPE bigint;",
+			expected:  []string{"workspace_agent_stats"},
+		},
+		{
+			name:      "SetNotNull",
+			migration: "ALTER TABLE users\n\tALTER COLUMN name SET NOT NULL;",
+			expected:  []string{"users"},
+		},
+		{
+			name:      "AddForeignKey",
+			migration: "ALTER TABLE files ADD CONSTRAINT files_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id);",
+			expected:  []string{"files"},
+		},
+		{
+			name:      "AddForeignKeyNotValid",
+			migration: "ALTER TABLE files ADD CONSTRAINT files_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) NOT VALID;",
+		},
+		{
+			name:      "Backfill",
+			migration: "-- Backfill the new column.\nUPDATE workspaces SET automatic_updates = 'never';\nDELETE FROM audit_logs WHERE action = 'login';",
+			expected:  []string{"workspaces", "audit_logs"},
+		},
+		{
+			name: "FunctionBody",
+			migration: `CREATE FUNCTION delete_stuff() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	DELETE FROM api_keys WHERE user_id = OLD.id;
+	RETURN NEW;
+END;
+$$;
+CREATE INDEX idx ON api_keys (user_id);`,
+			expected: []string{"api_keys"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, processedTables(tc.migration))
+		})
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	t.Parallel()
+
+	pending := []migrations.Migration{
+		{Version: 1, SQL: "CREATE INDEX a ON provisioner_job_logs (job_id); CREATE INDEX b ON provisioner_job_logs (created_at);"},
+		{Version: 2, SQL: "UPDATE users SET name = ''; CREATE INDEX c ON new_table (id);"},
+	}
+	stats := map[string]tableStat{
+		"provisioner_job_logs": {rows: 10_000_000, size: 1 << 30},
+		"users":                {rows: 1000, size: 1 << 20},
+	}
+	estimates := estimate(pending, stats, 100_000)
+	require.Equal(t, []TableEstimate{
+		{Table: "provisioner_job_logs", Rows: 10_000_000, Size: 1 << 30, Operations: 2, Duration: 200 * time.Second},
+		{Table: "users", Rows: 1000, Size: 1 << 20, Operations: 1, Duration: 10 * time.Millisecond},
+		{Table: "new_table", Operations: 1},
+	}, estimates)
+}
@@ -0,0 +1,59 @@
+package dbcheck_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/database/dbcheck"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("test requires postgres")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	connection, err := dbtestutil.Open(t)
+	require.NoError(t, err)
+	db, err := sql.Open("postgres", connection)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	// Hold a transaction open so it is reported.
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = tx.Rollback() })
+	_, err = tx.ExecContext(ctx, "SELECT 1")
+	require.NoError(t, err)
+
+	report, err := dbcheck.Run(ctx, db, dbcheck.Options{
+		LongTransactionThreshold: 0,
+		RowsPerSecond:            1000,
+	})
+	require.NoError(t, err)
+	require.False(t, report.Failed())
+	statuses := make(map[string]dbcheck.Status)
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	require.Equal(t, dbcheck.StatusOK, statuses["postgres_version"])
+	require.Equal(t, dbcheck.StatusOK, statuses["extensions"])
+	require.Equal(t, dbcheck.StatusWarning, statuses["long_running_transactions"])
+	require.Equal(t, dbcheck.StatusOK, statuses["migrations"])
+	require.NotEmpty(t, report.LongTransactions)
+	require.NotZero(t, report.CurrentVersion)
+	require.Empty(t, report.PendingMigrations)
+	require.Empty(t, report.Tables)
+	require.Equal(t, time.Duration(0), report.EstimatedDuration)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	coderdpubsub "github.com/coder/coder/v2/coderd/pubsub"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/wspubsub"
@@ -498,7 +499,7 @@ func (b JobCompleteBuilder) Do() JobCompleteResponse {
 	})
 	require.NoError(b.t, err, "complete job")
 	if b.ps != nil {
-		data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
+		data, err := coderdpubsub.ProvisionerJobLogsNotify.Encode(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
 		require.NoError(b.t, err)
 		err = b.ps.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(b.jobID), data)
 		require.NoError(b.t, err)
@@ -138,6 +138,7 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
 		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
 		TokenName:       takeFirst(seed.TokenName),
+		BoundIdentity:   takeFirst(seed.BoundIdentity),
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -145,16 +146,33 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 
 func WorkspaceAgentPortShare(t testing.TB, db database.Store, orig database.WorkspaceAgentPortShare) database.WorkspaceAgentPortShare {
 	ps, err := db.UpsertWorkspaceAgentPortShare(genCtx, database.UpsertWorkspaceAgentPortShareParams{
-		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
-		AgentName:   takeFirst(orig.AgentName, testutil.GetRandomName(t)),
-		Port:        takeFirst(orig.Port, 8080),
-		ShareLevel:  takeFirst(orig.ShareLevel, database.AppSharingLevelPublic),
-		Protocol:    takeFirst(orig.Protocol, database.PortShareProtocolHttp),
+		WorkspaceID:        takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentName:          takeFirst(orig.AgentName, testutil.GetRandomName(t)),
+		Port:               takeFirst(orig.Port, 8080),
+		ShareLevel:         takeFirst(orig.ShareLevel, database.AppSharingLevelPublic),
+		Protocol:           takeFirst(orig.Protocol, database.PortShareProtocolHttp),
+		SharedWithUserIDs:  takeFirstSlice(orig.SharedWithUserIDs, []uuid.UUID{}),
+		SharedWithGroupIDs: takeFirstSlice(orig.SharedWithGroupIDs, []uuid.UUID{}),
+		ExpiresAt:          orig.ExpiresAt,
 	})
 	require.NoError(t, err, "insert workspace agent")
 	return ps
 }
 
+func WorkspaceSessionRecording(t testing.TB, db database.Store, orig database.WorkspaceSessionRecording) database.WorkspaceSessionRecording {
+	recording, err := db.InsertWorkspaceSessionRecording(genCtx, database.InsertWorkspaceSessionRecordingParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentID:     takeFirst(orig.AgentID, uuid.New()),
+		Type:        takeFirst(orig.Type, database.WorkspaceSessionRecordingTypeSsh),
+		Width:       takeFirst(orig.Width, 80),
+		Height:      takeFirst(orig.Height, 24),
+		StartedAt:   takeFirst(orig.StartedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert workspace session recording")
+	return recording
+}
+
 func WorkspaceAgent(t testing.TB, db database.Store, orig database.WorkspaceAgent) database.WorkspaceAgent {
 	agt, err := db.InsertWorkspaceAgent(genCtx, database.InsertWorkspaceAgentParams{
 		ID:         takeFirst(orig.ID, uuid.New()),
@@ -189,6 +207,8 @@ func WorkspaceAgent(t testing.TB, db database.Store, orig database.WorkspaceAgen
 		DisplayApps:              append([]database.DisplayApp{}, orig.DisplayApps...),
 		DisplayOrder:             takeFirst(orig.DisplayOrder, 1),
 		APIKeyScope:              takeFirst(orig.APIKeyScope, database.AgentKeyScopeEnumAll),
+		DisplayGroup:             takeFirst(orig.DisplayGroup, ""),
+		Collapsed:                takeFirst(orig.Collapsed, false),
 	})
 	require.NoError(t, err, "insert workspace agent")
 	if orig.FirstConnectedAt.Valid || orig.LastConnectedAt.Valid || orig.DisconnectedAt.Valid || orig.LastConnectedReplicaID.Valid {
@@ -257,6 +277,7 @@ func WorkspaceAgentScript(t testing.TB, db database.Store, orig database.Workspa
 		StartBlocksLogin: []bool{takeFirst(orig.StartBlocksLogin, false)},
 		RunOnStart:       []bool{takeFirst(orig.RunOnStart, false)},
 		RunOnStop:        []bool{takeFirst(orig.RunOnStop, false)},
+		RunOnClaim:       []bool{takeFirst(orig.RunOnClaim, false)},
 		TimeoutSeconds:   []int32{takeFirst(orig.TimeoutSeconds, 0)},
 		DisplayName:      []string{takeFirst(orig.DisplayName, "")},
 		ID:               []uuid.UUID{takeFirst(orig.ID, uuid.New())},
@@ -389,6 +410,8 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 				UUID:  uuid.UUID{},
 				Valid: false,
 			}),
+			InitiatorContext: orig.InitiatorContext,
+			Warnings:         orig.Warnings,
 		})
 		if err != nil {
 			return err
@@ -422,6 +445,111 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 	return build
 }
 
+// HungWorkspaceBuildSeed seeds HungWorkspaceBuild.
+type HungWorkspaceBuildSeed struct {
+	// Job seeds the timestamps of the provisioner job of the build. If
+	// CreatedAt is zero, the job started ten minutes ago and was last updated
+	// six minutes ago, which is past the hung threshold of the job reaper.
+	// Otherwise the timestamps are used as is, and the job is pending unless
+	// StartedAt is set.
+	Job database.ProvisionerJob
+	// ProvisionerState is the provisioner state of the build.
+	ProvisionerState []byte
+	// PreviousBuildState, if set, creates a completed build with this
+	// provisioner state before the hung build.
+	PreviousBuildState []byte
+}
+
+// HungWorkspaceBuildChain is the chain of objects created by
+// HungWorkspaceBuild.
+type HungWorkspaceBuildChain struct {
+	Organization    database.Organization
+	User            database.User
+	Template        database.Template
+	TemplateVersion database.TemplateVersion
+	Workspace       database.WorkspaceTable
+	PreviousBuild   *database.WorkspaceBuild
+	Job             database.ProvisionerJob
+	Build           database.WorkspaceBuild
+}
+
+// HungWorkspaceBuild creates a workspace build whose provisioner job never
+// completed, along with the organization, user, template, template version
+// and workspace it belongs to.
+func HungWorkspaceBuild(t testing.TB, db database.Store, ps pubsub.Pubsub, seed HungWorkspaceBuildSeed) HungWorkspaceBuildChain {
+	t.Helper()
+
+	var chain HungWorkspaceBuildChain
+	chain.Organization = Organization(t, db, database.Organization{})
+	chain.User = User(t, db, database.User{})
+	chain.Template = Template(t, db, database.Template{
+		OrganizationID: chain.Organization.ID,
+		CreatedBy:      chain.User.ID,
+	})
+	chain.TemplateVersion = TemplateVersion(t, db, database.TemplateVersion{
+		OrganizationID: chain.Organization.ID,
+		TemplateID:     uuid.NullUUID{UUID: chain.Template.ID, Valid: true},
+		CreatedBy:      chain.User.ID,
+	})
+	chain.Workspace = Workspace(t, db, database.WorkspaceTable{
+		OwnerID:        chain.User.ID,
+		OrganizationID: chain.Organization.ID,
+		TemplateID:     chain.Template.ID,
+	})
+	file := File(t, db, database.File{CreatedBy: chain.User.ID})
+
+	job := seed.Job
+	if job.CreatedAt.IsZero() {
+		now := dbtime.Now()
+		job.CreatedAt = now.Add(-10 * time.Minute)
+		job.UpdatedAt = now.Add(-6 * time.Minute)
+		job.StartedAt = sql.NullTime{Time: job.CreatedAt, Valid: true}
+	}
+	job.UpdatedAt = takeFirst(job.UpdatedAt, job.CreatedAt)
+
+	buildNumber := int32(1)
+	if seed.PreviousBuildState != nil {
+		previousJob := ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			CreatedAt:      job.CreatedAt.Add(-10 * time.Minute),
+			UpdatedAt:      job.CreatedAt.Add(-10 * time.Minute),
+			StartedAt:      sql.NullTime{Time: job.CreatedAt.Add(-10 * time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: job.CreatedAt.Add(-10 * time.Minute), Valid: true},
+			OrganizationID: chain.Organization.ID,
+			InitiatorID:    chain.User.ID,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		previousBuild := WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       chain.Workspace.ID,
+			TemplateVersionID: chain.TemplateVersion.ID,
+			BuildNumber:       buildNumber,
+			ProvisionerState:  seed.PreviousBuildState,
+			JobID:             previousJob.ID,
+		})
+		chain.PreviousBuild = &previousBuild
+		buildNumber++
+	}
+
+	chain.Job = ProvisionerJob(t, db, ps, database.ProvisionerJob{
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+		StartedAt:      job.StartedAt,
+		CanceledAt:     job.CanceledAt,
+		OrganizationID: chain.Organization.ID,
+		InitiatorID:    chain.User.ID,
+		FileID:         file.ID,
+		Type:           database.ProvisionerJobTypeWorkspaceBuild,
+	})
+	chain.Build = WorkspaceBuild(t, db, database.WorkspaceBuild{
+		WorkspaceID:       chain.Workspace.ID,
+		TemplateVersionID: chain.TemplateVersion.ID,
+		BuildNumber:       buildNumber,
+		ProvisionerState:  seed.ProvisionerState,
+		JobID:             chain.Job.ID,
+	})
+	return chain
+}
+
 func WorkspaceBuildParameters(t testing.TB, db database.Store, orig []database.WorkspaceBuildParameter) []database.WorkspaceBuildParameter {
 	if len(orig) == 0 {
 		return nil
@@ -511,6 +639,7 @@ func Organization(t testing.TB, db database.Store, orig database.Organization) d
 		DisplayName: takeFirst(orig.Name, testutil.GetRandomName(t)),
 		Description: takeFirst(orig.Description, testutil.GetRandomName(t)),
 		Icon:        takeFirst(orig.Icon, ""),
+		CostCenter:  takeFirst(orig.CostCenter, ""),
 		CreatedAt:   takeFirst(orig.CreatedAt, dbtime.Now()),
 		UpdatedAt:   takeFirst(orig.UpdatedAt, dbtime.Now()),
 	})
@@ -569,6 +698,7 @@ func Group(t testing.TB, db database.Store, orig database.Group) database.Group
 		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
 		AvatarURL:      takeFirst(orig.AvatarURL, "https://logo.example.com"),
 		QuotaAllowance: takeFirst(orig.QuotaAllowance, 0),
+		MonthlyBudget:  takeFirst(orig.MonthlyBudget, 0),
 	})
 	require.NoError(t, err, "insert group")
 	return group
@@ -751,6 +881,53 @@ func ProvisionerJob(t testing.TB, db database.Store, ps pubsub.Pubsub, orig data
 	return job
 }
 
+func BuildAlertRule(t testing.TB, db database.Store, orig database.BuildAlertRule) database.BuildAlertRule {
+	rule, err := db.InsertBuildAlertRule(genCtx, database.InsertBuildAlertRuleParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		TemplateID:     orig.TemplateID,
+		Name:           takeFirst(orig.Name, testutil.GetRandomName(t)),
+		Metric:         takeFirst(orig.Metric, database.BuildAlertRuleMetricFailureRate),
+		Threshold:      takeFirst(orig.Threshold, 10),
+		WindowMs:       takeFirst(orig.WindowMs, (15 * time.Minute).Milliseconds()),
+		CreatedBy:      orig.CreatedBy,
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert build alert rule")
+	return rule
+}
+
+func AccessRequest(t testing.TB, db database.Store, orig database.AccessRequest) database.AccessRequest {
+	req, err := db.InsertAccessRequest(genCtx, database.InsertAccessRequestParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		RequesterID:    takeFirst(orig.RequesterID, uuid.New()),
+		ResourceType:   takeFirst(orig.ResourceType, database.AccessRequestResourceTypeTemplate),
+		ResourceID:     takeFirst(orig.ResourceID, uuid.New()),
+		Role:           takeFirst(orig.Role, "use"),
+		Reason:         takeFirst(orig.Reason, testutil.GetRandomName(t)),
+		DurationMs:     takeFirst(orig.DurationMs, time.Hour.Milliseconds()),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert access request")
+	return req
+}
+
+func UserActivityExport(t testing.TB, db database.Store, orig database.UserActivityExport) database.UserActivityExport {
+	export, err := db.InsertUserActivityExport(genCtx, database.InsertUserActivityExportParams{
+		ID:        takeFirst(orig.ID, uuid.New()),
+		CreatedBy: takeFirst(orig.CreatedBy, uuid.New()),
+		CreatedAt: takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt: takeFirst(orig.UpdatedAt, dbtime.Now()),
+		StartTime: takeFirst(orig.StartTime, dbtime.Now().Add(-24*time.Hour)),
+		EndTime:   takeFirst(orig.EndTime, dbtime.Now()),
+		Format:    takeFirst(orig.Format, database.UserActivityExportFormatCsv),
+	})
+	require.NoError(t, err, "insert user activity export")
+	return export
+}
+
 func ProvisionerKey(t testing.TB, db database.Store, orig database.ProvisionerKey) database.ProvisionerKey {
 	key, err := db.InsertProvisionerKey(genCtx, database.InsertProvisionerKeyParams{
 		ID:             takeFirst(orig.ID, uuid.New()),
@@ -759,11 +936,24 @@ func ProvisionerKey(t testing.TB, db database.Store, orig database.ProvisionerKe
 		Name:           takeFirst(orig.Name, testutil.GetRandomName(t)),
 		HashedSecret:   orig.HashedSecret,
 		Tags:           orig.Tags,
+		ExpiresAt:      orig.ExpiresAt,
 	})
 	require.NoError(t, err, "insert provisioner key")
 	return key
 }
 
+func ServiceAccount(t testing.TB, db database.Store, orig database.ServiceAccount) database.ServiceAccount {
+	serviceAccount, err := db.InsertServiceAccount(genCtx, database.InsertServiceAccountParams{
+		UserID:         takeFirst(orig.UserID, uuid.New()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		Description:    orig.Description,
+		CreatedBy:      orig.CreatedBy,
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert service account")
+	return serviceAccount
+}
+
 func WorkspaceApp(t testing.TB, db database.Store, orig database.WorkspaceApp) database.WorkspaceApp {
 	resource, err := db.UpsertWorkspaceApp(genCtx, database.UpsertWorkspaceAppParams{
 		ID:          takeFirst(orig.ID, uuid.New()),
@@ -846,7 +1036,9 @@ func WorkspaceResource(t testing.TB, db database.Store, orig database.WorkspaceR
 		ModulePath: sql.NullString{
 			String: takeFirst(orig.ModulePath.String, ""),
 			Valid:  takeFirst(orig.ModulePath.Valid, true),
-		},
+		}, DisplayGroup: takeFirst(orig.DisplayGroup, ""),
+		DisplayOrder: takeFirst(orig.DisplayOrder, 0),
+		Collapsed:    takeFirst(orig.Collapsed, false),
 	})
 	require.NoError(t, err, "insert resource")
 	return resource
@@ -1008,6 +1200,7 @@ func TemplateVersionVariable(t testing.TB, db database.Store, orig database.Temp
 		DefaultValue:      takeFirst(orig.DefaultValue, testutil.GetRandomName(t)),
 		Required:          takeFirst(orig.Required, false),
 		Sensitive:         takeFirst(orig.Sensitive, false),
+		ValueKeyID:        takeFirst(orig.ValueKeyID, sql.NullString{}),
 	})
 	require.NoError(t, err, "insert template version variable")
 	return version
@@ -1155,6 +1348,25 @@ func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAut
 	return app
 }
 
+func LoginDeviceCode(t testing.TB, db database.Store, seed database.LoginDeviceCode) database.LoginDeviceCode {
+	code, err := db.InsertLoginDeviceCode(genCtx, database.InsertLoginDeviceCodeParams{
+		ID:             takeFirst(seed.ID, uuid.New()),
+		DeviceCodeHash: takeFirstSlice(seed.DeviceCodeHash, []byte(uuid.NewString())),
+		UserCode:       takeFirst(seed.UserCode, testutil.GetRandomName(t)),
+		CreatedAt:      takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:      takeFirst(seed.ExpiresAt, dbtime.Now().Add(10*time.Minute)),
+	})
+	require.NoError(t, err, "insert login device code")
+	if seed.UserID.Valid {
+		code, err = db.ApproveLoginDeviceCode(genCtx, database.ApproveLoginDeviceCodeParams{
+			ID:     code.ID,
+			UserID: seed.UserID.UUID,
+		})
+		require.NoError(t, err, "approve login device code")
+	}
+	return code
+}
+
 func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
 	code, err := db.InsertOAuth2ProviderAppCode(genCtx, database.InsertOAuth2ProviderAppCodeParams{
 		ID:           takeFirst(seed.ID, uuid.New()),
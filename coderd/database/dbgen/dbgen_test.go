@@ -204,6 +204,19 @@ func TestGenerator(t *testing.T) {
 		require.Equal(t, exp, must(db.GetWorkspaceBuildByID(context.Background(), exp.ID)))
 	})
 
+	t.Run("HungWorkspaceBuild", func(t *testing.T) {
+		t.Parallel()
+		db, _ := dbtestutil.NewDB(t)
+		chain := dbgen.HungWorkspaceBuild(t, db, nil, dbgen.HungWorkspaceBuildSeed{
+			PreviousBuildState: []byte("state"),
+		})
+		require.NotNil(t, chain.PreviousBuild)
+		require.Equal(t, int32(2), chain.Build.BuildNumber)
+		job := must(db.GetProvisionerJobByID(context.Background(), chain.Job.ID))
+		require.True(t, job.StartedAt.Valid)
+		require.False(t, job.CompletedAt.Valid)
+	})
+
 	t.Run("User", func(t *testing.T) {
 		t.Parallel()
 		db, _ := dbtestutil.NewDB(t)
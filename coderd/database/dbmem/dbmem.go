@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -64,6 +65,7 @@ func New() database.Store {
 			groups:                         make([]database.Group, 0),
 			groupMembers:                   make([]database.GroupMemberTable, 0),
 			licenses:                       make([]database.License, 0),
+			loginDeviceCodes:               make([]database.LoginDeviceCode, 0),
 			locks:                          map[int64]struct{}{},
 			notificationMessages:           make([]database.NotificationMessage, 0),
 			notificationPreferences:        make([]database.NotificationPreference, 0),
@@ -214,73 +216,110 @@ type data struct {
 	userLinks           []database.UserLink
 
 	// New tables
-	auditLogs                            []database.AuditLog
-	cryptoKeys                           []database.CryptoKey
-	dbcryptKeys                          []database.DBCryptKey
-	files                                []database.File
-	externalAuthLinks                    []database.ExternalAuthLink
-	gitSSHKey                            []database.GitSSHKey
-	groupMembers                         []database.GroupMemberTable
-	groups                               []database.Group
-	licenses                             []database.License
-	notificationMessages                 []database.NotificationMessage
-	notificationPreferences              []database.NotificationPreference
-	notificationReportGeneratorLogs      []database.NotificationReportGeneratorLog
-	inboxNotifications                   []database.InboxNotification
-	oauth2ProviderApps                   []database.OAuth2ProviderApp
-	oauth2ProviderAppSecrets             []database.OAuth2ProviderAppSecret
-	oauth2ProviderAppCodes               []database.OAuth2ProviderAppCode
-	oauth2ProviderAppTokens              []database.OAuth2ProviderAppToken
-	parameterSchemas                     []database.ParameterSchema
-	provisionerDaemons                   []database.ProvisionerDaemon
-	provisionerJobLogs                   []database.ProvisionerJobLog
-	provisionerJobs                      []database.ProvisionerJob
-	provisionerKeys                      []database.ProvisionerKey
-	replicas                             []database.Replica
-	templateVersions                     []database.TemplateVersionTable
-	templateVersionParameters            []database.TemplateVersionParameter
-	templateVersionTerraformValues       []database.TemplateVersionTerraformValue
-	templateVersionVariables             []database.TemplateVersionVariable
-	templateVersionWorkspaceTags         []database.TemplateVersionWorkspaceTag
-	templates                            []database.TemplateTable
-	templateUsageStats                   []database.TemplateUsageStat
-	userConfigs                          []database.UserConfig
-	webpushSubscriptions                 []database.WebpushSubscription
-	workspaceAgents                      []database.WorkspaceAgent
-	workspaceAgentMetadata               []database.WorkspaceAgentMetadatum
-	workspaceAgentLogs                   []database.WorkspaceAgentLog
-	workspaceAgentLogSources             []database.WorkspaceAgentLogSource
-	workspaceAgentPortShares             []database.WorkspaceAgentPortShare
-	workspaceAgentScriptTimings          []database.WorkspaceAgentScriptTiming
-	workspaceAgentScripts                []database.WorkspaceAgentScript
-	workspaceAgentStats                  []database.WorkspaceAgentStat
-	workspaceAgentMemoryResourceMonitors []database.WorkspaceAgentMemoryResourceMonitor
-	workspaceAgentVolumeResourceMonitors []database.WorkspaceAgentVolumeResourceMonitor
-	workspaceAgentDevcontainers          []database.WorkspaceAgentDevcontainer
-	workspaceApps                        []database.WorkspaceApp
-	workspaceAppStatuses                 []database.WorkspaceAppStatus
-	workspaceAppAuditSessions            []database.WorkspaceAppAuditSession
-	workspaceAppStatsLastInsertID        int64
-	workspaceAppStats                    []database.WorkspaceAppStat
-	workspaceBuilds                      []database.WorkspaceBuild
-	workspaceBuildParameters             []database.WorkspaceBuildParameter
-	workspaceResourceMetadata            []database.WorkspaceResourceMetadatum
-	workspaceResources                   []database.WorkspaceResource
-	workspaceModules                     []database.WorkspaceModule
-	workspaces                           []database.WorkspaceTable
-	workspaceProxies                     []database.WorkspaceProxy
-	customRoles                          []database.CustomRole
-	provisionerJobTimings                []database.ProvisionerJobTiming
-	runtimeConfig                        map[string]string
+	accessRequests                              []database.AccessRequest
+	auditLogs                                   []database.AuditLog
+	buildAlertRules                             []database.BuildAlertRule
+	cryptoKeys                                  []database.CryptoKey
+	dbcryptKeys                                 []database.DBCryptKey
+	files                                       []database.File
+	externalAuthLinks                           []database.ExternalAuthLink
+	gitSSHKey                                   []database.GitSSHKey
+	groupMembers                                []database.GroupMemberTable
+	groups                                      []database.Group
+	licenses                                    []database.License
+	loginDeviceCodes                            []database.LoginDeviceCode
+	notificationMessages                        []database.NotificationMessage
+	notificationPreferences                     []database.NotificationPreference
+	notificationReportGeneratorLogs             []database.NotificationReportGeneratorLog
+	inboxNotifications                          []database.InboxNotification
+	oauth2ProviderApps                          []database.OAuth2ProviderApp
+	oauth2ProviderAppSecrets                    []database.OAuth2ProviderAppSecret
+	oauth2ProviderAppCodes                      []database.OAuth2ProviderAppCode
+	oauth2ProviderAppTokens                     []database.OAuth2ProviderAppToken
+	parameterSchemas                            []database.ParameterSchema
+	provisionerDaemons                          []database.ProvisionerDaemon
+	provisionerJobLogs                          []database.ProvisionerJobLog
+	provisionerJobLogArchives                   []database.ProvisionerJobLogArchive
+	provisionerJobs                             []database.ProvisionerJob
+	provisionerKeys                             []database.ProvisionerKey
+	provisionerReservations                     []database.ProvisionerReservation
+	provisionerBuildPauses                      []database.ProvisionerBuildPause
+	replicas                                    []database.Replica
+	serviceAccounts                             []database.ServiceAccount
+	templatePresets                             []database.TemplatePreset
+	templatePresetGroupDefaults                 []database.TemplatePresetGroupDefault
+	templateReleaseChannels                     []database.TemplateReleaseChannel
+	templateShares                              []database.TemplateShare
+	templateVersions                            []database.TemplateVersionTable
+	templateVersionParameters                   []database.TemplateVersionParameter
+	templateVersionRollouts                     []database.TemplateVersionRollout
+	templateVersionTerraformValues              []database.TemplateVersionTerraformValue
+	templateVersionVariables                    []database.TemplateVersionVariable
+	templateVersionWorkspaceTags                []database.TemplateVersionWorkspaceTag
+	templates                                   []database.TemplateTable
+	templateBuildDurationStats                  []database.TemplateBuildDurationStat
+	templateUsageStats                          []database.TemplateUsageStat
+	userActivityExports                         []database.UserActivityExport
+	userConfigs                                 []database.UserConfig
+	webpushSubscriptions                        []database.WebpushSubscription
+	workspaceAgents                             []database.WorkspaceAgent
+	workspaceAgentHealthEvents                  []database.WorkspaceAgentHealthEvent
+	workspaceAgentConnectionQuality             []database.WorkspaceAgentConnectionQuality
+	workspaceAgentMetadata                      []database.WorkspaceAgentMetadatum
+	workspaceAgentLogs                          []database.WorkspaceAgentLog
+	workspaceAgentLogSources                    []database.WorkspaceAgentLogSource
+	workspaceAgentPortShares                    []database.WorkspaceAgentPortShare
+	workspaceAgentResourceUsage                 []database.WorkspaceAgentResourceUsage
+	workspaceAgentScriptTimings                 []database.WorkspaceAgentScriptTiming
+	workspaceAgentScripts                       []database.WorkspaceAgentScript
+	workspaceAgentStats                         []database.WorkspaceAgentStat
+	workspaceAgentMemoryResourceMonitors        []database.WorkspaceAgentMemoryResourceMonitor
+	workspaceAgentVolumeResourceMonitors        []database.WorkspaceAgentVolumeResourceMonitor
+	workspaceAgentDevcontainers                 []database.WorkspaceAgentDevcontainer
+	workspaceApps                               []database.WorkspaceApp
+	workspaceAppStatuses                        []database.WorkspaceAppStatus
+	workspaceAppAuditSessions                   []database.WorkspaceAppAuditSession
+	workspaceAppStatsLastInsertID               int64
+	workspaceAppStats                           []database.WorkspaceAppStat
+	workspaceBuilds                             []database.WorkspaceBuild
+	workspaceBuildArchives                      []database.WorkspaceBuildArchive
+	workspaceBuildDurationNotifications         []database.WorkspaceBuildDurationNotification
+	workspaceBuildIdempotencyKeys               []database.WorkspaceBuildIdempotencyKey
+	workspaceBuildInterimStates                 []database.WorkspaceBuildInterimState
+	workspaceBuildParameters                    []database.WorkspaceBuildParameter
+	workspaceBuildQueue                         []database.WorkspaceBuildQueue
+	workspaceDailyCosts                         []database.WorkspaceDailyCost
+	workspaceDriftChecks                        []database.WorkspaceDriftCheck
+	workspaceLabels                             []database.WorkspaceLabel
+	workspaceLocks                              []database.WorkspaceLock
+	workspaceMigrations                         []database.WorkspaceMigration
+	workspaceResourceMetadata                   []database.WorkspaceResourceMetadatum
+	workspaceResources                          []database.WorkspaceResource
+	workspaceModules                            []database.WorkspaceModule
+	workspaceNamingPolicies                     []database.WorkspaceNamingPolicy
+	workspacePrebuildReservations               []database.WorkspacePrebuildReservation
+	workspaceProvisionerAffinities              []database.WorkspaceProvisionerAffinity
+	workspaceSessionRecordings                  []database.WorkspaceSessionRecording
+	workspaceSessionRecordingChunks             []database.WorkspaceSessionRecordingChunk
+	workspaceSessionRecordingChunksLastInsertID int64
+	workspaces                                  []database.WorkspaceTable
+	workspaceProxies                            []database.WorkspaceProxy
+	workspaceReleaseChannels                    []database.WorkspaceReleaseChannel
+	customRoles                                 []database.CustomRole
+	provisionerJobTimings                       []database.ProvisionerJobTiming
+	runtimeConfig                               map[string]string
 	// Locks is a map of lock names. Any keys within the map are currently
 	// locked.
 	locks                            map[int64]struct{}
 	deploymentID                     string
 	derpMeshKey                      string
 	lastUpdateCheck                  []byte
+	jobReaperLastRunAt               []byte
+	dbPurgeStats                     []byte
 	announcementBanners              []byte
 	healthSettings                   []byte
 	notificationsSettings            []byte
+	readOnlySettings                 []byte
 	oauth2GithubDefaultEligible      *bool
 	applicationName                  string
 	logoURL                          string
@@ -526,6 +565,8 @@ func (q *FakeQuerier) convertToWorkspaceRowsNoLock(ctx context.Context, workspac
 			AutomaticUpdates:  w.AutomaticUpdates,
 			Favorite:          w.Favorite,
 			NextStartAt:       w.NextStartAt,
+			GroupACL:          w.GroupACL,
+			UserACL:           w.UserACL,
 
 			OwnerAvatarUrl: extended.OwnerAvatarUrl,
 			OwnerUsername:  extended.OwnerUsername,
@@ -739,6 +780,15 @@ func (q *FakeQuerier) templateWithNameNoLock(tpl database.TemplateTable) databas
 	withNames.OrganizationName = org.Name
 	withNames.OrganizationDisplayName = org.DisplayName
 	withNames.OrganizationIcon = org.Icon
+	withNames.SharedOrganizationIDs = []uuid.UUID{}
+	for _, share := range q.templateShares {
+		if share.TemplateID == tpl.ID {
+			withNames.SharedOrganizationIDs = append(withNames.SharedOrganizationIDs, share.OrganizationID)
+		}
+	}
+	slices.SortFunc(withNames.SharedOrganizationIDs, func(a, b uuid.UUID) int {
+		return slice.Ascending(a.String(), b.String())
+	})
 	return withNames
 }
 
@@ -1390,6 +1440,24 @@ func isDeprecated(template database.Template) bool {
 	return template.Deprecated != ""
 }
 
+// compareProvisionerJobs compares provisioner jobs by creation time and then
+// ID.
+func compareProvisionerJobs(a, b database.ProvisionerJob) int {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// compareAuditLogs compares audit logs by time and then ID, which is the order
+// GetAuditLogsOffset returns them in, reversed.
+func compareAuditLogs(a, b database.AuditLog) int {
+	if c := a.Time.Compare(b.Time); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
 func (q *FakeQuerier) getWorkspaceBuildParametersNoLock(workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	params := make([]database.WorkspaceBuildParameter, 0)
 	for _, param := range q.workspaceBuildParameters {
@@ -1401,6 +1469,77 @@ func (q *FakeQuerier) getWorkspaceBuildParametersNoLock(workspaceBuildID uuid.UU
 	return params, nil
 }
 
+// provisionerJobHasAffinityNoLock mirrors the affinity ordering of
+// AcquireProvisionerJob.
+func (q *FakeQuerier) provisionerJobHasAffinityNoLock(job database.ProvisionerJob, workerID uuid.NullUUID) bool {
+	if !workerID.Valid {
+		return false
+	}
+	for _, build := range q.workspaceBuilds {
+		if build.JobID != job.ID {
+			continue
+		}
+		for _, affinity := range q.workspaceProvisionerAffinities {
+			if affinity.WorkspaceID == build.WorkspaceID &&
+				affinity.TemplateVersionID == build.TemplateVersionID &&
+				affinity.ProvisionerDaemonID == workerID.UUID {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// provisionerJobBlockedByReservationNoLock mirrors the reservation check of
+// AcquireProvisionerJob.
+func (q *FakeQuerier) provisionerJobBlockedByReservationNoLock(job database.ProvisionerJob, provisionerTags map[string]string, now time.Time) bool {
+	for _, reservation := range q.provisionerReservations {
+		if reservation.OrganizationID != job.OrganizationID {
+			continue
+		}
+		if reservation.StartsAt.After(now) || !reservation.EndsAt.After(now) {
+			continue
+		}
+		if tagsSubset(reservation.Tags, job.Tags) || !tagsSubset(reservation.Tags, provisionerTags) {
+			continue
+		}
+
+		var running, daemons int32
+		for _, daemon := range q.provisionerDaemons {
+			if !tagsSubset(reservation.Tags, daemon.Tags) {
+				continue
+			}
+			if daemon.OrganizationID == reservation.OrganizationID && daemon.LastSeenAt.Valid && !daemon.LastSeenAt.Time.Before(now.Add(-90*time.Second)) {
+				daemons++
+			}
+			for _, other := range q.provisionerJobs {
+				if other.WorkerID.Valid && other.WorkerID.UUID == daemon.ID &&
+					other.OrganizationID == reservation.OrganizationID &&
+					provisionerJobStatus(other) == database.ProvisionerJobStatusRunning &&
+					!tagsSubset(reservation.Tags, other.Tags) {
+					running++
+				}
+			}
+		}
+		if running+reservation.Slots >= daemons {
+			return true
+		}
+	}
+	return false
+}
+
+// provisionerJobPausedNoLock mirrors the build pause check of
+// AcquireProvisionerJob and GetProvisionerJobsToBeReaped.
+func (q *FakeQuerier) provisionerJobPausedNoLock(job database.ProvisionerJob) bool {
+	for _, pause := range q.provisionerBuildPauses {
+		if !pause.OrganizationID.Valid || pause.OrganizationID.UUID == job.OrganizationID {
+			return true
+		}
+	}
+	return false
+}
+
 func (*FakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
@@ -1459,6 +1598,7 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	selected := -1
 	for index, provisionerJob := range q.provisionerJobs {
 		if provisionerJob.OrganizationID != arg.OrganizationID {
 			continue
@@ -1496,18 +1636,37 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 		if !tagsSubset(provisionerJob.Tags, tags) {
 			continue
 		}
-		provisionerJob.StartedAt = arg.StartedAt
-		provisionerJob.UpdatedAt = arg.StartedAt.Time
-		provisionerJob.WorkerID = arg.WorkerID
-		provisionerJob.JobStatus = provisionerJobStatus(provisionerJob)
-		q.provisionerJobs[index] = provisionerJob
-		// clone the Tags before returning, since maps are reference types and
-		// we don't want the caller to be able to mutate the map we have inside
-		// dbmem!
-		provisionerJob.Tags = maps.Clone(provisionerJob.Tags)
-		return provisionerJob, nil
+		if q.provisionerJobBlockedByReservationNoLock(provisionerJob, tags, arg.StartedAt.Time) {
+			continue
+		}
+		if q.provisionerJobPausedNoLock(provisionerJob) {
+			continue
+		}
+		// Jobs are stored in creation order, so the first eligible job is the
+		// oldest one. A job with affinity to the caller takes precedence.
+		if selected == -1 {
+			selected = index
+		}
+		if q.provisionerJobHasAffinityNoLock(provisionerJob, arg.WorkerID) {
+			selected = index
+			break
+		}
 	}
-	return database.ProvisionerJob{}, sql.ErrNoRows
+	if selected == -1 {
+		return database.ProvisionerJob{}, sql.ErrNoRows
+	}
+
+	provisionerJob := q.provisionerJobs[selected]
+	provisionerJob.StartedAt = arg.StartedAt
+	provisionerJob.UpdatedAt = arg.StartedAt.Time
+	provisionerJob.WorkerID = arg.WorkerID
+	provisionerJob.JobStatus = provisionerJobStatus(provisionerJob)
+	q.provisionerJobs[selected] = provisionerJob
+	// clone the Tags before returning, since maps are reference types and
+	// we don't want the caller to be able to mutate the map we have inside
+	// dbmem!
+	provisionerJob.Tags = maps.Clone(provisionerJob.Tags)
+	return provisionerJob, nil
 }
 
 func (q *FakeQuerier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
@@ -1613,6 +1772,26 @@ func (q *FakeQuerier) AllUserIDs(_ context.Context, includeSystem bool) ([]uuid.
 	return userIDs, nil
 }
 
+func (q *FakeQuerier) ApproveLoginDeviceCode(_ context.Context, arg database.ApproveLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.LoginDeviceCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, code := range q.loginDeviceCodes {
+		if code.ID != arg.ID || code.UserID.Valid {
+			continue
+		}
+		code.UserID = uuid.NullUUID{UUID: arg.UserID, Valid: true}
+		q.loginDeviceCodes[i] = code
+		return code, nil
+	}
+	return database.LoginDeviceCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) ArchiveUnusedTemplateVersions(_ context.Context, arg database.ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1779,10 +1958,84 @@ func (*FakeQuerier) CleanTailnetTunnels(context.Context) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) CompleteWorkspaceMigration(_ context.Context, arg database.CompleteWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, migration := range q.workspaceMigrations {
+		if migration.ID != arg.ID {
+			continue
+		}
+		migration.Status = arg.Status
+		migration.Error = arg.Error
+		migration.DestinationSessionToken = ""
+		migration.DestinationSessionTokenKeyID = sql.NullString{}
+		migration.UpdatedAt = arg.CompletedAt.Time
+		migration.CompletedAt = arg.CompletedAt
+		q.workspaceMigrations[i] = migration
+		return migration, nil
+	}
+	return database.WorkspaceMigration{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) ConsumeApprovedLoginDeviceCode(_ context.Context, arg database.ConsumeApprovedLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.LoginDeviceCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, code := range q.loginDeviceCodes {
+		if !bytes.Equal(code.DeviceCodeHash, arg.DeviceCodeHash) || !code.UserID.Valid || !code.ExpiresAt.After(arg.Now) {
+			continue
+		}
+		q.loginDeviceCodes = append(q.loginDeviceCodes[:i], q.loginDeviceCodes[i+1:]...)
+		return code, nil
+	}
+	return database.LoginDeviceCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) CountInProgressPrebuilds(ctx context.Context) ([]database.CountInProgressPrebuildsRow, error) {
 	return nil, ErrUnimplemented
 }
 
+func (q *FakeQuerier) CountInProgressProvisionerJobsByInitiator(_ context.Context, arg database.CountInProgressProvisionerJobsByInitiatorParams) (database.CountInProgressProvisionerJobsByInitiatorRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.CountInProgressProvisionerJobsByInitiatorRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var row database.CountInProgressProvisionerJobsByInitiatorRow
+	for _, job := range q.provisionerJobs {
+		if job.InitiatorID != arg.InitiatorID || job.CompletedAt.Valid {
+			continue
+		}
+		row.Total++
+
+		for _, build := range q.workspaceBuilds {
+			if build.JobID != job.ID {
+				continue
+			}
+			for _, workspace := range q.workspaces {
+				if workspace.ID == build.WorkspaceID && workspace.TemplateID == arg.TemplateID {
+					row.TemplateBuilds++
+				}
+			}
+		}
+	}
+	return row, nil
+}
+
 func (q *FakeQuerier) CountUnreadInboxNotificationsByUserID(_ context.Context, userID uuid.UUID) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1803,6 +2056,16 @@ func (q *FakeQuerier) CountUnreadInboxNotificationsByUserID(_ context.Context, u
 	return count, nil
 }
 
+func (*FakeQuerier) CreateTimePartition(_ context.Context, arg database.CreateTimePartitionParams) (bool, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return false, err
+	}
+
+	// The in-memory database has no partitions.
+	return false, nil
+}
+
 func (q *FakeQuerier) CustomRoles(_ context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1907,6 +2170,20 @@ func (q *FakeQuerier) DeleteApplicationConnectAPIKeysByUserID(_ context.Context,
 	return nil
 }
 
+func (q *FakeQuerier) DeleteBuildAlertRuleByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, rule := range q.buildAlertRules {
+		if rule.ID == id {
+			q.buildAlertRules = append(q.buildAlertRules[:i], q.buildAlertRules[i+1:]...)
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
 func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
@@ -1961,6 +2238,21 @@ func (q *FakeQuerier) DeleteCustomRole(_ context.Context, arg database.DeleteCus
 	return nil
 }
 
+func (q *FakeQuerier) DeleteExpiredLoginDeviceCodes(_ context.Context, before time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	codes := make([]database.LoginDeviceCode, 0, len(q.loginDeviceCodes))
+	for _, code := range q.loginDeviceCodes {
+		if code.ExpiresAt.Before(before) {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	q.loginDeviceCodes = codes
+	return nil
+}
+
 func (q *FakeQuerier) DeleteExternalAuthLink(_ context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2183,6 +2475,109 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(_ context.Cont
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldAuditLogs(_ context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	old := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if alog.Time.Before(arg.Before) {
+			old = append(old, alog)
+		}
+	}
+	slices.SortFunc(old, func(a, b database.AuditLog) int {
+		return a.Time.Compare(b.Time)
+	})
+	if len(old) > int(arg.LimitCount) {
+		old = old[:arg.LimitCount]
+	}
+	deleted := make(map[uuid.UUID]struct{}, len(old))
+	for _, alog := range old {
+		deleted[alog.ID] = struct{}{}
+	}
+	q.auditLogs = slices.DeleteFunc(q.auditLogs, func(alog database.AuditLog) bool {
+		_, ok := deleted[alog.ID]
+		return ok
+	})
+	return int64(len(deleted)), nil
+}
+
+func (q *FakeQuerier) DeleteOldDeletedWorkspaces(ctx context.Context, before time.Time) (int64, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	latestBuilds := make(map[uuid.UUID]database.WorkspaceBuild)
+	for _, wb := range q.workspaceBuilds {
+		if latest, ok := latestBuilds[wb.WorkspaceID]; ok && latest.BuildNumber > wb.BuildNumber {
+			continue
+		}
+		latestBuilds[wb.WorkspaceID] = wb
+	}
+
+	purgedWorkspaces := make(map[uuid.UUID]struct{})
+	for _, ws := range q.workspaces {
+		if !ws.Deleted {
+			continue
+		}
+		build, ok := latestBuilds[ws.ID]
+		if !ok {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil || !job.CompletedAt.Valid || !job.CompletedAt.Time.Before(before) {
+			continue
+		}
+		purgedWorkspaces[ws.ID] = struct{}{}
+	}
+	if len(purgedWorkspaces) == 0 {
+		return 0, nil
+	}
+
+	purgedJobs := make(map[uuid.UUID]struct{})
+	purgedBuilds := make(map[uuid.UUID]struct{})
+	for _, wb := range q.workspaceBuilds {
+		if _, ok := purgedWorkspaces[wb.WorkspaceID]; ok {
+			purgedJobs[wb.JobID] = struct{}{}
+			purgedBuilds[wb.ID] = struct{}{}
+		}
+	}
+
+	q.provisionerJobs = slices.DeleteFunc(q.provisionerJobs, func(job database.ProvisionerJob) bool {
+		_, ok := purgedJobs[job.ID]
+		return ok
+	})
+	q.provisionerJobLogs = slices.DeleteFunc(q.provisionerJobLogs, func(log database.ProvisionerJobLog) bool {
+		_, ok := purgedJobs[log.JobID]
+		return ok
+	})
+	q.workspaceBuildParameters = slices.DeleteFunc(q.workspaceBuildParameters, func(param database.WorkspaceBuildParameter) bool {
+		_, ok := purgedBuilds[param.WorkspaceBuildID]
+		return ok
+	})
+	q.workspaceBuilds = slices.DeleteFunc(q.workspaceBuilds, func(wb database.WorkspaceBuild) bool {
+		_, ok := purgedBuilds[wb.ID]
+		return ok
+	})
+	q.workspaceAppStats = slices.DeleteFunc(q.workspaceAppStats, func(stat database.WorkspaceAppStat) bool {
+		_, ok := purgedWorkspaces[stat.WorkspaceID]
+		return ok
+	})
+	q.workspaceAppStatuses = slices.DeleteFunc(q.workspaceAppStatuses, func(status database.WorkspaceAppStatus) bool {
+		_, ok := purgedWorkspaces[status.WorkspaceID]
+		return ok
+	})
+	q.workspaces = slices.DeleteFunc(q.workspaces, func(ws database.WorkspaceTable) bool {
+		_, ok := purgedWorkspaces[ws.ID]
+		return ok
+	})
+	return int64(len(purgedWorkspaces)), nil
+}
+
 func (*FakeQuerier) DeleteOldNotificationMessages(_ context.Context) error {
 	return nil
 }
@@ -2206,7 +2601,90 @@ func (q *FakeQuerier) DeleteOldProvisionerDaemons(_ context.Context) error {
 	return nil
 }
 
-func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context, threshold time.Time) error {
+func (q *FakeQuerier) DeleteOldProvisionerJobLogs(_ context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	hasLogs := make(map[uuid.UUID]struct{})
+	for _, log := range q.provisionerJobLogs {
+		hasLogs[log.JobID] = struct{}{}
+	}
+	for _, archive := range q.provisionerJobLogArchives {
+		hasLogs[archive.JobID] = struct{}{}
+	}
+	old := make([]database.ProvisionerJob, 0)
+	for _, job := range q.provisionerJobs {
+		if _, ok := hasLogs[job.ID]; !ok {
+			continue
+		}
+		if job.CompletedAt.Valid && job.CompletedAt.Time.Before(arg.CompletedBefore) {
+			old = append(old, job)
+		}
+	}
+	slices.SortFunc(old, func(a, b database.ProvisionerJob) int {
+		return a.CompletedAt.Time.Compare(b.CompletedAt.Time)
+	})
+	if len(old) > int(arg.LimitCount) {
+		old = old[:arg.LimitCount]
+	}
+	jobs := make(map[uuid.UUID]struct{}, len(old))
+	for _, job := range old {
+		jobs[job.ID] = struct{}{}
+	}
+
+	var deleted int64
+	q.provisionerJobLogs = slices.DeleteFunc(q.provisionerJobLogs, func(log database.ProvisionerJobLog) bool {
+		_, ok := jobs[log.JobID]
+		if ok {
+			deleted++
+		}
+		return ok
+	})
+	files := make(map[uuid.UUID]struct{})
+	q.provisionerJobLogArchives = slices.DeleteFunc(q.provisionerJobLogArchives, func(archive database.ProvisionerJobLogArchive) bool {
+		_, ok := jobs[archive.JobID]
+		if ok {
+			deleted += int64(archive.LogCount)
+			files[archive.FileID] = struct{}{}
+		}
+		return ok
+	})
+	q.files = slices.DeleteFunc(q.files, func(file database.File) bool {
+		_, ok := files[file.ID]
+		return ok
+	})
+	return deleted, nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceAgentConnectionQuality(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceAgentConnectionQuality = slices.DeleteFunc(q.workspaceAgentConnectionQuality, func(quality database.WorkspaceAgentConnectionQuality) bool {
+		return quality.UpdatedAt.Before(beforeTime)
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceAgentHealthEvents(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceAgentHealthEvents = slices.DeleteFunc(q.workspaceAgentHealthEvents, func(event database.WorkspaceAgentHealthEvent) bool {
+		if event.ResolvedAt.Valid {
+			return event.ResolvedAt.Time.Before(beforeTime)
+		}
+		return event.CreatedAt.Before(beforeTime)
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context, threshold time.Time) (int64, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
@@ -2295,11 +2773,22 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context, threshold t
 		}
 		validLogs = append(validLogs, log)
 	}
+	deleted := int64(len(q.workspaceAgentLogs) - len(validLogs))
 	q.workspaceAgentLogs = validLogs
+	return deleted, nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceAgentResourceUsage(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceAgentResourceUsage = slices.DeleteFunc(q.workspaceAgentResourceUsage, func(usage database.WorkspaceAgentResourceUsage) bool {
+		return usage.BucketStart.Before(beforeTime)
+	})
 	return nil
 }
 
-func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
+func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context, before time.Time) (int64, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
@@ -2309,17 +2798,20 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 		WHERE
 			created_at < (
 				SELECT
-					COALESCE(
-						-- When generating initial template usage stats, all the
-						-- raw agent stats are needed, after that only ~30 mins
-						-- from last rollup is needed. Deployment stats seem to
-						-- use between 15 mins and 1 hour of data. We keep a
-						-- little bit more (1 day) just in case.
-						MAX(start_time) - '1 days'::interval,
-						-- Fall back to ~6 months ago if there are no template
-						-- usage stats so that we don't delete the data before
-						-- it's rolled up.
-						NOW() - '180 days'::interval
+					LEAST(
+						COALESCE(
+							-- When generating initial template usage stats, all the
+							-- raw agent stats are needed, after that only ~30 mins
+							-- from last rollup is needed. Deployment stats seem to
+							-- use between 15 mins and 1 hour of data. We keep a
+							-- little bit more (1 day) just in case.
+							MAX(start_time) - '1 days'::interval,
+							-- Fall back to ~6 months ago if there are no template
+							-- usage stats so that we don't delete the data before
+							-- it's rolled up.
+							NOW() - '180 days'::interval
+						),
+						@before :: timestamptz
 					)
 				FROM
 					template_usage_stats
@@ -2347,6 +2839,10 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 	if limit.IsZero() {
 		limit = now.AddDate(0, 0, -180)
 	}
+	// LEAST
+	if before.Before(limit) {
+		limit = before
+	}
 
 	var validStats []database.WorkspaceAgentStat
 	var batchLimit time.Time
@@ -2366,20 +2862,55 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 		}
 		validStats = append(validStats, stat)
 	}
+	deleted := int64(len(q.workspaceAgentStats) - len(validStats))
 	q.workspaceAgentStats = validStats
-	return nil
+	return deleted, nil
 }
 
-func (q *FakeQuerier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
-	err := validateDatabaseType(arg)
-	if err != nil {
-		return err
-	}
-
+func (q *FakeQuerier) DeleteOldWorkspaceBuildIdempotencyKeys(_ context.Context, before time.Time) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	deleted := false
+	keys := make([]database.WorkspaceBuildIdempotencyKey, 0, len(q.workspaceBuildIdempotencyKeys))
+	for _, key := range q.workspaceBuildIdempotencyKeys {
+		if key.CreatedAt.Before(before) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	q.workspaceBuildIdempotencyKeys = keys
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceSessionRecordings(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	deleted := make(map[uuid.UUID]struct{})
+	q.workspaceSessionRecordings = slices.DeleteFunc(q.workspaceSessionRecordings, func(recording database.WorkspaceSessionRecording) bool {
+		if recording.StartedAt.Before(beforeTime) {
+			deleted[recording.ID] = struct{}{}
+			return true
+		}
+		return false
+	})
+	q.workspaceSessionRecordingChunks = slices.DeleteFunc(q.workspaceSessionRecordingChunks, func(chunk database.WorkspaceSessionRecordingChunk) bool {
+		_, ok := deleted[chunk.RecordingID]
+		return ok
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	deleted := false
 	q.data.organizationMembers = slices.DeleteFunc(q.data.organizationMembers, func(member database.OrganizationMember) bool {
 		match := member.OrganizationID == arg.OrganizationID && member.UserID == arg.UserID
 		deleted = deleted || match
@@ -2401,6 +2932,31 @@ func (q *FakeQuerier) DeleteOrganizationMember(ctx context.Context, arg database
 	return nil
 }
 
+func (q *FakeQuerier) DeleteProvisionerBuildPause(_ context.Context, organizationID uuid.NullUUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.provisionerBuildPauses = slices.DeleteFunc(q.provisionerBuildPauses, func(pause database.ProvisionerBuildPause) bool {
+		return pause.OrganizationID == organizationID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteProvisionerJobLogsByJobID(_ context.Context, jobID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logs := make([]database.ProvisionerJobLog, 0, len(q.provisionerJobLogs))
+	for _, jobLog := range q.provisionerJobLogs {
+		if jobLog.JobID == jobID {
+			continue
+		}
+		logs = append(logs, jobLog)
+	}
+	q.provisionerJobLogs = logs
+	return nil
+}
+
 func (q *FakeQuerier) DeleteProvisionerKey(_ context.Context, id uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2415,6 +2971,20 @@ func (q *FakeQuerier) DeleteProvisionerKey(_ context.Context, id uuid.UUID) erro
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteProvisionerReservation(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, reservation := range q.provisionerReservations {
+		if reservation.ID == id {
+			q.provisionerReservations = append(q.provisionerReservations[:i], q.provisionerReservations[i+1:]...)
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) DeleteReplicasUpdatedBefore(_ context.Context, before time.Time) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2466,6 +3036,78 @@ func (*FakeQuerier) DeleteTailnetTunnel(_ context.Context, arg database.DeleteTa
 	return database.DeleteTailnetTunnelRow{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteTemplatePresetByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.templatePresetGroupDefaults = slices.DeleteFunc(q.templatePresetGroupDefaults, func(d database.TemplatePresetGroupDefault) bool {
+		return d.TemplatePresetID == id
+	})
+	q.templatePresets = slices.DeleteFunc(q.templatePresets, func(p database.TemplatePreset) bool {
+		return p.ID == id
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteTemplatePresetGroupDefaultsByPresetID(_ context.Context, templatePresetID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.templatePresetGroupDefaults = slices.DeleteFunc(q.templatePresetGroupDefaults, func(d database.TemplatePresetGroupDefault) bool {
+		return d.TemplatePresetID == templatePresetID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteTemplateReleaseChannel(_ context.Context, arg database.DeleteTemplateReleaseChannelParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, channel := range q.templateReleaseChannels {
+		if channel.TemplateID == arg.TemplateID && channel.Channel == arg.Channel {
+			q.templateReleaseChannels = append(q.templateReleaseChannels[:i], q.templateReleaseChannels[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteTemplateShare(_ context.Context, arg database.DeleteTemplateShareParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, share := range q.templateShares {
+		if share.TemplateID == arg.TemplateID && share.OrganizationID == arg.OrganizationID {
+			q.templateShares = append(q.templateShares[:i], q.templateShares[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteTemplateVersionRolloutByTemplateID(_ context.Context, templateID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, rollout := range q.templateVersionRollouts {
+		if rollout.TemplateID == templateID {
+			q.templateVersionRollouts = append(q.templateVersionRollouts[:i], q.templateVersionRollouts[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) DeleteWebpushSubscriptionByUserIDAndEndpoint(_ context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2541,6 +3183,90 @@ func (q *FakeQuerier) DeleteWorkspaceAgentPortSharesByTemplate(_ context.Context
 	return nil
 }
 
+func (q *FakeQuerier) DeleteWorkspaceBuildArchiveByBuildID(_ context.Context, workspaceBuildID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, archive := range q.workspaceBuildArchives {
+		if archive.WorkspaceBuildID != workspaceBuildID {
+			continue
+		}
+		q.workspaceBuildArchives = append(q.workspaceBuildArchives[:i], q.workspaceBuildArchives[i+1:]...)
+		q.files = slices.DeleteFunc(q.files, func(file database.File) bool {
+			return file.ID == archive.FileID
+		})
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceBuildInterimState(_ context.Context, workspaceBuildID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceBuildInterimStates = slices.DeleteFunc(q.workspaceBuildInterimStates, func(state database.WorkspaceBuildInterimState) bool {
+		return state.WorkspaceBuildID == workspaceBuildID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceBuildQueueEntry(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, entry := range q.workspaceBuildQueue {
+		if entry.ID == id {
+			q.workspaceBuildQueue = append(q.workspaceBuildQueue[:i], q.workspaceBuildQueue[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceLabelsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceLabels = slices.DeleteFunc(q.workspaceLabels, func(label database.WorkspaceLabel) bool {
+		return label.WorkspaceID == workspaceID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspacePrebuildReservationByWorkspaceID(_ context.Context, workspaceID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspacePrebuildReservations = slices.DeleteFunc(q.workspacePrebuildReservations, func(reservation database.WorkspacePrebuildReservation) bool {
+		return reservation.WorkspaceID == workspaceID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceReleaseChannelByWorkspaceID(_ context.Context, workspaceID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, channel := range q.workspaceReleaseChannels {
+		if channel.WorkspaceID == workspaceID {
+			q.workspaceReleaseChannels = append(q.workspaceReleaseChannels[:i], q.workspaceReleaseChannels[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceResourceMetadataByResourceIDs(_ context.Context, ids []uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceResourceMetadata = slices.DeleteFunc(q.workspaceResourceMetadata, func(metadatum database.WorkspaceResourceMetadatum) bool {
+		return slices.Contains(ids, metadatum.WorkspaceResourceID)
+	})
+	return nil
+}
+
 func (q *FakeQuerier) DeleteWorkspaceSubAgentByID(_ context.Context, id uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2560,6 +3286,16 @@ func (*FakeQuerier) DisableForeignKeysAndTriggers(_ context.Context) error {
 	return nil
 }
 
+func (*FakeQuerier) DropOldAuditLogsPartitions(_ context.Context, _ time.Time) (int32, error) {
+	// The in-memory database has no partitions.
+	return 0, nil
+}
+
+func (*FakeQuerier) DropOldWorkspaceAgentStatsPartitions(_ context.Context, _ time.Time) (int32, error) {
+	// The in-memory database has no partitions.
+	return 0, nil
+}
+
 func (q *FakeQuerier) EnqueueNotificationMessage(_ context.Context, arg database.EnqueueNotificationMessageParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2762,6 +3498,43 @@ func (q *FakeQuerier) GetAPIKeysLastUsedAfter(_ context.Context, after time.Time
 	return apiKeys, nil
 }
 
+func (q *FakeQuerier) GetAccessRequestByID(_ context.Context, id uuid.UUID) (database.AccessRequest, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, req := range q.accessRequests {
+		if req.ID == id {
+			return req, nil
+		}
+	}
+	return database.AccessRequest{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetAccessRequests(_ context.Context, arg database.GetAccessRequestsParams) ([]database.AccessRequest, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	reqs := make([]database.AccessRequest, 0)
+	for _, req := range q.accessRequests {
+		if arg.Status != "" && string(req.Status) != arg.Status {
+			continue
+		}
+		if arg.RequesterID != uuid.Nil && req.RequesterID != arg.RequesterID {
+			continue
+		}
+		reqs = append(reqs, req)
+	}
+	slices.SortFunc(reqs, func(a, b database.AccessRequest) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return reqs, nil
+}
+
 func (q *FakeQuerier) GetActivePresetPrebuildSchedules(ctx context.Context) ([]database.TemplateVersionPresetPrebuildSchedule, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2847,6 +3620,34 @@ func (q *FakeQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context,
 	return filteredBuilds, nil
 }
 
+func (q *FakeQuerier) GetActiveWorkspaceLockByWorkspaceID(_ context.Context, workspaceID uuid.UUID) (database.WorkspaceLock, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, lock := range q.workspaceLocks {
+		if lock.WorkspaceID == workspaceID && !lock.UnlockedAt.Valid {
+			return lock, nil
+		}
+	}
+	return database.WorkspaceLock{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetActiveWorkspaceMigrations(_ context.Context) ([]database.WorkspaceMigration, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var migrations []database.WorkspaceMigration
+	for _, migration := range q.workspaceMigrations {
+		if !migration.CompletedAt.Valid {
+			migrations = append(migrations, migration)
+		}
+	}
+	slices.SortFunc(migrations, func(a, b database.WorkspaceMigration) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return migrations, nil
+}
+
 func (*FakeQuerier) GetAllTailnetAgents(_ context.Context) ([]database.TailnetAgent, error) {
 	return nil, ErrUnimplemented
 }
@@ -2892,6 +3693,43 @@ func (q *FakeQuerier) GetApplicationName(_ context.Context) (string, error) {
 	return q.applicationName, nil
 }
 
+func (q *FakeQuerier) GetAuditLogsForExport(_ context.Context, arg database.GetAuditLogsForExportParams) ([]database.GetAuditLogsForExportRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	after := database.AuditLog{Time: arg.AfterTime, ID: arg.AfterID}
+	logs := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if compareAuditLogs(alog, after) <= 0 || !alog.Time.Before(arg.BeforeTime) {
+			continue
+		}
+		logs = append(logs, alog)
+	}
+	slices.SortFunc(logs, compareAuditLogs)
+	if len(logs) > int(arg.LimitCount) {
+		logs = logs[:arg.LimitCount]
+	}
+
+	rows := make([]database.GetAuditLogsForExportRow, 0, len(logs))
+	for _, alog := range logs {
+		row := database.GetAuditLogsForExportRow{AuditLog: alog}
+		if user, err := q.getUserByIDNoLock(alog.UserID); err == nil {
+			row.UserUsername = user.Username
+			row.UserEmail = user.Email
+		}
+		if org, err := q.getOrganizationByIDNoLock(alog.OrganizationID); err == nil {
+			row.OrganizationName = org.Name
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	return q.GetAuthorizedAuditLogsOffset(ctx, arg, nil)
 }
@@ -2940,36 +3778,139 @@ func (q *FakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 	}, nil
 }
 
-func (q *FakeQuerier) GetCoordinatorResumeTokenSigningKey(_ context.Context) (string, error) {
+func (*FakeQuerier) GetBatchedNotificationMessagesByUserID(_ context.Context, _ uuid.UUID) ([]database.GetBatchedNotificationMessagesByUserIDRow, error) {
+	// Not implementing this function because it relies on notification templates, which are created with migrations.
+	return nil, ErrUnimplemented
+}
+
+func (q *FakeQuerier) GetBuildAlertRuleByID(_ context.Context, id uuid.UUID) (database.BuildAlertRule, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
-	if q.coordinatorResumeTokenSigningKey == "" {
-		return "", sql.ErrNoRows
+
+	for _, rule := range q.buildAlertRules {
+		if rule.ID == id {
+			return rule, nil
+		}
 	}
-	return q.coordinatorResumeTokenSigningKey, nil
+
+	return database.BuildAlertRule{}, sql.ErrNoRows
 }
 
-func (q *FakeQuerier) GetCryptoKeyByFeatureAndSequence(_ context.Context, arg database.GetCryptoKeyByFeatureAndSequenceParams) (database.CryptoKey, error) {
+func (q *FakeQuerier) GetBuildAlertRuleMetrics(ctx context.Context, arg database.GetBuildAlertRuleMetricsParams) (database.GetBuildAlertRuleMetricsRow, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
-		return database.CryptoKey{}, err
+		return database.GetBuildAlertRuleMetricsRow{}, err
 	}
 
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	for _, key := range q.cryptoKeys {
-		if key.Feature == arg.Feature && key.Sequence == arg.Sequence {
-			// Keys with NULL secrets are considered deleted.
-			if key.Secret.Valid {
-				return key, nil
+	var row database.GetBuildAlertRuleMetricsRow
+	var queueWaits []float64
+	for _, build := range q.workspaceBuilds {
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil || job.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		if arg.TemplateID.Valid {
+			workspace, err := q.getWorkspaceByIDNoLock(ctx, build.WorkspaceID)
+			if err != nil || workspace.TemplateID != arg.TemplateID.UUID {
+				continue
 			}
-			return database.CryptoKey{}, sql.ErrNoRows
 		}
-	}
-
-	return database.CryptoKey{}, sql.ErrNoRows
-}
+
+		completedInWindow := job.CompletedAt.Valid && !job.CompletedAt.Time.Before(arg.Since)
+		if completedInWindow {
+			switch job.JobStatus {
+			case database.ProvisionerJobStatusSucceeded:
+				row.CompletedBuilds++
+			case database.ProvisionerJobStatusFailed:
+				row.CompletedBuilds++
+				row.FailedBuilds++
+			}
+		}
+		if !job.CreatedAt.Before(arg.Since) && (job.StartedAt.Valid || !job.CompletedAt.Valid) {
+			startedAt := arg.Now
+			if job.StartedAt.Valid {
+				startedAt = job.StartedAt.Time
+			}
+			row.QueuedBuilds++
+			queueWaits = append(queueWaits, float64(startedAt.Sub(job.CreatedAt).Milliseconds()))
+		}
+	}
+
+	// Mimic percentile_cont(0.95), which interpolates between the two
+	// closest values.
+	if len(queueWaits) > 0 {
+		slices.Sort(queueWaits)
+		pos := 0.95 * float64(len(queueWaits)-1)
+		lower := int(pos)
+		row.QueueWaitP95Ms = queueWaits[lower]
+		if lower+1 < len(queueWaits) {
+			row.QueueWaitP95Ms += (pos - float64(lower)) * (queueWaits[lower+1] - queueWaits[lower])
+		}
+	}
+
+	return row, nil
+}
+
+func (q *FakeQuerier) GetBuildAlertRules(_ context.Context) ([]database.BuildAlertRule, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rules := slices.Clone(q.buildAlertRules)
+	slices.SortFunc(rules, func(a, b database.BuildAlertRule) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return rules, nil
+}
+
+func (q *FakeQuerier) GetBuildAlertRulesByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.BuildAlertRule, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rules := make([]database.BuildAlertRule, 0)
+	for _, rule := range q.buildAlertRules {
+		if rule.OrganizationID == organizationID {
+			rules = append(rules, rule)
+		}
+	}
+	slices.SortFunc(rules, func(a, b database.BuildAlertRule) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return rules, nil
+}
+
+func (q *FakeQuerier) GetCoordinatorResumeTokenSigningKey(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	if q.coordinatorResumeTokenSigningKey == "" {
+		return "", sql.ErrNoRows
+	}
+	return q.coordinatorResumeTokenSigningKey, nil
+}
+
+func (q *FakeQuerier) GetCryptoKeyByFeatureAndSequence(_ context.Context, arg database.GetCryptoKeyByFeatureAndSequenceParams) (database.CryptoKey, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.CryptoKey{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.cryptoKeys {
+		if key.Feature == arg.Feature && key.Sequence == arg.Sequence {
+			// Keys with NULL secrets are considered deleted.
+			if key.Secret.Valid {
+				return key, nil
+			}
+			return database.CryptoKey{}, sql.ErrNoRows
+		}
+	}
+
+	return database.CryptoKey{}, sql.ErrNoRows
+}
 
 func (q *FakeQuerier) GetCryptoKeys(_ context.Context) ([]database.CryptoKey, error) {
 	q.mutex.RLock()
@@ -3009,6 +3950,16 @@ func (q *FakeQuerier) GetDBCryptKeys(_ context.Context) ([]database.DBCryptKey,
 	return ks, nil
 }
 
+func (q *FakeQuerier) GetDBPurgeStats(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.dbPurgeStats == nil {
+		return "", sql.ErrNoRows
+	}
+	return string(q.dbPurgeStats), nil
+}
+
 func (q *FakeQuerier) GetDERPMeshKey(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3038,6 +3989,63 @@ func (q *FakeQuerier) GetDefaultProxyConfig(_ context.Context) (database.GetDefa
 	}, nil
 }
 
+func (q *FakeQuerier) GetDefaultTemplatePresetForUser(_ context.Context, arg database.GetDefaultTemplatePresetForUserParams) (database.TemplatePreset, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplatePreset{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	userGroupIDs := make(map[uuid.UUID]struct{})
+	for _, member := range q.groupMembers {
+		if member.UserID == arg.UserID {
+			userGroupIDs[member.GroupID] = struct{}{}
+		}
+	}
+	// Handle the everyone group
+	for _, orgMember := range q.organizationMembers {
+		if orgMember.UserID == arg.UserID {
+			userGroupIDs[orgMember.OrganizationID] = struct{}{}
+		}
+	}
+
+	var (
+		found     *database.TemplatePresetGroupDefault
+		foundName string
+		everyone  bool
+	)
+	for _, def := range q.templatePresetGroupDefaults {
+		if def.TemplateID != arg.TemplateID {
+			continue
+		}
+		if _, ok := userGroupIDs[def.GroupID]; !ok {
+			continue
+		}
+		group, err := q.getGroupByIDNoLock(context.Background(), def.GroupID)
+		if err != nil {
+			continue
+		}
+		isEveryone := group.ID == group.OrganizationID
+		if found != nil && (isEveryone && !everyone || isEveryone == everyone && group.Name >= foundName) {
+			continue
+		}
+		found = &def
+		foundName = group.Name
+		everyone = isEveryone
+	}
+	if found == nil {
+		return database.TemplatePreset{}, sql.ErrNoRows
+	}
+	for _, preset := range q.templatePresets {
+		if preset.ID == found.TemplatePresetID {
+			return preset, nil
+		}
+	}
+	return database.TemplatePreset{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetDeploymentDAUs(_ context.Context, tzOffset int32) ([]database.GetDeploymentDAUsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3283,6 +4291,35 @@ func (q *FakeQuerier) GetEligibleProvisionerDaemonsByProvisionerJobIDs(_ context
 	return results, nil
 }
 
+func (q *FakeQuerier) GetEncryptedWorkspaceSessionRecordingChunks(_ context.Context) ([]database.WorkspaceSessionRecordingChunk, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	chunks := make([]database.WorkspaceSessionRecordingChunk, 0)
+	for _, chunk := range q.workspaceSessionRecordingChunks {
+		if chunk.DataKeyID.Valid {
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks, nil
+}
+
+func (q *FakeQuerier) GetExpiredAccessRequests(_ context.Context, now time.Time) ([]database.AccessRequest, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	reqs := make([]database.AccessRequest, 0)
+	for _, req := range q.accessRequests {
+		if req.Status == database.AccessRequestStatusApproved && req.ExpiresAt.Valid && !req.ExpiresAt.Time.After(now) {
+			reqs = append(reqs, req)
+		}
+	}
+	slices.SortFunc(reqs, func(a, b database.AccessRequest) int {
+		return a.ExpiresAt.Time.Compare(b.ExpiresAt.Time)
+	})
+	return reqs, nil
+}
+
 func (q *FakeQuerier) GetExternalAuthLink(_ context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ExternalAuthLink{}, err
@@ -3746,6 +4783,16 @@ func (q *FakeQuerier) GetInboxNotificationsByUserID(_ context.Context, params da
 	return notifications, nil
 }
 
+func (q *FakeQuerier) GetJobReaperLastRunAt(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.jobReaperLastRunAt == nil {
+		return "", sql.ErrNoRows
+	}
+	return string(q.jobReaperLastRunAt), nil
+}
+
 func (q *FakeQuerier) GetLastUpdateCheck(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3772,6 +4819,32 @@ func (q *FakeQuerier) GetLatestCryptoKeyByFeature(_ context.Context, feature dat
 	return latestKey, nil
 }
 
+func (q *FakeQuerier) GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var row database.WorkspaceBuild
+	var buildNum int32 = -1
+	for _, workspaceBuild := range q.workspaceBuilds {
+		if workspaceBuild.WorkspaceID != workspaceID || workspaceBuild.Transition != database.WorkspaceTransitionStart || workspaceBuild.BuildNumber <= buildNum {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, workspaceBuild.JobID)
+		if err != nil {
+			return database.WorkspaceBuild{}, err
+		}
+		if provisionerJobStatus(job) != database.ProvisionerJobStatusSucceeded {
+			continue
+		}
+		row = q.workspaceBuildWithUserNoLock(workspaceBuild)
+		buildNum = workspaceBuild.BuildNumber
+	}
+	if buildNum == -1 {
+		return database.WorkspaceBuild{}, sql.ErrNoRows
+	}
+	return row, nil
+}
+
 func (q *FakeQuerier) GetLatestWorkspaceAppStatusesByWorkspaceIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAppStatus, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3881,6 +4954,30 @@ func (q *FakeQuerier) GetLicenses(_ context.Context) ([]database.License, error)
 	return results, nil
 }
 
+func (q *FakeQuerier) GetLoginDeviceCodeByDeviceCodeHash(_ context.Context, deviceCodeHash []byte) (database.LoginDeviceCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.loginDeviceCodes {
+		if bytes.Equal(code.DeviceCodeHash, deviceCodeHash) {
+			return code, nil
+		}
+	}
+	return database.LoginDeviceCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetLoginDeviceCodeByUserCode(_ context.Context, userCode string) (database.LoginDeviceCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.loginDeviceCodes {
+		if code.UserCode == userCode {
+			return code, nil
+		}
+	}
+	return database.LoginDeviceCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4115,6 +5212,41 @@ func (q *FakeQuerier) GetOAuthSigningKey(_ context.Context) (string, error) {
 	return q.oauthSigningKey, nil
 }
 
+func (q *FakeQuerier) GetOIDCUserClaims(_ context.Context, organizationID uuid.UUID) ([]database.GetOIDCUserClaimsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	orgMembers := q.getOrganizationMemberNoLock(organizationID)
+
+	rows := make([]database.GetOIDCUserClaimsRow, 0)
+	for _, link := range q.userLinks {
+		if link.LoginType != database.LoginTypeOIDC {
+			continue
+		}
+		if organizationID != uuid.Nil {
+			inOrg := slices.ContainsFunc(orgMembers, func(organizationMember database.OrganizationMember) bool {
+				return organizationMember.UserID == link.UserID
+			})
+			if !inOrg {
+				continue
+			}
+		}
+		user, err := q.getUserByIDNoLock(link.UserID)
+		if err != nil || user.Deleted {
+			continue
+		}
+		rows = append(rows, database.GetOIDCUserClaimsRow{
+			UserID:   user.ID,
+			Username: user.Username,
+			Claims:   link.Claims,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetOIDCUserClaimsRow) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetOrganizationByID(_ context.Context, id uuid.UUID) (database.Organization, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4202,6 +5334,18 @@ func (q *FakeQuerier) GetOrganizationResourceCountByID(_ context.Context, organi
 	}, nil
 }
 
+func (q *FakeQuerier) GetOrganizationWorkspaceNamingPolicy(_ context.Context, organizationID uuid.UUID) (database.WorkspaceNamingPolicy, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, p := range q.workspaceNamingPolicies {
+		if p.OrganizationID == organizationID && !p.TemplateID.Valid {
+			return p, nil
+		}
+	}
+	return database.WorkspaceNamingPolicy{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetOrganizations(_ context.Context, args database.GetOrganizationsParams) ([]database.Organization, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4269,6 +5413,61 @@ func (q *FakeQuerier) GetParameterSchemasByJobID(_ context.Context, jobID uuid.U
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetPendingProvisionerJobsQueueDepthByTags(_ context.Context) ([]database.GetPendingProvisionerJobsQueueDepthByTagsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetPendingProvisionerJobsQueueDepthByTagsRow, 0)
+	for _, job := range q.provisionerJobs {
+		if job.JobStatus != database.ProvisionerJobStatusPending {
+			continue
+		}
+		idx := slices.IndexFunc(rows, func(row database.GetPendingProvisionerJobsQueueDepthByTagsRow) bool {
+			return row.OrganizationID == job.OrganizationID && maps.Equal(row.Tags, job.Tags)
+		})
+		if idx < 0 {
+			rows = append(rows, database.GetPendingProvisionerJobsQueueDepthByTagsRow{
+				OrganizationID:  job.OrganizationID,
+				Tags:            maps.Clone(job.Tags),
+				OldestCreatedAt: job.CreatedAt,
+			})
+			idx = len(rows) - 1
+		}
+		rows[idx].PendingJobs++
+		if job.CreatedAt.Before(rows[idx].OldestCreatedAt) {
+			rows[idx].OldestCreatedAt = job.CreatedAt
+		}
+	}
+
+	slices.SortFunc(rows, func(a, b database.GetPendingProvisionerJobsQueueDepthByTagsRow) int {
+		if a.PendingJobs != b.PendingJobs {
+			return int(b.PendingJobs - a.PendingJobs)
+		}
+		return a.OldestCreatedAt.Compare(b.OldestCreatedAt)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetPendingUserActivityExports(_ context.Context) ([]database.UserActivityExport, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	exports := make([]database.UserActivityExport, 0)
+	for _, export := range q.userActivityExports {
+		if export.Status == database.UserActivityExportStatusPending {
+			exports = append(exports, export)
+		}
+	}
+	slices.SortFunc(exports, func(a, b database.UserActivityExport) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return exports, nil
+}
+
+func (*FakeQuerier) GetPrebuildClaimDemand(_ context.Context, _ time.Time) ([]database.GetPrebuildClaimDemandRow, error) {
+	return nil, ErrUnimplemented
+}
+
 func (*FakeQuerier) GetPrebuildMetrics(_ context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	return make([]database.GetPrebuildMetricsRow, 0), nil
 }
@@ -4436,6 +5635,18 @@ func (q *FakeQuerier) GetPreviousTemplateVersion(_ context.Context, arg database
 	return previousTemplateVersions[0], nil
 }
 
+func (q *FakeQuerier) GetProvisionerBuildPause(_ context.Context, organizationID uuid.NullUUID) (database.ProvisionerBuildPause, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, pause := range q.provisionerBuildPauses {
+		if pause.OrganizationID == organizationID {
+			return pause, nil
+		}
+	}
+	return database.ProvisionerBuildPause{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetProvisionerDaemons(_ context.Context) ([]database.ProvisionerDaemon, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4650,6 +5861,50 @@ func (q *FakeQuerier) GetProvisionerJobByIDForUpdate(ctx context.Context, id uui
 	return q.getProvisionerJobByIDNoLock(ctx, id)
 }
 
+func (q *FakeQuerier) GetProvisionerJobLogArchiveByJobID(_ context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, archive := range q.provisionerJobLogArchives {
+		if archive.JobID == jobID {
+			return archive, nil
+		}
+	}
+	return database.ProvisionerJobLogArchive{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetProvisionerJobThroughputByTags(_ context.Context, arg database.GetProvisionerJobThroughputByTagsParams) ([]database.GetProvisionerJobThroughputByTagsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetProvisionerJobThroughputByTagsRow, 0)
+	for _, job := range q.provisionerJobs {
+		if !slices.Contains(arg.OrganizationIDs, job.OrganizationID) {
+			continue
+		}
+		if !job.StartedAt.Valid || job.StartedAt.Time.Before(arg.StartedAfter) {
+			continue
+		}
+		idx := slices.IndexFunc(rows, func(row database.GetProvisionerJobThroughputByTagsRow) bool {
+			return row.OrganizationID == job.OrganizationID && maps.Equal(row.Tags, job.Tags)
+		})
+		if idx < 0 {
+			rows = append(rows, database.GetProvisionerJobThroughputByTagsRow{
+				OrganizationID: job.OrganizationID,
+				Tags:           maps.Clone(job.Tags),
+			})
+			idx = len(rows) - 1
+		}
+		rows[idx].StartedJobs++
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobTimingsByJobID(_ context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4775,6 +6030,16 @@ func (q *FakeQuerier) GetProvisionerJobsByOrganizationAndStatusWithQueuePosition
 		return nil, err
 	}
 
+	var afterJob *database.ProvisionerJob
+	if arg.AfterID != uuid.Nil {
+		job, err := q.getProvisionerJobByIDNoLock(ctx, arg.AfterID)
+		if err != nil {
+			// The SQL query compares against NULL, which matches no rows.
+			return nil, nil
+		}
+		afterJob = &job
+	}
+
 	var rows []database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow
 	for _, rowQP := range rowsWithQueuePosition {
 		job := rowQP.ProvisionerJob
@@ -4782,9 +6047,15 @@ func (q *FakeQuerier) GetProvisionerJobsByOrganizationAndStatusWithQueuePosition
 		if job.OrganizationID != arg.OrganizationID {
 			continue
 		}
+		if afterJob != nil && compareProvisionerJobs(job, *afterJob) >= 0 {
+			continue
+		}
 		if len(arg.Status) > 0 && !slices.Contains(arg.Status, job.JobStatus) {
 			continue
 		}
+		if len(arg.Types) > 0 && !slices.Contains(arg.Types, job.Type) {
+			continue
+		}
 		if len(arg.IDs) > 0 && !slices.Contains(arg.IDs, job.ID) {
 			continue
 		}
@@ -4826,13 +6097,17 @@ func (q *FakeQuerier) GetProvisionerJobsByOrganizationAndStatusWithQueuePosition
 				return nil, err
 			}
 			row.TemplateVersionName = templateVersion.Name
-			template, err := q.getTemplateByIDNoLock(ctx, templateVersion.TemplateID.UUID)
-			if err != nil {
-				return nil, err
+			// Template versions that were never promoted to a template
+			// don't have one.
+			if templateVersion.TemplateID.Valid {
+				template, err := q.getTemplateByIDNoLock(ctx, templateVersion.TemplateID.UUID)
+				if err != nil {
+					return nil, err
+				}
+				row.TemplateID = uuid.NullUUID{UUID: template.ID, Valid: true}
+				row.TemplateName = template.Name
+				row.TemplateDisplayName = template.DisplayName
 			}
-			row.TemplateID = uuid.NullUUID{UUID: template.ID, Valid: true}
-			row.TemplateName = template.Name
-			row.TemplateDisplayName = template.DisplayName
 		}
 		// End add metadata.
 
@@ -4867,7 +6142,7 @@ func (q *FakeQuerier) GetProvisionerJobsByOrganizationAndStatusWithQueuePosition
 	}
 
 	slices.SortFunc(rows, func(a, b database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow) int {
-		return b.ProvisionerJob.CreatedAt.Compare(a.ProvisionerJob.CreatedAt)
+		return compareProvisionerJobs(b.ProvisionerJob, a.ProvisionerJob)
 	})
 	if arg.Limit.Valid && arg.Limit.Int32 > 0 && len(rows) > int(arg.Limit.Int32) {
 		rows = rows[:arg.Limit.Int32]
@@ -4892,6 +6167,83 @@ func (q *FakeQuerier) GetProvisionerJobsCreatedAfter(_ context.Context, after ti
 	return jobs, nil
 }
 
+func (q *FakeQuerier) GetProvisionerJobsRunningBetween(_ context.Context, arg database.GetProvisionerJobsRunningBetweenParams) ([]database.GetProvisionerJobsRunningBetweenRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetProvisionerJobsRunningBetweenRow, 0)
+	for _, job := range q.provisionerJobs {
+		if !job.StartedAt.Valid || !job.StartedAt.Time.Before(arg.EndTime) {
+			continue
+		}
+		if job.CompletedAt.Valid && !job.CompletedAt.Time.After(arg.StartTime) {
+			continue
+		}
+		rows = append(rows, database.GetProvisionerJobsRunningBetweenRow{
+			ID:             job.ID,
+			OrganizationID: job.OrganizationID,
+			Tags:           maps.Clone(job.Tags),
+			CreatedAt:      job.CreatedAt,
+			StartedAt:      job.StartedAt,
+			CompletedAt:    job.CompletedAt,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetProvisionerJobsRunningBetweenRow) int {
+		return a.StartedAt.Time.Compare(b.StartedAt.Time)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetProvisionerJobsToArchiveLogs(_ context.Context, arg database.GetProvisionerJobsToArchiveLogsParams) ([]database.GetProvisionerJobsToArchiveLogsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	hasLogs := make(map[uuid.UUID]bool)
+	for _, jobLog := range q.provisionerJobLogs {
+		hasLogs[jobLog.JobID] = true
+	}
+	archived := make(map[uuid.UUID]bool)
+	for _, archive := range q.provisionerJobLogArchives {
+		archived[archive.JobID] = true
+	}
+
+	jobs := make([]database.ProvisionerJob, 0)
+	for _, job := range q.provisionerJobs {
+		if !job.CompletedAt.Valid || !job.CompletedAt.Time.Before(arg.CompletedBefore) {
+			continue
+		}
+		if archived[job.ID] || !hasLogs[job.ID] {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b database.ProvisionerJob) int {
+		return a.CompletedAt.Time.Compare(b.CompletedAt.Time)
+	})
+	if arg.LimitCount >= 0 && len(jobs) > int(arg.LimitCount) {
+		jobs = jobs[:arg.LimitCount]
+	}
+
+	rows := make([]database.GetProvisionerJobsToArchiveLogsRow, 0, len(jobs))
+	for _, job := range jobs {
+		rows = append(rows, database.GetProvisionerJobsToArchiveLogsRow{
+			ID:          job.ID,
+			InitiatorID: job.InitiatorID,
+		})
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobsToBeReaped(_ context.Context, arg database.GetProvisionerJobsToBeReapedParams) ([]database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4901,7 +6253,8 @@ func (q *FakeQuerier) GetProvisionerJobsToBeReaped(_ context.Context, arg databa
 	for _, provisionerJob := range q.provisionerJobs {
 		if !provisionerJob.CompletedAt.Valid {
 			if (provisionerJob.StartedAt.Valid && provisionerJob.UpdatedAt.Before(arg.HungSince)) ||
-				(!provisionerJob.StartedAt.Valid && provisionerJob.UpdatedAt.Before(arg.PendingSince)) {
+				(!provisionerJob.StartedAt.Valid && provisionerJob.UpdatedAt.Before(arg.PendingSince) && !q.provisionerJobPausedNoLock(provisionerJob)) ||
+				(provisionerJob.StartedAt.Valid && provisionerJob.CanceledAt.Valid && provisionerJob.CanceledAt.Time.Before(arg.CanceledSince)) {
 				// clone the Tags before appending, since maps are reference types and
 				// we don't want the caller to be able to mutate the map we have inside
 				// dbmem!
@@ -4924,7 +6277,7 @@ func (q *FakeQuerier) GetProvisionerKeyByHashedSecret(_ context.Context, hashedS
 	defer q.mutex.RUnlock()
 
 	for _, key := range q.provisionerKeys {
-		if bytes.Equal(key.HashedSecret, hashedSecret) {
+		if bytes.Equal(key.HashedSecret, hashedSecret) || bytes.Equal(key.PreviousHashedSecret, hashedSecret) {
 			return key, nil
 		}
 	}
@@ -4979,6 +6332,41 @@ func (q *FakeQuerier) GetProvisionerLogsAfterID(_ context.Context, arg database.
 	return logs, nil
 }
 
+func (q *FakeQuerier) GetProvisionerReservationByID(_ context.Context, id uuid.UUID) (database.ProvisionerReservation, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, reservation := range q.provisionerReservations {
+		if reservation.ID == id {
+			return reservation, nil
+		}
+	}
+
+	return database.ProvisionerReservation{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetProvisionerReservationsByOrganization(_ context.Context, arg database.GetProvisionerReservationsByOrganizationParams) ([]database.ProvisionerReservation, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	reservations := make([]database.ProvisionerReservation, 0)
+	for _, reservation := range q.provisionerReservations {
+		if reservation.OrganizationID != arg.OrganizationID || !reservation.EndsAt.After(arg.EndsAfter) {
+			continue
+		}
+		reservations = append(reservations, reservation)
+	}
+	slices.SortFunc(reservations, func(a, b database.ProvisionerReservation) int {
+		return a.StartsAt.Compare(b.StartsAt)
+	})
+	return reservations, nil
+}
+
 func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, params database.GetQuotaAllowanceForUserParams) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -5019,78 +6407,310 @@ func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, params databas
 		sum += int64(group.QuotaAllowance)
 	}
 
-	return sum, nil
+	return sum, nil
+}
+
+func (q *FakeQuerier) GetQuotaConsumedForUser(_ context.Context, params database.GetQuotaConsumedForUserParams) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var sum int64
+	for _, workspace := range q.workspaces {
+		if workspace.OwnerID != params.OwnerID {
+			continue
+		}
+		if workspace.OrganizationID != params.OrganizationID {
+			continue
+		}
+		if workspace.Deleted {
+			continue
+		}
+
+		var lastBuild database.WorkspaceBuild
+		for _, build := range q.workspaceBuilds {
+			if build.WorkspaceID != workspace.ID {
+				continue
+			}
+			if build.CreatedAt.After(lastBuild.CreatedAt) {
+				lastBuild = build
+			}
+		}
+		sum += int64(lastBuild.DailyCost)
+	}
+	return sum, nil
+}
+
+func (q *FakeQuerier) GetQuotaMonthlyBudgetForUser(_ context.Context, arg database.GetQuotaMonthlyBudgetForUserParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var sum int64
+	for _, member := range q.groupMembers {
+		if member.UserID != arg.UserID {
+			continue
+		}
+		for _, group := range q.groups {
+			if group.ID == member.GroupID && group.OrganizationID == arg.OrganizationID && group.ID != group.OrganizationID {
+				sum += int64(group.MonthlyBudget)
+			}
+		}
+	}
+
+	// Add the budget of the Everyone group iff the user is a member of
+	// said organization.
+	for _, mem := range q.organizationMembers {
+		if mem.UserID != arg.UserID || mem.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		group, err := q.getGroupByIDNoLock(context.Background(), mem.OrganizationID)
+		if err != nil {
+			return -1, xerrors.Errorf("failed to get everyone group for org %q", mem.OrganizationID.String())
+		}
+		sum += int64(group.MonthlyBudget)
+	}
+
+	return sum, nil
+}
+
+func (q *FakeQuerier) GetQuotaMonthlySpendForUser(_ context.Context, arg database.GetQuotaMonthlySpendForUserParams) (float64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var sum float64
+	for _, cost := range q.workspaceDailyCosts {
+		if cost.OwnerID != arg.OwnerID || cost.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		if cost.StartTime.Before(arg.StartTime) {
+			continue
+		}
+		sum += cost.Cost
+	}
+	return sum, nil
+}
+
+func (q *FakeQuerier) GetReadOnlySettings(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.readOnlySettings == nil {
+		return "{}", nil
+	}
+
+	return string(q.readOnlySettings), nil
+}
+
+func (q *FakeQuerier) GetReplicaByID(_ context.Context, id uuid.UUID) (database.Replica, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, replica := range q.replicas {
+		if replica.ID == id {
+			return replica, nil
+		}
+	}
+
+	return database.Replica{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.Time) ([]database.Replica, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	replicas := make([]database.Replica, 0)
+	for _, replica := range q.replicas {
+		if replica.UpdatedAt.After(updatedAt) && !replica.StoppedAt.Valid {
+			replicas = append(replicas, replica)
+		}
+	}
+	return replicas, nil
+}
+
+func (q *FakeQuerier) GetRunningPrebuiltWorkspaces(ctx context.Context) ([]database.GetRunningPrebuiltWorkspacesRow, error) {
+	return nil, ErrUnimplemented
+}
+
+func (q *FakeQuerier) GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx context.Context, now time.Time) ([]database.GetRunningWorkspaceBuildsExceedingDurationThresholdRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	thresholds := make(map[uuid.UUID]int64)
+	for _, uc := range q.userConfigs {
+		if uc.Key != "notification_build_duration_threshold_ms" {
+			continue
+		}
+		threshold, err := strconv.ParseInt(uc.Value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		thresholds[uc.UserID] = threshold
+	}
+
+	notified := make(map[uuid.UUID]struct{})
+	for _, n := range q.workspaceBuildDurationNotifications {
+		notified[n.WorkspaceBuildID] = struct{}{}
+	}
+
+	type row struct {
+		database.GetRunningWorkspaceBuildsExceedingDurationThresholdRow
+		startedAt time.Time
+	}
+	var rows []row
+	for _, build := range q.workspaceBuilds {
+		if _, ok := notified[build.ID]; ok {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if job.JobStatus != database.ProvisionerJobStatusRunning || !job.StartedAt.Valid {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, build.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		threshold, ok := thresholds[workspace.OwnerID]
+		if !ok || threshold <= 0 || workspace.Deleted {
+			continue
+		}
+		if job.StartedAt.Time.Add(time.Duration(threshold) * time.Millisecond).After(now) {
+			continue
+		}
+		rows = append(rows, row{
+			GetRunningWorkspaceBuildsExceedingDurationThresholdRow: database.GetRunningWorkspaceBuildsExceedingDurationThresholdRow{
+				WorkspaceBuildID: build.ID,
+				BuildNumber:      build.BuildNumber,
+				Transition:       build.Transition,
+				WorkspaceID:      workspace.ID,
+				WorkspaceName:    workspace.Name,
+				OwnerID:          workspace.OwnerID,
+				TemplateID:       workspace.TemplateID,
+				OrganizationID:   workspace.OrganizationID,
+				ThresholdMs:      threshold,
+			},
+			startedAt: job.StartedAt.Time,
+		})
+	}
+
+	slices.SortFunc(rows, func(a, b row) int {
+		return a.startedAt.Compare(b.startedAt)
+	})
+	out := make([]database.GetRunningWorkspaceBuildsExceedingDurationThresholdRow, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, r.GetRunningWorkspaceBuildsExceedingDurationThresholdRow)
+	}
+	return out, nil
+}
+
+func (q *FakeQuerier) GetRuntimeConfig(_ context.Context, key string) (string, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	val, ok := q.runtimeConfig[key]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return val, nil
 }
 
-func (q *FakeQuerier) GetQuotaConsumedForUser(_ context.Context, params database.GetQuotaConsumedForUserParams) (int64, error) {
+func (q *FakeQuerier) GetSensitiveTemplateVersionVariables(_ context.Context) ([]database.TemplateVersionVariable, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	var sum int64
-	for _, workspace := range q.workspaces {
-		if workspace.OwnerID != params.OwnerID {
-			continue
-		}
-		if workspace.OrganizationID != params.OrganizationID {
-			continue
-		}
-		if workspace.Deleted {
+	variables := make([]database.TemplateVersionVariable, 0)
+	for _, variable := range q.templateVersionVariables {
+		if !variable.Sensitive {
 			continue
 		}
-
-		var lastBuild database.WorkspaceBuild
-		for _, build := range q.workspaceBuilds {
-			if build.WorkspaceID != workspace.ID {
-				continue
-			}
-			if build.CreatedAt.After(lastBuild.CreatedAt) {
-				lastBuild = build
-			}
-		}
-		sum += int64(lastBuild.DailyCost)
+		variables = append(variables, variable)
 	}
-	return sum, nil
+	return variables, nil
 }
 
-func (q *FakeQuerier) GetReplicaByID(_ context.Context, id uuid.UUID) (database.Replica, error) {
+func (q *FakeQuerier) GetServiceAccountByUserID(_ context.Context, userID uuid.UUID) (database.ServiceAccount, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	for _, replica := range q.replicas {
-		if replica.ID == id {
-			return replica, nil
+	for _, serviceAccount := range q.serviceAccounts {
+		if serviceAccount.UserID == userID {
+			return serviceAccount, nil
 		}
 	}
-
-	return database.Replica{}, sql.ErrNoRows
+	return database.ServiceAccount{}, sql.ErrNoRows
 }
 
-func (q *FakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.Time) ([]database.Replica, error) {
+func (q *FakeQuerier) GetServiceAccountsByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.ServiceAccount, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
-	replicas := make([]database.Replica, 0)
-	for _, replica := range q.replicas {
-		if replica.UpdatedAt.After(updatedAt) && !replica.StoppedAt.Valid {
-			replicas = append(replicas, replica)
+
+	serviceAccounts := make([]database.ServiceAccount, 0)
+	for _, serviceAccount := range q.serviceAccounts {
+		if serviceAccount.OrganizationID != organizationID {
+			continue
+		}
+		user, err := q.getUserByIDNoLock(serviceAccount.UserID)
+		if err != nil || user.Deleted {
+			continue
 		}
+		serviceAccounts = append(serviceAccounts, serviceAccount)
 	}
-	return replicas, nil
-}
-
-func (q *FakeQuerier) GetRunningPrebuiltWorkspaces(ctx context.Context) ([]database.GetRunningPrebuiltWorkspacesRow, error) {
-	return nil, ErrUnimplemented
+	slices.SortFunc(serviceAccounts, func(a, b database.ServiceAccount) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return serviceAccounts, nil
 }
 
-func (q *FakeQuerier) GetRuntimeConfig(_ context.Context, key string) (string, error) {
-	q.mutex.Lock()
-	defer q.mutex.Unlock()
+func (q *FakeQuerier) GetStartableWorkspaceBuildQueueEntries(ctx context.Context) ([]database.WorkspaceBuildQueue, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
 
-	val, ok := q.runtimeConfig[key]
-	if !ok {
-		return "", sql.ErrNoRows
+	oldest := make(map[uuid.UUID]database.WorkspaceBuildQueue)
+	for _, entry := range q.workspaceBuildQueue {
+		if entry.FailedAt.Valid {
+			continue
+		}
+		if prev, ok := oldest[entry.WorkspaceID]; ok && !entry.CreatedAt.Before(prev.CreatedAt) {
+			continue
+		}
+		oldest[entry.WorkspaceID] = entry
 	}
 
-	return val, nil
+	entries := make([]database.WorkspaceBuildQueue, 0)
+	for workspaceID, entry := range oldest {
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, workspaceID)
+		if err != nil || workspace.Deleted {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspaceID)
+		if err == nil {
+			job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+			if err != nil {
+				return nil, err
+			}
+			if codersdk.ProvisionerJobStatus(provisionerJobStatus(job)).Active() {
+				continue
+			}
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b database.WorkspaceBuildQueue) int {
+		return bytes.Compare(a.WorkspaceID[:], b.WorkspaceID[:])
+	})
+	return entries, nil
 }
 
 func (*FakeQuerier) GetTailnetAgents(context.Context, uuid.UUID) ([]database.TailnetAgent, error) {
@@ -5593,6 +7213,31 @@ func (q *FakeQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg datab
 	return row, nil
 }
 
+func (q *FakeQuerier) GetTemplateBuildDurationStats(ctx context.Context, arg database.GetTemplateBuildDurationStatsParams) ([]database.TemplateBuildDurationStat, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var stats []database.TemplateBuildDurationStat
+	for _, stat := range q.templateBuildDurationStats {
+		if stat.TemplateID != arg.TemplateID || stat.StartTime.Before(arg.StartTime) {
+			continue
+		}
+		stats = append(stats, stat)
+	}
+	slices.SortFunc(stats, func(a, b database.TemplateBuildDurationStat) int {
+		if c := a.StartTime.Compare(b.StartTime); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Transition), string(b.Transition))
+	})
+	return stats, nil
+}
+
 func (q *FakeQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -5600,6 +7245,18 @@ func (q *FakeQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (databa
 	return q.getTemplateByIDNoLock(ctx, id)
 }
 
+func (q *FakeQuerier) GetTemplateByIDForUpdate(_ context.Context, id uuid.UUID) (database.TemplateTable, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, template := range q.templates {
+		if template.ID == id {
+			return template, nil
+		}
+	}
+	return database.TemplateTable{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetTemplateByOrganizationAndName(_ context.Context, arg database.GetTemplateByOrganizationAndNameParams) (database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Template{}, err
@@ -6122,10 +7779,103 @@ func (q *FakeQuerier) GetTemplateParameterInsights(ctx context.Context, arg data
 	return rows, nil
 }
 
+func (q *FakeQuerier) GetTemplatePresetByID(_ context.Context, id uuid.UUID) (database.TemplatePreset, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, preset := range q.templatePresets {
+		if preset.ID == id {
+			return preset, nil
+		}
+	}
+	return database.TemplatePreset{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplatePresetGroupDefaultsByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplatePresetGroupDefault, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	defaults := make([]database.TemplatePresetGroupDefault, 0)
+	for _, def := range q.templatePresetGroupDefaults {
+		if def.TemplateID == templateID {
+			defaults = append(defaults, def)
+		}
+	}
+	return defaults, nil
+}
+
+func (q *FakeQuerier) GetTemplatePresetsByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplatePreset, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	presets := make([]database.TemplatePreset, 0)
+	for _, preset := range q.templatePresets {
+		if preset.TemplateID == templateID {
+			presets = append(presets, preset)
+		}
+	}
+	slices.SortFunc(presets, func(a, b database.TemplatePreset) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return presets, nil
+}
+
 func (*FakeQuerier) GetTemplatePresetsWithPrebuilds(_ context.Context, _ uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	return nil, ErrUnimplemented
 }
 
+func (q *FakeQuerier) GetTemplateReleaseChannel(_ context.Context, arg database.GetTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, channel := range q.templateReleaseChannels {
+		if channel.TemplateID == arg.TemplateID && channel.Channel == arg.Channel {
+			return channel, nil
+		}
+	}
+	return database.TemplateReleaseChannel{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateReleaseChannelsByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplateReleaseChannel, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	channels := make([]database.TemplateReleaseChannel, 0)
+	for _, channel := range q.templateReleaseChannels {
+		if channel.TemplateID == templateID {
+			channels = append(channels, channel)
+		}
+	}
+	slices.SortFunc(channels, func(a, b database.TemplateReleaseChannel) int {
+		return strings.Compare(string(a.Channel), string(b.Channel))
+	})
+	return channels, nil
+}
+
+func (q *FakeQuerier) GetTemplateSharesByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplateShare, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	shares := make([]database.TemplateShare, 0)
+	for _, share := range q.templateShares {
+		if share.TemplateID == templateID {
+			shares = append(shares, share)
+		}
+	}
+	slices.SortFunc(shares, func(a, b database.TemplateShare) int {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return slice.Ascending(a.OrganizationID.String(), b.OrganizationID.String())
+	})
+	return shares, nil
+}
+
 func (q *FakeQuerier) GetTemplateUsageStats(_ context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -6214,6 +7964,58 @@ func (q *FakeQuerier) GetTemplateVersionParameters(_ context.Context, templateVe
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetTemplateVersionRolloutBuildCounts(ctx context.Context, arg database.GetTemplateVersionRolloutBuildCountsParams) ([]database.GetTemplateVersionRolloutBuildCountsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	counts := make(map[uuid.UUID]*database.GetTemplateVersionRolloutBuildCountsRow)
+	var rows []database.GetTemplateVersionRolloutBuildCountsRow
+	for _, build := range q.workspaceBuilds {
+		if build.CreatedAt.Before(arg.CreatedAfter) {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, build.WorkspaceID)
+		if err != nil || workspace.TemplateID != arg.TemplateID {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if job.JobStatus != database.ProvisionerJobStatusSucceeded && job.JobStatus != database.ProvisionerJobStatusFailed {
+			continue
+		}
+		if _, ok := counts[build.TemplateVersionID]; !ok {
+			counts[build.TemplateVersionID] = &database.GetTemplateVersionRolloutBuildCountsRow{TemplateVersionID: build.TemplateVersionID}
+		}
+		counts[build.TemplateVersionID].TotalBuilds++
+		if job.JobStatus == database.ProvisionerJobStatusFailed {
+			counts[build.TemplateVersionID].FailedBuilds++
+		}
+	}
+	for _, count := range counts {
+		rows = append(rows, *count)
+	}
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetTemplateVersionRolloutByTemplateID(_ context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, rollout := range q.templateVersionRollouts {
+		if rollout.TemplateID == templateID {
+			return rollout, nil
+		}
+	}
+	return database.TemplateVersionRollout{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -6362,6 +8164,18 @@ func (q *FakeQuerier) GetTemplateVersionsCreatedAfter(_ context.Context, after t
 	return versions, nil
 }
 
+func (q *FakeQuerier) GetTemplateWorkspaceNamingPolicy(_ context.Context, templateID uuid.UUID) (database.WorkspaceNamingPolicy, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, p := range q.workspaceNamingPolicies {
+		if p.TemplateID.Valid && p.TemplateID.UUID == templateID {
+			return p, nil
+		}
+	}
+	return database.WorkspaceNamingPolicy{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetTemplates(_ context.Context) ([]database.Template, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -6377,6 +8191,30 @@ func (q *FakeQuerier) GetTemplates(_ context.Context) ([]database.Template, erro
 	return q.templatesWithUserNoLock(templates), nil
 }
 
+func (q *FakeQuerier) GetTemplatesSharedWithOrganization(_ context.Context, organizationID uuid.UUID) ([]database.Template, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var templates []database.TemplateTable
+	for _, share := range q.templateShares {
+		if share.OrganizationID != organizationID {
+			continue
+		}
+		for _, tpl := range q.templates {
+			if tpl.ID == share.TemplateID && !tpl.Deleted {
+				templates = append(templates, tpl)
+			}
+		}
+	}
+	slices.SortFunc(templates, func(a, b database.TemplateTable) int {
+		if a.Name != b.Name {
+			return slice.Ascending(a.Name, b.Name)
+		}
+		return slice.Ascending(a.ID.String(), b.ID.String())
+	})
+	return q.templatesWithUserNoLock(templates), nil
+}
+
 func (q *FakeQuerier) GetTemplatesWithFilter(ctx context.Context, arg database.GetTemplatesWithFilterParams) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -6400,6 +8238,41 @@ func (q *FakeQuerier) GetUnexpiredLicenses(_ context.Context) ([]database.Licens
 	return results, nil
 }
 
+func (q *FakeQuerier) GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentHealthEvent, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	events := make([]database.WorkspaceAgentHealthEvent, 0)
+	for _, event := range q.workspaceAgentHealthEvents {
+		if event.ResolvedAt.Valid || !slices.Contains(ids, event.AgentID) {
+			continue
+		}
+		events = append(events, event)
+	}
+	slices.SortFunc(events, func(a, b database.WorkspaceAgentHealthEvent) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		if c := strings.Compare(string(a.Kind), string(b.Kind)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Volume, b.Volume)
+	})
+	return events, nil
+}
+
+func (q *FakeQuerier) GetUserActivityExportByID(_ context.Context, id uuid.UUID) (database.UserActivityExport, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, export := range q.userActivityExports {
+		if export.ID == id {
+			return export, nil
+		}
+	}
+	return database.UserActivityExport{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetUserActivityInsights(_ context.Context, arg database.GetUserActivityInsightsParams) ([]database.GetUserActivityInsightsRow, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -6579,15 +8452,91 @@ func (q *FakeQuerier) GetUserCount(_ context.Context, includeSystem bool) (int64
 		if !includeSystem && u.IsSystem {
 			continue
 		}
-		if !u.Deleted {
-			existing++
+		if !u.Deleted {
+			existing++
+		}
+
+		if !includeSystem && u.IsSystem {
+			continue
+		}
+	}
+	return existing, nil
+}
+
+func (q *FakeQuerier) GetUserDailyActivity(_ context.Context, arg database.GetUserDailyActivityParams) ([]database.GetUserDailyActivityRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	type dayUser struct {
+		day    time.Time
+		userID uuid.UUID
+	}
+	type usageKey struct {
+		startTime time.Time
+		userID    uuid.UUID
+	}
+	usageMins := make(map[usageKey]int16)
+	apps := make(map[dayUser][]string)
+	for _, stat := range q.templateUsageStats {
+		if stat.StartTime.Before(arg.StartTime) || !stat.StartTime.Before(arg.EndTime) {
+			continue
+		}
+		key := usageKey{startTime: stat.StartTime, userID: stat.UserID}
+		// See motivation in GetTemplateInsights for LEAST(SUM(n), 30).
+		usageMins[key] = least(usageMins[key]+stat.UsageMins, 30)
+		day := dayUser{day: stat.StartTime.UTC().Truncate(24 * time.Hour), userID: stat.UserID}
+		for app := range stat.AppUsageMins {
+			if !slices.Contains(apps[day], app) {
+				apps[day] = append(apps[day], app)
+			}
+		}
+	}
+	rows := make(map[dayUser]*database.GetUserDailyActivityRow)
+	row := func(key dayUser) *database.GetUserDailyActivityRow {
+		if r, ok := rows[key]; ok {
+			return r
+		}
+		rows[key] = &database.GetUserDailyActivityRow{
+			Date:   key.day,
+			UserID: key.userID,
+			Apps:   []string{},
+		}
+		return rows[key]
+	}
+	for key, mins := range usageMins {
+		row(dayUser{day: key.startTime.UTC().Truncate(24 * time.Hour), userID: key.userID}).UsageSeconds += int64(mins) * 60
+	}
+	for _, build := range q.workspaceBuilds {
+		if build.CreatedAt.Before(arg.StartTime) || !build.CreatedAt.Before(arg.EndTime) {
+			continue
 		}
+		row(dayUser{day: build.CreatedAt.UTC().Truncate(24 * time.Hour), userID: build.InitiatorID}).WorkspaceBuilds++
+	}
 
-		if !includeSystem && u.IsSystem {
+	result := make([]database.GetUserDailyActivityRow, 0, len(rows))
+	for key, r := range rows {
+		user, err := q.getUserByIDNoLock(key.userID)
+		if err != nil || user.IsSystem {
 			continue
 		}
+		r.Username = user.Username
+		if dayApps, ok := apps[key]; ok {
+			r.Apps = slices.Sorted(slices.Values(dayApps))
+		}
+		result = append(result, *r)
 	}
-	return existing, nil
+	slices.SortFunc(result, func(a, b database.GetUserDailyActivityRow) int {
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Compare(b.Date)
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+	return result, nil
 }
 
 func (q *FakeQuerier) GetUserLatencyInsights(_ context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
@@ -6706,6 +8655,34 @@ func (q *FakeQuerier) GetUserLinksByUserID(_ context.Context, userID uuid.UUID)
 	return uls, nil
 }
 
+func (q *FakeQuerier) GetUserNotificationBuildDurationThreshold(_ context.Context, userID uuid.UUID) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, uc := range q.userConfigs {
+		if uc.UserID != userID || uc.Key != "notification_build_duration_threshold_ms" {
+			continue
+		}
+		return uc.Value, nil
+	}
+
+	return "", sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserNotificationDigestFrequency(_ context.Context, userID uuid.UUID) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, uc := range q.userConfigs {
+		if uc.UserID != userID || uc.Key != "notification_digest_frequency" {
+			continue
+		}
+		return uc.Value, nil
+	}
+
+	return "", sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetUserNotificationPreferences(_ context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7026,6 +9003,42 @@ func (q *FakeQuerier) GetUsersByIDs(_ context.Context, ids []uuid.UUID) ([]datab
 	return users, nil
 }
 
+func (q *FakeQuerier) GetUsersWithDueNotificationDigests(_ context.Context, now time.Time) ([]database.GetUsersWithDueNotificationDigestsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	oldest := make(map[uuid.UUID]time.Time)
+	for _, nm := range q.notificationMessages {
+		if nm.Status != database.NotificationMessageStatusBatched {
+			continue
+		}
+		if createdAt, ok := oldest[nm.UserID]; !ok || nm.CreatedAt.Before(createdAt) {
+			oldest[nm.UserID] = nm.CreatedAt
+		}
+	}
+
+	var rows []database.GetUsersWithDueNotificationDigestsRow
+	for userID, createdAt := range oldest {
+		frequency, period := "daily", 24*time.Hour
+		for _, uc := range q.userConfigs {
+			if uc.UserID == userID && uc.Key == "notification_digest_frequency" {
+				frequency = uc.Value
+			}
+		}
+		if frequency == "weekly" {
+			period = 7 * 24 * time.Hour
+		}
+		if createdAt.Add(period).After(now) {
+			continue
+		}
+		rows = append(rows, database.GetUsersWithDueNotificationDigestsRow{
+			UserID:    userID,
+			Frequency: frequency,
+		})
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWebpushSubscriptionsByUserID(_ context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7142,6 +9155,60 @@ func (q *FakeQuerier) GetWorkspaceAgentByInstanceID(_ context.Context, instanceI
 	return database.WorkspaceAgent{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentConnectionQualityByAgentID(_ context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentConnectionQuality, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var qualities []database.WorkspaceAgentConnectionQuality
+	for _, quality := range q.workspaceAgentConnectionQuality {
+		if quality.AgentID == agentID {
+			qualities = append(qualities, quality)
+		}
+	}
+	slices.SortFunc(qualities, func(a, b database.WorkspaceAgentConnectionQuality) int {
+		if c := b.UpdatedAt.Compare(a.UpdatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Ip, b.Ip)
+	})
+	return qualities, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentConnectionTypeCounts(_ context.Context, updatedAfter time.Time) ([]database.GetWorkspaceAgentConnectionTypeCountsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	type connectionType struct {
+		p2p            bool
+		derpRegionCode string
+	}
+	counts := make(map[connectionType]int64)
+	for _, quality := range q.workspaceAgentConnectionQuality {
+		if !quality.UpdatedAt.After(updatedAfter) {
+			continue
+		}
+		counts[connectionType{p2p: quality.P2P, derpRegionCode: quality.DerpRegionCode}]++
+	}
+	rows := make([]database.GetWorkspaceAgentConnectionTypeCountsRow, 0, len(counts))
+	for typ, count := range counts {
+		rows = append(rows, database.GetWorkspaceAgentConnectionTypeCountsRow{
+			P2P:            typ.p2p,
+			DerpRegionCode: typ.derpRegionCode,
+			Count:          count,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceAgentConnectionTypeCountsRow) int {
+		if a.P2P != b.P2P {
+			if a.P2P {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.DerpRegionCode, b.DerpRegionCode)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentDevcontainersByAgentID(_ context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7248,6 +9315,70 @@ func (q *FakeQuerier) GetWorkspaceAgentPortShare(_ context.Context, arg database
 	return database.WorkspaceAgentPortShare{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentResourceUsageByWorkspaceID(ctx context.Context, arg database.GetWorkspaceAgentResourceUsageByWorkspaceIDParams) ([]database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	agentNames := make(map[uuid.UUID]string)
+	for _, build := range q.workspaceBuilds {
+		if build.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		resources, err := q.getWorkspaceResourcesByJobIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, err
+		}
+		resourceIDs := make([]uuid.UUID, 0, len(resources))
+		for _, resource := range resources {
+			resourceIDs = append(resourceIDs, resource.ID)
+		}
+		agents, err := q.getWorkspaceAgentsByResourceIDsNoLock(ctx, resourceIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, agent := range agents {
+			agentNames[agent.ID] = agent.Name
+		}
+	}
+
+	rows := make([]database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow, 0)
+	for _, usage := range q.workspaceAgentResourceUsage {
+		name, ok := agentNames[usage.AgentID]
+		if !ok {
+			continue
+		}
+		if usage.BucketStart.Before(arg.StartTime) || !usage.BucketStart.Before(arg.EndTime) {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow{
+			AgentID:       usage.AgentID,
+			BucketStart:   usage.BucketStart,
+			Samples:       usage.Samples,
+			CPUUsedAvg:    usage.CPUUsedAvg,
+			CPUUsedMax:    usage.CPUUsedMax,
+			CPUTotal:      usage.CPUTotal,
+			MemoryUsedAvg: usage.MemoryUsedAvg,
+			MemoryUsedMax: usage.MemoryUsedMax,
+			MemoryTotal:   usage.MemoryTotal,
+			DiskUsed:      usage.DiskUsed,
+			DiskTotal:     usage.DiskTotal,
+			AgentName:     name,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow) int {
+		if c := strings.Compare(a.AgentName, b.AgentName); c != 0 {
+			return c
+		}
+		return a.BucketStart.Compare(b.BucketStart)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7858,6 +9989,18 @@ func (q *FakeQuerier) GetWorkspaceAppsCreatedAfter(_ context.Context, after time
 	return apps, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceBuildArchiveByBuildID(_ context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildArchive, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, archive := range q.workspaceBuildArchives {
+		if archive.WorkspaceBuildID == workspaceBuildID {
+			return archive, nil
+		}
+	}
+	return database.WorkspaceBuildArchive{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7897,6 +10040,76 @@ func (q *FakeQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(_ context.Con
 	return database.WorkspaceBuild{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceBuildIdempotencyKey(_ context.Context, arg database.GetWorkspaceBuildIdempotencyKeyParams) (database.WorkspaceBuildIdempotencyKey, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceBuildIdempotencyKey{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.workspaceBuildIdempotencyKeys {
+		if key.UserID == arg.UserID && key.IdempotencyKey == arg.IdempotencyKey {
+			return key, nil
+		}
+	}
+	return database.WorkspaceBuildIdempotencyKey{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceBuildInterimStateByBuildID(_ context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildInterimState, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, state := range q.workspaceBuildInterimStates {
+		if state.WorkspaceBuildID == workspaceBuildID {
+			return state, nil
+		}
+	}
+	return database.WorkspaceBuildInterimState{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceBuildOutcomesCompletedBetween(ctx context.Context, arg database.GetWorkspaceBuildOutcomesCompletedBetweenParams) ([]database.GetWorkspaceBuildOutcomesCompletedBetweenRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspaceBuildOutcomesCompletedBetweenRow, 0)
+	for _, wb := range q.workspaceBuilds {
+		job, err := q.getProvisionerJobByIDNoLock(ctx, wb.JobID)
+		if err != nil {
+			return nil, xerrors.Errorf("get provisioner job by ID: %w", err)
+		}
+		if !job.CompletedAt.Valid || !job.CompletedAt.Time.After(arg.CompletedAfter) || job.CompletedAt.Time.After(arg.CompletedBefore) {
+			continue
+		}
+		w, err := q.getWorkspaceByIDNoLock(ctx, wb.WorkspaceID)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace by ID: %w", err)
+		}
+		t, err := q.getTemplateByIDNoLock(ctx, w.TemplateID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template by ID: %w", err)
+		}
+		rows = append(rows, database.GetWorkspaceBuildOutcomesCompletedBetweenRow{
+			TemplateName: t.Name,
+			Transition:   wb.Transition,
+			JobStatus:    job.JobStatus,
+			ErrorCode:    job.ErrorCode,
+			StartedAt:    job.StartedAt,
+			CompletedAt:  job.CompletedAt,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceBuildOutcomesCompletedBetweenRow) int {
+		return a.CompletedAt.Time.Compare(b.CompletedAt.Time)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceBuildParameters(_ context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7909,6 +10122,34 @@ func (q *FakeQuerier) GetWorkspaceBuildParametersByBuildIDs(ctx context.Context,
 	return q.GetAuthorizedWorkspaceBuildParametersByBuildIDs(ctx, workspaceBuildIDs, nil)
 }
 
+func (q *FakeQuerier) GetWorkspaceBuildQueueByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceBuildQueue, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	entries := make([]database.WorkspaceBuildQueue, 0)
+	for _, entry := range q.workspaceBuildQueue {
+		if entry.WorkspaceID == workspaceID {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b database.WorkspaceBuildQueue) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return entries, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceBuildQueueEntryByID(_ context.Context, id uuid.UUID) (database.WorkspaceBuildQueue, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, entry := range q.workspaceBuildQueue {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return database.WorkspaceBuildQueue{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]database.GetWorkspaceBuildStatsByTemplatesRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8042,6 +10283,62 @@ func (q *FakeQuerier) GetWorkspaceBuildsCreatedAfter(_ context.Context, after ti
 	return workspaceBuilds, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceBuildsToArchive(ctx context.Context, arg database.GetWorkspaceBuildsToArchiveParams) ([]database.GetWorkspaceBuildsToArchiveRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	latest := make(map[uuid.UUID]int32)
+	for _, build := range q.workspaceBuilds {
+		if build.BuildNumber > latest[build.WorkspaceID] {
+			latest[build.WorkspaceID] = build.BuildNumber
+		}
+	}
+	archived := make(map[uuid.UUID]bool)
+	for _, archive := range q.workspaceBuildArchives {
+		archived[archive.WorkspaceBuildID] = true
+	}
+
+	type buildWithJob struct {
+		build database.WorkspaceBuild
+		job   database.ProvisionerJob
+	}
+	builds := make([]buildWithJob, 0)
+	for _, build := range q.workspaceBuilds {
+		if build.BuildNumber >= latest[build.WorkspaceID] || archived[build.ID] {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			continue
+		}
+		if !job.CompletedAt.Valid || !job.CompletedAt.Time.Before(arg.CompletedBefore) {
+			continue
+		}
+		builds = append(builds, buildWithJob{build: build, job: job})
+	}
+	slices.SortFunc(builds, func(a, b buildWithJob) int {
+		return a.job.CompletedAt.Time.Compare(b.job.CompletedAt.Time)
+	})
+	if arg.LimitCount >= 0 && len(builds) > int(arg.LimitCount) {
+		builds = builds[:arg.LimitCount]
+	}
+
+	rows := make([]database.GetWorkspaceBuildsToArchiveRow, 0, len(builds))
+	for _, b := range builds {
+		rows = append(rows, database.GetWorkspaceBuildsToArchiveRow{
+			ID:          b.build.ID,
+			JobID:       b.build.JobID,
+			InitiatorID: b.build.InitiatorID,
+		})
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.Workspace, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8061,6 +10358,49 @@ func (q *FakeQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (datab
 	return q.getWorkspaceByIDNoLock(ctx, id)
 }
 
+func (q *FakeQuerier) GetWorkspaceByIDForUpdate(_ context.Context, id uuid.UUID) (database.WorkspaceTable, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, workspace := range q.workspaces {
+		if workspace.ID == id {
+			return workspace, nil
+		}
+	}
+	return database.WorkspaceTable{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceByOrganizationIDAndName(_ context.Context, arg database.GetWorkspaceByOrganizationIDAndNameParams) (database.Workspace, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Workspace{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var found *database.WorkspaceTable
+	for _, workspace := range q.workspaces {
+		if workspace.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		if !strings.EqualFold(workspace.Name, arg.Name) {
+			continue
+		}
+		if workspace.Deleted {
+			continue
+		}
+
+		// Return the most recent workspace with the given name
+		if found == nil || workspace.CreatedAt.After(found.CreatedAt) {
+			found = &workspace
+		}
+	}
+	if found != nil {
+		return q.extendWorkspace(*found), nil
+	}
+	return database.Workspace{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceByOwnerIDAndName(_ context.Context, arg database.GetWorkspaceByOwnerIDAndNameParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -8135,6 +10475,220 @@ func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceA
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceDailyCosts(ctx context.Context, arg database.GetWorkspaceDailyCostsParams) ([]database.GetWorkspaceDailyCostsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var rows []database.GetWorkspaceDailyCostsRow
+	for _, cost := range q.workspaceDailyCosts {
+		if cost.StartTime.Before(arg.StartTime) || !cost.StartTime.Before(arg.EndTime) {
+			continue
+		}
+		if len(arg.TemplateIDs) > 0 && !slices.Contains(arg.TemplateIDs, cost.TemplateID) {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, cost.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		owner, err := q.getUserByIDNoLock(cost.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+		org, err := q.getOrganizationByIDNoLock(cost.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, database.GetWorkspaceDailyCostsRow{
+			StartTime:        cost.StartTime,
+			EndTime:          cost.EndTime,
+			WorkspaceID:      cost.WorkspaceID,
+			WorkspaceName:    workspace.Name,
+			OwnerID:          cost.OwnerID,
+			OwnerUsername:    owner.Username,
+			OrganizationID:   cost.OrganizationID,
+			OrganizationName: org.Name,
+			TemplateID:       cost.TemplateID,
+			Cost:             cost.Cost,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceDailyCostsRow) int {
+		if c := a.StartTime.Compare(b.StartTime); c != 0 {
+			return c
+		}
+		return slice.Ascending(a.WorkspaceID.String(), b.WorkspaceID.String())
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceDriftCheckCandidates(ctx context.Context, arg database.GetWorkspaceDriftCheckCandidatesParams) ([]database.GetWorkspaceDriftCheckCandidatesRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	checkedAt := make(map[uuid.UUID]time.Time, len(q.workspaceDriftChecks))
+	for _, check := range q.workspaceDriftChecks {
+		checkedAt[check.WorkspaceID] = check.CreatedAt
+	}
+
+	candidates := make([]database.GetWorkspaceDriftCheckCandidatesRow, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.OwnerID == database.PrebuildsSystemUserID {
+			continue
+		}
+		if at, ok := checkedAt[workspace.ID]; ok && !at.Before(arg.CheckedBefore) {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil {
+			continue
+		}
+		buildJob, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, err
+		}
+		status := provisionerJobStatus(buildJob)
+		stopped := build.Transition == database.WorkspaceTransitionStop && status == database.ProvisionerJobStatusSucceeded
+		failed := build.Transition != database.WorkspaceTransitionDelete && status == database.ProvisionerJobStatusFailed
+		if !stopped && !failed {
+			continue
+		}
+		templateVersion, err := q.getTemplateVersionByIDNoLock(ctx, build.TemplateVersionID)
+		if err != nil {
+			return nil, err
+		}
+		templateVersionJob, err := q.getProvisionerJobByIDNoLock(ctx, templateVersion.JobID)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, database.GetWorkspaceDriftCheckCandidatesRow{
+			WorkspaceID:       workspace.ID,
+			OwnerID:           workspace.OwnerID,
+			OrganizationID:    workspace.OrganizationID,
+			WorkspaceBuildID:  build.ID,
+			TemplateVersionID: build.TemplateVersionID,
+			Tags:              buildJob.Tags,
+			FileID:            templateVersionJob.FileID,
+			Provisioner:       templateVersionJob.Provisioner,
+		})
+	}
+
+	// Workspaces that were never checked go first.
+	slices.SortStableFunc(candidates, func(a, b database.GetWorkspaceDriftCheckCandidatesRow) int {
+		atA, okA := checkedAt[a.WorkspaceID]
+		atB, okB := checkedAt[b.WorkspaceID]
+		switch {
+		case !okA && !okB:
+			return 0
+		case !okA:
+			return -1
+		case !okB:
+			return 1
+		}
+		return atA.Compare(atB)
+	})
+	if len(candidates) > int(arg.LimitCount) {
+		candidates = candidates[:arg.LimitCount]
+	}
+	return candidates, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceDriftChecksByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetWorkspaceDriftChecksByWorkspaceIDsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	checks := make([]database.GetWorkspaceDriftChecksByWorkspaceIDsRow, 0)
+	for _, check := range q.workspaceDriftChecks {
+		if !slices.Contains(ids, check.WorkspaceID) {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, check.JobID)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, database.GetWorkspaceDriftChecksByWorkspaceIDsRow{
+			WorkspaceID:       check.WorkspaceID,
+			WorkspaceBuildID:  check.WorkspaceBuildID,
+			JobID:             check.JobID,
+			CreatedAt:         check.CreatedAt,
+			CompletedAt:       check.CompletedAt,
+			DriftedResources:  check.DriftedResources,
+			OrphanedResources: check.OrphanedResources,
+			JobStatus:         provisionerJobStatus(job),
+		})
+	}
+	return checks, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceLabelsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceLabel, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	labels := make([]database.WorkspaceLabel, 0)
+	for _, label := range q.workspaceLabels {
+		if label.WorkspaceID == workspaceID {
+			labels = append(labels, label)
+		}
+	}
+	slices.SortFunc(labels, func(a, b database.WorkspaceLabel) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return labels, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceLocksByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceLock, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var locks []database.WorkspaceLock
+	for _, lock := range q.workspaceLocks {
+		if lock.WorkspaceID == workspaceID {
+			locks = append(locks, lock)
+		}
+	}
+	slices.SortFunc(locks, func(a, b database.WorkspaceLock) int {
+		return b.LockedAt.Compare(a.LockedAt)
+	})
+	return locks, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceMigrationByID(_ context.Context, id uuid.UUID) (database.WorkspaceMigration, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, migration := range q.workspaceMigrations {
+		if migration.ID == id {
+			return migration, nil
+		}
+	}
+	return database.WorkspaceMigration{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceMigrationsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceMigration, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var migrations []database.WorkspaceMigration
+	for _, migration := range q.workspaceMigrations {
+		if migration.WorkspaceID == workspaceID {
+			migrations = append(migrations, migration)
+		}
+	}
+	slices.SortFunc(migrations, func(a, b database.WorkspaceMigration) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return migrations, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceModulesByJobID(_ context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8161,6 +10715,53 @@ func (q *FakeQuerier) GetWorkspaceModulesCreatedAfter(_ context.Context, created
 	return modules, nil
 }
 
+func (q *FakeQuerier) GetWorkspacePrebuildReservationByWorkspaceID(_ context.Context, workspaceID uuid.UUID) (database.WorkspacePrebuildReservation, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, reservation := range q.workspacePrebuildReservations {
+		if reservation.WorkspaceID == workspaceID {
+			return reservation, nil
+		}
+	}
+	return database.WorkspacePrebuildReservation{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspacePrebuildReservationsByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.WorkspacePrebuildReservation, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var reservations []database.WorkspacePrebuildReservation
+	for _, reservation := range q.workspacePrebuildReservations {
+		for _, workspace := range q.workspaces {
+			if workspace.ID == reservation.WorkspaceID && workspace.TemplateID == templateID &&
+				workspace.OwnerID == database.PrebuildsSystemUserID && !workspace.Deleted {
+				reservations = append(reservations, reservation)
+				break
+			}
+		}
+	}
+	slices.SortFunc(reservations, func(a, b database.WorkspacePrebuildReservation) int {
+		if c := a.ExpiresAt.Compare(b.ExpiresAt); c != 0 {
+			return c
+		}
+		return slice.Ascending(a.WorkspaceID.String(), b.WorkspaceID.String())
+	})
+	return reservations, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceProvisionerAffinity(_ context.Context, workspaceID uuid.UUID) (database.WorkspaceProvisionerAffinity, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, affinity := range q.workspaceProvisionerAffinities {
+		if affinity.WorkspaceID == workspaceID {
+			return affinity, nil
+		}
+	}
+	return database.WorkspaceProvisionerAffinity{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceProxies(_ context.Context) ([]database.WorkspaceProxy, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8238,6 +10839,18 @@ func (q *FakeQuerier) GetWorkspaceProxyByName(_ context.Context, name string) (d
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceReleaseChannelByWorkspaceID(_ context.Context, workspaceID uuid.UUID) (database.WorkspaceReleaseChannel, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, channel := range q.workspaceReleaseChannels {
+		if channel.WorkspaceID == workspaceID {
+			return channel, nil
+		}
+	}
+	return database.WorkspaceReleaseChannel{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceResourceByID(_ context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8325,6 +10938,47 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceSessionRecordingByID(_ context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID == id {
+			return recording, nil
+		}
+	}
+	return database.WorkspaceSessionRecording{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingChunks(_ context.Context, recordingID uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	chunks := make([]database.WorkspaceSessionRecordingChunk, 0)
+	for _, chunk := range q.workspaceSessionRecordingChunks {
+		if chunk.RecordingID == recordingID {
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	recordings := make([]database.WorkspaceSessionRecording, 0)
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.WorkspaceID == workspaceID {
+			recordings = append(recordings, recording)
+		}
+	}
+	slices.SortFunc(recordings, func(a, b database.WorkspaceSessionRecording) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+	return recordings, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(_ context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8535,11 +11189,43 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		LoginType:       arg.LoginType,
 		Scope:           arg.Scope,
 		TokenName:       arg.TokenName,
+		BoundIdentity:   arg.BoundIdentity,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
 }
 
+func (q *FakeQuerier) InsertAccessRequest(_ context.Context, arg database.InsertAccessRequestParams) (database.AccessRequest, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.AccessRequest{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, req := range q.accessRequests {
+		if req.RequesterID == arg.RequesterID && req.ResourceID == arg.ResourceID &&
+			(req.Status == database.AccessRequestStatusPending || req.Status == database.AccessRequestStatusApproved) {
+			return database.AccessRequest{}, newUniqueConstraintError(database.UniqueAccessRequestsOpenIndex)
+		}
+	}
+	req := database.AccessRequest{
+		ID:             arg.ID,
+		OrganizationID: arg.OrganizationID,
+		RequesterID:    arg.RequesterID,
+		ResourceType:   arg.ResourceType,
+		ResourceID:     arg.ResourceID,
+		Role:           arg.Role,
+		Reason:         arg.Reason,
+		DurationMs:     arg.DurationMs,
+		Status:         database.AccessRequestStatusPending,
+		CreatedAt:      arg.CreatedAt,
+	}
+	q.accessRequests = append(q.accessRequests, req)
+	return req, nil
+}
+
 func (q *FakeQuerier) InsertAllUsersGroup(ctx context.Context, orgID uuid.UUID) (database.Group, error) {
 	return q.InsertGroup(ctx, database.InsertGroupParams{
 		ID:             orgID,
@@ -8563,17 +11249,43 @@ func (q *FakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 
 	q.auditLogs = append(q.auditLogs, alog)
 	slices.SortFunc(q.auditLogs, func(a, b database.AuditLog) int {
-		if a.Time.Before(b.Time) {
-			return -1
-		} else if a.Time.Equal(b.Time) {
-			return 0
-		}
-		return 1
+		return compareAuditLogs(b, a)
 	})
 
 	return alog, nil
 }
 
+func (q *FakeQuerier) InsertBuildAlertRule(_ context.Context, arg database.InsertBuildAlertRuleParams) (database.BuildAlertRule, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.BuildAlertRule{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, rule := range q.buildAlertRules {
+		if rule.OrganizationID == arg.OrganizationID && strings.EqualFold(rule.Name, arg.Name) {
+			return database.BuildAlertRule{}, newUniqueConstraintError(database.UniqueBuildAlertRulesOrganizationIDNameIndex)
+		}
+	}
+
+	rule := database.BuildAlertRule{
+		ID:             arg.ID,
+		OrganizationID: arg.OrganizationID,
+		TemplateID:     arg.TemplateID,
+		Name:           arg.Name,
+		Metric:         arg.Metric,
+		Threshold:      arg.Threshold,
+		WindowMs:       arg.WindowMs,
+		CreatedBy:      arg.CreatedBy,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+	}
+	q.buildAlertRules = append(q.buildAlertRules, rule)
+	return rule, nil
+}
+
 func (q *FakeQuerier) InsertCryptoKey(_ context.Context, arg database.InsertCryptoKeyParams) (database.CryptoKey, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -8757,6 +11469,7 @@ func (q *FakeQuerier) InsertGroup(_ context.Context, arg database.InsertGroupPar
 		OrganizationID: arg.OrganizationID,
 		AvatarURL:      arg.AvatarURL,
 		QuotaAllowance: arg.QuotaAllowance,
+		MonthlyBudget:  arg.MonthlyBudget,
 		Source:         database.GroupSourceUser,
 	}
 
@@ -8834,6 +11547,35 @@ func (q *FakeQuerier) InsertLicense(
 	return l, nil
 }
 
+func (q *FakeQuerier) InsertLoginDeviceCode(_ context.Context, arg database.InsertLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.LoginDeviceCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.loginDeviceCodes {
+		if bytes.Equal(code.DeviceCodeHash, arg.DeviceCodeHash) {
+			return database.LoginDeviceCode{}, newUniqueConstraintError(database.UniqueLoginDeviceCodesDeviceCodeHashKey)
+		}
+		if code.UserCode == arg.UserCode {
+			return database.LoginDeviceCode{}, newUniqueConstraintError(database.UniqueLoginDeviceCodesUserCodeKey)
+		}
+	}
+
+	code := database.LoginDeviceCode{
+		ID:             arg.ID,
+		DeviceCodeHash: arg.DeviceCodeHash,
+		UserCode:       arg.UserCode,
+		CreatedAt:      arg.CreatedAt,
+		ExpiresAt:      arg.ExpiresAt,
+	}
+	q.loginDeviceCodes = append(q.loginDeviceCodes, code)
+	return code, nil
+}
+
 func (q *FakeQuerier) InsertMemoryResourceMonitor(_ context.Context, arg database.InsertMemoryResourceMonitorParams) (database.WorkspaceAgentMemoryResourceMonitor, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9024,6 +11766,7 @@ func (q *FakeQuerier) InsertOrganization(_ context.Context, arg database.InsertO
 		DisplayName: arg.DisplayName,
 		Description: arg.Description,
 		Icon:        arg.Icon,
+		CostCenter:  arg.CostCenter,
 		CreatedAt:   arg.CreatedAt,
 		UpdatedAt:   arg.UpdatedAt,
 		IsDefault:   len(q.organizations) == 0,
@@ -9136,6 +11879,36 @@ func (q *FakeQuerier) InsertPresetPrebuildSchedule(ctx context.Context, arg data
 	return presetPrebuildSchedule, nil
 }
 
+func (q *FakeQuerier) InsertProvisionerBuildPause(_ context.Context, arg database.InsertProvisionerBuildPauseParams) (database.ProvisionerBuildPause, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.ProvisionerBuildPause{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, pause := range q.provisionerBuildPauses {
+		if pause.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		if arg.OrganizationID.Valid {
+			return database.ProvisionerBuildPause{}, newUniqueConstraintError(database.UniqueProvisionerBuildPausesOrganizationIDIndex)
+		}
+		return database.ProvisionerBuildPause{}, newUniqueConstraintError(database.UniqueProvisionerBuildPausesDeploymentIndex)
+	}
+
+	pause := database.ProvisionerBuildPause{
+		ID:             arg.ID,
+		OrganizationID: arg.OrganizationID,
+		Reason:         arg.Reason,
+		CreatedBy:      arg.CreatedBy,
+		CreatedAt:      arg.CreatedAt,
+	}
+	q.provisionerBuildPauses = append(q.provisionerBuildPauses, pause)
+	return pause, nil
+}
+
 func (q *FakeQuerier) InsertProvisionerJob(_ context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -9158,9 +11931,33 @@ func (q *FakeQuerier) InsertProvisionerJob(_ context.Context, arg database.Inser
 		Tags:           maps.Clone(arg.Tags),
 		TraceMetadata:  arg.TraceMetadata,
 	}
-	job.JobStatus = provisionerJobStatus(job)
-	q.provisionerJobs = append(q.provisionerJobs, job)
-	return job, nil
+	job.JobStatus = provisionerJobStatus(job)
+	q.provisionerJobs = append(q.provisionerJobs, job)
+	return job, nil
+}
+
+func (q *FakeQuerier) InsertProvisionerJobLogArchive(_ context.Context, arg database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.ProvisionerJobLogArchive{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, archive := range q.provisionerJobLogArchives {
+		if archive.JobID == arg.JobID {
+			return database.ProvisionerJobLogArchive{}, errUniqueConstraint
+		}
+	}
+	archive := database.ProvisionerJobLogArchive{
+		JobID:      arg.JobID,
+		FileID:     arg.FileID,
+		LogCount:   arg.LogCount,
+		ArchivedAt: arg.ArchivedAt,
+	}
+	q.provisionerJobLogArchives = append(q.provisionerJobLogArchives, archive)
+	return archive, nil
 }
 
 func (q *FakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
@@ -9178,6 +11975,10 @@ func (q *FakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.I
 	}
 	for index, output := range arg.Output {
 		id++
+		fields := json.RawMessage("{}")
+		if index < len(arg.Fields) {
+			fields = json.RawMessage(arg.Fields[index])
+		}
 		logs = append(logs, database.ProvisionerJobLog{
 			ID:        id,
 			JobID:     arg.JobID,
@@ -9186,6 +11987,7 @@ func (q *FakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.I
 			Level:     arg.Level[index],
 			Stage:     arg.Stage[index],
 			Output:    output,
+			Fields:    fields,
 		})
 	}
 	q.provisionerJobLogs = append(q.provisionerJobLogs, logs...)
@@ -9242,12 +12044,35 @@ func (q *FakeQuerier) InsertProvisionerKey(_ context.Context, arg database.Inser
 		Name:           strings.ToLower(arg.Name),
 		HashedSecret:   arg.HashedSecret,
 		Tags:           arg.Tags,
+		ExpiresAt:      arg.ExpiresAt,
 	}
 	q.provisionerKeys = append(q.provisionerKeys, provisionerKey)
 
 	return provisionerKey, nil
 }
 
+func (q *FakeQuerier) InsertProvisionerReservation(_ context.Context, arg database.InsertProvisionerReservationParams) (database.ProvisionerReservation, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.ProvisionerReservation{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	reservation := database.ProvisionerReservation{
+		ID:             arg.ID,
+		OrganizationID: arg.OrganizationID,
+		Tags:           maps.Clone(arg.Tags),
+		Slots:          arg.Slots,
+		StartsAt:       arg.StartsAt,
+		EndsAt:         arg.EndsAt,
+		CreatedAt:      arg.CreatedAt,
+	}
+	q.provisionerReservations = append(q.provisionerReservations, reservation)
+	return reservation, nil
+}
+
 func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Replica{}, err
@@ -9272,6 +12097,25 @@ func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplic
 	return replica, nil
 }
 
+func (q *FakeQuerier) InsertServiceAccount(_ context.Context, arg database.InsertServiceAccountParams) (database.ServiceAccount, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.ServiceAccount{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, serviceAccount := range q.serviceAccounts {
+		if serviceAccount.UserID == arg.UserID {
+			return database.ServiceAccount{}, newUniqueConstraintError(database.UniqueServiceAccountsPkey)
+		}
+	}
+	serviceAccount := database.ServiceAccount(arg)
+	q.serviceAccounts = append(q.serviceAccounts, serviceAccount)
+	return serviceAccount, nil
+}
+
 func (q *FakeQuerier) InsertTelemetryItemIfNotExists(_ context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9329,6 +12173,41 @@ func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 	return nil
 }
 
+func (q *FakeQuerier) InsertTemplatePreset(_ context.Context, arg database.InsertTemplatePresetParams) (database.TemplatePreset, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplatePreset{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, preset := range q.templatePresets {
+		if preset.TemplateID == arg.TemplateID && preset.Name == arg.Name {
+			return database.TemplatePreset{}, &pq.Error{
+				Code:       "23505",
+				Message:    "duplicate key value violates unique constraint \"template_presets_template_id_name_key\"",
+				Constraint: string(database.UniqueTemplatePresetsTemplateIDNameKey),
+			}
+		}
+	}
+
+	preset := database.TemplatePreset{
+		ID:          arg.ID,
+		TemplateID:  arg.TemplateID,
+		Name:        arg.Name,
+		Description: arg.Description,
+		Parameters:  maps.Clone(arg.Parameters),
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+	}
+	if preset.Parameters == nil {
+		preset.Parameters = database.StringMap{}
+	}
+	q.templatePresets = append(q.templatePresets, preset)
+	return preset, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersion(_ context.Context, arg database.InsertTemplateVersionParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -9443,6 +12322,7 @@ func (q *FakeQuerier) InsertTemplateVersionVariable(_ context.Context, arg datab
 		DefaultValue:      arg.DefaultValue,
 		Required:          arg.Required,
 		Sensitive:         arg.Sensitive,
+		ValueKeyID:        arg.ValueKeyID,
 	}
 	q.templateVersionVariables = append(q.templateVersionVariables, variable)
 	return variable, nil
@@ -9512,6 +12392,29 @@ func (q *FakeQuerier) InsertUser(_ context.Context, arg database.InsertUserParam
 	return user, nil
 }
 
+func (q *FakeQuerier) InsertUserActivityExport(_ context.Context, arg database.InsertUserActivityExportParams) (database.UserActivityExport, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserActivityExport{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	export := database.UserActivityExport{
+		ID:        arg.ID,
+		CreatedBy: arg.CreatedBy,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		StartTime: arg.StartTime,
+		EndTime:   arg.EndTime,
+		Format:    arg.Format,
+		Status:    database.UserActivityExportStatusPending,
+	}
+	q.userActivityExports = append(q.userActivityExports, export)
+	return export, nil
+}
+
 func (q *FakeQuerier) InsertUserGroupsByID(_ context.Context, arg database.InsertUserGroupsByIDParams) ([]uuid.UUID, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9658,6 +12561,8 @@ func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		LastUsedAt:        arg.LastUsedAt,
 		AutomaticUpdates:  arg.AutomaticUpdates,
 		NextStartAt:       arg.NextStartAt,
+		GroupACL:          database.WorkspaceACL{},
+		UserACL:           database.WorkspaceACL{},
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -9693,6 +12598,8 @@ func (q *FakeQuerier) InsertWorkspaceAgent(_ context.Context, arg database.Inser
 		DisplayApps:              arg.DisplayApps,
 		DisplayOrder:             arg.DisplayOrder,
 		APIKeyScope:              arg.APIKeyScope,
+		DisplayGroup:             arg.DisplayGroup,
+		Collapsed:                arg.Collapsed,
 	}
 
 	q.workspaceAgents = append(q.workspaceAgents, agent)
@@ -9729,6 +12636,34 @@ func (q *FakeQuerier) InsertWorkspaceAgentDevcontainers(_ context.Context, arg d
 	return nil, errForeignKeyConstraint
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentHealthEvent(_ context.Context, arg database.InsertWorkspaceAgentHealthEventParams) (database.WorkspaceAgentHealthEvent, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentHealthEvent{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, event := range q.workspaceAgentHealthEvents {
+		if event.AgentID == arg.AgentID && event.Kind == arg.Kind && event.Volume == arg.Volume && !event.ResolvedAt.Valid {
+			return database.WorkspaceAgentHealthEvent{}, sql.ErrNoRows
+		}
+	}
+
+	event := database.WorkspaceAgentHealthEvent{
+		ID:        arg.ID,
+		AgentID:   arg.AgentID,
+		Kind:      arg.Kind,
+		Volume:    arg.Volume,
+		Used:      arg.Used,
+		Total:     arg.Total,
+		CreatedAt: arg.CreatedAt,
+	}
+	q.workspaceAgentHealthEvents = append(q.workspaceAgentHealthEvents, event)
+	return event, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentLogSources(_ context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9854,6 +12789,7 @@ func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg databas
 			StartBlocksLogin: arg.StartBlocksLogin[index],
 			RunOnStart:       arg.RunOnStart[index],
 			RunOnStop:        arg.RunOnStop[index],
+			RunOnClaim:       arg.RunOnClaim[index],
 			TimeoutSeconds:   arg.TimeoutSeconds[index],
 			CreatedAt:        arg.CreatedAt,
 		}
@@ -9991,11 +12927,79 @@ func (q *FakeQuerier) InsertWorkspaceBuild(_ context.Context, arg database.Inser
 		MaxDeadline:             arg.MaxDeadline,
 		Reason:                  arg.Reason,
 		TemplateVersionPresetID: arg.TemplateVersionPresetID,
+		InitiatorContext:        arg.InitiatorContext,
+		Warnings:                arg.Warnings,
 	}
 	q.workspaceBuilds = append(q.workspaceBuilds, workspaceBuild)
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceBuildArchive(_ context.Context, arg database.InsertWorkspaceBuildArchiveParams) (database.WorkspaceBuildArchive, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceBuildArchive{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, archive := range q.workspaceBuildArchives {
+		if archive.WorkspaceBuildID == arg.WorkspaceBuildID {
+			return database.WorkspaceBuildArchive{}, errUniqueConstraint
+		}
+	}
+	archive := database.WorkspaceBuildArchive{
+		WorkspaceBuildID:      arg.WorkspaceBuildID,
+		FileID:                arg.FileID,
+		StateSize:             arg.StateSize,
+		ResourceMetadataCount: arg.ResourceMetadataCount,
+		ArchivedAt:            arg.ArchivedAt,
+	}
+	q.workspaceBuildArchives = append(q.workspaceBuildArchives, archive)
+	return archive, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceBuildDurationNotification(_ context.Context, arg database.InsertWorkspaceBuildDurationNotificationParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, n := range q.workspaceBuildDurationNotifications {
+		if n.WorkspaceBuildID == arg.WorkspaceBuildID {
+			return 0, nil
+		}
+	}
+	q.workspaceBuildDurationNotifications = append(q.workspaceBuildDurationNotifications, database.WorkspaceBuildDurationNotification(arg))
+	return 1, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceBuildIdempotencyKey(_ context.Context, arg database.InsertWorkspaceBuildIdempotencyKeyParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, key := range q.workspaceBuildIdempotencyKeys {
+		if key.UserID == arg.UserID && key.IdempotencyKey == arg.IdempotencyKey {
+			return newUniqueConstraintError(database.UniqueWorkspaceBuildIdempotencyKeysUserIDIdempotencyKeyKey)
+		}
+	}
+	q.workspaceBuildIdempotencyKeys = append(q.workspaceBuildIdempotencyKeys, database.WorkspaceBuildIdempotencyKey{
+		UserID:         arg.UserID,
+		IdempotencyKey: arg.IdempotencyKey,
+		CreatedAt:      arg.CreatedAt,
+		RequestHash:    arg.RequestHash,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceBuildParameters(_ context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -10014,6 +13018,120 @@ func (q *FakeQuerier) InsertWorkspaceBuildParameters(_ context.Context, arg data
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceBuildQueueEntry(_ context.Context, arg database.InsertWorkspaceBuildQueueEntryParams) (database.WorkspaceBuildQueue, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceBuildQueue{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	richParameterValues := arg.RichParameterValues
+	if len(richParameterValues) == 0 {
+		richParameterValues = json.RawMessage("[]")
+	}
+	entry := database.WorkspaceBuildQueue{
+		ID:                      arg.ID,
+		WorkspaceID:             arg.WorkspaceID,
+		InitiatorID:             arg.InitiatorID,
+		Transition:              arg.Transition,
+		TemplateVersionID:       arg.TemplateVersionID,
+		TemplateVersionPresetID: arg.TemplateVersionPresetID,
+		RichParameterValues:     richParameterValues,
+		LogLevel:                arg.LogLevel,
+		CreatedAt:               arg.CreatedAt,
+	}
+	q.workspaceBuildQueue = append(q.workspaceBuildQueue, entry)
+	return entry, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceLabels(_ context.Context, arg database.InsertWorkspaceLabelsParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if len(arg.Keys) != len(arg.Values) {
+		return xerrors.Errorf("keys and values must have the same length")
+	}
+	for i, key := range arg.Keys {
+		if slices.ContainsFunc(q.workspaceLabels, func(label database.WorkspaceLabel) bool {
+			return label.WorkspaceID == arg.WorkspaceID && label.Key == key
+		}) {
+			return newUniqueConstraintError(database.UniqueWorkspaceLabelsPkey)
+		}
+		q.workspaceLabels = append(q.workspaceLabels, database.WorkspaceLabel{
+			WorkspaceID: arg.WorkspaceID,
+			Key:         key,
+			Value:       arg.Values[i],
+			CreatedAt:   arg.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceLock(_ context.Context, arg database.InsertWorkspaceLockParams) (database.WorkspaceLock, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceLock{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, lock := range q.workspaceLocks {
+		if lock.WorkspaceID == arg.WorkspaceID && !lock.UnlockedAt.Valid {
+			return database.WorkspaceLock{}, newUniqueConstraintError(database.UniqueWorkspaceLocksWorkspaceIDActiveIndex)
+		}
+	}
+	lock := database.WorkspaceLock{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		Reason:      arg.Reason,
+		LockedBy:    arg.LockedBy,
+		LockedAt:    arg.LockedAt,
+	}
+	q.workspaceLocks = append(q.workspaceLocks, lock)
+	return lock, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceMigration(_ context.Context, arg database.InsertWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, migration := range q.workspaceMigrations {
+		if migration.WorkspaceID == arg.WorkspaceID && !migration.CompletedAt.Valid {
+			return database.WorkspaceMigration{}, newUniqueConstraintError(database.UniqueWorkspaceMigrationsWorkspaceIDActiveIndex)
+		}
+	}
+	migration := database.WorkspaceMigration{
+		ID:                           arg.ID,
+		WorkspaceID:                  arg.WorkspaceID,
+		InitiatorID:                  arg.InitiatorID,
+		Status:                       database.WorkspaceMigrationStatusPending,
+		DestinationURL:               arg.DestinationURL,
+		DestinationOrganizationID:    arg.DestinationOrganizationID,
+		DestinationOwner:             arg.DestinationOwner,
+		DestinationTemplateID:        arg.DestinationTemplateID,
+		DestinationName:              arg.DestinationName,
+		DestinationSessionToken:      arg.DestinationSessionToken,
+		DestinationSessionTokenKeyID: arg.DestinationSessionTokenKeyID,
+		CreatedAt:                    arg.CreatedAt,
+		UpdatedAt:                    arg.UpdatedAt,
+	}
+	q.workspaceMigrations = append(q.workspaceMigrations, migration)
+	return migration, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceModule(_ context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -10069,16 +13187,20 @@ func (q *FakeQuerier) InsertWorkspaceResource(_ context.Context, arg database.In
 
 	//nolint:gosimple
 	resource := database.WorkspaceResource{
-		ID:         arg.ID,
-		CreatedAt:  arg.CreatedAt,
-		JobID:      arg.JobID,
-		Transition: arg.Transition,
-		Type:       arg.Type,
-		Name:       arg.Name,
-		Hide:       arg.Hide,
-		Icon:       arg.Icon,
-		DailyCost:  arg.DailyCost,
-		ModulePath: arg.ModulePath,
+		ID:           arg.ID,
+		CreatedAt:    arg.CreatedAt,
+		JobID:        arg.JobID,
+		Transition:   arg.Transition,
+		Type:         arg.Type,
+		Name:         arg.Name,
+		Hide:         arg.Hide,
+		Icon:         arg.Icon,
+		InstanceType: arg.InstanceType,
+		DailyCost:    arg.DailyCost,
+		ModulePath:   arg.ModulePath,
+		DisplayGroup: arg.DisplayGroup,
+		DisplayOrder: arg.DisplayOrder,
+		Collapsed:    arg.Collapsed,
 	}
 	q.workspaceResources = append(q.workspaceResources, resource)
 	return resource, nil
@@ -10115,6 +13237,85 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceSessionRecording(_ context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID == arg.ID {
+			return database.WorkspaceSessionRecording{}, newUniqueConstraintError(database.UniqueWorkspaceSessionRecordingsPkey)
+		}
+	}
+
+	recording := database.WorkspaceSessionRecording{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		AgentID:     arg.AgentID,
+		Type:        arg.Type,
+		Width:       arg.Width,
+		Height:      arg.Height,
+		StartedAt:   arg.StartedAt,
+	}
+	q.workspaceSessionRecordings = append(q.workspaceSessionRecordings, recording)
+	return recording, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceSessionRecordingChunk(_ context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (database.WorkspaceSessionRecordingChunk, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSessionRecordingChunk{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceSessionRecordingChunksLastInsertID++
+	chunk := database.WorkspaceSessionRecordingChunk{
+		ID:          q.workspaceSessionRecordingChunksLastInsertID,
+		RecordingID: arg.RecordingID,
+		CreatedAt:   arg.CreatedAt,
+		Data:        slices.Clone(arg.Data),
+		DataKeyID:   arg.DataKeyID,
+	}
+	q.workspaceSessionRecordingChunks = append(q.workspaceSessionRecordingChunks, chunk)
+	return chunk, nil
+}
+
+func (q *FakeQuerier) IsTemplateVersionRolloutCohortMember(ctx context.Context, arg database.IsTemplateVersionRolloutCohortMemberParams) (bool, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return false, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, rollout := range q.templateVersionRollouts {
+		if rollout.TemplateID != arg.TemplateID || !rollout.GroupID.Valid {
+			continue
+		}
+		if q.isEveryoneGroup(rollout.GroupID.UUID) {
+			for _, member := range q.getEveryoneGroupMembersNoLock(ctx, rollout.GroupID.UUID) {
+				if member.UserID == arg.UserID {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		for _, member := range q.groupMembers {
+			if member.GroupID == rollout.GroupID.UUID && member.UserID == arg.UserID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func (q *FakeQuerier) ListProvisionerKeysByOrganization(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -10177,6 +13378,29 @@ func (q *FakeQuerier) MarkAllInboxNotificationsAsRead(_ context.Context, arg dat
 	return nil
 }
 
+func (q *FakeQuerier) MarkNotificationMessagesDigested(_ context.Context, arg database.MarkNotificationMessagesDigestedParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var updated int64
+	for i, nm := range q.notificationMessages {
+		if nm.Status != database.NotificationMessageStatusBatched || !slices.Contains(arg.IDs, nm.ID) {
+			continue
+		}
+		nm.Status = database.NotificationMessageStatusSent
+		nm.StatusReason = sql.NullString{String: "Delivered in a notification digest", Valid: true}
+		nm.UpdatedAt = sql.NullTime{Time: arg.DigestedAt, Valid: true}
+		q.notificationMessages[i] = nm
+		updated++
+	}
+	return updated, nil
+}
+
 // nolint:forcetypeassert
 func (q *FakeQuerier) OIDCClaimFieldValues(_ context.Context, args database.OIDCClaimFieldValuesParams) ([]string, error) {
 	orgMembers := q.getOrganizationMemberNoLock(args.OrganizationID)
@@ -10417,6 +13641,25 @@ func (q *FakeQuerier) RemoveUserFromGroups(_ context.Context, arg database.Remov
 	return removed, nil
 }
 
+func (q *FakeQuerier) ResolveWorkspaceAgentHealthEvent(_ context.Context, arg database.ResolveWorkspaceAgentHealthEventParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var affected int64
+	for i, event := range q.workspaceAgentHealthEvents {
+		if event.AgentID == arg.AgentID && event.Kind == arg.Kind && event.Volume == arg.Volume && !event.ResolvedAt.Valid {
+			q.workspaceAgentHealthEvents[i].ResolvedAt = arg.ResolvedAt
+			affected++
+		}
+	}
+	return affected, nil
+}
+
 func (q *FakeQuerier) RevokeDBCryptKey(_ context.Context, activeKeyDigest string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -10457,6 +13700,48 @@ func (q *FakeQuerier) RevokeDBCryptKey(_ context.Context, activeKeyDigest string
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) RotateProvisionerKey(_ context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range q.provisionerKeys {
+		if key.ID != arg.ID {
+			continue
+		}
+		key.PreviousHashedSecret = key.HashedSecret
+		key.PreviousSecretExpiresAt = arg.PreviousSecretExpiresAt
+		key.HashedSecret = arg.HashedSecret
+		key.ExpiresAt = arg.ExpiresAt
+		q.provisionerKeys[i] = key
+		return key, nil
+	}
+
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) SearchProvisionerJobLogs(_ context.Context, arg database.SearchProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.ProvisionerJobLog, 0)
+	for _, jobLog := range q.provisionerJobLogs {
+		if arg.Matches(jobLog) {
+			logs = append(logs, jobLog)
+		}
+	}
+	return logs, nil
+}
+
 func (*FakeQuerier) TryAcquireLock(_ context.Context, _ int64) (bool, error) {
 	return false, xerrors.New("TryAcquireLock must only be called within a transaction")
 }
@@ -10501,6 +13786,27 @@ func (q *FakeQuerier) UnfavoriteWorkspace(_ context.Context, arg uuid.UUID) erro
 	return nil
 }
 
+func (q *FakeQuerier) UnlockWorkspace(_ context.Context, arg database.UnlockWorkspaceParams) (database.WorkspaceLock, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceLock{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, lock := range q.workspaceLocks {
+		if lock.WorkspaceID != arg.WorkspaceID || lock.UnlockedAt.Valid {
+			continue
+		}
+		lock.UnlockedBy = arg.UnlockedBy
+		lock.UnlockedAt = arg.UnlockedAt
+		q.workspaceLocks[i] = lock
+		return lock, nil
+	}
+	return database.WorkspaceLock{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPIKeyByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -10522,6 +13828,102 @@ func (q *FakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPI
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateAccessRequestExpiredByID(_ context.Context, id uuid.UUID) (database.AccessRequest, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, req := range q.accessRequests {
+		if req.ID != id || req.Status != database.AccessRequestStatusApproved {
+			continue
+		}
+		req.Status = database.AccessRequestStatusExpired
+		q.accessRequests[i] = req
+		return req, nil
+	}
+	return database.AccessRequest{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateAccessRequestReviewByID(_ context.Context, arg database.UpdateAccessRequestReviewByIDParams) (database.AccessRequest, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.AccessRequest{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, req := range q.accessRequests {
+		if req.ID != arg.ID || req.Status != database.AccessRequestStatusPending {
+			continue
+		}
+		req.Status = arg.Status
+		req.PreviousRole = arg.PreviousRole
+		req.ReviewedBy = arg.ReviewedBy
+		req.ReviewedAt = arg.ReviewedAt
+		req.ExpiresAt = arg.ExpiresAt
+		q.accessRequests[i] = req
+		return req, nil
+	}
+	return database.AccessRequest{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateBuildAlertRuleByID(_ context.Context, arg database.UpdateBuildAlertRuleByIDParams) (database.BuildAlertRule, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.BuildAlertRule{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, rule := range q.buildAlertRules {
+		if rule.ID != arg.ID {
+			continue
+		}
+		for _, other := range q.buildAlertRules {
+			if other.ID != rule.ID && other.OrganizationID == rule.OrganizationID && strings.EqualFold(other.Name, arg.Name) {
+				return database.BuildAlertRule{}, newUniqueConstraintError(database.UniqueBuildAlertRulesOrganizationIDNameIndex)
+			}
+		}
+		rule.TemplateID = arg.TemplateID
+		rule.Name = arg.Name
+		rule.Metric = arg.Metric
+		rule.Threshold = arg.Threshold
+		rule.WindowMs = arg.WindowMs
+		rule.UpdatedAt = arg.UpdatedAt
+		rule.LastEvaluatedAt = sql.NullTime{}
+		rule.LastValue = sql.NullFloat64{}
+		rule.BreachedAt = sql.NullTime{}
+		q.buildAlertRules[i] = rule
+		return rule, nil
+	}
+
+	return database.BuildAlertRule{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateBuildAlertRuleEvaluation(_ context.Context, arg database.UpdateBuildAlertRuleEvaluationParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, rule := range q.buildAlertRules {
+		if rule.ID != arg.ID {
+			continue
+		}
+		rule.LastEvaluatedAt = arg.LastEvaluatedAt
+		rule.LastValue = arg.LastValue
+		rule.BreachedAt = arg.BreachedAt
+		q.buildAlertRules[i] = rule
+		return nil
+	}
+
+	return nil
+}
+
 func (q *FakeQuerier) UpdateCryptoKeyDeletesAt(_ context.Context, arg database.UpdateCryptoKeyDeletesAtParams) (database.CryptoKey, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -10650,6 +14052,7 @@ func (q *FakeQuerier) UpdateGroupByID(_ context.Context, arg database.UpdateGrou
 			group.Name = arg.Name
 			group.AvatarURL = arg.AvatarURL
 			group.QuotaAllowance = arg.QuotaAllowance
+			group.MonthlyBudget = arg.MonthlyBudget
 			q.groups[i] = group
 			return group, nil
 		}
@@ -10847,6 +14250,7 @@ func (q *FakeQuerier) UpdateOrganization(_ context.Context, arg database.UpdateO
 			org.DisplayName = arg.DisplayName
 			org.Description = arg.Description
 			org.Icon = arg.Icon
+			org.CostCenter = arg.CostCenter
 			q.organizations[i] = org
 			return org, nil
 		}
@@ -10874,6 +14278,31 @@ func (q *FakeQuerier) UpdateOrganizationDeletedByID(_ context.Context, arg datab
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdatePendingProvisionerJobsUpdatedAt(_ context.Context, arg database.UpdatePendingProvisionerJobsUpdatedAtParams) ([]database.ProvisionerJob, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var jobs []database.ProvisionerJob
+	for index, job := range q.provisionerJobs {
+		if job.StartedAt.Valid || job.CompletedAt.Valid {
+			continue
+		}
+		if arg.OrganizationID.Valid && job.OrganizationID != arg.OrganizationID.UUID {
+			continue
+		}
+		job.UpdatedAt = arg.UpdatedAt
+		job.JobStatus = provisionerJobStatus(job)
+		q.provisionerJobs[index] = job
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
 func (q *FakeQuerier) UpdatePresetPrebuildStatus(ctx context.Context, arg database.UpdatePresetPrebuildStatusParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -11003,6 +14432,27 @@ func (q *FakeQuerier) UpdateProvisionerJobWithCompleteWithStartedAtByID(_ contex
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateProvisionerKeyLastUsedAt(_ context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range q.provisionerKeys {
+		if key.ID != arg.ID {
+			continue
+		}
+		key.LastUsedAt = arg.LastUsedAt
+		q.provisionerKeys[i] = key
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Replica{}, err
@@ -11137,6 +14587,9 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.AllowUserCancelWorkspaceJobs = arg.AllowUserCancelWorkspaceJobs
 		tpl.MaxPortSharingLevel = arg.MaxPortSharingLevel
 		tpl.UseClassicParameterFlow = arg.UseClassicParameterFlow
+		tpl.MaxConcurrentJobsPerUser = arg.MaxConcurrentJobsPerUser
+		tpl.ResourceCeilings = arg.ResourceCeilings
+		tpl.RequiredProvisionerTags = arg.RequiredProvisionerTags
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -11144,6 +14597,41 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplatePresetByID(_ context.Context, arg database.UpdateTemplatePresetByIDParams) (database.TemplatePreset, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplatePreset{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, preset := range q.templatePresets {
+		if preset.ID != arg.ID {
+			continue
+		}
+		for _, other := range q.templatePresets {
+			if other.ID != preset.ID && other.TemplateID == preset.TemplateID && other.Name == arg.Name {
+				return database.TemplatePreset{}, &pq.Error{
+					Code:       "23505",
+					Message:    "duplicate key value violates unique constraint \"template_presets_template_id_name_key\"",
+					Constraint: string(database.UniqueTemplatePresetsTemplateIDNameKey),
+				}
+			}
+		}
+		preset.Name = arg.Name
+		preset.Description = arg.Description
+		preset.Parameters = maps.Clone(arg.Parameters)
+		if preset.Parameters == nil {
+			preset.Parameters = database.StringMap{}
+		}
+		preset.UpdatedAt = arg.UpdatedAt
+		q.templatePresets[i] = preset
+		return preset, nil
+	}
+	return database.TemplatePreset{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateScheduleByID(_ context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -11256,6 +14744,27 @@ func (q *FakeQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(_ contex
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateVersionVariableValue(_ context.Context, arg database.UpdateTemplateVersionVariableValueParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, variable := range q.templateVersionVariables {
+		if variable.TemplateVersionID != arg.TemplateVersionID || variable.Name != arg.Name {
+			continue
+		}
+		variable.Value = arg.Value
+		variable.ValueKeyID = arg.ValueKeyID
+		q.templateVersionVariables[i] = variable
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateTemplateWorkspacesLastUsedAt(_ context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -11276,6 +14785,30 @@ func (q *FakeQuerier) UpdateTemplateWorkspacesLastUsedAt(_ context.Context, arg
 	return nil
 }
 
+func (q *FakeQuerier) UpdateUserActivityExportStatusByID(_ context.Context, arg database.UpdateUserActivityExportStatusByIDParams) (database.UserActivityExport, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserActivityExport{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, export := range q.userActivityExports {
+		if export.ID != arg.ID {
+			continue
+		}
+		export.Status = arg.Status
+		export.FileID = arg.FileID
+		export.Error = arg.Error
+		export.UpdatedAt = arg.UpdatedAt
+		export.CompletedAt = arg.CompletedAt
+		q.userActivityExports[i] = export
+		return export, nil
+	}
+	return database.UserActivityExport{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateUserDeletedByID(_ context.Context, id uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -11442,11 +14975,113 @@ func (q *FakeQuerier) UpdateUserLoginType(_ context.Context, arg database.Update
 			if arg.NewLoginType != database.LoginTypePassword {
 				u.HashedPassword = []byte{}
 			}
-			q.users[i] = u
-			return u, nil
+			q.users[i] = u
+			return u, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateUserNotificationBuildDurationThreshold(_ context.Context, arg database.UpdateUserNotificationBuildDurationThresholdParams) (database.UserConfig, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserConfig{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, uc := range q.userConfigs {
+		if uc.UserID != arg.UserID || uc.Key != "notification_build_duration_threshold_ms" {
+			continue
+		}
+		uc.Value = arg.NotificationBuildDurationThresholdMs
+		q.userConfigs[i] = uc
+		return uc, nil
+	}
+
+	uc := database.UserConfig{
+		UserID: arg.UserID,
+		Key:    "notification_build_duration_threshold_ms",
+		Value:  arg.NotificationBuildDurationThresholdMs,
+	}
+	q.userConfigs = append(q.userConfigs, uc)
+	return uc, nil
+}
+
+func (q *FakeQuerier) UpdateUserNotificationDigestFrequency(_ context.Context, arg database.UpdateUserNotificationDigestFrequencyParams) (database.UserConfig, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserConfig{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, uc := range q.userConfigs {
+		if uc.UserID != arg.UserID || uc.Key != "notification_digest_frequency" {
+			continue
+		}
+		uc.Value = arg.NotificationDigestFrequency
+		q.userConfigs[i] = uc
+		return uc, nil
+	}
+
+	uc := database.UserConfig{
+		UserID: arg.UserID,
+		Key:    "notification_digest_frequency",
+		Value:  arg.NotificationDigestFrequency,
+	}
+	q.userConfigs = append(q.userConfigs, uc)
+	return uc, nil
+}
+
+func (q *FakeQuerier) UpdateUserNotificationDigestPreferences(_ context.Context, arg database.UpdateUserNotificationDigestPreferencesParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var upserted int64
+	for i := range arg.NotificationTemplateIds {
+		var (
+			found      bool
+			templateID = arg.NotificationTemplateIds[i]
+			digest     = arg.Digests[i]
+		)
+
+		for j, np := range q.notificationPreferences {
+			if np.UserID != arg.UserID || np.NotificationTemplateID != templateID {
+				continue
+			}
+
+			np.Digest = digest
+			np.UpdatedAt = dbtime.Now()
+			q.notificationPreferences[j] = np
+
+			upserted++
+			found = true
+			break
+		}
+
+		if !found {
+			// The default enablement of templates isn't known here, since
+			// templates are created with migrations.
+			q.notificationPreferences = append(q.notificationPreferences, database.NotificationPreference{
+				Digest:                 digest,
+				UserID:                 arg.UserID,
+				NotificationTemplateID: templateID,
+				CreatedAt:              dbtime.Now(),
+				UpdatedAt:              dbtime.Now(),
+			})
+			upserted++
 		}
 	}
-	return database.User{}, sql.ErrNoRows
+
+	return upserted, nil
 }
 
 func (q *FakeQuerier) UpdateUserNotificationPreferences(_ context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
@@ -11709,6 +15344,27 @@ func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.WorkspaceTable{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceACLByID(_ context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.GroupACL = arg.GroupACL
+		workspace.UserACL = arg.UserACL
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -11960,6 +15616,26 @@ func (q *FakeQuerier) UpdateWorkspaceBuildDeadlineByID(_ context.Context, arg da
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceBuildIdempotencyKeyBuildID(_ context.Context, arg database.UpdateWorkspaceBuildIdempotencyKeyBuildIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range q.workspaceBuildIdempotencyKeys {
+		if key.WorkspaceBuildID.Valid && key.WorkspaceBuildID.UUID == arg.WorkspaceBuildID {
+			return newUniqueConstraintError(database.UniqueWorkspaceBuildIdempotencyKeysWorkspaceBuildIDKey)
+		}
+		if key.UserID == arg.UserID && key.IdempotencyKey == arg.IdempotencyKey {
+			q.workspaceBuildIdempotencyKeys[i].WorkspaceBuildID = uuid.NullUUID{UUID: arg.WorkspaceBuildID, Valid: true}
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateWorkspaceBuildProvisionerStateByID(_ context.Context, arg database.UpdateWorkspaceBuildProvisionerStateByIDParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -11982,6 +15658,26 @@ func (q *FakeQuerier) UpdateWorkspaceBuildProvisionerStateByID(_ context.Context
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceBuildQueueEntryFailed(_ context.Context, arg database.UpdateWorkspaceBuildQueueEntryFailedParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, entry := range q.workspaceBuildQueue {
+		if entry.ID == arg.ID {
+			entry.FailedAt = arg.FailedAt
+			entry.Error = arg.Error
+			q.workspaceBuildQueue[i] = entry
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -12040,6 +15736,28 @@ func (q *FakeQuerier) UpdateWorkspaceDormantDeletingAt(_ context.Context, arg da
 	return database.WorkspaceTable{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceDriftCheckByJobID(_ context.Context, arg database.UpdateWorkspaceDriftCheckByJobIDParams) (database.WorkspaceDriftCheck, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceDriftCheck{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, check := range q.workspaceDriftChecks {
+		if check.JobID != arg.JobID {
+			continue
+		}
+		check.CompletedAt = arg.CompletedAt
+		check.DriftedResources = arg.DriftedResources
+		check.OrphanedResources = arg.OrphanedResources
+		q.workspaceDriftChecks[i] = check
+		return check, nil
+	}
+	return database.WorkspaceDriftCheck{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceLastUsedAt(_ context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -12060,6 +15778,50 @@ func (q *FakeQuerier) UpdateWorkspaceLastUsedAt(_ context.Context, arg database.
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceMigrationSessionToken(_ context.Context, arg database.UpdateWorkspaceMigrationSessionTokenParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, migration := range q.workspaceMigrations {
+		if migration.ID == arg.ID {
+			migration.DestinationSessionToken = arg.DestinationSessionToken
+			migration.DestinationSessionTokenKeyID = arg.DestinationSessionTokenKeyID
+			q.workspaceMigrations[i] = migration
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateWorkspaceMigrationStatus(_ context.Context, arg database.UpdateWorkspaceMigrationStatusParams) (database.WorkspaceMigration, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, migration := range q.workspaceMigrations {
+		if migration.ID != arg.ID {
+			continue
+		}
+		migration.Status = arg.Status
+		migration.SourceBuildID = arg.SourceBuildID
+		migration.DestinationWorkspaceID = arg.DestinationWorkspaceID
+		migration.DestinationBuildID = arg.DestinationBuildID
+		migration.UpdatedAt = arg.UpdatedAt
+		q.workspaceMigrations[i] = migration
+		return migration, nil
+	}
+	return database.WorkspaceMigration{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceNextStartAt(_ context.Context, arg database.UpdateWorkspaceNextStartAtParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -12123,6 +15885,49 @@ func (q *FakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg databas
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceSessionRecording(_ context.Context, arg database.UpdateWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, recording := range q.workspaceSessionRecordings {
+		if recording.ID != arg.ID {
+			continue
+		}
+		recording.SizeBytes += arg.AddedBytes
+		if !recording.EndedAt.Valid {
+			recording.EndedAt = arg.EndedAt
+		}
+		q.workspaceSessionRecordings[i] = recording
+		return recording, nil
+	}
+	return database.WorkspaceSessionRecording{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWorkspaceSessionRecordingChunkData(_ context.Context, arg database.UpdateWorkspaceSessionRecordingChunkDataParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, chunk := range q.workspaceSessionRecordingChunks {
+		if chunk.ID == arg.ID {
+			chunk.Data = slices.Clone(arg.Data)
+			chunk.DataKeyID = arg.DataKeyID
+			q.workspaceSessionRecordingChunks[i] = chunk
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -12235,6 +16040,14 @@ func (q *FakeQuerier) UpsertCoordinatorResumeTokenSigningKey(_ context.Context,
 	return nil
 }
 
+func (q *FakeQuerier) UpsertDBPurgeStats(_ context.Context, value string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.dbPurgeStats = []byte(value)
+	return nil
+}
+
 func (q *FakeQuerier) UpsertDefaultProxy(_ context.Context, arg database.UpsertDefaultProxyParams) error {
 	q.defaultProxyDisplayName = arg.DisplayName
 	q.defaultProxyIconURL = arg.IconUrl
@@ -12249,6 +16062,14 @@ func (q *FakeQuerier) UpsertHealthSettings(_ context.Context, data string) error
 	return nil
 }
 
+func (q *FakeQuerier) UpsertJobReaperLastRunAt(_ context.Context, data string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.jobReaperLastRunAt = []byte(data)
+	return nil
+}
+
 func (q *FakeQuerier) UpsertLastUpdateCheck(_ context.Context, data string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -12309,6 +16130,42 @@ func (q *FakeQuerier) UpsertOAuthSigningKey(_ context.Context, value string) err
 	return nil
 }
 
+func (q *FakeQuerier) UpsertOrganizationWorkspaceNamingPolicy(_ context.Context, arg database.UpsertOrganizationWorkspaceNamingPolicyParams) (database.WorkspaceNamingPolicy, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, p := range q.workspaceNamingPolicies {
+		if p.OrganizationID != arg.OrganizationID || p.TemplateID.Valid {
+			continue
+		}
+		p.Regex = arg.Regex
+		p.Prefix = arg.Prefix
+		p.Suffix = arg.Suffix
+		p.UniquenessScope = arg.UniquenessScope
+		p.UpdatedAt = arg.UpdatedAt
+		q.workspaceNamingPolicies[i] = p
+		return p, nil
+	}
+
+	p := database.WorkspaceNamingPolicy{
+		ID:              arg.ID,
+		OrganizationID:  arg.OrganizationID,
+		Regex:           arg.Regex,
+		Prefix:          arg.Prefix,
+		Suffix:          arg.Suffix,
+		UniquenessScope: arg.UniquenessScope,
+		CreatedAt:       arg.UpdatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+	}
+	q.workspaceNamingPolicies = append(q.workspaceNamingPolicies, p)
+	return p, nil
+}
+
 func (q *FakeQuerier) UpsertProvisionerDaemon(_ context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerDaemon{}, err
@@ -12349,6 +16206,14 @@ func (q *FakeQuerier) UpsertProvisionerDaemon(_ context.Context, arg database.Up
 	return d, nil
 }
 
+func (q *FakeQuerier) UpsertReadOnlySettings(_ context.Context, data string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.readOnlySettings = []byte(data)
+	return nil
+}
+
 func (q *FakeQuerier) UpsertRuntimeConfig(_ context.Context, arg database.UpsertRuntimeConfigParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -12413,14 +16278,167 @@ func (q *FakeQuerier) UpsertTelemetryItem(_ context.Context, arg database.Upsert
 		}
 	}
 
-	q.telemetryItems = append(q.telemetryItems, database.TelemetryItem{
-		Key:       arg.Key,
-		Value:     arg.Value,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	})
+	q.telemetryItems = append(q.telemetryItems, database.TelemetryItem{
+		Key:       arg.Key,
+		Value:     arg.Value,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+func (q *FakeQuerier) UpsertTemplateBuildDurationStats(ctx context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	truncateDay := func(t time.Time) time.Time {
+		return t.UTC().Truncate(24 * time.Hour)
+	}
+
+	var latestStart time.Time
+	for _, stat := range q.templateBuildDurationStats {
+		if start := stat.StartTime.Add(-24 * time.Hour); start.After(latestStart) {
+			latestStart = start
+		}
+	}
+	if latestStart.IsZero() {
+		for _, job := range q.provisionerJobs {
+			if job.Type != database.ProvisionerJobTypeWorkspaceBuild || !job.CompletedAt.Valid {
+				continue
+			}
+			if latestStart.IsZero() || job.CompletedAt.Time.Before(latestStart) {
+				latestStart = job.CompletedAt.Time
+			}
+		}
+		if latestStart.IsZero() {
+			return nil
+		}
+		latestStart = truncateDay(latestStart)
+	}
+
+	type bucketKey struct {
+		start      time.Time
+		templateID uuid.UUID
+		transition database.WorkspaceTransition
+	}
+	durations := make(map[bucketKey][]float64)
+	for _, build := range q.workspaceBuilds {
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return err
+		}
+		if !job.StartedAt.Valid || !job.CompletedAt.Valid || job.CompletedAt.Time.Before(latestStart) {
+			continue
+		}
+		if job.CanceledAt.Valid || job.Error.String != "" {
+			continue
+		}
+		version, err := q.getTemplateVersionByIDNoLock(ctx, build.TemplateVersionID)
+		if err != nil {
+			return err
+		}
+		if !version.TemplateID.Valid {
+			continue
+		}
+		key := bucketKey{
+			start:      truncateDay(job.CompletedAt.Time),
+			templateID: version.TemplateID.UUID,
+			transition: build.Transition,
+		}
+		durations[key] = append(durations[key], float64(job.CompletedAt.Time.Sub(job.StartedAt.Time).Milliseconds()))
+	}
+
+	for key, ds := range durations {
+		stat := database.TemplateBuildDurationStat{
+			StartTime:  key.start,
+			EndTime:    key.start.Add(24 * time.Hour),
+			TemplateID: key.templateID,
+			Transition: key.transition,
+			// #nosec G115 - Safe conversion as build counts per day are expected to be within int32 range
+			BuildCount:    int32(len(ds)),
+			P50DurationMs: int64(tryPercentileDisc(ds, 50)),
+			P95DurationMs: int64(tryPercentileDisc(ds, 95)),
+			MaxDurationMs: int64(slices.Max(ds)),
+		}
+		i := slices.IndexFunc(q.templateBuildDurationStats, func(s database.TemplateBuildDurationStat) bool {
+			return s.StartTime.Equal(stat.StartTime) && s.TemplateID == stat.TemplateID && s.Transition == stat.Transition
+		})
+		if i >= 0 {
+			q.templateBuildDurationStats[i] = stat
+			continue
+		}
+		q.templateBuildDurationStats = append(q.templateBuildDurationStats, stat)
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpsertTemplatePresetGroupDefault(_ context.Context, arg database.UpsertTemplatePresetGroupDefaultParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, def := range q.templatePresetGroupDefaults {
+		if def.TemplateID == arg.TemplateID && def.GroupID == arg.GroupID {
+			q.templatePresetGroupDefaults[i].TemplatePresetID = arg.TemplatePresetID
+			return nil
+		}
+	}
+	q.templatePresetGroupDefaults = append(q.templatePresetGroupDefaults, database.TemplatePresetGroupDefault{
+		TemplateID:       arg.TemplateID,
+		GroupID:          arg.GroupID,
+		TemplatePresetID: arg.TemplatePresetID,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) UpsertTemplateReleaseChannel(_ context.Context, arg database.UpsertTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	channel := database.TemplateReleaseChannel{
+		TemplateID:        arg.TemplateID,
+		Channel:           arg.Channel,
+		TemplateVersionID: arg.TemplateVersionID,
+		UpdatedBy:         arg.UpdatedBy,
+		UpdatedAt:         arg.UpdatedAt,
+	}
+	for i, existing := range q.templateReleaseChannels {
+		if existing.TemplateID == arg.TemplateID && existing.Channel == arg.Channel {
+			q.templateReleaseChannels[i] = channel
+			return channel, nil
+		}
+	}
+	q.templateReleaseChannels = append(q.templateReleaseChannels, channel)
+	return channel, nil
+}
+
+func (q *FakeQuerier) UpsertTemplateShare(_ context.Context, arg database.UpsertTemplateShareParams) (database.TemplateShare, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateShare{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
 
-	return nil
+	for _, share := range q.templateShares {
+		if share.TemplateID == arg.TemplateID && share.OrganizationID == arg.OrganizationID {
+			return share, nil
+		}
+	}
+	share := database.TemplateShare(arg)
+	q.templateShares = append(q.templateShares, share)
+	return share, nil
 }
 
 func (q *FakeQuerier) UpsertTemplateUsageStats(ctx context.Context) error {
@@ -13021,6 +17039,76 @@ TemplateUsageStatsInsertLoop:
 	return nil
 }
 
+func (q *FakeQuerier) UpsertTemplateVersionRollout(_ context.Context, arg database.UpsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	rollout := database.TemplateVersionRollout{
+		TemplateID:        arg.TemplateID,
+		TemplateVersionID: arg.TemplateVersionID,
+		Percent:           arg.Percent,
+		GroupID:           arg.GroupID,
+		CreatedBy:         arg.CreatedBy,
+		CreatedAt:         arg.CreatedAt,
+		UpdatedAt:         arg.UpdatedAt,
+	}
+	for i, existing := range q.templateVersionRollouts {
+		if existing.TemplateID != arg.TemplateID {
+			continue
+		}
+		if existing.TemplateVersionID == arg.TemplateVersionID {
+			rollout.CreatedBy = existing.CreatedBy
+			rollout.CreatedAt = existing.CreatedAt
+		}
+		q.templateVersionRollouts[i] = rollout
+		return rollout, nil
+	}
+	q.templateVersionRollouts = append(q.templateVersionRollouts, rollout)
+	return rollout, nil
+}
+
+func (q *FakeQuerier) UpsertTemplateWorkspaceNamingPolicy(_ context.Context, arg database.UpsertTemplateWorkspaceNamingPolicyParams) (database.WorkspaceNamingPolicy, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceNamingPolicy{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, p := range q.workspaceNamingPolicies {
+		if !p.TemplateID.Valid || p.TemplateID.UUID != arg.TemplateID {
+			continue
+		}
+		p.Regex = arg.Regex
+		p.Prefix = arg.Prefix
+		p.Suffix = arg.Suffix
+		p.UniquenessScope = arg.UniquenessScope
+		p.UpdatedAt = arg.UpdatedAt
+		q.workspaceNamingPolicies[i] = p
+		return p, nil
+	}
+
+	p := database.WorkspaceNamingPolicy{
+		ID:              arg.ID,
+		OrganizationID:  arg.OrganizationID,
+		TemplateID:      uuid.NullUUID{UUID: arg.TemplateID, Valid: true},
+		Regex:           arg.Regex,
+		Prefix:          arg.Prefix,
+		Suffix:          arg.Suffix,
+		UniquenessScope: arg.UniquenessScope,
+		CreatedAt:       arg.UpdatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+	}
+	q.workspaceNamingPolicies = append(q.workspaceNamingPolicies, p)
+	return p, nil
+}
+
 func (q *FakeQuerier) UpsertWebpushVAPIDKeys(_ context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -13035,6 +17123,45 @@ func (q *FakeQuerier) UpsertWebpushVAPIDKeys(_ context.Context, arg database.Ups
 	return nil
 }
 
+func (q *FakeQuerier) UpsertWorkspaceAgentConnectionQuality(_ context.Context, arg database.UpsertWorkspaceAgentConnectionQualityParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, quality := range q.workspaceAgentConnectionQuality {
+		if quality.AgentID != arg.AgentID || quality.Ip != arg.Ip {
+			continue
+		}
+		quality.P2P = arg.P2P
+		quality.DerpRegionCode = arg.DerpRegionCode
+		quality.LatencyMS = arg.LatencyMS
+		quality.PingsSent = arg.PingsSent
+		quality.PingsLost = arg.PingsLost
+		quality.LastHandshakeAt = arg.LastHandshakeAt
+		quality.UpdatedAt = arg.UpdatedAt
+		q.workspaceAgentConnectionQuality[i] = quality
+		return nil
+	}
+
+	q.workspaceAgentConnectionQuality = append(q.workspaceAgentConnectionQuality, database.WorkspaceAgentConnectionQuality{
+		AgentID:         arg.AgentID,
+		Ip:              arg.Ip,
+		P2P:             arg.P2P,
+		DerpRegionCode:  arg.DerpRegionCode,
+		LatencyMS:       arg.LatencyMS,
+		PingsSent:       arg.PingsSent,
+		PingsLost:       arg.PingsLost,
+		LastHandshakeAt: arg.LastHandshakeAt,
+		CreatedAt:       arg.UpdatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -13048,6 +17175,9 @@ func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg datab
 		if share.WorkspaceID == arg.WorkspaceID && share.Port == arg.Port && share.AgentName == arg.AgentName {
 			share.ShareLevel = arg.ShareLevel
 			share.Protocol = arg.Protocol
+			share.SharedWithUserIDs = arg.SharedWithUserIDs
+			share.SharedWithGroupIDs = arg.SharedWithGroupIDs
+			share.ExpiresAt = arg.ExpiresAt
 			q.workspaceAgentPortShares[i] = share
 			return share, nil
 		}
@@ -13055,17 +17185,63 @@ func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg datab
 
 	//nolint:gosimple // casts are not a simplification
 	psl := database.WorkspaceAgentPortShare{
-		WorkspaceID: arg.WorkspaceID,
-		AgentName:   arg.AgentName,
-		Port:        arg.Port,
-		ShareLevel:  arg.ShareLevel,
-		Protocol:    arg.Protocol,
+		WorkspaceID:        arg.WorkspaceID,
+		AgentName:          arg.AgentName,
+		Port:               arg.Port,
+		ShareLevel:         arg.ShareLevel,
+		Protocol:           arg.Protocol,
+		SharedWithUserIDs:  arg.SharedWithUserIDs,
+		SharedWithGroupIDs: arg.SharedWithGroupIDs,
+		ExpiresAt:          arg.ExpiresAt,
 	}
 	q.workspaceAgentPortShares = append(q.workspaceAgentPortShares, psl)
 
 	return psl, nil
 }
 
+func (q *FakeQuerier) UpsertWorkspaceAgentResourceUsage(_ context.Context, arg database.UpsertWorkspaceAgentResourceUsageParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, usage := range q.workspaceAgentResourceUsage {
+		if usage.AgentID != arg.AgentID || !usage.BucketStart.Equal(arg.BucketStart) {
+			continue
+		}
+		samples := int64(usage.Samples)
+		usage.CPUUsedAvg = (usage.CPUUsedAvg*float64(samples) + arg.CPUUsed) / float64(samples+1)
+		usage.CPUUsedMax = max(usage.CPUUsedMax, arg.CPUUsed)
+		usage.CPUTotal = arg.CPUTotal
+		usage.MemoryUsedAvg = (usage.MemoryUsedAvg*samples + arg.MemoryUsed) / (samples + 1)
+		usage.MemoryUsedMax = max(usage.MemoryUsedMax, arg.MemoryUsed)
+		usage.MemoryTotal = arg.MemoryTotal
+		usage.DiskUsed = arg.DiskUsed
+		usage.DiskTotal = arg.DiskTotal
+		usage.Samples++
+		q.workspaceAgentResourceUsage[i] = usage
+		return nil
+	}
+
+	q.workspaceAgentResourceUsage = append(q.workspaceAgentResourceUsage, database.WorkspaceAgentResourceUsage{
+		AgentID:       arg.AgentID,
+		BucketStart:   arg.BucketStart,
+		Samples:       1,
+		CPUUsedAvg:    arg.CPUUsed,
+		CPUUsedMax:    arg.CPUUsed,
+		CPUTotal:      arg.CPUTotal,
+		MemoryUsedAvg: arg.MemoryUsed,
+		MemoryUsedMax: arg.MemoryUsed,
+		MemoryTotal:   arg.MemoryTotal,
+		DiskUsed:      arg.DiskUsed,
+		DiskTotal:     arg.DiskTotal,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) UpsertWorkspaceApp(ctx context.Context, arg database.UpsertWorkspaceAppParams) (database.WorkspaceApp, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -13176,6 +17352,243 @@ func (q *FakeQuerier) UpsertWorkspaceAppAuditSession(_ context.Context, arg data
 	return true, nil
 }
 
+func (q *FakeQuerier) UpsertWorkspaceBuildInterimState(_ context.Context, arg database.UpsertWorkspaceBuildInterimStateParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	state := database.WorkspaceBuildInterimState{
+		WorkspaceBuildID: arg.WorkspaceBuildID,
+		State:            arg.State,
+		UpdatedAt:        arg.UpdatedAt,
+	}
+	for i, existing := range q.workspaceBuildInterimStates {
+		if existing.WorkspaceBuildID == arg.WorkspaceBuildID {
+			q.workspaceBuildInterimStates[i] = state
+			return nil
+		}
+	}
+	q.workspaceBuildInterimStates = append(q.workspaceBuildInterimStates, state)
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceDailyCosts(ctx context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	const day = 24 * time.Hour
+	now := dbtime.Now()
+
+	var latestStart time.Time
+	for _, cost := range q.workspaceDailyCosts {
+		if start := cost.StartTime.Add(-day); start.After(latestStart) {
+			latestStart = start
+		}
+	}
+	if latestStart.IsZero() {
+		for _, job := range q.provisionerJobs {
+			if job.Type != database.ProvisionerJobTypeWorkspaceBuild || !job.CompletedAt.Valid {
+				continue
+			}
+			if latestStart.IsZero() || job.CompletedAt.Time.Before(latestStart) {
+				latestStart = job.CompletedAt.Time
+			}
+		}
+		if latestStart.IsZero() {
+			return nil
+		}
+		latestStart = latestStart.UTC().Truncate(day)
+	}
+
+	// The successful builds of each workspace, in order.
+	type buildCost struct {
+		dailyCost    int32
+		currentFrom  time.Time
+		currentUntil time.Time
+	}
+	buildCosts := make(map[uuid.UUID][]buildCost)
+	builds := slices.Clone(q.workspaceBuilds)
+	slices.SortFunc(builds, func(a, b database.WorkspaceBuild) int {
+		return int(a.BuildNumber - b.BuildNumber)
+	})
+	for _, build := range builds {
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return err
+		}
+		if !job.CompletedAt.Valid || job.CanceledAt.Valid || job.Error.String != "" {
+			continue
+		}
+		var dailyCost int32
+		if build.Transition != database.WorkspaceTransitionDelete {
+			resources, err := q.getWorkspaceResourcesByJobIDNoLock(ctx, build.JobID)
+			if err != nil {
+				return err
+			}
+			for _, resource := range resources {
+				dailyCost += resource.DailyCost
+			}
+		}
+		costs := buildCosts[build.WorkspaceID]
+		if len(costs) > 0 {
+			costs[len(costs)-1].currentUntil = job.CompletedAt.Time
+		}
+		buildCosts[build.WorkspaceID] = append(costs, buildCost{
+			dailyCost:    dailyCost,
+			currentFrom:  job.CompletedAt.Time,
+			currentUntil: now,
+		})
+	}
+
+	for workspaceID, costs := range buildCosts {
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, workspaceID)
+		if err != nil {
+			return err
+		}
+		for start := latestStart; !start.After(now); start = start.Add(day) {
+			end := start.Add(day)
+			var cost float64
+			var found bool
+			for _, bc := range costs {
+				if bc.dailyCost <= 0 || !bc.currentFrom.Before(end) || !bc.currentUntil.After(start) {
+					continue
+				}
+				overlap := minTime(bc.currentUntil, end).Sub(maxTime(bc.currentFrom, start))
+				cost += float64(bc.dailyCost) * overlap.Seconds() / day.Seconds()
+				found = true
+			}
+			if !found {
+				continue
+			}
+			row := database.WorkspaceDailyCost{
+				StartTime:      start,
+				EndTime:        end,
+				WorkspaceID:    workspaceID,
+				OrganizationID: workspace.OrganizationID,
+				OwnerID:        workspace.OwnerID,
+				TemplateID:     workspace.TemplateID,
+				Cost:           cost,
+			}
+			i := slices.IndexFunc(q.workspaceDailyCosts, func(c database.WorkspaceDailyCost) bool {
+				return c.StartTime.Equal(start) && c.WorkspaceID == workspaceID
+			})
+			if i >= 0 {
+				q.workspaceDailyCosts[i] = row
+				continue
+			}
+			q.workspaceDailyCosts = append(q.workspaceDailyCosts, row)
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceDriftCheck(_ context.Context, arg database.UpsertWorkspaceDriftCheckParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	check := database.WorkspaceDriftCheck{
+		WorkspaceID:       arg.WorkspaceID,
+		WorkspaceBuildID:  arg.WorkspaceBuildID,
+		JobID:             arg.JobID,
+		CreatedAt:         arg.CreatedAt,
+		DriftedResources:  []string{},
+		OrphanedResources: []string{},
+	}
+	for i, existing := range q.workspaceDriftChecks {
+		if existing.WorkspaceID == arg.WorkspaceID {
+			q.workspaceDriftChecks[i] = check
+			return nil
+		}
+	}
+	q.workspaceDriftChecks = append(q.workspaceDriftChecks, check)
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspacePrebuildReservation(_ context.Context, arg database.UpsertWorkspacePrebuildReservationParams) (database.WorkspacePrebuildReservation, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspacePrebuildReservation{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	reservation := database.WorkspacePrebuildReservation{
+		WorkspaceID: arg.WorkspaceID,
+		UserID:      arg.UserID,
+		GroupID:     arg.GroupID,
+		ReservedBy:  arg.ReservedBy,
+		CreatedAt:   arg.CreatedAt,
+		ExpiresAt:   arg.ExpiresAt,
+	}
+	for i, existing := range q.workspacePrebuildReservations {
+		if existing.WorkspaceID == arg.WorkspaceID {
+			q.workspacePrebuildReservations[i] = reservation
+			return reservation, nil
+		}
+	}
+	q.workspacePrebuildReservations = append(q.workspacePrebuildReservations, reservation)
+	return reservation, nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceProvisionerAffinity(_ context.Context, arg database.UpsertWorkspaceProvisionerAffinityParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	affinity := database.WorkspaceProvisionerAffinity{
+		WorkspaceID:         arg.WorkspaceID,
+		ProvisionerDaemonID: arg.ProvisionerDaemonID,
+		TemplateVersionID:   arg.TemplateVersionID,
+		UpdatedAt:           arg.UpdatedAt,
+	}
+	for i, existing := range q.workspaceProvisionerAffinities {
+		if existing.WorkspaceID == arg.WorkspaceID {
+			q.workspaceProvisionerAffinities[i] = affinity
+			return nil
+		}
+	}
+	q.workspaceProvisionerAffinities = append(q.workspaceProvisionerAffinities, affinity)
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceReleaseChannel(_ context.Context, arg database.UpsertWorkspaceReleaseChannelParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	channel := database.WorkspaceReleaseChannel{
+		WorkspaceID: arg.WorkspaceID,
+		Channel:     arg.Channel,
+		UpdatedAt:   arg.UpdatedAt,
+	}
+	for i, existing := range q.workspaceReleaseChannels {
+		if existing.WorkspaceID == arg.WorkspaceID {
+			q.workspaceReleaseChannels[i] = channel
+			return nil
+		}
+	}
+	q.workspaceReleaseChannels = append(q.workspaceReleaseChannels, channel)
+	return nil
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -13618,6 +18031,22 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 			}
 		}
 
+		if len(arg.LabelKeys) > 0 {
+			matches := true
+			for i, key := range arg.LabelKeys {
+				if !slices.ContainsFunc(q.workspaceLabels, func(label database.WorkspaceLabel) bool {
+					return label.WorkspaceID == workspace.ID && label.Key == key &&
+						(arg.LabelValues[i] == "" || strings.EqualFold(label.Value, arg.LabelValues[i]))
+				}) {
+					matches = false
+					break
+				}
+			}
+			if !matches {
+				continue
+			}
+		}
+
 		// If the filter exists, ensure the object is authorized.
 		if prepared != nil && prepared.Authorize(ctx, workspace.RBACObject()) != nil {
 			continue
@@ -13657,41 +18086,55 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 		}
 	}
 
-	sort.Slice(workspaces, func(i, j int) bool {
-		w1 := workspaces[i]
-		w2 := workspaces[j]
-
+	slices.SortFunc(workspaces, func(w1, w2 database.WorkspaceTable) int {
 		// Order by: favorite first
-		if arg.RequesterID == w1.OwnerID && w1.Favorite {
-			return true
-		}
-		if arg.RequesterID == w2.OwnerID && w2.Favorite {
-			return false
+		w1IsFavorite := arg.RequesterID == w1.OwnerID && w1.Favorite
+		w2IsFavorite := arg.RequesterID == w2.OwnerID && w2.Favorite
+		if w1IsFavorite != w2IsFavorite {
+			if w1IsFavorite {
+				return -1
+			}
+			return 1
 		}
 
 		// Order by: running
 		w1IsRunning := isRunning(preloadedWorkspaceBuilds[w1.ID], preloadedProvisionerJobs[w1.ID])
 		w2IsRunning := isRunning(preloadedWorkspaceBuilds[w2.ID], preloadedProvisionerJobs[w2.ID])
-
-		if w1IsRunning && !w2IsRunning {
-			return true
-		}
-
-		if !w1IsRunning && w2IsRunning {
-			return false
+		if w1IsRunning != w2IsRunning {
+			if w1IsRunning {
+				return -1
+			}
+			return 1
 		}
 
 		// Order by: usernames
-		if strings.Compare(preloadedUsers[w1.ID].Username, preloadedUsers[w2.ID].Username) < 0 {
-			return true
+		if c := strings.Compare(strings.ToLower(preloadedUsers[w1.ID].Username), strings.ToLower(preloadedUsers[w2.ID].Username)); c != 0 {
+			return c
 		}
 
 		// Order by: workspace names
-		return strings.Compare(w1.Name, w2.Name) < 0
+		if c := strings.Compare(strings.ToLower(w1.Name), strings.ToLower(w2.Name)); c != 0 {
+			return c
+		}
+
+		// Order by: IDs
+		return bytes.Compare(w1.ID[:], w2.ID[:])
 	})
 
 	beforePageCount := len(workspaces)
 
+	if arg.AfterID != uuid.Nil {
+		i := slices.IndexFunc(workspaces, func(w database.WorkspaceTable) bool {
+			return w.ID == arg.AfterID
+		})
+		if i < 0 {
+			// The SQL query compares against NULL, which matches no rows.
+			return q.convertToWorkspaceRowsNoLock(ctx, []database.WorkspaceTable{}, int64(beforePageCount), arg.WithSummary), nil
+		}
+		// Workspaces are sorted, so the ones after the cursor follow it.
+		workspaces = workspaces[i+1:]
+	}
+
 	if arg.Offset > 0 {
 		if int(arg.Offset) > len(workspaces) {
 			return q.convertToWorkspaceRowsNoLock(ctx, []database.WorkspaceTable{}, int64(beforePageCount), arg.WithSummary), nil
@@ -13852,10 +18295,27 @@ func (q *FakeQuerier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg data
 		arg.LimitOpt = 100
 	}
 
+	var afterLog *database.AuditLog
+	if arg.AfterID != uuid.Nil {
+		for _, alog := range q.auditLogs {
+			if alog.ID == arg.AfterID {
+				afterLog = &alog
+				break
+			}
+		}
+		if afterLog == nil {
+			// The SQL query compares against NULL, which matches no rows.
+			return []database.GetAuditLogsOffsetRow{}, nil
+		}
+	}
+
 	logs := make([]database.GetAuditLogsOffsetRow, 0, arg.LimitOpt)
 
 	// q.auditLogs are already sorted by time DESC, so no need to sort after the fact.
 	for _, alog := range q.auditLogs {
+		if afterLog != nil && compareAuditLogs(alog, *afterLog) >= 0 {
+			continue
+		}
 		if arg.OffsetOpt > 0 {
 			arg.OffsetOpt--
 			continue
@@ -13903,6 +18363,15 @@ func (q *FakeQuerier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg data
 				continue
 			}
 		}
+		if arg.ClientType != "" {
+			var fields struct {
+				ClientType string `json:"client_type"`
+			}
+			_ = json.Unmarshal(alog.AdditionalFields, &fields)
+			if fields.ClientType != arg.ClientType {
+				continue
+			}
+		}
 		// If the filter exists, ensure the object is authorized.
 		if prepared != nil && prepared.Authorize(ctx, alog.RBACObject()) != nil {
 			continue
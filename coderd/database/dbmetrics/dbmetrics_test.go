@@ -2,8 +2,11 @@ package dbmetrics_test
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
@@ -12,6 +15,7 @@ import (
 	"cdr.dev/slog/sloggers/sloghuman"
 	"github.com/coder/coder/v2/coderd/coderdtest/promhelp"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmetrics"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/testutil"
@@ -108,3 +112,54 @@ func TestInTxMetrics(t *testing.T) {
 		require.Contains(t, output.String(), "id="+id)
 	})
 }
+
+func TestQueryMetrics(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	reg := prometheus.NewRegistry()
+	var slowQueries []dbmetrics.SlowQuery
+	db = dbmetrics.NewQueryMetrics(db, testutil.Logger(t), reg,
+		dbmetrics.WithSlowQueryHook(0, func(_ context.Context, query dbmetrics.SlowQuery) {
+			slowQueries = append(slowQueries, query)
+		}),
+	)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	user := dbgen.User(t, db, database.User{})
+	_ = dbgen.User(t, db, database.User{})
+	users, err := db.GetUsers(ctx, database.GetUsersParams{})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+
+	// Finding no rows isn't an error, but failing to insert is.
+	_, err = db.GetUserByID(ctx, uuid.New())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = db.InsertUser(ctx, database.InsertUserParams{
+		ID:        user.ID,
+		Email:     user.Email,
+		Username:  user.Username,
+		LoginType: database.LoginTypePassword,
+	})
+	require.Error(t, err)
+
+	getUsers := prometheus.Labels{"query": "GetUsers"}
+	latency := promhelp.HistogramValue(t, reg, "coderd_db_query_latencies_seconds", getUsers)
+	require.Equal(t, uint64(1), latency.GetSampleCount())
+	rows := promhelp.HistogramValue(t, reg, "coderd_db_query_rows", getUsers)
+	require.Equal(t, uint64(1), rows.GetSampleCount())
+	require.Equal(t, float64(2), rows.GetSampleSum())
+
+	rows = promhelp.HistogramValue(t, reg, "coderd_db_query_rows", prometheus.Labels{"query": "GetUserByID"})
+	require.Equal(t, uint64(1), rows.GetSampleCount())
+	require.Zero(t, rows.GetSampleSum())
+	require.Nil(t, promhelp.MetricValue(t, reg, "coderd_db_query_errors_total", prometheus.Labels{"query": "GetUserByID"}))
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_db_query_errors_total", prometheus.Labels{"query": "InsertUser"}))
+
+	// Every query is slower than a threshold of 0.
+	require.NotEmpty(t, slowQueries)
+	last := slowQueries[len(slowQueries)-1]
+	require.Equal(t, "InsertUser", last.Query)
+	require.Zero(t, last.Rows)
+	require.Error(t, last.Err)
+}
@@ -5,6 +5,8 @@ package dbmetrics
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"slices"
 	"time"
 
@@ -27,8 +29,50 @@ var (
 
 const wrapname = "dbmetrics.metricsStore"
 
+// noRows is the row count observed for queries that don't return rows.
+const noRows = -1
+
+// SlowQuery describes a query that took longer than the threshold passed to
+// WithSlowQueryHook.
+type SlowQuery struct {
+	Query    string
+	Duration time.Duration
+	// Rows is the number of rows the query returned, or -1 if it doesn't
+	// return rows.
+	Rows int
+	Err  error
+}
+
+// SlowQueryHook is called with every query that is slower than the threshold
+// passed to WithSlowQueryHook.
+type SlowQueryHook func(ctx context.Context, query SlowQuery)
+
+// LogSlowQuery returns a SlowQueryHook that logs slow queries as warnings.
+func LogSlowQuery(logger slog.Logger) SlowQueryHook {
+	return func(ctx context.Context, query SlowQuery) {
+		logger.Warn(ctx, "slow database query",
+			slog.F("query", query.Query),
+			slog.F("duration", query.Duration),
+			slog.F("rows", query.Rows),
+			slog.Error(query.Err),
+		)
+	}
+}
+
+// QueryMetricsOption configures the store returned by NewQueryMetrics.
+type QueryMetricsOption func(*queryMetricsStore)
+
+// WithSlowQueryHook calls hook with every query that takes at least threshold
+// to execute.
+func WithSlowQueryHook(threshold time.Duration, hook SlowQueryHook) QueryMetricsOption {
+	return func(m *queryMetricsStore) {
+		m.slowQueryThreshold = threshold
+		m.slowQueryHook = hook
+	}
+}
+
 // NewQueryMetrics returns a database.Store that registers metrics for all queries to reg.
-func NewQueryMetrics(s database.Store, logger slog.Logger, reg prometheus.Registerer) database.Store {
+func NewQueryMetrics(s database.Store, logger slog.Logger, reg prometheus.Registerer, opts ...QueryMetricsOption) database.Store {
 	// Don't double-wrap.
 	if slices.Contains(s.Wrappers(), wrapname) {
 		return s
@@ -41,11 +85,32 @@ func NewQueryMetrics(s database.Store, logger slog.Logger, reg prometheus.Regist
 		Buckets:   prometheus.DefBuckets,
 	}, []string{"query"})
 	reg.MustRegister(queryLatencies)
-	return &queryMetricsStore{
+	queryRows := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "coderd",
+		Subsystem: "db",
+		Name:      "query_rows",
+		Help:      "Distribution of the number of rows returned by queries.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+	}, []string{"query"})
+	reg.MustRegister(queryRows)
+	queryErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Total number of queries that returned an error. Queries that found no rows are not counted.",
+	}, []string{"query"})
+	reg.MustRegister(queryErrors)
+	m := &queryMetricsStore{
 		s:              s,
 		queryLatencies: queryLatencies,
+		queryRows:      queryRows,
+		queryErrors:    queryErrors,
 		dbMetrics:      NewDBMetrics(s, logger, reg).(*metricsStore),
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 var _ database.Store = (*queryMetricsStore)(nil)
@@ -53,7 +118,38 @@ var _ database.Store = (*queryMetricsStore)(nil)
 type queryMetricsStore struct {
 	s              database.Store
 	queryLatencies *prometheus.HistogramVec
-	dbMetrics      *metricsStore
+	// queryRows is how many rows queries return.
+	queryRows *prometheus.HistogramVec
+	// queryErrors is how many queries fail.
+	queryErrors *prometheus.CounterVec
+	dbMetrics   *metricsStore
+
+	slowQueryThreshold time.Duration
+	slowQueryHook      SlowQueryHook
+}
+
+// observe records the metrics of a query that started at start. rows is the
+// number of rows the query returned, or noRows if it doesn't return rows.
+func (m queryMetricsStore) observe(ctx context.Context, query string, start time.Time, rows int, err error) {
+	duration := time.Since(start)
+	m.queryLatencies.WithLabelValues(query).Observe(duration.Seconds())
+	if rows != noRows {
+		if err != nil {
+			rows = 0
+		}
+		m.queryRows.WithLabelValues(query).Observe(float64(rows))
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		m.queryErrors.WithLabelValues(query).Inc()
+	}
+	if m.slowQueryHook != nil && duration >= m.slowQueryThreshold {
+		m.slowQueryHook(ctx, SlowQuery{
+			Query:    query,
+			Duration: duration,
+			Rows:     rows,
+			Err:      err,
+		})
+	}
 }
 
 func (m queryMetricsStore) Wrappers() []string {
@@ -63,14 +159,14 @@ func (m queryMetricsStore) Wrappers() []string {
 func (m queryMetricsStore) Ping(ctx context.Context) (time.Duration, error) {
 	start := time.Now()
 	duration, err := m.s.Ping(ctx)
-	m.queryLatencies.WithLabelValues("Ping").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "Ping", start, noRows, err)
 	return duration, err
 }
 
 func (m queryMetricsStore) PGLocks(ctx context.Context) (database.PGLocks, error) {
 	start := time.Now()
 	locks, err := m.s.PGLocks(ctx)
-	m.queryLatencies.WithLabelValues("PGLocks").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "PGLocks", start, len(locks), err)
 	return locks, err
 }
 
@@ -84,3240 +180,4479 @@ func (m queryMetricsStore) DeleteOrganization(ctx context.Context, id uuid.UUID)
 		ID:        id,
 		UpdatedAt: time.Now(),
 	})
-	m.queryLatencies.WithLabelValues("DeleteOrganization").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOrganization", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error {
 	start := time.Now()
 	err := m.s.AcquireLock(ctx, pgAdvisoryXactLock)
-	m.queryLatencies.WithLabelValues("AcquireLock").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "AcquireLock", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.AcquireNotificationMessagesRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.AcquireNotificationMessages(ctx, arg)
-	m.queryLatencies.WithLabelValues("AcquireNotificationMessages").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "AcquireNotificationMessages", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	start := time.Now()
 	provisionerJob, err := m.s.AcquireProvisionerJob(ctx, arg)
-	m.queryLatencies.WithLabelValues("AcquireProvisionerJob").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "AcquireProvisionerJob", start, 1, err)
 	return provisionerJob, err
 }
 
 func (m queryMetricsStore) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	start := time.Now()
 	r0 := m.s.ActivityBumpWorkspace(ctx, arg)
-	m.queryLatencies.WithLabelValues("ActivityBumpWorkspace").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "ActivityBumpWorkspace", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
 	start := time.Now()
 	r0, r1 := m.s.AllUserIDs(ctx, includeSystem)
-	m.queryLatencies.WithLabelValues("AllUserIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "AllUserIDs", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) ApproveLoginDeviceCode(ctx context.Context, arg database.ApproveLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.ApproveLoginDeviceCode(ctx, arg)
+	m.observe(ctx, "ApproveLoginDeviceCode", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) ArchiveUnusedTemplateVersions(ctx context.Context, arg database.ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
 	start := time.Now()
 	r0, r1 := m.s.ArchiveUnusedTemplateVersions(ctx, arg)
-	m.queryLatencies.WithLabelValues("ArchiveUnusedTemplateVersions").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "ArchiveUnusedTemplateVersions", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) BatchUpdateWorkspaceLastUsedAt(ctx context.Context, arg database.BatchUpdateWorkspaceLastUsedAtParams) error {
 	start := time.Now()
 	r0 := m.s.BatchUpdateWorkspaceLastUsedAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("BatchUpdateWorkspaceLastUsedAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "BatchUpdateWorkspaceLastUsedAt", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) BatchUpdateWorkspaceNextStartAt(ctx context.Context, arg database.BatchUpdateWorkspaceNextStartAtParams) error {
 	start := time.Now()
 	r0 := m.s.BatchUpdateWorkspaceNextStartAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("BatchUpdateWorkspaceNextStartAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "BatchUpdateWorkspaceNextStartAt", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) BulkMarkNotificationMessagesFailed(ctx context.Context, arg database.BulkMarkNotificationMessagesFailedParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.BulkMarkNotificationMessagesFailed(ctx, arg)
-	m.queryLatencies.WithLabelValues("BulkMarkNotificationMessagesFailed").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "BulkMarkNotificationMessagesFailed", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) BulkMarkNotificationMessagesSent(ctx context.Context, arg database.BulkMarkNotificationMessagesSentParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.BulkMarkNotificationMessagesSent(ctx, arg)
-	m.queryLatencies.WithLabelValues("BulkMarkNotificationMessagesSent").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "BulkMarkNotificationMessagesSent", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) ClaimPrebuiltWorkspace(ctx context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.ClaimPrebuiltWorkspaceRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.ClaimPrebuiltWorkspace(ctx, arg)
-	m.queryLatencies.WithLabelValues("ClaimPrebuiltWorkspace").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "ClaimPrebuiltWorkspace", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.CleanTailnetCoordinators(ctx)
-	m.queryLatencies.WithLabelValues("CleanTailnetCoordinators").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "CleanTailnetCoordinators", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) CleanTailnetLostPeers(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.CleanTailnetLostPeers(ctx)
-	m.queryLatencies.WithLabelValues("CleanTailnetLostPeers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "CleanTailnetLostPeers", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) CleanTailnetTunnels(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.CleanTailnetTunnels(ctx)
-	m.queryLatencies.WithLabelValues("CleanTailnetTunnels").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "CleanTailnetTunnels", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) CompleteWorkspaceMigration(ctx context.Context, arg database.CompleteWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	start := time.Now()
+	r0, r1 := m.s.CompleteWorkspaceMigration(ctx, arg)
+	m.observe(ctx, "CompleteWorkspaceMigration", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) ConsumeApprovedLoginDeviceCode(ctx context.Context, arg database.ConsumeApprovedLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.ConsumeApprovedLoginDeviceCode(ctx, arg)
+	m.observe(ctx, "ConsumeApprovedLoginDeviceCode", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) CountInProgressPrebuilds(ctx context.Context) ([]database.CountInProgressPrebuildsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.CountInProgressPrebuilds(ctx)
-	m.queryLatencies.WithLabelValues("CountInProgressPrebuilds").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "CountInProgressPrebuilds", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) CountInProgressProvisionerJobsByInitiator(ctx context.Context, arg database.CountInProgressProvisionerJobsByInitiatorParams) (database.CountInProgressProvisionerJobsByInitiatorRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountInProgressProvisionerJobsByInitiator(ctx, arg)
+	m.observe(ctx, "CountInProgressProvisionerJobsByInitiator", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.CountUnreadInboxNotificationsByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("CountUnreadInboxNotificationsByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "CountUnreadInboxNotificationsByUserID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) CreateTimePartition(ctx context.Context, arg database.CreateTimePartitionParams) (bool, error) {
+	start := time.Now()
+	r0, r1 := m.s.CreateTimePartition(ctx, arg)
+	m.observe(ctx, "CreateTimePartition", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	start := time.Now()
 	r0, r1 := m.s.CustomRoles(ctx, arg)
-	m.queryLatencies.WithLabelValues("CustomRoles").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "CustomRoles", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	start := time.Now()
 	err := m.s.DeleteAPIKeyByID(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteAPIKeyByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteAPIKeyByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteAPIKeysByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("DeleteAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteAPIKeysByUserID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg database.DeleteAllTailnetClientSubscriptionsParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteAllTailnetClientSubscriptions(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteAllTailnetClientSubscriptions").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteAllTailnetClientSubscriptions", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteAllTailnetTunnels(ctx context.Context, arg database.DeleteAllTailnetTunnelsParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteAllTailnetTunnels(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteAllTailnetTunnels").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteAllTailnetTunnels", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteAllWebpushSubscriptions(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteAllWebpushSubscriptions(ctx)
-	m.queryLatencies.WithLabelValues("DeleteAllWebpushSubscriptions").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteAllWebpushSubscriptions", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteApplicationConnectAPIKeysByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("DeleteApplicationConnectAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteApplicationConnectAPIKeysByUserID", start, noRows, err)
 	return err
 }
 
+func (m queryMetricsStore) DeleteBuildAlertRuleByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteBuildAlertRuleByID(ctx, id)
+	m.observe(ctx, "DeleteBuildAlertRuleByID", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteCoordinator(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteCoordinator").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteCoordinator", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteCryptoKey(ctx context.Context, arg database.DeleteCryptoKeyParams) (database.CryptoKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteCryptoKey(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteCryptoKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteCryptoKey", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) DeleteCustomRole(ctx context.Context, arg database.DeleteCustomRoleParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteCustomRole(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteCustomRole").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteCustomRole", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteExpiredLoginDeviceCodes(ctx context.Context, before time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteExpiredLoginDeviceCodes(ctx, before)
+	m.observe(ctx, "DeleteExpiredLoginDeviceCodes", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteExternalAuthLink(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteExternalAuthLink").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteExternalAuthLink", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteGitSSHKey(ctx, userID)
-	m.queryLatencies.WithLabelValues("DeleteGitSSHKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteGitSSHKey", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteGroupByID(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteGroupByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteGroupByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) DeleteGroupMemberFromGroup(ctx context.Context, arg database.DeleteGroupMemberFromGroupParams) error {
 	start := time.Now()
 	err := m.s.DeleteGroupMemberFromGroup(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteGroupMemberFromGroup").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteGroupMemberFromGroup", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	start := time.Now()
 	licenseID, err := m.s.DeleteLicense(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteLicense").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteLicense", start, 1, err)
 	return licenseID, err
 }
 
 func (m queryMetricsStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppByID(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOAuth2ProviderAppByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppCodeByID(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOAuth2ProviderAppCodeByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodesByAppAndUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOAuth2ProviderAppCodesByAppAndUserID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOAuth2ProviderAppSecretByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppTokensByAppAndUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOAuth2ProviderAppTokensByAppAndUserID", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldAuditLogs(ctx, arg)
+	m.observe(ctx, "DeleteOldAuditLogs", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteOldDeletedWorkspaces(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldDeletedWorkspaces(ctx, before)
+	m.observe(ctx, "DeleteOldDeletedWorkspaces", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteOldNotificationMessages(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldNotificationMessages(ctx)
-	m.queryLatencies.WithLabelValues("DeleteOldNotificationMessages").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOldNotificationMessages", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldProvisionerDaemons(ctx)
-	m.queryLatencies.WithLabelValues("DeleteOldProvisionerDaemons").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOldProvisionerDaemons", start, noRows, r0)
 	return r0
 }
 
-func (m queryMetricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, arg time.Time) error {
+func (m queryMetricsStore) DeleteOldProvisionerJobLogs(ctx context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
 	start := time.Now()
-	r0 := m.s.DeleteOldWorkspaceAgentLogs(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentLogs").Observe(time.Since(start).Seconds())
+	r0, r1 := m.s.DeleteOldProvisionerJobLogs(ctx, arg)
+	m.observe(ctx, "DeleteOldProvisionerJobLogs", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceAgentConnectionQuality(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceAgentConnectionQuality(ctx, beforeTime)
+	m.observe(ctx, "DeleteOldWorkspaceAgentConnectionQuality", start, noRows, r0)
 	return r0
 }
 
-func (m queryMetricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+func (m queryMetricsStore) DeleteOldWorkspaceAgentHealthEvents(ctx context.Context, beforeTime time.Time) error {
 	start := time.Now()
-	err := m.s.DeleteOldWorkspaceAgentStats(ctx)
-	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentStats").Observe(time.Since(start).Seconds())
-	return err
+	r0 := m.s.DeleteOldWorkspaceAgentHealthEvents(ctx, beforeTime)
+	m.observe(ctx, "DeleteOldWorkspaceAgentHealthEvents", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, arg time.Time) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldWorkspaceAgentLogs(ctx, arg)
+	m.observe(ctx, "DeleteOldWorkspaceAgentLogs", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceAgentResourceUsage(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceAgentResourceUsage(ctx, beforeTime)
+	m.observe(ctx, "DeleteOldWorkspaceAgentResourceUsage", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldWorkspaceAgentStats(ctx, before)
+	m.observe(ctx, "DeleteOldWorkspaceAgentStats", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceBuildIdempotencyKeys(ctx context.Context, before time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceBuildIdempotencyKeys(ctx, before)
+	m.observe(ctx, "DeleteOldWorkspaceBuildIdempotencyKeys", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceSessionRecordings(ctx, beforeTime)
+	m.observe(ctx, "DeleteOldWorkspaceSessionRecordings", start, noRows, r0)
+	return r0
 }
 
 func (m queryMetricsStore) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteOrganizationMember(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteOrganizationMember").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteOrganizationMember", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteProvisionerBuildPause(ctx, organizationID)
+	m.observe(ctx, "DeleteProvisionerBuildPause", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteProvisionerJobLogsByJobID(ctx, jobID)
+	m.observe(ctx, "DeleteProvisionerJobLogsByJobID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteProvisionerKey(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteProvisionerKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteProvisionerKey", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteProvisionerReservation(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteProvisionerReservation(ctx, id)
+	m.observe(ctx, "DeleteProvisionerReservation", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
 	start := time.Now()
 	err := m.s.DeleteReplicasUpdatedBefore(ctx, updatedAt)
-	m.queryLatencies.WithLabelValues("DeleteReplicasUpdatedBefore").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteReplicasUpdatedBefore", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) DeleteRuntimeConfig(ctx context.Context, key string) error {
 	start := time.Now()
 	r0 := m.s.DeleteRuntimeConfig(ctx, key)
-	m.queryLatencies.WithLabelValues("DeleteRuntimeConfig").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteRuntimeConfig", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetAgent(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteTailnetAgent").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteTailnetAgent", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) DeleteTailnetClient(ctx context.Context, arg database.DeleteTailnetClientParams) (database.DeleteTailnetClientRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetClient(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteTailnetClient").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteTailnetClient", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) DeleteTailnetClientSubscription(ctx context.Context, arg database.DeleteTailnetClientSubscriptionParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteTailnetClientSubscription(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteTailnetClientSubscription").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteTailnetClientSubscription", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteTailnetPeer(ctx context.Context, arg database.DeleteTailnetPeerParams) (database.DeleteTailnetPeerRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetPeer(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteTailnetPeer").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteTailnetPeer", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) DeleteTailnetTunnel(ctx context.Context, arg database.DeleteTailnetTunnelParams) (database.DeleteTailnetTunnelRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetTunnel(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteTailnetTunnel").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteTailnetTunnel", start, 1, r1)
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteTemplatePresetByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplatePresetByID(ctx, id)
+	m.observe(ctx, "DeleteTemplatePresetByID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplatePresetGroupDefaultsByPresetID(ctx context.Context, templatePresetID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplatePresetGroupDefaultsByPresetID(ctx, templatePresetID)
+	m.observe(ctx, "DeleteTemplatePresetGroupDefaultsByPresetID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateReleaseChannel(ctx context.Context, arg database.DeleteTemplateReleaseChannelParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateReleaseChannel(ctx, arg)
+	m.observe(ctx, "DeleteTemplateReleaseChannel", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateShare(ctx context.Context, arg database.DeleteTemplateShareParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateShare(ctx, arg)
+	m.observe(ctx, "DeleteTemplateShare", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateVersionRolloutByTemplateID(ctx, templateID)
+	m.observe(ctx, "DeleteTemplateVersionRolloutByTemplateID", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteWebpushSubscriptionByUserIDAndEndpoint").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteWebpushSubscriptionByUserIDAndEndpoint", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteWebpushSubscriptions(ctx context.Context, ids []uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteWebpushSubscriptions(ctx, ids)
-	m.queryLatencies.WithLabelValues("DeleteWebpushSubscriptions").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteWebpushSubscriptions", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteWorkspaceAgentPortShare(ctx, arg)
-	m.queryLatencies.WithLabelValues("DeleteWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteWorkspaceAgentPortShare", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
-	m.queryLatencies.WithLabelValues("DeleteWorkspaceAgentPortSharesByTemplate").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteWorkspaceAgentPortSharesByTemplate", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceBuildArchiveByBuildID(ctx, workspaceBuildID)
+	m.observe(ctx, "DeleteWorkspaceBuildArchiveByBuildID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceBuildInterimState(ctx context.Context, workspaceBuildID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceBuildInterimState(ctx, workspaceBuildID)
+	m.observe(ctx, "DeleteWorkspaceBuildInterimState", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceBuildQueueEntry(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceBuildQueueEntry(ctx, id)
+	m.observe(ctx, "DeleteWorkspaceBuildQueueEntry", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceLabelsByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "DeleteWorkspaceLabelsByWorkspaceID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspacePrebuildReservationByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "DeleteWorkspacePrebuildReservationByWorkspaceID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceReleaseChannelByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "DeleteWorkspaceReleaseChannelByWorkspaceID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceResourceMetadataByResourceIDs(ctx, ids)
+	m.observe(ctx, "DeleteWorkspaceResourceMetadataByResourceIDs", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteWorkspaceSubAgentByID(ctx, id)
-	m.queryLatencies.WithLabelValues("DeleteWorkspaceSubAgentByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DeleteWorkspaceSubAgentByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DisableForeignKeysAndTriggers(ctx)
-	m.queryLatencies.WithLabelValues("DisableForeignKeysAndTriggers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "DisableForeignKeysAndTriggers", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) DropOldAuditLogsPartitions(ctx context.Context, before time.Time) (int32, error) {
+	start := time.Now()
+	r0, r1 := m.s.DropOldAuditLogsPartitions(ctx, before)
+	m.observe(ctx, "DropOldAuditLogsPartitions", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) DropOldWorkspaceAgentStatsPartitions(ctx context.Context, before time.Time) (int32, error) {
+	start := time.Now()
+	r0, r1 := m.s.DropOldWorkspaceAgentStatsPartitions(ctx, before)
+	m.observe(ctx, "DropOldWorkspaceAgentStatsPartitions", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error {
 	start := time.Now()
 	r0 := m.s.EnqueueNotificationMessage(ctx, arg)
-	m.queryLatencies.WithLabelValues("EnqueueNotificationMessage").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "EnqueueNotificationMessage", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) FavoriteWorkspace(ctx context.Context, arg uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.FavoriteWorkspace(ctx, arg)
-	m.queryLatencies.WithLabelValues("FavoriteWorkspace").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "FavoriteWorkspace", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) FetchMemoryResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentMemoryResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.FetchMemoryResourceMonitorsByAgentID(ctx, agentID)
-	m.queryLatencies.WithLabelValues("FetchMemoryResourceMonitorsByAgentID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "FetchMemoryResourceMonitorsByAgentID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) FetchMemoryResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.WorkspaceAgentMemoryResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.FetchMemoryResourceMonitorsUpdatedAfter(ctx, updatedAt)
-	m.queryLatencies.WithLabelValues("FetchMemoryResourceMonitorsUpdatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "FetchMemoryResourceMonitorsUpdatedAfter", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) FetchNewMessageMetadata(ctx context.Context, arg database.FetchNewMessageMetadataParams) (database.FetchNewMessageMetadataRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.FetchNewMessageMetadata(ctx, arg)
-	m.queryLatencies.WithLabelValues("FetchNewMessageMetadata").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "FetchNewMessageMetadata", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.FetchVolumesResourceMonitorsByAgentID(ctx, agentID)
-	m.queryLatencies.WithLabelValues("FetchVolumesResourceMonitorsByAgentID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "FetchVolumesResourceMonitorsByAgentID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) FetchVolumesResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.FetchVolumesResourceMonitorsUpdatedAfter(ctx, updatedAt)
-	m.queryLatencies.WithLabelValues("FetchVolumesResourceMonitorsUpdatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "FetchVolumesResourceMonitorsUpdatedAfter", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetAPIKeyByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAPIKeyByID", start, 1, err)
 	return apiKey, err
 }
 
 func (m queryMetricsStore) GetAPIKeyByName(ctx context.Context, arg database.GetAPIKeyByNameParams) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByName(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetAPIKeyByName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAPIKeyByName", start, 1, err)
 	return apiKey, err
 }
 
 func (m queryMetricsStore) GetAPIKeysByLoginType(ctx context.Context, loginType database.LoginType) ([]database.APIKey, error) {
 	start := time.Now()
 	apiKeys, err := m.s.GetAPIKeysByLoginType(ctx, loginType)
-	m.queryLatencies.WithLabelValues("GetAPIKeysByLoginType").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAPIKeysByLoginType", start, len(apiKeys), err)
 	return apiKeys, err
 }
 
 func (m queryMetricsStore) GetAPIKeysByUserID(ctx context.Context, arg database.GetAPIKeysByUserIDParams) ([]database.APIKey, error) {
 	start := time.Now()
 	apiKeys, err := m.s.GetAPIKeysByUserID(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAPIKeysByUserID", start, len(apiKeys), err)
 	return apiKeys, err
 }
 
 func (m queryMetricsStore) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]database.APIKey, error) {
 	start := time.Now()
 	apiKeys, err := m.s.GetAPIKeysLastUsedAfter(ctx, lastUsed)
-	m.queryLatencies.WithLabelValues("GetAPIKeysLastUsedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAPIKeysLastUsedAfter", start, len(apiKeys), err)
 	return apiKeys, err
 }
 
+func (m queryMetricsStore) GetAccessRequestByID(ctx context.Context, id uuid.UUID) (database.AccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAccessRequestByID(ctx, id)
+	m.observe(ctx, "GetAccessRequestByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAccessRequests(ctx context.Context, arg database.GetAccessRequestsParams) ([]database.AccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAccessRequests(ctx, arg)
+	m.observe(ctx, "GetAccessRequests", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetActivePresetPrebuildSchedules(ctx context.Context) ([]database.TemplateVersionPresetPrebuildSchedule, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetActivePresetPrebuildSchedules(ctx)
-	m.queryLatencies.WithLabelValues("GetActivePresetPrebuildSchedules").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetActivePresetPrebuildSchedules", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
 	start := time.Now()
 	count, err := m.s.GetActiveUserCount(ctx, includeSystem)
-	m.queryLatencies.WithLabelValues("GetActiveUserCount").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetActiveUserCount", start, 1, err)
 	return count, err
 }
 
 func (m queryMetricsStore) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspaceBuild, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetActiveWorkspaceBuildsByTemplateID(ctx, templateID)
-	m.queryLatencies.WithLabelValues("GetActiveWorkspaceBuildsByTemplateID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetActiveWorkspaceBuildsByTemplateID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetActiveWorkspaceLockByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceLock, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveWorkspaceLockByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetActiveWorkspaceLockByWorkspaceID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetActiveWorkspaceMigrations(ctx context.Context) ([]database.WorkspaceMigration, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveWorkspaceMigrations(ctx)
+	m.observe(ctx, "GetActiveWorkspaceMigrations", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAllTailnetAgents(ctx context.Context) ([]database.TailnetAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetAgents(ctx)
-	m.queryLatencies.WithLabelValues("GetAllTailnetAgents").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAllTailnetAgents", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAllTailnetCoordinators(ctx context.Context) ([]database.TailnetCoordinator, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetCoordinators(ctx)
-	m.queryLatencies.WithLabelValues("GetAllTailnetCoordinators").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAllTailnetCoordinators", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAllTailnetPeers(ctx context.Context) ([]database.TailnetPeer, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetPeers(ctx)
-	m.queryLatencies.WithLabelValues("GetAllTailnetPeers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAllTailnetPeers", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAllTailnetTunnels(ctx context.Context) ([]database.TailnetTunnel, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetTunnels(ctx)
-	m.queryLatencies.WithLabelValues("GetAllTailnetTunnels").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAllTailnetTunnels", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAnnouncementBanners(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAnnouncementBanners(ctx)
-	m.queryLatencies.WithLabelValues("GetAnnouncementBanners").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAnnouncementBanners", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAppSecurityKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	key, err := m.s.GetAppSecurityKey(ctx)
-	m.queryLatencies.WithLabelValues("GetAppSecurityKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAppSecurityKey", start, 1, err)
 	return key, err
 }
 
 func (m queryMetricsStore) GetApplicationName(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetApplicationName(ctx)
-	m.queryLatencies.WithLabelValues("GetApplicationName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetApplicationName", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAuditLogsForExport(ctx context.Context, arg database.GetAuditLogsForExportParams) ([]database.GetAuditLogsForExportRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogsForExport(ctx, arg)
+	m.observe(ctx, "GetAuditLogsForExport", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	start := time.Now()
 	rows, err := m.s.GetAuditLogsOffset(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetAuditLogsOffset").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAuditLogsOffset", start, len(rows), err)
 	return rows, err
 }
 
 func (m queryMetricsStore) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (database.GetAuthorizationUserRolesRow, error) {
 	start := time.Now()
 	row, err := m.s.GetAuthorizationUserRoles(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetAuthorizationUserRoles").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAuthorizationUserRoles", start, 1, err)
 	return row, err
 }
 
+func (m queryMetricsStore) GetBatchedNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]database.GetBatchedNotificationMessagesByUserIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetBatchedNotificationMessagesByUserID(ctx, userID)
+	m.observe(ctx, "GetBatchedNotificationMessagesByUserID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetBuildAlertRuleByID(ctx context.Context, id uuid.UUID) (database.BuildAlertRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetBuildAlertRuleByID(ctx, id)
+	m.observe(ctx, "GetBuildAlertRuleByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetBuildAlertRuleMetrics(ctx context.Context, arg database.GetBuildAlertRuleMetricsParams) (database.GetBuildAlertRuleMetricsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetBuildAlertRuleMetrics(ctx, arg)
+	m.observe(ctx, "GetBuildAlertRuleMetrics", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetBuildAlertRules(ctx context.Context) ([]database.BuildAlertRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetBuildAlertRules(ctx)
+	m.observe(ctx, "GetBuildAlertRules", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetBuildAlertRulesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.BuildAlertRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetBuildAlertRulesByOrganizationID(ctx, organizationID)
+	m.observe(ctx, "GetBuildAlertRulesByOrganizationID", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetCoordinatorResumeTokenSigningKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetCoordinatorResumeTokenSigningKey(ctx)
-	m.queryLatencies.WithLabelValues("GetCoordinatorResumeTokenSigningKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetCoordinatorResumeTokenSigningKey", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetCryptoKeyByFeatureAndSequence(ctx context.Context, arg database.GetCryptoKeyByFeatureAndSequenceParams) (database.CryptoKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetCryptoKeyByFeatureAndSequence(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetCryptoKeyByFeatureAndSequence").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetCryptoKeyByFeatureAndSequence", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetCryptoKeys(ctx context.Context) ([]database.CryptoKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetCryptoKeys(ctx)
-	m.queryLatencies.WithLabelValues("GetCryptoKeys").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetCryptoKeys", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetCryptoKeysByFeature(ctx context.Context, feature database.CryptoKeyFeature) ([]database.CryptoKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetCryptoKeysByFeature(ctx, feature)
-	m.queryLatencies.WithLabelValues("GetCryptoKeysByFeature").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetCryptoKeysByFeature", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetDBCryptKeys(ctx)
-	m.queryLatencies.WithLabelValues("GetDBCryptKeys").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDBCryptKeys", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetDBPurgeStats(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetDBPurgeStats(ctx)
+	m.observe(ctx, "GetDBPurgeStats", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetDERPMeshKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	key, err := m.s.GetDERPMeshKey(ctx)
-	m.queryLatencies.WithLabelValues("GetDERPMeshKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDERPMeshKey", start, 1, err)
 	return key, err
 }
 
 func (m queryMetricsStore) GetDefaultOrganization(ctx context.Context) (database.Organization, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetDefaultOrganization(ctx)
-	m.queryLatencies.WithLabelValues("GetDefaultOrganization").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDefaultOrganization", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetDefaultProxyConfig(ctx context.Context) (database.GetDefaultProxyConfigRow, error) {
 	start := time.Now()
 	resp, err := m.s.GetDefaultProxyConfig(ctx)
-	m.queryLatencies.WithLabelValues("GetDefaultProxyConfig").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDefaultProxyConfig", start, 1, err)
 	return resp, err
 }
 
+func (m queryMetricsStore) GetDefaultTemplatePresetForUser(ctx context.Context, arg database.GetDefaultTemplatePresetForUserParams) (database.TemplatePreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetDefaultTemplatePresetForUser(ctx, arg)
+	m.observe(ctx, "GetDefaultTemplatePresetForUser", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]database.GetDeploymentDAUsRow, error) {
 	start := time.Now()
 	rows, err := m.s.GetDeploymentDAUs(ctx, tzOffset)
-	m.queryLatencies.WithLabelValues("GetDeploymentDAUs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDeploymentDAUs", start, len(rows), err)
 	return rows, err
 }
 
 func (m queryMetricsStore) GetDeploymentID(ctx context.Context) (string, error) {
 	start := time.Now()
 	id, err := m.s.GetDeploymentID(ctx)
-	m.queryLatencies.WithLabelValues("GetDeploymentID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDeploymentID", start, 1, err)
 	return id, err
 }
 
 func (m queryMetricsStore) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (database.GetDeploymentWorkspaceAgentStatsRow, error) {
 	start := time.Now()
 	row, err := m.s.GetDeploymentWorkspaceAgentStats(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetDeploymentWorkspaceAgentStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDeploymentWorkspaceAgentStats", start, 1, err)
 	return row, err
 }
 
 func (m queryMetricsStore) GetDeploymentWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) (database.GetDeploymentWorkspaceAgentUsageStatsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetDeploymentWorkspaceAgentUsageStats(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetDeploymentWorkspaceAgentUsageStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDeploymentWorkspaceAgentUsageStats", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetDeploymentWorkspaceStats(ctx context.Context) (database.GetDeploymentWorkspaceStatsRow, error) {
 	start := time.Now()
 	row, err := m.s.GetDeploymentWorkspaceStats(ctx)
-	m.queryLatencies.WithLabelValues("GetDeploymentWorkspaceStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetDeploymentWorkspaceStats", start, 1, err)
 	return row, err
 }
 
 func (m queryMetricsStore) GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx context.Context, provisionerJobIds []uuid.UUID) ([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx, provisionerJobIds)
-	m.queryLatencies.WithLabelValues("GetEligibleProvisionerDaemonsByProvisionerJobIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetEligibleProvisionerDaemonsByProvisionerJobIDs", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetEncryptedWorkspaceSessionRecordingChunks(ctx context.Context) ([]database.WorkspaceSessionRecordingChunk, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetEncryptedWorkspaceSessionRecordingChunks(ctx)
+	m.observe(ctx, "GetEncryptedWorkspaceSessionRecordingChunks", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetExpiredAccessRequests(ctx context.Context, now time.Time) ([]database.AccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetExpiredAccessRequests(ctx, now)
+	m.observe(ctx, "GetExpiredAccessRequests", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.GetExternalAuthLink(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetExternalAuthLink").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetExternalAuthLink", start, 1, err)
 	return link, err
 }
 
 func (m queryMetricsStore) GetExternalAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]database.ExternalAuthLink, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetExternalAuthLinksByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetExternalAuthLinksByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetExternalAuthLinksByUserID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetFailedWorkspaceBuildsByTemplateID(ctx context.Context, arg database.GetFailedWorkspaceBuildsByTemplateIDParams) ([]database.GetFailedWorkspaceBuildsByTemplateIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetFailedWorkspaceBuildsByTemplateID(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetFailedWorkspaceBuildsByTemplateID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetFailedWorkspaceBuildsByTemplateID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetFileByHashAndCreator(ctx context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
 	start := time.Now()
 	file, err := m.s.GetFileByHashAndCreator(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetFileByHashAndCreator").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetFileByHashAndCreator", start, 1, err)
 	return file, err
 }
 
 func (m queryMetricsStore) GetFileByID(ctx context.Context, id uuid.UUID) (database.File, error) {
 	start := time.Now()
 	file, err := m.s.GetFileByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetFileByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetFileByID", start, 1, err)
 	return file, err
 }
 
 func (m queryMetricsStore) GetFileIDByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (uuid.UUID, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetFileIDByTemplateVersionID(ctx, templateVersionID)
-	m.queryLatencies.WithLabelValues("GetFileIDByTemplateVersionID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetFileIDByTemplateVersionID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]database.GetFileTemplatesRow, error) {
 	start := time.Now()
 	rows, err := m.s.GetFileTemplates(ctx, fileID)
-	m.queryLatencies.WithLabelValues("GetFileTemplates").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetFileTemplates", start, len(rows), err)
 	return rows, err
 }
 
 func (m queryMetricsStore) GetFilteredInboxNotificationsByUserID(ctx context.Context, arg database.GetFilteredInboxNotificationsByUserIDParams) ([]database.InboxNotification, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetFilteredInboxNotificationsByUserID(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetFilteredInboxNotificationsByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetFilteredInboxNotificationsByUserID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (database.GitSSHKey, error) {
 	start := time.Now()
 	key, err := m.s.GetGitSSHKey(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetGitSSHKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetGitSSHKey", start, 1, err)
 	return key, err
 }
 
 func (m queryMetricsStore) GetGroupByID(ctx context.Context, id uuid.UUID) (database.Group, error) {
 	start := time.Now()
 	group, err := m.s.GetGroupByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetGroupByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetGroupByID", start, 1, err)
 	return group, err
 }
 
 func (m queryMetricsStore) GetGroupByOrgAndName(ctx context.Context, arg database.GetGroupByOrgAndNameParams) (database.Group, error) {
 	start := time.Now()
 	group, err := m.s.GetGroupByOrgAndName(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetGroupByOrgAndName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetGroupByOrgAndName", start, 1, err)
 	return group, err
 }
 
 func (m queryMetricsStore) GetGroupMembers(ctx context.Context, includeSystem bool) ([]database.GroupMember, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetGroupMembers(ctx, includeSystem)
-	m.queryLatencies.WithLabelValues("GetGroupMembers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetGroupMembers", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetGroupMembersByGroupID(ctx context.Context, arg database.GetGroupMembersByGroupIDParams) ([]database.GroupMember, error) {
 	start := time.Now()
 	users, err := m.s.GetGroupMembersByGroupID(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetGroupMembersByGroupID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetGroupMembersByGroupID", start, len(users), err)
 	return users, err
 }
 
 func (m queryMetricsStore) GetGroupMembersCountByGroupID(ctx context.Context, arg database.GetGroupMembersCountByGroupIDParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetGroupMembersCountByGroupID(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetGroupMembersCountByGroupID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetGroupMembersCountByGroupID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetGroups(ctx context.Context, arg database.GetGroupsParams) ([]database.GetGroupsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetGroups(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetGroups").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetGroups", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetHealthSettings(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetHealthSettings(ctx)
-	m.queryLatencies.WithLabelValues("GetHealthSettings").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetHealthSettings", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (database.InboxNotification, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetInboxNotificationByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetInboxNotificationByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetInboxNotificationByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetInboxNotificationsByUserID(ctx context.Context, userID database.GetInboxNotificationsByUserIDParams) ([]database.InboxNotification, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetInboxNotificationsByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetInboxNotificationsByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetInboxNotificationsByUserID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetJobReaperLastRunAt(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetJobReaperLastRunAt(ctx)
+	m.observe(ctx, "GetJobReaperLastRunAt", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	start := time.Now()
 	version, err := m.s.GetLastUpdateCheck(ctx)
-	m.queryLatencies.WithLabelValues("GetLastUpdateCheck").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLastUpdateCheck", start, 1, err)
 	return version, err
 }
 
 func (m queryMetricsStore) GetLatestCryptoKeyByFeature(ctx context.Context, feature database.CryptoKeyFeature) (database.CryptoKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetLatestCryptoKeyByFeature(ctx, feature)
-	m.queryLatencies.WithLabelValues("GetLatestCryptoKeyByFeature").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLatestCryptoKeyByFeature", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetLatestSuccessfulWorkspaceBuildByWorkspaceID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAppStatus, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetLatestWorkspaceAppStatusesByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLatestWorkspaceAppStatusesByWorkspaceIDs", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
-	m.queryLatencies.WithLabelValues("GetLatestWorkspaceBuildByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLatestWorkspaceBuildByWorkspaceID", start, 1, err)
 	return build, err
 }
 
 func (m queryMetricsStore) GetLatestWorkspaceBuilds(ctx context.Context) ([]database.WorkspaceBuild, error) {
 	start := time.Now()
 	builds, err := m.s.GetLatestWorkspaceBuilds(ctx)
-	m.queryLatencies.WithLabelValues("GetLatestWorkspaceBuilds").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLatestWorkspaceBuilds", start, len(builds), err)
 	return builds, err
 }
 
 func (m queryMetricsStore) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceBuild, error) {
 	start := time.Now()
 	builds, err := m.s.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetLatestWorkspaceBuildsByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLatestWorkspaceBuildsByWorkspaceIDs", start, len(builds), err)
 	return builds, err
 }
 
 func (m queryMetricsStore) GetLicenseByID(ctx context.Context, id int32) (database.License, error) {
 	start := time.Now()
 	license, err := m.s.GetLicenseByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetLicenseByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLicenseByID", start, 1, err)
 	return license, err
 }
 
 func (m queryMetricsStore) GetLicenses(ctx context.Context) ([]database.License, error) {
 	start := time.Now()
 	licenses, err := m.s.GetLicenses(ctx)
-	m.queryLatencies.WithLabelValues("GetLicenses").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLicenses", start, len(licenses), err)
 	return licenses, err
 }
 
+func (m queryMetricsStore) GetLoginDeviceCodeByDeviceCodeHash(ctx context.Context, deviceCodeHash []byte) (database.LoginDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLoginDeviceCodeByDeviceCodeHash(ctx, deviceCodeHash)
+	m.observe(ctx, "GetLoginDeviceCodeByDeviceCodeHash", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetLoginDeviceCodeByUserCode(ctx context.Context, userCode string) (database.LoginDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLoginDeviceCodeByUserCode(ctx, userCode)
+	m.observe(ctx, "GetLoginDeviceCodeByUserCode", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	start := time.Now()
 	url, err := m.s.GetLogoURL(ctx)
-	m.queryLatencies.WithLabelValues("GetLogoURL").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetLogoURL", start, 1, err)
 	return url, err
 }
 
 func (m queryMetricsStore) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationMessagesByStatus(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetNotificationMessagesByStatus").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetNotificationMessagesByStatus", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetNotificationReportGeneratorLogByTemplate(ctx context.Context, arg uuid.UUID) (database.NotificationReportGeneratorLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationReportGeneratorLogByTemplate(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetNotificationReportGeneratorLogByTemplate").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetNotificationReportGeneratorLogByTemplate", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetNotificationTemplateByID(ctx context.Context, id uuid.UUID) (database.NotificationTemplate, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationTemplateByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetNotificationTemplateByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetNotificationTemplateByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetNotificationTemplatesByKind(ctx context.Context, kind database.NotificationTemplateKind) ([]database.NotificationTemplate, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationTemplatesByKind(ctx, kind)
-	m.queryLatencies.WithLabelValues("GetNotificationTemplatesByKind").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetNotificationTemplatesByKind", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetNotificationsSettings(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationsSettings(ctx)
-	m.queryLatencies.WithLabelValues("GetNotificationsSettings").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetNotificationsSettings", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2GithubDefaultEligible(ctx context.Context) (bool, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2GithubDefaultEligible(ctx)
-	m.queryLatencies.WithLabelValues("GetOAuth2GithubDefaultEligible").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2GithubDefaultEligible", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderAppByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppCodeByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderAppCodeByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderAppCode, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppCodeByPrefix(ctx, secretPrefix)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByPrefix").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderAppCodeByPrefix", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppSecretByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderAppSecretByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderAppSecretByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppSecretByPrefix(ctx, secretPrefix)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByPrefix").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderAppSecretByPrefix", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretsByAppID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderAppSecretsByAppID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppTokenByPrefix(ctx, hashPrefix)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppTokenByPrefix").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderAppTokenByPrefix", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderApps(ctx)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderApps").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderApps", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.GetOAuth2ProviderAppsByUserIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppsByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppsByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuth2ProviderAppsByUserID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuthSigningKey(ctx)
-	m.queryLatencies.WithLabelValues("GetOAuthSigningKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOAuthSigningKey", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetOIDCUserClaims(ctx context.Context, organizationID uuid.UUID) ([]database.GetOIDCUserClaimsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOIDCUserClaims(ctx, organizationID)
+	m.observe(ctx, "GetOIDCUserClaims", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.GetOrganizationByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetOrganizationByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOrganizationByID", start, 1, err)
 	return organization, err
 }
 
 func (m queryMetricsStore) GetOrganizationByName(ctx context.Context, name database.GetOrganizationByNameParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.GetOrganizationByName(ctx, name)
-	m.queryLatencies.WithLabelValues("GetOrganizationByName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOrganizationByName", start, 1, err)
 	return organization, err
 }
 
 func (m queryMetricsStore) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetOrganizationIDsByMemberIDsRow, error) {
 	start := time.Now()
 	organizations, err := m.s.GetOrganizationIDsByMemberIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetOrganizationIDsByMemberIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOrganizationIDsByMemberIDs", start, len(organizations), err)
 	return organizations, err
 }
 
 func (m queryMetricsStore) GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (database.GetOrganizationResourceCountByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOrganizationResourceCountByID(ctx, organizationID)
-	m.queryLatencies.WithLabelValues("GetOrganizationResourceCountByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOrganizationResourceCountByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetOrganizationWorkspaceNamingPolicy(ctx context.Context, organizationID uuid.UUID) (database.WorkspaceNamingPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationWorkspaceNamingPolicy(ctx, organizationID)
+	m.observe(ctx, "GetOrganizationWorkspaceNamingPolicy", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetOrganizations(ctx context.Context, args database.GetOrganizationsParams) ([]database.Organization, error) {
 	start := time.Now()
 	organizations, err := m.s.GetOrganizations(ctx, args)
-	m.queryLatencies.WithLabelValues("GetOrganizations").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOrganizations", start, len(organizations), err)
 	return organizations, err
 }
 
 func (m queryMetricsStore) GetOrganizationsByUserID(ctx context.Context, userID database.GetOrganizationsByUserIDParams) ([]database.Organization, error) {
 	start := time.Now()
 	organizations, err := m.s.GetOrganizationsByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetOrganizationsByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetOrganizationsByUserID", start, len(organizations), err)
 	return organizations, err
 }
 
 func (m queryMetricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	start := time.Now()
 	schemas, err := m.s.GetParameterSchemasByJobID(ctx, jobID)
-	m.queryLatencies.WithLabelValues("GetParameterSchemasByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetParameterSchemasByJobID", start, len(schemas), err)
 	return schemas, err
 }
 
+func (m queryMetricsStore) GetPendingProvisionerJobsQueueDepthByTags(ctx context.Context) ([]database.GetPendingProvisionerJobsQueueDepthByTagsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPendingProvisionerJobsQueueDepthByTags(ctx)
+	m.observe(ctx, "GetPendingProvisionerJobsQueueDepthByTags", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetPendingUserActivityExports(ctx context.Context) ([]database.UserActivityExport, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPendingUserActivityExports(ctx)
+	m.observe(ctx, "GetPendingUserActivityExports", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetPrebuildClaimDemand(ctx context.Context, since time.Time) ([]database.GetPrebuildClaimDemandRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPrebuildClaimDemand(ctx, since)
+	m.observe(ctx, "GetPrebuildClaimDemand", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPrebuildMetrics(ctx)
-	m.queryLatencies.WithLabelValues("GetPrebuildMetrics").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPrebuildMetrics", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetPresetByID(ctx context.Context, presetID uuid.UUID) (database.GetPresetByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPresetByID(ctx, presetID)
-	m.queryLatencies.WithLabelValues("GetPresetByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPresetByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetPresetByWorkspaceBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.TemplateVersionPreset, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPresetByWorkspaceBuildID(ctx, workspaceBuildID)
-	m.queryLatencies.WithLabelValues("GetPresetByWorkspaceBuildID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPresetByWorkspaceBuildID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetPresetParametersByPresetID(ctx context.Context, presetID uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPresetParametersByPresetID(ctx, presetID)
-	m.queryLatencies.WithLabelValues("GetPresetParametersByPresetID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPresetParametersByPresetID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetPresetParametersByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPresetParametersByTemplateVersionID(ctx, templateVersionID)
-	m.queryLatencies.WithLabelValues("GetPresetParametersByTemplateVersionID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPresetParametersByTemplateVersionID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetPresetsAtFailureLimit(ctx context.Context, hardLimit int64) ([]database.GetPresetsAtFailureLimitRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPresetsAtFailureLimit(ctx, hardLimit)
-	m.queryLatencies.WithLabelValues("GetPresetsAtFailureLimit").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPresetsAtFailureLimit", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetPresetsBackoff(ctx context.Context, lookback time.Time) ([]database.GetPresetsBackoffRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPresetsBackoff(ctx, lookback)
-	m.queryLatencies.WithLabelValues("GetPresetsBackoff").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPresetsBackoff", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetPresetsByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPresetsByTemplateVersionID(ctx, templateVersionID)
-	m.queryLatencies.WithLabelValues("GetPresetsByTemplateVersionID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPresetsByTemplateVersionID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetPreviousTemplateVersion(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetPreviousTemplateVersion").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetPreviousTemplateVersion", start, 1, err)
 	return version, err
 }
 
+func (m queryMetricsStore) GetProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) (database.ProvisionerBuildPause, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerBuildPause(ctx, organizationID)
+	m.observe(ctx, "GetProvisionerBuildPause", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetProvisionerDaemons(ctx context.Context) ([]database.ProvisionerDaemon, error) {
 	start := time.Now()
 	daemons, err := m.s.GetProvisionerDaemons(ctx)
-	m.queryLatencies.WithLabelValues("GetProvisionerDaemons").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerDaemons", start, len(daemons), err)
 	return daemons, err
 }
 
 func (m queryMetricsStore) GetProvisionerDaemonsByOrganization(ctx context.Context, arg database.GetProvisionerDaemonsByOrganizationParams) ([]database.ProvisionerDaemon, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerDaemonsByOrganization(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetProvisionerDaemonsByOrganization").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerDaemonsByOrganization", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerDaemonsWithStatusByOrganization(ctx context.Context, arg database.GetProvisionerDaemonsWithStatusByOrganizationParams) ([]database.GetProvisionerDaemonsWithStatusByOrganizationRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerDaemonsWithStatusByOrganization(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetProvisionerDaemonsWithStatusByOrganization").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerDaemonsWithStatusByOrganization", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	start := time.Now()
 	job, err := m.s.GetProvisionerJobByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetProvisionerJobByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerJobByID", start, 1, err)
 	return job, err
 }
 
 func (m queryMetricsStore) GetProvisionerJobByIDForUpdate(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobByIDForUpdate(ctx, id)
-	m.queryLatencies.WithLabelValues("GetProvisionerJobByIDForUpdate").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerJobByIDForUpdate", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobLogArchiveByJobID(ctx, jobID)
+	m.observe(ctx, "GetProvisionerJobLogArchiveByJobID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetProvisionerJobThroughputByTags(ctx context.Context, arg database.GetProvisionerJobThroughputByTagsParams) ([]database.GetProvisionerJobThroughputByTagsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobThroughputByTags(ctx, arg)
+	m.observe(ctx, "GetProvisionerJobThroughputByTags", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobTimingsByJobID(ctx, jobID)
-	m.queryLatencies.WithLabelValues("GetProvisionerJobTimingsByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerJobTimingsByJobID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	start := time.Now()
 	jobs, err := m.s.GetProvisionerJobsByIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetProvisionerJobsByIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerJobsByIDs", start, len(jobs), err)
 	return jobs, err
 }
 
 func (m queryMetricsStore) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids database.GetProvisionerJobsByIDsWithQueuePositionParams) ([]database.GetProvisionerJobsByIDsWithQueuePositionRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobsByIDsWithQueuePosition(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetProvisionerJobsByIDsWithQueuePosition").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerJobsByIDsWithQueuePosition", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx context.Context, arg database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams) ([]database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.ProvisionerJob, error) {
 	start := time.Now()
 	jobs, err := m.s.GetProvisionerJobsCreatedAfter(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetProvisionerJobsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerJobsCreatedAfter", start, len(jobs), err)
 	return jobs, err
 }
 
+func (m queryMetricsStore) GetProvisionerJobsRunningBetween(ctx context.Context, arg database.GetProvisionerJobsRunningBetweenParams) ([]database.GetProvisionerJobsRunningBetweenRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobsRunningBetween(ctx, arg)
+	m.observe(ctx, "GetProvisionerJobsRunningBetween", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetProvisionerJobsToArchiveLogs(ctx context.Context, arg database.GetProvisionerJobsToArchiveLogsParams) ([]database.GetProvisionerJobsToArchiveLogsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobsToArchiveLogs(ctx, arg)
+	m.observe(ctx, "GetProvisionerJobsToArchiveLogs", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetProvisionerJobsToBeReaped(ctx context.Context, arg database.GetProvisionerJobsToBeReapedParams) ([]database.ProvisionerJob, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobsToBeReaped(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetProvisionerJobsToBeReaped").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerJobsToBeReaped", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerKeyByHashedSecret(ctx, hashedSecret)
-	m.queryLatencies.WithLabelValues("GetProvisionerKeyByHashedSecret").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerKeyByHashedSecret", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerKeyByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetProvisionerKeyByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerKeyByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerKeyByName(ctx context.Context, name database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerKeyByName(ctx, name)
-	m.queryLatencies.WithLabelValues("GetProvisionerKeyByName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerKeyByName", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	start := time.Now()
 	logs, err := m.s.GetProvisionerLogsAfterID(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetProvisionerLogsAfterID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetProvisionerLogsAfterID", start, len(logs), err)
 	return logs, err
 }
 
+func (m queryMetricsStore) GetProvisionerReservationByID(ctx context.Context, id uuid.UUID) (database.ProvisionerReservation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerReservationByID(ctx, id)
+	m.observe(ctx, "GetProvisionerReservationByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetProvisionerReservationsByOrganization(ctx context.Context, arg database.GetProvisionerReservationsByOrganizationParams) ([]database.ProvisionerReservation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerReservationsByOrganization(ctx, arg)
+	m.observe(ctx, "GetProvisionerReservationsByOrganization", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetQuotaAllowanceForUser(ctx context.Context, userID database.GetQuotaAllowanceForUserParams) (int64, error) {
 	start := time.Now()
 	allowance, err := m.s.GetQuotaAllowanceForUser(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetQuotaAllowanceForUser").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetQuotaAllowanceForUser", start, 1, err)
 	return allowance, err
 }
 
 func (m queryMetricsStore) GetQuotaConsumedForUser(ctx context.Context, ownerID database.GetQuotaConsumedForUserParams) (int64, error) {
 	start := time.Now()
 	consumed, err := m.s.GetQuotaConsumedForUser(ctx, ownerID)
-	m.queryLatencies.WithLabelValues("GetQuotaConsumedForUser").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetQuotaConsumedForUser", start, 1, err)
 	return consumed, err
 }
 
+func (m queryMetricsStore) GetQuotaMonthlyBudgetForUser(ctx context.Context, arg database.GetQuotaMonthlyBudgetForUserParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetQuotaMonthlyBudgetForUser(ctx, arg)
+	m.observe(ctx, "GetQuotaMonthlyBudgetForUser", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetQuotaMonthlySpendForUser(ctx context.Context, arg database.GetQuotaMonthlySpendForUserParams) (float64, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetQuotaMonthlySpendForUser(ctx, arg)
+	m.observe(ctx, "GetQuotaMonthlySpendForUser", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetReadOnlySettings(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetReadOnlySettings(ctx)
+	m.observe(ctx, "GetReadOnlySettings", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
 	start := time.Now()
 	replica, err := m.s.GetReplicaByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetReplicaByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetReplicaByID", start, 1, err)
 	return replica, err
 }
 
 func (m queryMetricsStore) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.Replica, error) {
 	start := time.Now()
 	replicas, err := m.s.GetReplicasUpdatedAfter(ctx, updatedAt)
-	m.queryLatencies.WithLabelValues("GetReplicasUpdatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetReplicasUpdatedAfter", start, len(replicas), err)
 	return replicas, err
 }
 
 func (m queryMetricsStore) GetRunningPrebuiltWorkspaces(ctx context.Context) ([]database.GetRunningPrebuiltWorkspacesRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetRunningPrebuiltWorkspaces(ctx)
-	m.queryLatencies.WithLabelValues("GetRunningPrebuiltWorkspaces").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetRunningPrebuiltWorkspaces", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx context.Context, now time.Time) ([]database.GetRunningWorkspaceBuildsExceedingDurationThresholdRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx, now)
+	m.observe(ctx, "GetRunningWorkspaceBuildsExceedingDurationThreshold", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetRuntimeConfig(ctx context.Context, key string) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetRuntimeConfig(ctx, key)
-	m.queryLatencies.WithLabelValues("GetRuntimeConfig").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetRuntimeConfig", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSensitiveTemplateVersionVariables(ctx context.Context) ([]database.TemplateVersionVariable, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSensitiveTemplateVersionVariables(ctx)
+	m.observe(ctx, "GetSensitiveTemplateVersionVariables", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetServiceAccountByUserID(ctx context.Context, userID uuid.UUID) (database.ServiceAccount, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetServiceAccountByUserID(ctx, userID)
+	m.observe(ctx, "GetServiceAccountByUserID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetServiceAccountsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.ServiceAccount, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetServiceAccountsByOrganizationID(ctx, organizationID)
+	m.observe(ctx, "GetServiceAccountsByOrganizationID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetStartableWorkspaceBuildQueueEntries(ctx context.Context) ([]database.WorkspaceBuildQueue, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetStartableWorkspaceBuildQueueEntries(ctx)
+	m.observe(ctx, "GetStartableWorkspaceBuildQueueEntries", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetAgents(ctx, id)
-	m.queryLatencies.WithLabelValues("GetTailnetAgents").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTailnetAgents", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]database.TailnetClient, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetClientsForAgent(ctx, agentID)
-	m.queryLatencies.WithLabelValues("GetTailnetClientsForAgent").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTailnetClientsForAgent", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]database.TailnetPeer, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetPeers(ctx, id)
-	m.queryLatencies.WithLabelValues("GetTailnetPeers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTailnetPeers", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTailnetTunnelPeerBindings(ctx context.Context, srcID uuid.UUID) ([]database.GetTailnetTunnelPeerBindingsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetTunnelPeerBindings(ctx, srcID)
-	m.queryLatencies.WithLabelValues("GetTailnetTunnelPeerBindings").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTailnetTunnelPeerBindings", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UUID) ([]database.GetTailnetTunnelPeerIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetTunnelPeerIDs(ctx, srcID)
-	m.queryLatencies.WithLabelValues("GetTailnetTunnelPeerIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTailnetTunnelPeerIDs", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTelemetryItem(ctx context.Context, key string) (database.TelemetryItem, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTelemetryItem(ctx, key)
-	m.queryLatencies.WithLabelValues("GetTelemetryItem").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTelemetryItem", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTelemetryItems(ctx context.Context) ([]database.TelemetryItem, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTelemetryItems(ctx)
-	m.queryLatencies.WithLabelValues("GetTelemetryItems").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTelemetryItems", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsights(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateAppInsights").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateAppInsights", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateAppInsightsByTemplate(ctx context.Context, arg database.GetTemplateAppInsightsByTemplateParams) ([]database.GetTemplateAppInsightsByTemplateRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsightsByTemplate(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateAppInsightsByTemplate").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateAppInsightsByTemplate", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateAverageBuildTime(ctx context.Context, arg database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
 	start := time.Now()
 	buildTime, err := m.s.GetTemplateAverageBuildTime(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateAverageBuildTime").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateAverageBuildTime", start, 1, err)
 	return buildTime, err
 }
 
+func (m queryMetricsStore) GetTemplateBuildDurationStats(ctx context.Context, arg database.GetTemplateBuildDurationStatsParams) ([]database.TemplateBuildDurationStat, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateBuildDurationStats(ctx, arg)
+	m.observe(ctx, "GetTemplateBuildDurationStats", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	start := time.Now()
 	template, err := m.s.GetTemplateByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetTemplateByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateByID", start, 1, err)
 	return template, err
 }
 
+func (m queryMetricsStore) GetTemplateByIDForUpdate(ctx context.Context, id uuid.UUID) (database.TemplateTable, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateByIDForUpdate(ctx, id)
+	m.observe(ctx, "GetTemplateByIDForUpdate", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateByOrganizationAndName(ctx context.Context, arg database.GetTemplateByOrganizationAndNameParams) (database.Template, error) {
 	start := time.Now()
 	template, err := m.s.GetTemplateByOrganizationAndName(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateByOrganizationAndName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateByOrganizationAndName", start, 1, err)
 	return template, err
 }
 
 func (m queryMetricsStore) GetTemplateDAUs(ctx context.Context, arg database.GetTemplateDAUsParams) ([]database.GetTemplateDAUsRow, error) {
 	start := time.Now()
 	daus, err := m.s.GetTemplateDAUs(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateDAUs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateDAUs", start, len(daus), err)
 	return daus, err
 }
 
 func (m queryMetricsStore) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsights(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateInsights").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateInsights", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateInsightsByInterval(ctx context.Context, arg database.GetTemplateInsightsByIntervalParams) ([]database.GetTemplateInsightsByIntervalRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsightsByInterval(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateInsightsByInterval").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateInsightsByInterval", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateInsightsByTemplate(ctx context.Context, arg database.GetTemplateInsightsByTemplateParams) ([]database.GetTemplateInsightsByTemplateRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsightsByTemplate(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateInsightsByTemplate").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateInsightsByTemplate", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateParameterInsights(ctx context.Context, arg database.GetTemplateParameterInsightsParams) ([]database.GetTemplateParameterInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateParameterInsights(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateParameterInsights").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateParameterInsights", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplatePresetByID(ctx context.Context, id uuid.UUID) (database.TemplatePreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplatePresetByID(ctx, id)
+	m.observe(ctx, "GetTemplatePresetByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplatePresetGroupDefaultsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePresetGroupDefault, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplatePresetGroupDefaultsByTemplateID(ctx, templateID)
+	m.observe(ctx, "GetTemplatePresetGroupDefaultsByTemplateID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplatePresetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplatePresetsByTemplateID(ctx, templateID)
+	m.observe(ctx, "GetTemplatePresetsByTemplateID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplatePresetsWithPrebuilds(ctx, templateID)
-	m.queryLatencies.WithLabelValues("GetTemplatePresetsWithPrebuilds").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplatePresetsWithPrebuilds", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateReleaseChannel(ctx context.Context, arg database.GetTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateReleaseChannel(ctx, arg)
+	m.observe(ctx, "GetTemplateReleaseChannel", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateReleaseChannelsByTemplateID(ctx, templateID)
+	m.observe(ctx, "GetTemplateReleaseChannelsByTemplateID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateSharesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateShare, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateSharesByTemplateID(ctx, templateID)
+	m.observe(ctx, "GetTemplateSharesByTemplateID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateUsageStats(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateUsageStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateUsageStats", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetTemplateVersionByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionByID", start, 1, err)
 	return version, err
 }
 
 func (m queryMetricsStore) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetTemplateVersionByJobID(ctx, jobID)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionByJobID", start, 1, err)
 	return version, err
 }
 
 func (m queryMetricsStore) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg database.GetTemplateVersionByTemplateIDAndNameParams) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetTemplateVersionByTemplateIDAndName(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionByTemplateIDAndName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionByTemplateIDAndName", start, 1, err)
 	return version, err
 }
 
 func (m queryMetricsStore) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionParameter, error) {
 	start := time.Now()
 	parameters, err := m.s.GetTemplateVersionParameters(ctx, templateVersionID)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionParameters").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionParameters", start, len(parameters), err)
 	return parameters, err
 }
 
+func (m queryMetricsStore) GetTemplateVersionRolloutBuildCounts(ctx context.Context, arg database.GetTemplateVersionRolloutBuildCountsParams) ([]database.GetTemplateVersionRolloutBuildCountsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionRolloutBuildCounts(ctx, arg)
+	m.observe(ctx, "GetTemplateVersionRolloutBuildCounts", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionRolloutByTemplateID(ctx, templateID)
+	m.observe(ctx, "GetTemplateVersionRolloutByTemplateID", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateVersionTerraformValues(ctx, templateVersionID)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionTerraformValues").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionTerraformValues", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variables, err := m.s.GetTemplateVersionVariables(ctx, templateVersionID)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionVariables").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionVariables", start, len(variables), err)
 	return variables, err
 }
 
 func (m queryMetricsStore) GetTemplateVersionWorkspaceTags(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionWorkspaceTag, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateVersionWorkspaceTags(ctx, templateVersionID)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionWorkspaceTags").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionWorkspaceTags", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.TemplateVersion, error) {
 	start := time.Now()
 	versions, err := m.s.GetTemplateVersionsByIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionsByIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionsByIDs", start, len(versions), err)
 	return versions, err
 }
 
 func (m queryMetricsStore) GetTemplateVersionsByTemplateID(ctx context.Context, arg database.GetTemplateVersionsByTemplateIDParams) ([]database.TemplateVersion, error) {
 	start := time.Now()
 	versions, err := m.s.GetTemplateVersionsByTemplateID(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionsByTemplateID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionsByTemplateID", start, len(versions), err)
 	return versions, err
 }
 
 func (m queryMetricsStore) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.TemplateVersion, error) {
 	start := time.Now()
 	versions, err := m.s.GetTemplateVersionsCreatedAfter(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateVersionsCreatedAfter", start, len(versions), err)
 	return versions, err
 }
 
+func (m queryMetricsStore) GetTemplateWorkspaceNamingPolicy(ctx context.Context, templateID uuid.UUID) (database.WorkspaceNamingPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateWorkspaceNamingPolicy(ctx, templateID)
+	m.observe(ctx, "GetTemplateWorkspaceNamingPolicy", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplates(ctx context.Context) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetTemplates(ctx)
-	m.queryLatencies.WithLabelValues("GetTemplates").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplates", start, len(templates), err)
 	return templates, err
 }
 
+func (m queryMetricsStore) GetTemplatesSharedWithOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.Template, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplatesSharedWithOrganization(ctx, organizationID)
+	m.observe(ctx, "GetTemplatesSharedWithOrganization", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplatesWithFilter(ctx context.Context, arg database.GetTemplatesWithFilterParams) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetTemplatesWithFilter(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplatesWithFilter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplatesWithFilter", start, len(templates), err)
 	return templates, err
 }
 
 func (m queryMetricsStore) GetUnexpiredLicenses(ctx context.Context) ([]database.License, error) {
 	start := time.Now()
 	licenses, err := m.s.GetUnexpiredLicenses(ctx)
-	m.queryLatencies.WithLabelValues("GetUnexpiredLicenses").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUnexpiredLicenses", start, len(licenses), err)
 	return licenses, err
 }
 
+func (m queryMetricsStore) GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentHealthEvent, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx, ids)
+	m.observe(ctx, "GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserActivityExportByID(ctx context.Context, id uuid.UUID) (database.UserActivityExport, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserActivityExportByID(ctx, id)
+	m.observe(ctx, "GetUserActivityExportByID", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserActivityInsights(ctx context.Context, arg database.GetUserActivityInsightsParams) ([]database.GetUserActivityInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserActivityInsights(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetUserActivityInsights").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserActivityInsights", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetUserByEmailOrUsername(ctx context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.GetUserByEmailOrUsername(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetUserByEmailOrUsername").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserByEmailOrUsername", start, 1, err)
 	return user, err
 }
 
 func (m queryMetricsStore) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.GetUserByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetUserByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserByID", start, 1, err)
 	return user, err
 }
 
 func (m queryMetricsStore) GetUserCount(ctx context.Context, includeSystem bool) (int64, error) {
 	start := time.Now()
 	count, err := m.s.GetUserCount(ctx, includeSystem)
-	m.queryLatencies.WithLabelValues("GetUserCount").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserCount", start, 1, err)
 	return count, err
 }
 
+func (m queryMetricsStore) GetUserDailyActivity(ctx context.Context, arg database.GetUserDailyActivityParams) ([]database.GetUserDailyActivityRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserDailyActivity(ctx, arg)
+	m.observe(ctx, "GetUserDailyActivity", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserLatencyInsights(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetUserLatencyInsights").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserLatencyInsights", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (database.UserLink, error) {
 	start := time.Now()
 	link, err := m.s.GetUserLinkByLinkedID(ctx, linkedID)
-	m.queryLatencies.WithLabelValues("GetUserLinkByLinkedID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserLinkByLinkedID", start, 1, err)
 	return link, err
 }
 
 func (m queryMetricsStore) GetUserLinkByUserIDLoginType(ctx context.Context, arg database.GetUserLinkByUserIDLoginTypeParams) (database.UserLink, error) {
 	start := time.Now()
 	link, err := m.s.GetUserLinkByUserIDLoginType(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetUserLinkByUserIDLoginType").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserLinkByUserIDLoginType", start, 1, err)
 	return link, err
 }
 
 func (m queryMetricsStore) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserLink, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserLinksByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetUserLinksByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserLinksByUserID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserNotificationBuildDurationThreshold(ctx context.Context, userID uuid.UUID) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserNotificationBuildDurationThreshold(ctx, userID)
+	m.observe(ctx, "GetUserNotificationBuildDurationThreshold", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserNotificationDigestFrequency(ctx context.Context, userID uuid.UUID) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserNotificationDigestFrequency(ctx, userID)
+	m.observe(ctx, "GetUserNotificationDigestFrequency", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserNotificationPreferences(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetUserNotificationPreferences").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserNotificationPreferences", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetUserStatusCounts(ctx context.Context, arg database.GetUserStatusCountsParams) ([]database.GetUserStatusCountsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserStatusCounts(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetUserStatusCounts").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserStatusCounts", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetUserTerminalFont(ctx context.Context, userID uuid.UUID) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserTerminalFont(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetUserTerminalFont").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserTerminalFont", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetUserThemePreference(ctx context.Context, userID uuid.UUID) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserThemePreference(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetUserThemePreference").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserThemePreference", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetUserWorkspaceBuildParameters(ctx context.Context, ownerID database.GetUserWorkspaceBuildParametersParams) ([]database.GetUserWorkspaceBuildParametersRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserWorkspaceBuildParameters(ctx, ownerID)
-	m.queryLatencies.WithLabelValues("GetUserWorkspaceBuildParameters").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUserWorkspaceBuildParameters", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	start := time.Now()
 	users, err := m.s.GetUsers(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetUsers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUsers", start, len(users), err)
 	return users, err
 }
 
 func (m queryMetricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]database.User, error) {
 	start := time.Now()
 	users, err := m.s.GetUsersByIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetUsersByIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetUsersByIDs", start, len(users), err)
 	return users, err
 }
 
+func (m queryMetricsStore) GetUsersWithDueNotificationDigests(ctx context.Context, now time.Time) ([]database.GetUsersWithDueNotificationDigestsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUsersWithDueNotificationDigests(ctx, now)
+	m.observe(ctx, "GetUsersWithDueNotificationDigests", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWebpushSubscriptionsByUserID(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetWebpushSubscriptionsByUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWebpushSubscriptionsByUserID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWebpushVAPIDKeys(ctx context.Context) (database.GetWebpushVAPIDKeysRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWebpushVAPIDKeys(ctx)
-	m.queryLatencies.WithLabelValues("GetWebpushVAPIDKeys").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWebpushVAPIDKeys", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentAndLatestBuildByAuthToken(ctx, authToken)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentAndLatestBuildByAuthToken").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentAndLatestBuildByAuthToken", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
 	start := time.Now()
 	agent, err := m.s.GetWorkspaceAgentByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentByID", start, 1, err)
 	return agent, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (database.WorkspaceAgent, error) {
 	start := time.Now()
 	agent, err := m.s.GetWorkspaceAgentByInstanceID(ctx, authInstanceID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentByInstanceID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentByInstanceID", start, 1, err)
 	return agent, err
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentConnectionQualityByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentConnectionQuality, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentConnectionQualityByAgentID(ctx, agentID)
+	m.observe(ctx, "GetWorkspaceAgentConnectionQualityByAgentID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentConnectionTypeCounts(ctx context.Context, updatedAfter time.Time) ([]database.GetWorkspaceAgentConnectionTypeCountsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentConnectionTypeCounts(ctx, updatedAfter)
+	m.observe(ctx, "GetWorkspaceAgentConnectionTypeCounts", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentDevcontainersByAgentID(ctx, workspaceAgentID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentDevcontainersByAgentID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentDevcontainersByAgentID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLifecycleStateByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLifecycleStateByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentLifecycleStateByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLogSourcesByAgentIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentLogSourcesByAgentIDs", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentLogsAfterParams) ([]database.WorkspaceAgentLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLogsAfter(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLogsAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentLogsAfter", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentID database.GetWorkspaceAgentMetadataParams) ([]database.WorkspaceAgentMetadatum, error) {
 	start := time.Now()
 	metadata, err := m.s.GetWorkspaceAgentMetadata(ctx, workspaceAgentID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentMetadata").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentMetadata", start, len(metadata), err)
 	return metadata, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentPortShare(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentPortShare", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentResourceUsageByWorkspaceID(ctx context.Context, arg database.GetWorkspaceAgentResourceUsageByWorkspaceIDParams) ([]database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentResourceUsageByWorkspaceID(ctx, arg)
+	m.observe(ctx, "GetWorkspaceAgentResourceUsageByWorkspaceID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentScriptTimingsByBuildID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentScriptTimingsByBuildID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentScriptTimingsByBuildID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentScriptsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentScriptsByAgentIDs", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	start := time.Now()
 	stats, err := m.s.GetWorkspaceAgentStats(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentStats", start, len(stats), err)
 	return stats, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsAndLabelsRow, error) {
 	start := time.Now()
 	stats, err := m.s.GetWorkspaceAgentStatsAndLabels(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentStatsAndLabels").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentStatsAndLabels", start, len(stats), err)
 	return stats, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentUsageStatsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentUsageStats(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentUsageStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentUsageStats", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentUsageStatsAndLabels(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentUsageStatsAndLabelsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentUsageStatsAndLabels(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentUsageStatsAndLabels").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentUsageStatsAndLabels", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentsByParentID(ctx context.Context, dollar_1 uuid.UUID) ([]database.WorkspaceAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentsByParentID(ctx, dollar_1)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsByParentID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentsByParentID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgent, error) {
 	start := time.Now()
 	agents, err := m.s.GetWorkspaceAgentsByResourceIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsByResourceIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentsByResourceIDs", start, len(agents), err)
 	return agents, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentsByWorkspaceAndBuildNumber(ctx context.Context, arg database.GetWorkspaceAgentsByWorkspaceAndBuildNumberParams) ([]database.WorkspaceAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentsByWorkspaceAndBuildNumber(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsByWorkspaceAndBuildNumber").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentsByWorkspaceAndBuildNumber", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceAgent, error) {
 	start := time.Now()
 	agents, err := m.s.GetWorkspaceAgentsCreatedAfter(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentsCreatedAfter", start, len(agents), err)
 	return agents, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgent, error) {
 	start := time.Now()
 	agents, err := m.s.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspaceID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsInLatestBuildByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAgentsInLatestBuildByWorkspaceID", start, len(agents), err)
 	return agents, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg database.GetWorkspaceAppByAgentIDAndSlugParams) (database.WorkspaceApp, error) {
 	start := time.Now()
 	app, err := m.s.GetWorkspaceAppByAgentIDAndSlug(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAppByAgentIDAndSlug").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAppByAgentIDAndSlug", start, 1, err)
 	return app, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAppStatusesByAppIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAppStatus, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAppStatusesByAppIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAppStatusesByAppIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAppStatusesByAppIDs", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceApp, error) {
 	start := time.Now()
 	apps, err := m.s.GetWorkspaceAppsByAgentID(ctx, agentID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAppsByAgentID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAppsByAgentID", start, len(apps), err)
 	return apps, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceApp, error) {
 	start := time.Now()
 	apps, err := m.s.GetWorkspaceAppsByAgentIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAppsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAppsByAgentIDs", start, len(apps), err)
 	return apps, err
 }
 
 func (m queryMetricsStore) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceApp, error) {
 	start := time.Now()
 	apps, err := m.s.GetWorkspaceAppsCreatedAfter(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceAppsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceAppsCreatedAfter", start, len(apps), err)
 	return apps, err
 }
 
+func (m queryMetricsStore) GetWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildArchiveByBuildID(ctx, workspaceBuildID)
+	m.observe(ctx, "GetWorkspaceBuildArchiveByBuildID", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetWorkspaceBuildByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetWorkspaceBuildByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceBuildByID", start, 1, err)
 	return build, err
 }
 
 func (m queryMetricsStore) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetWorkspaceBuildByJobID(ctx, jobID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceBuildByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceBuildByJobID", start, 1, err)
 	return build, err
 }
 
 func (m queryMetricsStore) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaceBuildByWorkspaceIDAndBuildNumber").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceBuildByWorkspaceIDAndBuildNumber", start, 1, err)
 	return build, err
 }
 
+func (m queryMetricsStore) GetWorkspaceBuildIdempotencyKey(ctx context.Context, arg database.GetWorkspaceBuildIdempotencyKeyParams) (database.WorkspaceBuildIdempotencyKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildIdempotencyKey(ctx, arg)
+	m.observe(ctx, "GetWorkspaceBuildIdempotencyKey", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceBuildInterimStateByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildInterimState, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildInterimStateByBuildID(ctx, workspaceBuildID)
+	m.observe(ctx, "GetWorkspaceBuildInterimStateByBuildID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceBuildOutcomesCompletedBetween(ctx context.Context, arg database.GetWorkspaceBuildOutcomesCompletedBetweenParams) ([]database.GetWorkspaceBuildOutcomesCompletedBetweenRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildOutcomesCompletedBetween(ctx, arg)
+	m.observe(ctx, "GetWorkspaceBuildOutcomesCompletedBetween", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	start := time.Now()
 	params, err := m.s.GetWorkspaceBuildParameters(ctx, workspaceBuildID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceBuildParameters").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceBuildParameters", start, len(params), err)
 	return params, err
 }
 
 func (m queryMetricsStore) GetWorkspaceBuildParametersByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceBuildParametersByBuildIDs(ctx, workspaceBuildIds)
-	m.queryLatencies.WithLabelValues("GetWorkspaceBuildParametersByBuildIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceBuildParametersByBuildIDs", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceBuildQueueByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceBuildQueue, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildQueueByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetWorkspaceBuildQueueByWorkspaceID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuildQueue, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildQueueEntryByID(ctx, id)
+	m.observe(ctx, "GetWorkspaceBuildQueueEntryByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]database.GetWorkspaceBuildStatsByTemplatesRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceBuildStatsByTemplates(ctx, since)
-	m.queryLatencies.WithLabelValues("GetWorkspaceBuildStatsByTemplates").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceBuildStatsByTemplates", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
 	start := time.Now()
 	builds, err := m.s.GetWorkspaceBuildsByWorkspaceID(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaceBuildsByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceBuildsByWorkspaceID", start, len(builds), err)
 	return builds, err
 }
 
 func (m queryMetricsStore) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceBuild, error) {
 	start := time.Now()
 	builds, err := m.s.GetWorkspaceBuildsCreatedAfter(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceBuildsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceBuildsCreatedAfter", start, len(builds), err)
 	return builds, err
 }
 
+func (m queryMetricsStore) GetWorkspaceBuildsToArchive(ctx context.Context, arg database.GetWorkspaceBuildsToArchiveParams) ([]database.GetWorkspaceBuildsToArchiveRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildsToArchive(ctx, arg)
+	m.observe(ctx, "GetWorkspaceBuildsToArchive", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByAgentID(ctx, agentID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceByAgentID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceByAgentID", start, 1, err)
 	return workspace, err
 }
 
 func (m queryMetricsStore) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetWorkspaceByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceByID", start, 1, err)
 	return workspace, err
 }
 
+func (m queryMetricsStore) GetWorkspaceByIDForUpdate(ctx context.Context, id uuid.UUID) (database.WorkspaceTable, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceByIDForUpdate(ctx, id)
+	m.observe(ctx, "GetWorkspaceByIDForUpdate", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceByOrganizationIDAndName(ctx context.Context, arg database.GetWorkspaceByOrganizationIDAndNameParams) (database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceByOrganizationIDAndName(ctx, arg)
+	m.observe(ctx, "GetWorkspaceByOrganizationIDAndName", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg database.GetWorkspaceByOwnerIDAndNameParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByOwnerIDAndName(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaceByOwnerIDAndName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceByOwnerIDAndName", start, 1, err)
 	return workspace, err
 }
 
 func (m queryMetricsStore) GetWorkspaceByResourceID(ctx context.Context, resourceID uuid.UUID) (database.Workspace, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceByResourceID(ctx, resourceID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceByResourceID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceByResourceID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByWorkspaceAppID(ctx, workspaceAppID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceByWorkspaceAppID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceByWorkspaceAppID", start, 1, err)
 	return workspace, err
 }
 
+func (m queryMetricsStore) GetWorkspaceDailyCosts(ctx context.Context, arg database.GetWorkspaceDailyCostsParams) ([]database.GetWorkspaceDailyCostsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceDailyCosts(ctx, arg)
+	m.observe(ctx, "GetWorkspaceDailyCosts", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceDriftCheckCandidates(ctx context.Context, arg database.GetWorkspaceDriftCheckCandidatesParams) ([]database.GetWorkspaceDriftCheckCandidatesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceDriftCheckCandidates(ctx, arg)
+	m.observe(ctx, "GetWorkspaceDriftCheckCandidates", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceDriftChecksByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetWorkspaceDriftChecksByWorkspaceIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceDriftChecksByWorkspaceIDs(ctx, ids)
+	m.observe(ctx, "GetWorkspaceDriftChecksByWorkspaceIDs", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceLabel, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceLabelsByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetWorkspaceLabelsByWorkspaceID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceLocksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceLock, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceLocksByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetWorkspaceLocksByWorkspaceID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceMigrationByID(ctx context.Context, id uuid.UUID) (database.WorkspaceMigration, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceMigrationByID(ctx, id)
+	m.observe(ctx, "GetWorkspaceMigrationByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceMigrationsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceMigration, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceMigrationsByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetWorkspaceMigrationsByWorkspaceID", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceModulesByJobID(ctx, jobID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceModulesByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceModulesByJobID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceModulesCreatedAfter(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceModulesCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceModulesCreatedAfter", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspacePrebuildReservation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePrebuildReservationByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetWorkspacePrebuildReservationByWorkspaceID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspacePrebuildReservationsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspacePrebuildReservation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePrebuildReservationsByTemplateID(ctx, templateID)
+	m.observe(ctx, "GetWorkspacePrebuildReservationsByTemplateID", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceProvisionerAffinity(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceProvisionerAffinity, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceProvisionerAffinity(ctx, workspaceID)
+	m.observe(ctx, "GetWorkspaceProvisionerAffinity", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxies, err := m.s.GetWorkspaceProxies(ctx)
-	m.queryLatencies.WithLabelValues("GetWorkspaceProxies").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceProxies", start, len(proxies), err)
 	return proxies, err
 }
 
 func (m queryMetricsStore) GetWorkspaceProxyByHostname(ctx context.Context, arg database.GetWorkspaceProxyByHostnameParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.GetWorkspaceProxyByHostname(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaceProxyByHostname").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceProxyByHostname", start, 1, err)
 	return proxy, err
 }
 
 func (m queryMetricsStore) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.GetWorkspaceProxyByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetWorkspaceProxyByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceProxyByID", start, 1, err)
 	return proxy, err
 }
 
 func (m queryMetricsStore) GetWorkspaceProxyByName(ctx context.Context, name string) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.GetWorkspaceProxyByName(ctx, name)
-	m.queryLatencies.WithLabelValues("GetWorkspaceProxyByName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceProxyByName", start, 1, err)
 	return proxy, err
 }
 
+func (m queryMetricsStore) GetWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceReleaseChannelByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetWorkspaceReleaseChannelByWorkspaceID", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	start := time.Now()
 	resource, err := m.s.GetWorkspaceResourceByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetWorkspaceResourceByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceResourceByID", start, 1, err)
 	return resource, err
 }
 
 func (m queryMetricsStore) GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceResourceMetadatum, error) {
 	start := time.Now()
 	metadata, err := m.s.GetWorkspaceResourceMetadataByResourceIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetWorkspaceResourceMetadataByResourceIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceResourceMetadataByResourceIDs", start, len(metadata), err)
 	return metadata, err
 }
 
 func (m queryMetricsStore) GetWorkspaceResourceMetadataCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceResourceMetadatum, error) {
 	start := time.Now()
 	metadata, err := m.s.GetWorkspaceResourceMetadataCreatedAfter(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceResourceMetadataCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceResourceMetadataCreatedAfter", start, len(metadata), err)
 	return metadata, err
 }
 
 func (m queryMetricsStore) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceResource, error) {
 	start := time.Now()
 	resources, err := m.s.GetWorkspaceResourcesByJobID(ctx, jobID)
-	m.queryLatencies.WithLabelValues("GetWorkspaceResourcesByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceResourcesByJobID", start, len(resources), err)
 	return resources, err
 }
 
 func (m queryMetricsStore) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceResource, error) {
 	start := time.Now()
 	resources, err := m.s.GetWorkspaceResourcesByJobIDs(ctx, ids)
-	m.queryLatencies.WithLabelValues("GetWorkspaceResourcesByJobIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceResourcesByJobIDs", start, len(resources), err)
 	return resources, err
 }
 
-func (m queryMetricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceResource, error) {
+func (m queryMetricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceResource, error) {
+	start := time.Now()
+	resources, err := m.s.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
+	m.observe(ctx, "GetWorkspaceResourcesCreatedAfter", start, len(resources), err)
+	return resources, err
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingByID(ctx, id)
+	m.observe(ctx, "GetWorkspaceSessionRecordingByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingChunks(ctx, recordingID)
+	m.observe(ctx, "GetWorkspaceSessionRecordingChunks", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
 	start := time.Now()
-	resources, err := m.s.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
-	m.queryLatencies.WithLabelValues("GetWorkspaceResourcesCreatedAfter").Observe(time.Since(start).Seconds())
-	return resources, err
+	r0, r1 := m.s.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspaceID)
+	m.observe(ctx, "GetWorkspaceSessionRecordingsByWorkspaceID", start, len(r0), r1)
+	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
-	m.queryLatencies.WithLabelValues("GetWorkspaceUniqueOwnerCountByTemplateIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaceUniqueOwnerCountByTemplateIDs", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	start := time.Now()
 	workspaces, err := m.s.GetWorkspaces(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetWorkspaces").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspaces", start, len(workspaces), err)
 	return workspaces, err
 }
 
 func (m queryMetricsStore) GetWorkspacesAndAgentsByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.GetWorkspacesAndAgentsByOwnerIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspacesAndAgentsByOwnerID(ctx, ownerID)
-	m.queryLatencies.WithLabelValues("GetWorkspacesAndAgentsByOwnerID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspacesAndAgentsByOwnerID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspaceTable, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspacesByTemplateID(ctx, templateID)
-	m.queryLatencies.WithLabelValues("GetWorkspacesByTemplateID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspacesByTemplateID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.GetWorkspacesEligibleForTransitionRow, error) {
 	start := time.Now()
 	workspaces, err := m.s.GetWorkspacesEligibleForTransition(ctx, now)
-	m.queryLatencies.WithLabelValues("GetWorkspacesEligibleForAutoStartStop").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetWorkspacesEligibleForAutoStartStop", start, len(workspaces), err)
 	return workspaces, err
 }
 
 func (m queryMetricsStore) HasTemplateVersionsWithAITask(ctx context.Context) (bool, error) {
 	start := time.Now()
 	r0, r1 := m.s.HasTemplateVersionsWithAITask(ctx)
-	m.queryLatencies.WithLabelValues("HasTemplateVersionsWithAITask").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "HasTemplateVersionsWithAITask", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	start := time.Now()
 	key, err := m.s.InsertAPIKey(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertAPIKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertAPIKey", start, 1, err)
 	return key, err
 }
 
+func (m queryMetricsStore) InsertAccessRequest(ctx context.Context, arg database.InsertAccessRequestParams) (database.AccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertAccessRequest(ctx, arg)
+	m.observe(ctx, "InsertAccessRequest", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (database.Group, error) {
 	start := time.Now()
 	group, err := m.s.InsertAllUsersGroup(ctx, organizationID)
-	m.queryLatencies.WithLabelValues("InsertAllUsersGroup").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertAllUsersGroup", start, 1, err)
 	return group, err
 }
 
 func (m queryMetricsStore) InsertAuditLog(ctx context.Context, arg database.InsertAuditLogParams) (database.AuditLog, error) {
 	start := time.Now()
 	log, err := m.s.InsertAuditLog(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertAuditLog").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertAuditLog", start, 1, err)
 	return log, err
 }
 
+func (m queryMetricsStore) InsertBuildAlertRule(ctx context.Context, arg database.InsertBuildAlertRuleParams) (database.BuildAlertRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertBuildAlertRule(ctx, arg)
+	m.observe(ctx, "InsertBuildAlertRule", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertCryptoKey(ctx context.Context, arg database.InsertCryptoKeyParams) (database.CryptoKey, error) {
 	start := time.Now()
 	key, err := m.s.InsertCryptoKey(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertCryptoKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertCryptoKey", start, 1, err)
 	return key, err
 }
 
 func (m queryMetricsStore) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertCustomRole(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertCustomRole").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertCustomRole", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertDBCryptKey(ctx context.Context, arg database.InsertDBCryptKeyParams) error {
 	start := time.Now()
 	r0 := m.s.InsertDBCryptKey(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertDBCryptKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertDBCryptKey", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) InsertDERPMeshKey(ctx context.Context, value string) error {
 	start := time.Now()
 	err := m.s.InsertDERPMeshKey(ctx, value)
-	m.queryLatencies.WithLabelValues("InsertDERPMeshKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertDERPMeshKey", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) InsertDeploymentID(ctx context.Context, value string) error {
 	start := time.Now()
 	err := m.s.InsertDeploymentID(ctx, value)
-	m.queryLatencies.WithLabelValues("InsertDeploymentID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertDeploymentID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) InsertExternalAuthLink(ctx context.Context, arg database.InsertExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.InsertExternalAuthLink(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertExternalAuthLink").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertExternalAuthLink", start, 1, err)
 	return link, err
 }
 
 func (m queryMetricsStore) InsertFile(ctx context.Context, arg database.InsertFileParams) (database.File, error) {
 	start := time.Now()
 	file, err := m.s.InsertFile(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertFile").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertFile", start, 1, err)
 	return file, err
 }
 
 func (m queryMetricsStore) InsertGitSSHKey(ctx context.Context, arg database.InsertGitSSHKeyParams) (database.GitSSHKey, error) {
 	start := time.Now()
 	key, err := m.s.InsertGitSSHKey(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertGitSSHKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertGitSSHKey", start, 1, err)
 	return key, err
 }
 
 func (m queryMetricsStore) InsertGroup(ctx context.Context, arg database.InsertGroupParams) (database.Group, error) {
 	start := time.Now()
 	group, err := m.s.InsertGroup(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertGroup").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertGroup", start, 1, err)
 	return group, err
 }
 
 func (m queryMetricsStore) InsertGroupMember(ctx context.Context, arg database.InsertGroupMemberParams) error {
 	start := time.Now()
 	err := m.s.InsertGroupMember(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertGroupMember").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertGroupMember", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertInboxNotification(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertInboxNotification").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertInboxNotification", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertLicense(ctx context.Context, arg database.InsertLicenseParams) (database.License, error) {
 	start := time.Now()
 	license, err := m.s.InsertLicense(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertLicense").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertLicense", start, 1, err)
 	return license, err
 }
 
+func (m queryMetricsStore) InsertLoginDeviceCode(ctx context.Context, arg database.InsertLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertLoginDeviceCode(ctx, arg)
+	m.observe(ctx, "InsertLoginDeviceCode", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertMemoryResourceMonitor(ctx context.Context, arg database.InsertMemoryResourceMonitorParams) (database.WorkspaceAgentMemoryResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertMemoryResourceMonitor(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertMemoryResourceMonitor").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertMemoryResourceMonitor", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertMissingGroups(ctx context.Context, arg database.InsertMissingGroupsParams) ([]database.Group, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertMissingGroups(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertMissingGroups").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertMissingGroups", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderApp(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderApp").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertOAuth2ProviderApp", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderAppCode(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppCode").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertOAuth2ProviderAppCode", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderAppSecret(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppSecret").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertOAuth2ProviderAppSecret", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderAppToken(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppToken").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertOAuth2ProviderAppToken", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertOrganization").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertOrganization", start, 1, err)
 	return organization, err
 }
 
 func (m queryMetricsStore) InsertOrganizationMember(ctx context.Context, arg database.InsertOrganizationMemberParams) (database.OrganizationMember, error) {
 	start := time.Now()
 	member, err := m.s.InsertOrganizationMember(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertOrganizationMember").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertOrganizationMember", start, 1, err)
 	return member, err
 }
 
 func (m queryMetricsStore) InsertPreset(ctx context.Context, arg database.InsertPresetParams) (database.TemplateVersionPreset, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertPreset(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertPreset").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertPreset", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertPresetParameters(ctx context.Context, arg database.InsertPresetParametersParams) ([]database.TemplateVersionPresetParameter, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertPresetParameters(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertPresetParameters").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertPresetParameters", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertPresetPrebuildSchedule(ctx context.Context, arg database.InsertPresetPrebuildScheduleParams) (database.TemplateVersionPresetPrebuildSchedule, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertPresetPrebuildSchedule(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertPresetPrebuildSchedule").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertPresetPrebuildSchedule", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertProvisionerBuildPause(ctx context.Context, arg database.InsertProvisionerBuildPauseParams) (database.ProvisionerBuildPause, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertProvisionerBuildPause(ctx, arg)
+	m.observe(ctx, "InsertProvisionerBuildPause", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertProvisionerJob(ctx context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	start := time.Now()
 	job, err := m.s.InsertProvisionerJob(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertProvisionerJob").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertProvisionerJob", start, 1, err)
 	return job, err
 }
 
+func (m queryMetricsStore) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertProvisionerJobLogArchive(ctx, arg)
+	m.observe(ctx, "InsertProvisionerJobLogArchive", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	start := time.Now()
 	logs, err := m.s.InsertProvisionerJobLogs(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertProvisionerJobLogs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertProvisionerJobLogs", start, len(logs), err)
 	return logs, err
 }
 
 func (m queryMetricsStore) InsertProvisionerJobTimings(ctx context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertProvisionerJobTimings(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertProvisionerJobTimings").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertProvisionerJobTimings", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertProvisionerKey(ctx context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertProvisionerKey(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertProvisionerKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertProvisionerKey", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertProvisionerReservation(ctx context.Context, arg database.InsertProvisionerReservationParams) (database.ProvisionerReservation, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertProvisionerReservation(ctx, arg)
+	m.observe(ctx, "InsertProvisionerReservation", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertReplica(ctx context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	start := time.Now()
 	replica, err := m.s.InsertReplica(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertReplica").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertReplica", start, 1, err)
 	return replica, err
 }
 
+func (m queryMetricsStore) InsertServiceAccount(ctx context.Context, arg database.InsertServiceAccountParams) (database.ServiceAccount, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertServiceAccount(ctx, arg)
+	m.observe(ctx, "InsertServiceAccount", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertTelemetryItemIfNotExists(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertTelemetryItemIfNotExists").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertTelemetryItemIfNotExists", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplate(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertTemplate").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertTemplate", start, noRows, err)
 	return err
 }
 
+func (m queryMetricsStore) InsertTemplatePreset(ctx context.Context, arg database.InsertTemplatePresetParams) (database.TemplatePreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplatePreset(ctx, arg)
+	m.observe(ctx, "InsertTemplatePreset", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplateVersion(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertTemplateVersion").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertTemplateVersion", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) InsertTemplateVersionParameter(ctx context.Context, arg database.InsertTemplateVersionParameterParams) (database.TemplateVersionParameter, error) {
 	start := time.Now()
 	parameter, err := m.s.InsertTemplateVersionParameter(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertTemplateVersionParameter").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertTemplateVersionParameter", start, 1, err)
 	return parameter, err
 }
 
 func (m queryMetricsStore) InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg database.InsertTemplateVersionTerraformValuesByJobIDParams) error {
 	start := time.Now()
 	r0 := m.s.InsertTemplateVersionTerraformValuesByJobID(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertTemplateVersionTerraformValuesByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertTemplateVersionTerraformValuesByJobID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variable, err := m.s.InsertTemplateVersionVariable(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertTemplateVersionVariable").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertTemplateVersionVariable", start, 1, err)
 	return variable, err
 }
 
 func (m queryMetricsStore) InsertTemplateVersionWorkspaceTag(ctx context.Context, arg database.InsertTemplateVersionWorkspaceTagParams) (database.TemplateVersionWorkspaceTag, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertTemplateVersionWorkspaceTag(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertTemplateVersionWorkspaceTag").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertTemplateVersionWorkspaceTag", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertUser(ctx context.Context, arg database.InsertUserParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.InsertUser(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertUser").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertUser", start, 1, err)
 	return user, err
 }
 
+func (m queryMetricsStore) InsertUserActivityExport(ctx context.Context, arg database.InsertUserActivityExportParams) (database.UserActivityExport, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertUserActivityExport(ctx, arg)
+	m.observe(ctx, "InsertUserActivityExport", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertUserGroupsByID(ctx context.Context, arg database.InsertUserGroupsByIDParams) ([]uuid.UUID, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertUserGroupsByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertUserGroupsByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertUserGroupsByID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertUserGroupsByName(ctx context.Context, arg database.InsertUserGroupsByNameParams) error {
 	start := time.Now()
 	err := m.s.InsertUserGroupsByName(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertUserGroupsByName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertUserGroupsByName", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) InsertUserLink(ctx context.Context, arg database.InsertUserLinkParams) (database.UserLink, error) {
 	start := time.Now()
 	link, err := m.s.InsertUserLink(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertUserLink").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertUserLink", start, 1, err)
 	return link, err
 }
 
 func (m queryMetricsStore) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertVolumeResourceMonitor(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertVolumeResourceMonitor").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertVolumeResourceMonitor", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWebpushSubscription(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWebpushSubscription").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWebpushSubscription", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.WorkspaceTable, error) {
 	start := time.Now()
 	workspace, err := m.s.InsertWorkspace(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspace").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspace", start, 1, err)
 	return workspace, err
 }
 
 func (m queryMetricsStore) InsertWorkspaceAgent(ctx context.Context, arg database.InsertWorkspaceAgentParams) (database.WorkspaceAgent, error) {
 	start := time.Now()
 	agent, err := m.s.InsertWorkspaceAgent(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAgent").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAgent", start, 1, err)
 	return agent, err
 }
 
 func (m queryMetricsStore) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg database.InsertWorkspaceAgentDevcontainersParams) ([]database.WorkspaceAgentDevcontainer, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentDevcontainers(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentDevcontainers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAgentDevcontainers", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceAgentHealthEvent(ctx context.Context, arg database.InsertWorkspaceAgentHealthEventParams) (database.WorkspaceAgentHealthEvent, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentHealthEvent(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceAgentHealthEvent", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWorkspaceAgentLogSources(ctx context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentLogSources(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentLogSources").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAgentLogSources", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWorkspaceAgentLogs(ctx context.Context, arg database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentLogs(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentLogs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAgentLogs", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
 	start := time.Now()
 	err := m.s.InsertWorkspaceAgentMetadata(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentMetadata").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAgentMetadata", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentScriptTimings(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentScriptTimings").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAgentScriptTimings", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWorkspaceAgentScripts(ctx context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentScripts(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentScripts").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAgentScripts", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWorkspaceAgentStats(ctx context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceAgentStats(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAgentStats", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) InsertWorkspaceAppStats(ctx context.Context, arg database.InsertWorkspaceAppStatsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceAppStats(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAppStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAppStats", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) InsertWorkspaceAppStatus(ctx context.Context, arg database.InsertWorkspaceAppStatusParams) (database.WorkspaceAppStatus, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAppStatus(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceAppStatus").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceAppStatus", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWorkspaceBuild(ctx context.Context, arg database.InsertWorkspaceBuildParams) error {
 	start := time.Now()
 	err := m.s.InsertWorkspaceBuild(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceBuild").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceBuild", start, noRows, err)
 	return err
 }
 
+func (m queryMetricsStore) InsertWorkspaceBuildArchive(ctx context.Context, arg database.InsertWorkspaceBuildArchiveParams) (database.WorkspaceBuildArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceBuildArchive(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceBuildArchive", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceBuildDurationNotification(ctx context.Context, arg database.InsertWorkspaceBuildDurationNotificationParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceBuildDurationNotification(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceBuildDurationNotification", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceBuildIdempotencyKey(ctx context.Context, arg database.InsertWorkspaceBuildIdempotencyKeyParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceBuildIdempotencyKey(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceBuildIdempotencyKey", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	start := time.Now()
 	err := m.s.InsertWorkspaceBuildParameters(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceBuildParameters").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceBuildParameters", start, noRows, err)
 	return err
 }
 
+func (m queryMetricsStore) InsertWorkspaceBuildQueueEntry(ctx context.Context, arg database.InsertWorkspaceBuildQueueEntryParams) (database.WorkspaceBuildQueue, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceBuildQueueEntry(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceBuildQueueEntry", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceLabels(ctx context.Context, arg database.InsertWorkspaceLabelsParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceLabels(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceLabels", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) InsertWorkspaceLock(ctx context.Context, arg database.InsertWorkspaceLockParams) (database.WorkspaceLock, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceLock(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceLock", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceMigration(ctx context.Context, arg database.InsertWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceMigration(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceMigration", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceModule(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceModule").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceModule", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.InsertWorkspaceProxy(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceProxy").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceProxy", start, 1, err)
 	return proxy, err
 }
 
 func (m queryMetricsStore) InsertWorkspaceResource(ctx context.Context, arg database.InsertWorkspaceResourceParams) (database.WorkspaceResource, error) {
 	start := time.Now()
 	resource, err := m.s.InsertWorkspaceResource(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceResource").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceResource", start, 1, err)
 	return resource, err
 }
 
 func (m queryMetricsStore) InsertWorkspaceResourceMetadata(ctx context.Context, arg database.InsertWorkspaceResourceMetadataParams) ([]database.WorkspaceResourceMetadatum, error) {
 	start := time.Now()
 	metadata, err := m.s.InsertWorkspaceResourceMetadata(ctx, arg)
-	m.queryLatencies.WithLabelValues("InsertWorkspaceResourceMetadata").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "InsertWorkspaceResourceMetadata", start, len(metadata), err)
 	return metadata, err
 }
 
+func (m queryMetricsStore) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSessionRecording(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceSessionRecording", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (database.WorkspaceSessionRecordingChunk, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+	m.observe(ctx, "InsertWorkspaceSessionRecordingChunk", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) IsTemplateVersionRolloutCohortMember(ctx context.Context, arg database.IsTemplateVersionRolloutCohortMemberParams) (bool, error) {
+	start := time.Now()
+	r0, r1 := m.s.IsTemplateVersionRolloutCohortMember(ctx, arg)
+	m.observe(ctx, "IsTemplateVersionRolloutCohortMember", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.ListProvisionerKeysByOrganization(ctx, organizationID)
-	m.queryLatencies.WithLabelValues("ListProvisionerKeysByOrganization").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "ListProvisionerKeysByOrganization", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) ListProvisionerKeysByOrganizationExcludeReserved(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.ListProvisionerKeysByOrganizationExcludeReserved(ctx, organizationID)
-	m.queryLatencies.WithLabelValues("ListProvisionerKeysByOrganizationExcludeReserved").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "ListProvisionerKeysByOrganizationExcludeReserved", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
 	start := time.Now()
 	r0, r1 := m.s.ListWorkspaceAgentPortShares(ctx, workspaceID)
-	m.queryLatencies.WithLabelValues("ListWorkspaceAgentPortShares").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "ListWorkspaceAgentPortShares", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) MarkAllInboxNotificationsAsRead(ctx context.Context, arg database.MarkAllInboxNotificationsAsReadParams) error {
 	start := time.Now()
 	r0 := m.s.MarkAllInboxNotificationsAsRead(ctx, arg)
-	m.queryLatencies.WithLabelValues("MarkAllInboxNotificationsAsRead").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "MarkAllInboxNotificationsAsRead", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) MarkNotificationMessagesDigested(ctx context.Context, arg database.MarkNotificationMessagesDigestedParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.MarkNotificationMessagesDigested(ctx, arg)
+	m.observe(ctx, "MarkNotificationMessagesDigested", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) OIDCClaimFieldValues(ctx context.Context, organizationID database.OIDCClaimFieldValuesParams) ([]string, error) {
 	start := time.Now()
 	r0, r1 := m.s.OIDCClaimFieldValues(ctx, organizationID)
-	m.queryLatencies.WithLabelValues("OIDCClaimFieldValues").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "OIDCClaimFieldValues", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) OIDCClaimFields(ctx context.Context, organizationID uuid.UUID) ([]string, error) {
 	start := time.Now()
 	r0, r1 := m.s.OIDCClaimFields(ctx, organizationID)
-	m.queryLatencies.WithLabelValues("OIDCClaimFields").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "OIDCClaimFields", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) OrganizationMembers(ctx context.Context, arg database.OrganizationMembersParams) ([]database.OrganizationMembersRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.OrganizationMembers(ctx, arg)
-	m.queryLatencies.WithLabelValues("OrganizationMembers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "OrganizationMembers", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) PaginatedOrganizationMembers(ctx context.Context, arg database.PaginatedOrganizationMembersParams) ([]database.PaginatedOrganizationMembersRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.PaginatedOrganizationMembers(ctx, arg)
-	m.queryLatencies.WithLabelValues("PaginatedOrganizationMembers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "PaginatedOrganizationMembers", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx, templateID)
-	m.queryLatencies.WithLabelValues("ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.RegisterWorkspaceProxy(ctx, arg)
-	m.queryLatencies.WithLabelValues("RegisterWorkspaceProxy").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "RegisterWorkspaceProxy", start, 1, err)
 	return proxy, err
 }
 
 func (m queryMetricsStore) RemoveUserFromAllGroups(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.RemoveUserFromAllGroups(ctx, userID)
-	m.queryLatencies.WithLabelValues("RemoveUserFromAllGroups").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "RemoveUserFromAllGroups", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) RemoveUserFromGroups(ctx context.Context, arg database.RemoveUserFromGroupsParams) ([]uuid.UUID, error) {
 	start := time.Now()
 	r0, r1 := m.s.RemoveUserFromGroups(ctx, arg)
-	m.queryLatencies.WithLabelValues("RemoveUserFromGroups").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "RemoveUserFromGroups", start, len(r0), r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) ResolveWorkspaceAgentHealthEvent(ctx context.Context, arg database.ResolveWorkspaceAgentHealthEventParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.ResolveWorkspaceAgentHealthEvent(ctx, arg)
+	m.observe(ctx, "ResolveWorkspaceAgentHealthEvent", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	start := time.Now()
 	r0 := m.s.RevokeDBCryptKey(ctx, activeKeyDigest)
-	m.queryLatencies.WithLabelValues("RevokeDBCryptKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "RevokeDBCryptKey", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) RotateProvisionerKey(ctx context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.RotateProvisionerKey(ctx, arg)
+	m.observe(ctx, "RotateProvisionerKey", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) SearchProvisionerJobLogs(ctx context.Context, arg database.SearchProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.SearchProvisionerJobLogs(ctx, arg)
+	m.observe(ctx, "SearchProvisionerJobLogs", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
 	start := time.Now()
 	ok, err := m.s.TryAcquireLock(ctx, pgTryAdvisoryXactLock)
-	m.queryLatencies.WithLabelValues("TryAcquireLock").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "TryAcquireLock", start, 1, err)
 	return ok, err
 }
 
 func (m queryMetricsStore) UnarchiveTemplateVersion(ctx context.Context, arg database.UnarchiveTemplateVersionParams) error {
 	start := time.Now()
 	r0 := m.s.UnarchiveTemplateVersion(ctx, arg)
-	m.queryLatencies.WithLabelValues("UnarchiveTemplateVersion").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UnarchiveTemplateVersion", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UnfavoriteWorkspace(ctx context.Context, arg uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.UnfavoriteWorkspace(ctx, arg)
-	m.queryLatencies.WithLabelValues("UnfavoriteWorkspace").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UnfavoriteWorkspace", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) UnlockWorkspace(ctx context.Context, arg database.UnlockWorkspaceParams) (database.WorkspaceLock, error) {
+	start := time.Now()
+	r0, r1 := m.s.UnlockWorkspace(ctx, arg)
+	m.observe(ctx, "UnlockWorkspace", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKeyByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateAPIKeyByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateAPIKeyByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateAPIKeyByID", start, noRows, err)
 	return err
 }
 
+func (m queryMetricsStore) UpdateAccessRequestExpiredByID(ctx context.Context, id uuid.UUID) (database.AccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateAccessRequestExpiredByID(ctx, id)
+	m.observe(ctx, "UpdateAccessRequestExpiredByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateAccessRequestReviewByID(ctx context.Context, arg database.UpdateAccessRequestReviewByIDParams) (database.AccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateAccessRequestReviewByID(ctx, arg)
+	m.observe(ctx, "UpdateAccessRequestReviewByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateBuildAlertRuleByID(ctx context.Context, arg database.UpdateBuildAlertRuleByIDParams) (database.BuildAlertRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateBuildAlertRuleByID(ctx, arg)
+	m.observe(ctx, "UpdateBuildAlertRuleByID", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateBuildAlertRuleEvaluation(ctx context.Context, arg database.UpdateBuildAlertRuleEvaluationParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateBuildAlertRuleEvaluation(ctx, arg)
+	m.observe(ctx, "UpdateBuildAlertRuleEvaluation", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) UpdateCryptoKeyDeletesAt(ctx context.Context, arg database.UpdateCryptoKeyDeletesAtParams) (database.CryptoKey, error) {
 	start := time.Now()
 	key, err := m.s.UpdateCryptoKeyDeletesAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateCryptoKeyDeletesAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateCryptoKeyDeletesAt", start, 1, err)
 	return key, err
 }
 
 func (m queryMetricsStore) UpdateCustomRole(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateCustomRole(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateCustomRole").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateCustomRole", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateExternalAuthLink(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateExternalAuthLink").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateExternalAuthLink", start, 1, err)
 	return link, err
 }
 
 func (m queryMetricsStore) UpdateExternalAuthLinkRefreshToken(ctx context.Context, arg database.UpdateExternalAuthLinkRefreshTokenParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateExternalAuthLinkRefreshToken(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateExternalAuthLinkRefreshToken").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateExternalAuthLinkRefreshToken", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateGitSSHKey(ctx context.Context, arg database.UpdateGitSSHKeyParams) (database.GitSSHKey, error) {
 	start := time.Now()
 	key, err := m.s.UpdateGitSSHKey(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateGitSSHKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateGitSSHKey", start, 1, err)
 	return key, err
 }
 
 func (m queryMetricsStore) UpdateGroupByID(ctx context.Context, arg database.UpdateGroupByIDParams) (database.Group, error) {
 	start := time.Now()
 	group, err := m.s.UpdateGroupByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateGroupByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateGroupByID", start, 1, err)
 	return group, err
 }
 
 func (m queryMetricsStore) UpdateInactiveUsersToDormant(ctx context.Context, lastSeenAfter database.UpdateInactiveUsersToDormantParams) ([]database.UpdateInactiveUsersToDormantRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateInactiveUsersToDormant(ctx, lastSeenAfter)
-	m.queryLatencies.WithLabelValues("UpdateInactiveUsersToDormant").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateInactiveUsersToDormant", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateInboxNotificationReadStatus(ctx context.Context, arg database.UpdateInboxNotificationReadStatusParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateInboxNotificationReadStatus(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateInboxNotificationReadStatus").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateInboxNotificationReadStatus", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	start := time.Now()
 	member, err := m.s.UpdateMemberRoles(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateMemberRoles").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateMemberRoles", start, 1, err)
 	return member, err
 }
 
 func (m queryMetricsStore) UpdateMemoryResourceMonitor(ctx context.Context, arg database.UpdateMemoryResourceMonitorParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateMemoryResourceMonitor(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateMemoryResourceMonitor").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateMemoryResourceMonitor", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateNotificationTemplateMethodByID(ctx context.Context, arg database.UpdateNotificationTemplateMethodByIDParams) (database.NotificationTemplate, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateNotificationTemplateMethodByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateNotificationTemplateMethodByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateNotificationTemplateMethodByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateOAuth2ProviderAppByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateOAuth2ProviderAppByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateOAuth2ProviderAppSecretByID", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateOrganization(ctx context.Context, arg database.UpdateOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateOrganization(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateOrganization").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateOrganization", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateOrganizationDeletedByID(ctx context.Context, arg database.UpdateOrganizationDeletedByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateOrganizationDeletedByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateOrganizationDeletedByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateOrganizationDeletedByID", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) UpdatePendingProvisionerJobsUpdatedAt(ctx context.Context, arg database.UpdatePendingProvisionerJobsUpdatedAtParams) ([]database.ProvisionerJob, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdatePendingProvisionerJobsUpdatedAt(ctx, arg)
+	m.observe(ctx, "UpdatePendingProvisionerJobsUpdatedAt", start, len(r0), r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdatePresetPrebuildStatus(ctx context.Context, arg database.UpdatePresetPrebuildStatusParams) error {
 	start := time.Now()
 	r0 := m.s.UpdatePresetPrebuildStatus(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdatePresetPrebuildStatus").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdatePresetPrebuildStatus", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateProvisionerDaemonLastSeenAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateProvisionerDaemonLastSeenAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateProvisionerDaemonLastSeenAt", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateProvisionerJobByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateProvisionerJobByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobWithCancelByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateProvisionerJobWithCancelByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateProvisionerJobWithCancelByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg database.UpdateProvisionerJobWithCompleteByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobWithCompleteByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateProvisionerJobWithCompleteByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateProvisionerJobWithCompleteByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx context.Context, arg database.UpdateProvisionerJobWithCompleteWithStartedAtByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateProvisionerJobWithCompleteWithStartedAtByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateProvisionerJobWithCompleteWithStartedAtByID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateProvisionerKeyLastUsedAt(ctx, arg)
+	m.observe(ctx, "UpdateProvisionerKeyLastUsedAt", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	start := time.Now()
 	replica, err := m.s.UpdateReplica(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateReplica").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateReplica", start, 1, err)
 	return replica, err
 }
 
 func (m queryMetricsStore) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTailnetPeerStatusByCoordinator(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTailnetPeerStatusByCoordinator").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTailnetPeerStatusByCoordinator", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateACLByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateACLByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateACLByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateTemplateAccessControlByID(ctx context.Context, arg database.UpdateTemplateAccessControlByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTemplateAccessControlByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateAccessControlByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateAccessControlByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateTemplateActiveVersionByID(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateActiveVersionByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateActiveVersionByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateActiveVersionByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateTemplateDeletedByID(ctx context.Context, arg database.UpdateTemplateDeletedByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateDeletedByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateDeletedByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateDeletedByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateTemplateMetaByID(ctx context.Context, arg database.UpdateTemplateMetaByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateMetaByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateMetaByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateMetaByID", start, noRows, err)
 	return err
 }
 
+func (m queryMetricsStore) UpdateTemplatePresetByID(ctx context.Context, arg database.UpdateTemplatePresetByIDParams) (database.TemplatePreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplatePresetByID(ctx, arg)
+	m.observe(ctx, "UpdateTemplatePresetByID", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateScheduleByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateScheduleByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateScheduleByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateTemplateVersionAITaskByJobID(ctx context.Context, arg database.UpdateTemplateVersionAITaskByJobIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTemplateVersionAITaskByJobID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateVersionAITaskByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateVersionAITaskByJobID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateVersionByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateVersionByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateVersionByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg database.UpdateTemplateVersionDescriptionByJobIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateVersionDescriptionByJobID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateVersionDescriptionByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateVersionDescriptionByJobID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg database.UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateVersionExternalAuthProvidersByJobID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateVersionExternalAuthProvidersByJobID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateVersionExternalAuthProvidersByJobID", start, noRows, err)
 	return err
 }
 
+func (m queryMetricsStore) UpdateTemplateVersionVariableValue(ctx context.Context, arg database.UpdateTemplateVersionVariableValueParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateTemplateVersionVariableValue(ctx, arg)
+	m.observe(ctx, "UpdateTemplateVersionVariableValue", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTemplateWorkspacesLastUsedAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateTemplateWorkspacesLastUsedAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateTemplateWorkspacesLastUsedAt", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) UpdateUserActivityExportStatusByID(ctx context.Context, arg database.UpdateUserActivityExportStatusByIDParams) (database.UserActivityExport, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserActivityExportStatusByID(ctx, arg)
+	m.observe(ctx, "UpdateUserActivityExportStatusByID", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateUserDeletedByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.UpdateUserDeletedByID(ctx, id)
-	m.queryLatencies.WithLabelValues("UpdateUserDeletedByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserDeletedByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateUserGithubComUserID(ctx context.Context, arg database.UpdateUserGithubComUserIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateUserGithubComUserID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserGithubComUserID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserGithubComUserID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateUserHashedOneTimePasscode(ctx context.Context, arg database.UpdateUserHashedOneTimePasscodeParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateUserHashedOneTimePasscode(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserHashedOneTimePasscode").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserHashedOneTimePasscode", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateUserHashedPassword(ctx context.Context, arg database.UpdateUserHashedPasswordParams) error {
 	start := time.Now()
 	err := m.s.UpdateUserHashedPassword(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserHashedPassword").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserHashedPassword", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateUserLastSeenAt(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.UpdateUserLastSeenAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserLastSeenAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserLastSeenAt", start, 1, err)
 	return user, err
 }
 
 func (m queryMetricsStore) UpdateUserLink(ctx context.Context, arg database.UpdateUserLinkParams) (database.UserLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateUserLink(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserLink").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserLink", start, 1, err)
 	return link, err
 }
 
 func (m queryMetricsStore) UpdateUserLinkedID(ctx context.Context, arg database.UpdateUserLinkedIDParams) (database.UserLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateUserLinkedID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserLinkedID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserLinkedID", start, 1, err)
 	return link, err
 }
 
 func (m queryMetricsStore) UpdateUserLoginType(ctx context.Context, arg database.UpdateUserLoginTypeParams) (database.User, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserLoginType(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserLoginType").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserLoginType", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateUserNotificationBuildDurationThreshold(ctx context.Context, arg database.UpdateUserNotificationBuildDurationThresholdParams) (database.UserConfig, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserNotificationBuildDurationThreshold(ctx, arg)
+	m.observe(ctx, "UpdateUserNotificationBuildDurationThreshold", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateUserNotificationDigestFrequency(ctx context.Context, arg database.UpdateUserNotificationDigestFrequencyParams) (database.UserConfig, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserNotificationDigestFrequency(ctx, arg)
+	m.observe(ctx, "UpdateUserNotificationDigestFrequency", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateUserNotificationDigestPreferences(ctx context.Context, arg database.UpdateUserNotificationDigestPreferencesParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserNotificationDigestPreferences(ctx, arg)
+	m.observe(ctx, "UpdateUserNotificationDigestPreferences", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateUserNotificationPreferences(ctx context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserNotificationPreferences(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserNotificationPreferences").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserNotificationPreferences", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.UpdateUserProfile(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserProfile").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserProfile", start, 1, err)
 	return user, err
 }
 
 func (m queryMetricsStore) UpdateUserQuietHoursSchedule(ctx context.Context, arg database.UpdateUserQuietHoursScheduleParams) (database.User, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserQuietHoursSchedule(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserQuietHoursSchedule").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserQuietHoursSchedule", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateUserRoles(ctx context.Context, arg database.UpdateUserRolesParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.UpdateUserRoles(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserRoles").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserRoles", start, 1, err)
 	return user, err
 }
 
 func (m queryMetricsStore) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.UpdateUserStatus(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserStatus").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserStatus", start, 1, err)
 	return user, err
 }
 
 func (m queryMetricsStore) UpdateUserTerminalFont(ctx context.Context, arg database.UpdateUserTerminalFontParams) (database.UserConfig, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserTerminalFont(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserTerminalFont").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserTerminalFont", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateUserThemePreference(ctx context.Context, arg database.UpdateUserThemePreferenceParams) (database.UserConfig, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserThemePreference(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateUserThemePreference").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateUserThemePreference", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateVolumeResourceMonitor(ctx context.Context, arg database.UpdateVolumeResourceMonitorParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateVolumeResourceMonitor(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateVolumeResourceMonitor").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateVolumeResourceMonitor", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 	start := time.Now()
 	workspace, err := m.s.UpdateWorkspace(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspace").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspace", start, 1, err)
 	return workspace, err
 }
 
+func (m queryMetricsStore) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceACLByID(ctx, arg)
+	m.observe(ctx, "UpdateWorkspaceACLByID", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentConnectionByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceAgentConnectionByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg database.UpdateWorkspaceAgentLifecycleStateByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceAgentLifecycleStateByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentLifecycleStateByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceAgentLifecycleStateByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg database.UpdateWorkspaceAgentLogOverflowByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceAgentLogOverflowByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentLogOverflowByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceAgentLogOverflowByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspaceAgentMetadata(ctx context.Context, arg database.UpdateWorkspaceAgentMetadataParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentMetadata(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentMetadata").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceAgentMetadata", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentStartupByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentStartupByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceAgentStartupByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateWorkspaceAppHealthByID(ctx context.Context, arg database.UpdateWorkspaceAppHealthByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAppHealthByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceAppHealthByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceAppHealthByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceAutomaticUpdates(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceAutomaticUpdates").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceAutomaticUpdates", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspaceAutostart(ctx context.Context, arg database.UpdateWorkspaceAutostartParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAutostart(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceAutostart").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceAutostart", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateWorkspaceBuildAITaskByID(ctx context.Context, arg database.UpdateWorkspaceBuildAITaskByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceBuildAITaskByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildAITaskByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceBuildAITaskByID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspaceBuildCostByID(ctx context.Context, arg database.UpdateWorkspaceBuildCostByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceBuildCostByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildCostByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceBuildCostByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg database.UpdateWorkspaceBuildDeadlineByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceBuildDeadlineByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildDeadlineByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceBuildDeadlineByID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpdateWorkspaceBuildIdempotencyKeyBuildID(ctx context.Context, arg database.UpdateWorkspaceBuildIdempotencyKeyBuildIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceBuildIdempotencyKeyBuildID(ctx, arg)
+	m.observe(ctx, "UpdateWorkspaceBuildIdempotencyKeyBuildID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg database.UpdateWorkspaceBuildProvisionerStateByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceBuildProvisionerStateByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildProvisionerStateByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceBuildProvisionerStateByID", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpdateWorkspaceBuildQueueEntryFailed(ctx context.Context, arg database.UpdateWorkspaceBuildQueueEntryFailedParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceBuildQueueEntryFailed(ctx, arg)
+	m.observe(ctx, "UpdateWorkspaceBuildQueueEntryFailed", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceDeletedByID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceDeletedByID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceDeletedByID", start, noRows, err)
 	return err
 }
 
 func (m queryMetricsStore) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 	start := time.Now()
 	ws, r0 := m.s.UpdateWorkspaceDormantDeletingAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceDormantDeletingAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceDormantDeletingAt", start, 1, r0)
 	return ws, r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceDriftCheckByJobID(ctx context.Context, arg database.UpdateWorkspaceDriftCheckByJobIDParams) (database.WorkspaceDriftCheck, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceDriftCheckByJobID(ctx, arg)
+	m.observe(ctx, "UpdateWorkspaceDriftCheckByJobID", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceLastUsedAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceLastUsedAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceLastUsedAt", start, noRows, err)
 	return err
 }
 
+func (m queryMetricsStore) UpdateWorkspaceMigrationSessionToken(ctx context.Context, arg database.UpdateWorkspaceMigrationSessionTokenParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceMigrationSessionToken(ctx, arg)
+	m.observe(ctx, "UpdateWorkspaceMigrationSessionToken", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpdateWorkspaceMigrationStatus(ctx context.Context, arg database.UpdateWorkspaceMigrationStatusParams) (database.WorkspaceMigration, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceMigrationStatus(ctx, arg)
+	m.observe(ctx, "UpdateWorkspaceMigrationStatus", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceNextStartAt(ctx context.Context, arg database.UpdateWorkspaceNextStartAtParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceNextStartAt(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceNextStartAt").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceNextStartAt", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.UpdateWorkspaceProxy(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceProxy").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceProxy", start, 1, err)
 	return proxy, err
 }
 
 func (m queryMetricsStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.UpdateWorkspaceProxyDeletedParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceProxyDeleted(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceProxyDeleted").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceProxyDeleted", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpdateWorkspaceSessionRecording(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceSessionRecording(ctx, arg)
+	m.observe(ctx, "UpdateWorkspaceSessionRecording", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateWorkspaceSessionRecordingChunkData(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingChunkDataParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceSessionRecordingChunkData(ctx, arg)
+	m.observe(ctx, "UpdateWorkspaceSessionRecordingChunkData", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceTTL(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspaceTTL").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspaceTTL", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Context, arg database.UpdateWorkspacesDormantDeletingAtByTemplateIDParams) ([]database.WorkspaceTable, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateWorkspacesDormantDeletingAtByTemplateID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspacesDormantDeletingAtByTemplateID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspacesDormantDeletingAtByTemplateID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg database.UpdateWorkspacesTTLByTemplateIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspacesTTLByTemplateID(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpdateWorkspacesTTLByTemplateID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpdateWorkspacesTTLByTemplateID", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertAnnouncementBanners(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertAnnouncementBanners(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertAnnouncementBanners").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertAnnouncementBanners", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertAppSecurityKey(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertAppSecurityKey(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertAppSecurityKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertAppSecurityKey", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertApplicationName(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertApplicationName(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertApplicationName").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertApplicationName", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertCoordinatorResumeTokenSigningKey(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertCoordinatorResumeTokenSigningKey(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertCoordinatorResumeTokenSigningKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertCoordinatorResumeTokenSigningKey", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertDBPurgeStats(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertDBPurgeStats(ctx, value)
+	m.observe(ctx, "UpsertDBPurgeStats", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertDefaultProxy(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertDefaultProxy").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertDefaultProxy", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertHealthSettings(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertHealthSettings(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertHealthSettings").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertHealthSettings", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertJobReaperLastRunAt(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertJobReaperLastRunAt(ctx, value)
+	m.observe(ctx, "UpsertJobReaperLastRunAt", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertLastUpdateCheck(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertLastUpdateCheck(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertLastUpdateCheck").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertLastUpdateCheck", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertLogoURL(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertLogoURL(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertLogoURL").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertLogoURL", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertNotificationReportGeneratorLog(ctx context.Context, arg database.UpsertNotificationReportGeneratorLogParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertNotificationReportGeneratorLog(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertNotificationReportGeneratorLog").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertNotificationReportGeneratorLog", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertNotificationsSettings(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertNotificationsSettings(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertNotificationsSettings").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertNotificationsSettings", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertOAuth2GithubDefaultEligible(ctx context.Context, eligible bool) error {
 	start := time.Now()
 	r0 := m.s.UpsertOAuth2GithubDefaultEligible(ctx, eligible)
-	m.queryLatencies.WithLabelValues("UpsertOAuth2GithubDefaultEligible").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertOAuth2GithubDefaultEligible", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertOAuthSigningKey(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertOAuthSigningKey(ctx, value)
-	m.queryLatencies.WithLabelValues("UpsertOAuthSigningKey").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertOAuthSigningKey", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) UpsertOrganizationWorkspaceNamingPolicy(ctx context.Context, arg database.UpsertOrganizationWorkspaceNamingPolicyParams) (database.WorkspaceNamingPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertOrganizationWorkspaceNamingPolicy(ctx, arg)
+	m.observe(ctx, "UpsertOrganizationWorkspaceNamingPolicy", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertProvisionerDaemon(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertProvisionerDaemon").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertProvisionerDaemon", start, 1, r1)
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertReadOnlySettings(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertReadOnlySettings(ctx, value)
+	m.observe(ctx, "UpsertReadOnlySettings", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) UpsertRuntimeConfig(ctx context.Context, arg database.UpsertRuntimeConfigParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertRuntimeConfig(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertRuntimeConfig").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertRuntimeConfig", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertTailnetAgent(ctx context.Context, arg database.UpsertTailnetAgentParams) (database.TailnetAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertTailnetAgent(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertTailnetAgent").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertTailnetAgent", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpsertTailnetClient(ctx context.Context, arg database.UpsertTailnetClientParams) (database.TailnetClient, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertTailnetClient(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertTailnetClient").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertTailnetClient", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpsertTailnetClientSubscription(ctx context.Context, arg database.UpsertTailnetClientSubscriptionParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertTailnetClientSubscription(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertTailnetClientSubscription").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertTailnetClientSubscription", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (database.TailnetCoordinator, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertTailnetCoordinator(ctx, id)
-	m.queryLatencies.WithLabelValues("UpsertTailnetCoordinator").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertTailnetCoordinator", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpsertTailnetPeer(ctx context.Context, arg database.UpsertTailnetPeerParams) (database.TailnetPeer, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertTailnetPeer(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertTailnetPeer").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertTailnetPeer", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpsertTailnetTunnel(ctx context.Context, arg database.UpsertTailnetTunnelParams) (database.TailnetTunnel, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertTailnetTunnel(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertTailnetTunnel").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertTailnetTunnel", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpsertTelemetryItem(ctx context.Context, arg database.UpsertTelemetryItemParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertTelemetryItem(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertTelemetryItem").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertTelemetryItem", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertTemplateBuildDurationStats(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.UpsertTemplateBuildDurationStats(ctx)
+	m.observe(ctx, "UpsertTemplateBuildDurationStats", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertTemplatePresetGroupDefault(ctx context.Context, arg database.UpsertTemplatePresetGroupDefaultParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertTemplatePresetGroupDefault(ctx, arg)
+	m.observe(ctx, "UpsertTemplatePresetGroupDefault", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) UpsertTemplateReleaseChannel(ctx context.Context, arg database.UpsertTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateReleaseChannel(ctx, arg)
+	m.observe(ctx, "UpsertTemplateReleaseChannel", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertTemplateShare(ctx context.Context, arg database.UpsertTemplateShareParams) (database.TemplateShare, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateShare(ctx, arg)
+	m.observe(ctx, "UpsertTemplateShare", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.UpsertTemplateUsageStats(ctx)
-	m.queryLatencies.WithLabelValues("UpsertTemplateUsageStats").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertTemplateUsageStats", start, noRows, r0)
 	return r0
 }
 
+func (m queryMetricsStore) UpsertTemplateVersionRollout(ctx context.Context, arg database.UpsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateVersionRollout(ctx, arg)
+	m.observe(ctx, "UpsertTemplateVersionRollout", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertTemplateWorkspaceNamingPolicy(ctx context.Context, arg database.UpsertTemplateWorkspaceNamingPolicyParams) (database.WorkspaceNamingPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateWorkspaceNamingPolicy(ctx, arg)
+	m.observe(ctx, "UpsertTemplateWorkspaceNamingPolicy", start, 1, r1)
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertWebpushVAPIDKeys(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertWebpushVAPIDKeys").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertWebpushVAPIDKeys", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceAgentConnectionQuality(ctx context.Context, arg database.UpsertWorkspaceAgentConnectionQualityParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceAgentConnectionQuality(ctx, arg)
+	m.observe(ctx, "UpsertWorkspaceAgentConnectionQuality", start, noRows, r0)
 	return r0
 }
 
 func (m queryMetricsStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertWorkspaceAgentPortShare(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertWorkspaceAgentPortShare", start, 1, r1)
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceAgentResourceUsage(ctx context.Context, arg database.UpsertWorkspaceAgentResourceUsageParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceAgentResourceUsage(ctx, arg)
+	m.observe(ctx, "UpsertWorkspaceAgentResourceUsage", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) UpsertWorkspaceApp(ctx context.Context, arg database.UpsertWorkspaceAppParams) (database.WorkspaceApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertWorkspaceApp(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertWorkspaceApp").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertWorkspaceApp", start, 1, r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) UpsertWorkspaceAppAuditSession(ctx context.Context, arg database.UpsertWorkspaceAppAuditSessionParams) (bool, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertWorkspaceAppAuditSession(ctx, arg)
-	m.queryLatencies.WithLabelValues("UpsertWorkspaceAppAuditSession").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "UpsertWorkspaceAppAuditSession", start, 1, r1)
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertWorkspaceBuildInterimState(ctx context.Context, arg database.UpsertWorkspaceBuildInterimStateParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceBuildInterimState(ctx, arg)
+	m.observe(ctx, "UpsertWorkspaceBuildInterimState", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceDailyCosts(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceDailyCosts(ctx)
+	m.observe(ctx, "UpsertWorkspaceDailyCosts", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceDriftCheck(ctx context.Context, arg database.UpsertWorkspaceDriftCheckParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceDriftCheck(ctx, arg)
+	m.observe(ctx, "UpsertWorkspaceDriftCheck", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspacePrebuildReservation(ctx context.Context, arg database.UpsertWorkspacePrebuildReservationParams) (database.WorkspacePrebuildReservation, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspacePrebuildReservation(ctx, arg)
+	m.observe(ctx, "UpsertWorkspacePrebuildReservation", start, 1, r1)
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceProvisionerAffinity(ctx context.Context, arg database.UpsertWorkspaceProvisionerAffinityParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceProvisionerAffinity(ctx, arg)
+	m.observe(ctx, "UpsertWorkspaceProvisionerAffinity", start, noRows, r0)
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceReleaseChannel(ctx context.Context, arg database.UpsertWorkspaceReleaseChannelParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceReleaseChannel(ctx, arg)
+	m.observe(ctx, "UpsertWorkspaceReleaseChannel", start, noRows, r0)
+	return r0
+}
+
 func (m queryMetricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
-	m.queryLatencies.WithLabelValues("GetAuthorizedTemplates").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAuthorizedTemplates", start, len(templates), err)
 	return templates, err
 }
 
 func (m queryMetricsStore) GetTemplateGroupRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateGroup, error) {
 	start := time.Now()
 	roles, err := m.s.GetTemplateGroupRoles(ctx, id)
-	m.queryLatencies.WithLabelValues("GetTemplateGroupRoles").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateGroupRoles", start, len(roles), err)
 	return roles, err
 }
 
 func (m queryMetricsStore) GetTemplateUserRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateUser, error) {
 	start := time.Now()
 	roles, err := m.s.GetTemplateUserRoles(ctx, id)
-	m.queryLatencies.WithLabelValues("GetTemplateUserRoles").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetTemplateUserRoles", start, len(roles), err)
 	return roles, err
 }
 
 func (m queryMetricsStore) GetAuthorizedWorkspaces(ctx context.Context, arg database.GetWorkspacesParams, prepared rbac.PreparedAuthorized) ([]database.GetWorkspacesRow, error) {
 	start := time.Now()
 	workspaces, err := m.s.GetAuthorizedWorkspaces(ctx, arg, prepared)
-	m.queryLatencies.WithLabelValues("GetAuthorizedWorkspaces").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAuthorizedWorkspaces", start, len(workspaces), err)
 	return workspaces, err
 }
 
 func (m queryMetricsStore) GetAuthorizedWorkspacesAndAgentsByOwnerID(ctx context.Context, ownerID uuid.UUID, prepared rbac.PreparedAuthorized) ([]database.GetWorkspacesAndAgentsByOwnerIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAuthorizedWorkspacesAndAgentsByOwnerID(ctx, ownerID, prepared)
-	m.queryLatencies.WithLabelValues("GetAuthorizedWorkspacesAndAgentsByOwnerID").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAuthorizedWorkspacesAndAgentsByOwnerID", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAuthorizedWorkspaceBuildParametersByBuildIDs(ctx context.Context, workspaceBuildIDs []uuid.UUID, prepared rbac.PreparedAuthorized) ([]database.WorkspaceBuildParameter, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAuthorizedWorkspaceBuildParametersByBuildIDs(ctx, workspaceBuildIDs, prepared)
-	m.queryLatencies.WithLabelValues("GetAuthorizedWorkspaceBuildParametersByBuildIDs").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAuthorizedWorkspaceBuildParametersByBuildIDs", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAuthorizedUsers(ctx context.Context, arg database.GetUsersParams, prepared rbac.PreparedAuthorized) ([]database.GetUsersRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAuthorizedUsers(ctx, arg, prepared)
-	m.queryLatencies.WithLabelValues("GetAuthorizedUsers").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAuthorizedUsers", start, len(r0), r1)
 	return r0, r1
 }
 
 func (m queryMetricsStore) GetAuthorizedAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams, prepared rbac.PreparedAuthorized) ([]database.GetAuditLogsOffsetRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAuthorizedAuditLogsOffset(ctx, arg, prepared)
-	m.queryLatencies.WithLabelValues("GetAuthorizedAuditLogsOffset").Observe(time.Since(start).Seconds())
+	m.observe(ctx, "GetAuthorizedAuditLogsOffset", start, len(r0), r1)
 	return r0, r1
 }
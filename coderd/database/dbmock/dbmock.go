@@ -117,6 +117,21 @@ func (mr *MockStoreMockRecorder) AllUserIDs(ctx, includeSystem any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllUserIDs", reflect.TypeOf((*MockStore)(nil).AllUserIDs), ctx, includeSystem)
 }
 
+// ApproveLoginDeviceCode mocks base method.
+func (m *MockStore) ApproveLoginDeviceCode(ctx context.Context, arg database.ApproveLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveLoginDeviceCode", ctx, arg)
+	ret0, _ := ret[0].(database.LoginDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveLoginDeviceCode indicates an expected call of ApproveLoginDeviceCode.
+func (mr *MockStoreMockRecorder) ApproveLoginDeviceCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveLoginDeviceCode", reflect.TypeOf((*MockStore)(nil).ApproveLoginDeviceCode), ctx, arg)
+}
+
 // ArchiveUnusedTemplateVersions mocks base method.
 func (m *MockStore) ArchiveUnusedTemplateVersions(ctx context.Context, arg database.ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -247,6 +262,36 @@ func (mr *MockStoreMockRecorder) CleanTailnetTunnels(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanTailnetTunnels", reflect.TypeOf((*MockStore)(nil).CleanTailnetTunnels), ctx)
 }
 
+// CompleteWorkspaceMigration mocks base method.
+func (m *MockStore) CompleteWorkspaceMigration(ctx context.Context, arg database.CompleteWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteWorkspaceMigration", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteWorkspaceMigration indicates an expected call of CompleteWorkspaceMigration.
+func (mr *MockStoreMockRecorder) CompleteWorkspaceMigration(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteWorkspaceMigration", reflect.TypeOf((*MockStore)(nil).CompleteWorkspaceMigration), ctx, arg)
+}
+
+// ConsumeApprovedLoginDeviceCode mocks base method.
+func (m *MockStore) ConsumeApprovedLoginDeviceCode(ctx context.Context, arg database.ConsumeApprovedLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeApprovedLoginDeviceCode", ctx, arg)
+	ret0, _ := ret[0].(database.LoginDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeApprovedLoginDeviceCode indicates an expected call of ConsumeApprovedLoginDeviceCode.
+func (mr *MockStoreMockRecorder) ConsumeApprovedLoginDeviceCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeApprovedLoginDeviceCode", reflect.TypeOf((*MockStore)(nil).ConsumeApprovedLoginDeviceCode), ctx, arg)
+}
+
 // CountInProgressPrebuilds mocks base method.
 func (m *MockStore) CountInProgressPrebuilds(ctx context.Context) ([]database.CountInProgressPrebuildsRow, error) {
 	m.ctrl.T.Helper()
@@ -262,6 +307,21 @@ func (mr *MockStoreMockRecorder) CountInProgressPrebuilds(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountInProgressPrebuilds", reflect.TypeOf((*MockStore)(nil).CountInProgressPrebuilds), ctx)
 }
 
+// CountInProgressProvisionerJobsByInitiator mocks base method.
+func (m *MockStore) CountInProgressProvisionerJobsByInitiator(ctx context.Context, arg database.CountInProgressProvisionerJobsByInitiatorParams) (database.CountInProgressProvisionerJobsByInitiatorRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountInProgressProvisionerJobsByInitiator", ctx, arg)
+	ret0, _ := ret[0].(database.CountInProgressProvisionerJobsByInitiatorRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountInProgressProvisionerJobsByInitiator indicates an expected call of CountInProgressProvisionerJobsByInitiator.
+func (mr *MockStoreMockRecorder) CountInProgressProvisionerJobsByInitiator(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountInProgressProvisionerJobsByInitiator", reflect.TypeOf((*MockStore)(nil).CountInProgressProvisionerJobsByInitiator), ctx, arg)
+}
+
 // CountUnreadInboxNotificationsByUserID mocks base method.
 func (m *MockStore) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
@@ -277,6 +337,21 @@ func (mr *MockStoreMockRecorder) CountUnreadInboxNotificationsByUserID(ctx, user
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadInboxNotificationsByUserID", reflect.TypeOf((*MockStore)(nil).CountUnreadInboxNotificationsByUserID), ctx, userID)
 }
 
+// CreateTimePartition mocks base method.
+func (m *MockStore) CreateTimePartition(ctx context.Context, arg database.CreateTimePartitionParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTimePartition", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTimePartition indicates an expected call of CreateTimePartition.
+func (mr *MockStoreMockRecorder) CreateTimePartition(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTimePartition", reflect.TypeOf((*MockStore)(nil).CreateTimePartition), ctx, arg)
+}
+
 // CustomRoles mocks base method.
 func (m *MockStore) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	m.ctrl.T.Helper()
@@ -376,6 +451,20 @@ func (mr *MockStoreMockRecorder) DeleteApplicationConnectAPIKeysByUserID(ctx, us
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationConnectAPIKeysByUserID", reflect.TypeOf((*MockStore)(nil).DeleteApplicationConnectAPIKeysByUserID), ctx, userID)
 }
 
+// DeleteBuildAlertRuleByID mocks base method.
+func (m *MockStore) DeleteBuildAlertRuleByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBuildAlertRuleByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBuildAlertRuleByID indicates an expected call of DeleteBuildAlertRuleByID.
+func (mr *MockStoreMockRecorder) DeleteBuildAlertRuleByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBuildAlertRuleByID", reflect.TypeOf((*MockStore)(nil).DeleteBuildAlertRuleByID), ctx, id)
+}
+
 // DeleteCoordinator mocks base method.
 func (m *MockStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -419,6 +508,20 @@ func (mr *MockStoreMockRecorder) DeleteCustomRole(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomRole", reflect.TypeOf((*MockStore)(nil).DeleteCustomRole), ctx, arg)
 }
 
+// DeleteExpiredLoginDeviceCodes mocks base method.
+func (m *MockStore) DeleteExpiredLoginDeviceCodes(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredLoginDeviceCodes", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredLoginDeviceCodes indicates an expected call of DeleteExpiredLoginDeviceCodes.
+func (mr *MockStoreMockRecorder) DeleteExpiredLoginDeviceCodes(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredLoginDeviceCodes", reflect.TypeOf((*MockStore)(nil).DeleteExpiredLoginDeviceCodes), ctx, before)
+}
+
 // DeleteExternalAuthLink mocks base method.
 func (m *MockStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	m.ctrl.T.Helper()
@@ -560,6 +663,36 @@ func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppTokensByAppAndUserID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppTokensByAppAndUserID), ctx, arg)
 }
 
+// DeleteOldAuditLogs mocks base method.
+func (m *MockStore) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldAuditLogs", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOldAuditLogs indicates an expected call of DeleteOldAuditLogs.
+func (mr *MockStoreMockRecorder) DeleteOldAuditLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldAuditLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldAuditLogs), ctx, arg)
+}
+
+// DeleteOldDeletedWorkspaces mocks base method.
+func (m *MockStore) DeleteOldDeletedWorkspaces(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldDeletedWorkspaces", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOldDeletedWorkspaces indicates an expected call of DeleteOldDeletedWorkspaces.
+func (mr *MockStoreMockRecorder) DeleteOldDeletedWorkspaces(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldDeletedWorkspaces", reflect.TypeOf((*MockStore)(nil).DeleteOldDeletedWorkspaces), ctx, before)
+}
+
 // DeleteOldNotificationMessages mocks base method.
 func (m *MockStore) DeleteOldNotificationMessages(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -588,32 +721,119 @@ func (mr *MockStoreMockRecorder) DeleteOldProvisionerDaemons(ctx any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldProvisionerDaemons", reflect.TypeOf((*MockStore)(nil).DeleteOldProvisionerDaemons), ctx)
 }
 
-// DeleteOldWorkspaceAgentLogs mocks base method.
-func (m *MockStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) error {
+// DeleteOldProvisionerJobLogs mocks base method.
+func (m *MockStore) DeleteOldProvisionerJobLogs(ctx context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentLogs", ctx, threshold)
+	ret := m.ctrl.Call(m, "DeleteOldProvisionerJobLogs", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOldProvisionerJobLogs indicates an expected call of DeleteOldProvisionerJobLogs.
+func (mr *MockStoreMockRecorder) DeleteOldProvisionerJobLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldProvisionerJobLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldProvisionerJobLogs), ctx, arg)
+}
+
+// DeleteOldWorkspaceAgentConnectionQuality mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentConnectionQuality(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentConnectionQuality", ctx, beforeTime)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DeleteOldWorkspaceAgentConnectionQuality indicates an expected call of DeleteOldWorkspaceAgentConnectionQuality.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentConnectionQuality(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentConnectionQuality", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentConnectionQuality), ctx, beforeTime)
+}
+
+// DeleteOldWorkspaceAgentHealthEvents mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentHealthEvents(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentHealthEvents", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceAgentHealthEvents indicates an expected call of DeleteOldWorkspaceAgentHealthEvents.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentHealthEvents(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentHealthEvents", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentHealthEvents), ctx, beforeTime)
+}
+
+// DeleteOldWorkspaceAgentLogs mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentLogs", ctx, threshold)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteOldWorkspaceAgentLogs indicates an expected call of DeleteOldWorkspaceAgentLogs.
 func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentLogs(ctx, threshold any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentLogs), ctx, threshold)
 }
 
-// DeleteOldWorkspaceAgentStats mocks base method.
-func (m *MockStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+// DeleteOldWorkspaceAgentResourceUsage mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentResourceUsage(ctx context.Context, beforeTime time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentStats", ctx)
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentResourceUsage", ctx, beforeTime)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DeleteOldWorkspaceAgentResourceUsage indicates an expected call of DeleteOldWorkspaceAgentResourceUsage.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentResourceUsage(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentResourceUsage", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentResourceUsage), ctx, beforeTime)
+}
+
+// DeleteOldWorkspaceAgentStats mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentStats(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentStats", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteOldWorkspaceAgentStats indicates an expected call of DeleteOldWorkspaceAgentStats.
-func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(ctx any) *gomock.Call {
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(ctx, before any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), ctx, before)
+}
+
+// DeleteOldWorkspaceBuildIdempotencyKeys mocks base method.
+func (m *MockStore) DeleteOldWorkspaceBuildIdempotencyKeys(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceBuildIdempotencyKeys", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceBuildIdempotencyKeys indicates an expected call of DeleteOldWorkspaceBuildIdempotencyKeys.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceBuildIdempotencyKeys(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceBuildIdempotencyKeys", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceBuildIdempotencyKeys), ctx, before)
+}
+
+// DeleteOldWorkspaceSessionRecordings mocks base method.
+func (m *MockStore) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceSessionRecordings", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceSessionRecordings indicates an expected call of DeleteOldWorkspaceSessionRecordings.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceSessionRecordings(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceSessionRecordings", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceSessionRecordings), ctx, beforeTime)
 }
 
 // DeleteOrganizationMember mocks base method.
@@ -630,6 +850,34 @@ func (mr *MockStoreMockRecorder) DeleteOrganizationMember(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganizationMember", reflect.TypeOf((*MockStore)(nil).DeleteOrganizationMember), ctx, arg)
 }
 
+// DeleteProvisionerBuildPause mocks base method.
+func (m *MockStore) DeleteProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProvisionerBuildPause", ctx, organizationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProvisionerBuildPause indicates an expected call of DeleteProvisionerBuildPause.
+func (mr *MockStoreMockRecorder) DeleteProvisionerBuildPause(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProvisionerBuildPause", reflect.TypeOf((*MockStore)(nil).DeleteProvisionerBuildPause), ctx, organizationID)
+}
+
+// DeleteProvisionerJobLogsByJobID mocks base method.
+func (m *MockStore) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProvisionerJobLogsByJobID", ctx, jobID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProvisionerJobLogsByJobID indicates an expected call of DeleteProvisionerJobLogsByJobID.
+func (mr *MockStoreMockRecorder) DeleteProvisionerJobLogsByJobID(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProvisionerJobLogsByJobID", reflect.TypeOf((*MockStore)(nil).DeleteProvisionerJobLogsByJobID), ctx, jobID)
+}
+
 // DeleteProvisionerKey mocks base method.
 func (m *MockStore) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -644,6 +892,20 @@ func (mr *MockStoreMockRecorder) DeleteProvisionerKey(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProvisionerKey", reflect.TypeOf((*MockStore)(nil).DeleteProvisionerKey), ctx, id)
 }
 
+// DeleteProvisionerReservation mocks base method.
+func (m *MockStore) DeleteProvisionerReservation(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProvisionerReservation", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProvisionerReservation indicates an expected call of DeleteProvisionerReservation.
+func (mr *MockStoreMockRecorder) DeleteProvisionerReservation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProvisionerReservation", reflect.TypeOf((*MockStore)(nil).DeleteProvisionerReservation), ctx, id)
+}
+
 // DeleteReplicasUpdatedBefore mocks base method.
 func (m *MockStore) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
 	m.ctrl.T.Helper()
@@ -746,6 +1008,76 @@ func (mr *MockStoreMockRecorder) DeleteTailnetTunnel(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTailnetTunnel", reflect.TypeOf((*MockStore)(nil).DeleteTailnetTunnel), ctx, arg)
 }
 
+// DeleteTemplatePresetByID mocks base method.
+func (m *MockStore) DeleteTemplatePresetByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplatePresetByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplatePresetByID indicates an expected call of DeleteTemplatePresetByID.
+func (mr *MockStoreMockRecorder) DeleteTemplatePresetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplatePresetByID", reflect.TypeOf((*MockStore)(nil).DeleteTemplatePresetByID), ctx, id)
+}
+
+// DeleteTemplatePresetGroupDefaultsByPresetID mocks base method.
+func (m *MockStore) DeleteTemplatePresetGroupDefaultsByPresetID(ctx context.Context, templatePresetID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplatePresetGroupDefaultsByPresetID", ctx, templatePresetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplatePresetGroupDefaultsByPresetID indicates an expected call of DeleteTemplatePresetGroupDefaultsByPresetID.
+func (mr *MockStoreMockRecorder) DeleteTemplatePresetGroupDefaultsByPresetID(ctx, templatePresetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplatePresetGroupDefaultsByPresetID", reflect.TypeOf((*MockStore)(nil).DeleteTemplatePresetGroupDefaultsByPresetID), ctx, templatePresetID)
+}
+
+// DeleteTemplateReleaseChannel mocks base method.
+func (m *MockStore) DeleteTemplateReleaseChannel(ctx context.Context, arg database.DeleteTemplateReleaseChannelParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateReleaseChannel", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplateReleaseChannel indicates an expected call of DeleteTemplateReleaseChannel.
+func (mr *MockStoreMockRecorder) DeleteTemplateReleaseChannel(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateReleaseChannel", reflect.TypeOf((*MockStore)(nil).DeleteTemplateReleaseChannel), ctx, arg)
+}
+
+// DeleteTemplateShare mocks base method.
+func (m *MockStore) DeleteTemplateShare(ctx context.Context, arg database.DeleteTemplateShareParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateShare", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplateShare indicates an expected call of DeleteTemplateShare.
+func (mr *MockStoreMockRecorder) DeleteTemplateShare(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateShare", reflect.TypeOf((*MockStore)(nil).DeleteTemplateShare), ctx, arg)
+}
+
+// DeleteTemplateVersionRolloutByTemplateID mocks base method.
+func (m *MockStore) DeleteTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateVersionRolloutByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplateVersionRolloutByTemplateID indicates an expected call of DeleteTemplateVersionRolloutByTemplateID.
+func (mr *MockStoreMockRecorder) DeleteTemplateVersionRolloutByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateVersionRolloutByTemplateID", reflect.TypeOf((*MockStore)(nil).DeleteTemplateVersionRolloutByTemplateID), ctx, templateID)
+}
+
 // DeleteWebpushSubscriptionByUserIDAndEndpoint mocks base method.
 func (m *MockStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	m.ctrl.T.Helper()
@@ -802,6 +1134,104 @@ func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortSharesByTemplate(ctx, t
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortSharesByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortSharesByTemplate), ctx, templateID)
 }
 
+// DeleteWorkspaceBuildArchiveByBuildID mocks base method.
+func (m *MockStore) DeleteWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceBuildArchiveByBuildID", ctx, workspaceBuildID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceBuildArchiveByBuildID indicates an expected call of DeleteWorkspaceBuildArchiveByBuildID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceBuildArchiveByBuildID(ctx, workspaceBuildID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceBuildArchiveByBuildID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceBuildArchiveByBuildID), ctx, workspaceBuildID)
+}
+
+// DeleteWorkspaceBuildInterimState mocks base method.
+func (m *MockStore) DeleteWorkspaceBuildInterimState(ctx context.Context, workspaceBuildID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceBuildInterimState", ctx, workspaceBuildID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceBuildInterimState indicates an expected call of DeleteWorkspaceBuildInterimState.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceBuildInterimState(ctx, workspaceBuildID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceBuildInterimState", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceBuildInterimState), ctx, workspaceBuildID)
+}
+
+// DeleteWorkspaceBuildQueueEntry mocks base method.
+func (m *MockStore) DeleteWorkspaceBuildQueueEntry(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceBuildQueueEntry", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceBuildQueueEntry indicates an expected call of DeleteWorkspaceBuildQueueEntry.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceBuildQueueEntry(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceBuildQueueEntry", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceBuildQueueEntry), ctx, id)
+}
+
+// DeleteWorkspaceLabelsByWorkspaceID mocks base method.
+func (m *MockStore) DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceLabelsByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceLabelsByWorkspaceID indicates an expected call of DeleteWorkspaceLabelsByWorkspaceID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceLabelsByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceLabelsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceLabelsByWorkspaceID), ctx, workspaceID)
+}
+
+// DeleteWorkspacePrebuildReservationByWorkspaceID mocks base method.
+func (m *MockStore) DeleteWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePrebuildReservationByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspacePrebuildReservationByWorkspaceID indicates an expected call of DeleteWorkspacePrebuildReservationByWorkspaceID.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePrebuildReservationByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePrebuildReservationByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePrebuildReservationByWorkspaceID), ctx, workspaceID)
+}
+
+// DeleteWorkspaceReleaseChannelByWorkspaceID mocks base method.
+func (m *MockStore) DeleteWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceReleaseChannelByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceReleaseChannelByWorkspaceID indicates an expected call of DeleteWorkspaceReleaseChannelByWorkspaceID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceReleaseChannelByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceReleaseChannelByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceReleaseChannelByWorkspaceID), ctx, workspaceID)
+}
+
+// DeleteWorkspaceResourceMetadataByResourceIDs mocks base method.
+func (m *MockStore) DeleteWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceResourceMetadataByResourceIDs", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceResourceMetadataByResourceIDs indicates an expected call of DeleteWorkspaceResourceMetadataByResourceIDs.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceResourceMetadataByResourceIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceResourceMetadataByResourceIDs", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceResourceMetadataByResourceIDs), ctx, ids)
+}
+
 // DeleteWorkspaceSubAgentByID mocks base method.
 func (m *MockStore) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -830,6 +1260,36 @@ func (mr *MockStoreMockRecorder) DisableForeignKeysAndTriggers(ctx any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableForeignKeysAndTriggers", reflect.TypeOf((*MockStore)(nil).DisableForeignKeysAndTriggers), ctx)
 }
 
+// DropOldAuditLogsPartitions mocks base method.
+func (m *MockStore) DropOldAuditLogsPartitions(ctx context.Context, before time.Time) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropOldAuditLogsPartitions", ctx, before)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DropOldAuditLogsPartitions indicates an expected call of DropOldAuditLogsPartitions.
+func (mr *MockStoreMockRecorder) DropOldAuditLogsPartitions(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropOldAuditLogsPartitions", reflect.TypeOf((*MockStore)(nil).DropOldAuditLogsPartitions), ctx, before)
+}
+
+// DropOldWorkspaceAgentStatsPartitions mocks base method.
+func (m *MockStore) DropOldWorkspaceAgentStatsPartitions(ctx context.Context, before time.Time) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropOldWorkspaceAgentStatsPartitions", ctx, before)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DropOldWorkspaceAgentStatsPartitions indicates an expected call of DropOldWorkspaceAgentStatsPartitions.
+func (mr *MockStoreMockRecorder) DropOldWorkspaceAgentStatsPartitions(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropOldWorkspaceAgentStatsPartitions", reflect.TypeOf((*MockStore)(nil).DropOldWorkspaceAgentStatsPartitions), ctx, before)
+}
+
 // EnqueueNotificationMessage mocks base method.
 func (m *MockStore) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error {
 	m.ctrl.T.Helper()
@@ -1008,6 +1468,36 @@ func (mr *MockStoreMockRecorder) GetAPIKeysLastUsedAfter(ctx, lastUsed any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeysLastUsedAfter", reflect.TypeOf((*MockStore)(nil).GetAPIKeysLastUsedAfter), ctx, lastUsed)
 }
 
+// GetAccessRequestByID mocks base method.
+func (m *MockStore) GetAccessRequestByID(ctx context.Context, id uuid.UUID) (database.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessRequestByID", ctx, id)
+	ret0, _ := ret[0].(database.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessRequestByID indicates an expected call of GetAccessRequestByID.
+func (mr *MockStoreMockRecorder) GetAccessRequestByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessRequestByID", reflect.TypeOf((*MockStore)(nil).GetAccessRequestByID), ctx, id)
+}
+
+// GetAccessRequests mocks base method.
+func (m *MockStore) GetAccessRequests(ctx context.Context, arg database.GetAccessRequestsParams) ([]database.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessRequests", ctx, arg)
+	ret0, _ := ret[0].([]database.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessRequests indicates an expected call of GetAccessRequests.
+func (mr *MockStoreMockRecorder) GetAccessRequests(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessRequests", reflect.TypeOf((*MockStore)(nil).GetAccessRequests), ctx, arg)
+}
+
 // GetActivePresetPrebuildSchedules mocks base method.
 func (m *MockStore) GetActivePresetPrebuildSchedules(ctx context.Context) ([]database.TemplateVersionPresetPrebuildSchedule, error) {
 	m.ctrl.T.Helper()
@@ -1053,6 +1543,36 @@ func (mr *MockStoreMockRecorder) GetActiveWorkspaceBuildsByTemplateID(ctx, templ
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveWorkspaceBuildsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetActiveWorkspaceBuildsByTemplateID), ctx, templateID)
 }
 
+// GetActiveWorkspaceLockByWorkspaceID mocks base method.
+func (m *MockStore) GetActiveWorkspaceLockByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveWorkspaceLockByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveWorkspaceLockByWorkspaceID indicates an expected call of GetActiveWorkspaceLockByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetActiveWorkspaceLockByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveWorkspaceLockByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetActiveWorkspaceLockByWorkspaceID), ctx, workspaceID)
+}
+
+// GetActiveWorkspaceMigrations mocks base method.
+func (m *MockStore) GetActiveWorkspaceMigrations(ctx context.Context) ([]database.WorkspaceMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveWorkspaceMigrations", ctx)
+	ret0, _ := ret[0].([]database.WorkspaceMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveWorkspaceMigrations indicates an expected call of GetActiveWorkspaceMigrations.
+func (mr *MockStoreMockRecorder) GetActiveWorkspaceMigrations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveWorkspaceMigrations", reflect.TypeOf((*MockStore)(nil).GetActiveWorkspaceMigrations), ctx)
+}
+
 // GetAllTailnetAgents mocks base method.
 func (m *MockStore) GetAllTailnetAgents(ctx context.Context) ([]database.TailnetAgent, error) {
 	m.ctrl.T.Helper()
@@ -1158,6 +1678,21 @@ func (mr *MockStoreMockRecorder) GetApplicationName(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationName", reflect.TypeOf((*MockStore)(nil).GetApplicationName), ctx)
 }
 
+// GetAuditLogsForExport mocks base method.
+func (m *MockStore) GetAuditLogsForExport(ctx context.Context, arg database.GetAuditLogsForExportParams) ([]database.GetAuditLogsForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogsForExport", ctx, arg)
+	ret0, _ := ret[0].([]database.GetAuditLogsForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogsForExport indicates an expected call of GetAuditLogsForExport.
+func (mr *MockStoreMockRecorder) GetAuditLogsForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsForExport", reflect.TypeOf((*MockStore)(nil).GetAuditLogsForExport), ctx, arg)
+}
+
 // GetAuditLogsOffset mocks base method.
 func (m *MockStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	m.ctrl.T.Helper()
@@ -1278,6 +1813,81 @@ func (mr *MockStoreMockRecorder) GetAuthorizedWorkspacesAndAgentsByOwnerID(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizedWorkspacesAndAgentsByOwnerID", reflect.TypeOf((*MockStore)(nil).GetAuthorizedWorkspacesAndAgentsByOwnerID), ctx, ownerID, prepared)
 }
 
+// GetBatchedNotificationMessagesByUserID mocks base method.
+func (m *MockStore) GetBatchedNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]database.GetBatchedNotificationMessagesByUserIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatchedNotificationMessagesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]database.GetBatchedNotificationMessagesByUserIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatchedNotificationMessagesByUserID indicates an expected call of GetBatchedNotificationMessagesByUserID.
+func (mr *MockStoreMockRecorder) GetBatchedNotificationMessagesByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatchedNotificationMessagesByUserID", reflect.TypeOf((*MockStore)(nil).GetBatchedNotificationMessagesByUserID), ctx, userID)
+}
+
+// GetBuildAlertRuleByID mocks base method.
+func (m *MockStore) GetBuildAlertRuleByID(ctx context.Context, id uuid.UUID) (database.BuildAlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuildAlertRuleByID", ctx, id)
+	ret0, _ := ret[0].(database.BuildAlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuildAlertRuleByID indicates an expected call of GetBuildAlertRuleByID.
+func (mr *MockStoreMockRecorder) GetBuildAlertRuleByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuildAlertRuleByID", reflect.TypeOf((*MockStore)(nil).GetBuildAlertRuleByID), ctx, id)
+}
+
+// GetBuildAlertRuleMetrics mocks base method.
+func (m *MockStore) GetBuildAlertRuleMetrics(ctx context.Context, arg database.GetBuildAlertRuleMetricsParams) (database.GetBuildAlertRuleMetricsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuildAlertRuleMetrics", ctx, arg)
+	ret0, _ := ret[0].(database.GetBuildAlertRuleMetricsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuildAlertRuleMetrics indicates an expected call of GetBuildAlertRuleMetrics.
+func (mr *MockStoreMockRecorder) GetBuildAlertRuleMetrics(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuildAlertRuleMetrics", reflect.TypeOf((*MockStore)(nil).GetBuildAlertRuleMetrics), ctx, arg)
+}
+
+// GetBuildAlertRules mocks base method.
+func (m *MockStore) GetBuildAlertRules(ctx context.Context) ([]database.BuildAlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuildAlertRules", ctx)
+	ret0, _ := ret[0].([]database.BuildAlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuildAlertRules indicates an expected call of GetBuildAlertRules.
+func (mr *MockStoreMockRecorder) GetBuildAlertRules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuildAlertRules", reflect.TypeOf((*MockStore)(nil).GetBuildAlertRules), ctx)
+}
+
+// GetBuildAlertRulesByOrganizationID mocks base method.
+func (m *MockStore) GetBuildAlertRulesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.BuildAlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuildAlertRulesByOrganizationID", ctx, organizationID)
+	ret0, _ := ret[0].([]database.BuildAlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuildAlertRulesByOrganizationID indicates an expected call of GetBuildAlertRulesByOrganizationID.
+func (mr *MockStoreMockRecorder) GetBuildAlertRulesByOrganizationID(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuildAlertRulesByOrganizationID", reflect.TypeOf((*MockStore)(nil).GetBuildAlertRulesByOrganizationID), ctx, organizationID)
+}
+
 // GetCoordinatorResumeTokenSigningKey mocks base method.
 func (m *MockStore) GetCoordinatorResumeTokenSigningKey(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1353,6 +1963,21 @@ func (mr *MockStoreMockRecorder) GetDBCryptKeys(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDBCryptKeys", reflect.TypeOf((*MockStore)(nil).GetDBCryptKeys), ctx)
 }
 
+// GetDBPurgeStats mocks base method.
+func (m *MockStore) GetDBPurgeStats(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDBPurgeStats", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDBPurgeStats indicates an expected call of GetDBPurgeStats.
+func (mr *MockStoreMockRecorder) GetDBPurgeStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDBPurgeStats", reflect.TypeOf((*MockStore)(nil).GetDBPurgeStats), ctx)
+}
+
 // GetDERPMeshKey mocks base method.
 func (m *MockStore) GetDERPMeshKey(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1398,6 +2023,21 @@ func (mr *MockStoreMockRecorder) GetDefaultProxyConfig(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultProxyConfig", reflect.TypeOf((*MockStore)(nil).GetDefaultProxyConfig), ctx)
 }
 
+// GetDefaultTemplatePresetForUser mocks base method.
+func (m *MockStore) GetDefaultTemplatePresetForUser(ctx context.Context, arg database.GetDefaultTemplatePresetForUserParams) (database.TemplatePreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultTemplatePresetForUser", ctx, arg)
+	ret0, _ := ret[0].(database.TemplatePreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefaultTemplatePresetForUser indicates an expected call of GetDefaultTemplatePresetForUser.
+func (mr *MockStoreMockRecorder) GetDefaultTemplatePresetForUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultTemplatePresetForUser", reflect.TypeOf((*MockStore)(nil).GetDefaultTemplatePresetForUser), ctx, arg)
+}
+
 // GetDeploymentDAUs mocks base method.
 func (m *MockStore) GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]database.GetDeploymentDAUsRow, error) {
 	m.ctrl.T.Helper()
@@ -1488,6 +2128,36 @@ func (mr *MockStoreMockRecorder) GetEligibleProvisionerDaemonsByProvisionerJobID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEligibleProvisionerDaemonsByProvisionerJobIDs", reflect.TypeOf((*MockStore)(nil).GetEligibleProvisionerDaemonsByProvisionerJobIDs), ctx, provisionerJobIds)
 }
 
+// GetEncryptedWorkspaceSessionRecordingChunks mocks base method.
+func (m *MockStore) GetEncryptedWorkspaceSessionRecordingChunks(ctx context.Context) ([]database.WorkspaceSessionRecordingChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEncryptedWorkspaceSessionRecordingChunks", ctx)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecordingChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEncryptedWorkspaceSessionRecordingChunks indicates an expected call of GetEncryptedWorkspaceSessionRecordingChunks.
+func (mr *MockStoreMockRecorder) GetEncryptedWorkspaceSessionRecordingChunks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEncryptedWorkspaceSessionRecordingChunks", reflect.TypeOf((*MockStore)(nil).GetEncryptedWorkspaceSessionRecordingChunks), ctx)
+}
+
+// GetExpiredAccessRequests mocks base method.
+func (m *MockStore) GetExpiredAccessRequests(ctx context.Context, now time.Time) ([]database.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredAccessRequests", ctx, now)
+	ret0, _ := ret[0].([]database.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredAccessRequests indicates an expected call of GetExpiredAccessRequests.
+func (mr *MockStoreMockRecorder) GetExpiredAccessRequests(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredAccessRequests", reflect.TypeOf((*MockStore)(nil).GetExpiredAccessRequests), ctx, now)
+}
+
 // GetExternalAuthLink mocks base method.
 func (m *MockStore) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -1758,6 +2428,21 @@ func (mr *MockStoreMockRecorder) GetInboxNotificationsByUserID(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboxNotificationsByUserID", reflect.TypeOf((*MockStore)(nil).GetInboxNotificationsByUserID), ctx, arg)
 }
 
+// GetJobReaperLastRunAt mocks base method.
+func (m *MockStore) GetJobReaperLastRunAt(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobReaperLastRunAt", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobReaperLastRunAt indicates an expected call of GetJobReaperLastRunAt.
+func (mr *MockStoreMockRecorder) GetJobReaperLastRunAt(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobReaperLastRunAt", reflect.TypeOf((*MockStore)(nil).GetJobReaperLastRunAt), ctx)
+}
+
 // GetLastUpdateCheck mocks base method.
 func (m *MockStore) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1788,6 +2473,21 @@ func (mr *MockStoreMockRecorder) GetLatestCryptoKeyByFeature(ctx, feature any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestCryptoKeyByFeature", reflect.TypeOf((*MockStore)(nil).GetLatestCryptoKeyByFeature), ctx, feature)
 }
 
+// GetLatestSuccessfulWorkspaceBuildByWorkspaceID mocks base method.
+func (m *MockStore) GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestSuccessfulWorkspaceBuildByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceBuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestSuccessfulWorkspaceBuildByWorkspaceID indicates an expected call of GetLatestSuccessfulWorkspaceBuildByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestSuccessfulWorkspaceBuildByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetLatestSuccessfulWorkspaceBuildByWorkspaceID), ctx, workspaceID)
+}
+
 // GetLatestWorkspaceAppStatusesByWorkspaceIDs mocks base method.
 func (m *MockStore) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAppStatus, error) {
 	m.ctrl.T.Helper()
@@ -1878,6 +2578,36 @@ func (mr *MockStoreMockRecorder) GetLicenses(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenses", reflect.TypeOf((*MockStore)(nil).GetLicenses), ctx)
 }
 
+// GetLoginDeviceCodeByDeviceCodeHash mocks base method.
+func (m *MockStore) GetLoginDeviceCodeByDeviceCodeHash(ctx context.Context, deviceCodeHash []byte) (database.LoginDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginDeviceCodeByDeviceCodeHash", ctx, deviceCodeHash)
+	ret0, _ := ret[0].(database.LoginDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginDeviceCodeByDeviceCodeHash indicates an expected call of GetLoginDeviceCodeByDeviceCodeHash.
+func (mr *MockStoreMockRecorder) GetLoginDeviceCodeByDeviceCodeHash(ctx, deviceCodeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginDeviceCodeByDeviceCodeHash", reflect.TypeOf((*MockStore)(nil).GetLoginDeviceCodeByDeviceCodeHash), ctx, deviceCodeHash)
+}
+
+// GetLoginDeviceCodeByUserCode mocks base method.
+func (m *MockStore) GetLoginDeviceCodeByUserCode(ctx context.Context, userCode string) (database.LoginDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginDeviceCodeByUserCode", ctx, userCode)
+	ret0, _ := ret[0].(database.LoginDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginDeviceCodeByUserCode indicates an expected call of GetLoginDeviceCodeByUserCode.
+func (mr *MockStoreMockRecorder) GetLoginDeviceCodeByUserCode(ctx, userCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginDeviceCodeByUserCode", reflect.TypeOf((*MockStore)(nil).GetLoginDeviceCodeByUserCode), ctx, userCode)
+}
+
 // GetLogoURL mocks base method.
 func (m *MockStore) GetLogoURL(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -2133,6 +2863,21 @@ func (mr *MockStoreMockRecorder) GetOAuthSigningKey(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthSigningKey", reflect.TypeOf((*MockStore)(nil).GetOAuthSigningKey), ctx)
 }
 
+// GetOIDCUserClaims mocks base method.
+func (m *MockStore) GetOIDCUserClaims(ctx context.Context, organizationID uuid.UUID) ([]database.GetOIDCUserClaimsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOIDCUserClaims", ctx, organizationID)
+	ret0, _ := ret[0].([]database.GetOIDCUserClaimsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOIDCUserClaims indicates an expected call of GetOIDCUserClaims.
+func (mr *MockStoreMockRecorder) GetOIDCUserClaims(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOIDCUserClaims", reflect.TypeOf((*MockStore)(nil).GetOIDCUserClaims), ctx, organizationID)
+}
+
 // GetOrganizationByID mocks base method.
 func (m *MockStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -2193,6 +2938,21 @@ func (mr *MockStoreMockRecorder) GetOrganizationResourceCountByID(ctx, organizat
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationResourceCountByID", reflect.TypeOf((*MockStore)(nil).GetOrganizationResourceCountByID), ctx, organizationID)
 }
 
+// GetOrganizationWorkspaceNamingPolicy mocks base method.
+func (m *MockStore) GetOrganizationWorkspaceNamingPolicy(ctx context.Context, organizationID uuid.UUID) (database.WorkspaceNamingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationWorkspaceNamingPolicy", ctx, organizationID)
+	ret0, _ := ret[0].(database.WorkspaceNamingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationWorkspaceNamingPolicy indicates an expected call of GetOrganizationWorkspaceNamingPolicy.
+func (mr *MockStoreMockRecorder) GetOrganizationWorkspaceNamingPolicy(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationWorkspaceNamingPolicy", reflect.TypeOf((*MockStore)(nil).GetOrganizationWorkspaceNamingPolicy), ctx, organizationID)
+}
+
 // GetOrganizations mocks base method.
 func (m *MockStore) GetOrganizations(ctx context.Context, arg database.GetOrganizationsParams) ([]database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -2202,40 +2962,85 @@ func (m *MockStore) GetOrganizations(ctx context.Context, arg database.GetOrgani
 	return ret0, ret1
 }
 
-// GetOrganizations indicates an expected call of GetOrganizations.
-func (mr *MockStoreMockRecorder) GetOrganizations(ctx, arg any) *gomock.Call {
+// GetOrganizations indicates an expected call of GetOrganizations.
+func (mr *MockStoreMockRecorder) GetOrganizations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizations", reflect.TypeOf((*MockStore)(nil).GetOrganizations), ctx, arg)
+}
+
+// GetOrganizationsByUserID mocks base method.
+func (m *MockStore) GetOrganizationsByUserID(ctx context.Context, arg database.GetOrganizationsByUserIDParams) ([]database.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationsByUserID", ctx, arg)
+	ret0, _ := ret[0].([]database.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationsByUserID indicates an expected call of GetOrganizationsByUserID.
+func (mr *MockStoreMockRecorder) GetOrganizationsByUserID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationsByUserID), ctx, arg)
+}
+
+// GetParameterSchemasByJobID mocks base method.
+func (m *MockStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParameterSchemasByJobID", ctx, jobID)
+	ret0, _ := ret[0].([]database.ParameterSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParameterSchemasByJobID indicates an expected call of GetParameterSchemasByJobID.
+func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), ctx, jobID)
+}
+
+// GetPendingProvisionerJobsQueueDepthByTags mocks base method.
+func (m *MockStore) GetPendingProvisionerJobsQueueDepthByTags(ctx context.Context) ([]database.GetPendingProvisionerJobsQueueDepthByTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingProvisionerJobsQueueDepthByTags", ctx)
+	ret0, _ := ret[0].([]database.GetPendingProvisionerJobsQueueDepthByTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingProvisionerJobsQueueDepthByTags indicates an expected call of GetPendingProvisionerJobsQueueDepthByTags.
+func (mr *MockStoreMockRecorder) GetPendingProvisionerJobsQueueDepthByTags(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizations", reflect.TypeOf((*MockStore)(nil).GetOrganizations), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingProvisionerJobsQueueDepthByTags", reflect.TypeOf((*MockStore)(nil).GetPendingProvisionerJobsQueueDepthByTags), ctx)
 }
 
-// GetOrganizationsByUserID mocks base method.
-func (m *MockStore) GetOrganizationsByUserID(ctx context.Context, arg database.GetOrganizationsByUserIDParams) ([]database.Organization, error) {
+// GetPendingUserActivityExports mocks base method.
+func (m *MockStore) GetPendingUserActivityExports(ctx context.Context) ([]database.UserActivityExport, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizationsByUserID", ctx, arg)
-	ret0, _ := ret[0].([]database.Organization)
+	ret := m.ctrl.Call(m, "GetPendingUserActivityExports", ctx)
+	ret0, _ := ret[0].([]database.UserActivityExport)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizationsByUserID indicates an expected call of GetOrganizationsByUserID.
-func (mr *MockStoreMockRecorder) GetOrganizationsByUserID(ctx, arg any) *gomock.Call {
+// GetPendingUserActivityExports indicates an expected call of GetPendingUserActivityExports.
+func (mr *MockStoreMockRecorder) GetPendingUserActivityExports(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationsByUserID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingUserActivityExports", reflect.TypeOf((*MockStore)(nil).GetPendingUserActivityExports), ctx)
 }
 
-// GetParameterSchemasByJobID mocks base method.
-func (m *MockStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
+// GetPrebuildClaimDemand mocks base method.
+func (m *MockStore) GetPrebuildClaimDemand(ctx context.Context, since time.Time) ([]database.GetPrebuildClaimDemandRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetParameterSchemasByJobID", ctx, jobID)
-	ret0, _ := ret[0].([]database.ParameterSchema)
+	ret := m.ctrl.Call(m, "GetPrebuildClaimDemand", ctx, since)
+	ret0, _ := ret[0].([]database.GetPrebuildClaimDemandRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetParameterSchemasByJobID indicates an expected call of GetParameterSchemasByJobID.
-func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(ctx, jobID any) *gomock.Call {
+// GetPrebuildClaimDemand indicates an expected call of GetPrebuildClaimDemand.
+func (mr *MockStoreMockRecorder) GetPrebuildClaimDemand(ctx, since any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), ctx, jobID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrebuildClaimDemand", reflect.TypeOf((*MockStore)(nil).GetPrebuildClaimDemand), ctx, since)
 }
 
 // GetPrebuildMetrics mocks base method.
@@ -2373,6 +3178,21 @@ func (mr *MockStoreMockRecorder) GetPreviousTemplateVersion(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviousTemplateVersion", reflect.TypeOf((*MockStore)(nil).GetPreviousTemplateVersion), ctx, arg)
 }
 
+// GetProvisionerBuildPause mocks base method.
+func (m *MockStore) GetProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) (database.ProvisionerBuildPause, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerBuildPause", ctx, organizationID)
+	ret0, _ := ret[0].(database.ProvisionerBuildPause)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerBuildPause indicates an expected call of GetProvisionerBuildPause.
+func (mr *MockStoreMockRecorder) GetProvisionerBuildPause(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerBuildPause", reflect.TypeOf((*MockStore)(nil).GetProvisionerBuildPause), ctx, organizationID)
+}
+
 // GetProvisionerDaemons mocks base method.
 func (m *MockStore) GetProvisionerDaemons(ctx context.Context) ([]database.ProvisionerDaemon, error) {
 	m.ctrl.T.Helper()
@@ -2448,6 +3268,36 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobByIDForUpdate(ctx, id any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobByIDForUpdate), ctx, id)
 }
 
+// GetProvisionerJobLogArchiveByJobID mocks base method.
+func (m *MockStore) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobLogArchiveByJobID", ctx, jobID)
+	ret0, _ := ret[0].(database.ProvisionerJobLogArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobLogArchiveByJobID indicates an expected call of GetProvisionerJobLogArchiveByJobID.
+func (mr *MockStoreMockRecorder) GetProvisionerJobLogArchiveByJobID(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobLogArchiveByJobID", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobLogArchiveByJobID), ctx, jobID)
+}
+
+// GetProvisionerJobThroughputByTags mocks base method.
+func (m *MockStore) GetProvisionerJobThroughputByTags(ctx context.Context, arg database.GetProvisionerJobThroughputByTagsParams) ([]database.GetProvisionerJobThroughputByTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobThroughputByTags", ctx, arg)
+	ret0, _ := ret[0].([]database.GetProvisionerJobThroughputByTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobThroughputByTags indicates an expected call of GetProvisionerJobThroughputByTags.
+func (mr *MockStoreMockRecorder) GetProvisionerJobThroughputByTags(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobThroughputByTags", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobThroughputByTags), ctx, arg)
+}
+
 // GetProvisionerJobTimingsByJobID mocks base method.
 func (m *MockStore) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	m.ctrl.T.Helper()
@@ -2523,6 +3373,36 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobsCreatedAfter(ctx, createdAt a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsCreatedAfter), ctx, createdAt)
 }
 
+// GetProvisionerJobsRunningBetween mocks base method.
+func (m *MockStore) GetProvisionerJobsRunningBetween(ctx context.Context, arg database.GetProvisionerJobsRunningBetweenParams) ([]database.GetProvisionerJobsRunningBetweenRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobsRunningBetween", ctx, arg)
+	ret0, _ := ret[0].([]database.GetProvisionerJobsRunningBetweenRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobsRunningBetween indicates an expected call of GetProvisionerJobsRunningBetween.
+func (mr *MockStoreMockRecorder) GetProvisionerJobsRunningBetween(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsRunningBetween", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsRunningBetween), ctx, arg)
+}
+
+// GetProvisionerJobsToArchiveLogs mocks base method.
+func (m *MockStore) GetProvisionerJobsToArchiveLogs(ctx context.Context, arg database.GetProvisionerJobsToArchiveLogsParams) ([]database.GetProvisionerJobsToArchiveLogsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobsToArchiveLogs", ctx, arg)
+	ret0, _ := ret[0].([]database.GetProvisionerJobsToArchiveLogsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobsToArchiveLogs indicates an expected call of GetProvisionerJobsToArchiveLogs.
+func (mr *MockStoreMockRecorder) GetProvisionerJobsToArchiveLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsToArchiveLogs", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsToArchiveLogs), ctx, arg)
+}
+
 // GetProvisionerJobsToBeReaped mocks base method.
 func (m *MockStore) GetProvisionerJobsToBeReaped(ctx context.Context, arg database.GetProvisionerJobsToBeReapedParams) ([]database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -2598,6 +3478,36 @@ func (mr *MockStoreMockRecorder) GetProvisionerLogsAfterID(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerLogsAfterID", reflect.TypeOf((*MockStore)(nil).GetProvisionerLogsAfterID), ctx, arg)
 }
 
+// GetProvisionerReservationByID mocks base method.
+func (m *MockStore) GetProvisionerReservationByID(ctx context.Context, id uuid.UUID) (database.ProvisionerReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerReservationByID", ctx, id)
+	ret0, _ := ret[0].(database.ProvisionerReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerReservationByID indicates an expected call of GetProvisionerReservationByID.
+func (mr *MockStoreMockRecorder) GetProvisionerReservationByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerReservationByID", reflect.TypeOf((*MockStore)(nil).GetProvisionerReservationByID), ctx, id)
+}
+
+// GetProvisionerReservationsByOrganization mocks base method.
+func (m *MockStore) GetProvisionerReservationsByOrganization(ctx context.Context, arg database.GetProvisionerReservationsByOrganizationParams) ([]database.ProvisionerReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerReservationsByOrganization", ctx, arg)
+	ret0, _ := ret[0].([]database.ProvisionerReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerReservationsByOrganization indicates an expected call of GetProvisionerReservationsByOrganization.
+func (mr *MockStoreMockRecorder) GetProvisionerReservationsByOrganization(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerReservationsByOrganization", reflect.TypeOf((*MockStore)(nil).GetProvisionerReservationsByOrganization), ctx, arg)
+}
+
 // GetQuotaAllowanceForUser mocks base method.
 func (m *MockStore) GetQuotaAllowanceForUser(ctx context.Context, arg database.GetQuotaAllowanceForUserParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -2628,6 +3538,51 @@ func (mr *MockStoreMockRecorder) GetQuotaConsumedForUser(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaConsumedForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaConsumedForUser), ctx, arg)
 }
 
+// GetQuotaMonthlyBudgetForUser mocks base method.
+func (m *MockStore) GetQuotaMonthlyBudgetForUser(ctx context.Context, arg database.GetQuotaMonthlyBudgetForUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaMonthlyBudgetForUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaMonthlyBudgetForUser indicates an expected call of GetQuotaMonthlyBudgetForUser.
+func (mr *MockStoreMockRecorder) GetQuotaMonthlyBudgetForUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaMonthlyBudgetForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaMonthlyBudgetForUser), ctx, arg)
+}
+
+// GetQuotaMonthlySpendForUser mocks base method.
+func (m *MockStore) GetQuotaMonthlySpendForUser(ctx context.Context, arg database.GetQuotaMonthlySpendForUserParams) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaMonthlySpendForUser", ctx, arg)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaMonthlySpendForUser indicates an expected call of GetQuotaMonthlySpendForUser.
+func (mr *MockStoreMockRecorder) GetQuotaMonthlySpendForUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaMonthlySpendForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaMonthlySpendForUser), ctx, arg)
+}
+
+// GetReadOnlySettings mocks base method.
+func (m *MockStore) GetReadOnlySettings(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadOnlySettings", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadOnlySettings indicates an expected call of GetReadOnlySettings.
+func (mr *MockStoreMockRecorder) GetReadOnlySettings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadOnlySettings", reflect.TypeOf((*MockStore)(nil).GetReadOnlySettings), ctx)
+}
+
 // GetReplicaByID mocks base method.
 func (m *MockStore) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -2673,6 +3628,21 @@ func (mr *MockStoreMockRecorder) GetRunningPrebuiltWorkspaces(ctx any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningPrebuiltWorkspaces", reflect.TypeOf((*MockStore)(nil).GetRunningPrebuiltWorkspaces), ctx)
 }
 
+// GetRunningWorkspaceBuildsExceedingDurationThreshold mocks base method.
+func (m *MockStore) GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx context.Context, now time.Time) ([]database.GetRunningWorkspaceBuildsExceedingDurationThresholdRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunningWorkspaceBuildsExceedingDurationThreshold", ctx, now)
+	ret0, _ := ret[0].([]database.GetRunningWorkspaceBuildsExceedingDurationThresholdRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunningWorkspaceBuildsExceedingDurationThreshold indicates an expected call of GetRunningWorkspaceBuildsExceedingDurationThreshold.
+func (mr *MockStoreMockRecorder) GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningWorkspaceBuildsExceedingDurationThreshold", reflect.TypeOf((*MockStore)(nil).GetRunningWorkspaceBuildsExceedingDurationThreshold), ctx, now)
+}
+
 // GetRuntimeConfig mocks base method.
 func (m *MockStore) GetRuntimeConfig(ctx context.Context, key string) (string, error) {
 	m.ctrl.T.Helper()
@@ -2688,6 +3658,66 @@ func (mr *MockStoreMockRecorder) GetRuntimeConfig(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeConfig", reflect.TypeOf((*MockStore)(nil).GetRuntimeConfig), ctx, key)
 }
 
+// GetSensitiveTemplateVersionVariables mocks base method.
+func (m *MockStore) GetSensitiveTemplateVersionVariables(ctx context.Context) ([]database.TemplateVersionVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSensitiveTemplateVersionVariables", ctx)
+	ret0, _ := ret[0].([]database.TemplateVersionVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSensitiveTemplateVersionVariables indicates an expected call of GetSensitiveTemplateVersionVariables.
+func (mr *MockStoreMockRecorder) GetSensitiveTemplateVersionVariables(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSensitiveTemplateVersionVariables", reflect.TypeOf((*MockStore)(nil).GetSensitiveTemplateVersionVariables), ctx)
+}
+
+// GetServiceAccountByUserID mocks base method.
+func (m *MockStore) GetServiceAccountByUserID(ctx context.Context, userID uuid.UUID) (database.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceAccountByUserID", ctx, userID)
+	ret0, _ := ret[0].(database.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceAccountByUserID indicates an expected call of GetServiceAccountByUserID.
+func (mr *MockStoreMockRecorder) GetServiceAccountByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccountByUserID", reflect.TypeOf((*MockStore)(nil).GetServiceAccountByUserID), ctx, userID)
+}
+
+// GetServiceAccountsByOrganizationID mocks base method.
+func (m *MockStore) GetServiceAccountsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceAccountsByOrganizationID", ctx, organizationID)
+	ret0, _ := ret[0].([]database.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceAccountsByOrganizationID indicates an expected call of GetServiceAccountsByOrganizationID.
+func (mr *MockStoreMockRecorder) GetServiceAccountsByOrganizationID(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccountsByOrganizationID", reflect.TypeOf((*MockStore)(nil).GetServiceAccountsByOrganizationID), ctx, organizationID)
+}
+
+// GetStartableWorkspaceBuildQueueEntries mocks base method.
+func (m *MockStore) GetStartableWorkspaceBuildQueueEntries(ctx context.Context) ([]database.WorkspaceBuildQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStartableWorkspaceBuildQueueEntries", ctx)
+	ret0, _ := ret[0].([]database.WorkspaceBuildQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStartableWorkspaceBuildQueueEntries indicates an expected call of GetStartableWorkspaceBuildQueueEntries.
+func (mr *MockStoreMockRecorder) GetStartableWorkspaceBuildQueueEntries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStartableWorkspaceBuildQueueEntries", reflect.TypeOf((*MockStore)(nil).GetStartableWorkspaceBuildQueueEntries), ctx)
+}
+
 // GetTailnetAgents mocks base method.
 func (m *MockStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	m.ctrl.T.Helper()
@@ -2838,6 +3868,21 @@ func (mr *MockStoreMockRecorder) GetTemplateAverageBuildTime(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAverageBuildTime", reflect.TypeOf((*MockStore)(nil).GetTemplateAverageBuildTime), ctx, arg)
 }
 
+// GetTemplateBuildDurationStats mocks base method.
+func (m *MockStore) GetTemplateBuildDurationStats(ctx context.Context, arg database.GetTemplateBuildDurationStatsParams) ([]database.TemplateBuildDurationStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBuildDurationStats", ctx, arg)
+	ret0, _ := ret[0].([]database.TemplateBuildDurationStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBuildDurationStats indicates an expected call of GetTemplateBuildDurationStats.
+func (mr *MockStoreMockRecorder) GetTemplateBuildDurationStats(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBuildDurationStats", reflect.TypeOf((*MockStore)(nil).GetTemplateBuildDurationStats), ctx, arg)
+}
+
 // GetTemplateByID mocks base method.
 func (m *MockStore) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	m.ctrl.T.Helper()
@@ -2853,6 +3898,21 @@ func (mr *MockStoreMockRecorder) GetTemplateByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateByID", reflect.TypeOf((*MockStore)(nil).GetTemplateByID), ctx, id)
 }
 
+// GetTemplateByIDForUpdate mocks base method.
+func (m *MockStore) GetTemplateByIDForUpdate(ctx context.Context, id uuid.UUID) (database.TemplateTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateByIDForUpdate", ctx, id)
+	ret0, _ := ret[0].(database.TemplateTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateByIDForUpdate indicates an expected call of GetTemplateByIDForUpdate.
+func (mr *MockStoreMockRecorder) GetTemplateByIDForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetTemplateByIDForUpdate), ctx, id)
+}
+
 // GetTemplateByOrganizationAndName mocks base method.
 func (m *MockStore) GetTemplateByOrganizationAndName(ctx context.Context, arg database.GetTemplateByOrganizationAndNameParams) (database.Template, error) {
 	m.ctrl.T.Helper()
@@ -2958,6 +4018,51 @@ func (mr *MockStoreMockRecorder) GetTemplateParameterInsights(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateParameterInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateParameterInsights), ctx, arg)
 }
 
+// GetTemplatePresetByID mocks base method.
+func (m *MockStore) GetTemplatePresetByID(ctx context.Context, id uuid.UUID) (database.TemplatePreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplatePresetByID", ctx, id)
+	ret0, _ := ret[0].(database.TemplatePreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplatePresetByID indicates an expected call of GetTemplatePresetByID.
+func (mr *MockStoreMockRecorder) GetTemplatePresetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplatePresetByID", reflect.TypeOf((*MockStore)(nil).GetTemplatePresetByID), ctx, id)
+}
+
+// GetTemplatePresetGroupDefaultsByTemplateID mocks base method.
+func (m *MockStore) GetTemplatePresetGroupDefaultsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePresetGroupDefault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplatePresetGroupDefaultsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplatePresetGroupDefault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplatePresetGroupDefaultsByTemplateID indicates an expected call of GetTemplatePresetGroupDefaultsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplatePresetGroupDefaultsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplatePresetGroupDefaultsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplatePresetGroupDefaultsByTemplateID), ctx, templateID)
+}
+
+// GetTemplatePresetsByTemplateID mocks base method.
+func (m *MockStore) GetTemplatePresetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplatePresetsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplatePreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplatePresetsByTemplateID indicates an expected call of GetTemplatePresetsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplatePresetsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplatePresetsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplatePresetsByTemplateID), ctx, templateID)
+}
+
 // GetTemplatePresetsWithPrebuilds mocks base method.
 func (m *MockStore) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	m.ctrl.T.Helper()
@@ -2973,6 +4078,51 @@ func (mr *MockStoreMockRecorder) GetTemplatePresetsWithPrebuilds(ctx, templateID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplatePresetsWithPrebuilds", reflect.TypeOf((*MockStore)(nil).GetTemplatePresetsWithPrebuilds), ctx, templateID)
 }
 
+// GetTemplateReleaseChannel mocks base method.
+func (m *MockStore) GetTemplateReleaseChannel(ctx context.Context, arg database.GetTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateReleaseChannel", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateReleaseChannel indicates an expected call of GetTemplateReleaseChannel.
+func (mr *MockStoreMockRecorder) GetTemplateReleaseChannel(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateReleaseChannel", reflect.TypeOf((*MockStore)(nil).GetTemplateReleaseChannel), ctx, arg)
+}
+
+// GetTemplateReleaseChannelsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateReleaseChannelsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateReleaseChannelsByTemplateID indicates an expected call of GetTemplateReleaseChannelsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateReleaseChannelsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateReleaseChannelsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateReleaseChannelsByTemplateID), ctx, templateID)
+}
+
+// GetTemplateSharesByTemplateID mocks base method.
+func (m *MockStore) GetTemplateSharesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateSharesByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateSharesByTemplateID indicates an expected call of GetTemplateSharesByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateSharesByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateSharesByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateSharesByTemplateID), ctx, templateID)
+}
+
 // GetTemplateUsageStats mocks base method.
 func (m *MockStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	m.ctrl.T.Helper()
@@ -3063,6 +4213,36 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionParameters(ctx, templateVersi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionParameters", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionParameters), ctx, templateVersionID)
 }
 
+// GetTemplateVersionRolloutBuildCounts mocks base method.
+func (m *MockStore) GetTemplateVersionRolloutBuildCounts(ctx context.Context, arg database.GetTemplateVersionRolloutBuildCountsParams) ([]database.GetTemplateVersionRolloutBuildCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionRolloutBuildCounts", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateVersionRolloutBuildCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionRolloutBuildCounts indicates an expected call of GetTemplateVersionRolloutBuildCounts.
+func (mr *MockStoreMockRecorder) GetTemplateVersionRolloutBuildCounts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionRolloutBuildCounts", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionRolloutBuildCounts), ctx, arg)
+}
+
+// GetTemplateVersionRolloutByTemplateID mocks base method.
+func (m *MockStore) GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionRolloutByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionRolloutByTemplateID indicates an expected call of GetTemplateVersionRolloutByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionRolloutByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionRolloutByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionRolloutByTemplateID), ctx, templateID)
+}
+
 // GetTemplateVersionTerraformValues mocks base method.
 func (m *MockStore) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	m.ctrl.T.Helper()
@@ -3153,6 +4333,21 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionsCreatedAfter(ctx, createdAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionsCreatedAfter), ctx, createdAt)
 }
 
+// GetTemplateWorkspaceNamingPolicy mocks base method.
+func (m *MockStore) GetTemplateWorkspaceNamingPolicy(ctx context.Context, templateID uuid.UUID) (database.WorkspaceNamingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateWorkspaceNamingPolicy", ctx, templateID)
+	ret0, _ := ret[0].(database.WorkspaceNamingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateWorkspaceNamingPolicy indicates an expected call of GetTemplateWorkspaceNamingPolicy.
+func (mr *MockStoreMockRecorder) GetTemplateWorkspaceNamingPolicy(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateWorkspaceNamingPolicy", reflect.TypeOf((*MockStore)(nil).GetTemplateWorkspaceNamingPolicy), ctx, templateID)
+}
+
 // GetTemplates mocks base method.
 func (m *MockStore) GetTemplates(ctx context.Context) ([]database.Template, error) {
 	m.ctrl.T.Helper()
@@ -3168,6 +4363,21 @@ func (mr *MockStoreMockRecorder) GetTemplates(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplates", reflect.TypeOf((*MockStore)(nil).GetTemplates), ctx)
 }
 
+// GetTemplatesSharedWithOrganization mocks base method.
+func (m *MockStore) GetTemplatesSharedWithOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplatesSharedWithOrganization", ctx, organizationID)
+	ret0, _ := ret[0].([]database.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplatesSharedWithOrganization indicates an expected call of GetTemplatesSharedWithOrganization.
+func (mr *MockStoreMockRecorder) GetTemplatesSharedWithOrganization(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplatesSharedWithOrganization", reflect.TypeOf((*MockStore)(nil).GetTemplatesSharedWithOrganization), ctx, organizationID)
+}
+
 // GetTemplatesWithFilter mocks base method.
 func (m *MockStore) GetTemplatesWithFilter(ctx context.Context, arg database.GetTemplatesWithFilterParams) ([]database.Template, error) {
 	m.ctrl.T.Helper()
@@ -3192,10 +4402,40 @@ func (m *MockStore) GetUnexpiredLicenses(ctx context.Context) ([]database.Licens
 	return ret0, ret1
 }
 
-// GetUnexpiredLicenses indicates an expected call of GetUnexpiredLicenses.
-func (mr *MockStoreMockRecorder) GetUnexpiredLicenses(ctx any) *gomock.Call {
+// GetUnexpiredLicenses indicates an expected call of GetUnexpiredLicenses.
+func (mr *MockStoreMockRecorder) GetUnexpiredLicenses(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnexpiredLicenses", reflect.TypeOf((*MockStore)(nil).GetUnexpiredLicenses), ctx)
+}
+
+// GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs mocks base method.
+func (m *MockStore) GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentHealthEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceAgentHealthEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs indicates an expected call of GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs), ctx, ids)
+}
+
+// GetUserActivityExportByID mocks base method.
+func (m *MockStore) GetUserActivityExportByID(ctx context.Context, id uuid.UUID) (database.UserActivityExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserActivityExportByID", ctx, id)
+	ret0, _ := ret[0].(database.UserActivityExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserActivityExportByID indicates an expected call of GetUserActivityExportByID.
+func (mr *MockStoreMockRecorder) GetUserActivityExportByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnexpiredLicenses", reflect.TypeOf((*MockStore)(nil).GetUnexpiredLicenses), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserActivityExportByID", reflect.TypeOf((*MockStore)(nil).GetUserActivityExportByID), ctx, id)
 }
 
 // GetUserActivityInsights mocks base method.
@@ -3258,6 +4498,21 @@ func (mr *MockStoreMockRecorder) GetUserCount(ctx, includeSystem any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCount", reflect.TypeOf((*MockStore)(nil).GetUserCount), ctx, includeSystem)
 }
 
+// GetUserDailyActivity mocks base method.
+func (m *MockStore) GetUserDailyActivity(ctx context.Context, arg database.GetUserDailyActivityParams) ([]database.GetUserDailyActivityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserDailyActivity", ctx, arg)
+	ret0, _ := ret[0].([]database.GetUserDailyActivityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserDailyActivity indicates an expected call of GetUserDailyActivity.
+func (mr *MockStoreMockRecorder) GetUserDailyActivity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDailyActivity", reflect.TypeOf((*MockStore)(nil).GetUserDailyActivity), ctx, arg)
+}
+
 // GetUserLatencyInsights mocks base method.
 func (m *MockStore) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -3318,6 +4573,36 @@ func (mr *MockStoreMockRecorder) GetUserLinksByUserID(ctx, userID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinksByUserID", reflect.TypeOf((*MockStore)(nil).GetUserLinksByUserID), ctx, userID)
 }
 
+// GetUserNotificationBuildDurationThreshold mocks base method.
+func (m *MockStore) GetUserNotificationBuildDurationThreshold(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationBuildDurationThreshold", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationBuildDurationThreshold indicates an expected call of GetUserNotificationBuildDurationThreshold.
+func (mr *MockStoreMockRecorder) GetUserNotificationBuildDurationThreshold(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationBuildDurationThreshold", reflect.TypeOf((*MockStore)(nil).GetUserNotificationBuildDurationThreshold), ctx, userID)
+}
+
+// GetUserNotificationDigestFrequency mocks base method.
+func (m *MockStore) GetUserNotificationDigestFrequency(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationDigestFrequency", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationDigestFrequency indicates an expected call of GetUserNotificationDigestFrequency.
+func (mr *MockStoreMockRecorder) GetUserNotificationDigestFrequency(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationDigestFrequency", reflect.TypeOf((*MockStore)(nil).GetUserNotificationDigestFrequency), ctx, userID)
+}
+
 // GetUserNotificationPreferences mocks base method.
 func (m *MockStore) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
 	m.ctrl.T.Helper()
@@ -3423,6 +4708,21 @@ func (mr *MockStoreMockRecorder) GetUsersByIDs(ctx, ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockStore)(nil).GetUsersByIDs), ctx, ids)
 }
 
+// GetUsersWithDueNotificationDigests mocks base method.
+func (m *MockStore) GetUsersWithDueNotificationDigests(ctx context.Context, now time.Time) ([]database.GetUsersWithDueNotificationDigestsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersWithDueNotificationDigests", ctx, now)
+	ret0, _ := ret[0].([]database.GetUsersWithDueNotificationDigestsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersWithDueNotificationDigests indicates an expected call of GetUsersWithDueNotificationDigests.
+func (mr *MockStoreMockRecorder) GetUsersWithDueNotificationDigests(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersWithDueNotificationDigests", reflect.TypeOf((*MockStore)(nil).GetUsersWithDueNotificationDigests), ctx, now)
+}
+
 // GetWebpushSubscriptionsByUserID mocks base method.
 func (m *MockStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	m.ctrl.T.Helper()
@@ -3498,6 +4798,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentByInstanceID(ctx, authInstance
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentByInstanceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentByInstanceID), ctx, authInstanceID)
 }
 
+// GetWorkspaceAgentConnectionQualityByAgentID mocks base method.
+func (m *MockStore) GetWorkspaceAgentConnectionQualityByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentConnectionQuality, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentConnectionQualityByAgentID", ctx, agentID)
+	ret0, _ := ret[0].([]database.WorkspaceAgentConnectionQuality)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentConnectionQualityByAgentID indicates an expected call of GetWorkspaceAgentConnectionQualityByAgentID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentConnectionQualityByAgentID(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentConnectionQualityByAgentID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentConnectionQualityByAgentID), ctx, agentID)
+}
+
+// GetWorkspaceAgentConnectionTypeCounts mocks base method.
+func (m *MockStore) GetWorkspaceAgentConnectionTypeCounts(ctx context.Context, updatedAfter time.Time) ([]database.GetWorkspaceAgentConnectionTypeCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentConnectionTypeCounts", ctx, updatedAfter)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentConnectionTypeCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentConnectionTypeCounts indicates an expected call of GetWorkspaceAgentConnectionTypeCounts.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentConnectionTypeCounts(ctx, updatedAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentConnectionTypeCounts", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentConnectionTypeCounts), ctx, updatedAfter)
+}
+
 // GetWorkspaceAgentDevcontainersByAgentID mocks base method.
 func (m *MockStore) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	m.ctrl.T.Helper()
@@ -3588,6 +4918,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentPortShare(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPortShare), ctx, arg)
 }
 
+// GetWorkspaceAgentResourceUsageByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceAgentResourceUsageByWorkspaceID(ctx context.Context, arg database.GetWorkspaceAgentResourceUsageByWorkspaceIDParams) ([]database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentResourceUsageByWorkspaceID", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentResourceUsageByWorkspaceID indicates an expected call of GetWorkspaceAgentResourceUsageByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentResourceUsageByWorkspaceID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentResourceUsageByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentResourceUsageByWorkspaceID), ctx, arg)
+}
+
 // GetWorkspaceAgentScriptTimingsByBuildID mocks base method.
 func (m *MockStore) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	m.ctrl.T.Helper()
@@ -3828,6 +5173,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAppsCreatedAfter(ctx, createdAt any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppsCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceBuildArchiveByBuildID mocks base method.
+func (m *MockStore) GetWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildArchiveByBuildID", ctx, workspaceBuildID)
+	ret0, _ := ret[0].(database.WorkspaceBuildArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildArchiveByBuildID indicates an expected call of GetWorkspaceBuildArchiveByBuildID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildArchiveByBuildID(ctx, workspaceBuildID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildArchiveByBuildID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildArchiveByBuildID), ctx, workspaceBuildID)
+}
+
 // GetWorkspaceBuildByID mocks base method.
 func (m *MockStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -3873,6 +5233,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildByWorkspaceIDAndBuildNumber", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildByWorkspaceIDAndBuildNumber), ctx, arg)
 }
 
+// GetWorkspaceBuildIdempotencyKey mocks base method.
+func (m *MockStore) GetWorkspaceBuildIdempotencyKey(ctx context.Context, arg database.GetWorkspaceBuildIdempotencyKeyParams) (database.WorkspaceBuildIdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceBuildIdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildIdempotencyKey indicates an expected call of GetWorkspaceBuildIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildIdempotencyKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildIdempotencyKey), ctx, arg)
+}
+
+// GetWorkspaceBuildInterimStateByBuildID mocks base method.
+func (m *MockStore) GetWorkspaceBuildInterimStateByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildInterimState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildInterimStateByBuildID", ctx, workspaceBuildID)
+	ret0, _ := ret[0].(database.WorkspaceBuildInterimState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildInterimStateByBuildID indicates an expected call of GetWorkspaceBuildInterimStateByBuildID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildInterimStateByBuildID(ctx, workspaceBuildID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildInterimStateByBuildID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildInterimStateByBuildID), ctx, workspaceBuildID)
+}
+
+// GetWorkspaceBuildOutcomesCompletedBetween mocks base method.
+func (m *MockStore) GetWorkspaceBuildOutcomesCompletedBetween(ctx context.Context, arg database.GetWorkspaceBuildOutcomesCompletedBetweenParams) ([]database.GetWorkspaceBuildOutcomesCompletedBetweenRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildOutcomesCompletedBetween", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceBuildOutcomesCompletedBetweenRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildOutcomesCompletedBetween indicates an expected call of GetWorkspaceBuildOutcomesCompletedBetween.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildOutcomesCompletedBetween(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildOutcomesCompletedBetween", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildOutcomesCompletedBetween), ctx, arg)
+}
+
 // GetWorkspaceBuildParameters mocks base method.
 func (m *MockStore) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	m.ctrl.T.Helper()
@@ -3903,6 +5308,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildParametersByBuildIDs(ctx, work
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildParametersByBuildIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildParametersByBuildIDs), ctx, workspaceBuildIds)
 }
 
+// GetWorkspaceBuildQueueByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceBuildQueueByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceBuildQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildQueueByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspaceBuildQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildQueueByWorkspaceID indicates an expected call of GetWorkspaceBuildQueueByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildQueueByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildQueueByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildQueueByWorkspaceID), ctx, workspaceID)
+}
+
+// GetWorkspaceBuildQueueEntryByID mocks base method.
+func (m *MockStore) GetWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuildQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildQueueEntryByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceBuildQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildQueueEntryByID indicates an expected call of GetWorkspaceBuildQueueEntryByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildQueueEntryByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildQueueEntryByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildQueueEntryByID), ctx, id)
+}
+
 // GetWorkspaceBuildStatsByTemplates mocks base method.
 func (m *MockStore) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]database.GetWorkspaceBuildStatsByTemplatesRow, error) {
 	m.ctrl.T.Helper()
@@ -3948,6 +5383,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildsCreatedAfter(ctx, createdAt a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildsCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceBuildsToArchive mocks base method.
+func (m *MockStore) GetWorkspaceBuildsToArchive(ctx context.Context, arg database.GetWorkspaceBuildsToArchiveParams) ([]database.GetWorkspaceBuildsToArchiveRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildsToArchive", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceBuildsToArchiveRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildsToArchive indicates an expected call of GetWorkspaceBuildsToArchive.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildsToArchive(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildsToArchive", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildsToArchive), ctx, arg)
+}
+
 // GetWorkspaceByAgentID mocks base method.
 func (m *MockStore) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -3978,6 +5428,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByID), ctx, id)
 }
 
+// GetWorkspaceByIDForUpdate mocks base method.
+func (m *MockStore) GetWorkspaceByIDForUpdate(ctx context.Context, id uuid.UUID) (database.WorkspaceTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceByIDForUpdate", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceByIDForUpdate indicates an expected call of GetWorkspaceByIDForUpdate.
+func (mr *MockStoreMockRecorder) GetWorkspaceByIDForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByIDForUpdate), ctx, id)
+}
+
+// GetWorkspaceByOrganizationIDAndName mocks base method.
+func (m *MockStore) GetWorkspaceByOrganizationIDAndName(ctx context.Context, arg database.GetWorkspaceByOrganizationIDAndNameParams) (database.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceByOrganizationIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceByOrganizationIDAndName indicates an expected call of GetWorkspaceByOrganizationIDAndName.
+func (mr *MockStoreMockRecorder) GetWorkspaceByOrganizationIDAndName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByOrganizationIDAndName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByOrganizationIDAndName), ctx, arg)
+}
+
 // GetWorkspaceByOwnerIDAndName mocks base method.
 func (m *MockStore) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg database.GetWorkspaceByOwnerIDAndNameParams) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -4023,6 +5503,111 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(ctx, workspaceAppI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), ctx, workspaceAppID)
 }
 
+// GetWorkspaceDailyCosts mocks base method.
+func (m *MockStore) GetWorkspaceDailyCosts(ctx context.Context, arg database.GetWorkspaceDailyCostsParams) ([]database.GetWorkspaceDailyCostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceDailyCosts", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceDailyCostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceDailyCosts indicates an expected call of GetWorkspaceDailyCosts.
+func (mr *MockStoreMockRecorder) GetWorkspaceDailyCosts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceDailyCosts", reflect.TypeOf((*MockStore)(nil).GetWorkspaceDailyCosts), ctx, arg)
+}
+
+// GetWorkspaceDriftCheckCandidates mocks base method.
+func (m *MockStore) GetWorkspaceDriftCheckCandidates(ctx context.Context, arg database.GetWorkspaceDriftCheckCandidatesParams) ([]database.GetWorkspaceDriftCheckCandidatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceDriftCheckCandidates", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceDriftCheckCandidatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceDriftCheckCandidates indicates an expected call of GetWorkspaceDriftCheckCandidates.
+func (mr *MockStoreMockRecorder) GetWorkspaceDriftCheckCandidates(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceDriftCheckCandidates", reflect.TypeOf((*MockStore)(nil).GetWorkspaceDriftCheckCandidates), ctx, arg)
+}
+
+// GetWorkspaceDriftChecksByWorkspaceIDs mocks base method.
+func (m *MockStore) GetWorkspaceDriftChecksByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetWorkspaceDriftChecksByWorkspaceIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceDriftChecksByWorkspaceIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.GetWorkspaceDriftChecksByWorkspaceIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceDriftChecksByWorkspaceIDs indicates an expected call of GetWorkspaceDriftChecksByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceDriftChecksByWorkspaceIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceDriftChecksByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceDriftChecksByWorkspaceIDs), ctx, ids)
+}
+
+// GetWorkspaceLabelsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceLabel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceLabelsByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspaceLabel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceLabelsByWorkspaceID indicates an expected call of GetWorkspaceLabelsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceLabelsByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceLabelsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceLabelsByWorkspaceID), ctx, workspaceID)
+}
+
+// GetWorkspaceLocksByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceLocksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceLocksByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspaceLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceLocksByWorkspaceID indicates an expected call of GetWorkspaceLocksByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceLocksByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceLocksByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceLocksByWorkspaceID), ctx, workspaceID)
+}
+
+// GetWorkspaceMigrationByID mocks base method.
+func (m *MockStore) GetWorkspaceMigrationByID(ctx context.Context, id uuid.UUID) (database.WorkspaceMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceMigrationByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceMigrationByID indicates an expected call of GetWorkspaceMigrationByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceMigrationByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceMigrationByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceMigrationByID), ctx, id)
+}
+
+// GetWorkspaceMigrationsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceMigrationsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceMigrationsByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspaceMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceMigrationsByWorkspaceID indicates an expected call of GetWorkspaceMigrationsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceMigrationsByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceMigrationsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceMigrationsByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceModulesByJobID mocks base method.
 func (m *MockStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -4053,6 +5638,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceModulesCreatedAfter(ctx, createdAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceModulesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceModulesCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspacePrebuildReservationByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspacePrebuildReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePrebuildReservationByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspacePrebuildReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePrebuildReservationByWorkspaceID indicates an expected call of GetWorkspacePrebuildReservationByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspacePrebuildReservationByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePrebuildReservationByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspacePrebuildReservationByWorkspaceID), ctx, workspaceID)
+}
+
+// GetWorkspacePrebuildReservationsByTemplateID mocks base method.
+func (m *MockStore) GetWorkspacePrebuildReservationsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspacePrebuildReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePrebuildReservationsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.WorkspacePrebuildReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePrebuildReservationsByTemplateID indicates an expected call of GetWorkspacePrebuildReservationsByTemplateID.
+func (mr *MockStoreMockRecorder) GetWorkspacePrebuildReservationsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePrebuildReservationsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetWorkspacePrebuildReservationsByTemplateID), ctx, templateID)
+}
+
+// GetWorkspaceProvisionerAffinity mocks base method.
+func (m *MockStore) GetWorkspaceProvisionerAffinity(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceProvisionerAffinity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceProvisionerAffinity", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceProvisionerAffinity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceProvisionerAffinity indicates an expected call of GetWorkspaceProvisionerAffinity.
+func (mr *MockStoreMockRecorder) GetWorkspaceProvisionerAffinity(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceProvisionerAffinity", reflect.TypeOf((*MockStore)(nil).GetWorkspaceProvisionerAffinity), ctx, workspaceID)
+}
+
 // GetWorkspaceProxies mocks base method.
 func (m *MockStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -4113,6 +5743,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceProxyByName(ctx, name any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceProxyByName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceProxyByName), ctx, name)
 }
 
+// GetWorkspaceReleaseChannelByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceReleaseChannelByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceReleaseChannelByWorkspaceID indicates an expected call of GetWorkspaceReleaseChannelByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceReleaseChannelByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceReleaseChannelByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceReleaseChannelByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceResourceByID mocks base method.
 func (m *MockStore) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	m.ctrl.T.Helper()
@@ -4203,6 +5848,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(ctx, createdA
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceSessionRecordingByID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingByID indicates an expected call of GetWorkspaceSessionRecordingByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingByID), ctx, id)
+}
+
+// GetWorkspaceSessionRecordingChunks mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingChunks", ctx, recordingID)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecordingChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingChunks indicates an expected call of GetWorkspaceSessionRecordingChunks.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingChunks(ctx, recordingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingChunks", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingChunks), ctx, recordingID)
+}
+
+// GetWorkspaceSessionRecordingsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingsByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingsByWorkspaceID indicates an expected call of GetWorkspaceSessionRecordingsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingsByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceUniqueOwnerCountByTemplateIDs mocks base method.
 func (m *MockStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -4322,6 +6012,21 @@ func (mr *MockStoreMockRecorder) InsertAPIKey(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAPIKey", reflect.TypeOf((*MockStore)(nil).InsertAPIKey), ctx, arg)
 }
 
+// InsertAccessRequest mocks base method.
+func (m *MockStore) InsertAccessRequest(ctx context.Context, arg database.InsertAccessRequestParams) (database.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAccessRequest", ctx, arg)
+	ret0, _ := ret[0].(database.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAccessRequest indicates an expected call of InsertAccessRequest.
+func (mr *MockStoreMockRecorder) InsertAccessRequest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAccessRequest", reflect.TypeOf((*MockStore)(nil).InsertAccessRequest), ctx, arg)
+}
+
 // InsertAllUsersGroup mocks base method.
 func (m *MockStore) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (database.Group, error) {
 	m.ctrl.T.Helper()
@@ -4352,6 +6057,21 @@ func (mr *MockStoreMockRecorder) InsertAuditLog(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLog", reflect.TypeOf((*MockStore)(nil).InsertAuditLog), ctx, arg)
 }
 
+// InsertBuildAlertRule mocks base method.
+func (m *MockStore) InsertBuildAlertRule(ctx context.Context, arg database.InsertBuildAlertRuleParams) (database.BuildAlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBuildAlertRule", ctx, arg)
+	ret0, _ := ret[0].(database.BuildAlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertBuildAlertRule indicates an expected call of InsertBuildAlertRule.
+func (mr *MockStoreMockRecorder) InsertBuildAlertRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBuildAlertRule", reflect.TypeOf((*MockStore)(nil).InsertBuildAlertRule), ctx, arg)
+}
+
 // InsertCryptoKey mocks base method.
 func (m *MockStore) InsertCryptoKey(ctx context.Context, arg database.InsertCryptoKeyParams) (database.CryptoKey, error) {
 	m.ctrl.T.Helper()
@@ -4528,6 +6248,21 @@ func (mr *MockStoreMockRecorder) InsertLicense(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLicense", reflect.TypeOf((*MockStore)(nil).InsertLicense), ctx, arg)
 }
 
+// InsertLoginDeviceCode mocks base method.
+func (m *MockStore) InsertLoginDeviceCode(ctx context.Context, arg database.InsertLoginDeviceCodeParams) (database.LoginDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertLoginDeviceCode", ctx, arg)
+	ret0, _ := ret[0].(database.LoginDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertLoginDeviceCode indicates an expected call of InsertLoginDeviceCode.
+func (mr *MockStoreMockRecorder) InsertLoginDeviceCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLoginDeviceCode", reflect.TypeOf((*MockStore)(nil).InsertLoginDeviceCode), ctx, arg)
+}
+
 // InsertMemoryResourceMonitor mocks base method.
 func (m *MockStore) InsertMemoryResourceMonitor(ctx context.Context, arg database.InsertMemoryResourceMonitorParams) (database.WorkspaceAgentMemoryResourceMonitor, error) {
 	m.ctrl.T.Helper()
@@ -4693,6 +6428,21 @@ func (mr *MockStoreMockRecorder) InsertPresetPrebuildSchedule(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPresetPrebuildSchedule", reflect.TypeOf((*MockStore)(nil).InsertPresetPrebuildSchedule), ctx, arg)
 }
 
+// InsertProvisionerBuildPause mocks base method.
+func (m *MockStore) InsertProvisionerBuildPause(ctx context.Context, arg database.InsertProvisionerBuildPauseParams) (database.ProvisionerBuildPause, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerBuildPause", ctx, arg)
+	ret0, _ := ret[0].(database.ProvisionerBuildPause)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProvisionerBuildPause indicates an expected call of InsertProvisionerBuildPause.
+func (mr *MockStoreMockRecorder) InsertProvisionerBuildPause(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerBuildPause", reflect.TypeOf((*MockStore)(nil).InsertProvisionerBuildPause), ctx, arg)
+}
+
 // InsertProvisionerJob mocks base method.
 func (m *MockStore) InsertProvisionerJob(ctx context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -4708,6 +6458,21 @@ func (mr *MockStoreMockRecorder) InsertProvisionerJob(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJob", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJob), ctx, arg)
 }
 
+// InsertProvisionerJobLogArchive mocks base method.
+func (m *MockStore) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerJobLogArchive", ctx, arg)
+	ret0, _ := ret[0].(database.ProvisionerJobLogArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProvisionerJobLogArchive indicates an expected call of InsertProvisionerJobLogArchive.
+func (mr *MockStoreMockRecorder) InsertProvisionerJobLogArchive(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobLogArchive", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobLogArchive), ctx, arg)
+}
+
 // InsertProvisionerJobLogs mocks base method.
 func (m *MockStore) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	m.ctrl.T.Helper()
@@ -4753,6 +6518,21 @@ func (mr *MockStoreMockRecorder) InsertProvisionerKey(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerKey", reflect.TypeOf((*MockStore)(nil).InsertProvisionerKey), ctx, arg)
 }
 
+// InsertProvisionerReservation mocks base method.
+func (m *MockStore) InsertProvisionerReservation(ctx context.Context, arg database.InsertProvisionerReservationParams) (database.ProvisionerReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerReservation", ctx, arg)
+	ret0, _ := ret[0].(database.ProvisionerReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProvisionerReservation indicates an expected call of InsertProvisionerReservation.
+func (mr *MockStoreMockRecorder) InsertProvisionerReservation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerReservation", reflect.TypeOf((*MockStore)(nil).InsertProvisionerReservation), ctx, arg)
+}
+
 // InsertReplica mocks base method.
 func (m *MockStore) InsertReplica(ctx context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -4768,6 +6548,21 @@ func (mr *MockStoreMockRecorder) InsertReplica(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), ctx, arg)
 }
 
+// InsertServiceAccount mocks base method.
+func (m *MockStore) InsertServiceAccount(ctx context.Context, arg database.InsertServiceAccountParams) (database.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertServiceAccount", ctx, arg)
+	ret0, _ := ret[0].(database.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertServiceAccount indicates an expected call of InsertServiceAccount.
+func (mr *MockStoreMockRecorder) InsertServiceAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertServiceAccount", reflect.TypeOf((*MockStore)(nil).InsertServiceAccount), ctx, arg)
+}
+
 // InsertTelemetryItemIfNotExists mocks base method.
 func (m *MockStore) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	m.ctrl.T.Helper()
@@ -4796,6 +6591,21 @@ func (mr *MockStoreMockRecorder) InsertTemplate(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplate", reflect.TypeOf((*MockStore)(nil).InsertTemplate), ctx, arg)
 }
 
+// InsertTemplatePreset mocks base method.
+func (m *MockStore) InsertTemplatePreset(ctx context.Context, arg database.InsertTemplatePresetParams) (database.TemplatePreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplatePreset", ctx, arg)
+	ret0, _ := ret[0].(database.TemplatePreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplatePreset indicates an expected call of InsertTemplatePreset.
+func (mr *MockStoreMockRecorder) InsertTemplatePreset(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplatePreset", reflect.TypeOf((*MockStore)(nil).InsertTemplatePreset), ctx, arg)
+}
+
 // InsertTemplateVersion mocks base method.
 func (m *MockStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	m.ctrl.T.Helper()
@@ -4884,6 +6694,21 @@ func (mr *MockStoreMockRecorder) InsertUser(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockStore)(nil).InsertUser), ctx, arg)
 }
 
+// InsertUserActivityExport mocks base method.
+func (m *MockStore) InsertUserActivityExport(ctx context.Context, arg database.InsertUserActivityExportParams) (database.UserActivityExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserActivityExport", ctx, arg)
+	ret0, _ := ret[0].(database.UserActivityExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserActivityExport indicates an expected call of InsertUserActivityExport.
+func (mr *MockStoreMockRecorder) InsertUserActivityExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserActivityExport", reflect.TypeOf((*MockStore)(nil).InsertUserActivityExport), ctx, arg)
+}
+
 // InsertUserGroupsByID mocks base method.
 func (m *MockStore) InsertUserGroupsByID(ctx context.Context, arg database.InsertUserGroupsByIDParams) ([]uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -5003,6 +6828,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentDevcontainers(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentDevcontainers", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentDevcontainers), ctx, arg)
 }
 
+// InsertWorkspaceAgentHealthEvent mocks base method.
+func (m *MockStore) InsertWorkspaceAgentHealthEvent(ctx context.Context, arg database.InsertWorkspaceAgentHealthEventParams) (database.WorkspaceAgentHealthEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentHealthEvent", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentHealthEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentHealthEvent indicates an expected call of InsertWorkspaceAgentHealthEvent.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentHealthEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentHealthEvent", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentHealthEvent), ctx, arg)
+}
+
 // InsertWorkspaceAgentLogSources mocks base method.
 func (m *MockStore) InsertWorkspaceAgentLogSources(ctx context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
 	m.ctrl.T.Helper()
@@ -5134,6 +6974,50 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuild(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuild", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuild), ctx, arg)
 }
 
+// InsertWorkspaceBuildArchive mocks base method.
+func (m *MockStore) InsertWorkspaceBuildArchive(ctx context.Context, arg database.InsertWorkspaceBuildArchiveParams) (database.WorkspaceBuildArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildArchive", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceBuildArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceBuildArchive indicates an expected call of InsertWorkspaceBuildArchive.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildArchive(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildArchive", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildArchive), ctx, arg)
+}
+
+// InsertWorkspaceBuildDurationNotification mocks base method.
+func (m *MockStore) InsertWorkspaceBuildDurationNotification(ctx context.Context, arg database.InsertWorkspaceBuildDurationNotificationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildDurationNotification", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceBuildDurationNotification indicates an expected call of InsertWorkspaceBuildDurationNotification.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildDurationNotification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildDurationNotification", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildDurationNotification), ctx, arg)
+}
+
+// InsertWorkspaceBuildIdempotencyKey mocks base method.
+func (m *MockStore) InsertWorkspaceBuildIdempotencyKey(ctx context.Context, arg database.InsertWorkspaceBuildIdempotencyKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceBuildIdempotencyKey indicates an expected call of InsertWorkspaceBuildIdempotencyKey.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildIdempotencyKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildIdempotencyKey", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildIdempotencyKey), ctx, arg)
+}
+
 // InsertWorkspaceBuildParameters mocks base method.
 func (m *MockStore) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	m.ctrl.T.Helper()
@@ -5148,6 +7032,65 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameters(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameters), ctx, arg)
 }
 
+// InsertWorkspaceBuildQueueEntry mocks base method.
+func (m *MockStore) InsertWorkspaceBuildQueueEntry(ctx context.Context, arg database.InsertWorkspaceBuildQueueEntryParams) (database.WorkspaceBuildQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildQueueEntry", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceBuildQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceBuildQueueEntry indicates an expected call of InsertWorkspaceBuildQueueEntry.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildQueueEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildQueueEntry", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildQueueEntry), ctx, arg)
+}
+
+// InsertWorkspaceLabels mocks base method.
+func (m *MockStore) InsertWorkspaceLabels(ctx context.Context, arg database.InsertWorkspaceLabelsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceLabels", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceLabels indicates an expected call of InsertWorkspaceLabels.
+func (mr *MockStoreMockRecorder) InsertWorkspaceLabels(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceLabels", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceLabels), ctx, arg)
+}
+
+// InsertWorkspaceLock mocks base method.
+func (m *MockStore) InsertWorkspaceLock(ctx context.Context, arg database.InsertWorkspaceLockParams) (database.WorkspaceLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceLock", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceLock indicates an expected call of InsertWorkspaceLock.
+func (mr *MockStoreMockRecorder) InsertWorkspaceLock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceLock", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceLock), ctx, arg)
+}
+
+// InsertWorkspaceMigration mocks base method.
+func (m *MockStore) InsertWorkspaceMigration(ctx context.Context, arg database.InsertWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceMigration", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceMigration indicates an expected call of InsertWorkspaceMigration.
+func (mr *MockStoreMockRecorder) InsertWorkspaceMigration(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceMigration", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceMigration), ctx, arg)
+}
+
 // InsertWorkspaceModule mocks base method.
 func (m *MockStore) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -5208,6 +7151,51 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceResourceMetadata(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceResourceMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceResourceMetadata), ctx, arg)
 }
 
+// InsertWorkspaceSessionRecording mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecording", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSessionRecording indicates an expected call of InsertWorkspaceSessionRecording.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecording(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecording", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecording), ctx, arg)
+}
+
+// InsertWorkspaceSessionRecordingChunk mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (database.WorkspaceSessionRecordingChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecordingChunk", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecordingChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSessionRecordingChunk indicates an expected call of InsertWorkspaceSessionRecordingChunk.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecordingChunk(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecordingChunk", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecordingChunk), ctx, arg)
+}
+
+// IsTemplateVersionRolloutCohortMember mocks base method.
+func (m *MockStore) IsTemplateVersionRolloutCohortMember(ctx context.Context, arg database.IsTemplateVersionRolloutCohortMemberParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTemplateVersionRolloutCohortMember", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTemplateVersionRolloutCohortMember indicates an expected call of IsTemplateVersionRolloutCohortMember.
+func (mr *MockStoreMockRecorder) IsTemplateVersionRolloutCohortMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTemplateVersionRolloutCohortMember", reflect.TypeOf((*MockStore)(nil).IsTemplateVersionRolloutCohortMember), ctx, arg)
+}
+
 // ListProvisionerKeysByOrganization mocks base method.
 func (m *MockStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	m.ctrl.T.Helper()
@@ -5267,6 +7255,21 @@ func (mr *MockStoreMockRecorder) MarkAllInboxNotificationsAsRead(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllInboxNotificationsAsRead", reflect.TypeOf((*MockStore)(nil).MarkAllInboxNotificationsAsRead), ctx, arg)
 }
 
+// MarkNotificationMessagesDigested mocks base method.
+func (m *MockStore) MarkNotificationMessagesDigested(ctx context.Context, arg database.MarkNotificationMessagesDigestedParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationMessagesDigested", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationMessagesDigested indicates an expected call of MarkNotificationMessagesDigested.
+func (mr *MockStoreMockRecorder) MarkNotificationMessagesDigested(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationMessagesDigested", reflect.TypeOf((*MockStore)(nil).MarkNotificationMessagesDigested), ctx, arg)
+}
+
 // OIDCClaimFieldValues mocks base method.
 func (m *MockStore) OIDCClaimFieldValues(ctx context.Context, arg database.OIDCClaimFieldValuesParams) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -5415,6 +7418,21 @@ func (mr *MockStoreMockRecorder) RemoveUserFromGroups(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserFromGroups", reflect.TypeOf((*MockStore)(nil).RemoveUserFromGroups), ctx, arg)
 }
 
+// ResolveWorkspaceAgentHealthEvent mocks base method.
+func (m *MockStore) ResolveWorkspaceAgentHealthEvent(ctx context.Context, arg database.ResolveWorkspaceAgentHealthEventParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveWorkspaceAgentHealthEvent", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveWorkspaceAgentHealthEvent indicates an expected call of ResolveWorkspaceAgentHealthEvent.
+func (mr *MockStoreMockRecorder) ResolveWorkspaceAgentHealthEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveWorkspaceAgentHealthEvent", reflect.TypeOf((*MockStore)(nil).ResolveWorkspaceAgentHealthEvent), ctx, arg)
+}
+
 // RevokeDBCryptKey mocks base method.
 func (m *MockStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	m.ctrl.T.Helper()
@@ -5429,6 +7447,36 @@ func (mr *MockStoreMockRecorder) RevokeDBCryptKey(ctx, activeKeyDigest any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDBCryptKey", reflect.TypeOf((*MockStore)(nil).RevokeDBCryptKey), ctx, activeKeyDigest)
 }
 
+// RotateProvisionerKey mocks base method.
+func (m *MockStore) RotateProvisionerKey(ctx context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateProvisionerKey", ctx, arg)
+	ret0, _ := ret[0].(database.ProvisionerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateProvisionerKey indicates an expected call of RotateProvisionerKey.
+func (mr *MockStoreMockRecorder) RotateProvisionerKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateProvisionerKey", reflect.TypeOf((*MockStore)(nil).RotateProvisionerKey), ctx, arg)
+}
+
+// SearchProvisionerJobLogs mocks base method.
+func (m *MockStore) SearchProvisionerJobLogs(ctx context.Context, arg database.SearchProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProvisionerJobLogs", ctx, arg)
+	ret0, _ := ret[0].([]database.ProvisionerJobLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchProvisionerJobLogs indicates an expected call of SearchProvisionerJobLogs.
+func (mr *MockStoreMockRecorder) SearchProvisionerJobLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProvisionerJobLogs", reflect.TypeOf((*MockStore)(nil).SearchProvisionerJobLogs), ctx, arg)
+}
+
 // TryAcquireLock mocks base method.
 func (m *MockStore) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
 	m.ctrl.T.Helper()
@@ -5472,6 +7520,21 @@ func (mr *MockStoreMockRecorder) UnfavoriteWorkspace(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfavoriteWorkspace", reflect.TypeOf((*MockStore)(nil).UnfavoriteWorkspace), ctx, id)
 }
 
+// UnlockWorkspace mocks base method.
+func (m *MockStore) UnlockWorkspace(ctx context.Context, arg database.UnlockWorkspaceParams) (database.WorkspaceLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockWorkspace", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockWorkspace indicates an expected call of UnlockWorkspace.
+func (mr *MockStoreMockRecorder) UnlockWorkspace(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockWorkspace", reflect.TypeOf((*MockStore)(nil).UnlockWorkspace), ctx, arg)
+}
+
 // UpdateAPIKeyByID mocks base method.
 func (m *MockStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKeyByIDParams) error {
 	m.ctrl.T.Helper()
@@ -5486,6 +7549,65 @@ func (mr *MockStoreMockRecorder) UpdateAPIKeyByID(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKeyByID", reflect.TypeOf((*MockStore)(nil).UpdateAPIKeyByID), ctx, arg)
 }
 
+// UpdateAccessRequestExpiredByID mocks base method.
+func (m *MockStore) UpdateAccessRequestExpiredByID(ctx context.Context, id uuid.UUID) (database.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccessRequestExpiredByID", ctx, id)
+	ret0, _ := ret[0].(database.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccessRequestExpiredByID indicates an expected call of UpdateAccessRequestExpiredByID.
+func (mr *MockStoreMockRecorder) UpdateAccessRequestExpiredByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccessRequestExpiredByID", reflect.TypeOf((*MockStore)(nil).UpdateAccessRequestExpiredByID), ctx, id)
+}
+
+// UpdateAccessRequestReviewByID mocks base method.
+func (m *MockStore) UpdateAccessRequestReviewByID(ctx context.Context, arg database.UpdateAccessRequestReviewByIDParams) (database.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccessRequestReviewByID", ctx, arg)
+	ret0, _ := ret[0].(database.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccessRequestReviewByID indicates an expected call of UpdateAccessRequestReviewByID.
+func (mr *MockStoreMockRecorder) UpdateAccessRequestReviewByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccessRequestReviewByID", reflect.TypeOf((*MockStore)(nil).UpdateAccessRequestReviewByID), ctx, arg)
+}
+
+// UpdateBuildAlertRuleByID mocks base method.
+func (m *MockStore) UpdateBuildAlertRuleByID(ctx context.Context, arg database.UpdateBuildAlertRuleByIDParams) (database.BuildAlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBuildAlertRuleByID", ctx, arg)
+	ret0, _ := ret[0].(database.BuildAlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBuildAlertRuleByID indicates an expected call of UpdateBuildAlertRuleByID.
+func (mr *MockStoreMockRecorder) UpdateBuildAlertRuleByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBuildAlertRuleByID", reflect.TypeOf((*MockStore)(nil).UpdateBuildAlertRuleByID), ctx, arg)
+}
+
+// UpdateBuildAlertRuleEvaluation mocks base method.
+func (m *MockStore) UpdateBuildAlertRuleEvaluation(ctx context.Context, arg database.UpdateBuildAlertRuleEvaluationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBuildAlertRuleEvaluation", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBuildAlertRuleEvaluation indicates an expected call of UpdateBuildAlertRuleEvaluation.
+func (mr *MockStoreMockRecorder) UpdateBuildAlertRuleEvaluation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBuildAlertRuleEvaluation", reflect.TypeOf((*MockStore)(nil).UpdateBuildAlertRuleEvaluation), ctx, arg)
+}
+
 // UpdateCryptoKeyDeletesAt mocks base method.
 func (m *MockStore) UpdateCryptoKeyDeletesAt(ctx context.Context, arg database.UpdateCryptoKeyDeletesAtParams) (database.CryptoKey, error) {
 	m.ctrl.T.Helper()
@@ -5707,6 +7829,21 @@ func (mr *MockStoreMockRecorder) UpdateOrganizationDeletedByID(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrganizationDeletedByID", reflect.TypeOf((*MockStore)(nil).UpdateOrganizationDeletedByID), ctx, arg)
 }
 
+// UpdatePendingProvisionerJobsUpdatedAt mocks base method.
+func (m *MockStore) UpdatePendingProvisionerJobsUpdatedAt(ctx context.Context, arg database.UpdatePendingProvisionerJobsUpdatedAtParams) ([]database.ProvisionerJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePendingProvisionerJobsUpdatedAt", ctx, arg)
+	ret0, _ := ret[0].([]database.ProvisionerJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePendingProvisionerJobsUpdatedAt indicates an expected call of UpdatePendingProvisionerJobsUpdatedAt.
+func (mr *MockStoreMockRecorder) UpdatePendingProvisionerJobsUpdatedAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePendingProvisionerJobsUpdatedAt", reflect.TypeOf((*MockStore)(nil).UpdatePendingProvisionerJobsUpdatedAt), ctx, arg)
+}
+
 // UpdatePresetPrebuildStatus mocks base method.
 func (m *MockStore) UpdatePresetPrebuildStatus(ctx context.Context, arg database.UpdatePresetPrebuildStatusParams) error {
 	m.ctrl.T.Helper()
@@ -5791,6 +7928,20 @@ func (mr *MockStoreMockRecorder) UpdateProvisionerJobWithCompleteWithStartedAtBy
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobWithCompleteWithStartedAtByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobWithCompleteWithStartedAtByID), ctx, arg)
 }
 
+// UpdateProvisionerKeyLastUsedAt mocks base method.
+func (m *MockStore) UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProvisionerKeyLastUsedAt", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProvisionerKeyLastUsedAt indicates an expected call of UpdateProvisionerKeyLastUsedAt.
+func (mr *MockStoreMockRecorder) UpdateProvisionerKeyLastUsedAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerKeyLastUsedAt", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerKeyLastUsedAt), ctx, arg)
+}
+
 // UpdateReplica mocks base method.
 func (m *MockStore) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -5884,10 +8035,25 @@ func (m *MockStore) UpdateTemplateMetaByID(ctx context.Context, arg database.Upd
 	return ret0
 }
 
-// UpdateTemplateMetaByID indicates an expected call of UpdateTemplateMetaByID.
-func (mr *MockStoreMockRecorder) UpdateTemplateMetaByID(ctx, arg any) *gomock.Call {
+// UpdateTemplateMetaByID indicates an expected call of UpdateTemplateMetaByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateMetaByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMetaByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMetaByID), ctx, arg)
+}
+
+// UpdateTemplatePresetByID mocks base method.
+func (m *MockStore) UpdateTemplatePresetByID(ctx context.Context, arg database.UpdateTemplatePresetByIDParams) (database.TemplatePreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplatePresetByID", ctx, arg)
+	ret0, _ := ret[0].(database.TemplatePreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplatePresetByID indicates an expected call of UpdateTemplatePresetByID.
+func (mr *MockStoreMockRecorder) UpdateTemplatePresetByID(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMetaByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMetaByID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplatePresetByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplatePresetByID), ctx, arg)
 }
 
 // UpdateTemplateScheduleByID mocks base method.
@@ -5960,6 +8126,20 @@ func (mr *MockStoreMockRecorder) UpdateTemplateVersionExternalAuthProvidersByJob
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionExternalAuthProvidersByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionExternalAuthProvidersByJobID), ctx, arg)
 }
 
+// UpdateTemplateVersionVariableValue mocks base method.
+func (m *MockStore) UpdateTemplateVersionVariableValue(ctx context.Context, arg database.UpdateTemplateVersionVariableValueParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionVariableValue", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateVersionVariableValue indicates an expected call of UpdateTemplateVersionVariableValue.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionVariableValue(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionVariableValue", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionVariableValue), ctx, arg)
+}
+
 // UpdateTemplateWorkspacesLastUsedAt mocks base method.
 func (m *MockStore) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	m.ctrl.T.Helper()
@@ -5974,6 +8154,21 @@ func (mr *MockStoreMockRecorder) UpdateTemplateWorkspacesLastUsedAt(ctx, arg any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateWorkspacesLastUsedAt", reflect.TypeOf((*MockStore)(nil).UpdateTemplateWorkspacesLastUsedAt), ctx, arg)
 }
 
+// UpdateUserActivityExportStatusByID mocks base method.
+func (m *MockStore) UpdateUserActivityExportStatusByID(ctx context.Context, arg database.UpdateUserActivityExportStatusByIDParams) (database.UserActivityExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserActivityExportStatusByID", ctx, arg)
+	ret0, _ := ret[0].(database.UserActivityExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserActivityExportStatusByID indicates an expected call of UpdateUserActivityExportStatusByID.
+func (mr *MockStoreMockRecorder) UpdateUserActivityExportStatusByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserActivityExportStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateUserActivityExportStatusByID), ctx, arg)
+}
+
 // UpdateUserDeletedByID mocks base method.
 func (m *MockStore) UpdateUserDeletedByID(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -6090,6 +8285,51 @@ func (mr *MockStoreMockRecorder) UpdateUserLoginType(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserLoginType", reflect.TypeOf((*MockStore)(nil).UpdateUserLoginType), ctx, arg)
 }
 
+// UpdateUserNotificationBuildDurationThreshold mocks base method.
+func (m *MockStore) UpdateUserNotificationBuildDurationThreshold(ctx context.Context, arg database.UpdateUserNotificationBuildDurationThresholdParams) (database.UserConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserNotificationBuildDurationThreshold", ctx, arg)
+	ret0, _ := ret[0].(database.UserConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserNotificationBuildDurationThreshold indicates an expected call of UpdateUserNotificationBuildDurationThreshold.
+func (mr *MockStoreMockRecorder) UpdateUserNotificationBuildDurationThreshold(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserNotificationBuildDurationThreshold", reflect.TypeOf((*MockStore)(nil).UpdateUserNotificationBuildDurationThreshold), ctx, arg)
+}
+
+// UpdateUserNotificationDigestFrequency mocks base method.
+func (m *MockStore) UpdateUserNotificationDigestFrequency(ctx context.Context, arg database.UpdateUserNotificationDigestFrequencyParams) (database.UserConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserNotificationDigestFrequency", ctx, arg)
+	ret0, _ := ret[0].(database.UserConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserNotificationDigestFrequency indicates an expected call of UpdateUserNotificationDigestFrequency.
+func (mr *MockStoreMockRecorder) UpdateUserNotificationDigestFrequency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserNotificationDigestFrequency", reflect.TypeOf((*MockStore)(nil).UpdateUserNotificationDigestFrequency), ctx, arg)
+}
+
+// UpdateUserNotificationDigestPreferences mocks base method.
+func (m *MockStore) UpdateUserNotificationDigestPreferences(ctx context.Context, arg database.UpdateUserNotificationDigestPreferencesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserNotificationDigestPreferences", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserNotificationDigestPreferences indicates an expected call of UpdateUserNotificationDigestPreferences.
+func (mr *MockStoreMockRecorder) UpdateUserNotificationDigestPreferences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserNotificationDigestPreferences", reflect.TypeOf((*MockStore)(nil).UpdateUserNotificationDigestPreferences), ctx, arg)
+}
+
 // UpdateUserNotificationPreferences mocks base method.
 func (m *MockStore) UpdateUserNotificationPreferences(ctx context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -6224,6 +8464,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspace(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockStore)(nil).UpdateWorkspace), ctx, arg)
 }
 
+// UpdateWorkspaceACLByID mocks base method.
+func (m *MockStore) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceACLByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceACLByID indicates an expected call of UpdateWorkspaceACLByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceACLByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceACLByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceACLByID), ctx, arg)
+}
+
 // UpdateWorkspaceAgentConnectionByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6378,6 +8632,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildDeadlineByID(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildDeadlineByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildDeadlineByID), ctx, arg)
 }
 
+// UpdateWorkspaceBuildIdempotencyKeyBuildID mocks base method.
+func (m *MockStore) UpdateWorkspaceBuildIdempotencyKeyBuildID(ctx context.Context, arg database.UpdateWorkspaceBuildIdempotencyKeyBuildIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceBuildIdempotencyKeyBuildID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceBuildIdempotencyKeyBuildID indicates an expected call of UpdateWorkspaceBuildIdempotencyKeyBuildID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildIdempotencyKeyBuildID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildIdempotencyKeyBuildID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildIdempotencyKeyBuildID), ctx, arg)
+}
+
 // UpdateWorkspaceBuildProvisionerStateByID mocks base method.
 func (m *MockStore) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg database.UpdateWorkspaceBuildProvisionerStateByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6392,6 +8660,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildProvisionerStateByID(ctx, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildProvisionerStateByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildProvisionerStateByID), ctx, arg)
 }
 
+// UpdateWorkspaceBuildQueueEntryFailed mocks base method.
+func (m *MockStore) UpdateWorkspaceBuildQueueEntryFailed(ctx context.Context, arg database.UpdateWorkspaceBuildQueueEntryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceBuildQueueEntryFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceBuildQueueEntryFailed indicates an expected call of UpdateWorkspaceBuildQueueEntryFailed.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildQueueEntryFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildQueueEntryFailed", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildQueueEntryFailed), ctx, arg)
+}
+
 // UpdateWorkspaceDeletedByID mocks base method.
 func (m *MockStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6421,6 +8703,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceDormantDeletingAt(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDormantDeletingAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDormantDeletingAt), ctx, arg)
 }
 
+// UpdateWorkspaceDriftCheckByJobID mocks base method.
+func (m *MockStore) UpdateWorkspaceDriftCheckByJobID(ctx context.Context, arg database.UpdateWorkspaceDriftCheckByJobIDParams) (database.WorkspaceDriftCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceDriftCheckByJobID", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceDriftCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceDriftCheckByJobID indicates an expected call of UpdateWorkspaceDriftCheckByJobID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceDriftCheckByJobID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDriftCheckByJobID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDriftCheckByJobID), ctx, arg)
+}
+
 // UpdateWorkspaceLastUsedAt mocks base method.
 func (m *MockStore) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
 	m.ctrl.T.Helper()
@@ -6435,6 +8732,35 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceLastUsedAt(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceLastUsedAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceLastUsedAt), ctx, arg)
 }
 
+// UpdateWorkspaceMigrationSessionToken mocks base method.
+func (m *MockStore) UpdateWorkspaceMigrationSessionToken(ctx context.Context, arg database.UpdateWorkspaceMigrationSessionTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceMigrationSessionToken", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceMigrationSessionToken indicates an expected call of UpdateWorkspaceMigrationSessionToken.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceMigrationSessionToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceMigrationSessionToken", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceMigrationSessionToken), ctx, arg)
+}
+
+// UpdateWorkspaceMigrationStatus mocks base method.
+func (m *MockStore) UpdateWorkspaceMigrationStatus(ctx context.Context, arg database.UpdateWorkspaceMigrationStatusParams) (database.WorkspaceMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceMigrationStatus", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceMigrationStatus indicates an expected call of UpdateWorkspaceMigrationStatus.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceMigrationStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceMigrationStatus", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceMigrationStatus), ctx, arg)
+}
+
 // UpdateWorkspaceNextStartAt mocks base method.
 func (m *MockStore) UpdateWorkspaceNextStartAt(ctx context.Context, arg database.UpdateWorkspaceNextStartAtParams) error {
 	m.ctrl.T.Helper()
@@ -6478,6 +8804,35 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceProxyDeleted(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceProxyDeleted", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceProxyDeleted), ctx, arg)
 }
 
+// UpdateWorkspaceSessionRecording mocks base method.
+func (m *MockStore) UpdateWorkspaceSessionRecording(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceSessionRecording", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceSessionRecording indicates an expected call of UpdateWorkspaceSessionRecording.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceSessionRecording(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceSessionRecording", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceSessionRecording), ctx, arg)
+}
+
+// UpdateWorkspaceSessionRecordingChunkData mocks base method.
+func (m *MockStore) UpdateWorkspaceSessionRecordingChunkData(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingChunkDataParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceSessionRecordingChunkData", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceSessionRecordingChunkData indicates an expected call of UpdateWorkspaceSessionRecordingChunkData.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceSessionRecordingChunkData(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceSessionRecordingChunkData", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceSessionRecordingChunkData), ctx, arg)
+}
+
 // UpdateWorkspaceTTL mocks base method.
 func (m *MockStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	m.ctrl.T.Helper()
@@ -6577,6 +8932,20 @@ func (mr *MockStoreMockRecorder) UpsertCoordinatorResumeTokenSigningKey(ctx, val
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCoordinatorResumeTokenSigningKey", reflect.TypeOf((*MockStore)(nil).UpsertCoordinatorResumeTokenSigningKey), ctx, value)
 }
 
+// UpsertDBPurgeStats mocks base method.
+func (m *MockStore) UpsertDBPurgeStats(ctx context.Context, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDBPurgeStats", ctx, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertDBPurgeStats indicates an expected call of UpsertDBPurgeStats.
+func (mr *MockStoreMockRecorder) UpsertDBPurgeStats(ctx, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDBPurgeStats", reflect.TypeOf((*MockStore)(nil).UpsertDBPurgeStats), ctx, value)
+}
+
 // UpsertDefaultProxy mocks base method.
 func (m *MockStore) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	m.ctrl.T.Helper()
@@ -6605,6 +8974,20 @@ func (mr *MockStoreMockRecorder) UpsertHealthSettings(ctx, value any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertHealthSettings", reflect.TypeOf((*MockStore)(nil).UpsertHealthSettings), ctx, value)
 }
 
+// UpsertJobReaperLastRunAt mocks base method.
+func (m *MockStore) UpsertJobReaperLastRunAt(ctx context.Context, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertJobReaperLastRunAt", ctx, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertJobReaperLastRunAt indicates an expected call of UpsertJobReaperLastRunAt.
+func (mr *MockStoreMockRecorder) UpsertJobReaperLastRunAt(ctx, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertJobReaperLastRunAt", reflect.TypeOf((*MockStore)(nil).UpsertJobReaperLastRunAt), ctx, value)
+}
+
 // UpsertLastUpdateCheck mocks base method.
 func (m *MockStore) UpsertLastUpdateCheck(ctx context.Context, value string) error {
 	m.ctrl.T.Helper()
@@ -6689,6 +9072,21 @@ func (mr *MockStoreMockRecorder) UpsertOAuthSigningKey(ctx, value any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOAuthSigningKey", reflect.TypeOf((*MockStore)(nil).UpsertOAuthSigningKey), ctx, value)
 }
 
+// UpsertOrganizationWorkspaceNamingPolicy mocks base method.
+func (m *MockStore) UpsertOrganizationWorkspaceNamingPolicy(ctx context.Context, arg database.UpsertOrganizationWorkspaceNamingPolicyParams) (database.WorkspaceNamingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrganizationWorkspaceNamingPolicy", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceNamingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOrganizationWorkspaceNamingPolicy indicates an expected call of UpsertOrganizationWorkspaceNamingPolicy.
+func (mr *MockStoreMockRecorder) UpsertOrganizationWorkspaceNamingPolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrganizationWorkspaceNamingPolicy", reflect.TypeOf((*MockStore)(nil).UpsertOrganizationWorkspaceNamingPolicy), ctx, arg)
+}
+
 // UpsertProvisionerDaemon mocks base method.
 func (m *MockStore) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	m.ctrl.T.Helper()
@@ -6704,6 +9102,20 @@ func (mr *MockStoreMockRecorder) UpsertProvisionerDaemon(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertProvisionerDaemon", reflect.TypeOf((*MockStore)(nil).UpsertProvisionerDaemon), ctx, arg)
 }
 
+// UpsertReadOnlySettings mocks base method.
+func (m *MockStore) UpsertReadOnlySettings(ctx context.Context, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReadOnlySettings", ctx, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertReadOnlySettings indicates an expected call of UpsertReadOnlySettings.
+func (mr *MockStoreMockRecorder) UpsertReadOnlySettings(ctx, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReadOnlySettings", reflect.TypeOf((*MockStore)(nil).UpsertReadOnlySettings), ctx, value)
+}
+
 // UpsertRuntimeConfig mocks base method.
 func (m *MockStore) UpsertRuntimeConfig(ctx context.Context, arg database.UpsertRuntimeConfigParams) error {
 	m.ctrl.T.Helper()
@@ -6821,6 +9233,64 @@ func (mr *MockStoreMockRecorder) UpsertTelemetryItem(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTelemetryItem", reflect.TypeOf((*MockStore)(nil).UpsertTelemetryItem), ctx, arg)
 }
 
+// UpsertTemplateBuildDurationStats mocks base method.
+func (m *MockStore) UpsertTemplateBuildDurationStats(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateBuildDurationStats", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertTemplateBuildDurationStats indicates an expected call of UpsertTemplateBuildDurationStats.
+func (mr *MockStoreMockRecorder) UpsertTemplateBuildDurationStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateBuildDurationStats", reflect.TypeOf((*MockStore)(nil).UpsertTemplateBuildDurationStats), ctx)
+}
+
+// UpsertTemplatePresetGroupDefault mocks base method.
+func (m *MockStore) UpsertTemplatePresetGroupDefault(ctx context.Context, arg database.UpsertTemplatePresetGroupDefaultParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplatePresetGroupDefault", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertTemplatePresetGroupDefault indicates an expected call of UpsertTemplatePresetGroupDefault.
+func (mr *MockStoreMockRecorder) UpsertTemplatePresetGroupDefault(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplatePresetGroupDefault", reflect.TypeOf((*MockStore)(nil).UpsertTemplatePresetGroupDefault), ctx, arg)
+}
+
+// UpsertTemplateReleaseChannel mocks base method.
+func (m *MockStore) UpsertTemplateReleaseChannel(ctx context.Context, arg database.UpsertTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateReleaseChannel", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateReleaseChannel indicates an expected call of UpsertTemplateReleaseChannel.
+func (mr *MockStoreMockRecorder) UpsertTemplateReleaseChannel(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateReleaseChannel", reflect.TypeOf((*MockStore)(nil).UpsertTemplateReleaseChannel), ctx, arg)
+}
+
+// UpsertTemplateShare mocks base method.
+func (m *MockStore) UpsertTemplateShare(ctx context.Context, arg database.UpsertTemplateShareParams) (database.TemplateShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateShare", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateShare indicates an expected call of UpsertTemplateShare.
+func (mr *MockStoreMockRecorder) UpsertTemplateShare(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateShare", reflect.TypeOf((*MockStore)(nil).UpsertTemplateShare), ctx, arg)
+}
+
 // UpsertTemplateUsageStats mocks base method.
 func (m *MockStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -6835,6 +9305,36 @@ func (mr *MockStoreMockRecorder) UpsertTemplateUsageStats(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateUsageStats", reflect.TypeOf((*MockStore)(nil).UpsertTemplateUsageStats), ctx)
 }
 
+// UpsertTemplateVersionRollout mocks base method.
+func (m *MockStore) UpsertTemplateVersionRollout(ctx context.Context, arg database.UpsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateVersionRollout", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateVersionRollout indicates an expected call of UpsertTemplateVersionRollout.
+func (mr *MockStoreMockRecorder) UpsertTemplateVersionRollout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateVersionRollout", reflect.TypeOf((*MockStore)(nil).UpsertTemplateVersionRollout), ctx, arg)
+}
+
+// UpsertTemplateWorkspaceNamingPolicy mocks base method.
+func (m *MockStore) UpsertTemplateWorkspaceNamingPolicy(ctx context.Context, arg database.UpsertTemplateWorkspaceNamingPolicyParams) (database.WorkspaceNamingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateWorkspaceNamingPolicy", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceNamingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateWorkspaceNamingPolicy indicates an expected call of UpsertTemplateWorkspaceNamingPolicy.
+func (mr *MockStoreMockRecorder) UpsertTemplateWorkspaceNamingPolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateWorkspaceNamingPolicy", reflect.TypeOf((*MockStore)(nil).UpsertTemplateWorkspaceNamingPolicy), ctx, arg)
+}
+
 // UpsertWebpushVAPIDKeys mocks base method.
 func (m *MockStore) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	m.ctrl.T.Helper()
@@ -6849,6 +9349,20 @@ func (mr *MockStoreMockRecorder) UpsertWebpushVAPIDKeys(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWebpushVAPIDKeys", reflect.TypeOf((*MockStore)(nil).UpsertWebpushVAPIDKeys), ctx, arg)
 }
 
+// UpsertWorkspaceAgentConnectionQuality mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentConnectionQuality(ctx context.Context, arg database.UpsertWorkspaceAgentConnectionQualityParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentConnectionQuality", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceAgentConnectionQuality indicates an expected call of UpsertWorkspaceAgentConnectionQuality.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentConnectionQuality(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentConnectionQuality", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentConnectionQuality), ctx, arg)
+}
+
 // UpsertWorkspaceAgentPortShare mocks base method.
 func (m *MockStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	m.ctrl.T.Helper()
@@ -6864,6 +9378,20 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPortShare(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPortShare), ctx, arg)
 }
 
+// UpsertWorkspaceAgentResourceUsage mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentResourceUsage(ctx context.Context, arg database.UpsertWorkspaceAgentResourceUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentResourceUsage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceAgentResourceUsage indicates an expected call of UpsertWorkspaceAgentResourceUsage.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentResourceUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentResourceUsage", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentResourceUsage), ctx, arg)
+}
+
 // UpsertWorkspaceApp mocks base method.
 func (m *MockStore) UpsertWorkspaceApp(ctx context.Context, arg database.UpsertWorkspaceAppParams) (database.WorkspaceApp, error) {
 	m.ctrl.T.Helper()
@@ -6894,6 +9422,91 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAppAuditSession(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAppAuditSession", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAppAuditSession), ctx, arg)
 }
 
+// UpsertWorkspaceBuildInterimState mocks base method.
+func (m *MockStore) UpsertWorkspaceBuildInterimState(ctx context.Context, arg database.UpsertWorkspaceBuildInterimStateParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceBuildInterimState", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceBuildInterimState indicates an expected call of UpsertWorkspaceBuildInterimState.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceBuildInterimState(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceBuildInterimState", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceBuildInterimState), ctx, arg)
+}
+
+// UpsertWorkspaceDailyCosts mocks base method.
+func (m *MockStore) UpsertWorkspaceDailyCosts(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceDailyCosts", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceDailyCosts indicates an expected call of UpsertWorkspaceDailyCosts.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceDailyCosts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceDailyCosts", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceDailyCosts), ctx)
+}
+
+// UpsertWorkspaceDriftCheck mocks base method.
+func (m *MockStore) UpsertWorkspaceDriftCheck(ctx context.Context, arg database.UpsertWorkspaceDriftCheckParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceDriftCheck", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceDriftCheck indicates an expected call of UpsertWorkspaceDriftCheck.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceDriftCheck(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceDriftCheck", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceDriftCheck), ctx, arg)
+}
+
+// UpsertWorkspacePrebuildReservation mocks base method.
+func (m *MockStore) UpsertWorkspacePrebuildReservation(ctx context.Context, arg database.UpsertWorkspacePrebuildReservationParams) (database.WorkspacePrebuildReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspacePrebuildReservation", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspacePrebuildReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspacePrebuildReservation indicates an expected call of UpsertWorkspacePrebuildReservation.
+func (mr *MockStoreMockRecorder) UpsertWorkspacePrebuildReservation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspacePrebuildReservation", reflect.TypeOf((*MockStore)(nil).UpsertWorkspacePrebuildReservation), ctx, arg)
+}
+
+// UpsertWorkspaceProvisionerAffinity mocks base method.
+func (m *MockStore) UpsertWorkspaceProvisionerAffinity(ctx context.Context, arg database.UpsertWorkspaceProvisionerAffinityParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceProvisionerAffinity", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceProvisionerAffinity indicates an expected call of UpsertWorkspaceProvisionerAffinity.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceProvisionerAffinity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceProvisionerAffinity", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceProvisionerAffinity), ctx, arg)
+}
+
+// UpsertWorkspaceReleaseChannel mocks base method.
+func (m *MockStore) UpsertWorkspaceReleaseChannel(ctx context.Context, arg database.UpsertWorkspaceReleaseChannelParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceReleaseChannel", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceReleaseChannel indicates an expected call of UpsertWorkspaceReleaseChannel.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceReleaseChannel(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceReleaseChannel", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceReleaseChannel), ctx, arg)
+}
+
 // Wrappers mocks base method.
 func (m *MockStore) Wrappers() []string {
 	m.ctrl.T.Helper()
@@ -2,6 +2,9 @@ package dbpurge
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"io"
 	"time"
 
@@ -12,19 +15,128 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/quartz"
 )
 
 const (
-	delay          = 10 * time.Minute
-	maxAgentLogAge = 7 * 24 * time.Hour
+	delay = 10 * time.Minute
+	// defaultAgentStatsRetention and defaultWorkspaceAgentLogsRetention are
+	// used unless the purger is configured otherwise.
+	defaultAgentStatsRetention         = 24 * time.Hour
+	defaultWorkspaceAgentLogsRetention = 7 * 24 * time.Hour
+	// jobLogsBatchSize is the maximum number of jobs whose logs are deleted
+	// per tick.
+	jobLogsBatchSize = 1000
+	// auditLogsBatchSize is the maximum number of audit logs deleted per
+	// tick, in addition to those in dropped partitions.
+	auditLogsBatchSize = 10000
+	// maxAgentResourceUsageAge is how long agent resource usage is kept
+	// for right-sizing recommendations.
+	maxAgentResourceUsageAge = 90 * 24 * time.Hour
+	// maxAgentHealthEventAge is how long agent health events are kept after
+	// they were resolved, or reported if they never were.
+	maxAgentHealthEventAge = 30 * 24 * time.Hour
+	// maxAgentConnectionQualityAge is how long the connection quality of
+	// a peer is kept after it was last reported.
+	maxAgentConnectionQualityAge = 7 * 24 * time.Hour
+	// maxIdempotencyKeyAge is how long requests can be retried with the same
+	// idempotency key.
+	maxIdempotencyKeyAge = 24 * time.Hour
+	// partitionLookahead is how far ahead partitions of time partitioned
+	// tables are created, so that new rows are not written to the default
+	// partition.
+	partitionLookahead = 7 * 24 * time.Hour
 )
 
+// timePartitionedTables are partitioned by range on their creation time.
+// Rows that don't fall into any range partition are stored in the table's
+// default partition.
+var timePartitionedTables = []struct {
+	name    string
+	monthly bool
+}{
+	{name: "audit_logs", monthly: true},
+	{name: "provisioner_job_logs", monthly: true},
+	{name: "workspace_agent_stats", monthly: false},
+}
+
+type options struct {
+	deletedWorkspaceRetention time.Duration
+	sessionRecordingRetention time.Duration
+	retention                 map[codersdk.PurgeDataClass]time.Duration
+}
+
+// Option configures the purger.
+type Option func(*options)
+
+// WithDeletedWorkspaceRetention permanently removes deleted workspaces once
+// their delete build completed more than retention ago. By default, deleted
+// workspaces are kept so that they can be restored.
+func WithDeletedWorkspaceRetention(retention time.Duration) Option {
+	return func(o *options) {
+		o.deletedWorkspaceRetention = retention
+	}
+}
+
+// WithSessionRecordingRetention deletes recorded workspace sessions that
+// started more than retention ago. By default, recordings are kept forever.
+func WithSessionRecordingRetention(retention time.Duration) Option {
+	return func(o *options) {
+		o.sessionRecordingRetention = retention
+	}
+}
+
+// WithAgentStatsRetention deletes raw workspace agent stats created more than
+// retention ago. Stats are always kept until they were rolled up into
+// template usage stats.
+func WithAgentStatsRetention(retention time.Duration) Option {
+	return func(o *options) {
+		o.retention[codersdk.PurgeDataClassAgentStats] = retention
+	}
+}
+
+// WithWorkspaceAgentLogsRetention deletes the agent logs of builds that are
+// not the latest build of their workspace once the agent last connected more
+// than retention ago. 0 keeps them forever.
+func WithWorkspaceAgentLogsRetention(retention time.Duration) Option {
+	return func(o *options) {
+		o.retention[codersdk.PurgeDataClassWorkspaceAgentLogs] = retention
+	}
+}
+
+// WithJobLogsRetention deletes the logs of provisioner jobs that completed
+// more than retention ago, including archived logs. By default, job logs are
+// kept forever.
+func WithJobLogsRetention(retention time.Duration) Option {
+	return func(o *options) {
+		o.retention[codersdk.PurgeDataClassJobLogs] = retention
+	}
+}
+
+// WithAuditLogsRetention deletes audit logs older than retention. By default,
+// audit logs are kept forever.
+func WithAuditLogsRetention(retention time.Duration) Option {
+	return func(o *options) {
+		o.retention[codersdk.PurgeDataClassAuditLogs] = retention
+	}
+}
+
 // New creates a new periodically purging database instance.
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for cleaning up old, unused resources from the database that take up space.
-func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.Clock) io.Closer {
+func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.Clock, opts ...Option) io.Closer {
+	o := options{
+		retention: map[codersdk.PurgeDataClass]time.Duration{
+			codersdk.PurgeDataClassAgentStats:         defaultAgentStatsRetention,
+			codersdk.PurgeDataClassWorkspaceAgentLogs: defaultWorkspaceAgentLogsRetention,
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	closed := make(chan struct{})
 
 	ctx, cancelFunc := context.WithCancel(ctx)
@@ -49,19 +161,95 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.
 				return nil
 			}
 
-			deleteOldWorkspaceAgentLogsBefore := start.Add(-maxAgentLogAge)
-			if err := tx.DeleteOldWorkspaceAgentLogs(ctx, deleteOldWorkspaceAgentLogsBefore); err != nil {
-				return xerrors.Errorf("failed to delete old workspace agent logs: %w", err)
+			purged := make(map[codersdk.PurgeDataClass]codersdk.PurgeDataClassStats)
+			if retention := o.retention[codersdk.PurgeDataClassWorkspaceAgentLogs]; retention > 0 {
+				deleted, err := tx.DeleteOldWorkspaceAgentLogs(ctx, start.Add(-retention))
+				if err != nil {
+					return xerrors.Errorf("failed to delete old workspace agent logs: %w", err)
+				}
+				purged[codersdk.PurgeDataClassWorkspaceAgentLogs] = codersdk.PurgeDataClassStats{LastRunDeleted: deleted}
+			}
+			if err := tx.DeleteOldWorkspaceAgentResourceUsage(ctx, start.Add(-maxAgentResourceUsageAge)); err != nil {
+				return xerrors.Errorf("failed to delete old workspace agent resource usage: %w", err)
+			}
+			if err := tx.DeleteOldWorkspaceAgentHealthEvents(ctx, start.Add(-maxAgentHealthEventAge)); err != nil {
+				return xerrors.Errorf("failed to delete old workspace agent health events: %w", err)
 			}
-			if err := tx.DeleteOldWorkspaceAgentStats(ctx); err != nil {
+			if err := tx.DeleteOldWorkspaceAgentConnectionQuality(ctx, start.Add(-maxAgentConnectionQualityAge)); err != nil {
+				return xerrors.Errorf("failed to delete old workspace agent connection quality: %w", err)
+			}
+			agentStatsBefore := start.Add(-o.retention[codersdk.PurgeDataClassAgentStats])
+			dropped, err := tx.DropOldWorkspaceAgentStatsPartitions(ctx, agentStatsBefore)
+			if err != nil {
+				return xerrors.Errorf("failed to drop old workspace agent stats partitions: %w", err)
+			}
+			deleted, err := tx.DeleteOldWorkspaceAgentStats(ctx, agentStatsBefore)
+			if err != nil {
 				return xerrors.Errorf("failed to delete old workspace agent stats: %w", err)
 			}
+			purged[codersdk.PurgeDataClassAgentStats] = codersdk.PurgeDataClassStats{
+				LastRunDeleted:           deleted,
+				LastRunDroppedPartitions: int64(dropped),
+			}
+			if retention := o.retention[codersdk.PurgeDataClassJobLogs]; retention > 0 {
+				deleted, err := tx.DeleteOldProvisionerJobLogs(ctx, database.DeleteOldProvisionerJobLogsParams{
+					CompletedBefore: start.Add(-retention),
+					LimitCount:      jobLogsBatchSize,
+				})
+				if err != nil {
+					return xerrors.Errorf("failed to delete old provisioner job logs: %w", err)
+				}
+				purged[codersdk.PurgeDataClassJobLogs] = codersdk.PurgeDataClassStats{LastRunDeleted: deleted}
+			}
+			if retention := o.retention[codersdk.PurgeDataClassAuditLogs]; retention > 0 {
+				dropped, err := tx.DropOldAuditLogsPartitions(ctx, start.Add(-retention))
+				if err != nil {
+					return xerrors.Errorf("failed to drop old audit logs partitions: %w", err)
+				}
+				deleted, err := tx.DeleteOldAuditLogs(ctx, database.DeleteOldAuditLogsParams{
+					Before:     start.Add(-retention),
+					LimitCount: auditLogsBatchSize,
+				})
+				if err != nil {
+					return xerrors.Errorf("failed to delete old audit logs: %w", err)
+				}
+				purged[codersdk.PurgeDataClassAuditLogs] = codersdk.PurgeDataClassStats{
+					LastRunDeleted:           deleted,
+					LastRunDroppedPartitions: int64(dropped),
+				}
+			}
 			if err := tx.DeleteOldProvisionerDaemons(ctx); err != nil {
 				return xerrors.Errorf("failed to delete old provisioner daemons: %w", err)
 			}
 			if err := tx.DeleteOldNotificationMessages(ctx); err != nil {
 				return xerrors.Errorf("failed to delete old notification messages: %w", err)
 			}
+			if err := tx.DeleteExpiredLoginDeviceCodes(ctx, start); err != nil {
+				return xerrors.Errorf("failed to delete expired login device codes: %w", err)
+			}
+			if err := tx.DeleteOldWorkspaceBuildIdempotencyKeys(ctx, start.Add(-maxIdempotencyKeyAge)); err != nil {
+				return xerrors.Errorf("failed to delete old workspace build idempotency keys: %w", err)
+			}
+			if o.deletedWorkspaceRetention > 0 {
+				purged, err := tx.DeleteOldDeletedWorkspaces(ctx, start.Add(-o.deletedWorkspaceRetention))
+				if err != nil {
+					return xerrors.Errorf("failed to delete old deleted workspaces: %w", err)
+				}
+				if purged > 0 {
+					logger.Info(ctx, "permanently removed deleted workspaces past their retention", slog.F("count", purged))
+				}
+			}
+			if o.sessionRecordingRetention > 0 {
+				if err := tx.DeleteOldWorkspaceSessionRecordings(ctx, start.Add(-o.sessionRecordingRetention)); err != nil {
+					return xerrors.Errorf("failed to delete old workspace session recordings: %w", err)
+				}
+			}
+			if err := createTimePartitions(ctx, tx, start); err != nil {
+				return xerrors.Errorf("failed to create time partitions: %w", err)
+			}
+			if err := updateStats(ctx, tx, start, o, purged); err != nil {
+				return xerrors.Errorf("failed to update purge stats: %w", err)
+			}
 
 			logger.Debug(ctx, "purged old database entries", slog.F("duration", clk.Since(start)))
 
@@ -93,6 +281,78 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.
 	}
 }
 
+// updateStats records what a purge deleted from each data class, adding it to
+// the totals of all previous purges.
+func updateStats(ctx context.Context, db database.Store, now time.Time, o options, purged map[codersdk.PurgeDataClass]codersdk.PurgeDataClassStats) error {
+	var previous codersdk.PurgeStats
+	raw, err := db.GetDBPurgeStats(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get purge stats: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(raw), &previous); err != nil {
+			return xerrors.Errorf("unmarshal purge stats: %w", err)
+		}
+	}
+	totals := make(map[codersdk.PurgeDataClass]int64, len(previous.DataClasses))
+	for _, class := range previous.DataClasses {
+		totals[class.DataClass] = class.TotalDeleted
+	}
+
+	stats := codersdk.PurgeStats{
+		LastRunAt:   &now,
+		DataClasses: make([]codersdk.PurgeDataClassStats, 0, len(codersdk.PurgeDataClasses)),
+	}
+	for _, class := range codersdk.PurgeDataClasses {
+		classStats := purged[class]
+		classStats.DataClass = class
+		classStats.RetentionMillis = o.retention[class].Milliseconds()
+		classStats.TotalDeleted = totals[class] + classStats.LastRunDeleted
+		stats.DataClasses = append(stats.DataClasses, classStats)
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return xerrors.Errorf("marshal purge stats: %w", err)
+	}
+	return db.UpsertDBPurgeStats(ctx, string(data))
+}
+
+// createTimePartitions creates the partitions of all time partitioned tables
+// from the one containing now up to partitionLookahead ahead.
+func createTimePartitions(ctx context.Context, db database.Store, now time.Time) error {
+	for _, table := range timePartitionedTables {
+		for start := partitionStart(now, table.monthly); !start.After(now.Add(partitionLookahead)); start = partitionEnd(start, table.monthly) {
+			_, err := db.CreateTimePartition(ctx, database.CreateTimePartitionParams{
+				ParentTable:    table.name,
+				PartitionStart: start,
+				PartitionEnd:   partitionEnd(start, table.monthly),
+			})
+			if err != nil {
+				return xerrors.Errorf("create %s partition starting at %s: %w", table.name, start.Format(time.DateOnly), err)
+			}
+		}
+	}
+	return nil
+}
+
+// partitionStart returns the start of the daily or monthly partition
+// containing t. Partition boundaries are in UTC.
+func partitionStart(t time.Time, monthly bool) time.Time {
+	t = t.UTC()
+	if monthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// partitionEnd returns the end of the partition starting at start.
+func partitionEnd(start time.Time, monthly bool) time.Time {
+	if monthly {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
 type instance struct {
 	cancel context.CancelFunc
 	closed chan struct{}
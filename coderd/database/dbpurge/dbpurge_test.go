@@ -20,6 +20,7 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbpurge"
 	"github.com/coder/coder/v2/coderd/database/dbrollup"
@@ -485,6 +486,570 @@ func TestDeleteOldProvisionerDaemons(t *testing.T) {
 	}, testutil.WaitShort, testutil.IntervalSlow)
 }
 
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldDeletedWorkspaces(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	retention := 24 * time.Hour
+	clk.Set(now.Add(2 * retention)).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	tv := dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, CreatedBy: user.ID})
+	tpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, ActiveVersionID: tv.ID, CreatedBy: user.ID})
+
+	// Given: a workspace deleted before the retention period, one deleted
+	// within it, and one that is not deleted.
+	newWorkspace := func(deleted bool) dbfake.WorkspaceResponse {
+		resp := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			OwnerID:        user.ID,
+			TemplateID:     tpl.ID,
+		}).Seed(database.WorkspaceBuild{
+			TemplateVersionID: tv.ID,
+			Transition:        database.WorkspaceTransitionDelete,
+		}).Do()
+		err := db.UpdateWorkspaceDeletedByID(ctx, database.UpdateWorkspaceDeletedByIDParams{
+			ID:      resp.Workspace.ID,
+			Deleted: deleted,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+	expired := newWorkspace(true)
+	retained := newWorkspace(true)
+	active := newWorkspace(false)
+	err := db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+		ID:          retained.Build.JobID,
+		UpdatedAt:   now.Add(retention + time.Hour),
+		CompletedAt: sql.NullTime{Time: now.Add(retention + time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk, dbpurge.WithDeletedWorkspaceRetention(retention))
+	defer closer.Close()
+	<-done
+
+	// Then: only the workspace deleted before the retention period is removed,
+	// along with its build and provisioner job.
+	_, err = db.GetWorkspaceByID(ctx, expired.Workspace.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = db.GetWorkspaceBuildByID(ctx, expired.Build.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = db.GetProvisionerJobByID(ctx, expired.Build.JobID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	for _, ws := range []dbfake.WorkspaceResponse{retained, active} {
+		_, err = db.GetWorkspaceByID(ctx, ws.Workspace.ID)
+		require.NoError(t, err)
+		_, err = db.GetProvisionerJobByID(ctx, ws.Build.JobID)
+		require.NoError(t, err)
+	}
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldWorkspaceAgentResourceUsage(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+
+	// Given: resource usage older and newer than 90 days.
+	for _, bucketStart := range []time.Time{now.Add(-91 * 24 * time.Hour), now.Add(-89 * 24 * time.Hour)} {
+		err := db.UpsertWorkspaceAgentResourceUsage(ctx, database.UpsertWorkspaceAgentResourceUsageParams{
+			AgentID:     agents[0].ID,
+			BucketStart: bucketStart.Truncate(15 * time.Minute),
+			CPUUsed:     1,
+			CPUTotal:    2,
+		})
+		require.NoError(t, err)
+	}
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk)
+	defer closer.Close()
+	<-done
+
+	// Then: only the usage newer than 90 days remains.
+	usage, err := db.GetWorkspaceAgentResourceUsageByWorkspaceID(ctx, database.GetWorkspaceAgentResourceUsageByWorkspaceIDParams{
+		WorkspaceID: ws.Workspace.ID,
+		StartTime:   now.Add(-365 * 24 * time.Hour),
+		EndTime:     now,
+	})
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	require.WithinDuration(t, now.Add(-89*24*time.Hour), usage[0].BucketStart, 15*time.Minute)
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldWorkspaceAgentHealthEvents(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+
+	// Given: ongoing events reported before and after 30 days ago.
+	for _, event := range []struct {
+		kind      database.WorkspaceAgentHealthEventKind
+		createdAt time.Time
+	}{
+		{kind: database.WorkspaceAgentHealthEventKindNearOutOfMemory, createdAt: now.Add(-31 * 24 * time.Hour)},
+		{kind: database.WorkspaceAgentHealthEventKindLowDiskSpace, createdAt: now.Add(-29 * 24 * time.Hour)},
+	} {
+		_, err := db.InsertWorkspaceAgentHealthEvent(ctx, database.InsertWorkspaceAgentHealthEventParams{
+			ID:        uuid.New(),
+			AgentID:   agents[0].ID,
+			Kind:      event.kind,
+			Used:      99,
+			Total:     100,
+			CreatedAt: event.createdAt,
+		})
+		require.NoError(t, err)
+	}
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk)
+	defer closer.Close()
+	<-done
+
+	// Then: only the event reported within 30 days remains.
+	events, err := db.GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx, []uuid.UUID{agents[0].ID})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, database.WorkspaceAgentHealthEventKindLowDiskSpace, events[0].Kind)
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldWorkspaceSessionRecordings(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+
+	// Given: recordings started before and after the retention period, each
+	// with some output.
+	const retention = 7 * 24 * time.Hour
+	expired := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		WorkspaceID: ws.Workspace.ID,
+		AgentID:     agents[0].ID,
+		StartedAt:   now.Add(-retention - time.Hour),
+	})
+	retained := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		WorkspaceID: ws.Workspace.ID,
+		AgentID:     agents[0].ID,
+		StartedAt:   now.Add(-retention + time.Hour),
+	})
+	for _, recording := range []database.WorkspaceSessionRecording{expired, retained} {
+		_, err := db.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			CreatedAt:   recording.StartedAt,
+			Data:        []byte("hello"),
+		})
+		require.NoError(t, err)
+	}
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk, dbpurge.WithSessionRecordingRetention(retention))
+	defer closer.Close()
+	<-done
+
+	// Then: only the recording started within the retention period remains,
+	// and the output of the expired one is gone.
+	recordings, err := db.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, recordings, 1)
+	require.Equal(t, retained.ID, recordings[0].ID)
+	chunks, err := db.GetWorkspaceSessionRecordingChunks(ctx, expired.ID)
+	require.NoError(t, err)
+	require.Empty(t, chunks)
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldProvisionerJobLogs(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	user := dbgen.User(t, db, database.User{})
+
+	// Given: a job that completed before the retention period with logs, one
+	// with archived logs, and one that completed within the retention period.
+	const retention = 30 * 24 * time.Hour
+	newJob := func(completedAt time.Time) database.ProvisionerJob {
+		return dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			InitiatorID: user.ID,
+			StartedAt:   sql.NullTime{Time: completedAt.Add(-time.Minute), Valid: true},
+			CompletedAt: sql.NullTime{Time: completedAt, Valid: true},
+		})
+	}
+	expired := newJob(now.Add(-retention - time.Hour))
+	archived := newJob(now.Add(-retention - time.Hour))
+	retained := newJob(now.Add(-retention + time.Hour))
+	for _, job := range []database.ProvisionerJob{expired, retained} {
+		_, err := db.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+			JobID:     job.ID,
+			CreatedAt: []time.Time{job.CompletedAt.Time, job.CompletedAt.Time},
+			Source:    []database.LogSource{database.LogSourceProvisioner, database.LogSourceProvisioner},
+			Level:     []database.LogLevel{database.LogLevelInfo, database.LogLevelInfo},
+			Stage:     []string{"Planning", "Planning"},
+			Output:    []string{"one", "two"},
+			Fields:    []string{"{}", "{}"},
+		})
+		require.NoError(t, err)
+	}
+	file := dbgen.File(t, db, database.File{CreatedBy: user.ID, Mimetype: "application/gzip"})
+	_, err := db.InsertProvisionerJobLogArchive(ctx, database.InsertProvisionerJobLogArchiveParams{
+		JobID:      archived.ID,
+		FileID:     file.ID,
+		LogCount:   3,
+		ArchivedAt: now,
+	})
+	require.NoError(t, err)
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk, dbpurge.WithJobLogsRetention(retention))
+	defer closer.Close()
+	<-done
+
+	// Then: the logs of the expired jobs are gone, including the archive and
+	// its file.
+	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{JobID: expired.ID})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+	_, err = db.GetProvisionerJobLogArchiveByJobID(ctx, archived.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = db.GetFileByID(ctx, file.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// And: the logs of the job within the retention period remain.
+	logs, err = db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{JobID: retained.ID})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+
+	// And: the stats count archived logs as deleted.
+	stats := purgeStats(ctx, t, db)
+	require.EqualValues(t, 5, stats[codersdk.PurgeDataClassJobLogs].TotalDeleted)
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldAuditLogs(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	// Given: audit logs from before and within the retention period.
+	const retention = 90 * 24 * time.Hour
+	expired := dbgen.AuditLog(t, db, database.AuditLog{Time: now.Add(-retention - time.Hour)})
+	retained := dbgen.AuditLog(t, db, database.AuditLog{Time: now.Add(-retention + time.Hour)})
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk, dbpurge.WithAuditLogsRetention(retention))
+	defer closer.Close()
+	<-done
+
+	// Then: only the audit log within the retention period remains.
+	logs, err := db.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{LimitOpt: 10})
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, 0, len(logs))
+	for _, log := range logs {
+		ids = append(ids, log.AuditLog.ID)
+	}
+	require.NotContains(t, ids, expired.ID)
+	require.Contains(t, ids, retained.ID)
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestPurgeStats(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	// Given: an audit log past its retention.
+	const retention = 24 * time.Hour
+	_ = dbgen.AuditLog(t, db, database.AuditLog{Time: now.Add(-retention - time.Hour)})
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk, dbpurge.WithAuditLogsRetention(retention))
+	<-done
+	require.NoError(t, closer.Close())
+
+	// Then: the run and its retention are recorded for every data class.
+	raw, err := db.GetDBPurgeStats(ctx)
+	require.NoError(t, err)
+	var stats codersdk.PurgeStats
+	require.NoError(t, json.Unmarshal([]byte(raw), &stats))
+	require.NotNil(t, stats.LastRunAt)
+	require.False(t, stats.LastRunAt.Before(now))
+	require.Len(t, stats.DataClasses, len(codersdk.PurgeDataClasses))
+	classes := purgeStats(ctx, t, db)
+	require.Equal(t, (24 * time.Hour).Milliseconds(), classes[codersdk.PurgeDataClassAgentStats].RetentionMillis)
+	require.Equal(t, (7 * 24 * time.Hour).Milliseconds(), classes[codersdk.PurgeDataClassWorkspaceAgentLogs].RetentionMillis)
+	require.Zero(t, classes[codersdk.PurgeDataClassJobLogs].RetentionMillis)
+	require.Equal(t, retention.Milliseconds(), classes[codersdk.PurgeDataClassAuditLogs].RetentionMillis)
+	require.EqualValues(t, 1, classes[codersdk.PurgeDataClassAuditLogs].TotalDeleted)
+
+	// When: dbpurge runs again after another audit log expired.
+	_ = dbgen.AuditLog(t, db, database.AuditLog{Time: now.Add(-retention - time.Hour)})
+	done = awaitDoTick(ctx, t, clk)
+	closer = dbpurge.New(ctx, logger, db, clk, dbpurge.WithAuditLogsRetention(retention))
+	<-done
+	require.NoError(t, closer.Close())
+
+	// Then: the totals include all runs.
+	classes = purgeStats(ctx, t, db)
+	require.EqualValues(t, 2, classes[codersdk.PurgeDataClassAuditLogs].TotalDeleted)
+}
+
+func purgeStats(ctx context.Context, t *testing.T, db database.Store) map[codersdk.PurgeDataClass]codersdk.PurgeDataClassStats {
+	t.Helper()
+
+	raw, err := db.GetDBPurgeStats(ctx)
+	require.NoError(t, err)
+	var stats codersdk.PurgeStats
+	require.NoError(t, json.Unmarshal([]byte(raw), &stats))
+	classes := make(map[codersdk.PurgeDataClass]codersdk.PurgeDataClassStats, len(stats.DataClasses))
+	for _, class := range stats.DataClasses {
+		classes[class.DataClass] = class
+	}
+	return classes
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldWorkspaceAgentConnectionQuality(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+
+	// Given: peers last reported before and after 7 days ago.
+	for _, quality := range []struct {
+		ip        string
+		updatedAt time.Time
+	}{
+		{ip: "fd7a:115c:a1e0::1", updatedAt: now.Add(-8 * 24 * time.Hour)},
+		{ip: "fd7a:115c:a1e0::2", updatedAt: now.Add(-6 * 24 * time.Hour)},
+	} {
+		err := db.UpsertWorkspaceAgentConnectionQuality(ctx, database.UpsertWorkspaceAgentConnectionQualityParams{
+			AgentID:   agents[0].ID,
+			Ip:        quality.ip,
+			P2P:       true,
+			PingsSent: 1,
+			UpdatedAt: quality.updatedAt,
+		})
+		require.NoError(t, err)
+	}
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk)
+	defer closer.Close()
+	<-done
+
+	// Then: only the peer reported within 7 days remains.
+	qualities, err := db.GetWorkspaceAgentConnectionQualityByAgentID(ctx, agents[0].ID)
+	require.NoError(t, err)
+	require.Len(t, qualities, 1)
+	require.Equal(t, "fd7a:115c:a1e0::2", qualities[0].Ip)
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteExpiredLoginDeviceCodes(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	// Given: a device code that expired and one that has not.
+	expired := dbgen.LoginDeviceCode(t, db, database.LoginDeviceCode{
+		CreatedAt: now.Add(-20 * time.Minute),
+		ExpiresAt: now.Add(-10 * time.Minute),
+	})
+	pending := dbgen.LoginDeviceCode(t, db, database.LoginDeviceCode{
+		CreatedAt: now,
+		ExpiresAt: now.Add(10 * time.Minute),
+	})
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk)
+	defer closer.Close()
+	<-done
+
+	// Then: only the code that has not expired remains.
+	_, err := db.GetLoginDeviceCodeByDeviceCodeHash(ctx, expired.DeviceCodeHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = db.GetLoginDeviceCodeByDeviceCodeHash(ctx, pending.DeviceCodeHash)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldWorkspaceBuildIdempotencyKeys(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	user := dbgen.User(t, db, database.User{})
+
+	// Given: a key used two days ago and one used an hour ago.
+	for key, createdAt := range map[string]time.Time{
+		"old":    now.Add(-48 * time.Hour),
+		"recent": now.Add(-time.Hour),
+	} {
+		err := db.InsertWorkspaceBuildIdempotencyKey(ctx, database.InsertWorkspaceBuildIdempotencyKeyParams{
+			UserID:         user.ID,
+			IdempotencyKey: key,
+			RequestHash:    []byte(key),
+			CreatedAt:      createdAt,
+		})
+		require.NoError(t, err)
+	}
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk)
+	defer closer.Close()
+	<-done
+
+	// Then: only the recent key remains.
+	_, err := db.GetWorkspaceBuildIdempotencyKey(ctx, database.GetWorkspaceBuildIdempotencyKeyParams{
+		UserID:         user.ID,
+		IdempotencyKey: "old",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = db.GetWorkspaceBuildIdempotencyKey(ctx, database.GetWorkspaceBuildIdempotencyKeyParams{
+		UserID:         user.ID,
+		IdempotencyKey: "recent",
+	})
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestTimePartitions(t *testing.T) {
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("Skipping test; only works with PostgreSQL.")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	// Given: an agent stats partition that is past the retention period.
+	oldStart := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldPartition := database.CreateTimePartitionParams{
+		ParentTable:    "workspace_agent_stats",
+		PartitionStart: oldStart,
+		PartitionEnd:   oldStart.AddDate(0, 0, 1),
+	}
+	created, err := db.CreateTimePartition(ctx, oldPartition)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	// When: dbpurge runs.
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk)
+	defer closer.Close()
+	<-done
+
+	// Then: partitions were created up to a week ahead.
+	ahead := now.Add(7 * 24 * time.Hour).UTC()
+	nextDay := time.Date(ahead.Year(), ahead.Month(), ahead.Day(), 0, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(ahead.Year(), ahead.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, partition := range []database.CreateTimePartitionParams{
+		{ParentTable: "audit_logs", PartitionStart: nextMonth, PartitionEnd: nextMonth.AddDate(0, 1, 0)},
+		{ParentTable: "provisioner_job_logs", PartitionStart: nextMonth, PartitionEnd: nextMonth.AddDate(0, 1, 0)},
+		{ParentTable: "workspace_agent_stats", PartitionStart: nextDay, PartitionEnd: nextDay.AddDate(0, 0, 1)},
+	} {
+		created, err := db.CreateTimePartition(ctx, partition)
+		require.NoError(t, err)
+		require.False(t, created, "%s partition starting at %s should exist", partition.ParentTable, partition.PartitionStart)
+	}
+
+	// And: the old agent stats partition was dropped.
+	created, err = db.CreateTimePartition(ctx, oldPartition)
+	require.NoError(t, err)
+	require.True(t, created, "old partition should have been dropped")
+}
+
 func containsProvisionerDaemon(daemons []database.ProvisionerDaemon, name string) bool {
 	return slices.ContainsFunc(daemons, func(d database.ProvisionerDaemon) bool {
 		return d.Name == name
@@ -21,8 +21,10 @@ const (
 )
 
 type Event struct {
-	Init               bool `json:"-"`
-	TemplateUsageStats bool `json:"template_usage_stats"`
+	Init                       bool `json:"-"`
+	TemplateUsageStats         bool `json:"template_usage_stats"`
+	TemplateBuildDurationStats bool `json:"template_build_duration_stats"`
+	WorkspaceDailyCosts        bool `json:"workspace_daily_costs"`
 }
 
 type Rolluper struct {
@@ -59,7 +61,10 @@ func WithEventChannel(ch chan<- Event) Option {
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for e.g. generating insights data (template_usage_stats) from
-// raw data (workspace_agent_stats, workspace_app_stats).
+// raw data (workspace_agent_stats, workspace_app_stats), build duration
+// percentiles (template_build_duration_stats) from workspace builds, and the
+// cost accrued by workspaces (workspace_daily_costs) from the daily cost of
+// their resources.
 func New(logger slog.Logger, db database.Store, opts ...Option) *Rolluper {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -107,7 +112,17 @@ func (r *Rolluper) start(ctx context.Context) {
 				}
 
 				ev.TemplateUsageStats = true
-				return tx.UpsertTemplateUsageStats(ctx)
+				if err := tx.UpsertTemplateUsageStats(ctx); err != nil {
+					return err
+				}
+
+				ev.TemplateBuildDurationStats = true
+				if err := tx.UpsertTemplateBuildDurationStats(ctx); err != nil {
+					return err
+				}
+
+				ev.WorkspaceDailyCosts = true
+				return tx.UpsertWorkspaceDailyCosts(ctx)
 			}, database.DefaultTXOptions().WithID("db_rollup"))
 		})
 
@@ -261,3 +261,154 @@ func TestRollupTemplateUsageStats(t *testing.T) {
 		},
 	}, stats[0])
 }
+
+func TestRollupTemplateBuildDurationStats(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	yesterday := dbtime.Now().UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{Name: "user1"})
+		tpl  = dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+		ver  = dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}, CreatedBy: user.ID})
+		ws   = dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: org.ID, TemplateID: tpl.ID, OwnerID: user.ID})
+	)
+
+	build := func(number int32, took time.Duration, jobErr string) {
+		startedAt := yesterday.Add(time.Duration(number) * time.Hour)
+		job := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			StartedAt:      sql.NullTime{Time: startedAt, Valid: true},
+			CompletedAt:    sql.NullTime{Time: startedAt.Add(took), Valid: true},
+			Error:          sql.NullString{String: jobErr, Valid: jobErr != ""},
+		})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       ws.ID,
+			JobID:             job.ID,
+			TemplateVersionID: ver.ID,
+			BuildNumber:       number,
+			Transition:        database.WorkspaceTransitionStart,
+		})
+	}
+	build(1, time.Minute, "")
+	build(2, 2*time.Minute, "")
+	build(3, 3*time.Minute, "")
+	// Failed builds are excluded.
+	build(4, 10*time.Minute, "failed")
+
+	// The data is already present, so we can rely on initial rollup to occur.
+	events := make(chan dbrollup.Event, 1)
+	rolluper := dbrollup.New(logger, db, dbrollup.WithInterval(250*time.Millisecond), dbrollup.WithEventChannel(events))
+	defer rolluper.Close()
+
+	<-events // Deplete init event, resume operation.
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for rollup to occur")
+	case ev := <-events:
+		require.True(t, ev.TemplateBuildDurationStats, "expected template build duration stats to be rolled up")
+	}
+
+	stats, err := db.GetTemplateBuildDurationStats(ctx, database.GetTemplateBuildDurationStatsParams{
+		TemplateID: tpl.ID,
+		StartTime:  yesterday.Add(-24 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+
+	stats[0].StartTime = stats[0].StartTime.UTC()
+	stats[0].EndTime = stats[0].EndTime.UTC()
+
+	require.Equal(t, database.TemplateBuildDurationStat{
+		StartTime:     yesterday,
+		EndTime:       yesterday.Add(24 * time.Hour),
+		TemplateID:    tpl.ID,
+		Transition:    database.WorkspaceTransitionStart,
+		BuildCount:    3,
+		P50DurationMs: (2 * time.Minute).Milliseconds(),
+		P95DurationMs: (3 * time.Minute).Milliseconds(),
+		MaxDurationMs: (3 * time.Minute).Milliseconds(),
+	}, stats[0])
+}
+
+func TestRollupWorkspaceDailyCosts(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	yesterday := dbtime.Now().UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{Name: "user1"})
+		tpl  = dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+		ver  = dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}, CreatedBy: user.ID})
+		ws   = dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: org.ID, TemplateID: tpl.ID, OwnerID: user.ID})
+	)
+
+	build := func(number int32, completedAt time.Time, transition database.WorkspaceTransition, dailyCost int32, jobErr string) {
+		job := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			StartedAt:      sql.NullTime{Time: completedAt.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: completedAt, Valid: true},
+			Error:          sql.NullString{String: jobErr, Valid: jobErr != ""},
+		})
+		_ = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{
+			JobID:      job.ID,
+			Transition: transition,
+			DailyCost:  dailyCost,
+		})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       ws.ID,
+			JobID:             job.ID,
+			TemplateVersionID: ver.ID,
+			BuildNumber:       number,
+			Transition:        transition,
+		})
+	}
+	// Running for 12 hours, then stopped for 6 hours with only a volume left.
+	build(1, yesterday.Add(6*time.Hour), database.WorkspaceTransitionStart, 10, "")
+	// Failed builds don't change the cost.
+	build(2, yesterday.Add(12*time.Hour), database.WorkspaceTransitionStart, 100, "failed")
+	build(3, yesterday.Add(18*time.Hour), database.WorkspaceTransitionStop, 2, "")
+
+	// The data is already present, so we can rely on initial rollup to occur.
+	events := make(chan dbrollup.Event, 1)
+	rolluper := dbrollup.New(logger, db, dbrollup.WithInterval(250*time.Millisecond), dbrollup.WithEventChannel(events))
+	defer rolluper.Close()
+
+	<-events // Deplete init event, resume operation.
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for rollup to occur")
+	case ev := <-events:
+		require.True(t, ev.WorkspaceDailyCosts, "expected workspace daily costs to be rolled up")
+	}
+
+	costs, err := db.GetWorkspaceDailyCosts(ctx, database.GetWorkspaceDailyCostsParams{
+		StartTime: yesterday,
+		EndTime:   yesterday.Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, costs, 1)
+
+	require.True(t, yesterday.Equal(costs[0].StartTime))
+	require.True(t, yesterday.Add(24*time.Hour).Equal(costs[0].EndTime))
+	require.Equal(t, ws.ID, costs[0].WorkspaceID)
+	require.Equal(t, ws.Name, costs[0].WorkspaceName)
+	require.Equal(t, user.Username, costs[0].OwnerUsername)
+	require.Equal(t, org.Name, costs[0].OrganizationName)
+	require.Equal(t, tpl.ID, costs[0].TemplateID)
+	require.InDelta(t, 10*12.0/24+2*6.0/24, costs[0].Cost, 0.001)
+}
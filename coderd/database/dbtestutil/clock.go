@@ -0,0 +1,21 @@
+package dbtestutil
+
+import (
+	"testing"
+
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/quartz"
+)
+
+// NewClock returns a mock clock for background services under test, such as
+// the job reaper and the autobuild executor. It starts at the current time
+// rounded to the precision of the database, so timestamps the services write
+// with it read back unchanged, and tests can compare them exactly. Time only
+// moves when the test advances the clock.
+func NewClock(t testing.TB) *quartz.Mock {
+	t.Helper()
+
+	clock := quartz.NewMock(t)
+	clock.Set(dbtime.Now()).MustWait(t.Context())
+	return clock
+}
@@ -1,5 +1,17 @@
 -- Code generated by 'make coderd/database/generate'. DO NOT EDIT.
 
+CREATE TYPE access_request_resource_type AS ENUM (
+    'workspace',
+    'template'
+);
+
+CREATE TYPE access_request_status AS ENUM (
+    'pending',
+    'approved',
+    'denied',
+    'expired'
+);
+
 CREATE TYPE agent_id_name_pair AS (
 	id uuid,
 	name text
@@ -12,14 +24,18 @@ CREATE TYPE agent_key_scope_enum AS ENUM (
 
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'workspace:read',
+    'build:create',
+    'template:admin'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
     'owner',
     'authenticated',
     'organization',
-    'public'
+    'public',
+    'restricted'
 );
 
 CREATE TYPE audit_action AS ENUM (
@@ -43,6 +59,11 @@ CREATE TYPE automatic_updates AS ENUM (
     'never'
 );
 
+CREATE TYPE build_alert_rule_metric AS ENUM (
+    'failure_rate',
+    'queue_wait_p95'
+);
+
 CREATE TYPE build_reason AS ENUM (
     'initiator',
     'autostart',
@@ -114,7 +135,8 @@ CREATE TYPE notification_message_status AS ENUM (
     'permanent_failure',
     'temporary_failure',
     'unknown',
-    'inhibited'
+    'inhibited',
+    'batched'
 );
 
 CREATE TYPE notification_method AS ENUM (
@@ -218,6 +240,11 @@ CREATE TYPE provisioner_type AS ENUM (
     'terraform'
 );
 
+CREATE TYPE release_channel AS ENUM (
+    'stable',
+    'beta'
+);
+
 CREATE TYPE resource_type AS ENUM (
     'organization',
     'template',
@@ -242,7 +269,11 @@ CREATE TYPE resource_type AS ENUM (
     'idp_sync_settings_group',
     'idp_sync_settings_role',
     'workspace_agent',
-    'workspace_app'
+    'workspace_app',
+    'read_only_settings',
+    'provisioner_build_pause',
+    'access_request',
+    'login_device_code'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -259,6 +290,17 @@ CREATE TYPE tailnet_status AS ENUM (
     'lost'
 );
 
+CREATE TYPE user_activity_export_format AS ENUM (
+    'csv',
+    'parquet'
+);
+
+CREATE TYPE user_activity_export_status AS ENUM (
+    'pending',
+    'completed',
+    'failed'
+);
+
 CREATE TYPE user_status AS ENUM (
     'active',
     'suspended',
@@ -267,6 +309,11 @@ CREATE TYPE user_status AS ENUM (
 
 COMMENT ON TYPE user_status IS 'Defines the users status: active, dormant, or suspended.';
 
+CREATE TYPE workspace_agent_health_event_kind AS ENUM (
+    'near_out_of_memory',
+    'low_disk_space'
+);
+
 CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'created',
     'starting',
@@ -328,12 +375,49 @@ CREATE TYPE workspace_app_status_state AS ENUM (
     'idle'
 );
 
+CREATE TYPE workspace_migration_status AS ENUM (
+    'pending',
+    'stopping',
+    'starting',
+    'succeeded',
+    'failed'
+);
+
+CREATE TYPE workspace_name_uniqueness_scope AS ENUM (
+    'owner',
+    'organization'
+);
+
+CREATE TYPE workspace_session_recording_type AS ENUM (
+    'ssh',
+    'reconnecting_pty'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
     'delete'
 );
 
+CREATE FUNCTION batch_notification_if_digest() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+	-- Hold the message back for the digest if the user opted into digests
+	-- for this template. Inbox notifications are still delivered right away.
+	IF NEW.method != 'inbox'::notification_method AND EXISTS (
+		SELECT 1 FROM notification_preferences
+		WHERE notification_preferences.notification_template_id = NEW.notification_template_id
+			AND notification_preferences.user_id = NEW.user_id
+			AND notification_preferences.digest = TRUE
+	) THEN
+		NEW.status := 'batched'::notification_message_status;
+	END IF;
+
+	RETURN NEW;
+END;
+$$;
+
 CREATE FUNCTION check_workspace_agent_name_unique() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -372,6 +456,48 @@ BEGIN
 END;
 $$;
 
+CREATE FUNCTION check_workspace_name_organization_unique() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+	scope workspace_name_uniqueness_scope;
+BEGIN
+	IF NEW.deleted THEN
+		RETURN NEW;
+	END IF;
+
+	-- A template policy replaces the organization policy when it restricts
+	-- anything.
+	SELECT uniqueness_scope INTO scope
+	FROM workspace_naming_policies
+	WHERE template_id = NEW.template_id
+		AND (regex != '' OR prefix != '' OR suffix != '' OR uniqueness_scope = 'organization');
+	IF NOT FOUND THEN
+		SELECT uniqueness_scope INTO scope
+		FROM workspace_naming_policies
+		WHERE organization_id = NEW.organization_id AND template_id IS NULL;
+	END IF;
+	IF scope IS DISTINCT FROM 'organization' THEN
+		RETURN NEW;
+	END IF;
+
+	-- Serialize concurrent inserts and renames to the same name, so both
+	-- can't pass the check before either commits.
+	PERFORM pg_advisory_xact_lock(hashtextextended(NEW.organization_id::text || '/' || lower(NEW.name), 0));
+	IF EXISTS (
+		SELECT 1 FROM workspaces
+		WHERE organization_id = NEW.organization_id
+			AND lower(name) = lower(NEW.name)
+			AND id != NEW.id
+			AND deleted = false
+	) THEN
+		RAISE EXCEPTION 'workspace "%" already exists in the organization', NEW.name
+			USING ERRCODE = 'unique_violation', CONSTRAINT = 'workspaces_organization_name_unique', TABLE = 'workspaces';
+	END IF;
+	RETURN NEW;
+END;
+$$;
+
 CREATE FUNCTION compute_notification_message_dedupe_hash() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -390,6 +516,41 @@ $$;
 
 COMMENT ON FUNCTION compute_notification_message_dedupe_hash() IS 'Computes a unique hash which will be used to prevent duplicate messages from being enqueued on the same day';
 
+CREATE FUNCTION create_time_partition(parent_table text, partition_start timestamp with time zone, partition_end timestamp with time zone) RETURNS boolean
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+	partition_name text := parent_table || '_p' || to_char(partition_start AT TIME ZONE 'UTC', 'YYYYMMDD');
+	partition_key text;
+	has_rows boolean;
+BEGIN
+	IF to_regclass(partition_name) IS NOT NULL THEN
+		RETURN false;
+	END IF;
+
+	SELECT pg_attribute.attname INTO partition_key
+	FROM pg_partitioned_table
+	JOIN pg_attribute ON pg_attribute.attrelid = pg_partitioned_table.partrelid
+		AND pg_attribute.attnum = pg_partitioned_table.partattrs[0]
+	WHERE pg_partitioned_table.partrelid = parent_table::regclass;
+
+	-- A range partition cannot be created while the default partition holds
+	-- rows that belong in it. Those rows are eventually removed by the
+	-- regular purge queries, so the partition is created on a later attempt.
+	EXECUTE format('SELECT EXISTS (SELECT 1 FROM %I WHERE %I >= $1 AND %I < $2)', parent_table || '_default', partition_key, partition_key)
+		INTO has_rows
+		USING partition_start, partition_end;
+	IF has_rows THEN
+		RETURN false;
+	END IF;
+
+	EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)', partition_name, parent_table, partition_start, partition_end);
+	RETURN true;
+END;
+$$;
+
+COMMENT ON FUNCTION create_time_partition(parent_table text, partition_start timestamp with time zone, partition_end timestamp with time zone) IS 'Creates a range partition of parent_table covering [partition_start, partition_end). Returns false if the partition already exists or if rows in that range are still stored in the default partition.';
+
 CREATE FUNCTION delete_deleted_oauth2_provider_app_token_api_key() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -441,6 +602,33 @@ BEGIN
 END;
 $$;
 
+CREATE FUNCTION drop_time_partitions(parent_table text, older_than timestamp with time zone) RETURNS integer
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+	part record;
+	dropped integer := 0;
+BEGIN
+	FOR part IN
+		SELECT
+			pg_class.oid::regclass AS name,
+			substring(pg_get_expr(pg_class.relpartbound, pg_class.oid) FROM 'TO \(''([^'']+)''\)')::timestamp with time zone AS partition_end
+		FROM pg_inherits
+		JOIN pg_class ON pg_class.oid = pg_inherits.inhrelid
+		WHERE pg_inherits.inhparent = parent_table::regclass
+	LOOP
+		-- The default partition has no upper bound and is never dropped.
+		IF part.partition_end IS NOT NULL AND part.partition_end <= older_than THEN
+			EXECUTE format('DROP TABLE %s', part.name);
+			dropped := dropped + 1;
+		END IF;
+	END LOOP;
+	RETURN dropped;
+END;
+$$;
+
+COMMENT ON FUNCTION drop_time_partitions(parent_table text, older_than timestamp with time zone) IS 'Drops the range partitions of parent_table whose upper bound is at or before older_than. Returns the number of dropped partitions.';
+
 CREATE FUNCTION inhibit_enqueue_if_disabled() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -787,6 +975,32 @@ BEGIN
 END;
 $$;
 
+CREATE TABLE access_requests (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    requester_id uuid NOT NULL,
+    resource_type access_request_resource_type NOT NULL,
+    resource_id uuid NOT NULL,
+    role text NOT NULL,
+    reason text NOT NULL,
+    duration_ms bigint NOT NULL,
+    status access_request_status DEFAULT 'pending'::access_request_status NOT NULL,
+    previous_role text DEFAULT ''::text NOT NULL,
+    reviewed_by uuid,
+    reviewed_at timestamp with time zone,
+    expires_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL,
+    CONSTRAINT access_requests_duration_ms_check CHECK ((duration_ms > 0))
+);
+
+COMMENT ON TABLE access_requests IS 'Requests for time-boxed access to a workspace or template. Approved requests grant the role through the ACL of the resource until they expire.';
+
+COMMENT ON COLUMN access_requests.role IS 'The requested role, a workspace role for workspaces and a template role for templates.';
+
+COMMENT ON COLUMN access_requests.previous_role IS 'The role the requester had on the resource when the request was approved. It is restored when the request expires.';
+
+COMMENT ON COLUMN access_requests.expires_at IS 'Set when the request is approved. The granted role is revoked after this time.';
+
 CREATE TABLE api_keys (
     id text NOT NULL,
     hashed_secret bytea NOT NULL,
@@ -799,11 +1013,14 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    bound_identity text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.bound_identity IS 'bound_identity is the identity of the client the key is bound to, e.g. the fingerprint of a client certificate or a device ID. Empty if the key is not bound. Requests using a bound key must present the same identity.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -820,8 +1037,52 @@ CREATE TABLE audit_logs (
     additional_fields jsonb NOT NULL,
     request_id uuid NOT NULL,
     resource_icon text NOT NULL
+)
+PARTITION BY RANGE ("time");
+
+CREATE TABLE audit_logs_default (
+    id uuid NOT NULL,
+    "time" timestamp with time zone NOT NULL,
+    user_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    ip inet,
+    user_agent character varying(256),
+    resource_type resource_type NOT NULL,
+    resource_id uuid NOT NULL,
+    resource_target text NOT NULL,
+    action audit_action NOT NULL,
+    diff jsonb NOT NULL,
+    status_code integer NOT NULL,
+    additional_fields jsonb NOT NULL,
+    request_id uuid NOT NULL,
+    resource_icon text NOT NULL
+);
+
+CREATE TABLE build_alert_rules (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    template_id uuid,
+    name text NOT NULL,
+    metric build_alert_rule_metric NOT NULL,
+    threshold double precision NOT NULL,
+    window_ms bigint NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    last_evaluated_at timestamp with time zone,
+    last_value double precision,
+    breached_at timestamp with time zone,
+    CONSTRAINT build_alert_rules_window_ms_check CHECK ((window_ms > 0))
 );
 
+COMMENT ON TABLE build_alert_rules IS 'Rules over workspace build metrics that notify template admins when breached.';
+
+COMMENT ON COLUMN build_alert_rules.template_id IS 'Restricts the rule to builds of a single template. If NULL, the rule covers all templates of the organization.';
+
+COMMENT ON COLUMN build_alert_rules.threshold IS 'The rule is breached when the metric is above this value. Percent for failure_rate, milliseconds for queue_wait_p95.';
+
+COMMENT ON COLUMN build_alert_rules.breached_at IS 'Set when the rule became breached, and cleared once the metric is back under the threshold. Notifications are only sent when it is set.';
+
 CREATE TABLE crypto_keys (
     feature crypto_key_feature NOT NULL,
     sequence integer NOT NULL,
@@ -918,13 +1179,16 @@ CREATE TABLE groups (
     avatar_url text DEFAULT ''::text NOT NULL,
     quota_allowance integer DEFAULT 0 NOT NULL,
     display_name text DEFAULT ''::text NOT NULL,
-    source group_source DEFAULT 'user'::group_source NOT NULL
+    source group_source DEFAULT 'user'::group_source NOT NULL,
+    monthly_budget integer DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN groups.display_name IS 'Display name is a custom, human-friendly group name that user can set. This is not required to be unique and can be the empty string.';
 
 COMMENT ON COLUMN groups.source IS 'Source indicates how the group was created. It can be created by a user manually, or through some system process like OIDC group sync.';
 
+COMMENT ON COLUMN groups.monthly_budget IS 'Credits the members of the group may spend on workspaces each calendar month (UTC), accrued from the daily cost of their workspaces by the hour. 0 means the group adds no monthly budget.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -1045,6 +1309,23 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE login_device_codes (
+    id uuid NOT NULL,
+    device_code_hash bytea NOT NULL,
+    user_code text NOT NULL,
+    user_id uuid,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE login_device_codes IS 'Pending device authorizations of the CLI. A device polls with its device code until a user signed in on another device approves the user code, and then receives a session token for that user.';
+
+COMMENT ON COLUMN login_device_codes.device_code_hash IS 'SHA-256 hash of the secret device code the device polls with.';
+
+COMMENT ON COLUMN login_device_codes.user_code IS 'The short code the user enters to approve the device, without separators.';
+
+COMMENT ON COLUMN login_device_codes.user_id IS 'The user that approved the device, or null if it has not been approved yet.';
+
 CREATE TABLE notification_messages (
     id uuid NOT NULL,
     notification_template_id uuid NOT NULL,
@@ -1071,9 +1352,12 @@ CREATE TABLE notification_preferences (
     notification_template_id uuid NOT NULL,
     disabled boolean DEFAULT false NOT NULL,
     created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
-    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    digest boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN notification_preferences.digest IS 'Whether the notifications of this template are batched into a periodic digest instead of being delivered immediately';
+
 CREATE TABLE notification_report_generator_logs (
     notification_template_id uuid NOT NULL,
     last_generated_at timestamp with time zone NOT NULL
@@ -1153,9 +1437,12 @@ CREATE TABLE organizations (
     is_default boolean DEFAULT false NOT NULL,
     display_name text NOT NULL,
     icon text DEFAULT ''::text NOT NULL,
-    deleted boolean DEFAULT false NOT NULL
+    deleted boolean DEFAULT false NOT NULL,
+    cost_center text DEFAULT ''::text NOT NULL
 );
 
+COMMENT ON COLUMN organizations.cost_center IS 'Cost center the organization is billed to. Available to workspace naming policies as {{.CostCenter}}.';
+
 CREATE TABLE parameter_schemas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1188,6 +1475,18 @@ CREATE TABLE parameter_values (
     destination_scheme parameter_destination_scheme NOT NULL
 );
 
+CREATE TABLE provisioner_build_pauses (
+    id uuid NOT NULL,
+    organization_id uuid,
+    reason text DEFAULT ''::text NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE provisioner_build_pauses IS 'Provisioner jobs in the scope of a pause are accepted but held pending: provisioners do not acquire them and the job reaper does not time them out. Used during planned infrastructure maintenance.';
+
+COMMENT ON COLUMN provisioner_build_pauses.organization_id IS 'The organization whose builds are paused. NULL pauses builds across the deployment.';
+
 CREATE TABLE provisioner_daemons (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1204,6 +1503,15 @@ CREATE TABLE provisioner_daemons (
 
 COMMENT ON COLUMN provisioner_daemons.api_version IS 'The API version of the provisioner daemon';
 
+CREATE TABLE provisioner_job_log_archives (
+    job_id uuid NOT NULL,
+    file_id uuid NOT NULL,
+    log_count integer NOT NULL,
+    archived_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_log_archives IS 'Provisioner job logs that were moved out of provisioner_job_logs into a compressed file after the retention period.';
+
 CREATE TABLE provisioner_job_logs (
     job_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1211,7 +1519,22 @@ CREATE TABLE provisioner_job_logs (
     level log_level NOT NULL,
     stage character varying(128) NOT NULL,
     output character varying(1024) NOT NULL,
-    id bigint NOT NULL
+    id bigint NOT NULL,
+    fields jsonb DEFAULT '{}'::jsonb NOT NULL
+)
+PARTITION BY RANGE (created_at);
+
+COMMENT ON COLUMN provisioner_job_logs.fields IS 'Structured fields extracted from machine-readable provisioner output, such as the Terraform resource address, action and diagnostic severity.';
+
+CREATE TABLE provisioner_job_logs_default (
+    job_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    source log_source NOT NULL,
+    level log_level NOT NULL,
+    stage character varying(128) NOT NULL,
+    output character varying(1024) NOT NULL,
+    id bigint NOT NULL,
+    fields jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 CREATE SEQUENCE provisioner_job_logs_id_seq
@@ -1295,9 +1618,39 @@ CREATE TABLE provisioner_keys (
     organization_id uuid NOT NULL,
     name character varying(64) NOT NULL,
     hashed_secret bytea NOT NULL,
-    tags jsonb NOT NULL
+    tags jsonb NOT NULL,
+    last_used_at timestamp with time zone,
+    expires_at timestamp with time zone,
+    previous_hashed_secret bytea,
+    previous_secret_expires_at timestamp with time zone
+);
+
+COMMENT ON COLUMN provisioner_keys.last_used_at IS 'The last time a provisioner daemon authenticated with this key.';
+
+COMMENT ON COLUMN provisioner_keys.expires_at IS 'When set, the key can no longer be used to authenticate after this time.';
+
+COMMENT ON COLUMN provisioner_keys.previous_hashed_secret IS 'The hashed secret that was replaced by the most recent rotation. It remains valid until previous_secret_expires_at so that daemons can be migrated to the new secret without downtime.';
+
+COMMENT ON COLUMN provisioner_keys.previous_secret_expires_at IS 'The end of the rotation overlap window for previous_hashed_secret.';
+
+CREATE TABLE provisioner_reservations (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    tags jsonb NOT NULL,
+    slots integer NOT NULL,
+    starts_at timestamp with time zone NOT NULL,
+    ends_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    CONSTRAINT provisioner_reservations_slots_check CHECK ((slots > 0)),
+    CONSTRAINT provisioner_reservations_window_check CHECK ((ends_at > starts_at))
 );
 
+COMMENT ON TABLE provisioner_reservations IS 'Provisioner capacity held back for jobs with a specific tag set during a time window, e.g. for scheduled trainings.';
+
+COMMENT ON COLUMN provisioner_reservations.tags IS 'Jobs whose tags contain these tags may use the reserved slots. Provisioners whose tags contain these tags provide the slots.';
+
+COMMENT ON COLUMN provisioner_reservations.slots IS 'Number of concurrent jobs that provisioners able to serve the reservation keep available for reserved jobs.';
+
 CREATE TABLE replicas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1313,6 +1666,18 @@ CREATE TABLE replicas (
     "primary" boolean DEFAULT true NOT NULL
 );
 
+CREATE TABLE service_accounts (
+    user_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE service_accounts IS 'Users that are used for automation rather than by people. They belong to a single organization, have no way to log in and authenticate with tokens that organization admins manage.';
+
+COMMENT ON COLUMN service_accounts.created_by IS 'The user that created the service account.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value text NOT NULL
@@ -1368,6 +1733,74 @@ CREATE TABLE telemetry_items (
     updated_at timestamp with time zone DEFAULT now() NOT NULL
 );
 
+CREATE TABLE template_build_duration_stats (
+    start_time timestamp with time zone NOT NULL,
+    end_time timestamp with time zone NOT NULL,
+    template_id uuid NOT NULL,
+    transition workspace_transition NOT NULL,
+    build_count integer NOT NULL,
+    p50_duration_ms bigint NOT NULL,
+    p95_duration_ms bigint NOT NULL,
+    max_duration_ms bigint NOT NULL
+);
+
+COMMENT ON TABLE template_build_duration_stats IS 'Records aggregated durations of successful workspace builds per template and transition, in daily buckets.';
+
+COMMENT ON COLUMN template_build_duration_stats.start_time IS 'Start time of the period the builds completed in.';
+
+COMMENT ON COLUMN template_build_duration_stats.end_time IS 'End time of the period the builds completed in.';
+
+COMMENT ON COLUMN template_build_duration_stats.template_id IS 'ID of the template the builds are of.';
+
+COMMENT ON COLUMN template_build_duration_stats.build_count IS 'Number of successful builds that completed in the period.';
+
+COMMENT ON COLUMN template_build_duration_stats.p50_duration_ms IS 'Median build duration, in milliseconds.';
+
+COMMENT ON COLUMN template_build_duration_stats.p95_duration_ms IS '95th percentile build duration, in milliseconds.';
+
+COMMENT ON COLUMN template_build_duration_stats.max_duration_ms IS 'Longest build duration, in milliseconds.';
+
+CREATE TABLE template_preset_group_defaults (
+    template_id uuid NOT NULL,
+    group_id uuid NOT NULL,
+    template_preset_id uuid NOT NULL
+);
+
+COMMENT ON TABLE template_preset_group_defaults IS 'The template preset that members of a group get by default when they create a workspace without choosing a preset.';
+
+CREATE TABLE template_presets (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    name text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    parameters jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_presets IS 'Named bundles of parameter values that are managed at the template level, independently of template versions.';
+
+COMMENT ON COLUMN template_presets.parameters IS 'Parameter values by parameter name. Parameters that a template version does not define are ignored.';
+
+CREATE TABLE template_release_channels (
+    template_id uuid NOT NULL,
+    channel release_channel NOT NULL,
+    template_version_id uuid NOT NULL,
+    updated_by uuid NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_release_channels IS 'Template versions published to named release channels. Builds of workspaces tracking a channel that target the active version use the version of the channel instead.';
+
+CREATE TABLE template_shares (
+    template_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_shares IS 'Organizations that templates are shared into. Members of these organizations can read the template, but not modify it.';
+
 CREATE TABLE template_usage_stats (
     start_time timestamp with time zone NOT NULL,
     end_time timestamp with time zone NOT NULL,
@@ -1491,6 +1924,23 @@ CREATE TABLE template_version_presets (
     is_default boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE template_version_rollouts (
+    template_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    percent integer NOT NULL,
+    group_id uuid,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT template_version_rollouts_percent_check CHECK (((percent >= 0) AND (percent <= 100)))
+);
+
+COMMENT ON TABLE template_version_rollouts IS 'Gradual promotions of template versions. Builds that target the active version of the template use the rollout version instead if their workspace falls within the percentage or its owner is a member of the group.';
+
+COMMENT ON COLUMN template_version_rollouts.percent IS 'The percentage of workspaces, bucketed by workspace ID, that build the rollout version.';
+
+COMMENT ON COLUMN template_version_rollouts.group_id IS 'The group whose members always build the rollout version, regardless of the percentage.';
+
 CREATE TABLE template_version_terraform_values (
     template_version_id uuid NOT NULL,
     updated_at timestamp with time zone DEFAULT now() NOT NULL,
@@ -1509,7 +1959,8 @@ CREATE TABLE template_version_variables (
     value text NOT NULL,
     default_value text NOT NULL,
     required boolean NOT NULL,
-    sensitive boolean NOT NULL
+    sensitive boolean NOT NULL,
+    value_key_id text
 );
 
 COMMENT ON COLUMN template_version_variables.name IS 'Variable name';
@@ -1526,6 +1977,8 @@ COMMENT ON COLUMN template_version_variables.required IS 'Required variables nee
 
 COMMENT ON COLUMN template_version_variables.sensitive IS 'Sensitive variables have their values redacted in logs or site UI';
 
+COMMENT ON COLUMN template_version_variables.value_key_id IS 'The ID of the key used to encrypt the variable value. If this is NULL, the value is not encrypted';
+
 CREATE TABLE template_versions (
     id uuid NOT NULL,
     template_id uuid,
@@ -1614,7 +2067,10 @@ CREATE TABLE templates (
     deprecated text DEFAULT ''::text NOT NULL,
     activity_bump bigint DEFAULT '3600000000000'::bigint NOT NULL,
     max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
-    use_classic_parameter_flow boolean DEFAULT true NOT NULL
+    use_classic_parameter_flow boolean DEFAULT true NOT NULL,
+    max_concurrent_jobs_per_user integer DEFAULT 0 NOT NULL,
+    resource_ceilings jsonb DEFAULT '{}'::jsonb NOT NULL,
+    required_provisioner_tags jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -1637,6 +2093,12 @@ COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the tem
 
 COMMENT ON COLUMN templates.use_classic_parameter_flow IS 'Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.';
 
+COMMENT ON COLUMN templates.max_concurrent_jobs_per_user IS 'Maximum number of pending and running workspace builds of this template a single user may have at a time. 0 means unlimited.';
+
+COMMENT ON COLUMN templates.resource_ceilings IS 'Maximum CPU cores, memory, disk and daily cost the planned resources of a single workspace build may have. Ceilings that are unset or 0 are not enforced.';
+
+COMMENT ON COLUMN templates.required_provisioner_tags IS 'Provisioner tags that the jobs of the template must target, so that they only run on provisioner daemons with these tags. A value of "*" requires the tag to be set to any value.';
+
 CREATE VIEW template_with_names AS
  SELECT templates.id,
     templates.created_at,
@@ -1667,18 +2129,45 @@ CREATE VIEW template_with_names AS
     templates.activity_bump,
     templates.max_port_sharing_level,
     templates.use_classic_parameter_flow,
+    templates.max_concurrent_jobs_per_user,
+    templates.resource_ceilings,
+    templates.required_provisioner_tags,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username,
     COALESCE(visible_users.name, ''::text) AS created_by_name,
     COALESCE(organizations.name, ''::text) AS organization_name,
     COALESCE(organizations.display_name, ''::text) AS organization_display_name,
-    COALESCE(organizations.icon, ''::text) AS organization_icon
+    COALESCE(organizations.icon, ''::text) AS organization_icon,
+    COALESCE(( SELECT array_agg(template_shares.organization_id ORDER BY template_shares.organization_id) AS array_agg
+           FROM template_shares
+          WHERE (template_shares.template_id = templates.id)), '{}'::uuid[]) AS shared_organization_ids
    FROM ((templates
      LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
      LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
 
 COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
 
+CREATE TABLE user_activity_exports (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    start_time timestamp with time zone NOT NULL,
+    end_time timestamp with time zone NOT NULL,
+    format user_activity_export_format NOT NULL,
+    status user_activity_export_status DEFAULT 'pending'::user_activity_export_status NOT NULL,
+    file_id uuid,
+    error text DEFAULT ''::text NOT NULL,
+    completed_at timestamp with time zone,
+    CONSTRAINT user_activity_exports_time_range_check CHECK ((end_time > start_time))
+);
+
+COMMENT ON TABLE user_activity_exports IS 'Exports of the daily activity of each user, generated in the background for ingestion by external tools.';
+
+COMMENT ON COLUMN user_activity_exports.file_id IS 'The file holding the export once it has completed.';
+
+COMMENT ON COLUMN user_activity_exports.error IS 'Why the export failed, if it did.';
+
 CREATE TABLE user_configs (
     user_id uuid NOT NULL,
     key character varying(256) NOT NULL,
@@ -1729,18 +2218,47 @@ CREATE TABLE webpush_subscriptions (
     endpoint_auth_key text NOT NULL
 );
 
-CREATE TABLE workspace_agent_devcontainers (
-    id uuid NOT NULL,
-    workspace_agent_id uuid NOT NULL,
-    created_at timestamp with time zone DEFAULT now() NOT NULL,
-    workspace_folder text NOT NULL,
-    config_path text NOT NULL,
-    name text NOT NULL
+CREATE TABLE workspace_agent_connection_quality (
+    agent_id uuid NOT NULL,
+    ip text NOT NULL,
+    p2p boolean NOT NULL,
+    derp_region_code text DEFAULT ''::text NOT NULL,
+    latency_ms double precision,
+    pings_sent bigint NOT NULL,
+    pings_lost bigint NOT NULL,
+    last_handshake_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
 );
 
-COMMENT ON TABLE workspace_agent_devcontainers IS 'Workspace agent devcontainer configuration';
+COMMENT ON TABLE workspace_agent_connection_quality IS 'Network quality of each peer connected to a workspace agent, as last reported in the agent stats.';
 
-COMMENT ON COLUMN workspace_agent_devcontainers.id IS 'Unique identifier';
+COMMENT ON COLUMN workspace_agent_connection_quality.ip IS 'Tailnet IP address of the peer. Every client connection uses its own address.';
+
+COMMENT ON COLUMN workspace_agent_connection_quality.p2p IS 'Whether the connection is direct. If false, it is relayed through DERP.';
+
+COMMENT ON COLUMN workspace_agent_connection_quality.derp_region_code IS 'DERP region the connection is relayed through. Empty for direct connections.';
+
+COMMENT ON COLUMN workspace_agent_connection_quality.latency_ms IS 'Round trip time of the most recent successful ping. NULL if no ping was answered.';
+
+COMMENT ON COLUMN workspace_agent_connection_quality.pings_sent IS 'Number of pings the agent sent to the peer since it connected.';
+
+COMMENT ON COLUMN workspace_agent_connection_quality.pings_lost IS 'Number of pings sent to the peer that went unanswered.';
+
+COMMENT ON COLUMN workspace_agent_connection_quality.last_handshake_at IS 'Time of the last WireGuard handshake with the peer.';
+
+CREATE TABLE workspace_agent_devcontainers (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    workspace_folder text NOT NULL,
+    config_path text NOT NULL,
+    name text NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_devcontainers IS 'Workspace agent devcontainer configuration';
+
+COMMENT ON COLUMN workspace_agent_devcontainers.id IS 'Unique identifier';
 
 COMMENT ON COLUMN workspace_agent_devcontainers.workspace_agent_id IS 'Workspace agent foreign key';
 
@@ -1752,6 +2270,27 @@ COMMENT ON COLUMN workspace_agent_devcontainers.config_path IS 'Path to devconta
 
 COMMENT ON COLUMN workspace_agent_devcontainers.name IS 'The name of the Dev Container.';
 
+CREATE TABLE workspace_agent_health_events (
+    id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    kind workspace_agent_health_event_kind NOT NULL,
+    volume text DEFAULT ''::text NOT NULL,
+    used bigint NOT NULL,
+    total bigint NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    resolved_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_agent_health_events IS 'Conditions detected by workspace agents that are likely to cause failures, such as their host running out of memory.';
+
+COMMENT ON COLUMN workspace_agent_health_events.volume IS 'Path of the volume that is low on space. Empty for memory events.';
+
+COMMENT ON COLUMN workspace_agent_health_events.used IS 'Amount of the resource in use when the condition was detected, in bytes.';
+
+COMMENT ON COLUMN workspace_agent_health_events.total IS 'Amount of the resource available when the condition was detected, in bytes.';
+
+COMMENT ON COLUMN workspace_agent_health_events.resolved_at IS 'Time the agent reported the condition no longer applies. NULL while it is ongoing.';
+
 CREATE TABLE workspace_agent_log_sources (
     workspace_agent_id uuid NOT NULL,
     id uuid NOT NULL,
@@ -1799,9 +2338,54 @@ CREATE TABLE workspace_agent_port_share (
     agent_name text NOT NULL,
     port integer NOT NULL,
     share_level app_sharing_level NOT NULL,
-    protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL
+    protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL,
+    shared_with_user_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    shared_with_group_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    expires_at timestamp with time zone
 );
 
+COMMENT ON COLUMN workspace_agent_port_share.shared_with_user_ids IS 'Users that can access the port when the share level is restricted.';
+
+COMMENT ON COLUMN workspace_agent_port_share.shared_with_group_ids IS 'Groups whose members can access the port when the share level is restricted.';
+
+COMMENT ON COLUMN workspace_agent_port_share.expires_at IS 'When set, the port share is no longer honored after this time.';
+
+CREATE TABLE workspace_agent_resource_usage (
+    agent_id uuid NOT NULL,
+    bucket_start timestamp with time zone NOT NULL,
+    samples integer NOT NULL,
+    cpu_used_avg double precision NOT NULL,
+    cpu_used_max double precision NOT NULL,
+    cpu_total double precision NOT NULL,
+    memory_used_avg bigint NOT NULL,
+    memory_used_max bigint NOT NULL,
+    memory_total bigint NOT NULL,
+    disk_used bigint NOT NULL,
+    disk_total bigint NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_resource_usage IS 'Host resource utilization reported by workspace agents, downsampled into fixed buckets.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.bucket_start IS 'Start time of the bucket the samples were collected in.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.samples IS 'Number of samples aggregated into the bucket.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.cpu_used_avg IS 'Average number of CPU cores in use.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.cpu_used_max IS 'Maximum number of CPU cores in use.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.cpu_total IS 'Number of CPU cores available to the agent.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.memory_used_avg IS 'Average memory in use, in bytes.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.memory_used_max IS 'Maximum memory in use, in bytes.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.memory_total IS 'Memory available to the agent, in bytes.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.disk_used IS 'Disk space in use at the end of the bucket, in bytes.';
+
+COMMENT ON COLUMN workspace_agent_resource_usage.disk_total IS 'Disk space available to the agent, in bytes.';
+
 CREATE TABLE workspace_agent_script_timings (
     script_id uuid NOT NULL,
     started_at timestamp with time zone NOT NULL,
@@ -1823,9 +2407,12 @@ CREATE TABLE workspace_agent_scripts (
     run_on_stop boolean NOT NULL,
     timeout_seconds integer NOT NULL,
     display_name text NOT NULL,
-    id uuid DEFAULT gen_random_uuid() NOT NULL
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    run_on_claim boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN workspace_agent_scripts.run_on_claim IS 'Whether the script runs when a prebuilt workspace is claimed by a user.';
+
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -1854,6 +2441,28 @@ CREATE TABLE workspace_agent_stats (
     session_count_reconnecting_pty bigint DEFAULT 0 NOT NULL,
     session_count_ssh bigint DEFAULT 0 NOT NULL,
     usage boolean DEFAULT false NOT NULL
+)
+PARTITION BY RANGE (created_at);
+
+CREATE TABLE workspace_agent_stats_default (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    user_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    connections_by_proto jsonb DEFAULT '{}'::jsonb NOT NULL,
+    connection_count bigint DEFAULT 0 NOT NULL,
+    rx_packets bigint DEFAULT 0 NOT NULL,
+    rx_bytes bigint DEFAULT 0 NOT NULL,
+    tx_packets bigint DEFAULT 0 NOT NULL,
+    tx_bytes bigint DEFAULT 0 NOT NULL,
+    connection_median_latency_ms double precision DEFAULT '-1'::integer NOT NULL,
+    session_count_vscode bigint DEFAULT 0 NOT NULL,
+    session_count_jetbrains bigint DEFAULT 0 NOT NULL,
+    session_count_reconnecting_pty bigint DEFAULT 0 NOT NULL,
+    session_count_ssh bigint DEFAULT 0 NOT NULL,
+    usage boolean DEFAULT false NOT NULL
 );
 
 CREATE TABLE workspace_agent_volume_resource_monitors (
@@ -1902,6 +2511,8 @@ CREATE TABLE workspace_agents (
     parent_id uuid,
     api_key_scope agent_key_scope_enum DEFAULT 'all'::agent_key_scope_enum NOT NULL,
     deleted boolean DEFAULT false NOT NULL,
+    display_group text DEFAULT ''::text NOT NULL,
+    collapsed boolean DEFAULT false NOT NULL,
     CONSTRAINT max_logs_length CHECK ((logs_length <= 1048576)),
     CONSTRAINT subsystems_not_none CHECK ((NOT ('none'::workspace_agent_subsystem = ANY (subsystems))))
 );
@@ -1932,6 +2543,10 @@ COMMENT ON COLUMN workspace_agents.api_key_scope IS 'Defines the scope of the AP
 
 COMMENT ON COLUMN workspace_agents.deleted IS 'Indicates whether or not the agent has been deleted. This is currently only applicable to sub agents.';
 
+COMMENT ON COLUMN workspace_agents.display_group IS 'The name of the group the agent is displayed under in user interfaces. Empty means the agent is not grouped.';
+
+COMMENT ON COLUMN workspace_agents.collapsed IS 'Whether the agent should be collapsed by default in user interfaces.';
+
 CREATE UNLOGGED TABLE workspace_app_audit_sessions (
     agent_id uuid NOT NULL,
     app_id uuid NOT NULL,
@@ -2046,6 +2661,47 @@ COMMENT ON COLUMN workspace_apps.display_order IS 'Specifies the order in which
 
 COMMENT ON COLUMN workspace_apps.hidden IS 'Determines if the app is not shown in user interfaces.';
 
+CREATE TABLE workspace_build_archives (
+    workspace_build_id uuid NOT NULL,
+    file_id uuid NOT NULL,
+    state_size integer NOT NULL,
+    resource_metadata_count integer NOT NULL,
+    archived_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_archives IS 'Workspace builds whose provisioner state and resource metadata were moved into a compressed file. The build, job and resource rows are kept as summaries.';
+
+CREATE TABLE workspace_build_duration_notifications (
+    workspace_build_id uuid NOT NULL,
+    notified_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_duration_notifications IS 'The workspace builds whose owner was notified that they run longer than their build duration notification threshold. Each build is only notified once.';
+
+CREATE TABLE workspace_build_idempotency_keys (
+    workspace_build_id uuid,
+    user_id uuid NOT NULL,
+    idempotency_key text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    request_hash bytea NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_idempotency_keys IS 'The Idempotency-Key headers of the requests that created workspace builds. A retried request with the same key returns the build it created instead of creating another one.';
+
+COMMENT ON COLUMN workspace_build_idempotency_keys.workspace_build_id IS 'The build the request created. It is set in the transaction that reserved the key, so it is only null while the request is in progress.';
+
+COMMENT ON COLUMN workspace_build_idempotency_keys.user_id IS 'The user that made the request. Keys are unique per user.';
+
+COMMENT ON COLUMN workspace_build_idempotency_keys.request_hash IS 'The SHA-256 hash of the request. A request that reuses the key with a different hash is rejected.';
+
+CREATE TABLE workspace_build_interim_states (
+    workspace_build_id uuid NOT NULL,
+    state bytea NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_interim_states IS 'The provisioner state uploaded while a workspace build is applied. Removed once the build completes or fails with a final state. A build that did not complete can be resumed from it.';
+
 CREATE TABLE workspace_build_parameters (
     workspace_build_id uuid NOT NULL,
     name text NOT NULL,
@@ -2056,6 +2712,26 @@ COMMENT ON COLUMN workspace_build_parameters.name IS 'Parameter name';
 
 COMMENT ON COLUMN workspace_build_parameters.value IS 'Parameter value';
 
+CREATE TABLE workspace_build_queue (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    initiator_id uuid NOT NULL,
+    transition workspace_transition NOT NULL,
+    template_version_id uuid,
+    template_version_preset_id uuid,
+    rich_parameter_values jsonb DEFAULT '[]'::jsonb NOT NULL,
+    log_level text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    failed_at timestamp with time zone,
+    error text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_queue IS 'Workspace builds that were requested while another build of the workspace was active. The oldest entry of a workspace is started once no build of the workspace is active.';
+
+COMMENT ON COLUMN workspace_build_queue.template_version_id IS 'The template version to build, or NULL to build the version of the build that precedes it.';
+
+COMMENT ON COLUMN workspace_build_queue.failed_at IS 'Set when the queued build could not be started. Failed entries are kept so the error can be shown, and do not hold up later entries.';
+
 CREATE TABLE workspace_builds (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2074,9 +2750,15 @@ CREATE TABLE workspace_builds (
     template_version_preset_id uuid,
     has_ai_task boolean,
     ai_task_sidebar_app_id uuid,
+    initiator_context jsonb DEFAULT '{}'::jsonb NOT NULL,
+    warnings jsonb DEFAULT '[]'::jsonb NOT NULL,
     CONSTRAINT workspace_builds_ai_task_sidebar_app_id_required CHECK (((((has_ai_task IS NULL) OR (has_ai_task = false)) AND (ai_task_sidebar_app_id IS NULL)) OR ((has_ai_task = true) AND (ai_task_sidebar_app_id IS NOT NULL))))
 );
 
+COMMENT ON COLUMN workspace_builds.initiator_context IS 'Structured context about what initiated the build, such as the name of the API key used to request it, the automation that started it, or the schedule that triggered an autostart.';
+
+COMMENT ON COLUMN workspace_builds.warnings IS 'Warnings recorded when the build was created, such as the build using a template version that is not the active version of its template.';
+
 CREATE VIEW workspace_build_with_user AS
  SELECT workspace_builds.id,
     workspace_builds.created_at,
@@ -2095,6 +2777,8 @@ CREATE VIEW workspace_build_with_user AS
     workspace_builds.template_version_preset_id,
     workspace_builds.has_ai_task,
     workspace_builds.ai_task_sidebar_app_id,
+    workspace_builds.initiator_context,
+    workspace_builds.warnings,
     COALESCE(visible_users.avatar_url, ''::text) AS initiator_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS initiator_by_username,
     COALESCE(visible_users.name, ''::text) AS initiator_by_name
@@ -2103,6 +2787,50 @@ CREATE VIEW workspace_build_with_user AS
 
 COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
 
+CREATE TABLE workspace_daily_costs (
+    start_time timestamp with time zone NOT NULL,
+    end_time timestamp with time zone NOT NULL,
+    workspace_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    cost double precision NOT NULL
+);
+
+COMMENT ON TABLE workspace_daily_costs IS 'Records the cost accrued by each workspace in daily buckets, based on the daily cost of its resources.';
+
+COMMENT ON COLUMN workspace_daily_costs.start_time IS 'Start time of the period the cost accrued in.';
+
+COMMENT ON COLUMN workspace_daily_costs.end_time IS 'End time of the period the cost accrued in.';
+
+COMMENT ON COLUMN workspace_daily_costs.workspace_id IS 'ID of the workspace that accrued the cost.';
+
+COMMENT ON COLUMN workspace_daily_costs.organization_id IS 'ID of the organization of the workspace.';
+
+COMMENT ON COLUMN workspace_daily_costs.owner_id IS 'ID of the owner of the workspace when the cost was rolled up.';
+
+COMMENT ON COLUMN workspace_daily_costs.template_id IS 'ID of the template of the workspace.';
+
+COMMENT ON COLUMN workspace_daily_costs.cost IS 'Cost accrued in the period, prorated by the time each build of the workspace was current.';
+
+CREATE TABLE workspace_drift_checks (
+    workspace_id uuid NOT NULL,
+    workspace_build_id uuid NOT NULL,
+    job_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    completed_at timestamp with time zone,
+    drifted_resources text[] DEFAULT '{}'::text[] NOT NULL,
+    orphaned_resources text[] DEFAULT '{}'::text[] NOT NULL
+);
+
+COMMENT ON TABLE workspace_drift_checks IS 'The latest drift check of each workspace, which plans the latest build of a stopped or failed workspace against its state.';
+
+COMMENT ON COLUMN workspace_drift_checks.job_id IS 'The template version dry-run job that plans the build.';
+
+COMMENT ON COLUMN workspace_drift_checks.drifted_resources IS 'Addresses of resources that were changed outside of Terraform.';
+
+COMMENT ON COLUMN workspace_drift_checks.orphaned_resources IS 'Addresses of resources that are in the state but would be deleted by the plan.';
+
 CREATE TABLE workspaces (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2119,11 +2847,17 @@ CREATE TABLE workspaces (
     deleting_at timestamp with time zone,
     automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL,
     favorite boolean DEFAULT false NOT NULL,
-    next_start_at timestamp with time zone
+    next_start_at timestamp with time zone,
+    group_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    user_acl jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN workspaces.favorite IS 'Favorite is true if the workspace owner has favorited the workspace.';
 
+COMMENT ON COLUMN workspaces.group_acl IS 'Groups the workspace is shared with, and the actions they are granted.';
+
+COMMENT ON COLUMN workspaces.user_acl IS 'Users the workspace is shared with, and the actions they are granted.';
+
 CREATE VIEW workspace_latest_builds AS
  SELECT latest_build.id,
     latest_build.workspace_id,
@@ -2150,6 +2884,66 @@ CREATE VIEW workspace_latest_builds AS
   WHERE (workspaces.deleted = false)
   ORDER BY workspaces.id;
 
+CREATE TABLE workspace_labels (
+    workspace_id uuid NOT NULL,
+    key text NOT NULL,
+    value text NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_labels IS 'Arbitrary key/value labels used to group and filter workspaces.';
+
+COMMENT ON COLUMN workspace_labels.key IS 'Keys are lowercase, since workspace search queries are case insensitive.';
+
+CREATE TABLE workspace_locks (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    reason text NOT NULL,
+    locked_by uuid NOT NULL,
+    locked_at timestamp with time zone NOT NULL,
+    unlocked_by uuid,
+    unlocked_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_locks IS 'Administrative locks that prevent any new builds of a workspace, including automatic ones, until they are lifted. Lifted locks are kept as a record of who locked the workspace and why.';
+
+COMMENT ON COLUMN workspace_locks.unlocked_at IS 'The time the lock was lifted, or NULL if the workspace is still locked.';
+
+CREATE TABLE workspace_migrations (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    initiator_id uuid NOT NULL,
+    status workspace_migration_status DEFAULT 'pending'::workspace_migration_status NOT NULL,
+    destination_url text NOT NULL,
+    destination_organization_id uuid NOT NULL,
+    destination_owner text NOT NULL,
+    destination_template_id uuid,
+    destination_name text DEFAULT ''::text NOT NULL,
+    destination_session_token text NOT NULL,
+    destination_session_token_key_id text,
+    source_build_id uuid,
+    destination_workspace_id uuid,
+    destination_build_id uuid,
+    error text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    completed_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_migrations IS 'Transfers of workspaces, including their provisioner state, to another deployment. The workspace is stopped, imported on the destination with its state and started there, then deleted without destroying its resources.';
+
+COMMENT ON COLUMN workspace_migrations.destination_organization_id IS 'The organization on the destination deployment. It does not reference an organization of this deployment.';
+
+COMMENT ON COLUMN workspace_migrations.destination_template_id IS 'The template on the destination deployment, or NULL to look it up by the name of the template of the workspace.';
+
+COMMENT ON COLUMN workspace_migrations.destination_session_token IS 'The session token used to authenticate with the destination deployment. It is cleared once the migration completes.';
+
+COMMENT ON COLUMN workspace_migrations.destination_session_token_key_id IS 'The ID of the key used to encrypt the destination session token. If this is NULL, the token is not encrypted';
+
+COMMENT ON COLUMN workspace_migrations.source_build_id IS 'The build that stopped the workspace on this deployment.';
+
+COMMENT ON COLUMN workspace_migrations.destination_build_id IS 'The build that starts the workspace on the destination deployment.';
+
 CREATE TABLE workspace_modules (
     id uuid NOT NULL,
     job_id uuid NOT NULL,
@@ -2160,6 +2954,28 @@ CREATE TABLE workspace_modules (
     created_at timestamp with time zone NOT NULL
 );
 
+CREATE TABLE workspace_naming_policies (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    template_id uuid,
+    regex text DEFAULT ''::text NOT NULL,
+    prefix text DEFAULT ''::text NOT NULL,
+    suffix text DEFAULT ''::text NOT NULL,
+    uniqueness_scope workspace_name_uniqueness_scope DEFAULT 'owner'::workspace_name_uniqueness_scope NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_naming_policies IS 'Naming rules that are enforced when workspaces are created. A row without a template_id applies to the whole organization, a row with a template_id applies to workspaces created from that template and takes precedence over the organization policy.';
+
+COMMENT ON COLUMN workspace_naming_policies.regex IS 'Regular expression the full workspace name must match. Empty disables the check.';
+
+COMMENT ON COLUMN workspace_naming_policies.prefix IS 'Go template rendered with the owner, organization and template names and the organization cost center. The workspace name must start with the result.';
+
+COMMENT ON COLUMN workspace_naming_policies.suffix IS 'Go template rendered with the owner, organization and template names and the organization cost center. The workspace name must end with the result.';
+
+COMMENT ON COLUMN workspace_naming_policies.uniqueness_scope IS 'Scope in which workspace names must be unique.';
+
 CREATE VIEW workspace_prebuild_builds AS
  SELECT workspace_builds.id,
     workspace_builds.workspace_id,
@@ -2171,6 +2987,41 @@ CREATE VIEW workspace_prebuild_builds AS
    FROM workspace_builds
   WHERE (workspace_builds.initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid);
 
+CREATE TABLE workspace_prebuild_reservations (
+    workspace_id uuid NOT NULL,
+    user_id uuid,
+    group_id uuid,
+    reserved_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    CONSTRAINT workspace_prebuild_reservations_reserved_for_check CHECK ((num_nonnulls(user_id, group_id) = 1))
+);
+
+COMMENT ON TABLE workspace_prebuild_reservations IS 'Prebuilt workspaces held for a specific user or group. Until the reservation expires, a reserved prebuilt workspace can only be claimed by the user or a member of the group.';
+
+COMMENT ON COLUMN workspace_prebuild_reservations.user_id IS 'The user the prebuilt workspace is reserved for. Exactly one of user_id and group_id is set.';
+
+COMMENT ON COLUMN workspace_prebuild_reservations.group_id IS 'The group whose members the prebuilt workspace is reserved for. Exactly one of user_id and group_id is set.';
+
+CREATE TABLE workspace_provisioner_affinities (
+    workspace_id uuid NOT NULL,
+    provisioner_daemon_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_provisioner_affinities IS 'The provisioner daemon that last built a workspace successfully. The daemon likely still has the providers of the template version cached, so it is preferred for the next build of the workspace.';
+
+COMMENT ON COLUMN workspace_provisioner_affinities.template_version_id IS 'The template version of the last successful build. Affinity is only honored for builds of the same template version.';
+
+CREATE TABLE workspace_release_channels (
+    workspace_id uuid NOT NULL,
+    channel release_channel NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_release_channels IS 'The release channel a workspace tracks instead of the active version of its template.';
+
 CREATE TABLE workspace_resources (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2182,9 +3033,18 @@ CREATE TABLE workspace_resources (
     icon character varying(256) DEFAULT ''::character varying NOT NULL,
     instance_type character varying(256),
     daily_cost integer DEFAULT 0 NOT NULL,
-    module_path text
+    module_path text,
+    display_group text DEFAULT ''::text NOT NULL,
+    display_order integer DEFAULT 0 NOT NULL,
+    collapsed boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN workspace_resources.display_group IS 'The name of the group the resource is displayed under in user interfaces. Empty means the resource is not grouped.';
+
+COMMENT ON COLUMN workspace_resources.display_order IS 'Specifies the order in which to display resources in user interfaces.';
+
+COMMENT ON COLUMN workspace_resources.collapsed IS 'Whether the resource should be collapsed by default in user interfaces.';
+
 CREATE VIEW workspace_prebuilds AS
  WITH all_prebuilds AS (
          SELECT w.id,
@@ -2281,6 +3141,51 @@ CREATE SEQUENCE workspace_resource_metadata_id_seq
 
 ALTER SEQUENCE workspace_resource_metadata_id_seq OWNED BY workspace_resource_metadata.id;
 
+CREATE TABLE workspace_session_recording_chunks (
+    id bigint NOT NULL,
+    recording_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    data bytea NOT NULL,
+    data_key_id text
+);
+
+COMMENT ON TABLE workspace_session_recording_chunks IS 'Output of recorded terminal sessions, in the order it was written.';
+
+COMMENT ON COLUMN workspace_session_recording_chunks.created_at IS 'Time the output was written by the session, as reported by the agent.';
+
+COMMENT ON COLUMN workspace_session_recording_chunks.data_key_id IS 'The ID of the key used to encrypt the data. If this is NULL, the data is not encrypted';
+
+CREATE SEQUENCE workspace_session_recording_chunks_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE workspace_session_recording_chunks_id_seq OWNED BY workspace_session_recording_chunks.id;
+
+CREATE TABLE workspace_session_recordings (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    type workspace_session_recording_type NOT NULL,
+    width integer NOT NULL,
+    height integer NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone,
+    size_bytes bigint DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Terminal sessions recorded by workspace agents when session recording is enabled.';
+
+COMMENT ON COLUMN workspace_session_recordings.width IS 'Width of the terminal in columns when the session started.';
+
+COMMENT ON COLUMN workspace_session_recordings.height IS 'Height of the terminal in rows when the session started.';
+
+COMMENT ON COLUMN workspace_session_recordings.ended_at IS 'Time the session ended. NULL while it is ongoing, or if the agent never reported the end.';
+
+COMMENT ON COLUMN workspace_session_recordings.size_bytes IS 'Total size of the recorded output, before encryption.';
+
 CREATE VIEW workspaces_expanded AS
  SELECT workspaces.id,
     workspaces.created_at,
@@ -2298,6 +3203,8 @@ CREATE VIEW workspaces_expanded AS
     workspaces.automatic_updates,
     workspaces.favorite,
     workspaces.next_start_at,
+    workspaces.group_acl,
+    workspaces.user_acl,
     visible_users.avatar_url AS owner_avatar_url,
     visible_users.username AS owner_username,
     visible_users.name AS owner_name,
@@ -2316,6 +3223,12 @@ CREATE VIEW workspaces_expanded AS
 
 COMMENT ON VIEW workspaces_expanded IS 'Joins in the display name information such as username, avatar, and organization name.';
 
+ALTER TABLE ONLY audit_logs ATTACH PARTITION audit_logs_default DEFAULT;
+
+ALTER TABLE ONLY provisioner_job_logs ATTACH PARTITION provisioner_job_logs_default DEFAULT;
+
+ALTER TABLE ONLY workspace_agent_stats ATTACH PARTITION workspace_agent_stats_default DEFAULT;
+
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('licenses_id_seq'::regclass);
 
 ALTER TABLE ONLY provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
@@ -2328,14 +3241,25 @@ ALTER TABLE ONLY workspace_proxies ALTER COLUMN region_id SET DEFAULT nextval('w
 
 ALTER TABLE ONLY workspace_resource_metadata ALTER COLUMN id SET DEFAULT nextval('workspace_resource_metadata_id_seq'::regclass);
 
+ALTER TABLE ONLY workspace_session_recording_chunks ALTER COLUMN id SET DEFAULT nextval('workspace_session_recording_chunks_id_seq'::regclass);
+
+ALTER TABLE ONLY access_requests
+    ADD CONSTRAINT access_requests_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_stats
-    ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id);
+    ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id, created_at);
 
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY audit_logs
-    ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
+    ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id, "time");
+
+ALTER TABLE ONLY audit_logs_default
+    ADD CONSTRAINT audit_logs_default_pkey PRIMARY KEY (id, "time");
+
+ALTER TABLE ONLY build_alert_rules
+    ADD CONSTRAINT build_alert_rules_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY crypto_keys
     ADD CONSTRAINT crypto_keys_pkey PRIMARY KEY (feature, sequence);
@@ -2385,6 +3309,15 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY login_device_codes
+    ADD CONSTRAINT login_device_codes_device_code_hash_key UNIQUE (device_code_hash);
+
+ALTER TABLE ONLY login_device_codes
+    ADD CONSTRAINT login_device_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY login_device_codes
+    ADD CONSTRAINT login_device_codes_user_code_key UNIQUE (user_code);
+
 ALTER TABLE ONLY notification_messages
     ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
 
@@ -2442,11 +3375,20 @@ ALTER TABLE ONLY parameter_values
 ALTER TABLE ONLY parameter_values
     ADD CONSTRAINT parameter_values_scope_id_name_key UNIQUE (scope_id, name);
 
+ALTER TABLE ONLY provisioner_build_pauses
+    ADD CONSTRAINT provisioner_build_pauses_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY provisioner_job_log_archives
+    ADD CONSTRAINT provisioner_job_log_archives_pkey PRIMARY KEY (job_id);
+
+ALTER TABLE ONLY provisioner_job_logs_default
+    ADD CONSTRAINT provisioner_job_logs_default_pkey PRIMARY KEY (id, created_at);
+
 ALTER TABLE ONLY provisioner_job_logs
-    ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
+    ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id, created_at);
 
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
@@ -2454,6 +3396,12 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY provisioner_reservations
+    ADD CONSTRAINT provisioner_reservations_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY service_accounts
+    ADD CONSTRAINT service_accounts_pkey PRIMARY KEY (user_id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -2478,6 +3426,24 @@ ALTER TABLE ONLY tailnet_tunnels
 ALTER TABLE ONLY telemetry_items
     ADD CONSTRAINT telemetry_items_pkey PRIMARY KEY (key);
 
+ALTER TABLE ONLY template_build_duration_stats
+    ADD CONSTRAINT template_build_duration_stats_pkey PRIMARY KEY (start_time, template_id, transition);
+
+ALTER TABLE ONLY template_preset_group_defaults
+    ADD CONSTRAINT template_preset_group_defaults_pkey PRIMARY KEY (template_id, group_id);
+
+ALTER TABLE ONLY template_presets
+    ADD CONSTRAINT template_presets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_presets
+    ADD CONSTRAINT template_presets_template_id_name_key UNIQUE (template_id, name);
+
+ALTER TABLE ONLY template_release_channels
+    ADD CONSTRAINT template_release_channels_pkey PRIMARY KEY (template_id, channel);
+
+ALTER TABLE ONLY template_shares
+    ADD CONSTRAINT template_shares_pkey PRIMARY KEY (template_id, organization_id);
+
 ALTER TABLE ONLY template_usage_stats
     ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
 
@@ -2493,6 +3459,9 @@ ALTER TABLE ONLY template_version_preset_prebuild_schedules
 ALTER TABLE ONLY template_version_presets
     ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_pkey PRIMARY KEY (template_id);
+
 ALTER TABLE ONLY template_version_terraform_values
     ADD CONSTRAINT template_version_terraform_values_template_version_id_key UNIQUE (template_version_id);
 
@@ -2511,6 +3480,9 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY user_activity_exports
+    ADD CONSTRAINT user_activity_exports_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY user_configs
     ADD CONSTRAINT user_configs_pkey PRIMARY KEY (user_id, key);
 
@@ -2529,9 +3501,15 @@ ALTER TABLE ONLY users
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_agent_connection_quality
+    ADD CONSTRAINT workspace_agent_connection_quality_pkey PRIMARY KEY (agent_id, ip);
+
 ALTER TABLE ONLY workspace_agent_devcontainers
     ADD CONSTRAINT workspace_agent_devcontainers_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_agent_health_events
+    ADD CONSTRAINT workspace_agent_health_events_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
 
@@ -2544,6 +3522,9 @@ ALTER TABLE ONLY workspace_agent_metadata
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
 
+ALTER TABLE ONLY workspace_agent_resource_usage
+    ADD CONSTRAINT workspace_agent_resource_usage_pkey PRIMARY KEY (agent_id, bucket_start);
+
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
 
@@ -2553,6 +3534,9 @@ ALTER TABLE ONLY workspace_agent_scripts
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_agent_stats_default
+    ADD CONSTRAINT workspace_agent_stats_default_pkey PRIMARY KEY (id, created_at);
+
 ALTER TABLE ONLY workspace_agent_volume_resource_monitors
     ADD CONSTRAINT workspace_agent_volume_resource_monitors_pkey PRIMARY KEY (agent_id, path);
 
@@ -2580,9 +3564,27 @@ ALTER TABLE ONLY workspace_apps
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_build_archives
+    ADD CONSTRAINT workspace_build_archives_pkey PRIMARY KEY (workspace_build_id);
+
+ALTER TABLE ONLY workspace_build_duration_notifications
+    ADD CONSTRAINT workspace_build_duration_notifications_pkey PRIMARY KEY (workspace_build_id);
+
+ALTER TABLE ONLY workspace_build_idempotency_keys
+    ADD CONSTRAINT workspace_build_idempotency_keys_user_id_idempotency_key_key UNIQUE (user_id, idempotency_key);
+
+ALTER TABLE ONLY workspace_build_idempotency_keys
+    ADD CONSTRAINT workspace_build_idempotency_keys_workspace_build_id_key UNIQUE (workspace_build_id);
+
+ALTER TABLE ONLY workspace_build_interim_states
+    ADD CONSTRAINT workspace_build_interim_states_pkey PRIMARY KEY (workspace_build_id);
+
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
 
+ALTER TABLE ONLY workspace_build_queue
+    ADD CONSTRAINT workspace_build_queue_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
 
@@ -2592,12 +3594,39 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_daily_costs
+    ADD CONSTRAINT workspace_daily_costs_pkey PRIMARY KEY (start_time, workspace_id);
+
+ALTER TABLE ONLY workspace_drift_checks
+    ADD CONSTRAINT workspace_drift_checks_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_labels
+    ADD CONSTRAINT workspace_labels_pkey PRIMARY KEY (workspace_id, key);
+
+ALTER TABLE ONLY workspace_locks
+    ADD CONSTRAINT workspace_locks_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_migrations
+    ADD CONSTRAINT workspace_migrations_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_naming_policies
+    ADD CONSTRAINT workspace_naming_policies_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_prebuild_reservations
+    ADD CONSTRAINT workspace_prebuild_reservations_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_provisioner_affinities
+    ADD CONSTRAINT workspace_provisioner_affinities_pkey PRIMARY KEY (workspace_id);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
 
+ALTER TABLE ONLY workspace_release_channels
+    ADD CONSTRAINT workspace_release_channels_pkey PRIMARY KEY (workspace_id);
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 
@@ -2607,24 +3636,44 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
-CREATE INDEX idx_agent_stats_created_at ON workspace_agent_stats USING btree (created_at);
+CREATE INDEX access_requests_expires_at_idx ON access_requests USING btree (expires_at) WHERE (status = 'approved'::access_request_status);
+
+CREATE UNIQUE INDEX access_requests_open_idx ON access_requests USING btree (requester_id, resource_id) WHERE (status = ANY (ARRAY['pending'::access_request_status, 'approved'::access_request_status]));
+
+CREATE INDEX audit_logs_default_organization_id_idx ON audit_logs_default USING btree (organization_id);
+
+CREATE INDEX audit_logs_default_resource_id_idx ON audit_logs_default USING btree (resource_id);
 
-CREATE INDEX idx_agent_stats_user_id ON workspace_agent_stats USING btree (user_id);
+CREATE INDEX audit_logs_default_time_idx ON audit_logs_default USING btree ("time" DESC);
+
+CREATE INDEX audit_logs_default_user_id_idx ON audit_logs_default USING btree (user_id);
+
+CREATE UNIQUE INDEX build_alert_rules_organization_id_name_idx ON build_alert_rules USING btree (organization_id, lower(name));
+
+CREATE INDEX idx_agent_stats_created_at ON ONLY workspace_agent_stats USING btree (created_at);
+
+CREATE INDEX idx_agent_stats_user_id ON ONLY workspace_agent_stats USING btree (user_id);
 
 CREATE UNIQUE INDEX idx_api_key_name ON api_keys USING btree (user_id, token_name) WHERE (login_type = 'token'::login_type);
 
 CREATE INDEX idx_api_keys_user ON api_keys USING btree (user_id);
 
-CREATE INDEX idx_audit_log_organization_id ON audit_logs USING btree (organization_id);
+CREATE INDEX idx_audit_log_organization_id ON ONLY audit_logs USING btree (organization_id);
 
-CREATE INDEX idx_audit_log_resource_id ON audit_logs USING btree (resource_id);
+CREATE INDEX idx_audit_log_resource_id ON ONLY audit_logs USING btree (resource_id);
 
-CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
+CREATE INDEX idx_audit_log_user_id ON ONLY audit_logs USING btree (user_id);
 
-CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
+CREATE INDEX idx_audit_logs_time_desc ON ONLY audit_logs USING btree ("time" DESC);
 
 CREATE INDEX idx_custom_roles_id ON custom_roles USING btree (id);
 
@@ -2648,6 +3697,8 @@ COMMENT ON INDEX idx_provisioner_daemons_org_name_owner_key IS 'Allow unique pro
 
 CREATE INDEX idx_provisioner_jobs_status ON provisioner_jobs USING btree (job_status);
 
+CREATE INDEX idx_service_accounts_organization_id ON service_accounts USING btree (organization_id);
+
 CREATE INDEX idx_tailnet_agents_coordinator ON tailnet_agents USING btree (coordinator_id);
 
 CREATE INDEX idx_tailnet_clients_coordinator ON tailnet_clients USING btree (coordinator_id);
@@ -2678,12 +3729,34 @@ CREATE UNIQUE INDEX notification_messages_dedupe_hash_idx ON notification_messag
 
 CREATE UNIQUE INDEX organizations_single_default_org ON organizations USING btree (is_default) WHERE (is_default = true);
 
-CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
+CREATE UNIQUE INDEX provisioner_build_pauses_deployment_idx ON provisioner_build_pauses USING btree (((organization_id IS NULL))) WHERE (organization_id IS NULL);
+
+CREATE UNIQUE INDEX provisioner_build_pauses_organization_id_idx ON provisioner_build_pauses USING btree (organization_id) WHERE (organization_id IS NOT NULL);
+
+CREATE INDEX provisioner_job_logs_default_job_id_id_idx ON provisioner_job_logs_default USING btree (job_id, id);
+
+CREATE INDEX provisioner_job_logs_default_to_tsvector_idx ON provisioner_job_logs_default USING gin (to_tsvector('simple'::regconfig, (output)::text));
+
+CREATE INDEX provisioner_job_logs_id_job_id_idx ON ONLY provisioner_job_logs USING btree (job_id, id);
+
+CREATE INDEX provisioner_job_logs_output_search_idx ON ONLY provisioner_job_logs USING gin (to_tsvector('simple'::regconfig, (output)::text));
+
+CREATE INDEX provisioner_jobs_initiator_id_in_progress_idx ON provisioner_jobs USING btree (initiator_id) WHERE (completed_at IS NULL);
 
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
 
 CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
 
+CREATE INDEX provisioner_keys_previous_hashed_secret_idx ON provisioner_keys USING btree (previous_hashed_secret) WHERE (previous_hashed_secret IS NOT NULL);
+
+CREATE INDEX provisioner_reservations_organization_id_ends_at_idx ON provisioner_reservations USING btree (organization_id, ends_at);
+
+CREATE INDEX template_build_duration_stats_template_id_start_time_idx ON template_build_duration_stats USING btree (template_id, start_time);
+
+CREATE INDEX template_preset_group_defaults_template_preset_id_idx ON template_preset_group_defaults USING btree (template_preset_id);
+
+CREATE INDEX template_shares_organization_id_idx ON template_shares USING btree (organization_id);
+
 CREATE INDEX template_usage_stats_start_time_idx ON template_usage_stats USING btree (start_time DESC);
 
 COMMENT ON INDEX template_usage_stats_start_time_idx IS 'Index for querying MAX(start_time).';
@@ -2694,23 +3767,39 @@ COMMENT ON INDEX template_usage_stats_start_time_template_id_user_id_idx IS 'Ind
 
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
+CREATE INDEX user_activity_exports_pending_idx ON user_activity_exports USING btree (created_at) WHERE (status = 'pending'::user_activity_export_status);
+
 CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
 
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX workspace_agent_connection_quality_updated_at_idx ON workspace_agent_connection_quality USING btree (updated_at);
+
 CREATE INDEX workspace_agent_devcontainers_workspace_agent_id ON workspace_agent_devcontainers USING btree (workspace_agent_id);
 
 COMMENT ON INDEX workspace_agent_devcontainers_workspace_agent_id IS 'Workspace agent foreign key and query index';
 
+CREATE UNIQUE INDEX workspace_agent_health_events_unresolved_idx ON workspace_agent_health_events USING btree (agent_id, kind, volume) WHERE (resolved_at IS NULL);
+
+CREATE INDEX workspace_agent_resource_usage_bucket_start_idx ON workspace_agent_resource_usage USING btree (bucket_start);
+
 CREATE INDEX workspace_agent_scripts_workspace_agent_id_idx ON workspace_agent_scripts USING btree (workspace_agent_id);
 
 COMMENT ON INDEX workspace_agent_scripts_workspace_agent_id_idx IS 'Foreign key support index for faster lookups';
 
 CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_logs USING btree (agent_id, id);
 
-CREATE INDEX workspace_agent_stats_template_id_created_at_user_id_idx ON workspace_agent_stats USING btree (template_id, created_at, user_id) INCLUDE (session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, connection_median_latency_ms) WHERE (connection_count > 0);
+CREATE INDEX workspace_agent_stats_default_created_at_idx ON workspace_agent_stats_default USING btree (created_at);
+
+CREATE INDEX workspace_agent_stats_default_template_id_created_at_idx ON workspace_agent_stats_default USING btree (template_id, created_at, user_id) INCLUDE (session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, connection_median_latency_ms) WHERE (connection_count > 0);
+
+COMMENT ON INDEX workspace_agent_stats_default_template_id_created_at_idx IS 'Support index for template insights endpoint to build interval reports faster.';
+
+CREATE INDEX workspace_agent_stats_default_user_id_idx ON workspace_agent_stats_default USING btree (user_id);
+
+CREATE INDEX workspace_agent_stats_template_id_created_at_user_id_idx ON ONLY workspace_agent_stats USING btree (template_id, created_at, user_id) INCLUDE (session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, connection_median_latency_ms) WHERE (connection_count > 0);
 
 COMMENT ON INDEX workspace_agent_stats_template_id_created_at_user_id_idx IS 'Support index for template insights endpoint to build interval reports faster.';
 
@@ -2724,18 +3813,70 @@ COMMENT ON INDEX workspace_app_audit_sessions_unique_index IS 'Unique index to e
 
 CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING btree (workspace_id);
 
+CREATE INDEX workspace_build_idempotency_keys_created_at_idx ON workspace_build_idempotency_keys USING btree (created_at);
+
+CREATE INDEX workspace_build_queue_workspace_id_created_at_idx ON workspace_build_queue USING btree (workspace_id, created_at);
+
+CREATE INDEX workspace_daily_costs_start_time_idx ON workspace_daily_costs USING btree (start_time);
+
+CREATE UNIQUE INDEX workspace_drift_checks_job_id_idx ON workspace_drift_checks USING btree (job_id);
+
+CREATE INDEX workspace_labels_key_value_idx ON workspace_labels USING btree (key, lower(value));
+
+CREATE UNIQUE INDEX workspace_locks_workspace_id_active_idx ON workspace_locks USING btree (workspace_id) WHERE (unlocked_at IS NULL);
+
+CREATE INDEX workspace_locks_workspace_id_locked_at_idx ON workspace_locks USING btree (workspace_id, locked_at);
+
+CREATE UNIQUE INDEX workspace_migrations_workspace_id_active_idx ON workspace_migrations USING btree (workspace_id) WHERE (completed_at IS NULL);
+
+CREATE INDEX workspace_migrations_workspace_id_created_at_idx ON workspace_migrations USING btree (workspace_id, created_at);
+
 CREATE INDEX workspace_modules_created_at_idx ON workspace_modules USING btree (created_at);
 
+CREATE UNIQUE INDEX workspace_naming_policies_organization_id_idx ON workspace_naming_policies USING btree (organization_id) WHERE (template_id IS NULL);
+
+CREATE UNIQUE INDEX workspace_naming_policies_template_id_idx ON workspace_naming_policies USING btree (template_id) WHERE (template_id IS NOT NULL);
+
 CREATE INDEX workspace_next_start_at_idx ON workspaces USING btree (next_start_at) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
 
+CREATE INDEX workspace_session_recording_chunks_recording_id_idx ON workspace_session_recording_chunks USING btree (recording_id, id);
+
+CREATE INDEX workspace_session_recordings_started_at_idx ON workspace_session_recordings USING btree (started_at);
+
+CREATE INDEX workspace_session_recordings_workspace_id_started_at_idx ON workspace_session_recordings USING btree (workspace_id, started_at DESC);
+
 CREATE INDEX workspace_template_id_idx ON workspaces USING btree (template_id) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
 
+ALTER INDEX agent_stats_pkey ATTACH PARTITION workspace_agent_stats_default_pkey;
+
+ALTER INDEX audit_logs_pkey ATTACH PARTITION audit_logs_default_pkey;
+
+ALTER INDEX idx_agent_stats_created_at ATTACH PARTITION workspace_agent_stats_default_created_at_idx;
+
+ALTER INDEX idx_agent_stats_user_id ATTACH PARTITION workspace_agent_stats_default_user_id_idx;
+
+ALTER INDEX idx_audit_log_organization_id ATTACH PARTITION audit_logs_default_organization_id_idx;
+
+ALTER INDEX idx_audit_log_resource_id ATTACH PARTITION audit_logs_default_resource_id_idx;
+
+ALTER INDEX idx_audit_log_user_id ATTACH PARTITION audit_logs_default_user_id_idx;
+
+ALTER INDEX idx_audit_logs_time_desc ATTACH PARTITION audit_logs_default_time_idx;
+
+ALTER INDEX provisioner_job_logs_id_job_id_idx ATTACH PARTITION provisioner_job_logs_default_job_id_id_idx;
+
+ALTER INDEX provisioner_job_logs_output_search_idx ATTACH PARTITION provisioner_job_logs_default_to_tsvector_idx;
+
+ALTER INDEX provisioner_job_logs_pkey ATTACH PARTITION provisioner_job_logs_default_pkey;
+
+ALTER INDEX workspace_agent_stats_template_id_created_at_user_id_idx ATTACH PARTITION workspace_agent_stats_default_template_id_created_at_idx;
+
 CREATE OR REPLACE VIEW provisioner_job_stats AS
  SELECT pj.id AS job_id,
     pj.job_status,
@@ -2788,6 +3929,8 @@ CREATE OR REPLACE VIEW provisioner_job_stats AS
      LEFT JOIN provisioner_job_timings pjt ON ((pjt.job_id = pj.id)))
   GROUP BY pj.id, wb.workspace_id;
 
+CREATE TRIGGER batch_notification_if_digest BEFORE INSERT ON notification_messages FOR EACH ROW EXECUTE FUNCTION batch_notification_if_digest();
+
 CREATE TRIGGER inhibit_enqueue_if_disabled BEFORE INSERT ON notification_messages FOR EACH ROW EXECUTE FUNCTION inhibit_enqueue_if_disabled();
 
 CREATE TRIGGER protect_deleting_organizations BEFORE UPDATE ON organizations FOR EACH ROW WHEN (((new.deleted = true) AND (old.deleted = false))) EXECUTE FUNCTION protect_deleting_organizations();
@@ -2826,13 +3969,33 @@ CREATE TRIGGER user_status_change_trigger AFTER INSERT OR UPDATE ON users FOR EA
 
 CREATE TRIGGER workspace_agent_name_unique_trigger BEFORE INSERT OR UPDATE OF name, resource_id ON workspace_agents FOR EACH ROW EXECUTE FUNCTION check_workspace_agent_name_unique();
 
+CREATE TRIGGER workspace_name_organization_unique_trigger BEFORE INSERT OR UPDATE OF name, deleted ON workspaces FOR EACH ROW EXECUTE FUNCTION check_workspace_name_organization_unique();
+
 COMMENT ON TRIGGER workspace_agent_name_unique_trigger ON workspace_agents IS 'Use a trigger instead of a unique constraint because existing data may violate
 the uniqueness requirement. A trigger allows us to enforce uniqueness going
 forward without requiring a migration to clean up historical data.';
 
+ALTER TABLE ONLY access_requests
+    ADD CONSTRAINT access_requests_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY access_requests
+    ADD CONSTRAINT access_requests_requester_id_fkey FOREIGN KEY (requester_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY access_requests
+    ADD CONSTRAINT access_requests_reviewed_by_fkey FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY build_alert_rules
+    ADD CONSTRAINT build_alert_rules_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY build_alert_rules
+    ADD CONSTRAINT build_alert_rules_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY build_alert_rules
+    ADD CONSTRAINT build_alert_rules_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY crypto_keys
     ADD CONSTRAINT crypto_keys_secret_key_id_fkey FOREIGN KEY (secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 
@@ -2866,6 +4029,9 @@ ALTER TABLE ONLY jfrog_xray_scans
 ALTER TABLE ONLY jfrog_xray_scans
     ADD CONSTRAINT jfrog_xray_scans_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY login_device_codes
+    ADD CONSTRAINT login_device_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY notification_messages
     ADD CONSTRAINT notification_messages_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
 
@@ -2902,13 +4068,25 @@ ALTER TABLE ONLY organization_members
 ALTER TABLE ONLY parameter_schemas
     ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_build_pauses
+    ADD CONSTRAINT provisioner_build_pauses_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY provisioner_build_pauses
+    ADD CONSTRAINT provisioner_build_pauses_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_key_id_fkey FOREIGN KEY (key_id) REFERENCES provisioner_keys(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
-ALTER TABLE ONLY provisioner_job_logs
+ALTER TABLE ONLY provisioner_job_log_archives
+    ADD CONSTRAINT provisioner_job_log_archives_file_id_fkey FOREIGN KEY (file_id) REFERENCES files(id);
+
+ALTER TABLE ONLY provisioner_job_log_archives
+    ADD CONSTRAINT provisioner_job_log_archives_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
+ALTER TABLE provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY provisioner_job_timings
@@ -2920,6 +4098,18 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_reservations
+    ADD CONSTRAINT provisioner_reservations_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY service_accounts
+    ADD CONSTRAINT service_accounts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY service_accounts
+    ADD CONSTRAINT service_accounts_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY service_accounts
+    ADD CONSTRAINT service_accounts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY tailnet_agents
     ADD CONSTRAINT tailnet_agents_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
@@ -2935,6 +4125,36 @@ ALTER TABLE ONLY tailnet_peers
 ALTER TABLE ONLY tailnet_tunnels
     ADD CONSTRAINT tailnet_tunnels_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_preset_group_defaults
+    ADD CONSTRAINT template_preset_group_defaults_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_preset_group_defaults
+    ADD CONSTRAINT template_preset_group_defaults_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_preset_group_defaults
+    ADD CONSTRAINT template_preset_group_defaults_template_preset_id_fkey FOREIGN KEY (template_preset_id) REFERENCES template_presets(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_presets
+    ADD CONSTRAINT template_presets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_release_channels
+    ADD CONSTRAINT template_release_channels_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_release_channels
+    ADD CONSTRAINT template_release_channels_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_release_channels
+    ADD CONSTRAINT template_release_channels_updated_by_fkey FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_shares
+    ADD CONSTRAINT template_shares_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_shares
+    ADD CONSTRAINT template_shares_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_shares
+    ADD CONSTRAINT template_shares_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -2947,6 +4167,18 @@ ALTER TABLE ONLY template_version_preset_prebuild_schedules
 ALTER TABLE ONLY template_version_presets
     ADD CONSTRAINT template_version_presets_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_terraform_values
     ADD CONSTRAINT template_version_terraform_values_cached_module_files_fkey FOREIGN KEY (cached_module_files) REFERENCES files(id);
 
@@ -2956,6 +4188,9 @@ ALTER TABLE ONLY template_version_terraform_values
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_variables
+    ADD CONSTRAINT template_version_variables_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
 ALTER TABLE ONLY template_version_workspace_tags
     ADD CONSTRAINT template_version_workspace_tags_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -2974,6 +4209,12 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_activity_exports
+    ADD CONSTRAINT user_activity_exports_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_activity_exports
+    ADD CONSTRAINT user_activity_exports_file_id_fkey FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY user_configs
     ADD CONSTRAINT user_configs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
@@ -2995,9 +4236,15 @@ ALTER TABLE ONLY user_status_changes
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_connection_quality
+    ADD CONSTRAINT workspace_agent_connection_quality_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_devcontainers
     ADD CONSTRAINT workspace_agent_devcontainers_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_health_events
+    ADD CONSTRAINT workspace_agent_health_events_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -3010,6 +4257,9 @@ ALTER TABLE ONLY workspace_agent_metadata
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_resource_usage
+    ADD CONSTRAINT workspace_agent_resource_usage_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_fkey FOREIGN KEY (script_id) REFERENCES workspace_agent_scripts(id) ON DELETE CASCADE;
 
@@ -3052,9 +4302,39 @@ ALTER TABLE ONLY workspace_app_statuses
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_build_archives
+    ADD CONSTRAINT workspace_build_archives_file_id_fkey FOREIGN KEY (file_id) REFERENCES files(id);
+
+ALTER TABLE ONLY workspace_build_archives
+    ADD CONSTRAINT workspace_build_archives_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_duration_notifications
+    ADD CONSTRAINT workspace_build_duration_notifications_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_idempotency_keys
+    ADD CONSTRAINT workspace_build_idempotency_keys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_idempotency_keys
+    ADD CONSTRAINT workspace_build_idempotency_keys_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_interim_states
+    ADD CONSTRAINT workspace_build_interim_states_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_build_queue
+    ADD CONSTRAINT workspace_build_queue_initiator_id_fkey FOREIGN KEY (initiator_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_queue
+    ADD CONSTRAINT workspace_build_queue_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_queue
+    ADD CONSTRAINT workspace_build_queue_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_build_queue
+    ADD CONSTRAINT workspace_build_queue_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_ai_task_sidebar_app_id_fkey FOREIGN KEY (ai_task_sidebar_app_id) REFERENCES workspace_apps(id);
 
@@ -3070,15 +4350,87 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_drift_checks
+    ADD CONSTRAINT workspace_drift_checks_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_drift_checks
+    ADD CONSTRAINT workspace_drift_checks_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_drift_checks
+    ADD CONSTRAINT workspace_drift_checks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_labels
+    ADD CONSTRAINT workspace_labels_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_locks
+    ADD CONSTRAINT workspace_locks_locked_by_fkey FOREIGN KEY (locked_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_locks
+    ADD CONSTRAINT workspace_locks_unlocked_by_fkey FOREIGN KEY (unlocked_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_locks
+    ADD CONSTRAINT workspace_locks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_migrations
+    ADD CONSTRAINT workspace_migrations_destination_session_token_key_id_fkey FOREIGN KEY (destination_session_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY workspace_migrations
+    ADD CONSTRAINT workspace_migrations_initiator_id_fkey FOREIGN KEY (initiator_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_migrations
+    ADD CONSTRAINT workspace_migrations_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_modules
     ADD CONSTRAINT workspace_modules_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_naming_policies
+    ADD CONSTRAINT workspace_naming_policies_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_naming_policies
+    ADD CONSTRAINT workspace_naming_policies_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_prebuild_reservations
+    ADD CONSTRAINT workspace_prebuild_reservations_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_prebuild_reservations
+    ADD CONSTRAINT workspace_prebuild_reservations_reserved_by_fkey FOREIGN KEY (reserved_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_prebuild_reservations
+    ADD CONSTRAINT workspace_prebuild_reservations_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_prebuild_reservations
+    ADD CONSTRAINT workspace_prebuild_reservations_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_provisioner_affinities
+    ADD CONSTRAINT workspace_provisioner_affinities_provisioner_daemon_id_fkey FOREIGN KEY (provisioner_daemon_id) REFERENCES provisioner_daemons(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_provisioner_affinities
+    ADD CONSTRAINT workspace_provisioner_affinities_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_provisioner_affinities
+    ADD CONSTRAINT workspace_provisioner_affinities_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_release_channels
+    ADD CONSTRAINT workspace_release_channels_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_data_key_id_fkey FOREIGN KEY (data_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_recording_id_fkey FOREIGN KEY (recording_id) REFERENCES workspace_session_recordings(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
@@ -71,6 +71,18 @@ func IsQueryCanceledError(err error) bool {
 	return false
 }
 
+// IsWorkspaceOrganizationNameConflict checks if the error is due to a
+// workspace name that is already in use in an organization whose naming
+// policy requires unique names.
+func IsWorkspaceOrganizationNameConflict(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Name() == "unique_violation" && pqErr.Constraint == "workspaces_organization_name_unique"
+	}
+
+	return false
+}
+
 func IsWorkspaceAgentLogsLimitError(err error) bool {
 	var pqErr *pq.Error
 	if errors.As(err, &pqErr) {
@@ -6,7 +6,13 @@ type ForeignKeyConstraint string
 
 // ForeignKeyConstraint enums.
 const (
+	ForeignKeyAccessRequestsOrganizationID                        ForeignKeyConstraint = "access_requests_organization_id_fkey"                            // ALTER TABLE ONLY access_requests ADD CONSTRAINT access_requests_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyAccessRequestsRequesterID                           ForeignKeyConstraint = "access_requests_requester_id_fkey"                               // ALTER TABLE ONLY access_requests ADD CONSTRAINT access_requests_requester_id_fkey FOREIGN KEY (requester_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyAccessRequestsReviewedBy                            ForeignKeyConstraint = "access_requests_reviewed_by_fkey"                                // ALTER TABLE ONLY access_requests ADD CONSTRAINT access_requests_reviewed_by_fkey FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL;
 	ForeignKeyAPIKeysUserIDUUID                                   ForeignKeyConstraint = "api_keys_user_id_uuid_fkey"                                      // ALTER TABLE ONLY api_keys ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyBuildAlertRulesCreatedBy                            ForeignKeyConstraint = "build_alert_rules_created_by_fkey"                               // ALTER TABLE ONLY build_alert_rules ADD CONSTRAINT build_alert_rules_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+	ForeignKeyBuildAlertRulesOrganizationID                       ForeignKeyConstraint = "build_alert_rules_organization_id_fkey"                          // ALTER TABLE ONLY build_alert_rules ADD CONSTRAINT build_alert_rules_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyBuildAlertRulesTemplateID                           ForeignKeyConstraint = "build_alert_rules_template_id_fkey"                              // ALTER TABLE ONLY build_alert_rules ADD CONSTRAINT build_alert_rules_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
 	ForeignKeyCryptoKeysSecretKeyID                               ForeignKeyConstraint = "crypto_keys_secret_key_id_fkey"                                  // ALTER TABLE ONLY crypto_keys ADD CONSTRAINT crypto_keys_secret_key_id_fkey FOREIGN KEY (secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 	ForeignKeyGitAuthLinksOauthAccessTokenKeyID                   ForeignKeyConstraint = "git_auth_links_oauth_access_token_key_id_fkey"                   // ALTER TABLE ONLY external_auth_links ADD CONSTRAINT git_auth_links_oauth_access_token_key_id_fkey FOREIGN KEY (oauth_access_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 	ForeignKeyGitAuthLinksOauthRefreshTokenKeyID                  ForeignKeyConstraint = "git_auth_links_oauth_refresh_token_key_id_fkey"                  // ALTER TABLE ONLY external_auth_links ADD CONSTRAINT git_auth_links_oauth_refresh_token_key_id_fkey FOREIGN KEY (oauth_refresh_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
@@ -18,6 +24,7 @@ const (
 	ForeignKeyInboxNotificationsUserID                            ForeignKeyConstraint = "inbox_notifications_user_id_fkey"                                // ALTER TABLE ONLY inbox_notifications ADD CONSTRAINT inbox_notifications_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyJfrogXrayScansAgentID                               ForeignKeyConstraint = "jfrog_xray_scans_agent_id_fkey"                                  // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyJfrogXrayScansWorkspaceID                           ForeignKeyConstraint = "jfrog_xray_scans_workspace_id_fkey"                              // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyLoginDeviceCodesUserID                              ForeignKeyConstraint = "login_device_codes_user_id_fkey"                                 // ALTER TABLE ONLY login_device_codes ADD CONSTRAINT login_device_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyNotificationMessagesNotificationTemplateID          ForeignKeyConstraint = "notification_messages_notification_template_id_fkey"             // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
 	ForeignKeyNotificationMessagesUserID                          ForeignKeyConstraint = "notification_messages_user_id_fkey"                              // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyNotificationPreferencesNotificationTemplateID       ForeignKeyConstraint = "notification_preferences_notification_template_id_fkey"          // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
@@ -30,30 +37,55 @@ const (
 	ForeignKeyOrganizationMembersOrganizationIDUUID               ForeignKeyConstraint = "organization_members_organization_id_uuid_fkey"                  // ALTER TABLE ONLY organization_members ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyOrganizationMembersUserIDUUID                       ForeignKeyConstraint = "organization_members_user_id_uuid_fkey"                          // ALTER TABLE ONLY organization_members ADD CONSTRAINT organization_members_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyParameterSchemasJobID                               ForeignKeyConstraint = "parameter_schemas_job_id_fkey"                                   // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyProvisionerBuildPausesCreatedBy                     ForeignKeyConstraint = "provisioner_build_pauses_created_by_fkey"                        // ALTER TABLE ONLY provisioner_build_pauses ADD CONSTRAINT provisioner_build_pauses_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+	ForeignKeyProvisionerBuildPausesOrganizationID                ForeignKeyConstraint = "provisioner_build_pauses_organization_id_fkey"                   // ALTER TABLE ONLY provisioner_build_pauses ADD CONSTRAINT provisioner_build_pauses_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerDaemonsKeyID                             ForeignKeyConstraint = "provisioner_daemons_key_id_fkey"                                 // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_key_id_fkey FOREIGN KEY (key_id) REFERENCES provisioner_keys(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerDaemonsOrganizationID                    ForeignKeyConstraint = "provisioner_daemons_organization_id_fkey"                        // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
-	ForeignKeyProvisionerJobLogsJobID                             ForeignKeyConstraint = "provisioner_job_logs_job_id_fkey"                                // ALTER TABLE ONLY provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyProvisionerJobLogArchivesFileID                     ForeignKeyConstraint = "provisioner_job_log_archives_file_id_fkey"                       // ALTER TABLE ONLY provisioner_job_log_archives ADD CONSTRAINT provisioner_job_log_archives_file_id_fkey FOREIGN KEY (file_id) REFERENCES files(id);
+	ForeignKeyProvisionerJobLogArchivesJobID                      ForeignKeyConstraint = "provisioner_job_log_archives_job_id_fkey"                        // ALTER TABLE ONLY provisioner_job_log_archives ADD CONSTRAINT provisioner_job_log_archives_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyProvisionerJobLogsJobID                             ForeignKeyConstraint = "provisioner_job_logs_job_id_fkey"                                // ALTER TABLE provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerJobTimingsJobID                          ForeignKeyConstraint = "provisioner_job_timings_job_id_fkey"                             // ALTER TABLE ONLY provisioner_job_timings ADD CONSTRAINT provisioner_job_timings_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerJobsOrganizationID                       ForeignKeyConstraint = "provisioner_jobs_organization_id_fkey"                           // ALTER TABLE ONLY provisioner_jobs ADD CONSTRAINT provisioner_jobs_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerKeysOrganizationID                       ForeignKeyConstraint = "provisioner_keys_organization_id_fkey"                           // ALTER TABLE ONLY provisioner_keys ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyProvisionerReservationsOrganizationID               ForeignKeyConstraint = "provisioner_reservations_organization_id_fkey"                   // ALTER TABLE ONLY provisioner_reservations ADD CONSTRAINT provisioner_reservations_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyServiceAccountsCreatedBy                            ForeignKeyConstraint = "service_accounts_created_by_fkey"                                // ALTER TABLE ONLY service_accounts ADD CONSTRAINT service_accounts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+	ForeignKeyServiceAccountsOrganizationID                       ForeignKeyConstraint = "service_accounts_organization_id_fkey"                           // ALTER TABLE ONLY service_accounts ADD CONSTRAINT service_accounts_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyServiceAccountsUserID                               ForeignKeyConstraint = "service_accounts_user_id_fkey"                                   // ALTER TABLE ONLY service_accounts ADD CONSTRAINT service_accounts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyTailnetAgentsCoordinatorID                          ForeignKeyConstraint = "tailnet_agents_coordinator_id_fkey"                              // ALTER TABLE ONLY tailnet_agents ADD CONSTRAINT tailnet_agents_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 	ForeignKeyTailnetClientSubscriptionsCoordinatorID             ForeignKeyConstraint = "tailnet_client_subscriptions_coordinator_id_fkey"                // ALTER TABLE ONLY tailnet_client_subscriptions ADD CONSTRAINT tailnet_client_subscriptions_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 	ForeignKeyTailnetClientsCoordinatorID                         ForeignKeyConstraint = "tailnet_clients_coordinator_id_fkey"                             // ALTER TABLE ONLY tailnet_clients ADD CONSTRAINT tailnet_clients_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 	ForeignKeyTailnetPeersCoordinatorID                           ForeignKeyConstraint = "tailnet_peers_coordinator_id_fkey"                               // ALTER TABLE ONLY tailnet_peers ADD CONSTRAINT tailnet_peers_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 	ForeignKeyTailnetTunnelsCoordinatorID                         ForeignKeyConstraint = "tailnet_tunnels_coordinator_id_fkey"                             // ALTER TABLE ONLY tailnet_tunnels ADD CONSTRAINT tailnet_tunnels_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
+	ForeignKeyTemplatePresetGroupDefaultsGroupID                  ForeignKeyConstraint = "template_preset_group_defaults_group_id_fkey"                    // ALTER TABLE ONLY template_preset_group_defaults ADD CONSTRAINT template_preset_group_defaults_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+	ForeignKeyTemplatePresetGroupDefaultsTemplateID               ForeignKeyConstraint = "template_preset_group_defaults_template_id_fkey"                 // ALTER TABLE ONLY template_preset_group_defaults ADD CONSTRAINT template_preset_group_defaults_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplatePresetGroupDefaultsTemplatePresetID         ForeignKeyConstraint = "template_preset_group_defaults_template_preset_id_fkey"          // ALTER TABLE ONLY template_preset_group_defaults ADD CONSTRAINT template_preset_group_defaults_template_preset_id_fkey FOREIGN KEY (template_preset_id) REFERENCES template_presets(id) ON DELETE CASCADE;
+	ForeignKeyTemplatePresetsTemplateID                           ForeignKeyConstraint = "template_presets_template_id_fkey"                               // ALTER TABLE ONLY template_presets ADD CONSTRAINT template_presets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateReleaseChannelsTemplateID                   ForeignKeyConstraint = "template_release_channels_template_id_fkey"                      // ALTER TABLE ONLY template_release_channels ADD CONSTRAINT template_release_channels_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateReleaseChannelsTemplateVersionID            ForeignKeyConstraint = "template_release_channels_template_version_id_fkey"              // ALTER TABLE ONLY template_release_channels ADD CONSTRAINT template_release_channels_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+	ForeignKeyTemplateReleaseChannelsUpdatedBy                    ForeignKeyConstraint = "template_release_channels_updated_by_fkey"                       // ALTER TABLE ONLY template_release_channels ADD CONSTRAINT template_release_channels_updated_by_fkey FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyTemplateSharesCreatedBy                             ForeignKeyConstraint = "template_shares_created_by_fkey"                                 // ALTER TABLE ONLY template_shares ADD CONSTRAINT template_shares_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyTemplateSharesOrganizationID                        ForeignKeyConstraint = "template_shares_organization_id_fkey"                            // ALTER TABLE ONLY template_shares ADD CONSTRAINT template_shares_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyTemplateSharesTemplateID                            ForeignKeyConstraint = "template_shares_template_id_fkey"                                // ALTER TABLE ONLY template_shares ADD CONSTRAINT template_shares_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionParametersTemplateVersionID          ForeignKeyConstraint = "template_version_parameters_template_version_id_fkey"            // ALTER TABLE ONLY template_version_parameters ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionPresetParametTemplateVersionPresetID ForeignKeyConstraint = "template_version_preset_paramet_template_version_preset_id_fkey" // ALTER TABLE ONLY template_version_preset_parameters ADD CONSTRAINT template_version_preset_paramet_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionPresetPrebuildSchedulesPresetID      ForeignKeyConstraint = "template_version_preset_prebuild_schedules_preset_id_fkey"       // ALTER TABLE ONLY template_version_preset_prebuild_schedules ADD CONSTRAINT template_version_preset_prebuild_schedules_preset_id_fkey FOREIGN KEY (preset_id) REFERENCES template_version_presets(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionPresetsTemplateVersionID             ForeignKeyConstraint = "template_version_presets_template_version_id_fkey"               // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionRolloutsCreatedBy                    ForeignKeyConstraint = "template_version_rollouts_created_by_fkey"                       // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionRolloutsGroupID                      ForeignKeyConstraint = "template_version_rollouts_group_id_fkey"                         // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE SET NULL;
+	ForeignKeyTemplateVersionRolloutsTemplateID                   ForeignKeyConstraint = "template_version_rollouts_template_id_fkey"                      // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionRolloutsTemplateVersionID            ForeignKeyConstraint = "template_version_rollouts_template_version_id_fkey"              // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionTerraformValuesCachedModuleFiles     ForeignKeyConstraint = "template_version_terraform_values_cached_module_files_fkey"      // ALTER TABLE ONLY template_version_terraform_values ADD CONSTRAINT template_version_terraform_values_cached_module_files_fkey FOREIGN KEY (cached_module_files) REFERENCES files(id);
 	ForeignKeyTemplateVersionTerraformValuesTemplateVersionID     ForeignKeyConstraint = "template_version_terraform_values_template_version_id_fkey"      // ALTER TABLE ONLY template_version_terraform_values ADD CONSTRAINT template_version_terraform_values_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionVariablesTemplateVersionID           ForeignKeyConstraint = "template_version_variables_template_version_id_fkey"             // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionVariablesValueKeyID                  ForeignKeyConstraint = "template_version_variables_value_key_id_fkey"                    // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 	ForeignKeyTemplateVersionWorkspaceTagsTemplateVersionID       ForeignKeyConstraint = "template_version_workspace_tags_template_version_id_fkey"        // ALTER TABLE ONLY template_version_workspace_tags ADD CONSTRAINT template_version_workspace_tags_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionsCreatedBy                           ForeignKeyConstraint = "template_versions_created_by_fkey"                               // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
 	ForeignKeyTemplateVersionsOrganizationID                      ForeignKeyConstraint = "template_versions_organization_id_fkey"                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionsTemplateID                          ForeignKeyConstraint = "template_versions_template_id_fkey"                              // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
 	ForeignKeyTemplatesCreatedBy                                  ForeignKeyConstraint = "templates_created_by_fkey"                                       // ALTER TABLE ONLY templates ADD CONSTRAINT templates_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
 	ForeignKeyTemplatesOrganizationID                             ForeignKeyConstraint = "templates_organization_id_fkey"                                  // ALTER TABLE ONLY templates ADD CONSTRAINT templates_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyUserActivityExportsCreatedBy                        ForeignKeyConstraint = "user_activity_exports_created_by_fkey"                           // ALTER TABLE ONLY user_activity_exports ADD CONSTRAINT user_activity_exports_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyUserActivityExportsFileID                           ForeignKeyConstraint = "user_activity_exports_file_id_fkey"                              // ALTER TABLE ONLY user_activity_exports ADD CONSTRAINT user_activity_exports_file_id_fkey FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE SET NULL;
 	ForeignKeyUserConfigsUserID                                   ForeignKeyConstraint = "user_configs_user_id_fkey"                                       // ALTER TABLE ONLY user_configs ADD CONSTRAINT user_configs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyUserDeletedUserID                                   ForeignKeyConstraint = "user_deleted_user_id_fkey"                                       // ALTER TABLE ONLY user_deleted ADD CONSTRAINT user_deleted_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 	ForeignKeyUserLinksOauthAccessTokenKeyID                      ForeignKeyConstraint = "user_links_oauth_access_token_key_id_fkey"                       // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_oauth_access_token_key_id_fkey FOREIGN KEY (oauth_access_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
@@ -61,11 +93,14 @@ const (
 	ForeignKeyUserLinksUserID                                     ForeignKeyConstraint = "user_links_user_id_fkey"                                         // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyUserStatusChangesUserID                             ForeignKeyConstraint = "user_status_changes_user_id_fkey"                                // ALTER TABLE ONLY user_status_changes ADD CONSTRAINT user_status_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 	ForeignKeyWebpushSubscriptionsUserID                          ForeignKeyConstraint = "webpush_subscriptions_user_id_fkey"                              // ALTER TABLE ONLY webpush_subscriptions ADD CONSTRAINT webpush_subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAgentConnectionQualityAgentID              ForeignKeyConstraint = "workspace_agent_connection_quality_agent_id_fkey"                // ALTER TABLE ONLY workspace_agent_connection_quality ADD CONSTRAINT workspace_agent_connection_quality_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentDevcontainersWorkspaceAgentID         ForeignKeyConstraint = "workspace_agent_devcontainers_workspace_agent_id_fkey"           // ALTER TABLE ONLY workspace_agent_devcontainers ADD CONSTRAINT workspace_agent_devcontainers_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAgentHealthEventsAgentID                   ForeignKeyConstraint = "workspace_agent_health_events_agent_id_fkey"                     // ALTER TABLE ONLY workspace_agent_health_events ADD CONSTRAINT workspace_agent_health_events_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentLogSourcesWorkspaceAgentID            ForeignKeyConstraint = "workspace_agent_log_sources_workspace_agent_id_fkey"             // ALTER TABLE ONLY workspace_agent_log_sources ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentMemoryResourceMonitorsAgentID         ForeignKeyConstraint = "workspace_agent_memory_resource_monitors_agent_id_fkey"          // ALTER TABLE ONLY workspace_agent_memory_resource_monitors ADD CONSTRAINT workspace_agent_memory_resource_monitors_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentMetadataWorkspaceAgentID              ForeignKeyConstraint = "workspace_agent_metadata_workspace_agent_id_fkey"                // ALTER TABLE ONLY workspace_agent_metadata ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentPortShareWorkspaceID                  ForeignKeyConstraint = "workspace_agent_port_share_workspace_id_fkey"                    // ALTER TABLE ONLY workspace_agent_port_share ADD CONSTRAINT workspace_agent_port_share_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAgentResourceUsageAgentID                  ForeignKeyConstraint = "workspace_agent_resource_usage_agent_id_fkey"                    // ALTER TABLE ONLY workspace_agent_resource_usage ADD CONSTRAINT workspace_agent_resource_usage_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentScriptTimingsScriptID                 ForeignKeyConstraint = "workspace_agent_script_timings_script_id_fkey"                   // ALTER TABLE ONLY workspace_agent_script_timings ADD CONSTRAINT workspace_agent_script_timings_script_id_fkey FOREIGN KEY (script_id) REFERENCES workspace_agent_scripts(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentScriptsWorkspaceAgentID               ForeignKeyConstraint = "workspace_agent_scripts_workspace_agent_id_fkey"                 // ALTER TABLE ONLY workspace_agent_scripts ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentStartupLogsAgentID                    ForeignKeyConstraint = "workspace_agent_startup_logs_agent_id_fkey"                      // ALTER TABLE ONLY workspace_agent_logs ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
@@ -80,15 +115,49 @@ const (
 	ForeignKeyWorkspaceAppStatusesAppID                           ForeignKeyConstraint = "workspace_app_statuses_app_id_fkey"                              // ALTER TABLE ONLY workspace_app_statuses ADD CONSTRAINT workspace_app_statuses_app_id_fkey FOREIGN KEY (app_id) REFERENCES workspace_apps(id);
 	ForeignKeyWorkspaceAppStatusesWorkspaceID                     ForeignKeyConstraint = "workspace_app_statuses_workspace_id_fkey"                        // ALTER TABLE ONLY workspace_app_statuses ADD CONSTRAINT workspace_app_statuses_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
 	ForeignKeyWorkspaceAppsAgentID                                ForeignKeyConstraint = "workspace_apps_agent_id_fkey"                                    // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildArchivesFileID                        ForeignKeyConstraint = "workspace_build_archives_file_id_fkey"                           // ALTER TABLE ONLY workspace_build_archives ADD CONSTRAINT workspace_build_archives_file_id_fkey FOREIGN KEY (file_id) REFERENCES files(id);
+	ForeignKeyWorkspaceBuildArchivesWorkspaceBuildID              ForeignKeyConstraint = "workspace_build_archives_workspace_build_id_fkey"                // ALTER TABLE ONLY workspace_build_archives ADD CONSTRAINT workspace_build_archives_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildDurationNotificationsWorkspaceBuildID ForeignKeyConstraint = "workspace_build_duration_notifications_workspace_build_id_fkey"  // ALTER TABLE ONLY workspace_build_duration_notifications ADD CONSTRAINT workspace_build_duration_notifications_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildIdempotencyKeysUserID                 ForeignKeyConstraint = "workspace_build_idempotency_keys_user_id_fkey"                   // ALTER TABLE ONLY workspace_build_idempotency_keys ADD CONSTRAINT workspace_build_idempotency_keys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildIdempotencyKeysWorkspaceBuildID       ForeignKeyConstraint = "workspace_build_idempotency_keys_workspace_build_id_fkey"        // ALTER TABLE ONLY workspace_build_idempotency_keys ADD CONSTRAINT workspace_build_idempotency_keys_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildInterimStatesWorkspaceBuildID         ForeignKeyConstraint = "workspace_build_interim_states_workspace_build_id_fkey"          // ALTER TABLE ONLY workspace_build_interim_states ADD CONSTRAINT workspace_build_interim_states_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceBuildParametersWorkspaceBuildID            ForeignKeyConstraint = "workspace_build_parameters_workspace_build_id_fkey"              // ALTER TABLE ONLY workspace_build_parameters ADD CONSTRAINT workspace_build_parameters_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildQueueInitiatorID                      ForeignKeyConstraint = "workspace_build_queue_initiator_id_fkey"                         // ALTER TABLE ONLY workspace_build_queue ADD CONSTRAINT workspace_build_queue_initiator_id_fkey FOREIGN KEY (initiator_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildQueueTemplateVersionID                ForeignKeyConstraint = "workspace_build_queue_template_version_id_fkey"                  // ALTER TABLE ONLY workspace_build_queue ADD CONSTRAINT workspace_build_queue_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildQueueTemplateVersionPresetID          ForeignKeyConstraint = "workspace_build_queue_template_version_preset_id_fkey"           // ALTER TABLE ONLY workspace_build_queue ADD CONSTRAINT workspace_build_queue_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE SET NULL;
+	ForeignKeyWorkspaceBuildQueueWorkspaceID                      ForeignKeyConstraint = "workspace_build_queue_workspace_id_fkey"                         // ALTER TABLE ONLY workspace_build_queue ADD CONSTRAINT workspace_build_queue_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceBuildsAiTaskSidebarAppID                   ForeignKeyConstraint = "workspace_builds_ai_task_sidebar_app_id_fkey"                    // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_ai_task_sidebar_app_id_fkey FOREIGN KEY (ai_task_sidebar_app_id) REFERENCES workspace_apps(id);
 	ForeignKeyWorkspaceBuildsJobID                                ForeignKeyConstraint = "workspace_builds_job_id_fkey"                                    // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceBuildsTemplateVersionID                    ForeignKeyConstraint = "workspace_builds_template_version_id_fkey"                       // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceBuildsTemplateVersionPresetID              ForeignKeyConstraint = "workspace_builds_template_version_preset_id_fkey"                // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE SET NULL;
 	ForeignKeyWorkspaceBuildsWorkspaceID                          ForeignKeyConstraint = "workspace_builds_workspace_id_fkey"                              // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceDriftChecksJobID                           ForeignKeyConstraint = "workspace_drift_checks_job_id_fkey"                              // ALTER TABLE ONLY workspace_drift_checks ADD CONSTRAINT workspace_drift_checks_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceDriftChecksWorkspaceBuildID                ForeignKeyConstraint = "workspace_drift_checks_workspace_build_id_fkey"                  // ALTER TABLE ONLY workspace_drift_checks ADD CONSTRAINT workspace_drift_checks_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceDriftChecksWorkspaceID                     ForeignKeyConstraint = "workspace_drift_checks_workspace_id_fkey"                        // ALTER TABLE ONLY workspace_drift_checks ADD CONSTRAINT workspace_drift_checks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceLabelsWorkspaceID                          ForeignKeyConstraint = "workspace_labels_workspace_id_fkey"                              // ALTER TABLE ONLY workspace_labels ADD CONSTRAINT workspace_labels_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceLocksLockedBy                              ForeignKeyConstraint = "workspace_locks_locked_by_fkey"                                  // ALTER TABLE ONLY workspace_locks ADD CONSTRAINT workspace_locks_locked_by_fkey FOREIGN KEY (locked_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceLocksUnlockedBy                            ForeignKeyConstraint = "workspace_locks_unlocked_by_fkey"                                // ALTER TABLE ONLY workspace_locks ADD CONSTRAINT workspace_locks_unlocked_by_fkey FOREIGN KEY (unlocked_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceLocksWorkspaceID                           ForeignKeyConstraint = "workspace_locks_workspace_id_fkey"                               // ALTER TABLE ONLY workspace_locks ADD CONSTRAINT workspace_locks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceMigrationsDestinationSessionTokenKeyID     ForeignKeyConstraint = "workspace_migrations_destination_session_token_key_id_fkey"      // ALTER TABLE ONLY workspace_migrations ADD CONSTRAINT workspace_migrations_destination_session_token_key_id_fkey FOREIGN KEY (destination_session_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+	ForeignKeyWorkspaceMigrationsInitiatorID                      ForeignKeyConstraint = "workspace_migrations_initiator_id_fkey"                          // ALTER TABLE ONLY workspace_migrations ADD CONSTRAINT workspace_migrations_initiator_id_fkey FOREIGN KEY (initiator_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceMigrationsWorkspaceID                      ForeignKeyConstraint = "workspace_migrations_workspace_id_fkey"                          // ALTER TABLE ONLY workspace_migrations ADD CONSTRAINT workspace_migrations_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceModulesJobID                               ForeignKeyConstraint = "workspace_modules_job_id_fkey"                                   // ALTER TABLE ONLY workspace_modules ADD CONSTRAINT workspace_modules_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceNamingPoliciesOrganizationID               ForeignKeyConstraint = "workspace_naming_policies_organization_id_fkey"                  // ALTER TABLE ONLY workspace_naming_policies ADD CONSTRAINT workspace_naming_policies_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceNamingPoliciesTemplateID                   ForeignKeyConstraint = "workspace_naming_policies_template_id_fkey"                      // ALTER TABLE ONLY workspace_naming_policies ADD CONSTRAINT workspace_naming_policies_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyWorkspacePrebuildReservationsGroupID                ForeignKeyConstraint = "workspace_prebuild_reservations_group_id_fkey"                   // ALTER TABLE ONLY workspace_prebuild_reservations ADD CONSTRAINT workspace_prebuild_reservations_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+	ForeignKeyWorkspacePrebuildReservationsReservedBy             ForeignKeyConstraint = "workspace_prebuild_reservations_reserved_by_fkey"                // ALTER TABLE ONLY workspace_prebuild_reservations ADD CONSTRAINT workspace_prebuild_reservations_reserved_by_fkey FOREIGN KEY (reserved_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspacePrebuildReservationsUserID                 ForeignKeyConstraint = "workspace_prebuild_reservations_user_id_fkey"                    // ALTER TABLE ONLY workspace_prebuild_reservations ADD CONSTRAINT workspace_prebuild_reservations_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspacePrebuildReservationsWorkspaceID            ForeignKeyConstraint = "workspace_prebuild_reservations_workspace_id_fkey"               // ALTER TABLE ONLY workspace_prebuild_reservations ADD CONSTRAINT workspace_prebuild_reservations_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceProvisionerAffinitiesProvisionerDaemonID   ForeignKeyConstraint = "workspace_provisioner_affinities_provisioner_daemon_id_fkey"     // ALTER TABLE ONLY workspace_provisioner_affinities ADD CONSTRAINT workspace_provisioner_affinities_provisioner_daemon_id_fkey FOREIGN KEY (provisioner_daemon_id) REFERENCES provisioner_daemons(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceProvisionerAffinitiesTemplateVersionID     ForeignKeyConstraint = "workspace_provisioner_affinities_template_version_id_fkey"       // ALTER TABLE ONLY workspace_provisioner_affinities ADD CONSTRAINT workspace_provisioner_affinities_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceProvisionerAffinitiesWorkspaceID           ForeignKeyConstraint = "workspace_provisioner_affinities_workspace_id_fkey"              // ALTER TABLE ONLY workspace_provisioner_affinities ADD CONSTRAINT workspace_provisioner_affinities_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceReleaseChannelsWorkspaceID                 ForeignKeyConstraint = "workspace_release_channels_workspace_id_fkey"                    // ALTER TABLE ONLY workspace_release_channels ADD CONSTRAINT workspace_release_channels_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceResourceMetadataWorkspaceResourceID        ForeignKeyConstraint = "workspace_resource_metadata_workspace_resource_id_fkey"          // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceResourcesJobID                             ForeignKeyConstraint = "workspace_resources_job_id_fkey"                                 // ALTER TABLE ONLY workspace_resources ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceSessionRecordingChunksDataKeyID            ForeignKeyConstraint = "workspace_session_recording_chunks_data_key_id_fkey"             // ALTER TABLE ONLY workspace_session_recording_chunks ADD CONSTRAINT workspace_session_recording_chunks_data_key_id_fkey FOREIGN KEY (data_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+	ForeignKeyWorkspaceSessionRecordingChunksRecordingID          ForeignKeyConstraint = "workspace_session_recording_chunks_recording_id_fkey"            // ALTER TABLE ONLY workspace_session_recording_chunks ADD CONSTRAINT workspace_session_recording_chunks_recording_id_fkey FOREIGN KEY (recording_id) REFERENCES workspace_session_recordings(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceSessionRecordingsAgentID                   ForeignKeyConstraint = "workspace_session_recordings_agent_id_fkey"                      // ALTER TABLE ONLY workspace_session_recordings ADD CONSTRAINT workspace_session_recordings_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceSessionRecordingsWorkspaceID               ForeignKeyConstraint = "workspace_session_recordings_workspace_id_fkey"                  // ALTER TABLE ONLY workspace_session_recordings ADD CONSTRAINT workspace_session_recordings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyWorkspacesOrganizationID                            ForeignKeyConstraint = "workspaces_organization_id_fkey"                                 // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 	ForeignKeyWorkspacesOwnerID                                   ForeignKeyConstraint = "workspaces_owner_id_fkey"                                        // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE RESTRICT;
 	ForeignKeyWorkspacesTemplateID                                ForeignKeyConstraint = "workspaces_template_id_fkey"                                     // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE RESTRICT;
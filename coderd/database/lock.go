@@ -13,6 +13,12 @@ const (
 	LockIDNotificationsReportGenerator
 	LockIDCryptoKeyRotation
 	LockIDReconcilePrebuilds
+	LockIDJobLogArchive
+	LockIDBuildAlerts
+	LockIDDriftCheck
+	LockIDAuditLogExport
+	LockIDLicenseUserLimitNotification
+	LockIDWorkspaceBuildArchive
 )
 
 // GenLockID generates a unique and consistent lock ID from a given string.
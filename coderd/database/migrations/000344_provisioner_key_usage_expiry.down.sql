@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS provisioner_keys_previous_hashed_secret_idx;
+
+ALTER TABLE provisioner_keys
+	DROP COLUMN IF EXISTS previous_secret_expires_at,
+	DROP COLUMN IF EXISTS previous_hashed_secret,
+	DROP COLUMN IF EXISTS expires_at,
+	DROP COLUMN IF EXISTS last_used_at;
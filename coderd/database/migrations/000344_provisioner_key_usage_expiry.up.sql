@@ -0,0 +1,12 @@
+ALTER TABLE provisioner_keys
+	ADD COLUMN last_used_at timestamp with time zone,
+	ADD COLUMN expires_at timestamp with time zone,
+	ADD COLUMN previous_hashed_secret bytea,
+	ADD COLUMN previous_secret_expires_at timestamp with time zone;
+
+COMMENT ON COLUMN provisioner_keys.last_used_at IS 'The last time a provisioner daemon authenticated with this key.';
+COMMENT ON COLUMN provisioner_keys.expires_at IS 'When set, the key can no longer be used to authenticate after this time.';
+COMMENT ON COLUMN provisioner_keys.previous_hashed_secret IS 'The hashed secret that was replaced by the most recent rotation. It remains valid until previous_secret_expires_at so that daemons can be migrated to the new secret without downtime.';
+COMMENT ON COLUMN provisioner_keys.previous_secret_expires_at IS 'The end of the rotation overlap window for previous_hashed_secret.';
+
+CREATE INDEX provisioner_keys_previous_hashed_secret_idx ON provisioner_keys USING btree (previous_hashed_secret) WHERE (previous_hashed_secret IS NOT NULL);
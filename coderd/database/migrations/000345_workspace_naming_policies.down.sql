@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_naming_policies;
+
+DROP TYPE IF EXISTS workspace_name_uniqueness_scope;
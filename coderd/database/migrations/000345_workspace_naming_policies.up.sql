@@ -0,0 +1,26 @@
+CREATE TYPE workspace_name_uniqueness_scope AS ENUM (
+	'owner',
+	'organization'
+);
+
+CREATE TABLE workspace_naming_policies (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	template_id uuid REFERENCES templates (id) ON DELETE CASCADE,
+	regex text NOT NULL DEFAULT '',
+	prefix text NOT NULL DEFAULT '',
+	suffix text NOT NULL DEFAULT '',
+	uniqueness_scope workspace_name_uniqueness_scope NOT NULL DEFAULT 'owner',
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_naming_policies IS 'Naming rules that are enforced when workspaces are created. A row without a template_id applies to the whole organization, a row with a template_id applies to workspaces created from that template and takes precedence over the organization policy.';
+COMMENT ON COLUMN workspace_naming_policies.regex IS 'Regular expression the full workspace name must match. Empty disables the check.';
+COMMENT ON COLUMN workspace_naming_policies.prefix IS 'Go template rendered with the owner, organization and template names. The workspace name must start with the result.';
+COMMENT ON COLUMN workspace_naming_policies.suffix IS 'Go template rendered with the owner, organization and template names. The workspace name must end with the result.';
+COMMENT ON COLUMN workspace_naming_policies.uniqueness_scope IS 'Scope in which workspace names must be unique.';
+
+CREATE UNIQUE INDEX workspace_naming_policies_organization_id_idx ON workspace_naming_policies USING btree (organization_id) WHERE (template_id IS NULL);
+
+CREATE UNIQUE INDEX workspace_naming_policies_template_id_idx ON workspace_naming_policies USING btree (template_id) WHERE (template_id IS NOT NULL);
@@ -0,0 +1,17 @@
+CREATE TABLE provisioner_reservations (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	tags jsonb NOT NULL,
+	slots integer NOT NULL,
+	starts_at timestamp with time zone NOT NULL,
+	ends_at timestamp with time zone NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	CONSTRAINT provisioner_reservations_slots_check CHECK (slots > 0),
+	CONSTRAINT provisioner_reservations_window_check CHECK (ends_at > starts_at)
+);
+
+COMMENT ON TABLE provisioner_reservations IS 'Provisioner capacity held back for jobs with a specific tag set during a time window, e.g. for scheduled trainings.';
+COMMENT ON COLUMN provisioner_reservations.tags IS 'Jobs whose tags contain these tags may use the reserved slots. Provisioners whose tags contain these tags provide the slots.';
+COMMENT ON COLUMN provisioner_reservations.slots IS 'Number of concurrent jobs that provisioners able to serve the reservation keep available for reserved jobs.';
+
+CREATE INDEX provisioner_reservations_organization_id_ends_at_idx ON provisioner_reservations USING btree (organization_id, ends_at);
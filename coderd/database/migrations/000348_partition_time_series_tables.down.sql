@@ -0,0 +1,39 @@
+DROP FUNCTION drop_time_partitions(text, timestamp with time zone);
+DROP FUNCTION create_time_partition(text, timestamp with time zone, timestamp with time zone);
+
+-- Move the rows of all range partitions back into the default partitions,
+-- which then become regular tables again.
+
+-- workspace_agent_stats
+ALTER TABLE workspace_agent_stats DETACH PARTITION workspace_agent_stats_default;
+INSERT INTO workspace_agent_stats_default SELECT * FROM workspace_agent_stats;
+DROP TABLE workspace_agent_stats;
+ALTER TABLE workspace_agent_stats_default RENAME TO workspace_agent_stats;
+ALTER TABLE workspace_agent_stats DROP CONSTRAINT workspace_agent_stats_default_pkey;
+ALTER TABLE workspace_agent_stats ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id);
+ALTER INDEX workspace_agent_stats_default_created_at_idx RENAME TO idx_agent_stats_created_at;
+ALTER INDEX workspace_agent_stats_default_user_id_idx RENAME TO idx_agent_stats_user_id;
+ALTER INDEX workspace_agent_stats_default_template_id_created_at_idx RENAME TO workspace_agent_stats_template_id_created_at_user_id_idx;
+
+-- provisioner_job_logs
+ALTER TABLE provisioner_job_logs DETACH PARTITION provisioner_job_logs_default;
+INSERT INTO provisioner_job_logs_default SELECT * FROM provisioner_job_logs;
+ALTER SEQUENCE provisioner_job_logs_id_seq OWNED BY provisioner_job_logs_default.id;
+DROP TABLE provisioner_job_logs;
+ALTER TABLE provisioner_job_logs_default RENAME TO provisioner_job_logs;
+ALTER TABLE provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
+ALTER TABLE provisioner_job_logs DROP CONSTRAINT provisioner_job_logs_default_pkey;
+ALTER TABLE provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
+ALTER INDEX provisioner_job_logs_default_job_id_id_idx RENAME TO provisioner_job_logs_id_job_id_idx;
+
+-- audit_logs
+ALTER TABLE audit_logs DETACH PARTITION audit_logs_default;
+INSERT INTO audit_logs_default SELECT * FROM audit_logs;
+DROP TABLE audit_logs;
+ALTER TABLE audit_logs_default RENAME TO audit_logs;
+ALTER TABLE audit_logs DROP CONSTRAINT audit_logs_default_pkey;
+ALTER TABLE audit_logs ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
+ALTER INDEX audit_logs_default_organization_id_idx RENAME TO idx_audit_log_organization_id;
+ALTER INDEX audit_logs_default_resource_id_idx RENAME TO idx_audit_log_resource_id;
+ALTER INDEX audit_logs_default_user_id_idx RENAME TO idx_audit_log_user_id;
+ALTER INDEX audit_logs_default_time_idx RENAME TO idx_audit_logs_time_desc;
@@ -0,0 +1,169 @@
+-- Partition the largest append-only tables by time so that old data can be
+-- removed by dropping whole partitions instead of deleting rows.
+--
+-- Each existing table is attached as the DEFAULT partition of a new
+-- partitioned table with the same name. Existing indexes are renamed and
+-- reused so that only the primary keys, which must include the partition
+-- key, are rebuilt. Range partitions are created ahead of time by dbpurge.
+
+-- audit_logs
+ALTER TABLE audit_logs RENAME TO audit_logs_default;
+ALTER TABLE audit_logs_default DROP CONSTRAINT audit_logs_pkey;
+ALTER TABLE audit_logs_default ADD CONSTRAINT audit_logs_default_pkey PRIMARY KEY (id, "time");
+ALTER INDEX idx_audit_log_organization_id RENAME TO audit_logs_default_organization_id_idx;
+ALTER INDEX idx_audit_log_resource_id RENAME TO audit_logs_default_resource_id_idx;
+ALTER INDEX idx_audit_log_user_id RENAME TO audit_logs_default_user_id_idx;
+ALTER INDEX idx_audit_logs_time_desc RENAME TO audit_logs_default_time_idx;
+
+CREATE TABLE audit_logs (
+	id uuid NOT NULL,
+	"time" timestamp with time zone NOT NULL,
+	user_id uuid NOT NULL,
+	organization_id uuid NOT NULL,
+	ip inet,
+	user_agent character varying(256),
+	resource_type resource_type NOT NULL,
+	resource_id uuid NOT NULL,
+	resource_target text NOT NULL,
+	action audit_action NOT NULL,
+	diff jsonb NOT NULL,
+	status_code integer NOT NULL,
+	additional_fields jsonb NOT NULL,
+	request_id uuid NOT NULL,
+	resource_icon text NOT NULL
+) PARTITION BY RANGE ("time");
+
+ALTER TABLE audit_logs ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id, "time");
+CREATE INDEX idx_audit_log_organization_id ON audit_logs USING btree (organization_id);
+CREATE INDEX idx_audit_log_resource_id ON audit_logs USING btree (resource_id);
+CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
+CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
+
+ALTER TABLE audit_logs ATTACH PARTITION audit_logs_default DEFAULT;
+
+-- provisioner_job_logs
+ALTER TABLE provisioner_job_logs RENAME TO provisioner_job_logs_default;
+ALTER TABLE provisioner_job_logs_default DROP CONSTRAINT provisioner_job_logs_pkey;
+ALTER TABLE provisioner_job_logs_default ADD CONSTRAINT provisioner_job_logs_default_pkey PRIMARY KEY (id, created_at);
+ALTER TABLE provisioner_job_logs_default ALTER COLUMN id DROP DEFAULT;
+ALTER INDEX provisioner_job_logs_id_job_id_idx RENAME TO provisioner_job_logs_default_job_id_id_idx;
+
+CREATE TABLE provisioner_job_logs (
+	job_id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	source log_source NOT NULL,
+	level log_level NOT NULL,
+	stage character varying(128) NOT NULL,
+	output character varying(1024) NOT NULL,
+	id bigint NOT NULL
+) PARTITION BY RANGE (created_at);
+
+ALTER SEQUENCE provisioner_job_logs_id_seq OWNED BY provisioner_job_logs.id;
+ALTER TABLE provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
+
+ALTER TABLE provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id, created_at);
+CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
+
+ALTER TABLE provisioner_job_logs ATTACH PARTITION provisioner_job_logs_default DEFAULT;
+
+-- The existing foreign key on the default partition is reused.
+ALTER TABLE provisioner_job_logs
+	ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
+-- workspace_agent_stats
+ALTER TABLE workspace_agent_stats RENAME TO workspace_agent_stats_default;
+ALTER TABLE workspace_agent_stats_default DROP CONSTRAINT agent_stats_pkey;
+ALTER TABLE workspace_agent_stats_default ADD CONSTRAINT workspace_agent_stats_default_pkey PRIMARY KEY (id, created_at);
+ALTER INDEX idx_agent_stats_created_at RENAME TO workspace_agent_stats_default_created_at_idx;
+ALTER INDEX idx_agent_stats_user_id RENAME TO workspace_agent_stats_default_user_id_idx;
+ALTER INDEX workspace_agent_stats_template_id_created_at_user_id_idx RENAME TO workspace_agent_stats_default_template_id_created_at_idx;
+
+CREATE TABLE workspace_agent_stats (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	user_id uuid NOT NULL,
+	agent_id uuid NOT NULL,
+	workspace_id uuid NOT NULL,
+	template_id uuid NOT NULL,
+	connections_by_proto jsonb DEFAULT '{}'::jsonb NOT NULL,
+	connection_count bigint DEFAULT 0 NOT NULL,
+	rx_packets bigint DEFAULT 0 NOT NULL,
+	rx_bytes bigint DEFAULT 0 NOT NULL,
+	tx_packets bigint DEFAULT 0 NOT NULL,
+	tx_bytes bigint DEFAULT 0 NOT NULL,
+	connection_median_latency_ms double precision DEFAULT '-1'::integer NOT NULL,
+	session_count_vscode bigint DEFAULT 0 NOT NULL,
+	session_count_jetbrains bigint DEFAULT 0 NOT NULL,
+	session_count_reconnecting_pty bigint DEFAULT 0 NOT NULL,
+	session_count_ssh bigint DEFAULT 0 NOT NULL,
+	usage boolean DEFAULT false NOT NULL
+) PARTITION BY RANGE (created_at);
+
+ALTER TABLE workspace_agent_stats ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id, created_at);
+CREATE INDEX idx_agent_stats_created_at ON workspace_agent_stats USING btree (created_at);
+CREATE INDEX idx_agent_stats_user_id ON workspace_agent_stats USING btree (user_id);
+CREATE INDEX workspace_agent_stats_template_id_created_at_user_id_idx ON workspace_agent_stats USING btree (template_id, created_at, user_id) INCLUDE (session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, connection_median_latency_ms) WHERE (connection_count > 0);
+
+COMMENT ON INDEX workspace_agent_stats_template_id_created_at_user_id_idx IS 'Support index for template insights endpoint to build interval reports faster.';
+
+ALTER TABLE workspace_agent_stats ATTACH PARTITION workspace_agent_stats_default DEFAULT;
+
+CREATE FUNCTION create_time_partition(parent_table text, partition_start timestamp with time zone, partition_end timestamp with time zone)
+RETURNS boolean AS $$
+DECLARE
+	partition_name text := parent_table || '_p' || to_char(partition_start AT TIME ZONE 'UTC', 'YYYYMMDD');
+	partition_key text;
+	has_rows boolean;
+BEGIN
+	IF to_regclass(partition_name) IS NOT NULL THEN
+		RETURN false;
+	END IF;
+
+	SELECT pg_attribute.attname INTO partition_key
+	FROM pg_partitioned_table
+	JOIN pg_attribute ON pg_attribute.attrelid = pg_partitioned_table.partrelid
+		AND pg_attribute.attnum = pg_partitioned_table.partattrs[0]
+	WHERE pg_partitioned_table.partrelid = parent_table::regclass;
+
+	-- A range partition cannot be created while the default partition holds
+	-- rows that belong in it. Those rows are eventually removed by the
+	-- regular purge queries, so the partition is created on a later attempt.
+	EXECUTE format('SELECT EXISTS (SELECT 1 FROM %I WHERE %I >= $1 AND %I < $2)', parent_table || '_default', partition_key, partition_key)
+		INTO has_rows
+		USING partition_start, partition_end;
+	IF has_rows THEN
+		RETURN false;
+	END IF;
+
+	EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)', partition_name, parent_table, partition_start, partition_end);
+	RETURN true;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION create_time_partition(text, timestamp with time zone, timestamp with time zone) IS 'Creates a range partition of parent_table covering [partition_start, partition_end). Returns false if the partition already exists or if rows in that range are still stored in the default partition.';
+
+CREATE FUNCTION drop_time_partitions(parent_table text, older_than timestamp with time zone)
+RETURNS integer AS $$
+DECLARE
+	part record;
+	dropped integer := 0;
+BEGIN
+	FOR part IN
+		SELECT
+			pg_class.oid::regclass AS name,
+			substring(pg_get_expr(pg_class.relpartbound, pg_class.oid) FROM 'TO \(''([^'']+)''\)')::timestamp with time zone AS partition_end
+		FROM pg_inherits
+		JOIN pg_class ON pg_class.oid = pg_inherits.inhrelid
+		WHERE pg_inherits.inhparent = parent_table::regclass
+	LOOP
+		-- The default partition has no upper bound and is never dropped.
+		IF part.partition_end IS NOT NULL AND part.partition_end <= older_than THEN
+			EXECUTE format('DROP TABLE %s', part.name);
+			dropped := dropped + 1;
+		END IF;
+	END LOOP;
+	RETURN dropped;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION drop_time_partitions(text, timestamp with time zone) IS 'Drops the range partitions of parent_table whose upper bound is at or before older_than. Returns the number of dropped partitions.';
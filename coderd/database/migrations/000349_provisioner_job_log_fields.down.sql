@@ -0,0 +1 @@
+ALTER TABLE provisioner_job_logs DROP COLUMN fields;
@@ -0,0 +1,3 @@
+ALTER TABLE provisioner_job_logs ADD COLUMN fields jsonb DEFAULT '{}'::jsonb NOT NULL;
+
+COMMENT ON COLUMN provisioner_job_logs.fields IS 'Structured fields extracted from machine-readable provisioner output, such as the Terraform resource address, action and diagnostic severity.';
@@ -0,0 +1,8 @@
+CREATE TABLE provisioner_job_log_archives (
+	job_id uuid NOT NULL PRIMARY KEY REFERENCES provisioner_jobs(id) ON DELETE CASCADE,
+	file_id uuid NOT NULL REFERENCES files(id),
+	log_count integer NOT NULL,
+	archived_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_log_archives IS 'Provisioner job logs that were moved out of provisioner_job_logs into a compressed file after the retention period.';
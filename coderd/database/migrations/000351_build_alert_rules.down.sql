@@ -0,0 +1,5 @@
+DELETE FROM notification_templates WHERE id = '5d4ba6d2-1d4b-4a1b-9a0e-6b4f0c1f4b8e';
+
+DROP TABLE IF EXISTS build_alert_rules;
+
+DROP TYPE IF EXISTS build_alert_rule_metric;
@@ -0,0 +1,46 @@
+CREATE TYPE build_alert_rule_metric AS ENUM (
+	'failure_rate',
+	'queue_wait_p95'
+);
+
+CREATE TABLE build_alert_rules (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	template_id uuid REFERENCES templates (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	metric build_alert_rule_metric NOT NULL,
+	threshold double precision NOT NULL,
+	window_ms bigint NOT NULL,
+	created_by uuid REFERENCES users (id) ON DELETE SET NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	last_evaluated_at timestamp with time zone,
+	last_value double precision,
+	breached_at timestamp with time zone,
+	CONSTRAINT build_alert_rules_window_ms_check CHECK (window_ms > 0)
+);
+
+COMMENT ON TABLE build_alert_rules IS 'Rules over workspace build metrics that notify template admins when breached.';
+COMMENT ON COLUMN build_alert_rules.template_id IS 'Restricts the rule to builds of a single template. If NULL, the rule covers all templates of the organization.';
+COMMENT ON COLUMN build_alert_rules.threshold IS 'The rule is breached when the metric is above this value. Percent for failure_rate, milliseconds for queue_wait_p95.';
+COMMENT ON COLUMN build_alert_rules.breached_at IS 'Set when the rule became breached, and cleared once the metric is back under the threshold. Notifications are only sent when it is set.';
+
+CREATE UNIQUE INDEX build_alert_rules_organization_id_name_idx ON build_alert_rules USING btree (organization_id, lower(name));
+
+INSERT INTO notification_templates
+(id, name, title_template, body_template, "group", actions)
+VALUES ('5d4ba6d2-1d4b-4a1b-9a0e-6b4f0c1f4b8e',
+		'Workspace Build Alert Triggered',
+		E'Build alert "{{.Labels.rule}}" triggered',
+		$$
+The {{.Labels.metric}} of workspace builds for {{.Labels.scope}} is **{{.Labels.value}}** over the last {{.Labels.window}}, above the threshold of **{{.Labels.threshold}}** set by the build alert rule **{{.Labels.rule}}**.
+
+You will be notified again if the rule is breached after recovering.
+$$,
+		'Template Events',
+		'[
+		{
+			"label": "View templates",
+			"url": "{{base_url}}/templates"
+		}
+	]'::jsonb);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS provisioner_job_logs_output_search_idx;
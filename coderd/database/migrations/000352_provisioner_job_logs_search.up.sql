@@ -0,0 +1,4 @@
+-- Supports full-text search of job logs. The 'simple' configuration is used
+-- because log output is mostly identifiers, paths and error messages that
+-- should not be stemmed.
+CREATE INDEX provisioner_job_logs_output_search_idx ON provisioner_job_logs USING gin (to_tsvector('simple', output));
@@ -0,0 +1,3 @@
+ALTER TABLE api_keys ADD COLUMN bound_identity text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN api_keys.bound_identity IS 'bound_identity is the identity of the client the key is bound to, e.g. the fingerprint of a client certificate or a device ID. Empty if the key is not bound. Requests using a bound key must present the same identity.';
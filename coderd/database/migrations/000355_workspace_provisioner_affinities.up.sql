@@ -0,0 +1,9 @@
+CREATE TABLE workspace_provisioner_affinities (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+	provisioner_daemon_id uuid NOT NULL REFERENCES provisioner_daemons (id) ON DELETE CASCADE,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_provisioner_affinities IS 'The provisioner daemon that last built a workspace successfully. The daemon likely still has the providers of the template version cached, so it is preferred for the next build of the workspace.';
+COMMENT ON COLUMN workspace_provisioner_affinities.template_version_id IS 'The template version of the last successful build. Affinity is only honored for builds of the same template version.';
@@ -0,0 +1,8 @@
+ALTER TABLE workspace_agents
+	DROP COLUMN collapsed,
+	DROP COLUMN display_group;
+
+ALTER TABLE workspace_resources
+	DROP COLUMN collapsed,
+	DROP COLUMN display_order,
+	DROP COLUMN display_group;
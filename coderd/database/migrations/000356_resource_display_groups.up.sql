@@ -0,0 +1,15 @@
+ALTER TABLE workspace_resources
+	ADD COLUMN display_group text NOT NULL DEFAULT '',
+	ADD COLUMN display_order integer NOT NULL DEFAULT 0,
+	ADD COLUMN collapsed boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspace_resources.display_group IS 'The name of the group the resource is displayed under in user interfaces. Empty means the resource is not grouped.';
+COMMENT ON COLUMN workspace_resources.display_order IS 'Specifies the order in which to display resources in user interfaces.';
+COMMENT ON COLUMN workspace_resources.collapsed IS 'Whether the resource should be collapsed by default in user interfaces.';
+
+ALTER TABLE workspace_agents
+	ADD COLUMN display_group text NOT NULL DEFAULT '',
+	ADD COLUMN collapsed boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspace_agents.display_group IS 'The name of the group the agent is displayed under in user interfaces. Empty means the agent is not grouped.';
+COMMENT ON COLUMN workspace_agents.collapsed IS 'Whether the agent should be collapsed by default in user interfaces.';
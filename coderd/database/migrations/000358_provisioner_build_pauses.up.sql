@@ -0,0 +1,15 @@
+CREATE TABLE provisioner_build_pauses (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+	reason text NOT NULL DEFAULT '',
+	created_by uuid REFERENCES users (id) ON DELETE SET NULL,
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE provisioner_build_pauses IS 'Provisioner jobs in the scope of a pause are accepted but held pending: provisioners do not acquire them and the job reaper does not time them out. Used during planned infrastructure maintenance.';
+COMMENT ON COLUMN provisioner_build_pauses.organization_id IS 'The organization whose builds are paused. NULL pauses builds across the deployment.';
+
+CREATE UNIQUE INDEX provisioner_build_pauses_organization_id_idx ON provisioner_build_pauses USING btree (organization_id) WHERE (organization_id IS NOT NULL);
+
+-- At most one deployment-wide pause.
+CREATE UNIQUE INDEX provisioner_build_pauses_deployment_idx ON provisioner_build_pauses USING btree ((organization_id IS NULL)) WHERE (organization_id IS NULL);
@@ -0,0 +1,7 @@
+CREATE TABLE workspace_build_interim_states (
+	workspace_build_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	state bytea NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_interim_states IS 'The provisioner state uploaded while a workspace build is applied. Removed once the build completes or fails with a final state. A build that did not complete can be resumed from it.';
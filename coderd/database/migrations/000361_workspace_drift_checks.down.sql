@@ -0,0 +1,3 @@
+DELETE FROM notification_templates WHERE id = '47878bb4-dd4e-4ef8-a1f7-f5dcbbada481';
+
+DROP TABLE IF EXISTS workspace_drift_checks;
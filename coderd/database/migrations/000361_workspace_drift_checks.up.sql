@@ -0,0 +1,44 @@
+CREATE TABLE workspace_drift_checks (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	job_id uuid NOT NULL REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	completed_at timestamp with time zone,
+	drifted_resources text[] NOT NULL DEFAULT '{}'::text[],
+	orphaned_resources text[] NOT NULL DEFAULT '{}'::text[]
+);
+
+COMMENT ON TABLE workspace_drift_checks IS 'The latest drift check of each workspace, which plans the latest build of a stopped or failed workspace against its state.';
+COMMENT ON COLUMN workspace_drift_checks.job_id IS 'The template version dry-run job that plans the build.';
+COMMENT ON COLUMN workspace_drift_checks.drifted_resources IS 'Addresses of resources that were changed outside of Terraform.';
+COMMENT ON COLUMN workspace_drift_checks.orphaned_resources IS 'Addresses of resources that are in the state but would be deleted by the plan.';
+
+CREATE UNIQUE INDEX workspace_drift_checks_job_id_idx ON workspace_drift_checks USING btree (job_id);
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES ('47878bb4-dd4e-4ef8-a1f7-f5dcbbada481',
+		'Workspace Drift Detected',
+		E'Workspace "{{.Labels.name}}" has drifted from its state',
+		$$
+Checking the resources of your workspace **{{.Labels.name}}** against its last build found differences.
+{{if .Data.orphaned}}
+The following resources are no longer part of the workspace but still exist, and may keep incurring costs:
+
+{{range .Data.orphaned -}}
+- `{{ . }}`
+{{end}}{{end}}{{if .Data.drifted}}
+The following resources were changed outside of Coder:
+
+{{range .Data.drifted -}}
+- `{{ . }}`
+{{end}}{{end}}
+The next build of the workspace reconciles these resources with its template.
+$$,
+		'Workspace Events',
+		'[
+		{
+			"label": "View workspace",
+			"url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.name}}"
+		}
+	]'::jsonb);
@@ -0,0 +1,19 @@
+CREATE TABLE workspace_build_queue (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	initiator_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	transition workspace_transition NOT NULL,
+	template_version_id uuid REFERENCES template_versions (id) ON DELETE CASCADE,
+	template_version_preset_id uuid REFERENCES template_version_presets (id) ON DELETE SET NULL,
+	rich_parameter_values jsonb NOT NULL DEFAULT '[]'::jsonb,
+	log_level text NOT NULL DEFAULT '',
+	created_at timestamp with time zone NOT NULL,
+	failed_at timestamp with time zone,
+	error text NOT NULL DEFAULT ''
+);
+
+COMMENT ON TABLE workspace_build_queue IS 'Workspace builds that were requested while another build of the workspace was active. The oldest entry of a workspace is started once no build of the workspace is active.';
+COMMENT ON COLUMN workspace_build_queue.template_version_id IS 'The template version to build, or NULL to build the version of the build that precedes it.';
+COMMENT ON COLUMN workspace_build_queue.failed_at IS 'Set when the queued build could not be started. Failed entries are kept so the error can be shown, and do not hold up later entries.';
+
+CREATE INDEX workspace_build_queue_workspace_id_created_at_idx ON workspace_build_queue USING btree (workspace_id, created_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS template_build_duration_stats;
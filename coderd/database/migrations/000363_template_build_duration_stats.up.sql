@@ -0,0 +1,23 @@
+CREATE TABLE template_build_duration_stats (
+	start_time timestamptz NOT NULL,
+	end_time timestamptz NOT NULL,
+	template_id uuid NOT NULL,
+	transition workspace_transition NOT NULL,
+	build_count integer NOT NULL,
+	p50_duration_ms bigint NOT NULL,
+	p95_duration_ms bigint NOT NULL,
+	max_duration_ms bigint NOT NULL,
+
+	PRIMARY KEY (start_time, template_id, transition)
+);
+
+COMMENT ON TABLE template_build_duration_stats IS 'Records aggregated durations of successful workspace builds per template and transition, in daily buckets.';
+COMMENT ON COLUMN template_build_duration_stats.start_time IS 'Start time of the period the builds completed in.';
+COMMENT ON COLUMN template_build_duration_stats.end_time IS 'End time of the period the builds completed in.';
+COMMENT ON COLUMN template_build_duration_stats.template_id IS 'ID of the template the builds are of.';
+COMMENT ON COLUMN template_build_duration_stats.build_count IS 'Number of successful builds that completed in the period.';
+COMMENT ON COLUMN template_build_duration_stats.p50_duration_ms IS 'Median build duration, in milliseconds.';
+COMMENT ON COLUMN template_build_duration_stats.p95_duration_ms IS '95th percentile build duration, in milliseconds.';
+COMMENT ON COLUMN template_build_duration_stats.max_duration_ms IS 'Longest build duration, in milliseconds.';
+
+CREATE INDEX template_build_duration_stats_template_id_start_time_idx ON template_build_duration_stats (template_id, start_time);
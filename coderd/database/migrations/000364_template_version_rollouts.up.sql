@@ -0,0 +1,13 @@
+CREATE TABLE template_version_rollouts (
+	template_id uuid NOT NULL PRIMARY KEY REFERENCES templates (id) ON DELETE CASCADE,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	percent integer NOT NULL CHECK (percent >= 0 AND percent <= 100),
+	group_id uuid REFERENCES groups (id) ON DELETE SET NULL,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_version_rollouts IS 'Gradual promotions of template versions. Builds that target the active version of the template use the rollout version instead if their workspace falls within the percentage or its owner is a member of the group.';
+COMMENT ON COLUMN template_version_rollouts.percent IS 'The percentage of workspaces, bucketed by workspace ID, that build the rollout version.';
+COMMENT ON COLUMN template_version_rollouts.group_id IS 'The group whose members always build the rollout version, regardless of the percentage.';
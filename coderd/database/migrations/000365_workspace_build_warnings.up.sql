@@ -0,0 +1,69 @@
+ALTER TABLE workspace_builds ADD COLUMN warnings jsonb NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN workspace_builds.warnings IS 'Warnings recorded when the build was created, such as the build using a template version that is not the active version of its template.';
+
+-- Recreate the workspace_build_with_user view to include the new column.
+DROP VIEW workspace_build_with_user;
+
+CREATE VIEW workspace_build_with_user AS
+SELECT
+    workspace_builds.id,
+    workspace_builds.created_at,
+    workspace_builds.updated_at,
+    workspace_builds.workspace_id,
+    workspace_builds.template_version_id,
+    workspace_builds.build_number,
+    workspace_builds.transition,
+    workspace_builds.initiator_id,
+    workspace_builds.provisioner_state,
+    workspace_builds.job_id,
+    workspace_builds.deadline,
+    workspace_builds.reason,
+    workspace_builds.daily_cost,
+    workspace_builds.max_deadline,
+    workspace_builds.template_version_preset_id,
+    workspace_builds.has_ai_task,
+    workspace_builds.ai_task_sidebar_app_id,
+    workspace_builds.initiator_context,
+    workspace_builds.warnings,
+    COALESCE(
+        visible_users.avatar_url,
+        '' :: text
+    ) AS initiator_by_avatar_url,
+    COALESCE(
+        visible_users.username,
+        '' :: text
+    ) AS initiator_by_username,
+    COALESCE(visible_users.name, '' :: text) AS initiator_by_name
+FROM
+    (
+        workspace_builds
+        LEFT JOIN visible_users ON (
+            (
+                workspace_builds.initiator_id = visible_users.id
+            )
+        )
+    );
+
+COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES ('b71525cb-0c72-4d1f-8fa7-c11695b81e80',
+		'Workspace Started With Outdated Template Version',
+		E'Workspace "{{.Labels.name}}" should be updated',
+		$$
+Your workspace **{{.Labels.name}}** was started with version **{{.Labels.template_version_name}}** of template **{{.Labels.template_name}}**.
+
+{{range .Data.warnings -}}
+- {{ . }}
+{{end}}
+Update the workspace to use the active version of its template.
+$$,
+		'Workspace Events',
+		'[
+		{
+			"label": "View workspace",
+			"url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.name}}"
+		}
+	]'::jsonb);
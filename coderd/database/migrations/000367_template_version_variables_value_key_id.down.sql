@@ -0,0 +1 @@
+ALTER TABLE template_version_variables DROP COLUMN value_key_id;
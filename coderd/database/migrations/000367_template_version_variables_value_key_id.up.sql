@@ -0,0 +1,4 @@
+ALTER TABLE template_version_variables
+	ADD COLUMN value_key_id text REFERENCES dbcrypt_keys(active_key_digest);
+
+COMMENT ON COLUMN template_version_variables.value_key_id IS 'The ID of the key used to encrypt the variable value. If this is NULL, the value is not encrypted';
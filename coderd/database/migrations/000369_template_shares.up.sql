@@ -0,0 +1,63 @@
+CREATE TABLE template_shares (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, organization_id)
+);
+
+CREATE INDEX template_shares_organization_id_idx ON template_shares (organization_id);
+
+COMMENT ON TABLE template_shares IS 'Organizations that templates are shared into. Members of these organizations can read the template, but not modify it.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+	SELECT templates.id,
+		templates.created_at,
+		templates.updated_at,
+		templates.organization_id,
+		templates.deleted,
+		templates.name,
+		templates.provisioner,
+		templates.active_version_id,
+		templates.description,
+		templates.default_ttl,
+		templates.created_by,
+		templates.icon,
+		templates.user_acl,
+		templates.group_acl,
+		templates.display_name,
+		templates.allow_user_cancel_workspace_jobs,
+		templates.allow_user_autostart,
+		templates.allow_user_autostop,
+		templates.failure_ttl,
+		templates.time_til_dormant,
+		templates.time_til_dormant_autodelete,
+		templates.autostop_requirement_days_of_week,
+		templates.autostop_requirement_weeks,
+		templates.autostart_block_days_of_week,
+		templates.require_active_version,
+		templates.deprecated,
+		templates.activity_bump,
+		templates.max_port_sharing_level,
+		templates.use_classic_parameter_flow,
+		templates.max_concurrent_jobs_per_user,
+		templates.resource_ceilings,
+		templates.required_provisioner_tags,
+		COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+		COALESCE(visible_users.username, ''::text) AS created_by_username,
+		COALESCE(visible_users.name, ''::text) AS created_by_name,
+		COALESCE(organizations.name, ''::text) AS organization_name,
+		COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+		COALESCE(organizations.icon, ''::text) AS organization_icon,
+		COALESCE((
+			SELECT array_agg(template_shares.organization_id ORDER BY template_shares.organization_id)
+			FROM template_shares
+			WHERE template_shares.template_id = templates.id
+		), '{}'::uuid[]) AS shared_organization_ids
+	FROM ((templates
+	  LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	  LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
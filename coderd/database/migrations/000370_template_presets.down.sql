@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS template_preset_group_defaults;
+DROP TABLE IF EXISTS template_presets;
@@ -0,0 +1,25 @@
+CREATE TABLE template_presets (
+	id uuid NOT NULL PRIMARY KEY,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	description text NOT NULL DEFAULT '',
+	parameters jsonb NOT NULL DEFAULT '{}'::jsonb,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	UNIQUE (template_id, name)
+);
+
+COMMENT ON TABLE template_presets IS 'Named bundles of parameter values that are managed at the template level, independently of template versions.';
+
+COMMENT ON COLUMN template_presets.parameters IS 'Parameter values by parameter name. Parameters that a template version does not define are ignored.';
+
+CREATE TABLE template_preset_group_defaults (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	group_id uuid NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+	template_preset_id uuid NOT NULL REFERENCES template_presets (id) ON DELETE CASCADE,
+	PRIMARY KEY (template_id, group_id)
+);
+
+CREATE INDEX template_preset_group_defaults_template_preset_id_idx ON template_preset_group_defaults (template_preset_id);
+
+COMMENT ON TABLE template_preset_group_defaults IS 'The template preset that members of a group get by default when they create a workspace without choosing a preset.';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS workspace_migrations;
+DROP TYPE IF EXISTS workspace_migration_status;
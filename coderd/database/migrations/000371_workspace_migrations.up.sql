@@ -0,0 +1,40 @@
+CREATE TYPE workspace_migration_status AS ENUM (
+	'pending',
+	'stopping',
+	'starting',
+	'succeeded',
+	'failed'
+);
+
+CREATE TABLE workspace_migrations (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	initiator_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	status workspace_migration_status NOT NULL DEFAULT 'pending',
+	destination_url text NOT NULL,
+	destination_organization_id uuid NOT NULL,
+	destination_owner text NOT NULL,
+	destination_template_id uuid,
+	destination_name text NOT NULL DEFAULT '',
+	destination_session_token text NOT NULL,
+	destination_session_token_key_id text REFERENCES dbcrypt_keys (active_key_digest),
+	source_build_id uuid,
+	destination_workspace_id uuid,
+	destination_build_id uuid,
+	error text NOT NULL DEFAULT '',
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	completed_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_migrations IS 'Transfers of workspaces, including their provisioner state, to another deployment. The workspace is stopped, imported on the destination with its state and started there, then deleted without destroying its resources.';
+COMMENT ON COLUMN workspace_migrations.destination_organization_id IS 'The organization on the destination deployment. It does not reference an organization of this deployment.';
+COMMENT ON COLUMN workspace_migrations.destination_template_id IS 'The template on the destination deployment, or NULL to look it up by the name of the template of the workspace.';
+COMMENT ON COLUMN workspace_migrations.destination_session_token IS 'The session token used to authenticate with the destination deployment. It is cleared once the migration completes.';
+COMMENT ON COLUMN workspace_migrations.destination_session_token_key_id IS 'The ID of the key used to encrypt the destination session token. If this is NULL, the token is not encrypted';
+COMMENT ON COLUMN workspace_migrations.source_build_id IS 'The build that stopped the workspace on this deployment.';
+COMMENT ON COLUMN workspace_migrations.destination_build_id IS 'The build that starts the workspace on the destination deployment.';
+
+CREATE INDEX workspace_migrations_workspace_id_created_at_idx ON workspace_migrations USING btree (workspace_id, created_at);
+
+CREATE UNIQUE INDEX workspace_migrations_workspace_id_active_idx ON workspace_migrations USING btree (workspace_id) WHERE (completed_at IS NULL);
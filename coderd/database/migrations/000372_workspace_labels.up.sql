@@ -0,0 +1,12 @@
+CREATE TABLE workspace_labels (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	key text NOT NULL,
+	value text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (workspace_id, key)
+);
+
+COMMENT ON TABLE workspace_labels IS 'Arbitrary key/value labels used to group and filter workspaces.';
+COMMENT ON COLUMN workspace_labels.key IS 'Keys are lowercase, since workspace search queries are case insensitive.';
+
+CREATE INDEX workspace_labels_key_value_idx ON workspace_labels USING btree (key, lower(value));
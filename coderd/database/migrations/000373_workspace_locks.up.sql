@@ -0,0 +1,16 @@
+CREATE TABLE workspace_locks (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	reason text NOT NULL,
+	locked_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	locked_at timestamp with time zone NOT NULL,
+	unlocked_by uuid REFERENCES users (id) ON DELETE CASCADE,
+	unlocked_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_locks IS 'Administrative locks that prevent any new builds of a workspace, including automatic ones, until they are lifted. Lifted locks are kept as a record of who locked the workspace and why.';
+COMMENT ON COLUMN workspace_locks.unlocked_at IS 'The time the lock was lifted, or NULL if the workspace is still locked.';
+
+CREATE INDEX workspace_locks_workspace_id_locked_at_idx ON workspace_locks USING btree (workspace_id, locked_at);
+
+CREATE UNIQUE INDEX workspace_locks_workspace_id_active_idx ON workspace_locks USING btree (workspace_id) WHERE (unlocked_at IS NULL);
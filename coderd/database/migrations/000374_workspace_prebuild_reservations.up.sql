@@ -0,0 +1,13 @@
+CREATE TABLE workspace_prebuild_reservations (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+	user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+	group_id uuid REFERENCES groups (id) ON DELETE CASCADE,
+	reserved_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	CONSTRAINT workspace_prebuild_reservations_reserved_for_check CHECK (num_nonnulls(user_id, group_id) = 1)
+);
+
+COMMENT ON TABLE workspace_prebuild_reservations IS 'Prebuilt workspaces held for a specific user or group. Until the reservation expires, a reserved prebuilt workspace can only be claimed by the user or a member of the group.';
+COMMENT ON COLUMN workspace_prebuild_reservations.user_id IS 'The user the prebuilt workspace is reserved for. Exactly one of user_id and group_id is set.';
+COMMENT ON COLUMN workspace_prebuild_reservations.group_id IS 'The group whose members the prebuilt workspace is reserved for. Exactly one of user_id and group_id is set.';
@@ -0,0 +1,22 @@
+CREATE TABLE workspace_daily_costs (
+	start_time timestamptz NOT NULL,
+	end_time timestamptz NOT NULL,
+	workspace_id uuid NOT NULL,
+	organization_id uuid NOT NULL,
+	owner_id uuid NOT NULL,
+	template_id uuid NOT NULL,
+	cost double precision NOT NULL,
+
+	PRIMARY KEY (start_time, workspace_id)
+);
+
+COMMENT ON TABLE workspace_daily_costs IS 'Records the cost accrued by each workspace in daily buckets, based on the daily cost of its resources.';
+COMMENT ON COLUMN workspace_daily_costs.start_time IS 'Start time of the period the cost accrued in.';
+COMMENT ON COLUMN workspace_daily_costs.end_time IS 'End time of the period the cost accrued in.';
+COMMENT ON COLUMN workspace_daily_costs.workspace_id IS 'ID of the workspace that accrued the cost.';
+COMMENT ON COLUMN workspace_daily_costs.organization_id IS 'ID of the organization of the workspace.';
+COMMENT ON COLUMN workspace_daily_costs.owner_id IS 'ID of the owner of the workspace when the cost was rolled up.';
+COMMENT ON COLUMN workspace_daily_costs.template_id IS 'ID of the template of the workspace.';
+COMMENT ON COLUMN workspace_daily_costs.cost IS 'Cost accrued in the period, prorated by the time each build of the workspace was current.';
+
+CREATE INDEX workspace_daily_costs_start_time_idx ON workspace_daily_costs (start_time);
@@ -0,0 +1,3 @@
+ALTER TABLE groups ADD COLUMN monthly_budget integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN groups.monthly_budget IS 'Credits the members of the group may spend on workspaces each calendar month (UTC), accrued from the daily cost of their workspaces by the hour. 0 means the group adds no monthly budget.';
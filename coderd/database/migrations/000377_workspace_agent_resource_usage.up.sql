@@ -0,0 +1,29 @@
+CREATE TABLE workspace_agent_resource_usage (
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	bucket_start timestamptz NOT NULL,
+	samples integer NOT NULL,
+	cpu_used_avg double precision NOT NULL,
+	cpu_used_max double precision NOT NULL,
+	cpu_total double precision NOT NULL,
+	memory_used_avg bigint NOT NULL,
+	memory_used_max bigint NOT NULL,
+	memory_total bigint NOT NULL,
+	disk_used bigint NOT NULL,
+	disk_total bigint NOT NULL,
+
+	PRIMARY KEY (agent_id, bucket_start)
+);
+
+COMMENT ON TABLE workspace_agent_resource_usage IS 'Host resource utilization reported by workspace agents, downsampled into fixed buckets.';
+COMMENT ON COLUMN workspace_agent_resource_usage.bucket_start IS 'Start time of the bucket the samples were collected in.';
+COMMENT ON COLUMN workspace_agent_resource_usage.samples IS 'Number of samples aggregated into the bucket.';
+COMMENT ON COLUMN workspace_agent_resource_usage.cpu_used_avg IS 'Average number of CPU cores in use.';
+COMMENT ON COLUMN workspace_agent_resource_usage.cpu_used_max IS 'Maximum number of CPU cores in use.';
+COMMENT ON COLUMN workspace_agent_resource_usage.cpu_total IS 'Number of CPU cores available to the agent.';
+COMMENT ON COLUMN workspace_agent_resource_usage.memory_used_avg IS 'Average memory in use, in bytes.';
+COMMENT ON COLUMN workspace_agent_resource_usage.memory_used_max IS 'Maximum memory in use, in bytes.';
+COMMENT ON COLUMN workspace_agent_resource_usage.memory_total IS 'Memory available to the agent, in bytes.';
+COMMENT ON COLUMN workspace_agent_resource_usage.disk_used IS 'Disk space in use at the end of the bucket, in bytes.';
+COMMENT ON COLUMN workspace_agent_resource_usage.disk_total IS 'Disk space available to the agent, in bytes.';
+
+CREATE INDEX workspace_agent_resource_usage_bucket_start_idx ON workspace_agent_resource_usage (bucket_start);
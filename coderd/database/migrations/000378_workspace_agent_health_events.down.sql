@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS workspace_agent_health_events;
+DROP TYPE IF EXISTS workspace_agent_health_event_kind;
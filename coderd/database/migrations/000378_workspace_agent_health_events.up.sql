@@ -0,0 +1,24 @@
+CREATE TYPE workspace_agent_health_event_kind AS ENUM (
+	'near_out_of_memory',
+	'low_disk_space'
+);
+
+CREATE TABLE workspace_agent_health_events (
+	id uuid NOT NULL PRIMARY KEY,
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	kind workspace_agent_health_event_kind NOT NULL,
+	volume text NOT NULL DEFAULT '',
+	used bigint NOT NULL,
+	total bigint NOT NULL,
+	created_at timestamptz NOT NULL,
+	resolved_at timestamptz
+);
+
+COMMENT ON TABLE workspace_agent_health_events IS 'Conditions detected by workspace agents that are likely to cause failures, such as their host running out of memory.';
+COMMENT ON COLUMN workspace_agent_health_events.volume IS 'Path of the volume that is low on space. Empty for memory events.';
+COMMENT ON COLUMN workspace_agent_health_events.used IS 'Amount of the resource in use when the condition was detected, in bytes.';
+COMMENT ON COLUMN workspace_agent_health_events.total IS 'Amount of the resource available when the condition was detected, in bytes.';
+COMMENT ON COLUMN workspace_agent_health_events.resolved_at IS 'Time the agent reported the condition no longer applies. NULL while it is ongoing.';
+
+-- An agent can only have one ongoing event of a kind per volume.
+CREATE UNIQUE INDEX workspace_agent_health_events_unresolved_idx ON workspace_agent_health_events (agent_id, kind, volume) WHERE resolved_at IS NULL;
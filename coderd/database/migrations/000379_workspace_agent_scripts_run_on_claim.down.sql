@@ -0,0 +1 @@
+ALTER TABLE workspace_agent_scripts DROP COLUMN run_on_claim;
@@ -0,0 +1,3 @@
+ALTER TABLE workspace_agent_scripts ADD COLUMN run_on_claim boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspace_agent_scripts.run_on_claim IS 'Whether the script runs when a prebuilt workspace is claimed by a user.';
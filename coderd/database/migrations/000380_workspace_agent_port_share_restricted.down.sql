@@ -0,0 +1,9 @@
+-- It's not possible to drop enum values from enum types, so the up migration
+-- has "IF NOT EXISTS". Remove any usage of the value instead.
+DELETE FROM workspace_agent_port_share WHERE share_level = 'restricted';
+UPDATE templates SET max_port_sharing_level = 'owner' WHERE max_port_sharing_level = 'restricted';
+
+ALTER TABLE workspace_agent_port_share
+	DROP COLUMN shared_with_user_ids,
+	DROP COLUMN shared_with_group_ids,
+	DROP COLUMN expires_at;
@@ -0,0 +1,10 @@
+ALTER TYPE app_sharing_level ADD VALUE IF NOT EXISTS 'restricted';
+
+ALTER TABLE workspace_agent_port_share
+	ADD COLUMN shared_with_user_ids uuid[] NOT NULL DEFAULT '{}',
+	ADD COLUMN shared_with_group_ids uuid[] NOT NULL DEFAULT '{}',
+	ADD COLUMN expires_at timestamp with time zone;
+
+COMMENT ON COLUMN workspace_agent_port_share.shared_with_user_ids IS 'Users that can access the port when the share level is restricted.';
+COMMENT ON COLUMN workspace_agent_port_share.shared_with_group_ids IS 'Groups whose members can access the port when the share level is restricted.';
+COMMENT ON COLUMN workspace_agent_port_share.expires_at IS 'When set, the port share is no longer honored after this time.';
@@ -0,0 +1,40 @@
+CREATE TYPE workspace_session_recording_type AS ENUM (
+	'ssh',
+	'reconnecting_pty'
+);
+
+CREATE TABLE workspace_session_recordings (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	type workspace_session_recording_type NOT NULL,
+	width integer NOT NULL,
+	height integer NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone,
+	size_bytes bigint NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Terminal sessions recorded by workspace agents when session recording is enabled.';
+COMMENT ON COLUMN workspace_session_recordings.width IS 'Width of the terminal in columns when the session started.';
+COMMENT ON COLUMN workspace_session_recordings.height IS 'Height of the terminal in rows when the session started.';
+COMMENT ON COLUMN workspace_session_recordings.ended_at IS 'Time the session ended. NULL while it is ongoing, or if the agent never reported the end.';
+COMMENT ON COLUMN workspace_session_recordings.size_bytes IS 'Total size of the recorded output, before encryption.';
+
+CREATE INDEX workspace_session_recordings_workspace_id_started_at_idx ON workspace_session_recordings USING btree (workspace_id, started_at DESC);
+
+CREATE INDEX workspace_session_recordings_started_at_idx ON workspace_session_recordings USING btree (started_at);
+
+CREATE TABLE workspace_session_recording_chunks (
+	id bigserial NOT NULL PRIMARY KEY,
+	recording_id uuid NOT NULL REFERENCES workspace_session_recordings (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	data bytea NOT NULL,
+	data_key_id text REFERENCES dbcrypt_keys (active_key_digest)
+);
+
+COMMENT ON TABLE workspace_session_recording_chunks IS 'Output of recorded terminal sessions, in the order it was written.';
+COMMENT ON COLUMN workspace_session_recording_chunks.created_at IS 'Time the output was written by the session, as reported by the agent.';
+COMMENT ON COLUMN workspace_session_recording_chunks.data_key_id IS 'The ID of the key used to encrypt the data. If this is NULL, the data is not encrypted';
+
+CREATE INDEX workspace_session_recording_chunks_recording_id_idx ON workspace_session_recording_chunks USING btree (recording_id, id);
@@ -0,0 +1,24 @@
+CREATE TABLE workspace_agent_connection_quality (
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	ip text NOT NULL,
+	p2p boolean NOT NULL,
+	derp_region_code text NOT NULL DEFAULT '',
+	latency_ms double precision,
+	pings_sent bigint NOT NULL,
+	pings_lost bigint NOT NULL,
+	last_handshake_at timestamptz,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	PRIMARY KEY (agent_id, ip)
+);
+
+COMMENT ON TABLE workspace_agent_connection_quality IS 'Network quality of each peer connected to a workspace agent, as last reported in the agent stats.';
+COMMENT ON COLUMN workspace_agent_connection_quality.ip IS 'Tailnet IP address of the peer. Every client connection uses its own address.';
+COMMENT ON COLUMN workspace_agent_connection_quality.p2p IS 'Whether the connection is direct. If false, it is relayed through DERP.';
+COMMENT ON COLUMN workspace_agent_connection_quality.derp_region_code IS 'DERP region the connection is relayed through. Empty for direct connections.';
+COMMENT ON COLUMN workspace_agent_connection_quality.latency_ms IS 'Round trip time of the most recent successful ping. NULL if no ping was answered.';
+COMMENT ON COLUMN workspace_agent_connection_quality.pings_sent IS 'Number of pings the agent sent to the peer since it connected.';
+COMMENT ON COLUMN workspace_agent_connection_quality.pings_lost IS 'Number of pings sent to the peer that went unanswered.';
+COMMENT ON COLUMN workspace_agent_connection_quality.last_handshake_at IS 'Time of the last WireGuard handshake with the peer.';
+
+CREATE INDEX workspace_agent_connection_quality_updated_at_idx ON workspace_agent_connection_quality (updated_at);
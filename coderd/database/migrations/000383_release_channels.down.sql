@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_release_channels;
+DROP TABLE IF EXISTS template_release_channels;
+DROP TYPE IF EXISTS release_channel;
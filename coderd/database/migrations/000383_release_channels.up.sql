@@ -0,0 +1,20 @@
+CREATE TYPE release_channel AS ENUM ('stable', 'beta');
+
+CREATE TABLE template_release_channels (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	channel release_channel NOT NULL,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	updated_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, channel)
+);
+
+COMMENT ON TABLE template_release_channels IS 'Template versions published to named release channels. Builds of workspaces tracking a channel that target the active version use the version of the channel instead.';
+
+CREATE TABLE workspace_release_channels (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+	channel release_channel NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_release_channels IS 'The release channel a workspace tracks instead of the active version of its template.';
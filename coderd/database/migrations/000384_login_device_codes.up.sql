@@ -0,0 +1,16 @@
+CREATE TABLE login_device_codes (
+	id uuid NOT NULL PRIMARY KEY,
+	device_code_hash bytea NOT NULL UNIQUE,
+	user_code text NOT NULL UNIQUE,
+	user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE login_device_codes IS 'Pending device authorizations of the CLI. A device polls with its device code until a user signed in on another device approves the user code, and then receives a session token for that user.';
+
+COMMENT ON COLUMN login_device_codes.device_code_hash IS 'SHA-256 hash of the secret device code the device polls with.';
+
+COMMENT ON COLUMN login_device_codes.user_code IS 'The short code the user enters to approve the device, without separators.';
+
+COMMENT ON COLUMN login_device_codes.user_id IS 'The user that approved the device, or null if it has not been approved yet.';
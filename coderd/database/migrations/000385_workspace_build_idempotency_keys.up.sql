@@ -0,0 +1,11 @@
+CREATE TABLE workspace_build_idempotency_keys (
+	workspace_build_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	idempotency_key text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	UNIQUE (user_id, idempotency_key)
+);
+
+COMMENT ON TABLE workspace_build_idempotency_keys IS 'The Idempotency-Key headers of the requests that created workspace builds. A retried request with the same key returns the build it created instead of creating another one.';
+
+COMMENT ON COLUMN workspace_build_idempotency_keys.user_id IS 'The user that made the request. Keys are unique per user.';
@@ -0,0 +1,3 @@
+-- It's not possible to drop enum values from enum types, so the up migration
+-- has "IF NOT EXISTS". Remove any keys using the values instead.
+DELETE FROM api_keys WHERE scope IN ('workspace:read', 'build:create', 'template:admin');
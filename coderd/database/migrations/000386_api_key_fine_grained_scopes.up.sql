@@ -0,0 +1,3 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'workspace:read';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'build:create';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'template:admin';
@@ -0,0 +1,13 @@
+CREATE TABLE service_accounts (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	description text NOT NULL DEFAULT '',
+	created_by uuid REFERENCES users (id) ON DELETE SET NULL,
+	created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX idx_service_accounts_organization_id ON service_accounts USING btree (organization_id);
+
+COMMENT ON TABLE service_accounts IS 'Users that are used for automation rather than by people. They belong to a single organization, have no way to log in and authenticate with tokens that organization admins manage.';
+
+COMMENT ON COLUMN service_accounts.created_by IS 'The user that created the service account.';
@@ -0,0 +1,7 @@
+DELETE FROM notification_templates WHERE id = '8c2f1a7e-3b4d-4e6f-9a1b-2c3d4e5f6a7b';
+
+DROP TABLE IF EXISTS access_requests;
+
+DROP TYPE IF EXISTS access_request_resource_type;
+
+DROP TYPE IF EXISTS access_request_status;
@@ -0,0 +1,60 @@
+CREATE TYPE access_request_status AS ENUM (
+	'pending',
+	'approved',
+	'denied',
+	'expired'
+);
+
+CREATE TYPE access_request_resource_type AS ENUM (
+	'workspace',
+	'template'
+);
+
+CREATE TABLE access_requests (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	requester_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	resource_type access_request_resource_type NOT NULL,
+	resource_id uuid NOT NULL,
+	role text NOT NULL,
+	reason text NOT NULL,
+	duration_ms bigint NOT NULL,
+	status access_request_status NOT NULL DEFAULT 'pending',
+	previous_role text NOT NULL DEFAULT '',
+	reviewed_by uuid REFERENCES users (id) ON DELETE SET NULL,
+	reviewed_at timestamp with time zone,
+	expires_at timestamp with time zone,
+	created_at timestamp with time zone NOT NULL,
+	CONSTRAINT access_requests_duration_ms_check CHECK (duration_ms > 0)
+);
+
+COMMENT ON TABLE access_requests IS 'Requests for time-boxed access to a workspace or template. Approved requests grant the role through the ACL of the resource until they expire.';
+COMMENT ON COLUMN access_requests.role IS 'The requested role, a workspace role for workspaces and a template role for templates.';
+COMMENT ON COLUMN access_requests.previous_role IS 'The role the requester had on the resource when the request was approved. It is restored when the request expires.';
+COMMENT ON COLUMN access_requests.expires_at IS 'Set when the request is approved. The granted role is revoked after this time.';
+
+-- A user can only have one open request per resource, so expiring a grant
+-- never restores a role over another active grant.
+CREATE UNIQUE INDEX access_requests_open_idx ON access_requests USING btree (requester_id, resource_id) WHERE status IN ('pending', 'approved');
+
+CREATE INDEX access_requests_expires_at_idx ON access_requests USING btree (expires_at) WHERE status = 'approved';
+
+INSERT INTO notification_templates
+(id, name, title_template, body_template, "group", actions)
+VALUES ('8c2f1a7e-3b4d-4e6f-9a1b-2c3d4e5f6a7b',
+		'Access Request Created',
+		E'Access request for {{.Labels.resource_type}} "{{.Labels.resource}}"',
+		$$
+**{{.Labels.requester}}** requested the **{{.Labels.role}}** role on the {{.Labels.resource_type}} **{{.Labels.resource}}** for {{.Labels.duration}}.
+
+Reason: {{.Labels.reason}}
+
+The access is granted once the request is approved, and revoked automatically when it expires.
+$$,
+		'User Events',
+		'[
+		{
+			"label": "View resource",
+			"url": "{{base_url}}/{{.Labels.resource_path}}"
+		}
+	]'::jsonb);
@@ -0,0 +1,2 @@
+-- This has to be outside a transaction
+ALTER TYPE notification_message_status ADD VALUE IF NOT EXISTS 'batched';
@@ -0,0 +1,13 @@
+DELETE FROM notification_templates WHERE id = '8b42563f-a4ce-441e-934c-cd165daed78e';
+
+-- Deliver the notifications which are still waiting for a digest.
+UPDATE notification_messages
+SET status = 'pending'::notification_message_status
+WHERE status = 'batched'::notification_message_status;
+
+DROP TRIGGER IF EXISTS batch_notification_if_digest ON notification_messages;
+
+DROP FUNCTION IF EXISTS batch_notification_if_digest;
+
+ALTER TABLE notification_preferences
+	DROP COLUMN IF EXISTS digest;
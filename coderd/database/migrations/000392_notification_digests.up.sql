@@ -0,0 +1,52 @@
+ALTER TABLE notification_preferences
+	ADD COLUMN digest boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN notification_preferences.digest IS 'Whether the notifications of this template are batched into a periodic digest instead of being delivered immediately';
+
+CREATE OR REPLACE FUNCTION batch_notification_if_digest()
+	RETURNS TRIGGER AS
+$$
+BEGIN
+	-- Hold the message back for the digest if the user opted into digests
+	-- for this template. Inbox notifications are still delivered right away.
+	IF NEW.method != 'inbox'::notification_method AND EXISTS (
+		SELECT 1 FROM notification_preferences
+		WHERE notification_preferences.notification_template_id = NEW.notification_template_id
+			AND notification_preferences.user_id = NEW.user_id
+			AND notification_preferences.digest = TRUE
+	) THEN
+		NEW.status := 'batched'::notification_message_status;
+	END IF;
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER batch_notification_if_digest
+	BEFORE INSERT
+	ON notification_messages
+	FOR EACH ROW
+EXECUTE FUNCTION batch_notification_if_digest();
+
+INSERT INTO notification_templates
+(id, name, title_template, body_template, "group", actions)
+VALUES ('8b42563f-a4ce-441e-934c-cd165daed78e',
+		'Notification Digest',
+		E'Your {{.Labels.frequency}} notification digest',
+		$$
+You received {{.Labels.count}} notifications since your last {{.Labels.frequency}} digest.
+{{range $group := .Data.notifications}}
+**{{$group.name}}** ({{$group.count}})
+{{range $title := $group.titles}}
+- {{$title}}{{end}}{{if gt $group.more 0.0}}
+- and {{$group.more}} more{{end}}
+{{end}}
+You can choose which notifications are delivered in digests in your notification preferences.
+$$,
+		'User Events',
+		'[
+		{
+			"label": "Manage preferences",
+			"url": "{{base_url}}/settings/notifications"
+		}
+	]'::jsonb);
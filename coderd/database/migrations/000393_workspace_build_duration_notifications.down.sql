@@ -0,0 +1,3 @@
+DELETE FROM notification_templates WHERE id = '6b5c6a6e-4f9d-4c8e-9a43-0b9f3f6ed2a1';
+
+DROP TABLE IF EXISTS workspace_build_duration_notifications;
@@ -0,0 +1,24 @@
+CREATE TABLE workspace_build_duration_notifications (
+	workspace_build_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	notified_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_duration_notifications IS 'The workspace builds whose owner was notified that they run longer than their build duration notification threshold. Each build is only notified once.';
+
+INSERT INTO notification_templates
+(id, name, title_template, body_template, "group", actions)
+VALUES ('6b5c6a6e-4f9d-4c8e-9a43-0b9f3f6ed2a1',
+		'Workspace Build Running Long',
+		E'Workspace "{{.Labels.name}}" build is taking longer than expected',
+		$$
+The {{.Labels.transition}} build of your workspace **{{.Labels.name}}** has been running for more than **{{.Labels.threshold}}**.
+
+The build may be stuck waiting on a provisioner, or on a resource that is slow to provision.
+$$,
+		'Workspace Events',
+		'[
+		{
+			"label": "View build",
+			"url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.name}}/builds/{{.Labels.build_number}}"
+		}
+	]'::jsonb);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS user_activity_exports;
+
+DROP TYPE IF EXISTS user_activity_export_status;
+
+DROP TYPE IF EXISTS user_activity_export_format;
@@ -0,0 +1,31 @@
+CREATE TYPE user_activity_export_format AS ENUM (
+	'csv',
+	'parquet'
+);
+
+CREATE TYPE user_activity_export_status AS ENUM (
+	'pending',
+	'completed',
+	'failed'
+);
+
+CREATE TABLE user_activity_exports (
+	id uuid NOT NULL PRIMARY KEY,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	start_time timestamp with time zone NOT NULL,
+	end_time timestamp with time zone NOT NULL,
+	format user_activity_export_format NOT NULL,
+	status user_activity_export_status NOT NULL DEFAULT 'pending',
+	file_id uuid REFERENCES files (id) ON DELETE SET NULL,
+	error text NOT NULL DEFAULT '',
+	completed_at timestamp with time zone,
+	CONSTRAINT user_activity_exports_time_range_check CHECK (end_time > start_time)
+);
+
+COMMENT ON TABLE user_activity_exports IS 'Exports of the daily activity of each user, generated in the background for ingestion by external tools.';
+COMMENT ON COLUMN user_activity_exports.file_id IS 'The file holding the export once it has completed.';
+COMMENT ON COLUMN user_activity_exports.error IS 'Why the export failed, if it did.';
+
+CREATE INDEX user_activity_exports_pending_idx ON user_activity_exports USING btree (created_at) WHERE status = 'pending';
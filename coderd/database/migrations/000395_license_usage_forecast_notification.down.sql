@@ -0,0 +1 @@
+DELETE FROM notification_templates WHERE id = '57fd22bf-e7b4-4123-96cf-86d659baf5a1';
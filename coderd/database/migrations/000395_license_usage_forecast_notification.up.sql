@@ -0,0 +1,19 @@
+INSERT INTO notification_templates
+(id, name, title_template, body_template, "group", actions)
+VALUES ('57fd22bf-e7b4-4123-96cf-86d659baf5a1',
+		'License User Limit Approaching',
+		E'Active users are projected to reach the license user limit',
+		$$
+This deployment has **{{.Labels.active_users}}** of **{{.Labels.user_limit}}** licensed active users.
+
+At the current growth of {{.Labels.daily_growth}} active users per day, the user limit will be reached in **{{.Labels.days_until_limit}} days**, around {{.Labels.projected_date}}.
+
+To stay within your license, suspend users who no longer need access or contact sales to increase the user limit.
+$$,
+		'User Events',
+		'[
+		{
+			"label": "View licenses",
+			"url": "{{base_url}}/deployment/licenses"
+		}
+	]'::jsonb);
@@ -0,0 +1,9 @@
+CREATE TABLE workspace_build_archives (
+	workspace_build_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_builds(id) ON DELETE CASCADE,
+	file_id uuid NOT NULL REFERENCES files(id),
+	state_size integer NOT NULL,
+	resource_metadata_count integer NOT NULL,
+	archived_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_archives IS 'Workspace builds whose provisioner state and resource metadata were moved into a compressed file. The build, job and resource rows are kept as summaries.';
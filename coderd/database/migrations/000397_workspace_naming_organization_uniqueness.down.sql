@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS workspace_name_organization_unique_trigger ON workspaces;
+DROP FUNCTION IF EXISTS check_workspace_name_organization_unique();
+
+COMMENT ON COLUMN workspace_naming_policies.prefix IS 'Go template rendered with the owner, organization and template names. The workspace name must start with the result.';
+COMMENT ON COLUMN workspace_naming_policies.suffix IS 'Go template rendered with the owner, organization and template names. The workspace name must end with the result.';
+
+ALTER TABLE organizations DROP COLUMN IF EXISTS cost_center;
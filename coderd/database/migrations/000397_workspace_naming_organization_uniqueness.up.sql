@@ -0,0 +1,56 @@
+ALTER TABLE organizations ADD COLUMN cost_center text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN organizations.cost_center IS 'Cost center the organization is billed to. Available to workspace naming policies as {{.CostCenter}}.';
+
+COMMENT ON COLUMN workspace_naming_policies.prefix IS 'Go template rendered with the owner, organization and template names and the organization cost center. The workspace name must start with the result.';
+COMMENT ON COLUMN workspace_naming_policies.suffix IS 'Go template rendered with the owner, organization and template names and the organization cost center. The workspace name must end with the result.';
+
+-- Workspace names that must be unique in the organization can't be enforced
+-- with a unique index, since the scope depends on the naming policy of the
+-- template or organization.
+CREATE FUNCTION check_workspace_name_organization_unique() RETURNS trigger
+	LANGUAGE plpgsql
+	AS $$
+DECLARE
+	scope workspace_name_uniqueness_scope;
+BEGIN
+	IF NEW.deleted THEN
+		RETURN NEW;
+	END IF;
+
+	-- A template policy replaces the organization policy when it restricts
+	-- anything.
+	SELECT uniqueness_scope INTO scope
+	FROM workspace_naming_policies
+	WHERE template_id = NEW.template_id
+		AND (regex != '' OR prefix != '' OR suffix != '' OR uniqueness_scope = 'organization');
+	IF NOT FOUND THEN
+		SELECT uniqueness_scope INTO scope
+		FROM workspace_naming_policies
+		WHERE organization_id = NEW.organization_id AND template_id IS NULL;
+	END IF;
+	IF scope IS DISTINCT FROM 'organization' THEN
+		RETURN NEW;
+	END IF;
+
+	-- Serialize concurrent inserts and renames to the same name, so both
+	-- can't pass the check before either commits.
+	PERFORM pg_advisory_xact_lock(hashtextextended(NEW.organization_id::text || '/' || lower(NEW.name), 0));
+	IF EXISTS (
+		SELECT 1 FROM workspaces
+		WHERE organization_id = NEW.organization_id
+			AND lower(name) = lower(NEW.name)
+			AND id != NEW.id
+			AND deleted = false
+	) THEN
+		RAISE EXCEPTION 'workspace "%" already exists in the organization', NEW.name
+			USING ERRCODE = 'unique_violation', CONSTRAINT = 'workspaces_organization_name_unique', TABLE = 'workspaces';
+	END IF;
+	RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER workspace_name_organization_unique_trigger
+	BEFORE INSERT OR UPDATE OF name, deleted ON workspaces
+	FOR EACH ROW
+	EXECUTE FUNCTION check_workspace_name_organization_unique();
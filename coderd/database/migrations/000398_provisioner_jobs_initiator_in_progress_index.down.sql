@@ -0,0 +1 @@
+DROP INDEX IF EXISTS provisioner_jobs_initiator_id_in_progress_idx;
@@ -0,0 +1,5 @@
+-- migrate:no-transaction
+
+-- Speeds up counting the pending and running jobs of a user, which is done
+-- for every build that is subject to a concurrent job limit.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS provisioner_jobs_initiator_id_in_progress_idx ON provisioner_jobs USING btree (initiator_id) WHERE (completed_at IS NULL);
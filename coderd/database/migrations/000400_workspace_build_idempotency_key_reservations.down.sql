@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS workspace_build_idempotency_keys_created_at_idx;
+
+DELETE FROM workspace_build_idempotency_keys WHERE workspace_build_id IS NULL;
+
+ALTER TABLE workspace_build_idempotency_keys
+	DROP COLUMN request_hash,
+	DROP CONSTRAINT workspace_build_idempotency_keys_workspace_build_id_key,
+	ALTER COLUMN workspace_build_id SET NOT NULL,
+	ADD CONSTRAINT workspace_build_idempotency_keys_pkey PRIMARY KEY (workspace_build_id);
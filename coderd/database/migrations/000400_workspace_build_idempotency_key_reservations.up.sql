@@ -0,0 +1,17 @@
+-- Keys are reserved before the build of the request is created, so a
+-- concurrent request with the same key waits for the first one and replays
+-- it.
+ALTER TABLE workspace_build_idempotency_keys
+	DROP CONSTRAINT workspace_build_idempotency_keys_pkey,
+	ALTER COLUMN workspace_build_id DROP NOT NULL,
+	ADD CONSTRAINT workspace_build_idempotency_keys_workspace_build_id_key UNIQUE (workspace_build_id),
+	ADD COLUMN request_hash bytea NOT NULL DEFAULT ''::bytea;
+
+ALTER TABLE workspace_build_idempotency_keys
+	ALTER COLUMN request_hash DROP DEFAULT;
+
+CREATE INDEX workspace_build_idempotency_keys_created_at_idx ON workspace_build_idempotency_keys (created_at);
+
+COMMENT ON COLUMN workspace_build_idempotency_keys.workspace_build_id IS 'The build the request created. It is set in the transaction that reserved the key, so it is only null while the request is in progress.';
+
+COMMENT ON COLUMN workspace_build_idempotency_keys.request_hash IS 'The SHA-256 hash of the request. A request that reuses the key with a different hash is rejected.';
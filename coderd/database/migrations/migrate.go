@@ -7,6 +7,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"sort"
@@ -273,3 +274,74 @@ func Stepper(db *sql.DB) (next func() (version uint, more bool, err error), err
 		return v, true, nil
 	}, nil
 }
+
+// Migration is a single up migration.
+type Migration struct {
+	Version uint
+	Name    string
+	SQL     string
+}
+
+// Pending returns the up migrations that have not been applied to the
+// database yet, in the order they would run. Unlike the other functions of
+// this package, it does not write to the database. It also returns the
+// current migration version, which is 0 for an empty database, and
+// whether the database is dirty.
+func Pending(ctx context.Context, db *sql.DB) (pending []Migration, current uint, dirty bool, err error) {
+	row := db.QueryRowContext(ctx, "SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = '"+migrationsTableName+"';")
+	var exists int
+	err = row.Scan(&exists)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, 0, false, xerrors.Errorf("check version table: %w", err)
+	default:
+		var version int64
+		err = db.QueryRowContext(ctx, "SELECT version, dirty FROM "+migrationsTableName+" LIMIT 1;").Scan(&version, &dirty)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, false, xerrors.Errorf("get migration version: %w", err)
+		}
+		if version > 0 {
+			current = uint(version)
+		}
+	}
+
+	sourceDriver, err := iofs.New(migrations, ".")
+	if err != nil {
+		return nil, 0, false, xerrors.Errorf("create iofs: %w", err)
+	}
+	defer sourceDriver.Close()
+
+	version, err := sourceDriver.First()
+	for err == nil {
+		if version > current {
+			migration, readErr := readUp(sourceDriver, version)
+			if readErr != nil {
+				return nil, 0, false, readErr
+			}
+			pending = append(pending, migration)
+		}
+		version, err = sourceDriver.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, 0, false, xerrors.Errorf("list migrations: %w", err)
+	}
+	return pending, current, dirty, nil
+}
+
+func readUp(sourceDriver source.Driver, version uint) (Migration, error) {
+	r, name, err := sourceDriver.ReadUp(version)
+	if err != nil {
+		return Migration{}, xerrors.Errorf("read migration %d: %w", version, err)
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return Migration{}, xerrors.Errorf("read migration %d: %w", version, err)
+	}
+	return Migration{
+		Version: version,
+		Name:    name,
+		SQL:     string(content),
+	}, nil
+}
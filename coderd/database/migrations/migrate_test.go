@@ -9,6 +9,7 @@ import (
 	"slices"
 	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/golang-migrate/migrate/v4"
 	migratepostgres "github.com/golang-migrate/migrate/v4/database/postgres"
@@ -90,6 +91,125 @@ func TestMigrate(t *testing.T) {
 	})
 }
 
+func TestPending(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	db := testSQLDB(t)
+
+	// Nothing is applied to an empty database, and checking doesn't create
+	// the version table.
+	pending, current, dirty, err := migrations.Pending(ctx, db)
+	require.NoError(t, err)
+	require.Zero(t, current)
+	require.False(t, dirty)
+	require.NotEmpty(t, pending)
+	require.EqualValues(t, 1, pending[0].Version)
+	require.NotEmpty(t, pending[0].SQL)
+	all := len(pending)
+	var exists bool
+	err = db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'schema_migrations')").Scan(&exists)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	next, err := migrations.Stepper(db)
+	require.NoError(t, err)
+	version, more, err := next()
+	require.NoError(t, err)
+	pending, current, _, err = migrations.Pending(ctx, db)
+	require.NoError(t, err)
+	require.Equal(t, version, current)
+	require.Len(t, pending, all-1)
+
+	// Stepper must run to completion.
+	for more {
+		_, more, err = next()
+		require.NoError(t, err)
+	}
+	pending, _, _, err = migrations.Pending(ctx, db)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
+
+func TestOnlineMigration(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	db := testSQLDB(t)
+
+	migs := fstest.MapFS{
+		"000001_create.up.sql": &fstest.MapFile{Data: []byte(`
+CREATE TABLE t (id serial PRIMARY KEY, a int NOT NULL);
+INSERT INTO t (a) SELECT g % 10 FROM generate_series(1, 1000) AS g;
+`)},
+		"000001_create.down.sql": &fstest.MapFile{Data: []byte(`DROP TABLE t;`)},
+		"000002_backfill.up.sql": &fstest.MapFile{Data: []byte(`-- migrate:no-transaction
+ALTER TABLE t ADD COLUMN IF NOT EXISTS b int;
+
+-- migrate:batch
+UPDATE t SET b = a * 2 WHERE id IN (SELECT id FROM t WHERE b IS NULL LIMIT 100);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS t_b_idx ON t (b);
+`)},
+		"000002_backfill.down.sql": &fstest.MapFile{Data: []byte(`ALTER TABLE t DROP COLUMN b;`)},
+		// The index can't be created, since a is not unique.
+		"000003_unique.up.sql": &fstest.MapFile{Data: []byte(`-- migrate:no-transaction
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS t_a_idx ON t (a);
+`)},
+		"000003_unique.down.sql": &fstest.MapFile{Data: []byte(`DROP INDEX t_a_idx;`)},
+	}
+
+	err := migrations.UpWithFS(db, migs)
+	require.Error(t, err)
+
+	// The failed online migration didn't leave the database dirty.
+	var (
+		version int
+		dirty   bool
+	)
+	err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&version, &dirty)
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+	require.False(t, dirty)
+
+	var missing int
+	err = db.QueryRowContext(ctx, "SELECT count(*) FROM t WHERE b IS DISTINCT FROM a * 2").Scan(&missing)
+	require.NoError(t, err)
+	require.Zero(t, missing)
+	var valid bool
+	err = db.QueryRowContext(ctx, "SELECT indisvalid FROM pg_index WHERE indexrelid = 't_b_idx'::regclass").Scan(&valid)
+	require.NoError(t, err)
+	require.True(t, valid)
+	// The failed concurrent index creation left an invalid index behind.
+	err = db.QueryRowContext(ctx, "SELECT indisvalid FROM pg_index WHERE indexrelid = 't_a_idx'::regclass").Scan(&valid)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// Once the data is fixed, the migration is retried and replaces the
+	// invalid index.
+	_, err = db.ExecContext(ctx, "UPDATE t SET a = id")
+	require.NoError(t, err)
+	err = migrations.UpWithFS(db, migs)
+	require.NoError(t, err)
+	err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&version, &dirty)
+	require.NoError(t, err)
+	require.Equal(t, 3, version)
+	require.False(t, dirty)
+	err = db.QueryRowContext(ctx, "SELECT indisvalid FROM pg_index WHERE indexrelid = 't_a_idx'::regclass").Scan(&valid)
+	require.NoError(t, err)
+	require.True(t, valid)
+}
+
 func testSQLDB(t testing.TB) *sql.DB {
 	t.Helper()
 
@@ -256,6 +376,7 @@ func TestMigrateUpWithFixtures(t *testing.T) {
 	// but we should eventually add fixtures for them.
 	ignoredTablesForStats := []string{
 		"audit_logs",
+		"audit_logs_default",
 		"external_auth_links",
 		"group_members",
 		"licenses",
@@ -0,0 +1,243 @@
+package migrations
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/xerrors"
+)
+
+// Online migrations are migrations that cannot hold their locks for the
+// whole migration, usually because they touch tables with millions of rows.
+// They are marked with a directive comment on its own line:
+//
+//	-- migrate:no-transaction
+//
+// The statements of such a migration are run one at a time outside of the
+// migration transaction, so they can use CREATE INDEX CONCURRENTLY. A
+// statement that is preceded by the directive
+//
+//	-- migrate:batch
+//
+// is a batched backfill. It is repeated until it affects no rows, so it must
+// only process a limited number of rows that still need to be backfilled,
+// for example:
+//
+//	UPDATE t SET b = a WHERE id IN (SELECT id FROM t WHERE b IS NULL LIMIT 10000);
+//
+// Each batch commits on its own, so row locks are only held for one batch.
+//
+// If an online migration fails, the database is left at the previous version
+// instead of being marked dirty, and the migration is retried from the start
+// on the next run. Its statements must therefore be idempotent, e.g. use
+// IF NOT EXISTS. An invalid index left behind by a failed CREATE INDEX
+// CONCURRENTLY is dropped before the statement is retried.
+var (
+	noTransactionDirective = regexp.MustCompile(`(?m)^--\s*migrate:no-transaction\s*$`)
+	batchDirective         = regexp.MustCompile(`(?m)^--\s*migrate:batch\s*$`)
+	concurrentIndex        = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+CONCURRENTLY\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+)
+
+// statement is a single statement of an online migration.
+type statement struct {
+	// SQL is the statement, including its leading comments.
+	SQL string
+	// Batch is whether the statement is a batched backfill.
+	Batch bool
+	// index is the name of the index created concurrently by the statement.
+	index string
+}
+
+// isOnline returns whether a migration must run outside of the migration
+// transaction.
+func isOnline(migration string) bool {
+	return noTransactionDirective.MatchString(migration)
+}
+
+// splitStatements splits a migration into its statements. Semicolons in
+// comments, quoted strings and identifiers, and dollar-quoted bodies do not
+// end a statement.
+func splitStatements(migration string) []statement {
+	var (
+		statements []statement
+		start      int
+		// code is the statement without comments, used to detect empty
+		// statements and statement kinds.
+		code strings.Builder
+	)
+	add := func(end int) {
+		stmt := migration[start:end]
+		start = end
+		trimmed := strings.TrimSpace(code.String())
+		code.Reset()
+		if trimmed == "" {
+			return
+		}
+		s := statement{
+			SQL:   strings.TrimSpace(stmt),
+			Batch: batchDirective.MatchString(stmt),
+		}
+		if m := concurrentIndex.FindStringSubmatch(trimmed); m != nil {
+			s.index = strings.ToLower(m[1])
+		}
+		statements = append(statements, s)
+	}
+
+	for i := 0; i < len(migration); i++ {
+		switch c := migration[i]; {
+		case strings.HasPrefix(migration[i:], "--"):
+			end := strings.IndexByte(migration[i:], '\n')
+			if end < 0 {
+				end = len(migration) - i
+			}
+			i += end - 1
+			code.WriteByte(' ')
+		case strings.HasPrefix(migration[i:], "/*"):
+			end := strings.Index(migration[i+2:], "*/")
+			if end < 0 {
+				end = len(migration) - i - 4
+			}
+			i += end + 3
+			code.WriteByte(' ')
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(migration[i+1:], c)
+			if end < 0 {
+				end = len(migration) - i - 2
+			}
+			code.WriteString(migration[i : i+end+2])
+			i += end + 1
+		case c == '$':
+			tag := dollarTag(migration[i:])
+			if tag == "" {
+				code.WriteByte(c)
+				continue
+			}
+			end := strings.Index(migration[i+len(tag):], tag)
+			if end < 0 {
+				end = len(migration) - i - 2*len(tag)
+			}
+			code.WriteString(migration[i : i+end+2*len(tag)])
+			i += end + 2*len(tag) - 1
+		case c == ';':
+			add(i + 1)
+		default:
+			code.WriteByte(c)
+		}
+	}
+	add(len(migration))
+	return statements
+}
+
+// dollarTag returns the dollar quote tag at the start of s, e.g. "$$" or
+// "$body$", or an empty string if s does not start with one.
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 1 && c >= '0' && c <= '9'):
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// runOnline runs the statements of an online migration outside of the
+// migration transaction. The migration lock is kept with a session level
+// lock, and the version is reset to the previous version so a failure
+// doesn't leave the database dirty.
+func (d *pgTxnDriver) runOnline(migration string) (retErr error) {
+	statements := splitStatements(migration)
+
+	// The lock is taken on the connection of the migration transaction,
+	// so it doesn't conflict with the transaction level lock.
+	if _, err := d.tx.ExecContext(d.ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return xerrors.Errorf("acquire session migration lock: %w", err)
+	}
+	if err := d.setVersion(d.previousVersion, false); err != nil {
+		return xerrors.Errorf("reset version: %w", err)
+	}
+	err := d.tx.Commit()
+	d.tx = nil
+	if err != nil {
+		return xerrors.Errorf("commit tx before online migration: %w", err)
+	}
+	defer func() {
+		// Continue in a new transaction, since golang-migrate sets the
+		// version after running the migration.
+		var err error
+		d.tx, err = d.conn.BeginTx(d.ctx, nil)
+		if err == nil {
+			_, err = d.tx.ExecContext(d.ctx, `SELECT pg_advisory_xact_lock($1)`, lockID)
+		}
+		if err == nil {
+			_, err = d.tx.ExecContext(d.ctx, `SELECT pg_advisory_unlock($1)`, lockID)
+		}
+		if err == nil {
+			return
+		}
+		// Discard the connection, so the session level lock can't be
+		// leaked back into the pool.
+		_ = d.conn.Raw(func(any) error {
+			return driver.ErrBadConn
+		})
+		if retErr == nil {
+			retErr = xerrors.Errorf("reacquire migration lock: %w", err)
+		}
+	}()
+
+	for _, stmt := range statements {
+		if err := d.runOnlineStatement(d.ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *pgTxnDriver) runOnlineStatement(ctx context.Context, stmt statement) error {
+	if stmt.index != "" {
+		var invalid bool
+		err := d.conn.QueryRowContext(ctx, `
+SELECT EXISTS (
+	SELECT 1 FROM pg_index
+	JOIN pg_class ON pg_class.oid = pg_index.indexrelid
+	WHERE pg_class.relname = $1
+	AND pg_class.relnamespace = current_schema()::regnamespace
+	AND NOT pg_index.indisvalid
+)`, stmt.index).Scan(&invalid)
+		if err != nil {
+			return xerrors.Errorf("check index %q: %w", stmt.index, err)
+		}
+		if invalid {
+			query := `DROP INDEX CONCURRENTLY IF EXISTS ` + pq.QuoteIdentifier(stmt.index)
+			if _, err := d.conn.ExecContext(ctx, query); err != nil {
+				return xerrors.Errorf("drop invalid index %q: %w", stmt.index, err)
+			}
+		}
+	}
+
+	for {
+		res, err := d.conn.ExecContext(ctx, stmt.SQL)
+		if err != nil {
+			return migrationError(err, []byte(stmt.SQL))
+		}
+		if !stmt.Batch {
+			return nil
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return xerrors.Errorf("get affected rows: %w", err)
+		}
+		if rows == 0 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		migration string
+		expected  []statement
+	}{
+		{
+			name:      "Single",
+			migration: "CREATE TABLE t (a int);",
+			expected:  []statement{{SQL: "CREATE TABLE t (a int);"}},
+		},
+		{
+			name:      "NoTrailingSemicolon",
+			migration: "ALTER TABLE t ADD COLUMN b int;\nALTER TABLE t ADD COLUMN c int\n",
+			expected: []statement{
+				{SQL: "ALTER TABLE t ADD COLUMN b int;"},
+				{SQL: "ALTER TABLE t ADD COLUMN c int"},
+			},
+		},
+		{
+			name:      "CommentsOnly",
+			migration: "-- migrate:no-transaction\n/* nothing; to do */\n",
+		},
+		{
+			name: "Quoted",
+			migration: "COMMENT ON TABLE t IS 'a; b -- c';\n" +
+				"ALTER TABLE \"odd;name\" ADD COLUMN d int;",
+			expected: []statement{
+				{SQL: "COMMENT ON TABLE t IS 'a; b -- c';"},
+				{SQL: "ALTER TABLE \"odd;name\" ADD COLUMN d int;"},
+			},
+		},
+		{
+			name: "DollarQuoted",
+			migration: "CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN RETURN NEW; END; $body$ LANGUAGE plpgsql;\n" +
+				"DO $$ BEGIN PERFORM 1; END $$;",
+			expected: []statement{
+				{SQL: "CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN RETURN NEW; END; $body$ LANGUAGE plpgsql;"},
+				{SQL: "DO $$ BEGIN PERFORM 1; END $$;"},
+			},
+		},
+		{
+			name: "Directives",
+			migration: "-- migrate:no-transaction\n\n" +
+				"-- migrate:batch\n" +
+				"UPDATE t SET b = a WHERE id IN (SELECT id FROM t WHERE b IS NULL LIMIT $1);\n\n" +
+				"-- The index is used to find rows; it is created online.\n" +
+				"CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS T_B_idx ON t (b);\n",
+			expected: []statement{
+				{
+					SQL:   "-- migrate:no-transaction\n\n-- migrate:batch\nUPDATE t SET b = a WHERE id IN (SELECT id FROM t WHERE b IS NULL LIMIT $1);",
+					Batch: true,
+				},
+				{
+					SQL:   "-- The index is used to find rows; it is created online.\nCREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS T_B_idx ON t (b);",
+					index: "t_b_idx",
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, splitStatements(tc.migration))
+		})
+	}
+}
+
+func TestIsOnline(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isOnline("-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY i ON t (a);"))
+	require.True(t, isOnline("-- A comment.\n--  migrate:no-transaction  \nSELECT 1;"))
+	require.False(t, isOnline("SELECT '-- migrate:no-transaction';"))
+	require.False(t, isOnline("CREATE INDEX i ON t (a);"))
+}
@@ -0,0 +1,4 @@
+INSERT INTO workspace_naming_policies (id, organization_id, template_id, prefix, uniqueness_scope, created_at, updated_at)
+SELECT gen_random_uuid(), id, NULL, '{{.Username}}-', 'owner', NOW(), NOW()
+FROM organizations
+LIMIT 1;
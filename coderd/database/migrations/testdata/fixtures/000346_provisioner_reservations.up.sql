@@ -0,0 +1,4 @@
+INSERT INTO provisioner_reservations (id, organization_id, tags, slots, starts_at, ends_at, created_at)
+SELECT gen_random_uuid(), id, '{"purpose": "training"}'::jsonb, 5, NOW(), NOW() + INTERVAL '2 hours', NOW()
+FROM organizations
+LIMIT 1;
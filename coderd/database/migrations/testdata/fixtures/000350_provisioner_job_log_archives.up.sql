@@ -0,0 +1,4 @@
+INSERT INTO provisioner_job_log_archives (job_id, file_id, log_count, archived_at)
+SELECT provisioner_jobs.id, files.id, 1, NOW()
+FROM provisioner_jobs, files
+LIMIT 1;
@@ -0,0 +1,4 @@
+INSERT INTO build_alert_rules (id, organization_id, template_id, name, metric, threshold, window_ms, created_at, updated_at)
+SELECT gen_random_uuid(), organization_id, id, 'Failure rate', 'failure_rate', 10, 900000, NOW(), NOW()
+FROM templates
+LIMIT 1;
@@ -0,0 +1,6 @@
+INSERT INTO workspace_provisioner_affinities (workspace_id, provisioner_daemon_id, template_version_id, updated_at)
+SELECT workspaces.id, provisioner_daemons.id, templates.active_version_id, NOW()
+FROM workspaces
+INNER JOIN templates ON templates.id = workspaces.template_id
+CROSS JOIN provisioner_daemons
+LIMIT 1;
@@ -0,0 +1,4 @@
+INSERT INTO provisioner_build_pauses (id, organization_id, reason, created_by, created_at)
+SELECT gen_random_uuid(), id, 'Database maintenance', NULL, NOW()
+FROM organizations
+LIMIT 1;
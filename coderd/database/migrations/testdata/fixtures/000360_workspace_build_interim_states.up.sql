@@ -0,0 +1,4 @@
+INSERT INTO workspace_build_interim_states (workspace_build_id, state, updated_at)
+SELECT id, '\x7b7d', NOW()
+FROM workspace_builds
+LIMIT 1;
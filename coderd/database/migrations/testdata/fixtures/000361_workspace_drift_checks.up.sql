@@ -0,0 +1,4 @@
+INSERT INTO workspace_drift_checks (workspace_id, workspace_build_id, job_id, created_at, completed_at, drifted_resources, orphaned_resources)
+SELECT workspace_id, id, job_id, NOW(), NOW(), '{}', '{docker_container.workspace}'
+FROM workspace_builds
+LIMIT 1;
@@ -0,0 +1,4 @@
+INSERT INTO workspace_build_queue (id, workspace_id, initiator_id, transition, created_at)
+SELECT gen_random_uuid(), id, owner_id, 'stop', NOW()
+FROM workspaces
+LIMIT 1;
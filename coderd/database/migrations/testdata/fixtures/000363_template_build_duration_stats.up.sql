@@ -0,0 +1,22 @@
+INSERT INTO
+	template_build_duration_stats (
+		start_time,
+		end_time,
+		template_id,
+		transition,
+		build_count,
+		p50_duration_ms,
+		p95_duration_ms,
+		max_duration_ms
+	)
+VALUES
+	(
+		date_trunc('day', NOW()),
+		date_trunc('day', NOW()) + '1 day'::interval,
+		gen_random_uuid(),
+		'start',
+		12,
+		45000,
+		93000,
+		121000
+	);
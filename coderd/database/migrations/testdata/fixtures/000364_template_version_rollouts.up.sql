@@ -0,0 +1,4 @@
+INSERT INTO template_version_rollouts (template_id, template_version_id, percent, created_by, created_at, updated_at)
+SELECT templates.id, templates.active_version_id, 10, templates.created_by, NOW(), NOW()
+FROM templates
+LIMIT 1;
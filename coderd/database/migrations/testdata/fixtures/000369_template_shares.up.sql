@@ -0,0 +1,5 @@
+INSERT INTO template_shares (template_id, organization_id, created_by, created_at)
+SELECT templates.id, organizations.id, templates.created_by, NOW()
+FROM templates, organizations
+WHERE organizations.id != templates.organization_id
+LIMIT 1;
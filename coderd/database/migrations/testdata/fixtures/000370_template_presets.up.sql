@@ -0,0 +1,12 @@
+INSERT INTO template_presets (id, template_id, name, description, parameters, created_at, updated_at)
+SELECT 'bb0d5fb0-67b2-4e1d-9a4c-3a0ec5d7e1f2', id, 'small', 'A small workspace.', '{"cpu": "2"}'::jsonb, NOW(), NOW()
+FROM templates
+LIMIT 1;
+
+INSERT INTO template_preset_group_defaults (template_id, group_id, template_preset_id)
+SELECT template_presets.template_id, groups.id, template_presets.id
+FROM template_presets
+	INNER JOIN templates ON templates.id = template_presets.template_id
+	INNER JOIN groups ON groups.organization_id = templates.organization_id
+WHERE template_presets.id = 'bb0d5fb0-67b2-4e1d-9a4c-3a0ec5d7e1f2'
+LIMIT 1;
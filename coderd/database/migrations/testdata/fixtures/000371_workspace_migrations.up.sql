@@ -0,0 +1,4 @@
+INSERT INTO workspace_migrations (id, workspace_id, initiator_id, destination_url, destination_organization_id, destination_owner, destination_session_token, created_at, updated_at)
+SELECT gen_random_uuid(), id, owner_id, 'https://coder.example.com', gen_random_uuid(), 'me', 'token', NOW(), NOW()
+FROM workspaces
+LIMIT 1;
@@ -0,0 +1,4 @@
+INSERT INTO workspace_labels (workspace_id, key, value, created_at)
+SELECT id, 'team', 'payments', NOW()
+FROM workspaces
+LIMIT 1;
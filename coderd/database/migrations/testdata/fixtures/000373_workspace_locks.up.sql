@@ -0,0 +1,4 @@
+INSERT INTO workspace_locks (id, workspace_id, reason, locked_by, locked_at)
+SELECT gen_random_uuid(), id, 'incident', owner_id, NOW()
+FROM workspaces
+LIMIT 1;
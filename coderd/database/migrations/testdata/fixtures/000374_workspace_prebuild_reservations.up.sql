@@ -0,0 +1,4 @@
+INSERT INTO workspace_prebuild_reservations (workspace_id, user_id, reserved_by, created_at, expires_at)
+SELECT id, owner_id, owner_id, NOW(), NOW() + INTERVAL '1 day'
+FROM workspaces
+LIMIT 1;
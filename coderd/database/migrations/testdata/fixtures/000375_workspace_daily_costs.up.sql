@@ -0,0 +1,20 @@
+INSERT INTO
+	workspace_daily_costs (
+		start_time,
+		end_time,
+		workspace_id,
+		organization_id,
+		owner_id,
+		template_id,
+		cost
+	)
+VALUES
+	(
+		date_trunc('day', NOW()),
+		date_trunc('day', NOW()) + '1 day'::interval,
+		gen_random_uuid(),
+		gen_random_uuid(),
+		gen_random_uuid(),
+		gen_random_uuid(),
+		12.5
+	);
@@ -0,0 +1,28 @@
+INSERT INTO
+	workspace_agent_resource_usage (
+		agent_id,
+		bucket_start,
+		samples,
+		cpu_used_avg,
+		cpu_used_max,
+		cpu_total,
+		memory_used_avg,
+		memory_used_max,
+		memory_total,
+		disk_used,
+		disk_total
+	)
+VALUES
+	(
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'2024-01-01 00:00:00+00',
+		15,
+		0.5,
+		1.75,
+		4,
+		2147483648,
+		3221225472,
+		8589934592,
+		10737418240,
+		53687091200
+	);
@@ -0,0 +1,32 @@
+INSERT INTO
+	workspace_agent_health_events (
+		id,
+		agent_id,
+		kind,
+		volume,
+		used,
+		total,
+		created_at,
+		resolved_at
+	)
+VALUES
+	(
+		'b4b7a3e2-8f3c-4c5e-9d7a-2f6e1c0a9b51',
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'near_out_of_memory',
+		'',
+		8266752000,
+		8589934592,
+		'2024-01-01 00:00:00+00',
+		'2024-01-01 00:10:00+00'
+	),
+	(
+		'0c5d2a9f-3e6b-4a1d-8c7f-5b9e2d4a6f13',
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'low_disk_space',
+		'/home/coder',
+		52076478464,
+		53687091200,
+		'2024-01-01 00:00:00+00',
+		NULL
+	);
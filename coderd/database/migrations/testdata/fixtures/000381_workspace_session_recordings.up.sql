@@ -0,0 +1,39 @@
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		workspace_id,
+		agent_id,
+		type,
+		width,
+		height,
+		started_at,
+		ended_at,
+		size_bytes
+	)
+VALUES
+	(
+		'7c1f4a2e-5b3d-4e8f-9a6c-1d2e3f4a5b6c',
+		'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'ssh',
+		80,
+		24,
+		'2024-01-01 00:00:00+00',
+		'2024-01-01 00:05:00+00',
+		12
+	);
+
+INSERT INTO
+	workspace_session_recording_chunks (
+		recording_id,
+		created_at,
+		data,
+		data_key_id
+	)
+VALUES
+	(
+		'7c1f4a2e-5b3d-4e8f-9a6c-1d2e3f4a5b6c',
+		'2024-01-01 00:00:01+00',
+		'\x68656c6c6f20776f726c640a',
+		NULL
+	);
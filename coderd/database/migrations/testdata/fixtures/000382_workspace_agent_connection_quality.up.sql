@@ -0,0 +1,26 @@
+INSERT INTO
+	workspace_agent_connection_quality (
+		agent_id,
+		ip,
+		p2p,
+		derp_region_code,
+		latency_ms,
+		pings_sent,
+		pings_lost,
+		last_handshake_at,
+		created_at,
+		updated_at
+	)
+VALUES
+	(
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'fd7a:115c:a1e0:4353:89d9:4ca8:9c42:8d2d',
+		false,
+		'coder',
+		42.5,
+		10,
+		1,
+		'2024-01-01 00:09:00+00',
+		'2024-01-01 00:00:00+00',
+		'2024-01-01 00:10:00+00'
+	);
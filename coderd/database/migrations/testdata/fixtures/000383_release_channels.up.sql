@@ -0,0 +1,9 @@
+INSERT INTO template_release_channels (template_id, channel, template_version_id, updated_by, updated_at)
+SELECT templates.id, 'beta', templates.active_version_id, templates.created_by, NOW()
+FROM templates
+LIMIT 1;
+
+INSERT INTO workspace_release_channels (workspace_id, channel, updated_at)
+SELECT id, 'beta', NOW()
+FROM workspaces
+LIMIT 1;
@@ -0,0 +1,4 @@
+INSERT INTO login_device_codes (id, device_code_hash, user_code, user_id, created_at, expires_at)
+SELECT 'b4a9b1d6-6a7e-4a55-8d3a-2b1f0d1c9e01', '\xdeadbeef'::bytea, 'BCDFGHJK', id, NOW(), NOW() + INTERVAL '10 minutes'
+FROM users
+LIMIT 1;
@@ -0,0 +1,5 @@
+INSERT INTO workspace_build_idempotency_keys (workspace_build_id, user_id, idempotency_key, created_at)
+SELECT workspace_builds.id, workspace_builds.initiator_id, 'fixture-key', NOW()
+FROM workspace_builds
+JOIN users ON users.id = workspace_builds.initiator_id
+LIMIT 1;
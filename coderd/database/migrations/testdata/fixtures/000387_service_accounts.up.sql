@@ -0,0 +1,7 @@
+INSERT INTO users (id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type)
+VALUES ('5a1c6b0e-8a8e-4f7e-9b0a-2f3c1e6d4b7a', 'fixture-service-account@service-account', 'fixture-service-account', '', NOW(), NOW(), 'active', '{}', 'none');
+
+INSERT INTO service_accounts (user_id, organization_id, description, created_by, created_at)
+SELECT '5a1c6b0e-8a8e-4f7e-9b0a-2f3c1e6d4b7a', organizations.id, 'Fixture service account', NULL, NOW()
+FROM organizations
+LIMIT 1;
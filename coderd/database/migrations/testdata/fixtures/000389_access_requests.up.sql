@@ -0,0 +1,5 @@
+INSERT INTO access_requests (id, organization_id, requester_id, resource_type, resource_id, role, reason, duration_ms, created_at)
+SELECT gen_random_uuid(), o.id, u.id, 'template', t.id, 'use', 'Debugging a build failure', 3600000, NOW()
+FROM organizations o, users u, templates t
+WHERE t.organization_id = o.id
+LIMIT 1;
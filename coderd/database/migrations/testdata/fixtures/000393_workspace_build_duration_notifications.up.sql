@@ -0,0 +1,4 @@
+INSERT INTO workspace_build_duration_notifications (workspace_build_id, notified_at)
+SELECT id, NOW()
+FROM workspace_builds
+LIMIT 1;
@@ -0,0 +1,4 @@
+INSERT INTO user_activity_exports (id, created_by, created_at, updated_at, start_time, end_time, format)
+SELECT gen_random_uuid(), u.id, NOW(), NOW(), NOW() - INTERVAL '7 days', NOW(), 'csv'
+FROM users u
+LIMIT 1;
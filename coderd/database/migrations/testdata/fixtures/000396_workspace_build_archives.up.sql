@@ -0,0 +1,4 @@
+INSERT INTO workspace_build_archives (workspace_build_id, file_id, state_size, resource_metadata_count, archived_at)
+SELECT workspace_builds.id, files.id, 1, 1, NOW()
+FROM workspace_builds, files
+LIMIT 1;
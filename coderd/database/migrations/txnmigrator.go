@@ -20,11 +20,16 @@ const (
 // pgTxnDriver is a Postgres migration driver that runs all migrations in a
 // single transaction. This is done to prevent users from being locked out of
 // their deployment if a migration fails, since the schema will simply revert
-// back to the previous version.
+// back to the previous version. Online migrations are the exception, see
+// runOnline.
 type pgTxnDriver struct {
-	ctx context.Context
-	db  *sql.DB
-	tx  *sql.Tx
+	ctx  context.Context
+	db   *sql.DB
+	conn *sql.Conn
+	tx   *sql.Tx
+	// previousVersion is the version before the migration that is being
+	// run, which online migrations reset to.
+	previousVersion int
 }
 
 func (*pgTxnDriver) Open(string) (database.Driver, error) {
@@ -38,10 +43,18 @@ func (*pgTxnDriver) Close() error {
 func (d *pgTxnDriver) Lock() error {
 	var err error
 
-	d.tx, err = d.db.BeginTx(d.ctx, nil)
+	// Online migrations run outside of the transaction, but must use the
+	// same connection.
+	d.conn, err = d.db.Conn(d.ctx)
 	if err != nil {
 		return err
 	}
+	d.tx, err = d.conn.BeginTx(d.ctx, nil)
+	if err != nil {
+		_ = d.conn.Close()
+		d.conn = nil
+		return err
+	}
 	const q = `
 SELECT pg_advisory_xact_lock($1)
 `
@@ -54,8 +67,16 @@ SELECT pg_advisory_xact_lock($1)
 }
 
 func (d *pgTxnDriver) Unlock() error {
+	if d.tx == nil {
+		// The transaction could not be restarted after an online migration.
+		_ = d.conn.Close()
+		d.conn = nil
+		return xerrors.New("no migration transaction")
+	}
 	err := d.tx.Commit()
 	d.tx = nil
+	_ = d.conn.Close()
+	d.conn = nil
 	if err != nil {
 		return xerrors.Errorf("commit tx on unlock: %w", err)
 	}
@@ -67,6 +88,13 @@ func (d *pgTxnDriver) Run(migration io.Reader) error {
 	if err != nil {
 		return xerrors.Errorf("read migration: %w", err)
 	}
+	if isOnline(string(migr)) {
+		err = d.runOnline(string(migr))
+		if err != nil {
+			return xerrors.Errorf("run online migration: %w", err)
+		}
+		return nil
+	}
 	err = d.runStatement(migr)
 	if err != nil {
 		return xerrors.Errorf("run statement: %w", err)
@@ -81,22 +109,40 @@ func (d *pgTxnDriver) runStatement(statement []byte) error {
 		return nil
 	}
 	if _, err := d.tx.ExecContext(ctx, query); err != nil {
-		var pgErr *pq.Error
-		if xerrors.As(err, &pgErr) {
-			var line uint
-			message := fmt.Sprintf("migration failed: %s", pgErr.Message)
-			if pgErr.Detail != "" {
-				message += ", " + pgErr.Detail
-			}
-			return database.Error{OrigErr: err, Err: message, Query: statement, Line: line}
-		}
-		return database.Error{OrigErr: err, Err: "migration failed", Query: statement}
+		return migrationError(err, statement)
 	}
 	return nil
 }
 
+func migrationError(err error, statement []byte) error {
+	var pgErr *pq.Error
+	if xerrors.As(err, &pgErr) {
+		var line uint
+		message := fmt.Sprintf("migration failed: %s", pgErr.Message)
+		if pgErr.Detail != "" {
+			message += ", " + pgErr.Detail
+		}
+		return database.Error{OrigErr: err, Err: message, Query: statement, Line: line}
+	}
+	return database.Error{OrigErr: err, Err: "migration failed", Query: statement}
+}
+
 //nolint:revive
 func (d *pgTxnDriver) SetVersion(version int, dirty bool) error {
+	if dirty {
+		// golang-migrate marks the database dirty right before running a
+		// migration.
+		previous, _, err := d.Version()
+		if err != nil {
+			return err
+		}
+		d.previousVersion = previous
+	}
+	return d.setVersion(version, dirty)
+}
+
+//nolint:revive
+func (d *pgTxnDriver) setVersion(version int, dirty bool) error {
 	query := `TRUNCATE ` + migrationsTableName
 	if _, err := d.tx.Exec(query); err != nil {
 		return &database.Error{OrigErr: err, Query: []byte(query)}
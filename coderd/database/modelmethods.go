@@ -2,9 +2,12 @@ package database
 
 import (
 	"encoding/hex"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
@@ -123,6 +126,12 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 		return rbac.ScopeAll
 	case APIKeyScopeApplicationConnect:
 		return rbac.ScopeApplicationConnect
+	case APIKeyScopeWorkspaceRead:
+		return rbac.ScopeWorkspaceRead
+	case APIKeyScopeBuildCreate:
+		return rbac.ScopeBuildCreate
+	case APIKeyScopeTemplateAdmin:
+		return rbac.ScopeTemplateAdmin
 	default:
 		panic("developer error: unknown scope type " + string(s))
 	}
@@ -133,11 +142,34 @@ func (k APIKey) RBACObject() rbac.Object {
 		WithOwner(k.UserID.String())
 }
 
+func (s ServiceAccount) RBACObject() rbac.Object {
+	return rbac.ResourceServiceAccount.WithID(s.UserID).InOrg(s.OrganizationID)
+}
+
 func (t Template) RBACObject() rbac.Object {
 	return rbac.ResourceTemplate.WithID(t.ID).
 		InOrg(t.OrganizationID).
 		WithACLUserList(t.UserACL).
-		WithGroupACL(t.GroupACL)
+		WithGroupACL(t.groupACLWithShares())
+}
+
+// groupACLWithShares returns the group ACL of the template, with read access
+// for the 'Everyone' group of each organization the template is shared into.
+// The ID of the 'Everyone' group is the organization ID.
+func (t Template) groupACLWithShares() map[string][]policy.Action {
+	if len(t.SharedOrganizationIDs) == 0 {
+		return t.GroupACL
+	}
+	acl := maps.Clone(t.GroupACL)
+	if acl == nil {
+		acl = TemplateACL{}
+	}
+	for _, orgID := range t.SharedOrganizationIDs {
+		if _, ok := acl[orgID.String()]; !ok {
+			acl[orgID.String()] = []policy.Action{policy.ActionRead}
+		}
+	}
+	return acl
 }
 
 func (t GetFileTemplatesRow) RBACObject() rbac.Object {
@@ -151,6 +183,7 @@ func (t Template) DeepCopy() Template {
 	cpy := t
 	cpy.UserACL = maps.Clone(t.UserACL)
 	cpy.GroupACL = maps.Clone(t.GroupACL)
+	cpy.SharedOrganizationIDs = slices.Clone(t.SharedOrganizationIDs)
 	return cpy
 }
 
@@ -229,6 +262,8 @@ func (w Workspace) WorkspaceTable() WorkspaceTable {
 		AutomaticUpdates:  w.AutomaticUpdates,
 		Favorite:          w.Favorite,
 		NextStartAt:       w.NextStartAt,
+		GroupACL:          w.GroupACL,
+		UserACL:           w.UserACL,
 	}
 }
 
@@ -261,7 +296,9 @@ func (w WorkspaceTable) RBACObject() rbac.Object {
 
 	return rbac.ResourceWorkspace.WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithGroupACL(w.GroupACL).
+		WithACLUserList(w.UserACL)
 }
 
 func (w WorkspaceTable) DormantRBAC() rbac.Object {
@@ -339,6 +376,16 @@ func (p ProvisionerKey) RBACObject() rbac.Object {
 		InOrg(p.OrganizationID)
 }
 
+func (p ProvisionerReservation) RBACObject() rbac.Object {
+	return rbac.ResourceProvisionerDaemon.
+		WithID(p.ID).
+		InOrg(p.OrganizationID)
+}
+
+func (r BuildAlertRule) RBACObject() rbac.Object {
+	return rbac.ResourceTemplate.InOrg(r.OrganizationID)
+}
+
 func (w WorkspaceProxy) RBACObject() rbac.Object {
 	return rbac.ResourceWorkspaceProxy.
 		WithID(w.ID)
@@ -504,6 +551,8 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) []Workspace {
 			TemplateIcon:            r.TemplateIcon,
 			TemplateDescription:     r.TemplateDescription,
 			NextStartAt:             r.NextStartAt,
+			GroupACL:                r.GroupACL,
+			UserACL:                 r.UserACL,
 		}
 	}
 
@@ -611,3 +660,38 @@ func (m WorkspaceAgentVolumeResourceMonitor) Debounce(
 
 	return m.DebouncedUntil, false
 }
+
+// Matches reports whether a log is returned by SearchProvisionerJobLogs with
+// these parameters. Full-text search is approximated by requiring every word
+// of the search to appear as a word of the output.
+func (p SearchProvisionerJobLogsParams) Matches(log ProvisionerJobLog) bool {
+	if log.JobID != p.JobID || log.ID <= p.CreatedAfter {
+		return false
+	}
+	if p.MinLevel.Valid && log.Level.Severity() < p.MinLevel.LogLevel.Severity() {
+		return false
+	}
+	if p.Stage != "" && log.Stage != p.Stage {
+		return false
+	}
+	if p.Search != "" {
+		words := logWords(log.Output)
+		for _, word := range logWords(p.Search) {
+			if !slices.Contains(words, word) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Severity orders log levels the same way as the log_level enum.
+func (l LogLevel) Severity() int {
+	return slices.Index(AllLogLevelValues(), l)
+}
+
+func logWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -119,12 +119,16 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.MaxConcurrentJobsPerUser,
+			&i.ResourceCeilings,
+			&i.RequiredProvisionerTags,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
 			&i.OrganizationName,
 			&i.OrganizationDisplayName,
 			&i.OrganizationIcon,
+			pq.Array(&i.SharedOrganizationIDs),
 		); err != nil {
 			return nil, err
 		}
@@ -267,6 +271,9 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 		arg.LastUsedAfter,
 		arg.UsingActive,
 		arg.HasAITask,
+		pq.Array(arg.LabelKeys),
+		pq.Array(arg.LabelValues),
+		arg.AfterID,
 		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
@@ -296,6 +303,8 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.GroupACL,
+			&i.UserACL,
 			&i.OwnerAvatarUrl,
 			&i.OwnerUsername,
 			&i.OwnerName,
@@ -506,7 +515,9 @@ func (q *sqlQuerier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg GetAu
 		arg.DateFrom,
 		arg.DateTo,
 		arg.BuildReason,
+		arg.ClientType,
 		arg.RequestID,
+		arg.AfterID,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -21,6 +21,9 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeWorkspaceRead      APIKeyScope = "workspace:read"
+	APIKeyScopeBuildCreate        APIKeyScope = "build:create"
+	APIKeyScopeTemplateAdmin      APIKeyScope = "template:admin"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -61,7 +64,10 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeWorkspaceRead,
+		APIKeyScopeBuildCreate,
+		APIKeyScopeTemplateAdmin:
 		return true
 	}
 	return false
@@ -71,6 +77,131 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeWorkspaceRead,
+		APIKeyScopeBuildCreate,
+		APIKeyScopeTemplateAdmin,
+	}
+}
+
+type AccessRequestResourceType string
+
+const (
+	AccessRequestResourceTypeWorkspace AccessRequestResourceType = "workspace"
+	AccessRequestResourceTypeTemplate  AccessRequestResourceType = "template"
+)
+
+func (e *AccessRequestResourceType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccessRequestResourceType(s)
+	case string:
+		*e = AccessRequestResourceType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccessRequestResourceType: %T", src)
+	}
+	return nil
+}
+
+type NullAccessRequestResourceType struct {
+	AccessRequestResourceType AccessRequestResourceType `json:"access_request_resource_type"`
+	Valid                     bool                      `json:"valid"` // Valid is true if AccessRequestResourceType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccessRequestResourceType) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccessRequestResourceType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccessRequestResourceType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccessRequestResourceType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccessRequestResourceType), nil
+}
+
+func (e AccessRequestResourceType) Valid() bool {
+	switch e {
+	case AccessRequestResourceTypeWorkspace,
+		AccessRequestResourceTypeTemplate:
+		return true
+	}
+	return false
+}
+
+func AllAccessRequestResourceTypeValues() []AccessRequestResourceType {
+	return []AccessRequestResourceType{
+		AccessRequestResourceTypeWorkspace,
+		AccessRequestResourceTypeTemplate,
+	}
+}
+
+type AccessRequestStatus string
+
+const (
+	AccessRequestStatusPending  AccessRequestStatus = "pending"
+	AccessRequestStatusApproved AccessRequestStatus = "approved"
+	AccessRequestStatusDenied   AccessRequestStatus = "denied"
+	AccessRequestStatusExpired  AccessRequestStatus = "expired"
+)
+
+func (e *AccessRequestStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccessRequestStatus(s)
+	case string:
+		*e = AccessRequestStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccessRequestStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAccessRequestStatus struct {
+	AccessRequestStatus AccessRequestStatus `json:"access_request_status"`
+	Valid               bool                `json:"valid"` // Valid is true if AccessRequestStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccessRequestStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccessRequestStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccessRequestStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccessRequestStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccessRequestStatus), nil
+}
+
+func (e AccessRequestStatus) Valid() bool {
+	switch e {
+	case AccessRequestStatusPending,
+		AccessRequestStatusApproved,
+		AccessRequestStatusDenied,
+		AccessRequestStatusExpired:
+		return true
+	}
+	return false
+}
+
+func AllAccessRequestStatusValues() []AccessRequestStatus {
+	return []AccessRequestStatus{
+		AccessRequestStatusPending,
+		AccessRequestStatusApproved,
+		AccessRequestStatusDenied,
+		AccessRequestStatusExpired,
 	}
 }
 
@@ -139,6 +270,7 @@ const (
 	AppSharingLevelAuthenticated AppSharingLevel = "authenticated"
 	AppSharingLevelOrganization  AppSharingLevel = "organization"
 	AppSharingLevelPublic        AppSharingLevel = "public"
+	AppSharingLevelRestricted    AppSharingLevel = "restricted"
 )
 
 func (e *AppSharingLevel) Scan(src interface{}) error {
@@ -181,7 +313,8 @@ func (e AppSharingLevel) Valid() bool {
 	case AppSharingLevelOwner,
 		AppSharingLevelAuthenticated,
 		AppSharingLevelOrganization,
-		AppSharingLevelPublic:
+		AppSharingLevelPublic,
+		AppSharingLevelRestricted:
 		return true
 	}
 	return false
@@ -193,6 +326,7 @@ func AllAppSharingLevelValues() []AppSharingLevel {
 		AppSharingLevelAuthenticated,
 		AppSharingLevelOrganization,
 		AppSharingLevelPublic,
+		AppSharingLevelRestricted,
 	}
 }
 
@@ -345,6 +479,64 @@ func AllAutomaticUpdatesValues() []AutomaticUpdates {
 	}
 }
 
+type BuildAlertRuleMetric string
+
+const (
+	BuildAlertRuleMetricFailureRate  BuildAlertRuleMetric = "failure_rate"
+	BuildAlertRuleMetricQueueWaitP95 BuildAlertRuleMetric = "queue_wait_p95"
+)
+
+func (e *BuildAlertRuleMetric) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BuildAlertRuleMetric(s)
+	case string:
+		*e = BuildAlertRuleMetric(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BuildAlertRuleMetric: %T", src)
+	}
+	return nil
+}
+
+type NullBuildAlertRuleMetric struct {
+	BuildAlertRuleMetric BuildAlertRuleMetric `json:"build_alert_rule_metric"`
+	Valid                bool                 `json:"valid"` // Valid is true if BuildAlertRuleMetric is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBuildAlertRuleMetric) Scan(value interface{}) error {
+	if value == nil {
+		ns.BuildAlertRuleMetric, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BuildAlertRuleMetric.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBuildAlertRuleMetric) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BuildAlertRuleMetric), nil
+}
+
+func (e BuildAlertRuleMetric) Valid() bool {
+	switch e {
+	case BuildAlertRuleMetricFailureRate,
+		BuildAlertRuleMetricQueueWaitP95:
+		return true
+	}
+	return false
+}
+
+func AllBuildAlertRuleMetricValues() []BuildAlertRuleMetric {
+	return []BuildAlertRuleMetric{
+		BuildAlertRuleMetricFailureRate,
+		BuildAlertRuleMetricQueueWaitP95,
+	}
+}
+
 type BuildReason string
 
 const (
@@ -871,6 +1063,7 @@ const (
 	NotificationMessageStatusTemporaryFailure NotificationMessageStatus = "temporary_failure"
 	NotificationMessageStatusUnknown          NotificationMessageStatus = "unknown"
 	NotificationMessageStatusInhibited        NotificationMessageStatus = "inhibited"
+	NotificationMessageStatusBatched          NotificationMessageStatus = "batched"
 )
 
 func (e *NotificationMessageStatus) Scan(src interface{}) error {
@@ -916,7 +1109,8 @@ func (e NotificationMessageStatus) Valid() bool {
 		NotificationMessageStatusPermanentFailure,
 		NotificationMessageStatusTemporaryFailure,
 		NotificationMessageStatusUnknown,
-		NotificationMessageStatusInhibited:
+		NotificationMessageStatusInhibited,
+		NotificationMessageStatusBatched:
 		return true
 	}
 	return false
@@ -931,6 +1125,7 @@ func AllNotificationMessageStatusValues() []NotificationMessageStatus {
 		NotificationMessageStatusTemporaryFailure,
 		NotificationMessageStatusUnknown,
 		NotificationMessageStatusInhibited,
+		NotificationMessageStatusBatched,
 	}
 }
 
@@ -1867,6 +2062,64 @@ func AllProvisionerTypeValues() []ProvisionerType {
 	}
 }
 
+type ReleaseChannel string
+
+const (
+	ReleaseChannelStable ReleaseChannel = "stable"
+	ReleaseChannelBeta   ReleaseChannel = "beta"
+)
+
+func (e *ReleaseChannel) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReleaseChannel(s)
+	case string:
+		*e = ReleaseChannel(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReleaseChannel: %T", src)
+	}
+	return nil
+}
+
+type NullReleaseChannel struct {
+	ReleaseChannel ReleaseChannel `json:"release_channel"`
+	Valid          bool           `json:"valid"` // Valid is true if ReleaseChannel is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReleaseChannel) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReleaseChannel, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReleaseChannel.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReleaseChannel) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReleaseChannel), nil
+}
+
+func (e ReleaseChannel) Valid() bool {
+	switch e {
+	case ReleaseChannelStable,
+		ReleaseChannelBeta:
+		return true
+	}
+	return false
+}
+
+func AllReleaseChannelValues() []ReleaseChannel {
+	return []ReleaseChannel{
+		ReleaseChannelStable,
+		ReleaseChannelBeta,
+	}
+}
+
 type ResourceType string
 
 const (
@@ -1894,6 +2147,10 @@ const (
 	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
 	ResourceTypeWorkspaceAgent              ResourceType = "workspace_agent"
 	ResourceTypeWorkspaceApp                ResourceType = "workspace_app"
+	ResourceTypeReadOnlySettings            ResourceType = "read_only_settings"
+	ResourceTypeProvisionerBuildPause       ResourceType = "provisioner_build_pause"
+	ResourceTypeAccessRequest               ResourceType = "access_request"
+	ResourceTypeLoginDeviceCode             ResourceType = "login_device_code"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1956,7 +2213,11 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeIdpSyncSettingsGroup,
 		ResourceTypeIdpSyncSettingsRole,
 		ResourceTypeWorkspaceAgent,
-		ResourceTypeWorkspaceApp:
+		ResourceTypeWorkspaceApp,
+		ResourceTypeReadOnlySettings,
+		ResourceTypeProvisionerBuildPause,
+		ResourceTypeAccessRequest,
+		ResourceTypeLoginDeviceCode:
 		return true
 	}
 	return false
@@ -1988,6 +2249,10 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeIdpSyncSettingsRole,
 		ResourceTypeWorkspaceAgent,
 		ResourceTypeWorkspaceApp,
+		ResourceTypeReadOnlySettings,
+		ResourceTypeProvisionerBuildPause,
+		ResourceTypeAccessRequest,
+		ResourceTypeLoginDeviceCode,
 	}
 }
 
@@ -2108,198 +2373,376 @@ func AllTailnetStatusValues() []TailnetStatus {
 }
 
 // Defines the users status: active, dormant, or suspended.
-type UserStatus string
+type UserActivityExportFormat string
 
 const (
-	UserStatusActive    UserStatus = "active"
-	UserStatusSuspended UserStatus = "suspended"
-	UserStatusDormant   UserStatus = "dormant"
+	UserActivityExportFormatCsv     UserActivityExportFormat = "csv"
+	UserActivityExportFormatParquet UserActivityExportFormat = "parquet"
 )
 
-func (e *UserStatus) Scan(src interface{}) error {
+func (e *UserActivityExportFormat) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = UserStatus(s)
+		*e = UserActivityExportFormat(s)
 	case string:
-		*e = UserStatus(s)
+		*e = UserActivityExportFormat(s)
 	default:
-		return fmt.Errorf("unsupported scan type for UserStatus: %T", src)
+		return fmt.Errorf("unsupported scan type for UserActivityExportFormat: %T", src)
 	}
 	return nil
 }
 
-type NullUserStatus struct {
-	UserStatus UserStatus `json:"user_status"`
-	Valid      bool       `json:"valid"` // Valid is true if UserStatus is not NULL
+type NullUserActivityExportFormat struct {
+	UserActivityExportFormat UserActivityExportFormat `json:"user_activity_export_format"`
+	Valid                    bool                     `json:"valid"` // Valid is true if UserActivityExportFormat is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullUserStatus) Scan(value interface{}) error {
+func (ns *NullUserActivityExportFormat) Scan(value interface{}) error {
 	if value == nil {
-		ns.UserStatus, ns.Valid = "", false
+		ns.UserActivityExportFormat, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.UserStatus.Scan(value)
+	return ns.UserActivityExportFormat.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullUserStatus) Value() (driver.Value, error) {
+func (ns NullUserActivityExportFormat) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.UserStatus), nil
+	return string(ns.UserActivityExportFormat), nil
 }
 
-func (e UserStatus) Valid() bool {
+func (e UserActivityExportFormat) Valid() bool {
 	switch e {
-	case UserStatusActive,
-		UserStatusSuspended,
-		UserStatusDormant:
+	case UserActivityExportFormatCsv,
+		UserActivityExportFormatParquet:
 		return true
 	}
 	return false
 }
 
-func AllUserStatusValues() []UserStatus {
-	return []UserStatus{
-		UserStatusActive,
-		UserStatusSuspended,
-		UserStatusDormant,
+func AllUserActivityExportFormatValues() []UserActivityExportFormat {
+	return []UserActivityExportFormat{
+		UserActivityExportFormatCsv,
+		UserActivityExportFormatParquet,
 	}
 }
 
-type WorkspaceAgentLifecycleState string
+type UserActivityExportStatus string
 
 const (
-	WorkspaceAgentLifecycleStateCreated         WorkspaceAgentLifecycleState = "created"
-	WorkspaceAgentLifecycleStateStarting        WorkspaceAgentLifecycleState = "starting"
-	WorkspaceAgentLifecycleStateStartTimeout    WorkspaceAgentLifecycleState = "start_timeout"
-	WorkspaceAgentLifecycleStateStartError      WorkspaceAgentLifecycleState = "start_error"
-	WorkspaceAgentLifecycleStateReady           WorkspaceAgentLifecycleState = "ready"
-	WorkspaceAgentLifecycleStateShuttingDown    WorkspaceAgentLifecycleState = "shutting_down"
-	WorkspaceAgentLifecycleStateShutdownTimeout WorkspaceAgentLifecycleState = "shutdown_timeout"
-	WorkspaceAgentLifecycleStateShutdownError   WorkspaceAgentLifecycleState = "shutdown_error"
-	WorkspaceAgentLifecycleStateOff             WorkspaceAgentLifecycleState = "off"
+	UserActivityExportStatusPending   UserActivityExportStatus = "pending"
+	UserActivityExportStatusCompleted UserActivityExportStatus = "completed"
+	UserActivityExportStatusFailed    UserActivityExportStatus = "failed"
 )
 
-func (e *WorkspaceAgentLifecycleState) Scan(src interface{}) error {
+func (e *UserActivityExportStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = WorkspaceAgentLifecycleState(s)
+		*e = UserActivityExportStatus(s)
 	case string:
-		*e = WorkspaceAgentLifecycleState(s)
+		*e = UserActivityExportStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for WorkspaceAgentLifecycleState: %T", src)
+		return fmt.Errorf("unsupported scan type for UserActivityExportStatus: %T", src)
 	}
 	return nil
 }
 
-type NullWorkspaceAgentLifecycleState struct {
-	WorkspaceAgentLifecycleState WorkspaceAgentLifecycleState `json:"workspace_agent_lifecycle_state"`
-	Valid                        bool                         `json:"valid"` // Valid is true if WorkspaceAgentLifecycleState is not NULL
+type NullUserActivityExportStatus struct {
+	UserActivityExportStatus UserActivityExportStatus `json:"user_activity_export_status"`
+	Valid                    bool                     `json:"valid"` // Valid is true if UserActivityExportStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullWorkspaceAgentLifecycleState) Scan(value interface{}) error {
+func (ns *NullUserActivityExportStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.WorkspaceAgentLifecycleState, ns.Valid = "", false
+		ns.UserActivityExportStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.WorkspaceAgentLifecycleState.Scan(value)
+	return ns.UserActivityExportStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullWorkspaceAgentLifecycleState) Value() (driver.Value, error) {
+func (ns NullUserActivityExportStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.WorkspaceAgentLifecycleState), nil
+	return string(ns.UserActivityExportStatus), nil
 }
 
-func (e WorkspaceAgentLifecycleState) Valid() bool {
+func (e UserActivityExportStatus) Valid() bool {
 	switch e {
-	case WorkspaceAgentLifecycleStateCreated,
-		WorkspaceAgentLifecycleStateStarting,
-		WorkspaceAgentLifecycleStateStartTimeout,
-		WorkspaceAgentLifecycleStateStartError,
-		WorkspaceAgentLifecycleStateReady,
-		WorkspaceAgentLifecycleStateShuttingDown,
-		WorkspaceAgentLifecycleStateShutdownTimeout,
-		WorkspaceAgentLifecycleStateShutdownError,
-		WorkspaceAgentLifecycleStateOff:
+	case UserActivityExportStatusPending,
+		UserActivityExportStatusCompleted,
+		UserActivityExportStatusFailed:
 		return true
 	}
 	return false
 }
 
-func AllWorkspaceAgentLifecycleStateValues() []WorkspaceAgentLifecycleState {
-	return []WorkspaceAgentLifecycleState{
-		WorkspaceAgentLifecycleStateCreated,
-		WorkspaceAgentLifecycleStateStarting,
-		WorkspaceAgentLifecycleStateStartTimeout,
-		WorkspaceAgentLifecycleStateStartError,
-		WorkspaceAgentLifecycleStateReady,
-		WorkspaceAgentLifecycleStateShuttingDown,
-		WorkspaceAgentLifecycleStateShutdownTimeout,
-		WorkspaceAgentLifecycleStateShutdownError,
-		WorkspaceAgentLifecycleStateOff,
+func AllUserActivityExportStatusValues() []UserActivityExportStatus {
+	return []UserActivityExportStatus{
+		UserActivityExportStatusPending,
+		UserActivityExportStatusCompleted,
+		UserActivityExportStatusFailed,
 	}
 }
 
-type WorkspaceAgentMonitorState string
+// Defines the users status: active, dormant, or suspended.
+type UserStatus string
 
 const (
-	WorkspaceAgentMonitorStateOK  WorkspaceAgentMonitorState = "OK"
-	WorkspaceAgentMonitorStateNOK WorkspaceAgentMonitorState = "NOK"
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+	UserStatusDormant   UserStatus = "dormant"
 )
 
-func (e *WorkspaceAgentMonitorState) Scan(src interface{}) error {
+func (e *UserStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = WorkspaceAgentMonitorState(s)
+		*e = UserStatus(s)
 	case string:
-		*e = WorkspaceAgentMonitorState(s)
+		*e = UserStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for WorkspaceAgentMonitorState: %T", src)
+		return fmt.Errorf("unsupported scan type for UserStatus: %T", src)
 	}
 	return nil
 }
 
-type NullWorkspaceAgentMonitorState struct {
-	WorkspaceAgentMonitorState WorkspaceAgentMonitorState `json:"workspace_agent_monitor_state"`
-	Valid                      bool                       `json:"valid"` // Valid is true if WorkspaceAgentMonitorState is not NULL
+type NullUserStatus struct {
+	UserStatus UserStatus `json:"user_status"`
+	Valid      bool       `json:"valid"` // Valid is true if UserStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullWorkspaceAgentMonitorState) Scan(value interface{}) error {
+func (ns *NullUserStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.WorkspaceAgentMonitorState, ns.Valid = "", false
+		ns.UserStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.WorkspaceAgentMonitorState.Scan(value)
+	return ns.UserStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullWorkspaceAgentMonitorState) Value() (driver.Value, error) {
+func (ns NullUserStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.WorkspaceAgentMonitorState), nil
+	return string(ns.UserStatus), nil
 }
 
-func (e WorkspaceAgentMonitorState) Valid() bool {
+func (e UserStatus) Valid() bool {
 	switch e {
-	case WorkspaceAgentMonitorStateOK,
-		WorkspaceAgentMonitorStateNOK:
+	case UserStatusActive,
+		UserStatusSuspended,
+		UserStatusDormant:
 		return true
 	}
 	return false
 }
 
-func AllWorkspaceAgentMonitorStateValues() []WorkspaceAgentMonitorState {
+func AllUserStatusValues() []UserStatus {
+	return []UserStatus{
+		UserStatusActive,
+		UserStatusSuspended,
+		UserStatusDormant,
+	}
+}
+
+type WorkspaceAgentHealthEventKind string
+
+const (
+	WorkspaceAgentHealthEventKindNearOutOfMemory WorkspaceAgentHealthEventKind = "near_out_of_memory"
+	WorkspaceAgentHealthEventKindLowDiskSpace    WorkspaceAgentHealthEventKind = "low_disk_space"
+)
+
+func (e *WorkspaceAgentHealthEventKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentHealthEventKind(s)
+	case string:
+		*e = WorkspaceAgentHealthEventKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentHealthEventKind: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentHealthEventKind struct {
+	WorkspaceAgentHealthEventKind WorkspaceAgentHealthEventKind `json:"workspace_agent_health_event_kind"`
+	Valid                         bool                          `json:"valid"` // Valid is true if WorkspaceAgentHealthEventKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentHealthEventKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentHealthEventKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentHealthEventKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentHealthEventKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentHealthEventKind), nil
+}
+
+func (e WorkspaceAgentHealthEventKind) Valid() bool {
+	switch e {
+	case WorkspaceAgentHealthEventKindNearOutOfMemory,
+		WorkspaceAgentHealthEventKindLowDiskSpace:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentHealthEventKindValues() []WorkspaceAgentHealthEventKind {
+	return []WorkspaceAgentHealthEventKind{
+		WorkspaceAgentHealthEventKindNearOutOfMemory,
+		WorkspaceAgentHealthEventKindLowDiskSpace,
+	}
+}
+
+type WorkspaceAgentLifecycleState string
+
+const (
+	WorkspaceAgentLifecycleStateCreated         WorkspaceAgentLifecycleState = "created"
+	WorkspaceAgentLifecycleStateStarting        WorkspaceAgentLifecycleState = "starting"
+	WorkspaceAgentLifecycleStateStartTimeout    WorkspaceAgentLifecycleState = "start_timeout"
+	WorkspaceAgentLifecycleStateStartError      WorkspaceAgentLifecycleState = "start_error"
+	WorkspaceAgentLifecycleStateReady           WorkspaceAgentLifecycleState = "ready"
+	WorkspaceAgentLifecycleStateShuttingDown    WorkspaceAgentLifecycleState = "shutting_down"
+	WorkspaceAgentLifecycleStateShutdownTimeout WorkspaceAgentLifecycleState = "shutdown_timeout"
+	WorkspaceAgentLifecycleStateShutdownError   WorkspaceAgentLifecycleState = "shutdown_error"
+	WorkspaceAgentLifecycleStateOff             WorkspaceAgentLifecycleState = "off"
+)
+
+func (e *WorkspaceAgentLifecycleState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentLifecycleState(s)
+	case string:
+		*e = WorkspaceAgentLifecycleState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentLifecycleState: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentLifecycleState struct {
+	WorkspaceAgentLifecycleState WorkspaceAgentLifecycleState `json:"workspace_agent_lifecycle_state"`
+	Valid                        bool                         `json:"valid"` // Valid is true if WorkspaceAgentLifecycleState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentLifecycleState) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentLifecycleState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentLifecycleState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentLifecycleState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentLifecycleState), nil
+}
+
+func (e WorkspaceAgentLifecycleState) Valid() bool {
+	switch e {
+	case WorkspaceAgentLifecycleStateCreated,
+		WorkspaceAgentLifecycleStateStarting,
+		WorkspaceAgentLifecycleStateStartTimeout,
+		WorkspaceAgentLifecycleStateStartError,
+		WorkspaceAgentLifecycleStateReady,
+		WorkspaceAgentLifecycleStateShuttingDown,
+		WorkspaceAgentLifecycleStateShutdownTimeout,
+		WorkspaceAgentLifecycleStateShutdownError,
+		WorkspaceAgentLifecycleStateOff:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentLifecycleStateValues() []WorkspaceAgentLifecycleState {
+	return []WorkspaceAgentLifecycleState{
+		WorkspaceAgentLifecycleStateCreated,
+		WorkspaceAgentLifecycleStateStarting,
+		WorkspaceAgentLifecycleStateStartTimeout,
+		WorkspaceAgentLifecycleStateStartError,
+		WorkspaceAgentLifecycleStateReady,
+		WorkspaceAgentLifecycleStateShuttingDown,
+		WorkspaceAgentLifecycleStateShutdownTimeout,
+		WorkspaceAgentLifecycleStateShutdownError,
+		WorkspaceAgentLifecycleStateOff,
+	}
+}
+
+type WorkspaceAgentMonitorState string
+
+const (
+	WorkspaceAgentMonitorStateOK  WorkspaceAgentMonitorState = "OK"
+	WorkspaceAgentMonitorStateNOK WorkspaceAgentMonitorState = "NOK"
+)
+
+func (e *WorkspaceAgentMonitorState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentMonitorState(s)
+	case string:
+		*e = WorkspaceAgentMonitorState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentMonitorState: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentMonitorState struct {
+	WorkspaceAgentMonitorState WorkspaceAgentMonitorState `json:"workspace_agent_monitor_state"`
+	Valid                      bool                       `json:"valid"` // Valid is true if WorkspaceAgentMonitorState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentMonitorState) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentMonitorState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentMonitorState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentMonitorState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentMonitorState), nil
+}
+
+func (e WorkspaceAgentMonitorState) Valid() bool {
+	switch e {
+	case WorkspaceAgentMonitorStateOK,
+		WorkspaceAgentMonitorStateNOK:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentMonitorStateValues() []WorkspaceAgentMonitorState {
 	return []WorkspaceAgentMonitorState{
 		WorkspaceAgentMonitorStateOK,
 		WorkspaceAgentMonitorStateNOK,
@@ -2686,6 +3129,189 @@ func AllWorkspaceAppStatusStateValues() []WorkspaceAppStatusState {
 	}
 }
 
+type WorkspaceMigrationStatus string
+
+const (
+	WorkspaceMigrationStatusPending   WorkspaceMigrationStatus = "pending"
+	WorkspaceMigrationStatusStopping  WorkspaceMigrationStatus = "stopping"
+	WorkspaceMigrationStatusStarting  WorkspaceMigrationStatus = "starting"
+	WorkspaceMigrationStatusSucceeded WorkspaceMigrationStatus = "succeeded"
+	WorkspaceMigrationStatusFailed    WorkspaceMigrationStatus = "failed"
+)
+
+func (e *WorkspaceMigrationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceMigrationStatus(s)
+	case string:
+		*e = WorkspaceMigrationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceMigrationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceMigrationStatus struct {
+	WorkspaceMigrationStatus WorkspaceMigrationStatus `json:"workspace_migration_status"`
+	Valid                    bool                     `json:"valid"` // Valid is true if WorkspaceMigrationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceMigrationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceMigrationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceMigrationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceMigrationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceMigrationStatus), nil
+}
+
+func (e WorkspaceMigrationStatus) Valid() bool {
+	switch e {
+	case WorkspaceMigrationStatusPending,
+		WorkspaceMigrationStatusStopping,
+		WorkspaceMigrationStatusStarting,
+		WorkspaceMigrationStatusSucceeded,
+		WorkspaceMigrationStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceMigrationStatusValues() []WorkspaceMigrationStatus {
+	return []WorkspaceMigrationStatus{
+		WorkspaceMigrationStatusPending,
+		WorkspaceMigrationStatusStopping,
+		WorkspaceMigrationStatusStarting,
+		WorkspaceMigrationStatusSucceeded,
+		WorkspaceMigrationStatusFailed,
+	}
+}
+
+type WorkspaceNameUniquenessScope string
+
+const (
+	WorkspaceNameUniquenessScopeOwner        WorkspaceNameUniquenessScope = "owner"
+	WorkspaceNameUniquenessScopeOrganization WorkspaceNameUniquenessScope = "organization"
+)
+
+func (e *WorkspaceNameUniquenessScope) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceNameUniquenessScope(s)
+	case string:
+		*e = WorkspaceNameUniquenessScope(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceNameUniquenessScope: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceNameUniquenessScope struct {
+	WorkspaceNameUniquenessScope WorkspaceNameUniquenessScope `json:"workspace_name_uniqueness_scope"`
+	Valid                        bool                         `json:"valid"` // Valid is true if WorkspaceNameUniquenessScope is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceNameUniquenessScope) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceNameUniquenessScope, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceNameUniquenessScope.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceNameUniquenessScope) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceNameUniquenessScope), nil
+}
+
+func (e WorkspaceNameUniquenessScope) Valid() bool {
+	switch e {
+	case WorkspaceNameUniquenessScopeOwner,
+		WorkspaceNameUniquenessScopeOrganization:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceNameUniquenessScopeValues() []WorkspaceNameUniquenessScope {
+	return []WorkspaceNameUniquenessScope{
+		WorkspaceNameUniquenessScopeOwner,
+		WorkspaceNameUniquenessScopeOrganization,
+	}
+}
+
+type WorkspaceSessionRecordingType string
+
+const (
+	WorkspaceSessionRecordingTypeSsh             WorkspaceSessionRecordingType = "ssh"
+	WorkspaceSessionRecordingTypeReconnectingPty WorkspaceSessionRecordingType = "reconnecting_pty"
+)
+
+func (e *WorkspaceSessionRecordingType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceSessionRecordingType(s)
+	case string:
+		*e = WorkspaceSessionRecordingType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceSessionRecordingType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceSessionRecordingType struct {
+	WorkspaceSessionRecordingType WorkspaceSessionRecordingType `json:"workspace_session_recording_type"`
+	Valid                         bool                          `json:"valid"` // Valid is true if WorkspaceSessionRecordingType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceSessionRecordingType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceSessionRecordingType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceSessionRecordingType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceSessionRecordingType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceSessionRecordingType), nil
+}
+
+func (e WorkspaceSessionRecordingType) Valid() bool {
+	switch e {
+	case WorkspaceSessionRecordingTypeSsh,
+		WorkspaceSessionRecordingTypeReconnectingPty:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceSessionRecordingTypeValues() []WorkspaceSessionRecordingType {
+	return []WorkspaceSessionRecordingType{
+		WorkspaceSessionRecordingTypeSsh,
+		WorkspaceSessionRecordingTypeReconnectingPty,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -2761,6 +3387,29 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// bound_identity is the identity of the client the key is bound to, e.g. the fingerprint of a client certificate or a device ID. Empty if the key is not bound. Requests using a bound key must present the same identity.
+	BoundIdentity string `db:"bound_identity" json:"bound_identity"`
+}
+
+// Requests for time-boxed access to a workspace or template. Approved requests grant the role through the ACL of the resource until they expire.
+type AccessRequest struct {
+	ID             uuid.UUID                 `db:"id" json:"id"`
+	OrganizationID uuid.UUID                 `db:"organization_id" json:"organization_id"`
+	RequesterID    uuid.UUID                 `db:"requester_id" json:"requester_id"`
+	ResourceType   AccessRequestResourceType `db:"resource_type" json:"resource_type"`
+	ResourceID     uuid.UUID                 `db:"resource_id" json:"resource_id"`
+	// The requested role, a workspace role for workspaces and a template role for templates.
+	Role       string              `db:"role" json:"role"`
+	Reason     string              `db:"reason" json:"reason"`
+	DurationMs int64               `db:"duration_ms" json:"duration_ms"`
+	Status     AccessRequestStatus `db:"status" json:"status"`
+	// The role the requester had on the resource when the request was approved. It is restored when the request expires.
+	PreviousRole string        `db:"previous_role" json:"previous_role"`
+	ReviewedBy   uuid.NullUUID `db:"reviewed_by" json:"reviewed_by"`
+	ReviewedAt   sql.NullTime  `db:"reviewed_at" json:"reviewed_at"`
+	// Set when the request is approved. The granted role is revoked after this time.
+	ExpiresAt sql.NullTime `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
 }
 
 type AuditLog struct {
@@ -2781,6 +3430,44 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+type AuditLogsDefault struct {
+	ID               uuid.UUID       `db:"id" json:"id"`
+	Time             time.Time       `db:"time" json:"time"`
+	UserID           uuid.UUID       `db:"user_id" json:"user_id"`
+	OrganizationID   uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Ip               pqtype.Inet     `db:"ip" json:"ip"`
+	UserAgent        sql.NullString  `db:"user_agent" json:"user_agent"`
+	ResourceType     ResourceType    `db:"resource_type" json:"resource_type"`
+	ResourceID       uuid.UUID       `db:"resource_id" json:"resource_id"`
+	ResourceTarget   string          `db:"resource_target" json:"resource_target"`
+	Action           AuditAction     `db:"action" json:"action"`
+	Diff             json.RawMessage `db:"diff" json:"diff"`
+	StatusCode       int32           `db:"status_code" json:"status_code"`
+	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
+	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
+	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
+}
+
+// Rules over workspace build metrics that notify template admins when breached.
+type BuildAlertRule struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// Restricts the rule to builds of a single template. If NULL, the rule covers all templates of the organization.
+	TemplateID uuid.NullUUID        `db:"template_id" json:"template_id"`
+	Name       string               `db:"name" json:"name"`
+	Metric     BuildAlertRuleMetric `db:"metric" json:"metric"`
+	// The rule is breached when the metric is above this value. Percent for failure_rate, milliseconds for queue_wait_p95.
+	Threshold       float64         `db:"threshold" json:"threshold"`
+	WindowMs        int64           `db:"window_ms" json:"window_ms"`
+	CreatedBy       uuid.NullUUID   `db:"created_by" json:"created_by"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+	LastEvaluatedAt sql.NullTime    `db:"last_evaluated_at" json:"last_evaluated_at"`
+	LastValue       sql.NullFloat64 `db:"last_value" json:"last_value"`
+	// Set when the rule became breached, and cleared once the metric is back under the threshold. Notifications are only sent when it is set.
+	BreachedAt sql.NullTime `db:"breached_at" json:"breached_at"`
+}
+
 type CryptoKey struct {
 	Feature     CryptoKeyFeature `db:"feature" json:"feature"`
 	Sequence    int32            `db:"sequence" json:"sequence"`
@@ -2863,6 +3550,8 @@ type Group struct {
 	DisplayName string `db:"display_name" json:"display_name"`
 	// Source indicates how the group was created. It can be created by a user manually, or through some system process like OIDC group sync.
 	Source GroupSource `db:"source" json:"source"`
+	// Credits the members of the group may spend on workspaces each calendar month (UTC), accrued from the daily cost of their workspaces by the hour. 0 means the group adds no monthly budget.
+	MonthlyBudget int32 `db:"monthly_budget" json:"monthly_budget"`
 }
 
 // Joins group members with user information, organization ID, group name. Includes both regular group members and organization members (as part of the "Everyone" group).
@@ -2924,6 +3613,19 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// Pending device authorizations of the CLI. A device polls with its device code until a user signed in on another device approves the user code, and then receives a session token for that user.
+type LoginDeviceCode struct {
+	ID uuid.UUID `db:"id" json:"id"`
+	// SHA-256 hash of the secret device code the device polls with.
+	DeviceCodeHash []byte `db:"device_code_hash" json:"device_code_hash"`
+	// The short code the user enters to approve the device, without separators.
+	UserCode string `db:"user_code" json:"user_code"`
+	// The user that approved the device, or null if it has not been approved yet.
+	UserID    uuid.NullUUID `db:"user_id" json:"user_id"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time     `db:"expires_at" json:"expires_at"`
+}
+
 type NotificationMessage struct {
 	ID                     uuid.UUID                 `db:"id" json:"id"`
 	NotificationTemplateID uuid.UUID                 `db:"notification_template_id" json:"notification_template_id"`
@@ -2950,6 +3652,8 @@ type NotificationPreference struct {
 	Disabled               bool      `db:"disabled" json:"disabled"`
 	CreatedAt              time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt              time.Time `db:"updated_at" json:"updated_at"`
+	// Whether the notifications of this template are batched into a periodic digest instead of being delivered immediately
+	Digest bool `db:"digest" json:"digest"`
 }
 
 // Log of generated reports for users.
@@ -3025,6 +3729,8 @@ type Organization struct {
 	DisplayName string    `db:"display_name" json:"display_name"`
 	Icon        string    `db:"icon" json:"icon"`
 	Deleted     bool      `db:"deleted" json:"deleted"`
+	// Cost center the organization is billed to. Available to workspace naming policies as {{.CostCenter}}.
+	CostCenter string `db:"cost_center" json:"cost_center"`
 }
 
 type OrganizationMember struct {
@@ -3067,6 +3773,16 @@ type ParameterValue struct {
 	DestinationScheme ParameterDestinationScheme `db:"destination_scheme" json:"destination_scheme"`
 }
 
+// Provisioner jobs in the scope of a pause are accepted but held pending: provisioners do not acquire them and the job reaper does not time them out. Used during planned infrastructure maintenance.
+type ProvisionerBuildPause struct {
+	ID uuid.UUID `db:"id" json:"id"`
+	// The organization whose builds are paused. NULL pauses builds across the deployment.
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	Reason         string        `db:"reason" json:"reason"`
+	CreatedBy      uuid.NullUUID `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+}
+
 type ProvisionerDaemon struct {
 	ID           uuid.UUID         `db:"id" json:"id"`
 	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
@@ -3113,6 +3829,27 @@ type ProvisionerJobLog struct {
 	Stage     string    `db:"stage" json:"stage"`
 	Output    string    `db:"output" json:"output"`
 	ID        int64     `db:"id" json:"id"`
+	// Structured fields extracted from machine-readable provisioner output, such as the Terraform resource address, action and diagnostic severity.
+	Fields json.RawMessage `db:"fields" json:"fields"`
+}
+
+// Provisioner job logs that were moved out of provisioner_job_logs into a compressed file after the retention period.
+type ProvisionerJobLogArchive struct {
+	JobID      uuid.UUID `db:"job_id" json:"job_id"`
+	FileID     uuid.UUID `db:"file_id" json:"file_id"`
+	LogCount   int32     `db:"log_count" json:"log_count"`
+	ArchivedAt time.Time `db:"archived_at" json:"archived_at"`
+}
+
+type ProvisionerJobLogsDefault struct {
+	JobID     uuid.UUID       `db:"job_id" json:"job_id"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	Source    LogSource       `db:"source" json:"source"`
+	Level     LogLevel        `db:"level" json:"level"`
+	Stage     string          `db:"stage" json:"stage"`
+	Output    string          `db:"output" json:"output"`
+	ID        int64           `db:"id" json:"id"`
+	Fields    json.RawMessage `db:"fields" json:"fields"`
 }
 
 type ProvisionerJobStat struct {
@@ -3149,6 +3886,27 @@ type ProvisionerKey struct {
 	Name           string    `db:"name" json:"name"`
 	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
 	Tags           StringMap `db:"tags" json:"tags"`
+	// The last time a provisioner daemon authenticated with this key.
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	// When set, the key can no longer be used to authenticate after this time.
+	ExpiresAt sql.NullTime `db:"expires_at" json:"expires_at"`
+	// The hashed secret that was replaced by the most recent rotation. It remains valid until previous_secret_expires_at so that daemons can be migrated to the new secret without downtime.
+	PreviousHashedSecret []byte `db:"previous_hashed_secret" json:"previous_hashed_secret"`
+	// The end of the rotation overlap window for previous_hashed_secret.
+	PreviousSecretExpiresAt sql.NullTime `db:"previous_secret_expires_at" json:"previous_secret_expires_at"`
+}
+
+// Provisioner capacity held back for jobs with a specific tag set during a time window, e.g. for scheduled trainings.
+type ProvisionerReservation struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// Jobs whose tags contain these tags may use the reserved slots. Provisioners whose tags contain these tags provide the slots.
+	Tags StringMap `db:"tags" json:"tags"`
+	// Number of concurrent jobs that provisioners able to serve the reservation keep available for reserved jobs.
+	Slots     int32     `db:"slots" json:"slots"`
+	StartsAt  time.Time `db:"starts_at" json:"starts_at"`
+	EndsAt    time.Time `db:"ends_at" json:"ends_at"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
 
 type Replica struct {
@@ -3166,6 +3924,16 @@ type Replica struct {
 	Primary         bool         `db:"primary" json:"primary"`
 }
 
+// Users that are used for automation rather than by people. They belong to a single organization, have no way to log in and authenticate with tokens that organization admins manage.
+type ServiceAccount struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Description    string    `db:"description" json:"description"`
+	// The user that created the service account.
+	CreatedBy uuid.NullUUID `db:"created_by" json:"created_by"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -3222,41 +3990,64 @@ type TelemetryItem struct {
 
 // Joins in the display name information such as username, avatar, and organization name.
 type Template struct {
-	ID                            uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                     time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt                     time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID                uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Deleted                       bool            `db:"deleted" json:"deleted"`
-	Name                          string          `db:"name" json:"name"`
-	Provisioner                   ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID               uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description                   string          `db:"description" json:"description"`
-	DefaultTTL                    int64           `db:"default_ttl" json:"default_ttl"`
-	CreatedBy                     uuid.UUID       `db:"created_by" json:"created_by"`
-	Icon                          string          `db:"icon" json:"icon"`
-	UserACL                       TemplateACL     `db:"user_acl" json:"user_acl"`
-	GroupACL                      TemplateACL     `db:"group_acl" json:"group_acl"`
-	DisplayName                   string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs  bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	AllowUserAutostart            bool            `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop             bool            `db:"allow_user_autostop" json:"allow_user_autostop"`
-	FailureTTL                    int64           `db:"failure_ttl" json:"failure_ttl"`
-	TimeTilDormant                int64           `db:"time_til_dormant" json:"time_til_dormant"`
-	TimeTilDormantAutoDelete      int64           `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
-	AutostopRequirementDaysOfWeek int16           `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
-	AutostopRequirementWeeks      int64           `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
-	AutostartBlockDaysOfWeek      int16           `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
-	RequireActiveVersion          bool            `db:"require_active_version" json:"require_active_version"`
-	Deprecated                    string          `db:"deprecated" json:"deprecated"`
-	ActivityBump                  int64           `db:"activity_bump" json:"activity_bump"`
-	MaxPortSharingLevel           AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
-	UseClassicParameterFlow       bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
-	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
-	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
-	CreatedByName                 string          `db:"created_by_name" json:"created_by_name"`
-	OrganizationName              string          `db:"organization_name" json:"organization_name"`
-	OrganizationDisplayName       string          `db:"organization_display_name" json:"organization_display_name"`
-	OrganizationIcon              string          `db:"organization_icon" json:"organization_icon"`
+	ID                            uuid.UUID                `db:"id" json:"id"`
+	CreatedAt                     time.Time                `db:"created_at" json:"created_at"`
+	UpdatedAt                     time.Time                `db:"updated_at" json:"updated_at"`
+	OrganizationID                uuid.UUID                `db:"organization_id" json:"organization_id"`
+	Deleted                       bool                     `db:"deleted" json:"deleted"`
+	Name                          string                   `db:"name" json:"name"`
+	Provisioner                   ProvisionerType          `db:"provisioner" json:"provisioner"`
+	ActiveVersionID               uuid.UUID                `db:"active_version_id" json:"active_version_id"`
+	Description                   string                   `db:"description" json:"description"`
+	DefaultTTL                    int64                    `db:"default_ttl" json:"default_ttl"`
+	CreatedBy                     uuid.UUID                `db:"created_by" json:"created_by"`
+	Icon                          string                   `db:"icon" json:"icon"`
+	UserACL                       TemplateACL              `db:"user_acl" json:"user_acl"`
+	GroupACL                      TemplateACL              `db:"group_acl" json:"group_acl"`
+	DisplayName                   string                   `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs  bool                     `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	AllowUserAutostart            bool                     `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop             bool                     `db:"allow_user_autostop" json:"allow_user_autostop"`
+	FailureTTL                    int64                    `db:"failure_ttl" json:"failure_ttl"`
+	TimeTilDormant                int64                    `db:"time_til_dormant" json:"time_til_dormant"`
+	TimeTilDormantAutoDelete      int64                    `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
+	AutostopRequirementDaysOfWeek int16                    `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
+	AutostopRequirementWeeks      int64                    `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	AutostartBlockDaysOfWeek      int16                    `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
+	RequireActiveVersion          bool                     `db:"require_active_version" json:"require_active_version"`
+	Deprecated                    string                   `db:"deprecated" json:"deprecated"`
+	ActivityBump                  int64                    `db:"activity_bump" json:"activity_bump"`
+	MaxPortSharingLevel           AppSharingLevel          `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	UseClassicParameterFlow       bool                     `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	MaxConcurrentJobsPerUser      int32                    `db:"max_concurrent_jobs_per_user" json:"max_concurrent_jobs_per_user"`
+	ResourceCeilings              TemplateResourceCeilings `db:"resource_ceilings" json:"resource_ceilings"`
+	RequiredProvisionerTags       StringMap                `db:"required_provisioner_tags" json:"required_provisioner_tags"`
+	CreatedByAvatarURL            string                   `db:"created_by_avatar_url" json:"created_by_avatar_url"`
+	CreatedByUsername             string                   `db:"created_by_username" json:"created_by_username"`
+	CreatedByName                 string                   `db:"created_by_name" json:"created_by_name"`
+	OrganizationName              string                   `db:"organization_name" json:"organization_name"`
+	OrganizationDisplayName       string                   `db:"organization_display_name" json:"organization_display_name"`
+	OrganizationIcon              string                   `db:"organization_icon" json:"organization_icon"`
+	SharedOrganizationIDs         []uuid.UUID              `db:"shared_organization_ids" json:"shared_organization_ids"`
+}
+
+// Records aggregated durations of successful workspace builds per template and transition, in daily buckets.
+type TemplateBuildDurationStat struct {
+	// Start time of the period the builds completed in.
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	// End time of the period the builds completed in.
+	EndTime time.Time `db:"end_time" json:"end_time"`
+	// ID of the template the builds are of.
+	TemplateID uuid.UUID           `db:"template_id" json:"template_id"`
+	Transition WorkspaceTransition `db:"transition" json:"transition"`
+	// Number of successful builds that completed in the period.
+	BuildCount int32 `db:"build_count" json:"build_count"`
+	// Median build duration, in milliseconds.
+	P50DurationMs int64 `db:"p50_duration_ms" json:"p50_duration_ms"`
+	// 95th percentile build duration, in milliseconds.
+	P95DurationMs int64 `db:"p95_duration_ms" json:"p95_duration_ms"`
+	// Longest build duration, in milliseconds.
+	MaxDurationMs int64 `db:"max_duration_ms" json:"max_duration_ms"`
 }
 
 type TemplateTable struct {
@@ -3299,6 +4090,48 @@ type TemplateTable struct {
 	MaxPortSharingLevel AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
 	// Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.
 	UseClassicParameterFlow bool `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	// Maximum number of pending and running workspace builds of this template a single user may have at a time. 0 means unlimited.
+	MaxConcurrentJobsPerUser int32 `db:"max_concurrent_jobs_per_user" json:"max_concurrent_jobs_per_user"`
+	// Maximum CPU cores, memory, disk and daily cost the planned resources of a single workspace build may have. Ceilings that are unset or 0 are not enforced.
+	ResourceCeilings TemplateResourceCeilings `db:"resource_ceilings" json:"resource_ceilings"`
+	// Provisioner tags that the jobs of the template must target, so that they only run on provisioner daemons with these tags. A value of "*" requires the tag to be set to any value.
+	RequiredProvisionerTags StringMap `db:"required_provisioner_tags" json:"required_provisioner_tags"`
+}
+
+// Named bundles of parameter values that are managed at the template level, independently of template versions.
+type TemplatePreset struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	TemplateID  uuid.UUID `db:"template_id" json:"template_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	// Parameter values by parameter name. Parameters that a template version does not define are ignored.
+	Parameters StringMap `db:"parameters" json:"parameters"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// The template preset that members of a group get by default when they create a workspace without choosing a preset.
+type TemplatePresetGroupDefault struct {
+	TemplateID       uuid.UUID `db:"template_id" json:"template_id"`
+	GroupID          uuid.UUID `db:"group_id" json:"group_id"`
+	TemplatePresetID uuid.UUID `db:"template_preset_id" json:"template_preset_id"`
+}
+
+// Template versions published to named release channels. Builds of workspaces tracking a channel that target the active version use the version of the channel instead.
+type TemplateReleaseChannel struct {
+	TemplateID        uuid.UUID      `db:"template_id" json:"template_id"`
+	Channel           ReleaseChannel `db:"channel" json:"channel"`
+	TemplateVersionID uuid.UUID      `db:"template_version_id" json:"template_version_id"`
+	UpdatedBy         uuid.UUID      `db:"updated_by" json:"updated_by"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// Organizations that templates are shared into. Members of these organizations can read the template, but not modify it.
+type TemplateShare struct {
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	CreatedBy      uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
 }
 
 // Records aggregated usage statistics for templates/users. All usage is rounded up to the nearest minute.
@@ -3414,6 +4247,19 @@ type TemplateVersionPresetPrebuildSchedule struct {
 	DesiredInstances int32     `db:"desired_instances" json:"desired_instances"`
 }
 
+// Gradual promotions of template versions. Builds that target the active version of the template use the rollout version instead if their workspace falls within the percentage or its owner is a member of the group.
+type TemplateVersionRollout struct {
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	// The percentage of workspaces, bucketed by workspace ID, that build the rollout version.
+	Percent int32 `db:"percent" json:"percent"`
+	// The group whose members always build the rollout version, regardless of the percentage.
+	GroupID   uuid.NullUUID `db:"group_id" json:"group_id"`
+	CreatedBy uuid.UUID     `db:"created_by" json:"created_by"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
+}
+
 type TemplateVersionTable struct {
 	ID             uuid.UUID     `db:"id" json:"id"`
 	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
@@ -3458,6 +4304,8 @@ type TemplateVersionVariable struct {
 	Required bool `db:"required" json:"required"`
 	// Sensitive variables have their values redacted in logs or site UI
 	Sensitive bool `db:"sensitive" json:"sensitive"`
+	// The ID of the key used to encrypt the variable value. If this is NULL, the value is not encrypted
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
 }
 
 type TemplateVersionWorkspaceTag struct {
@@ -3493,6 +4341,23 @@ type User struct {
 	IsSystem bool `db:"is_system" json:"is_system"`
 }
 
+// Exports of the daily activity of each user, generated in the background for ingestion by external tools.
+type UserActivityExport struct {
+	ID        uuid.UUID                `db:"id" json:"id"`
+	CreatedBy uuid.UUID                `db:"created_by" json:"created_by"`
+	CreatedAt time.Time                `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time                `db:"updated_at" json:"updated_at"`
+	StartTime time.Time                `db:"start_time" json:"start_time"`
+	EndTime   time.Time                `db:"end_time" json:"end_time"`
+	Format    UserActivityExportFormat `db:"format" json:"format"`
+	Status    UserActivityExportStatus `db:"status" json:"status"`
+	// The file holding the export once it has completed.
+	FileID uuid.NullUUID `db:"file_id" json:"file_id"`
+	// Why the export failed, if it did.
+	Error       string       `db:"error" json:"error"`
+	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
+}
+
 type UserConfig struct {
 	UserID uuid.UUID `db:"user_id" json:"user_id"`
 	Key    string    `db:"key" json:"key"`
@@ -3564,6 +4429,8 @@ type Workspace struct {
 	AutomaticUpdates        AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 	Favorite                bool             `db:"favorite" json:"favorite"`
 	NextStartAt             sql.NullTime     `db:"next_start_at" json:"next_start_at"`
+	GroupACL                WorkspaceACL     `db:"group_acl" json:"group_acl"`
+	UserACL                 WorkspaceACL     `db:"user_acl" json:"user_acl"`
 	OwnerAvatarUrl          string           `db:"owner_avatar_url" json:"owner_avatar_url"`
 	OwnerUsername           string           `db:"owner_username" json:"owner_username"`
 	OwnerName               string           `db:"owner_name" json:"owner_name"`
@@ -3625,6 +4492,31 @@ type WorkspaceAgent struct {
 	APIKeyScope AgentKeyScopeEnum `db:"api_key_scope" json:"api_key_scope"`
 	// Indicates whether or not the agent has been deleted. This is currently only applicable to sub agents.
 	Deleted bool `db:"deleted" json:"deleted"`
+	// The name of the group the agent is displayed under in user interfaces. Empty means the agent is not grouped.
+	DisplayGroup string `db:"display_group" json:"display_group"`
+	// Whether the agent should be collapsed by default in user interfaces.
+	Collapsed bool `db:"collapsed" json:"collapsed"`
+}
+
+// Network quality of each peer connected to a workspace agent, as last reported in the agent stats.
+type WorkspaceAgentConnectionQuality struct {
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+	// Tailnet IP address of the peer. Every client connection uses its own address.
+	Ip string `db:"ip" json:"ip"`
+	// Whether the connection is direct. If false, it is relayed through DERP.
+	P2P bool `db:"p2p" json:"p2p"`
+	// DERP region the connection is relayed through. Empty for direct connections.
+	DerpRegionCode string `db:"derp_region_code" json:"derp_region_code"`
+	// Round trip time of the most recent successful ping. NULL if no ping was answered.
+	LatencyMS sql.NullFloat64 `db:"latency_ms" json:"latency_ms"`
+	// Number of pings the agent sent to the peer since it connected.
+	PingsSent int64 `db:"pings_sent" json:"pings_sent"`
+	// Number of pings sent to the peer that went unanswered.
+	PingsLost int64 `db:"pings_lost" json:"pings_lost"`
+	// Time of the last WireGuard handshake with the peer.
+	LastHandshakeAt sql.NullTime `db:"last_handshake_at" json:"last_handshake_at"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
 }
 
 // Workspace agent devcontainer configuration
@@ -3643,6 +4535,22 @@ type WorkspaceAgentDevcontainer struct {
 	Name string `db:"name" json:"name"`
 }
 
+// Conditions detected by workspace agents that are likely to cause failures, such as their host running out of memory.
+type WorkspaceAgentHealthEvent struct {
+	ID      uuid.UUID                     `db:"id" json:"id"`
+	AgentID uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Kind    WorkspaceAgentHealthEventKind `db:"kind" json:"kind"`
+	// Path of the volume that is low on space. Empty for memory events.
+	Volume string `db:"volume" json:"volume"`
+	// Amount of the resource in use when the condition was detected, in bytes.
+	Used int64 `db:"used" json:"used"`
+	// Amount of the resource available when the condition was detected, in bytes.
+	Total     int64     `db:"total" json:"total"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// Time the agent reported the condition no longer applies. NULL while it is ongoing.
+	ResolvedAt sql.NullTime `db:"resolved_at" json:"resolved_at"`
+}
+
 type WorkspaceAgentLog struct {
 	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
@@ -3690,6 +4598,37 @@ type WorkspaceAgentPortShare struct {
 	Port        int32             `db:"port" json:"port"`
 	ShareLevel  AppSharingLevel   `db:"share_level" json:"share_level"`
 	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
+	// Users that can access the port when the share level is restricted.
+	SharedWithUserIDs []uuid.UUID `db:"shared_with_user_ids" json:"shared_with_user_ids"`
+	// Groups whose members can access the port when the share level is restricted.
+	SharedWithGroupIDs []uuid.UUID `db:"shared_with_group_ids" json:"shared_with_group_ids"`
+	// When set, the port share is no longer honored after this time.
+	ExpiresAt sql.NullTime `db:"expires_at" json:"expires_at"`
+}
+
+// Host resource utilization reported by workspace agents, downsampled into fixed buckets.
+type WorkspaceAgentResourceUsage struct {
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+	// Start time of the bucket the samples were collected in.
+	BucketStart time.Time `db:"bucket_start" json:"bucket_start"`
+	// Number of samples aggregated into the bucket.
+	Samples int32 `db:"samples" json:"samples"`
+	// Average number of CPU cores in use.
+	CPUUsedAvg float64 `db:"cpu_used_avg" json:"cpu_used_avg"`
+	// Maximum number of CPU cores in use.
+	CPUUsedMax float64 `db:"cpu_used_max" json:"cpu_used_max"`
+	// Number of CPU cores available to the agent.
+	CPUTotal float64 `db:"cpu_total" json:"cpu_total"`
+	// Average memory in use, in bytes.
+	MemoryUsedAvg int64 `db:"memory_used_avg" json:"memory_used_avg"`
+	// Maximum memory in use, in bytes.
+	MemoryUsedMax int64 `db:"memory_used_max" json:"memory_used_max"`
+	// Memory available to the agent, in bytes.
+	MemoryTotal int64 `db:"memory_total" json:"memory_total"`
+	// Disk space in use at the end of the bucket, in bytes.
+	DiskUsed int64 `db:"disk_used" json:"disk_used"`
+	// Disk space available to the agent, in bytes.
+	DiskTotal int64 `db:"disk_total" json:"disk_total"`
 }
 
 type WorkspaceAgentScript struct {
@@ -3705,6 +4644,8 @@ type WorkspaceAgentScript struct {
 	TimeoutSeconds   int32     `db:"timeout_seconds" json:"timeout_seconds"`
 	DisplayName      string    `db:"display_name" json:"display_name"`
 	ID               uuid.UUID `db:"id" json:"id"`
+	// Whether the script runs when a prebuilt workspace is claimed by a user.
+	RunOnClaim bool `db:"run_on_claim" json:"run_on_claim"`
 }
 
 type WorkspaceAgentScriptTiming struct {
@@ -3737,6 +4678,27 @@ type WorkspaceAgentStat struct {
 	Usage                       bool            `db:"usage" json:"usage"`
 }
 
+type WorkspaceAgentStatsDefault struct {
+	ID                          uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
+	UserID                      uuid.UUID       `db:"user_id" json:"user_id"`
+	AgentID                     uuid.UUID       `db:"agent_id" json:"agent_id"`
+	WorkspaceID                 uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	TemplateID                  uuid.UUID       `db:"template_id" json:"template_id"`
+	ConnectionsByProto          json.RawMessage `db:"connections_by_proto" json:"connections_by_proto"`
+	ConnectionCount             int64           `db:"connection_count" json:"connection_count"`
+	RxPackets                   int64           `db:"rx_packets" json:"rx_packets"`
+	RxBytes                     int64           `db:"rx_bytes" json:"rx_bytes"`
+	TxPackets                   int64           `db:"tx_packets" json:"tx_packets"`
+	TxBytes                     int64           `db:"tx_bytes" json:"tx_bytes"`
+	ConnectionMedianLatencyMS   float64         `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+	SessionCountVSCode          int64           `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountJetBrains       int64           `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY int64           `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	SessionCountSSH             int64           `db:"session_count_ssh" json:"session_count_ssh"`
+	Usage                       bool            `db:"usage" json:"usage"`
+}
+
 type WorkspaceAgentVolumeResourceMonitor struct {
 	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
 	Enabled        bool                       `db:"enabled" json:"enabled"`
@@ -3832,26 +4794,62 @@ type WorkspaceAppStatus struct {
 
 // Joins in the username + avatar url of the initiated by user.
 type WorkspaceBuild struct {
-	ID                      uuid.UUID           `db:"id" json:"id"`
-	CreatedAt               time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt               time.Time           `db:"updated_at" json:"updated_at"`
-	WorkspaceID             uuid.UUID           `db:"workspace_id" json:"workspace_id"`
-	TemplateVersionID       uuid.UUID           `db:"template_version_id" json:"template_version_id"`
-	BuildNumber             int32               `db:"build_number" json:"build_number"`
-	Transition              WorkspaceTransition `db:"transition" json:"transition"`
-	InitiatorID             uuid.UUID           `db:"initiator_id" json:"initiator_id"`
-	ProvisionerState        []byte              `db:"provisioner_state" json:"provisioner_state"`
-	JobID                   uuid.UUID           `db:"job_id" json:"job_id"`
-	Deadline                time.Time           `db:"deadline" json:"deadline"`
-	Reason                  BuildReason         `db:"reason" json:"reason"`
-	DailyCost               int32               `db:"daily_cost" json:"daily_cost"`
-	MaxDeadline             time.Time           `db:"max_deadline" json:"max_deadline"`
-	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
-	HasAITask               sql.NullBool        `db:"has_ai_task" json:"has_ai_task"`
-	AITaskSidebarAppID      uuid.NullUUID       `db:"ai_task_sidebar_app_id" json:"ai_task_sidebar_app_id"`
-	InitiatorByAvatarUrl    string              `db:"initiator_by_avatar_url" json:"initiator_by_avatar_url"`
-	InitiatorByUsername     string              `db:"initiator_by_username" json:"initiator_by_username"`
-	InitiatorByName         string              `db:"initiator_by_name" json:"initiator_by_name"`
+	ID                      uuid.UUID              `db:"id" json:"id"`
+	CreatedAt               time.Time              `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time              `db:"updated_at" json:"updated_at"`
+	WorkspaceID             uuid.UUID              `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID       uuid.UUID              `db:"template_version_id" json:"template_version_id"`
+	BuildNumber             int32                  `db:"build_number" json:"build_number"`
+	Transition              WorkspaceTransition    `db:"transition" json:"transition"`
+	InitiatorID             uuid.UUID              `db:"initiator_id" json:"initiator_id"`
+	ProvisionerState        []byte                 `db:"provisioner_state" json:"provisioner_state"`
+	JobID                   uuid.UUID              `db:"job_id" json:"job_id"`
+	Deadline                time.Time              `db:"deadline" json:"deadline"`
+	Reason                  BuildReason            `db:"reason" json:"reason"`
+	DailyCost               int32                  `db:"daily_cost" json:"daily_cost"`
+	MaxDeadline             time.Time              `db:"max_deadline" json:"max_deadline"`
+	TemplateVersionPresetID uuid.NullUUID          `db:"template_version_preset_id" json:"template_version_preset_id"`
+	HasAITask               sql.NullBool           `db:"has_ai_task" json:"has_ai_task"`
+	AITaskSidebarAppID      uuid.NullUUID          `db:"ai_task_sidebar_app_id" json:"ai_task_sidebar_app_id"`
+	InitiatorContext        BuildInitiatorContext  `db:"initiator_context" json:"initiator_context"`
+	Warnings                WorkspaceBuildWarnings `db:"warnings" json:"warnings"`
+	InitiatorByAvatarUrl    string                 `db:"initiator_by_avatar_url" json:"initiator_by_avatar_url"`
+	InitiatorByUsername     string                 `db:"initiator_by_username" json:"initiator_by_username"`
+	InitiatorByName         string                 `db:"initiator_by_name" json:"initiator_by_name"`
+}
+
+// Workspace builds whose provisioner state and resource metadata were moved into a compressed file. The build, job and resource rows are kept as summaries.
+type WorkspaceBuildArchive struct {
+	WorkspaceBuildID      uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	FileID                uuid.UUID `db:"file_id" json:"file_id"`
+	StateSize             int32     `db:"state_size" json:"state_size"`
+	ResourceMetadataCount int32     `db:"resource_metadata_count" json:"resource_metadata_count"`
+	ArchivedAt            time.Time `db:"archived_at" json:"archived_at"`
+}
+
+// The workspace builds whose owner was notified that they run longer than their build duration notification threshold. Each build is only notified once.
+type WorkspaceBuildDurationNotification struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	NotifiedAt       time.Time `db:"notified_at" json:"notified_at"`
+}
+
+// The Idempotency-Key headers of the requests that created workspace builds. A retried request with the same key returns the build it created instead of creating another one.
+type WorkspaceBuildIdempotencyKey struct {
+	// The build the request created. It is set in the transaction that reserved the key, so it is only null while the request is in progress.
+	WorkspaceBuildID uuid.NullUUID `db:"workspace_build_id" json:"workspace_build_id"`
+	// The user that made the request. Keys are unique per user.
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	IdempotencyKey string    `db:"idempotency_key" json:"idempotency_key"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	// The SHA-256 hash of the request. A request that reuses the key with a different hash is rejected.
+	RequestHash []byte `db:"request_hash" json:"request_hash"`
+}
+
+// The provisioner state uploaded while a workspace build is applied. Removed once the build completes or fails with a final state. A build that did not complete can be resumed from it.
+type WorkspaceBuildInterimState struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	State            []byte    `db:"state" json:"state"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
 }
 
 type WorkspaceBuildParameter struct {
@@ -3862,6 +4860,23 @@ type WorkspaceBuildParameter struct {
 	Value string `db:"value" json:"value"`
 }
 
+// Workspace builds that were requested while another build of the workspace was active. The oldest entry of a workspace is started once no build of the workspace is active.
+type WorkspaceBuildQueue struct {
+	ID          uuid.UUID           `db:"id" json:"id"`
+	WorkspaceID uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	InitiatorID uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	Transition  WorkspaceTransition `db:"transition" json:"transition"`
+	// The template version to build, or NULL to build the version of the build that precedes it.
+	TemplateVersionID       uuid.NullUUID   `db:"template_version_id" json:"template_version_id"`
+	TemplateVersionPresetID uuid.NullUUID   `db:"template_version_preset_id" json:"template_version_preset_id"`
+	RichParameterValues     json.RawMessage `db:"rich_parameter_values" json:"rich_parameter_values"`
+	LogLevel                string          `db:"log_level" json:"log_level"`
+	CreatedAt               time.Time       `db:"created_at" json:"created_at"`
+	// Set when the queued build could not be started. Failed entries are kept so the error can be shown, and do not hold up later entries.
+	FailedAt sql.NullTime `db:"failed_at" json:"failed_at"`
+	Error    string       `db:"error" json:"error"`
+}
+
 type WorkspaceBuildTable struct {
 	ID                      uuid.UUID           `db:"id" json:"id"`
 	CreatedAt               time.Time           `db:"created_at" json:"created_at"`
@@ -3880,6 +4895,42 @@ type WorkspaceBuildTable struct {
 	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
 	HasAITask               sql.NullBool        `db:"has_ai_task" json:"has_ai_task"`
 	AITaskSidebarAppID      uuid.NullUUID       `db:"ai_task_sidebar_app_id" json:"ai_task_sidebar_app_id"`
+	// Structured context about what initiated the build, such as the name of the API key used to request it, the automation that started it, or the schedule that triggered an autostart.
+	InitiatorContext BuildInitiatorContext `db:"initiator_context" json:"initiator_context"`
+	// Warnings recorded when the build was created, such as the build using a template version that is not the active version of its template.
+	Warnings WorkspaceBuildWarnings `db:"warnings" json:"warnings"`
+}
+
+// Records the cost accrued by each workspace in daily buckets, based on the daily cost of its resources.
+type WorkspaceDailyCost struct {
+	// Start time of the period the cost accrued in.
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	// End time of the period the cost accrued in.
+	EndTime time.Time `db:"end_time" json:"end_time"`
+	// ID of the workspace that accrued the cost.
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// ID of the organization of the workspace.
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// ID of the owner of the workspace when the cost was rolled up.
+	OwnerID uuid.UUID `db:"owner_id" json:"owner_id"`
+	// ID of the template of the workspace.
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// Cost accrued in the period, prorated by the time each build of the workspace was current.
+	Cost float64 `db:"cost" json:"cost"`
+}
+
+// The latest drift check of each workspace, which plans the latest build of a stopped or failed workspace against its state.
+type WorkspaceDriftCheck struct {
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	// The template version dry-run job that plans the build.
+	JobID       uuid.UUID    `db:"job_id" json:"job_id"`
+	CreatedAt   time.Time    `db:"created_at" json:"created_at"`
+	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
+	// Addresses of resources that were changed outside of Terraform.
+	DriftedResources []string `db:"drifted_resources" json:"drifted_resources"`
+	// Addresses of resources that are in the state but would be deleted by the plan.
+	OrphanedResources []string `db:"orphaned_resources" json:"orphaned_resources"`
 }
 
 type WorkspaceLatestBuild struct {
@@ -3893,6 +4944,55 @@ type WorkspaceLatestBuild struct {
 	JobStatus               ProvisionerJobStatus `db:"job_status" json:"job_status"`
 }
 
+// Arbitrary key/value labels used to group and filter workspaces.
+type WorkspaceLabel struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// Keys are lowercase, since workspace search queries are case insensitive.
+	Key       string    `db:"key" json:"key"`
+	Value     string    `db:"value" json:"value"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Administrative locks that prevent any new builds of a workspace, including automatic ones, until they are lifted. Lifted locks are kept as a record of who locked the workspace and why.
+type WorkspaceLock struct {
+	ID          uuid.UUID     `db:"id" json:"id"`
+	WorkspaceID uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	Reason      string        `db:"reason" json:"reason"`
+	LockedBy    uuid.UUID     `db:"locked_by" json:"locked_by"`
+	LockedAt    time.Time     `db:"locked_at" json:"locked_at"`
+	UnlockedBy  uuid.NullUUID `db:"unlocked_by" json:"unlocked_by"`
+	// The time the lock was lifted, or NULL if the workspace is still locked.
+	UnlockedAt sql.NullTime `db:"unlocked_at" json:"unlocked_at"`
+}
+
+// Transfers of workspaces, including their provisioner state, to another deployment. The workspace is stopped, imported on the destination with its state and started there, then deleted without destroying its resources.
+type WorkspaceMigration struct {
+	ID             uuid.UUID                `db:"id" json:"id"`
+	WorkspaceID    uuid.UUID                `db:"workspace_id" json:"workspace_id"`
+	InitiatorID    uuid.UUID                `db:"initiator_id" json:"initiator_id"`
+	Status         WorkspaceMigrationStatus `db:"status" json:"status"`
+	DestinationURL string                   `db:"destination_url" json:"destination_url"`
+	// The organization on the destination deployment. It does not reference an organization of this deployment.
+	DestinationOrganizationID uuid.UUID `db:"destination_organization_id" json:"destination_organization_id"`
+	DestinationOwner          string    `db:"destination_owner" json:"destination_owner"`
+	// The template on the destination deployment, or NULL to look it up by the name of the template of the workspace.
+	DestinationTemplateID uuid.NullUUID `db:"destination_template_id" json:"destination_template_id"`
+	DestinationName       string        `db:"destination_name" json:"destination_name"`
+	// The session token used to authenticate with the destination deployment. It is cleared once the migration completes.
+	DestinationSessionToken string `db:"destination_session_token" json:"destination_session_token"`
+	// The ID of the key used to encrypt the destination session token. If this is NULL, the token is not encrypted
+	DestinationSessionTokenKeyID sql.NullString `db:"destination_session_token_key_id" json:"destination_session_token_key_id"`
+	// The build that stopped the workspace on this deployment.
+	SourceBuildID          uuid.NullUUID `db:"source_build_id" json:"source_build_id"`
+	DestinationWorkspaceID uuid.NullUUID `db:"destination_workspace_id" json:"destination_workspace_id"`
+	// The build that starts the workspace on the destination deployment.
+	DestinationBuildID uuid.NullUUID `db:"destination_build_id" json:"destination_build_id"`
+	Error              string        `db:"error" json:"error"`
+	CreatedAt          time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time     `db:"updated_at" json:"updated_at"`
+	CompletedAt        sql.NullTime  `db:"completed_at" json:"completed_at"`
+}
+
 type WorkspaceModule struct {
 	ID         uuid.UUID           `db:"id" json:"id"`
 	JobID      uuid.UUID           `db:"job_id" json:"job_id"`
@@ -3903,6 +5003,23 @@ type WorkspaceModule struct {
 	CreatedAt  time.Time           `db:"created_at" json:"created_at"`
 }
 
+// Naming rules that are enforced when workspaces are created. A row without a template_id applies to the whole organization, a row with a template_id applies to workspaces created from that template and takes precedence over the organization policy.
+type WorkspaceNamingPolicy struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+	// Regular expression the full workspace name must match. Empty disables the check.
+	Regex string `db:"regex" json:"regex"`
+	// Go template rendered with the owner, organization and template names. The workspace name must start with the result.
+	Prefix string `db:"prefix" json:"prefix"`
+	// Go template rendered with the owner, organization and template names. The workspace name must end with the result.
+	Suffix string `db:"suffix" json:"suffix"`
+	// Scope in which workspace names must be unique.
+	UniquenessScope WorkspaceNameUniquenessScope `db:"uniqueness_scope" json:"uniqueness_scope"`
+	CreatedAt       time.Time                    `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time                    `db:"updated_at" json:"updated_at"`
+}
+
 type WorkspacePrebuild struct {
 	ID              uuid.UUID     `db:"id" json:"id"`
 	Name            string        `db:"name" json:"name"`
@@ -3922,6 +5039,27 @@ type WorkspacePrebuildBuild struct {
 	BuildNumber             int32               `db:"build_number" json:"build_number"`
 }
 
+// Prebuilt workspaces held for a specific user or group. Until the reservation expires, a reserved prebuilt workspace can only be claimed by the user or a member of the group.
+type WorkspacePrebuildReservation struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The user the prebuilt workspace is reserved for. Exactly one of user_id and group_id is set.
+	UserID uuid.NullUUID `db:"user_id" json:"user_id"`
+	// The group whose members the prebuilt workspace is reserved for. Exactly one of user_id and group_id is set.
+	GroupID    uuid.NullUUID `db:"group_id" json:"group_id"`
+	ReservedBy uuid.UUID     `db:"reserved_by" json:"reserved_by"`
+	CreatedAt  time.Time     `db:"created_at" json:"created_at"`
+	ExpiresAt  time.Time     `db:"expires_at" json:"expires_at"`
+}
+
+// The provisioner daemon that last built a workspace successfully. The daemon likely still has the providers of the template version cached, so it is preferred for the next build of the workspace.
+type WorkspaceProvisionerAffinity struct {
+	WorkspaceID         uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	ProvisionerDaemonID uuid.UUID `db:"provisioner_daemon_id" json:"provisioner_daemon_id"`
+	// The template version of the last successful build. Affinity is only honored for builds of the same template version.
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type WorkspaceProxy struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -3945,6 +5083,13 @@ type WorkspaceProxy struct {
 	Version  string `db:"version" json:"version"`
 }
 
+// The release channel a workspace tracks instead of the active version of its template.
+type WorkspaceReleaseChannel struct {
+	WorkspaceID uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	Channel     ReleaseChannel `db:"channel" json:"channel"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
 type WorkspaceResource struct {
 	ID           uuid.UUID           `db:"id" json:"id"`
 	CreatedAt    time.Time           `db:"created_at" json:"created_at"`
@@ -3957,6 +5102,12 @@ type WorkspaceResource struct {
 	InstanceType sql.NullString      `db:"instance_type" json:"instance_type"`
 	DailyCost    int32               `db:"daily_cost" json:"daily_cost"`
 	ModulePath   sql.NullString      `db:"module_path" json:"module_path"`
+	// The name of the group the resource is displayed under in user interfaces. Empty means the resource is not grouped.
+	DisplayGroup string `db:"display_group" json:"display_group"`
+	// Specifies the order in which to display resources in user interfaces.
+	DisplayOrder int32 `db:"display_order" json:"display_order"`
+	// Whether the resource should be collapsed by default in user interfaces.
+	Collapsed bool `db:"collapsed" json:"collapsed"`
 }
 
 type WorkspaceResourceMetadatum struct {
@@ -3967,6 +5118,34 @@ type WorkspaceResourceMetadatum struct {
 	ID                  int64          `db:"id" json:"id"`
 }
 
+// Terminal sessions recorded by workspace agents when session recording is enabled.
+type WorkspaceSessionRecording struct {
+	ID          uuid.UUID                     `db:"id" json:"id"`
+	WorkspaceID uuid.UUID                     `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Type        WorkspaceSessionRecordingType `db:"type" json:"type"`
+	// Width of the terminal in columns when the session started.
+	Width int32 `db:"width" json:"width"`
+	// Height of the terminal in rows when the session started.
+	Height    int32     `db:"height" json:"height"`
+	StartedAt time.Time `db:"started_at" json:"started_at"`
+	// Time the session ended. NULL while it is ongoing, or if the agent never reported the end.
+	EndedAt sql.NullTime `db:"ended_at" json:"ended_at"`
+	// Total size of the recorded output, before encryption.
+	SizeBytes int64 `db:"size_bytes" json:"size_bytes"`
+}
+
+// Output of recorded terminal sessions, in the order it was written.
+type WorkspaceSessionRecordingChunk struct {
+	ID          int64     `db:"id" json:"id"`
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	// Time the output was written by the session, as reported by the agent.
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Data      []byte    `db:"data" json:"data"`
+	// The ID of the key used to encrypt the data. If this is NULL, the data is not encrypted
+	DataKeyID sql.NullString `db:"data_key_id" json:"data_key_id"`
+}
+
 type WorkspaceTable struct {
 	ID                uuid.UUID        `db:"id" json:"id"`
 	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
@@ -3985,4 +5164,8 @@ type WorkspaceTable struct {
 	// Favorite is true if the workspace owner has favorited the workspace.
 	Favorite    bool         `db:"favorite" json:"favorite"`
 	NextStartAt sql.NullTime `db:"next_start_at" json:"next_start_at"`
+	// Groups the workspace is shared with, and the actions they are granted.
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
+	// Users the workspace is shared with, and the actions they are granted.
+	UserACL WorkspaceACL `db:"user_acl" json:"user_acl"`
 }
@@ -2,6 +2,7 @@ package provisionerjobs
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -28,5 +29,34 @@ func PostJob(ps pubsub.Pubsub, job database.ProvisionerJob) error {
 		return xerrors.Errorf("marshal job posting: %w", err)
 	}
 	err = ps.Publish(EventJobPosted, msg)
-	return err
+	if err != nil {
+		return err
+	}
+	return PublishJobStateChange(ps, job)
+}
+
+// JobStateChannel is the channel that state changes of the provisioner jobs
+// of an organization are published on.
+func JobStateChannel(organizationID uuid.UUID) string {
+	return fmt.Sprintf("provisioner_job_state:%s", organizationID)
+}
+
+type JobStateChange struct {
+	JobID uuid.UUID `json:"job_id"`
+}
+
+// PublishJobStateChange notifies watchers that a job was queued, acquired,
+// canceled or completed. Watchers read the new state from the database.
+func PublishJobStateChange(ps pubsub.Pubsub, job database.ProvisionerJob) error {
+	msg, err := json.Marshal(JobStateChange{
+		JobID: job.ID,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal job state change: %w", err)
+	}
+	err = ps.Publish(JobStateChannel(job.OrganizationID), msg)
+	if err != nil {
+		return xerrors.Errorf("publish job state change: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,120 @@
+package pubsub_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPubsubRedis(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*miniredis.Miniredis, *pubsub.RedisPubsub) {
+		t.Helper()
+		server := miniredis.RunT(t)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		ps, err := pubsub.NewRedis(ctx, logger, "redis://"+server.Addr())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = ps.Close()
+		})
+		return server, ps
+	}
+
+	t.Run("Publish", func(t *testing.T) {
+		t.Parallel()
+		_, ps := setup(t)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		messages := make(chan string, 2)
+		cancelFirst, err := ps.Subscribe("test", func(_ context.Context, message []byte) {
+			messages <- "first:" + string(message)
+		})
+		require.NoError(t, err)
+		defer cancelFirst()
+		cancelSecond, err := ps.SubscribeWithErr("test", func(_ context.Context, message []byte, err error) {
+			assert.NoError(t, err)
+			messages <- "second:" + string(message)
+		})
+		require.NoError(t, err)
+
+		err = ps.Publish("test", []byte("hello"))
+		require.NoError(t, err)
+		received := []string{testutil.TryReceive(ctx, t, messages), testutil.TryReceive(ctx, t, messages)}
+		require.ElementsMatch(t, []string{"first:hello", "second:hello"}, received)
+
+		cancelSecond()
+		err = ps.Publish("test", []byte("world"))
+		require.NoError(t, err)
+		require.Equal(t, "first:world", testutil.TryReceive(ctx, t, messages))
+	})
+
+	t.Run("Resubscribe", func(t *testing.T) {
+		t.Parallel()
+		_, ps := setup(t)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		// Unsubscribing from Redis must not race with subscribing again.
+		for range 5 {
+			cancel, err := ps.Subscribe("test", func(context.Context, []byte) {})
+			require.NoError(t, err)
+			cancel()
+		}
+
+		messages := make(chan []byte, 1)
+		cancel, err := ps.Subscribe("test", func(_ context.Context, message []byte) {
+			messages <- message
+		})
+		require.NoError(t, err)
+		defer cancel()
+		err = ps.Publish("test", []byte("hello"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("hello"), testutil.TryReceive(ctx, t, messages))
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		t.Parallel()
+		server, ps := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		messages := make(chan []byte, 1)
+		errs := make(chan error, 1)
+		cancel, err := ps.SubscribeWithErr("test", func(_ context.Context, message []byte, err error) {
+			if err != nil {
+				errs <- err
+				return
+			}
+			messages <- message
+		})
+		require.NoError(t, err)
+		defer cancel()
+
+		server.Close()
+		require.NoError(t, server.Restart())
+
+		// Messages published while disconnected are lost, which subscribers
+		// are told about once the subscription is restored.
+		require.ErrorIs(t, testutil.TryReceive(ctx, t, errs), pubsub.ErrDroppedMessages)
+		err = ps.Publish("test", []byte("hello"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("hello"), testutil.TryReceive(ctx, t, messages))
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		t.Parallel()
+		_, ps := setup(t)
+
+		require.NoError(t, ps.Close())
+		_, err := ps.Subscribe("test", func(context.Context, []byte) {})
+		require.Error(t, err)
+	})
+}
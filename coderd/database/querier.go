@@ -50,6 +50,8 @@ type sqlcQuerier interface {
 	ActivityBumpWorkspace(ctx context.Context, arg ActivityBumpWorkspaceParams) error
 	// AllUserIDs returns all UserIDs regardless of user status or deletion.
 	AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error)
+	// Approves a device for a user. Devices can only be approved once.
+	ApproveLoginDeviceCode(ctx context.Context, arg ApproveLoginDeviceCodeParams) (LoginDeviceCode, error)
 	// Archiving templates is a soft delete action, so is reversible.
 	// Archiving prevents the version from being used and discovered
 	// by listing.
@@ -64,10 +66,23 @@ type sqlcQuerier interface {
 	CleanTailnetCoordinators(ctx context.Context) error
 	CleanTailnetLostPeers(ctx context.Context) error
 	CleanTailnetTunnels(ctx context.Context) error
+	// The destination session token is only needed while the migration runs, so
+	// it is cleared once the migration completes.
+	CompleteWorkspaceMigration(ctx context.Context, arg CompleteWorkspaceMigrationParams) (WorkspaceMigration, error)
+	// Deletes an approved device code that has not expired, so a session token is
+	// issued for it only once.
+	ConsumeApprovedLoginDeviceCode(ctx context.Context, arg ConsumeApprovedLoginDeviceCodeParams) (LoginDeviceCode, error)
 	// CountInProgressPrebuilds returns the number of in-progress prebuilds, grouped by preset ID and transition.
 	// Prebuild considered in-progress if it's in the "starting", "stopping", or "deleting" state.
 	CountInProgressPrebuilds(ctx context.Context) ([]CountInProgressPrebuildsRow, error)
+	// Counts the pending and running provisioner jobs started by a user, both in
+	// total and for workspace builds of the given template.
+	CountInProgressProvisionerJobsByInitiator(ctx context.Context, arg CountInProgressProvisionerJobsByInitiatorParams) (CountInProgressProvisionerJobsByInitiatorRow, error)
 	CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Creates the range partition of a time partitioned table covering
+	// [partition_start, partition_end). Returns false if the partition already
+	// exists, or if rows in that range are still stored in the default partition.
+	CreateTimePartition(ctx context.Context, arg CreateTimePartitionParams) (bool, error)
 	CustomRoles(ctx context.Context, arg CustomRolesParams) ([]CustomRole, error)
 	DeleteAPIKeyByID(ctx context.Context, id string) error
 	DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
@@ -79,9 +94,11 @@ type sqlcQuerier interface {
 	// be recreated.
 	DeleteAllWebpushSubscriptions(ctx context.Context) error
 	DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteBuildAlertRuleByID(ctx context.Context, id uuid.UUID) error
 	DeleteCoordinator(ctx context.Context, id uuid.UUID) error
 	DeleteCryptoKey(ctx context.Context, arg DeleteCryptoKeyParams) (CryptoKey, error)
 	DeleteCustomRole(ctx context.Context, arg DeleteCustomRoleParams) error
+	DeleteExpiredLoginDeviceCodes(ctx context.Context, before time.Time) error
 	DeleteExternalAuthLink(ctx context.Context, arg DeleteExternalAuthLinkParams) error
 	DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error
 	DeleteGroupByID(ctx context.Context, id uuid.UUID) error
@@ -92,6 +109,12 @@ type sqlcQuerier interface {
 	DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error
 	DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error
+	// Deletes at most @limit_count audit logs older than @before, oldest first.
+	DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditLogsParams) (int64, error)
+	// Permanently removes workspaces whose delete build completed before the given
+	// time, along with their builds, provisioner jobs and job logs. Until then,
+	// deleted workspaces can be restored.
+	DeleteOldDeletedWorkspaces(ctx context.Context, before time.Time) (int64, error)
 	// Delete all notification messages which have not been updated for over a week.
 	DeleteOldNotificationMessages(ctx context.Context) error
 	// Delete provisioner daemons that have been created at least a week ago
@@ -99,13 +122,30 @@ type sqlcQuerier interface {
 	// A provisioner daemon with "zeroed" last_seen_at column indicates possible
 	// connectivity issues (no provisioner daemon activity since registration).
 	DeleteOldProvisionerDaemons(ctx context.Context) error
+	// Deletes the logs of at most @limit_count jobs that completed before
+	// @completed_before, oldest first, including archived logs and their files.
+	// Returns the number of deleted logs.
+	DeleteOldProvisionerJobLogs(ctx context.Context, arg DeleteOldProvisionerJobLogsParams) (int64, error)
+	DeleteOldWorkspaceAgentConnectionQuality(ctx context.Context, beforeTime time.Time) error
+	// Events that are still ongoing are deleted as well, as the agents that
+	// reported them are usually long gone.
+	DeleteOldWorkspaceAgentHealthEvents(ctx context.Context, beforeTime time.Time) error
 	// If an agent hasn't connected in the last 7 days, we purge it's logs.
 	// Exception: if the logs are related to the latest build, we keep those around.
 	// Logs can take up a lot of space, so it's important we clean up frequently.
-	DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) error
-	DeleteOldWorkspaceAgentStats(ctx context.Context) error
+	DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error)
+	DeleteOldWorkspaceAgentResourceUsage(ctx context.Context, beforeTime time.Time) error
+	// Deletes raw agent stats created before @before, but never those that may
+	// not have been rolled up into template usage stats yet.
+	DeleteOldWorkspaceAgentStats(ctx context.Context, before time.Time) (int64, error)
+	DeleteOldWorkspaceBuildIdempotencyKeys(ctx context.Context, before time.Time) error
+	// Recorded output is deleted along with the recordings by the foreign key.
+	DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error
 	DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error
+	DeleteProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) error
+	DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error
 	DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error
+	DeleteProvisionerReservation(ctx context.Context, id uuid.UUID) error
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
 	DeleteRuntimeConfig(ctx context.Context, key string) error
 	DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error)
@@ -113,15 +153,35 @@ type sqlcQuerier interface {
 	DeleteTailnetClientSubscription(ctx context.Context, arg DeleteTailnetClientSubscriptionParams) error
 	DeleteTailnetPeer(ctx context.Context, arg DeleteTailnetPeerParams) (DeleteTailnetPeerRow, error)
 	DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error)
+	DeleteTemplatePresetByID(ctx context.Context, id uuid.UUID) error
+	DeleteTemplatePresetGroupDefaultsByPresetID(ctx context.Context, templatePresetID uuid.UUID) error
+	DeleteTemplateReleaseChannel(ctx context.Context, arg DeleteTemplateReleaseChannelParams) error
+	DeleteTemplateShare(ctx context.Context, arg DeleteTemplateShareParams) error
+	DeleteTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) error
 	DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg DeleteWebpushSubscriptionByUserIDAndEndpointParams) error
 	DeleteWebpushSubscriptions(ctx context.Context, ids []uuid.UUID) error
 	DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error
 	DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error
+	// Deletes the archive of a build together with the file holding its data.
+	DeleteWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) error
+	DeleteWorkspaceBuildInterimState(ctx context.Context, workspaceBuildID uuid.UUID) error
+	DeleteWorkspaceBuildQueueEntry(ctx context.Context, id uuid.UUID) error
+	DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error
+	DeleteWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error
+	DeleteWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error
+	DeleteWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) error
 	DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error
 	// Disable foreign keys and triggers for all tables.
 	// Deprecated: disable foreign keys was created to aid in migrating off
 	// of the test-only in-memory database. Do not use this in new code.
 	DisableForeignKeysAndTriggers(ctx context.Context) error
+	// Drops the audit_logs partitions that only hold rows older than @before.
+	// Returns the number of dropped partitions.
+	DropOldAuditLogsPartitions(ctx context.Context, before time.Time) (int32, error)
+	// Drops the workspace_agent_stats partitions that only hold rows older than
+	// the cutoff used by DeleteOldWorkspaceAgentStats. Returns the number of
+	// dropped partitions.
+	DropOldWorkspaceAgentStatsPartitions(ctx context.Context, before time.Time) (int32, error)
 	EnqueueNotificationMessage(ctx context.Context, arg EnqueueNotificationMessageParams) error
 	FavoriteWorkspace(ctx context.Context, id uuid.UUID) error
 	FetchMemoryResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentMemoryResourceMonitor, error)
@@ -136,9 +196,14 @@ type sqlcQuerier interface {
 	GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error)
 	GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error)
 	GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error)
+	GetAccessRequestByID(ctx context.Context, id uuid.UUID) (AccessRequest, error)
+	GetAccessRequests(ctx context.Context, arg GetAccessRequestsParams) ([]AccessRequest, error)
 	GetActivePresetPrebuildSchedules(ctx context.Context) ([]TemplateVersionPresetPrebuildSchedule, error)
 	GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error)
 	GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error)
+	// Returns the migrations that have not completed, oldest first.
+	GetActiveWorkspaceLockByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceLock, error)
+	GetActiveWorkspaceMigrations(ctx context.Context) ([]WorkspaceMigration, error)
 	GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error)
 	// For PG Coordinator HTMLDebug
 	GetAllTailnetCoordinators(ctx context.Context) ([]TailnetCoordinator, error)
@@ -147,26 +212,46 @@ type sqlcQuerier interface {
 	GetAnnouncementBanners(ctx context.Context) (string, error)
 	GetAppSecurityKey(ctx context.Context) (string, error)
 	GetApplicationName(ctx context.Context) (string, error)
+	// Returns the audit logs logged after the given cursor, which is the time and
+	// ID of the last exported log, in the order they are exported.
+	GetAuditLogsForExport(ctx context.Context, arg GetAuditLogsForExportParams) ([]GetAuditLogsForExportRow, error)
 	// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
 	// ID.
 	GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOffsetParams) ([]GetAuditLogsOffsetRow, error)
 	// This function returns roles for authorization purposes. Implied member roles
 	// are included.
 	GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error)
+	GetBatchedNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]GetBatchedNotificationMessagesByUserIDRow, error)
+	GetBuildAlertRuleByID(ctx context.Context, id uuid.UUID) (BuildAlertRule, error)
+	// Returns the metrics build alert rules are evaluated against, over the
+	// workspace builds of an organization since the given time. If template_id
+	// is set, only builds of that template are considered.
+	GetBuildAlertRuleMetrics(ctx context.Context, arg GetBuildAlertRuleMetricsParams) (GetBuildAlertRuleMetricsRow, error)
+	GetBuildAlertRules(ctx context.Context) ([]BuildAlertRule, error)
+	GetBuildAlertRulesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]BuildAlertRule, error)
 	GetCoordinatorResumeTokenSigningKey(ctx context.Context) (string, error)
 	GetCryptoKeyByFeatureAndSequence(ctx context.Context, arg GetCryptoKeyByFeatureAndSequenceParams) (CryptoKey, error)
 	GetCryptoKeys(ctx context.Context) ([]CryptoKey, error)
 	GetCryptoKeysByFeature(ctx context.Context, feature CryptoKeyFeature) ([]CryptoKey, error)
 	GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error)
+	GetDBPurgeStats(ctx context.Context) (string, error)
 	GetDERPMeshKey(ctx context.Context) (string, error)
 	GetDefaultOrganization(ctx context.Context) (Organization, error)
 	GetDefaultProxyConfig(ctx context.Context) (GetDefaultProxyConfigRow, error)
+	// Returns the default preset of the template for the groups of the user.
+	// Defaults of groups take precedence over the default of the "Everyone" group,
+	// and ties are broken by group name.
+	GetDefaultTemplatePresetForUser(ctx context.Context, arg GetDefaultTemplatePresetForUserParams) (TemplatePreset, error)
 	GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]GetDeploymentDAUsRow, error)
 	GetDeploymentID(ctx context.Context) (string, error)
 	GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error)
 	GetDeploymentWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentUsageStatsRow, error)
 	GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploymentWorkspaceStatsRow, error)
 	GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx context.Context, provisionerJobIds []uuid.UUID) ([]GetEligibleProvisionerDaemonsByProvisionerJobIDsRow, error)
+	// Returns the recorded output that is encrypted, for rotating or removing the
+	// encryption keys.
+	GetEncryptedWorkspaceSessionRecordingChunks(ctx context.Context) ([]WorkspaceSessionRecordingChunk, error)
+	GetExpiredAccessRequests(ctx context.Context, now time.Time) ([]AccessRequest, error)
 	GetExternalAuthLink(ctx context.Context, arg GetExternalAuthLinkParams) (ExternalAuthLink, error)
 	GetExternalAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]ExternalAuthLink, error)
 	GetFailedWorkspaceBuildsByTemplateID(ctx context.Context, arg GetFailedWorkspaceBuildsByTemplateIDParams) ([]GetFailedWorkspaceBuildsByTemplateIDRow, error)
@@ -201,14 +286,20 @@ type sqlcQuerier interface {
 	// param created_at_opt: The created_at timestamp to filter by. This parameter is usd for pagination - it fetches notifications created before the specified timestamp if it is not the zero value
 	// param limit_opt: The limit of notifications to fetch. If the limit is not specified, it defaults to 25
 	GetInboxNotificationsByUserID(ctx context.Context, arg GetInboxNotificationsByUserIDParams) ([]InboxNotification, error)
+	GetJobReaperLastRunAt(ctx context.Context) (string, error)
 	GetLastUpdateCheck(ctx context.Context) (string, error)
 	GetLatestCryptoKeyByFeature(ctx context.Context, feature CryptoKeyFeature) (CryptoKey, error)
+	// Returns the latest start build of the workspace whose job succeeded, which
+	// is the build a workspace is rolled back to.
+	GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
 	GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAppStatus, error)
 	GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
 	GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceBuild, error)
 	GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error)
 	GetLicenseByID(ctx context.Context, id int32) (License, error)
 	GetLicenses(ctx context.Context) ([]License, error)
+	GetLoginDeviceCodeByDeviceCodeHash(ctx context.Context, deviceCodeHash []byte) (LoginDeviceCode, error)
+	GetLoginDeviceCodeByUserCode(ctx context.Context, userCode string) (LoginDeviceCode, error)
 	GetLogoURL(ctx context.Context) (string, error)
 	GetNotificationMessagesByStatus(ctx context.Context, arg GetNotificationMessagesByStatusParams) ([]NotificationMessage, error)
 	// Fetch the notification report generator log indicating recent activity.
@@ -227,13 +318,27 @@ type sqlcQuerier interface {
 	GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error)
 	GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]GetOAuth2ProviderAppsByUserIDRow, error)
 	GetOAuthSigningKey(ctx context.Context) (string, error)
+	// GetOIDCUserClaims returns the claims OIDC users last logged in with. This
+	// query is used to preview idp sync settings before they are applied.
+	GetOIDCUserClaims(ctx context.Context, organizationID uuid.UUID) ([]GetOIDCUserClaimsRow, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, arg GetOrganizationByNameParams) (Organization, error)
 	GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error)
 	GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (GetOrganizationResourceCountByIDRow, error)
+	GetOrganizationWorkspaceNamingPolicy(ctx context.Context, organizationID uuid.UUID) (WorkspaceNamingPolicy, error)
 	GetOrganizations(ctx context.Context, arg GetOrganizationsParams) ([]Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, arg GetOrganizationsByUserIDParams) ([]Organization, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
+	// Counts the pending jobs grouped by organization and tag set, along with when
+	// the oldest of them was created. This is used by the health check to detect
+	// tag sets that no provisioner is picking jobs up for.
+	GetPendingProvisionerJobsQueueDepthByTags(ctx context.Context) ([]GetPendingProvisionerJobsQueueDepthByTagsRow, error)
+	// Returns the exports that still have to be generated, oldest first.
+	GetPendingUserActivityExports(ctx context.Context) ([]UserActivityExport, error)
+	// GetPrebuildClaimDemand returns the number of workspaces created from each preset in every hour since
+	// the given time. Claimed workspaces were assigned a prebuilt workspace, missed ones were provisioned from
+	// scratch. Presets are identified by template and name, since every template version has its own presets.
+	GetPrebuildClaimDemand(ctx context.Context, since time.Time) ([]GetPrebuildClaimDemandRow, error)
 	GetPrebuildMetrics(ctx context.Context) ([]GetPrebuildMetricsRow, error)
 	GetPresetByID(ctx context.Context, presetID uuid.UUID) (GetPresetByIDRow, error)
 	GetPresetByWorkspaceBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (TemplateVersionPreset, error)
@@ -265,6 +370,9 @@ type sqlcQuerier interface {
 	GetPresetsBackoff(ctx context.Context, lookback time.Time) ([]GetPresetsBackoffRow, error)
 	GetPresetsByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPreset, error)
 	GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error)
+	// Returns the pause of an organization, or the deployment-wide pause if
+	// organization_id is NULL.
+	GetProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) (ProvisionerBuildPause, error)
 	GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error)
 	GetProvisionerDaemonsByOrganization(ctx context.Context, arg GetProvisionerDaemonsByOrganizationParams) ([]ProvisionerDaemon, error)
 	// Current job information.
@@ -274,23 +382,55 @@ type sqlcQuerier interface {
 	// Gets a single provisioner job by ID for update.
 	// This is used to securely reap jobs that have been hung/pending for a long time.
 	GetProvisionerJobByIDForUpdate(ctx context.Context, id uuid.UUID) (ProvisionerJob, error)
+	GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (ProvisionerJobLogArchive, error)
+	// Counts the jobs that were picked up by a provisioner since the given time,
+	// grouped by organization and tag set. This is used to estimate when pending
+	// jobs will start.
+	GetProvisionerJobThroughputByTags(ctx context.Context, arg GetProvisionerJobThroughputByTagsParams) ([]GetProvisionerJobThroughputByTagsRow, error)
 	GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobTiming, error)
 	GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error)
 	GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, arg GetProvisionerJobsByIDsWithQueuePositionParams) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error)
 	GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx context.Context, arg GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams) ([]GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow, error)
 	GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error)
+	// Returns the jobs that a provisioner was running at any point between the
+	// start and end times. This is used to report provisioner utilization and
+	// queue wait times.
+	GetProvisionerJobsRunningBetween(ctx context.Context, arg GetProvisionerJobsRunningBetweenParams) ([]GetProvisionerJobsRunningBetweenRow, error)
+	// Returns jobs that completed before the given time and still have logs in
+	// provisioner_job_logs, oldest first.
+	GetProvisionerJobsToArchiveLogs(ctx context.Context, arg GetProvisionerJobsToArchiveLogsParams) ([]GetProvisionerJobsToArchiveLogsRow, error)
 	// To avoid repeatedly attempting to reap the same jobs, we randomly order and limit to @max_jobs.
 	GetProvisionerJobsToBeReaped(ctx context.Context, arg GetProvisionerJobsToBeReapedParams) ([]ProvisionerJob, error)
 	GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (ProvisionerKey, error)
 	GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (ProvisionerKey, error)
 	GetProvisionerKeyByName(ctx context.Context, arg GetProvisionerKeyByNameParams) (ProvisionerKey, error)
 	GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error)
+	GetProvisionerReservationByID(ctx context.Context, id uuid.UUID) (ProvisionerReservation, error)
+	// Returns the reservations of an organization that have not ended yet.
+	GetProvisionerReservationsByOrganization(ctx context.Context, arg GetProvisionerReservationsByOrganizationParams) ([]ProvisionerReservation, error)
 	GetQuotaAllowanceForUser(ctx context.Context, arg GetQuotaAllowanceForUserParams) (int64, error)
 	GetQuotaConsumedForUser(ctx context.Context, arg GetQuotaConsumedForUserParams) (int64, error)
+	GetQuotaMonthlyBudgetForUser(ctx context.Context, arg GetQuotaMonthlyBudgetForUserParams) (int64, error)
+	// Returns the credits spent by the workspaces of the user in the organization
+	// since the start time, as rolled up in workspace_daily_costs.
+	GetQuotaMonthlySpendForUser(ctx context.Context, arg GetQuotaMonthlySpendForUserParams) (float64, error)
+	GetReadOnlySettings(ctx context.Context) (string, error)
 	GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error)
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
 	GetRunningPrebuiltWorkspaces(ctx context.Context) ([]GetRunningPrebuiltWorkspacesRow, error)
+	// Returns the running workspace builds which have been running for longer
+	// than the build duration notification threshold of the workspace owner, and
+	// whose owner wasn't notified yet.
+	GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx context.Context, now time.Time) ([]GetRunningWorkspaceBuildsExceedingDurationThresholdRow, error)
 	GetRuntimeConfig(ctx context.Context, key string) (string, error)
+	// Returns the variables whose values are encrypted at rest when database
+	// encryption is enabled. Used to re-encrypt them when rotating keys.
+	GetSensitiveTemplateVersionVariables(ctx context.Context) ([]TemplateVersionVariable, error)
+	GetServiceAccountByUserID(ctx context.Context, userID uuid.UUID) (ServiceAccount, error)
+	GetServiceAccountsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]ServiceAccount, error)
+	// Returns the oldest entry that has not failed of each workspace whose latest
+	// build is not active.
+	GetStartableWorkspaceBuildQueueEntries(ctx context.Context) ([]WorkspaceBuildQueue, error)
 	GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error)
 	GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error)
 	GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]TailnetPeer, error)
@@ -306,7 +446,11 @@ type sqlcQuerier interface {
 	// in sync with GetTemplateAppInsights and UpsertTemplateUsageStats.
 	GetTemplateAppInsightsByTemplate(ctx context.Context, arg GetTemplateAppInsightsByTemplateParams) ([]GetTemplateAppInsightsByTemplateRow, error)
 	GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error)
+	GetTemplateBuildDurationStats(ctx context.Context, arg GetTemplateBuildDurationStatsParams) ([]TemplateBuildDurationStat, error)
 	GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error)
+	// Locks the template until the end of the transaction, so that concurrent
+	// read-modify-write updates of its ACL don't overwrite each other.
+	GetTemplateByIDForUpdate(ctx context.Context, id uuid.UUID) (TemplateTable, error)
 	GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error)
 	GetTemplateDAUs(ctx context.Context, arg GetTemplateDAUsParams) ([]GetTemplateDAUsRow, error)
 	// GetTemplateInsights returns the aggregate user-produced usage of all
@@ -332,24 +476,40 @@ type sqlcQuerier interface {
 	// created in the timeframe and return the aggregate usage counts of parameter
 	// values.
 	GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error)
+	GetTemplatePresetByID(ctx context.Context, id uuid.UUID) (TemplatePreset, error)
+	GetTemplatePresetGroupDefaultsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplatePresetGroupDefault, error)
+	GetTemplatePresetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplatePreset, error)
 	// GetTemplatePresetsWithPrebuilds retrieves template versions with configured presets and prebuilds.
 	// It also returns the number of desired instances for each preset.
 	// If template_id is specified, only template versions associated with that template will be returned.
 	GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]GetTemplatePresetsWithPrebuildsRow, error)
+	GetTemplateReleaseChannel(ctx context.Context, arg GetTemplateReleaseChannelParams) (TemplateReleaseChannel, error)
+	GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateReleaseChannel, error)
+	GetTemplateSharesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateShare, error)
 	GetTemplateUsageStats(ctx context.Context, arg GetTemplateUsageStatsParams) ([]TemplateUsageStat, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error)
 	GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error)
+	// Returns the number of completed and failed builds of each version of the
+	// template that were created since the given time.
+	GetTemplateVersionRolloutBuildCounts(ctx context.Context, arg GetTemplateVersionRolloutBuildCountsParams) ([]GetTemplateVersionRolloutBuildCountsRow, error)
+	GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateVersionRollout, error)
 	GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionTerraformValue, error)
 	GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error)
 	GetTemplateVersionWorkspaceTags(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionWorkspaceTag, error)
 	GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error)
 	GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error)
 	GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error)
+	GetTemplateWorkspaceNamingPolicy(ctx context.Context, templateID uuid.UUID) (WorkspaceNamingPolicy, error)
 	GetTemplates(ctx context.Context) ([]Template, error)
+	// Returns the templates that are shared into the organization, excluding
+	// deleted templates.
+	GetTemplatesSharedWithOrganization(ctx context.Context, organizationID uuid.UUID) ([]Template, error)
 	GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error)
 	GetUnexpiredLicenses(ctx context.Context) ([]License, error)
+	GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentHealthEvent, error)
+	GetUserActivityExportByID(ctx context.Context, id uuid.UUID) (UserActivityExport, error)
 	// GetUserActivityInsights returns the ranking with top active users.
 	// The result can be filtered on template_ids, meaning only user data
 	// from workspaces based on those templates will be included.
@@ -361,6 +521,11 @@ type sqlcQuerier interface {
 	GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserCount(ctx context.Context, includeSystem bool) (int64, error)
+	// GetUserDailyActivity returns the activity of each user in each UTC day that
+	// starts within the given period: how long they were connected to
+	// workspaces, how many workspace builds they started and which apps they
+	// used. Days in which a user had no activity are omitted.
+	GetUserDailyActivity(ctx context.Context, arg GetUserDailyActivityParams) ([]GetUserDailyActivityRow, error)
 	// GetUserLatencyInsights returns the median and 95th percentile connection
 	// latency that users have experienced. The result can be filtered on
 	// template_ids, meaning only user data from workspaces based on those templates
@@ -369,6 +534,8 @@ type sqlcQuerier interface {
 	GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error)
 	GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error)
 	GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error)
+	GetUserNotificationBuildDurationThreshold(ctx context.Context, userID uuid.UUID) (string, error)
+	GetUserNotificationDigestFrequency(ctx context.Context, userID uuid.UUID) (string, error)
 	GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error)
 	// GetUserStatusCounts returns the count of users in each status over time.
 	// The time range is inclusively defined by the start_time and end_time parameters.
@@ -392,17 +559,28 @@ type sqlcQuerier interface {
 	// to look up references to actions. eg. a user could build a workspace
 	// for another user, then be deleted... we still want them to appear!
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
+	// Returns the users whose oldest batched notification message is older than
+	// their digest frequency. Digests are sent daily unless configured otherwise.
+	GetUsersWithDueNotificationDigests(ctx context.Context, now time.Time) ([]GetUsersWithDueNotificationDigestsRow, error)
 	GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]WebpushSubscription, error)
 	GetWebpushVAPIDKeys(ctx context.Context) (GetWebpushVAPIDKeysRow, error)
 	GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error)
 	GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error)
 	GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (WorkspaceAgent, error)
+	GetWorkspaceAgentConnectionQualityByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentConnectionQuality, error)
+	// Counts the connections to workspace agents reported after @updated_after by
+	// whether they are direct or relayed, and the DERP region they are relayed
+	// through.
+	GetWorkspaceAgentConnectionTypeCounts(ctx context.Context, updatedAfter time.Time) ([]GetWorkspaceAgentConnectionTypeCountsRow, error)
 	GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentDevcontainer, error)
 	GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentLifecycleStateByIDRow, error)
 	GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentLogSource, error)
 	GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWorkspaceAgentLogsAfterParams) ([]WorkspaceAgentLog, error)
 	GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorkspaceAgentMetadataParams) ([]WorkspaceAgentMetadatum, error)
 	GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	// Returns the resource usage of the agents of all builds of the workspace, so
+	// that usage can be followed across builds by agent name.
+	GetWorkspaceAgentResourceUsageByWorkspaceID(ctx context.Context, arg GetWorkspaceAgentResourceUsageByWorkspaceIDParams) ([]GetWorkspaceAgentResourceUsageByWorkspaceIDRow, error)
 	GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]GetWorkspaceAgentScriptTimingsByBuildIDRow, error)
 	GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error)
 	GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error)
@@ -420,21 +598,58 @@ type sqlcQuerier interface {
 	GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error)
+	GetWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (WorkspaceBuildArchive, error)
 	GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error)
+	GetWorkspaceBuildIdempotencyKey(ctx context.Context, arg GetWorkspaceBuildIdempotencyKeyParams) (WorkspaceBuildIdempotencyKey, error)
+	GetWorkspaceBuildInterimStateByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (WorkspaceBuildInterimState, error)
+	// Returns the outcome of every workspace build whose provisioner job
+	// completed in the given time range, along with the template of the
+	// workspace.
+	GetWorkspaceBuildOutcomesCompletedBetween(ctx context.Context, arg GetWorkspaceBuildOutcomesCompletedBetweenParams) ([]GetWorkspaceBuildOutcomesCompletedBetweenRow, error)
 	GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error)
 	GetWorkspaceBuildParametersByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]WorkspaceBuildParameter, error)
+	GetWorkspaceBuildQueueByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceBuildQueue, error)
+	GetWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) (WorkspaceBuildQueue, error)
 	GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]GetWorkspaceBuildStatsByTemplatesRow, error)
 	GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error)
 	GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error)
+	// Returns builds that are not the latest build of their workspace, whose
+	// job completed before the given time and that are not archived yet,
+	// oldest first.
+	GetWorkspaceBuildsToArchive(ctx context.Context, arg GetWorkspaceBuildsToArchiveParams) ([]GetWorkspaceBuildsToArchiveRow, error)
 	GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (Workspace, error)
 	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace, error)
+	// Locks the workspace until the end of the transaction, so that concurrent
+	// read-modify-write updates of its ACL don't overwrite each other.
+	GetWorkspaceByIDForUpdate(ctx context.Context, id uuid.UUID) (WorkspaceTable, error)
+	GetWorkspaceByOrganizationIDAndName(ctx context.Context, arg GetWorkspaceByOrganizationIDAndNameParams) (Workspace, error)
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
 	GetWorkspaceByResourceID(ctx context.Context, resourceID uuid.UUID) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	// GetWorkspaceDailyCosts returns the daily costs of workspaces in the buckets
+	// that start within the given period, along with the names of the workspaces,
+	// their owners and their organizations. The result can be filtered on
+	// template_ids, meaning only the costs of workspaces based on those templates
+	// will be included.
+	GetWorkspaceDailyCosts(ctx context.Context, arg GetWorkspaceDailyCostsParams) ([]GetWorkspaceDailyCostsRow, error)
+	// Returns the latest builds of stopped or failed workspaces that have not been
+	// checked for drift since @checked_before, least recently checked first.
+	GetWorkspaceDriftCheckCandidates(ctx context.Context, arg GetWorkspaceDriftCheckCandidatesParams) ([]GetWorkspaceDriftCheckCandidatesRow, error)
+	GetWorkspaceDriftChecksByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetWorkspaceDriftChecksByWorkspaceIDsRow, error)
+	GetWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceLabel, error)
+	// Returns the current and lifted locks of a workspace, newest first.
+	GetWorkspaceLocksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceLock, error)
+	GetWorkspaceMigrationByID(ctx context.Context, id uuid.UUID) (WorkspaceMigration, error)
+	GetWorkspaceMigrationsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceMigration, error)
 	GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceModule, error)
 	GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceModule, error)
+	GetWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspacePrebuildReservation, error)
+	// GetWorkspacePrebuildReservationsByTemplateID returns the reservations of the prebuilt workspaces of a template
+	// which have been neither claimed nor deleted, including expired ones.
+	GetWorkspacePrebuildReservationsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspacePrebuildReservation, error)
+	GetWorkspaceProvisionerAffinity(ctx context.Context, workspaceID uuid.UUID) (WorkspaceProvisionerAffinity, error)
 	GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
 	// Finds a workspace proxy that has an access URL or app hostname that matches
 	// the provided hostname. This is to check if a hostname matches any workspace
@@ -446,12 +661,16 @@ type sqlcQuerier interface {
 	GetWorkspaceProxyByHostname(ctx context.Context, arg GetWorkspaceProxyByHostnameParams) (WorkspaceProxy, error)
 	GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error)
 	GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error)
+	GetWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceReleaseChannel, error)
 	GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (WorkspaceResource, error)
 	GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResourceMetadatum, error)
 	GetWorkspaceResourceMetadataCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResourceMetadatum, error)
 	GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error)
+	GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error)
+	GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]WorkspaceSessionRecordingChunk, error)
+	GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSessionRecording, error)
 	GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error)
 	// build_params is used to filter by build parameters if present.
 	// It has to be a CTE because the set returning function 'unnest' cannot
@@ -463,11 +682,13 @@ type sqlcQuerier interface {
 	// Determines if the template versions table has any rows with has_ai_task = TRUE.
 	HasTemplateVersionsWithAITask(ctx context.Context) (bool, error)
 	InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error)
+	InsertAccessRequest(ctx context.Context, arg InsertAccessRequestParams) (AccessRequest, error)
 	// We use the organization_id as the id
 	// for simplicity since all users is
 	// every member of the org.
 	InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error)
 	InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error)
+	InsertBuildAlertRule(ctx context.Context, arg InsertBuildAlertRuleParams) (BuildAlertRule, error)
 	InsertCryptoKey(ctx context.Context, arg InsertCryptoKeyParams) (CryptoKey, error)
 	InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error)
 	InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error
@@ -480,6 +701,7 @@ type sqlcQuerier interface {
 	InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error
 	InsertInboxNotification(ctx context.Context, arg InsertInboxNotificationParams) (InboxNotification, error)
 	InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error)
+	InsertLoginDeviceCode(ctx context.Context, arg InsertLoginDeviceCodeParams) (LoginDeviceCode, error)
 	InsertMemoryResourceMonitor(ctx context.Context, arg InsertMemoryResourceMonitorParams) (WorkspaceAgentMemoryResourceMonitor, error)
 	// Inserts any group by name that does not exist. All new groups are given
 	// a random uuid, are inserted into the same organization. They have the default
@@ -495,19 +717,25 @@ type sqlcQuerier interface {
 	InsertPreset(ctx context.Context, arg InsertPresetParams) (TemplateVersionPreset, error)
 	InsertPresetParameters(ctx context.Context, arg InsertPresetParametersParams) ([]TemplateVersionPresetParameter, error)
 	InsertPresetPrebuildSchedule(ctx context.Context, arg InsertPresetPrebuildScheduleParams) (TemplateVersionPresetPrebuildSchedule, error)
+	InsertProvisionerBuildPause(ctx context.Context, arg InsertProvisionerBuildPauseParams) (ProvisionerBuildPause, error)
 	InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error)
+	InsertProvisionerJobLogArchive(ctx context.Context, arg InsertProvisionerJobLogArchiveParams) (ProvisionerJobLogArchive, error)
 	InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
 	InsertProvisionerJobTimings(ctx context.Context, arg InsertProvisionerJobTimingsParams) ([]ProvisionerJobTiming, error)
 	InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error)
+	InsertProvisionerReservation(ctx context.Context, arg InsertProvisionerReservationParams) (ProvisionerReservation, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
+	InsertServiceAccount(ctx context.Context, arg InsertServiceAccountParams) (ServiceAccount, error)
 	InsertTelemetryItemIfNotExists(ctx context.Context, arg InsertTelemetryItemIfNotExistsParams) error
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) error
+	InsertTemplatePreset(ctx context.Context, arg InsertTemplatePresetParams) (TemplatePreset, error)
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error
 	InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error)
 	InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg InsertTemplateVersionTerraformValuesByJobIDParams) error
 	InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error)
 	InsertTemplateVersionWorkspaceTag(ctx context.Context, arg InsertTemplateVersionWorkspaceTagParams) (TemplateVersionWorkspaceTag, error)
 	InsertUser(ctx context.Context, arg InsertUserParams) (User, error)
+	InsertUserActivityExport(ctx context.Context, arg InsertUserActivityExportParams) (UserActivityExport, error)
 	// InsertUserGroupsByID adds a user to all provided groups, if they exist.
 	// If there is a conflict, the user is already a member
 	InsertUserGroupsByID(ctx context.Context, arg InsertUserGroupsByIDParams) ([]uuid.UUID, error)
@@ -519,6 +747,10 @@ type sqlcQuerier interface {
 	InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (WorkspaceTable, error)
 	InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error)
 	InsertWorkspaceAgentDevcontainers(ctx context.Context, arg InsertWorkspaceAgentDevcontainersParams) ([]WorkspaceAgentDevcontainer, error)
+	// Records a condition detected by an agent. If the agent already has an
+	// ongoing event of the same kind for the volume, nothing is inserted and no
+	// rows are returned.
+	InsertWorkspaceAgentHealthEvent(ctx context.Context, arg InsertWorkspaceAgentHealthEventParams) (WorkspaceAgentHealthEvent, error)
 	InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error)
 	InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error)
 	InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error
@@ -528,15 +760,33 @@ type sqlcQuerier interface {
 	InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error
 	InsertWorkspaceAppStatus(ctx context.Context, arg InsertWorkspaceAppStatusParams) (WorkspaceAppStatus, error)
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error
+	InsertWorkspaceBuildArchive(ctx context.Context, arg InsertWorkspaceBuildArchiveParams) (WorkspaceBuildArchive, error)
+	// Records that the owner of a workspace build was notified about its
+	// duration. No rows are affected if the build was already notified, e.g. by
+	// another replica.
+	InsertWorkspaceBuildDurationNotification(ctx context.Context, arg InsertWorkspaceBuildDurationNotificationParams) (int64, error)
+	// Reserves the key before the build of the request is created. A concurrent
+	// insert of the same key waits for the transaction that reserved it.
+	InsertWorkspaceBuildIdempotencyKey(ctx context.Context, arg InsertWorkspaceBuildIdempotencyKeyParams) error
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
+	InsertWorkspaceBuildQueueEntry(ctx context.Context, arg InsertWorkspaceBuildQueueEntryParams) (WorkspaceBuildQueue, error)
+	InsertWorkspaceLabels(ctx context.Context, arg InsertWorkspaceLabelsParams) error
+	InsertWorkspaceLock(ctx context.Context, arg InsertWorkspaceLockParams) (WorkspaceLock, error)
+	InsertWorkspaceMigration(ctx context.Context, arg InsertWorkspaceMigrationParams) (WorkspaceMigration, error)
 	InsertWorkspaceModule(ctx context.Context, arg InsertWorkspaceModuleParams) (WorkspaceModule, error)
 	InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error)
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
 	InsertWorkspaceResourceMetadata(ctx context.Context, arg InsertWorkspaceResourceMetadataParams) ([]WorkspaceResourceMetadatum, error)
+	InsertWorkspaceSessionRecording(ctx context.Context, arg InsertWorkspaceSessionRecordingParams) (WorkspaceSessionRecording, error)
+	InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg InsertWorkspaceSessionRecordingChunkParams) (WorkspaceSessionRecordingChunk, error)
+	// Returns whether the user is a member of the group of the rollout of the
+	// template.
+	IsTemplateVersionRolloutCohortMember(ctx context.Context, arg IsTemplateVersionRolloutCohortMemberParams) (bool, error)
 	ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error)
 	ListProvisionerKeysByOrganizationExcludeReserved(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error)
 	ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error)
 	MarkAllInboxNotificationsAsRead(ctx context.Context, arg MarkAllInboxNotificationsAsReadParams) error
+	MarkNotificationMessagesDigested(ctx context.Context, arg MarkNotificationMessagesDigestedParams) (int64, error)
 	OIDCClaimFieldValues(ctx context.Context, arg OIDCClaimFieldValuesParams) ([]string, error)
 	// OIDCClaimFields returns a list of distinct keys in the the merged_claims fields.
 	// This query is used to generate the list of available sync fields for idp sync settings.
@@ -551,7 +801,12 @@ type sqlcQuerier interface {
 	RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error)
 	RemoveUserFromAllGroups(ctx context.Context, userID uuid.UUID) error
 	RemoveUserFromGroups(ctx context.Context, arg RemoveUserFromGroupsParams) ([]uuid.UUID, error)
+	ResolveWorkspaceAgentHealthEvent(ctx context.Context, arg ResolveWorkspaceAgentHealthEventParams) (int64, error)
 	RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error
+	RotateProvisionerKey(ctx context.Context, arg RotateProvisionerKeyParams) (ProvisionerKey, error)
+	// Returns the logs of a job with an ID greater than created_after that match
+	// all of the given filters. Unset filters match every log.
+	SearchProvisionerJobLogs(ctx context.Context, arg SearchProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
 	// Non blocking lock. Returns true if the lock was acquired, false otherwise.
 	//
 	// This must be called from within a transaction. The lock will be automatically
@@ -560,7 +815,12 @@ type sqlcQuerier interface {
 	// This will always work regardless of the current state of the template version.
 	UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error
 	UnfavoriteWorkspace(ctx context.Context, id uuid.UUID) error
+	UnlockWorkspace(ctx context.Context, arg UnlockWorkspaceParams) (WorkspaceLock, error)
 	UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDParams) error
+	UpdateAccessRequestExpiredByID(ctx context.Context, id uuid.UUID) (AccessRequest, error)
+	UpdateAccessRequestReviewByID(ctx context.Context, arg UpdateAccessRequestReviewByIDParams) (AccessRequest, error)
+	UpdateBuildAlertRuleByID(ctx context.Context, arg UpdateBuildAlertRuleByIDParams) (BuildAlertRule, error)
+	UpdateBuildAlertRuleEvaluation(ctx context.Context, arg UpdateBuildAlertRuleEvaluationParams) error
 	UpdateCryptoKeyDeletesAt(ctx context.Context, arg UpdateCryptoKeyDeletesAtParams) (CryptoKey, error)
 	UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleParams) (CustomRole, error)
 	UpdateExternalAuthLink(ctx context.Context, arg UpdateExternalAuthLinkParams) (ExternalAuthLink, error)
@@ -576,12 +836,18 @@ type sqlcQuerier interface {
 	UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error)
 	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error)
 	UpdateOrganizationDeletedByID(ctx context.Context, arg UpdateOrganizationDeletedByIDParams) error
+	// Restarts the pending timeout of the jobs that have not been acquired yet,
+	// either in an organization or, if organization_id is NULL, across the
+	// deployment. It is used when builds are resumed so that jobs held by the
+	// pause are not reaped right away.
+	UpdatePendingProvisionerJobsUpdatedAt(ctx context.Context, arg UpdatePendingProvisionerJobsUpdatedAtParams) ([]ProvisionerJob, error)
 	UpdatePresetPrebuildStatus(ctx context.Context, arg UpdatePresetPrebuildStatusParams) error
 	UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error
 	UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error
 	UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error
 	UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error
 	UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteWithStartedAtByIDParams) error
+	UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg UpdateProvisionerKeyLastUsedAtParams) error
 	UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error)
 	UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg UpdateTailnetPeerStatusByCoordinatorParams) error
 	UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error
@@ -589,12 +855,15 @@ type sqlcQuerier interface {
 	UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error
 	UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error
 	UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error
+	UpdateTemplatePresetByID(ctx context.Context, arg UpdateTemplatePresetByIDParams) (TemplatePreset, error)
 	UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error
 	UpdateTemplateVersionAITaskByJobID(ctx context.Context, arg UpdateTemplateVersionAITaskByJobIDParams) error
 	UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error
 	UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error
 	UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error
+	UpdateTemplateVersionVariableValue(ctx context.Context, arg UpdateTemplateVersionVariableValueParams) error
 	UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg UpdateTemplateWorkspacesLastUsedAtParams) error
+	UpdateUserActivityExportStatusByID(ctx context.Context, arg UpdateUserActivityExportStatusByIDParams) (UserActivityExport, error)
 	UpdateUserDeletedByID(ctx context.Context, id uuid.UUID) error
 	UpdateUserGithubComUserID(ctx context.Context, arg UpdateUserGithubComUserIDParams) error
 	UpdateUserHashedOneTimePasscode(ctx context.Context, arg UpdateUserHashedOneTimePasscodeParams) error
@@ -603,6 +872,11 @@ type sqlcQuerier interface {
 	UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error)
 	UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error)
 	UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error)
+	UpdateUserNotificationBuildDurationThreshold(ctx context.Context, arg UpdateUserNotificationBuildDurationThresholdParams) (UserConfig, error)
+	UpdateUserNotificationDigestFrequency(ctx context.Context, arg UpdateUserNotificationDigestFrequencyParams) (UserConfig, error)
+	// Preferences which don't exist yet are created with the default enablement
+	// of their template, so opting into a digest doesn't enable a notification.
+	UpdateUserNotificationDigestPreferences(ctx context.Context, arg UpdateUserNotificationDigestPreferencesParams) (int64, error)
 	UpdateUserNotificationPreferences(ctx context.Context, arg UpdateUserNotificationPreferencesParams) (int64, error)
 	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
 	UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error)
@@ -612,6 +886,7 @@ type sqlcQuerier interface {
 	UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error)
 	UpdateVolumeResourceMonitor(ctx context.Context, arg UpdateVolumeResourceMonitorParams) error
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (WorkspaceTable, error)
+	UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
 	UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error
 	UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg UpdateWorkspaceAgentLogOverflowByIDParams) error
@@ -623,14 +898,23 @@ type sqlcQuerier interface {
 	UpdateWorkspaceBuildAITaskByID(ctx context.Context, arg UpdateWorkspaceBuildAITaskByIDParams) error
 	UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error
 	UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error
+	UpdateWorkspaceBuildIdempotencyKeyBuildID(ctx context.Context, arg UpdateWorkspaceBuildIdempotencyKeyBuildIDParams) error
 	UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error
+	UpdateWorkspaceBuildQueueEntryFailed(ctx context.Context, arg UpdateWorkspaceBuildQueueEntryFailedParams) error
 	UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateWorkspaceDeletedByIDParams) error
 	UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg UpdateWorkspaceDormantDeletingAtParams) (WorkspaceTable, error)
+	UpdateWorkspaceDriftCheckByJobID(ctx context.Context, arg UpdateWorkspaceDriftCheckByJobIDParams) (WorkspaceDriftCheck, error)
 	UpdateWorkspaceLastUsedAt(ctx context.Context, arg UpdateWorkspaceLastUsedAtParams) error
+	UpdateWorkspaceMigrationSessionToken(ctx context.Context, arg UpdateWorkspaceMigrationSessionTokenParams) error
+	UpdateWorkspaceMigrationStatus(ctx context.Context, arg UpdateWorkspaceMigrationStatusParams) (WorkspaceMigration, error)
 	UpdateWorkspaceNextStartAt(ctx context.Context, arg UpdateWorkspaceNextStartAtParams) error
 	// This allows editing the properties of a workspace proxy.
 	UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error)
 	UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error
+	// Adds the size of newly recorded output to the recording, and marks it as
+	// ended if an end time is given. Once set, the end time is never changed.
+	UpdateWorkspaceSessionRecording(ctx context.Context, arg UpdateWorkspaceSessionRecordingParams) (WorkspaceSessionRecording, error)
+	UpdateWorkspaceSessionRecordingChunkData(ctx context.Context, arg UpdateWorkspaceSessionRecordingChunkDataParams) error
 	UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspaceTTLParams) error
 	UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Context, arg UpdateWorkspacesDormantDeletingAtByTemplateIDParams) ([]WorkspaceTable, error)
 	UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg UpdateWorkspacesTTLByTemplateIDParams) error
@@ -638,11 +922,13 @@ type sqlcQuerier interface {
 	UpsertAppSecurityKey(ctx context.Context, value string) error
 	UpsertApplicationName(ctx context.Context, value string) error
 	UpsertCoordinatorResumeTokenSigningKey(ctx context.Context, value string) error
+	UpsertDBPurgeStats(ctx context.Context, value string) error
 	// The default proxy is implied and not actually stored in the database.
 	// So we need to store it's configuration here for display purposes.
 	// The functional values are immutable and controlled implicitly.
 	UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error
 	UpsertHealthSettings(ctx context.Context, value string) error
+	UpsertJobReaperLastRunAt(ctx context.Context, value string) error
 	UpsertLastUpdateCheck(ctx context.Context, value string) error
 	UpsertLogoURL(ctx context.Context, value string) error
 	// Insert or update notification report generator logs with recent activity.
@@ -650,7 +936,9 @@ type sqlcQuerier interface {
 	UpsertNotificationsSettings(ctx context.Context, value string) error
 	UpsertOAuth2GithubDefaultEligible(ctx context.Context, eligible bool) error
 	UpsertOAuthSigningKey(ctx context.Context, value string) error
+	UpsertOrganizationWorkspaceNamingPolicy(ctx context.Context, arg UpsertOrganizationWorkspaceNamingPolicyParams) (WorkspaceNamingPolicy, error)
 	UpsertProvisionerDaemon(ctx context.Context, arg UpsertProvisionerDaemonParams) (ProvisionerDaemon, error)
+	UpsertReadOnlySettings(ctx context.Context, value string) error
 	UpsertRuntimeConfig(ctx context.Context, arg UpsertRuntimeConfigParams) error
 	UpsertTailnetAgent(ctx context.Context, arg UpsertTailnetAgentParams) (TailnetAgent, error)
 	UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error)
@@ -659,19 +947,55 @@ type sqlcQuerier interface {
 	UpsertTailnetPeer(ctx context.Context, arg UpsertTailnetPeerParams) (TailnetPeer, error)
 	UpsertTailnetTunnel(ctx context.Context, arg UpsertTailnetTunnelParams) (TailnetTunnel, error)
 	UpsertTelemetryItem(ctx context.Context, arg UpsertTelemetryItemParams) error
+	// This query aggregates the durations of successful workspace builds into
+	// daily buckets per template and transition. The result is stored in the
+	// template_build_duration_stats table. The most recent bucket and the one
+	// before it are recomputed on every run, since builds keep completing in them.
+	UpsertTemplateBuildDurationStats(ctx context.Context) error
+	// A group has at most one default preset per template, so making a preset the
+	// default of a group replaces the previous default.
+	UpsertTemplatePresetGroupDefault(ctx context.Context, arg UpsertTemplatePresetGroupDefaultParams) error
+	// Publishes a template version to a release channel of its template.
+	UpsertTemplateReleaseChannel(ctx context.Context, arg UpsertTemplateReleaseChannelParams) (TemplateReleaseChannel, error)
+	// Sharing a template into an organization it is already shared into keeps the
+	// original share.
+	UpsertTemplateShare(ctx context.Context, arg UpsertTemplateShareParams) (TemplateShare, error)
 	// This query aggregates the workspace_agent_stats and workspace_app_stats data
 	// into a single table for efficient storage and querying. Half-hour buckets are
 	// used to store the data, and the minutes are summed for each user and template
 	// combination. The result is stored in the template_usage_stats table.
 	UpsertTemplateUsageStats(ctx context.Context) error
+	// Starts or updates the rollout of a template. Switching to another version
+	// starts a new rollout, so its creator and start time are reset.
+	UpsertTemplateVersionRollout(ctx context.Context, arg UpsertTemplateVersionRolloutParams) (TemplateVersionRollout, error)
+	UpsertTemplateWorkspaceNamingPolicy(ctx context.Context, arg UpsertTemplateWorkspaceNamingPolicyParams) (WorkspaceNamingPolicy, error)
 	UpsertWebpushVAPIDKeys(ctx context.Context, arg UpsertWebpushVAPIDKeysParams) error
+	UpsertWorkspaceAgentConnectionQuality(ctx context.Context, arg UpsertWorkspaceAgentConnectionQualityParams) error
 	UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	// Adds a sample to the bucket it was collected in, keeping a running average
+	// and maximum of the usage.
+	UpsertWorkspaceAgentResourceUsage(ctx context.Context, arg UpsertWorkspaceAgentResourceUsageParams) error
 	UpsertWorkspaceApp(ctx context.Context, arg UpsertWorkspaceAppParams) (WorkspaceApp, error)
 	//
 	// The returned boolean, new_or_stale, can be used to deduce if a new session
 	// was started. This means that a new row was inserted (no previous session) or
 	// the updated_at is older than stale interval.
 	UpsertWorkspaceAppAuditSession(ctx context.Context, arg UpsertWorkspaceAppAuditSessionParams) (bool, error)
+	UpsertWorkspaceBuildInterimState(ctx context.Context, arg UpsertWorkspaceBuildInterimStateParams) error
+	// This query prorates the daily cost of the resources of each successful
+	// workspace build over the time it was the latest successful build of its
+	// workspace, and stores the cost accrued by each workspace in daily buckets
+	// in the workspace_daily_costs table. The most recent bucket and the one
+	// before it are recomputed on every run, since costs keep accruing in them.
+	UpsertWorkspaceDailyCosts(ctx context.Context) error
+	// Replaces the drift check of the workspace with a new check, clearing the
+	// results of the previous one.
+	UpsertWorkspaceDriftCheck(ctx context.Context, arg UpsertWorkspaceDriftCheckParams) error
+	UpsertWorkspacePrebuildReservation(ctx context.Context, arg UpsertWorkspacePrebuildReservationParams) (WorkspacePrebuildReservation, error)
+	// Records the provisioner daemon that built a workspace successfully, so
+	// the next build of the same template version prefers it.
+	UpsertWorkspaceProvisionerAffinity(ctx context.Context, arg UpsertWorkspaceProvisionerAffinityParams) error
+	UpsertWorkspaceReleaseChannel(ctx context.Context, arg UpsertWorkspaceReleaseChannelParams) error
 }
 
 var _ sqlcQuerier = (*sqlQuerier)(nil)
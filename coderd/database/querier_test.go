@@ -2827,6 +2827,93 @@ func TestGetProvisionerJobsByIDsWithQueuePosition_OrderValidation(t *testing.T)
 	assert.EqualValues(t, []int64{1, 2, 3, 4, 5, 6}, queuePositions, "expected queue positions to be set correctly")
 }
 
+func TestAcquireProvisionerJobAffinity(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	ctx := testutil.Context(t, testutil.WaitShort)
+	now := dbtime.Now()
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	tpl := dbgen.Template(t, db, database.Template{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	oldVersion := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	newVersion := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	daemon := dbgen.ProvisionerDaemon(t, db, database.ProvisionerDaemon{
+		OrganizationID: org.ID,
+		Provisioners:   []database.ProvisionerType{database.ProvisionerTypeEcho},
+		Tags:           database.StringMap{},
+	})
+
+	// queueBuild queues a build of a new workspace on the new template version.
+	// If affinityVersion is set, the workspace was last built by the daemon on
+	// that version.
+	queueBuild := func(createdAt time.Time, affinityVersion uuid.UUID) database.ProvisionerJob {
+		ws := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			CreatedAt:      createdAt,
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+			Tags:           database.StringMap{},
+		})
+		dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       ws.ID,
+			TemplateVersionID: newVersion.ID,
+			InitiatorID:       user.ID,
+			JobID:             job.ID,
+		})
+		if affinityVersion != uuid.Nil {
+			err := db.UpsertWorkspaceProvisionerAffinity(ctx, database.UpsertWorkspaceProvisionerAffinityParams{
+				WorkspaceID:         ws.ID,
+				ProvisionerDaemonID: daemon.ID,
+				TemplateVersionID:   affinityVersion,
+				UpdatedAt:           now,
+			})
+			require.NoError(t, err)
+		}
+		return job
+	}
+	acquire := func(workerID uuid.UUID) database.ProvisionerJob {
+		job, err := db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			OrganizationID:  org.ID,
+			StartedAt:       sql.NullTime{Time: now, Valid: true},
+			Types:           database.AllProvisionerTypeValues(),
+			WorkerID:        uuid.NullUUID{UUID: workerID, Valid: true},
+			ProvisionerTags: json.RawMessage("{}"),
+		})
+		require.NoError(t, err)
+		return job
+	}
+
+	oldestJob := queueBuild(now.Add(-3*time.Minute), uuid.Nil)
+	// The workspace was last built on another template version, so the
+	// daemon's cache doesn't help.
+	staleJob := queueBuild(now.Add(-2*time.Minute), oldVersion.ID)
+	affineJob := queueBuild(now.Add(-time.Minute), newVersion.ID)
+
+	// The daemon picks up the job it has affinity to first...
+	require.Equal(t, affineJob.ID, acquire(daemon.ID).ID)
+	// ...then falls back to the oldest job.
+	require.Equal(t, oldestJob.ID, acquire(daemon.ID).ID)
+	// Other daemons aren't affected by the affinity.
+	require.Equal(t, staleJob.ID, acquire(uuid.New()).ID)
+}
+
 func TestGroupRemovalTrigger(t *testing.T) {
 	t.Parallel()
 
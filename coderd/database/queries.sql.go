@@ -15,6 +15,299 @@ import (
 	"github.com/sqlc-dev/pqtype"
 )
 
+const getAccessRequestByID = `-- name: GetAccessRequestByID :one
+SELECT
+	id, organization_id, requester_id, resource_type, resource_id, role, reason, duration_ms, status, previous_role, reviewed_by, reviewed_at, expires_at, created_at
+FROM
+	access_requests
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetAccessRequestByID(ctx context.Context, id uuid.UUID) (AccessRequest, error) {
+	row := q.db.QueryRowContext(ctx, getAccessRequestByID, id)
+	var i AccessRequest
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.RequesterID,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.Role,
+		&i.Reason,
+		&i.DurationMs,
+		&i.Status,
+		&i.PreviousRole,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAccessRequests = `-- name: GetAccessRequests :many
+SELECT
+	id, organization_id, requester_id, resource_type, resource_id, role, reason, duration_ms, status, previous_role, reviewed_by, reviewed_at, expires_at, created_at
+FROM
+	access_requests
+WHERE
+	CASE
+		WHEN $1 :: text != '' THEN
+			status :: text = $1 :: text
+		ELSE true
+	END
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			requester_id = $2
+		ELSE true
+	END
+ORDER BY
+	created_at DESC
+`
+
+type GetAccessRequestsParams struct {
+	Status      string    `db:"status" json:"status"`
+	RequesterID uuid.UUID `db:"requester_id" json:"requester_id"`
+}
+
+func (q *sqlQuerier) GetAccessRequests(ctx context.Context, arg GetAccessRequestsParams) ([]AccessRequest, error) {
+	rows, err := q.db.QueryContext(ctx, getAccessRequests, arg.Status, arg.RequesterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AccessRequest
+	for rows.Next() {
+		var i AccessRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.RequesterID,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.Role,
+			&i.Reason,
+			&i.DurationMs,
+			&i.Status,
+			&i.PreviousRole,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredAccessRequests = `-- name: GetExpiredAccessRequests :many
+SELECT
+	id, organization_id, requester_id, resource_type, resource_id, role, reason, duration_ms, status, previous_role, reviewed_by, reviewed_at, expires_at, created_at
+FROM
+	access_requests
+WHERE
+	status = 'approved'
+	AND expires_at <= $1 :: timestamptz
+ORDER BY
+	expires_at ASC
+`
+
+func (q *sqlQuerier) GetExpiredAccessRequests(ctx context.Context, now time.Time) ([]AccessRequest, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredAccessRequests, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AccessRequest
+	for rows.Next() {
+		var i AccessRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.RequesterID,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.Role,
+			&i.Reason,
+			&i.DurationMs,
+			&i.Status,
+			&i.PreviousRole,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertAccessRequest = `-- name: InsertAccessRequest :one
+INSERT INTO
+	access_requests (
+		id,
+		organization_id,
+		requester_id,
+		resource_type,
+		resource_id,
+		role,
+		reason,
+		duration_ms,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, organization_id, requester_id, resource_type, resource_id, role, reason, duration_ms, status, previous_role, reviewed_by, reviewed_at, expires_at, created_at
+`
+
+type InsertAccessRequestParams struct {
+	ID             uuid.UUID                 `db:"id" json:"id"`
+	OrganizationID uuid.UUID                 `db:"organization_id" json:"organization_id"`
+	RequesterID    uuid.UUID                 `db:"requester_id" json:"requester_id"`
+	ResourceType   AccessRequestResourceType `db:"resource_type" json:"resource_type"`
+	ResourceID     uuid.UUID                 `db:"resource_id" json:"resource_id"`
+	Role           string                    `db:"role" json:"role"`
+	Reason         string                    `db:"reason" json:"reason"`
+	DurationMs     int64                     `db:"duration_ms" json:"duration_ms"`
+	CreatedAt      time.Time                 `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertAccessRequest(ctx context.Context, arg InsertAccessRequestParams) (AccessRequest, error) {
+	row := q.db.QueryRowContext(ctx, insertAccessRequest,
+		arg.ID,
+		arg.OrganizationID,
+		arg.RequesterID,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Role,
+		arg.Reason,
+		arg.DurationMs,
+		arg.CreatedAt,
+	)
+	var i AccessRequest
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.RequesterID,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.Role,
+		&i.Reason,
+		&i.DurationMs,
+		&i.Status,
+		&i.PreviousRole,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateAccessRequestExpiredByID = `-- name: UpdateAccessRequestExpiredByID :one
+UPDATE
+	access_requests
+SET
+	status = 'expired'
+WHERE
+	id = $1
+	AND status = 'approved'
+RETURNING id, organization_id, requester_id, resource_type, resource_id, role, reason, duration_ms, status, previous_role, reviewed_by, reviewed_at, expires_at, created_at
+`
+
+func (q *sqlQuerier) UpdateAccessRequestExpiredByID(ctx context.Context, id uuid.UUID) (AccessRequest, error) {
+	row := q.db.QueryRowContext(ctx, updateAccessRequestExpiredByID, id)
+	var i AccessRequest
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.RequesterID,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.Role,
+		&i.Reason,
+		&i.DurationMs,
+		&i.Status,
+		&i.PreviousRole,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateAccessRequestReviewByID = `-- name: UpdateAccessRequestReviewByID :one
+UPDATE
+	access_requests
+SET
+	status = $1,
+	previous_role = $2,
+	reviewed_by = $3,
+	reviewed_at = $4,
+	expires_at = $5
+WHERE
+	id = $6
+	-- Requests can only be reviewed once.
+	AND status = 'pending'
+RETURNING id, organization_id, requester_id, resource_type, resource_id, role, reason, duration_ms, status, previous_role, reviewed_by, reviewed_at, expires_at, created_at
+`
+
+type UpdateAccessRequestReviewByIDParams struct {
+	Status       AccessRequestStatus `db:"status" json:"status"`
+	PreviousRole string              `db:"previous_role" json:"previous_role"`
+	ReviewedBy   uuid.NullUUID       `db:"reviewed_by" json:"reviewed_by"`
+	ReviewedAt   sql.NullTime        `db:"reviewed_at" json:"reviewed_at"`
+	ExpiresAt    sql.NullTime        `db:"expires_at" json:"expires_at"`
+	ID           uuid.UUID           `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateAccessRequestReviewByID(ctx context.Context, arg UpdateAccessRequestReviewByIDParams) (AccessRequest, error) {
+	row := q.db.QueryRowContext(ctx, updateAccessRequestReviewByID,
+		arg.Status,
+		arg.PreviousRole,
+		arg.ReviewedBy,
+		arg.ReviewedAt,
+		arg.ExpiresAt,
+		arg.ID,
+	)
+	var i AccessRequest
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.RequesterID,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.Role,
+		&i.Reason,
+		&i.DurationMs,
+		&i.Status,
+		&i.PreviousRole,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const activityBumpWorkspace = `-- name: ActivityBumpWorkspace :exec
 WITH latest AS (
 	SELECT
@@ -146,7 +439,7 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, bound_identity
 FROM
 	api_keys
 WHERE
@@ -171,13 +464,14 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.BoundIdentity,
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, bound_identity
 FROM
 	api_keys
 WHERE
@@ -210,12 +504,13 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.BoundIdentity,
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, bound_identity FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -240,6 +535,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.BoundIdentity,
 		); err != nil {
 			return nil, err
 		}
@@ -255,7 +551,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, bound_identity FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -285,6 +581,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.BoundIdentity,
 		); err != nil {
 			return nil, err
 		}
@@ -300,7 +597,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, bound_identity FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -325,6 +622,7 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.BoundIdentity,
 		); err != nil {
 			return nil, err
 		}
@@ -353,7 +651,8 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		bound_identity
 	)
 VALUES
 	($1,
@@ -362,7 +661,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, bound_identity
 `
 
 type InsertAPIKeyParams struct {
@@ -378,6 +677,7 @@ type InsertAPIKeyParams struct {
 	LoginType       LoginType   `db:"login_type" json:"login_type"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	BoundIdentity   string      `db:"bound_identity" json:"bound_identity"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -394,6 +694,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		arg.BoundIdentity,
 	)
 	var i APIKey
 	err := row.Scan(
@@ -409,6 +710,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.BoundIdentity,
 	)
 	return i, err
 }
@@ -441,6 +743,134 @@ func (q *sqlQuerier) UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDP
 	return err
 }
 
+const deleteOldAuditLogs = `-- name: DeleteOldAuditLogs :execrows
+DELETE FROM
+	audit_logs
+WHERE
+	(id, "time") IN (
+		SELECT
+			id, "time"
+		FROM
+			audit_logs
+		WHERE
+			"time" < $1 :: timestamptz
+		ORDER BY
+			"time" ASC
+		LIMIT
+			$2 :: int
+	)
+`
+
+type DeleteOldAuditLogsParams struct {
+	Before     time.Time `db:"before" json:"before"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Deletes at most @limit_count audit logs older than @before, oldest first.
+func (q *sqlQuerier) DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditLogsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldAuditLogs, arg.Before, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const dropOldAuditLogsPartitions = `-- name: DropOldAuditLogsPartitions :one
+SELECT
+	drop_time_partitions('audit_logs', $1 :: timestamptz)::integer AS dropped
+`
+
+// Drops the audit_logs partitions that only hold rows older than @before.
+// Returns the number of dropped partitions.
+func (q *sqlQuerier) DropOldAuditLogsPartitions(ctx context.Context, before time.Time) (int32, error) {
+	row := q.db.QueryRowContext(ctx, dropOldAuditLogsPartitions, before)
+	var dropped int32
+	err := row.Scan(&dropped)
+	return dropped, err
+}
+
+const getAuditLogsForExport = `-- name: GetAuditLogsForExport :many
+SELECT
+	audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
+	COALESCE(users.username, '') AS user_username,
+	COALESCE(users.email, '') AS user_email,
+	COALESCE(organizations.name, '') AS organization_name
+FROM
+	audit_logs
+	LEFT JOIN users ON audit_logs.user_id = users.id
+	LEFT JOIN organizations ON audit_logs.organization_id = organizations.id
+WHERE
+	(audit_logs."time", audit_logs.id) > ($1 :: timestamptz, $2 :: uuid)
+	AND audit_logs."time" < $3 :: timestamptz
+ORDER BY
+	audit_logs."time" ASC, audit_logs.id ASC
+LIMIT
+	$4 :: int
+`
+
+type GetAuditLogsForExportParams struct {
+	AfterTime  time.Time `db:"after_time" json:"after_time"`
+	AfterID    uuid.UUID `db:"after_id" json:"after_id"`
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+type GetAuditLogsForExportRow struct {
+	AuditLog         AuditLog `db:"audit_log" json:"audit_log"`
+	UserUsername     string   `db:"user_username" json:"user_username"`
+	UserEmail        string   `db:"user_email" json:"user_email"`
+	OrganizationName string   `db:"organization_name" json:"organization_name"`
+}
+
+// Returns the audit logs logged after the given cursor, which is the time and
+// ID of the last exported log, in the order they are exported.
+func (q *sqlQuerier) GetAuditLogsForExport(ctx context.Context, arg GetAuditLogsForExportParams) ([]GetAuditLogsForExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsForExport,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.BeforeTime,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAuditLogsForExportRow
+	for rows.Next() {
+		var i GetAuditLogsForExportRow
+		if err := rows.Scan(
+			&i.AuditLog.ID,
+			&i.AuditLog.Time,
+			&i.AuditLog.UserID,
+			&i.AuditLog.OrganizationID,
+			&i.AuditLog.Ip,
+			&i.AuditLog.UserAgent,
+			&i.AuditLog.ResourceType,
+			&i.AuditLog.ResourceID,
+			&i.AuditLog.ResourceTarget,
+			&i.AuditLog.Action,
+			&i.AuditLog.Diff,
+			&i.AuditLog.StatusCode,
+			&i.AuditLog.AdditionalFields,
+			&i.AuditLog.RequestID,
+			&i.AuditLog.ResourceIcon,
+			&i.UserUsername,
+			&i.UserEmail,
+			&i.OrganizationName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
 SELECT
     audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
@@ -557,24 +987,49 @@ WHERE
             workspace_builds.reason::text = $11
         ELSE true
     END
+	-- Filter by client_type of connections
+	AND CASE
+		WHEN $12 :: text != '' THEN
+			audit_logs.additional_fields ->> 'client_type' = $12
+		ELSE true
+	END
 	-- Filter request_id
 	AND CASE
-		WHEN $12 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
-			audit_logs.request_id = $12
+		WHEN $13 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			audit_logs.request_id = $13
 		ELSE true
 	END
-
-	-- Authorize Filter clause will be injected below in GetAuthorizedAuditLogsOffset
-	-- @authorize_filter
-ORDER BY
-    "time" DESC
-LIMIT
+	AND CASE
+		-- This allows using the last audit log on a page as a cursor, which
+		-- doesn't degrade on large tables like offsets do.
+		WHEN $14 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The query is ordered by time and then ID, descending, so
+			-- select all rows before the cursor.
+			(audit_logs."time", audit_logs.id) < (
+				SELECT
+					"time", id
+				FROM
+					audit_logs
+				WHERE
+					id = $14
+			)
+		)
+		ELSE true
+	END
+
+	-- Authorize Filter clause will be injected below in GetAuthorizedAuditLogsOffset
+	-- @authorize_filter
+ORDER BY
+    "time" DESC,
+    -- Order by ID as well, so logs with the same time have a stable order.
+    audit_logs.id DESC
+LIMIT
 	-- a limit of 0 means "no limit". The audit log table is unbounded
 	-- in size, and is expected to be quite large. Implement a default
 	-- limit of 100 to prevent accidental excessively large queries.
-	COALESCE(NULLIF($14 :: int, 0), 100)
+	COALESCE(NULLIF($16 :: int, 0), 100)
 OFFSET
-    $13
+    $15
 `
 
 type GetAuditLogsOffsetParams struct {
@@ -589,7 +1044,9 @@ type GetAuditLogsOffsetParams struct {
 	DateFrom       time.Time `db:"date_from" json:"date_from"`
 	DateTo         time.Time `db:"date_to" json:"date_to"`
 	BuildReason    string    `db:"build_reason" json:"build_reason"`
+	ClientType     string    `db:"client_type" json:"client_type"`
 	RequestID      uuid.UUID `db:"request_id" json:"request_id"`
+	AfterID        uuid.UUID `db:"after_id" json:"after_id"`
 	OffsetOpt      int32     `db:"offset_opt" json:"offset_opt"`
 	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
 }
@@ -629,7 +1086,9 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 		arg.DateFrom,
 		arg.DateTo,
 		arg.BuildReason,
+		arg.ClientType,
 		arg.RequestID,
+		arg.AfterID,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -766,6 +1225,368 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
+const deleteBuildAlertRuleByID = `-- name: DeleteBuildAlertRuleByID :exec
+DELETE FROM
+	build_alert_rules
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteBuildAlertRuleByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteBuildAlertRuleByID, id)
+	return err
+}
+
+const getBuildAlertRuleByID = `-- name: GetBuildAlertRuleByID :one
+SELECT
+	id, organization_id, template_id, name, metric, threshold, window_ms, created_by, created_at, updated_at, last_evaluated_at, last_value, breached_at
+FROM
+	build_alert_rules
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetBuildAlertRuleByID(ctx context.Context, id uuid.UUID) (BuildAlertRule, error) {
+	row := q.db.QueryRowContext(ctx, getBuildAlertRuleByID, id)
+	var i BuildAlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Metric,
+		&i.Threshold,
+		&i.WindowMs,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastEvaluatedAt,
+		&i.LastValue,
+		&i.BreachedAt,
+	)
+	return i, err
+}
+
+const getBuildAlertRuleMetrics = `-- name: GetBuildAlertRuleMetrics :one
+SELECT
+	COUNT(*) FILTER (
+		WHERE provisioner_jobs.job_status IN ('succeeded', 'failed')
+		AND provisioner_jobs.completed_at >= $1 :: timestamptz
+	) :: bigint AS completed_builds,
+	COUNT(*) FILTER (
+		WHERE provisioner_jobs.job_status = 'failed'
+		AND provisioner_jobs.completed_at >= $1 :: timestamptz
+	) :: bigint AS failed_builds,
+	COUNT(*) FILTER (
+		WHERE provisioner_jobs.created_at >= $1 :: timestamptz
+		AND (provisioner_jobs.started_at IS NOT NULL OR provisioner_jobs.completed_at IS NULL)
+	) :: bigint AS queued_builds,
+	-- Jobs that are still pending have been waiting until now.
+	COALESCE(
+		percentile_cont(0.95) WITHIN GROUP (
+			ORDER BY EXTRACT(EPOCH FROM COALESCE(provisioner_jobs.started_at, $2 :: timestamptz) - provisioner_jobs.created_at) * 1000
+		) FILTER (
+			WHERE provisioner_jobs.created_at >= $1 :: timestamptz
+			AND (provisioner_jobs.started_at IS NOT NULL OR provisioner_jobs.completed_at IS NULL)
+		),
+		0
+	) :: double precision AS queue_wait_p95_ms
+FROM
+	provisioner_jobs
+JOIN
+	workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+WHERE
+	provisioner_jobs.organization_id = $3
+	AND (
+		$4 :: uuid IS NULL
+		OR workspaces.template_id = $4 :: uuid
+	)
+	AND (
+		provisioner_jobs.created_at >= $1 :: timestamptz
+		OR provisioner_jobs.completed_at >= $1 :: timestamptz
+	)
+`
+
+type GetBuildAlertRuleMetricsParams struct {
+	Since          time.Time     `db:"since" json:"since"`
+	Now            time.Time     `db:"now" json:"now"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+}
+
+type GetBuildAlertRuleMetricsRow struct {
+	CompletedBuilds int64   `db:"completed_builds" json:"completed_builds"`
+	FailedBuilds    int64   `db:"failed_builds" json:"failed_builds"`
+	QueuedBuilds    int64   `db:"queued_builds" json:"queued_builds"`
+	QueueWaitP95Ms  float64 `db:"queue_wait_p95_ms" json:"queue_wait_p95_ms"`
+}
+
+// Returns the metrics build alert rules are evaluated against, over the
+// workspace builds of an organization since the given time. If template_id
+// is set, only builds of that template are considered.
+func (q *sqlQuerier) GetBuildAlertRuleMetrics(ctx context.Context, arg GetBuildAlertRuleMetricsParams) (GetBuildAlertRuleMetricsRow, error) {
+	row := q.db.QueryRowContext(ctx, getBuildAlertRuleMetrics,
+		arg.Since,
+		arg.Now,
+		arg.OrganizationID,
+		arg.TemplateID,
+	)
+	var i GetBuildAlertRuleMetricsRow
+	err := row.Scan(
+		&i.CompletedBuilds,
+		&i.FailedBuilds,
+		&i.QueuedBuilds,
+		&i.QueueWaitP95Ms,
+	)
+	return i, err
+}
+
+const getBuildAlertRules = `-- name: GetBuildAlertRules :many
+SELECT
+	id, organization_id, template_id, name, metric, threshold, window_ms, created_by, created_at, updated_at, last_evaluated_at, last_value, breached_at
+FROM
+	build_alert_rules
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetBuildAlertRules(ctx context.Context) ([]BuildAlertRule, error) {
+	rows, err := q.db.QueryContext(ctx, getBuildAlertRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BuildAlertRule
+	for rows.Next() {
+		var i BuildAlertRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.Name,
+			&i.Metric,
+			&i.Threshold,
+			&i.WindowMs,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastEvaluatedAt,
+			&i.LastValue,
+			&i.BreachedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBuildAlertRulesByOrganizationID = `-- name: GetBuildAlertRulesByOrganizationID :many
+SELECT
+	id, organization_id, template_id, name, metric, threshold, window_ms, created_by, created_at, updated_at, last_evaluated_at, last_value, breached_at
+FROM
+	build_alert_rules
+WHERE
+	organization_id = $1
+ORDER BY
+	lower(name) ASC
+`
+
+func (q *sqlQuerier) GetBuildAlertRulesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]BuildAlertRule, error) {
+	rows, err := q.db.QueryContext(ctx, getBuildAlertRulesByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BuildAlertRule
+	for rows.Next() {
+		var i BuildAlertRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.Name,
+			&i.Metric,
+			&i.Threshold,
+			&i.WindowMs,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastEvaluatedAt,
+			&i.LastValue,
+			&i.BreachedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertBuildAlertRule = `-- name: InsertBuildAlertRule :one
+INSERT INTO
+	build_alert_rules (
+		id,
+		organization_id,
+		template_id,
+		name,
+		metric,
+		threshold,
+		window_ms,
+		created_by,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, organization_id, template_id, name, metric, threshold, window_ms, created_by, created_at, updated_at, last_evaluated_at, last_value, breached_at
+`
+
+type InsertBuildAlertRuleParams struct {
+	ID             uuid.UUID            `db:"id" json:"id"`
+	OrganizationID uuid.UUID            `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.NullUUID        `db:"template_id" json:"template_id"`
+	Name           string               `db:"name" json:"name"`
+	Metric         BuildAlertRuleMetric `db:"metric" json:"metric"`
+	Threshold      float64              `db:"threshold" json:"threshold"`
+	WindowMs       int64                `db:"window_ms" json:"window_ms"`
+	CreatedBy      uuid.NullUUID        `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time            `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time            `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertBuildAlertRule(ctx context.Context, arg InsertBuildAlertRuleParams) (BuildAlertRule, error) {
+	row := q.db.QueryRowContext(ctx, insertBuildAlertRule,
+		arg.ID,
+		arg.OrganizationID,
+		arg.TemplateID,
+		arg.Name,
+		arg.Metric,
+		arg.Threshold,
+		arg.WindowMs,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i BuildAlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Metric,
+		&i.Threshold,
+		&i.WindowMs,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastEvaluatedAt,
+		&i.LastValue,
+		&i.BreachedAt,
+	)
+	return i, err
+}
+
+const updateBuildAlertRuleByID = `-- name: UpdateBuildAlertRuleByID :one
+UPDATE
+	build_alert_rules
+SET
+	template_id = $1,
+	name = $2,
+	metric = $3,
+	threshold = $4,
+	window_ms = $5,
+	updated_at = $6,
+	-- The rule changed, so it is evaluated from scratch.
+	last_evaluated_at = NULL,
+	last_value = NULL,
+	breached_at = NULL
+WHERE
+	id = $7
+RETURNING id, organization_id, template_id, name, metric, threshold, window_ms, created_by, created_at, updated_at, last_evaluated_at, last_value, breached_at
+`
+
+type UpdateBuildAlertRuleByIDParams struct {
+	TemplateID uuid.NullUUID        `db:"template_id" json:"template_id"`
+	Name       string               `db:"name" json:"name"`
+	Metric     BuildAlertRuleMetric `db:"metric" json:"metric"`
+	Threshold  float64              `db:"threshold" json:"threshold"`
+	WindowMs   int64                `db:"window_ms" json:"window_ms"`
+	UpdatedAt  time.Time            `db:"updated_at" json:"updated_at"`
+	ID         uuid.UUID            `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateBuildAlertRuleByID(ctx context.Context, arg UpdateBuildAlertRuleByIDParams) (BuildAlertRule, error) {
+	row := q.db.QueryRowContext(ctx, updateBuildAlertRuleByID,
+		arg.TemplateID,
+		arg.Name,
+		arg.Metric,
+		arg.Threshold,
+		arg.WindowMs,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i BuildAlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Metric,
+		&i.Threshold,
+		&i.WindowMs,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastEvaluatedAt,
+		&i.LastValue,
+		&i.BreachedAt,
+	)
+	return i, err
+}
+
+const updateBuildAlertRuleEvaluation = `-- name: UpdateBuildAlertRuleEvaluation :exec
+UPDATE
+	build_alert_rules
+SET
+	last_evaluated_at = $1,
+	last_value = $2,
+	breached_at = $3
+WHERE
+	id = $4
+`
+
+type UpdateBuildAlertRuleEvaluationParams struct {
+	LastEvaluatedAt sql.NullTime    `db:"last_evaluated_at" json:"last_evaluated_at"`
+	LastValue       sql.NullFloat64 `db:"last_value" json:"last_value"`
+	BreachedAt      sql.NullTime    `db:"breached_at" json:"breached_at"`
+	ID              uuid.UUID       `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateBuildAlertRuleEvaluation(ctx context.Context, arg UpdateBuildAlertRuleEvaluationParams) error {
+	_, err := q.db.ExecContext(ctx, updateBuildAlertRuleEvaluation,
+		arg.LastEvaluatedAt,
+		arg.LastValue,
+		arg.BreachedAt,
+		arg.ID,
+	)
+	return err
+}
+
 const deleteCryptoKey = `-- name: DeleteCryptoKey :one
 UPDATE crypto_keys
 SET secret = NULL, secret_key_id = NULL
@@ -1902,7 +2723,7 @@ func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
 
 const getGroupByID = `-- name: GetGroupByID :one
 SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source, monthly_budget
 FROM
 	groups
 WHERE
@@ -1922,13 +2743,14 @@ func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, err
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MonthlyBudget,
 	)
 	return i, err
 }
 
 const getGroupByOrgAndName = `-- name: GetGroupByOrgAndName :one
 SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source, monthly_budget
 FROM
 	groups
 WHERE
@@ -1955,13 +2777,14 @@ func (q *sqlQuerier) GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrg
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MonthlyBudget,
 	)
 	return i, err
 }
 
 const getGroups = `-- name: GetGroups :many
 SELECT
-		groups.id, groups.name, groups.organization_id, groups.avatar_url, groups.quota_allowance, groups.display_name, groups.source,
+		groups.id, groups.name, groups.organization_id, groups.avatar_url, groups.quota_allowance, groups.display_name, groups.source, groups.monthly_budget,
 		organizations.name AS organization_name,
 		organizations.display_name AS organization_display_name
 FROM
@@ -2036,6 +2859,7 @@ func (q *sqlQuerier) GetGroups(ctx context.Context, arg GetGroupsParams) ([]GetG
 			&i.Group.QuotaAllowance,
 			&i.Group.DisplayName,
 			&i.Group.Source,
+			&i.Group.MonthlyBudget,
 			&i.OrganizationName,
 			&i.OrganizationDisplayName,
 		); err != nil {
@@ -2059,7 +2883,7 @@ INSERT INTO groups (
 	organization_id
 )
 VALUES
-	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, monthly_budget
 `
 
 // We use the organization_id as the id
@@ -2076,6 +2900,7 @@ func (q *sqlQuerier) InsertAllUsersGroup(ctx context.Context, organizationID uui
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MonthlyBudget,
 	)
 	return i, err
 }
@@ -2087,10 +2912,11 @@ INSERT INTO groups (
 	display_name,
 	organization_id,
 	avatar_url,
-	quota_allowance
+	quota_allowance,
+	monthly_budget
 )
 VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, monthly_budget
 `
 
 type InsertGroupParams struct {
@@ -2100,6 +2926,7 @@ type InsertGroupParams struct {
 	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
 	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
 	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	MonthlyBudget  int32     `db:"monthly_budget" json:"monthly_budget"`
 }
 
 func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
@@ -2110,6 +2937,7 @@ func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Gr
 		arg.OrganizationID,
 		arg.AvatarURL,
 		arg.QuotaAllowance,
+		arg.MonthlyBudget,
 	)
 	var i Group
 	err := row.Scan(
@@ -2120,6 +2948,7 @@ func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Gr
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MonthlyBudget,
 	)
 	return i, err
 }
@@ -2139,7 +2968,7 @@ SELECT
 FROM
 						UNNEST($3 :: text[]) AS group_name
 ON CONFLICT DO NOTHING
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, monthly_budget
 `
 
 type InsertMissingGroupsParams struct {
@@ -2150,7 +2979,8 @@ type InsertMissingGroupsParams struct {
 
 // Inserts any group by name that does not exist. All new groups are given
 // a random uuid, are inserted into the same organization. They have the default
-// values for avatar, display name, and quota allowance (all zero values).
+// values for avatar, display name, quota allowance and monthly budget (all
+// zero values).
 // If the name conflicts, do nothing.
 func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingGroupsParams) ([]Group, error) {
 	rows, err := q.db.QueryContext(ctx, insertMissingGroups, arg.OrganizationID, arg.Source, pq.Array(arg.GroupNames))
@@ -2169,6 +2999,7 @@ func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingG
 			&i.QuotaAllowance,
 			&i.DisplayName,
 			&i.Source,
+			&i.MonthlyBudget,
 		); err != nil {
 			return nil, err
 		}
@@ -2190,10 +3021,11 @@ SET
 	name = $1,
 	display_name = $2,
 	avatar_url = $3,
-	quota_allowance = $4
+	quota_allowance = $4,
+	monthly_budget = $5
 WHERE
-	id = $5
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id = $6
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, monthly_budget
 `
 
 type UpdateGroupByIDParams struct {
@@ -2201,6 +3033,7 @@ type UpdateGroupByIDParams struct {
 	DisplayName    string    `db:"display_name" json:"display_name"`
 	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
 	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	MonthlyBudget  int32     `db:"monthly_budget" json:"monthly_budget"`
 	ID             uuid.UUID `db:"id" json:"id"`
 }
 
@@ -2210,6 +3043,7 @@ func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDPar
 		arg.DisplayName,
 		arg.AvatarURL,
 		arg.QuotaAllowance,
+		arg.MonthlyBudget,
 		arg.ID,
 	)
 	var i Group
@@ -2221,6 +3055,7 @@ func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDPar
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MonthlyBudget,
 	)
 	return i, err
 }
@@ -3081,13 +3916,150 @@ func (q *sqlQuerier) GetUserActivityInsights(ctx context.Context, arg GetUserAct
 	return items, nil
 }
 
-const getUserLatencyInsights = `-- name: GetUserLatencyInsights :many
+const getUserDailyActivity = `-- name: GetUserDailyActivity :many
+WITH
+	usage_stats AS (
+		SELECT
+			date_trunc('day', start_time, 'UTC') AS day,
+			user_id,
+			-- See motivation in GetTemplateInsights for LEAST(SUM(n), 30).
+			LEAST(SUM(usage_mins), 30) AS usage_mins
+		FROM
+			template_usage_stats
+		WHERE
+			start_time >= $1::timestamptz
+			AND start_time < $2::timestamptz
+		GROUP BY
+			start_time, user_id
+	),
+	daily_usage AS (
+		SELECT
+			day,
+			user_id,
+			SUM(usage_mins) AS usage_mins
+		FROM
+			usage_stats
+		GROUP BY
+			day, user_id
+	),
+	daily_apps AS (
+		SELECT
+			date_trunc('day', tus.start_time, 'UTC') AS day,
+			tus.user_id,
+			array_agg(DISTINCT app.key ORDER BY app.key) AS apps
+		FROM
+			template_usage_stats tus, jsonb_each(tus.app_usage_mins) app
+		WHERE
+			tus.start_time >= $1::timestamptz
+			AND tus.start_time < $2::timestamptz
+		GROUP BY
+			day, tus.user_id
+	),
+	daily_builds AS (
+		SELECT
+			date_trunc('day', created_at, 'UTC') AS day,
+			initiator_id AS user_id,
+			COUNT(*) AS builds
+		FROM
+			workspace_builds
+		WHERE
+			created_at >= $1::timestamptz
+			AND created_at < $2::timestamptz
+		GROUP BY
+			day, initiator_id
+	),
+	days AS (
+		SELECT day, user_id FROM daily_usage
+		UNION
+		SELECT day, user_id FROM daily_builds
+	)
+
 SELECT
-	tus.user_id,
+	d.day::timestamptz AS date,
+	d.user_id,
 	u.username,
-	u.avatar_url,
-	array_agg(DISTINCT tus.template_id)::uuid[] AS template_ids,
-	COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_50,
+	(COALESCE(du.usage_mins, 0) * 60)::bigint AS usage_seconds,
+	COALESCE(db.builds, 0)::bigint AS workspace_builds,
+	COALESCE(da.apps, '{}')::text[] AS apps
+FROM
+	days d
+JOIN
+	users u
+ON
+	u.id = d.user_id
+LEFT JOIN
+	daily_usage du
+ON
+	du.day = d.day AND du.user_id = d.user_id
+LEFT JOIN
+	daily_builds db
+ON
+	db.day = d.day AND db.user_id = d.user_id
+LEFT JOIN
+	daily_apps da
+ON
+	da.day = d.day AND da.user_id = d.user_id
+WHERE
+	NOT u.is_system
+ORDER BY
+	d.day ASC, u.username ASC
+`
+
+type GetUserDailyActivityParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetUserDailyActivityRow struct {
+	Date            time.Time `db:"date" json:"date"`
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	Username        string    `db:"username" json:"username"`
+	UsageSeconds    int64     `db:"usage_seconds" json:"usage_seconds"`
+	WorkspaceBuilds int64     `db:"workspace_builds" json:"workspace_builds"`
+	Apps            []string  `db:"apps" json:"apps"`
+}
+
+// GetUserDailyActivity returns the activity of each user in each UTC day that
+// starts within the given period: how long they were connected to
+// workspaces, how many workspace builds they started and which apps they
+// used. Days in which a user had no activity are omitted.
+func (q *sqlQuerier) GetUserDailyActivity(ctx context.Context, arg GetUserDailyActivityParams) ([]GetUserDailyActivityRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserDailyActivity, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserDailyActivityRow
+	for rows.Next() {
+		var i GetUserDailyActivityRow
+		if err := rows.Scan(
+			&i.Date,
+			&i.UserID,
+			&i.Username,
+			&i.UsageSeconds,
+			&i.WorkspaceBuilds,
+			pq.Array(&i.Apps),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserLatencyInsights = `-- name: GetUserLatencyInsights :many
+SELECT
+	tus.user_id,
+	u.username,
+	u.avatar_url,
+	array_agg(DISTINCT tus.template_id)::uuid[] AS template_ids,
+	COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_50,
 	COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_95
 FROM
 	template_usage_stats tus
@@ -3743,6 +4715,168 @@ func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock i
 	return pg_try_advisory_xact_lock, err
 }
 
+const approveLoginDeviceCode = `-- name: ApproveLoginDeviceCode :one
+UPDATE
+	login_device_codes
+SET
+	user_id = $1 :: uuid
+WHERE
+	id = $2
+	AND user_id IS NULL
+RETURNING id, device_code_hash, user_code, user_id, created_at, expires_at
+`
+
+type ApproveLoginDeviceCodeParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	ID     uuid.UUID `db:"id" json:"id"`
+}
+
+// Approves a device for a user. Devices can only be approved once.
+func (q *sqlQuerier) ApproveLoginDeviceCode(ctx context.Context, arg ApproveLoginDeviceCodeParams) (LoginDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, approveLoginDeviceCode, arg.UserID, arg.ID)
+	var i LoginDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const consumeApprovedLoginDeviceCode = `-- name: ConsumeApprovedLoginDeviceCode :one
+DELETE FROM
+	login_device_codes
+WHERE
+	device_code_hash = $1
+	AND user_id IS NOT NULL
+	AND expires_at > $2
+RETURNING id, device_code_hash, user_code, user_id, created_at, expires_at
+`
+
+type ConsumeApprovedLoginDeviceCodeParams struct {
+	DeviceCodeHash []byte    `db:"device_code_hash" json:"device_code_hash"`
+	Now            time.Time `db:"now" json:"now"`
+}
+
+// Deletes an approved device code that has not expired, so a session token is
+// issued for it only once.
+func (q *sqlQuerier) ConsumeApprovedLoginDeviceCode(ctx context.Context, arg ConsumeApprovedLoginDeviceCodeParams) (LoginDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, consumeApprovedLoginDeviceCode, arg.DeviceCodeHash, arg.Now)
+	var i LoginDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const deleteExpiredLoginDeviceCodes = `-- name: DeleteExpiredLoginDeviceCodes :exec
+DELETE FROM
+	login_device_codes
+WHERE
+	expires_at < $1
+`
+
+func (q *sqlQuerier) DeleteExpiredLoginDeviceCodes(ctx context.Context, before time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredLoginDeviceCodes, before)
+	return err
+}
+
+const getLoginDeviceCodeByDeviceCodeHash = `-- name: GetLoginDeviceCodeByDeviceCodeHash :one
+SELECT
+	id, device_code_hash, user_code, user_id, created_at, expires_at
+FROM
+	login_device_codes
+WHERE
+	device_code_hash = $1
+`
+
+func (q *sqlQuerier) GetLoginDeviceCodeByDeviceCodeHash(ctx context.Context, deviceCodeHash []byte) (LoginDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, getLoginDeviceCodeByDeviceCodeHash, deviceCodeHash)
+	var i LoginDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getLoginDeviceCodeByUserCode = `-- name: GetLoginDeviceCodeByUserCode :one
+SELECT
+	id, device_code_hash, user_code, user_id, created_at, expires_at
+FROM
+	login_device_codes
+WHERE
+	user_code = $1
+`
+
+func (q *sqlQuerier) GetLoginDeviceCodeByUserCode(ctx context.Context, userCode string) (LoginDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, getLoginDeviceCodeByUserCode, userCode)
+	var i LoginDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const insertLoginDeviceCode = `-- name: InsertLoginDeviceCode :one
+INSERT INTO
+	login_device_codes (
+		id,
+		device_code_hash,
+		user_code,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+RETURNING id, device_code_hash, user_code, user_id, created_at, expires_at
+`
+
+type InsertLoginDeviceCodeParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	DeviceCodeHash []byte    `db:"device_code_hash" json:"device_code_hash"`
+	UserCode       string    `db:"user_code" json:"user_code"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt      time.Time `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) InsertLoginDeviceCode(ctx context.Context, arg InsertLoginDeviceCodeParams) (LoginDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, insertLoginDeviceCode,
+		arg.ID,
+		arg.DeviceCodeHash,
+		arg.UserCode,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i LoginDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
 const acquireNotificationMessages = `-- name: AcquireNotificationMessages :many
 WITH acquired AS (
     UPDATE
@@ -4081,6 +5215,56 @@ func (q *sqlQuerier) FetchNewMessageMetadata(ctx context.Context, arg FetchNewMe
 	return i, err
 }
 
+const getBatchedNotificationMessagesByUserID = `-- name: GetBatchedNotificationMessagesByUserID :many
+SELECT nm.id,
+       nm.payload,
+       nm.created_at,
+       nt.name AS notification_name,
+       nt.title_template
+FROM notification_messages AS nm
+         JOIN notification_templates AS nt ON nm.notification_template_id = nt.id
+WHERE nm.user_id = $1::uuid
+  AND nm.status = 'batched'::notification_message_status
+ORDER BY nm.created_at ASC
+`
+
+type GetBatchedNotificationMessagesByUserIDRow struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	Payload          []byte    `db:"payload" json:"payload"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	NotificationName string    `db:"notification_name" json:"notification_name"`
+	TitleTemplate    string    `db:"title_template" json:"title_template"`
+}
+
+func (q *sqlQuerier) GetBatchedNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]GetBatchedNotificationMessagesByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBatchedNotificationMessagesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBatchedNotificationMessagesByUserIDRow
+	for rows.Next() {
+		var i GetBatchedNotificationMessagesByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.NotificationName,
+			&i.TitleTemplate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNotificationMessagesByStatus = `-- name: GetNotificationMessagesByStatus :many
 SELECT id, notification_template_id, user_id, method, status, status_reason, created_by, payload, attempt_count, targets, created_at, updated_at, leased_until, next_retry_after, queued_seconds, dedupe_hash
 FROM notification_messages
@@ -4213,8 +5397,42 @@ func (q *sqlQuerier) GetNotificationTemplatesByKind(ctx context.Context, kind No
 	return items, nil
 }
 
+const getUserNotificationBuildDurationThreshold = `-- name: GetUserNotificationBuildDurationThreshold :one
+SELECT
+	value AS notification_build_duration_threshold_ms
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'notification_build_duration_threshold_ms'
+`
+
+func (q *sqlQuerier) GetUserNotificationBuildDurationThreshold(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserNotificationBuildDurationThreshold, userID)
+	var notification_build_duration_threshold_ms string
+	err := row.Scan(&notification_build_duration_threshold_ms)
+	return notification_build_duration_threshold_ms, err
+}
+
+const getUserNotificationDigestFrequency = `-- name: GetUserNotificationDigestFrequency :one
+SELECT
+	value AS notification_digest_frequency
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'notification_digest_frequency'
+`
+
+func (q *sqlQuerier) GetUserNotificationDigestFrequency(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserNotificationDigestFrequency, userID)
+	var notification_digest_frequency string
+	err := row.Scan(&notification_digest_frequency)
+	return notification_digest_frequency, err
+}
+
 const getUserNotificationPreferences = `-- name: GetUserNotificationPreferences :many
-SELECT user_id, notification_template_id, disabled, created_at, updated_at
+SELECT user_id, notification_template_id, disabled, created_at, updated_at, digest
 FROM notification_preferences
 WHERE user_id = $1::uuid
 `
@@ -4234,6 +5452,7 @@ func (q *sqlQuerier) GetUserNotificationPreferences(ctx context.Context, userID
 			&i.Disabled,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Digest,
 		); err != nil {
 			return nil, err
 		}
@@ -4248,6 +5467,49 @@ func (q *sqlQuerier) GetUserNotificationPreferences(ctx context.Context, userID
 	return items, nil
 }
 
+const getUsersWithDueNotificationDigests = `-- name: GetUsersWithDueNotificationDigests :many
+SELECT nm.user_id,
+       COALESCE(uc.value, 'daily')::text AS frequency
+FROM notification_messages AS nm
+         LEFT JOIN user_configs AS uc
+                   ON (uc.user_id = nm.user_id AND uc.key = 'notification_digest_frequency')
+WHERE nm.status = 'batched'::notification_message_status
+GROUP BY nm.user_id, uc.value
+HAVING MIN(nm.created_at) + (CASE
+                                 WHEN uc.value = 'weekly' THEN INTERVAL '7 days'
+                                 ELSE INTERVAL '1 day' END) <= $1::timestamptz
+`
+
+type GetUsersWithDueNotificationDigestsRow struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Frequency string    `db:"frequency" json:"frequency"`
+}
+
+// Returns the users whose oldest batched notification message is older than
+// their digest frequency. Digests are sent daily unless configured otherwise.
+func (q *sqlQuerier) GetUsersWithDueNotificationDigests(ctx context.Context, now time.Time) ([]GetUsersWithDueNotificationDigestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersWithDueNotificationDigests, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersWithDueNotificationDigestsRow
+	for rows.Next() {
+		var i GetUsersWithDueNotificationDigestsRow
+		if err := rows.Scan(&i.UserID, &i.Frequency); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWebpushSubscriptionsByUserID = `-- name: GetWebpushSubscriptionsByUserID :many
 SELECT id, user_id, created_at, endpoint, endpoint_p256dh_key, endpoint_auth_key
 FROM webpush_subscriptions
@@ -4318,6 +5580,28 @@ func (q *sqlQuerier) InsertWebpushSubscription(ctx context.Context, arg InsertWe
 	return i, err
 }
 
+const markNotificationMessagesDigested = `-- name: MarkNotificationMessagesDigested :execrows
+UPDATE notification_messages
+SET updated_at    = $1::timestamptz,
+    status        = 'sent'::notification_message_status,
+    status_reason = 'Delivered in a notification digest'
+WHERE id = ANY($2::uuid[])
+  AND status = 'batched'::notification_message_status
+`
+
+type MarkNotificationMessagesDigestedParams struct {
+	DigestedAt time.Time   `db:"digested_at" json:"digested_at"`
+	IDs        []uuid.UUID `db:"ids" json:"ids"`
+}
+
+func (q *sqlQuerier) MarkNotificationMessagesDigested(ctx context.Context, arg MarkNotificationMessagesDigestedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markNotificationMessagesDigested, arg.DigestedAt, pq.Array(arg.IDs))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateNotificationTemplateMethodByID = `-- name: UpdateNotificationTemplateMethodByID :one
 UPDATE notification_templates
 SET method = $1::notification_method
@@ -4347,32 +5631,115 @@ func (q *sqlQuerier) UpdateNotificationTemplateMethodByID(ctx context.Context, a
 	return i, err
 }
 
-const updateUserNotificationPreferences = `-- name: UpdateUserNotificationPreferences :execrows
-INSERT
-INTO notification_preferences (user_id, notification_template_id, disabled)
-SELECT $1::uuid, new_values.notification_template_id, new_values.disabled
-FROM (SELECT UNNEST($2::uuid[]) AS notification_template_id,
-             UNNEST($3::bool[])                 AS disabled) AS new_values
-ON CONFLICT (user_id, notification_template_id) DO UPDATE
-    SET disabled   = EXCLUDED.disabled,
-        updated_at = CURRENT_TIMESTAMP
+const updateUserNotificationBuildDurationThreshold = `-- name: UpdateUserNotificationBuildDurationThreshold :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'notification_build_duration_threshold_ms', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'notification_build_duration_threshold_ms'
+RETURNING user_id, key, value
 `
 
-type UpdateUserNotificationPreferencesParams struct {
-	UserID                  uuid.UUID   `db:"user_id" json:"user_id"`
-	NotificationTemplateIds []uuid.UUID `db:"notification_template_ids" json:"notification_template_ids"`
-	Disableds               []bool      `db:"disableds" json:"disableds"`
+type UpdateUserNotificationBuildDurationThresholdParams struct {
+	UserID                               uuid.UUID `db:"user_id" json:"user_id"`
+	NotificationBuildDurationThresholdMs string    `db:"notification_build_duration_threshold_ms" json:"notification_build_duration_threshold_ms"`
 }
 
-func (q *sqlQuerier) UpdateUserNotificationPreferences(ctx context.Context, arg UpdateUserNotificationPreferencesParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, updateUserNotificationPreferences, arg.UserID, pq.Array(arg.NotificationTemplateIds), pq.Array(arg.Disableds))
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
+func (q *sqlQuerier) UpdateUserNotificationBuildDurationThreshold(ctx context.Context, arg UpdateUserNotificationBuildDurationThresholdParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserNotificationBuildDurationThreshold, arg.UserID, arg.NotificationBuildDurationThresholdMs)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
 }
 
-const upsertNotificationReportGeneratorLog = `-- name: UpsertNotificationReportGeneratorLog :exec
+const updateUserNotificationDigestFrequency = `-- name: UpdateUserNotificationDigestFrequency :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'notification_digest_frequency', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'notification_digest_frequency'
+RETURNING user_id, key, value
+`
+
+type UpdateUserNotificationDigestFrequencyParams struct {
+	UserID                      uuid.UUID `db:"user_id" json:"user_id"`
+	NotificationDigestFrequency string    `db:"notification_digest_frequency" json:"notification_digest_frequency"`
+}
+
+func (q *sqlQuerier) UpdateUserNotificationDigestFrequency(ctx context.Context, arg UpdateUserNotificationDigestFrequencyParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserNotificationDigestFrequency, arg.UserID, arg.NotificationDigestFrequency)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserNotificationDigestPreferences = `-- name: UpdateUserNotificationDigestPreferences :execrows
+INSERT
+INTO notification_preferences (user_id, notification_template_id, disabled, digest)
+SELECT $1::uuid, new_values.notification_template_id, NOT nt.enabled_by_default, new_values.digest
+FROM (SELECT UNNEST($2::uuid[]) AS notification_template_id,
+             UNNEST($3::bool[])                   AS digest) AS new_values,
+     notification_templates AS nt
+WHERE nt.id = new_values.notification_template_id
+ON CONFLICT (user_id, notification_template_id) DO UPDATE
+    SET digest     = EXCLUDED.digest,
+        updated_at = CURRENT_TIMESTAMP
+`
+
+type UpdateUserNotificationDigestPreferencesParams struct {
+	UserID                  uuid.UUID   `db:"user_id" json:"user_id"`
+	NotificationTemplateIds []uuid.UUID `db:"notification_template_ids" json:"notification_template_ids"`
+	Digests                 []bool      `db:"digests" json:"digests"`
+}
+
+// Preferences which don't exist yet are created with the default enablement
+// of their template, so opting into a digest doesn't enable a notification.
+func (q *sqlQuerier) UpdateUserNotificationDigestPreferences(ctx context.Context, arg UpdateUserNotificationDigestPreferencesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserNotificationDigestPreferences, arg.UserID, pq.Array(arg.NotificationTemplateIds), pq.Array(arg.Digests))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserNotificationPreferences = `-- name: UpdateUserNotificationPreferences :execrows
+INSERT
+INTO notification_preferences (user_id, notification_template_id, disabled)
+SELECT $1::uuid, new_values.notification_template_id, new_values.disabled
+FROM (SELECT UNNEST($2::uuid[]) AS notification_template_id,
+             UNNEST($3::bool[])                 AS disabled) AS new_values
+ON CONFLICT (user_id, notification_template_id) DO UPDATE
+    SET disabled   = EXCLUDED.disabled,
+        updated_at = CURRENT_TIMESTAMP
+`
+
+type UpdateUserNotificationPreferencesParams struct {
+	UserID                  uuid.UUID   `db:"user_id" json:"user_id"`
+	NotificationTemplateIds []uuid.UUID `db:"notification_template_ids" json:"notification_template_ids"`
+	Disableds               []bool      `db:"disableds" json:"disableds"`
+}
+
+func (q *sqlQuerier) UpdateUserNotificationPreferences(ctx context.Context, arg UpdateUserNotificationPreferencesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserNotificationPreferences, arg.UserID, pq.Array(arg.NotificationTemplateIds), pq.Array(arg.Disableds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertNotificationReportGeneratorLog = `-- name: UpsertNotificationReportGeneratorLog :exec
 INSERT INTO notification_report_generator_logs (notification_template_id, last_generated_at) VALUES ($1, $2)
 ON CONFLICT (notification_template_id) DO UPDATE set last_generated_at = EXCLUDED.last_generated_at
 WHERE notification_report_generator_logs.notification_template_id = EXCLUDED.notification_template_id
@@ -5497,7 +6864,7 @@ func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRole
 
 const getDefaultOrganization = `-- name: GetDefaultOrganization :one
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, cost_center
 FROM
     organizations
 WHERE
@@ -5519,13 +6886,14 @@ func (q *sqlQuerier) GetDefaultOrganization(ctx context.Context) (Organization,
 		&i.DisplayName,
 		&i.Icon,
 		&i.Deleted,
+		&i.CostCenter,
 	)
 	return i, err
 }
 
 const getOrganizationByID = `-- name: GetOrganizationByID :one
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, cost_center
 FROM
     organizations
 WHERE
@@ -5545,13 +6913,14 @@ func (q *sqlQuerier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (Org
 		&i.DisplayName,
 		&i.Icon,
 		&i.Deleted,
+		&i.CostCenter,
 	)
 	return i, err
 }
 
 const getOrganizationByName = `-- name: GetOrganizationByName :one
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, cost_center
 FROM
     organizations
 WHERE
@@ -5580,6 +6949,7 @@ func (q *sqlQuerier) GetOrganizationByName(ctx context.Context, arg GetOrganizat
 		&i.DisplayName,
 		&i.Icon,
 		&i.Deleted,
+		&i.CostCenter,
 	)
 	return i, err
 }
@@ -5650,7 +7020,7 @@ func (q *sqlQuerier) GetOrganizationResourceCountByID(ctx context.Context, organ
 
 const getOrganizations = `-- name: GetOrganizations :many
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, cost_center
 FROM
     organizations
 WHERE
@@ -5694,6 +7064,7 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context, arg GetOrganizationsP
 			&i.DisplayName,
 			&i.Icon,
 			&i.Deleted,
+			&i.CostCenter,
 		); err != nil {
 			return nil, err
 		}
@@ -5710,7 +7081,7 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context, arg GetOrganizationsP
 
 const getOrganizationsByUserID = `-- name: GetOrganizationsByUserID :many
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, cost_center
 FROM
     organizations
 WHERE
@@ -5755,6 +7126,7 @@ func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, arg GetOrgani
 			&i.DisplayName,
 			&i.Icon,
 			&i.Deleted,
+			&i.CostCenter,
 		); err != nil {
 			return nil, err
 		}
@@ -5771,10 +7143,10 @@ func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, arg GetOrgani
 
 const insertOrganization = `-- name: InsertOrganization :one
 INSERT INTO
-    organizations (id, "name", display_name, description, icon, created_at, updated_at, is_default)
+    organizations (id, "name", display_name, description, icon, cost_center, created_at, updated_at, is_default)
 VALUES
     -- If no organizations exist, and this is the first, make it the default.
-    ($1, $2, $3, $4, $5, $6, $7, (SELECT TRUE FROM organizations LIMIT 1) IS NULL) RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted
+    ($1, $2, $3, $4, $5, $6, $7, $8, (SELECT TRUE FROM organizations LIMIT 1) IS NULL) RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, cost_center
 `
 
 type InsertOrganizationParams struct {
@@ -5783,6 +7155,7 @@ type InsertOrganizationParams struct {
 	DisplayName string    `db:"display_name" json:"display_name"`
 	Description string    `db:"description" json:"description"`
 	Icon        string    `db:"icon" json:"icon"`
+	CostCenter  string    `db:"cost_center" json:"cost_center"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -5794,6 +7167,7 @@ func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizat
 		arg.DisplayName,
 		arg.Description,
 		arg.Icon,
+		arg.CostCenter,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -5808,6 +7182,7 @@ func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizat
 		&i.DisplayName,
 		&i.Icon,
 		&i.Deleted,
+		&i.CostCenter,
 	)
 	return i, err
 }
@@ -5820,10 +7195,11 @@ SET
     name = $2,
     display_name = $3,
     description = $4,
-    icon = $5
+    icon = $5,
+    cost_center = $6
 WHERE
-    id = $6
-RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted
+    id = $7
+RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, cost_center
 `
 
 type UpdateOrganizationParams struct {
@@ -5832,6 +7208,7 @@ type UpdateOrganizationParams struct {
 	DisplayName string    `db:"display_name" json:"display_name"`
 	Description string    `db:"description" json:"description"`
 	Icon        string    `db:"icon" json:"icon"`
+	CostCenter  string    `db:"cost_center" json:"cost_center"`
 	ID          uuid.UUID `db:"id" json:"id"`
 }
 
@@ -5842,6 +7219,7 @@ func (q *sqlQuerier) UpdateOrganization(ctx context.Context, arg UpdateOrganizat
 		arg.DisplayName,
 		arg.Description,
 		arg.Icon,
+		arg.CostCenter,
 		arg.ID,
 	)
 	var i Organization
@@ -5855,6 +7233,7 @@ func (q *sqlQuerier) UpdateOrganization(ctx context.Context, arg UpdateOrganizat
 		&i.DisplayName,
 		&i.Icon,
 		&i.Deleted,
+		&i.CostCenter,
 	)
 	return i, err
 }
@@ -5931,6 +7310,27 @@ func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.
 	return items, nil
 }
 
+const createTimePartition = `-- name: CreateTimePartition :one
+SELECT
+	create_time_partition($1::text, $2::timestamptz, $3::timestamptz)::boolean AS created
+`
+
+type CreateTimePartitionParams struct {
+	ParentTable    string    `db:"parent_table" json:"parent_table"`
+	PartitionStart time.Time `db:"partition_start" json:"partition_start"`
+	PartitionEnd   time.Time `db:"partition_end" json:"partition_end"`
+}
+
+// Creates the range partition of a time partitioned table covering
+// [partition_start, partition_end). Returns false if the partition already
+// exists, or if rows in that range are still stored in the default partition.
+func (q *sqlQuerier) CreateTimePartition(ctx context.Context, arg CreateTimePartitionParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, createTimePartition, arg.ParentTable, arg.PartitionStart, arg.PartitionEnd)
+	var created bool
+	err := row.Scan(&created)
+	return created, err
+}
+
 const claimPrebuiltWorkspace = `-- name: ClaimPrebuiltWorkspace :one
 UPDATE workspaces w
 SET owner_id   = $1::uuid,
@@ -5941,6 +7341,7 @@ WHERE w.id IN (
 	FROM workspace_prebuilds p
 		INNER JOIN workspace_latest_builds b ON b.workspace_id = p.id
 		INNER JOIN templates t ON p.template_id = t.id
+		LEFT JOIN workspace_prebuild_reservations r ON r.workspace_id = p.id AND r.expires_at > NOW()
 	WHERE (b.transition = 'start'::workspace_transition
 		AND b.job_status IN ('succeeded'::provisioner_job_status))
 		-- The prebuilds system should never try to claim a prebuild for an inactive template version.
@@ -5949,6 +7350,12 @@ WHERE w.id IN (
 		AND p.current_preset_id = $3::uuid
 		AND p.ready
 		AND NOT t.deleted
+		-- Prebuilds reserved for another user or group can't be claimed until the reservation expires.
+		AND (r.workspace_id IS NULL
+			OR r.user_id = $1::uuid
+			OR r.group_id IN (SELECT gme.group_id FROM group_members_expanded gme WHERE gme.user_id = $1::uuid))
+	-- Claim prebuilds reserved for the user before unreserved ones.
+	ORDER BY r.workspace_id IS NULL
 	LIMIT 1 FOR UPDATE OF p SKIP LOCKED -- Ensure that a concurrent request will not select the same prebuild.
 )
 RETURNING w.id, w.name
@@ -6027,6 +7434,74 @@ func (q *sqlQuerier) CountInProgressPrebuilds(ctx context.Context) ([]CountInPro
 	return items, nil
 }
 
+const deleteWorkspacePrebuildReservationByWorkspaceID = `-- name: DeleteWorkspacePrebuildReservationByWorkspaceID :exec
+DELETE FROM workspace_prebuild_reservations WHERE workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePrebuildReservationByWorkspaceID, workspaceID)
+	return err
+}
+
+const getPrebuildClaimDemand = `-- name: GetPrebuildClaimDemand :many
+SELECT
+	w.template_id,
+	tvp.name AS preset_name,
+	date_trunc('hour', wb.created_at)::timestamptz AS hour,
+	COUNT(*) FILTER (WHERE wb.build_number > 1) AS claimed_count,
+	COUNT(*) FILTER (WHERE wb.build_number = 1) AS missed_count
+FROM workspace_builds wb
+INNER JOIN workspaces w ON w.id = wb.workspace_id
+INNER JOIN template_version_presets tvp ON tvp.id = wb.template_version_preset_id
+LEFT JOIN workspace_builds prev ON prev.workspace_id = wb.workspace_id AND prev.build_number = wb.build_number - 1
+WHERE wb.created_at >= $1::timestamptz
+	AND wb.initiator_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+	-- Either the first build of a workspace created by a user, or the build that claimed a prebuilt workspace.
+	AND (wb.build_number = 1 OR prev.initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid)
+GROUP BY w.template_id, tvp.name, hour
+ORDER BY w.template_id, tvp.name, hour
+`
+
+type GetPrebuildClaimDemandRow struct {
+	TemplateID   uuid.UUID `db:"template_id" json:"template_id"`
+	PresetName   string    `db:"preset_name" json:"preset_name"`
+	Hour         time.Time `db:"hour" json:"hour"`
+	ClaimedCount int64     `db:"claimed_count" json:"claimed_count"`
+	MissedCount  int64     `db:"missed_count" json:"missed_count"`
+}
+
+// GetPrebuildClaimDemand returns the number of workspaces created from each preset in every hour since
+// the given time. Claimed workspaces were assigned a prebuilt workspace, missed ones were provisioned from
+// scratch. Presets are identified by template and name, since every template version has its own presets.
+func (q *sqlQuerier) GetPrebuildClaimDemand(ctx context.Context, since time.Time) ([]GetPrebuildClaimDemandRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPrebuildClaimDemand, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPrebuildClaimDemandRow
+	for rows.Next() {
+		var i GetPrebuildClaimDemandRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.PresetName,
+			&i.Hour,
+			&i.ClaimedCount,
+			&i.MissedCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPrebuildMetrics = `-- name: GetPrebuildMetrics :many
 SELECT
 	t.name as template_name,
@@ -6384,6 +7859,121 @@ func (q *sqlQuerier) GetTemplatePresetsWithPrebuilds(ctx context.Context, templa
 	return items, nil
 }
 
+const getWorkspacePrebuildReservationByWorkspaceID = `-- name: GetWorkspacePrebuildReservationByWorkspaceID :one
+SELECT workspace_id, user_id, group_id, reserved_by, created_at, expires_at FROM workspace_prebuild_reservations WHERE workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspacePrebuildReservationByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspacePrebuildReservation, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspacePrebuildReservationByWorkspaceID, workspaceID)
+	var i WorkspacePrebuildReservation
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.UserID,
+		&i.GroupID,
+		&i.ReservedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getWorkspacePrebuildReservationsByTemplateID = `-- name: GetWorkspacePrebuildReservationsByTemplateID :many
+SELECT r.workspace_id, r.user_id, r.group_id, r.reserved_by, r.created_at, r.expires_at
+FROM workspace_prebuild_reservations r
+INNER JOIN workspaces w ON w.id = r.workspace_id
+WHERE w.template_id = $1
+	AND w.owner_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+	AND NOT w.deleted
+ORDER BY r.expires_at, r.workspace_id
+`
+
+// GetWorkspacePrebuildReservationsByTemplateID returns the reservations of the prebuilt workspaces of a template
+// which have been neither claimed nor deleted, including expired ones.
+func (q *sqlQuerier) GetWorkspacePrebuildReservationsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspacePrebuildReservation, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePrebuildReservationsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspacePrebuildReservation
+	for rows.Next() {
+		var i WorkspacePrebuildReservation
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.UserID,
+			&i.GroupID,
+			&i.ReservedBy,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspacePrebuildReservation = `-- name: UpsertWorkspacePrebuildReservation :one
+INSERT INTO workspace_prebuild_reservations (
+	workspace_id,
+	user_id,
+	group_id,
+	reserved_by,
+	created_at,
+	expires_at
+) VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	user_id = EXCLUDED.user_id,
+	group_id = EXCLUDED.group_id,
+	reserved_by = EXCLUDED.reserved_by,
+	created_at = EXCLUDED.created_at,
+	expires_at = EXCLUDED.expires_at
+RETURNING workspace_id, user_id, group_id, reserved_by, created_at, expires_at
+`
+
+type UpsertWorkspacePrebuildReservationParams struct {
+	WorkspaceID uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	UserID      uuid.NullUUID `db:"user_id" json:"user_id"`
+	GroupID     uuid.NullUUID `db:"group_id" json:"group_id"`
+	ReservedBy  uuid.UUID     `db:"reserved_by" json:"reserved_by"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time     `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspacePrebuildReservation(ctx context.Context, arg UpsertWorkspacePrebuildReservationParams) (WorkspacePrebuildReservation, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspacePrebuildReservation,
+		arg.WorkspaceID,
+		arg.UserID,
+		arg.GroupID,
+		arg.ReservedBy,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i WorkspacePrebuildReservation
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.UserID,
+		&i.GroupID,
+		&i.ReservedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
 const getActivePresetPrebuildSchedules = `-- name: GetActivePresetPrebuildSchedules :many
 SELECT
 	tvpps.id, tvpps.preset_id, tvpps.cron_expression, tvpps.desired_instances
@@ -6762,6 +8352,83 @@ func (q *sqlQuerier) UpdatePresetPrebuildStatus(ctx context.Context, arg UpdateP
 	return err
 }
 
+const deleteProvisionerBuildPause = `-- name: DeleteProvisionerBuildPause :exec
+DELETE FROM
+	provisioner_build_pauses
+WHERE
+	organization_id IS NOT DISTINCT FROM $1 :: uuid
+`
+
+func (q *sqlQuerier) DeleteProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProvisionerBuildPause, organizationID)
+	return err
+}
+
+const getProvisionerBuildPause = `-- name: GetProvisionerBuildPause :one
+SELECT
+	id, organization_id, reason, created_by, created_at
+FROM
+	provisioner_build_pauses
+WHERE
+	organization_id IS NOT DISTINCT FROM $1 :: uuid
+`
+
+// Returns the pause of an organization, or the deployment-wide pause if
+// organization_id is NULL.
+func (q *sqlQuerier) GetProvisionerBuildPause(ctx context.Context, organizationID uuid.NullUUID) (ProvisionerBuildPause, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerBuildPause, organizationID)
+	var i ProvisionerBuildPause
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Reason,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertProvisionerBuildPause = `-- name: InsertProvisionerBuildPause :one
+INSERT INTO
+	provisioner_build_pauses (
+		id,
+		organization_id,
+		reason,
+		created_by,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+RETURNING id, organization_id, reason, created_by, created_at
+`
+
+type InsertProvisionerBuildPauseParams struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	Reason         string        `db:"reason" json:"reason"`
+	CreatedBy      uuid.NullUUID `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertProvisionerBuildPause(ctx context.Context, arg InsertProvisionerBuildPauseParams) (ProvisionerBuildPause, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerBuildPause,
+		arg.ID,
+		arg.OrganizationID,
+		arg.Reason,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i ProvisionerBuildPause
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Reason,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const deleteOldProvisionerDaemons = `-- name: DeleteOldProvisionerDaemons :exec
 DELETE FROM provisioner_daemons WHERE (
 	(created_at < (NOW() - INTERVAL '7 days') AND last_seen_at IS NULL) OR
@@ -7189,25 +8856,164 @@ func (q *sqlQuerier) UpsertProvisionerDaemon(ctx context.Context, arg UpsertProv
 	return i, err
 }
 
-const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
+const deleteOldProvisionerJobLogs = `-- name: DeleteOldProvisionerJobLogs :one
+WITH
+	old_jobs AS (
+		SELECT
+			provisioner_jobs.id
+		FROM
+			provisioner_jobs
+		WHERE
+			provisioner_jobs.completed_at < $1 :: timestamptz
+			AND (
+				EXISTS (
+					SELECT 1 FROM provisioner_job_logs WHERE provisioner_job_logs.job_id = provisioner_jobs.id
+				)
+				OR EXISTS (
+					SELECT 1 FROM provisioner_job_log_archives WHERE provisioner_job_log_archives.job_id = provisioner_jobs.id
+				)
+			)
+		ORDER BY
+			provisioner_jobs.completed_at ASC
+		LIMIT
+			$2 :: int
+	),
+	deleted_logs AS (
+		DELETE FROM provisioner_job_logs WHERE job_id IN (SELECT id FROM old_jobs) RETURNING id
+	),
+	deleted_archives AS (
+		DELETE FROM provisioner_job_log_archives WHERE job_id IN (SELECT id FROM old_jobs) RETURNING file_id, log_count
+	),
+	-- Archive files are only referenced by their archive.
+	deleted_files AS (
+		DELETE FROM files WHERE id IN (SELECT file_id FROM deleted_archives)
+	)
+SELECT
+	(
+		(SELECT COUNT(*) FROM deleted_logs)
+		+ (SELECT COALESCE(SUM(log_count), 0) FROM deleted_archives)
+	)::bigint AS deleted
+`
+
+type DeleteOldProvisionerJobLogsParams struct {
+	CompletedBefore time.Time `db:"completed_before" json:"completed_before"`
+	LimitCount      int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Deletes the logs of at most @limit_count jobs that completed before
+// @completed_before, oldest first, including archived logs and their files.
+// Returns the number of deleted logs.
+func (q *sqlQuerier) DeleteOldProvisionerJobLogs(ctx context.Context, arg DeleteOldProvisionerJobLogsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, deleteOldProvisionerJobLogs, arg.CompletedBefore, arg.LimitCount)
+	var deleted int64
+	err := row.Scan(&deleted)
+	return deleted, err
+}
+
+const deleteProvisionerJobLogsByJobID = `-- name: DeleteProvisionerJobLogsByJobID :exec
+DELETE FROM provisioner_job_logs WHERE job_id = $1
+`
+
+func (q *sqlQuerier) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProvisionerJobLogsByJobID, jobID)
+	return err
+}
+
+const getProvisionerJobLogArchiveByJobID = `-- name: GetProvisionerJobLogArchiveByJobID :one
 SELECT
-	job_id, created_at, source, level, stage, output, id
+	job_id, file_id, log_count, archived_at
 FROM
-	provisioner_job_logs
+	provisioner_job_log_archives
 WHERE
 	job_id = $1
-	AND (
-		id > $2
-	) ORDER BY id ASC
 `
 
-type GetProvisionerLogsAfterIDParams struct {
-	JobID        uuid.UUID `db:"job_id" json:"job_id"`
-	CreatedAfter int64     `db:"created_after" json:"created_after"`
+func (q *sqlQuerier) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (ProvisionerJobLogArchive, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerJobLogArchiveByJobID, jobID)
+	var i ProvisionerJobLogArchive
+	err := row.Scan(
+		&i.JobID,
+		&i.FileID,
+		&i.LogCount,
+		&i.ArchivedAt,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerLogsAfterID, arg.JobID, arg.CreatedAfter)
+const getProvisionerJobsToArchiveLogs = `-- name: GetProvisionerJobsToArchiveLogs :many
+SELECT
+	provisioner_jobs.id,
+	provisioner_jobs.initiator_id
+FROM
+	provisioner_jobs
+WHERE
+	provisioner_jobs.completed_at < $1 :: timestamptz
+	AND NOT EXISTS (
+		SELECT 1 FROM provisioner_job_log_archives WHERE provisioner_job_log_archives.job_id = provisioner_jobs.id
+	)
+	AND EXISTS (
+		SELECT 1 FROM provisioner_job_logs WHERE provisioner_job_logs.job_id = provisioner_jobs.id
+	)
+ORDER BY
+	provisioner_jobs.completed_at ASC
+LIMIT
+	$2 :: int
+`
+
+type GetProvisionerJobsToArchiveLogsParams struct {
+	CompletedBefore time.Time `db:"completed_before" json:"completed_before"`
+	LimitCount      int32     `db:"limit_count" json:"limit_count"`
+}
+
+type GetProvisionerJobsToArchiveLogsRow struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	InitiatorID uuid.UUID `db:"initiator_id" json:"initiator_id"`
+}
+
+// Returns jobs that completed before the given time and still have logs in
+// provisioner_job_logs, oldest first.
+func (q *sqlQuerier) GetProvisionerJobsToArchiveLogs(ctx context.Context, arg GetProvisionerJobsToArchiveLogsParams) ([]GetProvisionerJobsToArchiveLogsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsToArchiveLogs, arg.CompletedBefore, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProvisionerJobsToArchiveLogsRow
+	for rows.Next() {
+		var i GetProvisionerJobsToArchiveLogsRow
+		if err := rows.Scan(&i.ID, &i.InitiatorID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
+SELECT
+	job_id, created_at, source, level, stage, output, id, fields
+FROM
+	provisioner_job_logs
+WHERE
+	job_id = $1
+	AND (
+		id > $2
+	) ORDER BY id ASC
+`
+
+type GetProvisionerLogsAfterIDParams struct {
+	JobID        uuid.UUID `db:"job_id" json:"job_id"`
+	CreatedAfter int64     `db:"created_after" json:"created_after"`
+}
+
+func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerLogsAfterID, arg.JobID, arg.CreatedAfter)
 	if err != nil {
 		return nil, err
 	}
@@ -7223,6 +9029,7 @@ func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvi
 			&i.Stage,
 			&i.Output,
 			&i.ID,
+			&i.Fields,
 		); err != nil {
 			return nil, err
 		}
@@ -7237,16 +9044,50 @@ func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvi
 	return items, nil
 }
 
+const insertProvisionerJobLogArchive = `-- name: InsertProvisionerJobLogArchive :one
+INSERT INTO
+	provisioner_job_log_archives (job_id, file_id, log_count, archived_at)
+VALUES
+	($1, $2, $3, $4) RETURNING job_id, file_id, log_count, archived_at
+`
+
+type InsertProvisionerJobLogArchiveParams struct {
+	JobID      uuid.UUID `db:"job_id" json:"job_id"`
+	FileID     uuid.UUID `db:"file_id" json:"file_id"`
+	LogCount   int32     `db:"log_count" json:"log_count"`
+	ArchivedAt time.Time `db:"archived_at" json:"archived_at"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobLogArchive(ctx context.Context, arg InsertProvisionerJobLogArchiveParams) (ProvisionerJobLogArchive, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerJobLogArchive,
+		arg.JobID,
+		arg.FileID,
+		arg.LogCount,
+		arg.ArchivedAt,
+	)
+	var i ProvisionerJobLogArchive
+	err := row.Scan(
+		&i.JobID,
+		&i.FileID,
+		&i.LogCount,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const insertProvisionerJobLogs = `-- name: InsertProvisionerJobLogs :many
 INSERT INTO
-	provisioner_job_logs
+	provisioner_job_logs (job_id, created_at, source, level, stage, output, fields)
 SELECT
 	$1 :: uuid AS job_id,
 	unnest($2 :: timestamptz [ ]) AS created_at,
 	unnest($3 :: log_source [ ]) AS source,
 	unnest($4 :: log_level [ ]) AS LEVEL,
 	unnest($5 :: VARCHAR(128) [ ]) AS stage,
-	unnest($6 :: VARCHAR(1024) [ ]) AS output RETURNING job_id, created_at, source, level, stage, output, id
+	unnest($6 :: VARCHAR(1024) [ ]) AS output,
+	-- jsonb arrays cannot be passed as parameters, so the fields are sent
+	-- as text and cast.
+	unnest($7 :: text [ ]) :: jsonb AS fields RETURNING job_id, created_at, source, level, stage, output, id, fields
 `
 
 type InsertProvisionerJobLogsParams struct {
@@ -7256,6 +9097,7 @@ type InsertProvisionerJobLogsParams struct {
 	Level     []LogLevel  `db:"level" json:"level"`
 	Stage     []string    `db:"stage" json:"stage"`
 	Output    []string    `db:"output" json:"output"`
+	Fields    []string    `db:"fields" json:"fields"`
 }
 
 func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error) {
@@ -7266,6 +9108,80 @@ func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertPro
 		pq.Array(arg.Level),
 		pq.Array(arg.Stage),
 		pq.Array(arg.Output),
+		pq.Array(arg.Fields),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobLog
+	for rows.Next() {
+		var i ProvisionerJobLog
+		if err := rows.Scan(
+			&i.JobID,
+			&i.CreatedAt,
+			&i.Source,
+			&i.Level,
+			&i.Stage,
+			&i.Output,
+			&i.ID,
+			&i.Fields,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchProvisionerJobLogs = `-- name: SearchProvisionerJobLogs :many
+SELECT
+	job_id, created_at, source, level, stage, output, id, fields
+FROM
+	provisioner_job_logs
+WHERE
+	job_id = $1
+	AND id > $2
+	AND (
+		$3 :: log_level IS NULL
+		OR level >= $3 :: log_level
+	)
+	AND (
+		$4 :: text = ''
+		OR stage = $4 :: text
+	)
+	-- Matches provisioner_job_logs_output_search_idx.
+	AND (
+		$5 :: text = ''
+		OR to_tsvector('simple', output) @@ plainto_tsquery('simple', $5 :: text)
+	)
+ORDER BY
+	id ASC
+`
+
+type SearchProvisionerJobLogsParams struct {
+	JobID        uuid.UUID    `db:"job_id" json:"job_id"`
+	CreatedAfter int64        `db:"created_after" json:"created_after"`
+	MinLevel     NullLogLevel `db:"min_level" json:"min_level"`
+	Stage        string       `db:"stage" json:"stage"`
+	Search       string       `db:"search" json:"search"`
+}
+
+// Returns the logs of a job with an ID greater than created_after that match
+// all of the given filters. Unset filters match every log.
+func (q *sqlQuerier) SearchProvisionerJobLogs(ctx context.Context, arg SearchProvisionerJobLogsParams) ([]ProvisionerJobLog, error) {
+	rows, err := q.db.QueryContext(ctx, searchProvisionerJobLogs,
+		arg.JobID,
+		arg.CreatedAfter,
+		arg.MinLevel,
+		arg.Stage,
+		arg.Search,
 	)
 	if err != nil {
 		return nil, err
@@ -7282,6 +9198,7 @@ func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertPro
 			&i.Stage,
 			&i.Output,
 			&i.ID,
+			&i.Fields,
 		); err != nil {
 			return nil, err
 		}
@@ -7317,7 +9234,73 @@ WHERE
 			-- elsewhere, we use the tagset type, but here we use jsonb for backward compatibility
 			-- they are aliases and the code that calls this query already relies on a different type
 			AND provisioner_tagset_contains($5 :: jsonb, potential_job.tags :: jsonb)
+			-- Jobs are held while builds are paused for the deployment or the
+			-- job's organization.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					provisioner_build_pauses AS pause
+				WHERE
+					pause.organization_id IS NULL
+					OR pause.organization_id = potential_job.organization_id
+			)
+			-- Honor capacity reservations. While a reservation is active, provisioners
+			-- able to serve it only pick up jobs outside the reservation as long as
+			-- the reserved number of slots stays available for reserved jobs.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					provisioner_reservations AS reservation
+				WHERE
+					reservation.organization_id = potential_job.organization_id
+					AND reservation.starts_at <= $1
+					AND reservation.ends_at > $1
+					-- The job is not covered by the reservation...
+					AND NOT (potential_job.tags :: jsonb @> reservation.tags)
+					-- ...but the caller provides reserved capacity...
+					AND $5 :: jsonb @> reservation.tags
+					-- ...and all unreserved capacity is in use.
+					AND (
+						SELECT
+							COUNT(*)
+						FROM
+							provisioner_jobs AS running_job
+							INNER JOIN provisioner_daemons AS daemon ON daemon.id = running_job.worker_id
+						WHERE
+							running_job.job_status = 'running'
+							AND running_job.organization_id = reservation.organization_id
+							AND daemon.tags :: jsonb @> reservation.tags
+							AND NOT (running_job.tags :: jsonb @> reservation.tags)
+					) + reservation.slots >= (
+						SELECT
+							COUNT(*)
+						FROM
+							provisioner_daemons AS daemon
+						WHERE
+							daemon.organization_id = reservation.organization_id
+							AND daemon.tags :: jsonb @> reservation.tags
+							-- Matches provisionerdserver.StaleInterval.
+							AND daemon.last_seen_at >= $1 - INTERVAL '90 seconds'
+					)
+			)
 		ORDER BY
+			-- Prefer builds of workspaces that were last built successfully by
+			-- the caller with the same template version, as the caller likely
+			-- still has the providers cached. Other daemons never wait for the
+			-- preferred one, so affinity doesn't delay jobs.
+			EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_builds AS build
+					INNER JOIN workspace_provisioner_affinities AS affinity ON affinity.workspace_id = build.workspace_id
+				WHERE
+					build.job_id = potential_job.id
+					AND affinity.template_version_id = build.template_version_id
+					AND affinity.provisioner_daemon_id = $2
+			) DESC,
 			potential_job.created_at
 		FOR UPDATE
 		SKIP LOCKED
@@ -7373,6 +9356,98 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 	return i, err
 }
 
+const countInProgressProvisionerJobsByInitiator = `-- name: CountInProgressProvisionerJobsByInitiator :one
+SELECT
+	COUNT(*) AS total,
+	COUNT(*) FILTER (WHERE workspaces.template_id = $1 :: uuid) AS template_builds
+FROM
+	provisioner_jobs
+LEFT JOIN
+	workspace_builds
+ON
+	workspace_builds.job_id = provisioner_jobs.id
+LEFT JOIN
+	workspaces
+ON
+	workspaces.id = workspace_builds.workspace_id
+WHERE
+	provisioner_jobs.initiator_id = $2 :: uuid
+	AND provisioner_jobs.completed_at IS NULL
+`
+
+type CountInProgressProvisionerJobsByInitiatorParams struct {
+	TemplateID  uuid.UUID `db:"template_id" json:"template_id"`
+	InitiatorID uuid.UUID `db:"initiator_id" json:"initiator_id"`
+}
+
+type CountInProgressProvisionerJobsByInitiatorRow struct {
+	Total          int64 `db:"total" json:"total"`
+	TemplateBuilds int64 `db:"template_builds" json:"template_builds"`
+}
+
+// Counts the pending and running provisioner jobs started by a user, both in
+// total and for workspace builds of the given template.
+func (q *sqlQuerier) CountInProgressProvisionerJobsByInitiator(ctx context.Context, arg CountInProgressProvisionerJobsByInitiatorParams) (CountInProgressProvisionerJobsByInitiatorRow, error) {
+	row := q.db.QueryRowContext(ctx, countInProgressProvisionerJobsByInitiator, arg.TemplateID, arg.InitiatorID)
+	var i CountInProgressProvisionerJobsByInitiatorRow
+	err := row.Scan(&i.Total, &i.TemplateBuilds)
+	return i, err
+}
+
+const getPendingProvisionerJobsQueueDepthByTags = `-- name: GetPendingProvisionerJobsQueueDepthByTags :many
+SELECT
+	organization_id,
+	tags,
+	COUNT(*) AS pending_jobs,
+	MIN(created_at) :: timestamptz AS oldest_created_at
+FROM
+	provisioner_jobs
+WHERE
+	job_status = 'pending'
+GROUP BY
+	organization_id, tags
+ORDER BY
+	pending_jobs DESC, oldest_created_at ASC
+`
+
+type GetPendingProvisionerJobsQueueDepthByTagsRow struct {
+	OrganizationID  uuid.UUID `db:"organization_id" json:"organization_id"`
+	Tags            StringMap `db:"tags" json:"tags"`
+	PendingJobs     int64     `db:"pending_jobs" json:"pending_jobs"`
+	OldestCreatedAt time.Time `db:"oldest_created_at" json:"oldest_created_at"`
+}
+
+// Counts the pending jobs grouped by organization and tag set, along with when
+// the oldest of them was created. This is used by the health check to detect
+// tag sets that no provisioner is picking jobs up for.
+func (q *sqlQuerier) GetPendingProvisionerJobsQueueDepthByTags(ctx context.Context) ([]GetPendingProvisionerJobsQueueDepthByTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingProvisionerJobsQueueDepthByTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPendingProvisionerJobsQueueDepthByTagsRow
+	for rows.Next() {
+		var i GetPendingProvisionerJobsQueueDepthByTagsRow
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.Tags,
+			&i.PendingJobs,
+			&i.OldestCreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
 	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
@@ -7449,6 +9524,57 @@ func (q *sqlQuerier) GetProvisionerJobByIDForUpdate(ctx context.Context, id uuid
 	return i, err
 }
 
+const getProvisionerJobThroughputByTags = `-- name: GetProvisionerJobThroughputByTags :many
+SELECT
+	organization_id,
+	tags,
+	COUNT(*) AS started_jobs
+FROM
+	provisioner_jobs
+WHERE
+	organization_id = ANY($1 :: uuid [ ])
+	AND started_at >= $2 :: timestamptz
+GROUP BY
+	organization_id, tags
+`
+
+type GetProvisionerJobThroughputByTagsParams struct {
+	OrganizationIDs []uuid.UUID `db:"organization_ids" json:"organization_ids"`
+	StartedAfter    time.Time   `db:"started_after" json:"started_after"`
+}
+
+type GetProvisionerJobThroughputByTagsRow struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Tags           StringMap `db:"tags" json:"tags"`
+	StartedJobs    int64     `db:"started_jobs" json:"started_jobs"`
+}
+
+// Counts the jobs that were picked up by a provisioner since the given time,
+// grouped by organization and tag set. This is used to estimate when pending
+// jobs will start.
+func (q *sqlQuerier) GetProvisionerJobThroughputByTags(ctx context.Context, arg GetProvisionerJobThroughputByTagsParams) ([]GetProvisionerJobThroughputByTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobThroughputByTags, pq.Array(arg.OrganizationIDs), arg.StartedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProvisionerJobThroughputByTagsRow
+	for rows.Next() {
+		var i GetProvisionerJobThroughputByTagsRow
+		if err := rows.Scan(&i.OrganizationID, &i.Tags, &i.StartedJobs); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerJobTimingsByJobID = `-- name: GetProvisionerJobTimingsByJobID :many
 SELECT job_id, started_at, ended_at, stage, source, action, resource FROM provisioner_job_timings
 WHERE job_id = $1
@@ -7748,7 +9874,16 @@ WHERE
 	pj.organization_id = $1::uuid
 	AND (COALESCE(array_length($2::uuid[], 1), 0) = 0 OR pj.id = ANY($2::uuid[]))
 	AND (COALESCE(array_length($3::provisioner_job_status[], 1), 0) = 0 OR pj.job_status = ANY($3::provisioner_job_status[]))
-	AND ($4::tagset = 'null'::tagset OR provisioner_tagset_contains(pj.tags::tagset, $4::tagset))
+	AND (COALESCE(array_length($4::provisioner_job_type[], 1), 0) = 0 OR pj.type = ANY($4::provisioner_job_type[]))
+	AND ($5::tagset = 'null'::tagset OR provisioner_tagset_contains(pj.tags::tagset, $5::tagset))
+	AND CASE
+		-- This allows using the last job on a page as a cursor. The query is
+		-- ordered by creation time and then ID, descending, so select all
+		-- rows before the cursor.
+		WHEN $6 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			(pj.created_at, pj.id) < (SELECT created_at, id FROM provisioner_jobs WHERE id = $6)
+		ELSE true
+	END
 GROUP BY
 	pj.id,
 	qp.queue_position,
@@ -7762,16 +9897,19 @@ GROUP BY
 	w.name,
 	pd.name
 ORDER BY
-	pj.created_at DESC
+	pj.created_at DESC,
+	pj.id DESC
 LIMIT
-	$5::int
+	$7::int
 `
 
 type GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams struct {
 	OrganizationID uuid.UUID              `db:"organization_id" json:"organization_id"`
 	IDs            []uuid.UUID            `db:"ids" json:"ids"`
 	Status         []ProvisionerJobStatus `db:"status" json:"status"`
+	Types          []ProvisionerJobType   `db:"types" json:"types"`
 	Tags           StringMap              `db:"tags" json:"tags"`
+	AfterID        uuid.UUID              `db:"after_id" json:"after_id"`
 	Limit          sql.NullInt32          `db:"limit" json:"limit"`
 }
 
@@ -7795,7 +9933,9 @@ func (q *sqlQuerier) GetProvisionerJobsByOrganizationAndStatusWithQueuePositionA
 		arg.OrganizationID,
 		pq.Array(arg.IDs),
 		pq.Array(arg.Status),
+		pq.Array(arg.Types),
 		arg.Tags,
+		arg.AfterID,
 		arg.Limit,
 	)
 	if err != nil {
@@ -7897,38 +10037,127 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 	return items, nil
 }
 
-const getProvisionerJobsToBeReaped = `-- name: GetProvisionerJobsToBeReaped :many
+const getProvisionerJobsRunningBetween = `-- name: GetProvisionerJobsRunningBetween :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id,
+	organization_id,
+	tags,
+	created_at,
+	started_at,
+	completed_at
 FROM
 	provisioner_jobs
 WHERE
-	(
-		-- If the job has not been started before @pending_since, reap it.
-		updated_at < $1
-		AND started_at IS NULL
-		AND completed_at IS NULL
-	)
-	OR
-	(
-		-- If the job has been started but not completed before @hung_since, reap it.
-		updated_at < $2
-		AND started_at IS NOT NULL
-		AND completed_at IS NULL
-	)
-ORDER BY random()
-LIMIT $3
+	started_at IS NOT NULL
+	AND started_at < $1 :: timestamptz
+	AND (completed_at IS NULL OR completed_at > $2 :: timestamptz)
+ORDER BY
+	started_at ASC
 `
 
-type GetProvisionerJobsToBeReapedParams struct {
-	PendingSince time.Time `db:"pending_since" json:"pending_since"`
-	HungSince    time.Time `db:"hung_since" json:"hung_since"`
-	MaxJobs      int32     `db:"max_jobs" json:"max_jobs"`
+type GetProvisionerJobsRunningBetweenParams struct {
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	StartTime time.Time `db:"start_time" json:"start_time"`
 }
 
-// To avoid repeatedly attempting to reap the same jobs, we randomly order and limit to @max_jobs.
-func (q *sqlQuerier) GetProvisionerJobsToBeReaped(ctx context.Context, arg GetProvisionerJobsToBeReapedParams) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsToBeReaped, arg.PendingSince, arg.HungSince, arg.MaxJobs)
+type GetProvisionerJobsRunningBetweenRow struct {
+	ID             uuid.UUID    `db:"id" json:"id"`
+	OrganizationID uuid.UUID    `db:"organization_id" json:"organization_id"`
+	Tags           StringMap    `db:"tags" json:"tags"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	StartedAt      sql.NullTime `db:"started_at" json:"started_at"`
+	CompletedAt    sql.NullTime `db:"completed_at" json:"completed_at"`
+}
+
+// Returns the jobs that a provisioner was running at any point between the
+// start and end times. This is used to report provisioner utilization and
+// queue wait times.
+func (q *sqlQuerier) GetProvisionerJobsRunningBetween(ctx context.Context, arg GetProvisionerJobsRunningBetweenParams) ([]GetProvisionerJobsRunningBetweenRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsRunningBetween, arg.EndTime, arg.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProvisionerJobsRunningBetweenRow
+	for rows.Next() {
+		var i GetProvisionerJobsRunningBetweenRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProvisionerJobsToBeReaped = `-- name: GetProvisionerJobsToBeReaped :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+FROM
+	provisioner_jobs
+WHERE
+	(
+		-- If the job has not been started before @pending_since, reap it.
+		updated_at < $1
+		AND started_at IS NULL
+		AND completed_at IS NULL
+		-- Jobs held by a build pause are not timed out.
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				provisioner_build_pauses AS pause
+			WHERE
+				pause.organization_id IS NULL
+				OR pause.organization_id = provisioner_jobs.organization_id
+		)
+	)
+	OR
+	(
+		-- If the job has been started but not completed before @hung_since, reap it.
+		updated_at < $2
+		AND started_at IS NOT NULL
+		AND completed_at IS NULL
+	)
+	OR
+	(
+		-- If the job was canceled before @canceled_since but the provisioner
+		-- has not acknowledged it by completing the job, reap it.
+		canceled_at < $3
+		AND started_at IS NOT NULL
+		AND completed_at IS NULL
+	)
+ORDER BY random()
+LIMIT $4
+`
+
+type GetProvisionerJobsToBeReapedParams struct {
+	PendingSince  time.Time `db:"pending_since" json:"pending_since"`
+	HungSince     time.Time `db:"hung_since" json:"hung_since"`
+	CanceledSince time.Time `db:"canceled_since" json:"canceled_since"`
+	MaxJobs       int32     `db:"max_jobs" json:"max_jobs"`
+}
+
+// To avoid repeatedly attempting to reap the same jobs, we randomly order and limit to @max_jobs.
+func (q *sqlQuerier) GetProvisionerJobsToBeReaped(ctx context.Context, arg GetProvisionerJobsToBeReapedParams) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsToBeReaped,
+		arg.PendingSince,
+		arg.HungSince,
+		arg.CanceledSince,
+		arg.MaxJobs,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -8107,6 +10336,73 @@ func (q *sqlQuerier) InsertProvisionerJobTimings(ctx context.Context, arg Insert
 	return items, nil
 }
 
+const updatePendingProvisionerJobsUpdatedAt = `-- name: UpdatePendingProvisionerJobsUpdatedAt :many
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $1
+WHERE
+	started_at IS NULL
+	AND completed_at IS NULL
+	AND (
+		$2 :: uuid IS NULL
+		OR organization_id = $2
+	)
+RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+`
+
+type UpdatePendingProvisionerJobsUpdatedAtParams struct {
+	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+}
+
+// Restarts the pending timeout of the jobs that have not been acquired yet,
+// either in an organization or, if organization_id is NULL, across the
+// deployment. It is used when builds are resumed so that jobs held by the
+// pause are not reaped right away.
+func (q *sqlQuerier) UpdatePendingProvisionerJobsUpdatedAt(ctx context.Context, arg UpdatePendingProvisionerJobsUpdatedAtParams) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, updatePendingProvisionerJobsUpdatedAt, arg.UpdatedAt, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJob
+	for rows.Next() {
+		var i ProvisionerJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+			&i.JobStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
 UPDATE
 	provisioner_jobs
@@ -8226,11 +10522,15 @@ func (q *sqlQuerier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) err
 
 const getProvisionerKeyByHashedSecret = `-- name: GetProvisionerKeyByHashedSecret :one
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, expires_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
     hashed_secret = $1
+OR
+    -- The previous secret is still returned after a rotation so that the
+    -- caller can decide whether the overlap window is still open.
+    previous_hashed_secret = $1
 `
 
 func (q *sqlQuerier) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (ProvisionerKey, error) {
@@ -8243,13 +10543,17 @@ func (q *sqlQuerier) GetProvisionerKeyByHashedSecret(ctx context.Context, hashed
 		&i.Name,
 		&i.HashedSecret,
 		&i.Tags,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const getProvisionerKeyByID = `-- name: GetProvisionerKeyByID :one
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, expires_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
@@ -8266,13 +10570,17 @@ func (q *sqlQuerier) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (P
 		&i.Name,
 		&i.HashedSecret,
 		&i.Tags,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const getProvisionerKeyByName = `-- name: GetProvisionerKeyByName :one
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, expires_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
@@ -8296,6 +10604,10 @@ func (q *sqlQuerier) GetProvisionerKeyByName(ctx context.Context, arg GetProvisi
 		&i.Name,
 		&i.HashedSecret,
 		&i.Tags,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
@@ -8308,19 +10620,21 @@ INSERT INTO
         organization_id,
         name,
         hashed_secret,
-        tags
+        tags,
+        expires_at
     )
 VALUES
-    ($1, $2, $3, lower($6), $4, $5) RETURNING id, created_at, organization_id, name, hashed_secret, tags
+    ($1, $2, $3, lower($7), $4, $5, $6) RETURNING id, created_at, organization_id, name, hashed_secret, tags, last_used_at, expires_at, previous_hashed_secret, previous_secret_expires_at
 `
 
 type InsertProvisionerKeyParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	CreatedAt      time.Time `db:"created_at" json:"created_at"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
-	Tags           StringMap `db:"tags" json:"tags"`
-	Name           string    `db:"name" json:"name"`
+	ID             uuid.UUID    `db:"id" json:"id"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	OrganizationID uuid.UUID    `db:"organization_id" json:"organization_id"`
+	HashedSecret   []byte       `db:"hashed_secret" json:"hashed_secret"`
+	Tags           StringMap    `db:"tags" json:"tags"`
+	ExpiresAt      sql.NullTime `db:"expires_at" json:"expires_at"`
+	Name           string       `db:"name" json:"name"`
 }
 
 func (q *sqlQuerier) InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error) {
@@ -8330,6 +10644,7 @@ func (q *sqlQuerier) InsertProvisionerKey(ctx context.Context, arg InsertProvisi
 		arg.OrganizationID,
 		arg.HashedSecret,
 		arg.Tags,
+		arg.ExpiresAt,
 		arg.Name,
 	)
 	var i ProvisionerKey
@@ -8340,13 +10655,17 @@ func (q *sqlQuerier) InsertProvisionerKey(ctx context.Context, arg InsertProvisi
 		&i.Name,
 		&i.HashedSecret,
 		&i.Tags,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const listProvisionerKeysByOrganization = `-- name: ListProvisionerKeysByOrganization :many
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, expires_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
@@ -8369,6 +10688,10 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganization(ctx context.Context, orga
 			&i.Name,
 			&i.HashedSecret,
 			&i.Tags,
+			&i.LastUsedAt,
+			&i.ExpiresAt,
+			&i.PreviousHashedSecret,
+			&i.PreviousSecretExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -8385,7 +10708,7 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganization(ctx context.Context, orga
 
 const listProvisionerKeysByOrganizationExcludeReserved = `-- name: ListProvisionerKeysByOrganizationExcludeReserved :many
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, expires_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
@@ -8417,6 +10740,10 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganizationExcludeReserved(ctx contex
 			&i.Name,
 			&i.HashedSecret,
 			&i.Tags,
+			&i.LastUsedAt,
+			&i.ExpiresAt,
+			&i.PreviousHashedSecret,
+			&i.PreviousSecretExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -8431,39 +10758,139 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganizationExcludeReserved(ctx contex
 	return items, nil
 }
 
-const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
+const rotateProvisionerKey = `-- name: RotateProvisionerKey :one
+UPDATE
+    provisioner_keys
+SET
+    previous_hashed_secret = hashed_secret,
+    previous_secret_expires_at = $1,
+    hashed_secret = $2,
+    expires_at = $3
+WHERE
+    id = $4
+RETURNING id, created_at, organization_id, name, hashed_secret, tags, last_used_at, expires_at, previous_hashed_secret, previous_secret_expires_at
+`
+
+type RotateProvisionerKeyParams struct {
+	PreviousSecretExpiresAt sql.NullTime `db:"previous_secret_expires_at" json:"previous_secret_expires_at"`
+	HashedSecret            []byte       `db:"hashed_secret" json:"hashed_secret"`
+	ExpiresAt               sql.NullTime `db:"expires_at" json:"expires_at"`
+	ID                      uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) RotateProvisionerKey(ctx context.Context, arg RotateProvisionerKeyParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, rotateProvisionerKey,
+		arg.PreviousSecretExpiresAt,
+		arg.HashedSecret,
+		arg.ExpiresAt,
+		arg.ID,
+	)
+	var i ProvisionerKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
+	)
+	return i, err
+}
+
+const updateProvisionerKeyLastUsedAt = `-- name: UpdateProvisionerKeyLastUsedAt :exec
+UPDATE
+    provisioner_keys
+SET
+    last_used_at = $1
+WHERE
+    id = $2
+`
+
+type UpdateProvisionerKeyLastUsedAtParams struct {
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg UpdateProvisionerKeyLastUsedAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerKeyLastUsedAt, arg.LastUsedAt, arg.ID)
+	return err
+}
+
+const deleteProvisionerReservation = `-- name: DeleteProvisionerReservation :exec
+DELETE FROM
+	provisioner_reservations
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteProvisionerReservation(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProvisionerReservation, id)
+	return err
+}
+
+const getProvisionerReservationByID = `-- name: GetProvisionerReservationByID :one
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+	id, organization_id, tags, slots, starts_at, ends_at, created_at
 FROM
-	workspace_proxies
+	provisioner_reservations
 WHERE
-	deleted = false
+	id = $1
 `
 
-func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceProxies)
+func (q *sqlQuerier) GetProvisionerReservationByID(ctx context.Context, id uuid.UUID) (ProvisionerReservation, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerReservationByID, id)
+	var i ProvisionerReservation
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Tags,
+		&i.Slots,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProvisionerReservationsByOrganization = `-- name: GetProvisionerReservationsByOrganization :many
+SELECT
+	id, organization_id, tags, slots, starts_at, ends_at, created_at
+FROM
+	provisioner_reservations
+WHERE
+	organization_id = $1
+	AND ends_at > $2 :: timestamptz
+ORDER BY
+	starts_at ASC
+`
+
+type GetProvisionerReservationsByOrganizationParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	EndsAfter      time.Time `db:"ends_after" json:"ends_after"`
+}
+
+// Returns the reservations of an organization that have not ended yet.
+func (q *sqlQuerier) GetProvisionerReservationsByOrganization(ctx context.Context, arg GetProvisionerReservationsByOrganizationParams) ([]ProvisionerReservation, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerReservationsByOrganization, arg.OrganizationID, arg.EndsAfter)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceProxy
+	var items []ProvisionerReservation
 	for rows.Next() {
-		var i WorkspaceProxy
+		var i ProvisionerReservation
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Url,
-			&i.WildcardHostname,
+			&i.OrganizationID,
+			&i.Tags,
+			&i.Slots,
+			&i.StartsAt,
+			&i.EndsAt,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Deleted,
-			&i.TokenHashedSecret,
-			&i.RegionID,
-			&i.DerpEnabled,
-			&i.DerpOnly,
-			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -8478,27 +10905,123 @@ func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy,
 	return items, nil
 }
 
-const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
-SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
-FROM
-	workspace_proxies
-WHERE
-	-- Validate that the @hostname has been sanitized and is not empty. This
-	-- doesn't prevent SQL injection (already prevented by using prepared
-	-- queries), but it does prevent carefully crafted hostnames from matching
-	-- when they shouldn't.
-	--
-	-- Periods don't need to be escaped because they're not special characters
-	-- in SQL matches unlike regular expressions.
-	$1 :: text SIMILAR TO '[a-zA-Z0-9._-]+' AND
-	deleted = false AND
+const insertProvisionerReservation = `-- name: InsertProvisionerReservation :one
+INSERT INTO
+	provisioner_reservations (
+		id,
+		organization_id,
+		tags,
+		slots,
+		starts_at,
+		ends_at,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, organization_id, tags, slots, starts_at, ends_at, created_at
+`
 
-	-- Validate that the hostname matches either the wildcard hostname or the
-	-- access URL (ignoring scheme, port and path).
-	(
-		(
-			$2 :: bool = true AND
+type InsertProvisionerReservationParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Tags           StringMap `db:"tags" json:"tags"`
+	Slots          int32     `db:"slots" json:"slots"`
+	StartsAt       time.Time `db:"starts_at" json:"starts_at"`
+	EndsAt         time.Time `db:"ends_at" json:"ends_at"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertProvisionerReservation(ctx context.Context, arg InsertProvisionerReservationParams) (ProvisionerReservation, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerReservation,
+		arg.ID,
+		arg.OrganizationID,
+		arg.Tags,
+		arg.Slots,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.CreatedAt,
+	)
+	var i ProvisionerReservation
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Tags,
+		&i.Slots,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+FROM
+	workspace_proxies
+WHERE
+	deleted = false
+`
+
+func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceProxies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceProxy
+	for rows.Next() {
+		var i WorkspaceProxy
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Url,
+			&i.WildcardHostname,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.TokenHashedSecret,
+			&i.RegionID,
+			&i.DerpEnabled,
+			&i.DerpOnly,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+FROM
+	workspace_proxies
+WHERE
+	-- Validate that the @hostname has been sanitized and is not empty. This
+	-- doesn't prevent SQL injection (already prevented by using prepared
+	-- queries), but it does prevent carefully crafted hostnames from matching
+	-- when they shouldn't.
+	--
+	-- Periods don't need to be escaped because they're not special characters
+	-- in SQL matches unlike regular expressions.
+	$1 :: text SIMILAR TO '[a-zA-Z0-9._-]+' AND
+	deleted = false AND
+
+	-- Validate that the hostname matches either the wildcard hostname or the
+	-- access URL (ignoring scheme, port and path).
+	(
+		(
+			$2 :: bool = true AND
 			url SIMILAR TO '[^:]*://' || $1 :: text || '([:/]?%)*'
 		) OR
 		(
@@ -8875,6 +11398,241 @@ func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, arg GetQuotaCo
 	return column_1, err
 }
 
+const getQuotaMonthlyBudgetForUser = `-- name: GetQuotaMonthlyBudgetForUser :one
+SELECT
+	coalesce(SUM(groups.monthly_budget), 0)::BIGINT
+FROM
+	(
+		-- Select all groups this user is a member of. This will also include
+		-- the "Everyone" group for organizations the user is a member of.
+		SELECT user_id, user_email, user_username, user_hashed_password, user_created_at, user_updated_at, user_status, user_rbac_roles, user_login_type, user_avatar_url, user_deleted, user_last_seen_at, user_quiet_hours_schedule, user_name, user_github_com_user_id, user_is_system, organization_id, group_name, group_id FROM group_members_expanded
+		         WHERE
+		             $1 = user_id AND
+		             $2 = group_members_expanded.organization_id
+	) AS members
+INNER JOIN groups ON
+	members.group_id = groups.id
+`
+
+type GetQuotaMonthlyBudgetForUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) GetQuotaMonthlyBudgetForUser(ctx context.Context, arg GetQuotaMonthlyBudgetForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaMonthlyBudgetForUser, arg.UserID, arg.OrganizationID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getQuotaMonthlySpendForUser = `-- name: GetQuotaMonthlySpendForUser :one
+SELECT
+	coalesce(SUM(cost), 0)::double precision
+FROM
+	workspace_daily_costs
+WHERE
+	owner_id = $1 AND
+	organization_id = $2 AND
+	start_time >= $3::timestamptz
+`
+
+type GetQuotaMonthlySpendForUserParams struct {
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	StartTime      time.Time `db:"start_time" json:"start_time"`
+}
+
+// Returns the credits spent by the workspaces of the user in the organization
+// since the start time, as rolled up in workspace_daily_costs.
+func (q *sqlQuerier) GetQuotaMonthlySpendForUser(ctx context.Context, arg GetQuotaMonthlySpendForUserParams) (float64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaMonthlySpendForUser, arg.OwnerID, arg.OrganizationID, arg.StartTime)
+	var column_1 float64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const deleteTemplateReleaseChannel = `-- name: DeleteTemplateReleaseChannel :exec
+DELETE FROM
+	template_release_channels
+WHERE
+	template_id = $1
+	AND channel = $2
+`
+
+type DeleteTemplateReleaseChannelParams struct {
+	TemplateID uuid.UUID      `db:"template_id" json:"template_id"`
+	Channel    ReleaseChannel `db:"channel" json:"channel"`
+}
+
+func (q *sqlQuerier) DeleteTemplateReleaseChannel(ctx context.Context, arg DeleteTemplateReleaseChannelParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateReleaseChannel, arg.TemplateID, arg.Channel)
+	return err
+}
+
+const deleteWorkspaceReleaseChannelByWorkspaceID = `-- name: DeleteWorkspaceReleaseChannelByWorkspaceID :exec
+DELETE FROM
+	workspace_release_channels
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceReleaseChannelByWorkspaceID, workspaceID)
+	return err
+}
+
+const getTemplateReleaseChannel = `-- name: GetTemplateReleaseChannel :one
+SELECT
+	template_id, channel, template_version_id, updated_by, updated_at
+FROM
+	template_release_channels
+WHERE
+	template_id = $1
+	AND channel = $2
+`
+
+type GetTemplateReleaseChannelParams struct {
+	TemplateID uuid.UUID      `db:"template_id" json:"template_id"`
+	Channel    ReleaseChannel `db:"channel" json:"channel"`
+}
+
+func (q *sqlQuerier) GetTemplateReleaseChannel(ctx context.Context, arg GetTemplateReleaseChannelParams) (TemplateReleaseChannel, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateReleaseChannel, arg.TemplateID, arg.Channel)
+	var i TemplateReleaseChannel
+	err := row.Scan(
+		&i.TemplateID,
+		&i.Channel,
+		&i.TemplateVersionID,
+		&i.UpdatedBy,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateReleaseChannelsByTemplateID = `-- name: GetTemplateReleaseChannelsByTemplateID :many
+SELECT
+	template_id, channel, template_version_id, updated_by, updated_at
+FROM
+	template_release_channels
+WHERE
+	template_id = $1
+ORDER BY
+	channel ASC
+`
+
+func (q *sqlQuerier) GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateReleaseChannel, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateReleaseChannelsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateReleaseChannel
+	for rows.Next() {
+		var i TemplateReleaseChannel
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.Channel,
+			&i.TemplateVersionID,
+			&i.UpdatedBy,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceReleaseChannelByWorkspaceID = `-- name: GetWorkspaceReleaseChannelByWorkspaceID :one
+SELECT
+	workspace_id, channel, updated_at
+FROM
+	workspace_release_channels
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceReleaseChannelByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceReleaseChannel, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceReleaseChannelByWorkspaceID, workspaceID)
+	var i WorkspaceReleaseChannel
+	err := row.Scan(&i.WorkspaceID, &i.Channel, &i.UpdatedAt)
+	return i, err
+}
+
+const upsertTemplateReleaseChannel = `-- name: UpsertTemplateReleaseChannel :one
+INSERT INTO
+	template_release_channels (
+		template_id,
+		channel,
+		template_version_id,
+		updated_by,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (template_id, channel) DO UPDATE SET
+	template_version_id = EXCLUDED.template_version_id,
+	updated_by = EXCLUDED.updated_by,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, channel, template_version_id, updated_by, updated_at
+`
+
+type UpsertTemplateReleaseChannelParams struct {
+	TemplateID        uuid.UUID      `db:"template_id" json:"template_id"`
+	Channel           ReleaseChannel `db:"channel" json:"channel"`
+	TemplateVersionID uuid.UUID      `db:"template_version_id" json:"template_version_id"`
+	UpdatedBy         uuid.UUID      `db:"updated_by" json:"updated_by"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// Publishes a template version to a release channel of its template.
+func (q *sqlQuerier) UpsertTemplateReleaseChannel(ctx context.Context, arg UpsertTemplateReleaseChannelParams) (TemplateReleaseChannel, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateReleaseChannel,
+		arg.TemplateID,
+		arg.Channel,
+		arg.TemplateVersionID,
+		arg.UpdatedBy,
+		arg.UpdatedAt,
+	)
+	var i TemplateReleaseChannel
+	err := row.Scan(
+		&i.TemplateID,
+		&i.Channel,
+		&i.TemplateVersionID,
+		&i.UpdatedBy,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertWorkspaceReleaseChannel = `-- name: UpsertWorkspaceReleaseChannel :exec
+INSERT INTO
+	workspace_release_channels (workspace_id, channel, updated_at)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	channel = EXCLUDED.channel,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertWorkspaceReleaseChannelParams struct {
+	WorkspaceID uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	Channel     ReleaseChannel `db:"channel" json:"channel"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceReleaseChannel(ctx context.Context, arg UpsertWorkspaceReleaseChannelParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceReleaseChannel, arg.WorkspaceID, arg.Channel, arg.UpdatedAt)
+	return err
+}
+
 const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
 DELETE FROM replicas WHERE updated_at < $1
 `
@@ -9259,18 +12017,124 @@ func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleP
 	return i, err
 }
 
-const deleteRuntimeConfig = `-- name: DeleteRuntimeConfig :exec
-DELETE FROM site_configs
-WHERE site_configs.key = $1
+const getServiceAccountByUserID = `-- name: GetServiceAccountByUserID :one
+SELECT
+	user_id, organization_id, description, created_by, created_at
+FROM
+	service_accounts
+WHERE
+	user_id = $1
 `
 
-func (q *sqlQuerier) DeleteRuntimeConfig(ctx context.Context, key string) error {
-	_, err := q.db.ExecContext(ctx, deleteRuntimeConfig, key)
-	return err
+func (q *sqlQuerier) GetServiceAccountByUserID(ctx context.Context, userID uuid.UUID) (ServiceAccount, error) {
+	row := q.db.QueryRowContext(ctx, getServiceAccountByUserID, userID)
+	var i ServiceAccount
+	err := row.Scan(
+		&i.UserID,
+		&i.OrganizationID,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const getAnnouncementBanners = `-- name: GetAnnouncementBanners :one
-SELECT value FROM site_configs WHERE key = 'announcement_banners'
+const getServiceAccountsByOrganizationID = `-- name: GetServiceAccountsByOrganizationID :many
+SELECT
+	service_accounts.user_id, service_accounts.organization_id, service_accounts.description, service_accounts.created_by, service_accounts.created_at
+FROM
+	service_accounts
+JOIN
+	users ON users.id = service_accounts.user_id
+WHERE
+	service_accounts.organization_id = $1
+	AND users.deleted = false
+ORDER BY
+	service_accounts.created_at ASC
+`
+
+func (q *sqlQuerier) GetServiceAccountsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]ServiceAccount, error) {
+	rows, err := q.db.QueryContext(ctx, getServiceAccountsByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceAccount
+	for rows.Next() {
+		var i ServiceAccount
+		if err := rows.Scan(
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertServiceAccount = `-- name: InsertServiceAccount :one
+INSERT INTO
+	service_accounts (
+		user_id,
+		organization_id,
+		description,
+		created_by,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+RETURNING user_id, organization_id, description, created_by, created_at
+`
+
+type InsertServiceAccountParams struct {
+	UserID         uuid.UUID     `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	Description    string        `db:"description" json:"description"`
+	CreatedBy      uuid.NullUUID `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertServiceAccount(ctx context.Context, arg InsertServiceAccountParams) (ServiceAccount, error) {
+	row := q.db.QueryRowContext(ctx, insertServiceAccount,
+		arg.UserID,
+		arg.OrganizationID,
+		arg.Description,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i ServiceAccount
+	err := row.Scan(
+		&i.UserID,
+		&i.OrganizationID,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteRuntimeConfig = `-- name: DeleteRuntimeConfig :exec
+DELETE FROM site_configs
+WHERE site_configs.key = $1
+`
+
+func (q *sqlQuerier) DeleteRuntimeConfig(ctx context.Context, key string) error {
+	_, err := q.db.ExecContext(ctx, deleteRuntimeConfig, key)
+	return err
+}
+
+const getAnnouncementBanners = `-- name: GetAnnouncementBanners :one
+SELECT value FROM site_configs WHERE key = 'announcement_banners'
 `
 
 func (q *sqlQuerier) GetAnnouncementBanners(ctx context.Context) (string, error) {
@@ -9313,6 +12177,17 @@ func (q *sqlQuerier) GetCoordinatorResumeTokenSigningKey(ctx context.Context) (s
 	return value, err
 }
 
+const getDBPurgeStats = `-- name: GetDBPurgeStats :one
+SELECT value FROM site_configs WHERE key = 'dbpurge_stats'
+`
+
+func (q *sqlQuerier) GetDBPurgeStats(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDBPurgeStats)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
 const getDERPMeshKey = `-- name: GetDERPMeshKey :one
 SELECT value FROM site_configs WHERE key = 'derp_mesh_key'
 `
@@ -9365,6 +12240,17 @@ func (q *sqlQuerier) GetHealthSettings(ctx context.Context) (string, error) {
 	return health_settings, err
 }
 
+const getJobReaperLastRunAt = `-- name: GetJobReaperLastRunAt :one
+SELECT value FROM site_configs WHERE key = 'job_reaper_last_run_at'
+`
+
+func (q *sqlQuerier) GetJobReaperLastRunAt(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getJobReaperLastRunAt)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
 const getLastUpdateCheck = `-- name: GetLastUpdateCheck :one
 SELECT value FROM site_configs WHERE key = 'last_update_check'
 `
@@ -9427,6 +12313,18 @@ func (q *sqlQuerier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	return value, err
 }
 
+const getReadOnlySettings = `-- name: GetReadOnlySettings :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'read_only_settings'), '{}') :: text AS read_only_settings
+`
+
+func (q *sqlQuerier) GetReadOnlySettings(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getReadOnlySettings)
+	var read_only_settings string
+	err := row.Scan(&read_only_settings)
+	return read_only_settings, err
+}
+
 const getRuntimeConfig = `-- name: GetRuntimeConfig :one
 SELECT value FROM site_configs WHERE site_configs.key = $1
 `
@@ -9514,6 +12412,16 @@ func (q *sqlQuerier) UpsertCoordinatorResumeTokenSigningKey(ctx context.Context,
 	return err
 }
 
+const upsertDBPurgeStats = `-- name: UpsertDBPurgeStats :exec
+INSERT INTO site_configs (key, value) VALUES ('dbpurge_stats', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'dbpurge_stats'
+`
+
+func (q *sqlQuerier) UpsertDBPurgeStats(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertDBPurgeStats, value)
+	return err
+}
+
 const upsertDefaultProxy = `-- name: UpsertDefaultProxy :exec
 INSERT INTO site_configs (key, value)
 VALUES
@@ -9547,6 +12455,16 @@ func (q *sqlQuerier) UpsertHealthSettings(ctx context.Context, value string) err
 	return err
 }
 
+const upsertJobReaperLastRunAt = `-- name: UpsertJobReaperLastRunAt :exec
+INSERT INTO site_configs (key, value) VALUES ('job_reaper_last_run_at', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'job_reaper_last_run_at'
+`
+
+func (q *sqlQuerier) UpsertJobReaperLastRunAt(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertJobReaperLastRunAt, value)
+	return err
+}
+
 const upsertLastUpdateCheck = `-- name: UpsertLastUpdateCheck :exec
 INSERT INTO site_configs (key, value) VALUES ('last_update_check', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update_check'
@@ -9609,6 +12527,16 @@ func (q *sqlQuerier) UpsertOAuthSigningKey(ctx context.Context, value string) er
 	return err
 }
 
+const upsertReadOnlySettings = `-- name: UpsertReadOnlySettings :exec
+INSERT INTO site_configs (key, value) VALUES ('read_only_settings', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'read_only_settings'
+`
+
+func (q *sqlQuerier) UpsertReadOnlySettings(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertReadOnlySettings, value)
+	return err
+}
+
 const upsertRuntimeConfig = `-- name: UpsertRuntimeConfig :exec
 INSERT INTO site_configs (key, value) VALUES ($1, $2)
 ON CONFLICT (key) DO UPDATE SET value = $2 WHERE site_configs.key = $1
@@ -10464,100 +13392,513 @@ func (q *sqlQuerier) UpsertTelemetryItem(ctx context.Context, arg UpsertTelemetr
 	return err
 }
 
-const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
-WITH build_times AS (
+const getTemplateBuildDurationStats = `-- name: GetTemplateBuildDurationStats :many
 SELECT
-	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
-	workspace_builds.transition
+	start_time, end_time, template_id, transition, build_count, p50_duration_ms, p95_duration_ms, max_duration_ms
 FROM
-	workspace_builds
-JOIN template_versions ON
-	workspace_builds.template_version_id = template_versions.id
-JOIN provisioner_jobs pj ON
-	workspace_builds.job_id = pj.id
+	template_build_duration_stats
 WHERE
-	template_versions.template_id = $1 AND
-		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
-		(pj.started_at > $2) AND
-		(pj.canceled_at IS NULL) AND
-		((pj.error IS NULL) OR (pj.error = ''))
+	template_id = $1
+	AND start_time >= $2::timestamptz
 ORDER BY
-	workspace_builds.created_at DESC
+	start_time ASC,
+	transition ASC
+`
+
+type GetTemplateBuildDurationStatsParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	StartTime  time.Time `db:"start_time" json:"start_time"`
+}
+
+func (q *sqlQuerier) GetTemplateBuildDurationStats(ctx context.Context, arg GetTemplateBuildDurationStatsParams) ([]TemplateBuildDurationStat, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateBuildDurationStats, arg.TemplateID, arg.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateBuildDurationStat
+	for rows.Next() {
+		var i TemplateBuildDurationStat
+		if err := rows.Scan(
+			&i.StartTime,
+			&i.EndTime,
+			&i.TemplateID,
+			&i.Transition,
+			&i.BuildCount,
+			&i.P50DurationMs,
+			&i.P95DurationMs,
+			&i.MaxDurationMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateBuildDurationStats = `-- name: UpsertTemplateBuildDurationStats :exec
+WITH
+	latest_start AS (
+		SELECT
+			COALESCE(
+				MAX(start_time) - '1 day'::interval,
+				-- Fallback when there are no build duration stats yet.
+				date_trunc('day', (
+					SELECT MIN(completed_at) FROM provisioner_jobs WHERE type = 'workspace_build'
+				) AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+			) AS t
+		FROM
+			template_build_duration_stats
+	),
+	build_durations AS (
+		SELECT
+			-- Buckets are UTC days regardless of the time zone of the
+			-- database session.
+			date_trunc('day', pj.completed_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS time_bucket,
+			tv.template_id,
+			wb.transition,
+			EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)) * 1000 AS duration_ms
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		JOIN
+			template_versions AS tv
+		ON
+			tv.id = wb.template_version_id
+		WHERE
+			tv.template_id IS NOT NULL
+			AND pj.started_at IS NOT NULL
+			AND pj.completed_at >= (SELECT t FROM latest_start)
+			AND pj.canceled_at IS NULL
+			AND (pj.error IS NULL OR pj.error = '')
+	)
+INSERT INTO template_build_duration_stats AS tbds (
+	start_time,
+	end_time,
+	template_id,
+	transition,
+	build_count,
+	p50_duration_ms,
+	p95_duration_ms,
+	max_duration_ms
 )
 SELECT
-	-- Postgres offers no clear way to DRY this short of a function or other
-	-- complexities.
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
-FROM build_times
+	time_bucket AS start_time,
+	time_bucket + '1 day'::interval AS end_time,
+	template_id,
+	transition,
+	COUNT(*) AS build_count,
+	(PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY duration_ms))::bigint AS p50_duration_ms,
+	(PERCENTILE_DISC(0.95) WITHIN GROUP (ORDER BY duration_ms))::bigint AS p95_duration_ms,
+	MAX(duration_ms)::bigint AS max_duration_ms
+FROM
+	build_durations
+GROUP BY
+	time_bucket, template_id, transition
+ON CONFLICT
+	(start_time, template_id, transition)
+DO UPDATE
+SET
+	build_count = EXCLUDED.build_count,
+	p50_duration_ms = EXCLUDED.p50_duration_ms,
+	p95_duration_ms = EXCLUDED.p95_duration_ms,
+	max_duration_ms = EXCLUDED.max_duration_ms
+WHERE
+	(tbds.build_count, tbds.p50_duration_ms, tbds.p95_duration_ms, tbds.max_duration_ms)
+	IS DISTINCT FROM
+	(EXCLUDED.build_count, EXCLUDED.p50_duration_ms, EXCLUDED.p95_duration_ms, EXCLUDED.max_duration_ms)
 `
 
-type GetTemplateAverageBuildTimeParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
+// This query aggregates the durations of successful workspace builds into
+// daily buckets per template and transition. The result is stored in the
+// template_build_duration_stats table. The most recent bucket and the one
+// before it are recomputed on every run, since builds keep completing in them.
+func (q *sqlQuerier) UpsertTemplateBuildDurationStats(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, upsertTemplateBuildDurationStats)
+	return err
 }
 
-type GetTemplateAverageBuildTimeRow struct {
-	Start50  float64 `db:"start_50" json:"start_50"`
-	Stop50   float64 `db:"stop_50" json:"stop_50"`
-	Delete50 float64 `db:"delete_50" json:"delete_50"`
-	Start95  float64 `db:"start_95" json:"start_95"`
-	Stop95   float64 `db:"stop_95" json:"stop_95"`
-	Delete95 float64 `db:"delete_95" json:"delete_95"`
+const deleteTemplatePresetByID = `-- name: DeleteTemplatePresetByID :exec
+DELETE FROM
+	template_presets
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteTemplatePresetByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplatePresetByID, id)
+	return err
 }
 
-func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
-	var i GetTemplateAverageBuildTimeRow
+const deleteTemplatePresetGroupDefaultsByPresetID = `-- name: DeleteTemplatePresetGroupDefaultsByPresetID :exec
+DELETE FROM
+	template_preset_group_defaults
+WHERE
+	template_preset_id = $1
+`
+
+func (q *sqlQuerier) DeleteTemplatePresetGroupDefaultsByPresetID(ctx context.Context, templatePresetID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplatePresetGroupDefaultsByPresetID, templatePresetID)
+	return err
+}
+
+const getDefaultTemplatePresetForUser = `-- name: GetDefaultTemplatePresetForUser :one
+SELECT
+	template_presets.id, template_presets.template_id, template_presets.name, template_presets.description, template_presets.parameters, template_presets.created_at, template_presets.updated_at
+FROM
+	template_presets
+	INNER JOIN template_preset_group_defaults ON template_preset_group_defaults.template_preset_id = template_presets.id
+	INNER JOIN group_members_expanded ON group_members_expanded.group_id = template_preset_group_defaults.group_id
+WHERE
+	template_preset_group_defaults.template_id = $1
+	AND group_members_expanded.user_id = $2
+ORDER BY
+	group_members_expanded.group_id = group_members_expanded.organization_id,
+	group_members_expanded.group_name
+LIMIT 1
+`
+
+type GetDefaultTemplatePresetForUserParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Returns the default preset of the template for the groups of the user.
+// Defaults of groups take precedence over the default of the "Everyone" group,
+// and ties are broken by group name.
+func (q *sqlQuerier) GetDefaultTemplatePresetForUser(ctx context.Context, arg GetDefaultTemplatePresetForUserParams) (TemplatePreset, error) {
+	row := q.db.QueryRowContext(ctx, getDefaultTemplatePresetForUser, arg.TemplateID, arg.UserID)
+	var i TemplatePreset
 	err := row.Scan(
-		&i.Start50,
-		&i.Stop50,
-		&i.Delete50,
-		&i.Start95,
-		&i.Stop95,
-		&i.Delete95,
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Parameters,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getTemplateByID = `-- name: GetTemplateByID :one
+const getTemplatePresetByID = `-- name: GetTemplatePresetByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, template_id, name, description, parameters, created_at, updated_at
 FROM
-	template_with_names
+	template_presets
 WHERE
 	id = $1
-LIMIT
-	1
 `
 
-func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
-	var i Template
+func (q *sqlQuerier) GetTemplatePresetByID(ctx context.Context, id uuid.UUID) (TemplatePreset, error) {
+	row := q.db.QueryRowContext(ctx, getTemplatePresetByID, id)
+	var i TemplatePreset
 	err := row.Scan(
 		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.OrganizationID,
-		&i.Deleted,
+		&i.TemplateID,
 		&i.Name,
-		&i.Provisioner,
-		&i.ActiveVersionID,
 		&i.Description,
-		&i.DefaultTTL,
-		&i.CreatedBy,
-		&i.Icon,
-		&i.UserACL,
-		&i.GroupACL,
-		&i.DisplayName,
-		&i.AllowUserCancelWorkspaceJobs,
-		&i.AllowUserAutostart,
-		&i.AllowUserAutostop,
-		&i.FailureTTL,
+		&i.Parameters,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplatePresetGroupDefaultsByTemplateID = `-- name: GetTemplatePresetGroupDefaultsByTemplateID :many
+SELECT
+	template_id, group_id, template_preset_id
+FROM
+	template_preset_group_defaults
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) GetTemplatePresetGroupDefaultsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplatePresetGroupDefault, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplatePresetGroupDefaultsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplatePresetGroupDefault
+	for rows.Next() {
+		var i TemplatePresetGroupDefault
+		if err := rows.Scan(&i.TemplateID, &i.GroupID, &i.TemplatePresetID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplatePresetsByTemplateID = `-- name: GetTemplatePresetsByTemplateID :many
+SELECT
+	id, template_id, name, description, parameters, created_at, updated_at
+FROM
+	template_presets
+WHERE
+	template_id = $1
+ORDER BY
+	name
+`
+
+func (q *sqlQuerier) GetTemplatePresetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplatePreset, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplatePresetsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplatePreset
+	for rows.Next() {
+		var i TemplatePreset
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.Name,
+			&i.Description,
+			&i.Parameters,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplatePreset = `-- name: InsertTemplatePreset :one
+INSERT INTO
+	template_presets (
+		id,
+		template_id,
+		name,
+		description,
+		parameters,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, template_id, name, description, parameters, created_at, updated_at
+`
+
+type InsertTemplatePresetParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	TemplateID  uuid.UUID `db:"template_id" json:"template_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Parameters  StringMap `db:"parameters" json:"parameters"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertTemplatePreset(ctx context.Context, arg InsertTemplatePresetParams) (TemplatePreset, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplatePreset,
+		arg.ID,
+		arg.TemplateID,
+		arg.Name,
+		arg.Description,
+		arg.Parameters,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i TemplatePreset
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Parameters,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateTemplatePresetByID = `-- name: UpdateTemplatePresetByID :one
+UPDATE
+	template_presets
+SET
+	name = $1,
+	description = $2,
+	parameters = $3,
+	updated_at = $4
+WHERE
+	id = $5
+RETURNING id, template_id, name, description, parameters, created_at, updated_at
+`
+
+type UpdateTemplatePresetByIDParams struct {
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Parameters  StringMap `db:"parameters" json:"parameters"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateTemplatePresetByID(ctx context.Context, arg UpdateTemplatePresetByIDParams) (TemplatePreset, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplatePresetByID,
+		arg.Name,
+		arg.Description,
+		arg.Parameters,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i TemplatePreset
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Parameters,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTemplatePresetGroupDefault = `-- name: UpsertTemplatePresetGroupDefault :exec
+INSERT INTO
+	template_preset_group_defaults (
+		template_id,
+		group_id,
+		template_preset_id
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (template_id, group_id) DO UPDATE SET
+	template_preset_id = EXCLUDED.template_preset_id
+`
+
+type UpsertTemplatePresetGroupDefaultParams struct {
+	TemplateID       uuid.UUID `db:"template_id" json:"template_id"`
+	GroupID          uuid.UUID `db:"group_id" json:"group_id"`
+	TemplatePresetID uuid.UUID `db:"template_preset_id" json:"template_preset_id"`
+}
+
+// A group has at most one default preset per template, so making a preset the
+// default of a group replaces the previous default.
+func (q *sqlQuerier) UpsertTemplatePresetGroupDefault(ctx context.Context, arg UpsertTemplatePresetGroupDefaultParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTemplatePresetGroupDefault, arg.TemplateID, arg.GroupID, arg.TemplatePresetID)
+	return err
+}
+
+const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
+WITH build_times AS (
+SELECT
+	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
+	workspace_builds.transition
+FROM
+	workspace_builds
+JOIN template_versions ON
+	workspace_builds.template_version_id = template_versions.id
+JOIN provisioner_jobs pj ON
+	workspace_builds.job_id = pj.id
+WHERE
+	template_versions.template_id = $1 AND
+		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
+		(pj.started_at > $2) AND
+		(pj.canceled_at IS NULL) AND
+		((pj.error IS NULL) OR (pj.error = ''))
+ORDER BY
+	workspace_builds.created_at DESC
+)
+SELECT
+	-- Postgres offers no clear way to DRY this short of a function or other
+	-- complexities.
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
+FROM build_times
+`
+
+type GetTemplateAverageBuildTimeParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
+}
+
+type GetTemplateAverageBuildTimeRow struct {
+	Start50  float64 `db:"start_50" json:"start_50"`
+	Stop50   float64 `db:"stop_50" json:"stop_50"`
+	Delete50 float64 `db:"delete_50" json:"delete_50"`
+	Start95  float64 `db:"start_95" json:"start_95"`
+	Stop95   float64 `db:"stop_95" json:"stop_95"`
+	Delete95 float64 `db:"delete_95" json:"delete_95"`
+}
+
+func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
+	var i GetTemplateAverageBuildTimeRow
+	err := row.Scan(
+		&i.Start50,
+		&i.Stop50,
+		&i.Delete50,
+		&i.Start95,
+		&i.Stop95,
+		&i.Delete95,
+	)
+	return i, err
+}
+
+const getTemplateByID = `-- name: GetTemplateByID :one
+SELECT
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, max_concurrent_jobs_per_user, resource_ceilings, required_provisioner_tags, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon, shared_organization_ids
+FROM
+	template_with_names
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
+		&i.Name,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.DefaultTTL,
+		&i.CreatedBy,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.DisplayName,
+		&i.AllowUserCancelWorkspaceJobs,
+		&i.AllowUserAutostart,
+		&i.AllowUserAutostop,
+		&i.FailureTTL,
 		&i.TimeTilDormant,
 		&i.TimeTilDormantAutoDelete,
 		&i.AutostopRequirementDaysOfWeek,
@@ -10568,19 +13909,23 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
 		&i.UseClassicParameterFlow,
+		&i.MaxConcurrentJobsPerUser,
+		&i.ResourceCeilings,
+		&i.RequiredProvisionerTags,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
 		&i.OrganizationName,
 		&i.OrganizationDisplayName,
 		&i.OrganizationIcon,
+		pq.Array(&i.SharedOrganizationIDs),
 	)
 	return i, err
 }
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, max_concurrent_jobs_per_user, resource_ceilings, required_provisioner_tags, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon, shared_organization_ids
 FROM
 	template_with_names AS templates
 WHERE
@@ -10630,18 +13975,22 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
 		&i.UseClassicParameterFlow,
+		&i.MaxConcurrentJobsPerUser,
+		&i.ResourceCeilings,
+		&i.RequiredProvisionerTags,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
 		&i.OrganizationName,
 		&i.OrganizationDisplayName,
 		&i.OrganizationIcon,
+		pq.Array(&i.SharedOrganizationIDs),
 	)
 	return i, err
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, max_concurrent_jobs_per_user, resource_ceilings, required_provisioner_tags, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon, shared_organization_ids FROM template_with_names AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -10684,12 +14033,16 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.MaxConcurrentJobsPerUser,
+			&i.ResourceCeilings,
+			&i.RequiredProvisionerTags,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
 			&i.OrganizationName,
 			&i.OrganizationDisplayName,
 			&i.OrganizationIcon,
+			pq.Array(&i.SharedOrganizationIDs),
 		); err != nil {
 			return nil, err
 		}
@@ -10706,7 +14059,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	t.id, t.created_at, t.updated_at, t.organization_id, t.deleted, t.name, t.provisioner, t.active_version_id, t.description, t.default_ttl, t.created_by, t.icon, t.user_acl, t.group_acl, t.display_name, t.allow_user_cancel_workspace_jobs, t.allow_user_autostart, t.allow_user_autostop, t.failure_ttl, t.time_til_dormant, t.time_til_dormant_autodelete, t.autostop_requirement_days_of_week, t.autostop_requirement_weeks, t.autostart_block_days_of_week, t.require_active_version, t.deprecated, t.activity_bump, t.max_port_sharing_level, t.use_classic_parameter_flow, t.created_by_avatar_url, t.created_by_username, t.created_by_name, t.organization_name, t.organization_display_name, t.organization_icon
+	t.id, t.created_at, t.updated_at, t.organization_id, t.deleted, t.name, t.provisioner, t.active_version_id, t.description, t.default_ttl, t.created_by, t.icon, t.user_acl, t.group_acl, t.display_name, t.allow_user_cancel_workspace_jobs, t.allow_user_autostart, t.allow_user_autostop, t.failure_ttl, t.time_til_dormant, t.time_til_dormant_autodelete, t.autostop_requirement_days_of_week, t.autostop_requirement_weeks, t.autostart_block_days_of_week, t.require_active_version, t.deprecated, t.activity_bump, t.max_port_sharing_level, t.use_classic_parameter_flow, t.max_concurrent_jobs_per_user, t.resource_ceilings, t.required_provisioner_tags, t.created_by_avatar_url, t.created_by_username, t.created_by_name, t.organization_name, t.organization_display_name, t.organization_icon, t.shared_organization_ids
 FROM
 	template_with_names AS t
 LEFT JOIN
@@ -10817,12 +14170,16 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.MaxConcurrentJobsPerUser,
+			&i.ResourceCeilings,
+			&i.RequiredProvisionerTags,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
 			&i.OrganizationName,
 			&i.OrganizationDisplayName,
 			&i.OrganizationIcon,
+			pq.Array(&i.SharedOrganizationIDs),
 		); err != nil {
 			return nil, err
 		}
@@ -10899,29 +14256,81 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 	return err
 }
 
-const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
-UPDATE
+const getTemplateByIDForUpdate = `-- name: GetTemplateByIDForUpdate :one
+SELECT
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, max_concurrent_jobs_per_user, resource_ceilings, required_provisioner_tags
+FROM
 	templates
-SET
-	group_acl = $1,
-	user_acl = $2
 WHERE
-	id = $3
+	id = $1
+FOR UPDATE
 `
 
-type UpdateTemplateACLByIDParams struct {
-	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
-	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
-	ID       uuid.UUID   `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
-	return err
-}
-
-const updateTemplateAccessControlByID = `-- name: UpdateTemplateAccessControlByID :exec
-UPDATE
+// Locks the template until the end of the transaction, so that concurrent
+// read-modify-write updates of its ACL don't overwrite each other.
+func (q *sqlQuerier) GetTemplateByIDForUpdate(ctx context.Context, id uuid.UUID) (TemplateTable, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByIDForUpdate, id)
+	var i TemplateTable
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
+		&i.Name,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.DefaultTTL,
+		&i.CreatedBy,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.DisplayName,
+		&i.AllowUserCancelWorkspaceJobs,
+		&i.AllowUserAutostart,
+		&i.AllowUserAutostop,
+		&i.FailureTTL,
+		&i.TimeTilDormant,
+		&i.TimeTilDormantAutoDelete,
+		&i.AutostopRequirementDaysOfWeek,
+		&i.AutostopRequirementWeeks,
+		&i.AutostartBlockDaysOfWeek,
+		&i.RequireActiveVersion,
+		&i.Deprecated,
+		&i.ActivityBump,
+		&i.MaxPortSharingLevel,
+		&i.UseClassicParameterFlow,
+		&i.MaxConcurrentJobsPerUser,
+		&i.ResourceCeilings,
+		&i.RequiredProvisionerTags,
+	)
+	return i, err
+}
+
+const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
+UPDATE
+	templates
+SET
+	group_acl = $1,
+	user_acl = $2
+WHERE
+	id = $3
+`
+
+type UpdateTemplateACLByIDParams struct {
+	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
+	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
+	ID       uuid.UUID   `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
+	return err
+}
+
+const updateTemplateAccessControlByID = `-- name: UpdateTemplateAccessControlByID :exec
+UPDATE
 	templates
 SET
 	require_active_version = $2,
@@ -10995,22 +14404,28 @@ SET
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
 	max_port_sharing_level = $9,
-	use_classic_parameter_flow = $10
+	use_classic_parameter_flow = $10,
+	max_concurrent_jobs_per_user = $11,
+	resource_ceilings = $12,
+	required_provisioner_tags = $13
 WHERE
 	id = $1
 `
 
 type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID       `db:"id" json:"id"`
-	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
-	Description                  string          `db:"description" json:"description"`
-	Name                         string          `db:"name" json:"name"`
-	Icon                         string          `db:"icon" json:"icon"`
-	DisplayName                  string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
-	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
-	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	ID                           uuid.UUID                `db:"id" json:"id"`
+	UpdatedAt                    time.Time                `db:"updated_at" json:"updated_at"`
+	Description                  string                   `db:"description" json:"description"`
+	Name                         string                   `db:"name" json:"name"`
+	Icon                         string                   `db:"icon" json:"icon"`
+	DisplayName                  string                   `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool                     `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	GroupACL                     TemplateACL              `db:"group_acl" json:"group_acl"`
+	MaxPortSharingLevel          AppSharingLevel          `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	UseClassicParameterFlow      bool                     `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	MaxConcurrentJobsPerUser     int32                    `db:"max_concurrent_jobs_per_user" json:"max_concurrent_jobs_per_user"`
+	ResourceCeilings             TemplateResourceCeilings `db:"resource_ceilings" json:"resource_ceilings"`
+	RequiredProvisionerTags      StringMap                `db:"required_provisioner_tags" json:"required_provisioner_tags"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -11025,6 +14440,9 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.GroupACL,
 		arg.MaxPortSharingLevel,
 		arg.UseClassicParameterFlow,
+		arg.MaxConcurrentJobsPerUser,
+		arg.ResourceCeilings,
+		arg.RequiredProvisionerTags,
 	)
 	return err
 }
@@ -11081,6 +14499,182 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 	return err
 }
 
+const deleteTemplateShare = `-- name: DeleteTemplateShare :exec
+DELETE FROM
+	template_shares
+WHERE
+	template_id = $1
+	AND organization_id = $2
+`
+
+type DeleteTemplateShareParams struct {
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) DeleteTemplateShare(ctx context.Context, arg DeleteTemplateShareParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateShare, arg.TemplateID, arg.OrganizationID)
+	return err
+}
+
+const getTemplateSharesByTemplateID = `-- name: GetTemplateSharesByTemplateID :many
+SELECT
+	template_id, organization_id, created_by, created_at
+FROM
+	template_shares
+WHERE
+	template_id = $1
+ORDER BY
+	created_at, organization_id
+`
+
+func (q *sqlQuerier) GetTemplateSharesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateShare, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateSharesByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateShare
+	for rows.Next() {
+		var i TemplateShare
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplatesSharedWithOrganization = `-- name: GetTemplatesSharedWithOrganization :many
+SELECT
+	templates.id, templates.created_at, templates.updated_at, templates.organization_id, templates.deleted, templates.name, templates.provisioner, templates.active_version_id, templates.description, templates.default_ttl, templates.created_by, templates.icon, templates.user_acl, templates.group_acl, templates.display_name, templates.allow_user_cancel_workspace_jobs, templates.allow_user_autostart, templates.allow_user_autostop, templates.failure_ttl, templates.time_til_dormant, templates.time_til_dormant_autodelete, templates.autostop_requirement_days_of_week, templates.autostop_requirement_weeks, templates.autostart_block_days_of_week, templates.require_active_version, templates.deprecated, templates.activity_bump, templates.max_port_sharing_level, templates.use_classic_parameter_flow, templates.max_concurrent_jobs_per_user, templates.resource_ceilings, templates.required_provisioner_tags, templates.created_by_avatar_url, templates.created_by_username, templates.created_by_name, templates.organization_name, templates.organization_display_name, templates.organization_icon, templates.shared_organization_ids
+FROM
+	template_with_names AS templates
+	INNER JOIN template_shares ON template_shares.template_id = templates.id
+WHERE
+	template_shares.organization_id = $1
+	AND templates.deleted = false
+ORDER BY
+	templates.name, templates.id
+`
+
+// Returns the templates that are shared into the organization, excluding
+// deleted templates.
+func (q *sqlQuerier) GetTemplatesSharedWithOrganization(ctx context.Context, organizationID uuid.UUID) ([]Template, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplatesSharedWithOrganization, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Template
+	for rows.Next() {
+		var i Template
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrganizationID,
+			&i.Deleted,
+			&i.Name,
+			&i.Provisioner,
+			&i.ActiveVersionID,
+			&i.Description,
+			&i.DefaultTTL,
+			&i.CreatedBy,
+			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.DisplayName,
+			&i.AllowUserCancelWorkspaceJobs,
+			&i.AllowUserAutostart,
+			&i.AllowUserAutostop,
+			&i.FailureTTL,
+			&i.TimeTilDormant,
+			&i.TimeTilDormantAutoDelete,
+			&i.AutostopRequirementDaysOfWeek,
+			&i.AutostopRequirementWeeks,
+			&i.AutostartBlockDaysOfWeek,
+			&i.RequireActiveVersion,
+			&i.Deprecated,
+			&i.ActivityBump,
+			&i.MaxPortSharingLevel,
+			&i.UseClassicParameterFlow,
+			&i.MaxConcurrentJobsPerUser,
+			&i.ResourceCeilings,
+			&i.RequiredProvisionerTags,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+			&i.CreatedByName,
+			&i.OrganizationName,
+			&i.OrganizationDisplayName,
+			&i.OrganizationIcon,
+			pq.Array(&i.SharedOrganizationIDs),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateShare = `-- name: UpsertTemplateShare :one
+INSERT INTO
+	template_shares (
+		template_id,
+		organization_id,
+		created_by,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (template_id, organization_id) DO UPDATE SET
+	created_at = template_shares.created_at
+RETURNING template_id, organization_id, created_by, created_at
+`
+
+type UpsertTemplateShareParams struct {
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	CreatedBy      uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// Sharing a template into an organization it is already shared into keeps the
+// original share.
+func (q *sqlQuerier) UpsertTemplateShare(ctx context.Context, arg UpsertTemplateShareParams) (TemplateShare, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateShare,
+		arg.TemplateID,
+		arg.OrganizationID,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i TemplateShare
+	err := row.Scan(
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
 SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
 `
@@ -11238,55 +14832,235 @@ func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg Ins
 	return i, err
 }
 
-const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
-UPDATE
-	template_versions
-SET
-	archived = true,
-	updated_at = $1
+const deleteTemplateVersionRolloutByTemplateID = `-- name: DeleteTemplateVersionRolloutByTemplateID :exec
+DELETE FROM
+	template_version_rollouts
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) DeleteTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateVersionRolloutByTemplateID, templateID)
+	return err
+}
+
+const getTemplateVersionRolloutBuildCounts = `-- name: GetTemplateVersionRolloutBuildCounts :many
+SELECT
+	workspace_builds.template_version_id,
+	COUNT(*) AS total_builds,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status = 'failed'::provisioner_job_status) AS failed_builds
 FROM
-	-- Archive all versions that are returned from this query.
-	(
-		SELECT
-			scoped_template_versions.id
-		FROM
-			-- Scope an archive to a single template and ignore already archived template versions
-			(
-				SELECT
-					id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task
-				FROM
-					template_versions
-				WHERE
-					template_versions.template_id = $2 :: uuid
-					AND
-					archived = false
-					AND
-					-- This allows archiving a specific template version.
-					CASE
-						WHEN $3::uuid  != '00000000-0000-0000-0000-000000000000'::uuid THEN
-							template_versions.id = $3 :: uuid
-						ELSE
-							true
-						END
-			) AS scoped_template_versions
-			LEFT JOIN
-				provisioner_jobs ON scoped_template_versions.job_id = provisioner_jobs.id
-			LEFT JOIN
-				templates ON scoped_template_versions.template_id = templates.id
-		WHERE
-		  -- Actively used template versions (meaning the latest build is using
-		  -- the version) are never archived. A "restart" command on the workspace,
-		  -- even if failed, would use the version. So it cannot be archived until
-		  -- the build is outdated.
-		NOT EXISTS (
-			-- Return all "used" versions, where "used" is defined as being
-			-- used by a latest workspace build.
-			SELECT template_version_id FROM (
-				SELECT
-					DISTINCT ON (workspace_id) template_version_id, transition
-				FROM
-					workspace_builds
-				ORDER BY workspace_id, build_number DESC
+	workspace_builds
+	INNER JOIN workspaces ON workspaces.id = workspace_builds.workspace_id
+	INNER JOIN provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspaces.template_id = $1
+	AND workspace_builds.created_at >= $2
+	AND provisioner_jobs.job_status IN ('succeeded'::provisioner_job_status, 'failed'::provisioner_job_status)
+GROUP BY
+	workspace_builds.template_version_id
+`
+
+type GetTemplateVersionRolloutBuildCountsParams struct {
+	TemplateID   uuid.UUID `db:"template_id" json:"template_id"`
+	CreatedAfter time.Time `db:"created_after" json:"created_after"`
+}
+
+type GetTemplateVersionRolloutBuildCountsRow struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	TotalBuilds       int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds      int64     `db:"failed_builds" json:"failed_builds"`
+}
+
+// Returns the number of completed and failed builds of each version of the
+// template that were created since the given time.
+func (q *sqlQuerier) GetTemplateVersionRolloutBuildCounts(ctx context.Context, arg GetTemplateVersionRolloutBuildCountsParams) ([]GetTemplateVersionRolloutBuildCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionRolloutBuildCounts, arg.TemplateID, arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateVersionRolloutBuildCountsRow
+	for rows.Next() {
+		var i GetTemplateVersionRolloutBuildCountsRow
+		if err := rows.Scan(&i.TemplateVersionID, &i.TotalBuilds, &i.FailedBuilds); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateVersionRolloutByTemplateID = `-- name: GetTemplateVersionRolloutByTemplateID :one
+SELECT
+	template_id, template_version_id, percent, group_id, created_by, created_at, updated_at
+FROM
+	template_version_rollouts
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionRolloutByTemplateID, templateID)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.Percent,
+		&i.GroupID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const isTemplateVersionRolloutCohortMember = `-- name: IsTemplateVersionRolloutCohortMember :one
+SELECT EXISTS (
+	SELECT
+		1
+	FROM
+		template_version_rollouts
+		INNER JOIN group_members_expanded ON group_members_expanded.group_id = template_version_rollouts.group_id
+	WHERE
+		template_version_rollouts.template_id = $1
+		AND group_members_expanded.user_id = $2
+)
+`
+
+type IsTemplateVersionRolloutCohortMemberParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Returns whether the user is a member of the group of the rollout of the
+// template.
+func (q *sqlQuerier) IsTemplateVersionRolloutCohortMember(ctx context.Context, arg IsTemplateVersionRolloutCohortMemberParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isTemplateVersionRolloutCohortMember, arg.TemplateID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const upsertTemplateVersionRollout = `-- name: UpsertTemplateVersionRollout :one
+INSERT INTO
+	template_version_rollouts (
+		template_id,
+		template_version_id,
+		percent,
+		group_id,
+		created_by,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (template_id) DO UPDATE SET
+	created_by = CASE
+		WHEN template_version_rollouts.template_version_id = EXCLUDED.template_version_id THEN template_version_rollouts.created_by
+		ELSE EXCLUDED.created_by
+	END,
+	created_at = CASE
+		WHEN template_version_rollouts.template_version_id = EXCLUDED.template_version_id THEN template_version_rollouts.created_at
+		ELSE EXCLUDED.created_at
+	END,
+	template_version_id = EXCLUDED.template_version_id,
+	percent = EXCLUDED.percent,
+	group_id = EXCLUDED.group_id,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, template_version_id, percent, group_id, created_by, created_at, updated_at
+`
+
+type UpsertTemplateVersionRolloutParams struct {
+	TemplateID        uuid.UUID     `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID     `db:"template_version_id" json:"template_version_id"`
+	Percent           int32         `db:"percent" json:"percent"`
+	GroupID           uuid.NullUUID `db:"group_id" json:"group_id"`
+	CreatedBy         uuid.UUID     `db:"created_by" json:"created_by"`
+	CreatedAt         time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// Starts or updates the rollout of a template. Switching to another version
+// starts a new rollout, so its creator and start time are reset.
+func (q *sqlQuerier) UpsertTemplateVersionRollout(ctx context.Context, arg UpsertTemplateVersionRolloutParams) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateVersionRollout,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.Percent,
+		arg.GroupID,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.Percent,
+		&i.GroupID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
+UPDATE
+	template_versions
+SET
+	archived = true,
+	updated_at = $1
+FROM
+	-- Archive all versions that are returned from this query.
+	(
+		SELECT
+			scoped_template_versions.id
+		FROM
+			-- Scope an archive to a single template and ignore already archived template versions
+			(
+				SELECT
+					id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task
+				FROM
+					template_versions
+				WHERE
+					template_versions.template_id = $2 :: uuid
+					AND
+					archived = false
+					AND
+					-- This allows archiving a specific template version.
+					CASE
+						WHEN $3::uuid  != '00000000-0000-0000-0000-000000000000'::uuid THEN
+							template_versions.id = $3 :: uuid
+						ELSE
+							true
+						END
+			) AS scoped_template_versions
+			LEFT JOIN
+				provisioner_jobs ON scoped_template_versions.job_id = provisioner_jobs.id
+			LEFT JOIN
+				templates ON scoped_template_versions.template_id = templates.id
+		WHERE
+		  -- Actively used template versions (meaning the latest build is using
+		  -- the version) are never archived. A "restart" command on the workspace,
+		  -- even if failed, would use the version. So it cannot be archived until
+		  -- the build is outdated.
+		NOT EXISTS (
+			-- Return all "used" versions, where "used" is defined as being
+			-- used by a latest workspace build.
+			SELECT template_version_id FROM (
+				SELECT
+					DISTINCT ON (workspace_id) template_version_id, transition
+				FROM
+					workspace_builds
+				ORDER BY workspace_id, build_number DESC
 				) AS used_versions
 			WHERE
 				used_versions.transition != 'delete'
@@ -11937,8 +15711,49 @@ func (q *sqlQuerier) InsertTemplateVersionTerraformValuesByJobID(ctx context.Con
 	return err
 }
 
+const getSensitiveTemplateVersionVariables = `-- name: GetSensitiveTemplateVersionVariables :many
+-- Returns the variables whose values are encrypted at rest when database
+-- encryption is enabled. Used to re-encrypt them when rotating keys.
+SELECT template_version_id, name, description, type, value, default_value, required, sensitive, value_key_id FROM template_version_variables WHERE sensitive = true
+`
+
+// Returns the variables whose values are encrypted at rest when database
+// encryption is enabled. Used to re-encrypt them when rotating keys.
+func (q *sqlQuerier) GetSensitiveTemplateVersionVariables(ctx context.Context) ([]TemplateVersionVariable, error) {
+	rows, err := q.db.QueryContext(ctx, getSensitiveTemplateVersionVariables)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionVariable
+	for rows.Next() {
+		var i TemplateVersionVariable
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Value,
+			&i.DefaultValue,
+			&i.Required,
+			&i.Sensitive,
+			&i.ValueKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
-SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
+SELECT template_version_id, name, description, type, value, default_value, required, sensitive, value_key_id FROM template_version_variables WHERE template_version_id = $1
 `
 
 func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
@@ -11959,6 +15774,7 @@ func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVe
 			&i.DefaultValue,
 			&i.Required,
 			&i.Sensitive,
+			&i.ValueKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -11983,7 +15799,8 @@ INSERT INTO
         value,
         default_value,
         required,
-        sensitive
+        sensitive,
+        value_key_id
     )
 VALUES
     (
@@ -11994,19 +15811,21 @@ VALUES
         $5,
         $6,
         $7,
-        $8
-    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
+        $8,
+        $9
+    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive, value_key_id
 `
 
 type InsertTemplateVersionVariableParams struct {
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
-	Name              string    `db:"name" json:"name"`
-	Description       string    `db:"description" json:"description"`
-	Type              string    `db:"type" json:"type"`
-	Value             string    `db:"value" json:"value"`
-	DefaultValue      string    `db:"default_value" json:"default_value"`
-	Required          bool      `db:"required" json:"required"`
-	Sensitive         bool      `db:"sensitive" json:"sensitive"`
+	TemplateVersionID uuid.UUID      `db:"template_version_id" json:"template_version_id"`
+	Name              string         `db:"name" json:"name"`
+	Description       string         `db:"description" json:"description"`
+	Type              string         `db:"type" json:"type"`
+	Value             string         `db:"value" json:"value"`
+	DefaultValue      string         `db:"default_value" json:"default_value"`
+	Required          bool           `db:"required" json:"required"`
+	Sensitive         bool           `db:"sensitive" json:"sensitive"`
+	ValueKeyID        sql.NullString `db:"value_key_id" json:"value_key_id"`
 }
 
 func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
@@ -12019,6 +15838,7 @@ func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg Inse
 		arg.DefaultValue,
 		arg.Required,
 		arg.Sensitive,
+		arg.ValueKeyID,
 	)
 	var i TemplateVersionVariable
 	err := row.Scan(
@@ -12030,10 +15850,39 @@ func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg Inse
 		&i.DefaultValue,
 		&i.Required,
 		&i.Sensitive,
+		&i.ValueKeyID,
 	)
 	return i, err
 }
 
+const updateTemplateVersionVariableValue = `-- name: UpdateTemplateVersionVariableValue :exec
+UPDATE
+	template_version_variables
+SET
+	value = $3,
+	value_key_id = $4
+WHERE
+	template_version_id = $1
+	AND name = $2
+`
+
+type UpdateTemplateVersionVariableValueParams struct {
+	TemplateVersionID uuid.UUID      `db:"template_version_id" json:"template_version_id"`
+	Name              string         `db:"name" json:"name"`
+	Value             string         `db:"value" json:"value"`
+	ValueKeyID        sql.NullString `db:"value_key_id" json:"value_key_id"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionVariableValue(ctx context.Context, arg UpdateTemplateVersionVariableValueParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionVariableValue,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Value,
+		arg.ValueKeyID,
+	)
+	return err
+}
+
 const getTemplateVersionWorkspaceTags = `-- name: GetTemplateVersionWorkspaceTags :many
 SELECT template_version_id, key, value FROM template_version_workspace_tags WHERE template_version_id = $1 ORDER BY LOWER(key) ASC
 `
@@ -12116,6 +15965,57 @@ func (q *sqlQuerier) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	return err
 }
 
+const getOIDCUserClaims = `-- name: GetOIDCUserClaims :many
+SELECT
+	users.id AS user_id,
+	users.username,
+	user_links.claims
+FROM
+	user_links
+INNER JOIN
+	users ON users.id = user_links.user_id
+WHERE
+	user_links.login_type = 'oidc'
+	AND users.deleted = false
+	AND CASE WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
+		user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $1)
+		ELSE true
+	END
+ORDER BY
+	users.username ASC
+`
+
+type GetOIDCUserClaimsRow struct {
+	UserID   uuid.UUID      `db:"user_id" json:"user_id"`
+	Username string         `db:"username" json:"username"`
+	Claims   UserLinkClaims `db:"claims" json:"claims"`
+}
+
+// GetOIDCUserClaims returns the claims OIDC users last logged in with. This
+// query is used to preview idp sync settings before they are applied.
+func (q *sqlQuerier) GetOIDCUserClaims(ctx context.Context, organizationID uuid.UUID) ([]GetOIDCUserClaimsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOIDCUserClaims, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOIDCUserClaimsRow
+	for rows.Next() {
+		var i GetOIDCUserClaimsRow
+		if err := rows.Scan(&i.UserID, &i.Username, &i.Claims); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
 SELECT
 	user_links.user_id, user_links.login_type, user_links.linked_id, user_links.oauth_access_token, user_links.oauth_refresh_token, user_links.oauth_expiry, user_links.oauth_access_token_key_id, user_links.oauth_refresh_token_key_id, user_links.claims
@@ -12486,37 +16386,201 @@ func (q *sqlQuerier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid
 	return items, nil
 }
 
-const getActiveUserCount = `-- name: GetActiveUserCount :one
-SELECT
-	COUNT(*)
-FROM
-	users
-WHERE
-	status = 'active'::user_status AND deleted = false
-	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+const getPendingUserActivityExports = `-- name: GetPendingUserActivityExports :many
+SELECT id, created_by, created_at, updated_at, start_time, end_time, format, status, file_id, error, completed_at FROM user_activity_exports WHERE status = 'pending' ORDER BY created_at ASC
 `
 
-func (q *sqlQuerier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getActiveUserCount, includeSystem)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
-const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
-SELECT
-	-- username and email are returned just to help for logging purposes
-	-- status is used to enforce 'suspended' users, as all roles are ignored
-	--	when suspended.
-	id, username, status, email,
-	-- All user roles, including their org roles.
-	array_cat(
-		-- All users are members
-		array_append(users.rbac_roles, 'member'),
-		(
-			SELECT
-				-- The roles are returned as a flat array, org scoped and site side.
-				-- Concatenating the organization id scopes the organization roles.
+// Returns the exports that still have to be generated, oldest first.
+func (q *sqlQuerier) GetPendingUserActivityExports(ctx context.Context) ([]UserActivityExport, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingUserActivityExports)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserActivityExport
+	for rows.Next() {
+		var i UserActivityExport
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Format,
+			&i.Status,
+			&i.FileID,
+			&i.Error,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserActivityExportByID = `-- name: GetUserActivityExportByID :one
+SELECT id, created_by, created_at, updated_at, start_time, end_time, format, status, file_id, error, completed_at FROM user_activity_exports WHERE id = $1
+`
+
+func (q *sqlQuerier) GetUserActivityExportByID(ctx context.Context, id uuid.UUID) (UserActivityExport, error) {
+	row := q.db.QueryRowContext(ctx, getUserActivityExportByID, id)
+	var i UserActivityExport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Format,
+		&i.Status,
+		&i.FileID,
+		&i.Error,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const insertUserActivityExport = `-- name: InsertUserActivityExport :one
+INSERT INTO user_activity_exports (
+	id,
+	created_by,
+	created_at,
+	updated_at,
+	start_time,
+	end_time,
+	format
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_by, created_at, updated_at, start_time, end_time, format, status, file_id, error, completed_at
+`
+
+type InsertUserActivityExportParams struct {
+	ID        uuid.UUID                `db:"id" json:"id"`
+	CreatedBy uuid.UUID                `db:"created_by" json:"created_by"`
+	CreatedAt time.Time                `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time                `db:"updated_at" json:"updated_at"`
+	StartTime time.Time                `db:"start_time" json:"start_time"`
+	EndTime   time.Time                `db:"end_time" json:"end_time"`
+	Format    UserActivityExportFormat `db:"format" json:"format"`
+}
+
+func (q *sqlQuerier) InsertUserActivityExport(ctx context.Context, arg InsertUserActivityExportParams) (UserActivityExport, error) {
+	row := q.db.QueryRowContext(ctx, insertUserActivityExport,
+		arg.ID,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.StartTime,
+		arg.EndTime,
+		arg.Format,
+	)
+	var i UserActivityExport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Format,
+		&i.Status,
+		&i.FileID,
+		&i.Error,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const updateUserActivityExportStatusByID = `-- name: UpdateUserActivityExportStatusByID :one
+UPDATE
+	user_activity_exports
+SET
+	status = $1,
+	file_id = $2,
+	error = $3,
+	updated_at = $4,
+	completed_at = $5
+WHERE
+	id = $6
+RETURNING id, created_by, created_at, updated_at, start_time, end_time, format, status, file_id, error, completed_at
+`
+
+type UpdateUserActivityExportStatusByIDParams struct {
+	Status      UserActivityExportStatus `db:"status" json:"status"`
+	FileID      uuid.NullUUID            `db:"file_id" json:"file_id"`
+	Error       string                   `db:"error" json:"error"`
+	UpdatedAt   time.Time                `db:"updated_at" json:"updated_at"`
+	CompletedAt sql.NullTime             `db:"completed_at" json:"completed_at"`
+	ID          uuid.UUID                `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateUserActivityExportStatusByID(ctx context.Context, arg UpdateUserActivityExportStatusByIDParams) (UserActivityExport, error) {
+	row := q.db.QueryRowContext(ctx, updateUserActivityExportStatusByID,
+		arg.Status,
+		arg.FileID,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.CompletedAt,
+		arg.ID,
+	)
+	var i UserActivityExport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Format,
+		&i.Status,
+		&i.FileID,
+		&i.Error,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getActiveUserCount = `-- name: GetActiveUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false
+	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+`
+
+func (q *sqlQuerier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getActiveUserCount, includeSystem)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
+SELECT
+	-- username and email are returned just to help for logging purposes
+	-- status is used to enforce 'suspended' users, as all roles are ignored
+	--	when suspended.
+	id, username, status, email,
+	-- All user roles, including their org roles.
+	array_cat(
+		-- All users are members
+		array_append(users.rbac_roles, 'member'),
+		(
+			SELECT
+				-- The roles are returned as a flat array, org scoped and site side.
+				-- Concatenating the organization id scopes the organization roles.
 				array_agg(org_roles || ':' || organization_members.organization_id::text)
 			FROM
 				organization_members,
@@ -13473,6 +17537,163 @@ func (q *sqlQuerier) UpdateUserThemePreference(ctx context.Context, arg UpdateUs
 	return i, err
 }
 
+const deleteOldWorkspaceAgentConnectionQuality = `-- name: DeleteOldWorkspaceAgentConnectionQuality :exec
+DELETE FROM
+	workspace_agent_connection_quality
+WHERE
+	updated_at < $1::timestamptz
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentConnectionQuality(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentConnectionQuality, beforeTime)
+	return err
+}
+
+const getWorkspaceAgentConnectionQualityByAgentID = `-- name: GetWorkspaceAgentConnectionQualityByAgentID :many
+SELECT
+	agent_id, ip, p2p, derp_region_code, latency_ms, pings_sent, pings_lost, last_handshake_at, created_at, updated_at
+FROM
+	workspace_agent_connection_quality
+WHERE
+	agent_id = $1
+ORDER BY
+	updated_at DESC, ip
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentConnectionQualityByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentConnectionQuality, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentConnectionQualityByAgentID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentConnectionQuality
+	for rows.Next() {
+		var i WorkspaceAgentConnectionQuality
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Ip,
+			&i.P2P,
+			&i.DerpRegionCode,
+			&i.LatencyMS,
+			&i.PingsSent,
+			&i.PingsLost,
+			&i.LastHandshakeAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentConnectionTypeCounts = `-- name: GetWorkspaceAgentConnectionTypeCounts :many
+SELECT
+	p2p,
+	derp_region_code,
+	COUNT(*) AS count
+FROM
+	workspace_agent_connection_quality
+WHERE
+	updated_at > $1
+GROUP BY
+	p2p, derp_region_code
+ORDER BY
+	p2p DESC, derp_region_code
+`
+
+type GetWorkspaceAgentConnectionTypeCountsRow struct {
+	P2P            bool   `db:"p2p" json:"p2p"`
+	DerpRegionCode string `db:"derp_region_code" json:"derp_region_code"`
+	Count          int64  `db:"count" json:"count"`
+}
+
+// Counts the connections to workspace agents reported after @updated_after by
+// whether they are direct or relayed, and the DERP region they are relayed
+// through.
+func (q *sqlQuerier) GetWorkspaceAgentConnectionTypeCounts(ctx context.Context, updatedAfter time.Time) ([]GetWorkspaceAgentConnectionTypeCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentConnectionTypeCounts, updatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentConnectionTypeCountsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentConnectionTypeCountsRow
+		if err := rows.Scan(&i.P2P, &i.DerpRegionCode, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceAgentConnectionQuality = `-- name: UpsertWorkspaceAgentConnectionQuality :exec
+INSERT INTO
+	workspace_agent_connection_quality (
+		agent_id,
+		ip,
+		p2p,
+		derp_region_code,
+		latency_ms,
+		pings_sent,
+		pings_lost,
+		last_handshake_at,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+ON CONFLICT (agent_id, ip) DO UPDATE SET
+	p2p = EXCLUDED.p2p,
+	derp_region_code = EXCLUDED.derp_region_code,
+	latency_ms = EXCLUDED.latency_ms,
+	pings_sent = EXCLUDED.pings_sent,
+	pings_lost = EXCLUDED.pings_lost,
+	last_handshake_at = EXCLUDED.last_handshake_at,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertWorkspaceAgentConnectionQualityParams struct {
+	AgentID         uuid.UUID       `db:"agent_id" json:"agent_id"`
+	Ip              string          `db:"ip" json:"ip"`
+	P2P             bool            `db:"p2p" json:"p2p"`
+	DerpRegionCode  string          `db:"derp_region_code" json:"derp_region_code"`
+	LatencyMS       sql.NullFloat64 `db:"latency_ms" json:"latency_ms"`
+	PingsSent       int64           `db:"pings_sent" json:"pings_sent"`
+	PingsLost       int64           `db:"pings_lost" json:"pings_lost"`
+	LastHandshakeAt sql.NullTime    `db:"last_handshake_at" json:"last_handshake_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentConnectionQuality(ctx context.Context, arg UpsertWorkspaceAgentConnectionQualityParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceAgentConnectionQuality,
+		arg.AgentID,
+		arg.Ip,
+		arg.P2P,
+		arg.DerpRegionCode,
+		arg.LatencyMS,
+		arg.PingsSent,
+		arg.PingsLost,
+		arg.LastHandshakeAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const getWorkspaceAgentDevcontainersByAgentID = `-- name: GetWorkspaceAgentDevcontainersByAgentID :many
 SELECT
 	id, workspace_agent_id, created_at, workspace_folder, config_path, name
@@ -13573,60 +17794,204 @@ func (q *sqlQuerier) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg
 	return items, nil
 }
 
-const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
-DELETE FROM
-	workspace_agent_port_share
-WHERE
-	workspace_id = $1
-	AND agent_name = $2
-	AND port = $3
-`
-
-type DeleteWorkspaceAgentPortShareParams struct {
-	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	AgentName   string    `db:"agent_name" json:"agent_name"`
-	Port        int32     `db:"port" json:"port"`
-}
-
-func (q *sqlQuerier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error {
-	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
-	return err
-}
-
-const deleteWorkspaceAgentPortSharesByTemplate = `-- name: DeleteWorkspaceAgentPortSharesByTemplate :exec
+const deleteOldWorkspaceAgentHealthEvents = `-- name: DeleteOldWorkspaceAgentHealthEvents :exec
 DELETE FROM
-	workspace_agent_port_share
+	workspace_agent_health_events
 WHERE
-	workspace_id IN (
-		SELECT
-			id
-		FROM
-			workspaces
-		WHERE
-			template_id = $1
-	)
+	COALESCE(resolved_at, created_at) < $1::timestamptz
 `
 
-func (q *sqlQuerier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortSharesByTemplate, templateID)
+// Events that are still ongoing are deleted as well, as the agents that
+// reported them are usually long gone.
+func (q *sqlQuerier) DeleteOldWorkspaceAgentHealthEvents(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentHealthEvents, beforeTime)
 	return err
 }
 
-const getWorkspaceAgentPortShare = `-- name: GetWorkspaceAgentPortShare :one
+const getUnresolvedWorkspaceAgentHealthEventsByAgentIDs = `-- name: GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs :many
 SELECT
-	workspace_id, agent_name, port, share_level, protocol
+	id, agent_id, kind, volume, used, total, created_at, resolved_at
 FROM
-	workspace_agent_port_share
+	workspace_agent_health_events
 WHERE
-	workspace_id = $1
-	AND agent_name = $2
-	AND port = $3
+	agent_id = ANY($1 :: uuid[])
+	AND resolved_at IS NULL
+ORDER BY
+	created_at, kind, volume
 `
 
-type GetWorkspaceAgentPortShareParams struct {
-	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	AgentName   string    `db:"agent_name" json:"agent_name"`
-	Port        int32     `db:"port" json:"port"`
+func (q *sqlQuerier) GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentHealthEvent, error) {
+	rows, err := q.db.QueryContext(ctx, getUnresolvedWorkspaceAgentHealthEventsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentHealthEvent
+	for rows.Next() {
+		var i WorkspaceAgentHealthEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.AgentID,
+			&i.Kind,
+			&i.Volume,
+			&i.Used,
+			&i.Total,
+			&i.CreatedAt,
+			&i.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentHealthEvent = `-- name: InsertWorkspaceAgentHealthEvent :one
+INSERT INTO
+	workspace_agent_health_events (
+		id,
+		agent_id,
+		kind,
+		volume,
+		used,
+		total,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (agent_id, kind, volume) WHERE resolved_at IS NULL DO NOTHING
+RETURNING id, agent_id, kind, volume, used, total, created_at, resolved_at
+`
+
+type InsertWorkspaceAgentHealthEventParams struct {
+	ID        uuid.UUID                     `db:"id" json:"id"`
+	AgentID   uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Kind      WorkspaceAgentHealthEventKind `db:"kind" json:"kind"`
+	Volume    string                        `db:"volume" json:"volume"`
+	Used      int64                         `db:"used" json:"used"`
+	Total     int64                         `db:"total" json:"total"`
+	CreatedAt time.Time                     `db:"created_at" json:"created_at"`
+}
+
+// Records a condition detected by an agent. If the agent already has an
+// ongoing event of the same kind for the volume, nothing is inserted and no
+// rows are returned.
+func (q *sqlQuerier) InsertWorkspaceAgentHealthEvent(ctx context.Context, arg InsertWorkspaceAgentHealthEventParams) (WorkspaceAgentHealthEvent, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentHealthEvent,
+		arg.ID,
+		arg.AgentID,
+		arg.Kind,
+		arg.Volume,
+		arg.Used,
+		arg.Total,
+		arg.CreatedAt,
+	)
+	var i WorkspaceAgentHealthEvent
+	err := row.Scan(
+		&i.ID,
+		&i.AgentID,
+		&i.Kind,
+		&i.Volume,
+		&i.Used,
+		&i.Total,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const resolveWorkspaceAgentHealthEvent = `-- name: ResolveWorkspaceAgentHealthEvent :execrows
+UPDATE
+	workspace_agent_health_events
+SET
+	resolved_at = $1
+WHERE
+	agent_id = $2
+	AND kind = $3
+	AND volume = $4
+	AND resolved_at IS NULL
+`
+
+type ResolveWorkspaceAgentHealthEventParams struct {
+	ResolvedAt sql.NullTime                  `db:"resolved_at" json:"resolved_at"`
+	AgentID    uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Kind       WorkspaceAgentHealthEventKind `db:"kind" json:"kind"`
+	Volume     string                        `db:"volume" json:"volume"`
+}
+
+func (q *sqlQuerier) ResolveWorkspaceAgentHealthEvent(ctx context.Context, arg ResolveWorkspaceAgentHealthEventParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resolveWorkspaceAgentHealthEvent,
+		arg.ResolvedAt,
+		arg.AgentID,
+		arg.Kind,
+		arg.Volume,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
+DELETE FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type DeleteWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+}
+
+func (q *sqlQuerier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	return err
+}
+
+const deleteWorkspaceAgentPortSharesByTemplate = `-- name: DeleteWorkspaceAgentPortSharesByTemplate :exec
+DELETE FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = $1
+	)
+`
+
+func (q *sqlQuerier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortSharesByTemplate, templateID)
+	return err
+}
+
+const getWorkspaceAgentPortShare = `-- name: GetWorkspaceAgentPortShare :one
+SELECT
+	workspace_id, agent_name, port, share_level, protocol, shared_with_user_ids, shared_with_group_ids, expires_at
+FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type GetWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
 }
 
 func (q *sqlQuerier) GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
@@ -13638,13 +18003,16 @@ func (q *sqlQuerier) GetWorkspaceAgentPortShare(ctx context.Context, arg GetWork
 		&i.Port,
 		&i.ShareLevel,
 		&i.Protocol,
+		pq.Array(&i.SharedWithUserIDs),
+		pq.Array(&i.SharedWithGroupIDs),
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
 const listWorkspaceAgentPortShares = `-- name: ListWorkspaceAgentPortShares :many
 SELECT
-	workspace_id, agent_name, port, share_level, protocol
+	workspace_id, agent_name, port, share_level, protocol, shared_with_user_ids, shared_with_group_ids, expires_at
 FROM
 	workspace_agent_port_share
 WHERE
@@ -13666,6 +18034,9 @@ func (q *sqlQuerier) ListWorkspaceAgentPortShares(ctx context.Context, workspace
 			&i.Port,
 			&i.ShareLevel,
 			&i.Protocol,
+			pq.Array(&i.SharedWithUserIDs),
+			pq.Array(&i.SharedWithGroupIDs),
+			&i.ExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -13709,14 +18080,20 @@ INSERT INTO
 		agent_name,
 		port,
 		share_level,
-		protocol
+		protocol,
+		shared_with_user_ids,
+		shared_with_group_ids,
+		expires_at
 	)
 VALUES (
 	$1,
 	$2,
 	$3,
 	$4,
-	$5
+	$5,
+	$6,
+	$7,
+	$8
 )
 ON CONFLICT (
 	workspace_id,
@@ -13725,16 +18102,22 @@ ON CONFLICT (
 )
 DO UPDATE SET
 	share_level = $4,
-	protocol = $5
-RETURNING workspace_id, agent_name, port, share_level, protocol
+	protocol = $5,
+	shared_with_user_ids = $6,
+	shared_with_group_ids = $7,
+	expires_at = $8
+RETURNING workspace_id, agent_name, port, share_level, protocol, shared_with_user_ids, shared_with_group_ids, expires_at
 `
 
 type UpsertWorkspaceAgentPortShareParams struct {
-	WorkspaceID uuid.UUID         `db:"workspace_id" json:"workspace_id"`
-	AgentName   string            `db:"agent_name" json:"agent_name"`
-	Port        int32             `db:"port" json:"port"`
-	ShareLevel  AppSharingLevel   `db:"share_level" json:"share_level"`
-	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
+	WorkspaceID        uuid.UUID         `db:"workspace_id" json:"workspace_id"`
+	AgentName          string            `db:"agent_name" json:"agent_name"`
+	Port               int32             `db:"port" json:"port"`
+	ShareLevel         AppSharingLevel   `db:"share_level" json:"share_level"`
+	Protocol           PortShareProtocol `db:"protocol" json:"protocol"`
+	SharedWithUserIDs  []uuid.UUID       `db:"shared_with_user_ids" json:"shared_with_user_ids"`
+	SharedWithGroupIDs []uuid.UUID       `db:"shared_with_group_ids" json:"shared_with_group_ids"`
+	ExpiresAt          sql.NullTime      `db:"expires_at" json:"expires_at"`
 }
 
 func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
@@ -13744,6 +18127,9 @@ func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg Upse
 		arg.Port,
 		arg.ShareLevel,
 		arg.Protocol,
+		pq.Array(arg.SharedWithUserIDs),
+		pq.Array(arg.SharedWithGroupIDs),
+		arg.ExpiresAt,
 	)
 	var i WorkspaceAgentPortShare
 	err := row.Scan(
@@ -13752,6 +18138,9 @@ func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg Upse
 		&i.Port,
 		&i.ShareLevel,
 		&i.Protocol,
+		pq.Array(&i.SharedWithUserIDs),
+		pq.Array(&i.SharedWithGroupIDs),
+		&i.ExpiresAt,
 	)
 	return i, err
 }
@@ -14058,7 +18447,164 @@ func (q *sqlQuerier) UpdateVolumeResourceMonitor(ctx context.Context, arg Update
 	return err
 }
 
-const deleteOldWorkspaceAgentLogs = `-- name: DeleteOldWorkspaceAgentLogs :exec
+const deleteOldWorkspaceAgentResourceUsage = `-- name: DeleteOldWorkspaceAgentResourceUsage :exec
+DELETE FROM workspace_agent_resource_usage WHERE bucket_start < $1::timestamptz
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentResourceUsage(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentResourceUsage, beforeTime)
+	return err
+}
+
+const getWorkspaceAgentResourceUsageByWorkspaceID = `-- name: GetWorkspaceAgentResourceUsageByWorkspaceID :many
+SELECT
+	workspace_agent_resource_usage.agent_id, workspace_agent_resource_usage.bucket_start, workspace_agent_resource_usage.samples, workspace_agent_resource_usage.cpu_used_avg, workspace_agent_resource_usage.cpu_used_max, workspace_agent_resource_usage.cpu_total, workspace_agent_resource_usage.memory_used_avg, workspace_agent_resource_usage.memory_used_max, workspace_agent_resource_usage.memory_total, workspace_agent_resource_usage.disk_used, workspace_agent_resource_usage.disk_total,
+	workspace_agents.name AS agent_name
+FROM
+	workspace_agent_resource_usage
+INNER JOIN
+	workspace_agents ON workspace_agents.id = workspace_agent_resource_usage.agent_id
+INNER JOIN
+	workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+INNER JOIN
+	workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+WHERE
+	workspace_builds.workspace_id = $1
+	AND workspace_agent_resource_usage.bucket_start >= $2
+	AND workspace_agent_resource_usage.bucket_start < $3
+ORDER BY
+	workspace_agents.name,
+	workspace_agent_resource_usage.bucket_start
+`
+
+type GetWorkspaceAgentResourceUsageByWorkspaceIDParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	StartTime   time.Time `db:"start_time" json:"start_time"`
+	EndTime     time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetWorkspaceAgentResourceUsageByWorkspaceIDRow struct {
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+	BucketStart   time.Time `db:"bucket_start" json:"bucket_start"`
+	Samples       int32     `db:"samples" json:"samples"`
+	CPUUsedAvg    float64   `db:"cpu_used_avg" json:"cpu_used_avg"`
+	CPUUsedMax    float64   `db:"cpu_used_max" json:"cpu_used_max"`
+	CPUTotal      float64   `db:"cpu_total" json:"cpu_total"`
+	MemoryUsedAvg int64     `db:"memory_used_avg" json:"memory_used_avg"`
+	MemoryUsedMax int64     `db:"memory_used_max" json:"memory_used_max"`
+	MemoryTotal   int64     `db:"memory_total" json:"memory_total"`
+	DiskUsed      int64     `db:"disk_used" json:"disk_used"`
+	DiskTotal     int64     `db:"disk_total" json:"disk_total"`
+	AgentName     string    `db:"agent_name" json:"agent_name"`
+}
+
+// Returns the resource usage of the agents of all builds of the workspace, so
+// that usage can be followed across builds by agent name.
+func (q *sqlQuerier) GetWorkspaceAgentResourceUsageByWorkspaceID(ctx context.Context, arg GetWorkspaceAgentResourceUsageByWorkspaceIDParams) ([]GetWorkspaceAgentResourceUsageByWorkspaceIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentResourceUsageByWorkspaceID, arg.WorkspaceID, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentResourceUsageByWorkspaceIDRow
+	for rows.Next() {
+		var i GetWorkspaceAgentResourceUsageByWorkspaceIDRow
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.BucketStart,
+			&i.Samples,
+			&i.CPUUsedAvg,
+			&i.CPUUsedMax,
+			&i.CPUTotal,
+			&i.MemoryUsedAvg,
+			&i.MemoryUsedMax,
+			&i.MemoryTotal,
+			&i.DiskUsed,
+			&i.DiskTotal,
+			&i.AgentName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceAgentResourceUsage = `-- name: UpsertWorkspaceAgentResourceUsage :exec
+INSERT INTO
+	workspace_agent_resource_usage (
+		agent_id,
+		bucket_start,
+		samples,
+		cpu_used_avg,
+		cpu_used_max,
+		cpu_total,
+		memory_used_avg,
+		memory_used_max,
+		memory_total,
+		disk_used,
+		disk_total
+	)
+VALUES
+	(
+		$1,
+		$2,
+		1,
+		$3::double precision,
+		$3::double precision,
+		$4,
+		$5::bigint,
+		$5::bigint,
+		$6,
+		$7,
+		$8
+	)
+ON CONFLICT (agent_id, bucket_start) DO UPDATE SET
+	samples = workspace_agent_resource_usage.samples + 1,
+	cpu_used_avg = (workspace_agent_resource_usage.cpu_used_avg * workspace_agent_resource_usage.samples + EXCLUDED.cpu_used_avg) / (workspace_agent_resource_usage.samples + 1),
+	cpu_used_max = GREATEST(workspace_agent_resource_usage.cpu_used_max, EXCLUDED.cpu_used_max),
+	cpu_total = EXCLUDED.cpu_total,
+	memory_used_avg = (workspace_agent_resource_usage.memory_used_avg * workspace_agent_resource_usage.samples + EXCLUDED.memory_used_avg) / (workspace_agent_resource_usage.samples + 1),
+	memory_used_max = GREATEST(workspace_agent_resource_usage.memory_used_max, EXCLUDED.memory_used_max),
+	memory_total = EXCLUDED.memory_total,
+	disk_used = EXCLUDED.disk_used,
+	disk_total = EXCLUDED.disk_total
+`
+
+type UpsertWorkspaceAgentResourceUsageParams struct {
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	BucketStart time.Time `db:"bucket_start" json:"bucket_start"`
+	CPUUsed     float64   `db:"cpu_used" json:"cpu_used"`
+	CPUTotal    float64   `db:"cpu_total" json:"cpu_total"`
+	MemoryUsed  int64     `db:"memory_used" json:"memory_used"`
+	MemoryTotal int64     `db:"memory_total" json:"memory_total"`
+	DiskUsed    int64     `db:"disk_used" json:"disk_used"`
+	DiskTotal   int64     `db:"disk_total" json:"disk_total"`
+}
+
+// Adds a sample to the bucket it was collected in, keeping a running average
+// and maximum of the usage.
+func (q *sqlQuerier) UpsertWorkspaceAgentResourceUsage(ctx context.Context, arg UpsertWorkspaceAgentResourceUsageParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceAgentResourceUsage,
+		arg.AgentID,
+		arg.BucketStart,
+		arg.CPUUsed,
+		arg.CPUTotal,
+		arg.MemoryUsed,
+		arg.MemoryTotal,
+		arg.DiskUsed,
+		arg.DiskTotal,
+	)
+	return err
+}
+
+const deleteOldWorkspaceAgentLogs = `-- name: DeleteOldWorkspaceAgentLogs :execrows
 WITH
 	latest_builds AS (
 		SELECT
@@ -14104,9 +18650,12 @@ DELETE FROM workspace_agent_logs WHERE agent_id IN (SELECT id FROM old_agents)
 // If an agent hasn't connected in the last 7 days, we purge it's logs.
 // Exception: if the logs are related to the latest build, we keep those around.
 // Logs can take up a lot of space, so it's important we clean up frequently.
-func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) error {
-	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentLogs, threshold)
-	return err
+func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentLogs, threshold)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const deleteWorkspaceSubAgentByID = `-- name: DeleteWorkspaceSubAgentByID :exec
@@ -14127,9 +18676,9 @@ func (q *sqlQuerier) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UU
 
 const getWorkspaceAgentAndLatestBuildByAuthToken = `-- name: GetWorkspaceAgentAndLatestBuildByAuthToken :one
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at,
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted,
-	workspace_build_with_user.id, workspace_build_with_user.created_at, workspace_build_with_user.updated_at, workspace_build_with_user.workspace_id, workspace_build_with_user.template_version_id, workspace_build_with_user.build_number, workspace_build_with_user.transition, workspace_build_with_user.initiator_id, workspace_build_with_user.provisioner_state, workspace_build_with_user.job_id, workspace_build_with_user.deadline, workspace_build_with_user.reason, workspace_build_with_user.daily_cost, workspace_build_with_user.max_deadline, workspace_build_with_user.template_version_preset_id, workspace_build_with_user.has_ai_task, workspace_build_with_user.ai_task_sidebar_app_id, workspace_build_with_user.initiator_by_avatar_url, workspace_build_with_user.initiator_by_username, workspace_build_with_user.initiator_by_name
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl,
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.display_group, workspace_agents.collapsed,
+	workspace_build_with_user.id, workspace_build_with_user.created_at, workspace_build_with_user.updated_at, workspace_build_with_user.workspace_id, workspace_build_with_user.template_version_id, workspace_build_with_user.build_number, workspace_build_with_user.transition, workspace_build_with_user.initiator_id, workspace_build_with_user.provisioner_state, workspace_build_with_user.job_id, workspace_build_with_user.deadline, workspace_build_with_user.reason, workspace_build_with_user.daily_cost, workspace_build_with_user.max_deadline, workspace_build_with_user.template_version_preset_id, workspace_build_with_user.has_ai_task, workspace_build_with_user.ai_task_sidebar_app_id, workspace_build_with_user.initiator_context, workspace_build_with_user.warnings, workspace_build_with_user.initiator_by_avatar_url, workspace_build_with_user.initiator_by_username, workspace_build_with_user.initiator_by_name
 FROM
 	workspace_agents
 JOIN
@@ -14189,6 +18738,8 @@ func (q *sqlQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Cont
 		&i.WorkspaceTable.AutomaticUpdates,
 		&i.WorkspaceTable.Favorite,
 		&i.WorkspaceTable.NextStartAt,
+		&i.WorkspaceTable.GroupACL,
+		&i.WorkspaceTable.UserACL,
 		&i.WorkspaceAgent.ID,
 		&i.WorkspaceAgent.CreatedAt,
 		&i.WorkspaceAgent.UpdatedAt,
@@ -14223,6 +18774,8 @@ func (q *sqlQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Cont
 		&i.WorkspaceAgent.ParentID,
 		&i.WorkspaceAgent.APIKeyScope,
 		&i.WorkspaceAgent.Deleted,
+		&i.WorkspaceAgent.DisplayGroup,
+		&i.WorkspaceAgent.Collapsed,
 		&i.WorkspaceBuild.ID,
 		&i.WorkspaceBuild.CreatedAt,
 		&i.WorkspaceBuild.UpdatedAt,
@@ -14240,6 +18793,8 @@ func (q *sqlQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Cont
 		&i.WorkspaceBuild.TemplateVersionPresetID,
 		&i.WorkspaceBuild.HasAITask,
 		&i.WorkspaceBuild.AITaskSidebarAppID,
+		&i.WorkspaceBuild.InitiatorContext,
+		&i.WorkspaceBuild.Warnings,
 		&i.WorkspaceBuild.InitiatorByAvatarUrl,
 		&i.WorkspaceBuild.InitiatorByUsername,
 		&i.WorkspaceBuild.InitiatorByName,
@@ -14249,7 +18804,7 @@ func (q *sqlQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Cont
 
 const getWorkspaceAgentByID = `-- name: GetWorkspaceAgentByID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, display_group, collapsed
 FROM
 	workspace_agents
 WHERE
@@ -14296,13 +18851,15 @@ func (q *sqlQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (W
 		&i.ParentID,
 		&i.APIKeyScope,
 		&i.Deleted,
+		&i.DisplayGroup,
+		&i.Collapsed,
 	)
 	return i, err
 }
 
 const getWorkspaceAgentByInstanceID = `-- name: GetWorkspaceAgentByInstanceID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, display_group, collapsed
 FROM
 	workspace_agents
 WHERE
@@ -14351,6 +18908,8 @@ func (q *sqlQuerier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInst
 		&i.ParentID,
 		&i.APIKeyScope,
 		&i.Deleted,
+		&i.DisplayGroup,
+		&i.Collapsed,
 	)
 	return i, err
 }
@@ -14570,7 +19129,7 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context
 
 const getWorkspaceAgentsByParentID = `-- name: GetWorkspaceAgentsByParentID :many
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, display_group, collapsed
 FROM
 	workspace_agents
 WHERE
@@ -14622,6 +19181,8 @@ func (q *sqlQuerier) GetWorkspaceAgentsByParentID(ctx context.Context, parentID
 			&i.ParentID,
 			&i.APIKeyScope,
 			&i.Deleted,
+			&i.DisplayGroup,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -14638,7 +19199,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsByParentID(ctx context.Context, parentID
 
 const getWorkspaceAgentsByResourceIDs = `-- name: GetWorkspaceAgentsByResourceIDs :many
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, display_group, collapsed
 FROM
 	workspace_agents
 WHERE
@@ -14691,6 +19252,8 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 			&i.ParentID,
 			&i.APIKeyScope,
 			&i.Deleted,
+			&i.DisplayGroup,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -14707,7 +19270,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 
 const getWorkspaceAgentsByWorkspaceAndBuildNumber = `-- name: GetWorkspaceAgentsByWorkspaceAndBuildNumber :many
 SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.display_group, workspace_agents.collapsed
 FROM
 	workspace_agents
 JOIN
@@ -14770,6 +19333,8 @@ func (q *sqlQuerier) GetWorkspaceAgentsByWorkspaceAndBuildNumber(ctx context.Con
 			&i.ParentID,
 			&i.APIKeyScope,
 			&i.Deleted,
+			&i.DisplayGroup,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -14785,7 +19350,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsByWorkspaceAndBuildNumber(ctx context.Con
 }
 
 const getWorkspaceAgentsCreatedAfter = `-- name: GetWorkspaceAgentsCreatedAfter :many
-SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted FROM workspace_agents
+SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, display_group, collapsed FROM workspace_agents
 WHERE
 	created_at > $1
 	-- Filter out deleted sub agents.
@@ -14836,6 +19401,8 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 			&i.ParentID,
 			&i.APIKeyScope,
 			&i.Deleted,
+			&i.DisplayGroup,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -14852,7 +19419,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 
 const getWorkspaceAgentsInLatestBuildByWorkspaceID = `-- name: GetWorkspaceAgentsInLatestBuildByWorkspaceID :many
 SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.display_group, workspace_agents.collapsed
 FROM
 	workspace_agents
 JOIN
@@ -14917,6 +19484,8 @@ func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Co
 			&i.ParentID,
 			&i.APIKeyScope,
 			&i.Deleted,
+			&i.DisplayGroup,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -14953,10 +19522,12 @@ INSERT INTO
 		motd_file,
 		display_apps,
 		display_order,
-		api_key_scope
+		api_key_scope,
+		display_group,
+		collapsed
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, display_group, collapsed
 `
 
 type InsertWorkspaceAgentParams struct {
@@ -14980,6 +19551,8 @@ type InsertWorkspaceAgentParams struct {
 	DisplayApps              []DisplayApp          `db:"display_apps" json:"display_apps"`
 	DisplayOrder             int32                 `db:"display_order" json:"display_order"`
 	APIKeyScope              AgentKeyScopeEnum     `db:"api_key_scope" json:"api_key_scope"`
+	DisplayGroup             string                `db:"display_group" json:"display_group"`
+	Collapsed                bool                  `db:"collapsed" json:"collapsed"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error) {
@@ -15004,6 +19577,8 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		pq.Array(arg.DisplayApps),
 		arg.DisplayOrder,
 		arg.APIKeyScope,
+		arg.DisplayGroup,
+		arg.Collapsed,
 	)
 	var i WorkspaceAgent
 	err := row.Scan(
@@ -15041,6 +19616,8 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		&i.ParentID,
 		&i.APIKeyScope,
 		&i.Deleted,
+		&i.DisplayGroup,
+		&i.Collapsed,
 	)
 	return i, err
 }
@@ -15397,23 +19974,26 @@ func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg Up
 	return err
 }
 
-const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
+const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :execrows
 DELETE FROM
 	workspace_agent_stats
 WHERE
 	created_at < (
 		SELECT
-			COALESCE(
-				-- When generating initial template usage stats, all the
-				-- raw agent stats are needed, after that only ~30 mins
-				-- from last rollup is needed. Deployment stats seem to
-				-- use between 15 mins and 1 hour of data. We keep a
-				-- little bit more (1 day) just in case.
-				MAX(start_time) - '1 days'::interval,
-				-- Fall back to ~6 months ago if there are no template
-				-- usage stats so that we don't delete the data before
-				-- it's rolled up.
-				NOW() - '180 days'::interval
+			LEAST(
+				COALESCE(
+					-- When generating initial template usage stats, all the
+					-- raw agent stats are needed, after that only ~30 mins
+					-- from last rollup is needed. Deployment stats seem to
+					-- use between 15 mins and 1 hour of data. We keep a
+					-- little bit more (1 day) just in case.
+					MAX(start_time) - '1 days'::interval,
+					-- Fall back to ~6 months ago if there are no template
+					-- usage stats so that we don't delete the data before
+					-- it's rolled up.
+					NOW() - '180 days'::interval
+				),
+				$1 :: timestamptz
 			)
 		FROM
 			template_usage_stats
@@ -15429,9 +20009,40 @@ WHERE
 	)
 `
 
-func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats)
-	return err
+// Deletes raw agent stats created before @before, but never those that may
+// not have been rolled up into template usage stats yet.
+func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const dropOldWorkspaceAgentStatsPartitions = `-- name: DropOldWorkspaceAgentStatsPartitions :one
+SELECT
+	drop_time_partitions(
+		'workspace_agent_stats',
+		(
+			SELECT
+				LEAST(
+					COALESCE(MAX(start_time) - '1 days'::interval, NOW() - '180 days'::interval),
+					$1 :: timestamptz
+				)
+			FROM
+				template_usage_stats
+		)
+	)::integer AS dropped
+`
+
+// Drops the workspace_agent_stats partitions that only hold rows older than
+// the cutoff used by DeleteOldWorkspaceAgentStats. Returns the number of
+// dropped partitions.
+func (q *sqlQuerier) DropOldWorkspaceAgentStatsPartitions(ctx context.Context, before time.Time) (int32, error) {
+	row := q.db.QueryRowContext(ctx, dropOldWorkspaceAgentStatsPartitions, before)
+	var dropped int32
+	err := row.Scan(&dropped)
+	return dropped, err
 }
 
 const getDeploymentDAUs = `-- name: GetDeploymentDAUs :many
@@ -16741,47 +21352,466 @@ func (q *sqlQuerier) InsertWorkspaceAppStats(ctx context.Context, arg InsertWork
 	return err
 }
 
-const getUserWorkspaceBuildParameters = `-- name: GetUserWorkspaceBuildParameters :many
-SELECT name, value
-FROM (
-    SELECT DISTINCT ON (tvp.name)
-        tvp.name,
-        wbp.value,
-        wb.created_at
-    FROM
-        workspace_build_parameters wbp
-    JOIN
-        workspace_builds wb ON wb.id = wbp.workspace_build_id
-    JOIN
-        workspaces w ON w.id = wb.workspace_id
-    JOIN
-        template_version_parameters tvp ON tvp.template_version_id = wb.template_version_id
-    WHERE
-		w.owner_id = $1
-		AND wb.transition = 'start'
-		AND w.template_id = $2
-		AND tvp.ephemeral = false
-		AND tvp.name = wbp.name
-    ORDER BY
-        tvp.name, wb.created_at DESC
-) q1
-ORDER BY created_at DESC, name
-LIMIT 100
+const deleteWorkspaceBuildArchiveByBuildID = `-- name: DeleteWorkspaceBuildArchiveByBuildID :exec
+WITH deleted AS (
+	DELETE FROM
+		workspace_build_archives
+	WHERE
+		workspace_build_id = $1
+	RETURNING file_id
+)
+DELETE FROM
+	files
+WHERE
+	id IN (SELECT file_id FROM deleted)
 `
 
-type GetUserWorkspaceBuildParametersParams struct {
-	OwnerID    uuid.UUID `db:"owner_id" json:"owner_id"`
-	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+// Deletes the archive of a build together with the file holding its data.
+func (q *sqlQuerier) DeleteWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceBuildArchiveByBuildID, workspaceBuildID)
+	return err
 }
 
-type GetUserWorkspaceBuildParametersRow struct {
-	Name  string `db:"name" json:"name"`
-	Value string `db:"value" json:"value"`
-}
+const getWorkspaceBuildArchiveByBuildID = `-- name: GetWorkspaceBuildArchiveByBuildID :one
+SELECT
+	workspace_build_id, file_id, state_size, resource_metadata_count, archived_at
+FROM
+	workspace_build_archives
+WHERE
+	workspace_build_id = $1
+`
 
-func (q *sqlQuerier) GetUserWorkspaceBuildParameters(ctx context.Context, arg GetUserWorkspaceBuildParametersParams) ([]GetUserWorkspaceBuildParametersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUserWorkspaceBuildParameters, arg.OwnerID, arg.TemplateID)
-	if err != nil {
+func (q *sqlQuerier) GetWorkspaceBuildArchiveByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (WorkspaceBuildArchive, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildArchiveByBuildID, workspaceBuildID)
+	var i WorkspaceBuildArchive
+	err := row.Scan(
+		&i.WorkspaceBuildID,
+		&i.FileID,
+		&i.StateSize,
+		&i.ResourceMetadataCount,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceBuildsToArchive = `-- name: GetWorkspaceBuildsToArchive :many
+SELECT
+	workspace_builds.id,
+	workspace_builds.job_id,
+	workspace_builds.initiator_id
+FROM
+	workspace_builds
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	provisioner_jobs.completed_at < $1 :: timestamptz
+	AND workspace_builds.build_number < (
+		SELECT
+			MAX(latest.build_number)
+		FROM
+			workspace_builds AS latest
+		WHERE
+			latest.workspace_id = workspace_builds.workspace_id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM workspace_build_archives WHERE workspace_build_archives.workspace_build_id = workspace_builds.id
+	)
+ORDER BY
+	provisioner_jobs.completed_at ASC
+LIMIT
+	$2 :: int
+`
+
+type GetWorkspaceBuildsToArchiveParams struct {
+	CompletedBefore time.Time `db:"completed_before" json:"completed_before"`
+	LimitCount      int32     `db:"limit_count" json:"limit_count"`
+}
+
+type GetWorkspaceBuildsToArchiveRow struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	JobID       uuid.UUID `db:"job_id" json:"job_id"`
+	InitiatorID uuid.UUID `db:"initiator_id" json:"initiator_id"`
+}
+
+// Returns builds that are not the latest build of their workspace, whose
+// job completed before the given time and that are not archived yet,
+// oldest first.
+func (q *sqlQuerier) GetWorkspaceBuildsToArchive(ctx context.Context, arg GetWorkspaceBuildsToArchiveParams) ([]GetWorkspaceBuildsToArchiveRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsToArchive, arg.CompletedBefore, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceBuildsToArchiveRow
+	for rows.Next() {
+		var i GetWorkspaceBuildsToArchiveRow
+		if err := rows.Scan(&i.ID, &i.JobID, &i.InitiatorID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceBuildArchive = `-- name: InsertWorkspaceBuildArchive :one
+INSERT INTO
+	workspace_build_archives (
+		workspace_build_id,
+		file_id,
+		state_size,
+		resource_metadata_count,
+		archived_at
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING workspace_build_id, file_id, state_size, resource_metadata_count, archived_at
+`
+
+type InsertWorkspaceBuildArchiveParams struct {
+	WorkspaceBuildID      uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	FileID                uuid.UUID `db:"file_id" json:"file_id"`
+	StateSize             int32     `db:"state_size" json:"state_size"`
+	ResourceMetadataCount int32     `db:"resource_metadata_count" json:"resource_metadata_count"`
+	ArchivedAt            time.Time `db:"archived_at" json:"archived_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceBuildArchive(ctx context.Context, arg InsertWorkspaceBuildArchiveParams) (WorkspaceBuildArchive, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceBuildArchive,
+		arg.WorkspaceBuildID,
+		arg.FileID,
+		arg.StateSize,
+		arg.ResourceMetadataCount,
+		arg.ArchivedAt,
+	)
+	var i WorkspaceBuildArchive
+	err := row.Scan(
+		&i.WorkspaceBuildID,
+		&i.FileID,
+		&i.StateSize,
+		&i.ResourceMetadataCount,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const getRunningWorkspaceBuildsExceedingDurationThreshold = `-- name: GetRunningWorkspaceBuildsExceedingDurationThreshold :many
+WITH thresholds AS MATERIALIZED (
+	-- Materialized so the cast only applies to threshold values.
+	SELECT
+		user_id,
+		value::bigint AS threshold_ms
+	FROM
+		user_configs
+	WHERE
+		key = 'notification_build_duration_threshold_ms'
+)
+SELECT
+	wb.id AS workspace_build_id,
+	wb.build_number,
+	wb.transition,
+	w.id AS workspace_id,
+	w.name AS workspace_name,
+	w.owner_id,
+	w.template_id,
+	w.organization_id,
+	t.threshold_ms
+FROM
+	workspace_builds AS wb
+JOIN
+	provisioner_jobs AS pj
+ON
+	wb.job_id = pj.id
+JOIN
+	workspaces AS w
+ON
+	wb.workspace_id = w.id
+JOIN
+	thresholds AS t
+ON
+	w.owner_id = t.user_id
+WHERE
+	pj.job_status = 'running'
+	AND t.threshold_ms > 0
+	AND w.deleted = false
+	AND pj.started_at + t.threshold_ms * INTERVAL '1 millisecond' <= $1::timestamptz
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			workspace_build_duration_notifications AS n
+		WHERE
+			n.workspace_build_id = wb.id
+	)
+ORDER BY
+	pj.started_at ASC
+`
+
+type GetRunningWorkspaceBuildsExceedingDurationThresholdRow struct {
+	WorkspaceBuildID uuid.UUID           `db:"workspace_build_id" json:"workspace_build_id"`
+	BuildNumber      int32               `db:"build_number" json:"build_number"`
+	Transition       WorkspaceTransition `db:"transition" json:"transition"`
+	WorkspaceID      uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName    string              `db:"workspace_name" json:"workspace_name"`
+	OwnerID          uuid.UUID           `db:"owner_id" json:"owner_id"`
+	TemplateID       uuid.UUID           `db:"template_id" json:"template_id"`
+	OrganizationID   uuid.UUID           `db:"organization_id" json:"organization_id"`
+	ThresholdMs      int64               `db:"threshold_ms" json:"threshold_ms"`
+}
+
+// Returns the running workspace builds which have been running for longer
+// than the build duration notification threshold of the workspace owner, and
+// whose owner wasn't notified yet.
+func (q *sqlQuerier) GetRunningWorkspaceBuildsExceedingDurationThreshold(ctx context.Context, now time.Time) ([]GetRunningWorkspaceBuildsExceedingDurationThresholdRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRunningWorkspaceBuildsExceedingDurationThreshold, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRunningWorkspaceBuildsExceedingDurationThresholdRow
+	for rows.Next() {
+		var i GetRunningWorkspaceBuildsExceedingDurationThresholdRow
+		if err := rows.Scan(
+			&i.WorkspaceBuildID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.ThresholdMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceBuildDurationNotification = `-- name: InsertWorkspaceBuildDurationNotification :execrows
+INSERT INTO
+	workspace_build_duration_notifications (
+		workspace_build_id,
+		notified_at
+	)
+VALUES
+	($1, $2)
+ON CONFLICT (workspace_build_id) DO NOTHING
+`
+
+type InsertWorkspaceBuildDurationNotificationParams struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	NotifiedAt       time.Time `db:"notified_at" json:"notified_at"`
+}
+
+// Records that the owner of a workspace build was notified about its
+// duration. No rows are affected if the build was already notified, e.g. by
+// another replica.
+func (q *sqlQuerier) InsertWorkspaceBuildDurationNotification(ctx context.Context, arg InsertWorkspaceBuildDurationNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertWorkspaceBuildDurationNotification, arg.WorkspaceBuildID, arg.NotifiedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOldWorkspaceBuildIdempotencyKeys = `-- name: DeleteOldWorkspaceBuildIdempotencyKeys :exec
+DELETE FROM
+	workspace_build_idempotency_keys
+WHERE
+	created_at < $1
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceBuildIdempotencyKeys(ctx context.Context, before time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceBuildIdempotencyKeys, before)
+	return err
+}
+
+const getWorkspaceBuildIdempotencyKey = `-- name: GetWorkspaceBuildIdempotencyKey :one
+SELECT
+	workspace_build_id, user_id, idempotency_key, created_at, request_hash
+FROM
+	workspace_build_idempotency_keys
+WHERE
+	user_id = $1
+	AND idempotency_key = $2
+`
+
+type GetWorkspaceBuildIdempotencyKeyParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	IdempotencyKey string    `db:"idempotency_key" json:"idempotency_key"`
+}
+
+func (q *sqlQuerier) GetWorkspaceBuildIdempotencyKey(ctx context.Context, arg GetWorkspaceBuildIdempotencyKeyParams) (WorkspaceBuildIdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildIdempotencyKey, arg.UserID, arg.IdempotencyKey)
+	var i WorkspaceBuildIdempotencyKey
+	err := row.Scan(
+		&i.WorkspaceBuildID,
+		&i.UserID,
+		&i.IdempotencyKey,
+		&i.CreatedAt,
+		&i.RequestHash,
+	)
+	return i, err
+}
+
+const insertWorkspaceBuildIdempotencyKey = `-- name: InsertWorkspaceBuildIdempotencyKey :exec
+INSERT INTO
+	workspace_build_idempotency_keys (
+		user_id,
+		idempotency_key,
+		request_hash,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4)
+`
+
+type InsertWorkspaceBuildIdempotencyKeyParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	IdempotencyKey string    `db:"idempotency_key" json:"idempotency_key"`
+	RequestHash    []byte    `db:"request_hash" json:"request_hash"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// Reserves the key before the build of the request is created. A concurrent
+// insert of the same key waits for the transaction that reserved it.
+func (q *sqlQuerier) InsertWorkspaceBuildIdempotencyKey(ctx context.Context, arg InsertWorkspaceBuildIdempotencyKeyParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBuildIdempotencyKey,
+		arg.UserID,
+		arg.IdempotencyKey,
+		arg.RequestHash,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const updateWorkspaceBuildIdempotencyKeyBuildID = `-- name: UpdateWorkspaceBuildIdempotencyKeyBuildID :exec
+UPDATE
+	workspace_build_idempotency_keys
+SET
+	workspace_build_id = $1 :: uuid
+WHERE
+	user_id = $2
+	AND idempotency_key = $3
+`
+
+type UpdateWorkspaceBuildIdempotencyKeyBuildIDParams struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	IdempotencyKey   string    `db:"idempotency_key" json:"idempotency_key"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildIdempotencyKeyBuildID(ctx context.Context, arg UpdateWorkspaceBuildIdempotencyKeyBuildIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildIdempotencyKeyBuildID, arg.WorkspaceBuildID, arg.UserID, arg.IdempotencyKey)
+	return err
+}
+
+const deleteWorkspaceBuildInterimState = `-- name: DeleteWorkspaceBuildInterimState :exec
+DELETE FROM
+	workspace_build_interim_states
+WHERE
+	workspace_build_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceBuildInterimState(ctx context.Context, workspaceBuildID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceBuildInterimState, workspaceBuildID)
+	return err
+}
+
+const getWorkspaceBuildInterimStateByBuildID = `-- name: GetWorkspaceBuildInterimStateByBuildID :one
+SELECT
+	workspace_build_id, state, updated_at
+FROM
+	workspace_build_interim_states
+WHERE
+	workspace_build_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildInterimStateByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (WorkspaceBuildInterimState, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildInterimStateByBuildID, workspaceBuildID)
+	var i WorkspaceBuildInterimState
+	err := row.Scan(&i.WorkspaceBuildID, &i.State, &i.UpdatedAt)
+	return i, err
+}
+
+const upsertWorkspaceBuildInterimState = `-- name: UpsertWorkspaceBuildInterimState :exec
+INSERT INTO
+	workspace_build_interim_states (
+		workspace_build_id,
+		state,
+		updated_at
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (workspace_build_id) DO UPDATE SET
+	state = EXCLUDED.state,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertWorkspaceBuildInterimStateParams struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	State            []byte    `db:"state" json:"state"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceBuildInterimState(ctx context.Context, arg UpsertWorkspaceBuildInterimStateParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceBuildInterimState, arg.WorkspaceBuildID, arg.State, arg.UpdatedAt)
+	return err
+}
+
+const getUserWorkspaceBuildParameters = `-- name: GetUserWorkspaceBuildParameters :many
+SELECT name, value
+FROM (
+    SELECT DISTINCT ON (tvp.name)
+        tvp.name,
+        wbp.value,
+        wb.created_at
+    FROM
+        workspace_build_parameters wbp
+    JOIN
+        workspace_builds wb ON wb.id = wbp.workspace_build_id
+    JOIN
+        workspaces w ON w.id = wb.workspace_id
+    JOIN
+        template_version_parameters tvp ON tvp.template_version_id = wb.template_version_id
+    WHERE
+		w.owner_id = $1
+		AND wb.transition = 'start'
+		AND w.template_id = $2
+		AND tvp.ephemeral = false
+		AND tvp.name = wbp.name
+    ORDER BY
+        tvp.name, wb.created_at DESC
+) q1
+ORDER BY created_at DESC, name
+LIMIT 100
+`
+
+type GetUserWorkspaceBuildParametersParams struct {
+	OwnerID    uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+}
+
+type GetUserWorkspaceBuildParametersRow struct {
+	Name  string `db:"name" json:"name"`
+	Value string `db:"value" json:"value"`
+}
+
+func (q *sqlQuerier) GetUserWorkspaceBuildParameters(ctx context.Context, arg GetUserWorkspaceBuildParametersParams) ([]GetUserWorkspaceBuildParametersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserWorkspaceBuildParameters, arg.OwnerID, arg.TemplateID)
+	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
@@ -16893,16 +21923,236 @@ func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg Ins
 	return err
 }
 
-const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
-FROM (
-    SELECT
-        workspace_id, MAX(build_number) as max_build_number
-    FROM
-		workspace_build_with_user AS workspace_builds
-    WHERE
-        workspace_id IN (
-			SELECT
+const deleteWorkspaceBuildQueueEntry = `-- name: DeleteWorkspaceBuildQueueEntry :exec
+DELETE FROM
+	workspace_build_queue
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceBuildQueueEntry(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceBuildQueueEntry, id)
+	return err
+}
+
+const getStartableWorkspaceBuildQueueEntries = `-- name: GetStartableWorkspaceBuildQueueEntries :many
+SELECT DISTINCT ON (workspace_build_queue.workspace_id)
+	workspace_build_queue.id, workspace_build_queue.workspace_id, workspace_build_queue.initiator_id, workspace_build_queue.transition, workspace_build_queue.template_version_id, workspace_build_queue.template_version_preset_id, workspace_build_queue.rich_parameter_values, workspace_build_queue.log_level, workspace_build_queue.created_at, workspace_build_queue.failed_at, workspace_build_queue.error
+FROM
+	workspace_build_queue
+	INNER JOIN workspace_latest_builds ON workspace_latest_builds.workspace_id = workspace_build_queue.workspace_id
+WHERE
+	workspace_build_queue.failed_at IS NULL
+	AND (
+		workspace_latest_builds.job_status IS NULL
+		OR workspace_latest_builds.job_status NOT IN ('pending'::provisioner_job_status, 'running'::provisioner_job_status, 'canceling'::provisioner_job_status)
+	)
+ORDER BY
+	workspace_build_queue.workspace_id,
+	workspace_build_queue.created_at ASC
+`
+
+// Returns the oldest entry that has not failed of each workspace whose latest
+// build is not active.
+func (q *sqlQuerier) GetStartableWorkspaceBuildQueueEntries(ctx context.Context) ([]WorkspaceBuildQueue, error) {
+	rows, err := q.db.QueryContext(ctx, getStartableWorkspaceBuildQueueEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBuildQueue
+	for rows.Next() {
+		var i WorkspaceBuildQueue
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.InitiatorID,
+			&i.Transition,
+			&i.TemplateVersionID,
+			&i.TemplateVersionPresetID,
+			&i.RichParameterValues,
+			&i.LogLevel,
+			&i.CreatedAt,
+			&i.FailedAt,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceBuildQueueByWorkspaceID = `-- name: GetWorkspaceBuildQueueByWorkspaceID :many
+SELECT
+	id, workspace_id, initiator_id, transition, template_version_id, template_version_preset_id, rich_parameter_values, log_level, created_at, failed_at, error
+FROM
+	workspace_build_queue
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildQueueByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceBuildQueue, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildQueueByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBuildQueue
+	for rows.Next() {
+		var i WorkspaceBuildQueue
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.InitiatorID,
+			&i.Transition,
+			&i.TemplateVersionID,
+			&i.TemplateVersionPresetID,
+			&i.RichParameterValues,
+			&i.LogLevel,
+			&i.CreatedAt,
+			&i.FailedAt,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceBuildQueueEntryByID = `-- name: GetWorkspaceBuildQueueEntryByID :one
+SELECT
+	id, workspace_id, initiator_id, transition, template_version_id, template_version_preset_id, rich_parameter_values, log_level, created_at, failed_at, error
+FROM
+	workspace_build_queue
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) (WorkspaceBuildQueue, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildQueueEntryByID, id)
+	var i WorkspaceBuildQueue
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.InitiatorID,
+		&i.Transition,
+		&i.TemplateVersionID,
+		&i.TemplateVersionPresetID,
+		&i.RichParameterValues,
+		&i.LogLevel,
+		&i.CreatedAt,
+		&i.FailedAt,
+		&i.Error,
+	)
+	return i, err
+}
+
+const insertWorkspaceBuildQueueEntry = `-- name: InsertWorkspaceBuildQueueEntry :one
+INSERT INTO
+	workspace_build_queue (
+		id,
+		workspace_id,
+		initiator_id,
+		transition,
+		template_version_id,
+		template_version_preset_id,
+		rich_parameter_values,
+		log_level,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, workspace_id, initiator_id, transition, template_version_id, template_version_preset_id, rich_parameter_values, log_level, created_at, failed_at, error
+`
+
+type InsertWorkspaceBuildQueueEntryParams struct {
+	ID                      uuid.UUID           `db:"id" json:"id"`
+	WorkspaceID             uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	InitiatorID             uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	Transition              WorkspaceTransition `db:"transition" json:"transition"`
+	TemplateVersionID       uuid.NullUUID       `db:"template_version_id" json:"template_version_id"`
+	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
+	RichParameterValues     json.RawMessage     `db:"rich_parameter_values" json:"rich_parameter_values"`
+	LogLevel                string              `db:"log_level" json:"log_level"`
+	CreatedAt               time.Time           `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceBuildQueueEntry(ctx context.Context, arg InsertWorkspaceBuildQueueEntryParams) (WorkspaceBuildQueue, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceBuildQueueEntry,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.InitiatorID,
+		arg.Transition,
+		arg.TemplateVersionID,
+		arg.TemplateVersionPresetID,
+		arg.RichParameterValues,
+		arg.LogLevel,
+		arg.CreatedAt,
+	)
+	var i WorkspaceBuildQueue
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.InitiatorID,
+		&i.Transition,
+		&i.TemplateVersionID,
+		&i.TemplateVersionPresetID,
+		&i.RichParameterValues,
+		&i.LogLevel,
+		&i.CreatedAt,
+		&i.FailedAt,
+		&i.Error,
+	)
+	return i, err
+}
+
+const updateWorkspaceBuildQueueEntryFailed = `-- name: UpdateWorkspaceBuildQueueEntryFailed :exec
+UPDATE
+	workspace_build_queue
+SET
+	failed_at = $1,
+	error = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceBuildQueueEntryFailedParams struct {
+	FailedAt sql.NullTime `db:"failed_at" json:"failed_at"`
+	Error    string       `db:"error" json:"error"`
+	ID       uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildQueueEntryFailed(ctx context.Context, arg UpdateWorkspaceBuildQueueEntryFailedParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildQueueEntryFailed, arg.FailedAt, arg.Error, arg.ID)
+	return err
+}
+
+const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_context, wb.warnings, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
+FROM (
+    SELECT
+        workspace_id, MAX(build_number) as max_build_number
+    FROM
+		workspace_build_with_user AS workspace_builds
+    WHERE
+        workspace_id IN (
+			SELECT
 				id
 			FROM
 				workspaces
@@ -16951,6 +22201,8 @@ func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, t
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.InitiatorContext,
+			&i.Warnings,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -17048,9 +22300,60 @@ func (q *sqlQuerier) GetFailedWorkspaceBuildsByTemplateID(ctx context.Context, a
 	return items, nil
 }
 
+const getLatestSuccessfulWorkspaceBuildByWorkspaceID = `-- name: GetLatestSuccessfulWorkspaceBuildByWorkspaceID :one
+SELECT
+	workspace_builds.id, workspace_builds.created_at, workspace_builds.updated_at, workspace_builds.workspace_id, workspace_builds.template_version_id, workspace_builds.build_number, workspace_builds.transition, workspace_builds.initiator_id, workspace_builds.provisioner_state, workspace_builds.job_id, workspace_builds.deadline, workspace_builds.reason, workspace_builds.daily_cost, workspace_builds.max_deadline, workspace_builds.template_version_preset_id, workspace_builds.has_ai_task, workspace_builds.ai_task_sidebar_app_id, workspace_builds.initiator_context, workspace_builds.warnings, workspace_builds.initiator_by_avatar_url, workspace_builds.initiator_by_username, workspace_builds.initiator_by_name
+FROM
+	workspace_build_with_user AS workspace_builds
+JOIN
+	provisioner_jobs
+ON
+	provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_builds.workspace_id = $1
+	AND workspace_builds.transition = 'start'
+	AND provisioner_jobs.job_status = 'succeeded'
+ORDER BY
+	workspace_builds.build_number DESC
+LIMIT
+	1
+`
+
+// Returns the latest start build of the workspace whose job succeeded, which
+// is the build a workspace is rolled back to.
+func (q *sqlQuerier) GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getLatestSuccessfulWorkspaceBuildByWorkspaceID, workspaceID)
+	var i WorkspaceBuild
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.ProvisionerState,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.TemplateVersionPresetID,
+		&i.HasAITask,
+		&i.AITaskSidebarAppID,
+		&i.InitiatorContext,
+		&i.Warnings,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
+		&i.InitiatorByName,
+	)
+	return i, err
+}
+
 const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_context, warnings, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -17082,6 +22385,8 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 		&i.TemplateVersionPresetID,
 		&i.HasAITask,
 		&i.AITaskSidebarAppID,
+		&i.InitiatorContext,
+		&i.Warnings,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 		&i.InitiatorByName,
@@ -17090,7 +22395,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 }
 
 const getLatestWorkspaceBuilds = `-- name: GetLatestWorkspaceBuilds :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_context, wb.warnings, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -17131,6 +22436,8 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.InitiatorContext,
+			&i.Warnings,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -17149,7 +22456,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 }
 
 const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_context, wb.warnings, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -17192,6 +22499,8 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.InitiatorContext,
+			&i.Warnings,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -17211,7 +22520,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 
 const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_context, warnings, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -17241,6 +22550,8 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 		&i.TemplateVersionPresetID,
 		&i.HasAITask,
 		&i.AITaskSidebarAppID,
+		&i.InitiatorContext,
+		&i.Warnings,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 		&i.InitiatorByName,
@@ -17250,7 +22561,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 
 const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_context, warnings, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -17280,6 +22591,8 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 		&i.TemplateVersionPresetID,
 		&i.HasAITask,
 		&i.AITaskSidebarAppID,
+		&i.InitiatorContext,
+		&i.Warnings,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 		&i.InitiatorByName,
@@ -17289,7 +22602,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 
 const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_context, warnings, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -17323,6 +22636,8 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Co
 		&i.TemplateVersionPresetID,
 		&i.HasAITask,
 		&i.AITaskSidebarAppID,
+		&i.InitiatorContext,
+		&i.Warnings,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 		&i.InitiatorByName,
@@ -17330,6 +22645,82 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Co
 	return i, err
 }
 
+const getWorkspaceBuildOutcomesCompletedBetween = `-- name: GetWorkspaceBuildOutcomesCompletedBetween :many
+SELECT
+	t.name AS template_name,
+	wb.transition,
+	pj.job_status,
+	pj.error_code,
+	pj.started_at,
+	pj.completed_at
+FROM
+	workspace_builds AS wb
+JOIN
+	provisioner_jobs AS pj
+ON
+	wb.job_id = pj.id
+JOIN
+	workspaces AS w
+ON
+	wb.workspace_id = w.id
+JOIN
+	templates AS t
+ON
+	w.template_id = t.id
+WHERE
+	pj.completed_at > $1 :: timestamptz
+	AND pj.completed_at <= $2 :: timestamptz
+ORDER BY
+	pj.completed_at ASC
+`
+
+type GetWorkspaceBuildOutcomesCompletedBetweenParams struct {
+	CompletedAfter  time.Time `db:"completed_after" json:"completed_after"`
+	CompletedBefore time.Time `db:"completed_before" json:"completed_before"`
+}
+
+type GetWorkspaceBuildOutcomesCompletedBetweenRow struct {
+	TemplateName string               `db:"template_name" json:"template_name"`
+	Transition   WorkspaceTransition  `db:"transition" json:"transition"`
+	JobStatus    ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	ErrorCode    sql.NullString       `db:"error_code" json:"error_code"`
+	StartedAt    sql.NullTime         `db:"started_at" json:"started_at"`
+	CompletedAt  sql.NullTime         `db:"completed_at" json:"completed_at"`
+}
+
+// Returns the outcome of every workspace build whose provisioner job
+// completed in the given time range, along with the template of the
+// workspace.
+func (q *sqlQuerier) GetWorkspaceBuildOutcomesCompletedBetween(ctx context.Context, arg GetWorkspaceBuildOutcomesCompletedBetweenParams) ([]GetWorkspaceBuildOutcomesCompletedBetweenRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildOutcomesCompletedBetween, arg.CompletedAfter, arg.CompletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceBuildOutcomesCompletedBetweenRow
+	for rows.Next() {
+		var i GetWorkspaceBuildOutcomesCompletedBetweenRow
+		if err := rows.Scan(
+			&i.TemplateName,
+			&i.Transition,
+			&i.JobStatus,
+			&i.ErrorCode,
+			&i.StartedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceBuildStatsByTemplates = `-- name: GetWorkspaceBuildStatsByTemplates :many
 SELECT
     w.template_id,
@@ -17399,7 +22790,7 @@ func (q *sqlQuerier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, sinc
 
 const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_context, warnings, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -17472,6 +22863,8 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.InitiatorContext,
+			&i.Warnings,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -17490,7 +22883,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 }
 
 const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
-SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name FROM workspace_build_with_user WHERE created_at > $1
+SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_context, warnings, initiator_by_avatar_url, initiator_by_username, initiator_by_name FROM workspace_build_with_user WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
@@ -17520,6 +22913,8 @@ func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, created
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.InitiatorContext,
+			&i.Warnings,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -17553,27 +22948,31 @@ INSERT INTO
 		deadline,
 		max_deadline,
 		reason,
-		template_version_preset_id
+		template_version_preset_id,
+		initiator_context,
+		warnings
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 `
 
 type InsertWorkspaceBuildParams struct {
-	ID                      uuid.UUID           `db:"id" json:"id"`
-	CreatedAt               time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt               time.Time           `db:"updated_at" json:"updated_at"`
-	WorkspaceID             uuid.UUID           `db:"workspace_id" json:"workspace_id"`
-	TemplateVersionID       uuid.UUID           `db:"template_version_id" json:"template_version_id"`
-	BuildNumber             int32               `db:"build_number" json:"build_number"`
-	Transition              WorkspaceTransition `db:"transition" json:"transition"`
-	InitiatorID             uuid.UUID           `db:"initiator_id" json:"initiator_id"`
-	JobID                   uuid.UUID           `db:"job_id" json:"job_id"`
-	ProvisionerState        []byte              `db:"provisioner_state" json:"provisioner_state"`
-	Deadline                time.Time           `db:"deadline" json:"deadline"`
-	MaxDeadline             time.Time           `db:"max_deadline" json:"max_deadline"`
-	Reason                  BuildReason         `db:"reason" json:"reason"`
-	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
+	ID                      uuid.UUID              `db:"id" json:"id"`
+	CreatedAt               time.Time              `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time              `db:"updated_at" json:"updated_at"`
+	WorkspaceID             uuid.UUID              `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID       uuid.UUID              `db:"template_version_id" json:"template_version_id"`
+	BuildNumber             int32                  `db:"build_number" json:"build_number"`
+	Transition              WorkspaceTransition    `db:"transition" json:"transition"`
+	InitiatorID             uuid.UUID              `db:"initiator_id" json:"initiator_id"`
+	JobID                   uuid.UUID              `db:"job_id" json:"job_id"`
+	ProvisionerState        []byte                 `db:"provisioner_state" json:"provisioner_state"`
+	Deadline                time.Time              `db:"deadline" json:"deadline"`
+	MaxDeadline             time.Time              `db:"max_deadline" json:"max_deadline"`
+	Reason                  BuildReason            `db:"reason" json:"reason"`
+	TemplateVersionPresetID uuid.NullUUID          `db:"template_version_preset_id" json:"template_version_preset_id"`
+	InitiatorContext        BuildInitiatorContext  `db:"initiator_context" json:"initiator_context"`
+	Warnings                WorkspaceBuildWarnings `db:"warnings" json:"warnings"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error {
@@ -17592,6 +22991,8 @@ func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspa
 		arg.MaxDeadline,
 		arg.Reason,
 		arg.TemplateVersionPresetID,
+		arg.InitiatorContext,
+		arg.Warnings,
 	)
 	return err
 }
@@ -17689,6 +23090,1038 @@ func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Contex
 	return err
 }
 
+const getWorkspaceDailyCosts = `-- name: GetWorkspaceDailyCosts :many
+SELECT
+	wdc.start_time,
+	wdc.end_time,
+	wdc.workspace_id,
+	w.name AS workspace_name,
+	wdc.owner_id,
+	u.username AS owner_username,
+	wdc.organization_id,
+	o.name AS organization_name,
+	wdc.template_id,
+	wdc.cost
+FROM
+	workspace_daily_costs wdc
+JOIN
+	workspaces w
+ON
+	w.id = wdc.workspace_id
+JOIN
+	users u
+ON
+	u.id = wdc.owner_id
+JOIN
+	organizations o
+ON
+	o.id = wdc.organization_id
+WHERE
+	wdc.start_time >= $1::timestamptz
+	AND wdc.start_time < $2::timestamptz
+	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN wdc.template_id = ANY($3::uuid[]) ELSE TRUE END
+ORDER BY
+	wdc.start_time ASC,
+	wdc.workspace_id ASC;
+`
+
+type GetWorkspaceDailyCostsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetWorkspaceDailyCostsRow struct {
+	StartTime        time.Time `db:"start_time" json:"start_time"`
+	EndTime          time.Time `db:"end_time" json:"end_time"`
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName    string    `db:"workspace_name" json:"workspace_name"`
+	OwnerID          uuid.UUID `db:"owner_id" json:"owner_id"`
+	OwnerUsername    string    `db:"owner_username" json:"owner_username"`
+	OrganizationID   uuid.UUID `db:"organization_id" json:"organization_id"`
+	OrganizationName string    `db:"organization_name" json:"organization_name"`
+	TemplateID       uuid.UUID `db:"template_id" json:"template_id"`
+	Cost             float64   `db:"cost" json:"cost"`
+}
+
+// GetWorkspaceDailyCosts returns the daily costs of workspaces in the buckets
+// that start within the given period, along with the names of the workspaces,
+// their owners and their organizations. The result can be filtered on
+// template_ids, meaning only the costs of workspaces based on those templates
+// will be included.
+func (q *sqlQuerier) GetWorkspaceDailyCosts(ctx context.Context, arg GetWorkspaceDailyCostsParams) ([]GetWorkspaceDailyCostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceDailyCosts, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceDailyCostsRow
+	for rows.Next() {
+		var i GetWorkspaceDailyCostsRow
+		if err := rows.Scan(
+			&i.StartTime,
+			&i.EndTime,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OwnerUsername,
+			&i.OrganizationID,
+			&i.OrganizationName,
+			&i.TemplateID,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceDailyCosts = `-- name: UpsertWorkspaceDailyCosts :exec
+WITH
+	latest_start AS (
+		SELECT
+			COALESCE(
+				MAX(start_time) - '1 day'::interval,
+				-- Fallback when there are no daily costs yet.
+				date_trunc('day', (
+					SELECT MIN(completed_at) FROM provisioner_jobs WHERE type = 'workspace_build'
+				) AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+			) AS t
+		FROM
+			workspace_daily_costs
+	),
+	buckets AS (
+		SELECT
+			-- Buckets are UTC days regardless of the time zone of the
+			-- database session.
+			generate_series(
+				(SELECT t FROM latest_start),
+				date_trunc('day', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+				'1 day'::interval
+			) AS start_time
+	),
+	-- Only the builds that are current at or after latest_start are
+	-- prorated: those completed since, and the latest one completed before
+	-- it for each workspace. This keeps the window below from sorting the
+	-- whole build history on every run.
+	current_builds AS (
+		SELECT
+			wb.workspace_id,
+			wb.build_number,
+			wb.job_id,
+			wb.transition,
+			pj.completed_at
+		FROM
+			workspace_builds wb
+		JOIN
+			provisioner_jobs pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			pj.completed_at >= (SELECT t FROM latest_start)
+			AND pj.canceled_at IS NULL
+			AND (pj.error IS NULL OR pj.error = '')
+		UNION ALL
+		SELECT
+			prev.workspace_id, prev.build_number, prev.job_id, prev.transition, prev.completed_at
+		FROM
+			workspaces w
+		CROSS JOIN LATERAL (
+			SELECT
+				wb.workspace_id,
+				wb.build_number,
+				wb.job_id,
+				wb.transition,
+				pj.completed_at
+			FROM
+				workspace_builds wb
+			JOIN
+				provisioner_jobs pj
+			ON
+				pj.id = wb.job_id
+			WHERE
+				wb.workspace_id = w.id
+				AND pj.completed_at < (SELECT t FROM latest_start)
+				AND pj.canceled_at IS NULL
+				AND (pj.error IS NULL OR pj.error = '')
+			ORDER BY
+				wb.build_number DESC
+			LIMIT 1
+		) prev
+	),
+	build_costs AS (
+		SELECT
+			cb.workspace_id,
+			w.organization_id,
+			w.owner_id,
+			w.template_id,
+			-- Deleted workspaces don't cost anything, whatever resources
+			-- remain in the state.
+			CASE WHEN cb.transition = 'delete' THEN 0 ELSE (
+				SELECT COALESCE(SUM(wr.daily_cost), 0) FROM workspace_resources wr WHERE wr.job_id = cb.job_id
+			) END AS daily_cost,
+			cb.completed_at AS current_from,
+			COALESCE(
+				LEAD(cb.completed_at) OVER (PARTITION BY cb.workspace_id ORDER BY cb.build_number),
+				NOW()
+			) AS current_until
+		FROM
+			current_builds cb
+		JOIN
+			workspaces w
+		ON
+			w.id = cb.workspace_id
+	)
+INSERT INTO workspace_daily_costs AS wdc (
+	start_time,
+	end_time,
+	workspace_id,
+	organization_id,
+	owner_id,
+	template_id,
+	cost
+)
+SELECT
+	b.start_time,
+	b.start_time + '1 day'::interval AS end_time,
+	bc.workspace_id,
+	bc.organization_id,
+	bc.owner_id,
+	bc.template_id,
+	SUM(
+		bc.daily_cost * EXTRACT(EPOCH FROM (
+			LEAST(bc.current_until, b.start_time + '1 day'::interval) - GREATEST(bc.current_from, b.start_time)
+		)) / 86400
+	)::double precision AS cost
+FROM
+	build_costs bc
+JOIN
+	buckets b
+ON
+	bc.current_from < b.start_time + '1 day'::interval
+	AND bc.current_until > b.start_time
+WHERE
+	bc.daily_cost > 0
+GROUP BY
+	b.start_time, bc.workspace_id, bc.organization_id, bc.owner_id, bc.template_id
+ON CONFLICT
+	(start_time, workspace_id)
+DO UPDATE
+SET
+	organization_id = EXCLUDED.organization_id,
+	owner_id = EXCLUDED.owner_id,
+	template_id = EXCLUDED.template_id,
+	cost = EXCLUDED.cost
+WHERE
+	(wdc.organization_id, wdc.owner_id, wdc.template_id, wdc.cost)
+	IS DISTINCT FROM
+	(EXCLUDED.organization_id, EXCLUDED.owner_id, EXCLUDED.template_id, EXCLUDED.cost)
+`
+
+// This query prorates the daily cost of the resources of each successful
+// workspace build over the time it was the latest successful build of its
+// workspace, and stores the cost accrued by each workspace in daily buckets
+// in the workspace_daily_costs table. The most recent bucket and the one
+// before it are recomputed on every run, since costs keep accruing in them.
+func (q *sqlQuerier) UpsertWorkspaceDailyCosts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceDailyCosts)
+	return err
+}
+
+const getWorkspaceDriftCheckCandidates = `-- name: GetWorkspaceDriftCheckCandidates :many
+SELECT
+	workspaces.id AS workspace_id,
+	workspaces.owner_id,
+	workspaces.organization_id,
+	workspace_latest_builds.id AS workspace_build_id,
+	workspace_latest_builds.template_version_id,
+	build_jobs.tags,
+	template_version_jobs.file_id,
+	template_version_jobs.provisioner
+FROM
+	workspaces
+	INNER JOIN workspace_latest_builds ON workspace_latest_builds.workspace_id = workspaces.id
+	INNER JOIN provisioner_jobs build_jobs ON build_jobs.id = workspace_latest_builds.job_id
+	INNER JOIN template_versions ON template_versions.id = workspace_latest_builds.template_version_id
+	INNER JOIN provisioner_jobs template_version_jobs ON template_version_jobs.id = template_versions.job_id
+	LEFT JOIN workspace_drift_checks ON workspace_drift_checks.workspace_id = workspaces.id
+WHERE
+	workspaces.deleted = false
+	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+	AND (
+		(
+			workspace_latest_builds.transition = 'stop'::workspace_transition
+			AND workspace_latest_builds.job_status = 'succeeded'::provisioner_job_status
+		)
+		OR (
+			workspace_latest_builds.transition != 'delete'::workspace_transition
+			AND workspace_latest_builds.job_status = 'failed'::provisioner_job_status
+		)
+	)
+	AND (
+		workspace_drift_checks.workspace_id IS NULL
+		OR workspace_drift_checks.created_at < $1
+	)
+ORDER BY
+	workspace_drift_checks.created_at ASC NULLS FIRST
+LIMIT
+	$2 :: int
+`
+
+type GetWorkspaceDriftCheckCandidatesParams struct {
+	CheckedBefore time.Time `db:"checked_before" json:"checked_before"`
+	LimitCount    int32     `db:"limit_count" json:"limit_count"`
+}
+
+type GetWorkspaceDriftCheckCandidatesRow struct {
+	WorkspaceID       uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	OwnerID           uuid.UUID       `db:"owner_id" json:"owner_id"`
+	OrganizationID    uuid.UUID       `db:"organization_id" json:"organization_id"`
+	WorkspaceBuildID  uuid.UUID       `db:"workspace_build_id" json:"workspace_build_id"`
+	TemplateVersionID uuid.UUID       `db:"template_version_id" json:"template_version_id"`
+	Tags              StringMap       `db:"tags" json:"tags"`
+	FileID            uuid.UUID       `db:"file_id" json:"file_id"`
+	Provisioner       ProvisionerType `db:"provisioner" json:"provisioner"`
+}
+
+// Returns the latest builds of stopped or failed workspaces that have not been
+// checked for drift since @checked_before, least recently checked first.
+func (q *sqlQuerier) GetWorkspaceDriftCheckCandidates(ctx context.Context, arg GetWorkspaceDriftCheckCandidatesParams) ([]GetWorkspaceDriftCheckCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceDriftCheckCandidates, arg.CheckedBefore, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceDriftCheckCandidatesRow
+	for rows.Next() {
+		var i GetWorkspaceDriftCheckCandidatesRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.OwnerID,
+			&i.OrganizationID,
+			&i.WorkspaceBuildID,
+			&i.TemplateVersionID,
+			&i.Tags,
+			&i.FileID,
+			&i.Provisioner,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceDriftChecksByWorkspaceIDs = `-- name: GetWorkspaceDriftChecksByWorkspaceIDs :many
+SELECT
+	workspace_drift_checks.workspace_id, workspace_drift_checks.workspace_build_id, workspace_drift_checks.job_id, workspace_drift_checks.created_at, workspace_drift_checks.completed_at, workspace_drift_checks.drifted_resources, workspace_drift_checks.orphaned_resources,
+	provisioner_jobs.job_status
+FROM
+	workspace_drift_checks
+	INNER JOIN provisioner_jobs ON provisioner_jobs.id = workspace_drift_checks.job_id
+WHERE
+	workspace_drift_checks.workspace_id = ANY($1 :: uuid[])
+`
+
+type GetWorkspaceDriftChecksByWorkspaceIDsRow struct {
+	WorkspaceID       uuid.UUID            `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID  uuid.UUID            `db:"workspace_build_id" json:"workspace_build_id"`
+	JobID             uuid.UUID            `db:"job_id" json:"job_id"`
+	CreatedAt         time.Time            `db:"created_at" json:"created_at"`
+	CompletedAt       sql.NullTime         `db:"completed_at" json:"completed_at"`
+	DriftedResources  []string             `db:"drifted_resources" json:"drifted_resources"`
+	OrphanedResources []string             `db:"orphaned_resources" json:"orphaned_resources"`
+	JobStatus         ProvisionerJobStatus `db:"job_status" json:"job_status"`
+}
+
+func (q *sqlQuerier) GetWorkspaceDriftChecksByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetWorkspaceDriftChecksByWorkspaceIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceDriftChecksByWorkspaceIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceDriftChecksByWorkspaceIDsRow
+	for rows.Next() {
+		var i GetWorkspaceDriftChecksByWorkspaceIDsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.WorkspaceBuildID,
+			&i.JobID,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			pq.Array(&i.DriftedResources),
+			pq.Array(&i.OrphanedResources),
+			&i.JobStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWorkspaceDriftCheckByJobID = `-- name: UpdateWorkspaceDriftCheckByJobID :one
+UPDATE
+	workspace_drift_checks
+SET
+	completed_at = $1,
+	drifted_resources = $2 :: text[],
+	orphaned_resources = $3 :: text[]
+WHERE
+	job_id = $4
+RETURNING
+	workspace_id, workspace_build_id, job_id, created_at, completed_at, drifted_resources, orphaned_resources
+`
+
+type UpdateWorkspaceDriftCheckByJobIDParams struct {
+	CompletedAt       sql.NullTime `db:"completed_at" json:"completed_at"`
+	DriftedResources  []string     `db:"drifted_resources" json:"drifted_resources"`
+	OrphanedResources []string     `db:"orphaned_resources" json:"orphaned_resources"`
+	JobID             uuid.UUID    `db:"job_id" json:"job_id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceDriftCheckByJobID(ctx context.Context, arg UpdateWorkspaceDriftCheckByJobIDParams) (WorkspaceDriftCheck, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceDriftCheckByJobID,
+		arg.CompletedAt,
+		pq.Array(arg.DriftedResources),
+		pq.Array(arg.OrphanedResources),
+		arg.JobID,
+	)
+	var i WorkspaceDriftCheck
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.JobID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		pq.Array(&i.DriftedResources),
+		pq.Array(&i.OrphanedResources),
+	)
+	return i, err
+}
+
+const upsertWorkspaceDriftCheck = `-- name: UpsertWorkspaceDriftCheck :exec
+INSERT INTO
+	workspace_drift_checks (
+		workspace_id,
+		workspace_build_id,
+		job_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	workspace_build_id = EXCLUDED.workspace_build_id,
+	job_id = EXCLUDED.job_id,
+	created_at = EXCLUDED.created_at,
+	completed_at = NULL,
+	drifted_resources = '{}'::text[],
+	orphaned_resources = '{}'::text[]
+`
+
+type UpsertWorkspaceDriftCheckParams struct {
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	JobID            uuid.UUID `db:"job_id" json:"job_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
+
+// Replaces the drift check of the workspace with a new check, clearing the
+// results of the previous one.
+func (q *sqlQuerier) UpsertWorkspaceDriftCheck(ctx context.Context, arg UpsertWorkspaceDriftCheckParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceDriftCheck,
+		arg.WorkspaceID,
+		arg.WorkspaceBuildID,
+		arg.JobID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const deleteWorkspaceLabelsByWorkspaceID = `-- name: DeleteWorkspaceLabelsByWorkspaceID :exec
+DELETE FROM
+	workspace_labels
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceLabelsByWorkspaceID, workspaceID)
+	return err
+}
+
+const getWorkspaceLabelsByWorkspaceID = `-- name: GetWorkspaceLabelsByWorkspaceID :many
+SELECT
+	workspace_id, key, value, created_at
+FROM
+	workspace_labels
+WHERE
+	workspace_id = $1
+ORDER BY
+	key ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceLabel, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceLabelsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceLabel
+	for rows.Next() {
+		var i WorkspaceLabel
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.Key,
+			&i.Value,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceLabels = `-- name: InsertWorkspaceLabels :exec
+INSERT INTO
+	workspace_labels (workspace_id, key, value, created_at)
+SELECT
+	$1,
+	unnest($2 :: TEXT[]),
+	unnest($3 :: TEXT[]),
+	$4
+`
+
+type InsertWorkspaceLabelsParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Keys        []string  `db:"keys" json:"keys"`
+	Values      []string  `db:"values" json:"values"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceLabels(ctx context.Context, arg InsertWorkspaceLabelsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceLabels,
+		arg.WorkspaceID,
+		pq.Array(arg.Keys),
+		pq.Array(arg.Values),
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const getActiveWorkspaceLockByWorkspaceID = `-- name: GetActiveWorkspaceLockByWorkspaceID :one
+SELECT
+	id, workspace_id, reason, locked_by, locked_at, unlocked_by, unlocked_at
+FROM
+	workspace_locks
+WHERE
+	workspace_id = $1
+	AND unlocked_at IS NULL
+`
+
+func (q *sqlQuerier) GetActiveWorkspaceLockByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceLock, error) {
+	row := q.db.QueryRowContext(ctx, getActiveWorkspaceLockByWorkspaceID, workspaceID)
+	var i WorkspaceLock
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Reason,
+		&i.LockedBy,
+		&i.LockedAt,
+		&i.UnlockedBy,
+		&i.UnlockedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceLocksByWorkspaceID = `-- name: GetWorkspaceLocksByWorkspaceID :many
+SELECT
+	id, workspace_id, reason, locked_by, locked_at, unlocked_by, unlocked_at
+FROM
+	workspace_locks
+WHERE
+	workspace_id = $1
+ORDER BY
+	locked_at DESC
+`
+
+// Returns the current and lifted locks of a workspace, newest first.
+func (q *sqlQuerier) GetWorkspaceLocksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceLock, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceLocksByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceLock
+	for rows.Next() {
+		var i WorkspaceLock
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.Reason,
+			&i.LockedBy,
+			&i.LockedAt,
+			&i.UnlockedBy,
+			&i.UnlockedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceLock = `-- name: InsertWorkspaceLock :one
+INSERT INTO
+	workspace_locks (id, workspace_id, reason, locked_by, locked_at)
+VALUES
+	($1, $2, $3, $4, $5)
+RETURNING id, workspace_id, reason, locked_by, locked_at, unlocked_by, unlocked_at
+`
+
+type InsertWorkspaceLockParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Reason      string    `db:"reason" json:"reason"`
+	LockedBy    uuid.UUID `db:"locked_by" json:"locked_by"`
+	LockedAt    time.Time `db:"locked_at" json:"locked_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceLock(ctx context.Context, arg InsertWorkspaceLockParams) (WorkspaceLock, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceLock,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.Reason,
+		arg.LockedBy,
+		arg.LockedAt,
+	)
+	var i WorkspaceLock
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Reason,
+		&i.LockedBy,
+		&i.LockedAt,
+		&i.UnlockedBy,
+		&i.UnlockedAt,
+	)
+	return i, err
+}
+
+const unlockWorkspace = `-- name: UnlockWorkspace :one
+UPDATE
+	workspace_locks
+SET
+	unlocked_by = $1,
+	unlocked_at = $2
+WHERE
+	workspace_id = $3
+	AND unlocked_at IS NULL
+RETURNING id, workspace_id, reason, locked_by, locked_at, unlocked_by, unlocked_at
+`
+
+type UnlockWorkspaceParams struct {
+	UnlockedBy  uuid.NullUUID `db:"unlocked_by" json:"unlocked_by"`
+	UnlockedAt  sql.NullTime  `db:"unlocked_at" json:"unlocked_at"`
+	WorkspaceID uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+}
+
+func (q *sqlQuerier) UnlockWorkspace(ctx context.Context, arg UnlockWorkspaceParams) (WorkspaceLock, error) {
+	row := q.db.QueryRowContext(ctx, unlockWorkspace, arg.UnlockedBy, arg.UnlockedAt, arg.WorkspaceID)
+	var i WorkspaceLock
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Reason,
+		&i.LockedBy,
+		&i.LockedAt,
+		&i.UnlockedBy,
+		&i.UnlockedAt,
+	)
+	return i, err
+}
+
+const completeWorkspaceMigration = `-- name: CompleteWorkspaceMigration :one
+UPDATE
+	workspace_migrations
+SET
+	status = $1,
+	error = $2,
+	destination_session_token = '',
+	destination_session_token_key_id = NULL,
+	updated_at = $3,
+	completed_at = $3
+WHERE
+	id = $4
+RETURNING id, workspace_id, initiator_id, status, destination_url, destination_organization_id, destination_owner, destination_template_id, destination_name, destination_session_token, destination_session_token_key_id, source_build_id, destination_workspace_id, destination_build_id, error, created_at, updated_at, completed_at
+`
+
+type CompleteWorkspaceMigrationParams struct {
+	Status      WorkspaceMigrationStatus `db:"status" json:"status"`
+	Error       string                   `db:"error" json:"error"`
+	CompletedAt sql.NullTime             `db:"completed_at" json:"completed_at"`
+	ID          uuid.UUID                `db:"id" json:"id"`
+}
+
+// The destination session token is only needed while the migration runs, so
+// it is cleared once the migration completes.
+func (q *sqlQuerier) CompleteWorkspaceMigration(ctx context.Context, arg CompleteWorkspaceMigrationParams) (WorkspaceMigration, error) {
+	row := q.db.QueryRowContext(ctx, completeWorkspaceMigration,
+		arg.Status,
+		arg.Error,
+		arg.CompletedAt,
+		arg.ID,
+	)
+	var i WorkspaceMigration
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.InitiatorID,
+		&i.Status,
+		&i.DestinationURL,
+		&i.DestinationOrganizationID,
+		&i.DestinationOwner,
+		&i.DestinationTemplateID,
+		&i.DestinationName,
+		&i.DestinationSessionToken,
+		&i.DestinationSessionTokenKeyID,
+		&i.SourceBuildID,
+		&i.DestinationWorkspaceID,
+		&i.DestinationBuildID,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getActiveWorkspaceMigrations = `-- name: GetActiveWorkspaceMigrations :many
+SELECT
+	id, workspace_id, initiator_id, status, destination_url, destination_organization_id, destination_owner, destination_template_id, destination_name, destination_session_token, destination_session_token_key_id, source_build_id, destination_workspace_id, destination_build_id, error, created_at, updated_at, completed_at
+FROM
+	workspace_migrations
+WHERE
+	completed_at IS NULL
+ORDER BY
+	created_at ASC
+`
+
+// Returns the migrations that have not completed, oldest first.
+func (q *sqlQuerier) GetActiveWorkspaceMigrations(ctx context.Context) ([]WorkspaceMigration, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveWorkspaceMigrations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceMigration
+	for rows.Next() {
+		var i WorkspaceMigration
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.InitiatorID,
+			&i.Status,
+			&i.DestinationURL,
+			&i.DestinationOrganizationID,
+			&i.DestinationOwner,
+			&i.DestinationTemplateID,
+			&i.DestinationName,
+			&i.DestinationSessionToken,
+			&i.DestinationSessionTokenKeyID,
+			&i.SourceBuildID,
+			&i.DestinationWorkspaceID,
+			&i.DestinationBuildID,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceMigrationByID = `-- name: GetWorkspaceMigrationByID :one
+SELECT
+	id, workspace_id, initiator_id, status, destination_url, destination_organization_id, destination_owner, destination_template_id, destination_name, destination_session_token, destination_session_token_key_id, source_build_id, destination_workspace_id, destination_build_id, error, created_at, updated_at, completed_at
+FROM
+	workspace_migrations
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceMigrationByID(ctx context.Context, id uuid.UUID) (WorkspaceMigration, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceMigrationByID, id)
+	var i WorkspaceMigration
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.InitiatorID,
+		&i.Status,
+		&i.DestinationURL,
+		&i.DestinationOrganizationID,
+		&i.DestinationOwner,
+		&i.DestinationTemplateID,
+		&i.DestinationName,
+		&i.DestinationSessionToken,
+		&i.DestinationSessionTokenKeyID,
+		&i.SourceBuildID,
+		&i.DestinationWorkspaceID,
+		&i.DestinationBuildID,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceMigrationsByWorkspaceID = `-- name: GetWorkspaceMigrationsByWorkspaceID :many
+SELECT
+	id, workspace_id, initiator_id, status, destination_url, destination_organization_id, destination_owner, destination_template_id, destination_name, destination_session_token, destination_session_token_key_id, source_build_id, destination_workspace_id, destination_build_id, error, created_at, updated_at, completed_at
+FROM
+	workspace_migrations
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceMigrationsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceMigration, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceMigrationsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceMigration
+	for rows.Next() {
+		var i WorkspaceMigration
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.InitiatorID,
+			&i.Status,
+			&i.DestinationURL,
+			&i.DestinationOrganizationID,
+			&i.DestinationOwner,
+			&i.DestinationTemplateID,
+			&i.DestinationName,
+			&i.DestinationSessionToken,
+			&i.DestinationSessionTokenKeyID,
+			&i.SourceBuildID,
+			&i.DestinationWorkspaceID,
+			&i.DestinationBuildID,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceMigration = `-- name: InsertWorkspaceMigration :one
+INSERT INTO
+	workspace_migrations (
+		id,
+		workspace_id,
+		initiator_id,
+		destination_url,
+		destination_organization_id,
+		destination_owner,
+		destination_template_id,
+		destination_name,
+		destination_session_token,
+		destination_session_token_key_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, workspace_id, initiator_id, status, destination_url, destination_organization_id, destination_owner, destination_template_id, destination_name, destination_session_token, destination_session_token_key_id, source_build_id, destination_workspace_id, destination_build_id, error, created_at, updated_at, completed_at
+`
+
+type InsertWorkspaceMigrationParams struct {
+	ID                           uuid.UUID      `db:"id" json:"id"`
+	WorkspaceID                  uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	InitiatorID                  uuid.UUID      `db:"initiator_id" json:"initiator_id"`
+	DestinationURL               string         `db:"destination_url" json:"destination_url"`
+	DestinationOrganizationID    uuid.UUID      `db:"destination_organization_id" json:"destination_organization_id"`
+	DestinationOwner             string         `db:"destination_owner" json:"destination_owner"`
+	DestinationTemplateID        uuid.NullUUID  `db:"destination_template_id" json:"destination_template_id"`
+	DestinationName              string         `db:"destination_name" json:"destination_name"`
+	DestinationSessionToken      string         `db:"destination_session_token" json:"destination_session_token"`
+	DestinationSessionTokenKeyID sql.NullString `db:"destination_session_token_key_id" json:"destination_session_token_key_id"`
+	CreatedAt                    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt                    time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceMigration(ctx context.Context, arg InsertWorkspaceMigrationParams) (WorkspaceMigration, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceMigration,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.InitiatorID,
+		arg.DestinationURL,
+		arg.DestinationOrganizationID,
+		arg.DestinationOwner,
+		arg.DestinationTemplateID,
+		arg.DestinationName,
+		arg.DestinationSessionToken,
+		arg.DestinationSessionTokenKeyID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i WorkspaceMigration
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.InitiatorID,
+		&i.Status,
+		&i.DestinationURL,
+		&i.DestinationOrganizationID,
+		&i.DestinationOwner,
+		&i.DestinationTemplateID,
+		&i.DestinationName,
+		&i.DestinationSessionToken,
+		&i.DestinationSessionTokenKeyID,
+		&i.SourceBuildID,
+		&i.DestinationWorkspaceID,
+		&i.DestinationBuildID,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const updateWorkspaceMigrationSessionToken = `-- name: UpdateWorkspaceMigrationSessionToken :exec
+UPDATE
+	workspace_migrations
+SET
+	destination_session_token = $1,
+	destination_session_token_key_id = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceMigrationSessionTokenParams struct {
+	DestinationSessionToken      string         `db:"destination_session_token" json:"destination_session_token"`
+	DestinationSessionTokenKeyID sql.NullString `db:"destination_session_token_key_id" json:"destination_session_token_key_id"`
+	ID                           uuid.UUID      `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceMigrationSessionToken(ctx context.Context, arg UpdateWorkspaceMigrationSessionTokenParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceMigrationSessionToken, arg.DestinationSessionToken, arg.DestinationSessionTokenKeyID, arg.ID)
+	return err
+}
+
+const updateWorkspaceMigrationStatus = `-- name: UpdateWorkspaceMigrationStatus :one
+UPDATE
+	workspace_migrations
+SET
+	status = $1,
+	source_build_id = $2,
+	destination_workspace_id = $3,
+	destination_build_id = $4,
+	updated_at = $5
+WHERE
+	id = $6
+RETURNING id, workspace_id, initiator_id, status, destination_url, destination_organization_id, destination_owner, destination_template_id, destination_name, destination_session_token, destination_session_token_key_id, source_build_id, destination_workspace_id, destination_build_id, error, created_at, updated_at, completed_at
+`
+
+type UpdateWorkspaceMigrationStatusParams struct {
+	Status                 WorkspaceMigrationStatus `db:"status" json:"status"`
+	SourceBuildID          uuid.NullUUID            `db:"source_build_id" json:"source_build_id"`
+	DestinationWorkspaceID uuid.NullUUID            `db:"destination_workspace_id" json:"destination_workspace_id"`
+	DestinationBuildID     uuid.NullUUID            `db:"destination_build_id" json:"destination_build_id"`
+	UpdatedAt              time.Time                `db:"updated_at" json:"updated_at"`
+	ID                     uuid.UUID                `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceMigrationStatus(ctx context.Context, arg UpdateWorkspaceMigrationStatusParams) (WorkspaceMigration, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceMigrationStatus,
+		arg.Status,
+		arg.SourceBuildID,
+		arg.DestinationWorkspaceID,
+		arg.DestinationBuildID,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i WorkspaceMigration
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.InitiatorID,
+		&i.Status,
+		&i.DestinationURL,
+		&i.DestinationOrganizationID,
+		&i.DestinationOwner,
+		&i.DestinationTemplateID,
+		&i.DestinationName,
+		&i.DestinationSessionToken,
+		&i.DestinationSessionTokenKeyID,
+		&i.SourceBuildID,
+		&i.DestinationWorkspaceID,
+		&i.DestinationBuildID,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
 const getWorkspaceModulesByJobID = `-- name: GetWorkspaceModulesByJobID :many
 SELECT
 	id, job_id, transition, source, version, key, created_at
@@ -17804,9 +24237,269 @@ func (q *sqlQuerier) InsertWorkspaceModule(ctx context.Context, arg InsertWorksp
 	return i, err
 }
 
+const getOrganizationWorkspaceNamingPolicy = `-- name: GetOrganizationWorkspaceNamingPolicy :one
+SELECT
+	id, organization_id, template_id, regex, prefix, suffix, uniqueness_scope, created_at, updated_at
+FROM
+	workspace_naming_policies
+WHERE
+	organization_id = $1
+	AND template_id IS NULL
+`
+
+func (q *sqlQuerier) GetOrganizationWorkspaceNamingPolicy(ctx context.Context, organizationID uuid.UUID) (WorkspaceNamingPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationWorkspaceNamingPolicy, organizationID)
+	var i WorkspaceNamingPolicy
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Regex,
+		&i.Prefix,
+		&i.Suffix,
+		&i.UniquenessScope,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateWorkspaceNamingPolicy = `-- name: GetTemplateWorkspaceNamingPolicy :one
+SELECT
+	id, organization_id, template_id, regex, prefix, suffix, uniqueness_scope, created_at, updated_at
+FROM
+	workspace_naming_policies
+WHERE
+	template_id = $1::uuid
+`
+
+func (q *sqlQuerier) GetTemplateWorkspaceNamingPolicy(ctx context.Context, templateID uuid.UUID) (WorkspaceNamingPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateWorkspaceNamingPolicy, templateID)
+	var i WorkspaceNamingPolicy
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Regex,
+		&i.Prefix,
+		&i.Suffix,
+		&i.UniquenessScope,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertOrganizationWorkspaceNamingPolicy = `-- name: UpsertOrganizationWorkspaceNamingPolicy :one
+INSERT INTO
+	workspace_naming_policies (
+		id,
+		organization_id,
+		regex,
+		prefix,
+		suffix,
+		uniqueness_scope,
+		created_at,
+		updated_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$7
+)
+ON CONFLICT (organization_id) WHERE template_id IS NULL
+DO UPDATE SET
+	regex = $3,
+	prefix = $4,
+	suffix = $5,
+	uniqueness_scope = $6,
+	updated_at = $7
+RETURNING id, organization_id, template_id, regex, prefix, suffix, uniqueness_scope, created_at, updated_at
+`
+
+type UpsertOrganizationWorkspaceNamingPolicyParams struct {
+	ID              uuid.UUID                    `db:"id" json:"id"`
+	OrganizationID  uuid.UUID                    `db:"organization_id" json:"organization_id"`
+	Regex           string                       `db:"regex" json:"regex"`
+	Prefix          string                       `db:"prefix" json:"prefix"`
+	Suffix          string                       `db:"suffix" json:"suffix"`
+	UniquenessScope WorkspaceNameUniquenessScope `db:"uniqueness_scope" json:"uniqueness_scope"`
+	UpdatedAt       time.Time                    `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertOrganizationWorkspaceNamingPolicy(ctx context.Context, arg UpsertOrganizationWorkspaceNamingPolicyParams) (WorkspaceNamingPolicy, error) {
+	row := q.db.QueryRowContext(ctx, upsertOrganizationWorkspaceNamingPolicy,
+		arg.ID,
+		arg.OrganizationID,
+		arg.Regex,
+		arg.Prefix,
+		arg.Suffix,
+		arg.UniquenessScope,
+		arg.UpdatedAt,
+	)
+	var i WorkspaceNamingPolicy
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Regex,
+		&i.Prefix,
+		&i.Suffix,
+		&i.UniquenessScope,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTemplateWorkspaceNamingPolicy = `-- name: UpsertTemplateWorkspaceNamingPolicy :one
+INSERT INTO
+	workspace_naming_policies (
+		id,
+		organization_id,
+		template_id,
+		regex,
+		prefix,
+		suffix,
+		uniqueness_scope,
+		created_at,
+		updated_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3::uuid,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$8
+)
+ON CONFLICT (template_id) WHERE template_id IS NOT NULL
+DO UPDATE SET
+	regex = $4,
+	prefix = $5,
+	suffix = $6,
+	uniqueness_scope = $7,
+	updated_at = $8
+RETURNING id, organization_id, template_id, regex, prefix, suffix, uniqueness_scope, created_at, updated_at
+`
+
+type UpsertTemplateWorkspaceNamingPolicyParams struct {
+	ID              uuid.UUID                    `db:"id" json:"id"`
+	OrganizationID  uuid.UUID                    `db:"organization_id" json:"organization_id"`
+	TemplateID      uuid.UUID                    `db:"template_id" json:"template_id"`
+	Regex           string                       `db:"regex" json:"regex"`
+	Prefix          string                       `db:"prefix" json:"prefix"`
+	Suffix          string                       `db:"suffix" json:"suffix"`
+	UniquenessScope WorkspaceNameUniquenessScope `db:"uniqueness_scope" json:"uniqueness_scope"`
+	UpdatedAt       time.Time                    `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertTemplateWorkspaceNamingPolicy(ctx context.Context, arg UpsertTemplateWorkspaceNamingPolicyParams) (WorkspaceNamingPolicy, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateWorkspaceNamingPolicy,
+		arg.ID,
+		arg.OrganizationID,
+		arg.TemplateID,
+		arg.Regex,
+		arg.Prefix,
+		arg.Suffix,
+		arg.UniquenessScope,
+		arg.UpdatedAt,
+	)
+	var i WorkspaceNamingPolicy
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Regex,
+		&i.Prefix,
+		&i.Suffix,
+		&i.UniquenessScope,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceProvisionerAffinity = `-- name: GetWorkspaceProvisionerAffinity :one
+SELECT
+	workspace_id, provisioner_daemon_id, template_version_id, updated_at
+FROM
+	workspace_provisioner_affinities
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceProvisionerAffinity(ctx context.Context, workspaceID uuid.UUID) (WorkspaceProvisionerAffinity, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProvisionerAffinity, workspaceID)
+	var i WorkspaceProvisionerAffinity
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.ProvisionerDaemonID,
+		&i.TemplateVersionID,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertWorkspaceProvisionerAffinity = `-- name: UpsertWorkspaceProvisionerAffinity :exec
+INSERT INTO
+	workspace_provisioner_affinities (
+		workspace_id,
+		provisioner_daemon_id,
+		template_version_id,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	provisioner_daemon_id = EXCLUDED.provisioner_daemon_id,
+	template_version_id = EXCLUDED.template_version_id,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertWorkspaceProvisionerAffinityParams struct {
+	WorkspaceID         uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	ProvisionerDaemonID uuid.UUID `db:"provisioner_daemon_id" json:"provisioner_daemon_id"`
+	TemplateVersionID   uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	UpdatedAt           time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Records the provisioner daemon that built a workspace successfully, so
+// the next build of the same template version prefers it.
+func (q *sqlQuerier) UpsertWorkspaceProvisionerAffinity(ctx context.Context, arg UpsertWorkspaceProvisionerAffinityParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceProvisionerAffinity,
+		arg.WorkspaceID,
+		arg.ProvisionerDaemonID,
+		arg.TemplateVersionID,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const deleteWorkspaceResourceMetadataByResourceIDs = `-- name: DeleteWorkspaceResourceMetadataByResourceIDs :exec
+DELETE FROM
+	workspace_resource_metadata
+WHERE
+	workspace_resource_id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) DeleteWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceResourceMetadataByResourceIDs, pq.Array(ids))
+	return err
+}
+
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, display_group, display_order, collapsed
 FROM
 	workspace_resources
 WHERE
@@ -17828,6 +24521,9 @@ func (q *sqlQuerier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID)
 		&i.InstanceType,
 		&i.DailyCost,
 		&i.ModulePath,
+		&i.DisplayGroup,
+		&i.DisplayOrder,
+		&i.Collapsed,
 	)
 	return i, err
 }
@@ -17907,7 +24603,7 @@ func (q *sqlQuerier) GetWorkspaceResourceMetadataCreatedAfter(ctx context.Contex
 
 const getWorkspaceResourcesByJobID = `-- name: GetWorkspaceResourcesByJobID :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, display_group, display_order, collapsed
 FROM
 	workspace_resources
 WHERE
@@ -17935,6 +24631,9 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 			&i.InstanceType,
 			&i.DailyCost,
 			&i.ModulePath,
+			&i.DisplayGroup,
+			&i.DisplayOrder,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -17951,7 +24650,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 
 const getWorkspaceResourcesByJobIDs = `-- name: GetWorkspaceResourcesByJobIDs :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, display_group, display_order, collapsed
 FROM
 	workspace_resources
 WHERE
@@ -17979,6 +24678,9 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 			&i.InstanceType,
 			&i.DailyCost,
 			&i.ModulePath,
+			&i.DisplayGroup,
+			&i.DisplayOrder,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -17994,7 +24696,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 }
 
 const getWorkspaceResourcesCreatedAfter = `-- name: GetWorkspaceResourcesCreatedAfter :many
-SELECT id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path FROM workspace_resources WHERE created_at > $1
+SELECT id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, display_group, display_order, collapsed FROM workspace_resources WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error) {
@@ -18018,6 +24720,9 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 			&i.InstanceType,
 			&i.DailyCost,
 			&i.ModulePath,
+			&i.DisplayGroup,
+			&i.DisplayOrder,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -18034,9 +24739,9 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 
 const insertWorkspaceResource = `-- name: InsertWorkspaceResource :one
 INSERT INTO
-	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path)
+	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, display_group, display_order, collapsed)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, display_group, display_order, collapsed
 `
 
 type InsertWorkspaceResourceParams struct {
@@ -18051,6 +24756,9 @@ type InsertWorkspaceResourceParams struct {
 	InstanceType sql.NullString      `db:"instance_type" json:"instance_type"`
 	DailyCost    int32               `db:"daily_cost" json:"daily_cost"`
 	ModulePath   sql.NullString      `db:"module_path" json:"module_path"`
+	DisplayGroup string              `db:"display_group" json:"display_group"`
+	DisplayOrder int32               `db:"display_order" json:"display_order"`
+	Collapsed    bool                `db:"collapsed" json:"collapsed"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error) {
@@ -18066,6 +24774,9 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		arg.InstanceType,
 		arg.DailyCost,
 		arg.ModulePath,
+		arg.DisplayGroup,
+		arg.DisplayOrder,
+		arg.Collapsed,
 	)
 	var i WorkspaceResource
 	err := row.Scan(
@@ -18080,6 +24791,9 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		&i.InstanceType,
 		&i.DailyCost,
 		&i.ModulePath,
+		&i.DisplayGroup,
+		&i.DisplayOrder,
+		&i.Collapsed,
 	)
 	return i, err
 }
@@ -18184,6 +24898,71 @@ func (q *sqlQuerier) BatchUpdateWorkspaceNextStartAt(ctx context.Context, arg Ba
 	return err
 }
 
+const deleteOldDeletedWorkspaces = `-- name: DeleteOldDeletedWorkspaces :execrows
+WITH purged_workspaces AS (
+	SELECT
+		workspaces.id
+	FROM
+		workspaces
+	JOIN LATERAL (
+		SELECT
+			workspace_builds.job_id
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+		ORDER BY
+			workspace_builds.build_number DESC
+		LIMIT 1
+	) latest_build ON TRUE
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = latest_build.job_id
+	WHERE
+		workspaces.deleted
+		AND provisioner_jobs.completed_at < $1 :: timestamptz
+),
+purged_jobs AS (
+	DELETE FROM
+		provisioner_jobs
+	WHERE
+		id IN (
+			SELECT
+				job_id
+			FROM
+				workspace_builds
+			WHERE
+				workspace_id IN (SELECT id FROM purged_workspaces)
+		)
+),
+purged_app_stats AS (
+	DELETE FROM
+		workspace_app_stats
+	WHERE
+		workspace_id IN (SELECT id FROM purged_workspaces)
+),
+purged_app_statuses AS (
+	DELETE FROM
+		workspace_app_statuses
+	WHERE
+		workspace_id IN (SELECT id FROM purged_workspaces)
+)
+DELETE FROM
+	workspaces
+WHERE
+	id IN (SELECT id FROM purged_workspaces)
+`
+
+// Permanently removes workspaces whose delete build completed before the given
+// time, along with their builds, provisioner jobs and job logs. Until then,
+// deleted workspaces can be restored.
+func (q *sqlQuerier) DeleteOldDeletedWorkspaces(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldDeletedWorkspaces, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const favoriteWorkspace = `-- name: FavoriteWorkspace :exec
 UPDATE workspaces SET favorite = true WHERE id = $1
 `
@@ -18279,7 +25058,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -18327,6 +25106,8 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -18344,7 +25125,7 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded
 WHERE
@@ -18373,6 +25154,63 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
+		&i.OwnerAvatarUrl,
+		&i.OwnerUsername,
+		&i.OwnerName,
+		&i.OrganizationName,
+		&i.OrganizationDisplayName,
+		&i.OrganizationIcon,
+		&i.OrganizationDescription,
+		&i.TemplateName,
+		&i.TemplateDisplayName,
+		&i.TemplateIcon,
+		&i.TemplateDescription,
+	)
+	return i, err
+}
+
+const getWorkspaceByOrganizationIDAndName = `-- name: GetWorkspaceByOrganizationIDAndName :one
+SELECT
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+FROM
+	workspaces_expanded as workspaces
+WHERE
+	organization_id = $1
+	AND deleted = false
+	AND LOWER("name") = LOWER($2)
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetWorkspaceByOrganizationIDAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetWorkspaceByOrganizationIDAndName(ctx context.Context, arg GetWorkspaceByOrganizationIDAndNameParams) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceByOrganizationIDAndName, arg.OrganizationID, arg.Name)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+		&i.Favorite,
+		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -18390,7 +25228,7 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -18426,6 +25264,8 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -18443,7 +25283,7 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 
 const getWorkspaceByResourceID = `-- name: GetWorkspaceByResourceID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -18486,6 +25326,8 @@ func (q *sqlQuerier) GetWorkspaceByResourceID(ctx context.Context, resourceID uu
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -18503,7 +25345,7 @@ func (q *sqlQuerier) GetWorkspaceByResourceID(ctx context.Context, resourceID uu
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -18558,6 +25400,8 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -18618,7 +25462,7 @@ SELECT
 ),
 filtered_workspaces AS (
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.owner_avatar_url, workspaces.owner_username, workspaces.owner_name, workspaces.organization_name, workspaces.organization_display_name, workspaces.organization_icon, workspaces.organization_description, workspaces.template_name, workspaces.template_display_name, workspaces.template_icon, workspaces.template_description,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.owner_avatar_url, workspaces.owner_username, workspaces.owner_name, workspaces.organization_name, workspaces.organization_display_name, workspaces.organization_icon, workspaces.organization_description, workspaces.template_name, workspaces.template_display_name, workspaces.template_icon, workspaces.template_description,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
 	latest_build.completed_at as latest_build_completed_at,
@@ -18666,7 +25510,7 @@ LEFT JOIN LATERAL (
 ) latest_build ON TRUE
 LEFT JOIN LATERAL (
 	SELECT
-		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow
+		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, max_concurrent_jobs_per_user, resource_ceilings
 	FROM
 		templates
 	WHERE
@@ -18879,32 +25723,92 @@ WHERE
 			)) = ($19 :: boolean)
 		ELSE true
 	END
+	-- Filter by workspace labels
+	-- $20 and $21 are matched by index. An empty value
+	-- matches any workspace that has the label, whatever its value.
+	AND CASE WHEN array_length($20 :: text[], 1) > 0 THEN
+		NOT EXISTS (
+			SELECT
+				1
+			FROM
+				unnest($20 :: text[], $21 :: text[]) AS wanted(key, value)
+			WHERE
+				NOT EXISTS (
+					SELECT
+						1
+					FROM
+						workspace_labels
+					WHERE
+						workspace_labels.workspace_id = workspaces.id AND
+						workspace_labels.key = wanted.key AND
+						(wanted.value = '' OR LOWER(workspace_labels.value) = wanted.value)
+				)
+		)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaces
 	-- @authorize_filter
 ), filtered_workspaces_order AS (
 	SELECT
-		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.next_start_at, fw.owner_avatar_url, fw.owner_username, fw.owner_name, fw.organization_name, fw.organization_display_name, fw.organization_icon, fw.organization_description, fw.template_name, fw.template_display_name, fw.template_icon, fw.template_description, fw.template_version_id, fw.template_version_name, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status, fw.latest_build_has_ai_task
+		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.next_start_at, fw.group_acl, fw.user_acl, fw.owner_avatar_url, fw.owner_username, fw.owner_name, fw.organization_name, fw.organization_display_name, fw.organization_icon, fw.organization_description, fw.template_name, fw.template_display_name, fw.template_icon, fw.template_description, fw.template_version_id, fw.template_version_name, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status, fw.latest_build_has_ai_task
 	FROM
 		filtered_workspaces fw
+	WHERE
+		CASE
+			-- This allows using the last workspace on a page as a cursor.
+			-- Workspaces are compared by the columns they are ordered by.
+			-- Running workspaces are ordered first, so a workspace that
+			-- starts or stops between two requests may move across the
+			-- cursor.
+			WHEN $22 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+				(
+					CASE WHEN fw.owner_id = $23 AND fw.favorite THEN 0 ELSE 1 END,
+					NOT (fw.latest_build_completed_at IS NOT NULL AND
+						fw.latest_build_canceled_at IS NULL AND
+						fw.latest_build_error IS NULL AND
+						fw.latest_build_transition = 'start'::workspace_transition),
+					LOWER(fw.owner_username),
+					LOWER(fw.name),
+					fw.id
+				) > (
+					SELECT
+						CASE WHEN cursor_fw.owner_id = $23 AND cursor_fw.favorite THEN 0 ELSE 1 END,
+						NOT (cursor_fw.latest_build_completed_at IS NOT NULL AND
+							cursor_fw.latest_build_canceled_at IS NULL AND
+							cursor_fw.latest_build_error IS NULL AND
+							cursor_fw.latest_build_transition = 'start'::workspace_transition),
+						LOWER(cursor_fw.owner_username),
+						LOWER(cursor_fw.name),
+						cursor_fw.id
+					FROM
+						filtered_workspaces cursor_fw
+					WHERE
+						cursor_fw.id = $22
+				)
+			)
+			ELSE true
+		END
 	ORDER BY
 		-- To ensure that 'favorite' workspaces show up first in the list only for their owner.
-		CASE WHEN owner_id = $20 AND favorite THEN 0 ELSE 1 END ASC,
+		CASE WHEN owner_id = $23 AND favorite THEN 0 ELSE 1 END ASC,
 		(latest_build_completed_at IS NOT NULL AND
 			latest_build_canceled_at IS NULL AND
 			latest_build_error IS NULL AND
 			latest_build_transition = 'start'::workspace_transition) DESC,
 		LOWER(owner_username) ASC,
-		LOWER(name) ASC
+		LOWER(name) ASC,
+		-- Order by ID as well, so the order is stable for the cursor.
+		id ASC
 	LIMIT
 		CASE
-			WHEN $22 :: integer > 0 THEN
-				$22
+			WHEN $25 :: integer > 0 THEN
+				$25
 		END
 	OFFSET
-		$21
+		$24
 ), filtered_workspaces_order_with_summary AS (
 	SELECT
-		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.next_start_at, fwo.owner_avatar_url, fwo.owner_username, fwo.owner_name, fwo.organization_name, fwo.organization_display_name, fwo.organization_icon, fwo.organization_description, fwo.template_name, fwo.template_display_name, fwo.template_icon, fwo.template_description, fwo.template_version_id, fwo.template_version_name, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status, fwo.latest_build_has_ai_task
+		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.next_start_at, fwo.group_acl, fwo.user_acl, fwo.owner_avatar_url, fwo.owner_username, fwo.owner_name, fwo.organization_name, fwo.organization_display_name, fwo.organization_icon, fwo.organization_description, fwo.template_name, fwo.template_display_name, fwo.template_icon, fwo.template_description, fwo.template_version_id, fwo.template_version_name, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status, fwo.latest_build_has_ai_task
 	FROM
 		filtered_workspaces_order fwo
 	-- Return a technical summary row with total count of workspaces.
@@ -18927,6 +25831,8 @@ WHERE
 		'never'::automatic_updates, -- automatic_updates
 		false, -- favorite
 		'0001-01-01 00:00:00+00'::timestamptz, -- next_start_at
+		'{}'::jsonb, -- group_acl
+		'{}'::jsonb, -- user_acl
 		'', -- owner_avatar_url
 		'', -- owner_username
 		'', -- owner_name
@@ -18948,7 +25854,7 @@ WHERE
 		'unknown'::provisioner_job_status, -- latest_build_status
 		false -- latest_build_has_ai_task
 	WHERE
-		$23 :: boolean = true
+		$26 :: boolean = true
 ), total_count AS (
 	SELECT
 		count(*) AS count
@@ -18956,7 +25862,7 @@ WHERE
 		filtered_workspaces
 )
 SELECT
-	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.next_start_at, fwos.owner_avatar_url, fwos.owner_username, fwos.owner_name, fwos.organization_name, fwos.organization_display_name, fwos.organization_icon, fwos.organization_description, fwos.template_name, fwos.template_display_name, fwos.template_icon, fwos.template_description, fwos.template_version_id, fwos.template_version_name, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status, fwos.latest_build_has_ai_task,
+	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.next_start_at, fwos.group_acl, fwos.user_acl, fwos.owner_avatar_url, fwos.owner_username, fwos.owner_name, fwos.organization_name, fwos.organization_display_name, fwos.organization_icon, fwos.organization_description, fwos.template_name, fwos.template_display_name, fwos.template_icon, fwos.template_description, fwos.template_version_id, fwos.template_version_name, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status, fwos.latest_build_has_ai_task,
 	tc.count
 FROM
 	filtered_workspaces_order_with_summary fwos
@@ -18984,6 +25890,9 @@ type GetWorkspacesParams struct {
 	LastUsedAfter                         time.Time    `db:"last_used_after" json:"last_used_after"`
 	UsingActive                           sql.NullBool `db:"using_active" json:"using_active"`
 	HasAITask                             sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
+	LabelKeys                             []string     `db:"label_keys" json:"label_keys"`
+	LabelValues                           []string     `db:"label_values" json:"label_values"`
+	AfterID                               uuid.UUID    `db:"after_id" json:"after_id"`
 	RequesterID                           uuid.UUID    `db:"requester_id" json:"requester_id"`
 	Offset                                int32        `db:"offset_" json:"offset_"`
 	Limit                                 int32        `db:"limit_" json:"limit_"`
@@ -19007,6 +25916,8 @@ type GetWorkspacesRow struct {
 	AutomaticUpdates        AutomaticUpdates     `db:"automatic_updates" json:"automatic_updates"`
 	Favorite                bool                 `db:"favorite" json:"favorite"`
 	NextStartAt             sql.NullTime         `db:"next_start_at" json:"next_start_at"`
+	GroupACL                WorkspaceACL         `db:"group_acl" json:"group_acl"`
+	UserACL                 WorkspaceACL         `db:"user_acl" json:"user_acl"`
 	OwnerAvatarUrl          string               `db:"owner_avatar_url" json:"owner_avatar_url"`
 	OwnerUsername           string               `db:"owner_username" json:"owner_username"`
 	OwnerName               string               `db:"owner_name" json:"owner_name"`
@@ -19053,6 +25964,9 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 		arg.LastUsedAfter,
 		arg.UsingActive,
 		arg.HasAITask,
+		pq.Array(arg.LabelKeys),
+		pq.Array(arg.LabelValues),
+		arg.AfterID,
 		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
@@ -19082,6 +25996,8 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.GroupACL,
+			&i.UserACL,
 			&i.OwnerAvatarUrl,
 			&i.OwnerUsername,
 			&i.OwnerName,
@@ -19196,7 +26112,7 @@ func (q *sqlQuerier) GetWorkspacesAndAgentsByOwnerID(ctx context.Context, ownerI
 }
 
 const getWorkspacesByTemplateID = `-- name: GetWorkspacesByTemplateID :many
-SELECT id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at FROM workspaces WHERE template_id = $1 AND deleted = false
+SELECT id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl FROM workspaces WHERE template_id = $1 AND deleted = false
 `
 
 func (q *sqlQuerier) GetWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceTable, error) {
@@ -19225,6 +26141,8 @@ func (q *sqlQuerier) GetWorkspacesByTemplateID(ctx context.Context, templateID u
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.GroupACL,
+			&i.UserACL,
 		); err != nil {
 			return nil, err
 		}
@@ -19407,7 +26325,7 @@ INSERT INTO
 		next_start_at
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl
 `
 
 type InsertWorkspaceParams struct {
@@ -19458,6 +26376,8 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
 	)
 	return i, err
 }
@@ -19497,7 +26417,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl
 `
 
 type UpdateWorkspaceParams struct {
@@ -19525,10 +26445,71 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
+	)
+	return i, err
+}
+
+const getWorkspaceByIDForUpdate = `-- name: GetWorkspaceByIDForUpdate :one
+SELECT
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl
+FROM
+	workspaces
+WHERE
+	id = $1
+FOR UPDATE
+`
+
+// Locks the workspace until the end of the transaction, so that concurrent
+// read-modify-write updates of its ACL don't overwrite each other.
+func (q *sqlQuerier) GetWorkspaceByIDForUpdate(ctx context.Context, id uuid.UUID) (WorkspaceTable, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceByIDForUpdate, id)
+	var i WorkspaceTable
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+		&i.Favorite,
+		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
 	)
 	return i, err
 }
 
+const updateWorkspaceACLByID = `-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	group_acl = $1,
+	user_acl = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceACLByIDParams struct {
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
+	UserACL  WorkspaceACL `db:"user_acl" json:"user_acl"`
+	ID       uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceACLByID, arg.GroupACL, arg.UserACL, arg.ID)
+	return err
+}
+
 const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
 UPDATE
 	workspaces
@@ -19613,7 +26594,7 @@ WHERE
     workspaces.id = $1
     AND templates.id = workspaces.template_id
 RETURNING
-    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at
+    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl
 `
 
 type UpdateWorkspaceDormantDeletingAtParams struct {
@@ -19641,6 +26622,8 @@ func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg U
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
 	)
 	return i, err
 }
@@ -19715,7 +26698,7 @@ WHERE
     template_id = $3
 AND
     dormant_at IS NOT NULL
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl
 `
 
 type UpdateWorkspacesDormantDeletingAtByTemplateIDParams struct {
@@ -19750,6 +26733,8 @@ func (q *sqlQuerier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.C
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.GroupACL,
+			&i.UserACL,
 		); err != nil {
 			return nil, err
 		}
@@ -19784,7 +26769,7 @@ func (q *sqlQuerier) UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg Up
 }
 
 const getWorkspaceAgentScriptsByAgentIDs = `-- name: GetWorkspaceAgentScriptsByAgentIDs :many
-SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
+SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id, run_on_claim FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
 `
 
 func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error) {
@@ -19809,6 +26794,7 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids
 			&i.TimeoutSeconds,
 			&i.DisplayName,
 			&i.ID,
+			&i.RunOnClaim,
 		); err != nil {
 			return nil, err
 		}
@@ -19825,7 +26811,7 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids
 
 const insertWorkspaceAgentScripts = `-- name: InsertWorkspaceAgentScripts :many
 INSERT INTO
-	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id)
+	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, run_on_claim, timeout_seconds, display_name, id)
 SELECT
 	$1 :: uuid AS workspace_agent_id,
 	$2 :: timestamptz AS created_at,
@@ -19836,10 +26822,11 @@ SELECT
 	unnest($7 :: boolean [ ]) AS start_blocks_login,
 	unnest($8 :: boolean [ ]) AS run_on_start,
 	unnest($9 :: boolean [ ]) AS run_on_stop,
-	unnest($10 :: integer [ ]) AS timeout_seconds,
-	unnest($11 :: text [ ]) AS display_name,
-	unnest($12 :: uuid [ ]) AS id
-RETURNING workspace_agent_scripts.workspace_agent_id, workspace_agent_scripts.log_source_id, workspace_agent_scripts.log_path, workspace_agent_scripts.created_at, workspace_agent_scripts.script, workspace_agent_scripts.cron, workspace_agent_scripts.start_blocks_login, workspace_agent_scripts.run_on_start, workspace_agent_scripts.run_on_stop, workspace_agent_scripts.timeout_seconds, workspace_agent_scripts.display_name, workspace_agent_scripts.id
+	unnest($10 :: boolean [ ]) AS run_on_claim,
+	unnest($11 :: integer [ ]) AS timeout_seconds,
+	unnest($12 :: text [ ]) AS display_name,
+	unnest($13 :: uuid [ ]) AS id
+RETURNING workspace_agent_scripts.workspace_agent_id, workspace_agent_scripts.log_source_id, workspace_agent_scripts.log_path, workspace_agent_scripts.created_at, workspace_agent_scripts.script, workspace_agent_scripts.cron, workspace_agent_scripts.start_blocks_login, workspace_agent_scripts.run_on_start, workspace_agent_scripts.run_on_stop, workspace_agent_scripts.timeout_seconds, workspace_agent_scripts.display_name, workspace_agent_scripts.id, workspace_agent_scripts.run_on_claim
 `
 
 type InsertWorkspaceAgentScriptsParams struct {
@@ -19852,6 +26839,7 @@ type InsertWorkspaceAgentScriptsParams struct {
 	StartBlocksLogin []bool      `db:"start_blocks_login" json:"start_blocks_login"`
 	RunOnStart       []bool      `db:"run_on_start" json:"run_on_start"`
 	RunOnStop        []bool      `db:"run_on_stop" json:"run_on_stop"`
+	RunOnClaim       []bool      `db:"run_on_claim" json:"run_on_claim"`
 	TimeoutSeconds   []int32     `db:"timeout_seconds" json:"timeout_seconds"`
 	DisplayName      []string    `db:"display_name" json:"display_name"`
 	ID               []uuid.UUID `db:"id" json:"id"`
@@ -19868,6 +26856,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 		pq.Array(arg.StartBlocksLogin),
 		pq.Array(arg.RunOnStart),
 		pq.Array(arg.RunOnStop),
+		pq.Array(arg.RunOnClaim),
 		pq.Array(arg.TimeoutSeconds),
 		pq.Array(arg.DisplayName),
 		pq.Array(arg.ID),
@@ -19892,6 +26881,172 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 			&i.TimeoutSeconds,
 			&i.DisplayName,
 			&i.ID,
+			&i.RunOnClaim,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteOldWorkspaceSessionRecordings = `-- name: DeleteOldWorkspaceSessionRecordings :exec
+DELETE FROM
+	workspace_session_recordings
+WHERE
+	started_at < $1 :: timestamptz
+`
+
+// Recorded output is deleted along with the recordings by the foreign key.
+func (q *sqlQuerier) DeleteOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceSessionRecordings, beforeTime)
+	return err
+}
+
+const getEncryptedWorkspaceSessionRecordingChunks = `-- name: GetEncryptedWorkspaceSessionRecordingChunks :many
+SELECT
+	id, recording_id, created_at, data, data_key_id
+FROM
+	workspace_session_recording_chunks
+WHERE
+	data_key_id IS NOT NULL
+ORDER BY
+	id ASC
+`
+
+// Returns the recorded output that is encrypted, for rotating or removing the
+// encryption keys.
+func (q *sqlQuerier) GetEncryptedWorkspaceSessionRecordingChunks(ctx context.Context) ([]WorkspaceSessionRecordingChunk, error) {
+	rows, err := q.db.QueryContext(ctx, getEncryptedWorkspaceSessionRecordingChunks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecordingChunk
+	for rows.Next() {
+		var i WorkspaceSessionRecordingChunk
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecordingID,
+			&i.CreatedAt,
+			&i.Data,
+			&i.DataKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceSessionRecordingByID = `-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	id, workspace_id, agent_id, type, width, height, started_at, ended_at, size_bytes
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSessionRecordingByID, id)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.Type,
+		&i.Width,
+		&i.Height,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.SizeBytes,
+	)
+	return i, err
+}
+
+const getWorkspaceSessionRecordingChunks = `-- name: GetWorkspaceSessionRecordingChunks :many
+SELECT
+	id, recording_id, created_at, data, data_key_id
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = $1
+ORDER BY
+	id ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]WorkspaceSessionRecordingChunk, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingChunks, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecordingChunk
+	for rows.Next() {
+		var i WorkspaceSessionRecordingChunk
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecordingID,
+			&i.CreatedAt,
+			&i.Data,
+			&i.DataKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceSessionRecordingsByWorkspaceID = `-- name: GetWorkspaceSessionRecordingsByWorkspaceID :many
+SELECT
+	id, workspace_id, agent_id, type, width, height, started_at, ended_at, size_bytes
+FROM
+	workspace_session_recordings
+WHERE
+	workspace_id = $1
+ORDER BY
+	started_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSessionRecording, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecording
+	for rows.Next() {
+		var i WorkspaceSessionRecording
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.Type,
+			&i.Width,
+			&i.Height,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.SizeBytes,
 		); err != nil {
 			return nil, err
 		}
@@ -19905,3 +27060,149 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 	}
 	return items, nil
 }
+
+const insertWorkspaceSessionRecording = `-- name: InsertWorkspaceSessionRecording :one
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		workspace_id,
+		agent_id,
+		type,
+		width,
+		height,
+		started_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, workspace_id, agent_id, type, width, height, started_at, ended_at, size_bytes
+`
+
+type InsertWorkspaceSessionRecordingParams struct {
+	ID          uuid.UUID                     `db:"id" json:"id"`
+	WorkspaceID uuid.UUID                     `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Type        WorkspaceSessionRecordingType `db:"type" json:"type"`
+	Width       int32                         `db:"width" json:"width"`
+	Height      int32                         `db:"height" json:"height"`
+	StartedAt   time.Time                     `db:"started_at" json:"started_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSessionRecording(ctx context.Context, arg InsertWorkspaceSessionRecordingParams) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSessionRecording,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.Type,
+		arg.Width,
+		arg.Height,
+		arg.StartedAt,
+	)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.Type,
+		&i.Width,
+		&i.Height,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.SizeBytes,
+	)
+	return i, err
+}
+
+const insertWorkspaceSessionRecordingChunk = `-- name: InsertWorkspaceSessionRecordingChunk :one
+INSERT INTO
+	workspace_session_recording_chunks (
+		recording_id,
+		created_at,
+		data,
+		data_key_id
+	)
+VALUES
+	($1, $2, $3, $4)
+RETURNING id, recording_id, created_at, data, data_key_id
+`
+
+type InsertWorkspaceSessionRecordingChunkParams struct {
+	RecordingID uuid.UUID      `db:"recording_id" json:"recording_id"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	Data        []byte         `db:"data" json:"data"`
+	DataKeyID   sql.NullString `db:"data_key_id" json:"data_key_id"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg InsertWorkspaceSessionRecordingChunkParams) (WorkspaceSessionRecordingChunk, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSessionRecordingChunk,
+		arg.RecordingID,
+		arg.CreatedAt,
+		arg.Data,
+		arg.DataKeyID,
+	)
+	var i WorkspaceSessionRecordingChunk
+	err := row.Scan(
+		&i.ID,
+		&i.RecordingID,
+		&i.CreatedAt,
+		&i.Data,
+		&i.DataKeyID,
+	)
+	return i, err
+}
+
+const updateWorkspaceSessionRecording = `-- name: UpdateWorkspaceSessionRecording :one
+UPDATE
+	workspace_session_recordings
+SET
+	size_bytes = size_bytes + $1 :: bigint,
+	ended_at = COALESCE(ended_at, $2 :: timestamptz)
+WHERE
+	id = $3
+RETURNING id, workspace_id, agent_id, type, width, height, started_at, ended_at, size_bytes
+`
+
+type UpdateWorkspaceSessionRecordingParams struct {
+	AddedBytes int64        `db:"added_bytes" json:"added_bytes"`
+	EndedAt    sql.NullTime `db:"ended_at" json:"ended_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
+}
+
+// Adds the size of newly recorded output to the recording, and marks it as
+// ended if an end time is given. Once set, the end time is never changed.
+func (q *sqlQuerier) UpdateWorkspaceSessionRecording(ctx context.Context, arg UpdateWorkspaceSessionRecordingParams) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceSessionRecording, arg.AddedBytes, arg.EndedAt, arg.ID)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.Type,
+		&i.Width,
+		&i.Height,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.SizeBytes,
+	)
+	return i, err
+}
+
+const updateWorkspaceSessionRecordingChunkData = `-- name: UpdateWorkspaceSessionRecordingChunkData :exec
+UPDATE
+	workspace_session_recording_chunks
+SET
+	data = $1,
+	data_key_id = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceSessionRecordingChunkDataParams struct {
+	Data      []byte         `db:"data" json:"data"`
+	DataKeyID sql.NullString `db:"data_key_id" json:"data_key_id"`
+	ID        int64          `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceSessionRecordingChunkData(ctx context.Context, arg UpdateWorkspaceSessionRecordingChunkDataParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceSessionRecordingChunkData, arg.Data, arg.DataKeyID, arg.ID)
+	return err
+}
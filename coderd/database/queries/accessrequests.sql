@@ -0,0 +1,79 @@
+-- name: InsertAccessRequest :one
+INSERT INTO
+	access_requests (
+		id,
+		organization_id,
+		requester_id,
+		resource_type,
+		resource_id,
+		role,
+		reason,
+		duration_ms,
+		created_at
+	)
+VALUES
+	(@id, @organization_id, @requester_id, @resource_type, @resource_id, @role, @reason, @duration_ms, @created_at)
+RETURNING *;
+
+-- name: GetAccessRequestByID :one
+SELECT
+	*
+FROM
+	access_requests
+WHERE
+	id = @id;
+
+-- name: GetAccessRequests :many
+SELECT
+	*
+FROM
+	access_requests
+WHERE
+	CASE
+		WHEN @status :: text != '' THEN
+			status :: text = @status :: text
+		ELSE true
+	END
+	AND CASE
+		WHEN @requester_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			requester_id = @requester_id
+		ELSE true
+	END
+ORDER BY
+	created_at DESC;
+
+-- name: UpdateAccessRequestReviewByID :one
+UPDATE
+	access_requests
+SET
+	status = @status,
+	previous_role = @previous_role,
+	reviewed_by = @reviewed_by,
+	reviewed_at = @reviewed_at,
+	expires_at = @expires_at
+WHERE
+	id = @id
+	-- Requests can only be reviewed once.
+	AND status = 'pending'
+RETURNING *;
+
+-- name: GetExpiredAccessRequests :many
+SELECT
+	*
+FROM
+	access_requests
+WHERE
+	status = 'approved'
+	AND expires_at <= @now :: timestamptz
+ORDER BY
+	expires_at ASC;
+
+-- name: UpdateAccessRequestExpiredByID :one
+UPDATE
+	access_requests
+SET
+	status = 'expired'
+WHERE
+	id = @id
+	AND status = 'approved'
+RETURNING *;
@@ -116,17 +116,42 @@ WHERE
             workspace_builds.reason::text = @build_reason
         ELSE true
     END
+	-- Filter by client_type of connections
+	AND CASE
+		WHEN @client_type :: text != '' THEN
+			audit_logs.additional_fields ->> 'client_type' = @client_type
+		ELSE true
+	END
 	-- Filter request_id
 	AND CASE
 		WHEN @request_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
 			audit_logs.request_id = @request_id
 		ELSE true
 	END
+	AND CASE
+		-- This allows using the last audit log on a page as a cursor, which
+		-- doesn't degrade on large tables like offsets do.
+		WHEN @after_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The query is ordered by time and then ID, descending, so
+			-- select all rows before the cursor.
+			(audit_logs."time", audit_logs.id) < (
+				SELECT
+					"time", id
+				FROM
+					audit_logs
+				WHERE
+					id = @after_id
+			)
+		)
+		ELSE true
+	END
 
 	-- Authorize Filter clause will be injected below in GetAuthorizedAuditLogsOffset
 	-- @authorize_filter
 ORDER BY
-    "time" DESC
+    "time" DESC,
+    -- Order by ID as well, so logs with the same time have a stable order.
+    audit_logs.id DESC
 LIMIT
 	-- a limit of 0 means "no limit". The audit log table is unbounded
 	-- in size, and is expected to be quite large. Implement a default
@@ -135,6 +160,50 @@ LIMIT
 OFFSET
     @offset_opt;
 
+-- name: GetAuditLogsForExport :many
+-- Returns the audit logs logged after the given cursor, which is the time and
+-- ID of the last exported log, in the order they are exported.
+SELECT
+	sqlc.embed(audit_logs),
+	COALESCE(users.username, '') AS user_username,
+	COALESCE(users.email, '') AS user_email,
+	COALESCE(organizations.name, '') AS organization_name
+FROM
+	audit_logs
+	LEFT JOIN users ON audit_logs.user_id = users.id
+	LEFT JOIN organizations ON audit_logs.organization_id = organizations.id
+WHERE
+	(audit_logs."time", audit_logs.id) > (@after_time :: timestamptz, @after_id :: uuid)
+	AND audit_logs."time" < @before_time :: timestamptz
+ORDER BY
+	audit_logs."time" ASC, audit_logs.id ASC
+LIMIT
+	@limit_count :: int;
+
+-- name: DeleteOldAuditLogs :execrows
+-- Deletes at most @limit_count audit logs older than @before, oldest first.
+DELETE FROM
+	audit_logs
+WHERE
+	(id, "time") IN (
+		SELECT
+			id, "time"
+		FROM
+			audit_logs
+		WHERE
+			"time" < @before :: timestamptz
+		ORDER BY
+			"time" ASC
+		LIMIT
+			@limit_count :: int
+	);
+
+-- name: DropOldAuditLogsPartitions :one
+-- Drops the audit_logs partitions that only hold rows older than @before.
+-- Returns the number of dropped partitions.
+SELECT
+	drop_time_partitions('audit_logs', @before :: timestamptz)::integer AS dropped;
+
 -- name: InsertAuditLog :one
 INSERT INTO
 	audit_logs (
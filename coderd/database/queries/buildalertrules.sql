@@ -0,0 +1,121 @@
+-- name: InsertBuildAlertRule :one
+INSERT INTO
+	build_alert_rules (
+		id,
+		organization_id,
+		template_id,
+		name,
+		metric,
+		threshold,
+		window_ms,
+		created_by,
+		created_at,
+		updated_at
+	)
+VALUES
+	(@id, @organization_id, @template_id, @name, @metric, @threshold, @window_ms, @created_by, @created_at, @updated_at)
+RETURNING *;
+
+-- name: GetBuildAlertRuleByID :one
+SELECT
+	*
+FROM
+	build_alert_rules
+WHERE
+	id = @id;
+
+-- name: GetBuildAlertRulesByOrganizationID :many
+SELECT
+	*
+FROM
+	build_alert_rules
+WHERE
+	organization_id = @organization_id
+ORDER BY
+	lower(name) ASC;
+
+-- name: GetBuildAlertRules :many
+SELECT
+	*
+FROM
+	build_alert_rules
+ORDER BY
+	created_at ASC;
+
+-- name: UpdateBuildAlertRuleByID :one
+UPDATE
+	build_alert_rules
+SET
+	template_id = @template_id,
+	name = @name,
+	metric = @metric,
+	threshold = @threshold,
+	window_ms = @window_ms,
+	updated_at = @updated_at,
+	-- The rule changed, so it is evaluated from scratch.
+	last_evaluated_at = NULL,
+	last_value = NULL,
+	breached_at = NULL
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: UpdateBuildAlertRuleEvaluation :exec
+UPDATE
+	build_alert_rules
+SET
+	last_evaluated_at = @last_evaluated_at,
+	last_value = @last_value,
+	breached_at = @breached_at
+WHERE
+	id = @id;
+
+-- name: DeleteBuildAlertRuleByID :exec
+DELETE FROM
+	build_alert_rules
+WHERE
+	id = @id;
+
+-- name: GetBuildAlertRuleMetrics :one
+-- Returns the metrics build alert rules are evaluated against, over the
+-- workspace builds of an organization since the given time. If template_id
+-- is set, only builds of that template are considered.
+SELECT
+	COUNT(*) FILTER (
+		WHERE provisioner_jobs.job_status IN ('succeeded', 'failed')
+		AND provisioner_jobs.completed_at >= @since :: timestamptz
+	) :: bigint AS completed_builds,
+	COUNT(*) FILTER (
+		WHERE provisioner_jobs.job_status = 'failed'
+		AND provisioner_jobs.completed_at >= @since :: timestamptz
+	) :: bigint AS failed_builds,
+	COUNT(*) FILTER (
+		WHERE provisioner_jobs.created_at >= @since :: timestamptz
+		AND (provisioner_jobs.started_at IS NOT NULL OR provisioner_jobs.completed_at IS NULL)
+	) :: bigint AS queued_builds,
+	-- Jobs that are still pending have been waiting until now.
+	COALESCE(
+		percentile_cont(0.95) WITHIN GROUP (
+			ORDER BY EXTRACT(EPOCH FROM COALESCE(provisioner_jobs.started_at, @now :: timestamptz) - provisioner_jobs.created_at) * 1000
+		) FILTER (
+			WHERE provisioner_jobs.created_at >= @since :: timestamptz
+			AND (provisioner_jobs.started_at IS NOT NULL OR provisioner_jobs.completed_at IS NULL)
+		),
+		0
+	) :: double precision AS queue_wait_p95_ms
+FROM
+	provisioner_jobs
+JOIN
+	workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+WHERE
+	provisioner_jobs.organization_id = @organization_id
+	AND (
+		sqlc.narg('template_id') :: uuid IS NULL
+		OR workspaces.template_id = sqlc.narg('template_id') :: uuid
+	)
+	AND (
+		provisioner_jobs.created_at >= @since :: timestamptz
+		OR provisioner_jobs.completed_at >= @since :: timestamptz
+	);
@@ -69,15 +69,17 @@ INSERT INTO groups (
 	display_name,
 	organization_id,
 	avatar_url,
-	quota_allowance
+	quota_allowance,
+	monthly_budget
 )
 VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
 
 -- name: InsertMissingGroups :many
 -- Inserts any group by name that does not exist. All new groups are given
 -- a random uuid, are inserted into the same organization. They have the default
--- values for avatar, display name, and quota allowance (all zero values).
+-- values for avatar, display name, quota allowance and monthly budget (all
+-- zero values).
 INSERT INTO groups (
 	id,
 	name,
@@ -115,7 +117,8 @@ SET
 	name = @name,
 	display_name = @display_name,
 	avatar_url = @avatar_url,
-	quota_allowance = @quota_allowance
+	quota_allowance = @quota_allowance,
+	monthly_budget = @monthly_budget
 WHERE
 	id = @id
 RETURNING *;
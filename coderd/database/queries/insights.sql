@@ -81,6 +81,98 @@ GROUP BY
 ORDER BY
 	ds.user_id ASC;
 
+-- name: GetUserDailyActivity :many
+-- GetUserDailyActivity returns the activity of each user in each UTC day that
+-- starts within the given period: how long they were connected to
+-- workspaces, how many workspace builds they started and which apps they
+-- used. Days in which a user had no activity are omitted.
+WITH
+	usage_stats AS (
+		SELECT
+			date_trunc('day', start_time, 'UTC') AS day,
+			user_id,
+			-- See motivation in GetTemplateInsights for LEAST(SUM(n), 30).
+			LEAST(SUM(usage_mins), 30) AS usage_mins
+		FROM
+			template_usage_stats
+		WHERE
+			start_time >= @start_time::timestamptz
+			AND start_time < @end_time::timestamptz
+		GROUP BY
+			start_time, user_id
+	),
+	daily_usage AS (
+		SELECT
+			day,
+			user_id,
+			SUM(usage_mins) AS usage_mins
+		FROM
+			usage_stats
+		GROUP BY
+			day, user_id
+	),
+	daily_apps AS (
+		SELECT
+			date_trunc('day', tus.start_time, 'UTC') AS day,
+			tus.user_id,
+			array_agg(DISTINCT app.key ORDER BY app.key) AS apps
+		FROM
+			template_usage_stats tus, jsonb_each(tus.app_usage_mins) app
+		WHERE
+			tus.start_time >= @start_time::timestamptz
+			AND tus.start_time < @end_time::timestamptz
+		GROUP BY
+			day, tus.user_id
+	),
+	daily_builds AS (
+		SELECT
+			date_trunc('day', created_at, 'UTC') AS day,
+			initiator_id AS user_id,
+			COUNT(*) AS builds
+		FROM
+			workspace_builds
+		WHERE
+			created_at >= @start_time::timestamptz
+			AND created_at < @end_time::timestamptz
+		GROUP BY
+			day, initiator_id
+	),
+	days AS (
+		SELECT day, user_id FROM daily_usage
+		UNION
+		SELECT day, user_id FROM daily_builds
+	)
+
+SELECT
+	d.day::timestamptz AS date,
+	d.user_id,
+	u.username,
+	(COALESCE(du.usage_mins, 0) * 60)::bigint AS usage_seconds,
+	COALESCE(db.builds, 0)::bigint AS workspace_builds,
+	COALESCE(da.apps, '{}')::text[] AS apps
+FROM
+	days d
+JOIN
+	users u
+ON
+	u.id = d.user_id
+LEFT JOIN
+	daily_usage du
+ON
+	du.day = d.day AND du.user_id = d.user_id
+LEFT JOIN
+	daily_builds db
+ON
+	db.day = d.day AND db.user_id = d.user_id
+LEFT JOIN
+	daily_apps da
+ON
+	da.day = d.day AND da.user_id = d.user_id
+WHERE
+	NOT u.is_system
+ORDER BY
+	d.day ASC, u.username ASC;
+
 -- name: GetTemplateInsights :one
 -- GetTemplateInsights returns the aggregate user-produced usage of all
 -- workspaces in a given timeframe. The template IDs, active users, and
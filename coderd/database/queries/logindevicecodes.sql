@@ -0,0 +1,56 @@
+-- name: InsertLoginDeviceCode :one
+INSERT INTO
+	login_device_codes (
+		id,
+		device_code_hash,
+		user_code,
+		created_at,
+		expires_at
+	)
+VALUES
+	(@id, @device_code_hash, @user_code, @created_at, @expires_at)
+RETURNING *;
+
+-- name: GetLoginDeviceCodeByDeviceCodeHash :one
+SELECT
+	*
+FROM
+	login_device_codes
+WHERE
+	device_code_hash = @device_code_hash;
+
+-- name: GetLoginDeviceCodeByUserCode :one
+SELECT
+	*
+FROM
+	login_device_codes
+WHERE
+	user_code = @user_code;
+
+-- name: ApproveLoginDeviceCode :one
+-- Approves a device for a user. Devices can only be approved once.
+UPDATE
+	login_device_codes
+SET
+	user_id = @user_id :: uuid
+WHERE
+	id = @id
+	AND user_id IS NULL
+RETURNING *;
+
+-- name: ConsumeApprovedLoginDeviceCode :one
+-- Deletes an approved device code that has not expired, so a session token is
+-- issued for it only once.
+DELETE FROM
+	login_device_codes
+WHERE
+	device_code_hash = @device_code_hash
+	AND user_id IS NOT NULL
+	AND expires_at > @now
+RETURNING *;
+
+-- name: DeleteExpiredLoginDeviceCodes :exec
+DELETE FROM
+	login_device_codes
+WHERE
+	expires_at < @before;
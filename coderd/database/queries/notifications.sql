@@ -158,6 +158,100 @@ ON CONFLICT (user_id, notification_template_id) DO UPDATE
     SET disabled   = EXCLUDED.disabled,
         updated_at = CURRENT_TIMESTAMP;
 
+-- name: UpdateUserNotificationDigestPreferences :execrows
+-- Preferences which don't exist yet are created with the default enablement
+-- of their template, so opting into a digest doesn't enable a notification.
+INSERT
+INTO notification_preferences (user_id, notification_template_id, disabled, digest)
+SELECT @user_id::uuid, new_values.notification_template_id, NOT nt.enabled_by_default, new_values.digest
+FROM (SELECT UNNEST(@notification_template_ids::uuid[]) AS notification_template_id,
+             UNNEST(@digests::bool[])                   AS digest) AS new_values,
+     notification_templates AS nt
+WHERE nt.id = new_values.notification_template_id
+ON CONFLICT (user_id, notification_template_id) DO UPDATE
+    SET digest     = EXCLUDED.digest,
+        updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetUserNotificationDigestFrequency :one
+SELECT
+	value AS notification_digest_frequency
+FROM
+	user_configs
+WHERE
+	user_id = @user_id
+	AND key = 'notification_digest_frequency';
+
+-- name: UpdateUserNotificationDigestFrequency :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	(@user_id, 'notification_digest_frequency', @notification_digest_frequency)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = @notification_digest_frequency
+WHERE user_configs.user_id = @user_id
+	AND user_configs.key = 'notification_digest_frequency'
+RETURNING *;
+
+-- name: GetUserNotificationBuildDurationThreshold :one
+SELECT
+	value AS notification_build_duration_threshold_ms
+FROM
+	user_configs
+WHERE
+	user_id = @user_id
+	AND key = 'notification_build_duration_threshold_ms';
+
+-- name: UpdateUserNotificationBuildDurationThreshold :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	(@user_id, 'notification_build_duration_threshold_ms', @notification_build_duration_threshold_ms)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = @notification_build_duration_threshold_ms
+WHERE user_configs.user_id = @user_id
+	AND user_configs.key = 'notification_build_duration_threshold_ms'
+RETURNING *;
+
+-- name: GetUsersWithDueNotificationDigests :many
+-- Returns the users whose oldest batched notification message is older than
+-- their digest frequency. Digests are sent daily unless configured otherwise.
+SELECT nm.user_id,
+       COALESCE(uc.value, 'daily')::text AS frequency
+FROM notification_messages AS nm
+         LEFT JOIN user_configs AS uc
+                   ON (uc.user_id = nm.user_id AND uc.key = 'notification_digest_frequency')
+WHERE nm.status = 'batched'::notification_message_status
+GROUP BY nm.user_id, uc.value
+HAVING MIN(nm.created_at) + (CASE
+                                 WHEN uc.value = 'weekly' THEN INTERVAL '7 days'
+                                 ELSE INTERVAL '1 day' END) <= @now::timestamptz;
+
+-- name: GetBatchedNotificationMessagesByUserID :many
+SELECT nm.id,
+       nm.payload,
+       nm.created_at,
+       nt.name AS notification_name,
+       nt.title_template
+FROM notification_messages AS nm
+         JOIN notification_templates AS nt ON nm.notification_template_id = nt.id
+WHERE nm.user_id = @user_id::uuid
+  AND nm.status = 'batched'::notification_message_status
+ORDER BY nm.created_at ASC;
+
+-- name: MarkNotificationMessagesDigested :execrows
+UPDATE notification_messages
+SET updated_at    = @digested_at::timestamptz,
+    status        = 'sent'::notification_message_status,
+    status_reason = 'Delivered in a notification digest'
+WHERE id = ANY(@ids::uuid[])
+  AND status = 'batched'::notification_message_status;
+
 -- name: UpdateNotificationTemplateMethodByID :one
 UPDATE notification_templates
 SET method = sqlc.narg('method')::notification_method
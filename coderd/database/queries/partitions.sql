@@ -0,0 +1,6 @@
+-- name: CreateTimePartition :one
+-- Creates the range partition of a time partitioned table covering
+-- [partition_start, partition_end). Returns false if the partition already
+-- exists, or if rows in that range are still stored in the default partition.
+SELECT
+	create_time_partition(@parent_table::text, @partition_start::timestamptz, @partition_end::timestamptz)::boolean AS created;
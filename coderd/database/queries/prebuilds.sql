@@ -8,6 +8,7 @@ WHERE w.id IN (
 	FROM workspace_prebuilds p
 		INNER JOIN workspace_latest_builds b ON b.workspace_id = p.id
 		INNER JOIN templates t ON p.template_id = t.id
+		LEFT JOIN workspace_prebuild_reservations r ON r.workspace_id = p.id AND r.expires_at > NOW()
 	WHERE (b.transition = 'start'::workspace_transition
 		AND b.job_status IN ('succeeded'::provisioner_job_status))
 		-- The prebuilds system should never try to claim a prebuild for an inactive template version.
@@ -16,6 +17,12 @@ WHERE w.id IN (
 		AND p.current_preset_id = @preset_id::uuid
 		AND p.ready
 		AND NOT t.deleted
+		-- Prebuilds reserved for another user or group can't be claimed until the reservation expires.
+		AND (r.workspace_id IS NULL
+			OR r.user_id = @new_user_id::uuid
+			OR r.group_id IN (SELECT gme.group_id FROM group_members_expanded gme WHERE gme.user_id = @new_user_id::uuid))
+	-- Claim prebuilds reserved for the user before unreserved ones.
+	ORDER BY r.workspace_id IS NULL
 	LIMIT 1 FOR UPDATE OF p SKIP LOCKED -- Ensure that a concurrent request will not select the same prebuild.
 )
 RETURNING w.id, w.name;
@@ -188,3 +195,65 @@ INNER JOIN organizations o ON o.id = w.organization_id
 WHERE NOT t.deleted AND wpb.build_number = 1
 GROUP BY t.name, tvp.name, o.name
 ORDER BY t.name, tvp.name, o.name;
+
+-- name: GetPrebuildClaimDemand :many
+-- GetPrebuildClaimDemand returns the number of workspaces created from each preset in every hour since
+-- the given time. Claimed workspaces were assigned a prebuilt workspace, missed ones were provisioned from
+-- scratch. Presets are identified by template and name, since every template version has its own presets.
+SELECT
+	w.template_id,
+	tvp.name AS preset_name,
+	date_trunc('hour', wb.created_at)::timestamptz AS hour,
+	COUNT(*) FILTER (WHERE wb.build_number > 1) AS claimed_count,
+	COUNT(*) FILTER (WHERE wb.build_number = 1) AS missed_count
+FROM workspace_builds wb
+INNER JOIN workspaces w ON w.id = wb.workspace_id
+INNER JOIN template_version_presets tvp ON tvp.id = wb.template_version_preset_id
+LEFT JOIN workspace_builds prev ON prev.workspace_id = wb.workspace_id AND prev.build_number = wb.build_number - 1
+WHERE wb.created_at >= @since::timestamptz
+	AND wb.initiator_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+	-- Either the first build of a workspace created by a user, or the build that claimed a prebuilt workspace.
+	AND (wb.build_number = 1 OR prev.initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid)
+GROUP BY w.template_id, tvp.name, hour
+ORDER BY w.template_id, tvp.name, hour;
+
+-- name: UpsertWorkspacePrebuildReservation :one
+INSERT INTO workspace_prebuild_reservations (
+	workspace_id,
+	user_id,
+	group_id,
+	reserved_by,
+	created_at,
+	expires_at
+) VALUES (
+	@workspace_id,
+	@user_id,
+	@group_id,
+	@reserved_by,
+	@created_at,
+	@expires_at
+)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	user_id = EXCLUDED.user_id,
+	group_id = EXCLUDED.group_id,
+	reserved_by = EXCLUDED.reserved_by,
+	created_at = EXCLUDED.created_at,
+	expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: GetWorkspacePrebuildReservationByWorkspaceID :one
+SELECT * FROM workspace_prebuild_reservations WHERE workspace_id = @workspace_id;
+
+-- name: GetWorkspacePrebuildReservationsByTemplateID :many
+-- GetWorkspacePrebuildReservationsByTemplateID returns the reservations of the prebuilt workspaces of a template
+-- which have been neither claimed nor deleted, including expired ones.
+SELECT r.*
+FROM workspace_prebuild_reservations r
+INNER JOIN workspaces w ON w.id = r.workspace_id
+WHERE w.template_id = @template_id
+	AND w.owner_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+	AND NOT w.deleted
+ORDER BY r.expires_at, r.workspace_id;
+
+-- name: DeleteWorkspacePrebuildReservationByWorkspaceID :exec
+DELETE FROM workspace_prebuild_reservations WHERE workspace_id = @workspace_id;
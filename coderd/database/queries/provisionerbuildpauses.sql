@@ -0,0 +1,28 @@
+-- name: GetProvisionerBuildPause :one
+-- Returns the pause of an organization, or the deployment-wide pause if
+-- organization_id is NULL.
+SELECT
+	*
+FROM
+	provisioner_build_pauses
+WHERE
+	organization_id IS NOT DISTINCT FROM sqlc.narg('organization_id') :: uuid;
+
+-- name: InsertProvisionerBuildPause :one
+INSERT INTO
+	provisioner_build_pauses (
+		id,
+		organization_id,
+		reason,
+		created_by,
+		created_at
+	)
+VALUES
+	(@id, @organization_id, @reason, @created_by, @created_at)
+RETURNING *;
+
+-- name: DeleteProvisionerBuildPause :exec
+DELETE FROM
+	provisioner_build_pauses
+WHERE
+	organization_id IS NOT DISTINCT FROM sqlc.narg('organization_id') :: uuid;
@@ -9,13 +9,121 @@ WHERE
 		id > @created_after
 	) ORDER BY id ASC;
 
+-- name: SearchProvisionerJobLogs :many
+-- Returns the logs of a job with an ID greater than created_after that match
+-- all of the given filters. Unset filters match every log.
+SELECT
+	*
+FROM
+	provisioner_job_logs
+WHERE
+	job_id = @job_id
+	AND id > @created_after
+	AND (
+		sqlc.narg('min_level') :: log_level IS NULL
+		OR level >= sqlc.narg('min_level') :: log_level
+	)
+	AND (
+		@stage :: text = ''
+		OR stage = @stage :: text
+	)
+	-- Matches provisioner_job_logs_output_search_idx.
+	AND (
+		@search :: text = ''
+		OR to_tsvector('simple', output) @@ plainto_tsquery('simple', @search :: text)
+	)
+ORDER BY
+	id ASC;
+
 -- name: InsertProvisionerJobLogs :many
 INSERT INTO
-	provisioner_job_logs
+	provisioner_job_logs (job_id, created_at, source, level, stage, output, fields)
 SELECT
 	@job_id :: uuid AS job_id,
 	unnest(@created_at :: timestamptz [ ]) AS created_at,
 	unnest(@source :: log_source [ ]) AS source,
 	unnest(@level :: log_level [ ]) AS LEVEL,
 	unnest(@stage :: VARCHAR(128) [ ]) AS stage,
-	unnest(@output :: VARCHAR(1024) [ ]) AS output RETURNING *;
+	unnest(@output :: VARCHAR(1024) [ ]) AS output,
+	-- jsonb arrays cannot be passed as parameters, so the fields are sent
+	-- as text and cast.
+	unnest(@fields :: text [ ]) :: jsonb AS fields RETURNING *;
+
+-- name: DeleteProvisionerJobLogsByJobID :exec
+DELETE FROM provisioner_job_logs WHERE job_id = @job_id;
+
+-- name: DeleteOldProvisionerJobLogs :one
+-- Deletes the logs of at most @limit_count jobs that completed before
+-- @completed_before, oldest first, including archived logs and their files.
+-- Returns the number of deleted logs.
+WITH
+	old_jobs AS (
+		SELECT
+			provisioner_jobs.id
+		FROM
+			provisioner_jobs
+		WHERE
+			provisioner_jobs.completed_at < @completed_before :: timestamptz
+			AND (
+				EXISTS (
+					SELECT 1 FROM provisioner_job_logs WHERE provisioner_job_logs.job_id = provisioner_jobs.id
+				)
+				OR EXISTS (
+					SELECT 1 FROM provisioner_job_log_archives WHERE provisioner_job_log_archives.job_id = provisioner_jobs.id
+				)
+			)
+		ORDER BY
+			provisioner_jobs.completed_at ASC
+		LIMIT
+			@limit_count :: int
+	),
+	deleted_logs AS (
+		DELETE FROM provisioner_job_logs WHERE job_id IN (SELECT id FROM old_jobs) RETURNING id
+	),
+	deleted_archives AS (
+		DELETE FROM provisioner_job_log_archives WHERE job_id IN (SELECT id FROM old_jobs) RETURNING file_id, log_count
+	),
+	-- Archive files are only referenced by their archive.
+	deleted_files AS (
+		DELETE FROM files WHERE id IN (SELECT file_id FROM deleted_archives)
+	)
+SELECT
+	(
+		(SELECT COUNT(*) FROM deleted_logs)
+		+ (SELECT COALESCE(SUM(log_count), 0) FROM deleted_archives)
+	)::bigint AS deleted;
+
+-- name: GetProvisionerJobsToArchiveLogs :many
+-- Returns jobs that completed before the given time and still have logs in
+-- provisioner_job_logs, oldest first.
+SELECT
+	provisioner_jobs.id,
+	provisioner_jobs.initiator_id
+FROM
+	provisioner_jobs
+WHERE
+	provisioner_jobs.completed_at < @completed_before :: timestamptz
+	AND NOT EXISTS (
+		SELECT 1 FROM provisioner_job_log_archives WHERE provisioner_job_log_archives.job_id = provisioner_jobs.id
+	)
+	AND EXISTS (
+		SELECT 1 FROM provisioner_job_logs WHERE provisioner_job_logs.job_id = provisioner_jobs.id
+	)
+ORDER BY
+	provisioner_jobs.completed_at ASC
+LIMIT
+	@limit_count :: int;
+
+-- name: GetProvisionerJobLogArchiveByJobID :one
+SELECT
+	*
+FROM
+	provisioner_job_log_archives
+WHERE
+	job_id = @job_id;
+
+-- name: InsertProvisionerJobLogArchive :one
+INSERT INTO
+	provisioner_job_log_archives (job_id, file_id, log_count, archived_at)
+VALUES
+	(@job_id, @file_id, @log_count, @archived_at) RETURNING *;
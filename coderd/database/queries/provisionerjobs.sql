@@ -25,7 +25,73 @@ WHERE
 			-- elsewhere, we use the tagset type, but here we use jsonb for backward compatibility
 			-- they are aliases and the code that calls this query already relies on a different type
 			AND provisioner_tagset_contains(@provisioner_tags :: jsonb, potential_job.tags :: jsonb)
+			-- Jobs are held while builds are paused for the deployment or the
+			-- job's organization.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					provisioner_build_pauses AS pause
+				WHERE
+					pause.organization_id IS NULL
+					OR pause.organization_id = potential_job.organization_id
+			)
+			-- Honor capacity reservations. While a reservation is active, provisioners
+			-- able to serve it only pick up jobs outside the reservation as long as
+			-- the reserved number of slots stays available for reserved jobs.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					provisioner_reservations AS reservation
+				WHERE
+					reservation.organization_id = potential_job.organization_id
+					AND reservation.starts_at <= @started_at
+					AND reservation.ends_at > @started_at
+					-- The job is not covered by the reservation...
+					AND NOT (potential_job.tags :: jsonb @> reservation.tags)
+					-- ...but the caller provides reserved capacity...
+					AND @provisioner_tags :: jsonb @> reservation.tags
+					-- ...and all unreserved capacity is in use.
+					AND (
+						SELECT
+							COUNT(*)
+						FROM
+							provisioner_jobs AS running_job
+							INNER JOIN provisioner_daemons AS daemon ON daemon.id = running_job.worker_id
+						WHERE
+							running_job.job_status = 'running'
+							AND running_job.organization_id = reservation.organization_id
+							AND daemon.tags :: jsonb @> reservation.tags
+							AND NOT (running_job.tags :: jsonb @> reservation.tags)
+					) + reservation.slots >= (
+						SELECT
+							COUNT(*)
+						FROM
+							provisioner_daemons AS daemon
+						WHERE
+							daemon.organization_id = reservation.organization_id
+							AND daemon.tags :: jsonb @> reservation.tags
+							-- Matches provisionerdserver.StaleInterval.
+							AND daemon.last_seen_at >= @started_at - INTERVAL '90 seconds'
+					)
+			)
 		ORDER BY
+			-- Prefer builds of workspaces that were last built successfully by
+			-- the caller with the same template version, as the caller likely
+			-- still has the providers cached. Other daemons never wait for the
+			-- preferred one, so affinity doesn't delay jobs.
+			EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_builds AS build
+					INNER JOIN workspace_provisioner_affinities AS affinity ON affinity.workspace_id = build.workspace_id
+				WHERE
+					build.job_id = potential_job.id
+					AND affinity.template_version_id = build.template_version_id
+					AND affinity.provisioner_daemon_id = @worker_id
+			) DESC,
 			potential_job.created_at
 		FOR UPDATE
 		SKIP LOCKED
@@ -33,6 +99,26 @@ WHERE
 			1
 	) RETURNING *;
 
+-- name: CountInProgressProvisionerJobsByInitiator :one
+-- Counts the pending and running provisioner jobs started by a user, both in
+-- total and for workspace builds of the given template.
+SELECT
+	COUNT(*) AS total,
+	COUNT(*) FILTER (WHERE workspaces.template_id = @template_id :: uuid) AS template_builds
+FROM
+	provisioner_jobs
+LEFT JOIN
+	workspace_builds
+ON
+	workspace_builds.job_id = provisioner_jobs.id
+LEFT JOIN
+	workspaces
+ON
+	workspaces.id = workspace_builds.workspace_id
+WHERE
+	provisioner_jobs.initiator_id = @initiator_id :: uuid
+	AND provisioner_jobs.completed_at IS NULL;
+
 -- name: GetProvisionerJobByID :one
 SELECT
 	*
@@ -210,7 +296,16 @@ WHERE
 	pj.organization_id = @organization_id::uuid
 	AND (COALESCE(array_length(@ids::uuid[], 1), 0) = 0 OR pj.id = ANY(@ids::uuid[]))
 	AND (COALESCE(array_length(@status::provisioner_job_status[], 1), 0) = 0 OR pj.job_status = ANY(@status::provisioner_job_status[]))
+	AND (COALESCE(array_length(@types::provisioner_job_type[], 1), 0) = 0 OR pj.type = ANY(@types::provisioner_job_type[]))
 	AND (@tags::tagset = 'null'::tagset OR provisioner_tagset_contains(pj.tags::tagset, @tags::tagset))
+	AND CASE
+		-- This allows using the last job on a page as a cursor. The query is
+		-- ordered by creation time and then ID, descending, so select all
+		-- rows before the cursor.
+		WHEN @after_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			(pj.created_at, pj.id) < (SELECT created_at, id FROM provisioner_jobs WHERE id = @after_id)
+		ELSE true
+	END
 GROUP BY
 	pj.id,
 	qp.queue_position,
@@ -224,13 +319,68 @@ GROUP BY
 	w.name,
 	pd.name
 ORDER BY
-	pj.created_at DESC
+	pj.created_at DESC,
+	pj.id DESC
 LIMIT
 	sqlc.narg('limit')::int;
 
 -- name: GetProvisionerJobsCreatedAfter :many
 SELECT * FROM provisioner_jobs WHERE created_at > $1;
 
+-- name: GetProvisionerJobThroughputByTags :many
+-- Counts the jobs that were picked up by a provisioner since the given time,
+-- grouped by organization and tag set. This is used to estimate when pending
+-- jobs will start.
+SELECT
+	organization_id,
+	tags,
+	COUNT(*) AS started_jobs
+FROM
+	provisioner_jobs
+WHERE
+	organization_id = ANY(@organization_ids :: uuid [ ])
+	AND started_at >= @started_after :: timestamptz
+GROUP BY
+	organization_id, tags;
+
+-- name: GetPendingProvisionerJobsQueueDepthByTags :many
+-- Counts the pending jobs grouped by organization and tag set, along with when
+-- the oldest of them was created. This is used by the health check to detect
+-- tag sets that no provisioner is picking jobs up for.
+SELECT
+	organization_id,
+	tags,
+	COUNT(*) AS pending_jobs,
+	MIN(created_at) :: timestamptz AS oldest_created_at
+FROM
+	provisioner_jobs
+WHERE
+	job_status = 'pending'
+GROUP BY
+	organization_id, tags
+ORDER BY
+	pending_jobs DESC, oldest_created_at ASC;
+
+-- name: GetProvisionerJobsRunningBetween :many
+-- Returns the jobs that a provisioner was running at any point between the
+-- start and end times. This is used to report provisioner utilization and
+-- queue wait times.
+SELECT
+	id,
+	organization_id,
+	tags,
+	created_at,
+	started_at,
+	completed_at
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NOT NULL
+	AND started_at < @end_time :: timestamptz
+	AND (completed_at IS NULL OR completed_at > @start_time :: timestamptz)
+ORDER BY
+	started_at ASC;
+
 -- name: InsertProvisionerJob :one
 INSERT INTO
 	provisioner_jobs (
@@ -301,6 +451,16 @@ WHERE
 		updated_at < @pending_since
 		AND started_at IS NULL
 		AND completed_at IS NULL
+		-- Jobs held by a build pause are not timed out.
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				provisioner_build_pauses AS pause
+			WHERE
+				pause.organization_id IS NULL
+				OR pause.organization_id = provisioner_jobs.organization_id
+		)
 	)
 	OR
 	(
@@ -309,6 +469,14 @@ WHERE
 		AND started_at IS NOT NULL
 		AND completed_at IS NULL
 	)
+	OR
+	(
+		-- If the job was canceled before @canceled_since but the provisioner
+		-- has not acknowledged it by completing the job, reap it.
+		canceled_at < @canceled_since
+		AND started_at IS NOT NULL
+		AND completed_at IS NULL
+	)
 -- To avoid repeatedly attempting to reap the same jobs, we randomly order and limit to @max_jobs.
 ORDER BY random()
 LIMIT @max_jobs;
@@ -329,3 +497,21 @@ RETURNING *;
 SELECT * FROM provisioner_job_timings
 WHERE job_id = $1
 ORDER BY started_at ASC;
+
+-- name: UpdatePendingProvisionerJobsUpdatedAt :many
+-- Restarts the pending timeout of the jobs that have not been acquired yet,
+-- either in an organization or, if organization_id is NULL, across the
+-- deployment. It is used when builds are resumed so that jobs held by the
+-- pause are not reaped right away.
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = @updated_at
+WHERE
+	started_at IS NULL
+	AND completed_at IS NULL
+	AND (
+		sqlc.narg('organization_id') :: uuid IS NULL
+		OR organization_id = sqlc.narg('organization_id')
+	)
+RETURNING *;
@@ -6,10 +6,11 @@ INSERT INTO
         organization_id,
         name,
         hashed_secret,
-        tags
+        tags,
+        expires_at
     )
 VALUES
-    ($1, $2, $3, lower(@name), $4, $5) RETURNING *;
+    ($1, $2, $3, lower(@name), $4, $5, $6) RETURNING *;
 
 -- name: GetProvisionerKeyByID :one
 SELECT
@@ -25,7 +26,11 @@ SELECT
 FROM
     provisioner_keys
 WHERE
-    hashed_secret = $1;
+    hashed_secret = $1
+OR
+    -- The previous secret is still returned after a rotation so that the
+    -- caller can decide whether the overlap window is still open.
+    previous_hashed_secret = $1;
 
 -- name: GetProvisionerKeyByName :one
 SELECT
@@ -67,3 +72,23 @@ DELETE FROM
     provisioner_keys
 WHERE
     id = $1;
+
+-- name: UpdateProvisionerKeyLastUsedAt :exec
+UPDATE
+    provisioner_keys
+SET
+    last_used_at = @last_used_at
+WHERE
+    id = @id;
+
+-- name: RotateProvisionerKey :one
+UPDATE
+    provisioner_keys
+SET
+    previous_hashed_secret = hashed_secret,
+    previous_secret_expires_at = @previous_secret_expires_at,
+    hashed_secret = @hashed_secret,
+    expires_at = @expires_at
+WHERE
+    id = @id
+RETURNING *;
@@ -0,0 +1,40 @@
+-- name: InsertProvisionerReservation :one
+INSERT INTO
+	provisioner_reservations (
+		id,
+		organization_id,
+		tags,
+		slots,
+		starts_at,
+		ends_at,
+		created_at
+	)
+VALUES
+	(@id, @organization_id, @tags, @slots, @starts_at, @ends_at, @created_at)
+RETURNING *;
+
+-- name: GetProvisionerReservationByID :one
+SELECT
+	*
+FROM
+	provisioner_reservations
+WHERE
+	id = @id;
+
+-- name: GetProvisionerReservationsByOrganization :many
+-- Returns the reservations of an organization that have not ended yet.
+SELECT
+	*
+FROM
+	provisioner_reservations
+WHERE
+	organization_id = @organization_id
+	AND ends_at > @ends_after :: timestamptz
+ORDER BY
+	starts_at ASC;
+
+-- name: DeleteProvisionerReservation :exec
+DELETE FROM
+	provisioner_reservations
+WHERE
+	id = @id;
@@ -42,3 +42,32 @@ SELECT
 FROM
 	latest_builds
 ;
+
+-- name: GetQuotaMonthlyBudgetForUser :one
+SELECT
+	coalesce(SUM(groups.monthly_budget), 0)::BIGINT
+FROM
+	(
+		-- Select all groups this user is a member of. This will also include
+		-- the "Everyone" group for organizations the user is a member of.
+		SELECT * FROM group_members_expanded
+		         WHERE
+		             @user_id = user_id AND
+		             @organization_id = group_members_expanded.organization_id
+	) AS members
+INNER JOIN groups ON
+	members.group_id = groups.id
+;
+
+-- name: GetQuotaMonthlySpendForUser :one
+-- Returns the credits spent by the workspaces of the user in the organization
+-- since the start time, as rolled up in workspace_daily_costs.
+SELECT
+	coalesce(SUM(cost), 0)::double precision
+FROM
+	workspace_daily_costs
+WHERE
+	owner_id = @owner_id AND
+	organization_id = @organization_id AND
+	start_time >= @start_time::timestamptz
+;
@@ -0,0 +1,66 @@
+-- name: GetTemplateReleaseChannelsByTemplateID :many
+SELECT
+	*
+FROM
+	template_release_channels
+WHERE
+	template_id = @template_id
+ORDER BY
+	channel ASC;
+
+-- name: GetTemplateReleaseChannel :one
+SELECT
+	*
+FROM
+	template_release_channels
+WHERE
+	template_id = @template_id
+	AND channel = @channel;
+
+-- name: UpsertTemplateReleaseChannel :one
+-- Publishes a template version to a release channel of its template.
+INSERT INTO
+	template_release_channels (
+		template_id,
+		channel,
+		template_version_id,
+		updated_by,
+		updated_at
+	)
+VALUES
+	(@template_id, @channel, @template_version_id, @updated_by, @updated_at)
+ON CONFLICT (template_id, channel) DO UPDATE SET
+	template_version_id = EXCLUDED.template_version_id,
+	updated_by = EXCLUDED.updated_by,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteTemplateReleaseChannel :exec
+DELETE FROM
+	template_release_channels
+WHERE
+	template_id = @template_id
+	AND channel = @channel;
+
+-- name: GetWorkspaceReleaseChannelByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_release_channels
+WHERE
+	workspace_id = @workspace_id;
+
+-- name: UpsertWorkspaceReleaseChannel :exec
+INSERT INTO
+	workspace_release_channels (workspace_id, channel, updated_at)
+VALUES
+	(@workspace_id, @channel, @updated_at)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	channel = EXCLUDED.channel,
+	updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteWorkspaceReleaseChannelByWorkspaceID :exec
+DELETE FROM
+	workspace_release_channels
+WHERE
+	workspace_id = @workspace_id;
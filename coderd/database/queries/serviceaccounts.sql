@@ -0,0 +1,33 @@
+-- name: InsertServiceAccount :one
+INSERT INTO
+	service_accounts (
+		user_id,
+		organization_id,
+		description,
+		created_by,
+		created_at
+	)
+VALUES
+	(@user_id, @organization_id, @description, @created_by, @created_at)
+RETURNING *;
+
+-- name: GetServiceAccountByUserID :one
+SELECT
+	*
+FROM
+	service_accounts
+WHERE
+	user_id = @user_id;
+
+-- name: GetServiceAccountsByOrganizationID :many
+SELECT
+	service_accounts.*
+FROM
+	service_accounts
+JOIN
+	users ON users.id = service_accounts.user_id
+WHERE
+	service_accounts.organization_id = @organization_id
+	AND users.deleted = false
+ORDER BY
+	service_accounts.created_at ASC;
@@ -36,6 +36,20 @@ ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update
 -- name: GetLastUpdateCheck :one
 SELECT value FROM site_configs WHERE key = 'last_update_check';
 
+-- name: UpsertDBPurgeStats :exec
+INSERT INTO site_configs (key, value) VALUES ('dbpurge_stats', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'dbpurge_stats';
+
+-- name: GetDBPurgeStats :one
+SELECT value FROM site_configs WHERE key = 'dbpurge_stats';
+
+-- name: UpsertJobReaperLastRunAt :exec
+INSERT INTO site_configs (key, value) VALUES ('job_reaper_last_run_at', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'job_reaper_last_run_at';
+
+-- name: GetJobReaperLastRunAt :one
+SELECT value FROM site_configs WHERE key = 'job_reaper_last_run_at';
+
 -- name: UpsertAnnouncementBanners :exec
 INSERT INTO site_configs (key, value) VALUES ('announcement_banners', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'announcement_banners';
@@ -96,6 +110,15 @@ SELECT
 INSERT INTO site_configs (key, value) VALUES ('notifications_settings', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'notifications_settings';
 
+-- name: GetReadOnlySettings :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'read_only_settings'), '{}') :: text AS read_only_settings
+;
+
+-- name: UpsertReadOnlySettings :exec
+INSERT INTO site_configs (key, value) VALUES ('read_only_settings', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'read_only_settings';
+
 -- name: GetRuntimeConfig :one
 SELECT value FROM site_configs WHERE site_configs.key = $1;
 
@@ -0,0 +1,90 @@
+-- name: GetTemplateBuildDurationStats :many
+SELECT
+	*
+FROM
+	template_build_duration_stats
+WHERE
+	template_id = @template_id
+	AND start_time >= @start_time::timestamptz
+ORDER BY
+	start_time ASC,
+	transition ASC;
+
+-- name: UpsertTemplateBuildDurationStats :exec
+-- This query aggregates the durations of successful workspace builds into
+-- daily buckets per template and transition. The result is stored in the
+-- template_build_duration_stats table. The most recent bucket and the one
+-- before it are recomputed on every run, since builds keep completing in them.
+WITH
+	latest_start AS (
+		SELECT
+			COALESCE(
+				MAX(start_time) - '1 day'::interval,
+				-- Fallback when there are no build duration stats yet.
+				date_trunc('day', (
+					SELECT MIN(completed_at) FROM provisioner_jobs WHERE type = 'workspace_build'
+				) AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+			) AS t
+		FROM
+			template_build_duration_stats
+	),
+	build_durations AS (
+		SELECT
+			-- Buckets are UTC days regardless of the time zone of the
+			-- database session.
+			date_trunc('day', pj.completed_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS time_bucket,
+			tv.template_id,
+			wb.transition,
+			EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)) * 1000 AS duration_ms
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		JOIN
+			template_versions AS tv
+		ON
+			tv.id = wb.template_version_id
+		WHERE
+			tv.template_id IS NOT NULL
+			AND pj.started_at IS NOT NULL
+			AND pj.completed_at >= (SELECT t FROM latest_start)
+			AND pj.canceled_at IS NULL
+			AND (pj.error IS NULL OR pj.error = '')
+	)
+INSERT INTO template_build_duration_stats AS tbds (
+	start_time,
+	end_time,
+	template_id,
+	transition,
+	build_count,
+	p50_duration_ms,
+	p95_duration_ms,
+	max_duration_ms
+)
+SELECT
+	time_bucket AS start_time,
+	time_bucket + '1 day'::interval AS end_time,
+	template_id,
+	transition,
+	COUNT(*) AS build_count,
+	(PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY duration_ms))::bigint AS p50_duration_ms,
+	(PERCENTILE_DISC(0.95) WITHIN GROUP (ORDER BY duration_ms))::bigint AS p95_duration_ms,
+	MAX(duration_ms)::bigint AS max_duration_ms
+FROM
+	build_durations
+GROUP BY
+	time_bucket, template_id, transition
+ON CONFLICT
+	(start_time, template_id, transition)
+DO UPDATE
+SET
+	build_count = EXCLUDED.build_count,
+	p50_duration_ms = EXCLUDED.p50_duration_ms,
+	p95_duration_ms = EXCLUDED.p95_duration_ms,
+	max_duration_ms = EXCLUDED.max_duration_ms
+WHERE
+	(tbds.build_count, tbds.p50_duration_ms, tbds.p95_duration_ms, tbds.max_duration_ms)
+	IS DISTINCT FROM
+	(EXCLUDED.build_count, EXCLUDED.p50_duration_ms, EXCLUDED.p95_duration_ms, EXCLUDED.max_duration_ms);
@@ -0,0 +1,96 @@
+-- name: GetTemplatePresetsByTemplateID :many
+SELECT
+	*
+FROM
+	template_presets
+WHERE
+	template_id = @template_id
+ORDER BY
+	name;
+
+-- name: GetTemplatePresetByID :one
+SELECT
+	*
+FROM
+	template_presets
+WHERE
+	id = @id;
+
+-- name: InsertTemplatePreset :one
+INSERT INTO
+	template_presets (
+		id,
+		template_id,
+		name,
+		description,
+		parameters,
+		created_at,
+		updated_at
+	)
+VALUES
+	(@id, @template_id, @name, @description, @parameters, @created_at, @updated_at)
+RETURNING *;
+
+-- name: UpdateTemplatePresetByID :one
+UPDATE
+	template_presets
+SET
+	name = @name,
+	description = @description,
+	parameters = @parameters,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteTemplatePresetByID :exec
+DELETE FROM
+	template_presets
+WHERE
+	id = @id;
+
+-- name: GetTemplatePresetGroupDefaultsByTemplateID :many
+SELECT
+	*
+FROM
+	template_preset_group_defaults
+WHERE
+	template_id = @template_id;
+
+-- name: UpsertTemplatePresetGroupDefault :exec
+-- A group has at most one default preset per template, so making a preset the
+-- default of a group replaces the previous default.
+INSERT INTO
+	template_preset_group_defaults (
+		template_id,
+		group_id,
+		template_preset_id
+	)
+VALUES
+	(@template_id, @group_id, @template_preset_id)
+ON CONFLICT (template_id, group_id) DO UPDATE SET
+	template_preset_id = EXCLUDED.template_preset_id;
+
+-- name: DeleteTemplatePresetGroupDefaultsByPresetID :exec
+DELETE FROM
+	template_preset_group_defaults
+WHERE
+	template_preset_id = @template_preset_id;
+
+-- name: GetDefaultTemplatePresetForUser :one
+-- Returns the default preset of the template for the groups of the user.
+-- Defaults of groups take precedence over the default of the "Everyone" group,
+-- and ties are broken by group name.
+SELECT
+	template_presets.*
+FROM
+	template_presets
+	INNER JOIN template_preset_group_defaults ON template_preset_group_defaults.template_preset_id = template_presets.id
+	INNER JOIN group_members_expanded ON group_members_expanded.group_id = template_preset_group_defaults.group_id
+WHERE
+	template_preset_group_defaults.template_id = @template_id
+	AND group_members_expanded.user_id = @user_id
+ORDER BY
+	group_members_expanded.group_id = group_members_expanded.organization_id,
+	group_members_expanded.group_name
+LIMIT 1;
@@ -133,7 +133,10 @@ SET
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
 	max_port_sharing_level = $9,
-	use_classic_parameter_flow = $10
+	use_classic_parameter_flow = $10,
+	max_concurrent_jobs_per_user = $11,
+	resource_ceilings = $12,
+	required_provisioner_tags = $13
 WHERE
 	id = $1
 ;
@@ -157,6 +160,17 @@ WHERE
 	id = $1
 ;
 
+-- name: GetTemplateByIDForUpdate :one
+-- Locks the template until the end of the transaction, so that concurrent
+-- read-modify-write updates of its ACL don't overwrite each other.
+SELECT
+	*
+FROM
+	templates
+WHERE
+	id = $1
+FOR UPDATE;
+
 -- name: UpdateTemplateACLByID :exec
 UPDATE
 	templates
@@ -0,0 +1,46 @@
+-- name: GetTemplateSharesByTemplateID :many
+SELECT
+	*
+FROM
+	template_shares
+WHERE
+	template_id = @template_id
+ORDER BY
+	created_at, organization_id;
+
+-- name: UpsertTemplateShare :one
+-- Sharing a template into an organization it is already shared into keeps the
+-- original share.
+INSERT INTO
+	template_shares (
+		template_id,
+		organization_id,
+		created_by,
+		created_at
+	)
+VALUES
+	(@template_id, @organization_id, @created_by, @created_at)
+ON CONFLICT (template_id, organization_id) DO UPDATE SET
+	created_at = template_shares.created_at
+RETURNING *;
+
+-- name: DeleteTemplateShare :exec
+DELETE FROM
+	template_shares
+WHERE
+	template_id = @template_id
+	AND organization_id = @organization_id;
+
+-- name: GetTemplatesSharedWithOrganization :many
+-- Returns the templates that are shared into the organization, excluding
+-- deleted templates.
+SELECT
+	templates.*
+FROM
+	template_with_names AS templates
+	INNER JOIN template_shares ON template_shares.template_id = templates.id
+WHERE
+	template_shares.organization_id = @organization_id
+	AND templates.deleted = false
+ORDER BY
+	templates.name, templates.id;
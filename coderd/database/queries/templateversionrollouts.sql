@@ -0,0 +1,75 @@
+-- name: GetTemplateVersionRolloutByTemplateID :one
+SELECT
+	*
+FROM
+	template_version_rollouts
+WHERE
+	template_id = @template_id;
+
+-- name: UpsertTemplateVersionRollout :one
+-- Starts or updates the rollout of a template. Switching to another version
+-- starts a new rollout, so its creator and start time are reset.
+INSERT INTO
+	template_version_rollouts (
+		template_id,
+		template_version_id,
+		percent,
+		group_id,
+		created_by,
+		created_at,
+		updated_at
+	)
+VALUES
+	(@template_id, @template_version_id, @percent, @group_id, @created_by, @created_at, @updated_at)
+ON CONFLICT (template_id) DO UPDATE SET
+	created_by = CASE
+		WHEN template_version_rollouts.template_version_id = EXCLUDED.template_version_id THEN template_version_rollouts.created_by
+		ELSE EXCLUDED.created_by
+	END,
+	created_at = CASE
+		WHEN template_version_rollouts.template_version_id = EXCLUDED.template_version_id THEN template_version_rollouts.created_at
+		ELSE EXCLUDED.created_at
+	END,
+	template_version_id = EXCLUDED.template_version_id,
+	percent = EXCLUDED.percent,
+	group_id = EXCLUDED.group_id,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteTemplateVersionRolloutByTemplateID :exec
+DELETE FROM
+	template_version_rollouts
+WHERE
+	template_id = @template_id;
+
+-- name: IsTemplateVersionRolloutCohortMember :one
+-- Returns whether the user is a member of the group of the rollout of the
+-- template.
+SELECT EXISTS (
+	SELECT
+		1
+	FROM
+		template_version_rollouts
+		INNER JOIN group_members_expanded ON group_members_expanded.group_id = template_version_rollouts.group_id
+	WHERE
+		template_version_rollouts.template_id = @template_id
+		AND group_members_expanded.user_id = @user_id
+);
+
+-- name: GetTemplateVersionRolloutBuildCounts :many
+-- Returns the number of completed and failed builds of each version of the
+-- template that were created since the given time.
+SELECT
+	workspace_builds.template_version_id,
+	COUNT(*) AS total_builds,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status = 'failed'::provisioner_job_status) AS failed_builds
+FROM
+	workspace_builds
+	INNER JOIN workspaces ON workspaces.id = workspace_builds.workspace_id
+	INNER JOIN provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspaces.template_id = @template_id
+	AND workspace_builds.created_at >= @created_after
+	AND provisioner_jobs.job_status IN ('succeeded'::provisioner_job_status, 'failed'::provisioner_job_status)
+GROUP BY
+	workspace_builds.template_version_id;
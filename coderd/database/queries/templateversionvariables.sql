@@ -8,7 +8,8 @@ INSERT INTO
         value,
         default_value,
         required,
-        sensitive
+        sensitive,
+        value_key_id
     )
 VALUES
     (
@@ -19,8 +20,24 @@ VALUES
         $5,
         $6,
         $7,
-        $8
+        $8,
+        $9
     ) RETURNING *;
 
 -- name: GetTemplateVersionVariables :many
 SELECT * FROM template_version_variables WHERE template_version_id = $1;
+
+-- name: GetSensitiveTemplateVersionVariables :many
+-- Returns the variables whose values are encrypted at rest when database
+-- encryption is enabled. Used to re-encrypt them when rotating keys.
+SELECT * FROM template_version_variables WHERE sensitive = true;
+
+-- name: UpdateTemplateVersionVariableValue :exec
+UPDATE
+	template_version_variables
+SET
+	value = $3,
+	value_key_id = $4
+WHERE
+	template_version_id = $1
+	AND name = $2;
@@ -110,3 +110,24 @@ WHERE
 		ELSE true
 	END
 ;
+
+-- name: GetOIDCUserClaims :many
+-- GetOIDCUserClaims returns the claims OIDC users last logged in with. This
+-- query is used to preview idp sync settings before they are applied.
+SELECT
+	users.id AS user_id,
+	users.username,
+	user_links.claims
+FROM
+	user_links
+INNER JOIN
+	users ON users.id = user_links.user_id
+WHERE
+	user_links.login_type = 'oidc'
+	AND users.deleted = false
+	AND CASE WHEN @organization_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
+		user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = @organization_id)
+		ELSE true
+	END
+ORDER BY
+	users.username ASC;
@@ -0,0 +1,33 @@
+-- name: InsertUserActivityExport :one
+INSERT INTO user_activity_exports (
+	id,
+	created_by,
+	created_at,
+	updated_at,
+	start_time,
+	end_time,
+	format
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetUserActivityExportByID :one
+SELECT * FROM user_activity_exports WHERE id = $1;
+
+-- name: GetPendingUserActivityExports :many
+-- Returns the exports that still have to be generated, oldest first.
+SELECT * FROM user_activity_exports WHERE status = 'pending' ORDER BY created_at ASC;
+
+-- name: UpdateUserActivityExportStatusByID :one
+UPDATE
+	user_activity_exports
+SET
+	status = @status,
+	file_id = @file_id,
+	error = @error,
+	updated_at = @updated_at,
+	completed_at = @completed_at
+WHERE
+	id = @id
+RETURNING *;
@@ -0,0 +1,57 @@
+-- name: UpsertWorkspaceAgentConnectionQuality :exec
+INSERT INTO
+	workspace_agent_connection_quality (
+		agent_id,
+		ip,
+		p2p,
+		derp_region_code,
+		latency_ms,
+		pings_sent,
+		pings_lost,
+		last_handshake_at,
+		created_at,
+		updated_at
+	)
+VALUES
+	(@agent_id, @ip, @p2p, @derp_region_code, @latency_ms, @pings_sent, @pings_lost, @last_handshake_at, @updated_at, @updated_at)
+ON CONFLICT (agent_id, ip) DO UPDATE SET
+	p2p = EXCLUDED.p2p,
+	derp_region_code = EXCLUDED.derp_region_code,
+	latency_ms = EXCLUDED.latency_ms,
+	pings_sent = EXCLUDED.pings_sent,
+	pings_lost = EXCLUDED.pings_lost,
+	last_handshake_at = EXCLUDED.last_handshake_at,
+	updated_at = EXCLUDED.updated_at;
+
+-- name: GetWorkspaceAgentConnectionQualityByAgentID :many
+SELECT
+	*
+FROM
+	workspace_agent_connection_quality
+WHERE
+	agent_id = @agent_id
+ORDER BY
+	updated_at DESC, ip;
+
+-- name: DeleteOldWorkspaceAgentConnectionQuality :exec
+DELETE FROM
+	workspace_agent_connection_quality
+WHERE
+	updated_at < @before_time::timestamptz;
+
+-- name: GetWorkspaceAgentConnectionTypeCounts :many
+-- Counts the connections to workspace agents reported after @updated_after by
+-- whether they are direct or relayed, and the DERP region they are relayed
+-- through.
+SELECT
+	p2p,
+	derp_region_code,
+	COUNT(*) AS count
+FROM
+	workspace_agent_connection_quality
+WHERE
+	updated_at > @updated_after
+GROUP BY
+	p2p, derp_region_code
+ORDER BY
+	p2p DESC, derp_region_code;
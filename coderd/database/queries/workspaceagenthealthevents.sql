@@ -0,0 +1,48 @@
+-- name: InsertWorkspaceAgentHealthEvent :one
+-- Records a condition detected by an agent. If the agent already has an
+-- ongoing event of the same kind for the volume, nothing is inserted and no
+-- rows are returned.
+INSERT INTO
+	workspace_agent_health_events (
+		id,
+		agent_id,
+		kind,
+		volume,
+		used,
+		total,
+		created_at
+	)
+VALUES
+	(@id, @agent_id, @kind, @volume, @used, @total, @created_at)
+ON CONFLICT (agent_id, kind, volume) WHERE resolved_at IS NULL DO NOTHING
+RETURNING *;
+
+-- name: ResolveWorkspaceAgentHealthEvent :execrows
+UPDATE
+	workspace_agent_health_events
+SET
+	resolved_at = @resolved_at
+WHERE
+	agent_id = @agent_id
+	AND kind = @kind
+	AND volume = @volume
+	AND resolved_at IS NULL;
+
+-- name: GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs :many
+SELECT
+	*
+FROM
+	workspace_agent_health_events
+WHERE
+	agent_id = ANY(@ids :: uuid[])
+	AND resolved_at IS NULL
+ORDER BY
+	created_at, kind, volume;
+
+-- name: DeleteOldWorkspaceAgentHealthEvents :exec
+-- Events that are still ongoing are deleted as well, as the agents that
+-- reported them are usually long gone.
+DELETE FROM
+	workspace_agent_health_events
+WHERE
+	COALESCE(resolved_at, created_at) < @before_time::timestamptz;
@@ -31,14 +31,20 @@ INSERT INTO
 		agent_name,
 		port,
 		share_level,
-		protocol
+		protocol,
+		shared_with_user_ids,
+		shared_with_group_ids,
+		expires_at
 	)
 VALUES (
 	$1,
 	$2,
 	$3,
 	$4,
-	$5
+	$5,
+	$6,
+	$7,
+	$8
 )
 ON CONFLICT (
 	workspace_id,
@@ -47,7 +53,10 @@ ON CONFLICT (
 )
 DO UPDATE SET
 	share_level = $4,
-	protocol = $5
+	protocol = $5,
+	shared_with_user_ids = $6,
+	shared_with_group_ids = $7,
+	expires_at = $8
 RETURNING *;
 
 -- name: ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate :exec
@@ -0,0 +1,66 @@
+-- name: UpsertWorkspaceAgentResourceUsage :exec
+-- Adds a sample to the bucket it was collected in, keeping a running average
+-- and maximum of the usage.
+INSERT INTO
+	workspace_agent_resource_usage (
+		agent_id,
+		bucket_start,
+		samples,
+		cpu_used_avg,
+		cpu_used_max,
+		cpu_total,
+		memory_used_avg,
+		memory_used_max,
+		memory_total,
+		disk_used,
+		disk_total
+	)
+VALUES
+	(
+		@agent_id,
+		@bucket_start,
+		1,
+		@cpu_used::double precision,
+		@cpu_used::double precision,
+		@cpu_total,
+		@memory_used::bigint,
+		@memory_used::bigint,
+		@memory_total,
+		@disk_used,
+		@disk_total
+	)
+ON CONFLICT (agent_id, bucket_start) DO UPDATE SET
+	samples = workspace_agent_resource_usage.samples + 1,
+	cpu_used_avg = (workspace_agent_resource_usage.cpu_used_avg * workspace_agent_resource_usage.samples + EXCLUDED.cpu_used_avg) / (workspace_agent_resource_usage.samples + 1),
+	cpu_used_max = GREATEST(workspace_agent_resource_usage.cpu_used_max, EXCLUDED.cpu_used_max),
+	cpu_total = EXCLUDED.cpu_total,
+	memory_used_avg = (workspace_agent_resource_usage.memory_used_avg * workspace_agent_resource_usage.samples + EXCLUDED.memory_used_avg) / (workspace_agent_resource_usage.samples + 1),
+	memory_used_max = GREATEST(workspace_agent_resource_usage.memory_used_max, EXCLUDED.memory_used_max),
+	memory_total = EXCLUDED.memory_total,
+	disk_used = EXCLUDED.disk_used,
+	disk_total = EXCLUDED.disk_total;
+
+-- name: GetWorkspaceAgentResourceUsageByWorkspaceID :many
+-- Returns the resource usage of the agents of all builds of the workspace, so
+-- that usage can be followed across builds by agent name.
+SELECT
+	workspace_agent_resource_usage.*,
+	workspace_agents.name AS agent_name
+FROM
+	workspace_agent_resource_usage
+INNER JOIN
+	workspace_agents ON workspace_agents.id = workspace_agent_resource_usage.agent_id
+INNER JOIN
+	workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+INNER JOIN
+	workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+WHERE
+	workspace_builds.workspace_id = @workspace_id
+	AND workspace_agent_resource_usage.bucket_start >= @start_time
+	AND workspace_agent_resource_usage.bucket_start < @end_time
+ORDER BY
+	workspace_agents.name,
+	workspace_agent_resource_usage.bucket_start;
+
+-- name: DeleteOldWorkspaceAgentResourceUsage :exec
+DELETE FROM workspace_agent_resource_usage WHERE bucket_start < @before_time::timestamptz;
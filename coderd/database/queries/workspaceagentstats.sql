@@ -54,6 +54,24 @@ GROUP BY
 ORDER BY
 	date ASC;
 
+-- name: DropOldWorkspaceAgentStatsPartitions :one
+-- Drops the workspace_agent_stats partitions that only hold rows older than
+-- the cutoff used by DeleteOldWorkspaceAgentStats. Returns the number of
+-- dropped partitions.
+SELECT
+	drop_time_partitions(
+		'workspace_agent_stats',
+		(
+			SELECT
+				LEAST(
+					COALESCE(MAX(start_time) - '1 days'::interval, NOW() - '180 days'::interval),
+					@before :: timestamptz
+				)
+			FROM
+				template_usage_stats
+		)
+	)::integer AS dropped;
+
 -- name: GetDeploymentDAUs :many
 SELECT
 	(created_at at TIME ZONE cast(@tz_offset::integer as text))::date as date,
@@ -67,23 +85,28 @@ GROUP BY
 ORDER BY
 	date ASC;
 
--- name: DeleteOldWorkspaceAgentStats :exec
+-- name: DeleteOldWorkspaceAgentStats :execrows
+-- Deletes raw agent stats created before @before, but never those that may
+-- not have been rolled up into template usage stats yet.
 DELETE FROM
 	workspace_agent_stats
 WHERE
 	created_at < (
 		SELECT
-			COALESCE(
-				-- When generating initial template usage stats, all the
-				-- raw agent stats are needed, after that only ~30 mins
-				-- from last rollup is needed. Deployment stats seem to
-				-- use between 15 mins and 1 hour of data. We keep a
-				-- little bit more (1 day) just in case.
-				MAX(start_time) - '1 days'::interval,
-				-- Fall back to ~6 months ago if there are no template
-				-- usage stats so that we don't delete the data before
-				-- it's rolled up.
-				NOW() - '180 days'::interval
+			LEAST(
+				COALESCE(
+					-- When generating initial template usage stats, all the
+					-- raw agent stats are needed, after that only ~30 mins
+					-- from last rollup is needed. Deployment stats seem to
+					-- use between 15 mins and 1 hour of data. We keep a
+					-- little bit more (1 day) just in case.
+					MAX(start_time) - '1 days'::interval,
+					-- Fall back to ~6 months ago if there are no template
+					-- usage stats so that we don't delete the data before
+					-- it's rolled up.
+					NOW() - '180 days'::interval
+				),
+				@before :: timestamptz
 			)
 		FROM
 			template_usage_stats
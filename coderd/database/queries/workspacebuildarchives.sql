@@ -0,0 +1,63 @@
+-- name: GetWorkspaceBuildsToArchive :many
+-- Returns builds that are not the latest build of their workspace, whose
+-- job completed before the given time and that are not archived yet,
+-- oldest first.
+SELECT
+	workspace_builds.id,
+	workspace_builds.job_id,
+	workspace_builds.initiator_id
+FROM
+	workspace_builds
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	provisioner_jobs.completed_at < @completed_before :: timestamptz
+	AND workspace_builds.build_number < (
+		SELECT
+			MAX(latest.build_number)
+		FROM
+			workspace_builds AS latest
+		WHERE
+			latest.workspace_id = workspace_builds.workspace_id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM workspace_build_archives WHERE workspace_build_archives.workspace_build_id = workspace_builds.id
+	)
+ORDER BY
+	provisioner_jobs.completed_at ASC
+LIMIT
+	@limit_count :: int;
+
+-- name: GetWorkspaceBuildArchiveByBuildID :one
+SELECT
+	*
+FROM
+	workspace_build_archives
+WHERE
+	workspace_build_id = @workspace_build_id;
+
+-- name: InsertWorkspaceBuildArchive :one
+INSERT INTO
+	workspace_build_archives (
+		workspace_build_id,
+		file_id,
+		state_size,
+		resource_metadata_count,
+		archived_at
+	)
+VALUES
+	(@workspace_build_id, @file_id, @state_size, @resource_metadata_count, @archived_at) RETURNING *;
+
+-- name: DeleteWorkspaceBuildArchiveByBuildID :exec
+-- Deletes the archive of a build together with the file holding its data.
+WITH deleted AS (
+	DELETE FROM
+		workspace_build_archives
+	WHERE
+		workspace_build_id = @workspace_build_id
+	RETURNING file_id
+)
+DELETE FROM
+	files
+WHERE
+	id IN (SELECT file_id FROM deleted);
@@ -0,0 +1,66 @@
+-- name: GetRunningWorkspaceBuildsExceedingDurationThreshold :many
+-- Returns the running workspace builds which have been running for longer
+-- than the build duration notification threshold of the workspace owner, and
+-- whose owner wasn't notified yet.
+WITH thresholds AS MATERIALIZED (
+	-- Materialized so the cast only applies to threshold values.
+	SELECT
+		user_id,
+		value::bigint AS threshold_ms
+	FROM
+		user_configs
+	WHERE
+		key = 'notification_build_duration_threshold_ms'
+)
+SELECT
+	wb.id AS workspace_build_id,
+	wb.build_number,
+	wb.transition,
+	w.id AS workspace_id,
+	w.name AS workspace_name,
+	w.owner_id,
+	w.template_id,
+	w.organization_id,
+	t.threshold_ms
+FROM
+	workspace_builds AS wb
+JOIN
+	provisioner_jobs AS pj
+ON
+	wb.job_id = pj.id
+JOIN
+	workspaces AS w
+ON
+	wb.workspace_id = w.id
+JOIN
+	thresholds AS t
+ON
+	w.owner_id = t.user_id
+WHERE
+	pj.job_status = 'running'
+	AND t.threshold_ms > 0
+	AND w.deleted = false
+	AND pj.started_at + t.threshold_ms * INTERVAL '1 millisecond' <= @now::timestamptz
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			workspace_build_duration_notifications AS n
+		WHERE
+			n.workspace_build_id = wb.id
+	)
+ORDER BY
+	pj.started_at ASC;
+
+-- name: InsertWorkspaceBuildDurationNotification :execrows
+-- Records that the owner of a workspace build was notified about its
+-- duration. No rows are affected if the build was already notified, e.g. by
+-- another replica.
+INSERT INTO
+	workspace_build_duration_notifications (
+		workspace_build_id,
+		notified_at
+	)
+VALUES
+	(@workspace_build_id, @notified_at)
+ON CONFLICT (workspace_build_id) DO NOTHING;
@@ -0,0 +1,36 @@
+-- name: GetWorkspaceBuildIdempotencyKey :one
+SELECT
+	*
+FROM
+	workspace_build_idempotency_keys
+WHERE
+	user_id = @user_id
+	AND idempotency_key = @idempotency_key;
+
+-- name: InsertWorkspaceBuildIdempotencyKey :exec
+-- Reserves the key before the build of the request is created. A concurrent
+-- insert of the same key waits for the transaction that reserved it.
+INSERT INTO
+	workspace_build_idempotency_keys (
+		user_id,
+		idempotency_key,
+		request_hash,
+		created_at
+	)
+VALUES
+	(@user_id, @idempotency_key, @request_hash, @created_at);
+
+-- name: UpdateWorkspaceBuildIdempotencyKeyBuildID :exec
+UPDATE
+	workspace_build_idempotency_keys
+SET
+	workspace_build_id = @workspace_build_id :: uuid
+WHERE
+	user_id = @user_id
+	AND idempotency_key = @idempotency_key;
+
+-- name: DeleteOldWorkspaceBuildIdempotencyKeys :exec
+DELETE FROM
+	workspace_build_idempotency_keys
+WHERE
+	created_at < @before;
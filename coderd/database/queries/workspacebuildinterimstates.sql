@@ -0,0 +1,26 @@
+-- name: GetWorkspaceBuildInterimStateByBuildID :one
+SELECT
+	*
+FROM
+	workspace_build_interim_states
+WHERE
+	workspace_build_id = @workspace_build_id;
+
+-- name: UpsertWorkspaceBuildInterimState :exec
+INSERT INTO
+	workspace_build_interim_states (
+		workspace_build_id,
+		state,
+		updated_at
+	)
+VALUES
+	(@workspace_build_id, @state, @updated_at)
+ON CONFLICT (workspace_build_id) DO UPDATE SET
+	state = EXCLUDED.state,
+	updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteWorkspaceBuildInterimState :exec
+DELETE FROM
+	workspace_build_interim_states
+WHERE
+	workspace_build_id = @workspace_build_id;
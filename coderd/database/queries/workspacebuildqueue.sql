@@ -0,0 +1,67 @@
+-- name: InsertWorkspaceBuildQueueEntry :one
+INSERT INTO
+	workspace_build_queue (
+		id,
+		workspace_id,
+		initiator_id,
+		transition,
+		template_version_id,
+		template_version_preset_id,
+		rich_parameter_values,
+		log_level,
+		created_at
+	)
+VALUES
+	(@id, @workspace_id, @initiator_id, @transition, @template_version_id, @template_version_preset_id, @rich_parameter_values, @log_level, @created_at)
+RETURNING *;
+
+-- name: GetWorkspaceBuildQueueEntryByID :one
+SELECT
+	*
+FROM
+	workspace_build_queue
+WHERE
+	id = @id;
+
+-- name: GetWorkspaceBuildQueueByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_build_queue
+WHERE
+	workspace_id = @workspace_id
+ORDER BY
+	created_at ASC;
+
+-- name: GetStartableWorkspaceBuildQueueEntries :many
+-- Returns the oldest entry that has not failed of each workspace whose latest
+-- build is not active.
+SELECT DISTINCT ON (workspace_build_queue.workspace_id)
+	workspace_build_queue.*
+FROM
+	workspace_build_queue
+	INNER JOIN workspace_latest_builds ON workspace_latest_builds.workspace_id = workspace_build_queue.workspace_id
+WHERE
+	workspace_build_queue.failed_at IS NULL
+	AND (
+		workspace_latest_builds.job_status IS NULL
+		OR workspace_latest_builds.job_status NOT IN ('pending'::provisioner_job_status, 'running'::provisioner_job_status, 'canceling'::provisioner_job_status)
+	)
+ORDER BY
+	workspace_build_queue.workspace_id,
+	workspace_build_queue.created_at ASC;
+
+-- name: UpdateWorkspaceBuildQueueEntryFailed :exec
+UPDATE
+	workspace_build_queue
+SET
+	failed_at = @failed_at,
+	error = @error
+WHERE
+	id = @id;
+
+-- name: DeleteWorkspaceBuildQueueEntry :exec
+DELETE FROM
+	workspace_build_queue
+WHERE
+	id = @id;
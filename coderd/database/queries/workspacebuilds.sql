@@ -75,6 +75,26 @@ ORDER BY
 LIMIT
 	1;
 
+-- name: GetLatestSuccessfulWorkspaceBuildByWorkspaceID :one
+-- Returns the latest start build of the workspace whose job succeeded, which
+-- is the build a workspace is rolled back to.
+SELECT
+	workspace_builds.*
+FROM
+	workspace_build_with_user AS workspace_builds
+JOIN
+	provisioner_jobs
+ON
+	provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_builds.workspace_id = $1
+	AND workspace_builds.transition = 'start'
+	AND provisioner_jobs.job_status = 'succeeded'
+ORDER BY
+	workspace_builds.build_number DESC
+LIMIT
+	1;
+
 -- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
 SELECT wb.*
 FROM (
@@ -121,10 +141,12 @@ INSERT INTO
 		deadline,
 		max_deadline,
 		reason,
-		template_version_preset_id
+		template_version_preset_id,
+		initiator_context,
+		warnings
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);
 
 -- name: UpdateWorkspaceBuildCostByID :exec
 UPDATE
@@ -253,3 +275,34 @@ WHERE
 	AND pj.job_status = 'failed'
 ORDER BY
 	tv.name ASC, wb.build_number DESC;
+
+-- name: GetWorkspaceBuildOutcomesCompletedBetween :many
+-- Returns the outcome of every workspace build whose provisioner job
+-- completed in the given time range, along with the template of the
+-- workspace.
+SELECT
+	t.name AS template_name,
+	wb.transition,
+	pj.job_status,
+	pj.error_code,
+	pj.started_at,
+	pj.completed_at
+FROM
+	workspace_builds AS wb
+JOIN
+	provisioner_jobs AS pj
+ON
+	wb.job_id = pj.id
+JOIN
+	workspaces AS w
+ON
+	wb.workspace_id = w.id
+JOIN
+	templates AS t
+ON
+	w.template_id = t.id
+WHERE
+	pj.completed_at > @completed_after :: timestamptz
+	AND pj.completed_at <= @completed_before :: timestamptz
+ORDER BY
+	pj.completed_at ASC;
@@ -0,0 +1,184 @@
+-- name: GetWorkspaceDailyCosts :many
+-- GetWorkspaceDailyCosts returns the daily costs of workspaces in the buckets
+-- that start within the given period, along with the names of the workspaces,
+-- their owners and their organizations. The result can be filtered on
+-- template_ids, meaning only the costs of workspaces based on those templates
+-- will be included.
+SELECT
+	wdc.start_time,
+	wdc.end_time,
+	wdc.workspace_id,
+	w.name AS workspace_name,
+	wdc.owner_id,
+	u.username AS owner_username,
+	wdc.organization_id,
+	o.name AS organization_name,
+	wdc.template_id,
+	wdc.cost
+FROM
+	workspace_daily_costs wdc
+JOIN
+	workspaces w
+ON
+	w.id = wdc.workspace_id
+JOIN
+	users u
+ON
+	u.id = wdc.owner_id
+JOIN
+	organizations o
+ON
+	o.id = wdc.organization_id
+WHERE
+	wdc.start_time >= @start_time::timestamptz
+	AND wdc.start_time < @end_time::timestamptz
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN wdc.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+ORDER BY
+	wdc.start_time ASC,
+	wdc.workspace_id ASC;
+
+-- name: UpsertWorkspaceDailyCosts :exec
+-- This query prorates the daily cost of the resources of each successful
+-- workspace build over the time it was the latest successful build of its
+-- workspace, and stores the cost accrued by each workspace in daily buckets
+-- in the workspace_daily_costs table. The most recent bucket and the one
+-- before it are recomputed on every run, since costs keep accruing in them.
+WITH
+	latest_start AS (
+		SELECT
+			COALESCE(
+				MAX(start_time) - '1 day'::interval,
+				-- Fallback when there are no daily costs yet.
+				date_trunc('day', (
+					SELECT MIN(completed_at) FROM provisioner_jobs WHERE type = 'workspace_build'
+				) AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+			) AS t
+		FROM
+			workspace_daily_costs
+	),
+	buckets AS (
+		SELECT
+			-- Buckets are UTC days regardless of the time zone of the
+			-- database session.
+			generate_series(
+				(SELECT t FROM latest_start),
+				date_trunc('day', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+				'1 day'::interval
+			) AS start_time
+	),
+	-- Only the builds that are current at or after latest_start are
+	-- prorated: those completed since, and the latest one completed before
+	-- it for each workspace. This keeps the window below from sorting the
+	-- whole build history on every run.
+	current_builds AS (
+		SELECT
+			wb.workspace_id,
+			wb.build_number,
+			wb.job_id,
+			wb.transition,
+			pj.completed_at
+		FROM
+			workspace_builds wb
+		JOIN
+			provisioner_jobs pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			pj.completed_at >= (SELECT t FROM latest_start)
+			AND pj.canceled_at IS NULL
+			AND (pj.error IS NULL OR pj.error = '')
+		UNION ALL
+		SELECT
+			prev.*
+		FROM
+			workspaces w
+		CROSS JOIN LATERAL (
+			SELECT
+				wb.workspace_id,
+				wb.build_number,
+				wb.job_id,
+				wb.transition,
+				pj.completed_at
+			FROM
+				workspace_builds wb
+			JOIN
+				provisioner_jobs pj
+			ON
+				pj.id = wb.job_id
+			WHERE
+				wb.workspace_id = w.id
+				AND pj.completed_at < (SELECT t FROM latest_start)
+				AND pj.canceled_at IS NULL
+				AND (pj.error IS NULL OR pj.error = '')
+			ORDER BY
+				wb.build_number DESC
+			LIMIT 1
+		) prev
+	),
+	build_costs AS (
+		SELECT
+			cb.workspace_id,
+			w.organization_id,
+			w.owner_id,
+			w.template_id,
+			-- Deleted workspaces don't cost anything, whatever resources
+			-- remain in the state.
+			CASE WHEN cb.transition = 'delete' THEN 0 ELSE (
+				SELECT COALESCE(SUM(wr.daily_cost), 0) FROM workspace_resources wr WHERE wr.job_id = cb.job_id
+			) END AS daily_cost,
+			cb.completed_at AS current_from,
+			COALESCE(
+				LEAD(cb.completed_at) OVER (PARTITION BY cb.workspace_id ORDER BY cb.build_number),
+				NOW()
+			) AS current_until
+		FROM
+			current_builds cb
+		JOIN
+			workspaces w
+		ON
+			w.id = cb.workspace_id
+	)
+INSERT INTO workspace_daily_costs AS wdc (
+	start_time,
+	end_time,
+	workspace_id,
+	organization_id,
+	owner_id,
+	template_id,
+	cost
+)
+SELECT
+	b.start_time,
+	b.start_time + '1 day'::interval AS end_time,
+	bc.workspace_id,
+	bc.organization_id,
+	bc.owner_id,
+	bc.template_id,
+	SUM(
+		bc.daily_cost * EXTRACT(EPOCH FROM (
+			LEAST(bc.current_until, b.start_time + '1 day'::interval) - GREATEST(bc.current_from, b.start_time)
+		)) / 86400
+	)::double precision AS cost
+FROM
+	build_costs bc
+JOIN
+	buckets b
+ON
+	bc.current_from < b.start_time + '1 day'::interval
+	AND bc.current_until > b.start_time
+WHERE
+	bc.daily_cost > 0
+GROUP BY
+	b.start_time, bc.workspace_id, bc.organization_id, bc.owner_id, bc.template_id
+ON CONFLICT
+	(start_time, workspace_id)
+DO UPDATE
+SET
+	organization_id = EXCLUDED.organization_id,
+	owner_id = EXCLUDED.owner_id,
+	template_id = EXCLUDED.template_id,
+	cost = EXCLUDED.cost
+WHERE
+	(wdc.organization_id, wdc.owner_id, wdc.template_id, wdc.cost)
+	IS DISTINCT FROM
+	(EXCLUDED.organization_id, EXCLUDED.owner_id, EXCLUDED.template_id, EXCLUDED.cost);
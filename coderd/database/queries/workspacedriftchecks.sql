@@ -0,0 +1,82 @@
+-- name: GetWorkspaceDriftCheckCandidates :many
+-- Returns the latest builds of stopped or failed workspaces that have not been
+-- checked for drift since @checked_before, least recently checked first.
+SELECT
+	workspaces.id AS workspace_id,
+	workspaces.owner_id,
+	workspaces.organization_id,
+	workspace_latest_builds.id AS workspace_build_id,
+	workspace_latest_builds.template_version_id,
+	build_jobs.tags,
+	template_version_jobs.file_id,
+	template_version_jobs.provisioner
+FROM
+	workspaces
+	INNER JOIN workspace_latest_builds ON workspace_latest_builds.workspace_id = workspaces.id
+	INNER JOIN provisioner_jobs build_jobs ON build_jobs.id = workspace_latest_builds.job_id
+	INNER JOIN template_versions ON template_versions.id = workspace_latest_builds.template_version_id
+	INNER JOIN provisioner_jobs template_version_jobs ON template_version_jobs.id = template_versions.job_id
+	LEFT JOIN workspace_drift_checks ON workspace_drift_checks.workspace_id = workspaces.id
+WHERE
+	workspaces.deleted = false
+	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+	AND (
+		(
+			workspace_latest_builds.transition = 'stop'::workspace_transition
+			AND workspace_latest_builds.job_status = 'succeeded'::provisioner_job_status
+		)
+		OR (
+			workspace_latest_builds.transition != 'delete'::workspace_transition
+			AND workspace_latest_builds.job_status = 'failed'::provisioner_job_status
+		)
+	)
+	AND (
+		workspace_drift_checks.workspace_id IS NULL
+		OR workspace_drift_checks.created_at < @checked_before
+	)
+ORDER BY
+	workspace_drift_checks.created_at ASC NULLS FIRST
+LIMIT
+	@limit_count :: int;
+
+-- name: GetWorkspaceDriftChecksByWorkspaceIDs :many
+SELECT
+	workspace_drift_checks.*,
+	provisioner_jobs.job_status
+FROM
+	workspace_drift_checks
+	INNER JOIN provisioner_jobs ON provisioner_jobs.id = workspace_drift_checks.job_id
+WHERE
+	workspace_drift_checks.workspace_id = ANY(@ids :: uuid[]);
+
+-- name: UpsertWorkspaceDriftCheck :exec
+-- Replaces the drift check of the workspace with a new check, clearing the
+-- results of the previous one.
+INSERT INTO
+	workspace_drift_checks (
+		workspace_id,
+		workspace_build_id,
+		job_id,
+		created_at
+	)
+VALUES
+	(@workspace_id, @workspace_build_id, @job_id, @created_at)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	workspace_build_id = EXCLUDED.workspace_build_id,
+	job_id = EXCLUDED.job_id,
+	created_at = EXCLUDED.created_at,
+	completed_at = NULL,
+	drifted_resources = '{}'::text[],
+	orphaned_resources = '{}'::text[];
+
+-- name: UpdateWorkspaceDriftCheckByJobID :one
+UPDATE
+	workspace_drift_checks
+SET
+	completed_at = @completed_at,
+	drifted_resources = @drifted_resources :: text[],
+	orphaned_resources = @orphaned_resources :: text[]
+WHERE
+	job_id = @job_id
+RETURNING
+	*;
@@ -0,0 +1,24 @@
+-- name: GetWorkspaceLabelsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_labels
+WHERE
+	workspace_id = @workspace_id
+ORDER BY
+	key ASC;
+
+-- name: DeleteWorkspaceLabelsByWorkspaceID :exec
+DELETE FROM
+	workspace_labels
+WHERE
+	workspace_id = @workspace_id;
+
+-- name: InsertWorkspaceLabels :exec
+INSERT INTO
+	workspace_labels (workspace_id, key, value, created_at)
+SELECT
+	@workspace_id,
+	unnest(@keys :: TEXT[]),
+	unnest(@values :: TEXT[]),
+	@created_at;
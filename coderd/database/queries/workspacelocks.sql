@@ -0,0 +1,37 @@
+-- name: InsertWorkspaceLock :one
+INSERT INTO
+	workspace_locks (id, workspace_id, reason, locked_by, locked_at)
+VALUES
+	(@id, @workspace_id, @reason, @locked_by, @locked_at)
+RETURNING *;
+
+-- name: GetActiveWorkspaceLockByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_locks
+WHERE
+	workspace_id = @workspace_id
+	AND unlocked_at IS NULL;
+
+-- name: GetWorkspaceLocksByWorkspaceID :many
+-- Returns the current and lifted locks of a workspace, newest first.
+SELECT
+	*
+FROM
+	workspace_locks
+WHERE
+	workspace_id = @workspace_id
+ORDER BY
+	locked_at DESC;
+
+-- name: UnlockWorkspace :one
+UPDATE
+	workspace_locks
+SET
+	unlocked_by = @unlocked_by,
+	unlocked_at = @unlocked_at
+WHERE
+	workspace_id = @workspace_id
+	AND unlocked_at IS NULL
+RETURNING *;
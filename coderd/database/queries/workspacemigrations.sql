@@ -0,0 +1,86 @@
+-- name: InsertWorkspaceMigration :one
+INSERT INTO
+	workspace_migrations (
+		id,
+		workspace_id,
+		initiator_id,
+		destination_url,
+		destination_organization_id,
+		destination_owner,
+		destination_template_id,
+		destination_name,
+		destination_session_token,
+		destination_session_token_key_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	(@id, @workspace_id, @initiator_id, @destination_url, @destination_organization_id, @destination_owner, @destination_template_id, @destination_name, @destination_session_token, @destination_session_token_key_id, @created_at, @updated_at)
+RETURNING *;
+
+-- name: GetWorkspaceMigrationByID :one
+SELECT
+	*
+FROM
+	workspace_migrations
+WHERE
+	id = @id;
+
+-- name: GetWorkspaceMigrationsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_migrations
+WHERE
+	workspace_id = @workspace_id
+ORDER BY
+	created_at DESC;
+
+-- name: GetActiveWorkspaceMigrations :many
+-- Returns the migrations that have not completed, oldest first.
+SELECT
+	*
+FROM
+	workspace_migrations
+WHERE
+	completed_at IS NULL
+ORDER BY
+	created_at ASC;
+
+-- name: UpdateWorkspaceMigrationStatus :one
+UPDATE
+	workspace_migrations
+SET
+	status = @status,
+	source_build_id = @source_build_id,
+	destination_workspace_id = @destination_workspace_id,
+	destination_build_id = @destination_build_id,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: CompleteWorkspaceMigration :one
+-- The destination session token is only needed while the migration runs, so
+-- it is cleared once the migration completes.
+UPDATE
+	workspace_migrations
+SET
+	status = @status,
+	error = @error,
+	destination_session_token = '',
+	destination_session_token_key_id = NULL,
+	updated_at = @completed_at,
+	completed_at = @completed_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: UpdateWorkspaceMigrationSessionToken :exec
+UPDATE
+	workspace_migrations
+SET
+	destination_session_token = @destination_session_token,
+	destination_session_token_key_id = @destination_session_token_key_id
+WHERE
+	id = @id;
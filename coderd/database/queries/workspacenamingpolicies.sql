@@ -0,0 +1,80 @@
+-- name: GetOrganizationWorkspaceNamingPolicy :one
+SELECT
+	*
+FROM
+	workspace_naming_policies
+WHERE
+	organization_id = @organization_id
+	AND template_id IS NULL;
+
+-- name: GetTemplateWorkspaceNamingPolicy :one
+SELECT
+	*
+FROM
+	workspace_naming_policies
+WHERE
+	template_id = @template_id::uuid;
+
+-- name: UpsertOrganizationWorkspaceNamingPolicy :one
+INSERT INTO
+	workspace_naming_policies (
+		id,
+		organization_id,
+		regex,
+		prefix,
+		suffix,
+		uniqueness_scope,
+		created_at,
+		updated_at
+	)
+VALUES (
+	@id,
+	@organization_id,
+	@regex,
+	@prefix,
+	@suffix,
+	@uniqueness_scope,
+	@updated_at,
+	@updated_at
+)
+ON CONFLICT (organization_id) WHERE template_id IS NULL
+DO UPDATE SET
+	regex = @regex,
+	prefix = @prefix,
+	suffix = @suffix,
+	uniqueness_scope = @uniqueness_scope,
+	updated_at = @updated_at
+RETURNING *;
+
+-- name: UpsertTemplateWorkspaceNamingPolicy :one
+INSERT INTO
+	workspace_naming_policies (
+		id,
+		organization_id,
+		template_id,
+		regex,
+		prefix,
+		suffix,
+		uniqueness_scope,
+		created_at,
+		updated_at
+	)
+VALUES (
+	@id,
+	@organization_id,
+	@template_id::uuid,
+	@regex,
+	@prefix,
+	@suffix,
+	@uniqueness_scope,
+	@updated_at,
+	@updated_at
+)
+ON CONFLICT (template_id) WHERE template_id IS NOT NULL
+DO UPDATE SET
+	regex = @regex,
+	prefix = @prefix,
+	suffix = @suffix,
+	uniqueness_scope = @uniqueness_scope,
+	updated_at = @updated_at
+RETURNING *;
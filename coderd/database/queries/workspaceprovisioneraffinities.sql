@@ -0,0 +1,25 @@
+-- name: UpsertWorkspaceProvisionerAffinity :exec
+-- Records the provisioner daemon that built a workspace successfully, so
+-- the next build of the same template version prefers it.
+INSERT INTO
+	workspace_provisioner_affinities (
+		workspace_id,
+		provisioner_daemon_id,
+		template_version_id,
+		updated_at
+	)
+VALUES
+	(@workspace_id, @provisioner_daemon_id, @template_version_id, @updated_at)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	provisioner_daemon_id = EXCLUDED.provisioner_daemon_id,
+	template_version_id = EXCLUDED.template_version_id,
+	updated_at = EXCLUDED.updated_at;
+
+-- name: GetWorkspaceProvisionerAffinity :one
+SELECT
+	*
+FROM
+	workspace_provisioner_affinities
+WHERE
+	workspace_id = @workspace_id;
@@ -27,9 +27,9 @@ SELECT * FROM workspace_resources WHERE created_at > $1;
 
 -- name: InsertWorkspaceResource :one
 INSERT INTO
-	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path)
+	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, display_group, display_order, collapsed)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING *;
 
 -- name: GetWorkspaceResourceMetadataByResourceIDs :many
 SELECT
@@ -52,3 +52,9 @@ SELECT
 SELECT * FROM workspace_resource_metadata WHERE workspace_resource_id = ANY(
 	SELECT id FROM workspace_resources WHERE created_at > $1
 );
+
+-- name: DeleteWorkspaceResourceMetadataByResourceIDs :exec
+DELETE FROM
+	workspace_resource_metadata
+WHERE
+	workspace_resource_id = ANY(@ids :: uuid [ ]);
@@ -370,6 +370,29 @@ WHERE
 			)) = (sqlc.narg('has_ai_task') :: boolean)
 		ELSE true
 	END
+	-- Filter by workspace labels
+	-- @label_keys and @label_values are matched by index. An empty value
+	-- matches any workspace that has the label, whatever its value.
+	AND CASE WHEN array_length(@label_keys :: text[], 1) > 0 THEN
+		NOT EXISTS (
+			SELECT
+				1
+			FROM
+				unnest(@label_keys :: text[], @label_values :: text[]) AS wanted(key, value)
+			WHERE
+				NOT EXISTS (
+					SELECT
+						1
+					FROM
+						workspace_labels
+					WHERE
+						workspace_labels.workspace_id = workspaces.id AND
+						workspace_labels.key = wanted.key AND
+						(wanted.value = '' OR LOWER(workspace_labels.value) = wanted.value)
+				)
+		)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaces
 	-- @authorize_filter
 ), filtered_workspaces_order AS (
@@ -377,6 +400,41 @@ WHERE
 		fw.*
 	FROM
 		filtered_workspaces fw
+	WHERE
+		CASE
+			-- This allows using the last workspace on a page as a cursor.
+			-- Workspaces are compared by the columns they are ordered by.
+			-- Running workspaces are ordered first, so a workspace that
+			-- starts or stops between two requests may move across the
+			-- cursor.
+			WHEN @after_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+				(
+					CASE WHEN fw.owner_id = @requester_id AND fw.favorite THEN 0 ELSE 1 END,
+					NOT (fw.latest_build_completed_at IS NOT NULL AND
+						fw.latest_build_canceled_at IS NULL AND
+						fw.latest_build_error IS NULL AND
+						fw.latest_build_transition = 'start'::workspace_transition),
+					LOWER(fw.owner_username),
+					LOWER(fw.name),
+					fw.id
+				) > (
+					SELECT
+						CASE WHEN cursor_fw.owner_id = @requester_id AND cursor_fw.favorite THEN 0 ELSE 1 END,
+						NOT (cursor_fw.latest_build_completed_at IS NOT NULL AND
+							cursor_fw.latest_build_canceled_at IS NULL AND
+							cursor_fw.latest_build_error IS NULL AND
+							cursor_fw.latest_build_transition = 'start'::workspace_transition),
+						LOWER(cursor_fw.owner_username),
+						LOWER(cursor_fw.name),
+						cursor_fw.id
+					FROM
+						filtered_workspaces cursor_fw
+					WHERE
+						cursor_fw.id = @after_id
+				)
+			)
+			ELSE true
+		END
 	ORDER BY
 		-- To ensure that 'favorite' workspaces show up first in the list only for their owner.
 		CASE WHEN owner_id = @requester_id AND favorite THEN 0 ELSE 1 END ASC,
@@ -385,7 +443,9 @@ WHERE
 			latest_build_error IS NULL AND
 			latest_build_transition = 'start'::workspace_transition) DESC,
 		LOWER(owner_username) ASC,
-		LOWER(name) ASC
+		LOWER(name) ASC,
+		-- Order by ID as well, so the order is stable for the cursor.
+		id ASC
 	LIMIT
 		CASE
 			WHEN @limit_ :: integer > 0 THEN
@@ -418,6 +478,8 @@ WHERE
 		'never'::automatic_updates, -- automatic_updates
 		false, -- favorite
 		'0001-01-01 00:00:00+00'::timestamptz, -- next_start_at
+		'{}'::jsonb, -- group_acl
+		'{}'::jsonb, -- user_acl
 		'', -- owner_avatar_url
 		'', -- owner_username
 		'', -- owner_name
@@ -454,6 +516,18 @@ FROM
 CROSS JOIN
 	total_count tc;
 
+-- name: GetWorkspaceByOrganizationIDAndName :one
+SELECT
+	*
+FROM
+	workspaces_expanded as workspaces
+WHERE
+	organization_id = @organization_id
+	AND deleted = false
+	AND LOWER("name") = LOWER(@name)
+ORDER BY created_at DESC
+LIMIT 1;
+
 -- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
 	*
@@ -499,6 +573,62 @@ SET
 WHERE
 	id = $1;
 
+-- name: DeleteOldDeletedWorkspaces :execrows
+-- Permanently removes workspaces whose delete build completed before the given
+-- time, along with their builds, provisioner jobs and job logs. Until then,
+-- deleted workspaces can be restored.
+WITH purged_workspaces AS (
+	SELECT
+		workspaces.id
+	FROM
+		workspaces
+	JOIN LATERAL (
+		SELECT
+			workspace_builds.job_id
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+		ORDER BY
+			workspace_builds.build_number DESC
+		LIMIT 1
+	) latest_build ON TRUE
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = latest_build.job_id
+	WHERE
+		workspaces.deleted
+		AND provisioner_jobs.completed_at < @before :: timestamptz
+),
+purged_jobs AS (
+	DELETE FROM
+		provisioner_jobs
+	WHERE
+		id IN (
+			SELECT
+				job_id
+			FROM
+				workspace_builds
+			WHERE
+				workspace_id IN (SELECT id FROM purged_workspaces)
+		)
+),
+purged_app_stats AS (
+	DELETE FROM
+		workspace_app_stats
+	WHERE
+		workspace_id IN (SELECT id FROM purged_workspaces)
+),
+purged_app_statuses AS (
+	DELETE FROM
+		workspace_app_statuses
+	WHERE
+		workspace_id IN (SELECT id FROM purged_workspaces)
+)
+DELETE FROM
+	workspaces
+WHERE
+	id IN (SELECT id FROM purged_workspaces);
+
 -- name: UpdateWorkspace :one
 UPDATE
 	workspaces
@@ -817,6 +947,26 @@ SET
 WHERE
 		id = $1;
 
+-- name: GetWorkspaceByIDForUpdate :one
+-- Locks the workspace until the end of the transaction, so that concurrent
+-- read-modify-write updates of its ACL don't overwrite each other.
+SELECT
+	*
+FROM
+	workspaces
+WHERE
+	id = $1
+FOR UPDATE;
+
+-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	group_acl = @group_acl,
+	user_acl = @user_acl
+WHERE
+	id = @id;
+
 -- name: FavoriteWorkspace :exec
 UPDATE workspaces SET favorite = true WHERE id = @id;
 
@@ -1,6 +1,6 @@
 -- name: InsertWorkspaceAgentScripts :many
 INSERT INTO
-	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id)
+	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, run_on_claim, timeout_seconds, display_name, id)
 SELECT
 	@workspace_agent_id :: uuid AS workspace_agent_id,
 	@created_at :: timestamptz AS created_at,
@@ -11,6 +11,7 @@ SELECT
 	unnest(@start_blocks_login :: boolean [ ]) AS start_blocks_login,
 	unnest(@run_on_start :: boolean [ ]) AS run_on_start,
 	unnest(@run_on_stop :: boolean [ ]) AS run_on_stop,
+	unnest(@run_on_claim :: boolean [ ]) AS run_on_claim,
 	unnest(@timeout_seconds :: integer [ ]) AS timeout_seconds,
 	unnest(@display_name :: text [ ]) AS display_name,
 	unnest(@id :: uuid [ ]) AS id
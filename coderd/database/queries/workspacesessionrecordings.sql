@@ -0,0 +1,94 @@
+-- name: InsertWorkspaceSessionRecording :one
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		workspace_id,
+		agent_id,
+		type,
+		width,
+		height,
+		started_at
+	)
+VALUES
+	(@id, @workspace_id, @agent_id, @type, @width, @height, @started_at)
+RETURNING *;
+
+-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	*
+FROM
+	workspace_session_recordings
+WHERE
+	id = @id;
+
+-- name: GetWorkspaceSessionRecordingsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_session_recordings
+WHERE
+	workspace_id = @workspace_id
+ORDER BY
+	started_at DESC;
+
+-- name: UpdateWorkspaceSessionRecording :one
+-- Adds the size of newly recorded output to the recording, and marks it as
+-- ended if an end time is given. Once set, the end time is never changed.
+UPDATE
+	workspace_session_recordings
+SET
+	size_bytes = size_bytes + @added_bytes :: bigint,
+	ended_at = COALESCE(ended_at, sqlc.narg('ended_at') :: timestamptz)
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteOldWorkspaceSessionRecordings :exec
+-- Recorded output is deleted along with the recordings by the foreign key.
+DELETE FROM
+	workspace_session_recordings
+WHERE
+	started_at < @before_time :: timestamptz;
+
+-- name: InsertWorkspaceSessionRecordingChunk :one
+INSERT INTO
+	workspace_session_recording_chunks (
+		recording_id,
+		created_at,
+		data,
+		data_key_id
+	)
+VALUES
+	(@recording_id, @created_at, @data, @data_key_id)
+RETURNING *;
+
+-- name: GetWorkspaceSessionRecordingChunks :many
+SELECT
+	*
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = @recording_id
+ORDER BY
+	id ASC;
+
+-- name: GetEncryptedWorkspaceSessionRecordingChunks :many
+-- Returns the recorded output that is encrypted, for rotating or removing the
+-- encryption keys.
+SELECT
+	*
+FROM
+	workspace_session_recording_chunks
+WHERE
+	data_key_id IS NOT NULL
+ORDER BY
+	id ASC;
+
+-- name: UpdateWorkspaceSessionRecordingChunkData :exec
+UPDATE
+	workspace_session_recording_chunks
+SET
+	data = @data,
+	data_key_id = @data_key_id
+WHERE
+	id = @id;
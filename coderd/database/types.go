@@ -35,6 +35,11 @@ type NotificationsSettings struct {
 	NotifierPaused bool      `db:"notifier_paused" json:"notifier_paused"`
 }
 
+type ReadOnlySettings struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	Enabled bool      `db:"enabled" json:"enabled"`
+}
+
 type Actions []policy.Action
 
 func (a *Actions) Scan(src interface{}) error {
@@ -70,6 +75,26 @@ func (t TemplateACL) Value() (driver.Value, error) {
 	return json.Marshal(t)
 }
 
+// WorkspaceACL is a map of ids to the actions they are granted on a
+// workspace.
+type WorkspaceACL map[string][]policy.Action
+
+func (t *WorkspaceACL) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &t)
+	case []byte, json.RawMessage:
+		//nolint
+		return json.Unmarshal(v.([]byte), &t)
+	}
+
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (t WorkspaceACL) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
 type ExternalAuthProvider struct {
 	ID       string `json:"id"`
 	Optional bool   `json:"optional,omitempty"`
@@ -232,3 +257,91 @@ func (a *UserLinkClaims) Scan(src interface{}) error {
 func (a UserLinkClaims) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
+
+// BuildInitiatorContext carries structured context about what initiated a
+// workspace build. It complements the build reason, which only describes
+// the category of the initiator.
+type BuildInitiatorContext struct {
+	// APIKeyName is the name of the API token the build was requested with.
+	// It is empty for builds requested with a browser session.
+	APIKeyName string `json:"api_key_name,omitempty"`
+	// Automation identifies the automated subsystem that initiated the
+	// build, if any.
+	Automation string `json:"automation,omitempty"`
+	// Schedule is the autostart schedule that triggered the build.
+	Schedule string `json:"schedule,omitempty"`
+}
+
+func (c *BuildInitiatorContext) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &c)
+	case []byte:
+		return json.Unmarshal(v, &c)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (c BuildInitiatorContext) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// WorkspaceBuildWarnings are the warnings recorded on a workspace build when
+// it is created.
+type WorkspaceBuildWarnings []WorkspaceBuildWarning
+
+// WorkspaceBuildWarning suggests updating a workspace, because its build used
+// an outdated template version or a deprecated template.
+type WorkspaceBuildWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// VersionsBehind are the names of the template versions created after the
+	// version of the build, up to and including the active version.
+	VersionsBehind []string `json:"versions_behind,omitempty"`
+}
+
+func (w *WorkspaceBuildWarnings) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &w)
+	case []byte:
+		return json.Unmarshal(v, &w)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (w WorkspaceBuildWarnings) Value() (driver.Value, error) {
+	if w == nil {
+		// The column is a JSON array, so store no warnings as an empty array
+		// rather than null.
+		return []byte("[]"), nil
+	}
+	return json.Marshal(w)
+}
+
+// TemplateResourceCeilings are the maximum resources that the planned
+// resources of a single workspace build of a template may have. Ceilings that
+// are 0 are not enforced.
+type TemplateResourceCeilings struct {
+	// CPU is the number of CPU cores.
+	CPU float64 `json:"cpu,omitempty"`
+	// MemoryGiB and DiskGiB are in gibibytes.
+	MemoryGiB float64 `json:"memory_gib,omitempty"`
+	DiskGiB   float64 `json:"disk_gib,omitempty"`
+	// DailyCost is in quota cost units.
+	DailyCost int32 `json:"daily_cost,omitempty"`
+}
+
+func (c *TemplateResourceCeilings) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &c)
+	case []byte:
+		return json.Unmarshal(v, &c)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (c TemplateResourceCeilings) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
@@ -6,117 +6,167 @@ type UniqueConstraint string
 
 // UniqueConstraint enums.
 const (
-	UniqueAgentStatsPkey                                      UniqueConstraint = "agent_stats_pkey"                                                // ALTER TABLE ONLY workspace_agent_stats ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id);
-	UniqueAPIKeysPkey                                         UniqueConstraint = "api_keys_pkey"                                                   // ALTER TABLE ONLY api_keys ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
-	UniqueAuditLogsPkey                                       UniqueConstraint = "audit_logs_pkey"                                                 // ALTER TABLE ONLY audit_logs ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
-	UniqueCryptoKeysPkey                                      UniqueConstraint = "crypto_keys_pkey"                                                // ALTER TABLE ONLY crypto_keys ADD CONSTRAINT crypto_keys_pkey PRIMARY KEY (feature, sequence);
-	UniqueCustomRolesUniqueKey                                UniqueConstraint = "custom_roles_unique_key"                                         // ALTER TABLE ONLY custom_roles ADD CONSTRAINT custom_roles_unique_key UNIQUE (name, organization_id);
-	UniqueDbcryptKeysActiveKeyDigestKey                       UniqueConstraint = "dbcrypt_keys_active_key_digest_key"                              // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_active_key_digest_key UNIQUE (active_key_digest);
-	UniqueDbcryptKeysPkey                                     UniqueConstraint = "dbcrypt_keys_pkey"                                               // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_pkey PRIMARY KEY (number);
-	UniqueDbcryptKeysRevokedKeyDigestKey                      UniqueConstraint = "dbcrypt_keys_revoked_key_digest_key"                             // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_revoked_key_digest_key UNIQUE (revoked_key_digest);
-	UniqueFilesHashCreatedByKey                               UniqueConstraint = "files_hash_created_by_key"                                       // ALTER TABLE ONLY files ADD CONSTRAINT files_hash_created_by_key UNIQUE (hash, created_by);
-	UniqueFilesPkey                                           UniqueConstraint = "files_pkey"                                                      // ALTER TABLE ONLY files ADD CONSTRAINT files_pkey PRIMARY KEY (id);
-	UniqueGitAuthLinksProviderIDUserIDKey                     UniqueConstraint = "git_auth_links_provider_id_user_id_key"                          // ALTER TABLE ONLY external_auth_links ADD CONSTRAINT git_auth_links_provider_id_user_id_key UNIQUE (provider_id, user_id);
-	UniqueGitSSHKeysPkey                                      UniqueConstraint = "gitsshkeys_pkey"                                                 // ALTER TABLE ONLY gitsshkeys ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
-	UniqueGroupMembersUserIDGroupIDKey                        UniqueConstraint = "group_members_user_id_group_id_key"                              // ALTER TABLE ONLY group_members ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
-	UniqueGroupsNameOrganizationIDKey                         UniqueConstraint = "groups_name_organization_id_key"                                 // ALTER TABLE ONLY groups ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
-	UniqueGroupsPkey                                          UniqueConstraint = "groups_pkey"                                                     // ALTER TABLE ONLY groups ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
-	UniqueInboxNotificationsPkey                              UniqueConstraint = "inbox_notifications_pkey"                                        // ALTER TABLE ONLY inbox_notifications ADD CONSTRAINT inbox_notifications_pkey PRIMARY KEY (id);
-	UniqueJfrogXrayScansPkey                                  UniqueConstraint = "jfrog_xray_scans_pkey"                                           // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_pkey PRIMARY KEY (agent_id, workspace_id);
-	UniqueLicensesJWTKey                                      UniqueConstraint = "licenses_jwt_key"                                                // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
-	UniqueLicensesPkey                                        UniqueConstraint = "licenses_pkey"                                                   // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
-	UniqueNotificationMessagesPkey                            UniqueConstraint = "notification_messages_pkey"                                      // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
-	UniqueNotificationPreferencesPkey                         UniqueConstraint = "notification_preferences_pkey"                                   // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, notification_template_id);
-	UniqueNotificationReportGeneratorLogsPkey                 UniqueConstraint = "notification_report_generator_logs_pkey"                         // ALTER TABLE ONLY notification_report_generator_logs ADD CONSTRAINT notification_report_generator_logs_pkey PRIMARY KEY (notification_template_id);
-	UniqueNotificationTemplatesNameKey                        UniqueConstraint = "notification_templates_name_key"                                 // ALTER TABLE ONLY notification_templates ADD CONSTRAINT notification_templates_name_key UNIQUE (name);
-	UniqueNotificationTemplatesPkey                           UniqueConstraint = "notification_templates_pkey"                                     // ALTER TABLE ONLY notification_templates ADD CONSTRAINT notification_templates_pkey PRIMARY KEY (id);
-	UniqueOauth2ProviderAppCodesPkey                          UniqueConstraint = "oauth2_provider_app_codes_pkey"                                  // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
-	UniqueOauth2ProviderAppCodesSecretPrefixKey               UniqueConstraint = "oauth2_provider_app_codes_secret_prefix_key"                     // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
-	UniqueOauth2ProviderAppSecretsPkey                        UniqueConstraint = "oauth2_provider_app_secrets_pkey"                                // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
-	UniqueOauth2ProviderAppSecretsSecretPrefixKey             UniqueConstraint = "oauth2_provider_app_secrets_secret_prefix_key"                   // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_secret_prefix_key UNIQUE (secret_prefix);
-	UniqueOauth2ProviderAppTokensHashPrefixKey                UniqueConstraint = "oauth2_provider_app_tokens_hash_prefix_key"                      // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_hash_prefix_key UNIQUE (hash_prefix);
-	UniqueOauth2ProviderAppTokensPkey                         UniqueConstraint = "oauth2_provider_app_tokens_pkey"                                 // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
-	UniqueOauth2ProviderAppsNameKey                           UniqueConstraint = "oauth2_provider_apps_name_key"                                   // ALTER TABLE ONLY oauth2_provider_apps ADD CONSTRAINT oauth2_provider_apps_name_key UNIQUE (name);
-	UniqueOauth2ProviderAppsPkey                              UniqueConstraint = "oauth2_provider_apps_pkey"                                       // ALTER TABLE ONLY oauth2_provider_apps ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
-	UniqueOrganizationMembersPkey                             UniqueConstraint = "organization_members_pkey"                                       // ALTER TABLE ONLY organization_members ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
-	UniqueOrganizationsPkey                                   UniqueConstraint = "organizations_pkey"                                              // ALTER TABLE ONLY organizations ADD CONSTRAINT organizations_pkey PRIMARY KEY (id);
-	UniqueParameterSchemasJobIDNameKey                        UniqueConstraint = "parameter_schemas_job_id_name_key"                               // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_job_id_name_key UNIQUE (job_id, name);
-	UniqueParameterSchemasPkey                                UniqueConstraint = "parameter_schemas_pkey"                                          // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_pkey PRIMARY KEY (id);
-	UniqueParameterValuesPkey                                 UniqueConstraint = "parameter_values_pkey"                                           // ALTER TABLE ONLY parameter_values ADD CONSTRAINT parameter_values_pkey PRIMARY KEY (id);
-	UniqueParameterValuesScopeIDNameKey                       UniqueConstraint = "parameter_values_scope_id_name_key"                              // ALTER TABLE ONLY parameter_values ADD CONSTRAINT parameter_values_scope_id_name_key UNIQUE (scope_id, name);
-	UniqueProvisionerDaemonsPkey                              UniqueConstraint = "provisioner_daemons_pkey"                                        // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_pkey PRIMARY KEY (id);
-	UniqueProvisionerJobLogsPkey                              UniqueConstraint = "provisioner_job_logs_pkey"                                       // ALTER TABLE ONLY provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
-	UniqueProvisionerJobsPkey                                 UniqueConstraint = "provisioner_jobs_pkey"                                           // ALTER TABLE ONLY provisioner_jobs ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
-	UniqueProvisionerKeysPkey                                 UniqueConstraint = "provisioner_keys_pkey"                                           // ALTER TABLE ONLY provisioner_keys ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
-	UniqueSiteConfigsKeyKey                                   UniqueConstraint = "site_configs_key_key"                                            // ALTER TABLE ONLY site_configs ADD CONSTRAINT site_configs_key_key UNIQUE (key);
-	UniqueTailnetAgentsPkey                                   UniqueConstraint = "tailnet_agents_pkey"                                             // ALTER TABLE ONLY tailnet_agents ADD CONSTRAINT tailnet_agents_pkey PRIMARY KEY (id, coordinator_id);
-	UniqueTailnetClientSubscriptionsPkey                      UniqueConstraint = "tailnet_client_subscriptions_pkey"                               // ALTER TABLE ONLY tailnet_client_subscriptions ADD CONSTRAINT tailnet_client_subscriptions_pkey PRIMARY KEY (client_id, coordinator_id, agent_id);
-	UniqueTailnetClientsPkey                                  UniqueConstraint = "tailnet_clients_pkey"                                            // ALTER TABLE ONLY tailnet_clients ADD CONSTRAINT tailnet_clients_pkey PRIMARY KEY (id, coordinator_id);
-	UniqueTailnetCoordinatorsPkey                             UniqueConstraint = "tailnet_coordinators_pkey"                                       // ALTER TABLE ONLY tailnet_coordinators ADD CONSTRAINT tailnet_coordinators_pkey PRIMARY KEY (id);
-	UniqueTailnetPeersPkey                                    UniqueConstraint = "tailnet_peers_pkey"                                              // ALTER TABLE ONLY tailnet_peers ADD CONSTRAINT tailnet_peers_pkey PRIMARY KEY (id, coordinator_id);
-	UniqueTailnetTunnelsPkey                                  UniqueConstraint = "tailnet_tunnels_pkey"                                            // ALTER TABLE ONLY tailnet_tunnels ADD CONSTRAINT tailnet_tunnels_pkey PRIMARY KEY (coordinator_id, src_id, dst_id);
-	UniqueTelemetryItemsPkey                                  UniqueConstraint = "telemetry_items_pkey"                                            // ALTER TABLE ONLY telemetry_items ADD CONSTRAINT telemetry_items_pkey PRIMARY KEY (key);
-	UniqueTemplateUsageStatsPkey                              UniqueConstraint = "template_usage_stats_pkey"                                       // ALTER TABLE ONLY template_usage_stats ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
-	UniqueTemplateVersionParametersTemplateVersionIDNameKey   UniqueConstraint = "template_version_parameters_template_version_id_name_key"        // ALTER TABLE ONLY template_version_parameters ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
-	UniqueTemplateVersionPresetParametersPkey                 UniqueConstraint = "template_version_preset_parameters_pkey"                         // ALTER TABLE ONLY template_version_preset_parameters ADD CONSTRAINT template_version_preset_parameters_pkey PRIMARY KEY (id);
-	UniqueTemplateVersionPresetPrebuildSchedulesPkey          UniqueConstraint = "template_version_preset_prebuild_schedules_pkey"                 // ALTER TABLE ONLY template_version_preset_prebuild_schedules ADD CONSTRAINT template_version_preset_prebuild_schedules_pkey PRIMARY KEY (id);
-	UniqueTemplateVersionPresetsPkey                          UniqueConstraint = "template_version_presets_pkey"                                   // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
-	UniqueTemplateVersionTerraformValuesTemplateVersionIDKey  UniqueConstraint = "template_version_terraform_values_template_version_id_key"       // ALTER TABLE ONLY template_version_terraform_values ADD CONSTRAINT template_version_terraform_values_template_version_id_key UNIQUE (template_version_id);
-	UniqueTemplateVersionVariablesTemplateVersionIDNameKey    UniqueConstraint = "template_version_variables_template_version_id_name_key"         // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
-	UniqueTemplateVersionWorkspaceTagsTemplateVersionIDKeyKey UniqueConstraint = "template_version_workspace_tags_template_version_id_key_key"     // ALTER TABLE ONLY template_version_workspace_tags ADD CONSTRAINT template_version_workspace_tags_template_version_id_key_key UNIQUE (template_version_id, key);
-	UniqueTemplateVersionsPkey                                UniqueConstraint = "template_versions_pkey"                                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_pkey PRIMARY KEY (id);
-	UniqueTemplateVersionsTemplateIDNameKey                   UniqueConstraint = "template_versions_template_id_name_key"                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_template_id_name_key UNIQUE (template_id, name);
-	UniqueTemplatesPkey                                       UniqueConstraint = "templates_pkey"                                                  // ALTER TABLE ONLY templates ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
-	UniqueUserConfigsPkey                                     UniqueConstraint = "user_configs_pkey"                                               // ALTER TABLE ONLY user_configs ADD CONSTRAINT user_configs_pkey PRIMARY KEY (user_id, key);
-	UniqueUserDeletedPkey                                     UniqueConstraint = "user_deleted_pkey"                                               // ALTER TABLE ONLY user_deleted ADD CONSTRAINT user_deleted_pkey PRIMARY KEY (id);
-	UniqueUserLinksPkey                                       UniqueConstraint = "user_links_pkey"                                                 // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
-	UniqueUserStatusChangesPkey                               UniqueConstraint = "user_status_changes_pkey"                                        // ALTER TABLE ONLY user_status_changes ADD CONSTRAINT user_status_changes_pkey PRIMARY KEY (id);
-	UniqueUsersPkey                                           UniqueConstraint = "users_pkey"                                                      // ALTER TABLE ONLY users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
-	UniqueWebpushSubscriptionsPkey                            UniqueConstraint = "webpush_subscriptions_pkey"                                      // ALTER TABLE ONLY webpush_subscriptions ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
-	UniqueWorkspaceAgentDevcontainersPkey                     UniqueConstraint = "workspace_agent_devcontainers_pkey"                              // ALTER TABLE ONLY workspace_agent_devcontainers ADD CONSTRAINT workspace_agent_devcontainers_pkey PRIMARY KEY (id);
-	UniqueWorkspaceAgentLogSourcesPkey                        UniqueConstraint = "workspace_agent_log_sources_pkey"                                // ALTER TABLE ONLY workspace_agent_log_sources ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
-	UniqueWorkspaceAgentMemoryResourceMonitorsPkey            UniqueConstraint = "workspace_agent_memory_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_memory_resource_monitors ADD CONSTRAINT workspace_agent_memory_resource_monitors_pkey PRIMARY KEY (agent_id);
-	UniqueWorkspaceAgentMetadataPkey                          UniqueConstraint = "workspace_agent_metadata_pkey"                                   // ALTER TABLE ONLY workspace_agent_metadata ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
-	UniqueWorkspaceAgentPortSharePkey                         UniqueConstraint = "workspace_agent_port_share_pkey"                                 // ALTER TABLE ONLY workspace_agent_port_share ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
-	UniqueWorkspaceAgentScriptTimingsScriptIDStartedAtKey     UniqueConstraint = "workspace_agent_script_timings_script_id_started_at_key"         // ALTER TABLE ONLY workspace_agent_script_timings ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
-	UniqueWorkspaceAgentScriptsIDKey                          UniqueConstraint = "workspace_agent_scripts_id_key"                                  // ALTER TABLE ONLY workspace_agent_scripts ADD CONSTRAINT workspace_agent_scripts_id_key UNIQUE (id);
-	UniqueWorkspaceAgentStartupLogsPkey                       UniqueConstraint = "workspace_agent_startup_logs_pkey"                               // ALTER TABLE ONLY workspace_agent_logs ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
-	UniqueWorkspaceAgentVolumeResourceMonitorsPkey            UniqueConstraint = "workspace_agent_volume_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_volume_resource_monitors ADD CONSTRAINT workspace_agent_volume_resource_monitors_pkey PRIMARY KEY (agent_id, path);
-	UniqueWorkspaceAgentsPkey                                 UniqueConstraint = "workspace_agents_pkey"                                           // ALTER TABLE ONLY workspace_agents ADD CONSTRAINT workspace_agents_pkey PRIMARY KEY (id);
-	UniqueWorkspaceAppAuditSessionsAgentIDAppIDUserIDIpUseKey UniqueConstraint = "workspace_app_audit_sessions_agent_id_app_id_user_id_ip_use_key" // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_agent_id_app_id_user_id_ip_use_key UNIQUE (agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code);
-	UniqueWorkspaceAppAuditSessionsPkey                       UniqueConstraint = "workspace_app_audit_sessions_pkey"                               // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_pkey PRIMARY KEY (id);
-	UniqueWorkspaceAppStatsPkey                               UniqueConstraint = "workspace_app_stats_pkey"                                        // ALTER TABLE ONLY workspace_app_stats ADD CONSTRAINT workspace_app_stats_pkey PRIMARY KEY (id);
-	UniqueWorkspaceAppStatsUserIDAgentIDSessionIDKey          UniqueConstraint = "workspace_app_stats_user_id_agent_id_session_id_key"             // ALTER TABLE ONLY workspace_app_stats ADD CONSTRAINT workspace_app_stats_user_id_agent_id_session_id_key UNIQUE (user_id, agent_id, session_id);
-	UniqueWorkspaceAppStatusesPkey                            UniqueConstraint = "workspace_app_statuses_pkey"                                     // ALTER TABLE ONLY workspace_app_statuses ADD CONSTRAINT workspace_app_statuses_pkey PRIMARY KEY (id);
-	UniqueWorkspaceAppsAgentIDSlugIndex                       UniqueConstraint = "workspace_apps_agent_id_slug_idx"                                // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_agent_id_slug_idx UNIQUE (agent_id, slug);
-	UniqueWorkspaceAppsPkey                                   UniqueConstraint = "workspace_apps_pkey"                                             // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_pkey PRIMARY KEY (id);
-	UniqueWorkspaceBuildParametersWorkspaceBuildIDNameKey     UniqueConstraint = "workspace_build_parameters_workspace_build_id_name_key"          // ALTER TABLE ONLY workspace_build_parameters ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
-	UniqueWorkspaceBuildsJobIDKey                             UniqueConstraint = "workspace_builds_job_id_key"                                     // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
-	UniqueWorkspaceBuildsPkey                                 UniqueConstraint = "workspace_builds_pkey"                                           // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_pkey PRIMARY KEY (id);
-	UniqueWorkspaceBuildsWorkspaceIDBuildNumberKey            UniqueConstraint = "workspace_builds_workspace_id_build_number_key"                  // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
-	UniqueWorkspaceProxiesPkey                                UniqueConstraint = "workspace_proxies_pkey"                                          // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
-	UniqueWorkspaceProxiesRegionIDUnique                      UniqueConstraint = "workspace_proxies_region_id_unique"                              // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
-	UniqueWorkspaceResourceMetadataName                       UniqueConstraint = "workspace_resource_metadata_name"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
-	UniqueWorkspaceResourceMetadataPkey                       UniqueConstraint = "workspace_resource_metadata_pkey"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_pkey PRIMARY KEY (id);
-	UniqueWorkspaceResourcesPkey                              UniqueConstraint = "workspace_resources_pkey"                                        // ALTER TABLE ONLY workspace_resources ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
-	UniqueWorkspacesPkey                                      UniqueConstraint = "workspaces_pkey"                                                 // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
-	UniqueIndexAPIKeyName                                     UniqueConstraint = "idx_api_key_name"                                                // CREATE UNIQUE INDEX idx_api_key_name ON api_keys USING btree (user_id, token_name) WHERE (login_type = 'token'::login_type);
-	UniqueIndexCustomRolesNameLower                           UniqueConstraint = "idx_custom_roles_name_lower"                                     // CREATE UNIQUE INDEX idx_custom_roles_name_lower ON custom_roles USING btree (lower(name));
-	UniqueIndexOrganizationNameLower                          UniqueConstraint = "idx_organization_name_lower"                                     // CREATE UNIQUE INDEX idx_organization_name_lower ON organizations USING btree (lower(name)) WHERE (deleted = false);
-	UniqueIndexProvisionerDaemonsOrgNameOwnerKey              UniqueConstraint = "idx_provisioner_daemons_org_name_owner_key"                      // CREATE UNIQUE INDEX idx_provisioner_daemons_org_name_owner_key ON provisioner_daemons USING btree (organization_id, name, lower(COALESCE((tags ->> 'owner'::text), ''::text)));
-	UniqueIndexTemplateVersionPresetsDefault                  UniqueConstraint = "idx_template_version_presets_default"                            // CREATE UNIQUE INDEX idx_template_version_presets_default ON template_version_presets USING btree (template_version_id) WHERE (is_default = true);
-	UniqueIndexUniquePresetName                               UniqueConstraint = "idx_unique_preset_name"                                          // CREATE UNIQUE INDEX idx_unique_preset_name ON template_version_presets USING btree (name, template_version_id);
-	UniqueIndexUsersEmail                                     UniqueConstraint = "idx_users_email"                                                 // CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted = false);
-	UniqueIndexUsersUsername                                  UniqueConstraint = "idx_users_username"                                              // CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
-	UniqueNotificationMessagesDedupeHashIndex                 UniqueConstraint = "notification_messages_dedupe_hash_idx"                           // CREATE UNIQUE INDEX notification_messages_dedupe_hash_idx ON notification_messages USING btree (dedupe_hash);
-	UniqueOrganizationsSingleDefaultOrg                       UniqueConstraint = "organizations_single_default_org"                                // CREATE UNIQUE INDEX organizations_single_default_org ON organizations USING btree (is_default) WHERE (is_default = true);
-	UniqueProvisionerKeysOrganizationIDNameIndex              UniqueConstraint = "provisioner_keys_organization_id_name_idx"                       // CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
-	UniqueTemplateUsageStatsStartTimeTemplateIDUserIDIndex    UniqueConstraint = "template_usage_stats_start_time_template_id_user_id_idx"         // CREATE UNIQUE INDEX template_usage_stats_start_time_template_id_user_id_idx ON template_usage_stats USING btree (start_time, template_id, user_id);
-	UniqueTemplatesOrganizationIDNameIndex                    UniqueConstraint = "templates_organization_id_name_idx"                              // CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
-	UniqueUserLinksLinkedIDLoginTypeIndex                     UniqueConstraint = "user_links_linked_id_login_type_idx"                             // CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
-	UniqueUsersEmailLowerIndex                                UniqueConstraint = "users_email_lower_idx"                                           // CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
-	UniqueUsersUsernameLowerIndex                             UniqueConstraint = "users_username_lower_idx"                                        // CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
-	UniqueWorkspaceAppAuditSessionsUniqueIndex                UniqueConstraint = "workspace_app_audit_sessions_unique_index"                       // CREATE UNIQUE INDEX workspace_app_audit_sessions_unique_index ON workspace_app_audit_sessions USING btree (agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code);
-	UniqueWorkspaceProxiesLowerNameIndex                      UniqueConstraint = "workspace_proxies_lower_name_idx"                                // CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
-	UniqueWorkspacesOwnerIDLowerIndex                         UniqueConstraint = "workspaces_owner_id_lower_idx"                                   // CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
+	UniqueAccessRequestsPkey                                   UniqueConstraint = "access_requests_pkey"                                            // ALTER TABLE ONLY access_requests ADD CONSTRAINT access_requests_pkey PRIMARY KEY (id);
+	UniqueAgentStatsPkey                                       UniqueConstraint = "agent_stats_pkey"                                                // ALTER TABLE ONLY workspace_agent_stats ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id, created_at);
+	UniqueAPIKeysPkey                                          UniqueConstraint = "api_keys_pkey"                                                   // ALTER TABLE ONLY api_keys ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
+	UniqueAuditLogsPkey                                        UniqueConstraint = "audit_logs_pkey"                                                 // ALTER TABLE ONLY audit_logs ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id, "time");
+	UniqueAuditLogsDefaultPkey                                 UniqueConstraint = "audit_logs_default_pkey"                                         // ALTER TABLE ONLY audit_logs_default ADD CONSTRAINT audit_logs_default_pkey PRIMARY KEY (id, "time");
+	UniqueBuildAlertRulesPkey                                  UniqueConstraint = "build_alert_rules_pkey"                                          // ALTER TABLE ONLY build_alert_rules ADD CONSTRAINT build_alert_rules_pkey PRIMARY KEY (id);
+	UniqueCryptoKeysPkey                                       UniqueConstraint = "crypto_keys_pkey"                                                // ALTER TABLE ONLY crypto_keys ADD CONSTRAINT crypto_keys_pkey PRIMARY KEY (feature, sequence);
+	UniqueCustomRolesUniqueKey                                 UniqueConstraint = "custom_roles_unique_key"                                         // ALTER TABLE ONLY custom_roles ADD CONSTRAINT custom_roles_unique_key UNIQUE (name, organization_id);
+	UniqueDbcryptKeysActiveKeyDigestKey                        UniqueConstraint = "dbcrypt_keys_active_key_digest_key"                              // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_active_key_digest_key UNIQUE (active_key_digest);
+	UniqueDbcryptKeysPkey                                      UniqueConstraint = "dbcrypt_keys_pkey"                                               // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_pkey PRIMARY KEY (number);
+	UniqueDbcryptKeysRevokedKeyDigestKey                       UniqueConstraint = "dbcrypt_keys_revoked_key_digest_key"                             // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_revoked_key_digest_key UNIQUE (revoked_key_digest);
+	UniqueFilesHashCreatedByKey                                UniqueConstraint = "files_hash_created_by_key"                                       // ALTER TABLE ONLY files ADD CONSTRAINT files_hash_created_by_key UNIQUE (hash, created_by);
+	UniqueFilesPkey                                            UniqueConstraint = "files_pkey"                                                      // ALTER TABLE ONLY files ADD CONSTRAINT files_pkey PRIMARY KEY (id);
+	UniqueGitAuthLinksProviderIDUserIDKey                      UniqueConstraint = "git_auth_links_provider_id_user_id_key"                          // ALTER TABLE ONLY external_auth_links ADD CONSTRAINT git_auth_links_provider_id_user_id_key UNIQUE (provider_id, user_id);
+	UniqueGitSSHKeysPkey                                       UniqueConstraint = "gitsshkeys_pkey"                                                 // ALTER TABLE ONLY gitsshkeys ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
+	UniqueGroupMembersUserIDGroupIDKey                         UniqueConstraint = "group_members_user_id_group_id_key"                              // ALTER TABLE ONLY group_members ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
+	UniqueGroupsNameOrganizationIDKey                          UniqueConstraint = "groups_name_organization_id_key"                                 // ALTER TABLE ONLY groups ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
+	UniqueGroupsPkey                                           UniqueConstraint = "groups_pkey"                                                     // ALTER TABLE ONLY groups ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
+	UniqueInboxNotificationsPkey                               UniqueConstraint = "inbox_notifications_pkey"                                        // ALTER TABLE ONLY inbox_notifications ADD CONSTRAINT inbox_notifications_pkey PRIMARY KEY (id);
+	UniqueJfrogXrayScansPkey                                   UniqueConstraint = "jfrog_xray_scans_pkey"                                           // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_pkey PRIMARY KEY (agent_id, workspace_id);
+	UniqueLicensesJWTKey                                       UniqueConstraint = "licenses_jwt_key"                                                // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
+	UniqueLicensesPkey                                         UniqueConstraint = "licenses_pkey"                                                   // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
+	UniqueLoginDeviceCodesDeviceCodeHashKey                    UniqueConstraint = "login_device_codes_device_code_hash_key"                         // ALTER TABLE ONLY login_device_codes ADD CONSTRAINT login_device_codes_device_code_hash_key UNIQUE (device_code_hash);
+	UniqueLoginDeviceCodesPkey                                 UniqueConstraint = "login_device_codes_pkey"                                         // ALTER TABLE ONLY login_device_codes ADD CONSTRAINT login_device_codes_pkey PRIMARY KEY (id);
+	UniqueLoginDeviceCodesUserCodeKey                          UniqueConstraint = "login_device_codes_user_code_key"                                // ALTER TABLE ONLY login_device_codes ADD CONSTRAINT login_device_codes_user_code_key UNIQUE (user_code);
+	UniqueNotificationMessagesPkey                             UniqueConstraint = "notification_messages_pkey"                                      // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
+	UniqueNotificationPreferencesPkey                          UniqueConstraint = "notification_preferences_pkey"                                   // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, notification_template_id);
+	UniqueNotificationReportGeneratorLogsPkey                  UniqueConstraint = "notification_report_generator_logs_pkey"                         // ALTER TABLE ONLY notification_report_generator_logs ADD CONSTRAINT notification_report_generator_logs_pkey PRIMARY KEY (notification_template_id);
+	UniqueNotificationTemplatesNameKey                         UniqueConstraint = "notification_templates_name_key"                                 // ALTER TABLE ONLY notification_templates ADD CONSTRAINT notification_templates_name_key UNIQUE (name);
+	UniqueNotificationTemplatesPkey                            UniqueConstraint = "notification_templates_pkey"                                     // ALTER TABLE ONLY notification_templates ADD CONSTRAINT notification_templates_pkey PRIMARY KEY (id);
+	UniqueOauth2ProviderAppCodesPkey                           UniqueConstraint = "oauth2_provider_app_codes_pkey"                                  // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
+	UniqueOauth2ProviderAppCodesSecretPrefixKey                UniqueConstraint = "oauth2_provider_app_codes_secret_prefix_key"                     // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
+	UniqueOauth2ProviderAppSecretsPkey                         UniqueConstraint = "oauth2_provider_app_secrets_pkey"                                // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
+	UniqueOauth2ProviderAppSecretsSecretPrefixKey              UniqueConstraint = "oauth2_provider_app_secrets_secret_prefix_key"                   // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_secret_prefix_key UNIQUE (secret_prefix);
+	UniqueOauth2ProviderAppTokensHashPrefixKey                 UniqueConstraint = "oauth2_provider_app_tokens_hash_prefix_key"                      // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_hash_prefix_key UNIQUE (hash_prefix);
+	UniqueOauth2ProviderAppTokensPkey                          UniqueConstraint = "oauth2_provider_app_tokens_pkey"                                 // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
+	UniqueOauth2ProviderAppsNameKey                            UniqueConstraint = "oauth2_provider_apps_name_key"                                   // ALTER TABLE ONLY oauth2_provider_apps ADD CONSTRAINT oauth2_provider_apps_name_key UNIQUE (name);
+	UniqueOauth2ProviderAppsPkey                               UniqueConstraint = "oauth2_provider_apps_pkey"                                       // ALTER TABLE ONLY oauth2_provider_apps ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
+	UniqueOrganizationMembersPkey                              UniqueConstraint = "organization_members_pkey"                                       // ALTER TABLE ONLY organization_members ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
+	UniqueOrganizationsPkey                                    UniqueConstraint = "organizations_pkey"                                              // ALTER TABLE ONLY organizations ADD CONSTRAINT organizations_pkey PRIMARY KEY (id);
+	UniqueParameterSchemasJobIDNameKey                         UniqueConstraint = "parameter_schemas_job_id_name_key"                               // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_job_id_name_key UNIQUE (job_id, name);
+	UniqueParameterSchemasPkey                                 UniqueConstraint = "parameter_schemas_pkey"                                          // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_pkey PRIMARY KEY (id);
+	UniqueParameterValuesPkey                                  UniqueConstraint = "parameter_values_pkey"                                           // ALTER TABLE ONLY parameter_values ADD CONSTRAINT parameter_values_pkey PRIMARY KEY (id);
+	UniqueParameterValuesScopeIDNameKey                        UniqueConstraint = "parameter_values_scope_id_name_key"                              // ALTER TABLE ONLY parameter_values ADD CONSTRAINT parameter_values_scope_id_name_key UNIQUE (scope_id, name);
+	UniqueProvisionerBuildPausesPkey                           UniqueConstraint = "provisioner_build_pauses_pkey"                                   // ALTER TABLE ONLY provisioner_build_pauses ADD CONSTRAINT provisioner_build_pauses_pkey PRIMARY KEY (id);
+	UniqueProvisionerDaemonsPkey                               UniqueConstraint = "provisioner_daemons_pkey"                                        // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_pkey PRIMARY KEY (id);
+	UniqueProvisionerJobLogArchivesPkey                        UniqueConstraint = "provisioner_job_log_archives_pkey"                               // ALTER TABLE ONLY provisioner_job_log_archives ADD CONSTRAINT provisioner_job_log_archives_pkey PRIMARY KEY (job_id);
+	UniqueProvisionerJobLogsDefaultPkey                        UniqueConstraint = "provisioner_job_logs_default_pkey"                               // ALTER TABLE ONLY provisioner_job_logs_default ADD CONSTRAINT provisioner_job_logs_default_pkey PRIMARY KEY (id, created_at);
+	UniqueProvisionerJobLogsPkey                               UniqueConstraint = "provisioner_job_logs_pkey"                                       // ALTER TABLE ONLY provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id, created_at);
+	UniqueProvisionerJobsPkey                                  UniqueConstraint = "provisioner_jobs_pkey"                                           // ALTER TABLE ONLY provisioner_jobs ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
+	UniqueProvisionerKeysPkey                                  UniqueConstraint = "provisioner_keys_pkey"                                           // ALTER TABLE ONLY provisioner_keys ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
+	UniqueProvisionerReservationsPkey                          UniqueConstraint = "provisioner_reservations_pkey"                                   // ALTER TABLE ONLY provisioner_reservations ADD CONSTRAINT provisioner_reservations_pkey PRIMARY KEY (id);
+	UniqueServiceAccountsPkey                                  UniqueConstraint = "service_accounts_pkey"                                           // ALTER TABLE ONLY service_accounts ADD CONSTRAINT service_accounts_pkey PRIMARY KEY (user_id);
+	UniqueSiteConfigsKeyKey                                    UniqueConstraint = "site_configs_key_key"                                            // ALTER TABLE ONLY site_configs ADD CONSTRAINT site_configs_key_key UNIQUE (key);
+	UniqueTailnetAgentsPkey                                    UniqueConstraint = "tailnet_agents_pkey"                                             // ALTER TABLE ONLY tailnet_agents ADD CONSTRAINT tailnet_agents_pkey PRIMARY KEY (id, coordinator_id);
+	UniqueTailnetClientSubscriptionsPkey                       UniqueConstraint = "tailnet_client_subscriptions_pkey"                               // ALTER TABLE ONLY tailnet_client_subscriptions ADD CONSTRAINT tailnet_client_subscriptions_pkey PRIMARY KEY (client_id, coordinator_id, agent_id);
+	UniqueTailnetClientsPkey                                   UniqueConstraint = "tailnet_clients_pkey"                                            // ALTER TABLE ONLY tailnet_clients ADD CONSTRAINT tailnet_clients_pkey PRIMARY KEY (id, coordinator_id);
+	UniqueTailnetCoordinatorsPkey                              UniqueConstraint = "tailnet_coordinators_pkey"                                       // ALTER TABLE ONLY tailnet_coordinators ADD CONSTRAINT tailnet_coordinators_pkey PRIMARY KEY (id);
+	UniqueTailnetPeersPkey                                     UniqueConstraint = "tailnet_peers_pkey"                                              // ALTER TABLE ONLY tailnet_peers ADD CONSTRAINT tailnet_peers_pkey PRIMARY KEY (id, coordinator_id);
+	UniqueTailnetTunnelsPkey                                   UniqueConstraint = "tailnet_tunnels_pkey"                                            // ALTER TABLE ONLY tailnet_tunnels ADD CONSTRAINT tailnet_tunnels_pkey PRIMARY KEY (coordinator_id, src_id, dst_id);
+	UniqueTelemetryItemsPkey                                   UniqueConstraint = "telemetry_items_pkey"                                            // ALTER TABLE ONLY telemetry_items ADD CONSTRAINT telemetry_items_pkey PRIMARY KEY (key);
+	UniqueTemplateBuildDurationStatsPkey                       UniqueConstraint = "template_build_duration_stats_pkey"                              // ALTER TABLE ONLY template_build_duration_stats ADD CONSTRAINT template_build_duration_stats_pkey PRIMARY KEY (start_time, template_id, transition);
+	UniqueTemplatePresetGroupDefaultsPkey                      UniqueConstraint = "template_preset_group_defaults_pkey"                             // ALTER TABLE ONLY template_preset_group_defaults ADD CONSTRAINT template_preset_group_defaults_pkey PRIMARY KEY (template_id, group_id);
+	UniqueTemplatePresetsPkey                                  UniqueConstraint = "template_presets_pkey"                                           // ALTER TABLE ONLY template_presets ADD CONSTRAINT template_presets_pkey PRIMARY KEY (id);
+	UniqueTemplatePresetsTemplateIDNameKey                     UniqueConstraint = "template_presets_template_id_name_key"                           // ALTER TABLE ONLY template_presets ADD CONSTRAINT template_presets_template_id_name_key UNIQUE (template_id, name);
+	UniqueTemplateReleaseChannelsPkey                          UniqueConstraint = "template_release_channels_pkey"                                  // ALTER TABLE ONLY template_release_channels ADD CONSTRAINT template_release_channels_pkey PRIMARY KEY (template_id, channel);
+	UniqueTemplateSharesPkey                                   UniqueConstraint = "template_shares_pkey"                                            // ALTER TABLE ONLY template_shares ADD CONSTRAINT template_shares_pkey PRIMARY KEY (template_id, organization_id);
+	UniqueTemplateUsageStatsPkey                               UniqueConstraint = "template_usage_stats_pkey"                                       // ALTER TABLE ONLY template_usage_stats ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
+	UniqueTemplateVersionParametersTemplateVersionIDNameKey    UniqueConstraint = "template_version_parameters_template_version_id_name_key"        // ALTER TABLE ONLY template_version_parameters ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
+	UniqueTemplateVersionPresetParametersPkey                  UniqueConstraint = "template_version_preset_parameters_pkey"                         // ALTER TABLE ONLY template_version_preset_parameters ADD CONSTRAINT template_version_preset_parameters_pkey PRIMARY KEY (id);
+	UniqueTemplateVersionPresetPrebuildSchedulesPkey           UniqueConstraint = "template_version_preset_prebuild_schedules_pkey"                 // ALTER TABLE ONLY template_version_preset_prebuild_schedules ADD CONSTRAINT template_version_preset_prebuild_schedules_pkey PRIMARY KEY (id);
+	UniqueTemplateVersionPresetsPkey                           UniqueConstraint = "template_version_presets_pkey"                                   // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
+	UniqueTemplateVersionRolloutsPkey                          UniqueConstraint = "template_version_rollouts_pkey"                                  // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_pkey PRIMARY KEY (template_id);
+	UniqueTemplateVersionTerraformValuesTemplateVersionIDKey   UniqueConstraint = "template_version_terraform_values_template_version_id_key"       // ALTER TABLE ONLY template_version_terraform_values ADD CONSTRAINT template_version_terraform_values_template_version_id_key UNIQUE (template_version_id);
+	UniqueTemplateVersionVariablesTemplateVersionIDNameKey     UniqueConstraint = "template_version_variables_template_version_id_name_key"         // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
+	UniqueTemplateVersionWorkspaceTagsTemplateVersionIDKeyKey  UniqueConstraint = "template_version_workspace_tags_template_version_id_key_key"     // ALTER TABLE ONLY template_version_workspace_tags ADD CONSTRAINT template_version_workspace_tags_template_version_id_key_key UNIQUE (template_version_id, key);
+	UniqueTemplateVersionsPkey                                 UniqueConstraint = "template_versions_pkey"                                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_pkey PRIMARY KEY (id);
+	UniqueTemplateVersionsTemplateIDNameKey                    UniqueConstraint = "template_versions_template_id_name_key"                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_template_id_name_key UNIQUE (template_id, name);
+	UniqueTemplatesPkey                                        UniqueConstraint = "templates_pkey"                                                  // ALTER TABLE ONLY templates ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
+	UniqueUserActivityExportsPkey                              UniqueConstraint = "user_activity_exports_pkey"                                      // ALTER TABLE ONLY user_activity_exports ADD CONSTRAINT user_activity_exports_pkey PRIMARY KEY (id);
+	UniqueUserConfigsPkey                                      UniqueConstraint = "user_configs_pkey"                                               // ALTER TABLE ONLY user_configs ADD CONSTRAINT user_configs_pkey PRIMARY KEY (user_id, key);
+	UniqueUserDeletedPkey                                      UniqueConstraint = "user_deleted_pkey"                                               // ALTER TABLE ONLY user_deleted ADD CONSTRAINT user_deleted_pkey PRIMARY KEY (id);
+	UniqueUserLinksPkey                                        UniqueConstraint = "user_links_pkey"                                                 // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
+	UniqueUserStatusChangesPkey                                UniqueConstraint = "user_status_changes_pkey"                                        // ALTER TABLE ONLY user_status_changes ADD CONSTRAINT user_status_changes_pkey PRIMARY KEY (id);
+	UniqueUsersPkey                                            UniqueConstraint = "users_pkey"                                                      // ALTER TABLE ONLY users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+	UniqueWebpushSubscriptionsPkey                             UniqueConstraint = "webpush_subscriptions_pkey"                                      // ALTER TABLE ONLY webpush_subscriptions ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAgentConnectionQualityPkey                  UniqueConstraint = "workspace_agent_connection_quality_pkey"                         // ALTER TABLE ONLY workspace_agent_connection_quality ADD CONSTRAINT workspace_agent_connection_quality_pkey PRIMARY KEY (agent_id, ip);
+	UniqueWorkspaceAgentDevcontainersPkey                      UniqueConstraint = "workspace_agent_devcontainers_pkey"                              // ALTER TABLE ONLY workspace_agent_devcontainers ADD CONSTRAINT workspace_agent_devcontainers_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAgentHealthEventsPkey                       UniqueConstraint = "workspace_agent_health_events_pkey"                              // ALTER TABLE ONLY workspace_agent_health_events ADD CONSTRAINT workspace_agent_health_events_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAgentLogSourcesPkey                         UniqueConstraint = "workspace_agent_log_sources_pkey"                                // ALTER TABLE ONLY workspace_agent_log_sources ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
+	UniqueWorkspaceAgentMemoryResourceMonitorsPkey             UniqueConstraint = "workspace_agent_memory_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_memory_resource_monitors ADD CONSTRAINT workspace_agent_memory_resource_monitors_pkey PRIMARY KEY (agent_id);
+	UniqueWorkspaceAgentMetadataPkey                           UniqueConstraint = "workspace_agent_metadata_pkey"                                   // ALTER TABLE ONLY workspace_agent_metadata ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
+	UniqueWorkspaceAgentPortSharePkey                          UniqueConstraint = "workspace_agent_port_share_pkey"                                 // ALTER TABLE ONLY workspace_agent_port_share ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
+	UniqueWorkspaceAgentResourceUsagePkey                      UniqueConstraint = "workspace_agent_resource_usage_pkey"                             // ALTER TABLE ONLY workspace_agent_resource_usage ADD CONSTRAINT workspace_agent_resource_usage_pkey PRIMARY KEY (agent_id, bucket_start);
+	UniqueWorkspaceAgentScriptTimingsScriptIDStartedAtKey      UniqueConstraint = "workspace_agent_script_timings_script_id_started_at_key"         // ALTER TABLE ONLY workspace_agent_script_timings ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
+	UniqueWorkspaceAgentScriptsIDKey                           UniqueConstraint = "workspace_agent_scripts_id_key"                                  // ALTER TABLE ONLY workspace_agent_scripts ADD CONSTRAINT workspace_agent_scripts_id_key UNIQUE (id);
+	UniqueWorkspaceAgentStartupLogsPkey                        UniqueConstraint = "workspace_agent_startup_logs_pkey"                               // ALTER TABLE ONLY workspace_agent_logs ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAgentStatsDefaultPkey                       UniqueConstraint = "workspace_agent_stats_default_pkey"                              // ALTER TABLE ONLY workspace_agent_stats_default ADD CONSTRAINT workspace_agent_stats_default_pkey PRIMARY KEY (id, created_at);
+	UniqueWorkspaceAgentVolumeResourceMonitorsPkey             UniqueConstraint = "workspace_agent_volume_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_volume_resource_monitors ADD CONSTRAINT workspace_agent_volume_resource_monitors_pkey PRIMARY KEY (agent_id, path);
+	UniqueWorkspaceAgentsPkey                                  UniqueConstraint = "workspace_agents_pkey"                                           // ALTER TABLE ONLY workspace_agents ADD CONSTRAINT workspace_agents_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAppAuditSessionsAgentIDAppIDUserIDIpUseKey  UniqueConstraint = "workspace_app_audit_sessions_agent_id_app_id_user_id_ip_use_key" // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_agent_id_app_id_user_id_ip_use_key UNIQUE (agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code);
+	UniqueWorkspaceAppAuditSessionsPkey                        UniqueConstraint = "workspace_app_audit_sessions_pkey"                               // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAppStatsPkey                                UniqueConstraint = "workspace_app_stats_pkey"                                        // ALTER TABLE ONLY workspace_app_stats ADD CONSTRAINT workspace_app_stats_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAppStatsUserIDAgentIDSessionIDKey           UniqueConstraint = "workspace_app_stats_user_id_agent_id_session_id_key"             // ALTER TABLE ONLY workspace_app_stats ADD CONSTRAINT workspace_app_stats_user_id_agent_id_session_id_key UNIQUE (user_id, agent_id, session_id);
+	UniqueWorkspaceAppStatusesPkey                             UniqueConstraint = "workspace_app_statuses_pkey"                                     // ALTER TABLE ONLY workspace_app_statuses ADD CONSTRAINT workspace_app_statuses_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAppsAgentIDSlugIndex                        UniqueConstraint = "workspace_apps_agent_id_slug_idx"                                // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_agent_id_slug_idx UNIQUE (agent_id, slug);
+	UniqueWorkspaceAppsPkey                                    UniqueConstraint = "workspace_apps_pkey"                                             // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_pkey PRIMARY KEY (id);
+	UniqueWorkspaceBuildArchivesPkey                           UniqueConstraint = "workspace_build_archives_pkey"                                   // ALTER TABLE ONLY workspace_build_archives ADD CONSTRAINT workspace_build_archives_pkey PRIMARY KEY (workspace_build_id);
+	UniqueWorkspaceBuildDurationNotificationsPkey              UniqueConstraint = "workspace_build_duration_notifications_pkey"                     // ALTER TABLE ONLY workspace_build_duration_notifications ADD CONSTRAINT workspace_build_duration_notifications_pkey PRIMARY KEY (workspace_build_id);
+	UniqueWorkspaceBuildIdempotencyKeysUserIDIdempotencyKeyKey UniqueConstraint = "workspace_build_idempotency_keys_user_id_idempotency_key_key"    // ALTER TABLE ONLY workspace_build_idempotency_keys ADD CONSTRAINT workspace_build_idempotency_keys_user_id_idempotency_key_key UNIQUE (user_id, idempotency_key);
+	UniqueWorkspaceBuildIdempotencyKeysWorkspaceBuildIDKey     UniqueConstraint = "workspace_build_idempotency_keys_workspace_build_id_key"         // ALTER TABLE ONLY workspace_build_idempotency_keys ADD CONSTRAINT workspace_build_idempotency_keys_workspace_build_id_key UNIQUE (workspace_build_id);
+	UniqueWorkspaceBuildInterimStatesPkey                      UniqueConstraint = "workspace_build_interim_states_pkey"                             // ALTER TABLE ONLY workspace_build_interim_states ADD CONSTRAINT workspace_build_interim_states_pkey PRIMARY KEY (workspace_build_id);
+	UniqueWorkspaceBuildParametersWorkspaceBuildIDNameKey      UniqueConstraint = "workspace_build_parameters_workspace_build_id_name_key"          // ALTER TABLE ONLY workspace_build_parameters ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
+	UniqueWorkspaceBuildQueuePkey                              UniqueConstraint = "workspace_build_queue_pkey"                                      // ALTER TABLE ONLY workspace_build_queue ADD CONSTRAINT workspace_build_queue_pkey PRIMARY KEY (id);
+	UniqueWorkspaceBuildsJobIDKey                              UniqueConstraint = "workspace_builds_job_id_key"                                     // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
+	UniqueWorkspaceBuildsPkey                                  UniqueConstraint = "workspace_builds_pkey"                                           // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_pkey PRIMARY KEY (id);
+	UniqueWorkspaceBuildsWorkspaceIDBuildNumberKey             UniqueConstraint = "workspace_builds_workspace_id_build_number_key"                  // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
+	UniqueWorkspaceDailyCostsPkey                              UniqueConstraint = "workspace_daily_costs_pkey"                                      // ALTER TABLE ONLY workspace_daily_costs ADD CONSTRAINT workspace_daily_costs_pkey PRIMARY KEY (start_time, workspace_id);
+	UniqueWorkspaceDriftChecksPkey                             UniqueConstraint = "workspace_drift_checks_pkey"                                     // ALTER TABLE ONLY workspace_drift_checks ADD CONSTRAINT workspace_drift_checks_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceLabelsPkey                                  UniqueConstraint = "workspace_labels_pkey"                                           // ALTER TABLE ONLY workspace_labels ADD CONSTRAINT workspace_labels_pkey PRIMARY KEY (workspace_id, key);
+	UniqueWorkspaceLocksPkey                                   UniqueConstraint = "workspace_locks_pkey"                                            // ALTER TABLE ONLY workspace_locks ADD CONSTRAINT workspace_locks_pkey PRIMARY KEY (id);
+	UniqueWorkspaceMigrationsPkey                              UniqueConstraint = "workspace_migrations_pkey"                                       // ALTER TABLE ONLY workspace_migrations ADD CONSTRAINT workspace_migrations_pkey PRIMARY KEY (id);
+	UniqueWorkspaceNamingPoliciesPkey                          UniqueConstraint = "workspace_naming_policies_pkey"                                  // ALTER TABLE ONLY workspace_naming_policies ADD CONSTRAINT workspace_naming_policies_pkey PRIMARY KEY (id);
+	UniqueWorkspacePrebuildReservationsPkey                    UniqueConstraint = "workspace_prebuild_reservations_pkey"                            // ALTER TABLE ONLY workspace_prebuild_reservations ADD CONSTRAINT workspace_prebuild_reservations_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceProvisionerAffinitiesPkey                   UniqueConstraint = "workspace_provisioner_affinities_pkey"                           // ALTER TABLE ONLY workspace_provisioner_affinities ADD CONSTRAINT workspace_provisioner_affinities_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceProxiesPkey                                 UniqueConstraint = "workspace_proxies_pkey"                                          // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
+	UniqueWorkspaceProxiesRegionIDUnique                       UniqueConstraint = "workspace_proxies_region_id_unique"                              // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
+	UniqueWorkspaceReleaseChannelsPkey                         UniqueConstraint = "workspace_release_channels_pkey"                                 // ALTER TABLE ONLY workspace_release_channels ADD CONSTRAINT workspace_release_channels_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceResourceMetadataName                        UniqueConstraint = "workspace_resource_metadata_name"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
+	UniqueWorkspaceResourceMetadataPkey                        UniqueConstraint = "workspace_resource_metadata_pkey"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_pkey PRIMARY KEY (id);
+	UniqueWorkspaceResourcesPkey                               UniqueConstraint = "workspace_resources_pkey"                                        // ALTER TABLE ONLY workspace_resources ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
+	UniqueWorkspaceSessionRecordingChunksPkey                  UniqueConstraint = "workspace_session_recording_chunks_pkey"                         // ALTER TABLE ONLY workspace_session_recording_chunks ADD CONSTRAINT workspace_session_recording_chunks_pkey PRIMARY KEY (id);
+	UniqueWorkspaceSessionRecordingsPkey                       UniqueConstraint = "workspace_session_recordings_pkey"                               // ALTER TABLE ONLY workspace_session_recordings ADD CONSTRAINT workspace_session_recordings_pkey PRIMARY KEY (id);
+	UniqueWorkspacesPkey                                       UniqueConstraint = "workspaces_pkey"                                                 // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
+	UniqueAccessRequestsOpenIndex                              UniqueConstraint = "access_requests_open_idx"                                        // CREATE UNIQUE INDEX access_requests_open_idx ON access_requests USING btree (requester_id, resource_id) WHERE (status = ANY (ARRAY['pending'::access_request_status, 'approved'::access_request_status]));
+	UniqueBuildAlertRulesOrganizationIDNameIndex               UniqueConstraint = "build_alert_rules_organization_id_name_idx"                      // CREATE UNIQUE INDEX build_alert_rules_organization_id_name_idx ON build_alert_rules USING btree (organization_id, lower(name));
+	UniqueIndexAPIKeyName                                      UniqueConstraint = "idx_api_key_name"                                                // CREATE UNIQUE INDEX idx_api_key_name ON api_keys USING btree (user_id, token_name) WHERE (login_type = 'token'::login_type);
+	UniqueIndexCustomRolesNameLower                            UniqueConstraint = "idx_custom_roles_name_lower"                                     // CREATE UNIQUE INDEX idx_custom_roles_name_lower ON custom_roles USING btree (lower(name));
+	UniqueIndexOrganizationNameLower                           UniqueConstraint = "idx_organization_name_lower"                                     // CREATE UNIQUE INDEX idx_organization_name_lower ON organizations USING btree (lower(name)) WHERE (deleted = false);
+	UniqueIndexProvisionerDaemonsOrgNameOwnerKey               UniqueConstraint = "idx_provisioner_daemons_org_name_owner_key"                      // CREATE UNIQUE INDEX idx_provisioner_daemons_org_name_owner_key ON provisioner_daemons USING btree (organization_id, name, lower(COALESCE((tags ->> 'owner'::text), ''::text)));
+	UniqueIndexTemplateVersionPresetsDefault                   UniqueConstraint = "idx_template_version_presets_default"                            // CREATE UNIQUE INDEX idx_template_version_presets_default ON template_version_presets USING btree (template_version_id) WHERE (is_default = true);
+	UniqueIndexUniquePresetName                                UniqueConstraint = "idx_unique_preset_name"                                          // CREATE UNIQUE INDEX idx_unique_preset_name ON template_version_presets USING btree (name, template_version_id);
+	UniqueIndexUsersEmail                                      UniqueConstraint = "idx_users_email"                                                 // CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted = false);
+	UniqueIndexUsersUsername                                   UniqueConstraint = "idx_users_username"                                              // CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
+	UniqueNotificationMessagesDedupeHashIndex                  UniqueConstraint = "notification_messages_dedupe_hash_idx"                           // CREATE UNIQUE INDEX notification_messages_dedupe_hash_idx ON notification_messages USING btree (dedupe_hash);
+	UniqueOrganizationsSingleDefaultOrg                        UniqueConstraint = "organizations_single_default_org"                                // CREATE UNIQUE INDEX organizations_single_default_org ON organizations USING btree (is_default) WHERE (is_default = true);
+	UniqueProvisionerBuildPausesDeploymentIndex                UniqueConstraint = "provisioner_build_pauses_deployment_idx"                         // CREATE UNIQUE INDEX provisioner_build_pauses_deployment_idx ON provisioner_build_pauses USING btree (((organization_id IS NULL))) WHERE (organization_id IS NULL);
+	UniqueProvisionerBuildPausesOrganizationIDIndex            UniqueConstraint = "provisioner_build_pauses_organization_id_idx"                    // CREATE UNIQUE INDEX provisioner_build_pauses_organization_id_idx ON provisioner_build_pauses USING btree (organization_id) WHERE (organization_id IS NOT NULL);
+	UniqueProvisionerKeysOrganizationIDNameIndex               UniqueConstraint = "provisioner_keys_organization_id_name_idx"                       // CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
+	UniqueTemplateUsageStatsStartTimeTemplateIDUserIDIndex     UniqueConstraint = "template_usage_stats_start_time_template_id_user_id_idx"         // CREATE UNIQUE INDEX template_usage_stats_start_time_template_id_user_id_idx ON template_usage_stats USING btree (start_time, template_id, user_id);
+	UniqueTemplatesOrganizationIDNameIndex                     UniqueConstraint = "templates_organization_id_name_idx"                              // CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
+	UniqueUserLinksLinkedIDLoginTypeIndex                      UniqueConstraint = "user_links_linked_id_login_type_idx"                             // CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
+	UniqueUsersEmailLowerIndex                                 UniqueConstraint = "users_email_lower_idx"                                           // CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
+	UniqueUsersUsernameLowerIndex                              UniqueConstraint = "users_username_lower_idx"                                        // CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
+	UniqueWorkspaceAgentHealthEventsUnresolvedIndex            UniqueConstraint = "workspace_agent_health_events_unresolved_idx"                    // CREATE UNIQUE INDEX workspace_agent_health_events_unresolved_idx ON workspace_agent_health_events USING btree (agent_id, kind, volume) WHERE (resolved_at IS NULL);
+	UniqueWorkspaceAppAuditSessionsUniqueIndex                 UniqueConstraint = "workspace_app_audit_sessions_unique_index"                       // CREATE UNIQUE INDEX workspace_app_audit_sessions_unique_index ON workspace_app_audit_sessions USING btree (agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code);
+	UniqueWorkspaceDriftChecksJobIDIndex                       UniqueConstraint = "workspace_drift_checks_job_id_idx"                               // CREATE UNIQUE INDEX workspace_drift_checks_job_id_idx ON workspace_drift_checks USING btree (job_id);
+	UniqueWorkspaceLocksWorkspaceIDActiveIndex                 UniqueConstraint = "workspace_locks_workspace_id_active_idx"                         // CREATE UNIQUE INDEX workspace_locks_workspace_id_active_idx ON workspace_locks USING btree (workspace_id) WHERE (unlocked_at IS NULL);
+	UniqueWorkspaceMigrationsWorkspaceIDActiveIndex            UniqueConstraint = "workspace_migrations_workspace_id_active_idx"                    // CREATE UNIQUE INDEX workspace_migrations_workspace_id_active_idx ON workspace_migrations USING btree (workspace_id) WHERE (completed_at IS NULL);
+	UniqueWorkspaceNamingPoliciesOrganizationIDIndex           UniqueConstraint = "workspace_naming_policies_organization_id_idx"                   // CREATE UNIQUE INDEX workspace_naming_policies_organization_id_idx ON workspace_naming_policies USING btree (organization_id) WHERE (template_id IS NULL);
+	UniqueWorkspaceNamingPoliciesTemplateIDIndex               UniqueConstraint = "workspace_naming_policies_template_id_idx"                       // CREATE UNIQUE INDEX workspace_naming_policies_template_id_idx ON workspace_naming_policies USING btree (template_id) WHERE (template_id IS NOT NULL);
+	UniqueWorkspaceProxiesLowerNameIndex                       UniqueConstraint = "workspace_proxies_lower_name_idx"                                // CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
+	UniqueWorkspacesOwnerIDLowerIndex                          UniqueConstraint = "workspaces_owner_id_lower_idx"                                   // CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
 )
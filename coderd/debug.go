@@ -16,8 +16,10 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/jobreaper"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/util/slice"
@@ -124,6 +126,8 @@ func formatHealthcheck(ctx context.Context, rw http.ResponseWriter, r *http.Requ
 			hc.Websocket.Dismissed = true
 		case healthsdk.HealthSectionWorkspaceProxy:
 			hc.WorkspaceProxy.Dismissed = true
+		case healthsdk.HealthSectionProvisionerJobs:
+			hc.ProvisionerJobs.Dismissed = true
 		}
 	}
 
@@ -278,6 +282,110 @@ func validateHealthSettings(settings healthsdk.HealthSettings) error {
 	return nil
 }
 
+// jobReaperReportReapedJobsWindow is how far back the job reaper report looks
+// for jobs terminated by the job reaper.
+const jobReaperReportReapedJobsWindow = 24 * time.Hour
+
+// @Summary Debug Info Job Reaper
+// @ID debug-info-job-reaper
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Success 200 {object} healthsdk.JobReaperReport
+// @Router /debug/job-reaper [get]
+func (api *API) debugJobReaper(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	now := dbtime.Now()
+
+	report := healthsdk.JobReaperReport{
+		ReapedJobs:        []healthsdk.JobReaperJob{},
+		HungJobCandidates: []healthsdk.JobReaperJob{},
+	}
+
+	lastRunAt, err := api.Database.GetJobReaperLastRunAt(ctx)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch last job reaper run.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		t, err := time.Parse(time.RFC3339Nano, lastRunAt)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to parse last job reaper run.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		report.LastRunAt = &t
+	}
+
+	jobs, err := api.Database.GetProvisionerJobsCreatedAfter(ctx, now.Add(-jobReaperReportReapedJobsWindow))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, job := range jobs {
+		if job.ErrorCode.String == string(codersdk.JobReaped) {
+			report.ReapedJobs = append(report.ReapedJobs, convertJobReaperJob(job))
+		}
+	}
+	slices.SortFunc(report.ReapedJobs, func(a, b healthsdk.JobReaperJob) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	cancelDeadline := api.DeploymentValues.Provisioner.CancelDeadline.Value()
+	if cancelDeadline <= 0 {
+		cancelDeadline = jobreaper.CanceledJobDeadline
+	}
+	candidates, err := api.Database.GetProvisionerJobsToBeReaped(ctx, database.GetProvisionerJobsToBeReapedParams{
+		PendingSince:  now.Add(-jobreaper.PendingJobDuration),
+		HungSince:     now.Add(-jobreaper.HungJobDuration),
+		CanceledSince: now.Add(-cancelDeadline),
+		MaxJobs:       jobreaper.MaxJobsPerRun,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch hung provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, job := range candidates {
+		report.HungJobCandidates = append(report.HungJobCandidates, convertJobReaperJob(job))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
+
+func convertJobReaperJob(job database.ProvisionerJob) healthsdk.JobReaperJob {
+	rj := healthsdk.JobReaperJob{
+		ID:             job.ID,
+		OrganizationID: job.OrganizationID,
+		Type:           codersdk.ProvisionerJobType(job.Type),
+		Status:         codersdk.ProvisionerJobStatus(job.JobStatus),
+		Tags:           job.Tags,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+		Error:          job.Error.String,
+	}
+	if job.StartedAt.Valid {
+		rj.StartedAt = &job.StartedAt.Time
+	}
+	if job.CanceledAt.Valid {
+		rj.CanceledAt = &job.CanceledAt.Time
+	}
+	if job.CompletedAt.Valid {
+		rj.CompletedAt = &job.CompletedAt.Time
+	}
+	return rj
+}
+
 // For some reason the swagger docs need to be attached to a function.
 
 // @Summary Debug Info Websocket Test
@@ -2,6 +2,7 @@ package coderd_test
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -15,6 +16,11 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/healthsdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -368,3 +374,40 @@ func TestDebugWebsocket(t *testing.T) {
 		t.Parallel()
 	})
 }
+
+func TestDebugJobReaper(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	var (
+		ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+		client      = coderdtest.New(t, &coderdtest.Options{Database: db, Pubsub: ps})
+		_           = coderdtest.CreateFirstUser(t, client)
+		now         = dbtime.Now()
+	)
+	defer cancel()
+
+	reaped := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		CreatedAt:   now.Add(-time.Hour),
+		UpdatedAt:   now.Add(-time.Hour),
+		CompletedAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+		Error:       sql.NullString{String: "Build has been detected as hung for 5 minutes and has been terminated by the job reaper.", Valid: true},
+		ErrorCode:   sql.NullString{String: string(codersdk.JobReaped), Valid: true},
+	})
+	pending := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		CreatedAt: now.Add(-time.Hour),
+		UpdatedAt: now.Add(-time.Hour),
+	})
+	// A recently created job is not a candidate for reaping.
+	_ = dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{})
+
+	rpt, err := healthsdk.New(client).DebugJobReaper(ctx)
+	require.NoError(t, err)
+	require.Nil(t, rpt.LastRunAt)
+	require.Len(t, rpt.ReapedJobs, 1)
+	assert.Equal(t, reaped.ID, rpt.ReapedJobs[0].ID)
+	assert.Equal(t, codersdk.ProvisionerJobFailed, rpt.ReapedJobs[0].Status)
+	require.Len(t, rpt.HungJobCandidates, 1)
+	assert.Equal(t, pending.ID, rpt.HungJobCandidates[0].ID)
+	assert.Equal(t, codersdk.ProvisionerJobPending, rpt.HungJobCandidates[0].Status)
+}
@@ -1,8 +1,13 @@
 package coderd
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
@@ -61,6 +66,62 @@ func (api *API) deploymentStats(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(r.Context(), rw, http.StatusOK, stats)
 }
 
+// @Summary Get database purge stats
+// @Description Returns the retention of each class of data purged from the
+// @Description database, and the number of rows the purges deleted.
+// @ID get-database-purge-stats
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.PurgeStats
+// @Router /deployment/purge-stats [get]
+func (api *API) purgeStats(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var stored codersdk.PurgeStats
+	//nolint:gocritic // Purge stats are stored as a site config.
+	raw, err := api.Database.GetDBPurgeStats(dbauthz.AsSystemRestricted(ctx))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+
+	// The retention is reported as currently configured, which may differ
+	// from the one the last purge used.
+	retention := map[codersdk.PurgeDataClass]time.Duration{
+		codersdk.PurgeDataClassAgentStats:         api.DeploymentValues.Retention.AgentStats.Value(),
+		codersdk.PurgeDataClassWorkspaceAgentLogs: api.DeploymentValues.Retention.WorkspaceAgentLogs.Value(),
+		codersdk.PurgeDataClassJobLogs:            api.DeploymentValues.Retention.JobLogs.Value(),
+		codersdk.PurgeDataClassAuditLogs:          api.DeploymentValues.Retention.AuditLogs.Value(),
+	}
+	storedClasses := make(map[codersdk.PurgeDataClass]codersdk.PurgeDataClassStats, len(stored.DataClasses))
+	for _, class := range stored.DataClasses {
+		storedClasses[class.DataClass] = class
+	}
+	stats := codersdk.PurgeStats{
+		LastRunAt:   stored.LastRunAt,
+		DataClasses: make([]codersdk.PurgeDataClassStats, 0, len(codersdk.PurgeDataClasses)),
+	}
+	for _, class := range codersdk.PurgeDataClasses {
+		classStats := storedClasses[class]
+		classStats.DataClass = class
+		classStats.RetentionMillis = retention[class].Milliseconds()
+		stats.DataClasses = append(stats.DataClasses, classStats)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, stats)
+}
+
 // @Summary Build info
 // @ID build-info
 // @Produce json
@@ -2,12 +2,17 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -25,6 +30,8 @@ func TestDeploymentValues(t *testing.T) {
 	cfg.OIDC.AuthURLParams.Set(`{"foo":"bar"}`)
 	cfg.OIDC.EmailField.Set("some_random_field_you_never_expected")
 	cfg.PostgresURL.Set(hi)
+	cfg.PubsubRedisURL.Set(hi)
+	cfg.AuditLogExport.WebhookURL.Set(hi)
 	cfg.SCIMAPIKey.Set(hi)
 	cfg.ExternalTokenEncryptionKeys.Set("the_random_key_we_never_expected,an_other_key_we_never_unexpected")
 	cfg.Provisioner.DaemonPSK = "provisionersftw"
@@ -45,6 +52,8 @@ func TestDeploymentValues(t *testing.T) {
 	require.Empty(t, scrubbed.Values.OAuth2.Github.ClientSecret.Value())
 	require.Empty(t, scrubbed.Values.OIDC.ClientSecret.Value())
 	require.Empty(t, scrubbed.Values.PostgresURL.Value())
+	require.Empty(t, scrubbed.Values.PubsubRedisURL.Value())
+	require.Empty(t, scrubbed.Values.AuditLogExport.WebhookURL.Value())
 	require.Empty(t, scrubbed.Values.SCIMAPIKey.Value())
 	require.Empty(t, scrubbed.Values.ExternalTokenEncryptionKeys.Value())
 	require.Empty(t, scrubbed.Values.Provisioner.DaemonPSK.Value())
@@ -62,3 +71,61 @@ func TestDeploymentStats(t *testing.T) {
 		return err == nil
 	}, testutil.IntervalMedium), "failed to get deployment stats in time")
 }
+
+func TestPurgeStats(t *testing.T) {
+	t.Parallel()
+
+	cfg := coderdtest.DeploymentValues(t)
+	require.NoError(t, cfg.Retention.AuditLogs.Set("720h"))
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{DeploymentValues: cfg})
+	owner := coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	// Before the first purge, only the retention is reported.
+	stats, err := client.PurgeStats(ctx)
+	require.NoError(t, err)
+	require.Nil(t, stats.LastRunAt)
+	require.Len(t, stats.DataClasses, len(codersdk.PurgeDataClasses))
+	for _, class := range stats.DataClasses {
+		require.Zero(t, class.TotalDeleted)
+	}
+
+	// The counts of the last purge are reported along with the configured
+	// retention.
+	lastRunAt := time.Now().UTC().Truncate(time.Second)
+	raw, err := json.Marshal(codersdk.PurgeStats{
+		LastRunAt: &lastRunAt,
+		DataClasses: []codersdk.PurgeDataClassStats{{
+			DataClass:       codersdk.PurgeDataClassAuditLogs,
+			RetentionMillis: time.Hour.Milliseconds(),
+			LastRunDeleted:  2,
+			TotalDeleted:    5,
+		}},
+	})
+	require.NoError(t, err)
+	//nolint:gocritic // Purge stats are written by the system.
+	require.NoError(t, db.UpsertDBPurgeStats(dbauthz.AsSystemRestricted(ctx), string(raw)))
+
+	stats, err = client.PurgeStats(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, stats.LastRunAt)
+	require.True(t, lastRunAt.Equal(*stats.LastRunAt))
+	for _, class := range stats.DataClasses {
+		switch class.DataClass {
+		case codersdk.PurgeDataClassAuditLogs:
+			require.Equal(t, (720 * time.Hour).Milliseconds(), class.RetentionMillis)
+			require.EqualValues(t, 2, class.LastRunDeleted)
+			require.EqualValues(t, 5, class.TotalDeleted)
+		case codersdk.PurgeDataClassAgentStats:
+			require.Equal(t, cfg.Retention.AgentStats.Value().Milliseconds(), class.RetentionMillis)
+			require.Zero(t, class.TotalDeleted)
+		}
+	}
+
+	// Members can't read the purge stats.
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	_, err = member.PurgeStats(ctx)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
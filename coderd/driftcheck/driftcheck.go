@@ -0,0 +1,159 @@
+// Package driftcheck periodically plans the latest build of stopped and failed
+// workspaces against their state, to detect resources that were changed
+// outside of Terraform or that are no longer part of the workspace but still
+// exist. The plans run as template version dry-run jobs, and their results are
+// stored when the job completes.
+package driftcheck
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/quartz"
+)
+
+const (
+	delay = time.Minute
+	// batchSize is the maximum number of checks scheduled per tick, so a
+	// large number of stopped workspaces does not flood the provisioners.
+	batchSize = 10
+)
+
+// New creates a new periodically scheduling instance. Every workspace whose
+// latest build is stopped or failed is checked at most once per interval.
+// It is the caller's responsibility to call Close on the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, ps pubsub.Pubsub, interval time.Duration, clk quartz.Clock) io.Closer {
+	closed := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system schedules drift checks without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	// Start the ticker with the initial delay.
+	ticker := clk.NewTicker(delay)
+	doTick := func(start time.Time) {
+		defer ticker.Reset(delay)
+		var jobs []database.ProvisionerJob
+		// Start a transaction to grab advisory lock, we don't want to
+		// schedule the same checks on multiple replicas at the same time.
+		if err := db.InTx(func(tx database.Store) error {
+			ok, err := tx.TryAcquireLock(ctx, database.LockIDDriftCheck)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				logger.Debug(ctx, "unable to acquire lock for scheduling drift checks, skipping")
+				return nil
+			}
+
+			jobs, err = schedule(ctx, tx, start, interval)
+			return err
+		}, database.DefaultTXOptions().WithID("drift_check")); err != nil {
+			logger.Error(ctx, "failed to schedule drift checks", slog.Error(err))
+			return
+		}
+		for _, job := range jobs {
+			if err := provisionerjobs.PostJob(ps, job); err != nil {
+				logger.Error(ctx, "failed to post drift check job", slog.F("job_id", job.ID), slog.Error(err))
+			}
+		}
+		logger.Debug(ctx, "scheduled drift checks", slog.F("count", len(jobs)), slog.F("duration", clk.Since(start)))
+	}
+
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		// Force an initial tick.
+		doTick(dbtime.Time(clk.Now()).UTC())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick := <-ticker.C:
+				ticker.Stop()
+				doTick(dbtime.Time(tick).UTC())
+			}
+		}
+	}()
+	return &instance{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+type instance struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (i *instance) Close() error {
+	i.cancel()
+	<-i.closed
+	return nil
+}
+
+// schedule inserts a dry-run job for each workspace that is due for a check,
+// and returns the inserted jobs.
+func schedule(ctx context.Context, db database.Store, now time.Time, interval time.Duration) ([]database.ProvisionerJob, error) {
+	candidates, err := db.GetWorkspaceDriftCheckCandidates(ctx, database.GetWorkspaceDriftCheckCandidatesParams{
+		CheckedBefore: now.Add(-interval),
+		LimitCount:    batchSize,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get candidates: %w", err)
+	}
+
+	jobs := make([]database.ProvisionerJob, 0, len(candidates))
+	for _, candidate := range candidates {
+		input, err := json.Marshal(provisionerdserver.TemplateVersionDryRunJob{
+			TemplateVersionID: candidate.TemplateVersionID,
+			WorkspaceBuildID:  uuid.NullUUID{UUID: candidate.WorkspaceBuildID, Valid: true},
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("marshal job input: %w", err)
+		}
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:             uuid.New(),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			OrganizationID: candidate.OrganizationID,
+			// The plan runs on behalf of the owner, like the builds of
+			// the workspace.
+			InitiatorID:   candidate.OwnerID,
+			Provisioner:   candidate.Provisioner,
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			FileID:        candidate.FileID,
+			Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+			Input:         input,
+			// Run on the same provisioners as the build that is checked.
+			Tags: candidate.Tags,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("insert provisioner job: %w", err)
+		}
+		err = db.UpsertWorkspaceDriftCheck(ctx, database.UpsertWorkspaceDriftCheckParams{
+			WorkspaceID:      candidate.WorkspaceID,
+			WorkspaceBuildID: candidate.WorkspaceBuildID,
+			JobID:            job.ID,
+			CreatedAt:        now,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("upsert drift check: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
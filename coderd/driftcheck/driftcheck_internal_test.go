@@ -0,0 +1,78 @@
+package driftcheck
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+)
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+
+	// nolint:gocritic // schedule is called by the system.
+	ctx := dbauthz.AsSystemRestricted(context.Background())
+	db, _ := dbtestutil.NewDB(t)
+	now := dbtime.Now()
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	stopped := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).Seed(database.WorkspaceBuild{
+		Transition: database.WorkspaceTransitionStop,
+	}).Do()
+	// Running workspaces are not checked, their resources are expected to
+	// change.
+	dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+		TemplateID:     stopped.Template.ID,
+	}).Seed(database.WorkspaceBuild{
+		Transition: database.WorkspaceTransitionStart,
+	}).Do()
+
+	jobs, err := schedule(ctx, db, now, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	job := jobs[0]
+	require.Equal(t, database.ProvisionerJobTypeTemplateVersionDryRun, job.Type)
+	require.Equal(t, user.ID, job.InitiatorID)
+	var input provisionerdserver.TemplateVersionDryRunJob
+	require.NoError(t, json.Unmarshal(job.Input, &input))
+	require.Equal(t, stopped.Build.TemplateVersionID, input.TemplateVersionID)
+	require.Equal(t, uuid.NullUUID{UUID: stopped.Build.ID, Valid: true}, input.WorkspaceBuildID)
+
+	checks, err := db.GetWorkspaceDriftChecksByWorkspaceIDs(ctx, []uuid.UUID{stopped.Workspace.ID})
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Equal(t, stopped.Build.ID, checks[0].WorkspaceBuildID)
+	require.Equal(t, job.ID, checks[0].JobID)
+	require.False(t, checks[0].CompletedAt.Valid)
+
+	// The workspace is not checked again within the interval.
+	jobs, err = schedule(ctx, db, now.Add(30*time.Minute), time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+
+	// Once the interval passed, a new check replaces the previous one.
+	jobs, err = schedule(ctx, db, now.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	checks, err = db.GetWorkspaceDriftChecksByWorkspaceIDs(ctx, []uuid.UUID{stopped.Workspace.ID})
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Equal(t, jobs[0].ID, checks[0].JobID)
+}
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/archive"
@@ -196,3 +198,32 @@ func (api *API) fileByID(rw http.ResponseWriter, r *http.Request) {
 		})
 	}
 }
+
+// getOrInsertTarFile returns the file of the user with the hash of the tar
+// archive, and inserts it if it does not exist.
+func (api *API) getOrInsertTarFile(ctx context.Context, userID uuid.UUID, data []byte) (database.File, error) {
+	hashBytes := sha256.Sum256(data)
+	hash := hex.EncodeToString(hashBytes[:])
+	file, err := api.Database.GetFileByHashAndCreator(ctx, database.GetFileByHashAndCreatorParams{
+		Hash:      hash,
+		CreatedBy: userID,
+	})
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.File{}, xerrors.Errorf("get file: %w", err)
+	}
+	file, err = api.Database.InsertFile(ctx, database.InsertFileParams{
+		ID:        uuid.New(),
+		Hash:      hash,
+		CreatedBy: userID,
+		CreatedAt: dbtime.Now(),
+		Mimetype:  tarMimeType,
+		Data:      data,
+	})
+	if err != nil {
+		return database.File{}, xerrors.Errorf("insert file: %w", err)
+	}
+	return file, nil
+}
@@ -43,6 +43,10 @@ const (
 	CodeProvisionerDaemonVersionMismatch           Code = `EPD02`
 	CodeProvisionerDaemonAPIMajorVersionDeprecated Code = `EPD03`
 
+	CodeProvisionerJobsQueueDepth     Code = `EPJ01`
+	CodeProvisionerJobsPendingTooLong Code = `EPJ02`
+	CodeProvisionerJobsReaperStale    Code = `EPJ03`
+
 	CodeInterfaceSmallMTU = `EIF01`
 )
 
@@ -19,6 +19,7 @@ type Checker interface {
 	Database(ctx context.Context, opts *DatabaseReportOptions) healthsdk.DatabaseReport
 	WorkspaceProxy(ctx context.Context, opts *WorkspaceProxyReportOptions) healthsdk.WorkspaceProxyReport
 	ProvisionerDaemons(ctx context.Context, opts *ProvisionerDaemonsReportDeps) healthsdk.ProvisionerDaemonsReport
+	ProvisionerJobs(ctx context.Context, opts *ProvisionerJobsReportOptions) healthsdk.ProvisionerJobsReport
 }
 
 type ReportOptions struct {
@@ -28,6 +29,7 @@ type ReportOptions struct {
 	Websocket          WebsocketReportOptions
 	WorkspaceProxy     WorkspaceProxyReportOptions
 	ProvisionerDaemons ProvisionerDaemonsReportDeps
+	ProvisionerJobs    ProvisionerJobsReportOptions
 
 	Checker Checker
 }
@@ -70,6 +72,12 @@ func (defaultChecker) ProvisionerDaemons(ctx context.Context, opts *ProvisionerD
 	return healthsdk.ProvisionerDaemonsReport(report)
 }
 
+func (defaultChecker) ProvisionerJobs(ctx context.Context, opts *ProvisionerJobsReportOptions) healthsdk.ProvisionerJobsReport {
+	var report ProvisionerJobsReport
+	report.Run(ctx, opts)
+	return healthsdk.ProvisionerJobsReport(report)
+}
+
 func Run(ctx context.Context, opts *ReportOptions) *healthsdk.HealthcheckReport {
 	var (
 		wg     sync.WaitGroup
@@ -152,6 +160,18 @@ func Run(ctx context.Context, opts *ReportOptions) *healthsdk.HealthcheckReport
 		report.ProvisionerDaemons = opts.Checker.ProvisionerDaemons(ctx, &opts.ProvisionerDaemons)
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if err := recover(); err != nil {
+				report.ProvisionerJobs.Error = health.Errorf(health.CodeUnknown, "provisioner jobs report panic: %s", err)
+			}
+		}()
+
+		report.ProvisionerJobs = opts.Checker.ProvisionerJobs(ctx, &opts.ProvisionerJobs)
+	}()
+
 	report.CoderVersion = buildinfo.Version()
 	wg.Wait()
 
@@ -175,6 +195,9 @@ func Run(ctx context.Context, opts *ReportOptions) *healthsdk.HealthcheckReport
 	if report.ProvisionerDaemons.Severity.Value() > health.SeverityWarning.Value() {
 		failingSections = append(failingSections, healthsdk.HealthSectionProvisionerDaemons)
 	}
+	if report.ProvisionerJobs.Severity.Value() > health.SeverityWarning.Value() {
+		failingSections = append(failingSections, healthsdk.HealthSectionProvisionerJobs)
+	}
 
 	report.Healthy = len(failingSections) == 0
 
@@ -199,6 +222,9 @@ func Run(ctx context.Context, opts *ReportOptions) *healthsdk.HealthcheckReport
 	if report.ProvisionerDaemons.Severity.Value() > report.Severity.Value() {
 		report.Severity = report.ProvisionerDaemons.Severity
 	}
+	if report.ProvisionerJobs.Severity.Value() > report.Severity.Value() {
+		report.Severity = report.ProvisionerJobs.Severity
+	}
 	return &report
 }
 
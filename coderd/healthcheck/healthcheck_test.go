@@ -19,6 +19,7 @@ type testChecker struct {
 	DatabaseReport           healthsdk.DatabaseReport
 	WorkspaceProxyReport     healthsdk.WorkspaceProxyReport
 	ProvisionerDaemonsReport healthsdk.ProvisionerDaemonsReport
+	ProvisionerJobsReport    healthsdk.ProvisionerJobsReport
 }
 
 func (c *testChecker) DERP(context.Context, *derphealth.ReportOptions) healthsdk.DERPHealthReport {
@@ -45,6 +46,10 @@ func (c *testChecker) ProvisionerDaemons(context.Context, *healthcheck.Provision
 	return c.ProvisionerDaemonsReport
 }
 
+func (c *testChecker) ProvisionerJobs(context.Context, *healthcheck.ProvisionerJobsReportOptions) healthsdk.ProvisionerJobsReport {
+	return c.ProvisionerJobsReport
+}
+
 func TestHealthcheck(t *testing.T) {
 	t.Parallel()
 
@@ -466,6 +471,99 @@ func TestHealthcheck(t *testing.T) {
 		},
 		severity: health.SeverityWarning,
 		healthy:  true,
+	}, {
+		name: "ProvisionerJobsFail",
+		checker: &testChecker{
+			DERPReport: healthsdk.DERPHealthReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			AccessURLReport: healthsdk.AccessURLReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			WebsocketReport: healthsdk.WebsocketReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			DatabaseReport: healthsdk.DatabaseReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			WorkspaceProxyReport: healthsdk.WorkspaceProxyReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			ProvisionerDaemonsReport: healthsdk.ProvisionerDaemonsReport{
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			ProvisionerJobsReport: healthsdk.ProvisionerJobsReport{
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityError,
+				},
+			},
+		},
+		severity: health.SeverityError,
+		healthy:  false,
+	}, {
+		name: "ProvisionerJobsWarn",
+		checker: &testChecker{
+			DERPReport: healthsdk.DERPHealthReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			AccessURLReport: healthsdk.AccessURLReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			WebsocketReport: healthsdk.WebsocketReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			DatabaseReport: healthsdk.DatabaseReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			WorkspaceProxyReport: healthsdk.WorkspaceProxyReport{
+				Healthy: true,
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			ProvisionerDaemonsReport: healthsdk.ProvisionerDaemonsReport{
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityOK,
+				},
+			},
+			ProvisionerJobsReport: healthsdk.ProvisionerJobsReport{
+				BaseReport: healthsdk.BaseReport{
+					Severity: health.SeverityWarning,
+					Warnings: []health.Message{{Message: "foobar", Code: "EFOOBAR"}},
+				},
+			},
+		},
+		severity: health.SeverityWarning,
+		healthy:  true,
 	}, {
 		name:    "AllFail",
 		healthy: false,
@@ -528,6 +626,7 @@ func TestHealthcheck(t *testing.T) {
 			assert.Equal(t, c.checker.WebsocketReport.Severity, report.Websocket.Severity)
 			assert.Equal(t, c.checker.DatabaseReport.Healthy, report.Database.Healthy)
 			assert.Equal(t, c.checker.DatabaseReport.Severity, report.Database.Severity)
+			assert.Equal(t, c.checker.ProvisionerJobsReport.Severity, report.ProvisionerJobs.Severity)
 			assert.NotZero(t, report.Time)
 			assert.NotZero(t, report.CoderVersion)
 		})
@@ -0,0 +1,145 @@
+package healthcheck
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
+)
+
+const (
+	ProvisionerJobsDefaultQueueDepthThreshold         = 50
+	ProvisionerJobsDefaultPendingJobAgeThreshold      = 15 * time.Minute
+	ProvisionerJobsDefaultJobReaperStalenessThreshold = 10 * time.Minute
+)
+
+type ProvisionerJobsReport healthsdk.ProvisionerJobsReport
+
+type ProvisionerJobsReportOptions struct {
+	// Required
+	Store ProvisionerJobsStore
+
+	// Optional
+	QueueDepthThreshold         int64            // Defaults to ProvisionerJobsDefaultQueueDepthThreshold
+	PendingJobAgeThreshold      time.Duration    // Defaults to ProvisionerJobsDefaultPendingJobAgeThreshold
+	JobReaperStalenessThreshold time.Duration    // Defaults to ProvisionerJobsDefaultJobReaperStalenessThreshold
+	TimeNow                     func() time.Time // Defaults to dbtime.Now
+
+	Dismissed bool
+}
+
+type ProvisionerJobsStore interface {
+	GetPendingProvisionerJobsQueueDepthByTags(ctx context.Context) ([]database.GetPendingProvisionerJobsQueueDepthByTagsRow, error)
+	GetJobReaperLastRunAt(ctx context.Context) (string, error)
+}
+
+func (r *ProvisionerJobsReport) Run(ctx context.Context, opts *ProvisionerJobsReportOptions) {
+	r.QueueDepths = make([]healthsdk.ProvisionerJobsQueueDepth, 0)
+	r.Severity = health.SeverityOK
+	r.Warnings = make([]health.Message, 0)
+	r.Dismissed = opts.Dismissed
+
+	if opts.TimeNow == nil {
+		opts.TimeNow = dbtime.Now
+	}
+	now := opts.TimeNow()
+
+	r.QueueDepthThreshold = opts.QueueDepthThreshold
+	if r.QueueDepthThreshold == 0 {
+		r.QueueDepthThreshold = ProvisionerJobsDefaultQueueDepthThreshold
+	}
+	pendingJobAgeThreshold := opts.PendingJobAgeThreshold
+	if pendingJobAgeThreshold == 0 {
+		pendingJobAgeThreshold = ProvisionerJobsDefaultPendingJobAgeThreshold
+	}
+	r.PendingJobAgeThresholdMS = pendingJobAgeThreshold.Milliseconds()
+	jobReaperStalenessThreshold := opts.JobReaperStalenessThreshold
+	if jobReaperStalenessThreshold == 0 {
+		jobReaperStalenessThreshold = ProvisionerJobsDefaultJobReaperStalenessThreshold
+	}
+	r.JobReaperStalenessThresholdMS = jobReaperStalenessThreshold.Milliseconds()
+
+	if opts.Store == nil {
+		r.Severity = health.SeverityError
+		r.Error = ptr.Ref("Developer error: Store is nil!")
+		return
+	}
+
+	// nolint: gocritic // need an actor to fetch the provisioner job queue
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	queues, err := opts.Store.GetPendingProvisionerJobsQueueDepthByTags(ctx)
+	if err != nil {
+		r.Severity = health.SeverityError
+		r.Error = ptr.Ref("error fetching provisioner job queue depths: " + err.Error())
+		return
+	}
+
+	var deepQueues int
+	for _, queue := range queues {
+		age := now.Sub(queue.OldestCreatedAt)
+		r.QueueDepths = append(r.QueueDepths, healthsdk.ProvisionerJobsQueueDepth{
+			OrganizationID:        queue.OrganizationID,
+			Tags:                  queue.Tags,
+			PendingJobs:           queue.PendingJobs,
+			OldestPendingJobAgeMS: age.Milliseconds(),
+		})
+		if age.Milliseconds() > r.OldestPendingJobAgeMS {
+			r.OldestPendingJobAgeMS = age.Milliseconds()
+		}
+		if queue.PendingJobs >= r.QueueDepthThreshold {
+			deepQueues++
+		}
+	}
+
+	if deepQueues > 0 {
+		r.Severity = health.SeverityWarning
+		r.Warnings = append(r.Warnings, health.Messagef(health.CodeProvisionerJobsQueueDepth,
+			"%d provisioner job queue(s) have %d or more pending jobs", deepQueues, r.QueueDepthThreshold))
+	}
+	if r.OldestPendingJobAgeMS >= r.PendingJobAgeThresholdMS {
+		r.Severity = health.SeverityWarning
+		r.Warnings = append(r.Warnings, health.Messagef(health.CodeProvisionerJobsPendingTooLong,
+			"oldest pending provisioner job has been waiting for %s", time.Duration(r.OldestPendingJobAgeMS)*time.Millisecond))
+	}
+
+	lastRunAt, err := jobReaperLastRunAt(ctx, opts.Store)
+	if err != nil {
+		r.Severity = health.SeverityError
+		r.Error = ptr.Ref("error fetching last job reaper run: " + err.Error())
+		return
+	}
+	// A deployment that never ran the job reaper has nothing to report yet.
+	if lastRunAt.IsZero() {
+		return
+	}
+	r.JobReaperLastRunAt = &lastRunAt
+	if since := now.Sub(lastRunAt); since >= jobReaperStalenessThreshold {
+		r.Severity = health.SeverityWarning
+		r.Warnings = append(r.Warnings, health.Messagef(health.CodeProvisionerJobsReaperStale,
+			"job reaper last ran successfully %s ago", since.Truncate(time.Second)))
+	}
+}
+
+func jobReaperLastRunAt(ctx context.Context, store ProvisionerJobsStore) (time.Time, error) {
+	value, err := store.GetJobReaperLastRunAt(ctx)
+	if err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	lastRunAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("parse %q: %w", value, err)
+	}
+	return lastRunAt, nil
+}
@@ -0,0 +1,132 @@
+package healthcheck_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
+)
+
+func TestProvisionerJobsReport(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now   = dbtime.Now()
+		orgID = uuid.New()
+		tags  = database.StringMap{"scope": "organization", "owner": ""}
+	)
+
+	for _, tt := range []struct {
+		name                string
+		queues              []database.GetPendingProvisionerJobsQueueDepthByTagsRow
+		queuesErr           error
+		jobReaperLastRunAt  string
+		jobReaperErr        error
+		expectedSeverity    health.Severity
+		expectedWarningCode health.Code
+		expectedError       string
+		expectedQueueDepths []healthsdk.ProvisionerJobsQueueDepth
+	}{
+		{
+			name:                "no pending jobs",
+			jobReaperLastRunAt:  now.Add(-time.Minute).Format(time.RFC3339Nano),
+			expectedSeverity:    health.SeverityOK,
+			expectedQueueDepths: []healthsdk.ProvisionerJobsQueueDepth{},
+		},
+		{
+			name: "pending jobs below thresholds",
+			queues: []database.GetPendingProvisionerJobsQueueDepthByTagsRow{
+				{OrganizationID: orgID, Tags: tags, PendingJobs: 3, OldestCreatedAt: now.Add(-time.Minute)},
+			},
+			jobReaperLastRunAt: now.Add(-time.Minute).Format(time.RFC3339Nano),
+			expectedSeverity:   health.SeverityOK,
+			expectedQueueDepths: []healthsdk.ProvisionerJobsQueueDepth{
+				{OrganizationID: orgID, Tags: tags, PendingJobs: 3, OldestPendingJobAgeMS: time.Minute.Milliseconds()},
+			},
+		},
+		{
+			name: "queue too deep",
+			queues: []database.GetPendingProvisionerJobsQueueDepthByTagsRow{
+				{OrganizationID: orgID, Tags: tags, PendingJobs: healthcheck.ProvisionerJobsDefaultQueueDepthThreshold, OldestCreatedAt: now.Add(-time.Minute)},
+			},
+			jobReaperLastRunAt:  now.Add(-time.Minute).Format(time.RFC3339Nano),
+			expectedSeverity:    health.SeverityWarning,
+			expectedWarningCode: health.CodeProvisionerJobsQueueDepth,
+		},
+		{
+			name: "pending job too old",
+			queues: []database.GetPendingProvisionerJobsQueueDepthByTagsRow{
+				{OrganizationID: orgID, Tags: tags, PendingJobs: 1, OldestCreatedAt: now.Add(-time.Hour)},
+			},
+			jobReaperLastRunAt:  now.Add(-time.Minute).Format(time.RFC3339Nano),
+			expectedSeverity:    health.SeverityWarning,
+			expectedWarningCode: health.CodeProvisionerJobsPendingTooLong,
+		},
+		{
+			name:                "job reaper stale",
+			jobReaperLastRunAt:  now.Add(-time.Hour).Format(time.RFC3339Nano),
+			expectedSeverity:    health.SeverityWarning,
+			expectedWarningCode: health.CodeProvisionerJobsReaperStale,
+		},
+		{
+			name:             "job reaper never ran",
+			jobReaperErr:     sql.ErrNoRows,
+			expectedSeverity: health.SeverityOK,
+		},
+		{
+			name:             "error fetching queue depths",
+			queuesErr:        assert.AnError,
+			expectedSeverity: health.SeverityError,
+			expectedError:    assert.AnError.Error(),
+		},
+		{
+			name:             "error fetching job reaper run",
+			jobReaperErr:     assert.AnError,
+			expectedSeverity: health.SeverityError,
+			expectedError:    assert.AnError.Error(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mDB := dbmock.NewMockStore(ctrl)
+			mDB.EXPECT().GetPendingProvisionerJobsQueueDepthByTags(gomock.Any()).AnyTimes().Return(tt.queues, tt.queuesErr)
+			mDB.EXPECT().GetJobReaperLastRunAt(gomock.Any()).AnyTimes().Return(tt.jobReaperLastRunAt, tt.jobReaperErr)
+
+			var rpt healthcheck.ProvisionerJobsReport
+			rpt.Run(context.Background(), &healthcheck.ProvisionerJobsReportOptions{
+				Store: mDB,
+				TimeNow: func() time.Time {
+					return now
+				},
+			})
+
+			assert.Equal(t, tt.expectedSeverity, rpt.Severity)
+			if tt.expectedWarningCode != "" && assert.Len(t, rpt.Warnings, 1) {
+				assert.Equal(t, tt.expectedWarningCode, rpt.Warnings[0].Code)
+			} else {
+				assert.Empty(t, rpt.Warnings)
+			}
+			if tt.expectedError != "" && assert.NotNil(t, rpt.Error) {
+				assert.Contains(t, *rpt.Error, tt.expectedError)
+			} else {
+				assert.Nil(t, rpt.Error)
+			}
+			if tt.expectedQueueDepths != nil {
+				assert.Equal(t, tt.expectedQueueDepths, rpt.QueueDepths)
+			}
+		})
+	}
+}
@@ -25,7 +25,8 @@ func StripCoderCookies(header string) string {
 			name == codersdk.OAuth2RedirectCookie ||
 			name == codersdk.PathAppSessionTokenCookie ||
 			name == codersdk.SubdomainAppSessionTokenCookie ||
-			name == codersdk.SignedAppTokenCookie {
+			name == codersdk.SignedAppTokenCookie ||
+			name == codersdk.DeviceIDCookie {
 			continue
 		}
 		cookies = append(cookies, part)
@@ -0,0 +1,222 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// FieldSelection is the set of JSON fields of a response that a client asked
+// for. Nested fields are selected with dots, e.g. "latest_build.status". A
+// field with an empty selection is returned in full.
+type FieldSelection map[string]FieldSelection
+
+// ParseFields parses a comma-separated list of JSON fields of T. Unknown fields
+// are reported as errors on the parser. A nil selection is returned when the
+// query param is not set, which selects all fields.
+func ParseFields[T any](parser *QueryParamParser, vals url.Values, queryParam string) FieldSelection {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	paths := ParseCustomList(parser, vals, nil, queryParam, func(v string) (string, error) {
+		v = strings.TrimSpace(v)
+		err := validateFieldPath(typ, v)
+		if err != nil {
+			return "", err
+		}
+		return v, nil
+	})
+	if len(paths) == 0 {
+		return nil
+	}
+	selection := FieldSelection{}
+	for _, path := range paths {
+		selection.add(strings.Split(path, "."))
+	}
+	return selection
+}
+
+func (s FieldSelection) add(path []string) {
+	if len(path) == 1 {
+		s[path[0]] = FieldSelection{}
+		return
+	}
+	sub, ok := s[path[0]]
+	if ok && len(sub) == 0 {
+		// The whole field is already selected.
+		return
+	}
+	if !ok {
+		sub = FieldSelection{}
+		s[path[0]] = sub
+	}
+	sub.add(path[1:])
+}
+
+// Project marshals v and removes the fields that are not selected. The
+// selection applies to each element of arrays.
+func (s FieldSelection) Project(v any) (json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal: %w", err)
+	}
+	return s.project(b)
+}
+
+func (s FieldSelection) project(b json.RawMessage) (json.RawMessage, error) {
+	if len(s) == 0 {
+		return b, nil
+	}
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 {
+		return b, nil
+	}
+	switch trimmed[0] {
+	case '[':
+		var items []json.RawMessage
+		err := json.Unmarshal(trimmed, &items)
+		if err != nil {
+			return nil, xerrors.Errorf("unmarshal array: %w", err)
+		}
+		for i, item := range items {
+			items[i], err = s.project(item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(items)
+	case '{':
+		var object map[string]json.RawMessage
+		err := json.Unmarshal(trimmed, &object)
+		if err != nil {
+			return nil, xerrors.Errorf("unmarshal object: %w", err)
+		}
+		projected := make(map[string]json.RawMessage, len(s))
+		for name, sub := range s {
+			value, ok := object[name]
+			if !ok {
+				continue
+			}
+			projected[name], err = sub.project(value)
+			if err != nil {
+				return nil, xerrors.Errorf("field %q: %w", name, err)
+			}
+		}
+		return json.Marshal(projected)
+	default:
+		// Scalars and null have no fields to select.
+		return b, nil
+	}
+}
+
+// WriteFields writes the selected fields of the response. The whole response
+// is written if the selection is empty.
+func WriteFields(ctx context.Context, rw http.ResponseWriter, status int, fields FieldSelection, response interface{}) {
+	if len(fields) == 0 {
+		Write(ctx, rw, status, response)
+		return
+	}
+	projected, err := fields.Project(response)
+	if err != nil {
+		Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error selecting response fields.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	Write(ctx, rw, status, projected)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// validateFieldPath checks that the dot-separated path refers to a JSON field
+// of typ.
+func validateFieldPath(typ reflect.Type, path string) error {
+	for _, name := range strings.Split(path, ".") {
+		if name == "" {
+			return xerrors.Errorf("%q is not a valid field", path)
+		}
+		typ = fieldValueType(typ)
+		switch {
+		case isJSONMarshaler(typ):
+			return xerrors.Errorf("%q is not a valid field", path)
+		case typ.Kind() == reflect.Map:
+			// Map keys are not known ahead of time.
+			return nil
+		case typ.Kind() == reflect.Struct:
+			field, ok := jsonField(typ, name)
+			if !ok {
+				return xerrors.Errorf("%q is not a valid field", path)
+			}
+			typ = field
+		default:
+			return xerrors.Errorf("%q is not a valid field", path)
+		}
+	}
+	return nil
+}
+
+// fieldValueType returns the type whose fields are selected for a value of
+// typ, looking through pointers and the elements of slices.
+func fieldValueType(typ reflect.Type) reflect.Type {
+	for !isJSONMarshaler(typ) {
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			typ = typ.Elem()
+		default:
+			return typ
+		}
+	}
+	return typ
+}
+
+func isJSONMarshaler(typ reflect.Type) bool {
+	ptr := reflect.PointerTo(typ)
+	return typ.Implements(jsonMarshalerType) || ptr.Implements(jsonMarshalerType) ||
+		typ.Implements(textMarshalerType) || ptr.Implements(textMarshalerType)
+}
+
+// jsonField returns the type of the struct field that is marshaled with the
+// given name, including fields of embedded structs.
+func jsonField(typ reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagName, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && tagName == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if t, ok := jsonField(embedded, name); ok {
+					return t, true
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if tagName == "" {
+			tagName = field.Name
+		}
+		if tagName == name {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
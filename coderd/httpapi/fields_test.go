@@ -0,0 +1,154 @@
+package httpapi_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+)
+
+type fieldsTestBuild struct {
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type fieldsTestEmbedded struct {
+	Owner string `json:"owner"`
+}
+
+type fieldsTestWorkspace struct {
+	fieldsTestEmbedded
+	ID          uuid.UUID         `json:"id"`
+	Name        string            `json:"name"`
+	LatestBuild fieldsTestBuild   `json:"latest_build"`
+	Builds      []fieldsTestBuild `json:"builds"`
+	Labels      map[string]string `json:"labels"`
+	Internal    string            `json:"-"`
+}
+
+func TestParseFields(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Name                  string
+		Values                []string
+		Expected              httpapi.FieldSelection
+		ExpectedErrorContains string
+	}{
+		{
+			Name:     "NotSet",
+			Expected: nil,
+		},
+		{
+			Name:   "TopLevel",
+			Values: []string{"id,name"},
+			Expected: httpapi.FieldSelection{
+				"id":   {},
+				"name": {},
+			},
+		},
+		{
+			Name:   "Nested",
+			Values: []string{"latest_build.status", "builds.created_at"},
+			Expected: httpapi.FieldSelection{
+				"latest_build": {"status": {}},
+				"builds":       {"created_at": {}},
+			},
+		},
+		{
+			Name:   "WholeOverridesNested",
+			Values: []string{"latest_build.status,latest_build,latest_build.created_at"},
+			Expected: httpapi.FieldSelection{
+				"latest_build": {},
+			},
+		},
+		{
+			Name:   "EmbeddedAndMap",
+			Values: []string{"owner,labels.team"},
+			Expected: httpapi.FieldSelection{
+				"owner":  {},
+				"labels": {"team": {}},
+			},
+		},
+		{
+			Name:                  "Unknown",
+			Values:                []string{"id,nope"},
+			ExpectedErrorContains: `"nope" is not a valid field`,
+		},
+		{
+			Name:                  "Ignored",
+			Values:                []string{"-"},
+			ExpectedErrorContains: `"-" is not a valid field`,
+		},
+		{
+			Name:                  "NestedScalar",
+			Values:                []string{"name.length"},
+			ExpectedErrorContains: `"name.length" is not a valid field`,
+		},
+		{
+			Name:                  "NestedMarshaler",
+			Values:                []string{"latest_build.created_at.day"},
+			ExpectedErrorContains: `"latest_build.created_at.day" is not a valid field`,
+		},
+		{
+			Name:                  "Empty",
+			Values:                []string{"id,"},
+			ExpectedErrorContains: `"" is not a valid field`,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := httpapi.NewQueryParamParser()
+			vals := url.Values{}
+			for _, v := range tc.Values {
+				vals.Add("fields", v)
+			}
+			fields := httpapi.ParseFields[fieldsTestWorkspace](parser, vals, "fields")
+			if tc.ExpectedErrorContains != "" {
+				require.Len(t, parser.Errors, 1)
+				require.Contains(t, parser.Errors[0].Detail, tc.ExpectedErrorContains)
+				return
+			}
+			require.Empty(t, parser.Errors)
+			require.Equal(t, tc.Expected, fields)
+		})
+	}
+}
+
+func TestFieldSelectionProject(t *testing.T) {
+	t.Parallel()
+
+	workspaces := []fieldsTestWorkspace{{
+		fieldsTestEmbedded: fieldsTestEmbedded{Owner: "alice"},
+		Name:               "dev",
+		LatestBuild:        fieldsTestBuild{Status: "running"},
+		Builds:             []fieldsTestBuild{{Status: "running"}, {Status: "stopped"}},
+		Labels:             map[string]string{"team": "a", "env": "prod"},
+	}}
+
+	projected, err := httpapi.FieldSelection{
+		"name":         {},
+		"owner":        {},
+		"latest_build": {"status": {}},
+		"builds":       {"status": {}},
+		"labels":       {"team": {}},
+	}.Project(workspaces)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{
+		"name": "dev",
+		"owner": "alice",
+		"latest_build": {"status": "running"},
+		"builds": [{"status": "running"}, {"status": "stopped"}],
+		"labels": {"team": "a"}
+	}]`, string(projected))
+
+	// Null values are kept as they are.
+	projected, err = httpapi.FieldSelection{"builds": {"status": {}}}.Project(fieldsTestWorkspace{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"builds": null}`, string(projected))
+}
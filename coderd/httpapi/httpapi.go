@@ -146,7 +146,10 @@ func IsUnauthorizedError(err error) bool {
 // Convenience error functions don't take contexts since their responses are
 // static, it doesn't make much sense to trace them.
 
-var ResourceNotFoundResponse = codersdk.Response{Message: "Resource not found or you do not have access to this resource"}
+var ResourceNotFoundResponse = codersdk.Response{
+	Message: "Resource not found or you do not have access to this resource",
+	Code:    codersdk.ResponseCodeNotFound,
+}
 
 // ResourceNotFound is intentionally vague. All 404 responses should be identical
 // to prevent leaking existence of resources.
@@ -157,6 +160,7 @@ func ResourceNotFound(rw http.ResponseWriter) {
 var ResourceForbiddenResponse = codersdk.Response{
 	Message: "Forbidden.",
 	Detail:  "You don't have permission to view this content. If you believe this is a mistake, please contact your administrator or try signing in with different credentials.",
+	Code:    codersdk.ResponseCodeForbidden,
 }
 
 func Forbidden(rw http.ResponseWriter) {
@@ -22,10 +22,43 @@ func WriteWorkspaceBuildError(ctx context.Context, rw http.ResponseWriter, err e
 			buildErr.Status = http.StatusForbidden
 		}
 
-		httpapi.Write(ctx, rw, buildErr.Status, codersdk.Response{
+		resp := codersdk.Response{
 			Message: buildErr.Message,
 			Detail:  buildErr.Error(),
-		})
+		}
+		switch {
+		case errors.Is(err, wsbuilder.ErrBuildActive):
+			resp.Code = codersdk.ResponseCodeBuildInProgress
+		case errors.Is(err, wsbuilder.ErrWorkspaceLocked):
+			resp.Code = codersdk.ResponseCodeWorkspaceLocked
+		case errors.Is(err, wsbuilder.ErrJobLimitReached):
+			resp.Code = codersdk.ResponseCodeJobLimitReached
+		case buildErr.Status == http.StatusNotFound:
+			resp.Code = codersdk.ResponseCodeNotFound
+		case buildErr.Status == http.StatusForbidden:
+			resp.Code = codersdk.ResponseCodeForbidden
+		}
+		var preflightErr wsbuilder.PreflightError
+		if errors.As(err, &preflightErr) {
+			resp.Code = codersdk.ResponseCodePreflightFailed
+			if len(preflightErr.Failures) > 0 && preflightErr.Failures[0].Code != "" {
+				resp.Code = preflightErr.Failures[0].Code
+			}
+			for _, failure := range preflightErr.Failures {
+				// Failures that don't relate to a field are reported against
+				// the check that failed.
+				field := failure.Field
+				if field == "" {
+					field = failure.Check
+				}
+				resp.Validations = append(resp.Validations, codersdk.ValidationError{
+					Field:  field,
+					Detail: failure.Message,
+				})
+			}
+		}
+
+		httpapi.Write(ctx, rw, buildErr.Status, resp)
 		return
 	}
 
@@ -231,6 +231,52 @@ func (p *QueryParamParser) Time3339Nano(vals url.Values, def time.Time, queryPar
 	return p.timeWithMutate(vals, def, queryParam, layout, strings.ToUpper)
 }
 
+// Time3339NanoOrAgo is like Time3339Nano, but also accepts a relative
+// duration such as "30d" or "12h", which is interpreted as that long before
+// now.
+func (p *QueryParamParser) Time3339NanoOrAgo(vals url.Values, def time.Time, queryParam string, now time.Time) time.Time {
+	v, err := parseQueryParam(p, vals, func(term string) (time.Time, error) {
+		if ago, ok := parseAgo(term); ok {
+			return now.Add(-ago).UTC(), nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, strings.ToUpper(term))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.UTC(), nil
+	}, def, queryParam)
+	if err != nil {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  queryParam,
+			Detail: fmt.Sprintf("Query param %q must be a valid date format (%s) or a duration such as \"30d\": %s", queryParam, time.RFC3339Nano, err.Error()),
+		})
+	}
+	return v
+}
+
+// parseAgo parses a positive duration that additionally supports days ("d")
+// and weeks ("w") as units.
+func parseAgo(term string) (time.Duration, bool) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(term, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(term, "w"):
+		unit = 7 * 24 * time.Hour
+	default:
+		d, err := time.ParseDuration(term)
+		if err != nil || d <= 0 {
+			return 0, false
+		}
+		return d, true
+	}
+	n, err := strconv.ParseUint(term[:len(term)-1], 10, 16)
+	if err != nil || n == 0 {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
 func (p *QueryParamParser) timeWithMutate(vals url.Values, def time.Time, queryParam, layout string, mutate func(term string) string) time.Time {
 	v, err := parseQueryParam(p, vals, func(term string) (time.Time, error) {
 		if mutate != nil {
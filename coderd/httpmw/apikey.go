@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
@@ -25,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/rolestore"
+	"github.com/coder/coder/v2/coderd/readonly"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -77,6 +79,7 @@ func (c *OAuth2Configs) IsZero() bool {
 
 const (
 	SignedOutErrorMessage = "You are signed out or your session has expired. Please sign in again to continue."
+	BoundAPIKeyMessage    = "This session token is bound to another client. Please sign in again on this machine to continue."
 	internalErrorMessage  = "An internal error occurred. Please try again or contact the system administrator."
 )
 
@@ -86,6 +89,15 @@ type ExtractAPIKeyConfig struct {
 	OAuth2Configs               *OAuth2Configs
 	RedirectToLogin             bool
 	DisableSessionExpiryRefresh bool
+	// SessionTokenBinding requires API keys to be used from the client
+	// certificate or device they were issued to. Requests presenting no or
+	// another client identity are rejected. Empty or disabled turns binding
+	// off.
+	SessionTokenBinding codersdk.SessionTokenBinding
+	// ReadOnlyMode skips refreshing the last used time and expiry of API keys
+	// while the deployment is in read-only mode, so reads keep working when
+	// writes are not possible.
+	ReadOnlyMode *readonly.Mode
 
 	// Optional governs whether the API key is optional. Use this if you want to
 	// allow unauthenticated requests.
@@ -190,6 +202,82 @@ func APIKeyFromRequest(ctx context.Context, db database.Store, sessionTokenFunc
 	return &key, codersdk.Response{}, true
 }
 
+// APIKeyBoundIdentity returns the identity of the client making the request
+// under the given session token binding mode. API keys are bound to it when
+// they are issued. An empty string is returned if binding is disabled or the
+// request doesn't present an identity.
+func APIKeyBoundIdentity(mode codersdk.SessionTokenBinding, r *http.Request) string {
+	switch mode {
+	case codersdk.SessionTokenBindingCertificate:
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return ""
+		}
+		fingerprint := sha256.Sum256(r.TLS.PeerCertificates[0].Raw)
+		return "certificate:" + hex.EncodeToString(fingerprint[:])
+	case codersdk.SessionTokenBindingDevice:
+		deviceID := DeviceID(r)
+		if deviceID == "" {
+			return ""
+		}
+		// The device ID is hashed so arbitrarily long values are stored with
+		// a fixed length.
+		hashed := sha256.Sum256([]byte(deviceID))
+		return "device:" + hex.EncodeToString(hashed[:])
+	default:
+		return ""
+	}
+}
+
+// DeviceID returns the device ID the client sent with r, or an empty string
+// if it didn't send one.
+func DeviceID(r *http.Request) string {
+	deviceID := strings.TrimSpace(r.Header.Get(codersdk.DeviceIDHeader))
+	if deviceID == "" {
+		// Browsers can't set the header, so their device ID is kept in a
+		// cookie instead.
+		if cookie, err := r.Cookie(codersdk.DeviceIDCookie); err == nil {
+			deviceID = strings.TrimSpace(cookie.Value)
+		}
+	}
+	return deviceID
+}
+
+// checkAPIKeyBinding enforces the session token binding mode. Keys are bound
+// when they are issued, so requests that don't present an identity, keys that
+// were issued without one, and keys bound to another identity are all
+// rejected.
+func checkAPIKeyBinding(mode codersdk.SessionTokenBinding, key *database.APIKey, r *http.Request) (codersdk.Response, bool) {
+	var missing string
+	switch mode {
+	case codersdk.SessionTokenBindingCertificate:
+		missing = "The request did not present a client certificate."
+	case codersdk.SessionTokenBindingDevice:
+		missing = fmt.Sprintf("The request did not include the %q header or the %q cookie.", codersdk.DeviceIDHeader, codersdk.DeviceIDCookie)
+	default:
+		return codersdk.Response{}, true
+	}
+
+	identity := APIKeyBoundIdentity(mode, r)
+	switch {
+	case identity == "":
+		return codersdk.Response{
+			Message: BoundAPIKeyMessage,
+			Detail:  missing,
+		}, false
+	case key.BoundIdentity == "":
+		return codersdk.Response{
+			Message: BoundAPIKeyMessage,
+			Detail:  fmt.Sprintf("The token was issued without being bound to a %s.", mode),
+		}, false
+	case subtle.ConstantTimeCompare([]byte(identity), []byte(key.BoundIdentity)) != 1:
+		return codersdk.Response{
+			Message: BoundAPIKeyMessage,
+			Detail:  fmt.Sprintf("The token is bound to a different %s.", mode),
+		}, false
+	}
+	return codersdk.Response{}, true
+}
+
 // ExtractAPIKey requires authentication using a valid API key. It handles
 // extending an API key if it comes close to expiry, updating the last used time
 // in the database.
@@ -240,6 +328,12 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	// Check the binding before acting on behalf of the user, so a replayed
+	// key can't be used to refresh the OIDC token either.
+	if resp, ok := checkAPIKeyBinding(cfg.SessionTokenBinding, key, r); !ok {
+		return optionalWrite(http.StatusUnauthorized, resp)
+	}
+
 	// We only check OIDC stuff if we have a valid APIKey. An expired key means we don't trust the requestor
 	// really is the user whose key they have, and so we shouldn't be doing anything on their behalf including possibly
 	// refreshing the OIDC token.
@@ -374,7 +468,7 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 			changed = true
 		}
 	}
-	if changed {
+	if changed && !cfg.ReadOnlyMode.Enabled() {
 		//nolint:gocritic // System needs to update API Key LastUsed
 		err := cfg.DB.UpdateAPIKeyByID(dbauthz.AsSystemRestricted(ctx), database.UpdateAPIKeyByIDParams{
 			ID:        key.ID,
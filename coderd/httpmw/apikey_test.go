@@ -3,6 +3,8 @@ package httpmw_test
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -990,4 +992,98 @@ func TestAPIKey(t *testing.T) {
 		defer res.Body.Close()
 		require.Equal(t, http.StatusOK, res.StatusCode)
 	})
+
+	t.Run("DeviceBinding", func(t *testing.T) {
+		t.Parallel()
+		issuedTo := httptest.NewRequest("POST", "/", nil)
+		issuedTo.Header.Set(codersdk.DeviceIDHeader, "laptop")
+		var (
+			db, _    = dbtestutil.NewDB(t)
+			user     = dbgen.User(t, db, database.User{})
+			_, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:        user.ID,
+				ExpiresAt:     dbtime.Now().AddDate(0, 0, 1),
+				BoundIdentity: httpmw.APIKeyBoundIdentity(codersdk.SessionTokenBindingDevice, issuedTo),
+			})
+			_, unboundToken = dbgen.APIKey(t, db, database.APIKey{
+				UserID:    user.ID,
+				ExpiresAt: dbtime.Now().AddDate(0, 0, 1),
+			})
+		)
+		mw := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB:                  db,
+			SessionTokenBinding: codersdk.SessionTokenBindingDevice,
+		})(successHandler)
+		do := func(token string, setDevice func(r *http.Request)) *http.Response {
+			r := httptest.NewRequest("GET", "/", nil)
+			rw := httptest.NewRecorder()
+			r.Header.Set(codersdk.SessionTokenHeader, token)
+			if setDevice != nil {
+				setDevice(r)
+			}
+			mw.ServeHTTP(rw, r)
+			res := rw.Result()
+			t.Cleanup(func() { _ = res.Body.Close() })
+			return res
+		}
+		header := func(deviceID string) func(r *http.Request) {
+			return func(r *http.Request) { r.Header.Set(codersdk.DeviceIDHeader, deviceID) }
+		}
+
+		// The device the key was issued to can use it, whether it sends the
+		// device ID in the header or in the cookie browsers use.
+		require.Equal(t, http.StatusOK, do(token, header("laptop")).StatusCode)
+		require.Equal(t, http.StatusOK, do(token, func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: codersdk.DeviceIDCookie, Value: "laptop"})
+		}).StatusCode)
+
+		// Other devices and requests without a device are rejected.
+		res := do(token, header("stolen"))
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		var resp codersdk.Response
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&resp))
+		require.Contains(t, resp.Message, "bound to another client")
+		require.Equal(t, http.StatusUnauthorized, do(token, nil).StatusCode)
+
+		// Keys issued without a device are rejected rather than bound on
+		// first use.
+		require.Equal(t, http.StatusUnauthorized, do(unboundToken, header("laptop")).StatusCode)
+		require.Equal(t, http.StatusUnauthorized, do(unboundToken, nil).StatusCode)
+	})
+
+	t.Run("CertificateBinding", func(t *testing.T) {
+		t.Parallel()
+		laptop := &x509.Certificate{Raw: []byte("laptop")}
+		issuedTo := httptest.NewRequest("POST", "/", nil)
+		issuedTo.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{laptop}}
+		var (
+			db, _    = dbtestutil.NewDB(t)
+			user     = dbgen.User(t, db, database.User{})
+			_, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:        user.ID,
+				ExpiresAt:     dbtime.Now().AddDate(0, 0, 1),
+				BoundIdentity: httpmw.APIKeyBoundIdentity(codersdk.SessionTokenBindingCertificate, issuedTo),
+			})
+		)
+		mw := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB:                  db,
+			SessionTokenBinding: codersdk.SessionTokenBindingCertificate,
+		})(successHandler)
+		do := func(cert *x509.Certificate) int {
+			r := httptest.NewRequest("GET", "/", nil)
+			rw := httptest.NewRecorder()
+			r.Header.Set(codersdk.SessionTokenHeader, token)
+			if cert != nil {
+				r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+			}
+			mw.ServeHTTP(rw, r)
+			res := rw.Result()
+			_ = res.Body.Close()
+			return res.StatusCode
+		}
+
+		require.Equal(t, http.StatusOK, do(laptop))
+		require.Equal(t, http.StatusUnauthorized, do(&x509.Certificate{Raw: []byte("stolen")}))
+		require.Equal(t, http.StatusUnauthorized, do(nil))
+	})
 }
@@ -129,6 +129,7 @@ func (c *SlogRequestLogger) addAuthContextFields() {
 }
 
 var actorLogOrder = []rbac.SubjectType{
+	rbac.SubjectTypeAccessRequestGranter,
 	rbac.SubjectTypeAutostart,
 	rbac.SubjectTypeCryptoKeyReader,
 	rbac.SubjectTypeCryptoKeyRotator,
@@ -3,10 +3,15 @@ package httpmw
 import (
 	"context"
 	"crypto/subtle"
+	"database/sql"
 	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/provisionerkey"
 	"github.com/coder/coder/v2/codersdk"
@@ -14,6 +19,10 @@ import (
 
 type provisionerDaemonContextKey struct{}
 
+// provisionerKeyLastUsedInterval is the minimum time between updates of a
+// provisioner key's last used timestamp.
+const provisionerKeyLastUsedInterval = time.Minute
+
 func ProvisionerDaemonAuthenticated(r *http.Request) bool {
 	proxy, ok := r.Context().Value(provisionerDaemonContextKey{}).(bool)
 	return ok && proxy
@@ -87,13 +96,40 @@ func ExtractProvisionerDaemonAuthenticated(opts ExtractProvisionerAuthConfig) fu
 				return
 			}
 
-			if provisionerkey.Compare(pk.HashedSecret, hashedKey) {
+			now := dbtime.Now()
+			err = provisionerkey.Verify(pk, hashedKey, now)
+			if xerrors.Is(err, provisionerkey.ErrExpired) {
+				handleOptional(http.StatusUnauthorized, codersdk.Response{
+					Message: "provisioner daemon key expired",
+					Detail:  "Rotate the key or create a new one to continue authenticating.",
+				})
+				return
+			}
+			if err != nil {
 				handleOptional(http.StatusUnauthorized, codersdk.Response{
 					Message: "provisioner daemon key invalid",
 				})
 				return
 			}
 
+			// Only bump the last used timestamp once per interval to avoid a
+			// write on every request from a large fleet of daemons.
+			if !pk.LastUsedAt.Valid || now.Sub(pk.LastUsedAt.Time) > provisionerKeyLastUsedInterval {
+				pk.LastUsedAt = sql.NullTime{Time: now, Valid: true}
+				// nolint:gocritic // System must record provisioner key usage.
+				err = opts.DB.UpdateProvisionerKeyLastUsedAt(dbauthz.AsSystemRestricted(ctx), database.UpdateProvisionerKeyLastUsedAtParams{
+					ID:         pk.ID,
+					LastUsedAt: pk.LastUsedAt,
+				})
+				if err != nil {
+					handleOptional(http.StatusInternalServerError, codersdk.Response{
+						Message: "update provisioner daemon key last used",
+						Detail:  err.Error(),
+					})
+					return
+				}
+			}
+
 			// The provisioner key does not indicate a specific provisioner daemon. So just
 			// store a boolean so the caller can check if the request is from an
 			// authenticated provisioner daemon.
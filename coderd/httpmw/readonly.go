@@ -0,0 +1,42 @@
+package httpmw
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/readonly"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// readOnlyRetryAfterSeconds is a hint to clients on how long to wait before
+// retrying a rejected request. Maintenance windows usually last longer, but
+// clients shouldn't back off so far that they miss the end of one.
+const readOnlyRetryAfterSeconds = 60
+
+// RejectMutationsInReadOnlyMode rejects requests that may mutate state with a
+// 503 while the deployment is in read-only mode. GET, HEAD and OPTIONS
+// requests are always passed through, as are requests to the exempt paths
+// (e.g. the endpoint that turns read-only mode off).
+func RejectMutationsInReadOnlyMode(mode *readonly.Mode, exemptPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(rw, r)
+				return
+			}
+			if !mode.Enabled() || slices.Contains(exemptPaths, r.URL.Path) {
+				next.ServeHTTP(rw, r)
+				return
+			}
+
+			rw.Header().Set("Retry-After", fmt.Sprintf("%d", readOnlyRetryAfterSeconds))
+			httpapi.Write(r.Context(), rw, http.StatusServiceUnavailable, codersdk.Response{
+				Message: "This deployment is in read-only mode for maintenance.",
+				Detail:  fmt.Sprintf("%s %s modifies state and was rejected. Only read requests are served until maintenance completes, retry later.", r.Method, r.URL.Path),
+			})
+		})
+	}
+}
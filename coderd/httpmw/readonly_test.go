@@ -0,0 +1,75 @@
+package httpmw_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/readonly"
+)
+
+func TestRejectMutationsInReadOnlyMode(t *testing.T) {
+	t.Parallel()
+
+	const exemptPath = "/api/v2/deployment/read-only"
+
+	tests := []struct {
+		Name     string
+		Enabled  bool
+		Method   string
+		Path     string
+		Rejected bool
+	}{
+		{Name: "DisabledPost", Enabled: false, Method: http.MethodPost, Path: "/api/v2/users"},
+		{Name: "EnabledGet", Enabled: true, Method: http.MethodGet, Path: "/api/v2/users"},
+		{Name: "EnabledHead", Enabled: true, Method: http.MethodHead, Path: "/api/v2/users"},
+		{Name: "EnabledOptions", Enabled: true, Method: http.MethodOptions, Path: "/api/v2/users"},
+		{Name: "EnabledPost", Enabled: true, Method: http.MethodPost, Path: "/api/v2/users", Rejected: true},
+		{Name: "EnabledPut", Enabled: true, Method: http.MethodPut, Path: "/api/v2/users/me/profile", Rejected: true},
+		{Name: "EnabledPatch", Enabled: true, Method: http.MethodPatch, Path: "/api/v2/templates/x", Rejected: true},
+		{Name: "EnabledDelete", Enabled: true, Method: http.MethodDelete, Path: "/api/v2/workspaces/x", Rejected: true},
+		{Name: "EnabledExempt", Enabled: true, Method: http.MethodPut, Path: exemptPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var mode readonly.Mode
+			mode.Set(tt.Enabled)
+
+			handler := httpmw.RejectMutationsInReadOnlyMode(&mode, exemptPath)(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			}))
+
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.Method, tt.Path, nil)
+			handler.ServeHTTP(rw, r)
+
+			if tt.Rejected {
+				require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+				require.Equal(t, "60", rw.Header().Get("Retry-After"))
+				require.Contains(t, rw.Body.String(), "read-only mode")
+				return
+			}
+			require.Equal(t, http.StatusOK, rw.Code)
+			require.Empty(t, rw.Header().Get("Retry-After"))
+		})
+	}
+
+	t.Run("NilMode", func(t *testing.T) {
+		t.Parallel()
+
+		handler := httpmw.RejectMutationsInReadOnlyMode(nil)(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		}))
+
+		rw := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/api/v2/users", nil)
+		handler.ServeHTTP(rw, r)
+		require.Equal(t, http.StatusOK, rw.Code)
+	})
+}
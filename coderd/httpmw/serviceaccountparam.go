@@ -0,0 +1,78 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type serviceAccountParamContextKey struct{}
+
+// ServiceAccount is the database object plus the user of the service account.
+type ServiceAccount struct {
+	database.ServiceAccount
+	User database.User
+}
+
+// ServiceAccountParam returns the service account from the
+// ExtractServiceAccountParam handler.
+func ServiceAccountParam(r *http.Request) ServiceAccount {
+	serviceAccount, ok := r.Context().Value(serviceAccountParamContextKey{}).(ServiceAccount)
+	if !ok {
+		panic("developer error: service account param middleware not provided")
+	}
+	return serviceAccount
+}
+
+// ExtractServiceAccountParam grabs a service account from the "organization"
+// and "user" URL parameters. This middleware requires the ExtractOrganization
+// middleware higher in the stack.
+func ExtractServiceAccountParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			organization := OrganizationParam(r)
+
+			// The user is resolved as SystemRestricted since the caller might
+			// be allowed to manage service accounts, but not to read users.
+			// The user is only added to the context if the caller can read
+			// the service account, which is the same thing.
+			// nolint:gocritic
+			user, ok := ExtractUserContext(dbauthz.AsSystemRestricted(ctx), db, rw, r)
+			if !ok {
+				return
+			}
+			if user.Deleted {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+
+			serviceAccount, err := db.GetServiceAccountByUserID(ctx, user.ID)
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching service account.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			if serviceAccount.OrganizationID != organization.ID {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+
+			ctx = context.WithValue(ctx, serviceAccountParamContextKey{}, ServiceAccount{
+				ServiceAccount: serviceAccount,
+				User:           user,
+			})
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
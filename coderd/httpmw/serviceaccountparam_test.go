@@ -0,0 +1,80 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/httpmw"
+)
+
+func TestServiceAccountParam(t *testing.T) {
+	t.Parallel()
+
+	serve := func(t *testing.T, db database.Store, organization database.Organization, user string) *http.Response {
+		t.Helper()
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+
+		router := chi.NewRouter()
+		router.Use(
+			httpmw.ExtractOrganizationParam(db),
+			httpmw.ExtractServiceAccountParam(db),
+		)
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			serviceAccount := httpmw.ServiceAccountParam(r)
+			require.Equal(t, user, serviceAccount.User.Username)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("organization", organization.ID.String())
+		rctx.URLParams.Add("user", user)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		router.ServeHTTP(w, r)
+		res := w.Result()
+		t.Cleanup(func() { _ = res.Body.Close() })
+		return res
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		db, _ := dbtestutil.NewDB(t)
+		organization := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{LoginType: database.LoginTypeNone})
+		dbgen.ServiceAccount(t, db, database.ServiceAccount{UserID: user.ID, OrganizationID: organization.ID})
+
+		res := serve(t, db, organization, user.Username)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("NotServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		db, _ := dbtestutil.NewDB(t)
+		organization := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+
+		res := serve(t, db, organization, user.Username)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("OtherOrganization", func(t *testing.T) {
+		t.Parallel()
+		db, _ := dbtestutil.NewDB(t)
+		organization := dbgen.Organization(t, db, database.Organization{})
+		other := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{LoginType: database.LoginTypeNone})
+		dbgen.ServiceAccount(t, db, database.ServiceAccount{UserID: user.ID, OrganizationID: other.ID})
+
+		res := serve(t, db, organization, user.Username)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
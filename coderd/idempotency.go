@@ -0,0 +1,239 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxIdempotencyKeyLength limits the length of the Idempotency-Key header.
+// UUIDs and other generated keys fit comfortably.
+const maxIdempotencyKeyLength = 255
+
+var idempotencyKeyReusedResponse = codersdk.Response{
+	Message: "The idempotency key was already used for a different request.",
+	Detail:  "Use a new idempotency key for every request that isn't a retry.",
+	Code:    codersdk.ResponseCodeIdempotencyKeyReused,
+}
+
+// idempotencyKey is the Idempotency-Key header of a request. Keys are unique
+// per user.
+type idempotencyKey struct {
+	UserID uuid.UUID
+	Key    string
+	// RequestHash identifies the request, a retry with the same key must be
+	// the same request.
+	RequestHash []byte
+}
+
+// parseIdempotencyKey returns the Idempotency-Key header of the request made
+// with body, whose Key is empty if it's not set. If ok is false, the key is
+// invalid and a response has been written.
+func parseIdempotencyKey(ctx context.Context, rw http.ResponseWriter, r *http.Request, body any) (key idempotencyKey, ok bool) {
+	key.Key = r.Header.Get(codersdk.IdempotencyKeyHeader)
+	if key.Key == "" {
+		return idempotencyKey{}, true
+	}
+	if len(key.Key) > maxIdempotencyKeyLength {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The %s header must not be longer than %d characters.", codersdk.IdempotencyKeyHeader, maxIdempotencyKeyLength),
+		})
+		return idempotencyKey{}, false
+	}
+	key.UserID = httpmw.APIKey(r).UserID
+
+	// The body is hashed as it was decoded, so retries don't have to be
+	// formatted the same.
+	data, err := json.Marshal(body)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error hashing request.",
+			Detail:  err.Error(),
+		})
+		return idempotencyKey{}, false
+	}
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.Path)
+	_, _ = hash.Write(data)
+	key.RequestHash = hash.Sum(nil)
+	return key, true
+}
+
+// reserveIdempotencyKey reserves the idempotency key in the transaction that
+// does the work of the request, before doing it. A concurrent request with the
+// same key waits for the transaction to end, and then fails with an error that
+// isIdempotencyKeyConflict reports, so that it can replay what was created.
+func reserveIdempotencyKey(ctx context.Context, tx database.Store, key idempotencyKey) error {
+	if key.Key == "" {
+		return nil
+	}
+	err := tx.InsertWorkspaceBuildIdempotencyKey(ctx, database.InsertWorkspaceBuildIdempotencyKeyParams{
+		UserID:         key.UserID,
+		IdempotencyKey: key.Key,
+		RequestHash:    key.RequestHash,
+		CreatedAt:      dbtime.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("reserve idempotency key: %w", err)
+	}
+	return nil
+}
+
+// idempotentWorkspaceBuild returns the workspace build that an earlier request
+// of the user with the same idempotency key created. ok is false if there is
+// none, or the user can no longer read it. reused is true if the earlier
+// request differs from this one.
+func (api *API) idempotentWorkspaceBuild(ctx context.Context, key idempotencyKey) (_ database.WorkspaceBuild, ok bool, reused bool, _ error) {
+	if key.Key == "" {
+		return database.WorkspaceBuild{}, false, false, nil
+	}
+	row, err := api.Database.GetWorkspaceBuildIdempotencyKey(ctx, database.GetWorkspaceBuildIdempotencyKeyParams{
+		UserID:         key.UserID,
+		IdempotencyKey: key.Key,
+	})
+	if httpapi.Is404Error(err) {
+		return database.WorkspaceBuild{}, false, false, nil
+	}
+	if err != nil {
+		return database.WorkspaceBuild{}, false, false, xerrors.Errorf("get idempotency key: %w", err)
+	}
+	if !bytes.Equal(row.RequestHash, key.RequestHash) {
+		return database.WorkspaceBuild{}, false, true, nil
+	}
+	if !row.WorkspaceBuildID.Valid {
+		return database.WorkspaceBuild{}, false, false, nil
+	}
+	build, err := api.Database.GetWorkspaceBuildByID(ctx, row.WorkspaceBuildID.UUID)
+	if httpapi.Is404Error(err) {
+		return database.WorkspaceBuild{}, false, false, nil
+	}
+	if err != nil {
+		return database.WorkspaceBuild{}, false, false, xerrors.Errorf("get workspace build: %w", err)
+	}
+	return build, true, false, nil
+}
+
+// isIdempotencyKeyConflict returns whether the request could not be done
+// because a concurrent request reserved the same idempotency key.
+func isIdempotencyKeyConflict(err error) bool {
+	return database.IsUniqueViolation(err, database.UniqueWorkspaceBuildIdempotencyKeysUserIDIdempotencyKeyKey)
+}
+
+// replayCreateWorkspace writes the workspace that an earlier request with the
+// same idempotency key created. It returns false if there's no such request,
+// in which case nothing has been written.
+func (api *API) replayCreateWorkspace(ctx context.Context, rw http.ResponseWriter, key idempotencyKey) bool {
+	build, ok, reused, err := api.idempotentWorkspaceBuild(ctx, key)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build by idempotency key.",
+			Detail:  err.Error(),
+		})
+		return true
+	}
+	if reused {
+		httpapi.Write(ctx, rw, http.StatusUnprocessableEntity, idempotencyKeyReusedResponse)
+		return true
+	}
+	if !ok {
+		return false
+	}
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return true
+	}
+	data, err := api.workspaceData(ctx, []database.Workspace{workspace})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return true
+	}
+	apiWorkspaces, err := convertWorkspaces(key.UserID, []database.Workspace{workspace}, data)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace.",
+			Detail:  err.Error(),
+		})
+		return true
+	}
+	if len(apiWorkspaces) == 0 {
+		httpapi.Forbidden(rw)
+		return true
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, apiWorkspaces[0])
+	return true
+}
+
+// replayWorkspaceBuild writes the workspace build that an earlier request with
+// the same idempotency key created. It returns false if there's no such
+// request, in which case nothing has been written.
+func (api *API) replayWorkspaceBuild(ctx context.Context, rw http.ResponseWriter, key idempotencyKey, workspace database.Workspace) bool {
+	build, ok, reused, err := api.idempotentWorkspaceBuild(ctx, key)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build by idempotency key.",
+			Detail:  err.Error(),
+		})
+		return true
+	}
+	if reused {
+		httpapi.Write(ctx, rw, http.StatusUnprocessableEntity, idempotencyKeyReusedResponse)
+		return true
+	}
+	if !ok {
+		return false
+	}
+
+	data, err := api.workspaceBuildsData(ctx, []database.WorkspaceBuild{build})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error getting workspace build data.",
+			Detail:  err.Error(),
+		})
+		return true
+	}
+	apiBuild, err := api.convertWorkspaceBuild(
+		build,
+		workspace,
+		data.jobs[0],
+		data.resources,
+		data.metadata,
+		data.agents,
+		data.apps,
+		data.appStatuses,
+		data.scripts,
+		data.logSources,
+		data.templateVersions[0],
+		data.provisionerDaemons,
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+		return true
+	}
+	api.estimateProvisionerJobStarts(ctx, &apiBuild.Job)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+	return true
+}
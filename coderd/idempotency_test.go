@@ -0,0 +1,136 @@
+package coderd_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	t.Run("CreateWorkspace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		key := codersdk.WithIdempotencyKey(uuid.NewString())
+		req := codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       coderdtest.RandomUsername(t),
+		}
+
+		workspace, err := member.CreateUserWorkspace(ctx, codersdk.Me, req, key)
+		require.NoError(t, err)
+
+		// Retrying the request returns the workspace it created.
+		retried, err := member.CreateUserWorkspace(ctx, codersdk.Me, req, key)
+		require.NoError(t, err)
+		require.Equal(t, workspace.ID, retried.ID)
+		require.Equal(t, workspace.LatestBuild.ID, retried.LatestBuild.ID)
+
+		// The key can't be used for another workspace.
+		req.Name = coderdtest.RandomUsername(t)
+		_, err = member.CreateUserWorkspace(ctx, codersdk.Me, req, key)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode())
+		require.Equal(t, codersdk.ResponseCodeIdempotencyKeyReused, apiErr.Code)
+
+		// Keys are unique per user.
+		workspace, err = client.CreateUserWorkspace(ctx, codersdk.Me, req, key)
+		require.NoError(t, err)
+		require.NotEqual(t, retried.ID, workspace.ID)
+	})
+
+	t.Run("ConcurrentCreateWorkspace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		key := codersdk.WithIdempotencyKey(uuid.NewString())
+		req := codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       coderdtest.RandomUsername(t),
+		}
+
+		// Concurrent requests with the same key wait for the first one, and
+		// return the workspace it created instead of conflicting with it.
+		var eg errgroup.Group
+		workspaces := make([]codersdk.Workspace, 5)
+		for i := range workspaces {
+			eg.Go(func() error {
+				var err error
+				workspaces[i], err = member.CreateUserWorkspace(ctx, codersdk.Me, req, key)
+				return err
+			})
+		}
+		require.NoError(t, eg.Wait())
+		for _, workspace := range workspaces {
+			require.Equal(t, workspaces[0].ID, workspace.ID)
+			require.Equal(t, workspaces[0].LatestBuild.ID, workspace.LatestBuild.ID)
+		}
+	})
+
+	t.Run("CreateWorkspaceBuild", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+		key := codersdk.WithIdempotencyKey(uuid.NewString())
+		req := codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		}
+
+		build, err := member.CreateWorkspaceBuild(ctx, workspace.ID, req, key)
+		require.NoError(t, err)
+
+		// Retrying the request returns the build it created, even though
+		// another build would conflict with it while it's active.
+		retried, err := member.CreateWorkspaceBuild(ctx, workspace.ID, req, key)
+		require.NoError(t, err)
+		require.Equal(t, build.ID, retried.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+
+		// The key can't be used for builds of another workspace.
+		other := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, other.LatestBuild.ID)
+		_, err = member.CreateWorkspaceBuild(ctx, other.ID, req, key)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode())
+		require.Equal(t, codersdk.ResponseCodeIdempotencyKeyReused, apiErr.Code)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		_, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		}, codersdk.WithIdempotencyKey(strings.Repeat("a", 256)))
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+			Queue:      true,
+		}, codersdk.WithIdempotencyKey(uuid.NewString()))
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -136,27 +136,6 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 				continue
 			}
 
-			// expectedGroups is the set of groups the IDP expects the
-			// user to be a member of.
-			expectedGroups, err := settings.ParseClaims(orgID, params.MergedClaims)
-			if err != nil {
-				s.Logger.Debug(ctx, "failed to parse claims for groups",
-					slog.F("organization_field", s.GroupField),
-					slog.F("organization_id", orgID),
-					slog.Error(err),
-				)
-				// Unsure where to raise this error on the UI or database.
-				// TODO: This error prevents group sync, but we have no way
-				// to raise this to an org admin. Come up with a solution to
-				// notify the admin and user of this issue.
-				continue
-			}
-			// Everyone group is always implied, so include it.
-			expectedGroups = append(expectedGroups, ExpectedGroup{
-				OrganizationID: orgID,
-				GroupID:        &orgID,
-			})
-
 			// Now we know what groups the user should be in for a given org,
 			// determine if we have to do any group updates to sync the user's
 			// state.
@@ -169,9 +148,19 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 				}
 			})
 
-			add, remove := slice.SymmetricDifferenceFunc(existingGroupsTyped, expectedGroups, func(a, b ExpectedGroup) bool {
-				return a.Equal(b)
-			})
+			add, remove, err := settings.Difference(orgID, params.MergedClaims, existingGroupsTyped)
+			if err != nil {
+				s.Logger.Debug(ctx, "failed to parse claims for groups",
+					slog.F("organization_field", s.GroupField),
+					slog.F("organization_id", orgID),
+					slog.Error(err),
+				)
+				// Unsure where to raise this error on the UI or database.
+				// TODO: This error prevents group sync, but we have no way
+				// to raise this to an org admin. Come up with a solution to
+				// notify the admin and user of this issue.
+				continue
+			}
 
 			for _, r := range remove {
 				if r.GroupID == nil {
@@ -335,7 +324,7 @@ func (s GroupSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClai
 	}
 
 	groups := make([]ExpectedGroup, 0)
-	for _, group := range parsedGroups {
+	for _, group := range FlattenNestedGroups(parsedGroups, s.NestedGroupSeparator) {
 		// Legacy group mappings happen before the regex filter.
 		mappedGroupName, ok := s.LegacyNameMapping[group]
 		if ok {
@@ -349,7 +338,7 @@ func (s GroupSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClai
 			}
 		}
 
-		mappedGroupIDs, ok := s.Mapping[group]
+		mappedGroupIDs, ok := mappedIDs(s.Mapping, s.MappingRules, group)
 		if ok {
 			for _, gid := range mappedGroupIDs {
 				groups = append(groups, ExpectedGroup{OrganizationID: orgID, GroupID: &gid})
@@ -363,6 +352,28 @@ func (s GroupSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClai
 	return groups, nil
 }
 
+// Difference returns the groups the user has to be added to and removed from
+// for their groups in the organization to match the claims. The "Everyone"
+// group is always expected.
+func (s GroupSyncSettings) Difference(orgID uuid.UUID, mergedClaims jwt.MapClaims, existing []ExpectedGroup) (add []ExpectedGroup, remove []ExpectedGroup, err error) {
+	// expectedGroups is the set of groups the IDP expects the
+	// user to be a member of.
+	expectedGroups, err := s.ParseClaims(orgID, mergedClaims)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Everyone group is always implied, so include it.
+	expectedGroups = append(expectedGroups, ExpectedGroup{
+		OrganizationID: orgID,
+		GroupID:        &orgID,
+	})
+
+	add, remove = slice.SymmetricDifferenceFunc(existing, expectedGroups, func(a, b ExpectedGroup) bool {
+		return a.Equal(b)
+	})
+	return add, remove, nil
+}
+
 // HandleMissingGroups ensures all ExpectedGroups convert to uuids.
 // Groups can be referenced by name via legacy params or IDP group names.
 // These group names are converted to IDs for easier assignment.
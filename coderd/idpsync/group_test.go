@@ -74,6 +74,7 @@ func TestGroupSyncTable(t *testing.T) {
 			"foo", "bar", "baz",
 			"create-bar", "create-baz",
 			"legacy-bar",
+			"eng/backend",
 		},
 	}
 
@@ -240,6 +241,46 @@ func TestGroupSyncTable(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "MappingRules",
+			GroupSettings: &codersdk.GroupSyncSettings{
+				Field: "groups",
+				MappingRules: []codersdk.IDPSyncMappingRule{
+					{Prefix: "create-", Gets: []uuid.UUID{ids.ID("mr-create")}},
+					{Regex: regexp.MustCompile("^ba[rz]$"), Gets: []uuid.UUID{ids.ID("mr-ba")}},
+				},
+			},
+			Groups: map[uuid.UUID]bool{
+				ids.ID("mr-create"): false,
+				ids.ID("mr-ba"):     false,
+				ids.ID("mr-other"):  true,
+			},
+			assertGroups: &orgGroupAssert{
+				ExpectedGroups: []uuid.UUID{
+					ids.ID("mr-create"),
+					ids.ID("mr-ba"),
+				},
+			},
+		},
+		{
+			Name: "NestedGroups",
+			GroupSettings: &codersdk.GroupSyncSettings{
+				Field:                "groups",
+				NestedGroupSeparator: "/",
+				Mapping: map[string][]uuid.UUID{
+					// Only the parent of "eng/backend" is mapped.
+					"eng": {ids.ID("ne-eng")},
+				},
+			},
+			Groups: map[uuid.UUID]bool{
+				ids.ID("ne-eng"): false,
+			},
+			assertGroups: &orgGroupAssert{
+				ExpectedGroups: []uuid.UUID{
+					ids.ID("ne-eng"),
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -14,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/site"
 )
@@ -222,6 +224,45 @@ func ParseStringSliceClaim(claim interface{}) ([]string, error) {
 	return nil, xerrors.Errorf("invalid claim type. Expected an array of strings, got: %T", claim)
 }
 
+// FlattenNestedGroups adds the ancestors of nested claim values, so a user in
+// a nested group is considered to be in its parent groups as well. With "/" as
+// the separator, "eng/backend" adds "eng". A leading separator, which some IdPs
+// use for group paths, is kept: "/eng/backend" adds "/eng".
+func FlattenNestedGroups(values []string, separator string) []string {
+	if separator == "" {
+		return values
+	}
+
+	flattened := make([]string, 0, len(values))
+	for _, value := range values {
+		for i := 0; i < len(value); {
+			idx := strings.Index(value[i:], separator)
+			if idx < 0 {
+				break
+			}
+			if i+idx > 0 {
+				flattened = append(flattened, value[:i+idx])
+			}
+			i += idx + len(separator)
+		}
+		flattened = append(flattened, value)
+	}
+	return slice.Unique(flattened)
+}
+
+// mappedIDs returns the IDs a claim value maps to, from both the mapping and
+// the mapping rules. The boolean is false if nothing matched the value.
+func mappedIDs(mapping map[string][]uuid.UUID, rules []codersdk.IDPSyncMappingRule, value string) ([]uuid.UUID, bool) {
+	ids, ok := mapping[value]
+	for _, rule := range rules {
+		if rule.Matches(value) {
+			ids = append(slices.Clip(ids), rule.Gets...)
+			ok = true
+		}
+	}
+	return ids, ok
+}
+
 // IsHTTPError handles us being inconsistent with returning errors as values or
 // pointers.
 func IsHTTPError(err error) *HTTPError {
@@ -199,3 +199,46 @@ func TestIsHTTPError(t *testing.T) {
 
 	require.Nil(t, error(nil))
 }
+
+func TestFlattenNestedGroups(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name      string
+		Values    []string
+		Separator string
+		Expected  []string
+	}{
+		{
+			Name:     "NoSeparator",
+			Values:   []string{"eng/backend", "web"},
+			Expected: []string{"eng/backend", "web"},
+		},
+		{
+			Name:      "Nested",
+			Values:    []string{"eng/backend/api", "web"},
+			Separator: "/",
+			Expected:  []string{"eng/backend/api", "eng", "eng/backend", "web"},
+		},
+		{
+			Name:      "LeadingSeparator",
+			Values:    []string{"/eng/backend"},
+			Separator: "/",
+			Expected:  []string{"/eng/backend", "/eng"},
+		},
+		{
+			Name:      "Duplicates",
+			Values:    []string{"eng/backend", "eng/frontend", "eng"},
+			Separator: "/",
+			Expected:  []string{"eng/backend", "eng", "eng/frontend"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			require.ElementsMatch(t, c.Expected, idpsync.FlattenNestedGroups(c.Values, c.Separator))
+		})
+	}
+}
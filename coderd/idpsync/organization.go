@@ -16,6 +16,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/runtimeconfig"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 type OrganizationParams struct {
@@ -210,6 +211,10 @@ type OrganizationSyncSettings struct {
 	// placed into the default organization. This is mostly a hack to support
 	// legacy deployments.
 	AssignDefault bool `json:"assign_default"`
+	// MappingRules map every claim value matching a rule to organizations.
+	MappingRules []codersdk.IDPSyncMappingRule `json:"mapping_rules,omitempty"`
+	// NestedGroupSeparator flattens nested claim values when set.
+	NestedGroupSeparator string `json:"nested_group_separator,omitempty"`
 }
 
 func (s *OrganizationSyncSettings) Set(v string) error {
@@ -273,8 +278,8 @@ func (s *OrganizationSyncSettings) ParseClaims(ctx context.Context, db database.
 	}
 
 	// add any mapped organizations
-	for _, parsedOrg := range parsedOrganizations {
-		if mappedOrganization, ok := s.Mapping[parsedOrg]; ok {
+	for _, parsedOrg := range FlattenNestedGroups(parsedOrganizations, s.NestedGroupSeparator) {
+		if mappedOrganization, ok := mappedIDs(s.Mapping, s.MappingRules, parsedOrg); ok {
 			// parsedOrg is in the mapping, so add the mapped organizations to the
 			// user's organizations.
 			userOrganizations = append(userOrganizations, mappedOrganization...)
@@ -42,6 +42,9 @@ var fallbackIcons = map[uuid.UUID]string{
 	notifications.TemplateWorkspaceManualBuildFailed: codersdk.InboxNotificationFallbackIconWorkspace,
 	notifications.TemplateWorkspaceOutOfMemory:       codersdk.InboxNotificationFallbackIconWorkspace,
 	notifications.TemplateWorkspaceOutOfDisk:         codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceDriftDetected:     codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceOutdatedBuild:     codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceBuildRunningLong:  codersdk.InboxNotificationFallbackIconWorkspace,
 
 	// account related notifications
 	notifications.TemplateUserAccountCreated:           codersdk.InboxNotificationFallbackIconAccount,
@@ -51,11 +54,13 @@ var fallbackIcons = map[uuid.UUID]string{
 	notifications.TemplateYourAccountSuspended:         codersdk.InboxNotificationFallbackIconAccount,
 	notifications.TemplateYourAccountActivated:         codersdk.InboxNotificationFallbackIconAccount,
 	notifications.TemplateUserRequestedOneTimePasscode: codersdk.InboxNotificationFallbackIconAccount,
+	notifications.TemplateAccessRequestCreated:         codersdk.InboxNotificationFallbackIconAccount,
 
 	// template related notifications
-	notifications.TemplateTemplateDeleted:             codersdk.InboxNotificationFallbackIconTemplate,
-	notifications.TemplateTemplateDeprecated:          codersdk.InboxNotificationFallbackIconTemplate,
-	notifications.TemplateWorkspaceBuildsFailedReport: codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateTemplateDeleted:              codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateTemplateDeprecated:           codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateWorkspaceBuildsFailedReport:  codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateWorkspaceBuildAlertTriggered: codersdk.InboxNotificationFallbackIconTemplate,
 }
 
 func ensureNotificationIcon(notif codersdk.InboxNotification) codersdk.InboxNotification {
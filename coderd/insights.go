@@ -3,7 +3,10 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
 	"net/http"
 	"slices"
 	"strings"
@@ -533,6 +536,373 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Get insights about workspace costs
+// @ID get-insights-about-workspace-costs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param group_by query string false "Group by" Enums(workspace,user,organization)
+// @Success 200 {object} codersdk.CostInsightsResponse
+// @Router /insights/costs [get]
+func (api *API) insightsCosts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		groupBy         = codersdk.CostInsightsGroupBy(p.String(vals, string(codersdk.CostInsightsGroupByWorkspace), "group_by"))
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	groupBys := []codersdk.CostInsightsGroupBy{codersdk.CostInsightsGroupByWorkspace, codersdk.CostInsightsGroupByUser, codersdk.CostInsightsGroupByOrganization}
+	if !slices.Contains(groupBys, groupBy) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "group_by",
+					Detail: fmt.Sprintf("must be one of %v", groupBys),
+				},
+			},
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetWorkspaceDailyCosts(ctx, database.GetWorkspaceDailyCostsParams{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+	})
+	if err != nil {
+		// Check authorization.
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace costs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	report := codersdk.CostInsightsReport{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: []uuid.UUID{},
+		GroupBy:     groupBy,
+		Groups:      []codersdk.CostInsightsGroup{},
+	}
+	groupIndex := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		if !slices.Contains(report.TemplateIDs, row.TemplateID) {
+			report.TemplateIDs = append(report.TemplateIDs, row.TemplateID)
+		}
+
+		id, name := row.WorkspaceID, row.OwnerUsername+"/"+row.WorkspaceName
+		switch groupBy {
+		case codersdk.CostInsightsGroupByUser:
+			id, name = row.OwnerID, row.OwnerUsername
+		case codersdk.CostInsightsGroupByOrganization:
+			id, name = row.OrganizationID, row.OrganizationName
+		}
+		i, ok := groupIndex[id]
+		if !ok {
+			i = len(report.Groups)
+			groupIndex[id] = i
+			report.Groups = append(report.Groups, codersdk.CostInsightsGroup{
+				ID:   id,
+				Name: name,
+				Days: []codersdk.DailyCost{},
+			})
+		}
+
+		// Rows are ordered by day, so the costs of a group in a day are
+		// always adjacent.
+		group := &report.Groups[i]
+		group.Cost += row.Cost
+		if n := len(group.Days); n > 0 && group.Days[n-1].StartTime.Equal(row.StartTime) {
+			group.Days[n-1].Cost += row.Cost
+		} else {
+			group.Days = append(group.Days, codersdk.DailyCost{
+				StartTime: row.StartTime,
+				EndTime:   row.EndTime,
+				Cost:      row.Cost,
+			})
+		}
+		report.TotalCost += row.Cost
+	}
+
+	// Most expensive first.
+	slices.SortFunc(report.Groups, func(a, b codersdk.CostInsightsGroup) int {
+		if a.Cost != b.Cost {
+			return slice.Descending(a.Cost, b.Cost)
+		}
+		return slice.Ascending(a.Name, b.Name)
+	})
+	slices.SortFunc(report.TemplateIDs, func(a, b uuid.UUID) int {
+		return slice.Ascending(a.String(), b.String())
+	})
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.CostInsightsResponse{Report: report})
+}
+
+// @Summary Get insights about provisioner utilization
+// @ID get-insights-about-provisioner-utilization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param interval query string false "Interval" Enums(day,week)
+// @Success 200 {object} codersdk.ProvisionerInsightsResponse
+// @Router /insights/provisioners [get]
+func (api *API) insightsProvisioners(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		intervalString  = p.String(vals, string(codersdk.InsightsReportIntervalDay), "interval")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	now := time.Now()
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, now, startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	interval, ok := parseInsightsInterval(ctx, rw, intervalString, startTime, endTime)
+	if !ok {
+		return
+	}
+	if interval == "" {
+		interval = codersdk.InsightsReportIntervalDay
+	}
+
+	jobs, err := api.Database.GetProvisionerJobsRunningBetween(ctx, database.GetProvisionerJobsRunningBetweenParams{
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		// Check authorization.
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	daemons, err := api.Database.GetProvisionerDaemons(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner daemons.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	utilization := newProvisionerUtilization(jobs, daemons, startTime, endTime, now)
+	resp := codersdk.ProvisionerInsightsResponse{
+		Report: codersdk.ProvisionerInsightsReport{
+			StartTime:     startTime,
+			EndTime:       endTime,
+			BusySeconds:   int64(utilization.busy.Seconds()),
+			IdleSeconds:   int64(utilization.idle.Seconds()),
+			Utilization:   utilization.ratio(),
+			CompletedJobs: utilization.completedJobs,
+			JobsPerHour:   utilization.jobsPerHour(),
+			QueueWaits:    provisionerQueueWaits(jobs, startTime, endTime),
+		},
+		IntervalReports: []codersdk.ProvisionerInsightsIntervalReport{},
+	}
+	for intervalStart := startTime; intervalStart.Before(endTime); intervalStart = intervalStart.AddDate(0, 0, int(interval.Days())) {
+		intervalEnd := intervalStart.AddDate(0, 0, int(interval.Days()))
+		if intervalEnd.After(endTime) {
+			intervalEnd = endTime
+		}
+		utilization := newProvisionerUtilization(jobs, daemons, intervalStart, intervalEnd, now)
+		resp.IntervalReports = append(resp.IntervalReports, codersdk.ProvisionerInsightsIntervalReport{
+			StartTime:     intervalStart,
+			EndTime:       intervalEnd,
+			Interval:      interval,
+			BusySeconds:   int64(utilization.busy.Seconds()),
+			IdleSeconds:   int64(utilization.idle.Seconds()),
+			Utilization:   utilization.ratio(),
+			CompletedJobs: utilization.completedJobs,
+			JobsPerHour:   utilization.jobsPerHour(),
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// provisionerUtilization is the time provisioner daemons spent busy and idle
+// in a period of time, along with the jobs they completed in it.
+type provisionerUtilization struct {
+	busy          time.Duration
+	idle          time.Duration
+	elapsed       time.Duration
+	completedJobs int64
+}
+
+// newProvisionerUtilization calculates the utilization of the provisioner
+// daemons between the start and end times. Jobs that are still running are
+// busy until now. Daemons are assumed to have been available from when they
+// were first registered until they were last seen.
+func newProvisionerUtilization(jobs []database.GetProvisionerJobsRunningBetweenRow, daemons []database.ProvisionerDaemon, startTime, endTime, now time.Time) provisionerUtilization {
+	u := provisionerUtilization{
+		elapsed: overlapDuration(startTime, now, startTime, endTime),
+	}
+	var available time.Duration
+	for _, daemon := range daemons {
+		if daemon.LastSeenAt.Valid {
+			available += overlapDuration(daemon.CreatedAt, daemon.LastSeenAt.Time, startTime, endTime)
+		}
+	}
+	for _, job := range jobs {
+		completedAt := now
+		if job.CompletedAt.Valid {
+			completedAt = job.CompletedAt.Time
+			if !completedAt.Before(startTime) && completedAt.Before(endTime) {
+				u.completedJobs++
+			}
+		}
+		u.busy += overlapDuration(job.StartedAt.Time, completedAt, startTime, endTime)
+	}
+	// Daemons that were deleted are not accounted for, so they may have run
+	// jobs for longer than the remaining daemons were available.
+	u.idle = max(available-u.busy, 0)
+	return u
+}
+
+// ratio returns the fraction of the provisioner time that was spent busy.
+func (u provisionerUtilization) ratio() float64 {
+	total := u.busy + u.idle
+	if total <= 0 {
+		return 0
+	}
+	return u.busy.Seconds() / total.Seconds()
+}
+
+func (u provisionerUtilization) jobsPerHour() float64 {
+	if u.elapsed <= 0 {
+		return 0
+	}
+	return float64(u.completedJobs) / u.elapsed.Hours()
+}
+
+// overlapDuration returns how long the a-b range overlaps the start-end range.
+func overlapDuration(a, b, startTime, endTime time.Time) time.Duration {
+	if a.Before(startTime) {
+		a = startTime
+	}
+	if b.After(endTime) {
+		b = endTime
+	}
+	return max(b.Sub(a), 0)
+}
+
+// provisionerQueueWaits returns the queue wait percentiles of the jobs that
+// started between the start and end times, by organization and tag set.
+func provisionerQueueWaits(jobs []database.GetProvisionerJobsRunningBetweenRow, startTime, endTime time.Time) []codersdk.ProvisionerQueueWait {
+	type tagSet struct {
+		organizationID uuid.UUID
+		tags           string
+	}
+	waits := make(map[tagSet][]float64)
+	tagsBySet := make(map[tagSet]database.StringMap)
+	for _, job := range jobs {
+		if job.StartedAt.Time.Before(startTime) || !job.StartedAt.Time.Before(endTime) {
+			continue
+		}
+		// Map keys are marshaled in sorted order, so equal tag sets have
+		// equal keys.
+		tags, err := json.Marshal(job.Tags)
+		if err != nil {
+			continue
+		}
+		key := tagSet{organizationID: job.OrganizationID, tags: string(tags)}
+		waits[key] = append(waits[key], job.StartedAt.Time.Sub(job.CreatedAt).Seconds())
+		tagsBySet[key] = job.Tags
+	}
+
+	keys := slices.Collect(maps.Keys(waits))
+	slices.SortFunc(keys, func(a, b tagSet) int {
+		if a.organizationID != b.organizationID {
+			return slice.Ascending(a.organizationID.String(), b.organizationID.String())
+		}
+		return slice.Ascending(a.tags, b.tags)
+	})
+	queueWaits := make([]codersdk.ProvisionerQueueWait, 0, len(keys))
+	for _, key := range keys {
+		values := waits[key]
+		slices.Sort(values)
+		queueWaits = append(queueWaits, codersdk.ProvisionerQueueWait{
+			OrganizationID: key.organizationID,
+			Tags:           tagsBySet[key],
+			Jobs:           int64(len(values)),
+			P50Seconds:     sortedPercentile(values, 0.5),
+			P95Seconds:     sortedPercentile(values, 0.95),
+			P99Seconds:     sortedPercentile(values, 0.99),
+		})
+	}
+	slices.SortStableFunc(queueWaits, func(a, b codersdk.ProvisionerQueueWait) int {
+		if a.P95Seconds != b.P95Seconds {
+			return slice.Descending(a.P95Seconds, b.P95Seconds)
+		}
+		return slice.Descending(a.Jobs, b.Jobs)
+	})
+	return queueWaits
+}
+
+// sortedPercentile returns the nearest-rank percentile of the sorted values.
+func sortedPercentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(values)))) - 1
+	return values[max(rank, 0)]
+}
+
 // convertTemplateInsightsApps builds the list of builtin apps and template apps
 // from the provided database rows, builtin apps are implicitly a part of all
 // templates.
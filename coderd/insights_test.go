@@ -1,7 +1,10 @@
 package coderd_test
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +17,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -25,6 +29,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbrollup"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
@@ -37,6 +42,7 @@ import (
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
 )
 
 func TestDeploymentInsights(t *testing.T) {
@@ -381,6 +387,276 @@ func TestUserActivityInsights_BadRequest(t *testing.T) {
 	assert.Error(t, err, "want error for end time before start time")
 }
 
+func TestCostInsights(t *testing.T) {
+	t.Parallel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{Database: db, Pubsub: ps})
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	tpl := dbgen.Template(t, db, database.Template{OrganizationID: owner.OrganizationID, CreatedBy: owner.UserID})
+	ver := dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: owner.OrganizationID, TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}, CreatedBy: owner.UserID})
+
+	// Each workspace runs for the whole of yesterday.
+	createWorkspace := func(ownerID uuid.UUID, dailyCost int32) database.WorkspaceTable {
+		ws := dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: owner.OrganizationID, TemplateID: tpl.ID, OwnerID: ownerID})
+		job := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			OrganizationID: owner.OrganizationID,
+			StartedAt:      sql.NullTime{Time: yesterday.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: yesterday, Valid: true},
+		})
+		_ = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: job.ID, DailyCost: dailyCost})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       ws.ID,
+			JobID:             job.ID,
+			TemplateVersionID: ver.ID,
+			Transition:        database.WorkspaceTransitionStart,
+		})
+		return ws
+	}
+	first := createWorkspace(owner.UserID, 10)
+	_ = createWorkspace(owner.UserID, 5)
+	_ = createWorkspace(member.ID, 20)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	err := db.UpsertWorkspaceDailyCosts(ctx)
+	require.NoError(t, err)
+
+	res, err := client.CostInsights(ctx, codersdk.CostInsightsRequest{
+		StartTime: yesterday,
+		EndTime:   today,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.CostInsightsGroupByWorkspace, res.Report.GroupBy)
+	require.Equal(t, []uuid.UUID{tpl.ID}, res.Report.TemplateIDs)
+	require.InDelta(t, 35, res.Report.TotalCost, 0.001)
+	require.Len(t, res.Report.Groups, 3)
+	require.Equal(t, first.ID, res.Report.Groups[1].ID)
+	require.Len(t, res.Report.Groups[1].Days, 1)
+	require.True(t, yesterday.Equal(res.Report.Groups[1].Days[0].StartTime))
+	require.InDelta(t, 10, res.Report.Groups[1].Days[0].Cost, 0.001)
+
+	res, err = client.CostInsights(ctx, codersdk.CostInsightsRequest{
+		StartTime: yesterday,
+		EndTime:   today,
+		GroupBy:   codersdk.CostInsightsGroupByUser,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Report.Groups, 2)
+	require.Equal(t, member.ID, res.Report.Groups[0].ID)
+	require.InDelta(t, 20, res.Report.Groups[0].Cost, 0.001)
+	require.Equal(t, owner.UserID, res.Report.Groups[1].ID)
+	require.InDelta(t, 15, res.Report.Groups[1].Cost, 0.001)
+	require.Len(t, res.Report.Groups[1].Days, 1)
+
+	res, err = client.CostInsights(ctx, codersdk.CostInsightsRequest{
+		StartTime: yesterday,
+		EndTime:   today,
+		GroupBy:   codersdk.CostInsightsGroupByOrganization,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Report.Groups, 1)
+	require.Equal(t, owner.OrganizationID, res.Report.Groups[0].ID)
+	require.InDelta(t, 35, res.Report.Groups[0].Cost, 0.001)
+
+	_, err = client.CostInsights(ctx, codersdk.CostInsightsRequest{
+		StartTime: yesterday,
+		EndTime:   today,
+		GroupBy:   "template",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
+func TestProvisionerInsights(t *testing.T) {
+	t.Parallel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{Database: db, Pubsub: ps})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// A single daemon is available for the whole of yesterday.
+	_ = dbgen.ProvisionerDaemon(t, db, database.ProvisionerDaemon{
+		OrganizationID: owner.OrganizationID,
+		CreatedAt:      yesterday.Add(-time.Hour),
+		LastSeenAt:     sql.NullTime{Time: today.Add(time.Minute), Valid: true},
+	})
+	createJob := func(createdAt time.Time, wait, run time.Duration) database.ProvisionerJob {
+		return dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: owner.OrganizationID,
+			CreatedAt:      createdAt,
+			StartedAt:      sql.NullTime{Time: createdAt.Add(wait), Valid: true},
+			CompletedAt:    sql.NullTime{Time: createdAt.Add(wait + run), Valid: true},
+		})
+	}
+	_ = createJob(yesterday.Add(time.Hour), 10*time.Second, 2*time.Hour)
+	slowJob := createJob(yesterday.Add(6*time.Hour), 30*time.Second, 4*time.Hour)
+	// Jobs outside the report are not counted.
+	_ = createJob(yesterday.Add(-3*time.Hour), 0, time.Hour)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	res, err := client.ProvisionerInsights(ctx, codersdk.ProvisionerInsightsRequest{
+		StartTime: yesterday,
+		EndTime:   today,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 6*60*60, res.Report.BusySeconds)
+	require.EqualValues(t, 18*60*60, res.Report.IdleSeconds)
+	require.InDelta(t, 0.25, res.Report.Utilization, 0.001)
+	require.EqualValues(t, 2, res.Report.CompletedJobs)
+	require.InDelta(t, 2.0/24, res.Report.JobsPerHour, 0.001)
+	require.Len(t, res.Report.QueueWaits, 2)
+	require.Equal(t, map[string]string(slowJob.Tags), res.Report.QueueWaits[0].Tags)
+	require.EqualValues(t, 1, res.Report.QueueWaits[0].Jobs)
+	require.InDelta(t, 30, res.Report.QueueWaits[0].P95Seconds, 0.001)
+	require.InDelta(t, 10, res.Report.QueueWaits[1].P50Seconds, 0.001)
+	require.Len(t, res.IntervalReports, 1)
+	require.Equal(t, codersdk.InsightsReportIntervalDay, res.IntervalReports[0].Interval)
+	require.True(t, yesterday.Equal(res.IntervalReports[0].StartTime))
+	require.Equal(t, res.Report.BusySeconds, res.IntervalReports[0].BusySeconds)
+
+	// Reports spanning multiple days have a report for each day.
+	res, err = client.ProvisionerInsights(ctx, codersdk.ProvisionerInsightsRequest{
+		StartTime: yesterday.AddDate(0, 0, -1),
+		EndTime:   today,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.IntervalReports, 2)
+	require.EqualValues(t, 60*60, res.IntervalReports[0].BusySeconds)
+	require.EqualValues(t, 1, res.IntervalReports[0].CompletedJobs)
+	require.EqualValues(t, 6*60*60, res.IntervalReports[1].BusySeconds)
+
+	// Provisioner utilization is not visible to regular members.
+	_, err = memberClient.ProvisionerInsights(ctx, codersdk.ProvisionerInsightsRequest{
+		StartTime: yesterday,
+		EndTime:   today,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestUserActivityExports(t *testing.T) {
+	t.Parallel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	clock := quartz.NewMock(t)
+	clock.Set(time.Now())
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{Database: db, Pubsub: ps, Clock: clock})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	_ = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+	}).Do()
+
+	awaitExport := func(ctx context.Context, id uuid.UUID) codersdk.UserActivityExport {
+		var export codersdk.UserActivityExport
+		require.Eventually(t, func() bool {
+			clock.AdvanceNext()
+			var err error
+			export, err = client.UserActivityExport(ctx, id)
+			return err == nil && export.Status != codersdk.UserActivityExportStatusPending
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, codersdk.UserActivityExportStatusCompleted, export.Status, export.Error)
+		require.NotNil(t, export.FileID)
+		require.NotEmpty(t, export.DownloadURL)
+		return export
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		export, err := client.CreateUserActivityExport(ctx, codersdk.CreateUserActivityExportRequest{
+			StartTime: today,
+			EndTime:   today.AddDate(0, 0, 1),
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserActivityExportFormatCSV, export.Format)
+		require.Equal(t, codersdk.UserActivityExportStatusPending, export.Status)
+
+		export = awaitExport(ctx, export.ID)
+		data, mimetype, err := client.Download(ctx, *export.FileID)
+		require.NoError(t, err)
+		require.Equal(t, "text/csv", mimetype)
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"date", "user_id", "username", "connection_hours", "workspace_builds", "apps_used"},
+			{today.Format(time.DateOnly), member.ID.String(), member.Username, "0.00", "1", ""},
+		}, records)
+	})
+
+	t.Run("Parquet", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		export, err := client.CreateUserActivityExport(ctx, codersdk.CreateUserActivityExportRequest{
+			StartTime: today,
+			EndTime:   today.AddDate(0, 0, 1),
+			Format:    codersdk.UserActivityExportFormatParquet,
+		})
+		require.NoError(t, err)
+
+		export = awaitExport(ctx, export.ID)
+		data, _, err := client.Download(ctx, *export.FileID)
+		require.NoError(t, err)
+		type row struct {
+			Date            int32     `parquet:"date,date"`
+			UserID          uuid.UUID `parquet:"user_id,uuid"`
+			Username        string    `parquet:"username"`
+			WorkspaceBuilds int64     `parquet:"workspace_builds"`
+		}
+		rows, err := parquet.Read[row](bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.EqualValues(t, today.Unix()/(24*60*60), rows[0].Date)
+		require.Equal(t, member.ID, rows[0].UserID)
+		require.Equal(t, member.Username, rows[0].Username)
+		require.EqualValues(t, 1, rows[0].WorkspaceBuilds)
+	})
+
+	t.Run("BadRequest", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateUserActivityExport(ctx, codersdk.CreateUserActivityExportRequest{
+			StartTime: today,
+			EndTime:   today.AddDate(-2, 0, 0),
+			Format:    "xlsx",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := memberClient.CreateUserActivityExport(ctx, codersdk.CreateUserActivityExportRequest{
+			StartTime: today,
+			EndTime:   today.AddDate(0, 0, 1),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
 func TestTemplateInsights_Golden(t *testing.T) {
 	t.Parallel()
 
@@ -2260,6 +2536,14 @@ func TestGenericInsights_RBAC(t *testing.T) {
 			})
 			return err
 		},
+		"Costs": func(ctx context.Context, client *codersdk.Client, startTime, endTime time.Time, templateIDs ...uuid.UUID) error {
+			_, err := client.CostInsights(ctx, codersdk.CostInsightsRequest{
+				StartTime:   startTime,
+				EndTime:     endTime,
+				TemplateIDs: templateIDs,
+			})
+			return err
+		},
 	}
 
 	for endpointName, endpoint := range endpoints {
@@ -0,0 +1,219 @@
+// Package joblogarchive moves the logs of old provisioner jobs out of the
+// provisioner_job_logs table into compressed files, and reads them back.
+package joblogarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/quartz"
+)
+
+const (
+	delay = 10 * time.Minute
+	// batchSize is the maximum number of jobs archived per tick.
+	batchSize = 100
+	// Mimetype is the mimetype of archived log files.
+	Mimetype = "application/gzip"
+)
+
+// New creates a new periodically archiving instance. Logs of provisioner
+// jobs that completed more than retention ago are compressed into a file
+// and removed from the provisioner_job_logs table.
+// It is the caller's responsibility to call Close on the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.Clock, retention time.Duration) io.Closer {
+	closed := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system archives old logs without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	// Start the ticker with the initial delay.
+	ticker := clk.NewTicker(delay)
+	doTick := func(start time.Time) {
+		defer ticker.Reset(delay)
+		var archived int
+		// Start a transaction to grab advisory lock, we don't want to run
+		// multiple archivers at the same time (multiple replicas).
+		if err := db.InTx(func(tx database.Store) error {
+			ok, err := tx.TryAcquireLock(ctx, database.LockIDJobLogArchive)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				logger.Debug(ctx, "unable to acquire lock for archiving provisioner job logs, skipping")
+				return nil
+			}
+
+			jobs, err := tx.GetProvisionerJobsToArchiveLogs(ctx, database.GetProvisionerJobsToArchiveLogsParams{
+				CompletedBefore: start.Add(-retention),
+				LimitCount:      batchSize,
+			})
+			if err != nil {
+				return xerrors.Errorf("get provisioner jobs to archive logs: %w", err)
+			}
+			for _, job := range jobs {
+				if err := ArchiveJobLogs(ctx, tx, job.ID, job.InitiatorID, start); err != nil {
+					return xerrors.Errorf("archive logs of job %s: %w", job.ID, err)
+				}
+			}
+			archived = len(jobs)
+
+			return nil
+		}, database.DefaultTXOptions().WithID("job_log_archive")); err != nil {
+			logger.Error(ctx, "failed to archive provisioner job logs", slog.Error(err))
+			return
+		}
+		logger.Debug(ctx, "archived provisioner job logs",
+			slog.F("jobs", archived),
+			slog.F("duration", clk.Since(start)),
+		)
+	}
+
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		// Force an initial tick.
+		doTick(dbtime.Time(clk.Now()).UTC())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick := <-ticker.C:
+				ticker.Stop()
+				doTick(dbtime.Time(tick).UTC())
+			}
+		}
+	}()
+	return &instance{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+type instance struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (i *instance) Close() error {
+	i.cancel()
+	<-i.closed
+	return nil
+}
+
+// ArchiveJobLogs compresses all logs of a job into a file owned by the
+// job initiator and deletes them from the provisioner_job_logs table.
+// Jobs without logs in the table are left untouched.
+func ArchiveJobLogs(ctx context.Context, db database.Store, jobID, initiatorID uuid.UUID, now time.Time) error {
+	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID: jobID,
+	})
+	if err != nil {
+		return xerrors.Errorf("get logs: %w", err)
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(logs); err != nil {
+		return xerrors.Errorf("encode logs: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return xerrors.Errorf("compress logs: %w", err)
+	}
+	data := buf.Bytes()
+	hash := sha256.Sum256(data)
+
+	file, err := db.InsertFile(ctx, database.InsertFileParams{
+		ID:        uuid.New(),
+		Hash:      hex.EncodeToString(hash[:]),
+		CreatedAt: now,
+		CreatedBy: initiatorID,
+		Mimetype:  Mimetype,
+		Data:      data,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert file: %w", err)
+	}
+	_, err = db.InsertProvisionerJobLogArchive(ctx, database.InsertProvisionerJobLogArchiveParams{
+		JobID:      jobID,
+		FileID:     file.ID,
+		LogCount:   int32(len(logs)), // #nosec G115 - A job never has more than math.MaxInt32 logs.
+		ArchivedAt: now,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert archive: %w", err)
+	}
+	if err := db.DeleteProvisionerJobLogsByJobID(ctx, jobID); err != nil {
+		return xerrors.Errorf("delete logs: %w", err)
+	}
+	return nil
+}
+
+// SearchLogs returns the logs of a job matching the search parameters,
+// reading them from the job's archive if they were moved out of the
+// provisioner_job_logs table. The caller must be authorized to read the job.
+func SearchLogs(ctx context.Context, db database.Store, arg database.SearchProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
+	logs, err := db.SearchProvisionerJobLogs(ctx, arg)
+	if err != nil || len(logs) > 0 {
+		return logs, err
+	}
+
+	archive, err := db.GetProvisionerJobLogArchiveByJobID(ctx, arg.JobID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return logs, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get log archive: %w", err)
+	}
+	// The archive file is owned by the job initiator, but anyone who can
+	// read the job can read its logs.
+	//nolint:gocritic // Access to the job was authorized above.
+	file, err := db.GetFileByID(dbauthz.AsSystemRestricted(ctx), archive.FileID)
+	if err != nil {
+		return nil, xerrors.Errorf("get log archive file: %w", err)
+	}
+	archived, err := decode(file.Data)
+	if err != nil {
+		return nil, err
+	}
+	logs = make([]database.ProvisionerJobLog, 0, len(archived))
+	for _, log := range archived {
+		if !arg.Matches(log) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+func decode(data []byte) ([]database.ProvisionerJobLog, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, xerrors.Errorf("decompress logs: %w", err)
+	}
+	defer zr.Close()
+	var logs []database.ProvisionerJobLog
+	if err := json.NewDecoder(zr).Decode(&logs); err != nil {
+		return nil, xerrors.Errorf("decode logs: %w", err)
+	}
+	return logs, nil
+}
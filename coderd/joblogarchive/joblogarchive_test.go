@@ -0,0 +1,154 @@
+package joblogarchive_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/joblogarchive"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+//nolint:paralleltest // It uses LockIDJobLogArchive.
+func TestArchive(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+	db, _ := dbtestutil.NewDB(t)
+	user := dbgen.User(t, db, database.User{})
+
+	oldJob := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		InitiatorID: user.ID,
+		StartedAt:   sql.NullTime{Time: now.Add(-49 * time.Hour), Valid: true},
+		CompletedAt: sql.NullTime{Time: now.Add(-48 * time.Hour), Valid: true},
+	})
+	recentJob := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		InitiatorID: user.ID,
+		StartedAt:   sql.NullTime{Time: now.Add(-2 * time.Hour), Valid: true},
+		CompletedAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+	})
+	oldLogs := insertLogs(ctx, t, db, oldJob.ID, now.Add(-48*time.Hour), "one", "two", "three")
+	recentLogs := insertLogs(ctx, t, db, recentJob.ID, now.Add(-time.Hour), "four")
+
+	done := awaitDoTick(ctx, t, clk)
+	archiver := joblogarchive.New(context.Background(), testutil.Logger(t), db, clk, 24*time.Hour)
+	<-done
+	require.NoError(t, archiver.Close())
+
+	// The old job's logs were moved into an archive.
+	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{JobID: oldJob.ID})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+	archive, err := db.GetProvisionerJobLogArchiveByJobID(ctx, oldJob.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, len(oldLogs), archive.LogCount)
+	file, err := db.GetFileByID(ctx, archive.FileID)
+	require.NoError(t, err)
+	require.Equal(t, joblogarchive.Mimetype, file.Mimetype)
+	require.Equal(t, user.ID, file.CreatedBy)
+
+	// The recent job's logs were kept.
+	logs, err = db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{JobID: recentJob.ID})
+	require.NoError(t, err)
+	require.Len(t, logs, len(recentLogs))
+	_, err = db.GetProvisionerJobLogArchiveByJobID(ctx, recentJob.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Archived logs are read back transparently.
+	logs, err = joblogarchive.SearchLogs(ctx, db, database.SearchProvisionerJobLogsParams{JobID: oldJob.ID})
+	require.NoError(t, err)
+	requireSameLogs(t, oldLogs, logs)
+	logs, err = joblogarchive.SearchLogs(ctx, db, database.SearchProvisionerJobLogsParams{JobID: oldJob.ID, CreatedAfter: oldLogs[0].ID})
+	require.NoError(t, err)
+	requireSameLogs(t, oldLogs[1:], logs)
+	logs, err = joblogarchive.SearchLogs(ctx, db, database.SearchProvisionerJobLogsParams{JobID: recentJob.ID})
+	require.NoError(t, err)
+	requireSameLogs(t, recentLogs, logs)
+
+	// Archived logs are filtered like the logs in the database.
+	logs, err = joblogarchive.SearchLogs(ctx, db, database.SearchProvisionerJobLogsParams{JobID: oldJob.ID, Search: "two"})
+	require.NoError(t, err)
+	requireSameLogs(t, oldLogs[1:2], logs)
+	logs, err = joblogarchive.SearchLogs(ctx, db, database.SearchProvisionerJobLogsParams{
+		JobID:    oldJob.ID,
+		MinLevel: database.NullLogLevel{LogLevel: database.LogLevelWarn, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+}
+
+func insertLogs(ctx context.Context, t *testing.T, db database.Store, jobID uuid.UUID, createdAt time.Time, outputs ...string) []database.ProvisionerJobLog {
+	t.Helper()
+
+	params := database.InsertProvisionerJobLogsParams{JobID: jobID}
+	for _, output := range outputs {
+		params.CreatedAt = append(params.CreatedAt, createdAt)
+		params.Source = append(params.Source, database.LogSourceProvisioner)
+		params.Level = append(params.Level, database.LogLevelInfo)
+		params.Stage = append(params.Stage, "Planning")
+		params.Output = append(params.Output, output)
+		params.Fields = append(params.Fields, "{}")
+	}
+	logs, err := db.InsertProvisionerJobLogs(ctx, params)
+	require.NoError(t, err)
+	return logs
+}
+
+func requireSameLogs(t *testing.T, expected, actual []database.ProvisionerJobLog) {
+	t.Helper()
+
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].ID, actual[i].ID)
+		assert.Equal(t, expected[i].Output, actual[i].Output)
+		assert.Equal(t, expected[i].Stage, actual[i].Stage)
+		assert.True(t, expected[i].CreatedAt.Equal(actual[i].CreatedAt))
+		assert.JSONEq(t, string(expected[i].Fields), string(actual[i].Fields))
+	}
+}
+
+func awaitDoTick(ctx context.Context, t *testing.T, clk *quartz.Mock) chan struct{} {
+	t.Helper()
+	ch := make(chan struct{})
+	trapNow := clk.Trap().Now()
+	trapStop := clk.Trap().TickerStop()
+	trapReset := clk.Trap().TickerReset()
+	go func() {
+		defer close(ch)
+		defer trapReset.Close()
+		defer trapStop.Close()
+		defer trapNow.Close()
+		// Wait for the initial tick signified by a call to Now().
+		trapNow.MustWait(ctx).MustRelease(ctx)
+		// doTick runs here. Wait for the next
+		// ticker reset event that signifies it's completed.
+		trapReset.MustWait(ctx).MustRelease(ctx)
+		// Ensure that the next tick happens in 10 minutes from start.
+		d, w := clk.AdvanceNext()
+		if !assert.Equal(t, 10*time.Minute, d) {
+			return
+		}
+		w.MustWait(ctx)
+		// Wait for the ticker stop event.
+		trapStop.MustWait(ctx).MustRelease(ctx)
+	}()
+
+	return ch
+}
@@ -3,7 +3,6 @@ package jobreaper
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt" //#nosec // this is only used for shuffling an array to pick random jobs to unhang
 	"time"
 
@@ -15,8 +14,13 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	coderdpubsub "github.com/coder/coder/v2/coderd/pubsub"
+	"github.com/coder/coder/v2/coderd/readonly"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/quartz"
 )
 
 const (
@@ -36,6 +40,12 @@ const (
 	// time after failing to send an update to the job.
 	HungJobExitTimeout = 3 * time.Minute
 
+	// CanceledJobDeadline is the default duration of time since a RUNNING
+	// job was canceled before it is forcefully terminated. Provisioners learn
+	// about the cancellation on their next update and are given
+	// HungJobExitTimeout to exit gracefully.
+	CanceledJobDeadline = HungJobExitTimeout + time.Minute
+
 	// MaxJobsPerRun is the maximum number of hung jobs that the detector will
 	// terminate in a single run.
 	MaxJobsPerRun = 10
@@ -43,10 +53,14 @@ const (
 
 // jobLogMessages are written to provisioner job logs when a job is reaped
 func JobLogMessages(reapType ReapType, threshold time.Duration) []string {
+	msg := fmt.Sprintf("Coder: Build has been detected as %s for %.0f minutes and will be terminated.", reapType, threshold.Minutes())
+	if reapType == Manual {
+		msg = "Coder: Build has been manually terminated by an administrator."
+	}
 	return []string{
 		"",
 		"====================",
-		fmt.Sprintf("Coder: Build has been detected as %s for %.0f minutes and will be terminated.", reapType, threshold.Minutes()),
+		msg,
 		"====================",
 		"",
 	}
@@ -61,8 +75,12 @@ type jobToReap struct {
 type ReapType string
 
 const (
-	Pending ReapType = "pending"
-	Hung    ReapType = "hung"
+	Pending   ReapType = "pending"
+	Hung      ReapType = "hung"
+	Canceling ReapType = "canceling"
+	// Manual is used for jobs that an administrator terminated through the
+	// API, regardless of when they were last updated.
+	Manual ReapType = "manual"
 )
 
 // acquireLockError is returned when the detector fails to acquire a lock and
@@ -95,8 +113,12 @@ type Detector struct {
 	db     database.Store
 	pubsub pubsub.Pubsub
 	log    slog.Logger
-	tick   <-chan time.Time
+	clock  quartz.Clock
 	stats  chan<- Stats
+
+	interval       time.Duration
+	cancelDeadline time.Duration
+	readOnlyMode   *readonly.Mode
 }
 
 // Stats contains statistics about the last run of the detector.
@@ -110,8 +132,8 @@ type Stats struct {
 	Error error
 }
 
-// New returns a new job reaper.
-func New(ctx context.Context, db database.Store, pub pubsub.Pubsub, log slog.Logger, tick <-chan time.Time) *Detector {
+// New returns a new job reaper that runs every interval.
+func New(ctx context.Context, db database.Store, pub pubsub.Pubsub, log slog.Logger, interval time.Duration) *Detector {
 	//nolint:gocritic // Job reaper has a limited set of permissions.
 	ctx, cancel := context.WithCancel(dbauthz.AsJobReaper(ctx))
 	d := &Detector{
@@ -121,38 +143,66 @@ func New(ctx context.Context, db database.Store, pub pubsub.Pubsub, log slog.Log
 		db:     db,
 		pubsub: pub,
 		log:    log,
-		tick:   tick,
+		clock:  quartz.NewReal(),
 		stats:  nil,
+
+		interval:       interval,
+		cancelDeadline: CanceledJobDeadline,
 	}
 	return d
 }
 
+// WithCancelDeadline sets how long a running job may remain canceled without
+// the provisioner acknowledging it before the job is forcefully terminated.
+// A zero deadline keeps the default.
+func (d *Detector) WithCancelDeadline(deadline time.Duration) *Detector {
+	if deadline > 0 {
+		d.cancelDeadline = deadline
+	}
+	return d
+}
+
+// WithClock sets the clock the detector runs on, checks the eligibility of jobs
+// and timestamps their termination with. It must be called before Start.
+func (d *Detector) WithClock(clock quartz.Clock) *Detector {
+	d.clock = clock
+	return d
+}
+
+// WithReadOnlyMode pauses the detector while the deployment is in read-only
+// mode. Jobs that become hung in the meantime are reaped once it is turned
+// off.
+func (d *Detector) WithReadOnlyMode(mode *readonly.Mode) *Detector {
+	d.readOnlyMode = mode
+	return d
+}
+
 // WithStatsChannel will cause Executor to push a RunStats to ch after
-// every tick. This push is blocking, so if ch is not read, the detector will
+// every run. This push is blocking, so if ch is not read, the detector will
 // hang. This should only be used in tests.
 func (d *Detector) WithStatsChannel(ch chan<- Stats) *Detector {
 	d.stats = ch
 	return d
 }
 
-// Start will cause the detector to detect and unhang provisioner jobs on every
-// tick from its channel. It will stop when its context is Done, or when its
-// channel is closed.
+// Start will cause the detector to detect and unhang provisioner jobs every
+// interval. It will stop when its context is Done.
 //
 // Start should only be called once.
 func (d *Detector) Start() {
+	// The ticker is created before returning so that tests can advance a mock
+	// clock right after starting the detector.
+	ticker := d.clock.NewTicker(d.interval, "jobreaper")
 	go func() {
 		defer close(d.done)
 		defer d.cancel()
+		defer ticker.Stop()
 
 		for {
 			select {
 			case <-d.ctx.Done():
 				return
-			case t, ok := <-d.tick:
-				if !ok {
-					return
-				}
+			case t := <-ticker.C:
 				stats := d.run(t)
 				if stats.Error != nil && !xerrors.As(stats.Error, &acquireLockError{}) {
 					d.log.Warn(d.ctx, "error running workspace build hang detector once", slog.Error(stats.Error))
@@ -189,11 +239,17 @@ func (d *Detector) run(t time.Time) Stats {
 		Error:            nil,
 	}
 
+	if d.readOnlyMode.Enabled() {
+		d.log.Debug(ctx, "deployment is in read-only mode, skipping job reaper run")
+		return stats
+	}
+
 	// Find all provisioner jobs to be reaped
 	jobs, err := d.db.GetProvisionerJobsToBeReaped(ctx, database.GetProvisionerJobsToBeReapedParams{
-		PendingSince: t.Add(-PendingJobDuration),
-		HungSince:    t.Add(-HungJobDuration),
-		MaxJobs:      MaxJobsPerRun,
+		PendingSince:  t.Add(-PendingJobDuration),
+		HungSince:     t.Add(-HungJobDuration),
+		CanceledSince: t.Add(-d.cancelDeadline),
+		MaxJobs:       MaxJobsPerRun,
 	})
 	if err != nil {
 		stats.Error = xerrors.Errorf("get provisioner jobs to be reaped: %w", err)
@@ -206,12 +262,18 @@ func (d *Detector) run(t time.Time) Stats {
 		j := &jobToReap{
 			ID: job.ID,
 		}
-		if job.JobStatus == database.ProvisionerJobStatusPending {
+		switch {
+		case job.JobStatus == database.ProvisionerJobStatusPending:
 			j.Threshold = PendingJobDuration
 			j.Type = Pending
-		} else {
+		case job.UpdatedAt.Before(t.Add(-HungJobDuration)):
 			j.Threshold = HungJobDuration
 			j.Type = Hung
+		default:
+			// The provisioner is still sending updates, but it has not
+			// acknowledged the cancellation before the deadline.
+			j.Threshold = d.cancelDeadline
+			j.Type = Canceling
 		}
 		jobsToReap = append(jobsToReap, j)
 	}
@@ -221,7 +283,7 @@ func (d *Detector) run(t time.Time) Stats {
 	for _, job := range jobsToReap {
 		log := d.log.With(slog.F("job_id", job.ID))
 
-		err := reapJob(ctx, log, d.db, d.pubsub, job)
+		err := reapJob(ctx, log, d.db, d.pubsub, d.clock, job)
 		if err != nil {
 			if !(xerrors.As(err, &acquireLockError{}) || xerrors.As(err, &jobIneligibleError{})) {
 				log.Error(ctx, "error forcefully terminating provisioner job", slog.F("type", job.Type), slog.Error(err))
@@ -232,11 +294,31 @@ func (d *Detector) run(t time.Time) Stats {
 		stats.TerminatedJobIDs = append(stats.TerminatedJobIDs, job.ID)
 	}
 
+	// Record the successful run, so the deployment health check can report a
+	// stale job reaper.
+	err = d.db.UpsertJobReaperLastRunAt(ctx, t.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		d.log.Warn(ctx, "failed to record job reaper run", slog.Error(err))
+	}
+
 	return stats
 }
 
-func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, jobToReap *jobToReap) error {
-	var lowestLogID int64
+// ReapJob forcefully terminates the given job in the same way the detector
+// terminates hung jobs, without waiting for any threshold to pass. It allows
+// administrators to clean up stuck jobs that the detector does not pick up.
+func ReapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, clock quartz.Clock, jobID uuid.UUID) error {
+	return reapJob(ctx, log, db, pub, clock, &jobToReap{
+		ID:   jobID,
+		Type: Manual,
+	})
+}
+
+func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, clock quartz.Clock, jobToReap *jobToReap) error {
+	var (
+		lowestLogID int64
+		reapedJob   database.ProvisionerJob
+	)
 
 	err := db.InTx(func(db database.Store) error {
 		// Refetch the job while we hold the lock.
@@ -253,9 +335,20 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 				Err: xerrors.Errorf("job is completed (status %s)", job.JobStatus),
 			}
 		}
-		if job.UpdatedAt.After(time.Now().Add(-jobToReap.Threshold)) {
-			return jobIneligibleError{
-				Err: xerrors.New("job has been updated recently"),
+		switch jobToReap.Type {
+		case Manual:
+			// Administrators may terminate a job at any time.
+		case Canceling:
+			if !job.CanceledAt.Valid || job.CanceledAt.Time.After(clock.Now().Add(-jobToReap.Threshold)) {
+				return jobIneligibleError{
+					Err: xerrors.New("job has been canceled recently"),
+				}
+			}
+		default:
+			if job.UpdatedAt.After(clock.Now().Add(-jobToReap.Threshold)) {
+				return jobIneligibleError{
+					Err: xerrors.New("job has been updated recently"),
+				}
 			}
 		}
 
@@ -290,8 +383,9 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 			Level:     nil,
 			Stage:     nil,
 			Output:    nil,
+			Fields:    nil,
 		}
-		now := dbtime.Now()
+		now := dbtime.Time(clock.Now())
 		for i, msg := range JobLogMessages(jobToReap.Type, jobToReap.Threshold) {
 			// Set the created at in a way that ensures each message has
 			// a unique timestamp so they will be sorted correctly.
@@ -300,6 +394,7 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 			insertParams.Stage = append(insertParams.Stage, logStage)
 			insertParams.Source = append(insertParams.Source, database.LogSourceProvisionerDaemon)
 			insertParams.Output = append(insertParams.Output, msg)
+			insertParams.Fields = append(insertParams.Fields, "{}")
 		}
 		newLogs, err := db.InsertProvisionerJobLogs(ctx, insertParams)
 		if err != nil {
@@ -308,7 +403,11 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 		lowestLogID = newLogs[0].ID
 
 		// Mark the job as failed.
-		now = dbtime.Now()
+		now = dbtime.Time(clock.Now())
+		jobError := fmt.Sprintf("Coder: Build has been detected as %s for %.0f minutes and has been terminated by the reaper.", jobToReap.Type, jobToReap.Threshold.Minutes())
+		if jobToReap.Type == Manual {
+			jobError = "Coder: Build has been manually terminated by an administrator."
+		}
 
 		// If the job was never started (pending), set the StartedAt time to the current
 		// time so that the build duration is correct.
@@ -326,17 +425,19 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 				Valid: true,
 			},
 			Error: sql.NullString{
-				String: fmt.Sprintf("Coder: Build has been detected as %s for %.0f minutes and has been terminated by the reaper.", jobToReap.Type, jobToReap.Threshold.Minutes()),
+				String: jobError,
 				Valid:  true,
 			},
 			ErrorCode: sql.NullString{
-				Valid: false,
+				String: string(codersdk.JobReaped),
+				Valid:  true,
 			},
 			StartedAt: job.StartedAt,
 		})
 		if err != nil {
 			return xerrors.Errorf("mark job as failed: %w", err)
 		}
+		reapedJob = job
 
 		// If the provisioner job is a workspace build, copy the
 		// provisioner state from the previous build to this workspace
@@ -361,7 +462,7 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 				if err == nil {
 					err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
 						ID:               build.ID,
-						UpdatedAt:        dbtime.Now(),
+						UpdatedAt:        dbtime.Time(clock.Now()),
 						ProvisionerState: prevBuild.ProvisionerState,
 					})
 					if err != nil {
@@ -379,7 +480,7 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 
 	// Publish the new log notification to pubsub. Use the lowest log ID
 	// inserted so the log stream will fetch everything after that point.
-	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{
+	data, err := coderdpubsub.ProvisionerJobLogsNotify.Encode(provisionersdk.ProvisionerJobLogsNotifyMessage{
 		CreatedAfter: lowestLogID - 1,
 		EndOfLogs:    true,
 	})
@@ -390,6 +491,10 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 	if err != nil {
 		return xerrors.Errorf("publish log notification: %w", err)
 	}
+	err = provisionerjobs.PublishJobStateChange(pub, reapedJob)
+	if err != nil {
+		return xerrors.Errorf("publish job state change: %w", err)
+	}
 
 	return nil
 }
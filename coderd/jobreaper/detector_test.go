@@ -22,7 +22,10 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/jobreaper"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	coderdpubsub "github.com/coder/coder/v2/coderd/pubsub"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/readonly"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -31,6 +34,10 @@ func TestMain(m *testing.M) {
 	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
 }
 
+// testInterval is how often the detector runs in these tests. Its first run is
+// one interval after it starts, when the test advances the mock clock.
+const testInterval = time.Minute
+
 func TestDetectorNoJobs(t *testing.T) {
 	t.Parallel()
 
@@ -38,18 +45,24 @@ func TestDetectorNoJobs(t *testing.T) {
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
+		clock      = dbtestutil.NewClock(t)
 		statsCh    = make(chan jobreaper.Stats)
 	)
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- time.Now()
+	clock.Advance(testInterval).MustWait(ctx)
+	now := clock.Now()
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
 	require.Empty(t, stats.TerminatedJobIDs)
 
+	// Check that the run was recorded for the deployment health check.
+	lastRunAt, err := db.GetJobReaperLastRunAt(ctx)
+	require.NoError(t, err)
+	require.Equal(t, now.UTC().Format(time.RFC3339Nano), lastRunAt)
+
 	detector.Close()
 	detector.Wait()
 }
@@ -61,13 +74,13 @@ func TestDetectorNoHungJobs(t *testing.T) {
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
+		clock      = dbtestutil.NewClock(t)
 		statsCh    = make(chan jobreaper.Stats)
 	)
 
 	// Insert some jobs that are running and haven't been updated in a while,
 	// but not enough to be considered hung.
-	now := time.Now()
+	now := clock.Now().Add(testInterval)
 	org := dbgen.Organization(t, db, database.Organization{})
 	user := dbgen.User(t, db, database.User{})
 	file := dbgen.File(t, db, database.File{})
@@ -89,9 +102,9 @@ func TestDetectorNoHungJobs(t *testing.T) {
 		})
 	}
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
@@ -108,96 +121,26 @@ func TestDetectorHungWorkspaceBuild(t *testing.T) {
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
 		statsCh    = make(chan jobreaper.Stats)
+		clock      = dbtestutil.NewClock(t)
 	)
 
 	var (
-		now          = time.Now()
-		twentyMinAgo = now.Add(-time.Minute * 20)
-		tenMinAgo    = now.Add(-time.Minute * 10)
-		sixMinAgo    = now.Add(-time.Minute * 6)
-		org          = dbgen.Organization(t, db, database.Organization{})
-		user         = dbgen.User(t, db, database.User{})
-		file         = dbgen.File(t, db, database.File{})
-		template     = dbgen.Template(t, db, database.Template{
-			OrganizationID: org.ID,
-			CreatedBy:      user.ID,
-		})
-		templateVersion = dbgen.TemplateVersion(t, db, database.TemplateVersion{
-			OrganizationID: org.ID,
-			TemplateID: uuid.NullUUID{
-				UUID:  template.ID,
-				Valid: true,
-			},
-			CreatedBy: user.ID,
-		})
-		workspace = dbgen.Workspace(t, db, database.WorkspaceTable{
-			OwnerID:        user.ID,
-			OrganizationID: org.ID,
-			TemplateID:     template.ID,
-		})
-
-		// Previous build.
+		now                         = clock.Now().Add(testInterval)
 		expectedWorkspaceBuildState = []byte(`{"dean":"cool","colin":"also cool"}`)
-		previousWorkspaceBuildJob   = dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
-			CreatedAt: twentyMinAgo,
-			UpdatedAt: twentyMinAgo,
-			StartedAt: sql.NullTime{
-				Time:  twentyMinAgo,
-				Valid: true,
-			},
-			CompletedAt: sql.NullTime{
-				Time:  twentyMinAgo,
-				Valid: true,
-			},
-			OrganizationID: org.ID,
-			InitiatorID:    user.ID,
-			Provisioner:    database.ProvisionerTypeEcho,
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         file.ID,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			Input:          []byte("{}"),
-		})
-		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
-			WorkspaceID:       workspace.ID,
-			TemplateVersionID: templateVersion.ID,
-			BuildNumber:       1,
-			ProvisionerState:  expectedWorkspaceBuildState,
-			JobID:             previousWorkspaceBuildJob.ID,
-		})
-
-		// Current build.
-		currentWorkspaceBuildJob = dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
-			CreatedAt: tenMinAgo,
-			UpdatedAt: sixMinAgo,
-			StartedAt: sql.NullTime{
-				Time:  tenMinAgo,
-				Valid: true,
-			},
-			OrganizationID: org.ID,
-			InitiatorID:    user.ID,
-			Provisioner:    database.ProvisionerTypeEcho,
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         file.ID,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			Input:          []byte("{}"),
-		})
-		currentWorkspaceBuild = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
-			WorkspaceID:       workspace.ID,
-			TemplateVersionID: templateVersion.ID,
-			BuildNumber:       2,
-			JobID:             currentWorkspaceBuildJob.ID,
-			// No provisioner state.
+		chain                       = dbgen.HungWorkspaceBuild(t, db, pubsub, dbgen.HungWorkspaceBuildSeed{
+			PreviousBuildState: expectedWorkspaceBuildState,
 		})
+		currentWorkspaceBuildJob = chain.Job
+		currentWorkspaceBuild    = chain.Build
 	)
 
-	t.Log("previous job ID: ", previousWorkspaceBuildJob.ID)
+	t.Log("previous job ID: ", chain.PreviousBuild.JobID)
 	t.Log("current job ID: ", currentWorkspaceBuildJob.ID)
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
@@ -207,12 +150,12 @@ func TestDetectorHungWorkspaceBuild(t *testing.T) {
 	// Check that the current provisioner job was updated.
 	job, err := db.GetProvisionerJobByID(ctx, currentWorkspaceBuildJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as hung")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
 
 	// Check that the provisioner state was copied.
 	build, err := db.GetWorkspaceBuildByID(ctx, currentWorkspaceBuild.ID)
@@ -230,97 +173,28 @@ func TestDetectorHungWorkspaceBuildNoOverrideState(t *testing.T) {
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
 		statsCh    = make(chan jobreaper.Stats)
+		clock      = dbtestutil.NewClock(t)
 	)
 
 	var (
-		now          = time.Now()
-		twentyMinAgo = now.Add(-time.Minute * 20)
-		tenMinAgo    = now.Add(-time.Minute * 10)
-		sixMinAgo    = now.Add(-time.Minute * 6)
-		org          = dbgen.Organization(t, db, database.Organization{})
-		user         = dbgen.User(t, db, database.User{})
-		file         = dbgen.File(t, db, database.File{})
-		template     = dbgen.Template(t, db, database.Template{
-			OrganizationID: org.ID,
-			CreatedBy:      user.ID,
-		})
-		templateVersion = dbgen.TemplateVersion(t, db, database.TemplateVersion{
-			OrganizationID: org.ID,
-			TemplateID: uuid.NullUUID{
-				UUID:  template.ID,
-				Valid: true,
-			},
-			CreatedBy: user.ID,
-		})
-		workspace = dbgen.Workspace(t, db, database.WorkspaceTable{
-			OwnerID:        user.ID,
-			OrganizationID: org.ID,
-			TemplateID:     template.ID,
-		})
-
-		// Previous build.
-		previousWorkspaceBuildJob = dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
-			CreatedAt: twentyMinAgo,
-			UpdatedAt: twentyMinAgo,
-			StartedAt: sql.NullTime{
-				Time:  twentyMinAgo,
-				Valid: true,
-			},
-			CompletedAt: sql.NullTime{
-				Time:  twentyMinAgo,
-				Valid: true,
-			},
-			OrganizationID: org.ID,
-			InitiatorID:    user.ID,
-			Provisioner:    database.ProvisionerTypeEcho,
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         file.ID,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			Input:          []byte("{}"),
-		})
-		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
-			WorkspaceID:       workspace.ID,
-			TemplateVersionID: templateVersion.ID,
-			BuildNumber:       1,
-			ProvisionerState:  []byte(`{"dean":"NOT cool","colin":"also NOT cool"}`),
-			JobID:             previousWorkspaceBuildJob.ID,
-		})
-
-		// Current build.
+		now                         = clock.Now().Add(testInterval)
 		expectedWorkspaceBuildState = []byte(`{"dean":"cool","colin":"also cool"}`)
-		currentWorkspaceBuildJob    = dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
-			CreatedAt: tenMinAgo,
-			UpdatedAt: sixMinAgo,
-			StartedAt: sql.NullTime{
-				Time:  tenMinAgo,
-				Valid: true,
-			},
-			OrganizationID: org.ID,
-			InitiatorID:    user.ID,
-			Provisioner:    database.ProvisionerTypeEcho,
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         file.ID,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			Input:          []byte("{}"),
-		})
-		currentWorkspaceBuild = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
-			WorkspaceID:       workspace.ID,
-			TemplateVersionID: templateVersion.ID,
-			BuildNumber:       2,
-			JobID:             currentWorkspaceBuildJob.ID,
+		chain                       = dbgen.HungWorkspaceBuild(t, db, pubsub, dbgen.HungWorkspaceBuildSeed{
+			PreviousBuildState: []byte(`{"dean":"NOT cool","colin":"also NOT cool"}`),
 			// Should not be overridden.
 			ProvisionerState: expectedWorkspaceBuildState,
 		})
+		currentWorkspaceBuildJob = chain.Job
+		currentWorkspaceBuild    = chain.Build
 	)
 
-	t.Log("previous job ID: ", previousWorkspaceBuildJob.ID)
+	t.Log("previous job ID: ", chain.PreviousBuild.JobID)
 	t.Log("current job ID: ", currentWorkspaceBuildJob.ID)
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
@@ -330,12 +204,12 @@ func TestDetectorHungWorkspaceBuildNoOverrideState(t *testing.T) {
 	// Check that the current provisioner job was updated.
 	job, err := db.GetProvisionerJobByID(ctx, currentWorkspaceBuildJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as hung")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
 
 	// Check that the provisioner state was NOT copied.
 	build, err := db.GetWorkspaceBuildByID(ctx, currentWorkspaceBuild.ID)
@@ -353,67 +227,26 @@ func TestDetectorHungWorkspaceBuildNoOverrideStateIfNoExistingBuild(t *testing.T
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
 		statsCh    = make(chan jobreaper.Stats)
+		clock      = dbtestutil.NewClock(t)
 	)
 
 	var (
-		now       = time.Now()
-		tenMinAgo = now.Add(-time.Minute * 10)
-		sixMinAgo = now.Add(-time.Minute * 6)
-		org       = dbgen.Organization(t, db, database.Organization{})
-		user      = dbgen.User(t, db, database.User{})
-		file      = dbgen.File(t, db, database.File{})
-		template  = dbgen.Template(t, db, database.Template{
-			OrganizationID: org.ID,
-			CreatedBy:      user.ID,
-		})
-		templateVersion = dbgen.TemplateVersion(t, db, database.TemplateVersion{
-			OrganizationID: org.ID,
-			TemplateID: uuid.NullUUID{
-				UUID:  template.ID,
-				Valid: true,
-			},
-			CreatedBy: user.ID,
-		})
-		workspace = dbgen.Workspace(t, db, database.WorkspaceTable{
-			OwnerID:        user.ID,
-			OrganizationID: org.ID,
-			TemplateID:     template.ID,
-		})
-
-		// First build.
+		now                         = clock.Now().Add(testInterval)
 		expectedWorkspaceBuildState = []byte(`{"dean":"cool","colin":"also cool"}`)
-		currentWorkspaceBuildJob    = dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
-			CreatedAt: tenMinAgo,
-			UpdatedAt: sixMinAgo,
-			StartedAt: sql.NullTime{
-				Time:  tenMinAgo,
-				Valid: true,
-			},
-			OrganizationID: org.ID,
-			InitiatorID:    user.ID,
-			Provisioner:    database.ProvisionerTypeEcho,
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         file.ID,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			Input:          []byte("{}"),
-		})
-		currentWorkspaceBuild = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
-			WorkspaceID:       workspace.ID,
-			TemplateVersionID: templateVersion.ID,
-			BuildNumber:       1,
-			JobID:             currentWorkspaceBuildJob.ID,
+		chain                       = dbgen.HungWorkspaceBuild(t, db, pubsub, dbgen.HungWorkspaceBuildSeed{
 			// Should not be overridden.
 			ProvisionerState: expectedWorkspaceBuildState,
 		})
+		currentWorkspaceBuildJob = chain.Job
+		currentWorkspaceBuild    = chain.Build
 	)
 
 	t.Log("current job ID: ", currentWorkspaceBuildJob.ID)
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
@@ -423,12 +256,12 @@ func TestDetectorHungWorkspaceBuildNoOverrideStateIfNoExistingBuild(t *testing.T
 	// Check that the current provisioner job was updated.
 	job, err := db.GetProvisionerJobByID(ctx, currentWorkspaceBuildJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as hung")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
 
 	// Check that the provisioner state was NOT updated.
 	build, err := db.GetWorkspaceBuildByID(ctx, currentWorkspaceBuild.ID)
@@ -446,66 +279,31 @@ func TestDetectorPendingWorkspaceBuildNoOverrideStateIfNoExistingBuild(t *testin
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
 		statsCh    = make(chan jobreaper.Stats)
+		clock      = dbtestutil.NewClock(t)
 	)
 
 	var (
-		now              = time.Now()
-		thirtyFiveMinAgo = now.Add(-time.Minute * 35)
-		org              = dbgen.Organization(t, db, database.Organization{})
-		user             = dbgen.User(t, db, database.User{})
-		file             = dbgen.File(t, db, database.File{})
-		template         = dbgen.Template(t, db, database.Template{
-			OrganizationID: org.ID,
-			CreatedBy:      user.ID,
-		})
-		templateVersion = dbgen.TemplateVersion(t, db, database.TemplateVersion{
-			OrganizationID: org.ID,
-			TemplateID: uuid.NullUUID{
-				UUID:  template.ID,
-				Valid: true,
-			},
-			CreatedBy: user.ID,
-		})
-		workspace = dbgen.Workspace(t, db, database.WorkspaceTable{
-			OwnerID:        user.ID,
-			OrganizationID: org.ID,
-			TemplateID:     template.ID,
-		})
-
-		// First build.
+		now                         = clock.Now().Add(testInterval)
+		thirtyFiveMinAgo            = now.Add(-time.Minute * 35)
 		expectedWorkspaceBuildState = []byte(`{"dean":"cool","colin":"also cool"}`)
-		currentWorkspaceBuildJob    = dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
-			CreatedAt: thirtyFiveMinAgo,
-			UpdatedAt: thirtyFiveMinAgo,
-			StartedAt: sql.NullTime{
-				Time:  time.Time{},
-				Valid: false,
+		chain                       = dbgen.HungWorkspaceBuild(t, db, pubsub, dbgen.HungWorkspaceBuildSeed{
+			Job: database.ProvisionerJob{
+				CreatedAt: thirtyFiveMinAgo,
+				UpdatedAt: thirtyFiveMinAgo,
 			},
-			OrganizationID: org.ID,
-			InitiatorID:    user.ID,
-			Provisioner:    database.ProvisionerTypeEcho,
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         file.ID,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			Input:          []byte("{}"),
-		})
-		currentWorkspaceBuild = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
-			WorkspaceID:       workspace.ID,
-			TemplateVersionID: templateVersion.ID,
-			BuildNumber:       1,
-			JobID:             currentWorkspaceBuildJob.ID,
 			// Should not be overridden.
 			ProvisionerState: expectedWorkspaceBuildState,
 		})
+		currentWorkspaceBuildJob = chain.Job
+		currentWorkspaceBuild    = chain.Build
 	)
 
 	t.Log("current job ID: ", currentWorkspaceBuildJob.ID)
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
@@ -515,14 +313,14 @@ func TestDetectorPendingWorkspaceBuildNoOverrideStateIfNoExistingBuild(t *testin
 	// Check that the current provisioner job was updated.
 	job, err := db.GetProvisionerJobByID(ctx, currentWorkspaceBuildJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.StartedAt.Valid)
-	require.WithinDuration(t, now, job.StartedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.StartedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as pending")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
 
 	// Check that the provisioner state was NOT updated.
 	build, err := db.GetWorkspaceBuildByID(ctx, currentWorkspaceBuild.ID)
@@ -540,12 +338,12 @@ func TestDetectorHungOtherJobTypes(t *testing.T) {
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
 		statsCh    = make(chan jobreaper.Stats)
+		clock      = dbtestutil.NewClock(t)
 	)
 
 	var (
-		now       = time.Now()
+		now       = clock.Now().Add(testInterval)
 		tenMinAgo = now.Add(-time.Minute * 10)
 		sixMinAgo = now.Add(-time.Minute * 6)
 		org       = dbgen.Organization(t, db, database.Organization{})
@@ -603,9 +401,9 @@ func TestDetectorHungOtherJobTypes(t *testing.T) {
 	t.Log("template import job ID: ", templateImportJob.ID)
 	t.Log("template dry-run job ID: ", templateDryRunJob.ID)
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
@@ -616,22 +414,22 @@ func TestDetectorHungOtherJobTypes(t *testing.T) {
 	// Check that the template import job was updated.
 	job, err := db.GetProvisionerJobByID(ctx, templateImportJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as hung")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
 
 	// Check that the template dry-run job was updated.
 	job, err = db.GetProvisionerJobByID(ctx, templateDryRunJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as hung")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
 
 	detector.Close()
 	detector.Wait()
@@ -644,12 +442,12 @@ func TestDetectorPendingOtherJobTypes(t *testing.T) {
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
 		statsCh    = make(chan jobreaper.Stats)
+		clock      = dbtestutil.NewClock(t)
 	)
 
 	var (
-		now              = time.Now()
+		now              = clock.Now().Add(testInterval)
 		thirtyFiveMinAgo = now.Add(-time.Minute * 35)
 		org              = dbgen.Organization(t, db, database.Organization{})
 		user             = dbgen.User(t, db, database.User{})
@@ -706,9 +504,9 @@ func TestDetectorPendingOtherJobTypes(t *testing.T) {
 	t.Log("template import job ID: ", templateImportJob.ID)
 	t.Log("template dry-run job ID: ", templateDryRunJob.ID)
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
@@ -719,26 +517,167 @@ func TestDetectorPendingOtherJobTypes(t *testing.T) {
 	// Check that the template import job was updated.
 	job, err := db.GetProvisionerJobByID(ctx, templateImportJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.StartedAt.Valid)
-	require.WithinDuration(t, now, job.StartedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.StartedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as pending")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
 
 	// Check that the template dry-run job was updated.
 	job, err = db.GetProvisionerJobByID(ctx, templateDryRunJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.StartedAt.Valid)
-	require.WithinDuration(t, now, job.StartedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.StartedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as pending")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
+
+	detector.Close()
+	detector.Wait()
+}
+
+func TestDetectorReadOnlyMode(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = testutil.Logger(t)
+		clock      = dbtestutil.NewClock(t)
+		statsCh    = make(chan jobreaper.Stats)
+		mode       readonly.Mode
+	)
+
+	var (
+		now              = clock.Now().Add(testInterval)
+		thirtyFiveMinAgo = now.Add(-time.Minute * 35)
+		org              = dbgen.Organization(t, db, database.Organization{})
+		user             = dbgen.User(t, db, database.User{})
+		file             = dbgen.File(t, db, database.File{})
+
+		pendingJob = dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+			CreatedAt:      thirtyFiveMinAgo,
+			UpdatedAt:      thirtyFiveMinAgo,
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			Input:          []byte("{}"),
+		})
+		_ = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			JobID:          pendingJob.ID,
+			CreatedBy:      user.ID,
+		})
+	)
+
+	mode.Set(true)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).
+		WithStatsChannel(statsCh).
+		WithReadOnlyMode(&mode).
+		WithClock(clock)
+	detector.Start()
+
+	// The job is left alone while the deployment is read-only.
+	clock.Advance(testInterval).MustWait(ctx)
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.TerminatedJobIDs)
+
+	job, err := db.GetProvisionerJobByID(ctx, pendingJob.ID)
+	require.NoError(t, err)
+	require.False(t, job.CompletedAt.Valid)
+
+	// And reaped once read-only mode is turned off.
+	mode.Set(false)
+	clock.Advance(testInterval).MustWait(ctx)
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{pendingJob.ID}, stats.TerminatedJobIDs)
+
+	detector.Close()
+	detector.Wait()
+}
+
+func TestDetectorPausedBuilds(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = testutil.Logger(t)
+		clock      = dbtestutil.NewClock(t)
+		statsCh    = make(chan jobreaper.Stats)
+	)
+
+	var (
+		now              = clock.Now().Add(testInterval)
+		thirtyFiveMinAgo = now.Add(-time.Minute * 35)
+		pausedOrg        = dbgen.Organization(t, db, database.Organization{})
+		otherOrg         = dbgen.Organization(t, db, database.Organization{})
+		user             = dbgen.User(t, db, database.User{})
+		file             = dbgen.File(t, db, database.File{})
+	)
+
+	pendingJob := func(orgID uuid.UUID) database.ProvisionerJob {
+		job := dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+			CreatedAt:      thirtyFiveMinAgo,
+			UpdatedAt:      thirtyFiveMinAgo,
+			OrganizationID: orgID,
+			InitiatorID:    user.ID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			Input:          []byte("{}"),
+		})
+		_ = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: orgID,
+			JobID:          job.ID,
+			CreatedBy:      user.ID,
+		})
+		return job
+	}
+	heldJob := pendingJob(pausedOrg.ID)
+	otherJob := pendingJob(otherOrg.ID)
+
+	pausedOrgID := uuid.NullUUID{UUID: pausedOrg.ID, Valid: true}
+	_, err := db.InsertProvisionerBuildPause(ctx, database.InsertProvisionerBuildPauseParams{
+		ID:             uuid.New(),
+		OrganizationID: pausedOrgID,
+		CreatedAt:      now,
+	})
+	require.NoError(t, err)
+
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
+	detector.Start()
+
+	// Only the job of the organization whose builds are not paused is reaped.
+	clock.Advance(testInterval).MustWait(ctx)
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{otherJob.ID}, stats.TerminatedJobIDs)
+
+	job, err := db.GetProvisionerJobByID(ctx, heldJob.ID)
+	require.NoError(t, err)
+	require.False(t, job.CompletedAt.Valid)
+
+	// Once builds are resumed, the job is reaped when it has been pending for
+	// too long.
+	err = db.DeleteProvisionerBuildPause(ctx, pausedOrgID)
+	require.NoError(t, err)
+	clock.Advance(testInterval).MustWait(ctx)
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{heldJob.ID}, stats.TerminatedJobIDs)
 
 	detector.Close()
 	detector.Wait()
@@ -751,12 +690,12 @@ func TestDetectorHungCanceledJob(t *testing.T) {
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
 		statsCh    = make(chan jobreaper.Stats)
+		clock      = dbtestutil.NewClock(t)
 	)
 
 	var (
-		now       = time.Now()
+		now       = clock.Now().Add(testInterval)
 		tenMinAgo = now.Add(-time.Minute * 10)
 		sixMinAgo = now.Add(-time.Minute * 6)
 		org       = dbgen.Organization(t, db, database.Organization{})
@@ -792,9 +731,9 @@ func TestDetectorHungCanceledJob(t *testing.T) {
 
 	t.Log("template import job ID: ", templateImportJob.ID)
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	stats := <-statsCh
 	require.NoError(t, stats.Error)
@@ -804,12 +743,119 @@ func TestDetectorHungCanceledJob(t *testing.T) {
 	// Check that the job was updated.
 	job, err := db.GetProvisionerJobByID(ctx, templateImportJob.ID)
 	require.NoError(t, err)
-	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.Equal(t, now.UTC(), job.UpdatedAt.UTC())
 	require.True(t, job.CompletedAt.Valid)
-	require.WithinDuration(t, now, job.CompletedAt.Time, 30*time.Second)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, "Build has been detected as hung")
-	require.False(t, job.ErrorCode.Valid)
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
+
+	detector.Close()
+	detector.Wait()
+}
+
+func TestDetectorUnacknowledgedCanceledJob(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = testutil.Logger(t)
+		statsCh    = make(chan jobreaper.Stats)
+		clock      = dbtestutil.NewClock(t)
+	)
+
+	var (
+		now        = clock.Now().Add(testInterval)
+		tenMinAgo  = now.Add(-time.Minute * 10)
+		fiveMinAgo = now.Add(-time.Minute * 5)
+		twoMinAgo  = now.Add(-time.Minute * 2)
+		oneMinAgo  = now.Add(-time.Minute)
+		org        = dbgen.Organization(t, db, database.Organization{})
+		user       = dbgen.User(t, db, database.User{})
+		file       = dbgen.File(t, db, database.File{})
+
+		// The provisioner keeps sending updates, so these jobs are not hung.
+		insertCanceledJob = func(canceledAt time.Time) database.ProvisionerJob {
+			job := dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+				CreatedAt: tenMinAgo,
+				CanceledAt: sql.NullTime{
+					Time:  canceledAt,
+					Valid: true,
+				},
+				StartedAt: sql.NullTime{
+					Time:  tenMinAgo,
+					Valid: true,
+				},
+				OrganizationID: org.ID,
+				InitiatorID:    user.ID,
+				Provisioner:    database.ProvisionerTypeEcho,
+				StorageMethod:  database.ProvisionerStorageMethodFile,
+				FileID:         file.ID,
+				Type:           database.ProvisionerJobTypeTemplateVersionImport,
+				Input:          []byte("{}"),
+			})
+			// Acquiring the job sets updated_at to started_at.
+			err := db.UpdateProvisionerJobByID(ctx, database.UpdateProvisionerJobByIDParams{
+				ID:        job.ID,
+				UpdatedAt: oneMinAgo,
+			})
+			require.NoError(t, err)
+			_ = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+				OrganizationID: org.ID,
+				JobID:          job.ID,
+				CreatedBy:      user.ID,
+			})
+			return job
+		}
+
+		// Canceled before the deadline and never acknowledged.
+		expiredJob = insertCanceledJob(fiveMinAgo)
+		// Canceled recently, the provisioner still has time to exit.
+		recentJob = insertCanceledJob(twoMinAgo)
+	)
+
+	t.Log("expired job ID: ", expiredJob.ID)
+	t.Log("recent job ID: ", recentJob.ID)
+
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
+	detector.Start()
+	clock.Advance(testInterval).MustWait(ctx)
+
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.TerminatedJobIDs, 1)
+	require.Contains(t, stats.TerminatedJobIDs, expiredJob.ID)
+
+	// Check that the expired job was terminated.
+	job, err := db.GetProvisionerJobByID(ctx, expiredJob.ID)
+	require.NoError(t, err)
+	require.True(t, job.CompletedAt.Valid)
+	require.Equal(t, now.UTC(), job.CompletedAt.Time.UTC())
+	require.True(t, job.Error.Valid)
+	require.Contains(t, job.Error.String, "Build has been detected as canceling")
+	require.Equal(t, string(codersdk.JobReaped), job.ErrorCode.String)
+
+	// Check that the recent job was left alone.
+	job, err = db.GetProvisionerJobByID(ctx, recentJob.ID)
+	require.NoError(t, err)
+	require.False(t, job.CompletedAt.Valid)
+
+	detector.Close()
+	detector.Wait()
+
+	// A shorter deadline also terminates the recently canceled job.
+	statsCh = make(chan jobreaper.Stats)
+	detector = jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).
+		WithStatsChannel(statsCh).
+		WithCancelDeadline(time.Minute).
+		WithClock(clock)
+	detector.Start()
+	clock.Advance(testInterval).MustWait(ctx)
+
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{recentJob.ID}, stats.TerminatedJobIDs)
 
 	detector.Close()
 	detector.Wait()
@@ -851,12 +897,12 @@ func TestDetectorPushesLogs(t *testing.T) {
 				ctx        = testutil.Context(t, testutil.WaitLong)
 				db, pubsub = dbtestutil.NewDB(t)
 				log        = testutil.Logger(t)
-				tickCh     = make(chan time.Time)
+				clock      = dbtestutil.NewClock(t)
 				statsCh    = make(chan jobreaper.Stats)
 			)
 
 			var (
-				now       = time.Now()
+				now       = clock.Now().Add(testInterval)
 				tenMinAgo = now.Add(-time.Minute * 10)
 				sixMinAgo = now.Add(-time.Minute * 6)
 				org       = dbgen.Organization(t, db, database.Organization{})
@@ -899,21 +945,21 @@ func TestDetectorPushesLogs(t *testing.T) {
 					insertParams.Stage = append(insertParams.Stage, c.preLogStage)
 					insertParams.Source = append(insertParams.Source, database.LogSourceProvisioner)
 					insertParams.Output = append(insertParams.Output, fmt.Sprintf("Output %d", i))
+					insertParams.Fields = append(insertParams.Fields, "{}")
 				}
 				logs, err := db.InsertProvisionerJobLogs(ctx, insertParams)
 				require.NoError(t, err)
 				require.Len(t, logs, 10)
 			}
 
-			detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+			detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 			detector.Start()
 
 			// Create pubsub subscription to listen for new log events.
 			pubsubCalled := make(chan int64, 1)
 			pubsubCancel, err := pubsub.Subscribe(provisionersdk.ProvisionerJobLogsNotifyChannel(templateImportJob.ID), func(ctx context.Context, message []byte) {
 				defer close(pubsubCalled)
-				var event provisionersdk.ProvisionerJobLogsNotifyMessage
-				err := json.Unmarshal(message, &event)
+				event, err := coderdpubsub.ProvisionerJobLogsNotify.Decode(message)
 				if !assert.NoError(t, err) {
 					return
 				}
@@ -924,7 +970,7 @@ func TestDetectorPushesLogs(t *testing.T) {
 			require.NoError(t, err)
 			defer pubsubCancel()
 
-			tickCh <- now
+			clock.Advance(testInterval).MustWait(ctx)
 
 			stats := <-statsCh
 			require.NoError(t, stats.Error)
@@ -976,7 +1022,7 @@ func TestDetectorMaxJobsPerRun(t *testing.T) {
 		ctx        = testutil.Context(t, testutil.WaitLong)
 		db, pubsub = dbtestutil.NewDB(t)
 		log        = testutil.Logger(t)
-		tickCh     = make(chan time.Time)
+		clock      = dbtestutil.NewClock(t)
 		statsCh    = make(chan jobreaper.Stats)
 		org        = dbgen.Organization(t, db, database.Organization{})
 		user       = dbgen.User(t, db, database.User{})
@@ -984,7 +1030,7 @@ func TestDetectorMaxJobsPerRun(t *testing.T) {
 	)
 
 	// Create MaxJobsPerRun + 1 hung jobs.
-	now := time.Now()
+	now := clock.Now().Add(testInterval)
 	for i := 0; i < jobreaper.MaxJobsPerRun+1; i++ {
 		pj := dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
 			CreatedAt: now.Add(-time.Hour),
@@ -1008,9 +1054,9 @@ func TestDetectorMaxJobsPerRun(t *testing.T) {
 		})
 	}
 
-	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, testInterval).WithStatsChannel(statsCh).WithClock(clock)
 	detector.Start()
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 
 	// Make sure that only MaxJobsPerRun jobs are terminated.
 	stats := <-statsCh
@@ -1019,7 +1065,7 @@ func TestDetectorMaxJobsPerRun(t *testing.T) {
 
 	// Run the detector again and make sure that only the remaining job is
 	// terminated.
-	tickCh <- now
+	clock.Advance(testInterval).MustWait(ctx)
 	stats = <-statsCh
 	require.NoError(t, stats.Error)
 	require.Len(t, stats.TerminatedJobIDs, 1)
@@ -1028,6 +1074,65 @@ func TestDetectorMaxJobsPerRun(t *testing.T) {
 	detector.Wait()
 }
 
+func TestReapJob(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = testutil.Logger(t)
+		clock      = dbtestutil.NewClock(t)
+		org        = dbgen.Organization(t, db, database.Organization{})
+		user       = dbgen.User(t, db, database.User{})
+		file       = dbgen.File(t, db, database.File{})
+	)
+
+	// The job was updated a moment ago, so the detector would not consider
+	// it hung.
+	now := clock.Now()
+	job := dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+		CreatedAt: now.Add(-time.Minute),
+		UpdatedAt: now.Add(-time.Second),
+		StartedAt: sql.NullTime{
+			Time:  now.Add(-time.Minute),
+			Valid: true,
+		},
+		OrganizationID: org.ID,
+		InitiatorID:    user.ID,
+		Provisioner:    database.ProvisionerTypeEcho,
+		StorageMethod:  database.ProvisionerStorageMethodFile,
+		FileID:         file.ID,
+		Type:           database.ProvisionerJobTypeTemplateVersionImport,
+		Input:          []byte("{}"),
+	})
+	_ = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		OrganizationID: org.ID,
+		JobID:          job.ID,
+		CreatedBy:      user.ID,
+	})
+
+	//nolint:gocritic // Test needs to act as the job reaper.
+	reaperCtx := dbauthz.AsJobReaper(ctx)
+	err := jobreaper.ReapJob(reaperCtx, log, wrapDBAuthz(db, log), pubsub, clock, job.ID)
+	require.NoError(t, err)
+
+	job, err = db.GetProvisionerJobByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.True(t, job.CompletedAt.Valid)
+	require.True(t, job.Error.Valid)
+	require.Contains(t, job.Error.String, "manually terminated")
+
+	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID: job.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, len(jobreaper.JobLogMessages(jobreaper.Manual, 0)))
+
+	// Reaping a completed job fails.
+	err = jobreaper.ReapJob(reaperCtx, log, wrapDBAuthz(db, log), pubsub, clock, job.ID)
+	require.ErrorContains(t, err, "job is completed")
+}
+
 // wrapDBAuthz adds our Authorization/RBAC around the given database store, to
 // ensure the reaper has the right permissions to do its work.
 func wrapDBAuthz(db database.Store, logger slog.Logger) database.Store {
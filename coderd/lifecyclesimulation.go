@@ -0,0 +1,236 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// lifecycleSimulationMaintenanceWindows is the number of upcoming maintenance
+// windows returned by a lifecycle simulation.
+const lifecycleSimulationMaintenanceWindows = 4
+
+// @Summary Simulate workspace lifecycle
+// @Description Computes the effective lifecycle policies (autostop deadlines,
+// @Description dormancy, quota charge and maintenance windows) for a
+// @Description hypothetical workspace created from the template. Nothing is
+// @Description created.
+// @ID simulate-workspace-lifecycle
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.SimulateWorkspaceLifecycleRequest true "Hypothetical workspace"
+// @Success 200 {object} codersdk.WorkspaceLifecycleSimulation
+// @Router /templates/{template}/lifecycle-simulation [post]
+func (api *API) postTemplateLifecycleSimulation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	// Simulations expose the schedules and quota of other users, so they
+	// are limited to those who can manage the template.
+	if !api.Authorize(r, policy.ActionUpdate, template.RBACObject()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.SimulateWorkspaceLifecycleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	userID := req.UserID
+	if userID == uuid.Nil {
+		userID = apiKey.UserID
+	}
+	owner, err := api.Database.GetUserByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) || dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "User not found.",
+			Validations: []codersdk.ValidationError{{Field: "user_id", Detail: "user does not exist"}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	dbAutostartSchedule, err := validWorkspaceSchedule(req.AutostartSchedule)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid Autostart Schedule.",
+			Validations: []codersdk.ValidationError{{Field: "autostart_schedule", Detail: err.Error()}},
+		})
+		return
+	}
+
+	templateSchedule, err := (*api.TemplateScheduleStore.Load()).Get(ctx, api.Database, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template schedule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	dbTTL, err := validWorkspaceTTLMillis(req.TTLMillis, templateSchedule.DefaultTTL)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid Workspace Time to Shutdown.",
+			Validations: []codersdk.ValidationError{{Field: "ttl_ms", Detail: err.Error()}},
+		})
+		return
+	}
+
+	startAt := dbtime.Now()
+	if req.StartAt != nil {
+		startAt = dbtime.Time(*req.StartAt)
+	}
+	if startAt.Before(schedule.TemplateAutostopRequirementEpoch(time.UTC)) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid start time.",
+			Validations: []codersdk.ValidationError{{Field: "start_at", Detail: "start time is too far in the past"}},
+		})
+		return
+	}
+
+	resp := codersdk.WorkspaceLifecycleSimulation{
+		StartAt:            startAt,
+		ActivityBumpMillis: templateSchedule.ActivityBump.Milliseconds(),
+		MaintenanceWindows: []time.Time{},
+	}
+
+	if dbAutostartSchedule.Valid && templateSchedule.UserAutostartEnabled {
+		next, err := schedule.NextAllowedAutostart(startAt, dbAutostartSchedule.String, templateSchedule)
+		if err == nil {
+			resp.NextAutostart = ptr.Ref(next)
+		}
+	}
+
+	autostop, err := schedule.CalculateAutostop(ctx, schedule.CalculateAutostopParams{
+		Database:                    api.Database,
+		TemplateScheduleStore:       *api.TemplateScheduleStore.Load(),
+		UserQuietHoursScheduleStore: *api.UserQuietHoursScheduleStore.Load(),
+		WorkspaceAutostart:          dbAutostartSchedule.String,
+		Now:                         startAt,
+		Workspace: database.WorkspaceTable{
+			OwnerID:        owner.ID,
+			OrganizationID: template.OrganizationID,
+			TemplateID:     template.ID,
+			Ttl:            dbTTL,
+		},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error calculating autostop.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !autostop.Deadline.IsZero() {
+		resp.Deadline = ptr.Ref(autostop.Deadline)
+	}
+	if !autostop.MaxDeadline.IsZero() {
+		resp.MaxDeadline = ptr.Ref(autostop.MaxDeadline)
+	}
+
+	// The workspace is assumed to be unused after it starts, so it becomes
+	// dormant as soon as the template allows.
+	if templateSchedule.TimeTilDormant > 0 {
+		dormantAt := startAt.Add(templateSchedule.TimeTilDormant)
+		resp.DormantAt = ptr.Ref(dormantAt)
+		if templateSchedule.TimeTilDormantAutoDelete > 0 {
+			resp.DeletingAt = ptr.Ref(dormantAt.Add(templateSchedule.TimeTilDormantAutoDelete))
+		}
+	}
+
+	if templateSchedule.AutostopRequirement.DaysOfWeek != 0 {
+		quietHours, err := (*api.UserQuietHoursScheduleStore.Load()).Get(ctx, api.Database, owner.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user quiet hours schedule.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		windows, err := schedule.NextAutostopRequirementWindows(startAt, quietHours.Schedule, templateSchedule.AutostopRequirement, lifecycleSimulationMaintenanceWindows)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error calculating maintenance windows.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		resp.MaintenanceWindows = append(resp.MaintenanceWindows, windows...)
+	}
+
+	// Quotas are only enforced when a quota committer is registered.
+	if api.QuotaCommitter.Load() != nil {
+		quota, err := api.simulateQuotaCharge(ctx, template, owner)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error calculating quota charge.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		resp.Quota = &quota
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// simulateQuotaCharge returns the quota charge of starting a new workspace
+// from the template's active version. It mirrors the check made by the quota
+// committer for a first build.
+func (api *API) simulateQuotaCharge(ctx context.Context, template database.Template, owner database.User) (codersdk.WorkspaceLifecycleSimulationQuota, error) {
+	version, err := api.Database.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+	if err != nil {
+		return codersdk.WorkspaceLifecycleSimulationQuota{}, err
+	}
+	dailyCost, err := templateVersionDailyCost(ctx, api.Database, version.JobID)
+	if err != nil {
+		return codersdk.WorkspaceLifecycleSimulationQuota{}, err
+	}
+
+	consumed, err := api.Database.GetQuotaConsumedForUser(ctx, database.GetQuotaConsumedForUserParams{
+		OwnerID:        owner.ID,
+		OrganizationID: template.OrganizationID,
+	})
+	if err != nil {
+		return codersdk.WorkspaceLifecycleSimulationQuota{}, err
+	}
+	budget, err := api.Database.GetQuotaAllowanceForUser(ctx, database.GetQuotaAllowanceForUserParams{
+		UserID:         owner.ID,
+		OrganizationID: template.OrganizationID,
+	})
+	if err != nil {
+		return codersdk.WorkspaceLifecycleSimulationQuota{}, err
+	}
+
+	return codersdk.WorkspaceLifecycleSimulationQuota{
+		DailyCost:       dailyCost,
+		CreditsConsumed: int(consumed),
+		Budget:          int(budget),
+		Allowed:         consumed+int64(dailyCost) <= budget,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSimulateWorkspaceLifecycle(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+		ctr.DefaultTTLMillis = ptr.Ref((8 * time.Hour).Milliseconds())
+	})
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// Monday 9am, with a weekday autostart at 8am.
+	startAt := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	autostart := "CRON_TZ=UTC 0 8 * * 1-5"
+
+	t.Run("TemplateDefaults", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		sim, err := client.SimulateWorkspaceLifecycle(ctx, template.ID, codersdk.SimulateWorkspaceLifecycleRequest{
+			UserID:            member.ID,
+			AutostartSchedule: ptr.Ref(autostart),
+			StartAt:           ptr.Ref(startAt),
+		})
+		require.NoError(t, err)
+		require.True(t, startAt.Equal(sim.StartAt))
+		require.NotNil(t, sim.NextAutostart)
+		require.True(t, time.Date(2024, time.January, 2, 8, 0, 0, 0, time.UTC).Equal(*sim.NextAutostart))
+		require.NotNil(t, sim.Deadline)
+		require.True(t, startAt.Add(8*time.Hour).Equal(*sim.Deadline))
+		require.Nil(t, sim.MaxDeadline)
+		// Dormancy, quotas and autostop requirements are not available
+		// without a license.
+		require.Nil(t, sim.DormantAt)
+		require.Nil(t, sim.DeletingAt)
+		require.Nil(t, sim.Quota)
+		require.Empty(t, sim.MaintenanceWindows)
+	})
+
+	t.Run("CustomTTL", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		sim, err := client.SimulateWorkspaceLifecycle(ctx, template.ID, codersdk.SimulateWorkspaceLifecycleRequest{
+			TTLMillis: ptr.Ref(time.Hour.Milliseconds()),
+			StartAt:   ptr.Ref(startAt),
+		})
+		require.NoError(t, err)
+		require.Nil(t, sim.NextAutostart)
+		require.NotNil(t, sim.Deadline)
+		require.True(t, startAt.Add(time.Hour).Equal(*sim.Deadline))
+	})
+
+	t.Run("InvalidSchedule", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := client.SimulateWorkspaceLifecycle(ctx, template.ID, codersdk.SimulateWorkspaceLifecycleRequest{
+			AutostartSchedule: ptr.Ref("not a schedule"),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := client.SimulateWorkspaceLifecycle(ctx, template.ID, codersdk.SimulateWorkspaceLifecycleRequest{
+			UserID: uuid.New(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := memberClient.SimulateWorkspaceLifecycle(ctx, template.ID, codersdk.SimulateWorkspaceLifecycleRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,312 @@
+package coderd
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+const (
+	// loginDeviceCodeLifetime is how long a user has to approve a device login.
+	loginDeviceCodeLifetime = 10 * time.Minute
+	// loginDeviceCodeInterval is the polling interval suggested to clients.
+	loginDeviceCodeInterval = 5 * time.Second
+	// loginDeviceUserCodeCharset excludes vowels and characters that are easy
+	// to confuse, so user codes can't spell words and are easy to type.
+	loginDeviceUserCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	loginDeviceUserCodeLength  = 8
+)
+
+// normalizeLoginDeviceUserCode removes the formatting from a user code, so
+// "bcdf-ghjk" and "BCDFGHJK" refer to the same code.
+func normalizeLoginDeviceUserCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// formatLoginDeviceUserCode splits a user code in two halves to make it
+// easier to read.
+func formatLoginDeviceUserCode(code string) string {
+	if len(code) != loginDeviceUserCodeLength {
+		return code
+	}
+	return code[:loginDeviceUserCodeLength/2] + "-" + code[loginDeviceUserCodeLength/2:]
+}
+
+func hashLoginDeviceCode(code string) []byte {
+	hashed := sha256.Sum256([]byte(code))
+	return hashed[:]
+}
+
+// Starts a device login for machines that can't open a browser, like a
+// headless server on a deployment that uses OIDC.
+//
+// @Summary Start device login
+// @ID start-device-login
+// @Produce json
+// @Tags Authorization
+// @Success 201 {object} codersdk.LoginDeviceCodeResponse
+// @Router /users/login/device [post]
+func (api *API) postLoginDevice(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deviceCode, err := cryptorand.String(32)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to generate device code.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	var code database.LoginDeviceCode
+	// User codes are short, so retry in the unlikely case of a collision.
+	for attempt := 0; attempt < 5; attempt++ {
+		userCode, err := cryptorand.StringCharset(loginDeviceUserCodeCharset, loginDeviceUserCodeLength)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to generate user code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		//nolint:gocritic // The user is not authenticated yet.
+		code, err = api.Database.InsertLoginDeviceCode(dbauthz.AsSystemRestricted(ctx), database.InsertLoginDeviceCodeParams{
+			ID:             uuid.New(),
+			DeviceCodeHash: hashLoginDeviceCode(deviceCode),
+			UserCode:       userCode,
+			CreatedAt:      now,
+			ExpiresAt:      now.Add(loginDeviceCodeLifetime),
+		})
+		if database.IsUniqueViolation(err, database.UniqueLoginDeviceCodesUserCodeKey) {
+			continue
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to create device code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		break
+	}
+	if code.ID == uuid.Nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create a unique user code.",
+		})
+		return
+	}
+
+	userCode := formatLoginDeviceUserCode(code.UserCode)
+	verificationURI := api.AccessURL.ResolveReference(&url.URL{Path: "/cli-auth/device"})
+	verificationURIComplete := *verificationURI
+	verificationURIComplete.RawQuery = url.Values{"code": {userCode}}.Encode()
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginDeviceCodeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI.String(),
+		VerificationURIComplete: verificationURIComplete.String(),
+		ExpiresIn:               int(loginDeviceCodeLifetime.Seconds()),
+		Interval:                int(loginDeviceCodeInterval.Seconds()),
+	})
+}
+
+// Exchanges an approved device code for a session token. Until the user
+// approves the login, the response is a 400 with the detail
+// "authorization_pending".
+//
+// @Summary Exchange device code for session token
+// @ID exchange-device-code-for-session-token
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginDeviceTokenRequest true "Device token request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/login/device/token [post]
+func (api *API) postLoginDeviceToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+
+	var req codersdk.LoginDeviceTokenRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	//nolint:gocritic // The device is not authenticated yet.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	deviceCodeHash := hashLoginDeviceCode(req.DeviceCode)
+	code, err := api.Database.ConsumeApprovedLoginDeviceCode(sysCtx, database.ConsumeApprovedLoginDeviceCodeParams{
+		DeviceCodeHash: deviceCodeHash,
+		Now:            dbtime.Now(),
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// Polling is expected, so only failed logins are audited.
+		pending, err := api.Database.GetLoginDeviceCodeByDeviceCodeHash(sysCtx, deviceCodeHash)
+		if err == nil && !pending.UserID.Valid && pending.ExpiresAt.After(dbtime.Now()) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The device login has not been approved yet.",
+				Detail:  codersdk.LoginDeviceAuthorizationPending,
+			})
+			return
+		}
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		defer commitAudit()
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The device code is invalid or expired.",
+			Detail:  codersdk.LoginDeviceExpiredToken,
+		})
+		return
+	}
+	defer commitAudit()
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.UserID = code.UserID.UUID
+
+	actor, userStatus, err := httpmw.UserRBACSubject(ctx, api.Database, code.UserID.UUID, rbac.ScopeAll)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch authorization user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	if userStatus != database.UserStatusActive {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: fmt.Sprintf("Your account is %s. Contact an admin to reactivate your account.", userStatus),
+		})
+		return
+	}
+
+	// The session matches the one created when logging in to the CLI with a
+	// browser.
+	//nolint:gocritic // Creating the API key as the user instead of as system.
+	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, actor), apikey.CreateParams{
+		UserID:          code.UserID.UUID,
+		LoginType:       database.LoginTypePassword,
+		RemoteAddr:      r.RemoteAddr,
+		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
+		BoundIdentity:   httpmw.APIKeyBoundIdentity(codersdk.SessionTokenBinding(api.DeploymentValues.Sessions.TokenBinding), r),
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to create API key", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = *key
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: cookie.Value,
+	})
+}
+
+// Approves a device login, signing the device in as the authenticated user.
+//
+// @Summary Approve device login
+// @ID approve-device-login
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Authorization
+// @Param request body codersdk.AuthorizeLoginDeviceRequest true "Authorize device request"
+// @Success 204
+// @Router /users/login/device/authorize [post]
+func (api *API) postLoginDeviceAuthorize(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.LoginDeviceCode](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.AuthorizeLoginDeviceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// The device is signed in with a session that isn't restricted to a
+	// scope, so approving it requires the permission to create such a session
+	// rather than any API key of the user.
+	if !api.Authorize(r, policy.ActionCreate, rbac.ResourceApiKey.WithOwner(apiKey.UserID.String())) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	//nolint:gocritic // Device codes are not owned by a user until approved.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	code, err := api.Database.GetLoginDeviceCodeByUserCode(sysCtx, normalizeLoginDeviceUserCode(req.UserCode))
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if err != nil || !code.ExpiresAt.After(dbtime.Now()) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The code is invalid or expired.",
+			Detail:  "Run the login command on your device again to get a new code.",
+		})
+		return
+	}
+	aReq.Old = code
+
+	approved, err := api.Database.ApproveLoginDeviceCode(sysCtx, database.ApproveLoginDeviceCodeParams{
+		ID:     code.ID,
+		UserID: apiKey.UserID,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The code was already used.",
+			Detail:  "Run the login command on your device again to get a new code.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = approved
+
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,161 @@
+package coderd_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestLoginDevice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Approve", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		first := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		device := codersdk.New(client.URL)
+		start, err := device.LoginDeviceCode(ctx)
+		require.NoError(t, err)
+		require.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, start.UserCode)
+		require.Equal(t, client.URL.String()+"/cli-auth/device", start.VerificationURI)
+		require.Equal(t, start.VerificationURI+"?code="+url.QueryEscape(start.UserCode), start.VerificationURIComplete)
+		require.Equal(t, 600, start.ExpiresIn)
+		require.Equal(t, 5, start.Interval)
+
+		// The device has to wait until the user approves the login.
+		_, err = device.LoginDeviceToken(ctx, codersdk.LoginDeviceTokenRequest{DeviceCode: start.DeviceCode})
+		sdkErr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Equal(t, codersdk.LoginDeviceAuthorizationPending, sdkErr.Detail)
+
+		// User codes are accepted regardless of case and formatting.
+		numLogs := len(auditor.AuditLogs())
+		err = member.AuthorizeLoginDevice(ctx, codersdk.AuthorizeLoginDeviceRequest{UserCode: "  " + start.UserCode[:4] + start.UserCode[5:]})
+		require.NoError(t, err)
+
+		res, err := device.LoginDeviceToken(ctx, codersdk.LoginDeviceTokenRequest{DeviceCode: start.DeviceCode})
+		require.NoError(t, err)
+		device.SetSessionToken(res.SessionToken)
+		me, err := device.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, memberUser.ID, me.ID)
+
+		// Both the approval and the login are audited.
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, numLogs+2)
+		require.Equal(t, database.AuditActionWrite, logs[numLogs].Action)
+		require.Equal(t, database.ResourceTypeLoginDeviceCode, logs[numLogs].ResourceType)
+		require.Equal(t, memberUser.ID, logs[numLogs].UserID)
+		require.Equal(t, database.AuditActionLogin, logs[numLogs+1].Action)
+		require.Equal(t, memberUser.ID, logs[numLogs+1].UserID)
+
+		// A device code can only be exchanged once.
+		_, err = device.LoginDeviceToken(ctx, codersdk.LoginDeviceTokenRequest{DeviceCode: start.DeviceCode})
+		sdkErr = coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Equal(t, codersdk.LoginDeviceExpiredToken, sdkErr.Detail)
+	})
+
+	t.Run("AlreadyApproved", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		start, err := codersdk.New(client.URL).LoginDeviceCode(ctx)
+		require.NoError(t, err)
+		err = client.AuthorizeLoginDevice(ctx, codersdk.AuthorizeLoginDeviceRequest{UserCode: start.UserCode})
+		require.NoError(t, err)
+
+		// Another user can't take over an approved login.
+		err = member.AuthorizeLoginDevice(ctx, codersdk.AuthorizeLoginDeviceRequest{UserCode: start.UserCode})
+		sdkErr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+
+	t.Run("ScopedToken", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// A token restricted to connecting to apps can't be turned into a full
+		// session by approving a device.
+		token, err := member.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope: codersdk.APIKeyScopeApplicationConnect,
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(token.Key)
+
+		start, err := codersdk.New(client.URL).LoginDeviceCode(ctx)
+		require.NoError(t, err)
+		err = scoped.AuthorizeLoginDevice(ctx, codersdk.AuthorizeLoginDeviceRequest{UserCode: start.UserCode})
+		sdkErr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+
+		_, err = codersdk.New(client.URL).LoginDeviceToken(ctx, codersdk.LoginDeviceTokenRequest{DeviceCode: start.DeviceCode})
+		sdkErr = coderdtest.SDKError(t, err)
+		require.Equal(t, codersdk.LoginDeviceAuthorizationPending, sdkErr.Detail)
+	})
+
+	t.Run("InvalidCodes", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		err := client.AuthorizeLoginDevice(ctx, codersdk.AuthorizeLoginDeviceRequest{UserCode: "BCDF-GHJK"})
+		sdkErr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+		_, err = codersdk.New(client.URL).LoginDeviceToken(ctx, codersdk.LoginDeviceTokenRequest{DeviceCode: "invalid"})
+		sdkErr = coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Equal(t, codersdk.LoginDeviceExpiredToken, sdkErr.Detail)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_ = dbgen.LoginDeviceCode(t, db, database.LoginDeviceCode{
+			UserCode:  "BCDFGHJK",
+			CreatedAt: dbtime.Now().Add(-20 * time.Minute),
+			ExpiresAt: dbtime.Now().Add(-10 * time.Minute),
+		})
+
+		err := client.AuthorizeLoginDevice(ctx, codersdk.AuthorizeLoginDeviceRequest{UserCode: "BCDF-GHJK"})
+		sdkErr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		err := codersdk.New(client.URL).AuthorizeLoginDevice(ctx, codersdk.AuthorizeLoginDeviceRequest{UserCode: "BCDF-GHJK"})
+		sdkErr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusUnauthorized, sdkErr.StatusCode())
+	})
+}
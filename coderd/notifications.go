@@ -2,10 +2,15 @@ package coderd
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 
@@ -293,8 +298,56 @@ func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.R
 		input.Disableds = append(input.Disableds, disabled)
 	}
 
+	digestInput := database.UpdateUserNotificationDigestPreferencesParams{
+		UserID:                  user.ID,
+		NotificationTemplateIds: make([]uuid.UUID, 0, len(prefs.TemplateDigestMap)),
+		Digests:                 make([]bool, 0, len(prefs.TemplateDigestMap)),
+	}
+	for tmplID, digest := range prefs.TemplateDigestMap {
+		id, err := uuid.Parse(tmplID)
+		if err != nil {
+			logger.Warn(ctx, "failed to parse notification template UUID", slog.F("input", tmplID), slog.Error(err))
+
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Unable to parse notification template UUID.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		// One-time passcodes can't wait for a digest, and digests can't be
+		// batched into themselves.
+		if digest && (id == notifications.TemplateUserRequestedOneTimePasscode || id == notifications.TemplateNotificationDigest) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "This notification can't be delivered in a digest.",
+				Validations: []codersdk.ValidationError{
+					{Field: "template_digest_map", Detail: fmt.Sprintf("Notification template %q can't be batched into digests.", tmplID)},
+				},
+			})
+			return
+		}
+
+		digestInput.NotificationTemplateIds = append(digestInput.NotificationTemplateIds, id)
+		digestInput.Digests = append(digestInput.Digests, digest)
+	}
+
 	// Update preferences with params.
-	updated, err := api.Database.UpdateUserNotificationPreferences(ctx, input)
+	var updated int64
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		updated, err = tx.UpdateUserNotificationPreferences(ctx, input)
+		if err != nil {
+			return xerrors.Errorf("update disabled preferences: %w", err)
+		}
+		if len(digestInput.NotificationTemplateIds) == 0 {
+			return nil
+		}
+		digestUpdated, err := tx.UpdateUserNotificationDigestPreferences(ctx, digestInput)
+		if err != nil {
+			return xerrors.Errorf("update digest preferences: %w", err)
+		}
+		updated += digestUpdated
+		return nil
+	}, nil)
 	if err != nil {
 		logger.Error(ctx, "failed to update preferences", slog.Error(err))
 
@@ -323,6 +376,163 @@ func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.R
 	httpapi.Write(ctx, rw, http.StatusOK, out)
 }
 
+// @Summary Get user notification digest settings
+// @ID get-user-notification-digest-settings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.NotificationDigestSettings
+// @Router /users/{user}/notifications/preferences/digest [get]
+func (api *API) userNotificationDigestSettings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	frequency, err := api.Database.GetUserNotificationDigestFrequency(ctx, user.ID)
+	if err != nil {
+		if !xerrors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to retrieve user notification digest settings.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		frequency = string(codersdk.NotificationDigestFrequencyDaily)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.NotificationDigestSettings{
+		Frequency: codersdk.NotificationDigestFrequency(frequency),
+	})
+}
+
+// @Summary Update user notification digest settings
+// @ID update-user-notification-digest-settings
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Notifications
+// @Param request body codersdk.NotificationDigestSettings true "Digest settings"
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.NotificationDigestSettings
+// @Router /users/{user}/notifications/preferences/digest [put]
+func (api *API) putUserNotificationDigestSettings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var req codersdk.NotificationDigestSettings
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !req.Frequency.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid digest frequency.",
+			Validations: []codersdk.ValidationError{
+				{Field: "frequency", Detail: fmt.Sprintf("Must be %q or %q.", codersdk.NotificationDigestFrequencyDaily, codersdk.NotificationDigestFrequencyWeekly)},
+			},
+		})
+		return
+	}
+
+	updated, err := api.Database.UpdateUserNotificationDigestFrequency(ctx, database.UpdateUserNotificationDigestFrequencyParams{
+		UserID:                      user.ID,
+		NotificationDigestFrequency: string(req.Frequency),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update user notification digest settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.NotificationDigestSettings{
+		Frequency: codersdk.NotificationDigestFrequency(updated.Value),
+	})
+}
+
+// @Summary Get user build duration notification settings
+// @ID get-user-build-duration-notification-settings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.NotificationBuildDurationSettings
+// @Router /users/{user}/notifications/preferences/build-duration [get]
+func (api *API) userNotificationBuildDurationSettings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var thresholdMillis int64
+	threshold, err := api.Database.GetUserNotificationBuildDurationThreshold(ctx, user.ID)
+	if err == nil {
+		thresholdMillis, err = strconv.ParseInt(threshold, 10, 64)
+	}
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve user build duration notification settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.NotificationBuildDurationSettings{
+		ThresholdMillis: thresholdMillis,
+	})
+}
+
+// @Summary Update user build duration notification settings
+// @ID update-user-build-duration-notification-settings
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Notifications
+// @Param request body codersdk.NotificationBuildDurationSettings true "Build duration settings"
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.NotificationBuildDurationSettings
+// @Router /users/{user}/notifications/preferences/build-duration [put]
+func (api *API) putUserNotificationBuildDurationSettings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var req codersdk.NotificationBuildDurationSettings
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	// Builds are compared against the threshold once a minute, so shorter
+	// thresholds aren't meaningful.
+	if req.ThresholdMillis < 0 || (req.ThresholdMillis > 0 && req.ThresholdMillis < time.Minute.Milliseconds()) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid build duration threshold.",
+			Validations: []codersdk.ValidationError{
+				{Field: "threshold_ms", Detail: "Must be zero to disable the notification, or at least one minute."},
+			},
+		})
+		return
+	}
+
+	_, err := api.Database.UpdateUserNotificationBuildDurationThreshold(ctx, database.UpdateUserNotificationBuildDurationThresholdParams{
+		UserID:                               user.ID,
+		NotificationBuildDurationThresholdMs: strconv.FormatInt(req.ThresholdMillis, 10),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update user build duration notification settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, req)
+}
+
 func convertNotificationTemplates(in []database.NotificationTemplate) (out []codersdk.NotificationTemplate) {
 	for _, tmpl := range in {
 		out = append(out, codersdk.NotificationTemplate{
@@ -346,6 +556,7 @@ func convertNotificationPreferences(in []database.NotificationPreference) (out [
 		out = append(out, codersdk.NotificationPreference{
 			NotificationTemplateID: pref.NotificationTemplateID,
 			Disabled:               pref.Disabled,
+			Digest:                 pref.Digest,
 			UpdatedAt:              pref.UpdatedAt,
 		})
 	}
@@ -122,6 +122,10 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 		if method == database.NotificationMethodInbox && templateID == TemplateUserRequestedOneTimePasscode {
 			continue
 		}
+		// The notifications summarized by digests are delivered to Coder Inbox right away.
+		if method == database.NotificationMethodInbox && templateID == TemplateNotificationDigest {
+			continue
+		}
 
 		id := uuid.New()
 		err = s.store.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
@@ -18,6 +18,9 @@ var (
 	TemplateWorkspaceManualBuildFailed = uuid.MustParse("2faeee0f-26cb-4e96-821c-85ccb9f71513")
 	TemplateWorkspaceOutOfMemory       = uuid.MustParse("a9d027b4-ac49-4fb1-9f6d-45af15f64e7a")
 	TemplateWorkspaceOutOfDisk         = uuid.MustParse("f047f6a3-5713-40f7-85aa-0394cce9fa3a")
+	TemplateWorkspaceDriftDetected     = uuid.MustParse("47878bb4-dd4e-4ef8-a1f7-f5dcbbada481")
+	TemplateWorkspaceOutdatedBuild     = uuid.MustParse("b71525cb-0c72-4d1f-8fa7-c11695b81e80")
+	TemplateWorkspaceBuildRunningLong  = uuid.MustParse("6b5c6a6e-4f9d-4c8e-9a43-0b9f3f6ed2a1")
 )
 
 // Account-related events.
@@ -31,6 +34,8 @@ var (
 	TemplateYourAccountActivated = uuid.MustParse("1a6a6bea-ee0a-43e2-9e7c-eabdb53730e4")
 
 	TemplateUserRequestedOneTimePasscode = uuid.MustParse("62f86a30-2330-4b61-a26d-311ff3b608cf")
+
+	TemplateAccessRequestCreated = uuid.MustParse("8c2f1a7e-3b4d-4e6f-9a1b-2c3d4e5f6a7b")
 )
 
 // Template-related events.
@@ -38,8 +43,9 @@ var (
 	TemplateTemplateDeleted    = uuid.MustParse("29a09665-2a4c-403f-9648-54301670e7be")
 	TemplateTemplateDeprecated = uuid.MustParse("f40fae84-55a2-42cd-99fa-b41c1ca64894")
 
-	TemplateWorkspaceBuildsFailedReport = uuid.MustParse("34a20db2-e9cc-4a93-b0e4-8569699d7a00")
-	TemplateWorkspaceResourceReplaced   = uuid.MustParse("89d9745a-816e-4695-a17f-3d0a229e2b8d")
+	TemplateWorkspaceBuildsFailedReport  = uuid.MustParse("34a20db2-e9cc-4a93-b0e4-8569699d7a00")
+	TemplateWorkspaceResourceReplaced    = uuid.MustParse("89d9745a-816e-4695-a17f-3d0a229e2b8d")
+	TemplateWorkspaceBuildAlertTriggered = uuid.MustParse("5d4ba6d2-1d4b-4a1b-9a0e-6b4f0c1f4b8e")
 )
 
 // Prebuilds-related events
@@ -47,7 +53,13 @@ var (
 	PrebuildFailureLimitReached = uuid.MustParse("414d9331-c1fc-4761-b40c-d1f4702279eb")
 )
 
+// License-related events.
+var (
+	TemplateLicenseUserLimitApproaching = uuid.MustParse("57fd22bf-e7b4-4123-96cf-86d659baf5a1")
+)
+
 // Notification-related events.
 var (
-	TemplateTestNotification = uuid.MustParse("c425f63e-716a-4bf4-ae24-78348f706c3f")
+	TemplateTestNotification   = uuid.MustParse("c425f63e-716a-4bf4-ae24-78348f706c3f")
+	TemplateNotificationDigest = uuid.MustParse("8b42563f-a4ce-441e-934c-cd165daed78e")
 )
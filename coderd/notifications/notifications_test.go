@@ -1217,6 +1217,75 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateWorkspaceDriftDetected",
+			id:   notifications.TemplateWorkspaceDriftDetected,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"name": "bobby-workspace",
+				},
+				Data: map[string]any{
+					"orphaned": []string{"docker_volume.home_volume"},
+					"drifted":  []string{"docker_container.workspace[0]", "docker_network.private"},
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceOutdatedBuild",
+			id:   notifications.TemplateWorkspaceOutdatedBuild,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"name":                  "bobby-workspace",
+					"template_name":         "bobby-template",
+					"template_version_name": "bobby-template-version-1",
+				},
+				Data: map[string]any{
+					"warnings": []string{
+						"The template of the workspace is deprecated: Use the new template instead.",
+						"Template version \"bobby-template-version-1\" is behind the active version of the template, \"bobby-template-version-3\", by 2 version(s).",
+					},
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceBuildRunningLong",
+			id:   notifications.TemplateWorkspaceBuildRunningLong,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"name":         "bobby-workspace",
+					"transition":   "start",
+					"build_number": "3",
+					"threshold":    "30 minutes",
+				},
+				Data: map[string]any{},
+			},
+		},
+		{
+			name: "TemplateLicenseUserLimitApproaching",
+			id:   notifications.TemplateLicenseUserLimitApproaching,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"active_users":     "45",
+					"user_limit":       "50",
+					"daily_growth":     "0.8",
+					"days_until_limit": "6",
+					"projected_date":   "2025-03-16",
+				},
+				Data: map[string]any{},
+			},
+		},
 		{
 			name: "TemplateTestNotification",
 			id:   notifications.TemplateTestNotification,
@@ -1250,6 +1319,24 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateWorkspaceBuildAlertTriggered",
+			id:   notifications.TemplateWorkspaceBuildAlertTriggered,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"rule":      "docker failures",
+					"metric":    "failure rate",
+					"scope":     "template docker",
+					"value":     "25.0%",
+					"threshold": "10.0%",
+					"window":    "15m0s",
+				},
+				Data: map[string]any{},
+			},
+		},
 		{
 			name: "PrebuildFailureLimitReached",
 			id:   notifications.PrebuildFailureLimitReached,
@@ -1266,6 +1353,58 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				Data: map[string]any{},
 			},
 		},
+		{
+			name: "TemplateAccessRequestCreated",
+			id:   notifications.TemplateAccessRequestCreated,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"requester":     "alice",
+					"role":          "connect",
+					"resource_type": "workspace",
+					"resource":      "bobby/bobby-workspace",
+					"resource_path": "@bobby/bobby-workspace",
+					"duration":      "2h0m0s",
+					"reason":        "Debugging a failing deployment",
+				},
+				Data: map[string]any{},
+			},
+		},
+		{
+			name: "TemplateNotificationDigest",
+			id:   notifications.TemplateNotificationDigest,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"frequency": "daily",
+					"count":     "13",
+				},
+				// We need to use floats as `json.Unmarshal` unmarshal numbers in `map[string]any` to floats.
+				Data: map[string]any{
+					"notifications": []map[string]any{
+						{
+							"name":  "Workspace Marked as Dormant",
+							"count": 12.0,
+							"titles": []string{
+								`Workspace "bobby-workspace" marked as dormant`,
+								`Workspace "bobby-other-workspace" marked as dormant`,
+							},
+							"more": 10.0,
+						},
+						{
+							"name":   "Template Deprecated",
+							"count":  1.0,
+							"titles": []string{`Template "bobby-template" has been deprecated`},
+							"more":   0.0,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	// We must have a test case for every notification_template. This is enforced below:
@@ -0,0 +1,135 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/render"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/quartz"
+)
+
+// digestTitlesLimit caps the titles listed per notification in a digest, to
+// prevent long digests of noisy notifications.
+const digestTitlesLimit = 10
+
+// sendNotificationDigests delivers the notifications batched for users whose
+// digest is due, as a single digest notification per user.
+func sendNotificationDigests(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock) error {
+	now := dbtime.Time(clk.Now()).UTC()
+
+	users, err := db.GetUsersWithDueNotificationDigests(ctx, now)
+	if err != nil {
+		return xerrors.Errorf("unable to fetch users with due notification digests: %w", err)
+	}
+
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := sendNotificationDigest(ctx, db, enqueuer, user, now)
+		if err != nil {
+			logger.Warn(ctx, "failed to send a notification digest", slog.F("user_id", user.UserID), slog.Error(err))
+		}
+	}
+	return nil
+}
+
+func sendNotificationDigest(ctx context.Context, db database.Store, enqueuer notifications.Enqueuer, user database.GetUsersWithDueNotificationDigestsRow, now time.Time) error {
+	messages, err := db.GetBatchedNotificationMessagesByUserID(ctx, user.UserID)
+	if err != nil {
+		return xerrors.Errorf("fetch batched notification messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	_, err = enqueuer.EnqueueWithData(ctx, user.UserID, notifications.TemplateNotificationDigest,
+		map[string]string{
+			"frequency": user.Frequency,
+			"count":     strconv.Itoa(len(messages)),
+		},
+		buildDataForNotificationDigest(messages),
+		"report_generator",
+	)
+	// A digest the user disabled drops the batched notifications, just like
+	// any other disabled notification. A duplicate digest was enqueued by a
+	// previous run that failed to mark the messages.
+	if err != nil && !xerrors.Is(err, notifications.ErrCannotEnqueueDisabledNotification) && !xerrors.Is(err, notifications.ErrDuplicate) {
+		return xerrors.Errorf("enqueue notification digest: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+	if _, err := db.MarkNotificationMessagesDigested(ctx, database.MarkNotificationMessagesDigestedParams{
+		IDs:        ids,
+		DigestedAt: now,
+	}); err != nil {
+		return xerrors.Errorf("mark notification messages digested: %w", err)
+	}
+	return nil
+}
+
+func buildDataForNotificationDigest(messages []database.GetBatchedNotificationMessagesByUserIDRow) map[string]any {
+	// Notifications are grouped by their name, in the order they were first
+	// received. The map requires `[]map[string]any{}` to be compatible with
+	// data passed to `NotificationEnqueuer`.
+	groups := []map[string]any{}
+	indexes := make(map[string]int)
+	for _, msg := range messages {
+		i, ok := indexes[msg.NotificationName]
+		if !ok {
+			i = len(groups)
+			indexes[msg.NotificationName] = i
+			groups = append(groups, map[string]any{
+				"name":   msg.NotificationName,
+				"count":  0,
+				"titles": []string{},
+				"more":   0,
+			})
+		}
+
+		group := groups[i]
+		//nolint:errorlint,forcetypeassert // only this function prepares the notification model
+		group["count"] = group["count"].(int) + 1
+		//nolint:errorlint,forcetypeassert // only this function prepares the notification model
+		titles := group["titles"].([]string)
+		if len(titles) < digestTitlesLimit {
+			group["titles"] = append(titles, digestTitle(msg))
+		} else {
+			//nolint:errorlint,forcetypeassert // only this function prepares the notification model
+			group["more"] = group["more"].(int) + 1
+		}
+	}
+
+	return map[string]any{
+		"notifications": groups,
+	}
+}
+
+// digestTitle renders the title of a batched message, and falls back to the
+// name of its notification if the title can't be rendered.
+func digestTitle(msg database.GetBatchedNotificationMessagesByUserIDRow) string {
+	var payload types.MessagePayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return msg.NotificationName
+	}
+	title, err := render.GoTemplate(msg.TitleTemplate, payload, nil)
+	if err != nil || title == "" {
+		return msg.NotificationName
+	}
+	return title
+}
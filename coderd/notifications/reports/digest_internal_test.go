@@ -0,0 +1,153 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+)
+
+func TestSendNotificationDigests(t *testing.T) {
+	t.Parallel()
+
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("This test requires postgres; it relies on business-logic only implemented in the database")
+	}
+
+	// Setup
+	ctx, logger, db, _, notifEnq, clk := setup(t)
+	user := dbgen.User(t, db, database.User{})
+	other := dbgen.User(t, db, database.User{})
+
+	// Given: the user batches dormancy notifications into a daily digest
+	_, err := db.UpdateUserNotificationDigestPreferences(ctx, database.UpdateUserNotificationDigestPreferencesParams{
+		UserID:                  user.ID,
+		NotificationTemplateIds: []uuid.UUID{notifications.TemplateWorkspaceDormant},
+		Digests:                 []bool{true},
+	})
+	require.NoError(t, err)
+
+	enqueue := func(userID uuid.UUID, workspace string) uuid.UUID {
+		payload, err := json.Marshal(types.MessagePayload{
+			NotificationName: "Workspace Marked as Dormant",
+			Labels:           map[string]string{"name": workspace},
+		})
+		require.NoError(t, err)
+		id := uuid.New()
+		err = db.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
+			ID:                     id,
+			UserID:                 userID,
+			NotificationTemplateID: notifications.TemplateWorkspaceDormant,
+			Method:                 database.NotificationMethodSmtp,
+			Payload:                payload,
+			CreatedBy:              "test",
+			CreatedAt:              clk.Now(),
+		})
+		require.NoError(t, err)
+		return id
+	}
+	batched := []uuid.UUID{enqueue(user.ID, "ws-1"), enqueue(user.ID, "ws-2")}
+	_ = enqueue(other.ID, "ws-3")
+
+	// Then: only the messages of the user are held back
+	held, err := db.GetNotificationMessagesByStatus(ctx, database.GetNotificationMessagesByStatusParams{
+		Status: database.NotificationMessageStatusBatched,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, held, 2)
+
+	// When: the digest isn't due yet
+	notifEnq.Clear()
+	err = sendNotificationDigests(ctx, logger, db, notifEnq, clk)
+
+	// Then: no digest is sent
+	require.NoError(t, err)
+	require.Empty(t, notifEnq.Sent())
+
+	// Given: one day later
+	clk.Advance(24*time.Hour + time.Minute)
+
+	// When
+	err = sendNotificationDigests(ctx, logger, db, notifEnq, clk)
+
+	// Then: a single digest summarizes the batched notifications
+	require.NoError(t, err)
+	sent := notifEnq.Sent()
+	require.Len(t, sent, 1)
+	require.Equal(t, user.ID, sent[0].UserID)
+	require.Equal(t, notifications.TemplateNotificationDigest, sent[0].TemplateID)
+	require.Equal(t, map[string]string{"frequency": "daily", "count": "2"}, sent[0].Labels)
+	require.Equal(t, []map[string]any{{
+		"name":   "Workspace Marked as Dormant",
+		"count":  2,
+		"titles": []string{`Workspace "ws-1" marked as dormant`, `Workspace "ws-2" marked as dormant`},
+		"more":   0,
+	}}, sent[0].Data["notifications"])
+
+	// Then: the batched messages were delivered
+	delivered, err := db.GetNotificationMessagesByStatus(ctx, database.GetNotificationMessagesByStatusParams{
+		Status: database.NotificationMessageStatusSent,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, delivered, 2)
+	for _, msg := range delivered {
+		require.Contains(t, batched, msg.ID)
+	}
+
+	// When: the digest job runs again
+	notifEnq.Clear()
+	err = sendNotificationDigests(ctx, logger, db, notifEnq, clk)
+
+	// Then: nothing is left to send
+	require.NoError(t, err)
+	require.Empty(t, notifEnq.Sent())
+}
+
+func TestBuildDataForNotificationDigest(t *testing.T) {
+	t.Parallel()
+
+	message := func(name, title string, labels map[string]string) database.GetBatchedNotificationMessagesByUserIDRow {
+		payload, err := json.Marshal(types.MessagePayload{NotificationName: name, Labels: labels})
+		require.NoError(t, err)
+		return database.GetBatchedNotificationMessagesByUserIDRow{
+			ID:               uuid.New(),
+			Payload:          payload,
+			NotificationName: name,
+			TitleTemplate:    title,
+		}
+	}
+
+	var messages []database.GetBatchedNotificationMessagesByUserIDRow
+	for i := range digestTitlesLimit + 2 {
+		messages = append(messages, message("Workspace Marked as Dormant", `Workspace "{{.Labels.name}}" marked as dormant`, map[string]string{
+			"name": fmt.Sprintf("ws-%d", i),
+		}))
+	}
+	// Titles that can't be rendered fall back to the notification name.
+	messages = append(messages, message("Template Deleted", `Template deleted on {{ app_name }}`, nil))
+
+	data := buildDataForNotificationDigest(messages)
+	groups, ok := data["notifications"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, groups, 2)
+
+	require.Equal(t, "Workspace Marked as Dormant", groups[0]["name"])
+	require.Equal(t, digestTitlesLimit+2, groups[0]["count"])
+	require.Len(t, groups[0]["titles"], digestTitlesLimit)
+	require.Equal(t, `Workspace "ws-0" marked as dormant`, groups[0]["titles"].([]string)[0])
+	require.Equal(t, 2, groups[0]["more"])
+
+	require.Equal(t, "Template Deleted", groups[1]["name"])
+	require.Equal(t, []string{"Template Deleted"}, groups[1]["titles"])
+}
@@ -55,6 +55,11 @@ func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Sto
 				return xerrors.Errorf("unable to generate reports with failed workspace builds: %w", err)
 			}
 
+			err = sendNotificationDigests(ctx, logger, tx, enqueuer, clk)
+			if err != nil {
+				return xerrors.Errorf("unable to send notification digests: %w", err)
+			}
+
 			logger.Info(ctx, "report generator finished", slog.F("duration", clk.Since(start)))
 
 			return nil
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -268,6 +269,137 @@ func TestNotificationPreferences(t *testing.T) {
 		}
 		require.True(t, found, "dormant notification preference was not found")
 	})
+
+	t.Run("Digest preferences", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: a member who disabled a notification.
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+		_, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateDisabledMap: map[string]bool{
+				notifications.TemplateWorkspaceDeleted.String(): true,
+			},
+		})
+		require.NoError(t, err)
+
+		// When: batching notifications into digests.
+		prefs, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateDigestMap: map[string]bool{
+				notifications.TemplateWorkspaceDeleted.String(): true,
+				notifications.TemplateWorkspaceDormant.String(): true,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, prefs, 2)
+
+		// Then: the digest preferences are set without changing whether the
+		// notifications are enabled.
+		for _, p := range prefs {
+			require.True(t, p.Digest)
+			require.Equal(t, p.NotificationTemplateID == notifications.TemplateWorkspaceDeleted, p.Disabled)
+		}
+
+		// When: attempting to batch one-time passcodes into digests.
+		_, err = memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateDigestMap: map[string]bool{
+				notifications.TemplateUserRequestedOneTimePasscode.String(): true,
+			},
+		})
+
+		// Then: the request is rejected.
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+	})
+}
+
+func TestNotificationDigestSettings(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	api := coderdtest.New(t, createOpts(t))
+	firstUser := coderdtest.CreateFirstUser(t, api)
+	memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+	// Digests are sent daily by default.
+	settings, err := memberClient.GetUserNotificationDigestSettings(ctx, member.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NotificationDigestFrequencyDaily, settings.Frequency)
+
+	settings, err = memberClient.UpdateUserNotificationDigestSettings(ctx, member.ID, codersdk.NotificationDigestSettings{
+		Frequency: codersdk.NotificationDigestFrequencyWeekly,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NotificationDigestFrequencyWeekly, settings.Frequency)
+
+	settings, err = memberClient.GetUserNotificationDigestSettings(ctx, member.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NotificationDigestFrequencyWeekly, settings.Frequency)
+
+	_, err = memberClient.UpdateUserNotificationDigestSettings(ctx, member.ID, codersdk.NotificationDigestSettings{
+		Frequency: "hourly",
+	})
+	var sdkError *codersdk.Error
+	require.ErrorAs(t, err, &sdkError)
+	require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+
+	// Members can't change the settings of other users. ExtractUserParam
+	// rejects the request before it is authorized.
+	_, err = memberClient.UpdateUserNotificationDigestSettings(ctx, firstUser.UserID, codersdk.NotificationDigestSettings{
+		Frequency: codersdk.NotificationDigestFrequencyWeekly,
+	})
+	require.ErrorAs(t, err, &sdkError)
+	require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+}
+
+func TestNotificationBuildDurationSettings(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	api := coderdtest.New(t, createOpts(t))
+	firstUser := coderdtest.CreateFirstUser(t, api)
+	memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+	// The notification is disabled by default.
+	settings, err := memberClient.GetUserNotificationBuildDurationSettings(ctx, member.ID)
+	require.NoError(t, err)
+	require.Zero(t, settings.ThresholdMillis)
+
+	settings, err = memberClient.UpdateUserNotificationBuildDurationSettings(ctx, member.ID, codersdk.NotificationBuildDurationSettings{
+		ThresholdMillis: (30 * time.Minute).Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, (30 * time.Minute).Milliseconds(), settings.ThresholdMillis)
+
+	settings, err = memberClient.GetUserNotificationBuildDurationSettings(ctx, member.ID)
+	require.NoError(t, err)
+	require.Equal(t, (30 * time.Minute).Milliseconds(), settings.ThresholdMillis)
+
+	// Thresholds shorter than a minute are rejected.
+	var sdkError *codersdk.Error
+	for _, threshold := range []int64{-1, (30 * time.Second).Milliseconds()} {
+		_, err = memberClient.UpdateUserNotificationBuildDurationSettings(ctx, member.ID, codersdk.NotificationBuildDurationSettings{
+			ThresholdMillis: threshold,
+		})
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+	}
+
+	// A zero threshold disables the notification again.
+	settings, err = memberClient.UpdateUserNotificationBuildDurationSettings(ctx, member.ID, codersdk.NotificationBuildDurationSettings{})
+	require.NoError(t, err)
+	require.Zero(t, settings.ThresholdMillis)
+
+	// Members can't change the settings of other users.
+	_, err = memberClient.UpdateUserNotificationBuildDurationSettings(ctx, firstUser.UserID, codersdk.NotificationBuildDurationSettings{
+		ThresholdMillis: (30 * time.Minute).Milliseconds(),
+	})
+	require.ErrorAs(t, err, &sdkError)
+	require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
 }
 
 func TestNotificationDispatchMethods(t *testing.T) {
@@ -0,0 +1,297 @@
+// Package oci pulls template archives that are distributed as OCI artifacts
+// from container registries.
+//
+// A template artifact is a manifest with a single tar layer that contains the
+// template source code, for example as pushed by:
+//
+//	oras push ghcr.io/acme/templates/docker:v1 template.tar:application/vnd.coder.template.layer.v1.tar
+package oci
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	MediaTypeImageManifest  = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+
+	MediaTypeTemplateLayer     = "application/vnd.coder.template.layer.v1.tar"
+	MediaTypeTemplateLayerGzip = "application/vnd.coder.template.layer.v1.tar+gzip"
+
+	// maxManifestBytes matches the limit that most registries enforce on
+	// manifests.
+	maxManifestBytes = 4 << 20
+)
+
+// layerMediaTypes are the media types of layers that may contain the template,
+// mapped to whether the layer is gzip compressed.
+var layerMediaTypes = map[string]bool{
+	MediaTypeTemplateLayer:                        false,
+	MediaTypeTemplateLayerGzip:                    true,
+	"application/vnd.oci.image.layer.v1.tar":      false,
+	"application/vnd.oci.image.layer.v1.tar+gzip": true,
+	"application/x-tar":                           false,
+}
+
+// Descriptor describes a blob in a registry.
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// TarHash returns the hex-encoded sha256 hash of the tar archive in the layer
+// if it can be known without downloading the layer, i.e. if the layer is not
+// compressed.
+func (d Descriptor) TarHash() (string, bool) {
+	if layerMediaTypes[d.MediaType] {
+		return "", false
+	}
+	return strings.TrimPrefix(d.Digest, "sha256:"), true
+}
+
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []Descriptor `json:"layers"`
+}
+
+// Artifact is a template artifact that has been resolved in a registry.
+type Artifact struct {
+	Reference Reference
+	// Digest is the digest of the manifest of the artifact.
+	Digest string
+	// Layer is the layer that contains the template.
+	Layer Descriptor
+}
+
+// Client pulls template artifacts from registries. Only anonymous access is
+// supported, including registries that issue anonymous bearer tokens.
+type Client struct {
+	HTTPClient *http.Client
+	// MaxSize is the maximum size of the layer and of the tar archive it
+	// contains. 0 means unlimited.
+	MaxSize int64
+}
+
+// Resolve fetches the manifest of the artifact, verifies its digest and finds
+// the layer that contains the template.
+func (c *Client) Resolve(ctx context.Context, ref Reference) (Artifact, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.apiHost(), ref.Repository, ref.manifestReference())
+	res, err := c.get(ctx, ref, u, MediaTypeImageManifest+", "+MediaTypeDockerManifest)
+	if err != nil {
+		return Artifact{}, xerrors.Errorf("fetch manifest: %w", err)
+	}
+	defer res.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(res.Body, maxManifestBytes+1))
+	if err != nil {
+		return Artifact{}, xerrors.Errorf("read manifest: %w", err)
+	}
+	if len(raw) > maxManifestBytes {
+		return Artifact{}, xerrors.Errorf("manifest exceeds %d bytes", maxManifestBytes)
+	}
+
+	digest := sha256Digest(raw)
+	if ref.Digest != "" && digest != ref.Digest {
+		return Artifact{}, xerrors.Errorf("manifest digest %s does not match reference digest %s", digest, ref.Digest)
+	}
+	if header := res.Header.Get("Docker-Content-Digest"); strings.HasPrefix(header, "sha256:") && header != digest {
+		return Artifact{}, xerrors.Errorf("manifest digest %s does not match registry digest %s", digest, header)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return Artifact{}, xerrors.Errorf("decode manifest: %w", err)
+	}
+	mediaType := m.MediaType
+	if mediaType == "" {
+		mediaType = res.Header.Get("Content-Type")
+	}
+	if mediaType != MediaTypeImageManifest && mediaType != MediaTypeDockerManifest {
+		return Artifact{}, xerrors.Errorf("unsupported manifest media type %q: references must point to a single manifest", mediaType)
+	}
+
+	for _, layer := range m.Layers {
+		if _, ok := layerMediaTypes[layer.MediaType]; !ok {
+			continue
+		}
+		if !digestRegex.MatchString(layer.Digest) {
+			return Artifact{}, xerrors.Errorf("unsupported layer digest %q", layer.Digest)
+		}
+		if c.MaxSize > 0 && layer.Size > c.MaxSize {
+			return Artifact{}, xerrors.Errorf("layer size %d exceeds the maximum of %d bytes", layer.Size, c.MaxSize)
+		}
+		return Artifact{
+			Reference: ref,
+			Digest:    digest,
+			Layer:     layer,
+		}, nil
+	}
+	return Artifact{}, xerrors.New("the artifact has no tar layer")
+}
+
+// Fetch downloads the layer of the artifact, verifies its digest and returns
+// the tar archive it contains.
+func (c *Client) Fetch(ctx context.Context, artifact Artifact) ([]byte, error) {
+	ref := artifact.Reference
+	u := fmt.Sprintf("https://%s/v2/%s/blobs/%s", ref.apiHost(), ref.Repository, artifact.Layer.Digest)
+	res, err := c.get(ctx, ref, u, "")
+	if err != nil {
+		return nil, xerrors.Errorf("fetch layer: %w", err)
+	}
+	defer res.Body.Close()
+	data, err := c.readAll(res.Body)
+	if err != nil {
+		return nil, xerrors.Errorf("read layer: %w", err)
+	}
+	if digest := sha256Digest(data); digest != artifact.Layer.Digest {
+		return nil, xerrors.Errorf("layer digest %s does not match manifest digest %s", digest, artifact.Layer.Digest)
+	}
+
+	if !layerMediaTypes[artifact.Layer.MediaType] {
+		return data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, xerrors.Errorf("decompress layer: %w", err)
+	}
+	defer zr.Close()
+	data, err = c.readAll(zr)
+	if err != nil {
+		return nil, xerrors.Errorf("decompress layer: %w", err)
+	}
+	return data, nil
+}
+
+func (c *Client) readAll(r io.Reader) ([]byte, error) {
+	if c.MaxSize <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, c.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > c.MaxSize {
+		return nil, xerrors.Errorf("exceeds the maximum of %d bytes", c.MaxSize)
+	}
+	return data, nil
+}
+
+// get performs a GET request against the registry API. If the registry
+// requires a bearer token, an anonymous token is requested and the request
+// is retried.
+func (c *Client) get(ctx context.Context, ref Reference, u, accept string) (*http.Response, error) {
+	do := func(token string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return c.httpClient().Do(req)
+	}
+
+	res, err := do("")
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		challenge := res.Header.Get("WWW-Authenticate")
+		_ = res.Body.Close()
+		token, err := c.token(ctx, ref, challenge)
+		if err != nil {
+			return nil, xerrors.Errorf("authenticate: %w", err)
+		}
+		res, err = do(token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, xerrors.Errorf("registry responded with status %d", res.StatusCode)
+	}
+	return res, nil
+}
+
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// token requests an anonymous pull token from the authorization server of the
+// challenge.
+func (c *Client) token(ctx context.Context, ref Reference, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", xerrors.Errorf("unsupported authentication scheme %q: only anonymous access is supported", scheme)
+	}
+	values := url.Values{}
+	var realm string
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(params, -1) {
+		switch match[1] {
+		case "realm":
+			realm = match[2]
+		case "service", "scope":
+			values.Set(match[1], match[2])
+		}
+	}
+	if realm == "" {
+		return "", xerrors.New("bearer challenge has no realm")
+	}
+	if values.Get("scope") == "" {
+		values.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+values.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", xerrors.Errorf("token server responded with status %d", res.StatusCode)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", xerrors.Errorf("decode token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", xerrors.New("token server returned no token")
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,209 @@
+package oci_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/oci"
+	"github.com/coder/coder/v2/coderd/oci/ocitest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+
+	digest := "sha256:" + strings.Repeat("a", 64)
+	for _, tc := range []struct {
+		name     string
+		input    string
+		expected oci.Reference
+		err      string
+	}{
+		{
+			name:     "Tag",
+			input:    "ghcr.io/acme/templates/docker:v1",
+			expected: oci.Reference{Registry: "ghcr.io", Repository: "acme/templates/docker", Tag: "v1"},
+		},
+		{
+			name:     "DefaultTag",
+			input:    "ghcr.io/acme/docker",
+			expected: oci.Reference{Registry: "ghcr.io", Repository: "acme/docker", Tag: "latest"},
+		},
+		{
+			name:     "Digest",
+			input:    "ghcr.io/acme/docker@" + digest,
+			expected: oci.Reference{Registry: "ghcr.io", Repository: "acme/docker", Digest: digest},
+		},
+		{
+			name:     "TagAndDigest",
+			input:    "ghcr.io/acme/docker:v1@" + digest,
+			expected: oci.Reference{Registry: "ghcr.io", Repository: "acme/docker", Tag: "v1", Digest: digest},
+		},
+		{
+			name:     "Port",
+			input:    "localhost:5000/docker:v1",
+			expected: oci.Reference{Registry: "localhost:5000", Repository: "docker", Tag: "v1"},
+		},
+		{
+			name:     "DockerHub",
+			input:    "acme/docker:v1",
+			expected: oci.Reference{Registry: "docker.io", Repository: "acme/docker", Tag: "v1"},
+		},
+		{
+			name:     "DockerHubLibrary",
+			input:    "docker",
+			expected: oci.Reference{Registry: "docker.io", Repository: "library/docker", Tag: "latest"},
+		},
+		{
+			name:  "UnsupportedDigest",
+			input: "ghcr.io/acme/docker@sha512:abc",
+			err:   "only sha256 digests are supported",
+		},
+		{
+			name:  "InvalidRepository",
+			input: "ghcr.io/Acme/Docker:v1",
+			err:   "invalid repository",
+		},
+		{
+			name:  "InvalidTag",
+			input: "ghcr.io/acme/docker:v1!",
+			err:   "invalid tag",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ref, err := oci.ParseReference(tc.input)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ref)
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	tarData := []byte("template archive")
+
+	t.Run("Tar", func(t *testing.T) {
+		t.Parallel()
+		registry := ocitest.New(t, "acme/docker", "v1", oci.MediaTypeTemplateLayer, tarData)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ref, err := oci.ParseReference(registry.Reference)
+		require.NoError(t, err)
+		client := &oci.Client{HTTPClient: registry.Client()}
+
+		artifact, err := client.Resolve(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, registry.Digest, artifact.Digest)
+		hash, ok := artifact.Layer.TarHash()
+		require.True(t, ok)
+		require.Equal(t, strings.TrimPrefix(artifact.Layer.Digest, "sha256:"), hash)
+
+		data, err := client.Fetch(ctx, artifact)
+		require.NoError(t, err)
+		require.Equal(t, tarData, data)
+	})
+
+	t.Run("Gzip", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := zw.Write(tarData)
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		registry := ocitest.New(t, "acme/docker", "v1", oci.MediaTypeTemplateLayerGzip, buf.Bytes())
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ref, err := oci.ParseReference(registry.Reference)
+		require.NoError(t, err)
+		client := &oci.Client{HTTPClient: registry.Client()}
+
+		artifact, err := client.Resolve(ctx, ref)
+		require.NoError(t, err)
+		_, ok := artifact.Layer.TarHash()
+		require.False(t, ok)
+
+		data, err := client.Fetch(ctx, artifact)
+		require.NoError(t, err)
+		require.Equal(t, tarData, data)
+	})
+
+	t.Run("BearerAuth", func(t *testing.T) {
+		t.Parallel()
+		registry := ocitest.New(t, "acme/docker", "v1", oci.MediaTypeTemplateLayer, tarData, ocitest.WithBearerAuth())
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ref, err := oci.ParseReference(registry.Reference)
+		require.NoError(t, err)
+		client := &oci.Client{HTTPClient: registry.Client()}
+
+		artifact, err := client.Resolve(ctx, ref)
+		require.NoError(t, err)
+		data, err := client.Fetch(ctx, artifact)
+		require.NoError(t, err)
+		require.Equal(t, tarData, data)
+	})
+
+	t.Run("PinnedDigest", func(t *testing.T) {
+		t.Parallel()
+		registry := ocitest.New(t, "acme/docker", "v1", oci.MediaTypeTemplateLayer, tarData)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := &oci.Client{HTTPClient: registry.Client()}
+
+		ref, err := oci.ParseReference(registry.Reference + "@" + registry.Digest)
+		require.NoError(t, err)
+		artifact, err := client.Resolve(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, registry.Digest, artifact.Digest)
+
+		// Pinned references are fetched by digest, so they ignore the tag.
+		ref.Digest = "sha256:" + strings.Repeat("a", 64)
+		_, err = client.Resolve(ctx, ref)
+		require.ErrorContains(t, err, "status 404")
+	})
+
+	t.Run("LayerDigestMismatch", func(t *testing.T) {
+		t.Parallel()
+		registry := ocitest.New(t, "acme/docker", "v1", oci.MediaTypeTemplateLayer, tarData, ocitest.WithCorruptLayer())
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ref, err := oci.ParseReference(registry.Reference)
+		require.NoError(t, err)
+		client := &oci.Client{HTTPClient: registry.Client()}
+
+		artifact, err := client.Resolve(ctx, ref)
+		require.NoError(t, err)
+		_, err = client.Fetch(ctx, artifact)
+		require.ErrorContains(t, err, "does not match manifest digest")
+	})
+
+	t.Run("NoTarLayer", func(t *testing.T) {
+		t.Parallel()
+		registry := ocitest.New(t, "acme/docker", "v1", "application/vnd.acme.config.v1+json", tarData)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ref, err := oci.ParseReference(registry.Reference)
+		require.NoError(t, err)
+		client := &oci.Client{HTTPClient: registry.Client()}
+
+		_, err = client.Resolve(ctx, ref)
+		require.ErrorContains(t, err, "no tar layer")
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		t.Parallel()
+		registry := ocitest.New(t, "acme/docker", "v1", oci.MediaTypeTemplateLayer, tarData)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ref, err := oci.ParseReference(registry.Reference)
+		require.NoError(t, err)
+		client := &oci.Client{HTTPClient: registry.Client(), MaxSize: int64(len(tarData) - 1)}
+
+		_, err = client.Resolve(ctx, ref)
+		require.ErrorContains(t, err, fmt.Sprintf("exceeds the maximum of %d bytes", len(tarData)-1))
+	})
+}
@@ -0,0 +1,139 @@
+// Package ocitest provides a fake OCI registry for tests.
+package ocitest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/oci"
+)
+
+const token = "anonymous-token"
+
+// Registry is a fake registry that serves a single template artifact.
+type Registry struct {
+	Server *httptest.Server
+	// Reference refers to the artifact by tag.
+	Reference string
+	// Digest is the digest of the manifest of the artifact.
+	Digest string
+	// BlobRequests counts the requests for the layer of the artifact.
+	BlobRequests atomic.Int64
+
+	repository  string
+	tag         string
+	manifest    []byte
+	layer       []byte
+	layerPath   string
+	requireAuth bool
+	corrupt     bool
+}
+
+type Option func(*Registry)
+
+// WithCorruptLayer serves a layer whose content does not match its digest.
+func WithCorruptLayer() Option {
+	return func(r *Registry) {
+		r.corrupt = true
+	}
+}
+
+// WithBearerAuth requires clients to fetch an anonymous bearer token.
+func WithBearerAuth() Option {
+	return func(r *Registry) {
+		r.requireAuth = true
+	}
+}
+
+// New starts a registry that serves the layer as repository:tag. The layer is
+// served with the given media type.
+func New(t testing.TB, repository, tag, mediaType string, layer []byte, opts ...Option) *Registry {
+	t.Helper()
+
+	r := &Registry{
+		repository: repository,
+		tag:        tag,
+		layer:      layer,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	layerDigest := digest(layer)
+	r.layerPath = fmt.Sprintf("/v2/%s/blobs/%s", repository, layerDigest)
+	manifest, err := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     oci.MediaTypeImageManifest,
+		"config": map[string]any{
+			"mediaType": "application/vnd.oci.empty.v1+json",
+			"digest":    digest([]byte("{}")),
+			"size":      2,
+		},
+		"layers": []map[string]any{{
+			"mediaType": mediaType,
+			"digest":    layerDigest,
+			"size":      len(layer),
+		}},
+	})
+	require.NoError(t, err)
+	r.manifest = manifest
+	r.Digest = digest(manifest)
+
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.Server.Close)
+	r.Reference = fmt.Sprintf("%s/%s:%s", r.Server.Listener.Addr().String(), repository, tag)
+	return r
+}
+
+// Client returns an HTTP client that trusts the registry.
+func (r *Registry) Client() *http.Client {
+	return r.Server.Client()
+}
+
+func (r *Registry) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		_ = json.NewEncoder(rw).Encode(map[string]string{"token": token})
+		return
+	}
+	if r.requireAuth && req.Header.Get("Authorization") != "Bearer "+token {
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry",scope="repository:%s:pull"`, req.Host, r.repository))
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	manifestPrefix := fmt.Sprintf("/v2/%s/manifests/", r.repository)
+	switch {
+	case strings.HasPrefix(req.URL.Path, manifestPrefix):
+		ref := strings.TrimPrefix(req.URL.Path, manifestPrefix)
+		if ref != r.tag && ref != r.Digest {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.Header().Set("Content-Type", oci.MediaTypeImageManifest)
+		rw.Header().Set("Docker-Content-Digest", r.Digest)
+		_, _ = rw.Write(r.manifest)
+	case req.URL.Path == r.layerPath:
+		r.BlobRequests.Add(1)
+		rw.Header().Set("Content-Type", "application/octet-stream")
+		if r.corrupt {
+			_, _ = rw.Write(append([]byte("corrupt"), r.layer...))
+			return
+		}
+		_, _ = rw.Write(r.layer)
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
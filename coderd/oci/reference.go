@@ -0,0 +1,104 @@
+package oci
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	dockerHubRegistry    = "docker.io"
+	dockerHubAPIRegistry = "registry-1.docker.io"
+)
+
+var (
+	repositoryRegex = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	tagRegex        = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	digestRegex     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// Reference identifies an artifact in an OCI registry, such as
+// "ghcr.io/acme/templates/docker:v1" or
+// "ghcr.io/acme/templates/docker@sha256:...".
+type Reference struct {
+	Registry   string
+	Repository string
+	// Tag is empty if the reference is pinned to a digest.
+	Tag string
+	// Digest is the digest of the manifest of the artifact. Only sha256
+	// digests are supported.
+	Digest string
+}
+
+// ParseReference parses a reference in the format used by Docker and other
+// OCI clients. References without a registry default to Docker Hub, and
+// references without a tag or digest default to the "latest" tag.
+func ParseReference(s string) (Reference, error) {
+	var ref Reference
+	rest := s
+	if i := strings.Index(rest, "@"); i >= 0 {
+		ref.Digest = rest[i+1:]
+		rest = rest[:i]
+		if !digestRegex.MatchString(ref.Digest) {
+			return Reference{}, xerrors.Errorf("invalid digest %q: only sha256 digests are supported", ref.Digest)
+		}
+	}
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		ref.Tag = rest[i+1:]
+		rest = rest[:i]
+		if !tagRegex.MatchString(ref.Tag) {
+			return Reference{}, xerrors.Errorf("invalid tag %q", ref.Tag)
+		}
+	}
+
+	ref.Registry = dockerHubRegistry
+	ref.Repository = rest
+	if i := strings.Index(rest, "/"); i >= 0 {
+		host := rest[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry = host
+			ref.Repository = rest[i+1:]
+		}
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if !repositoryRegex.MatchString(ref.Repository) {
+		return Reference{}, xerrors.Errorf("invalid repository %q", ref.Repository)
+	}
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// String returns the canonical form of the reference.
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// manifestReference is the digest or tag used to fetch the manifest. The
+// digest takes precedence so that pinned references are immutable.
+func (r Reference) manifestReference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// apiHost is the host that serves the registry API.
+func (r Reference) apiHost() string {
+	if r.Registry == dockerHubRegistry {
+		return dockerHubAPIRegistry
+	}
+	return r.Registry
+}
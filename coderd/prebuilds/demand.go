@@ -0,0 +1,101 @@
+package prebuilds
+
+import (
+	"math"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+const (
+	hoursPerWeek = 7 * 24
+	week         = hoursPerWeek * time.Hour
+)
+
+// DemandForecastOptions controls how the desired number of prebuilt workspaces of each preset is derived
+// from historical demand.
+type DemandForecastOptions struct {
+	// Enabled determines whether the forecast is applied to the desired instance count.
+	// Historical demand is still tracked when disabled so that it can be reported.
+	Enabled bool
+	// Lookback is the period of historical demand considered. It is rounded down to whole weeks, with a minimum
+	// of one week, so that every hour of the week is sampled the same number of times.
+	Lookback time.Duration
+}
+
+// LookbackWeeks returns the number of whole weeks of historical demand considered.
+func (o DemandForecastOptions) LookbackWeeks() int {
+	return max(int(o.Lookback/week), 1)
+}
+
+// DemandSince returns the start of the period of historical demand to fetch.
+func (o DemandForecastOptions) DemandSince(now time.Time) time.Time {
+	return now.Add(-time.Duration(o.LookbackWeeks()) * week).Truncate(time.Hour)
+}
+
+// WithDemand returns a copy of the snapshot which includes the historical demand for prebuilt workspaces.
+// Demand is keyed by template and preset name rather than preset ID so that it carries over between
+// template versions.
+func (s GlobalSnapshot) WithDemand(demand []database.GetPrebuildClaimDemandRow, opts DemandForecastOptions) GlobalSnapshot {
+	s.Demand = demand
+	s.DemandForecast = opts
+	return s
+}
+
+// ClaimHitsAndMisses returns the number of workspaces created from the preset within the lookback period
+// which claimed a prebuilt workspace (hits) and which had to be built from scratch (misses).
+func (p PresetSnapshot) ClaimHitsAndMisses() (hits, misses int64) {
+	for _, row := range p.Demand {
+		hits += row.ClaimedCount
+		misses += row.MissedCount
+	}
+	return hits, misses
+}
+
+// ForecastDesiredInstances returns the number of prebuilt workspaces expected to be claimed from the preset
+// around the provided time, based on the average number of workspaces created from it in the same hour of
+// the week during the lookback period. The following hour is also considered so that the pool is filled
+// before demand rises, given that prebuilds take time to build.
+// The second return value is false if there is no historical demand to base a forecast on.
+func (p PresetSnapshot) ForecastDesiredInstances(at time.Time) (int32, bool) {
+	loc := p.demandLocation()
+
+	var (
+		perSlot [hoursPerWeek]int64
+		total   int64
+	)
+	for _, row := range p.Demand {
+		count := row.ClaimedCount + row.MissedCount
+		perSlot[hourOfWeek(row.Hour.In(loc))] += count
+		total += count
+	}
+	if total == 0 {
+		return 0, false
+	}
+
+	current := hourOfWeek(at.In(loc))
+	next := (current + 1) % hoursPerWeek
+	weeks := float64(p.DemandForecast.LookbackWeeks())
+	forecast := math.Ceil(float64(max(perSlot[current], perSlot[next])) / weeks)
+
+	return int32(min(forecast, math.MaxInt32)), true
+}
+
+// demandLocation returns the location in which hours of the week are counted: the preset's scheduling
+// timezone if it is valid, UTC otherwise.
+func (p PresetSnapshot) demandLocation() *time.Location {
+	if p.Preset.SchedulingTimezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(p.Preset.SchedulingTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// hourOfWeek returns the index of the hour within the week, starting with Monday 00:00.
+func hourOfWeek(t time.Time) int {
+	day := (int(t.Weekday()) + 6) % 7
+	return day*24 + t.Hour()
+}
@@ -22,6 +22,8 @@ type GlobalSnapshot struct {
 	PrebuildsInProgress   []database.CountInProgressPrebuildsRow
 	Backoffs              []database.GetPresetsBackoffRow
 	HardLimitedPresetsMap map[uuid.UUID]database.GetPresetsAtFailureLimitRow
+	Demand                []database.GetPrebuildClaimDemandRow
+	DemandForecast        DemandForecastOptions
 	clock                 quartz.Clock
 	logger                slog.Logger
 }
@@ -90,6 +92,10 @@ func (s GlobalSnapshot) FilterByPreset(presetID uuid.UUID) (*PresetSnapshot, err
 
 	_, isHardLimited := s.HardLimitedPresetsMap[preset.ID]
 
+	demand := slice.Filter(s.Demand, func(row database.GetPrebuildClaimDemandRow) bool {
+		return row.TemplateID == preset.TemplateID && row.PresetName == preset.Name
+	})
+
 	presetSnapshot := NewPresetSnapshot(
 		preset,
 		prebuildSchedules,
@@ -102,6 +108,9 @@ func (s GlobalSnapshot) FilterByPreset(presetID uuid.UUID) (*PresetSnapshot, err
 		s.logger,
 	)
 
+	presetSnapshot.Demand = demand
+	presetSnapshot.DemandForecast = s.DemandForecast
+
 	return &presetSnapshot, nil
 }
 
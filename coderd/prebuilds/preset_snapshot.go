@@ -43,6 +43,7 @@ const (
 // - Expired: prebuilds running and expired due to the preset's TTL
 // - InProgress: prebuilds currently in progress
 // - Backoff: holds failure info to decide if prebuild creation should be backed off
+// - Demand: hourly counts of workspaces created from the preset, used to forecast the desired instance count
 type PresetSnapshot struct {
 	Preset            database.GetTemplatePresetsWithPrebuildsRow
 	PrebuildSchedules []database.TemplateVersionPresetPrebuildSchedule
@@ -51,6 +52,8 @@ type PresetSnapshot struct {
 	InProgress        []database.CountInProgressPrebuildsRow
 	Backoff           *database.GetPresetsBackoffRow
 	IsHardLimited     bool
+	Demand            []database.GetPrebuildClaimDemandRow
+	DemandForecast    DemandForecastOptions
 	clock             quartz.Clock
 	logger            slog.Logger
 }
@@ -130,9 +133,26 @@ func MatchesCron(cronExpression string, at time.Time) (bool, error) {
 }
 
 // CalculateDesiredInstances returns the number of desired instances based on the provided time.
-// If the time matches any defined prebuild schedule, the corresponding number of instances is returned.
-// Otherwise, it falls back to the default number of instances specified in the prebuild configuration.
+// If the time matches any defined prebuild schedule, the corresponding number of instances is configured.
+// Otherwise, the default number of instances specified in the prebuild configuration is configured.
+// If demand forecasting is enabled and there is historical demand for the preset, the forecast is returned
+// instead, capped at the configured number of instances.
 func (p PresetSnapshot) CalculateDesiredInstances(at time.Time) int32 {
+	configured := p.configuredDesiredInstances(at)
+	if !p.DemandForecast.Enabled {
+		return configured
+	}
+
+	forecast, ok := p.ForecastDesiredInstances(at)
+	if !ok {
+		return configured
+	}
+	return min(forecast, configured)
+}
+
+// configuredDesiredInstances returns the number of desired instances configured in the preset for the provided
+// time, taking prebuild schedules into account.
+func (p PresetSnapshot) configuredDesiredInstances(at time.Time) int32 {
 	if len(p.PrebuildSchedules) == 0 {
 		// If no schedules are defined, fall back to the default desired instance count
 		return p.Preset.DesiredInstances.Int32
@@ -1390,6 +1390,132 @@ func TestCalculateDesiredInstances(t *testing.T) {
 	}
 }
 
+func TestDemandForecasting(t *testing.T) {
+	t.Parallel()
+
+	presetOpts := options{
+		templateID:          uuid.New(),
+		templateVersionID:   uuid.New(),
+		presetID:            uuid.New(),
+		presetName:          "my-preset",
+		prebuiltWorkspaceID: uuid.New(),
+		workspaceName:       "prebuilds",
+	}
+	otherPresetOpts := options{
+		templateID:          presetOpts.templateID,
+		templateVersionID:   presetOpts.templateVersionID,
+		presetID:            uuid.New(),
+		presetName:          "other-preset",
+		prebuiltWorkspaceID: uuid.New(),
+		workspaceName:       "other-prebuilds",
+	}
+	presets := []database.GetTemplatePresetsWithPrebuildsRow{
+		preset(true, 5, presetOpts),
+		preset(true, 5, otherPresetOpts),
+	}
+
+	demandRow := func(templateID uuid.UUID, presetName, hour string, claimed, missed int64) database.GetPrebuildClaimDemandRow {
+		return database.GetPrebuildClaimDemandRow{
+			TemplateID:   templateID,
+			PresetName:   presetName,
+			Hour:         mustParseTime(t, time.RFC1123, hour),
+			ClaimedCount: claimed,
+			MissedCount:  missed,
+		}
+	}
+	// Two weeks of demand for my-preset on Mondays at 09:00 and 10:00 UTC.
+	demand := []database.GetPrebuildClaimDemandRow{
+		demandRow(presetOpts.templateID, presetOpts.presetName, "Mon, 02 Jun 2025 09:00:00 UTC", 3, 1),
+		demandRow(presetOpts.templateID, presetOpts.presetName, "Mon, 09 Jun 2025 09:00:00 UTC", 2, 0),
+		demandRow(presetOpts.templateID, presetOpts.presetName, "Mon, 02 Jun 2025 10:00:00 UTC", 4, 10),
+		// A preset with the same name in a different template must not be taken into account.
+		demandRow(uuid.New(), presetOpts.presetName, "Mon, 09 Jun 2025 08:00:00 UTC", 50, 50),
+	}
+
+	testCases := []struct {
+		name              string
+		presetID          uuid.UUID
+		enabled           bool
+		now               time.Time
+		expectedInstances int32
+	}{
+		{
+			name:              "Disabled",
+			presetID:          presetOpts.presetID,
+			enabled:           false,
+			now:               mustParseTime(t, time.RFC1123, "Mon, 16 Jun 2025 08:30:00 UTC"),
+			expectedInstances: 5,
+		},
+		{
+			name:              "No demand in the current or next hour",
+			presetID:          presetOpts.presetID,
+			enabled:           true,
+			now:               mustParseTime(t, time.RFC1123, "Tue, 17 Jun 2025 09:30:00 UTC"),
+			expectedInstances: 0,
+		},
+		{
+			name:              "Demand in the next hour",
+			presetID:          presetOpts.presetID,
+			enabled:           true,
+			now:               mustParseTime(t, time.RFC1123, "Mon, 16 Jun 2025 08:30:00 UTC"),
+			expectedInstances: 3,
+		},
+		{
+			name:              "Forecast capped at the configured instances",
+			presetID:          presetOpts.presetID,
+			enabled:           true,
+			now:               mustParseTime(t, time.RFC1123, "Mon, 16 Jun 2025 10:30:00 UTC"),
+			expectedInstances: 5,
+		},
+		{
+			name:              "No historical demand",
+			presetID:          otherPresetOpts.presetID,
+			enabled:           true,
+			now:               mustParseTime(t, time.RFC1123, "Mon, 16 Jun 2025 08:30:00 UTC"),
+			expectedInstances: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := quartz.NewMock(t)
+			clock.Set(tc.now)
+			snapshot := prebuilds.NewGlobalSnapshot(presets, nil, nil, nil, nil, nil, clock, testutil.Logger(t)).
+				WithDemand(demand, prebuilds.DemandForecastOptions{
+					Enabled:  tc.enabled,
+					Lookback: 2 * 7 * 24 * time.Hour,
+				})
+
+			ps, err := snapshot.FilterByPreset(tc.presetID)
+			require.NoError(t, err)
+
+			state := ps.CalculateState()
+			require.Equal(t, tc.expectedInstances, state.Desired)
+		})
+	}
+
+	t.Run("ClaimHitsAndMisses", func(t *testing.T) {
+		t.Parallel()
+
+		snapshot := prebuilds.NewGlobalSnapshot(presets, nil, nil, nil, nil, nil, quartz.NewMock(t), testutil.Logger(t)).
+			WithDemand(demand, prebuilds.DemandForecastOptions{Lookback: 2 * 7 * 24 * time.Hour})
+
+		ps, err := snapshot.FilterByPreset(presetOpts.presetID)
+		require.NoError(t, err)
+		hits, misses := ps.ClaimHitsAndMisses()
+		require.EqualValues(t, 9, hits)
+		require.EqualValues(t, 11, misses)
+
+		ps, err = snapshot.FilterByPreset(otherPresetOpts.presetID)
+		require.NoError(t, err)
+		hits, misses = ps.ClaimHitsAndMisses()
+		require.Zero(t, hits)
+		require.Zero(t, misses)
+	})
+}
+
 func mustParseTime(t *testing.T, layout, value string) time.Time {
 	t.Helper()
 	parsedTime, err := time.Parse(layout, value)
@@ -252,6 +252,102 @@ func Workspaces(ctx context.Context, logger slog.Logger, registerer prometheus.R
 	}, nil
 }
 
+// workspaceBuildOutcomesDelay is how long after a build completes it is
+// counted. The delay prevents missing builds whose completion is committed
+// after a query that covered their completion time.
+const workspaceBuildOutcomesDelay = 10 * time.Second
+
+// WorkspaceBuildOutcomes tracks the outcome and duration of completed workspace
+// builds by template and transition. Only builds that complete after the
+// metrics are registered are counted.
+func WorkspaceBuildOutcomes(ctx context.Context, logger slog.Logger, clk quartz.Clock, registerer prometheus.Registerer, db database.Store, duration time.Duration) (func(), error) {
+	if duration == 0 {
+		duration = defaultRefreshRate
+	}
+
+	outcomes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "api",
+		Name:      "workspace_build_outcomes_total",
+		Help:      "The number of completed workspace builds by template, transition, and outcome.",
+	}, []string{"template_name", "workspace_transition", "status"})
+	if err := registerer.Register(outcomes); err != nil {
+		return nil, xerrors.Errorf("register workspace_build_outcomes_total counter: %w", err)
+	}
+
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "coderd",
+		Subsystem: "api",
+		Name:      "workspace_build_duration_seconds",
+		Help:      "The time taken by completed workspace builds by template, transition, and outcome.",
+		Buckets: []float64{
+			1, // 1s
+			10,
+			30,
+			60, // 1min
+			60 * 5,
+			60 * 10,
+			60 * 30, // 30min
+			60 * 60, // 1hr
+		},
+	}, []string{"template_name", "workspace_transition", "status"})
+	if err := registerer.Register(durations); err != nil {
+		return nil, xerrors.Errorf("register workspace_build_duration_seconds histogram: %w", err)
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	done := make(chan struct{})
+	completedAfter := clk.Now().Add(-workspaceBuildOutcomesDelay)
+	ticker := clk.NewTicker(duration)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			completedBefore := clk.Now().Add(-workspaceBuildOutcomesDelay)
+			//nolint:gocritic // This is a system service that needs to read
+			// the builds of all workspaces.
+			builds, err := db.GetWorkspaceBuildOutcomesCompletedBetween(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceBuildOutcomesCompletedBetweenParams{
+				CompletedAfter:  completedAfter,
+				CompletedBefore: completedBefore,
+			})
+			if err != nil {
+				logger.Warn(ctx, "failed to load workspace build outcomes", slog.Error(err))
+				continue
+			}
+			completedAfter = completedBefore
+
+			for _, build := range builds {
+				labels := []string{build.TemplateName, string(build.Transition), workspaceBuildOutcome(build)}
+				outcomes.WithLabelValues(labels...).Inc()
+				// Builds canceled before a provisioner picked them up never
+				// started, so they have no duration.
+				if build.StartedAt.Valid {
+					durations.WithLabelValues(labels...).Observe(build.CompletedAt.Time.Sub(build.StartedAt.Time).Seconds())
+				}
+			}
+		}
+	}()
+	return func() {
+		cancelFunc()
+		<-done
+	}, nil
+}
+
+// workspaceBuildOutcome returns the status label of a completed build. Builds
+// terminated by the job reaper are reported separately from other failures.
+func workspaceBuildOutcome(build database.GetWorkspaceBuildOutcomesCompletedBetweenRow) string {
+	if build.ErrorCode.String == string(codersdk.JobReaped) {
+		return "reaped"
+	}
+	return string(build.JobStatus)
+}
+
 // Agents tracks the total number of workspaces with labels on status.
 func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, coordinator *atomic.Pointer[tailnet.Coordinator], derpMapFn func() *tailcfg.DERPMap, agentInactiveDisconnectTimeout, duration time.Duration) (func(), error) {
 	if duration == 0 {
@@ -371,6 +371,122 @@ func TestWorkspaceLatestBuildStatuses(t *testing.T) {
 	}
 }
 
+func TestWorkspaceBuildOutcomes(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db, _ := dbtestutil.NewDB(t)
+	mClock := quartz.NewMock(t)
+	now := dbtime.Time(mClock.Now()).UTC()
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	template := dbgen.Template(t, db, database.Template{
+		Name:            "docker",
+		OrganizationID:  org.ID,
+		CreatedBy:       user.ID,
+		ActiveVersionID: version.ID,
+	})
+	insertBuild := func(transition database.WorkspaceTransition, job database.ProvisionerJob) {
+		t.Helper()
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			OwnerID:        user.ID,
+			TemplateID:     template.ID,
+		})
+		job.OrganizationID = org.ID
+		job.InitiatorID = user.ID
+		job = dbgen.ProvisionerJob(t, db, nil, job)
+		dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       workspace.ID,
+			TemplateVersionID: version.ID,
+			JobID:             job.ID,
+			Transition:        transition,
+		})
+	}
+
+	registry := prometheus.NewRegistry()
+	closeFunc, err := prometheusmetrics.WorkspaceBuildOutcomes(ctx, testutil.Logger(t), mClock, registry, db, time.Minute)
+	require.NoError(t, err)
+	t.Cleanup(closeFunc)
+
+	startedAt := sql.NullTime{Time: now.Add(-2 * time.Minute), Valid: true}
+	completedAt := sql.NullTime{Time: now, Valid: true}
+	// Builds completed before the metrics were registered are not counted.
+	insertBuild(database.WorkspaceTransitionStart, database.ProvisionerJob{
+		StartedAt:   sql.NullTime{Time: now.Add(-2 * time.Hour), Valid: true},
+		CompletedAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+	})
+	insertBuild(database.WorkspaceTransitionStart, database.ProvisionerJob{
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+	})
+	insertBuild(database.WorkspaceTransitionStart, database.ProvisionerJob{
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+	})
+	insertBuild(database.WorkspaceTransitionStart, database.ProvisionerJob{
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+		Error:       sql.NullString{String: "terraform apply failed", Valid: true},
+	})
+	insertBuild(database.WorkspaceTransitionStop, database.ProvisionerJob{
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+		Error:       sql.NullString{String: "Build has been detected as hung for 5 minutes and will be terminated.", Valid: true},
+		ErrorCode:   sql.NullString{String: string(codersdk.JobReaped), Valid: true},
+	})
+	insertBuild(database.WorkspaceTransitionDelete, database.ProvisionerJob{
+		CanceledAt:  completedAt,
+		CompletedAt: completedAt,
+	})
+
+	_, w := mClock.AdvanceNext()
+	w.MustWait(ctx)
+
+	expected := map[string]uint64{
+		"start/succeeded": 2,
+		"start/failed":    1,
+		"stop/reaped":     1,
+		"delete/canceled": 1,
+	}
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		if !assert.NoError(t, err) {
+			return false
+		}
+		outcomes := map[string]uint64{}
+		observed := map[string]uint64{}
+		for _, m := range metrics {
+			for _, metric := range m.Metric {
+				labels := map[string]string{}
+				for _, label := range metric.Label {
+					labels[label.GetName()] = label.GetValue()
+				}
+				assert.Equal(t, template.Name, labels["template_name"])
+				key := labels["workspace_transition"] + "/" + labels["status"]
+				switch m.GetName() {
+				case "coderd_api_workspace_build_outcomes_total":
+					outcomes[key] = uint64(metric.Counter.GetValue())
+				case "coderd_api_workspace_build_duration_seconds":
+					observed[key] = metric.Histogram.GetSampleCount()
+				}
+			}
+		}
+		if !reflect.DeepEqual(expected, outcomes) {
+			t.Logf("outcomes = %v, expected %v", outcomes, expected)
+			return false
+		}
+		// The canceled build never started, so it has no duration.
+		delete(outcomes, "delete/canceled")
+		return reflect.DeepEqual(outcomes, observed)
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func TestAgents(t *testing.T) {
 	t.Parallel()
 
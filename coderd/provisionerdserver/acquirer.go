@@ -148,6 +148,10 @@ func (a *Acquirer) AcquireJob(
 				return database.ProvisionerJob{}, xerrors.Errorf("failed to acquire job: %w", err)
 			}
 			logger.Debug(ctx, "successfully acquired job")
+			err = provisionerjobs.PublishJobStateChange(a.ps, job)
+			if err != nil {
+				logger.Warn(ctx, "failed to publish job state change", slog.Error(err))
+			}
 			return job, nil
 		}
 	}
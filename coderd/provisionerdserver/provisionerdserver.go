@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -19,7 +20,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/sqlc-dev/pqtype"
+	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.14.0"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/maps"
@@ -35,16 +38,19 @@ import (
 
 	"github.com/coder/quartz"
 
+	"github.com/coder/coder/v2/apiversion"
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/promoauth"
+	coderdpubsub "github.com/coder/coder/v2/coderd/pubsub"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
@@ -59,6 +65,10 @@ import (
 
 const (
 	tarMimeType = "application/x-tar"
+
+	// templateTestOwner is the username of the synthetic owner of the
+	// workspaces of template tests.
+	templateTestOwner = "template-test"
 )
 
 const (
@@ -408,6 +418,8 @@ func (s *server) AcquireJobWithCancel(stream proto.DRPCProvisionerDaemon_Acquire
 			})
 		if err != nil {
 			logger.Error(streamCtx, "error updating failed job", slog.Error(err))
+		} else {
+			s.publishJobStateChange(streamCtx, je.job)
 		}
 		return recvErr
 	}
@@ -443,6 +455,7 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		if err != nil {
 			return xerrors.Errorf("update provisioner job: %w", err)
 		}
+		s.publishJobStateChange(ctx, job)
 		return xerrors.Errorf("request job was invalidated: %s", errorMessage)
 	}
 
@@ -458,6 +471,19 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 			return nil, failJob(fmt.Sprintf("unmarshal metadata: %s", err))
 		}
 	}
+	// Continue the trace the job was created in, so acquiring and provisioning
+	// the job show up in the same trace as the request that created it. The
+	// span starts when the job was queued to show how long it was pending.
+	jobCtx, span := s.startTrace(tracing.MetadataToContext(ctx, jobTraceMetadata), tracing.FuncName(),
+		trace.WithTimestamp(job.CreatedAt),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithAttributes(
+			attribute.String("job_id", job.ID.String()),
+			attribute.String("job_type", string(job.Type)),
+		),
+	)
+	defer span.End()
+	maps.Copy(jobTraceMetadata, tracing.MetadataFromContext(jobCtx))
 
 	protoJob := &proto.AcquiredJob{
 		JobId:         job.ID.String(),
@@ -733,6 +759,27 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 			return nil, failJob(fmt.Sprintf("get template version variables: %s", err))
 		}
 
+		if input.WorkspaceBuildID.Valid {
+			dryRun, err := s.acquireDriftCheck(ctx, input.WorkspaceBuildID.UUID, templateVersion)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("acquire drift check: %s", err))
+			}
+			dryRun.VariableValues = asVariableValues(templateVariables)
+			protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
+				TemplateDryRun: dryRun,
+			}
+			break
+		}
+		if input.Test {
+			if !supportsTemplateTests(s.apiVersion) {
+				return nil, failJob(fmt.Sprintf("provisioner daemon API version %s does not support template tests; ensure your provisioner daemon is up-to-date", s.apiVersion))
+			}
+			protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
+				TemplateDryRun: s.acquireTemplateTest(ctx, job, input, templateVersion, templateVariables),
+			}
+			break
+		}
+
 		protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
 			TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
 				RichParameterValues: convertRichParameterValues(input.RichParameterValues),
@@ -862,6 +909,27 @@ func (s *server) CommitQuota(ctx context.Context, request *proto.CommitQuotaRequ
 		return nil, xerrors.New("you don't own this job")
 	}
 
+	if job.Type == database.ProvisionerJobTypeWorkspaceBuild {
+		violations, err := s.resourceCeilingViolations(ctx, job.ID, request)
+		if err != nil {
+			return nil, xerrors.Errorf("check resource ceilings: %w", err)
+		}
+		if len(violations) > 0 {
+			return &proto.CommitQuotaResponse{
+				Budget:                    -1,
+				ResourceCeilingViolations: violations,
+			}, nil
+		}
+	}
+	// Builds that cost nothing are only committed to check their resource
+	// ceilings, and must not be rejected by the quota of their owner.
+	if request.DailyCost == 0 {
+		return &proto.CommitQuotaResponse{
+			Budget: -1,
+			Ok:     true,
+		}, nil
+	}
+
 	q := s.QuotaCommitter.Load()
 	if q == nil {
 		// We're probably in community edition or a test.
@@ -873,6 +941,114 @@ func (s *server) CommitQuota(ctx context.Context, request *proto.CommitQuotaRequ
 	return (*q).CommitQuota(ctx, request)
 }
 
+// resourceCeilingViolations checks the planned resources of a workspace build
+// against the resource ceilings of its template. Only builds that start a
+// workspace are checked, so that lowering a ceiling never prevents stopping a
+// workspace.
+func (s *server) resourceCeilingViolations(ctx context.Context, jobID uuid.UUID, request *proto.CommitQuotaRequest) ([]*proto.ResourceCeilingViolation, error) {
+	build, err := s.Database.GetWorkspaceBuildByJobID(ctx, jobID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace build: %w", err)
+	}
+	if build.Transition != database.WorkspaceTransitionStart {
+		return nil, nil
+	}
+	workspace, err := s.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace: %w", err)
+	}
+	template, err := s.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template: %w", err)
+	}
+	return checkResourceCeilings(template.ResourceCeilings, request.Resources, request.DailyCost), nil
+}
+
+// checkResourceCeilings sums the `cpu`, `memory` and `disk` metadata items of
+// the resources and returns the amounts that exceed the ceilings. Memory and
+// disk are in gibibytes, and metadata values that cannot be parsed are not
+// counted.
+func checkResourceCeilings(ceilings database.TemplateResourceCeilings, resources []*sdkproto.Resource, dailyCost int32) []*proto.ResourceCeilingViolation {
+	var cpu, memory, disk float64
+	for _, resource := range resources {
+		for _, item := range resource.Metadata {
+			if item.IsNull {
+				continue
+			}
+			switch strings.ToLower(item.Key) {
+			case "cpu":
+				if amount, _, ok := parseResourceAmount(item.Value); ok {
+					cpu += amount
+				}
+			case "memory":
+				if gib, ok := parseGiB(item.Value); ok {
+					memory += gib
+				}
+			case "disk":
+				if gib, ok := parseGiB(item.Value); ok {
+					disk += gib
+				}
+			}
+		}
+	}
+
+	var violations []*proto.ResourceCeilingViolation
+	for _, c := range []struct {
+		resource         string
+		planned, ceiling float64
+	}{
+		{"cpu", cpu, ceilings.CPU},
+		{"memory_gib", memory, ceilings.MemoryGiB},
+		{"disk_gib", disk, ceilings.DiskGiB},
+		{"daily_cost", float64(dailyCost), float64(ceilings.DailyCost)},
+	} {
+		if c.ceiling > 0 && c.planned > c.ceiling {
+			violations = append(violations, &proto.ResourceCeilingViolation{
+				Resource: c.resource,
+				Planned:  c.planned,
+				Ceiling:  c.ceiling,
+			})
+		}
+	}
+	return violations
+}
+
+// parseResourceAmount splits a metadata value such as "4 cores" or "512MiB"
+// into its amount and lowercase unit.
+func parseResourceAmount(value string) (amount float64, unit string, ok bool) {
+	value = strings.TrimSpace(value)
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end == -1 {
+		end = len(value)
+	}
+	amount, err := strconv.ParseFloat(value[:end], 64)
+	if err != nil || amount < 0 {
+		return 0, "", false
+	}
+	return amount, strings.ToLower(strings.TrimSpace(value[end:])), true
+}
+
+// parseGiB parses a memory or disk size in gibibytes. Values without a unit
+// are assumed to be in gibibytes, and decimal units are treated like their
+// binary counterparts.
+func parseGiB(value string) (float64, bool) {
+	amount, unit, ok := parseResourceAmount(value)
+	if !ok {
+		return 0, false
+	}
+	switch unit {
+	case "m", "mb", "mi", "mib":
+		return amount / 1024, true
+	case "", "g", "gb", "gi", "gib":
+		return amount, true
+	case "t", "tb", "ti", "tib":
+		return amount * 1024, true
+	}
+	return 0, false
+}
+
 func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error) {
 	ctx, span := s.startTrace(ctx, tracing.FuncName())
 	defer span.End()
@@ -903,51 +1079,29 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 	}
 
 	if len(request.Logs) > 0 {
-		//nolint:exhaustruct // We append to the additional fields below.
-		insertParams := database.InsertProvisionerJobLogsParams{
-			JobID: parsedID,
+		err = s.insertJobLogs(ctx, parsedID, request.Logs)
+		if err != nil {
+			return nil, err
 		}
-		for _, log := range request.Logs {
-			logLevel, err := convertLogLevel(log.Level)
-			if err != nil {
-				return nil, xerrors.Errorf("convert log level: %w", err)
-			}
-			logSource, err := convertLogSource(log.Source)
-			if err != nil {
-				return nil, xerrors.Errorf("convert log source: %w", err)
-			}
-			insertParams.CreatedAt = append(insertParams.CreatedAt, time.UnixMilli(log.CreatedAt))
-			insertParams.Level = append(insertParams.Level, logLevel)
-			insertParams.Stage = append(insertParams.Stage, log.Stage)
-			insertParams.Source = append(insertParams.Source, logSource)
-			insertParams.Output = append(insertParams.Output, log.Output)
-			s.Logger.Debug(ctx, "job log",
-				slog.F("job_id", parsedID),
-				slog.F("stage", log.Stage),
-				slog.F("output", log.Output))
-		}
-
-		logs, err := s.Database.InsertProvisionerJobLogs(ctx, insertParams)
-		if err != nil {
-			s.Logger.Error(ctx, "failed to insert job logs", slog.F("job_id", parsedID), slog.Error(err))
-			return nil, xerrors.Errorf("insert job logs: %w", err)
-		}
-		// Publish by the lowest log ID inserted so the log stream will fetch
-		// everything from that point.
-		lowestID := logs[0].ID
-		s.Logger.Debug(ctx, "inserted job logs", slog.F("job_id", parsedID))
-		data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{
-			CreatedAfter: lowestID - 1,
-		})
+	}
+
+	if len(request.InterimState) > 0 {
+		if job.Type != database.ProvisionerJobTypeWorkspaceBuild {
+			return nil, xerrors.Errorf("interim state is only accepted for workspace builds, got %q", job.Type)
+		}
+		var input WorkspaceProvisionJob
+		err = json.Unmarshal(job.Input, &input)
 		if err != nil {
-			return nil, xerrors.Errorf("marshal: %w", err)
+			return nil, xerrors.Errorf("unmarshal workspace provision input: %w", err)
 		}
-		err = s.Pubsub.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(parsedID), data)
+		err = s.Database.UpsertWorkspaceBuildInterimState(ctx, database.UpsertWorkspaceBuildInterimStateParams{
+			WorkspaceBuildID: input.WorkspaceBuildID,
+			State:            request.InterimState,
+			UpdatedAt:        s.timeNow(),
+		})
 		if err != nil {
-			s.Logger.Error(ctx, "failed to publish job logs", slog.F("job_id", parsedID), slog.Error(err))
-			return nil, xerrors.Errorf("publish job logs: %w", err)
+			return nil, xerrors.Errorf("update workspace build interim state: %w", err)
 		}
-		s.Logger.Debug(ctx, "published job logs", slog.F("job_id", parsedID))
 	}
 
 	if len(request.WorkspaceTags) > 0 {
@@ -1040,6 +1194,19 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 	}, nil
 }
 
+// logCancelAcknowledged logs how long the provisioner took to finish a job
+// after it was canceled. Jobs that take longer than the cancel deadline are
+// terminated by the job reaper instead.
+func (s *server) logCancelAcknowledged(ctx context.Context, job database.ProvisionerJob) {
+	if !job.CanceledAt.Valid {
+		return
+	}
+	s.Logger.Info(ctx, "provisioner acknowledged job cancellation",
+		slog.F("job_id", job.ID),
+		slog.F("duration", s.timeNow().Sub(job.CanceledAt.Time)),
+	)
+}
+
 func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.Empty, error) {
 	ctx, span := s.startTrace(ctx, tracing.FuncName())
 	defer span.End()
@@ -1061,6 +1228,7 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 	if job.CompletedAt.Valid {
 		return nil, xerrors.Errorf("job already completed")
 	}
+	s.logCancelAcknowledged(ctx, job)
 	job.CompletedAt = sql.NullTime{
 		Time:  s.timeNow(),
 		Valid: true,
@@ -1118,6 +1286,11 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 				if err != nil {
 					return xerrors.Errorf("update workspace build state: %w", err)
 				}
+				// The final state supersedes the state uploaded during the apply.
+				err = db.DeleteWorkspaceBuildInterimState(ctx, input.WorkspaceBuildID)
+				if err != nil {
+					return xerrors.Errorf("delete workspace build interim state: %w", err)
+				}
 				err = db.UpdateWorkspaceBuildDeadlineByID(ctx, database.UpdateWorkspaceBuildDeadlineByIDParams{
 					ID:          input.WorkspaceBuildID,
 					UpdatedAt:   s.timeNow(),
@@ -1175,10 +1348,11 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 				// We pass the below information to the Auditor so that it
 				// can form a friendly string for the user to view in the UI.
 				buildResourceInfo := audit.AdditionalFields{
-					WorkspaceName: workspace.Name,
-					BuildNumber:   strconv.FormatInt(int64(build.BuildNumber), 10),
-					BuildReason:   database.BuildReason(string(build.Reason)),
-					WorkspaceID:   workspace.ID,
+					WorkspaceName:         workspace.Name,
+					BuildNumber:           strconv.FormatInt(int64(build.BuildNumber), 10),
+					BuildReason:           database.BuildReason(string(build.Reason)),
+					WorkspaceID:           workspace.ID,
+					BuildInitiatorContext: &build.InitiatorContext,
 				}
 
 				wriBytes, err := json.Marshal(buildResourceInfo)
@@ -1206,7 +1380,9 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		}
 	}
 
-	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
+	s.publishJobStateChange(ctx, job)
+
+	data, err := coderdpubsub.ProvisionerJobLogsNotify.Encode(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
 	if err != nil {
 		return nil, xerrors.Errorf("marshal job log: %w", err)
 	}
@@ -1218,6 +1394,15 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 	return &proto.Empty{}, nil
 }
 
+// publishJobStateChange notifies watchers of the provisioner jobs of the
+// organization that the job was completed.
+func (s *server) publishJobStateChange(ctx context.Context, job database.ProvisionerJob) {
+	err := provisionerjobs.PublishJobStateChange(s.Pubsub, job)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to publish job state change", slog.F("job_id", job.ID), slog.Error(err))
+	}
+}
+
 func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild) {
 	var reason string
 	if build.Reason.Valid() && build.Reason == database.BuildReasonInitiator {
@@ -1322,6 +1507,200 @@ func (s *server) prepareForNotifyWorkspaceManualBuildFailed(ctx context.Context,
 	return templateAdmins, template, templateVersion, workspaceOwner, nil
 }
 
+const (
+	// streamJobLogsFlushInterval is how long StreamJobLogs coalesces logs
+	// before inserting them.
+	streamJobLogsFlushInterval = 250 * time.Millisecond
+	// streamJobLogsMaxBatchLogs and streamJobLogsMaxBatchBytes limit the size
+	// of a batched insert of StreamJobLogs.
+	streamJobLogsMaxBatchLogs  = 1000
+	streamJobLogsMaxBatchBytes = 1 << 20
+	// streamJobLogsSlowInsert is how long inserting a batch may take before
+	// the daemon is asked to back off for as long as the insert took.
+	streamJobLogsSlowInsert = time.Second
+)
+
+// StreamJobLogs inserts the logs of a job in batches. A batch is inserted
+// when it is full, when its oldest log has been buffered for
+// streamJobLogsFlushInterval, and when the daemon closes the stream.
+func (s *server) StreamJobLogs(stream proto.DRPCProvisionerDaemon_StreamJobLogsStream) error {
+	ctx, span := s.startTrace(stream.Context(), tracing.FuncName())
+	defer span.End()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	//nolint:gocritic // Provisionerd has specific authz rules.
+	ctx = dbauthz.AsProvisionerd(ctx)
+
+	// Receive on a separate goroutine so the flush timer can fire while we
+	// wait for logs. Nothing is received while a batch is inserted, which
+	// pushes back on the daemon.
+	requests := make(chan *proto.StreamJobLogsRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			request, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- request:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		jobID      uuid.UUID
+		batch      []*proto.Log
+		batchBytes int
+		inserted   int64
+		flushTimer *quartz.Timer
+		flushC     <-chan time.Time
+	)
+	flush := func() error {
+		if flushTimer != nil {
+			flushTimer.Stop()
+			flushTimer, flushC = nil, nil
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		start := s.Clock.Now()
+		err := s.insertJobLogs(ctx, jobID, batch)
+		if err != nil {
+			return err
+		}
+		took := s.Clock.Since(start)
+		inserted += int64(len(batch))
+		batch, batchBytes = nil, 0
+
+		response := &proto.StreamJobLogsResponse{InsertedLogs: inserted}
+		if took > streamJobLogsSlowInsert {
+			response.BackoffMs = took.Milliseconds()
+		}
+		err = stream.Send(response)
+		if err != nil {
+			return xerrors.Errorf("send response: %w", err)
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-recvErr:
+			if !errors.Is(err, io.EOF) {
+				return xerrors.Errorf("receive job logs: %w", err)
+			}
+			// The daemon closed the stream, so insert what's left.
+			err = flush()
+			if err != nil {
+				return xerrors.Errorf("flush job logs: %w", err)
+			}
+			return nil
+		case <-flushC:
+			err := flush()
+			if err != nil {
+				return xerrors.Errorf("flush job logs: %w", err)
+			}
+		case request := <-requests:
+			if jobID == uuid.Nil {
+				id, err := uuid.Parse(request.JobId)
+				if err != nil {
+					return xerrors.Errorf("parse job id: %w", err)
+				}
+				job, err := s.Database.GetProvisionerJobByID(ctx, id)
+				if err != nil {
+					return xerrors.Errorf("get job: %w", err)
+				}
+				if !job.WorkerID.Valid {
+					return xerrors.New("job isn't running yet")
+				}
+				if job.WorkerID.UUID.String() != s.ID.String() {
+					return xerrors.New("you don't own this job")
+				}
+				jobID = id
+				s.Logger.Debug(ctx, "stage StreamJobLogs starting", slog.F("job_id", jobID))
+			} else if request.JobId != jobID.String() {
+				return xerrors.Errorf("stream carries logs of job %s, not %s", jobID, request.JobId)
+			}
+
+			for _, log := range request.Logs {
+				batch = append(batch, log)
+				batchBytes += len(log.Output)
+			}
+			if len(batch) >= streamJobLogsMaxBatchLogs || batchBytes >= streamJobLogsMaxBatchBytes {
+				err := flush()
+				if err != nil {
+					return xerrors.Errorf("flush job logs: %w", err)
+				}
+			} else if len(batch) > 0 && flushTimer == nil {
+				flushTimer = s.Clock.NewTimer(streamJobLogsFlushInterval, "StreamJobLogs", "flush")
+				flushC = flushTimer.C
+			}
+		}
+	}
+}
+
+// insertJobLogs inserts logs of a job and notifies log followers about them.
+func (s *server) insertJobLogs(ctx context.Context, jobID uuid.UUID, logs []*proto.Log) error {
+	//nolint:exhaustruct // We append to the additional fields below.
+	insertParams := database.InsertProvisionerJobLogsParams{
+		JobID: jobID,
+	}
+	for _, log := range logs {
+		logLevel, err := convertLogLevel(log.Level)
+		if err != nil {
+			return xerrors.Errorf("convert log level: %w", err)
+		}
+		logSource, err := convertLogSource(log.Source)
+		if err != nil {
+			return xerrors.Errorf("convert log source: %w", err)
+		}
+		logFields, err := convertLogFields(log.Fields)
+		if err != nil {
+			return xerrors.Errorf("convert log fields: %w", err)
+		}
+		insertParams.CreatedAt = append(insertParams.CreatedAt, time.UnixMilli(log.CreatedAt))
+		insertParams.Level = append(insertParams.Level, logLevel)
+		insertParams.Stage = append(insertParams.Stage, log.Stage)
+		insertParams.Source = append(insertParams.Source, logSource)
+		insertParams.Output = append(insertParams.Output, log.Output)
+		insertParams.Fields = append(insertParams.Fields, logFields)
+		s.Logger.Debug(ctx, "job log",
+			slog.F("job_id", jobID),
+			slog.F("stage", log.Stage),
+			slog.F("output", log.Output))
+	}
+
+	inserted, err := s.Database.InsertProvisionerJobLogs(ctx, insertParams)
+	if err != nil {
+		s.Logger.Error(ctx, "failed to insert job logs", slog.F("job_id", jobID), slog.Error(err))
+		return xerrors.Errorf("insert job logs: %w", err)
+	}
+	// Publish by the lowest log ID inserted so the log stream will fetch
+	// everything from that point.
+	lowestID := inserted[0].ID
+	s.Logger.Debug(ctx, "inserted job logs", slog.F("job_id", jobID))
+	data, err := coderdpubsub.ProvisionerJobLogsNotify.Encode(provisionersdk.ProvisionerJobLogsNotifyMessage{
+		CreatedAfter: lowestID - 1,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal: %w", err)
+	}
+	err = s.Pubsub.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(jobID), data)
+	if err != nil {
+		s.Logger.Error(ctx, "failed to publish job logs", slog.F("job_id", jobID), slog.Error(err))
+		return xerrors.Errorf("publish job logs: %w", err)
+	}
+	s.Logger.Debug(ctx, "published job logs", slog.F("job_id", jobID))
+	return nil
+}
+
 func (s *server) UploadFile(stream proto.DRPCProvisionerDaemon_UploadFileStream) error {
 	var file *sdkproto.DataBuilder
 	// Always terminate the stream with an empty response.
@@ -1439,6 +1818,7 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 	if job.WorkerID.UUID.String() != s.ID.String() {
 		return nil, xerrors.Errorf("you don't own this job")
 	}
+	s.logCancelAcknowledged(ctx, job)
 
 	telemetrySnapshot := &telemetry.Snapshot{}
 	// Items are added to this snapshot as they complete!
@@ -1468,7 +1848,9 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			reflect.TypeOf(completed.Type).String())
 	}
 
-	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
+	s.publishJobStateChange(ctx, job)
+
+	data, err := coderdpubsub.ProvisionerJobLogsNotify.Encode(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
 	if err != nil {
 		return nil, xerrors.Errorf("marshal job log: %w", err)
 	}
@@ -1849,6 +2231,11 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 		if err != nil {
 			return xerrors.Errorf("update workspace build provisioner state: %w", err)
 		}
+		// The final state supersedes the state uploaded during the apply.
+		err = db.DeleteWorkspaceBuildInterimState(ctx, workspaceBuild.ID)
+		if err != nil {
+			return xerrors.Errorf("delete workspace build interim state: %w", err)
+		}
 		err = db.UpdateWorkspaceBuildDeadlineByID(ctx, database.UpdateWorkspaceBuildDeadlineByIDParams{
 			ID:          workspaceBuild.ID,
 			Deadline:    autoStop.Deadline,
@@ -2026,12 +2413,35 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 	// Post-transaction operations (operations that do not require transactions or
 	// are external to the database, like audit logging, notifications, etc.)
 
+	// Remember that this daemon built the workspace, so the next build of the
+	// same template version prefers it while its provider cache is warm. The
+	// affinity is only a hint, so failing to record it doesn't fail the job.
+	if workspaceBuild.Transition != database.WorkspaceTransitionDelete {
+		err = s.Database.UpsertWorkspaceProvisionerAffinity(ctx, database.UpsertWorkspaceProvisionerAffinityParams{
+			WorkspaceID:         workspaceBuild.WorkspaceID,
+			ProvisionerDaemonID: s.ID,
+			TemplateVersionID:   workspaceBuild.TemplateVersionID,
+			UpdatedAt:           s.timeNow(),
+		})
+		if err != nil {
+			s.Logger.Warn(ctx, "failed to record workspace provisioner affinity",
+				slog.F("workspace_id", workspaceBuild.WorkspaceID),
+				slog.Error(err),
+			)
+		}
+	}
+
 	// audit the outcome of the workspace build
 	if getWorkspaceError == nil {
 		// If the workspace has been deleted, notify the owner about it.
 		if workspaceBuild.Transition == database.WorkspaceTransitionDelete {
 			s.notifyWorkspaceDeleted(ctx, workspace, workspaceBuild)
 		}
+		// If the workspace was started with an outdated template version,
+		// suggest that the owner updates it.
+		if workspaceBuild.Transition == database.WorkspaceTransitionStart && len(workspaceBuild.Warnings) > 0 && !workspace.IsPrebuild() {
+			s.notifyWorkspaceOutdatedBuild(ctx, workspace, workspaceBuild)
+		}
 
 		auditor := s.Auditor.Load()
 		auditAction := auditActionFromTransition(workspaceBuild.Transition)
@@ -2048,10 +2458,11 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 		// We pass the below information to the Auditor so that it
 		// can form a friendly string for the user to view in the UI.
 		buildResourceInfo := audit.AdditionalFields{
-			WorkspaceName: workspace.Name,
-			BuildNumber:   strconv.FormatInt(int64(workspaceBuild.BuildNumber), 10),
-			BuildReason:   database.BuildReason(string(workspaceBuild.Reason)),
-			WorkspaceID:   workspace.ID,
+			WorkspaceName:         workspace.Name,
+			BuildNumber:           strconv.FormatInt(int64(workspaceBuild.BuildNumber), 10),
+			BuildReason:           database.BuildReason(string(workspaceBuild.Reason)),
+			WorkspaceID:           workspace.ID,
+			BuildInitiatorContext: &workspaceBuild.InitiatorContext,
 		}
 
 		wriBytes, err := json.Marshal(buildResourceInfo)
@@ -2116,8 +2527,15 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 // completeTemplateDryRunJob handles completion of a template dry-run job.
 // All database operations are performed within a transaction.
 func (s *server) completeTemplateDryRunJob(ctx context.Context, job database.ProvisionerJob, jobID uuid.UUID, jobType *proto.CompletedJob_TemplateDryRun_, telemetrySnapshot *telemetry.Snapshot) error {
+	var input TemplateVersionDryRunJob
+	err := json.Unmarshal(job.Input, &input)
+	if err != nil {
+		return xerrors.Errorf("unmarshal job input %q: %w", job.Input, err)
+	}
+
+	var driftCheck database.WorkspaceDriftCheck
 	// Execute all database operations in a transaction
-	return s.Database.InTx(func(db database.Store) error {
+	err = s.Database.InTx(func(db database.Store) error {
 		now := s.timeNow()
 
 		// Process resources
@@ -2161,8 +2579,244 @@ func (s *server) completeTemplateDryRunJob(ctx context.Context, job database.Pro
 		}
 		s.Logger.Debug(ctx, "marked template dry-run job as completed", slog.F("job_id", jobID))
 
+		if input.WorkspaceBuildID.Valid {
+			driftCheck, err = completeDriftCheck(ctx, db, jobID, jobType.TemplateDryRun.Plan, now)
+			if err != nil {
+				return xerrors.Errorf("complete drift check: %w", err)
+			}
+		}
+
 		return nil
 	}, nil) // End of transaction
+	if err != nil {
+		return err
+	}
+
+	if len(driftCheck.DriftedResources) > 0 || len(driftCheck.OrphanedResources) > 0 {
+		s.notifyWorkspaceDriftDetected(ctx, driftCheck)
+	}
+	return nil
+}
+
+// acquireDriftCheck returns the dry-run of a drift check, which plans the
+// workspace build against its state with the workspace and parameters of the
+// build. No session token is passed since it would have to be regenerated.
+func (s *server) acquireDriftCheck(ctx context.Context, workspaceBuildID uuid.UUID, templateVersion database.TemplateVersion) (*proto.AcquiredJob_TemplateDryRun, error) {
+	workspaceBuild, err := s.Database.GetWorkspaceBuildByID(ctx, workspaceBuildID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace build: %w", err)
+	}
+	workspace, err := s.Database.GetWorkspaceByID(ctx, workspaceBuild.WorkspaceID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace: %w", err)
+	}
+	template, err := s.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template: %w", err)
+	}
+	owner, err := s.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		return nil, xerrors.Errorf("get owner: %w", err)
+	}
+	ownerGroups, err := s.Database.GetGroups(ctx, database.GetGroupsParams{
+		HasMemberID:    owner.ID,
+		OrganizationID: s.OrganizationID,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get owner group names: %w", err)
+	}
+	ownerGroupNames := []string{}
+	for _, group := range ownerGroups {
+		ownerGroupNames = append(ownerGroupNames, group.Group.Name)
+	}
+	workspaceBuildParameters, err := s.Database.GetWorkspaceBuildParameters(ctx, workspaceBuild.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace build parameters: %w", err)
+	}
+	transition, err := convertWorkspaceTransition(workspaceBuild.Transition)
+	if err != nil {
+		return nil, xerrors.Errorf("convert workspace transition: %w", err)
+	}
+
+	return &proto.AcquiredJob_TemplateDryRun{
+		RichParameterValues: convertRichParameterValues(workspaceBuildParameters),
+		State:               workspaceBuild.ProvisionerState,
+		Metadata: &sdkproto.Metadata{
+			CoderUrl:                s.AccessURL.String(),
+			WorkspaceTransition:     transition,
+			WorkspaceName:           workspace.Name,
+			WorkspaceOwner:          owner.Username,
+			WorkspaceOwnerEmail:     owner.Email,
+			WorkspaceOwnerName:      owner.Name,
+			WorkspaceOwnerGroups:    ownerGroupNames,
+			WorkspaceId:             workspace.ID.String(),
+			WorkspaceOwnerId:        owner.ID.String(),
+			TemplateId:              template.ID.String(),
+			TemplateName:            template.Name,
+			TemplateVersion:         templateVersion.Name,
+			WorkspaceBuildId:        workspaceBuild.ID.String(),
+			WorkspaceOwnerLoginType: string(owner.LoginType),
+		},
+	}, nil
+}
+
+// acquireTemplateTest returns the dry-run of a template test. Tests build a
+// workspace with synthetic owner data, and names derived from the job ID so
+// that their resources do not collide with real workspaces. No session token
+// is passed, so agents of the test workspace cannot connect.
+func (s *server) acquireTemplateTest(ctx context.Context, job database.ProvisionerJob, input TemplateVersionDryRunJob, templateVersion database.TemplateVersion, templateVariables []database.TemplateVersionVariable) *proto.AcquiredJob_TemplateDryRun {
+	metadata := &sdkproto.Metadata{
+		CoderUrl:             s.AccessURL.String(),
+		WorkspaceTransition:  sdkproto.WorkspaceTransition_START,
+		WorkspaceName:        "test-" + strings.Split(job.ID.String(), "-")[0],
+		WorkspaceId:          job.ID.String(),
+		WorkspaceBuildId:     uuid.NewString(),
+		WorkspaceOwner:       templateTestOwner,
+		WorkspaceOwnerName:   "Template Test",
+		WorkspaceOwnerEmail:  templateTestOwner + "@coder.invalid",
+		WorkspaceOwnerId:     uuid.NewString(),
+		WorkspaceOwnerGroups: []string{database.EveryoneGroup},
+		TemplateVersion:      templateVersion.Name,
+	}
+	if templateVersion.TemplateID.Valid {
+		template, err := s.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+		if err == nil {
+			metadata.TemplateId = template.ID.String()
+			metadata.TemplateName = template.Name
+		}
+	}
+	return &proto.AcquiredJob_TemplateDryRun{
+		RichParameterValues: convertRichParameterValues(input.RichParameterValues),
+		VariableValues:      asVariableValues(templateVariables),
+		Metadata:            metadata,
+		Test:                true,
+	}
+}
+
+// supportsTemplateTests returns whether a provisioner daemon with the API
+// version applies and destroys template tests. Older daemons would only plan
+// them, and report a test that did not run as passed.
+func supportsTemplateTests(apiVersion string) bool {
+	major, minor, err := apiversion.Parse(apiVersion)
+	if err != nil {
+		return false
+	}
+	return major > 1 || (major == 1 && minor >= 13)
+}
+
+// completeDriftCheck stores the resources that drifted or were orphaned
+// according to the plan of a drift check. A check that was replaced by a
+// newer one is ignored.
+func completeDriftCheck(ctx context.Context, db database.Store, jobID uuid.UUID, plan []byte, now time.Time) (database.WorkspaceDriftCheck, error) {
+	drifted, orphaned, err := driftFromPlan(plan)
+	if err != nil {
+		return database.WorkspaceDriftCheck{}, err
+	}
+	check, err := db.UpdateWorkspaceDriftCheckByJobID(ctx, database.UpdateWorkspaceDriftCheckByJobIDParams{
+		JobID:             jobID,
+		CompletedAt:       sql.NullTime{Time: now, Valid: true},
+		DriftedResources:  drifted,
+		OrphanedResources: orphaned,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.WorkspaceDriftCheck{}, nil
+	}
+	if err != nil {
+		return database.WorkspaceDriftCheck{}, xerrors.Errorf("update drift check: %w", err)
+	}
+	return check, nil
+}
+
+// driftFromPlan returns the addresses of the resources that were changed
+// outside of Terraform, and of the resources that are in the state but would
+// be deleted by the plan. Resources of the Coder provider are skipped, since
+// they do not exist outside of Coder.
+func driftFromPlan(plan []byte) (drifted []string, orphaned []string, err error) {
+	drifted, orphaned = []string{}, []string{}
+	if len(plan) == 0 {
+		return drifted, orphaned, nil
+	}
+	var parsed tfjson.Plan
+	if err := json.Unmarshal(plan, &parsed); err != nil {
+		return nil, nil, xerrors.Errorf("unmarshal plan: %w", err)
+	}
+
+	isCloudResource := func(change *tfjson.ResourceChange) bool {
+		return change.Mode == tfjson.ManagedResourceMode && !strings.HasPrefix(change.Type, "coder_")
+	}
+	for _, change := range parsed.ResourceDrift {
+		if !isCloudResource(change) {
+			continue
+		}
+		drifted = append(drifted, change.Address)
+	}
+	for _, change := range parsed.ResourceChanges {
+		if !isCloudResource(change) || change.Change == nil || !change.Change.Actions.Delete() {
+			continue
+		}
+		orphaned = append(orphaned, change.Address)
+	}
+	slices.Sort(drifted)
+	slices.Sort(orphaned)
+	return slices.Compact(drifted), slices.Compact(orphaned), nil
+}
+
+func (s *server) notifyWorkspaceDriftDetected(ctx context.Context, check database.WorkspaceDriftCheck) {
+	workspace, err := s.Database.GetWorkspaceByID(ctx, check.WorkspaceID)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to get workspace for drift notification", slog.F("workspace_id", check.WorkspaceID), slog.Error(err))
+		return
+	}
+
+	if _, err := s.NotificationsEnqueuer.EnqueueWithData(ctx, workspace.OwnerID, notifications.TemplateWorkspaceDriftDetected,
+		map[string]string{
+			"name": workspace.Name,
+		},
+		map[string]any{
+			"drifted":  check.DriftedResources,
+			"orphaned": check.OrphanedResources,
+		}, "provisionerdserver",
+		// Associate this notification with all the related entities.
+		workspace.ID, workspace.OwnerID, workspace.TemplateID, workspace.OrganizationID,
+	); err != nil {
+		s.Logger.Warn(ctx, "failed to notify of workspace drift", slog.Error(err))
+	}
+}
+
+func (s *server) notifyWorkspaceOutdatedBuild(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild) {
+	template, err := s.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to get template for outdated build notification", slog.F("template_id", workspace.TemplateID), slog.Error(err))
+		return
+	}
+	templateVersion, err := s.Database.GetTemplateVersionByID(ctx, build.TemplateVersionID)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to get template version for outdated build notification", slog.F("template_version_id", build.TemplateVersionID), slog.Error(err))
+		return
+	}
+	templateNameLabel := template.DisplayName
+	if templateNameLabel == "" {
+		templateNameLabel = template.Name
+	}
+	messages := make([]string, 0, len(build.Warnings))
+	for _, warning := range build.Warnings {
+		messages = append(messages, warning.Message)
+	}
+
+	if _, err := s.NotificationsEnqueuer.EnqueueWithData(ctx, workspace.OwnerID, notifications.TemplateWorkspaceOutdatedBuild,
+		map[string]string{
+			"name":                  workspace.Name,
+			"template_name":         templateNameLabel,
+			"template_version_name": templateVersion.Name,
+		},
+		map[string]any{
+			"warnings": messages,
+		}, "provisionerdserver",
+		// Associate this notification with all the related entities.
+		workspace.ID, workspace.OwnerID, workspace.TemplateID, workspace.OrganizationID,
+	); err != nil {
+		s.Logger.Warn(ctx, "failed to notify of outdated workspace build", slog.Error(err))
+	}
 }
 
 func (s *server) notifyWorkspaceDeleted(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild) {
@@ -2334,6 +2988,10 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			// empty string is root module
 			Valid: true,
 		},
+		DisplayGroup: protoResource.DisplayGroup,
+		// #nosec G115 - Order represents a display order value that's always small and fits in int32
+		DisplayOrder: int32(protoResource.Order),
+		Collapsed:    protoResource.Collapsed,
 	})
 	if err != nil {
 		return xerrors.Errorf("insert provisioner job resource %q: %w", protoResource.Name, err)
@@ -2433,6 +3091,8 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			// #nosec G115 - Order represents a display order value that's always small and fits in int32
 			DisplayOrder: int32(prAgent.Order),
 			APIKeyScope:  apiKeyScope,
+			DisplayGroup: prAgent.GetDisplayGroup(),
+			Collapsed:    prAgent.GetCollapsed(),
 		})
 		if err != nil {
 			return xerrors.Errorf("insert agent: %w", err)
@@ -2500,6 +3160,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 		scriptStartBlocksLogin := make([]bool, 0, len(prAgent.Scripts))
 		scriptRunOnStart := make([]bool, 0, len(prAgent.Scripts))
 		scriptRunOnStop := make([]bool, 0, len(prAgent.Scripts))
+		scriptRunOnClaim := make([]bool, 0, len(prAgent.Scripts))
 
 		for _, script := range prAgent.Scripts {
 			logSourceIDs = append(logSourceIDs, uuid.New())
@@ -2514,6 +3175,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			scriptStartBlocksLogin = append(scriptStartBlocksLogin, script.StartBlocksLogin)
 			scriptRunOnStart = append(scriptRunOnStart, script.RunOnStart)
 			scriptRunOnStop = append(scriptRunOnStop, script.RunOnStop)
+			scriptRunOnClaim = append(scriptRunOnClaim, script.RunOnClaim)
 		}
 
 		// Dev Containers require a script and log/source, so we do this before
@@ -2550,6 +3212,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 				// been enabled.
 				scriptRunOnStart = append(scriptRunOnStart, true)
 				scriptRunOnStop = append(scriptRunOnStop, false)
+				scriptRunOnClaim = append(scriptRunOnClaim, false)
 			}
 
 			_, err = db.InsertWorkspaceAgentDevcontainers(ctx, database.InsertWorkspaceAgentDevcontainersParams{
@@ -2587,6 +3250,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			StartBlocksLogin: scriptStartBlocksLogin,
 			RunOnStart:       scriptRunOnStart,
 			RunOnStop:        scriptRunOnStop,
+			RunOnClaim:       scriptRunOnClaim,
 			DisplayName:      scriptDisplayName,
 			ID:               scriptIDs,
 		})
@@ -2842,6 +3506,20 @@ func convertLogSource(logSource proto.LogSource) (database.LogSource, error) {
 	}
 }
 
+// convertLogFields converts structured log fields to the JSON object stored
+// alongside the log output.
+func convertLogFields(logFields *sdkproto.LogFields) (string, error) {
+	data, err := json.Marshal(codersdk.ProvisionerJobLogFields{
+		ResourceAddress:    logFields.GetResourceAddress(),
+		Action:             logFields.GetAction(),
+		DiagnosticSeverity: logFields.GetDiagnosticSeverity(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 func convertRichParameterValues(workspaceBuildParameters []database.WorkspaceBuildParameter) []*sdkproto.RichParameterValue {
 	protoParameters := make([]*sdkproto.RichParameterValue, len(workspaceBuildParameters))
 	for i, buildParameter := range workspaceBuildParameters {
@@ -2909,6 +3587,13 @@ type TemplateVersionDryRunJob struct {
 	TemplateVersionID   uuid.UUID                          `json:"template_version_id"`
 	WorkspaceName       string                             `json:"workspace_name"`
 	RichParameterValues []database.WorkspaceBuildParameter `json:"rich_parameter_values"`
+	// WorkspaceBuildID is set when the dry-run checks the workspace build
+	// for drift. The build is planned against its state, with the workspace
+	// and parameters of the build.
+	WorkspaceBuildID uuid.NullUUID `json:"workspace_build_id"`
+	// Test is set when the dry-run tests the template version by applying it
+	// and destroying the resources again.
+	Test bool `json:"test,omitempty"`
 }
 
 func asVariableValues(templateVariables []database.TemplateVersionVariable) []*sdkproto.VariableValue {
@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -71,3 +72,91 @@ func TestObtainOIDCAccessToken(t *testing.T) {
 		require.Equal(t, "token", link.OAuthAccessToken)
 	})
 }
+
+func TestDriftFromPlan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		drifted, orphaned, err := driftFromPlan(nil)
+		require.NoError(t, err)
+		require.Empty(t, drifted)
+		require.Empty(t, orphaned)
+	})
+
+	t.Run("DriftedAndOrphaned", func(t *testing.T) {
+		t.Parallel()
+		plan := []byte(`{
+			"format_version": "1.2",
+			"resource_drift": [
+				{"address": "docker_network.private", "mode": "managed", "type": "docker_network", "change": {"actions": ["update"]}},
+				{"address": "coder_agent.main", "mode": "managed", "type": "coder_agent", "change": {"actions": ["update"]}}
+			],
+			"resource_changes": [
+				{"address": "docker_volume.home_volume", "mode": "managed", "type": "docker_volume", "change": {"actions": ["delete"]}},
+				{"address": "docker_container.workspace[0]", "mode": "managed", "type": "docker_container", "change": {"actions": ["create"]}},
+				{"address": "data.coder_workspace.me", "mode": "data", "type": "coder_workspace", "change": {"actions": ["delete"]}},
+				{"address": "coder_app.code", "mode": "managed", "type": "coder_app", "change": {"actions": ["delete"]}}
+			]
+		}`)
+		drifted, orphaned, err := driftFromPlan(plan)
+		require.NoError(t, err)
+		require.Equal(t, []string{"docker_network.private"}, drifted)
+		require.Equal(t, []string{"docker_volume.home_volume"}, orphaned)
+	})
+}
+
+func TestCheckResourceCeilings(t *testing.T) {
+	t.Parallel()
+
+	resources := []*sdkproto.Resource{{
+		Name: "dev",
+		Metadata: []*sdkproto.Resource_Metadata{
+			{Key: "cpu", Value: "4 cores"},
+			{Key: "Memory", Value: "8GiB"},
+			{Key: "disk", Value: "512 MB"},
+		},
+	}, {
+		Name: "home",
+		Metadata: []*sdkproto.Resource_Metadata{
+			{Key: "disk", Value: "1 TB"},
+			{Key: "memory", Value: "lots"},
+			{Key: "cpu", IsNull: true},
+		},
+	}}
+
+	t.Run("NoCeilings", func(t *testing.T) {
+		t.Parallel()
+		violations := checkResourceCeilings(database.TemplateResourceCeilings{}, resources, 100)
+		require.Empty(t, violations)
+	})
+
+	t.Run("WithinCeilings", func(t *testing.T) {
+		t.Parallel()
+		violations := checkResourceCeilings(database.TemplateResourceCeilings{
+			CPU:       4,
+			MemoryGiB: 8,
+			DiskGiB:   1024.5,
+			DailyCost: 10,
+		}, resources, 10)
+		require.Empty(t, violations)
+	})
+
+	t.Run("Exceeded", func(t *testing.T) {
+		t.Parallel()
+		violations := checkResourceCeilings(database.TemplateResourceCeilings{
+			CPU:       2,
+			MemoryGiB: 16,
+			DiskGiB:   100,
+			DailyCost: 5,
+		}, resources, 10)
+		require.Len(t, violations, 3)
+		require.Equal(t, "cpu", violations[0].Resource)
+		require.Equal(t, float64(4), violations[0].Planned)
+		require.Equal(t, float64(2), violations[0].Ceiling)
+		require.Equal(t, "disk_gib", violations[1].Resource)
+		require.Equal(t, 1024.5, violations[1].Planned)
+		require.Equal(t, "daily_cost", violations[2].Resource)
+		require.Equal(t, float64(10), violations[2].Planned)
+	})
+}
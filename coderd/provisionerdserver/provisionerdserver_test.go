@@ -782,6 +782,86 @@ func TestUpdateJob(t *testing.T) {
 
 		<-published
 	})
+	t.Run("StreamLogs", func(t *testing.T) {
+		t.Parallel()
+		clock := quartz.NewMock(t)
+		srv, db, ps, pd := setup(t, false, &overrides{clock: clock})
+		job := setupJob(t, db, pd.ID, pd.Tags)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		published := make(chan struct{}, 2)
+		closeListener, err := ps.Subscribe(provisionersdk.ProvisionerJobLogsNotifyChannel(job), func(_ context.Context, _ []byte) {
+			published <- struct{}{}
+		})
+		require.NoError(t, err)
+		defer closeListener()
+
+		flushTimers := clock.Trap().NewTimer("StreamJobLogs", "flush")
+		defer flushTimers.Close()
+		stream := newFakeLogStream(ctx)
+		streamErr := make(chan error, 1)
+		go func() {
+			streamErr <- srv.StreamJobLogs(stream)
+		}()
+		log := func(output string) *proto.Log {
+			return &proto.Log{
+				Source: proto.LogSource_PROVISIONER,
+				Level:  sdkproto.LogLevel_INFO,
+				Output: output,
+			}
+		}
+
+		// Logs are coalesced until the flush timer fires.
+		testutil.RequireSend(ctx, t, stream.requests, &proto.StreamJobLogsRequest{
+			JobId: job.String(),
+			Logs:  []*proto.Log{log("one"), log("two")},
+		})
+		flushTimers.MustWait(ctx).MustRelease(ctx)
+		clock.Advance(250 * time.Millisecond).MustWait(ctx)
+		response := testutil.TryReceive(ctx, t, stream.responses)
+		require.EqualValues(t, 2, response.InsertedLogs)
+		require.Zero(t, response.BackoffMs)
+		testutil.TryReceive(ctx, t, published)
+
+		// The rest is inserted when the daemon closes the stream.
+		testutil.RequireSend(ctx, t, stream.requests, &proto.StreamJobLogsRequest{
+			JobId: job.String(),
+			Logs:  []*proto.Log{log("three")},
+		})
+		flushTimers.MustWait(ctx).MustRelease(ctx)
+		close(stream.requests)
+		response = testutil.TryReceive(ctx, t, stream.responses)
+		require.EqualValues(t, 3, response.InsertedLogs)
+		testutil.TryReceive(ctx, t, published)
+		require.NoError(t, testutil.TryReceive(ctx, t, streamErr))
+
+		logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+			JobID: job,
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 3)
+		for i, output := range []string{"one", "two", "three"} {
+			require.Equal(t, output, logs[i].Output)
+		}
+	})
+	t.Run("StreamLogsOtherJob", func(t *testing.T) {
+		t.Parallel()
+		srv, db, _, pd := setup(t, false, &overrides{})
+		job := setupJob(t, db, pd.ID, pd.Tags)
+		otherJob := setupJob(t, db, uuid.New(), pd.Tags)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		stream := newFakeLogStream(ctx)
+		stream.requests <- &proto.StreamJobLogsRequest{JobId: otherJob.String()}
+		err := srv.StreamJobLogs(stream)
+		require.ErrorContains(t, err, "you don't own this job")
+
+		stream = newFakeLogStream(ctx)
+		stream.requests <- &proto.StreamJobLogsRequest{JobId: job.String()}
+		stream.requests <- &proto.StreamJobLogsRequest{JobId: otherJob.String()}
+		err = srv.StreamJobLogs(stream)
+		require.ErrorContains(t, err, "stream carries logs of job")
+	})
 	t.Run("Readme", func(t *testing.T) {
 		t.Parallel()
 		srv, db, _, pd := setup(t, false, &overrides{})
@@ -897,6 +977,17 @@ func TestUpdateJob(t *testing.T) {
 		})
 	})
 
+	t.Run("InterimStateNotWorkspaceBuild", func(t *testing.T) {
+		t.Parallel()
+		srv, db, _, pd := setup(t, false, &overrides{})
+		job := setupJob(t, db, pd.ID, pd.Tags)
+		_, err := srv.UpdateJob(ctx, &proto.UpdateJobRequest{
+			JobId:        job.String(),
+			InterimState: []byte("{}"),
+		})
+		require.ErrorContains(t, err, "interim state is only accepted for workspace builds")
+	})
+
 	t.Run("WorkspaceTags", func(t *testing.T) {
 		t.Parallel()
 
@@ -1099,6 +1190,16 @@ func TestFailJob(t *testing.T) {
 		require.NoError(t, err)
 		defer closeLogsSubscribe()
 
+		// The state uploaded during the apply is stored...
+		_, err = srv.UpdateJob(ctx, &proto.UpdateJobRequest{
+			JobId:        job.ID.String(),
+			InterimState: []byte("interim state"),
+		})
+		require.NoError(t, err)
+		interim, err := db.GetWorkspaceBuildInterimStateByBuildID(ctx, buildID)
+		require.NoError(t, err)
+		require.Equal(t, "interim state", string(interim.State))
+
 		auditor.ResetLogs()
 		_, err = srv.FailJob(ctx, &proto.FailedJob{
 			JobId: job.ID.String(),
@@ -1114,6 +1215,9 @@ func TestFailJob(t *testing.T) {
 		build, err := db.GetWorkspaceBuildByID(ctx, buildID)
 		require.NoError(t, err)
 		require.Equal(t, "some state", string(build.ProvisionerState))
+		// ...until it is superseded by the state the build failed with.
+		_, err = db.GetWorkspaceBuildInterimStateByBuildID(ctx, buildID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
 		require.Len(t, auditor.AuditLogs(), 1)
 
 		// Assert that the workspace_id field get populated
@@ -1871,6 +1975,17 @@ func TestCompleteJob(t *testing.T) {
 				require.NoError(t, err)
 				require.Equal(t, c.transition == database.WorkspaceTransitionDelete, workspace.Deleted)
 
+				// The next build of the workspace should prefer this daemon,
+				// unless the workspace is gone.
+				affinity, err := db.GetWorkspaceProvisionerAffinity(ctx, workspaceTable.ID)
+				if c.transition == database.WorkspaceTransitionDelete {
+					require.ErrorIs(t, err, sql.ErrNoRows)
+				} else {
+					require.NoError(t, err)
+					require.Equal(t, pd.ID, affinity.ProvisionerDaemonID)
+					require.Equal(t, version.ID, affinity.TemplateVersionID)
+				}
+
 				workspaceBuild, err := db.GetWorkspaceBuildByID(ctx, build.ID)
 				require.NoError(t, err)
 
@@ -1939,6 +2054,72 @@ func TestCompleteJob(t *testing.T) {
 		})
 		require.NoError(t, err)
 	})
+	t.Run("TemplateDryRunDriftCheck", func(t *testing.T) {
+		t.Parallel()
+		notifEnq := &notificationstest.FakeEnqueuer{}
+		srv, db, _, pd := setup(t, false, &overrides{notificationEnqueuer: notifEnq})
+		user := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{OwnerID: user.ID})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			Transition:  database.WorkspaceTransitionStop,
+		})
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:            uuid.New(),
+			Provisioner:   database.ProvisionerTypeEcho,
+			Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			Input: must(json.Marshal(provisionerdserver.TemplateVersionDryRunJob{
+				TemplateVersionID: build.TemplateVersionID,
+				WorkspaceBuildID:  uuid.NullUUID{UUID: build.ID, Valid: true},
+			})),
+			Tags: pd.Tags,
+		})
+		require.NoError(t, err)
+		err = db.UpsertWorkspaceDriftCheck(ctx, database.UpsertWorkspaceDriftCheckParams{
+			WorkspaceID:      workspace.ID,
+			WorkspaceBuildID: build.ID,
+			JobID:            job.ID,
+			CreatedAt:        dbtime.Now(),
+		})
+		require.NoError(t, err)
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  pd.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+			StartedAt: sql.NullTime{
+				Time:  dbtime.Now(),
+				Valid: true,
+			},
+			ProvisionerTags: must(json.Marshal(job.Tags)),
+		})
+		require.NoError(t, err)
+
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_TemplateDryRun_{
+				TemplateDryRun: &proto.CompletedJob_TemplateDryRun{
+					Plan: []byte(`{"format_version": "1.2", "resource_changes": [{"address": "docker_volume.home_volume", "mode": "managed", "type": "docker_volume", "change": {"actions": ["delete"]}}]}`),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		checks, err := db.GetWorkspaceDriftChecksByWorkspaceIDs(ctx, []uuid.UUID{workspace.ID})
+		require.NoError(t, err)
+		require.Len(t, checks, 1)
+		require.True(t, checks[0].CompletedAt.Valid)
+		require.Empty(t, checks[0].DriftedResources)
+		require.Equal(t, []string{"docker_volume.home_volume"}, checks[0].OrphanedResources)
+
+		sent := notifEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceDriftDetected))
+		require.Len(t, sent, 1)
+		require.Equal(t, user.ID, sent[0].UserID)
+		require.Equal(t, workspace.Name, sent[0].Labels["name"])
+		require.Contains(t, sent[0].Targets, workspace.ID)
+	})
 
 	t.Run("Modules", func(t *testing.T) {
 		t.Parallel()
@@ -3257,6 +3438,37 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		require.Equal(t, "/workspace2", devcontainers[1].WorkspaceFolder)
 		require.Equal(t, "/workspace2/.devcontainer/devcontainer.json", devcontainers[1].ConfigPath)
 	})
+
+	t.Run("DisplayHints", func(t *testing.T) {
+		t.Parallel()
+		db, _ := dbtestutil.NewDB(t)
+		dbtestutil.DisableForeignKeysAndTriggers(t, db)
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name:         "something",
+			Type:         "aws_instance",
+			DisplayGroup: "Compute",
+			Order:        2,
+			Collapsed:    true,
+			Agents: []*sdkproto.Agent{{
+				Name:         "dev",
+				DisplayGroup: "Agents",
+				Collapsed:    true,
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		require.Equal(t, "Compute", resources[0].DisplayGroup)
+		require.EqualValues(t, 2, resources[0].DisplayOrder)
+		require.True(t, resources[0].Collapsed)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		require.Equal(t, "Agents", agents[0].DisplayGroup)
+		require.True(t, agents[0].Collapsed)
+	})
 }
 
 func TestNotifications(t *testing.T) {
@@ -3395,6 +3607,91 @@ func TestNotifications(t *testing.T) {
 		}
 	})
 
+	t.Run("Workspace started with outdated version", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		notifEnq := &notificationstest.FakeEnqueuer{}
+
+		srv, db, ps, pd := setup(t, false, &overrides{
+			notificationEnqueuer: notifEnq,
+		})
+
+		user := dbgen.User(t, db, database.User{})
+		template := dbgen.Template(t, db, database.Template{
+			Name:           "template",
+			Provisioner:    database.ProvisionerTypeEcho,
+			OrganizationID: pd.OrganizationID,
+		})
+		file := dbgen.File(t, db, database.File{CreatedBy: user.ID})
+		workspaceTable := dbgen.Workspace(t, db, database.WorkspaceTable{
+			TemplateID:     template.ID,
+			OwnerID:        user.ID,
+			OrganizationID: pd.OrganizationID,
+		})
+		version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			Name:           "v1",
+			OrganizationID: pd.OrganizationID,
+			TemplateID: uuid.NullUUID{
+				UUID:  template.ID,
+				Valid: true,
+			},
+			JobID: uuid.New(),
+		})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       workspaceTable.ID,
+			TemplateVersionID: version.ID,
+			InitiatorID:       user.ID,
+			Transition:        database.WorkspaceTransitionStart,
+			Reason:            database.BuildReasonInitiator,
+			Warnings: database.WorkspaceBuildWarnings{{
+				Code:           string(codersdk.WorkspaceBuildWarningInactiveTemplateVersion),
+				Message:        "Template version \"v1\" is behind the active version of the template, \"v2\", by 1 version(s).",
+				VersionsBehind: []string{"v2"},
+			}},
+		})
+		job := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			FileID:      file.ID,
+			InitiatorID: user.ID,
+			Type:        database.ProvisionerJobTypeWorkspaceBuild,
+			Input: must(json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+				WorkspaceBuildID: build.ID,
+			})),
+			OrganizationID: pd.OrganizationID,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		})
+		_, err := db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			OrganizationID: pd.OrganizationID,
+			WorkerID: uuid.NullUUID{
+				UUID:  pd.ID,
+				Valid: true,
+			},
+			Types:           []database.ProvisionerType{database.ProvisionerTypeEcho},
+			ProvisionerTags: must(json.Marshal(job.Tags)),
+			StartedAt:       sql.NullTime{Time: job.CreatedAt, Valid: true},
+		})
+		require.NoError(t, err)
+
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_WorkspaceBuild_{
+				WorkspaceBuild: &proto.CompletedJob_WorkspaceBuild{
+					State: []byte{},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		sent := notifEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceOutdatedBuild))
+		require.Len(t, sent, 1)
+		require.Equal(t, user.ID, sent[0].UserID)
+		require.Equal(t, workspaceTable.Name, sent[0].Labels["name"])
+		require.Equal(t, "v1", sent[0].Labels["template_version_name"])
+		require.Equal(t, []string{build.Warnings[0].Message}, sent[0].Data["warnings"])
+		require.Contains(t, sent[0].Targets, workspaceTable.ID)
+	})
+
 	t.Run("Workspace build failed", func(t *testing.T) {
 		t.Parallel()
 
@@ -3809,3 +4106,60 @@ func (s *fakeStream) cancel() {
 	s.canceled = true
 	s.c.Broadcast()
 }
+
+type fakeLogStream struct {
+	ctx       context.Context
+	requests  chan *proto.StreamJobLogsRequest
+	responses chan *proto.StreamJobLogsResponse
+}
+
+func newFakeLogStream(ctx context.Context) *fakeLogStream {
+	return &fakeLogStream{
+		ctx:       ctx,
+		requests:  make(chan *proto.StreamJobLogsRequest, 2),
+		responses: make(chan *proto.StreamJobLogsResponse, 2),
+	}
+}
+
+func (s *fakeLogStream) Send(response *proto.StreamJobLogsResponse) error {
+	select {
+	case s.responses <- response:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// Recv returns io.EOF once requests is closed, like a stream the daemon
+// closed its sending side of.
+func (s *fakeLogStream) Recv() (*proto.StreamJobLogsRequest, error) {
+	select {
+	case request, ok := <-s.requests:
+		if !ok {
+			return nil, io.EOF
+		}
+		return request, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func (s *fakeLogStream) Context() context.Context {
+	return s.ctx
+}
+
+func (*fakeLogStream) MsgSend(drpc.Message, drpc.Encoding) error {
+	return errUnimplemented
+}
+
+func (*fakeLogStream) MsgRecv(drpc.Message, drpc.Encoding) error {
+	return errUnimplemented
+}
+
+func (*fakeLogStream) CloseSend() error {
+	return errUnimplemented
+}
+
+func (*fakeLogStream) Close() error {
+	return errUnimplemented
+}
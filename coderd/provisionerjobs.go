@@ -5,22 +5,31 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/httpmw/loggermw"
+	"github.com/coder/coder/v2/coderd/joblogarchive"
+	"github.com/coder/coder/v2/coderd/jobreaper"
+	coderdpubsub "github.com/coder/coder/v2/coderd/pubsub"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/util/slice"
@@ -63,7 +72,10 @@ func (api *API) provisionerJob(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerJobWithQueuePosition(jobs[0]))
+	job := convertProvisionerJobWithQueuePosition(jobs[0])
+	api.estimateProvisionerJobStarts(ctx, &job)
+
+	httpapi.Write(ctx, rw, http.StatusOK, job)
 }
 
 // @Summary Get provisioner jobs
@@ -73,8 +85,10 @@ func (api *API) provisionerJob(rw http.ResponseWriter, r *http.Request) {
 // @Tags Organizations
 // @Param organization path string true "Organization ID" format(uuid)
 // @Param limit query int false "Page limit"
+// @Param after_id query string false "After ID" format(uuid)
 // @Param ids query []string false "Filter results by job IDs" format(uuid)
 // @Param status query codersdk.ProvisionerJobStatus false "Filter results by status" enums(pending,running,succeeded,canceling,canceled,failed)
+// @Param type query codersdk.ProvisionerJobType false "Filter results by type" enums(template_version_import,workspace_build,template_version_dry_run)
 // @Param tags query object false "Provisioner tags to filter by (JSON of the form {'tag1':'value1','tag2':'value2'})"
 // @Success 200 {array} codersdk.ProvisionerJob
 // @Router /organizations/{organization}/provisionerjobs [get]
@@ -86,7 +100,196 @@ func (api *API) provisionerJobs(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(jobs, convertProvisionerJobWithQueuePosition))
+	apiJobs := db2sdk.List(jobs, convertProvisionerJobWithQueuePosition)
+	toEstimate := make([]*codersdk.ProvisionerJob, 0, len(apiJobs))
+	for i := range apiJobs {
+		toEstimate = append(toEstimate, &apiJobs[i])
+	}
+	api.estimateProvisionerJobStarts(ctx, toEstimate...)
+
+	httpapi.Write(ctx, rw, http.StatusOK, apiJobs)
+}
+
+// @Summary Reap provisioner job
+// @Description Forcefully marks a job that has not completed as failed, as if
+// @Description it had been detected as hung.
+// @ID reap-provisioner-job
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param job path string true "Job ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /organizations/{organization}/provisionerjobs/{job}/reap [post]
+func (api *API) reapProvisionerJob(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	jobID, ok := httpmw.ParseUUIDParam(rw, r, "job")
+	if !ok {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceProvisionerJobs.InOrg(org.ID)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceProvisionerJobs.InOrg(org.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, jobID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if job.OrganizationID != org.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if job.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Job has already completed!",
+			Code:    completedJobResponseCode(job),
+		})
+		return
+	}
+
+	// The reaper also updates the workspace build of the job, which the user
+	// is not necessarily allowed to do directly.
+	//nolint:gocritic // The user is authorized to update provisioner jobs above.
+	err = jobreaper.ReapJob(dbauthz.AsJobReaper(ctx), api.Logger.Named("jobreaper"), api.Database, api.Pubsub, api.Clock, job.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reaping provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Job has been marked as failed.",
+	})
+}
+
+// @Summary Watch provisioner jobs
+// @Description Streams provisioner jobs of the organization as server-sent
+// @Description events whenever they are queued, acquired, canceled or
+// @Description completed. A ping event is sent once the stream is ready.
+// @ID watch-provisioner-jobs
+// @Security CoderSessionToken
+// @Produce text/event-stream
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.ServerSentEvent
+// @Router /organizations/{organization}/provisionerjobs/watch [get]
+func (api *API) watchProvisionerJobs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceProvisionerJobs.InOrg(org.ID)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	sendEvent, senderClosed, err := httpapi.ServerSentEventSender(rw, r)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error setting up server-sent events.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Prevent handler from returning until the sender is closed.
+	defer func() {
+		<-senderClosed
+	}()
+
+	sendJob := func(jobID uuid.UUID) {
+		jobs, err := api.Database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx, database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams{
+			OrganizationID: org.ID,
+			IDs:            []uuid.UUID{jobID},
+		})
+		if err != nil {
+			_ = sendEvent(codersdk.ServerSentEvent{
+				Type: codersdk.ServerSentEventTypeError,
+				Data: codersdk.Response{
+					Message: "Internal error fetching provisioner job.",
+					Detail:  err.Error(),
+				},
+			})
+			return
+		}
+		if len(jobs) == 0 {
+			return
+		}
+
+		job := convertProvisionerJobWithQueuePosition(jobs[0])
+		api.estimateProvisionerJobStarts(ctx, &job)
+		_ = sendEvent(codersdk.ServerSentEvent{
+			Type: codersdk.ServerSentEventTypeData,
+			Data: job,
+		})
+	}
+
+	cancelSubscribe, err := api.Pubsub.Subscribe(provisionerjobs.JobStateChannel(org.ID), func(_ context.Context, message []byte) {
+		var change provisionerjobs.JobStateChange
+		err := json.Unmarshal(message, &change)
+		if err != nil {
+			api.Logger.Warn(ctx, "invalid provisioner job state change", slog.Error(err))
+			return
+		}
+		sendJob(change.JobID)
+	})
+	if err != nil {
+		_ = sendEvent(codersdk.ServerSentEvent{
+			Type: codersdk.ServerSentEventTypeError,
+			Data: codersdk.Response{
+				Message: "Internal error subscribing to provisioner job events.",
+				Detail:  err.Error(),
+			},
+		})
+		return
+	}
+	defer cancelSubscribe()
+
+	// An initial ping signals to the client that the server is now ready, so
+	// jobs listed afterwards won't miss any state change.
+	_ = sendEvent(codersdk.ServerSentEvent{
+		Type: codersdk.ServerSentEventTypePing,
+	})
+
+	select {
+	case <-ctx.Done():
+	case <-senderClosed:
+	}
+}
+
+// publishProvisionerJobStateChange notifies watchers of the provisioner jobs of
+// the organization that the job changed state.
+func (api *API) publishProvisionerJobStateChange(ctx context.Context, job database.ProvisionerJob) {
+	err := provisionerjobs.PublishJobStateChange(api.Pubsub, job)
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to publish provisioner job state change",
+			slog.F("job_id", job.ID), slog.Error(err))
+	}
+}
+
+// completedJobResponseCode returns the response code for requests that can't
+// proceed because the job has already completed.
+func completedJobResponseCode(job database.ProvisionerJob) codersdk.ResponseCode {
+	if job.ErrorCode.String == string(codersdk.JobReaped) {
+		return codersdk.ResponseCodeJobReaped
+	}
+	return codersdk.ResponseCodeJobCompleted
 }
 
 // handleAuthAndFetchProvisionerJobs is an internal method shared by
@@ -105,7 +308,9 @@ func (api *API) handleAuthAndFetchProvisionerJobs(rw http.ResponseWriter, r *htt
 	qp := r.URL.Query()
 	p := httpapi.NewQueryParamParser()
 	limit := p.PositiveInt32(qp, 50, "limit")
+	afterID := p.UUID(qp, uuid.Nil, "after_id")
 	status := p.Strings(qp, nil, "status")
+	types := p.Strings(qp, nil, "type")
 	if ids == nil {
 		ids = p.UUIDs(qp, nil, "ids")
 	}
@@ -122,7 +327,9 @@ func (api *API) handleAuthAndFetchProvisionerJobs(rw http.ResponseWriter, r *htt
 	jobs, err := api.Database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx, database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams{
 		OrganizationID: org.ID,
 		Status:         slice.StringEnums[database.ProvisionerJobStatus](status),
+		Types:          slice.StringEnums[database.ProvisionerJobType](types),
 		Limit:          sql.NullInt32{Int32: limit, Valid: limit > 0},
+		AfterID:        afterID,
 		IDs:            ids,
 		Tags:           tags,
 	})
@@ -148,12 +355,15 @@ func (api *API) handleAuthAndFetchProvisionerJobs(rw http.ResponseWriter, r *htt
 // GET /logs?after=<id>&follow
 // The combination of these responses should provide all current logs
 // to the consumer, and future logs are streamed in the follow request.
+// The level, stage and search query parameters filter the returned logs.
 func (api *API) provisionerJobLogs(rw http.ResponseWriter, r *http.Request, job database.ProvisionerJob) {
 	var (
-		ctx      = r.Context()
-		logger   = api.Logger.With(slog.F("job_id", job.ID))
-		follow   = r.URL.Query().Has("follow")
-		afterRaw = r.URL.Query().Get("after")
+		ctx        = r.Context()
+		logger     = api.Logger.With(slog.F("job_id", job.ID))
+		follow     = r.URL.Query().Has("follow")
+		structured = r.URL.Query().Has("structured")
+		afterRaw   = r.URL.Query().Get("after")
+		levelRaw   = r.URL.Query().Get("level")
 	)
 
 	var after int64
@@ -172,12 +382,33 @@ func (api *API) provisionerJobLogs(rw http.ResponseWriter, r *http.Request, job
 		}
 	}
 
+	filter := database.SearchProvisionerJobLogsParams{
+		JobID:        job.ID,
+		CreatedAfter: after,
+		Stage:        r.URL.Query().Get("stage"),
+		Search:       strings.TrimSpace(r.URL.Query().Get("search")),
+	}
+	// Only fetch logs at or above the level provided.
+	if levelRaw != "" {
+		level := database.LogLevel(levelRaw)
+		if !level.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Query param \"level\" must be one of %v.", database.AllLogLevelValues()),
+				Validations: []codersdk.ValidationError{
+					{Field: "level", Detail: "Must be a valid log level"},
+				},
+			})
+			return
+		}
+		filter.MinLevel = database.NullLogLevel{LogLevel: level, Valid: true}
+	}
+
 	if !follow {
-		fetchAndWriteLogs(ctx, api.Database, job.ID, after, rw)
+		fetchAndWriteLogs(ctx, api.Database, filter, structured, rw)
 		return
 	}
 
-	follower := newLogFollower(ctx, logger, api.Database, api.Pubsub, rw, r, job, after)
+	follower := newLogFollower(ctx, logger, api.Database, api.Pubsub, rw, r, job, filter, structured)
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Add(1)
 	api.WebsocketWaitMutex.Unlock()
@@ -325,22 +556,27 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 		apiResources = append(apiResources, convertWorkspaceResource(resource, agents, metadata))
 	}
 	sort.Slice(apiResources, func(i, j int) bool {
+		if apiResources[i].DisplayOrder != apiResources[j].DisplayOrder {
+			return apiResources[i].DisplayOrder < apiResources[j].DisplayOrder
+		}
 		return apiResources[i].Name < apiResources[j].Name
 	})
 
 	httpapi.Write(ctx, rw, http.StatusOK, apiResources)
 }
 
-func convertProvisionerJobLogs(provisionerJobLogs []database.ProvisionerJobLog) []codersdk.ProvisionerJobLog {
+func convertProvisionerJobLogs(provisionerJobLogs []database.ProvisionerJobLog, structured bool) []codersdk.ProvisionerJobLog {
 	sdk := make([]codersdk.ProvisionerJobLog, 0, len(provisionerJobLogs))
 	for _, log := range provisionerJobLogs {
-		sdk = append(sdk, convertProvisionerJobLog(log))
+		sdk = append(sdk, convertProvisionerJobLog(log, structured))
 	}
 	return sdk
 }
 
-func convertProvisionerJobLog(provisionerJobLog database.ProvisionerJobLog) codersdk.ProvisionerJobLog {
-	return codersdk.ProvisionerJobLog{
+// convertProvisionerJobLog converts a database log. The structured fields are
+// only included if structured is true.
+func convertProvisionerJobLog(provisionerJobLog database.ProvisionerJobLog, structured bool) codersdk.ProvisionerJobLog {
+	log := codersdk.ProvisionerJobLog{
 		ID:        provisionerJobLog.ID,
 		CreatedAt: provisionerJobLog.CreatedAt,
 		Source:    codersdk.LogSource(provisionerJobLog.Source),
@@ -348,6 +584,14 @@ func convertProvisionerJobLog(provisionerJobLog database.ProvisionerJobLog) code
 		Stage:     provisionerJobLog.Stage,
 		Output:    provisionerJobLog.Output,
 	}
+	if structured {
+		var fields codersdk.ProvisionerJobLogFields
+		// The fields are always written by provisionerdserver, so an
+		// unparsable value can only be a missing one.
+		_ = json.Unmarshal(provisionerJobLog.Fields, &fields)
+		log.Fields = &fields
+	}
+	return log
 }
 
 func convertProvisionerJob(pj database.GetProvisionerJobsByIDsWithQueuePositionRow) codersdk.ProvisionerJob {
@@ -411,11 +655,100 @@ func convertProvisionerJobWithQueuePosition(pj database.GetProvisionerJobsByOrga
 	return job
 }
 
-func fetchAndWriteLogs(ctx context.Context, db database.Store, jobID uuid.UUID, after int64, rw http.ResponseWriter) {
-	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
-		JobID:        jobID,
-		CreatedAfter: after,
+// provisionerJobThroughputWindow is how far back started jobs are counted when
+// estimating when a pending job will be picked up.
+const provisionerJobThroughputWindow = time.Hour
+
+// estimateProvisionerJobStarts sets EstimatedStartAt on every pending job with
+// a queue position. Estimates are best-effort, so failures are logged rather
+// than returned.
+func (api *API) estimateProvisionerJobStarts(ctx context.Context, jobs ...*codersdk.ProvisionerJob) {
+	organizationIDs := make([]uuid.UUID, 0)
+	for _, job := range jobs {
+		if job.Status != codersdk.ProvisionerJobPending || job.QueuePosition <= 0 {
+			continue
+		}
+		if !slices.Contains(organizationIDs, job.OrganizationID) {
+			organizationIDs = append(organizationIDs, job.OrganizationID)
+		}
+	}
+	if len(organizationIDs) == 0 {
+		return
+	}
+
+	now := api.Clock.Now()
+	// Throughput is aggregated over all jobs in the organization, which the
+	// actor is not necessarily able to read.
+	// nolint:gocritic // Only job counts are exposed to the actor.
+	throughput, err := api.Database.GetProvisionerJobThroughputByTags(dbauthz.AsSystemRestricted(ctx), database.GetProvisionerJobThroughputByTagsParams{
+		OrganizationIDs: organizationIDs,
+		StartedAfter:    now.Add(-provisionerJobThroughputWindow),
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to get provisioner job throughput", slog.Error(err))
+		return
+	}
+
+	for _, job := range jobs {
+		if job.Status != codersdk.ProvisionerJobPending {
+			continue
+		}
+		for _, row := range throughput {
+			if row.OrganizationID != job.OrganizationID || !maps.Equal(row.Tags, database.StringMap(job.Tags)) {
+				continue
+			}
+			if estimate, ok := estimateProvisionerJobStart(now, job.QueuePosition, row.StartedJobs); ok {
+				job.EstimatedStartAt = &estimate
+			}
+			break
+		}
+	}
+}
+
+// estimateProvisionerJobStart assumes jobs ahead in the queue keep being
+// picked up at the rate observed over provisionerJobThroughputWindow.
+func estimateProvisionerJobStart(now time.Time, queuePosition int, startedJobs int64) (time.Time, bool) {
+	if queuePosition <= 0 || startedJobs <= 0 {
+		return time.Time{}, false
+	}
+	interval := provisionerJobThroughputWindow / time.Duration(startedJobs)
+	return now.Add(interval * time.Duration(queuePosition)), true
+}
+
+// checkProvisionerJobLimit enforces the deployment limit on the number of
+// pending and running provisioner jobs a single user may have. It writes a
+// response and returns false if the user may not start another job.
+func (api *API) checkProvisionerJobLimit(ctx context.Context, rw http.ResponseWriter, initiatorID uuid.UUID) bool {
+	limit := api.DeploymentValues.Provisioner.MaxConcurrentJobsPerUser.Value()
+	if limit <= 0 {
+		return true
+	}
+
+	// nolint:gocritic // Users are not necessarily allowed to read provisioner
+	// jobs, but they must be able to hit the limit.
+	counts, err := api.Database.CountInProgressProvisionerJobsByInitiator(dbauthz.AsSystemRestricted(ctx), database.CountInProgressProvisionerJobsByInitiatorParams{
+		InitiatorID: initiatorID,
 	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error counting in-progress provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if counts.Total >= limit {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("You have reached the limit of %d concurrent provisioner jobs.", limit),
+			Detail:  "Wait for your pending and running jobs to finish before starting another.",
+			Code:    codersdk.ResponseCodeJobLimitReached,
+		})
+		return false
+	}
+	return true
+}
+
+func fetchAndWriteLogs(ctx context.Context, db database.Store, filter database.SearchProvisionerJobLogsParams, structured bool, rw http.ResponseWriter) {
+	logs, err := joblogarchive.SearchLogs(ctx, db, filter)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching provisioner logs.",
@@ -426,7 +759,7 @@ func fetchAndWriteLogs(ctx context.Context, db database.Store, jobID uuid.UUID,
 	if logs == nil {
 		logs = []database.ProvisionerJobLog{}
 	}
-	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerJobLogs(logs))
+	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerJobLogs(logs, structured))
 }
 
 func jobIsComplete(logger slog.Logger, job database.ProvisionerJob) bool {
@@ -464,6 +797,8 @@ type logFollower struct {
 
 	jobID         uuid.UUID
 	after         int64
+	filter        database.SearchProvisionerJobLogsParams
+	structured    bool
 	complete      bool
 	notifications chan provisionersdk.ProvisionerJobLogsNotifyMessage
 	errors        chan error
@@ -471,7 +806,7 @@ type logFollower struct {
 
 func newLogFollower(
 	ctx context.Context, logger slog.Logger, db database.Store, ps pubsub.Pubsub,
-	rw http.ResponseWriter, r *http.Request, job database.ProvisionerJob, after int64,
+	rw http.ResponseWriter, r *http.Request, job database.ProvisionerJob, filter database.SearchProvisionerJobLogsParams, structured bool,
 ) *logFollower {
 	return &logFollower{
 		ctx:           ctx,
@@ -481,7 +816,9 @@ func newLogFollower(
 		r:             r,
 		rw:            rw,
 		jobID:         job.ID,
-		after:         after,
+		after:         filter.CreatedAfter,
+		filter:        filter,
+		structured:    structured,
 		complete:      jobIsComplete(logger, job),
 		notifications: make(chan provisionersdk.ProvisionerJobLogsNotifyMessage),
 		errors:        make(chan error),
@@ -616,8 +953,7 @@ func (f *logFollower) listener(_ context.Context, message []byte, err error) {
 		}
 		return
 	}
-	var n provisionersdk.ProvisionerJobLogsNotifyMessage
-	err = json.Unmarshal(message, &n)
+	n, err := coderdpubsub.ProvisionerJobLogsNotify.Decode(message)
 	if err != nil {
 		select {
 		case <-f.ctx.Done():
@@ -635,15 +971,14 @@ func (f *logFollower) listener(_ context.Context, message []byte, err error) {
 // connection.
 func (f *logFollower) query() error {
 	f.logger.Debug(f.ctx, "querying logs", slog.F("after", f.after))
-	logs, err := f.db.GetProvisionerLogsAfterID(f.ctx, database.GetProvisionerLogsAfterIDParams{
-		JobID:        f.jobID,
-		CreatedAfter: f.after,
-	})
+	f.filter.JobID = f.jobID
+	f.filter.CreatedAfter = f.after
+	logs, err := joblogarchive.SearchLogs(f.ctx, f.db, f.filter)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return xerrors.Errorf("error fetching logs: %w", err)
 	}
 	for _, log := range logs {
-		err := f.enc.Encode(convertProvisionerJobLog(log))
+		err := f.enc.Encode(convertProvisionerJobLog(log, f.structured))
 		if err != nil {
 			return xerrors.Errorf("error writing to websocket: %w", err)
 		}
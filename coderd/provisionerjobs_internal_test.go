@@ -21,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/httpmw/loggermw"
 	"github.com/coder/coder/v2/coderd/httpmw/loggermw/loggermock"
+	coderdpubsub "github.com/coder/coder/v2/coderd/pubsub"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/testutil"
@@ -142,6 +143,54 @@ func TestConvertProvisionerJob_Unit(t *testing.T) {
 	}
 }
 
+func TestEstimateProvisionerJobStart(t *testing.T) {
+	t.Parallel()
+	now := dbtime.Now()
+
+	testCases := []struct {
+		name          string
+		queuePosition int
+		startedJobs   int64
+		expected      time.Duration
+		ok            bool
+	}{
+		{
+			name:          "no queue position",
+			queuePosition: 0,
+			startedJobs:   10,
+		},
+		{
+			name:          "no throughput",
+			queuePosition: 3,
+			startedJobs:   0,
+		},
+		{
+			name:          "next in queue",
+			queuePosition: 1,
+			startedJobs:   6,
+			expected:      10 * time.Minute,
+			ok:            true,
+		},
+		{
+			name:          "behind other jobs",
+			queuePosition: 4,
+			startedJobs:   12,
+			expected:      20 * time.Minute,
+			ok:            true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			actual, ok := estimateProvisionerJobStart(now, testCase.queuePosition, testCase.startedJobs)
+			require.Equal(t, testCase.ok, ok)
+			if ok {
+				require.Equal(t, now.Add(testCase.expected), actual)
+			}
+		})
+	}
+}
+
 func Test_logFollower_completeBeforeFollow(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
@@ -169,13 +218,13 @@ func Test_logFollower_completeBeforeFollow(t *testing.T) {
 
 	// we need an HTTP server to get a websocket
 	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		uut := newLogFollower(ctx, logger, mDB, ps, rw, r, job, 10)
+		uut := newLogFollower(ctx, logger, mDB, ps, rw, r, job, database.SearchProvisionerJobLogsParams{JobID: job.ID, CreatedAfter: 10}, false)
 		uut.follow()
 	}))
 	defer srv.Close()
 
 	// return some historical logs
-	mDB.EXPECT().GetProvisionerLogsAfterID(gomock.Any(), matchesJobAfter(job.ID, 10)).
+	mDB.EXPECT().SearchProvisionerJobLogs(gomock.Any(), matchesJobAfter(job.ID, 10)).
 		Times(1).
 		Return(
 			[]database.ProvisionerJobLog{
@@ -230,7 +279,7 @@ func Test_logFollower_completeBeforeSubscribe(t *testing.T) {
 
 	// we need an HTTP server to get a websocket
 	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		uut := newLogFollower(ctx, logger, mDB, ps, rw, r, job, 0)
+		uut := newLogFollower(ctx, logger, mDB, ps, rw, r, job, database.SearchProvisionerJobLogsParams{JobID: job.ID, CreatedAfter: 0}, false)
 		uut.follow()
 	}))
 	defer srv.Close()
@@ -253,7 +302,7 @@ func Test_logFollower_completeBeforeSubscribe(t *testing.T) {
 	)
 
 	// return some historical logs
-	mDB.EXPECT().GetProvisionerLogsAfterID(gomock.Any(), matchesJobAfter(job.ID, 0)).
+	mDB.EXPECT().SearchProvisionerJobLogs(gomock.Any(), matchesJobAfter(job.ID, 0)).
 		Times(1).
 		Return(
 			[]database.ProvisionerJobLog{
@@ -312,7 +361,7 @@ func Test_logFollower_EndOfLogs(t *testing.T) {
 
 	// we need an HTTP server to get a websocket
 	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		uut := newLogFollower(ctx, logger, mDB, ps, rw, r, job, 0)
+		uut := newLogFollower(ctx, logger, mDB, ps, rw, r, job, database.SearchProvisionerJobLogsParams{JobID: job.ID, CreatedAfter: 0}, false)
 		uut.follow()
 	}))
 
@@ -322,7 +371,7 @@ func Test_logFollower_EndOfLogs(t *testing.T) {
 	mDB.EXPECT().GetProvisionerJobByID(gomock.Any(), job.ID).Times(1).Return(job, nil)
 
 	// return some historical logs
-	q0 := mDB.EXPECT().GetProvisionerLogsAfterID(gomock.Any(), matchesJobAfter(job.ID, 0)).
+	q0 := mDB.EXPECT().SearchProvisionerJobLogs(gomock.Any(), matchesJobAfter(job.ID, 0)).
 		Times(1).
 		Return(
 			[]database.ProvisionerJobLog{
@@ -332,7 +381,7 @@ func Test_logFollower_EndOfLogs(t *testing.T) {
 			nil,
 		)
 	// return some logs from a kick.
-	mDB.EXPECT().GetProvisionerLogsAfterID(gomock.Any(), matchesJobAfter(job.ID, 2)).
+	mDB.EXPECT().SearchProvisionerJobLogs(gomock.Any(), matchesJobAfter(job.ID, 2)).
 		After(q0).
 		Times(1).
 		Return(
@@ -356,7 +405,8 @@ func Test_logFollower_EndOfLogs(t *testing.T) {
 	assert.Equal(t, websocket.MessageText, mt)
 	assertLog(t, "One", "Two", 2, msg)
 
-	// send in the kick so follower will query a second time
+	// send in the kick so follower will query a second time, without an
+	// envelope like a replica that predates them
 	n := provisionersdk.ProvisionerJobLogsNotifyMessage{
 		CreatedAfter: 2,
 	}
@@ -378,7 +428,7 @@ func Test_logFollower_EndOfLogs(t *testing.T) {
 	// send EndOfLogs
 	n.EndOfLogs = true
 	n.CreatedAfter = 0
-	msg, err = json.Marshal(&n)
+	msg, err = coderdpubsub.ProvisionerJobLogsNotify.Encode(n)
 	require.NoError(t, err)
 	err = ps.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(job.ID), msg)
 	require.NoError(t, err)
@@ -401,11 +451,11 @@ func assertLog(t *testing.T, stage, output string, id int64, msg []byte) {
 }
 
 type logsAfterMatcher struct {
-	params database.GetProvisionerLogsAfterIDParams
+	params database.SearchProvisionerJobLogsParams
 }
 
 func (m *logsAfterMatcher) Matches(x interface{}) bool {
-	p, ok := x.(database.GetProvisionerLogsAfterIDParams)
+	p, ok := x.(database.SearchProvisionerJobLogsParams)
 	if !ok {
 		return false
 	}
@@ -418,7 +468,7 @@ func (m *logsAfterMatcher) String() string {
 
 func matchesJobAfter(jobID uuid.UUID, after int64) gomock.Matcher {
 	return &logsAfterMatcher{
-		params: database.GetProvisionerLogsAfterIDParams{
+		params: database.SearchProvisionerJobLogsParams{
 			JobID:        jobID,
 			CreatedAfter: after,
 		},
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"net/http"
 	"strconv"
 	"testing"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
@@ -155,6 +157,17 @@ func TestProvisionerJobs(t *testing.T) {
 			require.Len(t, jobs, 1)
 		})
 
+		t.Run("Type", func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitMedium)
+			jobs, err := templateAdminClient.OrganizationProvisionerJobs(ctx, owner.OrganizationID, &codersdk.OrganizationProvisionerJobsOptions{
+				Types: []codersdk.ProvisionerJobType{codersdk.ProvisionerJobTypeTemplateVersionImport},
+			})
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			require.Equal(t, version.Job.ID, jobs[0].ID)
+		})
+
 		t.Run("Tags", func(t *testing.T) {
 			t.Parallel()
 			ctx := testutil.Context(t, testutil.WaitMedium)
@@ -175,6 +188,38 @@ func TestProvisionerJobs(t *testing.T) {
 			require.Len(t, jobs, 1)
 		})
 
+		t.Run("AfterID", func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitMedium)
+			all, err := templateAdminClient.OrganizationProvisionerJobs(ctx, owner.OrganizationID, &codersdk.OrganizationProvisionerJobsOptions{
+				Limit: 100,
+			})
+			require.NoError(t, err)
+
+			// Paging with the last job of each page returns every job once,
+			// in the same order.
+			var want, got []uuid.UUID
+			for _, job := range all {
+				want = append(want, job.ID)
+			}
+			var afterID uuid.UUID
+			for {
+				page, err := templateAdminClient.OrganizationProvisionerJobs(ctx, owner.OrganizationID, &codersdk.OrganizationProvisionerJobsOptions{
+					Limit:   25,
+					AfterID: afterID,
+				})
+				require.NoError(t, err)
+				if len(page) == 0 {
+					break
+				}
+				for _, job := range page {
+					got = append(got, job.ID)
+				}
+				afterID = page[len(page)-1].ID
+			}
+			require.Equal(t, want, got)
+		})
+
 		// For now, this is not allowed even though the member has created a
 		// workspace. Once member-level permissions for jobs are supported
 		// by RBAC, this test should be updated.
@@ -287,6 +332,124 @@ func TestProvisionerJobs(t *testing.T) {
 	})
 }
 
+func TestReapProvisionerJob(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database: db,
+		Pubsub:   ps,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.ScopedRoleOrgTemplateAdmin(owner.OrganizationID))
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// No provisioner daemon is running, so the job stays pending.
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+
+	t.Run("MemberDenied", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := memberClient.ReapOrganizationProvisionerJob(ctx, owner.OrganizationID, version.Job.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+		require.Equal(t, codersdk.ResponseCodeNotFound, apiErr.Code)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := templateAdminClient.ReapOrganizationProvisionerJob(ctx, owner.OrganizationID, uuid.New())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := templateAdminClient.ReapOrganizationProvisionerJob(ctx, owner.OrganizationID, version.Job.ID)
+		require.NoError(t, err)
+
+		job, err := templateAdminClient.OrganizationProvisionerJob(ctx, owner.OrganizationID, version.Job.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobFailed, job.Status)
+		require.Contains(t, job.Error, "manually terminated")
+
+		require.Equal(t, codersdk.JobReaped, job.ErrorCode)
+
+		// The job has completed, so it can't be reaped again.
+		err = templateAdminClient.ReapOrganizationProvisionerJob(ctx, owner.OrganizationID, version.Job.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, codersdk.ResponseCodeJobReaped, apiErr.Code)
+	})
+}
+
+func TestWatchProvisionerJobs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MemberDenied", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := memberClient.WatchOrganizationProvisionerJobs(ctx, owner.OrganizationID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.ScopedRoleOrgTemplateAdmin(owner.OrganizationID))
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		jobs, err := templateAdminClient.WatchOrganizationProvisionerJobs(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+
+		// No provisioner daemon is running, so the job stays pending until
+		// it's canceled.
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		job := testutil.RequireReceive(ctx, t, jobs)
+		require.Equal(t, version.Job.ID, job.ID)
+		require.Equal(t, codersdk.ProvisionerJobPending, job.Status)
+
+		err = client.CancelTemplateVersion(ctx, version.ID)
+		require.NoError(t, err)
+		job = testutil.RequireReceive(ctx, t, jobs)
+		require.Equal(t, version.Job.ID, job.ID)
+		require.Equal(t, codersdk.ProvisionerJobCanceled, job.Status)
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		jobs, err := client.WatchOrganizationProvisionerJobs(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		// The job is queued, acquired and completed. Events carry the state of
+		// the job when they are sent, so intermediate states may be skipped.
+		for {
+			job := testutil.RequireReceive(ctx, t, jobs)
+			require.Equal(t, version.Job.ID, job.ID)
+			if job.Status == codersdk.ProvisionerJobSucceeded {
+				break
+			}
+		}
+	})
+}
+
 func TestProvisionerJobLogs(t *testing.T) {
 	t.Parallel()
 	t.Run("StreamAfterComplete", func(t *testing.T) {
@@ -365,3 +528,121 @@ func TestProvisionerJobLogs(t *testing.T) {
 		}
 	})
 }
+
+func TestProvisionerJobConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deployment", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.Provisioner.MaxConcurrentJobsPerUser = 1
+		client, closer := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			DeploymentValues:         dv,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		// Without a provisioner, jobs stay pending.
+		require.NoError(t, closer.Close())
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "first",
+		})
+		require.NoError(t, err)
+
+		_, err = member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "second",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "limit of 1 concurrent provisioner jobs")
+		require.Equal(t, codersdk.ResponseCodeJobLimitReached, apiErr.Code)
+
+		// The limit applies per user.
+		_, err = client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "owner",
+		})
+		require.NoError(t, err)
+
+		// Template imports count towards the limit too.
+		_, err = client.CreateTemplateVersion(ctx, owner.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			FileID:        version.Job.FileID,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		t.Parallel()
+		client, closer := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		limited := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		version = coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		unlimited := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		limited, err := client.UpdateTemplateMeta(ctx, limited.ID, codersdk.UpdateTemplateMeta{
+			MaxConcurrentJobsPerUser: ptr.Ref[int32](1),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(1), limited.MaxConcurrentJobsPerUser)
+
+		// Without a provisioner, jobs stay pending.
+		require.NoError(t, closer.Close())
+
+		_, err = member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: limited.ID,
+			Name:       "first",
+		})
+		require.NoError(t, err)
+
+		_, err = member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: limited.ID,
+			Name:       "second",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// Other templates are not affected.
+		_, err = member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: unlimited.ID,
+			Name:       "third",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("InvalidTemplateLimit", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxConcurrentJobsPerUser: ptr.Ref[int32](-1),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -3,6 +3,8 @@ package provisionerkey
 import (
 	"crypto/sha256"
 	"crypto/subtle"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -16,7 +18,12 @@ const (
 	secretLength = 43
 )
 
-func New(organizationID uuid.UUID, name string, tags map[string]string) (database.InsertProvisionerKeyParams, string, error) {
+var (
+	ErrInvalid = xerrors.New("provisioner key invalid")
+	ErrExpired = xerrors.New("provisioner key expired")
+)
+
+func New(organizationID uuid.UUID, name string, tags map[string]string, expiresAt *time.Time) (database.InsertProvisionerKeyParams, string, error) {
 	secret, err := cryptorand.String(secretLength)
 	if err != nil {
 		return database.InsertProvisionerKeyParams{}, "", xerrors.Errorf("generate secret: %w", err)
@@ -26,14 +33,57 @@ func New(organizationID uuid.UUID, name string, tags map[string]string) (databas
 		tags = map[string]string{}
 	}
 
-	return database.InsertProvisionerKeyParams{
+	params := database.InsertProvisionerKeyParams{
 		ID:             uuid.New(),
 		CreatedAt:      dbtime.Now(),
 		OrganizationID: organizationID,
 		Name:           name,
 		HashedSecret:   HashSecret(secret),
 		Tags:           tags,
-	}, secret, nil
+	}
+	if expiresAt != nil {
+		params.ExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+	return params, secret, nil
+}
+
+// Rotate generates a new secret for an existing key. The current secret stays
+// valid until now+overlap.
+func Rotate(key database.ProvisionerKey, now time.Time, overlap time.Duration, expiresAt *time.Time) (database.RotateProvisionerKeyParams, string, error) {
+	secret, err := cryptorand.String(secretLength)
+	if err != nil {
+		return database.RotateProvisionerKeyParams{}, "", xerrors.Errorf("generate secret: %w", err)
+	}
+
+	params := database.RotateProvisionerKeyParams{
+		ID:           key.ID,
+		HashedSecret: HashSecret(secret),
+	}
+	if overlap > 0 {
+		params.PreviousSecretExpiresAt = sql.NullTime{Time: now.Add(overlap), Valid: true}
+	}
+	if expiresAt != nil {
+		params.ExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+	return params, secret, nil
+}
+
+// Verify checks that hashedSecret authenticates the given key at the provided
+// time. The previous secret of a rotated key is accepted until the end of the
+// rotation overlap window.
+func Verify(key database.ProvisionerKey, hashedSecret []byte, now time.Time) error {
+	if Compare(key.HashedSecret, hashedSecret) {
+		if len(key.PreviousHashedSecret) == 0 || Compare(key.PreviousHashedSecret, hashedSecret) {
+			return ErrInvalid
+		}
+		if !key.PreviousSecretExpiresAt.Valid || !now.Before(key.PreviousSecretExpiresAt.Time) {
+			return ErrExpired
+		}
+	}
+	if key.ExpiresAt.Valid && !now.Before(key.ExpiresAt.Time) {
+		return ErrExpired
+	}
+	return nil
 }
 
 func Validate(token string) error {
@@ -0,0 +1,173 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get provisioner reservations
+// @ID get-provisioner-reservations
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.ProvisionerReservation
+// @Router /organizations/{organization}/provisionerreservations [get]
+func (api *API) provisionerReservations(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	reservations, err := api.Database.GetProvisionerReservationsByOrganization(ctx, database.GetProvisionerReservationsByOrganizationParams{
+		OrganizationID: org.ID,
+		EndsAfter:      dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner reservations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(reservations, convertProvisionerReservation))
+}
+
+// @Summary Create provisioner reservation
+// @ID create-provisioner-reservation
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateProvisionerReservationRequest true "Create provisioner reservation request"
+// @Success 201 {object} codersdk.ProvisionerReservation
+// @Router /organizations/{organization}/provisionerreservations [post]
+func (api *API) postProvisionerReservation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	var req codersdk.CreateProvisionerReservationRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validations []codersdk.ValidationError
+	if len(req.Tags) == 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "tags",
+			Detail: "At least one tag is required.",
+		})
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "ends_at",
+			Detail: "Must be after starts_at.",
+		})
+	} else if !req.EndsAt.After(dbtime.Now()) {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "ends_at",
+			Detail: "Must be in the future.",
+		})
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid provisioner reservation.",
+			Validations: validations,
+		})
+		return
+	}
+
+	reservation, err := api.Database.InsertProvisionerReservation(ctx, database.InsertProvisionerReservationParams{
+		ID:             uuid.New(),
+		OrganizationID: org.ID,
+		Tags:           req.Tags,
+		Slots:          req.Slots,
+		StartsAt:       dbtime.Time(req.StartsAt),
+		EndsAt:         dbtime.Time(req.EndsAt),
+		CreatedAt:      dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating provisioner reservation.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertProvisionerReservation(reservation))
+}
+
+// @Summary Delete provisioner reservation
+// @ID delete-provisioner-reservation
+// @Security CoderSessionToken
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param reservation path string true "Reservation ID" format(uuid)
+// @Success 204
+// @Router /organizations/{organization}/provisionerreservations/{reservation} [delete]
+func (api *API) deleteProvisionerReservation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	reservationID, ok := httpmw.ParseUUIDParam(rw, r, "reservation")
+	if !ok {
+		return
+	}
+
+	reservation, err := api.Database.GetProvisionerReservationByID(ctx, reservationID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner reservation.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if reservation.OrganizationID != org.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	err = api.Database.DeleteProvisionerReservation(ctx, reservation.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting provisioner reservation.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertProvisionerReservation(reservation database.ProvisionerReservation) codersdk.ProvisionerReservation {
+	return codersdk.ProvisionerReservation{
+		ID:             reservation.ID,
+		OrganizationID: reservation.OrganizationID,
+		Tags:           reservation.Tags,
+		Slots:          reservation.Slots,
+		StartsAt:       reservation.StartsAt,
+		EndsAt:         reservation.EndsAt,
+		CreatedAt:      reservation.CreatedAt,
+	}
+}
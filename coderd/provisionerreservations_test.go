@@ -0,0 +1,153 @@
+package coderd_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProvisionerReservations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateListDelete", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		now := time.Now()
+		reservation, err := client.CreateProvisionerReservation(ctx, owner.OrganizationID, codersdk.CreateProvisionerReservationRequest{
+			Tags:     map[string]string{"purpose": "training"},
+			Slots:    5,
+			StartsAt: now,
+			EndsAt:   now.Add(2 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(5), reservation.Slots)
+		require.Equal(t, map[string]string{"purpose": "training"}, reservation.Tags)
+
+		reservations, err := client.ProvisionerReservations(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, reservations, 1)
+		require.Equal(t, reservation.ID, reservations[0].ID)
+
+		err = client.DeleteProvisionerReservation(ctx, owner.OrganizationID, reservation.ID)
+		require.NoError(t, err)
+
+		reservations, err = client.ProvisionerReservations(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, reservations)
+
+		err = client.DeleteProvisionerReservation(ctx, owner.OrganizationID, reservation.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		now := time.Now()
+		_, err := client.CreateProvisionerReservation(ctx, owner.OrganizationID, codersdk.CreateProvisionerReservationRequest{
+			Tags:     map[string]string{},
+			Slots:    1,
+			StartsAt: now,
+			EndsAt:   now.Add(-time.Hour),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		now := time.Now()
+		_, err := member.CreateProvisionerReservation(ctx, owner.OrganizationID, codersdk.CreateProvisionerReservationRequest{
+			Tags:     map[string]string{"purpose": "training"},
+			Slots:    1,
+			StartsAt: now,
+			EndsAt:   now.Add(time.Hour),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Acquire", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db, _ := dbtestutil.NewDB(t)
+		org := dbgen.Organization(t, db, database.Organization{})
+
+		trainingTags := database.StringMap{"purpose": "training"}
+		now := dbtime.Now()
+		_, err := db.InsertProvisionerReservation(ctx, database.InsertProvisionerReservationParams{
+			ID:             uuid.New(),
+			OrganizationID: org.ID,
+			Tags:           trainingTags,
+			Slots:          1,
+			StartsAt:       now.Add(-time.Minute),
+			EndsAt:         now.Add(time.Hour),
+			CreatedAt:      now,
+		})
+		require.NoError(t, err)
+
+		// A single daemon is able to run training jobs, so its only slot is
+		// reserved.
+		dbgen.ProvisionerDaemon(t, db, database.ProvisionerDaemon{
+			OrganizationID: org.ID,
+			Tags:           trainingTags,
+		})
+		regular := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			Tags:           database.StringMap{},
+		})
+		training := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			CreatedAt:      regular.CreatedAt.Add(time.Second),
+			Tags:           trainingTags,
+		})
+
+		provisionerTags, err := json.Marshal(trainingTags)
+		require.NoError(t, err)
+		acquire := func() (database.ProvisionerJob, error) {
+			return db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+				OrganizationID:  org.ID,
+				StartedAt:       sql.NullTime{Time: dbtime.Now(), Valid: true},
+				WorkerID:        uuid.NullUUID{UUID: uuid.New(), Valid: true},
+				Types:           database.AllProvisionerTypeValues(),
+				ProvisionerTags: provisionerTags,
+			})
+		}
+
+		// The regular job is older, but the training job is acquired first.
+		job, err := acquire()
+		require.NoError(t, err)
+		require.Equal(t, training.ID, job.ID)
+
+		_, err = acquire()
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
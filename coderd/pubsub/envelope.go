@@ -0,0 +1,134 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// LegacyVersion is the version of messages that were published as bare JSON
+// payloads, before messages were wrapped in an Envelope.
+const LegacyVersion = 0
+
+// Envelope wraps the payload of a versioned pubsub message.
+type Envelope struct {
+	Type    string          `json:"type"`
+	Version int             `json:"version"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+var (
+	messageTypesMu sync.Mutex
+	messageTypes   = map[string]struct{}{}
+)
+
+// MessageType encodes and decodes the messages of a pubsub channel. Messages
+// are published at the current version of the type, and subscribers decode
+// older versions with the decoders registered for them, so coderd replicas
+// running different versions can share a channel during rolling upgrades.
+//
+// Payloads of newer versions than the current one are decoded as the current
+// version, so a new version may only add fields. Changing the meaning of an
+// existing field requires a new message type.
+type MessageType[T any] struct {
+	name     string
+	version  int
+	decoders map[int]func(payload json.RawMessage) (T, error)
+}
+
+// NewMessageType registers a message type with the given name, published at
+// the given version. It panics if the name is already registered.
+func NewMessageType[T any](name string, version int) *MessageType[T] {
+	if version <= LegacyVersion {
+		panic(fmt.Sprintf("developer error: message type %q must have a version greater than %d", name, LegacyVersion))
+	}
+
+	messageTypesMu.Lock()
+	defer messageTypesMu.Unlock()
+	if _, ok := messageTypes[name]; ok {
+		panic(fmt.Sprintf("developer error: message type %q is already registered", name))
+	}
+	messageTypes[name] = struct{}{}
+
+	return &MessageType[T]{
+		name:     name,
+		version:  version,
+		decoders: map[int]func(payload json.RawMessage) (T, error){},
+	}
+}
+
+// WithDecoder registers the decoder for payloads of an older version of the
+// message type. The decoder for LegacyVersion receives messages that were
+// published without an envelope.
+func (m *MessageType[T]) WithDecoder(version int, decode func(payload json.RawMessage) (T, error)) *MessageType[T] {
+	if version >= m.version {
+		panic(fmt.Sprintf("developer error: message type %q decodes version %d natively", m.name, version))
+	}
+	m.decoders[version] = decode
+	return m
+}
+
+// Name returns the name of the message type.
+func (m *MessageType[T]) Name() string {
+	return m.name
+}
+
+// Version returns the version messages of the type are published at.
+func (m *MessageType[T]) Version() int {
+	return m.version
+}
+
+// Encode wraps the payload in an envelope of the current version.
+func (m *MessageType[T]) Encode(payload T) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal %s payload: %w", m.name, err)
+	}
+	message, err := json.Marshal(Envelope{
+		Type:    m.name,
+		Version: m.version,
+		Payload: data,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("marshal %s envelope: %w", m.name, err)
+	}
+	return message, nil
+}
+
+// Decode decodes a message of any version of the type. Messages without an
+// envelope are decoded as LegacyVersion.
+func (m *MessageType[T]) Decode(message []byte) (T, error) {
+	var empty T
+
+	var envelope Envelope
+	if err := json.Unmarshal(message, &envelope); err != nil || envelope.Type == "" {
+		envelope = Envelope{
+			Type:    m.name,
+			Version: LegacyVersion,
+			Payload: message,
+		}
+	}
+	if envelope.Type != m.name {
+		return empty, xerrors.Errorf("message of type %q is not of type %q", envelope.Type, m.name)
+	}
+
+	if envelope.Version >= m.version {
+		var payload T
+		if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+			return empty, xerrors.Errorf("unmarshal %s payload of version %d: %w", m.name, envelope.Version, err)
+		}
+		return payload, nil
+	}
+
+	decode, ok := m.decoders[envelope.Version]
+	if !ok {
+		return empty, xerrors.Errorf("unsupported version %d of message type %q", envelope.Version, m.name)
+	}
+	payload, err := decode(envelope.Payload)
+	if err != nil {
+		return empty, xerrors.Errorf("decode %s payload of version %d: %w", m.name, envelope.Version, err)
+	}
+	return payload, nil
+}
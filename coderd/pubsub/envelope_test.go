@@ -0,0 +1,96 @@
+package pubsub_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/pubsub"
+)
+
+type testMessage struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestMessageType(t *testing.T) {
+	t.Parallel()
+
+	// Version 1 of the message had a single "label" field that was renamed
+	// to "name" in version 2.
+	messageType := pubsub.NewMessageType[testMessage]("test_message", 2).
+		WithDecoder(pubsub.LegacyVersion, func(payload json.RawMessage) (testMessage, error) {
+			var m testMessage
+			err := json.Unmarshal(payload, &m)
+			return m, err
+		}).
+		WithDecoder(1, func(payload json.RawMessage) (testMessage, error) {
+			var v1 struct {
+				Label string `json:"label"`
+			}
+			err := json.Unmarshal(payload, &v1)
+			return testMessage{Name: v1.Label}, err
+		})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := messageType.Encode(testMessage{Name: "hello", Count: 2})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"type":"test_message","version":2,"payload":{"name":"hello","count":2}}`, string(data))
+
+		m, err := messageType.Decode(data)
+		require.NoError(t, err)
+		require.Equal(t, testMessage{Name: "hello", Count: 2}, m)
+	})
+
+	t.Run("Legacy", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := messageType.Decode([]byte(`{"name":"hello","count":2}`))
+		require.NoError(t, err)
+		require.Equal(t, testMessage{Name: "hello", Count: 2}, m)
+	})
+
+	t.Run("OlderVersion", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := messageType.Decode([]byte(`{"type":"test_message","version":1,"payload":{"label":"hello"}}`))
+		require.NoError(t, err)
+		require.Equal(t, testMessage{Name: "hello"}, m)
+	})
+
+	t.Run("NewerVersion", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := messageType.Decode([]byte(`{"type":"test_message","version":3,"payload":{"name":"hello","count":2,"extra":true}}`))
+		require.NoError(t, err)
+		require.Equal(t, testMessage{Name: "hello", Count: 2}, m)
+	})
+
+	t.Run("OtherType", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := messageType.Decode([]byte(`{"type":"other_message","version":2,"payload":{}}`))
+		require.Error(t, err)
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		t.Parallel()
+
+		require.Panics(t, func() {
+			_ = pubsub.NewMessageType[testMessage]("test_message", 1)
+		})
+	})
+}
+
+func TestMessageTypeUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	messageType := pubsub.NewMessageType[testMessage]("test_message_without_decoders", 2)
+	_, err := messageType.Decode([]byte(`{"type":"test_message_without_decoders","version":1,"payload":{}}`))
+	require.ErrorContains(t, err, "unsupported version 1")
+	_, err = messageType.Decode([]byte(`{"name":"hello"}`))
+	require.ErrorContains(t, err, "unsupported version 0")
+}
@@ -0,0 +1,17 @@
+package pubsub
+
+import (
+	"encoding/json"
+
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+// ProvisionerJobLogsNotify is published on
+// provisionersdk.ProvisionerJobLogsNotifyChannel when the logs of a job
+// change. Version 1 wraps the legacy payload unchanged.
+var ProvisionerJobLogsNotify = NewMessageType[provisionersdk.ProvisionerJobLogsNotifyMessage]("provisioner_job_logs_notify", 1).
+	WithDecoder(LegacyVersion, func(payload json.RawMessage) (provisionersdk.ProvisionerJobLogsNotifyMessage, error) {
+		var n provisionersdk.ProvisionerJobLogsNotifyMessage
+		err := json.Unmarshal(payload, &n)
+		return n, err
+	})
@@ -378,6 +378,30 @@ func TestAuthorizeDomain(t *testing.T) {
 			actions: []policy.Action{policy.ActionUpdate},
 			allow:   true,
 		},
+		{
+			// Objects shared into the organization of the user are readable,
+			// even though the user is not a member of the owning organization.
+			resource: ResourceTemplate.InOrg(unuseID).WithGroupACL(map[string][]policy.Action{
+				defOrg.String(): {policy.ActionRead},
+			}),
+			actions: []policy.Action{policy.ActionRead},
+			allow:   true,
+		},
+		{
+			resource: ResourceTemplate.InOrg(unuseID).WithGroupACL(map[string][]policy.Action{
+				defOrg.String(): {policy.ActionRead},
+			}),
+			actions: []policy.Action{policy.ActionUpdate, policy.ActionDelete, policy.ActionUse},
+			allow:   false,
+		},
+		{
+			// Sharing into another organization does not grant access.
+			resource: ResourceTemplate.InOrg(unuseID).WithGroupACL(map[string][]policy.Action{
+				unuseID.String(): {policy.ActionRead},
+			}),
+			actions: []policy.Action{policy.ActionRead},
+			allow:   false,
+		},
 	})
 
 	testAuthorize(t, "Member", user, []authTestCase{
@@ -246,6 +246,16 @@ var (
 		Type: "replicas",
 	}
 
+	// ResourceServiceAccount
+	// Valid Actions
+	//  - "ActionCreate" :: create a service account
+	//  - "ActionDelete" :: delete a service account
+	//  - "ActionRead" :: read service accounts
+	//  - "ActionUpdate" :: update a service account and manage its tokens
+	ResourceServiceAccount = Object{
+		Type: "service_account",
+	}
+
 	// ResourceSystem
 	// Valid Actions
 	//  - "ActionCreate" :: create system resources
@@ -390,6 +400,7 @@ func AllResources() []Objecter {
 		ResourceProvisionerDaemon,
 		ResourceProvisionerJobs,
 		ResourceReplicas,
+		ResourceServiceAccount,
 		ResourceSystem,
 		ResourceTailnetCoordinator,
 		ResourceTemplate,
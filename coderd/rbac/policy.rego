@@ -335,6 +335,16 @@ acl_allow if {
 	[input.action, "*"][_] in perms
 }
 
+# ACL for the 'all_users' group of other organizations. Objects can be shared
+# into organizations they are not owned by, which grants the members of those
+# organizations the listed actions.
+acl_allow if {
+	org := org_members[_]
+	org != input.object.org_owner
+	perms := input.object.acl_group_list[org]
+	[input.action, "*"][_] in perms
+}
+
 # -------------------
 # Final Allow
 #
@@ -215,6 +215,14 @@ var RBACPermissions = map[string]PermissionDefinition{
 			ActionDelete: actDef("delete member"),
 		},
 	},
+	"service_account": {
+		Actions: map[Action]ActionDefinition{
+			ActionCreate: actDef("create a service account"),
+			ActionRead:   actDef("read service accounts"),
+			ActionUpdate: actDef("update a service account and manage its tokens"),
+			ActionDelete: actDef("delete a service account"),
+		},
+	},
 	"debug_info": {
 		Actions: map[Action]ActionDefinition{
 			ActionRead: actDef("access to debug routes"),
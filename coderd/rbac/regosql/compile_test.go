@@ -242,6 +242,16 @@ neq(input.object.owner, "");
 				p("false")),
 			VariableConverter: regosql.TemplateConverter(),
 		},
+		{
+			Name: "TemplateSharedOrganization",
+			Queries: []string{
+				`"3bf82434-e40b-44ae-b3d8-d0115bba9bad" != input.object.org_owner;
+"read" in input.object.acl_group_list["3bf82434-e40b-44ae-b3d8-d0115bba9bad"]`,
+			},
+			ExpectedSQL: p(p("'3bf82434-e40b-44ae-b3d8-d0115bba9bad' != t.organization_id :: text") + " AND " +
+				p(`((SELECT COALESCE(jsonb_object_agg(shared_org_id :: text, '["read"]' :: jsonb), '{}' :: jsonb) FROM unnest(t.shared_organization_ids) AS shared_org_id) || group_acl)->'3bf82434-e40b-44ae-b3d8-d0115bba9bad' ? 'read'`)),
+			VariableConverter: regosql.TemplateConverter(),
+		},
 		{
 			Name: "UserNoOrgOwner",
 			Queries: []string{
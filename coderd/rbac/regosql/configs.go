@@ -22,6 +22,11 @@ func userACLMatcher(m sqltypes.VariableMatcher) sqltypes.VariableMatcher {
 	return ACLGroupMatcher(m, "user_acl", []string{"input", "object", "acl_user_list"})
 }
 
+// templateGroupACL grants the 'Everyone' group of each organization a template
+// is shared into read access, in addition to the group ACL of the template.
+// This must match database.Template.RBACObject.
+const templateGroupACL = `((SELECT COALESCE(jsonb_object_agg(shared_org_id :: text, '["read"]' :: jsonb), '{}' :: jsonb) FROM unnest(t.shared_organization_ids) AS shared_org_id) || group_acl)`
+
 func TemplateConverter() *sqltypes.VariableConverter {
 	matcher := sqltypes.NewVariableConverter().RegisterMatcher(
 		resourceIDMatcher(),
@@ -30,7 +35,7 @@ func TemplateConverter() *sqltypes.VariableConverter {
 		sqltypes.AlwaysFalse(userOwnerMatcher()),
 	)
 	matcher.RegisterMatcher(
-		groupACLMatcher(matcher),
+		ACLGroupMatcher(matcher, templateGroupACL, []string{"input", "object", "acl_group_list"}),
 		userACLMatcher(matcher),
 	)
 	return matcher
@@ -74,8 +79,8 @@ func WorkspaceConverter() *sqltypes.VariableConverter {
 		userOwnerMatcher(),
 	)
 	matcher.RegisterMatcher(
-		sqltypes.AlwaysFalse(groupACLMatcher(matcher)),
-		sqltypes.AlwaysFalse(userACLMatcher(matcher)),
+		ACLGroupMatcher(matcher, "workspaces.group_acl", []string{"input", "object", "acl_group_list"}),
+		ACLGroupMatcher(matcher, "workspaces.user_acl", []string{"input", "object", "acl_user_list"}),
 	)
 
 	return matcher
@@ -61,8 +61,33 @@ const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
 	ScopeNoUserData         ScopeName = "no_user_data"
+	ScopeWorkspaceRead      ScopeName = "workspace:read"
+	ScopeBuildCreate        ScopeName = "build:create"
+	ScopeTemplateAdmin      ScopeName = "template:admin"
 )
 
+// scopeReadContext are the permissions that the fine-grained scopes share.
+// They allow looking up the users and organizations that resources belong to,
+// which almost every request does.
+var scopeReadContext = map[string][]policy.Action{
+	ResourceUser.Type:               {policy.ActionRead},
+	ResourceOrganization.Type:       {policy.ActionRead},
+	ResourceOrganizationMember.Type: {policy.ActionRead},
+}
+
+// scopePermissions returns the permissions of a fine-grained scope, which are
+// the shared read permissions plus the given ones.
+func scopePermissions(perms map[string][]policy.Action) []Permission {
+	merged := make(map[string][]policy.Action, len(scopeReadContext)+len(perms))
+	for resource, actions := range scopeReadContext {
+		merged[resource] = append(merged[resource], actions...)
+	}
+	for resource, actions := range perms {
+		merged[resource] = append(merged[resource], actions...)
+	}
+	return Permissions(merged)
+}
+
 // TODO: Support passing in scopeID list for allowlisting resources.
 var builtinScopes = map[ScopeName]Scope{
 	// ScopeAll is a special scope that allows access to all resources. During
@@ -103,6 +128,57 @@ var builtinScopes = map[ScopeName]Scope{
 		},
 		AllowIDList: []string{policy.WildcardSymbol},
 	},
+
+	ScopeWorkspaceRead: {
+		Role: Role{
+			Identifier:  RoleIdentifier{Name: fmt.Sprintf("Scope_%s", ScopeWorkspaceRead)},
+			DisplayName: "Read workspaces and their builds",
+			Site: scopePermissions(map[string][]policy.Action{
+				ResourceWorkspace.Type:        {policy.ActionRead},
+				ResourceWorkspaceDormant.Type: {policy.ActionRead},
+				ResourceTemplate.Type:         {policy.ActionRead},
+				ResourceProvisionerJobs.Type:  {policy.ActionRead},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
+		AllowIDList: []string{policy.WildcardSymbol},
+	},
+
+	// ScopeBuildCreate can start, stop and update existing workspaces, but
+	// not create or delete them.
+	ScopeBuildCreate: {
+		Role: Role{
+			Identifier:  RoleIdentifier{Name: fmt.Sprintf("Scope_%s", ScopeBuildCreate)},
+			DisplayName: "Create builds of workspaces",
+			Site: scopePermissions(map[string][]policy.Action{
+				ResourceWorkspace.Type:        {policy.ActionRead, policy.ActionUpdate, policy.ActionWorkspaceStart, policy.ActionWorkspaceStop},
+				ResourceWorkspaceDormant.Type: {policy.ActionRead},
+				ResourceTemplate.Type:         {policy.ActionRead},
+				ResourceProvisionerJobs.Type:  {policy.ActionRead},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
+		AllowIDList: []string{policy.WildcardSymbol},
+	},
+
+	ScopeTemplateAdmin: {
+		Role: Role{
+			Identifier:  RoleIdentifier{Name: fmt.Sprintf("Scope_%s", ScopeTemplateAdmin)},
+			DisplayName: "Manage templates and their versions",
+			Site: scopePermissions(map[string][]policy.Action{
+				ResourceTemplate.Type:          {policy.WildcardSymbol},
+				ResourceFile.Type:              {policy.ActionCreate, policy.ActionRead},
+				ResourceGroup.Type:             {policy.ActionRead},
+				ResourceProvisionerDaemon.Type: {policy.ActionRead},
+				ResourceProvisionerJobs.Type:   {policy.ActionRead},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
+		AllowIDList: []string{policy.WildcardSymbol},
+	},
 }
 
 type ExpandableScope interface {
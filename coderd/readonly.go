@@ -0,0 +1,127 @@
+package coderd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/readonly"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get read-only mode settings
+// @ID get-read-only-mode-settings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.ReadOnlySettings
+// @Router /deployment/read-only [get]
+func (api *API) readOnlySettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	settingsJSON, err := api.Database.GetReadOnlySettings(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch read-only settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	settings, err := readonly.ParseSettings(settingsJSON)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to unmarshal read-only settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, settings)
+}
+
+// @Summary Update read-only mode settings
+// @ID update-read-only-mode-settings
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags General
+// @Param request body codersdk.ReadOnlySettings true "Read-only settings request"
+// @Success 200 {object} codersdk.ReadOnlySettings
+// @Success 304
+// @Router /deployment/read-only [put]
+func (api *API) putReadOnlySettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var settings codersdk.ReadOnlySettings
+	if !httpapi.Read(ctx, rw, r, &settings) {
+		return
+	}
+
+	settingsJSON, err := json.Marshal(&settings)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to marshal read-only settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	currentSettingsJSON, err := api.Database.GetReadOnlySettings(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch current read-only settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if bytes.Equal(settingsJSON, []byte(currentSettingsJSON)) {
+		// See: https://www.rfc-editor.org/rfc/rfc7232#section-4.1
+		httpapi.Write(ctx, rw, http.StatusNotModified, nil)
+		return
+	}
+
+	auditor := api.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.ReadOnlySettings](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+
+	aReq.New = database.ReadOnlySettings{
+		ID:      uuid.New(),
+		Enabled: settings.Enabled,
+	}
+
+	err = api.Database.UpsertReadOnlySettings(ctx, string(settingsJSON))
+	if err != nil {
+		if rbac.IsUnauthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update read-only settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Apply the change on this replica right away so the response reflects
+	// it, and let the other replicas know.
+	api.ReadOnlyMode.Set(settings.Enabled)
+	if err := api.Pubsub.Publish(readonly.EventSettingsUpdated, []byte{}); err != nil {
+		api.Logger.Warn(ctx, "failed to publish read-only settings update", slog.Error(err))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, settings)
+}
@@ -0,0 +1,31 @@
+package readonly
+
+import (
+	"slices"
+
+	"golang.org/x/xerrors"
+	"storj.io/drpc"
+)
+
+// ErrReadOnly is returned to dRPC clients for calls rejected while the
+// deployment is in read-only mode.
+var ErrReadOnly = xerrors.New("this deployment is in read-only mode for maintenance, retry later")
+
+// DRPCHandler rejects RPCs that may mutate state while the deployment is in
+// read-only mode. Agents and provisioner daemons speak dRPC over websockets,
+// which are opened with GET requests the HTTP middleware lets through, so
+// their RPCs are gated here instead.
+type DRPCHandler struct {
+	Handler drpc.Handler
+	Mode    *Mode
+	// ReadRPCs are served while the deployment is in read-only mode, e.g.
+	// "/coder.agent.v2.Agent/GetManifest". All other RPCs are rejected.
+	ReadRPCs []string
+}
+
+func (h *DRPCHandler) HandleRPC(stream drpc.Stream, rpc string) error {
+	if h.Mode.Enabled() && !slices.Contains(h.ReadRPCs, rpc) {
+		return ErrReadOnly
+	}
+	return h.Handler.HandleRPC(stream, rpc)
+}
@@ -0,0 +1,73 @@
+package readonly_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"storj.io/drpc"
+
+	"github.com/coder/coder/v2/coderd/readonly"
+)
+
+type handlerFunc func(stream drpc.Stream, rpc string) error
+
+func (f handlerFunc) HandleRPC(stream drpc.Stream, rpc string) error {
+	return f(stream, rpc)
+}
+
+func TestDRPCHandler(t *testing.T) {
+	t.Parallel()
+
+	const (
+		readRPC  = "/coder.agent.v2.Agent/GetManifest"
+		writeRPC = "/coder.agent.v2.Agent/UpdateStats"
+	)
+
+	tests := []struct {
+		Name     string
+		Enabled  bool
+		RPC      string
+		Rejected bool
+	}{
+		{Name: "DisabledWrite", Enabled: false, RPC: writeRPC},
+		{Name: "EnabledRead", Enabled: true, RPC: readRPC},
+		{Name: "EnabledWrite", Enabled: true, RPC: writeRPC, Rejected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var mode readonly.Mode
+			mode.Set(tt.Enabled)
+
+			handled := false
+			handler := &readonly.DRPCHandler{
+				Handler: handlerFunc(func(drpc.Stream, string) error {
+					handled = true
+					return nil
+				}),
+				Mode:     &mode,
+				ReadRPCs: []string{readRPC},
+			}
+
+			err := handler.HandleRPC(nil, tt.RPC)
+			if tt.Rejected {
+				require.ErrorIs(t, err, readonly.ErrReadOnly)
+				require.False(t, handled)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, handled)
+		})
+	}
+
+	t.Run("NilMode", func(t *testing.T) {
+		t.Parallel()
+
+		handler := &readonly.DRPCHandler{
+			Handler: handlerFunc(func(drpc.Stream, string) error { return nil }),
+		}
+		require.NoError(t, handler.HandleRPC(nil, writeRPC))
+	})
+}
@@ -0,0 +1,147 @@
+// Package readonly tracks whether the deployment is in read-only mode. While
+// it is, the API rejects requests that would mutate state and background jobs
+// that write to the database are paused.
+package readonly
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+// EventSettingsUpdated is published when the read-only settings change, so
+// every replica picks up the new state without waiting for the next poll.
+const EventSettingsUpdated = "read_only_settings_updated"
+
+// pollInterval bounds how long a replica can act on stale settings if it
+// misses a pubsub event.
+const pollInterval = 30 * time.Second
+
+// Mode holds the current read-only state of the deployment. A nil *Mode is
+// never enabled, so callers that don't care about read-only mode can leave it
+// unset. The zero value is usable but never refreshes from the database.
+type Mode struct {
+	db      database.Store
+	logger  slog.Logger
+	enabled atomic.Bool
+
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+// New loads the read-only settings from the database and keeps them up to
+// date, refreshing when the settings are updated on any replica and
+// periodically as a fallback. It is the caller's responsibility to call Close
+// on the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, ps pubsub.Pubsub, clk quartz.Clock) *Mode {
+	ctx, cancel := context.WithCancel(ctx)
+	//nolint:gocritic // The system needs to read the settings without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	m := &Mode{
+		db:     db,
+		logger: logger,
+		cancel: cancel,
+		closed: make(chan struct{}),
+	}
+	// Load the settings synchronously so the API never starts out accepting
+	// mutations while the deployment is in read-only mode.
+	m.refresh(ctx)
+
+	updated := make(chan struct{}, 1)
+	unsubscribe, err := ps.Subscribe(EventSettingsUpdated, func(context.Context, []byte) {
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		logger.Warn(ctx, "failed to subscribe to read-only settings updates, falling back to polling", slog.Error(err))
+		unsubscribe = func() {}
+	}
+
+	go func() {
+		defer close(m.closed)
+		defer unsubscribe()
+		ticker := clk.NewTicker(pollInterval, "readonly")
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-updated:
+			}
+			m.refresh(ctx)
+		}
+	}()
+	return m
+}
+
+// Enabled reports whether the deployment is in read-only mode.
+func (m *Mode) Enabled() bool {
+	if m == nil {
+		return false
+	}
+	return m.enabled.Load()
+}
+
+// Set updates the state held by this replica. Other replicas are notified
+// through EventSettingsUpdated.
+func (m *Mode) Set(enabled bool) {
+	if m == nil {
+		return
+	}
+	if m.enabled.Swap(enabled) != enabled {
+		m.logger.Info(context.Background(), "deployment read-only mode changed", slog.F("enabled", enabled))
+	}
+}
+
+func (m *Mode) Close() error {
+	if m == nil || m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+	<-m.closed
+	return nil
+}
+
+func (m *Mode) refresh(ctx context.Context) {
+	raw, err := m.db.GetReadOnlySettings(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			// Keep the last known state rather than guessing.
+			m.logger.Warn(ctx, "failed to fetch read-only settings", slog.Error(err))
+		}
+		return
+	}
+	settings, err := ParseSettings(raw)
+	if err != nil {
+		m.logger.Error(ctx, "failed to parse read-only settings", slog.Error(err))
+		return
+	}
+	m.Set(settings.Enabled)
+}
+
+// ParseSettings decodes the read-only settings as stored in the database.
+func ParseSettings(raw string) (codersdk.ReadOnlySettings, error) {
+	var settings codersdk.ReadOnlySettings
+	if len(raw) == 0 {
+		return settings, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		return codersdk.ReadOnlySettings{}, xerrors.Errorf("unmarshal read-only settings: %w", err)
+	}
+	return settings, nil
+}
@@ -0,0 +1,87 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestReadOnlySettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PermissionDenied", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		// Members can see whether the deployment is read-only...
+		settings, err := memberClient.ReadOnlySettings(ctx)
+		require.NoError(t, err)
+		require.False(t, settings.Enabled)
+
+		// ...but can't change it.
+		err = memberClient.PutReadOnlySettings(ctx, codersdk.ReadOnlySettings{Enabled: true})
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusForbidden, sdkError.StatusCode())
+	})
+
+	t.Run("RejectsMutations", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, api := coderdtest.NewWithAPI(t, nil)
+		firstUser := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		err := client.PutReadOnlySettings(ctx, codersdk.ReadOnlySettings{Enabled: true})
+		require.NoError(t, err)
+		require.True(t, api.ReadOnlyMode.Enabled())
+
+		settings, err := client.ReadOnlySettings(ctx)
+		require.NoError(t, err)
+		require.True(t, settings.Enabled)
+
+		// Reads are still served.
+		_, err = client.Organization(ctx, firstUser.OrganizationID)
+		require.NoError(t, err)
+
+		// Mutations are rejected.
+		_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusServiceUnavailable, sdkError.StatusCode())
+		require.Contains(t, sdkError.Message, "read-only mode")
+
+		// The OAuth2 provider routes are outside of /api/v2, but are
+		// rejected too.
+		res, err := client.Request(ctx, http.MethodPost, "/oauth2/tokens", nil)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+
+		// Admins can still sign in to turn read-only mode off.
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.NoError(t, err)
+
+		// Read-only mode can be turned off again.
+		err = client.PutReadOnlySettings(ctx, codersdk.ReadOnlySettings{Enabled: false})
+		require.NoError(t, err)
+		require.False(t, api.ReadOnlyMode.Enabled())
+
+		_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+	})
+}
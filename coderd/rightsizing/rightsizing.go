@@ -0,0 +1,229 @@
+// Package rightsizing recommends resizing workspaces based on the resource
+// usage reported by their agents.
+package rightsizing
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// minimumBuckets is the number of usage intervals an agent needs to have
+	// reported before recommendations are made for it, one day's worth.
+	minimumBuckets = 96
+	// peakPercentile is the percentile of the interval maxima that is
+	// considered the peak usage, so that rare spikes are ignored.
+	peakPercentile = 0.95
+	// targetUtilization is the share of a resource that the peak usage
+	// should use after resizing.
+	targetUtilization = 0.7
+	// increaseUtilization is the share of a resource that the peak usage
+	// must reach for an increase to be recommended.
+	increaseUtilization = 0.9
+	// decreaseRatio is the share of the current total that the suggested
+	// total must be at most for a decrease to be recommended. This avoids
+	// recommending small decreases that aren't worth a rebuild.
+	decreaseRatio = 0.5
+
+	gibibyte = 1 << 30
+)
+
+var parameterPatterns = map[codersdk.WorkspaceRecommendationResource]*regexp.Regexp{
+	codersdk.WorkspaceRecommendationResourceCPU:    regexp.MustCompile(`(?i)cpu|core`),
+	codersdk.WorkspaceRecommendationResourceMemory: regexp.MustCompile(`(?i)mem|ram`),
+}
+
+// Usage summarizes the usage of a resource by an agent.
+type Usage struct {
+	// Total is the most recently reported total of the resource.
+	Total float64
+	// Average is the average usage over all samples.
+	Average float64
+	// Peak is the 95th percentile of the maximum usage of each interval.
+	Peak float64
+	// Buckets is the number of intervals the agent reported usage in.
+	Buckets int
+}
+
+// AgentUsage summarizes the usage of an agent.
+type AgentUsage struct {
+	AgentName string
+	CPU       Usage
+	Memory    Usage
+}
+
+// Summarize aggregates the resource usage of each agent, identified by name.
+// Rows must be ordered by agent name and interval start, as returned by
+// GetWorkspaceAgentResourceUsageByWorkspaceID.
+func Summarize(rows []database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow) []AgentUsage {
+	var summaries []AgentUsage
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].AgentName == rows[start].AgentName {
+			end++
+		}
+		agentRows := rows[start:end]
+		last := agentRows[len(agentRows)-1]
+
+		var (
+			samples            float64
+			cpuSum, memSum     float64
+			cpuMaxes, memMaxes = make([]float64, 0, len(agentRows)), make([]float64, 0, len(agentRows))
+		)
+		for _, row := range agentRows {
+			samples += float64(row.Samples)
+			cpuSum += row.CPUUsedAvg * float64(row.Samples)
+			memSum += float64(row.MemoryUsedAvg) * float64(row.Samples)
+			cpuMaxes = append(cpuMaxes, row.CPUUsedMax)
+			memMaxes = append(memMaxes, float64(row.MemoryUsedMax))
+		}
+		summary := AgentUsage{
+			AgentName: last.AgentName,
+			CPU: Usage{
+				Total:   last.CPUTotal,
+				Peak:    percentile(cpuMaxes, peakPercentile),
+				Buckets: len(agentRows),
+			},
+			Memory: Usage{
+				Total:   float64(last.MemoryTotal),
+				Peak:    percentile(memMaxes, peakPercentile),
+				Buckets: len(agentRows),
+			},
+		}
+		if samples > 0 {
+			summary.CPU.Average = cpuSum / samples
+			summary.Memory.Average = memSum / samples
+		}
+		summaries = append(summaries, summary)
+		start = end
+	}
+	return summaries
+}
+
+// Recommend returns a recommendation to resize a resource, or false if the
+// resource is sized well or there isn't enough usage to tell. CPU totals are
+// suggested in whole cores and memory totals in whole gibibytes.
+func Recommend(agentName string, resource codersdk.WorkspaceRecommendationResource, usage Usage) (codersdk.WorkspaceRecommendation, bool) {
+	if usage.Buckets < minimumBuckets || usage.Total <= 0 {
+		return codersdk.WorkspaceRecommendation{}, false
+	}
+
+	unit := 1.0
+	if resource == codersdk.WorkspaceRecommendationResourceMemory {
+		unit = gibibyte
+	}
+	suggested := max(math.Ceil(usage.Peak/targetUtilization/unit), 1) * unit
+
+	var direction codersdk.WorkspaceRecommendationDirection
+	switch {
+	case usage.Peak >= usage.Total*increaseUtilization && suggested > usage.Total:
+		direction = codersdk.WorkspaceRecommendationDirectionIncrease
+	case suggested <= usage.Total*decreaseRatio:
+		direction = codersdk.WorkspaceRecommendationDirectionDecrease
+	default:
+		return codersdk.WorkspaceRecommendation{}, false
+	}
+
+	return codersdk.WorkspaceRecommendation{
+		AgentName:        agentName,
+		Resource:         resource,
+		Direction:        direction,
+		CurrentTotal:     usage.Total,
+		SuggestedTotal:   suggested,
+		UsedAverage:      usage.Average,
+		UsedPeak:         usage.Peak,
+		ParameterChanges: []codersdk.WorkspaceRecommendationParameterChange{},
+	}, true
+}
+
+// SuggestParameterChange returns the change to a mutable numeric parameter
+// that sizes the recommended resource, or false if the parameter doesn't seem
+// to size it. Parameters are matched by name, and their values are scaled in
+// proportion to the suggested total so that their unit doesn't matter. If the
+// parameter has options, the closest option in the recommended direction is
+// suggested.
+func SuggestParameterChange(rec codersdk.WorkspaceRecommendation, param codersdk.TemplateVersionParameter, value string) (codersdk.WorkspaceRecommendationParameterChange, bool) {
+	pattern, ok := parameterPatterns[rec.Resource]
+	if !ok || !param.Mutable || param.Ephemeral {
+		return codersdk.WorkspaceRecommendationParameterChange{}, false
+	}
+	if !pattern.MatchString(param.Name) && !pattern.MatchString(param.DisplayName) {
+		return codersdk.WorkspaceRecommendationParameterChange{}, false
+	}
+	current, err := strconv.ParseFloat(value, 64)
+	if err != nil || current <= 0 || rec.CurrentTotal <= 0 {
+		return codersdk.WorkspaceRecommendationParameterChange{}, false
+	}
+	target := current * rec.SuggestedTotal / rec.CurrentTotal
+	increase := rec.Direction == codersdk.WorkspaceRecommendationDirectionIncrease
+
+	var suggested string
+	if len(param.Options) > 0 {
+		// Suggest the smallest option that fits the target. When increasing,
+		// fall back to the largest option if none fit.
+		var (
+			fitting, largest           string
+			fittingValue, largestValue float64
+		)
+		for _, option := range param.Options {
+			optionValue, err := strconv.ParseFloat(option.Value, 64)
+			if err != nil {
+				continue
+			}
+			if increase && optionValue <= current || !increase && optionValue >= current {
+				continue
+			}
+			if optionValue >= target && (fitting == "" || optionValue < fittingValue) {
+				fitting, fittingValue = option.Value, optionValue
+			}
+			if largest == "" || optionValue > largestValue {
+				largest, largestValue = option.Value, optionValue
+			}
+		}
+		switch {
+		case fitting != "":
+			suggested = fitting
+		case increase && largest != "":
+			suggested = largest
+		default:
+			return codersdk.WorkspaceRecommendationParameterChange{}, false
+		}
+	} else {
+		if param.Type != "number" {
+			return codersdk.WorkspaceRecommendationParameterChange{}, false
+		}
+		target = math.Ceil(target)
+		if param.ValidationMin != nil {
+			target = max(target, float64(*param.ValidationMin))
+		}
+		if param.ValidationMax != nil {
+			target = min(target, float64(*param.ValidationMax))
+		}
+		if increase && target <= current || !increase && target >= current {
+			return codersdk.WorkspaceRecommendationParameterChange{}, false
+		}
+		suggested = strconv.FormatFloat(target, 'f', -1, 64)
+	}
+
+	return codersdk.WorkspaceRecommendationParameterChange{
+		Name:           param.Name,
+		CurrentValue:   value,
+		SuggestedValue: suggested,
+	}, true
+}
+
+// percentile returns the nearest-rank percentile of the values.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
@@ -0,0 +1,251 @@
+package rightsizing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rightsizing"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var rows []database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow
+	for i := range 100 {
+		rows = append(rows, database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow{
+			AgentName:     "dev",
+			BucketStart:   start.Add(time.Duration(i) * 15 * time.Minute),
+			Samples:       15,
+			CPUUsedAvg:    1,
+			CPUUsedMax:    float64(i + 1),
+			CPUTotal:      float64(100 + i),
+			MemoryUsedAvg: 1 << 30,
+			MemoryUsedMax: 2 << 30,
+			MemoryTotal:   8 << 30,
+		})
+	}
+	rows = append(rows, database.GetWorkspaceAgentResourceUsageByWorkspaceIDRow{
+		AgentName:     "other",
+		BucketStart:   start,
+		Samples:       1,
+		CPUUsedAvg:    2,
+		CPUUsedMax:    2,
+		CPUTotal:      4,
+		MemoryUsedAvg: 3 << 30,
+		MemoryUsedMax: 3 << 30,
+		MemoryTotal:   4 << 30,
+	})
+
+	summaries := rightsizing.Summarize(rows)
+	require.Len(t, summaries, 2)
+	require.Equal(t, "dev", summaries[0].AgentName)
+	require.Equal(t, rightsizing.Usage{
+		// The latest total is used.
+		Total:   199,
+		Average: 1,
+		// The 95th percentile of the interval maxima.
+		Peak:    95,
+		Buckets: 100,
+	}, summaries[0].CPU)
+	require.Equal(t, rightsizing.Usage{
+		Total:   8 << 30,
+		Average: 1 << 30,
+		Peak:    2 << 30,
+		Buckets: 100,
+	}, summaries[0].Memory)
+	require.Equal(t, "other", summaries[1].AgentName)
+	require.Equal(t, 1, summaries[1].CPU.Buckets)
+	require.Equal(t, 2.0, summaries[1].CPU.Peak)
+}
+
+func TestRecommend(t *testing.T) {
+	t.Parallel()
+
+	const gib = 1 << 30
+
+	tests := []struct {
+		name      string
+		resource  codersdk.WorkspaceRecommendationResource
+		usage     rightsizing.Usage
+		direction codersdk.WorkspaceRecommendationDirection
+		suggested float64
+	}{
+		{
+			name:      "IncreaseCPU",
+			resource:  codersdk.WorkspaceRecommendationResourceCPU,
+			usage:     rightsizing.Usage{Total: 4, Average: 3, Peak: 3.9, Buckets: 96},
+			direction: codersdk.WorkspaceRecommendationDirectionIncrease,
+			suggested: 6,
+		},
+		{
+			name:      "DecreaseCPU",
+			resource:  codersdk.WorkspaceRecommendationResourceCPU,
+			usage:     rightsizing.Usage{Total: 16, Average: 1, Peak: 2, Buckets: 96},
+			direction: codersdk.WorkspaceRecommendationDirectionDecrease,
+			suggested: 3,
+		},
+		{
+			name:     "CPUSizedWell",
+			resource: codersdk.WorkspaceRecommendationResourceCPU,
+			usage:    rightsizing.Usage{Total: 4, Average: 2, Peak: 3, Buckets: 96},
+		},
+		{
+			name:      "IncreaseMemory",
+			resource:  codersdk.WorkspaceRecommendationResourceMemory,
+			usage:     rightsizing.Usage{Total: 8 * gib, Average: 7 * gib, Peak: 7.5 * gib, Buckets: 96},
+			direction: codersdk.WorkspaceRecommendationDirectionIncrease,
+			suggested: 11 * gib,
+		},
+		{
+			name:      "DecreaseMemory",
+			resource:  codersdk.WorkspaceRecommendationResourceMemory,
+			usage:     rightsizing.Usage{Total: 32 * gib, Average: 0.2 * gib, Peak: 0.5 * gib, Buckets: 96},
+			direction: codersdk.WorkspaceRecommendationDirectionDecrease,
+			suggested: 1 * gib,
+		},
+		{
+			name:     "NotEnoughUsage",
+			resource: codersdk.WorkspaceRecommendationResourceCPU,
+			usage:    rightsizing.Usage{Total: 16, Average: 1, Peak: 2, Buckets: 95},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ok := rightsizing.Recommend("dev", tt.resource, tt.usage)
+			if tt.direction == "" {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, "dev", rec.AgentName)
+			require.Equal(t, tt.resource, rec.Resource)
+			require.Equal(t, tt.direction, rec.Direction)
+			require.Equal(t, tt.usage.Total, rec.CurrentTotal)
+			require.Equal(t, tt.suggested, rec.SuggestedTotal)
+			require.Equal(t, tt.usage.Peak, rec.UsedPeak)
+		})
+	}
+}
+
+func TestSuggestParameterChange(t *testing.T) {
+	t.Parallel()
+
+	increaseCPU := codersdk.WorkspaceRecommendation{
+		Resource:       codersdk.WorkspaceRecommendationResourceCPU,
+		Direction:      codersdk.WorkspaceRecommendationDirectionIncrease,
+		CurrentTotal:   4,
+		SuggestedTotal: 6,
+	}
+	decreaseMemory := codersdk.WorkspaceRecommendation{
+		Resource:       codersdk.WorkspaceRecommendationResourceMemory,
+		Direction:      codersdk.WorkspaceRecommendationDirectionDecrease,
+		CurrentTotal:   16 << 30,
+		SuggestedTotal: 3 << 30,
+	}
+	options := func(values ...string) []codersdk.TemplateVersionParameterOption {
+		var opts []codersdk.TemplateVersionParameterOption
+		for _, v := range values {
+			opts = append(opts, codersdk.TemplateVersionParameterOption{Name: v, Value: v})
+		}
+		return opts
+	}
+
+	tests := []struct {
+		name      string
+		rec       codersdk.WorkspaceRecommendation
+		param     codersdk.TemplateVersionParameter
+		value     string
+		suggested string
+	}{
+		{
+			name:      "Number",
+			rec:       increaseCPU,
+			param:     codersdk.TemplateVersionParameter{Name: "cpu", Type: "number", Mutable: true},
+			value:     "4",
+			suggested: "6",
+		},
+		{
+			name:      "NumberMax",
+			rec:       increaseCPU,
+			param:     codersdk.TemplateVersionParameter{Name: "cores", Type: "number", Mutable: true, ValidationMax: ptr.Ref[int32](5)},
+			value:     "4",
+			suggested: "5",
+		},
+		{
+			name:  "NumberAtMax",
+			rec:   increaseCPU,
+			param: codersdk.TemplateVersionParameter{Name: "cores", Type: "number", Mutable: true, ValidationMax: ptr.Ref[int32](4)},
+			value: "4",
+		},
+		{
+			name: "OptionsInOtherUnit",
+			rec:  decreaseMemory,
+			param: codersdk.TemplateVersionParameter{
+				Name: "instance_memory", Type: "string", Mutable: true,
+				Options: options("1024", "2048", "4096", "8192", "16384"),
+			},
+			value:     "16384",
+			suggested: "4096",
+		},
+		{
+			name: "OptionsLargest",
+			rec:  increaseCPU,
+			param: codersdk.TemplateVersionParameter{
+				Name: "size", DisplayName: "CPU cores", Type: "number", Mutable: true,
+				Options: options("2", "4", "5"),
+			},
+			value:     "4",
+			suggested: "5",
+		},
+		{
+			name: "OptionsNoneSmaller",
+			rec:  decreaseMemory,
+			param: codersdk.TemplateVersionParameter{
+				Name: "memory", Type: "number", Mutable: true,
+				Options: options("1", "16"),
+			},
+			value: "16",
+		},
+		{
+			name:  "Immutable",
+			rec:   increaseCPU,
+			param: codersdk.TemplateVersionParameter{Name: "cpu", Type: "number"},
+			value: "4",
+		},
+		{
+			name:  "UnrelatedName",
+			rec:   increaseCPU,
+			param: codersdk.TemplateVersionParameter{Name: "disk_size", Type: "number", Mutable: true},
+			value: "4",
+		},
+		{
+			name:  "NotNumeric",
+			rec:   increaseCPU,
+			param: codersdk.TemplateVersionParameter{Name: "cpu", Type: "string", Mutable: true},
+			value: "large",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			change, ok := rightsizing.SuggestParameterChange(tt.rec, tt.param, tt.value)
+			if tt.suggested == "" {
+				require.False(t, ok, "unexpected change %+v", change)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tt.param.Name, change.Name)
+			require.Equal(t, tt.value, change.CurrentValue)
+			require.Equal(t, tt.suggested, change.SuggestedValue)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/tracing"
 )
 
@@ -247,6 +248,47 @@ func CalculateAutostop(ctx context.Context, params CalculateAutostopParams) (Aut
 	return autostop, nil
 }
 
+// NextAutostopRequirementWindows returns up to n upcoming quiet hours windows
+// after "at" that fall on a day (and week) of the template's autostop
+// requirement. These are the windows in which workspaces are forced to stop.
+// No windows are returned if there is no quiet hours schedule or the template
+// has no autostop requirement.
+func NextAutostopRequirementWindows(at time.Time, quietHours *cron.Schedule, requirement TemplateAutostopRequirement, n int) ([]time.Time, error) {
+	if quietHours == nil || requirement.DaysOfWeek == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	weeks := requirement.Weeks
+	if weeks < 1 {
+		weeks = 1
+	}
+	requirementDays := requirement.DaysMap()
+
+	// Quiet hours schedules are daily, so consecutive windows are at most
+	// one requirement period apart.
+	maxChecks := (n*int(weeks) + 1) * len(DaysOfWeek)
+	windows := make([]time.Time, 0, n)
+	next := at.In(quietHours.Location())
+	for i := 0; i < maxChecks && len(windows) < n; i++ {
+		next = quietHours.Next(next)
+		if next.IsZero() {
+			break
+		}
+		if !requirementDays[next.Weekday()] {
+			continue
+		}
+		week, err := WeeksSinceEpoch(next)
+		if err != nil {
+			return nil, xerrors.Errorf("get week of window: %w", err)
+		}
+		if week%weeks != 0 {
+			continue
+		}
+		windows = append(windows, next)
+	}
+	return windows, nil
+}
+
 // truncateMidnight truncates a time to midnight in the time object's timezone.
 // t.Truncate(24 * time.Hour) truncates based on the internal time and doesn't
 // factor daylight savings properly.
@@ -682,3 +682,74 @@ func TestFindWeek(t *testing.T) {
 		})
 	}
 }
+
+func TestNextAutostopRequirementWindows(t *testing.T) {
+	t.Parallel()
+
+	quietHours, err := cron.Daily("CRON_TZ=UTC 0 0 * * *")
+	require.NoError(t, err)
+	// Wednesday of week 52 since the epoch.
+	at := time.Date(2024, time.January, 3, 12, 0, 0, 0, time.UTC)
+	saturday := uint8(0b00100000)
+
+	date := func(day int) time.Time {
+		return time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name        string
+		quietHours  *cron.Schedule
+		requirement schedule.TemplateAutostopRequirement
+		n           int
+		expected    []time.Time
+	}{
+		{
+			name:        "NoQuietHours",
+			quietHours:  nil,
+			requirement: schedule.TemplateAutostopRequirement{DaysOfWeek: saturday, Weeks: 1},
+			n:           3,
+			expected:    nil,
+		},
+		{
+			name:        "NoRequirement",
+			quietHours:  quietHours,
+			requirement: schedule.TemplateAutostopRequirement{DaysOfWeek: 0, Weeks: 1},
+			n:           3,
+			expected:    nil,
+		},
+		{
+			name:        "Weekly",
+			quietHours:  quietHours,
+			requirement: schedule.TemplateAutostopRequirement{DaysOfWeek: saturday, Weeks: 1},
+			n:           3,
+			expected:    []time.Time{date(6), date(13), date(20)},
+		},
+		{
+			name:        "EveryOtherWeek",
+			quietHours:  quietHours,
+			requirement: schedule.TemplateAutostopRequirement{DaysOfWeek: saturday, Weeks: 2},
+			n:           2,
+			expected:    []time.Time{date(6), date(20)},
+		},
+		{
+			name:        "Weekdays",
+			quietHours:  quietHours,
+			requirement: schedule.TemplateAutostopRequirement{DaysOfWeek: 0b00011111, Weeks: 1},
+			n:           3,
+			expected:    []time.Time{date(4), date(5), date(8)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			windows, err := schedule.NextAutostopRequirementWindows(at, c.quietHours, c.requirement, c.n)
+			require.NoError(t, err)
+			require.Len(t, windows, len(c.expected))
+			for i, window := range windows {
+				require.True(t, c.expected[i].Equal(window), "window %d: expected %s, got %s", i, c.expected[i], window)
+			}
+		})
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -33,6 +35,7 @@ import (
 //   - resource_type: string (enum)
 //   - action: string (enum)
 //   - build_reason: string (enum)
+//   - client_type: string (enum)
 func AuditLogs(ctx context.Context, db database.Store, query string) (database.GetAuditLogsOffsetParams, []codersdk.ValidationError) {
 	// Always lowercase for all searches.
 	query = strings.ToLower(query)
@@ -58,6 +61,7 @@ func AuditLogs(ctx context.Context, db database.Store, query string) (database.G
 		ResourceType:   string(httpapi.ParseCustom(parser, values, "", "resource_type", httpapi.ParseEnum[database.ResourceType])),
 		Action:         string(httpapi.ParseCustom(parser, values, "", "action", httpapi.ParseEnum[database.AuditAction])),
 		BuildReason:    string(httpapi.ParseCustom(parser, values, "", "build_reason", httpapi.ParseEnum[database.BuildReason])),
+		ClientType:     string(httpapi.ParseCustom(parser, values, "", "client_type", httpapi.ParseEnum[codersdk.AuditClientType])),
 	}
 	if !filter.DateTo.IsZero() {
 		filter.DateTo = filter.DateTo.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
@@ -101,7 +105,8 @@ func Workspaces(ctx context.Context, db database.Store, query string, page coder
 		// #nosec G115 - Safe conversion for pagination offset which is expected to be within int32 range
 		Offset: int32(page.Offset),
 		// #nosec G115 - Safe conversion for pagination limit which is expected to be within int32 range
-		Limit: int32(page.Limit),
+		Limit:   int32(page.Limit),
+		AfterID: page.AfterID,
 	}
 
 	if query == "" {
@@ -136,8 +141,9 @@ func Workspaces(ctx context.Context, db database.Store, query string, page coder
 	filter.Status = string(httpapi.ParseCustom(parser, values, "", "status", httpapi.ParseEnum[database.WorkspaceStatus]))
 	filter.HasAgent = parser.String(values, "", "has-agent")
 	filter.Dormant = parser.Boolean(values, false, "dormant")
-	filter.LastUsedAfter = parser.Time3339Nano(values, time.Time{}, "last_used_after")
-	filter.LastUsedBefore = parser.Time3339Nano(values, time.Time{}, "last_used_before")
+	now := dbtime.Now()
+	filter.LastUsedAfter = parser.Time3339NanoOrAgo(values, time.Time{}, "last_used_after", now)
+	filter.LastUsedBefore = parser.Time3339NanoOrAgo(values, time.Time{}, "last_used_before", now)
 	filter.UsingActive = sql.NullBool{
 		// Invert the value of the query parameter to get the correct value.
 		// UsingActive returns if the workspace is on the latest template active version.
@@ -183,6 +189,28 @@ func Workspaces(ctx context.Context, db database.Store, query string, page coder
 		filter.ParamValues = append(filter.ParamValues, *p.value)
 	}
 
+	// label matching takes the same form as parameter matching:
+	//	`label:<key>[=<value>]`
+	// If the value is omitted, then we match on the presence of the label.
+	labels := httpapi.ParseCustomList(parser, values, []paramMatch{}, "label", func(v string) (paramMatch, error) {
+		v = strings.TrimSpace(v)
+		parts := strings.Split(v, "=")
+		switch {
+		case len(parts) == 1:
+			return paramMatch{name: parts[0]}, nil
+		case len(parts) == 2 && parts[1] == "":
+			return paramMatch{}, xerrors.Errorf("query element %q has an empty value. omit the '=' to match just on the label key", v)
+		case len(parts) == 2:
+			return paramMatch{name: parts[0], value: &parts[1]}, nil
+		}
+		return paramMatch{}, xerrors.Errorf("query element %q can only contain 1 '='", v)
+	})
+	for _, l := range labels {
+		filter.LabelKeys = append(filter.LabelKeys, l.name)
+		// An empty value matches any value of the label.
+		filter.LabelValues = append(filter.LabelValues, ptr.NilToEmpty(l.value))
+	}
+
 	parser.ErrorExcessParams(values)
 	return filter, parser.Errors
 }
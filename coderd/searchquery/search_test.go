@@ -197,6 +197,14 @@ func TestSearchWorkspace(t *testing.T) {
 				ParamValues: []string{"bar"},
 			},
 		},
+		{
+			Name:  "Labels",
+			Query: "label:team=payments label:oncall",
+			Expected: database.GetWorkspacesParams{
+				LabelKeys:   []string{"team", "oncall"},
+				LabelValues: []string{"payments", ""},
+			},
+		},
 		{
 			Name:  "Organization",
 			Query: `organization:4fe722f0-49bc-4a90-a3eb-4ac439bfce20`,
@@ -264,6 +272,11 @@ func TestSearchWorkspace(t *testing.T) {
 			Query:                 "param:foo=",
 			ExpectedErrorContains: "omit the '=' to match",
 		},
+		{
+			Name:                  "LabelNoValue",
+			Query:                 "label:team=",
+			ExpectedErrorContains: "omit the '=' to match",
+		},
 		{
 			Name:                  "NoPrefix",
 			Query:                 `:foo`,
@@ -326,6 +339,23 @@ func TestSearchWorkspace(t *testing.T) {
 			}
 		})
 	}
+	t.Run("LastUsedRelative", func(t *testing.T) {
+		t.Parallel()
+
+		before := time.Now()
+		values, errs := searchquery.Workspaces(context.Background(), dbmem.New(), "last_used_before:30d last_used_after:12h", codersdk.Pagination{}, 0)
+		after := time.Now()
+		require.Empty(t, errs)
+		require.WithinRange(t, values.LastUsedBefore, before.Add(-30*24*time.Hour).Add(-time.Second), after.Add(-30*24*time.Hour))
+		require.WithinRange(t, values.LastUsedAfter, before.Add(-12*time.Hour).Add(-time.Second), after.Add(-12*time.Hour))
+	})
+	t.Run("LastUsedInvalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, errs := searchquery.Workspaces(context.Background(), dbmem.New(), "last_used_before:0d", codersdk.Pagination{}, 0)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0].Detail, "or a duration")
+	})
 	t.Run("AgentInactiveDisconnectTimeout", func(t *testing.T) {
 		t.Parallel()
 
@@ -378,6 +408,19 @@ func TestSearchAudit(t *testing.T) {
 			Query:                 "request_id:foo",
 			ExpectedErrorContains: "valid uuid",
 		},
+		{
+			Name:  "ClientType",
+			Query: "action:connect client_type:vscode",
+			Expected: database.GetAuditLogsOffsetParams{
+				Action:     string(database.AuditActionConnect),
+				ClientType: string(codersdk.AuditClientTypeVSCode),
+			},
+		},
+		{
+			Name:                  "InvalidClientType",
+			Query:                 "client_type:emacs",
+			ExpectedErrorContains: `"emacs" is not a valid value`,
+		},
 	}
 
 	for _, c := range testCases {
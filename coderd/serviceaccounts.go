@@ -0,0 +1,640 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// serviceAccountEmailDomain is used to build the email address of the user
+// behind a service account. Users must have a unique email, but service
+// accounts never receive mail.
+const serviceAccountEmailDomain = "service-account"
+
+// @Summary Get service accounts
+// @ID get-service-accounts
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.ServiceAccount
+// @Router /organizations/{organization}/service-accounts [get]
+func (api *API) serviceAccounts(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	serviceAccounts, err := api.Database.GetServiceAccountsByOrganizationID(ctx, organization.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching service accounts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp, err := convertServiceAccounts(ctx, api.Database, organization.ID, serviceAccounts)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Create service account
+// @ID create-service-account
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateServiceAccountRequest true "Create service account request"
+// @Success 201 {object} codersdk.ServiceAccount
+// @Router /organizations/{organization}/service-accounts [post]
+func (api *API) postServiceAccount(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		organization      = httpmw.OrganizationParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			OrganizationID: organization.ID,
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+		})
+	)
+	aReq.Old = database.User{}
+	defer commitAudit()
+
+	var req codersdk.CreateServiceAccountRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionCreate, rbac.ResourceServiceAccount.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	email := fmt.Sprintf("%s@%s", req.Username, serviceAccountEmailDomain)
+	// nolint:gocritic // The caller might not be able to read users, but
+	// usernames must be unique across the deployment.
+	_, err := api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
+		Username: req.Username,
+		Email:    email,
+	})
+	if err == nil {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "User already exists.",
+		})
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var (
+		user           database.User
+		serviceAccount database.ServiceAccount
+		// rolesErr is set when the roles could not be assigned, which is the
+		// fault of the request rather than the server.
+		rolesErr error
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		status := codersdk.UserStatusActive
+		// nolint:gocritic // Creating the user requires permissions the
+		// caller might not have. Creating the service account below is
+		// authorized against the caller, which rolls this back on failure.
+		user, err = api.CreateUser(dbauthz.AsSystemRestricted(ctx), tx, CreateUserRequest{
+			CreateUserRequestWithOrgs: codersdk.CreateUserRequestWithOrgs{
+				Email:           email,
+				Username:        req.Username,
+				Name:            req.Name,
+				UserLoginType:   codersdk.LoginTypeNone,
+				UserStatus:      &status,
+				OrganizationIDs: []uuid.UUID{organization.ID},
+			},
+			LoginType:         database.LoginTypeNone,
+			SkipNotifications: true,
+		})
+		if err != nil {
+			return xerrors.Errorf("create user: %w", err)
+		}
+
+		serviceAccount, err = tx.InsertServiceAccount(ctx, database.InsertServiceAccountParams{
+			UserID:         user.ID,
+			OrganizationID: organization.ID,
+			Description:    req.Description,
+			CreatedBy:      uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+			CreatedAt:      dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("insert service account: %w", err)
+		}
+
+		if len(req.Roles) > 0 {
+			// Role assignment is authorized against the caller, so a service
+			// account can't be given more permissions than the caller has.
+			_, rolesErr = tx.UpdateMemberRoles(ctx, database.UpdateMemberRolesParams{
+				GrantedRoles: req.Roles,
+				UserID:       user.ID,
+				OrgID:        organization.ID,
+			})
+			if rolesErr != nil {
+				return xerrors.Errorf("update member roles: %w", rolesErr)
+			}
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) || httpapi.Is404Error(rolesErr) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if rolesErr != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to assign roles to the service account.",
+			Detail:  rolesErr.Error(),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating service account.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = user
+
+	resp, err := convertServiceAccounts(ctx, api.Database, organization.ID, []database.ServiceAccount{serviceAccount})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if len(resp) != 1 {
+		httpapi.InternalServerError(rw, xerrors.Errorf("failed to serialize service account to response, creation still succeeded"))
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, resp[0])
+}
+
+// @Summary Delete service account
+// @ID delete-service-account
+// @Security CoderSessionToken
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "User ID or name"
+// @Success 204
+// @Router /organizations/{organization}/service-accounts/{user} [delete]
+func (api *API) deleteServiceAccount(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		serviceAccount    = httpmw.ServiceAccountParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			OrganizationID: organization.ID,
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+		})
+	)
+	aReq.Old = serviceAccount.User
+	defer commitAudit()
+
+	if !api.Authorize(r, policy.ActionDelete, serviceAccount.ServiceAccount) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// nolint:gocritic // The caller is allowed to delete the service account,
+	// which covers the user behind it.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	workspaces, err := api.Database.GetWorkspaces(sysCtx, database.GetWorkspacesParams{
+		OwnerID: serviceAccount.UserID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(workspaces) > 0 {
+		httpapi.Write(ctx, rw, http.StatusExpectationFailed, codersdk.Response{
+			Message: "You cannot delete a service account that has workspaces. Delete its workspaces and try again!",
+		})
+		return
+	}
+
+	err = api.Database.UpdateUserDeletedByID(sysCtx, serviceAccount.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting service account.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	user := serviceAccount.User
+	user.Deleted = true
+	aReq.New = user
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get service account tokens
+// @ID get-service-account-tokens
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "User ID or name"
+// @Success 200 {array} codersdk.APIKey
+// @Router /organizations/{organization}/service-accounts/{user}/tokens [get]
+func (api *API) serviceAccountTokens(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		serviceAccount = httpmw.ServiceAccountParam(r)
+	)
+
+	// nolint:gocritic // The middleware checked the caller can read the
+	// service account, which covers its tokens.
+	keys, err := api.Database.GetAPIKeysByUserID(dbauthz.AsSystemRestricted(ctx), database.GetAPIKeysByUserIDParams{
+		LoginType: database.LoginTypeToken,
+		UserID:    serviceAccount.UserID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching API keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiKeys := make([]codersdk.APIKey, 0, len(keys))
+	for _, key := range keys {
+		apiKeys = append(apiKeys, convertAPIKey(key))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiKeys)
+}
+
+// @Summary Create service account token
+// @ID create-service-account-token
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "User ID or name"
+// @Param request body codersdk.CreateTokenRequest true "Create token request"
+// @Success 201 {object} codersdk.GenerateAPIKeyResponse
+// @Router /organizations/{organization}/service-accounts/{user}/tokens [post]
+func (api *API) postServiceAccountToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		serviceAccount    = httpmw.ServiceAccountParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			OrganizationID: organization.ID,
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	var createToken codersdk.CreateTokenRequest
+	if !httpapi.Read(ctx, rw, r, &createToken) {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionUpdate, serviceAccount.ServiceAccount) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if !api.authorizeServiceAccountRoles(rw, r, organization.ID, serviceAccount) {
+		return
+	}
+
+	// nolint:gocritic // The caller is allowed to manage the tokens of the
+	// service account.
+	token, key, ok := api.createToken(dbauthz.AsSystemRestricted(ctx), rw, serviceAccount.UserID, createToken, "")
+	if !ok {
+		return
+	}
+	aReq.New = key
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: token})
+}
+
+// @Summary Rotate service account token
+// @ID rotate-service-account-token
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "User ID or name"
+// @Param keyname path string true "Key name"
+// @Success 200 {object} codersdk.GenerateAPIKeyResponse
+// @Router /organizations/{organization}/service-accounts/{user}/tokens/{keyname}/rotate [post]
+func (api *API) rotateServiceAccountToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		serviceAccount    = httpmw.ServiceAccountParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			OrganizationID: organization.ID,
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	if !api.Authorize(r, policy.ActionUpdate, serviceAccount.ServiceAccount) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if !api.authorizeServiceAccountRoles(rw, r, organization.ID, serviceAccount) {
+		return
+	}
+
+	// nolint:gocritic // The caller is allowed to manage the tokens of the
+	// service account.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	oldKey, ok := api.serviceAccountToken(sysCtx, rw, r, serviceAccount)
+	if !ok {
+		return
+	}
+	aReq.Old = oldKey
+
+	params, token, err := apikey.Generate(apikey.CreateParams{
+		UserID:          serviceAccount.UserID,
+		LoginType:       database.LoginTypeToken,
+		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
+		LifetimeSeconds: oldKey.LifetimeSeconds,
+		Scope:           oldKey.Scope,
+		TokenName:       oldKey.TokenName,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to generate API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var newKey database.APIKey
+	err = api.Database.InTx(func(tx database.Store) error {
+		// The old key is deleted first, since token names are unique per
+		// user.
+		err := tx.DeleteAPIKeyByID(sysCtx, oldKey.ID)
+		if err != nil {
+			return xerrors.Errorf("delete old API key: %w", err)
+		}
+		newKey, err = tx.InsertAPIKey(sysCtx, params)
+		if err != nil {
+			return xerrors.Errorf("insert API key: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error rotating API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = newKey
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.GenerateAPIKeyResponse{Key: token})
+}
+
+// @Summary Delete service account token
+// @ID delete-service-account-token
+// @Security CoderSessionToken
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "User ID or name"
+// @Param keyname path string true "Key name"
+// @Success 204
+// @Router /organizations/{organization}/service-accounts/{user}/tokens/{keyname} [delete]
+func (api *API) deleteServiceAccountToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		serviceAccount    = httpmw.ServiceAccountParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			OrganizationID: organization.ID,
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	if !api.Authorize(r, policy.ActionUpdate, serviceAccount.ServiceAccount) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// nolint:gocritic // The caller is allowed to manage the tokens of the
+	// service account.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	key, ok := api.serviceAccountToken(sysCtx, rw, r, serviceAccount)
+	if !ok {
+		return
+	}
+	aReq.Old = key
+
+	err := api.Database.DeleteAPIKeyByID(sysCtx, key.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// serviceAccountToken fetches the token named by the "keyname" URL parameter.
+// If ok is false, an error response has already been written.
+func (api *API) serviceAccountToken(ctx context.Context, rw http.ResponseWriter, r *http.Request, serviceAccount httpmw.ServiceAccount) (database.APIKey, bool) {
+	key, err := api.Database.GetAPIKeyByName(ctx, database.GetAPIKeyByNameParams{
+		UserID:    serviceAccount.UserID,
+		TokenName: chi.URLParam(r, "keyname"),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.APIKey{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching API key.",
+			Detail:  err.Error(),
+		})
+		return database.APIKey{}, false
+	}
+	return key, true
+}
+
+// convertServiceAccounts looks up the users and organization roles behind the
+// service accounts of an organization.
+func convertServiceAccounts(ctx context.Context, db database.Store, organizationID uuid.UUID, serviceAccounts []database.ServiceAccount) ([]codersdk.ServiceAccount, error) {
+	converted := make([]codersdk.ServiceAccount, 0, len(serviceAccounts))
+	if len(serviceAccounts) == 0 {
+		return converted, nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(serviceAccounts))
+	for _, serviceAccount := range serviceAccounts {
+		userIDs = append(userIDs, serviceAccount.UserID)
+	}
+	// Fetch memberships of a single user directly to avoid listing every
+	// member of the organization.
+	memberUserID := uuid.Nil
+	if len(userIDs) == 1 {
+		memberUserID = userIDs[0]
+	}
+
+	// nolint:gocritic // The caller can read the service accounts, which
+	// covers the users behind them.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	users, err := db.GetUsersByIDs(sysCtx, userIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get users: %w", err)
+	}
+	usersByID := make(map[uuid.UUID]database.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	rows, err := db.OrganizationMembers(sysCtx, database.OrganizationMembersParams{
+		OrganizationID: organizationID,
+		UserID:         memberUserID,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get organization members: %w", err)
+	}
+	members := make([]database.OrganizationMember, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, row.OrganizationMember)
+	}
+	convertedMembers, err := convertOrganizationMembers(ctx, db, members)
+	if err != nil {
+		return nil, err
+	}
+	rolesByUserID := make(map[uuid.UUID][]codersdk.SlimRole, len(convertedMembers))
+	for _, member := range convertedMembers {
+		rolesByUserID[member.UserID] = member.Roles
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		user := usersByID[serviceAccount.UserID]
+		roles := rolesByUserID[serviceAccount.UserID]
+		if roles == nil {
+			roles = []codersdk.SlimRole{}
+		}
+		var createdBy *uuid.UUID
+		if serviceAccount.CreatedBy.Valid {
+			createdBy = &serviceAccount.CreatedBy.UUID
+		}
+		converted = append(converted, codersdk.ServiceAccount{
+			ID:             serviceAccount.UserID,
+			OrganizationID: serviceAccount.OrganizationID,
+			Username:       user.Username,
+			Name:           user.Name,
+			Description:    serviceAccount.Description,
+			Status:         codersdk.UserStatus(user.Status),
+			Roles:          roles,
+			CreatedBy:      createdBy,
+			CreatedAt:      serviceAccount.CreatedAt,
+		})
+	}
+	return converted, nil
+}
+
+// authorizeServiceAccountRoles writes a response and returns false unless the
+// caller could assign every role the service account holds. A token acts with
+// all of them, so minting one must not let the caller act with roles it can't
+// grant. The checks mirror the ones dbauthz makes in UpdateMemberRoles.
+func (api *API) authorizeServiceAccountRoles(rw http.ResponseWriter, r *http.Request, organizationID uuid.UUID, serviceAccount httpmw.ServiceAccount) bool {
+	ctx := r.Context()
+	// nolint:gocritic // The caller might not be allowed to read the member,
+	// in which case it can't assign its roles either.
+	member, err := database.ExpectOne(api.Database.OrganizationMembers(dbauthz.AsSystemRestricted(ctx), database.OrganizationMembersParams{
+		OrganizationID: organizationID,
+		UserID:         serviceAccount.UserID,
+	}))
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("get service account roles: %w", err))
+		return false
+	}
+
+	roles := make([]rbac.RoleIdentifier, 0, len(member.OrganizationMember.Roles)+len(serviceAccount.User.RBACRoles))
+	for _, name := range member.OrganizationMember.Roles {
+		roles = append(roles, rbac.RoleIdentifier{Name: name, OrganizationID: organizationID})
+	}
+	for _, name := range serviceAccount.User.RBACRoles {
+		roles = append(roles, rbac.RoleIdentifier{Name: name})
+	}
+
+	actor := httpmw.UserAuthorization(ctx)
+	for _, role := range roles {
+		assignRole := rbac.ResourceAssignRole
+		if role.IsOrgRole() {
+			assignRole = rbac.ResourceAssignOrgRole.InOrg(role.OrganizationID)
+		}
+		if !api.Authorize(r, policy.ActionAssign, assignRole) {
+			httpapi.Forbidden(rw)
+			return false
+		}
+
+		// Custom roles are assigned with a static role, see dbauthz.
+		if _, err := rbac.RoleByName(role); err != nil {
+			if role.IsOrgRole() {
+				role = rbac.CustomOrganizationRole(role.OrganizationID)
+			} else {
+				role = rbac.CustomSiteRole()
+			}
+		}
+		if !rbac.CanAssignRole(actor.Roles, role) {
+			httpapi.Forbidden(rw)
+			return false
+		}
+	}
+	return true
+}
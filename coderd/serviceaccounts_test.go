@@ -0,0 +1,239 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestServiceAccounts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		owner := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, admin := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID, rbac.ScopedRoleOrgAdmin(first.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		serviceAccount, err := client.CreateServiceAccount(ctx, first.OrganizationID, codersdk.CreateServiceAccountRequest{
+			Username:    "ci-bot",
+			Name:        "CI Bot",
+			Description: "Runs the nightly builds.",
+			Roles:       []string{rbac.RoleOrgAuditor()},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "ci-bot", serviceAccount.Username)
+		require.Equal(t, "Runs the nightly builds.", serviceAccount.Description)
+		require.Equal(t, codersdk.UserStatusActive, serviceAccount.Status)
+		require.NotNil(t, serviceAccount.CreatedBy)
+		require.Equal(t, admin.ID, *serviceAccount.CreatedBy)
+		require.Len(t, serviceAccount.Roles, 1)
+		require.Equal(t, rbac.RoleOrgAuditor(), serviceAccount.Roles[0].Name)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeUser,
+			ResourceID:   serviceAccount.ID,
+		}))
+
+		serviceAccounts, err := client.ServiceAccounts(ctx, first.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, serviceAccounts, 1)
+		require.Equal(t, serviceAccount.ID, serviceAccounts[0].ID)
+
+		// The token authenticates as the service account.
+		token, err := client.CreateServiceAccountToken(ctx, first.OrganizationID, serviceAccount.Username, codersdk.CreateTokenRequest{
+			TokenName: "nightly",
+		})
+		require.NoError(t, err)
+		saClient := codersdk.New(owner.URL)
+		saClient.SetSessionToken(token.Key)
+		me, err := saClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, serviceAccount.ID, me.ID)
+		require.Equal(t, codersdk.LoginTypeNone, me.LoginType)
+
+		tokens, err := client.ServiceAccountTokens(ctx, first.OrganizationID, serviceAccount.Username)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.Equal(t, "nightly", tokens[0].TokenName)
+
+		// Rotating replaces the token with a new one of the same name.
+		rotated, err := client.RotateServiceAccountToken(ctx, first.OrganizationID, serviceAccount.Username, "nightly")
+		require.NoError(t, err)
+		require.NotEqual(t, token.Key, rotated.Key)
+		_, err = saClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		saClient.SetSessionToken(rotated.Key)
+		_, err = saClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		tokens, err = client.ServiceAccountTokens(ctx, first.OrganizationID, serviceAccount.Username)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.Equal(t, "nightly", tokens[0].TokenName)
+
+		err = client.DeleteServiceAccountToken(ctx, first.OrganizationID, serviceAccount.Username, "nightly")
+		require.NoError(t, err)
+		_, err = saClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		tokens, err = client.ServiceAccountTokens(ctx, first.OrganizationID, serviceAccount.Username)
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+
+		err = client.DeleteServiceAccount(ctx, first.OrganizationID, serviceAccount.Username)
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionDelete,
+			ResourceType: database.ResourceTypeUser,
+			ResourceID:   serviceAccount.ID,
+		}))
+		serviceAccounts, err = client.ServiceAccounts(ctx, first.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, serviceAccounts)
+	})
+
+	t.Run("NotServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		owner := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+		_, user := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := owner.CreateServiceAccountToken(ctx, first.OrganizationID, user.Username, codersdk.CreateTokenRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		owner := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+		member, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := member.CreateServiceAccount(ctx, first.OrganizationID, codersdk.CreateServiceAccountRequest{
+			Username: "ci-bot",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		//nolint:gocritic // Only owners and user admins create service accounts.
+		_, err = owner.CreateServiceAccount(ctx, first.OrganizationID, codersdk.CreateServiceAccountRequest{
+			Username: "ci-bot",
+		})
+		require.NoError(t, err)
+		serviceAccounts, err := member.ServiceAccounts(ctx, first.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, serviceAccounts)
+		_, err = member.CreateServiceAccountToken(ctx, first.OrganizationID, "ci-bot", codersdk.CreateTokenRequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("CannotEscalateRoles", func(t *testing.T) {
+		t.Parallel()
+		owner := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID, rbac.ScopedRoleOrgUserAdmin(first.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := client.CreateServiceAccount(ctx, first.OrganizationID, codersdk.CreateServiceAccountRequest{
+			Username: "ci-bot",
+			Roles:    []string{rbac.RoleOrgAdmin()},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// The user behind the service account is rolled back. The in-memory
+		// database doesn't support rolling back transactions.
+		if dbtestutil.WillUsePostgres() {
+			//nolint:gocritic // Only owners can read the user.
+			_, err = owner.User(ctx, "ci-bot")
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("CannotMintTokensWithEscalatedRoles", func(t *testing.T) {
+		t.Parallel()
+		owner := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID, rbac.ScopedRoleOrgUserAdmin(first.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		//nolint:gocritic // Only owners and org admins can assign the role.
+		_, err := owner.CreateServiceAccount(ctx, first.OrganizationID, codersdk.CreateServiceAccountRequest{
+			Username: "admin-bot",
+			Roles:    []string{rbac.RoleOrgAdmin()},
+		})
+		require.NoError(t, err)
+		//nolint:gocritic // Only owners and org admins can assign the role.
+		_, err = owner.CreateServiceAccountToken(ctx, first.OrganizationID, "admin-bot", codersdk.CreateTokenRequest{
+			TokenName: "nightly",
+		})
+		require.NoError(t, err)
+
+		// The user admin can manage the service account, but can't act as an
+		// organization admin through its tokens.
+		_, err = client.CreateServiceAccountToken(ctx, first.OrganizationID, "admin-bot", codersdk.CreateTokenRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = client.RotateServiceAccountToken(ctx, first.OrganizationID, "admin-bot", "nightly")
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Tokens of service accounts with roles the user admin can assign
+		// are fine.
+		_, err = client.CreateServiceAccount(ctx, first.OrganizationID, codersdk.CreateServiceAccountRequest{
+			Username: "ci-bot",
+		})
+		require.NoError(t, err)
+		_, err = client.CreateServiceAccountToken(ctx, first.OrganizationID, "ci-bot", codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+	})
+
+	t.Run("CannotLogin", func(t *testing.T) {
+		t.Parallel()
+		owner := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		//nolint:gocritic // Only owners and user admins create service accounts.
+		serviceAccount, err := owner.CreateServiceAccount(ctx, first.OrganizationID, codersdk.CreateServiceAccountRequest{
+			Username: "ci-bot",
+		})
+		require.NoError(t, err)
+
+		//nolint:gocritic // Only owners can read the user.
+		user, err := owner.User(ctx, serviceAccount.ID.String())
+		require.NoError(t, err)
+		_, err = codersdk.New(owner.URL).LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.Error(t, err)
+
+		// Usernames are shared with regular users.
+		//nolint:gocritic // Only owners and user admins create service accounts.
+		_, err = owner.CreateServiceAccount(ctx, first.OrganizationID, codersdk.CreateServiceAccountRequest{
+			Username: "ci-bot",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+}
@@ -649,6 +649,17 @@ func (r *remoteReporter) createSnapshot() (*Snapshot, error) {
 		}
 		return nil
 	})
+	eg.Go(func() error {
+		connectionTypes, err := r.options.Database.GetWorkspaceAgentConnectionTypeCounts(ctx, createdAfter)
+		if err != nil {
+			return xerrors.Errorf("get workspace agent connection types: %w", err)
+		}
+		snapshot.WorkspaceAgentConnectionTypes = make([]WorkspaceAgentConnectionType, 0, len(connectionTypes))
+		for _, connectionType := range connectionTypes {
+			snapshot.WorkspaceAgentConnectionTypes = append(snapshot.WorkspaceAgentConnectionTypes, ConvertWorkspaceAgentConnectionType(connectionType))
+		}
+		return nil
+	})
 	eg.Go(func() error {
 		proxies, err := r.options.Database.GetWorkspaceProxies(ctx)
 		if err != nil {
@@ -853,6 +864,14 @@ func ConvertWorkspaceAgentVolumeResourceMonitor(monitor database.WorkspaceAgentV
 	}
 }
 
+func ConvertWorkspaceAgentConnectionType(row database.GetWorkspaceAgentConnectionTypeCountsRow) WorkspaceAgentConnectionType {
+	return WorkspaceAgentConnectionType{
+		Direct:         row.P2P,
+		DERPRegionCode: row.DerpRegionCode,
+		Count:          row.Count,
+	}
+}
+
 // ConvertWorkspaceAgentStat anonymizes a workspace agent stat.
 func ConvertWorkspaceAgentStat(stat database.GetWorkspaceAgentStatsRow) WorkspaceAgentStat {
 	return WorkspaceAgentStat{
@@ -1192,6 +1211,7 @@ type Snapshot struct {
 	WorkspaceResources                   []WorkspaceResource                   `json:"workspace_resources"`
 	WorkspaceAgentMemoryResourceMonitors []WorkspaceAgentMemoryResourceMonitor `json:"workspace_agent_memory_resource_monitors"`
 	WorkspaceAgentVolumeResourceMonitors []WorkspaceAgentVolumeResourceMonitor `json:"workspace_agent_volume_resource_monitors"`
+	WorkspaceAgentConnectionTypes        []WorkspaceAgentConnectionType        `json:"workspace_agent_connection_types"`
 	WorkspaceModules                     []WorkspaceModule                     `json:"workspace_modules"`
 	Workspaces                           []Workspace                           `json:"workspaces"`
 	NetworkEvents                        []NetworkEvent                        `json:"network_events"`
@@ -1342,6 +1362,14 @@ type WorkspaceAgentVolumeResourceMonitor struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// WorkspaceAgentConnectionType is the number of connections to workspace
+// agents that are direct, or relayed through the same DERP region.
+type WorkspaceAgentConnectionType struct {
+	Direct         bool   `json:"direct"`
+	DERPRegionCode string `json:"derp_region_code"`
+	Count          int64  `json:"count"`
+}
+
 type WorkspaceApp struct {
 	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
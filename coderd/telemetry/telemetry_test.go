@@ -146,6 +146,20 @@ func TestTelemetry(t *testing.T) {
 		_ = dbgen.WorkspaceAgentVolumeResourceMonitor(t, db, database.WorkspaceAgentVolumeResourceMonitor{
 			AgentID: wsagent.ID,
 		})
+		for ip, derpRegionCode := range map[string]string{
+			"fd7a:115c:a1e0::1": "",
+			"fd7a:115c:a1e0::2": "nyc",
+			"fd7a:115c:a1e0::3": "nyc",
+		} {
+			err = db.UpsertWorkspaceAgentConnectionQuality(ctx, database.UpsertWorkspaceAgentConnectionQualityParams{
+				AgentID:        wsagent.ID,
+				Ip:             ip,
+				P2P:            derpRegionCode == "",
+				DerpRegionCode: derpRegionCode,
+				UpdatedAt:      dbtime.Now(),
+			})
+			require.NoError(t, err)
+		}
 
 		_, snapshot := collectSnapshot(ctx, t, db, nil)
 		require.Len(t, snapshot.ProvisionerJobs, 1)
@@ -169,6 +183,10 @@ func TestTelemetry(t *testing.T) {
 		require.Len(t, snapshot.TelemetryItems, 2)
 		require.Len(t, snapshot.WorkspaceAgentMemoryResourceMonitors, 1)
 		require.Len(t, snapshot.WorkspaceAgentVolumeResourceMonitors, 1)
+		require.Equal(t, []telemetry.WorkspaceAgentConnectionType{
+			{Direct: true, Count: 1},
+			{Direct: false, DERPRegionCode: "nyc", Count: 2},
+		}, snapshot.WorkspaceAgentConnectionTypes)
 		wsa := snapshot.WorkspaceAgents[0]
 		require.Len(t, wsa.Subsystems, 2)
 		require.Equal(t, string(database.WorkspaceAgentSubsystemEnvbox), wsa.Subsystems[0])
@@ -0,0 +1,100 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxTemplateBuildStatsDays limits how far back build duration stats can be
+// requested.
+const maxTemplateBuildStatsDays = 365
+
+// @Summary Get template build stats
+// @Description Returns the durations of the successful builds of the template
+// @Description per day and transition, along with the percentiles that builds
+// @Description are flagged against.
+// @ID get-template-build-stats
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param days query int false "Number of days to return, including today. Defaults to 30."
+// @Success 200 {object} codersdk.TemplateBuildStats
+// @Router /templates/{template}/build-stats [get]
+func (api *API) templateBuildStats(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	qp := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	days := p.PositiveInt32(qp, codersdk.DefaultTemplateBuildStatsDays, "days")
+	p.ErrorExcessParams(qp)
+	if len(p.Errors) == 0 && (days < 1 || days > maxTemplateBuildStatsDays) {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "days",
+			Detail: fmt.Sprintf("Query param %q must be between 1 and %d.", "days", maxTemplateBuildStatsDays),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	// Stats are rolled up into UTC days, so the range starts at the
+	// beginning of the oldest requested day.
+	startTime := api.Clock.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -int(days-1))
+	stats, err := api.Database.GetTemplateBuildDurationStats(ctx, database.GetTemplateBuildDurationStatsParams{
+		TemplateID: template.ID,
+		StartTime:  startTime,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template build stats.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TemplateBuildStats{
+		BuildTimeStats: api.metricsCache.TemplateBuildTimeStats(template.ID),
+		Intervals: db2sdk.List(stats, func(stat database.TemplateBuildDurationStat) codersdk.TemplateBuildStatsInterval {
+			return codersdk.TemplateBuildStatsInterval{
+				StartTime:  stat.StartTime,
+				EndTime:    stat.EndTime,
+				Transition: codersdk.WorkspaceTransition(stat.Transition),
+				BuildCount: int64(stat.BuildCount),
+				P50Ms:      stat.P50DurationMs,
+				P95Ms:      stat.P95DurationMs,
+				MaxMs:      stat.MaxDurationMs,
+			}
+		}),
+	})
+}
+
+// exceedsTemplateP95 reports whether a successful build took longer than the
+// 95th percentile of the recent builds of its template and transition.
+func (api *API) exceedsTemplateP95(templateVersion database.TemplateVersion, transition codersdk.WorkspaceTransition, job database.ProvisionerJob) bool {
+	if !templateVersion.TemplateID.Valid || job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		return false
+	}
+	if !job.StartedAt.Valid || !job.CompletedAt.Valid {
+		return false
+	}
+	p95 := api.metricsCache.TemplateBuildTimeStats(templateVersion.TemplateID.UUID)[transition].P95
+	if p95 == nil {
+		return false
+	}
+	return job.CompletedAt.Time.Sub(job.StartedAt.Time).Milliseconds() > *p95
+}
@@ -0,0 +1,76 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database/dbrollup"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateBuildStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		db, ps := dbtestutil.NewDB(t)
+		logger := testutil.Logger(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database:                 db,
+			Pubsub:                   ps,
+			IncludeProvisionerDaemon: true,
+			DatabaseRolluper: dbrollup.New(
+				logger.Named("dbrollup"),
+				db,
+				dbrollup.WithInterval(100*time.Millisecond),
+			),
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		var stats codersdk.TemplateBuildStats
+		require.Eventually(t, func() bool {
+			var err error
+			stats, err = client.TemplateBuildStats(ctx, template.ID, codersdk.TemplateBuildStatsRequest{})
+			return err == nil && len(stats.Intervals) > 0
+		}, testutil.WaitLong, testutil.IntervalMedium)
+
+		require.Len(t, stats.Intervals, 1)
+		interval := stats.Intervals[0]
+		require.Equal(t, codersdk.WorkspaceTransitionStart, interval.Transition)
+		require.EqualValues(t, 1, interval.BuildCount)
+		require.Equal(t, 24*time.Hour, interval.EndTime.Sub(interval.StartTime))
+		require.LessOrEqual(t, interval.P50Ms, interval.P95Ms)
+		require.LessOrEqual(t, interval.P95Ms, interval.MaxMs)
+		require.Contains(t, stats.BuildTimeStats, codersdk.WorkspaceTransitionStart)
+	})
+
+	t.Run("InvalidDays", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := client.TemplateBuildStats(ctx, template.ID, codersdk.TemplateBuildStatsRequest{Days: 1000})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,364 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template presets
+// @Description Returns the presets that are managed at the template level.
+// @Description Presets that are defined by template versions are returned by
+// @Description the template version presets endpoint.
+// @ID get-template-presets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplatePreset
+// @Router /templates/{template}/presets [get]
+func (api *API) templatePresets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	presets, err := api.Database.GetTemplatePresetsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template presets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defaults, err := api.Database.GetTemplatePresetGroupDefaultsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template preset defaults.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	res := make([]codersdk.TemplatePreset, 0, len(presets))
+	for _, preset := range presets {
+		res = append(res, convertTemplatePreset(preset, defaults))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Create template preset
+// @ID create-template-preset
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplatePresetRequest true "Preset request"
+// @Success 201 {object} codersdk.TemplatePreset
+// @Router /templates/{template}/presets [post]
+func (api *API) postTemplatePreset(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	var req codersdk.CreateTemplatePresetRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	parameters, ok := api.validateTemplatePreset(ctx, rw, template, req.Parameters, req.DefaultGroupIDs)
+	if !ok {
+		return
+	}
+
+	var (
+		preset   database.TemplatePreset
+		defaults []database.TemplatePresetGroupDefault
+	)
+	err := api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		var err error
+		preset, err = tx.InsertTemplatePreset(ctx, database.InsertTemplatePresetParams{
+			ID:          uuid.New(),
+			TemplateID:  template.ID,
+			Name:        req.Name,
+			Description: req.Description,
+			Parameters:  parameters,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert template preset: %w", err)
+		}
+		defaults, err = setTemplatePresetDefaults(ctx, tx, preset, req.DefaultGroupIDs)
+		return err
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueTemplatePresetsTemplateIDNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Template preset with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating template preset.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplatePreset(preset, defaults))
+}
+
+// @Summary Update template preset
+// @Description Replaces the fields of a template preset, including the groups
+// @Description it is the default of.
+// @ID update-template-preset
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param preset path string true "Template preset ID" format(uuid)
+// @Param request body codersdk.UpdateTemplatePresetRequest true "Preset request"
+// @Success 200 {object} codersdk.TemplatePreset
+// @Router /templates/{template}/presets/{preset} [put]
+func (api *API) putTemplatePreset(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	presetID, ok := httpmw.ParseUUIDParam(rw, r, "preset")
+	if !ok {
+		return
+	}
+	var req codersdk.UpdateTemplatePresetRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !api.templatePresetBelongsToTemplate(ctx, rw, template, presetID) {
+		return
+	}
+	parameters, ok := api.validateTemplatePreset(ctx, rw, template, req.Parameters, req.DefaultGroupIDs)
+	if !ok {
+		return
+	}
+
+	var (
+		preset   database.TemplatePreset
+		defaults []database.TemplatePresetGroupDefault
+	)
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		preset, err = tx.UpdateTemplatePresetByID(ctx, database.UpdateTemplatePresetByIDParams{
+			ID:          presetID,
+			Name:        req.Name,
+			Description: req.Description,
+			Parameters:  parameters,
+			UpdatedAt:   dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update template preset: %w", err)
+		}
+		err = tx.DeleteTemplatePresetGroupDefaultsByPresetID(ctx, preset.ID)
+		if err != nil {
+			return xerrors.Errorf("delete template preset defaults: %w", err)
+		}
+		defaults, err = setTemplatePresetDefaults(ctx, tx, preset, req.DefaultGroupIDs)
+		return err
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueTemplatePresetsTemplateIDNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Template preset with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template preset.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplatePreset(preset, defaults))
+}
+
+// @Summary Delete template preset
+// @ID delete-template-preset
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param preset path string true "Template preset ID" format(uuid)
+// @Success 204
+// @Router /templates/{template}/presets/{preset} [delete]
+func (api *API) deleteTemplatePreset(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	presetID, ok := httpmw.ParseUUIDParam(rw, r, "preset")
+	if !ok {
+		return
+	}
+	if !api.templatePresetBelongsToTemplate(ctx, rw, template, presetID) {
+		return
+	}
+
+	err := api.Database.DeleteTemplatePresetByID(ctx, presetID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template preset.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// templatePresetBelongsToTemplate writes a not found response if the preset
+// does not exist or belongs to another template.
+func (api *API) templatePresetBelongsToTemplate(ctx context.Context, rw http.ResponseWriter, template database.Template, presetID uuid.UUID) bool {
+	preset, err := api.Database.GetTemplatePresetByID(ctx, presetID)
+	if httpapi.Is404Error(err) || (err == nil && preset.TemplateID != template.ID) {
+		httpapi.ResourceNotFound(rw)
+		return false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template preset.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// validateTemplatePreset validates the parameters and default groups of a
+// template preset, and returns the parameter values by name.
+func (api *API) validateTemplatePreset(ctx context.Context, rw http.ResponseWriter, template database.Template, parameters []codersdk.WorkspaceBuildParameter, groupIDs []uuid.UUID) (database.StringMap, bool) {
+	values := make(database.StringMap, len(parameters))
+	var validErrs []codersdk.ValidationError
+	for _, parameter := range parameters {
+		if parameter.Name == "" {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "parameters", Detail: "Parameter names must not be empty."})
+			continue
+		}
+		if _, ok := values[parameter.Name]; ok {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "parameters", Detail: fmt.Sprintf("Parameter %q is set more than once.", parameter.Name)})
+			continue
+		}
+		values[parameter.Name] = parameter.Value
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid template preset parameters.",
+			Validations: validErrs,
+		})
+		return nil, false
+	}
+
+	for _, groupID := range groupIDs {
+		group, err := api.Database.GetGroupByID(ctx, groupID)
+		if httpapi.Is404Error(err) || (err == nil && group.OrganizationID != template.OrganizationID) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The provided group doesn't belong to the organization of the template.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "default_group_ids",
+					Detail: fmt.Sprintf("Group %s doesn't belong to the organization of the template.", groupID),
+				}},
+			})
+			return nil, false
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group.",
+				Detail:  err.Error(),
+			})
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// setTemplatePresetDefaults makes the preset the default of the groups, and
+// returns the defaults of the template.
+func setTemplatePresetDefaults(ctx context.Context, tx database.Store, preset database.TemplatePreset, groupIDs []uuid.UUID) ([]database.TemplatePresetGroupDefault, error) {
+	for _, groupID := range groupIDs {
+		err := tx.UpsertTemplatePresetGroupDefault(ctx, database.UpsertTemplatePresetGroupDefaultParams{
+			TemplateID:       preset.TemplateID,
+			GroupID:          groupID,
+			TemplatePresetID: preset.ID,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("upsert template preset default: %w", err)
+		}
+	}
+	defaults, err := tx.GetTemplatePresetGroupDefaultsByTemplateID(ctx, preset.TemplateID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template preset defaults: %w", err)
+	}
+	return defaults, nil
+}
+
+func convertTemplatePreset(preset database.TemplatePreset, defaults []database.TemplatePresetGroupDefault) codersdk.TemplatePreset {
+	parameters := make([]codersdk.WorkspaceBuildParameter, 0, len(preset.Parameters))
+	for name, value := range preset.Parameters {
+		parameters = append(parameters, codersdk.WorkspaceBuildParameter{
+			Name:  name,
+			Value: value,
+		})
+	}
+	slices.SortFunc(parameters, func(a, b codersdk.WorkspaceBuildParameter) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	groupIDs := make([]uuid.UUID, 0)
+	for _, def := range defaults {
+		if def.TemplatePresetID == preset.ID {
+			groupIDs = append(groupIDs, def.GroupID)
+		}
+	}
+	slices.SortFunc(groupIDs, func(a, b uuid.UUID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return codersdk.TemplatePreset{
+		ID:              preset.ID,
+		TemplateID:      preset.TemplateID,
+		Name:            preset.Name,
+		Description:     preset.Description,
+		Parameters:      parameters,
+		DefaultGroupIDs: groupIDs,
+		CreatedAt:       preset.CreatedAt,
+		UpdatedAt:       preset.UpdatedAt,
+	}
+}
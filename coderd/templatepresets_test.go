@@ -0,0 +1,217 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplatePresets(t *testing.T) {
+	t.Parallel()
+
+	responses := &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{{
+						Name:         "region",
+						Type:         "string",
+						DefaultValue: "us",
+						Mutable:      true,
+						FormType:     proto.ParameterFormType_INPUT,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	}
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, codersdk.Template) {
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, responses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		return client, user, template
+	}
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client, user, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		presets, err := client.TemplatePresets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, presets)
+
+		preset, err := client.CreateTemplatePreset(ctx, template.ID, codersdk.CreateTemplatePresetRequest{
+			Name:        "europe",
+			Description: "Workspaces in Europe.",
+			Parameters:  []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, template.ID, preset.TemplateID)
+		require.Equal(t, "europe", preset.Name)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}, preset.Parameters)
+		require.Empty(t, preset.DefaultGroupIDs)
+
+		_, err = client.CreateTemplatePreset(ctx, template.ID, codersdk.CreateTemplatePresetRequest{
+			Name: "europe",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// The Everyone group has the ID of the organization.
+		updated, err := client.UpdateTemplatePreset(ctx, template.ID, preset.ID, codersdk.UpdateTemplatePresetRequest{
+			Name:            "europe-west",
+			Parameters:      []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu-west"}},
+			DefaultGroupIDs: []uuid.UUID{user.OrganizationID},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "europe-west", updated.Name)
+		require.Empty(t, updated.Description)
+		require.Equal(t, []uuid.UUID{user.OrganizationID}, updated.DefaultGroupIDs)
+
+		presets, err = client.TemplatePresets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, presets, 1)
+		require.Equal(t, updated.ID, presets[0].ID)
+		require.Equal(t, updated.DefaultGroupIDs, presets[0].DefaultGroupIDs)
+
+		err = client.DeleteTemplatePreset(ctx, template.ID, preset.ID)
+		require.NoError(t, err)
+		presets, err = client.TemplatePresets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, presets)
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		t.Parallel()
+		client, _, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateTemplatePreset(ctx, template.ID, codersdk.CreateTemplatePresetRequest{
+			Name: "duplicate",
+			Parameters: []codersdk.WorkspaceBuildParameter{
+				{Name: "region", Value: "eu"},
+				{Name: "region", Value: "us"},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.CreateTemplatePreset(ctx, template.ID, codersdk.CreateTemplatePresetRequest{
+			Name:            "unknown-group",
+			DefaultGroupIDs: []uuid.UUID{uuid.New()},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberCannotManage", func(t *testing.T) {
+		t.Parallel()
+		client, user, template := setup(t)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CreateTemplatePreset(ctx, template.ID, codersdk.CreateTemplatePresetRequest{
+			Name: "europe",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		t.Parallel()
+		client, _, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		preset, err := client.CreateTemplatePreset(ctx, template.ID, codersdk.CreateTemplatePresetRequest{
+			Name:       "europe",
+			Parameters: []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}},
+		})
+		require.NoError(t, err)
+
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		params, err := client.WorkspaceBuildParameters(ctx, workspace.LatestBuild.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "us"}}, params)
+
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:       codersdk.WorkspaceTransitionStart,
+			TemplatePresetID: preset.ID,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		params, err = client.WorkspaceBuildParameters(ctx, build.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}, params)
+	})
+
+	t.Run("GroupDefault", func(t *testing.T) {
+		t.Parallel()
+		client, user, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateTemplatePreset(ctx, template.ID, codersdk.CreateTemplatePresetRequest{
+			Name:            "europe",
+			Parameters:      []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}},
+			DefaultGroupIDs: []uuid.UUID{user.OrganizationID},
+		})
+		require.NoError(t, err)
+
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		params, err := client.WorkspaceBuildParameters(ctx, workspace.LatestBuild.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}, params)
+	})
+
+	t.Run("BuildErrors", func(t *testing.T) {
+		t.Parallel()
+		client, user, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, responses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		other := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		otherPreset, err := client.CreateTemplatePreset(ctx, other.ID, codersdk.CreateTemplatePresetRequest{
+			Name: "other",
+		})
+		require.NoError(t, err)
+
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		// A preset of another template.
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:       codersdk.WorkspaceTransitionStart,
+			TemplatePresetID: otherPreset.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Both kinds of presets.
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:              codersdk.WorkspaceTransitionStart,
+			TemplatePresetID:        otherPreset.ID,
+			TemplateVersionPresetID: uuid.New(),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,317 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template release channels
+// @Description Returns the versions published to the release channels of a
+// @Description template. Channels that nothing is published to are omitted.
+// @ID get-template-release-channels
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateReleaseChannel
+// @Router /templates/{template}/channels [get]
+func (api *API) templateReleaseChannels(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	channels, err := api.Database.GetTemplateReleaseChannelsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template release channels.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	versionNames := make(map[uuid.UUID]string, len(channels))
+	if len(channels) > 0 {
+		versionIDs := make([]uuid.UUID, 0, len(channels))
+		for _, channel := range channels {
+			versionIDs = append(versionIDs, channel.TemplateVersionID)
+		}
+		versions, err := api.Database.GetTemplateVersionsByIDs(ctx, versionIDs)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template versions.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		for _, version := range versions {
+			versionNames[version.ID] = version.Name
+		}
+	}
+
+	res := make([]codersdk.TemplateReleaseChannel, 0, len(channels))
+	for _, channel := range channels {
+		res = append(res, convertTemplateReleaseChannel(channel, versionNames[channel.TemplateVersionID]))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Promote template version to release channel
+// @Description Publishes a template version to a release channel of its
+// @Description template. Builds of workspaces that track the channel and target
+// @Description the active version use the published version instead.
+// @ID promote-template-version-to-release-channel
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param channel path string true "Release channel" Enums(stable,beta)
+// @Param request body codersdk.PromoteTemplateReleaseChannelRequest true "Promote request"
+// @Success 200 {object} codersdk.TemplateReleaseChannel
+// @Router /templates/{template}/channels/{channel} [put]
+func (api *API) putTemplateReleaseChannel(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	channel, ok := parseReleaseChannel(rw, r)
+	if !ok {
+		return
+	}
+
+	var req codersdk.PromoteTemplateReleaseChannelRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	version, err := api.Database.GetTemplateVersionByID(ctx, req.TemplateVersionID)
+	if httpapi.Is404Error(err) || (err == nil && version.TemplateID.UUID != template.ID) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version doesn't belong to the specified template.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if version.Archived {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is archived.",
+		})
+		return
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version job status.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only versions that have been built successfully can be promoted.",
+			Detail:  fmt.Sprintf("Attempted to promote a version with a %s build", job.JobStatus),
+		})
+		return
+	}
+
+	published, err := api.Database.UpsertTemplateReleaseChannel(ctx, database.UpsertTemplateReleaseChannelParams{
+		TemplateID:        template.ID,
+		Channel:           channel,
+		TemplateVersionID: version.ID,
+		UpdatedBy:         apiKey.UserID,
+		UpdatedAt:         dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template release channel.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateReleaseChannel(published, version.Name))
+}
+
+// @Summary Delete template release channel
+// @Description Unpublishes the version of a release channel. Workspaces that
+// @Description track the channel follow the active version until another version
+// @Description is published to it.
+// @ID delete-template-release-channel
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param channel path string true "Release channel" Enums(stable,beta)
+// @Success 204
+// @Router /templates/{template}/channels/{channel} [delete]
+func (api *API) deleteTemplateReleaseChannel(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	channel, ok := parseReleaseChannel(rw, r)
+	if !ok {
+		return
+	}
+
+	err := api.Database.DeleteTemplateReleaseChannel(ctx, database.DeleteTemplateReleaseChannelParams{
+		TemplateID: template.ID,
+		Channel:    channel,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template release channel.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace release channel
+// @ID get-workspace-release-channel
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceReleaseChannel
+// @Router /workspaces/{workspace}/channel [get]
+func (api *API) workspaceReleaseChannel(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	tracked, err := api.Database.GetWorkspaceReleaseChannelByWorkspaceID(ctx, workspace.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceReleaseChannel{})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace release channel.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	res := codersdk.WorkspaceReleaseChannel{Channel: codersdk.ReleaseChannel(tracked.Channel)}
+	published, err := api.Database.GetTemplateReleaseChannel(ctx, database.GetTemplateReleaseChannelParams{
+		TemplateID: workspace.TemplateID,
+		Channel:    tracked.Channel,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template release channel.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		res.TemplateVersionID = &published.TemplateVersionID
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Update workspace release channel
+// @Description Sets the release channel a workspace tracks. Builds of the
+// @Description workspace that target the active version use the version
+// @Description published to the channel instead. An empty channel makes the
+// @Description workspace follow the active version again.
+// @ID update-workspace-release-channel
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceReleaseChannelRequest true "Update workspace release channel request"
+// @Success 204
+// @Router /workspaces/{workspace}/channel [put]
+func (api *API) putWorkspaceReleaseChannel(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.UpdateWorkspaceReleaseChannelRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var err error
+	switch {
+	case req.Channel == "":
+		err = api.Database.DeleteWorkspaceReleaseChannelByWorkspaceID(ctx, workspace.ID)
+	case req.Channel.Valid():
+		err = api.Database.UpsertWorkspaceReleaseChannel(ctx, database.UpsertWorkspaceReleaseChannelParams{
+			WorkspaceID: workspace.ID,
+			Channel:     database.ReleaseChannel(req.Channel),
+			UpdatedAt:   dbtime.Now(),
+		})
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid release channel %q.", req.Channel),
+			Detail:  fmt.Sprintf("Release channels are %v.", codersdk.ReleaseChannels),
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace release channel.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// parseReleaseChannel parses the release channel path parameter, writing an
+// error response if it is not a known channel.
+func parseReleaseChannel(rw http.ResponseWriter, r *http.Request) (database.ReleaseChannel, bool) {
+	channel := codersdk.ReleaseChannel(chi.URLParam(r, "channel"))
+	if !channel.Valid() {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid release channel %q.", channel),
+			Detail:  fmt.Sprintf("Release channels are %v.", codersdk.ReleaseChannels),
+		})
+		return "", false
+	}
+	return database.ReleaseChannel(channel), true
+}
+
+func convertTemplateReleaseChannel(channel database.TemplateReleaseChannel, versionName string) codersdk.TemplateReleaseChannel {
+	return codersdk.TemplateReleaseChannel{
+		TemplateID:          channel.TemplateID,
+		Channel:             codersdk.ReleaseChannel(channel.Channel),
+		TemplateVersionID:   channel.TemplateVersionID,
+		TemplateVersionName: versionName,
+		UpdatedBy:           channel.UpdatedBy,
+		UpdatedAt:           channel.UpdatedAt,
+	}
+}
@@ -0,0 +1,135 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateReleaseChannels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PromoteAndTrack", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		beta := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, beta.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		channels, err := client.TemplateReleaseChannels(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, channels)
+
+		published, err := client.PromoteTemplateReleaseChannel(ctx, template.ID, codersdk.ReleaseChannelBeta, codersdk.PromoteTemplateReleaseChannelRequest{
+			TemplateVersionID: beta.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ReleaseChannelBeta, published.Channel)
+		require.Equal(t, beta.ID, published.TemplateVersionID)
+		require.Equal(t, beta.Name, published.TemplateVersionName)
+
+		channels, err = client.TemplateReleaseChannels(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, channels, 1)
+		require.Equal(t, beta.ID, channels[0].TemplateVersionID)
+
+		// Members can't publish versions, but can choose the channel their
+		// workspace tracks.
+		_, err = member.PromoteTemplateReleaseChannel(ctx, template.ID, codersdk.ReleaseChannelStable, codersdk.PromoteTemplateReleaseChannelRequest{
+			TemplateVersionID: version.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		tracked, err := member.WorkspaceReleaseChannel(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, tracked.Channel)
+
+		err = member.UpdateWorkspaceReleaseChannel(ctx, workspace.ID, codersdk.UpdateWorkspaceReleaseChannelRequest{
+			Channel: codersdk.ReleaseChannelBeta,
+		})
+		require.NoError(t, err)
+		tracked, err = member.WorkspaceReleaseChannel(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ReleaseChannelBeta, tracked.Channel)
+		require.NotNil(t, tracked.TemplateVersionID)
+		require.Equal(t, beta.ID, *tracked.TemplateVersionID)
+
+		// Unpublishing the channel makes tracking workspaces follow the active
+		// version.
+		err = client.DeleteTemplateReleaseChannel(ctx, template.ID, codersdk.ReleaseChannelBeta)
+		require.NoError(t, err)
+		tracked, err = member.WorkspaceReleaseChannel(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ReleaseChannelBeta, tracked.Channel)
+		require.Nil(t, tracked.TemplateVersionID)
+
+		err = member.UpdateWorkspaceReleaseChannel(ctx, workspace.ID, codersdk.UpdateWorkspaceReleaseChannelRequest{})
+		require.NoError(t, err)
+		tracked, err = member.WorkspaceReleaseChannel(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, tracked.Channel)
+	})
+
+	t.Run("InvalidChannel", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.PromoteTemplateReleaseChannel(ctx, template.ID, "nightly", codersdk.PromoteTemplateReleaseChannelRequest{
+			TemplateVersionID: version.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		err = client.UpdateWorkspaceReleaseChannel(ctx, workspace.ID, codersdk.UpdateWorkspaceReleaseChannelRequest{
+			Channel: "nightly",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherTemplateVersion", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		_ = coderdtest.CreateTemplate(t, client, user.OrganizationID, otherVersion.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.PromoteTemplateReleaseChannel(ctx, template.ID, codersdk.ReleaseChannelStable, codersdk.PromoteTemplateReleaseChannelRequest{
+			TemplateVersionID: otherVersion.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"sort"
 	"time"
@@ -100,6 +101,10 @@ func (api *API) deleteTemplate(rw http.ResponseWriter, r *http.Request) {
 		Deleted:   true,
 		UpdatedAt: dbtime.Now(),
 	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error deleting template.",
@@ -495,6 +500,7 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 // @Produce json
 // @Tags Templates
 // @Param organization path string true "Organization ID" format(uuid)
+// @Param fields query string false "Comma-separated list of template fields to return, e.g. `id,name,active_version_id`. Nested fields are selected with dots."
 // @Success 200 {array} codersdk.Template
 // @Router /organizations/{organization}/templates [get]
 func (api *API) templatesByOrganization() http.HandlerFunc {
@@ -516,12 +522,23 @@ func (api *API) templatesByOrganization() http.HandlerFunc {
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Templates
+// @Param fields query string false "Comma-separated list of template fields to return, e.g. `id,name,active_version_id`. Nested fields are selected with dots."
 // @Success 200 {array} codersdk.Template
 // @Router /templates [get]
 func (api *API) fetchTemplates(mutate func(r *http.Request, arg *database.GetTemplatesWithFilterParams)) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		p := httpapi.NewQueryParamParser()
+		fields := httpapi.ParseFields[codersdk.Template](p, r.URL.Query(), "fields")
+		if len(p.Errors) > 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid query parameters.",
+				Validations: p.Errors,
+			})
+			return
+		}
+
 		queryStr := r.URL.Query().Get("q")
 		filter, errs := searchquery.Templates(ctx, api.Database, queryStr)
 		if len(errs) > 0 {
@@ -568,7 +585,7 @@ func (api *API) fetchTemplates(mutate func(r *http.Request, arg *database.GetTem
 			return
 		}
 
-		httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplates(templates))
+		httpapi.WriteFields(ctx, rw, http.StatusOK, fields, api.convertTemplates(templates))
 	}
 }
 
@@ -710,6 +727,42 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.TimeTilDormantAutoDeleteMillis < 0 || (req.TimeTilDormantAutoDeleteMillis > 0 && req.TimeTilDormantAutoDeleteMillis < minTTL) {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_autodelete_ms", Detail: "Value must be at least one minute."})
 	}
+	// Defaults to the existing.
+	maxConcurrentJobsPerUser := template.MaxConcurrentJobsPerUser
+	if req.MaxConcurrentJobsPerUser != nil {
+		if *req.MaxConcurrentJobsPerUser < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_concurrent_jobs_per_user", Detail: "Must be a positive integer."})
+		}
+		maxConcurrentJobsPerUser = *req.MaxConcurrentJobsPerUser
+	}
+	// Defaults to the existing.
+	resourceCeilings := template.ResourceCeilings
+	if req.ResourceCeilings != nil {
+		if req.ResourceCeilings.CPU < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "resource_ceilings.cpu", Detail: "Must not be negative."})
+		}
+		if req.ResourceCeilings.MemoryGiB < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "resource_ceilings.memory_gib", Detail: "Must not be negative."})
+		}
+		if req.ResourceCeilings.DiskGiB < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "resource_ceilings.disk_gib", Detail: "Must not be negative."})
+		}
+		if req.ResourceCeilings.DailyCost < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "resource_ceilings.daily_cost", Detail: "Must not be negative."})
+		}
+		resourceCeilings = database.TemplateResourceCeilings(*req.ResourceCeilings)
+	}
+	// Defaults to the existing.
+	requiredProvisionerTags := template.RequiredProvisionerTags
+	if req.RequiredProvisionerTags != nil {
+		for key, value := range *req.RequiredProvisionerTags {
+			if key == "" || value == "" {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "required_provisioner_tags", Detail: "Tag keys and values must not be empty."})
+				break
+			}
+		}
+		requiredProvisionerTags = database.StringMap(*req.RequiredProvisionerTags)
+	}
 	maxPortShareLevel := template.MaxPortSharingLevel
 	if req.MaxPortShareLevel != nil && *req.MaxPortShareLevel != portSharer.ConvertMaxLevel(template.MaxPortSharingLevel) {
 		err := portSharer.ValidateTemplateMaxLevel(*req.MaxPortShareLevel)
@@ -754,6 +807,9 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.RequireActiveVersion == template.RequireActiveVersion &&
 			(deprecationMessage == template.Deprecated) &&
 			(classicTemplateFlow == template.UseClassicParameterFlow) &&
+			maxConcurrentJobsPerUser == template.MaxConcurrentJobsPerUser &&
+			resourceCeilings == template.ResourceCeilings &&
+			maps.Equal(requiredProvisionerTags, template.RequiredProvisionerTags) &&
 			maxPortShareLevel == template.MaxPortSharingLevel {
 			return nil
 		}
@@ -796,6 +852,9 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			GroupACL:                     groupACL,
 			MaxPortSharingLevel:          maxPortShareLevel,
 			UseClassicParameterFlow:      classicTemplateFlow,
+			MaxConcurrentJobsPerUser:     maxConcurrentJobsPerUser,
+			ResourceCeilings:             resourceCeilings,
+			RequiredProvisionerTags:      requiredProvisionerTags,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -1004,6 +1063,12 @@ func (api *API) templateExamples(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, ex)
 }
 
+// ConvertTemplates converts templates to their API representation, sorted by
+// active user count. It is exported for enterprise handlers that list templates.
+func (api *API) ConvertTemplates(templates []database.Template) []codersdk.Template {
+	return api.convertTemplates(templates)
+}
+
 func (api *API) convertTemplates(templates []database.Template) []codersdk.Template {
 	apiTemplates := make([]codersdk.Template, 0, len(templates))
 
@@ -1040,6 +1105,9 @@ func (api *API) convertTemplate(
 	portSharer := *(api.PortSharer.Load())
 	maxPortShareLevel := portSharer.ConvertMaxLevel(template.MaxPortSharingLevel)
 
+	requiredProvisionerTags := map[string]string{}
+	maps.Copy(requiredProvisionerTags, template.RequiredProvisionerTags)
+
 	return codersdk.Template{
 		ID:                             template.ID,
 		CreatedAt:                      template.CreatedAt,
@@ -1074,11 +1142,14 @@ func (api *API) convertTemplate(
 			DaysOfWeek: codersdk.BitmapToWeekdays(template.AutostartAllowedDays()),
 		},
 		// These values depend on entitlements and come from the templateAccessControl
-		RequireActiveVersion:    templateAccessControl.RequireActiveVersion,
-		Deprecated:              templateAccessControl.IsDeprecated(),
-		DeprecationMessage:      templateAccessControl.Deprecated,
-		MaxPortShareLevel:       maxPortShareLevel,
-		UseClassicParameterFlow: template.UseClassicParameterFlow,
+		RequireActiveVersion:     templateAccessControl.RequireActiveVersion,
+		Deprecated:               templateAccessControl.IsDeprecated(),
+		DeprecationMessage:       templateAccessControl.Deprecated,
+		MaxPortShareLevel:        maxPortShareLevel,
+		UseClassicParameterFlow:  template.UseClassicParameterFlow,
+		MaxConcurrentJobsPerUser: template.MaxConcurrentJobsPerUser,
+		ResourceCeilings:         codersdk.TemplateResourceCeilings(template.ResourceCeilings),
+		RequiredProvisionerTags:  requiredProvisionerTags,
 	}
 }
 
@@ -685,6 +685,34 @@ func TestTemplates(t *testing.T) {
 			require.Equal(t, expectedTemplate.DeprecationMessage, actualTemplate.DeprecationMessage)
 		}
 	})
+
+	t.Run("Fields", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		templates, err := client.Templates(ctx, codersdk.TemplateFilter{
+			Fields: []string{"id", "name"},
+		})
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, template.ID, templates[0].ID)
+		require.Equal(t, template.Name, templates[0].Name)
+		// Fields that were not selected are not returned.
+		require.Equal(t, uuid.Nil, templates[0].OrganizationID)
+		require.Equal(t, uuid.Nil, templates[0].ActiveVersionID)
+
+		_, err = client.Templates(ctx, codersdk.TemplateFilter{
+			Fields: []string{"id", "unknown"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
 }
 
 func TestTemplatesByOrganization(t *testing.T) {
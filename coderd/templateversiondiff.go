@@ -0,0 +1,588 @@
+package coderd
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pkg/diff/myers"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template version diff
+// @Description Compares the planned resources, parameters and files of a template
+// @Description version against a base version, the active version of the template by
+// @Description default. Files are only compared for users who can update the template.
+// @ID get-template-version-diff
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param base query string false "Base template version ID, defaults to the active version of the template" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionDiff
+// @Router /templateversions/{templateversion}/diff [get]
+func (api *API) templateVersionDiff(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	queryParams := r.URL.Query()
+	parser := httpapi.NewQueryParamParser()
+	baseID := parser.UUID(queryParams, uuid.Nil, "base")
+	parser.ErrorExcessParams(queryParams)
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	if baseID == uuid.Nil {
+		if !templateVersion.TemplateID.Valid {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Template version is not assigned to a template, specify a base version to compare against.",
+			})
+			return
+		}
+		template, err := api.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		baseID = template.ActiveVersionID
+	}
+
+	baseVersion, err := api.Database.GetTemplateVersionByID(ctx, baseID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Base template version not found.",
+			Validations: []codersdk.ValidationError{
+				{Field: "base", Detail: "template version not found"},
+			},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching base template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	versions := []database.TemplateVersion{baseVersion, templateVersion}
+	inputs := make([]templateVersionDiffInput, 0, len(versions))
+	for _, version := range versions {
+		job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching provisioner job.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !job.CompletedAt.Valid {
+			httpapi.Write(ctx, rw, http.StatusTooEarly, codersdk.Response{
+				Message: "Template version job has not finished",
+				Detail:  "Template version " + version.ID.String() + " is still being imported.",
+			})
+			return
+		}
+
+		input, err := api.templateVersionDiffInput(ctx, version, job)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template version resources and parameters.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		inputs = append(inputs, input)
+	}
+
+	diff, err := diffTemplateVersions(inputs[0], inputs[1])
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error comparing template versions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	diff.TemplateVersionID = templateVersion.ID
+	diff.BaseTemplateVersionID = baseVersion.ID
+	httpapi.Write(ctx, rw, http.StatusOK, diff)
+}
+
+const (
+	// maxFileDiffLines is the maximum number of differing lines of a file that
+	// are compared to count additions and deletions. Comparing more is too
+	// expensive, so larger changes are reported without counts.
+	maxFileDiffLines = 10000
+	// maxAuditFileDiffs is the maximum number of files listed in the audit log
+	// of a template version change.
+	maxAuditFileDiffs = 100
+)
+
+// templateVersionDiffInput is what is compared between template versions.
+type templateVersionDiffInput struct {
+	resourceKeys []templateVersionDiffResourceKey
+	resources    map[templateVersionDiffResourceKey]templateVersionDiffResource
+	parameters   []codersdk.TemplateVersionParameter
+	// files are the contents of the source files by path. They are nil if
+	// the user can't read the files of the template.
+	files map[string][]byte
+}
+
+type templateVersionDiffResourceKey struct {
+	transition codersdk.WorkspaceTransition
+	modulePath string
+	typ        string
+	name       string
+}
+
+// templateVersionDiffResource holds the fields of a resource that are
+// compared. The JSON field names are reported as changed fields.
+type templateVersionDiffResource struct {
+	Hide         bool                       `json:"hide"`
+	Icon         string                     `json:"icon"`
+	InstanceType string                     `json:"instance_type"`
+	DailyCost    int32                      `json:"daily_cost"`
+	Metadata     map[string]string          `json:"metadata"`
+	Agents       []templateVersionDiffAgent `json:"agents"`
+}
+
+type templateVersionDiffAgent struct {
+	Name            string   `json:"name"`
+	OperatingSystem string   `json:"operating_system"`
+	Architecture    string   `json:"architecture"`
+	Apps            []string `json:"apps"`
+}
+
+func (api *API) templateVersionDiffInput(ctx context.Context, version database.TemplateVersion, job database.ProvisionerJob) (templateVersionDiffInput, error) {
+	input := templateVersionDiffInput{
+		resources: map[templateVersionDiffResourceKey]templateVersionDiffResource{},
+	}
+
+	// nolint:gocritic // GetWorkspaceResourcesByJobID is a system function.
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(dbauthz.AsSystemRestricted(ctx), job.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return input, xerrors.Errorf("get resources: %w", err)
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+
+	// nolint:gocritic // GetWorkspaceAgentsByResourceIDs is a system function.
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return input, xerrors.Errorf("get agents: %w", err)
+	}
+	agentIDs := make([]uuid.UUID, 0, len(agents))
+	for _, agent := range agents {
+		agentIDs = append(agentIDs, agent.ID)
+	}
+
+	// nolint:gocritic // GetWorkspaceAppsByAgentIDs is a system function.
+	apps, err := api.Database.GetWorkspaceAppsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return input, xerrors.Errorf("get apps: %w", err)
+	}
+
+	// nolint:gocritic // GetWorkspaceResourceMetadataByResourceIDs is a system function.
+	metadata, err := api.Database.GetWorkspaceResourceMetadataByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return input, xerrors.Errorf("get resource metadata: %w", err)
+	}
+
+	for _, resource := range resources {
+		key := templateVersionDiffResourceKey{
+			transition: codersdk.WorkspaceTransition(resource.Transition),
+			modulePath: resource.ModulePath.String,
+			typ:        resource.Type,
+			name:       resource.Name,
+		}
+		diffResource := templateVersionDiffResource{
+			Hide:         resource.Hide,
+			Icon:         resource.Icon,
+			InstanceType: resource.InstanceType.String,
+			DailyCost:    resource.DailyCost,
+			Metadata:     map[string]string{},
+			Agents:       []templateVersionDiffAgent{},
+		}
+		for _, datum := range metadata {
+			if datum.WorkspaceResourceID == resource.ID {
+				diffResource.Metadata[datum.Key] = datum.Value.String
+			}
+		}
+		for _, agent := range agents {
+			if agent.ResourceID != resource.ID {
+				continue
+			}
+			diffAgent := templateVersionDiffAgent{
+				Name:            agent.Name,
+				OperatingSystem: agent.OperatingSystem,
+				Architecture:    agent.Architecture,
+				Apps:            []string{},
+			}
+			for _, app := range apps {
+				if app.AgentID == agent.ID {
+					diffAgent.Apps = append(diffAgent.Apps, app.Slug)
+				}
+			}
+			slices.Sort(diffAgent.Apps)
+			diffResource.Agents = append(diffResource.Agents, diffAgent)
+		}
+		sort.Slice(diffResource.Agents, func(i, j int) bool {
+			return diffResource.Agents[i].Name < diffResource.Agents[j].Name
+		})
+
+		if _, ok := input.resources[key]; !ok {
+			input.resourceKeys = append(input.resourceKeys, key)
+		}
+		input.resources[key] = diffResource
+	}
+
+	parameters, err := api.Database.GetTemplateVersionParameters(ctx, version.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return input, xerrors.Errorf("get parameters: %w", err)
+	}
+	input.parameters, err = db2sdk.TemplateVersionParameters(parameters)
+	if err != nil {
+		return input, xerrors.Errorf("convert parameters: %w", err)
+	}
+
+	input.files, err = api.templateVersionFiles(ctx, job)
+	if err != nil && !dbauthz.IsNotAuthorizedError(err) {
+		return input, xerrors.Errorf("get files: %w", err)
+	}
+	return input, nil
+}
+
+// templateVersionFiles returns the contents of the regular files in the source
+// archive of a template version import job by path.
+func (api *API) templateVersionFiles(ctx context.Context, job database.ProvisionerJob) (map[string][]byte, error) {
+	file, err := api.Database.GetFileByID(ctx, job.FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	reader := tar.NewReader(bytes.NewReader(file.Data))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		// The archive is already in memory, so its files are bounded by its size.
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", header.Name, err)
+		}
+		files[path.Clean(header.Name)] = content
+	}
+	return files, nil
+}
+
+// templateVersionAuditFields summarizes how the files of a template version
+// differ from a base version for its audit log. Failures are logged instead of
+// returned, so they don't fail the audited request, and nil is returned.
+func (api *API) templateVersionAuditFields(ctx context.Context, baseID uuid.UUID, version database.TemplateVersion) *audit.TemplateVersionFields {
+	logger := api.Logger.With(
+		slog.F("template_version_id", version.ID),
+		slog.F("base_template_version_id", baseID),
+	)
+	//nolint:gocritic // The audit log of the user's change is written by the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	base, err := api.Database.GetTemplateVersionByID(ctx, baseID)
+	if err != nil {
+		logger.Warn(ctx, "failed to get base template version for audit log", slog.Error(err))
+		return nil
+	}
+	versions := []database.TemplateVersion{base, version}
+	files := make([]map[string][]byte, 0, len(versions))
+	for _, version := range versions {
+		job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+		if err != nil {
+			logger.Warn(ctx, "failed to get template version job for audit log", slog.Error(err))
+			return nil
+		}
+		versionFiles, err := api.templateVersionFiles(ctx, job)
+		if err != nil {
+			logger.Warn(ctx, "failed to get template version files for audit log", slog.Error(err))
+			return nil
+		}
+		files = append(files, versionFiles)
+	}
+
+	fields := &audit.TemplateVersionFields{
+		TemplateVersionID:     version.ID,
+		BaseTemplateVersionID: baseID,
+		Files:                 diffTemplateVersionFiles(files[0], files[1]),
+	}
+	if len(fields.Files) > maxAuditFileDiffs {
+		fields.Files = fields.Files[:maxAuditFileDiffs]
+		fields.FilesTruncated = true
+	}
+	return fields
+}
+
+// diffTemplateVersions compares the resources and parameters of two template
+// versions. Items are listed in the order of the current version, followed by
+// the items removed from the base version.
+func diffTemplateVersions(base, current templateVersionDiffInput) (codersdk.TemplateVersionDiff, error) {
+	diff := codersdk.TemplateVersionDiff{
+		Resources:  []codersdk.TemplateVersionResourceDiff{},
+		Parameters: []codersdk.TemplateVersionParameterDiff{},
+	}
+
+	for _, key := range current.resourceKeys {
+		resourceDiff := codersdk.TemplateVersionResourceDiff{
+			Transition: key.transition,
+			ModulePath: key.modulePath,
+			Type:       key.typ,
+			Name:       key.name,
+		}
+		baseResource, ok := base.resources[key]
+		if !ok {
+			resourceDiff.Change = codersdk.TemplateVersionDiffChangeAdded
+			diff.Resources = append(diff.Resources, resourceDiff)
+			continue
+		}
+		changed, err := changedJSONFields(baseResource, current.resources[key])
+		if err != nil {
+			return diff, xerrors.Errorf("compare resource %s.%s: %w", key.typ, key.name, err)
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		resourceDiff.Change = codersdk.TemplateVersionDiffChangeModified
+		resourceDiff.ChangedFields = changed
+		diff.Resources = append(diff.Resources, resourceDiff)
+	}
+	for _, key := range base.resourceKeys {
+		if _, ok := current.resources[key]; ok {
+			continue
+		}
+		diff.Resources = append(diff.Resources, codersdk.TemplateVersionResourceDiff{
+			Transition: key.transition,
+			ModulePath: key.modulePath,
+			Type:       key.typ,
+			Name:       key.name,
+			Change:     codersdk.TemplateVersionDiffChangeRemoved,
+		})
+	}
+
+	baseParameters := make(map[string]codersdk.TemplateVersionParameter, len(base.parameters))
+	for _, parameter := range base.parameters {
+		baseParameters[parameter.Name] = parameter
+	}
+	currentParameters := make(map[string]struct{}, len(current.parameters))
+	for _, parameter := range current.parameters {
+		currentParameters[parameter.Name] = struct{}{}
+		baseParameter, ok := baseParameters[parameter.Name]
+		if !ok {
+			diff.Parameters = append(diff.Parameters, codersdk.TemplateVersionParameterDiff{
+				Name:    parameter.Name,
+				Change:  codersdk.TemplateVersionDiffChangeAdded,
+				Current: &parameter,
+			})
+			continue
+		}
+		changed, err := changedJSONFields(baseParameter, parameter)
+		if err != nil {
+			return diff, xerrors.Errorf("compare parameter %q: %w", parameter.Name, err)
+		}
+		// The plaintext description is derived from the description.
+		changed = slices.DeleteFunc(changed, func(field string) bool {
+			return field == "description_plaintext"
+		})
+		if len(changed) == 0 {
+			continue
+		}
+		diff.Parameters = append(diff.Parameters, codersdk.TemplateVersionParameterDiff{
+			Name:          parameter.Name,
+			Change:        codersdk.TemplateVersionDiffChangeModified,
+			ChangedFields: changed,
+			Previous:      &baseParameter,
+			Current:       &parameter,
+		})
+	}
+	for _, parameter := range base.parameters {
+		if _, ok := currentParameters[parameter.Name]; ok {
+			continue
+		}
+		diff.Parameters = append(diff.Parameters, codersdk.TemplateVersionParameterDiff{
+			Name:     parameter.Name,
+			Change:   codersdk.TemplateVersionDiffChangeRemoved,
+			Previous: &parameter,
+		})
+	}
+
+	diff.Files = []codersdk.TemplateVersionFileDiff{}
+	if base.files != nil && current.files != nil {
+		diff.Files = diffTemplateVersionFiles(base.files, current.files)
+	}
+
+	return diff, nil
+}
+
+// diffTemplateVersionFiles compares the source files of two template versions.
+// Files are sorted by path.
+func diffTemplateVersionFiles(base, current map[string][]byte) []codersdk.TemplateVersionFileDiff {
+	paths := make([]string, 0, len(base)+len(current))
+	for name := range base {
+		paths = append(paths, name)
+	}
+	for name := range current {
+		if _, ok := base[name]; !ok {
+			paths = append(paths, name)
+		}
+	}
+	slices.Sort(paths)
+
+	diffs := []codersdk.TemplateVersionFileDiff{}
+	for _, name := range paths {
+		baseContent, inBase := base[name]
+		currentContent, inCurrent := current[name]
+		fileDiff := codersdk.TemplateVersionFileDiff{Path: name}
+		switch {
+		case !inBase:
+			fileDiff.Change = codersdk.TemplateVersionDiffChangeAdded
+		case !inCurrent:
+			fileDiff.Change = codersdk.TemplateVersionDiffChangeRemoved
+		case bytes.Equal(baseContent, currentContent):
+			continue
+		default:
+			fileDiff.Change = codersdk.TemplateVersionDiffChangeModified
+		}
+		if additions, deletions, ok := countChangedLines(baseContent, currentContent); ok {
+			fileDiff.Additions = &additions
+			fileDiff.Deletions = &deletions
+		}
+		diffs = append(diffs, fileDiff)
+	}
+	return diffs
+}
+
+// countChangedLines returns the number of lines added and deleted between two
+// versions of a file. ok is false if either version is binary, or the change
+// is too large to compare.
+func countChangedLines(a, b []byte) (additions, deletions int, ok bool) {
+	if !isTextFile(a) || !isTextFile(b) {
+		return 0, 0, false
+	}
+	linesA, linesB := splitLines(a), splitLines(b)
+
+	// Lines at the start and end are usually unchanged, and skipping them keeps
+	// the comparison cheap.
+	for len(linesA) > 0 && len(linesB) > 0 && linesA[0] == linesB[0] {
+		linesA, linesB = linesA[1:], linesB[1:]
+	}
+	for len(linesA) > 0 && len(linesB) > 0 && linesA[len(linesA)-1] == linesB[len(linesB)-1] {
+		linesA, linesB = linesA[:len(linesA)-1], linesB[:len(linesB)-1]
+	}
+	if len(linesA) > 0 && len(linesB) > 0 && len(linesA)+len(linesB) > maxFileDiffLines {
+		return 0, 0, false
+	}
+
+	script := myers.Diff(context.Background(), linePair{a: linesA, b: linesB})
+	additions, deletions = script.Stat()
+	return additions, deletions, true
+}
+
+func isTextFile(content []byte) bool {
+	return utf8.Valid(content) && !bytes.Contains(content, []byte{0})
+}
+
+// splitLines splits content into lines, keeping the line endings so that a
+// missing newline at the end of the file counts as a change.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// linePair compares two files line by line.
+type linePair struct {
+	a, b []string
+}
+
+func (p linePair) LenA() int             { return len(p.a) }
+func (p linePair) LenB() int             { return len(p.b) }
+func (p linePair) Equal(ai, bi int) bool { return p.a[ai] == p.b[bi] }
+
+// changedJSONFields returns the sorted names of the top-level JSON fields that
+// differ between a and b, which must be of the same type.
+func changedJSONFields(a, b any) ([]string, error) {
+	fieldsA, err := jsonFields(a)
+	if err != nil {
+		return nil, err
+	}
+	fieldsB, err := jsonFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for name, valueA := range fieldsA {
+		if valueB, ok := fieldsB[name]; !ok || !bytes.Equal(valueA, valueB) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range fieldsB {
+		if _, ok := fieldsA[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+	return changed, nil
+}
+
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, xerrors.Errorf("unmarshal: %w", err)
+	}
+	return fields, nil
+}
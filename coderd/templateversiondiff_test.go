@@ -0,0 +1,178 @@
+package coderd_test
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionDiff(t *testing.T) {
+	t.Parallel()
+
+	responses := func(instanceType string, resources []string, parameters ...*proto.RichParameter) *echo.Responses {
+		protoResources := []*proto.Resource{}
+		for _, name := range resources {
+			protoResources = append(protoResources, &proto.Resource{
+				Name:         name,
+				Type:         "example",
+				InstanceType: instanceType,
+			})
+		}
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						Resources:  protoResources,
+						Parameters: parameters,
+					},
+				},
+			}},
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: protoResources,
+					},
+				},
+			}},
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		activeResponses := responses("small", []string{"kept", "removed"},
+			&proto.RichParameter{Name: "region", Type: "string", DefaultValue: "us"},
+			&proto.RichParameter{Name: "unchanged", Type: "string", DefaultValue: "a"},
+			&proto.RichParameter{Name: "legacy", Type: "string", DefaultValue: "b"},
+		)
+		activeResponses.ExtraFiles = map[string][]byte{
+			"startup.sh": []byte("one\ntwo\nthree\n"),
+			"README.md":  []byte("unchanged\n"),
+			"icon.png":   {0x89, 'P', 'N', 'G', 0x00},
+		}
+		activeVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, activeResponses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, activeVersion.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, activeVersion.ID)
+		versionResponses := responses("large", []string{"kept", "added"},
+			&proto.RichParameter{Name: "region", Type: "string", DefaultValue: "eu"},
+			&proto.RichParameter{Name: "unchanged", Type: "string", DefaultValue: "a"},
+			&proto.RichParameter{Name: "size", Type: "number", DefaultValue: "1"},
+		)
+		versionResponses.ExtraFiles = map[string][]byte{
+			"startup.sh": []byte("one\n2\nthree\nfour\n"),
+			"README.md":  []byte("unchanged\n"),
+			"icon.png":   {0x89, 'P', 'N', 'G', 0x01},
+		}
+		version := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, versionResponses, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		diff, err := client.TemplateVersionDiff(ctx, version.ID, uuid.Nil)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, diff.TemplateVersionID)
+		require.Equal(t, activeVersion.ID, diff.BaseTemplateVersionID)
+
+		// Template imports plan both the start and stop transitions.
+		changes := map[codersdk.TemplateVersionDiffChange][]string{}
+		for _, resource := range diff.Resources {
+			if resource.Transition != codersdk.WorkspaceTransitionStart {
+				continue
+			}
+			changes[resource.Change] = append(changes[resource.Change], resource.Name)
+			if resource.Change == codersdk.TemplateVersionDiffChangeModified {
+				require.Equal(t, []string{"instance_type"}, resource.ChangedFields)
+			}
+		}
+		require.Equal(t, map[codersdk.TemplateVersionDiffChange][]string{
+			codersdk.TemplateVersionDiffChangeAdded:    {"added"},
+			codersdk.TemplateVersionDiffChangeModified: {"kept"},
+			codersdk.TemplateVersionDiffChangeRemoved:  {"removed"},
+		}, changes)
+
+		require.Len(t, diff.Parameters, 3)
+		require.Equal(t, "region", diff.Parameters[0].Name)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeModified, diff.Parameters[0].Change)
+		require.Equal(t, []string{"default_value"}, diff.Parameters[0].ChangedFields)
+		require.Equal(t, "us", diff.Parameters[0].Previous.DefaultValue)
+		require.Equal(t, "eu", diff.Parameters[0].Current.DefaultValue)
+		require.Equal(t, "size", diff.Parameters[1].Name)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeAdded, diff.Parameters[1].Change)
+		require.Nil(t, diff.Parameters[1].Previous)
+		require.Equal(t, "legacy", diff.Parameters[2].Name)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeRemoved, diff.Parameters[2].Change)
+		require.Nil(t, diff.Parameters[2].Current)
+
+		// The echo provisioner writes its responses to the archive, ignore them.
+		files := slices.DeleteFunc(diff.Files, func(file codersdk.TemplateVersionFileDiff) bool {
+			return strings.HasSuffix(file.Path, ".protobuf")
+		})
+		require.Equal(t, []codersdk.TemplateVersionFileDiff{
+			// Lines are not counted for binary files.
+			{Path: "icon.png", Change: codersdk.TemplateVersionDiffChangeModified},
+			{Path: "startup.sh", Change: codersdk.TemplateVersionDiffChangeModified, Additions: ptr.Ref(2), Deletions: ptr.Ref(1)},
+		}, files)
+
+		// Comparing the active version against itself yields no changes.
+		diff, err = client.TemplateVersionDiff(ctx, activeVersion.ID, uuid.Nil)
+		require.NoError(t, err)
+		require.Empty(t, diff.Resources)
+		require.Empty(t, diff.Parameters)
+		require.Empty(t, diff.Files)
+
+		// Any version can be used as the base.
+		diff, err = client.TemplateVersionDiff(ctx, activeVersion.ID, version.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, diff.BaseTemplateVersionID)
+		require.Len(t, diff.Parameters, 3)
+	})
+
+	t.Run("NotImported", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.TemplateVersionDiff(ctx, version.ID, version.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusTooEarly, apiErr.StatusCode())
+	})
+
+	t.Run("NoTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.TemplateVersionDiff(ctx, version.ID, uuid.Nil)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.TemplateVersionDiff(ctx, version.ID, uuid.New())
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
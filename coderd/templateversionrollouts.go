@@ -0,0 +1,248 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template version rollout
+// @Description Returns the rollout of a template, along with the failure rates
+// @Description of the rollout version and the active version since the rollout
+// @Description started.
+// @ID get-template-version-rollout
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionRollout
+// @Router /templates/{template}/rollout [get]
+func (api *API) templateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	rollout, err := api.Database.GetTemplateVersionRolloutByTemplateID(ctx, template.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The template has no rollout.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version rollout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	res, err := api.convertTemplateVersionRollout(ctx, template, rollout)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version rollout stats.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Update template version rollout
+// @Description Starts or updates the rollout of a template version. Builds that
+// @Description target the active version use the rollout version instead if
+// @Description their workspace falls within the percentage, or its owner is a
+// @Description member of the group. Promoting the version completes the rollout.
+// @ID update-template-version-rollout
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpdateTemplateVersionRolloutRequest true "Rollout request"
+// @Success 200 {object} codersdk.TemplateVersionRollout
+// @Router /templates/{template}/rollout [put]
+func (api *API) putTemplateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	var req codersdk.UpdateTemplateVersionRolloutRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	version, err := api.Database.GetTemplateVersionByID(ctx, req.TemplateVersionID)
+	if httpapi.Is404Error(err) || (err == nil && version.TemplateID.UUID != template.ID) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version doesn't belong to the specified template.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if version.ID == template.ActiveVersionID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is already the active version.",
+		})
+		return
+	}
+	if version.Archived {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is archived.",
+		})
+		return
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version job status.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only versions that have been built successfully can be rolled out.",
+			Detail:  fmt.Sprintf("Attempted to roll out a version with a %s build", job.JobStatus),
+		})
+		return
+	}
+
+	groupID := uuid.NullUUID{}
+	if req.GroupID != nil {
+		group, err := api.Database.GetGroupByID(ctx, *req.GroupID)
+		if httpapi.Is404Error(err) || (err == nil && group.OrganizationID != template.OrganizationID) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The provided group doesn't belong to the organization of the template.",
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		groupID = uuid.NullUUID{UUID: group.ID, Valid: true}
+	}
+
+	now := dbtime.Now()
+	rollout, err := api.Database.UpsertTemplateVersionRollout(ctx, database.UpsertTemplateVersionRolloutParams{
+		TemplateID:        template.ID,
+		TemplateVersionID: version.ID,
+		Percent:           req.Percent,
+		GroupID:           groupID,
+		CreatedBy:         apiKey.UserID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template version rollout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	res, err := api.convertTemplateVersionRollout(ctx, template, rollout)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version rollout stats.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Delete template version rollout
+// @Description Ends the rollout of a template without promoting its version.
+// @Description Builds that target the active version use it again.
+// @ID delete-template-version-rollout
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 204
+// @Router /templates/{template}/rollout [delete]
+func (api *API) deleteTemplateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	err := api.Database.DeleteTemplateVersionRolloutByTemplateID(ctx, template.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template version rollout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// convertTemplateVersionRollout converts a rollout and counts the builds of
+// the rollout version and the active version since the rollout started.
+func (api *API) convertTemplateVersionRollout(ctx context.Context, template database.Template, rollout database.TemplateVersionRollout) (codersdk.TemplateVersionRollout, error) {
+	counts, err := api.Database.GetTemplateVersionRolloutBuildCounts(ctx, database.GetTemplateVersionRolloutBuildCountsParams{
+		TemplateID:   template.ID,
+		CreatedAfter: rollout.CreatedAt,
+	})
+	if err != nil {
+		return codersdk.TemplateVersionRollout{}, err
+	}
+	stats := func(versionID uuid.UUID) codersdk.TemplateVersionRolloutStats {
+		s := codersdk.TemplateVersionRolloutStats{TemplateVersionID: versionID}
+		for _, count := range counts {
+			if count.TemplateVersionID == versionID {
+				s.TotalBuilds = count.TotalBuilds
+				s.FailedBuilds = count.FailedBuilds
+			}
+		}
+		if s.TotalBuilds > 0 {
+			s.FailureRate = float64(s.FailedBuilds) / float64(s.TotalBuilds)
+		}
+		return s
+	}
+
+	res := codersdk.TemplateVersionRollout{
+		TemplateID:          rollout.TemplateID,
+		TemplateVersionID:   rollout.TemplateVersionID,
+		Percent:             rollout.Percent,
+		CreatedBy:           rollout.CreatedBy,
+		CreatedAt:           rollout.CreatedAt,
+		UpdatedAt:           rollout.UpdatedAt,
+		RolloutVersionStats: stats(rollout.TemplateVersionID),
+		ActiveVersionStats:  stats(template.ActiveVersionID),
+	}
+	if rollout.GroupID.Valid {
+		res.GroupID = &rollout.GroupID.UUID
+	}
+	return res, nil
+}
@@ -0,0 +1,149 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionRollout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RolloutAndPromote", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		canary := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, canary.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.TemplateVersionRollout(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		rollout, err := client.UpdateTemplateVersionRollout(ctx, template.ID, codersdk.UpdateTemplateVersionRolloutRequest{
+			TemplateVersionID: canary.ID,
+			Percent:           100,
+		})
+		require.NoError(t, err)
+		require.Equal(t, canary.ID, rollout.TemplateVersionID)
+		require.Equal(t, int32(100), rollout.Percent)
+		require.Nil(t, rollout.GroupID)
+
+		// New workspaces build the active version of the template, which is
+		// the rollout version for every workspace.
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, canary.ID, workspace.LatestBuild.TemplateVersionID)
+
+		rollout, err = client.TemplateVersionRollout(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, canary.ID, rollout.RolloutVersionStats.TemplateVersionID)
+		require.Equal(t, int64(1), rollout.RolloutVersionStats.TotalBuilds)
+		require.Equal(t, int64(0), rollout.RolloutVersionStats.FailedBuilds)
+		require.Equal(t, version.ID, rollout.ActiveVersionStats.TemplateVersionID)
+		require.Equal(t, int64(0), rollout.ActiveVersionStats.TotalBuilds)
+
+		// Promoting the version completes the rollout.
+		err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: canary.ID,
+		})
+		require.NoError(t, err)
+		_, err = client.TemplateVersionRollout(ctx, template.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Cohort", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		canary := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, canary.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The Everyone group shares its ID with the organization.
+		everyone := user.OrganizationID
+		rollout, err := client.UpdateTemplateVersionRollout(ctx, template.ID, codersdk.UpdateTemplateVersionRolloutRequest{
+			TemplateVersionID: canary.ID,
+			GroupID:           &everyone,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, rollout.GroupID)
+		require.Equal(t, everyone, *rollout.GroupID)
+
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, canary.ID, workspace.LatestBuild.TemplateVersionID)
+
+		// Once the rollout is deleted, new workspaces build the active version
+		// again.
+		err = client.DeleteTemplateVersionRollout(ctx, template.ID)
+		require.NoError(t, err)
+		workspace = coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, version.ID, workspace.LatestBuild.TemplateVersionID)
+	})
+
+	t.Run("ActiveVersion", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateVersionRollout(ctx, template.ID, codersdk.UpdateTemplateVersionRolloutRequest{
+			TemplateVersionID: version.ID,
+			Percent:           50,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberCannotUpdate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		canary := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, canary.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.UpdateTemplateVersionRollout(ctx, template.ID, codersdk.UpdateTemplateVersionRolloutRequest{
+			TemplateVersionID: canary.ID,
+			Percent:           50,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -28,6 +29,7 @@ import (
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/oci"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
@@ -237,12 +239,14 @@ func (api *API) patchCancelTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	if job.CompletedAt.Valid {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Job has already completed!",
+			Code:    completedJobResponseCode(job),
 		})
 		return
 	}
 	if job.CanceledAt.Valid {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Job has already been marked as canceled!",
+			Code:    codersdk.ResponseCodeJobCanceled,
 		})
 		return
 	}
@@ -265,6 +269,8 @@ func (api *API) patchCancelTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	api.publishProvisionerJobStateChange(ctx, job)
+
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Job has been marked as canceled...",
 	})
@@ -478,6 +484,63 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	api.insertTemplateVersionDryRun(rw, r, provisionerdserver.TemplateVersionDryRunJob{
+		TemplateVersionID:   templateVersion.ID,
+		WorkspaceName:       req.WorkspaceName,
+		RichParameterValues: convertToWorkspaceBuildParameters(req.RichParameterValues),
+	})
+}
+
+// @Summary Create template version test
+// @Description Tests the template version end-to-end: a workspace with
+// @Description synthetic owner data is built and destroyed again. The test is
+// @Description a dry-run job, so its status, logs and resources are fetched
+// @Description with the dry-run endpoints.
+// @ID create-template-version-test
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param request body codersdk.CreateTemplateVersionTestRequest true "Test request"
+// @Success 201 {object} codersdk.ProvisionerJob
+// @Router /templateversions/{templateversion}/test [post]
+func (api *API) postTemplateVersionTest(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var (
+		apiKey          = httpmw.APIKey(r)
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
+	// Tests provision real resources, so the user must be able to create
+	// workspaces, like for dry runs.
+	if !api.Authorize(r, policy.ActionCreate,
+		rbac.ResourceWorkspace.InOrg(templateVersion.OrganizationID).WithOwner(apiKey.UserID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.CreateTemplateVersionTestRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	api.insertTemplateVersionDryRun(rw, r, provisionerdserver.TemplateVersionDryRunJob{
+		TemplateVersionID:   templateVersion.ID,
+		RichParameterValues: convertToWorkspaceBuildParameters(req.RichParameterValues),
+		Test:                true,
+	})
+}
+
+// insertTemplateVersionDryRun creates a dry-run job of the template version
+// from the route with the input, and writes the job to the response.
+func (api *API) insertTemplateVersionDryRun(rw http.ResponseWriter, r *http.Request, input provisionerdserver.TemplateVersionDryRunJob) {
+	ctx := r.Context()
+	var (
+		apiKey          = httpmw.APIKey(r)
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
 	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -493,22 +556,9 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	richParameterValues := make([]database.WorkspaceBuildParameter, len(req.RichParameterValues))
-	for i, v := range req.RichParameterValues {
-		richParameterValues[i] = database.WorkspaceBuildParameter{
-			WorkspaceBuildID: uuid.Nil,
-			Name:             v.Name,
-			Value:            v.Value,
-		}
-	}
-
 	// Marshal template version dry-run job with the parameters from the
 	// request.
-	input, err := json.Marshal(provisionerdserver.TemplateVersionDryRunJob{
-		TemplateVersionID:   templateVersion.ID,
-		WorkspaceName:       req.WorkspaceName,
-		RichParameterValues: richParameterValues,
-	})
+	rawInput, err := json.Marshal(input)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error unmarshalling provisioner job.",
@@ -526,6 +576,10 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if !api.checkProvisionerJobLimit(ctx, rw, apiKey.UserID) {
+		return
+	}
+
 	// Create a dry-run job
 	jobID := uuid.New()
 	provisionerJob, err := api.Database.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
@@ -538,7 +592,7 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		StorageMethod:  job.StorageMethod,
 		FileID:         job.FileID,
 		Type:           database.ProvisionerJobTypeTemplateVersionDryRun,
-		Input:          input,
+		Input:          rawInput,
 		// Copy tags from the previous run.
 		Tags: job.Tags,
 		TraceMetadata: pqtype.NullRawMessage{
@@ -565,6 +619,18 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 	}))
 }
 
+func convertToWorkspaceBuildParameters(values []codersdk.WorkspaceBuildParameter) []database.WorkspaceBuildParameter {
+	parameters := make([]database.WorkspaceBuildParameter, len(values))
+	for i, v := range values {
+		parameters[i] = database.WorkspaceBuildParameter{
+			WorkspaceBuildID: uuid.Nil,
+			Name:             v.Name,
+			Value:            v.Value,
+		}
+	}
+	return parameters
+}
+
 // @Summary Get template version dry-run by job ID
 // @ID get-template-version-dry-run-by-job-id
 // @Security CoderSessionToken
@@ -649,6 +715,10 @@ func (api *API) templateVersionDryRunResources(rw http.ResponseWriter, r *http.R
 // @Param before query int false "Before Unix timestamp"
 // @Param after query int false "After Unix timestamp"
 // @Param follow query bool false "Follow log stream"
+// @Param structured query bool false "Include structured log fields"
+// @Param level query string false "Only return logs at or above this level" enums(trace,debug,info,warn,error)
+// @Param stage query string false "Only return logs of this stage"
+// @Param search query string false "Only return logs whose output contains every word of the search"
 // @Success 200 {array} codersdk.ProvisionerJobLog
 // @Router /templateversions/{templateversion}/dry-run/{jobID}/logs [get]
 func (api *API) templateVersionDryRunLogs(rw http.ResponseWriter, r *http.Request) {
@@ -686,12 +756,14 @@ func (api *API) patchTemplateVersionDryRunCancel(rw http.ResponseWriter, r *http
 	if job.ProvisionerJob.CompletedAt.Valid {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Job has already completed.",
+			Code:    completedJobResponseCode(job.ProvisionerJob),
 		})
 		return
 	}
 	if job.ProvisionerJob.CanceledAt.Valid {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Job has already been marked as canceled.",
+			Code:    codersdk.ResponseCodeJobCanceled,
 		})
 		return
 	}
@@ -715,6 +787,7 @@ func (api *API) patchTemplateVersionDryRunCancel(rw http.ResponseWriter, r *http
 		})
 		return
 	}
+	api.publishProvisionerJobStateChange(ctx, job.ProvisionerJob)
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Job has been marked as canceled.",
@@ -1410,6 +1483,17 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		if err != nil {
 			return xerrors.Errorf("update active version: %w", err)
 		}
+		// Promoting the version being rolled out completes its rollout.
+		rollout, err := store.GetTemplateVersionRolloutByTemplateID(ctx, template.ID)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get template version rollout: %w", err)
+		}
+		if err == nil && rollout.TemplateVersionID == req.ID {
+			err = store.DeleteTemplateVersionRolloutByTemplateID(ctx, template.ID)
+			if err != nil {
+				return xerrors.Errorf("delete template version rollout: %w", err)
+			}
+		}
 		return nil
 	}, nil)
 	if err != nil {
@@ -1422,6 +1506,11 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	newTemplate := template
 	newTemplate.ActiveVersionID = req.ID
 	aReq.New = newTemplate
+	if template.ActiveVersionID != version.ID {
+		if fields := api.templateVersionAuditFields(ctx, template.ActiveVersionID, version); fields != nil {
+			aReq.UpdateAdditionalFields(fields)
+		}
+	}
 
 	api.publishTemplateUpdate(ctx, template.ID)
 
@@ -1464,8 +1553,14 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		return
 	}
 
+	var (
+		requiredProvisionerTags map[string]string
+		// activeVersionID is the active version of the template the version
+		// is created for, if any.
+		activeVersionID uuid.UUID
+	)
 	if req.TemplateID != uuid.Nil {
-		_, err := api.Database.GetTemplateByID(ctx, req.TemplateID)
+		template, err := api.Database.GetTemplateByID(ctx, req.TemplateID)
 		if httpapi.Is404Error(err) {
 			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
 				Message: "Template does not exist.",
@@ -1479,6 +1574,8 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 			})
 			return
 		}
+		requiredProvisionerTags = template.RequiredProvisionerTags
+		activeVersionID = template.ActiveVersionID
 	}
 
 	if req.ExampleID != "" && req.FileID != uuid.Nil {
@@ -1487,6 +1584,12 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		})
 		return
 	}
+	if req.OCIReference != "" && (req.ExampleID != "" || req.FileID != uuid.Nil) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot specify an oci_reference with an example_id or a file_id.",
+		})
+		return
+	}
 
 	var file database.File
 	var err error
@@ -1557,6 +1660,84 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		req.FileID = file.ID
 	}
 
+	// if an OCI reference is specified we need to pull the artifact into a new file in the database
+	if req.OCIReference != "" {
+		if !api.Authorize(r, policy.ActionCreate, rbac.ResourceFile.WithOwner(apiKey.UserID.String())) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		if !api.Authorize(r, policy.ActionRead, rbac.ResourceFile.WithOwner(apiKey.UserID.String())) {
+			httpapi.Forbidden(rw)
+			return
+		}
+
+		ref, err := oci.ParseReference(req.OCIReference)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid OCI reference.",
+				Validations: []codersdk.ValidationError{
+					{Field: "oci_reference", Detail: err.Error()},
+				},
+			})
+			return
+		}
+		client := &oci.Client{HTTPClient: api.HTTPClient, MaxSize: HTTPFileMaxBytes}
+		artifact, err := client.Resolve(ctx, ref)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Failed to resolve OCI artifact %q.", ref.String()),
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		// Artifacts are cached as files. The hash of the tar archive in an
+		// uncompressed layer is known from the manifest, so such layers are
+		// only pulled once.
+		var file database.File
+		hash, cached := artifact.Layer.TarHash()
+		if cached {
+			file, err = api.Database.GetFileByHashAndCreator(ctx, database.GetFileByHashAndCreatorParams{
+				Hash:      hash,
+				CreatedBy: apiKey.UserID,
+			})
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching file.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			cached = err == nil
+		}
+		if !cached {
+			data, err := client.Fetch(ctx, artifact)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: fmt.Sprintf("Failed to pull OCI artifact %q.", ref.String()),
+					Detail:  err.Error(),
+				})
+				return
+			}
+			file, err = api.getOrInsertTarFile(ctx, apiKey.UserID, data)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error creating file.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		}
+		api.Logger.Info(ctx, "pulled template from OCI artifact",
+			slog.F("reference", ref.String()),
+			slog.F("digest", artifact.Digest),
+			slog.F("file_id", file.ID),
+			slog.F("cached", cached),
+		)
+
+		req.FileID = file.ID
+	}
+
 	if req.FileID != uuid.Nil {
 		file, err = api.Database.GetFileByID(ctx, req.FileID)
 		if httpapi.Is404Error(err) {
@@ -1619,6 +1800,20 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 	// User-specified tags in the request will take precedence over tags parsed from `coder_workspace_tags`
 	// data sources defined in the template file.
 	tags := provisionersdk.MutateTags(apiKey.UserID, parsedTags, req.ProvisionerTags)
+	if missing := provisionersdk.MissingTags(requiredProvisionerTags, tags); len(missing) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The template requires its jobs to run on provisioners with specific tags.",
+			Detail:  fmt.Sprintf("Missing provisioner tags: %s", strings.Join(missing, ", ")),
+			Validations: []codersdk.ValidationError{
+				{Field: "provisioner_tags", Detail: "Must include the required provisioner tags of the template."},
+			},
+		})
+		return
+	}
+
+	if !api.checkProvisionerJobLimit(ctx, rw, apiKey.UserID) {
+		return
+	}
 
 	var templateVersion database.TemplateVersion
 	var provisionerJob database.ProvisionerJob
@@ -1765,6 +1960,11 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		return
 	}
 	aReq.New = templateVersion
+	if activeVersionID != uuid.Nil {
+		if fields := api.templateVersionAuditFields(ctx, activeVersionID, templateVersion); fields != nil {
+			aReq.UpdateAdditionalFields(fields)
+		}
+	}
 	err = provisionerjobs.PostJob(api.Pubsub, provisionerJob)
 	if err != nil {
 		// Client probably doesn't care about this error, so just log it.
@@ -1826,6 +2026,10 @@ func (api *API) templateVersionResources(rw http.ResponseWriter, r *http.Request
 // @Param before query int false "Before log id"
 // @Param after query int false "After log id"
 // @Param follow query bool false "Follow log stream"
+// @Param structured query bool false "Include structured log fields"
+// @Param level query string false "Only return logs at or above this level" enums(trace,debug,info,warn,error)
+// @Param stage query string false "Only return logs of this stage"
+// @Param search query string false "Only return logs whose output contains every word of the search"
 // @Success 200 {array} codersdk.ProvisionerJobLog
 // @Router /templateversions/{templateversion}/logs [get]
 func (api *API) templateVersionLogs(rw http.ResponseWriter, r *http.Request) {
@@ -3,8 +3,10 @@ package coderd_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 
@@ -19,8 +21,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/oci"
+	"github.com/coder/coder/v2/coderd/oci/ocitest"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
 	"github.com/coder/coder/v2/provisioner/echo"
@@ -254,6 +259,75 @@ func TestPostTemplateVersionsByOrganization(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("OCIArtifact", func(t *testing.T) {
+		t.Parallel()
+		data, err := echo.Tar(&echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+			ProvisionPlan:  echo.PlanComplete,
+		})
+		require.NoError(t, err)
+		registry := ocitest.New(t, "acme/templates/docker", "v1", oci.MediaTypeTemplateLayer, data, ocitest.WithBearerAuth())
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			HTTPClient:               registry.Client(),
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		version, err := client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			OCIReference:  registry.Reference,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		fl, _, err := client.Download(ctx, version.Job.FileID)
+		require.NoError(t, err)
+		require.Equal(t, data, fl)
+		require.EqualValues(t, 1, registry.BlobRequests.Load())
+
+		// The artifact is cached, so pulling it again by digest reuses the
+		// file.
+		version, err = client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			OCIReference:  registry.Reference + "@" + registry.Digest,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, registry.BlobRequests.Load())
+
+		// A reference and a file cannot both be specified.
+		_, err = client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			OCIReference:  registry.Reference,
+			FileID:        version.Job.FileID,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.ErrorContains(t, err, "oci_reference")
+
+		// Artifacts that are not in the registry fail to resolve.
+		_, err = client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			OCIReference:  strings.Replace(registry.Reference, ":v1", ":v2", 1),
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "Failed to resolve OCI artifact")
+
+		_, err = client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			OCIReference:  "ghcr.io/Acme/Docker",
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, "Invalid OCI reference.", apiErr.Message)
+	})
+
 	t.Run("WorkspaceTags", func(t *testing.T) {
 		t.Parallel()
 		// This test ensures that when creating a template version from an archive continaining a coder_workspace_tags
@@ -1102,6 +1176,69 @@ func TestPatchActiveTemplateVersion(t *testing.T) {
 		require.Len(t, auditor.AuditLogs(), 6)
 		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs()[5].Action)
 	})
+
+	t.Run("AuditFileDiff", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Auditor:                  auditor,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		activeVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ExtraFiles: map[string][]byte{
+				"startup.sh": []byte("a\nb\nc\n"),
+				"removed.sh": []byte("removed\n"),
+			},
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, activeVersion.ID)
+		version := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ExtraFiles: map[string][]byte{
+				"startup.sh": []byte("a\nB\nc\nd\n"),
+				"added.sh":   []byte("added\n"),
+			},
+		}, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		// The echo provisioner writes its responses to the archive, ignore them.
+		requireFileDiff := func(t *testing.T, alog database.AuditLog) {
+			t.Helper()
+			var fields audit.TemplateVersionFields
+			require.NoError(t, json.Unmarshal(alog.AdditionalFields, &fields))
+			require.Equal(t, version.ID, fields.TemplateVersionID)
+			require.Equal(t, activeVersion.ID, fields.BaseTemplateVersionID)
+			fields.Files = slices.DeleteFunc(fields.Files, func(file codersdk.TemplateVersionFileDiff) bool {
+				return strings.HasSuffix(file.Path, ".protobuf")
+			})
+			require.Equal(t, []codersdk.TemplateVersionFileDiff{
+				{Path: "added.sh", Change: codersdk.TemplateVersionDiffChangeAdded, Additions: ptr.Ref(1), Deletions: ptr.Ref(0)},
+				{Path: "removed.sh", Change: codersdk.TemplateVersionDiffChangeRemoved, Additions: ptr.Ref(0), Deletions: ptr.Ref(1)},
+				{Path: "startup.sh", Change: codersdk.TemplateVersionDiffChangeModified, Additions: ptr.Ref(2), Deletions: ptr.Ref(1)},
+			}, fields.Files)
+		}
+
+		created := slices.IndexFunc(auditor.AuditLogs(), func(alog database.AuditLog) bool {
+			return alog.ResourceID == version.ID && alog.Action == database.AuditActionCreate
+		})
+		require.NotEqual(t, -1, created)
+		requireFileDiff(t, auditor.AuditLogs()[created])
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.NoError(t, err)
+		logs := auditor.AuditLogs()
+		promoted := logs[len(logs)-1]
+		require.Equal(t, template.ID, promoted.ResourceID)
+		requireFileDiff(t, promoted)
+	})
 }
 
 func TestTemplateVersionDryRun(t *testing.T) {
@@ -1373,6 +1510,117 @@ func TestTemplateVersionDryRun(t *testing.T) {
 }
 
 // TestPaginatedTemplateVersions creates a list of template versions and paginate.
+func TestTemplateVersionTest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		resource := &proto.Resource{
+			Name: "cool-resource",
+			Type: "cool_resource_type",
+		}
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: []*proto.Resource{resource},
+					},
+				},
+			}},
+		})
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		job, err := client.CreateTemplateVersionTest(ctx, version.ID, codersdk.CreateTemplateVersionTestRequest{})
+		require.NoError(t, err)
+
+		logs, closer, err := client.TemplateVersionDryRunLogsAfter(ctx, version.ID, job.ID, 0)
+		require.NoError(t, err)
+		defer closer.Close()
+		stages := map[string]bool{}
+		for log := range logs {
+			stages[log.Stage] = true
+		}
+		require.True(t, stages["Starting workspace"], "workspace should be started")
+		require.True(t, stages["Destroying workspace"], "workspace should be destroyed")
+
+		job, err = client.TemplateVersionDryRun(ctx, version.ID, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, job.Status)
+
+		resources, err := client.TemplateVersionDryRunResources(ctx, version.ID, job.ID)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		require.Equal(t, resource.Name, resources[0].Name)
+	})
+
+	t.Run("ApplyFailed", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+			ProvisionApplyMap: map[proto.WorkspaceTransition][]*proto.Response{
+				proto.WorkspaceTransition_START: {{
+					Type: &proto.Response_Apply{
+						Apply: &proto.ApplyComplete{
+							Error: "failed to start",
+						},
+					},
+				}},
+			},
+		})
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		job, err := client.CreateTemplateVersionTest(ctx, version.ID, codersdk.CreateTemplateVersionTestRequest{})
+		require.NoError(t, err)
+
+		logs, closer, err := client.TemplateVersionDryRunLogsAfter(ctx, version.ID, job.ID, 0)
+		require.NoError(t, err)
+		defer closer.Close()
+		stages := map[string]bool{}
+		for log := range logs {
+			stages[log.Stage] = true
+		}
+		// Resources that were created before the failure are destroyed.
+		require.True(t, stages["Destroying workspace"], "workspace should be destroyed")
+
+		job, err = client.TemplateVersionDryRun(ctx, version.ID, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobFailed, job.Status)
+		require.Contains(t, job.Error, "failed to start")
+	})
+
+	t.Run("ImportNotFinished", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		// This import job will never finish
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Log{
+					Log: &proto.Log{},
+				},
+			}},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateTemplateVersionTest(ctx, version.ID, codersdk.CreateTemplateVersionTestRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusTooEarly, apiErr.StatusCode())
+	})
+}
+
 func TestPaginatedTemplateVersions(t *testing.T) {
 	t.Parallel()
 
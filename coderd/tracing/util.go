@@ -53,3 +53,16 @@ func MetadataFromContext(ctx context.Context) map[string]string {
 func MetadataToContext(ctx context.Context, metadata map[string]string) context.Context {
 	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(metadata))
 }
+
+// TraceIDFromMetadata returns the ID of the trace propagated in the given
+// metadata, or an empty string if the metadata does not carry a valid trace.
+// The W3C trace context is always used so the ID can be read regardless of the
+// configured propagator.
+func TraceIDFromMetadata(metadata map[string]string) string {
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier(metadata))
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
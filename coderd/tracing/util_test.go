@@ -37,3 +37,29 @@ func TestFuncNameMethodPointer(t *testing.T) {
 	fn := (&foo{}).baz()
 	assert.Equal(t, "tracing_test.(*foo).baz", fn)
 }
+
+func TestTraceIDFromMetadata(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		traceID := tracing.TraceIDFromMetadata(map[string]string{
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		})
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, tracing.TraceIDFromMetadata(map[string]string{}))
+		assert.Empty(t, tracing.TraceIDFromMetadata(nil))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		traceID := tracing.TraceIDFromMetadata(map[string]string{
+			"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		})
+		assert.Empty(t, traceID)
+	})
+}
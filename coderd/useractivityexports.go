@@ -0,0 +1,394 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// userActivityExportsInterval is how often pending user activity exports
+	// are generated. Exports are generated by whichever replica notices them
+	// first.
+	userActivityExportsInterval = 5 * time.Second
+	// userActivityExportMaxRange is the longest time range a single export
+	// may cover.
+	userActivityExportMaxRange = 366 * 24 * time.Hour
+
+	csvMimeType     = "text/csv"
+	parquetMimeType = "application/vnd.apache.parquet"
+)
+
+// @Summary Create user activity export
+// @Description Starts exporting the daily activity of each user in the time
+// @Description range. The export is generated in the background, poll it until
+// @Description it completes and download the file from its download URL.
+// @ID create-user-activity-export
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Insights
+// @Param request body codersdk.CreateUserActivityExportRequest true "Export request"
+// @Success 202 {object} codersdk.UserActivityExport
+// @Router /insights/user-activity/exports [post]
+func (api *API) postUserActivityExport(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	var req codersdk.CreateUserActivityExportRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Format == "" {
+		req.Format = codersdk.UserActivityExportFormatCSV
+	}
+
+	var validationErrors []codersdk.ValidationError
+	switch req.Format {
+	case codersdk.UserActivityExportFormatCSV, codersdk.UserActivityExportFormatParquet:
+	default:
+		validationErrors = append(validationErrors, codersdk.ValidationError{
+			Field:  "format",
+			Detail: fmt.Sprintf("Must be one of %q or %q.", codersdk.UserActivityExportFormatCSV, codersdk.UserActivityExportFormatParquet),
+		})
+	}
+	if !req.EndTime.After(req.StartTime) {
+		validationErrors = append(validationErrors, codersdk.ValidationError{
+			Field:  "end_time",
+			Detail: "Must be after start_time.",
+		})
+	} else if req.EndTime.Sub(req.StartTime) > userActivityExportMaxRange {
+		validationErrors = append(validationErrors, codersdk.ValidationError{
+			Field:  "end_time",
+			Detail: fmt.Sprintf("The time range must not exceed %d days.", int(userActivityExportMaxRange.Hours()/24)),
+		})
+	}
+	if len(validationErrors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid user activity export request.",
+			Validations: validationErrors,
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	export, err := api.Database.InsertUserActivityExport(ctx, database.InsertUserActivityExportParams{
+		ID:        uuid.New(),
+		CreatedBy: apiKey.UserID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Format:    database.UserActivityExportFormat(req.Format),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating user activity export.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, api.convertUserActivityExport(export))
+}
+
+// @Summary Get user activity export
+// @ID get-user-activity-export
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param export path string true "Export ID" format(uuid)
+// @Success 200 {object} codersdk.UserActivityExport
+// @Router /insights/user-activity/exports/{export} [get]
+func (api *API) userActivityExport(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := httpmw.ParseUUIDParam(rw, r, "export")
+	if !ok {
+		return
+	}
+	export, err := api.Database.GetUserActivityExportByID(ctx, id)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user activity export.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertUserActivityExport(export))
+}
+
+func (api *API) runUserActivityExports(ctx context.Context) {
+	defer close(api.userActivityExportsDone)
+	//nolint:gocritic // The system generates exports on behalf of their creators.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	ticker := api.Clock.NewTicker(userActivityExportsInterval, "user_activity_exports")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if api.ReadOnlyMode.Enabled() {
+			continue
+		}
+
+		exports, err := api.Database.GetPendingUserActivityExports(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				api.Logger.Error(ctx, "failed to fetch pending user activity exports", slog.Error(err))
+			}
+			continue
+		}
+		for _, export := range exports {
+			api.generateUserActivityExport(ctx, export)
+		}
+	}
+}
+
+// generateUserActivityExport stores the activity in the time range of the
+// export as a file owned by its creator. Activity is fetched with the
+// permissions of the creator, so an export never contains more than they are
+// allowed to view.
+func (api *API) generateUserActivityExport(ctx context.Context, export database.UserActivityExport) {
+	logger := api.Logger.With(slog.F("user_activity_export_id", export.ID))
+
+	actor, _, err := httpmw.UserRBACSubject(ctx, api.Database, export.CreatedBy, rbac.ScopeAll)
+	if err != nil {
+		logger.Error(ctx, "failed to fetch user activity export creator, will retry", slog.Error(err))
+		return
+	}
+	actorCtx := dbauthz.As(ctx, actor)
+
+	var generated database.UserActivityExport
+	err = api.Database.InTx(func(tx database.Store) error {
+		// Every replica processes pending exports, the lock ensures only one
+		// of them generates each export.
+		ok, err := tx.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("user-activity-export:%s", export.ID)))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		failed := func(reason string) error {
+			generated, err = tx.UpdateUserActivityExportStatusByID(ctx, database.UpdateUserActivityExportStatusByIDParams{
+				ID:          export.ID,
+				Status:      database.UserActivityExportStatusFailed,
+				Error:       reason,
+				UpdatedAt:   dbtime.Now(),
+				CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+			})
+			return err
+		}
+
+		// The export may have been generated since it was fetched.
+		export, err = tx.GetUserActivityExportByID(actorCtx, export.ID)
+		if dbauthz.IsNotAuthorizedError(err) {
+			return failed("The creator of the export is not allowed to view user activity.")
+		}
+		if err != nil {
+			return xerrors.Errorf("get export: %w", err)
+		}
+		if export.Status != database.UserActivityExportStatusPending {
+			return nil
+		}
+
+		rows, err := tx.GetUserDailyActivity(actorCtx, database.GetUserDailyActivityParams{
+			StartTime: export.StartTime,
+			EndTime:   export.EndTime,
+		})
+		if dbauthz.IsNotAuthorizedError(err) {
+			return failed("The creator of the export is not allowed to view user activity.")
+		}
+		if err != nil {
+			return xerrors.Errorf("get user daily activity: %w", err)
+		}
+
+		var (
+			data     []byte
+			mimetype string
+		)
+		switch export.Format {
+		case database.UserActivityExportFormatParquet:
+			data, err = encodeUserActivityParquet(rows)
+			mimetype = parquetMimeType
+		default:
+			data, err = encodeUserActivityCSV(rows)
+			mimetype = csvMimeType
+		}
+		if err != nil {
+			return failed(fmt.Sprintf("Encode %s: %s", export.Format, err))
+		}
+
+		hashBytes := sha256.Sum256(data)
+		hash := hex.EncodeToString(hashBytes[:])
+		file, err := tx.GetFileByHashAndCreator(ctx, database.GetFileByHashAndCreatorParams{
+			Hash:      hash,
+			CreatedBy: export.CreatedBy,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			file, err = tx.InsertFile(ctx, database.InsertFileParams{
+				ID:        uuid.New(),
+				Hash:      hash,
+				CreatedBy: export.CreatedBy,
+				CreatedAt: dbtime.Now(),
+				Mimetype:  mimetype,
+				Data:      data,
+			})
+		}
+		if err != nil {
+			return xerrors.Errorf("store file: %w", err)
+		}
+
+		generated, err = tx.UpdateUserActivityExportStatusByID(ctx, database.UpdateUserActivityExportStatusByIDParams{
+			ID:          export.ID,
+			Status:      database.UserActivityExportStatusCompleted,
+			FileID:      uuid.NullUUID{UUID: file.ID, Valid: true},
+			UpdatedAt:   dbtime.Now(),
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		return err
+	}, nil)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error(ctx, "failed to generate user activity export, will retry", slog.Error(err))
+		}
+		return
+	}
+
+	switch generated.Status {
+	case database.UserActivityExportStatusCompleted:
+		logger.Info(ctx, "generated user activity export", slog.F("file_id", generated.FileID.UUID))
+	case database.UserActivityExportStatusFailed:
+		logger.Warn(ctx, "user activity export failed", slog.F("reason", generated.Error))
+	}
+}
+
+// userActivityExportColumns are the columns of CSV exports, in the same order
+// as the fields of userActivityParquetRow.
+var userActivityExportColumns = []string{
+	"date",
+	"user_id",
+	"username",
+	"connection_hours",
+	"workspace_builds",
+	"apps_used",
+}
+
+func encodeUserActivityCSV(rows []database.GetUserDailyActivityRow) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(userActivityExportColumns); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		err := w.Write([]string{
+			row.Date.UTC().Format(time.DateOnly),
+			row.UserID.String(),
+			row.Username,
+			strconv.FormatFloat(float64(row.UsageSeconds)/3600, 'f', 2, 64),
+			strconv.FormatInt(row.WorkspaceBuilds, 10),
+			strings.Join(row.Apps, ";"),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type userActivityParquetRow struct {
+	// Date is the number of days since the Unix epoch, as required by the
+	// Parquet DATE logical type.
+	Date            int32     `parquet:"date,date"`
+	UserID          uuid.UUID `parquet:"user_id,uuid"`
+	Username        string    `parquet:"username"`
+	ConnectionHours float64   `parquet:"connection_hours"`
+	WorkspaceBuilds int64     `parquet:"workspace_builds"`
+	AppsUsed        []string  `parquet:"apps_used,list"`
+}
+
+func encodeUserActivityParquet(rows []database.GetUserDailyActivityRow) ([]byte, error) {
+	records := make([]userActivityParquetRow, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, userActivityParquetRow{
+			Date:            int32(row.Date.Unix() / int64((24 * time.Hour).Seconds())),
+			UserID:          row.UserID,
+			Username:        row.Username,
+			ConnectionHours: float64(row.UsageSeconds) / 3600,
+			WorkspaceBuilds: row.WorkspaceBuilds,
+			AppsUsed:        row.Apps,
+		})
+	}
+
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[userActivityParquetRow](&buf)
+	if _, err := w.Write(records); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (api *API) convertUserActivityExport(export database.UserActivityExport) codersdk.UserActivityExport {
+	converted := codersdk.UserActivityExport{
+		ID:        export.ID,
+		CreatedBy: export.CreatedBy,
+		CreatedAt: export.CreatedAt,
+		StartTime: export.StartTime,
+		EndTime:   export.EndTime,
+		Format:    codersdk.UserActivityExportFormat(export.Format),
+		Status:    codersdk.UserActivityExportStatus(export.Status),
+		Error:     export.Error,
+	}
+	if export.FileID.Valid {
+		converted.FileID = &export.FileID.UUID
+		converted.DownloadURL = api.AccessURL.JoinPath("/api/v2/files", export.FileID.UUID.String()).String()
+	}
+	if export.CompletedAt.Valid {
+		converted.CompletedAt = &export.CompletedAt.Time
+	}
+	return converted
+}
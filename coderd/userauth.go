@@ -530,12 +530,14 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	boundIdentity, deviceCookie := api.browserAPIKeyBoundIdentity(r)
 	//nolint:gocritic // Creating the API key as the user instead of as system.
 	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, actor), apikey.CreateParams{
 		UserID:          user.ID,
 		LoginType:       database.LoginTypePassword,
 		RemoteAddr:      r.RemoteAddr,
 		DefaultLifetime: api.DeploymentValues.Sessions.DefaultDuration.Value(),
+		BoundIdentity:   boundIdentity,
 	})
 	if err != nil {
 		logger.Error(ctx, "unable to create API key", slog.Error(err))
@@ -549,6 +551,9 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 	aReq.New = *key
 
 	http.SetCookie(rw, cookie)
+	if deviceCookie != nil {
+		http.SetCookie(rw, deviceCookie)
+	}
 
 	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
 		SessionToken: cookie.Value,
@@ -1923,17 +1928,22 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 		// as the user needs to be forced to log back in.
 		key = *oldKey
 	} else {
+		boundIdentity, deviceCookie := api.browserAPIKeyBoundIdentity(r)
 		//nolint:gocritic
 		cookie, newKey, err := api.createAPIKey(dbauthz.AsSystemRestricted(ctx), apikey.CreateParams{
 			UserID:          user.ID,
 			LoginType:       params.LoginType,
 			DefaultLifetime: api.DeploymentValues.Sessions.DefaultDuration.Value(),
 			RemoteAddr:      r.RemoteAddr,
+			BoundIdentity:   boundIdentity,
 		})
 		if err != nil {
 			return nil, database.User{}, database.APIKey{}, xerrors.Errorf("create API key: %w", err)
 		}
 		cookies = append(cookies, cookie)
+		if deviceCookie != nil {
+			cookies = append(cookies, deviceCookie)
+		}
 		key = *newKey
 	}
 
@@ -0,0 +1,314 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace ACL
+// @ID get-workspace-acl
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceACL
+// @Router /workspaces/{workspace}/acl [get]
+func (api *API) workspaceACL(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	// Anyone that can read the workspace can see who it is shared with, even
+	// if they can't read those users and groups otherwise.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	acl := codersdk.WorkspaceACL{
+		Users:  []codersdk.WorkspaceUser{},
+		Groups: []codersdk.WorkspaceGroup{},
+	}
+	userIDs, err := parseWorkspaceACLIDs(workspace.UserACL)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if len(userIDs) > 0 {
+		users, err := api.Database.GetUsersByIDs(sysCtx, userIDs)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		for _, user := range users {
+			if user.Deleted {
+				continue
+			}
+			acl.Users = append(acl.Users, codersdk.WorkspaceUser{
+				MinimalUser: codersdk.MinimalUser{
+					ID:        user.ID,
+					Username:  user.Username,
+					AvatarURL: user.AvatarURL,
+				},
+				Role: convertToWorkspaceRole(workspace.UserACL[user.ID.String()]),
+			})
+		}
+	}
+	groupIDs, err := parseWorkspaceACLIDs(workspace.GroupACL)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if len(groupIDs) > 0 {
+		groups, err := api.Database.GetGroups(sysCtx, database.GetGroupsParams{
+			OrganizationID: workspace.OrganizationID,
+			GroupIds:       groupIDs,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		for _, group := range groups {
+			acl.Groups = append(acl.Groups, codersdk.WorkspaceGroup{
+				ID:          group.Group.ID,
+				Name:        group.Group.Name,
+				DisplayName: group.Group.DisplayName,
+				AvatarURL:   group.Group.AvatarURL,
+				Role:        convertToWorkspaceRole(workspace.GroupACL[group.Group.ID.String()]),
+			})
+		}
+	}
+
+	slices.SortFunc(acl.Users, func(a, b codersdk.WorkspaceUser) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+	slices.SortFunc(acl.Groups, func(a, b codersdk.WorkspaceGroup) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	httpapi.Write(ctx, rw, http.StatusOK, acl)
+}
+
+// @Summary Update workspace ACL
+// @Description Shares a workspace with users and groups of its organization.
+// @Description The "view" role allows reading the workspace, and the "connect"
+// @Description role additionally allows connecting to it over SSH and opening
+// @Description its apps.
+// @ID update-workspace-acl
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceACL true "Update workspace ACL request"
+// @Success 204
+// @Router /workspaces/{workspace}/acl [patch]
+func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+		auditor   = api.Auditor.Load()
+	)
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:          *auditor,
+		Log:            api.Logger,
+		Request:        r,
+		Action:         database.AuditActionWrite,
+		OrganizationID: workspace.OrganizationID,
+	})
+	defer commitAudit()
+	aReq.Old = workspace.WorkspaceTable()
+
+	var req codersdk.UpdateWorkspaceACL
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	validations, err := api.validateWorkspaceACL(ctx, workspace, req)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace ACL.",
+			Validations: validations,
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by ID: %w", err)
+		}
+		userACL := updateWorkspaceACLRoles(workspace.UserACL, req.UserRoles)
+		groupACL := updateWorkspaceACLRoles(workspace.GroupACL, req.GroupRoles)
+		err = tx.UpdateWorkspaceACLByID(ctx, database.UpdateWorkspaceACLByIDParams{
+			ID:       workspace.ID,
+			UserACL:  userACL,
+			GroupACL: groupACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace ACL: %w", err)
+		}
+		workspace.UserACL = userACL
+		workspace.GroupACL = groupACL
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace ACL.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = workspace.WorkspaceTable()
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// validateWorkspaceACL checks that the roles are valid, and that the users and
+// groups exist in the organization of the workspace.
+func (api *API) validateWorkspaceACL(ctx context.Context, workspace database.Workspace, req codersdk.UpdateWorkspaceACL) ([]codersdk.ValidationError, error) {
+	// Validating requires reading users and groups the caller may not be
+	// able to read.
+	// nolint:gocritic
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	var validations []codersdk.ValidationError
+	userIDs := make([]uuid.UUID, 0, len(req.UserRoles))
+	for id, role := range req.UserRoles {
+		if err := validateWorkspaceRole(role); err != nil {
+			validations = append(validations, codersdk.ValidationError{Field: "user_roles", Detail: err.Error()})
+			continue
+		}
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			validations = append(validations, codersdk.ValidationError{Field: "user_roles", Detail: fmt.Sprintf("User ID %q must be a valid UUID.", id)})
+			continue
+		}
+		if userID == workspace.OwnerID {
+			validations = append(validations, codersdk.ValidationError{Field: "user_roles", Detail: "A workspace can't be shared with its owner."})
+			continue
+		}
+		// Removing users that no longer exist must be possible.
+		if role != codersdk.WorkspaceRoleDeleted {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	groupIDs := make([]uuid.UUID, 0, len(req.GroupRoles))
+	for id, role := range req.GroupRoles {
+		if err := validateWorkspaceRole(role); err != nil {
+			validations = append(validations, codersdk.ValidationError{Field: "group_roles", Detail: err.Error()})
+			continue
+		}
+		groupID, err := uuid.Parse(id)
+		if err != nil {
+			validations = append(validations, codersdk.ValidationError{Field: "group_roles", Detail: fmt.Sprintf("Group ID %q must be a valid UUID.", id)})
+			continue
+		}
+		if role != codersdk.WorkspaceRoleDeleted {
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+
+	if len(userIDs) > 0 {
+		rows, err := api.Database.GetOrganizationIDsByMemberIDs(ctx, userIDs)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get organization IDs by member IDs: %w", err)
+		}
+		members := make(map[uuid.UUID]bool, len(rows))
+		for _, row := range rows {
+			members[row.UserID] = slices.Contains(row.OrganizationIDs, workspace.OrganizationID)
+		}
+		for _, userID := range userIDs {
+			if !members[userID] {
+				validations = append(validations, codersdk.ValidationError{Field: "user_roles", Detail: fmt.Sprintf("User %q is not a member of the workspace's organization.", userID)})
+			}
+		}
+	}
+	if len(groupIDs) > 0 {
+		groups, err := api.Database.GetGroups(ctx, database.GetGroupsParams{
+			OrganizationID: workspace.OrganizationID,
+			GroupIds:       groupIDs,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get groups: %w", err)
+		}
+		for _, groupID := range groupIDs {
+			if !slices.ContainsFunc(groups, func(g database.GetGroupsRow) bool {
+				return g.Group.ID == groupID
+			}) {
+				validations = append(validations, codersdk.ValidationError{Field: "group_roles", Detail: fmt.Sprintf("Group %q does not exist in the workspace's organization.", groupID)})
+			}
+		}
+	}
+	return validations, nil
+}
+
+// updateWorkspaceACLRoles returns a copy of the ACL with the roles applied. An
+// empty role removes the ID from the ACL. IDs must already be validated.
+func updateWorkspaceACLRoles(acl database.WorkspaceACL, roles map[string]codersdk.WorkspaceRole) database.WorkspaceACL {
+	updated := maps.Clone(acl)
+	if updated == nil {
+		updated = database.WorkspaceACL{}
+	}
+	for id, role := range roles {
+		// RBAC matches on the canonical form of the ID.
+		id = uuid.MustParse(id).String()
+		if role == codersdk.WorkspaceRoleDeleted {
+			delete(updated, id)
+			continue
+		}
+		updated[id] = db2sdk.WorkspaceRoleActions(role)
+	}
+	return updated
+}
+
+func validateWorkspaceRole(role codersdk.WorkspaceRole) error {
+	actions := db2sdk.WorkspaceRoleActions(role)
+	if len(actions) == 0 && role != codersdk.WorkspaceRoleDeleted {
+		return xerrors.Errorf("role %q is not a valid workspace role", role)
+	}
+	return nil
+}
+
+func convertToWorkspaceRole(actions []policy.Action) codersdk.WorkspaceRole {
+	switch {
+	case slice.SameElements(actions, db2sdk.WorkspaceRoleActions(codersdk.WorkspaceRoleConnect)):
+		return codersdk.WorkspaceRoleConnect
+	case slice.SameElements(actions, db2sdk.WorkspaceRoleActions(codersdk.WorkspaceRoleView)):
+		return codersdk.WorkspaceRoleView
+	}
+	return codersdk.WorkspaceRoleDeleted
+}
+
+func parseWorkspaceACLIDs(acl database.WorkspaceACL) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(acl))
+	for id := range acl {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, xerrors.Errorf("parse ACL ID %q: %w", id, err)
+		}
+		ids = append(ids, parsed)
+	}
+	return ids, nil
+}
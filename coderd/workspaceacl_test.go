@@ -0,0 +1,188 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceACL(t *testing.T) {
+	t.Parallel()
+
+	// workspacePerms returns whether the client can read, SSH into and open
+	// the apps of the workspace.
+	workspacePerms := func(t *testing.T, client *codersdk.Client, workspace codersdk.Workspace) (read, ssh, app bool) {
+		t.Helper()
+		check := func(action codersdk.RBACAction) codersdk.AuthorizationCheck {
+			return codersdk.AuthorizationCheck{
+				Object: codersdk.AuthorizationObject{
+					ResourceType: codersdk.ResourceWorkspace,
+					ResourceID:   workspace.ID.String(),
+				},
+				Action: action,
+			}
+		}
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		res, err := client.AuthCheck(ctx, codersdk.AuthorizationRequest{
+			Checks: map[string]codersdk.AuthorizationCheck{
+				"read": check(codersdk.ActionRead),
+				"ssh":  check(codersdk.ActionSSH),
+				"app":  check(codersdk.ActionApplicationConnect),
+			},
+		})
+		require.NoError(t, err)
+		return res["read"], res["ssh"], res["app"]
+	}
+
+	t.Run("User", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		owner := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		pair, pairUser := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, owner, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, first.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := pair.Workspace(ctx, workspace.ID)
+		require.Error(t, err)
+
+		err = client.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				pairUser.ID.String(): codersdk.WorkspaceRoleView,
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionWrite,
+			ResourceType: database.ResourceTypeWorkspace,
+			ResourceID:   workspace.ID,
+		}))
+
+		acl, err := client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, pairUser.ID, acl.Users[0].ID)
+		require.Equal(t, codersdk.WorkspaceRoleView, acl.Users[0].Role)
+		require.Empty(t, acl.Groups)
+
+		// Viewers can read the workspace, and see it when listing workspaces.
+		_, err = pair.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		workspaces, err := pair.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+		require.Equal(t, workspace.ID, workspaces.Workspaces[0].ID)
+		read, ssh, app := workspacePerms(t, pair, workspace)
+		require.True(t, read)
+		require.False(t, ssh)
+		require.False(t, app)
+
+		err = client.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				pairUser.ID.String(): codersdk.WorkspaceRoleConnect,
+			},
+		})
+		require.NoError(t, err)
+		read, ssh, app = workspacePerms(t, pair, workspace)
+		require.True(t, read)
+		require.True(t, ssh)
+		require.True(t, app)
+
+		// Shared users can't change who the workspace is shared with.
+		err = pair.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				pairUser.ID.String(): codersdk.WorkspaceRoleDeleted,
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = client.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				pairUser.ID.String(): codersdk.WorkspaceRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+		_, err = pair.Workspace(ctx, workspace.ID)
+		require.Error(t, err)
+		acl, err = client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, acl.Users)
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		t.Parallel()
+		owner := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		member, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, owner, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, first.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+
+		// The ID of the 'Everyone' group is the organization ID.
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			GroupRoles: map[string]codersdk.WorkspaceRole{
+				first.OrganizationID.String(): codersdk.WorkspaceRoleConnect,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Groups, 1)
+		require.Equal(t, first.OrganizationID, acl.Groups[0].ID)
+		require.Equal(t, codersdk.WorkspaceRoleConnect, acl.Groups[0].Role)
+
+		read, ssh, app := workspacePerms(t, member, workspace)
+		require.True(t, read)
+		require.True(t, ssh)
+		require.True(t, app)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		owner, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		first := coderdtest.CreateFirstUser(t, owner)
+		client, user := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		otherOrg := dbgen.Organization(t, db, database.Organization{})
+		otherGroup := dbgen.Group(t, db, database.Group{OrganizationID: otherOrg.ID})
+		outsider := dbgen.User(t, db, database.User{})
+		version := coderdtest.CreateTemplateVersion(t, owner, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, first.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+
+		for name, req := range map[string]codersdk.UpdateWorkspaceACL{
+			"Role":          {UserRoles: map[string]codersdk.WorkspaceRole{first.UserID.String(): "admin"}},
+			"ID":            {UserRoles: map[string]codersdk.WorkspaceRole{"alice": codersdk.WorkspaceRoleView}},
+			"Owner":         {UserRoles: map[string]codersdk.WorkspaceRole{user.ID.String(): codersdk.WorkspaceRoleView}},
+			"NotOrgMember":  {UserRoles: map[string]codersdk.WorkspaceRole{outsider.ID.String(): codersdk.WorkspaceRoleView}},
+			"OtherOrgGroup": {GroupRoles: map[string]codersdk.WorkspaceRole{otherGroup.ID.String(): codersdk.WorkspaceRoleView}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				ctx := testutil.Context(t, testutil.WaitMedium)
+				err := client.UpdateWorkspaceACL(ctx, workspace.ID, req)
+				var apiErr *codersdk.Error
+				require.ErrorAs(t, err, &apiErr)
+				require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+			})
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	workspaceAgentExecDefaultTimeout = time.Minute
+	workspaceAgentExecMaxTimeout     = 10 * time.Minute
+	// workspaceAgentExecMaxOutput is the number of bytes retained from each
+	// of stdout and stderr.
+	workspaceAgentExecMaxOutput = 1 << 20
+)
+
+type workspaceAgentExecAuditFields struct {
+	Command  string `json:"command"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+	TimedOut bool   `json:"timed_out,omitempty"`
+}
+
+// @Summary Execute command in workspace agent
+// @ID execute-command-in-workspace-agent
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param request body codersdk.WorkspaceAgentExecRequest true "Exec request"
+// @Success 200 {object} codersdk.WorkspaceAgentExecResponse
+// @Router /workspaceagents/{workspaceagent}/exec [post]
+func (api *API) workspaceAgentExec(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		workspace      = httpmw.WorkspaceParam(r)
+		workspaceAgent = httpmw.WorkspaceAgentParam(r)
+		auditor        = api.Auditor.Load()
+		auditFields    = &workspaceAgentExecAuditFields{}
+	)
+
+	// Running a command is equivalent to opening an SSH session.
+	if !api.Authorize(r, policy.ActionSSH, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	// Non-browser connections may be disabled, e.g. with
+	// `CODER_BROWSER_ONLY`.
+	override := api.WorkspaceClientCoordinateOverride.Load()
+	if override != nil {
+		overrideFunc := *override
+		if overrideFunc != nil && overrideFunc(rw) {
+			return
+		}
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceAgent](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionConnect,
+		OrganizationID:   workspace.OrganizationID,
+		AdditionalFields: auditFields,
+	})
+	defer commitAudit()
+	aReq.Old = workspaceAgent
+	aReq.New = workspaceAgent
+
+	var req codersdk.WorkspaceAgentExecRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	auditFields.Command = req.Command
+
+	timeout := workspaceAgentExecDefaultTimeout
+	if req.TimeoutMillis != 0 {
+		timeout = time.Duration(req.TimeoutMillis) * time.Millisecond
+	}
+	if timeout <= 0 || timeout > workspaceAgentExecMaxTimeout {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid timeout.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "timeout_ms",
+				Detail: fmt.Sprintf("Must be greater than 0 and at most %d.", workspaceAgentExecMaxTimeout.Milliseconds()),
+			}},
+		})
+		return
+	}
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(),
+		*api.TailnetCoordinator.Load(),
+		workspaceAgent,
+		nil,
+		nil,
+		nil,
+		api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	// If the agent is unreachable, the request will hang. Assume that if we
+	// don't get a response after 30s that the agent is unreachable.
+	dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer dialCancel()
+	agentConn, release, err := api.agentProvider.AgentConn(dialCtx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	sshClient, err := agentConn.SSHClient(dialCtx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error connecting to workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer sshClient.Close()
+
+	session, err := sshClient.NewSession()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer session.Close()
+
+	stdout := &truncatingBuffer{limit: workspaceAgentExecMaxOutput}
+	stderr := &truncatingBuffer{limit: workspaceAgentExecMaxOutput}
+	session.Stdout = stdout
+	session.Stderr = stderr
+	if err := session.Start(req.Command); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error starting command.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+
+	timer := api.Clock.NewTimer(timeout, "workspaceAgentExec")
+	defer timer.Stop()
+
+	resp := codersdk.WorkspaceAgentExecResponse{}
+	select {
+	case err = <-done:
+	case <-timer.C:
+		resp.TimedOut = true
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		err = <-done
+	case <-ctx.Done():
+		// The client went away, there is nobody to respond to.
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		<-done
+		return
+	}
+
+	var exitErr *ssh.ExitError
+	switch {
+	case resp.TimedOut:
+		resp.ExitCode = -1
+	case err == nil:
+		resp.ExitCode = 0
+	case errors.As(err, &exitErr):
+		resp.ExitCode = exitErr.ExitStatus()
+	default:
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error running command.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	resp.Stdout = stdout.String()
+	resp.Stderr = stderr.String()
+	auditFields.ExitCode = &resp.ExitCode
+	auditFields.TimedOut = resp.TimedOut
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// truncatingBuffer retains up to limit bytes and silently discards the
+// rest, so a chatty command cannot fail or exhaust memory.
+type truncatingBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *truncatingBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Buffer.Len(); remaining > 0 {
+		if len(p) > remaining {
+			_, _ = b.Buffer.Write(p[:remaining])
+		} else {
+			_, _ = b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,97 @@
+package coderd_test
+
+import (
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentExec(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("commands use a POSIX shell")
+	}
+
+	auditor := audit.NewMock()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        owner.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.WorkspaceAgentExec(ctx, agentID, codersdk.WorkspaceAgentExecRequest{
+			Command: "echo hello; echo oops >&2; exit 3",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, resp.ExitCode)
+		assert.Equal(t, "hello\n", resp.Stdout)
+		assert.Equal(t, "oops\n", resp.Stderr)
+		assert.False(t, resp.TimedOut)
+
+		assert.True(t, auditor.Contains(t, database.AuditLog{
+			ResourceType: database.ResourceTypeWorkspaceAgent,
+			ResourceID:   agentID,
+			Action:       database.AuditActionConnect,
+		}))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.WorkspaceAgentExec(ctx, agentID, codersdk.WorkspaceAgentExecRequest{
+			Command:       "sleep 30",
+			TimeoutMillis: 100,
+		})
+		require.NoError(t, err)
+		assert.True(t, resp.TimedOut)
+		assert.Equal(t, -1, resp.ExitCode)
+	})
+
+	t.Run("InvalidTimeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.WorkspaceAgentExec(ctx, agentID, codersdk.WorkspaceAgentExecRequest{
+			Command:       "true",
+			TimeoutMillis: -1,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NoSSHPermission", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Auditors can read workspaces but cannot connect to them.
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleAuditor())
+		_, err := otherClient.WorkspaceAgentExec(ctx, agentID, codersdk.WorkspaceAgentExecRequest{
+			Command: "true",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -1,14 +1,23 @@
 package coderd
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -62,6 +71,38 @@ func (api *API) postWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Requ
 		})
 		return
 	}
+	restricted := req.ShareLevel == codersdk.WorkspaceAgentPortShareLevelRestricted
+	hasPrincipals := len(req.SharedWithUserIDs) > 0 || len(req.SharedWithGroupIDs) > 0
+	if restricted && !hasPrincipals {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "At least one user or group is required to share a port with the restricted sharing level.",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "shared_with_user_ids",
+					Detail: "At least one user or group is required.",
+				},
+			},
+		})
+		return
+	}
+	if !restricted && hasPrincipals {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Users and groups can only be specified with the restricted sharing level.",
+		})
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(dbtime.Now()) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Port share expiry must be in the future.",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "expires_at",
+					Detail: "Port share expiry must be in the future.",
+				},
+			},
+		})
+		return
+	}
 
 	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
 	if err != nil {
@@ -97,12 +138,35 @@ func (api *API) postWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if restricted {
+		validations, err := validatePortSharePrincipals(ctx, api.Database, workspace.OrganizationID, req.SharedWithUserIDs, req.SharedWithGroupIDs)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if len(validations) > 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid users or groups to share the port with.",
+				Validations: validations,
+			})
+			return
+		}
+	}
+
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: dbtime.Time(*req.ExpiresAt), Valid: true}
+	}
+
 	psl, err := api.Database.UpsertWorkspaceAgentPortShare(ctx, database.UpsertWorkspaceAgentPortShareParams{
-		WorkspaceID: workspace.ID,
-		AgentName:   req.AgentName,
-		Port:        req.Port,
-		ShareLevel:  database.AppSharingLevel(req.ShareLevel),
-		Protocol:    database.PortShareProtocol(req.Protocol),
+		WorkspaceID:        workspace.ID,
+		AgentName:          req.AgentName,
+		Port:               req.Port,
+		ShareLevel:         database.AppSharingLevel(req.ShareLevel),
+		Protocol:           database.PortShareProtocol(req.Protocol),
+		SharedWithUserIDs:  slice.Unique(req.SharedWithUserIDs),
+		SharedWithGroupIDs: slice.Unique(req.SharedWithGroupIDs),
+		ExpiresAt:          expiresAt,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -182,6 +246,52 @@ func (api *API) deleteWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Re
 	rw.WriteHeader(http.StatusOK)
 }
 
+// validatePortSharePrincipals ensures every user is a member of, and every group
+// belongs to, the workspace's organization.
+func validatePortSharePrincipals(ctx context.Context, db database.Store, orgID uuid.UUID, userIDs, groupIDs []uuid.UUID) ([]codersdk.ValidationError, error) {
+	// The user sharing the port may not be allowed to read every member or
+	// group in the organization, but we only need to confirm they exist.
+	//nolint:gocritic // System access is required to validate memberships.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	var validations []codersdk.ValidationError
+	for _, userID := range userIDs {
+		members, err := db.OrganizationMembers(ctx, database.OrganizationMembersParams{
+			OrganizationID: orgID,
+			UserID:         userID,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get organization member: %w", err)
+		}
+		if len(members) == 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "shared_with_user_ids",
+				Detail: fmt.Sprintf("User %q is not a member of the workspace's organization.", userID),
+			})
+		}
+	}
+	for _, groupID := range groupIDs {
+		if groupID == orgID {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "shared_with_group_ids",
+				Detail: "Use the organization sharing level to share with everyone in the organization.",
+			})
+			continue
+		}
+		group, err := db.GetGroupByID(ctx, groupID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get group: %w", err)
+		}
+		if err != nil || group.OrganizationID != orgID {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "shared_with_group_ids",
+				Detail: fmt.Sprintf("Group %q does not belong to the workspace's organization.", groupID),
+			})
+		}
+	}
+	return validations, nil
+}
+
 func convertPortShares(shares []database.WorkspaceAgentPortShare) []codersdk.WorkspaceAgentPortShare {
 	converted := []codersdk.WorkspaceAgentPortShare{}
 	for _, share := range shares {
@@ -194,11 +304,18 @@ func convertPortShares(shares []database.WorkspaceAgentPortShare) []codersdk.Wor
 }
 
 func convertPortShare(share database.WorkspaceAgentPortShare) codersdk.WorkspaceAgentPortShare {
+	var expiresAt *time.Time
+	if share.ExpiresAt.Valid {
+		expiresAt = &share.ExpiresAt.Time
+	}
 	return codersdk.WorkspaceAgentPortShare{
-		WorkspaceID: share.WorkspaceID,
-		AgentName:   share.AgentName,
-		Port:        share.Port,
-		ShareLevel:  codersdk.WorkspaceAgentPortShareLevel(share.ShareLevel),
-		Protocol:    codersdk.WorkspaceAgentPortShareProtocol(share.Protocol),
+		WorkspaceID:        share.WorkspaceID,
+		AgentName:          share.AgentName,
+		Port:               share.Port,
+		ShareLevel:         codersdk.WorkspaceAgentPortShareLevel(share.ShareLevel),
+		Protocol:           codersdk.WorkspaceAgentPortShareProtocol(share.Protocol),
+		SharedWithUserIDs:  append([]uuid.UUID{}, share.SharedWithUserIDs...),
+		SharedWithGroupIDs: append([]uuid.UUID{}, share.SharedWithGroupIDs...),
+		ExpiresAt:          expiresAt,
 	}
 }
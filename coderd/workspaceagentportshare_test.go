@@ -3,13 +3,17 @@ package coderd_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
@@ -131,6 +135,92 @@ func TestPostWorkspaceAgentPortShare(t *testing.T) {
 	require.EqualValues(t, 8081, list.Shares[1].Port)
 }
 
+func TestPostWorkspaceAgentPortShareRestricted(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	ownerClient, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	_, member := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	group := dbgen.Group(t, db, database.Group{OrganizationID: owner.OrganizationID})
+	otherOrg := dbgen.Organization(t, db, database.Organization{})
+	otherUser := dbgen.User(t, db, database.User{})
+	otherGroup := dbgen.Group(t, db, database.Group{OrganizationID: otherOrg.ID})
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(dbauthz.As(ctx, coderdtest.AuthzUserSubject(user, owner.OrganizationID)), r.Workspace.ID)
+	require.NoError(t, err)
+
+	upsert := func(req codersdk.UpsertWorkspaceAgentPortShareRequest) (codersdk.WorkspaceAgentPortShare, error) {
+		req.AgentName = agents[0].Name
+		req.Port = 8080
+		req.Protocol = codersdk.WorkspaceAgentPortShareProtocolHTTP
+		return client.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, req)
+	}
+
+	// restricted without any users or groups should fail
+	_, err = upsert(codersdk.UpsertWorkspaceAgentPortShareRequest{
+		ShareLevel: codersdk.WorkspaceAgentPortShareLevelRestricted,
+	})
+	require.Error(t, err)
+
+	// users with a non-restricted level should fail
+	_, err = upsert(codersdk.UpsertWorkspaceAgentPortShareRequest{
+		ShareLevel:        codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+		SharedWithUserIDs: []uuid.UUID{member.ID},
+	})
+	require.Error(t, err)
+
+	// users and groups outside the organization should fail
+	_, err = upsert(codersdk.UpsertWorkspaceAgentPortShareRequest{
+		ShareLevel:        codersdk.WorkspaceAgentPortShareLevelRestricted,
+		SharedWithUserIDs: []uuid.UUID{otherUser.ID},
+	})
+	require.Error(t, err)
+	_, err = upsert(codersdk.UpsertWorkspaceAgentPortShareRequest{
+		ShareLevel:         codersdk.WorkspaceAgentPortShareLevelRestricted,
+		SharedWithGroupIDs: []uuid.UUID{otherGroup.ID},
+	})
+	require.Error(t, err)
+
+	// expiry in the past should fail
+	past := dbtime.Now().Add(-time.Minute)
+	_, err = upsert(codersdk.UpsertWorkspaceAgentPortShareRequest{
+		ShareLevel:        codersdk.WorkspaceAgentPortShareLevelRestricted,
+		SharedWithUserIDs: []uuid.UUID{member.ID},
+		ExpiresAt:         &past,
+	})
+	require.Error(t, err)
+
+	// OK
+	expiresAt := dbtime.Now().Add(time.Hour)
+	ps, err := upsert(codersdk.UpsertWorkspaceAgentPortShareRequest{
+		ShareLevel:         codersdk.WorkspaceAgentPortShareLevelRestricted,
+		SharedWithUserIDs:  []uuid.UUID{member.ID, member.ID},
+		SharedWithGroupIDs: []uuid.UUID{group.ID},
+		ExpiresAt:          &expiresAt,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, codersdk.WorkspaceAgentPortShareLevelRestricted, ps.ShareLevel)
+	require.Equal(t, []uuid.UUID{member.ID}, ps.SharedWithUserIDs)
+	require.Equal(t, []uuid.UUID{group.ID}, ps.SharedWithGroupIDs)
+	require.NotNil(t, ps.ExpiresAt)
+	require.WithinDuration(t, expiresAt, *ps.ExpiresAt, time.Second)
+
+	// changing to a broader level clears the users, groups and expiry
+	ps, err = upsert(codersdk.UpsertWorkspaceAgentPortShareRequest{
+		ShareLevel: codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+	})
+	require.NoError(t, err)
+	require.Empty(t, ps.SharedWithUserIDs)
+	require.Empty(t, ps.SharedWithGroupIDs)
+	require.Nil(t, ps.ExpiresAt)
+}
+
 func TestGetWorkspaceAgentPortShares(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
@@ -1004,6 +1004,30 @@ func (api *API) workspaceAgentConnection(rw http.ResponseWriter, r *http.Request
 	})
 }
 
+// @Summary Get connection quality for workspace agent
+// @ID get-connection-quality-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceAgentConnectionQuality
+// @Router /workspaceagents/{workspaceagent}/connection-quality [get]
+func (api *API) workspaceAgentConnectionQuality(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	qualities, err := api.Database.GetWorkspaceAgentConnectionQualityByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent connection quality.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(qualities, db2sdk.WorkspaceAgentConnectionQuality))
+}
+
 // workspaceAgentConnectionGeneric is the same as workspaceAgentConnection but
 // without the workspaceagent path parameter.
 //
@@ -1363,6 +1387,7 @@ func convertScripts(dbScripts []database.WorkspaceAgentScript) []codersdk.Worksp
 			Cron:             dbScript.Cron,
 			RunOnStart:       dbScript.RunOnStart,
 			RunOnStop:        dbScript.RunOnStop,
+			RunOnClaim:       dbScript.RunOnClaim,
 			StartBlocksLogin: dbScript.StartBlocksLogin,
 			Timeout:          time.Duration(dbScript.TimeoutSeconds) * time.Second,
 			DisplayName:      dbScript.DisplayName,
@@ -2,6 +2,7 @@ package coderd_test
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"maps"
@@ -2623,7 +2624,7 @@ func requireGetManifest(ctx context.Context, t testing.TB, aAPI agentproto.DRPCA
 }
 
 func postStartup(ctx context.Context, t testing.TB, client agent.Client, startup *agentproto.Startup) error {
-	aAPI, _, err := client.ConnectRPC26(ctx)
+	aAPI, _, err := client.ConnectRPC27(ctx)
 	require.NoError(t, err)
 	defer func() {
 		cErr := aAPI.DRPCConn().Close()
@@ -2804,6 +2805,67 @@ func TestAgentConnectionInfo(t *testing.T) {
 	require.True(t, info.DERPForceWebSockets)
 }
 
+func TestWorkspaceAgentConnectionQuality(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(sysCtx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	agentID := agents[0].ID
+
+	now := dbtime.Now()
+	err = db.UpsertWorkspaceAgentConnectionQuality(sysCtx, database.UpsertWorkspaceAgentConnectionQualityParams{
+		AgentID:         agentID,
+		Ip:              "fd7a:115c:a1e0::1",
+		DerpRegionCode:  "coder",
+		LatencyMS:       sql.NullFloat64{Float64: 42.5, Valid: true},
+		PingsSent:       4,
+		PingsLost:       1,
+		LastHandshakeAt: sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+		UpdatedAt:       now.Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	err = db.UpsertWorkspaceAgentConnectionQuality(sysCtx, database.UpsertWorkspaceAgentConnectionQualityParams{
+		AgentID:   agentID,
+		Ip:        "fd7a:115c:a1e0::2",
+		P2P:       true,
+		PingsSent: 1,
+		UpdatedAt: now,
+	})
+	require.NoError(t, err)
+
+	qualities, err := client.WorkspaceAgentConnectionQuality(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, qualities, 2)
+	// The most recently reported peer comes first.
+	require.Equal(t, "fd7a:115c:a1e0::2", qualities[0].IP)
+	require.True(t, qualities[0].P2P)
+	require.Nil(t, qualities[0].LatencyMS)
+	require.Zero(t, qualities[0].PacketLoss)
+	require.Equal(t, "fd7a:115c:a1e0::1", qualities[1].IP)
+	require.False(t, qualities[1].P2P)
+	require.Equal(t, "coder", qualities[1].DERPRegionCode)
+	require.NotNil(t, qualities[1].LatencyMS)
+	require.Equal(t, 42.5, *qualities[1].LatencyMS)
+	require.Equal(t, 0.25, qualities[1].PacketLoss)
+	require.NotNil(t, qualities[1].LastHandshakeAt)
+
+	// Members can't see the connections to workspaces they can't read.
+	_, err = memberClient.WorkspaceAgentConnectionQuality(ctx, agentID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
 func TestReinit(t *testing.T) {
 	t.Parallel()
 
@@ -3,6 +3,7 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/yamux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -24,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
@@ -122,6 +126,10 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		slog.F("agent_api_version", workspaceAgent.APIVersion),
 		slog.F("agent_resource_id", workspaceAgent.ResourceID))
 
+	if !workspaceAgent.FirstConnectedAt.Valid {
+		api.traceAgentFirstConnect(ctx, logger, build, workspaceAgent)
+	}
+
 	closeCtx, closeCtxCancel := context.WithCancel(ctx)
 	defer closeCtxCancel()
 	monitor := api.startAgentYamuxMonitor(closeCtx, workspace, workspaceAgent, build, mux)
@@ -147,12 +155,15 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		PublishWorkspaceUpdateFn:          api.publishWorkspaceUpdate,
 		PublishWorkspaceAgentLogsUpdateFn: api.publishWorkspaceAgentLogsUpdate,
 		NetworkTelemetryHandler:           api.NetworkTelemetryBatcher.Handler,
+		ReadOnlyMode:                      api.ReadOnlyMode,
 
 		AccessURL:                 api.AccessURL,
 		AppHostname:               api.AppHostname,
+		PathAppsOnly:              api.DeploymentValues.PathAppsOnly.Value(),
 		AgentStatsRefreshInterval: api.AgentStatsRefreshInterval,
 		DisableDirectConnections:  api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		DerpForceWebSockets:       api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
+		SessionRecordingEnabled:   api.DeploymentValues.SessionRecording.Enabled.Value(),
 		DerpMapUpdateFrequency:    api.Options.DERPMapUpdateFrequency,
 		ExternalAuthConfigs:       api.ExternalAuthConfigs,
 		Experiments:               api.Experiments,
@@ -176,6 +187,44 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// traceAgentFirstConnect records a span in the trace of the build that created
+// the agent, covering the time between the build's provisioner job completing
+// and the agent connecting for the first time. Together with the spans of the
+// provisioner job, this lets the whole build lifecycle show up in one trace.
+func (api *API) traceAgentFirstConnect(ctx context.Context, logger slog.Logger, build database.WorkspaceBuild, workspaceAgent database.WorkspaceAgent) {
+	//nolint:gocritic // The agent is not allowed to read the provisioner job of its build.
+	job, err := api.Database.GetProvisionerJobByID(dbauthz.AsSystemRestricted(ctx), build.JobID)
+	if err != nil {
+		logger.Warn(ctx, "failed to fetch provisioner job to trace agent first connect", slog.Error(err))
+		return
+	}
+	jobTraceMetadata := map[string]string{}
+	if job.TraceMetadata.Valid {
+		err := json.Unmarshal(job.TraceMetadata.RawMessage, &jobTraceMetadata)
+		if err != nil {
+			logger.Warn(ctx, "failed to unmarshal provisioner job trace metadata", slog.Error(err))
+			return
+		}
+	}
+	start := workspaceAgent.CreatedAt
+	if job.CompletedAt.Valid {
+		start = job.CompletedAt.Time
+	}
+
+	_, span := api.TracerProvider.Tracer(tracing.TracerName).Start(
+		tracing.MetadataToContext(ctx, jobTraceMetadata), tracing.FuncName(),
+		trace.WithTimestamp(start),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithAttributes(
+			attribute.String("job_id", job.ID.String()),
+			attribute.String("workspace_build_id", build.ID.String()),
+			attribute.String("agent_id", workspaceAgent.ID.String()),
+			attribute.String("agent_name", workspaceAgent.Name),
+		),
+	)
+	span.End()
+}
+
 func (api *API) handleNetworkTelemetry(batch []*tailnetproto.TelemetryEvent) {
 	var (
 		telemetryEvents = make([]telemetry.NetworkEvent, 0, len(batch))
@@ -114,6 +114,7 @@ func (api *API) workspaceApplicationAuth(rw http.ResponseWriter, r *http.Request
 		ExpiresAt:       exp,
 		LifetimeSeconds: lifetimeSeconds,
 		Scope:           database.APIKeyScopeApplicationConnect,
+		BoundIdentity:   httpmw.APIKeyBoundIdentity(codersdk.SessionTokenBinding(api.DeploymentValues.Sessions.TokenBinding), r),
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -125,6 +126,9 @@ func (api *API) workspaceApplicationAuth(rw http.ResponseWriter, r *http.Request
 
 	payload := workspaceapps.EncryptedAPIKeyPayload{
 		APIKey: cookie.Value,
+		// The app domain doesn't receive the device ID cookie of the
+		// dashboard, so it's passed along with the key it's bound to.
+		DeviceID: httpmw.DeviceID(r),
 	}
 	payload.Fill(api.Clock.Now())
 	encryptedAPIKey, err := jwtutils.Encrypt(ctx, api.AppEncryptionKeyCache, payload)
@@ -49,6 +49,7 @@ type DeploymentOptions struct {
 	PrimaryAppHost                       string
 	AppHost                              string
 	DisablePathApps                      bool
+	PathAppsOnly                         bool
 	DisableSubdomainApps                 bool
 	DangerousAllowPathAppSharing         bool
 	DangerousAllowPathAppSiteOwnerAccess bool
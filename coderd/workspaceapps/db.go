@@ -116,6 +116,7 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		OAuth2Configs:               p.OAuth2Configs,
 		RedirectToLogin:             false,
 		DisableSessionExpiryRefresh: p.DeploymentValues.Sessions.DisableExpiryRefresh.Value(),
+		SessionTokenBinding:         codersdk.SessionTokenBinding(p.DeploymentValues.Sessions.TokenBinding),
 		// Optional is true to allow for public apps. If the authorization check
 		// (later on) fails and the user is not authenticated, they will be
 		// redirected to the login page or app auth endpoint using code below.
@@ -131,7 +132,7 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 	aReq.apiKey = apiKey // Update audit request.
 
 	// Lookup workspace app details from DB.
-	dbReq, err := appReq.getDatabase(dangerousSystemCtx, p.Database)
+	dbReq, err := appReq.getDatabase(dangerousSystemCtx, p.Database, p.DeploymentValues.PathAppsOnly.Value())
 	switch {
 	case xerrors.Is(err, sql.ErrNoRows):
 		WriteWorkspaceApp404(p.Logger, p.DashboardURL, rw, r, &appReq, nil, err.Error())
@@ -245,8 +246,13 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		return nil, "", false
 	}
 
+	// Don't let the token outlive the port share that granted access.
+	expiry := time.Now().Add(DefaultTokenExpiry)
+	if !dbReq.AppShareExpiresAt.IsZero() && dbReq.AppShareExpiresAt.Before(expiry) {
+		expiry = dbReq.AppShareExpiresAt
+	}
 	token.RegisteredClaims = jwtutils.RegisteredClaims{
-		Expiry: jwt.NewNumericDate(time.Now().Add(DefaultTokenExpiry)),
+		Expiry: jwt.NewNumericDate(expiry),
 	}
 	// Sign the token.
 	tokenStr, err := jwtutils.Sign(ctx, p.Keycache, token)
@@ -375,6 +381,26 @@ func (p *DBTokenProvider) authorizeRequest(ctx context.Context, roles *rbac.Subj
 		}
 		// User is not a member of the workspace's organization
 		return false, warnings, nil
+	case database.AppSharingLevelRestricted:
+		// Enforce scopes the same way as the organization level.
+		err := p.Authorizer.Authorize(ctx, *roles, rbacAction, rbacResourceOwned)
+		if err != nil {
+			return false, warnings, nil
+		}
+
+		// Check if the user, or any group they belong to, was explicitly
+		// granted access by the port share.
+		if slices.ContainsFunc(dbReq.AppSharedWithUserIDs, func(id uuid.UUID) bool {
+			return id.String() == roles.ID
+		}) {
+			return true, []string{}, nil
+		}
+		for _, groupID := range dbReq.AppSharedWithGroupIDs {
+			if slices.Contains(roles.Groups, groupID.String()) {
+				return true, []string{}, nil
+			}
+		}
+		return false, warnings, nil
 	case database.AppSharingLevelPublic:
 		// We don't really care about scopes and stuff if it's public anyways.
 		// Someone with a restricted-scope API key could just not submit the API
@@ -441,7 +467,8 @@ func (p *DBTokenProvider) auditInitRequest(ctx context.Context, w http.ResponseW
 
 		type additionalFields struct {
 			audit.AdditionalFields
-			SlugOrPort string `json:"slug_or_port,omitempty"`
+			SlugOrPort string                   `json:"slug_or_port,omitempty"`
+			ClientType codersdk.AuditClientType `json:"client_type"`
 		}
 		appInfo := additionalFields{
 			AdditionalFields: audit.AdditionalFields{
@@ -449,6 +476,7 @@ func (p *DBTokenProvider) auditInitRequest(ctx context.Context, w http.ResponseW
 				WorkspaceName:  aReq.dbReq.Workspace.Name,
 				WorkspaceID:    aReq.dbReq.Workspace.ID,
 			},
+			ClientType: userAgentClientType(userAgent),
 		}
 		switch {
 		case aReq.dbReq.AccessMethod == AccessMethodTerminal:
@@ -559,3 +587,12 @@ func (p *DBTokenProvider) auditInitRequest(ctx context.Context, w http.ResponseW
 		}
 	}
 }
+
+// userAgentClientType determines the kind of client that opened an app from
+// its user agent. All browsers identify as "Mozilla/5.0".
+func userAgentClientType(userAgent string) codersdk.AuditClientType {
+	if strings.HasPrefix(userAgent, "Mozilla/") {
+		return codersdk.AuditClientTypeBrowser
+	}
+	return codersdk.AuditClientTypeUnknown
+}
@@ -118,6 +118,7 @@ func Test_ResolveRequest(t *testing.T) {
 	require.NoError(t, err)
 
 	secondUserClient, secondUser := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+	thirdUserClient, _ := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
 
 	agentAuthToken := uuid.NewString()
 	version := coderdtest.CreateTemplateVersion(t, client, firstUser.OrganizationID, &echo.Responses{
@@ -752,6 +753,7 @@ func Test_ResolveRequest(t *testing.T) {
 
 		assertAuditAgent(t, rw, r, auditor, agent, me.ID, map[string]any{
 			"slug_or_port": "9090",
+			"client_type":  string(codersdk.AuditClientTypeUnknown),
 		})
 		require.Len(t, auditor.AuditLogs(), 1, "single audit log")
 	})
@@ -795,6 +797,77 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Len(t, auditor.AuditLogs(), 0, "no audit logs for invalid requests")
 	})
 
+	t.Run("PortSubdomainRestrictedShare", func(t *testing.T) {
+		t.Parallel()
+
+		const port = 9091
+
+		shareExpiresAt := time.Now().Add(30 * time.Second).Truncate(time.Second)
+		_, err := client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:         agentName,
+			Port:              port,
+			ShareLevel:        codersdk.WorkspaceAgentPortShareLevelRestricted,
+			Protocol:          codersdk.WorkspaceAgentPortShareProtocolHTTP,
+			SharedWithUserIDs: []uuid.UUID{secondUser.ID},
+			ExpiresAt:         &shareExpiresAt,
+		})
+		require.NoError(t, err)
+
+		req := (workspaceapps.Request{
+			AccessMethod:      workspaceapps.AccessMethodSubdomain,
+			BasePath:          "/",
+			UsernameOrID:      me.Username,
+			WorkspaceNameOrID: workspace.Name,
+			AgentNameOrID:     agentName,
+			AppSlugOrPort:     fmt.Sprint(port),
+		}).Normalize()
+
+		resolve := func(sessionToken string) (*workspaceapps.SignedToken, bool) {
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set(codersdk.SessionTokenHeader, sessionToken)
+			r.RemoteAddr = testutil.RandomIPv6(t)
+
+			return workspaceappsResolveRequest(t, audit.NewMock(), rw, r, workspaceapps.ResolveRequestOptions{
+				Logger:              api.Logger,
+				SignedTokenProvider: api.WorkspaceAppsProvider,
+				DashboardURL:        api.AccessURL,
+				PathAppBaseURL:      api.AccessURL,
+				AppHostname:         api.AppHostname,
+				AppRequest:          req,
+			})
+		}
+
+		// The user the port was shared with can access it, and the token
+		// does not outlive the share.
+		token, ok := resolve(secondUserClient.SessionToken())
+		require.True(t, ok)
+		require.Equal(t, fmt.Sprintf("http://127.0.0.1:%d", port), token.AppURL)
+		require.WithinDuration(t, shareExpiresAt, token.Expiry.Time(), time.Second)
+
+		// Other users cannot.
+		_, ok = resolve(thirdUserClient.SessionToken())
+		require.False(t, ok)
+
+		// Once the share has expired, nobody but the owner can access it.
+		//nolint:gocritic // Bypassing API validation to expire the share.
+		_, err = api.Database.UpsertWorkspaceAgentPortShare(dbauthz.AsSystemRestricted(ctx), database.UpsertWorkspaceAgentPortShareParams{
+			WorkspaceID:        workspace.ID,
+			AgentName:          agentName,
+			Port:               port,
+			ShareLevel:         database.AppSharingLevelRestricted,
+			Protocol:           database.PortShareProtocolHttp,
+			SharedWithUserIDs:  []uuid.UUID{secondUser.ID},
+			SharedWithGroupIDs: []uuid.UUID{},
+			ExpiresAt:          sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+		})
+		require.NoError(t, err)
+		_, ok = resolve(secondUserClient.SessionToken())
+		require.False(t, ok)
+		_, ok = resolve(client.SessionToken())
+		require.True(t, ok)
+	})
+
 	t.Run("SubdomainEndsInS", func(t *testing.T) {
 		t.Parallel()
 
@@ -845,6 +918,7 @@ func Test_ResolveRequest(t *testing.T) {
 		r := httptest.NewRequest("GET", "/app", nil)
 		r.Header.Set(codersdk.SessionTokenHeader, client.SessionToken())
 		r.RemoteAddr = auditableIP
+		r.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0")
 
 		token, ok := workspaceappsResolveRequest(t, auditor, rw, r, workspaceapps.ResolveRequestOptions{
 			Logger:              api.Logger,
@@ -864,6 +938,7 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Empty(t, token.AppURL)
 		assertAuditAgent(t, rw, r, auditor, agent, me.ID, map[string]any{
 			"slug_or_port": "terminal",
+			"client_type":  string(codersdk.AuditClientTypeBrowser),
 		})
 		require.Len(t, auditor.AuditLogs(), 1, "single audit log")
 	})
@@ -1249,6 +1324,113 @@ func Test_ResolveRequest(t *testing.T) {
 	})
 }
 
+func Test_ResolveRequestTokenBinding(t *testing.T) {
+	t.Parallel()
+
+	const (
+		agentName = "agent"
+		appName   = "app"
+	)
+
+	deploymentValues := coderdtest.DeploymentValues(t)
+	deploymentValues.DisablePathApps = false
+	deploymentValues.Sessions.TokenBinding = string(codersdk.SessionTokenBindingDevice)
+	client, closer, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+		AppHostname:              "*.test.coder.com",
+		DeploymentValues:         deploymentValues,
+		IncludeProvisionerDaemon: true,
+	})
+	t.Cleanup(func() {
+		_ = closer.Close()
+	})
+	// The session of the first user is bound to the laptop.
+	client.HTTPClient = &http.Client{
+		Transport: &codersdk.HeaderTransport{
+			Transport: client.HTTPClient.Transport,
+			Header:    http.Header{codersdk.DeviceIDHeader: []string{"laptop"}},
+		},
+	}
+	firstUser := coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	me, err := client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	agentAuthToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, firstUser.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.PlanComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id:   uuid.NewString(),
+							Name: agentName,
+							Auth: &proto.Agent_Token{
+								Token: agentAuthToken,
+							},
+							Apps: []*proto.App{{
+								Slug:         appName,
+								DisplayName:  appName,
+								SharingLevel: proto.AppSharingLevel_OWNER,
+								Url:          "http://localhost:8080",
+							}},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, firstUser.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	_ = agenttest.New(t, client.URL, agentAuthToken)
+	_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID, agentName)
+
+	resolve := func(deviceID string) (*workspaceapps.SignedToken, *httptest.ResponseRecorder) {
+		req := (workspaceapps.Request{
+			AccessMethod:      workspaceapps.AccessMethodPath,
+			BasePath:          "/app",
+			UsernameOrID:      me.Username,
+			WorkspaceNameOrID: workspace.Name,
+			AgentNameOrID:     agentName,
+			AppSlugOrPort:     appName,
+		}).Normalize()
+		rw := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/app", nil)
+		r.Header.Set(codersdk.SessionTokenHeader, client.SessionToken())
+		if deviceID != "" {
+			r.Header.Set(codersdk.DeviceIDHeader, deviceID)
+		}
+		token, _ := workspaceappsResolveRequest(t, nil, rw, r, workspaceapps.ResolveRequestOptions{
+			Logger:              api.Logger,
+			SignedTokenProvider: api.WorkspaceAppsProvider,
+			DashboardURL:        api.AccessURL,
+			PathAppBaseURL:      api.AccessURL,
+			AppHostname:         api.AppHostname,
+			AppRequest:          req,
+		})
+		return token, rw
+	}
+
+	token, _ := resolve("laptop")
+	require.NotNil(t, token)
+	require.Equal(t, me.ID, token.UserID)
+
+	// Requests with the session token from other devices are treated as
+	// signed out, and redirected to the login page.
+	for _, deviceID := range []string{"desktop", ""} {
+		token, rw := resolve(deviceID)
+		require.Nil(t, token)
+		require.Equal(t, http.StatusSeeOther, rw.Code)
+		require.Contains(t, rw.Header().Get("Location"), "/login")
+	}
+}
+
 func workspaceappsResolveRequest(t testing.TB, auditor audit.Auditor, w http.ResponseWriter, r *http.Request, opts workspaceapps.ResolveRequestOptions) (token *workspaceapps.SignedToken, ok bool) {
 	t.Helper()
 	if opts.SignedTokenProvider != nil && auditor != nil {
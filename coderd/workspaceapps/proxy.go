@@ -238,6 +238,16 @@ func (s *Server) handleAPIKeySmuggling(rw http.ResponseWriter, r *http.Request,
 		MaxAge:   0,
 		HttpOnly: true,
 	}))
+	if payload.DeviceID != "" {
+		http.SetCookie(rw, s.Cookies.Apply(&http.Cookie{
+			Name:     codersdk.DeviceIDCookie,
+			Value:    payload.DeviceID,
+			Domain:   domain,
+			Path:     "/",
+			MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+			HttpOnly: true,
+		}))
+	}
 
 	// Strip the query parameter.
 	path := r.URL.Path
@@ -309,8 +319,10 @@ func (s *Server) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request)
 			Prefix:            "", // Prefix doesn't exist for path apps
 			UsernameOrID:      chi.URLParam(r, "user"),
 			WorkspaceAndAgent: chi.URLParam(r, "workspace_and_agent"),
-			// We don't support port proxying on paths. The ResolveRequest method
-			// won't allow port proxying on path-based apps if the app is a number.
+			// Port proxying on paths is only supported when the deployment
+			// serves all apps on paths. Otherwise, the ResolveRequest method
+			// won't allow port proxying on path-based apps if the app is a
+			// number.
 			AppSlugOrPort: chi.URLParam(r, "workspaceapp"),
 		},
 		AppPath:  chiPath,
@@ -8,12 +8,14 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/xerrors"
 
 	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -43,6 +45,10 @@ type IssueTokenRequest struct {
 	AppQuery string `json:"app_query"`
 	// SessionToken is the session token provided by the user.
 	SessionToken string `json:"session_token"`
+	// DeviceID and ClientCertificate identify the client of the external
+	// proxy, so session tokens bound to them can be checked.
+	DeviceID          string `json:"device_id,omitempty"`
+	ClientCertificate []byte `json:"client_certificate,omitempty"`
 }
 
 // AppBaseURL returns the base URL of this specific app request. An error is
@@ -204,6 +210,13 @@ type databaseRequest struct {
 	// AppSharingLevel is the sharing level of the app. This is forced to be set
 	// to AppSharingLevelOwner if the access method is terminal.
 	AppSharingLevel database.AppSharingLevel
+	// AppSharedWithUserIDs and AppSharedWithGroupIDs are the users and groups
+	// allowed to access a port shared with AppSharingLevelRestricted.
+	AppSharedWithUserIDs  []uuid.UUID
+	AppSharedWithGroupIDs []uuid.UUID
+	// AppShareExpiresAt is when the port share granting access expires. It is
+	// zero if the app is not a shared port or the share never expires.
+	AppShareExpiresAt time.Time
 }
 
 // getDatabase does queries to get the owner user, workspace and agent
@@ -211,9 +224,13 @@ type databaseRequest struct {
 // queries in the correct order based on the access method and what fields are
 // available.
 //
+// Port-based URLs are only allowed for subdomain-based requests unless
+// pathPorts is true, which is the case when the deployment serves all apps on
+// paths.
+//
 // If any of the queries don't return any rows, the error will wrap
 // sql.ErrNoRows. All other errors should be considered internal server errors.
-func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseRequest, error) {
+func (r Request) getDatabase(ctx context.Context, db database.Store, pathPorts bool) (*databaseRequest, error) {
 	// If the AccessMethod is AccessMethodTerminal, then we need to get the
 	// agent first since that's the only info we have.
 	if r.AccessMethod == AccessMethodTerminal {
@@ -289,10 +306,13 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 	// whether the app is a slug or a port and whether there are multiple agents
 	// in the workspace or not.
 	var (
-		agentNameOrID   = r.AgentNameOrID
-		app             database.WorkspaceApp
-		appURL          string
-		appSharingLevel database.AppSharingLevel
+		agentNameOrID         = r.AgentNameOrID
+		app                   database.WorkspaceApp
+		appURL                string
+		appSharingLevel       database.AppSharingLevel
+		appSharedWithUserIDs  []uuid.UUID
+		appSharedWithGroupIDs []uuid.UUID
+		appShareExpiresAt     time.Time
 		// First check if it's a port-based URL with an optional "s" suffix for HTTPS.
 		potentialPortStr      = strings.TrimSuffix(r.AppSlugOrPort, "s")
 		portUint, portUintErr = strconv.ParseUint(potentialPortStr, 10, 16)
@@ -304,7 +324,7 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 			protocol = "https"
 		}
 
-		if r.AccessMethod != AccessMethodSubdomain {
+		if r.AccessMethod != AccessMethodSubdomain && !(pathPorts && r.AccessMethod == AccessMethodPath) {
 			// TODO(@deansheather): this should return a 400 instead of a 500.
 			return nil, xerrors.New("port-based URLs are only supported for subdomain-based applications")
 		}
@@ -322,7 +342,8 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		// If the app slug is a port number, then route to the port as an
 		// "anonymous app".
 		//
-		// This is only supported for subdomain-based applications.
+		// This is only supported for subdomain-based applications, or
+		// path-based applications when path apps only mode is enabled.
 		appURL = fmt.Sprintf("%s://127.0.0.1:%d", protocol, portUint)
 		appSharingLevel = database.AppSharingLevelOwner
 
@@ -352,8 +373,14 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 				return nil, xerrors.Errorf("get workspace agent port share: %w", err)
 			}
 			// No port share found, so we keep default to owner.
-		} else {
+		} else if !ps.ExpiresAt.Valid || ps.ExpiresAt.Time.After(dbtime.Now()) {
+			// Expired port shares are ignored, keeping the default of owner.
 			appSharingLevel = ps.ShareLevel
+			appSharedWithUserIDs = ps.SharedWithUserIDs
+			appSharedWithGroupIDs = ps.SharedWithGroupIDs
+			if ps.ExpiresAt.Valid {
+				appShareExpiresAt = ps.ExpiresAt.Time
+			}
 		}
 	} else {
 		for _, a := range apps {
@@ -410,13 +437,16 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 	}
 
 	return &databaseRequest{
-		Request:         r,
-		User:            user,
-		Workspace:       workspace,
-		Agent:           agent,
-		App:             app,
-		AppURL:          appURLParsed,
-		AppSharingLevel: appSharingLevel,
+		Request:               r,
+		User:                  user,
+		Workspace:             workspace,
+		Agent:                 agent,
+		App:                   app,
+		AppURL:                appURLParsed,
+		AppSharingLevel:       appSharingLevel,
+		AppSharedWithUserIDs:  appSharedWithUserIDs,
+		AppSharedWithGroupIDs: appSharedWithGroupIDs,
+		AppShareExpiresAt:     appShareExpiresAt,
 	}, nil
 }
 
@@ -52,6 +52,9 @@ func (t SignedToken) MatchesRequest(req Request) bool {
 type EncryptedAPIKeyPayload struct {
 	jwtutils.RegisteredClaims
 	APIKey string `json:"api_key"`
+	// DeviceID is the device ID of the browser the API key is bound to, if
+	// any.
+	DeviceID string `json:"device_id,omitempty"`
 }
 
 func (e *EncryptedAPIKeyPayload) Fill(now time.Time) {
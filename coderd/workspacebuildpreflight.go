@@ -0,0 +1,196 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// workspaceBuildPreflightChecks returns the checks run before inserting the
+// provisioner job of a workspace build requested by a user.
+func (api *API) workspaceBuildPreflightChecks() []wsbuilder.PreflightCheck {
+	checks := []wsbuilder.PreflightCheck{
+		deprecatedTemplatePreflightCheck{accessControlStore: api.AccessControlStore},
+		workspaceMigrationPreflightCheck{},
+	}
+	// Quotas are only enforced when a quota committer is registered.
+	if api.QuotaCommitter.Load() != nil {
+		checks = append(checks, quotaPreflightCheck{})
+	}
+	return append(checks, api.Options.WorkspaceBuildPreflightChecks...)
+}
+
+// deprecatedTemplatePreflightCheck prevents new workspaces from being created
+// from deprecated templates. Existing workspaces can still be built.
+type deprecatedTemplatePreflightCheck struct {
+	accessControlStore *atomic.Pointer[dbauthz.AccessControlStore]
+}
+
+func (deprecatedTemplatePreflightCheck) Name() string {
+	return "template_deprecation"
+}
+
+func (c deprecatedTemplatePreflightCheck) Check(_ context.Context, req wsbuilder.PreflightRequest) ([]wsbuilder.PreflightFailure, error) {
+	if req.Transition != database.WorkspaceTransitionStart {
+		return nil, nil
+	}
+	// Claiming a prebuilt workspace creates a new workspace for the user.
+	newWorkspace := req.LastBuild == nil || req.PrebuiltWorkspaceBuildStage == sdkproto.PrebuiltWorkspaceBuildStage_CLAIM
+	if !newWorkspace {
+		return nil, nil
+	}
+
+	accessControl := (*c.accessControlStore.Load()).GetTemplateAccessControl(req.Template)
+	if !accessControl.IsDeprecated() {
+		return nil, nil
+	}
+	// Pass the deprecated message to the user.
+	msg := fmt.Sprintf("Template %q has been deprecated, and cannot be used to create a new workspace. %s", req.Template.Name, accessControl.Deprecated)
+	return []wsbuilder.PreflightFailure{{
+		Field:   "template_id",
+		Code:    codersdk.ResponseCodeTemplateDeprecated,
+		Message: strings.TrimSpace(msg),
+	}}, nil
+}
+
+// quotaPreflightCheck rejects starting a workspace that would exceed the
+// owner's quota, or once the owner's monthly budget is spent. The cost of the build is estimated from the resources of the
+// template version import, so it mirrors the check made by the quota committer
+// once the real cost is known.
+type quotaPreflightCheck struct{}
+
+func (quotaPreflightCheck) Name() string {
+	return "quota"
+}
+
+func (quotaPreflightCheck) Check(ctx context.Context, req wsbuilder.PreflightRequest) ([]wsbuilder.PreflightFailure, error) {
+	if req.Transition != database.WorkspaceTransitionStart {
+		return nil, nil
+	}
+
+	// nolint:gocritic // Users are not necessarily allowed to read the quota
+	// of the workspace owner, but they must be told when it is exceeded.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	dailyCost, err := templateVersionDailyCost(ctx, req.Store, req.TemplateVersion.JobID)
+	if err != nil {
+		return nil, err
+	}
+	// If the new build will reduce overall quota consumption, then it is
+	// allowed even if the owner is over quota.
+	if req.LastBuild != nil && dailyCost < req.LastBuild.DailyCost {
+		return nil, nil
+	}
+
+	consumed, err := req.Store.GetQuotaConsumedForUser(ctx, database.GetQuotaConsumedForUserParams{
+		OwnerID:        req.Workspace.OwnerID,
+		OrganizationID: req.Workspace.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	budget, err := req.Store.GetQuotaAllowanceForUser(ctx, database.GetQuotaAllowanceForUserParams{
+		UserID:         req.Workspace.OwnerID,
+		OrganizationID: req.Workspace.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The new build replaces the last build of the workspace, so its cost
+	// no longer counts.
+	if req.LastBuild != nil {
+		consumed -= int64(req.LastBuild.DailyCost)
+	}
+	if consumed+int64(dailyCost) > budget {
+		return []wsbuilder.PreflightFailure{{
+			Code:    codersdk.ResponseCodeQuotaExceeded,
+			Message: fmt.Sprintf("Insufficient quota: starting this workspace is estimated to cost %d credits per day, and %d of %d credits are already in use.", dailyCost, consumed, budget),
+		}}, nil
+	}
+	if dailyCost == 0 {
+		return nil, nil
+	}
+
+	monthlyBudget, err := req.Store.GetQuotaMonthlyBudgetForUser(ctx, database.GetQuotaMonthlyBudgetForUserParams{
+		UserID:         req.Workspace.OwnerID,
+		OrganizationID: req.Workspace.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if monthlyBudget <= 0 {
+		return nil, nil
+	}
+	monthStart := dbtime.StartOfMonth(dbtime.Now())
+	monthlySpent, err := req.Store.GetQuotaMonthlySpendForUser(ctx, database.GetQuotaMonthlySpendForUserParams{
+		OwnerID:        req.Workspace.OwnerID,
+		OrganizationID: req.Workspace.OrganizationID,
+		StartTime:      monthStart,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if monthlySpent < float64(monthlyBudget) {
+		return nil, nil
+	}
+	return []wsbuilder.PreflightFailure{{
+		Code:    codersdk.ResponseCodeQuotaExceeded,
+		Message: fmt.Sprintf("Insufficient monthly budget: %.2f of %d credits have been spent this month. The budget resets on %s.", monthlySpent, monthlyBudget, monthStart.AddDate(0, 1, 0).Format(time.DateOnly)),
+	}}, nil
+}
+
+// workspaceMigrationPreflightCheck rejects builds of workspaces that are
+// being migrated to another deployment, since the provisioner state would
+// change after it was transferred. The builds of the migration itself skip
+// pre-flight checks.
+type workspaceMigrationPreflightCheck struct{}
+
+func (workspaceMigrationPreflightCheck) Name() string {
+	return "workspace_migration"
+}
+
+func (workspaceMigrationPreflightCheck) Check(ctx context.Context, req wsbuilder.PreflightRequest) ([]wsbuilder.PreflightFailure, error) {
+	if req.LastBuild == nil {
+		return nil, nil
+	}
+	// nolint:gocritic // Users are not necessarily allowed to read the
+	// migrations of the workspace, but they must be told why the build fails.
+	migrations, err := req.Store.GetWorkspaceMigrationsByWorkspaceID(dbauthz.AsSystemRestricted(ctx), req.Workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 || migrations[0].CompletedAt.Valid {
+		return nil, nil
+	}
+	return []wsbuilder.PreflightFailure{{
+		Message: "The workspace is being migrated to another deployment.",
+	}}, nil
+}
+
+// templateVersionDailyCost returns the daily cost of the resources a workspace
+// gets when it is started, as planned when the template version was imported.
+func templateVersionDailyCost(ctx context.Context, db database.Store, importJobID uuid.UUID) (int32, error) {
+	resources, err := db.GetWorkspaceResourcesByJobID(ctx, importJobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	var dailyCost int32
+	for _, resource := range resources {
+		if resource.Transition == database.WorkspaceTransitionStart {
+			dailyCost += resource.DailyCost
+		}
+	}
+	return dailyCost, nil
+}
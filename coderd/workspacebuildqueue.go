@@ -0,0 +1,344 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/dynamicparameters"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// workspaceBuildQueueInterval is how often queued builds are checked for
+// whether their workspace is idle. Builds are started by whichever replica
+// notices first, so builds completed on any replica or failed by the job
+// reaper are picked up.
+const workspaceBuildQueueInterval = 5 * time.Second
+
+// @Summary Get workspace build queue
+// @Description Returns the builds queued while another build of the workspace
+// @Description was active, oldest first.
+// @ID get-workspace-build-queue
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.QueuedWorkspaceBuild
+// @Router /workspaces/{workspace}/builds/queue [get]
+func (api *API) workspaceBuildQueue(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	entries, err := api.Database.GetWorkspaceBuildQueueByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build queue.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertQueuedWorkspaceBuilds(entries))
+}
+
+// @Summary Cancel queued workspace build
+// @ID cancel-queued-workspace-build
+// @Security CoderSessionToken
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param queuedbuild path string true "Queued build ID" format(uuid)
+// @Success 204
+// @Router /workspaces/{workspace}/builds/queue/{queuedbuild} [delete]
+func (api *API) deleteQueuedWorkspaceBuild(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	id, ok := httpmw.ParseUUIDParam(rw, r, "queuedbuild")
+	if !ok {
+		return
+	}
+	entry, err := api.Database.GetWorkspaceBuildQueueEntryByID(ctx, id)
+	if httpapi.Is404Error(err) || (err == nil && entry.WorkspaceID != workspace.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching queued workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.DeleteWorkspaceBuildQueueEntry(ctx, entry.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error canceling queued workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// queueWorkspaceBuild queues the requested build if another build of the
+// workspace is active or builds are already queued for it. It returns nil if
+// the build can start right away.
+func queueWorkspaceBuild(ctx context.Context, tx database.Store, workspace database.Workspace, initiator uuid.UUID, lastBuild database.WorkspaceBuild, req codersdk.CreateWorkspaceBuildRequest) (*codersdk.QueuedWorkspaceBuild, error) {
+	entries, err := tx.GetWorkspaceBuildQueueByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		return nil, wsbuilder.BuildError{Status: http.StatusInternalServerError, Message: "failed to fetch workspace build queue", Wrapped: err}
+	}
+	pending := 0
+	for _, entry := range entries {
+		if !entry.FailedAt.Valid {
+			pending++
+		}
+	}
+	// Builds queued earlier go first, even if the workspace is idle until the
+	// queue is processed.
+	if pending == 0 {
+		if lastBuild.ID == uuid.Nil {
+			return nil, nil
+		}
+		job, err := tx.GetProvisionerJobByID(ctx, lastBuild.JobID)
+		if err != nil {
+			return nil, wsbuilder.BuildError{Status: http.StatusInternalServerError, Message: "failed to fetch prior build job", Wrapped: err}
+		}
+		if !codersdk.ProvisionerJobStatus(job.JobStatus).Active() {
+			return nil, nil
+		}
+	}
+
+	richParameterValues, err := json.Marshal(req.RichParameterValues)
+	if err != nil {
+		return nil, wsbuilder.BuildError{Status: http.StatusInternalServerError, Message: "failed to marshal parameter values", Wrapped: err}
+	}
+	entry, err := tx.InsertWorkspaceBuildQueueEntry(ctx, database.InsertWorkspaceBuildQueueEntryParams{
+		ID:                      uuid.New(),
+		WorkspaceID:             workspace.ID,
+		InitiatorID:             initiator,
+		Transition:              database.WorkspaceTransition(req.Transition),
+		TemplateVersionID:       uuid.NullUUID{UUID: req.TemplateVersionID, Valid: req.TemplateVersionID != uuid.Nil},
+		TemplateVersionPresetID: uuid.NullUUID{UUID: req.TemplateVersionPresetID, Valid: req.TemplateVersionPresetID != uuid.Nil},
+		RichParameterValues:     richParameterValues,
+		LogLevel:                string(req.LogLevel),
+		CreatedAt:               dbtime.Now(),
+	})
+	if err != nil {
+		return nil, wsbuilder.BuildError{Status: http.StatusInternalServerError, Message: "failed to queue workspace build", Wrapped: err}
+	}
+	queued := convertQueuedWorkspaceBuild(entry, pending+1)
+	return &queued, nil
+}
+
+// runWorkspaceBuildQueue starts queued workspace builds once no build of their
+// workspace is active, until ctx is canceled.
+func (api *API) runWorkspaceBuildQueue(ctx context.Context) {
+	defer close(api.workspaceBuildQueueDone)
+	//nolint:gocritic // The system starts queued builds on behalf of their initiators.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	ticker := api.Clock.NewTicker(workspaceBuildQueueInterval, "workspace_build_queue")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if api.ReadOnlyMode.Enabled() {
+			continue
+		}
+
+		entries, err := api.Database.GetStartableWorkspaceBuildQueueEntries(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				api.Logger.Error(ctx, "failed to fetch startable queued workspace builds", slog.Error(err))
+			}
+			continue
+		}
+		for _, entry := range entries {
+			api.startQueuedWorkspaceBuild(ctx, entry)
+		}
+	}
+}
+
+func (api *API) startQueuedWorkspaceBuild(ctx context.Context, entry database.WorkspaceBuildQueue) {
+	logger := api.Logger.With(slog.F("workspace_id", entry.WorkspaceID), slog.F("queued_build_id", entry.ID))
+
+	// The build is authorized again with the permissions the initiator has
+	// now, since they may have changed while the build was queued.
+	initiator, _, err := httpmw.UserRBACSubject(ctx, api.Database, entry.InitiatorID, rbac.ScopeAll)
+	if err != nil {
+		logger.Error(ctx, "failed to fetch queued workspace build initiator, will retry", slog.Error(err))
+		return
+	}
+	authFunc := func(action policy.Action, object rbac.Objecter) bool {
+		return api.Authorizer.Authorize(ctx, initiator, action, object.RBACObject()) == nil
+	}
+
+	var (
+		workspace database.Workspace
+		build     *database.WorkspaceBuild
+		job       *database.ProvisionerJob
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		// Every replica processes the queue, the lock ensures only one of them
+		// starts the build.
+		ok, err := tx.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("workspace-build-queue:%s", entry.WorkspaceID)))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+		// The build may have been started or canceled since it was fetched.
+		entry, err = tx.GetWorkspaceBuildQueueEntryByID(ctx, entry.ID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("get queued build: %w", err)
+		}
+		if entry.FailedAt.Valid {
+			return nil
+		}
+		workspace, err = tx.GetWorkspaceByID(ctx, entry.WorkspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+
+		var richParameterValues []codersdk.WorkspaceBuildParameter
+		if err := json.Unmarshal(entry.RichParameterValues, &richParameterValues); err != nil {
+			return xerrors.Errorf("unmarshal parameter values: %w", err)
+		}
+		builder := wsbuilder.New(workspace, entry.Transition).
+			Initiator(entry.InitiatorID).
+			InitiatorContext(database.BuildInitiatorContext{
+				Automation: string(codersdk.BuildAutomationBuildQueue),
+			}).
+			RichParameterValues(richParameterValues).
+			LogLevel(entry.LogLevel).
+			DeploymentValues(api.Options.DeploymentValues).
+			Experiments(api.Experiments).
+			TemplateVersionPresetID(entry.TemplateVersionPresetID.UUID).
+			PreflightChecks(api.workspaceBuildPreflightChecks()...)
+		if entry.TemplateVersionID.Valid {
+			builder = builder.VersionID(entry.TemplateVersionID.UUID)
+		}
+		build, job, _, err = builder.Build(ctx, tx, api.FileCache, authFunc, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+		if err != nil {
+			return err
+		}
+		return tx.DeleteWorkspaceBuildQueueEntry(ctx, entry.ID)
+	}, nil)
+	if err != nil {
+		var (
+			buildErr     wsbuilder.BuildError
+			parameterErr *dynamicparameters.ResolverError
+			reason       string
+		)
+		switch {
+		case xerrors.Is(err, wsbuilder.ErrBuildActive):
+			// Another build was started in the meantime, try again once it
+			// completes.
+			return
+		case errors.As(err, &buildErr) && buildErr.Status < http.StatusInternalServerError:
+			reason = buildErr.Message
+		case errors.As(err, &parameterErr):
+			reason = parameterErr.Error()
+		default:
+			logger.Error(ctx, "failed to start queued workspace build, will retry", slog.Error(err))
+			return
+		}
+
+		logger.Warn(ctx, "queued workspace build cannot be started", slog.F("reason", reason), slog.Error(err))
+		err = api.Database.UpdateWorkspaceBuildQueueEntryFailed(ctx, database.UpdateWorkspaceBuildQueueEntryFailedParams{
+			ID:       entry.ID,
+			FailedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+			Error:    reason,
+		})
+		if err != nil {
+			logger.Error(ctx, "failed to mark queued workspace build as failed", slog.Error(err))
+		}
+		return
+	}
+	if build == nil {
+		return
+	}
+
+	logger.Info(ctx, "started queued workspace build", slog.F("workspace_build_id", build.ID))
+	if err := provisionerjobs.PostJob(api.Pubsub, *job); err != nil {
+		logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+}
+
+func convertQueuedWorkspaceBuilds(entries []database.WorkspaceBuildQueue) []codersdk.QueuedWorkspaceBuild {
+	queued := make([]codersdk.QueuedWorkspaceBuild, 0, len(entries))
+	position := 0
+	for _, entry := range entries {
+		if !entry.FailedAt.Valid {
+			position++
+			queued = append(queued, convertQueuedWorkspaceBuild(entry, position))
+			continue
+		}
+		queued = append(queued, convertQueuedWorkspaceBuild(entry, 0))
+	}
+	return queued
+}
+
+func convertQueuedWorkspaceBuild(entry database.WorkspaceBuildQueue, position int) codersdk.QueuedWorkspaceBuild {
+	queued := codersdk.QueuedWorkspaceBuild{
+		ID:          entry.ID,
+		WorkspaceID: entry.WorkspaceID,
+		InitiatorID: entry.InitiatorID,
+		Transition:  codersdk.WorkspaceTransition(entry.Transition),
+		Status:      codersdk.QueuedWorkspaceBuildStatusPending,
+		Position:    position,
+		Error:       entry.Error,
+		CreatedAt:   entry.CreatedAt,
+	}
+	if entry.TemplateVersionID.Valid {
+		queued.TemplateVersionID = &entry.TemplateVersionID.UUID
+	}
+	if entry.FailedAt.Valid {
+		queued.Status = codersdk.QueuedWorkspaceBuildStatusFailed
+		queued.FailedAt = &entry.FailedAt.Time
+	}
+	return queued
+}
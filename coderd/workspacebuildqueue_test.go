@@ -0,0 +1,236 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceBuildQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StartsWhenActiveBuildCompletes", func(t *testing.T) {
+		t.Parallel()
+		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		// Without a provisioner, builds stay pending.
+		_ = closeDaemon.Close()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		active, queued, err := client.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, active, "no build is active, so the build starts right away")
+		require.Nil(t, queued)
+
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		build, queued, err := client.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		require.Nil(t, build)
+		require.NotNil(t, queued)
+		require.Equal(t, codersdk.QueuedWorkspaceBuildStatusPending, queued.Status)
+		require.Equal(t, 1, queued.Position)
+
+		_, second, err := client.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, second)
+		require.Equal(t, 2, second.Position)
+
+		queue, err := client.WorkspaceBuildQueue(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, queue, 2)
+		require.Equal(t, queued.ID, queue[0].ID)
+		require.Equal(t, second.ID, queue[1].ID)
+
+		err = client.CancelQueuedWorkspaceBuild(ctx, workspace.ID, second.ID)
+		require.NoError(t, err)
+		queue, err = client.WorkspaceBuildQueue(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, queue, 1)
+
+		// Canceling the active build lets the queued build start.
+		err = client.CancelWorkspaceBuild(ctx, active.ID)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			queue, err := client.WorkspaceBuildQueue(ctx, workspace.ID)
+			return err == nil && len(queue) == 0
+		}, testutil.WaitLong, testutil.IntervalMedium)
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, active.BuildNumber+1, workspace.LatestBuild.BuildNumber)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+		require.Equal(t, user.UserID, workspace.LatestBuild.InitiatorID)
+		require.Equal(t, codersdk.BuildAutomationBuildQueue, workspace.LatestBuild.InitiatorContext.Automation)
+	})
+
+	t.Run("FailsWhenBuildCannotStart", func(t *testing.T) {
+		t.Parallel()
+		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		// Versions that failed to import cannot be built, which is only found
+		// out when the queued build starts.
+		failed := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:         echo.ParseComplete,
+			ProvisionPlan: echo.PlanFailed,
+		}, func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, failed.ID)
+		_ = closeDaemon.Close()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		active, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		_, queued, err := client.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:        codersdk.WorkspaceTransitionStart,
+			TemplateVersionID: failed.ID,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, queued)
+
+		err = client.CancelWorkspaceBuild(ctx, active.ID)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			queue, err := client.WorkspaceBuildQueue(ctx, workspace.ID)
+			return err == nil && len(queue) == 1 && queue[0].Status == codersdk.QueuedWorkspaceBuildStatusFailed
+		}, testutil.WaitLong, testutil.IntervalMedium)
+
+		queue, err := client.WorkspaceBuildQueue(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, queue[0].Position)
+		require.NotEmpty(t, queue[0].Error)
+	})
+
+	t.Run("MemberCannotQueuePrivilegedBuild", func(t *testing.T) {
+		t.Parallel()
+		deploymentValues := coderdtest.DeploymentValues(t)
+		deploymentValues.EnableTerraformDebugMode = true
+		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			DeploymentValues:         deploymentValues,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+		_ = closeDaemon.Close()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+
+		// The queued build would start as the member, without the permissions
+		// checked when builds start right away.
+		_, _, err = member.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+			LogLevel:   codersdk.ProvisionerLogLevelDebug,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "restricted to administrators")
+
+		_, _, err = member.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:       codersdk.WorkspaceTransitionStart,
+			ProvisionerState: []byte("{}"),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		queue, err := member.WorkspaceBuildQueue(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, queue)
+
+		// Administrators may queue builds with a custom log level.
+		_, queued, err := client.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+			LogLevel:   codersdk.ProvisionerLogLevelDebug,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, queued)
+	})
+
+	t.Run("QueueWithOrphan", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, _, err := client.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+			Orphan:     true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("QueueWithParameterDefaults", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, _, err := client.QueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:           codersdk.WorkspaceTransitionStart,
+			UseParameterDefaults: true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"cdr.dev/slog"
 
 	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/buildarchive"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -33,6 +34,7 @@ import (
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
@@ -99,6 +101,7 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	api.estimateProvisionerJobStarts(ctx, &apiBuild.Job)
 
 	httpapi.Write(ctx, rw, http.StatusOK, apiBuild)
 }
@@ -218,6 +221,7 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	api.estimateWorkspaceBuildJobStarts(ctx, apiBuilds)
 
 	httpapi.Write(ctx, rw, http.StatusOK, apiBuilds)
 }
@@ -309,6 +313,7 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 		})
 		return
 	}
+	api.estimateProvisionerJobStarts(ctx, &apiBuild.Job)
 
 	httpapi.Write(ctx, rw, http.StatusOK, apiBuild)
 }
@@ -324,7 +329,9 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 // @Tags Builds
 // @Param workspace path string true "Workspace ID" format(uuid)
 // @Param request body codersdk.CreateWorkspaceBuildRequest true "Create workspace build request"
+// @Param Idempotency-Key header string false "Retrying a request with the same key returns what the first request created"
 // @Success 200 {object} codersdk.WorkspaceBuild
+// @Success 202 {object} codersdk.QueuedWorkspaceBuild
 // @Router /workspaces/{workspace}/builds [post]
 func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -334,22 +341,73 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &createBuild) {
 		return
 	}
+	idempotencyKey, ok := parseIdempotencyKey(ctx, rw, r, createBuild)
+	if !ok {
+		return
+	}
+	if createBuild.Queue && idempotencyKey.Key != "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Queue cannot be set alongside the %s header since queued builds are created after the request.", codersdk.IdempotencyKeyHeader),
+		})
+		return
+	}
+	if api.replayWorkspaceBuild(ctx, rw, idempotencyKey, workspace) {
+		return
+	}
 
 	builder := wsbuilder.New(workspace, database.WorkspaceTransition(createBuild.Transition)).
 		Initiator(apiKey.UserID).
+		InitiatorContext(database.BuildInitiatorContext{
+			APIKeyName: apiKey.TokenName,
+		}).
 		RichParameterValues(createBuild.RichParameterValues).
 		LogLevel(string(createBuild.LogLevel)).
 		DeploymentValues(api.Options.DeploymentValues).
 		Experiments(api.Experiments).
-		TemplateVersionPresetID(createBuild.TemplateVersionPresetID)
+		TemplateVersionPresetID(createBuild.TemplateVersionPresetID).
+		TemplatePresetID(createBuild.TemplatePresetID).
+		PreflightChecks(api.workspaceBuildPreflightChecks()...)
+
+	if createBuild.Queue && (createBuild.Orphan || createBuild.Resume || len(createBuild.ProvisionerState) > 0) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Queue cannot be set alongside Orphan, Resume or ProvisionerState since the state the queued build starts from is unknown.",
+		})
+		return
+	}
+	if createBuild.Queue && createBuild.UseParameterDefaults {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Queue cannot be set alongside UseParameterDefaults since queued builds are started with the values of the last build.",
+		})
+		return
+	}
+	if createBuild.UseParameterDefaults {
+		builder = builder.UseParameterDefaults()
+	}
+	if idempotencyKey.Key != "" {
+		builder = builder.IdempotencyKey(idempotencyKey.UserID, idempotencyKey.Key)
+	}
 
 	var (
 		previousWorkspaceBuild database.WorkspaceBuild
 		workspaceBuild         *database.WorkspaceBuild
 		provisionerJob         *database.ProvisionerJob
 		provisionerDaemons     []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
+		queuedBuild            *codersdk.QueuedWorkspaceBuild
 	)
 
+	authFunc := func(action policy.Action, object rbac.Objecter) bool {
+		if auth := api.Authorize(r, action, object); auth {
+			return true
+		}
+		// Special handling for prebuilt workspace deletion
+		if action == policy.ActionDelete {
+			if workspaceObj, ok := object.(database.PrebuiltWorkspaceResource); ok && workspaceObj.IsPrebuild() {
+				return api.Authorize(r, action, workspaceObj.AsPrebuild())
+			}
+		}
+		return false
+	}
+
 	err := api.Database.InTx(func(tx database.Store) error {
 		var err error
 
@@ -363,6 +421,19 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			return nil
 		}
 
+		if createBuild.Queue {
+			// Queued builds are started later, so the initiator is authorized
+			// for the whole request up front.
+			err = builder.Authorize(ctx, tx, authFunc)
+			if err != nil {
+				return err
+			}
+			queuedBuild, err = queueWorkspaceBuild(ctx, tx, workspace, apiKey.UserID, previousWorkspaceBuild, createBuild)
+			if err != nil || queuedBuild != nil {
+				return err
+			}
+		}
+
 		if createBuild.TemplateVersionID != uuid.Nil {
 			builder = builder.VersionID(createBuild.TemplateVersionID)
 		}
@@ -382,34 +453,54 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			}
 			builder = builder.Orphan()
 		}
+		if createBuild.Resume {
+			if createBuild.Orphan || len(createBuild.ProvisionerState) > 0 {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "Resume cannot be set alongside Orphan or ProvisionerState since state intent is unclear.",
+				})
+				return nil
+			}
+			builder = builder.Resume()
+		}
 		if len(createBuild.ProvisionerState) > 0 {
 			builder = builder.State(createBuild.ProvisionerState)
 		}
 
+		// The key is reserved before the build, so that a concurrent retry
+		// replays this request rather than conflicting with the build.
+		err = reserveIdempotencyKey(ctx, tx, idempotencyKey)
+		if err != nil {
+			return err
+		}
+
 		workspaceBuild, provisionerJob, provisionerDaemons, err = builder.Build(
 			ctx,
 			tx,
 			api.FileCache,
-			func(action policy.Action, object rbac.Objecter) bool {
-				if auth := api.Authorize(r, action, object); auth {
-					return true
-				}
-				// Special handling for prebuilt workspace deletion
-				if action == policy.ActionDelete {
-					if workspaceObj, ok := object.(database.PrebuiltWorkspaceResource); ok && workspaceObj.IsPrebuild() {
-						return api.Authorize(r, action, workspaceObj.AsPrebuild())
-					}
-				}
-				return false
-			},
+			authFunc,
 			audit.WorkspaceBuildBaggageFromRequest(r),
 		)
 		return err
 	}, nil)
+	if isIdempotencyKeyConflict(err) {
+		// A concurrent request with the same key created the build.
+		if !api.replayWorkspaceBuild(ctx, rw, idempotencyKey, workspace) {
+			httpapi.Write(ctx, rw, http.StatusConflict, idempotencyKeyReusedResponse)
+		}
+		return
+	}
 	if err != nil {
 		httperror.WriteWorkspaceBuildError(ctx, rw, err)
 		return
 	}
+	if queuedBuild != nil {
+		api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+			Kind:        wspubsub.WorkspaceEventKindStateChange,
+			WorkspaceID: workspace.ID,
+		})
+		httpapi.Write(ctx, rw, http.StatusAccepted, queuedBuild)
+		return
+	}
 
 	var queuePos database.GetProvisionerJobsByIDsWithQueuePositionRow
 	if provisionerJob != nil {
@@ -429,11 +520,12 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 				slog.F("provisioner_job_id", provisionerJob.ID),
 			)
 			buildResourceInfo := audit.AdditionalFields{
-				WorkspaceName:  workspace.Name,
-				BuildNumber:    strconv.Itoa(int(workspaceBuild.BuildNumber)),
-				BuildReason:    workspaceBuild.Reason,
-				WorkspaceID:    workspace.ID,
-				WorkspaceOwner: workspace.OwnerName,
+				WorkspaceName:         workspace.Name,
+				BuildNumber:           strconv.Itoa(int(workspaceBuild.BuildNumber)),
+				BuildReason:           workspaceBuild.Reason,
+				WorkspaceID:           workspace.ID,
+				WorkspaceOwner:        workspace.OwnerName,
+				BuildInitiatorContext: &workspaceBuild.InitiatorContext,
 			}
 			briBytes, err := json.Marshal(buildResourceInfo)
 			if err != nil {
@@ -506,6 +598,106 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
 }
 
+// @Summary Roll back workspace build
+// @Description Creates a build that starts the workspace with the template version
+// @Description and parameter values of its last successful start build.
+// @ID roll-back-workspace-build
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 201 {object} codersdk.WorkspaceBuild
+// @Router /workspaces/{workspace}/builds/rollback [post]
+func (api *API) postWorkspaceBuildRollback(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	workspace := httpmw.WorkspaceParam(r)
+
+	rollbackBuild, err := api.Database.GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspace has no successful build to roll back to.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching last successful workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+		Initiator(apiKey.UserID).
+		InitiatorContext(database.BuildInitiatorContext{
+			APIKeyName: apiKey.TokenName,
+		}).
+		DeploymentValues(api.Options.DeploymentValues).
+		Experiments(api.Experiments).
+		Rollback(rollbackBuild).
+		PreflightChecks(api.workspaceBuildPreflightChecks()...)
+
+	var (
+		workspaceBuild     *database.WorkspaceBuild
+		provisionerJob     *database.ProvisionerJob
+		provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		var err error
+		workspaceBuild, provisionerJob, provisionerDaemons, err = builder.Build(
+			ctx,
+			tx,
+			api.FileCache,
+			func(action policy.Action, object rbac.Objecter) bool {
+				return api.Authorize(r, action, object)
+			},
+			audit.WorkspaceBuildBaggageFromRequest(r),
+		)
+		return err
+	}, nil)
+	if err != nil {
+		httperror.WriteWorkspaceBuildError(ctx, rw, err)
+		return
+	}
+
+	if err := provisionerjobs.PostJob(api.Pubsub, *provisionerJob); err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		*workspaceBuild,
+		workspace,
+		database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: *provisionerJob,
+		},
+		[]database.WorkspaceResource{},
+		[]database.WorkspaceResourceMetadatum{},
+		[]database.WorkspaceAgent{},
+		[]database.WorkspaceApp{},
+		[]database.WorkspaceAppStatus{},
+		[]database.WorkspaceAgentScript{},
+		[]database.WorkspaceAgentLogSource{},
+		database.TemplateVersion{},
+		provisionerDaemons,
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+}
+
 func (api *API) notifyWorkspaceUpdated(
 	ctx context.Context,
 	initiatorID uuid.UUID,
@@ -620,12 +812,14 @@ func (api *API) patchCancelWorkspaceBuild(rw http.ResponseWriter, r *http.Reques
 	if job.CompletedAt.Valid {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Job has already completed!",
+			Code:    completedJobResponseCode(job),
 		})
 		return
 	}
 	if job.CanceledAt.Valid {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Job has already been marked as canceled!",
+			Code:    codersdk.ResponseCodeJobCanceled,
 		})
 		return
 	}
@@ -649,6 +843,7 @@ func (api *API) patchCancelWorkspaceBuild(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	api.publishProvisionerJobStateChange(ctx, job)
 	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
 		Kind:        wspubsub.WorkspaceEventKindStateChange,
 		WorkspaceID: workspace.ID,
@@ -709,6 +904,10 @@ func (api *API) workspaceBuildParameters(rw http.ResponseWriter, r *http.Request
 // @Param before query int false "Before log id"
 // @Param after query int false "After log id"
 // @Param follow query bool false "Follow log stream"
+// @Param structured query bool false "Include structured log fields"
+// @Param level query string false "Only return logs at or above this level" enums(trace,debug,info,warn,error)
+// @Param stage query string false "Only return logs of this stage"
+// @Param search query string false "Only return logs whose output contains every word of the search"
 // @Success 200 {array} codersdk.ProvisionerJobLog
 // @Router /workspacebuilds/{workspacebuild}/logs [get]
 func (api *API) workspaceBuildLogs(rw http.ResponseWriter, r *http.Request) {
@@ -791,6 +990,105 @@ func (api *API) workspaceBuildTimings(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, timings)
 }
 
+// @Summary Get workspace build archive
+// @ID get-workspace-build-archive
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBuildArchive
+// @Router /workspacebuilds/{workspacebuild}/archive [get]
+func (api *API) workspaceBuildArchive(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		build = httpmw.WorkspaceBuildParam(r)
+	)
+
+	archive, err := api.Database.GetWorkspaceBuildArchiveByBuildID(ctx, build.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Workspace build is not archived.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build archive.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceBuildArchive{
+		WorkspaceBuildID:      archive.WorkspaceBuildID,
+		FileID:                archive.FileID,
+		StateSize:             archive.StateSize,
+		ResourceMetadataCount: archive.ResourceMetadataCount,
+		ArchivedAt:            archive.ArchivedAt,
+	})
+}
+
+// @Summary Rehydrate archived workspace build
+// @Description Restores the provisioner state and resource metadata of an
+// @Description archived workspace build into the database.
+// @ID rehydrate-archived-workspace-build
+// @Security CoderSessionToken
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Success 204
+// @Router /workspacebuilds/{workspacebuild}/rehydrate [post]
+func (api *API) postWorkspaceBuildRehydrate(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		build = httpmw.WorkspaceBuildParam(r)
+	)
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		// The archive file is owned by the build initiator and the archived
+		// data is not readable by users directly.
+		//nolint:gocritic // Access to the workspace was authorized above.
+		return buildarchive.Rehydrate(dbauthz.AsSystemRestricted(ctx), tx, build.ID)
+	}, nil)
+	if errors.Is(err, buildarchive.ErrNotArchived) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Workspace build is not archived.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error rehydrating workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// estimateWorkspaceBuildJobStarts sets the estimated start time on the jobs of
+// pending workspace builds.
+func (api *API) estimateWorkspaceBuildJobStarts(ctx context.Context, builds []codersdk.WorkspaceBuild) {
+	jobs := make([]*codersdk.ProvisionerJob, 0, len(builds))
+	for i := range builds {
+		jobs = append(jobs, &builds[i].Job)
+	}
+	api.estimateProvisionerJobStarts(ctx, jobs...)
+}
+
 type workspaceBuildsData struct {
 	jobs               []database.GetProvisionerJobsByIDsWithQueuePositionRow
 	templateVersions   []database.TemplateVersion
@@ -1090,6 +1388,9 @@ func (api *API) convertWorkspaceBuild(
 		apiResources = append(apiResources, convertWorkspaceResource(resource, apiAgents, metadata))
 	}
 	sort.Slice(apiResources, func(i, j int) bool {
+		if apiResources[i].DisplayOrder != apiResources[j].DisplayOrder {
+			return apiResources[i].DisplayOrder < apiResources[j].DisplayOrder
+		}
 		orderI := resourceAgentsMinOrder[apiResources[i].ID]
 		orderJ := resourceAgentsMinOrder[apiResources[j].ID]
 		if orderI != orderJ {
@@ -1132,6 +1433,11 @@ func (api *API) convertWorkspaceBuild(
 		Deadline:                codersdk.NewNullTime(build.Deadline, !build.Deadline.IsZero()),
 		MaxDeadline:             codersdk.NewNullTime(build.MaxDeadline, !build.MaxDeadline.IsZero()),
 		Reason:                  codersdk.BuildReason(build.Reason),
+		InitiatorContext: codersdk.WorkspaceBuildInitiatorContext{
+			APIKeyName: build.InitiatorContext.APIKeyName,
+			Automation: codersdk.BuildAutomation(build.InitiatorContext.Automation),
+			Schedule:   build.InitiatorContext.Schedule,
+		},
 		Resources:               apiResources,
 		Status:                  codersdk.ConvertWorkspaceStatus(apiJob.Status, transition),
 		DailyCost:               build.DailyCost,
@@ -1139,9 +1445,31 @@ func (api *API) convertWorkspaceBuild(
 		TemplateVersionPresetID: presetID,
 		HasAITask:               hasAITask,
 		AITaskSidebarAppID:      aiTasksSidebarAppID,
+		ExceedsTemplateP95:      api.exceedsTemplateP95(templateVersion, transition, job.ProvisionerJob),
+		TraceID:                 provisionerJobTraceID(job.ProvisionerJob),
+		Warnings: db2sdk.List(build.Warnings, func(w database.WorkspaceBuildWarning) codersdk.WorkspaceBuildWarning {
+			return codersdk.WorkspaceBuildWarning{
+				Code:           codersdk.WorkspaceBuildWarningCode(w.Code),
+				Message:        w.Message,
+				VersionsBehind: w.VersionsBehind,
+			}
+		}),
 	}, nil
 }
 
+// provisionerJobTraceID returns the ID of the trace the provisioner job was
+// created in, or an empty string if the job was not created in a trace.
+func provisionerJobTraceID(job database.ProvisionerJob) string {
+	if !job.TraceMetadata.Valid {
+		return ""
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal(job.TraceMetadata.RawMessage, &metadata); err != nil {
+		return ""
+	}
+	return tracing.TraceIDFromMetadata(metadata)
+}
+
 func convertWorkspaceResource(resource database.WorkspaceResource, agents []codersdk.WorkspaceAgent, metadata []database.WorkspaceResourceMetadatum) codersdk.WorkspaceResource {
 	var convertedMetadata []codersdk.WorkspaceResourceMetadata
 	for _, field := range metadata {
@@ -1153,17 +1481,20 @@ func convertWorkspaceResource(resource database.WorkspaceResource, agents []code
 	}
 
 	return codersdk.WorkspaceResource{
-		ID:         resource.ID,
-		CreatedAt:  resource.CreatedAt,
-		JobID:      resource.JobID,
-		Transition: codersdk.WorkspaceTransition(resource.Transition),
-		Type:       resource.Type,
-		Name:       resource.Name,
-		Hide:       resource.Hide,
-		Icon:       resource.Icon,
-		Agents:     agents,
-		Metadata:   convertedMetadata,
-		DailyCost:  resource.DailyCost,
+		ID:           resource.ID,
+		CreatedAt:    resource.CreatedAt,
+		JobID:        resource.JobID,
+		Transition:   codersdk.WorkspaceTransition(resource.Transition),
+		Type:         resource.Type,
+		Name:         resource.Name,
+		Hide:         resource.Hide,
+		Icon:         resource.Icon,
+		Agents:       agents,
+		Metadata:     convertedMetadata,
+		DailyCost:    resource.DailyCost,
+		DisplayGroup: resource.DisplayGroup,
+		DisplayOrder: resource.DisplayOrder,
+		Collapsed:    resource.Collapsed,
 	}
 }
 
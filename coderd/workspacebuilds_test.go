@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -17,11 +18,15 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/buildarchive"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
@@ -31,13 +36,16 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/joblogarchive"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
 )
 
 func TestWorkspaceBuild(t *testing.T) {
@@ -85,6 +93,56 @@ func TestWorkspaceBuild(t *testing.T) {
 	require.Equal(t, up.AvatarURL, wb.WorkspaceOwnerAvatarURL)
 }
 
+func TestWorkspaceBuildTrace(t *testing.T) {
+	t.Parallel()
+	otel.SetTextMapPropagator(
+		propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		),
+	)
+	spanRecorder := tracetest.NewSpanRecorder()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		TracerProvider:           sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)),
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	require.NotEmpty(t, build.TraceID, "build should carry the ID of its trace")
+
+	_ = agenttest.New(t, client.URL, authToken)
+	coderdtest.NewWorkspaceAgentWaiter(t, client, workspace.ID).Wait()
+
+	// Acquiring the job, provisioning it, and the first connection of the
+	// agent should all be part of the trace of the build.
+	require.Eventually(t, func() bool {
+		var acquired, provisioned, connected bool
+		for _, span := range spanRecorder.Ended() {
+			if span.SpanContext().TraceID().String() != build.TraceID {
+				continue
+			}
+			switch span.Name() {
+			case "provisionerdserver.(*server).acquireProtoJob":
+				acquired = true
+			case "runner.(*Runner).Run":
+				provisioned = true
+			case "coderd.(*API).traceAgentFirstConnect":
+				connected = true
+			}
+		}
+		return acquired && provisioned && connected
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func TestWorkspaceBuildByBuildNumber(t *testing.T) {
 	t.Parallel()
 	t.Run("Successful", func(t *testing.T) {
@@ -696,6 +754,61 @@ func TestWorkspaceBuildResources(t *testing.T) {
 		assertWorkspaceResource(t, workspace.LatestBuild.Resources[3], "fourth_resource", "example", 0) // resource has no agents, sorted by name
 		assertWorkspaceResource(t, workspace.LatestBuild.Resources[4], "third_resource", "example", 0)  // resource is the last one
 	})
+
+	t.Run("DisplayHints", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: []*proto.Resource{{
+							Name: "first_resource",
+							Type: "example",
+							Agents: []*proto.Agent{{
+								Id:           "something-1",
+								Name:         "something-1",
+								Auth:         &proto.Agent_Token{},
+								DisplayGroup: "Agents",
+								Collapsed:    true,
+							}},
+						}, {
+							Name:         "second_resource",
+							Type:         "example",
+							DisplayGroup: "Storage",
+							Order:        -1,
+							Collapsed:    true,
+						}},
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		workspace, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		resources := workspace.LatestBuild.Resources
+		require.Len(t, resources, 2)
+		// The explicit resource order takes precedence over the order of
+		// the agents.
+		require.Equal(t, "second_resource", resources[0].Name)
+		require.Equal(t, "Storage", resources[0].DisplayGroup)
+		require.EqualValues(t, -1, resources[0].DisplayOrder)
+		require.True(t, resources[0].Collapsed)
+		require.Equal(t, "first_resource", resources[1].Name)
+		require.Empty(t, resources[1].DisplayGroup)
+		require.False(t, resources[1].Collapsed)
+		require.Len(t, resources[1].Agents, 1)
+		require.Equal(t, "Agents", resources[1].Agents[0].DisplayGroup)
+		require.True(t, resources[1].Agents[0].Collapsed)
+	})
 }
 
 func TestWorkspaceBuildWithUpdatedTemplateVersionSendsNotification(t *testing.T) {
@@ -888,6 +1001,174 @@ func TestWorkspaceBuildLogs(t *testing.T) {
 	require.Fail(t, "example message never happened")
 }
 
+func TestWorkspaceBuildLogsStructured(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Log{
+				Log: &proto.Log{
+					Level:  proto.LogLevel_INFO,
+					Output: "docker_container.workspace: Creating...",
+					Fields: &proto.LogFields{
+						ResourceAddress: "docker_container.workspace",
+						Action:          "create",
+					},
+				},
+			},
+		}, {
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	getLogs := func(query string) []codersdk.ProvisionerJobLog {
+		res, err := client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs%s", workspace.LatestBuild.ID, query), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var logs []codersdk.ProvisionerJobLog
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&logs))
+		return logs
+	}
+
+	// Fields are omitted unless requested.
+	for _, log := range getLogs("") {
+		require.Nil(t, log.Fields)
+	}
+
+	var found bool
+	for _, log := range getLogs("?structured") {
+		require.NotNil(t, log.Fields)
+		if log.Output == "docker_container.workspace: Creating..." {
+			found = true
+			require.Equal(t, codersdk.ProvisionerJobLogFields{
+				ResourceAddress: "docker_container.workspace",
+				Action:          "create",
+			}, *log.Fields)
+		}
+	}
+	require.True(t, found, "structured log was not returned")
+}
+
+func TestWorkspaceBuildLogsFiltered(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Log{
+				Log: &proto.Log{
+					Level:  proto.LogLevel_INFO,
+					Output: "docker_container.workspace: Creating...",
+				},
+			},
+		}, {
+			Type: &proto.Response_Log{
+				Log: &proto.Log{
+					Level:  proto.LogLevel_WARN,
+					Output: "Warning: image pulled from an untrusted registry",
+				},
+			},
+		}, {
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	all, err := client.WorkspaceBuildLogs(ctx, workspace.LatestBuild.ID, codersdk.ProvisionerJobLogsFilter{})
+	require.NoError(t, err)
+	require.Greater(t, len(all), 2)
+
+	// The echo provisioner sends the logs both while planning and applying.
+	logs, err := client.WorkspaceBuildLogs(ctx, workspace.LatestBuild.ID, codersdk.ProvisionerJobLogsFilter{
+		Level: codersdk.LogLevelWarn,
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	for _, log := range logs {
+		require.Equal(t, "Warning: image pulled from an untrusted registry", log.Output)
+	}
+	require.NotEqual(t, logs[0].Stage, logs[1].Stage)
+
+	logs, err = client.WorkspaceBuildLogs(ctx, workspace.LatestBuild.ID, codersdk.ProvisionerJobLogsFilter{
+		Search: "Untrusted REGISTRY",
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+
+	stage := logs[1].Stage
+	logs, err = client.WorkspaceBuildLogs(ctx, workspace.LatestBuild.ID, codersdk.ProvisionerJobLogsFilter{
+		Stage:  stage,
+		Search: "creating",
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, stage, logs[0].Stage)
+	require.Equal(t, "docker_container.workspace: Creating...", logs[0].Output)
+
+	_, err = client.WorkspaceBuildLogs(ctx, workspace.LatestBuild.ID, codersdk.ProvisionerJobLogsFilter{
+		Level: "verbose",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
+func TestWorkspaceBuildLogsArchived(t *testing.T) {
+	t.Parallel()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	getLogs := func(query string) []codersdk.ProvisionerJobLog {
+		res, err := client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs%s", build.ID, query), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var logs []codersdk.ProvisionerJobLog
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&logs))
+		return logs
+	}
+	expected := getLogs("")
+	require.NotEmpty(t, expected)
+
+	archiver := joblogarchive.New(ctx, testutil.Logger(t), db, quartz.NewReal(), time.Nanosecond)
+	defer archiver.Close()
+	require.Eventually(t, func() bool {
+		//nolint:gocritic // Test checks the archive directly.
+		_, err := db.GetProvisionerJobLogArchiveByJobID(dbauthz.AsSystemRestricted(ctx), build.Job.ID)
+		return err == nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	// Archived logs are served as if they were still in the database.
+	require.Equal(t, expected, getLogs(""))
+	require.Equal(t, expected[1:], getLogs(fmt.Sprintf("?after=%d", expected[0].ID)))
+}
+
 func TestWorkspaceBuildState(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -917,6 +1198,67 @@ func TestWorkspaceBuildState(t *testing.T) {
 	require.Equal(t, wantState, gotState)
 }
 
+func TestWorkspaceBuildArchive(t *testing.T) {
+	t.Parallel()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	wantState := []byte("some kinda state")
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.PlanComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{
+					State: wantState,
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Builds that are not archived have no archive and cannot be rehydrated.
+	_, err := client.WorkspaceBuildArchive(ctx, build.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	err = client.RehydrateWorkspaceBuild(ctx, build.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	//nolint:gocritic // Test archives the build directly.
+	err = db.InTx(func(tx database.Store) error {
+		return buildarchive.Archive(dbauthz.AsSystemRestricted(ctx), tx, build.ID, dbtime.Now())
+	}, nil)
+	require.NoError(t, err)
+
+	archive, err := client.WorkspaceBuildArchive(ctx, build.ID)
+	require.NoError(t, err)
+	require.Equal(t, build.ID, archive.WorkspaceBuildID)
+	require.EqualValues(t, len(wantState), archive.StateSize)
+	gotState, err := client.WorkspaceBuildState(ctx, build.ID)
+	require.NoError(t, err)
+	require.Empty(t, gotState)
+	// The build still renders from its summary rows.
+	got, err := client.WorkspaceBuild(ctx, build.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Resources, len(build.Resources))
+
+	err = client.RehydrateWorkspaceBuild(ctx, build.ID)
+	require.NoError(t, err)
+	gotState, err = client.WorkspaceBuildState(ctx, build.ID)
+	require.NoError(t, err)
+	require.Equal(t, wantState, gotState)
+	_, err = client.WorkspaceBuildArchive(ctx, build.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
 func TestWorkspaceBuildStatus(t *testing.T) {
 	t.Parallel()
 
@@ -1337,6 +1679,7 @@ func TestPostWorkspaceBuild(t *testing.T) {
 		var apiErr *codersdk.Error
 		require.ErrorAs(t, err, &apiErr)
 		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+		require.Equal(t, codersdk.ResponseCodeBuildInProgress, apiErr.Code)
 	})
 
 	t.Run("Audit", func(t *testing.T) {
@@ -1408,6 +1751,41 @@ func TestPostWorkspaceBuild(t *testing.T) {
 		require.Equal(t, workspace.LatestBuild.BuildNumber+1, build.BuildNumber)
 	})
 
+	t.Run("InitiatorContext", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// Builds started with a named API token are attributed to it.
+		token, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci-pipeline",
+		})
+		require.NoError(t, err)
+		tokenClient := codersdk.New(client.URL)
+		tokenClient.SetSessionToken(token.Key)
+
+		build, err := tokenClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.BuildReasonInitiator, build.Reason)
+		require.Equal(t, "ci-pipeline", build.InitiatorContext.APIKeyName)
+		require.Empty(t, build.InitiatorContext.Automation)
+		require.Empty(t, build.InitiatorContext.Schedule)
+
+		build, err = client.WorkspaceBuild(ctx, build.ID)
+		require.NoError(t, err)
+		require.Equal(t, "ci-pipeline", build.InitiatorContext.APIKeyName)
+	})
+
 	t.Run("WithState", func(t *testing.T) {
 		t.Parallel()
 		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
@@ -1442,6 +1820,73 @@ func TestPostWorkspaceBuild(t *testing.T) {
 		require.Equal(t, wantState, gotState)
 	})
 
+	t.Run("Resume", func(t *testing.T) {
+		t.Parallel()
+		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:         echo.ParseComplete,
+			ProvisionPlan: echo.PlanComplete,
+			ProvisionApplyMap: map[proto.WorkspaceTransition][]*proto.Response{
+				proto.WorkspaceTransition_START: {{
+					Type: &proto.Response_InterimState{InterimState: &proto.InterimState{
+						State: []byte("interim state"),
+					}},
+				}, echo.ApplyFailed[0]},
+			},
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+		_ = closeDaemon.Close()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+			Resume:     true,
+			Orphan:     true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+			Resume:     true,
+		})
+		require.NoError(t, err)
+		gotState, err := client.WorkspaceBuildState(ctx, build.ID)
+		require.NoError(t, err)
+		require.Equal(t, "interim state", string(gotState))
+	})
+
+	t.Run("ResumeSucceededBuild", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+			Resume:     true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, "Only failed or canceled builds can be resumed, the last build is succeeded.", apiErr.Message)
+	})
+
 	t.Run("SetsPresetID", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -1614,6 +2059,291 @@ func TestPostWorkspaceBuild(t *testing.T) {
 			assert.True(t, build.MatchedProvisioners.MostRecentlySeen.Valid)
 		}
 	})
+
+	t.Run("PreflightChecks", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			WorkspaceBuildPreflightChecks: []wsbuilder.PreflightCheck{
+				stopForbiddenPreflightCheck{},
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, "Workspaces cannot be stopped.", apiErr.Message)
+		require.Equal(t, codersdk.ResponseCodePreflightFailed, apiErr.Code)
+		require.Equal(t, []codersdk.ValidationError{{
+			Field:  "transition",
+			Detail: "Workspaces cannot be stopped.",
+		}}, apiErr.Validations)
+
+		// No build was inserted.
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, workspace.LatestBuild.BuildNumber)
+	})
+}
+
+type stopForbiddenPreflightCheck struct{}
+
+func (stopForbiddenPreflightCheck) Name() string {
+	return "stop_forbidden"
+}
+
+func (stopForbiddenPreflightCheck) Check(_ context.Context, req wsbuilder.PreflightRequest) ([]wsbuilder.PreflightFailure, error) {
+	if req.Transition != database.WorkspaceTransitionStop {
+		return nil, nil
+	}
+	return []wsbuilder.PreflightFailure{{Field: "transition", Message: "Workspaces cannot be stopped."}}, nil
+}
+
+func TestPostWorkspaceBuildRollback(t *testing.T) {
+	t.Parallel()
+
+	responses := func(apply []*proto.Response) *echo.Responses {
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						Parameters: []*proto.RichParameter{{
+							Name:         "region",
+							Type:         "string",
+							DefaultValue: "us",
+							Mutable:      true,
+							FormType:     proto.ParameterFormType_INPUT,
+						}},
+					},
+				},
+			}},
+			ProvisionApply: apply,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, responses(echo.ApplyComplete))
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "us"}}
+		})
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Promote a version that fails to apply, and update the workspace
+		// to it with a different parameter value.
+		badVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, responses(echo.ApplyFailed), template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, badVersion.ID)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{ID: badVersion.ID})
+		require.NoError(t, err)
+		badBuild, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID:   badVersion.ID,
+			Transition:          codersdk.WorkspaceTransitionStart,
+			RichParameterValues: []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}},
+		})
+		require.NoError(t, err)
+		badBuild = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, badBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, badBuild.Status)
+
+		build, err := client.RollbackWorkspaceBuild(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, build.TemplateVersionID)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, build.Transition)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		params, err := client.WorkspaceBuildParameters(ctx, build.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "us"}}, params)
+	})
+
+	t.Run("NoSuccessfulBuild", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, responses(echo.ApplyFailed))
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.RollbackWorkspaceBuild(ctx, workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, "Workspace has no successful build to roll back to.", apiErr.Message)
+	})
+}
+
+func TestWorkspaceBuildWarnings(t *testing.T) {
+	t.Parallel()
+
+	notify := &notificationstest.FakeEnqueuer{}
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, NotificationsEnqueuer: notify})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	require.Empty(t, build.Warnings)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, newVersion.ID)
+	coderdtest.UpdateActiveTemplateVersion(t, client, template.ID, newVersion.ID)
+
+	// Restarting the workspace keeps the version of its last build, which is
+	// now behind the active version.
+	coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStop, codersdk.WorkspaceTransitionStart)
+	build, err := client.WorkspaceBuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, version.ID, build.TemplateVersionID)
+	require.Len(t, build.Warnings, 1)
+	require.Equal(t, codersdk.WorkspaceBuildWarningInactiveTemplateVersion, build.Warnings[0].Code)
+	require.Equal(t, []string{newVersion.Name}, build.Warnings[0].VersionsBehind)
+
+	sent := notify.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceOutdatedBuild))
+	require.Len(t, sent, 1)
+	require.Equal(t, user.UserID, sent[0].UserID)
+	require.Equal(t, workspace.Name, sent[0].Labels["name"])
+}
+
+func TestWorkspaceBuildResourceCeilings(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	resources := []*proto.Resource{{
+		Name: "dev",
+		Type: "docker_container",
+		Metadata: []*proto.Resource_Metadata{{
+			Key:   "cpu",
+			Value: "8 cores",
+		}},
+	}}
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{Plan: &proto.PlanComplete{Resources: resources}},
+		}},
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{Apply: &proto.ApplyComplete{Resources: resources}},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		ResourceCeilings: &codersdk.TemplateResourceCeilings{CPU: 4},
+	})
+	require.NoError(t, err)
+	require.Equal(t, float64(4), template.ResourceCeilings.CPU)
+
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.ProvisionerJobFailed, build.Job.Status)
+	require.True(t, codersdk.JobIsResourceCeilingErrorCode(build.Job.ErrorCode))
+	require.Contains(t, build.Job.Error, "cpu 8 > 4")
+
+	// Raising the ceiling allows the workspace to start.
+	_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		ResourceCeilings: &codersdk.TemplateResourceCeilings{CPU: 8},
+	})
+	require.NoError(t, err)
+	build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStart,
+	})
+	require.NoError(t, err)
+	build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+	require.Equal(t, codersdk.ProvisionerJobSucceeded, build.Job.Status)
+}
+
+func TestWorkspaceBuildRequiredProvisionerTags(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	require.Empty(t, template.RequiredProvisionerTags)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		RequiredProvisionerTags: &map[string]string{"pool": ""},
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Len(t, apiErr.Validations, 1)
+	require.Equal(t, "required_provisioner_tags", apiErr.Validations[0].Field)
+
+	template, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		RequiredProvisionerTags: &map[string]string{"pool": "hardened"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"pool": "hardened"}, template.RequiredProvisionerTags)
+
+	// The active version does not target the required tags, so builds are
+	// rejected before a job is queued.
+	_, err = client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: template.ID,
+		Name:       coderdtest.RandomUsername(t),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Contains(t, apiErr.Detail, "pool=hardened")
+
+	// New versions of the template must target the required tags too.
+	_, err = client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+		TemplateID:    template.ID,
+		FileID:        version.Job.FileID,
+		StorageMethod: codersdk.ProvisionerStorageMethodFile,
+		Provisioner:   codersdk.ProvisionerTypeEcho,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Contains(t, apiErr.Detail, "pool=hardened")
+
+	pinned := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+		req.TemplateID = template.ID
+		req.ProvisionerTags = map[string]string{"pool": "hardened"}
+	})
+	require.Equal(t, "hardened", pinned.Job.Tags["pool"])
+
+	// Removing the requirement allows the workspace to build again.
+	_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		RequiredProvisionerTags: &map[string]string{},
+	})
+	require.NoError(t, err)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.ProvisionerJobSucceeded, build.Job.Status)
 }
 
 func TestWorkspaceBuildTimings(t *testing.T) {
@@ -0,0 +1,349 @@
+package coderd
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Export workspace
+// @Description Exports the definition of a workspace as a bundle that can be
+// @Description imported into another deployment or organization.
+// @ID export-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBundle
+// @Router /workspaces/{workspace}/export [get]
+func (api *API) workspaceExport(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	bundle, err := workspaceBundle(ctx, api.Database, workspace)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error exporting workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, bundle)
+}
+
+// workspaceBundle exports the workspace from db, which may be a transaction.
+func workspaceBundle(ctx context.Context, db database.Store, workspace database.Workspace) (codersdk.WorkspaceBundle, error) {
+	build, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		return codersdk.WorkspaceBundle{}, xerrors.Errorf("get latest build: %w", err)
+	}
+	template, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return codersdk.WorkspaceBundle{}, xerrors.Errorf("get template: %w", err)
+	}
+	version, err := db.GetTemplateVersionByID(ctx, build.TemplateVersionID)
+	if err != nil {
+		return codersdk.WorkspaceBundle{}, xerrors.Errorf("get template version: %w", err)
+	}
+
+	versionParameters, err := db.GetTemplateVersionParameters(ctx, version.ID)
+	if err != nil {
+		return codersdk.WorkspaceBundle{}, xerrors.Errorf("get template version parameters: %w", err)
+	}
+	ephemeral := make(map[string]bool, len(versionParameters))
+	for _, parameter := range versionParameters {
+		ephemeral[parameter.Name] = parameter.Ephemeral
+	}
+	buildParameters, err := db.GetWorkspaceBuildParameters(ctx, build.ID)
+	if err != nil {
+		return codersdk.WorkspaceBundle{}, xerrors.Errorf("get build parameters: %w", err)
+	}
+	parameters := make([]codersdk.WorkspaceBuildParameter, 0, len(buildParameters))
+	for _, parameter := range buildParameters {
+		if ephemeral[parameter.Name] {
+			continue
+		}
+		parameters = append(parameters, codersdk.WorkspaceBuildParameter{
+			Name:  parameter.Name,
+			Value: parameter.Value,
+		})
+	}
+
+	resources, err := bundleResources(ctx, db, build.JobID)
+	if err != nil {
+		return codersdk.WorkspaceBundle{}, err
+	}
+
+	var autostartSchedule *string
+	if workspace.AutostartSchedule.Valid {
+		autostartSchedule = &workspace.AutostartSchedule.String
+	}
+
+	return codersdk.WorkspaceBundle{
+		Version:             codersdk.WorkspaceBundleVersion,
+		ExportedAt:          dbtime.Now(),
+		Name:                workspace.Name,
+		OrganizationName:    template.OrganizationName,
+		TemplateName:        template.Name,
+		TemplateVersionID:   version.ID,
+		TemplateVersionName: version.Name,
+		RichParameterValues: parameters,
+		AutostartSchedule:   autostartSchedule,
+		TTLMillis:           convertWorkspaceTTLMillis(workspace.Ttl),
+		AutomaticUpdates:    codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
+		Resources:           resources,
+	}, nil
+}
+
+func bundleResources(ctx context.Context, db database.Store, jobID uuid.UUID) ([]codersdk.WorkspaceBundleResource, error) {
+	resources, err := db.GetWorkspaceResourcesByJobID(ctx, jobID)
+	if err != nil {
+		return nil, xerrors.Errorf("get resources: %w", err)
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	metadata, err := db.GetWorkspaceResourceMetadataByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get resource metadata: %w", err)
+	}
+	agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get agents: %w", err)
+	}
+	agentIDs := make([]uuid.UUID, 0, len(agents))
+	for _, agent := range agents {
+		agentIDs = append(agentIDs, agent.ID)
+	}
+	apps, err := db.GetWorkspaceAppsByAgentIDs(ctx, agentIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get apps: %w", err)
+	}
+
+	bundleResources := make([]codersdk.WorkspaceBundleResource, 0, len(resources))
+	for _, resource := range resources {
+		bundleResource := codersdk.WorkspaceBundleResource{
+			Type: resource.Type,
+			Name: resource.Name,
+		}
+		for _, item := range metadata {
+			if item.WorkspaceResourceID != resource.ID || item.Sensitive {
+				continue
+			}
+			bundleResource.Metadata = append(bundleResource.Metadata, codersdk.WorkspaceResourceMetadata{
+				Key:   item.Key,
+				Value: item.Value.String,
+			})
+		}
+		for _, agent := range agents {
+			if agent.ResourceID != resource.ID {
+				continue
+			}
+			bundleAgent := codersdk.WorkspaceBundleAgent{
+				Name:            agent.Name,
+				OperatingSystem: agent.OperatingSystem,
+				Architecture:    agent.Architecture,
+				Directory:       agent.Directory,
+				DisplayApps:     make([]codersdk.DisplayApp, 0, len(agent.DisplayApps)),
+			}
+			for _, displayApp := range agent.DisplayApps {
+				bundleAgent.DisplayApps = append(bundleAgent.DisplayApps, codersdk.DisplayApp(displayApp))
+			}
+			for _, app := range apps {
+				if app.AgentID == agent.ID {
+					bundleAgent.Apps = append(bundleAgent.Apps, app.Slug)
+				}
+			}
+			bundleResource.Agents = append(bundleResource.Agents, bundleAgent)
+		}
+		bundleResources = append(bundleResources, bundleResource)
+	}
+	return bundleResources, nil
+}
+
+// @Summary Import workspace
+// @Description Creates a workspace for an organization member from a bundle
+// @Description produced by the export workspace endpoint. The template is
+// @Description looked up by name in the organization unless template_id is
+// @Description set. The exported template version is used if the template
+// @Description has it, otherwise the active version is. Parameter values
+// @Description that the template version doesn't define are dropped.
+// @Description Template managers can set the provisioner state to keep the
+// @Description resources of a workspace that is moved between deployments.
+// @ID import-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "Username, UUID, or me"
+// @Param request body codersdk.ImportWorkspaceRequest true "Import workspace request"
+// @Success 201 {object} codersdk.Workspace
+// @Router /organizations/{organization}/members/{user}/workspaces/import [post]
+func (api *API) postImportWorkspace(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		apiKey       = httpmw.APIKey(r)
+		auditor      = api.Auditor.Load()
+		organization = httpmw.OrganizationParam(r)
+		member       = httpmw.OrganizationMemberParam(r)
+	)
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+		AdditionalFields: audit.AdditionalFields{
+			WorkspaceOwner: member.Username,
+		},
+		OrganizationID: organization.ID,
+	})
+	defer commitAudit()
+
+	var req codersdk.ImportWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	bundle := req.Bundle
+
+	if bundle.Version != codersdk.WorkspaceBundleVersion {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Unsupported workspace bundle version %d.", bundle.Version),
+			Detail:  fmt.Sprintf("This deployment imports bundles of version %d.", codersdk.WorkspaceBundleVersion),
+		})
+		return
+	}
+
+	name := cmp.Or(req.Name, bundle.Name)
+	if err := codersdk.NameValid(name); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid workspace name %q.", name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: err.Error(),
+			}},
+		})
+		return
+	}
+
+	var (
+		template    database.Template
+		templateRef = bundle.TemplateName
+		err         error
+	)
+	if req.TemplateID != uuid.Nil {
+		templateRef = req.TemplateID.String()
+		template, err = api.Database.GetTemplateByID(ctx, req.TemplateID)
+		if err == nil && template.OrganizationID != organization.ID {
+			err = sql.ErrNoRows
+		}
+	} else {
+		template, err = api.Database.GetTemplateByOrganizationAndName(ctx, database.GetTemplateByOrganizationAndNameParams{
+			OrganizationID: organization.ID,
+			Name:           bundle.TemplateName,
+		})
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template %q not found in organization %q.", templateRef, organization.Name),
+			Detail:  "Set template_id to import the workspace with another template.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	versionID, err := api.bundleTemplateVersion(ctx, template, bundle)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	versionParameters, err := api.Database.GetTemplateVersionParameters(ctx, versionID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defined := make(map[string]bool, len(versionParameters))
+	for _, parameter := range versionParameters {
+		defined[parameter.Name] = !parameter.Ephemeral
+	}
+	parameters := make([]codersdk.WorkspaceBuildParameter, 0, len(bundle.RichParameterValues))
+	for _, parameter := range bundle.RichParameterValues {
+		if defined[parameter.Name] {
+			parameters = append(parameters, parameter)
+		}
+	}
+
+	owner := workspaceOwner{
+		ID:        member.UserID,
+		Username:  member.Username,
+		AvatarURL: member.AvatarURL,
+	}
+	createWorkspace(ctx, aReq, apiKey.UserID, api, owner, codersdk.CreateWorkspaceRequest{
+		TemplateVersionID:   versionID,
+		Name:                name,
+		AutostartSchedule:   bundle.AutostartSchedule,
+		TTLMillis:           bundle.TTLMillis,
+		RichParameterValues: parameters,
+		AutomaticUpdates:    bundle.AutomaticUpdates,
+	}, req.ProvisionerState, rw, r)
+}
+
+// bundleTemplateVersion returns the version of the template to import a
+// workspace bundle with. The exported version is matched by ID, then by name,
+// and the active version is used if the template has neither or requires the
+// active version.
+func (api *API) bundleTemplateVersion(ctx context.Context, template database.Template, bundle codersdk.WorkspaceBundle) (uuid.UUID, error) {
+	if template.RequireActiveVersion {
+		return template.ActiveVersionID, nil
+	}
+
+	version, err := api.Database.GetTemplateVersionByID(ctx, bundle.TemplateVersionID)
+	if err == nil && version.TemplateID.UUID == template.ID && !version.Archived {
+		return version.ID, nil
+	}
+	if err != nil && !httpapi.Is404Error(err) {
+		return uuid.Nil, xerrors.Errorf("get template version by id: %w", err)
+	}
+
+	if bundle.TemplateVersionName != "" {
+		version, err = api.Database.GetTemplateVersionByTemplateIDAndName(ctx, database.GetTemplateVersionByTemplateIDAndNameParams{
+			TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
+			Name:       bundle.TemplateVersionName,
+		})
+		if err == nil && !version.Archived {
+			return version.ID, nil
+		}
+		if err != nil && !httpapi.Is404Error(err) {
+			return uuid.Nil, xerrors.Errorf("get template version by name: %w", err)
+		}
+	}
+
+	return template.ActiveVersionID, nil
+}
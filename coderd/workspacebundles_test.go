@@ -0,0 +1,139 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceExportImport(t *testing.T) {
+	t.Parallel()
+
+	responses := &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{
+						{
+							Name:         "region",
+							Type:         "string",
+							DefaultValue: "us",
+							Mutable:      true,
+							FormType:     proto.ParameterFormType_INPUT,
+						},
+						{
+							Name:         "reset",
+							Type:         "bool",
+							DefaultValue: "false",
+							Mutable:      true,
+							Ephemeral:    true,
+							FormType:     proto.ParameterFormType_CHECKBOX,
+						},
+					},
+				},
+			},
+		}},
+		ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+	}
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, responses)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{
+			{Name: "region", Value: "eu"},
+			{Name: "reset", Value: "true"},
+		}
+	})
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	bundle, err := client.ExportWorkspace(ctx, workspace.ID)
+	require.NoError(t, err)
+
+	t.Run("Export", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, codersdk.WorkspaceBundleVersion, bundle.Version)
+		require.Equal(t, workspace.Name, bundle.Name)
+		require.Equal(t, template.Name, bundle.TemplateName)
+		require.Equal(t, version.ID, bundle.TemplateVersionID)
+		require.Equal(t, version.Name, bundle.TemplateVersionName)
+		require.Equal(t, workspace.OrganizationName, bundle.OrganizationName)
+		// Ephemeral parameters only apply to a single build.
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}, bundle.RichParameterValues)
+		require.Len(t, bundle.Resources, 1)
+		require.Len(t, bundle.Resources[0].Agents, 1)
+		require.Equal(t, "example", bundle.Resources[0].Agents[0].Name)
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		imported, err := client.ImportWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.ImportWorkspaceRequest{
+			Bundle: bundle,
+			Name:   "imported",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "imported", imported.Name)
+		require.Equal(t, template.ID, imported.TemplateID)
+		require.Equal(t, version.ID, imported.LatestBuild.TemplateVersionID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, imported.LatestBuild.ID)
+
+		params, err := client.WorkspaceBuildParameters(ctx, imported.LatestBuild.ID)
+		require.NoError(t, err)
+		require.Contains(t, params, codersdk.WorkspaceBuildParameter{Name: "region", Value: "eu"})
+	})
+
+	t.Run("TemplateNotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		notFound := bundle
+		notFound.TemplateName = "missing"
+		_, err := client.ImportWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.ImportWorkspaceRequest{
+			Bundle: notFound,
+			Name:   "missing-template",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// The template can be chosen explicitly instead.
+		imported, err := client.ImportWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.ImportWorkspaceRequest{
+			Bundle:     notFound,
+			Name:       "explicit-template",
+			TemplateID: template.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, template.ID, imported.TemplateID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, imported.LatestBuild.ID)
+	})
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		unsupported := bundle
+		unsupported.Version = codersdk.WorkspaceBundleVersion + 1
+		_, err := client.ImportWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.ImportWorkspaceRequest{
+			Bundle: unsupported,
+			Name:   "unsupported",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
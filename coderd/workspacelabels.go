@@ -0,0 +1,145 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	workspaceLabelKeyMaxLength   = 63
+	workspaceLabelValueMaxLength = 255
+)
+
+// Label keys are lowercase, since workspace search queries are case
+// insensitive. They can't contain ':' or '=', which delimit search terms.
+var workspaceLabelKeyRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9._/-]*[a-z0-9])?$`)
+
+// @Summary Get workspace labels
+// @ID get-workspace-labels
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceLabels
+// @Router /workspaces/{workspace}/labels [get]
+func (api *API) workspaceLabels(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	labels, err := api.Database.GetWorkspaceLabelsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace labels.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := codersdk.WorkspaceLabels{Labels: make(map[string]string, len(labels))}
+	for _, label := range labels {
+		converted.Labels[label.Key] = label.Value
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Update workspace labels
+// @Description Replaces the labels of a workspace. Workspaces can be filtered
+// @Description by label with the `label:<key>[=<value>]` search query.
+// @ID update-workspace-labels
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceLabelsRequest true "Update workspace labels request"
+// @Success 204
+// @Router /workspaces/{workspace}/labels [put]
+func (api *API) putWorkspaceLabels(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.UpdateWorkspaceLabelsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if validations := validateWorkspaceLabels(req.Labels); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace labels.",
+			Validations: validations,
+		})
+		return
+	}
+
+	keys := maps.Keys(req.Labels)
+	slices.Sort(keys)
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, req.Labels[key])
+	}
+	err := api.Database.InTx(func(tx database.Store) error {
+		if err := tx.DeleteWorkspaceLabelsByWorkspaceID(ctx, workspace.ID); err != nil {
+			return xerrors.Errorf("delete workspace labels: %w", err)
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		if err := tx.InsertWorkspaceLabels(ctx, database.InsertWorkspaceLabelsParams{
+			WorkspaceID: workspace.ID,
+			Keys:        keys,
+			Values:      values,
+			CreatedAt:   dbtime.Now(),
+		}); err != nil {
+			return xerrors.Errorf("insert workspace labels: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace labels.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func validateWorkspaceLabels(labels map[string]string) []codersdk.ValidationError {
+	if len(labels) > codersdk.MaxWorkspaceLabels {
+		return []codersdk.ValidationError{{
+			Field:  "labels",
+			Detail: fmt.Sprintf("A workspace can have at most %d labels.", codersdk.MaxWorkspaceLabels),
+		}}
+	}
+	var validations []codersdk.ValidationError
+	for key, value := range labels {
+		if len(key) > workspaceLabelKeyMaxLength || !workspaceLabelKeyRegex.MatchString(key) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "labels",
+				Detail: fmt.Sprintf("Label key %q must be at most %d lowercase alphanumeric characters, and may contain '.', '_', '/' and '-' in between.", key, workspaceLabelKeyMaxLength),
+			})
+		}
+		if len(value) > workspaceLabelValueMaxLength {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "labels",
+				Detail: fmt.Sprintf("The value of label %q must be at most %d characters.", key, workspaceLabelValueMaxLength),
+			})
+		}
+	}
+	return validations
+}
@@ -0,0 +1,105 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceLabels(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	payments := coderdtest.CreateWorkspace(t, client, template.ID)
+	search := coderdtest.CreateWorkspace(t, client, template.ID)
+	unlabeled := coderdtest.CreateWorkspace(t, client, template.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	err := client.UpdateWorkspaceLabels(ctx, payments.ID, codersdk.UpdateWorkspaceLabelsRequest{
+		Labels: map[string]string{"team": "Payments", "oncall": "alice"},
+	})
+	require.NoError(t, err)
+	err = client.UpdateWorkspaceLabels(ctx, search.ID, codersdk.UpdateWorkspaceLabelsRequest{
+		Labels: map[string]string{"team": "search"},
+	})
+	require.NoError(t, err)
+
+	t.Run("Get", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		labels, err := client.WorkspaceLabels(ctx, payments.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"team": "Payments", "oncall": "alice"}, labels.Labels)
+
+		labels, err = client.WorkspaceLabels(ctx, unlabeled.ID)
+		require.NoError(t, err)
+		require.Empty(t, labels.Labels)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Values are matched case insensitively.
+		res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "label:team=payments"})
+		require.NoError(t, err)
+		require.Len(t, res.Workspaces, 1)
+		require.Equal(t, payments.ID, res.Workspaces[0].ID)
+
+		res, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "label:team"})
+		require.NoError(t, err)
+		require.Len(t, res.Workspaces, 2)
+
+		res, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "label:team label:oncall=alice"})
+		require.NoError(t, err)
+		require.Len(t, res.Workspaces, 1)
+		require.Equal(t, payments.ID, res.Workspaces[0].ID)
+
+		res, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "label:team=billing"})
+		require.NoError(t, err)
+		require.Empty(t, res.Workspaces)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		err := client.UpdateWorkspaceLabels(ctx, workspace.ID, codersdk.UpdateWorkspaceLabelsRequest{
+			Labels: map[string]string{"env": "dev", "cost-center": "42"},
+		})
+		require.NoError(t, err)
+		err = client.UpdateWorkspaceLabels(ctx, workspace.ID, codersdk.UpdateWorkspaceLabelsRequest{
+			Labels: map[string]string{"env": "prod"},
+		})
+		require.NoError(t, err)
+
+		labels, err := client.WorkspaceLabels(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod"}, labels.Labels)
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, key := range []string{"Team", "team=payments", "-team", ""} {
+			err := client.UpdateWorkspaceLabels(ctx, unlabeled.ID, codersdk.UpdateWorkspaceLabelsRequest{
+				Labels: map[string]string{key: "payments"},
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, key)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), key)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Lock workspace
+// @Description Prevents any new builds of a workspace, including autostart,
+// @Description autostop and other automatic builds, until it is unlocked.
+// @Description Builds that are already running are not canceled. Locking
+// @Description requires permission to update the template of the workspace.
+// @ID lock-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.LockWorkspaceRequest true "Lock workspace request"
+// @Success 201 {object} codersdk.WorkspaceLock
+// @Router /workspaces/{workspace}/locks [post]
+func (api *API) postWorkspaceLock(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	var req codersdk.LockWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	lock, err := api.Database.InsertWorkspaceLock(ctx, database.InsertWorkspaceLockParams{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+		Reason:      req.Reason,
+		LockedBy:    apiKey.UserID,
+		LockedAt:    dbtime.Now(),
+	})
+	if database.IsUniqueViolation(err, database.UniqueWorkspaceLocksWorkspaceIDActiveIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The workspace is already locked.",
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template managers may lock workspaces.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error locking workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWorkspaceLock(lock))
+}
+
+// @Summary Unlock workspace
+// @Description Lifts the lock of a workspace and returns it. Unlocking
+// @Description requires permission to update the template of the workspace.
+// @ID unlock-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceLock
+// @Router /workspaces/{workspace}/locks [delete]
+func (api *API) deleteWorkspaceLock(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	lock, err := api.Database.UnlockWorkspace(ctx, database.UnlockWorkspaceParams{
+		UnlockedBy:  uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+		UnlockedAt:  sql.NullTime{Time: dbtime.Now(), Valid: true},
+		WorkspaceID: workspace.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The workspace is not locked.",
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template managers may unlock workspaces.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error unlocking workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceLock(lock))
+}
+
+// @Summary Get workspace locks
+// @Description Returns the current and lifted locks of a workspace, newest
+// @Description first.
+// @ID get-workspace-locks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceLock
+// @Router /workspaces/{workspace}/locks [get]
+func (api *API) workspaceLocks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	locks, err := api.Database.GetWorkspaceLocksByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace locks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.WorkspaceLock, 0, len(locks))
+	for _, lock := range locks {
+		converted = append(converted, convertWorkspaceLock(lock))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+func convertWorkspaceLock(lock database.WorkspaceLock) codersdk.WorkspaceLock {
+	converted := codersdk.WorkspaceLock{
+		ID:          lock.ID,
+		WorkspaceID: lock.WorkspaceID,
+		Reason:      lock.Reason,
+		LockedBy:    lock.LockedBy,
+		LockedAt:    lock.LockedAt,
+	}
+	if lock.UnlockedBy.Valid {
+		converted.UnlockedBy = &lock.UnlockedBy.UUID
+	}
+	if lock.UnlockedAt.Valid {
+		converted.UnlockedAt = &lock.UnlockedAt.Time
+	}
+	return converted
+}
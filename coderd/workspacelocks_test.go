@@ -0,0 +1,108 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceLocks(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		lock, err := client.LockWorkspace(ctx, workspace.ID, codersdk.LockWorkspaceRequest{Reason: "incident 42"})
+		require.NoError(t, err)
+		require.Equal(t, "incident 42", lock.Reason)
+		require.Equal(t, user.UserID, lock.LockedBy)
+		require.Nil(t, lock.UnlockedAt)
+
+		// A workspace can only have one lock at a time.
+		_, err = client.LockWorkspace(ctx, workspace.ID, codersdk.LockWorkspaceRequest{Reason: "again"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// Builds are rejected for everyone while the workspace is locked.
+		for _, c := range []*codersdk.Client{member, client} {
+			_, err = c.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+				Transition: codersdk.WorkspaceTransitionStop,
+			})
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+			require.Contains(t, apiErr.Message, "incident 42")
+		}
+
+		// The owner of the workspace can't lift the lock.
+		_, err = member.UnlockWorkspace(ctx, workspace.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		unlocked, err := client.UnlockWorkspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, lock.ID, unlocked.ID)
+		require.NotNil(t, unlocked.UnlockedBy)
+		require.Equal(t, user.UserID, *unlocked.UnlockedBy)
+		require.NotNil(t, unlocked.UnlockedAt)
+
+		build, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+
+		// The lifted lock is kept, and the owner can read it.
+		locks, err := member.WorkspaceLocks(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, locks, 1)
+		require.Equal(t, lock.ID, locks[0].ID)
+		require.NotNil(t, locks[0].UnlockedAt)
+	})
+
+	t.Run("TemplateManagerOnly", func(t *testing.T) {
+		t.Parallel()
+
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := member.LockWorkspace(ctx, workspace.ID, codersdk.LockWorkspaceRequest{Reason: "mine"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		locks, err := member.WorkspaceLocks(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, locks)
+	})
+
+	t.Run("NotLocked", func(t *testing.T) {
+		t.Parallel()
+
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.UnlockWorkspace(ctx, workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
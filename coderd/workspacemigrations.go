@@ -0,0 +1,501 @@
+package coderd
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// workspaceMigrationInterval is how often active workspace migrations are
+	// advanced. Like the build queue, migrations are advanced by whichever
+	// replica notices first.
+	workspaceMigrationInterval = 5 * time.Second
+	// workspaceMigrationRequestTimeout bounds each request made to the
+	// destination deployment.
+	workspaceMigrationRequestTimeout = 30 * time.Second
+	// workspaceMigrationStartTimeout is how long a migration waits for the
+	// workspace to start on the destination before it fails.
+	workspaceMigrationStartTimeout = time.Hour
+)
+
+// @Summary Migrate workspace to another deployment
+// @Description Starts migrating a workspace, including its provisioner state,
+// @Description to another deployment. The workspace is stopped, imported on
+// @Description the destination with the given session token and started
+// @Description there. Once it has started on the destination, the workspace
+// @Description is deleted from this deployment without destroying its
+// @Description resources. Other builds of the workspace are rejected while
+// @Description it is being migrated.
+// @ID migrate-workspace-to-another-deployment
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceMigrationRequest true "Create workspace migration request"
+// @Success 201 {object} codersdk.WorkspaceMigration
+// @Router /workspaces/{workspace}/migrations [post]
+func (api *API) postWorkspaceMigration(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	var req codersdk.CreateWorkspaceMigrationRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// The provisioner state of the workspace is handed to the destination,
+	// which is restricted to template managers like pulling the state.
+	if !api.Authorize(r, policy.ActionUpdate, template.RBACObject()) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template managers may migrate workspaces.",
+			Detail:  "The provisioner state of the workspace is transferred to the destination deployment.",
+		})
+		return
+	}
+
+	destinationURL, err := url.Parse(req.DestinationURL)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid destination URL.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "destination_url",
+				Detail: err.Error(),
+			}},
+		})
+		return
+	}
+	// Fail early if the destination can't be reached with the token, rather
+	// than after the workspace has been stopped.
+	destination := codersdk.New(destinationURL)
+	destination.SetSessionToken(req.SessionToken)
+	destinationCtx, cancel := context.WithTimeout(ctx, workspaceMigrationRequestTimeout)
+	defer cancel()
+	if _, err := destination.Organization(destinationCtx, req.OrganizationID); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Unable to fetch the organization from the destination deployment.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	migration, err := api.Database.InsertWorkspaceMigration(ctx, database.InsertWorkspaceMigrationParams{
+		ID:                        uuid.New(),
+		WorkspaceID:               workspace.ID,
+		InitiatorID:               apiKey.UserID,
+		DestinationURL:            destinationURL.String(),
+		DestinationOrganizationID: req.OrganizationID,
+		DestinationOwner:          cmp.Or(req.Owner, codersdk.Me),
+		DestinationTemplateID:     uuid.NullUUID{UUID: req.TemplateID, Valid: req.TemplateID != uuid.Nil},
+		DestinationName:           req.Name,
+		DestinationSessionToken:   req.SessionToken,
+		CreatedAt:                 now,
+		UpdatedAt:                 now,
+	})
+	if database.IsUniqueViolation(err, database.UniqueWorkspaceMigrationsWorkspaceIDActiveIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The workspace is already being migrated.",
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating workspace migration.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWorkspaceMigration(migration))
+}
+
+// @Summary Get workspace migrations
+// @Description Returns the migrations of a workspace to other deployments,
+// @Description newest first.
+// @ID get-workspace-migrations
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceMigration
+// @Router /workspaces/{workspace}/migrations [get]
+func (api *API) workspaceMigrations(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	migrations, err := api.Database.GetWorkspaceMigrationsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace migrations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.WorkspaceMigration, 0, len(migrations))
+	for _, migration := range migrations {
+		converted = append(converted, convertWorkspaceMigration(migration))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// runWorkspaceMigrations advances active workspace migrations until ctx is
+// canceled.
+func (api *API) runWorkspaceMigrations(ctx context.Context) {
+	defer close(api.workspaceMigrationsDone)
+	//nolint:gocritic // The system migrates workspaces on behalf of their initiators.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	ticker := api.Clock.NewTicker(workspaceMigrationInterval, "workspace_migrations")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if api.ReadOnlyMode.Enabled() {
+			continue
+		}
+
+		migrations, err := api.Database.GetActiveWorkspaceMigrations(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				api.Logger.Error(ctx, "failed to fetch active workspace migrations", slog.Error(err))
+			}
+			continue
+		}
+		for _, migration := range migrations {
+			api.advanceWorkspaceMigration(ctx, migration)
+		}
+	}
+}
+
+// workspaceMigrationFailure fails a migration. The message is shown to the
+// user, other errors are logged and the step is retried.
+type workspaceMigrationFailure struct {
+	message string
+}
+
+func (f workspaceMigrationFailure) Error() string {
+	return f.message
+}
+
+func (api *API) advanceWorkspaceMigration(ctx context.Context, migration database.WorkspaceMigration) {
+	logger := api.Logger.With(slog.F("workspace_id", migration.WorkspaceID), slog.F("workspace_migration_id", migration.ID))
+
+	var (
+		workspace database.Workspace
+		job       *database.ProvisionerJob
+	)
+	err := api.Database.InTx(func(tx database.Store) error {
+		// Every replica advances migrations, the lock ensures only one of
+		// them imports the workspace on the destination.
+		ok, err := tx.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("workspace-migration:%s", migration.ID)))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+		// The migration may have advanced since it was fetched.
+		migration, err = tx.GetWorkspaceMigrationByID(ctx, migration.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace migration: %w", err)
+		}
+		if migration.CompletedAt.Valid {
+			return nil
+		}
+		workspace, err = tx.GetWorkspaceByID(ctx, migration.WorkspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+		if workspace.Deleted {
+			return workspaceMigrationFailure{message: "The workspace was deleted."}
+		}
+
+		switch migration.Status {
+		case database.WorkspaceMigrationStatusPending:
+			job, err = api.stopMigratingWorkspace(ctx, tx, migration, workspace)
+		case database.WorkspaceMigrationStatusStopping:
+			err = api.importMigratingWorkspace(ctx, tx, migration, workspace)
+		case database.WorkspaceMigrationStatusStarting:
+			job, err = api.awaitMigratedWorkspace(ctx, tx, migration, workspace)
+		}
+		return err
+	}, nil)
+	var failure workspaceMigrationFailure
+	if errors.As(err, &failure) {
+		logger.Warn(ctx, "workspace migration failed", slog.F("reason", failure.message))
+		_, err = api.Database.CompleteWorkspaceMigration(ctx, database.CompleteWorkspaceMigrationParams{
+			ID:          migration.ID,
+			Status:      database.WorkspaceMigrationStatusFailed,
+			Error:       failure.message,
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		if err != nil {
+			logger.Error(ctx, "failed to mark workspace migration as failed", slog.Error(err))
+		}
+		return
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error(ctx, "failed to advance workspace migration, will retry", slog.Error(err))
+		}
+		return
+	}
+	if job == nil {
+		return
+	}
+
+	if err := provisionerjobs.PostJob(api.Pubsub, *job); err != nil {
+		logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+}
+
+// stopMigratingWorkspace stops the workspace so that its provisioner state
+// no longer changes. Workspaces that are already stopped are not built again.
+func (api *API) stopMigratingWorkspace(ctx context.Context, tx database.Store, migration database.WorkspaceMigration, workspace database.Workspace) (*database.ProvisionerJob, error) {
+	lastBuild, lastJob, err := latestWorkspaceBuildAndJob(ctx, tx, workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Builds are rejected while the workspace is being migrated, so this
+	// build was started before the migration was requested.
+	if codersdk.ProvisionerJobStatus(lastJob.JobStatus).Active() {
+		return nil, nil
+	}
+
+	if lastBuild.Transition == database.WorkspaceTransitionStop && lastJob.JobStatus == database.ProvisionerJobStatusSucceeded {
+		_, err = tx.UpdateWorkspaceMigrationStatus(ctx, database.UpdateWorkspaceMigrationStatusParams{
+			ID:            migration.ID,
+			Status:        database.WorkspaceMigrationStatusStopping,
+			SourceBuildID: uuid.NullUUID{UUID: lastBuild.ID, Valid: true},
+			UpdatedAt:     dbtime.Now(),
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("update workspace migration: %w", err)
+		}
+		return nil, nil
+	}
+
+	build, job, err := buildMigratingWorkspace(ctx, tx, api, migration, workspace, wsbuilder.New(workspace, database.WorkspaceTransitionStop))
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.UpdateWorkspaceMigrationStatus(ctx, database.UpdateWorkspaceMigrationStatusParams{
+		ID:            migration.ID,
+		Status:        database.WorkspaceMigrationStatusStopping,
+		SourceBuildID: uuid.NullUUID{UUID: build.ID, Valid: true},
+		UpdatedAt:     dbtime.Now(),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("update workspace migration: %w", err)
+	}
+	return job, nil
+}
+
+// importMigratingWorkspace imports the stopped workspace with its
+// provisioner state on the destination, which starts it there.
+func (api *API) importMigratingWorkspace(ctx context.Context, tx database.Store, migration database.WorkspaceMigration, workspace database.Workspace) error {
+	lastBuild, lastJob, err := latestWorkspaceBuildAndJob(ctx, tx, workspace.ID)
+	if err != nil {
+		return err
+	}
+	if lastBuild.ID != migration.SourceBuildID.UUID {
+		return workspaceMigrationFailure{message: "The workspace was built while it was being migrated."}
+	}
+	switch status := codersdk.ProvisionerJobStatus(lastJob.JobStatus); {
+	case status.Active():
+		return nil
+	case status != codersdk.ProvisionerJobSucceeded:
+		return workspaceMigrationFailure{message: fmt.Sprintf("Stopping the workspace %s: %s", status, lastJob.Error.String)}
+	}
+
+	bundle, err := workspaceBundle(ctx, tx, workspace)
+	if err != nil {
+		return xerrors.Errorf("export workspace: %w", err)
+	}
+	destination, err := workspaceMigrationDestination(migration)
+	if err != nil {
+		return workspaceMigrationFailure{message: err.Error()}
+	}
+	requestCtx, cancel := context.WithTimeout(ctx, workspaceMigrationRequestTimeout)
+	defer cancel()
+	// The request is not retried, since it may have created the workspace on
+	// the destination even if it failed.
+	imported, err := destination.ImportWorkspace(requestCtx, migration.DestinationOrganizationID, migration.DestinationOwner, codersdk.ImportWorkspaceRequest{
+		Bundle:           bundle,
+		Name:             migration.DestinationName,
+		TemplateID:       migration.DestinationTemplateID.UUID,
+		ProvisionerState: lastBuild.ProvisionerState,
+	})
+	if err != nil {
+		return workspaceMigrationFailure{message: fmt.Sprintf("Importing the workspace on the destination: %s", err)}
+	}
+
+	_, err = tx.UpdateWorkspaceMigrationStatus(ctx, database.UpdateWorkspaceMigrationStatusParams{
+		ID:                     migration.ID,
+		Status:                 database.WorkspaceMigrationStatusStarting,
+		SourceBuildID:          migration.SourceBuildID,
+		DestinationWorkspaceID: uuid.NullUUID{UUID: imported.ID, Valid: true},
+		DestinationBuildID:     uuid.NullUUID{UUID: imported.LatestBuild.ID, Valid: true},
+		UpdatedAt:              dbtime.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("update workspace migration: %w", err)
+	}
+	return nil
+}
+
+// awaitMigratedWorkspace waits for the workspace to start on the destination,
+// then deletes it from this deployment without destroying its resources,
+// which now belong to the destination.
+func (api *API) awaitMigratedWorkspace(ctx context.Context, tx database.Store, migration database.WorkspaceMigration, workspace database.Workspace) (*database.ProvisionerJob, error) {
+	destination, err := workspaceMigrationDestination(migration)
+	if err != nil {
+		return nil, workspaceMigrationFailure{message: err.Error()}
+	}
+	requestCtx, cancel := context.WithTimeout(ctx, workspaceMigrationRequestTimeout)
+	defer cancel()
+	build, err := destination.WorkspaceBuild(requestCtx, migration.DestinationBuildID.UUID)
+	if err != nil {
+		if dbtime.Now().Sub(migration.UpdatedAt) > workspaceMigrationStartTimeout {
+			return nil, workspaceMigrationFailure{message: fmt.Sprintf("Timed out fetching the build of the workspace from the destination: %s", err)}
+		}
+		return nil, xerrors.Errorf("get destination workspace build: %w", err)
+	}
+	switch build.Job.Status {
+	case codersdk.ProvisionerJobSucceeded:
+	case codersdk.ProvisionerJobPending, codersdk.ProvisionerJobRunning, codersdk.ProvisionerJobCanceling:
+		if dbtime.Now().Sub(migration.UpdatedAt) > workspaceMigrationStartTimeout {
+			return nil, workspaceMigrationFailure{message: "Timed out waiting for the workspace to start on the destination."}
+		}
+		return nil, nil
+	default:
+		return nil, workspaceMigrationFailure{message: fmt.Sprintf("Starting the workspace on the destination %s: %s", build.Job.Status, build.Job.Error)}
+	}
+
+	_, job, err := buildMigratingWorkspace(ctx, tx, api, migration, workspace, wsbuilder.New(workspace, database.WorkspaceTransitionDelete).Orphan())
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.CompleteWorkspaceMigration(ctx, database.CompleteWorkspaceMigrationParams{
+		ID:          migration.ID,
+		Status:      database.WorkspaceMigrationStatusSucceeded,
+		CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("complete workspace migration: %w", err)
+	}
+	return job, nil
+}
+
+// buildMigratingWorkspace builds the workspace on behalf of the initiator of
+// the migration. Pre-flight checks are skipped, since they reject builds of
+// workspaces that are being migrated.
+func buildMigratingWorkspace(ctx context.Context, tx database.Store, api *API, migration database.WorkspaceMigration, workspace database.Workspace, builder wsbuilder.Builder) (*database.WorkspaceBuild, *database.ProvisionerJob, error) {
+	// The initiator was authorized when the migration was requested.
+	builder = builder.
+		Initiator(migration.InitiatorID).
+		InitiatorContext(database.BuildInitiatorContext{
+			Automation: string(codersdk.BuildAutomationWorkspaceMigration),
+		}).
+		DeploymentValues(api.Options.DeploymentValues).
+		Experiments(api.Experiments)
+	build, job, _, err := builder.Build(ctx, tx, api.FileCache, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+	var buildErr wsbuilder.BuildError
+	if errors.As(err, &buildErr) && buildErr.Status < http.StatusInternalServerError {
+		return nil, nil, workspaceMigrationFailure{message: fmt.Sprintf("Building workspace %q: %s", workspace.Name, buildErr.Message)}
+	}
+	if err != nil {
+		return nil, nil, xerrors.Errorf("build workspace: %w", err)
+	}
+	return build, job, nil
+}
+
+func latestWorkspaceBuildAndJob(ctx context.Context, db database.Store, workspaceID uuid.UUID) (database.WorkspaceBuild, database.ProvisionerJob, error) {
+	build, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, xerrors.Errorf("get latest workspace build: %w", err)
+	}
+	job, err := db.GetProvisionerJobByID(ctx, build.JobID)
+	if err != nil {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, xerrors.Errorf("get provisioner job: %w", err)
+	}
+	return build, job, nil
+}
+
+func workspaceMigrationDestination(migration database.WorkspaceMigration) (*codersdk.Client, error) {
+	destinationURL, err := url.Parse(migration.DestinationURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse destination URL: %w", err)
+	}
+	client := codersdk.New(destinationURL)
+	client.SetSessionToken(migration.DestinationSessionToken)
+	return client, nil
+}
+
+func convertWorkspaceMigration(migration database.WorkspaceMigration) codersdk.WorkspaceMigration {
+	converted := codersdk.WorkspaceMigration{
+		ID:                        migration.ID,
+		WorkspaceID:               migration.WorkspaceID,
+		InitiatorID:               migration.InitiatorID,
+		Status:                    codersdk.WorkspaceMigrationStatus(migration.Status),
+		DestinationURL:            migration.DestinationURL,
+		DestinationOrganizationID: migration.DestinationOrganizationID,
+		Error:                     migration.Error,
+		CreatedAt:                 migration.CreatedAt,
+		UpdatedAt:                 migration.UpdatedAt,
+	}
+	if migration.DestinationWorkspaceID.Valid {
+		converted.DestinationWorkspaceID = &migration.DestinationWorkspaceID.UUID
+	}
+	if migration.CompletedAt.Valid {
+		converted.CompletedAt = &migration.CompletedAt.Time
+	}
+	return converted
+}
@@ -0,0 +1,158 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceMigration(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		source := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		sourceUser := coderdtest.CreateFirstUser(t, source)
+		sourceVersion := coderdtest.CreateTemplateVersion(t, source, sourceUser.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, source, sourceVersion.ID)
+		sourceTemplate := coderdtest.CreateTemplate(t, source, sourceUser.OrganizationID, sourceVersion.ID)
+		workspace := coderdtest.CreateWorkspace(t, source, sourceTemplate.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, source, workspace.LatestBuild.ID)
+
+		destination := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		destinationUser := coderdtest.CreateFirstUser(t, destination)
+		destinationVersion := coderdtest.CreateTemplateVersion(t, destination, destinationUser.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, destination, destinationVersion.ID)
+		destinationTemplate := coderdtest.CreateTemplate(t, destination, destinationUser.OrganizationID, destinationVersion.ID)
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		migration, err := source.CreateWorkspaceMigration(ctx, workspace.ID, codersdk.CreateWorkspaceMigrationRequest{
+			DestinationURL: destination.URL.String(),
+			SessionToken:   destination.SessionToken(),
+			OrganizationID: destinationUser.OrganizationID,
+			TemplateID:     destinationTemplate.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceMigrationStatusPending, migration.Status)
+
+		// Only one migration of a workspace may be active.
+		_, err = source.CreateWorkspaceMigration(ctx, workspace.ID, codersdk.CreateWorkspaceMigrationRequest{
+			DestinationURL: destination.URL.String(),
+			SessionToken:   destination.SessionToken(),
+			OrganizationID: destinationUser.OrganizationID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		require.Eventually(t, func() bool {
+			migrations, err := source.WorkspaceMigrations(ctx, workspace.ID)
+			if err != nil || len(migrations) != 1 {
+				return false
+			}
+			migration = migrations[0]
+			return migration.CompletedAt != nil
+		}, testutil.WaitSuperLong, testutil.IntervalMedium)
+		require.Equal(t, codersdk.WorkspaceMigrationStatusSucceeded, migration.Status, migration.Error)
+		require.NotNil(t, migration.DestinationWorkspaceID)
+
+		migrated, err := destination.Workspace(ctx, *migration.DestinationWorkspaceID)
+		require.NoError(t, err)
+		require.Equal(t, workspace.Name, migrated.Name)
+		require.Equal(t, destinationTemplate.ID, migrated.TemplateID)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, migrated.LatestBuild.Transition)
+
+		// The workspace is deleted from the source without destroying its
+		// resources.
+		deleted, err := source.DeletedWorkspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, source, deleted.LatestBuild.ID)
+	})
+
+	t.Run("BuildRejected", func(t *testing.T) {
+		t.Parallel()
+
+		source := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		sourceUser := coderdtest.CreateFirstUser(t, source)
+		version := coderdtest.CreateTemplateVersion(t, source, sourceUser.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, source, version.ID)
+		template := coderdtest.CreateTemplate(t, source, sourceUser.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, source, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, source, workspace.LatestBuild.ID)
+		// The destination has no provisioner, so the migration stays active.
+		destination := coderdtest.New(t, nil)
+		destinationUser := coderdtest.CreateFirstUser(t, destination)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := source.CreateWorkspaceMigration(ctx, workspace.ID, codersdk.CreateWorkspaceMigrationRequest{
+			DestinationURL: destination.URL.String(),
+			SessionToken:   destination.SessionToken(),
+			OrganizationID: destinationUser.OrganizationID,
+		})
+		require.NoError(t, err)
+
+		_, err = source.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("TemplateManagerOnly", func(t *testing.T) {
+		t.Parallel()
+
+		source := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		sourceUser := coderdtest.CreateFirstUser(t, source)
+		member, _ := coderdtest.CreateAnotherUser(t, source, sourceUser.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, source, sourceUser.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, source, version.ID)
+		template := coderdtest.CreateTemplate(t, source, sourceUser.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := member.CreateWorkspaceMigration(ctx, workspace.ID, codersdk.CreateWorkspaceMigrationRequest{
+			DestinationURL: source.URL.String(),
+			SessionToken:   member.SessionToken(),
+			OrganizationID: sourceUser.OrganizationID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidDestination", func(t *testing.T) {
+		t.Parallel()
+
+		source := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		sourceUser := coderdtest.CreateFirstUser(t, source)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, source, sourceUser.OrganizationID, rbac.RoleTemplateAdmin())
+		version := coderdtest.CreateTemplateVersion(t, source, sourceUser.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, source, version.ID)
+		template := coderdtest.CreateTemplate(t, source, sourceUser.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, templateAdmin, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, templateAdmin, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := templateAdmin.CreateWorkspaceMigration(ctx, workspace.ID, codersdk.CreateWorkspaceMigrationRequest{
+			DestinationURL: source.URL.String(),
+			SessionToken:   "invalid",
+			OrganizationID: sourceUser.OrganizationID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,175 @@
+// Package workspacenaming enforces the workspace naming policies configured
+// for organizations and templates.
+package workspacenaming
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Input is the data available to the prefix and suffix templates of a
+// policy.
+type Input struct {
+	Username         string
+	OrganizationName string
+	TemplateName     string
+	// CostCenter is the cost center of the organization. It is empty if the
+	// organization doesn't have one.
+	CostCenter string
+}
+
+// FromDatabase converts a stored policy to its API representation.
+func FromDatabase(p database.WorkspaceNamingPolicy) codersdk.WorkspaceNamingPolicy {
+	scope := codersdk.WorkspaceNameUniquenessScope(p.UniquenessScope)
+	if scope == "" {
+		// Organizations and templates without a stored policy use the
+		// default scope.
+		scope = codersdk.WorkspaceNameUniquenessScopeOwner
+	}
+	return codersdk.WorkspaceNamingPolicy{
+		Regex:           p.Regex,
+		Prefix:          p.Prefix,
+		Suffix:          p.Suffix,
+		UniquenessScope: scope,
+	}
+}
+
+// IsZero returns true if the policy does not restrict workspace names beyond
+// the default behavior.
+func IsZero(p codersdk.WorkspaceNamingPolicy) bool {
+	return p.Regex == "" && p.Prefix == "" && p.Suffix == "" &&
+		(p.UniquenessScope == "" || p.UniquenessScope == codersdk.WorkspaceNameUniquenessScopeOwner)
+}
+
+// Resolve returns the policy that applies to a workspace created from a
+// template. The template policy replaces the organization policy entirely
+// when it is set.
+func Resolve(organization, template codersdk.WorkspaceNamingPolicy) codersdk.WorkspaceNamingPolicy {
+	if !IsZero(template) {
+		return template
+	}
+	return organization
+}
+
+// Validate checks that a policy can be enforced. The returned validation
+// errors are suitable for an API response.
+func Validate(p codersdk.WorkspaceNamingPolicy) []codersdk.ValidationError {
+	var validations []codersdk.ValidationError
+	if p.Regex != "" {
+		if _, err := regexp.Compile(p.Regex); err != nil {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "regex",
+				Detail: fmt.Sprintf("Invalid regular expression: %s", err.Error()),
+			})
+		}
+	}
+	// Render against placeholder input so templates referencing unknown
+	// fields are rejected up front instead of at workspace creation.
+	placeholder := Input{Username: "user", OrganizationName: "org", TemplateName: "template", CostCenter: "cc"}
+	if _, err := render(p.Prefix, placeholder); err != nil {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "prefix",
+			Detail: fmt.Sprintf("Invalid template: %s", err.Error()),
+		})
+	}
+	if _, err := render(p.Suffix, placeholder); err != nil {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "suffix",
+			Detail: fmt.Sprintf("Invalid template: %s", err.Error()),
+		})
+	}
+	switch p.UniquenessScope {
+	case "", codersdk.WorkspaceNameUniquenessScopeOwner, codersdk.WorkspaceNameUniquenessScopeOrganization:
+	default:
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "uniqueness_scope",
+			Detail: fmt.Sprintf("Must be one of %q or %q.", codersdk.WorkspaceNameUniquenessScopeOwner, codersdk.WorkspaceNameUniquenessScopeOrganization),
+		})
+	}
+	return validations
+}
+
+// Check returns a validation error for every rule of the policy the
+// workspace name violates. Uniqueness is not checked here as it requires a
+// database lookup.
+func Check(p codersdk.WorkspaceNamingPolicy, name string, in Input) ([]codersdk.ValidationError, error) {
+	var validations []codersdk.ValidationError
+	// Workspace names are compared case-insensitively everywhere else.
+	lowerName := strings.ToLower(name)
+
+	// An empty cost center would silently relax the prefix or suffix, so
+	// names are rejected until the organization has one.
+	if in.CostCenter == "" && (UsesCostCenter(p.Prefix) || UsesCostCenter(p.Suffix)) {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "name",
+			Detail: "The naming policy requires a cost center, but the organization doesn't have one.",
+		})
+		return validations, nil
+	}
+
+	prefix, err := render(p.Prefix, in)
+	if err != nil {
+		return nil, xerrors.Errorf("render prefix: %w", err)
+	}
+	if prefix != "" && !strings.HasPrefix(lowerName, prefix) {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "name",
+			Detail: fmt.Sprintf("Workspace name must start with %q.", prefix),
+		})
+	}
+
+	suffix, err := render(p.Suffix, in)
+	if err != nil {
+		return nil, xerrors.Errorf("render suffix: %w", err)
+	}
+	if suffix != "" && !strings.HasSuffix(lowerName, suffix) {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "name",
+			Detail: fmt.Sprintf("Workspace name must end with %q.", suffix),
+		})
+	}
+
+	if p.Regex != "" {
+		// Anchor the expression so it must match the full name.
+		re, err := regexp.Compile(`^(?:` + p.Regex + `)$`)
+		if err != nil {
+			return nil, xerrors.Errorf("compile regex: %w", err)
+		}
+		if !re.MatchString(name) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "name",
+				Detail: fmt.Sprintf("Workspace name must match the regular expression %q.", p.Regex),
+			})
+		}
+	}
+
+	return validations, nil
+}
+
+// UsesCostCenter returns true if a prefix or suffix template references the
+// cost center.
+func UsesCostCenter(text string) bool {
+	return strings.Contains(text, ".CostCenter")
+}
+
+func render(text string, in Input) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, in); err != nil {
+		return "", err
+	}
+	return strings.ToLower(sb.String()), nil
+}
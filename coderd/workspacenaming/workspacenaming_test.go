@@ -0,0 +1,142 @@
+package workspacenaming_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/workspacenaming"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	in := workspacenaming.Input{
+		Username:         "Alice",
+		OrganizationName: "acme",
+		TemplateName:     "docker",
+		CostCenter:       "CC42",
+	}
+
+	for _, tc := range []struct {
+		Name    string
+		Policy  codersdk.WorkspaceNamingPolicy
+		Valid   []string
+		Invalid []string
+	}{
+		{
+			Name:   "Empty",
+			Policy: codersdk.WorkspaceNamingPolicy{},
+			Valid:  []string{"anything", "a"},
+		},
+		{
+			Name:    "Prefix",
+			Policy:  codersdk.WorkspaceNamingPolicy{Prefix: "{{.Username}}-"},
+			Valid:   []string{"alice-dev", "Alice-dev"},
+			Invalid: []string{"dev", "bob-dev", "dev-alice-"},
+		},
+		{
+			Name:    "Suffix",
+			Policy:  codersdk.WorkspaceNamingPolicy{Suffix: "-{{.TemplateName}}"},
+			Valid:   []string{"dev-docker"},
+			Invalid: []string{"docker-dev", "dev"},
+		},
+		{
+			Name:    "Regex",
+			Policy:  codersdk.WorkspaceNamingPolicy{Regex: "[a-z]+-[0-9]+"},
+			Valid:   []string{"dev-1", "prod-42"},
+			Invalid: []string{"dev", "x-dev-1", "dev-1-x"},
+		},
+		{
+			Name: "Combined",
+			Policy: codersdk.WorkspaceNamingPolicy{
+				Prefix: "{{.OrganizationName}}-",
+				Regex:  "[a-z-]+",
+			},
+			Valid:   []string{"acme-dev"},
+			Invalid: []string{"acme-dev1", "other-dev"},
+		},
+		{
+			Name:    "CostCenter",
+			Policy:  codersdk.WorkspaceNamingPolicy{Prefix: "{{.CostCenter}}-"},
+			Valid:   []string{"cc42-dev"},
+			Invalid: []string{"dev", "cc43-dev"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			require.Empty(t, workspacenaming.Validate(tc.Policy))
+			for _, name := range tc.Valid {
+				validations, err := workspacenaming.Check(tc.Policy, name, in)
+				require.NoError(t, err)
+				require.Empty(t, validations, "expected %q to be valid", name)
+			}
+			for _, name := range tc.Invalid {
+				validations, err := workspacenaming.Check(tc.Policy, name, in)
+				require.NoError(t, err)
+				require.NotEmpty(t, validations, "expected %q to be invalid", name)
+			}
+		})
+	}
+}
+
+func TestCheckMissingCostCenter(t *testing.T) {
+	t.Parallel()
+
+	policy := codersdk.WorkspaceNamingPolicy{Suffix: "-{{.CostCenter}}"}
+	validations, err := workspacenaming.Check(policy, "dev-", workspacenaming.Input{Username: "alice"})
+	require.NoError(t, err)
+	require.Len(t, validations, 1)
+	require.Equal(t, "name", validations[0].Field)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Name   string
+		Policy codersdk.WorkspaceNamingPolicy
+		Field  string
+	}{
+		{
+			Name:   "InvalidRegex",
+			Policy: codersdk.WorkspaceNamingPolicy{Regex: "("},
+			Field:  "regex",
+		},
+		{
+			Name:   "UnknownPrefixField",
+			Policy: codersdk.WorkspaceNamingPolicy{Prefix: "{{.Department}}"},
+			Field:  "prefix",
+		},
+		{
+			Name:   "MalformedSuffix",
+			Policy: codersdk.WorkspaceNamingPolicy{Suffix: "{{.Username"},
+			Field:  "suffix",
+		},
+		{
+			Name:   "UnknownScope",
+			Policy: codersdk.WorkspaceNamingPolicy{UniquenessScope: "global"},
+			Field:  "uniqueness_scope",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			validations := workspacenaming.Validate(tc.Policy)
+			require.Len(t, validations, 1)
+			require.Equal(t, tc.Field, validations[0].Field)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	org := codersdk.WorkspaceNamingPolicy{Prefix: "org-", UniquenessScope: codersdk.WorkspaceNameUniquenessScopeOwner}
+	tmpl := codersdk.WorkspaceNamingPolicy{Suffix: "-tmpl", UniquenessScope: codersdk.WorkspaceNameUniquenessScopeOwner}
+	empty := codersdk.WorkspaceNamingPolicy{UniquenessScope: codersdk.WorkspaceNameUniquenessScopeOwner}
+
+	require.Equal(t, org, workspacenaming.Resolve(org, empty))
+	require.Equal(t, tmpl, workspacenaming.Resolve(org, tmpl))
+	require.True(t, workspacenaming.IsZero(workspacenaming.Resolve(empty, empty)))
+}
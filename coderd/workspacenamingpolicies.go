@@ -0,0 +1,269 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/workspacenaming"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get organization workspace naming policy
+// @ID get-organization-workspace-naming-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceNamingPolicy
+// @Router /organizations/{organization}/workspace-naming-policy [get]
+func (api *API) organizationWorkspaceNamingPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	policy, err := api.Database.GetOrganizationWorkspaceNamingPolicy(ctx, org.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspacenaming.FromDatabase(policy))
+}
+
+// @Summary Update organization workspace naming policy
+// @ID update-organization-workspace-naming-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.WorkspaceNamingPolicy true "Workspace naming policy"
+// @Success 200 {object} codersdk.WorkspaceNamingPolicy
+// @Router /organizations/{organization}/workspace-naming-policy [put]
+func (api *API) putOrganizationWorkspaceNamingPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	var req codersdk.WorkspaceNamingPolicy
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !validateWorkspaceNamingPolicy(ctx, rw, &req) {
+		return
+	}
+
+	policy, err := api.Database.UpsertOrganizationWorkspaceNamingPolicy(ctx, database.UpsertOrganizationWorkspaceNamingPolicyParams{
+		ID:              uuid.New(),
+		OrganizationID:  org.ID,
+		Regex:           req.Regex,
+		Prefix:          req.Prefix,
+		Suffix:          req.Suffix,
+		UniquenessScope: database.WorkspaceNameUniquenessScope(req.UniquenessScope),
+		UpdatedAt:       dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspacenaming.FromDatabase(policy))
+}
+
+// @Summary Get template workspace naming policy
+// @ID get-template-workspace-naming-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceNamingPolicy
+// @Router /templates/{template}/workspace-naming-policy [get]
+func (api *API) templateWorkspaceNamingPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	policy, err := api.Database.GetTemplateWorkspaceNamingPolicy(ctx, template.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspacenaming.FromDatabase(policy))
+}
+
+// @Summary Update template workspace naming policy
+// @ID update-template-workspace-naming-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.WorkspaceNamingPolicy true "Workspace naming policy"
+// @Success 200 {object} codersdk.WorkspaceNamingPolicy
+// @Router /templates/{template}/workspace-naming-policy [put]
+func (api *API) putTemplateWorkspaceNamingPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	var req codersdk.WorkspaceNamingPolicy
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !validateWorkspaceNamingPolicy(ctx, rw, &req) {
+		return
+	}
+
+	policy, err := api.Database.UpsertTemplateWorkspaceNamingPolicy(ctx, database.UpsertTemplateWorkspaceNamingPolicyParams{
+		ID:              uuid.New(),
+		OrganizationID:  template.OrganizationID,
+		TemplateID:      template.ID,
+		Regex:           req.Regex,
+		Prefix:          req.Prefix,
+		Suffix:          req.Suffix,
+		UniquenessScope: database.WorkspaceNameUniquenessScope(req.UniquenessScope),
+		UpdatedAt:       dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspacenaming.FromDatabase(policy))
+}
+
+func validateWorkspaceNamingPolicy(ctx context.Context, rw http.ResponseWriter, req *codersdk.WorkspaceNamingPolicy) bool {
+	if req.UniquenessScope == "" {
+		req.UniquenessScope = codersdk.WorkspaceNameUniquenessScopeOwner
+	}
+	if validations := workspacenaming.Validate(*req); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace naming policy.",
+			Validations: validations,
+		})
+		return false
+	}
+	return true
+}
+
+// checkWorkspaceNamingPolicy enforces the naming policy that applies to a new
+// or renamed workspace. workspaceID is the workspace being renamed, or
+// uuid.Nil for a new workspace. It writes an error response and returns false
+// if the name is rejected.
+//
+// Organization-scoped uniqueness is also enforced by the database, this check
+// only provides a friendlier error.
+func (api *API) checkWorkspaceNamingPolicy(ctx context.Context, rw http.ResponseWriter, template database.Template, owner workspaceOwner, workspaceID uuid.UUID, name string) bool {
+	policy, err := api.resolveWorkspaceNamingPolicy(ctx, template)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace naming policy.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if workspacenaming.IsZero(policy) {
+		return true
+	}
+
+	in := workspacenaming.Input{
+		Username:         owner.Username,
+		OrganizationName: template.OrganizationName,
+		TemplateName:     template.Name,
+	}
+	if workspacenaming.UsesCostCenter(policy.Prefix) || workspacenaming.UsesCostCenter(policy.Suffix) {
+		// nolint:gocritic // The actor might not be able to read the organization.
+		org, err := api.Database.GetOrganizationByID(dbauthz.AsSystemRestricted(ctx), template.OrganizationID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		in.CostCenter = org.CostCenter
+	}
+
+	validations, err := workspacenaming.Check(policy, name, in)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error evaluating workspace naming policy.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     fmt.Sprintf("Workspace name %q does not satisfy the naming policy.", name),
+			Validations: validations,
+		})
+		return false
+	}
+
+	if policy.UniquenessScope == codersdk.WorkspaceNameUniquenessScopeOrganization {
+		// The actor is not necessarily able to read other workspaces in the
+		// organization, but the name must still be unique among them.
+		// nolint:gocritic // Checking name uniqueness across the organization.
+		existing, err := api.Database.GetWorkspaceByOrganizationIDAndName(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceByOrganizationIDAndNameParams{
+			OrganizationID: template.OrganizationID,
+			Name:           name,
+		})
+		if err == nil && existing.ID != workspaceID {
+			writeWorkspaceOrganizationNameConflict(ctx, rw, name)
+			return false
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: fmt.Sprintf("Internal error fetching workspace by name %q.", name),
+				Detail:  err.Error(),
+			})
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeWorkspaceOrganizationNameConflict(ctx context.Context, rw http.ResponseWriter, name string) {
+	httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+		Message: fmt.Sprintf("Workspace %q already exists in the organization.", name),
+		Validations: []codersdk.ValidationError{{
+			Field:  "name",
+			Detail: "This value is already in use in the organization and should be unique.",
+		}},
+	})
+}
+
+func (api *API) resolveWorkspaceNamingPolicy(ctx context.Context, template database.Template) (codersdk.WorkspaceNamingPolicy, error) {
+	// Policies must be enforced regardless of whether the actor can read
+	// them.
+	// nolint:gocritic // Workspace creation must always enforce the policy.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	orgPolicy, err := api.Database.GetOrganizationWorkspaceNamingPolicy(sysCtx, template.OrganizationID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.WorkspaceNamingPolicy{}, xerrors.Errorf("get organization policy: %w", err)
+	}
+	templatePolicy, err := api.Database.GetTemplateWorkspaceNamingPolicy(sysCtx, template.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.WorkspaceNamingPolicy{}, xerrors.Errorf("get template policy: %w", err)
+	}
+
+	return workspacenaming.Resolve(workspacenaming.FromDatabase(orgPolicy), workspacenaming.FromDatabase(templatePolicy)), nil
+}
@@ -0,0 +1,235 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceNamingPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		policy, err := client.OrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceNameUniquenessScopeOwner, policy.UniquenessScope)
+
+		policy, err = client.UpdateOrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID, codersdk.WorkspaceNamingPolicy{
+			Prefix: "{{.Username}}-",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "{{.Username}}-", policy.Prefix)
+
+		// Members cannot change the policy.
+		_, err = member.UpdateOrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID, codersdk.WorkspaceNamingPolicy{})
+		require.Error(t, err)
+
+		_, err = member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "dev",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "name", apiErr.Validations[0].Field)
+
+		_, err = member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       memberUser.Username + "-dev",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("TemplateOverridesOrganization", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		_, err := client.UpdateOrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID, codersdk.WorkspaceNamingPolicy{
+			Prefix: "org-",
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateTemplateWorkspaceNamingPolicy(ctx, template.ID, codersdk.WorkspaceNamingPolicy{
+			Suffix: "-{{.TemplateName}}",
+		})
+		require.NoError(t, err)
+
+		policy, err := client.TemplateWorkspaceNamingPolicy(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, "-{{.TemplateName}}", policy.Suffix)
+
+		_, err = client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "org-dev",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "dev-" + template.Name,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("OrganizationUniqueness", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		_, err := client.UpdateOrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID, codersdk.WorkspaceNamingPolicy{
+			UniquenessScope: codersdk.WorkspaceNameUniquenessScopeOrganization,
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "shared",
+		})
+		require.NoError(t, err)
+
+		_, err = member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "Shared",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, AllowWorkspaceRenames: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		_, err := client.UpdateOrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID, codersdk.WorkspaceNamingPolicy{
+			Prefix:          "{{.Username}}-",
+			UniquenessScope: codersdk.WorkspaceNameUniquenessScopeOrganization,
+		})
+		require.NoError(t, err)
+
+		workspace, err := member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       memberUser.Username + "-dev",
+		})
+		require.NoError(t, err)
+
+		// Renames can't bypass the prefix.
+		err = member.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{Name: "dev"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Changing the case of the name doesn't conflict with itself.
+		err = member.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{Name: memberUser.Username + "-Dev"})
+		require.NoError(t, err)
+
+		// Another member's workspace can't be renamed to a name in use in
+		// the organization.
+		_, err = client.UpdateOrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID, codersdk.WorkspaceNamingPolicy{
+			UniquenessScope: codersdk.WorkspaceNameUniquenessScopeOrganization,
+		})
+		require.NoError(t, err)
+		other, err := client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "other",
+		})
+		require.NoError(t, err)
+		err = client.UpdateWorkspace(ctx, other.ID, codersdk.UpdateWorkspaceRequest{Name: memberUser.Username + "-dev"})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("CostCenter", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db, ps := dbtestutil.NewDB(t)
+		client := coderdtest.New(t, &coderdtest.Options{Database: db, Pubsub: ps, IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		_, err := client.UpdateOrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID, codersdk.WorkspaceNamingPolicy{
+			Prefix: "{{.CostCenter}}-",
+		})
+		require.NoError(t, err)
+
+		// The organization doesn't have a cost center yet.
+		_, err = client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "dev",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		org, err := db.GetOrganizationByID(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		_, err = db.UpdateOrganization(ctx, database.UpdateOrganizationParams{
+			ID:          org.ID,
+			Name:        org.Name,
+			DisplayName: org.DisplayName,
+			Description: org.Description,
+			Icon:        org.Icon,
+			CostCenter:  "CC42",
+			UpdatedAt:   dbtime.Now(),
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "cc42-dev",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("InvalidPolicy", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.UpdateOrganizationWorkspaceNamingPolicy(ctx, owner.OrganizationID, codersdk.WorkspaceNamingPolicy{
+			Regex:  "(",
+			Prefix: "{{.Unknown}}",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+}
@@ -45,6 +45,7 @@ func (api *API) PrimaryRegion(ctx context.Context) (codersdk.Region, error) {
 		Healthy:          true,
 		PathAppURL:       api.AccessURL.String(),
 		WildcardHostname: appurl.SubdomainAppHost(api.AppHostname, api.AccessURL),
+		PathAppsOnly:     api.DeploymentValues.PathAppsOnly.Value(),
 	}, nil
 }
 
@@ -0,0 +1,144 @@
+package coderd
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rightsizing"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace recommendations
+// @Description Recommends increasing or decreasing the CPU and memory of the
+// @Description agents of the workspace, based on the resource usage they
+// @Description reported within the lookback window. Where the parameters of
+// @Description the workspace appear to size a resource, the parameter changes
+// @Description that apply the recommendation are suggested.
+// @ID get-workspace-recommendations
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param lookback_days query int false "Number of days of resource usage to consider. Defaults to 7."
+// @Success 200 {object} codersdk.WorkspaceRecommendations
+// @Router /workspaces/{workspace}/recommendations [get]
+func (api *API) workspaceRecommendations(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	maxLookbackDays := int32(maxWorkspaceResourceUsageRange / (24 * time.Hour))
+	qp := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	lookbackDays := p.PositiveInt32(qp, codersdk.DefaultWorkspaceRecommendationsLookbackDays, "lookback_days")
+	p.ErrorExcessParams(qp)
+	if len(p.Errors) == 0 && (lookbackDays < 1 || lookbackDays > maxLookbackDays) {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "lookback_days",
+			Detail: fmt.Sprintf("Query param %q must be between 1 and %d.", "lookback_days", maxLookbackDays),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	now := api.Clock.Now().UTC()
+	rows, err := api.Database.GetWorkspaceAgentResourceUsageByWorkspaceID(ctx, database.GetWorkspaceAgentResourceUsageByWorkspaceIDParams{
+		WorkspaceID: workspace.ID,
+		StartTime:   now.AddDate(0, 0, -int(lookbackDays)),
+		EndTime:     now,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resource usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	res := codersdk.WorkspaceRecommendations{
+		LookbackDays:    int(lookbackDays),
+		Recommendations: []codersdk.WorkspaceRecommendation{},
+	}
+	summaries := rightsizing.Summarize(rows)
+	for _, summary := range summaries {
+		for resource, usage := range map[codersdk.WorkspaceRecommendationResource]rightsizing.Usage{
+			codersdk.WorkspaceRecommendationResourceCPU:    summary.CPU,
+			codersdk.WorkspaceRecommendationResourceMemory: summary.Memory,
+		} {
+			if rec, ok := rightsizing.Recommend(summary.AgentName, resource, usage); ok {
+				res.Recommendations = append(res.Recommendations, rec)
+			}
+		}
+	}
+	slices.SortFunc(res.Recommendations, func(a, b codersdk.WorkspaceRecommendation) int {
+		return cmp.Or(cmp.Compare(a.AgentName, b.AgentName), cmp.Compare(a.Resource, b.Resource))
+	})
+
+	// Parameters can't be attributed to one of several agents, so parameter
+	// changes are only suggested for workspaces with a single agent.
+	if len(summaries) == 1 && len(res.Recommendations) > 0 {
+		build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching latest workspace build.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		templateVersionParams, err := api.Database.GetTemplateVersionParameters(ctx, build.TemplateVersionID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template version parameters.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		buildParams, err := api.Database.GetWorkspaceBuildParameters(ctx, build.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace build parameters.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		values := make(map[string]string, len(buildParams))
+		for _, param := range buildParams {
+			values[param.Name] = param.Value
+		}
+
+		for i := range res.Recommendations {
+			for _, dbParam := range templateVersionParams {
+				value, ok := values[dbParam.Name]
+				if !ok {
+					continue
+				}
+				param, err := db2sdk.TemplateVersionParameter(dbParam)
+				if err != nil {
+					httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+						Message: "Internal error converting template version parameter.",
+						Detail:  err.Error(),
+					})
+					return
+				}
+				if change, ok := rightsizing.SuggestParameterChange(res.Recommendations[i], param, value); ok {
+					res.Recommendations[i].ParameterChanges = append(res.Recommendations[i].ParameterChanges, change)
+				}
+			}
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
@@ -0,0 +1,97 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceRecommendations(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{Database: db, Pubsub: ps})
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).Params(database.WorkspaceBuildParameter{
+		Name:  "cpu",
+		Value: "4",
+	}).WithAgent().Do()
+	dbgen.TemplateVersionParameter(t, db, database.TemplateVersionParameter{
+		TemplateVersionID: r.Build.TemplateVersionID,
+		Name:              "cpu",
+		DisplayName:       "CPU cores",
+		Type:              "number",
+		Mutable:           true,
+	})
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// No recommendations are made without enough usage.
+	recommendations, err := client.WorkspaceRecommendations(ctx, r.Workspace.ID, codersdk.WorkspaceRecommendationsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.DefaultWorkspaceRecommendationsLookbackDays, recommendations.LookbackDays)
+	require.Empty(t, recommendations.Recommendations)
+
+	// Given: two days of usage that nearly saturates the CPU and uses a
+	// quarter of the memory.
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	start := time.Now().Add(-48 * time.Hour).Truncate(15 * time.Minute)
+	for i := range 2 * 96 {
+		err := db.UpsertWorkspaceAgentResourceUsage(ctx, database.UpsertWorkspaceAgentResourceUsageParams{
+			AgentID:     agents[0].ID,
+			BucketStart: start.Add(time.Duration(i) * 15 * time.Minute),
+			CPUUsed:     3.9,
+			CPUTotal:    4,
+			MemoryUsed:  2 << 30,
+			MemoryTotal: 8 << 30,
+		})
+		require.NoError(t, err)
+	}
+
+	recommendations, err = client.WorkspaceRecommendations(ctx, r.Workspace.ID, codersdk.WorkspaceRecommendationsRequest{
+		LookbackDays: 3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, recommendations.LookbackDays)
+	require.Len(t, recommendations.Recommendations, 2)
+
+	cpu := recommendations.Recommendations[0]
+	require.Equal(t, "dev", cpu.AgentName)
+	require.Equal(t, codersdk.WorkspaceRecommendationResourceCPU, cpu.Resource)
+	require.Equal(t, codersdk.WorkspaceRecommendationDirectionIncrease, cpu.Direction)
+	require.Equal(t, 4.0, cpu.CurrentTotal)
+	require.Equal(t, 6.0, cpu.SuggestedTotal)
+	require.Equal(t, []codersdk.WorkspaceRecommendationParameterChange{{
+		Name:           "cpu",
+		CurrentValue:   "4",
+		SuggestedValue: "6",
+	}}, cpu.ParameterChanges)
+
+	memory := recommendations.Recommendations[1]
+	require.Equal(t, codersdk.WorkspaceRecommendationResourceMemory, memory.Resource)
+	require.Equal(t, codersdk.WorkspaceRecommendationDirectionDecrease, memory.Direction)
+	require.Equal(t, float64(3<<30), memory.SuggestedTotal)
+	require.Empty(t, memory.ParameterChanges)
+
+	// The lookback window is limited to the retention of resource usage.
+	_, err = client.WorkspaceRecommendations(ctx, r.Workspace.ID, codersdk.WorkspaceRecommendationsRequest{
+		LookbackDays: 91,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
@@ -0,0 +1,105 @@
+package coderd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxWorkspaceResourceUsageRange limits the period of resource usage that can
+// be requested at once. Older usage is purged anyway.
+const maxWorkspaceResourceUsageRange = 90 * 24 * time.Hour
+
+// @Summary Get workspace resource usage
+// @Description Returns the CPU, memory and disk usage reported by the agents
+// @Description of the workspace, downsampled into 15 minute intervals. Usage is
+// @Description kept for 90 days.
+// @ID get-workspace-resource-usage
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param start_time query string false "Start time (RFC3339). Defaults to 24 hours before the end time." format(date-time)
+// @Param end_time query string false "End time (RFC3339). Defaults to now." format(date-time)
+// @Success 200 {object} codersdk.WorkspaceResourceUsage
+// @Router /workspaces/{workspace}/resource-usage [get]
+func (api *API) workspaceResourceUsage(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	qp := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	endTime := p.Time3339Nano(qp, api.Clock.Now().UTC(), "end_time")
+	startTime := p.Time3339Nano(qp, endTime.Add(-24*time.Hour), "start_time")
+	p.ErrorExcessParams(qp)
+	if len(p.Errors) == 0 && !startTime.Before(endTime) {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "start_time",
+			Detail: "Query param \"start_time\" must be before \"end_time\".",
+		})
+	}
+	if len(p.Errors) == 0 && endTime.Sub(startTime) > maxWorkspaceResourceUsageRange {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "start_time",
+			Detail: "Query params \"start_time\" and \"end_time\" must be at most 90 days apart.",
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	rows, err := api.Database.GetWorkspaceAgentResourceUsageByWorkspaceID(ctx, database.GetWorkspaceAgentResourceUsageByWorkspaceIDParams{
+		WorkspaceID: workspace.ID,
+		// Include the interval that the start time falls into.
+		StartTime: startTime.Truncate(agentapi.ResourceUsageBucket),
+		EndTime:   endTime,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resource usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	res := codersdk.WorkspaceResourceUsage{
+		IntervalSeconds: int64(agentapi.ResourceUsageBucket.Seconds()),
+		Agents:          []codersdk.WorkspaceAgentResourceUsage{},
+	}
+	// Rows are ordered by agent name, so the usage of each agent is
+	// contiguous.
+	for _, row := range rows {
+		if len(res.Agents) == 0 || res.Agents[len(res.Agents)-1].AgentName != row.AgentName {
+			res.Agents = append(res.Agents, codersdk.WorkspaceAgentResourceUsage{
+				AgentName:  row.AgentName,
+				Datapoints: []codersdk.WorkspaceAgentResourceUsageDatapoint{},
+			})
+		}
+		agent := &res.Agents[len(res.Agents)-1]
+		agent.Datapoints = append(agent.Datapoints, codersdk.WorkspaceAgentResourceUsageDatapoint{
+			StartTime:     row.BucketStart,
+			Samples:       row.Samples,
+			CPUUsedAvg:    row.CPUUsedAvg,
+			CPUUsedMax:    row.CPUUsedMax,
+			CPUTotal:      row.CPUTotal,
+			MemoryUsedAvg: row.MemoryUsedAvg,
+			MemoryUsedMax: row.MemoryUsedMax,
+			MemoryTotal:   row.MemoryTotal,
+			DiskUsed:      row.DiskUsed,
+			DiskTotal:     row.DiskTotal,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
@@ -0,0 +1,102 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(r.AgentToken)
+		aAPI, _, err := agentClient.ConnectRPC27(ctx)
+		require.NoError(t, err)
+		defer func() {
+			_ = aAPI.DRPCConn().Close()
+		}()
+
+		// Two samples in the same interval are averaged.
+		collectedAt := time.Now().Add(-time.Hour).Truncate(15 * time.Minute)
+		for _, used := range []float64{1, 3} {
+			_, err = aAPI.PushResourceUsage(ctx, &agentproto.PushResourceUsageRequest{
+				CollectedAt: timestamppb.New(collectedAt.Add(time.Minute)),
+				CpuUsed:     used,
+				CpuTotal:    4,
+				MemoryUsed:  int64(used) << 30,
+				MemoryTotal: 8 << 30,
+				DiskUsed:    10 << 30,
+				DiskTotal:   100 << 30,
+			})
+			require.NoError(t, err)
+		}
+
+		usage, err := client.WorkspaceResourceUsage(ctx, r.Workspace.ID, codersdk.WorkspaceResourceUsageRequest{})
+		require.NoError(t, err)
+		require.Equal(t, int64(900), usage.IntervalSeconds)
+		require.Len(t, usage.Agents, 1)
+		require.Equal(t, "dev", usage.Agents[0].AgentName)
+		require.Len(t, usage.Agents[0].Datapoints, 1)
+		datapoint := usage.Agents[0].Datapoints[0]
+		require.WithinDuration(t, collectedAt, datapoint.StartTime, 0)
+		require.Equal(t, int32(2), datapoint.Samples)
+		require.Equal(t, 2.0, datapoint.CPUUsedAvg)
+		require.Equal(t, 3.0, datapoint.CPUUsedMax)
+		require.Equal(t, 4.0, datapoint.CPUTotal)
+		require.Equal(t, int64(2<<30), datapoint.MemoryUsedAvg)
+		require.Equal(t, int64(3<<30), datapoint.MemoryUsedMax)
+		require.Equal(t, int64(10<<30), datapoint.DiskUsed)
+
+		// Usage outside of the requested range is omitted.
+		usage, err = client.WorkspaceResourceUsage(ctx, r.Workspace.ID, codersdk.WorkspaceResourceUsageRequest{
+			StartTime: time.Now().Add(-10 * time.Minute),
+		})
+		require.NoError(t, err)
+		require.Empty(t, usage.Agents)
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).Do()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, req := range []codersdk.WorkspaceResourceUsageRequest{
+			{StartTime: time.Now(), EndTime: time.Now().Add(-time.Hour)},
+			{StartTime: time.Now().Add(-100 * 24 * time.Hour)},
+		} {
+			_, err := client.WorkspaceResourceUsage(ctx, r.Workspace.ID, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+}
@@ -110,6 +110,10 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 	if len(data.appStatuses) > 0 {
 		appStatus = data.appStatuses[0]
 	}
+	var driftCheck *codersdk.WorkspaceDriftCheck
+	if len(data.driftChecks) > 0 {
+		driftCheck = &data.driftChecks[0]
+	}
 
 	w, err := convertWorkspace(
 		apiKey.UserID,
@@ -118,6 +122,8 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 		data.templates[0],
 		api.Options.AllowWorkspaceRenames,
 		appStatus,
+		driftCheck,
+		data.healthWarnings,
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -137,9 +143,11 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Workspaces
-// @Param q query string false "Search query in the format `key:value`. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before, has-ai-task."
+// @Param q query string false "Search query in the format `key:value`. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before, has-ai-task, label."
 // @Param limit query int false "Page limit"
 // @Param offset query int false "Page offset"
+// @Param after_id query string false "After ID" format(uuid)
+// @Param fields query string false "Comma-separated list of workspace fields to return, e.g. `id,name,latest_build.status`. Nested fields are selected with dots."
 // @Success 200 {object} codersdk.WorkspacesResponse
 // @Router /workspaces [get]
 func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
@@ -151,6 +159,20 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	p := httpapi.NewQueryParamParser()
+	fields := httpapi.ParseFields[codersdk.Workspace](p, r.URL.Query(), "fields")
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	if fields != nil {
+		// The selected fields apply to each workspace.
+		fields = httpapi.FieldSelection{"workspaces": fields, "count": {}}
+	}
+
 	queryStr := r.URL.Query().Get("q")
 	filter, errs := searchquery.Workspaces(ctx, api.Database, queryStr, page, api.AgentInactiveDisconnectTimeout)
 	if len(errs) > 0 {
@@ -236,7 +258,7 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspacesResponse{
+	httpapi.WriteFields(ctx, rw, http.StatusOK, fields, codersdk.WorkspacesResponse{
 		Workspaces: wss,
 		Count:      int(workspaceRows[0].Count),
 	})
@@ -315,6 +337,10 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 	if len(data.appStatuses) > 0 {
 		appStatus = data.appStatuses[0]
 	}
+	var driftCheck *codersdk.WorkspaceDriftCheck
+	if len(data.driftChecks) > 0 {
+		driftCheck = &data.driftChecks[0]
+	}
 
 	w, err := convertWorkspace(
 		apiKey.UserID,
@@ -323,6 +349,8 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		data.templates[0],
 		api.Options.AllowWorkspaceRenames,
 		appStatus,
+		driftCheck,
+		data.healthWarnings,
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -350,6 +378,7 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 // @Param organization path string true "Organization ID" format(uuid)
 // @Param user path string true "Username, UUID, or me"
 // @Param request body codersdk.CreateWorkspaceRequest true "Create workspace request"
+// @Param Idempotency-Key header string false "Retrying a request with the same key returns what the first request created"
 // @Success 200 {object} codersdk.Workspace
 // @Router /organizations/{organization}/members/{user}/workspaces [post]
 func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Request) {
@@ -386,7 +415,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		AvatarURL: member.AvatarURL,
 	}
 
-	createWorkspace(ctx, aReq, apiKey.UserID, api, owner, req, rw, r)
+	createWorkspace(ctx, aReq, apiKey.UserID, api, owner, req, nil, rw, r)
 }
 
 // Create a new workspace for the currently authenticated user.
@@ -403,6 +432,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 // @Tags Workspaces
 // @Param user path string true "Username, UUID, or me"
 // @Param request body codersdk.CreateWorkspaceRequest true "Create workspace request"
+// @Param Idempotency-Key header string false "Retrying a request with the same key returns what the first request created"
 // @Success 200 {object} codersdk.Workspace
 // @Router /users/{user}/workspaces [post]
 func (api *API) postUserWorkspaces(rw http.ResponseWriter, r *http.Request) {
@@ -474,7 +504,7 @@ func (api *API) postUserWorkspaces(rw http.ResponseWriter, r *http.Request) {
 	})
 
 	defer commitAudit()
-	createWorkspace(ctx, aReq, apiKey.UserID, api, owner, req, rw, r)
+	createWorkspace(ctx, aReq, apiKey.UserID, api, owner, req, nil, rw, r)
 }
 
 type workspaceOwner struct {
@@ -490,9 +520,18 @@ func createWorkspace(
 	api *API,
 	owner workspaceOwner,
 	req codersdk.CreateWorkspaceRequest,
+	provisionerState []byte,
 	rw http.ResponseWriter,
 	r *http.Request,
 ) {
+	idempotencyKey, ok := parseIdempotencyKey(ctx, rw, r, req)
+	if !ok {
+		return
+	}
+	if api.replayCreateWorkspace(ctx, rw, idempotencyKey) {
+		return
+	}
+
 	template, ok := requestTemplate(ctx, rw, req, api.Database)
 	if !ok {
 		return
@@ -537,6 +576,8 @@ func createWorkspace(
 		return
 	}
 
+	// This is also checked by the pre-flight checks of the build. Doing this
+	// up front saves creating the workspace.
 	templateAccessControl := (*(api.AccessControlStore.Load())).GetTemplateAccessControl(template)
 	if templateAccessControl.IsDeprecated() {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -544,6 +585,7 @@ func createWorkspace(
 			// Pass the deprecated message to the user.
 			Detail:      templateAccessControl.Deprecated,
 			Validations: nil,
+			Code:        codersdk.ResponseCodeTemplateDeprecated,
 		})
 		return
 	}
@@ -596,6 +638,10 @@ func createWorkspace(
 		}
 	}
 
+	if !api.checkWorkspaceNamingPolicy(ctx, rw, template, owner, uuid.Nil, req.Name) {
+		return
+	}
+
 	// TODO: This should be a system call as the actor might not be able to
 	// read other workspaces. Ideally we check the error on create and look for
 	// a postgres conflict error.
@@ -621,6 +667,24 @@ func createWorkspace(
 		return
 	}
 
+	// If no preset was chosen, fall back to the template preset that is the
+	// default of one of the owner's groups.
+	if req.TemplateVersionPresetID == uuid.Nil && req.TemplatePresetID == uuid.Nil {
+		preset, err := api.Database.GetDefaultTemplatePresetForUser(ctx, database.GetDefaultTemplatePresetForUserParams{
+			TemplateID: template.ID,
+			UserID:     owner.ID,
+		})
+		if err == nil {
+			req.TemplatePresetID = preset.ID
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching default template preset.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
 	var (
 		provisionerJob     *database.ProvisionerJob
 		workspaceBuild     *database.WorkspaceBuild
@@ -634,6 +698,13 @@ func createWorkspace(
 			claimedWorkspace *database.Workspace
 		)
 
+		// The key is reserved first, so that a concurrent retry replays this
+		// request rather than conflicting with the workspace it creates.
+		err = reserveIdempotencyKey(ctx, db, idempotencyKey)
+		if err != nil {
+			return err
+		}
+
 		// If a template preset was chosen, try claim a prebuilt workspace.
 		if req.TemplateVersionPresetID != uuid.Nil {
 			// Try and claim an eligible prebuild, if available.
@@ -683,6 +754,9 @@ func createWorkspace(
 				LastUsedAt:       dbtime.Now(),
 				AutomaticUpdates: dbAU,
 			})
+			if database.IsWorkspaceOrganizationNameConflict(err) {
+				return wsbuilder.BuildError{Status: http.StatusConflict, Message: fmt.Sprintf("Workspace %q already exists in the organization.", req.Name), Wrapped: err}
+			}
 			if err != nil {
 				return xerrors.Errorf("insert workspace: %w", err)
 			}
@@ -704,19 +778,32 @@ func createWorkspace(
 		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
 			Reason(database.BuildReasonInitiator).
 			Initiator(initiatorID).
+			InitiatorContext(database.BuildInitiatorContext{
+				APIKeyName: httpmw.APIKey(r).TokenName,
+			}).
 			ActiveVersion().
 			Experiments(api.Experiments).
 			DeploymentValues(api.DeploymentValues).
-			RichParameterValues(req.RichParameterValues)
+			RichParameterValues(req.RichParameterValues).
+			PreflightChecks(api.workspaceBuildPreflightChecks()...)
+		if idempotencyKey.Key != "" {
+			builder = builder.IdempotencyKey(idempotencyKey.UserID, idempotencyKey.Key)
+		}
 		if req.TemplateVersionID != uuid.Nil {
 			builder = builder.VersionID(req.TemplateVersionID)
 		}
 		if req.TemplateVersionPresetID != uuid.Nil {
 			builder = builder.TemplateVersionPresetID(req.TemplateVersionPresetID)
 		}
+		if req.TemplatePresetID != uuid.Nil {
+			builder = builder.TemplatePresetID(req.TemplatePresetID)
+		}
 		if claimedWorkspace != nil {
 			builder = builder.MarkPrebuiltWorkspaceClaim()
 		}
+		if len(provisionerState) > 0 {
+			builder = builder.State(provisionerState)
+		}
 
 		workspaceBuild, provisionerJob, provisionerDaemons, err = builder.Build(
 			ctx,
@@ -729,6 +816,13 @@ func createWorkspace(
 		)
 		return err
 	}, nil)
+	if isIdempotencyKeyConflict(err) {
+		// A concurrent request with the same key created the workspace.
+		if !api.replayCreateWorkspace(ctx, rw, idempotencyKey) {
+			httpapi.Write(ctx, rw, http.StatusConflict, idempotencyKeyReusedResponse)
+		}
+		return
+	}
 	if err != nil {
 		httperror.WriteWorkspaceBuildError(ctx, rw, err)
 		return
@@ -794,6 +888,8 @@ func createWorkspace(
 		template,
 		api.Options.AllowWorkspaceRenames,
 		codersdk.WorkspaceAppStatus{},
+		nil,
+		nil,
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -996,6 +1092,24 @@ func (api *API) patchWorkspace(rw http.ResponseWriter, r *http.Request) {
 		name = req.Name
 	}
 
+	// Renames must satisfy the naming policy, just like new workspaces.
+	// Existing names are left alone, even if the policy changed since.
+	if name != "" && name != workspace.Name {
+		// nolint:gocritic // The owner may rename a workspace without being able to read its template.
+		template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		owner := workspaceOwner{ID: workspace.OwnerID, Username: workspace.OwnerUsername}
+		if !api.checkWorkspaceNamingPolicy(ctx, rw, template, owner, workspace.ID, name) {
+			return
+		}
+	}
+
 	newWorkspace, err := api.Database.UpdateWorkspace(ctx, database.UpdateWorkspaceParams{
 		ID:   workspace.ID,
 		Name: name,
@@ -1013,6 +1127,10 @@ func (api *API) patchWorkspace(rw http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
+		if database.IsWorkspaceOrganizationNameConflict(err) {
+			writeWorkspaceOrganizationNameConflict(ctx, rw, req.Name)
+			return
+		}
 		// Check if the name was already in use.
 		if database.IsUniqueViolation(err) {
 			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
@@ -1363,6 +1481,10 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 	if len(data.appStatuses) > 0 {
 		appStatus = data.appStatuses[0]
 	}
+	var driftCheck *codersdk.WorkspaceDriftCheck
+	if len(data.driftChecks) > 0 {
+		driftCheck = &data.driftChecks[0]
+	}
 
 	w, err := convertWorkspace(
 		apiKey.UserID,
@@ -1371,6 +1493,8 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 		data.templates[0],
 		api.Options.AllowWorkspaceRenames,
 		appStatus,
+		driftCheck,
+		data.healthWarnings,
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -1382,6 +1506,169 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, w)
 }
 
+// @Summary Restore deleted workspace
+// @Description Restores a deleted workspace that is still within the deleted
+// @Description workspace retention period, and starts it with the template
+// @Description version and parameter values of its last successful start build.
+// @ID restore-deleted-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 201 {object} codersdk.WorkspaceBuild
+// @Router /workspaces/{workspace}/restore [post]
+func (api *API) postWorkspaceRestore(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		oldWorkspace      = httpmw.WorkspaceParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: oldWorkspace.OrganizationID,
+		})
+	)
+	aReq.Old = oldWorkspace.WorkspaceTable()
+	defer commitAudit()
+
+	if !oldWorkspace.Deleted {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspace is not deleted.",
+		})
+		return
+	}
+
+	// The name of a deleted workspace may have been reused since.
+	_, err := api.Database.GetWorkspaceByOwnerIDAndName(ctx, database.GetWorkspaceByOwnerIDAndNameParams{
+		OwnerID: oldWorkspace.OwnerID,
+		Name:    oldWorkspace.Name,
+	})
+	if err == nil {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q already exists. Rename it before restoring this workspace.", oldWorkspace.Name),
+		})
+		return
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: fmt.Sprintf("Internal error fetching workspace by name %q.", oldWorkspace.Name),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Workspaces that never started successfully are rebuilt from their last
+	// build instead.
+	lastStartBuild, err := api.Database.GetLatestSuccessfulWorkspaceBuildByWorkspaceID(ctx, oldWorkspace.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching last successful workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	hasStartBuild := err == nil
+
+	var (
+		workspace          database.Workspace
+		workspaceBuild     *database.WorkspaceBuild
+		provisionerJob     *database.ProvisionerJob
+		provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.UpdateWorkspaceDeletedByID(ctx, database.UpdateWorkspaceDeletedByIDParams{
+			ID:      oldWorkspace.ID,
+			Deleted: false,
+		})
+		if err != nil {
+			if database.IsUniqueViolation(err, database.UniqueWorkspacesOwnerIDLowerIndex) {
+				return wsbuilder.BuildError{Status: http.StatusConflict, Message: fmt.Sprintf("Workspace %q already exists. Rename it before restoring this workspace.", oldWorkspace.Name), Wrapped: err}
+			}
+			return wsbuilder.BuildError{Status: http.StatusInternalServerError, Message: "mark workspace as restored", Wrapped: err}
+		}
+		// Workspaces deleted because they were dormant for too long would
+		// otherwise be deleted again.
+		if oldWorkspace.DormantAt.Valid {
+			_, err = tx.UpdateWorkspaceDormantDeletingAt(ctx, database.UpdateWorkspaceDormantDeletingAtParams{
+				ID:        oldWorkspace.ID,
+				DormantAt: sql.NullTime{},
+			})
+			if err != nil {
+				return wsbuilder.BuildError{Status: http.StatusInternalServerError, Message: "clear workspace dormancy", Wrapped: err}
+			}
+		}
+		workspace, err = tx.GetWorkspaceByID(ctx, oldWorkspace.ID)
+		if err != nil {
+			return wsbuilder.BuildError{Status: http.StatusInternalServerError, Message: "get restored workspace", Wrapped: err}
+		}
+
+		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+			Initiator(apiKey.UserID).
+			InitiatorContext(database.BuildInitiatorContext{
+				APIKeyName: apiKey.TokenName,
+			}).
+			DeploymentValues(api.Options.DeploymentValues).
+			Experiments(api.Experiments).
+			PreflightChecks(api.workspaceBuildPreflightChecks()...)
+		if hasStartBuild {
+			builder = builder.Rollback(lastStartBuild)
+		}
+		workspaceBuild, provisionerJob, provisionerDaemons, err = builder.Build(
+			ctx,
+			tx,
+			api.FileCache,
+			func(action policy.Action, object rbac.Objecter) bool {
+				return api.Authorize(r, action, object)
+			},
+			audit.WorkspaceBuildBaggageFromRequest(r),
+		)
+		return err
+	}, nil)
+	if err != nil {
+		httperror.WriteWorkspaceBuildError(ctx, rw, err)
+		return
+	}
+	aReq.New = workspace.WorkspaceTable()
+
+	if err := provisionerjobs.PostJob(api.Pubsub, *provisionerJob); err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		*workspaceBuild,
+		workspace,
+		database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: *provisionerJob,
+		},
+		[]database.WorkspaceResource{},
+		[]database.WorkspaceResourceMetadatum{},
+		[]database.WorkspaceAgent{},
+		[]database.WorkspaceApp{},
+		[]database.WorkspaceAppStatus{},
+		[]database.WorkspaceAgentScript{},
+		[]database.WorkspaceAgentLogSource{},
+		database.TemplateVersion{},
+		provisionerDaemons,
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+}
+
 // @Summary Extend workspace deadline by ID
 // @ID extend-workspace-deadline-by-id
 // @Security CoderSessionToken
@@ -1927,6 +2214,10 @@ func (api *API) watchWorkspace(
 		if len(data.appStatuses) > 0 {
 			appStatus = data.appStatuses[0]
 		}
+		var driftCheck *codersdk.WorkspaceDriftCheck
+		if len(data.driftChecks) > 0 {
+			driftCheck = &data.driftChecks[0]
+		}
 		w, err := convertWorkspace(
 			apiKey.UserID,
 			workspace,
@@ -1934,6 +2225,8 @@ func (api *API) watchWorkspace(
 			data.templates[0],
 			api.Options.AllowWorkspaceRenames,
 			appStatus,
+			driftCheck,
+			data.healthWarnings,
 		)
 		if err != nil {
 			_ = sendEvent(codersdk.ServerSentEvent{
@@ -2042,10 +2335,13 @@ func (api *API) workspaceTimings(rw http.ResponseWriter, r *http.Request) {
 }
 
 type workspaceData struct {
-	templates    []database.Template
-	builds       []codersdk.WorkspaceBuild
-	appStatuses  []codersdk.WorkspaceAppStatus
-	allowRenames bool
+	templates   []database.Template
+	builds      []codersdk.WorkspaceBuild
+	appStatuses []codersdk.WorkspaceAppStatus
+	driftChecks []codersdk.WorkspaceDriftCheck
+	// healthWarnings are keyed by the ID of the agent that reported them.
+	healthWarnings map[uuid.UUID][]codersdk.WorkspaceHealthWarning
+	allowRenames   bool
 }
 
 // workspacesData only returns the data the caller can access. If the caller
@@ -2064,6 +2360,7 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		templates   []database.Template
 		builds      []database.WorkspaceBuild
 		appStatuses []database.WorkspaceAppStatus
+		driftChecks []database.GetWorkspaceDriftChecksByWorkspaceIDsRow
 		eg          errgroup.Group
 	)
 	eg.Go(func() (err error) {
@@ -2093,6 +2390,15 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		}
 		return nil
 	})
+	eg.Go(func() (err error) {
+		// This query must be run as system restricted to be efficient.
+		// nolint:gocritic
+		driftChecks, err = api.Database.GetWorkspaceDriftChecksByWorkspaceIDs(dbauthz.AsSystemRestricted(ctx), workspaceIDs)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get workspace drift checks: %w", err)
+		}
+		return nil
+	})
 	err := eg.Wait()
 	if err != nil {
 		return workspaceData{}, err
@@ -2120,12 +2426,32 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 	if err != nil {
 		return workspaceData{}, xerrors.Errorf("convert workspace builds: %w", err)
 	}
+	api.estimateWorkspaceBuildJobStarts(ctx, apiBuilds)
+
+	agentIDs := make([]uuid.UUID, 0, len(data.agents))
+	agentNames := make(map[uuid.UUID]string, len(data.agents))
+	for _, agent := range data.agents {
+		agentIDs = append(agentIDs, agent.ID)
+		agentNames[agent.ID] = agent.Name
+	}
+	// This query must be run as system restricted to be efficient.
+	// nolint:gocritic
+	healthEvents, err := api.Database.GetUnresolvedWorkspaceAgentHealthEventsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return workspaceData{}, xerrors.Errorf("get workspace agent health events: %w", err)
+	}
+	healthWarnings := make(map[uuid.UUID][]codersdk.WorkspaceHealthWarning)
+	for _, event := range healthEvents {
+		healthWarnings[event.AgentID] = append(healthWarnings[event.AgentID], db2sdk.WorkspaceHealthWarning(event, agentNames[event.AgentID]))
+	}
 
 	return workspaceData{
-		templates:    templates,
-		appStatuses:  db2sdk.WorkspaceAppStatuses(appStatuses),
-		builds:       apiBuilds,
-		allowRenames: api.Options.AllowWorkspaceRenames,
+		templates:      templates,
+		appStatuses:    db2sdk.WorkspaceAppStatuses(appStatuses),
+		driftChecks:    db2sdk.WorkspaceDriftChecks(driftChecks),
+		healthWarnings: healthWarnings,
+		builds:         apiBuilds,
+		allowRenames:   api.Options.AllowWorkspaceRenames,
 	}, nil
 }
 
@@ -2142,6 +2468,10 @@ func convertWorkspaces(requesterID uuid.UUID, workspaces []database.Workspace, d
 	for _, appStatus := range data.appStatuses {
 		appStatusesByWorkspaceID[appStatus.WorkspaceID] = appStatus
 	}
+	driftChecksByWorkspaceID := map[uuid.UUID]codersdk.WorkspaceDriftCheck{}
+	for _, driftCheck := range data.driftChecks {
+		driftChecksByWorkspaceID[driftCheck.WorkspaceID] = driftCheck
+	}
 
 	apiWorkspaces := make([]codersdk.Workspace, 0, len(workspaces))
 	for _, workspace := range workspaces {
@@ -2159,6 +2489,10 @@ func convertWorkspaces(requesterID uuid.UUID, workspaces []database.Workspace, d
 			continue
 		}
 		appStatus := appStatusesByWorkspaceID[workspace.ID]
+		var driftCheck *codersdk.WorkspaceDriftCheck
+		if check, ok := driftChecksByWorkspaceID[workspace.ID]; ok {
+			driftCheck = &check
+		}
 
 		w, err := convertWorkspace(
 			requesterID,
@@ -2167,6 +2501,8 @@ func convertWorkspaces(requesterID uuid.UUID, workspaces []database.Workspace, d
 			template,
 			data.allowRenames,
 			appStatus,
+			driftCheck,
+			data.healthWarnings,
 		)
 		if err != nil {
 			return nil, xerrors.Errorf("convert workspace: %w", err)
@@ -2184,6 +2520,8 @@ func convertWorkspace(
 	template database.Template,
 	allowRenames bool,
 	latestAppStatus codersdk.WorkspaceAppStatus,
+	driftCheck *codersdk.WorkspaceDriftCheck,
+	healthWarnings map[uuid.UUID][]codersdk.WorkspaceHealthWarning,
 ) (codersdk.Workspace, error) {
 	if requesterID == uuid.Nil {
 		return codersdk.Workspace{}, xerrors.Errorf("developer error: requesterID cannot be uuid.Nil!")
@@ -2209,11 +2547,13 @@ func convertWorkspace(
 	}
 
 	failingAgents := []uuid.UUID{}
+	warnings := []codersdk.WorkspaceHealthWarning{}
 	for _, resource := range workspaceBuild.Resources {
 		for _, agent := range resource.Agents {
 			if !agent.Health.Healthy {
 				failingAgents = append(failingAgents, agent.ID)
 			}
+			warnings = append(warnings, healthWarnings[agent.ID]...)
 		}
 	}
 
@@ -2260,11 +2600,13 @@ func convertWorkspace(
 		Health: codersdk.WorkspaceHealth{
 			Healthy:       len(failingAgents) == 0,
 			FailingAgents: failingAgents,
+			Warnings:      warnings,
 		},
 		AutomaticUpdates: codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
 		AllowRenames:     allowRenames,
 		Favorite:         requesterFavorite,
 		NextStartAt:      nextStartAt,
+		DriftCheck:       driftCheck,
 	}, nil
 }
 
@@ -347,6 +347,48 @@ func TestWorkspace(t *testing.T) {
 			assert.False(t, agent2.Health.Healthy)
 			assert.NotEmpty(t, agent2.Health.Reason)
 		})
+
+		t.Run("Warnings", func(t *testing.T) {
+			t.Parallel()
+			client, db := coderdtest.NewWithDatabase(t, nil)
+			owner := coderdtest.CreateFirstUser(t, client)
+			resp := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+				OwnerID:        owner.UserID,
+				OrganizationID: owner.OrganizationID,
+			}).WithAgent().Do()
+
+			ctx := testutil.Context(t, testutil.WaitShort)
+			workspace, err := client.Workspace(ctx, resp.Workspace.ID)
+			require.NoError(t, err)
+			agent := workspace.LatestBuild.Resources[0].Agents[0]
+			assert.Empty(t, workspace.Health.Warnings)
+
+			// nolint:gocritic // Unit test.
+			_, err = db.InsertWorkspaceAgentHealthEvent(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceAgentHealthEventParams{
+				ID:        uuid.New(),
+				AgentID:   agent.ID,
+				Kind:      database.WorkspaceAgentHealthEventKindLowDiskSpace,
+				Volume:    "/home/coder",
+				Used:      97,
+				Total:     100,
+				CreatedAt: dbtime.Now(),
+			})
+			require.NoError(t, err)
+
+			workspace, err = client.Workspace(ctx, resp.Workspace.ID)
+			require.NoError(t, err)
+
+			// Warnings don't make the workspace unhealthy.
+			assert.True(t, workspace.Health.Healthy)
+			require.Len(t, workspace.Health.Warnings, 1)
+			warning := workspace.Health.Warnings[0]
+			assert.Equal(t, agent.ID, warning.AgentID)
+			assert.Equal(t, agent.Name, warning.AgentName)
+			assert.Equal(t, codersdk.WorkspaceHealthWarningKindLowDiskSpace, warning.Kind)
+			assert.Equal(t, "/home/coder", warning.Volume)
+			assert.EqualValues(t, 97, warning.Used)
+			assert.EqualValues(t, 100, warning.Total)
+		})
 	})
 
 	t.Run("Archived", func(t *testing.T) {
@@ -2463,6 +2505,39 @@ func TestWorkspaceFilterManual(t *testing.T) {
 			expectIDs(t, []codersdk.Workspace{foo, bar, baz}, all.Workspaces)
 		})
 	})
+
+	t.Run("Fields", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+			Fields: []string{"id", "latest_build.status"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Count)
+		require.Len(t, res.Workspaces, 1)
+		workspace := res.Workspaces[0]
+		require.Equal(t, r.Workspace.ID, workspace.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, workspace.LatestBuild.Status)
+		// Fields that were not selected are not returned.
+		require.Empty(t, workspace.Name)
+		require.Equal(t, uuid.Nil, workspace.LatestBuild.ID)
+		require.Nil(t, workspace.LatestBuild.Resources)
+
+		_, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{
+			Fields: []string{"latest_build.unknown"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
 }
 
 func TestOffsetLimit(t *testing.T) {
@@ -2511,6 +2586,33 @@ func TestOffsetLimit(t *testing.T) {
 		Offset: math.MaxInt32 + 1, // Potential risk: pq: OFFSET must not be negative
 	})
 	require.Error(t, err)
+
+	// Case 6: paging with the last workspace of each page finds all
+	// workspaces in order
+	all, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+	require.NoError(t, err)
+	var want, got []uuid.UUID
+	for _, workspace := range all.Workspaces {
+		want = append(want, workspace.ID)
+	}
+	var afterID uuid.UUID
+	for range all.Workspaces {
+		ws, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{
+			Limit:   1,
+			AfterID: afterID,
+		})
+		require.NoError(t, err)
+		require.Len(t, ws.Workspaces, 1)
+		require.Equal(t, 3, ws.Count)
+		got = append(got, ws.Workspaces[0].ID)
+		afterID = ws.Workspaces[0].ID
+	}
+	require.Equal(t, want, got)
+	ws, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{
+		AfterID: afterID,
+	})
+	require.NoError(t, err)
+	require.Len(t, ws.Workspaces, 0)
 }
 
 func TestWorkspaceUpdateAutostart(t *testing.T) {
@@ -4010,6 +4112,112 @@ func TestWorkspaceDormant(t *testing.T) {
 	})
 }
 
+func TestWorkspaceRestore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		var (
+			auditRecorder = audit.NewMock()
+			client        = coderdtest.New(t, &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+				Auditor:                  auditRecorder,
+			})
+			user      = coderdtest.CreateFirstUser(t, client)
+			version   = coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+			_         = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+			template  = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+			workspace = coderdtest.CreateWorkspace(t, client, template.ID)
+			_         = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Workspaces deleted while dormant are restored as active workspaces.
+		err := client.UpdateWorkspaceDormancy(ctx, workspace.ID, codersdk.UpdateWorkspaceDormancy{
+			Dormant: true,
+		})
+		require.NoError(t, err)
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		_, err = client.Workspace(ctx, workspace.ID)
+		require.ErrorContains(t, err, "410")
+
+		auditRecorder.ResetLogs()
+		build, err = client.RestoreWorkspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, build.Transition)
+		require.Equal(t, version.ID, build.TemplateVersionID)
+		require.True(t, auditRecorder.Contains(t, database.AuditLog{
+			Action:         database.AuditActionWrite,
+			ResourceType:   database.ResourceTypeWorkspace,
+			ResourceTarget: workspace.Name,
+		}))
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Nil(t, workspace.DormantAt)
+		require.Equal(t, build.ID, workspace.LatestBuild.ID)
+	})
+
+	t.Run("NotDeleted", func(t *testing.T) {
+		t.Parallel()
+		var (
+			client    = coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+			user      = coderdtest.CreateFirstUser(t, client)
+			version   = coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+			_         = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+			template  = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+			workspace = coderdtest.CreateWorkspace(t, client, template.ID)
+			_         = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.RestoreWorkspace(ctx, workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NameInUse", func(t *testing.T) {
+		t.Parallel()
+		var (
+			client    = coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+			user      = coderdtest.CreateFirstUser(t, client)
+			version   = coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+			_         = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+			template  = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+			workspace = coderdtest.CreateWorkspace(t, client, template.ID)
+			_         = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		// The name of the deleted workspace is reused by a new workspace.
+		reused := coderdtest.CreateWorkspace(t, client, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.Name = workspace.Name
+		})
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, reused.LatestBuild.ID)
+
+		_, err = client.RestoreWorkspace(ctx, workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+}
+
 func TestWorkspaceFavoriteUnfavorite(t *testing.T) {
 	t.Parallel()
 	// Given:
@@ -0,0 +1,155 @@
+package coderd
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace session recordings
+// @Description Returns the recorded terminal sessions of a workspace, newest
+// @Description first. Reading recordings requires permission to read audit logs.
+// @ID get-workspace-session-recordings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceSessionRecording
+// @Router /workspaces/{workspace}/session-recordings [get]
+func (api *API) workspaceSessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	recordings, err := api.Database.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspace.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace session recordings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.WorkspaceSessionRecording, 0, len(recordings))
+	for _, recording := range recordings {
+		converted = append(converted, convertWorkspaceSessionRecording(recording))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get workspace session recording transcript
+// @Description Returns the transcript of a recorded terminal session in the
+// @Description asciicast v2 format, which can be played back with asciinema.
+// @ID get-workspace-session-recording-transcript
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param recording path string true "Recording ID" format(uuid)
+// @Success 200
+// @Router /workspaces/{workspace}/session-recordings/{recording}/transcript [get]
+func (api *API) workspaceSessionRecordingTranscript(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	recordingID, ok := httpmw.ParseUUIDParam(rw, r, "recording")
+	if !ok {
+		return
+	}
+	recording, err := api.Database.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if httpapi.Is404Error(err) || (err == nil && recording.WorkspaceID != workspace.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	chunks, err := api.Database.GetWorkspaceSessionRecordingChunks(ctx, recording.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace session recording output.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", codersdk.ContentTypeAsciicast)
+	rw.WriteHeader(http.StatusOK)
+	w := bufio.NewWriter(rw)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(asciicastHeader{
+		Version:   2,
+		Width:     recording.Width,
+		Height:    recording.Height,
+		Timestamp: recording.StartedAt.Unix(),
+	})
+	// Output events must be valid UTF-8, but a chunk can end in the middle
+	// of a character, so incomplete characters are carried over to the next
+	// event.
+	var carry []byte
+	for _, chunk := range chunks {
+		data := append(carry, chunk.Data...)
+		end := len(data)
+		for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+			if !utf8.RuneStart(data[i]) {
+				continue
+			}
+			if !utf8.FullRune(data[i:]) {
+				end = i
+			}
+			break
+		}
+		carry = append([]byte(nil), data[end:]...)
+		if end == 0 {
+			continue
+		}
+		_ = enc.Encode([]any{
+			chunk.CreatedAt.Sub(recording.StartedAt).Seconds(),
+			"o",
+			string(data[:end]),
+		})
+	}
+	_ = w.Flush()
+}
+
+// asciicastHeader is the first line of an asciicast v2 file.
+type asciicastHeader struct {
+	Version   int   `json:"version"`
+	Width     int32 `json:"width"`
+	Height    int32 `json:"height"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+func convertWorkspaceSessionRecording(recording database.WorkspaceSessionRecording) codersdk.WorkspaceSessionRecording {
+	converted := codersdk.WorkspaceSessionRecording{
+		ID:          recording.ID,
+		WorkspaceID: recording.WorkspaceID,
+		AgentID:     recording.AgentID,
+		Type:        codersdk.WorkspaceSessionRecordingType(recording.Type),
+		Width:       recording.Width,
+		Height:      recording.Height,
+		StartedAt:   recording.StartedAt,
+		SizeBytes:   recording.SizeBytes,
+	}
+	if recording.EndedAt.Valid {
+		converted.EndedAt = &recording.EndedAt.Time
+	}
+	return converted
+}
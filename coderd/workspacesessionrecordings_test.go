@@ -0,0 +1,194 @@
+package coderd_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.SessionRecording.Enabled = true
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{DeploymentValues: dv})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        member.ID,
+		}).WithAgent().Do()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(r.AgentToken)
+		aAPI, _, err := agentClient.ConnectRPC27(ctx)
+		require.NoError(t, err)
+		defer func() {
+			_ = aAPI.DRPCConn().Close()
+		}()
+
+		id := uuid.New()
+		startedAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+		_, err = aAPI.CreateSessionRecording(ctx, &agentproto.CreateSessionRecordingRequest{
+			Id:        id[:],
+			Type:      agentproto.CreateSessionRecordingRequest_SSH,
+			StartedAt: timestamppb.New(startedAt),
+			Width:     80,
+			Height:    24,
+		})
+		require.NoError(t, err)
+
+		// The check mark is split across two chunks.
+		checkMark := []byte("✓")
+		_, err = aAPI.AppendSessionRecording(ctx, &agentproto.AppendSessionRecordingRequest{
+			Id: id[:],
+			Chunks: []*agentproto.AppendSessionRecordingRequest_Chunk{{
+				CreatedAt: timestamppb.New(startedAt.Add(time.Second)),
+				Data:      []byte("$ echo <ok>\r\n"),
+			}, {
+				CreatedAt: timestamppb.New(startedAt.Add(2 * time.Second)),
+				Data:      append([]byte("done "), checkMark[:1]...),
+			}},
+		})
+		require.NoError(t, err)
+		_, err = aAPI.AppendSessionRecording(ctx, &agentproto.AppendSessionRecordingRequest{
+			Id: id[:],
+			Chunks: []*agentproto.AppendSessionRecordingRequest_Chunk{{
+				CreatedAt: timestamppb.New(startedAt.Add(3 * time.Second)),
+				Data:      checkMark[1:],
+			}},
+			EndedAt: timestamppb.New(startedAt.Add(4 * time.Second)),
+		})
+		require.NoError(t, err)
+
+		recordings, err := client.WorkspaceSessionRecordings(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, recordings, 1)
+		recording := recordings[0]
+		require.Equal(t, id, recording.ID)
+		require.Equal(t, r.Workspace.ID, recording.WorkspaceID)
+		require.NotEqual(t, uuid.Nil, recording.AgentID)
+		require.Equal(t, codersdk.WorkspaceSessionRecordingTypeSSH, recording.Type)
+		require.EqualValues(t, 80, recording.Width)
+		require.EqualValues(t, 24, recording.Height)
+		require.WithinDuration(t, startedAt, recording.StartedAt, 0)
+		require.NotNil(t, recording.EndedAt)
+		require.WithinDuration(t, startedAt.Add(4*time.Second), *recording.EndedAt, 0)
+		require.EqualValues(t, len("$ echo <ok>\r\ndone ✓"), recording.SizeBytes)
+
+		transcript, err := client.WorkspaceSessionRecordingTranscript(ctx, r.Workspace.ID, id)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(bytes.NewReader(transcript))
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		require.Len(t, lines, 4)
+		var header map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+		require.Equal(t, map[string]any{
+			"version":   2.0,
+			"width":     80.0,
+			"height":    24.0,
+			"timestamp": float64(startedAt.Unix()),
+		}, header)
+		require.JSONEq(t, `[1, "o", "$ echo <ok>\r\n"]`, lines[1])
+		// The incomplete character is written with the rest of it.
+		require.JSONEq(t, `[2, "o", "done "]`, lines[2])
+		require.JSONEq(t, `[3, "o", "✓"]`, lines[3])
+
+		// Playing back recordings requires access to audit logs, which the
+		// owner of the workspace doesn't have.
+		_, err = memberClient.WorkspaceSessionRecordings(ctx, r.Workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = memberClient.WorkspaceSessionRecordingTranscript(ctx, r.Workspace.ID, id)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Recordings can't be appended to once they have ended.
+		_, err = aAPI.AppendSessionRecording(ctx, &agentproto.AppendSessionRecordingRequest{
+			Id: id[:],
+			Chunks: []*agentproto.AppendSessionRecordingRequest_Chunk{{
+				CreatedAt: timestamppb.New(startedAt.Add(5 * time.Second)),
+				Data:      []byte("more"),
+			}},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).WithAgent().Do()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(r.AgentToken)
+		aAPI, _, err := agentClient.ConnectRPC27(ctx)
+		require.NoError(t, err)
+		defer func() {
+			_ = aAPI.DRPCConn().Close()
+		}()
+
+		manifest, err := aAPI.GetManifest(ctx, &agentproto.GetManifestRequest{})
+		require.NoError(t, err)
+		require.False(t, manifest.GetSessionRecordingEnabled())
+
+		id := uuid.New()
+		_, err = aAPI.CreateSessionRecording(ctx, &agentproto.CreateSessionRecordingRequest{
+			Id:        id[:],
+			Type:      agentproto.CreateSessionRecordingRequest_SSH,
+			StartedAt: timestamppb.Now(),
+			Width:     80,
+			Height:    24,
+		})
+		require.Error(t, err)
+
+		recordings, err := client.WorkspaceSessionRecordings(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, recordings)
+	})
+
+	t.Run("TranscriptNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).Do()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.WorkspaceSessionRecordingTranscript(ctx, r.Workspace.ID, uuid.New())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
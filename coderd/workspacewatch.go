@@ -0,0 +1,356 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/httpmw/loggermw"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/wsjson"
+	"github.com/coder/websocket"
+)
+
+// maxWatchedWorkspaces limits the number of workspaces a single watch
+// connection can subscribe to.
+const maxWatchedWorkspaces = 1000
+
+// Watches any number of workspaces over a single WebSocket. Clients send
+// codersdk.WatchRequest messages to subscribe to workspaces, and receive
+// codersdk.WatchEvent messages when the workspaces, their builds or their
+// agents change.
+//
+// @Summary Watch workspaces via WebSockets
+// @ID watch-workspaces-via-websockets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Success 200 {object} codersdk.WatchEvent
+// @Router /workspaces/watch [get]
+func (api *API) watchWorkspaces(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	conn, err := websocket.Accept(rw, r, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go httpapi.Heartbeat(ctx, conn)
+
+	stream := wsjson.NewStream[codersdk.WatchRequest, codersdk.WatchEvent](
+		conn,
+		websocket.MessageText,
+		websocket.MessageText,
+		api.Logger,
+	)
+	defer stream.Close(websocket.StatusNormalClosure)
+
+	// Log the request immediately instead of after it completes.
+	if rl := loggermw.RequestLoggerFromContext(ctx); rl != nil {
+		rl.WriteLog(ctx, http.StatusAccepted)
+	}
+
+	watch := &workspaceWatch{
+		api:        api,
+		stream:     stream,
+		userID:     apiKey.UserID,
+		workspaces: map[uuid.UUID]uuid.UUID{},
+		owners:     map[uuid.UUID]func(){},
+		pending:    map[uuid.UUID]*workspaceWatchUpdate{},
+		notify:     make(chan struct{}, 1),
+	}
+	defer watch.close()
+
+	requests := stream.Chan()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req, ok := <-requests:
+			if !ok {
+				return
+			}
+			switch req.Type {
+			case codersdk.WatchRequestTypeSubscribe:
+				err = watch.subscribe(ctx, req.WorkspaceIDs)
+			case codersdk.WatchRequestTypeUnsubscribe:
+				watch.unsubscribe(req.WorkspaceIDs)
+			default:
+				err = stream.Send(codersdk.WatchEvent{
+					Type: codersdk.WatchEventTypeError,
+					Error: &codersdk.Response{
+						Message: fmt.Sprintf("Unknown request type %q.", req.Type),
+					},
+				})
+			}
+		case <-watch.notify:
+			err = watch.flush(ctx)
+		}
+		if err != nil {
+			api.Logger.Debug(ctx, "send workspace watch event", slog.Error(err))
+			return
+		}
+	}
+}
+
+// workspaceWatch tracks the workspaces a watch connection is subscribed to.
+// Workspace events are published per owner, so there is one pubsub
+// subscription for each owner of a watched workspace.
+type workspaceWatch struct {
+	api    *API
+	stream *wsjson.Stream[codersdk.WatchRequest, codersdk.WatchEvent]
+	userID uuid.UUID
+
+	// workspaces maps watched workspaces to their owners. It's only used by
+	// the goroutine handling the connection.
+	workspaces map[uuid.UUID]uuid.UUID
+	// owners maps the owners of watched workspaces to the function that
+	// cancels the pubsub subscription for their workspaces.
+	owners map[uuid.UUID]func()
+
+	// pending collects the updates to send for each workspace, so bursts of
+	// events for the same workspace result in a single update.
+	mu      sync.Mutex
+	pending map[uuid.UUID]*workspaceWatchUpdate
+	notify  chan struct{}
+}
+
+type workspaceWatchUpdate struct {
+	build  bool
+	agents []uuid.UUID
+}
+
+func (w *workspaceWatch) subscribe(ctx context.Context, workspaceIDs []uuid.UUID) error {
+	for _, workspaceID := range workspaceIDs {
+		if _, ok := w.workspaces[workspaceID]; ok {
+			continue
+		}
+		if len(w.workspaces) >= maxWatchedWorkspaces {
+			return w.stream.Send(codersdk.WatchEvent{
+				Type:        codersdk.WatchEventTypeError,
+				WorkspaceID: workspaceID,
+				Error: &codersdk.Response{
+					Message: fmt.Sprintf("Only %d workspaces can be watched at once.", maxWatchedWorkspaces),
+				},
+			})
+		}
+
+		workspace, err := w.api.Database.GetWorkspaceByID(ctx, workspaceID)
+		if err != nil {
+			if err := w.sendWorkspaceError(workspaceID, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := w.owners[workspace.OwnerID]; !ok {
+			cancel, err := w.api.Pubsub.SubscribeWithErr(wspubsub.WorkspaceEventChannel(workspace.OwnerID),
+				wspubsub.HandleWorkspaceEvent(w.handleEvent))
+			if err != nil {
+				err = w.stream.Send(codersdk.WatchEvent{
+					Type:        codersdk.WatchEventTypeError,
+					WorkspaceID: workspaceID,
+					Error: &codersdk.Response{
+						Message: "Internal error subscribing to workspace events.",
+						Detail:  err.Error(),
+					},
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			w.owners[workspace.OwnerID] = cancel
+		}
+		w.workspaces[workspaceID] = workspace.OwnerID
+		// Send the current state of the workspace, so events that happened
+		// before subscribing are not missed.
+		w.enqueue(workspaceID, func(*workspaceWatchUpdate) {})
+	}
+	return nil
+}
+
+func (w *workspaceWatch) unsubscribe(workspaceIDs []uuid.UUID) {
+	for _, workspaceID := range workspaceIDs {
+		ownerID, ok := w.workspaces[workspaceID]
+		if !ok {
+			continue
+		}
+		delete(w.workspaces, workspaceID)
+
+		watchingOwner := false
+		for _, id := range w.workspaces {
+			if id == ownerID {
+				watchingOwner = true
+				break
+			}
+		}
+		if !watchingOwner {
+			w.owners[ownerID]()
+			delete(w.owners, ownerID)
+		}
+	}
+}
+
+func (w *workspaceWatch) close() {
+	for _, cancel := range w.owners {
+		cancel()
+	}
+}
+
+// handleEvent is called by pubsub for events of any workspace of the owners
+// of watched workspaces.
+func (w *workspaceWatch) handleEvent(_ context.Context, event wspubsub.WorkspaceEvent, err error) {
+	if err != nil {
+		return
+	}
+	w.enqueue(event.WorkspaceID, func(update *workspaceWatchUpdate) {
+		switch event.Kind {
+		case wspubsub.WorkspaceEventKindStateChange:
+			update.build = true
+		case wspubsub.WorkspaceEventKindAgentLifecycleUpdate,
+			wspubsub.WorkspaceEventKindAgentConnectionUpdate,
+			wspubsub.WorkspaceEventKindAgentHealthUpdate:
+			if event.AgentID != nil && !slices.Contains(update.agents, *event.AgentID) {
+				update.agents = append(update.agents, *event.AgentID)
+			}
+		}
+	})
+}
+
+func (w *workspaceWatch) enqueue(workspaceID uuid.UUID, fn func(update *workspaceWatchUpdate)) {
+	w.mu.Lock()
+	update, ok := w.pending[workspaceID]
+	if !ok {
+		update = &workspaceWatchUpdate{}
+		w.pending[workspaceID] = update
+	}
+	fn(update)
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// flush sends the pending updates of watched workspaces.
+func (w *workspaceWatch) flush(ctx context.Context) error {
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = map[uuid.UUID]*workspaceWatchUpdate{}
+	w.mu.Unlock()
+
+	workspaces := make([]database.Workspace, 0, len(pending))
+	for workspaceID := range pending {
+		if _, ok := w.workspaces[workspaceID]; !ok {
+			continue
+		}
+		workspace, err := w.api.Database.GetWorkspaceByID(ctx, workspaceID)
+		if err != nil {
+			if err := w.sendWorkspaceError(workspaceID, err); err != nil {
+				return err
+			}
+			continue
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	if len(workspaces) == 0 {
+		return nil
+	}
+
+	data, err := w.api.workspaceData(ctx, workspaces)
+	if err != nil {
+		return w.stream.Send(codersdk.WatchEvent{
+			Type: codersdk.WatchEventTypeError,
+			Error: &codersdk.Response{
+				Message: "Internal error fetching workspace data.",
+				Detail:  err.Error(),
+			},
+		})
+	}
+	apiWorkspaces, err := convertWorkspaces(w.userID, workspaces, data)
+	if err != nil {
+		return w.stream.Send(codersdk.WatchEvent{
+			Type: codersdk.WatchEventTypeError,
+			Error: &codersdk.Response{
+				Message: "Internal error converting workspaces.",
+				Detail:  err.Error(),
+			},
+		})
+	}
+
+	for _, workspace := range apiWorkspaces {
+		err := w.stream.Send(codersdk.WatchEvent{
+			Type:        codersdk.WatchEventTypeWorkspace,
+			WorkspaceID: workspace.ID,
+			Workspace:   &workspace,
+		})
+		if err != nil {
+			return err
+		}
+
+		update := pending[workspace.ID]
+		if update.build {
+			err := w.stream.Send(codersdk.WatchEvent{
+				Type:           codersdk.WatchEventTypeWorkspaceBuild,
+				WorkspaceID:    workspace.ID,
+				WorkspaceBuild: &workspace.LatestBuild,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, resource := range workspace.LatestBuild.Resources {
+			for _, agent := range resource.Agents {
+				if !slices.Contains(update.agents, agent.ID) {
+					continue
+				}
+				err := w.stream.Send(codersdk.WatchEvent{
+					Type:           codersdk.WatchEventTypeWorkspaceAgent,
+					WorkspaceID:    workspace.ID,
+					WorkspaceAgent: &agent,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sendWorkspaceError tells the client that a workspace can't be watched, and
+// stops watching it.
+func (w *workspaceWatch) sendWorkspaceError(workspaceID uuid.UUID, err error) error {
+	event := codersdk.WatchEvent{
+		Type:        codersdk.WatchEventTypeError,
+		WorkspaceID: workspaceID,
+		Error: &codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		},
+	}
+	if httpapi.Is404Error(err) {
+		event.Error = &codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q not found.", workspaceID),
+		}
+	}
+	w.unsubscribe([]uuid.UUID{workspaceID})
+	return w.stream.Send(event)
+}
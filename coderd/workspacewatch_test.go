@@ -0,0 +1,123 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWatchWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database: db,
+		Pubsub:   ps,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	withAgent := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).WithAgent().Do()
+	withoutAgent := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).Do()
+	// The member can't read the workspaces of other users.
+	forbidden := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        owner.UserID,
+	}).Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, withAgent.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+
+	publish := func(event wspubsub.WorkspaceEvent) {
+		t.Helper()
+		msg, err := json.Marshal(event)
+		require.NoError(t, err)
+		err = ps.Publish(wspubsub.WorkspaceEventChannel(memberUser.ID), msg)
+		require.NoError(t, err)
+	}
+
+	watcher, err := member.Watch(ctx)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	// Subscribing sends the current state of each workspace, and an error for
+	// workspaces that can't be watched.
+	missing := uuid.New()
+	err = watcher.Subscribe(withAgent.Workspace.ID, withoutAgent.Workspace.ID, forbidden.Workspace.ID, missing)
+	require.NoError(t, err)
+	received := map[uuid.UUID]codersdk.WatchEvent{}
+	for range 4 {
+		event := testutil.RequireReceive(ctx, t, watcher.Events())
+		received[event.WorkspaceID] = event
+	}
+	require.Equal(t, codersdk.WatchEventTypeWorkspace, received[withAgent.Workspace.ID].Type)
+	require.Equal(t, withAgent.Workspace.Name, received[withAgent.Workspace.ID].Workspace.Name)
+	require.Equal(t, codersdk.WatchEventTypeWorkspace, received[withoutAgent.Workspace.ID].Type)
+	require.Equal(t, codersdk.WatchEventTypeError, received[forbidden.Workspace.ID].Type)
+	require.Equal(t, codersdk.WatchEventTypeError, received[missing].Type)
+
+	// Agent updates are sent with the updated workspace.
+	publish(wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindAgentLifecycleUpdate,
+		WorkspaceID: withAgent.Workspace.ID,
+		AgentID:     &agents[0].ID,
+	})
+	event := testutil.RequireReceive(ctx, t, watcher.Events())
+	require.Equal(t, codersdk.WatchEventTypeWorkspace, event.Type)
+	require.Equal(t, withAgent.Workspace.ID, event.WorkspaceID)
+	event = testutil.RequireReceive(ctx, t, watcher.Events())
+	require.Equal(t, codersdk.WatchEventTypeWorkspaceAgent, event.Type)
+	require.Equal(t, agents[0].ID, event.WorkspaceAgent.ID)
+
+	// Build updates are sent with the updated workspace.
+	publish(wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: withoutAgent.Workspace.ID,
+	})
+	event = testutil.RequireReceive(ctx, t, watcher.Events())
+	require.Equal(t, codersdk.WatchEventTypeWorkspace, event.Type)
+	require.Equal(t, withoutAgent.Workspace.ID, event.WorkspaceID)
+	event = testutil.RequireReceive(ctx, t, watcher.Events())
+	require.Equal(t, codersdk.WatchEventTypeWorkspaceBuild, event.Type)
+	require.Equal(t, withoutAgent.Build.ID, event.WorkspaceBuild.ID)
+
+	// Unsubscribed workspaces don't receive events. Requests are handled in
+	// order, so the error for the missing workspace signals that the
+	// unsubscribe was handled.
+	err = watcher.Unsubscribe(withAgent.Workspace.ID)
+	require.NoError(t, err)
+	err = watcher.Subscribe(missing)
+	require.NoError(t, err)
+	event = testutil.RequireReceive(ctx, t, watcher.Events())
+	require.Equal(t, codersdk.WatchEventTypeError, event.Type)
+	require.Equal(t, missing, event.WorkspaceID)
+
+	publish(wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: withAgent.Workspace.ID,
+	})
+	publish(wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStatsUpdate,
+		WorkspaceID: withoutAgent.Workspace.ID,
+	})
+	event = testutil.RequireReceive(ctx, t, watcher.Events())
+	require.Equal(t, codersdk.WatchEventTypeWorkspace, event.Type)
+	require.Equal(t, withoutAgent.Workspace.ID, event.WorkspaceID)
+}
@@ -0,0 +1,139 @@
+package wsbuilder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// PreflightCheck validates a workspace build before its provisioner job is
+// inserted, so that users get immediate feedback on builds that are bound to
+// fail instead of a failed job.
+type PreflightCheck interface {
+	// Name identifies the check in failures, e.g. "quota".
+	Name() string
+	// Check returns the reasons the build must not proceed, if any. An error
+	// is only returned if the check itself could not be run.
+	Check(ctx context.Context, req PreflightRequest) ([]PreflightFailure, error)
+}
+
+// PreflightRequest describes the build being checked. Checks must treat it as
+// read-only.
+type PreflightRequest struct {
+	// Store is the transaction the build is inserted in.
+	Store           database.Store
+	Workspace       database.Workspace
+	Transition      database.WorkspaceTransition
+	Template        database.Template
+	TemplateVersion database.TemplateVersion
+	Initiator       uuid.UUID
+	Reason          database.BuildReason
+	// LastBuild is nil if this is the first build of the workspace.
+	LastBuild *database.WorkspaceBuild
+	// Parameters are the resolved parameter values of the build. They have
+	// already been validated against the template version.
+	Parameters                  []codersdk.WorkspaceBuildParameter
+	PrebuiltWorkspaceBuildStage sdkproto.PrebuiltWorkspaceBuildStage
+}
+
+// PreflightFailure is a reason a pre-flight check rejected a build.
+type PreflightFailure struct {
+	// Check is the name of the check that failed.
+	Check string
+	// Field is the request field the failure relates to, if any.
+	Field string
+	// Code is returned to the client when this is the first failure of the
+	// build. codersdk.ResponseCodePreflightFailed is returned if it's empty.
+	Code    codersdk.ResponseCode
+	Message string
+}
+
+// PreflightError is returned, wrapped in a BuildError, when one or more
+// pre-flight checks reject a build.
+type PreflightError struct {
+	Failures []PreflightFailure
+}
+
+func (e PreflightError) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.Check, f.Message))
+	}
+	return "pre-flight checks failed: " + strings.Join(msgs, "; ")
+}
+
+// runPreflightChecks runs every check, rather than stopping at the first
+// failure, so users can fix all of them at once.
+func (b *Builder) runPreflightChecks() error {
+	if len(b.preflightChecks) == 0 {
+		return nil
+	}
+
+	template, err := b.getTemplate()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+	templateVersion, err := b.getTemplateVersion()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template version", err}
+	}
+	names, values, err := b.getParameters()
+	if err != nil {
+		// getParameters already wraps errors in BuildError
+		return err
+	}
+	req := PreflightRequest{
+		Store:                       b.store,
+		Workspace:                   b.workspace,
+		Transition:                  b.trans,
+		Template:                    *template,
+		TemplateVersion:             *templateVersion,
+		Initiator:                   b.initiator,
+		Reason:                      b.reason,
+		Parameters:                  make([]codersdk.WorkspaceBuildParameter, 0, len(names)),
+		PrebuiltWorkspaceBuildStage: b.prebuiltWorkspaceBuildStage,
+	}
+	for i, name := range names {
+		req.Parameters = append(req.Parameters, codersdk.WorkspaceBuildParameter{Name: name, Value: values[i]})
+	}
+	lastBuild, err := b.getLastBuild()
+	if err == nil {
+		req.LastBuild = lastBuild
+	} else if !xerrors.Is(err, sql.ErrNoRows) {
+		return BuildError{http.StatusInternalServerError, "failed to fetch prior build", err}
+	}
+
+	var failures []PreflightFailure
+	for _, check := range b.preflightChecks {
+		checkFailures, err := check.Check(b.ctx, req)
+		if err != nil {
+			return BuildError{
+				http.StatusInternalServerError,
+				fmt.Sprintf("failed to run %q pre-flight check", check.Name()),
+				err,
+			}
+		}
+		for _, f := range checkFailures {
+			f.Check = check.Name()
+			failures = append(failures, f)
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("The workspace build failed %d pre-flight checks.", len(failures))
+	if len(failures) == 1 {
+		msg = failures[0].Message
+	}
+	return BuildError{http.StatusBadRequest, msg, PreflightError{Failures: failures}}
+}
@@ -7,7 +7,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -59,8 +63,15 @@ type Builder struct {
 
 	richParameterValues     []codersdk.WorkspaceBuildParameter
 	initiator               uuid.UUID
+	initiatorContext        database.BuildInitiatorContext
 	reason                  database.BuildReason
 	templateVersionPresetID uuid.UUID
+	templatePresetID        uuid.UUID
+	preflightChecks         []PreflightCheck
+	rollbackBuild           *database.WorkspaceBuild
+	useParameterDefaults    bool
+	idempotencyKey          string
+	idempotencyKeyUserID    uuid.UUID
 
 	// used during build, makes function arguments less verbose
 	ctx       context.Context
@@ -71,6 +82,7 @@ type Builder struct {
 	template                             *database.Template
 	templateVersion                      *database.TemplateVersion
 	templateVersionJob                   *database.ProvisionerJob
+	activeVersionID                      *uuid.UUID
 	terraformValues                      *database.TemplateVersionTerraformValue
 	templateVersionParameters            *[]previewtypes.Parameter
 	templateVersionVariables             *[]database.TemplateVersionVariable
@@ -82,6 +94,7 @@ type Builder struct {
 	parameterNames                       *[]string
 	parameterValues                      *[]string
 	templateVersionPresetParameterValues *[]database.TemplateVersionPresetParameter
+	templatePreset                       *database.TemplatePreset
 	parameterRender                      dynamicparameters.Renderer
 
 	prebuiltWorkspaceBuildStage  sdkproto.PrebuiltWorkspaceBuildStage
@@ -114,10 +127,14 @@ type versionTarget struct {
 //
 // setting explicit to a non-nil value means to use the provided state
 //
-// orphan and explicit are mutually exclusive and setting them both results in undefined behavior.
+// setting resume: true means to use the state uploaded while the last build was applied, so a build that failed or
+// was canceled part way through can be resumed from the resources it already created.
+//
+// orphan, explicit and resume are mutually exclusive and setting more than one results in undefined behavior.
 type stateTarget struct {
 	orphan   bool
 	explicit *[]byte
+	resume   bool
 }
 
 func New(w database.Workspace, t database.WorkspaceTransition) Builder {
@@ -150,6 +167,37 @@ func (b Builder) Orphan() Builder {
 	return b
 }
 
+// Resume uses the state uploaded while the last build was applied, rather than
+// the state it completed with. The last build must have failed or been
+// canceled.
+func (b Builder) Resume() Builder {
+	// nolint: revive
+	b.state = stateTarget{resume: true}
+	return b
+}
+
+// Rollback pins the build to the template version, preset and parameter values
+// of a previous build of the workspace. Parameters are resolved against the
+// values of that build instead of the last build, so immutable parameters may
+// return to their previous values.
+func (b Builder) Rollback(build database.WorkspaceBuild) Builder {
+	// nolint: revive
+	b.version = versionTarget{specific: &build.TemplateVersionID}
+	b.templateVersionPresetID = build.TemplateVersionPresetID.UUID
+	b.rollbackBuild = &build
+	return b
+}
+
+// UseParameterDefaults resolves mutable parameters from the defaults of the
+// template version instead of the values of the last build. Values passed to
+// RichParameterValues and preset values still take precedence, and immutable
+// parameters keep their values.
+func (b Builder) UseParameterDefaults() Builder {
+	// nolint: revive
+	b.useParameterDefaults = true
+	return b
+}
+
 func (b Builder) LogLevel(l string) Builder {
 	// nolint: revive
 	b.logLevel = l
@@ -182,6 +230,25 @@ func (b Builder) Reason(r database.BuildReason) Builder {
 	return b
 }
 
+// InitiatorContext attaches structured context about what initiated the
+// build, such as the API key or automation that requested it.
+func (b Builder) InitiatorContext(c database.BuildInitiatorContext) Builder {
+	// nolint: revive
+	b.initiatorContext = c
+	return b
+}
+
+// IdempotencyKey links the build to the Idempotency-Key header of the request,
+// which the caller reserved in the same transaction. A retried request of the
+// same user with the same key returns the build instead of creating another
+// one.
+func (b Builder) IdempotencyKey(userID uuid.UUID, key string) Builder {
+	// nolint: revive
+	b.idempotencyKeyUserID = userID
+	b.idempotencyKey = key
+	return b
+}
+
 func (b Builder) RichParameterValues(p []codersdk.WorkspaceBuildParameter) Builder {
 	// nolint: revive
 	b.richParameterValues = p
@@ -232,6 +299,35 @@ func (b Builder) TemplateVersionPresetID(id uuid.UUID) Builder {
 	return b
 }
 
+// TemplatePresetID sets the template preset whose parameter values the build
+// uses. Unlike template version presets, template presets are not tied to a
+// template version, so parameters the version does not define are ignored.
+func (b Builder) TemplatePresetID(id uuid.UUID) Builder {
+	// nolint: revive
+	b.templatePresetID = id
+	return b
+}
+
+// PreflightChecks sets the checks run before the provisioner job is inserted.
+// See PreflightCheck.
+func (b Builder) PreflightChecks(checks ...PreflightCheck) Builder {
+	// nolint: revive
+	b.preflightChecks = append([]PreflightCheck(nil), checks...)
+	return b
+}
+
+// ErrBuildActive is wrapped by the error returned when a build is requested
+// while another build of the workspace is active.
+var ErrBuildActive = xerrors.New("A workspace build is already active.")
+
+// ErrWorkspaceLocked is wrapped by the error returned when a build is requested
+// while the workspace is locked by an administrator.
+var ErrWorkspaceLocked = xerrors.New("The workspace is locked.")
+
+// ErrJobLimitReached is wrapped by the error returned when a build is requested
+// while the initiator has reached their limit of concurrent provisioner jobs.
+var ErrJobLimitReached = xerrors.New("The concurrent provisioner job limit has been reached.")
+
 type BuildError struct {
 	// Status is a suitable HTTP status code
 	Status  int
@@ -247,6 +343,15 @@ func (e BuildError) Unwrap() error {
 	return e.Wrapped
 }
 
+// Authorize performs the authorization preflight checks of Build without
+// building. It's used to authorize builds that are started later, on behalf of
+// the initiator.
+func (b *Builder) Authorize(ctx context.Context, store database.Store, authFunc func(action policy.Action, object rbac.Objecter) bool) error {
+	b.ctx = ctx
+	b.store = store
+	return b.authorize(authFunc)
+}
+
 // Build computes and inserts a new workspace build into the database.  If authFunc is provided, it also performs
 // authorization preflight checks.
 func (b *Builder) Build(
@@ -275,12 +380,32 @@ func (b *Builder) Build(
 	var workspaceBuild *database.WorkspaceBuild
 	var provisionerJob *database.ProvisionerJob
 	var provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
-	err = database.ReadModifyUpdate(store, func(tx database.Store) error {
+	limited, err := b.hasConcurrentJobLimit(store)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	buildTx := func(tx database.Store) error {
 		var err error
 		b.store = tx
+		if limited {
+			err = b.lockInitiator()
+			if err != nil {
+				return err
+			}
+		}
 		workspaceBuild, provisionerJob, provisionerDaemons, err = b.buildTx(authFunc)
 		return err
-	})
+	}
+	if limited {
+		// Builds that are subject to a concurrent job limit are serialized per
+		// initiator, so concurrent builds can't all pass the limit. A
+		// RepeatableRead snapshot is taken before the lock is acquired and would
+		// miss the jobs inserted by the previous holder of the lock, so these
+		// builds run with ReadCommitted isolation instead.
+		err = store.InTx(buildTx, &database.TxOptions{Isolation: sql.LevelReadCommitted})
+	} else {
+		err = database.ReadModifyUpdate(store, buildTx)
+	}
 	if err != nil {
 		return nil, nil, nil, xerrors.Errorf("build tx: %w", err)
 	}
@@ -314,6 +439,18 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	err = b.checkWorkspaceLock()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = b.checkResumable()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = b.checkTemplatePreset()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	template, err := b.getTemplate()
 	if err != nil {
@@ -335,6 +472,21 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 	if b.reason == "" {
 		b.reason = database.BuildReasonInitiator
 	}
+	err = b.checkConcurrentJobLimit()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Resolve and validate parameters before inserting the job, so invalid
+	// values are reported to the user rather than failing the job.
+	names, values, err := b.getParameters()
+	if err != nil {
+		// getParameters already wraps errors in BuildError
+		return nil, nil, nil, err
+	}
+	err = b.runPreflightChecks()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	workspaceBuildID := uuid.New()
 	input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
@@ -358,6 +510,13 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 	if err != nil {
 		return nil, nil, nil, err // already wrapped BuildError
 	}
+	if missing := provisionersdk.MissingTags(template.RequiredProvisionerTags, tags); len(missing) > 0 {
+		return nil, nil, nil, BuildError{
+			http.StatusBadRequest,
+			"The template requires its jobs to run on provisioners with specific tags.",
+			xerrors.Errorf("build does not target required provisioner tags: %s", strings.Join(missing, ", ")),
+		}
+	}
 
 	now := dbtime.Now()
 	provisionerJob, err := b.store.InsertProvisionerJob(b.ctx, database.InsertProvisionerJobParams{
@@ -405,6 +564,10 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 	if err != nil {
 		return nil, nil, nil, BuildError{http.StatusInternalServerError, "compute build state", err}
 	}
+	warnings, err := b.getWarnings()
+	if err != nil {
+		return nil, nil, nil, BuildError{http.StatusInternalServerError, "compute build warnings", err}
+	}
 
 	var workspaceBuild database.WorkspaceBuild
 	err = b.store.InTx(func(store database.Store) error {
@@ -426,6 +589,8 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 				UUID:  b.templateVersionPresetID,
 				Valid: b.templateVersionPresetID != uuid.Nil,
 			},
+			InitiatorContext: b.initiatorContext,
+			Warnings:         warnings,
 		})
 		if err != nil {
 			code := http.StatusInternalServerError
@@ -439,12 +604,6 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 			return BuildError{code, "insert workspace build", err}
 		}
 
-		names, values, err := b.getParameters()
-		if err != nil {
-			// getParameters already wraps errors in BuildError
-			return err
-		}
-
 		err = store.InsertWorkspaceBuildParameters(b.ctx, database.InsertWorkspaceBuildParametersParams{
 			WorkspaceBuildID: workspaceBuildID,
 			Name:             names,
@@ -454,6 +613,17 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 			return BuildError{http.StatusInternalServerError, "insert workspace build parameters: %w", err}
 		}
 
+		if b.idempotencyKey != "" {
+			err = store.UpdateWorkspaceBuildIdempotencyKeyBuildID(b.ctx, database.UpdateWorkspaceBuildIdempotencyKeyBuildIDParams{
+				WorkspaceBuildID: workspaceBuildID,
+				UserID:           b.idempotencyKeyUserID,
+				IdempotencyKey:   b.idempotencyKey,
+			})
+			if err != nil {
+				return BuildError{http.StatusInternalServerError, "update workspace build idempotency key", err}
+			}
+		}
+
 		workspaceBuild, err = store.GetWorkspaceBuildByID(b.ctx, workspaceBuildID)
 		if err != nil {
 			return BuildError{http.StatusInternalServerError, "get workspace build", err}
@@ -561,11 +731,7 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 		return *b.version.specific, nil
 	}
 	if b.version.active {
-		t, err := b.getTemplate()
-		if err != nil {
-			return uuid.Nil, xerrors.Errorf("get template so we can get active version: %w", err)
-		}
-		return t.ActiveVersionID, nil
+		return b.getActiveVersionID()
 	}
 	// default is prior version
 	bld, err := b.getLastBuild()
@@ -575,6 +741,152 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 	return bld.TemplateVersionID, nil
 }
 
+// getActiveVersionID returns the version that builds targeting the active
+// version use. That is the version published to the release channel the
+// workspace tracks, if any, then the version being rolled out if the workspace
+// is part of the rollout of the template, and the active version otherwise.
+func (b *Builder) getActiveVersionID() (uuid.UUID, error) {
+	if b.activeVersionID != nil {
+		return *b.activeVersionID, nil
+	}
+	t, err := b.getTemplate()
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get template so we can get active version: %w", err)
+	}
+	channelVersionID, err := b.getReleaseChannelVersionID(t.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if channelVersionID != uuid.Nil {
+		b.activeVersionID = &channelVersionID
+		return channelVersionID, nil
+	}
+	versionID := t.ActiveVersionID
+	rollout, err := b.store.GetTemplateVersionRolloutByTemplateID(b.ctx, t.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, xerrors.Errorf("get template version rollout: %w", err)
+	}
+	if err == nil && rollout.TemplateVersionID != t.ActiveVersionID {
+		inRollout := RolloutBucket(b.workspace.ID) < rollout.Percent
+		if !inRollout && rollout.GroupID.Valid {
+			inRollout, err = b.store.IsTemplateVersionRolloutCohortMember(b.ctx, database.IsTemplateVersionRolloutCohortMemberParams{
+				TemplateID: t.ID,
+				UserID:     b.workspace.OwnerID,
+			})
+			if err != nil {
+				return uuid.Nil, xerrors.Errorf("check template version rollout cohort: %w", err)
+			}
+		}
+		if inRollout {
+			versionID = rollout.TemplateVersionID
+		}
+	}
+	b.activeVersionID = &versionID
+	return versionID, nil
+}
+
+// getReleaseChannelVersionID returns the version published to the release
+// channel the workspace tracks. It returns uuid.Nil if the workspace does not
+// track a channel, or nothing is published to the channel, in which case the
+// workspace follows the active version of the template.
+func (b *Builder) getReleaseChannelVersionID(templateID uuid.UUID) (uuid.UUID, error) {
+	tracked, err := b.store.GetWorkspaceReleaseChannelByWorkspaceID(b.ctx, b.workspace.ID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get workspace release channel: %w", err)
+	}
+	channel, err := b.store.GetTemplateReleaseChannel(b.ctx, database.GetTemplateReleaseChannelParams{
+		TemplateID: templateID,
+		Channel:    tracked.Channel,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get template release channel: %w", err)
+	}
+	return channel.TemplateVersionID, nil
+}
+
+// RolloutBucket returns the bucket, between 0 and 99, that a workspace falls in
+// for template version rollouts. Workspaces in buckets below the percentage of
+// a rollout build the version being rolled out. Buckets are stable, so raising
+// the percentage only adds workspaces to the rollout.
+func RolloutBucket(workspaceID uuid.UUID) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write(workspaceID[:])
+	// #nosec G115 - Safe conversion as the bucket is always below 100.
+	return int32(h.Sum32() % 100)
+}
+
+// getWarnings returns the warnings to record on the build, which suggest
+// updating the workspace. Only builds that start the workspace are warned
+// about, since stopping or deleting it uses the version of its last build.
+func (b *Builder) getWarnings() (database.WorkspaceBuildWarnings, error) {
+	if b.trans != database.WorkspaceTransitionStart {
+		return nil, nil
+	}
+	t, err := b.getTemplate()
+	if err != nil {
+		return nil, xerrors.Errorf("get template so we can get warnings: %w", err)
+	}
+	v, err := b.getTemplateVersion()
+	if err != nil {
+		return nil, xerrors.Errorf("get template version so we can get warnings: %w", err)
+	}
+
+	var warnings database.WorkspaceBuildWarnings
+	if t.Deprecated != "" {
+		warnings = append(warnings, database.WorkspaceBuildWarning{
+			Code:    string(codersdk.WorkspaceBuildWarningDeprecatedTemplate),
+			Message: fmt.Sprintf("The template of the workspace is deprecated: %s", t.Deprecated),
+		})
+	}
+
+	activeVersionID := t.ActiveVersionID
+	if b.activeVersionID != nil {
+		// The build targeted the active version, which may be the version
+		// being rolled out to the workspace.
+		activeVersionID = *b.activeVersionID
+	}
+	if v.ID == activeVersionID {
+		return warnings, nil
+	}
+	newer, err := b.store.GetTemplateVersionsByTemplateID(b.ctx, database.GetTemplateVersionsByTemplateIDParams{
+		TemplateID: t.ID,
+		Archived:   sql.NullBool{Bool: false, Valid: true},
+		AfterID:    v.ID,
+	})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get newer template versions: %w", err)
+	}
+	// The versions behind end at the active version. If it isn't among the
+	// newer versions, the version of the build is newer than the active
+	// version.
+	var behind []string
+	for i, newerVersion := range newer {
+		if newerVersion.ID == t.ActiveVersionID {
+			behind = db2sdk.List(newer[:i+1], func(tv database.TemplateVersion) string {
+				return tv.Name
+			})
+			break
+		}
+	}
+
+	message := fmt.Sprintf("Template version %q is not the active version of the template.", v.Name)
+	if len(behind) > 0 {
+		message = fmt.Sprintf("Template version %q is behind the active version of the template, %q, by %d version(s).", v.Name, behind[len(behind)-1], len(behind))
+	}
+	warnings = append(warnings, database.WorkspaceBuildWarning{
+		Code:           string(codersdk.WorkspaceBuildWarningInactiveTemplateVersion),
+		Message:        message,
+		VersionsBehind: behind,
+	})
+	return warnings, nil
+}
+
 func (b *Builder) getTemplateTerraformValues() (*database.TemplateVersionTerraformValue, error) {
 	if b.terraformValues != nil {
 		return b.terraformValues, nil
@@ -687,6 +999,22 @@ func (b *Builder) getState() ([]byte, error) {
 	if b.state.explicit != nil {
 		return *b.state.explicit, nil
 	}
+	if b.state.resume {
+		bld, err := b.getLastBuild()
+		if err != nil {
+			return nil, xerrors.Errorf("get last build to get interim state: %w", err)
+		}
+		interim, err := b.store.GetWorkspaceBuildInterimStateByBuildID(b.ctx, bld.ID)
+		if err == nil {
+			return interim.State, nil
+		}
+		if !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get last build %s interim state: %w", bld.ID, err)
+		}
+		// No state was uploaded before the build stopped, so the state it
+		// failed with is the most recent.
+		return bld.ProvisionerState, nil
+	}
 	// Default is to use state from prior build
 	bld, err := b.getLastBuild()
 	if xerrors.Is(err, sql.ErrNoRows) {
@@ -831,6 +1159,14 @@ func (b *Builder) getLastBuildParameters() ([]database.WorkspaceBuildParameter,
 	if b.lastBuildParameters != nil {
 		return *b.lastBuildParameters, nil
 	}
+	if b.rollbackBuild != nil {
+		values, err := b.store.GetWorkspaceBuildParameters(b.ctx, b.rollbackBuild.ID)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get rollback build %s parameters: %w", b.rollbackBuild.ID, err)
+		}
+		b.lastBuildParameters = &values
+		return values, nil
+	}
 	bld, err := b.getLastBuild()
 	if xerrors.Is(err, sql.ErrNoRows) {
 		// if the build doesn't exist, then clearly there can be no parameters.
@@ -844,10 +1180,38 @@ func (b *Builder) getLastBuildParameters() ([]database.WorkspaceBuildParameter,
 	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
 		return nil, xerrors.Errorf("get last build %s parameters: %w", bld.ID, err)
 	}
+	if b.useParameterDefaults {
+		values, err = b.withoutMutableParameters(values)
+		if err != nil {
+			return nil, err
+		}
+	}
 	b.lastBuildParameters = &values
 	return values, nil
 }
 
+// withoutMutableParameters drops the values of the parameters that are mutable
+// in the template version, so they are resolved from their defaults. Values of
+// immutable parameters and parameters unknown to the version are kept.
+func (b *Builder) withoutMutableParameters(values []database.WorkspaceBuildParameter) ([]database.WorkspaceBuildParameter, error) {
+	templateVersionParameters, err := b.getTemplateVersionParameters()
+	if err != nil {
+		return nil, xerrors.Errorf("get template version parameters: %w", err)
+	}
+	mutable := make(map[string]bool, len(templateVersionParameters))
+	for _, p := range templateVersionParameters {
+		mutable[p.Name] = p.Mutable
+	}
+	kept := make([]database.WorkspaceBuildParameter, 0, len(values))
+	for _, v := range values {
+		if mutable[v.Name] {
+			continue
+		}
+		kept = append(kept, v)
+	}
+	return kept, nil
+}
+
 func (b *Builder) getTemplateVersionParameters() ([]previewtypes.Parameter, error) {
 	if b.templateVersionParameters != nil {
 		return *b.templateVersionParameters, nil
@@ -1020,6 +1384,23 @@ func (b *Builder) getPresetParameterValues() ([]database.TemplateVersionPresetPa
 		return *b.templateVersionPresetParameterValues, nil
 	}
 
+	if b.templatePresetID != uuid.Nil {
+		preset, err := b.getTemplatePreset()
+		if err != nil {
+			return nil, xerrors.Errorf("get template preset: %w", err)
+		}
+		names := slices.Sorted(maps.Keys(preset.Parameters))
+		presetParameters := make([]database.TemplateVersionPresetParameter, 0, len(names))
+		for _, name := range names {
+			presetParameters = append(presetParameters, database.TemplateVersionPresetParameter{
+				Name:  name,
+				Value: preset.Parameters[name],
+			})
+		}
+		b.templateVersionPresetParameterValues = ptr.Ref(presetParameters)
+		return *b.templateVersionPresetParameterValues, nil
+	}
+
 	if b.templateVersionPresetID == uuid.Nil {
 		return []database.TemplateVersionPresetParameter{}, nil
 	}
@@ -1033,6 +1414,42 @@ func (b *Builder) getPresetParameterValues() ([]database.TemplateVersionPresetPa
 	return *b.templateVersionPresetParameterValues, nil
 }
 
+func (b *Builder) getTemplatePreset() (*database.TemplatePreset, error) {
+	if b.templatePreset != nil {
+		return b.templatePreset, nil
+	}
+	preset, err := b.store.GetTemplatePresetByID(b.ctx, b.templatePresetID)
+	if err != nil {
+		return nil, err
+	}
+	b.templatePreset = &preset
+	return b.templatePreset, nil
+}
+
+// checkTemplatePreset verifies that the template preset of the build, if any,
+// belongs to the template of the workspace.
+func (b *Builder) checkTemplatePreset() error {
+	if b.templatePresetID == uuid.Nil {
+		return nil
+	}
+	if b.templateVersionPresetID != uuid.Nil {
+		msg := "A build cannot use both a template preset and a template version preset."
+		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
+	preset, err := b.getTemplatePreset()
+	if httpapi.Is404Error(err) {
+		return BuildError{http.StatusBadRequest, "The template preset does not exist.", err}
+	}
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template preset", err}
+	}
+	if preset.TemplateID != b.workspace.TemplateID {
+		msg := "The template preset does not belong to the template of the workspace."
+		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
+	return nil
+}
+
 // authorize performs build authorization pre-checks using the provided authFunc
 func (b *Builder) authorize(authFunc func(action policy.Action, object rbac.Objecter) bool) error {
 	// Doing this up front saves a lot of work if the user doesn't have permission.
@@ -1176,16 +1593,145 @@ func (b *Builder) checkRunningBuild() error {
 		return BuildError{http.StatusInternalServerError, "failed to fetch prior build", err}
 	}
 	if codersdk.ProvisionerJobStatus(job.JobStatus).Active() {
-		msg := "A workspace build is already active."
 		return BuildError{
 			http.StatusConflict,
-			msg,
-			xerrors.New(msg),
+			ErrBuildActive.Error(),
+			ErrBuildActive,
 		}
 	}
 	return nil
 }
 
+// checkWorkspaceLock rejects all builds of a locked workspace, including the
+// ones started by the system.
+func (b *Builder) checkWorkspaceLock() error {
+	// nolint:gocritic // Locks apply to every initiator, whether or not they
+	// are allowed to read them.
+	lock, err := b.store.GetActiveWorkspaceLockByWorkspaceID(dbauthz.AsSystemRestricted(b.ctx), b.workspace.ID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch workspace lock", err}
+	}
+	return BuildError{
+		http.StatusConflict,
+		fmt.Sprintf("%s Reason: %s", ErrWorkspaceLocked.Error(), lock.Reason),
+		ErrWorkspaceLocked,
+	}
+}
+
+// checkResumable ensures there is a build to resume, i.e. that the last build
+// failed or was canceled.
+func (b *Builder) checkResumable() error {
+	if !b.state.resume {
+		return nil
+	}
+	job, err := b.getLastBuildJob()
+	if xerrors.Is(err, sql.ErrNoRows) {
+		msg := "The workspace has no build to resume."
+		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch prior build", err}
+	}
+	switch job.JobStatus {
+	case database.ProvisionerJobStatusFailed, database.ProvisionerJobStatusCanceled:
+		return nil
+	default:
+		msg := fmt.Sprintf("Only failed or canceled builds can be resumed, the last build is %s.", job.JobStatus)
+		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
+}
+
+// isLimitedInitiator returns true if builds by the initiator count towards
+// the concurrent job limits. It can be called before buildTx sets the
+// defaults for the initiator and reason.
+func (b *Builder) isLimitedInitiator() bool {
+	initiator := b.initiator
+	if initiator == uuid.Nil {
+		initiator = b.workspace.OwnerID
+	}
+	return (b.reason == "" || b.reason == database.BuildReasonInitiator) && initiator != database.PrebuildsSystemUserID
+}
+
+func (b *Builder) deploymentJobLimit() int64 {
+	if b.deploymentValues == nil {
+		return 0
+	}
+	return b.deploymentValues.Provisioner.MaxConcurrentJobsPerUser.Value()
+}
+
+// hasConcurrentJobLimit returns true if the build is subject to a concurrent
+// job limit. It runs before the build transaction starts, since the
+// isolation level of the transaction depends on it.
+func (b *Builder) hasConcurrentJobLimit(store database.Store) (bool, error) {
+	if !b.isLimitedInitiator() {
+		return false, nil
+	}
+	if b.deploymentJobLimit() > 0 {
+		return true, nil
+	}
+	// nolint:gocritic // The limit applies regardless of whether the initiator
+	// can read the template.
+	template, err := store.GetTemplateByID(dbauthz.AsSystemRestricted(b.ctx), b.workspace.TemplateID)
+	if err != nil {
+		return false, BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+	return template.MaxConcurrentJobsPerUser > 0, nil
+}
+
+// lockInitiator takes a transaction lock for the initiator of the build, which
+// is held until the job is inserted and the transaction commits.
+func (b *Builder) lockInitiator() error {
+	initiator := b.initiator
+	if initiator == uuid.Nil {
+		initiator = b.workspace.OwnerID
+	}
+	err := b.store.AcquireLock(b.ctx, database.GenLockID(fmt.Sprintf("provisioner-jobs-initiator:%s", initiator)))
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to lock initiator", err}
+	}
+	return nil
+}
+
+// checkConcurrentJobLimit enforces the deployment and template limits on the
+// number of pending and running provisioner jobs a single user may have. Builds
+// that are not initiated by a user, such as autostart and prebuilds, are exempt.
+func (b *Builder) checkConcurrentJobLimit() error {
+	if !b.isLimitedInitiator() {
+		return nil
+	}
+	template, err := b.getTemplate()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+	deploymentLimit := b.deploymentJobLimit()
+	templateLimit := int64(template.MaxConcurrentJobsPerUser)
+	if deploymentLimit <= 0 && templateLimit <= 0 {
+		return nil
+	}
+
+	// nolint:gocritic // Users are not necessarily allowed to read provisioner
+	// jobs, but they must be able to hit the limit.
+	counts, err := b.store.CountInProgressProvisionerJobsByInitiator(dbauthz.AsSystemRestricted(b.ctx), database.CountInProgressProvisionerJobsByInitiatorParams{
+		TemplateID:  template.ID,
+		InitiatorID: b.initiator,
+	})
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to count in-progress provisioner jobs", err}
+	}
+	if deploymentLimit > 0 && counts.Total >= deploymentLimit {
+		msg := fmt.Sprintf("You have reached the limit of %d concurrent provisioner jobs. Wait for your pending and running builds to finish before starting another.", deploymentLimit)
+		return BuildError{http.StatusConflict, msg, ErrJobLimitReached}
+	}
+	if templateLimit > 0 && counts.TemplateBuilds >= templateLimit {
+		msg := fmt.Sprintf("You have reached the limit of %d concurrent builds for template %q. Wait for your pending and running builds to finish before starting another.", templateLimit, template.Name)
+		return BuildError{http.StatusConflict, msg, ErrJobLimitReached}
+	}
+	return nil
+}
+
 func (b *Builder) usingDynamicParameters() bool {
 	tpl, err := b.getTemplate()
 	if err != nil {
@@ -68,6 +68,7 @@ func TestBuilder_NoOptions(t *testing.T) {
 		withParameterSchemas(inactiveJobID, nil),
 		withWorkspaceTags(inactiveVersionID, nil),
 		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+		withNewerTemplateVersions(nil),
 
 		// Outputs
 		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
@@ -125,6 +126,7 @@ func TestBuilder_Initiator(t *testing.T) {
 		withParameterSchemas(inactiveJobID, nil),
 		withWorkspaceTags(inactiveVersionID, nil),
 		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+		withNewerTemplateVersions(nil),
 
 		// Outputs
 		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
@@ -172,6 +174,7 @@ func TestBuilder_Baggage(t *testing.T) {
 		withParameterSchemas(inactiveJobID, nil),
 		withWorkspaceTags(inactiveVersionID, nil),
 		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+		withNewerTemplateVersions(nil),
 
 		// Outputs
 		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
@@ -211,6 +214,7 @@ func TestBuilder_Reason(t *testing.T) {
 		withParameterSchemas(inactiveJobID, nil),
 		withWorkspaceTags(inactiveVersionID, nil),
 		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+		withNewerTemplateVersions(nil),
 
 		// Outputs
 		expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {
@@ -232,48 +236,759 @@ func TestBuilder_Reason(t *testing.T) {
 	req.NoError(err)
 }
 
+func TestBuilder_InitiatorContext(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initiatorContext := database.BuildInitiatorContext{
+		Automation: "lifecycle_executor",
+		Schedule:   "CRON_TZ=UTC 0 9 * * 1-5",
+	}
+
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withInactiveVersion(nil),
+		withLastBuildFound,
+		withTemplateVersionVariables(inactiveVersionID, nil),
+		withRichParameters(nil),
+		withParameterSchemas(inactiveJobID, nil),
+		withWorkspaceTags(inactiveVersionID, nil),
+		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+		withNewerTemplateVersions(nil),
+
+		// Outputs
+		expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {
+		}),
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			asrt.Equal(database.BuildReasonAutostart, bld.Reason)
+			asrt.Equal(initiatorContext, bld.InitiatorContext)
+		}),
+		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+		}),
+		withBuild,
+	)
+	fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).
+		Reason(database.BuildReasonAutostart).
+		InitiatorContext(initiatorContext)
+	// nolint: dogsled
+	_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
+func TestBuilder_Locked(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withInactiveVersionNoParams(),
+		withLastBuildFound,
+		withWorkspaceLock("incident"),
+
+		// Outputs
+		// no provisioner job, since the workspace is locked
+	)
+	fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStop).Reason(database.BuildReasonAutostop)
+	// nolint: dogsled
+	_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+	req.ErrorIs(err, wsbuilder.ErrWorkspaceLocked)
+	var buildErr wsbuilder.BuildError
+	req.ErrorAs(err, &buildErr)
+	req.Equal(http.StatusConflict, buildErr.Status)
+	req.Contains(buildErr.Message, "incident")
+}
+
+func TestBuilder_ConcurrentJobLimit(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctrl := gomock.NewController(t)
+	mDB := dbmock.NewMockStore(ctrl)
+	mTx := dbmock.NewMockStore(ctrl)
+
+	// Limited builds lock the initiator inside the transaction, so they
+	// can't use a RepeatableRead snapshot taken before the lock.
+	mDB.EXPECT().InTx(
+		gomock.Any(), gomock.Eq(&database.TxOptions{Isolation: sql.LevelReadCommitted}),
+	).
+		DoAndReturn(func(f func(database.Store) error, _ *database.TxOptions) error {
+			return f(mTx)
+		})
+	lock := mTx.EXPECT().AcquireLock(gomock.Any(), database.GenLockID("provisioner-jobs-initiator:"+userID.String())).
+		Times(1).
+		Return(nil)
+	mTx.EXPECT().CountInProgressProvisionerJobsByInitiator(gomock.Any(), database.CountInProgressProvisionerJobsByInitiatorParams{
+		TemplateID:  templateID,
+		InitiatorID: userID,
+	}).
+		After(lock).
+		Times(1).
+		Return(database.CountInProgressProvisionerJobsByInitiatorRow{Total: 1}, nil)
+	mTx.EXPECT().GetActiveWorkspaceLockByWorkspaceID(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(database.WorkspaceLock{}, sql.ErrNoRows)
+	withTemplate(mTx)
+	withInactiveVersionNoParams()(mTx)
+	withLastBuildFound(mTx)
+	// no provisioner job, since the limit is reached
+	fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.Provisioner.MaxConcurrentJobsPerUser = 1
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStop).DeploymentValues(dv)
+	// nolint: dogsled
+	_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+	req.ErrorIs(err, wsbuilder.ErrJobLimitReached)
+	var buildErr wsbuilder.BuildError
+	req.ErrorAs(err, &buildErr)
+	req.Equal(http.StatusConflict, buildErr.Status)
+}
+
+func TestBuilder_PreflightChecks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Pass", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withInactiveVersion(nil),
+			withLastBuildFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+			withNewerTemplateVersions(nil),
+
+			// Outputs
+			expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {
+			}),
+			withInTx,
+			expectBuild(func(_ database.InsertWorkspaceBuildParams) {
+			}),
+			expectBuildParameters(func(_ database.InsertWorkspaceBuildParametersParams) {
+			}),
+			withBuild,
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		check := &fakePreflightCheck{name: "pass"}
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).PreflightChecks(check)
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+
+		req.NotNil(check.req)
+		asrt.Equal(templateID, check.req.Template.ID)
+		asrt.Equal(inactiveVersionID, check.req.TemplateVersion.ID)
+		asrt.Equal(userID, check.req.Initiator)
+		asrt.Equal(database.BuildReasonInitiator, check.req.Reason)
+		req.NotNil(check.req.LastBuild)
+		asrt.Equal(lastBuildID, check.req.LastBuild.ID)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withInactiveVersion(nil),
+			withLastBuildFound,
+			withRichParameters(nil),
+			withParameterSchemas(inactiveJobID, nil),
+
+			// Outputs
+			// no provisioner job, since the pre-flight checks failed
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).PreflightChecks(
+			&fakePreflightCheck{name: "first", failures: []wsbuilder.PreflightFailure{{Message: "first failed"}}},
+			&fakePreflightCheck{name: "pass"},
+			&fakePreflightCheck{name: "second", failures: []wsbuilder.PreflightFailure{{Field: "template_id", Message: "second failed"}}},
+		)
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		bldErr := wsbuilder.BuildError{}
+		req.ErrorAs(err, &bldErr)
+		asrt.Equal(http.StatusBadRequest, bldErr.Status)
+		preflightErr := wsbuilder.PreflightError{}
+		req.ErrorAs(err, &preflightErr)
+		asrt.Equal([]wsbuilder.PreflightFailure{
+			{Check: "first", Message: "first failed"},
+			{Check: "second", Field: "template_id", Message: "second failed"},
+		}, preflightErr.Failures)
+	})
+}
+
+func TestBuilder_Resume(t *testing.T) {
+	t.Parallel()
+
+	t.Run("InterimState", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withInactiveVersion(nil),
+			withLastBuildJobStatus(database.ProvisionerJobStatusFailed),
+			withInterimState([]byte("interim state"), nil),
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+			withNewerTemplateVersions(nil),
+
+			// Outputs
+			expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {
+			}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				asrt.Equal("interim state", string(bld.ProvisionerState))
+			}),
+			expectBuildParameters(func(_ database.InsertWorkspaceBuildParametersParams) {
+			}),
+			withBuild,
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Resume()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
+
+	t.Run("NoInterimState", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withInactiveVersion(nil),
+			withLastBuildJobStatus(database.ProvisionerJobStatusCanceled),
+			withInterimState(nil, sql.ErrNoRows),
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+			withNewerTemplateVersions(nil),
+
+			// Outputs
+			expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {
+			}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				// Falls back to the state the last build stopped with.
+				asrt.Equal("last build state", string(bld.ProvisionerState))
+			}),
+			expectBuildParameters(func(_ database.InsertWorkspaceBuildParametersParams) {
+			}),
+			withBuild,
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Resume()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
+
+	t.Run("LastBuildSucceeded", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withInactiveVersionNoParams(),
+			withLastBuildFound,
+
+			// Outputs
+			// no provisioner job, since there is nothing to resume
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Resume()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		bldErr := wsbuilder.BuildError{}
+		req.ErrorAs(err, &bldErr)
+		asrt.Equal(http.StatusBadRequest, bldErr.Status)
+	})
+}
+
+func TestBuilder_Rollback(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rollbackBuildID := uuid.New()
+	presetID := uuid.New()
+	richParameters := []database.TemplateVersionParameter{
+		{Name: "immutable_parameter", Mutable: false, Options: json.RawMessage("[]")},
+	}
+
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withInactiveVersion(richParameters),
+		withLastBuildFound,
+		withTemplateVersionVariables(inactiveVersionID, nil),
+		// The parameters are resolved against the build that is rolled
+		// back to, not the last build.
+		func(mTx *dbmock.MockStore) {
+			mTx.EXPECT().GetWorkspaceBuildParameters(gomock.Any(), rollbackBuildID).
+				Times(1).
+				Return([]database.WorkspaceBuildParameter{
+					{WorkspaceBuildID: rollbackBuildID, Name: "immutable_parameter", Value: "previous"},
+				}, nil)
+			mTx.EXPECT().GetPresetParametersByPresetID(gomock.Any(), presetID).
+				Times(1).
+				Return(nil, nil)
+		},
+		withParameterSchemas(inactiveJobID, nil),
+		withWorkspaceTags(inactiveVersionID, nil),
+		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+		withNewerTemplateVersions(nil),
+
+		// Outputs
+		expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {
+		}),
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			asrt.Equal(inactiveVersionID, bld.TemplateVersionID)
+			asrt.Equal(presetID, bld.TemplateVersionPresetID.UUID)
+		}),
+		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+			asrt.Equal([]string{"immutable_parameter"}, params.Name)
+			asrt.Equal([]string{"previous"}, params.Value)
+		}),
+		withBuild,
+	)
+	fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Rollback(database.WorkspaceBuild{
+		ID:                      rollbackBuildID,
+		WorkspaceID:             workspaceID,
+		TemplateVersionID:       inactiveVersionID,
+		TemplateVersionPresetID: uuid.NullUUID{UUID: presetID, Valid: true},
+		Transition:              database.WorkspaceTransitionStart,
+	})
+	// nolint: dogsled
+	_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
 func TestBuilder_ActiveVersion(t *testing.T) {
 	t.Parallel()
-	req := require.New(t)
-	asrt := assert.New(t)
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withReleaseChannel(nil),
+		withRollout(nil),
+		withActiveVersion(nil),
+		withLastBuildNotFound,
+		withTemplateVersionVariables(activeVersionID, nil),
+		withParameterSchemas(activeJobID, nil),
+		withWorkspaceTags(activeVersionID, nil),
+		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+		// previous rich parameters are not queried because there is no previous build.
+
+		// Outputs
+		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+			asrt.Equal(activeFileID, job.FileID)
+		}),
+
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			asrt.Equal(activeVersionID, bld.TemplateVersionID)
+			// no previous build...
+			asrt.Equal(int32(1), bld.BuildNumber)
+			asrt.Len(bld.ProvisionerState, 0)
+		}),
+		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+		}),
+		withBuild,
+	)
+	fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+	// nolint: dogsled
+	_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
+func TestBuilder_ActiveVersionRollout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Percent", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withReleaseChannel(nil),
+			withRollout(&database.TemplateVersionRollout{
+				TemplateID:        templateID,
+				TemplateVersionID: inactiveVersionID,
+				Percent:           100,
+			}),
+			withInactiveVersion(nil),
+			withLastBuildNotFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+				asrt.Equal(inactiveFileID, job.FileID)
+			}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				asrt.Equal(inactiveVersionID, bld.TemplateVersionID)
+			}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+			}),
+			withBuild,
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
+
+	t.Run("Cohort", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withReleaseChannel(nil),
+			withRollout(&database.TemplateVersionRollout{
+				TemplateID:        templateID,
+				TemplateVersionID: inactiveVersionID,
+				Percent:           0,
+				GroupID:           uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			}),
+			func(mTx *dbmock.MockStore) {
+				mTx.EXPECT().IsTemplateVersionRolloutCohortMember(gomock.Any(), database.IsTemplateVersionRolloutCohortMemberParams{
+					TemplateID: templateID,
+					UserID:     userID,
+				}).
+					Times(1).
+					Return(true, nil)
+			},
+			withInactiveVersion(nil),
+			withLastBuildNotFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+				asrt.Equal(inactiveFileID, job.FileID)
+			}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				asrt.Equal(inactiveVersionID, bld.TemplateVersionID)
+			}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+			}),
+			withBuild,
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
+}
+
+func TestBuilder_ActiveVersionReleaseChannel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Published", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			// The rollout is not consulted for workspaces that track a channel
+			// with a published version.
+			withReleaseChannel(&database.TemplateReleaseChannel{
+				TemplateID:        templateID,
+				Channel:           database.ReleaseChannelBeta,
+				TemplateVersionID: inactiveVersionID,
+			}),
+			withInactiveVersion(nil),
+			withLastBuildNotFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+				asrt.Equal(inactiveFileID, job.FileID)
+			}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				asrt.Equal(inactiveVersionID, bld.TemplateVersionID)
+			}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+			}),
+			withBuild,
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
+
+	t.Run("Unpublished", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			func(mTx *dbmock.MockStore) {
+				mTx.EXPECT().GetWorkspaceReleaseChannelByWorkspaceID(gomock.Any(), workspaceID).
+					Times(1).
+					Return(database.WorkspaceReleaseChannel{WorkspaceID: workspaceID, Channel: database.ReleaseChannelBeta}, nil)
+				mTx.EXPECT().GetTemplateReleaseChannel(gomock.Any(), database.GetTemplateReleaseChannelParams{
+					TemplateID: templateID,
+					Channel:    database.ReleaseChannelBeta,
+				}).
+					Times(1).
+					Return(database.TemplateReleaseChannel{}, sql.ErrNoRows)
+			},
+			withRollout(nil),
+			withActiveVersion(nil),
+			withLastBuildNotFound,
+			withTemplateVersionVariables(activeVersionID, nil),
+			withParameterSchemas(activeJobID, nil),
+			withWorkspaceTags(activeVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+				asrt.Equal(activeFileID, job.FileID)
+			}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				asrt.Equal(activeVersionID, bld.TemplateVersionID)
+			}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+			}),
+			withBuild,
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
+}
+
+func TestBuilder_Warnings(t *testing.T) {
+	t.Parallel()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	t.Run("InactiveVersion", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
 
-	mDB := expectDB(t,
-		// Inputs
-		withTemplate,
-		withActiveVersion(nil),
-		withLastBuildNotFound,
-		withTemplateVersionVariables(activeVersionID, nil),
-		withParameterSchemas(activeJobID, nil),
-		withWorkspaceTags(activeVersionID, nil),
-		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
-		// previous rich parameters are not queried because there is no previous build.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
-		// Outputs
-		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
-			asrt.Equal(activeFileID, job.FileID)
-		}),
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withInactiveVersion(nil),
+			withLastBuildFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+			withNewerTemplateVersions([]database.TemplateVersion{
+				{ID: uuid.New(), TemplateID: uuid.NullUUID{UUID: templateID, Valid: true}, Name: "v2"},
+				{ID: activeVersionID, TemplateID: uuid.NullUUID{UUID: templateID, Valid: true}, Name: "v3"},
+				// Versions newer than the active version are not behind.
+				{ID: uuid.New(), TemplateID: uuid.NullUUID{UUID: templateID, Valid: true}, Name: "v4"},
+			}),
 
-		withInTx,
-		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
-			asrt.Equal(activeVersionID, bld.TemplateVersionID)
-			// no previous build...
-			asrt.Equal(int32(1), bld.BuildNumber)
-			asrt.Len(bld.ProvisionerState, 0)
-		}),
-		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
-		}),
-		withBuild,
-	)
-	fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+			// Outputs
+			expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				if asrt.Len(bld.Warnings, 1) {
+					asrt.Equal(string(codersdk.WorkspaceBuildWarningInactiveTemplateVersion), bld.Warnings[0].Code)
+					asrt.Equal([]string{"v2", "v3"}, bld.Warnings[0].VersionsBehind)
+				}
+			}),
+			withBuild,
+			expectBuildParameters(func(_ database.InsertWorkspaceBuildParametersParams) {}),
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
 
-	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
-	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
-	// nolint: dogsled
-	_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
-	req.NoError(err)
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart)
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
+
+	t.Run("DeprecatedTemplate", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			func(mTx *dbmock.MockStore) {
+				mTx.EXPECT().GetTemplateByID(gomock.Any(), templateID).
+					Times(1).
+					Return(database.Template{
+						ID:                      templateID,
+						OrganizationID:          orgID,
+						Provisioner:             database.ProvisionerTypeTerraform,
+						ActiveVersionID:         activeVersionID,
+						UseClassicParameterFlow: true,
+						Deprecated:              "Use the new template instead.",
+					}, nil)
+			},
+			withReleaseChannel(nil),
+			withRollout(nil),
+			withActiveVersion(nil),
+			withLastBuildFound,
+			withTemplateVersionVariables(activeVersionID, nil),
+			withRichParameters(nil),
+			withParameterSchemas(activeJobID, nil),
+			withWorkspaceTags(activeVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				asrt.Equal(activeVersionID, bld.TemplateVersionID)
+				if asrt.Len(bld.Warnings, 1) {
+					asrt.Equal(string(codersdk.WorkspaceBuildWarningDeprecatedTemplate), bld.Warnings[0].Code)
+					asrt.Contains(bld.Warnings[0].Message, "Use the new template instead.")
+				}
+			}),
+			withBuild,
+			expectBuildParameters(func(_ database.InsertWorkspaceBuildParametersParams) {}),
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
 }
 
 func TestWorkspaceBuildWithTags(t *testing.T) {
@@ -354,6 +1069,7 @@ func TestWorkspaceBuildWithTags(t *testing.T) {
 		withParameterSchemas(inactiveJobID, nil),
 		withWorkspaceTags(inactiveVersionID, workspaceTags),
 		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+		withNewerTemplateVersions(nil),
 
 		// Outputs
 		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
@@ -450,6 +1166,7 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 			withParameterSchemas(inactiveJobID, nil),
 			withWorkspaceTags(inactiveVersionID, nil),
 			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+			withNewerTemplateVersions(nil),
 
 			// Outputs
 			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
@@ -498,6 +1215,7 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 			withParameterSchemas(inactiveJobID, nil),
 			withWorkspaceTags(inactiveVersionID, nil),
 			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+			withNewerTemplateVersions(nil),
 
 			// Outputs
 			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
@@ -521,6 +1239,71 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
 		req.NoError(err)
 	})
+	t.Run("UseParameterDefaults", func(t *testing.T) {
+		t.Parallel()
+
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		const (
+			firstParameterDefault = "10"
+			updatedParameterValue = "20"
+		)
+		richParameters := []database.TemplateVersionParameter{
+			{Name: firstParameterName, Description: firstParameterDescription, Mutable: true, DefaultValue: firstParameterDefault, Options: json.RawMessage("[]")},
+			{Name: secondParameterName, Description: secondParameterDescription, Mutable: true, Options: json.RawMessage("[]")},
+			{Name: immutableParameterName, Description: immutableParameterDescription, Mutable: false, DefaultValue: "30", Options: json.RawMessage("[]")},
+		}
+		nextBuildParameters := []codersdk.WorkspaceBuildParameter{
+			{Name: secondParameterName, Value: updatedParameterValue},
+		}
+		// Mutable parameters take their defaults unless set explicitly, while
+		// immutable parameters keep the values of the last build.
+		expectedParams := map[string]string{
+			firstParameterName:     firstParameterDefault,
+			secondParameterName:    updatedParameterValue,
+			immutableParameterName: immutableParameterValue,
+		}
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withInactiveVersion(richParameters),
+			withLastBuildFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(initialBuildParameters),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+			withNewerTemplateVersions(nil),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+				asrt.Len(params.Name, len(expectedParams))
+				for i := range params.Name {
+					value, ok := expectedParams[params.Name[i]]
+					asrt.True(ok, "unexpected name %s", params.Name[i])
+					asrt.Equal(value, params.Value[i])
+				}
+			}),
+			withBuild,
+		)
+		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).
+			RichParameterValues(nextBuildParameters).
+			UseParameterDefaults()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
 
 	t.Run("StartWorkspaceWithLegacyParameterValues", func(t *testing.T) {
 		t.Parallel()
@@ -547,9 +1330,7 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 			withTemplate,
 			withInactiveVersionNoParams(),
 			withLastBuildFound,
-			withTemplateVersionVariables(inactiveVersionID, nil),
 			withParameterSchemas(inactiveJobID, schemas),
-			withWorkspaceTags(inactiveVersionID, nil),
 		)
 		fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
 
@@ -579,10 +1360,8 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 			withTemplate,
 			withInactiveVersion(richParameters),
 			withLastBuildFound,
-			withTemplateVersionVariables(inactiveVersionID, nil),
 			withRichParameters(initialBuildParameters),
 			withParameterSchemas(inactiveJobID, nil),
-			withWorkspaceTags(inactiveVersionID, nil),
 
 			// Outputs
 			// no transaction, since we failed fast while validation build parameters
@@ -804,6 +1583,8 @@ func TestWorkspaceBuildWithPreset(t *testing.T) {
 	mDB := expectDB(t,
 		// Inputs
 		withTemplate,
+		withReleaseChannel(nil),
+		withRollout(nil),
 		withActiveVersion(nil),
 		// building workspaces using presets with different combinations of parameters
 		// is tested at the API layer, in TestWorkspace. Here, it is sufficient to
@@ -1018,10 +1799,19 @@ func expectDB(t *testing.T, opts ...txExpect) *dbmock.MockStore {
 			return err
 		})
 
+	// Templates don't limit concurrent jobs unless the test says otherwise.
+	mDB.EXPECT().GetTemplateByID(gomock.Any(), templateID).
+		AnyTimes().
+		Return(database.Template{ID: templateID}, nil)
+
 	// txExpect args set up the expectations for what happens in the transaction.
 	for _, o := range opts {
 		o(mTx)
 	}
+	// Workspaces are not locked unless the test says otherwise.
+	mTx.EXPECT().GetActiveWorkspaceLockByWorkspaceID(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(database.WorkspaceLock{}, sql.ErrNoRows)
 	return mDB
 }
 
@@ -1139,41 +1929,118 @@ func withInactiveVersion(params []database.TemplateVersionParameter) func(mTx *d
 	}
 }
 
+// withReleaseChannel sets up the workspace to track the given release channel
+// of the template, or no channel if nil.
+func withReleaseChannel(channel *database.TemplateReleaseChannel) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		call := mTx.EXPECT().GetWorkspaceReleaseChannelByWorkspaceID(gomock.Any(), workspaceID).
+			Times(1)
+		if channel == nil {
+			call.Return(database.WorkspaceReleaseChannel{}, sql.ErrNoRows)
+			return
+		}
+		call.Return(database.WorkspaceReleaseChannel{WorkspaceID: workspaceID, Channel: channel.Channel}, nil)
+		mTx.EXPECT().GetTemplateReleaseChannel(gomock.Any(), database.GetTemplateReleaseChannelParams{
+			TemplateID: templateID,
+			Channel:    channel.Channel,
+		}).
+			Times(1).
+			Return(*channel, nil)
+	}
+}
+
+// withRollout sets up the rollout of the template, or no rollout if nil.
+func withRollout(rollout *database.TemplateVersionRollout) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		call := mTx.EXPECT().GetTemplateVersionRolloutByTemplateID(gomock.Any(), templateID).
+			Times(1)
+		if rollout != nil {
+			call.Return(*rollout, nil)
+		} else {
+			call.Return(database.TemplateVersionRollout{}, sql.ErrNoRows)
+		}
+	}
+}
+
+func withNewerTemplateVersions(versions []database.TemplateVersion) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetTemplateVersionsByTemplateID(gomock.Any(), database.GetTemplateVersionsByTemplateIDParams{
+			TemplateID: templateID,
+			Archived:   sql.NullBool{Bool: false, Valid: true},
+			AfterID:    inactiveVersionID,
+		}).
+			Times(1).
+			Return(versions, nil)
+	}
+}
+
 func withTemplateVersionPresetParameters(presetID uuid.UUID, params []database.TemplateVersionPresetParameter) func(mTx *dbmock.MockStore) {
 	return func(mTx *dbmock.MockStore) {
 		mTx.EXPECT().GetPresetParametersByPresetID(gomock.Any(), presetID).Return(params, nil)
 	}
 }
 
+func withWorkspaceLock(reason string) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetActiveWorkspaceLockByWorkspaceID(gomock.Any(), workspaceID).
+			Times(1).
+			Return(database.WorkspaceLock{
+				ID:          uuid.New(),
+				WorkspaceID: workspaceID,
+				Reason:      reason,
+				LockedBy:    userID,
+				LockedAt:    dbtime.Now(),
+			}, nil)
+	}
+}
+
 func withLastBuildFound(mTx *dbmock.MockStore) {
-	mTx.EXPECT().GetLatestWorkspaceBuildByWorkspaceID(gomock.Any(), workspaceID).
-		Times(1).
-		Return(database.WorkspaceBuild{
-			ID:                lastBuildID,
-			WorkspaceID:       workspaceID,
-			TemplateVersionID: inactiveVersionID,
-			BuildNumber:       1,
-			Transition:        database.WorkspaceTransitionStart,
-			InitiatorID:       userID,
-			JobID:             lastBuildJobID,
-			ProvisionerState:  []byte("last build state"),
-			Reason:            database.BuildReasonInitiator,
-		}, nil)
+	withLastBuildJobStatus(database.ProvisionerJobStatusSucceeded)(mTx)
+}
 
-	mTx.EXPECT().GetProvisionerJobByID(gomock.Any(), lastBuildJobID).
-		Times(1).
-		Return(database.ProvisionerJob{
-			ID:             lastBuildJobID,
-			OrganizationID: orgID,
-			InitiatorID:    userID,
-			Provisioner:    database.ProvisionerTypeTerraform,
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         inactiveFileID,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
-			UpdatedAt:      time.Now(),
-			CompletedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
-		}, nil)
+func withLastBuildJobStatus(status database.ProvisionerJobStatus) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetLatestWorkspaceBuildByWorkspaceID(gomock.Any(), workspaceID).
+			Times(1).
+			Return(database.WorkspaceBuild{
+				ID:                lastBuildID,
+				WorkspaceID:       workspaceID,
+				TemplateVersionID: inactiveVersionID,
+				BuildNumber:       1,
+				Transition:        database.WorkspaceTransitionStart,
+				InitiatorID:       userID,
+				JobID:             lastBuildJobID,
+				ProvisionerState:  []byte("last build state"),
+				Reason:            database.BuildReasonInitiator,
+			}, nil)
+
+		mTx.EXPECT().GetProvisionerJobByID(gomock.Any(), lastBuildJobID).
+			Times(1).
+			Return(database.ProvisionerJob{
+				ID:             lastBuildJobID,
+				OrganizationID: orgID,
+				InitiatorID:    userID,
+				Provisioner:    database.ProvisionerTypeTerraform,
+				StorageMethod:  database.ProvisionerStorageMethodFile,
+				FileID:         inactiveFileID,
+				Type:           database.ProvisionerJobTypeWorkspaceBuild,
+				StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+				UpdatedAt:      time.Now(),
+				CompletedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
+				JobStatus:      status,
+			}, nil)
+	}
+}
+
+func withInterimState(state []byte, err error) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetWorkspaceBuildInterimStateByBuildID(gomock.Any(), lastBuildID).
+			Times(1).
+			Return(database.WorkspaceBuildInterimState{
+				WorkspaceBuildID: lastBuildID,
+				State:            state,
+			}, err)
+	}
 }
 
 func withLastBuildNotFound(mTx *dbmock.MockStore) {
@@ -1348,3 +2215,18 @@ func withProvisionerDaemons(provisionerDaemons []database.GetEligibleProvisioner
 		mTx.EXPECT().GetEligibleProvisionerDaemonsByProvisionerJobIDs(gomock.Any(), gomock.Any()).Return(provisionerDaemons, nil)
 	}
 }
+
+type fakePreflightCheck struct {
+	name     string
+	failures []wsbuilder.PreflightFailure
+	req      *wsbuilder.PreflightRequest
+}
+
+func (c *fakePreflightCheck) Name() string {
+	return c.name
+}
+
+func (c *fakePreflightCheck) Check(_ context.Context, req wsbuilder.PreflightRequest) ([]wsbuilder.PreflightFailure, error) {
+	c.req = &req
+	return c.failures, nil
+}
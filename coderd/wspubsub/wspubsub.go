@@ -56,6 +56,7 @@ const (
 	WorkspaceEventKindAgentLogsOverflow     WorkspaceEventKind = "agt_logs_overflow"
 	WorkspaceEventKindAgentTimeout          WorkspaceEventKind = "agt_timeout"
 	WorkspaceEventKindAgentAppStatusUpdate  WorkspaceEventKind = "agt_app_status_update"
+	WorkspaceEventKindAgentHealthUpdate     WorkspaceEventKind = "agt_health_update"
 )
 
 func (w *WorkspaceEvent) Validate() error {
@@ -0,0 +1,141 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessRequestStatus is the state of an access request.
+type AccessRequestStatus string
+
+const (
+	AccessRequestStatusPending  AccessRequestStatus = "pending"
+	AccessRequestStatusApproved AccessRequestStatus = "approved"
+	AccessRequestStatusDenied   AccessRequestStatus = "denied"
+	// AccessRequestStatusExpired is set once the access granted by an
+	// approved request has been revoked.
+	AccessRequestStatusExpired AccessRequestStatus = "expired"
+)
+
+// AccessRequestResourceType is the type of resource access is requested to.
+type AccessRequestResourceType string
+
+const (
+	AccessRequestResourceTypeWorkspace AccessRequestResourceType = "workspace"
+	AccessRequestResourceTypeTemplate  AccessRequestResourceType = "template"
+)
+
+// AccessRequest is a request for a role on a workspace or template for a
+// limited time. Once approved, the role is granted through the ACL of the
+// resource and revoked when the request expires.
+type AccessRequest struct {
+	ID             uuid.UUID                 `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID                 `json:"organization_id" format:"uuid"`
+	RequesterID    uuid.UUID                 `json:"requester_id" format:"uuid"`
+	ResourceType   AccessRequestResourceType `json:"resource_type" enums:"workspace,template"`
+	ResourceID     uuid.UUID                 `json:"resource_id" format:"uuid"`
+	// Role is a workspace role for workspaces and a template role for
+	// templates.
+	Role           string              `json:"role"`
+	Reason         string              `json:"reason"`
+	DurationMillis int64               `json:"duration_ms"`
+	Status         AccessRequestStatus `json:"status" enums:"pending,approved,denied,expired"`
+	ReviewerID     *uuid.UUID          `json:"reviewer_id,omitempty" format:"uuid"`
+	ReviewedAt     *time.Time          `json:"reviewed_at,omitempty" format:"date-time"`
+	// ExpiresAt is set when the request is approved. The role is revoked
+	// after this time.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
+	CreatedAt time.Time  `json:"created_at" format:"date-time"`
+}
+
+type CreateAccessRequest struct {
+	ResourceType AccessRequestResourceType `json:"resource_type" validate:"required" enums:"workspace,template"`
+	ResourceID   uuid.UUID                 `json:"resource_id" validate:"required" format:"uuid"`
+	// Role is "view" or "connect" for workspaces, and "use" or "admin" for
+	// templates.
+	Role   string `json:"role" validate:"required"`
+	Reason string `json:"reason" validate:"required"`
+	// DurationMillis is how long the role is granted for once the request is
+	// approved.
+	DurationMillis int64 `json:"duration_ms" validate:"required,gt=0"`
+}
+
+type ReviewAccessRequest struct {
+	Status AccessRequestStatus `json:"status" validate:"required" enums:"approved,denied"`
+}
+
+// AccessRequestFilter filters the access requests returned by
+// AccessRequests.
+// @typescript-ignore AccessRequestFilter
+type AccessRequestFilter struct {
+	Status AccessRequestStatus `json:"status,omitempty"`
+}
+
+// CreateAccessRequest requests a role on a workspace or template. Those who
+// can update the resource are notified and can review the request.
+func (c *Client) CreateAccessRequest(ctx context.Context, req CreateAccessRequest) (AccessRequest, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/accessrequests", req)
+	if err != nil {
+		return AccessRequest{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return AccessRequest{}, ReadBodyAsError(res)
+	}
+	var resp AccessRequest
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// AccessRequests returns the access requests made by the user, and those the
+// user can review.
+func (c *Client) AccessRequests(ctx context.Context, filter AccessRequestFilter) ([]AccessRequest, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/accessrequests", nil, func(r *http.Request) {
+		q := r.URL.Query()
+		if filter.Status != "" {
+			q.Set("status", string(filter.Status))
+		}
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []AccessRequest
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// AccessRequest returns an access request by ID.
+func (c *Client) AccessRequest(ctx context.Context, id uuid.UUID) (AccessRequest, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/accessrequests/%s", id), nil)
+	if err != nil {
+		return AccessRequest{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return AccessRequest{}, ReadBodyAsError(res)
+	}
+	var resp AccessRequest
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ReviewAccessRequest approves or denies a pending access request.
+func (c *Client) ReviewAccessRequest(ctx context.Context, id uuid.UUID, req ReviewAccessRequest) (AccessRequest, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/accessrequests/%s/review", id), req)
+	if err != nil {
+		return AccessRequest{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return AccessRequest{}, ReadBodyAsError(res)
+	}
+	var resp AccessRequest
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -123,6 +123,7 @@ type Manifest struct {
 	Directory                string                                       `json:"directory"`
 	MOTDFile                 string                                       `json:"motd_file"`
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
+	SessionRecordingEnabled  bool                                         `json:"session_recording_enabled"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
 	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
 	Devcontainers            []codersdk.WorkspaceAgentDevcontainer        `json:"devcontainers"`
@@ -282,6 +283,18 @@ func (c *Client) ConnectRPC26(ctx context.Context) (
 	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
 }
 
+// ConnectRPC27 returns a dRPC client to the Agent API v2.7.  It is useful when you want to be
+// maximally compatible with Coderd Release Versions from 2.25+
+func (c *Client) ConnectRPC27(ctx context.Context) (
+	proto.DRPCAgentClient27, tailnetproto.DRPCTailnetClient27, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 7))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
 // ConnectRPC connects to the workspace agent API and tailnet API
 func (c *Client) ConnectRPC(ctx context.Context) (drpc.Conn, error) {
 	return c.connectRPCVersion(ctx, proto.CurrentVersion)
@@ -60,6 +60,7 @@ func ManifestFromProto(manifest *proto.Manifest) (Manifest, error) {
 		VSCodePortProxyURI:       manifest.VsCodePortProxyUri,
 		MOTDFile:                 manifest.MotdPath,
 		DisableDirectConnections: manifest.DisableDirectConnections,
+		SessionRecordingEnabled:  manifest.SessionRecordingEnabled,
 		Metadata:                 MetadataDescriptionsFromProto(manifest.Metadata),
 		Devcontainers:            devcontainers,
 	}, nil
@@ -85,6 +86,7 @@ func ProtoFromManifest(manifest Manifest) (*proto.Manifest, error) {
 		MotdPath:                 manifest.MOTDFile,
 		DisableDirectConnections: manifest.DisableDirectConnections,
 		DerpForceWebsockets:      manifest.DERPForceWebSockets,
+		SessionRecordingEnabled:  manifest.SessionRecordingEnabled,
 		DerpMap:                  tailnet.DERPMapToProto(manifest.DERPMap),
 		Scripts:                  ProtoFromScripts(manifest.Scripts),
 		Apps:                     apps,
@@ -193,6 +195,7 @@ func AgentScriptFromProto(protoScript *proto.WorkspaceAgentScript) (codersdk.Wor
 		Cron:             protoScript.Cron,
 		RunOnStart:       protoScript.RunOnStart,
 		RunOnStop:        protoScript.RunOnStop,
+		RunOnClaim:       protoScript.RunOnClaim,
 		StartBlocksLogin: protoScript.StartBlocksLogin,
 		Timeout:          protoScript.Timeout.AsDuration(),
 		DisplayName:      protoScript.DisplayName,
@@ -208,6 +211,7 @@ func ProtoFromScript(s codersdk.WorkspaceAgentScript) *proto.WorkspaceAgentScrip
 		Cron:             s.Cron,
 		RunOnStart:       s.RunOnStart,
 		RunOnStop:        s.RunOnStop,
+		RunOnClaim:       s.RunOnClaim,
 		StartBlocksLogin: s.StartBlocksLogin,
 		Timeout:          durationpb.New(s.Timeout),
 		DisplayName:      s.DisplayName,
@@ -89,6 +89,7 @@ func TestManifest(t *testing.T) {
 		Directory:                "/home/coder",
 		MOTDFile:                 "/etc/motd",
 		DisableDirectConnections: true,
+		SessionRecordingEnabled:  true,
 		Metadata: []codersdk.WorkspaceAgentMetadataDescription{
 			{
 				DisplayName: "CPU",
@@ -126,6 +127,7 @@ func TestManifest(t *testing.T) {
 				Cron:             "somecron2",
 				RunOnStart:       false,
 				RunOnStop:        true,
+				RunOnClaim:       true,
 				StartBlocksLogin: true,
 				Timeout:          time.Second * 4,
 				DisplayName:      "bar",
@@ -159,6 +161,7 @@ func TestManifest(t *testing.T) {
 	require.Equal(t, manifest.Directory, back.Directory)
 	require.Equal(t, manifest.MOTDFile, back.MOTDFile)
 	require.Equal(t, manifest.DisableDirectConnections, back.DisableDirectConnections)
+	require.Equal(t, manifest.SessionRecordingEnabled, back.SessionRecordingEnabled)
 	require.Equal(t, manifest.Metadata, back.Metadata)
 	require.Equal(t, manifest.Scripts, back.Scripts)
 	require.Equal(t, manifest.Devcontainers, back.Devcontainers)
@@ -19,7 +19,7 @@ type APIKey struct {
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
 	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,workspace:read,build:create,template:admin"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
 }
@@ -48,11 +48,20 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeWorkspaceRead is a scope that allows the user to read
+	// workspaces and their builds.
+	APIKeyScopeWorkspaceRead APIKeyScope = "workspace:read"
+	// APIKeyScopeBuildCreate is a scope that allows the user to start,
+	// stop and update existing workspaces.
+	APIKeyScopeBuildCreate APIKeyScope = "build:create"
+	// APIKeyScopeTemplateAdmin is a scope that allows the user to manage
+	// templates and their versions.
+	APIKeyScopeTemplateAdmin APIKeyScope = "template:admin"
 )
 
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
-	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
+	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect,workspace:read,build:create,template:admin"`
 	TokenName string        `json:"token_name"`
 }
 
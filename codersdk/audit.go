@@ -39,6 +39,10 @@ const (
 	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
 	ResourceTypeWorkspaceAgent              ResourceType = "workspace_agent"
 	ResourceTypeWorkspaceApp                ResourceType = "workspace_app"
+	ResourceTypeReadOnlySettings            ResourceType = "read_only_settings"
+	ResourceTypeProvisionerBuildPause       ResourceType = "provisioner_build_pause"
+	ResourceTypeAccessRequest               ResourceType = "access_request"
+	ResourceTypeLoginDeviceCode             ResourceType = "login_device_code"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -93,6 +97,14 @@ func (r ResourceType) FriendlyString() string {
 		return "workspace agent"
 	case ResourceTypeWorkspaceApp:
 		return "workspace app"
+	case ResourceTypeReadOnlySettings:
+		return "read_only_settings"
+	case ResourceTypeProvisionerBuildPause:
+		return "build pause"
+	case ResourceTypeAccessRequest:
+		return "access request"
+	case ResourceTypeLoginDeviceCode:
+		return "device login"
 	default:
 		return "unknown"
 	}
@@ -149,6 +161,33 @@ func (a AuditAction) Friendly() string {
 	}
 }
 
+// AuditClientType is the kind of client that connected to a workspace. It is
+// recorded as the "client_type" additional field of connect, disconnect and
+// open audit logs.
+type AuditClientType string
+
+const (
+	// AuditClientTypeCLI is the Coder CLI, e.g. "coder ssh".
+	AuditClientTypeCLI AuditClientType = "cli"
+	// AuditClientTypeSSH is any other SSH client, e.g. OpenSSH connecting
+	// through "coder ssh --stdio".
+	AuditClientTypeSSH       AuditClientType = "ssh"
+	AuditClientTypeVSCode    AuditClientType = "vscode"
+	AuditClientTypeJetBrains AuditClientType = "jetbrains"
+	AuditClientTypeBrowser   AuditClientType = "browser"
+	AuditClientTypeUnknown   AuditClientType = "unknown"
+)
+
+func (c AuditClientType) Valid() bool {
+	switch c {
+	case AuditClientTypeCLI, AuditClientTypeSSH, AuditClientTypeVSCode,
+		AuditClientTypeJetBrains, AuditClientTypeBrowser, AuditClientTypeUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
 type AuditDiff map[string]AuditDiffField
 
 type AuditDiffField struct {
@@ -0,0 +1,120 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BuildAlertRuleMetric is the workspace build metric a build alert rule
+// watches.
+type BuildAlertRuleMetric string
+
+const (
+	// BuildAlertRuleMetricFailureRate is the percentage of completed builds
+	// that failed.
+	BuildAlertRuleMetricFailureRate BuildAlertRuleMetric = "failure_rate"
+	// BuildAlertRuleMetricQueueWaitP95 is the 95th percentile of the time, in
+	// milliseconds, builds waited for a provisioner daemon.
+	BuildAlertRuleMetricQueueWaitP95 BuildAlertRuleMetric = "queue_wait_p95"
+)
+
+// BuildAlertRule notifies template administrators when a workspace build
+// metric stays above a threshold over a sliding window.
+type BuildAlertRule struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	// TemplateID scopes the rule to the builds of a single template. The rule
+	// covers all templates of the organization when it is not set.
+	TemplateID   *uuid.UUID           `json:"template_id,omitempty" format:"uuid"`
+	Name         string               `json:"name"`
+	Metric       BuildAlertRuleMetric `json:"metric" enums:"failure_rate,queue_wait_p95"`
+	Threshold    float64              `json:"threshold"`
+	WindowMillis int64                `json:"window_ms"`
+	// LastValue is the value of the metric at the last evaluation. It is not
+	// set if there were no builds to evaluate the metric over.
+	LastValue       *float64   `json:"last_value,omitempty"`
+	LastEvaluatedAt *time.Time `json:"last_evaluated_at,omitempty" format:"date-time"`
+	// BreachedAt is set while the metric is above the threshold.
+	BreachedAt *time.Time `json:"breached_at,omitempty" format:"date-time"`
+	CreatedAt  time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt  time.Time  `json:"updated_at" format:"date-time"`
+}
+
+type CreateBuildAlertRuleRequest struct {
+	TemplateID *uuid.UUID           `json:"template_id,omitempty" format:"uuid"`
+	Name       string               `json:"name" validate:"required"`
+	Metric     BuildAlertRuleMetric `json:"metric" validate:"required" enums:"failure_rate,queue_wait_p95"`
+	// Threshold is a percentage for failure_rate and a duration in
+	// milliseconds for queue_wait_p95.
+	Threshold    float64 `json:"threshold"`
+	WindowMillis int64   `json:"window_ms" validate:"required,gt=0"`
+}
+
+type UpdateBuildAlertRuleRequest struct {
+	TemplateID   *uuid.UUID           `json:"template_id,omitempty" format:"uuid"`
+	Name         string               `json:"name" validate:"required"`
+	Metric       BuildAlertRuleMetric `json:"metric" validate:"required" enums:"failure_rate,queue_wait_p95"`
+	Threshold    float64              `json:"threshold"`
+	WindowMillis int64                `json:"window_ms" validate:"required,gt=0"`
+}
+
+// BuildAlertRules returns the build alert rules of an organization.
+func (c *Client) BuildAlertRules(ctx context.Context, organizationID uuid.UUID) ([]BuildAlertRule, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/buildalertrules", organizationID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []BuildAlertRule
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// CreateBuildAlertRule creates a build alert rule in an organization.
+func (c *Client) CreateBuildAlertRule(ctx context.Context, organizationID uuid.UUID, req CreateBuildAlertRuleRequest) (BuildAlertRule, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/buildalertrules", organizationID), req)
+	if err != nil {
+		return BuildAlertRule{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return BuildAlertRule{}, ReadBodyAsError(res)
+	}
+	var resp BuildAlertRule
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateBuildAlertRule replaces the definition of a build alert rule and
+// resets its evaluation state.
+func (c *Client) UpdateBuildAlertRule(ctx context.Context, organizationID, ruleID uuid.UUID, req UpdateBuildAlertRuleRequest) (BuildAlertRule, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/buildalertrules/%s", organizationID, ruleID), req)
+	if err != nil {
+		return BuildAlertRule{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return BuildAlertRule{}, ReadBodyAsError(res)
+	}
+	var resp BuildAlertRule
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteBuildAlertRule deletes a build alert rule.
+func (c *Client) DeleteBuildAlertRule(ctx context.Context, organizationID, ruleID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/buildalertrules/%s", organizationID, ruleID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
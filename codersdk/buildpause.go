@@ -0,0 +1,84 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// BuildPause reports whether builds are paused, either across the deployment
+// or in an organization. While builds are paused, new provisioner jobs are
+// accepted but held pending: provisioners do not pick them up and they are not
+// timed out. It is meant for planned infrastructure maintenance.
+type BuildPause struct {
+	Paused bool   `json:"paused"`
+	Reason string `json:"reason,omitempty"`
+	// PausedAt and PausedBy are only set while builds are paused.
+	PausedAt *time.Time `json:"paused_at,omitempty" format:"date-time"`
+	PausedBy *uuid.UUID `json:"paused_by,omitempty" format:"uuid"`
+}
+
+type UpdateBuildPauseRequest struct {
+	Paused bool `json:"paused"`
+	// Reason is shown to users whose builds are held. It is ignored when
+	// resuming builds.
+	Reason string `json:"reason,omitempty" validate:"max=256"`
+}
+
+// DeploymentBuildPause returns whether builds are paused across the
+// deployment.
+func (c *Client) DeploymentBuildPause(ctx context.Context) (BuildPause, error) {
+	return c.buildPause(ctx, "/api/v2/deployment/build-pause")
+}
+
+// PutDeploymentBuildPause pauses or resumes builds across the deployment.
+func (c *Client) PutDeploymentBuildPause(ctx context.Context, req UpdateBuildPauseRequest) error {
+	return c.putBuildPause(ctx, "/api/v2/deployment/build-pause", req)
+}
+
+// OrganizationBuildPause returns whether builds are paused in an
+// organization. Builds paused across the deployment are not reflected.
+func (c *Client) OrganizationBuildPause(ctx context.Context, organizationID uuid.UUID) (BuildPause, error) {
+	return c.buildPause(ctx, fmt.Sprintf("/api/v2/organizations/%s/build-pause", organizationID))
+}
+
+// PutOrganizationBuildPause pauses or resumes builds in an organization.
+func (c *Client) PutOrganizationBuildPause(ctx context.Context, organizationID uuid.UUID, req UpdateBuildPauseRequest) error {
+	return c.putBuildPause(ctx, fmt.Sprintf("/api/v2/organizations/%s/build-pause", organizationID), req)
+}
+
+func (c *Client) buildPause(ctx context.Context, path string) (BuildPause, error) {
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return BuildPause{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return BuildPause{}, ReadBodyAsError(res)
+	}
+
+	var pause BuildPause
+	return pause, json.NewDecoder(res.Body).Decode(&pause)
+}
+
+func (c *Client) putBuildPause(ctx context.Context, path string, req UpdateBuildPauseRequest) error {
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
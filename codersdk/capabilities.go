@@ -0,0 +1,68 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// Capabilities describes what the deployment supports in a machine-readable
+// form, so clients can detect features instead of comparing version strings.
+type Capabilities struct {
+	// APIVersions are the versions of the APIs served by the deployment.
+	APIVersions APIVersions `json:"api_versions"`
+	// Experiments contains every experiment known to the deployment and
+	// whether it is enabled. Experiments missing from the map are not
+	// supported by the deployment at all.
+	Experiments map[Experiment]bool `json:"experiments"`
+	// Features contains every feature known to the deployment and whether it
+	// is enabled. Features missing from the map are not supported by the
+	// deployment at all.
+	Features map[FeatureName]bool `json:"features"`
+}
+
+// APIVersions are the versions of the APIs served by a deployment. Versions of
+// the dRPC APIs are in the form "major.minor". Older minor versions of the
+// same major version remain supported.
+type APIVersions struct {
+	// REST lists the supported versions of the HTTP API, e.g. "v2".
+	REST []string `json:"rest"`
+	// Agent is the current version of the workspace agent API.
+	Agent string `json:"agent"`
+	// Provisioner is the current version of the provisioner daemon API.
+	Provisioner string `json:"provisioner"`
+	// Tailnet is the current version of the tailnet coordination API used by
+	// clients connecting to workspaces.
+	Tailnet string `json:"tailnet"`
+}
+
+// ExperimentEnabled returns whether the experiment is known to the deployment
+// and enabled.
+func (c Capabilities) ExperimentEnabled(ex Experiment) bool {
+	return c.Experiments[ex]
+}
+
+// FeatureEnabled returns whether the feature is known to the deployment and
+// enabled.
+func (c Capabilities) FeatureEnabled(name FeatureName) bool {
+	return c.Features[name]
+}
+
+// Capabilities returns the APIs, experiments and features supported by the
+// deployment.
+func (c *Client) Capabilities(ctx context.Context) (Capabilities, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/capabilities", nil)
+	if err != nil {
+		return Capabilities{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Capabilities{}, ReadBodyAsError(res)
+	}
+
+	var capabilities Capabilities
+	return capabilities, json.NewDecoder(res.Body).Decode(&capabilities)
+}
@@ -81,6 +81,14 @@ const (
 	// fields, including device ID, OS, and Desktop version.
 	CoderDesktopTelemetryHeader = "Coder-Desktop-Telemetry"
 
+	// DeviceIDHeader contains a stable identifier of the machine the CLI runs
+	// on. Session tokens can be bound to it to prevent them from being
+	// replayed from other machines.
+	DeviceIDHeader = "Coder-Device-ID"
+	// DeviceIDCookie stores the device ID of browsers, which don't send the
+	// DeviceIDHeader.
+	DeviceIDCookie = "coder_device_id"
+
 	// ProvisionerDaemonPSK contains the authentication pre-shared key for an external provisioner daemon
 	ProvisionerDaemonPSK = "Coder-Provisioner-Daemon-PSK"
 
@@ -92,6 +100,12 @@ const (
 
 	// EntitlementsWarnings contains active warnings for the user's entitlements.
 	EntitlementsWarningHeader = "X-Coder-Entitlements-Warning"
+
+	// IdempotencyKeyHeader identifies a request that creates a workspace or a
+	// workspace build. Retrying the request with the same key returns what
+	// the first request created instead of creating it again. Keys are kept
+	// for a day.
+	IdempotencyKeyHeader = "Idempotency-Key"
 )
 
 // loggableMimeTypes is a list of MIME types that are safe to log
@@ -546,8 +560,55 @@ type Response struct {
 	// shown on a form field in the UI. These can also be used to add additional
 	// context if there is a set of errors in the primary 'Message'.
 	Validations []ValidationError `json:"validations,omitempty"`
+	// Code identifies the reason a request failed, so clients can handle
+	// specific errors without matching on the message. It's empty for
+	// errors that don't have a code.
+	Code ResponseCode `json:"code,omitempty" enums:"not_found,forbidden,quota_exceeded,template_deprecated,build_in_progress,workspace_locked,job_limit_reached,job_completed,job_reaped,job_canceled,preflight_failed,idempotency_key_reused"`
 }
 
+// ResponseCode is a stable identifier for an error returned by the API.
+type ResponseCode string
+
+const (
+	// ResponseCodeNotFound is returned when a resource doesn't exist, or the
+	// user isn't allowed to read it.
+	ResponseCodeNotFound ResponseCode = "not_found"
+	// ResponseCodeForbidden is returned when the user can read a resource but
+	// isn't allowed to perform the action.
+	ResponseCodeForbidden ResponseCode = "forbidden"
+	// ResponseCodeQuotaExceeded is returned when starting a workspace would
+	// exceed the quota or budget of its owner.
+	ResponseCodeQuotaExceeded ResponseCode = "quota_exceeded"
+	// ResponseCodeTemplateDeprecated is returned when creating a workspace
+	// from a deprecated template.
+	ResponseCodeTemplateDeprecated ResponseCode = "template_deprecated"
+	// ResponseCodeBuildInProgress is returned when building a workspace whose
+	// latest build is still pending or running.
+	ResponseCodeBuildInProgress ResponseCode = "build_in_progress"
+	// ResponseCodeWorkspaceLocked is returned when building a locked
+	// workspace.
+	ResponseCodeWorkspaceLocked ResponseCode = "workspace_locked"
+	// ResponseCodeJobLimitReached is returned when the user already has the
+	// maximum number of pending and running provisioner jobs.
+	ResponseCodeJobLimitReached ResponseCode = "job_limit_reached"
+	// ResponseCodeJobCompleted is returned when canceling or reaping a
+	// provisioner job that has already completed.
+	ResponseCodeJobCompleted ResponseCode = "job_completed"
+	// ResponseCodeJobReaped is returned instead of ResponseCodeJobCompleted
+	// when the job was terminated by the job reaper.
+	ResponseCodeJobReaped ResponseCode = "job_reaped"
+	// ResponseCodeJobCanceled is returned when canceling a provisioner job
+	// that has already been canceled.
+	ResponseCodeJobCanceled ResponseCode = "job_canceled"
+	// ResponseCodePreflightFailed is returned when a workspace build is
+	// rejected by a pre-flight check that has no more specific code.
+	ResponseCodePreflightFailed ResponseCode = "preflight_failed"
+	// ResponseCodeIdempotencyKeyReused is returned when an idempotency key
+	// is reused for a request that differs from the one it was first used
+	// for.
+	ResponseCodeIdempotencyKeyReused ResponseCode = "idempotency_key_reused"
+)
+
 // ValidationError represents a scoped error to a user input.
 type ValidationError struct {
 	Field  string `json:"field" validate:"required"`
@@ -600,6 +661,12 @@ func AsError(err error) (*Error, bool) {
 	return e, xerrors.As(err, &e)
 }
 
+// IsErrorCode returns whether err is an API error with the given code.
+func IsErrorCode(err error, code ResponseCode) bool {
+	apiErr, ok := AsError(err)
+	return ok && apiErr.Code == code
+}
+
 // RequestOption is a function that can be used to modify an http.Request.
 type RequestOption func(*http.Request)
 
@@ -615,6 +682,14 @@ func WithQueryParam(key, value string) RequestOption {
 	}
 }
 
+// WithIdempotencyKey sets the idempotency key of a request that creates a
+// workspace or a workspace build. See IdempotencyKeyHeader.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(IdempotencyKeyHeader, key)
+	}
+}
+
 // HeaderTransport is a http.RoundTripper that adds some headers to all requests.
 // @typescript-ignore HeaderTransport
 type HeaderTransport struct {
@@ -63,6 +63,12 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"Postgres Connection URL": {
 			yaml: true,
 		},
+		"Pubsub Redis URL": {
+			yaml: true,
+		},
+		"Audit Log Export Webhook URL": {
+			yaml: true,
+		},
 		"SCIM API Key": {
 			yaml: true,
 		},
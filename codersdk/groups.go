@@ -24,6 +24,10 @@ type CreateGroupRequest struct {
 	DisplayName    string `json:"display_name" validate:"omitempty,group_display_name"`
 	AvatarURL      string `json:"avatar_url"`
 	QuotaAllowance int    `json:"quota_allowance"`
+	// MonthlyBudget is the number of credits the members of the group may
+	// spend on workspaces each month, in addition to the monthly budgets
+	// of their other groups. 0 means the group adds no monthly budget.
+	MonthlyBudget int `json:"monthly_budget"`
 }
 
 type Group struct {
@@ -38,6 +42,7 @@ type Group struct {
 	TotalMemberCount        int         `json:"total_member_count"`
 	AvatarURL               string      `json:"avatar_url"`
 	QuotaAllowance          int         `json:"quota_allowance"`
+	MonthlyBudget           int         `json:"monthly_budget"`
 	Source                  GroupSource `json:"source"`
 	OrganizationName        string      `json:"organization_name"`
 	OrganizationDisplayName string      `json:"organization_display_name"`
@@ -154,6 +159,7 @@ type PatchGroupRequest struct {
 	DisplayName    *string  `json:"display_name" validate:"omitempty,group_display_name"`
 	AvatarURL      *string  `json:"avatar_url"`
 	QuotaAllowance *int     `json:"quota_allowance"`
+	MonthlyBudget  *int     `json:"monthly_budget"`
 }
 
 func (c *Client) PatchGroup(ctx context.Context, group uuid.UUID, req PatchGroupRequest) (Group, error) {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
 	"tailscale.com/net/netcheck"
@@ -35,6 +36,7 @@ const (
 	HealthSectionDatabase           HealthSection = "Database"
 	HealthSectionWorkspaceProxy     HealthSection = "WorkspaceProxy"
 	HealthSectionProvisionerDaemons HealthSection = "ProvisionerDaemons"
+	HealthSectionProvisionerJobs    HealthSection = "ProvisionerJobs"
 )
 
 var HealthSections = []HealthSection{
@@ -44,6 +46,7 @@ var HealthSections = []HealthSection{
 	HealthSectionDatabase,
 	HealthSectionWorkspaceProxy,
 	HealthSectionProvisionerDaemons,
+	HealthSectionProvisionerJobs,
 }
 
 type HealthSettings struct {
@@ -96,6 +99,21 @@ func (c *HealthClient) PutHealthSettings(ctx context.Context, settings HealthSet
 	return nil
 }
 
+// DebugJobReaper returns recent activity of the job reaper, which terminates
+// hung and pending provisioner jobs.
+func (c *HealthClient) DebugJobReaper(ctx context.Context) (JobReaperReport, error) {
+	res, err := c.client.Request(ctx, http.MethodGet, "/api/v2/debug/job-reaper", nil)
+	if err != nil {
+		return JobReaperReport{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return JobReaperReport{}, codersdk.ReadBodyAsError(res)
+	}
+	var rpt JobReaperReport
+	return rpt, json.NewDecoder(res.Body).Decode(&rpt)
+}
+
 // HealthcheckReport contains information about the health status of a Coder deployment.
 type HealthcheckReport struct {
 	// Time is the time the report was generated at.
@@ -112,6 +130,7 @@ type HealthcheckReport struct {
 	Database           DatabaseReport           `json:"database"`
 	WorkspaceProxy     WorkspaceProxyReport     `json:"workspace_proxy"`
 	ProvisionerDaemons ProvisionerDaemonsReport `json:"provisioner_daemons"`
+	ProvisionerJobs    ProvisionerJobsReport    `json:"provisioner_jobs"`
 
 	// The Coder version of the server that the report was generated on.
 	CoderVersion string `json:"coder_version"`
@@ -124,6 +143,7 @@ func (r *HealthcheckReport) Summarize(docsURL string) []string {
 	msgs = append(msgs, r.Database.Summarize("Database:", docsURL)...)
 	msgs = append(msgs, r.DERP.Summarize("DERP:", docsURL)...)
 	msgs = append(msgs, r.ProvisionerDaemons.Summarize("Provisioner Daemons:", docsURL)...)
+	msgs = append(msgs, r.ProvisionerJobs.Summarize("Provisioner Jobs:", docsURL)...)
 	msgs = append(msgs, r.Websocket.Summarize("Websocket:", docsURL)...)
 	msgs = append(msgs, r.WorkspaceProxy.Summarize("Workspace Proxies:", docsURL)...)
 	return msgs
@@ -251,6 +271,60 @@ type ProvisionerDaemonsReportItem struct {
 	Warnings                   []health.Message `json:"warnings"`
 }
 
+// ProvisionerJobsReport includes health details of the provisioner job queue
+// and the job reaper.
+type ProvisionerJobsReport struct {
+	BaseReport
+	// QueueDepths lists the pending jobs per organization and tag set, deepest
+	// queue first.
+	QueueDepths []ProvisionerJobsQueueDepth `json:"queue_depths"`
+	// OldestPendingJobAgeMS is the age of the oldest pending job, or 0 if no
+	// job is pending.
+	OldestPendingJobAgeMS int64 `json:"oldest_pending_job_age_ms"`
+	// JobReaperLastRunAt is the time of the last successful job reaper run, if
+	// the job reaper ever ran.
+	JobReaperLastRunAt *time.Time `json:"job_reaper_last_run_at,omitempty" format:"date-time"`
+
+	QueueDepthThreshold           int64 `json:"queue_depth_threshold"`
+	PendingJobAgeThresholdMS      int64 `json:"pending_job_age_threshold_ms"`
+	JobReaperStalenessThresholdMS int64 `json:"job_reaper_staleness_threshold_ms"`
+}
+
+type ProvisionerJobsQueueDepth struct {
+	OrganizationID        uuid.UUID         `json:"organization_id" format:"uuid"`
+	Tags                  map[string]string `json:"tags"`
+	PendingJobs           int64             `json:"pending_jobs"`
+	OldestPendingJobAgeMS int64             `json:"oldest_pending_job_age_ms"`
+}
+
+// JobReaperReport describes recent activity of the job reaper, which terminates
+// hung and pending provisioner jobs.
+type JobReaperReport struct {
+	// LastRunAt is the time of the last successful job reaper run, if the job
+	// reaper ever ran.
+	LastRunAt *time.Time `json:"last_run_at,omitempty" format:"date-time"`
+	// ReapedJobs are the jobs terminated by the job reaper during the last
+	// day, most recent first.
+	ReapedJobs []JobReaperJob `json:"reaped_jobs"`
+	// HungJobCandidates are the jobs eligible to be terminated by the next
+	// job reaper run.
+	HungJobCandidates []JobReaperJob `json:"hung_job_candidates"`
+}
+
+type JobReaperJob struct {
+	ID             uuid.UUID                     `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID                     `json:"organization_id" format:"uuid"`
+	Type           codersdk.ProvisionerJobType   `json:"type"`
+	Status         codersdk.ProvisionerJobStatus `json:"status"`
+	Tags           map[string]string             `json:"tags"`
+	CreatedAt      time.Time                     `json:"created_at" format:"date-time"`
+	UpdatedAt      time.Time                     `json:"updated_at" format:"date-time"`
+	StartedAt      *time.Time                    `json:"started_at,omitempty" format:"date-time"`
+	CanceledAt     *time.Time                    `json:"canceled_at,omitempty" format:"date-time"`
+	CompletedAt    *time.Time                    `json:"completed_at,omitempty" format:"date-time"`
+	Error          string                        `json:"error,omitempty"`
+}
+
 // WebsocketReport shows if the configured access URL allows establishing WebSocket connections.
 type WebsocketReport struct {
 	// Healthy is deprecated and left for backward compatibility purposes, use `Severity` instead.
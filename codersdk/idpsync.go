@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -19,12 +20,38 @@ type IDPSyncMapping[ResourceIdType uuid.UUID | string] struct {
 	Gets ResourceIdType
 }
 
+// IDPSyncMappingRule maps every IdP claim value matching an expression to a
+// set of Coder resources. Exactly one of Prefix or Regex must be set.
+type IDPSyncMappingRule struct {
+	// Prefix matches the claim values starting with the prefix.
+	Prefix string `json:"prefix,omitempty"`
+	// Regex matches the claim values matching the regular expression.
+	Regex *regexp.Regexp `json:"regex,omitempty"`
+	// Gets are the IDs of the Coder resources the user should be added to.
+	Gets []uuid.UUID `json:"gets" format:"uuid"`
+}
+
+// Matches returns true if the claim value matches the rule.
+func (r IDPSyncMappingRule) Matches(value string) bool {
+	if r.Regex != nil {
+		return r.Regex.MatchString(value)
+	}
+	return r.Prefix != "" && strings.HasPrefix(value, r.Prefix)
+}
+
 type GroupSyncSettings struct {
 	// Field is the name of the claim field that specifies what groups a user
 	// should be in. If empty, no groups will be synced.
 	Field string `json:"field"`
 	// Mapping is a map from OIDC groups to Coder group IDs
 	Mapping map[string][]uuid.UUID `json:"mapping"`
+	// MappingRules map every OIDC group matching a rule to Coder group IDs.
+	// They apply in addition to Mapping.
+	MappingRules []IDPSyncMappingRule `json:"mapping_rules,omitempty"`
+	// NestedGroupSeparator flattens nested OIDC groups when set. With "/" as
+	// the separator, a user in the "eng/backend" group is considered to be in
+	// the "eng" group as well.
+	NestedGroupSeparator string `json:"nested_group_separator,omitempty"`
 	// RegexFilter is a regular expression that filters the groups returned by
 	// the OIDC provider. Any group not matched by this regex will be ignored.
 	// If the group filter is nil, then no group filtering will occur.
@@ -189,6 +216,13 @@ type OrganizationSyncSettings struct {
 	Field string `json:"field"`
 	// Mapping maps from an OIDC claim --> Coder organization uuid
 	Mapping map[string][]uuid.UUID `json:"mapping"`
+	// MappingRules map every OIDC claim value matching a rule to Coder
+	// organization IDs. They apply in addition to Mapping.
+	MappingRules []IDPSyncMappingRule `json:"mapping_rules,omitempty"`
+	// NestedGroupSeparator flattens nested OIDC claim values when set. With
+	// "/" as the separator, a user with the "eng/backend" claim value is
+	// considered to have the "eng" value as well.
+	NestedGroupSeparator string `json:"nested_group_separator,omitempty"`
 	// AssignDefault will ensure the default org is always included
 	// for every user, regardless of their claims. This preserves legacy behavior.
 	AssignDefault bool `json:"organization_assign_default"`
@@ -261,6 +295,60 @@ func (c *Client) PatchOrganizationIDPSyncMapping(ctx context.Context, req PatchO
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// IDPSyncDryRunResponse previews the membership changes sync settings would
+// make, based on the claims users last logged in with. Only users whose
+// memberships would change are included.
+type IDPSyncDryRunResponse struct {
+	Users []IDPSyncDryRunUser `json:"users"`
+}
+
+type IDPSyncDryRunUser struct {
+	UserID   uuid.UUID                 `json:"user_id" format:"uuid"`
+	Username string                    `json:"username"`
+	Add      []IDPSyncDryRunMembership `json:"add"`
+	Remove   []IDPSyncDryRunMembership `json:"remove"`
+}
+
+// IDPSyncDryRunMembership is a group or organization the user would be added
+// to or removed from.
+type IDPSyncDryRunMembership struct {
+	// ID is not set for groups that do not exist yet, and would be created.
+	ID   *uuid.UUID `json:"id,omitempty" format:"uuid"`
+	Name string     `json:"name"`
+}
+
+// GroupIDPSyncDryRun previews the group memberships the settings would sync,
+// without saving the settings.
+func (c *Client) GroupIDPSyncDryRun(ctx context.Context, orgID string, req GroupSyncSettings) (IDPSyncDryRunResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/settings/idpsync/groups/dry-run", orgID), req)
+	if err != nil {
+		return IDPSyncDryRunResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return IDPSyncDryRunResponse{}, ReadBodyAsError(res)
+	}
+	var resp IDPSyncDryRunResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// OrganizationIDPSyncDryRun previews the organization memberships the
+// settings would sync, without saving the settings.
+func (c *Client) OrganizationIDPSyncDryRun(ctx context.Context, req OrganizationSyncSettings) (IDPSyncDryRunResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/settings/idpsync/organization/dry-run", req)
+	if err != nil {
+		return IDPSyncDryRunResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return IDPSyncDryRunResponse{}, ReadBodyAsError(res)
+	}
+	var resp IDPSyncDryRunResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 func (c *Client) GetAvailableIDPSyncFields(ctx context.Context) ([]string, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/settings/idpsync/available-fields", nil)
 	if err != nil {
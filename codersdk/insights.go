@@ -38,6 +38,17 @@ const (
 	InsightsReportIntervalWeek InsightsReportInterval = "week"
 )
 
+// CostInsightsGroupBy defines how costs are grouped in the cost insights
+// response.
+type CostInsightsGroupBy string
+
+// CostInsightsGroupBy enums.
+const (
+	CostInsightsGroupByWorkspace    CostInsightsGroupBy = "workspace"
+	CostInsightsGroupByUser         CostInsightsGroupBy = "user"
+	CostInsightsGroupByOrganization CostInsightsGroupBy = "organization"
+)
+
 // TemplateInsightsSection defines the section to be included in the template insights response.
 type TemplateInsightsSection string
 
@@ -313,3 +324,223 @@ func (c *Client) GetUserStatusCounts(ctx context.Context, req GetUserStatusCount
 	var result GetUserStatusCountsResponse
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
+
+// CostInsightsResponse is the response from the cost insights endpoint.
+type CostInsightsResponse struct {
+	Report CostInsightsReport `json:"report"`
+}
+
+// CostInsightsReport is the report from the cost insights endpoint. Costs
+// are the daily costs of the resources of workspaces, as reported by their
+// templates, prorated by the time the resources existed. They are rolled up
+// into UTC days.
+type CostInsightsReport struct {
+	StartTime   time.Time           `json:"start_time" format:"date-time"`
+	EndTime     time.Time           `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID         `json:"template_ids" format:"uuid"`
+	GroupBy     CostInsightsGroupBy `json:"group_by" enums:"workspace,user,organization"`
+	TotalCost   float64             `json:"total_cost" example:"412.5"`
+	Groups      []CostInsightsGroup `json:"groups"`
+}
+
+// CostInsightsGroup shows the cost accrued by a workspace, user or
+// organization, depending on how the report is grouped.
+type CostInsightsGroup struct {
+	ID   uuid.UUID `json:"id" format:"uuid"`
+	Name string    `json:"name"`
+	Cost float64   `json:"cost" example:"37.5"`
+	// Days holds the cost accrued in each day of the report that the group
+	// accrued any cost in, in chronological order.
+	Days []DailyCost `json:"days"`
+}
+
+// DailyCost is the cost accrued in a day.
+type DailyCost struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+	Cost      float64   `json:"cost" example:"2.5"`
+}
+
+type CostInsightsRequest struct {
+	StartTime   time.Time           `json:"start_time" format:"date-time"`
+	EndTime     time.Time           `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID         `json:"template_ids" format:"uuid"`
+	GroupBy     CostInsightsGroupBy `json:"group_by"`
+}
+
+func (c *Client) CostInsights(ctx context.Context, req CostInsightsRequest) (CostInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+	if req.GroupBy != "" {
+		qp.Add("group_by", string(req.GroupBy))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/costs?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return CostInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return CostInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result CostInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// ProvisionerInsightsResponse is the response from the provisioner insights
+// endpoint.
+type ProvisionerInsightsResponse struct {
+	Report          ProvisionerInsightsReport           `json:"report"`
+	IntervalReports []ProvisionerInsightsIntervalReport `json:"interval_reports"`
+}
+
+// ProvisionerInsightsReport is the report from the provisioner insights
+// endpoint. Busy time is the time provisioner daemons spent running jobs.
+// Idle time is estimated from when each daemon was first registered and last
+// seen, so daemons that were offline in between count as idle.
+type ProvisionerInsightsReport struct {
+	StartTime     time.Time `json:"start_time" format:"date-time"`
+	EndTime       time.Time `json:"end_time" format:"date-time"`
+	BusySeconds   int64     `json:"busy_seconds" example:"86400"`
+	IdleSeconds   int64     `json:"idle_seconds" example:"172800"`
+	Utilization   float64   `json:"utilization" example:"0.33"`
+	CompletedJobs int64     `json:"completed_jobs" example:"480"`
+	JobsPerHour   float64   `json:"jobs_per_hour" example:"2.86"`
+	// QueueWaits holds how long the jobs of each tag set that started during
+	// the report waited for a provisioner, longest waits first.
+	QueueWaits []ProvisionerQueueWait `json:"queue_waits"`
+}
+
+// ProvisionerInsightsIntervalReport is the report from the provisioner
+// insights endpoint for a specific interval.
+type ProvisionerInsightsIntervalReport struct {
+	StartTime     time.Time              `json:"start_time" format:"date-time"`
+	EndTime       time.Time              `json:"end_time" format:"date-time"`
+	Interval      InsightsReportInterval `json:"interval" example:"day"`
+	BusySeconds   int64                  `json:"busy_seconds" example:"12600"`
+	IdleSeconds   int64                  `json:"idle_seconds" example:"24000"`
+	Utilization   float64                `json:"utilization" example:"0.34"`
+	CompletedJobs int64                  `json:"completed_jobs" example:"68"`
+	JobsPerHour   float64                `json:"jobs_per_hour" example:"2.83"`
+}
+
+// ProvisionerQueueWait shows how long the jobs of a tag set waited for a
+// provisioner to pick them up.
+type ProvisionerQueueWait struct {
+	OrganizationID uuid.UUID         `json:"organization_id" format:"uuid"`
+	Tags           map[string]string `json:"tags"`
+	Jobs           int64             `json:"jobs" example:"120"`
+	P50Seconds     float64           `json:"p50_seconds" example:"4.5"`
+	P95Seconds     float64           `json:"p95_seconds" example:"61"`
+	P99Seconds     float64           `json:"p99_seconds" example:"180"`
+}
+
+type ProvisionerInsightsRequest struct {
+	StartTime time.Time              `json:"start_time" format:"date-time"`
+	EndTime   time.Time              `json:"end_time" format:"date-time"`
+	Interval  InsightsReportInterval `json:"interval" example:"day"`
+}
+
+func (c *Client) ProvisionerInsights(ctx context.Context, req ProvisionerInsightsRequest) (ProvisionerInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if req.Interval != "" {
+		qp.Add("interval", string(req.Interval))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/provisioners?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return ProvisionerInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ProvisionerInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result ProvisionerInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// UserActivityExportFormat is the file format of a user activity export.
+type UserActivityExportFormat string
+
+// UserActivityExportFormat enums.
+const (
+	UserActivityExportFormatCSV     UserActivityExportFormat = "csv"
+	UserActivityExportFormatParquet UserActivityExportFormat = "parquet"
+)
+
+// UserActivityExportStatus is the status of a user activity export.
+type UserActivityExportStatus string
+
+// UserActivityExportStatus enums.
+const (
+	UserActivityExportStatusPending   UserActivityExportStatus = "pending"
+	UserActivityExportStatusCompleted UserActivityExportStatus = "completed"
+	UserActivityExportStatusFailed    UserActivityExportStatus = "failed"
+)
+
+// UserActivityExport is an export of the activity of each user in each UTC
+// day of a time range. Exports are generated in the background, and can be
+// downloaded from DownloadURL once they complete.
+type UserActivityExport struct {
+	ID          uuid.UUID                `json:"id" format:"uuid"`
+	CreatedBy   uuid.UUID                `json:"created_by" format:"uuid"`
+	CreatedAt   time.Time                `json:"created_at" format:"date-time"`
+	StartTime   time.Time                `json:"start_time" format:"date-time"`
+	EndTime     time.Time                `json:"end_time" format:"date-time"`
+	Format      UserActivityExportFormat `json:"format" enums:"csv,parquet"`
+	Status      UserActivityExportStatus `json:"status" enums:"pending,completed,failed"`
+	FileID      *uuid.UUID               `json:"file_id,omitempty" format:"uuid"`
+	DownloadURL string                   `json:"download_url,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+	CompletedAt *time.Time               `json:"completed_at,omitempty" format:"date-time"`
+}
+
+type CreateUserActivityExportRequest struct {
+	StartTime time.Time                `json:"start_time" validate:"required" format:"date-time"`
+	EndTime   time.Time                `json:"end_time" validate:"required" format:"date-time"`
+	Format    UserActivityExportFormat `json:"format" enums:"csv,parquet"`
+}
+
+// CreateUserActivityExport starts exporting the daily activity of each user.
+// Poll UserActivityExport until the export completes.
+func (c *Client) CreateUserActivityExport(ctx context.Context, req CreateUserActivityExportRequest) (UserActivityExport, error) {
+	resp, err := c.Request(ctx, http.MethodPost, "/api/v2/insights/user-activity/exports", req)
+	if err != nil {
+		return UserActivityExport{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return UserActivityExport{}, ReadBodyAsError(resp)
+	}
+	var export UserActivityExport
+	return export, json.NewDecoder(resp.Body).Decode(&export)
+}
+
+func (c *Client) UserActivityExport(ctx context.Context, id uuid.UUID) (UserActivityExport, error) {
+	resp, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/insights/user-activity/exports/%s", id), nil)
+	if err != nil {
+		return UserActivityExport{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return UserActivityExport{}, ReadBodyAsError(resp)
+	}
+	var export UserActivityExport
+	return export, json.NewDecoder(resp.Body).Decode(&export)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -134,3 +136,48 @@ func (c *Client) DeleteLicense(ctx context.Context, id int32) error {
 	}
 	return nil
 }
+
+// LicenseUsageForecast projects when the active users of the deployment reach
+// the user limit of the license, from the trend of active users over the
+// trailing days.
+type LicenseUsageForecast struct {
+	ActiveUsers int64 `json:"active_users"`
+	// UserLimit is unset when the license does not limit active users.
+	UserLimit    *int64 `json:"user_limit,omitempty"`
+	TrailingDays int64  `json:"trailing_days"`
+	// DailyGrowth is the average change in active users per day over the
+	// trailing days.
+	DailyGrowth float64 `json:"daily_growth"`
+	// DaysUntilLimit is unset when the license does not limit active users,
+	// or when active users are not growing.
+	DaysUntilLimit     *int64                  `json:"days_until_limit,omitempty"`
+	ProjectedLimitDate *time.Time              `json:"projected_limit_date,omitempty" format:"date-time"`
+	History            []LicenseUsageDataPoint `json:"history"`
+}
+
+type LicenseUsageDataPoint struct {
+	Date        time.Time `json:"date" format:"date-time"`
+	ActiveUsers int64     `json:"active_users"`
+}
+
+type LicenseUsageForecastRequest struct {
+	// TrailingDays defaults to 30 days.
+	TrailingDays int `json:"trailing_days,omitempty"`
+}
+
+func (c *Client) LicenseUsageForecast(ctx context.Context, req LicenseUsageForecastRequest) (LicenseUsageForecast, error) {
+	qp := url.Values{}
+	if req.TrailingDays > 0 {
+		qp.Add("trailing_days", strconv.Itoa(req.TrailingDays))
+	}
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/licenses/usage-forecast?"+qp.Encode(), nil)
+	if err != nil {
+		return LicenseUsageForecast{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return LicenseUsageForecast{}, ReadBodyAsError(res)
+	}
+	var forecast LicenseUsageForecast
+	return forecast, json.NewDecoder(res.Body).Decode(&forecast)
+}
@@ -0,0 +1,86 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SimulateWorkspaceLifecycleRequest describes a hypothetical workspace created
+// from a template. Nothing is created; the request is only used to compute the
+// policies that would apply to the workspace.
+type SimulateWorkspaceLifecycleRequest struct {
+	// UserID is the owner of the hypothetical workspace. Defaults to the
+	// authenticated user.
+	UserID uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	// AutostartSchedule is the cron schedule the workspace would be created
+	// with, as in CreateWorkspaceRequest.
+	AutostartSchedule *string `json:"autostart_schedule,omitempty"`
+	// TTLMillis is the TTL the workspace would be created with. Defaults to
+	// the template's default TTL.
+	TTLMillis *int64 `json:"ttl_ms,omitempty"`
+	// StartAt is when the workspace is assumed to be started. Defaults to
+	// the current time.
+	StartAt *time.Time `json:"start_at,omitempty" format:"date-time"`
+}
+
+// WorkspaceLifecycleSimulation contains the effective lifecycle policies for
+// a hypothetical workspace. Times are computed assuming the workspace is
+// started at StartAt and not used afterwards.
+type WorkspaceLifecycleSimulation struct {
+	StartAt time.Time `json:"start_at" format:"date-time"`
+	// NextAutostart is the next time the workspace would be started by its
+	// autostart schedule, if it has one and the template allows it.
+	NextAutostart *time.Time `json:"next_autostart,omitempty" format:"date-time"`
+	// Deadline is when the workspace would be stopped if there is no
+	// activity.
+	Deadline *time.Time `json:"deadline,omitempty" format:"date-time"`
+	// MaxDeadline is the latest the workspace can be stopped, regardless of
+	// activity, as enforced by the template's autostop requirement.
+	MaxDeadline *time.Time `json:"max_deadline,omitempty" format:"date-time"`
+	// ActivityBumpMillis is how far the deadline is bumped on activity.
+	ActivityBumpMillis int64 `json:"activity_bump_ms"`
+	// DormantAt is when the workspace would become dormant due to
+	// inactivity.
+	DormantAt *time.Time `json:"dormant_at,omitempty" format:"date-time"`
+	// DeletingAt is when the workspace would be deleted after becoming
+	// dormant.
+	DeletingAt *time.Time `json:"deleting_at,omitempty" format:"date-time"`
+	// Quota is the quota charge of the workspace. It is omitted if quotas
+	// are not enforced on the deployment.
+	Quota *WorkspaceLifecycleSimulationQuota `json:"quota,omitempty"`
+	// MaintenanceWindows are the upcoming quiet hours windows of the owner in
+	// which the template's autostop requirement forces the workspace to stop.
+	MaintenanceWindows []time.Time `json:"maintenance_windows" format:"date-time"`
+}
+
+type WorkspaceLifecycleSimulationQuota struct {
+	// DailyCost is the cost of the resources of the template's active
+	// version.
+	DailyCost int32 `json:"daily_cost"`
+	// CreditsConsumed is the owner's current consumption, excluding the
+	// hypothetical workspace.
+	CreditsConsumed int `json:"credits_consumed"`
+	Budget          int `json:"budget"`
+	// Allowed is false if starting the workspace would exceed the budget.
+	Allowed bool `json:"allowed"`
+}
+
+// SimulateWorkspaceLifecycle computes the effective lifecycle policies for a
+// hypothetical workspace created from a template.
+func (c *Client) SimulateWorkspaceLifecycle(ctx context.Context, templateID uuid.UUID, req SimulateWorkspaceLifecycleRequest) (WorkspaceLifecycleSimulation, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/lifecycle-simulation", templateID), req)
+	if err != nil {
+		return WorkspaceLifecycleSimulation{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceLifecycleSimulation{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceLifecycleSimulation
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
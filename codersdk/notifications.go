@@ -36,7 +36,40 @@ type NotificationMethodsResponse struct {
 type NotificationPreference struct {
 	NotificationTemplateID uuid.UUID `json:"id" format:"uuid"`
 	Disabled               bool      `json:"disabled"`
-	UpdatedAt              time.Time `json:"updated_at" format:"date-time"`
+	// Digest is true if the notifications of this template are batched into
+	// the periodic digest of the user instead of being delivered immediately.
+	Digest    bool      `json:"digest"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+// NotificationDigestFrequency is how often digests of batched notifications
+// are delivered to a user.
+type NotificationDigestFrequency string
+
+const (
+	NotificationDigestFrequencyDaily  NotificationDigestFrequency = "daily"
+	NotificationDigestFrequencyWeekly NotificationDigestFrequency = "weekly"
+)
+
+func (f NotificationDigestFrequency) Valid() bool {
+	switch f {
+	case NotificationDigestFrequencyDaily, NotificationDigestFrequencyWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+type NotificationDigestSettings struct {
+	Frequency NotificationDigestFrequency `json:"frequency" enums:"daily,weekly"`
+}
+
+// NotificationBuildDurationSettings configures the notification a user
+// receives when one of their workspace builds runs for longer than expected.
+type NotificationBuildDurationSettings struct {
+	// ThresholdMillis is how long a workspace build runs before its owner is
+	// notified. Zero disables the notification.
+	ThresholdMillis int64 `json:"threshold_ms"`
 }
 
 // GetNotificationsSettings retrieves the notifications settings, which currently just describes whether all
@@ -168,6 +201,70 @@ func (c *Client) UpdateUserNotificationPreferences(ctx context.Context, userID u
 	return prefs, nil
 }
 
+// GetUserNotificationDigestSettings retrieves the notification digest settings of a given user.
+func (c *Client) GetUserNotificationDigestSettings(ctx context.Context, userID uuid.UUID) (NotificationDigestSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/preferences/digest", userID.String()), nil)
+	if err != nil {
+		return NotificationDigestSettings{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return NotificationDigestSettings{}, ReadBodyAsError(res)
+	}
+
+	var settings NotificationDigestSettings
+	return settings, json.NewDecoder(res.Body).Decode(&settings)
+}
+
+// UpdateUserNotificationDigestSettings updates the notification digest settings of a given user.
+func (c *Client) UpdateUserNotificationDigestSettings(ctx context.Context, userID uuid.UUID, req NotificationDigestSettings) (NotificationDigestSettings, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/preferences/digest", userID.String()), req)
+	if err != nil {
+		return NotificationDigestSettings{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return NotificationDigestSettings{}, ReadBodyAsError(res)
+	}
+
+	var settings NotificationDigestSettings
+	return settings, json.NewDecoder(res.Body).Decode(&settings)
+}
+
+// GetUserNotificationBuildDurationSettings retrieves the build duration notification settings of a given user.
+func (c *Client) GetUserNotificationBuildDurationSettings(ctx context.Context, userID uuid.UUID) (NotificationBuildDurationSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/preferences/build-duration", userID.String()), nil)
+	if err != nil {
+		return NotificationBuildDurationSettings{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return NotificationBuildDurationSettings{}, ReadBodyAsError(res)
+	}
+
+	var settings NotificationBuildDurationSettings
+	return settings, json.NewDecoder(res.Body).Decode(&settings)
+}
+
+// UpdateUserNotificationBuildDurationSettings updates the build duration notification settings of a given user.
+func (c *Client) UpdateUserNotificationBuildDurationSettings(ctx context.Context, userID uuid.UUID, req NotificationBuildDurationSettings) (NotificationBuildDurationSettings, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/preferences/build-duration", userID.String()), req)
+	if err != nil {
+		return NotificationBuildDurationSettings{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return NotificationBuildDurationSettings{}, ReadBodyAsError(res)
+	}
+
+	var settings NotificationBuildDurationSettings
+	return settings, json.NewDecoder(res.Body).Decode(&settings)
+}
+
 // GetNotificationDispatchMethods the available and default notification dispatch methods.
 func (c *Client) GetNotificationDispatchMethods(ctx context.Context) (NotificationMethodsResponse, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/notifications/dispatch-methods", nil)
@@ -212,6 +309,9 @@ type UpdateNotificationTemplateMethod struct {
 
 type UpdateUserNotificationPreferences struct {
 	TemplateDisabledMap map[string]bool `json:"template_disabled_map"`
+	// TemplateDigestMap opts notification templates into or out of the
+	// periodic digest. Templates that are omitted are left unchanged.
+	TemplateDigestMap map[string]bool `json:"template_digest_map,omitempty"`
 }
 
 type WebpushMessageAction struct {
@@ -55,6 +55,9 @@ type Organization struct {
 	CreatedAt           time.Time `table:"created at" json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt           time.Time `table:"updated at" json:"updated_at" validate:"required" format:"date-time"`
 	IsDefault           bool      `table:"default" json:"is_default" validate:"required"`
+	// CostCenter is the cost center the organization is billed to. Workspace
+	// naming policies can reference it as {{.CostCenter}}.
+	CostCenter string `table:"cost center" json:"cost_center"`
 }
 
 func (o Organization) HumanName() string {
@@ -97,6 +100,7 @@ type CreateOrganizationRequest struct {
 	DisplayName string `json:"display_name,omitempty" validate:"omitempty,organization_display_name"`
 	Description string `json:"description,omitempty"`
 	Icon        string `json:"icon,omitempty"`
+	CostCenter  string `json:"cost_center,omitempty"`
 }
 
 type UpdateOrganizationRequest struct {
@@ -104,6 +108,7 @@ type UpdateOrganizationRequest struct {
 	DisplayName string  `json:"display_name,omitempty" validate:"omitempty,organization_display_name"`
 	Description *string `json:"description,omitempty"`
 	Icon        *string `json:"icon,omitempty"`
+	CostCenter  *string `json:"cost_center,omitempty"`
 }
 
 // CreateTemplateVersionRequest enables callers to create a new Template Version.
@@ -111,12 +116,16 @@ type CreateTemplateVersionRequest struct {
 	Name    string `json:"name,omitempty" validate:"omitempty,template_version_name"`
 	Message string `json:"message,omitempty" validate:"lt=1048577"`
 	// TemplateID optionally associates a version with a template.
-	TemplateID      uuid.UUID                `json:"template_id,omitempty" format:"uuid"`
-	StorageMethod   ProvisionerStorageMethod `json:"storage_method" validate:"oneof=file,required" enums:"file"`
-	FileID          uuid.UUID                `json:"file_id,omitempty" validate:"required_without=ExampleID" format:"uuid"`
-	ExampleID       string                   `json:"example_id,omitempty" validate:"required_without=FileID"`
-	Provisioner     ProvisionerType          `json:"provisioner" validate:"oneof=terraform echo,required"`
-	ProvisionerTags map[string]string        `json:"tags"`
+	TemplateID    uuid.UUID                `json:"template_id,omitempty" format:"uuid"`
+	StorageMethod ProvisionerStorageMethod `json:"storage_method" validate:"oneof=file,required" enums:"file"`
+	FileID        uuid.UUID                `json:"file_id,omitempty" validate:"required_without_all=ExampleID OCIReference" format:"uuid"`
+	ExampleID     string                   `json:"example_id,omitempty" validate:"required_without_all=FileID OCIReference"`
+	// OCIReference is a reference to an OCI artifact that contains the
+	// template as a tar layer, such as "ghcr.io/acme/templates/docker:v1".
+	// coderd pulls the artifact, verifies its digests and stores it as a file.
+	OCIReference    string            `json:"oci_reference,omitempty" validate:"required_without_all=FileID ExampleID"`
+	Provisioner     ProvisionerType   `json:"provisioner" validate:"oneof=terraform echo,required"`
+	ProvisionerTags map[string]string `json:"tags"`
 
 	UserVariableValues []VariableValue `json:"user_variable_values,omitempty"`
 }
@@ -227,6 +236,10 @@ type CreateWorkspaceRequest struct {
 	RichParameterValues     []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
 	AutomaticUpdates        AutomaticUpdates          `json:"automatic_updates,omitempty"`
 	TemplateVersionPresetID uuid.UUID                 `json:"template_version_preset_id,omitempty" format:"uuid"`
+	// TemplatePresetID is the ID of the template preset to use for the initial
+	// build. If neither TemplatePresetID nor TemplateVersionPresetID is set,
+	// the default template preset of the groups of the owner is used.
+	TemplatePresetID uuid.UUID `json:"template_preset_id,omitempty" format:"uuid"`
 }
 
 func (c *Client) OrganizationByName(ctx context.Context, name string) (Organization, error) {
@@ -376,10 +389,14 @@ func (c *Client) OrganizationProvisionerDaemons(ctx context.Context, organizatio
 }
 
 type OrganizationProvisionerJobsOptions struct {
-	Limit  int
-	IDs    []uuid.UUID
-	Status []ProvisionerJobStatus
-	Tags   map[string]string
+	Limit int
+	// AfterID returns the jobs created before the given job. Set it to the ID
+	// of the last job of the previous page to paginate.
+	AfterID uuid.UUID
+	IDs     []uuid.UUID
+	Status  []ProvisionerJobStatus
+	Types   []ProvisionerJobType
+	Tags    map[string]string
 }
 
 func (c *Client) OrganizationProvisionerJobs(ctx context.Context, organizationID uuid.UUID, opts *OrganizationProvisionerJobsOptions) ([]ProvisionerJob, error) {
@@ -388,12 +405,18 @@ func (c *Client) OrganizationProvisionerJobs(ctx context.Context, organizationID
 		if opts.Limit > 0 {
 			qp.Add("limit", strconv.Itoa(opts.Limit))
 		}
+		if opts.AfterID != uuid.Nil {
+			qp.Add("after_id", opts.AfterID.String())
+		}
 		if len(opts.IDs) > 0 {
 			qp.Add("ids", joinSliceStringer(opts.IDs))
 		}
 		if len(opts.Status) > 0 {
 			qp.Add("status", joinSlice(opts.Status))
 		}
+		if len(opts.Types) > 0 {
+			qp.Add("type", joinSlice(opts.Types))
+		}
 		if len(opts.Tags) > 0 {
 			tagsRaw, err := json.Marshal(opts.Tags)
 			if err != nil {
@@ -436,6 +459,88 @@ func (c *Client) OrganizationProvisionerJob(ctx context.Context, organizationID,
 	return job, json.NewDecoder(res.Body).Decode(&job)
 }
 
+// ReapOrganizationProvisionerJob forcefully marks a job that has not completed
+// as failed, as if it had been detected as hung.
+func (c *Client) ReapOrganizationProvisionerJob(ctx context.Context, organizationID, jobID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerjobs/%s/reap", organizationID.String(), jobID.String()),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WatchOrganizationProvisionerJobs returns a channel that receives provisioner
+// jobs of the organization when they are queued, acquired, canceled or
+// completed. It returns once the server is ready, so jobs listed afterwards
+// don't miss state changes. The channel is closed when the context is canceled
+// or the connection is lost.
+func (c *Client) WatchOrganizationProvisionerJobs(ctx context.Context, organizationID uuid.UUID) (<-chan ProvisionerJob, error) {
+	//nolint:bodyclose
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerjobs/watch", organizationID.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	nextEvent := ServerSentEventReader(ctx, res.Body)
+
+	// The server sends a ping once it's subscribed to job state changes.
+	sse, err := nextEvent()
+	if err != nil {
+		_ = res.Body.Close()
+		return nil, xerrors.Errorf("read ready event: %w", err)
+	}
+	if sse.Type != ServerSentEventTypePing {
+		_ = res.Body.Close()
+		return nil, xerrors.Errorf("unexpected event %q", sse.Type)
+	}
+
+	jobs := make(chan ProvisionerJob, 256)
+	go func() {
+		defer close(jobs)
+		defer res.Body.Close()
+
+		for {
+			sse, err := nextEvent()
+			if err != nil {
+				return
+			}
+			if sse.Type != ServerSentEventTypeData {
+				continue
+			}
+			b, ok := sse.Data.([]byte)
+			if !ok {
+				return
+			}
+			var job ProvisionerJob
+			err = json.Unmarshal(b, &job)
+			if err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- job:
+			}
+		}
+	}()
+
+	return jobs, nil
+}
+
 func joinSlice[T ~string](s []T) string {
 	var ss []string
 	for _, v := range s {
@@ -533,6 +638,9 @@ type TemplateFilter struct {
 	ExactName      string    `typescript:"-"`
 	FuzzyName      string    `typescript:"-"`
 	SearchQuery    string    `json:"q,omitempty"`
+	// Fields limits the returned templates to the given JSON fields, e.g.
+	// "active_version_id". Fields that are not selected are left empty.
+	Fields []string `json:"fields,omitempty" typescript:"-"`
 }
 
 // asRequestOption returns a function that can be used in (*Client).Request.
@@ -559,6 +667,9 @@ func (f TemplateFilter) asRequestOption() RequestOption {
 
 		q := r.URL.Query()
 		q.Set("q", strings.Join(params, " "))
+		if len(f.Fields) > 0 {
+			q.Set("fields", strings.Join(f.Fields, ","))
+		}
 		r.URL.RawQuery = q.Encode()
 	}
 }
@@ -608,13 +719,13 @@ func (c *Client) TemplateByName(ctx context.Context, organizationID uuid.UUID, n
 // CreateWorkspace creates a new workspace for the template specified.
 //
 // Deprecated: Use CreateUserWorkspace instead.
-func (c *Client) CreateWorkspace(ctx context.Context, _ uuid.UUID, user string, request CreateWorkspaceRequest) (Workspace, error) {
-	return c.CreateUserWorkspace(ctx, user, request)
+func (c *Client) CreateWorkspace(ctx context.Context, _ uuid.UUID, user string, request CreateWorkspaceRequest, opts ...RequestOption) (Workspace, error) {
+	return c.CreateUserWorkspace(ctx, user, request, opts...)
 }
 
 // CreateUserWorkspace creates a new workspace for the template specified.
-func (c *Client) CreateUserWorkspace(ctx context.Context, user string, request CreateWorkspaceRequest) (Workspace, error) {
-	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/workspaces", user), request)
+func (c *Client) CreateUserWorkspace(ctx context.Context, user string, request CreateWorkspaceRequest, opts ...RequestOption) (Workspace, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/workspaces", user), request, opts...)
 	if err != nil {
 		return Workspace{}, err
 	}
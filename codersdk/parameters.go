@@ -2,7 +2,9 @@ package codersdk
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -142,3 +144,19 @@ func (c *Client) TemplateVersionDynamicParameters(ctx context.Context, userID st
 	}
 	return wsjson.NewStream[DynamicParametersResponse, DynamicParametersRequest](conn, websocket.MessageText, websocket.MessageText, c.Logger()), nil
 }
+
+// EvaluateTemplateVersionDynamicParameters renders the parameters of a template
+// version once with the given inputs. Unlike TemplateVersionDynamicParameters,
+// it does not keep a connection open.
+func (c *Client) EvaluateTemplateVersionDynamicParameters(ctx context.Context, version uuid.UUID, req DynamicParametersRequest) (DynamicParametersResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateversions/%s/dynamic-parameters/evaluate", version), req)
+	if err != nil {
+		return DynamicParametersResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return DynamicParametersResponse{}, ReadBodyAsError(res)
+	}
+	var resp DynamicParametersResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -0,0 +1,92 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrebuiltWorkspaceReservation holds a prebuilt workspace for a specific user
+// or group. Until the reservation expires, the prebuilt workspace can only be
+// claimed by the user or a member of the group. Exactly one of UserID and
+// GroupID is set.
+type PrebuiltWorkspaceReservation struct {
+	WorkspaceID uuid.UUID  `json:"workspace_id" format:"uuid"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	GroupID     *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	ReservedBy  uuid.UUID  `json:"reserved_by" format:"uuid"`
+	CreatedAt   time.Time  `json:"created_at" format:"date-time"`
+	ExpiresAt   time.Time  `json:"expires_at" format:"date-time"`
+}
+
+// ReservePrebuiltWorkspaceRequest reserves a prebuilt workspace. Exactly one
+// of UserID and GroupID must be set.
+type ReservePrebuiltWorkspaceRequest struct {
+	UserID    *uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	GroupID   *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	ExpiresAt time.Time  `json:"expires_at" format:"date-time" validate:"required"`
+}
+
+// ReservePrebuiltWorkspace reserves a prebuilt workspace for a user or group,
+// replacing any existing reservation.
+func (c *Client) ReservePrebuiltWorkspace(ctx context.Context, workspace uuid.UUID, req ReservePrebuiltWorkspaceRequest) (PrebuiltWorkspaceReservation, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/prebuild-reservation", workspace), req)
+	if err != nil {
+		return PrebuiltWorkspaceReservation{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return PrebuiltWorkspaceReservation{}, ReadBodyAsError(res)
+	}
+	var reservation PrebuiltWorkspaceReservation
+	return reservation, json.NewDecoder(res.Body).Decode(&reservation)
+}
+
+// PrebuiltWorkspaceReservation returns the reservation of a prebuilt
+// workspace, including an expired one.
+func (c *Client) PrebuiltWorkspaceReservation(ctx context.Context, workspace uuid.UUID) (PrebuiltWorkspaceReservation, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/prebuild-reservation", workspace), nil)
+	if err != nil {
+		return PrebuiltWorkspaceReservation{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return PrebuiltWorkspaceReservation{}, ReadBodyAsError(res)
+	}
+	var reservation PrebuiltWorkspaceReservation
+	return reservation, json.NewDecoder(res.Body).Decode(&reservation)
+}
+
+// DeletePrebuiltWorkspaceReservation releases a prebuilt workspace so that
+// anyone may claim it.
+func (c *Client) DeletePrebuiltWorkspaceReservation(ctx context.Context, workspace uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/prebuild-reservation", workspace), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TemplatePrebuiltWorkspaceReservations returns the reservations of the
+// prebuilt workspaces of a template that have not been claimed yet, soonest
+// to expire first.
+func (c *Client) TemplatePrebuiltWorkspaceReservations(ctx context.Context, template uuid.UUID) ([]PrebuiltWorkspaceReservation, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/prebuild-reservations", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var reservations []PrebuiltWorkspaceReservation
+	return reservations, json.NewDecoder(res.Body).Decode(&reservations)
+}
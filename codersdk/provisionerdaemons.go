@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
 	"slices"
 	"strings"
 	"time"
@@ -154,11 +155,23 @@ const (
 	ProvisionerJobTypeTemplateVersionDryRun ProvisionerJobType = "template_version_dry_run"
 )
 
+func ProvisionerJobTypeEnums() []ProvisionerJobType {
+	return []ProvisionerJobType{
+		ProvisionerJobTypeTemplateVersionImport,
+		ProvisionerJobTypeWorkspaceBuild,
+		ProvisionerJobTypeTemplateVersionDryRun,
+	}
+}
+
 // JobErrorCode defines the error code returned by job runner.
 type JobErrorCode string
 
 const (
 	RequiredTemplateVariables JobErrorCode = "REQUIRED_TEMPLATE_VARIABLES"
+	// JobReaped is set on jobs that were terminated by the job reaper, either
+	// because they were hung or pending for too long, or because an
+	// administrator reaped them manually.
+	JobReaped JobErrorCode = "JOB_REAPED"
 )
 
 // JobIsMissingParameterErrorCode returns whether the error is a missing parameter error.
@@ -167,6 +180,12 @@ func JobIsMissingParameterErrorCode(code JobErrorCode) bool {
 	return string(code) == runner.MissingParameterErrorCode
 }
 
+// JobIsResourceCeilingErrorCode returns whether the error is caused by planned
+// resources that exceed the resource ceilings of the template.
+func JobIsResourceCeilingErrorCode(code JobErrorCode) bool {
+	return string(code) == runner.ResourceCeilingExceededErrorCode
+}
+
 // ProvisionerJob describes the job executed by the provisioning daemon.
 type ProvisionerJob struct {
 	ID               uuid.UUID              `json:"id" format:"uuid" table:"id"`
@@ -175,7 +194,7 @@ type ProvisionerJob struct {
 	CompletedAt      *time.Time             `json:"completed_at,omitempty" format:"date-time" table:"completed at"`
 	CanceledAt       *time.Time             `json:"canceled_at,omitempty" format:"date-time" table:"canceled at"`
 	Error            string                 `json:"error,omitempty" table:"error"`
-	ErrorCode        JobErrorCode           `json:"error_code,omitempty" enums:"REQUIRED_TEMPLATE_VARIABLES" table:"error code"`
+	ErrorCode        JobErrorCode           `json:"error_code,omitempty" enums:"REQUIRED_TEMPLATE_VARIABLES,JOB_REAPED" table:"error code"`
 	Status           ProvisionerJobStatus   `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed" table:"status"`
 	WorkerID         *uuid.UUID             `json:"worker_id,omitempty" format:"uuid" table:"worker id"`
 	WorkerName       string                 `json:"worker_name,omitempty" table:"worker name"`
@@ -183,6 +202,7 @@ type ProvisionerJob struct {
 	Tags             map[string]string      `json:"tags" table:"tags"`
 	QueuePosition    int                    `json:"queue_position" table:"queue position"`
 	QueueSize        int                    `json:"queue_size" table:"queue size"`
+	EstimatedStartAt *time.Time             `json:"estimated_start_at,omitempty" format:"date-time" table:"estimated start at"`
 	OrganizationID   uuid.UUID              `json:"organization_id" format:"uuid" table:"organization id"`
 	Input            ProvisionerJobInput    `json:"input" table:"input,recursive_inline"`
 	Type             ProvisionerJobType     `json:"type" table:"type"`
@@ -198,10 +218,77 @@ type ProvisionerJobLog struct {
 	Level     LogLevel  `json:"log_level" enums:"trace,debug,info,warn,error"`
 	Stage     string    `json:"stage"`
 	Output    string    `json:"output"`
+	// Fields are only included if the "structured" query parameter is set.
+	Fields *ProvisionerJobLogFields `json:"fields,omitempty"`
+}
+
+// ProvisionerJobLogFields are structured fields extracted from
+// machine-readable provisioner output, such as Terraform's JSON logs.
+type ProvisionerJobLogFields struct {
+	// ResourceAddress is the address of the resource the log relates to.
+	ResourceAddress string `json:"resource_address,omitempty"`
+	// Action is the action performed on the resource, e.g. "create".
+	Action string `json:"action,omitempty"`
+	// DiagnosticSeverity is set for logs that are part of a diagnostic.
+	DiagnosticSeverity string `json:"diagnostic_severity,omitempty"`
+}
+
+// ProvisionerJobLogsFilter filters the logs of a provisioner job. Unset
+// fields match every log.
+type ProvisionerJobLogsFilter struct {
+	// Level only matches logs at or above the given level.
+	Level LogLevel `json:"level,omitempty"`
+	// Stage only matches logs of the given stage.
+	Stage string `json:"stage,omitempty"`
+	// Search only matches logs whose output contains every word of the
+	// search.
+	Search string `json:"search,omitempty"`
+}
+
+// asRequestOption returns a function that can be used in (*Client).Request.
+// It modifies the request query parameters.
+func (f ProvisionerJobLogsFilter) asRequestOption() RequestOption {
+	return func(r *http.Request) {
+		q := r.URL.Query()
+		f.setQuery(q)
+		r.URL.RawQuery = q.Encode()
+	}
+}
+
+func (f ProvisionerJobLogsFilter) setQuery(q url.Values) {
+	if f.Level != "" {
+		q.Set("level", string(f.Level))
+	}
+	if f.Stage != "" {
+		q.Set("stage", f.Stage)
+	}
+	if f.Search != "" {
+		q.Set("search", f.Search)
+	}
+}
+
+// provisionerJobLogs returns the current logs of a job matching the filter.
+func (c *Client) provisionerJobLogs(ctx context.Context, path string, filter ProvisionerJobLogsFilter) ([]ProvisionerJobLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, path, nil, filter.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []ProvisionerJobLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
 }
 
 // provisionerJobLogsAfter streams logs that occurred after a specific time.
 func (c *Client) provisionerJobLogsAfter(ctx context.Context, path string, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
+	return c.provisionerJobLogsAfterFiltered(ctx, path, after, ProvisionerJobLogsFilter{})
+}
+
+// provisionerJobLogsAfterFiltered streams logs matching the filter that
+// occurred after a specific time.
+func (c *Client) provisionerJobLogsAfterFiltered(ctx context.Context, path string, after int64, filter ProvisionerJobLogsFilter) (<-chan ProvisionerJobLog, io.Closer, error) {
 	afterQuery := ""
 	if after != 0 {
 		afterQuery = fmt.Sprintf("&after=%d", after)
@@ -210,6 +297,11 @@ func (c *Client) provisionerJobLogsAfter(ctx context.Context, path string, after
 	if err != nil {
 		return nil, nil, err
 	}
+	if filter != (ProvisionerJobLogsFilter{}) {
+		q := followURL.Query()
+		filter.setQuery(q)
+		followURL.RawQuery = q.Encode()
+	}
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("create cookie jar: %w", err)
@@ -354,6 +446,18 @@ type ProvisionerKey struct {
 	OrganizationID uuid.UUID          `json:"organization" table:"-" format:"uuid"`
 	Name           string             `json:"name" table:"name,default_sort"`
 	Tags           ProvisionerKeyTags `json:"tags" table:"tags"`
+	// LastUsedAt is the last time a provisioner daemon authenticated with
+	// this key.
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" table:"last used at" format:"date-time"`
+	// ExpiresAt is when the key stops being accepted. Keys without an expiry
+	// are valid until deleted.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" table:"expires at" format:"date-time"`
+	// RotationOverlapEndsAt is set after a rotation and marks when the
+	// previous secret stops being accepted.
+	RotationOverlapEndsAt *time.Time `json:"rotation_overlap_ends_at,omitempty" table:"rotation overlap ends at" format:"date-time"`
+	// ConnectedDaemons is the number of provisioner daemons that have
+	// recently been seen using this key.
+	ConnectedDaemons int `json:"connected_daemons" table:"connected daemons"`
 	// HashedSecret - never include the access token in the API response
 }
 
@@ -391,6 +495,21 @@ func ReservedProvisionerKeyNames() []string {
 type CreateProvisionerKeyRequest struct {
 	Name string            `json:"name"`
 	Tags map[string]string `json:"tags"`
+	// ExpiresAt optionally limits how long the key can be used to
+	// authenticate.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
+}
+
+// RotateProvisionerKeyRequest rotates the secret of an existing provisioner
+// key. The previous secret remains valid for the overlap window so that a
+// fleet of daemons can be migrated to the new secret without downtime.
+type RotateProvisionerKeyRequest struct {
+	// OverlapMillis is how long the previous secret continues to be
+	// accepted. Zero invalidates the previous secret immediately.
+	OverlapMillis int64 `json:"overlap_ms"`
+	// ExpiresAt optionally sets a new expiry for the key. When omitted, the
+	// key no longer expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
 }
 
 type CreateProvisionerKeyResponse struct {
@@ -415,6 +534,24 @@ func (c *Client) CreateProvisionerKey(ctx context.Context, organizationID uuid.U
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// RotateProvisionerKey generates a new secret for an existing provisioner key.
+func (c *Client) RotateProvisionerKey(ctx context.Context, organizationID uuid.UUID, name string, req RotateProvisionerKeyRequest) (CreateProvisionerKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys/%s/rotate", organizationID.String(), name),
+		req,
+	)
+	if err != nil {
+		return CreateProvisionerKeyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return CreateProvisionerKeyResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateProvisionerKeyResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // ListProvisionerKeys lists all provisioner keys for an organization.
 func (c *Client) ListProvisionerKeys(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error) {
 	res, err := c.Request(ctx, http.MethodGet,
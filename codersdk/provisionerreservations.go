@@ -0,0 +1,78 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProvisionerReservation holds back provisioner capacity for jobs matching a
+// set of tags during a time window. While a reservation is active, provisioner
+// daemons capable of running the reserved jobs will not pick up other jobs if
+// doing so would leave fewer than Slots daemons available.
+type ProvisionerReservation struct {
+	ID             uuid.UUID         `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID         `json:"organization_id" format:"uuid"`
+	Tags           map[string]string `json:"tags"`
+	Slots          int32             `json:"slots"`
+	StartsAt       time.Time         `json:"starts_at" format:"date-time"`
+	EndsAt         time.Time         `json:"ends_at" format:"date-time"`
+	CreatedAt      time.Time         `json:"created_at" format:"date-time"`
+}
+
+type CreateProvisionerReservationRequest struct {
+	// Tags are matched against provisioner job tags. A job is covered by the
+	// reservation when its tags contain all of these tags.
+	Tags map[string]string `json:"tags" validate:"required"`
+	// Slots is the number of concurrent provisioner jobs to reserve.
+	Slots    int32     `json:"slots" validate:"required,gt=0"`
+	StartsAt time.Time `json:"starts_at" validate:"required" format:"date-time"`
+	EndsAt   time.Time `json:"ends_at" validate:"required" format:"date-time"`
+}
+
+// ProvisionerReservations returns the reservations of an organization that
+// have not ended yet.
+func (c *Client) ProvisionerReservations(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerReservation, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/provisionerreservations", organizationID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []ProvisionerReservation
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// CreateProvisionerReservation reserves provisioner capacity in an
+// organization.
+func (c *Client) CreateProvisionerReservation(ctx context.Context, organizationID uuid.UUID, req CreateProvisionerReservationRequest) (ProvisionerReservation, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/provisionerreservations", organizationID), req)
+	if err != nil {
+		return ProvisionerReservation{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return ProvisionerReservation{}, ReadBodyAsError(res)
+	}
+	var resp ProvisionerReservation
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteProvisionerReservation releases a reservation.
+func (c *Client) DeleteProvisionerReservation(ctx context.Context, organizationID, reservationID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/provisionerreservations/%s", organizationID, reservationID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
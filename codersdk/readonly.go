@@ -0,0 +1,50 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// ReadOnlySettings controls the deployment-wide read-only mode. While enabled,
+// the API only accepts requests that read state, and background jobs that
+// mutate state (autobuild, prebuilds, the job reaper) are paused. It is meant
+// for maintenance windows, e.g. database upgrades or migrations.
+type ReadOnlySettings struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ReadOnlySettings returns whether the deployment is in read-only mode.
+func (c *Client) ReadOnlySettings(ctx context.Context) (ReadOnlySettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/read-only", nil)
+	if err != nil {
+		return ReadOnlySettings{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadOnlySettings{}, ReadBodyAsError(res)
+	}
+
+	var settings ReadOnlySettings
+	return settings, json.NewDecoder(res.Body).Decode(&settings)
+}
+
+// PutReadOnlySettings enables or disables the deployment-wide read-only mode.
+func (c *Client) PutReadOnlySettings(ctx context.Context, settings ReadOnlySettings) error {
+	res, err := c.Request(ctx, http.MethodPut, "/api/v2/deployment/read-only", settings)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
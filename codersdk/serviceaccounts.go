@@ -0,0 +1,163 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// ServiceAccount is a user that is used for automation rather than by a
+// person. Service accounts belong to a single organization, can't log in and
+// authenticate with tokens that organization admins manage.
+type ServiceAccount struct {
+	ID             uuid.UUID  `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID  `json:"organization_id" format:"uuid"`
+	Username       string     `json:"username"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	Status         UserStatus `json:"status" enums:"active,suspended"`
+	Roles          []SlimRole `json:"roles"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty" format:"uuid"`
+	CreatedAt      time.Time  `json:"created_at" format:"date-time"`
+}
+
+type CreateServiceAccountRequest struct {
+	Username    string `json:"username" validate:"required,username"`
+	Name        string `json:"name" validate:"user_real_name"`
+	Description string `json:"description" validate:"lt=256"`
+	// Roles are the organization roles of the service account.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// ServiceAccounts returns the service accounts of an organization.
+func (c *Client) ServiceAccounts(ctx context.Context, organizationID uuid.UUID) ([]ServiceAccount, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/service-accounts", organizationID.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var serviceAccounts []ServiceAccount
+	return serviceAccounts, json.NewDecoder(res.Body).Decode(&serviceAccounts)
+}
+
+// CreateServiceAccount creates a service account in an organization.
+func (c *Client) CreateServiceAccount(ctx context.Context, organizationID uuid.UUID, req CreateServiceAccountRequest) (ServiceAccount, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/service-accounts", organizationID.String()),
+		req,
+	)
+	if err != nil {
+		return ServiceAccount{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return ServiceAccount{}, ReadBodyAsError(res)
+	}
+	var serviceAccount ServiceAccount
+	return serviceAccount, json.NewDecoder(res.Body).Decode(&serviceAccount)
+}
+
+// DeleteServiceAccount deletes a service account, which revokes its tokens.
+// The user can be a username or UUID.
+func (c *Client) DeleteServiceAccount(ctx context.Context, organizationID uuid.UUID, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/organizations/%s/service-accounts/%s", organizationID.String(), user),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// ServiceAccountTokens returns the tokens of a service account.
+func (c *Client) ServiceAccountTokens(ctx context.Context, organizationID uuid.UUID, user string) ([]APIKey, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/service-accounts/%s/tokens", organizationID.String(), user),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var keys []APIKey
+	return keys, json.NewDecoder(res.Body).Decode(&keys)
+}
+
+// CreateServiceAccountToken creates a token that authenticates as the service
+// account.
+func (c *Client) CreateServiceAccountToken(ctx context.Context, organizationID uuid.UUID, user string, req CreateTokenRequest) (GenerateAPIKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/service-accounts/%s/tokens", organizationID.String(), user),
+		req,
+	)
+	if err != nil {
+		return GenerateAPIKeyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return GenerateAPIKeyResponse{}, ReadBodyAsError(res)
+	}
+	var apiKey GenerateAPIKeyResponse
+	return apiKey, json.NewDecoder(res.Body).Decode(&apiKey)
+}
+
+// RotateServiceAccountToken replaces a token of a service account with a new
+// one that has the same name, scope and lifetime. The old token stops working
+// immediately.
+func (c *Client) RotateServiceAccountToken(ctx context.Context, organizationID uuid.UUID, user string, tokenName string) (GenerateAPIKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/service-accounts/%s/tokens/%s/rotate", organizationID.String(), user, tokenName),
+		nil,
+	)
+	if err != nil {
+		return GenerateAPIKeyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return GenerateAPIKeyResponse{}, ReadBodyAsError(res)
+	}
+	var apiKey GenerateAPIKeyResponse
+	return apiKey, json.NewDecoder(res.Body).Decode(&apiKey)
+}
+
+// DeleteServiceAccountToken deletes a token of a service account.
+func (c *Client) DeleteServiceAccountToken(ctx context.Context, organizationID uuid.UUID, user string, tokenName string) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/organizations/%s/service-accounts/%s/tokens/%s", organizationID.String(), user, tokenName),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTemplateBuildStatsDays is the number of days of build duration stats
+// returned when no number is requested.
+const DefaultTemplateBuildStatsDays = 30
+
+// TemplateBuildStats holds the durations of the successful builds of a
+// template.
+type TemplateBuildStats struct {
+	// BuildTimeStats are the build duration percentiles of the last 30 days.
+	// Builds that take longer than the 95th percentile of their transition
+	// are flagged with WorkspaceBuild.ExceedsTemplateP95.
+	BuildTimeStats TemplateBuildTimeStats `json:"build_time_stats"`
+	// Intervals are the build durations of each transition per day, oldest
+	// first. Days without successful builds are omitted.
+	Intervals []TemplateBuildStatsInterval `json:"intervals"`
+}
+
+// TemplateBuildStatsInterval holds the durations of the successful builds of
+// a transition that completed within an interval.
+type TemplateBuildStatsInterval struct {
+	StartTime  time.Time           `json:"start_time" format:"date-time"`
+	EndTime    time.Time           `json:"end_time" format:"date-time"`
+	Transition WorkspaceTransition `json:"transition" enums:"start,stop,delete"`
+	BuildCount int64               `json:"build_count"`
+	P50Ms      int64               `json:"p50_ms" example:"123"`
+	P95Ms      int64               `json:"p95_ms" example:"146"`
+	MaxMs      int64               `json:"max_ms" example:"201"`
+}
+
+// TemplateBuildStatsRequest is the request to TemplateBuildStats.
+type TemplateBuildStatsRequest struct {
+	// Days is the number of days to return build durations for, including
+	// today. Defaults to DefaultTemplateBuildStatsDays.
+	Days int `json:"days,omitempty"`
+}
+
+// TemplateBuildStats returns the build durations of a template.
+func (c *Client) TemplateBuildStats(ctx context.Context, template uuid.UUID, req TemplateBuildStatsRequest) (TemplateBuildStats, error) {
+	var opts []RequestOption
+	if req.Days > 0 {
+		opts = append(opts, WithQueryParam("days", strconv.Itoa(req.Days)))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/build-stats", template), nil, opts...)
+	if err != nil {
+		return TemplateBuildStats{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateBuildStats{}, ReadBodyAsError(res)
+	}
+	var stats TemplateBuildStats
+	return stats, json.NewDecoder(res.Body).Decode(&stats)
+}
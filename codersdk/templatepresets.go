@@ -0,0 +1,105 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplatePreset is a named bundle of parameter values that is managed at the
+// template level, independently of the template versions. Parameters that a
+// template version does not define are ignored when building it.
+type TemplatePreset struct {
+	ID          uuid.UUID                 `json:"id" format:"uuid"`
+	TemplateID  uuid.UUID                 `json:"template_id" format:"uuid"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Parameters  []WorkspaceBuildParameter `json:"parameters"`
+	// DefaultGroupIDs are the groups whose members get the preset when they
+	// create a workspace without choosing a preset. The "Everyone" group, whose
+	// ID is the organization ID, makes the preset the default for all members.
+	DefaultGroupIDs []uuid.UUID `json:"default_group_ids" format:"uuid"`
+	CreatedAt       time.Time   `json:"created_at" format:"date-time"`
+	UpdatedAt       time.Time   `json:"updated_at" format:"date-time"`
+}
+
+// CreateTemplatePresetRequest creates a template preset.
+type CreateTemplatePresetRequest struct {
+	Name        string                    `json:"name" validate:"required"`
+	Description string                    `json:"description,omitempty"`
+	Parameters  []WorkspaceBuildParameter `json:"parameters"`
+	// DefaultGroupIDs makes the preset the default of these groups. A group
+	// has at most one default preset per template, so this replaces the
+	// previous default of the groups.
+	DefaultGroupIDs []uuid.UUID `json:"default_group_ids,omitempty" format:"uuid"`
+}
+
+// UpdateTemplatePresetRequest replaces the fields of a template preset.
+type UpdateTemplatePresetRequest struct {
+	Name        string                    `json:"name" validate:"required"`
+	Description string                    `json:"description,omitempty"`
+	Parameters  []WorkspaceBuildParameter `json:"parameters"`
+	// DefaultGroupIDs makes the preset the default of these groups, and only
+	// these groups. A group has at most one default preset per template, so
+	// this replaces the previous default of the groups.
+	DefaultGroupIDs []uuid.UUID `json:"default_group_ids,omitempty" format:"uuid"`
+}
+
+// TemplatePresets returns the presets of a template.
+func (c *Client) TemplatePresets(ctx context.Context, template uuid.UUID) ([]TemplatePreset, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/presets", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var presets []TemplatePreset
+	return presets, json.NewDecoder(res.Body).Decode(&presets)
+}
+
+// CreateTemplatePreset creates a preset for a template.
+func (c *Client) CreateTemplatePreset(ctx context.Context, template uuid.UUID, req CreateTemplatePresetRequest) (TemplatePreset, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/presets", template), req)
+	if err != nil {
+		return TemplatePreset{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplatePreset{}, ReadBodyAsError(res)
+	}
+	var preset TemplatePreset
+	return preset, json.NewDecoder(res.Body).Decode(&preset)
+}
+
+// UpdateTemplatePreset replaces the fields of a template preset.
+func (c *Client) UpdateTemplatePreset(ctx context.Context, template uuid.UUID, preset uuid.UUID, req UpdateTemplatePresetRequest) (TemplatePreset, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/presets/%s", template, preset), req)
+	if err != nil {
+		return TemplatePreset{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplatePreset{}, ReadBodyAsError(res)
+	}
+	var updated TemplatePreset
+	return updated, json.NewDecoder(res.Body).Decode(&updated)
+}
+
+// DeleteTemplatePreset deletes a template preset.
+func (c *Client) DeleteTemplatePreset(ctx context.Context, template uuid.UUID, preset uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/presets/%s", template, preset), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
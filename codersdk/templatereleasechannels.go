@@ -0,0 +1,140 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReleaseChannel is a named channel that template versions are published to.
+// Workspaces that track a channel build the version published to it instead of
+// the active version of their template.
+type ReleaseChannel string
+
+const (
+	ReleaseChannelStable ReleaseChannel = "stable"
+	ReleaseChannelBeta   ReleaseChannel = "beta"
+)
+
+// ReleaseChannels are the release channels of a template.
+var ReleaseChannels = []ReleaseChannel{ReleaseChannelStable, ReleaseChannelBeta}
+
+// Valid returns whether the release channel is known.
+func (c ReleaseChannel) Valid() bool {
+	switch c {
+	case ReleaseChannelStable, ReleaseChannelBeta:
+		return true
+	default:
+		return false
+	}
+}
+
+// TemplateReleaseChannel is the template version published to a release
+// channel of a template.
+type TemplateReleaseChannel struct {
+	TemplateID          uuid.UUID      `json:"template_id" format:"uuid"`
+	Channel             ReleaseChannel `json:"channel" enums:"stable,beta"`
+	TemplateVersionID   uuid.UUID      `json:"template_version_id" format:"uuid"`
+	TemplateVersionName string         `json:"template_version_name"`
+	UpdatedBy           uuid.UUID      `json:"updated_by" format:"uuid"`
+	UpdatedAt           time.Time      `json:"updated_at" format:"date-time"`
+}
+
+// PromoteTemplateReleaseChannelRequest publishes a template version to a
+// release channel, replacing the version previously published to it.
+type PromoteTemplateReleaseChannelRequest struct {
+	TemplateVersionID uuid.UUID `json:"template_version_id" validate:"required" format:"uuid"`
+}
+
+// WorkspaceReleaseChannel is the release channel a workspace tracks. Channel is
+// empty if the workspace follows the active version of its template.
+type WorkspaceReleaseChannel struct {
+	Channel ReleaseChannel `json:"channel,omitempty" enums:"stable,beta"`
+	// TemplateVersionID is the version published to the channel, or nil if
+	// nothing is published to it. Workspaces tracking a channel that nothing is
+	// published to follow the active version.
+	TemplateVersionID *uuid.UUID `json:"template_version_id,omitempty" format:"uuid"`
+}
+
+// UpdateWorkspaceReleaseChannelRequest sets the release channel a workspace
+// tracks. An empty channel makes the workspace follow the active version of
+// its template again.
+type UpdateWorkspaceReleaseChannelRequest struct {
+	Channel ReleaseChannel `json:"channel" enums:"stable,beta"`
+}
+
+// TemplateReleaseChannels returns the versions published to the release
+// channels of a template.
+func (c *Client) TemplateReleaseChannels(ctx context.Context, template uuid.UUID) ([]TemplateReleaseChannel, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/channels", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var channels []TemplateReleaseChannel
+	return channels, json.NewDecoder(res.Body).Decode(&channels)
+}
+
+// PromoteTemplateReleaseChannel publishes a template version to a release
+// channel of its template.
+func (c *Client) PromoteTemplateReleaseChannel(ctx context.Context, template uuid.UUID, channel ReleaseChannel, req PromoteTemplateReleaseChannelRequest) (TemplateReleaseChannel, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/channels/%s", template, channel), req)
+	if err != nil {
+		return TemplateReleaseChannel{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateReleaseChannel{}, ReadBodyAsError(res)
+	}
+	var published TemplateReleaseChannel
+	return published, json.NewDecoder(res.Body).Decode(&published)
+}
+
+// DeleteTemplateReleaseChannel unpublishes the version of a release channel.
+// Workspaces tracking the channel follow the active version until another
+// version is published to it.
+func (c *Client) DeleteTemplateReleaseChannel(ctx context.Context, template uuid.UUID, channel ReleaseChannel) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/channels/%s", template, channel), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceReleaseChannel returns the release channel a workspace tracks.
+func (c *Client) WorkspaceReleaseChannel(ctx context.Context, workspace uuid.UUID) (WorkspaceReleaseChannel, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/channel", workspace), nil)
+	if err != nil {
+		return WorkspaceReleaseChannel{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceReleaseChannel{}, ReadBodyAsError(res)
+	}
+	var channel WorkspaceReleaseChannel
+	return channel, json.NewDecoder(res.Body).Decode(&channel)
+}
+
+// UpdateWorkspaceReleaseChannel sets the release channel a workspace tracks.
+func (c *Client) UpdateWorkspaceReleaseChannel(ctx context.Context, workspace uuid.UUID, req UpdateWorkspaceReleaseChannelRequest) error {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/channel", workspace), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -63,6 +63,39 @@ type Template struct {
 	MaxPortShareLevel    WorkspaceAgentPortShareLevel `json:"max_port_share_level"`
 
 	UseClassicParameterFlow bool `json:"use_classic_parameter_flow"`
+
+	// MaxConcurrentJobsPerUser is the maximum number of pending and running
+	// workspace builds of this template a single user may have. 0 means
+	// unlimited.
+	MaxConcurrentJobsPerUser int32 `json:"max_concurrent_jobs_per_user"`
+
+	// ResourceCeilings are the maximum resources a single workspace of this
+	// template may plan. Builds that exceed them fail before any resources
+	// are created.
+	ResourceCeilings TemplateResourceCeilings `json:"resource_ceilings"`
+
+	// RequiredProvisionerTags are the provisioner tags that the jobs of this
+	// template must target, so that they only run on provisioner daemons
+	// with these tags. A value of "*" requires the tag to be set to any
+	// value. Builds and template versions that do not target the tags are
+	// rejected.
+	RequiredProvisionerTags map[string]string `json:"required_provisioner_tags"`
+}
+
+// TemplateResourceCeilings are the maximum resources that the planned
+// resources of a single workspace build may have. CPU, memory and disk are
+// summed from the `cpu`, `memory` and `disk` metadata items of the resources,
+// and the daily cost from their `daily_cost`. Ceilings that are 0 are not
+// enforced.
+type TemplateResourceCeilings struct {
+	// CPU is the number of CPU cores.
+	CPU float64 `json:"cpu,omitempty"`
+	// MemoryGiB is the memory in gibibytes.
+	MemoryGiB float64 `json:"memory_gib,omitempty"`
+	// DiskGiB is the disk size in gibibytes.
+	DiskGiB float64 `json:"disk_gib,omitempty"`
+	// DailyCost is in quota cost units.
+	DailyCost int32 `json:"daily_cost,omitempty"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -258,6 +291,16 @@ type UpdateTemplateMeta struct {
 	// made the default.
 	// An "opt-out" is present in case the new feature breaks some existing templates.
 	UseClassicParameterFlow *bool `json:"use_classic_parameter_flow,omitempty"`
+	// MaxConcurrentJobsPerUser limits the number of pending and running
+	// workspace builds of this template a single user may have. 0 removes
+	// the limit.
+	MaxConcurrentJobsPerUser *int32 `json:"max_concurrent_jobs_per_user,omitempty"`
+	// ResourceCeilings replaces the resource ceilings of the template. An
+	// empty value removes all ceilings.
+	ResourceCeilings *TemplateResourceCeilings `json:"resource_ceilings,omitempty"`
+	// RequiredProvisionerTags replaces the provisioner tags that the jobs of
+	// the template must target. An empty map removes the requirement.
+	RequiredProvisionerTags *map[string]string `json:"required_provisioner_tags,omitempty"`
 }
 
 type TemplateExample struct {
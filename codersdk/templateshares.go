@@ -0,0 +1,78 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateShare is an organization that a template is shared into. Members of
+// the organization can read the template and its versions, but cannot modify
+// it or create workspaces from it.
+type TemplateShare struct {
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	CreatedBy      uuid.UUID `json:"created_by" format:"uuid"`
+	CreatedAt      time.Time `json:"created_at" format:"date-time"`
+}
+
+// TemplateShares returns the organizations a template is shared into.
+func (c *Client) TemplateShares(ctx context.Context, template uuid.UUID) ([]TemplateShare, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/shares", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var shares []TemplateShare
+	return shares, json.NewDecoder(res.Body).Decode(&shares)
+}
+
+// ShareTemplate shares a template read-only into an organization. Sharing a
+// template into an organization it is already shared into is a no-op.
+func (c *Client) ShareTemplate(ctx context.Context, template uuid.UUID, organization uuid.UUID) (TemplateShare, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/shares/%s", template, organization), nil)
+	if err != nil {
+		return TemplateShare{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateShare{}, ReadBodyAsError(res)
+	}
+	var share TemplateShare
+	return share, json.NewDecoder(res.Body).Decode(&share)
+}
+
+// UnshareTemplate stops sharing a template into an organization.
+func (c *Client) UnshareTemplate(ctx context.Context, template uuid.UUID, organization uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/shares/%s", template, organization), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TemplatesSharedWithOrganization returns the templates of other organizations
+// that are shared into an organization.
+func (c *Client) TemplatesSharedWithOrganization(ctx context.Context, organization uuid.UUID) ([]Template, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/shared-templates", organization), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var templates []Template
+	return templates, json.NewDecoder(res.Body).Decode(&templates)
+}
@@ -0,0 +1,97 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateVersionRollout is the gradual promotion of a template version. Builds
+// that target the active version of the template use the rollout version
+// instead if their workspace falls within Percent, or its owner is a member of
+// the group. Builds of a specific version are not affected.
+//
+// The rollout ends when its version is promoted to the active version, or when
+// it is deleted.
+type TemplateVersionRollout struct {
+	TemplateID        uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID `json:"template_version_id" format:"uuid"`
+	// Percent is the percentage of workspaces that build the rollout version.
+	// Workspaces are bucketed by their ID, so raising the percentage only adds
+	// workspaces to the rollout.
+	Percent int32 `json:"percent"`
+	// GroupID is the group whose members always build the rollout version.
+	GroupID   *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	CreatedBy uuid.UUID  `json:"created_by" format:"uuid"`
+	CreatedAt time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time  `json:"updated_at" format:"date-time"`
+	// RolloutVersionStats and ActiveVersionStats compare the builds of the
+	// rollout version and the active version since the rollout started.
+	RolloutVersionStats TemplateVersionRolloutStats `json:"rollout_version_stats"`
+	ActiveVersionStats  TemplateVersionRolloutStats `json:"active_version_stats"`
+}
+
+// TemplateVersionRolloutStats counts the completed builds of a template version
+// since its rollout started.
+type TemplateVersionRolloutStats struct {
+	TemplateVersionID uuid.UUID `json:"template_version_id" format:"uuid"`
+	TotalBuilds       int64     `json:"total_builds"`
+	FailedBuilds      int64     `json:"failed_builds"`
+	// FailureRate is the fraction of builds that failed, or 0 if there were
+	// no builds.
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// UpdateTemplateVersionRolloutRequest starts or updates the rollout of a
+// template. Switching to another version starts a new rollout.
+type UpdateTemplateVersionRolloutRequest struct {
+	TemplateVersionID uuid.UUID  `json:"template_version_id" validate:"required" format:"uuid"`
+	Percent           int32      `json:"percent" validate:"min=0,max=100"`
+	GroupID           *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+}
+
+// TemplateVersionRollout returns the rollout of a template.
+func (c *Client) TemplateVersionRollout(ctx context.Context, template uuid.UUID) (TemplateVersionRollout, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/rollout", template), nil)
+	if err != nil {
+		return TemplateVersionRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateVersionRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
+
+// UpdateTemplateVersionRollout starts or updates the rollout of a template.
+func (c *Client) UpdateTemplateVersionRollout(ctx context.Context, template uuid.UUID, req UpdateTemplateVersionRolloutRequest) (TemplateVersionRollout, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/rollout", template), req)
+	if err != nil {
+		return TemplateVersionRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateVersionRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
+
+// DeleteTemplateVersionRollout ends the rollout of a template without promoting
+// its version.
+func (c *Client) DeleteTemplateVersionRollout(ctx context.Context, template uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/rollout", template), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
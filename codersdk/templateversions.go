@@ -187,8 +187,95 @@ func (c *Client) TemplateVersionLogsAfter(ctx context.Context, version uuid.UUID
 	return c.provisionerJobLogsAfter(ctx, fmt.Sprintf("/api/v2/templateversions/%s/logs", version), after)
 }
 
+// TemplateVersionLogs returns the current logs of a template version matching
+// the filter.
+func (c *Client) TemplateVersionLogs(ctx context.Context, version uuid.UUID, filter ProvisionerJobLogsFilter) ([]ProvisionerJobLog, error) {
+	return c.provisionerJobLogs(ctx, fmt.Sprintf("/api/v2/templateversions/%s/logs", version), filter)
+}
+
 // CreateTemplateVersionDryRunRequest defines the request parameters for
 // CreateTemplateVersionDryRun.
+// TemplateVersionDiffChange describes how an item differs between two template
+// versions.
+type TemplateVersionDiffChange string
+
+const (
+	TemplateVersionDiffChangeAdded    TemplateVersionDiffChange = "added"
+	TemplateVersionDiffChangeRemoved  TemplateVersionDiffChange = "removed"
+	TemplateVersionDiffChangeModified TemplateVersionDiffChange = "modified"
+)
+
+// TemplateVersionDiff describes how the planned resources, parameters and
+// files of a template version differ from a base version, so admins can review
+// what promoting the version will change. Unchanged items are omitted.
+type TemplateVersionDiff struct {
+	TemplateVersionID     uuid.UUID                      `json:"template_version_id" format:"uuid"`
+	BaseTemplateVersionID uuid.UUID                      `json:"base_template_version_id" format:"uuid"`
+	Resources             []TemplateVersionResourceDiff  `json:"resources"`
+	Parameters            []TemplateVersionParameterDiff `json:"parameters"`
+	Files                 []TemplateVersionFileDiff      `json:"files"`
+}
+
+// TemplateVersionResourceDiff is a resource that differs between two template
+// versions. Resources are matched by transition, module path, type and name.
+type TemplateVersionResourceDiff struct {
+	Transition WorkspaceTransition       `json:"workspace_transition" enums:"start,stop,delete"`
+	ModulePath string                    `json:"module_path,omitempty"`
+	Type       string                    `json:"type"`
+	Name       string                    `json:"name"`
+	Change     TemplateVersionDiffChange `json:"change" enums:"added,removed,modified"`
+	// ChangedFields lists the fields of a modified resource that differ, e.g.
+	// "instance_type" or "agents".
+	ChangedFields []string `json:"changed_fields,omitempty"`
+}
+
+// TemplateVersionParameterDiff is a parameter that differs between two
+// template versions. Parameters are matched by name.
+type TemplateVersionParameterDiff struct {
+	Name   string                    `json:"name"`
+	Change TemplateVersionDiffChange `json:"change" enums:"added,removed,modified"`
+	// ChangedFields lists the fields of a modified parameter that differ, e.g.
+	// "default_value" or "options".
+	ChangedFields []string `json:"changed_fields,omitempty"`
+	// Previous is the parameter in the base version. It is unset for added
+	// parameters.
+	Previous *TemplateVersionParameter `json:"previous,omitempty"`
+	// Current is the parameter in the compared version. It is unset for
+	// removed parameters.
+	Current *TemplateVersionParameter `json:"current,omitempty"`
+}
+
+// TemplateVersionFileDiff is a file in the source archive that differs between
+// two template versions. Files are matched by path.
+type TemplateVersionFileDiff struct {
+	Path   string                    `json:"path"`
+	Change TemplateVersionDiffChange `json:"change" enums:"added,removed,modified"`
+	// Additions and Deletions are the number of added and deleted lines. They
+	// are unset for binary files and files that are too large to compare.
+	Additions *int `json:"additions,omitempty"`
+	Deletions *int `json:"deletions,omitempty"`
+}
+
+// TemplateVersionDiff returns how the resources and parameters of a template
+// version differ from the base version. If base is uuid.Nil, the active version
+// of the template is used.
+func (c *Client) TemplateVersionDiff(ctx context.Context, version, base uuid.UUID) (TemplateVersionDiff, error) {
+	var opts []RequestOption
+	if base != uuid.Nil {
+		opts = append(opts, WithQueryParam("base", base.String()))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/diff", version), nil, opts...)
+	if err != nil {
+		return TemplateVersionDiff{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionDiff{}, ReadBodyAsError(res)
+	}
+	var diff TemplateVersionDiff
+	return diff, json.NewDecoder(res.Body).Decode(&diff)
+}
+
 type CreateTemplateVersionDryRunRequest struct {
 	WorkspaceName       string                    `json:"workspace_name"`
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values"`
@@ -278,6 +365,30 @@ func (c *Client) CancelTemplateVersionDryRun(ctx context.Context, version, job u
 	return nil
 }
 
+// CreateTemplateVersionTestRequest defines the request parameters for
+// CreateTemplateVersionTest.
+type CreateTemplateVersionTestRequest struct {
+	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
+}
+
+// CreateTemplateVersionTest begins a provisioner job that tests the given
+// template version end-to-end: a workspace with synthetic owner data is built
+// and destroyed again. The test is a dry-run job, so its status, logs and
+// resources are fetched like those of a dry-run.
+func (c *Client) CreateTemplateVersionTest(ctx context.Context, version uuid.UUID, req CreateTemplateVersionTestRequest) (ProvisionerJob, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateversions/%s/test", version), req)
+	if err != nil {
+		return ProvisionerJob{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return ProvisionerJob{}, ReadBodyAsError(res)
+	}
+
+	var job ProvisionerJob
+	return job, json.NewDecoder(res.Body).Decode(&job)
+}
+
 func (c *Client) PreviousTemplateVersion(ctx context.Context, organization uuid.UUID, templateName, versionName string) (TemplateVersion, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/templates/%s/versions/%s/previous", organization, templateName, versionName), nil)
 	if err != nil {
@@ -279,6 +279,39 @@ type LoginWithPasswordResponse struct {
 	SessionToken string `json:"session_token" validate:"required"`
 }
 
+const (
+	// LoginDeviceAuthorizationPending is the detail of the error returned while
+	// a device code has not been approved yet. Clients should keep polling.
+	LoginDeviceAuthorizationPending = "authorization_pending"
+	// LoginDeviceExpiredToken is the detail of the error returned when a device
+	// code expired or was already used. Clients should request a new code.
+	LoginDeviceExpiredToken = "expired_token"
+)
+
+// LoginDeviceCodeResponse starts a device login. The user approves the login
+// by visiting the verification URI on a device with a browser and entering
+// the user code.
+type LoginDeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	// ExpiresIn is the number of seconds until the device code expires.
+	ExpiresIn int `json:"expires_in"`
+	// Interval is the number of seconds clients should wait between polls.
+	Interval int `json:"interval"`
+}
+
+// LoginDeviceTokenRequest exchanges an approved device code for a session token.
+type LoginDeviceTokenRequest struct {
+	DeviceCode string `json:"device_code" validate:"required"`
+}
+
+// AuthorizeLoginDeviceRequest approves a device login for the authenticated user.
+type AuthorizeLoginDeviceRequest struct {
+	UserCode string `json:"user_code" validate:"required"`
+}
+
 // RequestOneTimePasscodeRequest enables callers to request a one-time-passcode to change their password.
 type RequestOneTimePasscodeRequest struct {
 	Email string `json:"email" validate:"required,email" format:"email"`
@@ -631,6 +664,53 @@ func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordReq
 	return resp, nil
 }
 
+// LoginDeviceCode starts a device login for a machine that can't open a
+// browser. Poll LoginDeviceToken with the device code until the user approves
+// the login.
+func (c *Client) LoginDeviceCode(ctx context.Context) (LoginDeviceCodeResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/device", nil)
+	if err != nil {
+		return LoginDeviceCodeResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginDeviceCodeResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginDeviceCodeResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// LoginDeviceToken exchanges an approved device code for a session token.
+// While the login is not approved, the returned error has the detail
+// LoginDeviceAuthorizationPending.
+// Call `SetSessionToken()` to apply the newly acquired token to the client.
+func (c *Client) LoginDeviceToken(ctx context.Context, req LoginDeviceTokenRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/device/token", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// AuthorizeLoginDevice approves the device login with the given user code,
+// signing the device in as the authenticated user.
+func (c *Client) AuthorizeLoginDevice(ctx context.Context, req AuthorizeLoginDeviceRequest) error {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/device/authorize", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 func (c *Client) RequestOneTimePasscode(ctx context.Context, req RequestOneTimePasscodeRequest) error {
 	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/otp/request", req)
 	if err != nil {
@@ -0,0 +1,111 @@
+package codersdk
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk/wsjson"
+	"github.com/coder/websocket"
+)
+
+type WatchRequestType string
+
+const (
+	WatchRequestTypeSubscribe   WatchRequestType = "subscribe"
+	WatchRequestTypeUnsubscribe WatchRequestType = "unsubscribe"
+)
+
+// WatchRequest changes the workspaces a watch connection receives events for.
+type WatchRequest struct {
+	Type         WatchRequestType `json:"type" enums:"subscribe,unsubscribe"`
+	WorkspaceIDs []uuid.UUID      `json:"workspace_ids" format:"uuid"`
+}
+
+type WatchEventType string
+
+const (
+	// WatchEventTypeWorkspace is sent when a workspace is subscribed to, and
+	// whenever it changes afterwards.
+	WatchEventTypeWorkspace WatchEventType = "workspace"
+	// WatchEventTypeWorkspaceBuild is sent when the status of the latest build
+	// of a workspace changes.
+	WatchEventTypeWorkspaceBuild WatchEventType = "workspace_build"
+	// WatchEventTypeWorkspaceAgent is sent when the lifecycle, connection or
+	// health of an agent in the latest build of a workspace changes.
+	WatchEventTypeWorkspaceAgent WatchEventType = "workspace_agent"
+	// WatchEventTypeError is sent when a workspace can't be watched, for
+	// example because it doesn't exist. The workspace is unsubscribed.
+	WatchEventTypeError WatchEventType = "error"
+)
+
+// WatchEvent is an update for a subscribed workspace. Only the field that
+// matches the type of the event is set.
+type WatchEvent struct {
+	Type           WatchEventType  `json:"type" enums:"workspace,workspace_build,workspace_agent,error"`
+	WorkspaceID    uuid.UUID       `json:"workspace_id" format:"uuid"`
+	Workspace      *Workspace      `json:"workspace,omitempty"`
+	WorkspaceBuild *WorkspaceBuild `json:"workspace_build,omitempty"`
+	WorkspaceAgent *WorkspaceAgent `json:"workspace_agent,omitempty"`
+	Error          *Response       `json:"error,omitempty"`
+}
+
+// Watcher receives workspace, build and agent updates for any number of
+// workspaces over a single connection.
+// @typescript-ignore Watcher
+type Watcher struct {
+	stream *wsjson.Stream[WatchEvent, WatchRequest]
+	events <-chan WatchEvent
+}
+
+// Watch opens a connection that receives updates for the workspaces that are
+// subscribed to with Subscribe. The Watcher must be closed when it's no longer
+// needed.
+func (c *Client) Watch(ctx context.Context) (*Watcher, error) {
+	conn, err := c.Dial(ctx, "/api/v2/workspaces/watch", nil)
+	if err != nil {
+		return nil, err
+	}
+	stream := wsjson.NewStream[WatchEvent, WatchRequest](conn, websocket.MessageText, websocket.MessageText, c.Logger())
+	return &Watcher{
+		stream: stream,
+		events: stream.Chan(),
+	}, nil
+}
+
+// Events returns the updates for subscribed workspaces. The channel is closed
+// when the connection is closed.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Subscribe starts sending events for the given workspaces. An event with the
+// current state of each workspace is sent first.
+func (w *Watcher) Subscribe(workspaceIDs ...uuid.UUID) error {
+	err := w.stream.Send(WatchRequest{
+		Type:         WatchRequestTypeSubscribe,
+		WorkspaceIDs: workspaceIDs,
+	})
+	if err != nil {
+		return xerrors.Errorf("subscribe: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe stops sending events for the given workspaces.
+func (w *Watcher) Unsubscribe(workspaceIDs ...uuid.UUID) error {
+	err := w.stream.Send(WatchRequest{
+		Type:         WatchRequestTypeUnsubscribe,
+		WorkspaceIDs: workspaceIDs,
+	})
+	if err != nil {
+		return xerrors.Errorf("unsubscribe: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (w *Watcher) Close() error {
+	return w.stream.Close(websocket.StatusNormalClosure)
+}
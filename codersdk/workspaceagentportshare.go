@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -15,6 +16,9 @@ const (
 	WorkspaceAgentPortShareLevelAuthenticated WorkspaceAgentPortShareLevel = "authenticated"
 	WorkspaceAgentPortShareLevelOrganization  WorkspaceAgentPortShareLevel = "organization"
 	WorkspaceAgentPortShareLevelPublic        WorkspaceAgentPortShareLevel = "public"
+	// WorkspaceAgentPortShareLevelRestricted shares the port only with the
+	// users and groups listed on the share.
+	WorkspaceAgentPortShareLevelRestricted WorkspaceAgentPortShareLevel = "restricted"
 
 	WorkspaceAgentPortShareProtocolHTTP  WorkspaceAgentPortShareProtocol = "http"
 	WorkspaceAgentPortShareProtocolHTTPS WorkspaceAgentPortShareProtocol = "https"
@@ -26,8 +30,16 @@ type (
 	UpsertWorkspaceAgentPortShareRequest struct {
 		AgentName  string                          `json:"agent_name"`
 		Port       int32                           `json:"port"`
-		ShareLevel WorkspaceAgentPortShareLevel    `json:"share_level" enums:"owner,authenticated,organization,public"`
+		ShareLevel WorkspaceAgentPortShareLevel    `json:"share_level" enums:"owner,authenticated,organization,public,restricted"`
 		Protocol   WorkspaceAgentPortShareProtocol `json:"protocol" enums:"http,https"`
+		// SharedWithUserIDs and SharedWithGroupIDs list who can access the
+		// port when the share level is "restricted". They must be empty for
+		// every other share level.
+		SharedWithUserIDs  []uuid.UUID `json:"shared_with_user_ids,omitempty" format:"uuid"`
+		SharedWithGroupIDs []uuid.UUID `json:"shared_with_group_ids,omitempty" format:"uuid"`
+		// ExpiresAt optionally stops the share from being honored after the
+		// given time.
+		ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
 	}
 	WorkspaceAgentPortShares struct {
 		Shares []WorkspaceAgentPortShare `json:"shares"`
@@ -36,8 +48,13 @@ type (
 		WorkspaceID uuid.UUID                       `json:"workspace_id" format:"uuid"`
 		AgentName   string                          `json:"agent_name"`
 		Port        int32                           `json:"port"`
-		ShareLevel  WorkspaceAgentPortShareLevel    `json:"share_level" enums:"owner,authenticated,organization,public"`
+		ShareLevel  WorkspaceAgentPortShareLevel    `json:"share_level" enums:"owner,authenticated,organization,public,restricted"`
 		Protocol    WorkspaceAgentPortShareProtocol `json:"protocol" enums:"http,https"`
+		// SharedWithUserIDs and SharedWithGroupIDs are only populated for the
+		// "restricted" share level.
+		SharedWithUserIDs  []uuid.UUID `json:"shared_with_user_ids" format:"uuid"`
+		SharedWithGroupIDs []uuid.UUID `json:"shared_with_group_ids" format:"uuid"`
+		ExpiresAt          *time.Time  `json:"expires_at,omitempty" format:"date-time"`
 	}
 	DeleteWorkspaceAgentPortShareRequest struct {
 		AgentName string `json:"agent_name"`
@@ -49,13 +66,15 @@ func (l WorkspaceAgentPortShareLevel) ValidMaxLevel() bool {
 	return l == WorkspaceAgentPortShareLevelOwner ||
 		l == WorkspaceAgentPortShareLevelAuthenticated ||
 		l == WorkspaceAgentPortShareLevelOrganization ||
-		l == WorkspaceAgentPortShareLevelPublic
+		l == WorkspaceAgentPortShareLevelPublic ||
+		l == WorkspaceAgentPortShareLevelRestricted
 }
 
 func (l WorkspaceAgentPortShareLevel) ValidPortShareLevel() bool {
 	return l == WorkspaceAgentPortShareLevelAuthenticated ||
 		l == WorkspaceAgentPortShareLevelOrganization ||
-		l == WorkspaceAgentPortShareLevelPublic
+		l == WorkspaceAgentPortShareLevelPublic ||
+		l == WorkspaceAgentPortShareLevelRestricted
 }
 
 // IsCompatibleWithMaxLevel determines whether the sharing level is valid under
@@ -64,7 +83,8 @@ func (l WorkspaceAgentPortShareLevel) ValidPortShareLevel() bool {
 // 1. Public
 // 2. Authenticated
 // 3. Organization
-// 4. Owner
+// 4. Restricted
+// 5. Owner
 // Returns an error if either level is invalid.
 func (l WorkspaceAgentPortShareLevel) IsCompatibleWithMaxLevel(maxLevel WorkspaceAgentPortShareLevel) error {
 	// Owner is always allowed.
@@ -97,6 +117,15 @@ func (l WorkspaceAgentPortShareLevel) IsCompatibleWithMaxLevel(maxLevel Workspac
 	if l == WorkspaceAgentPortShareLevelOrganization {
 		return xerrors.Errorf("%q sharing level is not allowed under max level %q", l, maxLevel)
 	}
+	// If restricted is allowed, every broader level has already been filtered
+	// out so anything is allowed.
+	if maxLevel == WorkspaceAgentPortShareLevelRestricted {
+		return nil
+	}
+	// Restricted is not allowed.
+	if l == WorkspaceAgentPortShareLevelRestricted {
+		return xerrors.Errorf("%q sharing level is not allowed under max level %q", l, maxLevel)
+	}
 
 	// An invalid value was provided.
 	return xerrors.New("port sharing level is invalid.")
@@ -171,6 +171,11 @@ type WorkspaceAgent struct {
 	DisplayApps              []DisplayApp              `json:"display_apps"`
 	LogSources               []WorkspaceAgentLogSource `json:"log_sources"`
 	Scripts                  []WorkspaceAgentScript    `json:"scripts"`
+	// DisplayGroup is the name of the group the agent is displayed under.
+	// Agents without a group are displayed ungrouped.
+	DisplayGroup string `json:"display_group,omitempty"`
+	// Collapsed hints that the agent should be collapsed by default.
+	Collapsed bool `json:"collapsed"`
 
 	// StartupScriptBehavior is a legacy field that is deprecated in favor
 	// of the `coder_script` resource. It's only referenced by old clients.
@@ -194,6 +199,7 @@ type WorkspaceAgentScript struct {
 	Cron             string        `json:"cron"`
 	RunOnStart       bool          `json:"run_on_start"`
 	RunOnStop        bool          `json:"run_on_stop"`
+	RunOnClaim       bool          `json:"run_on_claim"`
 	StartBlocksLogin bool          `json:"start_blocks_login"`
 	Timeout          time.Duration `json:"timeout"`
 	DisplayName      string        `json:"display_name"`
@@ -393,6 +399,45 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentConnectionQuality is the network quality of a peer connected
+// to a workspace agent, such as an SSH client, as measured by the agent.
+// Every client connection uses its own IP address.
+type WorkspaceAgentConnectionQuality struct {
+	IP string `json:"ip"`
+	// P2P is true if the connection is direct, and false if it is relayed
+	// through DERP.
+	P2P bool `json:"p2p"`
+	// DERPRegionCode is the DERP region the connection is relayed through.
+	DERPRegionCode string `json:"derp_region_code,omitempty"`
+	// LatencyMS is the round trip time of the most recent successful ping.
+	// It is nil if no ping was answered.
+	LatencyMS *float64 `json:"latency_ms,omitempty"`
+	// PingsSent and PingsLost count the pings the agent sent to the peer
+	// since it connected, and how many of them went unanswered.
+	PingsSent int64 `json:"pings_sent"`
+	PingsLost int64 `json:"pings_lost"`
+	// PacketLoss is the fraction of pings that went unanswered, from 0 to 1.
+	PacketLoss      float64    `json:"packet_loss"`
+	LastHandshakeAt *time.Time `json:"last_handshake_at,omitempty" format:"date-time"`
+	CreatedAt       time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt       time.Time  `json:"updated_at" format:"date-time"`
+}
+
+// WorkspaceAgentConnectionQuality returns the network quality of the peers
+// connected to the workspace agent, most recently reported first.
+func (c *Client) WorkspaceAgentConnectionQuality(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentConnectionQuality, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/connection-quality", agentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var qualities []WorkspaceAgentConnectionQuality
+	return qualities, json.NewDecoder(res.Body).Decode(&qualities)
+}
+
 // WorkspaceAgentDevcontainerStatus is the status of a devcontainer.
 type WorkspaceAgentDevcontainerStatus string
 
@@ -450,6 +495,9 @@ type WorkspaceAgentContainer struct {
 	// implementation-dependent, but should generally be a human-readable
 	// string.
 	Status string `json:"status"`
+	// Health is the result of the health check of the container. It is
+	// empty if the container has no health check.
+	Health WorkspaceAgentContainerHealth `json:"health,omitempty" enums:"starting,healthy,unhealthy"`
 	// Volumes is a map of "things" mounted into the container. Again, this
 	// is somewhat implementation-dependent.
 	Volumes map[string]string `json:"volumes"`
@@ -465,6 +513,16 @@ func (c *WorkspaceAgentContainer) Match(idOrName string) bool {
 	return false
 }
 
+// WorkspaceAgentContainerHealth is the result of the health check of a
+// container.
+type WorkspaceAgentContainerHealth string
+
+const (
+	WorkspaceAgentContainerHealthStarting  WorkspaceAgentContainerHealth = "starting"
+	WorkspaceAgentContainerHealthHealthy   WorkspaceAgentContainerHealth = "healthy"
+	WorkspaceAgentContainerHealthUnhealthy WorkspaceAgentContainerHealth = "unhealthy"
+)
+
 // WorkspaceAgentContainerPort describes a port as exposed by a container.
 type WorkspaceAgentContainerPort struct {
 	// Port is the port number *inside* the container.
@@ -535,6 +593,41 @@ func (c *Client) WorkspaceAgentRecreateDevcontainer(ctx context.Context, agentID
 	return m, nil
 }
 
+// WorkspaceAgentExecRequest runs a non-interactive command in a workspace
+// agent.
+type WorkspaceAgentExecRequest struct {
+	// Command is run by the agent using the user's shell.
+	Command string `json:"command" validate:"required"`
+	// TimeoutMillis is how long the command may run before it is killed.
+	// Defaults to one minute.
+	TimeoutMillis int64 `json:"timeout_ms,omitempty"`
+}
+
+// WorkspaceAgentExecResponse is the outcome of a command run in a workspace
+// agent. Stdout and Stderr are truncated if the command produces more output
+// than the server retains.
+type WorkspaceAgentExecResponse struct {
+	ExitCode int    `json:"exit_code"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	TimedOut bool   `json:"timed_out"`
+}
+
+// WorkspaceAgentExec runs a command in the workspace agent and waits for it
+// to exit.
+func (c *Client) WorkspaceAgentExec(ctx context.Context, agentID uuid.UUID, req WorkspaceAgentExecRequest) (WorkspaceAgentExecResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/exec", agentID), req)
+	if err != nil {
+		return WorkspaceAgentExecResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentExecResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentExecResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 //nolint:revive // Follow is a control flag on the server as well.
 func (c *Client) WorkspaceAgentLogsAfter(ctx context.Context, agentID uuid.UUID, after int64, follow bool) (<-chan []WorkspaceAgentLog, io.Closer, error) {
 	var queryParams []string
@@ -0,0 +1,90 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type QueuedWorkspaceBuildStatus string
+
+const (
+	// QueuedWorkspaceBuildStatusPending builds start once no build of the
+	// workspace is active and all builds queued before them have started.
+	QueuedWorkspaceBuildStatusPending QueuedWorkspaceBuildStatus = "pending"
+	// QueuedWorkspaceBuildStatusFailed builds could not be started, e.g.
+	// because their parameter values were no longer valid.
+	QueuedWorkspaceBuildStatusFailed QueuedWorkspaceBuildStatus = "failed"
+)
+
+// QueuedWorkspaceBuild is a workspace build that was requested while another
+// build of the workspace was active.
+type QueuedWorkspaceBuild struct {
+	ID          uuid.UUID           `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID           `json:"workspace_id" format:"uuid"`
+	InitiatorID uuid.UUID           `json:"initiator_id" format:"uuid"`
+	Transition  WorkspaceTransition `json:"transition" enums:"start,stop,delete"`
+	// TemplateVersionID is the template version to build. It is empty if the
+	// build uses the template version of the build that precedes it.
+	TemplateVersionID *uuid.UUID                 `json:"template_version_id,omitempty" format:"uuid"`
+	Status            QueuedWorkspaceBuildStatus `json:"status" enums:"pending,failed"`
+	// Position is the 1-based position of a pending build in the queue of the
+	// workspace. It is 0 for failed builds.
+	Position  int        `json:"position"`
+	Error     string     `json:"error,omitempty"`
+	CreatedAt time.Time  `json:"created_at" format:"date-time"`
+	FailedAt  *time.Time `json:"failed_at,omitempty" format:"date-time"`
+}
+
+// QueueWorkspaceBuild creates a workspace build, or queues it if another build
+// of the workspace is active. Exactly one of the returned build and queued
+// build is set.
+func (c *Client) QueueWorkspaceBuild(ctx context.Context, workspace uuid.UUID, request CreateWorkspaceBuildRequest) (*WorkspaceBuild, *QueuedWorkspaceBuild, error) {
+	request.Queue = true
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/builds", workspace), request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusCreated:
+		var build WorkspaceBuild
+		return &build, nil, json.NewDecoder(res.Body).Decode(&build)
+	case http.StatusAccepted:
+		var queued QueuedWorkspaceBuild
+		return nil, &queued, json.NewDecoder(res.Body).Decode(&queued)
+	default:
+		return nil, nil, ReadBodyAsError(res)
+	}
+}
+
+// WorkspaceBuildQueue returns the queued builds of a workspace, oldest first.
+func (c *Client) WorkspaceBuildQueue(ctx context.Context, workspace uuid.UUID) ([]QueuedWorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/builds/queue", workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var queued []QueuedWorkspaceBuild
+	return queued, json.NewDecoder(res.Body).Decode(&queued)
+}
+
+// CancelQueuedWorkspaceBuild removes a build from the queue of a workspace.
+func (c *Client) CancelQueuedWorkspaceBuild(ctx context.Context, workspace, queuedBuild uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/builds/queue/%s", workspace, queuedBuild), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
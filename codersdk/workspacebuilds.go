@@ -46,8 +46,75 @@ const (
 	// "autostop" is used when a build to stop a workspace is triggered by Autostop.
 	// The initiator id/username in this case is the workspace owner and can be ignored.
 	BuildReasonAutostop BuildReason = "autostop"
+	// "dormancy" is used when a build to stop a workspace is triggered by the
+	// workspace becoming dormant.
+	BuildReasonDormancy BuildReason = "dormancy"
+	// "failedstop" is used when a build to stop a workspace is triggered by a
+	// failed build that exceeded the template's failure TTL.
+	BuildReasonFailedStop BuildReason = "failedstop"
+	// "autodelete" is used when a build to delete a workspace is triggered by
+	// a dormant workspace exceeding the template's dormancy auto-delete TTL.
+	BuildReasonAutodelete BuildReason = "autodelete"
 )
 
+// BuildAutomation identifies an automated subsystem that initiates
+// workspace builds.
+type BuildAutomation string
+
+const (
+	// BuildAutomationLifecycleExecutor enforces workspace schedules,
+	// dormancy, and auto-deletion.
+	BuildAutomationLifecycleExecutor BuildAutomation = "lifecycle_executor"
+	// BuildAutomationPrebuilds maintains the pool of prebuilt workspaces.
+	BuildAutomationPrebuilds BuildAutomation = "prebuilds"
+	// BuildAutomationBuildQueue starts builds that were queued while another
+	// build of the workspace was active.
+	BuildAutomationBuildQueue BuildAutomation = "build_queue"
+	// BuildAutomationWorkspaceMigration stops and deletes workspaces that are
+	// migrated to another deployment.
+	BuildAutomationWorkspaceMigration BuildAutomation = "workspace_migration"
+)
+
+// WorkspaceBuildInitiatorContext carries structured context about what
+// initiated a workspace build. It complements Reason, which only
+// describes the category of the initiator.
+type WorkspaceBuildInitiatorContext struct {
+	// APIKeyName is the name of the API token the build was requested with.
+	// It is empty for builds requested with a browser session.
+	APIKeyName string `json:"api_key_name,omitempty"`
+	// Automation identifies the automated subsystem that initiated the
+	// build, if any.
+	Automation BuildAutomation `json:"automation,omitempty" enums:"lifecycle_executor,prebuilds,build_queue,workspace_migration"`
+	// Schedule is the autostart schedule that triggered the build.
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// WorkspaceBuildWarningCode identifies the kind of a workspace build warning.
+type WorkspaceBuildWarningCode string
+
+const (
+	// WorkspaceBuildWarningInactiveTemplateVersion is recorded when a build
+	// starts a workspace with a template version that is not the active
+	// version of its template.
+	WorkspaceBuildWarningInactiveTemplateVersion WorkspaceBuildWarningCode = "inactive_template_version"
+	// WorkspaceBuildWarningDeprecatedTemplate is recorded when a build starts
+	// a workspace of a deprecated template.
+	WorkspaceBuildWarningDeprecatedTemplate WorkspaceBuildWarningCode = "deprecated_template"
+)
+
+// WorkspaceBuildWarning is recorded on a build that starts a workspace with an
+// outdated template version or a deprecated template, to suggest updating the
+// workspace.
+type WorkspaceBuildWarning struct {
+	Code    WorkspaceBuildWarningCode `json:"code" enums:"inactive_template_version,deprecated_template"`
+	Message string                    `json:"message"`
+	// VersionsBehind lists the names of the template versions created after
+	// the version of the build, oldest first, up to and including the active
+	// version. It is empty if the version of the build is newer than the
+	// active version.
+	VersionsBehind []string `json:"versions_behind,omitempty"`
+}
+
 // WorkspaceBuild is an at-point representation of a workspace state.
 // BuildNumbers start at 1 and increase by 1 for each subsequent build
 type WorkspaceBuild struct {
@@ -58,25 +125,40 @@ type WorkspaceBuild struct {
 	WorkspaceName    string    `json:"workspace_name"`
 	WorkspaceOwnerID uuid.UUID `json:"workspace_owner_id" format:"uuid"`
 	// WorkspaceOwnerName is the username of the owner of the workspace.
-	WorkspaceOwnerName      string               `json:"workspace_owner_name"`
-	WorkspaceOwnerAvatarURL string               `json:"workspace_owner_avatar_url,omitempty"`
-	TemplateVersionID       uuid.UUID            `json:"template_version_id" format:"uuid"`
-	TemplateVersionName     string               `json:"template_version_name"`
-	BuildNumber             int32                `json:"build_number"`
-	Transition              WorkspaceTransition  `json:"transition" enums:"start,stop,delete"`
-	InitiatorID             uuid.UUID            `json:"initiator_id" format:"uuid"`
-	InitiatorUsername       string               `json:"initiator_name"`
-	Job                     ProvisionerJob       `json:"job"`
-	Reason                  BuildReason          `db:"reason" json:"reason" enums:"initiator,autostart,autostop"`
-	Resources               []WorkspaceResource  `json:"resources"`
-	Deadline                NullTime             `json:"deadline,omitempty" format:"date-time"`
-	MaxDeadline             NullTime             `json:"max_deadline,omitempty" format:"date-time"`
-	Status                  WorkspaceStatus      `json:"status" enums:"pending,starting,running,stopping,stopped,failed,canceling,canceled,deleting,deleted"`
-	DailyCost               int32                `json:"daily_cost"`
-	MatchedProvisioners     *MatchedProvisioners `json:"matched_provisioners,omitempty"`
-	TemplateVersionPresetID *uuid.UUID           `json:"template_version_preset_id" format:"uuid"`
-	HasAITask               *bool                `json:"has_ai_task,omitempty"`
-	AITaskSidebarAppID      *uuid.UUID           `json:"ai_task_sidebar_app_id,omitempty" format:"uuid"`
+	WorkspaceOwnerName      string              `json:"workspace_owner_name"`
+	WorkspaceOwnerAvatarURL string              `json:"workspace_owner_avatar_url,omitempty"`
+	TemplateVersionID       uuid.UUID           `json:"template_version_id" format:"uuid"`
+	TemplateVersionName     string              `json:"template_version_name"`
+	BuildNumber             int32               `json:"build_number"`
+	Transition              WorkspaceTransition `json:"transition" enums:"start,stop,delete"`
+	InitiatorID             uuid.UUID           `json:"initiator_id" format:"uuid"`
+	InitiatorUsername       string              `json:"initiator_name"`
+	Job                     ProvisionerJob      `json:"job"`
+	Reason                  BuildReason         `db:"reason" json:"reason" enums:"initiator,autostart,autostop,dormancy,failedstop,autodelete"`
+	// InitiatorContext carries structured context about what initiated the
+	// build, in addition to Reason.
+	InitiatorContext        WorkspaceBuildInitiatorContext `json:"initiator_context"`
+	Resources               []WorkspaceResource            `json:"resources"`
+	Deadline                NullTime                       `json:"deadline,omitempty" format:"date-time"`
+	MaxDeadline             NullTime                       `json:"max_deadline,omitempty" format:"date-time"`
+	Status                  WorkspaceStatus                `json:"status" enums:"pending,starting,running,stopping,stopped,failed,canceling,canceled,deleting,deleted"`
+	DailyCost               int32                          `json:"daily_cost"`
+	MatchedProvisioners     *MatchedProvisioners           `json:"matched_provisioners,omitempty"`
+	TemplateVersionPresetID *uuid.UUID                     `json:"template_version_preset_id" format:"uuid"`
+	HasAITask               *bool                          `json:"has_ai_task,omitempty"`
+	AITaskSidebarAppID      *uuid.UUID                     `json:"ai_task_sidebar_app_id,omitempty" format:"uuid"`
+	// ExceedsTemplateP95 is true if the build succeeded and took longer than
+	// the 95th percentile of the builds of its template and transition over
+	// the last 30 days.
+	ExceedsTemplateP95 bool `json:"exceeds_template_p95,omitempty"`
+	// Warnings are recorded when the build is created, and suggest updating
+	// the workspace.
+	Warnings []WorkspaceBuildWarning `json:"warnings,omitempty"`
+	// TraceID is the ID of the trace following the build from its creation
+	// through job acquisition and provisioning to the first connection of its
+	// agents. It is only set when tracing is enabled, and can be shared with
+	// support to correlate the build with its spans.
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // WorkspaceResource describes resources used to create a workspace, for instance:
@@ -93,6 +175,14 @@ type WorkspaceResource struct {
 	Agents     []WorkspaceAgent            `json:"agents,omitempty"`
 	Metadata   []WorkspaceResourceMetadata `json:"metadata,omitempty"`
 	DailyCost  int32                       `json:"daily_cost"`
+	// DisplayGroup is the name of the group the resource is displayed
+	// under. Resources without a group are displayed ungrouped.
+	DisplayGroup string `json:"display_group,omitempty"`
+	// DisplayOrder specifies the order in which to display the resource.
+	// Resources with a lower order are displayed first.
+	DisplayOrder int32 `json:"display_order"`
+	// Collapsed hints that the resource should be collapsed by default.
+	Collapsed bool `json:"collapsed"`
 }
 
 // WorkspaceResourceMetadata annotates the workspace resource with custom key-value pairs.
@@ -141,6 +231,17 @@ func (c *Client) WorkspaceBuildLogsAfter(ctx context.Context, build uuid.UUID, a
 	return c.provisionerJobLogsAfter(ctx, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs", build), after)
 }
 
+// WorkspaceBuildLogsAfterFiltered streams logs of a workspace build matching
+// the filter that occurred after a specific log ID.
+func (c *Client) WorkspaceBuildLogsAfterFiltered(ctx context.Context, build uuid.UUID, after int64, filter ProvisionerJobLogsFilter) (<-chan ProvisionerJobLog, io.Closer, error) {
+	return c.provisionerJobLogsAfterFiltered(ctx, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs", build), after, filter)
+}
+
+// WorkspaceBuildLogs returns the current logs of a build matching the filter.
+func (c *Client) WorkspaceBuildLogs(ctx context.Context, build uuid.UUID, filter ProvisionerJobLogsFilter) ([]ProvisionerJobLog, error) {
+	return c.provisionerJobLogs(ctx, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs", build), filter)
+}
+
 // WorkspaceBuildState returns the provisioner state of the build.
 func (c *Client) WorkspaceBuildState(ctx context.Context, build uuid.UUID) ([]byte, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/state", build), nil)
@@ -246,3 +347,43 @@ func (c *Client) WorkspaceBuildTimings(ctx context.Context, build uuid.UUID) (Wo
 	var timings WorkspaceBuildTimings
 	return timings, json.NewDecoder(res.Body).Decode(&timings)
 }
+
+// WorkspaceBuildArchive describes a workspace build whose provisioner state,
+// resource metadata and logs were moved out of the database into a
+// compressed file.
+type WorkspaceBuildArchive struct {
+	WorkspaceBuildID      uuid.UUID `json:"workspace_build_id" format:"uuid"`
+	FileID                uuid.UUID `json:"file_id" format:"uuid"`
+	StateSize             int32     `json:"state_size"`
+	ResourceMetadataCount int32     `json:"resource_metadata_count"`
+	ArchivedAt            time.Time `json:"archived_at" format:"date-time"`
+}
+
+// WorkspaceBuildArchive returns the archive of a workspace build. It fails
+// with a 404 if the build is not archived.
+func (c *Client) WorkspaceBuildArchive(ctx context.Context, build uuid.UUID) (WorkspaceBuildArchive, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/archive", build), nil)
+	if err != nil {
+		return WorkspaceBuildArchive{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildArchive{}, ReadBodyAsError(res)
+	}
+	var archive WorkspaceBuildArchive
+	return archive, json.NewDecoder(res.Body).Decode(&archive)
+}
+
+// RehydrateWorkspaceBuild restores the provisioner state and resource
+// metadata of an archived workspace build into the database.
+func (c *Client) RehydrateWorkspaceBuild(ctx context.Context, build uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspacebuilds/%s/rehydrate", build), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceBundleVersion is the version of the workspace bundle format
+// produced by this release. Bundles of other versions can't be imported.
+const WorkspaceBundleVersion = 1
+
+// WorkspaceBundle is a portable definition of a workspace. It references the
+// template by organization and name instead of by ID so that it can be
+// imported into another deployment or organization.
+type WorkspaceBundle struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at" format:"date-time"`
+	Name       string    `json:"name"`
+	// OrganizationName is the organization of the template the workspace was
+	// exported from.
+	OrganizationName string `json:"organization_name"`
+	TemplateName     string `json:"template_name"`
+	// TemplateVersionID and TemplateVersionName identify the template version
+	// of the latest build of the workspace. The version is looked up by ID,
+	// then by name, when the bundle is imported.
+	TemplateVersionID   uuid.UUID `json:"template_version_id" format:"uuid"`
+	TemplateVersionName string    `json:"template_version_name"`
+	// RichParameterValues are the values of the latest build of the
+	// workspace. Ephemeral parameters are not exported.
+	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values"`
+	AutostartSchedule   *string                   `json:"autostart_schedule,omitempty"`
+	TTLMillis           *int64                    `json:"ttl_ms,omitempty"`
+	AutomaticUpdates    AutomaticUpdates          `json:"automatic_updates" enums:"always,never"`
+	// Resources describe the resources of the latest build of the workspace.
+	// They are informational and are provisioned from the template when the
+	// bundle is imported.
+	Resources []WorkspaceBundleResource `json:"resources"`
+}
+
+// WorkspaceBundleResource is a resource of an exported workspace. Sensitive
+// metadata is not exported.
+type WorkspaceBundleResource struct {
+	Type     string                      `json:"type"`
+	Name     string                      `json:"name"`
+	Metadata []WorkspaceResourceMetadata `json:"metadata,omitempty"`
+	Agents   []WorkspaceBundleAgent      `json:"agents,omitempty"`
+}
+
+// WorkspaceBundleAgent holds the settings of an agent of an exported
+// workspace.
+type WorkspaceBundleAgent struct {
+	Name            string       `json:"name"`
+	OperatingSystem string       `json:"operating_system"`
+	Architecture    string       `json:"architecture"`
+	Directory       string       `json:"directory,omitempty"`
+	DisplayApps     []DisplayApp `json:"display_apps"`
+	// Apps are the slugs of the apps of the agent.
+	Apps []string `json:"apps,omitempty"`
+}
+
+// ImportWorkspaceRequest creates a workspace from a workspace bundle.
+type ImportWorkspaceRequest struct {
+	Bundle WorkspaceBundle `json:"bundle"`
+	// Name overrides the name of the workspace in the bundle.
+	Name string `json:"name,omitempty" validate:"omitempty,workspace_name"`
+	// TemplateID overrides the template referenced by the bundle. It must
+	// belong to the organization the workspace is imported into.
+	TemplateID uuid.UUID `json:"template_id,omitempty" format:"uuid"`
+	// ProvisionerState is the provisioner state to start the workspace from,
+	// for workspaces whose resources are kept when they are moved between
+	// deployments. Setting it requires permission to update the template.
+	ProvisionerState []byte `json:"state,omitempty"`
+}
+
+// ExportWorkspace returns a bundle of the definition of a workspace that can
+// be imported with ImportWorkspace.
+func (c *Client) ExportWorkspace(ctx context.Context, workspace uuid.UUID) (WorkspaceBundle, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/export", workspace), nil)
+	if err != nil {
+		return WorkspaceBundle{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBundle{}, ReadBodyAsError(res)
+	}
+	var bundle WorkspaceBundle
+	return bundle, json.NewDecoder(res.Body).Decode(&bundle)
+}
+
+// ImportWorkspace creates a workspace for the user in the organization from a
+// workspace bundle.
+func (c *Client) ImportWorkspace(ctx context.Context, organizationID uuid.UUID, user string, request ImportWorkspaceRequest) (Workspace, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/members/%s/workspaces/import", organizationID, user), request)
+	if err != nil {
+		return Workspace{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Workspace{}, ReadBodyAsError(res)
+	}
+	var workspace Workspace
+	return workspace, json.NewDecoder(res.Body).Decode(&workspace)
+}
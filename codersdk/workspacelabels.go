@@ -0,0 +1,53 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// MaxWorkspaceLabels is the maximum number of labels a workspace may have.
+const MaxWorkspaceLabels = 50
+
+// WorkspaceLabels are arbitrary key/value labels used to group workspaces.
+// Workspaces can be filtered by label with the `label:<key>[=<value>]` search
+// query.
+type WorkspaceLabels struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// UpdateWorkspaceLabelsRequest replaces the labels of a workspace. Keys must be
+// lowercase alphanumeric and may contain '.', '_', '/' and '-' in between.
+type UpdateWorkspaceLabelsRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// WorkspaceLabels returns the labels of a workspace.
+func (c *Client) WorkspaceLabels(ctx context.Context, workspace uuid.UUID) (WorkspaceLabels, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/labels", workspace), nil)
+	if err != nil {
+		return WorkspaceLabels{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceLabels{}, ReadBodyAsError(res)
+	}
+	var labels WorkspaceLabels
+	return labels, json.NewDecoder(res.Body).Decode(&labels)
+}
+
+// UpdateWorkspaceLabels replaces the labels of a workspace.
+func (c *Client) UpdateWorkspaceLabels(ctx context.Context, workspace uuid.UUID, req UpdateWorkspaceLabelsRequest) error {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/labels", workspace), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
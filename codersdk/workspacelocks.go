@@ -0,0 +1,76 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceLock prevents any new builds of a workspace, including automatic
+// ones such as autostart and autostop, until it is lifted. Lifted locks are
+// kept as a record of who locked the workspace and why.
+type WorkspaceLock struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	Reason      string    `json:"reason"`
+	LockedBy    uuid.UUID `json:"locked_by" format:"uuid"`
+	LockedAt    time.Time `json:"locked_at" format:"date-time"`
+	// UnlockedBy and UnlockedAt are set once the lock has been lifted.
+	UnlockedBy *uuid.UUID `json:"unlocked_by,omitempty" format:"uuid"`
+	UnlockedAt *time.Time `json:"unlocked_at,omitempty" format:"date-time"`
+}
+
+// LockWorkspaceRequest locks a workspace.
+type LockWorkspaceRequest struct {
+	// Reason is recorded with the lock and returned when builds of the
+	// workspace are rejected.
+	Reason string `json:"reason" validate:"required"`
+}
+
+// LockWorkspace prevents any new builds of a workspace until it is unlocked.
+// Builds that are already running are not canceled.
+func (c *Client) LockWorkspace(ctx context.Context, workspace uuid.UUID, req LockWorkspaceRequest) (WorkspaceLock, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/locks", workspace), req)
+	if err != nil {
+		return WorkspaceLock{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceLock{}, ReadBodyAsError(res)
+	}
+	var lock WorkspaceLock
+	return lock, json.NewDecoder(res.Body).Decode(&lock)
+}
+
+// UnlockWorkspace lifts the lock of a workspace and returns it.
+func (c *Client) UnlockWorkspace(ctx context.Context, workspace uuid.UUID) (WorkspaceLock, error) {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/locks", workspace), nil)
+	if err != nil {
+		return WorkspaceLock{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceLock{}, ReadBodyAsError(res)
+	}
+	var lock WorkspaceLock
+	return lock, json.NewDecoder(res.Body).Decode(&lock)
+}
+
+// WorkspaceLocks returns the current and lifted locks of a workspace, newest
+// first.
+func (c *Client) WorkspaceLocks(ctx context.Context, workspace uuid.UUID) ([]WorkspaceLock, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/locks", workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var locks []WorkspaceLock
+	return locks, json.NewDecoder(res.Body).Decode(&locks)
+}
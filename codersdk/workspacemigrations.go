@@ -0,0 +1,101 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceMigrationStatus is the progress of a workspace migration.
+type WorkspaceMigrationStatus string
+
+const (
+	// WorkspaceMigrationStatusPending migrations have not stopped the
+	// workspace yet.
+	WorkspaceMigrationStatusPending WorkspaceMigrationStatus = "pending"
+	// WorkspaceMigrationStatusStopping migrations wait for the workspace to
+	// stop before its provisioner state is transferred.
+	WorkspaceMigrationStatusStopping WorkspaceMigrationStatus = "stopping"
+	// WorkspaceMigrationStatusStarting migrations have imported the workspace
+	// on the destination and wait for it to start there.
+	WorkspaceMigrationStatusStarting  WorkspaceMigrationStatus = "starting"
+	WorkspaceMigrationStatusSucceeded WorkspaceMigrationStatus = "succeeded"
+	WorkspaceMigrationStatusFailed    WorkspaceMigrationStatus = "failed"
+)
+
+// WorkspaceMigration is a transfer of a workspace, including its provisioner
+// state, to another deployment. The workspace is stopped, imported on the
+// destination with its state and started there. Once it has started on the
+// destination, the workspace is deleted from this deployment without
+// destroying its resources.
+type WorkspaceMigration struct {
+	ID                        uuid.UUID                `json:"id" format:"uuid"`
+	WorkspaceID               uuid.UUID                `json:"workspace_id" format:"uuid"`
+	InitiatorID               uuid.UUID                `json:"initiator_id" format:"uuid"`
+	Status                    WorkspaceMigrationStatus `json:"status" enums:"pending,stopping,starting,succeeded,failed"`
+	DestinationURL            string                   `json:"destination_url"`
+	DestinationOrganizationID uuid.UUID                `json:"destination_organization_id" format:"uuid"`
+	// DestinationWorkspaceID is the ID of the workspace on the destination
+	// deployment, once it has been imported there.
+	DestinationWorkspaceID *uuid.UUID `json:"destination_workspace_id,omitempty" format:"uuid"`
+	Error                  string     `json:"error,omitempty"`
+	CreatedAt              time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt              time.Time  `json:"updated_at" format:"date-time"`
+	CompletedAt            *time.Time `json:"completed_at,omitempty" format:"date-time"`
+}
+
+// CreateWorkspaceMigrationRequest migrates a workspace to another deployment.
+type CreateWorkspaceMigrationRequest struct {
+	// DestinationURL is the access URL of the deployment to migrate the
+	// workspace to.
+	DestinationURL string `json:"destination_url" validate:"required,url"`
+	// SessionToken authenticates with the destination deployment. Its user
+	// must be able to update the template of the workspace on the
+	// destination, since the provisioner state of the workspace is imported.
+	// The token is discarded once the migration completes.
+	SessionToken   string    `json:"session_token" validate:"required"`
+	OrganizationID uuid.UUID `json:"organization_id" validate:"required" format:"uuid"`
+	// Owner is the username or ID of the owner of the workspace on the
+	// destination. It defaults to the user of the session token.
+	Owner string `json:"owner,omitempty"`
+	// TemplateID is the template on the destination. By default the template
+	// is looked up by the name of the template of the workspace.
+	TemplateID uuid.UUID `json:"template_id,omitempty" format:"uuid"`
+	// Name is the name of the workspace on the destination. It defaults to
+	// the name of the workspace.
+	Name string `json:"name,omitempty" validate:"omitempty,workspace_name"`
+}
+
+// CreateWorkspaceMigration starts migrating a workspace to another
+// deployment. The migration runs in the background, use WorkspaceMigrations
+// to follow its progress.
+func (c *Client) CreateWorkspaceMigration(ctx context.Context, workspace uuid.UUID, req CreateWorkspaceMigrationRequest) (WorkspaceMigration, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/migrations", workspace), req)
+	if err != nil {
+		return WorkspaceMigration{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceMigration{}, ReadBodyAsError(res)
+	}
+	var migration WorkspaceMigration
+	return migration, json.NewDecoder(res.Body).Decode(&migration)
+}
+
+// WorkspaceMigrations returns the migrations of a workspace, newest first.
+func (c *Client) WorkspaceMigrations(ctx context.Context, workspace uuid.UUID) ([]WorkspaceMigration, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/migrations", workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var migrations []WorkspaceMigration
+	return migrations, json.NewDecoder(res.Body).Decode(&migrations)
+}
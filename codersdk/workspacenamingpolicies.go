@@ -0,0 +1,99 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceNameUniquenessScope string
+
+const (
+	// WorkspaceNameUniquenessScopeOwner requires workspace names to be unique
+	// per owner. This is the default behavior.
+	WorkspaceNameUniquenessScopeOwner WorkspaceNameUniquenessScope = "owner"
+	// WorkspaceNameUniquenessScopeOrganization requires workspace names to be
+	// unique across all workspaces in the organization.
+	WorkspaceNameUniquenessScopeOrganization WorkspaceNameUniquenessScope = "organization"
+)
+
+// WorkspaceNamingPolicy restricts the names of workspaces created in an
+// organization or from a template. A template policy takes precedence over
+// the organization policy.
+//
+// Prefix and Suffix are Go templates that have access to {{.Username}},
+// {{.OrganizationName}}, {{.TemplateName}} and the cost center of the
+// organization, {{.CostCenter}}. Names are rejected while a policy uses the
+// cost center and the organization doesn't have one.
+type WorkspaceNamingPolicy struct {
+	// Regex must match the full workspace name. Empty disables the check.
+	Regex string `json:"regex"`
+	// Prefix is rendered and required at the start of the workspace name.
+	Prefix string `json:"prefix"`
+	// Suffix is rendered and required at the end of the workspace name.
+	Suffix          string                       `json:"suffix"`
+	UniquenessScope WorkspaceNameUniquenessScope `json:"uniqueness_scope" enums:"owner,organization"`
+}
+
+// OrganizationWorkspaceNamingPolicy returns the workspace naming policy of an
+// organization.
+func (c *Client) OrganizationWorkspaceNamingPolicy(ctx context.Context, organizationID uuid.UUID) (WorkspaceNamingPolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/workspace-naming-policy", organizationID), nil)
+	if err != nil {
+		return WorkspaceNamingPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceNamingPolicy{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceNamingPolicy
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateOrganizationWorkspaceNamingPolicy replaces the workspace naming
+// policy of an organization. An empty policy removes all restrictions.
+func (c *Client) UpdateOrganizationWorkspaceNamingPolicy(ctx context.Context, organizationID uuid.UUID, req WorkspaceNamingPolicy) (WorkspaceNamingPolicy, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/workspace-naming-policy", organizationID), req)
+	if err != nil {
+		return WorkspaceNamingPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceNamingPolicy{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceNamingPolicy
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// TemplateWorkspaceNamingPolicy returns the workspace naming policy of a
+// template.
+func (c *Client) TemplateWorkspaceNamingPolicy(ctx context.Context, templateID uuid.UUID) (WorkspaceNamingPolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/workspace-naming-policy", templateID), nil)
+	if err != nil {
+		return WorkspaceNamingPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceNamingPolicy{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceNamingPolicy
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateTemplateWorkspaceNamingPolicy replaces the workspace naming policy of
+// a template. An empty policy falls back to the organization policy.
+func (c *Client) UpdateTemplateWorkspaceNamingPolicy(ctx context.Context, templateID uuid.UUID, req WorkspaceNamingPolicy) (WorkspaceNamingPolicy, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/workspace-naming-policy", templateID), req)
+	if err != nil {
+		return WorkspaceNamingPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceNamingPolicy{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceNamingPolicy
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
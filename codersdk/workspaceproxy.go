@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -201,6 +202,10 @@ type Region struct {
 	// E.g. *--suffix.au.example.com
 	// Optional. Does not need to be on the same domain as PathAppURL.
 	WildcardHostname string `json:"wildcard_hostname" table:"wildcard hostname"`
+
+	// PathAppsOnly is true if the deployment serves all apps, including
+	// subdomain apps and ports, on paths of PathAppURL.
+	PathAppsOnly bool `json:"path_apps_only" table:"path apps only"`
 }
 
 func (c *Client) Regions(ctx context.Context) ([]Region, error) {
@@ -220,3 +225,43 @@ func (c *Client) Regions(ctx context.Context) ([]Region, error) {
 	var regions RegionsResponse[Region]
 	return regions.Regions, json.NewDecoder(res.Body).Decode(&regions)
 }
+
+// RegionFailover is the region that app traffic for a region should use.
+type RegionFailover struct {
+	// Region is the requested region if it is healthy. Otherwise, it is the
+	// first healthy region of the preferred regions in the request, or the
+	// primary region if none of them are healthy.
+	Region Region `json:"region"`
+	// FailedOver is true if Region is not the requested region.
+	FailedOver bool `json:"failed_over"`
+}
+
+// RegionFailover returns the region that app traffic for the given region
+// should fail over to when it is unhealthy. Preferred regions should be
+// ordered from closest to furthest, e.g. by latency.
+func (c *Client) RegionFailover(ctx context.Context, region uuid.UUID, preferred []uuid.UUID) (RegionFailover, error) {
+	var opts []RequestOption
+	if len(preferred) > 0 {
+		ids := make([]string, 0, len(preferred))
+		for _, id := range preferred {
+			ids = append(ids, id.String())
+		}
+		opts = append(opts, WithQueryParam("preferred", strings.Join(ids, ",")))
+	}
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/regions/%s/failover", region.String()),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return RegionFailover{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RegionFailover{}, ReadBodyAsError(res)
+	}
+
+	var failover RegionFailover
+	return failover, json.NewDecoder(res.Body).Decode(&failover)
+}
@@ -0,0 +1,158 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceResourceUsage holds the host resource usage reported by the agents
+// of a workspace, downsampled into fixed intervals.
+type WorkspaceResourceUsage struct {
+	// IntervalSeconds is the length of the interval that each datapoint
+	// covers.
+	IntervalSeconds int64 `json:"interval_seconds" example:"900"`
+	// Agents holds the usage of each agent, ordered by name. Agents are
+	// identified by name so that usage can be followed across builds.
+	Agents []WorkspaceAgentResourceUsage `json:"agents"`
+}
+
+// WorkspaceAgentResourceUsage holds the resource usage of an agent.
+type WorkspaceAgentResourceUsage struct {
+	AgentName string `json:"agent_name"`
+	// Datapoints are ordered by start time, oldest first. Intervals in which
+	// the agent did not report usage are omitted.
+	Datapoints []WorkspaceAgentResourceUsageDatapoint `json:"datapoints"`
+}
+
+// WorkspaceAgentResourceUsageDatapoint holds the resource usage of an agent
+// during an interval. CPU is measured in cores, memory and disk in bytes.
+// Disk usage is that of the volume holding the home directory of the agent,
+// as of the last sample in the interval.
+type WorkspaceAgentResourceUsageDatapoint struct {
+	StartTime     time.Time `json:"start_time" format:"date-time"`
+	Samples       int32     `json:"samples"`
+	CPUUsedAvg    float64   `json:"cpu_used_avg"`
+	CPUUsedMax    float64   `json:"cpu_used_max"`
+	CPUTotal      float64   `json:"cpu_total"`
+	MemoryUsedAvg int64     `json:"memory_used_avg"`
+	MemoryUsedMax int64     `json:"memory_used_max"`
+	MemoryTotal   int64     `json:"memory_total"`
+	DiskUsed      int64     `json:"disk_used"`
+	DiskTotal     int64     `json:"disk_total"`
+}
+
+// WorkspaceResourceUsageRequest is the request to WorkspaceResourceUsage.
+type WorkspaceResourceUsageRequest struct {
+	// StartTime defaults to 24 hours before EndTime.
+	StartTime time.Time `json:"start_time,omitempty" format:"date-time"`
+	// EndTime defaults to now.
+	EndTime time.Time `json:"end_time,omitempty" format:"date-time"`
+}
+
+// WorkspaceResourceUsage returns the host resource usage reported by the
+// agents of a workspace.
+func (c *Client) WorkspaceResourceUsage(ctx context.Context, workspace uuid.UUID, req WorkspaceResourceUsageRequest) (WorkspaceResourceUsage, error) {
+	var opts []RequestOption
+	if !req.StartTime.IsZero() {
+		opts = append(opts, WithQueryParam("start_time", req.StartTime.Format(time.RFC3339Nano)))
+	}
+	if !req.EndTime.IsZero() {
+		opts = append(opts, WithQueryParam("end_time", req.EndTime.Format(time.RFC3339Nano)))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/resource-usage", workspace), nil, opts...)
+	if err != nil {
+		return WorkspaceResourceUsage{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceResourceUsage{}, ReadBodyAsError(res)
+	}
+	var usage WorkspaceResourceUsage
+	return usage, json.NewDecoder(res.Body).Decode(&usage)
+}
+
+// DefaultWorkspaceRecommendationsLookbackDays is the number of days of
+// resource usage that recommendations are based on when no number is
+// requested.
+const DefaultWorkspaceRecommendationsLookbackDays = 7
+
+type WorkspaceRecommendationResource string
+
+const (
+	WorkspaceRecommendationResourceCPU    WorkspaceRecommendationResource = "cpu"
+	WorkspaceRecommendationResourceMemory WorkspaceRecommendationResource = "memory"
+)
+
+type WorkspaceRecommendationDirection string
+
+const (
+	WorkspaceRecommendationDirectionIncrease WorkspaceRecommendationDirection = "increase"
+	WorkspaceRecommendationDirectionDecrease WorkspaceRecommendationDirection = "decrease"
+)
+
+// WorkspaceRecommendations holds the recommendations to resize a workspace
+// based on the resource usage of its agents.
+type WorkspaceRecommendations struct {
+	LookbackDays int `json:"lookback_days" example:"7"`
+	// Recommendations are only made for agents that reported at least a
+	// day's worth of usage within the lookback window.
+	Recommendations []WorkspaceRecommendation `json:"recommendations"`
+}
+
+// WorkspaceRecommendation recommends resizing a resource of an agent. CPU is
+// measured in cores, memory in bytes.
+type WorkspaceRecommendation struct {
+	AgentName      string                           `json:"agent_name"`
+	Resource       WorkspaceRecommendationResource  `json:"resource" enums:"cpu,memory"`
+	Direction      WorkspaceRecommendationDirection `json:"direction" enums:"increase,decrease"`
+	CurrentTotal   float64                          `json:"current_total"`
+	SuggestedTotal float64                          `json:"suggested_total"`
+	UsedAverage    float64                          `json:"used_average"`
+	// UsedPeak is the 95th percentile of the maximum usage of each interval.
+	UsedPeak float64 `json:"used_peak"`
+	// ParameterChanges are the changes to the parameters of the workspace
+	// that are expected to apply the recommendation. They are inferred from
+	// the names of the parameters, so they may be empty even if the template
+	// allows resizing.
+	ParameterChanges []WorkspaceRecommendationParameterChange `json:"parameter_changes"`
+}
+
+// WorkspaceRecommendationParameterChange is a suggested change to a workspace
+// parameter.
+type WorkspaceRecommendationParameterChange struct {
+	Name           string `json:"name"`
+	CurrentValue   string `json:"current_value"`
+	SuggestedValue string `json:"suggested_value"`
+}
+
+// WorkspaceRecommendationsRequest is the request to WorkspaceRecommendations.
+type WorkspaceRecommendationsRequest struct {
+	// LookbackDays is the number of days of resource usage to base the
+	// recommendations on. Defaults to
+	// DefaultWorkspaceRecommendationsLookbackDays.
+	LookbackDays int `json:"lookback_days,omitempty"`
+}
+
+// WorkspaceRecommendations returns the recommendations to resize a workspace.
+func (c *Client) WorkspaceRecommendations(ctx context.Context, workspace uuid.UUID, req WorkspaceRecommendationsRequest) (WorkspaceRecommendations, error) {
+	var opts []RequestOption
+	if req.LookbackDays > 0 {
+		opts = append(opts, WithQueryParam("lookback_days", strconv.Itoa(req.LookbackDays)))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/recommendations", workspace), nil, opts...)
+	if err != nil {
+		return WorkspaceRecommendations{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceRecommendations{}, ReadBodyAsError(res)
+	}
+	var recommendations WorkspaceRecommendations
+	return recommendations, json.NewDecoder(res.Body).Decode(&recommendations)
+}
@@ -66,6 +66,10 @@ type Workspace struct {
 	AllowRenames     bool             `json:"allow_renames"`
 	Favorite         bool             `json:"favorite"`
 	NextStartAt      *time.Time       `json:"next_start_at" format:"date-time"`
+	// DriftCheck is the latest check of the workspace resources for drift.
+	// It is only set if the deployment periodically checks stopped and
+	// failed workspaces for drift.
+	DriftCheck *WorkspaceDriftCheck `json:"drift_check,omitempty"`
 }
 
 func (w Workspace) FullName() string {
@@ -75,6 +79,57 @@ func (w Workspace) FullName() string {
 type WorkspaceHealth struct {
 	Healthy       bool        `json:"healthy" example:"false"`      // Healthy is true if the workspace is healthy.
 	FailingAgents []uuid.UUID `json:"failing_agents" format:"uuid"` // FailingAgents lists the IDs of the agents that are failing, if any.
+	// Warnings are conditions reported by the agents of the workspace that
+	// do not make it unhealthy yet, but are likely to cause failures.
+	Warnings []WorkspaceHealthWarning `json:"warnings"`
+}
+
+type WorkspaceHealthWarningKind string
+
+const (
+	WorkspaceHealthWarningKindNearOutOfMemory WorkspaceHealthWarningKind = "near_out_of_memory"
+	WorkspaceHealthWarningKindLowDiskSpace    WorkspaceHealthWarningKind = "low_disk_space"
+)
+
+// WorkspaceHealthWarning is a condition detected by a workspace agent, such
+// as its host running out of memory.
+type WorkspaceHealthWarning struct {
+	AgentID   uuid.UUID                  `json:"agent_id" format:"uuid"`
+	AgentName string                     `json:"agent_name"`
+	Kind      WorkspaceHealthWarningKind `json:"kind" enums:"near_out_of_memory,low_disk_space"`
+	// Volume is the path of the volume that is low on space. It is empty
+	// for memory warnings.
+	Volume string `json:"volume,omitempty"`
+	// Used and Total are measured in bytes, as of when the condition was
+	// first detected.
+	Used      int64     `json:"used"`
+	Total     int64     `json:"total"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+}
+
+type WorkspaceDriftCheckStatus string
+
+const (
+	WorkspaceDriftCheckStatusPending WorkspaceDriftCheckStatus = "pending"
+	WorkspaceDriftCheckStatusFailed  WorkspaceDriftCheckStatus = "failed"
+	WorkspaceDriftCheckStatusClean   WorkspaceDriftCheckStatus = "clean"
+	WorkspaceDriftCheckStatusDrifted WorkspaceDriftCheckStatus = "drifted"
+)
+
+// WorkspaceDriftCheck is the result of planning the latest build of a
+// stopped or failed workspace against its state.
+type WorkspaceDriftCheck struct {
+	WorkspaceID      uuid.UUID                 `json:"workspace_id" format:"uuid"`
+	WorkspaceBuildID uuid.UUID                 `json:"workspace_build_id" format:"uuid"`
+	Status           WorkspaceDriftCheckStatus `json:"status" enums:"pending,failed,clean,drifted"`
+	CreatedAt        time.Time                 `json:"created_at" format:"date-time"`
+	CompletedAt      *time.Time                `json:"completed_at,omitempty" format:"date-time"`
+	// DriftedResources are the addresses of resources that were changed
+	// outside of Terraform.
+	DriftedResources []string `json:"drifted_resources"`
+	// OrphanedResources are the addresses of resources that are in the
+	// state of the build but are no longer part of the workspace.
+	OrphanedResources []string `json:"orphaned_resources"`
 }
 
 type WorkspacesRequest struct {
@@ -101,6 +156,10 @@ type CreateWorkspaceBuildRequest struct {
 	ProvisionerState  []byte              `json:"state,omitempty"`
 	// Orphan may be set for the Destroy transition.
 	Orphan bool `json:"orphan,omitempty"`
+	// Resume builds from the state uploaded while the last build was applied,
+	// rather than the state it completed with. It may be set if the last build
+	// failed or was canceled, to manage the resources it already created.
+	Resume bool `json:"resume,omitempty"`
 	// ParameterValues are optional. It will write params to the 'workspace' scope.
 	// This will overwrite any existing parameters with the same name.
 	// This will not delete old params not included in this list.
@@ -110,6 +169,18 @@ type CreateWorkspaceBuildRequest struct {
 	LogLevel ProvisionerLogLevel `json:"log_level,omitempty" validate:"omitempty,oneof=debug"`
 	// TemplateVersionPresetID is the ID of the template version preset to use for the build.
 	TemplateVersionPresetID uuid.UUID `json:"template_version_preset_id,omitempty" format:"uuid"`
+	// TemplatePresetID is the ID of the template preset to use for the build.
+	// It cannot be combined with TemplateVersionPresetID.
+	TemplatePresetID uuid.UUID `json:"template_preset_id,omitempty" format:"uuid"`
+	// Queue the build if another build of the workspace is active, rather
+	// than failing. The queued build starts once the active build completes,
+	// fails or is canceled.
+	Queue bool `json:"queue,omitempty"`
+	// UseParameterDefaults resolves mutable parameters from the current
+	// defaults of the template version instead of the values of the last
+	// build. Values in RichParameterValues and the preset still take
+	// precedence, and immutable parameters keep their values.
+	UseParameterDefaults bool `json:"use_parameter_defaults,omitempty"`
 }
 
 type WorkspaceOptions struct {
@@ -178,8 +249,39 @@ func (c *Client) WorkspaceBuilds(ctx context.Context, req WorkspaceBuildsRequest
 }
 
 // CreateWorkspaceBuild queues a new build to occur for a workspace.
-func (c *Client) CreateWorkspaceBuild(ctx context.Context, workspace uuid.UUID, request CreateWorkspaceBuildRequest) (WorkspaceBuild, error) {
-	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/builds", workspace), request)
+func (c *Client) CreateWorkspaceBuild(ctx context.Context, workspace uuid.UUID, request CreateWorkspaceBuildRequest, opts ...RequestOption) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/builds", workspace), request, opts...)
+	if err != nil {
+		return WorkspaceBuild{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var workspaceBuild WorkspaceBuild
+	return workspaceBuild, json.NewDecoder(res.Body).Decode(&workspaceBuild)
+}
+
+// RollbackWorkspaceBuild queues a new build that starts the workspace with the
+// template version and parameter values of its last successful start build.
+func (c *Client) RollbackWorkspaceBuild(ctx context.Context, workspace uuid.UUID) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/builds/rollback", workspace), nil)
+	if err != nil {
+		return WorkspaceBuild{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var workspaceBuild WorkspaceBuild
+	return workspaceBuild, json.NewDecoder(res.Body).Decode(&workspaceBuild)
+}
+
+// RestoreWorkspace restores a deleted workspace and queues a build that starts
+// it with the template version and parameter values of its last successful
+// start build.
+func (c *Client) RestoreWorkspace(ctx context.Context, workspace uuid.UUID) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/restore", workspace), nil)
 	if err != nil {
 		return WorkspaceBuild{}, err
 	}
@@ -485,6 +587,79 @@ func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UU
 	return nil
 }
 
+// WorkspaceRole is the level of access a workspace is shared with.
+type WorkspaceRole string
+
+const (
+	// WorkspaceRoleView allows reading the workspace.
+	WorkspaceRoleView WorkspaceRole = "view"
+	// WorkspaceRoleConnect allows reading the workspace, connecting to it
+	// over SSH and opening its apps.
+	WorkspaceRoleConnect WorkspaceRole = "connect"
+	// WorkspaceRoleDeleted removes a user or group from the ACL.
+	WorkspaceRoleDeleted WorkspaceRole = ""
+)
+
+// WorkspaceACL lists the users and groups a workspace is shared with.
+type WorkspaceACL struct {
+	Users  []WorkspaceUser  `json:"users"`
+	Groups []WorkspaceGroup `json:"groups"`
+}
+
+type WorkspaceUser struct {
+	MinimalUser
+	Role WorkspaceRole `json:"role" enums:"view,connect"`
+}
+
+type WorkspaceGroup struct {
+	ID          uuid.UUID     `json:"id" format:"uuid"`
+	Name        string        `json:"name"`
+	DisplayName string        `json:"display_name"`
+	AvatarURL   string        `json:"avatar_url" format:"uri"`
+	Role        WorkspaceRole `json:"role" enums:"view,connect"`
+}
+
+// UpdateWorkspaceACL adds, changes or removes the users and groups a
+// workspace is shared with. Users and groups not in the request are left
+// unchanged.
+type UpdateWorkspaceACL struct {
+	// UserRoles is a mapping of user ID to role. An empty role removes the
+	// user from the ACL.
+	UserRoles map[string]WorkspaceRole `json:"user_roles,omitempty" example:"4df59e74-c027-470b-ab4d-cbba8963a5e9:connect"`
+	// GroupRoles is a mapping of group ID to role. An empty role removes the
+	// group from the ACL.
+	GroupRoles map[string]WorkspaceRole `json:"group_roles,omitempty" example:"8bd26b20-f3e8-48be-a903-46bb920cf671:view"`
+}
+
+// WorkspaceACL returns the users and groups a workspace is shared with.
+func (c *Client) WorkspaceACL(ctx context.Context, id uuid.UUID) (WorkspaceACL, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/acl", id.String())
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return WorkspaceACL{}, xerrors.Errorf("get workspace acl: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceACL{}, ReadBodyAsError(res)
+	}
+	var acl WorkspaceACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
+// UpdateWorkspaceACL shares a workspace with users and groups.
+func (c *Client) UpdateWorkspaceACL(ctx context.Context, id uuid.UUID, req UpdateWorkspaceACL) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/acl", id.String())
+	res, err := c.Request(ctx, http.MethodPatch, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace acl: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type WorkspaceFilter struct {
 	// Owner can be "me" or a username
 	Owner string `json:"owner,omitempty" typescript:"-"`
@@ -498,8 +673,15 @@ type WorkspaceFilter struct {
 	Offset int `json:"offset,omitempty" typescript:"-"`
 	// Limit is a limit on the number of workspaces returned.
 	Limit int `json:"limit,omitempty" typescript:"-"`
+	// AfterID returns the workspaces after the given workspace. Set it to
+	// the ID of the last workspace of the previous page to paginate without
+	// an offset.
+	AfterID uuid.UUID `json:"after_id,omitempty" format:"uuid" typescript:"-"`
 	// FilterQuery supports a raw filter query string
 	FilterQuery string `json:"q,omitempty"`
+	// Fields limits the returned workspaces to the given JSON fields, e.g.
+	// "latest_build.status". Fields that are not selected are left empty.
+	Fields []string `json:"fields,omitempty" typescript:"-"`
 }
 
 // asRequestOption returns a function that can be used in (*Client).Request.
@@ -528,6 +710,9 @@ func (f WorkspaceFilter) asRequestOption() RequestOption {
 
 		q := r.URL.Query()
 		q.Set("q", strings.Join(params, " "))
+		if len(f.Fields) > 0 {
+			q.Set("fields", strings.Join(f.Fields, ","))
+		}
 		r.URL.RawQuery = q.Encode()
 	}
 }
@@ -535,8 +720,9 @@ func (f WorkspaceFilter) asRequestOption() RequestOption {
 // Workspaces returns all workspaces the authenticated user has access to.
 func (c *Client) Workspaces(ctx context.Context, filter WorkspaceFilter) (WorkspacesResponse, error) {
 	page := Pagination{
-		Offset: filter.Offset,
-		Limit:  filter.Limit,
+		AfterID: filter.AfterID,
+		Offset:  filter.Offset,
+		Limit:   filter.Limit,
 	}
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/workspaces", nil, filter.asRequestOption(), page.asRequestOption())
 	if err != nil {
@@ -575,6 +761,15 @@ func (c *Client) WorkspaceByOwnerAndName(ctx context.Context, owner string, name
 type WorkspaceQuota struct {
 	CreditsConsumed int `json:"credits_consumed"`
 	Budget          int `json:"budget"`
+	// MonthlyBudget is the number of credits the user may spend on workspaces
+	// in the current calendar month (UTC). Workspaces accrue their daily cost
+	// by the hour while they exist. 0 means there is no monthly budget.
+	MonthlyBudget           int     `json:"monthly_budget"`
+	MonthlyCreditsSpent     float64 `json:"monthly_credits_spent"`
+	MonthlyCreditsRemaining float64 `json:"monthly_credits_remaining"`
+	// MonthlyBudgetResetsAt is the start of the next month, when the monthly
+	// spend is reset. It is only set if there is a monthly budget.
+	MonthlyBudgetResetsAt *time.Time `json:"monthly_budget_resets_at,omitempty" format:"date-time"`
 }
 
 func (c *Client) WorkspaceQuota(ctx context.Context, organizationID string, userID string) (WorkspaceQuota, error) {
@@ -205,6 +205,7 @@ func (c *AgentConn) SSHClientOnPort(ctx context.Context, port uint16) (*ssh.Clie
 		// connection already signifies user-intent to dial a workspace.
 		// #nosec
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		ClientVersion:   AgentSSHClientVersion,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("ssh conn: %w", err)
@@ -49,6 +49,12 @@ const (
 	// *that* much. The user could bypass this in the CLI by using SSH instead
 	// anyways.
 	AgentMinimumListeningPort = 9
+
+	// AgentSSHClientVersion is the SSH version string sent by Coder when it
+	// connects to the agent over SSH. Agents report it when auditing
+	// connections so that the Coder CLI can be told apart from other SSH
+	// clients.
+	AgentSSHClientVersion = "SSH-2.0-Coder"
 )
 
 const (
@@ -0,0 +1,71 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContentTypeAsciicast is the content type of session recording transcripts,
+// which use the asciicast v2 format.
+const ContentTypeAsciicast = "application/x-asciicast"
+
+type WorkspaceSessionRecordingType string
+
+const (
+	WorkspaceSessionRecordingTypeSSH             WorkspaceSessionRecordingType = "ssh"
+	WorkspaceSessionRecordingTypeReconnectingPTY WorkspaceSessionRecordingType = "reconnecting_pty"
+)
+
+// WorkspaceSessionRecording is the recorded output of a terminal session in a
+// workspace. Sessions are recorded when session recording is enabled for the
+// deployment.
+type WorkspaceSessionRecording struct {
+	ID          uuid.UUID                     `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID                     `json:"workspace_id" format:"uuid"`
+	AgentID     uuid.UUID                     `json:"agent_id" format:"uuid"`
+	Type        WorkspaceSessionRecordingType `json:"type" enums:"ssh,reconnecting_pty"`
+	// Width and Height are the size of the terminal when the session started.
+	Width     int32     `json:"width"`
+	Height    int32     `json:"height"`
+	StartedAt time.Time `json:"started_at" format:"date-time"`
+	// EndedAt is unset while the session is still running, or if the agent
+	// disconnected before the end of the session was recorded.
+	EndedAt   *time.Time `json:"ended_at,omitempty" format:"date-time"`
+	SizeBytes int64      `json:"size_bytes"`
+}
+
+// WorkspaceSessionRecordings returns the session recordings of a workspace,
+// newest first. Reading recordings requires permission to read audit logs.
+func (c *Client) WorkspaceSessionRecordings(ctx context.Context, workspace uuid.UUID) ([]WorkspaceSessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings", workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []WorkspaceSessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// WorkspaceSessionRecordingTranscript returns the transcript of a session
+// recording in the asciicast v2 format, which can be played back with tools
+// such as asciinema.
+func (c *Client) WorkspaceSessionRecordingTranscript(ctx context.Context, workspace, recording uuid.UUID) ([]byte, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings/%s/transcript", workspace, recording), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	return io.ReadAll(res.Body)
+}
@@ -18,17 +18,20 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"WorkspaceAgent":  {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
-	"WorkspaceApp":    {codersdk.AuditActionOpen, codersdk.AuditActionClose},
+	"GitSSHKey":             {codersdk.AuditActionCreate},
+	"Template":              {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                  {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":             {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceBuild":        {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                 {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":               {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"WorkspaceAgent":        {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
+	"WorkspaceApp":          {codersdk.AuditActionOpen, codersdk.AuditActionClose},
+	"ProvisionerBuildPause": {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"AccessRequest":         {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"LoginDeviceCode":       {codersdk.AuditActionWrite},
 }
 
 type Action string
@@ -117,6 +120,10 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"max_port_sharing_level":            ActionTrack,
 		"activity_bump":                     ActionTrack,
 		"use_classic_parameter_flow":        ActionTrack,
+		"max_concurrent_jobs_per_user":      ActionTrack,
+		"resource_ceilings":                 ActionTrack,
+		"required_provisioner_tags":         ActionTrack,
+		"shared_organization_ids":           ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -174,6 +181,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"automatic_updates":  ActionTrack,
 		"favorite":           ActionTrack,
 		"next_start_at":      ActionTrack,
+		"group_acl":          ActionTrack,
+		"user_acl":           ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                         ActionIgnore,
@@ -196,6 +205,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"template_version_preset_id": ActionIgnore, // Never changes.
 		"has_ai_task":                ActionIgnore, // Never changes.
 		"ai_task_sidebar_app_id":     ActionIgnore, // Never changes.
+		"initiator_context":          ActionIgnore, // Never changes.
+		"warnings":                   ActionIgnore, // Never changes.
 	},
 	&database.AuditableGroup{}: {
 		"id":              ActionTrack,
@@ -204,6 +215,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"organization_id": ActionIgnore, // Never changes.
 		"avatar_url":      ActionTrack,
 		"quota_allowance": ActionTrack,
+		"monthly_budget":  ActionTrack,
 		"members":         ActionTrack,
 		"source":          ActionIgnore,
 	},
@@ -220,6 +232,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"ip_address":       ActionIgnore,
 		"scope":            ActionIgnore,
 		"token_name":       ActionIgnore,
+		"bound_identity":   ActionIgnore,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -236,6 +249,41 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"id":              ActionIgnore,
 		"notifier_paused": ActionTrack,
 	},
+	&database.ReadOnlySettings{}: {
+		"id":      ActionIgnore,
+		"enabled": ActionTrack,
+	},
+	&database.ProvisionerBuildPause{}: {
+		"id":              ActionIgnore,
+		"organization_id": ActionTrack,
+		"reason":          ActionTrack,
+		"created_by":      ActionTrack,
+		"created_at":      ActionIgnore,
+	},
+	&database.AccessRequest{}: {
+		"id":              ActionIgnore,
+		"organization_id": ActionIgnore, // Never changes.
+		"requester_id":    ActionTrack,
+		"resource_type":   ActionTrack,
+		"resource_id":     ActionTrack,
+		"role":            ActionTrack,
+		"reason":          ActionTrack,
+		"duration_ms":     ActionTrack,
+		"status":          ActionTrack,
+		"previous_role":   ActionTrack,
+		"reviewed_by":     ActionTrack,
+		"reviewed_at":     ActionIgnore,
+		"expires_at":      ActionTrack,
+		"created_at":      ActionIgnore,
+	},
+	&database.LoginDeviceCode{}: {
+		"id":               ActionIgnore,
+		"device_code_hash": ActionSecret,
+		"user_code":        ActionTrack,
+		"user_id":          ActionTrack,
+		"created_at":       ActionIgnore,
+		"expires_at":       ActionIgnore,
+	},
 	// TODO: track an ID here when the below ticket is completed:
 	// https://github.com/coder/coder/pull/6012
 	&database.License{}: {
@@ -288,6 +336,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"is_default":   ActionTrack,
 		"display_name": ActionTrack,
 		"icon":         ActionTrack,
+		"cost_center":  ActionTrack,
 	},
 	&database.NotificationTemplate{}: {
 		"id":                 ActionIgnore,
@@ -301,15 +350,19 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"enabled_by_default": ActionTrack,
 	},
 	&idpsync.OrganizationSyncSettings{}: {
-		"field":          ActionTrack,
-		"mapping":        ActionTrack,
-		"assign_default": ActionTrack,
+		"field":                  ActionTrack,
+		"mapping":                ActionTrack,
+		"mapping_rules":          ActionTrack,
+		"nested_group_separator": ActionTrack,
+		"assign_default":         ActionTrack,
 	},
 	&idpsync.GroupSyncSettings{}: {
 		"field":                      ActionTrack,
 		"mapping":                    ActionTrack,
 		"regex_filter":               ActionTrack,
 		"auto_create_missing_groups": ActionTrack,
+		"mapping_rules":              ActionTrack,
+		"nested_group_separator":     ActionTrack,
 		// Configured in env vars
 		"legacy_group_name_mapping": ActionIgnore,
 	},
@@ -352,6 +405,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"parent_id":                  ActionIgnore,
 		"api_key_scope":              ActionIgnore,
 		"deleted":                    ActionIgnore,
+		"display_group":              ActionIgnore,
+		"collapsed":                  ActionIgnore,
 	},
 	&database.WorkspaceApp{}: {
 		"id":                    ActionIgnore,
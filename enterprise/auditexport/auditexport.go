@@ -0,0 +1,331 @@
+// Package auditexport streams audit logs to external sinks, such as a SIEM.
+// Every sink tracks how far it got with a cursor stored in the database, so
+// each audit log is delivered at least once, even across restarts.
+package auditexport
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// settleDelay is how old audit logs must be before they are exported.
+	// Audit logs are exported in the order of their time, so a log inserted
+	// with an earlier time than the last exported one would be skipped.
+	// Waiting gives inserts that are in flight time to commit.
+	settleDelay = 5 * time.Second
+	// cursorKeyPrefix prefixes the site config keys the cursors of the
+	// sinks are stored at.
+	cursorKeyPrefix = "audit_log_export_cursor:"
+	// exportTimeout is how long a sink has to export a batch.
+	exportTimeout = time.Minute
+)
+
+// Event is an audit log as it is exported to sinks.
+type Event struct {
+	ID               uuid.UUID       `json:"id"`
+	Time             time.Time       `json:"time"`
+	OrganizationID   uuid.UUID       `json:"organization_id"`
+	OrganizationName string          `json:"organization_name,omitempty"`
+	UserID           uuid.UUID       `json:"user_id"`
+	Username         string          `json:"username,omitempty"`
+	UserEmail        string          `json:"user_email,omitempty"`
+	IP               string          `json:"ip,omitempty"`
+	UserAgent        string          `json:"user_agent,omitempty"`
+	ResourceType     string          `json:"resource_type"`
+	ResourceID       uuid.UUID       `json:"resource_id"`
+	ResourceTarget   string          `json:"resource_target"`
+	Action           string          `json:"action"`
+	Diff             json.RawMessage `json:"diff"`
+	StatusCode       int32           `json:"status_code"`
+	AdditionalFields json.RawMessage `json:"additional_fields"`
+	RequestID        uuid.UUID       `json:"request_id"`
+}
+
+// Sink receives exported audit logs.
+type Sink interface {
+	// Name identifies the sink. The progress of every sink is tracked
+	// separately, so the name must not change between restarts.
+	Name() string
+	// Export delivers events to the sink. Events are exported again until
+	// Export succeeds, so sinks may receive duplicates.
+	Export(ctx context.Context, events []Event) error
+	io.Closer
+}
+
+// NewSinks returns the sinks configured in the deployment values. No sinks
+// are returned if audit log export is not configured.
+func NewSinks(ctx context.Context, cfg codersdk.AuditLogExportConfig, client *http.Client) ([]Sink, error) {
+	var sinks []Sink
+	if webhookURL := cfg.WebhookURL.Value(); webhookURL != "" {
+		if _, err := url.ParseRequestURI(webhookURL); err != nil {
+			return nil, xerrors.Errorf("parse webhook URL: %w", err)
+		}
+		sinks = append(sinks, NewWebhook(client, webhookURL))
+	}
+	if brokers := cfg.KafkaBrokers.Value(); len(brokers) > 0 {
+		if cfg.KafkaTopic.Value() == "" {
+			return nil, xerrors.New("a Kafka topic is required")
+		}
+		sinks = append(sinks, NewKafka(brokers, cfg.KafkaTopic.Value(), int(cfg.BatchSize.Value())))
+	}
+	if bucket := cfg.S3Bucket.Value(); bucket != "" {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("load AWS config: %w", err)
+		}
+		sinks = append(sinks, NewS3(s3.NewFromConfig(awsCfg), bucket, cfg.S3Prefix.Value()))
+	}
+	if len(sinks) > 0 {
+		if err := validate(cfg.Interval.Value(), int(cfg.BatchSize.Value())); err != nil {
+			return nil, err
+		}
+	}
+	return sinks, nil
+}
+
+func validate(interval time.Duration, batchSize int) error {
+	if interval <= 0 {
+		return xerrors.New("the interval must be positive")
+	}
+	if batchSize <= 0 {
+		return xerrors.New("the batch size must be positive")
+	}
+	return nil
+}
+
+// cursor is the time and ID of the last audit log exported to a sink.
+type cursor struct {
+	Time time.Time `json:"time"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// New starts exporting new audit logs to the sinks every interval, in
+// batches of at most batchSize logs. A sink that hasn't exported any logs
+// yet starts with the oldest audit log.
+// It is the caller's responsibility to call Close on the returned instance,
+// which also closes the sinks.
+func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.Clock, sinks []Sink, interval time.Duration, batchSize int) (io.Closer, error) {
+	if err := validate(interval, batchSize); err != nil {
+		return nil, err
+	}
+	closed := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system exports audit logs without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	ticker := clk.NewTicker(interval)
+	doTick := func(start time.Time) {
+		defer ticker.Reset(interval)
+		for _, sink := range sinks {
+			var exported int
+			for {
+				n, err := exportBatch(ctx, db, sink, start, batchSize)
+				if err != nil {
+					logger.Warn(ctx, "failed to export audit logs, retrying on the next tick",
+						slog.F("sink", sink.Name()),
+						slog.Error(err),
+					)
+					break
+				}
+				exported += n
+				// A partial batch means the sink caught up.
+				if n < batchSize {
+					break
+				}
+			}
+			if exported > 0 {
+				logger.Debug(ctx, "exported audit logs",
+					slog.F("sink", sink.Name()),
+					slog.F("count", exported),
+					slog.F("duration", clk.Since(start)),
+				)
+			}
+		}
+	}
+
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		// Force an initial tick.
+		doTick(dbtime.Time(clk.Now()).UTC())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick := <-ticker.C:
+				ticker.Stop()
+				doTick(dbtime.Time(tick).UTC())
+			}
+		}
+	}()
+	return &instance{
+		cancel: cancelFunc,
+		closed: closed,
+		sinks:  sinks,
+	}, nil
+}
+
+type instance struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+	sinks  []Sink
+}
+
+func (i *instance) Close() error {
+	i.cancel()
+	<-i.closed
+	var errs []error
+	for _, sink := range i.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, xerrors.Errorf("close %s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// exportBatch exports the audit logs following the cursor of the sink, and
+// advances the cursor once the sink accepted them. It returns the number of
+// exported logs.
+func exportBatch(ctx context.Context, db database.Store, sink Sink, now time.Time, batchSize int) (int, error) {
+	key := cursorKeyPrefix + sink.Name()
+	var (
+		start cursor
+		rows  []database.GetAuditLogsForExportRow
+	)
+	// Read the batch under the advisory lock, so replicas don't export the
+	// same logs at the same time.
+	err := db.InTx(func(tx database.Store) error {
+		var (
+			ok  bool
+			err error
+		)
+		start, ok, err = lockCursor(ctx, tx, key)
+		if err != nil || !ok {
+			return err
+		}
+		rows, err = tx.GetAuditLogsForExport(ctx, database.GetAuditLogsForExportParams{
+			AfterTime:  start.Time,
+			AfterID:    start.ID,
+			BeforeTime: now.Add(-settleDelay),
+			LimitCount: int32(batchSize), // #nosec G115 - The batch size is small.
+		})
+		if err != nil {
+			return xerrors.Errorf("get audit logs: %w", err)
+		}
+		return nil
+	}, database.DefaultTXOptions().WithID("audit_log_export"))
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	events := make([]Event, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, convertEvent(row))
+	}
+	// The lock isn't held while exporting, so a slow sink doesn't hold a
+	// database connection. Another replica may export the same logs in the
+	// meantime, sinks have to handle duplicates anyway.
+	exportCtx, cancel := context.WithTimeout(ctx, exportTimeout)
+	err = sink.Export(exportCtx, events)
+	cancel()
+	if err != nil {
+		return 0, xerrors.Errorf("export: %w", err)
+	}
+
+	last := rows[len(rows)-1].AuditLog
+	value, err := json.Marshal(cursor{Time: last.Time, ID: last.ID})
+	if err != nil {
+		return 0, xerrors.Errorf("encode cursor: %w", err)
+	}
+	var exported int
+	err = db.InTx(func(tx database.Store) error {
+		current, ok, err := lockCursor(ctx, tx, key)
+		if err != nil || !ok {
+			return err
+		}
+		// Another replica exported the logs in the meantime, and already
+		// advanced the cursor.
+		if !current.Time.Equal(start.Time) || current.ID != start.ID {
+			return nil
+		}
+		if err := tx.UpsertRuntimeConfig(ctx, database.UpsertRuntimeConfigParams{
+			Key:   key,
+			Value: string(value),
+		}); err != nil {
+			return xerrors.Errorf("update cursor: %w", err)
+		}
+		exported = len(rows)
+		return nil
+	}, database.DefaultTXOptions().WithID("audit_log_export"))
+	return exported, err
+}
+
+// lockCursor acquires the export lock for the transaction, and returns the
+// cursor stored at key. It returns false if another replica holds the lock.
+func lockCursor(ctx context.Context, tx database.Store, key string) (cursor, bool, error) {
+	var c cursor
+	ok, err := tx.TryAcquireLock(ctx, database.LockIDAuditLogExport)
+	if err != nil || !ok {
+		return c, false, err
+	}
+	raw, err := tx.GetRuntimeConfig(ctx, key)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return c, false, xerrors.Errorf("get cursor: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(raw), &c); err != nil {
+			return c, false, xerrors.Errorf("decode cursor: %w", err)
+		}
+	}
+	return c, true, nil
+}
+
+func convertEvent(row database.GetAuditLogsForExportRow) Event {
+	alog := row.AuditLog
+	event := Event{
+		ID:               alog.ID,
+		Time:             alog.Time,
+		OrganizationID:   alog.OrganizationID,
+		OrganizationName: row.OrganizationName,
+		UserID:           alog.UserID,
+		Username:         row.UserUsername,
+		UserEmail:        row.UserEmail,
+		UserAgent:        alog.UserAgent.String,
+		ResourceType:     string(alog.ResourceType),
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		Action:           string(alog.Action),
+		Diff:             alog.Diff,
+		StatusCode:       alog.StatusCode,
+		AdditionalFields: alog.AdditionalFields,
+		RequestID:        alog.RequestID,
+	}
+	if alog.Ip.Valid {
+		event.IP = alog.Ip.IPNet.IP.String()
+	}
+	return event
+}
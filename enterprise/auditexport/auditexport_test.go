@@ -0,0 +1,261 @@
+package auditexport_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/auditexport"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+//nolint:paralleltest // It uses LockIDAuditLogExport.
+func TestExport(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	const interval = 10 * time.Second
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+	db, _ := dbtestutil.NewDB(t)
+	user := dbgen.User(t, db, database.User{})
+
+	var logs []database.AuditLog
+	for i := 3; i > 0; i-- {
+		logs = append(logs, dbgen.AuditLog(t, db, database.AuditLog{
+			UserID: user.ID,
+			Time:   now.Add(-time.Duration(i) * time.Minute),
+		}))
+	}
+	// Recent logs are exported once they settled.
+	recent := dbgen.AuditLog(t, db, database.AuditLog{
+		UserID: user.ID,
+		Time:   now.Add(-time.Second),
+	})
+
+	trapReset := clk.Trap().TickerReset()
+	defer trapReset.Close()
+
+	// The logs are exported in batches until the sink caught up.
+	sink := &fakeSink{}
+	exporter, err := auditexport.New(context.Background(), testutil.Logger(t), db, clk, []auditexport.Sink{sink}, interval, 2)
+	require.NoError(t, err)
+	trapReset.MustWait(ctx).MustRelease(ctx)
+	require.Equal(t, []uuid.UUID{logs[0].ID, logs[1].ID, logs[2].ID}, sink.exportedIDs())
+	require.Equal(t, user.Username, sink.exported()[0].Username)
+
+	// Logs that fail to export are retried on the next tick.
+	sink.setErr(xerrors.New("unavailable"))
+	clk.Advance(interval).MustWait(ctx)
+	trapReset.MustWait(ctx).MustRelease(ctx)
+	require.Len(t, sink.exported(), 3)
+
+	sink.setErr(nil)
+	clk.Advance(interval).MustWait(ctx)
+	trapReset.MustWait(ctx).MustRelease(ctx)
+	require.Equal(t, []uuid.UUID{logs[0].ID, logs[1].ID, logs[2].ID, recent.ID}, sink.exportedIDs())
+	require.NoError(t, exporter.Close())
+	require.True(t, sink.closed)
+
+	// The progress of the sink is kept across restarts.
+	restarted := &fakeSink{}
+	exporter, err = auditexport.New(context.Background(), testutil.Logger(t), db, clk, []auditexport.Sink{restarted}, interval, 2)
+	require.NoError(t, err)
+	trapReset.MustWait(ctx).MustRelease(ctx)
+	require.Empty(t, restarted.exported())
+	require.NoError(t, exporter.Close())
+}
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received []auditexport.Event
+		status   = http.StatusOK
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !strings.EqualFold(r.Header.Get("Content-Type"), "application/x-ndjson") {
+			rw.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var event auditexport.Event
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			received = append(received, event)
+		}
+		rw.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	sink := auditexport.NewWebhook(srv.Client(), srv.URL)
+	events := []auditexport.Event{newEvent(), newEvent()}
+	require.NoError(t, sink.Export(ctx, events))
+	mu.Lock()
+	require.Equal(t, events[0].ID, received[0].ID)
+	require.Equal(t, events[1].ID, received[1].ID)
+	status = http.StatusServiceUnavailable
+	mu.Unlock()
+
+	require.Error(t, sink.Export(ctx, events))
+}
+
+func TestS3(t *testing.T) {
+	t.Parallel()
+
+	type object struct {
+		path  string
+		lines int
+	}
+	objects := make(chan object, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var lines int
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines++
+		}
+		objects <- object{path: r.URL.Path, lines: lines}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := s3.New(s3.Options{
+		BaseEndpoint: aws.String(srv.URL),
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		UsePathStyle: true,
+		HTTPClient:   srv.Client(),
+	})
+	ctx := testutil.Context(t, testutil.WaitShort)
+	sink := auditexport.NewS3(client, "bucket", "audit-logs/")
+	events := []auditexport.Event{newEvent(), newEvent()}
+	events[0].Time = time.Date(2026, 10, 19, 8, 30, 15, 123456000, time.UTC)
+	require.NoError(t, sink.Export(ctx, events))
+
+	obj := testutil.TryReceive(ctx, t, objects)
+	// Files are named after the first event, so retries overwrite them.
+	require.Equal(t, "/bucket/audit-logs/2026/10/19/083015.123456-"+events[0].ID.String()+".ndjson", obj.path)
+	require.Equal(t, 2, obj.lines)
+}
+
+func TestNewSinks(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	var cfg codersdk.AuditLogExportConfig
+	sinks, err := auditexport.NewSinks(ctx, cfg, http.DefaultClient)
+	require.NoError(t, err)
+	require.Empty(t, sinks)
+
+	require.NoError(t, cfg.WebhookURL.Set("https://siem.example.com/ingest"))
+	require.NoError(t, cfg.BatchSize.Set("100"))
+	_, err = auditexport.NewSinks(ctx, cfg, http.DefaultClient)
+	require.ErrorContains(t, err, "interval must be positive")
+
+	require.NoError(t, cfg.Interval.Set("1m"))
+	sinks, err = auditexport.NewSinks(ctx, cfg, http.DefaultClient)
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+	require.Equal(t, "webhook", sinks[0].Name())
+
+	require.NoError(t, cfg.KafkaBrokers.Set("localhost:9092"))
+	_, err = auditexport.NewSinks(ctx, cfg, http.DefaultClient)
+	require.ErrorContains(t, err, "Kafka topic")
+}
+
+func newEvent() auditexport.Event {
+	return auditexport.Event{
+		ID:               uuid.New(),
+		Time:             dbtime.Now(),
+		ResourceType:     string(database.ResourceTypeTemplate),
+		ResourceID:       uuid.New(),
+		Action:           string(database.AuditActionWrite),
+		Diff:             json.RawMessage("{}"),
+		StatusCode:       http.StatusOK,
+		AdditionalFields: json.RawMessage("{}"),
+	}
+}
+
+type fakeSink struct {
+	mu     sync.Mutex
+	err    error
+	events []auditexport.Event
+	closed bool
+}
+
+func (*fakeSink) Name() string {
+	return "fake"
+}
+
+func (s *fakeSink) Export(ctx context.Context, events []auditexport.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return xerrors.New("export without a timeout")
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *fakeSink) exported() []auditexport.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]auditexport.Event(nil), s.events...)
+}
+
+func (s *fakeSink) exportedIDs() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, event := range s.exported() {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
@@ -0,0 +1,59 @@
+package auditexport
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"golang.org/x/xerrors"
+)
+
+type kafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafka returns a sink that produces a message for every event to topic.
+// Messages are keyed by organization, so the events of an organization stay
+// in order.
+func NewKafka(brokers []string, topic string, batchSize int) Sink {
+	return &kafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchSize:    batchSize,
+			// Messages are written synchronously in batches that are already
+			// complete, so there is no point in waiting for more.
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+}
+
+func (*kafkaSink) Name() string {
+	return "kafka"
+}
+
+func (s *kafkaSink) Export(ctx context.Context, events []Event) error {
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return xerrors.Errorf("encode event %s: %w", event.ID, err)
+		}
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(event.OrganizationID.String()),
+			Value: value,
+			Time:  event.Time,
+		})
+	}
+	if err := s.writer.WriteMessages(ctx, msgs...); err != nil {
+		return xerrors.Errorf("write messages: %w", err)
+	}
+	return nil
+}
+
+func (s *kafkaSink) Close() error {
+	return s.writer.Close()
+}
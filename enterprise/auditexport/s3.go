@@ -0,0 +1,52 @@
+package auditexport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"golang.org/x/xerrors"
+)
+
+type s3Sink struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3 returns a sink that writes every batch of events to a
+// newline-delimited JSON file in bucket. Files are named after the first
+// event of the batch, so a batch that is exported again overwrites the file
+// of the failed attempt.
+func NewS3(client *s3.Client, bucket, prefix string) Sink {
+	return &s3Sink{client: client, bucket: bucket, prefix: prefix}
+}
+
+func (*s3Sink) Name() string {
+	return "s3"
+}
+
+func (s *s3Sink) Export(ctx context.Context, events []Event) error {
+	body, err := encodeNDJSON(events)
+	if err != nil {
+		return err
+	}
+	first := events[0]
+	key := fmt.Sprintf("%s%s-%s.ndjson", s.prefix, first.Time.UTC().Format("2006/01/02/150405.000000"), first.ID)
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/x-ndjson"),
+	})
+	if err != nil {
+		return xerrors.Errorf("put object %q: %w", key, err)
+	}
+	return nil
+}
+
+func (*s3Sink) Close() error {
+	return nil
+}
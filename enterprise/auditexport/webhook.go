@@ -0,0 +1,65 @@
+package auditexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+type webhookSink struct {
+	client *http.Client
+	url    string
+}
+
+// NewWebhook returns a sink that sends batches of events as newline-delimited
+// JSON in HTTP POST requests to url. Any response status other than 2xx
+// fails the export.
+func NewWebhook(client *http.Client, url string) Sink {
+	return &webhookSink{client: client, url: url}
+}
+
+func (*webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Export(ctx context.Context, events []Event) error {
+	body, err := encodeNDJSON(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("unexpected status %d: %s", res.StatusCode, msg)
+	}
+	return nil
+}
+
+func (*webhookSink) Close() error {
+	return nil
+}
+
+// encodeNDJSON encodes events as newline-delimited JSON.
+func encodeNDJSON(events []Event) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return nil, xerrors.Errorf("encode event %s: %w", event.ID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,65 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestImportGroups(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureTemplateRBAC: 1,
+		},
+	}})
+
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"users": [
+			{"username": "alice", "email": "alice@coder.com", "login_type": "none"}
+		],
+		"groups": [
+			{"name": "developers", "display_name": "Developers", "members": ["alice", "testuser"]}
+		]
+	}`), 0o600))
+
+	// runImport returns the import status of the group.
+	runImport := func() string {
+		inv, conf := newCLI(t, "import", path, "-o", "json")
+		clitest.SetupConfig(t, client, conf)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		require.NoError(t, inv.Run())
+
+		var results []struct {
+			Kind   string `json:"kind"`
+			Status string `json:"status"`
+		}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+		require.Len(t, results, 2)
+		require.Equal(t, "group", results[1].Kind)
+		return results[1].Status
+	}
+
+	require.Equal(t, "created", runImport())
+	group, err := client.GroupByOrgAndName(ctx, owner.OrganizationID, "developers")
+	require.NoError(t, err)
+	require.Equal(t, "Developers", group.DisplayName)
+	require.Len(t, group.Members, 2)
+
+	// Importing again leaves the group as is.
+	require.Equal(t, "skipped", runImport())
+}
@@ -213,7 +213,11 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 				metrics = &m
 
 				closeFunc := agpl.ServeHandler(ctx, logger, promhttp.InstrumentMetricHandler(
-					prometheusRegistry, promhttp.HandlerFor(prometheusRegistry, promhttp.HandlerOpts{}),
+					prometheusRegistry, promhttp.HandlerFor(prometheusRegistry, promhttp.HandlerOpts{
+						// OpenMetrics is required to expose the trace exemplars
+						// of the provisioner job timings.
+						EnableOpenMetrics: true,
+					}),
 				), prometheusAddress, "prometheus")
 				defer closeFunc()
 			}
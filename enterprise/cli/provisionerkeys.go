@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"golang.org/x/xerrors"
 
@@ -25,6 +26,7 @@ func (r *RootCmd) provisionerKeys() *serpent.Command {
 			r.provisionerKeysCreate(),
 			r.provisionerKeysList(),
 			r.provisionerKeysDelete(),
+			r.provisionerKeysRotate(),
 		},
 	}
 
@@ -35,6 +37,7 @@ func (r *RootCmd) provisionerKeysCreate() *serpent.Command {
 	var (
 		orgContext = agpl.NewOrganizationContext()
 		rawTags    []string
+		expiresIn  time.Duration
 	)
 
 	client := new(codersdk.Client)
@@ -58,10 +61,16 @@ func (r *RootCmd) provisionerKeysCreate() *serpent.Command {
 				return err
 			}
 
-			res, err := client.CreateProvisionerKey(ctx, org.ID, codersdk.CreateProvisionerKeyRequest{
+			req := codersdk.CreateProvisionerKeyRequest{
 				Name: inv.Args[0],
 				Tags: tags,
-			})
+			}
+			if expiresIn > 0 {
+				expiresAt := time.Now().Add(expiresIn)
+				req.ExpiresAt = &expiresAt
+			}
+
+			res, err := client.CreateProvisionerKey(ctx, org.ID, req)
 			if err != nil {
 				return xerrors.Errorf("create provisioner key: %w", err)
 			}
@@ -85,6 +94,11 @@ func (r *RootCmd) provisionerKeysCreate() *serpent.Command {
 			Description:   "Tags to filter provisioner jobs by.",
 			Value:         serpent.StringArrayOf(&rawTags),
 		},
+		{
+			Flag:        "expires-in",
+			Description: "Duration after which the key can no longer be used. Keys do not expire by default.",
+			Value:       serpent.DurationOf(&expiresIn),
+		},
 	}
 	orgContext.AttachOptions(cmd)
 
@@ -189,3 +203,82 @@ func (r *RootCmd) provisionerKeysDelete() *serpent.Command {
 
 	return cmd
 }
+
+func (r *RootCmd) provisionerKeysRotate() *serpent.Command {
+	var (
+		orgContext = agpl.NewOrganizationContext()
+		overlap    time.Duration
+		expiresIn  time.Duration
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "rotate <name>",
+		Short: "Rotate the secret of a provisioner key",
+		Long: "The previous secret continues to be accepted for the duration of --overlap, " +
+			"giving time to roll the new secret out to all provisioner daemons using the key.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			org, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Are you sure you want to rotate provisioner key %s?", pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[0])),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			req := codersdk.RotateProvisionerKeyRequest{
+				OverlapMillis: overlap.Milliseconds(),
+			}
+			if expiresIn > 0 {
+				expiresAt := time.Now().Add(expiresIn)
+				req.ExpiresAt = &expiresAt
+			}
+
+			res, err := client.RotateProvisionerKey(ctx, org.ID, inv.Args[0], req)
+			if err != nil {
+				return xerrors.Errorf("rotate provisioner key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(
+				inv.Stdout,
+				"Successfully rotated provisioner key %s! Save this authentication token, it will not be shown again.\n\n%s\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, strings.ToLower(inv.Args[0])),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, res.Key),
+			)
+			if overlap > 0 {
+				_, _ = fmt.Fprintf(inv.Stdout, "\nThe previous secret remains valid for %s.\n", overlap)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "overlap",
+			Description: "How long the previous secret remains valid after rotation.",
+			Default:     "1h",
+			Value:       serpent.DurationOf(&overlap),
+		},
+		{
+			Flag:        "expires-in",
+			Description: "Duration after which the rotated key can no longer be used. Keys do not expire by default.",
+			Value:       serpent.DurationOf(&expiresIn),
+		},
+		cliui.SkipPromptOption(),
+	}
+	orgContext.AttachOptions(cmd)
+
+	return cmd
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/audit"
 	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/enterprise/auditexport"
 	"github.com/coder/coder/v2/enterprise/coderd"
 	"github.com/coder/coder/v2/enterprise/coderd/dormancy"
 	"github.com/coder/coder/v2/enterprise/dbcrypt"
@@ -115,6 +116,12 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 			o.ExternalTokenEncryption = cs
 		}
 
+		sinks, err := auditexport.NewSinks(ctx, options.DeploymentValues.AuditLogExport, options.HTTPClient)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("configure audit log export: %w", err)
+		}
+		o.AuditLogExportSinks = sinks
+
 		api, err := coderd.New(ctx, o)
 		if err != nil {
 			return nil, nil, err
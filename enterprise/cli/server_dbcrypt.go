@@ -201,6 +201,9 @@ func (*RootCmd) dbcryptDeleteCmd() *serpent.Command {
 			msg := `All encrypted data will be deleted from the database:
 - Encrypted user OAuth access and refresh tokens
 - Encrypted user Git authentication access and refresh tokens
+- Encrypted values of sensitive template version variables
+- Encrypted session tokens of active workspace migrations
+- Encrypted workspace session recordings
 
 Are you sure you want to continue?`
 			if _, err := cliui.Prompt(inv, cliui.PromptOptions{
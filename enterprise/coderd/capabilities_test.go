@@ -0,0 +1,32 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	adminClient, adminUser := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
+	coderdenttest.AddLicense(t, adminClient, coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureAppearance: 1,
+		},
+	})
+	memberClient, _ := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	capabilities, err := memberClient.Capabilities(ctx)
+	require.NoError(t, err)
+	require.True(t, capabilities.FeatureEnabled(codersdk.FeatureAppearance))
+	require.False(t, capabilities.FeatureEnabled(codersdk.FeatureTemplateRBAC))
+}
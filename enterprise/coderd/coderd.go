@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/ed25519"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"net/url"
@@ -44,6 +45,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	agplschedule "github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/auditexport"
 	"github.com/coder/coder/v2/enterprise/coderd/dbauthz"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
 	"github.com/coder/coder/v2/enterprise/coderd/prebuilds"
@@ -183,6 +185,8 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		OAuth2Configs:                 oauthConfigs,
 		RedirectToLogin:               false,
 		DisableSessionExpiryRefresh:   options.DeploymentValues.Sessions.DisableExpiryRefresh.Value(),
+		SessionTokenBinding:           codersdk.SessionTokenBinding(options.DeploymentValues.Sessions.TokenBinding),
+		ReadOnlyMode:                  api.AGPL.ReadOnlyMode,
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
@@ -192,6 +196,8 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		OAuth2Configs:                 oauthConfigs,
 		RedirectToLogin:               false,
 		DisableSessionExpiryRefresh:   options.DeploymentValues.Sessions.DisableExpiryRefresh.Value(),
+		SessionTokenBinding:           codersdk.SessionTokenBinding(options.DeploymentValues.Sessions.TokenBinding),
+		ReadOnlyMode:                  api.AGPL.ReadOnlyMode,
 		Optional:                      true,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
@@ -212,6 +218,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		r.Group(func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/regions", api.regions)
+			r.Get("/regions/{region}/failover", api.regionFailover)
 		})
 		r.Route("/replicas", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -222,6 +229,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Post("/refresh-entitlements", api.postRefreshEntitlements)
 			r.Post("/", api.postLicense)
 			r.Get("/", api.licenses)
+			r.Get("/usage-forecast", api.licenseUsageForecast)
 			r.Delete("/{id}", api.deleteLicense)
 		})
 		r.Route("/applications/reconnecting-pty-signed-token", func(r chi.Router) {
@@ -281,6 +289,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			)
 			r.Patch("/organizations/{organization}", api.patchOrganization)
 			r.Delete("/organizations/{organization}", api.deleteOrganization)
+			r.Get("/organizations/{organization}/shared-templates", api.templatesSharedWithOrganization)
 		})
 
 		r.Group(func(r chi.Router) {
@@ -304,6 +313,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 					r.Patch("/", api.patchOrganizationIDPSyncSettings)
 					r.Patch("/config", api.patchOrganizationIDPSyncConfig)
 					r.Patch("/mapping", api.patchOrganizationIDPSyncMapping)
+					r.Post("/dry-run", api.organizationIDPSyncDryRun)
 				})
 
 				r.Get("/available-fields", api.deploymentIDPSyncClaimFields)
@@ -321,6 +331,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Patch("/idpsync/groups", api.patchGroupIDPSyncSettings)
 				r.Patch("/idpsync/groups/config", api.patchGroupIDPSyncConfig)
 				r.Patch("/idpsync/groups/mapping", api.patchGroupIDPSyncMapping)
+				r.Post("/idpsync/groups/dry-run", api.groupIDPSyncDryRun)
 
 				r.Get("/idpsync/roles", api.roleIDPSyncSettings)
 				r.Patch("/idpsync/roles", api.patchRoleIDPSyncSettings)
@@ -384,6 +395,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 					httpmw.ExtractProvisionerKeyParam(options.Database),
 				)
 				r.Delete("/", api.deleteProvisionerKey)
+				r.Post("/rotate", api.rotateProvisionerKey)
 			})
 		})
 		// TODO: provisioner daemons are not scoped to organizations in the database, so placing them
@@ -421,6 +433,34 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Get("/", api.templateACL)
 			r.Patch("/", api.patchTemplateACL)
 		})
+		r.Route("/templates/{template}/shares", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.RequireFeatureMW(codersdk.FeatureMultipleOrganizations),
+				httpmw.ExtractTemplateParam(api.Database),
+			)
+			r.Get("/", api.templateShares)
+			r.With(httpmw.ExtractOrganizationParam(api.Database)).Put("/{organization}", api.putTemplateShare)
+			r.With(httpmw.ExtractOrganizationParam(api.Database)).Delete("/{organization}", api.deleteTemplateShare)
+		})
+		r.Route("/templates/{template}/prebuild-reservations", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.RequireFeatureMW(codersdk.FeatureWorkspacePrebuilds),
+				httpmw.ExtractTemplateParam(api.Database),
+			)
+			r.Get("/", api.templatePrebuildReservations)
+		})
+		r.Route("/workspaces/{workspace}/prebuild-reservation", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.RequireFeatureMW(codersdk.FeatureWorkspacePrebuilds),
+				httpmw.ExtractWorkspaceParam(api.Database),
+			)
+			r.Get("/", api.prebuildReservation)
+			r.Put("/", api.putPrebuildReservation)
+			r.Delete("/", api.deletePrebuildReservation)
+		})
 		r.Route("/groups", func(r chi.Router) {
 			r.Use(
 				api.templateRBACEnabledMW,
@@ -487,6 +527,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		api.AGPL.RootHandler.Route("/scim/v2", func(r chi.Router) {
 			r.Use(
 				api.RequireFeatureMW(codersdk.FeatureSCIM),
+				httpmw.RejectMutationsInReadOnlyMode(api.AGPL.ReadOnlyMode),
 			)
 			r.Get("/ServiceProviderConfig", api.scimServiceProviderConfig)
 			r.Post("/Users", api.scimPostUser)
@@ -568,6 +609,15 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	}
 	api.AGPL.WorkspaceProxiesFetchUpdater.Store(&fetchUpdater)
 
+	if len(options.AuditLogExportSinks) > 0 {
+		exportCfg := options.DeploymentValues.AuditLogExport
+		api.auditLogExporter, err = auditexport.New(ctx, options.Logger.Named("auditexport"), options.Database, quartz.NewReal(),
+			options.AuditLogExportSinks, exportCfg.Interval.Value(), int(exportCfg.BatchSize.Value()))
+		if err != nil {
+			return nil, xerrors.Errorf("start audit log export: %w", err)
+		}
+	}
+
 	err = api.PrometheusRegistry.Register(api.licenseMetricsCollector)
 	if err != nil {
 		return nil, xerrors.Errorf("unable to register license metrics collector")
@@ -610,6 +660,10 @@ type Options struct {
 	ProvisionerDaemonPSK string
 
 	CheckInactiveUsersCancelFunc func()
+
+	// AuditLogExportSinks receive every audit log. Audit logs are not
+	// exported if there are none.
+	AuditLogExportSinks []auditexport.Sink
 }
 
 type API struct {
@@ -632,6 +686,8 @@ type API struct {
 
 	licenseMetricsCollector *license.MetricsCollector
 	tailnetService          *tailnet.ClientService
+	// auditLogExporter streams audit logs to the AuditLogExportSinks.
+	auditLogExporter io.Closer
 }
 
 // writeEntitlementWarningsHeader writes the entitlement warnings to the response header
@@ -658,6 +714,9 @@ func (api *API) Close() error {
 	if api.derpMesh != nil {
 		_ = api.derpMesh.Close()
 	}
+	if api.auditLogExporter != nil {
+		_ = api.auditLogExporter.Close()
+	}
 
 	if api.Options.CheckInactiveUsersCancelFunc != nil {
 		api.Options.CheckInactiveUsersCancelFunc()
@@ -1132,6 +1191,10 @@ func (api *API) runEntitlementsLoop(ctx context.Context) {
 		b.Reset()
 		api.Logger.Debug(ctx, "synced licensed entitlements")
 
+		if err := api.notifyLicenseUserLimitForecast(ctx); err != nil && ctx.Err() == nil {
+			api.Logger.Warn(ctx, "failed to check license user limit forecast", slog.Error(err))
+		}
+
 		select {
 		case <-ctx.Done():
 			return
@@ -1158,6 +1221,7 @@ func (api *API) setupPrebuilds(featureEnabled bool) (agplprebuilds.Reconciliatio
 	}
 
 	reconciler := prebuilds.NewStoreReconciler(api.Database, api.Pubsub, api.AGPL.FileCache, api.DeploymentValues.Prebuilds,
-		api.Logger.Named("prebuilds"), quartz.NewReal(), api.PrometheusRegistry, api.NotificationsEnqueuer)
+		api.Logger.Named("prebuilds"), quartz.NewReal(), api.PrometheusRegistry, api.NotificationsEnqueuer).
+		WithReadOnlyMode(api.AGPL.ReadOnlyMode)
 	return reconciler, prebuilds.NewEnterpriseClaimer(api.Database)
 }
@@ -63,6 +63,8 @@ func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request)
 		AvatarURL:      req.AvatarURL,
 		// #nosec G115 - Quota allowance is small and fits in int32
 		QuotaAllowance: int32(req.QuotaAllowance),
+		// #nosec G115 - Monthly budget is small and fits in int32
+		MonthlyBudget: int32(req.MonthlyBudget),
 	})
 	if database.IsUniqueViolation(err) {
 		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
@@ -219,6 +221,7 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 			Name:           group.Name,
 			DisplayName:    group.DisplayName,
 			QuotaAllowance: group.QuotaAllowance,
+			MonthlyBudget:  group.MonthlyBudget,
 		}
 
 		// TODO: Do we care about validating this?
@@ -232,6 +235,10 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 			// #nosec G115 - Quota allowance is small and fits in int32
 			updateGroupParams.QuotaAllowance = int32(*req.QuotaAllowance)
 		}
+		if req.MonthlyBudget != nil {
+			// #nosec G115 - Monthly budget is small and fits in int32
+			updateGroupParams.MonthlyBudget = int32(*req.MonthlyBudget)
+		}
 		if req.DisplayName != nil {
 			updateGroupParams.DisplayName = *req.DisplayName
 		}
@@ -9,12 +9,14 @@ import (
 
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/idpsync"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -94,6 +96,14 @@ func (api *API) patchGroupIDPSyncSettings(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if validations := validateIDPSyncMappingRules(req.MappingRules); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid mapping rules.",
+			Validations: validations,
+		})
+		return
+	}
+
 	//nolint:gocritic // Requires system context to update runtime config
 	sysCtx := dbauthz.AsSystemRestricted(ctx)
 	existing, err := api.IDPSync.GroupSyncSettings(sysCtx, org.ID, api.Database)
@@ -104,11 +114,13 @@ func (api *API) patchGroupIDPSyncSettings(rw http.ResponseWriter, r *http.Reques
 	aReq.Old = *existing
 
 	err = api.IDPSync.UpdateGroupSyncSettings(sysCtx, org.ID, api.Database, idpsync.GroupSyncSettings{
-		Field:             req.Field,
-		Mapping:           req.Mapping,
-		RegexFilter:       req.RegexFilter,
-		AutoCreateMissing: req.AutoCreateMissing,
-		LegacyNameMapping: req.LegacyNameMapping,
+		Field:                req.Field,
+		Mapping:              req.Mapping,
+		MappingRules:         req.MappingRules,
+		NestedGroupSeparator: req.NestedGroupSeparator,
+		RegexFilter:          req.RegexFilter,
+		AutoCreateMissing:    req.AutoCreateMissing,
+		LegacyNameMapping:    req.LegacyNameMapping,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -123,11 +135,13 @@ func (api *API) patchGroupIDPSyncSettings(rw http.ResponseWriter, r *http.Reques
 
 	aReq.New = *settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.GroupSyncSettings{
-		Field:             settings.Field,
-		Mapping:           settings.Mapping,
-		RegexFilter:       settings.RegexFilter,
-		AutoCreateMissing: settings.AutoCreateMissing,
-		LegacyNameMapping: settings.LegacyNameMapping,
+		Field:                settings.Field,
+		Mapping:              settings.Mapping,
+		MappingRules:         settings.MappingRules,
+		NestedGroupSeparator: settings.NestedGroupSeparator,
+		RegexFilter:          settings.RegexFilter,
+		AutoCreateMissing:    settings.AutoCreateMissing,
+		LegacyNameMapping:    settings.LegacyNameMapping,
 	})
 }
 
@@ -175,11 +189,13 @@ func (api *API) patchGroupIDPSyncConfig(rw http.ResponseWriter, r *http.Request)
 		aReq.Old = *existing
 
 		settings = idpsync.GroupSyncSettings{
-			Field:             req.Field,
-			RegexFilter:       req.RegexFilter,
-			AutoCreateMissing: req.AutoCreateMissing,
-			LegacyNameMapping: existing.LegacyNameMapping,
-			Mapping:           existing.Mapping,
+			Field:                req.Field,
+			RegexFilter:          req.RegexFilter,
+			AutoCreateMissing:    req.AutoCreateMissing,
+			LegacyNameMapping:    existing.LegacyNameMapping,
+			Mapping:              existing.Mapping,
+			MappingRules:         existing.MappingRules,
+			NestedGroupSeparator: existing.NestedGroupSeparator,
 		}
 
 		err = api.IDPSync.UpdateGroupSyncSettings(sysCtx, org.ID, tx, settings)
@@ -196,11 +212,13 @@ func (api *API) patchGroupIDPSyncConfig(rw http.ResponseWriter, r *http.Request)
 
 	aReq.New = settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.GroupSyncSettings{
-		Field:             settings.Field,
-		RegexFilter:       settings.RegexFilter,
-		AutoCreateMissing: settings.AutoCreateMissing,
-		LegacyNameMapping: settings.LegacyNameMapping,
-		Mapping:           settings.Mapping,
+		Field:                settings.Field,
+		RegexFilter:          settings.RegexFilter,
+		AutoCreateMissing:    settings.AutoCreateMissing,
+		LegacyNameMapping:    settings.LegacyNameMapping,
+		Mapping:              settings.Mapping,
+		MappingRules:         settings.MappingRules,
+		NestedGroupSeparator: settings.NestedGroupSeparator,
 	})
 }
 
@@ -249,11 +267,13 @@ func (api *API) patchGroupIDPSyncMapping(rw http.ResponseWriter, r *http.Request
 
 		newMapping := applyIDPSyncMappingDiff(existing.Mapping, req.Add, req.Remove)
 		settings = idpsync.GroupSyncSettings{
-			Field:             existing.Field,
-			RegexFilter:       existing.RegexFilter,
-			AutoCreateMissing: existing.AutoCreateMissing,
-			LegacyNameMapping: existing.LegacyNameMapping,
-			Mapping:           newMapping,
+			Field:                existing.Field,
+			RegexFilter:          existing.RegexFilter,
+			AutoCreateMissing:    existing.AutoCreateMissing,
+			LegacyNameMapping:    existing.LegacyNameMapping,
+			Mapping:              newMapping,
+			MappingRules:         existing.MappingRules,
+			NestedGroupSeparator: existing.NestedGroupSeparator,
 		}
 
 		err = api.IDPSync.UpdateGroupSyncSettings(sysCtx, org.ID, tx, settings)
@@ -270,14 +290,154 @@ func (api *API) patchGroupIDPSyncMapping(rw http.ResponseWriter, r *http.Request
 
 	aReq.New = settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.GroupSyncSettings{
-		Field:             settings.Field,
-		RegexFilter:       settings.RegexFilter,
-		AutoCreateMissing: settings.AutoCreateMissing,
-		LegacyNameMapping: settings.LegacyNameMapping,
-		Mapping:           settings.Mapping,
+		Field:                settings.Field,
+		RegexFilter:          settings.RegexFilter,
+		AutoCreateMissing:    settings.AutoCreateMissing,
+		LegacyNameMapping:    settings.LegacyNameMapping,
+		Mapping:              settings.Mapping,
+		MappingRules:         settings.MappingRules,
+		NestedGroupSeparator: settings.NestedGroupSeparator,
 	})
 }
 
+// @Summary Preview group IdP Sync settings
+// @ID preview-group-idp-sync-settings
+// @Description Returns the group membership changes the settings would make,
+// @Description based on the claims OIDC users last logged in with. The
+// @Description settings are not saved.
+// @Security CoderSessionToken
+// @Produce json
+// @Accept json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.GroupSyncSettings true "Settings to preview"
+// @Success 200 {object} codersdk.IDPSyncDryRunResponse
+// @Router /organizations/{organization}/settings/idpsync/groups/dry-run [post]
+func (api *API) groupIDPSyncDryRun(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceIdpsyncSettings.InOrg(org.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.GroupSyncSettings
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if validations := validateIDPSyncMappingRules(req.MappingRules); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid mapping rules.",
+			Validations: validations,
+		})
+		return
+	}
+
+	resp := codersdk.IDPSyncDryRunResponse{Users: []codersdk.IDPSyncDryRunUser{}}
+	settings := idpsync.GroupSyncSettings(req)
+	if settings.Field == "" {
+		// Group sync is disabled, so no memberships change.
+		httpapi.Write(ctx, rw, http.StatusOK, resp)
+		return
+	}
+
+	//nolint:gocritic // Requires system context to read the claims and groups of all members.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	users, err := api.Database.GetOIDCUserClaims(sysCtx, org.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	groups, err := api.Database.GetGroups(sysCtx, database.GetGroupsParams{
+		OrganizationID: org.ID,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	members, err := api.Database.GetGroupMembers(sysCtx, false)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	groupsByID := make(map[uuid.UUID]database.Group, len(groups))
+	groupsByName := make(map[string]database.Group, len(groups))
+	for _, g := range groups {
+		groupsByID[g.Group.ID] = g.Group
+		groupsByName[g.Group.Name] = g.Group
+	}
+	existing := make(map[uuid.UUID][]idpsync.ExpectedGroup)
+	for _, m := range members {
+		if m.OrganizationID != org.ID {
+			continue
+		}
+		existing[m.UserID] = append(existing[m.UserID], idpsync.ExpectedGroup{
+			OrganizationID: org.ID,
+			GroupID:        ptr.Ref(m.GroupID),
+			GroupName:      ptr.Ref(m.GroupName),
+		})
+	}
+
+	for _, user := range users {
+		add, remove, err := settings.Difference(org.ID, user.Claims.MergedClaims, existing[user.UserID])
+		if err != nil {
+			// Group sync skips users whose claims cannot be parsed.
+			continue
+		}
+
+		change := codersdk.IDPSyncDryRunUser{
+			UserID:   user.UserID,
+			Username: user.Username,
+			Add:      []codersdk.IDPSyncDryRunMembership{},
+			Remove:   []codersdk.IDPSyncDryRunMembership{},
+		}
+		seen := make(map[string]struct{})
+		for _, expected := range add {
+			var membership codersdk.IDPSyncDryRunMembership
+			switch {
+			case expected.GroupID != nil:
+				group, ok := groupsByID[*expected.GroupID]
+				if !ok {
+					// Mappings to groups that do not exist are ignored.
+					continue
+				}
+				membership = codersdk.IDPSyncDryRunMembership{ID: ptr.Ref(group.ID), Name: group.Name}
+			case expected.GroupName != nil:
+				group, ok := groupsByName[*expected.GroupName]
+				if ok {
+					membership = codersdk.IDPSyncDryRunMembership{ID: ptr.Ref(group.ID), Name: group.Name}
+				} else if settings.AutoCreateMissing {
+					membership = codersdk.IDPSyncDryRunMembership{Name: *expected.GroupName}
+				} else {
+					continue
+				}
+			default:
+				continue
+			}
+			if _, ok := seen[membership.Name]; ok {
+				continue
+			}
+			seen[membership.Name] = struct{}{}
+			change.Add = append(change.Add, membership)
+		}
+		for _, expected := range remove {
+			change.Remove = append(change.Remove, codersdk.IDPSyncDryRunMembership{
+				ID:   expected.GroupID,
+				Name: ptr.NilToEmpty(expected.GroupName),
+			})
+		}
+
+		if len(change.Add) > 0 || len(change.Remove) > 0 {
+			resp.Users = append(resp.Users, change)
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
 // @Summary Get role IdP Sync settings by organization
 // @ID get-role-idp-sync-settings-by-organization
 // @Security CoderSessionToken
@@ -530,9 +690,11 @@ func (api *API) organizationIDPSyncSettings(rw http.ResponseWriter, r *http.Requ
 	}
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OrganizationSyncSettings{
-		Field:         settings.Field,
-		Mapping:       settings.Mapping,
-		AssignDefault: settings.AssignDefault,
+		Field:                settings.Field,
+		Mapping:              settings.Mapping,
+		MappingRules:         settings.MappingRules,
+		NestedGroupSeparator: settings.NestedGroupSeparator,
+		AssignDefault:        settings.AssignDefault,
 	})
 }
 
@@ -566,6 +728,14 @@ func (api *API) patchOrganizationIDPSyncSettings(rw http.ResponseWriter, r *http
 		return
 	}
 
+	if validations := validateIDPSyncMappingRules(req.MappingRules); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid mapping rules.",
+			Validations: validations,
+		})
+		return
+	}
+
 	//nolint:gocritic // Requires system context to update runtime config
 	sysCtx := dbauthz.AsSystemRestricted(ctx)
 	existing, err := api.IDPSync.OrganizationSyncSettings(sysCtx, api.Database)
@@ -578,8 +748,10 @@ func (api *API) patchOrganizationIDPSyncSettings(rw http.ResponseWriter, r *http
 	err = api.IDPSync.UpdateOrganizationSyncSettings(sysCtx, api.Database, idpsync.OrganizationSyncSettings{
 		Field: req.Field,
 		// We do not check if the mappings point to actual organizations.
-		Mapping:       req.Mapping,
-		AssignDefault: req.AssignDefault,
+		Mapping:              req.Mapping,
+		MappingRules:         req.MappingRules,
+		NestedGroupSeparator: req.NestedGroupSeparator,
+		AssignDefault:        req.AssignDefault,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -594,9 +766,11 @@ func (api *API) patchOrganizationIDPSyncSettings(rw http.ResponseWriter, r *http
 
 	aReq.New = *settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OrganizationSyncSettings{
-		Field:         settings.Field,
-		Mapping:       settings.Mapping,
-		AssignDefault: settings.AssignDefault,
+		Field:                settings.Field,
+		Mapping:              settings.Mapping,
+		MappingRules:         settings.MappingRules,
+		NestedGroupSeparator: settings.NestedGroupSeparator,
+		AssignDefault:        settings.AssignDefault,
 	})
 }
 
@@ -641,9 +815,11 @@ func (api *API) patchOrganizationIDPSyncConfig(rw http.ResponseWriter, r *http.R
 		aReq.Old = *existing
 
 		settings = idpsync.OrganizationSyncSettings{
-			Field:         req.Field,
-			AssignDefault: req.AssignDefault,
-			Mapping:       existing.Mapping,
+			Field:                req.Field,
+			AssignDefault:        req.AssignDefault,
+			Mapping:              existing.Mapping,
+			MappingRules:         existing.MappingRules,
+			NestedGroupSeparator: existing.NestedGroupSeparator,
 		}
 
 		err = api.IDPSync.UpdateOrganizationSyncSettings(sysCtx, tx, settings)
@@ -660,9 +836,11 @@ func (api *API) patchOrganizationIDPSyncConfig(rw http.ResponseWriter, r *http.R
 
 	aReq.New = settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OrganizationSyncSettings{
-		Field:         settings.Field,
-		Mapping:       settings.Mapping,
-		AssignDefault: settings.AssignDefault,
+		Field:                settings.Field,
+		Mapping:              settings.Mapping,
+		MappingRules:         settings.MappingRules,
+		NestedGroupSeparator: settings.NestedGroupSeparator,
+		AssignDefault:        settings.AssignDefault,
 	})
 }
 
@@ -708,9 +886,11 @@ func (api *API) patchOrganizationIDPSyncMapping(rw http.ResponseWriter, r *http.
 
 		newMapping := applyIDPSyncMappingDiff(existing.Mapping, req.Add, req.Remove)
 		settings = idpsync.OrganizationSyncSettings{
-			Field:         existing.Field,
-			Mapping:       newMapping,
-			AssignDefault: existing.AssignDefault,
+			Field:                existing.Field,
+			Mapping:              newMapping,
+			MappingRules:         existing.MappingRules,
+			NestedGroupSeparator: existing.NestedGroupSeparator,
+			AssignDefault:        existing.AssignDefault,
 		}
 
 		err = api.IDPSync.UpdateOrganizationSyncSettings(sysCtx, tx, settings)
@@ -727,10 +907,133 @@ func (api *API) patchOrganizationIDPSyncMapping(rw http.ResponseWriter, r *http.
 
 	aReq.New = settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OrganizationSyncSettings{
-		Field:         settings.Field,
-		Mapping:       settings.Mapping,
-		AssignDefault: settings.AssignDefault,
+		Field:                settings.Field,
+		Mapping:              settings.Mapping,
+		MappingRules:         settings.MappingRules,
+		NestedGroupSeparator: settings.NestedGroupSeparator,
+		AssignDefault:        settings.AssignDefault,
+	})
+}
+
+// @Summary Preview organization IdP Sync settings
+// @ID preview-organization-idp-sync-settings
+// @Description Returns the organization membership changes the settings
+// @Description would make, based on the claims OIDC users last logged in
+// @Description with. The settings are not saved.
+// @Security CoderSessionToken
+// @Produce json
+// @Accept json
+// @Tags Enterprise
+// @Param request body codersdk.OrganizationSyncSettings true "Settings to preview"
+// @Success 200 {object} codersdk.IDPSyncDryRunResponse
+// @Router /settings/idpsync/organization/dry-run [post]
+func (api *API) organizationIDPSyncDryRun(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceIdpsyncSettings) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.OrganizationSyncSettings
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if validations := validateIDPSyncMappingRules(req.MappingRules); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid mapping rules.",
+			Validations: validations,
+		})
+		return
+	}
+
+	resp := codersdk.IDPSyncDryRunResponse{Users: []codersdk.IDPSyncDryRunUser{}}
+	settings := idpsync.OrganizationSyncSettings{
+		Field:                req.Field,
+		Mapping:              req.Mapping,
+		MappingRules:         req.MappingRules,
+		NestedGroupSeparator: req.NestedGroupSeparator,
+		AssignDefault:        req.AssignDefault,
+	}
+	if settings.Field == "" {
+		// Organization sync is disabled, so no memberships change.
+		httpapi.Write(ctx, rw, http.StatusOK, resp)
+		return
+	}
+
+	//nolint:gocritic // Requires system context to read the claims and organizations of all users.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	users, err := api.Database.GetOIDCUserClaims(sysCtx, uuid.Nil)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	organizations, err := api.Database.GetOrganizations(sysCtx, database.GetOrganizationsParams{})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	// Users are removed from deleted organizations, so their names are
+	// needed as well.
+	deletedOrganizations, err := api.Database.GetOrganizations(sysCtx, database.GetOrganizationsParams{
+		Deleted: true,
 	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	memberships, err := api.Database.GetOrganizationIDsByMemberIDs(sysCtx, db2sdk.List(users, func(u database.GetOIDCUserClaimsRow) uuid.UUID {
+		return u.UserID
+	}))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	organizationNames := make(map[uuid.UUID]string, len(organizations)+len(deletedOrganizations))
+	for _, o := range deletedOrganizations {
+		organizationNames[o.ID] = o.Name
+	}
+	activeOrganizations := make(map[uuid.UUID]struct{}, len(organizations))
+	for _, o := range organizations {
+		organizationNames[o.ID] = o.Name
+		activeOrganizations[o.ID] = struct{}{}
+	}
+	existing := make(map[uuid.UUID][]uuid.UUID, len(memberships))
+	for _, m := range memberships {
+		existing[m.UserID] = m.OrganizationIDs
+	}
+
+	for _, user := range users {
+		expected, err := settings.ParseClaims(sysCtx, api.Database, user.Claims.MergedClaims)
+		if err != nil {
+			// Organization sync fails the login of users whose claims cannot
+			// be parsed, so their memberships do not change.
+			continue
+		}
+		// Organizations that do not exist or are deleted are ignored.
+		expected = slices.DeleteFunc(expected, func(id uuid.UUID) bool {
+			_, ok := activeOrganizations[id]
+			return !ok
+		})
+
+		add, remove := slice.SymmetricDifference(existing[user.UserID], expected)
+		if len(add) == 0 && len(remove) == 0 {
+			continue
+		}
+		toMembership := func(id uuid.UUID) codersdk.IDPSyncDryRunMembership {
+			return codersdk.IDPSyncDryRunMembership{ID: ptr.Ref(id), Name: organizationNames[id]}
+		}
+		resp.Users = append(resp.Users, codersdk.IDPSyncDryRunUser{
+			UserID:   user.UserID,
+			Username: user.Username,
+			Add:      db2sdk.List(add, toMembership),
+			Remove:   db2sdk.List(remove, toMembership),
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
 // @Summary Get the available organization idp sync claim fields
@@ -870,3 +1173,25 @@ func applyIDPSyncMappingDiff[IDType uuid.UUID | string](
 
 	return next
 }
+
+// validateIDPSyncMappingRules returns a validation error for each invalid
+// mapping rule.
+func validateIDPSyncMappingRules(rules []codersdk.IDPSyncMappingRule) []codersdk.ValidationError {
+	var validations []codersdk.ValidationError
+	for i, rule := range rules {
+		field := fmt.Sprintf("mapping_rules[%d]", i)
+		if (rule.Prefix == "") == (rule.Regex == nil) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  field,
+				Detail: "exactly one of prefix or regex must be set",
+			})
+		}
+		if len(rule.Gets) == 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  field + ".gets",
+				Detail: "at least one ID is required",
+			})
+		}
+	}
+	return validations
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/idpsync"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/runtimeconfig"
@@ -775,3 +777,138 @@ func TestPatchOrganizationSyncMapping(t *testing.T) {
 		require.Equal(t, http.StatusForbidden, apiError.StatusCode())
 	})
 }
+
+func TestGroupIDPSyncDryRun(t *testing.T) {
+	t.Parallel()
+
+	owner, db, user := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureCustomRoles:           1,
+				codersdk.FeatureMultipleOrganizations: 1,
+			},
+		},
+	})
+	orgAdmin, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.ScopedRoleOrgAdmin(user.OrganizationID))
+
+	oidcUser := dbgen.User(t, db, database.User{LoginType: database.LoginTypeOIDC})
+	dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: oidcUser.ID, OrganizationID: user.OrganizationID})
+	dbgen.UserLink(t, db, database.UserLink{
+		UserID:    oidcUser.ID,
+		LoginType: database.LoginTypeOIDC,
+		Claims: database.UserLinkClaims{
+			MergedClaims: map[string]interface{}{
+				"groups": []interface{}{"eng/backend", "team-web"},
+			},
+		},
+	})
+	eng := dbgen.Group(t, db, database.Group{OrganizationID: user.OrganizationID, Name: "eng"})
+	web := dbgen.Group(t, db, database.Group{OrganizationID: user.OrganizationID, Name: "web"})
+	old := dbgen.Group(t, db, database.Group{OrganizationID: user.OrganizationID, Name: "old"})
+	dbgen.GroupMember(t, db, database.GroupMemberTable{UserID: oidcUser.ID, GroupID: old.ID})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		preview, err := orgAdmin.GroupIDPSyncDryRun(ctx, user.OrganizationID.String(), codersdk.GroupSyncSettings{
+			Field:                "groups",
+			NestedGroupSeparator: "/",
+			Mapping: map[string][]uuid.UUID{
+				"eng": {eng.ID},
+			},
+			MappingRules: []codersdk.IDPSyncMappingRule{
+				{Prefix: "team-", Gets: []uuid.UUID{web.ID}},
+			},
+			AutoCreateMissing: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, preview.Users, 1)
+		require.Equal(t, oidcUser.ID, preview.Users[0].UserID)
+		require.ElementsMatch(t, []codersdk.IDPSyncDryRunMembership{
+			{ID: &eng.ID, Name: "eng"},
+			{ID: &web.ID, Name: "web"},
+			// Missing groups have no ID, as they would be created.
+			{Name: "eng/backend"},
+		}, preview.Users[0].Add)
+		require.Equal(t, []codersdk.IDPSyncDryRunMembership{
+			{ID: &old.ID, Name: "old"},
+		}, preview.Users[0].Remove)
+
+		// The settings are not saved.
+		settings, err := orgAdmin.GroupIDPSyncSettings(ctx, user.OrganizationID.String())
+		require.NoError(t, err)
+		require.Empty(t, settings.Field)
+	})
+
+	t.Run("InvalidMappingRule", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := orgAdmin.GroupIDPSyncDryRun(ctx, user.OrganizationID.String(), codersdk.GroupSyncSettings{
+			Field: "groups",
+			MappingRules: []codersdk.IDPSyncMappingRule{
+				{Prefix: "team-", Regex: regexp.MustCompile("^team-"), Gets: []uuid.UUID{web.ID}},
+			},
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+	})
+
+	t.Run("NotAuthorized", func(t *testing.T) {
+		t.Parallel()
+
+		member, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.GroupIDPSyncDryRun(ctx, user.OrganizationID.String(), codersdk.GroupSyncSettings{})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusForbidden, apiError.StatusCode())
+	})
+}
+
+func TestOrganizationIDPSyncDryRun(t *testing.T) {
+	t.Parallel()
+
+	owner, db, user := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureCustomRoles:           1,
+				codersdk.FeatureMultipleOrganizations: 1,
+			},
+		},
+	})
+
+	oidcUser := dbgen.User(t, db, database.User{LoginType: database.LoginTypeOIDC})
+	dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: oidcUser.ID, OrganizationID: user.OrganizationID})
+	dbgen.UserLink(t, db, database.UserLink{
+		UserID:    oidcUser.ID,
+		LoginType: database.LoginTypeOIDC,
+		Claims: database.UserLinkClaims{
+			MergedClaims: map[string]interface{}{
+				"organizations": []interface{}{"eng/backend"},
+			},
+		},
+	})
+	eng := dbgen.Organization(t, db, database.Organization{Name: "engineering"})
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	//nolint:gocritic // Only owners can preview organization IdP sync settings
+	preview, err := owner.OrganizationIDPSyncDryRun(ctx, codersdk.OrganizationSyncSettings{
+		Field:                "organizations",
+		NestedGroupSeparator: "/",
+		MappingRules: []codersdk.IDPSyncMappingRule{
+			{Regex: regexp.MustCompile("^eng$"), Gets: []uuid.UUID{eng.ID}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, preview.Users, 1)
+	require.Equal(t, oidcUser.ID, preview.Users[0].UserID)
+	require.Equal(t, []codersdk.IDPSyncDryRunMembership{
+		{ID: &eng.ID, Name: "engineering"},
+	}, preview.Users[0].Add)
+	require.Len(t, preview.Users[0].Remove, 1)
+	require.Equal(t, user.OrganizationID, *preview.Users[0].Remove[0].ID)
+}
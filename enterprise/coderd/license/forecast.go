@@ -0,0 +1,94 @@
+package license
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// UsageHistory returns the number of active users at the start of each of the
+// trailing days, oldest first.
+func UsageHistory(ctx context.Context, db database.Store, now time.Time, trailingDays int) ([]codersdk.LicenseUsageDataPoint, error) {
+	// nolint:gocritic // Counting active users is a system function.
+	rows, err := db.GetUserStatusCounts(dbauthz.AsSystemRestricted(ctx), database.GetUserStatusCountsParams{
+		StartTime: dbtime.StartOfDay(now).AddDate(0, 0, -trailingDays),
+		EndTime:   now,
+		Interval:  int32((24 * time.Hour).Seconds()),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get user status counts: %w", err)
+	}
+
+	history := make([]codersdk.LicenseUsageDataPoint, 0, trailingDays+1)
+	for _, row := range rows {
+		if row.Status != database.UserStatusActive {
+			continue
+		}
+		history = append(history, codersdk.LicenseUsageDataPoint{
+			Date:        row.Date,
+			ActiveUsers: row.Count,
+		})
+	}
+	return history, nil
+}
+
+// ForecastUsage projects when the active users reach the user limit, assuming
+// they keep growing at the average rate of the history. The user limit is nil
+// when the license does not limit active users.
+func ForecastUsage(now time.Time, activeUsers int64, userLimit *int64, history []codersdk.LicenseUsageDataPoint) codersdk.LicenseUsageForecast {
+	forecast := codersdk.LicenseUsageForecast{
+		ActiveUsers: activeUsers,
+		UserLimit:   userLimit,
+		DailyGrowth: dailyGrowth(history),
+		History:     history,
+	}
+	if userLimit == nil {
+		return forecast
+	}
+
+	remaining := *userLimit - activeUsers
+	var days float64
+	switch {
+	case remaining <= 0:
+	case forecast.DailyGrowth > 0:
+		days = float64(remaining) / forecast.DailyGrowth
+	default:
+		// The limit is never reached if active users are not growing.
+		return forecast
+	}
+	daysUntilLimit := int64(days)
+	projected := now.Add(time.Duration(days * float64(24*time.Hour)))
+	forecast.DaysUntilLimit = &daysUntilLimit
+	forecast.ProjectedLimitDate = &projected
+	return forecast
+}
+
+// dailyGrowth returns the slope of the least squares fit of active users over
+// time, in users per day.
+func dailyGrowth(history []codersdk.LicenseUsageDataPoint) float64 {
+	if len(history) < 2 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, point := range history {
+		x := point.Date.Sub(history[0].Date).Hours() / 24
+		y := float64(point.ActiveUsers)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(history))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
@@ -889,3 +889,52 @@ func assertEnterpriseFeatures(t *testing.T, entitlements codersdk.Entitlements)
 		assert.Equalf(t, true, f.Enabled, "%s enabled", expected)
 	}
 }
+
+func TestForecastUsage(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	history := []codersdk.LicenseUsageDataPoint{
+		{Date: now.AddDate(0, 0, -3), ActiveUsers: 8},
+		{Date: now.AddDate(0, 0, -2), ActiveUsers: 10},
+		{Date: now.AddDate(0, 0, -1), ActiveUsers: 12},
+		{Date: now, ActiveUsers: 14},
+	}
+	ptr := func(v int64) *int64 { return &v }
+
+	t.Run("Growing", func(t *testing.T) {
+		t.Parallel()
+		forecast := license.ForecastUsage(now, 14, ptr(20), history)
+		require.InDelta(t, 2, forecast.DailyGrowth, 0.001)
+		require.NotNil(t, forecast.DaysUntilLimit)
+		require.EqualValues(t, 3, *forecast.DaysUntilLimit)
+		require.Equal(t, now.AddDate(0, 0, 3), *forecast.ProjectedLimitDate)
+	})
+
+	t.Run("LimitReached", func(t *testing.T) {
+		t.Parallel()
+		forecast := license.ForecastUsage(now, 20, ptr(20), history)
+		require.NotNil(t, forecast.DaysUntilLimit)
+		require.EqualValues(t, 0, *forecast.DaysUntilLimit)
+		require.Equal(t, now, *forecast.ProjectedLimitDate)
+	})
+
+	t.Run("NotGrowing", func(t *testing.T) {
+		t.Parallel()
+		forecast := license.ForecastUsage(now, 14, ptr(20), []codersdk.LicenseUsageDataPoint{
+			{Date: now.AddDate(0, 0, -1), ActiveUsers: 15},
+			{Date: now, ActiveUsers: 14},
+		})
+		require.Negative(t, forecast.DailyGrowth)
+		require.Nil(t, forecast.DaysUntilLimit)
+		require.Nil(t, forecast.ProjectedLimitDate)
+	})
+
+	t.Run("NoUserLimit", func(t *testing.T) {
+		t.Parallel()
+		forecast := license.ForecastUsage(now, 14, nil, history)
+		require.InDelta(t, 2, forecast.DailyGrowth, 0.001)
+		require.Nil(t, forecast.UserLimit)
+		require.Nil(t, forecast.DaysUntilLimit)
+	})
+}
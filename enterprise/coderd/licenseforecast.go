@@ -0,0 +1,181 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+)
+
+const (
+	// licenseUsageTrailingDays is the number of trailing days the active user
+	// trend is computed from by default.
+	licenseUsageTrailingDays = 30
+	// licenseUserLimitNotifiedKey is the runtime config key recording that
+	// owners were notified of the projected user limit. Owners are notified
+	// again only after the projection moves past the threshold.
+	licenseUserLimitNotifiedKey = "license-user-limit-notified"
+)
+
+// @Summary Get license usage forecast
+// @Description Projects when the active users of the deployment reach the
+// @Description user limit of the license, from the trend of active users over
+// @Description the trailing days.
+// @ID get-license-usage-forecast
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param trailing_days query int false "Number of trailing days to compute the trend from, defaults to 30"
+// @Success 200 {object} codersdk.LicenseUsageForecast
+// @Router /licenses/usage-forecast [get]
+func (api *API) licenseUsageForecast(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.AGPL.Authorize(r, policy.ActionRead, rbac.ResourceLicense) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	trailingDays := p.PositiveInt32(vals, licenseUsageTrailingDays, "trailing_days")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) == 0 && (trailingDays < 2 || trailingDays > 365) {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "trailing_days",
+			Detail: "Must be between 2 and 365.",
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	forecast, err := api.forecastLicenseUsage(ctx, int(trailingDays))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error forecasting license usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, forecast)
+}
+
+func (api *API) forecastLicenseUsage(ctx context.Context, trailingDays int) (codersdk.LicenseUsageForecast, error) {
+	now := dbtime.Time(api.Clock.Now())
+	history, err := license.UsageHistory(ctx, api.Database, now, trailingDays)
+	if err != nil {
+		return codersdk.LicenseUsageForecast{}, err
+	}
+	// nolint:gocritic // Counting active users is a system function.
+	activeUsers, err := api.Database.GetActiveUserCount(dbauthz.AsSystemRestricted(ctx), false)
+	if err != nil {
+		return codersdk.LicenseUsageForecast{}, xerrors.Errorf("get active user count: %w", err)
+	}
+	var userLimit *int64
+	if feature, ok := api.Entitlements.Feature(codersdk.FeatureUserLimit); ok && feature.Enabled {
+		userLimit = feature.Limit
+	}
+	forecast := license.ForecastUsage(now, activeUsers, userLimit, history)
+	forecast.TrailingDays = int64(trailingDays)
+	return forecast, nil
+}
+
+// notifyLicenseUserLimitForecast notifies owners once the active users are
+// projected to reach the user limit of the license within the configured
+// number of days.
+func (api *API) notifyLicenseUserLimitForecast(ctx context.Context) error {
+	threshold := api.DeploymentValues.Notifications.LicenseUserLimitThresholdDays.Value()
+	if threshold <= 0 {
+		return nil
+	}
+
+	forecast, err := api.forecastLicenseUsage(ctx, licenseUsageTrailingDays)
+	if err != nil {
+		return xerrors.Errorf("forecast license usage: %w", err)
+	}
+	approaching := forecast.DaysUntilLimit != nil && *forecast.DaysUntilLimit <= threshold
+
+	//nolint:gocritic // The system notifies owners of the license usage.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	var owners []database.GetUsersRow
+	err = api.Database.InTx(func(tx database.Store) error {
+		// Every replica checks the forecast, the lock ensures only one of
+		// them notifies owners.
+		ok, err := tx.TryAcquireLock(ctx, database.LockIDLicenseUserLimitNotification)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		_, err = tx.GetRuntimeConfig(ctx, licenseUserLimitNotifiedKey)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get runtime config: %w", err)
+		}
+		notified := err == nil
+
+		switch {
+		case approaching && !notified:
+			err = tx.UpsertRuntimeConfig(ctx, database.UpsertRuntimeConfigParams{
+				Key:   licenseUserLimitNotifiedKey,
+				Value: dbtime.Time(api.Clock.Now()).Format(time.RFC3339),
+			})
+			if err != nil {
+				return xerrors.Errorf("upsert runtime config: %w", err)
+			}
+			owners, err = tx.GetUsers(ctx, database.GetUsersParams{
+				RbacRole: []string{codersdk.RoleOwner},
+			})
+			if err != nil {
+				return xerrors.Errorf("get owners: %w", err)
+			}
+		case !approaching && notified:
+			return tx.DeleteRuntimeConfig(ctx, licenseUserLimitNotifiedKey)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, owner := range owners {
+		_, err := api.NotificationsEnqueuer.Enqueue(ctx, owner.ID, notifications.TemplateLicenseUserLimitApproaching,
+			map[string]string{
+				"active_users":     strconv.FormatInt(forecast.ActiveUsers, 10),
+				"user_limit":       strconv.FormatInt(*forecast.UserLimit, 10),
+				"daily_growth":     fmt.Sprintf("%.1f", forecast.DailyGrowth),
+				"days_until_limit": strconv.FormatInt(*forecast.DaysUntilLimit, 10),
+				"projected_date":   forecast.ProjectedLimitDate.Format(time.DateOnly),
+			}, "license",
+		)
+		if err != nil {
+			api.Logger.Warn(ctx, "failed to notify owner of license user limit forecast",
+				slog.F("user_id", owner.ID),
+				slog.Error(err),
+			)
+		}
+	}
+	return nil
+}
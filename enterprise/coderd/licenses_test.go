@@ -11,6 +11,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
@@ -252,3 +255,59 @@ func TestDeleteLicense(t *testing.T) {
 		assert.Len(t, licenses, 0)
 	})
 }
+
+func TestLicenseUsageForecast(t *testing.T) {
+	t.Parallel()
+
+	notifyEnq := notificationstest.NewFakeEnqueuer()
+	dv := coderdtest.DeploymentValues(t)
+	dv.Notifications.LicenseUserLimitThresholdDays = 30
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		DontAddLicense: true,
+		Options: &coderdtest.Options{
+			DeploymentValues:      dv,
+			NotificationsEnqueuer: notifyEnq,
+		},
+	})
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	forecast, err := client.LicenseUsageForecast(ctx, codersdk.LicenseUsageForecastRequest{})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, forecast.ActiveUsers)
+	require.EqualValues(t, 30, forecast.TrailingDays)
+	require.Nil(t, forecast.UserLimit)
+	require.Nil(t, forecast.DaysUntilLimit)
+
+	// Both users count against the limit, so it is reached already.
+	coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureUserLimit: 2,
+		},
+	})
+	forecast, err = client.LicenseUsageForecast(ctx, codersdk.LicenseUsageForecastRequest{TrailingDays: 7})
+	require.NoError(t, err)
+	require.EqualValues(t, 7, forecast.TrailingDays)
+	require.NotNil(t, forecast.UserLimit)
+	require.EqualValues(t, 2, *forecast.UserLimit)
+	require.NotNil(t, forecast.DaysUntilLimit)
+	require.EqualValues(t, 0, *forecast.DaysUntilLimit)
+
+	// Owners are notified once the entitlements are refreshed.
+	require.Eventually(t, func() bool {
+		return len(notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateLicenseUserLimitApproaching))) > 0
+	}, testutil.WaitLong, testutil.IntervalFast)
+	sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateLicenseUserLimitApproaching))
+	require.Len(t, sent, 1)
+	require.Equal(t, owner.UserID, sent[0].UserID)
+	require.Equal(t, "0", sent[0].Labels["days_until_limit"])
+
+	_, err = client.LicenseUsageForecast(ctx, codersdk.LicenseUsageForecastRequest{TrailingDays: 1})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = memberClient.LicenseUsageForecast(ctx, codersdk.LicenseUsageForecastRequest{})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
@@ -0,0 +1,108 @@
+package coderd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSimulateWorkspaceLifecycle(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	require.NoError(t, dv.UserQuietHoursSchedule.DefaultSchedule.Set("CRON_TZ=UTC 0 0 * * *"))
+
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			DeploymentValues:         dv,
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+				codersdk.FeatureTemplateRBAC:               1,
+			},
+		},
+	})
+	templateAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	_, err := client.PatchGroup(ctx, owner.OrganizationID, codersdk.PatchGroupRequest{
+		QuotaAllowance: ptr.Ref(10),
+	})
+	require.NoError(t, err)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{
+					Resources: []*proto.Resource{{
+						Name:      "example",
+						Type:      "aws_instance",
+						DailyCost: 3,
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	_, err = templateAdminClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		DefaultTTLMillis: (8 * time.Hour).Milliseconds(),
+		AutostopRequirement: &codersdk.TemplateAutostopRequirement{
+			DaysOfWeek: []string{"saturday"},
+			Weeks:      1,
+		},
+		TimeTilDormantMillis:           (7 * 24 * time.Hour).Milliseconds(),
+		TimeTilDormantAutoDeleteMillis: (30 * 24 * time.Hour).Milliseconds(),
+	})
+	require.NoError(t, err)
+
+	// Monday 9am.
+	startAt := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	sim, err := templateAdminClient.SimulateWorkspaceLifecycle(ctx, template.ID, codersdk.SimulateWorkspaceLifecycleRequest{
+		UserID:  member.ID,
+		StartAt: ptr.Ref(startAt),
+	})
+	require.NoError(t, err)
+
+	saturday := func(day int) time.Time {
+		return time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	require.NotNil(t, sim.Deadline)
+	require.True(t, startAt.Add(8*time.Hour).Equal(*sim.Deadline))
+	require.NotNil(t, sim.MaxDeadline)
+	require.True(t, saturday(6).Equal(*sim.MaxDeadline))
+
+	require.NotNil(t, sim.DormantAt)
+	require.True(t, startAt.Add(7*24*time.Hour).Equal(*sim.DormantAt))
+	require.NotNil(t, sim.DeletingAt)
+	require.True(t, startAt.Add(37*24*time.Hour).Equal(*sim.DeletingAt))
+
+	require.Len(t, sim.MaintenanceWindows, 4)
+	for i, day := range []int{6, 13, 20, 27} {
+		require.True(t, saturday(day).Equal(sim.MaintenanceWindows[i]), "window %d", i)
+	}
+
+	require.NotNil(t, sim.Quota)
+	require.EqualValues(t, 3, sim.Quota.DailyCost)
+	require.Equal(t, 0, sim.Quota.CreditsConsumed)
+	require.Equal(t, 10, sim.Quota.Budget)
+	require.True(t, sim.Quota.Allowed)
+}
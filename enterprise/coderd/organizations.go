@@ -72,6 +72,7 @@ func (api *API) patchOrganization(rw http.ResponseWriter, r *http.Request) {
 			DisplayName: organization.DisplayName,
 			Description: organization.Description,
 			Icon:        organization.Icon,
+			CostCenter:  organization.CostCenter,
 		}
 
 		if req.Name != "" {
@@ -86,6 +87,9 @@ func (api *API) patchOrganization(rw http.ResponseWriter, r *http.Request) {
 		if req.Icon != nil {
 			updateOrgParams.Icon = *req.Icon
 		}
+		if req.CostCenter != nil {
+			updateOrgParams.CostCenter = *req.CostCenter
+		}
 
 		organization, err = tx.UpdateOrganization(ctx, updateOrgParams)
 		if err != nil {
@@ -275,6 +279,7 @@ func (api *API) postOrganizations(rw http.ResponseWriter, r *http.Request) {
 			DisplayName: req.DisplayName,
 			Description: req.Description,
 			Icon:        req.Icon,
+			CostCenter:  req.CostCenter,
 			CreatedAt:   dbtime.Now(),
 			UpdatedAt:   dbtime.Now(),
 		})
@@ -20,7 +20,7 @@ func (EnterprisePortSharer) AuthorizedLevel(template database.Template, level co
 
 func (EnterprisePortSharer) ValidateTemplateMaxLevel(level codersdk.WorkspaceAgentPortShareLevel) error {
 	if !level.ValidMaxLevel() {
-		return xerrors.New("invalid max port sharing level, value must be 'authenticated', 'organization', or 'public'.")
+		return xerrors.New("invalid max port sharing level, value must be 'restricted', 'authenticated', 'organization', or 'public'.")
 	}
 
 	return nil
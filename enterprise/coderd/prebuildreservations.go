@@ -0,0 +1,241 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get prebuilt workspace reservations by template
+// @Description Returns the reservations of the prebuilt workspaces of a
+// @Description template that have not been claimed yet, soonest to expire
+// @Description first. Expired reservations are included.
+// @ID get-prebuilt-workspace-reservations-by-template
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.PrebuiltWorkspaceReservation
+// @Router /templates/{template}/prebuild-reservations [get]
+func (api *API) templatePrebuildReservations(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	// Only users that can manage the template can see who its prebuilt
+	// workspaces are reserved for.
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	reservations, err := api.Database.GetWorkspacePrebuildReservationsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	res := make([]codersdk.PrebuiltWorkspaceReservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		res = append(res, convertPrebuildReservation(reservation))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Get prebuilt workspace reservation
+// @ID get-prebuilt-workspace-reservation
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.PrebuiltWorkspaceReservation
+// @Router /workspaces/{workspace}/prebuild-reservation [get]
+func (api *API) prebuildReservation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	reservation, err := api.Database.GetWorkspacePrebuildReservationByWorkspaceID(ctx, workspace.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertPrebuildReservation(reservation))
+}
+
+// @Summary Reserve prebuilt workspace
+// @Description Reserves a prebuilt workspace for a user or the members of a
+// @Description group, replacing any existing reservation. Until the reservation
+// @Description expires, nobody else can claim the prebuilt workspace. Reserving
+// @Description requires permission to update the template of the workspace.
+// @ID reserve-prebuilt-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.ReservePrebuiltWorkspaceRequest true "Reserve prebuilt workspace request"
+// @Success 200 {object} codersdk.PrebuiltWorkspaceReservation
+// @Router /workspaces/{workspace}/prebuild-reservation [put]
+func (api *API) putPrebuildReservation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	var req codersdk.ReservePrebuiltWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if workspace.OwnerID != database.PrebuildsSystemUserID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only prebuilt workspaces that have not been claimed can be reserved.",
+		})
+		return
+	}
+	if (req.UserID == nil) == (req.GroupID == nil) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Exactly one of user_id and group_id must be set.",
+		})
+		return
+	}
+	now := dbtime.Now()
+	if !req.ExpiresAt.After(now) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid reservation expiry.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "expires_at",
+				Detail: "must be in the future",
+			}},
+		})
+		return
+	}
+
+	// Template managers may not be able to read the users and groups of the
+	// organization, but they need to know whether the reservation is valid.
+	// nolint:gocritic // See above.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	params := database.UpsertWorkspacePrebuildReservationParams{
+		WorkspaceID: workspace.ID,
+		ReservedBy:  apiKey.UserID,
+		CreatedAt:   now,
+		ExpiresAt:   dbtime.Time(req.ExpiresAt),
+	}
+	if req.UserID != nil {
+		members, err := api.Database.OrganizationMembers(sysCtx, database.OrganizationMembersParams{
+			OrganizationID: workspace.OrganizationID,
+			UserID:         *req.UserID,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if len(members) == 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The user is not a member of the organization of the workspace.",
+			})
+			return
+		}
+		params.UserID = uuid.NullUUID{UUID: *req.UserID, Valid: true}
+	}
+	if req.GroupID != nil {
+		group, err := api.Database.GetGroupByID(sysCtx, *req.GroupID)
+		if httpapi.Is404Error(err) || (err == nil && group.OrganizationID != workspace.OrganizationID) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The group does not belong to the organization of the workspace.",
+			})
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		params.GroupID = uuid.NullUUID{UUID: group.ID, Valid: true}
+	}
+
+	reservation, err := api.Database.UpsertWorkspacePrebuildReservation(ctx, params)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template managers may reserve prebuilt workspaces.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertPrebuildReservation(reservation))
+}
+
+// @Summary Delete prebuilt workspace reservation
+// @Description Releases a prebuilt workspace so that anyone may claim it.
+// @ID delete-prebuilt-workspace-reservation
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 204
+// @Router /workspaces/{workspace}/prebuild-reservation [delete]
+func (api *API) deletePrebuildReservation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	_, err := api.Database.GetWorkspacePrebuildReservationByWorkspaceID(ctx, workspace.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	err = api.Database.DeleteWorkspacePrebuildReservationByWorkspaceID(ctx, workspace.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template managers may release prebuilt workspace reservations.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertPrebuildReservation(reservation database.WorkspacePrebuildReservation) codersdk.PrebuiltWorkspaceReservation {
+	res := codersdk.PrebuiltWorkspaceReservation{
+		WorkspaceID: reservation.WorkspaceID,
+		ReservedBy:  reservation.ReservedBy,
+		CreatedAt:   reservation.CreatedAt,
+		ExpiresAt:   reservation.ExpiresAt,
+	}
+	if reservation.UserID.Valid {
+		res.UserID = &reservation.UserID.UUID
+	}
+	if reservation.GroupID.Valid {
+		res.GroupID = &reservation.GroupID.UUID
+	}
+	return res
+}
@@ -0,0 +1,223 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPrebuildReservations(t *testing.T) {
+	t.Parallel()
+
+	type setupResult struct {
+		client   *codersdk.Client
+		db       database.Store
+		owner    codersdk.CreateFirstUserResponse
+		version  dbfake.TemplateVersionResponse
+		presetID uuid.UUID
+		prebuild dbfake.WorkspaceResponse
+	}
+	setup := func(t *testing.T) setupResult {
+		client, db, owner := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: coderdtest.DeploymentValues(t),
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureWorkspacePrebuilds: 1,
+				},
+			},
+		})
+
+		presetID := uuid.New()
+		version := dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+			OrganizationID: owner.OrganizationID,
+			CreatedBy:      owner.UserID,
+		}).Preset(database.TemplateVersionPreset{
+			ID: presetID,
+		}).Do()
+		prebuild := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:    database.PrebuildsSystemUserID,
+			TemplateID: version.Template.ID,
+		}).Seed(database.WorkspaceBuild{
+			TemplateVersionID:       version.TemplateVersion.ID,
+			TemplateVersionPresetID: uuid.NullUUID{UUID: presetID, Valid: true},
+		}).WithAgent(func(a []*proto.Agent) []*proto.Agent {
+			return a
+		}).Do()
+
+		return setupResult{
+			client:   client,
+			db:       db,
+			owner:    owner,
+			version:  version,
+			presetID: presetID,
+			prebuild: prebuild,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		s := setup(t)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, s.client, s.owner.OrganizationID, rbac.RoleTemplateAdmin())
+		_, member := coderdtest.CreateAnotherUser(t, s.client, s.owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		expiresAt := time.Now().Add(24 * time.Hour)
+		reservation, err := templateAdmin.ReservePrebuiltWorkspace(ctx, s.prebuild.Workspace.ID, codersdk.ReservePrebuiltWorkspaceRequest{
+			UserID:    &member.ID,
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, s.prebuild.Workspace.ID, reservation.WorkspaceID)
+		require.Equal(t, &member.ID, reservation.UserID)
+		require.Nil(t, reservation.GroupID)
+		require.WithinDuration(t, expiresAt, reservation.ExpiresAt, time.Second)
+
+		got, err := templateAdmin.PrebuiltWorkspaceReservation(ctx, s.prebuild.Workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, reservation.UserID, got.UserID)
+
+		// Reserving again replaces the reservation.
+		group := dbgen.Group(t, s.db, database.Group{OrganizationID: s.owner.OrganizationID})
+		reservation, err = templateAdmin.ReservePrebuiltWorkspace(ctx, s.prebuild.Workspace.ID, codersdk.ReservePrebuiltWorkspaceRequest{
+			GroupID:   &group.ID,
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+		require.Nil(t, reservation.UserID)
+		require.Equal(t, &group.ID, reservation.GroupID)
+
+		reservations, err := templateAdmin.TemplatePrebuiltWorkspaceReservations(ctx, s.version.Template.ID)
+		require.NoError(t, err)
+		require.Len(t, reservations, 1)
+		require.Equal(t, &group.ID, reservations[0].GroupID)
+
+		err = templateAdmin.DeletePrebuiltWorkspaceReservation(ctx, s.prebuild.Workspace.ID)
+		require.NoError(t, err)
+		_, err = templateAdmin.PrebuiltWorkspaceReservation(ctx, s.prebuild.Workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		s := setup(t)
+		_, member := coderdtest.CreateAnotherUser(t, s.client, s.owner.OrganizationID)
+		group := dbgen.Group(t, s.db, database.Group{OrganizationID: s.owner.OrganizationID})
+		otherOrg := dbgen.Organization(t, s.db, database.Organization{})
+		otherGroup := dbgen.Group(t, s.db, database.Group{OrganizationID: otherOrg.ID})
+		outsider := dbgen.User(t, s.db, database.User{})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for name, req := range map[string]codersdk.ReservePrebuiltWorkspaceRequest{
+			"Neither":    {ExpiresAt: time.Now().Add(time.Hour)},
+			"Both":       {UserID: &member.ID, GroupID: &group.ID, ExpiresAt: time.Now().Add(time.Hour)},
+			"Expired":    {UserID: &member.ID, ExpiresAt: time.Now().Add(-time.Hour)},
+			"OtherOrg":   {GroupID: &otherGroup.ID, ExpiresAt: time.Now().Add(time.Hour)},
+			"NotAMember": {UserID: &outsider.ID, ExpiresAt: time.Now().Add(time.Hour)},
+		} {
+			_, err := s.client.ReservePrebuiltWorkspace(ctx, s.prebuild.Workspace.ID, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), name)
+		}
+
+		// Claimed workspaces can't be reserved.
+		workspace := dbfake.WorkspaceBuild(t, s.db, database.WorkspaceTable{
+			OrganizationID: s.owner.OrganizationID,
+			OwnerID:        member.ID,
+			TemplateID:     s.version.Template.ID,
+		}).Do()
+		_, err := s.client.ReservePrebuiltWorkspace(ctx, workspace.Workspace.ID, codersdk.ReservePrebuiltWorkspaceRequest{
+			UserID:    &member.ID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("TemplateManagerOnly", func(t *testing.T) {
+		t.Parallel()
+		s := setup(t)
+		memberClient, member := coderdtest.CreateAnotherUser(t, s.client, s.owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Members can't see prebuilt workspaces at all.
+		_, err := memberClient.ReservePrebuiltWorkspace(ctx, s.prebuild.Workspace.ID, codersdk.ReservePrebuiltWorkspaceRequest{
+			UserID:    &member.ID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		_, err = memberClient.TemplatePrebuiltWorkspaceReservations(ctx, s.version.Template.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Claim", func(t *testing.T) {
+		t.Parallel()
+
+		if !dbtestutil.WillUsePostgres() {
+			t.Skip("dbmem cannot currently claim a workspace")
+		}
+
+		s := setup(t)
+		reservedClient, reserved := coderdtest.CreateAnotherUser(t, s.client, s.owner.OrganizationID)
+		otherClient, other := coderdtest.CreateAnotherUser(t, s.client, s.owner.OrganizationID)
+
+		// nolint:gocritic // this is a test
+		ctx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitLong))
+		agent, err := s.db.GetWorkspaceAgentAndLatestBuildByAuthToken(ctx, uuid.MustParse(s.prebuild.AgentToken))
+		require.NoError(t, err)
+		err = s.db.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+			ID:             agent.WorkspaceAgent.ID,
+			LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+		})
+		require.NoError(t, err)
+
+		_, err = s.client.ReservePrebuiltWorkspace(ctx, s.prebuild.Workspace.ID, codersdk.ReservePrebuiltWorkspaceRequest{
+			UserID:    &reserved.ID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		// Another user building first gets a new workspace.
+		workspace, err := otherClient.CreateUserWorkspace(ctx, other.ID.String(), codersdk.CreateWorkspaceRequest{
+			TemplateVersionID:       s.version.TemplateVersion.ID,
+			TemplateVersionPresetID: s.presetID,
+			Name:                    "other-workspace",
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, s.prebuild.Workspace.ID, workspace.ID)
+
+		// The user the prebuilt workspace is reserved for claims it.
+		workspace, err = reservedClient.CreateUserWorkspace(ctx, reserved.ID.String(), codersdk.CreateWorkspaceRequest{
+			TemplateVersionID:       s.version.TemplateVersion.ID,
+			TemplateVersionPresetID: s.presetID,
+			Name:                    "reserved-workspace",
+		})
+		require.NoError(t, err)
+		require.Equal(t, s.prebuild.Workspace.ID, workspace.ID)
+	})
+}
@@ -28,6 +28,9 @@ const (
 	MetricRunningGauge              = namespace + "running"
 	MetricEligibleGauge             = namespace + "eligible"
 	MetricPresetHardLimitedGauge    = namespace + "preset_hard_limited"
+	MetricClaimHitsGauge            = namespace + "claim_hits"
+	MetricClaimMissesGauge          = namespace + "claim_misses"
+	MetricForecastDesiredGauge      = namespace + "forecast_desired"
 	MetricLastUpdatedGauge          = namespace + "metrics_last_updated"
 )
 
@@ -89,6 +92,28 @@ var (
 		labels,
 		nil,
 	)
+	claimHitsDesc = prometheus.NewDesc(
+		MetricClaimHitsGauge,
+		"Number of workspaces created from each template preset within the demand forecast lookback period "+
+			"which claimed a prebuilt workspace.",
+		labels,
+		nil,
+	)
+	claimMissesDesc = prometheus.NewDesc(
+		MetricClaimMissesGauge,
+		"Number of workspaces created from each template preset within the demand forecast lookback period "+
+			"which had to be built from scratch because no prebuilt workspace was eligible to be claimed.",
+		labels,
+		nil,
+	)
+	forecastDesiredDesc = prometheus.NewDesc(
+		MetricForecastDesiredGauge,
+		"Number of prebuilt workspaces forecast to be claimed from each template preset in the current and next hour, "+
+			"based on historical demand. Reported even when demand forecasting is disabled. "+
+			"Metric is omitted if there is no historical demand.",
+		labels,
+		nil,
+	)
 	lastUpdateDesc = prometheus.NewDesc(
 		MetricLastUpdatedGauge,
 		"The unix timestamp when the metrics related to prebuilt workspaces were last updated; these metrics are cached.",
@@ -139,6 +164,9 @@ func (*MetricsCollector) Describe(descCh chan<- *prometheus.Desc) {
 	descCh <- runningPrebuildsDesc
 	descCh <- eligiblePrebuildsDesc
 	descCh <- presetHardLimitedDesc
+	descCh <- claimHitsDesc
+	descCh <- claimMissesDesc
+	descCh <- forecastDesiredDesc
 	descCh <- lastUpdateDesc
 }
 
@@ -184,6 +212,13 @@ func (mc *MetricsCollector) Collect(metricsCh chan<- prometheus.Metric) {
 		metricsCh <- prometheus.MustNewConstMetric(desiredPrebuildsDesc, prometheus.GaugeValue, float64(state.Desired), preset.TemplateName, preset.Name, preset.OrganizationName)
 		metricsCh <- prometheus.MustNewConstMetric(runningPrebuildsDesc, prometheus.GaugeValue, float64(state.Actual), preset.TemplateName, preset.Name, preset.OrganizationName)
 		metricsCh <- prometheus.MustNewConstMetric(eligiblePrebuildsDesc, prometheus.GaugeValue, float64(state.Eligible), preset.TemplateName, preset.Name, preset.OrganizationName)
+
+		hits, misses := presetSnapshot.ClaimHitsAndMisses()
+		metricsCh <- prometheus.MustNewConstMetric(claimHitsDesc, prometheus.GaugeValue, float64(hits), preset.TemplateName, preset.Name, preset.OrganizationName)
+		metricsCh <- prometheus.MustNewConstMetric(claimMissesDesc, prometheus.GaugeValue, float64(misses), preset.TemplateName, preset.Name, preset.OrganizationName)
+		if forecast, ok := presetSnapshot.ForecastDesiredInstances(currentState.createdAt); ok {
+			metricsCh <- prometheus.MustNewConstMetric(forecastDesiredDesc, prometheus.GaugeValue, float64(forecast), preset.TemplateName, preset.Name, preset.OrganizationName)
+		}
 	}
 
 	mc.isPresetHardLimitedMu.Lock()
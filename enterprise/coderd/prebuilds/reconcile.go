@@ -26,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/readonly"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
 	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
@@ -47,6 +48,7 @@ type StoreReconciler struct {
 	registerer prometheus.Registerer
 	metrics    *MetricsCollector
 	notifEnq   notifications.Enqueuer
+	readOnly   *readonly.Mode
 
 	cancelFn          context.CancelCauseFunc
 	running           atomic.Bool
@@ -90,6 +92,13 @@ func NewStoreReconciler(store database.Store,
 	return reconciler
 }
 
+// WithReadOnlyMode pauses reconciliation while the deployment is in read-only
+// mode.
+func (c *StoreReconciler) WithReadOnlyMode(mode *readonly.Mode) *StoreReconciler {
+	c.readOnly = mode
+	return c
+}
+
 func (c *StoreReconciler) Run(ctx context.Context) {
 	reconciliationInterval := c.cfg.ReconciliationInterval.Value()
 	if reconciliationInterval <= 0 { // avoids a panic
@@ -253,6 +262,11 @@ func (c *StoreReconciler) ReconcileAll(ctx context.Context) error {
 	default:
 	}
 
+	if c.readOnly.Enabled() {
+		logger.Debug(ctx, "deployment is in read-only mode, skipping reconciliation")
+		return nil
+	}
+
 	logger.Debug(ctx, "starting reconciliation")
 
 	err := c.WithReconciliationLock(ctx, logger, func(ctx context.Context, _ database.Store) error {
@@ -395,6 +409,15 @@ func (c *StoreReconciler) SnapshotState(ctx context.Context, store database.Stor
 			return xerrors.Errorf("failed to get hard limited presets: %w", err)
 		}
 
+		demandForecast := prebuilds.DemandForecastOptions{
+			Enabled:  c.cfg.DemandForecasting.Value(),
+			Lookback: c.cfg.DemandForecastLookback.Value(),
+		}
+		demand, err := db.GetPrebuildClaimDemand(ctx, demandForecast.DemandSince(c.clock.Now()))
+		if err != nil {
+			return xerrors.Errorf("failed to get prebuild claim demand: %w", err)
+		}
+
 		state = prebuilds.NewGlobalSnapshot(
 			presetsWithPrebuilds,
 			presetPrebuildSchedules,
@@ -404,7 +427,7 @@ func (c *StoreReconciler) SnapshotState(ctx context.Context, store database.Stor
 			hardLimitedPresets,
 			c.clock,
 			c.logger,
-		)
+		).WithDemand(demand, demandForecast)
 		return nil
 	}, &database.TxOptions{
 		Isolation:    sql.LevelRepeatableRead, // This mirrors the MVCC snapshotting Postgres does when using CTEs
@@ -717,6 +740,9 @@ func (c *StoreReconciler) provision(
 	builder := wsbuilder.New(workspace, transition).
 		Reason(database.BuildReasonInitiator).
 		Initiator(database.PrebuildsSystemUserID).
+		InitiatorContext(database.BuildInitiatorContext{
+			Automation: string(codersdk.BuildAutomationPrebuilds),
+		}).
 		MarkPrebuild()
 
 	if transition != database.WorkspaceTransitionDelete {
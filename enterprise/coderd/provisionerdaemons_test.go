@@ -586,7 +586,7 @@ func TestProvisionerDaemonServe(t *testing.T) {
 	t.Run("ProvisionerKeyAuth", func(t *testing.T) {
 		t.Parallel()
 
-		insertParams, token, err := provisionerkey.New(uuid.Nil, "dont-TEST-me", nil)
+		insertParams, token, err := provisionerkey.New(uuid.Nil, "dont-TEST-me", nil, nil)
 		require.NoError(t, err)
 
 		tcs := []struct {
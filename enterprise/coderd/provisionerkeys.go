@@ -1,14 +1,18 @@
 package coderd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
@@ -74,7 +78,20 @@ func (api *API) postProvisionerKey(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	params, token, err := provisionerkey.New(organization.ID, req.Name, req.Tags)
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(dbtime.Now()) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Expiry must be in the future",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "expires_at",
+					Detail: "Expiry must be in the future",
+				},
+			},
+		})
+		return
+	}
+
+	params, token, err := provisionerkey.New(organization.ID, req.Name, req.Tags, req.ExpiresAt)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
@@ -114,8 +131,18 @@ func (api *API) provisionerKeys(rw http.ResponseWriter, r *http.Request) {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
+	sdkKeys := convertProvisionerKeys(pks)
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerKeys(pks))
+	recentDaemons, err := api.recentProvisionerDaemons(ctx, organization.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	for i := range sdkKeys {
+		sdkKeys[i].ConnectedDaemons = len(daemonsForKey(recentDaemons, sdkKeys[i].ID))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sdkKeys)
 }
 
 // @Summary List provisioner key daemons
@@ -151,13 +178,11 @@ func (api *API) provisionerKeyDaemons(rw http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	daemons, err := api.Database.GetProvisionerDaemonsByOrganization(ctx, database.GetProvisionerDaemonsByOrganizationParams{OrganizationID: organization.ID})
+	recentDaemons, err := api.recentProvisionerDaemons(ctx, organization.ID)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
-	// provisionerdserver.DefaultHeartbeatInterval*3 matches the healthcheck report staleInterval.
-	recentDaemons := db2sdk.RecentProvisionerDaemons(time.Now(), provisionerdserver.DefaultHeartbeatInterval*3, daemons)
 
 	pkDaemons := []codersdk.ProvisionerKeyDaemons{}
 	for _, key := range sdkKeys {
@@ -169,12 +194,8 @@ func (api *API) provisionerKeyDaemons(rw http.ResponseWriter, r *http.Request) {
 		if key.ID.String() == codersdk.ProvisionerKeyIDUserAuth {
 			key.OrganizationID = organization.ID
 		}
-		daemons := []codersdk.ProvisionerDaemon{}
-		for _, daemon := range recentDaemons {
-			if daemon.KeyID == key.ID {
-				daemons = append(daemons, daemon)
-			}
-		}
+		daemons := daemonsForKey(recentDaemons, key.ID)
+		key.ConnectedDaemons = len(daemons)
 		pkDaemons = append(pkDaemons, codersdk.ProvisionerKeyDaemons{
 			Key:     key,
 			Daemons: daemons,
@@ -214,6 +235,79 @@ func (api *API) deleteProvisionerKey(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// @Summary Rotate provisioner key
+// @ID rotate-provisioner-key
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID"
+// @Param provisionerkey path string true "Provisioner key name"
+// @Param request body codersdk.RotateProvisionerKeyRequest true "Rotate provisioner key request"
+// @Success 200 {object} codersdk.CreateProvisionerKeyResponse
+// @Router /organizations/{organization}/provisionerkeys/{provisionerkey}/rotate [post]
+func (api *API) rotateProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	provisionerKey := httpmw.ProvisionerKeyParam(r)
+
+	var req codersdk.RotateProvisionerKeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if provisionerKey.ID.String() == codersdk.ProvisionerKeyIDBuiltIn ||
+		provisionerKey.ID.String() == codersdk.ProvisionerKeyIDUserAuth ||
+		provisionerKey.ID.String() == codersdk.ProvisionerKeyIDPSK {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Cannot rotate reserved '%s' provisioner key", provisionerKey.Name),
+		})
+		return
+	}
+
+	if req.OverlapMillis < 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Overlap must not be negative",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "overlap_ms",
+					Detail: "Overlap must not be negative",
+				},
+			},
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Expiry must be in the future",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "expires_at",
+					Detail: "Expiry must be in the future",
+				},
+			},
+		})
+		return
+	}
+
+	params, token, err := provisionerkey.Rotate(provisionerKey, now, time.Duration(req.OverlapMillis)*time.Millisecond, req.ExpiresAt)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	_, err = api.Database.RotateProvisionerKey(ctx, params)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.CreateProvisionerKeyResponse{
+		Key: token,
+	})
+}
+
 // @Summary Fetch provisioner key details
 // @ID fetch-provisioner-key-details
 // @Security CoderProvisionerKey
@@ -237,8 +331,29 @@ func (*API) fetchProvisionerKey(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerKey(pk))
 }
 
+// recentProvisionerDaemons returns the daemons in the organization that have
+// recently sent a heartbeat.
+func (api *API) recentProvisionerDaemons(ctx context.Context, organizationID uuid.UUID) ([]codersdk.ProvisionerDaemon, error) {
+	daemons, err := api.Database.GetProvisionerDaemonsByOrganization(ctx, database.GetProvisionerDaemonsByOrganizationParams{OrganizationID: organizationID})
+	if err != nil {
+		return nil, err
+	}
+	// provisionerdserver.DefaultHeartbeatInterval*3 matches the healthcheck report staleInterval.
+	return db2sdk.RecentProvisionerDaemons(time.Now(), provisionerdserver.DefaultHeartbeatInterval*3, daemons), nil
+}
+
+func daemonsForKey(daemons []codersdk.ProvisionerDaemon, keyID uuid.UUID) []codersdk.ProvisionerDaemon {
+	keyDaemons := []codersdk.ProvisionerDaemon{}
+	for _, daemon := range daemons {
+		if daemon.KeyID == keyID {
+			keyDaemons = append(keyDaemons, daemon)
+		}
+	}
+	return keyDaemons
+}
+
 func convertProvisionerKey(dbKey database.ProvisionerKey) codersdk.ProvisionerKey {
-	return codersdk.ProvisionerKey{
+	key := codersdk.ProvisionerKey{
 		ID:             dbKey.ID,
 		CreatedAt:      dbKey.CreatedAt,
 		OrganizationID: dbKey.OrganizationID,
@@ -246,6 +361,16 @@ func convertProvisionerKey(dbKey database.ProvisionerKey) codersdk.ProvisionerKe
 		Tags:           codersdk.ProvisionerKeyTags(dbKey.Tags),
 		// HashedSecret - never include the access token in the API response
 	}
+	if dbKey.LastUsedAt.Valid {
+		key.LastUsedAt = &dbKey.LastUsedAt.Time
+	}
+	if dbKey.ExpiresAt.Valid {
+		key.ExpiresAt = &dbKey.ExpiresAt.Time
+	}
+	if dbKey.PreviousSecretExpiresAt.Valid {
+		key.RotationOverlapEndsAt = &dbKey.PreviousSecretExpiresAt.Time
+	}
+	return key
 }
 
 func convertProvisionerKeys(dbKeys []database.ProvisionerKey) []codersdk.ProvisionerKey {
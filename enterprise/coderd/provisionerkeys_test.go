@@ -3,7 +3,9 @@ package coderd_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -266,3 +268,112 @@ func TestGetProvisionerKey(t *testing.T) {
 		require.Empty(t, fetchedKey)
 	})
 }
+
+func TestRotateProvisionerKey(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, uuid.UUID) {
+		dv := coderdtest.DeploymentValues(t)
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureMultipleOrganizations:      1,
+					codersdk.FeatureExternalProvisionerDaemons: 1,
+				},
+			},
+		})
+		return client, owner.OrganizationID
+	}
+
+	t.Run("Overlap", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client, orgID := setup(t)
+
+		//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+		oldKey, err := client.CreateProvisionerKey(ctx, orgID, codersdk.CreateProvisionerKeyRequest{
+			Name: "rotate-me",
+		})
+		require.NoError(t, err)
+
+		fetched, err := client.GetProvisionerKey(ctx, oldKey.Key)
+		require.NoError(t, err)
+		require.NotNil(t, fetched.LastUsedAt)
+
+		//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+		newKey, err := client.RotateProvisionerKey(ctx, orgID, "rotate-me", codersdk.RotateProvisionerKeyRequest{
+			OverlapMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, oldKey.Key, newKey.Key)
+
+		// Both secrets are accepted during the overlap window.
+		_, err = client.GetProvisionerKey(ctx, oldKey.Key)
+		require.NoError(t, err)
+		_, err = client.GetProvisionerKey(ctx, newKey.Key)
+		require.NoError(t, err)
+
+		//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+		keys, err := client.ListProvisionerKeys(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.NotNil(t, keys[0].RotationOverlapEndsAt)
+		require.NotNil(t, keys[0].LastUsedAt)
+	})
+
+	t.Run("NoOverlap", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client, orgID := setup(t)
+
+		//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+		oldKey, err := client.CreateProvisionerKey(ctx, orgID, codersdk.CreateProvisionerKeyRequest{
+			Name: "rotate-me",
+		})
+		require.NoError(t, err)
+
+		//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+		newKey, err := client.RotateProvisionerKey(ctx, orgID, "rotate-me", codersdk.RotateProvisionerKeyRequest{})
+		require.NoError(t, err)
+
+		_, err = client.GetProvisionerKey(ctx, oldKey.Key)
+		require.ErrorContains(t, err, "provisioner daemon key expired")
+		_, err = client.GetProvisionerKey(ctx, newKey.Key)
+		require.NoError(t, err)
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client, orgID := setup(t)
+
+		past := time.Now().Add(-time.Minute)
+		//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+		_, err := client.CreateProvisionerKey(ctx, orgID, codersdk.CreateProvisionerKeyRequest{
+			Name:      "expired",
+			ExpiresAt: &past,
+		})
+		require.ErrorContains(t, err, "Expiry must be in the future")
+
+		future := time.Now().Add(time.Hour)
+		//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+		_, err = client.CreateProvisionerKey(ctx, orgID, codersdk.CreateProvisionerKeyRequest{
+			Name:      "expiring",
+			ExpiresAt: &future,
+		})
+		require.NoError(t, err)
+
+		//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+		keys, err := client.ListProvisionerKeys(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.NotNil(t, keys[0].ExpiresAt)
+		require.WithinDuration(t, future, *keys[0].ExpiresAt, time.Second)
+	})
+}
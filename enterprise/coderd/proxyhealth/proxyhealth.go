@@ -70,6 +70,7 @@ type ProxyHealth struct {
 	// PromMetrics
 	healthCheckDuration prometheus.Histogram
 	healthCheckResults  *prometheusmetrics.CachedGaugeVec
+	failovers           *prometheus.CounterVec
 }
 
 func New(opts *Options) (*ProxyHealth, error) {
@@ -112,6 +113,15 @@ func New(opts *Options) (*ProxyHealth, error) {
 		}, []string{"proxy_id"}))
 	opts.Prometheus.MustRegister(healthCheckResults)
 
+	failovers := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "proxyhealth",
+		Name:      "failovers_total",
+		Help: "The number of times a workspace proxy stopped being healthy, " +
+			"which fails its app traffic over to another region.",
+	}, []string{"proxy_id"})
+	opts.Prometheus.MustRegister(failovers)
+
 	return &ProxyHealth{
 		db:                  opts.DB,
 		interval:            opts.Interval,
@@ -121,6 +131,7 @@ func New(opts *Options) (*ProxyHealth, error) {
 		proxyHosts:          &atomic.Pointer[[]*agplproxyhealth.ProxyHost]{},
 		healthCheckDuration: healthCheckDuration,
 		healthCheckResults:  healthCheckResults,
+		failovers:           failovers,
 	}, nil
 }
 
@@ -140,12 +151,28 @@ func (p *ProxyHealth) Run(ctx context.Context) {
 				p.logger.Error(ctx, "proxy health check failed", slog.Error(err))
 				continue
 			}
-			p.storeProxyHealth(statuses)
+			p.storeProxyHealth(ctx, statuses)
 		}
 	}
 }
 
-func (p *ProxyHealth) storeProxyHealth(statuses map[uuid.UUID]ProxyStatus) {
+func (p *ProxyHealth) storeProxyHealth(ctx context.Context, statuses map[uuid.UUID]ProxyStatus) {
+	// Clients stop using proxies that are not healthy, so a healthy proxy
+	// becoming unhealthy fails its app traffic over to another region.
+	previous := p.HealthStatus()
+	for id, s := range statuses {
+		prev, ok := previous[id]
+		if !ok || prev.Status != Healthy || s.Status == Healthy {
+			continue
+		}
+		p.failovers.WithLabelValues(id.String()).Inc()
+		p.logger.Warn(ctx, "workspace proxy is no longer healthy, failing over app traffic",
+			slog.F("proxy_id", id),
+			slog.F("proxy_name", s.Proxy.Name),
+			slog.F("status", s.Status),
+		)
+	}
+
 	var proxyHosts []*agplproxyhealth.ProxyHost
 	for _, s := range statuses {
 		if s.ProxyHost != nil {
@@ -167,7 +194,7 @@ func (p *ProxyHealth) ForceUpdate(ctx context.Context) error {
 		return err
 	}
 
-	p.storeProxyHealth(statuses)
+	p.storeProxyHealth(ctx, statuses)
 	return nil
 }
 
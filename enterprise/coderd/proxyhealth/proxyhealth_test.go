@@ -5,11 +5,14 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/coderdtest/promhelp"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmem"
@@ -145,6 +148,57 @@ func TestProxyHealth_Reachable(t *testing.T) {
 	}
 }
 
+func TestProxyHealth_Failover(t *testing.T) {
+	t.Parallel()
+	db := dbmem.New()
+
+	var unhealthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report codersdk.ProxyHealthReport
+		if unhealthy.Load() {
+			report.Errors = []string{"We have a problem!"}
+		}
+		httpapi.Write(context.Background(), w, http.StatusOK, report)
+	}))
+	defer srv.Close()
+
+	proxy := insertProxy(t, db, srv.URL)
+	reg := prometheus.NewRegistry()
+	ph, err := proxyhealth.New(&proxyhealth.Options{
+		Interval:   0,
+		DB:         db,
+		Logger:     testutil.Logger(t),
+		Client:     srv.Client(),
+		Prometheus: reg,
+	})
+	require.NoError(t, err, "failed to create proxy health")
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	labels := prometheus.Labels{"proxy_id": proxy.ID.String()}
+	err = ph.ForceUpdate(ctx)
+	require.NoError(t, err, "failed to force update")
+	require.Equal(t, proxyhealth.Healthy, ph.HealthStatus()[proxy.ID].Status)
+	require.Nil(t, promhelp.MetricValue(t, reg, "coderd_proxyhealth_failovers_total", labels))
+
+	// The proxy failing over is only counted once while it stays unhealthy.
+	unhealthy.Store(true)
+	for range 2 {
+		err = ph.ForceUpdate(ctx)
+		require.NoError(t, err, "failed to force update")
+		require.Equal(t, proxyhealth.Unhealthy, ph.HealthStatus()[proxy.ID].Status)
+		require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_proxyhealth_failovers_total", labels))
+	}
+
+	// Recovering isn't a failover.
+	unhealthy.Store(false)
+	err = ph.ForceUpdate(ctx)
+	require.NoError(t, err, "failed to force update")
+	require.Equal(t, proxyhealth.Healthy, ph.HealthStatus()[proxy.ID].Status)
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_proxyhealth_failovers_total", labels))
+}
+
 func TestProxyHealth_Unreachable(t *testing.T) {
 	t.Parallel()
 	db := dbmem.New()
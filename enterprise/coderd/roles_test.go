@@ -170,6 +170,58 @@ func TestCustomOrganizationRole(t *testing.T) {
 		require.ErrorContains(t, err, "forbidden")
 	})
 
+	// Negative permissions override grants from other organization roles.
+	t.Run("DenyPermissions", func(t *testing.T) {
+		t.Parallel()
+		owner, first := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureCustomRoles: 1,
+				},
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		//nolint:gocritic // owner is required for this
+		role, err := owner.CreateOrganizationRole(ctx, codersdk.Role{
+			Name:           "no-template-delete",
+			OrganizationID: first.OrganizationID.String(),
+			OrganizationPermissions: []codersdk.Permission{
+				{
+					Negate:       true,
+					ResourceType: codersdk.ResourceTemplate,
+					Action:       codersdk.ActionDelete,
+				},
+			},
+		})
+		require.NoError(t, err, "upsert role")
+		require.Len(t, role.OrganizationPermissions, 1)
+		require.True(t, role.OrganizationPermissions[0].Negate)
+
+		tmplAdmin, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID,
+			rbac.ScopedRoleOrgTemplateAdmin(first.OrganizationID),
+			rbac.RoleIdentifier{Name: role.Name, OrganizationID: first.OrganizationID},
+		)
+
+		version := coderdtest.CreateTemplateVersion(t, tmplAdmin, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, tmplAdmin, version.ID)
+		template := coderdtest.CreateTemplate(t, tmplAdmin, first.OrganizationID, version.ID)
+
+		// Everything else granted by the template admin role still works.
+		_, err = tmplAdmin.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "Updated by a template admin",
+		})
+		require.NoError(t, err)
+
+		err = tmplAdmin.DeleteTemplate(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
 	t.Run("InvalidName", func(t *testing.T) {
 		t.Parallel()
 		owner, first := coderdenttest.New(t, &coderdenttest.Options{
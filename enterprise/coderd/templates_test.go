@@ -113,6 +113,7 @@ func TestTemplates(t *testing.T) {
 			Name:       "foobar",
 		})
 		require.ErrorContains(t, err, "deprecated")
+		require.True(t, codersdk.IsErrorCode(err, codersdk.ResponseCodeTemplateDeprecated))
 
 		// Unset deprecated and try again
 		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{DeprecationMessage: ptr.Ref("")})
@@ -0,0 +1,204 @@
+package coderd
+
+import (
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template shares
+// @ID get-template-shares
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateShare
+// @Router /templates/{template}/shares [get]
+func (api *API) templateShares(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	// Only users that can manage the template can see where it is shared.
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	shares, err := api.Database.GetTemplateSharesByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	res := make([]codersdk.TemplateShare, 0, len(shares))
+	for _, share := range shares {
+		res = append(res, convertTemplateShare(share))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Share template with organization
+// @Description Shares a template read-only into another organization. Members
+// @Description of the organization can read the template and its versions, but
+// @Description cannot modify it or create workspaces from it.
+// @ID share-template-with-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateShare
+// @Router /templates/{template}/shares/{organization} [put]
+func (api *API) putTemplateShare(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		organization      = httpmw.OrganizationParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Template](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = template
+
+	if organization.ID == template.OrganizationID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A template cannot be shared into its own organization.",
+		})
+		return
+	}
+
+	var share database.TemplateShare
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		share, err = tx.UpsertTemplateShare(ctx, database.UpsertTemplateShareParams{
+			TemplateID:     template.ID,
+			OrganizationID: organization.ID,
+			CreatedBy:      apiKey.UserID,
+			CreatedAt:      dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert template share: %w", err)
+		}
+		template, err = tx.GetTemplateByID(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get updated template by ID: %w", err)
+		}
+		return nil
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = template
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateShare(share))
+}
+
+// @Summary Unshare template from organization
+// @ID unshare-template-from-organization
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 204
+// @Router /templates/{template}/shares/{organization} [delete]
+func (api *API) deleteTemplateShare(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		organization      = httpmw.OrganizationParam(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Template](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = template
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteTemplateShare(ctx, database.DeleteTemplateShareParams{
+			TemplateID:     template.ID,
+			OrganizationID: organization.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete template share: %w", err)
+		}
+		template, err = tx.GetTemplateByID(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get updated template by ID: %w", err)
+		}
+		return nil
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = template
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get templates shared with organization
+// @Description Returns the templates of other organizations that are shared
+// @Description into the organization.
+// @ID get-templates-shared-with-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.Template
+// @Router /organizations/{organization}/shared-templates [get]
+func (api *API) templatesSharedWithOrganization(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	templates, err := api.Database.GetTemplatesSharedWithOrganization(ctx, organization.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.AGPL.ConvertTemplates(templates))
+}
+
+func convertTemplateShare(share database.TemplateShare) codersdk.TemplateShare {
+	return codersdk.TemplateShare{
+		TemplateID:     share.TemplateID,
+		OrganizationID: share.OrganizationID,
+		CreatedBy:      share.CreatedBy,
+		CreatedAt:      share.CreatedAt,
+	}
+}
@@ -0,0 +1,134 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateShares(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Template, codersdk.Organization) {
+		owner, first := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureMultipleOrganizations: 1,
+				},
+			},
+		})
+		version := coderdtest.CreateTemplateVersion(t, owner, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, first.OrganizationID, version.ID)
+		org := coderdenttest.CreateOrganization(t, owner, coderdenttest.CreateOrganizationOptions{})
+		return owner, template, org
+	}
+
+	t.Run("Share", func(t *testing.T) {
+		t.Parallel()
+		owner, template, org := setup(t)
+		consumer, _ := coderdtest.CreateAnotherUser(t, owner, org.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The template is not visible before it is shared.
+		_, err := consumer.Template(ctx, template.ID)
+		require.Error(t, err)
+
+		//nolint:gocritic // Sharing requires access to both organizations.
+		share, err := owner.ShareTemplate(ctx, template.ID, org.ID)
+		require.NoError(t, err)
+		require.Equal(t, template.ID, share.TemplateID)
+		require.Equal(t, org.ID, share.OrganizationID)
+
+		// Sharing again keeps the original share.
+		//nolint:gocritic // Sharing requires access to both organizations.
+		again, err := owner.ShareTemplate(ctx, template.ID, org.ID)
+		require.NoError(t, err)
+		require.Equal(t, share.CreatedAt.UTC(), again.CreatedAt.UTC())
+
+		//nolint:gocritic // Only template managers can list shares.
+		shares, err := owner.TemplateShares(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, shares, 1)
+		require.Equal(t, org.ID, shares[0].OrganizationID)
+
+		// Members of the organization can read the template and its versions.
+		got, err := consumer.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, template.OrganizationID, got.OrganizationID)
+		versions, err := consumer.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+			TemplateID: template.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+
+		templates, err := consumer.Templates(ctx, codersdk.TemplateFilter{})
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, template.ID, templates[0].ID)
+
+		catalog, err := consumer.TemplatesSharedWithOrganization(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, catalog, 1)
+		require.Equal(t, template.ID, catalog[0].ID)
+
+		// But they cannot modify it, or see where it is shared.
+		_, err = consumer.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "changed",
+		})
+		require.Error(t, err)
+		_, err = consumer.TemplateShares(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Unsharing revokes access.
+		//nolint:gocritic // Sharing requires access to both organizations.
+		err = owner.UnshareTemplate(ctx, template.ID, org.ID)
+		require.NoError(t, err)
+		_, err = consumer.Template(ctx, template.ID)
+		require.Error(t, err)
+		catalog, err = consumer.TemplatesSharedWithOrganization(ctx, org.ID)
+		require.NoError(t, err)
+		require.Empty(t, catalog)
+	})
+
+	t.Run("OwnOrganization", func(t *testing.T) {
+		t.Parallel()
+		owner, template, _ := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // Sharing requires access to both organizations.
+		_, err := owner.ShareTemplate(ctx, template.ID, template.OrganizationID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberCannotShare", func(t *testing.T) {
+		t.Parallel()
+		owner, template, org := setup(t)
+		member, _ := coderdtest.CreateAnotherUser(t, owner, template.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.ShareTemplate(ctx, template.ID, org.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		//nolint:gocritic // Only template managers can list shares.
+		shares, err := owner.TemplateShares(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares)
+	})
+}
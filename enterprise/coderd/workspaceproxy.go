@@ -3,6 +3,8 @@ package coderd
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"fmt"
 	"net/http"
@@ -78,7 +80,9 @@ func (api *API) fetchRegions(ctx context.Context) (codersdk.RegionsResponse[code
 			continue
 		}
 		// Append the inner region data.
-		regions = append(regions, proxies.Regions[i].Region)
+		region := proxies.Regions[i].Region
+		region.PathAppsOnly = api.DeploymentValues.PathAppsOnly.Value()
+		regions = append(regions, region)
 	}
 
 	return codersdk.RegionsResponse[codersdk.Region]{
@@ -86,6 +90,75 @@ func (api *API) fetchRegions(ctx context.Context) (codersdk.RegionsResponse[code
 	}, nil
 }
 
+// @Summary Get failover region for workspace app traffic
+// @ID get-failover-region-for-workspace-app-traffic
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param region path string true "Region ID" format(uuid)
+// @Param preferred query string false "Comma-separated IDs of regions to fail over to, from closest to furthest"
+// @Success 200 {object} codersdk.RegionFailover
+// @Router /regions/{region}/failover [get]
+func (api *API) regionFailover(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	regionID, ok := httpmw.ParseUUIDParam(rw, r, "region")
+	if !ok {
+		return
+	}
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	preferred := p.UUIDs(vals, []uuid.UUID{}, "preferred")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	regions, err := api.fetchRegions(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	idx := slices.IndexFunc(regions.Regions, func(region codersdk.Region) bool {
+		return region.ID == regionID
+	})
+	if idx < 0 {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, failoverRegion(regions.Regions, regions.Regions[idx], preferred))
+}
+
+// failoverRegion returns the region that app traffic for the given region
+// should use. Healthy regions are used as is. Unhealthy regions fail over to
+// the first healthy region in preferred, which clients order by their latency
+// to each region, or the primary region, which is always healthy.
+func failoverRegion(regions []codersdk.Region, region codersdk.Region, preferred []uuid.UUID) codersdk.RegionFailover {
+	if region.Healthy {
+		return codersdk.RegionFailover{Region: region}
+	}
+
+	var primary codersdk.Region
+	byID := make(map[uuid.UUID]codersdk.Region, len(regions))
+	for _, r := range regions {
+		byID[r.ID] = r
+		if r.Name == "primary" {
+			primary = r
+		}
+	}
+	for _, id := range preferred {
+		if r, ok := byID[id]; ok && r.Healthy && r.ID != region.ID {
+			return codersdk.RegionFailover{Region: r, FailedOver: true}
+		}
+	}
+	return codersdk.RegionFailover{Region: primary, FailedOver: true}
+}
+
 // @Summary Update workspace proxy
 // @ID update-workspace-proxy
 // @Security CoderSessionToken
@@ -490,6 +563,16 @@ func (api *API) workspaceProxyIssueSignedAppToken(rw http.ResponseWriter, r *htt
 		return
 	}
 	userReq.Header.Set(codersdk.SessionTokenHeader, req.SessionToken)
+	if req.DeviceID != "" {
+		userReq.Header.Set(codersdk.DeviceIDHeader, req.DeviceID)
+	}
+	if len(req.ClientCertificate) > 0 {
+		// Only the raw certificate is needed to check the session token
+		// binding.
+		userReq.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Raw: req.ClientCertificate}},
+		}
+	}
 
 	// Exchange the token.
 	token, tokenStr, ok := api.AGPL.WorkspaceAppsProvider.Issue(ctx, rw, userReq, req)
@@ -3,7 +3,10 @@ package coderd
 import (
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
 )
 
 func Test_validateProxyURL(t *testing.T) {
@@ -59,3 +62,60 @@ func Test_validateProxyURL(t *testing.T) {
 		})
 	}
 }
+
+func Test_failoverRegion(t *testing.T) {
+	t.Parallel()
+
+	var (
+		primary   = codersdk.Region{ID: uuid.New(), Name: "primary", Healthy: true}
+		healthy   = codersdk.Region{ID: uuid.New(), Name: "healthy", Healthy: true}
+		unhealthy = codersdk.Region{ID: uuid.New(), Name: "unhealthy"}
+		other     = codersdk.Region{ID: uuid.New(), Name: "other"}
+		regions   = []codersdk.Region{primary, healthy, unhealthy, other}
+	)
+
+	testcases := []struct {
+		Name       string
+		Region     codersdk.Region
+		Preferred  []uuid.UUID
+		Expected   codersdk.Region
+		FailedOver bool
+	}{
+		{
+			Name:      "Healthy",
+			Region:    healthy,
+			Preferred: []uuid.UUID{primary.ID},
+			Expected:  healthy,
+		},
+		{
+			Name:       "Primary",
+			Region:     unhealthy,
+			Expected:   primary,
+			FailedOver: true,
+		},
+		{
+			Name:       "Preferred",
+			Region:     unhealthy,
+			Preferred:  []uuid.UUID{healthy.ID, primary.ID},
+			Expected:   healthy,
+			FailedOver: true,
+		},
+		{
+			Name:       "SkipUnhealthyPreferred",
+			Region:     unhealthy,
+			Preferred:  []uuid.UUID{unhealthy.ID, other.ID, uuid.New(), primary.ID, healthy.ID},
+			Expected:   primary,
+			FailedOver: true,
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+
+			failover := failoverRegion(regions, tt.Region, tt.Preferred)
+			require.Equal(t, tt.Expected, failover.Region)
+			require.Equal(t, tt.FailedOver, failover.FailedOver)
+		})
+	}
+}
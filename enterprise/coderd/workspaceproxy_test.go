@@ -181,6 +181,32 @@ func TestRegions(t *testing.T) {
 		require.WithinDuration(t, approxCreateTime, proxy.UpdatedAt, waitTime)
 	})
 
+	t.Run("Failover", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				AppHostname: appHostname,
+			},
+		})
+
+		regions, err := client.Regions(ctx)
+		require.NoError(t, err)
+		require.Len(t, regions, 1)
+
+		// The primary region is always healthy, so it never fails over.
+		failover, err := client.RegionFailover(ctx, regions[0].ID, []uuid.UUID{uuid.New()})
+		require.NoError(t, err)
+		require.False(t, failover.FailedOver)
+		require.Equal(t, regions[0], failover.Region)
+
+		_, err = client.RegionFailover(ctx, uuid.New(), nil)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
 	t.Run("RequireAuth", func(t *testing.T) {
 		t.Parallel()
 
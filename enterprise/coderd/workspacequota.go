@@ -12,6 +12,7 @@ import (
 	"cdr.dev/slog"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
@@ -42,9 +43,11 @@ func (c *committer) CommitQuota(
 	}
 
 	var (
-		consumed int64
-		budget   int64
-		permit   bool
+		consumed      int64
+		budget        int64
+		monthlyBudget int64
+		monthlySpent  float64
+		permit        bool
 	)
 	err = c.Database.InTx(func(s database.Store) error {
 		var err error
@@ -64,6 +67,24 @@ func (c *committer) CommitQuota(
 			return err
 		}
 
+		monthlyBudget, err = s.GetQuotaMonthlyBudgetForUser(ctx, database.GetQuotaMonthlyBudgetForUserParams{
+			UserID:         workspace.OwnerID,
+			OrganizationID: workspace.OrganizationID,
+		})
+		if err != nil {
+			return err
+		}
+		if monthlyBudget > 0 {
+			monthlySpent, err = s.GetQuotaMonthlySpendForUser(ctx, database.GetQuotaMonthlySpendForUserParams{
+				OwnerID:        workspace.OwnerID,
+				OrganizationID: workspace.OrganizationID,
+				StartTime:      dbtime.StartOfMonth(dbtime.Now()),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		// If the new build will reduce overall quota consumption, then we
 		// allow it even if the user is over quota.
 		netIncrease := true
@@ -93,6 +114,18 @@ func (c *committer) CommitQuota(
 			)
 			return nil
 		}
+		// Once the monthly budget is spent, workspaces may no longer be
+		// started until the next month, since they would keep accruing
+		// costs. Stopping and deleting workspaces is always allowed.
+		if monthlyBudget > 0 && monthlySpent >= float64(monthlyBudget) &&
+			nextBuild.Transition == database.WorkspaceTransitionStart && netIncrease {
+			c.Log.Debug(
+				ctx, "monthly budget spent, rejecting",
+				slog.F("monthly_spent", monthlySpent),
+				slog.F("monthly_budget", monthlyBudget),
+			)
+			return nil
+		}
 
 		err = s.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
 			ID:        nextBuild.ID,
@@ -118,6 +151,9 @@ func (c *committer) CommitQuota(
 		CreditsConsumed: int32(consumed),
 		// #nosec G115 - Safe conversion as quota budget value is expected to be within int32 range
 		Budget: int32(budget),
+		// #nosec G115 - Safe conversion as the monthly budget is expected to be within int32 range
+		MonthlyBudget:       int32(monthlyBudget),
+		MonthlyCreditsSpent: monthlySpent,
 	}, nil
 }
 
@@ -189,8 +225,44 @@ func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceQuota{
+	quota := codersdk.WorkspaceQuota{
 		CreditsConsumed: int(quotaConsumed),
 		Budget:          int(quotaAllowance),
-	})
+	}
+	// Monthly budgets are granted by groups, like the allowance.
+	if licensed {
+		monthlyBudget, err := api.Database.GetQuotaMonthlyBudgetForUser(r.Context(), database.GetQuotaMonthlyBudgetForUserParams{
+			UserID:         user.ID,
+			OrganizationID: organization.ID,
+		})
+		if err != nil {
+			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get monthly budget",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if monthlyBudget > 0 {
+			monthStart := dbtime.StartOfMonth(dbtime.Now())
+			monthlySpent, err := api.Database.GetQuotaMonthlySpendForUser(r.Context(), database.GetQuotaMonthlySpendForUserParams{
+				OwnerID:        user.ID,
+				OrganizationID: organization.ID,
+				StartTime:      monthStart,
+			})
+			if err != nil {
+				httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Failed to get monthly spend",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			resetsAt := monthStart.AddDate(0, 1, 0)
+			quota.MonthlyBudget = int(monthlyBudget)
+			quota.MonthlyCreditsSpent = monthlySpent
+			quota.MonthlyCreditsRemaining = max(float64(monthlyBudget)-monthlySpent, 0)
+			quota.MonthlyBudgetResetsAt = &resetsAt
+		}
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, quota)
 }
@@ -63,6 +63,22 @@ func verifyQuotaUser(ctx context.Context, t *testing.T, client *codersdk.Client,
 	}
 }
 
+// requireQuotaExceeded asserts that creating a workspace from the template is
+// rejected by the quota pre-flight check, rather than failing the build.
+func requireQuotaExceeded(ctx context.Context, t *testing.T, client *codersdk.Client, templateID uuid.UUID) {
+	t.Helper()
+	_, err := client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: templateID,
+		Name:       coderdtest.RandomUsername(t),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Contains(t, apiErr.Message, "quota")
+	require.Len(t, apiErr.Validations, 1)
+	require.Equal(t, "quota", apiErr.Validations[0].Field)
+}
+
 func TestWorkspaceQuota(t *testing.T) {
 	t.Parallel()
 
@@ -158,13 +174,10 @@ func TestWorkspaceQuota(t *testing.T) {
 		verifyQuota(ctx, t, client, user.OrganizationID.String(), 4, 4)
 
 		// Next one must fail
-		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
-		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		requireQuotaExceeded(ctx, t, client, template.ID)
 
 		// Consumed shouldn't bump
 		verifyQuota(ctx, t, client, user.OrganizationID.String(), 4, 4)
-		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
-		require.Contains(t, build.Job.Error, "quota")
 
 		// Delete one random workspace, then quota should recover.
 		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
@@ -183,8 +196,8 @@ func TestWorkspaceQuota(t *testing.T) {
 		}
 
 		// Next one should now succeed
-		workspace = coderdtest.CreateWorkspace(t, client, template.ID)
-		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
 
 		verifyQuota(ctx, t, client, user.OrganizationID.String(), 4, 4)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
@@ -243,15 +256,12 @@ func TestWorkspaceQuota(t *testing.T) {
 		verifyQuota(ctx, t, client, user.OrganizationID.String(), 4, 4)
 
 		// Next one must fail
-		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
-		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
-		require.Contains(t, build.Job.Error, "quota")
+		requireQuotaExceeded(ctx, t, client, template.ID)
 
 		// Consumed shouldn't bump
 		verifyQuota(ctx, t, client, user.OrganizationID.String(), 4, 4)
-		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
 
-		build = coderdtest.CreateWorkspaceBuild(t, client, workspaces[0], database.WorkspaceTransitionStop)
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspaces[0], database.WorkspaceTransitionStop)
 		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
 
 		// Quota goes down one
@@ -307,6 +317,107 @@ func TestWorkspaceQuota(t *testing.T) {
 		verifyQuota(ctx, t, owner, second.ID.String(), 0, 15)
 	})
 
+	t.Run("MonthlyBudget", func(t *testing.T) {
+		t.Parallel()
+
+		db, ps := dbtestutil.NewDB(t)
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database:                 db,
+				Pubsub:                   ps,
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
+			QuotaAllowance: ptr.Ref(10),
+			MonthlyBudget:  ptr.Ref(5),
+		})
+		require.NoError(t, err)
+
+		quota, err := client.WorkspaceQuota(ctx, user.OrganizationID.String(), codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 5, quota.MonthlyBudget)
+		require.Zero(t, quota.MonthlyCreditsSpent)
+		require.Equal(t, 5.0, quota.MonthlyCreditsRemaining)
+		require.NotNil(t, quota.MonthlyBudgetResetsAt)
+		require.True(t, dbtime.StartOfMonth(dbtime.Now()).AddDate(0, 1, 0).Equal(*quota.MonthlyBudgetResetsAt))
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: []*proto.Resource{{
+							Name:      "example",
+							Type:      "aws_instance",
+							DailyCost: 1,
+						}},
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		// Seed an expensive workspace that has been running for a minute, so
+		// that the monthly budget is spent once costs are rolled up.
+		expensive := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			TemplateID:     template.ID,
+			OwnerID:        user.UserID,
+		})
+		job := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			OrganizationID: user.OrganizationID,
+			StartedAt:      sql.NullTime{Time: dbtime.Now().Add(-2 * time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: dbtime.Now().Add(-time.Minute), Valid: true},
+		})
+		_ = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{
+			JobID:      job.ID,
+			Transition: database.WorkspaceTransitionStart,
+			DailyCost:  1_000_000_000,
+		})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       expensive.ID,
+			JobID:             job.ID,
+			TemplateVersionID: version.ID,
+			BuildNumber:       1,
+			Transition:        database.WorkspaceTransitionStart,
+		})
+		require.NoError(t, db.UpsertWorkspaceDailyCosts(ctx))
+
+		quota, err = client.WorkspaceQuota(ctx, user.OrganizationID.String(), codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 5, quota.MonthlyBudget)
+		require.GreaterOrEqual(t, quota.MonthlyCreditsSpent, 5.0)
+		require.Zero(t, quota.MonthlyCreditsRemaining)
+
+		// Starting workspaces is rejected until the budget resets.
+		_, err = client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       coderdtest.RandomUsername(t),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "Insufficient monthly budget")
+		require.Equal(t, codersdk.ResponseCodeQuotaExceeded, apiErr.Code)
+
+		// Stopping workspaces is still allowed.
+		build = coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusStopped, build.Status)
+	})
+
 	// ManyWorkspaces uses dbfake and dbgen to insert a scenario into the db.
 	t.Run("ManyWorkspaces", func(t *testing.T) {
 		t.Parallel()
@@ -600,7 +600,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker = make(chan time.Time)
+			clock  = coderdtest.NewAutobuildClock(t)
 			statCh = make(chan autobuild.Stats)
 			logger = slogtest.Make(t, &slogtest.Options{
 				// We ignore errors here since we expect to fail
@@ -613,7 +613,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
 				Logger:                   &logger,
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -635,7 +635,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		ws := coderdtest.CreateWorkspace(t, client, template.ID)
 		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
 		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
-		ticker <- build.Job.CompletedAt.Add(failureTTL * 2)
+		clock.Tick(build.Job.CompletedAt.Add(failureTTL * 2))
 		stats := <-statCh
 		// Expect workspace to transition to stopped state for breaching
 		// failure TTL.
@@ -647,7 +647,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker = make(chan time.Time)
+			clock  = coderdtest.NewAutobuildClock(t)
 			statCh = make(chan autobuild.Stats)
 			logger = slogtest.Make(t, &slogtest.Options{
 				// We ignore errors here since we expect to fail
@@ -660,7 +660,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
 				Logger:                   &logger,
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -682,7 +682,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
 		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
 		// Make it impossible to trigger the failure TTL.
-		ticker <- build.Job.CompletedAt.Add(-failureTTL * 2)
+		clock.Tick(build.Job.CompletedAt.Add(-failureTTL * 2))
 		stats := <-statCh
 		// Expect no transitions since not enough time has elapsed.
 		require.Len(t, stats.Transitions, 0)
@@ -694,7 +694,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker = make(chan time.Time)
+			clock  = coderdtest.NewAutobuildClock(t)
 			statCh = make(chan autobuild.Stats)
 			logger = slogtest.Make(t, &slogtest.Options{
 				// We ignore errors here since we expect to fail
@@ -706,7 +706,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
 				Logger:                   &logger,
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -730,7 +730,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		ws := coderdtest.CreateWorkspace(t, client, template.ID)
 		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
-		ticker <- time.Now()
+		clock.Tick(time.Now())
 		stats := <-statCh
 		// Expect no transitions since the fields are unset on the template.
 		require.Len(t, stats.Transitions, 0)
@@ -740,7 +740,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker        = make(chan time.Time)
+			clock         = coderdtest.NewAutobuildClock(t)
 			statCh        = make(chan autobuild.Stats)
 			inactiveTTL   = time.Minute
 			auditRecorder = audit.NewMock()
@@ -750,7 +750,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 
 		client, db, user := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:       ticker,
+				AutobuildClock:        clock,
 				AutobuildStats:        statCh,
 				TemplateScheduleStore: schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
 				Auditor:               auditRecorder,
@@ -781,7 +781,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 
 		auditRecorder.ResetLogs()
 		// Simulate being inactive.
-		ticker <- workspace.LastUsedAt.Add(inactiveTTL * 2)
+		clock.Tick(workspace.LastUsedAt.Add(inactiveTTL * 2))
 		stats := <-statCh
 
 		// Expect workspace to transition to stopped state for breaching
@@ -826,7 +826,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		}
 
 		var (
-			ticker      = make(chan time.Time)
+			clock       = coderdtest.NewAutobuildClock(t)
 			statCh      = make(chan autobuild.Stats)
 			inactiveTTL = time.Minute
 		)
@@ -847,7 +847,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
 				Database:                 db,
@@ -879,7 +879,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		}
 
 		// Simulate being inactive.
-		ticker <- time.Now().Add(time.Hour)
+		clock.Tick(time.Now().Add(time.Hour))
 		stats := <-statCh
 
 		// Expect workspace to transition to stopped state for breaching
@@ -896,7 +896,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker      = make(chan time.Time)
+			clock       = coderdtest.NewAutobuildClock(t)
 			statCh      = make(chan autobuild.Stats)
 			inactiveTTL = time.Minute
 		)
@@ -904,7 +904,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -926,7 +926,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
 		// Make it impossible to trigger the inactive ttl.
-		ticker <- ws.LastUsedAt.Add(-inactiveTTL)
+		clock.Tick(ws.LastUsedAt.Add(-inactiveTTL))
 		stats := <-statCh
 		// Expect no transitions since not enough time has elapsed.
 		require.Len(t, stats.Transitions, 0)
@@ -939,7 +939,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker        = make(chan time.Time)
+			clock         = coderdtest.NewAutobuildClock(t)
 			statCh        = make(chan autobuild.Stats)
 			autoDeleteTTL = time.Minute
 		)
@@ -947,7 +947,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -969,7 +969,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
 		require.Nil(t, ws.DormantAt)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
-		ticker <- ws.LastUsedAt.Add(autoDeleteTTL * 2)
+		clock.Tick(ws.LastUsedAt.Add(autoDeleteTTL * 2))
 		stats := <-statCh
 		// Expect no transitions since workspace is active.
 		require.Len(t, stats.Transitions, 0)
@@ -982,7 +982,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker      = make(chan time.Time)
+			clock       = coderdtest.NewAutobuildClock(t)
 			statCh      = make(chan autobuild.Stats)
 			inactiveTTL = time.Minute
 		)
@@ -990,7 +990,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -1020,7 +1020,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		ws = coderdtest.MustTransitionWorkspace(t, client, ws.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 		// Simulate not having accessed the workspace in a while.
-		ticker <- ws.LastUsedAt.Add(2 * inactiveTTL)
+		clock.Tick(ws.LastUsedAt.Add(2 * inactiveTTL))
 		stats := <-statCh
 		// Expect no transitions since workspace is stopped.
 		require.Len(t, stats.Transitions, 0)
@@ -1036,7 +1036,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker        = make(chan time.Time)
+			clock         = coderdtest.NewAutobuildClock(t)
 			statCh        = make(chan autobuild.Stats)
 			transitionTTL = time.Minute
 		)
@@ -1044,7 +1044,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -1070,7 +1070,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
 
 		// Simulate not having accessed the workspace in a while.
-		ticker <- ws.LastUsedAt.Add(2 * transitionTTL)
+		clock.Tick(ws.LastUsedAt.Add(2 * transitionTTL))
 		stats := <-statCh
 		// Expect workspace to transition to stopped state for breaching
 		// inactive TTL.
@@ -1085,7 +1085,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		_ = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
 
 		// Simulate the workspace being dormant beyond the threshold.
-		ticker <- ws.DormantAt.Add(2 * transitionTTL)
+		clock.Tick(ws.DormantAt.Add(2 * transitionTTL))
 		stats = <-statCh
 		require.Len(t, stats.Transitions, 1)
 		// The workspace should be scheduled for deletion.
@@ -1108,7 +1108,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker     = make(chan time.Time)
+			clock      = coderdtest.NewAutobuildClock(t)
 			statCh     = make(chan autobuild.Stats)
 			dormantTTL = time.Minute
 		)
@@ -1116,7 +1116,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -1149,7 +1149,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		require.NotNil(t, ws.DormantAt)
 
 		// Ensure we haven't breached our threshold.
-		ticker <- ws.DormantAt.Add(-dormantTTL * 2)
+		clock.Tick(ws.DormantAt.Add(-dormantTTL * 2))
 		stats := <-statCh
 		// Expect no transitions since not enough time has elapsed.
 		require.Len(t, stats.Transitions, 0)
@@ -1160,7 +1160,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		require.NoError(t, err)
 
 		// Simlute the workspace breaching the threshold.
-		ticker <- ws.DormantAt.Add(dormantTTL * 2)
+		clock.Tick(ws.DormantAt.Add(dormantTTL * 2))
 		stats = <-statCh
 		require.Len(t, stats.Transitions, 1)
 		require.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[ws.ID])
@@ -1171,7 +1171,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			tickCh      = make(chan time.Time)
+			clock       = coderdtest.NewAutobuildClock(t)
 			statsCh     = make(chan autobuild.Stats)
 			inactiveTTL = time.Minute
 		)
@@ -1179,7 +1179,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          tickCh,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statsCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -1208,7 +1208,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		ws = coderdtest.MustTransitionWorkspace(t, client, ws.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 		// Assert that autostart works when the workspace isn't dormant..
-		tickCh <- sched.Next(ws.LatestBuild.CreatedAt)
+		clock.Tick(sched.Next(ws.LatestBuild.CreatedAt))
 		stats := <-statsCh
 		require.Len(t, stats.Errors, 0)
 		require.Len(t, stats.Transitions, 1)
@@ -1228,7 +1228,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		require.NoError(t, err)
 
 		// We should see the workspace get stopped now.
-		tickCh <- ws.LastUsedAt.Add(inactiveTTL * 2)
+		clock.Tick(ws.LastUsedAt.Add(inactiveTTL * 2))
 		stats = <-statsCh
 		require.Len(t, stats.Errors, 0)
 		require.Len(t, stats.Transitions, 1)
@@ -1241,7 +1241,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		require.NotNil(t, ws.DormantAt)
 
 		// Assert that autostart is no longer triggered since workspace is dormant.
-		tickCh <- sched.Next(ws.LatestBuild.CreatedAt)
+		clock.Tick(sched.Next(ws.LatestBuild.CreatedAt))
 		stats = <-statsCh
 		require.Len(t, stats.Transitions, 0)
 	})
@@ -1252,7 +1252,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			ticker        = make(chan time.Time)
+			clock         = coderdtest.NewAutobuildClock(t)
 			statCh        = make(chan autobuild.Stats)
 			transitionTTL = time.Minute
 		)
@@ -1260,7 +1260,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          ticker,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -1326,13 +1326,13 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		// Simulate ticking an hour after the workspace is expected to be deleted.
 		// Under normal circumstances this should result in a transition but
 		// since our last build resulted in failure it should be skipped.
-		ticker <- build.Job.CompletedAt.Add(time.Hour)
+		clock.Tick(build.Job.CompletedAt.Add(time.Hour))
 		stats := <-statCh
 		require.Len(t, stats.Transitions, 0)
 
 		// Simulate ticking a day after the workspace was last attempted to
 		// be deleted. This should result in an attempt.
-		ticker <- build.Job.CompletedAt.Add(time.Hour * 25)
+		clock.Tick(build.Job.CompletedAt.Add(time.Hour * 25))
 		stats = <-statCh
 		require.Len(t, stats.Transitions, 1)
 		require.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[ws.ID])
@@ -1342,14 +1342,14 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			tickCh  = make(chan time.Time)
+			clock   = coderdtest.NewAutobuildClock(t)
 			statsCh = make(chan autobuild.Stats)
 		)
 
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          tickCh,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statsCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -1392,7 +1392,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		require.NoError(t, err)
 
 		// Kick of an autostart build.
-		tickCh <- sched.Next(ws.LatestBuild.CreatedAt)
+		clock.Tick(sched.Next(ws.LatestBuild.CreatedAt))
 		stats := <-statsCh
 		require.Len(t, stats.Errors, 0)
 		require.Len(t, stats.Transitions, 1)
@@ -1420,7 +1420,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		})
 
 		// Force an autostart transition again.
-		tickCh <- sched.Next(firstBuild.CreatedAt)
+		clock.Tick(sched.Next(firstBuild.CreatedAt))
 		stats = <-statsCh
 		require.Len(t, stats.Errors, 0)
 		require.Len(t, stats.Transitions, 1)
@@ -1436,9 +1436,9 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			tickCh  = make(chan time.Time)
-			statsCh = make(chan autobuild.Stats)
-			clock   = quartz.NewMock(t)
+			autobuildClock = coderdtest.NewAutobuildClock(t)
+			statsCh        = make(chan autobuild.Stats)
+			clock          = quartz.NewMock(t)
 		)
 
 		clock.Set(dbtime.Now())
@@ -1446,7 +1446,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          tickCh,
+				AutobuildClock:           autobuildClock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statsCh,
 				Logger:                   &logger,
@@ -1485,7 +1485,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 			next = sched.Next(next)
 
 			clock.Set(next)
-			tickCh <- next
+			autobuildClock.Tick(next)
 			stats := <-statsCh
 			ws = coderdtest.MustWorkspace(t, client, ws.ID)
 
@@ -1522,7 +1522,6 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		t.Parallel()
 
 		var (
-			tickCh  = make(chan time.Time)
 			statsCh = make(chan autobuild.Stats)
 			clock   = quartz.NewMock(t)
 		)
@@ -1536,7 +1535,6 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		templateScheduleStore.Clock = clock
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          tickCh,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statsCh,
 				Logger:                   &logger,
@@ -1593,14 +1591,14 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		}
 
 		var (
-			tickCh  = make(chan time.Time)
+			clock   = coderdtest.NewAutobuildClock(t)
 			statsCh = make(chan autobuild.Stats)
 		)
 
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, db, user := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          tickCh,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statsCh,
 				Logger:                   &logger,
@@ -1658,7 +1656,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		// Now we let the lifecycle executor run. This should spot that the
 		// NextStartAt is null and update it for us.
 		next := dbtime.Now()
-		tickCh <- next
+		clock.Tick(next)
 		stats := <-statsCh
 		assert.Len(t, stats.Errors, 0)
 		assert.Len(t, stats.Transitions, 0)
@@ -2124,13 +2122,13 @@ func TestExecutorAutostartBlocked(t *testing.T) {
 
 	var (
 		sched   = must(cron.Weekly("CRON_TZ=UTC 0 * * * *"))
-		tickCh  = make(chan time.Time)
+		clock   = coderdtest.NewAutobuildClock(t)
 		statsCh = make(chan autobuild.Stats)
 
 		logger        = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
 		client, owner = coderdenttest.New(t, &coderdenttest.Options{
 			Options: &coderdtest.Options{
-				AutobuildTicker:          tickCh,
+				AutobuildClock:           clock,
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statsCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore(), notifications.NewNoopEnqueuer(), logger, nil),
@@ -2156,10 +2154,7 @@ func TestExecutorAutostartBlocked(t *testing.T) {
 	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
 
 	// When: the autobuild executor ticks into the future
-	go func() {
-		tickCh <- workspace.LatestBuild.CreatedAt.Add(2 * time.Hour)
-		close(tickCh)
-	}()
+	clock.Tick(workspace.LatestBuild.CreatedAt.Add(2 * time.Hour))
 
 	// Then: the workspace should not be started.
 	stats := <-statsCh
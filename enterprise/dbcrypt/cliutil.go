@@ -11,8 +11,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 )
 
-// Rotate rotates the database encryption keys by re-encrypting all user tokens
-// with the first cipher and revoking all other ciphers.
+// Rotate rotates the database encryption keys by re-encrypting all user tokens,
+// sensitive template version variables, workspace migration session tokens and
+// encrypted session recordings with the first cipher and revoking all other
+// ciphers. Session recordings that were stored before encryption was enabled
+// are left unencrypted until they expire.
 func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
 	cryptDB, err := New(ctx, db, ciphers...)
@@ -83,6 +86,67 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 		log.Debug(ctx, "encrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	variables, err := cryptDB.GetSensitiveTemplateVersionVariables(ctx)
+	if err != nil {
+		return xerrors.Errorf("get sensitive template version variables: %w", err)
+	}
+	log.Info(ctx, "encrypting sensitive template version variables", slog.F("variable_count", len(variables)))
+	for idx, variable := range variables {
+		if variable.ValueKeyID.String == ciphers[0].HexDigest() {
+			log.Debug(ctx, "skipping template version variable", slog.F("template_version_id", variable.TemplateVersionID), slog.F("name", variable.Name), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+			continue
+		}
+		if err := cryptDB.UpdateTemplateVersionVariableValue(ctx, database.UpdateTemplateVersionVariableValueParams{
+			TemplateVersionID: variable.TemplateVersionID,
+			Name:              variable.Name,
+			Value:             variable.Value,
+			ValueKeyID:        sql.NullString{}, // dbcrypt will update as required
+		}); err != nil {
+			return xerrors.Errorf("update template version variable template_version_id=%s name=%s: %w", variable.TemplateVersionID, variable.Name, err)
+		}
+		log.Debug(ctx, "encrypted template version variable", slog.F("template_version_id", variable.TemplateVersionID), slog.F("name", variable.Name), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+	}
+
+	migrations, err := cryptDB.GetActiveWorkspaceMigrations(ctx)
+	if err != nil {
+		return xerrors.Errorf("get active workspace migrations: %w", err)
+	}
+	log.Info(ctx, "encrypting workspace migration session tokens", slog.F("migration_count", len(migrations)))
+	for idx, migration := range migrations {
+		if migration.DestinationSessionTokenKeyID.String == ciphers[0].HexDigest() {
+			log.Debug(ctx, "skipping workspace migration", slog.F("workspace_migration_id", migration.ID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+			continue
+		}
+		if err := cryptDB.UpdateWorkspaceMigrationSessionToken(ctx, database.UpdateWorkspaceMigrationSessionTokenParams{
+			ID:                           migration.ID,
+			DestinationSessionToken:      migration.DestinationSessionToken,
+			DestinationSessionTokenKeyID: sql.NullString{}, // dbcrypt will update as required
+		}); err != nil {
+			return xerrors.Errorf("update workspace migration id=%s: %w", migration.ID, err)
+		}
+		log.Debug(ctx, "encrypted workspace migration session token", slog.F("workspace_migration_id", migration.ID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+	}
+
+	chunks, err := cryptDB.GetEncryptedWorkspaceSessionRecordingChunks(ctx)
+	if err != nil {
+		return xerrors.Errorf("get encrypted workspace session recording chunks: %w", err)
+	}
+	log.Info(ctx, "encrypting workspace session recordings", slog.F("chunk_count", len(chunks)))
+	for idx, chunk := range chunks {
+		if chunk.DataKeyID.String == ciphers[0].HexDigest() {
+			log.Debug(ctx, "skipping workspace session recording chunk", slog.F("chunk_id", chunk.ID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+			continue
+		}
+		if err := cryptDB.UpdateWorkspaceSessionRecordingChunkData(ctx, database.UpdateWorkspaceSessionRecordingChunkDataParams{
+			ID:        chunk.ID,
+			Data:      chunk.Data,
+			DataKeyID: sql.NullString{}, // dbcrypt will update as required
+		}); err != nil {
+			return xerrors.Errorf("update workspace session recording chunk id=%d: %w", chunk.ID, err)
+		}
+		log.Debug(ctx, "encrypted workspace session recording chunk", slog.F("chunk_id", chunk.ID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+	}
+
 	// Revoke old keys
 	for _, c := range ciphers[1:] {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -94,7 +158,9 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 	return nil
 }
 
-// Decrypt decrypts all user tokens and revokes all ciphers.
+// Decrypt decrypts all user tokens, sensitive template version variables,
+// workspace migration session tokens and session recordings and revokes all
+// ciphers.
 func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
 	cdb, err := New(ctx, db, ciphers...)
@@ -173,6 +239,63 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 		log.Debug(ctx, "decrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	variables, err := cryptDB.GetSensitiveTemplateVersionVariables(ctx)
+	if err != nil {
+		return xerrors.Errorf("get sensitive template version variables: %w", err)
+	}
+	log.Info(ctx, "decrypting sensitive template version variables", slog.F("variable_count", len(variables)))
+	for idx, variable := range variables {
+		if !variable.ValueKeyID.Valid {
+			log.Debug(ctx, "skipping template version variable", slog.F("template_version_id", variable.TemplateVersionID), slog.F("name", variable.Name), slog.F("current", idx+1))
+			continue
+		}
+		if err := cryptDB.UpdateTemplateVersionVariableValue(ctx, database.UpdateTemplateVersionVariableValueParams{
+			TemplateVersionID: variable.TemplateVersionID,
+			Name:              variable.Name,
+			Value:             variable.Value,
+			ValueKeyID:        sql.NullString{}, // we explicitly want to clear the key id
+		}); err != nil {
+			return xerrors.Errorf("update template version variable template_version_id=%s name=%s: %w", variable.TemplateVersionID, variable.Name, err)
+		}
+		log.Debug(ctx, "decrypted template version variable", slog.F("template_version_id", variable.TemplateVersionID), slog.F("name", variable.Name), slog.F("current", idx+1))
+	}
+
+	migrations, err := cryptDB.GetActiveWorkspaceMigrations(ctx)
+	if err != nil {
+		return xerrors.Errorf("get active workspace migrations: %w", err)
+	}
+	log.Info(ctx, "decrypting workspace migration session tokens", slog.F("migration_count", len(migrations)))
+	for idx, migration := range migrations {
+		if !migration.DestinationSessionTokenKeyID.Valid {
+			log.Debug(ctx, "skipping workspace migration", slog.F("workspace_migration_id", migration.ID), slog.F("current", idx+1))
+			continue
+		}
+		if err := cryptDB.UpdateWorkspaceMigrationSessionToken(ctx, database.UpdateWorkspaceMigrationSessionTokenParams{
+			ID:                           migration.ID,
+			DestinationSessionToken:      migration.DestinationSessionToken,
+			DestinationSessionTokenKeyID: sql.NullString{}, // we explicitly want to clear the key id
+		}); err != nil {
+			return xerrors.Errorf("update workspace migration id=%s: %w", migration.ID, err)
+		}
+		log.Debug(ctx, "decrypted workspace migration session token", slog.F("workspace_migration_id", migration.ID), slog.F("current", idx+1))
+	}
+
+	chunks, err := cryptDB.GetEncryptedWorkspaceSessionRecordingChunks(ctx)
+	if err != nil {
+		return xerrors.Errorf("get encrypted workspace session recording chunks: %w", err)
+	}
+	log.Info(ctx, "decrypting workspace session recordings", slog.F("chunk_count", len(chunks)))
+	for idx, chunk := range chunks {
+		if err := cryptDB.UpdateWorkspaceSessionRecordingChunkData(ctx, database.UpdateWorkspaceSessionRecordingChunkDataParams{
+			ID:        chunk.ID,
+			Data:      chunk.Data,
+			DataKeyID: sql.NullString{}, // we explicitly want to clear the key id
+		}); err != nil {
+			return xerrors.Errorf("update workspace session recording chunk id=%d: %w", chunk.ID, err)
+		}
+		log.Debug(ctx, "decrypted workspace session recording chunk", slog.F("chunk_id", chunk.ID), slog.F("current", idx+1))
+	}
+
 	// Revoke _all_ keys
 	for _, c := range ciphers {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -193,10 +316,20 @@ DELETE FROM user_links
 DELETE FROM external_auth_links
 	WHERE oauth_access_token_key_id IS NOT NULL
 	OR oauth_refresh_token_key_id IS NOT NULL;
+UPDATE template_version_variables
+	SET value = '', value_key_id = NULL
+	WHERE value_key_id IS NOT NULL;
+UPDATE workspace_migrations
+	SET destination_session_token = '', destination_session_token_key_id = NULL
+	WHERE destination_session_token_key_id IS NOT NULL;
+DELETE FROM workspace_session_recording_chunks
+	WHERE data_key_id IS NOT NULL;
 COMMIT;
 `
 
-// Delete deletes all user tokens and revokes all ciphers.
+// Delete deletes all user tokens and encrypted session recording output,
+// clears the values of encrypted template version variables and workspace
+// migration session tokens and revokes all ciphers.
 // This is a destructive operation and should only be used
 // as a last resort, for example, if the database encryption key has been
 // lost.
@@ -351,6 +351,187 @@ func (db *dbCrypt) GetCryptoKeysByFeature(ctx context.Context, feature database.
 	return keys, nil
 }
 
+func (db *dbCrypt) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
+	variables, err := db.Store.GetTemplateVersionVariables(ctx, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range variables {
+		if err := db.decryptField(&variables[i].Value, variables[i].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return variables, nil
+}
+
+func (db *dbCrypt) GetSensitiveTemplateVersionVariables(ctx context.Context) ([]database.TemplateVersionVariable, error) {
+	variables, err := db.Store.GetSensitiveTemplateVersionVariables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range variables {
+		if err := db.decryptField(&variables[i].Value, variables[i].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return variables, nil
+}
+
+// InsertTemplateVersionVariable only encrypts the values of sensitive
+// variables.
+func (db *dbCrypt) InsertTemplateVersionVariable(ctx context.Context, params database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
+	if params.Sensitive {
+		if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+			return database.TemplateVersionVariable{}, err
+		}
+	}
+	variable, err := db.Store.InsertTemplateVersionVariable(ctx, params)
+	if err != nil {
+		return database.TemplateVersionVariable{}, err
+	}
+	if err := db.decryptField(&variable.Value, variable.ValueKeyID); err != nil {
+		return database.TemplateVersionVariable{}, err
+	}
+	return variable, nil
+}
+
+// UpdateTemplateVersionVariableValue is only used to re-encrypt the values of
+// sensitive variables, so the value is always encrypted.
+func (db *dbCrypt) UpdateTemplateVersionVariableValue(ctx context.Context, params database.UpdateTemplateVersionVariableValueParams) error {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateTemplateVersionVariableValue(ctx, params)
+}
+
+func (db *dbCrypt) GetWorkspaceMigrationByID(ctx context.Context, id uuid.UUID) (database.WorkspaceMigration, error) {
+	migration, err := db.Store.GetWorkspaceMigrationByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	if err := db.decryptField(&migration.DestinationSessionToken, migration.DestinationSessionTokenKeyID); err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	return migration, nil
+}
+
+func (db *dbCrypt) GetWorkspaceMigrationsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceMigration, error) {
+	migrations, err := db.Store.GetWorkspaceMigrationsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range migrations {
+		if err := db.decryptField(&migrations[i].DestinationSessionToken, migrations[i].DestinationSessionTokenKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return migrations, nil
+}
+
+func (db *dbCrypt) GetActiveWorkspaceMigrations(ctx context.Context) ([]database.WorkspaceMigration, error) {
+	migrations, err := db.Store.GetActiveWorkspaceMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range migrations {
+		if err := db.decryptField(&migrations[i].DestinationSessionToken, migrations[i].DestinationSessionTokenKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return migrations, nil
+}
+
+func (db *dbCrypt) InsertWorkspaceMigration(ctx context.Context, params database.InsertWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	if err := db.encryptField(&params.DestinationSessionToken, &params.DestinationSessionTokenKeyID); err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	migration, err := db.Store.InsertWorkspaceMigration(ctx, params)
+	if err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	if err := db.decryptField(&migration.DestinationSessionToken, migration.DestinationSessionTokenKeyID); err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	return migration, nil
+}
+
+func (db *dbCrypt) UpdateWorkspaceMigrationStatus(ctx context.Context, params database.UpdateWorkspaceMigrationStatusParams) (database.WorkspaceMigration, error) {
+	migration, err := db.Store.UpdateWorkspaceMigrationStatus(ctx, params)
+	if err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	if err := db.decryptField(&migration.DestinationSessionToken, migration.DestinationSessionTokenKeyID); err != nil {
+		return database.WorkspaceMigration{}, err
+	}
+	return migration, nil
+}
+
+// CompleteWorkspaceMigration clears the session token of the migration, so
+// there is nothing to decrypt.
+func (db *dbCrypt) CompleteWorkspaceMigration(ctx context.Context, params database.CompleteWorkspaceMigrationParams) (database.WorkspaceMigration, error) {
+	return db.Store.CompleteWorkspaceMigration(ctx, params)
+}
+
+// UpdateWorkspaceMigrationSessionToken is only used to re-encrypt the session
+// tokens of active migrations. Cleared tokens are not encrypted.
+func (db *dbCrypt) UpdateWorkspaceMigrationSessionToken(ctx context.Context, params database.UpdateWorkspaceMigrationSessionTokenParams) error {
+	if params.DestinationSessionToken != "" {
+		if err := db.encryptField(&params.DestinationSessionToken, &params.DestinationSessionTokenKeyID); err != nil {
+			return err
+		}
+	}
+	return db.Store.UpdateWorkspaceMigrationSessionToken(ctx, params)
+}
+
+func (db *dbCrypt) GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	chunks, err := db.Store.GetWorkspaceSessionRecordingChunks(ctx, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range chunks {
+		if err := db.decryptBytesField(&chunks[i].Data, chunks[i].DataKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return chunks, nil
+}
+
+func (db *dbCrypt) GetEncryptedWorkspaceSessionRecordingChunks(ctx context.Context) ([]database.WorkspaceSessionRecordingChunk, error) {
+	chunks, err := db.Store.GetEncryptedWorkspaceSessionRecordingChunks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range chunks {
+		if err := db.decryptBytesField(&chunks[i].Data, chunks[i].DataKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return chunks, nil
+}
+
+func (db *dbCrypt) InsertWorkspaceSessionRecordingChunk(ctx context.Context, params database.InsertWorkspaceSessionRecordingChunkParams) (database.WorkspaceSessionRecordingChunk, error) {
+	if err := db.encryptBytesField(&params.Data, &params.DataKeyID); err != nil {
+		return database.WorkspaceSessionRecordingChunk{}, err
+	}
+	chunk, err := db.Store.InsertWorkspaceSessionRecordingChunk(ctx, params)
+	if err != nil {
+		return database.WorkspaceSessionRecordingChunk{}, err
+	}
+	if err := db.decryptBytesField(&chunk.Data, chunk.DataKeyID); err != nil {
+		return database.WorkspaceSessionRecordingChunk{}, err
+	}
+	return chunk, nil
+}
+
+// UpdateWorkspaceSessionRecordingChunkData is only used to re-encrypt
+// recorded output.
+func (db *dbCrypt) UpdateWorkspaceSessionRecordingChunkData(ctx context.Context, params database.UpdateWorkspaceSessionRecordingChunkDataParams) error {
+	if err := db.encryptBytesField(&params.Data, &params.DataKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateWorkspaceSessionRecordingChunkData(ctx, params)
+}
+
 func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 	// If no cipher is loaded, then we can't encrypt anything!
 	if db.ciphers == nil || db.primaryCipherDigest == "" {
@@ -374,6 +555,56 @@ func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 	return nil
 }
 
+// encryptBytesField is like encryptField, but for binary columns. The
+// ciphertext is stored as is, as there is no encoding to preserve.
+func (db *dbCrypt) encryptBytesField(field *[]byte, digest *sql.NullString) error {
+	// If no cipher is loaded, then we can't encrypt anything!
+	if db.ciphers == nil || db.primaryCipherDigest == "" {
+		return nil
+	}
+
+	if field == nil {
+		return xerrors.Errorf("developer error: encryptBytesField called with nil field")
+	}
+	if digest == nil {
+		return xerrors.Errorf("developer error: encryptBytesField called with nil digest")
+	}
+
+	encrypted, err := db.ciphers[db.primaryCipherDigest].Encrypt(*field)
+	if err != nil {
+		return err
+	}
+	*field = encrypted
+	*digest = sql.NullString{String: db.primaryCipherDigest, Valid: true}
+	return nil
+}
+
+// decryptBytesField is like decryptField, but for binary columns.
+func (db *dbCrypt) decryptBytesField(field *[]byte, digest sql.NullString) error {
+	if field == nil {
+		return xerrors.Errorf("developer error: decryptBytesField called with nil field")
+	}
+
+	if !digest.Valid || digest.String == "" {
+		// This field is not encrypted.
+		return nil
+	}
+
+	key, ok := db.ciphers[digest.String]
+	if !ok {
+		return &DecryptFailedError{
+			Inner: xerrors.Errorf("no cipher with digest %q", digest.String),
+		}
+	}
+
+	decrypted, err := key.Decrypt(*field)
+	if err != nil {
+		return &DecryptFailedError{Inner: err}
+	}
+	*field = decrypted
+	return nil
+}
+
 // decryptFields decrypts the given field using the key with the given digest.
 // If the value fails to decrypt, sql.ErrNoRows will be returned.
 func (db *dbCrypt) decryptField(field *string, digest sql.NullString) error {
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -401,6 +402,307 @@ func TestExternalAuthLinks(t *testing.T) {
 	})
 }
 
+func TestTemplateVersionVariables(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	setupVersion := func(t *testing.T, db database.Store) database.TemplateVersion {
+		org := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+		return dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+	}
+
+	t.Run("InsertTemplateVersionVariable", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		version := setupVersion(t, crypt)
+		sensitive := dbgen.TemplateVersionVariable(t, crypt, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Value:             "secret",
+			Sensitive:         true,
+		})
+		require.Equal(t, "secret", sensitive.Value)
+		require.Equal(t, ciphers[0].HexDigest(), sensitive.ValueKeyID.String)
+		plain := dbgen.TemplateVersionVariable(t, crypt, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Value:             "plain",
+		})
+		require.Equal(t, "plain", plain.Value)
+		require.False(t, plain.ValueKeyID.Valid)
+
+		rawVariables, err := db.GetTemplateVersionVariables(ctx, version.ID)
+		require.NoError(t, err)
+		require.Len(t, rawVariables, 2)
+		for _, variable := range rawVariables {
+			if variable.Sensitive {
+				requireEncryptedEquals(t, ciphers[0], variable.Value, "secret")
+			} else {
+				require.Equal(t, "plain", variable.Value)
+			}
+		}
+	})
+
+	t.Run("UpdateTemplateVersionVariableValue", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		version := setupVersion(t, crypt)
+		// Variables that were stored before encryption was enabled are in
+		// plaintext.
+		variable := dbgen.TemplateVersionVariable(t, db, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Value:             "secret",
+			Sensitive:         true,
+		})
+		err := crypt.UpdateTemplateVersionVariableValue(ctx, database.UpdateTemplateVersionVariableValueParams{
+			TemplateVersionID: variable.TemplateVersionID,
+			Name:              variable.Name,
+			Value:             variable.Value,
+		})
+		require.NoError(t, err)
+
+		rawVariables, err := db.GetSensitiveTemplateVersionVariables(ctx)
+		require.NoError(t, err)
+		require.Len(t, rawVariables, 1)
+		require.Equal(t, ciphers[0].HexDigest(), rawVariables[0].ValueKeyID.String)
+		requireEncryptedEquals(t, ciphers[0], rawVariables[0].Value, "secret")
+	})
+
+	t.Run("GetTemplateVersionVariables", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("OK", func(t *testing.T) {
+			t.Parallel()
+			_, crypt, ciphers := setup(t)
+			version := setupVersion(t, crypt)
+			_ = dbgen.TemplateVersionVariable(t, crypt, database.TemplateVersionVariable{
+				TemplateVersionID: version.ID,
+				Value:             "secret",
+				Sensitive:         true,
+			})
+			variables, err := crypt.GetTemplateVersionVariables(ctx, version.ID)
+			require.NoError(t, err)
+			require.Len(t, variables, 1)
+			require.Equal(t, "secret", variables[0].Value)
+			require.Equal(t, ciphers[0].HexDigest(), variables[0].ValueKeyID.String)
+
+			variables, err = crypt.GetSensitiveTemplateVersionVariables(ctx)
+			require.NoError(t, err)
+			require.Len(t, variables, 1)
+			require.Equal(t, "secret", variables[0].Value)
+		})
+
+		t.Run("DecryptErr", func(t *testing.T) {
+			t.Parallel()
+			db, crypt, ciphers := setup(t)
+			version := setupVersion(t, db)
+			_ = dbgen.TemplateVersionVariable(t, db, database.TemplateVersionVariable{
+				TemplateVersionID: version.ID,
+				Value:             fakeBase64RandomData(t, 32),
+				Sensitive:         true,
+				ValueKeyID:        sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+			})
+			_, err := crypt.GetTemplateVersionVariables(ctx, version.ID)
+			require.Error(t, err, "expected an error")
+			var derr *DecryptFailedError
+			require.ErrorAs(t, err, &derr, "expected a decrypt error")
+		})
+	})
+}
+
+func TestWorkspaceMigrations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	insertMigration := func(t *testing.T, db database.Store) database.WorkspaceMigration {
+		org := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+		template := dbgen.Template(t, db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			OwnerID:        user.ID,
+			TemplateID:     template.ID,
+		})
+		migration, err := db.InsertWorkspaceMigration(ctx, database.InsertWorkspaceMigrationParams{
+			ID:                        uuid.New(),
+			WorkspaceID:               workspace.ID,
+			InitiatorID:               user.ID,
+			DestinationURL:            "https://coder.example.com",
+			DestinationOrganizationID: uuid.New(),
+			DestinationOwner:          "me",
+			DestinationSessionToken:   "token",
+			CreatedAt:                 dbtime.Now(),
+			UpdatedAt:                 dbtime.Now(),
+		})
+		require.NoError(t, err)
+		return migration
+	}
+
+	t.Run("InsertWorkspaceMigration", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		migration := insertMigration(t, crypt)
+		require.Equal(t, "token", migration.DestinationSessionToken)
+		require.Equal(t, ciphers[0].HexDigest(), migration.DestinationSessionTokenKeyID.String)
+
+		rawMigration, err := db.GetWorkspaceMigrationByID(ctx, migration.ID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], rawMigration.DestinationSessionToken, "token")
+	})
+
+	t.Run("UpdateWorkspaceMigrationSessionToken", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		// Migrations that were requested before encryption was enabled are in
+		// plaintext.
+		migration := insertMigration(t, db)
+		err := crypt.UpdateWorkspaceMigrationSessionToken(ctx, database.UpdateWorkspaceMigrationSessionTokenParams{
+			ID:                      migration.ID,
+			DestinationSessionToken: migration.DestinationSessionToken,
+		})
+		require.NoError(t, err)
+
+		rawMigration, err := db.GetWorkspaceMigrationByID(ctx, migration.ID)
+		require.NoError(t, err)
+		require.Equal(t, ciphers[0].HexDigest(), rawMigration.DestinationSessionTokenKeyID.String)
+		requireEncryptedEquals(t, ciphers[0], rawMigration.DestinationSessionToken, "token")
+
+		migrations, err := crypt.GetActiveWorkspaceMigrations(ctx)
+		require.NoError(t, err)
+		require.Len(t, migrations, 1)
+		require.Equal(t, "token", migrations[0].DestinationSessionToken)
+	})
+
+	t.Run("CompleteWorkspaceMigration", func(t *testing.T) {
+		t.Parallel()
+		_, crypt, _ := setup(t)
+		migration := insertMigration(t, crypt)
+		completed, err := crypt.CompleteWorkspaceMigration(ctx, database.CompleteWorkspaceMigrationParams{
+			ID:          migration.ID,
+			Status:      database.WorkspaceMigrationStatusSucceeded,
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		require.NoError(t, err)
+		require.Empty(t, completed.DestinationSessionToken)
+		require.False(t, completed.DestinationSessionTokenKeyID.Valid)
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		migration := insertMigration(t, db)
+		err := db.UpdateWorkspaceMigrationSessionToken(ctx, database.UpdateWorkspaceMigrationSessionTokenParams{
+			ID:                           migration.ID,
+			DestinationSessionToken:      fakeBase64RandomData(t, 32),
+			DestinationSessionTokenKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+		require.NoError(t, err)
+		_, err = crypt.GetWorkspaceMigrationByID(ctx, migration.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	insertRecording := func(t *testing.T, db database.Store) database.WorkspaceSessionRecording {
+		org := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+		template := dbgen.Template(t, db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			OwnerID:        user.ID,
+			TemplateID:     template.ID,
+		})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: workspace.ID})
+		resource := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: build.JobID})
+		agent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: resource.ID})
+		return dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+			WorkspaceID: workspace.ID,
+			AgentID:     agent.ID,
+		})
+	}
+
+	t.Run("InsertWorkspaceSessionRecordingChunk", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		recording := insertRecording(t, crypt)
+		chunk, err := crypt.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			CreatedAt:   dbtime.Now(),
+			Data:        []byte("hello"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []byte("hello"), chunk.Data)
+		require.Equal(t, ciphers[0].HexDigest(), chunk.DataKeyID.String)
+
+		rawChunks, err := db.GetWorkspaceSessionRecordingChunks(ctx, recording.ID)
+		require.NoError(t, err)
+		require.Len(t, rawChunks, 1)
+		got, err := ciphers[0].Decrypt(rawChunks[0].Data)
+		require.NoError(t, err)
+		require.Equal(t, []byte("hello"), got)
+
+		chunks, err := crypt.GetWorkspaceSessionRecordingChunks(ctx, recording.ID)
+		require.NoError(t, err)
+		require.Len(t, chunks, 1)
+		require.Equal(t, []byte("hello"), chunks[0].Data)
+	})
+
+	t.Run("UpdateWorkspaceSessionRecordingChunkData", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		// Output that was recorded before encryption was enabled is in
+		// plaintext.
+		recording := insertRecording(t, db)
+		chunk, err := db.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			CreatedAt:   dbtime.Now(),
+			Data:        []byte("hello"),
+		})
+		require.NoError(t, err)
+		err = crypt.UpdateWorkspaceSessionRecordingChunkData(ctx, database.UpdateWorkspaceSessionRecordingChunkDataParams{
+			ID:   chunk.ID,
+			Data: chunk.Data,
+		})
+		require.NoError(t, err)
+
+		chunks, err := crypt.GetEncryptedWorkspaceSessionRecordingChunks(ctx)
+		require.NoError(t, err)
+		require.Len(t, chunks, 1)
+		require.Equal(t, ciphers[0].HexDigest(), chunks[0].DataKeyID.String)
+		require.Equal(t, []byte("hello"), chunks[0].Data)
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		recording := insertRecording(t, db)
+		_, err := db.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			CreatedAt:   dbtime.Now(),
+			Data:        []byte("not encrypted"),
+			DataKeyID:   sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+		require.NoError(t, err)
+		_, err = crypt.GetWorkspaceSessionRecordingChunks(ctx, recording.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
 func TestCryptoKeys(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -11,6 +11,8 @@
 // - database.UserLink.OAuthRefreshToken
 // - database.GitAuthLink.OAuthAccessToken
 // - database.GitAuthLink.OAuthRefreshToken
+// - database.TemplateVersionVariable.Value (sensitive variables only)
+// - database.WorkspaceMigration.DestinationSessionToken
 // - database.DBCryptSentinelValue
 //
 // Multiple ciphers can be provided to support key rotation. The primary cipher
@@ -8,6 +8,7 @@ import (
 	"cdr.dev/slog"
 
 	"github.com/coder/coder/v2/coderd/cryptokeys"
+	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/jwtutils"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
@@ -38,6 +39,10 @@ func (p *TokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *ht
 		return nil, "", false
 	}
 	issueReq.AppRequest = appReq
+	issueReq.DeviceID = httpmw.DeviceID(r)
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		issueReq.ClientCertificate = r.TLS.PeerCertificates[0].Raw
+	}
 
 	resp, ok := p.Client.IssueSignedAppTokenHTML(ctx, rw, issueReq)
 	if !ok {
@@ -161,7 +161,7 @@ func (*echo) Parse(sess *provisionersdk.Session, _ *proto.ParseRequest, _ <-chan
 	}
 	for _, response := range responses {
 		if log := response.GetLog(); log != nil {
-			sess.ProvisionLog(log.Level, log.Output)
+			sess.ProvisionLogWithFields(log.Level, log.Output, log.Fields)
 		}
 		if complete := response.GetParse(); complete != nil {
 			return complete
@@ -183,7 +183,7 @@ func (*echo) Plan(sess *provisionersdk.Session, req *proto.PlanRequest, canceled
 	}
 	for _, response := range responses {
 		if log := response.GetLog(); log != nil {
-			sess.ProvisionLog(log.Level, log.Output)
+			sess.ProvisionLogWithFields(log.Level, log.Output, log.Fields)
 		}
 		if complete := response.GetPlan(); complete != nil {
 			return complete
@@ -206,7 +206,10 @@ func (*echo) Apply(sess *provisionersdk.Session, req *proto.ApplyRequest, cancel
 	}
 	for _, response := range responses {
 		if log := response.GetLog(); log != nil {
-			sess.ProvisionLog(log.Level, log.Output)
+			sess.ProvisionLogWithFields(log.Level, log.Output, log.Fields)
+		}
+		if state := response.GetInterimState(); state != nil {
+			sess.UploadInterimState(state.State)
 		}
 		if complete := response.GetApply(); complete != nil {
 			return complete
@@ -646,6 +646,7 @@ func interruptCommandOnCancel(ctx, killCtx context.Context, logger slog.Logger,
 
 type logSink interface {
 	ProvisionLog(l proto.LogLevel, o string)
+	ProvisionLogWithFields(l proto.LogLevel, o string, f *proto.LogFields)
 }
 
 // logWriter creates a WriteCloser that will log each line of text at the given level.  The WriteCloser must be closed
@@ -714,7 +715,8 @@ func (e *executor) provisionReadAndLog(sink logSink, r io.Reader, done chan<- an
 		}
 
 		logLevel := convertTerraformLogLevel(log.Level, sink)
-		sink.ProvisionLog(logLevel, log.Message)
+		fields := log.logFields()
+		sink.ProvisionLogWithFields(logLevel, log.Message, fields)
 
 		ts, span, err := extractTimingSpan(log)
 		if err != nil {
@@ -735,7 +737,7 @@ func (e *executor) provisionReadAndLog(sink logSink, r io.Reader, done chan<- an
 		}
 		logLevel = convertTerraformLogLevel(string(log.Diagnostic.Severity), sink)
 		for _, diagLine := range strings.Split(FormatDiagnostic(log.Diagnostic), "\n") {
-			sink.ProvisionLog(logLevel, diagLine)
+			sink.ProvisionLogWithFields(logLevel, diagLine, fields)
 		}
 	}
 }
@@ -818,10 +820,32 @@ type terraformProvisionLog struct {
 	Timestamp string                    `json:"@timestamp"`
 	Type      string                    `json:"type"`
 	Hook      terraformProvisionLogHook `json:"hook"`
+	// Change is set on planned_change and resource_drift messages.
+	Change terraformProvisionLogHook `json:"change"`
 
 	Diagnostic *tfjson.Diagnostic `json:"diagnostic,omitempty"`
 }
 
+// logFields returns the structured fields of the log, or nil if it has none.
+func (l *terraformProvisionLog) logFields() *proto.LogFields {
+	hook := l.Hook
+	if hook.Resource.Addr == "" {
+		hook = l.Change
+	}
+	var severity string
+	if l.Diagnostic != nil {
+		severity = string(l.Diagnostic.Severity)
+	}
+	if hook.Resource.Addr == "" && hook.Action == "" && severity == "" {
+		return nil
+	}
+	return &proto.LogFields{
+		ResourceAddress:    hook.Resource.Addr,
+		Action:             hook.Action,
+		DiagnosticSeverity: severity,
+	}
+}
+
 type terraformProvisionLogHook struct {
 	Action   string                            `json:"action"`
 	Resource terraformProvisionLogHookResource `json:"resource"`
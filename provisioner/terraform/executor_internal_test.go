@@ -2,11 +2,15 @@ package terraform
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/stretchr/testify/require"
 
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 )
 
@@ -20,6 +24,10 @@ func (m *mockLogger) ProvisionLog(l proto.LogLevel, o string) {
 	m.logs = append(m.logs, &proto.Log{Level: l, Output: o})
 }
 
+func (m *mockLogger) ProvisionLogWithFields(l proto.LogLevel, o string, f *proto.LogFields) {
+	m.logs = append(m.logs, &proto.Log{Level: l, Output: o, Fields: f})
+}
+
 func TestLogWriter_Mainline(t *testing.T) {
 	t.Parallel()
 
@@ -44,6 +52,34 @@ From standing in the English rain`))
 	require.Equal(t, expected, logr.logs)
 }
 
+func TestProvisionReadAndLog_Fields(t *testing.T) {
+	t.Parallel()
+
+	logr := &mockLogger{}
+	e := &executor{
+		logger:  slogtest.Make(t, nil),
+		timings: newTimingAggregator(database.ProvisionerJobTimingStageApply),
+	}
+	r := strings.NewReader(strings.Join([]string{
+		`{"@level":"info","@message":"docker_container.workspace: Creating...","type":"apply_start","hook":{"resource":{"addr":"docker_container.workspace"},"action":"create"}}`,
+		`{"@level":"info","@message":"docker_image.main: Plan to create","type":"planned_change","change":{"resource":{"addr":"docker_image.main"},"action":"create"}}`,
+		`{"@level":"error","@message":"Error: oops","type":"diagnostic","diagnostic":{"severity":"error","summary":"oops"}}`,
+		`Acquiring state lock. This may take a few moments...`,
+	}, "\n"))
+	done := make(chan any)
+	e.provisionReadAndLog(logr, r, done)
+	<-done
+
+	fields := make(map[string]*proto.LogFields)
+	for _, log := range logr.logs {
+		fields[log.Output] = log.Fields
+	}
+	require.Equal(t, &proto.LogFields{ResourceAddress: "docker_container.workspace", Action: "create"}, fields["docker_container.workspace: Creating..."])
+	require.Equal(t, &proto.LogFields{ResourceAddress: "docker_image.main", Action: "create"}, fields["docker_image.main: Plan to create"])
+	require.Equal(t, &proto.LogFields{DiagnosticSeverity: "error"}, fields["Error: oops"])
+	require.Nil(t, fields["Acquiring state lock. This may take a few moments..."])
+}
+
 func TestOnlyDataResources(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,67 @@
+package terraform
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"time"
+
+	"cdr.dev/slog"
+)
+
+// interimStateInterval is how often the state file is checked for changes
+// while an apply is in progress.
+const interimStateInterval = 5 * time.Second
+
+// watchInterimState uploads the state file at path whenever it changes, until
+// the returned function is called. Terraform persists the state as resources
+// are created, so the uploaded state allows a build that does not complete to
+// be resumed without orphaning those resources.
+func watchInterimState(ctx context.Context, logger slog.Logger, path string, interval time.Duration, upload func(state []byte)) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	// The state written before the apply is the state the build started
+	// from, so there is no need to upload it.
+	var last [sha256.Size]byte
+	if state, err := os.ReadFile(path); err == nil {
+		last = sha256.Sum256(state)
+	}
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			state, err := os.ReadFile(path)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					logger.Debug(ctx, "failed to read interim state", slog.F("path", path), slog.Error(err))
+				}
+				continue
+			}
+			// Terraform may be in the middle of writing the file.
+			if !json.Valid(state) {
+				continue
+			}
+			sum := sha256.Sum256(state)
+			if sum == last {
+				continue
+			}
+			last = sum
+			upload(state)
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,44 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWatchInterimState(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, nil)
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	require.NoError(t, os.WriteFile(path, []byte(`{"serial":1}`), 0o600))
+
+	uploads := make(chan string, 10)
+	stop := watchInterimState(ctx, logger, path, time.Millisecond, func(state []byte) {
+		uploads <- string(state)
+	})
+
+	// The state the apply started from is not uploaded, nor is a partially
+	// written state.
+	require.NoError(t, os.WriteFile(path, []byte(`{"serial":`), 0o600))
+	require.Never(t, func() bool {
+		return len(uploads) > 0
+	}, testutil.IntervalMedium, testutil.IntervalFast)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"serial":2}`), 0o600))
+	require.Equal(t, `{"serial":2}`, testutil.RequireReceive(ctx, t, uploads))
+
+	stop()
+	require.NoError(t, os.WriteFile(path, []byte(`{"serial":3}`), 0o600))
+	require.Never(t, func() bool {
+		return len(uploads) > 0
+	}, testutil.IntervalMedium, testutil.IntervalFast)
+}
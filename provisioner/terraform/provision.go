@@ -87,7 +87,7 @@ func (s *server) Plan(
 	// e.g. bad template param values and cannot be deleted. This is just for
 	// contingency, in the future we will try harder to prevent workspaces being
 	// broken this hard.
-	if request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY && len(sess.Config.State) == 0 {
+	if request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY && !hasState(sess) {
 		sess.ProvisionLog(proto.LogLevel_INFO, "The terraform state does not exist, there is nothing to do")
 		return &proto.PlanComplete{}
 	}
@@ -195,7 +195,7 @@ func (s *server) Apply(
 	// e.g. bad template param values and cannot be deleted. This is just for
 	// contingency, in the future we will try harder to prevent workspaces being
 	// broken this hard.
-	if request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY && len(sess.Config.State) == 0 {
+	if request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY && !hasState(sess) {
 		sess.ProvisionLog(proto.LogLevel_INFO, "The terraform plan does not exist, there is nothing to do")
 		return &proto.ApplyComplete{}
 	}
@@ -207,9 +207,11 @@ func (s *server) Apply(
 		return provisionersdk.ApplyErrorf("provision env: %s", err)
 	}
 	env = otelEnvInject(ctx, env)
+	stopWatchingState := watchInterimState(ctx, s.logger, statefilePath, interimStateInterval, sess.UploadInterimState)
 	resp, err := e.apply(
 		ctx, killCtx, env, sess,
 	)
+	stopWatchingState()
 	if err != nil {
 		errorMessage := err.Error()
 		// Terraform can fail and apply and still need to store it's state.
@@ -223,6 +225,16 @@ func (s *server) Apply(
 	return resp
 }
 
+// hasState returns whether the session has state to destroy, either from the
+// config or from an apply earlier in the session.
+func hasState(sess *provisionersdk.Session) bool {
+	if len(sess.Config.State) > 0 {
+		return true
+	}
+	_, err := os.Stat(getStateFilePath(sess.WorkDirectory))
+	return err == nil
+}
+
 func planVars(plan *proto.PlanRequest) ([]string, error) {
 	vars := []string{}
 	for _, variable := range plan.VariableValues {
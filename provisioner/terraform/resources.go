@@ -61,6 +61,8 @@ type agentAttributes struct {
 	DisplayApps              []agentDisplayAppsAttributes `mapstructure:"display_apps"`
 	Order                    int64                        `mapstructure:"order"`
 	ResourcesMonitoring      []agentResourcesMonitoring   `mapstructure:"resources_monitoring"`
+	DisplayGroup             string                       `mapstructure:"display_group"`
+	Collapsed                bool                         `mapstructure:"collapsed"`
 }
 
 type agentDevcontainerAttributes struct {
@@ -144,11 +146,14 @@ type appHealthcheckAttributes struct {
 
 // A mapping of attributes on the "coder_metadata" resource.
 type resourceMetadataAttributes struct {
-	ResourceID string                 `mapstructure:"resource_id"`
-	Hide       bool                   `mapstructure:"hide"`
-	Icon       string                 `mapstructure:"icon"`
-	DailyCost  int32                  `mapstructure:"daily_cost"`
-	Items      []resourceMetadataItem `mapstructure:"item"`
+	ResourceID   string                 `mapstructure:"resource_id"`
+	Hide         bool                   `mapstructure:"hide"`
+	Icon         string                 `mapstructure:"icon"`
+	DailyCost    int32                  `mapstructure:"daily_cost"`
+	DisplayGroup string                 `mapstructure:"display_group"`
+	Order        int64                  `mapstructure:"order"`
+	Collapsed    bool                   `mapstructure:"collapsed"`
+	Items        []resourceMetadataItem `mapstructure:"item"`
 }
 
 type resourceMetadataItem struct {
@@ -350,6 +355,8 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 				DisplayApps:              displayApps,
 				Order:                    attrs.Order,
 				ApiKeyScope:              attrs.APIKeyScope,
+				DisplayGroup:             attrs.DisplayGroup,
+				Collapsed:                attrs.Collapsed,
 			}
 			// Support the legacy script attributes in the agent!
 			if attrs.StartupScript != "" {
@@ -671,6 +678,9 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 	resourceHidden := map[string]bool{}
 	resourceIcon := map[string]string{}
 	resourceCost := map[string]int32{}
+	resourceDisplayGroup := map[string]string{}
+	resourceOrder := map[string]int64{}
+	resourceCollapsed := map[string]bool{}
 
 	metadataTargetLabels := map[string]bool{}
 	for _, resources := range tfResourcesByLabel {
@@ -728,6 +738,9 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 			resourceHidden[targetLabel] = attrs.Hide
 			resourceIcon[targetLabel] = attrs.Icon
 			resourceCost[targetLabel] = attrs.DailyCost
+			resourceDisplayGroup[targetLabel] = attrs.DisplayGroup
+			resourceOrder[targetLabel] = attrs.Order
+			resourceCollapsed[targetLabel] = attrs.Collapsed
 			for _, item := range attrs.Items {
 				resourceMetadata[targetLabel] = append(resourceMetadata[targetLabel],
 					&proto.Resource_Metadata{
@@ -779,6 +792,9 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 				DailyCost:    resourceCost[label],
 				InstanceType: applyInstanceType(resource),
 				ModulePath:   modulePath,
+				DisplayGroup: resourceDisplayGroup[label],
+				Order:        resourceOrder[label],
+				Collapsed:    resourceCollapsed[label],
 			})
 		}
 	}
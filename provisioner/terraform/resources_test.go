@@ -1524,6 +1524,64 @@ func TestInstanceIDAssociation(t *testing.T) {
 	}
 }
 
+func TestDisplayHints(t *testing.T) {
+	t.Parallel()
+	ctx, logger := ctxAndLogger(t)
+	state, err := terraform.ConvertState(ctx, []*tfjson.StateModule{{
+		Resources: []*tfjson.StateResource{{
+			Address: "coder_agent.dev",
+			Type:    "coder_agent",
+			Name:    "dev",
+			Mode:    tfjson.ManagedResourceMode,
+			AttributeValues: map[string]interface{}{
+				"arch":          "amd64",
+				"auth":          "token",
+				"display_group": "Agents",
+				"collapsed":     true,
+			},
+		}, {
+			Address:   "null_resource.dev",
+			Type:      "null_resource",
+			Name:      "dev",
+			Mode:      tfjson.ManagedResourceMode,
+			DependsOn: []string{"coder_agent.dev"},
+		}, {
+			Address:   "coder_metadata.dev",
+			Type:      "coder_metadata",
+			Name:      "dev",
+			Mode:      tfjson.ManagedResourceMode,
+			DependsOn: []string{"null_resource.dev"},
+			AttributeValues: map[string]interface{}{
+				"resource_id":   "dev",
+				"display_group": "Compute",
+				"order":         3,
+				"collapsed":     true,
+			},
+		}},
+		// This is manually created to join the edges.
+	}}, `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] coder_agent.dev" [label = "coder_agent.dev", shape = "box"]
+		"[root] coder_metadata.dev" [label = "coder_metadata.dev", shape = "box"]
+		"[root] null_resource.dev" [label = "null_resource.dev", shape = "box"]
+		"[root] coder_metadata.dev" -> "[root] null_resource.dev"
+		"[root] null_resource.dev" -> "[root] coder_agent.dev"
+	}
+}
+`, logger)
+	require.NoError(t, err)
+	require.Len(t, state.Resources, 1)
+	resource := state.Resources[0]
+	require.Equal(t, "Compute", resource.GetDisplayGroup())
+	require.EqualValues(t, 3, resource.GetOrder())
+	require.True(t, resource.GetCollapsed())
+	require.Len(t, resource.Agents, 1)
+	require.Equal(t, "Agents", resource.Agents[0].GetDisplayGroup())
+	require.True(t, resource.Agents[0].GetCollapsed())
+}
+
 func TestAITasks(t *testing.T) {
 	t.Parallel()
 	ctx, logger := ctxAndLogger(t)
@@ -42,6 +42,7 @@ type DRPCProvisionerDaemonClient interface {
 	AcquireJobWithCancel(ctx context.Context) (DRPCProvisionerDaemon_AcquireJobWithCancelClient, error)
 	CommitQuota(ctx context.Context, in *CommitQuotaRequest) (*CommitQuotaResponse, error)
 	UpdateJob(ctx context.Context, in *UpdateJobRequest) (*UpdateJobResponse, error)
+	StreamJobLogs(ctx context.Context) (DRPCProvisionerDaemon_StreamJobLogsClient, error)
 	FailJob(ctx context.Context, in *FailedJob) (*Empty, error)
 	CompleteJob(ctx context.Context, in *CompletedJob) (*Empty, error)
 	UploadFile(ctx context.Context) (DRPCProvisionerDaemon_UploadFileClient, error)
@@ -123,6 +124,45 @@ func (c *drpcProvisionerDaemonClient) UpdateJob(ctx context.Context, in *UpdateJ
 	return out, nil
 }
 
+func (c *drpcProvisionerDaemonClient) StreamJobLogs(ctx context.Context) (DRPCProvisionerDaemon_StreamJobLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/provisionerd.ProvisionerDaemon/StreamJobLogs", drpcEncoding_File_provisionerd_proto_provisionerd_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcProvisionerDaemon_StreamJobLogsClient{stream}
+	return x, nil
+}
+
+type DRPCProvisionerDaemon_StreamJobLogsClient interface {
+	drpc.Stream
+	Send(*StreamJobLogsRequest) error
+	Recv() (*StreamJobLogsResponse, error)
+}
+
+type drpcProvisionerDaemon_StreamJobLogsClient struct {
+	drpc.Stream
+}
+
+func (x *drpcProvisionerDaemon_StreamJobLogsClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcProvisionerDaemon_StreamJobLogsClient) Send(m *StreamJobLogsRequest) error {
+	return x.MsgSend(m, drpcEncoding_File_provisionerd_proto_provisionerd_proto{})
+}
+
+func (x *drpcProvisionerDaemon_StreamJobLogsClient) Recv() (*StreamJobLogsResponse, error) {
+	m := new(StreamJobLogsResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_provisionerd_proto_provisionerd_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcProvisionerDaemon_StreamJobLogsClient) RecvMsg(m *StreamJobLogsResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_provisionerd_proto_provisionerd_proto{})
+}
+
 func (c *drpcProvisionerDaemonClient) FailJob(ctx context.Context, in *FailedJob) (*Empty, error) {
 	out := new(Empty)
 	err := c.cc.Invoke(ctx, "/provisionerd.ProvisionerDaemon/FailJob", drpcEncoding_File_provisionerd_proto_provisionerd_proto{}, in, out)
@@ -191,6 +231,7 @@ type DRPCProvisionerDaemonServer interface {
 	AcquireJobWithCancel(DRPCProvisionerDaemon_AcquireJobWithCancelStream) error
 	CommitQuota(context.Context, *CommitQuotaRequest) (*CommitQuotaResponse, error)
 	UpdateJob(context.Context, *UpdateJobRequest) (*UpdateJobResponse, error)
+	StreamJobLogs(DRPCProvisionerDaemon_StreamJobLogsStream) error
 	FailJob(context.Context, *FailedJob) (*Empty, error)
 	CompleteJob(context.Context, *CompletedJob) (*Empty, error)
 	UploadFile(DRPCProvisionerDaemon_UploadFileStream) error
@@ -214,6 +255,10 @@ func (s *DRPCProvisionerDaemonUnimplementedServer) UpdateJob(context.Context, *U
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCProvisionerDaemonUnimplementedServer) StreamJobLogs(DRPCProvisionerDaemon_StreamJobLogsStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 func (s *DRPCProvisionerDaemonUnimplementedServer) FailJob(context.Context, *FailedJob) (*Empty, error) {
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
@@ -228,7 +273,7 @@ func (s *DRPCProvisionerDaemonUnimplementedServer) UploadFile(DRPCProvisionerDae
 
 type DRPCProvisionerDaemonDescription struct{}
 
-func (DRPCProvisionerDaemonDescription) NumMethods() int { return 7 }
+func (DRPCProvisionerDaemonDescription) NumMethods() int { return 8 }
 
 func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -268,6 +313,14 @@ func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, dr
 					)
 			}, DRPCProvisionerDaemonServer.UpdateJob, true
 	case 4:
+		return "/provisionerd.ProvisionerDaemon/StreamJobLogs", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCProvisionerDaemonServer).
+					StreamJobLogs(
+						&drpcProvisionerDaemon_StreamJobLogsStream{in1.(drpc.Stream)},
+					)
+			}, DRPCProvisionerDaemonServer.StreamJobLogs, true
+	case 5:
 		return "/provisionerd.ProvisionerDaemon/FailJob", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCProvisionerDaemonServer).
@@ -276,7 +329,7 @@ func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, dr
 						in1.(*FailedJob),
 					)
 			}, DRPCProvisionerDaemonServer.FailJob, true
-	case 5:
+	case 6:
 		return "/provisionerd.ProvisionerDaemon/CompleteJob", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCProvisionerDaemonServer).
@@ -285,7 +338,7 @@ func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, dr
 						in1.(*CompletedJob),
 					)
 			}, DRPCProvisionerDaemonServer.CompleteJob, true
-	case 6:
+	case 7:
 		return "/provisionerd.ProvisionerDaemon/UploadFile", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return nil, srv.(DRPCProvisionerDaemonServer).
@@ -376,6 +429,32 @@ func (x *drpcProvisionerDaemon_UpdateJobStream) SendAndClose(m *UpdateJobRespons
 	return x.CloseSend()
 }
 
+type DRPCProvisionerDaemon_StreamJobLogsStream interface {
+	drpc.Stream
+	Send(*StreamJobLogsResponse) error
+	Recv() (*StreamJobLogsRequest, error)
+}
+
+type drpcProvisionerDaemon_StreamJobLogsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcProvisionerDaemon_StreamJobLogsStream) Send(m *StreamJobLogsResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_provisionerd_proto_provisionerd_proto{})
+}
+
+func (x *drpcProvisionerDaemon_StreamJobLogsStream) Recv() (*StreamJobLogsRequest, error) {
+	m := new(StreamJobLogsRequest)
+	if err := x.MsgRecv(m, drpcEncoding_File_provisionerd_proto_provisionerd_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcProvisionerDaemon_StreamJobLogsStream) RecvMsg(m *StreamJobLogsRequest) error {
+	return x.MsgRecv(m, drpcEncoding_File_provisionerd_proto_provisionerd_proto{})
+}
+
 type DRPCProvisionerDaemon_FailJobStream interface {
 	drpc.Stream
 	SendAndClose(*Empty) error
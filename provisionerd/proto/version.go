@@ -44,9 +44,49 @@ import "github.com/coder/coder/v2/apiversion"
 //     -> `has_ai_tasks` in `CompleteJob.TemplateImport`
 //     -> `has_ai_tasks` and `ai_tasks` in `PlanComplete`
 //     -> new message types `AITaskSidebarApp` and `AITask`
+//
+// API v1.8:
+//   - Add new message type `LogFields` and a `fields` field of that type to
+//     `provisioner.Log` and `provisionerd.Log` for structured log entries.
+//
+// API v1.9:
+//   - Add `display_group`, `order` and `collapsed` fields to `Resource` and
+//     `display_group` and `collapsed` fields to `Agent` to organize resources
+//     and agents in user interfaces.
+//
+// API v1.10:
+//   - Add new message type `InterimState` to `provisioner.Response` and a
+//     field named `interim_state` to `UpdateJobRequest` to persist the state
+//     of workspace builds while they are applied.
+//
+// API v1.11:
+//   - Add `state` field to `AcquiredJob.TemplateDryRun` and `plan` field to
+//     `CompletedJob.TemplateDryRun` to check workspaces for drift.
+//
+// API v1.12:
+//   - Add `resources` field to `CommitQuotaRequest` and new message type
+//     `ResourceCeilingViolation` with a field named
+//     `resource_ceiling_violations` in `CommitQuotaResponse` to enforce the
+//     resource ceilings of templates.
+//
+// API v1.13:
+//   - Add `test` field to `AcquiredJob.TemplateDryRun` to test template
+//     versions by applying and destroying them.
+//
+// API v1.14:
+//   - Add `StreamJobLogs` RPC with new message types `StreamJobLogsRequest` and
+//     `StreamJobLogsResponse` to stream job logs with backpressure.
+//
+// API v1.15:
+//   - Add `monthly_budget` and `monthly_credits_spent` fields to
+//     `CommitQuotaResponse` to enforce monthly quota budgets.
+//
+// API v1.16:
+//   - Add `run_on_claim` field to `Script` to run scripts when a prebuilt
+//     workspace is claimed.
 const (
 	CurrentMajor = 1
-	CurrentMinor = 7
+	CurrentMinor = 16
 )
 
 // CurrentVersion is the current provisionerd API version.
@@ -417,6 +417,7 @@ func (p *Server) acquireAndRunOne(client proto.DRPCProvisionerDaemonClient) erro
 		job,
 		runner.Options{
 			Updater:             p,
+			LogStreamer:         p,
 			QuotaCommitter:      p,
 			Logger:              p.opts.Logger.Named("runner"),
 			Provisioner:         resp.Client,
@@ -511,6 +512,15 @@ func (p *Server) UpdateJob(ctx context.Context, in *proto.UpdateJobRequest) (*pr
 	return out, nil
 }
 
+// StreamJobLogs opens a stream to send the logs of a job over.
+func (p *Server) StreamJobLogs(ctx context.Context) (proto.DRPCProvisionerDaemon_StreamJobLogsClient, error) {
+	client, ok := p.client()
+	if !ok {
+		return nil, xerrors.New("no client available")
+	}
+	return client.StreamJobLogs(ctx)
+}
+
 func (p *Server) FailJob(ctx context.Context, in *proto.FailedJob) error {
 	_, err := clientDoWithRetries(ctx, p.client, func(ctx context.Context, client proto.DRPCProvisionerDaemonClient) (*proto.Empty, error) {
 		return client.FailJob(ctx, in)
@@ -2,6 +2,7 @@ package provisionerd_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -24,6 +25,7 @@ import (
 	"github.com/coder/coder/v2/codersdk/drpcsdk"
 	"github.com/coder/coder/v2/provisionerd"
 	"github.com/coder/coder/v2/provisionerd/proto"
+	"github.com/coder/coder/v2/provisionerd/runner"
 	"github.com/coder/coder/v2/provisionersdk"
 	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
@@ -458,6 +460,83 @@ func TestProvisionerd(t *testing.T) {
 		assert.True(t, didComplete.Load(), "should complete the job")
 	})
 
+	t.Run("TemplateTest", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			mu          sync.Mutex
+			transitions []string
+			completed   *proto.CompletedJob
+			acq         = newAcquireOne(t, &proto.AcquiredJob{
+				JobId:       "test",
+				Provisioner: "someprovisioner",
+				TemplateSourceArchive: testutil.CreateTar(t, map[string]string{
+					"test.txt": "content",
+				}),
+				Type: &proto.AcquiredJob_TemplateDryRun_{
+					TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
+						Metadata: &sdkproto.Metadata{
+							WorkspaceOwner: "template-test",
+						},
+						Test: true,
+					},
+				},
+			})
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJobWithCancel: acq.acquireWithCancel,
+				updateJob: func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error) {
+					return &proto.UpdateJobResponse{}, nil
+				},
+				completeJob: func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					completed = job
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.LocalProvisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				plan: func(
+					_ *provisionersdk.Session,
+					req *sdkproto.PlanRequest,
+					_ <-chan struct{},
+				) *sdkproto.PlanComplete {
+					mu.Lock()
+					defer mu.Unlock()
+					assert.Equal(t, "template-test", req.Metadata.WorkspaceOwner)
+					transitions = append(transitions, "plan "+req.Metadata.WorkspaceTransition.String())
+					return &sdkproto.PlanComplete{}
+				},
+				apply: func(
+					_ *provisionersdk.Session,
+					req *sdkproto.ApplyRequest,
+					_ <-chan struct{},
+				) *sdkproto.ApplyComplete {
+					mu.Lock()
+					defer mu.Unlock()
+					transitions = append(transitions, "apply "+req.Metadata.WorkspaceTransition.String())
+					return &sdkproto.ApplyComplete{
+						Resources: []*sdkproto.Resource{{Name: "resource"}},
+					}
+				},
+			}),
+		})
+
+		require.Condition(t, closedWithin(acq.complete, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, []string{"plan START", "apply START", "plan DESTROY", "apply DESTROY"}, transitions)
+		require.NotNil(t, completed, "should complete the job")
+		require.Len(t, completed.GetTemplateDryRun().GetResources(), 1)
+	})
+
 	t.Run("WorkspaceBuild", func(t *testing.T) {
 		t.Parallel()
 		done := make(chan struct{})
@@ -520,6 +599,84 @@ func TestProvisionerd(t *testing.T) {
 		assert.True(t, didComplete.Load(), "should complete the job")
 	})
 
+	t.Run("WorkspaceBuildStreamLogs", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			didComplete  atomic.Bool
+			streamedLogs atomic.Int64
+			acq          = newAcquireOne(t, &proto.AcquiredJob{
+				JobId:       "test",
+				Provisioner: "someprovisioner",
+				TemplateSourceArchive: testutil.CreateTar(t, map[string]string{
+					"test.txt": "content",
+				}),
+				Type: &proto.AcquiredJob_WorkspaceBuild_{
+					WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
+						Metadata: &sdkproto.Metadata{},
+					},
+				},
+			})
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJobWithCancel: acq.acquireWithCancel,
+				streamJobLogs: func(stream proto.DRPCProvisionerDaemon_StreamJobLogsStream) error {
+					for {
+						request, err := stream.Recv()
+						if errors.Is(err, io.EOF) {
+							return nil
+						}
+						if err != nil {
+							return err
+						}
+						assert.Equal(t, "test", request.JobId)
+						err = stream.Send(&proto.StreamJobLogsResponse{
+							InsertedLogs: streamedLogs.Add(int64(len(request.Logs))),
+						})
+						if err != nil {
+							return err
+						}
+					}
+				},
+				updateJob: func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error) {
+					assert.Empty(t, update.Logs, "logs should be streamed")
+					return &proto.UpdateJobResponse{}, nil
+				},
+				completeJob: func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
+					assert.NotZero(t, streamedLogs.Load(), "logs should be streamed before the job completes")
+					didComplete.Store(true)
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.LocalProvisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				plan: func(
+					s *provisionersdk.Session,
+					_ *sdkproto.PlanRequest,
+					cancelOrComplete <-chan struct{},
+				) *sdkproto.PlanComplete {
+					s.ProvisionLog(sdkproto.LogLevel_DEBUG, "wow")
+					return &sdkproto.PlanComplete{}
+				},
+				apply: func(
+					_ *provisionersdk.Session,
+					_ *sdkproto.ApplyRequest,
+					_ <-chan struct{},
+				) *sdkproto.ApplyComplete {
+					return &sdkproto.ApplyComplete{}
+				},
+			}),
+		})
+		require.Condition(t, closedWithin(acq.complete, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		assert.True(t, didComplete.Load(), "should complete the job")
+	})
+
 	t.Run("WorkspaceBuildQuotaExceeded", func(t *testing.T) {
 		t.Parallel()
 		done := make(chan struct{})
@@ -612,6 +769,92 @@ func TestProvisionerd(t *testing.T) {
 		assert.True(t, didFail.Load(), "should fail the job")
 	})
 
+	t.Run("WorkspaceBuildResourceCeilingExceeded", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			didComplete atomic.Bool
+			failedJob   atomic.Pointer[proto.FailedJob]
+			acq         = newAcquireOne(t, &proto.AcquiredJob{
+				JobId:       "test",
+				Provisioner: "someprovisioner",
+				TemplateSourceArchive: testutil.CreateTar(t, map[string]string{
+					"test.txt": "content",
+				}),
+				Type: &proto.AcquiredJob_WorkspaceBuild_{
+					WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
+						Metadata: &sdkproto.Metadata{},
+					},
+				},
+			})
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJobWithCancel: acq.acquireWithCancel,
+				updateJob: func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error) {
+					return &proto.UpdateJobResponse{}, nil
+				},
+				completeJob: func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
+					didComplete.Store(true)
+					return &proto.Empty{}, nil
+				},
+				commitQuota: func(ctx context.Context, com *proto.CommitQuotaRequest) (*proto.CommitQuotaResponse, error) {
+					// Resources that cost nothing are still sent to be checked.
+					assert.Zero(t, com.DailyCost)
+					assert.Len(t, com.Resources, 1)
+					return &proto.CommitQuotaResponse{
+						ResourceCeilingViolations: []*proto.ResourceCeilingViolation{{
+							Resource: "cpu",
+							Planned:  8,
+							Ceiling:  4,
+						}},
+					}, nil
+				},
+				failJob: func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error) {
+					failedJob.Store(job)
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.LocalProvisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				plan: func(
+					_ *provisionersdk.Session,
+					_ *sdkproto.PlanRequest,
+					_ <-chan struct{},
+				) *sdkproto.PlanComplete {
+					return &sdkproto.PlanComplete{
+						Resources: []*sdkproto.Resource{{
+							Name: "dev",
+							Metadata: []*sdkproto.Resource_Metadata{{
+								Key:   "cpu",
+								Value: "8",
+							}},
+						}},
+					}
+				},
+				apply: func(
+					_ *provisionersdk.Session,
+					_ *sdkproto.ApplyRequest,
+					_ <-chan struct{},
+				) *sdkproto.ApplyComplete {
+					t.Error("should not apply when resources exceed the ceilings")
+					return &sdkproto.ApplyComplete{}
+				},
+			}),
+		})
+		require.Condition(t, closedWithin(acq.complete, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		assert.False(t, didComplete.Load(), "should not complete the job")
+		failed := failedJob.Load()
+		require.NotNil(t, failed, "should fail the job")
+		assert.Equal(t, runner.ResourceCeilingExceededErrorCode, failed.ErrorCode)
+		assert.Contains(t, failed.Error, "cpu 8 > 4")
+	})
+
 	t.Run("WorkspaceBuildFailComplete", func(t *testing.T) {
 		t.Parallel()
 		done := make(chan struct{})
@@ -1279,6 +1522,7 @@ type provisionerDaemonTestServer struct {
 	acquireJobWithCancel func(stream proto.DRPCProvisionerDaemon_AcquireJobWithCancelStream) error
 	commitQuota          func(ctx context.Context, com *proto.CommitQuotaRequest) (*proto.CommitQuotaResponse, error)
 	updateJob            func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error)
+	streamJobLogs        func(stream proto.DRPCProvisionerDaemon_StreamJobLogsStream) error
 	failJob              func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error)
 	completeJob          func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error)
 	uploadFile           func(stream proto.DRPCProvisionerDaemon_UploadFileStream) error
@@ -1311,6 +1555,14 @@ func (p *provisionerDaemonTestServer) UpdateJob(ctx context.Context, update *pro
 	return p.updateJob(ctx, update)
 }
 
+func (p *provisionerDaemonTestServer) StreamJobLogs(stream proto.DRPCProvisionerDaemon_StreamJobLogsStream) error {
+	if p.streamJobLogs == nil {
+		// Like coderd before API v1.14.
+		return xerrors.New("unimplemented")
+	}
+	return p.streamJobLogs(stream)
+}
+
 func (p *provisionerDaemonTestServer) FailJob(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error) {
 	return p.failJob(ctx, job)
 }
@@ -0,0 +1,172 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisionerd/proto"
+)
+
+// maxUnacknowledgedLogs is the number of logs a logStream sends before it
+// waits for the server to acknowledge them.
+const maxUnacknowledgedLogs = 2000
+
+// JobLogStreamer opens StreamJobLogs streams. Runners send logs over a stream
+// when they have one, and with UpdateJob otherwise.
+type JobLogStreamer interface {
+	StreamJobLogs(ctx context.Context) (proto.DRPCProvisionerDaemon_StreamJobLogsClient, error)
+}
+
+// logStream sends the logs of a job over a StreamJobLogs stream. It keeps the
+// logs the server hasn't acknowledged yet, so they can be sent with UpdateJob
+// if the stream breaks.
+type logStream struct {
+	jobID  string
+	stream proto.DRPCProvisionerDaemon_StreamJobLogsClient
+	// broken is called if the stream breaks before it is closed.
+	broken func(*logStream)
+	done   chan struct{}
+
+	mu           sync.Mutex
+	unacked      []*proto.Log
+	acked        int64
+	backoffUntil time.Time
+	err          error
+	closing      bool
+	// changed is closed and replaced whenever the fields above change.
+	changed chan struct{}
+}
+
+func newLogStream(jobID string, stream proto.DRPCProvisionerDaemon_StreamJobLogsClient, broken func(*logStream)) *logStream {
+	s := &logStream{
+		jobID:   jobID,
+		stream:  stream,
+		broken:  broken,
+		done:    make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+	go s.receive()
+	return s
+}
+
+func (s *logStream) receive() {
+	defer close(s.done)
+	for {
+		response, err := s.stream.Recv()
+
+		s.mu.Lock()
+		if err != nil {
+			if s.err == nil {
+				s.err = err
+			}
+			s.notifyLocked()
+			closing := s.closing
+			s.mu.Unlock()
+			if !closing {
+				// close waits for this goroutine to exit.
+				go s.broken(s)
+			}
+			return
+		}
+		if n := response.InsertedLogs - s.acked; n > 0 && n <= int64(len(s.unacked)) {
+			s.unacked = s.unacked[n:]
+			s.acked = response.InsertedLogs
+		}
+		if response.BackoffMs > 0 {
+			s.backoffUntil = time.Now().Add(time.Duration(response.BackoffMs) * time.Millisecond)
+		}
+		s.notifyLocked()
+		s.mu.Unlock()
+	}
+}
+
+func (s *logStream) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// send sends logs once the server has acknowledged enough of the logs sent
+// before, and any backoff it asked for has passed.
+func (s *logStream) send(ctx context.Context, logs []*proto.Log) error {
+	for {
+		s.mu.Lock()
+		err := s.err
+		full := len(s.unacked) >= maxUnacknowledgedLogs
+		backoff := time.Until(s.backoffUntil)
+		changed := s.changed
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		if !full && backoff <= 0 {
+			break
+		}
+
+		var (
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		if backoff > 0 {
+			timer = time.NewTimer(backoff)
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-changed:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Track the logs before sending them, so they are tracked by the time the
+	// server acknowledges them.
+	s.mu.Lock()
+	s.unacked = append(s.unacked, logs...)
+	s.mu.Unlock()
+	err := s.stream.Send(&proto.StreamJobLogsRequest{
+		JobId: s.jobID,
+		Logs:  logs,
+	})
+	if err != nil {
+		s.mu.Lock()
+		if s.err == nil {
+			s.err = err
+		}
+		s.mu.Unlock()
+		return xerrors.Errorf("send logs: %w", err)
+	}
+	return nil
+}
+
+// close closes the stream once the server has inserted the logs sent over it,
+// or ctx is done. It returns the logs the server didn't acknowledge.
+func (s *logStream) close(ctx context.Context) []*proto.Log {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+	_ = s.stream.CloseSend()
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+	}
+	_ = s.stream.Close()
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unacked := s.unacked
+	s.unacked = nil
+	return unacked
+}
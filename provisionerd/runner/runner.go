@@ -34,11 +34,15 @@ const (
 
 	RequiredTemplateVariablesErrorCode = "REQUIRED_TEMPLATE_VARIABLES"
 	requiredTemplateVariablesErrorText = "required template variables"
+
+	ResourceCeilingExceededErrorCode = "TEMPLATE_RESOURCE_CEILING_EXCEEDED"
+	resourceCeilingExceededErrorText = "exceed the resource ceilings"
 )
 
 var errorCodes = map[string]string{
 	MissingParameterErrorCode:          missingParameterErrorText,
 	RequiredTemplateVariablesErrorCode: requiredTemplateVariablesErrorText,
+	ResourceCeilingExceededErrorCode:   resourceCeilingExceededErrorText,
 }
 
 var errUpdateSkipped = xerrors.New("update skipped; job complete or failed")
@@ -48,6 +52,7 @@ type Runner struct {
 	metrics             Metrics
 	job                 *proto.AcquiredJob
 	sender              JobUpdater
+	logStreamer         JobLogStreamer
 	quotaCommitter      QuotaCommitter
 	logger              slog.Logger
 	provisioner         sdkproto.DRPCProvisionerClient
@@ -80,6 +85,13 @@ type Runner struct {
 	// of a Cancel().  However, when someone calls Fail() or ForceStop(), we might not send the
 	// terminal message, but okToSend is set to false regardless.
 	okToSend bool
+
+	// logStreamMu serializes sending logs over logStream. If opening or
+	// sending over the stream fails, logStreamFailed makes the Runner send
+	// logs with UpdateJob for the rest of the job.
+	logStreamMu     sync.Mutex
+	logStream       *logStream
+	logStreamFailed bool
 }
 
 type Metrics struct {
@@ -103,6 +115,7 @@ type QuotaCommitter interface {
 
 type Options struct {
 	Updater             JobUpdater
+	LogStreamer         JobLogStreamer
 	QuotaCommitter      QuotaCommitter
 	Logger              slog.Logger
 	Provisioner         sdkproto.DRPCProvisionerClient
@@ -142,6 +155,7 @@ func New(
 		metrics:             opts.Metrics,
 		job:                 job,
 		sender:              opts.Updater,
+		logStreamer:         opts.LogStreamer,
 		quotaCommitter:      opts.QuotaCommitter,
 		logger:              logger,
 		provisioner:         opts.Provisioner,
@@ -192,14 +206,14 @@ func (r *Runner) Run() {
 				build.Metadata.WorkspaceTransition.String(),
 				status,
 			).Inc()
-			r.metrics.WorkspaceBuildTimings.WithLabelValues(
+			observeWithTraceExemplar(r.metrics.WorkspaceBuildTimings.WithLabelValues(
 				build.Metadata.TemplateName,
 				build.Metadata.TemplateVersion,
 				build.Metadata.WorkspaceTransition.String(),
 				status,
-			).Observe(time.Since(start).Seconds())
+			), time.Since(start).Seconds(), span)
 		}
-		r.metrics.JobTimings.WithLabelValues(r.job.Provisioner, status).Observe(time.Since(start).Seconds())
+		observeWithTraceExemplar(r.metrics.JobTimings.WithLabelValues(r.job.Provisioner, status), time.Since(start).Seconds(), span)
 	}()
 
 	r.mutex.Lock()
@@ -354,6 +368,7 @@ func (r *Runner) update(ctx context.Context, u *proto.UpdateJobRequest) (*proto.
 		attribute.Int64("template_variables_len", int64(len(u.TemplateVariables))),
 		attribute.Int64("user_variable_values_len", int64(len(u.UserVariableValues))),
 		attribute.Int64("readme_len", int64(len(u.Readme))),
+		attribute.Int64("interim_state_len", int64(len(u.InterimState))),
 	)
 
 	r.mutex.Lock()
@@ -404,6 +419,7 @@ func (r *Runner) doCleanFinish(ctx context.Context) {
 			CreatedAt: time.Now().UnixMilli(),
 		})
 		r.flushQueuedLogs(ctx)
+		r.closeLogStream(ctx)
 	}()
 
 	completedJob, failedJob = r.do(ctx)
@@ -636,6 +652,7 @@ func (r *Runner) runTemplateImportParse(ctx context.Context) (
 				CreatedAt: time.Now().UnixMilli(),
 				Output:    msgType.Log.Output,
 				Stage:     "Parse parameters",
+				Fields:    msgType.Log.Fields,
 			})
 		case *sdkproto.Response_Parse:
 			pc := msgType.Parse
@@ -743,6 +760,7 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(
 				CreatedAt: time.Now().UnixMilli(),
 				Output:    msgType.Log.Output,
 				Stage:     stage,
+				Fields:    msgType.Log.Fields,
 			})
 		case *sdkproto.Response_DataUpload:
 			c := msgType.DataUpload
@@ -821,14 +839,25 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 
 	// Ensure all metadata fields are set as they are all optional for dry-run.
 	metadata := r.job.GetTemplateDryRun().GetMetadata()
-	metadata.WorkspaceTransition = sdkproto.WorkspaceTransition_START
+	// A dry-run of a template version previews a workspace start, while a
+	// dry-run with state checks an existing workspace for drift and keeps
+	// the transition of its build.
+	state := r.job.GetTemplateDryRun().GetState()
+	if len(state) == 0 {
+		metadata.WorkspaceTransition = sdkproto.WorkspaceTransition_START
+	}
 	if metadata.CoderUrl == "" {
 		metadata.CoderUrl = "http://localhost:3000"
 	}
 	if metadata.WorkspaceName == "" {
 		metadata.WorkspaceName = "dryrun"
 	}
-	metadata.WorkspaceOwner = r.job.UserName
+	test := r.job.GetTemplateDryRun().GetTest()
+	// Tests run as the synthetic owner that coderd assigns to them, so that
+	// they do not collide with the workspaces of the user.
+	if !test {
+		metadata.WorkspaceOwner = r.job.UserName
+	}
 	if metadata.WorkspaceOwner == "" {
 		metadata.WorkspaceOwner = "dryrunner"
 	}
@@ -849,10 +878,14 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 
 	failedJob := r.configure(&sdkproto.Config{
 		TemplateSourceArchive: r.job.GetTemplateSourceArchive(),
+		State:                 state,
 	})
 	if failedJob != nil {
 		return nil, failedJob
 	}
+	if test {
+		return r.runTemplateTest(ctx, metadata)
+	}
 
 	// Run the template import provision task since it's already a dry run.
 	provision, err := r.runTemplateImportProvisionWithRichParameters(ctx,
@@ -871,13 +904,120 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 			TemplateDryRun: &proto.CompletedJob_TemplateDryRun{
 				Resources: provision.Resources,
 				Modules:   provision.Modules,
+				Plan:      provision.Plan,
+			},
+		},
+	}, nil
+}
+
+// runTemplateTest tests the template version end-to-end by building a
+// workspace and destroying it again in the same provisioner session. The
+// workspace is destroyed even if the build fails or the test is canceled, so
+// that no resources are left behind.
+func (r *Runner) runTemplateTest(ctx context.Context, metadata *sdkproto.Metadata) (*proto.CompletedJob, *proto.FailedJob) {
+	ctx, span := r.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	dryRun := r.job.GetTemplateDryRun()
+	planComplete, applyComplete, failed := r.applyTemplateTest(ctx, "Starting workspace", &sdkproto.PlanRequest{
+		OmitModuleFiles:     true,
+		Metadata:            metadata,
+		RichParameterValues: dryRun.GetRichParameterValues(),
+		VariableValues:      dryRun.GetVariableValues(),
+	}, r.notCanceled.Done())
+	if applyComplete == nil {
+		// Nothing was applied, so there is nothing to destroy.
+		return nil, failed
+	}
+
+	// The start request has been sent, so the metadata can be reused.
+	metadata.WorkspaceTransition = sdkproto.WorkspaceTransition_DESTROY
+	_, _, destroyFailed := r.applyTemplateTest(ctx, "Destroying workspace", &sdkproto.PlanRequest{
+		OmitModuleFiles:     true,
+		Metadata:            metadata,
+		RichParameterValues: dryRun.GetRichParameterValues(),
+		VariableValues:      dryRun.GetVariableValues(),
+	}, nil)
+	if failed != nil {
+		return nil, failed
+	}
+	if destroyFailed != nil {
+		destroyFailed.Error = fmt.Sprintf("destroy test workspace, resources may have been left behind: %s", destroyFailed.Error)
+		return nil, destroyFailed
+	}
+
+	return &proto.CompletedJob{
+		JobId: r.job.JobId,
+		Type: &proto.CompletedJob_TemplateDryRun_{
+			TemplateDryRun: &proto.CompletedJob_TemplateDryRun{
+				Resources: applyComplete.Resources,
+				Modules:   planComplete.Modules,
+				Plan:      planComplete.Plan,
 			},
 		},
 	}, nil
 }
 
+// applyTemplateTest plans and applies a transition of a template test. The
+// apply is returned even if it failed, since its state may contain resources
+// that have to be destroyed.
+func (r *Runner) applyTemplateTest(ctx context.Context, stage string, plan *sdkproto.PlanRequest, canceled <-chan struct{}) (
+	*sdkproto.PlanComplete, *sdkproto.ApplyComplete, *proto.FailedJob,
+) {
+	resp, failed := r.buildWorkspaceWithCancel(ctx, "Planning infrastructure", &sdkproto.Request{
+		Type: &sdkproto.Request_Plan{Plan: plan},
+	}, canceled)
+	if failed != nil {
+		failed.Type = &proto.FailedJob_TemplateDryRun_{}
+		return nil, nil, failed
+	}
+	planComplete := resp.GetPlan()
+	if planComplete == nil {
+		return nil, nil, r.failedTemplateDryRunf("invalid message type %T received from provisioner", resp.Type)
+	}
+	if planComplete.Error != "" {
+		return nil, nil, r.failedTemplateDryRunf("%s", planComplete.Error)
+	}
+	r.flushQueuedLogs(ctx)
+
+	r.queueLog(ctx, &proto.Log{
+		Source:    proto.LogSource_PROVISIONER_DAEMON,
+		Level:     sdkproto.LogLevel_INFO,
+		Stage:     stage,
+		CreatedAt: time.Now().UnixMilli(),
+	})
+	resp, failed = r.buildWorkspaceWithCancel(ctx, stage, &sdkproto.Request{
+		Type: &sdkproto.Request_Apply{
+			Apply: &sdkproto.ApplyRequest{
+				Metadata: plan.Metadata,
+			},
+		},
+	}, canceled)
+	if failed != nil {
+		failed.Type = &proto.FailedJob_TemplateDryRun_{}
+		return nil, nil, failed
+	}
+	applyComplete := resp.GetApply()
+	if applyComplete == nil {
+		return nil, nil, r.failedTemplateDryRunf("invalid message type %T received from provisioner", resp.Type)
+	}
+	r.flushQueuedLogs(ctx)
+	if applyComplete.Error != "" {
+		return planComplete, applyComplete, r.failedTemplateDryRunf("%s", applyComplete.Error)
+	}
+	return planComplete, applyComplete, nil
+}
+
 func (r *Runner) buildWorkspace(ctx context.Context, stage string, req *sdkproto.Request) (
 	*sdkproto.Response, *proto.FailedJob,
+) {
+	return r.buildWorkspaceWithCancel(ctx, stage, req, r.notCanceled.Done())
+}
+
+// buildWorkspaceWithCancel sends the request to the provisioner, and cancels
+// it when canceled is closed. A nil channel never cancels the request.
+func (r *Runner) buildWorkspaceWithCancel(ctx context.Context, stage string, req *sdkproto.Request, canceled <-chan struct{}) (
+	*sdkproto.Response, *proto.FailedJob,
 ) {
 	// use the notStopped so that if we attempt to gracefully cancel, the stream
 	// will still be available for us to send the cancel to the provisioner
@@ -893,7 +1033,7 @@ func (r *Runner) buildWorkspace(ctx context.Context, stage string, req *sdkproto
 			return
 		case <-r.notStopped.Done():
 			return
-		case <-r.notCanceled.Done():
+		case <-canceled:
 			_ = r.session.Send(&sdkproto.Request{
 				Type: &sdkproto.Request_Cancel{
 					Cancel: &sdkproto.CancelRequest{},
@@ -921,11 +1061,17 @@ func (r *Runner) buildWorkspace(ctx context.Context, stage string, req *sdkproto
 				CreatedAt: time.Now().UnixMilli(),
 				Output:    msgType.Log.Output,
 				Stage:     stage,
+				Fields:    msgType.Log.Fields,
 			})
 		case *sdkproto.Response_DataUpload:
 			continue // Only for template imports
 		case *sdkproto.Response_ChunkPiece:
 			continue // Only for template imports
+		case *sdkproto.Response_InterimState:
+			// Only workspace builds are resumed from their interim state.
+			if r.job.GetWorkspaceBuild() != nil {
+				r.uploadInterimState(ctx, msgType.InterimState.State)
+			}
 		default:
 			// Stop looping!
 			return msg, nil
@@ -933,13 +1079,28 @@ func (r *Runner) buildWorkspace(ctx context.Context, stage string, req *sdkproto
 	}
 }
 
+// uploadInterimState persists the state of an apply that is in progress. A
+// failure is not fatal to the build, the state is only used to resume builds
+// that do not complete.
+func (r *Runner) uploadInterimState(ctx context.Context, state []byte) {
+	_, err := r.update(ctx, &proto.UpdateJobRequest{
+		JobId:        r.job.JobId,
+		InterimState: state,
+	})
+	if err != nil && !errors.Is(err, errUpdateSkipped) {
+		r.logger.Warn(ctx, "upload interim state", slog.Error(err))
+	}
+}
+
 func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource) *proto.FailedJob {
 	cost := sumDailyCost(resources)
 	r.logger.Debug(ctx, "committing quota",
 		slog.F("resources", resourceNames(resources)),
 		slog.F("cost", cost),
 	)
-	if cost == 0 {
+	// The resources are sent even if they cost nothing, so that coderd can
+	// check them against the resource ceilings of the template.
+	if cost == 0 && len(resources) == 0 {
 		return nil
 	}
 
@@ -949,6 +1110,7 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 		JobId: r.job.JobId,
 		// #nosec G115 - Safe conversion as cost is expected to be within int32 range for provisioning costs
 		DailyCost: int32(cost),
+		Resources: resources,
 	})
 	if err != nil {
 		r.queueLog(ctx, &proto.Log{
@@ -960,11 +1122,35 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 		})
 		return r.failedJobf("commit quota: %+v", err)
 	}
-	for _, line := range []string{
+	if len(resp.ResourceCeilingViolations) > 0 {
+		violations := make([]string, 0, len(resp.ResourceCeilingViolations))
+		for _, v := range resp.ResourceCeilingViolations {
+			violations = append(violations, fmt.Sprintf("%s %v > %v", v.Resource, v.Planned, v.Ceiling))
+			r.queueLog(ctx, &proto.Log{
+				Source:    proto.LogSource_PROVISIONER,
+				Level:     sdkproto.LogLevel_ERROR,
+				CreatedAt: time.Now().UnixMilli(),
+				Output:    fmt.Sprintf("Planned %s of %v exceeds the template ceiling of %v.", v.Resource, v.Planned, v.Ceiling),
+				Stage:     stage,
+			})
+		}
+		return r.failedWorkspaceBuildf("planned resources %s of the template: %s", resourceCeilingExceededErrorText, strings.Join(violations, ", "))
+	}
+	if cost == 0 {
+		return nil
+	}
+	lines := []string{
 		fmt.Sprintf("Build cost       —   %v", cost),
 		fmt.Sprintf("Budget           —   %v", resp.Budget),
 		fmt.Sprintf("Credits consumed —   %v", resp.CreditsConsumed),
-	} {
+	}
+	if resp.MonthlyBudget > 0 {
+		lines = append(lines,
+			fmt.Sprintf("Monthly budget   —   %v", resp.MonthlyBudget),
+			fmt.Sprintf("Monthly spend    —   %.2f", resp.MonthlyCreditsSpent),
+		)
+	}
+	for _, line := range lines {
 		r.queueLog(ctx, &proto.Log{
 			Source:    proto.LogSource_PROVISIONER,
 			Level:     sdkproto.LogLevel_INFO,
@@ -975,11 +1161,15 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 	}
 
 	if !resp.Ok {
+		output := "This build would exceed your quota. Failing."
+		if resp.MonthlyBudget > 0 && resp.MonthlyCreditsSpent >= float64(resp.MonthlyBudget) {
+			output = "Your monthly budget has been spent. Failing."
+		}
 		r.queueLog(ctx, &proto.Log{
 			Source:    proto.LogSource_PROVISIONER,
 			Level:     sdkproto.LogLevel_WARN,
 			CreatedAt: time.Now().UnixMilli(),
-			Output:    "This build would exceed your quota. Failing.",
+			Output:    output,
 			Stage:     stage,
 		})
 		return r.failedWorkspaceBuildf("insufficient quota")
@@ -1152,6 +1342,12 @@ func (r *Runner) failedWorkspaceBuildf(format string, args ...interface{}) *prot
 	return failedJob
 }
 
+func (r *Runner) failedTemplateDryRunf(format string, args ...interface{}) *proto.FailedJob {
+	failedJob := r.failedJobf(format, args...)
+	failedJob.Type = &proto.FailedJob_TemplateDryRun_{}
+	return failedJob
+}
+
 func (r *Runner) failedJobf(format string, args ...interface{}) *proto.FailedJob {
 	message := fmt.Sprintf(format, args...)
 	var code string
@@ -1176,6 +1372,21 @@ func (r *Runner) startTrace(ctx context.Context, name string, opts ...trace.Span
 	))...)
 }
 
+// observeWithTraceExemplar observes the value, attaching the trace ID of the
+// span as an exemplar when the span is part of a trace. This lets slow jobs in
+// a histogram bucket be correlated with their trace.
+func observeWithTraceExemplar(observer prometheus.Observer, value float64, span trace.Span) {
+	spanContext := span.SpanContext()
+	exemplarObserver, ok := observer.(prometheus.ExemplarObserver)
+	if !ok || !spanContext.HasTraceID() {
+		observer.Observe(value)
+		return
+	}
+	exemplarObserver.ObserveWithExemplar(value, prometheus.Labels{
+		"trace_id": spanContext.TraceID().String(),
+	})
+}
+
 // queueLog adds a log to the buffer and debounces a timer
 // if one exists to flush the logs. It stores a maximum of
 // 100 log lines before flushing as a safe-guard mechanism.
@@ -1206,6 +1417,9 @@ func (r *Runner) flushQueuedLogs(ctx context.Context) {
 	logs := r.queuedLogs
 	r.queuedLogs = make([]*proto.Log, 0)
 	r.mutex.Unlock()
+	if len(logs) > 0 && r.streamLogs(ctx, logs) {
+		return
+	}
 	_, err := r.update(ctx, &proto.UpdateJobRequest{
 		JobId: r.job.JobId,
 		Logs:  logs,
@@ -1218,6 +1432,93 @@ func (r *Runner) flushQueuedLogs(ctx context.Context) {
 	}
 }
 
+// streamLogs sends logs over the log stream of the job, and opens it if
+// needed. It returns false if the logs must be sent with UpdateJob instead.
+func (r *Runner) streamLogs(ctx context.Context, logs []*proto.Log) bool {
+	if r.logStreamer == nil {
+		return false
+	}
+	r.logStreamMu.Lock()
+	defer r.logStreamMu.Unlock()
+	if r.logStreamFailed {
+		return false
+	}
+	r.mutex.Lock()
+	okToSend := r.okToSend
+	r.mutex.Unlock()
+	if !okToSend {
+		// Like UpdateJob, skip logs once the job is complete or failed.
+		return true
+	}
+
+	if r.logStream == nil {
+		// The stream outlives the context of any single flush.
+		stream, err := r.logStreamer.StreamJobLogs(r.notStopped)
+		if err != nil {
+			r.logger.Warn(ctx, "open log stream, falling back to UpdateJob", slog.Error(err))
+			r.logStreamFailed = true
+			return false
+		}
+		r.logStream = newLogStream(r.job.JobId, stream, r.logStreamBroken)
+	}
+
+	err := r.logStream.send(ctx, logs)
+	if err == nil {
+		return true
+	}
+	// The server might not support streaming logs, or the connection broke.
+	// Either way, the logs it didn't acknowledge, including these, are sent
+	// with UpdateJob.
+	r.logger.Warn(ctx, "stream logs, falling back to UpdateJob", slog.Error(err))
+	r.logStreamFailed = true
+	unacked := r.logStream.close(ctx)
+	r.logStream = nil
+	r.sendLogs(ctx, unacked)
+	return true
+}
+
+// logStreamBroken sends the logs a broken log stream didn't get acknowledged
+// with UpdateJob, so they aren't held back until the next flush.
+func (r *Runner) logStreamBroken(ls *logStream) {
+	r.logStreamMu.Lock()
+	defer r.logStreamMu.Unlock()
+	if r.logStream != ls {
+		return
+	}
+	r.logger.Warn(r.notStopped, "log stream broke, falling back to UpdateJob")
+	r.logStreamFailed = true
+	unacked := ls.close(r.notStopped)
+	r.logStream = nil
+	r.sendLogs(r.notStopped, unacked)
+}
+
+// closeLogStream closes the log stream of the job, if any, once the server has
+// inserted every log sent over it.
+func (r *Runner) closeLogStream(ctx context.Context) {
+	r.logStreamMu.Lock()
+	defer r.logStreamMu.Unlock()
+	if r.logStream == nil {
+		return
+	}
+	unacked := r.logStream.close(ctx)
+	r.logStream = nil
+	r.sendLogs(ctx, unacked)
+}
+
+// sendLogs sends logs with UpdateJob.
+func (r *Runner) sendLogs(ctx context.Context, logs []*proto.Log) {
+	if len(logs) == 0 {
+		return
+	}
+	_, err := r.update(ctx, &proto.UpdateJobRequest{
+		JobId: r.job.JobId,
+		Logs:  logs,
+	})
+	if err != nil && !errors.Is(err, errUpdateSkipped) {
+		r.logger.Error(ctx, "send logs", slog.Error(err))
+	}
+}
+
 func redactVariableValues(variableValues []*sdkproto.VariableValue) []*sdkproto.VariableValue {
 	var redacted []*sdkproto.VariableValue
 	for _, v := range variableValues {
@@ -1,6 +1,11 @@
 package provisionersdk
 
-import "github.com/google/uuid"
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
 
 const (
 	TagScope = "scope"
@@ -8,6 +13,9 @@ const (
 
 	ScopeUser         = "user"
 	ScopeOrganization = "organization"
+
+	// TagValueAny matches any non-empty value of a required tag.
+	TagValueAny = "*"
 )
 
 // MutateTags adjusts the "owner" tag dependent on the "scope".
@@ -42,6 +50,21 @@ func MutateTags(userID uuid.UUID, provided ...map[string]string) map[string]stri
 	return tags
 }
 
+// MissingTags returns the required tags that are not present in tags,
+// formatted as sorted "key=value" pairs. A required value of "*" is
+// satisfied by any non-empty value.
+func MissingTags(required, tags map[string]string) []string {
+	var missing []string
+	for key, want := range required {
+		got := tags[key]
+		if got == "" || (want != TagValueAny && got != want) {
+			missing = append(missing, fmt.Sprintf("%s=%s", key, want))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
 // mergeTags merges two sets of provisioner tags.
 // If b[key] is an empty string, the value from a[key] is retained.
 // This function handles nil maps gracefully.
@@ -192,3 +192,53 @@ func TestMutateTags(t *testing.T) {
 		})
 	}
 }
+
+func TestMissingTags(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name     string
+		required map[string]string
+		tags     map[string]string
+		want     []string
+	}{
+		{
+			name: "no requirements",
+			tags: map[string]string{"foo": "bar"},
+		},
+		{
+			name:     "satisfied",
+			required: map[string]string{"pool": "hardened"},
+			tags:     map[string]string{"pool": "hardened", "foo": "bar"},
+		},
+		{
+			name:     "wrong value",
+			required: map[string]string{"pool": "hardened"},
+			tags:     map[string]string{"pool": "default"},
+			want:     []string{"pool=hardened"},
+		},
+		{
+			name:     "any value",
+			required: map[string]string{"pool": provisionersdk.TagValueAny},
+			tags:     map[string]string{"pool": "default"},
+		},
+		{
+			name:     "any value missing",
+			required: map[string]string{"pool": provisionersdk.TagValueAny},
+			tags:     map[string]string{"pool": ""},
+			want:     []string{"pool=*"},
+		},
+		{
+			name:     "sorted",
+			required: map[string]string{"zone": "eu", "pool": "hardened"},
+			tags:     nil,
+			want:     []string{"pool=hardened", "zone=eu"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := provisionersdk.MissingTags(tt.required, tt.tags)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
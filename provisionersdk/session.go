@@ -320,6 +320,12 @@ func (s *Session) extractArchive() error {
 }
 
 func (s *Session) ProvisionLog(level proto.LogLevel, output string) {
+	s.ProvisionLogWithFields(level, output, nil)
+}
+
+// ProvisionLogWithFields sends a log with structured fields extracted from
+// machine-readable provisioner output. Fields may be nil.
+func (s *Session) ProvisionLogWithFields(level proto.LogLevel, output string, fields *proto.LogFields) {
 	if int32(level) < s.logLevel {
 		return
 	}
@@ -327,6 +333,7 @@ func (s *Session) ProvisionLog(level proto.LogLevel, output string) {
 	err := s.stream.Send(&proto.Response{Type: &proto.Response_Log{Log: &proto.Log{
 		Level:  level,
 		Output: output,
+		Fields: fields,
 	}}})
 	if err != nil {
 		s.Logger.Error(s.Context(), "failed to transmit log",
@@ -334,6 +341,17 @@ func (s *Session) ProvisionLog(level proto.LogLevel, output string) {
 	}
 }
 
+// UploadInterimState sends the provisioner state of an apply that is still in
+// progress, so the build can be resumed if it does not complete.
+func (s *Session) UploadInterimState(state []byte) {
+	err := s.stream.Send(&proto.Response{Type: &proto.Response_InterimState{InterimState: &proto.InterimState{
+		State: state,
+	}}})
+	if err != nil {
+		s.Logger.Error(s.Context(), "failed to transmit interim state", slog.Error(err))
+	}
+}
+
 type pRequest interface {
 	*proto.ParseRequest | *proto.PlanRequest | *proto.ApplyRequest
 }
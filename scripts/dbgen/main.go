@@ -61,12 +61,20 @@ func run() error {
 	}
 
 	err = orderAndStubDatabaseFunctions(filepath.Join(databasePath, "dbmetrics", "querymetrics.go"), "m", "queryMetricsStore", func(params stubParams) string {
+		returns := strings.Split(params.Returns, ",")
+		rows := "1"
+		switch {
+		case len(returns) == 1:
+			rows = "noRows"
+		case params.ReturnsSlice:
+			rows = "len(r0)"
+		}
 		return fmt.Sprintf(`
 start := time.Now()
 %s := m.s.%s(%s)
-m.queryLatencies.WithLabelValues("%s").Observe(time.Since(start).Seconds())
+m.observe(ctx, "%s", start, %s, %s)
 return %s
-`, params.Returns, params.FuncName, params.Parameters, params.FuncName, params.Returns)
+`, params.Returns, params.FuncName, params.Parameters, params.FuncName, rows, returns[len(returns)-1], params.Returns)
 	})
 	if err != nil {
 		return xerrors.Errorf("stub dbmetrics: %w", err)
@@ -144,7 +152,7 @@ const (
 		name := ""
 		switch {
 		case strings.Contains(query, "ALTER TABLE") && strings.Contains(query, "ADD CONSTRAINT"):
-			name = strings.Split(query, " ")[6]
+			name = addConstraintName(query)
 		case strings.Contains(query, "CREATE UNIQUE INDEX"):
 			name = strings.Split(query, " ")[3]
 		default:
@@ -218,7 +226,7 @@ const (
 		name := ""
 		switch {
 		case strings.Contains(query, "ALTER TABLE") && strings.Contains(query, "ADD CONSTRAINT"):
-			name = strings.Split(query, " ")[6]
+			name = addConstraintName(query)
 		default:
 			return xerrors.Errorf("unknown foreign key constraint format: %s", query)
 		}
@@ -237,10 +245,22 @@ const (
 	return os.WriteFile(outputPath, data, 0o600)
 }
 
+// addConstraintName returns the name of the constraint added by an
+// "ALTER TABLE ... ADD CONSTRAINT" statement. pg_dump omits ONLY for
+// constraints on partitioned tables, so the name cannot be found at a fixed
+// position.
+func addConstraintName(query string) string {
+	_, after, _ := strings.Cut(query, "ADD CONSTRAINT ")
+	name, _, _ := strings.Cut(after, " ")
+	return name
+}
+
 type stubParams struct {
 	FuncName   string
 	Parameters string
 	Returns    string
+	// ReturnsSlice is true if the first result of the function is a slice.
+	ReturnsSlice bool
 }
 
 // orderAndStubDatabaseFunctions orders the functions in the file and stubs them.
@@ -389,10 +409,16 @@ func orderAndStubDatabaseFunctions(filePath, receiver, structName string, stub f
 				}
 			}
 
+			returnsSlice := false
+			if fn.Func.Results != nil && len(fn.Func.Results.List) > 0 {
+				_, returnsSlice = fn.Func.Results.List[0].Type.(*dst.ArrayType)
+			}
+
 			funcDecl, err := compileFuncDecl(stub(stubParams{
-				FuncName:   fn.Name,
-				Parameters: strings.Join(params, ","),
-				Returns:    strings.Join(returns, ","),
+				FuncName:     fn.Name,
+				Parameters:   strings.Join(params, ","),
+				Returns:      strings.Join(returns, ","),
+				ReturnsSlice: returnsSlice,
 			}))
 			if err != nil {
 				return xerrors.Errorf("compile func decl: %w", err)
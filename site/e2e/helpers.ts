@@ -647,6 +647,8 @@ const createTemplateVersionTar = async (
 						token: randomUUID(),
 						devcontainers: [],
 						apiKeyScope: "all",
+						displayGroup: "",
+						collapsed: false,
 						...agent,
 					} as Agent;
 
@@ -679,6 +681,9 @@ const createTemplateVersionTar = async (
 			name: "dev",
 			type: "echo",
 			modulePath: "",
+			displayGroup: "",
+			order: 0,
+			collapsed: false,
 			...resource,
 		} as Resource;
 	};
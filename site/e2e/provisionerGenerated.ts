@@ -181,10 +181,21 @@ export interface VariableValue {
   sensitive: boolean;
 }
 
+/**
+ * LogFields are optional structured fields extracted from machine-readable
+ * provisioner output, so consumers don't have to parse the log output.
+ */
+export interface LogFields {
+  resourceAddress: string;
+  action: string;
+  diagnosticSeverity: string;
+}
+
 /** Log represents output from a request. */
 export interface Log {
   level: LogLevel;
   output: string;
+  fields: LogFields | undefined;
 }
 
 export interface InstanceIdentityAuth {
@@ -229,6 +240,10 @@ export interface Agent {
   resourcesMonitoring: ResourcesMonitoring | undefined;
   devcontainers: Devcontainer[];
   apiKeyScope: string;
+  /** display_group groups agents in user interfaces. */
+  displayGroup: string;
+  /** collapsed hints that user interfaces should collapse the agent by default. */
+  collapsed: boolean;
 }
 
 export interface Agent_Metadata {
@@ -285,6 +300,7 @@ export interface Script {
   runOnStop: boolean;
   timeoutSeconds: number;
   logPath: string;
+  runOnClaim: boolean;
 }
 
 export interface Devcontainer {
@@ -334,6 +350,15 @@ export interface Resource {
   instanceType: string;
   dailyCost: number;
   modulePath: string;
+  /** display_group groups resources in user interfaces. */
+  displayGroup: string;
+  /** order is the position of the resource in user interfaces. */
+  order: number;
+  /**
+   * collapsed hints that user interfaces should collapse the resource by
+   * default.
+   */
+  collapsed: boolean;
 }
 
 export interface Resource_Metadata {
@@ -510,6 +535,16 @@ export interface Response {
   apply?: ApplyComplete | undefined;
   dataUpload?: DataUpload | undefined;
   chunkPiece?: ChunkPiece | undefined;
+  interimState?: InterimState | undefined;
+}
+
+/**
+ * InterimState is the provisioner state persisted while an apply is in
+ * progress. It is sent whenever the state changes, so a build that does not
+ * complete can be resumed from the resources that were already created.
+ */
+export interface InterimState {
+  state: Uint8Array;
 }
 
 export interface DataUpload {
@@ -758,6 +793,21 @@ export const VariableValue = {
   },
 };
 
+export const LogFields = {
+  encode(message: LogFields, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.resourceAddress !== "") {
+      writer.uint32(10).string(message.resourceAddress);
+    }
+    if (message.action !== "") {
+      writer.uint32(18).string(message.action);
+    }
+    if (message.diagnosticSeverity !== "") {
+      writer.uint32(26).string(message.diagnosticSeverity);
+    }
+    return writer;
+  },
+};
+
 export const Log = {
   encode(message: Log, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
     if (message.level !== 0) {
@@ -766,6 +816,9 @@ export const Log = {
     if (message.output !== "") {
       writer.uint32(18).string(message.output);
     }
+    if (message.fields !== undefined) {
+      LogFields.encode(message.fields, writer.uint32(26).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -865,6 +918,12 @@ export const Agent = {
     if (message.apiKeyScope !== "") {
       writer.uint32(210).string(message.apiKeyScope);
     }
+    if (message.displayGroup !== "") {
+      writer.uint32(218).string(message.displayGroup);
+    }
+    if (message.collapsed === true) {
+      writer.uint32(224).bool(message.collapsed);
+    }
     return writer;
   },
 };
@@ -1006,6 +1065,9 @@ export const Script = {
     if (message.logPath !== "") {
       writer.uint32(74).string(message.logPath);
     }
+    if (message.runOnClaim === true) {
+      writer.uint32(80).bool(message.runOnClaim);
+    }
     return writer;
   },
 };
@@ -1117,6 +1179,15 @@ export const Resource = {
     if (message.modulePath !== "") {
       writer.uint32(74).string(message.modulePath);
     }
+    if (message.displayGroup !== "") {
+      writer.uint32(82).string(message.displayGroup);
+    }
+    if (message.order !== 0) {
+      writer.uint32(88).int64(message.order);
+    }
+    if (message.collapsed === true) {
+      writer.uint32(96).bool(message.collapsed);
+    }
     return writer;
   },
 };
@@ -1501,6 +1572,18 @@ export const Response = {
     if (message.chunkPiece !== undefined) {
       ChunkPiece.encode(message.chunkPiece, writer.uint32(50).fork()).ldelim();
     }
+    if (message.interimState !== undefined) {
+      InterimState.encode(message.interimState, writer.uint32(58).fork()).ldelim();
+    }
+    return writer;
+  },
+};
+
+export const InterimState = {
+  encode(message: InterimState, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.state.length !== 0) {
+      writer.uint32(10).bytes(message.state);
+    }
     return writer;
   },
 };
@@ -492,6 +492,12 @@ class ApiMethods {
 		return response.data;
 	};
 
+	authorizeLoginDevice = async (
+		req: TypesGen.AuthorizeLoginDeviceRequest,
+	): Promise<void> => {
+		await this.axios.post<void>("/api/v2/users/login/device/authorize", req);
+	};
+
 	getTokens = async (
 		params: TypesGen.TokensFilter,
 	): Promise<TypesGen.APIKeyWithOwner[]> => {
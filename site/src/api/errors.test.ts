@@ -1,5 +1,6 @@
 import { mockApiError } from "testHelpers/entities";
 import {
+	getErrorCode,
 	getErrorMessage,
 	getValidationErrorMessage,
 	isApiError,
@@ -101,3 +102,21 @@ describe("getValidationErrorMessage", () => {
 		).toBe("Something went wrong.");
 	});
 });
+
+describe("getErrorCode", () => {
+	it("returns the code of an API error", () => {
+		expect(
+			getErrorCode(
+				mockApiError({
+					message: "A workspace build is already active.",
+					code: "build_in_progress",
+				}),
+			),
+		).toBe("build_in_progress");
+	});
+
+	it("returns undefined for errors without a code", () => {
+		expect(getErrorCode(mockApiError({}))).toBeUndefined();
+		expect(getErrorCode(new Error("Something went wrong."))).toBeUndefined();
+	});
+});
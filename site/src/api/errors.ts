@@ -1,4 +1,5 @@
 import { type AxiosError, type AxiosResponse, isAxiosError } from "axios";
+import type { ResponseCode } from "./typesGenerated";
 
 const Language = {
 	errorsByCode: {
@@ -17,6 +18,7 @@ export interface ApiErrorResponse {
 	message: string;
 	detail?: string;
 	validations?: FieldError[];
+	code?: ResponseCode;
 }
 
 export type ApiError = AxiosError<ApiErrorResponse> & {
@@ -141,6 +143,18 @@ export const getErrorStatus = (error: unknown): number | undefined => {
 	return undefined;
 };
 
+/**
+ * Returns the code of an API error, so callers can handle specific errors
+ * without matching on the message.
+ */
+export const getErrorCode = (error: unknown): ResponseCode | undefined => {
+	if (isApiError(error)) {
+		return error.response.data.code;
+	}
+
+	return undefined;
+};
+
 export class DetailedError extends Error {
 	constructor(
 		message: string,
@@ -275,3 +275,9 @@ export const changePasswordWithOTP = () => {
 		mutationFn: API.changePasswordWithOTP,
 	};
 };
+
+export const authorizeLoginDevice = () => {
+	return {
+		mutationFn: API.authorizeLoginDevice,
+	};
+};
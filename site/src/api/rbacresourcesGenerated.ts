@@ -135,6 +135,12 @@ export const RBACResourceActions: Partial<
 	replicas: {
 		read: "read replicas",
 	},
+	service_account: {
+		create: "create a service account",
+		delete: "delete a service account",
+		read: "read service accounts",
+		update: "update a service account and manage its tokens",
+	},
 	system: {
 		create: "create system resources",
 		delete: "delete system resources",
@@ -29,15 +29,69 @@ export interface APIKey {
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect";
+export type APIKeyScope =
+	| "all"
+	| "application_connect"
+	| "build:create"
+	| "template:admin"
+	| "workspace:read";
 
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"];
+export const APIKeyScopes: APIKeyScope[] = [
+	"all",
+	"application_connect",
+	"build:create",
+	"template:admin",
+	"workspace:read",
+];
 
 // From codersdk/apikey.go
 export interface APIKeyWithOwner extends APIKey {
 	readonly username: string;
 }
 
+// From codersdk/capabilities.go
+export interface APIVersions {
+	readonly rest: readonly string[];
+	readonly agent: string;
+	readonly provisioner: string;
+	readonly tailnet: string;
+}
+
+// From codersdk/accessrequests.go
+export interface AccessRequest {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly requester_id: string;
+	readonly resource_type: AccessRequestResourceType;
+	readonly resource_id: string;
+	readonly role: string;
+	readonly reason: string;
+	readonly duration_ms: number;
+	readonly status: AccessRequestStatus;
+	readonly reviewer_id?: string;
+	readonly reviewed_at?: string;
+	readonly expires_at?: string;
+	readonly created_at: string;
+}
+
+// From codersdk/accessrequests.go
+export type AccessRequestResourceType = "template" | "workspace";
+
+export const AccessRequestResourceTypes: AccessRequestResourceType[] = [
+	"template",
+	"workspace",
+];
+
+// From codersdk/accessrequests.go
+export type AccessRequestStatus = "approved" | "denied" | "expired" | "pending";
+
+export const AccessRequestStatuses: AccessRequestStatus[] = [
+	"approved",
+	"denied",
+	"expired",
+	"pending",
+];
+
 // From healthsdk/healthsdk.go
 export interface AccessURLReport extends BaseReport {
 	readonly healthy: boolean;
@@ -153,6 +207,24 @@ export const AuditActions: AuditAction[] = [
 	"write",
 ];
 
+// From codersdk/audit.go
+export type AuditClientType =
+	| "browser"
+	| "cli"
+	| "jetbrains"
+	| "ssh"
+	| "unknown"
+	| "vscode";
+
+export const AuditClientTypes: AuditClientType[] = [
+	"browser",
+	"cli",
+	"jetbrains",
+	"ssh",
+	"unknown",
+	"vscode",
+];
+
 // From codersdk/audit.go
 export type AuditDiff = Record<string, AuditDiffField>;
 
@@ -188,6 +260,17 @@ export interface AuditLog {
 	readonly user: User | null;
 }
 
+// From codersdk/deployment.go
+export interface AuditLogExportConfig {
+	readonly webhook_url: string;
+	readonly kafka_brokers: string;
+	readonly kafka_topic: string;
+	readonly s3_bucket: string;
+	readonly s3_prefix: string;
+	readonly batch_size: number;
+	readonly interval: number;
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
 	readonly audit_logs: readonly AuditLog[];
@@ -235,6 +318,11 @@ export interface AuthorizationRequest {
 // From codersdk/authorization.go
 export type AuthorizationResponse = Record<string, boolean>;
 
+// From codersdk/users.go
+export interface AuthorizeLoginDeviceRequest {
+	readonly user_code: string;
+}
+
 // From codersdk/workspaces.go
 export type AutomaticUpdates = "always" | "never";
 
@@ -260,6 +348,44 @@ export interface BaseReport {
 	readonly dismissed: boolean;
 }
 
+// From codersdk/buildalertrules.go
+export interface BuildAlertRule {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly template_id?: string;
+	readonly name: string;
+	readonly metric: BuildAlertRuleMetric;
+	readonly threshold: number;
+	readonly window_ms: number;
+	readonly last_value?: number;
+	readonly last_evaluated_at?: string;
+	readonly breached_at?: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/buildalertrules.go
+export type BuildAlertRuleMetric = "failure_rate" | "queue_wait_p95";
+
+export const BuildAlertRuleMetrics: BuildAlertRuleMetric[] = [
+	"failure_rate",
+	"queue_wait_p95",
+];
+
+// From codersdk/workspacebuilds.go
+export type BuildAutomation =
+	| "build_queue"
+	| "lifecycle_executor"
+	| "prebuilds"
+	| "workspace_migration";
+
+export const BuildAutomations: BuildAutomation[] = [
+	"build_queue",
+	"lifecycle_executor",
+	"prebuilds",
+	"workspace_migration",
+];
+
 // From codersdk/deployment.go
 export interface BuildInfoResponse {
 	readonly external_url: string;
@@ -272,14 +398,32 @@ export interface BuildInfoResponse {
 	readonly upgrade_message: string;
 	readonly deployment_id: string;
 	readonly webpush_public_key?: string;
+	readonly experiments?: readonly Experiment[];
+}
+
+// From codersdk/buildpause.go
+export interface BuildPause {
+	readonly paused: boolean;
+	readonly reason?: string;
+	readonly paused_at?: string;
+	readonly paused_by?: string;
 }
 
 // From codersdk/workspacebuilds.go
-export type BuildReason = "autostart" | "autostop" | "initiator";
+export type BuildReason =
+	| "autodelete"
+	| "autostart"
+	| "autostop"
+	| "dormancy"
+	| "failedstop"
+	| "initiator";
 
 export const BuildReasons: BuildReason[] = [
+	"autodelete",
 	"autostart",
 	"autostop",
+	"dormancy",
+	"failedstop",
 	"initiator",
 ];
 
@@ -292,6 +436,13 @@ export const BypassRatelimitHeader = "X-Coder-Bypass-Ratelimit";
 // From codersdk/client.go
 export const CLITelemetryHeader = "Coder-CLI-Telemetry";
 
+// From codersdk/capabilities.go
+export interface Capabilities {
+	readonly api_versions: APIVersions;
+	readonly experiments: Record<Experiment, boolean>;
+	readonly features: Record<FeatureName, boolean>;
+}
+
 // From codersdk/users.go
 export interface ChangePasswordWithOneTimePasscodeRequest {
 	readonly email: string;
@@ -308,6 +459,9 @@ export interface ConnectionLatency {
 	readonly p95: number;
 }
 
+// From codersdk/workspacesessionrecordings.go
+export const ContentTypeAsciicast = "application/x-asciicast";
+
 // From codersdk/files.go
 export const ContentTypeTar = "application/x-tar";
 
@@ -320,6 +474,60 @@ export interface ConvertLoginRequest {
 	readonly password: string;
 }
 
+// From codersdk/insights.go
+export interface CostInsightsGroup {
+	readonly id: string;
+	readonly name: string;
+	readonly cost: number;
+	readonly days: readonly DailyCost[];
+}
+
+// From codersdk/insights.go
+export type CostInsightsGroupBy = "organization" | "user" | "workspace";
+
+export const CostInsightsGroupBys: CostInsightsGroupBy[] = ["organization", "user", "workspace"];
+
+// From codersdk/insights.go
+export interface CostInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly group_by: CostInsightsGroupBy;
+	readonly total_cost: number;
+	readonly groups: readonly CostInsightsGroup[];
+}
+
+// From codersdk/insights.go
+export interface CostInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly group_by: CostInsightsGroupBy;
+}
+
+// From codersdk/insights.go
+export interface CostInsightsResponse {
+	readonly report: CostInsightsReport;
+}
+
+// From codersdk/accessrequests.go
+export interface CreateAccessRequest {
+	readonly resource_type: AccessRequestResourceType;
+	readonly resource_id: string;
+	readonly role: string;
+	readonly reason: string;
+	readonly duration_ms: number;
+}
+
+// From codersdk/buildalertrules.go
+export interface CreateBuildAlertRuleRequest {
+	readonly template_id?: string;
+	readonly name: string;
+	readonly metric: BuildAlertRuleMetric;
+	readonly threshold: number;
+	readonly window_ms: number;
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
 	readonly email: string;
@@ -353,6 +561,7 @@ export interface CreateGroupRequest {
 	readonly display_name: string;
 	readonly avatar_url: string;
 	readonly quota_allowance: number;
+	readonly monthly_budget: number;
 }
 
 // From codersdk/organizations.go
@@ -361,12 +570,14 @@ export interface CreateOrganizationRequest {
 	readonly display_name?: string;
 	readonly description?: string;
 	readonly icon?: string;
+	readonly cost_center?: string;
 }
 
 // From codersdk/provisionerdaemons.go
 export interface CreateProvisionerKeyRequest {
 	readonly name: string;
 	readonly tags: Record<string, string>;
+	readonly expires_at?: string;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -374,6 +585,30 @@ export interface CreateProvisionerKeyResponse {
 	readonly key: string;
 }
 
+// From codersdk/provisionerreservations.go
+export interface CreateProvisionerReservationRequest {
+	readonly tags: Record<string, string>;
+	readonly slots: number;
+	readonly starts_at: string;
+	readonly ends_at: string;
+}
+
+// From codersdk/serviceaccounts.go
+export interface CreateServiceAccountRequest {
+	readonly username: string;
+	readonly name: string;
+	readonly description: string;
+	readonly roles?: readonly string[];
+}
+
+// From codersdk/templatepresets.go
+export interface CreateTemplatePresetRequest {
+	readonly name: string;
+	readonly description?: string;
+	readonly parameters: readonly WorkspaceBuildParameter[];
+	readonly default_group_ids?: readonly string[];
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateRequest {
 	readonly name: string;
@@ -411,11 +646,17 @@ export interface CreateTemplateVersionRequest {
 	readonly storage_method: ProvisionerStorageMethod;
 	readonly file_id?: string;
 	readonly example_id?: string;
+	readonly oci_reference?: string;
 	readonly provisioner: ProvisionerType;
 	readonly tags: Record<string, string>;
 	readonly user_variable_values?: readonly VariableValue[];
 }
 
+// From codersdk/templateversions.go
+export interface CreateTemplateVersionTestRequest {
+	readonly rich_parameter_values?: readonly WorkspaceBuildParameter[];
+}
+
 // From codersdk/audit.go
 export interface CreateTestAuditLogRequest {
 	readonly action?: AuditAction;
@@ -435,6 +676,13 @@ export interface CreateTokenRequest {
 	readonly token_name: string;
 }
 
+// From codersdk/insights.go
+export interface CreateUserActivityExportRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly format: UserActivityExportFormat;
+}
+
 // From codersdk/users.go
 export interface CreateUserRequestWithOrgs {
 	readonly email: string;
@@ -453,9 +701,23 @@ export interface CreateWorkspaceBuildRequest {
 	readonly dry_run?: boolean;
 	readonly state?: string;
 	readonly orphan?: boolean;
+	readonly resume?: boolean;
 	readonly rich_parameter_values?: readonly WorkspaceBuildParameter[];
 	readonly log_level?: ProvisionerLogLevel;
 	readonly template_version_preset_id?: string;
+	readonly template_preset_id?: string;
+	readonly queue?: boolean;
+	readonly use_parameter_defaults?: boolean;
+}
+
+// From codersdk/workspacemigrations.go
+export interface CreateWorkspaceMigrationRequest {
+	readonly destination_url: string;
+	readonly session_token: string;
+	readonly organization_id: string;
+	readonly owner?: string;
+	readonly template_id?: string;
+	readonly name?: string;
 }
 
 // From codersdk/workspaceproxy.go
@@ -475,6 +737,7 @@ export interface CreateWorkspaceRequest {
 	readonly rich_parameter_values?: readonly WorkspaceBuildParameter[];
 	readonly automatic_updates?: AutomaticUpdates;
 	readonly template_version_preset_id?: string;
+	readonly template_preset_id?: string;
 }
 
 // From codersdk/deployment.go
@@ -592,6 +855,13 @@ export interface DERPServerConfig {
 	readonly relay_url: string;
 }
 
+// From codersdk/insights.go
+export interface DailyCost {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly cost: number;
+}
+
 // From codersdk/deployment.go
 export interface DangerousConfig {
 	readonly allow_path_app_sharing: boolean;
@@ -611,6 +881,12 @@ export interface DatabaseReport extends BaseReport {
 	readonly threshold_ms: number;
 }
 
+// From codersdk/templatebuildstats.go
+export const DefaultTemplateBuildStatsDays = 30;
+
+// From codersdk/workspaceresourceusage.go
+export const DefaultWorkspaceRecommendationsLookbackDays = 7;
+
 // From codersdk/notifications.go
 export interface DeleteWebpushSubscription {
 	readonly endpoint: string;
@@ -657,6 +933,7 @@ export interface DeploymentValues {
 	readonly ephemeral_deployment?: boolean;
 	readonly pg_connection_url?: string;
 	readonly pg_auth?: string;
+	readonly pubsub_redis_url?: string;
 	readonly oauth2?: OAuth2Config;
 	readonly oidc?: OIDCConfig;
 	readonly telemetry?: TelemetryConfig;
@@ -680,6 +957,7 @@ export interface DeploymentValues {
 	readonly logging?: LoggingConfig;
 	readonly dangerous?: DangerousConfig;
 	readonly disable_path_apps?: boolean;
+	readonly path_apps_only?: boolean;
 	readonly session_lifetime?: SessionLifetime;
 	readonly disable_password_auth?: boolean;
 	readonly support?: SupportConfig;
@@ -692,6 +970,7 @@ export interface DeploymentValues {
 	readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig;
 	readonly web_terminal_renderer?: string;
 	readonly allow_workspace_renames?: boolean;
+	readonly deleted_workspace_retention?: number;
 	readonly healthcheck?: HealthcheckConfig;
 	readonly cli_upgrade_message?: string;
 	readonly terms_of_service_url?: string;
@@ -700,11 +979,20 @@ export interface DeploymentValues {
 	readonly workspace_hostname_suffix?: string;
 	readonly workspace_prebuilds?: PrebuildsConfig;
 	readonly hide_ai_tasks?: boolean;
+	readonly session_recording?: SessionRecordingConfig;
+	readonly audit_log_export?: AuditLogExportConfig;
+	readonly retention?: RetentionConfig;
 	readonly config?: string;
 	readonly write_config?: boolean;
 	readonly address?: string;
 }
 
+// From codersdk/client.go
+export const DeviceIDCookie = "coder_device_id";
+
+// From codersdk/client.go
+export const DeviceIDHeader = "Coder-Device-ID";
+
 // From codersdk/parameters.go
 export interface DiagnosticExtra {
 	readonly code: string;
@@ -1006,6 +1294,7 @@ export interface Group {
 	readonly total_member_count: number;
 	readonly avatar_url: string;
 	readonly quota_allowance: number;
+	readonly monthly_budget: number;
 	readonly source: GroupSource;
 	readonly organization_name: string;
 	readonly organization_display_name: string;
@@ -1027,6 +1316,8 @@ export const GroupSources: GroupSource[] = ["oidc", "user"];
 export interface GroupSyncSettings {
 	readonly field: string;
 	readonly mapping: Record<string, string[]>;
+	readonly mapping_rules?: readonly IDPSyncMappingRule[];
+	readonly nested_group_separator?: string;
 	readonly regex_filter: string | null;
 	readonly auto_create_missing_groups: boolean;
 	readonly legacy_group_name_mapping?: Record<string, string>;
@@ -1051,6 +1342,9 @@ export type HealthCode =
 	| "EPD03"
 	| "EPD02"
 	| "EPD01"
+	| "EPJ02"
+	| "EPJ01"
+	| "EPJ03"
 	| "EWP02"
 	| "EWP04"
 	| "EWP01"
@@ -1080,6 +1374,9 @@ export const HealthCodes: HealthCode[] = [
 	"EPD03",
 	"EPD02",
 	"EPD01",
+	"EPJ02",
+	"EPJ01",
+	"EPJ03",
 	"EWP02",
 	"EWP04",
 	"EWP01",
@@ -1101,6 +1398,7 @@ export type HealthSection =
 	| "DERP"
 	| "Database"
 	| "ProvisionerDaemons"
+	| "ProvisionerJobs"
 	| "Websocket"
 	| "WorkspaceProxy";
 
@@ -1109,6 +1407,7 @@ export const HealthSections: HealthSection[] = [
 	"DERP",
 	"Database",
 	"ProvisionerDaemons",
+	"ProvisionerJobs",
 	"Websocket",
 	"WorkspaceProxy",
 ];
@@ -1134,6 +1433,9 @@ export interface Healthcheck {
 export interface HealthcheckConfig {
 	readonly refresh: number;
 	readonly threshold_database: number;
+	readonly threshold_provisioner_queue_depth: number;
+	readonly threshold_provisioner_pending_job_age: number;
+	readonly threshold_job_reaper_staleness: number;
 }
 
 // From healthsdk/healthsdk.go
@@ -1147,15 +1449,53 @@ export interface HealthcheckReport {
 	readonly database: DatabaseReport;
 	readonly workspace_proxy: WorkspaceProxyReport;
 	readonly provisioner_daemons: ProvisionerDaemonsReport;
+	readonly provisioner_jobs: ProvisionerJobsReport;
 	readonly coder_version: string;
 }
 
+// From codersdk/idpsync.go
+export interface IDPSyncDryRunMembership {
+	readonly id?: string;
+	readonly name: string;
+}
+
+// From codersdk/idpsync.go
+export interface IDPSyncDryRunResponse {
+	readonly users: readonly IDPSyncDryRunUser[];
+}
+
+// From codersdk/idpsync.go
+export interface IDPSyncDryRunUser {
+	readonly user_id: string;
+	readonly username: string;
+	readonly add: readonly IDPSyncDryRunMembership[];
+	readonly remove: readonly IDPSyncDryRunMembership[];
+}
+
 // From codersdk/idpsync.go
 export interface IDPSyncMapping<ResourceIdType extends string | string> {
 	readonly Given: string;
 	readonly Gets: ResourceIdType;
 }
 
+// From codersdk/idpsync.go
+export interface IDPSyncMappingRule {
+	readonly prefix?: string;
+	readonly regex?: string;
+	readonly gets: readonly string[];
+}
+
+// From codersdk/client.go
+export const IdempotencyKeyHeader = "Idempotency-Key";
+
+// From codersdk/workspacebundles.go
+export interface ImportWorkspaceRequest {
+	readonly bundle: WorkspaceBundle;
+	readonly name?: string;
+	readonly template_id?: string;
+	readonly state?: string;
+}
+
 // From codersdk/inboxnotification.go
 export interface InboxNotification {
 	readonly id: string;
@@ -1208,9 +1548,34 @@ export interface IssueReconnectingPTYSignedTokenResponse {
 }
 
 // From codersdk/provisionerdaemons.go
-export type JobErrorCode = "REQUIRED_TEMPLATE_VARIABLES";
+export type JobErrorCode = "JOB_REAPED" | "REQUIRED_TEMPLATE_VARIABLES";
+
+export const JobErrorCodes: JobErrorCode[] = [
+	"JOB_REAPED",
+	"REQUIRED_TEMPLATE_VARIABLES",
+];
+
+// From healthsdk/healthsdk.go
+export interface JobReaperJob {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly type: ProvisionerJobType;
+	readonly status: ProvisionerJobStatus;
+	readonly tags: Record<string, string>;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly started_at?: string;
+	readonly canceled_at?: string;
+	readonly completed_at?: string;
+	readonly error?: string;
+}
 
-export const JobErrorCodes: JobErrorCode[] = ["REQUIRED_TEMPLATE_VARIABLES"];
+// From healthsdk/healthsdk.go
+export interface JobReaperReport {
+	readonly last_run_at?: string;
+	readonly reaped_jobs: readonly JobReaperJob[];
+	readonly hung_job_candidates: readonly JobReaperJob[];
+}
 
 // From codersdk/licenses.go
 export interface License {
@@ -1228,6 +1593,28 @@ export const LicenseExpiryClaim = "license_expires";
 export const LicenseTelemetryRequiredErrorText =
 	"License requires telemetry but telemetry is disabled";
 
+// From codersdk/licenses.go
+export interface LicenseUsageDataPoint {
+	readonly date: string;
+	readonly active_users: number;
+}
+
+// From codersdk/licenses.go
+export interface LicenseUsageForecast {
+	readonly active_users: number;
+	readonly user_limit?: number;
+	readonly trailing_days: number;
+	readonly daily_growth: number;
+	readonly days_until_limit?: number;
+	readonly projected_limit_date?: string;
+	readonly history: readonly LicenseUsageDataPoint[];
+}
+
+// From codersdk/licenses.go
+export interface LicenseUsageForecastRequest {
+	readonly trailing_days?: number;
+}
+
 // From codersdk/deployment.go
 export interface LinkConfig {
 	readonly name: string;
@@ -1255,6 +1642,11 @@ export interface ListUserExternalAuthResponse {
 	readonly links: readonly ExternalAuthLink[];
 }
 
+// From codersdk/workspacelocks.go
+export interface LockWorkspaceRequest {
+	readonly reason: string;
+}
+
 // From codersdk/provisionerdaemons.go
 export type LogLevel = "debug" | "error" | "info" | "trace" | "warn";
 
@@ -1279,6 +1671,27 @@ export interface LoggingConfig {
 	readonly stackdriver: string;
 }
 
+// From codersdk/users.go
+export const LoginDeviceAuthorizationPending = "authorization_pending";
+
+// From codersdk/users.go
+export interface LoginDeviceCodeResponse {
+	readonly device_code: string;
+	readonly user_code: string;
+	readonly verification_uri: string;
+	readonly verification_uri_complete: string;
+	readonly expires_in: number;
+	readonly interval: number;
+}
+
+// From codersdk/users.go
+export const LoginDeviceExpiredToken = "expired_token";
+
+// From codersdk/users.go
+export interface LoginDeviceTokenRequest {
+	readonly device_code: string;
+}
+
 // From codersdk/apikey.go
 export type LoginType = "github" | "none" | "oidc" | "password" | "token" | "";
 
@@ -1309,6 +1722,9 @@ export interface MatchedProvisioners {
 	readonly most_recently_seen?: string;
 }
 
+// From codersdk/workspacelabels.go
+export const MaxWorkspaceLabels = 50;
+
 // From codersdk/organizations.go
 export interface MinimalOrganization {
 	readonly id: string;
@@ -1347,6 +1763,24 @@ export interface NetcheckReport {
 	readonly CaptivePortal: boolean | null;
 }
 
+// From codersdk/notifications.go
+export interface NotificationBuildDurationSettings {
+	readonly threshold_ms: number;
+}
+
+// From codersdk/notifications.go
+export type NotificationDigestFrequency = "daily" | "weekly";
+
+export const NotificationDigestFrequencys: NotificationDigestFrequency[] = [
+	"daily",
+	"weekly",
+];
+
+// From codersdk/notifications.go
+export interface NotificationDigestSettings {
+	readonly frequency: NotificationDigestFrequency;
+}
+
 // From codersdk/notifications.go
 export interface NotificationMethodsResponse {
 	readonly available: readonly string[];
@@ -1357,6 +1791,7 @@ export interface NotificationMethodsResponse {
 export interface NotificationPreference {
 	readonly id: string;
 	readonly disabled: boolean;
+	readonly digest: boolean;
 	readonly updated_at: string;
 }
 
@@ -1384,6 +1819,7 @@ export interface NotificationsConfig {
 	readonly fetch_interval: number;
 	readonly method: string;
 	readonly dispatch_timeout: number;
+	readonly license_user_limit_threshold_days: number;
 	readonly email: NotificationsEmailConfig;
 	readonly webhook: NotificationsWebhookConfig;
 	readonly inbox: NotificationsInboxConfig;
@@ -1580,6 +2016,7 @@ export interface Organization extends MinimalOrganization {
 	readonly created_at: string;
 	readonly updated_at: string;
 	readonly is_default: boolean;
+	readonly cost_center: string;
 }
 
 // From codersdk/organizations.go
@@ -1610,8 +2047,10 @@ export interface OrganizationProvisionerDaemonsOptions {
 // From codersdk/organizations.go
 export interface OrganizationProvisionerJobsOptions {
 	readonly Limit: number;
+	readonly AfterID: string;
 	readonly IDs: readonly string[];
 	readonly Status: readonly ProvisionerJobStatus[];
+	readonly Types: readonly ProvisionerJobType[];
 	readonly Tags: Record<string, string>;
 }
 
@@ -1619,6 +2058,8 @@ export interface OrganizationProvisionerJobsOptions {
 export interface OrganizationSyncSettings {
 	readonly field: string;
 	readonly mapping: Record<string, string[]>;
+	readonly mapping_rules?: readonly IDPSyncMappingRule[];
+	readonly nested_group_separator?: string;
 	readonly organization_assign_default: boolean;
 }
 
@@ -1690,6 +2131,7 @@ export interface PatchGroupRequest {
 	readonly display_name: string | null;
 	readonly avatar_url: string | null;
 	readonly quota_allowance: number | null;
+	readonly monthly_budget: number | null;
 }
 
 // From codersdk/idpsync.go
@@ -1770,6 +2212,18 @@ export interface PrebuildsConfig {
 	readonly reconciliation_backoff_interval: number;
 	readonly reconciliation_backoff_lookback: number;
 	readonly failure_hard_limit: number;
+	readonly demand_forecasting: boolean;
+	readonly demand_forecast_lookback: number;
+}
+
+// From codersdk/prebuildreservations.go
+export interface PrebuiltWorkspaceReservation {
+	readonly workspace_id: string;
+	readonly user_id?: string;
+	readonly group_id?: string;
+	readonly reserved_by: string;
+	readonly created_at: string;
+	readonly expires_at: string;
 }
 
 // From codersdk/presets.go
@@ -1843,6 +2297,11 @@ export interface PrometheusConfig {
 	readonly aggregate_agent_stats_by: string;
 }
 
+// From codersdk/templatereleasechannels.go
+export interface PromoteTemplateReleaseChannelRequest {
+	readonly template_version_id: string;
+}
+
 // From codersdk/deployment.go
 export interface ProvisionerConfig {
 	readonly daemons: number;
@@ -1851,6 +2310,11 @@ export interface ProvisionerConfig {
 	readonly daemon_poll_jitter: number;
 	readonly force_cancel_interval: number;
 	readonly daemon_psk: string;
+	readonly max_concurrent_jobs_per_user: number;
+	readonly job_log_retention: number;
+	readonly build_archive_age: number;
+	readonly cancel_deadline: number;
+	readonly drift_check_interval: number;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -1906,6 +2370,43 @@ export interface ProvisionerDaemonsReportItem {
 	readonly warnings: readonly HealthMessage[];
 }
 
+// From codersdk/insights.go
+export interface ProvisionerInsightsIntervalReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly interval: InsightsReportInterval;
+	readonly busy_seconds: number;
+	readonly idle_seconds: number;
+	readonly utilization: number;
+	readonly completed_jobs: number;
+	readonly jobs_per_hour: number;
+}
+
+// From codersdk/insights.go
+export interface ProvisionerInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly busy_seconds: number;
+	readonly idle_seconds: number;
+	readonly utilization: number;
+	readonly completed_jobs: number;
+	readonly jobs_per_hour: number;
+	readonly queue_waits: readonly ProvisionerQueueWait[];
+}
+
+// From codersdk/insights.go
+export interface ProvisionerInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly interval: InsightsReportInterval;
+}
+
+// From codersdk/insights.go
+export interface ProvisionerInsightsResponse {
+	readonly report: ProvisionerInsightsReport;
+	readonly interval_reports: readonly ProvisionerInsightsIntervalReport[];
+}
+
 // From codersdk/provisionerdaemons.go
 export interface ProvisionerJob {
 	readonly id: string;
@@ -1922,6 +2423,7 @@ export interface ProvisionerJob {
 	readonly tags: Record<string, string>;
 	readonly queue_position: number;
 	readonly queue_size: number;
+	readonly estimated_start_at?: string;
 	readonly organization_id: string;
 	readonly input: ProvisionerJobInput;
 	readonly type: ProvisionerJobType;
@@ -1944,6 +2446,21 @@ export interface ProvisionerJobLog {
 	readonly log_level: LogLevel;
 	readonly stage: string;
 	readonly output: string;
+	readonly fields?: ProvisionerJobLogFields;
+}
+
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerJobLogFields {
+	readonly resource_address?: string;
+	readonly action?: string;
+	readonly diagnostic_severity?: string;
+}
+
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerJobLogsFilter {
+	readonly level?: LogLevel;
+	readonly stage?: string;
+	readonly search?: string;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -1989,6 +2506,24 @@ export const ProvisionerJobTypes: ProvisionerJobType[] = [
 	"workspace_build",
 ];
 
+// From healthsdk/healthsdk.go
+export interface ProvisionerJobsQueueDepth {
+	readonly organization_id: string;
+	readonly tags: Record<string, string>;
+	readonly pending_jobs: number;
+	readonly oldest_pending_job_age_ms: number;
+}
+
+// From healthsdk/healthsdk.go
+export interface ProvisionerJobsReport extends BaseReport {
+	readonly queue_depths: readonly ProvisionerJobsQueueDepth[];
+	readonly oldest_pending_job_age_ms: number;
+	readonly job_reaper_last_run_at?: string;
+	readonly queue_depth_threshold: number;
+	readonly pending_job_age_threshold_ms: number;
+	readonly job_reaper_staleness_threshold_ms: number;
+}
+
 // From codersdk/provisionerdaemons.go
 export interface ProvisionerKey {
 	readonly id: string;
@@ -1996,6 +2531,10 @@ export interface ProvisionerKey {
 	readonly organization: string;
 	readonly name: string;
 	readonly tags: ProvisionerKeyTags;
+	readonly last_used_at?: string;
+	readonly expires_at?: string;
+	readonly rotation_overlap_ends_at?: string;
+	readonly connected_daemons: number;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -2030,9 +2569,30 @@ export type ProvisionerLogLevel = "debug";
 
 export const ProvisionerLogLevels: ProvisionerLogLevel[] = ["debug"];
 
-// From codersdk/organizations.go
-export type ProvisionerStorageMethod = "file";
-
+// From codersdk/insights.go
+export interface ProvisionerQueueWait {
+	readonly organization_id: string;
+	readonly tags: Record<string, string>;
+	readonly jobs: number;
+	readonly p50_seconds: number;
+	readonly p95_seconds: number;
+	readonly p99_seconds: number;
+}
+
+// From codersdk/provisionerreservations.go
+export interface ProvisionerReservation {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly tags: Record<string, string>;
+	readonly slots: number;
+	readonly starts_at: string;
+	readonly ends_at: string;
+	readonly created_at: string;
+}
+
+// From codersdk/organizations.go
+export type ProvisionerStorageMethod = "file";
+
 export const ProvisionerStorageMethods: ProvisionerStorageMethod[] = ["file"];
 
 // From codersdk/workspacebuilds.go
@@ -2071,6 +2631,35 @@ export const ProxyHealthStatuses: ProxyHealthStatus[] = [
 	"unregistered",
 ];
 
+// From codersdk/deployment.go
+export type PurgeDataClass =
+	| "agent_stats"
+	| "audit_logs"
+	| "job_logs"
+	| "workspace_agent_logs";
+
+// From codersdk/deployment.go
+export interface PurgeDataClassStats {
+	readonly data_class: PurgeDataClass;
+	readonly retention_ms: number;
+	readonly last_run_deleted: number;
+	readonly last_run_dropped_partitions: number;
+	readonly total_deleted: number;
+}
+
+export const PurgeDataClasses: PurgeDataClass[] = [
+	"agent_stats",
+	"audit_logs",
+	"job_logs",
+	"workspace_agent_logs",
+];
+
+// From codersdk/deployment.go
+export interface PurgeStats {
+	readonly last_run_at?: string;
+	readonly data_classes: readonly PurgeDataClassStats[];
+}
+
 // From codersdk/workspaces.go
 export interface PutExtendWorkspaceRequest {
 	readonly deadline: string;
@@ -2083,6 +2672,28 @@ export interface PutOAuth2ProviderAppRequest {
 	readonly icon: string;
 }
 
+// From codersdk/workspacebuildqueue.go
+export interface QueuedWorkspaceBuild {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly initiator_id: string;
+	readonly transition: WorkspaceTransition;
+	readonly template_version_id?: string;
+	readonly status: QueuedWorkspaceBuildStatus;
+	readonly position: number;
+	readonly error?: string;
+	readonly created_at: string;
+	readonly failed_at?: string;
+}
+
+// From codersdk/workspacebuildqueue.go
+export type QueuedWorkspaceBuildStatus = "failed" | "pending";
+
+export const QueuedWorkspaceBuildStatuses: QueuedWorkspaceBuildStatus[] = [
+	"failed",
+	"pending",
+];
+
 // From codersdk/rbacresources_gen.go
 export type RBACAction =
 	| "application_connect"
@@ -2149,6 +2760,7 @@ export type RBACResource =
 	| "provisioner_daemon"
 	| "provisioner_jobs"
 	| "replicas"
+	| "service_account"
 	| "system"
 	| "tailnet_coordinator"
 	| "template"
@@ -2188,6 +2800,7 @@ export const RBACResources: RBACResource[] = [
 	"provisioner_daemon",
 	"provisioner_jobs",
 	"replicas",
+	"service_account",
 	"system",
 	"tailnet_coordinator",
 	"template",
@@ -2207,6 +2820,11 @@ export interface RateLimitConfig {
 	readonly api: number;
 }
 
+// From codersdk/readonly.go
+export interface ReadOnlySettings {
+	readonly enabled: boolean;
+}
+
 // From codersdk/users.go
 export interface ReducedUser extends MinimalUser {
 	readonly name?: string;
@@ -2228,6 +2846,13 @@ export interface Region {
 	readonly healthy: boolean;
 	readonly path_app_url: string;
 	readonly wildcard_hostname: string;
+	readonly path_apps_only: boolean;
+}
+
+// From codersdk/workspaceproxy.go
+export interface RegionFailover {
+	readonly region: Region;
+	readonly failed_over: boolean;
 }
 
 // From codersdk/workspaceproxy.go
@@ -2238,6 +2863,11 @@ export interface RegionsResponse<R extends RegionTypes> {
 	readonly regions: readonly R[];
 }
 
+// From codersdk/templatereleasechannels.go
+export type ReleaseChannel = "beta" | "stable";
+
+export const ReleaseChannels: ReleaseChannel[] = ["beta", "stable"];
+
 // From codersdk/replicas.go
 export interface Replica {
 	readonly id: string;
@@ -2254,6 +2884,13 @@ export interface RequestOneTimePasscodeRequest {
 	readonly email: string;
 }
 
+// From codersdk/prebuildreservations.go
+export interface ReservePrebuiltWorkspaceRequest {
+	readonly user_id?: string;
+	readonly group_id?: string;
+	readonly expires_at: string;
+}
+
 // From codersdk/workspaces.go
 export interface ResolveAutostartResponse {
 	readonly parameter_mismatch: boolean;
@@ -2262,6 +2899,7 @@ export interface ResolveAutostartResponse {
 // From codersdk/audit.go
 export type ResourceType =
 	| "api_key"
+	| "access_request"
 	| "convert_login"
 	| "custom_role"
 	| "git_ssh_key"
@@ -2271,12 +2909,15 @@ export type ResourceType =
 	| "idp_sync_settings_organization"
 	| "idp_sync_settings_role"
 	| "license"
+	| "login_device_code"
 	| "notification_template"
 	| "notifications_settings"
 	| "oauth2_provider_app"
 	| "oauth2_provider_app_secret"
 	| "organization"
 	| "organization_member"
+	| "provisioner_build_pause"
+	| "read_only_settings"
 	| "template"
 	| "template_version"
 	| "user"
@@ -2288,6 +2929,7 @@ export type ResourceType =
 
 export const ResourceTypes: ResourceType[] = [
 	"api_key",
+	"access_request",
 	"convert_login",
 	"custom_role",
 	"git_ssh_key",
@@ -2297,12 +2939,15 @@ export const ResourceTypes: ResourceType[] = [
 	"idp_sync_settings_organization",
 	"idp_sync_settings_role",
 	"license",
+	"login_device_code",
 	"notification_template",
 	"notifications_settings",
 	"oauth2_provider_app",
 	"oauth2_provider_app_secret",
 	"organization",
 	"organization_member",
+	"provisioner_build_pause",
+	"read_only_settings",
 	"template",
 	"template_version",
 	"user",
@@ -2318,6 +2963,50 @@ export interface Response {
 	readonly message: string;
 	readonly detail?: string;
 	readonly validations?: readonly ValidationError[];
+	readonly code?: ResponseCode;
+}
+
+// From codersdk/client.go
+export type ResponseCode =
+	| "build_in_progress"
+	| "forbidden"
+	| "idempotency_key_reused"
+	| "job_canceled"
+	| "job_completed"
+	| "job_limit_reached"
+	| "job_reaped"
+	| "not_found"
+	| "preflight_failed"
+	| "quota_exceeded"
+	| "template_deprecated"
+	| "workspace_locked";
+
+export const ResponseCodes: ResponseCode[] = [
+	"build_in_progress",
+	"forbidden",
+	"idempotency_key_reused",
+	"job_canceled",
+	"job_completed",
+	"job_limit_reached",
+	"job_reaped",
+	"not_found",
+	"preflight_failed",
+	"quota_exceeded",
+	"template_deprecated",
+	"workspace_locked",
+];
+
+// From codersdk/deployment.go
+export interface RetentionConfig {
+	readonly agent_stats: number;
+	readonly workspace_agent_logs: number;
+	readonly job_logs: number;
+	readonly audit_logs: number;
+}
+
+// From codersdk/accessrequests.go
+export interface ReviewAccessRequest {
+	readonly status: AccessRequestStatus;
 }
 
 // From codersdk/roles.go
@@ -2370,6 +3059,12 @@ export const RoleTemplateAdmin = "template-admin";
 // From codersdk/rbacroles.go
 export const RoleUserAdmin = "user-admin";
 
+// From codersdk/provisionerdaemons.go
+export interface RotateProvisionerKeyRequest {
+	readonly overlap_ms: number;
+	readonly expires_at?: string;
+}
+
 // From codersdk/deployment.go
 export interface SSHConfig {
 	readonly DeploymentName: string;
@@ -2452,6 +3147,19 @@ export const ServerSentEventTypes: ServerSentEventType[] = [
 	"ping",
 ];
 
+// From codersdk/serviceaccounts.go
+export interface ServiceAccount {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly username: string;
+	readonly name: string;
+	readonly description: string;
+	readonly status: UserStatus;
+	readonly roles: readonly SlimRole[];
+	readonly created_by?: string;
+	readonly created_at: string;
+}
+
 // From codersdk/deployment.go
 export interface ServiceBannerConfig {
 	readonly enabled: boolean;
@@ -2474,8 +3182,24 @@ export interface SessionLifetime {
 	readonly default_token_lifetime?: number;
 	readonly max_token_lifetime?: number;
 	readonly max_admin_token_lifetime?: number;
+	readonly token_binding?: string;
 }
 
+// From codersdk/deployment.go
+export interface SessionRecordingConfig {
+	readonly enabled: boolean;
+	readonly retention: number;
+}
+
+// From codersdk/deployment.go
+export type SessionTokenBinding = "certificate" | "device" | "disabled";
+
+export const SessionTokenBindings: SessionTokenBinding[] = [
+	"certificate",
+	"device",
+	"disabled",
+];
+
 // From codersdk/client.go
 export const SessionTokenCookie = "coder_session_token";
 
@@ -2488,6 +3212,14 @@ export const SignedAppTokenCookie = "coder_signed_app_token";
 // From codersdk/client.go
 export const SignedAppTokenQueryParameter = "coder_signed_app_token_23db1dde";
 
+// From codersdk/lifecyclesimulation.go
+export interface SimulateWorkspaceLifecycleRequest {
+	readonly user_id?: string;
+	readonly autostart_schedule?: string;
+	readonly ttl_ms?: number;
+	readonly start_at?: string;
+}
+
 // From codersdk/roles.go
 export interface SlimRole {
 	readonly name: string;
@@ -2593,6 +3325,9 @@ export interface Template {
 	readonly require_active_version: boolean;
 	readonly max_port_share_level: WorkspaceAgentPortShareLevel;
 	readonly use_classic_parameter_flow: boolean;
+	readonly max_concurrent_jobs_per_user: number;
+	readonly resource_ceilings: TemplateResourceCeilings;
+	readonly required_provisioner_tags: Record<string, string>;
 }
 
 // From codersdk/templates.go
@@ -2628,6 +3363,28 @@ export interface TemplateAutostopRequirement {
 	readonly weeks: number;
 }
 
+// From codersdk/templatebuildstats.go
+export interface TemplateBuildStats {
+	readonly build_time_stats: TemplateBuildTimeStats;
+	readonly intervals: readonly TemplateBuildStatsInterval[];
+}
+
+// From codersdk/templatebuildstats.go
+export interface TemplateBuildStatsInterval {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly transition: WorkspaceTransition;
+	readonly build_count: number;
+	readonly p50_ms: number;
+	readonly p95_ms: number;
+	readonly max_ms: number;
+}
+
+// From codersdk/templatebuildstats.go
+export interface TemplateBuildStatsRequest {
+	readonly days?: number;
+}
+
 // From codersdk/templates.go
 export type TemplateBuildTimeStats = Record<
 	WorkspaceTransition,
@@ -2729,11 +3486,49 @@ export interface TemplateParameterValue {
 	readonly count: number;
 }
 
+// From codersdk/templatepresets.go
+export interface TemplatePreset {
+	readonly id: string;
+	readonly template_id: string;
+	readonly name: string;
+	readonly description: string;
+	readonly parameters: readonly WorkspaceBuildParameter[];
+	readonly default_group_ids: readonly string[];
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/templatereleasechannels.go
+export interface TemplateReleaseChannel {
+	readonly template_id: string;
+	readonly channel: ReleaseChannel;
+	readonly template_version_id: string;
+	readonly template_version_name: string;
+	readonly updated_by: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/templates.go
+export interface TemplateResourceCeilings {
+	readonly cpu?: number;
+	readonly memory_gib?: number;
+	readonly disk_gib?: number;
+	readonly daily_cost?: number;
+}
+
 // From codersdk/templates.go
 export type TemplateRole = "admin" | "" | "use";
 
 export const TemplateRoles: TemplateRole[] = ["admin", "", "use"];
 
+// From codersdk/templateshares.go
+export interface TemplateShare {
+	readonly template_id: string;
+	readonly organization_id: string;
+	readonly created_by: string;
+	readonly created_at: string;
+}
+
 // From codersdk/templates.go
 export interface TemplateUser extends User {
 	readonly role: TemplateRole;
@@ -2756,6 +3551,24 @@ export interface TemplateVersion {
 	readonly matched_provisioners?: MatchedProvisioners;
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionDiff {
+	readonly template_version_id: string;
+	readonly base_template_version_id: string;
+	readonly resources: readonly TemplateVersionResourceDiff[];
+	readonly parameters: readonly TemplateVersionParameterDiff[];
+	readonly files: readonly TemplateVersionFileDiff[];
+}
+
+// From codersdk/templateversions.go
+export type TemplateVersionDiffChange = "added" | "modified" | "removed";
+
+export const TemplateVersionDiffChanges: TemplateVersionDiffChange[] = [
+	"added",
+	"modified",
+	"removed",
+];
+
 // From codersdk/templateversions.go
 export interface TemplateVersionExternalAuth {
 	readonly id: string;
@@ -2767,6 +3580,14 @@ export interface TemplateVersionExternalAuth {
 	readonly optional?: boolean;
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionFileDiff {
+	readonly path: string;
+	readonly change: TemplateVersionDiffChange;
+	readonly additions?: number;
+	readonly deletions?: number;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionParameter {
 	readonly name: string;
@@ -2788,6 +3609,15 @@ export interface TemplateVersionParameter {
 	readonly ephemeral: boolean;
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionParameterDiff {
+	readonly name: string;
+	readonly change: TemplateVersionDiffChange;
+	readonly changed_fields?: readonly string[];
+	readonly previous?: TemplateVersionParameter;
+	readonly current?: TemplateVersionParameter;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionParameterOption {
 	readonly name: string;
@@ -2796,6 +3626,37 @@ export interface TemplateVersionParameterOption {
 	readonly icon: string;
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionResourceDiff {
+	readonly workspace_transition: WorkspaceTransition;
+	readonly module_path?: string;
+	readonly type: string;
+	readonly name: string;
+	readonly change: TemplateVersionDiffChange;
+	readonly changed_fields?: readonly string[];
+}
+
+// From codersdk/templateversionrollouts.go
+export interface TemplateVersionRollout {
+	readonly template_id: string;
+	readonly template_version_id: string;
+	readonly percent: number;
+	readonly group_id?: string;
+	readonly created_by: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly rollout_version_stats: TemplateVersionRolloutStats;
+	readonly active_version_stats: TemplateVersionRolloutStats;
+}
+
+// From codersdk/templateversionrollouts.go
+export interface TemplateVersionRolloutStats {
+	readonly template_version_id: string;
+	readonly total_builds: number;
+	readonly failed_builds: number;
+	readonly failure_rate: number;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionVariable {
 	readonly name: string;
@@ -2895,6 +3756,21 @@ export interface UpdateAppearanceConfig {
 	readonly announcement_banners: readonly BannerConfig[];
 }
 
+// From codersdk/buildalertrules.go
+export interface UpdateBuildAlertRuleRequest {
+	readonly template_id?: string;
+	readonly name: string;
+	readonly metric: BuildAlertRuleMetric;
+	readonly threshold: number;
+	readonly window_ms: number;
+}
+
+// From codersdk/buildpause.go
+export interface UpdateBuildPauseRequest {
+	readonly paused: boolean;
+	readonly reason?: string;
+}
+
 // From codersdk/updatecheck.go
 export interface UpdateCheckResponse {
 	readonly current: boolean;
@@ -2929,6 +3805,7 @@ export interface UpdateOrganizationRequest {
 	readonly display_name?: string;
 	readonly description?: string;
 	readonly icon?: string;
+	readonly cost_center?: string;
 }
 
 // From codersdk/users.go
@@ -2965,6 +3842,24 @@ export interface UpdateTemplateMeta {
 	readonly disable_everyone_group_access: boolean;
 	readonly max_port_share_level?: WorkspaceAgentPortShareLevel;
 	readonly use_classic_parameter_flow?: boolean;
+	readonly max_concurrent_jobs_per_user?: number;
+	readonly resource_ceilings?: TemplateResourceCeilings;
+	readonly required_provisioner_tags?: (Record<string, string>);
+}
+
+// From codersdk/templatepresets.go
+export interface UpdateTemplatePresetRequest {
+	readonly name: string;
+	readonly description?: string;
+	readonly parameters: readonly WorkspaceBuildParameter[];
+	readonly default_group_ids?: readonly string[];
+}
+
+// From codersdk/templateversionrollouts.go
+export interface UpdateTemplateVersionRolloutRequest {
+	readonly template_version_id: string;
+	readonly percent: number;
+	readonly group_id?: string;
 }
 
 // From codersdk/users.go
@@ -2976,6 +3871,7 @@ export interface UpdateUserAppearanceSettingsRequest {
 // From codersdk/notifications.go
 export interface UpdateUserNotificationPreferences {
 	readonly template_disabled_map: Record<string, boolean>;
+	readonly template_digest_map?: Record<string, boolean>;
 }
 
 // From codersdk/users.go
@@ -2995,6 +3891,12 @@ export interface UpdateUserQuietHoursScheduleRequest {
 	readonly schedule: string;
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceACL {
+	readonly user_roles?: Record<string, WorkspaceRole>;
+	readonly group_roles?: Record<string, WorkspaceRole>;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutomaticUpdatesRequest {
 	readonly automatic_updates: AutomaticUpdates;
@@ -3010,12 +3912,22 @@ export interface UpdateWorkspaceDormancy {
 	readonly dormant: boolean;
 }
 
+// From codersdk/workspacelabels.go
+export interface UpdateWorkspaceLabelsRequest {
+	readonly labels: Record<string, string>;
+}
+
 // From codersdk/workspaceproxy.go
 export interface UpdateWorkspaceProxyResponse {
 	readonly proxy: WorkspaceProxy;
 	readonly proxy_token: string;
 }
 
+// From codersdk/templatereleasechannels.go
+export interface UpdateWorkspaceReleaseChannelRequest {
+	readonly channel: ReleaseChannel;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceRequest {
 	readonly name?: string;
@@ -3037,6 +3949,9 @@ export interface UpsertWorkspaceAgentPortShareRequest {
 	readonly port: number;
 	readonly share_level: WorkspaceAgentPortShareLevel;
 	readonly protocol: WorkspaceAgentPortShareProtocol;
+	readonly shared_with_user_ids?: readonly string[];
+	readonly shared_with_group_ids?: readonly string[];
+	readonly expires_at?: string;
 }
 
 // From codersdk/workspaces.go
@@ -3064,6 +3979,31 @@ export interface UserActivity {
 	readonly seconds: number;
 }
 
+// From codersdk/insights.go
+export interface UserActivityExport {
+	readonly id: string;
+	readonly created_by: string;
+	readonly created_at: string;
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly format: UserActivityExportFormat;
+	readonly status: UserActivityExportStatus;
+	readonly file_id?: string;
+	readonly download_url?: string;
+	readonly error?: string;
+	readonly completed_at?: string;
+}
+
+// From codersdk/insights.go
+export type UserActivityExportFormat = "csv" | "parquet";
+
+export const UserActivityExportFormats: UserActivityExportFormat[] = ["csv", "parquet"];
+
+// From codersdk/insights.go
+export type UserActivityExportStatus = "completed" | "failed" | "pending";
+
+export const UserActivityExportStatuses: UserActivityExportStatus[] = ["completed", "failed", "pending"];
+
 // From codersdk/insights.go
 export interface UserActivityInsightsReport {
 	readonly start_time: string;
@@ -3199,6 +4139,32 @@ export interface VariableValue {
 	readonly value: string;
 }
 
+// From codersdk/watch.go
+export interface WatchEvent {
+	readonly type: WatchEventType;
+	readonly workspace_id: string;
+	readonly workspace?: Workspace;
+	readonly workspace_build?: WorkspaceBuild;
+	readonly workspace_agent?: WorkspaceAgent;
+	readonly error?: Response;
+}
+
+// From codersdk/watch.go
+export type WatchEventType = "error" | "workspace" | "workspace_agent" | "workspace_build";
+
+export const WatchEventTypes: WatchEventType[] = ["error", "workspace", "workspace_agent", "workspace_build"];
+
+// From codersdk/watch.go
+export interface WatchRequest {
+	readonly type: WatchRequestType;
+	readonly workspace_ids: readonly string[];
+}
+
+// From codersdk/watch.go
+export type WatchRequestType = "subscribe" | "unsubscribe";
+
+export const WatchRequestTypes: WatchRequestType[] = ["subscribe", "unsubscribe"];
+
 // From codersdk/notifications.go
 export interface WebpushMessage {
 	readonly icon: string;
@@ -3259,6 +4225,13 @@ export interface Workspace {
 	readonly allow_renames: boolean;
 	readonly favorite: boolean;
 	readonly next_start_at: string | null;
+	readonly drift_check?: WorkspaceDriftCheck;
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceACL {
+	readonly users: readonly WorkspaceUser[];
+	readonly groups: readonly WorkspaceGroup[];
 }
 
 // From codersdk/workspaceagents.go
@@ -3295,9 +4268,25 @@ export interface WorkspaceAgent {
 	readonly display_apps: readonly DisplayApp[];
 	readonly log_sources: readonly WorkspaceAgentLogSource[];
 	readonly scripts: readonly WorkspaceAgentScript[];
+	readonly display_group?: string;
+	readonly collapsed: boolean;
 	readonly startup_script_behavior: WorkspaceAgentStartupScriptBehavior;
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentConnectionQuality {
+	readonly ip: string;
+	readonly p2p: boolean;
+	readonly derp_region_code?: string;
+	readonly latency_ms?: number;
+	readonly pings_sent: number;
+	readonly pings_lost: number;
+	readonly packet_loss: number;
+	readonly last_handshake_at?: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentContainer {
 	readonly created_at: string;
@@ -3308,9 +4297,19 @@ export interface WorkspaceAgentContainer {
 	readonly running: boolean;
 	readonly ports: readonly WorkspaceAgentContainerPort[];
 	readonly status: string;
+	readonly health?: WorkspaceAgentContainerHealth;
 	readonly volumes: Record<string, string>;
 }
 
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentContainerHealth =
+	| "healthy"
+	| "starting"
+	| "unhealthy";
+
+export const WorkspaceAgentContainerHealths: WorkspaceAgentContainerHealth[] =
+	["healthy", "starting", "unhealthy"];
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentContainerPort {
 	readonly port: number;
@@ -3348,6 +4347,20 @@ export type WorkspaceAgentDevcontainerStatus =
 export const WorkspaceAgentDevcontainerStatuses: WorkspaceAgentDevcontainerStatus[] =
 	["error", "running", "starting", "stopped"];
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentExecRequest {
+	readonly command: string;
+	readonly timeout_ms?: number;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentExecResponse {
+	readonly exit_code: number;
+	readonly stdout: string;
+	readonly stderr: string;
+	readonly timed_out: boolean;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentHealth {
 	readonly healthy: boolean;
@@ -3445,6 +4458,9 @@ export interface WorkspaceAgentPortShare {
 	readonly port: number;
 	readonly share_level: WorkspaceAgentPortShareLevel;
 	readonly protocol: WorkspaceAgentPortShareProtocol;
+	readonly shared_with_user_ids: readonly string[];
+	readonly shared_with_group_ids: readonly string[];
+	readonly expires_at?: string;
 }
 
 // From codersdk/workspaceagentportshare.go
@@ -3452,13 +4468,15 @@ export type WorkspaceAgentPortShareLevel =
 	| "authenticated"
 	| "organization"
 	| "owner"
-	| "public";
+	| "public"
+	| "restricted";
 
 export const WorkspaceAgentPortShareLevels: WorkspaceAgentPortShareLevel[] = [
 	"authenticated",
 	"organization",
 	"owner",
 	"public",
+	"restricted",
 ];
 
 // From codersdk/workspaceagentportshare.go
@@ -3472,6 +4490,26 @@ export interface WorkspaceAgentPortShares {
 	readonly shares: readonly WorkspaceAgentPortShare[];
 }
 
+// From codersdk/workspaceresourceusage.go
+export interface WorkspaceAgentResourceUsage {
+	readonly agent_name: string;
+	readonly datapoints: readonly WorkspaceAgentResourceUsageDatapoint[];
+}
+
+// From codersdk/workspaceresourceusage.go
+export interface WorkspaceAgentResourceUsageDatapoint {
+	readonly start_time: string;
+	readonly samples: number;
+	readonly cpu_used_avg: number;
+	readonly cpu_used_max: number;
+	readonly cpu_total: number;
+	readonly memory_used_avg: number;
+	readonly memory_used_max: number;
+	readonly memory_total: number;
+	readonly disk_used: number;
+	readonly disk_total: number;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentScript {
 	readonly id: string;
@@ -3481,6 +4519,7 @@ export interface WorkspaceAgentScript {
 	readonly cron: string;
 	readonly run_on_start: boolean;
 	readonly run_on_stop: boolean;
+	readonly run_on_claim: boolean;
 	readonly start_blocks_login: boolean;
 	readonly timeout: number;
 	readonly display_name: string;
@@ -3605,6 +4644,7 @@ export interface WorkspaceBuild {
 	readonly initiator_name: string;
 	readonly job: ProvisionerJob;
 	readonly reason: BuildReason;
+	readonly initiator_context: WorkspaceBuildInitiatorContext;
 	readonly resources: readonly WorkspaceResource[];
 	readonly deadline?: string;
 	readonly max_deadline?: string;
@@ -3614,6 +4654,25 @@ export interface WorkspaceBuild {
 	readonly template_version_preset_id: string | null;
 	readonly has_ai_task?: boolean;
 	readonly ai_task_sidebar_app_id?: string;
+	readonly exceeds_template_p95?: boolean;
+	readonly warnings?: readonly WorkspaceBuildWarning[];
+	readonly trace_id?: string;
+}
+
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildArchive {
+	readonly workspace_build_id: string;
+	readonly file_id: string;
+	readonly state_size: number;
+	readonly resource_metadata_count: number;
+	readonly archived_at: string;
+}
+
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildInitiatorContext {
+	readonly api_key_name?: string;
+	readonly automation?: BuildAutomation;
+	readonly schedule?: string;
 }
 
 // From codersdk/workspacebuilds.go
@@ -3629,11 +4688,65 @@ export interface WorkspaceBuildTimings {
 	readonly agent_connection_timings: readonly AgentConnectionTiming[];
 }
 
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildWarning {
+	readonly code: WorkspaceBuildWarningCode;
+	readonly message: string;
+	readonly versions_behind?: readonly string[];
+}
+
+// From codersdk/workspacebuilds.go
+export type WorkspaceBuildWarningCode =
+	| "deprecated_template"
+	| "inactive_template_version";
+
+export const WorkspaceBuildWarningCodes: WorkspaceBuildWarningCode[] = [
+	"deprecated_template",
+	"inactive_template_version",
+];
+
 // From codersdk/workspaces.go
 export interface WorkspaceBuildsRequest extends Pagination {
 	readonly since?: string;
 }
 
+// From codersdk/workspacebundles.go
+export interface WorkspaceBundle {
+	readonly version: number;
+	readonly exported_at: string;
+	readonly name: string;
+	readonly organization_name: string;
+	readonly template_name: string;
+	readonly template_version_id: string;
+	readonly template_version_name: string;
+	readonly rich_parameter_values: readonly WorkspaceBuildParameter[];
+	readonly autostart_schedule?: string;
+	readonly ttl_ms?: number;
+	readonly automatic_updates: AutomaticUpdates;
+	readonly resources: readonly WorkspaceBundleResource[];
+}
+
+// From codersdk/workspacebundles.go
+export interface WorkspaceBundleAgent {
+	readonly name: string;
+	readonly operating_system: string;
+	readonly architecture: string;
+	readonly directory?: string;
+	readonly display_apps: readonly DisplayApp[];
+	readonly apps?: readonly string[];
+}
+
+// From codersdk/workspacebundles.go
+export interface WorkspaceBundleResource {
+	readonly type: string;
+	readonly name: string;
+	readonly metadata?: readonly WorkspaceResourceMetadata[];
+	readonly agents?: readonly WorkspaceBundleAgent[];
+}
+
+// From codersdk/workspacebundles.go
+export const WorkspaceBundleVersion = 1;
+
 // From codersdk/deployment.go
 export interface WorkspaceConnectionLatencyMS {
 	readonly P50: number;
@@ -3652,15 +4765,153 @@ export interface WorkspaceDeploymentStats {
 	readonly tx_bytes: number;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceDriftCheck {
+	readonly workspace_id: string;
+	readonly workspace_build_id: string;
+	readonly status: WorkspaceDriftCheckStatus;
+	readonly created_at: string;
+	readonly completed_at?: string;
+	readonly drifted_resources: readonly string[];
+	readonly orphaned_resources: readonly string[];
+}
+
+// From codersdk/workspaces.go
+export type WorkspaceDriftCheckStatus =
+	| "clean"
+	| "drifted"
+	| "failed"
+	| "pending";
+
+export const WorkspaceDriftCheckStatuses: WorkspaceDriftCheckStatus[] = [
+	"clean",
+	"drifted",
+	"failed",
+	"pending",
+];
+
 // From codersdk/workspaces.go
 export interface WorkspaceFilter {
 	readonly q?: string;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceGroup {
+	readonly id: string;
+	readonly name: string;
+	readonly display_name: string;
+	readonly avatar_url: string;
+	readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceHealth {
 	readonly healthy: boolean;
 	readonly failing_agents: readonly string[];
+	readonly warnings: readonly WorkspaceHealthWarning[];
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceHealthWarning {
+	readonly agent_id: string;
+	readonly agent_name: string;
+	readonly kind: WorkspaceHealthWarningKind;
+	readonly volume?: string;
+	readonly used: number;
+	readonly total: number;
+	readonly created_at: string;
+}
+
+// From codersdk/workspaces.go
+export type WorkspaceHealthWarningKind =
+	| "low_disk_space"
+	| "near_out_of_memory";
+
+export const WorkspaceHealthWarningKinds: WorkspaceHealthWarningKind[] =
+	["low_disk_space", "near_out_of_memory"];
+
+// From codersdk/workspacelabels.go
+export interface WorkspaceLabels {
+	readonly labels: Record<string, string>;
+}
+
+// From codersdk/lifecyclesimulation.go
+export interface WorkspaceLifecycleSimulation {
+	readonly start_at: string;
+	readonly next_autostart?: string;
+	readonly deadline?: string;
+	readonly max_deadline?: string;
+	readonly activity_bump_ms: number;
+	readonly dormant_at?: string;
+	readonly deleting_at?: string;
+	readonly quota?: WorkspaceLifecycleSimulationQuota;
+	readonly maintenance_windows: readonly string[];
+}
+
+// From codersdk/lifecyclesimulation.go
+export interface WorkspaceLifecycleSimulationQuota {
+	readonly daily_cost: number;
+	readonly credits_consumed: number;
+	readonly budget: number;
+	readonly allowed: boolean;
+}
+
+// From codersdk/workspacelocks.go
+export interface WorkspaceLock {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly reason: string;
+	readonly locked_by: string;
+	readonly locked_at: string;
+	readonly unlocked_by?: string;
+	readonly unlocked_at?: string;
+}
+
+// From codersdk/workspacemigrations.go
+export interface WorkspaceMigration {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly initiator_id: string;
+	readonly status: WorkspaceMigrationStatus;
+	readonly destination_url: string;
+	readonly destination_organization_id: string;
+	readonly destination_workspace_id?: string;
+	readonly error?: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly completed_at?: string;
+}
+
+// From codersdk/workspacemigrations.go
+export type WorkspaceMigrationStatus =
+	| "failed"
+	| "pending"
+	| "starting"
+	| "stopping"
+	| "succeeded";
+
+export const WorkspaceMigrationStatuses: WorkspaceMigrationStatus[] = [
+	"failed",
+	"pending",
+	"starting",
+	"stopping",
+	"succeeded",
+];
+
+// From codersdk/workspacenamingpolicies.go
+export type WorkspaceNameUniquenessScope = "organization" | "owner";
+
+export const WorkspaceNameUniquenessScopes: WorkspaceNameUniquenessScope[] = [
+	"organization",
+	"owner",
+];
+
+// From codersdk/workspacenamingpolicies.go
+export interface WorkspaceNamingPolicy {
+	readonly regex: string;
+	readonly prefix: string;
+	readonly suffix: string;
+	readonly uniqueness_scope: WorkspaceNameUniquenessScope;
 }
 
 // From codersdk/workspaces.go
@@ -3702,6 +4953,58 @@ export interface WorkspaceProxyStatus {
 export interface WorkspaceQuota {
 	readonly credits_consumed: number;
 	readonly budget: number;
+	readonly monthly_budget: number;
+	readonly monthly_credits_spent: number;
+	readonly monthly_credits_remaining: number;
+	readonly monthly_budget_resets_at?: string;
+}
+
+// From codersdk/workspaceresourceusage.go
+export interface WorkspaceRecommendation {
+	readonly agent_name: string;
+	readonly resource: WorkspaceRecommendationResource;
+	readonly direction: WorkspaceRecommendationDirection;
+	readonly current_total: number;
+	readonly suggested_total: number;
+	readonly used_average: number;
+	readonly used_peak: number;
+	readonly parameter_changes: readonly WorkspaceRecommendationParameterChange[];
+}
+
+// From codersdk/workspaceresourceusage.go
+export type WorkspaceRecommendationDirection = "decrease" | "increase";
+
+export const WorkspaceRecommendationDirections: WorkspaceRecommendationDirection[] =
+	["decrease", "increase"];
+
+// From codersdk/workspaceresourceusage.go
+export interface WorkspaceRecommendationParameterChange {
+	readonly name: string;
+	readonly current_value: string;
+	readonly suggested_value: string;
+}
+
+// From codersdk/workspaceresourceusage.go
+export type WorkspaceRecommendationResource = "cpu" | "memory";
+
+export const WorkspaceRecommendationResources: WorkspaceRecommendationResource[] =
+	["cpu", "memory"];
+
+// From codersdk/workspaceresourceusage.go
+export interface WorkspaceRecommendations {
+	readonly lookback_days: number;
+	readonly recommendations: readonly WorkspaceRecommendation[];
+}
+
+// From codersdk/workspaceresourceusage.go
+export interface WorkspaceRecommendationsRequest {
+	readonly lookback_days?: number;
+}
+
+// From codersdk/templatereleasechannels.go
+export interface WorkspaceReleaseChannel {
+	readonly channel?: ReleaseChannel;
+	readonly template_version_id?: string;
 }
 
 // From codersdk/workspacebuilds.go
@@ -3717,6 +5020,9 @@ export interface WorkspaceResource {
 	readonly agents?: readonly WorkspaceAgent[];
 	readonly metadata?: readonly WorkspaceResourceMetadata[];
 	readonly daily_cost: number;
+	readonly display_group?: string;
+	readonly display_order: number;
+	readonly collapsed: boolean;
 }
 
 // From codersdk/workspacebuilds.go
@@ -3726,6 +5032,42 @@ export interface WorkspaceResourceMetadata {
 	readonly sensitive: boolean;
 }
 
+// From codersdk/workspaceresourceusage.go
+export interface WorkspaceResourceUsage {
+	readonly interval_seconds: number;
+	readonly agents: readonly WorkspaceAgentResourceUsage[];
+}
+
+// From codersdk/workspaceresourceusage.go
+export interface WorkspaceResourceUsageRequest {
+	readonly start_time?: string;
+	readonly end_time?: string;
+}
+
+// From codersdk/workspaces.go
+export type WorkspaceRole = "connect" | "" | "view";
+
+export const WorkspaceRoles: WorkspaceRole[] = ["connect", "", "view"];
+
+// From codersdk/workspacesessionrecordings.go
+export interface WorkspaceSessionRecording {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly agent_id: string;
+	readonly type: WorkspaceSessionRecordingType;
+	readonly width: number;
+	readonly height: number;
+	readonly started_at: string;
+	readonly ended_at?: string;
+	readonly size_bytes: number;
+}
+
+// From codersdk/workspacesessionrecordings.go
+export type WorkspaceSessionRecordingType = "reconnecting_pty" | "ssh";
+
+export const WorkspaceSessionRecordingTypes: WorkspaceSessionRecordingType[] =
+	["reconnecting_pty", "ssh"];
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
 	| "canceled"
@@ -3761,6 +5103,11 @@ export const WorkspaceTransitions: WorkspaceTransition[] = [
 	"stop",
 ];
 
+// From codersdk/workspaces.go
+export interface WorkspaceUser extends MinimalUser {
+	readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
 	readonly q?: string;
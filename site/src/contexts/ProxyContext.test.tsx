@@ -91,6 +91,19 @@ describe("ProxyContextGetURLs", () => {
 			"",
 			MockPrimaryWorkspaceProxy.wildcard_hostname,
 		],
+		// The selected proxy fails over to the closest healthy proxy
+		[
+			"unhealthy selection failover",
+			MockWorkspaceProxies,
+			{
+				[MockPrimaryWorkspaceProxy.id]: fakeLatency(100),
+				[MockHealthyWildWorkspaceProxy.id]: fakeLatency(50),
+				[MockUnhealthyWildWorkspaceProxy.id]: fakeLatency(25),
+			},
+			MockUnhealthyWildWorkspaceProxy,
+			MockHealthyWildWorkspaceProxy.path_app_url,
+			MockHealthyWildWorkspaceProxy.wildcard_hostname,
+		],
 		// This should never happen, when there is no primary
 		["no primary", [MockHealthyWildWorkspaceProxy], {}, undefined, "", ""],
 		// Latency behavior
@@ -81,6 +81,9 @@ interface PreferredProxy {
 	preferredPathAppURL: string;
 	// PreferredWildcardHostname is a hostname that includes a wildcard.
 	preferredWildcardHostname: string;
+	// PreferredPathAppsOnly is true if subdomain apps and ports are served on
+	// paths because the deployment has no wildcard hostname.
+	preferredPathAppsOnly: boolean;
 }
 
 export const ProxyContext = createContext<ProxyContextValue | undefined>(
@@ -120,6 +123,9 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
 				const resp = await apiCall();
 				return resp.regions;
 			},
+			// Proxy health is checked every minute, so refetch the proxies to
+			// fail over from proxies that become unhealthy while in use.
+			refetchInterval: 60_000,
 		}),
 	);
 
@@ -250,12 +256,16 @@ export const getPreferredProxy = (
 
 	// If no proxy is selected, or the selected proxy is unhealthy default to the primary proxy.
 	if (!selectedProxy || !selectedProxy.healthy) {
+		// A selected proxy that is unhealthy always fails over to the next
+		// closest healthy proxy, which matches the failover region chosen by
+		// the /regions/{region}/failover endpoint.
+		const failover = selectedProxy !== undefined;
 		// Default to the primary proxy
 		selectedProxy = proxies.find((proxy) => proxy.name === "primary");
 
 		// If we have latencies, then attempt to use the best proxy by latency instead.
 		const best = selectByLatency(proxies, latencies);
-		if (autoSelectBasedOnLatency && best !== undefined) {
+		if ((autoSelectBasedOnLatency || failover) && best !== undefined) {
 			selectedProxy = best;
 		}
 	}
@@ -297,6 +307,7 @@ const computeUsableURLS = (proxy?: Region): PreferredProxy => {
 			proxy: undefined,
 			preferredPathAppURL: "",
 			preferredWildcardHostname: "",
+			preferredPathAppsOnly: false,
 		};
 	}
 
@@ -311,6 +322,7 @@ const computeUsableURLS = (proxy?: Region): PreferredProxy => {
 		// Trim trailing slashes to be consistent
 		preferredPathAppURL: pathAppURL,
 		preferredWildcardHostname: proxy.wildcard_hostname,
+		preferredPathAppsOnly: proxy.path_apps_only,
 	};
 };
 
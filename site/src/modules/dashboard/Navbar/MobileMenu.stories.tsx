@@ -27,6 +27,7 @@ const meta: Meta<typeof MobileMenu> = {
 			proxy: {
 				preferredPathAppURL: "",
 				preferredWildcardHostname: "",
+				preferredPathAppsOnly: false,
 				proxy: MockPrimaryWorkspaceProxy,
 			},
 			isLoading: false,
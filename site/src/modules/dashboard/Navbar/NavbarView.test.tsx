@@ -10,6 +10,7 @@ const proxyContextValue: ProxyContextValue = {
 	proxy: {
 		preferredPathAppURL: "",
 		preferredWildcardHostname: "",
+		preferredPathAppsOnly: false,
 		proxy: MockPrimaryWorkspaceProxy,
 	},
 	isLoading: false,
@@ -67,6 +67,7 @@ export const OverflowDropdown: Story = {
 				created_at: "",
 				updated_at: "",
 				is_default: false,
+				cost_center: "",
 			},
 			{
 				id: "my-organization-4-id",
@@ -77,6 +78,7 @@ export const OverflowDropdown: Story = {
 				created_at: "",
 				updated_at: "",
 				is_default: false,
+				cost_center: "",
 			},
 			{
 				id: "my-organization-5-id",
@@ -87,6 +89,7 @@ export const OverflowDropdown: Story = {
 				created_at: "",
 				updated_at: "",
 				is_default: false,
+				cost_center: "",
 			},
 			{
 				id: "my-organization-6-id",
@@ -97,6 +100,7 @@ export const OverflowDropdown: Story = {
 				created_at: "",
 				updated_at: "",
 				is_default: false,
+				cost_center: "",
 			},
 			{
 				id: "my-organization-7-id",
@@ -107,6 +111,7 @@ export const OverflowDropdown: Story = {
 				created_at: "",
 				updated_at: "",
 				is_default: false,
+				cost_center: "",
 			},
 		],
 	},
@@ -60,6 +60,30 @@ export const WithPorts: Story = {
 	},
 };
 
+export const Healthy: Story = {
+	args: {
+		devcontainer: {
+			...MockWorkspaceAgentDevcontainer,
+			container: {
+				...MockWorkspaceAgentContainer,
+				health: "healthy",
+			},
+		},
+	},
+};
+
+export const Unhealthy: Story = {
+	args: {
+		devcontainer: {
+			...MockWorkspaceAgentDevcontainer,
+			container: {
+				...MockWorkspaceAgentContainer,
+				health: "unhealthy",
+			},
+		},
+	},
+};
+
 export const Dirty: Story = {
 	args: {
 		devcontainer: {
@@ -3,6 +3,7 @@ import type {
 	Template,
 	Workspace,
 	WorkspaceAgent,
+	WorkspaceAgentContainerHealth,
 	WorkspaceAgentDevcontainer,
 	WorkspaceAgentListContainersResponse,
 } from "api/typesGenerated";
@@ -23,6 +24,7 @@ import { AppStatuses } from "pages/WorkspacePage/AppStatuses";
 import type { FC } from "react";
 import { useEffect } from "react";
 import { useMutation, useQueryClient } from "react-query";
+import { cn } from "utils/cn";
 import { portForwardURL } from "utils/portForward";
 import { AgentApps, organizeAgentApps } from "./AgentApps/AgentApps";
 import { AgentButton } from "./AgentButton";
@@ -223,6 +225,9 @@ export const AgentDevcontainerCard: FC<AgentDevcontainerCardProps> = ({
 								</span>
 							)}
 						</span>
+						{devcontainer.container?.health && (
+							<ContainerHealth health={devcontainer.container.health} />
+						)}
 					</div>
 					{subAgent?.status === "connected" && (
 						<>
@@ -262,7 +267,8 @@ export const AgentDevcontainerCard: FC<AgentDevcontainerCardProps> = ({
 					)}
 					{showDevcontainerControls &&
 						displayApps.includes("port_forwarding_helper") &&
-						proxy.preferredWildcardHostname !== "" && (
+						(proxy.preferredWildcardHostname !== "" ||
+							proxy.preferredPathAppsOnly) && (
 							<PortForwardButton
 								host={proxy.preferredWildcardHostname}
 								workspace={workspace}
@@ -372,3 +378,24 @@ export const AgentDevcontainerCard: FC<AgentDevcontainerCardProps> = ({
 		</Stack>
 	);
 };
+
+const containerHealthClasses: Record<WorkspaceAgentContainerHealth, string> = {
+	healthy: "text-content-success",
+	unhealthy: "text-content-destructive",
+	starting: "text-content-secondary",
+};
+
+type ContainerHealthProps = {
+	health: WorkspaceAgentContainerHealth;
+};
+
+const ContainerHealth: FC<ContainerHealthProps> = ({ health }) => {
+	return (
+		<span
+			className={cn("shrink-0 text-xs", containerHealthClasses[health])}
+			title="Result of the container's health check"
+		>
+			{health}
+		</span>
+	);
+};
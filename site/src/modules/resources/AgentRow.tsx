@@ -206,7 +206,8 @@ export const AgentRow: FC<AgentRowProps> = ({
 							workspaceOwnerUsername={workspace.owner_name}
 						/>
 					)}
-					{proxy.preferredWildcardHostname !== "" &&
+					{(proxy.preferredWildcardHostname !== "" ||
+						proxy.preferredPathAppsOnly) &&
 						agent.display_apps.includes("port_forwarding_helper") && (
 							<PortForwardButton
 								host={proxy.preferredWildcardHostname}
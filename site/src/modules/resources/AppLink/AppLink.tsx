@@ -45,7 +45,8 @@ export const AppLink: FC<AppLinkProps> = ({
 	const link = useAppLink(app, { agent, workspace });
 
 	// canClick is ONLY false when it's a subdomain app and the admin hasn't
-	// enabled wildcard access URL or the session token is being fetched.
+	// enabled wildcard access URL or path apps only mode, or the session token
+	// is being fetched.
 	//
 	// To avoid bugs in the healthcheck code locking users out of apps, we no
 	// longer block access to apps if they are unhealthy/initializing.
@@ -71,7 +72,7 @@ export const AppLink: FC<AppLinkProps> = ({
 		primaryTooltip = "Unhealthy";
 	}
 
-	if (!host && app.subdomain) {
+	if (!host && app.subdomain && !proxy.preferredPathAppsOnly) {
 		canClick = false;
 		icon = (
 			<CircleAlertIcon
@@ -201,9 +201,12 @@ export const PortForwardPopoverView: FC<PortForwardPopoverViewProps> = ({
 	const filteredListeningPorts = listeningPorts.filter((port) =>
 		filteredSharedPorts.every((sharedPort) => sharedPort.port !== port.port),
 	);
-	// only disable the form if shared port controls are entitled and the template doesn't allow sharing ports
+	// only disable the form if shared port controls are entitled and the template doesn't allow sharing ports.
+	// Restricted shares list specific users and groups, which can only be set through the API.
 	const canSharePorts = !(
-		portSharingControlsEnabled && template.max_port_share_level === "owner"
+		portSharingControlsEnabled &&
+		(template.max_port_share_level === "owner" ||
+			template.max_port_share_level === "restricted")
 	);
 	const canSharePortsPublic =
 		canSharePorts && template.max_port_share_level === "public";
@@ -493,6 +496,9 @@ export const PortForwardPopoverView: FC<PortForwardPopoverViewProps> = ({
 													protocol: event.target
 														.value as WorkspaceAgentPortShareProtocol,
 													share_level: share.share_level,
+													shared_with_user_ids: share.shared_with_user_ids,
+													shared_with_group_ids: share.shared_with_group_ids,
+													expires_at: share.expires_at,
 												});
 											}}
 										>
@@ -516,9 +522,15 @@ export const PortForwardPopoverView: FC<PortForwardPopoverViewProps> = ({
 														protocol: share.protocol,
 														share_level: event.target
 															.value as WorkspaceAgentPortShareLevel,
+														expires_at: share.expires_at,
 													});
 												}}
 											>
+												{share.share_level === "restricted" && (
+													<MenuItem value="restricted" disabled>
+														Restricted
+													</MenuItem>
+												)}
 												<MenuItem value="organization">Organization</MenuItem>
 												{canSharePortsAuthenticated ? (
 													<MenuItem value="authenticated">
@@ -36,6 +36,8 @@ const nullDevice = {
 	hide: false,
 	icon: "",
 	daily_cost: 0,
+	display_order: 0,
+	collapsed: false,
 } as const;
 
 const short = {
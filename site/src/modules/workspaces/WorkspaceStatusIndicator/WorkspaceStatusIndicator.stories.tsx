@@ -34,6 +34,7 @@ export const Unhealthy: Story = {
 			health: {
 				healthy: false,
 				failing_agents: [],
+				warnings: [],
 			},
 		},
 	},
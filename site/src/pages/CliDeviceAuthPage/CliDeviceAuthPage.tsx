@@ -0,0 +1,31 @@
+import { authorizeLoginDevice } from "api/queries/users";
+import type { FC } from "react";
+import { Helmet } from "react-helmet-async";
+import { useMutation } from "react-query";
+import { useSearchParams } from "react-router-dom";
+import { pageTitle } from "utils/page";
+import { CliDeviceAuthPageView } from "./CliDeviceAuthPageView";
+
+const CliDeviceAuthPage: FC = () => {
+	const [searchParams] = useSearchParams();
+	const authorizeMutation = useMutation(authorizeLoginDevice());
+
+	return (
+		<>
+			<Helmet>
+				<title>{pageTitle("CLI Device Auth")}</title>
+			</Helmet>
+			<CliDeviceAuthPageView
+				initialCode={searchParams.get("code") ?? ""}
+				isAuthorizing={authorizeMutation.isLoading}
+				isAuthorized={authorizeMutation.isSuccess}
+				error={authorizeMutation.error}
+				onAuthorize={(userCode) =>
+					authorizeMutation.mutate({ user_code: userCode })
+				}
+			/>
+		</>
+	);
+};
+
+export default CliDeviceAuthPage;
@@ -0,0 +1,45 @@
+import type { Meta, StoryObj } from "@storybook/react";
+import { mockApiError } from "testHelpers/entities";
+import { CliDeviceAuthPageView } from "./CliDeviceAuthPageView";
+
+const meta: Meta<typeof CliDeviceAuthPageView> = {
+	title: "pages/CliDeviceAuthPage",
+	component: CliDeviceAuthPageView,
+	args: {
+		initialCode: "BCDF-GHJK",
+		isAuthorizing: false,
+		isAuthorized: false,
+	},
+};
+
+export default meta;
+type Story = StoryObj<typeof CliDeviceAuthPageView>;
+
+export const Default: Story = {};
+
+export const Empty: Story = {
+	args: {
+		initialCode: "",
+	},
+};
+
+export const Authorizing: Story = {
+	args: {
+		isAuthorizing: true,
+	},
+};
+
+export const InvalidCode: Story = {
+	args: {
+		error: mockApiError({
+			message: "The code is invalid or expired.",
+			detail: "Run the login command on your device again to get a new code.",
+		}),
+	},
+};
+
+export const Authorized: Story = {
+	args: {
+		isAuthorized: true,
+	},
+};
@@ -0,0 +1,99 @@
+import { ErrorAlert } from "components/Alert/ErrorAlert";
+import { Button } from "components/Button/Button";
+import { Input } from "components/Input/Input";
+import { SignInLayout } from "components/SignInLayout/SignInLayout";
+import { Spinner } from "components/Spinner/Spinner";
+import { Welcome } from "components/Welcome/Welcome";
+import { CheckIcon } from "lucide-react";
+import { type FC, useState } from "react";
+import { Link as RouterLink } from "react-router-dom";
+
+interface CliDeviceAuthPageViewProps {
+	initialCode: string;
+	isAuthorizing: boolean;
+	isAuthorized: boolean;
+	error?: unknown;
+	onAuthorize: (userCode: string) => void;
+}
+
+export const CliDeviceAuthPageView: FC<CliDeviceAuthPageViewProps> = ({
+	initialCode,
+	isAuthorizing,
+	isAuthorized,
+	error,
+	onAuthorize,
+}) => {
+	const [userCode, setUserCode] = useState(initialCode);
+
+	if (isAuthorized) {
+		return (
+			<SignInLayout>
+				<Welcome>Device authorized</Welcome>
+
+				<p className="m-0 text-center text-sm text-content-secondary leading-normal">
+					The CLI is now logged in.{" "}
+					<strong className="block">
+						You can return to your terminal.
+					</strong>
+				</p>
+
+				<div className="flex flex-col items-center gap-1 w-full mt-4">
+					<Button className="w-full" variant="subtle" asChild>
+						<RouterLink to="/workspaces">Go to workspaces</RouterLink>
+					</Button>
+				</div>
+			</SignInLayout>
+		);
+	}
+
+	return (
+		<SignInLayout>
+			<Welcome>Authorize device</Welcome>
+
+			<p className="m-0 text-center text-sm text-content-secondary leading-normal">
+				Enter the code shown in your terminal.{" "}
+				<strong className="block">
+					Only authorize devices that you started the login on.
+				</strong>
+			</p>
+
+			<form
+				className="flex flex-col items-center gap-1 w-full mt-4"
+				onSubmit={(event) => {
+					event.preventDefault();
+					onAuthorize(userCode);
+				}}
+			>
+				{Boolean(error) && (
+					<ErrorAlert error={error} className="w-full mb-3" />
+				)}
+
+				<Input
+					aria-label="Device code"
+					className="text-center font-mono uppercase mb-3"
+					placeholder="XXXX-XXXX"
+					autoComplete="off"
+					autoFocus
+					value={userCode}
+					onChange={(event) => setUserCode(event.target.value)}
+				/>
+
+				<Button
+					className="w-full"
+					size="lg"
+					type="submit"
+					disabled={!userCode || isAuthorizing}
+				>
+					<Spinner loading={isAuthorizing}>
+						<CheckIcon />
+					</Spinner>
+					Authorize device
+				</Button>
+
+				<Button className="w-full" variant="subtle" asChild>
+					<RouterLink to="/workspaces">Cancel</RouterLink>
+				</Button>
+			</form>
+		</SignInLayout>
+	);
+};
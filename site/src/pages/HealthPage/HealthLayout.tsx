@@ -41,6 +41,7 @@ export const HealthLayout: FC = () => {
 		database: "Database",
 		workspace_proxy: "Workspace Proxy",
 		provisioner_daemons: "Provisioner Daemons",
+		provisioner_jobs: "Provisioner Jobs",
 	} as const;
 	const visibleSections = filterVisibleSections(sections);
 
@@ -0,0 +1,18 @@
+import type { Meta, StoryObj } from "@storybook/react";
+import ProvisionerJobsPage from "./ProvisionerJobsPage";
+import { generateMeta } from "./storybook";
+
+const meta: Meta = {
+	title: "pages/Health/ProvisionerJobs",
+	...generateMeta({
+		path: "/health/provisioner-jobs",
+		element: <ProvisionerJobsPage />,
+	}),
+};
+
+export default meta;
+type Story = StoryObj;
+
+const Example: Story = {};
+
+export { Example as ProvisionerJobs };
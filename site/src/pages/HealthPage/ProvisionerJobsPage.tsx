@@ -0,0 +1,140 @@
+import type { HealthcheckReport } from "api/typesGenerated";
+import { Alert } from "components/Alert/Alert";
+import {
+	Table,
+	TableBody,
+	TableCell,
+	TableHead,
+	TableHeader,
+	TableRow,
+} from "components/Table/Table";
+import {
+	ProvisionerTag,
+	ProvisionerTags,
+} from "modules/provisioners/ProvisionerTags";
+import type { FC } from "react";
+import { Helmet } from "react-helmet-async";
+import { useOutletContext } from "react-router-dom";
+import { pageTitle } from "utils/page";
+import { humanDuration, relativeTime } from "utils/time";
+import {
+	GridData,
+	GridDataLabel,
+	GridDataValue,
+	Header,
+	HeaderTitle,
+	HealthMessageDocsLink,
+	HealthyDot,
+	Main,
+	SectionLabel,
+} from "./Content";
+import { DismissWarningButton } from "./DismissWarningButton";
+
+const ProvisionerJobsPage: FC = () => {
+	const healthStatus = useOutletContext<HealthcheckReport>();
+	const { provisioner_jobs: jobs } = healthStatus;
+
+	return (
+		<>
+			<Helmet>
+				<title>{pageTitle("Provisioner Jobs - Health")}</title>
+			</Helmet>
+
+			<Header>
+				<HeaderTitle>
+					<HealthyDot severity={jobs.severity} />
+					Provisioner Jobs
+				</HeaderTitle>
+				<DismissWarningButton healthcheck="ProvisionerJobs" />
+			</Header>
+
+			<Main>
+				{jobs.error && <Alert severity="error">{jobs.error}</Alert>}
+				{jobs.warnings.map((warning) => {
+					return (
+						<Alert
+							actions={HealthMessageDocsLink(warning)}
+							key={warning.code}
+							severity="warning"
+						>
+							{warning.message}
+						</Alert>
+					);
+				})}
+
+				<GridData>
+					<GridDataLabel>Oldest pending job</GridDataLabel>
+					<GridDataValue>
+						{jobs.oldest_pending_job_age_ms > 0
+							? humanDuration(jobs.oldest_pending_job_age_ms)
+							: "None"}
+					</GridDataValue>
+
+					<GridDataLabel>Pending job threshold</GridDataLabel>
+					<GridDataValue>
+						{humanDuration(jobs.pending_job_age_threshold_ms)}
+					</GridDataValue>
+
+					<GridDataLabel>Queue depth threshold</GridDataLabel>
+					<GridDataValue>{jobs.queue_depth_threshold}</GridDataValue>
+
+					<GridDataLabel>Job reaper last run</GridDataLabel>
+					<GridDataValue>
+						{jobs.job_reaper_last_run_at
+							? relativeTime(jobs.job_reaper_last_run_at)
+							: "Never"}
+					</GridDataValue>
+
+					<GridDataLabel>Job reaper threshold</GridDataLabel>
+					<GridDataValue>
+						{humanDuration(jobs.job_reaper_staleness_threshold_ms)}
+					</GridDataValue>
+				</GridData>
+
+				<section>
+					<SectionLabel>Queues</SectionLabel>
+					<Table>
+						<TableHeader>
+							<TableRow>
+								<TableHead>Tags</TableHead>
+								<TableHead>Pending jobs</TableHead>
+								<TableHead>Oldest pending job</TableHead>
+							</TableRow>
+						</TableHeader>
+						<TableBody>
+							{jobs.queue_depths.length === 0 ? (
+								<TableRow>
+									<TableCell colSpan={3}>No pending jobs</TableCell>
+								</TableRow>
+							) : (
+								jobs.queue_depths.map((queue) => (
+									<TableRow
+										key={`${queue.organization_id}:${JSON.stringify(queue.tags)}`}
+									>
+										<TableCell>
+											<ProvisionerTags>
+												{Object.entries(queue.tags).map(([key, value]) => (
+													<ProvisionerTag
+														key={key}
+														label={key}
+														value={value}
+													/>
+												))}
+											</ProvisionerTags>
+										</TableCell>
+										<TableCell>{queue.pending_jobs}</TableCell>
+										<TableCell>
+											{humanDuration(queue.oldest_pending_job_age_ms)}
+										</TableCell>
+									</TableRow>
+								))
+							)}
+						</TableBody>
+					</Table>
+				</section>
+			</Main>
+		</>
+	);
+};
+
+export default ProvisionerJobsPage;
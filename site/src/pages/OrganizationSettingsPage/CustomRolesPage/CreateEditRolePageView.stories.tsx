@@ -114,3 +114,27 @@ export const ToggleParentCheckbox: Story = {
 		await expect(checkbox).not.toBeChecked();
 	},
 };
+
+export const DenyAction: Story = {
+	args: {
+		...Default.args,
+		role: undefined,
+	},
+	play: async ({ canvasElement }) => {
+		const user = userEvent.setup();
+		const canvas = within(canvasElement);
+		const resource = canvas
+			.getByTestId("template")
+			.getElementsByTagName("input")[0];
+		await user.click(resource);
+		await expect(resource).toBeChecked();
+
+		// Denying an action removes it from the allowed actions.
+		const deny = canvas
+			.getByTestId("template:delete:deny")
+			.getElementsByTagName("input")[0];
+		await user.click(deny);
+		await expect(deny).toBeChecked();
+		await expect(resource).not.toBeChecked();
+	},
+};
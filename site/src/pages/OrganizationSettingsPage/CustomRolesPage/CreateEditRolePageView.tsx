@@ -158,7 +158,9 @@ const ResourceActionComparator = (
 	p: Permission,
 	resource: string,
 	action: string,
+	negate = false,
 ) =>
+	p.negate === negate &&
 	p.resource_type === resource &&
 	(p.action.toString() === "*" || p.action === action);
 
@@ -204,20 +206,27 @@ const ActionCheckboxes: FC<ActionCheckboxesProps> = ({
 		form: ReturnType<typeof useFormik<Role>> & { values: Role },
 	) => {
 		const { name, checked } = e.currentTarget;
-		const [resource_type, action] = name.split(":");
-
+		const [resource_type, action, deny] = name.split(":");
+		const negate = deny === "deny";
+
+		// An action is either allowed or denied, so checking one of them
+		// replaces the other.
+		const otherActions = checkedActions?.filter(
+			(p) =>
+				p.resource_type !== resource_type ||
+				p.action !== action ||
+				(!checked && p.negate !== negate),
+		);
 		const newPermissions = checked
 			? [
-					...checkedActions,
+					...otherActions,
 					{
-						negate: false,
+						negate,
 						resource_type: resource_type as RBACResource,
 						action: action as RBACAction,
 					},
 				]
-			: checkedActions?.filter(
-					(p) => p.resource_type !== resource_type || p.action !== action,
-				);
+			: otherActions;
 
 		setCheckActions(newPermissions);
 		await form.setFieldValue("organization_permissions", newPermissions);
@@ -233,22 +242,23 @@ const ActionCheckboxes: FC<ActionCheckboxesProps> = ({
 
 		const resourceActionsForResource = resourceActions[resource] || {};
 
-		const newCheckedActions =
-			!checked || indeterminate
-				? checkedActions?.filter((p) => p.resource_type !== resource)
-				: checkedActions;
-
-		const newPermissions =
-			checked || indeterminate
-				? [
-						...newCheckedActions,
-						...Object.keys(resourceActionsForResource).map((resourceKey) => ({
-							negate: false,
-							resource_type: resource as RBACResource,
-							action: resourceKey as RBACAction,
-						})),
-					]
-				: [...newCheckedActions];
+		// Selecting every action replaces any denied actions, while clearing
+		// the resource keeps them since they are not part of the checkbox.
+		const selectAll = checked || indeterminate;
+		const newCheckedActions = checkedActions?.filter(
+			(p) => p.resource_type !== resource || (!selectAll && p.negate),
+		);
+
+		const newPermissions = selectAll
+			? [
+					...newCheckedActions,
+					...Object.keys(resourceActionsForResource).map((resourceKey) => ({
+						negate: false,
+						resource_type: resource as RBACResource,
+						action: resourceKey as RBACAction,
+					})),
+				]
+			: [...newCheckedActions];
 
 		setCheckActions(newPermissions);
 		await form.setFieldValue("organization_permissions", newPermissions);
@@ -331,6 +341,8 @@ const PermissionCheckboxGroup: FC<PermissionCheckboxGroupProps> = ({
 	handleActionCheckChange,
 	handleResourceCheckChange,
 }) => {
+	const allowedActions = checkedActions.filter((p) => !p.negate);
+
 	return (
 		<TableRow key={resourceKey}>
 			<TableCell sx={{ paddingLeft: 2 }} colSpan={2}>
@@ -338,18 +350,18 @@ const PermissionCheckboxGroup: FC<PermissionCheckboxGroupProps> = ({
 					<Checkbox
 						size="small"
 						name={`${resourceKey}`}
-						checked={checkedActions.length === Object.keys(value).length}
+						checked={allowedActions.length === Object.keys(value).length}
 						indeterminate={
-							checkedActions.length > 0 &&
-							checkedActions.length < Object.keys(value).length
+							allowedActions.length > 0 &&
+							allowedActions.length < Object.keys(value).length
 						}
 						data-testid={`${resourceKey}`}
 						onChange={(e) =>
 							handleResourceCheckChange(
 								e,
 								form,
-								checkedActions.length > 0 &&
-									checkedActions.length < Object.keys(value).length,
+								allowedActions.length > 0 &&
+									allowedActions.length < Object.keys(value).length,
 							)
 						}
 					/>
@@ -369,6 +381,26 @@ const PermissionCheckboxGroup: FC<PermissionCheckboxGroupProps> = ({
 									{actionKey}
 								</span>
 								<span css={styles.actionDescription}>{value}</span>
+								<FormControlLabel
+									css={styles.denyLabel}
+									control={
+										<Checkbox
+											size="small"
+											name={`${resourceKey}:${actionKey}:deny`}
+											checked={checkedActions.some((p) =>
+												ResourceActionComparator(
+													p,
+													resourceKey,
+													actionKey,
+													true,
+												),
+											)}
+											onChange={(e) => handleActionCheckChange(e, form)}
+											data-testid={`${resourceKey}:${actionKey}:deny`}
+										/>
+									}
+									label={<span css={styles.actionDescription}>Deny</span>}
+								/>
 							</li>
 						))}
 					</ul>
@@ -426,7 +458,10 @@ const styles = {
 	}),
 	actionItem: {
 		display: "grid",
-		gridTemplateColumns: "270px 1fr",
+		gridTemplateColumns: "270px 1fr auto",
+	},
+	denyLabel: {
+		marginRight: 0,
 	},
 } satisfies Record<string, Interpolation<Theme>>;
 
@@ -41,6 +41,23 @@ export const SinglePermission: Story = {
 	},
 };
 
+export const DeniedPermission: Story = {
+	args: {
+		permissions: [
+			{
+				negate: false,
+				resource_type: "template",
+				action: "read",
+			},
+			{
+				negate: true,
+				resource_type: "template",
+				action: "delete",
+			},
+		],
+	},
+};
+
 export const NoPermissions: Story = {
 	args: {
 		permissions: [],
@@ -59,7 +59,10 @@ const PermissionsPill: FC<PermissionPillProps> = ({
 
 	return (
 		<Pill css={styles.permissionPill}>
-			<b>{resource}</b>: {actions.map((p) => p.action).join(", ")}
+			<b>{resource}</b>:{" "}
+			{actions
+				.map((p) => (p.negate ? `deny ${p.action}` : p.action))
+				.join(", ")}
 		</Pill>
 	);
 };
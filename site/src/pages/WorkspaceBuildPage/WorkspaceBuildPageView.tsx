@@ -118,7 +118,11 @@ export const WorkspaceBuildPageView: FC<WorkspaceBuildPageViewProps> = ({
 					<StatsItem
 						css={styles.statsItem}
 						label="Duration"
-						value={displayWorkspaceBuildDuration(build)}
+						value={
+							build.exceeds_template_p95
+								? `${displayWorkspaceBuildDuration(build)} (slower than 95% of recent builds)`
+								: displayWorkspaceBuildDuration(build)
+						}
 					/>
 					<StatsItem
 						css={styles.statsItem}
@@ -211,6 +215,21 @@ export const WorkspaceBuildPageView: FC<WorkspaceBuildPageViewProps> = ({
 							</div>
 						</Alert>
 					)}
+					{build.warnings && build.warnings.length > 0 && (
+						<Alert
+							severity="warning"
+							css={{
+								borderRadius: 0,
+								border: 0,
+								background: theme.roles.warning.background,
+								borderBottom: `1px solid ${theme.palette.divider}`,
+							}}
+						>
+							{build.warnings.map((warning) => (
+								<div key={warning.code}>{warning.message}</div>
+							))}
+						</Alert>
+					)}
 
 					{tabState.value === "build" && <BuildLogsContent logs={logs} />}
 					{tabState.value !== "build" && selectedAgent && (
@@ -123,6 +123,28 @@ export const WorkspaceNotifications: FC<WorkspaceNotificationsProps> = ({
 		});
 	}
 
+	// Running low on resources
+	if (
+		workspace.latest_build.status === "running" &&
+		workspace.health.warnings.length > 0
+	) {
+		notifications.push({
+			title: "Workspace is running low on resources",
+			severity: "warning",
+			detail: (
+				<ul css={{ margin: 0, paddingLeft: 16 }}>
+					{workspace.health.warnings.map((warning) => (
+						<li key={`${warning.agent_id}-${warning.kind}-${warning.volume}`}>
+							{warning.kind === "near_out_of_memory"
+								? `${warning.agent_name} is almost out of memory.`
+								: `${warning.agent_name} is almost out of disk space on ${warning.volume}.`}
+						</li>
+					))}
+				</ul>
+			),
+		});
+	}
+
 	// Dormant
 	const { entitlements } = useDashboard();
 	const advancedSchedulingEnabled =
@@ -217,6 +239,14 @@ export const WorkspaceNotifications: FC<WorkspaceNotificationsProps> = ({
 						Position in queue:{" "}
 						<strong>{workspace.latest_build.job.queue_position}</strong>
 					</span>
+					{workspace.latest_build.job.estimated_start_at && (
+						<span css={{ display: "block" }}>
+							Estimated start:{" "}
+							<strong>
+								{dayjs(workspace.latest_build.job.estimated_start_at).fromNow()}
+							</strong>
+						</span>
+					)}
 				</>
 			),
 		});
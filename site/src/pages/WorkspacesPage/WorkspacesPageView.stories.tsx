@@ -286,6 +286,7 @@ export const UnhealthyWorkspace: Story = {
 				health: {
 					healthy: false,
 					failing_agents: [],
+					warnings: [],
 				},
 			},
 		],
@@ -41,6 +41,9 @@ const OrganizationSettingsLayout = lazy(
 	() => import("./modules/management/OrganizationSettingsLayout"),
 );
 const CliAuthPage = lazy(() => import("./pages/CliAuthPage/CliAuthPage"));
+const CliDeviceAuthPage = lazy(
+	() => import("./pages/CliDeviceAuthPage/CliDeviceAuthPage"),
+);
 const CliInstallPage = lazy(
 	() => import("./pages/CliInstallPage/CliInstallPage"),
 );
@@ -295,6 +298,9 @@ const WorkspaceProxyHealthPage = lazy(
 const ProvisionerDaemonsHealthPage = lazy(
 	() => import("./pages/HealthPage/ProvisionerDaemonsPage"),
 );
+const ProvisionerJobsHealthPage = lazy(
+	() => import("./pages/HealthPage/ProvisionerJobsPage"),
+);
 const UserNotificationsPage = lazy(
 	() => import("./pages/UserSettingsPage/NotificationsPage/NotificationsPage"),
 );
@@ -562,6 +568,10 @@ export const router = createBrowserRouter(
 							path="provisioner-daemons"
 							element={<ProvisionerDaemonsHealthPage />}
 						/>
+						<Route
+							path="provisioner-jobs"
+							element={<ProvisionerJobsHealthPage />}
+						/>
 					</Route>
 
 					<Route path="/install" element={<CliInstallPage />} />
@@ -586,6 +596,7 @@ export const router = createBrowserRouter(
 					element={<TerminalPage />}
 				/>
 				<Route path="/cli-auth" element={<CliAuthPage />} />
+				<Route path="/cli-auth/device" element={<CliDeviceAuthPage />} />
 				<Route path="/icons" element={<IconsPage />} />
 				<Route path="/tasks/:username/:workspace" element={<TaskPage />} />
 			</Route>
@@ -21,6 +21,7 @@ export const MockOrganization: TypesGen.Organization = {
 	created_at: "",
 	updated_at: "",
 	is_default: false,
+	cost_center: "",
 };
 
 export const MockDefaultOrganization: TypesGen.Organization = {
@@ -37,6 +38,7 @@ export const MockOrganization2: TypesGen.Organization = {
 	created_at: "",
 	updated_at: "",
 	is_default: false,
+	cost_center: "",
 };
 
 export const MockTemplateDAUResponse: TypesGen.DAUsResponse = {
@@ -102,6 +104,7 @@ export const MockPrimaryWorkspaceProxy: TypesGen.WorkspaceProxy = {
 	healthy: true,
 	path_app_url: "https://coder.com",
 	wildcard_hostname: "*.coder.com",
+	path_apps_only: false,
 	derp_enabled: true,
 	derp_only: false,
 	created_at: new Date().toISOString(),
@@ -122,6 +125,7 @@ export const MockHealthyWildWorkspaceProxy: TypesGen.WorkspaceProxy = {
 	healthy: true,
 	path_app_url: "https://external.com",
 	wildcard_hostname: "*.external.com",
+	path_apps_only: false,
 	derp_enabled: true,
 	derp_only: false,
 	created_at: new Date().toISOString(),
@@ -142,6 +146,7 @@ export const MockUnhealthyWildWorkspaceProxy: TypesGen.WorkspaceProxy = {
 	healthy: false,
 	path_app_url: "https://unhealthy.coder.com",
 	wildcard_hostname: "*unhealthy..coder.com",
+	path_apps_only: false,
 	derp_enabled: true,
 	derp_only: true,
 	created_at: new Date().toISOString(),
@@ -170,6 +175,7 @@ export const MockWorkspaceProxies: TypesGen.WorkspaceProxy[] = [
 		healthy: true,
 		path_app_url: "https://cowboy.coder.com",
 		wildcard_hostname: "",
+		path_apps_only: false,
 		derp_enabled: false,
 		derp_only: false,
 		created_at: new Date().toISOString(),
@@ -827,6 +833,9 @@ export const MockTemplate: TypesGen.Template = {
 	deprecation_message: "",
 	max_port_share_level: "public",
 	use_classic_parameter_flow: true,
+	max_concurrent_jobs_per_user: 0,
+	resource_ceilings: {},
+	required_provisioner_tags: {},
 };
 
 const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -925,6 +934,7 @@ const MockWorkspaceAgentScript: TypesGen.WorkspaceAgentScript = {
 	log_path: "",
 	run_on_start: true,
 	run_on_stop: false,
+	run_on_claim: false,
 	script: "echo 'hello world'",
 	start_blocks_login: false,
 	timeout: 0,
@@ -970,6 +980,7 @@ export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
 		"vscode_insiders",
 		"web_terminal",
 	],
+	collapsed: false,
 };
 
 export const MockWorkspaceSubAgent: TypesGen.WorkspaceAgent = {
@@ -1179,6 +1190,8 @@ export const MockWorkspaceResource: TypesGen.WorkspaceResource = {
 	icon: "",
 	metadata: [{ key: "size", value: "32GB", sensitive: false }],
 	daily_cost: 10,
+	display_order: 0,
+	collapsed: false,
 };
 
 export const MockWorkspaceResourceSensitive: TypesGen.WorkspaceResource = {
@@ -1216,6 +1229,8 @@ export const MockWorkspaceVolumeResource: TypesGen.WorkspaceResource = {
 	hide: false,
 	icon: "",
 	daily_cost: 0,
+	display_order: 0,
+	collapsed: false,
 };
 
 export const MockWorkspaceImageResource: TypesGen.WorkspaceResource = {
@@ -1228,6 +1243,8 @@ export const MockWorkspaceImageResource: TypesGen.WorkspaceResource = {
 	hide: false,
 	icon: "",
 	daily_cost: 0,
+	display_order: 0,
+	collapsed: false,
 };
 
 export const MockWorkspaceContainerResource: TypesGen.WorkspaceResource = {
@@ -1240,6 +1257,8 @@ export const MockWorkspaceContainerResource: TypesGen.WorkspaceResource = {
 	hide: false,
 	icon: "",
 	daily_cost: 0,
+	display_order: 0,
+	collapsed: false,
 };
 
 const MockWorkspaceAutostartDisabled: TypesGen.UpdateWorkspaceAutostartRequest =
@@ -1272,6 +1291,7 @@ export const MockWorkspaceBuild: TypesGen.WorkspaceBuild = {
 	workspace_id: "759f1d46-3174-453d-aa60-980a9c1442f3",
 	deadline: "2022-05-17T23:39:00.00Z",
 	reason: "initiator",
+	initiator_context: {},
 	resources: [MockWorkspaceResource],
 	status: "running",
 	daily_cost: 20,
@@ -1300,6 +1320,10 @@ const MockWorkspaceBuildAutostart: TypesGen.WorkspaceBuild = {
 	workspace_id: "759f1d46-3174-453d-aa60-980a9c1442f3",
 	deadline: "2022-05-17T23:39:00.00Z",
 	reason: "autostart",
+	initiator_context: {
+		automation: "lifecycle_executor",
+		schedule: "CRON_TZ=UTC 0 9 * * 1-5",
+	},
 	resources: [MockWorkspaceResource],
 	status: "running",
 	daily_cost: 20,
@@ -1324,6 +1348,9 @@ const MockWorkspaceBuildAutostop: TypesGen.WorkspaceBuild = {
 	workspace_id: "759f1d46-3174-453d-aa60-980a9c1442f3",
 	deadline: "2022-05-17T23:39:00.00Z",
 	reason: "autostop",
+	initiator_context: {
+		automation: "lifecycle_executor",
+	},
 	resources: [MockWorkspaceResource],
 	status: "running",
 	daily_cost: 20,
@@ -1350,6 +1377,7 @@ export const MockFailedWorkspaceBuild = (
 	workspace_id: "759f1d46-3174-453d-aa60-980a9c1442f3",
 	deadline: "2022-05-17T23:39:00.00Z",
 	reason: "initiator",
+	initiator_context: {},
 	resources: [],
 	status: "failed",
 	daily_cost: 20,
@@ -1403,6 +1431,7 @@ export const MockWorkspace: TypesGen.Workspace = {
 	health: {
 		healthy: true,
 		failing_agents: [],
+		warnings: [],
 	},
 	latest_app_status: null,
 	automatic_updates: "never",
@@ -2366,6 +2395,7 @@ type MockAPIInput = {
 	message?: string;
 	detail?: string;
 	validations?: FieldError[];
+	code?: TypesGen.ResponseCode;
 };
 
 type MockAPIOutput = {
@@ -2375,6 +2405,7 @@ type MockAPIOutput = {
 			message: string;
 			detail: string | undefined;
 			validations: FieldError[] | undefined;
+			code: TypesGen.ResponseCode | undefined;
 		};
 	};
 };
@@ -2383,6 +2414,7 @@ export const mockApiError = ({
 	message = "Something went wrong.",
 	detail,
 	validations,
+	code,
 }: MockAPIInput): MockAPIOutput => ({
 	// This is how axios can check if it is an axios error when calling isAxiosError
 	isAxiosError: true,
@@ -2391,6 +2423,7 @@ export const mockApiError = ({
 			message,
 			detail,
 			validations,
+			code,
 		},
 	},
 });
@@ -3653,6 +3686,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: true,
 					path_app_url: "https://dev.coder.com",
 					wildcard_hostname: "*--apps.dev.coder.com",
+					path_apps_only: false,
 					derp_enabled: false,
 					derp_only: false,
 					status: {
@@ -3676,6 +3710,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: true,
 					path_app_url: "https://sydney.dev.coder.com",
 					wildcard_hostname: "*--apps.sydney.dev.coder.com",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -3699,6 +3734,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: true,
 					path_app_url: "https://europe.dev.coder.com",
 					wildcard_hostname: "*--apps.europe.dev.coder.com",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -3722,6 +3758,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: true,
 					path_app_url: "https://brazil.dev.coder.com",
 					wildcard_hostname: "*--apps.brazil.dev.coder.com",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -3745,6 +3782,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: false,
 					path_app_url: "",
 					wildcard_hostname: "",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -3768,6 +3806,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: false,
 					path_app_url: "http://127.0.0.1:3001",
 					wildcard_hostname: "",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -3793,6 +3832,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: true,
 					path_app_url: "https://paris-coder.fly.dev",
 					wildcard_hostname: "",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -3816,6 +3856,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: true,
 					path_app_url: "https://sydney-coder.fly.dev",
 					wildcard_hostname: "",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -3839,6 +3880,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 					healthy: true,
 					path_app_url: "https://sao-paulo-coder.fly.dev",
 					wildcard_hostname: "",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -3961,6 +4003,42 @@ export const MockHealth: TypesGen.HealthcheckReport = {
 		],
 	},
 	coder_version: MockBuildInfo.version,
+	provisioner_jobs: {
+		severity: "ok",
+		warnings: [
+			{
+				message: "1 provisioner job queue(s) have 50 or more pending jobs",
+				code: "EPJ01",
+			},
+		],
+		dismissed: false,
+		queue_depths: [
+			{
+				organization_id: MockOrganization.id,
+				tags: {
+					owner: "",
+					scope: "organization",
+				},
+				pending_jobs: 52,
+				oldest_pending_job_age_ms: 420000,
+			},
+			{
+				organization_id: MockOrganization.id,
+				tags: {
+					owner: "",
+					scope: "organization",
+					region: "eu",
+				},
+				pending_jobs: 3,
+				oldest_pending_job_age_ms: 65000,
+			},
+		],
+		oldest_pending_job_age_ms: 420000,
+		job_reaper_last_run_at: "2024-01-04T16:04:03.967551Z",
+		queue_depth_threshold: 50,
+		pending_job_age_threshold_ms: 900000,
+		job_reaper_staleness_threshold_ms: 600000,
+	},
 };
 
 export const MockListeningPortsResponse: TypesGen.WorkspaceAgentListeningPortsResponse =
@@ -3980,6 +4058,8 @@ export const MockSharedPortsResponse: TypesGen.WorkspaceAgentPortShares = {
 			port: 4000,
 			share_level: "authenticated",
 			protocol: "http",
+			shared_with_user_ids: [],
+			shared_with_group_ids: [],
 		},
 		{
 			workspace_id: MockWorkspace.id,
@@ -3987,6 +4067,8 @@ export const MockSharedPortsResponse: TypesGen.WorkspaceAgentPortShares = {
 			port: 4443,
 			share_level: "organization",
 			protocol: "http",
+			shared_with_user_ids: [],
+			shared_with_group_ids: [],
 		},
 		{
 			workspace_id: MockWorkspace.id,
@@ -3994,6 +4076,8 @@ export const MockSharedPortsResponse: TypesGen.WorkspaceAgentPortShares = {
 			port: 65535,
 			share_level: "authenticated",
 			protocol: "https",
+			shared_with_user_ids: [],
+			shared_with_group_ids: [],
 		},
 		{
 			workspace_id: MockWorkspace.id,
@@ -4001,6 +4085,8 @@ export const MockSharedPortsResponse: TypesGen.WorkspaceAgentPortShares = {
 			port: 8081,
 			share_level: "public",
 			protocol: "http",
+			shared_with_user_ids: [],
+			shared_with_group_ids: [],
 		},
 	],
 };
@@ -4062,6 +4148,7 @@ export const DeploymentHealthUnhealthy: TypesGen.HealthcheckReport = {
 					healthy: false,
 					path_app_url: "http://127.0.0.1:3001",
 					wildcard_hostname: "",
+					path_apps_only: false,
 					derp_enabled: true,
 					derp_only: false,
 					status: {
@@ -4120,6 +4207,16 @@ export const DeploymentHealthUnhealthy: TypesGen.HealthcheckReport = {
 			},
 		],
 	},
+	provisioner_jobs: {
+		severity: "ok",
+		warnings: [],
+		dismissed: false,
+		queue_depths: [],
+		oldest_pending_job_age_ms: 0,
+		queue_depth_threshold: 50,
+		pending_job_age_threshold_ms: 900000,
+		job_reaper_staleness_threshold_ms: 600000,
+	},
 };
 
 export const MockHealthSettings: TypesGen.HealthSettings = {
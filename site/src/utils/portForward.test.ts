@@ -64,4 +64,31 @@ describe("port forward URL", () => {
 			"http://12345--my-agent--my-workspace--my-username.proxy-host.tld/path1/path2?key1=value1&key2=value2",
 		);
 	});
+	it("https, port and path without host", () => {
+		const forwarded = portForwardURL(
+			"",
+			samplePort,
+			sampleAgent,
+			sampleWorkspace,
+			sampleUsername,
+			"https",
+			"/path1/path2",
+		);
+		expect(forwarded).toEqual(
+			"http://localhost/@my-username/my-workspace.my-agent/apps/12345s/path1/path2",
+		);
+	});
+	it("http and port without host", () => {
+		const forwarded = portForwardURL(
+			"",
+			samplePort,
+			sampleAgent,
+			sampleWorkspace,
+			sampleUsername,
+			"http",
+		);
+		expect(forwarded).toEqual(
+			"http://localhost/@my-username/my-workspace.my-agent/apps/12345/",
+		);
+	});
 });
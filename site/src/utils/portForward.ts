@@ -13,6 +13,20 @@ export const portForwardURL = (
 	const { location } = window;
 	const suffix = protocol === "https" ? "s" : "";
 
+	// Without a wildcard hostname, ports are served on paths of the
+	// dashboard, which only works when the deployment serves apps on paths.
+	if (!host) {
+		const basePath = `/@${username}/${workspaceName}.${agentName}/apps/${port}${suffix}`;
+		const url = new URL(`${basePath}/`, location.origin);
+		if (pathname) {
+			url.pathname = basePath + pathname;
+		}
+		if (search) {
+			url.search = search;
+		}
+		return url.toString();
+	}
+
 	const subdomain = `${port}${suffix}--${agentName}--${workspaceName}--${username}`;
 
 	const baseUrl = `${location.protocol}//${host.replace(/\*/g, subdomain)}`;
@@ -81,6 +81,9 @@ export const getDisplayWorkspaceBuildInitiatedBy = (
 			return build.initiator_name;
 		case "autostart":
 		case "autostop":
+		case "dormancy":
+		case "failedstop":
+		case "autodelete":
 			return "Coder";
 	}
 };
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -30,12 +31,13 @@ import (
 // Even though we do attempt to sanitize data, it may still contain
 // sensitive information and should thus be treated as secret.
 type Bundle struct {
-	Deployment Deployment `json:"deployment"`
-	Network    Network    `json:"network"`
-	Workspace  Workspace  `json:"workspace"`
-	Agent      Agent      `json:"agent"`
-	Logs       []string   `json:"logs"`
-	CLILogs    []byte     `json:"cli_logs"`
+	Deployment   Deployment   `json:"deployment"`
+	Network      Network      `json:"network"`
+	Provisioners Provisioners `json:"provisioners"`
+	Workspace    Workspace    `json:"workspace"`
+	Agent        Agent        `json:"agent"`
+	Logs         []string     `json:"logs"`
+	CLILogs      []byte       `json:"cli_logs"`
 }
 
 type Deployment struct {
@@ -60,6 +62,11 @@ type Netcheck struct {
 	Logs   []string         `json:"logs"`
 }
 
+type Provisioners struct {
+	Daemons   []codersdk.ProvisionerDaemon `json:"daemons"`
+	JobReaper *healthsdk.JobReaperReport   `json:"job_reaper"`
+}
+
 type Workspace struct {
 	Workspace          codersdk.Workspace                 `json:"workspace"`
 	Parameters         []codersdk.WorkspaceBuildParameter `json:"parameters"`
@@ -67,6 +74,10 @@ type Workspace struct {
 	TemplateVersion    codersdk.TemplateVersion           `json:"template_version"`
 	TemplateFileBase64 string                             `json:"template_file_base64"`
 	BuildLogs          []codersdk.ProvisionerJobLog       `json:"build_logs"`
+	// FailedBuild is the most recent failed build of the workspace, if any.
+	FailedBuild *codersdk.WorkspaceBuild `json:"failed_build"`
+	// FailedBuildLogs holds the last lines of the logs of FailedBuild.
+	FailedBuildLogs []codersdk.ProvisionerJobLog `json:"failed_build_logs"`
 }
 
 type Agent struct {
@@ -83,6 +94,14 @@ type Agent struct {
 	StartupLogs         []codersdk.WorkspaceAgentLog                   `json:"startup_logs"`
 }
 
+const (
+	// failedBuildSearchLimit is the number of recent workspace builds searched
+	// for a failed build.
+	failedBuildSearchLimit = 25
+	// failedBuildLogsTail is the number of log lines kept from a failed build.
+	failedBuildLogsTail = 100
+)
+
 // Deps is a set of dependencies for discovering information
 type Deps struct {
 	// Source from which to obtain information.
@@ -227,6 +246,45 @@ func NetworkInfo(ctx context.Context, client *codersdk.Client, log slog.Logger)
 	return n
 }
 
+func ProvisionersInfo(ctx context.Context, client *codersdk.Client, log slog.Logger) Provisioners {
+	// Note: each goroutine assigns to a different struct field, hence no mutex.
+	var (
+		p  Provisioners
+		eg errgroup.Group
+	)
+
+	eg.Go(func() error {
+		orgs, err := client.Organizations(ctx)
+		if err != nil {
+			return xerrors.Errorf("fetch organizations: %w", err)
+		}
+		p.Daemons = make([]codersdk.ProvisionerDaemon, 0)
+		for _, org := range orgs {
+			daemons, err := client.OrganizationProvisionerDaemons(ctx, org.ID, nil)
+			if err != nil {
+				return xerrors.Errorf("fetch provisioner daemons of organization %q: %w", org.Name, err)
+			}
+			p.Daemons = append(p.Daemons, daemons...)
+		}
+		return nil
+	})
+
+	eg.Go(func() error {
+		rpt, err := healthsdk.New(client).DebugJobReaper(ctx)
+		if err != nil {
+			return xerrors.Errorf("fetch job reaper report: %w", err)
+		}
+		p.JobReaper = &rpt
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		log.Error(ctx, "fetch provisioner information", slog.Error(err))
+	}
+
+	return p
+}
+
 func WorkspaceInfo(ctx context.Context, client *codersdk.Client, log slog.Logger, workspaceID uuid.UUID) Workspace {
 	var (
 		w  Workspace
@@ -301,6 +359,38 @@ func WorkspaceInfo(ctx context.Context, client *codersdk.Client, log slog.Logger
 		return nil
 	})
 
+	eg.Go(func() error {
+		builds, err := client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: ws.ID,
+			Pagination:  codersdk.Pagination{Limit: failedBuildSearchLimit},
+		})
+		if err != nil {
+			return xerrors.Errorf("fetch workspace builds: %w", err)
+		}
+		idx := slices.IndexFunc(builds, func(build codersdk.WorkspaceBuild) bool {
+			return build.Job.Status == codersdk.ProvisionerJobFailed
+		})
+		if idx < 0 {
+			return nil
+		}
+		w.FailedBuild = &builds[idx]
+
+		buildLogCh, closer, err := client.WorkspaceBuildLogsAfter(ctx, w.FailedBuild.ID, 0)
+		if err != nil {
+			return xerrors.Errorf("fetch failed build logs: %w", err)
+		}
+		defer closer.Close()
+		logs := make([]codersdk.ProvisionerJobLog, 0)
+		for log := range buildLogCh {
+			logs = append(logs, log)
+		}
+		if len(logs) > failedBuildLogsTail {
+			logs = logs[len(logs)-failedBuildLogsTail:]
+		}
+		w.FailedBuildLogs = logs
+		return nil
+	})
+
 	eg.Go(func() error {
 		if ws.LatestBuild.ID == uuid.Nil {
 			return xerrors.Errorf("workspace has nil latest build id")
@@ -511,6 +601,11 @@ func Run(ctx context.Context, d *Deps) (*Bundle, error) {
 		b.Deployment = di
 		return nil
 	})
+	eg.Go(func() error {
+		pi := ProvisionersInfo(ctx, d.Client, d.Log)
+		b.Provisioners = pi
+		return nil
+	})
 	eg.Go(func() error {
 		wi := WorkspaceInfo(ctx, d.Client, d.Log, d.WorkspaceID)
 		b.Workspace = wi
@@ -3,6 +3,7 @@ package support_test
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"io"
 	"net/http"
 	"os"
@@ -21,6 +22,7 @@ import (
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/util/ptr"
@@ -87,9 +89,42 @@ func TestRun(t *testing.T) {
 		assertNotNilNotEmpty(t, bun.Agent.PingResult, "agent ping result should be present")
 		assertNotNilNotEmpty(t, bun.Agent.Prometheus, "agent prometheus metrics should be present")
 		assertNotNilNotEmpty(t, bun.Agent.StartupLogs, "agent startup logs should be present")
+		require.NotNil(t, bun.Provisioners.Daemons, "provisioner daemons should be present")
+		assertNotNilNotEmpty(t, bun.Provisioners.JobReaper, "job reaper report should be present")
+		assert.Nil(t, bun.Workspace.FailedBuild, "did not expect a failed build to be present")
 		assertNotNilNotEmpty(t, bun.Logs, "bundle logs should be present")
 	})
 
+	t.Run("OK_FailedBuild", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			Logger: ptr.Ref(slog.Make(sloghuman.Sink(io.Discard))),
+		})
+		admin := coderdtest.CreateFirstUser(t, client)
+		ws, agt := setupWorkspaceAndAgent(ctx, t, client, db, admin)
+		// nolint:gocritic // Marking the build as failed requires system privileges.
+		err := db.UpdateProvisionerJobWithCompleteByID(dbauthz.AsSystemRestricted(ctx), database.UpdateProvisionerJobWithCompleteByIDParams{
+			ID:          ws.LatestBuild.Job.ID,
+			UpdatedAt:   dbtime.Now(),
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+			Error:       sql.NullString{String: "terraform apply failed", Valid: true},
+		})
+		require.NoError(t, err)
+
+		bun, err := support.Run(ctx, &support.Deps{
+			Client:      client,
+			Log:         slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Named("bundle").Leveled(slog.LevelDebug),
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, bun.Workspace.FailedBuild, "failed build should be present")
+		require.Equal(t, ws.LatestBuild.ID, bun.Workspace.FailedBuild.ID)
+		require.Equal(t, "terraform apply failed", bun.Workspace.FailedBuild.Job.Error)
+		assertNotNilNotEmpty(t, bun.Workspace.FailedBuildLogs, "failed build logs should be present")
+	})
+
 	t.Run("OK_NoWorkspace", func(t *testing.T) {
 		t.Parallel()
 		cfg := coderdtest.DeploymentValues(t)
@@ -119,6 +154,8 @@ func TestRun(t *testing.T) {
 		assertNotNilNotEmpty(t, bun.Network.Interfaces, "network interfaces health should be present")
 		assert.Empty(t, bun.Workspace.Workspace, "did not expect workspace to be present")
 		assert.Empty(t, bun.Agent, "did not expect agent to be present")
+		require.NotNil(t, bun.Provisioners.Daemons, "provisioner daemons should be present")
+		assertNotNilNotEmpty(t, bun.Provisioners.JobReaper, "job reaper report should be present")
 		assertNotNilNotEmpty(t, bun.Logs, "bundle logs should be present")
 	})
 
@@ -217,6 +254,7 @@ func setupWorkspaceAndAgent(ctx context.Context, t *testing.T, client *codersdk.
 		Level:     []database.LogLevel{database.LogLevelInfo},
 		Stage:     []string{"The World"},
 		Output:    []string{"Players"},
+		Fields:    []string{"{}"},
 	})
 	require.NoError(t, err)
 	// Insert an agent log
@@ -56,9 +56,19 @@ import (
 //   - Added support for DeleteSubAgent RPC on the Agent API.
 //   - Added support for ListSubAgents RPC on the Agent API.
 //   - Add ORGANIZATION SharingLevel
+//
+// API v2.7:
+//   - Shipped in Coder v2.25.0
+//   - Added support for PushResourceUsage RPC on the Agent API.
+//   - Added support for ReportHealthEvents RPC on the Agent API.
+//   - Added run_on_claim field to WorkspaceAgentScript.
+//   - Added support for CreateSessionRecording and AppendSessionRecording
+//     RPCs on the Agent API, and session_recording_enabled to the manifest.
+//   - Added client_version and duration fields to Connection.
+//   - Added connection_quality to Stats.
 const (
 	CurrentMajor = 2
-	CurrentMinor = 6
+	CurrentMinor = 7
 )
 
 var CurrentVersion = apiversion.New(CurrentMajor, CurrentMinor)